@@ -1,13 +1,19 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -25,7 +31,7 @@ func newCodeCmd() *cobra.Command {
 		DisableAutoGenTag: true,
 	}
 
-	cmd.AddCommand(newCodeKeysCmd(), newCodeServerCmd(), newCodeScriptsCmd())
+	cmd.AddCommand(newCodeKeysCmd(), newCodeServerCmd(), newCodeScriptsCmd(), newCodeOpenAPICmd())
 
 	return cmd
 }
@@ -54,6 +60,18 @@ func newCodeScriptsCmd() *cobra.Command {
 	return cmd
 }
 
+func newCodeOpenAPICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   cmdUseOpenAPI,
+		Short: "Generate the OpenAPI specification for version 2 of the API",
+		RunE:  codeOpenAPIRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
 func newCodeKeysCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   cmdUseKeys,
@@ -206,3 +224,160 @@ func codeKeysRunE(cmd *cobra.Command, args []string) (err error) {
 
 	return nil
 }
+
+func codeOpenAPIRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		pathOpenAPI, pathOpenAPIV2, pathMiddlewares string
+		data                                        []byte
+	)
+
+	if pathOpenAPI, err = getPFlagPath(cmd.Flags(), cmdFlagRoot, cmdFlagFileOpenAPI); err != nil {
+		return err
+	}
+
+	if pathOpenAPIV2, err = getPFlagPath(cmd.Flags(), cmdFlagRoot, cmdFlagFileOpenAPIV2); err != nil {
+		return err
+	}
+
+	if pathMiddlewares, err = getPFlagPath(cmd.Flags(), cmdFlagRoot, cmdFlagDirMiddlewares); err != nil {
+		return err
+	}
+
+	tmplData := tmplOpenAPIErrorCodeData{}
+
+	if tmplData.ErrorCodes, err = readErrorCodes(pathMiddlewares); err != nil {
+		return err
+	}
+
+	if data, err = os.ReadFile(pathOpenAPI); err != nil {
+		return fmt.Errorf("failed to read file '%s': %w", pathOpenAPI, err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = tmplOpenAPIErrorCode.Execute(buf, tmplData); err != nil {
+		return fmt.Errorf("failed to render the error code schema: %w", err)
+	}
+
+	schemaErrorCode := strings.TrimSuffix(buf.String(), "\n")
+
+	lines := strings.Split(string(data), "\n")
+
+	output := make([]string, 0, len(lines)+len(tmplData.ErrorCodes)*2+20)
+
+	output = append(output,
+		"# Code generated by go generate. DO NOT EDIT.",
+		"#",
+		"# Run the following command to generate this file:",
+		"# \t\tgo run ./cmd/authelia-gen code openapi",
+		"#",
+	)
+
+	var errorResponse bool
+
+	for _, line := range lines {
+		switch {
+		case line == openAPIVersion1:
+			line = openAPIVersion2
+		case strings.HasPrefix(line, openAPIPathPrefix) && !strings.HasPrefix(line, openAPIPathPrefixOIDC) && !strings.HasPrefix(line, openAPIPathPrefixSAML):
+			line = openAPIPathPrefixV2 + strings.TrimPrefix(line, openAPIPathPrefix)
+		case line == openAPISchemaErrorResp:
+			output = append(output, schemaErrorCode)
+
+			errorResponse = true
+		case errorResponse && line == openAPIPropertyMessage:
+			output = append(output,
+				"        code:",
+				"          $ref: '#/components/schemas/middlewares.ErrorCode'",
+			)
+
+			errorResponse = false
+		}
+
+		output = append(output, line)
+	}
+
+	if errorResponse {
+		return fmt.Errorf("failed to find the message property of the error response schema in file '%s'", pathOpenAPI)
+	}
+
+	if err = os.WriteFile(pathOpenAPIV2, []byte(strings.Join(output, "\n")), 0644); err != nil { //nolint:gosec
+		return fmt.Errorf("failed to write output file '%s': %w", pathOpenAPIV2, err)
+	}
+
+	return nil
+}
+
+// readErrorCodes reads the ErrorCode constants and the descriptions from their doc comments from the go files in the
+// given directory.
+func readErrorCodes(dir string) (codes []openAPIErrorCode, err error) {
+	fset := token.NewFileSet()
+
+	var pkgs map[string]*ast.Package
+
+	if pkgs, err = parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments); err != nil {
+		return nil, fmt.Errorf("failed to parse the go files in directory '%s': %w", dir, err)
+	}
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.CONST {
+					continue
+				}
+
+				for _, spec := range gen.Specs {
+					value, ok := spec.(*ast.ValueSpec)
+					if !ok || len(value.Names) != 1 || len(value.Values) != 1 {
+						continue
+					}
+
+					if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != goTypeErrorCode {
+						continue
+					}
+
+					var code openAPIErrorCode
+
+					if code, err = newErrorCode(value); err != nil {
+						return nil, err
+					}
+
+					codes = append(codes, code)
+				}
+			}
+		}
+	}
+
+	if len(codes) == 0 {
+		return nil, fmt.Errorf("failed to find any error codes in directory '%s'", dir)
+	}
+
+	return codes, nil
+}
+
+func newErrorCode(value *ast.ValueSpec) (code openAPIErrorCode, err error) {
+	name := value.Names[0].Name
+
+	lit, ok := value.Values[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return code, fmt.Errorf("failed to read the value of error code '%s': the value must be a string literal", name)
+	}
+
+	if code.Value, err = strconv.Unquote(lit.Value); err != nil {
+		return code, fmt.Errorf("failed to read the value of error code '%s': %w", name, err)
+	}
+
+	doc := strings.Join(strings.Fields(value.Doc.Text()), " ")
+
+	description, ok := strings.CutPrefix(doc, name+goDocErrorCodeSeparator)
+	if !ok {
+		return code, fmt.Errorf("failed to read the description of error code '%s': the doc comment must start with '%s%s'", name, name, goDocErrorCodeSeparator)
+	}
+
+	code.Description = "Returned when " + description
+
+	return code, nil
+}
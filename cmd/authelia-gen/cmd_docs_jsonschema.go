@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -338,56 +339,12 @@ func getJSONSchemaOutputPath(cmd *cobra.Command, flag string) (dir, file string,
 }
 
 func jsonschemaKoanfMapper(t reflect.Type) *jsonschema.Schema {
-	switch t.String() {
-	case "regexp.Regexp", "*regexp.Regexp":
-		return &jsonschema.Schema{
-			Type:   jsonschema.TypeString,
-			Format: jsonschema.FormatStringRegex,
-		}
-	case "time.Duration", "*time.Duration":
-		return &jsonschema.Schema{
-			OneOf: []*jsonschema.Schema{
-				{
-					Type:    jsonschema.TypeString,
-					Pattern: `^\d+\s*(y|M|w|d|h|m|s|ms|((year|month|week|day|hour|minute|second|millisecond)s?))(\s*\d+\s*(y|M|w|d|h|m|s|ms|((year|month|week|day|hour|minute|second|millisecond)s?)))*$`,
-				},
-				{
-					Type:        jsonschema.TypeInteger,
-					Description: "The duration in seconds",
-				},
-			},
-		}
-	case "schema.CryptographicKey":
-		return &jsonschema.Schema{
-			Type:    jsonschema.TypeString,
-			Pattern: `^-{5}BEGIN (((RSA|EC) )?(PRIVATE|PUBLIC) KEY|CERTIFICATE)-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (((RSA|EC) )?(PRIVATE|PUBLIC) KEY|CERTIFICATE)-{5}\n?$`,
-		}
-	case "schema.CryptographicPrivateKey":
-		return &jsonschema.Schema{
-			Type:    jsonschema.TypeString,
-			Pattern: `^-{5}BEGIN ((RSA|EC) )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END ((RSA|EC) )?PRIVATE KEY-{5}\n?$`,
-		}
-	case "rsa.PrivateKey", "*rsa.PrivateKey":
-		return &jsonschema.Schema{
-			Type:    jsonschema.TypeString,
-			Pattern: `^-{5}(BEGIN (RSA )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (RSA )?PRIVATE KEY-{5}\n?)+$`,
-		}
-	case "ecdsa.PrivateKey", "*.ecdsa.PrivateKey":
-		return &jsonschema.Schema{
-			Type:    jsonschema.TypeString,
-			Pattern: `^-{5}(BEGIN ((EC )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (EC )?PRIVATE KEY-{5}\n?)+$`,
-		}
-	case "mail.Address", "*mail.Address":
-		return &jsonschema.Schema{
-			Type:   jsonschema.TypeString,
-			Format: jsonschema.FormatStringEmail,
-		}
-	case "schema.CSPTemplate":
+	if t.String() == "schema.CSPTemplate" {
 		return &jsonschema.Schema{
 			Type:    jsonschema.TypeString,
 			Default: buildCSP(codeCSPProductionDefaultSrc, codeCSPValuesCommon, codeCSPValuesProduction),
 		}
 	}
 
-	return nil
+	return configuration.JSONSchemaKoanfMapper(t)
 }
@@ -55,6 +55,9 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().String(cmdFlagDocsStaticJSONSchemaExportsWebAuthn, fileDocsStaticJSONSchemasExportsWebAuthn, "Sets the path of the WebAuthn export JSONSchema")
 	cmd.PersistentFlags().String(cmdFlagDocsStaticJSONSchemaExportsIdentifiers, fileDocsStaticJSONSchemasExportsIdentifiers, "Sets the path of the identifiers export JSONSchema")
 	cmd.PersistentFlags().String(cmdFlagFileServerGenerated, fileServerGenerated, "Sets the path of the server generated file")
+	cmd.PersistentFlags().String(cmdFlagFileOpenAPI, fileOpenAPI, "Sets the path of the OpenAPI specification file")
+	cmd.PersistentFlags().String(cmdFlagFileOpenAPIV2, fileOpenAPIV2, "Sets the path of the generated OpenAPI specification file for version 2 of the API")
+	cmd.PersistentFlags().String(cmdFlagDirMiddlewares, dirMiddlewares, "The middlewares directory in relation to the root")
 	cmd.PersistentFlags().String(cmdFlagPackageScriptsGen, pkgScriptsGen, "Sets the package name of the authelia-scripts gen file")
 	cmd.PersistentFlags().String(cmdFlagFileConfigCommitLint, fileCICommitLintConfig, "The commit lint javascript configuration file in relation to the root")
 	cmd.PersistentFlags().String(cmdFlagFileDocsCommitMsgGuidelines, fileDocsCommitMessageGuidelines, "The commit message guidelines documentation file in relation to the root")
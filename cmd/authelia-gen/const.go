@@ -17,6 +17,9 @@ const (
 	fileCodeConfigKeys  = "internal/configuration/schema/keys.go"
 	fileServerGenerated = "internal/server/gen.go"
 	fileScriptsGen      = "cmd/authelia-scripts/cmd/gen.go"
+	fileOpenAPI         = "api/openapi.yml"
+	fileOpenAPIV2       = "api/openapi.v2.yml"
+	dirMiddlewares      = "internal/middlewares"
 
 	dirDocs                  = "docs"
 	dirDocsContent           = "content"
@@ -81,6 +84,7 @@ const (
 	cmdUseCodeScripts            = "scripts"
 	cmdUseKeys                   = "keys"
 	cmdUseServer                 = "server"
+	cmdUseOpenAPI                = "openapi"
 )
 
 const (
@@ -109,6 +113,9 @@ const (
 	cmdFlagFileConfigKeys                         = "file.configuration-keys"
 	cmdFlagFileScriptsGen                         = "file.scripts.gen"
 	cmdFlagFileServerGenerated                    = "file.server.generated"
+	cmdFlagFileOpenAPI                            = "file.openapi"
+	cmdFlagFileOpenAPIV2                          = "file.openapi.v2"
+	cmdFlagDirMiddlewares                         = "dir.middlewares"
 	cmdFlagFileConfigCommitLint                   = "file.commit-lint-config"
 	cmdFlagFileDocsCommitMsgGuidelines            = "file.docs-commit-msg-guidelines"
 	cmdFlagFeatureRequest                         = "file.feature-request"
@@ -138,6 +145,20 @@ const (
 	goModuleBase = "github.com/authelia/authelia/v4"
 )
 
+const (
+	openAPIVersion1        = "  version: 1.0.0"
+	openAPIVersion2        = "  version: 2.0.0"
+	openAPIPathPrefix      = "  /api/"
+	openAPIPathPrefixV2    = "  /api/v2/"
+	openAPIPathPrefixOIDC  = "  /api/oidc/"
+	openAPIPathPrefixSAML  = "  /api/saml/"
+	openAPISchemaErrorResp = "    middlewares.ErrorResponse:"
+	openAPIPropertyMessage = "        message:"
+
+	goTypeErrorCode         = "ErrorCode"
+	goDocErrorCodeSeparator = " is returned when "
+)
+
 const (
 	windows = "windows"
 )
@@ -20,6 +20,15 @@ type tmplConfigurationKeysData struct {
 	Package   string
 }
 
+type tmplOpenAPIErrorCodeData struct {
+	ErrorCodes []openAPIErrorCode
+}
+
+type openAPIErrorCode struct {
+	Value       string
+	Description string
+}
+
 type tmplScriptsGEnData struct {
 	Package          string
 	VersionSwaggerUI string
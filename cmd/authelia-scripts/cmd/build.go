@@ -146,13 +146,20 @@ func buildFrontend(branch string) {
 }
 
 func buildSwagger() {
-	cmd := utils.CommandWithStdout("bash", "-c", "wget -q https://github.com/swagger-api/swagger-ui/archive/v"+versionSwaggerUI+".tar.gz -O ./v"+versionSwaggerUI+extTarballGzip)
+	cmd := utils.CommandWithStdout("go", "run", "./cmd/authelia-gen", "code", "openapi")
 
 	err := cmd.Run()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	cmd = utils.CommandWithStdout("bash", "-c", "wget -q https://github.com/swagger-api/swagger-ui/archive/v"+versionSwaggerUI+".tar.gz -O ./v"+versionSwaggerUI+extTarballGzip)
+
+	err = cmd.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	cmd = utils.CommandWithStdout("cp", "-r", "api", "internal/server/public_html")
 
 	err = cmd.Run()
@@ -186,7 +193,7 @@ func cleanAssets() {
 		log.Fatal(err)
 	}
 
-	cmd = utils.CommandWithStdout("bash", "-c", "touch internal/server/public_html/{index.html,api/index.html,api/openapi.yml}")
+	cmd = utils.CommandWithStdout("bash", "-c", "touch internal/server/public_html/{index.html,api/index.html,api/openapi.yml,api/openapi.v2.yml}")
 
 	if err := cmd.Run(); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,292 @@
+package harness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// NewClient returns a Client which performs the requests with a new session against the portal of the target.
+func NewClient(target *Target) (client *Client, err error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		target: target,
+		base:   target.PortalURL,
+		jar:    jar,
+		http: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: target.TLS},
+			Timeout:   target.timeout(),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}, nil
+}
+
+// Client is a HTTP client for the Authelia API which keeps the session cookies of the portal between requests.
+type Client struct {
+	target *Target
+	base   *url.URL
+	jar    http.CookieJar
+	http   *http.Client
+
+	instance bool
+}
+
+// Instance returns a Client which shares the session of this Client but performs the requests against the Authelia
+// instance with the URL rather than the portal.
+func (c *Client) Instance(instance *url.URL) *Client {
+	return &Client{
+		target:   c.target,
+		base:     instance,
+		jar:      c.jar,
+		http:     c.http,
+		instance: true,
+	}
+}
+
+// State returns the state of the session.
+func (c *Client) State(ctx context.Context) (state *State, err error) {
+	state = &State{}
+
+	if err = c.doAPI(ctx, http.MethodGet, pathState, nil, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// FirstFactor authenticates the session with the username and password of the user. The redirect is returned if the
+// targetURL is a safe redirection URL.
+func (c *Client) FirstFactor(ctx context.Context, user User, keepMeLoggedIn bool, targetURL string) (redirect string, err error) {
+	body := FirstFactorRequest{
+		Username:       user.Username,
+		Password:       user.Password,
+		KeepMeLoggedIn: &keepMeLoggedIn,
+		TargetURL:      targetURL,
+	}
+
+	response := RedirectResponse{}
+
+	if err = c.doAPI(ctx, http.MethodPost, pathFirstFactor, body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Redirect, nil
+}
+
+// SecondFactorTOTP authenticates the session with the current TOTP code of the user. The redirect is returned if the
+// targetURL is a safe redirection URL.
+func (c *Client) SecondFactorTOTP(ctx context.Context, user User, targetURL string) (redirect string, err error) {
+	var code string
+
+	if code, err = history.next(ctx, user); err != nil {
+		return "", err
+	}
+
+	response := RedirectResponse{}
+
+	if err = c.doAPI(ctx, http.MethodPost, pathSecondFactorTOTP, TOTPRequest{Token: code, TargetURL: targetURL}, &response); err != nil {
+		return "", err
+	}
+
+	return response.Redirect, nil
+}
+
+// Logout destroys the session.
+func (c *Client) Logout(ctx context.Context) (err error) {
+	return c.doAPI(ctx, http.MethodPost, pathLogout, struct{}{}, nil)
+}
+
+// Authz performs the request built by the Proxy of the target which determines if a request with the method to the
+// resource is authorized and returns the status code and the Location header of the response.
+func (c *Client) Authz(ctx context.Context, method string, resource *url.URL) (status int, location string, err error) {
+	if c.target.Proxy == nil {
+		return 0, "", fmt.Errorf("the target does not have a proxy")
+	}
+
+	var (
+		req *http.Request
+		res *http.Response
+	)
+
+	if req, err = c.target.Proxy.NewAuthzRequest(ctx, c.base, method, resource); err != nil {
+		return 0, "", err
+	}
+
+	if res, err = c.do(req); err != nil {
+		return 0, "", err
+	}
+
+	defer res.Body.Close()
+
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	return res.StatusCode, res.Header.Get(headerLocation), nil
+}
+
+// Do performs the request with the cookies of the session.
+func (c *Client) Do(req *http.Request) (res *http.Response, err error) {
+	return c.do(req)
+}
+
+func (c *Client) doAPI(ctx context.Context, method, path string, body, v any) (err error) {
+	var (
+		req *http.Request
+		res *http.Response
+	)
+
+	if req, err = c.newAPIRequest(ctx, method, path, body); err != nil {
+		return err
+	}
+
+	if res, err = c.do(req); err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	return decodeAPIResponse(path, res, v)
+}
+
+func (c *Client) newAPIRequest(ctx context.Context, method, path string, body any) (req *http.Request, err error) {
+	var reader io.Reader
+
+	if body != nil {
+		var data []byte
+
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, method, c.base.JoinPath(path).String(), reader); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerAccept, contentTypeApplicationJSON)
+
+	if body != nil {
+		req.Header.Set(headerContentType, contentTypeApplicationJSON)
+	}
+
+	if c.target.Headless && method != http.MethodGet && method != http.MethodHead {
+		var token string
+
+		if token, err = c.csrf(ctx); err != nil {
+			return nil, fmt.Errorf("error retrieving the csrf token: %w", err)
+		}
+
+		req.Header.Set(headerCSRFToken, token)
+	}
+
+	return req, nil
+}
+
+func (c *Client) csrf(ctx context.Context) (token string, err error) {
+	response := CSRFTokenResponse{}
+
+	if err = c.doAPI(ctx, http.MethodGet, pathCSRF, nil, &response); err != nil {
+		return "", err
+	}
+
+	return response.Token, nil
+}
+
+// do performs the request with the cookies of the session. The requests to an instance use the cookies of the portal
+// and have the Host and X-Forwarded-* headers of the portal.
+func (c *Client) do(req *http.Request) (res *http.Response, err error) {
+	cookies := req.URL
+
+	if c.instance && req.URL.Host == c.base.Host {
+		cookies = c.target.PortalURL
+
+		req.Host = c.target.PortalURL.Host
+		req.Header.Set(headerXForwardedProto, c.target.PortalURL.Scheme)
+		req.Header.Set(headerXForwardedHost, c.target.PortalURL.Host)
+	}
+
+	for _, cookie := range c.jar.Cookies(cookies) {
+		req.AddCookie(cookie)
+	}
+
+	if res, err = c.http.Do(req); err != nil {
+		return nil, err
+	}
+
+	if values := res.Cookies(); len(values) != 0 {
+		c.jar.SetCookies(cookies, values)
+	}
+
+	return res, nil
+}
+
+func decodeAPIResponse(path string, res *http.Response, v any) (err error) {
+	response := APIResponse{}
+
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding the response to '%s' with status code %d: %w", path, res.StatusCode, err)
+	}
+
+	if res.StatusCode != http.StatusOK || response.Status != statusOK {
+		return &APIError{Path: path, StatusCode: res.StatusCode, Message: response.Message}
+	}
+
+	if v == nil || len(response.Data) == 0 {
+		return nil
+	}
+
+	if err = json.Unmarshal(response.Data, v); err != nil {
+		return fmt.Errorf("error decoding the response data to '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// history is the history of the TOTP codes used by each user which prevents the same TOTP code being used twice as
+// the TOTP codes can only be used once if the reuse security policy is enabled.
+var history = &totpHistory{codes: map[string]string{}}
+
+type totpHistory struct {
+	mu    sync.Mutex
+	codes map[string]string
+}
+
+// next returns the current TOTP code of the user, waiting for the next TOTP code if the current one has already been
+// used.
+func (h *totpHistory) next(ctx context.Context, user User) (code string, err error) {
+	h.mu.Lock()
+
+	defer h.mu.Unlock()
+
+	for {
+		if code, err = user.TOTP(); err != nil {
+			return "", err
+		}
+
+		if code != h.codes[user.Username] {
+			h.codes[user.Username] = code
+
+			return code, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package harness
+
+import (
+	"time"
+)
+
+const (
+	envCI   = "CI"
+	strTrue = "true"
+
+	dockerProjectDefault = "authelia"
+)
+
+const (
+	pathState                   = "/api/state"
+	pathFirstFactor             = "/api/firstfactor"
+	pathSecondFactorTOTP        = "/api/secondfactor/totp"
+	pathLogout                  = "/api/logout"
+	pathCSRF                    = "/api/csrf"
+	pathOpenIDConnectDiscovery  = "/.well-known/openid-configuration"
+	pathAuthzForwardAuthDefault = "/api/authz/forward-auth"
+	pathAuthzAuthRequestDefault = "/api/authz/auth-request"
+	pathAuthzExtAuthzDefault    = "/api/authz/ext-authz"
+)
+
+const (
+	headerContentType     = "Content-Type"
+	headerAccept          = "Accept"
+	headerLocation        = "Location"
+	headerAuthorization   = "Authorization"
+	headerCSRFToken       = "X-CSRF-Token"
+	headerXForwardedProto = "X-Forwarded-Proto"
+	headerXForwardedHost  = "X-Forwarded-Host"
+	headerXForwardedURI   = "X-Forwarded-URI"
+	headerXForwardedFor   = "X-Forwarded-For"
+	headerXForwardedMeth  = "X-Forwarded-Method"
+	headerXOriginalURL    = "X-Original-URL"
+	headerXOriginalMethod = "X-Original-Method"
+
+	contentTypeApplicationJSON = "application/json"
+	contentTypeFormURLEncoded  = "application/x-www-form-urlencoded"
+	acceptHTML                 = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+)
+
+const (
+	statusOK = "OK"
+)
+
+// Level is the authentication level of a session as reported by the state endpoint.
+type Level int
+
+const (
+	// Anonymous is the authentication level of a session which hasn't been authenticated.
+	Anonymous Level = iota
+
+	// OneFactor is the authentication level of a session which has been authenticated with the first factor.
+	OneFactor
+
+	// TwoFactor is the authentication level of a session which has been authenticated with the second factor.
+	TwoFactor
+)
+
+const (
+	defaultTimeout          = 10 * time.Second
+	defaultFailoverTimeout  = time.Minute
+	defaultFailoverInterval = 2 * time.Second
+)
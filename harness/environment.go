@@ -0,0 +1,42 @@
+package harness
+
+import (
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// Environment is the environment a Target is deployed with. It's used by the scenarios which need to control the
+// services of the deployment such as the FailoverScenario.
+type Environment interface {
+	Up() error
+	Down() error
+	Start(service string) error
+	Stop(service string) error
+	Restart(service string) error
+	Logs(service string, flags []string) (string, error)
+}
+
+// WaitUntilServiceLogDetected waits until one of the patterns is detected in the last 20 lines of the logs of the
+// service, or the timeout is reached.
+func WaitUntilServiceLogDetected(interval, timeout time.Duration, environment Environment, service string, patterns []string) error {
+	log.Debug("Waiting for service " + service + " to be ready...")
+
+	return utils.CheckUntil(interval, timeout, func() (bool, error) {
+		logs, err := environment.Logs(service, []string{"--tail", "20"})
+		if err != nil {
+			return false, err
+		}
+
+		for _, pattern := range patterns {
+			if strings.Contains(logs, pattern) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
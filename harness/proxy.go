@@ -0,0 +1,181 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Proxy is the reverse proxy integration used by the forward authentication scenarios. The Proxy builds the request
+// which is performed to determine if a request to a protected resource is authorized, which is either the
+// authorization request the proxy would make to Authelia or a request to the resource through the proxy itself.
+type Proxy interface {
+	// Name returns the name of the proxy.
+	Name() string
+
+	// NewAuthzRequest returns the request which determines if a request with the method to the resource is authorized.
+	// The authelia URL is the URL of the Authelia instance the authorization request should be made to.
+	NewAuthzRequest(ctx context.Context, authelia *url.URL, method string, resource *url.URL) (req *http.Request, err error)
+}
+
+// NewProxy returns the Proxy which emulates the proxy with the name using the authz implementation it integrates with
+// by default, i.e. 'traefik', 'caddy', 'haproxy', and 'skipper' use the ForwardAuth implementation, 'nginx' uses the
+// AuthRequest implementation, and 'envoy' and 'istio' use the ExtAuthz implementation.
+func NewProxy(name string) (proxy Proxy, err error) {
+	switch strings.ToLower(name) {
+	case "traefik", "caddy", "haproxy", "skipper":
+		return NewForwardAuthProxy(name, ""), nil
+	case "nginx":
+		return NewAuthRequestProxy(name, ""), nil
+	case "envoy", "istio":
+		return NewExtAuthzProxy(name, ""), nil
+	default:
+		return nil, fmt.Errorf("the proxy '%s' is unknown", name)
+	}
+}
+
+// NewForwardAuthProxy returns a Proxy which emulates a proxy which uses the ForwardAuth authz implementation. The path
+// defaults to '/api/authz/forward-auth' if empty.
+func NewForwardAuthProxy(name, path string) *ForwardAuthProxy {
+	if path == "" {
+		path = pathAuthzForwardAuthDefault
+	}
+
+	return &ForwardAuthProxy{name: name, path: path}
+}
+
+// ForwardAuthProxy is a Proxy which emulates a proxy which uses the ForwardAuth authz implementation such as Traefik,
+// Caddy, HAProxy, and Skipper.
+type ForwardAuthProxy struct {
+	name string
+	path string
+}
+
+// Name returns the name of the proxy.
+func (p *ForwardAuthProxy) Name() string {
+	return p.name
+}
+
+// NewAuthzRequest returns the ForwardAuth authorization request which uses the X-Forwarded-* headers to describe the
+// request to the resource.
+func (p *ForwardAuthProxy) NewAuthzRequest(ctx context.Context, authelia *url.URL, method string, resource *url.URL) (req *http.Request, err error) {
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, authelia.JoinPath(p.path).String(), nil); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerXForwardedMeth, method)
+	req.Header.Set(headerXForwardedProto, resource.Scheme)
+	req.Header.Set(headerXForwardedHost, resource.Host)
+	req.Header.Set(headerXForwardedURI, resource.RequestURI())
+	req.Header.Set(headerAccept, acceptHTML)
+
+	return req, nil
+}
+
+// NewAuthRequestProxy returns a Proxy which emulates a proxy which uses the AuthRequest authz implementation. The path
+// defaults to '/api/authz/auth-request' if empty.
+func NewAuthRequestProxy(name, path string) *AuthRequestProxy {
+	if path == "" {
+		path = pathAuthzAuthRequestDefault
+	}
+
+	return &AuthRequestProxy{name: name, path: path}
+}
+
+// AuthRequestProxy is a Proxy which emulates a proxy which uses the AuthRequest authz implementation such as NGINX.
+type AuthRequestProxy struct {
+	name string
+	path string
+}
+
+// Name returns the name of the proxy.
+func (p *AuthRequestProxy) Name() string {
+	return p.name
+}
+
+// NewAuthzRequest returns the AuthRequest authorization request which uses the X-Original-* headers to describe the
+// request to the resource.
+func (p *AuthRequestProxy) NewAuthzRequest(ctx context.Context, authelia *url.URL, method string, resource *url.URL) (req *http.Request, err error) {
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, authelia.JoinPath(p.path).String(), nil); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerXOriginalMethod, method)
+	req.Header.Set(headerXOriginalURL, resource.String())
+	req.Header.Set(headerAccept, acceptHTML)
+
+	return req, nil
+}
+
+// NewExtAuthzProxy returns a Proxy which emulates a proxy which uses the ExtAuthz authz implementation. The path
+// defaults to '/api/authz/ext-authz' if empty.
+func NewExtAuthzProxy(name, path string) *ExtAuthzProxy {
+	if path == "" {
+		path = pathAuthzExtAuthzDefault
+	}
+
+	return &ExtAuthzProxy{name: name, path: path}
+}
+
+// ExtAuthzProxy is a Proxy which emulates a proxy which uses the ExtAuthz authz implementation such as Envoy and Istio.
+type ExtAuthzProxy struct {
+	name string
+	path string
+}
+
+// Name returns the name of the proxy.
+func (p *ExtAuthzProxy) Name() string {
+	return p.name
+}
+
+// NewAuthzRequest returns the ExtAuthz authorization request which has the method of the request to the resource, the
+// request URI of the resource appended to the path, and the host of the resource as the Host header.
+func (p *ExtAuthzProxy) NewAuthzRequest(ctx context.Context, authelia *url.URL, method string, resource *url.URL) (req *http.Request, err error) {
+	uri := authelia.JoinPath(p.path).String() + resource.RequestURI()
+
+	if req, err = http.NewRequestWithContext(ctx, method, uri, nil); err != nil {
+		return nil, err
+	}
+
+	req.Host = resource.Host
+	req.Header.Set(headerXForwardedProto, resource.Scheme)
+	req.Header.Set(headerAccept, acceptHTML)
+
+	return req, nil
+}
+
+// NewPassthroughProxy returns a Proxy which requests the resources through the proxy itself rather than emulating the
+// authorization request the proxy makes to Authelia. This tests the complete integration of the proxy.
+func NewPassthroughProxy(name string) *PassthroughProxy {
+	return &PassthroughProxy{name: name}
+}
+
+// PassthroughProxy is a Proxy which requests the resources through the proxy itself.
+type PassthroughProxy struct {
+	name string
+}
+
+// Name returns the name of the proxy.
+func (p *PassthroughProxy) Name() string {
+	return p.name
+}
+
+// NewAuthzRequest returns the request to the resource, the authelia URL is ignored.
+func (p *PassthroughProxy) NewAuthzRequest(ctx context.Context, _ *url.URL, method string, resource *url.URL) (req *http.Request, err error) {
+	if req, err = http.NewRequestWithContext(ctx, method, resource.String(), nil); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(headerAccept, acceptHTML)
+
+	return req, nil
+}
+
+var (
+	_ Proxy = (*ForwardAuthProxy)(nil)
+	_ Proxy = (*AuthRequestProxy)(nil)
+	_ Proxy = (*ExtAuthzProxy)(nil)
+	_ Proxy = (*PassthroughProxy)(nil)
+)
@@ -0,0 +1,123 @@
+package harness
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Proxy
+		err      string
+	}{
+		{"Traefik", &ForwardAuthProxy{name: "Traefik", path: "/api/authz/forward-auth"}, ""},
+		{"caddy", &ForwardAuthProxy{name: "caddy", path: "/api/authz/forward-auth"}, ""},
+		{"HAProxy", &ForwardAuthProxy{name: "HAProxy", path: "/api/authz/forward-auth"}, ""},
+		{"NGINX", &AuthRequestProxy{name: "NGINX", path: "/api/authz/auth-request"}, ""},
+		{"Envoy", &ExtAuthzProxy{name: "Envoy", path: "/api/authz/ext-authz"}, ""},
+		{"apache", nil, "the proxy 'apache' is unknown"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proxy, err := NewProxy(tc.name)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, proxy)
+				assert.Equal(t, tc.name, proxy.Name())
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, proxy)
+			}
+		})
+	}
+}
+
+func TestProxyNewAuthzRequest(t *testing.T) {
+	authelia := &url.URL{Scheme: "https", Host: "auth.example.com", Path: "/authelia"}
+	resource := &url.URL{Scheme: "https", Host: "app.example.com", Path: "/path", RawQuery: "a=b"}
+
+	testCases := []struct {
+		name    string
+		proxy   Proxy
+		method  string
+		uri     string
+		host    string
+		headers map[string]string
+	}{
+		{
+			"ShouldBuildForwardAuth",
+			NewForwardAuthProxy("Traefik", ""),
+			http.MethodGet,
+			"https://auth.example.com/authelia/api/authz/forward-auth",
+			"auth.example.com",
+			map[string]string{
+				"X-Forwarded-Method": "POST",
+				"X-Forwarded-Proto":  "https",
+				"X-Forwarded-Host":   "app.example.com",
+				"X-Forwarded-URI":    "/path?a=b",
+			},
+		},
+		{
+			"ShouldBuildForwardAuthCustomPath",
+			NewForwardAuthProxy("Traefik", "/api/authz/custom"),
+			http.MethodGet,
+			"https://auth.example.com/authelia/api/authz/custom",
+			"auth.example.com",
+			map[string]string{
+				"X-Forwarded-Method": "POST",
+			},
+		},
+		{
+			"ShouldBuildAuthRequest",
+			NewAuthRequestProxy("NGINX", ""),
+			http.MethodGet,
+			"https://auth.example.com/authelia/api/authz/auth-request",
+			"auth.example.com",
+			map[string]string{
+				"X-Original-Method": "POST",
+				"X-Original-URL":    "https://app.example.com/path?a=b",
+			},
+		},
+		{
+			"ShouldBuildExtAuthz",
+			NewExtAuthzProxy("Envoy", ""),
+			http.MethodPost,
+			"https://auth.example.com/authelia/api/authz/ext-authz/path?a=b",
+			"app.example.com",
+			map[string]string{
+				"X-Forwarded-Proto": "https",
+			},
+		},
+		{
+			"ShouldBuildPassthrough",
+			NewPassthroughProxy("NGINX"),
+			http.MethodPost,
+			"https://app.example.com/path?a=b",
+			"app.example.com",
+			map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := tc.proxy.NewAuthzRequest(context.Background(), authelia, http.MethodPost, resource)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.method, req.Method)
+			assert.Equal(t, tc.uri, req.URL.String())
+			assert.Equal(t, tc.host, req.Host)
+
+			for header, value := range tc.headers {
+				assert.Equal(t, value, req.Header.Get(header), header)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package harness
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// Run runs each of the scenarios against the target as a subtest.
+func Run(t *testing.T, target *Target) {
+	t.Run("Login", func(t *testing.T) {
+		suite.Run(t, NewLoginScenario(target))
+	})
+
+	t.Run("TwoFactor", func(t *testing.T) {
+		suite.Run(t, NewTwoFactorScenario(target))
+	})
+
+	t.Run("ForwardAuth", func(t *testing.T) {
+		suite.Run(t, NewForwardAuthScenario(target))
+	})
+
+	t.Run("OpenIDConnect", func(t *testing.T) {
+		suite.Run(t, NewOpenIDConnectScenario(target))
+	})
+
+	t.Run("HighAvailability", func(t *testing.T) {
+		suite.Run(t, NewHighAvailabilityScenario(target))
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		suite.Run(t, NewFailoverScenario(target))
+	})
+}
+
+// scenario is the common implementation of the scenarios, each test has a new Client and as such a new session.
+type scenario struct {
+	suite.Suite
+
+	target *Target
+	client *Client
+}
+
+func (s *scenario) SetupTest() {
+	client, err := NewClient(s.target)
+	s.Require().NoError(err)
+
+	s.client = client
+}
+
+func (s *scenario) context() (ctx context.Context, cancel context.CancelFunc) {
+	return context.WithTimeout(context.Background(), s.target.timeout()*4)
+}
+
+func (s *scenario) requireTOTP() {
+	if s.target.User.TOTPSecret == "" {
+		s.T().Skip("the user of the target does not have a TOTP secret")
+	}
+}
+
+func (s *scenario) requireResource(resource *url.URL) {
+	if resource == nil {
+		s.T().Skip("the target does not have the resource")
+	}
+}
+
+func (s *scenario) firstFactor(ctx context.Context, client *Client) {
+	_, err := client.FirstFactor(ctx, s.target.User, false, "")
+	s.Require().NoError(err)
+}
+
+func (s *scenario) secondFactor(ctx context.Context, client *Client) {
+	s.firstFactor(ctx, client)
+
+	_, err := client.SecondFactorTOTP(ctx, s.target.User, "")
+	s.Require().NoError(err)
+}
+
+// authenticate authenticates the client with the second factor if the user has a TOTP secret, otherwise only the
+// first factor.
+func (s *scenario) authenticate(ctx context.Context, client *Client) {
+	if s.target.User.TOTPSecret == "" {
+		s.firstFactor(ctx, client)
+
+		return
+	}
+
+	s.secondFactor(ctx, client)
+}
+
+func (s *scenario) assertState(ctx context.Context, client *Client, level Level) {
+	state, err := client.State(ctx)
+	s.Require().NoError(err)
+
+	s.Equal(level, state.AuthenticationLevel)
+
+	if level == Anonymous {
+		s.Equal("", state.Username)
+	} else {
+		s.Equal(s.target.User.Username, state.Username)
+	}
+}
@@ -0,0 +1,87 @@
+package harness
+
+import (
+	"context"
+	"time"
+)
+
+// NewFailoverScenario returns the FailoverScenario for the target.
+func NewFailoverScenario(target *Target) *FailoverScenario {
+	return &FailoverScenario{scenario: &scenario{target: target}}
+}
+
+// FailoverScenario tests the target recovers from the failure of each of its Failover services by stopping them one
+// at a time with the Environment of the target. The tests are skipped if the target doesn't have an Environment or any
+// Failover services.
+type FailoverScenario struct {
+	*scenario
+}
+
+func (s *FailoverScenario) SetupTest() {
+	if s.target.Environment == nil || len(s.target.Failover) == 0 {
+		s.T().Skip("the target does not have an environment or failover services")
+	}
+
+	s.scenario.SetupTest()
+}
+
+func (s *FailoverScenario) TestShouldKeepSessionDuringFailover() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.target.failoverTimeout()*time.Duration(len(s.target.Failover)*2+1))
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+
+	for _, service := range s.target.Failover {
+		s.failover(service, func() bool {
+			state, err := s.client.State(ctx)
+
+			return err == nil && state.AuthenticationLevel == OneFactor
+		})
+	}
+
+	s.assertState(ctx, s.client, OneFactor)
+}
+
+func (s *FailoverScenario) TestShouldAuthenticateDuringFailover() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.target.failoverTimeout()*time.Duration(len(s.target.Failover)*2+1))
+	defer cancel()
+
+	for _, service := range s.target.Failover {
+		s.failover(service, func() bool {
+			client, err := NewClient(s.target)
+			if err != nil {
+				return false
+			}
+
+			if _, err = client.FirstFactor(ctx, s.target.User, false, ""); err != nil {
+				return false
+			}
+
+			state, err := client.State(ctx)
+
+			return err == nil && state.AuthenticationLevel == OneFactor
+		})
+	}
+}
+
+// failover stops the service and asserts the condition is eventually met while the service is stopped, then starts
+// the service and asserts the condition is eventually met after the service has recovered.
+func (s *FailoverScenario) failover(service string, condition func() bool) {
+	s.Require().NoError(s.target.Environment.Stop(service))
+
+	started := false
+
+	defer func() {
+		if !started {
+			_ = s.target.Environment.Start(service)
+		}
+	}()
+
+	s.Eventually(condition, s.target.failoverTimeout(), defaultFailoverInterval, "the condition was not met while the service '%s' was stopped", service)
+
+	s.Require().NoError(s.target.Environment.Start(service))
+
+	started = true
+
+	s.Eventually(condition, s.target.failoverTimeout(), defaultFailoverInterval, "the condition was not met after the service '%s' was started", service)
+}
@@ -0,0 +1,125 @@
+package harness
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// NewForwardAuthScenario returns the ForwardAuthScenario for the target.
+func NewForwardAuthScenario(target *Target) *ForwardAuthScenario {
+	return &ForwardAuthScenario{scenario: &scenario{target: target}}
+}
+
+// ForwardAuthScenario tests the authorization of the requests to the resources of the target using the Proxy of the
+// target. The tests are skipped if the target doesn't have a Proxy.
+type ForwardAuthScenario struct {
+	*scenario
+}
+
+func (s *ForwardAuthScenario) SetupTest() {
+	if s.target.Proxy == nil {
+		s.T().Skip("the target does not have a proxy")
+	}
+
+	s.scenario.SetupTest()
+}
+
+func (s *ForwardAuthScenario) TestShouldAllowBypassResource() {
+	s.requireResource(s.target.Resources.Bypass)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	status, _, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.Bypass)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusOK, status)
+}
+
+func (s *ForwardAuthScenario) TestShouldRedirectAnonymousUserToPortal() {
+	s.requireResource(s.target.Resources.OneFactor)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	status, location, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.OneFactor)
+	s.Require().NoError(err)
+
+	s.assertRedirectToPortal(status, location, s.target.Resources.OneFactor)
+}
+
+func (s *ForwardAuthScenario) TestShouldAllowOneFactorResourceAfterFirstFactor() {
+	s.requireResource(s.target.Resources.OneFactor)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+
+	status, _, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.OneFactor)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusOK, status)
+}
+
+func (s *ForwardAuthScenario) TestShouldRequireSecondFactorForTwoFactorResource() {
+	s.requireResource(s.target.Resources.TwoFactor)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+
+	status, location, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.TwoFactor)
+	s.Require().NoError(err)
+
+	s.assertRedirectToPortal(status, location, s.target.Resources.TwoFactor)
+}
+
+func (s *ForwardAuthScenario) TestShouldAllowTwoFactorResourceAfterSecondFactor() {
+	s.requireResource(s.target.Resources.TwoFactor)
+	s.requireTOTP()
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.secondFactor(ctx, s.client)
+
+	status, _, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.TwoFactor)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusOK, status)
+}
+
+func (s *ForwardAuthScenario) TestShouldForbidDenyResource() {
+	s.requireResource(s.target.Resources.Deny)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.authenticate(ctx, s.client)
+
+	status, _, err := s.client.Authz(ctx, http.MethodGet, s.target.Resources.Deny)
+	s.Require().NoError(err)
+
+	s.Equal(http.StatusForbidden, status)
+}
+
+// assertRedirectToPortal asserts the response redirects the user to the portal with the resource as the redirection
+// URL. The AuthRequest implementation responds with a 401 Unauthorized status code and the Location header rather than
+// a redirection as the proxy performs the redirection.
+func (s *ForwardAuthScenario) assertRedirectToPortal(status int, location string, resource *url.URL) {
+	s.Contains([]int{http.StatusFound, http.StatusSeeOther, http.StatusUnauthorized}, status)
+
+	if status == http.StatusUnauthorized && location == "" {
+		return
+	}
+
+	s.Require().True(strings.HasPrefix(location, s.target.PortalURL.String()), "the location '%s' is not the portal '%s'", location, s.target.PortalURL.String())
+
+	redirect, err := url.Parse(location)
+	s.Require().NoError(err)
+
+	s.Equal(resource.String(), redirect.Query().Get("rd"))
+}
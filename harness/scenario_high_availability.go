@@ -0,0 +1,60 @@
+package harness
+
+// NewHighAvailabilityScenario returns the HighAvailabilityScenario for the target.
+func NewHighAvailabilityScenario(target *Target) *HighAvailabilityScenario {
+	return &HighAvailabilityScenario{scenario: &scenario{target: target}}
+}
+
+// HighAvailabilityScenario tests the sessions are shared between each of the instances of a highly available target.
+// The tests are skipped if the target has less than two instances.
+type HighAvailabilityScenario struct {
+	*scenario
+}
+
+func (s *HighAvailabilityScenario) SetupTest() {
+	if len(s.target.Instances) < 2 {
+		s.T().Skip("the target has less than two instances")
+	}
+
+	s.scenario.SetupTest()
+}
+
+func (s *HighAvailabilityScenario) TestShouldShareSessionBetweenInstances() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client.Instance(s.target.Instances[0]))
+
+	for _, instance := range s.target.Instances {
+		s.assertState(ctx, s.client.Instance(instance), OneFactor)
+	}
+}
+
+func (s *HighAvailabilityScenario) TestShouldShareSecondFactorBetweenInstances() {
+	s.requireTOTP()
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client.Instance(s.target.Instances[0]))
+
+	_, err := s.client.Instance(s.target.Instances[1]).SecondFactorTOTP(ctx, s.target.User, "")
+	s.Require().NoError(err)
+
+	for _, instance := range s.target.Instances {
+		s.assertState(ctx, s.client.Instance(instance), TwoFactor)
+	}
+}
+
+func (s *HighAvailabilityScenario) TestShouldShareLogoutBetweenInstances() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client.Instance(s.target.Instances[0]))
+
+	s.Require().NoError(s.client.Instance(s.target.Instances[1]).Logout(ctx))
+
+	for _, instance := range s.target.Instances {
+		s.assertState(ctx, s.client.Instance(instance), Anonymous)
+	}
+}
@@ -0,0 +1,67 @@
+package harness
+
+import (
+	"net/http"
+)
+
+// NewLoginScenario returns the LoginScenario for the target.
+func NewLoginScenario(target *Target) *LoginScenario {
+	return &LoginScenario{scenario: &scenario{target: target}}
+}
+
+// LoginScenario tests the first factor authentication and the logout of the user of the target.
+type LoginScenario struct {
+	*scenario
+}
+
+func (s *LoginScenario) TestShouldAuthenticateFirstFactor() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.assertState(ctx, s.client, Anonymous)
+
+	s.firstFactor(ctx, s.client)
+
+	s.assertState(ctx, s.client, OneFactor)
+}
+
+func (s *LoginScenario) TestShouldRejectIncorrectPassword() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	user := s.target.User
+	user.Password += "-incorrect"
+
+	_, err := s.client.FirstFactor(ctx, user, false, "")
+
+	var apiErr *APIError
+
+	s.Require().ErrorAs(err, &apiErr)
+	s.Equal(http.StatusUnauthorized, apiErr.StatusCode)
+
+	s.assertState(ctx, s.client, Anonymous)
+}
+
+func (s *LoginScenario) TestShouldRedirectToTargetURL() {
+	s.requireResource(s.target.Resources.OneFactor)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	redirect, err := s.client.FirstFactor(ctx, s.target.User, false, s.target.Resources.OneFactor.String())
+	s.Require().NoError(err)
+
+	s.Equal(s.target.Resources.OneFactor.String(), redirect)
+}
+
+func (s *LoginScenario) TestShouldLogout() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+	s.assertState(ctx, s.client, OneFactor)
+
+	s.Require().NoError(s.client.Logout(ctx))
+
+	s.assertState(ctx, s.client, Anonymous)
+}
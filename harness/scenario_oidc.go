@@ -0,0 +1,219 @@
+package harness
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+// NewOpenIDConnectScenario returns the OpenIDConnectScenario for the target.
+func NewOpenIDConnectScenario(target *Target) *OpenIDConnectScenario {
+	return &OpenIDConnectScenario{scenario: &scenario{target: target}}
+}
+
+// OpenIDConnectScenario tests the OpenID Connect 1.0 provider of the target with the authorization code flow. The tests
+// are skipped if the target doesn't have an OpenID Connect 1.0 client.
+type OpenIDConnectScenario struct {
+	*scenario
+}
+
+func (s *OpenIDConnectScenario) SetupTest() {
+	if s.target.OpenIDConnect == nil {
+		s.T().Skip("the target does not have an openid connect client")
+	}
+
+	s.scenario.SetupTest()
+}
+
+func (s *OpenIDConnectScenario) TestShouldServeDiscoveryAndKeys() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	discovery := s.discovery(ctx)
+
+	s.NotEmpty(discovery.Issuer)
+	s.NotEmpty(discovery.AuthorizationEndpoint)
+	s.NotEmpty(discovery.TokenEndpoint)
+	s.Require().NotEmpty(discovery.JWKSURI)
+
+	keys := struct {
+		Keys []json.RawMessage `json:"keys"`
+	}{}
+
+	s.getJSON(ctx, discovery.JWKSURI, &keys)
+
+	s.NotEmpty(keys.Keys)
+}
+
+func (s *OpenIDConnectScenario) TestShouldRedirectAnonymousUserToPortal() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	discovery := s.discovery(ctx)
+
+	res := s.authorize(ctx, discovery.AuthorizationEndpoint, "state-anonymous", newPKCEVerifier())
+
+	s.Contains([]int{http.StatusFound, http.StatusSeeOther}, res.StatusCode)
+
+	location := res.Header.Get(headerLocation)
+
+	s.True(s.isPortal(location), "the location '%s' is not the portal '%s'", location, s.target.PortalURL.String())
+}
+
+func (s *OpenIDConnectScenario) TestShouldIssueTokensWithAuthorizationCodeFlow() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	discovery := s.discovery(ctx)
+
+	s.authenticate(ctx, s.client)
+
+	verifier := newPKCEVerifier()
+
+	res := s.authorize(ctx, discovery.AuthorizationEndpoint, "state-authenticated", verifier)
+
+	// The authorization endpoint may redirect to itself before redirecting to the redirect URI, for example to remove
+	// the parameters used for the consent flow.
+	for i := 0; i < 5 && !s.isRedirectURI(res.Header.Get(headerLocation)); i++ {
+		s.Require().Contains([]int{http.StatusFound, http.StatusSeeOther}, res.StatusCode)
+
+		location := res.Header.Get(headerLocation)
+
+		s.Require().False(s.isPortal(location), "the authorization endpoint redirected to the portal '%s' which indicates the client requires consent or a higher authentication level", location)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		s.Require().NoError(err)
+
+		res, err = s.client.Do(req)
+		s.Require().NoError(err)
+
+		s.Require().NoError(res.Body.Close())
+	}
+
+	redirect, err := url.Parse(res.Header.Get(headerLocation))
+	s.Require().NoError(err)
+	s.Require().True(s.isRedirectURI(redirect.String()), "the authorization endpoint did not redirect to the redirect uri")
+
+	query := redirect.Query()
+
+	s.Require().Equal("", query.Get("error"), query.Get("error_description"))
+	s.Equal("state-authenticated", query.Get("state"))
+	s.Require().NotEmpty(query.Get("code"))
+
+	tokens := s.token(ctx, discovery.TokenEndpoint, query.Get("code"), verifier)
+
+	s.NotEmpty(tokens.AccessToken)
+	s.NotEmpty(tokens.IDToken)
+	s.True(strings.EqualFold("bearer", tokens.TokenType))
+}
+
+func (s *OpenIDConnectScenario) discovery(ctx context.Context) (discovery *OpenIDConnectDiscovery) {
+	discovery = &OpenIDConnectDiscovery{}
+
+	s.getJSON(ctx, s.target.PortalURL.JoinPath(pathOpenIDConnectDiscovery).String(), discovery)
+
+	return discovery
+}
+
+func (s *OpenIDConnectScenario) authorize(ctx context.Context, endpoint, state, verifier string) (res *http.Response) {
+	uri, err := url.Parse(endpoint)
+	s.Require().NoError(err)
+
+	digest := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{}
+	query.Set("client_id", s.target.OpenIDConnect.ID)
+	query.Set("redirect_uri", s.target.OpenIDConnect.RedirectURI.String())
+	query.Set("response_type", "code")
+	query.Set("scope", "openid")
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(digest[:]))
+	query.Set("code_challenge_method", "S256")
+
+	uri.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	s.Require().NoError(err)
+
+	req.Header.Set(headerAccept, acceptHTML)
+
+	res, err = s.client.Do(req)
+	s.Require().NoError(err)
+
+	s.Require().NoError(res.Body.Close())
+
+	return res
+}
+
+func (s *OpenIDConnectScenario) token(ctx context.Context, endpoint, code, verifier string) (tokens *OpenIDConnectTokenResponse) {
+	client := s.target.OpenIDConnect
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", client.RedirectURI.String())
+	form.Set("code_verifier", verifier)
+
+	if client.ClientSecretPost {
+		form.Set("client_id", client.ID)
+		form.Set("client_secret", client.Secret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	s.Require().NoError(err)
+
+	req.Header.Set(headerContentType, contentTypeFormURLEncoded)
+	req.Header.Set(headerAccept, contentTypeApplicationJSON)
+
+	if !client.ClientSecretPost {
+		req.SetBasicAuth(url.QueryEscape(client.ID), url.QueryEscape(client.Secret))
+	}
+
+	res, err := s.client.Do(req)
+	s.Require().NoError(err)
+
+	defer res.Body.Close()
+
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+
+	tokens = &OpenIDConnectTokenResponse{}
+
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(tokens))
+
+	return tokens
+}
+
+func (s *OpenIDConnectScenario) getJSON(ctx context.Context, uri string, v any) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	s.Require().NoError(err)
+
+	req.Header.Set(headerAccept, contentTypeApplicationJSON)
+
+	res, err := s.client.Do(req)
+	s.Require().NoError(err)
+
+	defer res.Body.Close()
+
+	s.Require().Equal(http.StatusOK, res.StatusCode)
+	s.Require().NoError(json.NewDecoder(res.Body).Decode(v))
+}
+
+// isPortal returns true if the location is a page of the portal rather than an endpoint of the API.
+func (s *OpenIDConnectScenario) isPortal(location string) bool {
+	return strings.HasPrefix(location, s.target.PortalURL.String()) && !strings.HasPrefix(location, s.target.PortalURL.JoinPath("/api/").String())
+}
+
+func (s *OpenIDConnectScenario) isRedirectURI(location string) bool {
+	return strings.HasPrefix(location, s.target.OpenIDConnect.RedirectURI.String())
+}
+
+// newPKCEVerifier returns a random PKCE code verifier.
+func newPKCEVerifier() string {
+	return (&random.Cryptographical{}).StringCustom(64, random.CharSetRFC3986Unreserved)
+}
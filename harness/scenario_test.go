@@ -0,0 +1,304 @@
+package harness
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/authelia/otp"
+	"github.com/authelia/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testCookieName = "authelia_session"
+	testUsername   = "john"
+	testPassword   = "password"
+	testTOTPSecret = "JBSWY3DPEHPK3PXP"
+)
+
+var testTOTPOptions = totp.ValidateOpts{Period: 1, Skew: 1, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// testAuthelia is a minimal implementation of the Authelia API and the ForwardAuth authz implementation which is
+// shared by multiple instances.
+type testAuthelia struct {
+	mu       sync.Mutex
+	portal   *url.URL
+	sessions map[string]Level
+	counter  int
+}
+
+func (a *testAuthelia) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/state", func(w http.ResponseWriter, r *http.Request) {
+		level := a.level(r)
+
+		state := State{AuthenticationLevel: level}
+
+		if level != Anonymous {
+			state.Username = testUsername
+		}
+
+		a.respond(w, http.StatusOK, state)
+	})
+
+	mux.HandleFunc("POST /api/firstfactor", func(w http.ResponseWriter, r *http.Request) {
+		body := FirstFactorRequest{}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Username != testUsername || body.Password != testPassword {
+			a.fail(w, http.StatusUnauthorized)
+
+			return
+		}
+
+		a.mu.Lock()
+		a.counter++
+		id := strconv.Itoa(a.counter)
+		a.sessions[id] = OneFactor
+		a.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: testCookieName, Value: id, Path: "/"})
+
+		a.respondRedirect(w, body.TargetURL)
+	})
+
+	mux.HandleFunc("POST /api/secondfactor/totp", func(w http.ResponseWriter, r *http.Request) {
+		body := TOTPRequest{}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || a.level(r) == Anonymous {
+			a.fail(w, http.StatusUnauthorized)
+
+			return
+		}
+
+		if ok, _ := totp.ValidateCustom(body.Token, testTOTPSecret, time.Now(), testTOTPOptions); !ok {
+			a.fail(w, http.StatusUnauthorized)
+
+			return
+		}
+
+		a.set(r, TwoFactor)
+
+		a.respondRedirect(w, body.TargetURL)
+	})
+
+	mux.HandleFunc("POST /api/logout", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(testCookieName); err == nil {
+			a.mu.Lock()
+			delete(a.sessions, cookie.Value)
+			a.mu.Unlock()
+		}
+
+		a.respond(w, http.StatusOK, nil)
+	})
+
+	mux.HandleFunc("GET /api/authz/forward-auth", func(w http.ResponseWriter, r *http.Request) {
+		resource := &url.URL{Scheme: r.Header.Get(headerXForwardedProto), Host: r.Header.Get(headerXForwardedHost), Path: r.Header.Get(headerXForwardedURI)}
+
+		var required Level
+
+		switch resource.Host {
+		case "bypass.example.com":
+			required = Anonymous
+		case "one.example.com":
+			required = OneFactor
+		case "two.example.com":
+			required = TwoFactor
+		default:
+			if a.level(r) != Anonymous {
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			required = OneFactor
+		}
+
+		if a.level(r) < required {
+			redirect := *a.portal
+			redirect.RawQuery = url.Values{"rd": []string{resource.String()}}.Encode()
+
+			http.Redirect(w, r, redirect.String(), http.StatusFound)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func (a *testAuthelia) level(r *http.Request) Level {
+	cookie, err := r.Cookie(testCookieName)
+	if err != nil {
+		return Anonymous
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.sessions[cookie.Value]
+}
+
+func (a *testAuthelia) set(r *http.Request, level Level) {
+	cookie, err := r.Cookie(testCookieName)
+	if err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.sessions[cookie.Value] = level
+}
+
+func (a *testAuthelia) respondRedirect(w http.ResponseWriter, targetURL string) {
+	if targetURL == "" {
+		a.respond(w, http.StatusOK, nil)
+
+		return
+	}
+
+	a.respond(w, http.StatusOK, RedirectResponse{Redirect: targetURL})
+}
+
+func (a *testAuthelia) respond(w http.ResponseWriter, status int, data any) {
+	response := APIResponse{Status: statusOK}
+
+	if data != nil {
+		response.Data, _ = json.Marshal(data)
+	}
+
+	w.Header().Set(headerContentType, contentTypeApplicationJSON)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func (a *testAuthelia) fail(w http.ResponseWriter, status int) {
+	w.Header().Set(headerContentType, contentTypeApplicationJSON)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(APIResponse{Status: "KO", Message: "Authentication failed. Check your credentials."})
+}
+
+type testEnvironment struct {
+	stopped []string
+	started []string
+}
+
+func (e *testEnvironment) Up() error { return nil }
+
+func (e *testEnvironment) Down() error { return nil }
+
+func (e *testEnvironment) Start(service string) error {
+	e.started = append(e.started, service)
+
+	return nil
+}
+
+func (e *testEnvironment) Stop(service string) error {
+	e.stopped = append(e.stopped, service)
+
+	return nil
+}
+
+func (e *testEnvironment) Restart(_ string) error { return nil }
+
+func (e *testEnvironment) Logs(_ string, _ []string) (string, error) { return "", nil }
+
+func newTestTarget(t *testing.T) (target *Target, environment *testEnvironment) {
+	authelia := &testAuthelia{sessions: map[string]Level{}}
+
+	portal := httptest.NewServer(authelia.handler())
+	instance := httptest.NewServer(authelia.handler())
+
+	t.Cleanup(portal.Close)
+	t.Cleanup(instance.Close)
+
+	portalURL, err := url.Parse(portal.URL)
+	require.NoError(t, err)
+
+	instanceURL, err := url.Parse(instance.URL)
+	require.NoError(t, err)
+
+	authelia.portal = portalURL
+	environment = &testEnvironment{}
+
+	return &Target{
+		PortalURL: portalURL,
+		Instances: []*url.URL{portalURL, instanceURL},
+		Proxy:     NewForwardAuthProxy("Traefik", ""),
+		Resources: Resources{
+			Bypass:    &url.URL{Scheme: "https", Host: "bypass.example.com", Path: "/"},
+			OneFactor: &url.URL{Scheme: "https", Host: "one.example.com", Path: "/secret.html"},
+			TwoFactor: &url.URL{Scheme: "https", Host: "two.example.com", Path: "/secret.html"},
+			Deny:      &url.URL{Scheme: "https", Host: "deny.example.com", Path: "/"},
+		},
+		User: User{
+			Username:    testUsername,
+			Password:    testPassword,
+			TOTPSecret:  testTOTPSecret,
+			TOTPOptions: testTOTPOptions,
+		},
+		Environment:     environment,
+		Failover:        []string{"redis-node-0"},
+		FailoverTimeout: time.Second * 5,
+	}, environment
+}
+
+func TestScenarios(t *testing.T) {
+	target, environment := newTestTarget(t)
+
+	Run(t, target)
+
+	assert.Equal(t, []string{"redis-node-0", "redis-node-0"}, environment.stopped)
+	assert.Equal(t, []string{"redis-node-0", "redis-node-0"}, environment.started)
+}
+
+func TestScenariosShouldSkipWithoutOptionalTarget(t *testing.T) {
+	target, _ := newTestTarget(t)
+
+	target.Instances = nil
+	target.Proxy = nil
+	target.Environment = nil
+	target.User.TOTPSecret = ""
+
+	suite.Run(t, NewTwoFactorScenario(target))
+	suite.Run(t, NewForwardAuthScenario(target))
+	suite.Run(t, NewOpenIDConnectScenario(target))
+	suite.Run(t, NewHighAvailabilityScenario(target))
+	suite.Run(t, NewFailoverScenario(target))
+}
+
+func TestUserTOTP(t *testing.T) {
+	user := User{Username: testUsername}
+
+	code, err := user.TOTP()
+	assert.EqualError(t, err, "the user 'john' does not have a TOTP secret")
+	assert.Equal(t, "", code)
+
+	user.TOTPSecret = testTOTPSecret
+
+	code, err = user.TOTP()
+	require.NoError(t, err)
+
+	expected, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, code)
+}
+
+func TestAPIError(t *testing.T) {
+	assert.EqualError(t, &APIError{Path: "/api/state", StatusCode: 500}, "the request to '/api/state' failed with status code 500")
+	assert.EqualError(t, &APIError{Path: "/api/firstfactor", StatusCode: 401, Message: "Authentication failed."}, "the request to '/api/firstfactor' failed with status code 401: Authentication failed.")
+}
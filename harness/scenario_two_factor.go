@@ -0,0 +1,55 @@
+package harness
+
+// NewTwoFactorScenario returns the TwoFactorScenario for the target.
+func NewTwoFactorScenario(target *Target) *TwoFactorScenario {
+	return &TwoFactorScenario{scenario: &scenario{target: target}}
+}
+
+// TwoFactorScenario tests the second factor authentication of the user of the target with the TOTP second factor. The
+// tests are skipped if the user doesn't have a TOTP secret.
+type TwoFactorScenario struct {
+	*scenario
+}
+
+func (s *TwoFactorScenario) SetupTest() {
+	s.requireTOTP()
+
+	s.scenario.SetupTest()
+}
+
+func (s *TwoFactorScenario) TestShouldAuthenticateSecondFactor() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+	s.assertState(ctx, s.client, OneFactor)
+
+	_, err := s.client.SecondFactorTOTP(ctx, s.target.User, "")
+	s.Require().NoError(err)
+
+	s.assertState(ctx, s.client, TwoFactor)
+}
+
+func (s *TwoFactorScenario) TestShouldRejectSecondFactorWithoutFirstFactor() {
+	ctx, cancel := s.context()
+	defer cancel()
+
+	_, err := s.client.SecondFactorTOTP(ctx, s.target.User, "")
+	s.Require().Error(err)
+
+	s.assertState(ctx, s.client, Anonymous)
+}
+
+func (s *TwoFactorScenario) TestShouldRedirectToTargetURL() {
+	s.requireResource(s.target.Resources.TwoFactor)
+
+	ctx, cancel := s.context()
+	defer cancel()
+
+	s.firstFactor(ctx, s.client)
+
+	redirect, err := s.client.SecondFactorTOTP(ctx, s.target.User, s.target.Resources.TwoFactor.String())
+	s.Require().NoError(err)
+
+	s.Equal(s.target.Resources.TwoFactor.String(), redirect)
+}
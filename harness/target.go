@@ -0,0 +1,124 @@
+package harness
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/authelia/otp"
+	"github.com/authelia/otp/totp"
+)
+
+// Target describes the Authelia deployment the scenarios are run against. Only the PortalURL and User are required,
+// the scenarios which require the other options skip the tests which can't be performed when they're not configured.
+type Target struct {
+	// PortalURL is the URL of the login portal including the path prefix if any.
+	PortalURL *url.URL
+
+	// Instances are the URLs of each individual Authelia instance of a highly available deployment which are used by
+	// the HighAvailabilityScenario. The requests to the instances have the Host and X-Forwarded-* headers of the
+	// PortalURL so the instances can be reached directly rather than through the proxy.
+	Instances []*url.URL
+
+	// Proxy is the reverse proxy integration used by the ForwardAuthScenario.
+	Proxy Proxy
+
+	// Resources are the resources protected by the Proxy.
+	Resources Resources
+
+	// User is the user the scenarios authenticate as.
+	User User
+
+	// OpenIDConnect is the OpenID Connect 1.0 client used by the OpenIDConnectScenario.
+	OpenIDConnect *OpenIDConnectClient
+
+	// Environment is the environment the Target is deployed with which is used by the FailoverScenario.
+	Environment Environment
+
+	// Failover are the services of the Environment which are stopped one at a time by the FailoverScenario.
+	Failover []string
+
+	// Headless must be true when the server headless mode is enabled so the CSRF token is included in the requests.
+	Headless bool
+
+	// TLS is the TLS configuration used for the requests. The system roots are used if nil.
+	TLS *tls.Config
+
+	// Timeout is the timeout of each request, defaults to 10 seconds.
+	Timeout time.Duration
+
+	// FailoverTimeout is the duration the FailoverScenario waits for the deployment to recover, defaults to 1 minute.
+	FailoverTimeout time.Duration
+}
+
+// Resources are the resources protected by the Proxy each of which should have the access control policy matching
+// their name. Any resource which is nil is skipped.
+type Resources struct {
+	Bypass    *url.URL
+	OneFactor *url.URL
+	TwoFactor *url.URL
+	Deny      *url.URL
+}
+
+// User is a user of the Target.
+type User struct {
+	Username string
+	Password string
+
+	// TOTPSecret is the base32 secret of the TOTP credential of the user. The tests which require the second factor are
+	// skipped if empty.
+	TOTPSecret string
+
+	// TOTPOptions are the options of the TOTP credential of the user, the period defaults to 30 seconds, the digits
+	// default to 6, and the algorithm defaults to SHA1.
+	TOTPOptions totp.ValidateOpts
+}
+
+// TOTP returns the current TOTP code of the user.
+func (u User) TOTP() (code string, err error) {
+	if u.TOTPSecret == "" {
+		return "", fmt.Errorf("the user '%s' does not have a TOTP secret", u.Username)
+	}
+
+	opts := u.TOTPOptions
+
+	if opts.Period == 0 {
+		opts.Period = 30
+	}
+
+	if opts.Digits == 0 {
+		opts.Digits = otp.DigitsSix
+	}
+
+	return totp.GenerateCodeCustom(u.TOTPSecret, time.Now(), opts)
+}
+
+// OpenIDConnectClient is a registered OpenID Connect 1.0 client which uses the authorization code flow. The client
+// must be permitted to use the 'openid' scope and the RedirectURI, and should have the 'implicit' consent mode so the
+// authorization code is issued without the consent page.
+type OpenIDConnectClient struct {
+	ID          string
+	Secret      string
+	RedirectURI *url.URL
+
+	// ClientSecretPost sends the client credentials in the body of the token request rather than the Authorization
+	// header.
+	ClientSecretPost bool
+}
+
+func (t *Target) timeout() time.Duration {
+	if t.Timeout > 0 {
+		return t.Timeout
+	}
+
+	return defaultTimeout
+}
+
+func (t *Target) failoverTimeout() time.Duration {
+	if t.FailoverTimeout > 0 {
+		return t.FailoverTimeout
+	}
+
+	return defaultFailoverTimeout
+}
@@ -0,0 +1,76 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// APIResponse is the envelope of the responses of the Authelia API.
+type APIResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// APIError is returned when the Authelia API responds with a status code other than 200 or a status other than OK.
+type APIError struct {
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("the request to '%s' failed with status code %d", e.Path, e.StatusCode)
+	}
+
+	return fmt.Sprintf("the request to '%s' failed with status code %d: %s", e.Path, e.StatusCode, e.Message)
+}
+
+// State is the state of a session.
+type State struct {
+	Username              string `json:"username"`
+	AuthenticationLevel   Level  `json:"authentication_level"`
+	DefaultRedirectionURL string `json:"default_redirection_url"`
+}
+
+// FirstFactorRequest is the body of the first factor request.
+type FirstFactorRequest struct {
+	Username       string `json:"username"`
+	Password       string `json:"password"`
+	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn,omitempty"`
+	TargetURL      string `json:"targetURL,omitempty"`
+}
+
+// TOTPRequest is the body of the TOTP second factor request.
+type TOTPRequest struct {
+	Token     string `json:"token"`
+	TargetURL string `json:"targetURL,omitempty"`
+}
+
+// RedirectResponse is the data of the responses which may redirect the user.
+type RedirectResponse struct {
+	Redirect string `json:"redirect"`
+}
+
+// CSRFTokenResponse is the data of the CSRF token response.
+type CSRFTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// OpenIDConnectDiscovery is the subset of the OpenID Connect 1.0 discovery document used by the OpenIDConnectScenario.
+type OpenIDConnectDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// OpenIDConnectTokenResponse is the subset of the OpenID Connect 1.0 token response used by the OpenIDConnectScenario.
+type OpenIDConnectTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// NewBus returns a new Bus which writes the events to the sinks from the configuration. The personal data of the events
+// is redacted by the redactor before the events are written to the sinks. The storage provider is only used by the
+// storage sinks.
+func NewBus(config *schema.Audit, redactor *logging.Redactor, provider StorageProvider, trusted *x509.CertPool, log *logrus.Entry) (bus *Bus, err error) {
+	bus = &Bus{
+		redactor: redactor,
+		log:      log,
+	}
+
+	for i := range config.Sinks {
+		var sink Sink
+
+		if sink, err = NewSink(&config.Sinks[i], provider, trusted); err != nil {
+			bus.close()
+
+			return nil, fmt.Errorf("error occurred creating the audit sink '%s': %w", config.Sinks[i].Name, err)
+		}
+
+		bus.Register(config.Sinks[i].Name, sink, NewFilter(config.Sinks[i].Events, config.Sinks[i].ExcludeEvents), config.BufferSize)
+	}
+
+	return bus, nil
+}
+
+// NewSink returns the Sink for the driver configured in the sink configuration.
+func NewSink(config *schema.AuditSink, provider StorageProvider, trusted *x509.CertPool) (sink Sink, err error) {
+	switch {
+	case config.File != nil:
+		return NewFileSink(config.File)
+	case config.Syslog != nil:
+		return NewSyslogSink(config.Syslog)
+	case config.Webhook != nil:
+		return NewWebhookSink(config.Webhook, trusted), nil
+	case config.Kafka != nil:
+		return NewKafkaSink(config.Kafka, trusted)
+	case config.Storage != nil:
+		if provider == nil {
+			return nil, fmt.Errorf("the storage provider is not available")
+		}
+
+		return NewStorageSink(provider), nil
+	default:
+		return nil, fmt.Errorf("no sink driver is configured")
+	}
+}
+
+// Bus is the central audit event bus which fans the emitted events out to the sinks. Each sink has its own queue and
+// writer so a slow or unavailable sink does not delay the other sinks or the requests emitting the events.
+type Bus struct {
+	sinks    []*busSink
+	redactor *logging.Redactor
+	log      *logrus.Entry
+
+	mu sync.RWMutex
+}
+
+type busSink struct {
+	name   string
+	sink   Sink
+	filter Filter
+	events chan *Event
+}
+
+// Register a sink with the Bus. The events which match the filter are queued for the sink, up to the buffer size.
+// Sinks must be registered before the Bus is run.
+func (b *Bus) Register(name string, sink Sink, filter Filter, buffer int) {
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	b.sinks = append(b.sinks, &busSink{name: name, sink: sink, filter: filter, events: make(chan *Event, buffer)})
+}
+
+// Emit queues the event for each of the sinks it matches the filter of. The event is dropped for any sink which has a
+// full queue so the requests are never blocked by the sinks.
+func (b *Bus) Emit(event Event) {
+	if event.ID == "" {
+		if id, err := uuid.NewRandom(); err == nil {
+			event.ID = id.String()
+		}
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.redact(&event)
+
+	b.mu.RLock()
+
+	defer b.mu.RUnlock()
+
+	for _, s := range b.sinks {
+		if !s.filter.Matches(event.Type) {
+			continue
+		}
+
+		select {
+		case s.events <- &event:
+		default:
+			b.log.WithFields(map[string]any{"sink": s.name, "type": event.Type}).Warn("Dropped audit event as the queue of the sink is full")
+		}
+	}
+}
+
+// Run writes the queued events to the sinks until the context is done. The events which are still queued are written
+// and the sinks are closed before it returns.
+func (b *Bus) Run(ctx context.Context) {
+	b.mu.RLock()
+
+	sinks := b.sinks
+
+	b.mu.RUnlock()
+
+	wg := &sync.WaitGroup{}
+
+	for _, s := range sinks {
+		wg.Add(1)
+
+		go func(s *busSink) {
+			defer wg.Done()
+
+			b.run(ctx, s)
+		}(s)
+	}
+
+	wg.Wait()
+}
+
+func (b *Bus) run(ctx context.Context, s *busSink) {
+	defer b.closeSink(s)
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-s.events:
+					b.write(context.Background(), s, event)
+				default:
+					return
+				}
+			}
+		case event := <-s.events:
+			b.write(ctx, s, event)
+		}
+	}
+}
+
+func (b *Bus) write(ctx context.Context, s *busSink, event *Event) {
+	if err := s.sink.Write(ctx, event); err != nil {
+		b.log.WithError(err).WithFields(map[string]any{"sink": s.name, "type": event.Type}).Error("Error occurred writing the audit event to the sink")
+	}
+}
+
+func (b *Bus) redact(event *Event) {
+	if b.redactor == nil {
+		return
+	}
+
+	event.Actor.Username = b.redactor.Username(event.Actor.Username)
+	event.Actor.RemoteIP = b.redactor.IP(event.Actor.RemoteIP)
+
+	if len(event.Details) == 0 {
+		return
+	}
+
+	details := make(map[string]any, len(event.Details))
+
+	for key, value := range event.Details {
+		if v, ok := value.(string); ok {
+			details[key] = b.redactor.String(v)
+		} else {
+			details[key] = value
+		}
+	}
+
+	event.Details = details
+}
+
+func (b *Bus) close() {
+	for _, s := range b.sinks {
+		b.closeSink(s)
+	}
+}
+
+func (b *Bus) closeSink(s *busSink) {
+	if err := s.sink.Close(); err != nil {
+		b.log.WithError(err).WithField("sink", s.name).Error("Error occurred closing the audit sink")
+	}
+}
@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+type testSink struct {
+	events []*Event
+	err    error
+	closed bool
+
+	mu sync.Mutex
+}
+
+func (s *testSink) Write(_ context.Context, event *Event) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+
+	return s.err
+}
+
+func (s *testSink) Close() (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+func (s *testSink) Events() []*Event {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	return s.events
+}
+
+func TestBus_ShouldWriteMatchingEventsToSinks(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	bus := &Bus{log: logrus.NewEntry(logger)}
+
+	all, auth := &testSink{}, &testSink{}
+
+	bus.Register("all", all, NewFilter(nil, nil), 10)
+	bus.Register("auth", auth, NewFilter([]string{"authentication.*"}, nil), 10)
+
+	bus.Emit(Event{Type: EventTypeAuthenticationFirstFactor, Result: ResultSuccess, Actor: EventActor{Username: "john"}})
+	bus.Emit(Event{Type: EventTypeSessionLogout, Result: ResultSuccess, Actor: EventActor{Username: "john"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	bus.Run(ctx)
+
+	require.Len(t, all.Events(), 2)
+	require.Len(t, auth.Events(), 1)
+
+	assert.Equal(t, EventTypeAuthenticationFirstFactor, all.Events()[0].Type)
+	assert.Equal(t, EventTypeSessionLogout, all.Events()[1].Type)
+	assert.Equal(t, EventTypeAuthenticationFirstFactor, auth.Events()[0].Type)
+
+	assert.NotEmpty(t, all.Events()[0].ID)
+	assert.False(t, all.Events()[0].Time.IsZero())
+
+	assert.True(t, all.closed)
+	assert.True(t, auth.closed)
+
+	assert.Len(t, hook.Entries, 0)
+}
+
+func TestBus_ShouldRetainIDAndTime(t *testing.T) {
+	bus := &Bus{log: logrus.NewEntry(logrus.New())}
+
+	sink := &testSink{}
+
+	bus.Register("sink", sink, NewFilter(nil, nil), 10)
+
+	now := time.Unix(1700000000, 0)
+
+	bus.Emit(Event{ID: "abc", Time: now, Type: EventTypeTOTPRegister})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	bus.Run(ctx)
+
+	require.Len(t, sink.Events(), 1)
+
+	assert.Equal(t, "abc", sink.Events()[0].ID)
+	assert.Equal(t, now, sink.Events()[0].Time)
+}
+
+func TestBus_ShouldDropEventsWhenQueueIsFull(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	bus := &Bus{log: logrus.NewEntry(logger)}
+
+	sink := &testSink{}
+
+	bus.Register("sink", sink, NewFilter(nil, nil), 1)
+
+	bus.Emit(Event{Type: EventTypeTOTPRegister})
+	bus.Emit(Event{Type: EventTypeTOTPDelete})
+
+	require.Len(t, hook.Entries, 1)
+
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Dropped audit event as the queue of the sink is full", hook.LastEntry().Message)
+	assert.Equal(t, "sink", hook.LastEntry().Data["sink"])
+	assert.Equal(t, EventTypeTOTPDelete, hook.LastEntry().Data["type"])
+}
+
+func TestBus_ShouldLogWriteErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	bus := &Bus{log: logrus.NewEntry(logger)}
+
+	sink := &testSink{err: errors.New("bad sink")}
+
+	bus.Register("sink", sink, NewFilter(nil, nil), 10)
+
+	bus.Emit(Event{Type: EventTypeTOTPRegister})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	bus.Run(ctx)
+
+	require.Len(t, hook.Entries, 1)
+
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Error occurred writing the audit event to the sink", hook.LastEntry().Message)
+	assert.EqualError(t, hook.LastEntry().Data["error"].(error), "bad sink")
+}
+
+func TestBus_ShouldRedactEvents(t *testing.T) {
+	redactor := logging.NewRedactor(schema.LogRedaction{Usernames: true, IPAddresses: true, Emails: true, IPv4PrefixLength: 24, IPv6PrefixLength: 48}, []byte("secret"))
+
+	bus := &Bus{redactor: redactor, log: logrus.NewEntry(logrus.New())}
+
+	sink := &testSink{}
+
+	bus.Register("sink", sink, NewFilter(nil, nil), 10)
+
+	details := map[string]any{"email": "john.doe@example.com", "banned": false}
+
+	bus.Emit(Event{Type: EventTypePasswordReset, Actor: EventActor{Username: "john", RemoteIP: "192.168.1.10"}, Details: details})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	bus.Run(ctx)
+
+	require.Len(t, sink.Events(), 1)
+
+	assert.Equal(t, redactor.Username("john"), sink.Events()[0].Actor.Username)
+	assert.Equal(t, "192.168.1.0", sink.Events()[0].Actor.RemoteIP)
+	assert.Equal(t, map[string]any{"email": "j***@example.com", "banned": false}, sink.Events()[0].Details)
+	assert.Equal(t, "john.doe@example.com", details["email"])
+}
+
+func TestNewSink(t *testing.T) {
+	dir := t.TempDir()
+
+	sink, err := NewSink(&schema.AuditSink{File: &schema.AuditSinkFile{Path: dir + "/audit.log"}}, nil, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &FileSink{}, sink)
+	assert.NoError(t, sink.Close())
+
+	sink, err = NewSink(&schema.AuditSink{Syslog: &schema.DefaultAuditSinkSyslogConfiguration}, nil, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &SyslogSink{}, sink)
+
+	sink, err = NewSink(&schema.AuditSink{Storage: &schema.AuditSinkStorage{}}, &testStorageProvider{}, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &StorageSink{}, sink)
+
+	sink, err = NewSink(&schema.AuditSink{Storage: &schema.AuditSinkStorage{}}, nil, nil)
+	assert.Nil(t, sink)
+	assert.EqualError(t, err, "the storage provider is not available")
+
+	sink, err = NewSink(&schema.AuditSink{}, nil, nil)
+	assert.Nil(t, sink)
+	assert.EqualError(t, err, "no sink driver is configured")
+}
@@ -0,0 +1,102 @@
+package audit
+
+// Event types.
+const (
+	EventTypeAuthenticationFirstFactor  = "authentication.first_factor"
+	EventTypeAuthenticationSecondFactor = "authentication.second_factor"
+	EventTypeAuthorizationDecision      = "authorization.decision"
+	EventTypeSessionLogout              = "session.logout"
+	EventTypeSessionElevation           = "session.elevation"
+	EventTypePasswordReset              = "password.reset"
+	EventTypeTOTPRegister               = "totp.register"
+	EventTypeTOTPDelete                 = "totp.delete"
+	EventTypeSMSRegister                = "sms.register"
+	EventTypeSMSDelete                  = "sms.delete"
+	EventTypeWebAuthnRegister           = "webauthn.register"
+	EventTypeWebAuthnUpdate             = "webauthn.update"
+	EventTypeWebAuthnDelete             = "webauthn.delete"
+	EventTypeRecoveryCodesGenerate      = "recovery_codes.generate"
+	EventTypeDeviceCertificateIssue     = "device_certificate.issue"
+	EventTypeDeviceCertificateRevoke    = "device_certificate.revoke"
+	EventTypeAppPasswordGenerate        = "app_password.generate"
+	EventTypeAppPasswordDelete          = "app_password.delete"
+	EventTypeMobileDeviceRegister       = "mobile_device.register"
+	EventTypeMobileDeviceRevoke         = "mobile_device.revoke"
+	EventTypePreferencesUpdate          = "preferences.update"
+	EventTypeOpenIDConnectConsent       = "oidc.consent"
+	EventTypeSAMLAssertion              = "saml.assertion"
+	EventTypeSAMLLogout                 = "saml.logout"
+	EventTypeRegulationBan              = "regulation.ban"
+	EventTypeRegulationUnban            = "regulation.unban"
+	EventTypeSessionRevoke              = "session.revoke"
+)
+
+// EventTypes is the list of all event types.
+var EventTypes = []string{
+	EventTypeAuthenticationFirstFactor,
+	EventTypeAuthenticationSecondFactor,
+	EventTypeAuthorizationDecision,
+	EventTypeSessionLogout,
+	EventTypeSessionElevation,
+	EventTypePasswordReset,
+	EventTypeTOTPRegister,
+	EventTypeTOTPDelete,
+	EventTypeSMSRegister,
+	EventTypeSMSDelete,
+	EventTypeWebAuthnRegister,
+	EventTypeWebAuthnUpdate,
+	EventTypeWebAuthnDelete,
+	EventTypeRecoveryCodesGenerate,
+	EventTypeDeviceCertificateIssue,
+	EventTypeDeviceCertificateRevoke,
+	EventTypeAppPasswordGenerate,
+	EventTypeAppPasswordDelete,
+	EventTypeMobileDeviceRegister,
+	EventTypeMobileDeviceRevoke,
+	EventTypePreferencesUpdate,
+	EventTypeOpenIDConnectConsent,
+	EventTypeSAMLAssertion,
+	EventTypeSAMLLogout,
+	EventTypeRegulationBan,
+	EventTypeRegulationUnban,
+	EventTypeSessionRevoke,
+}
+
+// Event results.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Sink types.
+const (
+	SinkTypeFile    = "file"
+	SinkTypeSyslog  = "syslog"
+	SinkTypeWebhook = "webhook"
+	SinkTypeKafka   = "kafka"
+	SinkTypeStorage = "storage"
+)
+
+// SASL mechanisms of the Kafka sink.
+const (
+	SASLMechanismPlain       = "plain"
+	SASLMechanismSCRAMSHA256 = "scram-sha-256"
+	SASLMechanismSCRAMSHA512 = "scram-sha-512"
+)
+
+// SASLMechanisms is the list of the SASL mechanisms supported by the Kafka sink.
+var SASLMechanisms = []string{SASLMechanismPlain, SASLMechanismSCRAMSHA256, SASLMechanismSCRAMSHA512}
+
+const (
+	syslogSeverityWarning = 4
+	syslogSeverityInfo    = 6
+
+	syslogVersion    = 1
+	syslogNil        = "-"
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+const (
+	patternAll      = "*"
+	patternWildcard = ".*"
+)
@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewFilter returns a new Filter given the event type patterns which are included and excluded.
+func NewFilter(include, exclude []string) Filter {
+	return Filter{include: include, exclude: exclude}
+}
+
+// Filter determines which event types are written to a sink. A pattern is either an event type, the '*' pattern
+// which matches every event type, or an event type category followed by '.*' such as 'authentication.*' which
+// matches every event type in the category.
+type Filter struct {
+	include []string
+	exclude []string
+}
+
+// Matches returns true if the event type is not excluded and is included, all event types are included if no include
+// patterns were provided.
+func (f Filter) Matches(eventType string) bool {
+	for _, pattern := range f.exclude {
+		if MatchesPattern(pattern, eventType) {
+			return false
+		}
+	}
+
+	if len(f.include) == 0 {
+		return true
+	}
+
+	for _, pattern := range f.include {
+		if MatchesPattern(pattern, eventType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MatchesPattern returns true if the event type matches the pattern.
+func MatchesPattern(pattern, eventType string) bool {
+	switch {
+	case pattern == patternAll:
+		return true
+	case strings.HasSuffix(pattern, patternWildcard):
+		return strings.HasPrefix(eventType, strings.TrimSuffix(pattern, patternAll))
+	default:
+		return pattern == eventType
+	}
+}
+
+// IsValidPattern returns true if the pattern matches at least one of the event types.
+func IsValidPattern(pattern string) bool {
+	return utils.IsStringInSliceF(pattern, EventTypes, MatchesPattern)
+}
@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	testCases := []struct {
+		name      string
+		include   []string
+		exclude   []string
+		eventType string
+		expected  bool
+	}{
+		{"ShouldMatchAllWhenNoPatterns", nil, nil, EventTypeAuthenticationFirstFactor, true},
+		{"ShouldMatchExact", []string{EventTypeAuthenticationFirstFactor}, nil, EventTypeAuthenticationFirstFactor, true},
+		{"ShouldNotMatchOtherExact", []string{EventTypeAuthenticationFirstFactor}, nil, EventTypeAuthenticationSecondFactor, false},
+		{"ShouldMatchCategory", []string{"authentication.*"}, nil, EventTypeAuthenticationSecondFactor, true},
+		{"ShouldNotMatchOtherCategory", []string{"authentication.*"}, nil, EventTypeAuthorizationDecision, false},
+		{"ShouldMatchAll", []string{"*"}, nil, EventTypeOpenIDConnectConsent, true},
+		{"ShouldExcludeExact", nil, []string{EventTypeAuthorizationDecision}, EventTypeAuthorizationDecision, false},
+		{"ShouldExcludeOverInclude", []string{"*"}, []string{"webauthn.*"}, EventTypeWebAuthnDelete, false},
+		{"ShouldNotExcludeOther", []string{"*"}, []string{"webauthn.*"}, EventTypeTOTPDelete, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewFilter(tc.include, tc.exclude).Matches(tc.eventType))
+		})
+	}
+}
+
+func TestIsValidPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected bool
+	}{
+		{"*", true},
+		{"authentication.*", true},
+		{"authentication.first_factor", true},
+		{"oidc.consent", true},
+		{"authentication", false},
+		{"authentication.third_factor", false},
+		{"example.*", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsValidPattern(tc.pattern))
+		})
+	}
+}
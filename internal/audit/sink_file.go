@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewFileSink returns a new FileSink which appends the events to the file at the configured path.
+func NewFileSink(config *schema.AuditSinkFile) (sink *FileSink, err error) {
+	var file *os.File
+
+	if file, err = os.OpenFile(config.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600); err != nil {
+		return nil, fmt.Errorf("error opening the file: %w", err)
+	}
+
+	return &FileSink{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// FileSink is a Sink which appends each event to a file as a line of JSON.
+type FileSink struct {
+	file    *os.File
+	encoder *json.Encoder
+
+	mu sync.Mutex
+}
+
+// Write the event to the file.
+func (s *FileSink) Write(_ context.Context, event *Event) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(event)
+}
+
+// Close the file.
+func (s *FileSink) Close() (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestFileSink_ShouldAppendEventsAsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\":\"existing\"}\n"), 0600))
+
+	sink, err := NewFileSink(&schema.AuditSinkFile{Path: path})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "1", Time: time.Unix(1700000000, 0).UTC(), Type: EventTypeSessionLogout, Result: ResultSuccess, Actor: EventActor{Username: "john"}}))
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "2", Time: time.Unix(1700000001, 0).UTC(), Type: EventTypePasswordReset, Result: ResultFailure}))
+	require.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	var lines []string
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	require.Len(t, lines, 3)
+
+	assert.Equal(t, `{"id":"existing"}`, lines[0])
+	assert.Equal(t, `{"id":"1","time":"2023-11-14T22:13:20Z","type":"session.logout","result":"success","actor":{"username":"john"},"request":{}}`, lines[1])
+
+	event := Event{}
+
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+
+	assert.Equal(t, "2", event.ID)
+	assert.Equal(t, EventTypePasswordReset, event.Type)
+	assert.Equal(t, ResultFailure, event.Result)
+}
+
+func TestFileSink_ShouldErrorOnInvalidPath(t *testing.T) {
+	sink, err := NewFileSink(&schema.AuditSinkFile{Path: filepath.Join(t.TempDir(), "missing", "audit.log")})
+
+	assert.Nil(t, sink)
+	assert.ErrorContains(t, err, "error opening the file: ")
+}
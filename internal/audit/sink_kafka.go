@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewKafkaSink returns a new KafkaSink which produces the events to the configured topic.
+func NewKafkaSink(config *schema.AuditSinkKafka, trusted *x509.CertPool) (sink *KafkaSink, err error) {
+	transport := &kafka.Transport{
+		DialTimeout: config.Timeout,
+	}
+
+	if config.TLS != nil {
+		transport.TLS = utils.NewTLSConfig(config.TLS, trusted)
+	}
+
+	if config.SASL != nil {
+		if transport.SASL, err = newKafkaSASLMechanism(config.SASL); err != nil {
+			return nil, err
+		}
+	}
+
+	return &KafkaSink{
+		timeout: config.Timeout,
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(config.Brokers...),
+			Topic:        config.Topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireOne,
+			BatchSize:    1,
+			WriteTimeout: config.Timeout,
+			Transport:    transport,
+		},
+	}, nil
+}
+
+func newKafkaSASLMechanism(config *schema.AuditSinkKafkaSASL) (mechanism sasl.Mechanism, err error) {
+	switch config.Mechanism {
+	case SASLMechanismPlain:
+		return plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case SASLMechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case SASLMechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
+	default:
+		return nil, fmt.Errorf("unknown sasl mechanism '%s'", config.Mechanism)
+	}
+}
+
+// KafkaSink is a Sink which produces each event to a Kafka topic as a JSON message keyed by the username so the
+// events of each user are kept in order.
+type KafkaSink struct {
+	timeout time.Duration
+	writer  *kafka.Writer
+}
+
+// Write the event to the Kafka topic.
+func (s *KafkaSink) Write(ctx context.Context, event *Event) (err error) {
+	var data []byte
+
+	if data, err = json.Marshal(event); err != nil {
+		return fmt.Errorf("error encoding the event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+
+	defer cancel()
+
+	if err = s.writer.WriteMessages(ctx, kafka.Message{Key: []byte(event.Actor.Username), Value: data, Time: event.Time}); err != nil {
+		return fmt.Errorf("error producing the event: %w", err)
+	}
+
+	return nil
+}
+
+// Close the Kafka writer.
+func (s *KafkaSink) Close() (err error) {
+	return s.writer.Close()
+}
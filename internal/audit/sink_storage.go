@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewStorageSink returns a new StorageSink which saves the events to the storage provider.
+func NewStorageSink(provider StorageProvider) (sink *StorageSink) {
+	return &StorageSink{provider: provider}
+}
+
+// StorageSink is a Sink which saves each event to the audit events table of the storage provider so they can be
+// queried later.
+type StorageSink struct {
+	provider StorageProvider
+}
+
+// Write the event to the storage provider.
+func (s *StorageSink) Write(ctx context.Context, event *Event) (err error) {
+	details := "{}"
+
+	if len(event.Details) != 0 {
+		var data []byte
+
+		if data, err = json.Marshal(event.Details); err != nil {
+			return fmt.Errorf("error encoding the event details: %w", err)
+		}
+
+		details = string(data)
+	}
+
+	if err = s.provider.SaveAuditEvent(ctx, model.AuditEvent{
+		EventID:       event.ID,
+		Time:          event.Time,
+		Type:          event.Type,
+		Result:        event.Result,
+		Username:      event.Actor.Username,
+		RemoteIP:      event.Actor.RemoteIP,
+		UserAgent:     event.Actor.UserAgent,
+		RequestMethod: event.Request.Method,
+		RequestHost:   event.Request.Host,
+		RequestPath:   event.Request.Path,
+		TraceID:       event.TraceID,
+		Details:       details,
+	}); err != nil {
+		return fmt.Errorf("error saving the event: %w", err)
+	}
+
+	return nil
+}
+
+// Close the sink. The storage provider is owned by the caller so it's not closed.
+func (s *StorageSink) Close() (err error) {
+	return nil
+}
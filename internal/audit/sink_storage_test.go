@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestStorageSink_ShouldSaveEvents(t *testing.T) {
+	provider := &testStorageProvider{}
+
+	sink := NewStorageSink(provider)
+
+	now := time.Unix(1700000000, 0).UTC()
+
+	require.NoError(t, sink.Write(context.Background(), &Event{
+		ID:      "1",
+		Time:    now,
+		Type:    EventTypeAuthenticationFirstFactor,
+		Result:  ResultFailure,
+		Actor:   EventActor{Username: "john", RemoteIP: "192.168.0.1", UserAgent: "curl/8.0"},
+		Request: EventRequest{Method: "POST", Host: "auth.example.com", Path: "/api/firstfactor"},
+		TraceID: "abc",
+		Details: map[string]any{"banned": false},
+	}))
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "2", Time: now, Type: EventTypeSessionLogout, Result: ResultSuccess}))
+	require.NoError(t, sink.Close())
+
+	require.Len(t, provider.events, 2)
+
+	assert.Equal(t, model.AuditEvent{
+		EventID:       "1",
+		Time:          now,
+		Type:          EventTypeAuthenticationFirstFactor,
+		Result:        ResultFailure,
+		Username:      "john",
+		RemoteIP:      "192.168.0.1",
+		UserAgent:     "curl/8.0",
+		RequestMethod: "POST",
+		RequestHost:   "auth.example.com",
+		RequestPath:   "/api/firstfactor",
+		TraceID:       "abc",
+		Details:       `{"banned":false}`,
+	}, provider.events[0])
+
+	assert.Equal(t, "{}", provider.events[1].Details)
+}
+
+func TestStorageSink_ShouldReturnErrors(t *testing.T) {
+	sink := NewStorageSink(&testStorageProvider{err: errors.New("database is locked")})
+
+	assert.EqualError(t, sink.Write(context.Background(), &Event{ID: "1"}), "error saving the event: database is locked")
+	assert.EqualError(t, sink.Write(context.Background(), &Event{ID: "1", Details: map[string]any{"bad": make(chan int)}}), "error encoding the event details: json: unsupported type: chan int")
+}
+
+type testStorageProvider struct {
+	events []model.AuditEvent
+	err    error
+}
+
+func (p *testStorageProvider) SaveAuditEvent(_ context.Context, event model.AuditEvent) (err error) {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.events = append(p.events, event)
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// NewStreamSink returns a new StreamSink which queues up to buffer events for each of the subscribers.
+func NewStreamSink(buffer int) (sink *StreamSink) {
+	return &StreamSink{
+		buffer:        buffer,
+		subscriptions: map[*StreamSubscription]struct{}{},
+	}
+}
+
+// StreamSink is a Sink which fans the events out to the subscribers in real time, such as the clients of the admin
+// event stream. The events are dropped for any subscriber which has a full queue so a slow subscriber does not delay
+// the other subscribers or the sinks.
+type StreamSink struct {
+	buffer        int
+	subscriptions map[*StreamSubscription]struct{}
+	closed        bool
+
+	mu sync.RWMutex
+}
+
+// Subscribe returns a new StreamSubscription which receives the events matching the filter. The subscription must be
+// closed when it's no longer used.
+func (s *StreamSink) Subscribe(filter Filter) (subscription *StreamSubscription) {
+	subscription = &StreamSubscription{
+		sink:   s,
+		filter: filter,
+		events: make(chan *Event, s.buffer),
+	}
+
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(subscription.events)
+
+		return subscription
+	}
+
+	s.subscriptions[subscription] = struct{}{}
+
+	return subscription
+}
+
+// Subscribers returns the number of the active subscriptions.
+func (s *StreamSink) Subscribers() int {
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	return len(s.subscriptions)
+}
+
+// Write the event to the subscriptions it matches the filter of.
+func (s *StreamSink) Write(_ context.Context, event *Event) (err error) {
+	s.mu.RLock()
+
+	defer s.mu.RUnlock()
+
+	for subscription := range s.subscriptions {
+		if !subscription.filter.Matches(event.Type) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.dropped.Add(1)
+		}
+	}
+
+	return nil
+}
+
+// Close the sink which ends all of the subscriptions.
+func (s *StreamSink) Close() (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	for subscription := range s.subscriptions {
+		delete(s.subscriptions, subscription)
+
+		close(subscription.events)
+	}
+
+	return nil
+}
+
+func (s *StreamSink) unsubscribe(subscription *StreamSubscription) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(s.subscriptions, subscription)
+
+	close(subscription.events)
+}
+
+// StreamSubscription is a subscription to the events of a StreamSink.
+type StreamSubscription struct {
+	sink    *StreamSink
+	filter  Filter
+	events  chan *Event
+	dropped atomic.Uint64
+}
+
+// Events returns the channel the events are received from. The channel is closed when either the subscription or the
+// sink is closed.
+func (s *StreamSubscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Dropped returns the number of the events which were dropped as the queue of the subscription was full.
+func (s *StreamSubscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close the subscription.
+func (s *StreamSubscription) Close() {
+	s.sink.unsubscribe(s)
+}
@@ -0,0 +1,61 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamSink_ShouldWriteMatchingEventsToSubscribers(t *testing.T) {
+	sink := NewStreamSink(2)
+
+	all := sink.Subscribe(NewFilter(nil, nil))
+	bans := sink.Subscribe(NewFilter([]string{"regulation.*"}, nil))
+
+	assert.Equal(t, 2, sink.Subscribers())
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "1", Type: EventTypeAuthenticationFirstFactor}))
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "2", Type: EventTypeRegulationBan}))
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "3", Type: EventTypeRegulationBan}))
+
+	assert.Equal(t, "1", (<-all.Events()).ID)
+	assert.Equal(t, "2", (<-all.Events()).ID)
+	assert.Equal(t, uint64(1), all.Dropped())
+
+	assert.Equal(t, "2", (<-bans.Events()).ID)
+	assert.Equal(t, "3", (<-bans.Events()).ID)
+	assert.Equal(t, uint64(0), bans.Dropped())
+
+	bans.Close()
+	bans.Close()
+
+	_, ok := <-bans.Events()
+	assert.False(t, ok)
+	assert.Equal(t, 1, sink.Subscribers())
+
+	require.NoError(t, sink.Close())
+	require.NoError(t, sink.Close())
+
+	_, ok = <-all.Events()
+	assert.False(t, ok)
+	assert.Equal(t, 0, sink.Subscribers())
+
+	all.Close()
+}
+
+func TestStreamSink_ShouldEndSubscriptionsAfterClose(t *testing.T) {
+	sink := NewStreamSink(1)
+
+	require.NoError(t, sink.Close())
+
+	subscription := sink.Subscribe(NewFilter(nil, nil))
+
+	_, ok := <-subscription.Events()
+	assert.False(t, ok)
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "1", Type: EventTypeRegulationBan}))
+
+	subscription.Close()
+}
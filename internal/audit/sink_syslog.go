@@ -0,0 +1,166 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// NewSyslogSink returns a new SyslogSink which sends the events to the configured syslog server.
+func NewSyslogSink(config *schema.AuditSinkSyslog) (sink *SyslogSink, err error) {
+	facility, ok := logging.SyslogFacilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility '%s'", config.Facility)
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &SyslogSink{
+		address:  &config.Address.Address,
+		facility: facility,
+		tag:      config.Tag,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}, nil
+}
+
+// SyslogSink is a Sink which sends each event to a syslog server as a RFC5424 message with the JSON encoded event as
+// the message content. The connection is established when the first event is written and is re-established if it
+// fails.
+type SyslogSink struct {
+	address  *schema.Address
+	facility int
+	tag      string
+	hostname string
+	pid      int
+
+	conn   net.Conn
+	stream bool
+
+	mu sync.Mutex
+}
+
+// Write the event to the syslog server.
+func (s *SyslogSink) Write(_ context.Context, event *Event) (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	var data []byte
+
+	if data, err = json.Marshal(event); err != nil {
+		return fmt.Errorf("error encoding the event: %w", err)
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if err = s.dial(); err != nil {
+				return fmt.Errorf("error connecting to the syslog server: %w", err)
+			}
+		}
+
+		if _, err = s.conn.Write(s.format(event, data)); err == nil {
+			return nil
+		}
+
+		_ = s.conn.Close()
+
+		s.conn = nil
+	}
+
+	return fmt.Errorf("error writing to the syslog server: %w", err)
+}
+
+// Close the connection to the syslog server.
+func (s *SyslogSink) Close() (err error) {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+
+	err = s.conn.Close()
+
+	s.conn = nil
+
+	return err
+}
+
+func (s *SyslogSink) dial() (err error) {
+	if !s.address.IsUnixDomainSocket() {
+		if s.conn, err = s.address.Dial(); err != nil {
+			return err
+		}
+
+		s.stream = s.address.IsTCP()
+
+		return nil
+	}
+
+	// The local syslog sockets are usually datagram sockets but some implementations use stream sockets.
+	if s.conn, err = net.Dial("unixgram", s.address.NetworkAddress()); err == nil {
+		s.stream = false
+
+		return nil
+	}
+
+	if s.conn, err = net.Dial("unix", s.address.NetworkAddress()); err != nil {
+		return err
+	}
+
+	s.stream = true
+
+	return nil
+}
+
+// format the event as a RFC5424 message. Stream connections use the non-transparent framing described in RFC6587
+// where each message is terminated by a new line.
+func (s *SyslogSink) format(event *Event, data []byte) []byte {
+	severity := syslogSeverityInfo
+
+	if event.Result == ResultFailure {
+		severity = syslogSeverityWarning
+	}
+
+	hostname := s.hostname
+
+	if hostname == "" {
+		hostname = syslogNil
+	}
+
+	buf := make([]byte, 0, len(data)+128)
+
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(s.facility*8+severity), 10)
+	buf = append(buf, '>')
+	buf = strconv.AppendInt(buf, syslogVersion, 10)
+	buf = append(buf, ' ')
+	buf = event.Time.UTC().AppendFormat(buf, syslogTimeFormat)
+	buf = append(buf, ' ')
+	buf = append(buf, hostname...)
+	buf = append(buf, ' ')
+	buf = append(buf, s.tag...)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(s.pid), 10)
+	buf = append(buf, ' ')
+	buf = append(buf, event.Type...)
+	buf = append(buf, ' ')
+	buf = append(buf, syslogNil...)
+	buf = append(buf, ' ')
+	buf = append(buf, data...)
+
+	if s.stream {
+		buf = append(buf, '\n')
+	}
+
+	return buf
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+func TestSyslogSink_ShouldErrorOnUnknownFacility(t *testing.T) {
+	sink, err := NewSyslogSink(&schema.AuditSinkSyslog{Address: schema.DefaultAuditSinkSyslogConfiguration.Address, Facility: "abc", Tag: "authelia"})
+
+	assert.Nil(t, sink)
+	assert.EqualError(t, err, "unknown syslog facility 'abc'")
+}
+
+func TestSyslogSink_ShouldFormatMessage(t *testing.T) {
+	sink := &SyslogSink{facility: logging.SyslogFacilities["auth"], tag: "authelia", hostname: "example", pid: 123}
+
+	event := &Event{Time: time.Unix(1700000000, 0), Type: EventTypeSessionLogout, Result: ResultSuccess}
+
+	assert.Equal(t, `<38>1 2023-11-14T22:13:20.000000Z example authelia 123 session.logout - {}`, string(sink.format(event, []byte("{}"))))
+
+	event.Result = ResultFailure
+	sink.hostname = ""
+	sink.stream = true
+
+	assert.Equal(t, "<36>1 2023-11-14T22:13:20.000000Z - authelia 123 session.logout - {}\n", string(sink.format(event, []byte("{}"))))
+}
+
+func TestSyslogSink_ShouldWriteToUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+
+	address := schema.NewAddressFromNetworkValues(schema.AddressSchemeUDP, "127.0.0.1", port)
+
+	sink, err := NewSyslogSink(&schema.AuditSinkSyslog{Address: &schema.AddressUDP{Address: address}, Facility: "local0", Tag: "authelia"})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "1", Time: time.Unix(1700000000, 0), Type: EventTypeTOTPRegister, Result: ResultSuccess}))
+
+	buf := make([]byte, 1024)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	hostname, _ := os.Hostname()
+
+	assert.Equal(t, "<134>1 2023-11-14T22:13:20.000000Z "+hostname+" authelia "+strconv.Itoa(os.Getpid())+" totp.register - "+
+		`{"id":"1","time":"2023-11-14T22:13:20Z","type":"totp.register","result":"success","actor":{},"request":{}}`, string(buf[:n]))
+
+	assert.NoError(t, sink.Close())
+	assert.NoError(t, sink.Close())
+}
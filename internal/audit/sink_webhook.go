@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewWebhookSink returns a new WebhookSink which posts the events to the configured URL.
+func NewWebhookSink(config *schema.AuditSinkWebhook, trusted *x509.CertPool) (sink *WebhookSink) {
+	transport := &http.Transport{}
+
+	if config.TLS != nil {
+		transport.TLSClientConfig = utils.NewTLSConfig(config.TLS, trusted)
+	}
+
+	return &WebhookSink{
+		url:     config.URL.String(),
+		headers: config.Headers,
+		client:  &http.Client{Timeout: config.Timeout, Transport: transport},
+	}
+}
+
+// WebhookSink is a Sink which posts each event to a webhook as a JSON request body.
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// Write the event to the webhook.
+func (s *WebhookSink) Write(ctx context.Context, event *Event) (err error) {
+	var data []byte
+
+	if data, err = json.Marshal(event); err != nil {
+		return fmt.Errorf("error encoding the event: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error creating the request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	var resp *http.Response
+
+	if resp, err = s.client.Do(req); err != nil {
+		return fmt.Errorf("error posting the event: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error posting the event: the webhook responded with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Close the idle connections to the webhook.
+func (s *WebhookSink) Close() (err error) {
+	s.client.CloseIdleConnections()
+
+	return nil
+}
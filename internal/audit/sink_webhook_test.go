@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestWebhookSink_ShouldPostEvent(t *testing.T) {
+	var (
+		method, contentType, authorization string
+		event                              Event
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, contentType, authorization = r.Method, r.Header.Get("Content-Type"), r.Header.Get("Authorization")
+
+		_ = json.NewDecoder(r.Body).Decode(&event)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	sink := NewWebhookSink(&schema.AuditSinkWebhook{URL: u, Headers: map[string]string{"Authorization": "Bearer abc"}, Timeout: time.Second * 5}, nil)
+
+	require.NoError(t, sink.Write(context.Background(), &Event{ID: "1", Type: EventTypeWebAuthnRegister, Result: ResultSuccess, Actor: EventActor{Username: "john"}}))
+	assert.NoError(t, sink.Close())
+
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "Bearer abc", authorization)
+	assert.Equal(t, "1", event.ID)
+	assert.Equal(t, EventTypeWebAuthnRegister, event.Type)
+	assert.Equal(t, "john", event.Actor.Username)
+}
+
+func TestWebhookSink_ShouldErrorOnBadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	sink := NewWebhookSink(&schema.AuditSinkWebhook{URL: u, Timeout: time.Second * 5}, nil)
+
+	assert.EqualError(t, sink.Write(context.Background(), &Event{ID: "1"}), "error posting the event: the webhook responded with status code 500")
+}
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// Provider is implemented by types which emit audit events such as the Bus.
+type Provider interface {
+	Emit(event Event)
+}
+
+// Sink is implemented by the drivers which write the audit events to a destination.
+type Sink interface {
+	// Write the event to the destination.
+	Write(ctx context.Context, event *Event) (err error)
+
+	// Close the sink releasing any resources such as open files and connections.
+	Close() (err error)
+}
+
+// StorageProvider is the subset of the storage provider used by the StorageSink.
+type StorageProvider interface {
+	SaveAuditEvent(ctx context.Context, event model.AuditEvent) (err error)
+}
+
+// Event represents an audit event, describing who performed what action where, the result of the action, and the
+// trace id of the request which can be used to correlate the event with other logs.
+type Event struct {
+	ID      string         `json:"id"`
+	Time    time.Time      `json:"time"`
+	Type    string         `json:"type"`
+	Result  string         `json:"result"`
+	Actor   EventActor     `json:"actor"`
+	Request EventRequest   `json:"request"`
+	TraceID string         `json:"trace_id,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// EventActor represents who performed the action of an audit event.
+type EventActor struct {
+	Username  string `json:"username,omitempty"`
+	RemoteIP  string `json:"remote_ip,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+// EventRequest represents where the action of an audit event was performed.
+type EventRequest struct {
+	Method string `json:"method,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Path   string `json:"path,omitempty"`
+}
+
+// NewResult returns ResultSuccess if successful is true otherwise it returns ResultFailure.
+func NewResult(successful bool) string {
+	if successful {
+		return ResultSuccess
+	}
+
+	return ResultFailure
+}
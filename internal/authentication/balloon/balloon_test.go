@@ -0,0 +1,145 @@
+package balloon
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/go-crypt/crypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey(t *testing.T) {
+	// The test vector from the reference implementation.
+	key := Key([]byte("buildmeupbuttercup"), []byte("JqMcHqUcjinFhQKJ"), 16, 20, 4, VariantSHA256.HashFunc())
+
+	assert.Equal(t, "2ec8d833db5f88e584ab793950ecfb21657a3816edea8d9e73ea23c13ba2b740", hex.EncodeToString(key))
+}
+
+func TestHasher(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []Opt
+		expected string
+	}{
+		{
+			"ShouldHashSHA256",
+			[]Opt{WithSpaceCost(16), WithTimeCost(20), WithDelta(4)},
+			"$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A",
+		},
+		{
+			"ShouldHashSHA512",
+			[]Opt{WithVariantName("sha512"), WithSpaceCost(16), WithTimeCost(20), WithDelta(4)},
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher, err := New(tc.opts...)
+			require.NoError(t, err)
+
+			digest, err := hasher.HashWithSalt("buildmeupbuttercup", []byte("JqMcHqUcjinFhQKJ"))
+			require.NoError(t, err)
+
+			if tc.expected != "" {
+				assert.Equal(t, tc.expected, digest.Encode())
+			}
+
+			assert.True(t, digest.Match("buildmeupbuttercup"))
+			assert.False(t, digest.Match("buildmeupbutter"))
+
+			decoded, err := Decode(digest.Encode())
+			require.NoError(t, err)
+
+			assert.Equal(t, digest, decoded)
+			assert.Equal(t, digest.Encode(), decoded.String())
+		})
+	}
+}
+
+func TestHasherDefaults(t *testing.T) {
+	hasher, err := New(WithSpaceCost(64))
+	require.NoError(t, err)
+
+	digest, err := hasher.Hash("password")
+	require.NoError(t, err)
+
+	d, ok := digest.(*Digest)
+	require.True(t, ok)
+
+	assert.Equal(t, VariantSHA256, d.variant)
+	assert.Equal(t, 64, d.s)
+	assert.Equal(t, TimeCostDefault, d.t)
+	assert.Equal(t, DeltaDefault, d.d)
+	assert.Len(t, d.salt, SaltLengthDefault)
+	assert.Len(t, d.key, 32)
+	assert.True(t, digest.Match("password"))
+}
+
+func TestHasherShouldRaiseErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		opt  Opt
+		err  string
+	}{
+		{"ShouldRaiseErrorVariant", WithVariantName("md5"), "balloon validation error: parameter is invalid: variant identifier 'md5' is invalid"},
+		{"ShouldRaiseErrorSpaceCost", WithSpaceCost(-1), "balloon validation error: parameter is invalid: parameter 'space cost' must be between 1 and 2147483647 but is set to '-1'"},
+		{"ShouldRaiseErrorTimeCost", WithTimeCost(0), "balloon validation error: parameter is invalid: parameter 'time cost' must be between 1 and 2147483647 but is set to '0'"},
+		{"ShouldRaiseErrorDelta", WithDelta(1025), "balloon validation error: parameter is invalid: parameter 'delta' must be between 1 and 1024 but is set to '1025'"},
+		{"ShouldRaiseErrorSaltLength", WithSaltLength(4), "balloon validation error: parameter is invalid: parameter 'salt length' must be between 8 and 1024 but is set to '4'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher, err := New(tc.opt)
+
+			assert.Nil(t, hasher)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	hasher, err := New()
+	require.NoError(t, err)
+
+	_, err = hasher.HashWithSalt("password", []byte("abc"))
+	assert.EqualError(t, err, "balloon hashing error: salt is invalid: salt bytes must have a length of between 8 and 1024 but has a length of 3")
+}
+
+func TestDecodeShouldRaiseErrors(t *testing.T) {
+	testCases := []struct {
+		name, have, err string
+	}{
+		{"ShouldRaiseErrorFormat", "$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg", "balloon decode error: provided encoded hash has an invalid format"},
+		{"ShouldRaiseErrorIdentifier", "$balloon$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "balloon decode error: provided encoded hash has an invalid identifier: identifier 'balloon' is not an encoded balloon digest"},
+		{"ShouldRaiseErrorOptionKey", "$balloon-sha256$s=16,t=20,x=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "balloon decode error: provided encoded hash has an invalid option key: option 'x' with value '4' is unknown"},
+		{"ShouldRaiseErrorOptionValue", "$balloon-sha256$s=abc,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "balloon decode error: provided encoded hash has an invalid option value: option 's' has invalid value 'abc': strconv.Atoi: parsing \"abc\": invalid syntax"},
+		{"ShouldRaiseErrorMissingOption", "$balloon-sha256$s=16,t=20$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "balloon decode error: provided encoded hash has an invalid option value: parameter 'd' must be between 1 and 1024 but is set to '0'"},
+		{"ShouldRaiseErrorSalt", "$balloon-sha256$s=16,t=20,d=4$!!$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "balloon decode error: provided encoded hash has a salt value that can't be decoded: illegal base64 data at input byte 0"},
+		{"ShouldRaiseErrorKey", "$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$", "balloon decode error: provided encoded hash has a key value that can't be decoded: key has 0 bytes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := Decode(tc.have)
+
+			assert.Nil(t, digest)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	_, err := DecodeVariant(VariantSHA512)("$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A")
+	assert.EqualError(t, err, "balloon decode error: the 'sha256' variant cannot be decoded only the 'sha512' variant can be")
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	decoder := crypt.NewDecoder()
+
+	require.NoError(t, RegisterDecoder(decoder))
+
+	digest, err := decoder.Decode("$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A")
+	require.NoError(t, err)
+
+	assert.True(t, digest.Match("buildmeupbuttercup"))
+	assert.Error(t, RegisterDecoder(decoder))
+}
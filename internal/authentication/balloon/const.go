@@ -0,0 +1,59 @@
+package balloon
+
+import (
+	"math"
+)
+
+const (
+	// EncodingFmt is the encoding format for this algorithm.
+	EncodingFmt = "$%s$s=%d,t=%d,d=%d$%s$%s"
+
+	// AlgName is the name for this algorithm.
+	AlgName = "balloon"
+
+	// AlgIdentifierSHA256 is the identifier used in encoded SHA256 variants of this algorithm.
+	AlgIdentifierSHA256 = "balloon-sha256"
+
+	// AlgIdentifierSHA512 is the identifier used in encoded SHA512 variants of this algorithm.
+	AlgIdentifierSHA512 = "balloon-sha512"
+
+	// SpaceCostMin is the minimum space cost accepted.
+	SpaceCostMin = 1
+
+	// SpaceCostMax is the maximum space cost accepted.
+	SpaceCostMax = math.MaxInt32
+
+	// SpaceCostDefault is the default space cost.
+	SpaceCostDefault = 16384
+
+	// TimeCostMin is the minimum time cost accepted.
+	TimeCostMin = 1
+
+	// TimeCostMax is the maximum time cost accepted.
+	TimeCostMax = math.MaxInt32
+
+	// TimeCostDefault is the default time cost.
+	TimeCostDefault = 3
+
+	// DeltaMin is the minimum delta accepted.
+	DeltaMin = 1
+
+	// DeltaMax is the maximum delta accepted.
+	DeltaMax = 1024
+
+	// DeltaDefault is the default delta.
+	DeltaDefault = 3
+
+	// SaltLengthMin is the minimum salt size accepted.
+	SaltLengthMin = 8
+
+	// SaltLengthMax is the maximum salt size accepted.
+	SaltLengthMax = 1024
+
+	// SaltLengthDefault is the default salt size.
+	SaltLengthDefault = 16
+
+	oSpaceCost = "s"
+	oTimeCost  = "t"
+	oDelta     = "d"
+)
@@ -0,0 +1,117 @@
+package balloon
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// RegisterDecoder the decoder with the algorithm.DecoderRegister.
+func RegisterDecoder(r algorithm.DecoderRegister) (err error) {
+	if err = r.RegisterDecodeFunc(AlgIdentifierSHA256, DecodeVariant(VariantSHA256)); err != nil {
+		return err
+	}
+
+	if err = r.RegisterDecodeFunc(AlgIdentifierSHA512, DecodeVariant(VariantSHA512)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Decode the encoded digest into a algorithm.Digest.
+func Decode(encodedDigest string) (digest algorithm.Digest, err error) {
+	return DecodeVariant(VariantNone)(encodedDigest)
+}
+
+// DecodeVariant the encoded digest into a algorithm.Digest provided it matches the provided balloon.Variant. If
+// balloon.VariantNone is used all variants can be decoded.
+func DecodeVariant(v Variant) func(encodedDigest string) (digest algorithm.Digest, err error) {
+	return func(encodedDigest string) (digest algorithm.Digest, err error) {
+		var (
+			parts   []string
+			variant Variant
+		)
+
+		if variant, parts, err = decoderParts(encodedDigest); err != nil {
+			return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, AlgName, err)
+		}
+
+		if v != VariantNone && v != variant {
+			return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, AlgName, fmt.Errorf("the '%s' variant cannot be decoded only the '%s' variant can be", variant.String(), v.String()))
+		}
+
+		if digest, err = decode(variant, parts); err != nil {
+			return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, AlgName, err)
+		}
+
+		return digest, nil
+	}
+}
+
+func decoderParts(encodedDigest string) (variant Variant, parts []string, err error) {
+	parts = strings.Split(encodedDigest, "$")
+
+	if len(parts) != 5 || parts[0] != "" {
+		return VariantNone, nil, algorithm.ErrEncodedHashInvalidFormat
+	}
+
+	if variant = NewVariant(parts[1]); variant == VariantNone || parts[1] != variant.Prefix() {
+		return VariantNone, nil, fmt.Errorf("%w: identifier '%s' is not an encoded %s digest", algorithm.ErrEncodedHashInvalidIdentifier, parts[1], AlgName)
+	}
+
+	return variant, parts[2:], nil
+}
+
+func decode(variant Variant, parts []string) (digest algorithm.Digest, err error) {
+	decoded := &Digest{
+		variant: variant,
+	}
+
+	for _, param := range strings.Split(parts[0], ",") {
+		key, value, _ := strings.Cut(param, "=")
+
+		var v int
+
+		if v, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("%w: option '%s' has invalid value '%s': %v", algorithm.ErrEncodedHashInvalidOptionValue, key, value, err)
+		}
+
+		switch key {
+		case oSpaceCost:
+			decoded.s = v
+		case oTimeCost:
+			decoded.t = v
+		case oDelta:
+			decoded.d = v
+		default:
+			return nil, fmt.Errorf("%w: option '%s' with value '%s' is unknown", algorithm.ErrEncodedHashInvalidOptionKey, key, value)
+		}
+	}
+
+	switch {
+	case decoded.s < SpaceCostMin || decoded.s > SpaceCostMax:
+		return nil, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrEncodedHashInvalidOptionValue, oSpaceCost, SpaceCostMin, "", SpaceCostMax, decoded.s)
+	case decoded.t < TimeCostMin || decoded.t > TimeCostMax:
+		return nil, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrEncodedHashInvalidOptionValue, oTimeCost, TimeCostMin, "", TimeCostMax, decoded.t)
+	case decoded.d < DeltaMin || decoded.d > DeltaMax:
+		return nil, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrEncodedHashInvalidOptionValue, oDelta, DeltaMin, "", DeltaMax, decoded.d)
+	}
+
+	if decoded.salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		return nil, fmt.Errorf("%w: %v", algorithm.ErrEncodedHashSaltEncoding, err)
+	}
+
+	if decoded.key, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return nil, fmt.Errorf("%w: %v", algorithm.ErrEncodedHashKeyEncoding, err)
+	}
+
+	if len(decoded.key) == 0 {
+		return nil, fmt.Errorf("%w: key has 0 bytes", algorithm.ErrEncodedHashKeyEncoding)
+	}
+
+	return decoded, nil
+}
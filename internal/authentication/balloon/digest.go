@@ -0,0 +1,57 @@
+package balloon
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// Digest is a balloon.Digest which handles Balloon hashes.
+type Digest struct {
+	variant Variant
+
+	s, t, d   int
+	salt, key []byte
+}
+
+// Match returns true if the string password matches the current balloon.Digest.
+func (d *Digest) Match(password string) (match bool) {
+	return d.MatchBytes([]byte(password))
+}
+
+// MatchBytes returns true if the []byte passwordBytes matches the current balloon.Digest.
+func (d *Digest) MatchBytes(passwordBytes []byte) (match bool) {
+	match, _ = d.MatchBytesAdvanced(passwordBytes)
+
+	return match
+}
+
+// MatchAdvanced is the same as Match except if there is an error it returns that as well.
+func (d *Digest) MatchAdvanced(password string) (match bool, err error) {
+	return d.MatchBytesAdvanced([]byte(password))
+}
+
+// MatchBytesAdvanced is the same as MatchBytes except if there is an error it returns that as well.
+func (d *Digest) MatchBytesAdvanced(passwordBytes []byte) (match bool, err error) {
+	if len(d.key) == 0 {
+		return false, fmt.Errorf(algorithm.ErrFmtDigestMatch, AlgName, fmt.Errorf("%w: key has 0 bytes", algorithm.ErrPasswordInvalid))
+	}
+
+	return subtle.ConstantTimeCompare(d.key, Key(passwordBytes, d.salt, d.s, d.t, d.d, d.variant.HashFunc())) == 1, nil
+}
+
+// Encode returns the encoded form of this balloon.Digest.
+func (d *Digest) Encode() string {
+	return fmt.Sprintf(EncodingFmt,
+		d.variant.Prefix(),
+		d.s, d.t, d.d,
+		base64.RawStdEncoding.EncodeToString(d.salt), base64.RawStdEncoding.EncodeToString(d.key),
+	)
+}
+
+// String returns the storable format of the balloon.Digest encoded hash.
+func (d *Digest) String() string {
+	return d.Encode()
+}
@@ -0,0 +1,130 @@
+package balloon
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// New returns a new balloon.Hasher with the provided functional options applied.
+func New(opts ...Opt) (hasher *Hasher, err error) {
+	hasher = &Hasher{}
+
+	if err = hasher.WithOptions(opts...); err != nil {
+		return nil, err
+	}
+
+	if err = hasher.Validate(); err != nil {
+		return nil, err
+	}
+
+	return hasher, nil
+}
+
+// Hasher is a crypt.Hash for Balloon which can be initialized via New using a functional options pattern.
+type Hasher struct {
+	variant Variant
+
+	s, t, d, bytesSalt int
+}
+
+// WithOptions defines the options for this balloon.Hasher.
+func (h *Hasher) WithOptions(opts ...Opt) (err error) {
+	for _, opt := range opts {
+		if err = opt(h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Hash performs the hashing operation and returns either a Digest or an error.
+func (h *Hasher) Hash(password string) (digest algorithm.Digest, err error) {
+	h.defaults()
+
+	salt := make([]byte, h.bytesSalt)
+
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtHasherHash, AlgName, fmt.Errorf("%w: %v", algorithm.ErrSaltReadRandomBytes, err))
+	}
+
+	if digest, err = h.hashWithSalt(password, salt); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtHasherHash, AlgName, err)
+	}
+
+	return digest, nil
+}
+
+// HashWithSalt overloads the Hash method allowing the user to provide a salt. It's recommended instead to configure the
+// salt size and let this be a random value generated using crypto/rand.
+func (h *Hasher) HashWithSalt(password string, salt []byte) (digest algorithm.Digest, err error) {
+	h.defaults()
+
+	if digest, err = h.hashWithSalt(password, salt); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtHasherHash, AlgName, err)
+	}
+
+	return digest, nil
+}
+
+func (h *Hasher) hashWithSalt(password string, salt []byte) (digest algorithm.Digest, err error) {
+	if s := len(salt); s > SaltLengthMax || s < SaltLengthMin {
+		return nil, fmt.Errorf("%w: salt bytes must have a length of between %d and %d but has a length of %d", algorithm.ErrSaltInvalid, SaltLengthMin, SaltLengthMax, len(salt))
+	}
+
+	d := &Digest{
+		variant: h.variant,
+		s:       h.s,
+		t:       h.t,
+		d:       h.d,
+		salt:    salt,
+	}
+
+	d.key = Key([]byte(password), d.salt, d.s, d.t, d.d, d.variant.HashFunc())
+
+	return d, nil
+}
+
+// MustHash overloads the Hash method and panics if the error is not nil. It's recommended if you use this option to
+// utilize the Validate method first or handle the panic appropriately.
+func (h *Hasher) MustHash(password string) (digest algorithm.Digest) {
+	var err error
+
+	if digest, err = h.Hash(password); err != nil {
+		panic(err)
+	}
+
+	return digest
+}
+
+// Validate checks the settings/parameters for this Hash and returns an error.
+func (h *Hasher) Validate() (err error) {
+	h.defaults()
+
+	return nil
+}
+
+func (h *Hasher) defaults() {
+	if h.variant == VariantNone {
+		h.variant = VariantSHA256
+	}
+
+	if h.s == 0 {
+		h.s = SpaceCostDefault
+	}
+
+	if h.t == 0 {
+		h.t = TimeCostDefault
+	}
+
+	if h.d == 0 {
+		h.d = DeltaDefault
+	}
+
+	if h.bytesSalt == 0 {
+		h.bytesSalt = SaltLengthDefault
+	}
+}
@@ -0,0 +1,91 @@
+package balloon
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/big"
+	"slices"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// Key derives a key from the password and salt using the Balloon hashing algorithm described by Boneh, Corrigan-Gibbs,
+// and Schechter. The derivation is compatible with the reference implementation where each integer is encoded as an
+// 8 byte little-endian value, and the length of the key is the size of the hash function output.
+func Key(password, salt []byte, space, time, delta int, h algorithm.HashFunc) (key []byte) {
+	k := &keyer{h: h(), buf: make([][]byte, space)}
+
+	// Step 1: Expand the input into the buffer.
+	k.buf[0] = k.sum(password, salt)
+
+	for m := 1; m < space; m++ {
+		k.buf[m] = k.sum(k.buf[m-1])
+	}
+
+	// Step 2: Mix the contents of the buffer.
+	var (
+		other = new(big.Int)
+		s     = big.NewInt(int64(space))
+		idx   []byte
+	)
+
+	for t := 0; t < time; t++ {
+		for m := 0; m < space; m++ {
+			k.buf[m] = k.sum(k.buf[(m+space-1)%space], k.buf[m])
+
+			for i := 0; i < delta; i++ {
+				idx = k.sumInts(uint64(t), uint64(m), uint64(i))
+
+				other.SetBytes(reverse(k.sum(salt, idx)))
+				other.Mod(other, s)
+
+				k.buf[m] = k.sum(k.buf[m], k.buf[other.Int64()])
+			}
+		}
+	}
+
+	// Step 3: Extract the output from the buffer.
+	return k.buf[space-1]
+}
+
+type keyer struct {
+	h   hash.Hash
+	cnt uint64
+	buf [][]byte
+	b   [8]byte
+}
+
+// sum returns the hash of the counter followed by the data and increments the counter.
+func (k *keyer) sum(data ...[]byte) []byte {
+	k.h.Reset()
+
+	binary.LittleEndian.PutUint64(k.b[:], k.cnt)
+	k.h.Write(k.b[:])
+
+	k.cnt++
+
+	for _, d := range data {
+		k.h.Write(d)
+	}
+
+	return k.h.Sum(nil)
+}
+
+// sumInts returns the hash of the integers without the counter.
+func (k *keyer) sumInts(ints ...uint64) []byte {
+	k.h.Reset()
+
+	for _, i := range ints {
+		binary.LittleEndian.PutUint64(k.b[:], i)
+		k.h.Write(k.b[:])
+	}
+
+	return k.h.Sum(nil)
+}
+
+// reverse converts the little-endian bytes to big-endian in place as expected by big.Int.
+func reverse(b []byte) []byte {
+	slices.Reverse(b)
+
+	return b
+}
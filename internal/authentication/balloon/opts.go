@@ -0,0 +1,103 @@
+package balloon
+
+import (
+	"fmt"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// Opt describes the functional option pattern for the balloon.Hasher.
+type Opt func(h *Hasher) (err error)
+
+// WithVariant configures the balloon.Variant of the resulting balloon.Digest.
+// Default is balloon.VariantSHA256.
+func WithVariant(variant Variant) Opt {
+	return func(h *Hasher) (err error) {
+		switch variant {
+		case VariantNone:
+			return nil
+		case VariantSHA256, VariantSHA512:
+			h.variant = variant
+
+			return nil
+		default:
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf("%w: variant '%d' is invalid", algorithm.ErrParameterInvalid, variant))
+		}
+	}
+}
+
+// WithVariantName uses the variant name or identifier to configure the balloon.Variant of the resulting
+// balloon.Digest. Default is balloon.VariantSHA256.
+func WithVariantName(identifier string) Opt {
+	return func(h *Hasher) (err error) {
+		if identifier == "" {
+			return nil
+		}
+
+		variant := NewVariant(identifier)
+
+		if variant == VariantNone {
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf("%w: variant identifier '%s' is invalid", algorithm.ErrParameterInvalid, identifier))
+		}
+
+		h.variant = variant
+
+		return nil
+	}
+}
+
+// WithSpaceCost sets the number of blocks in the buffer of the resulting balloon.Digest.
+// Minimum is 1, Maximum is 2147483647. Default is 16384.
+func WithSpaceCost(s int) Opt {
+	return func(h *Hasher) (err error) {
+		if s < SpaceCostMin || s > SpaceCostMax {
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrParameterInvalid, "space cost", SpaceCostMin, "", SpaceCostMax, s))
+		}
+
+		h.s = s
+
+		return nil
+	}
+}
+
+// WithTimeCost sets the number of mixing rounds of the resulting balloon.Digest.
+// Minimum is 1, Maximum is 2147483647. Default is 3.
+func WithTimeCost(t int) Opt {
+	return func(h *Hasher) (err error) {
+		if t < TimeCostMin || t > TimeCostMax {
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrParameterInvalid, "time cost", TimeCostMin, "", TimeCostMax, t))
+		}
+
+		h.t = t
+
+		return nil
+	}
+}
+
+// WithDelta sets the number of dependencies per block of the resulting balloon.Digest.
+// Minimum is 1, Maximum is 1024. Default is 3.
+func WithDelta(d int) Opt {
+	return func(h *Hasher) (err error) {
+		if d < DeltaMin || d > DeltaMax {
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrParameterInvalid, "delta", DeltaMin, "", DeltaMax, d))
+		}
+
+		h.d = d
+
+		return nil
+	}
+}
+
+// WithSaltLength adjusts the salt length of the resulting balloon.Digest.
+// Minimum is 8, Maximum is 1024. Default is 16.
+func WithSaltLength(bytes int) Opt {
+	return func(h *Hasher) (err error) {
+		if bytes < SaltLengthMin || bytes > SaltLengthMax {
+			return fmt.Errorf(algorithm.ErrFmtHasherValidation, AlgName, fmt.Errorf(algorithm.ErrFmtInvalidIntParameter, algorithm.ErrParameterInvalid, "salt length", SaltLengthMin, "", SaltLengthMax, bytes))
+		}
+
+		h.bytesSalt = bytes
+
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+package balloon
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+
+	"github.com/go-crypt/crypt/algorithm"
+)
+
+// NewVariant converts an identifier string to a balloon.Variant.
+func NewVariant(identifier string) (variant Variant) {
+	switch identifier {
+	case AlgIdentifierSHA256, algorithm.DigestSHA256:
+		return VariantSHA256
+	case AlgIdentifierSHA512, algorithm.DigestSHA512:
+		return VariantSHA512
+	default:
+		return VariantNone
+	}
+}
+
+// Variant is a variant of the balloon.Digest which determines the hash function used to fill and mix the buffer.
+type Variant int
+
+const (
+	// VariantNone is a variant of the balloon.Digest which is unknown.
+	VariantNone Variant = iota
+
+	// VariantSHA256 is a variant of the balloon.Digest which uses SHA-256.
+	VariantSHA256
+
+	// VariantSHA512 is a variant of the balloon.Digest which uses SHA-512.
+	VariantSHA512
+)
+
+// String implements the fmt.Stringer returning a string representation of the balloon.Variant.
+func (v Variant) String() (variant string) {
+	switch v {
+	case VariantSHA256:
+		return algorithm.DigestSHA256
+	case VariantSHA512:
+		return algorithm.DigestSHA512
+	default:
+		return ""
+	}
+}
+
+// Prefix returns the balloon.Variant specific prefix.
+func (v Variant) Prefix() (prefix string) {
+	switch v {
+	case VariantSHA256:
+		return AlgIdentifierSHA256
+	case VariantSHA512:
+		return AlgIdentifierSHA512
+	default:
+		return ""
+	}
+}
+
+// HashFunc returns the algorithm.HashFunc for the balloon.Variant.
+func (v Variant) HashFunc() algorithm.HashFunc {
+	switch v {
+	case VariantSHA512:
+		return sha512.New
+	default:
+		return sha256.New
+	}
+}
@@ -1,7 +1,9 @@
 package authentication
 
 import (
+	"encoding/base64"
 	"errors"
+	"time"
 
 	"golang.org/x/text/encoding/unicode"
 )
@@ -89,18 +91,37 @@ const (
 	hashPBKDF2    = "pbkdf2"
 	hashSCrypt    = "scrypt"
 	hashBCrypt    = "bcrypt"
+	hashBalloon   = "balloon"
 )
 
+const (
+	cryptIdentifierDjangoPBKDF2   = "django-pbkdf2"
+	cryptIdentifierDjangoSCrypt   = "django-scrypt"
+	cryptIdentifierWerkzeugPBKDF2 = "werkzeug-pbkdf2"
+	cryptIdentifierWerkzeugSCrypt = "werkzeug-scrypt"
+)
+
+var cryptBase64RawAdaptedEncoding = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789./").WithPadding(base64.NoPadding)
+
 var (
 	// ErrUserNotFound indicates the user wasn't found in the authentication backend.
 	ErrUserNotFound = errors.New("user not found")
 
 	// ErrNoContent is returned when the file is empty.
 	ErrNoContent = errors.New("no file content")
+
+	// ErrSPNEGONoToken indicates the request doesn't include a SPNEGO token in the Authorization header.
+	ErrSPNEGONoToken = errors.New("the request doesn't have a spnego token")
 )
 
+const spnegoAuthScheme = "Negotiate"
+
 const fileAuthenticationMode = 0600
 
+// ldapBackendUnhealthyInterval is the duration of time a LDAP backend which failed the health check is skipped for
+// before it's used again.
+const ldapBackendUnhealthyInterval = time.Minute
+
 // OWASP recommends to escape some special characters.
 // https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/LDAP_Injection_Prevention_Cheat_Sheet.md
 const specialLDAPRunes = ",#+<>;\"="
@@ -0,0 +1,206 @@
+package authentication
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-crypt/crypt"
+	"github.com/go-crypt/crypt/algorithm"
+	"github.com/go-crypt/crypt/algorithm/pbkdf2"
+	"github.com/go-crypt/crypt/algorithm/scrypt"
+
+	"github.com/authelia/authelia/v4/internal/authentication/balloon"
+)
+
+var (
+	decoder     algorithm.DecoderRegister
+	decoderErr  error
+	decoderOnce sync.Once
+)
+
+// DecodeAlgorithmDigest decodes an encoded password digest from a users database. In addition to the default
+// algorithms this decodes the balloon algorithm, and the PBKDF2 and scrypt formats used by Django and Werkzeug so the
+// user databases imported from other systems can be used as is.
+func DecodeAlgorithmDigest(encodedDigest string) (digest algorithm.Digest, err error) {
+	decoderOnce.Do(func() {
+		decoder, decoderErr = newCryptDecoder()
+	})
+
+	if decoderErr != nil {
+		return nil, fmt.Errorf("failed to initialize decoder: %w", decoderErr)
+	}
+
+	return decoder.Decode(encodedDigest)
+}
+
+// CheckPassword returns true if the password matches the encoded password digest. The encoded password digest may be
+// any of the formats decoded by DecodeAlgorithmDigest.
+func CheckPassword(password, encodedDigest string) (valid bool, err error) {
+	var digest algorithm.Digest
+
+	if digest, err = DecodeAlgorithmDigest(encodedDigest); err != nil {
+		return false, err
+	}
+
+	return digest.MatchAdvanced(password)
+}
+
+func newCryptDecoder() (d *crypt.Decoder, err error) {
+	if d, err = crypt.NewDefaultDecoder(); err != nil {
+		return nil, err
+	}
+
+	if err = balloon.RegisterDecoder(d); err != nil {
+		return nil, fmt.Errorf("could not register the balloon decoder: %w", err)
+	}
+
+	decoders := []struct {
+		identifier string
+		prefixes   []string
+		decode     algorithm.DecodeFunc
+	}{
+		{cryptIdentifierDjangoPBKDF2, []string{"pbkdf2_sha1$", "pbkdf2_sha256$"}, decodeDjangoPBKDF2},
+		{cryptIdentifierDjangoSCrypt, []string{"scrypt$"}, decodeDjangoSCrypt},
+		{cryptIdentifierWerkzeugPBKDF2, []string{"pbkdf2:"}, decodeWerkzeugPBKDF2},
+		{cryptIdentifierWerkzeugSCrypt, []string{"scrypt:"}, decodeWerkzeugSCrypt},
+	}
+
+	for _, decoder := range decoders {
+		if err = d.RegisterDecodeFunc(decoder.identifier, decoder.decode); err != nil {
+			return nil, fmt.Errorf("could not register the %s decoder: %w", decoder.identifier, err)
+		}
+
+		for _, prefix := range decoder.prefixes {
+			if err = d.RegisterDecodePrefix(prefix, decoder.identifier); err != nil {
+				return nil, fmt.Errorf("could not register the %s decoder: %w", decoder.identifier, err)
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// decodeDjangoPBKDF2 decodes the Django PBKDF2 format 'pbkdf2_<digest>$<iterations>$<salt>$<base64 key>' where the
+// salt is used as is.
+func decodeDjangoPBKDF2(encodedDigest string) (digest algorithm.Digest, err error) {
+	parts := strings.Split(encodedDigest, "$")
+
+	if len(parts) != 4 {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierDjangoPBKDF2, algorithm.ErrEncodedHashInvalidFormat)
+	}
+
+	var key []byte
+
+	if key, err = base64.StdEncoding.DecodeString(parts[3]); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierDjangoPBKDF2, fmt.Errorf("%w: %v", algorithm.ErrEncodedHashKeyEncoding, err))
+	}
+
+	return decodeForeignPBKDF2(cryptIdentifierDjangoPBKDF2, strings.TrimPrefix(parts[0], "pbkdf2_"), parts[1], []byte(parts[2]), key)
+}
+
+// decodeDjangoSCrypt decodes the Django scrypt format 'scrypt$<N>$<salt>$<r>$<p>$<base64 key>' where the salt is
+// used as is.
+func decodeDjangoSCrypt(encodedDigest string) (digest algorithm.Digest, err error) {
+	parts := strings.Split(encodedDigest, "$")
+
+	if len(parts) != 6 {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierDjangoSCrypt, algorithm.ErrEncodedHashInvalidFormat)
+	}
+
+	var key []byte
+
+	if key, err = base64.StdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierDjangoSCrypt, fmt.Errorf("%w: %v", algorithm.ErrEncodedHashKeyEncoding, err))
+	}
+
+	return decodeForeignSCrypt(cryptIdentifierDjangoSCrypt, parts[1], parts[3], parts[4], []byte(parts[2]), key)
+}
+
+// decodeWerkzeugPBKDF2 decodes the Werkzeug PBKDF2 format 'pbkdf2:<digest>:<iterations>$<salt>$<hex key>' where the
+// salt is used as is.
+func decodeWerkzeugPBKDF2(encodedDigest string) (digest algorithm.Digest, err error) {
+	method, salt, key, err := werkzeugParts(cryptIdentifierWerkzeugPBKDF2, encodedDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(method) != 3 {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierWerkzeugPBKDF2, fmt.Errorf("%w: the method must include the digest and iterations", algorithm.ErrEncodedHashInvalidFormat))
+	}
+
+	return decodeForeignPBKDF2(cryptIdentifierWerkzeugPBKDF2, method[1], method[2], salt, key)
+}
+
+// decodeWerkzeugSCrypt decodes the Werkzeug scrypt format 'scrypt:<N>:<r>:<p>$<salt>$<hex key>' where the salt is
+// used as is.
+func decodeWerkzeugSCrypt(encodedDigest string) (digest algorithm.Digest, err error) {
+	method, salt, key, err := werkzeugParts(cryptIdentifierWerkzeugSCrypt, encodedDigest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(method) != 4 {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, cryptIdentifierWerkzeugSCrypt, fmt.Errorf("%w: the method must include the N, r, and p parameters", algorithm.ErrEncodedHashInvalidFormat))
+	}
+
+	return decodeForeignSCrypt(cryptIdentifierWerkzeugSCrypt, method[1], method[2], method[3], salt, key)
+}
+
+func werkzeugParts(identifier, encodedDigest string) (method []string, salt, key []byte, err error) {
+	parts := strings.Split(encodedDigest, "$")
+
+	if len(parts) != 3 {
+		return nil, nil, nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, algorithm.ErrEncodedHashInvalidFormat)
+	}
+
+	if key, err = hex.DecodeString(parts[2]); err != nil {
+		return nil, nil, nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: %v", algorithm.ErrEncodedHashKeyEncoding, err))
+	}
+
+	return strings.Split(parts[0], ":"), []byte(parts[1]), key, nil
+}
+
+// decodeForeignPBKDF2 decodes the individual values of a PBKDF2 digest from another system by encoding them in the
+// native format.
+func decodeForeignPBKDF2(identifier, variant, iterations string, salt, key []byte) (digest algorithm.Digest, err error) {
+	v := pbkdf2.NewVariant(variant)
+
+	if v == pbkdf2.VariantNone {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: digest '%s' is unknown", algorithm.ErrEncodedHashInvalidIdentifier, variant))
+	}
+
+	var i int
+
+	if i, err = strconv.Atoi(iterations); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: iterations could not be parsed: %v", algorithm.ErrEncodedHashInvalidOptionValue, err))
+	}
+
+	return pbkdf2.Decode(fmt.Sprintf(pbkdf2.EncodingFmt, v.Prefix(), i, cryptBase64RawAdaptedEncoding.EncodeToString(salt), cryptBase64RawAdaptedEncoding.EncodeToString(key)))
+}
+
+// decodeForeignSCrypt decodes the individual values of a scrypt digest from another system by encoding them in the
+// native format.
+func decodeForeignSCrypt(identifier, cost, blockSize, parallelism string, salt, key []byte) (digest algorithm.Digest, err error) {
+	var n uint64
+
+	if n, err = strconv.ParseUint(cost, 10, 64); err != nil || bits.OnesCount64(n) != 1 || n < 2 {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: N must be a power of 2 greater than 1 but it's '%s'", algorithm.ErrEncodedHashInvalidOptionValue, cost))
+	}
+
+	var r, p int
+
+	if r, err = strconv.Atoi(blockSize); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: r could not be parsed: %v", algorithm.ErrEncodedHashInvalidOptionValue, err))
+	}
+
+	if p, err = strconv.Atoi(parallelism); err != nil {
+		return nil, fmt.Errorf(algorithm.ErrFmtDigestDecode, identifier, fmt.Errorf("%w: p could not be parsed: %v", algorithm.ErrEncodedHashInvalidOptionValue, err))
+	}
+
+	return scrypt.Decode(fmt.Sprintf(scrypt.EncodingFormat, scrypt.AlgName, bits.TrailingZeros64(n), r, p, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)))
+}
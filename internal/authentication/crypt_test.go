@@ -0,0 +1,167 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/go-crypt/crypt/algorithm/pbkdf2"
+	"github.com/go-crypt/crypt/algorithm/scrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication/balloon"
+)
+
+func TestDecodeAlgorithmDigest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{
+			"ShouldDecodeNativePBKDF2",
+			"$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFHrLavHvBi1lA7/Bp2k13l3Vf6tKpEq3oaYBAt/OvA8YO7dDgDfqYtt9O3KAN4fDkmIA",
+			"$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFHrLavHvBi1lA7/Bp2k13l3Vf6tKpEq3oaYBAt/OvA8YO7dDgDfqYtt9O3KAN4fDkmIA",
+		},
+		{
+			"ShouldDecodeBalloon",
+			"$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A",
+			"$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A",
+		},
+		{
+			"ShouldDecodeDjangoPBKDF2SHA256",
+			"pbkdf2_sha256$870000$AkYaXgpvM9FAFHTmXQn2f3$Xu2Il6b4pQMP5zdBSMy6rZV7KVmXs208RF6PERRyyaM=",
+			"$pbkdf2-sha256$870000$QWtZYVhncHZNOUZBRkhUbVhRbjJmMw$Xu2Il6b4pQMP5zdBSMy6rZV7KVmXs208RF6PERRyyaM",
+		},
+		{
+			"ShouldDecodeDjangoPBKDF2SHA1",
+			"pbkdf2_sha1$100000$AkYaXgpvM9FAFHTmXQn2f3$rIXp5Ll0KtUDhztx7pVUm/pRRzI=",
+			"$pbkdf2$100000$QWtZYVhncHZNOUZBRkhUbVhRbjJmMw$rIXp5Ll0KtUDhztx7pVUm/pRRzI",
+		},
+		{
+			"ShouldDecodeDjangoSCrypt",
+			"scrypt$16384$AkYaXgpvM9FAFHTmXQn2f3$8$1$8W2ZgUWEVjda7/7nGO4LgZ19M6icMN6ZDaB6tCk+TjynJwdQI7+2c8fo+/jPdNaFmgCsYH06mYTeeal8fV44AA==",
+			"$scrypt$ln=14,r=8,p=1$QWtZYVhncHZNOUZBRkhUbVhRbjJmMw$8W2ZgUWEVjda7/7nGO4LgZ19M6icMN6ZDaB6tCk+TjynJwdQI7+2c8fo+/jPdNaFmgCsYH06mYTeeal8fV44AA",
+		},
+		{
+			"ShouldDecodeWerkzeugPBKDF2",
+			"pbkdf2:sha256:600000$5tTpccAjQ8fPNbkf$f641bc8d5667efdb8fbe039f86e1cdbac770559632ad9f4068c035dce627c8d4",
+			"$pbkdf2-sha256$600000$NXRUcGNjQWpROGZQTmJrZg$9kG8jVZn79uPvgOfhuHNusdwVZYyrZ9AaMA13OYnyNQ",
+		},
+		{
+			"ShouldDecodeWerkzeugSCrypt",
+			"scrypt:32768:8:1$5tTpccAjQ8fPNbkf$661740c88b27f5710117b8412e0d5332243a316b005ce87bc9637d31f781b0ea2c108f79c73c71efa2080d5dab855e05403a9ca539c4f5937c12bbc51b536e35",
+			"$scrypt$ln=15,r=8,p=1$NXRUcGNjQWpROGZQTmJrZg$ZhdAyIsn9XEBF7hBLg1TMiQ6MWsAXOh7yWN9MfeBsOosEI95xzxx76IIDV2rhV4FQDqcpTnE9ZN8ErvFG1NuNQ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := DecodeAlgorithmDigest(tc.have)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, digest.Encode())
+		})
+	}
+}
+
+func TestDecodeAlgorithmDigestVariants(t *testing.T) {
+	digest, err := DecodeAlgorithmDigest("pbkdf2_sha256$870000$AkYaXgpvM9FAFHTmXQn2f3$Xu2Il6b4pQMP5zdBSMy6rZV7KVmXs208RF6PERRyyaM=")
+	require.NoError(t, err)
+
+	_, ok := digest.(*pbkdf2.Digest)
+	assert.True(t, ok)
+
+	digest, err = DecodeAlgorithmDigest("scrypt:32768:8:1$5tTpccAjQ8fPNbkf$661740c88b27f5710117b8412e0d5332243a316b005ce87bc9637d31f781b0ea2c108f79c73c71efa2080d5dab855e05403a9ca539c4f5937c12bbc51b536e35")
+	require.NoError(t, err)
+
+	_, ok = digest.(*scrypt.Digest)
+	assert.True(t, ok)
+
+	digest, err = DecodeAlgorithmDigest("$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A")
+	require.NoError(t, err)
+
+	_, ok = digest.(*balloon.Digest)
+	assert.True(t, ok)
+}
+
+func TestDecodeAlgorithmDigestErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		have string
+		err  string
+	}{
+		{
+			"ShouldErrorDjangoPBKDF2Format",
+			"pbkdf2_sha256$870000$AkYaXgpvM9FAFHTmXQn2f3",
+			"django-pbkdf2 decode error: provided encoded hash has an invalid format",
+		},
+		{
+			"ShouldErrorDjangoPBKDF2Key",
+			"pbkdf2_sha256$870000$AkYaXgpvM9FAFHTmXQn2f3$@@@@",
+			"django-pbkdf2 decode error: provided encoded hash has a key value that can't be decoded: illegal base64 data at input byte 0",
+		},
+		{
+			"ShouldErrorDjangoPBKDF2Iterations",
+			"pbkdf2_sha256$abc$AkYaXgpvM9FAFHTmXQn2f3$Xu2Il6b4pQMP5zdBSMy6rZV7KVmXs208RF6PERRyyaM=",
+			"django-pbkdf2 decode error: provided encoded hash has an invalid option value: iterations could not be parsed: strconv.Atoi: parsing \"abc\": invalid syntax",
+		},
+		{
+			"ShouldErrorDjangoSCryptCost",
+			"scrypt$16383$AkYaXgpvM9FAFHTmXQn2f3$8$1$8W2ZgUWEVjda7/7nGO4LgZ19M6icMN6ZDaB6tCk+TjynJwdQI7+2c8fo+/jPdNaFmgCsYH06mYTeeal8fV44AA==",
+			"django-scrypt decode error: provided encoded hash has an invalid option value: N must be a power of 2 greater than 1 but it's '16383'",
+		},
+		{
+			"ShouldErrorWerkzeugPBKDF2Method",
+			"pbkdf2:sha256$5tTpccAjQ8fPNbkf$f641bc8d5667efdb8fbe039f86e1cdbac770559632ad9f4068c035dce627c8d4",
+			"werkzeug-pbkdf2 decode error: provided encoded hash has an invalid format: the method must include the digest and iterations",
+		},
+		{
+			"ShouldErrorWerkzeugPBKDF2Digest",
+			"pbkdf2:md5:600000$5tTpccAjQ8fPNbkf$f641bc8d5667efdb8fbe039f86e1cdbac770559632ad9f4068c035dce627c8d4",
+			"werkzeug-pbkdf2 decode error: provided encoded hash has an invalid identifier: digest 'md5' is unknown",
+		},
+		{
+			"ShouldErrorWerkzeugSCryptKey",
+			"scrypt:32768:8:1$5tTpccAjQ8fPNbkf$zz",
+			"werkzeug-scrypt decode error: provided encoded hash has a key value that can't be decoded: encoding/hex: invalid byte: U+007A 'z'",
+		},
+		{
+			"ShouldErrorWerkzeugSCryptMethod",
+			"scrypt:32768:8$5tTpccAjQ8fPNbkf$661740c88b27f5710117b8412e0d5332243a316b005ce87bc9637d31f781b0ea2c108f79c73c71efa2080d5dab855e05403a9ca539c4f5937c12bbc51b536e35",
+			"werkzeug-scrypt decode error: provided encoded hash has an invalid format: the method must include the N, r, and p parameters",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := DecodeAlgorithmDigest(tc.have)
+
+			assert.Nil(t, digest)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		password string
+		expected bool
+	}{
+		{"ShouldMatchBalloon", "$balloon-sha256$s=16,t=20,d=4$SnFNY0hxVWNqaW5GaFFLSg$LsjYM9tfiOWEq3k5UOz7IWV6OBbt6o2ec+ojwTuit0A", "buildmeupbuttercup", true},
+		{"ShouldMatchDjangoPBKDF2", "pbkdf2_sha1$100000$AkYaXgpvM9FAFHTmXQn2f3$rIXp5Ll0KtUDhztx7pVUm/pRRzI=", "password", true},
+		{"ShouldMatchDjangoSCrypt", "scrypt$16384$AkYaXgpvM9FAFHTmXQn2f3$8$1$8W2ZgUWEVjda7/7nGO4LgZ19M6icMN6ZDaB6tCk+TjynJwdQI7+2c8fo+/jPdNaFmgCsYH06mYTeeal8fV44AA==", "password", true},
+		{"ShouldMatchWerkzeugSCrypt", "scrypt:32768:8:1$5tTpccAjQ8fPNbkf$661740c88b27f5710117b8412e0d5332243a316b005ce87bc9637d31f781b0ea2c108f79c73c71efa2080d5dab855e05403a9ca539c4f5937c12bbc51b536e35", "password", true},
+		{"ShouldNotMatchDjangoPBKDF2", "pbkdf2_sha1$100000$AkYaXgpvM9FAFHTmXQn2f3$rIXp5Ll0KtUDhztx7pVUm/pRRzI=", "wrong", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, err := CheckPassword(tc.password, tc.have)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, valid)
+		})
+	}
+}
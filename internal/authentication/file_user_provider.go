@@ -15,6 +15,7 @@ import (
 	"github.com/go-crypt/crypt/algorithm/scrypt"
 	"github.com/go-crypt/crypt/algorithm/shacrypt"
 
+	"github.com/authelia/authelia/v4/internal/authentication/balloon"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 )
@@ -193,6 +194,14 @@ func NewFileCryptoHashFromConfig(config schema.AuthenticationBackendFilePassword
 			bcrypt.WithVariantName(config.BCrypt.Variant),
 			bcrypt.WithIterations(config.BCrypt.Cost),
 		)
+	case hashBalloon:
+		hash, err = balloon.New(
+			balloon.WithVariantName(config.Balloon.Variant),
+			balloon.WithSpaceCost(config.Balloon.SpaceCost),
+			balloon.WithTimeCost(config.Balloon.TimeCost),
+			balloon.WithDelta(config.Balloon.Delta),
+			balloon.WithSaltLength(config.Balloon.SaltLength),
+		)
 	default:
 		return nil, fmt.Errorf("algorithm '%s' is unknown", config.Algorithm)
 	}
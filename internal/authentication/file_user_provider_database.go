@@ -7,7 +7,6 @@ import (
 	"sync"
 
 	"github.com/asaskevich/govalidator"
-	"github.com/go-crypt/crypt"
 	"github.com/go-crypt/crypt/algorithm"
 	"gopkg.in/yaml.v3"
 
@@ -321,7 +320,7 @@ type FileDatabaseUserDetailsModel struct {
 func (m FileDatabaseUserDetailsModel) ToDatabaseUserDetailsModel(username string) (model *FileUserDatabaseUserDetails, err error) {
 	var d algorithm.Digest
 
-	if d, err = crypt.Decode(m.Password); err != nil {
+	if d, err = DecodeAlgorithmDigest(m.Password); err != nil {
 		return nil, err
 	}
 
@@ -18,6 +18,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
+	"github.com/authelia/authelia/v4/internal/authentication/balloon"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
@@ -633,6 +634,29 @@ func TestNewFileCryptoHashFromConfig(t *testing.T) {
 			&bcrypt.Hasher{},
 			"",
 		},
+		{
+			"ShouldCreateBalloon",
+			schema.AuthenticationBackendFilePassword{
+				Algorithm: "balloon",
+				Balloon: schema.AuthenticationBackendFilePasswordBalloon{
+					Variant:    "sha512",
+					SpaceCost:  1024,
+					TimeCost:   3,
+					Delta:      3,
+					SaltLength: 16,
+				},
+			},
+			&balloon.Hasher{},
+			"",
+		},
+		{
+			"ShouldFailToCreateBalloonInvalidParameter",
+			schema.AuthenticationBackendFilePassword{
+				Algorithm: "balloon",
+			},
+			nil,
+			"failed to initialize hash settings: balloon validation error: parameter is invalid: parameter 'space cost' must be between 1 and 2147483647 but is set to '0'",
+		},
 		{
 			"ShouldFailToCreateSCryptInvalidParameter",
 			schema.AuthenticationBackendFilePassword{
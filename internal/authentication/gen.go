@@ -7,3 +7,4 @@ package authentication
 //go:generate mockgen -package authentication -destination ldap_client_factory_mock_test.go -mock_names LDAPClientFactory=MockLDAPClientFactory github.com/authelia/authelia/v4/internal/authentication LDAPClientFactory
 //go:generate mockgen -package authentication -destination file_user_provider_database_mock_test.go -mock_names FileUserDatabase=MockFileUserDatabase github.com/authelia/authelia/v4/internal/authentication FileUserDatabase
 //go:generate mockgen -package authentication -destination file_user_provider_hash_mock_test.go -mock_names Hash=MockHash github.com/go-crypt/crypt/algorithm Hash
+//go:generate mockgen -package authentication -destination sql_user_provider_storage_mock_test.go -mock_names SQLUserProviderStorage=MockSQLUserProviderStorage github.com/authelia/authelia/v4/internal/authentication SQLUserProviderStorage
@@ -0,0 +1,149 @@
+package authentication
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// newLDAPClientPool creates a new ldapClientPool which opens at most config.Count clients with the dial func.
+func newLDAPClientPool(config schema.AuthenticationBackendLDAPPooling, dial func() (client LDAPClient, err error)) *ldapClientPool {
+	if config.Count < 1 {
+		config.Count = schema.DefaultLDAPAuthenticationBackendConfigurationPooling.Count
+	}
+
+	if config.Retries < 1 {
+		config.Retries = 1
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultLDAPAuthenticationBackendConfigurationPooling.Timeout
+	}
+
+	return &ldapClientPool{
+		dial:    dial,
+		retries: config.Retries,
+		timeout: config.Timeout,
+		idle:    make(chan LDAPClient, config.Count),
+		slots:   make(chan struct{}, config.Count),
+	}
+}
+
+// ldapClientPool is a pool of LDAP clients which are bound as the service account. Every open client holds a slot
+// until it's discarded, which caps the number of open clients.
+type ldapClientPool struct {
+	dial    func() (client LDAPClient, err error)
+	retries int
+	timeout time.Duration
+
+	idle  chan LDAPClient
+	slots chan struct{}
+}
+
+// Get returns an idle client from the pool, or opens a new client if the pool has a free slot. If neither is available
+// it waits for a client to be released until the timeout.
+func (p *ldapClientPool) Get() (client LDAPClient, err error) {
+	timer := time.NewTimer(p.timeout)
+
+	defer timer.Stop()
+
+	for {
+		select {
+		case client = <-p.idle:
+			if client.IsClosing() {
+				p.discard(client)
+
+				continue
+			}
+
+			return p.wrap(client), nil
+		default:
+		}
+
+		select {
+		case client = <-p.idle:
+			if client.IsClosing() {
+				p.discard(client)
+
+				continue
+			}
+
+			return p.wrap(client), nil
+		case p.slots <- struct{}{}:
+			if client, err = p.open(); err != nil {
+				<-p.slots
+
+				return nil, err
+			}
+
+			return p.wrap(client), nil
+		case <-timer.C:
+			return nil, fmt.Errorf("timeout waiting for an available connection from the pool after %s", p.timeout)
+		}
+	}
+}
+
+// Close closes all of the idle clients in the pool.
+func (p *ldapClientPool) Close() {
+	for {
+		select {
+		case client := <-p.idle:
+			p.discard(client)
+		default:
+			return
+		}
+	}
+}
+
+func (p *ldapClientPool) open() (client LDAPClient, err error) {
+	for i := 0; i < p.retries; i++ {
+		if client, err = p.dial(); err == nil {
+			return client, nil
+		}
+	}
+
+	return nil, err
+}
+
+func (p *ldapClientPool) put(client LDAPClient) {
+	if client.IsClosing() {
+		p.discard(client)
+
+		return
+	}
+
+	select {
+	case p.idle <- client:
+	default:
+		p.discard(client)
+	}
+}
+
+func (p *ldapClientPool) discard(client LDAPClient) {
+	_ = client.Close()
+
+	<-p.slots
+}
+
+func (p *ldapClientPool) wrap(client LDAPClient) LDAPClient {
+	return &ldapPooledClient{LDAPClient: client, pool: p}
+}
+
+// ldapPooledClient is a LDAPClient from a ldapClientPool which is released back to the pool instead of being closed.
+type ldapPooledClient struct {
+	LDAPClient
+
+	pool *ldapClientPool
+	once sync.Once
+}
+
+// Close releases the client back to the pool.
+func (c *ldapPooledClient) Close() (err error) {
+	c.once.Do(func() {
+		c.pool.put(c.LDAPClient)
+	})
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestLDAPClientPool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	first, second := NewMockLDAPClient(ctrl), NewMockLDAPClient(ctrl)
+
+	clients := []LDAPClient{first, second}
+
+	pool := newLDAPClientPool(schema.AuthenticationBackendLDAPPooling{Enable: true, Count: 2, Retries: 1, Timeout: time.Millisecond * 50}, func() (client LDAPClient, err error) {
+		client, clients = clients[0], clients[1:]
+
+		return client, nil
+	})
+
+	a, err := pool.Get()
+	require.NoError(t, err)
+
+	b, err := pool.Get()
+	require.NoError(t, err)
+
+	_, err = pool.Get()
+	assert.EqualError(t, err, "timeout waiting for an available connection from the pool after 50ms")
+
+	first.EXPECT().IsClosing().Return(false).Times(2)
+
+	assert.NoError(t, a.Close())
+	assert.NoError(t, a.Close())
+
+	c, err := pool.Get()
+	require.NoError(t, err)
+
+	assert.Equal(t, first, c.(*ldapPooledClient).LDAPClient)
+
+	gomock.InOrder(
+		second.EXPECT().IsClosing().Return(true),
+		second.EXPECT().Close().Return(nil),
+	)
+
+	assert.NoError(t, b.Close())
+
+	assert.Len(t, pool.slots, 1)
+	assert.Len(t, pool.idle, 0)
+}
+
+func TestLDAPClientPoolShouldRetryDial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	client := NewMockLDAPClient(ctrl)
+
+	attempts := 0
+
+	pool := newLDAPClientPool(schema.AuthenticationBackendLDAPPooling{Enable: true, Count: 1, Retries: 2, Timeout: time.Second}, func() (LDAPClient, error) {
+		attempts++
+
+		if attempts == 1 {
+			return nil, errors.New("dial failed with error: could not connect")
+		}
+
+		return client, nil
+	})
+
+	actual, err := pool.Get()
+	require.NoError(t, err)
+
+	assert.Equal(t, client, actual.(*ldapPooledClient).LDAPClient)
+	assert.Equal(t, 2, attempts)
+
+	pool = newLDAPClientPool(schema.AuthenticationBackendLDAPPooling{Enable: true, Count: 1, Retries: 2, Timeout: time.Second}, func() (LDAPClient, error) {
+		return nil, errors.New("dial failed with error: could not connect")
+	})
+
+	_, err = pool.Get()
+	assert.EqualError(t, err, "dial failed with error: could not connect")
+
+	assert.Len(t, pool.slots, 0)
+}
+
+func TestShouldConnectWithPool(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+	mockClient := NewMockLDAPClient(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.AuthenticationBackendLDAP{
+			Address:  testLDAPAddress,
+			User:     "cn=admin,dc=example,dc=com",
+			Password: "password",
+			Pooling:  schema.AuthenticationBackendLDAPPooling{Enable: true, Count: 1, Retries: 1, Timeout: time.Second},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	gomock.InOrder(
+		mockFactory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(mockClient, nil),
+		mockClient.EXPECT().Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).Return(nil),
+		mockClient.EXPECT().IsClosing().Return(false),
+		mockClient.EXPECT().IsClosing().Return(false),
+	)
+
+	client, err := provider.connect()
+	require.NoError(t, err)
+	assert.NoError(t, client.Close())
+
+	client, err = provider.connect()
+	require.NoError(t, err)
+
+	assert.Equal(t, mockClient, client.(*ldapPooledClient).LDAPClient)
+}
@@ -0,0 +1,256 @@
+package authentication
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/breaker"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+// MultiLDAPUserProvider is a UserProvider which uses multiple LDAP backends. The users whose username has a domain
+// which is configured for a backend are only looked up in that backend, and the other users are looked up in each of
+// the backends in order. The backends which fail the health check are skipped until they're healthy again.
+type MultiLDAPUserProvider struct {
+	backends []*ldapBackend
+	log      *logrus.Logger
+
+	clock clock.Provider
+}
+
+// NewMultiLDAPUserProvider creates a new instance of MultiLDAPUserProvider with the ProductionLDAPClientFactory.
+func NewMultiLDAPUserProvider(config schema.AuthenticationBackend, certPool *x509.CertPool) (provider *MultiLDAPUserProvider) {
+	return NewMultiLDAPUserProviderWithFactory(config.LDAPBackends, config.PasswordReset.Disable, certPool, NewProductionLDAPClientFactory())
+}
+
+// NewMultiLDAPUserProviderWithFactory creates a new instance of MultiLDAPUserProvider with the specified
+// LDAPClientFactory.
+func NewMultiLDAPUserProviderWithFactory(config []schema.AuthenticationBackendLDAPBackend, disableResetPassword bool, certPool *x509.CertPool, factory LDAPClientFactory) (provider *MultiLDAPUserProvider) {
+	provider = &MultiLDAPUserProvider{
+		backends: make([]*ldapBackend, len(config)),
+		log:      logging.LoggerModule(logging.ModuleLDAP),
+		clock:    clock.New(),
+	}
+
+	for i, backend := range config {
+		provider.backends[i] = &ldapBackend{
+			name:     backend.Name,
+			domains:  backend.Domains,
+			provider: NewLDAPUserProviderWithFactory(backend.AuthenticationBackendLDAP, disableResetPassword, certPool, factory),
+		}
+	}
+
+	return provider
+}
+
+// CheckUserPassword checks if provided password matches for the given user.
+func (p *MultiLDAPUserProvider) CheckUserPassword(username string, password string) (valid bool, err error) {
+	err = p.each(username, func(provider *LDAPUserProvider) (err error) {
+		valid, err = provider.CheckUserPassword(username, password)
+
+		return err
+	})
+
+	return valid, err
+}
+
+// GetDetails retrieve the groups a user belongs to.
+func (p *MultiLDAPUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	err = p.each(username, func(provider *LDAPUserProvider) (err error) {
+		details, err = provider.GetDetails(username)
+
+		return err
+	})
+
+	return details, err
+}
+
+// UpdatePassword update the password of the given user.
+func (p *MultiLDAPUserProvider) UpdatePassword(username, password string) (err error) {
+	return p.each(username, func(provider *LDAPUserProvider) (err error) {
+		return provider.UpdatePassword(username, password)
+	})
+}
+
+// SetCircuitBreakers sets the circuit breaker of each backend to the one returned by fn for the backend name and the
+// provider of the backend which is used to probe it.
+func (p *MultiLDAPUserProvider) SetCircuitBreakers(fn func(name string, provider *LDAPUserProvider) *breaker.Breaker) {
+	for _, backend := range p.backends {
+		backend.provider.SetCircuitBreaker(fn(backend.name, backend.provider))
+	}
+}
+
+// StartupCheck implements the startup check provider interface. It only fails if all of the backends fail, the
+// backends which fail are checked again the next time they're used.
+func (p *MultiLDAPUserProvider) StartupCheck() (err error) {
+	var errs []error
+
+	now := p.clock.Now()
+
+	for _, backend := range p.backends {
+		if err = backend.startup(now); err != nil {
+			p.log.WithError(err).Errorf("LDAP backend '%s' failed the startup check and will be skipped until it's available", backend.name)
+
+			errs = append(errs, fmt.Errorf("backend '%s': %w", backend.name, err))
+		}
+	}
+
+	if len(errs) == len(p.backends) {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// HealthCheck implements the health check provider interface. It only fails if none of the backends are healthy.
+func (p *MultiLDAPUserProvider) HealthCheck(ctx context.Context) (err error) {
+	var errs []error
+
+	now := p.clock.Now()
+
+	for _, backend := range p.backends {
+		if err = backend.check(ctx, now); err != nil {
+			errs = append(errs, fmt.Errorf("backend '%s': %w", backend.name, err))
+		}
+	}
+
+	if len(errs) == len(p.backends) {
+		return errors.Join(errs...)
+	}
+
+	return nil
+}
+
+// each performs fn with the provider of each backend the user may be in until the user is found. If fn returns an
+// error other than ErrUserNotFound the backend is checked, and the error is returned if it's healthy, otherwise the
+// next backend is used.
+func (p *MultiLDAPUserProvider) each(username string, fn func(provider *LDAPUserProvider) (err error)) (err error) {
+	var unavailable []string
+
+	now := p.clock.Now()
+
+	for _, backend := range p.route(username) {
+		if !backend.available(now) {
+			unavailable = append(unavailable, backend.name)
+
+			continue
+		}
+
+		if err = fn(backend.provider); err == nil {
+			return nil
+		}
+
+		if errors.Is(err, ErrUserNotFound) {
+			continue
+		}
+
+		errCheck := backend.check(context.Background(), now)
+		if errCheck == nil {
+			return err
+		}
+
+		p.log.WithError(errCheck).Errorf("LDAP backend '%s' failed the health check and will be skipped for %s", backend.name, ldapBackendUnhealthyInterval)
+
+		unavailable = append(unavailable, backend.name)
+	}
+
+	if len(unavailable) != 0 {
+		return fmt.Errorf("user '%s' was not found in the available backends and the backends '%s' are unavailable", username, strings.Join(unavailable, "', '"))
+	}
+
+	return ErrUserNotFound
+}
+
+// route returns the backends the user may be in. If the domain of the username is configured for a backend it's the
+// only backend, otherwise it's all the backends in order.
+func (p *MultiLDAPUserProvider) route(username string) (backends []*ldapBackend) {
+	if i := strings.LastIndex(username, "@"); i != -1 {
+		domain := strings.ToLower(username[i+1:])
+
+		for _, backend := range p.backends {
+			for _, d := range backend.domains {
+				if d == domain {
+					return []*ldapBackend{backend}
+				}
+			}
+		}
+	}
+
+	return p.backends
+}
+
+// ldapBackend is a single backend of the MultiLDAPUserProvider and its health.
+type ldapBackend struct {
+	name     string
+	domains  []string
+	provider *LDAPUserProvider
+
+	mu        sync.Mutex
+	started   bool
+	unhealthy time.Time
+}
+
+// available returns true if the backend passed the startup check and didn't recently fail the health check. The
+// startup check is performed if it hasn't passed yet.
+func (b *ldapBackend) available(now time.Time) bool {
+	b.mu.Lock()
+
+	started, unhealthy := b.started, b.unhealthy
+
+	b.mu.Unlock()
+
+	if now.Before(unhealthy) {
+		return false
+	}
+
+	if started {
+		return true
+	}
+
+	return b.startup(now) == nil
+}
+
+func (b *ldapBackend) startup(now time.Time) (err error) {
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	if b.started {
+		return nil
+	}
+
+	if err = b.provider.StartupCheck(); err != nil {
+		b.unhealthy = now.Add(ldapBackendUnhealthyInterval)
+
+		return err
+	}
+
+	b.started, b.unhealthy = true, time.Time{}
+
+	return nil
+}
+
+func (b *ldapBackend) check(ctx context.Context, now time.Time) (err error) {
+	err = b.provider.HealthCheck(ctx)
+
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.unhealthy = now.Add(ldapBackendUnhealthyInterval)
+	} else {
+		b.unhealthy = time.Time{}
+	}
+
+	return err
+}
@@ -0,0 +1,183 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestMultiLDAPUserProvider(t *testing.T) (provider *MultiLDAPUserProvider, factory *MockLDAPClientFactory, client *MockLDAPClient) {
+	ctrl := gomock.NewController(t)
+
+	factory = NewMockLDAPClientFactory(ctrl)
+	client = NewMockLDAPClient(ctrl)
+
+	provider = NewMultiLDAPUserProviderWithFactory(
+		[]schema.AuthenticationBackendLDAPBackend{
+			{
+				Name:    "corp",
+				Domains: []string{"corp.example.com"},
+				AuthenticationBackendLDAP: schema.AuthenticationBackendLDAP{
+					Address:  testLDAPAddress,
+					User:     "cn=admin,dc=corp,dc=example,dc=com",
+					Password: "password",
+				},
+			},
+			{
+				Name: "partners",
+				AuthenticationBackendLDAP: schema.AuthenticationBackendLDAP{
+					Address:  testLDAPSAddress,
+					User:     "cn=admin,dc=partners,dc=example,dc=com",
+					Password: "password",
+				},
+			},
+		},
+		false,
+		nil,
+		factory)
+
+	provider.clock = clock.NewFixed(time.Unix(1000000000, 0))
+
+	for _, backend := range provider.backends {
+		backend.started = true
+	}
+
+	return provider, factory, client
+}
+
+func TestMultiLDAPUserProviderRoute(t *testing.T) {
+	provider, _, _ := newTestMultiLDAPUserProvider(t)
+
+	testCases := []struct {
+		name     string
+		username string
+		expected []string
+	}{
+		{"ShouldRouteDomain", "john@corp.example.com", []string{"corp"}},
+		{"ShouldRouteDomainCaseInsensitive", "john@CORP.example.com", []string{"corp"}},
+		{"ShouldNotRouteOtherDomain", "john@example.com", []string{"corp", "partners"}},
+		{"ShouldNotRouteWithoutDomain", "john", []string{"corp", "partners"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []string
+
+			for _, backend := range provider.route(tc.username) {
+				actual = append(actual, backend.name)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMultiLDAPUserProviderEach(t *testing.T) {
+	provider, factory, client := newTestMultiLDAPUserProvider(t)
+
+	var called []string
+
+	fn := func(results map[string]error) func(provider *LDAPUserProvider) error {
+		return func(p *LDAPUserProvider) error {
+			name := "corp"
+			if p == provider.backends[1].provider {
+				name = "partners"
+			}
+
+			called = append(called, name)
+
+			return results[name]
+		}
+	}
+
+	called = nil
+
+	assert.NoError(t, provider.each("john", fn(map[string]error{"corp": ErrUserNotFound})))
+	assert.Equal(t, []string{"corp", "partners"}, called)
+
+	called = nil
+
+	assert.ErrorIs(t, provider.each("john", fn(map[string]error{"corp": ErrUserNotFound, "partners": ErrUserNotFound})), ErrUserNotFound)
+	assert.Equal(t, []string{"corp", "partners"}, called)
+
+	called = nil
+
+	assert.ErrorIs(t, provider.each("john@corp.example.com", fn(map[string]error{"corp": ErrUserNotFound})), ErrUserNotFound)
+	assert.Equal(t, []string{"corp"}, called)
+
+	called = nil
+
+	gomock.InOrder(
+		factory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(client, nil),
+		client.EXPECT().Bind(gomock.Eq("cn=admin,dc=corp,dc=example,dc=com"), gomock.Eq("password")).Return(nil),
+		client.EXPECT().Close().Return(nil),
+	)
+
+	assert.EqualError(t, provider.each("john", fn(map[string]error{"corp": errors.New("authentication failed. Cause: bad password")})), "authentication failed. Cause: bad password")
+	assert.Equal(t, []string{"corp"}, called)
+
+	called = nil
+
+	factory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(nil, errors.New("could not connect"))
+
+	assert.NoError(t, provider.each("john", fn(map[string]error{"corp": errors.New("search failed")})))
+	assert.Equal(t, []string{"corp", "partners"}, called)
+
+	called = nil
+
+	assert.EqualError(t, provider.each("john", fn(map[string]error{"partners": ErrUserNotFound})), "user 'john' was not found in the available backends and the backends 'corp' are unavailable")
+	assert.Equal(t, []string{"partners"}, called)
+
+	provider.clock = clock.NewFixed(time.Unix(1000000000, 0).Add(ldapBackendUnhealthyInterval))
+
+	called = nil
+
+	assert.NoError(t, provider.each("john", fn(nil)))
+	assert.Equal(t, []string{"corp"}, called)
+}
+
+func TestMultiLDAPUserProviderHealthCheck(t *testing.T) {
+	provider, factory, client := newTestMultiLDAPUserProvider(t)
+
+	gomock.InOrder(
+		factory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(nil, errors.New("could not connect")),
+		factory.EXPECT().DialURL(gomock.Eq("ldaps://127.0.0.1:636"), gomock.Any()).Return(client, nil),
+		client.EXPECT().Bind(gomock.Eq("cn=admin,dc=partners,dc=example,dc=com"), gomock.Eq("password")).Return(nil),
+		client.EXPECT().Close().Return(nil),
+	)
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	assert.False(t, provider.backends[0].unhealthy.IsZero())
+	assert.True(t, provider.backends[1].unhealthy.IsZero())
+
+	factory.EXPECT().DialURL(gomock.Any(), gomock.Any()).Return(nil, errors.New("could not connect")).Times(2)
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "backend 'corp': dial failed with error: could not connect\nbackend 'partners': dial failed with error: could not connect")
+}
+
+func TestMultiLDAPUserProviderStartupCheck(t *testing.T) {
+	provider, factory, _ := newTestMultiLDAPUserProvider(t)
+
+	for _, backend := range provider.backends {
+		backend.started = false
+	}
+
+	factory.EXPECT().DialURL(gomock.Any(), gomock.Any()).Return(nil, errors.New("could not connect")).Times(2)
+
+	err := provider.StartupCheck()
+	require.Error(t, err)
+
+	assert.EqualError(t, err, "backend 'corp': dial failed with error: could not connect\nbackend 'partners': dial failed with error: could not connect")
+
+	assert.False(t, provider.backends[0].started)
+	assert.False(t, provider.backends[0].available(provider.clock.Now()))
+}
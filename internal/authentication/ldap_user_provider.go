@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"github.com/go-ldap/ldap/v3"
 	"github.com/sirupsen/logrus"
 
+	"github.com/authelia/authelia/v4/internal/breaker"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
@@ -24,6 +26,8 @@ type LDAPUserProvider struct {
 	dialOpts  []ldap.DialOpt
 	log       *logrus.Logger
 	factory   LDAPClientFactory
+	pool      *ldapClientPool
+	breaker   *breaker.Breaker
 
 	clock clock.Provider
 
@@ -81,12 +85,16 @@ func NewLDAPUserProviderWithFactory(config schema.AuthenticationBackendLDAP, dis
 		config:               config,
 		tlsConfig:            tlsConfig,
 		dialOpts:             dialOpts,
-		log:                  logging.Logger(),
+		log:                  logging.LoggerModule(logging.ModuleLDAP),
 		factory:              factory,
 		disableResetPassword: disableResetPassword,
 		clock:                clock.New(),
 	}
 
+	if config.Pooling.Enable {
+		provider.pool = newLDAPClientPool(config.Pooling, provider.dial)
+	}
+
 	provider.parseDynamicUsersConfiguration()
 	provider.parseDynamicGroupsConfiguration()
 
@@ -209,7 +217,28 @@ func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error)
 	return nil
 }
 
+// SetCircuitBreaker sets the circuit breaker used when connecting as the service account.
+func (p *LDAPUserProvider) SetCircuitBreaker(b *breaker.Breaker) {
+	p.breaker = b
+}
+
+// connect returns a client bound as the service account, which is taken from the pool when pooling is enabled. The
+// connection fails fast without contacting the server while the circuit breaker is open.
 func (p *LDAPUserProvider) connect() (client LDAPClient, err error) {
+	err = p.breaker.Do(context.Background(), func(_ context.Context) (err error) {
+		if p.pool != nil {
+			client, err = p.pool.Get()
+		} else {
+			client, err = p.dial()
+		}
+
+		return err
+	})
+
+	return client, err
+}
+
+func (p *LDAPUserProvider) dial() (client LDAPClient, err error) {
 	return p.connectCustom(p.config.Address.String(), p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...)
 }
 
@@ -304,10 +333,7 @@ func (p *LDAPUserProvider) searchReferrals(request *ldap.SearchRequest, result *
 
 func (p *LDAPUserProvider) getUserProfile(client LDAPClient, username string) (profile *ldapUserProfile, err error) {
 	// Search for the given username.
-	request := ldap.NewSearchRequest(
-		p.usersBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
-		1, 0, false, p.resolveUsersFilter(username), p.usersAttributes, nil,
-	)
+	request := p.getUserProfileRequest(username)
 
 	p.log.
 		WithField("base_dn", request.BaseDN).
@@ -334,6 +360,13 @@ func (p *LDAPUserProvider) getUserProfile(client LDAPClient, username string) (p
 	return p.getUserProfileResultToProfile(username, result)
 }
 
+func (p *LDAPUserProvider) getUserProfileRequest(username string) (request *ldap.SearchRequest) {
+	return ldap.NewSearchRequest(
+		p.usersBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		1, 0, false, p.resolveUsersFilter(username), p.usersAttributes, nil,
+	)
+}
+
 //nolint:gocyclo // Not overly complex.
 func (p *LDAPUserProvider) getUserProfileResultToProfile(username string, result *ldap.SearchResult) (profile *ldapUserProfile, err error) {
 	userProfile := ldapUserProfile{
@@ -397,10 +430,7 @@ func (p *LDAPUserProvider) getUserProfileResultToProfile(username string, result
 }
 
 func (p *LDAPUserProvider) getUserGroups(client LDAPClient, username string, profile *ldapUserProfile) (groups []string, err error) {
-	request := ldap.NewSearchRequest(
-		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
-		0, 0, false, p.resolveGroupsFilter(username, profile), p.groupsAttributes, nil,
-	)
+	request := p.getUserGroupsRequest(username, profile)
 
 	p.log.
 		WithField("base_dn", request.BaseDN).
@@ -421,6 +451,13 @@ func (p *LDAPUserProvider) getUserGroups(client LDAPClient, username string, pro
 	}
 }
 
+func (p *LDAPUserProvider) getUserGroupsRequest(username string, profile *ldapUserProfile) (request *ldap.SearchRequest) {
+	return ldap.NewSearchRequest(
+		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, p.resolveGroupsFilter(username, profile), p.groupsAttributes, nil,
+	)
+}
+
 func (p *LDAPUserProvider) getUserGroupsRequestFilter(client LDAPClient, username string, _ *ldapUserProfile, request *ldap.SearchRequest) (groups []string, err error) {
 	var result *ldap.SearchResult
 
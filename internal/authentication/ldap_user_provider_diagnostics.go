@@ -0,0 +1,192 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// LDAPDiagnostics is the result of diagnosing the connection to the LDAP server and the searches performed for a user.
+type LDAPDiagnostics struct {
+	Address  string
+	StartTLS bool
+	TLS      *tls.ConnectionState
+	Features LDAPSupportedFeatures
+
+	Users  *LDAPDiagnosticsSearch
+	Groups *LDAPDiagnosticsSearch
+
+	Details *UserDetails
+
+	Warnings []string
+}
+
+// LDAPDiagnosticsSearch is the request and result of a search performed while diagnosing a user.
+type LDAPDiagnosticsSearch struct {
+	BaseDN     string
+	Filter     string
+	Attributes []string
+	Entries    []*ldap.Entry
+	Referrals  []string
+}
+
+// Diagnose connects to the LDAP server and performs the same users and groups searches used during authentication for
+// the given username, recording the resolved requests and their results. The diagnostics are returned even when an
+// error occurs so the stages which completed successfully can be inspected.
+func (p *LDAPUserProvider) Diagnose(username string) (diagnostics *LDAPDiagnostics, err error) {
+	diagnostics = &LDAPDiagnostics{
+		Address:  p.config.Address.String(),
+		StartTLS: p.config.StartTLS,
+	}
+
+	if !p.config.StartTLS && !p.config.Address.IsExplicitlySecure() {
+		diagnostics.warn("The connection is not secured with TLS as the address does not use the 'ldaps' scheme and StartTLS is not enabled")
+	}
+
+	var client LDAPClient
+
+	if client, err = p.connect(); err != nil {
+		diagnostics.warnTLS(err)
+
+		return diagnostics, fmt.Errorf("error occurred connecting to the LDAP server: %w", err)
+	}
+
+	defer client.Close()
+
+	if state, ok := client.TLSConnectionState(); ok {
+		diagnostics.TLS = &state
+	}
+
+	if diagnostics.Features, err = p.getServerSupportedFeatures(client); err != nil {
+		return diagnostics, err
+	}
+
+	if diagnostics.Features.Extensions.TLS && diagnostics.TLS == nil {
+		diagnostics.warn("The LDAP server supports TLS but the connection is not secured with TLS")
+	}
+
+	if !diagnostics.Features.Extensions.PwdModifyExOp && !p.disableResetPassword && p.config.Implementation != schema.LDAPImplementationActiveDirectory {
+		diagnostics.warn("The LDAP server does not advertise the Password Modify Extended Operation so it may not hash passwords which are reset")
+	}
+
+	var profile *ldapUserProfile
+
+	if diagnostics.Users, profile, err = p.diagnoseUser(client, username); err != nil {
+		diagnostics.warnReferral(err)
+
+		return diagnostics, err
+	}
+
+	var groups []string
+
+	if diagnostics.Groups, groups, err = p.diagnoseGroups(client, username, profile); err != nil {
+		diagnostics.warnReferral(err)
+
+		return diagnostics, err
+	}
+
+	diagnostics.Details = &UserDetails{
+		Username:    profile.Username,
+		DisplayName: profile.DisplayName,
+		Emails:      profile.Emails,
+		Groups:      groups,
+	}
+
+	if len(groups) == 0 && len(diagnostics.Groups.Entries) != 0 {
+		diagnostics.warn(fmt.Sprintf("The groups search returned %d entries but none of them resolved to a group, check the group name attribute '%s' is returned by the LDAP server", len(diagnostics.Groups.Entries), p.config.Attributes.GroupName))
+	}
+
+	return diagnostics, nil
+}
+
+func (p *LDAPUserProvider) diagnoseUser(client LDAPClient, username string) (search *LDAPDiagnosticsSearch, profile *ldapUserProfile, err error) {
+	var result *ldap.SearchResult
+
+	if search, result, err = p.diagnoseSearch(client, p.getUserProfileRequest(username)); err != nil {
+		return search, nil, fmt.Errorf("error occurred performing the users search: %w", err)
+	}
+
+	switch {
+	case len(result.Entries) == 0:
+		return search, nil, fmt.Errorf("error occurred performing the users search: %w", ErrUserNotFound)
+	case len(result.Entries) > 1:
+		return search, nil, fmt.Errorf("error occurred performing the users search: there were %d users found when searching for '%s' but there should only be 1", len(result.Entries), username)
+	}
+
+	if profile, err = p.getUserProfileResultToProfile(username, result); err != nil {
+		return search, nil, fmt.Errorf("error occurred resolving the user from the users search: %w", err)
+	}
+
+	return search, profile, nil
+}
+
+func (p *LDAPUserProvider) diagnoseGroups(client LDAPClient, username string, profile *ldapUserProfile) (search *LDAPDiagnosticsSearch, groups []string, err error) {
+	if search, _, err = p.diagnoseSearch(client, p.getUserGroupsRequest(username, profile)); err != nil {
+		return search, nil, fmt.Errorf("error occurred performing the groups search: %w", err)
+	}
+
+	if groups, err = p.getUserGroups(client, username, profile); err != nil {
+		return search, nil, fmt.Errorf("error occurred resolving the groups from the groups search: %w", err)
+	}
+
+	return search, groups, nil
+}
+
+func (p *LDAPUserProvider) diagnoseSearch(client LDAPClient, request *ldap.SearchRequest) (search *LDAPDiagnosticsSearch, result *ldap.SearchResult, err error) {
+	search = &LDAPDiagnosticsSearch{
+		BaseDN:     request.BaseDN,
+		Filter:     request.Filter,
+		Attributes: request.Attributes,
+	}
+
+	if result, err = p.search(client, request); err != nil {
+		return search, nil, err
+	}
+
+	search.Entries, search.Referrals = result.Entries, result.Referrals
+
+	return search, result, nil
+}
+
+func (d *LDAPDiagnostics) warn(warning string) {
+	d.Warnings = append(d.Warnings, warning)
+}
+
+// warnTLS adds a warning describing the likely cause of an error which occurred during the TLS handshake.
+func (d *LDAPDiagnostics) warnTLS(err error) {
+	var (
+		errUnknownAuthority x509.UnknownAuthorityError
+		errHostname         x509.HostnameError
+		errInvalid          x509.CertificateInvalidError
+		errRecordHeader     tls.RecordHeaderError
+	)
+
+	switch {
+	case errors.As(err, &errUnknownAuthority):
+		d.warn("The certificate of the LDAP server is signed by an unknown authority, the certificate of the authority should be added to the certificates directory")
+	case errors.As(err, &errHostname):
+		d.warn("The certificate of the LDAP server is not valid for the hostname, the server name of the TLS configuration should be set to a name in the certificate")
+	case errors.As(err, &errInvalid):
+		d.warn("The certificate of the LDAP server is not valid, it may be expired or not yet valid")
+	case errors.As(err, &errRecordHeader):
+		d.warn("The LDAP server did not respond with a TLS handshake, the address may need to use the 'ldap' scheme instead of the 'ldaps' scheme")
+	}
+}
+
+// warnReferral adds a warning if the error was caused by a referral which was not followed.
+func (d *LDAPDiagnostics) warnReferral(err error) {
+	var e *ldap.Error
+
+	if !errors.As(err, &e) {
+		return
+	}
+
+	if referral, ok := ldapGetReferral(e); ok {
+		d.warn(fmt.Sprintf("The LDAP server returned a referral to '%s' which was not followed as referrals are not permitted", referral))
+	}
+}
@@ -0,0 +1,156 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestLDAPUserProviderDiagnose(t *testing.T) {
+	config := schema.AuthenticationBackendLDAP{
+		Address:  testLDAPAddress,
+		User:     "cn=admin,dc=example,dc=com",
+		Password: "password",
+		Attributes: schema.AuthenticationBackendLDAPAttributes{
+			Username:    "uid",
+			Mail:        "mail",
+			DisplayName: "displayName",
+			MemberOf:    "memberOf",
+			GroupName:   "cn",
+		},
+		UsersFilter:       "(&({username_attribute}={input})(objectClass=person))",
+		GroupsFilter:      "(member={dn})",
+		AdditionalUsersDN: "ou=users",
+		BaseDN:            "dc=example,dc=com",
+	}
+
+	rootDSE := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			{
+				Attributes: []*ldap.EntryAttribute{
+					{Name: ldapSupportedExtensionAttribute, Values: []string{ldapOIDExtensionTLS}},
+				},
+			},
+		},
+	}
+
+	user := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			{
+				DN: "uid=john,ou=users,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "uid", Values: []string{"john"}},
+					{Name: "displayName", Values: []string{"John Doe"}},
+					{Name: "mail", Values: []string{"john@example.com"}},
+				},
+			},
+		},
+	}
+
+	groups := &ldap.SearchResult{
+		Entries: []*ldap.Entry{
+			{
+				DN: "cn=admins,dc=example,dc=com",
+				Attributes: []*ldap.EntryAttribute{
+					{Name: "cn", Values: []string{"admins"}},
+				},
+			},
+		},
+	}
+
+	t.Run("ShouldDiagnose", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockFactory := NewMockLDAPClientFactory(ctrl)
+		mockClient := NewMockLDAPClient(ctrl)
+
+		provider := NewLDAPUserProviderWithFactory(config, false, nil, mockFactory)
+
+		gomock.InOrder(
+			mockFactory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(mockClient, nil),
+			mockClient.EXPECT().Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).Return(nil),
+			mockClient.EXPECT().TLSConnectionState().Return(tls.ConnectionState{}, false),
+			mockClient.EXPECT().Search(gomock.Any()).Return(rootDSE, nil),
+			mockClient.EXPECT().Search(NewExtendedSearchRequestMatcher("(&(uid=john)(objectClass=person))", "ou=users,dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, false, []string{"uid", "mail", "displayName", "memberOf"})).Return(user, nil),
+			mockClient.EXPECT().Search(NewExtendedSearchRequestMatcher("(member=uid=john,ou=users,dc=example,dc=com)", "dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, false, []string{"cn"})).Return(groups, nil),
+			mockClient.EXPECT().Search(gomock.Any()).Return(groups, nil),
+			mockClient.EXPECT().Close(),
+		)
+
+		diagnostics, err := provider.Diagnose("john")
+
+		require.NoError(t, err)
+
+		assert.Equal(t, "ldap://127.0.0.1:389", diagnostics.Address)
+		assert.Nil(t, diagnostics.TLS)
+		assert.True(t, diagnostics.Features.Extensions.TLS)
+
+		require.NotNil(t, diagnostics.Users)
+		assert.Equal(t, "(&(uid=john)(objectClass=person))", diagnostics.Users.Filter)
+		assert.Equal(t, user.Entries, diagnostics.Users.Entries)
+
+		require.NotNil(t, diagnostics.Groups)
+		assert.Equal(t, "(member=uid=john,ou=users,dc=example,dc=com)", diagnostics.Groups.Filter)
+		assert.Equal(t, groups.Entries, diagnostics.Groups.Entries)
+
+		assert.Equal(t, &UserDetails{Username: "john", DisplayName: "John Doe", Emails: []string{"john@example.com"}, Groups: []string{"admins"}}, diagnostics.Details)
+
+		assert.Equal(t, []string{
+			"The connection is not secured with TLS as the address does not use the 'ldaps' scheme and StartTLS is not enabled",
+			"The LDAP server supports TLS but the connection is not secured with TLS",
+			"The LDAP server does not advertise the Password Modify Extended Operation so it may not hash passwords which are reset",
+		}, diagnostics.Warnings)
+	})
+
+	t.Run("ShouldReturnUserNotFound", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockFactory := NewMockLDAPClientFactory(ctrl)
+		mockClient := NewMockLDAPClient(ctrl)
+
+		provider := NewLDAPUserProviderWithFactory(config, true, nil, mockFactory)
+
+		gomock.InOrder(
+			mockFactory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(mockClient, nil),
+			mockClient.EXPECT().Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).Return(nil),
+			mockClient.EXPECT().TLSConnectionState().Return(tls.ConnectionState{}, false),
+			mockClient.EXPECT().Search(gomock.Any()).Return(rootDSE, nil),
+			mockClient.EXPECT().Search(gomock.Any()).Return(&ldap.SearchResult{}, nil),
+			mockClient.EXPECT().Close(),
+		)
+
+		diagnostics, err := provider.Diagnose("john")
+
+		assert.EqualError(t, err, "error occurred performing the users search: user not found")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+
+		require.NotNil(t, diagnostics.Users)
+		assert.Len(t, diagnostics.Users.Entries, 0)
+		assert.Nil(t, diagnostics.Groups)
+		assert.Nil(t, diagnostics.Details)
+	})
+
+	t.Run("ShouldWarnUnknownAuthority", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+
+		mockFactory := NewMockLDAPClientFactory(ctrl)
+
+		provider := NewLDAPUserProviderWithFactory(config, true, nil, mockFactory)
+
+		mockFactory.EXPECT().DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).Return(nil, &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}})
+
+		diagnostics, err := provider.Diagnose("john")
+
+		assert.True(t, errors.As(err, &x509.UnknownAuthorityError{}))
+		assert.Contains(t, diagnostics.Warnings, "The certificate of the LDAP server is signed by an unknown authority, the certificate of the authority should be added to the certificates directory")
+		assert.Nil(t, diagnostics.Users)
+	})
+}
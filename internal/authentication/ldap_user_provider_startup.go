@@ -1,6 +1,7 @@
 package authentication
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -10,6 +11,18 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
+// HealthCheck implements the health check provider interface. It always opens a new connection so the idle clients in
+// the pool don't hide a server which is unavailable.
+func (p *LDAPUserProvider) HealthCheck(_ context.Context) (err error) {
+	var client LDAPClient
+
+	if client, err = p.dial(); err != nil {
+		return err
+	}
+
+	return client.Close()
+}
+
 // StartupCheck implements the startup check provider interface.
 func (p *LDAPUserProvider) StartupCheck() (err error) {
 	var client LDAPClient
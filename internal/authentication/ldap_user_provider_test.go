@@ -12,6 +12,7 @@ import (
 	"go.uber.org/mock/gomock"
 	"golang.org/x/text/encoding/unicode"
 
+	"github.com/authelia/authelia/v4/internal/breaker"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -628,6 +629,56 @@ func TestShouldReturnCheckServerConnectError(t *testing.T) {
 	assert.False(t, provider.features.Extensions.PwdModifyExOp)
 }
 
+func TestShouldNotConnectWhenCircuitBreakerIsOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPClientFactory(ctrl)
+
+	provider := NewLDAPUserProviderWithFactory(
+		schema.AuthenticationBackendLDAP{
+			Address:     testLDAPAddress,
+			User:        "cn=admin,dc=example,dc=com",
+			UsersFilter: "(|({username_attribute}={input})({mail_attribute}={input}))",
+			Attributes: schema.AuthenticationBackendLDAPAttributes{
+				Username:    "uid",
+				Mail:        "mail",
+				DisplayName: "displayName",
+				MemberOf:    "memberOf",
+			},
+			Password:          "password",
+			AdditionalUsersDN: "ou=users",
+			BaseDN:            "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	provider.SetCircuitBreaker(breaker.New(&schema.CircuitBreaker{
+		Enabled:          true,
+		Dependencies:     []string{schema.CircuitBreakerDependencyLDAP},
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+		ProbeTimeout:     time.Second,
+	}, schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencyLDAP, clock.New(), nil))
+
+	mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(nil, errors.New("could not connect")).
+		Times(2)
+
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetDetails("john")
+
+		assert.EqualError(t, err, "dial failed with error: could not connect")
+	}
+
+	_, err := provider.GetDetails("john")
+
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.EqualError(t, err, "circuit breaker is open for the dependency 'ldap'")
+}
+
 func TestShouldReturnCheckServerSearchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,149 @@
+package authentication
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jcmturner/gokrb5/v8/credentials"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/service"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// SPNEGOProvider validates the SPNEGO tokens sent by the browsers of the users of domain-joined machines.
+type SPNEGOProvider interface {
+	Authenticate(value []byte) (username string, err error)
+}
+
+// NewKeytabSPNEGOProvider creates a new KeytabSPNEGOProvider given the SPNEGO configuration. It returns nil if the
+// SPNEGO authentication is not enabled.
+func NewKeytabSPNEGOProvider(config *schema.AuthenticationBackendSPNEGO) (provider *KeytabSPNEGOProvider, err error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	var kt *keytab.Keytab
+
+	if kt, err = keytab.Load(config.Keytab); err != nil {
+		return nil, fmt.Errorf("error loading the spnego keytab '%s': %w", config.Keytab, err)
+	}
+
+	options := []func(*service.Settings){
+		service.MaxClockSkew(config.MaxClockSkew),
+		service.DecodePAC(false),
+	}
+
+	if config.ServicePrincipal != "" {
+		options = append(options, service.KeytabPrincipal(config.ServicePrincipal))
+	}
+
+	return &KeytabSPNEGOProvider{
+		config:   config,
+		settings: service.NewSettings(kt, options...),
+	}, nil
+}
+
+// KeytabSPNEGOProvider is a SPNEGOProvider which validates the Kerberos tickets with the keys of the service principal
+// in a keytab.
+type KeytabSPNEGOProvider struct {
+	config   *schema.AuthenticationBackendSPNEGO
+	settings *service.Settings
+}
+
+// Authenticate validates the SPNEGO token in the value of the Authorization header and returns the username of the
+// user the Kerberos ticket was issued to.
+func (p *KeytabSPNEGOProvider) Authenticate(value []byte) (username string, err error) {
+	var token *spnego.KRB5Token
+
+	if token, err = spnegoDecodeAuthorization(value); err != nil {
+		return "", err
+	}
+
+	var (
+		ok    bool
+		creds *credentials.Credentials
+	)
+
+	if ok, creds, err = service.VerifyAPREQ(&token.APReq, p.settings); err != nil {
+		return "", fmt.Errorf("error validating the kerberos ticket: %w", err)
+	}
+
+	if !ok {
+		return "", errors.New("error validating the kerberos ticket: the ticket is not valid")
+	}
+
+	return spnegoPrincipalToUsername(p.config, creds.UserName(), creds.Domain())
+}
+
+// spnegoDecodeAuthorization decodes the Kerberos token from the value of an Authorization header with the Negotiate
+// scheme. The token is either a SPNEGO token or a raw Kerberos token, as some clients don't wrap it.
+func spnegoDecodeAuthorization(value []byte) (token *spnego.KRB5Token, err error) {
+	scheme, encoded, found := bytes.Cut(bytes.TrimSpace(value), []byte(" "))
+
+	if !found || !strings.EqualFold(string(scheme), spnegoAuthScheme) {
+		return nil, ErrSPNEGONoToken
+	}
+
+	raw := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+
+	var n int
+
+	if n, err = base64.StdEncoding.Decode(raw, bytes.TrimSpace(encoded)); err != nil {
+		return nil, fmt.Errorf("error decoding the spnego token: %w", err)
+	}
+
+	raw = raw[:n]
+
+	var negotiation spnego.SPNEGOToken
+
+	if err = negotiation.Unmarshal(raw); err == nil {
+		if !negotiation.Init {
+			return nil, errors.New("error decoding the spnego token: the token is not an initial negotiation token")
+		}
+
+		raw = negotiation.NegTokenInit.MechTokenBytes
+	}
+
+	token = &spnego.KRB5Token{}
+
+	if err = token.Unmarshal(raw); err != nil {
+		return nil, fmt.Errorf("error decoding the spnego token: the token is not a kerberos token: %w", err)
+	}
+
+	if !token.IsAPReq() {
+		return nil, errors.New("error decoding the spnego token: the kerberos token is not an AP-REQ")
+	}
+
+	return token, nil
+}
+
+// spnegoPrincipalToUsername maps the name and realm of a principal to the username of the user with the configured
+// username format.
+func spnegoPrincipalToUsername(config *schema.AuthenticationBackendSPNEGO, name, realm string) (username string, err error) {
+	if name == "" || strings.Contains(name, "/") {
+		return "", fmt.Errorf("the principal '%s' is not a user principal", name)
+	}
+
+	if len(config.Realms) != 0 {
+		permitted := false
+
+		for _, r := range config.Realms {
+			if strings.EqualFold(r, realm) {
+				permitted = true
+
+				break
+			}
+		}
+
+		if !permitted {
+			return "", fmt.Errorf("the realm '%s' of the principal '%s' is not permitted", realm, name)
+		}
+	}
+
+	return strings.NewReplacer("{username}", name, "{realm}", realm).Replace(config.UsernameFormat), nil
+}
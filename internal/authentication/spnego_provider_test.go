@@ -0,0 +1,74 @@
+package authentication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewKeytabSPNEGOProvider(t *testing.T) {
+	provider, err := NewKeytabSPNEGOProvider(&schema.AuthenticationBackendSPNEGO{})
+
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewKeytabSPNEGOProvider(&schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/not/a/keytab"})
+
+	assert.Nil(t, provider)
+	assert.ErrorContains(t, err, "error loading the spnego keytab '/not/a/keytab': ")
+}
+
+func TestSPNEGODecodeAuthorization(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		err   string
+	}{
+		{"ShouldErrorWithoutValue", "", "the request doesn't have a spnego token"},
+		{"ShouldErrorWithOtherScheme", "Basic am9objpwYXNzd29yZA==", "the request doesn't have a spnego token"},
+		{"ShouldErrorWithInvalidBase64", "Negotiate !!!", "error decoding the spnego token: illegal base64 data at input byte 0"},
+		{"ShouldErrorWithNTLMToken", "Negotiate TlRMTVNTUAABAAAAB4IIogAAAAAAAAAAAAAAAAAAAAAKAGFKAAAADw==", "error decoding the spnego token: the token is not a kerberos token: "},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := spnegoDecodeAuthorization([]byte(tc.value))
+
+			assert.Nil(t, token)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestSPNEGOPrincipalToUsername(t *testing.T) {
+	testCases := []struct {
+		name     string
+		config   schema.AuthenticationBackendSPNEGO
+		have     string
+		realm    string
+		expected string
+		err      string
+	}{
+		{"ShouldMapUsername", schema.AuthenticationBackendSPNEGO{UsernameFormat: "{username}"}, "john", "EXAMPLE.COM", "john", ""},
+		{"ShouldMapUsernameAndRealm", schema.AuthenticationBackendSPNEGO{UsernameFormat: "{username}@{realm}"}, "john", "EXAMPLE.COM", "john@EXAMPLE.COM", ""},
+		{"ShouldPermitRealm", schema.AuthenticationBackendSPNEGO{UsernameFormat: "{username}", Realms: []string{"CORP.EXAMPLE.COM", "example.com"}}, "john", "EXAMPLE.COM", "john", ""},
+		{"ShouldNotPermitRealm", schema.AuthenticationBackendSPNEGO{UsernameFormat: "{username}", Realms: []string{"CORP.EXAMPLE.COM"}}, "john", "EXAMPLE.COM", "", "the realm 'EXAMPLE.COM' of the principal 'john' is not permitted"},
+		{"ShouldNotPermitServicePrincipal", schema.AuthenticationBackendSPNEGO{UsernameFormat: "{username}"}, "HTTP/auth.example.com", "EXAMPLE.COM", "", "the principal 'HTTP/auth.example.com' is not a user principal"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			username, err := spnegoPrincipalToUsername(&tc.config, tc.have, tc.realm)
+
+			assert.Equal(t, tc.expected, username)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
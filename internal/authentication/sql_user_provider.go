@@ -0,0 +1,126 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-crypt/crypt/algorithm"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// SQLUserProvider is a provider reading the user details from the directory users and groups in the storage backend.
+type SQLUserProvider struct {
+	config  *schema.AuthenticationBackendSQL
+	storage SQLUserProviderStorage
+	hash    algorithm.Hash
+}
+
+// NewSQLUserProvider creates a new instance of SQLUserProvider.
+func NewSQLUserProvider(config *schema.AuthenticationBackendSQL, storage SQLUserProviderStorage) (provider *SQLUserProvider) {
+	return &SQLUserProvider{
+		config:  config,
+		storage: storage,
+	}
+}
+
+// CheckUserPassword checks if provided password matches for the given user.
+func (p *SQLUserProvider) CheckUserPassword(username string, password string) (match bool, err error) {
+	ctx := context.Background()
+
+	if _, err = p.getUser(ctx, username); err != nil {
+		return false, err
+	}
+
+	var digest string
+
+	if digest, err = p.storage.LoadDirectoryUserPassword(ctx, username); err != nil {
+		return false, fmt.Errorf("failed to load the password of user '%s': %w", username, err)
+	}
+
+	if digest == "" {
+		return false, nil
+	}
+
+	return CheckPassword(password, digest)
+}
+
+// GetDetails retrieve the details of the given user and the groups they belong to.
+func (p *SQLUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+	ctx := context.Background()
+
+	var user *model.DirectoryUser
+
+	if user, err = p.getUser(ctx, username); err != nil {
+		return nil, err
+	}
+
+	var groups []model.DirectoryGroup
+
+	if groups, err = p.storage.LoadDirectoryUserGroups(ctx, user.Username); err != nil {
+		return nil, fmt.Errorf("failed to load the groups of user '%s': %w", username, err)
+	}
+
+	details = &UserDetails{
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Emails:      user.Emails,
+		Groups:      make([]string, len(groups)),
+	}
+
+	if details.DisplayName == "" {
+		details.DisplayName = user.Username
+	}
+
+	for i, group := range groups {
+		details.Groups[i] = group.DisplayName
+	}
+
+	return details, nil
+}
+
+// UpdatePassword update the password of the given user.
+func (p *SQLUserProvider) UpdatePassword(username string, newPassword string) (err error) {
+	ctx := context.Background()
+
+	if _, err = p.getUser(ctx, username); err != nil {
+		return err
+	}
+
+	var digest algorithm.Digest
+
+	if digest, err = p.hash.Hash(newPassword); err != nil {
+		return err
+	}
+
+	if err = p.storage.UpdateDirectoryUserPassword(ctx, username, digest.Encode()); err != nil {
+		return fmt.Errorf("failed to update the password of user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// StartupCheck implements the startup check provider interface.
+func (p *SQLUserProvider) StartupCheck() (err error) {
+	if p.hash, err = NewFileCryptoHashFromConfig(p.config.Password); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// getUser returns the active directory user with the given username.
+func (p *SQLUserProvider) getUser(ctx context.Context, username string) (user *model.DirectoryUser, err error) {
+	var users []model.DirectoryUser
+
+	if users, err = p.storage.LoadDirectoryUsers(ctx, model.DirectoryUserFilter{Username: username}, 1, 0); err != nil {
+		return nil, fmt.Errorf("failed to load user '%s': %w", username, err)
+	}
+
+	if len(users) == 0 || users[0].Username != username || !users[0].Active {
+		return nil, ErrUserNotFound
+	}
+
+	return &users[0], nil
+}
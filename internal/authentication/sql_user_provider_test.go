@@ -0,0 +1,185 @@
+package authentication
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func newTestSQLUserProvider(t *testing.T) (provider *SQLUserProvider, mock *MockSQLUserProviderStorage) {
+	ctrl := gomock.NewController(t)
+
+	mock = NewMockSQLUserProviderStorage(ctrl)
+
+	provider = NewSQLUserProvider(&schema.AuthenticationBackendSQL{Password: schema.DefaultCIPasswordConfig}, mock)
+
+	require.NoError(t, provider.StartupCheck())
+
+	return provider, mock
+}
+
+func TestSQLUserProviderCheckUserPassword(t *testing.T) {
+	provider, mock := newTestSQLUserProvider(t)
+
+	digest, err := provider.hash.Hash("password")
+	require.NoError(t, err)
+
+	filter := model.DirectoryUserFilter{Username: "john"}
+
+	testCases := []struct {
+		name     string
+		setup    func()
+		password string
+		expected bool
+		err      string
+	}{
+		{
+			"ShouldMatch",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return([]model.DirectoryUser{{Username: "john", Active: true}}, nil)
+				mock.EXPECT().LoadDirectoryUserPassword(gomock.Any(), "john").Return(digest.Encode(), nil)
+			},
+			"password",
+			true,
+			"",
+		},
+		{
+			"ShouldNotMatch",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return([]model.DirectoryUser{{Username: "john", Active: true}}, nil)
+				mock.EXPECT().LoadDirectoryUserPassword(gomock.Any(), "john").Return(digest.Encode(), nil)
+			},
+			"bad",
+			false,
+			"",
+		},
+		{
+			"ShouldNotMatchWithoutPassword",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return([]model.DirectoryUser{{Username: "john", Active: true}}, nil)
+				mock.EXPECT().LoadDirectoryUserPassword(gomock.Any(), "john").Return("", nil)
+			},
+			"",
+			false,
+			"",
+		},
+		{
+			"ShouldNotFindInactiveUser",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return([]model.DirectoryUser{{Username: "john", Active: false}}, nil)
+			},
+			"password",
+			false,
+			"user not found",
+		},
+		{
+			"ShouldNotFindMissingUser",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return(nil, nil)
+			},
+			"password",
+			false,
+			"user not found",
+		},
+		{
+			"ShouldReturnStorageError",
+			func() {
+				mock.EXPECT().LoadDirectoryUsers(gomock.Any(), filter, 1, 0).Return(nil, errors.New("bad connection"))
+			},
+			"password",
+			false,
+			"failed to load user 'john': bad connection",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.setup()
+
+			valid, err := provider.CheckUserPassword("john", tc.password)
+
+			assert.Equal(t, tc.expected, valid)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSQLUserProviderGetDetails(t *testing.T) {
+	provider, mock := newTestSQLUserProvider(t)
+
+	mock.EXPECT().LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "john"}, 1, 0).
+		Return([]model.DirectoryUser{{Username: "john", DisplayName: "John Smith", Emails: model.StringSlicePipeDelimited{"john@example.com"}, Active: true}}, nil)
+	mock.EXPECT().LoadDirectoryUserGroups(gomock.Any(), "john").
+		Return([]model.DirectoryGroup{{DisplayName: "admins"}, {DisplayName: "dev"}}, nil)
+
+	details, err := provider.GetDetails("john")
+
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{Username: "john", DisplayName: "John Smith", Emails: []string{"john@example.com"}, Groups: []string{"admins", "dev"}}, details)
+
+	mock.EXPECT().LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "jane"}, 1, 0).
+		Return([]model.DirectoryUser{{Username: "jane", Active: true}}, nil)
+	mock.EXPECT().LoadDirectoryUserGroups(gomock.Any(), "jane").
+		Return(nil, nil)
+
+	details, err = provider.GetDetails("jane")
+
+	require.NoError(t, err)
+	assert.Equal(t, &UserDetails{Username: "jane", DisplayName: "jane", Groups: []string{}}, details)
+
+	mock.EXPECT().LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "fred"}, 1, 0).
+		Return(nil, nil)
+
+	details, err = provider.GetDetails("fred")
+
+	assert.Nil(t, details)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestSQLUserProviderUpdatePassword(t *testing.T) {
+	provider, mock := newTestSQLUserProvider(t)
+
+	var encoded string
+
+	gomock.InOrder(
+		mock.EXPECT().LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "john"}, 1, 0).
+			Return([]model.DirectoryUser{{Username: "john", Active: true}}, nil),
+		mock.EXPECT().UpdateDirectoryUserPassword(gomock.Any(), "john", gomock.Any()).
+			DoAndReturn(func(_ any, _, password string) error {
+				encoded = password
+
+				return nil
+			}),
+	)
+
+	require.NoError(t, provider.UpdatePassword("john", "apple123"))
+
+	valid, err := CheckPassword("apple123", encoded)
+
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	ctrl := gomock.NewController(t)
+
+	hash := NewMockHash(ctrl)
+	provider.hash = hash
+
+	gomock.InOrder(
+		mock.EXPECT().LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "john"}, 1, 0).
+			Return([]model.DirectoryUser{{Username: "john", Active: true}}, nil),
+		hash.EXPECT().Hash("apple123").Return(nil, errors.New("failed to mock hash")),
+	)
+
+	assert.EqualError(t, provider.UpdatePassword("john", "apple123"), "failed to mock hash")
+}
@@ -1,11 +1,14 @@
 package authentication
 
 import (
+	"context"
 	"crypto/tls"
 	"net/mail"
 	"time"
 
 	"github.com/go-ldap/ldap/v3"
+
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 // LDAPClientFactory an interface of factory of LDAP clients.
@@ -51,6 +54,15 @@ type LDAPClient interface {
 	WhoAmI(controls []ldap.Control) (result *ldap.WhoAmIResult, err error)
 }
 
+// SQLUserProviderStorage is a cut down version of the storage.Provider interface with just the methods the
+// SQLUserProvider uses.
+type SQLUserProviderStorage interface {
+	LoadDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter, limit, offset int) (users []model.DirectoryUser, err error)
+	LoadDirectoryUserPassword(ctx context.Context, username string) (password string, err error)
+	UpdateDirectoryUserPassword(ctx context.Context, username, password string) (err error)
+	LoadDirectoryUserGroups(ctx context.Context, username string) (groups []model.DirectoryGroup, err error)
+}
+
 // UserDetails represent the details retrieved for a given user.
 type UserDetails struct {
 	Username    string
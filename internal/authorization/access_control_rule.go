@@ -21,12 +21,18 @@ func NewAccessControlRules(config schema.AccessControl) (rules []*AccessControlR
 // NewAccessControlRule parses a schema ACL and generates an internal ACL.
 func NewAccessControlRule(pos int, rule schema.AccessControlRule, networksMap map[string][]*net.IPNet, networksCacheMap map[string]*net.IPNet) *AccessControlRule {
 	r := &AccessControlRule{
-		Position: pos,
-		Query:    NewAccessControlQuery(rule.Query),
-		Methods:  schemaMethodsToACL(rule.Methods),
-		Networks: schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
-		Subjects: schemaSubjectsToACL(rule.Subjects),
-		Policy:   NewLevel(rule.Policy),
+		Position:  pos,
+		Query:     NewAccessControlQuery(rule.Query),
+		Methods:   schemaMethodsToACL(rule.Methods),
+		Networks:  schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
+		Subjects:  schemaSubjectsToACL(rule.Subjects),
+		Policy:    NewLevel(rule.Policy),
+		Challenge: NewChallenge(rule.Challenge),
+		Device:    NewDevice(rule.Device),
+
+		AuthnStrategies: rule.AuthnStrategies,
+
+		RequireUserVerification: rule.WebAuthnUserVerification == userVerificationRequired,
 	}
 
 	if len(r.Subjects) != 0 {
@@ -52,6 +58,14 @@ type AccessControlRule struct {
 	Networks  []*net.IPNet
 	Subjects  []AccessControlSubjects
 	Policy    Level
+	Challenge Challenge
+	Device    Device
+
+	// AuthnStrategies are the authentication strategies used instead of the strategies of the authz endpoint.
+	AuthnStrategies []schema.ServerEndpointsAuthzAuthnStrategy
+
+	// RequireUserVerification requires the WebAuthn assertion of the session to have verified the user.
+	RequireUserVerification bool
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
@@ -76,6 +90,10 @@ func (acr *AccessControlRule) IsMatch(subject Subject, object Object) (match boo
 		return false
 	}
 
+	if !acr.MatchesDevice(subject) {
+		return false
+	}
+
 	if !acr.MatchesSubjects(subject) {
 		return false
 	}
@@ -161,6 +179,18 @@ func (acr *AccessControlRule) MatchesNetworks(subject Subject) (match bool) {
 	return false
 }
 
+// MatchesDevice returns true if the rule matches the device.
+func (acr *AccessControlRule) MatchesDevice(subject Subject) (match bool) {
+	switch acr.Device {
+	case DeviceManaged:
+		return subject.ManagedDevice
+	case DeviceUnmanaged:
+		return !subject.ManagedDevice
+	default:
+		return true
+	}
+}
+
 // MatchesSubjects returns true if the rule matches the subjects.
 func (acr *AccessControlRule) MatchesSubjects(subject Subject) (match bool) {
 	if subject.IsAnonymous() {
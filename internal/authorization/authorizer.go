@@ -1,6 +1,9 @@
 package authorization
 
 import (
+	"net"
+	"sync"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -11,52 +14,153 @@ import (
 type Authorizer struct {
 	defaultPolicy Level
 	rules         []*AccessControlRule
+	static        []*AccessControlRule
+	dynamic       []schema.AccessControlRule
 	mfa           bool
+	mfaStatic     bool
 	log           *logrus.Logger
+
+	networksMap      map[string][]*net.IPNet
+	networksCacheMap map[string]*net.IPNet
+
+	mu sync.RWMutex
 }
 
 // NewAuthorizer create an instance of authorizer with a given access control config.
 func NewAuthorizer(config *schema.Configuration) (authorizer *Authorizer) {
 	authorizer = &Authorizer{
-		defaultPolicy: NewLevel(config.AccessControl.DefaultPolicy),
-		rules:         NewAccessControlRules(config.AccessControl),
-		log:           logging.Logger(),
+		log: logging.Logger(),
 	}
 
-	if authorizer.defaultPolicy == TwoFactor {
-		authorizer.mfa = true
+	authorizer.SetConfiguration(config)
 
-		return authorizer
+	return authorizer
+}
+
+func isAuthorizerMFA(config *schema.Configuration, defaultPolicy Level, rules []*AccessControlRule) (mfa bool) {
+	if defaultPolicy == TwoFactor {
+		return true
 	}
 
-	for _, rule := range authorizer.rules {
+	for _, rule := range rules {
 		if rule.Policy == TwoFactor {
-			authorizer.mfa = true
-
-			return authorizer
+			return true
 		}
 	}
 
-	authorizer.mfa = isOpenIDConnectMFA(config)
-
-	return authorizer
+	return isOpenIDConnectMFA(config)
 }
 
 // IsSecondFactorEnabled return true if at least one policy is set to second factor.
 func (p *Authorizer) IsSecondFactorEnabled() bool {
+	p.mu.RLock()
+
+	defer p.mu.RUnlock()
+
 	return p.mfa
 }
 
+// SetConfiguration replaces the default policy, networks, and rules with those from the configuration. The dynamic
+// rules are retained and are evaluated after the new rules from the configuration.
+func (p *Authorizer) SetConfiguration(config *schema.Configuration) {
+	defaultPolicy := NewLevel(config.AccessControl.DefaultPolicy)
+	networksMap, networksCacheMap := parseSchemaNetworks(config.AccessControl.Networks)
+	static := NewAccessControlRules(config.AccessControl)
+
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	p.defaultPolicy, p.static = defaultPolicy, static
+	p.networksMap, p.networksCacheMap = networksMap, networksCacheMap
+	p.mfaStatic = isAuthorizerMFA(config, defaultPolicy, static)
+
+	p.compose()
+}
+
+// SetDynamicRules replaces the dynamic rules such as those synthesized from Kubernetes resources. The dynamic rules
+// are always evaluated after the rules from the configuration.
+func (p *Authorizer) SetDynamicRules(rules []schema.AccessControlRule) {
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	p.dynamic = rules
+
+	p.compose()
+}
+
+// compose combines the static and dynamic rules, the caller must hold the write lock.
+func (p *Authorizer) compose() {
+	mfa := p.mfaStatic
+
+	combined := make([]*AccessControlRule, 0, len(p.static)+len(p.dynamic))
+
+	combined = append(combined, p.static...)
+
+	for i, rule := range p.dynamic {
+		dynamic := NewAccessControlRule(len(p.static)+i+1, rule, p.networksMap, p.networksCacheMap)
+
+		if dynamic.Policy == TwoFactor {
+			mfa = true
+		}
+
+		combined = append(combined, dynamic)
+	}
+
+	p.rules, p.mfa = combined, mfa
+}
+
+func (p *Authorizer) getRules() (rules []*AccessControlRule, defaultPolicy Level) {
+	p.mu.RLock()
+
+	defer p.mu.RUnlock()
+
+	return p.rules, p.defaultPolicy
+}
+
 // GetRequiredLevel retrieve the required level of authorization to access the object.
 func (p *Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubjects bool, level Level) {
+	hasSubjects, level, _ = p.GetRequiredLevelAndChallenge(subject, object)
+
+	return hasSubjects, level
+}
+
+// GetRequiredLevelAndChallenge retrieve the required level of authorization to access the object and the challenge
+// behaviour which should be used when the subject is not authorized.
+func (p *Authorizer) GetRequiredLevelAndChallenge(subject Subject, object Object) (hasSubjects bool, level Level, challenge Challenge) {
+	hasSubjects, level, challenge, _ = p.GetRequiredLevelChallengeAndPosition(subject, object)
+
+	return hasSubjects, level, challenge
+}
+
+// GetRequiredLevelChallengeAndPosition is like GetRequiredLevelAndChallenge but also returns the position of the
+// matched rule, the position is 0 when no rule matched and the default policy applies.
+func (p *Authorizer) GetRequiredLevelChallengeAndPosition(subject Subject, object Object) (hasSubjects bool, level Level, challenge Challenge, position int) {
+	requirements := p.GetRequirements(subject, object)
+
+	return requirements.HasSubjects, requirements.Level, requirements.Challenge, requirements.Position
+}
+
+// GetRequirements retrieve all of the requirements of the rule which matches the subject and object, or the default
+// policy when no rule matches.
+func (p *Authorizer) GetRequirements(subject Subject, object Object) (requirements Requirements) {
 	p.log.Debugf("Check authorization of subject %s and object %s (method %s).",
 		subject.String(), object.String(), object.Method)
 
-	for _, rule := range p.rules {
+	rules, defaultPolicy := p.getRules()
+
+	for _, rule := range rules {
 		if rule.IsMatch(subject, object) {
 			p.log.Tracef(traceFmtACLHitMiss, "HIT", rule.Position, subject, object, object.Method, rule.Policy)
 
-			return rule.HasSubjects, rule.Policy
+			return Requirements{
+				HasSubjects:      rule.HasSubjects,
+				Position:         rule.Position,
+				Level:            rule.Policy,
+				Challenge:        rule.Challenge,
+				UserVerification: rule.RequireUserVerification,
+			}
 		}
 
 		p.log.Tracef(traceFmtACLHitMiss, "MISS", rule.Position, subject, object, object.Method, rule.Policy)
@@ -64,16 +168,36 @@ func (p *Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubjec
 
 	p.log.Debugf("No matching rule for subject %s and url %s (method %s) applying default policy", subject, object, object.Method)
 
-	return false, p.defaultPolicy
+	return Requirements{Level: defaultPolicy, Challenge: ChallengeAuto}
+}
+
+// GetAuthnStrategies returns the authentication strategies of the first rule which matches the object, or nil if the
+// rule doesn't configure any authentication strategies or no rule matches. As the subject isn't known until they're
+// authenticated only the criteria of the object and the network criteria are matched, the subject and device criteria
+// are ignored. The first matching rule may therefore not be the rule which is later applied to the subject, which is why
+// the rules with subject or device criteria can't configure authentication strategies.
+func (p *Authorizer) GetAuthnStrategies(subject Subject, object Object) (strategies []schema.ServerEndpointsAuthzAuthnStrategy) {
+	rules, _ := p.getRules()
+
+	for _, rule := range rules {
+		if rule.MatchesDomains(subject, object) && rule.MatchesResources(subject, object) && rule.MatchesQuery(object) &&
+			rule.MatchesMethods(object) && rule.MatchesNetworks(subject) {
+			return rule.AuthnStrategies
+		}
+	}
+
+	return nil
 }
 
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
 func (p *Authorizer) GetRuleMatchResults(subject Subject, object Object) (results []RuleMatchResult) {
 	skipped := false
 
-	results = make([]RuleMatchResult, len(p.rules))
+	rules, _ := p.getRules()
+
+	results = make([]RuleMatchResult, len(rules))
 
-	for i, rule := range p.rules {
+	for i, rule := range rules {
 		results[i] = RuleMatchResult{
 			Rule:    rule,
 			Skipped: skipped,
@@ -83,6 +207,7 @@ func (p *Authorizer) GetRuleMatchResults(subject Subject, object Object) (result
 			MatchQuery:         rule.MatchesQuery(object),
 			MatchMethods:       rule.MatchesMethods(object),
 			MatchNetworks:      rule.MatchesNetworks(subject),
+			MatchDevice:        rule.MatchesDevice(subject),
 			MatchSubjects:      rule.MatchesSubjects(subject),
 			MatchSubjectsExact: rule.MatchesSubjectExact(subject),
 		}
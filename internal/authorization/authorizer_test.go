@@ -369,6 +369,156 @@ func (s *AuthorizerSuite) TestShouldCheckRulePrecedence() {
 	tester.CheckAuthorizations(s.T(), OAuth2UserClientAClient, "https://protected.example.com/", fasthttp.MethodGet, OneFactor)
 }
 
+func (s *AuthorizerSuite) TestShouldReturnMatchedRulePosition() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"public.example.com"},
+			Policy:  bypass,
+		}).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"*.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	testCases := []struct {
+		name     string
+		have     string
+		level    Level
+		position int
+	}{
+		{"ShouldMatchFirstRule", "https://public.example.com/", Bypass, 1},
+		{"ShouldMatchSecondRule", "https://app.example.com/", TwoFactor, 2},
+		{"ShouldMatchDefaultPolicy", "https://example.org/", Denied, 0},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			targetURL, _ := url.ParseRequestURI(tc.have)
+
+			_, level, _, position := tester.GetRequiredLevelChallengeAndPosition(John, NewObject(targetURL, fasthttp.MethodGet))
+
+			s.Equal(tc.level, level)
+			s.Equal(tc.position, position)
+		})
+	}
+}
+
+func (s *AuthorizerSuite) TestShouldReturnUserVerificationRequirement() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.AccessControlRule{
+			Domains:                  []string{"secure.example.com"},
+			Policy:                   twoFactor,
+			WebAuthnUserVerification: "required",
+		}).
+		WithRule(schema.AccessControlRule{
+			Domains:                  []string{"*.example.com"},
+			Policy:                   twoFactor,
+			WebAuthnUserVerification: "preferred",
+		}).
+		Build()
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected Requirements
+	}{
+		{"ShouldRequireUserVerification", "https://secure.example.com/", Requirements{Position: 1, Level: TwoFactor, UserVerification: true}},
+		{"ShouldNotRequireUserVerification", "https://app.example.com/", Requirements{Position: 2, Level: TwoFactor}},
+		{"ShouldNotRequireUserVerificationDefaultPolicy", "https://example.org/", Requirements{Level: Denied}},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			targetURL, _ := url.ParseRequestURI(tc.have)
+
+			s.Equal(tc.expected, tester.GetRequirements(John, NewObject(targetURL, fasthttp.MethodGet)))
+		})
+	}
+}
+
+func (s *AuthorizerSuite) TestShouldCheckDynamicRules() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"public.example.com"},
+			Policy:  bypass,
+		}).
+		Build()
+
+	s.False(tester.IsSecondFactorEnabled())
+
+	tester.SetDynamicRules([]schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com", "app.example.com"},
+			Policy:  twoFactor,
+		},
+	})
+
+	s.True(tester.IsSecondFactorEnabled())
+
+	tester.CheckAuthorizations(s.T(), John, "https://public.example.com/", fasthttp.MethodGet, Bypass)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", fasthttp.MethodGet, TwoFactor)
+
+	results := tester.GetRuleMatchResults(John, "https://app.example.com/", fasthttp.MethodGet)
+
+	s.Require().Len(results, 2)
+	s.Equal(2, results[1].Rule.Position)
+
+	tester.SetDynamicRules(nil)
+
+	s.False(tester.IsSecondFactorEnabled())
+
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", fasthttp.MethodGet, Denied)
+}
+
+func (s *AuthorizerSuite) TestShouldRetainDynamicRulesWhenConfigurationChanges() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"public.example.com"},
+			Policy:  bypass,
+		}).
+		Build()
+
+	tester.SetDynamicRules([]schema.AccessControlRule{
+		{
+			Domains: []string{"app.example.com"},
+			Policy:  oneFactor,
+		},
+	})
+
+	tester.SetConfiguration(&schema.Configuration{
+		AccessControl: schema.AccessControl{
+			DefaultPolicy: oneFactor,
+			Rules: []schema.AccessControlRule{
+				{
+					Domains: []string{"public.example.com"},
+					Policy:  twoFactor,
+				},
+				{
+					Domains: []string{"admin.example.com"},
+					Policy:  deny,
+				},
+			},
+		},
+	})
+
+	s.True(tester.IsSecondFactorEnabled())
+
+	tester.CheckAuthorizations(s.T(), John, "https://public.example.com/", fasthttp.MethodGet, TwoFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://admin.example.com/", fasthttp.MethodGet, Denied)
+	tester.CheckAuthorizations(s.T(), John, "https://app.example.com/", fasthttp.MethodGet, OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://other.example.com/", fasthttp.MethodGet, OneFactor)
+
+	results := tester.GetRuleMatchResults(John, "https://app.example.com/", fasthttp.MethodGet)
+
+	s.Require().Len(results, 3)
+	s.Equal(3, results[2].Rule.Position)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckDomainMatching() {
 	tester := NewAuthorizerBuilder().
 		WithRule(schema.AccessControlRule{
@@ -756,6 +906,52 @@ func (s *AuthorizerSuite) TestShouldCheckMethodMatching() {
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://protected.example.com/", fasthttp.MethodDelete, TwoFactor)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckDeviceMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  oneFactor,
+			Device:  deviceManaged,
+		}).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  twoFactor,
+			Device:  deviceAny,
+		}).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"kiosk.example.com"},
+			Policy:  deny,
+			Device:  deviceUnmanaged,
+		}).
+		WithRule(schema.AccessControlRule{
+			Domains: []string{"kiosk.example.com"},
+			Policy:  oneFactor,
+		}).
+		Build()
+
+	managed := John
+	managed.ManagedDevice = true
+
+	tester.CheckAuthorizations(s.T(), managed, "https://protected.example.com/", fasthttp.MethodGet, OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://protected.example.com/", fasthttp.MethodGet, TwoFactor)
+	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://protected.example.com/", fasthttp.MethodGet, TwoFactor)
+
+	tester.CheckAuthorizations(s.T(), managed, "https://kiosk.example.com/", fasthttp.MethodGet, OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://kiosk.example.com/", fasthttp.MethodGet, Denied)
+
+	results := tester.GetRuleMatchResults(John, "https://protected.example.com", fasthttp.MethodGet)
+
+	s.Require().Len(results, 4)
+
+	s.Assert().False(results[0].IsMatch())
+	s.Assert().True(results[0].MatchDomain)
+	s.Assert().False(results[0].MatchDevice)
+
+	s.Assert().True(results[1].IsMatch())
+	s.Assert().True(results[1].MatchDevice)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckResourceMatching() {
 	createSliceRegexRule := func(t *testing.T, rules []string) []regexp.Regexp {
 		result, err := stringSliceToRegexpSlice(rules)
@@ -1098,3 +1294,60 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 	authorizer = NewAuthorizer(config)
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
+
+func TestAuthorizerGetAuthnStrategies(t *testing.T) {
+	strategies := []schema.ServerEndpointsAuthzAuthnStrategy{
+		{Name: "HeaderAuthorization", Schemes: []string{schema.SchemeBasic}},
+	}
+
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControl{
+			DefaultPolicy: deny,
+			Rules: []schema.AccessControlRule{
+				{
+					Domains:   []string{"dav.example.com"},
+					Resources: []regexp.Regexp{*regexp.MustCompile("^/public.*$")},
+					Policy:    bypass,
+				},
+				{
+					Domains:   []string{"dav.example.com"},
+					Resources: []regexp.Regexp{*regexp.MustCompile("^/admin.*$")},
+					Policy:    twoFactor,
+					Subjects:  [][]string{{"group:admins"}},
+				},
+				{
+					Domains:         []string{"dav.example.com"},
+					Policy:          oneFactor,
+					AuthnStrategies: strategies,
+				},
+				{
+					Domains: []string{"*.example.com"},
+					Policy:  oneFactor,
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected []schema.ServerEndpointsAuthzAuthnStrategy
+	}{
+		{"ShouldMatchRule", "https://dav.example.com/calendars", strategies},
+		{"ShouldMatchPriorRuleWithoutStrategies", "https://dav.example.com/public/feed.xml", nil},
+		{"ShouldMatchPriorRuleWithSubjectsIgnoringSubjects", "https://dav.example.com/admin/calendars", nil},
+		{"ShouldMatchOtherRuleWithoutStrategies", "https://app.example.com/", nil},
+		{"ShouldNotMatchAnyRule", "https://example.org/", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targetURL, err := url.ParseRequestURI(tc.have)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, authorizer.GetAuthnStrategies(Subject{IP: net.ParseIP("127.0.0.1")}, NewObject(targetURL, fasthttp.MethodGet)))
+		})
+	}
+}
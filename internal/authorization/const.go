@@ -17,6 +17,35 @@ const (
 	Denied
 )
 
+// Challenge is the type representing how an unauthorized request is challenged.
+type Challenge int
+
+const (
+	// ChallengeAuto redirects browser clients and responds with a status code and challenge metadata to non-browser
+	// clients such as WebSocket and gRPC clients.
+	ChallengeAuto Challenge = iota
+
+	// ChallengeRedirect always redirects the client to the portal.
+	ChallengeRedirect
+
+	// ChallengeStatus always responds with a status code and challenge metadata instead of a redirect.
+	ChallengeStatus
+)
+
+// Device is the type representing the device requirement of a rule.
+type Device int
+
+const (
+	// DeviceAny matches requests from any device.
+	DeviceAny Device = iota
+
+	// DeviceManaged only matches requests which present a valid client certificate issued to a managed device.
+	DeviceManaged
+
+	// DeviceUnmanaged only matches requests which do not present a valid client certificate issued to a managed device.
+	DeviceUnmanaged
+)
+
 const (
 	prefixUser         = "user:"
 	prefixGroup        = "group:"
@@ -36,6 +65,22 @@ const (
 	deny      = "deny"
 )
 
+const (
+	challengeAuto     = "auto"
+	challengeRedirect = "redirect"
+	challengeStatus   = "status"
+)
+
+const (
+	userVerificationRequired = "required"
+)
+
+const (
+	deviceAny       = "any"
+	deviceManaged   = "managed"
+	deviceUnmanaged = "unmanaged"
+)
+
 const (
 	operatorPresent    = "present"
 	operatorAbsent     = "absent"
@@ -35,11 +35,14 @@ type Subject struct {
 	Groups   []string
 	ClientID string
 	IP       net.IP
+
+	// ManagedDevice is true when the request presented a valid client certificate issued to a managed device.
+	ManagedDevice bool
 }
 
 // String returns a string representation of the Subject.
 func (s Subject) String() string {
-	return fmt.Sprintf("username=%s groups=%s ip=%s", s.Username, strings.Join(s.Groups, ","), s.IP.String())
+	return fmt.Sprintf("username=%s groups=%s ip=%s managed_device=%t", s.Username, strings.Join(s.Groups, ","), s.IP.String(), s.ManagedDevice)
 }
 
 // IsAnonymous returns true if the Subject username and groups are empty.
@@ -76,6 +79,21 @@ func NewObject(targetURL *url.URL, method string) (object Object) {
 	}
 }
 
+// Requirements describes the requirements of the rule which matched a subject/object combo, or the default policy
+// when no rule matched.
+type Requirements struct {
+	HasSubjects bool
+
+	// Position is the position of the matched rule, it's 0 when no rule matched and the default policy applies.
+	Position int
+
+	Level     Level
+	Challenge Challenge
+
+	// UserVerification is true when the WebAuthn assertion of the session must have verified the user.
+	UserVerification bool
+}
+
 // RuleMatchResult describes how well a rule matched a subject/object combo.
 type RuleMatchResult struct {
 	Rule *AccessControlRule
@@ -87,16 +105,17 @@ type RuleMatchResult struct {
 	MatchQuery         bool
 	MatchMethods       bool
 	MatchNetworks      bool
+	MatchDevice        bool
 	MatchSubjects      bool
 	MatchSubjectsExact bool
 }
 
 // IsMatch returns true if all the criteria matched.
 func (r RuleMatchResult) IsMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchDevice && r.MatchSubjectsExact
 }
 
 // IsPotentialMatch returns true if the rule is potentially a match.
 func (r RuleMatchResult) IsPotentialMatch() (match bool) {
-	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchSubjects && !r.MatchSubjectsExact
+	return r.MatchDomain && r.MatchResources && r.MatchMethods && r.MatchNetworks && r.MatchDevice && r.MatchSubjects && !r.MatchSubjectsExact
 }
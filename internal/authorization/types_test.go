@@ -99,12 +99,12 @@ func TestRuleMatchResult_IsPotentialMatch(t *testing.T) {
 		},
 		{
 			"ShouldMatch",
-			RuleMatchResult{nil, true, true, true, true, true, true, true, false},
+			RuleMatchResult{nil, true, true, true, true, true, true, true, true, false},
 			true,
 		},
 		{
 			"ShouldMatchExact",
-			RuleMatchResult{nil, true, true, true, true, true, true, true, true},
+			RuleMatchResult{nil, true, true, true, true, true, true, true, true, true},
 			false,
 		},
 	}
@@ -41,6 +41,54 @@ func (l Level) String() string {
 	}
 }
 
+// NewChallenge converts a challenge string to an authorization.Challenge.
+func NewChallenge(challenge string) Challenge {
+	switch challenge {
+	case challengeRedirect:
+		return ChallengeRedirect
+	case challengeStatus:
+		return ChallengeStatus
+	default:
+		return ChallengeAuto
+	}
+}
+
+// String returns a challenge string representation of an authorization.Challenge.
+func (c Challenge) String() string {
+	switch c {
+	case ChallengeRedirect:
+		return challengeRedirect
+	case ChallengeStatus:
+		return challengeStatus
+	default:
+		return challengeAuto
+	}
+}
+
+// NewDevice converts a device string to an authorization.Device.
+func NewDevice(device string) Device {
+	switch device {
+	case deviceManaged:
+		return DeviceManaged
+	case deviceUnmanaged:
+		return DeviceUnmanaged
+	default:
+		return DeviceAny
+	}
+}
+
+// String returns a device string representation of an authorization.Device.
+func (d Device) String() string {
+	switch d {
+	case DeviceManaged:
+		return deviceManaged
+	case DeviceUnmanaged:
+		return deviceUnmanaged
+	default:
+		return deviceAny
+	}
+}
+
 func stringSliceToRegexpSlice(strings []string) (regexps []regexp.Regexp, err error) {
 	var pattern *regexp.Regexp
 
@@ -31,6 +31,25 @@ func TestLevelToString(t *testing.T) {
 	}
 }
 
+func TestDeviceToString(t *testing.T) {
+	testCases := []struct {
+		have     Device
+		expected string
+	}{
+		{DeviceAny, "any"},
+		{DeviceManaged, "managed"},
+		{DeviceUnmanaged, "unmanaged"},
+		{99, "any"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("Expected_"+tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.have.String())
+			assert.Equal(t, tc.have.String(), NewDevice(tc.expected).String())
+		})
+	}
+}
+
 func TestShouldNotParseInvalidSubjects(t *testing.T) {
 	subjectsSchema := [][]string{{"groups:z"}, {"group:z", "users:b"}}
 	subjectsACL := schemaSubjectsToACL(subjectsSchema)
@@ -0,0 +1,32 @@
+package branding
+
+import (
+	"regexp"
+)
+
+const (
+	fileManifest = "branding.yml"
+)
+
+const (
+	attrTitle          = "title"
+	attrLogo           = "logo"
+	attrPrimaryColor   = "primary_color"
+	attrSecondaryColor = "secondary_color"
+	attrFooter         = "footer"
+	attrMessage        = "message"
+	attrHead           = "head"
+)
+
+const (
+	lenMaxTitle   = 64
+	lenMaxFooter  = 256
+	lenMaxMessage = 1024
+)
+
+var (
+	extsLogo = []string{".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp"}
+
+	reColor  = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	reDomain = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)*[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+)
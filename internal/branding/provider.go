@@ -0,0 +1,261 @@
+package branding
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	tt "text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewProvider returns a new branding Provider for the overlay directory. The manifest is loaded and validated
+// immediately, and a missing manifest is not considered an error.
+func NewProvider(path string) (provider *Provider, err error) {
+	provider = &Provider{
+		path:     path,
+		defaults: &Branding{},
+	}
+
+	if path == "" {
+		return provider, nil
+	}
+
+	if _, err = provider.Reload(); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// Provider of the portal branding loaded from the manifest in the overlay directory.
+type Provider struct {
+	path string
+
+	mu       sync.RWMutex
+	defaults *Branding
+	domains  map[string]*Branding
+}
+
+// Reload the manifest and partials from the overlay directory. The current branding is left unchanged if the
+// manifest is invalid.
+func (p *Provider) Reload() (reloaded bool, err error) {
+	if p.path == "" {
+		return false, nil
+	}
+
+	var (
+		defaults *Branding
+		domains  map[string]*Branding
+	)
+
+	if defaults, domains, err = load(p.path); err != nil {
+		return false, err
+	}
+
+	p.mu.Lock()
+
+	p.defaults, p.domains = defaults, domains
+
+	p.mu.Unlock()
+
+	return true, nil
+}
+
+// Get returns the Branding for the domain. The options of the most specific matching domain in the manifest take
+// precedence over the default options.
+func (p *Provider) Get(domain string) (branding *Branding) {
+	if p == nil {
+		return &Branding{}
+	}
+
+	domain = strings.ToLower(domain)
+
+	p.mu.RLock()
+
+	defer p.mu.RUnlock()
+
+	var match string
+
+	branding = p.defaults
+
+	for name, b := range p.domains {
+		if len(name) <= len(match) {
+			continue
+		}
+
+		if domain == name || strings.HasSuffix(domain, "."+name) {
+			match, branding = name, b
+		}
+	}
+
+	return branding
+}
+
+func load(root string) (defaults *Branding, domains map[string]*Branding, err error) {
+	path := filepath.Join(root, fileManifest)
+
+	var f *os.File
+
+	if f, err = os.Open(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Branding{}, nil, nil
+		}
+
+		return nil, nil, fmt.Errorf("error reading the branding manifest '%s': %w", path, err)
+	}
+
+	defer f.Close()
+
+	manifest := Manifest{}
+
+	decoder := yaml.NewDecoder(f)
+
+	decoder.KnownFields(true)
+
+	if err = decoder.Decode(&manifest); err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("error decoding the branding manifest '%s': %w", path, err)
+	}
+
+	var errs []error
+
+	defaults, errs = manifest.Default.resolve(root, "default")
+
+	names := make([]string, 0, len(manifest.Domains))
+
+	for name := range manifest.Domains {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	domains = make(map[string]*Branding, len(names))
+
+	for _, name := range names {
+		if !reDomain.MatchString(name) {
+			errs = append(errs, fmt.Errorf("domain '%s': the domain must be a valid lowercase domain name", name))
+
+			continue
+		}
+
+		b, berrs := manifest.Domains[name].resolve(root, fmt.Sprintf("domain '%s'", name))
+
+		if len(berrs) != 0 {
+			errs = append(errs, berrs...)
+
+			continue
+		}
+
+		domains[name] = defaults.merge(b)
+	}
+
+	if len(errs) != 0 {
+		return nil, nil, fmt.Errorf("error validating the branding manifest '%s': %w", path, errors.Join(errs...))
+	}
+
+	return defaults, domains, nil
+}
+
+func (o Options) resolve(root, name string) (branding *Branding, errs []error) {
+	branding = &Branding{
+		Title:          o.Title,
+		PrimaryColor:   o.PrimaryColor,
+		SecondaryColor: o.SecondaryColor,
+		Footer:         o.Footer,
+		Message:        o.Message,
+	}
+
+	for _, attr := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{attrTitle, o.Title, lenMaxTitle},
+		{attrFooter, o.Footer, lenMaxFooter},
+		{attrMessage, o.Message, lenMaxMessage},
+	} {
+		if n := len([]rune(attr.value)); n > attr.max {
+			errs = append(errs, fmt.Errorf("%s: option '%s' must not be more than %d characters but it's configured with %d characters", name, attr.name, attr.max, n))
+		}
+	}
+
+	for _, attr := range []struct {
+		name  string
+		value string
+	}{
+		{attrPrimaryColor, o.PrimaryColor},
+		{attrSecondaryColor, o.SecondaryColor},
+	} {
+		if attr.value != "" && !reColor.MatchString(attr.value) {
+			errs = append(errs, fmt.Errorf("%s: option '%s' must be a hex color in the '#RGB' or '#RRGGBB' format but it's configured as '%s'", name, attr.name, attr.value))
+		}
+	}
+
+	var err error
+
+	if o.Logo != "" {
+		switch branding.Logo, err = resolvePath(root, o.Logo); {
+		case err != nil:
+			errs = append(errs, fmt.Errorf("%s: option '%s' is invalid: %w", name, attrLogo, err))
+		case !utils.IsStringInSlice(strings.ToLower(filepath.Ext(o.Logo)), extsLogo):
+			errs = append(errs, fmt.Errorf("%s: option '%s' must have one of the extensions %s but it's configured as '%s'", name, attrLogo, utils.StringJoinOr(extsLogo), o.Logo))
+		}
+	}
+
+	if o.Head != "" {
+		if branding.head, err = loadPartial(root, o.Head); err != nil {
+			errs = append(errs, fmt.Errorf("%s: option '%s' is invalid: %w", name, attrHead, err))
+		}
+	}
+
+	return branding, errs
+}
+
+func resolvePath(root, value string) (path string, err error) {
+	if !filepath.IsLocal(filepath.FromSlash(value)) {
+		return "", fmt.Errorf("the path '%s' must be a relative path within the overlay directory", value)
+	}
+
+	path = filepath.Join(root, filepath.FromSlash(value))
+
+	var info os.FileInfo
+
+	if info, err = os.Stat(path); err != nil {
+		return "", fmt.Errorf("error stating the file '%s': %w", path, err)
+	}
+
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("the path '%s' must be a regular file", path)
+	}
+
+	return path, nil
+}
+
+func loadPartial(root, value string) (t *tt.Template, err error) {
+	var (
+		path string
+		data []byte
+	)
+
+	if path, err = resolvePath(root, value); err != nil {
+		return nil, err
+	}
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("error reading the partial '%s': %w", path, err)
+	}
+
+	if t, err = tt.New("branding/" + filepath.ToSlash(value)).Funcs(templates.SandboxFuncMap()).Parse(string(data)); err != nil {
+		return nil, fmt.Errorf("error parsing the partial '%s': %w", path, err)
+	}
+
+	return t, nil
+}
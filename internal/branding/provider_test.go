@@ -0,0 +1,175 @@
+package branding
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, root, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestNewProviderEmpty(t *testing.T) {
+	provider, err := NewProvider("")
+
+	require.NoError(t, err)
+
+	reloaded, err := provider.Reload()
+
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Equal(t, &Branding{}, provider.Get("example.com"))
+
+	provider, err = NewProvider(t.TempDir())
+
+	require.NoError(t, err)
+	assert.Equal(t, &Branding{}, provider.Get("example.com"))
+}
+
+func TestProviderGet(t *testing.T) {
+	root := t.TempDir()
+
+	writeTestFile(t, root, "logo.png", "default")
+	writeTestFile(t, root, "example.com/logo.svg", "example")
+	writeTestFile(t, root, "partials/head.html", `<style nonce="{{ .CSPNonce }}">body { margin: 0; }</style>`)
+	writeTestFile(t, root, fileManifest, `
+default:
+  title: 'Example Login'
+  logo: 'logo.png'
+  primary_color: '#1976d2'
+  footer: 'Example Corporation'
+domains:
+  example.com:
+    logo: 'example.com/logo.svg'
+    secondary_color: '#fff'
+    message: 'Welcome to Example.'
+  auth.example.com:
+    primary_color: '#000000'
+    head: 'partials/head.html'
+`)
+
+	provider, err := NewProvider(root)
+
+	require.NoError(t, err)
+
+	b := provider.Get("example.org")
+
+	assert.Equal(t, "Example Login", b.Title)
+	assert.Equal(t, filepath.Join(root, "logo.png"), b.Logo)
+	assert.Equal(t, "#1976d2", b.PrimaryColor)
+	assert.Equal(t, "", b.SecondaryColor)
+	assert.Equal(t, "Example Corporation", b.Footer)
+	assert.Equal(t, "", b.Message)
+
+	b = provider.Get("app.EXAMPLE.com")
+
+	assert.Equal(t, "Example Login", b.Title)
+	assert.Equal(t, filepath.Join(root, "example.com", "logo.svg"), b.Logo)
+	assert.Equal(t, "#1976d2", b.PrimaryColor)
+	assert.Equal(t, "#fff", b.SecondaryColor)
+	assert.Equal(t, "Welcome to Example.", b.Message)
+
+	head, err := b.Head(map[string]string{"CSPNonce": "abc"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", head)
+
+	b = provider.Get("auth.example.com")
+
+	assert.Equal(t, "#000000", b.PrimaryColor)
+	assert.Equal(t, filepath.Join(root, "logo.png"), b.Logo)
+
+	head, err = b.Head(map[string]string{"CSPNonce": "abc"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `<style nonce="abc">body { margin: 0; }</style>`, head)
+}
+
+func TestProviderReload(t *testing.T) {
+	root := t.TempDir()
+
+	writeTestFile(t, root, fileManifest, "default:\n  title: 'One'\n")
+
+	provider, err := NewProvider(root)
+
+	require.NoError(t, err)
+	assert.Equal(t, "One", provider.Get("example.com").Title)
+
+	writeTestFile(t, root, fileManifest, "default:\n  title: 'Two'\n")
+
+	reloaded, err := provider.Reload()
+
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, "Two", provider.Get("example.com").Title)
+
+	writeTestFile(t, root, fileManifest, "default:\n  primary_color: 'blue'\n")
+
+	reloaded, err = provider.Reload()
+
+	assert.EqualError(t, err, "error validating the branding manifest '"+filepath.Join(root, fileManifest)+"': default: option 'primary_color' must be a hex color in the '#RGB' or '#RRGGBB' format but it's configured as 'blue'")
+	assert.False(t, reloaded)
+	assert.Equal(t, "Two", provider.Get("example.com").Title)
+}
+
+func TestNewProviderInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		manifest string
+		expected string
+	}{
+		{
+			"ShouldErrorUnknownOption",
+			nil,
+			"default:\n  colour: '#fff'\n",
+			"error decoding the branding manifest '%[1]s/branding.yml': yaml: unmarshal errors:\n  line 2: field colour not found in type branding.Options",
+		},
+		{
+			"ShouldErrorPathTraversal",
+			nil,
+			"default:\n  logo: '../logo.png'\n",
+			"error validating the branding manifest '%[1]s/branding.yml': default: option 'logo' is invalid: the path '../logo.png' must be a relative path within the overlay directory",
+		},
+		{
+			"ShouldErrorLogoExtension",
+			map[string]string{"logo.txt": "logo"},
+			"default:\n  logo: 'logo.txt'\n",
+			"error validating the branding manifest '%[1]s/branding.yml': default: option 'logo' must have one of the extensions '.png', '.jpg', '.jpeg', '.gif', '.svg', or '.webp' but it's configured as 'logo.txt'",
+		},
+		{
+			"ShouldErrorInvalidDomainAndPartial",
+			map[string]string{"head.html": "{{ .Bad "},
+			"domains:\n  Example.com:\n    title: 'Example'\n  example.org:\n    head: 'head.html'\n",
+			"error validating the branding manifest '%[1]s/branding.yml': domain 'Example.com': the domain must be a valid lowercase domain name\ndomain 'example.org': option 'head' is invalid: error parsing the partial '%[1]s/head.html': template: branding/head.html:1: unclosed action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+
+			for name, content := range tc.files {
+				writeTestFile(t, root, name, content)
+			}
+
+			writeTestFile(t, root, fileManifest, tc.manifest)
+
+			provider, err := NewProvider(root)
+
+			assert.Nil(t, provider)
+
+			assert.EqualError(t, err, fmt.Sprintf(tc.expected, root))
+		})
+	}
+}
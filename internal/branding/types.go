@@ -0,0 +1,95 @@
+package branding
+
+import (
+	"bytes"
+	tt "text/template"
+)
+
+// Manifest represents the branding.yml file in the overlay directory.
+type Manifest struct {
+	Default Options            `yaml:"default"`
+	Domains map[string]Options `yaml:"domains"`
+}
+
+// Options represents the branding options for the default or an individual domain. The paths are relative to the
+// overlay directory.
+type Options struct {
+	Title          string `yaml:"title"`
+	Logo           string `yaml:"logo"`
+	PrimaryColor   string `yaml:"primary_color"`
+	SecondaryColor string `yaml:"secondary_color"`
+	Footer         string `yaml:"footer"`
+	Message        string `yaml:"message"`
+	Head           string `yaml:"head"`
+}
+
+// Branding is the resolved branding for a domain.
+type Branding struct {
+	Title          string
+	PrimaryColor   string
+	SecondaryColor string
+	Footer         string
+	Message        string
+
+	// Logo is the absolute path to the logo file.
+	Logo string
+
+	head *tt.Template
+}
+
+// Head renders the head partial with the provided data, returning an empty string if there is no partial.
+func (b *Branding) Head(data any) (head string, err error) {
+	if b == nil || b.head == nil {
+		return "", nil
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = b.head.Execute(buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (b *Branding) merge(other *Branding) (merged *Branding) {
+	merged = &Branding{
+		Title:          b.Title,
+		PrimaryColor:   b.PrimaryColor,
+		SecondaryColor: b.SecondaryColor,
+		Footer:         b.Footer,
+		Message:        b.Message,
+		Logo:           b.Logo,
+		head:           b.head,
+	}
+
+	if other.Title != "" {
+		merged.Title = other.Title
+	}
+
+	if other.PrimaryColor != "" {
+		merged.PrimaryColor = other.PrimaryColor
+	}
+
+	if other.SecondaryColor != "" {
+		merged.SecondaryColor = other.SecondaryColor
+	}
+
+	if other.Footer != "" {
+		merged.Footer = other.Footer
+	}
+
+	if other.Message != "" {
+		merged.Message = other.Message
+	}
+
+	if other.Logo != "" {
+		merged.Logo = other.Logo
+	}
+
+	if other.head != nil {
+		merged.head = other.head
+	}
+
+	return merged
+}
@@ -0,0 +1,185 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// New returns a Breaker for the dependency or nil if the circuit breakers are disabled or the dependency isn't one of
+// the configured dependencies. A nil Breaker is valid and always calls the dependency. Once the open duration has
+// elapsed the probe is used to determine if the dependency has recovered, or the next call if the probe is nil.
+func New(config *schema.CircuitBreaker, dependency, name string, clock clock.Provider, probe Probe) *Breaker {
+	if config == nil || !config.Enabled || !utils.IsStringInSlice(dependency, config.Dependencies) {
+		return nil
+	}
+
+	return &Breaker{
+		name:      name,
+		threshold: config.FailureThreshold,
+		duration:  config.OpenDuration,
+		timeout:   config.ProbeTimeout,
+		clock:     clock,
+		probe:     probe,
+		log:       logging.Logger().WithFields(map[string]any{logFieldCircuitBreaker: name}),
+	}
+}
+
+// Breaker is a circuit breaker which stops calling a dependency once it fails consecutively so the callers fail fast
+// instead of waiting on a dependency which is unavailable. The dependency is probed after the open duration and the
+// calls resume once it has recovered.
+type Breaker struct {
+	name      string
+	threshold int
+	duration  time.Duration
+	timeout   time.Duration
+	clock     clock.Provider
+	probe     Probe
+	log       *logrus.Entry
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	opened   time.Time
+}
+
+// Do calls fn unless the circuit breaker is open in which case an error wrapping ErrOpen is returned without calling
+// fn. The result of fn is recorded and the circuit breaker opens once the failure threshold is reached. The callers
+// canceling the context are not considered failures of the dependency.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) (err error)) (err error) {
+	if b == nil {
+		return fn(ctx)
+	}
+
+	if err = b.allow(ctx); err != nil {
+		return err
+	}
+
+	err = fn(ctx)
+
+	b.record(err)
+
+	return err
+}
+
+// State returns the current State of the circuit breaker.
+func (b *Breaker) State() State {
+	if b == nil {
+		return StateClosed
+	}
+
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) allow(ctx context.Context) (err error) {
+	b.mu.Lock()
+
+	switch b.state {
+	case StateClosed:
+		b.mu.Unlock()
+
+		return nil
+	case StateOpen:
+		if b.clock.Now().Before(b.opened.Add(b.duration)) {
+			b.mu.Unlock()
+
+			return b.errOpen()
+		}
+
+		// Only the caller which transitions the circuit breaker to half-open performs the probe, every other caller
+		// fails fast until the probe has completed.
+		b.state = StateHalfOpen
+
+		b.mu.Unlock()
+
+		if b.probe == nil {
+			return nil
+		}
+
+		return b.doProbe(ctx)
+	default:
+		b.mu.Unlock()
+
+		return b.errOpen()
+	}
+}
+
+func (b *Breaker) doProbe(ctx context.Context) (err error) {
+	// The probe is not bound to the cancellation of the caller as the result is used by every caller.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b.timeout)
+
+	err = b.probe.HealthCheck(ctx)
+
+	cancel()
+
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	if err != nil {
+		b.open(err)
+
+		return b.errOpen()
+	}
+
+	b.close()
+
+	return nil
+}
+
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+
+	defer b.mu.Unlock()
+
+	switch {
+	case err == nil:
+		b.close()
+	case errors.Is(err, context.Canceled):
+		// The call used to probe the dependency was canceled so the next call is used to probe it instead.
+		if b.state == StateHalfOpen && b.probe == nil {
+			b.state = StateOpen
+		}
+	default:
+		b.failures++
+
+		if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.threshold) {
+			b.open(err)
+		}
+	}
+}
+
+func (b *Breaker) open(err error) {
+	if b.state == StateHalfOpen {
+		b.log.WithError(err).Errorf("Circuit breaker probe failed, the calls will continue to fail fast for %s", b.duration)
+	} else {
+		b.log.WithError(err).Errorf("Circuit breaker opened after %d consecutive failures, the calls will fail fast for %s", b.failures, b.duration)
+	}
+
+	b.state, b.opened = StateOpen, b.clock.Now()
+}
+
+func (b *Breaker) close() {
+	if b.state != StateClosed {
+		b.log.Info("Circuit breaker closed as the dependency has recovered")
+	}
+
+	b.state, b.failures = StateClosed, 0
+}
+
+func (b *Breaker) errOpen() error {
+	return fmt.Errorf("%w for the dependency '%s'", ErrOpen, b.name)
+}
@@ -0,0 +1,260 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name       string
+		config     *schema.CircuitBreaker
+		dependency string
+		expected   bool
+	}{
+		{
+			"ShouldReturnNilWithoutConfiguration",
+			nil,
+			schema.CircuitBreakerDependencyLDAP,
+			false,
+		},
+		{
+			"ShouldReturnNilWhenDisabled",
+			&schema.CircuitBreaker{Dependencies: []string{schema.CircuitBreakerDependencyLDAP}},
+			schema.CircuitBreakerDependencyLDAP,
+			false,
+		},
+		{
+			"ShouldReturnNilWhenNotConfiguredDependency",
+			&schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencySMTP}},
+			schema.CircuitBreakerDependencyLDAP,
+			false,
+		},
+		{
+			"ShouldReturnBreaker",
+			&schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencyLDAP}},
+			schema.CircuitBreakerDependencyLDAP,
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := New(tc.config, tc.dependency, "example", clock.New(), nil)
+
+			assert.Equal(t, tc.expected, b != nil)
+		})
+	}
+}
+
+func TestBreakerNil(t *testing.T) {
+	var (
+		b     *Breaker
+		calls int
+	)
+
+	for i := 0; i < 10; i++ {
+		assert.EqualError(t, b.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+
+			return errors.New("connection refused")
+		}), "connection refused")
+	}
+
+	assert.Equal(t, 10, calls)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreakerShouldOpenAndRecoverWithoutProbe(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+
+	b := New(newTestConfig(), schema.CircuitBreakerDependencyLDAP, "ldap", c, nil)
+
+	var (
+		calls int
+		errFn = errors.New("connection refused")
+	)
+
+	fn := func(ctx context.Context) error {
+		calls++
+
+		return errFn
+	}
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, b.Do(context.Background(), fn), errFn)
+		assert.Equal(t, StateClosed, b.State())
+	}
+
+	assert.NoError(t, b.Do(context.Background(), func(ctx context.Context) error { return nil }))
+
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, b.Do(context.Background(), fn), errFn)
+	}
+
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, 5, calls)
+
+	err := b.Do(context.Background(), fn)
+
+	assert.ErrorIs(t, err, ErrOpen)
+	assert.EqualError(t, err, "circuit breaker is open for the dependency 'ldap'")
+	assert.Equal(t, 5, calls)
+
+	c.Set(now.Add(time.Second * 30))
+
+	assert.ErrorIs(t, b.Do(context.Background(), fn), errFn)
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, 6, calls)
+
+	assert.ErrorIs(t, b.Do(context.Background(), fn), ErrOpen)
+	assert.Equal(t, 6, calls)
+
+	c.Set(now.Add(time.Minute))
+
+	assert.NoError(t, b.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+
+		assert.Equal(t, StateHalfOpen, b.State())
+		assert.ErrorIs(t, b.Do(context.Background(), fn), ErrOpen)
+
+		return nil
+	}))
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, 7, calls)
+}
+
+func TestBreakerShouldOpenAndRecoverWithProbe(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+
+	var (
+		probes   int
+		errProbe = errors.New("dial failed")
+	)
+
+	b := New(newTestConfig(), schema.CircuitBreakerDependencySMTP, "smtp", c, probeFunc(func(ctx context.Context) error {
+		probes++
+
+		_, ok := ctx.Deadline()
+
+		assert.True(t, ok)
+
+		return errProbe
+	}))
+
+	var calls int
+
+	fn := func(ctx context.Context) error {
+		calls++
+
+		return errors.New("connection refused")
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.Error(t, b.Do(context.Background(), fn))
+	}
+
+	assert.Equal(t, StateOpen, b.State())
+
+	c.Set(now.Add(time.Second * 30))
+
+	assert.ErrorIs(t, b.Do(context.Background(), fn), ErrOpen)
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, 1, probes)
+	assert.Equal(t, 3, calls)
+
+	assert.ErrorIs(t, b.Do(context.Background(), fn), ErrOpen)
+	assert.Equal(t, 1, probes)
+
+	c.Set(now.Add(time.Minute))
+
+	errProbe = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancel()
+
+	assert.NoError(t, b.Do(ctx, func(ctx context.Context) error {
+		calls++
+
+		return nil
+	}))
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, 2, probes)
+	assert.Equal(t, 4, calls)
+}
+
+func TestBreakerShouldNotCountCanceled(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+
+	b := New(newTestConfig(), schema.CircuitBreakerDependencyDuo, "duo", c, nil)
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, b.Do(context.Background(), func(ctx context.Context) error {
+			return context.Canceled
+		}), context.Canceled)
+	}
+
+	assert.Equal(t, StateClosed, b.State())
+
+	for i := 0; i < 3; i++ {
+		require.Error(t, b.Do(context.Background(), func(ctx context.Context) error {
+			return context.DeadlineExceeded
+		}))
+	}
+
+	assert.Equal(t, StateOpen, b.State())
+
+	c.Set(now.Add(time.Second * 30))
+
+	assert.ErrorIs(t, b.Do(context.Background(), func(ctx context.Context) error {
+		return context.Canceled
+	}), context.Canceled)
+
+	assert.Equal(t, StateOpen, b.State())
+
+	assert.NoError(t, b.Do(context.Background(), func(ctx context.Context) error {
+		return nil
+	}))
+
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "closed", StateClosed.String())
+	assert.Equal(t, "open", StateOpen.String())
+	assert.Equal(t, "half-open", StateHalfOpen.String())
+	assert.Equal(t, "unknown", State(-1).String())
+}
+
+func newTestConfig() *schema.CircuitBreaker {
+	return &schema.CircuitBreaker{
+		Enabled:          true,
+		Dependencies:     []string{schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencyDuo, schema.CircuitBreakerDependencyStorage},
+		FailureThreshold: 3,
+		OpenDuration:     time.Second * 30,
+		ProbeTimeout:     time.Second,
+	}
+}
+
+type probeFunc func(ctx context.Context) error
+
+func (f probeFunc) HealthCheck(ctx context.Context) error {
+	return f(ctx)
+}
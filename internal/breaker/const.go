@@ -0,0 +1,23 @@
+package breaker
+
+import (
+	"errors"
+)
+
+// ErrOpen is returned instead of calling the dependency when the circuit breaker is open.
+var ErrOpen = errors.New("circuit breaker is open")
+
+const (
+	// StateClosed is the state where the calls are made to the dependency.
+	StateClosed State = iota
+
+	// StateOpen is the state where the calls fail fast without being made to the dependency.
+	StateOpen
+
+	// StateHalfOpen is the state where the dependency is being probed to determine if it has recovered.
+	StateHalfOpen
+)
+
+const (
+	logFieldCircuitBreaker = "circuit_breaker"
+)
@@ -0,0 +1,27 @@
+package breaker
+
+import (
+	"context"
+)
+
+// Probe is a dependency which can be health checked to determine if it has recovered.
+type Probe interface {
+	HealthCheck(ctx context.Context) (err error)
+}
+
+// State is the state of a Breaker.
+type State int
+
+// String returns the name of the State.
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
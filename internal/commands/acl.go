@@ -52,8 +52,12 @@ func newAccessControlCheckCommand(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.Flags().String("username", "", "the username of the subject")
 	cmd.Flags().StringSlice("groups", nil, "the groups of the subject")
 	cmd.Flags().String("ip", "", "the ip of the subject")
+	cmd.Flags().Bool("managed-device", false, "the subject presents a valid managed device certificate")
 	cmd.Flags().Bool("verbose", false, "enables verbose output")
 
+	_ = cmd.RegisterFlagCompletionFunc("url", ctx.CompletionDomainURLs)
+	_ = cmd.RegisterFlagCompletionFunc("username", ctx.CompletionStorageUsernamesFlag)
+
 	return cmd
 }
 
@@ -73,20 +77,133 @@ func (ctx *CmdCtx) AccessControlCheckRunE(cmd *cobra.Command, _ []string) (err e
 
 	results := authorizer.GetRuleMatchResults(subject, object)
 
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return err
+	}
+
+	result := newAccessControlCheckResult(object, subject, results, ctx.config.AccessControl.DefaultPolicy, verbose)
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
 	if len(results) == 0 {
 		fmt.Printf("\nThe default policy '%s' will be applied to ALL requests as no rules are configured.\n\n", ctx.config.AccessControl.DefaultPolicy)
 
 		return nil
 	}
 
-	verbose, err := cmd.Flags().GetBool("verbose")
-	if err != nil {
-		return err
+	accessControlCheckWriteOutput(object, subject, result)
+
+	return nil
+}
+
+// accessControlCheckResult is the result of the authelia access-control check-policy command.
+type accessControlCheckResult struct {
+	URL           string   `json:"url"`
+	Method        string   `json:"method,omitempty"`
+	Username      string   `json:"username,omitempty"`
+	Groups        []string `json:"groups,omitempty"`
+	IP            string   `json:"ip,omitempty"`
+	ManagedDevice bool     `json:"managed_device,omitempty"`
+
+	Rules []accessControlCheckRuleResult `json:"rules"`
+
+	// Policy is the policy which will be applied to the request from the rule at the position Rule or from the default
+	// policy if Rule is 0.
+	Policy string `json:"policy"`
+	Rule   int    `json:"rule,omitempty"`
+
+	// PotentialPolicy is the policy which will potentially be applied to the request from the rule at the position
+	// PotentialRule, it is potential as it depends on the groups of the subject.
+	PotentialPolicy string `json:"potential_policy,omitempty"`
+	PotentialRule   int    `json:"potential_rule,omitempty"`
+}
+
+// accessControlCheckRuleResult is the result of an individual rule for the authelia access-control check-policy
+// command, each criteria is either 'hit', 'miss', or 'may'.
+type accessControlCheckRuleResult struct {
+	Position  int    `json:"position"`
+	Policy    string `json:"policy"`
+	Applied   bool   `json:"applied"`
+	Potential bool   `json:"potential"`
+	Domain    string `json:"domain"`
+	Resource  string `json:"resource"`
+	Method    string `json:"method"`
+	Network   string `json:"network"`
+	Device    string `json:"device"`
+	Subject   string `json:"subject"`
+}
+
+func newAccessControlCheckResult(object authorization.Object, subject authorization.Subject, results []authorization.RuleMatchResult, defaultPolicy string, verbose bool) (result accessControlCheckResult) {
+	result = accessControlCheckResult{
+		URL:           object.String(),
+		Method:        object.Method,
+		Username:      subject.Username,
+		Groups:        subject.Groups,
+		ManagedDevice: subject.ManagedDevice,
+		Rules:         make([]accessControlCheckRuleResult, 0, len(results)),
 	}
 
-	accessControlCheckWriteOutput(object, subject, results, ctx.config.AccessControl.DefaultPolicy, verbose)
+	if subject.IP != nil {
+		result.IP = subject.IP.String()
+	}
 
-	return nil
+	var (
+		appliedPos int
+		applied    authorization.RuleMatchResult
+
+		potentialPos int
+		potential    authorization.RuleMatchResult
+	)
+
+	for i, match := range results {
+		if match.Skipped && !verbose {
+			break
+		}
+
+		rule := accessControlCheckRuleResult{
+			Position: i + 1,
+			Policy:   match.Rule.Policy.String(),
+			Domain:   hitMissMay(match.MatchDomain),
+			Resource: hitMissMay(match.MatchResources),
+			Method:   hitMissMay(match.MatchMethods),
+			Network:  hitMissMay(match.MatchNetworks),
+			Device:   hitMissMay(match.MatchDevice),
+			Subject:  hitMissMay(match.MatchSubjects, match.MatchSubjectsExact),
+		}
+
+		switch {
+		case match.IsMatch() && !match.Skipped:
+			appliedPos, applied = i+1, match
+
+			rule.Applied = true
+		case match.IsPotentialMatch() && !match.Skipped:
+			if potentialPos == 0 {
+				potentialPos, potential = i+1, match
+			}
+
+			rule.Potential = true
+		}
+
+		result.Rules = append(result.Rules, rule)
+	}
+
+	switch {
+	case appliedPos != 0 && (potentialPos == 0 || (potentialPos > appliedPos)):
+		result.Policy, result.Rule = applied.Rule.Policy.String(), appliedPos
+	case potentialPos != 0 && appliedPos != 0:
+		result.Policy, result.Rule = applied.Rule.Policy.String(), appliedPos
+		result.PotentialPolicy, result.PotentialRule = potential.Rule.Policy.String(), potentialPos
+	case potentialPos != 0:
+		result.Policy = defaultPolicy
+		result.PotentialPolicy, result.PotentialRule = potential.Rule.Policy.String(), potentialPos
+	default:
+		result.Policy = defaultPolicy
+	}
+
+	return result
 }
 
 func accessControlCheckWriteObjectSubject(object authorization.Object, subject authorization.Subject) {
@@ -110,58 +227,46 @@ func accessControlCheckWriteObjectSubject(object authorization.Object, subject a
 		output.WriteString(fmt.Sprintf(" from IP '%s'", subject.IP.String()))
 	}
 
+	if subject.ManagedDevice {
+		output.WriteString(" from a managed device")
+	}
+
 	output.WriteString(".\n")
 
 	fmt.Println(output.String())
 }
 
-func accessControlCheckWriteOutput(object authorization.Object, subject authorization.Subject, results []authorization.RuleMatchResult, defaultPolicy string, verbose bool) {
+func accessControlCheckWriteOutput(object authorization.Object, subject authorization.Subject, result accessControlCheckResult) {
 	accessControlCheckWriteObjectSubject(object, subject)
 
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
 
-	_, _ = fmt.Fprintln(w, "  #\tDomain\tResource\tMethod\tNetwork\tSubject")
-
-	var (
-		appliedPos int
-		applied    authorization.RuleMatchResult
-
-		potentialPos int
-		potential    authorization.RuleMatchResult
-	)
+	_, _ = fmt.Fprintln(w, "  #\tDomain\tResource\tMethod\tNetwork\tDevice\tSubject")
 
-	for i, result := range results {
-		if result.Skipped && !verbose {
-			break
-		}
+	for _, rule := range result.Rules {
+		prefix := " "
 
 		switch {
-		case result.IsMatch() && !result.Skipped:
-			appliedPos, applied = i+1, result
-
-			_, _ = fmt.Fprintf(w, "* %d\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
-		case result.IsPotentialMatch() && !result.Skipped:
-			if potentialPos == 0 {
-				potentialPos, potential = i+1, result
-			}
-
-			_, _ = fmt.Fprintf(w, "~ %d\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
-		default:
-			_, _ = fmt.Fprintf(w, "  %d\t%s\t%s\t%s\t%s\t%s\n", i+1, hitMissMay(result.MatchDomain), hitMissMay(result.MatchResources), hitMissMay(result.MatchMethods), hitMissMay(result.MatchNetworks), hitMissMay(result.MatchSubjects, result.MatchSubjectsExact))
+		case rule.Applied:
+			prefix = "*"
+		case rule.Potential:
+			prefix = "~"
 		}
+
+		_, _ = fmt.Fprintf(w, "%s %d\t%s\t%s\t%s\t%s\t%s\t%s\n", prefix, rule.Position, rule.Domain, rule.Resource, rule.Method, rule.Network, rule.Device, rule.Subject)
 	}
 
 	_ = w.Flush()
 
 	switch {
-	case appliedPos != 0 && (potentialPos == 0 || (potentialPos > appliedPos)):
-		fmt.Printf("\nThe policy '%s' from rule #%d will be applied to this request.\n\n", applied.Rule.Policy, appliedPos)
-	case potentialPos != 0 && appliedPos != 0:
-		fmt.Printf("\nThe policy '%s' from rule #%d will potentially be applied to this request. If not policy '%s' from rule #%d will be.\n\n", potential.Rule.Policy, potentialPos, applied.Rule.Policy, appliedPos)
-	case potentialPos != 0:
-		fmt.Printf("\nThe policy '%s' from rule #%d will potentially be applied to this request. Otherwise the policy '%s' from the default policy will be.\n\n", potential.Rule.Policy, potentialPos, defaultPolicy)
+	case result.Rule != 0 && result.PotentialRule == 0:
+		fmt.Printf("\nThe policy '%s' from rule #%d will be applied to this request.\n\n", result.Policy, result.Rule)
+	case result.Rule != 0:
+		fmt.Printf("\nThe policy '%s' from rule #%d will potentially be applied to this request. If not policy '%s' from rule #%d will be.\n\n", result.PotentialPolicy, result.PotentialRule, result.Policy, result.Rule)
+	case result.PotentialRule != 0:
+		fmt.Printf("\nThe policy '%s' from rule #%d will potentially be applied to this request. Otherwise the policy '%s' from the default policy will be.\n\n", result.PotentialPolicy, result.PotentialRule, result.Policy)
 	default:
-		fmt.Printf("\nThe policy '%s' from the default policy will be applied to this request as no rules matched the request.\n\n", defaultPolicy)
+		fmt.Printf("\nThe policy '%s' from the default policy will be applied to this request as no rules matched the request.\n\n", result.Policy)
 	}
 }
 
@@ -217,12 +322,18 @@ func getSubjectAndObjectFromFlags(cmd *cobra.Command) (subject authorization.Sub
 		return subject, object, err
 	}
 
+	managedDevice, err := cmd.Flags().GetBool("managed-device")
+	if err != nil {
+		return subject, object, err
+	}
+
 	parsedIP := net.ParseIP(remoteIP)
 
 	subject = authorization.Subject{
-		Username: username,
-		Groups:   groups,
-		IP:       parsedIP,
+		Username:      username,
+		Groups:        groups,
+		IP:            parsedIP,
+		ManagedDevice: managedDevice,
 	}
 
 	object = authorization.NewObject(parsedURL, method)
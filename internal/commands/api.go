@@ -0,0 +1,536 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newAPICmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "api",
+		Short:   cmdAutheliaAPIShort,
+		Long:    cmdAutheliaAPILong,
+		Example: cmdAutheliaAPIExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.PersistentFlags().String(cmdFlagNameURL, "", fmt.Sprintf("the url of the Authelia instance, can also be set using the %s environment variable", cmdFlagEnvNameURL))
+	cmd.PersistentFlags().String(cmdFlagNameToken, "", fmt.Sprintf("the bearer token used to authenticate to the admin API, can also be set using the %s environment variable", cmdFlagEnvNameToken))
+	cmd.PersistentFlags().Duration(cmdFlagNameTimeout, time.Second*10, "the timeout of each request to the admin API")
+
+	cmd.AddCommand(
+		newAPISessionsCmd(ctx),
+		newAPIUserCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newAPISessionsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "sessions",
+		Short:   cmdAutheliaAPISessionsShort,
+		Long:    cmdAutheliaAPISessionsLong,
+		Example: cmdAutheliaAPISessionsExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newAPISessionsListCmd(ctx),
+		newAPISessionsRevokeCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newAPISessionsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaAPISessionsListShort,
+		Long:    cmdAutheliaAPISessionsListLong,
+		Example: cmdAutheliaAPISessionsListExample,
+		RunE:    ctx.APISessionsListRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUser, "", "the username of the user to list the sessions of")
+	cmd.Flags().String(cmdFlagNameFormat, storageUserFormatTable, fmt.Sprintf("the output format, options are %s", utils.StringJoinOr(storageUserFormats)))
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUser)
+
+	return cmd
+}
+
+func newAPISessionsRevokeCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "revoke",
+		Short:   cmdAutheliaAPISessionsRevokeShort,
+		Long:    cmdAutheliaAPISessionsRevokeLong,
+		Example: cmdAutheliaAPISessionsRevokeExample,
+		RunE:    ctx.APISessionsRevokeRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUser, "", "the username of the user to revoke the sessions of")
+	cmd.Flags().String(cmdFlagNameID, "", "only revoke the session with this id")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUser)
+
+	return cmd
+}
+
+func newAPIUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "user",
+		Short:   cmdAutheliaAPIUserShort,
+		Long:    cmdAutheliaAPIUserLong,
+		Example: cmdAutheliaAPIUserExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newAPIUserUnbanCmd(ctx),
+		newAPIUserTOTPCmd(ctx),
+		newAPIUserWebAuthnCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newAPIUserUnbanCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "unban",
+		Short:   cmdAutheliaAPIUserUnbanShort,
+		Long:    cmdAutheliaAPIUserUnbanLong,
+		Example: cmdAutheliaAPIUserUnbanExample,
+		RunE:    ctx.APIUserUnbanRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUser, "", "the username of the user to unban")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUser)
+
+	return cmd
+}
+
+func newAPIUserTOTPCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "totp",
+		Short:   cmdAutheliaAPIUserTOTPShort,
+		Long:    cmdAutheliaAPIUserTOTPLong,
+		Example: cmdAutheliaAPIUserTOTPExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newAPIUserTOTPDeleteCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newAPIUserTOTPDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete",
+		Short:   cmdAutheliaAPIUserTOTPDeleteShort,
+		Long:    cmdAutheliaAPIUserTOTPDeleteLong,
+		Example: cmdAutheliaAPIUserTOTPDeleteExample,
+		RunE:    ctx.APIUserTOTPDeleteRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUser, "", "the username of the user to delete the TOTP configuration of")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUser)
+
+	return cmd
+}
+
+func newAPIUserWebAuthnCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "webauthn",
+		Short:   cmdAutheliaAPIUserWebAuthnShort,
+		Long:    cmdAutheliaAPIUserWebAuthnLong,
+		Example: cmdAutheliaAPIUserWebAuthnExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newAPIUserWebAuthnDeleteCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newAPIUserWebAuthnDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete",
+		Short:   cmdAutheliaAPIUserWebAuthnDeleteShort,
+		Long:    cmdAutheliaAPIUserWebAuthnDeleteLong,
+		Example: cmdAutheliaAPIUserWebAuthnDeleteExample,
+		RunE:    ctx.APIUserWebAuthnDeleteRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUser, "", "the username of the user to delete the WebAuthn credentials of")
+	cmd.Flags().Bool(cmdFlagNameAll, false, "delete all of the users WebAuthn credentials")
+	cmd.Flags().String(cmdFlagNameKeyID, "", "delete a users WebAuthn credential by key id")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUser)
+
+	cmd.MarkFlagsMutuallyExclusive(cmdFlagNameAll, cmdFlagNameKeyID)
+	cmd.MarkFlagsOneRequired(cmdFlagNameAll, cmdFlagNameKeyID)
+
+	return cmd
+}
+
+// APISessionsListRunE is the RunE for the authelia api sessions list command.
+func (ctx *CmdCtx) APISessionsListRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		client       *apiClient
+		user, format string
+	)
+
+	if client, err = newAPIClientFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if user, err = cmd.Flags().GetString(cmdFlagNameUser); err != nil {
+		return err
+	}
+
+	if format, err = cmd.Flags().GetString(cmdFlagNameFormat); err != nil {
+		return err
+	}
+
+	if !utils.IsStringInSlice(format, storageUserFormats) {
+		return fmt.Errorf("invalid format '%s': must be one of %s", format, utils.StringJoinOr(storageUserFormats))
+	}
+
+	var sessions []apiSession
+
+	if err = client.do(ctx, http.MethodGet, &sessions, apiPathUsers, user, apiPathSessions); err != nil {
+		return fmt.Errorf("failed to list the sessions of user '%s': %w", user, err)
+	}
+
+	if format == storageUserFormatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(sessions)
+	}
+
+	if len(sessions) == 0 {
+		fmt.Printf("No sessions were found for user '%s'\n", user)
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "ID\tDomain\tRemote IP\tLevel\tLast Activity")
+
+	for _, session := range sessions {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", session.ID, session.Domain, session.RemoteIP, session.AuthenticationLevel, session.LastActivity.Format(time.RFC3339))
+	}
+
+	return w.Flush()
+}
+
+// APISessionsRevokeRunE is the RunE for the authelia api sessions revoke command.
+func (ctx *CmdCtx) APISessionsRevokeRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		client   *apiClient
+		user, id string
+	)
+
+	if client, err = newAPIClientFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if user, err = cmd.Flags().GetString(cmdFlagNameUser); err != nil {
+		return err
+	}
+
+	if id, err = cmd.Flags().GetString(cmdFlagNameID); err != nil {
+		return err
+	}
+
+	if id != "" {
+		if err = client.do(ctx, http.MethodDelete, nil, apiPathUsers, user, apiPathSessions, id); err != nil {
+			return fmt.Errorf("failed to revoke the session with id '%s' of user '%s': %w", id, user, err)
+		}
+
+		fmt.Printf("Successfully revoked the session with id '%s' of user '%s'\n", id, user)
+
+		return nil
+	}
+
+	var result apiSessionsRevokeResult
+
+	if err = client.do(ctx, http.MethodDelete, &result, apiPathUsers, user, apiPathSessions); err != nil {
+		return fmt.Errorf("failed to revoke the sessions of user '%s': %w", user, err)
+	}
+
+	fmt.Printf("Successfully revoked %d sessions of user '%s'\n", result.Revoked, user)
+
+	return nil
+}
+
+// APIUserUnbanRunE is the RunE for the authelia api user unban command.
+func (ctx *CmdCtx) APIUserUnbanRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		client *apiClient
+		user   string
+	)
+
+	if client, err = newAPIClientFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if user, err = cmd.Flags().GetString(cmdFlagNameUser); err != nil {
+		return err
+	}
+
+	if err = client.do(ctx, http.MethodDelete, nil, apiPathUsers, user, apiPathBan); err != nil {
+		return fmt.Errorf("failed to unban user '%s': %w", user, err)
+	}
+
+	fmt.Printf("Successfully unbanned user '%s'\n", user)
+
+	return nil
+}
+
+// APIUserTOTPDeleteRunE is the RunE for the authelia api user totp delete command.
+func (ctx *CmdCtx) APIUserTOTPDeleteRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		client *apiClient
+		user   string
+	)
+
+	if client, err = newAPIClientFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if user, err = cmd.Flags().GetString(cmdFlagNameUser); err != nil {
+		return err
+	}
+
+	if err = client.do(ctx, http.MethodDelete, nil, apiPathUsers, user, apiPathTOTP); err != nil {
+		return fmt.Errorf("failed to delete the TOTP configuration of user '%s': %w", user, err)
+	}
+
+	fmt.Printf("Successfully deleted the TOTP configuration of user '%s'\n", user)
+
+	return nil
+}
+
+// APIUserWebAuthnDeleteRunE is the RunE for the authelia api user webauthn delete command.
+func (ctx *CmdCtx) APIUserWebAuthnDeleteRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		client    *apiClient
+		user, kid string
+	)
+
+	if client, err = newAPIClientFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if user, err = cmd.Flags().GetString(cmdFlagNameUser); err != nil {
+		return err
+	}
+
+	if kid, err = cmd.Flags().GetString(cmdFlagNameKeyID); err != nil {
+		return err
+	}
+
+	if kid != "" {
+		if err = client.do(ctx, http.MethodDelete, nil, apiPathUsers, user, apiPathWebAuthn, kid); err != nil {
+			return fmt.Errorf("failed to delete the WebAuthn credential with key id '%s' of user '%s': %w", kid, user, err)
+		}
+
+		fmt.Printf("Successfully deleted the WebAuthn credential with key id '%s' of user '%s'\n", kid, user)
+
+		return nil
+	}
+
+	if err = client.do(ctx, http.MethodDelete, nil, apiPathUsers, user, apiPathWebAuthn); err != nil {
+		return fmt.Errorf("failed to delete all WebAuthn credentials of user '%s': %w", user, err)
+	}
+
+	fmt.Printf("Successfully deleted all WebAuthn credentials of user '%s'\n", user)
+
+	return nil
+}
+
+// apiSession is a session of a user as returned by the admin API.
+type apiSession struct {
+	ID                  string    `json:"id"`
+	Domain              string    `json:"domain"`
+	RemoteIP            string    `json:"remote_ip"`
+	AuthenticationLevel string    `json:"authentication_level"`
+	LastActivity        time.Time `json:"last_activity"`
+}
+
+// apiSessionsRevokeResult is the result of revoking all the sessions of a user as returned by the admin API.
+type apiSessionsRevokeResult struct {
+	Revoked int `json:"revoked"`
+}
+
+// apiResponse is the response body of the admin API which uses the same format as the other API endpoints.
+type apiResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// apiClient performs authenticated requests against the admin API of a running Authelia instance.
+type apiClient struct {
+	client  *http.Client
+	baseURL *url.URL
+	token   string
+}
+
+func newAPIClientFromCmd(cmd *cobra.Command) (client *apiClient, err error) {
+	var (
+		rawURL, token string
+		timeout       time.Duration
+	)
+
+	if rawURL, _, err = loadXEnvCLIStringValue(cmd, cmdFlagEnvNameURL, cmdFlagNameURL); err != nil {
+		return nil, err
+	}
+
+	if token, _, err = loadXEnvCLIStringValue(cmd, cmdFlagEnvNameToken, cmdFlagNameToken); err != nil {
+		return nil, err
+	}
+
+	if timeout, err = cmd.Flags().GetDuration(cmdFlagNameTimeout); err != nil {
+		return nil, err
+	}
+
+	return newAPIClient(rawURL, token, timeout)
+}
+
+func newAPIClient(rawURL, token string, timeout time.Duration) (client *apiClient, err error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("the url must be specified using the --%s flag or the %s environment variable", cmdFlagNameURL, cmdFlagEnvNameURL)
+	}
+
+	if token == "" {
+		return nil, fmt.Errorf("the token must be specified using the --%s flag or the %s environment variable", cmdFlagNameToken, cmdFlagEnvNameToken)
+	}
+
+	var baseURL *url.URL
+
+	if baseURL, err = url.ParseRequestURI(rawURL); err != nil {
+		return nil, fmt.Errorf("failed to parse the url: %w", err)
+	}
+
+	switch baseURL.Scheme {
+	case "https", "http":
+		break
+	default:
+		return nil, fmt.Errorf("failed to parse the url: the scheme '%s' is not supported, the scheme must be 'https' or 'http'", baseURL.Scheme)
+	}
+
+	return &apiClient{
+		client:  &http.Client{Timeout: timeout},
+		baseURL: baseURL,
+		token:   token,
+	}, nil
+}
+
+// do performs a request against the admin API endpoint made up of the path elements which are escaped, and decodes
+// the data of the response into v if it's not nil.
+func (c *apiClient) do(ctx context.Context, method string, v any, elems ...string) (err error) {
+	escaped := make([]string, len(elems))
+
+	for i, elem := range elems {
+		escaped[i] = url.PathEscape(elem)
+	}
+
+	target := c.baseURL.JoinPath(append([]string{apiPathBase}, escaped...)...)
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, method, target.String(), nil); err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	var resp *http.Response
+
+	if resp, err = c.client.Do(req); err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	var data []byte
+
+	if data, err = io.ReadAll(resp.Body); err != nil {
+		return fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	body := apiResponse{}
+
+	if err = json.Unmarshal(data, &body); err != nil {
+		return fmt.Errorf("the server responded with status code %d and a response which is not valid JSON: %w", resp.StatusCode, err)
+	}
+
+	switch {
+	case resp.StatusCode != http.StatusOK || body.Status != "OK":
+		if body.Message == "" {
+			return fmt.Errorf("the server responded with status code %d", resp.StatusCode)
+		}
+
+		return fmt.Errorf("the server responded with status code %d: %s", resp.StatusCode, body.Message)
+	case v == nil:
+		return nil
+	case len(body.Data) == 0:
+		return errors.New("the server responded without any data")
+	default:
+		if err = json.Unmarshal(body.Data, v); err != nil {
+			return fmt.Errorf("failed to decode the response data: %w", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIClient(t *testing.T) {
+	testCases := []struct {
+		name  string
+		url   string
+		token string
+		err   string
+	}{
+		{"ShouldCreateClient", "https://auth.example.com", "a1b2c3d4", ""},
+		{"ShouldErrorNoURL", "", "a1b2c3d4", "the url must be specified using the --url flag or the X_AUTHELIA_API_URL environment variable"},
+		{"ShouldErrorNoToken", "https://auth.example.com", "", "the token must be specified using the --token flag or the X_AUTHELIA_API_TOKEN environment variable"},
+		{"ShouldErrorInvalidURL", "auth.example.com", "a1b2c3d4", "failed to parse the url: parse \"auth.example.com\": invalid URI for request"},
+		{"ShouldErrorInvalidScheme", "ftp://auth.example.com", "a1b2c3d4", "failed to parse the url: the scheme 'ftp' is not supported, the scheme must be 'https' or 'http'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := newAPIClient(tc.url, tc.token, time.Second)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.token, client.token)
+				assert.Equal(t, time.Second, client.client.Timeout)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, client)
+			}
+		})
+	}
+}
+
+func TestAPIClientDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Header.Get("Authorization") != "Bearer a1b2c3d4" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			_, _ = w.Write([]byte(`{"status":"KO","message":"Unauthorized"}`))
+
+			return
+		}
+
+		switch r.Method + " " + r.URL.EscapedPath() {
+		case "GET /authelia/api/admin/users/john%2Fdoe/sessions":
+			_, _ = w.Write([]byte(`{"status":"OK","data":[{"id":"3f6f3a4c","domain":"example.com","remote_ip":"192.168.1.10","authentication_level":"two_factor","last_activity":"2026-10-14T10:00:00Z"}]}`))
+		case "DELETE /authelia/api/admin/users/john/sessions":
+			_, _ = w.Write([]byte(`{"status":"OK","data":{"revoked":2}}`))
+		case "DELETE /authelia/api/admin/users/john/ban":
+			_, _ = w.Write([]byte(`{"status":"OK"}`))
+		case "DELETE /authelia/api/admin/users/john/totp":
+			_, _ = w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			_, _ = w.Write([]byte(`{"status":"KO","message":"Not Found"}`))
+		}
+	}))
+
+	defer server.Close()
+
+	client, err := newAPIClient(server.URL+"/authelia", "a1b2c3d4", time.Second)
+
+	require.NoError(t, err)
+
+	t.Run("ShouldDecodeData", func(t *testing.T) {
+		var sessions []apiSession
+
+		require.NoError(t, client.do(context.Background(), http.MethodGet, &sessions, apiPathUsers, "john/doe", apiPathSessions))
+
+		assert.Equal(t, []apiSession{{ID: "3f6f3a4c", Domain: "example.com", RemoteIP: "192.168.1.10", AuthenticationLevel: "two_factor", LastActivity: time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)}}, sessions)
+	})
+
+	t.Run("ShouldDecodeResult", func(t *testing.T) {
+		var result apiSessionsRevokeResult
+
+		require.NoError(t, client.do(context.Background(), http.MethodDelete, &result, apiPathUsers, "john", apiPathSessions))
+
+		assert.Equal(t, 2, result.Revoked)
+	})
+
+	t.Run("ShouldSucceedWithoutData", func(t *testing.T) {
+		assert.NoError(t, client.do(context.Background(), http.MethodDelete, nil, apiPathUsers, "john", apiPathBan))
+	})
+
+	t.Run("ShouldErrorWithoutData", func(t *testing.T) {
+		var result apiSessionsRevokeResult
+
+		assert.EqualError(t, client.do(context.Background(), http.MethodDelete, &result, apiPathUsers, "john", apiPathBan), "the server responded without any data")
+	})
+
+	t.Run("ShouldErrorInvalidJSON", func(t *testing.T) {
+		assert.EqualError(t, client.do(context.Background(), http.MethodDelete, nil, apiPathUsers, "john", apiPathTOTP), "the server responded with status code 200 and a response which is not valid JSON: invalid character 'o' in literal null (expecting 'u')")
+	})
+
+	t.Run("ShouldErrorNotFound", func(t *testing.T) {
+		assert.EqualError(t, client.do(context.Background(), http.MethodDelete, nil, apiPathUsers, "john", apiPathWebAuthn), "the server responded with status code 404: Not Found")
+	})
+
+	t.Run("ShouldErrorUnauthorized", func(t *testing.T) {
+		unauthorized, err := newAPIClient(server.URL+"/authelia", "invalid", time.Second)
+
+		require.NoError(t, err)
+
+		assert.EqualError(t, unauthorized.do(context.Background(), http.MethodDelete, nil, apiPathUsers, "john", apiPathBan), "the server responded with status code 401: Unauthorized")
+	})
+}
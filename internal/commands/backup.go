@@ -0,0 +1,399 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newBackupCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "backup",
+		Short:   cmdAutheliaBackupShort,
+		Long:    cmdAutheliaBackupLong,
+		Example: cmdAutheliaBackupExample,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigStorageCommandLineConfigRunE,
+			ctx.HelperConfigLoadRunE,
+			ctx.ConfigValidateStorageRunE,
+			ctx.LoadProvidersStorageRunE,
+		),
+		Args: cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorage(cmd)
+
+	cmd.AddCommand(
+		newBackupCreateCmd(ctx),
+		newBackupRestoreCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newBackupCreateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "create",
+		Short:   cmdAutheliaBackupCreateShort,
+		Long:    cmdAutheliaBackupCreateLong,
+		Example: cmdAutheliaBackupCreateExample,
+		RunE:    ctx.BackupCreateRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().StringP(cmdFlagNameFile, "f", "authelia.backup.tar.gz", "the file to write the backup to")
+
+	return cmd
+}
+
+func newBackupRestoreCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "restore <filename>",
+		Short:   cmdAutheliaBackupRestoreShort,
+		Long:    cmdAutheliaBackupRestoreLong,
+		Example: cmdAutheliaBackupRestoreExample,
+		RunE:    ctx.BackupRestoreRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().Bool(cmdFlagNameForce, false, "restore the backup even if it was created with a different storage schema version")
+
+	return cmd
+}
+
+// BackupCreateRunE is the RunE for the authelia backup create command.
+func (ctx *CmdCtx) BackupCreateRunE(cmd *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var filename string
+
+	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+		return err
+	}
+
+	switch _, err = os.Stat(filename); {
+	case err == nil:
+		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", filename)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	}
+
+	metadata := model.BackupMetadata{
+		Version:           utils.Version(),
+		ConfigurationHash: configuration.Fingerprint(ctx.config),
+		CreatedAt:         time.Now().UTC(),
+	}
+
+	if metadata.SchemaVersion, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+		return fmt.Errorf("failed to determine the storage schema version: %w", err)
+	}
+
+	limit := 10
+
+	totp := &model.TOTPConfigurationExport{}
+
+	for page := 0; true; page++ {
+		var configs []model.TOTPConfiguration
+
+		if configs, err = ctx.providers.StorageProvider.LoadTOTPConfigurations(ctx, limit, page); err != nil {
+			return fmt.Errorf("failed to load the TOTP configurations: %w", err)
+		}
+
+		totp.TOTPConfigurations = append(totp.TOTPConfigurations, configs...)
+
+		if len(configs) < limit {
+			break
+		}
+	}
+
+	webauthn := &model.WebAuthnCredentialExport{}
+
+	for page := 0; true; page++ {
+		var credentials []model.WebAuthnCredential
+
+		if credentials, err = ctx.providers.StorageProvider.LoadWebAuthnCredentials(ctx, limit, page); err != nil {
+			return fmt.Errorf("failed to load the WebAuthn credentials: %w", err)
+		}
+
+		webauthn.WebAuthnCredentials = append(webauthn.WebAuthnCredentials, credentials...)
+
+		if len(credentials) < limit {
+			break
+		}
+	}
+
+	identifiers := &model.UserOpaqueIdentifiersExport{}
+
+	if identifiers.Identifiers, err = ctx.providers.StorageProvider.LoadUserOpaqueIdentifiers(ctx); err != nil {
+		return fmt.Errorf("failed to load the user opaque identifiers: %w", err)
+	}
+
+	metadata.TOTPConfigurations = len(totp.TOTPConfigurations)
+	metadata.WebAuthnCredentials = len(webauthn.WebAuthnCredentials)
+	metadata.UserOpaqueIdentifiers = len(identifiers.Identifiers)
+
+	var files []backupFile
+
+	for _, entry := range []struct {
+		name string
+		v    any
+	}{
+		{backupFileNameMetadata, metadata},
+		{backupFileNameTOTP, totp},
+		{backupFileNameWebAuthn, webauthn},
+		{backupFileNameIdentifiers, identifiers},
+	} {
+		var data []byte
+
+		if data, err = yaml.Marshal(entry.v); err != nil {
+			return fmt.Errorf("error occurred marshalling the '%s' file of the backup to YAML: %w", entry.name, err)
+		}
+
+		files = append(files, backupFile{name: entry.name, data: data})
+	}
+
+	var f *os.File
+
+	if f, err = os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600); err != nil {
+		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	}
+
+	if err = backupWriteArchive(f, files, metadata.CreatedAt); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
+	}
+
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("error occurred closing file '%s': %w", filename, err)
+	}
+
+	fmt.Printf("Successfully created the backup '%s' at storage schema version %d:\n", filename, metadata.SchemaVersion)
+	backupPrintMetadata(metadata)
+
+	return nil
+}
+
+// BackupRestoreRunE is the RunE for the authelia backup restore command.
+func (ctx *CmdCtx) BackupRestoreRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var force bool
+
+	if force, err = cmd.Flags().GetBool(cmdFlagNameForce); err != nil {
+		return err
+	}
+
+	filename := args[0]
+
+	var f *os.File
+
+	if f, err = os.Open(filename); err != nil {
+		return fmt.Errorf("must specify a file that exists but '%s' had an error opening it: %w", filename, err)
+	}
+
+	files, err := backupReadArchive(f)
+
+	_ = f.Close()
+
+	if err != nil {
+		return fmt.Errorf("error occurred reading the backup '%s': %w", filename, err)
+	}
+
+	var (
+		metadata    model.BackupMetadata
+		totp        model.TOTPConfigurationExport
+		webauthn    model.WebAuthnCredentialExport
+		identifiers model.UserOpaqueIdentifiersExport
+	)
+
+	for _, entry := range []struct {
+		name string
+		v    any
+	}{
+		{backupFileNameMetadata, &metadata},
+		{backupFileNameTOTP, &totp},
+		{backupFileNameWebAuthn, &webauthn},
+		{backupFileNameIdentifiers, &identifiers},
+	} {
+		data, ok := files[entry.name]
+		if !ok {
+			return fmt.Errorf("error occurred reading the backup '%s': the '%s' file is missing from the archive", filename, entry.name)
+		}
+
+		if err = yaml.Unmarshal(data, entry.v); err != nil {
+			return fmt.Errorf("error occurred reading the backup '%s': the '%s' file could not be parsed: %w", filename, entry.name, err)
+		}
+	}
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var version int
+
+	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+		return fmt.Errorf("failed to determine the storage schema version: %w", err)
+	}
+
+	if err = backupCheckSchemaVersion(metadata.SchemaVersion, version, force); err != nil {
+		return err
+	}
+
+	if hash := configuration.Fingerprint(ctx.config); hash != metadata.ConfigurationHash {
+		fmt.Printf("The configuration has changed since the backup was created, the backup configuration hash is '%s' and the current configuration hash is '%s'\n\n", metadata.ConfigurationHash, hash)
+	}
+
+	for _, config := range totp.TOTPConfigurations {
+		if err = ctx.providers.StorageProvider.SaveTOTPConfiguration(ctx, config); err != nil {
+			return fmt.Errorf("failed to restore the TOTP configuration for user '%s': %w", config.Username, err)
+		}
+	}
+
+	for _, credential := range webauthn.WebAuthnCredentials {
+		if err = ctx.providers.StorageProvider.SaveWebAuthnCredential(ctx, credential); err != nil {
+			return fmt.Errorf("failed to restore the WebAuthn credential with description '%s' for user '%s': %w", credential.Description, credential.Username, err)
+		}
+	}
+
+	for _, identifier := range identifiers.Identifiers {
+		if err = ctx.providers.StorageProvider.SaveUserOpaqueIdentifier(ctx, identifier); err != nil {
+			return fmt.Errorf("failed to restore the user opaque identifier for user '%s': %w", identifier.Username, err)
+		}
+	}
+
+	fmt.Printf("Successfully restored the backup '%s' created by version %s at %s:\n", filename, metadata.Version, metadata.CreatedAt.Format(time.RFC3339))
+	backupPrintMetadata(model.BackupMetadata{
+		TOTPConfigurations:    len(totp.TOTPConfigurations),
+		WebAuthnCredentials:   len(webauthn.WebAuthnCredentials),
+		UserOpaqueIdentifiers: len(identifiers.Identifiers),
+	})
+
+	return nil
+}
+
+func backupPrintMetadata(metadata model.BackupMetadata) {
+	fmt.Printf("\tTOTP Configurations: %d\n", metadata.TOTPConfigurations)
+	fmt.Printf("\tWebAuthn Credentials: %d\n", metadata.WebAuthnCredentials)
+	fmt.Printf("\tUser Opaque Identifiers: %d\n", metadata.UserOpaqueIdentifiers)
+}
+
+// backupCheckSchemaVersion returns an error if the schema version of the backup doesn't match the schema version of
+// the storage unless the restore is forced.
+func backupCheckSchemaVersion(backup, current int, force bool) (err error) {
+	switch {
+	case backup == current:
+		return nil
+	case force:
+		fmt.Printf("The backup was created at storage schema version %d which is different to the current storage schema version %d, restoring anyway as the --%s flag was specified\n\n", backup, current, cmdFlagNameForce)
+
+		return nil
+	default:
+		return fmt.Errorf("the backup was created at storage schema version %d which is not compatible with the current storage schema version %d, use the --%s flag to restore it anyway", backup, current, cmdFlagNameForce)
+	}
+}
+
+// backupFile is a single file within a backup archive.
+type backupFile struct {
+	name string
+	data []byte
+}
+
+// backupWriteArchive writes the files to a gzip compressed tar archive.
+func backupWriteArchive(w io.Writer, files []backupFile, modified time.Time) (err error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	for _, file := range files {
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Mode:     0600,
+			Size:     int64(len(file.data)),
+			ModTime:  modified,
+		}
+
+		if err = tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err = tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return err
+	}
+
+	return gz.Close()
+}
+
+// backupReadArchive reads the regular files from a gzip compressed tar archive, any file larger than the maximum size
+// results in an error.
+func backupReadArchive(r io.Reader) (files map[string][]byte, err error) {
+	var gz *gzip.Reader
+
+	if gz, err = gzip.NewReader(r); err != nil {
+		return nil, fmt.Errorf("the file is not a gzip compressed archive: %w", err)
+	}
+
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	files = map[string][]byte{}
+
+	for {
+		var header *tar.Header
+
+		if header, err = tr.Next(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return files, nil
+			}
+
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if header.Size > backupFileMaximumSize {
+			return nil, fmt.Errorf("the '%s' file is larger than the maximum size of %d bytes", header.Name, backupFileMaximumSize)
+		}
+
+		var data []byte
+
+		if data, err = io.ReadAll(tr); err != nil {
+			return nil, err
+		}
+
+		files[header.Name] = data
+	}
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupArchive(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	files := []backupFile{
+		{name: backupFileNameMetadata, data: []byte("version: v4.39.0\nschema_version: 16\n")},
+		{name: backupFileNameTOTP, data: []byte("totp_configurations: []\n")},
+	}
+
+	require.NoError(t, backupWriteArchive(buf, files, time.Unix(1760000000, 0)))
+
+	actual, err := backupReadArchive(bytes.NewReader(buf.Bytes()))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]byte{
+		backupFileNameMetadata: []byte("version: v4.39.0\nschema_version: 16\n"),
+		backupFileNameTOTP:     []byte("totp_configurations: []\n"),
+	}, actual)
+}
+
+func TestBackupReadArchiveShouldErrorNotGzip(t *testing.T) {
+	files, err := backupReadArchive(bytes.NewReader([]byte("totp_configurations: []\n")))
+
+	assert.EqualError(t, err, "the file is not a gzip compressed archive: gzip: invalid header")
+	assert.Nil(t, files)
+}
+
+func TestBackupReadArchiveShouldErrorTooLarge(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: backupFileNameTOTP, Mode: 0600, Size: backupFileMaximumSize + 1}))
+	require.NoError(t, gz.Close())
+
+	files, err := backupReadArchive(bytes.NewReader(buf.Bytes()))
+
+	assert.EqualError(t, err, "the 'totp.yml' file is larger than the maximum size of 67108864 bytes")
+	assert.Nil(t, files)
+}
+
+func TestBackupReadArchiveShouldSkipDirectories(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "example/", Mode: 0700}))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	files, err := backupReadArchive(bytes.NewReader(buf.Bytes()))
+
+	require.NoError(t, err)
+	assert.Len(t, files, 0)
+}
+
+func TestBackupCheckSchemaVersion(t *testing.T) {
+	assert.NoError(t, backupCheckSchemaVersion(16, 16, false))
+	assert.NoError(t, backupCheckSchemaVersion(15, 16, true))
+	assert.EqualError(t, backupCheckSchemaVersion(15, 16, false), "the backup was created at storage schema version 15 which is not compatible with the current storage schema version 16, use the --force flag to restore it anyway")
+	assert.EqualError(t, backupCheckSchemaVersion(17, 16, false), "the backup was created at storage schema version 17 which is not compatible with the current storage schema version 16, use the --force flag to restore it anyway")
+}
@@ -0,0 +1,685 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func newBenchCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "bench",
+		Short:   cmdAutheliaBenchShort,
+		Long:    cmdAutheliaBenchLong,
+		Example: cmdAutheliaBenchExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.PersistentFlags().String(cmdFlagNameURL, "", "the url of the Authelia instance")
+	cmd.PersistentFlags().Int(cmdFlagNameRequests, 100, "the total number of requests to perform")
+	cmd.PersistentFlags().Int(cmdFlagNameConcurrency, 10, "the number of requests to perform concurrently")
+	cmd.PersistentFlags().Duration(cmdFlagNameTimeout, time.Second*10, "the timeout of each request")
+
+	cmd.AddCommand(
+		newBenchFirstFactorCmd(ctx),
+		newBenchAuthzCmd(ctx),
+		newBenchOpenIDConnectCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newBenchFirstFactorCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "firstfactor",
+		Short:   cmdAutheliaBenchFirstFactorShort,
+		Long:    cmdAutheliaBenchFirstFactorLong,
+		Example: cmdAutheliaBenchFirstFactorExample,
+		RunE:    ctx.BenchFirstFactorRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameUsername, "", "the username to sign in with")
+	cmd.Flags().String(cmdFlagNamePassword, "", "the password to sign in with, prompted for if not specified")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameUsername)
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameUsername, ctx.CompletionStorageUsernamesFlag)
+
+	return cmd
+}
+
+func newBenchAuthzCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "authz",
+		Short:   cmdAutheliaBenchAuthzShort,
+		Long:    cmdAutheliaBenchAuthzLong,
+		Example: cmdAutheliaBenchAuthzExample,
+		RunE:    ctx.BenchAuthzRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameEndpoint, schema.AuthzEndpointNameForwardAuth, "the name of the authz endpoint to perform the requests against which must use the ForwardAuth implementation")
+	cmd.Flags().String(cmdFlagNameTarget, "", "the url of the protected resource the requests are for")
+	cmd.Flags().String(cmdFlagNameMethod, http.MethodGet, "the HTTP method of the requests for the protected resource")
+	cmd.Flags().String(cmdFlagNameUsername, "", "the username to sign in with before performing the requests, the requests are anonymous if not specified")
+	cmd.Flags().String(cmdFlagNamePassword, "", "the password to sign in with, prompted for if not specified")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameTarget)
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameTarget, ctx.CompletionDomainURLs)
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameUsername, ctx.CompletionStorageUsernamesFlag)
+
+	return cmd
+}
+
+func newBenchOpenIDConnectCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "oidc",
+		Short:   cmdAutheliaBenchOpenIDConnectShort,
+		Long:    cmdAutheliaBenchOpenIDConnectLong,
+		Example: cmdAutheliaBenchOpenIDConnectExample,
+		RunE:    ctx.BenchOpenIDConnectRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameClientID, "", "the id of the client which must be allowed to use the client credentials grant")
+	cmd.Flags().String(cmdFlagNameClientSecret, "", "the secret of the client, prompted for if not specified")
+	cmd.Flags().String(cmdFlagNameScope, "", "the space separated scopes to request")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameClientID)
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameClientID, ctx.CompletionClientIDs)
+
+	return cmd
+}
+
+// BenchFirstFactorRunE is the RunE for the authelia bench firstfactor command.
+func (ctx *CmdCtx) BenchFirstFactorRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		opts               *benchOptions
+		username, password string
+	)
+
+	if opts, err = benchOptionsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if username, err = cmd.Flags().GetString(cmdFlagNameUsername); err != nil {
+		return err
+	}
+
+	if password, err = benchReadSecret(cmd, cmdFlagNamePassword, "Enter Password: "); err != nil {
+		return err
+	}
+
+	fn := func(ctx context.Context) (status int, err error) {
+		var resp *http.Response
+
+		if resp, err = benchFirstFactor(ctx, opts, username, password); err != nil {
+			return 0, err
+		}
+
+		return benchDiscard(resp, http.StatusOK)
+	}
+
+	return benchRunAndReport(ctx, opts, fn)
+}
+
+// BenchAuthzRunE is the RunE for the authelia bench authz command.
+func (ctx *CmdCtx) BenchAuthzRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		opts                              *benchOptions
+		name, rawTarget, method, username string
+		target                            *url.URL
+		cookies                           []*http.Cookie
+	)
+
+	if opts, err = benchOptionsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameEndpoint); err != nil {
+		return err
+	}
+
+	if rawTarget, err = cmd.Flags().GetString(cmdFlagNameTarget); err != nil {
+		return err
+	}
+
+	if method, err = cmd.Flags().GetString(cmdFlagNameMethod); err != nil {
+		return err
+	}
+
+	if username, err = cmd.Flags().GetString(cmdFlagNameUsername); err != nil {
+		return err
+	}
+
+	if target, err = url.ParseRequestURI(rawTarget); err != nil {
+		return fmt.Errorf("failed to parse the target: %w", err)
+	}
+
+	if username != "" {
+		var password string
+
+		if password, err = benchReadSecret(cmd, cmdFlagNamePassword, "Enter Password: "); err != nil {
+			return err
+		}
+
+		if cookies, err = benchSignIn(ctx, opts, username, password); err != nil {
+			return err
+		}
+	}
+
+	endpoint := opts.baseURL.JoinPath(pathAuthz, url.PathEscape(name)).String()
+
+	fn := func(ctx context.Context) (status int, err error) {
+		var req *http.Request
+
+		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil); err != nil {
+			return 0, err
+		}
+
+		req.Header.Set(fasthttp.HeaderXForwardedProto, target.Scheme)
+		req.Header.Set(fasthttp.HeaderXForwardedHost, target.Host)
+		req.Header.Set(headerXForwardedURI, target.RequestURI())
+		req.Header.Set(headerXForwardedMethod, method)
+
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		var resp *http.Response
+
+		if resp, err = opts.client.Do(req); err != nil {
+			return 0, err
+		}
+
+		return benchDiscard(resp, http.StatusOK, http.StatusFound, http.StatusSeeOther, http.StatusUnauthorized, http.StatusForbidden)
+	}
+
+	return benchRunAndReport(ctx, opts, fn)
+}
+
+// BenchOpenIDConnectRunE is the RunE for the authelia bench oidc command.
+func (ctx *CmdCtx) BenchOpenIDConnectRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		opts              *benchOptions
+		id, secret, scope string
+	)
+
+	if opts, err = benchOptionsFromCmd(cmd); err != nil {
+		return err
+	}
+
+	if id, err = cmd.Flags().GetString(cmdFlagNameClientID); err != nil {
+		return err
+	}
+
+	if secret, err = benchReadSecret(cmd, cmdFlagNameClientSecret, "Enter Client Secret: "); err != nil {
+		return err
+	}
+
+	if scope, err = cmd.Flags().GetString(cmdFlagNameScope); err != nil {
+		return err
+	}
+
+	form := url.Values{"grant_type": []string{oidc.GrantTypeClientCredentials}}
+
+	if scope != "" {
+		form.Set(oidc.FormParameterScope, scope)
+	}
+
+	endpoint, body := opts.baseURL.JoinPath(oidc.EndpointPathToken).String(), form.Encode()
+
+	fn := func(ctx context.Context) (status int, err error) {
+		var req *http.Request
+
+		if req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(body)); err != nil {
+			return 0, err
+		}
+
+		req.Header.Set(fasthttp.HeaderContentType, "application/x-www-form-urlencoded")
+		req.SetBasicAuth(url.QueryEscape(id), url.QueryEscape(secret))
+
+		var resp *http.Response
+
+		if resp, err = opts.client.Do(req); err != nil {
+			return 0, err
+		}
+
+		return benchDiscard(resp, http.StatusOK)
+	}
+
+	return benchRunAndReport(ctx, opts, fn)
+}
+
+// benchOptions are the options shared by all of the bench subcommands.
+type benchOptions struct {
+	client      *http.Client
+	baseURL     *url.URL
+	requests    int
+	concurrency int
+}
+
+func benchOptionsFromCmd(cmd *cobra.Command) (opts *benchOptions, err error) {
+	var (
+		rawURL  string
+		timeout time.Duration
+	)
+
+	opts = &benchOptions{}
+
+	if rawURL, err = cmd.Flags().GetString(cmdFlagNameURL); err != nil {
+		return nil, err
+	}
+
+	if opts.requests, err = cmd.Flags().GetInt(cmdFlagNameRequests); err != nil {
+		return nil, err
+	}
+
+	if opts.concurrency, err = cmd.Flags().GetInt(cmdFlagNameConcurrency); err != nil {
+		return nil, err
+	}
+
+	if timeout, err = cmd.Flags().GetDuration(cmdFlagNameTimeout); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case rawURL == "":
+		return nil, fmt.Errorf("the url must be specified using the --%s flag", cmdFlagNameURL)
+	case opts.requests < 1:
+		return nil, fmt.Errorf("the number of requests must be at least 1 but it's configured as %d", opts.requests)
+	case opts.concurrency < 1:
+		return nil, fmt.Errorf("the concurrency must be at least 1 but it's configured as %d", opts.concurrency)
+	}
+
+	if opts.baseURL, err = url.ParseRequestURI(rawURL); err != nil {
+		return nil, fmt.Errorf("failed to parse the url: %w", err)
+	}
+
+	if opts.concurrency > opts.requests {
+		opts.concurrency = opts.requests
+	}
+
+	opts.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConnsPerHost: opts.concurrency,
+		},
+		CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return opts, nil
+}
+
+func benchReadSecret(cmd *cobra.Command, flag, prompt string) (secret string, err error) {
+	if cmd.Flags().Changed(flag) {
+		return cmd.Flags().GetString(flag)
+	}
+
+	return termReadPasswordWithPrompt(prompt, flag)
+}
+
+func benchFirstFactor(ctx context.Context, opts *benchOptions, username, password string) (resp *http.Response, err error) {
+	var data []byte
+
+	if data, err = json.Marshal(map[string]any{"username": username, "password": password, "keepMeLoggedIn": false}); err != nil {
+		return nil, err
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, opts.baseURL.JoinPath(pathFirstFactor).String(), bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	req.Header.Set(fasthttp.HeaderContentType, "application/json")
+
+	return opts.client.Do(req)
+}
+
+// benchSignIn performs the first factor authentication once and returns the cookies set by the response.
+func benchSignIn(ctx context.Context, opts *benchOptions, username, password string) (cookies []*http.Cookie, err error) {
+	var resp *http.Response
+
+	if resp, err = benchFirstFactor(ctx, opts, username, password); err != nil {
+		return nil, fmt.Errorf("failed to sign in as '%s': %w", username, err)
+	}
+
+	if _, err = benchDiscard(resp, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("failed to sign in as '%s': %w", username, err)
+	}
+
+	if cookies = resp.Cookies(); len(cookies) == 0 {
+		return nil, fmt.Errorf("failed to sign in as '%s': the response did not set a session cookie", username)
+	}
+
+	return cookies, nil
+}
+
+// benchDiscard reads and closes the body of the response so the connection can be reused, and returns an error if the
+// status code is not one of the expected status codes.
+func benchDiscard(resp *http.Response, expected ...int) (status int, err error) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return resp.StatusCode, nil
+		}
+	}
+
+	return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+}
+
+// benchRequestFunc performs a single request of a benchmark and returns the status code of the response.
+type benchRequestFunc func(ctx context.Context) (status int, err error)
+
+// benchResult is the outcome of a single request of a benchmark.
+type benchResult struct {
+	latency time.Duration
+	status  int
+	err     error
+}
+
+func benchRunAndReport(ctx context.Context, opts *benchOptions, fn benchRequestFunc) (err error) {
+	fmt.Printf("Performing %d requests against '%s' with a concurrency of %d\n\n", opts.requests, opts.baseURL, opts.concurrency)
+
+	results, elapsed := benchRun(ctx, opts.requests, opts.concurrency, fn)
+
+	report := newBenchReport(results, elapsed, opts.concurrency)
+
+	if err = benchWriteReport(os.Stdout, report); err != nil {
+		return err
+	}
+
+	if report.Successful == 0 {
+		return errors.New("none of the requests were successful")
+	}
+
+	return nil
+}
+
+// benchRun performs the requests using the number of concurrent workers and returns the result of each request in the
+// order they were started, along with the total time taken. Requests which haven't started when the context is done
+// are not performed.
+func benchRun(ctx context.Context, requests, concurrency int, fn benchRequestFunc) (results []benchResult, elapsed time.Duration) {
+	results = make([]benchResult, 0, requests)
+
+	jobs := make(chan struct{})
+	out := make(chan benchResult)
+
+	wg := &sync.WaitGroup{}
+
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range jobs {
+				begin := time.Now()
+
+				status, err := fn(ctx)
+
+				out <- benchResult{latency: time.Since(begin), status: status, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for i := 0; i < requests; i++ {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- struct{}{}:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+
+		close(out)
+	}()
+
+	for result := range out {
+		results = append(results, result)
+	}
+
+	return results, time.Since(start)
+}
+
+// benchBucket is a bucket of the latency histogram of a benchmark which counts the requests with a latency less than or
+// equal to the bound and greater than the bound of the previous bucket. The last bucket has no bound.
+type benchBucket struct {
+	Bound time.Duration
+	Count int
+}
+
+// benchReport is the summary of the results of a benchmark.
+type benchReport struct {
+	Requests    int
+	Successful  int
+	Failed      int
+	Concurrency int
+	Elapsed     time.Duration
+	Throughput  float64
+	Statuses    map[int]int
+	Errors      map[string]int
+
+	Min, Mean, P50, P90, P95, P99, Max time.Duration
+
+	Histogram []benchBucket
+}
+
+// newBenchReport summarises the results. The latency statistics only include the successful requests.
+func newBenchReport(results []benchResult, elapsed time.Duration, concurrency int) (report benchReport) {
+	report = benchReport{
+		Requests:    len(results),
+		Concurrency: concurrency,
+		Elapsed:     elapsed,
+		Statuses:    map[int]int{},
+		Errors:      map[string]int{},
+		Histogram:   make([]benchBucket, len(benchHistogramBounds)+1),
+	}
+
+	for i, bound := range benchHistogramBounds {
+		report.Histogram[i].Bound = bound
+	}
+
+	var (
+		latencies []time.Duration
+		total     time.Duration
+	)
+
+	for _, result := range results {
+		if result.status != 0 {
+			report.Statuses[result.status]++
+		}
+
+		if result.err != nil {
+			report.Failed++
+			report.Errors[result.err.Error()]++
+
+			continue
+		}
+
+		report.Successful++
+
+		latencies = append(latencies, result.latency)
+		total += result.latency
+
+		report.Histogram[sort.Search(len(benchHistogramBounds), func(i int) bool {
+			return result.latency <= benchHistogramBounds[i]
+		})].Count++
+	}
+
+	if elapsed > 0 {
+		report.Throughput = float64(report.Successful) / elapsed.Seconds()
+	}
+
+	if len(latencies) == 0 {
+		return report
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	report.Min, report.Max = latencies[0], latencies[len(latencies)-1]
+	report.Mean = total / time.Duration(len(latencies))
+	report.P50 = benchPercentile(latencies, 50)
+	report.P90 = benchPercentile(latencies, 90)
+	report.P95 = benchPercentile(latencies, 95)
+	report.P99 = benchPercentile(latencies, 99)
+
+	return report
+}
+
+// benchPercentile returns the percentile of the sorted latencies using the nearest rank method.
+func benchPercentile(sorted []time.Duration, percentile float64) time.Duration {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func benchWriteReport(out io.Writer, report benchReport) (err error) {
+	w := tabwriter.NewWriter(out, 1, 1, 4, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "Requests:\t%d (%d successful, %d failed)\n", report.Requests, report.Successful, report.Failed)
+	_, _ = fmt.Fprintf(w, "Concurrency:\t%d\n", report.Concurrency)
+	_, _ = fmt.Fprintf(w, "Duration:\t%s\n", report.Elapsed.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(w, "Throughput:\t%.2f requests/second\n", report.Throughput)
+
+	if len(report.Statuses) != 0 {
+		codes := make([]int, 0, len(report.Statuses))
+
+		for code := range report.Statuses {
+			codes = append(codes, code)
+		}
+
+		sort.Ints(codes)
+
+		_, _ = fmt.Fprintln(w, "\nStatus Codes:")
+
+		for _, code := range codes {
+			_, _ = fmt.Fprintf(w, "    %d %s:\t%d\n", code, http.StatusText(code), report.Statuses[code])
+		}
+	}
+
+	if len(report.Errors) != 0 {
+		messages := make([]string, 0, len(report.Errors))
+
+		for message := range report.Errors {
+			messages = append(messages, message)
+		}
+
+		sort.Slice(messages, func(i, j int) bool {
+			if report.Errors[messages[i]] == report.Errors[messages[j]] {
+				return messages[i] < messages[j]
+			}
+
+			return report.Errors[messages[i]] > report.Errors[messages[j]]
+		})
+
+		_, _ = fmt.Fprintln(w, "\nErrors:")
+
+		for _, message := range messages {
+			_, _ = fmt.Fprintf(w, "    %s:\t%d\n", message, report.Errors[message])
+		}
+	}
+
+	if report.Successful != 0 {
+		_, _ = fmt.Fprintln(w, "\nLatency:")
+
+		for _, stat := range []struct {
+			name  string
+			value time.Duration
+		}{
+			{"Min", report.Min}, {"Mean", report.Mean}, {"P50", report.P50}, {"P90", report.P90},
+			{"P95", report.P95}, {"P99", report.P99}, {"Max", report.Max},
+		} {
+			_, _ = fmt.Fprintf(w, "    %s:\t%s\n", stat.name, benchFormatLatency(stat.value))
+		}
+
+		_, _ = fmt.Fprintln(w, "\nHistogram:")
+
+		first, last, largest := -1, 0, 0
+
+		for i, bucket := range report.Histogram {
+			if bucket.Count == 0 {
+				continue
+			}
+
+			if first == -1 {
+				first = i
+			}
+
+			last = i
+
+			if bucket.Count > largest {
+				largest = bucket.Count
+			}
+		}
+
+		for _, bucket := range report.Histogram[first : last+1] {
+			bound := "> " + benchFormatLatency(benchHistogramBounds[len(benchHistogramBounds)-1])
+
+			if bucket.Bound != 0 {
+				bound = "<= " + benchFormatLatency(bucket.Bound)
+			}
+
+			_, _ = fmt.Fprintf(w, "    %s\t%d\t%s\n", bound, bucket.Count, strings.Repeat("#", int(math.Ceil(float64(bucket.Count)/float64(largest)*benchHistogramWidth))))
+		}
+	}
+
+	return w.Flush()
+}
+
+func benchFormatLatency(latency time.Duration) string {
+	switch {
+	case latency >= time.Second:
+		return latency.Round(time.Millisecond).String()
+	case latency >= time.Millisecond:
+		return latency.Round(time.Microsecond * 100).String()
+	default:
+		return latency.Round(time.Microsecond).String()
+	}
+}
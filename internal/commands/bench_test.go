@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchRun(t *testing.T) {
+	var count atomic.Int64
+
+	results, elapsed := benchRun(context.Background(), 25, 5, func(ctx context.Context) (status int, err error) {
+		if count.Add(1)%5 == 0 {
+			return http.StatusServiceUnavailable, errors.New("unexpected status code 503")
+		}
+
+		return http.StatusOK, nil
+	})
+
+	assert.Len(t, results, 25)
+	assert.Equal(t, int64(25), count.Load())
+	assert.Greater(t, elapsed, time.Duration(0))
+
+	report := newBenchReport(results, elapsed, 5)
+
+	assert.Equal(t, 25, report.Requests)
+	assert.Equal(t, 20, report.Successful)
+	assert.Equal(t, 5, report.Failed)
+	assert.Equal(t, map[int]int{http.StatusOK: 20, http.StatusServiceUnavailable: 5}, report.Statuses)
+	assert.Equal(t, map[string]int{"unexpected status code 503": 5}, report.Errors)
+}
+
+func TestBenchRunShouldStopWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var count atomic.Int64
+
+	results, _ := benchRun(ctx, 100, 1, func(ctx context.Context) (status int, err error) {
+		if count.Add(1) == 10 {
+			cancel()
+		}
+
+		return http.StatusOK, nil
+	})
+
+	assert.GreaterOrEqual(t, len(results), 10)
+	assert.Less(t, len(results), 100)
+}
+
+func TestBenchPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, time.Millisecond, benchPercentile(latencies, 0))
+	assert.Equal(t, time.Millisecond*50, benchPercentile(latencies, 50))
+	assert.Equal(t, time.Millisecond*99, benchPercentile(latencies, 99))
+	assert.Equal(t, time.Millisecond*100, benchPercentile(latencies, 100))
+	assert.Equal(t, time.Second, benchPercentile([]time.Duration{time.Second}, 95))
+}
+
+func TestNewBenchReport(t *testing.T) {
+	results := []benchResult{
+		{latency: time.Microsecond * 500, status: http.StatusOK},
+		{latency: time.Millisecond * 3, status: http.StatusOK},
+		{latency: time.Millisecond * 4, status: http.StatusOK},
+		{latency: time.Millisecond * 45, status: http.StatusFound},
+		{latency: time.Second * 7, status: http.StatusOK},
+		{latency: time.Millisecond * 10, err: errors.New("context deadline exceeded")},
+	}
+
+	report := newBenchReport(results, time.Second*10, 2)
+
+	assert.Equal(t, 6, report.Requests)
+	assert.Equal(t, 5, report.Successful)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 0.5, report.Throughput)
+	assert.Equal(t, map[int]int{http.StatusOK: 4, http.StatusFound: 1}, report.Statuses)
+	assert.Equal(t, map[string]int{"context deadline exceeded": 1}, report.Errors)
+
+	assert.Equal(t, time.Microsecond*500, report.Min)
+	assert.Equal(t, time.Second*7, report.Max)
+	assert.Equal(t, time.Millisecond*4, report.P50)
+	assert.Equal(t, time.Second*7, report.P99)
+	assert.Equal(t, (time.Second*7+time.Millisecond*52+time.Microsecond*500)/5, report.Mean)
+
+	require.Len(t, report.Histogram, len(benchHistogramBounds)+1)
+
+	assert.Equal(t, benchBucket{Bound: time.Millisecond, Count: 1}, report.Histogram[0])
+	assert.Equal(t, benchBucket{Bound: time.Millisecond * 5, Count: 2}, report.Histogram[2])
+	assert.Equal(t, benchBucket{Bound: time.Millisecond * 50, Count: 1}, report.Histogram[5])
+	assert.Equal(t, benchBucket{Count: 1}, report.Histogram[len(benchHistogramBounds)])
+}
+
+func TestBenchWriteReport(t *testing.T) {
+	results := []benchResult{
+		{latency: time.Millisecond * 3, status: http.StatusOK},
+		{latency: time.Millisecond * 4, status: http.StatusOK},
+		{latency: time.Millisecond * 15, status: http.StatusOK},
+		{status: http.StatusTooManyRequests, err: errors.New("unexpected status code 429")},
+	}
+
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, benchWriteReport(buf, newBenchReport(results, time.Second, 4)))
+
+	assert.Equal(t, `Requests:       4 (3 successful, 1 failed)
+Concurrency:    4
+Duration:       1s
+Throughput:     3.00 requests/second
+
+Status Codes:
+    200 OK:                   3
+    429 Too Many Requests:    1
+
+Errors:
+    unexpected status code 429:    1
+
+Latency:
+    Min:     3ms
+    Mean:    7.3ms
+    P50:     4ms
+    P90:     15ms
+    P95:     15ms
+    P99:     15ms
+    Max:     15ms
+
+Histogram:
+    <= 5ms     2    ########################################
+`+"    <= 10ms    0    \n"+`    <= 20ms    1    ####################
+`, buf.String())
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// completionDynamicEnabled returns true if the dynamic completions are enabled. The dynamic completions load the
+// configuration and connect to the storage every time a completion is requested so they're opt-in.
+func completionDynamicEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(cmdFlagEnvNameCompletionDynamic))
+
+	return enabled
+}
+
+// CompletionStorageUsernames is a cobra completion function which completes the usernames known to the storage for
+// the first positional argument.
+func (ctx *CmdCtx) CompletionStorageUsernames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return ctx.CompletionStorageUsernamesFlag(cmd, args, toComplete)
+}
+
+// CompletionStorageUsernamesFlag is a cobra completion function which completes the usernames known to the storage.
+func (ctx *CmdCtx) CompletionStorageUsernamesFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionDynamicEnabled() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	usernames, err := ctx.completionStorageUsernames(cmd, args)
+	if err != nil {
+		cobra.CompDebugln("failed to load the usernames from the storage: "+err.Error(), true)
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completionFilterList(usernames, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompletionClientIDs is a cobra completion function which completes the OpenID Connect 1.0 client ids from the
+// configuration.
+func (ctx *CmdCtx) CompletionClientIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionDynamicEnabled() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if err := ctx.HelperConfigLoadRunE(cmd, args); err != nil {
+		cobra.CompDebugln("failed to load the configuration: "+err.Error(), true)
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completionFilter(completionClientIDsFromConfig(ctx.config), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompletionDomains is a cobra completion function which completes the domains from the configuration.
+func (ctx *CmdCtx) CompletionDomains(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionDynamicEnabled() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if err := ctx.HelperConfigLoadRunE(cmd, args); err != nil {
+		cobra.CompDebugln("failed to load the configuration: "+err.Error(), true)
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return completionFilterList(completionDomainsFromConfig(ctx.config), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompletionDomainURLs is a cobra completion function which completes the URLs of the domains from the configuration.
+func (ctx *CmdCtx) CompletionDomainURLs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if !completionDynamicEnabled() {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if err := ctx.HelperConfigLoadRunE(cmd, args); err != nil {
+		cobra.CompDebugln("failed to load the configuration: "+err.Error(), true)
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	domains := completionDomainsFromConfig(ctx.config)
+
+	urls := make([]string, len(domains))
+
+	for i, domain := range domains {
+		urls[i] = "https://" + domain + "/"
+	}
+
+	return completionFilter(urls, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+func (ctx *CmdCtx) completionStorageUsernames(cmd *cobra.Command, args []string) (usernames []string, err error) {
+	if err = ctx.ChainRunE(
+		ctx.ConfigStorageCommandLineConfigRunE,
+		ctx.HelperConfigLoadRunE,
+		ctx.ConfigValidateStorageRunE,
+		ctx.LoadProvidersStorageRunE,
+	)(cmd, args); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchemaVersion(); err != nil {
+		return nil, err
+	}
+
+	limit := 100
+
+	var summaries []model.UserSummary
+
+	for page := 0; true; page++ {
+		if summaries, err = ctx.providers.StorageProvider.LoadUserSummaries(ctx, limit, page); err != nil {
+			return nil, err
+		}
+
+		for _, summary := range summaries {
+			usernames = append(usernames, summary.Username)
+		}
+
+		if len(summaries) < limit {
+			break
+		}
+	}
+
+	return usernames, nil
+}
+
+// completionClientIDsFromConfig returns the ids of the registered OpenID Connect 1.0 clients.
+func completionClientIDsFromConfig(config *schema.Configuration) (ids []string) {
+	if config.IdentityProviders.OIDC == nil {
+		return nil
+	}
+
+	for _, client := range config.IdentityProviders.OIDC.Clients {
+		ids = append(ids, client.ID)
+	}
+
+	return ids
+}
+
+// completionDomainsFromConfig returns the session cookie domains and the literal access control rule domains, the
+// domains which contain wildcards or placeholders are excluded as they can't be completed as is.
+func completionDomainsFromConfig(config *schema.Configuration) (domains []string) {
+	for _, cookie := range config.Session.Cookies {
+		domains = append(domains, cookie.Domain)
+	}
+
+	for _, rule := range config.AccessControl.Rules {
+		for _, domain := range rule.Domains {
+			if strings.ContainsAny(domain, "*{}") {
+				continue
+			}
+
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// completionFilterList is like completionFilter but for flags which accept comma separated values, only the value after
+// the last comma is completed and the values before it are retained.
+func completionFilterList(values []string, toComplete string) (completions []string) {
+	i := strings.LastIndex(toComplete, ",")
+
+	completions = completionFilter(values, toComplete[i+1:])
+
+	if i == -1 {
+		return completions
+	}
+
+	for j, completion := range completions {
+		completions[j] = toComplete[:i+1] + completion
+	}
+
+	return completions
+}
+
+// completionFilter returns the sorted unique non-empty values which have the prefix.
+func completionFilter(values []string, prefix string) (completions []string) {
+	seen := map[string]struct{}{}
+
+	for _, value := range values {
+		if value == "" || !strings.HasPrefix(value, prefix) {
+			continue
+		}
+
+		if _, ok := seen[value]; ok {
+			continue
+		}
+
+		seen[value] = struct{}{}
+
+		completions = append(completions, value)
+	}
+
+	sort.Strings(completions)
+
+	return completions
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestCompletionFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		values   []string
+		prefix   string
+		expected []string
+	}{
+		{"ShouldReturnAllSorted", []string{"john", "harry", "bob"}, "", []string{"bob", "harry", "john"}},
+		{"ShouldFilterPrefix", []string{"john", "jane", "bob"}, "j", []string{"jane", "john"}},
+		{"ShouldRemoveDuplicatesAndEmpty", []string{"john", "", "john"}, "", []string{"john"}},
+		{"ShouldReturnNilWhenNoMatch", []string{"john"}, "x", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, completionFilter(tc.values, tc.prefix))
+		})
+	}
+}
+
+func TestCompletionFilterList(t *testing.T) {
+	values := []string{"example.com", "example.org", "other.com"}
+
+	assert.Equal(t, []string{"example.com", "example.org"}, completionFilterList(values, "ex"))
+	assert.Equal(t, []string{"other.com,example.com", "other.com,example.org"}, completionFilterList(values, "other.com,ex"))
+	assert.Equal(t, []string{"a,b,other.com"}, completionFilterList(values, "a,b,o"))
+	assert.Nil(t, completionFilterList(values, "a,x"))
+}
+
+func TestCompletionDomainsFromConfig(t *testing.T) {
+	config := &schema.Configuration{
+		Session: schema.Session{
+			Cookies: []schema.SessionCookie{
+				{Domain: "example.com"},
+			},
+		},
+		AccessControl: schema.AccessControl{
+			Rules: []schema.AccessControlRule{
+				{Domains: []string{"app.example.com", "*.example.com", "{user}.example.com"}},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"example.com", "app.example.com"}, completionDomainsFromConfig(config))
+}
+
+func TestCompletionClientIDsFromConfig(t *testing.T) {
+	assert.Nil(t, completionClientIDsFromConfig(&schema.Configuration{}))
+
+	config := &schema.Configuration{
+		IdentityProviders: schema.IdentityProviders{
+			OIDC: &schema.IdentityProvidersOpenIDConnect{
+				Clients: []schema.IdentityProvidersOpenIDConnectClient{{ID: "app"}, {ID: "cli"}},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{"app", "cli"}, completionClientIDsFromConfig(config))
+}
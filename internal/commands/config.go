@@ -2,13 +2,21 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 
 	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/kv"
+	"github.com/authelia/authelia/v4/internal/sops"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func newConfigCmd(ctx *CmdCtx) (cmd *cobra.Command) {
@@ -28,7 +36,7 @@ func newConfigCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
-	cmd.AddCommand(newConfigValidateCmd(ctx), newConfigTemplateCmd(ctx))
+	cmd.AddCommand(newConfigValidateCmd(ctx), newConfigTemplateCmd(ctx), newConfigSchemaCmd(ctx), newConfigMigrateCmd(ctx))
 
 	return cmd
 }
@@ -53,6 +61,38 @@ func newConfigTemplateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newConfigSchemaCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "schema",
+		Short:   cmdAutheliaConfigSchemaShort,
+		Long:    cmdAutheliaConfigSchemaLong,
+		Example: cmdAutheliaConfigSchemaExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.ConfigSchemaRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newConfigMigrateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "migrate",
+		Short:   cmdAutheliaConfigMigrateShort,
+		Long:    cmdAutheliaConfigMigrateLong,
+		Example: cmdAutheliaConfigMigrateExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.ConfigMigrateRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().BoolP(cmdFlagNameWrite, "w", false, "writes the migrated configuration to the configuration files instead of only printing the differences")
+
+	return cmd
+}
+
 func newConfigValidateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "validate",
@@ -62,7 +102,7 @@ func newConfigValidateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		Args:    cobra.NoArgs,
 		PreRunE: ctx.ChainRunE(
 			ctx.HelperConfigLoadRunE,
-			ctx.HelperConfigValidateKeysRunE,
+			ctx.ConfigValidateKeysRunE,
 			ctx.HelperConfigValidateRunE,
 		),
 		RunE: ctx.ConfigValidateRunE,
@@ -70,13 +110,41 @@ func newConfigValidateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
+	cmd.Flags().Bool(cmdFlagNameStrict, false, "rejects unexpected environment variables and warnings, and reports the position of unexpected keys")
+
 	return cmd
 }
 
+// ConfigValidateKeysRunE validates the configuration keys using the strict validation if the strict flag is set.
+func (ctx *CmdCtx) ConfigValidateKeysRunE(cmd *cobra.Command, args []string) (err error) {
+	var strict bool
+
+	if strict, err = cmd.Flags().GetBool(cmdFlagNameStrict); err != nil {
+		return err
+	}
+
+	if !strict {
+		return ctx.HelperConfigValidateKeysRunE(cmd, args)
+	}
+
+	var positions map[string][]string
+
+	if positions, err = configuration.GetFileKeyPositions(ctx.cconfig.sources...); err != nil {
+		return err
+	}
+
+	validator.ValidateKeysStrict(ctx.cconfig.keys, configuration.GetMultiKeyMappedDeprecationKeys(), configuration.DefaultEnvPrefix, positions, ctx.cconfig.validator)
+
+	return nil
+}
+
 // ConfigValidateRunE is the RunE for the authelia validate-config command.
-func (ctx *CmdCtx) ConfigValidateRunE(_ *cobra.Command, _ []string) (err error) {
+func (ctx *CmdCtx) ConfigValidateRunE(cmd *cobra.Command, _ []string) (err error) {
 	var isError bool
 
+	// The strict flag is not defined on all commands using this RunE, in which case strict is always false.
+	strict, _ := cmd.Flags().GetBool(cmdFlagNameStrict)
+
 	buf := &bytes.Buffer{}
 
 	switch {
@@ -97,6 +165,10 @@ func (ctx *CmdCtx) ConfigValidateRunE(_ *cobra.Command, _ []string) (err error)
 
 		fallthrough
 	case ctx.cconfig.validator.HasWarnings():
+		if strict {
+			isError = true
+		}
+
 		_, _ = fmt.Fprintf(buf, "Configuration parsed and loaded with warnings:\n\n")
 
 		for _, err = range ctx.cconfig.validator.Warnings() {
@@ -117,6 +189,179 @@ func (ctx *CmdCtx) ConfigValidateRunE(_ *cobra.Command, _ []string) (err error)
 	return nil
 }
 
+// ConfigSchemaRunE is the RunE for the authelia config schema command.
+func (ctx *CmdCtx) ConfigSchemaRunE(_ *cobra.Command, _ []string) (err error) {
+	var data []byte
+
+	if data, err = json.MarshalIndent(configuration.NewJSONSchema(utils.BuildTag), "", "  "); err != nil {
+		return fmt.Errorf("error occurred marshalling the configuration json schema: %w", err)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
+
+// ConfigMigrateRunE is the RunE for the authelia config migrate command.
+func (ctx *CmdCtx) ConfigMigrateRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		paths, files []string
+		write        bool
+	)
+
+	if paths, _, err = loadXEnvCLIConfigValues(cmd); err != nil {
+		return err
+	}
+
+	if write, err = cmd.Flags().GetBool(cmdFlagNameWrite); err != nil {
+		return err
+	}
+
+	if files, err = configMigrateFilePaths(paths); err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("migrating requires configuration files however no configuration file sources were specified")
+	}
+
+	for _, file := range files {
+		if err = configMigrateFile(file, write); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func configMigrateFile(path string, write bool) (err error) {
+	var (
+		info           os.FileInfo
+		data, migrated []byte
+	)
+
+	if info, err = os.Stat(path); err != nil {
+		return fmt.Errorf("error occurred migrating file '%s': %w", path, err)
+	}
+
+	if data, err = os.ReadFile(path); err != nil {
+		return fmt.Errorf("error occurred migrating file '%s': %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case extTOML, extJSON:
+		return fmt.Errorf("error occurred migrating file '%s': only YAML files can be migrated", path)
+	}
+
+	if sops.IsEncrypted(data) {
+		return fmt.Errorf("error occurred migrating file '%s': encrypted files must be decrypted before they can be migrated", path)
+	}
+
+	val := schema.NewStructValidator()
+
+	if migrated, err = configuration.Migrate(data, val); err != nil {
+		return fmt.Errorf("error occurred migrating file '%s': %w", path, err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if !val.HasWarnings() && !val.HasErrors() {
+		_, _ = fmt.Fprintf(buf, "Configuration file '%s' does not have any deprecated keys.\n\n", path)
+	}
+
+	if val.HasWarnings() {
+		_, _ = fmt.Fprintf(buf, "Configuration file '%s' has deprecated keys which can be migrated:\n\n", path)
+
+		for _, warning := range val.Warnings() {
+			_, _ = fmt.Fprintf(buf, "\t - %v\n", warning)
+		}
+
+		_, _ = fmt.Fprint(buf, "\n")
+	}
+
+	if val.HasErrors() {
+		_, _ = fmt.Fprintf(buf, "Configuration file '%s' has deprecated keys which must be migrated manually:\n\n", path)
+
+		for _, e := range val.Errors() {
+			_, _ = fmt.Fprintf(buf, "\t - %v\n", e)
+		}
+
+		_, _ = fmt.Fprint(buf, "\n")
+	}
+
+	if !bytes.Equal(data, migrated) {
+		var diff string
+
+		if diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(data)),
+			B:        difflib.SplitLines(string(migrated)),
+			FromFile: path,
+			ToFile:   path + " (migrated)",
+			Context:  3,
+		}); err != nil {
+			return fmt.Errorf("error occurred migrating file '%s': error occurred generating the differences: %w", path, err)
+		}
+
+		buf.WriteString(diff + "\n")
+
+		if write {
+			if err = os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("error occurred migrating file '%s': %w", path, err)
+			}
+
+			_, _ = fmt.Fprintf(buf, "Configuration file '%s' has been migrated.\n\n", path)
+		} else {
+			_, _ = fmt.Fprintf(buf, "Configuration file '%s' has not been modified, use the '--%s' flag to write the migrated configuration.\n\n", path, cmdFlagNameWrite)
+		}
+	}
+
+	fmt.Print(buf.String())
+
+	return nil
+}
+
+// configMigrateFilePaths returns the paths of the configuration files including the YAML files within configuration
+// directories, excluding key value store references.
+func configMigrateFilePaths(paths []string) (files []string, err error) {
+	var (
+		info    os.FileInfo
+		entries []os.DirEntry
+	)
+
+	for _, path := range paths {
+		if kv.IsReference(path) {
+			continue
+		}
+
+		if info, err = os.Stat(path); err != nil {
+			return nil, fmt.Errorf("error occurred stating file at path '%s': %w", path, err)
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+
+			continue
+		}
+
+		if entries, err = os.ReadDir(path); err != nil {
+			return nil, fmt.Errorf("error occurred reading directory at path '%s': %w", path, err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+
+			switch filepath.Ext(entry.Name()) {
+			case extYML, extYAML:
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	return files, nil
+}
+
 // ConfigTemplateRunE is the RunE for the authelia validate-config command.
 func (ctx *CmdCtx) ConfigTemplateRunE(_ *cobra.Command, _ []string) (err error) {
 	var (
@@ -15,21 +15,32 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/branding"
+	"github.com/authelia/authelia/v4/internal/breaker"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/duo"
+	"github.com/authelia/authelia/v4/internal/election"
+	"github.com/authelia/authelia/v4/internal/health"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/metrics"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/ntp"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/pki"
+	"github.com/authelia/authelia/v4/internal/push"
 	"github.com/authelia/authelia/v4/internal/random"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/saml"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/sms"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/templates"
 	"github.com/authelia/authelia/v4/internal/totp"
@@ -61,6 +72,8 @@ type CmdCtx struct {
 	trusted   *x509.CertPool
 
 	cconfig *CmdCtxConfig
+	reload  *ConfigReloader
+	elector *election.Elector
 }
 
 // NewCmdCtxConfig returns a new CmdCtxConfig.
@@ -74,6 +87,7 @@ func NewCmdCtxConfig() *CmdCtxConfig {
 type CmdCtxConfig struct {
 	files     []string
 	filters   []string
+	lists     configuration.ListMergeStrategy
 	defaults  configuration.Source
 	sources   []configuration.Source
 	keys      []string
@@ -88,7 +102,7 @@ func (ctx *CmdCtx) CheckSchemaVersion() (err error) {
 		return fmt.Errorf("storage not loaded")
 	}
 
-	var version, latest int
+	var version, latest, compatible int
 
 	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
 		return err
@@ -100,6 +114,14 @@ func (ctx *CmdCtx) CheckSchemaVersion() (err error) {
 
 	switch {
 	case version > latest:
+		if compatible, err = ctx.providers.StorageProvider.SchemaCompatibleVersion(ctx); err != nil {
+			return err
+		}
+
+		if compatible <= latest {
+			return nil
+		}
+
 		return fmt.Errorf("%w: version %d is not compatible with this version of the binary as the latest compatible version is %d", errStorageSchemaIncompatible, version, latest)
 	case version < latest:
 		return fmt.Errorf("%w: version %d is outdated please migrate to version %d in order to use this command or use an older binary", errStorageSchemaOutdated, version, latest)
@@ -147,8 +169,12 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 	ctx.providers.NTP = ntp.NewProvider(&ctx.config.NTP)
 	ctx.providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(ctx.config.PasswordPolicy)
 	ctx.providers.Regulator = regulation.NewRegulator(ctx.config.Regulation, ctx.providers.StorageProvider, clock.New())
-	ctx.providers.SessionProvider = session.NewProvider(ctx.config.Session, ctx.trusted)
-	ctx.providers.TOTP = totp.NewTimeBasedProvider(ctx.config.TOTP)
+	ctx.providers.SessionProvider = session.NewProvider(ctx.config.Session, ctx.trusted, ctx.providers.StorageProvider)
+
+	totpProvider := totp.NewTimeBasedProvider(ctx.config.TOTP)
+	totpProvider.SetClockSkewProvider(ctx.providers.NTP)
+
+	ctx.providers.TOTP = totpProvider
 
 	var err error
 
@@ -156,29 +182,159 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 	case ctx.config.AuthenticationBackend.File != nil:
 		ctx.providers.UserProvider = authentication.NewFileUserProvider(ctx.config.AuthenticationBackend.File)
 	case ctx.config.AuthenticationBackend.LDAP != nil:
-		ctx.providers.UserProvider = authentication.NewLDAPUserProvider(ctx.config.AuthenticationBackend, ctx.trusted)
+		provider := authentication.NewLDAPUserProvider(ctx.config.AuthenticationBackend, ctx.trusted)
+		provider.SetCircuitBreaker(breaker.New(&ctx.config.CircuitBreaker, schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencyLDAP, clock.New(), provider))
+
+		ctx.providers.UserProvider = provider
+	case len(ctx.config.AuthenticationBackend.LDAPBackends) != 0:
+		provider := authentication.NewMultiLDAPUserProvider(ctx.config.AuthenticationBackend, ctx.trusted)
+		provider.SetCircuitBreakers(func(name string, backend *authentication.LDAPUserProvider) *breaker.Breaker {
+			return breaker.New(&ctx.config.CircuitBreaker, schema.CircuitBreakerDependencyLDAP, fmt.Sprintf("%s (%s)", schema.CircuitBreakerDependencyLDAP, name), clock.New(), backend)
+		})
+
+		ctx.providers.UserProvider = provider
+	case ctx.config.AuthenticationBackend.SQL != nil:
+		ctx.providers.UserProvider = authentication.NewSQLUserProvider(ctx.config.AuthenticationBackend.SQL, ctx.providers.StorageProvider)
+	}
+
+	var spnego *authentication.KeytabSPNEGOProvider
+
+	if spnego, err = authentication.NewKeytabSPNEGOProvider(&ctx.config.AuthenticationBackend.SPNEGO); err != nil {
+		errs = append(errs, err)
+	} else if spnego != nil {
+		ctx.providers.SPNEGO = spnego
 	}
 
 	if ctx.providers.Templates, err = templates.New(templates.Config{EmailTemplatesPath: ctx.config.Notifier.TemplatePath}); err != nil {
 		errs = append(errs, err)
 	}
 
-	switch {
-	case ctx.config.Notifier.SMTP != nil:
-		ctx.providers.Notifier = notification.NewSMTPNotifier(ctx.config.Notifier.SMTP, ctx.trusted)
-	case ctx.config.Notifier.FileSystem != nil:
-		ctx.providers.Notifier = notification.NewFileNotifier(*ctx.config.Notifier.FileSystem)
+	if ctx.providers.Branding, err = branding.NewProvider(ctx.config.Server.AssetPath); err != nil {
+		errs = append(errs, err)
+	}
+
+	if notifier := ctx.newNotifier(&ctx.config.Notifier); notifier != nil {
+		ctx.providers.Notifier = notification.NewReloadableNotifier(notifier)
+	}
+
+	if !ctx.config.SMS.Disable {
+		ctx.providers.SMS = sms.NewProvider(&ctx.config.SMS, ctx.trusted)
+	}
+
+	if !ctx.config.Push.Disable {
+		provider := push.NewProvider(&ctx.config.Push, ctx.trusted, ctx.providers.StorageProvider)
+
+		ctx.providers.Push = push.NewCircuitBreakerProvider(provider, breaker.New(&ctx.config.CircuitBreaker, schema.CircuitBreakerDependencyPush, schema.CircuitBreakerDependencyPush, clock.New(), provider))
 	}
 
 	ctx.providers.OpenIDConnect = oidc.NewOpenIDConnectProvider(ctx.config.IdentityProviders.OIDC, ctx.providers.StorageProvider, ctx.providers.Templates)
 
-	if ctx.config.Telemetry.Metrics.Enabled {
-		ctx.providers.Metrics = metrics.NewPrometheus()
+	if ctx.providers.SAML, err = saml.NewProvider(ctx.config.IdentityProviders.SAML); err != nil {
+		errs = append(errs, err)
+	}
+
+	if ctx.config.Telemetry.Metrics.Enabled || ctx.config.Telemetry.Metrics.StatsD.Enabled || ctx.config.Telemetry.Metrics.OTLP.Enabled {
+		ctx.providers.Metrics = metrics.NewPrometheus(ctx.providers.SessionProvider, ctx.providers.Regulator)
 	}
 
+	if ctx.config.Audit.Enabled {
+		var bus *audit.Bus
+
+		if bus, err = audit.NewBus(&ctx.config.Audit, logging.NewRedactor(ctx.config.Log.Redaction, []byte(ctx.config.Storage.EncryptionKey)), ctx.providers.StorageProvider, ctx.trusted, ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "audit"})); err != nil {
+			errs = append(errs, err)
+		} else {
+			if ctx.config.Server.Admin.Enabled && ctx.config.Server.Admin.EnableEvents {
+				ctx.providers.AuditStream = audit.NewStreamSink(ctx.config.Audit.BufferSize)
+
+				bus.Register("stream", ctx.providers.AuditStream, audit.NewFilter(nil, nil), ctx.config.Audit.BufferSize)
+			}
+
+			ctx.providers.Audit = bus
+		}
+	}
+
+	var certificates *pki.Provider
+
+	if certificates, err = pki.NewProvider(&ctx.config.DeviceCertificates, ctx.providers.StorageProvider, clock.New()); err != nil {
+		errs = append(errs, err)
+	} else if certificates != nil {
+		ctx.providers.DeviceCertificates = certificates
+	}
+
+	ctx.providers.Health = ctx.newHealthProvider()
+
+	ctx.elector = ctx.newElector(&ctx.config.LeaderElection)
+
 	return warns, errs
 }
 
+func (ctx *CmdCtx) newNotifier(config *schema.Notifier) (notifier notification.Notifier) {
+	switch {
+	case config.SMTP != nil:
+		smtp := notification.NewSMTPNotifier(config.SMTP, ctx.trusted)
+		smtp.SetCircuitBreaker(breaker.New(&ctx.config.CircuitBreaker, schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencySMTP, clock.New(), smtp))
+
+		return smtp
+	case config.SES != nil:
+		return notification.NewSESNotifier(config.SES)
+	case config.FileSystem != nil:
+		return notification.NewFileNotifier(*config.FileSystem)
+	default:
+		return nil
+	}
+}
+
+func (ctx *CmdCtx) newElector(config *schema.LeaderElection) (elector *election.Elector) {
+	if !config.Enabled {
+		return nil
+	}
+
+	var lock election.Lock
+
+	switch config.Backend {
+	case schema.LeaderElectionBackendRedis:
+		lock = election.NewRedisLock(ctx.config.Session.Redis, ctx.trusted)
+	default:
+		lock = election.NewStorageLock(ctx.providers.StorageProvider, clock.New())
+	}
+
+	return election.NewElector(*config, lock, election.NewHolder(ctx.providers.Random))
+}
+
+func (ctx *CmdCtx) newHealthProvider() (provider *health.Provider) {
+	provider = health.NewProvider(clock.New(), ctx.config.Server.Endpoints.Health.CacheDuration, ctx.config.Server.Endpoints.Health.Timeout)
+
+	if checker, ok := ctx.providers.StorageProvider.(model.HealthCheck); ok {
+		provider.Register("storage", true, checker)
+	}
+
+	if checker, ok := ctx.providers.UserProvider.(model.HealthCheck); ok {
+		provider.Register("authentication_backend", true, checker)
+	}
+
+	if ctx.providers.SessionProvider != nil && ctx.providers.SessionProvider.IsRedis() {
+		provider.Register("session", true, ctx.providers.SessionProvider)
+	}
+
+	if checker, ok := ctx.providers.Notifier.(model.HealthCheck); ok {
+		provider.Register("notifier", false, checker)
+	}
+
+	if ctx.providers.NTP != nil {
+		provider.Register("ntp", false, ctx.providers.NTP)
+	}
+
+	if !ctx.config.DuoAPI.Disable {
+		provider.Register("duo", false, duo.NewDuoAPIFromConfiguration(&ctx.config.DuoAPI, os.Getenv("ENVIRONMENT") == "dev"))
+	}
+
+	if ctx.providers.Push != nil {
+		provider.Register("push", false, ctx.providers.Push)
+	}
+
+	return provider
+}
+
 // ChainRunE runs multiple CobraRunECmd funcs one after the other returning errors.
 func (ctx *CmdCtx) ChainRunE(cmdRunEs ...CobraRunECmd) CobraRunECmd {
 	return func(cmd *cobra.Command, args []string) (err error) {
@@ -251,7 +407,7 @@ func (ctx *CmdCtx) LogConfigure(_ *cobra.Command, _ []string) (err error) {
 	}
 
 	switch config.Format {
-	case logging.FormatText, logging.FormatJSON:
+	case logging.FormatText, logging.FormatJSON, logging.FormatCEF, logging.FormatLEEF, logging.FormatECS:
 		break
 	default:
 		config.Format = logging.FormatText
@@ -259,12 +415,14 @@ func (ctx *CmdCtx) LogConfigure(_ *cobra.Command, _ []string) (err error) {
 
 	config.KeepStdout = true
 
+	logging.SetVersion(utils.Version())
+
 	if err = logging.InitializeLogger(schema.Log{Level: ctx.config.Log.Level}, false); err != nil {
 		return fmt.Errorf("Cannot initialize logger: %w", err)
 	}
 
-	ctx.log.WithFields(map[string]any{"filters": ctx.cconfig.filters, "files": ctx.cconfig.files}).Debug("Loaded Configuration Sources")
-	ctx.log.WithFields(map[string]any{"level": ctx.config.Log.Level, "format": ctx.config.Log.Format, "file": ctx.config.Log.FilePath, "keep_stdout": ctx.config.Log.KeepStdout}).Debug("Logging Initialized")
+	ctx.log.WithFields(map[string]any{"filters": ctx.cconfig.filters, "files": ctx.cconfig.files, "lists": ctx.cconfig.lists}).Debug("Loaded Configuration Sources")
+	ctx.log.WithFields(map[string]any{"level": ctx.config.Log.Level, "format": ctx.config.Log.Format, "file": ctx.config.Log.FilePath, "file_format": ctx.config.Log.FileFormat, "keep_stdout": ctx.config.Log.KeepStdout, "modules": ctx.config.Log.Modules, "syslog": ctx.config.Log.Syslog.Enabled, "journald": ctx.config.Log.Journald.Enabled}).Debug("Logging Initialized")
 
 	return nil
 }
@@ -382,6 +540,11 @@ func (ctx *CmdCtx) ConfigEnsureExistsRunE(cmd *cobra.Command, _ []string) (err e
 		}
 	}
 
+	// A dry run must not make any changes, so a missing configuration is reported when it's loaded instead.
+	if dryRun, _ := cmd.Flags().GetBool(cmdFlagNameDryRun); dryRun {
+		return nil
+	}
+
 	if created, err = configuration.EnsureConfigurationExists(configs[0]); err != nil {
 		ctx.log.Fatal(err)
 	}
@@ -414,9 +577,14 @@ func (ctx *CmdCtx) HelperConfigLoadRunE(cmd *cobra.Command, _ []string) (err err
 		ctx.cconfig.filters[i] = filter.Name()
 	}
 
+	if ctx.cconfig.lists, err = loadXEnvCLIConfigListMergeStrategy(cmd); err != nil {
+		return err
+	}
+
 	ctx.cconfig.sources = configuration.NewDefaultSourcesWithDefaults(
 		ctx.cconfig.files,
 		filters,
+		ctx.cconfig.lists,
 		configuration.DefaultEnvPrefix,
 		configuration.DefaultEnvDelimiter,
 		ctx.cconfig.defaults,
@@ -193,6 +193,7 @@ func newCryptoGenerateCmd(ctx *CmdCtx, category, algorithm string) (cmd *cobra.C
 	}
 
 	cmdFlagsCryptoPrivateKey(cmd)
+	cmdFlagsCryptoJWKS(cmd)
 
 	algorithmFmt := fmtCryptoCertificateUse(algorithm)
 
@@ -253,15 +254,31 @@ func (ctx *CmdCtx) CryptoRandRunE(cmd *cobra.Command, args []string) (err error)
 		return err
 	}
 
-	fmt.Printf("Random Value: %s\n", random)
+	result := cryptoRandResult{Value: random}
 
 	if value := url.QueryEscape(random); random != value {
-		fmt.Printf("Random Value (URL Encoded): %s\n", value)
+		result.ValueURLEncoded = value
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
+	fmt.Printf("Random Value: %s\n", result.Value)
+
+	if result.ValueURLEncoded != "" {
+		fmt.Printf("Random Value (URL Encoded): %s\n", result.ValueURLEncoded)
 	}
 
 	return nil
 }
 
+// cryptoRandResult is the result of the authelia crypto rand command.
+type cryptoRandResult struct {
+	Value           string `json:"value"`
+	ValueURLEncoded string `json:"value_url_encoded,omitempty"`
+}
+
 // CryptoGenerateRunE is the RunE for the authelia crypto [pair|certificate] [rsa|ecdsa|ed25519] commands.
 func (ctx *CmdCtx) CryptoGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 	var (
@@ -308,6 +325,11 @@ func (ctx *CmdCtx) CryptoCertificateRequestRunE(cmd *cobra.Command, _ []string)
 		return err
 	}
 
+	result := &cryptoGenerateResult{SignatureAlgorithm: template.SignatureAlgorithm.String()}
+
+	result.setPrivateKey(privateKey)
+	result.setSubject(template.Subject, template.DNSNames, template.IPAddresses)
+
 	b := strings.Builder{}
 
 	b.WriteString("Generating Certificate Request\n\n")
@@ -348,6 +370,13 @@ func (ctx *CmdCtx) CryptoCertificateRequestRunE(cmd *cobra.Command, _ []string)
 		privateKeyPaths = append(privateKeyPaths, filepath.Base(privateKeyLegacyPath))
 	}
 
+	result.Files = cryptoGenerateResultFiles{
+		Directory:          filepath.Clean(dir),
+		PrivateKey:         privateKeyPath,
+		PrivateKeyLegacy:   privateKeyLegacyPath,
+		CertificateRequest: csrPath,
+	}
+
 	b.WriteString("Output Paths:\n")
 
 	if cdir := filepath.Clean(dir); len(cdir) != 0 {
@@ -375,6 +404,10 @@ func (ctx *CmdCtx) CryptoCertificateRequestRunE(cmd *cobra.Command, _ []string)
 		return err
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
 	b.WriteString("\n")
 
 	fmt.Print(b.String())
@@ -421,6 +454,17 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 		return err
 	}
 
+	result := &cryptoGenerateResult{
+		Serial:             fmt.Sprintf("%x", template.SerialNumber),
+		NotBefore:          &template.NotBefore,
+		NotAfter:           &template.NotAfter,
+		CA:                 template.IsCA,
+		SignatureAlgorithm: template.SignatureAlgorithm.String(),
+	}
+
+	result.setPrivateKey(privateKey)
+	result.setSubject(template.Subject, template.DNSNames, template.IPAddresses)
+
 	b := &strings.Builder{}
 
 	b.WriteString("Generating Certificate\n\n")
@@ -435,6 +479,8 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 	default:
 		parent = caCertificate
 
+		result.SignedBy = caCertificate.Subject.CommonName
+
 		b.WriteString(fmt.Sprintf("Signed By:\n\t%s\n", caCertificate.Subject.CommonName))
 		b.WriteString(fmt.Sprintf("\tSerial: %x, Expires: %s\n", caCertificate.SerialNumber, caCertificate.NotAfter.Format(time.RFC3339)))
 	}
@@ -461,7 +507,7 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 	b.WriteString(fmt.Sprintf("\n\tSubject Alternative Names: %s\n\n", strings.Join(cryptoSANsToString(template.DNSNames, template.IPAddresses), ", ")))
 
 	var (
-		dir, privateKeyPath, privateKeyLegacyPath, certificatePath string
+		dir, privateKeyPath, privateKeyLegacyPath, certificatePath, jwksPath string
 
 		certificate []byte
 	)
@@ -470,6 +516,10 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 		return err
 	}
 
+	if jwksPath, err = cryptoGetJWKSPathFromCmd(cmd, dir); err != nil {
+		return err
+	}
+
 	privateKeyPaths := []string{filepath.Base(privateKeyPath)}
 
 	if legacy {
@@ -482,6 +532,14 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 		privateKeyPaths = append(privateKeyPaths, filepath.Base(privateKeyLegacyPath))
 	}
 
+	result.Files = cryptoGenerateResultFiles{
+		Directory:        filepath.Clean(dir),
+		PrivateKey:       privateKeyPath,
+		PrivateKeyLegacy: privateKeyLegacyPath,
+		Certificate:      certificatePath,
+		JSONWebKeySet:    jwksPath,
+	}
+
 	b.WriteString("Output Paths:\n")
 
 	if cdir := filepath.Clean(dir); len(cdir) != 0 {
@@ -510,11 +568,33 @@ func (ctx *CmdCtx) CryptoCertificateGenerateRunE(cmd *cobra.Command, _ []string,
 	}
 
 	if cmd.Flags().Changed(cmdFlagNameBundles) {
-		if err = cryptoGenerateCertificateBundlesFromCmd(cmd, b, dir, caCertificate, certificate, privateKey); err != nil {
+		if err = cryptoGenerateCertificateBundlesFromCmd(cmd, b, &result.Files, dir, caCertificate, certificate, privateKey); err != nil {
 			return err
 		}
 	}
 
+	if jwksPath != "" {
+		var certificates []*x509.Certificate
+
+		if certificates, err = x509.ParseCertificates(certificate); err != nil {
+			return fmt.Errorf("failed to parse the generated certificate: %w", err)
+		}
+
+		if caCertificate != nil {
+			certificates = append(certificates, caCertificate)
+		}
+
+		b.WriteString(fmt.Sprintf("\tJSON Web Key Set: %s\n", filepath.Base(jwksPath)))
+
+		if err = cryptoWriteJWKS(jwksPath, privateKey, certificates); err != nil {
+			return err
+		}
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
 	b.WriteString("\n")
 
 	fmt.Print(b.String())
@@ -531,7 +611,7 @@ func (ctx *CmdCtx) CryptoPairGenerateRunE(cmd *cobra.Command, _ []string, privat
 	var (
 		privateKeyPath, publicKeyPath             string
 		privateKeyLegacyPath, publicKeyLegacyPath string
-		dir, extLegacy                            string
+		dir, extLegacy, jwksPath                  string
 
 		legacy bool
 	)
@@ -548,6 +628,14 @@ func (ctx *CmdCtx) CryptoPairGenerateRunE(cmd *cobra.Command, _ []string, privat
 		return err
 	}
 
+	if jwksPath, err = cryptoGetJWKSPathFromCmd(cmd, dir); err != nil {
+		return err
+	}
+
+	result := &cryptoGenerateResult{}
+
+	result.setPrivateKey(privateKey)
+
 	b := strings.Builder{}
 
 	b.WriteString("Generating key pair\n\n")
@@ -584,6 +672,15 @@ func (ctx *CmdCtx) CryptoPairGenerateRunE(cmd *cobra.Command, _ []string, privat
 		publicKeyPaths = append(publicKeyPaths, filepath.Base(publicKeyLegacyPath))
 	}
 
+	result.Files = cryptoGenerateResultFiles{
+		Directory:        filepath.Clean(dir),
+		PrivateKey:       privateKeyPath,
+		PrivateKeyLegacy: privateKeyLegacyPath,
+		PublicKey:        publicKeyPath,
+		PublicKeyLegacy:  publicKeyLegacyPath,
+		JSONWebKeySet:    jwksPath,
+	}
+
 	b.WriteString("Output Paths:\n")
 
 	if cdir := filepath.Clean(dir); len(cdir) != 0 {
@@ -591,7 +688,13 @@ func (ctx *CmdCtx) CryptoPairGenerateRunE(cmd *cobra.Command, _ []string, privat
 	}
 
 	b.WriteString(fmt.Sprintf("\tPrivate Key: %s\n", strings.Join(privateKeyPaths, ", ")))
-	b.WriteString(fmt.Sprintf("\tPublic Key: %s\n\n", strings.Join(publicKeyPaths, ", ")))
+	b.WriteString(fmt.Sprintf("\tPublic Key: %s\n", strings.Join(publicKeyPaths, ", ")))
+
+	if jwksPath != "" {
+		b.WriteString(fmt.Sprintf("\tJSON Web Key Set: %s\n", filepath.Base(jwksPath)))
+	}
+
+	b.WriteString("\n")
 
 	if err = utils.WriteKeyToPEM(privateKey, privateKeyPath, false); err != nil {
 		return err
@@ -619,6 +722,16 @@ func (ctx *CmdCtx) CryptoPairGenerateRunE(cmd *cobra.Command, _ []string, privat
 		}
 	}
 
+	if jwksPath != "" {
+		if err = cryptoWriteJWKS(jwksPath, privateKey, nil); err != nil {
+			return err
+		}
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
 	b.WriteString("\n")
 
 	fmt.Print(b.String())
@@ -5,7 +5,6 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/go-crypt/crypt"
 	"github.com/go-crypt/crypt/algorithm"
 	"github.com/spf13/cobra"
 
@@ -55,6 +54,11 @@ func newCryptoHashGenerateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		prefixFilePassword + suffixSCryptParallelism:   schema.DefaultPasswordConfig.SCrypt.Parallelism,
 		prefixFilePassword + suffixSCryptKeyLength:     schema.DefaultPasswordConfig.SCrypt.KeyLength,
 		prefixFilePassword + suffixSCryptSaltLength:    schema.DefaultPasswordConfig.SCrypt.SaltLength,
+		prefixFilePassword + suffixBalloonVariant:      schema.DefaultPasswordConfig.Balloon.Variant,
+		prefixFilePassword + suffixBalloonSpaceCost:    schema.DefaultPasswordConfig.Balloon.SpaceCost,
+		prefixFilePassword + suffixBalloonTimeCost:     schema.DefaultPasswordConfig.Balloon.TimeCost,
+		prefixFilePassword + suffixBalloonDelta:        schema.DefaultPasswordConfig.Balloon.Delta,
+		prefixFilePassword + suffixBalloonSaltLength:   schema.DefaultPasswordConfig.Balloon.SaltLength,
 	}
 
 	cmd = &cobra.Command{
@@ -77,7 +81,7 @@ func newCryptoHashGenerateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmdFlagPassword(cmd, true)
 	cmdFlagRandomPassword(cmd)
 
-	for _, use := range []string{cmdUseHashArgon2, cmdUseHashSHA2Crypt, cmdUseHashPBKDF2, cmdUseHashBCrypt, cmdUseHashSCrypt} {
+	for _, use := range []string{cmdUseHashArgon2, cmdUseHashSHA2Crypt, cmdUseHashPBKDF2, cmdUseHashBCrypt, cmdUseHashSCrypt, cmdUseHashBalloon} {
 		cmd.AddCommand(newCryptoHashGenerateSubCmd(ctx, use))
 	}
 
@@ -106,6 +110,11 @@ func newCryptoHashGenerateSubCmd(ctx *CmdCtx, use string) (cmd *cobra.Command) {
 		prefixFilePassword + suffixSCryptParallelism:   schema.DefaultPasswordConfig.SCrypt.Parallelism,
 		prefixFilePassword + suffixSCryptKeyLength:     schema.DefaultPasswordConfig.SCrypt.KeyLength,
 		prefixFilePassword + suffixSCryptSaltLength:    schema.DefaultPasswordConfig.SCrypt.SaltLength,
+		prefixFilePassword + suffixBalloonVariant:      schema.DefaultPasswordConfig.Balloon.Variant,
+		prefixFilePassword + suffixBalloonSpaceCost:    schema.DefaultPasswordConfig.Balloon.SpaceCost,
+		prefixFilePassword + suffixBalloonTimeCost:     schema.DefaultPasswordConfig.Balloon.TimeCost,
+		prefixFilePassword + suffixBalloonDelta:        schema.DefaultPasswordConfig.Balloon.Delta,
+		prefixFilePassword + suffixBalloonSaltLength:   schema.DefaultPasswordConfig.Balloon.SaltLength,
 	}
 
 	useFmt := fmtCryptoHashUse(use)
@@ -158,6 +167,13 @@ func newCryptoHashGenerateSubCmd(ctx *CmdCtx, use string) (cmd *cobra.Command) {
 		cmdFlagParallelism(cmd, schema.DefaultPasswordConfig.SCrypt.Parallelism)
 
 		cmd.Flags().IntP(cmdFlagNameBlockSize, "r", schema.DefaultPasswordConfig.SCrypt.BlockSize, "block size")
+	case cmdUseHashBalloon:
+		cmdFlagSaltSize(cmd, schema.DefaultPasswordConfig.Balloon.SaltLength)
+
+		cmd.Flags().StringP(cmdFlagNameVariant, "v", schema.DefaultPasswordConfig.Balloon.Variant, "variant, options are 'sha256' and 'sha512'")
+		cmd.Flags().IntP(cmdFlagNameSpaceCost, "m", schema.DefaultPasswordConfig.Balloon.SpaceCost, "space cost in blocks")
+		cmd.Flags().IntP(cmdFlagNameTimeCost, "t", schema.DefaultPasswordConfig.Balloon.TimeCost, "time cost in rounds")
+		cmd.Flags().IntP(cmdFlagNameDelta, "d", schema.DefaultPasswordConfig.Balloon.Delta, "number of dependencies per block")
 	}
 
 	return cmd
@@ -195,10 +211,14 @@ func (ctx *CmdCtx) CryptoHashValidateRunE(cmd *cobra.Command, args []string) (er
 		return fmt.Errorf("no password provided")
 	}
 
-	if valid, err = crypt.CheckPassword(password, args[0]); err != nil {
+	if valid, err = authentication.CheckPassword(password, args[0]); err != nil {
 		return fmt.Errorf("error occurred trying to validate the password against the digest: %w", err)
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, cryptoHashValidateResult{Valid: valid})
+	}
+
 	switch {
 	case valid:
 		fmt.Println("The password matches the digest.")
@@ -209,6 +229,11 @@ func (ctx *CmdCtx) CryptoHashValidateRunE(cmd *cobra.Command, args []string) (er
 	return nil
 }
 
+// cryptoHashValidateResult is the result of the authelia crypto hash validate command.
+type cryptoHashValidateResult struct {
+	Valid bool `json:"valid"`
+}
+
 // CryptoHashGenerateMapFlagsRunE is the RunE which configures the flags map configuration source for the
 // authelia crypto hash generate commands.
 func (ctx *CmdCtx) CryptoHashGenerateMapFlagsRunE(cmd *cobra.Command, args []string) (err error) {
@@ -250,6 +275,14 @@ func (ctx *CmdCtx) CryptoHashGenerateMapFlagsRunE(cmd *cobra.Command, args []str
 			cmdFlagNameKeySize:     prefixFilePassword + suffixSCryptKeyLength,
 			cmdFlagNameSaltSize:    prefixFilePassword + suffixSCryptSaltLength,
 		}
+	case cmdUseHashBalloon:
+		flagsMap = map[string]string{
+			cmdFlagNameVariant:   prefixFilePassword + suffixBalloonVariant,
+			cmdFlagNameSpaceCost: prefixFilePassword + suffixBalloonSpaceCost,
+			cmdFlagNameTimeCost:  prefixFilePassword + suffixBalloonTimeCost,
+			cmdFlagNameDelta:     prefixFilePassword + suffixBalloonDelta,
+			cmdFlagNameSaltSize:  prefixFilePassword + suffixBalloonSaltLength,
+		}
 	}
 
 	if flagsMap != nil {
@@ -291,19 +324,41 @@ func (ctx *CmdCtx) CryptoHashGenerateRunE(cmd *cobra.Command, args []string) (er
 		return err
 	}
 
+	result := cryptoHashGenerateResult{Digest: digest.Encode()}
+
 	if random {
-		fmt.Printf("Random Password: %s\n", password)
+		result.Password = password
 
 		if value := url.QueryEscape(password); password != value {
-			fmt.Printf("Random Password (URL Encoded): %s\n", value)
+			result.PasswordURLEncoded = value
 		}
 	}
 
-	fmt.Printf("Digest: %s\n", digest.Encode())
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
+	if result.Password != "" {
+		fmt.Printf("Random Password: %s\n", result.Password)
+
+		if result.PasswordURLEncoded != "" {
+			fmt.Printf("Random Password (URL Encoded): %s\n", result.PasswordURLEncoded)
+		}
+	}
+
+	fmt.Printf("Digest: %s\n", result.Digest)
 
 	return nil
 }
 
+// cryptoHashGenerateResult is the result of the authelia crypto hash generate commands, the password is only included
+// when it was randomly generated.
+type cryptoHashGenerateResult struct {
+	Password           string `json:"password,omitempty"`
+	PasswordURLEncoded string `json:"password_url_encoded,omitempty"`
+	Digest             string `json:"digest"`
+}
+
 func cmdCryptoHashGetPassword(cmd *cobra.Command, args []string, useArgs, useRandom bool) (password string, random bool, err error) {
 	if useRandom {
 		if random, err = cmd.Flags().GetBool(cmdFlagNameRandom); err != nil {
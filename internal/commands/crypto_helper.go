@@ -1,12 +1,14 @@
 package commands
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"math/big"
@@ -16,7 +18,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v4"
 	"github.com/spf13/cobra"
+	"software.sslmate.com/src/go-pkcs12"
 
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -46,7 +50,9 @@ func cmdFlagsCryptoCertificateGenerate(cmd *cobra.Command) {
 	cmd.Flags().String(cmdFlagNameFileCertificate, "public.crt", "name of the file to export the certificate data to")
 	cmd.Flags().String(cmdFlagNameFileBundleChain, "public.chain.pem", fmt.Sprintf("name of the file to export the certificate chain PEM bundle to when the --%s flag includes 'chain'", cmdFlagNameBundles))
 	cmd.Flags().String(cmdFlagNameFileBundlePrivKeyChain, "private.chain.pem", fmt.Sprintf("name of the file to export the certificate chain and private key PEM bundle to when the --%s flag includes 'priv-chain'", cmdFlagNameBundles))
-	cmd.Flags().StringSlice(cmdFlagNameBundles, nil, "enables generating bundles options are 'chain' and 'privkey-chain'")
+	cmd.Flags().String(cmdFlagNameFileBundlePKCS12, "bundle.p12", fmt.Sprintf("name of the file to export the certificate chain and private key PKCS#12 bundle to when the --%s flag includes 'pkcs12'", cmdFlagNameBundles))
+	cmd.Flags().String(cmdFlagNamePKCS12Password, "", fmt.Sprintf("manually supply the password for the PKCS#12 bundle rather than using the terminal prompt when the --%s flag includes 'pkcs12'", cmdFlagNameBundles))
+	cmd.Flags().StringSlice(cmdFlagNameBundles, nil, "enables generating bundles options are 'chain', 'priv-chain', and 'pkcs12'")
 
 	cmd.Flags().StringSlice(cmdFlagNameExtendedUsage, nil, "specify the extended usage types of the certificate")
 
@@ -68,6 +74,11 @@ func cmdFlagsCryptoPrivateKey(cmd *cobra.Command) {
 	cmd.Flags().StringP(cmdFlagNameDirectory, "d", "", "directory where the generated keys, certificates, etc will be stored")
 }
 
+func cmdFlagsCryptoJWKS(cmd *cobra.Command) {
+	cmd.Flags().Bool(cmdFlagNameJWKS, false, "enables the output of a JSON Web Key Set containing the public key when enabled")
+	cmd.Flags().String(cmdFlagNameFileJWKS, "jwks.json", "name of the file to export the JSON Web Key Set to")
+}
+
 func cmdFlagsCryptoPrivateKeyRSA(cmd *cobra.Command) {
 	cmd.Flags().IntP(cmdFlagNameBits, "b", 2048, "number of RSA bits for the certificate")
 }
@@ -79,6 +90,65 @@ func cmdFlagsCryptoPrivateKeyECDSA(cmd *cobra.Command) {
 func cmdFlagsCryptoPrivateKeyEd25519(cmd *cobra.Command) {
 }
 
+// cryptoGenerateResult is the result of the authelia crypto certificate and pair subcommands.
+type cryptoGenerateResult struct {
+	Serial   string `json:"serial,omitempty"`
+	SignedBy string `json:"signed_by,omitempty"`
+
+	CommonName         string   `json:"common_name,omitempty"`
+	Organization       []string `json:"organization,omitempty"`
+	OrganizationalUnit []string `json:"organizational_unit,omitempty"`
+
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	CA        bool       `json:"ca,omitempty"`
+
+	SignatureAlgorithm string `json:"signature_algorithm,omitempty"`
+	PublicKeyAlgorithm string `json:"public_key_algorithm"`
+	Bits               int    `json:"bits,omitempty"`
+	EllipticCurve      string `json:"elliptic_curve,omitempty"`
+
+	DNSNames    []string `json:"dns_names,omitempty"`
+	IPAddresses []string `json:"ip_addresses,omitempty"`
+
+	Files cryptoGenerateResultFiles `json:"files"`
+}
+
+// cryptoGenerateResultFiles is the paths of the files written by the authelia crypto certificate and pair subcommands.
+type cryptoGenerateResultFiles struct {
+	Directory                  string `json:"directory,omitempty"`
+	PrivateKey                 string `json:"private_key"`
+	PrivateKeyLegacy           string `json:"private_key_legacy,omitempty"`
+	PublicKey                  string `json:"public_key,omitempty"`
+	PublicKeyLegacy            string `json:"public_key_legacy,omitempty"`
+	Certificate                string `json:"certificate,omitempty"`
+	CertificateRequest         string `json:"certificate_request,omitempty"`
+	CertificateChain           string `json:"certificate_chain,omitempty"`
+	CertificatePrivateKeyChain string `json:"certificate_private_key_chain,omitempty"`
+	PKCS12                     string `json:"pkcs12,omitempty"`
+	JSONWebKeySet              string `json:"json_web_key_set,omitempty"`
+}
+
+func (r *cryptoGenerateResult) setPrivateKey(privateKey any) {
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		r.PublicKeyAlgorithm, r.Bits = x509.RSA.String(), k.N.BitLen()
+	case *ecdsa.PrivateKey:
+		r.PublicKeyAlgorithm, r.EllipticCurve = x509.ECDSA.String(), k.Curve.Params().Name
+	case ed25519.PrivateKey:
+		r.PublicKeyAlgorithm = x509.Ed25519.String()
+	}
+}
+
+func (r *cryptoGenerateResult) setSubject(subject pkix.Name, dnsSANs []string, ipSANs []net.IP) {
+	r.CommonName, r.Organization, r.OrganizationalUnit = subject.CommonName, subject.Organization, subject.OrganizationalUnit
+	r.DNSNames = dnsSANs
+
+	for _, ipSAN := range ipSANs {
+		r.IPAddresses = append(r.IPAddresses, ipSAN.String())
+	}
+}
+
 func cryptoSANsToString(dnsSANs []string, ipSANs []net.IP) (sans []string) {
 	sans = make([]string, len(dnsSANs)+len(ipSANs))
 
@@ -173,7 +243,7 @@ func (ctx *CmdCtx) cryptoGenPrivateKeyFromCmd(cmd *cobra.Command) (privateKey an
 	return privateKey, nil
 }
 
-func cryptoGenerateCertificateBundlesFromCmd(cmd *cobra.Command, b *strings.Builder, dir string, ca *x509.Certificate, certificate []byte, privkey any) (err error) {
+func cryptoGenerateCertificateBundlesFromCmd(cmd *cobra.Command, b *strings.Builder, files *cryptoGenerateResultFiles, dir string, ca *x509.Certificate, certificate []byte, privkey any) (err error) {
 	var bundles []string
 
 	if bundles, err = cmd.Flags().GetStringSlice(cmdFlagNameBundles); err != nil {
@@ -199,6 +269,8 @@ func cryptoGenerateCertificateBundlesFromCmd(cmd *cobra.Command, b *strings.Buil
 
 		b.WriteString(fmt.Sprintf("\tCertificate (chain): %s\n", pathPEM))
 
+		files.CertificateChain = pathPEM
+
 		if err = utils.WritePEMBlocksToPath(pathPEM, blocks...); err != nil {
 			return err
 		}
@@ -221,14 +293,154 @@ func cryptoGenerateCertificateBundlesFromCmd(cmd *cobra.Command, b *strings.Buil
 
 		b.WriteString(fmt.Sprintf("\tCertificate (priv-chain): %s\n", pathPEM))
 
+		files.CertificatePrivateKeyChain = pathPEM
+
 		if err = utils.WritePEMBlocksToPath(pathPEM, blocks...); err != nil {
 			return err
 		}
 	}
 
+	if utils.IsStringInSliceFold("pkcs12", bundles) {
+		if name, err = cmd.Flags().GetString(cmdFlagNameFileBundlePKCS12); err != nil {
+			return err
+		}
+
+		var (
+			password string
+			cert     *x509.Certificate
+			data     []byte
+		)
+
+		if password, err = cryptoGetPKCS12PasswordFromCmd(cmd); err != nil {
+			return err
+		}
+
+		if cert, err = x509.ParseCertificate(certificate); err != nil {
+			return fmt.Errorf("failed to parse the generated certificate: %w", err)
+		}
+
+		var caCertificates []*x509.Certificate
+
+		if ca != nil {
+			caCertificates = append(caCertificates, ca)
+		}
+
+		if data, err = pkcs12.Modern.Encode(privkey, cert, caCertificates, password); err != nil {
+			return fmt.Errorf("failed to encode the PKCS#12 bundle: %w", err)
+		}
+
+		pathPKCS12 := filepath.Join(dir, name)
+
+		b.WriteString(fmt.Sprintf("\tCertificate (pkcs12): %s\n", pathPKCS12))
+
+		files.PKCS12 = pathPKCS12
+
+		if err = os.WriteFile(pathPKCS12, data, 0600); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+func cryptoGetPKCS12PasswordFromCmd(cmd *cobra.Command) (password string, err error) {
+	if cmd.Flags().Changed(cmdFlagNamePKCS12Password) {
+		return cmd.Flags().GetString(cmdFlagNamePKCS12Password)
+	}
+
+	if password, err = termReadPasswordWithPrompt("Enter PKCS#12 Password: ", cmdFlagNamePKCS12Password); err != nil {
+		return "", err
+	}
+
+	var confirm string
+
+	if confirm, err = termReadPasswordWithPrompt("Confirm PKCS#12 Password: ", cmdFlagNamePKCS12Password); err != nil {
+		return "", err
+	}
+
+	if password != confirm {
+		return "", fmt.Errorf("the password and the confirmation don't match")
+	}
+
+	return password, nil
+}
+
+// cryptoGetJWKSPathFromCmd returns the path of the JSON Web Key Set file if the output of it is enabled.
+func cryptoGetJWKSPathFromCmd(cmd *cobra.Command, dir string) (path string, err error) {
+	var (
+		enabled bool
+		name    string
+	)
+
+	if enabled, err = cmd.Flags().GetBool(cmdFlagNameJWKS); err != nil || !enabled {
+		return "", err
+	}
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameFileJWKS); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// cryptoJWKFromKey returns a JSON Web Key for the public key of the private key with the certificates as the x5c
+// value. The key ID is derived from the thumbprint of the key and the algorithm in the same way as the OpenID Connect
+// 1.0 Provider configuration.
+func cryptoJWKFromKey(privateKey any, certificates []*x509.Certificate) (jwk *jose.JSONWebKey, err error) {
+	jwk = &jose.JSONWebKey{
+		Key:          utils.PublicKeyFromPrivateKey(privateKey),
+		Use:          "sig",
+		Certificates: certificates,
+	}
+
+	switch k := privateKey.(type) {
+	case *rsa.PrivateKey:
+		jwk.Algorithm = string(jose.RS256)
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			jwk.Algorithm = string(jose.ES256)
+		case elliptic.P384():
+			jwk.Algorithm = string(jose.ES384)
+		case elliptic.P521():
+			jwk.Algorithm = string(jose.ES512)
+		default:
+			return nil, fmt.Errorf("the elliptic curve %s is not supported by JSON Web Keys", k.Curve.Params().Name)
+		}
+	case ed25519.PrivateKey:
+		jwk.Algorithm = string(jose.EdDSA)
+	default:
+		return nil, fmt.Errorf("the key type %T is not supported by JSON Web Keys", privateKey)
+	}
+
+	var thumbprint []byte
+
+	if thumbprint, err = jwk.Thumbprint(crypto.SHA256); err != nil {
+		return nil, fmt.Errorf("failed to calculate the thumbprint of the JSON Web Key: %w", err)
+	}
+
+	jwk.KeyID = fmt.Sprintf("%s-%s", fmt.Sprintf("%x", thumbprint)[:6], strings.ToLower(jwk.Algorithm))
+
+	return jwk, nil
+}
+
+func cryptoWriteJWKS(path string, privateKey any, certificates []*x509.Certificate) (err error) {
+	var (
+		jwk  *jose.JSONWebKey
+		data []byte
+	)
+
+	if jwk, err = cryptoJWKFromKey(privateKey, certificates); err != nil {
+		return err
+	}
+
+	if data, err = json.MarshalIndent(&jose.JSONWebKeySet{Keys: []jose.JSONWebKey{*jwk}}, "", "  "); err != nil {
+		return fmt.Errorf("failed to marshal the JSON Web Key Set: %w", err)
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
 func cryptoGetCAFromCmd(cmd *cobra.Command) (privateKey any, cert *x509.Certificate, err error) {
 	if !cmd.Flags().Changed(cmdFlagNamePathCA) {
 		return nil, nil, nil
@@ -520,6 +732,8 @@ func fmtCryptoHashUse(use string) string {
 		return "SHA2 Crypt"
 	case cmdUseHashPBKDF2:
 		return "PBKDF2"
+	case cmdUseHashBalloon:
+		return "Balloon"
 	default:
 		return use
 	}
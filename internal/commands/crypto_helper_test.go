@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestCryptoJWKFromKey(t *testing.T) {
+	keyRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyECDSAP256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	keyECDSAP521, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	keyECDSAP224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	_, keyEd25519, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		key      any
+		expected string
+		err      string
+	}{
+		{"ShouldHandleRSA", keyRSA, "RS256", ""},
+		{"ShouldHandleECDSAP256", keyECDSAP256, "ES256", ""},
+		{"ShouldHandleECDSAP521", keyECDSAP521, "ES512", ""},
+		{"ShouldHandleEd25519", keyEd25519, "EdDSA", ""},
+		{"ShouldErrECDSAP224", keyECDSAP224, "", "the elliptic curve P-224 is not supported by JSON Web Keys"},
+		{"ShouldErrUnknown", "abc", "", "the key type string is not supported by JSON Web Keys"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			jwk, err := cryptoJWKFromKey(tc.key, nil)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, jwk.Algorithm)
+			assert.Equal(t, "sig", jwk.Use)
+			assert.True(t, jwk.IsPublic())
+			assert.True(t, strings.HasSuffix(jwk.KeyID, "-"+strings.ToLower(tc.expected)))
+			assert.Len(t, jwk.KeyID, 7+len(tc.expected))
+		})
+	}
+}
+
+func TestCryptoWriteJWKS(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	certificate := cryptoTestCertificate(t, key)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+
+	require.NoError(t, cryptoWriteJWKS(path, key, []*x509.Certificate{certificate}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	jwks := &jose.JSONWebKeySet{}
+
+	require.NoError(t, json.Unmarshal(data, jwks))
+	require.Len(t, jwks.Keys, 1)
+
+	assert.Equal(t, "ES256", jwks.Keys[0].Algorithm)
+	assert.True(t, jwks.Keys[0].IsPublic())
+	require.Len(t, jwks.Keys[0].Certificates, 1)
+	assert.Equal(t, certificate.Raw, jwks.Keys[0].Certificates[0].Raw)
+}
+
+func TestCryptoGenerateCertificateBundlesFromCmdPKCS12(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	certificate := cryptoTestCertificate(t, key)
+
+	dir := t.TempDir()
+
+	cmd := &cobra.Command{}
+
+	cmdFlagsCryptoCertificateGenerate(cmd)
+
+	require.NoError(t, cmd.Flags().Set(cmdFlagNameBundles, "pkcs12"))
+	require.NoError(t, cmd.Flags().Set(cmdFlagNamePKCS12Password, "example"))
+
+	b := &strings.Builder{}
+	files := &cryptoGenerateResultFiles{}
+
+	require.NoError(t, cryptoGenerateCertificateBundlesFromCmd(cmd, b, files, dir, nil, certificate.Raw, key))
+
+	path := filepath.Join(dir, "bundle.p12")
+
+	assert.Equal(t, "\tCertificate (pkcs12): "+path+"\n", b.String())
+	assert.Equal(t, path, files.PKCS12)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	actualKey, actualCertificate, err := pkcs12.Decode(data, "example")
+	require.NoError(t, err)
+
+	assert.Equal(t, key, actualKey)
+	assert.Equal(t, certificate.Raw, actualCertificate.Raw)
+
+	_, _, err = pkcs12.Decode(data, "invalid")
+	assert.ErrorIs(t, err, pkcs12.ErrIncorrectPassword)
+}
+
+func cryptoTestCertificate(t *testing.T, key *ecdsa.PrivateKey) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"example.com"},
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return certificate
+}
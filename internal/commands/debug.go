@@ -0,0 +1,567 @@
+package commands
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/spf13/cobra"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/handlers"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/notification"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+func newDebugCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "debug",
+		Short:   cmdAutheliaDebugShort,
+		Long:    cmdAutheliaDebugLong,
+		Example: cmdAutheliaDebugExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newDebugAuthzCmd(ctx),
+		newDebugLDAPCmd(ctx),
+		newDebugSMTPCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newDebugAuthzCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "authz",
+		Short:   cmdAutheliaDebugAuthzShort,
+		Long:    cmdAutheliaDebugAuthzLong,
+		Example: cmdAutheliaDebugAuthzExample,
+		Args:    cobra.NoArgs,
+		PreRunE: ctx.ChainRunE(
+			ctx.HelperConfigLoadRunE,
+			ctx.HelperConfigValidateKeysRunE,
+			ctx.HelperConfigValidateRunE,
+			ctx.ConfigValidateLogRunE,
+		),
+		RunE: ctx.DebugAuthzRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameEndpoint, schema.AuthzEndpointNameForwardAuth, "the name of the authz endpoint to replay the request against")
+	cmd.Flags().String("url", "", "the url of the request")
+	cmd.Flags().String("method", fasthttp.MethodGet, "the HTTP method of the request")
+	cmd.Flags().StringArray(cmdFlagNameHeader, nil, "additional headers of the request in the 'Name: Value' format")
+	cmd.Flags().String(cmdFlagNameSession, "", "the value of the session cookie of the request")
+	cmd.Flags().String("ip", "", "the ip of the client making the request")
+	cmd.Flags().Bool(cmdFlagNameVerbose, false, "enables verbose output")
+
+	_ = cmd.RegisterFlagCompletionFunc("url", ctx.CompletionDomainURLs)
+
+	return cmd
+}
+
+// DebugAuthzRunE is the RunE for the authelia debug authz command.
+func (ctx *CmdCtx) DebugAuthzRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		name, rawURL, method, cookie, ip string
+		headers                          []string
+		verbose                          bool
+	)
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameEndpoint); err != nil {
+		return err
+	}
+
+	if rawURL, err = cmd.Flags().GetString("url"); err != nil {
+		return err
+	}
+
+	if method, err = cmd.Flags().GetString("method"); err != nil {
+		return err
+	}
+
+	if headers, err = cmd.Flags().GetStringArray(cmdFlagNameHeader); err != nil {
+		return err
+	}
+
+	if cookie, err = cmd.Flags().GetString(cmdFlagNameSession); err != nil {
+		return err
+	}
+
+	if ip, err = cmd.Flags().GetString("ip"); err != nil {
+		return err
+	}
+
+	if verbose, err = cmd.Flags().GetBool(cmdFlagNameVerbose); err != nil {
+		return err
+	}
+
+	endpoint, ok := ctx.config.Server.Endpoints.Authz[name]
+	if !ok {
+		return fmt.Errorf("the authz endpoint '%s' is not configured", name)
+	}
+
+	var target *url.URL
+
+	if target, err = url.ParseRequestURI(rawURL); err != nil {
+		return fmt.Errorf("failed to parse the url: %w", err)
+	}
+
+	remoteIP := net.ParseIP(ip)
+
+	if ip != "" && remoteIP == nil {
+		return fmt.Errorf("failed to parse the ip '%s'", ip)
+	}
+
+	if _, errs := ctx.LoadProviders(); len(errs) != 0 {
+		return fmt.Errorf("failed to load the providers: %w", errors.Join(errs...))
+	}
+
+	reqCtx := newDebugAuthzRequestCtx(name, endpoint.Implementation, target, strings.ToUpper(method), remoteIP)
+
+	for _, header := range headers {
+		key, value, found := strings.Cut(header, ":")
+
+		if !found || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("failed to parse the header '%s': it must be in the 'Name: Value' format", header)
+		}
+
+		reqCtx.Request.Header.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	mctx := middlewares.NewAutheliaCtx(reqCtx, *ctx.config, ctx.providers)
+
+	var provider *session.Session
+
+	if provider, err = mctx.GetSessionProviderByTargetURI(target); err != nil && cookie != "" {
+		return fmt.Errorf("failed to determine the session cookie for the url: %w", err)
+	}
+
+	if cookie != "" {
+		reqCtx.Request.Header.SetCookie(provider.Config.Name, cookie)
+	}
+
+	authz := handlers.NewAuthzBuilder().WithConfig(ctx.config).WithEndpointConfig(endpoint).Build()
+
+	middlewares.NewBridgeBuilder(*ctx.config, ctx.providers).Build()(authz.Handler)(reqCtx)
+
+	subject := debugAuthzSubject(reqCtx, provider, remoteIP)
+	object := authorization.NewObject(target, strings.ToUpper(method))
+
+	debugAuthzWriteOutput(reqCtx, name, endpoint.Implementation, verbose)
+
+	results := ctx.providers.Authorizer.GetRuleMatchResults(subject, object)
+
+	if len(results) == 0 {
+		fmt.Printf("\nThe default policy '%s' was applied as no rules are configured.\n\n", ctx.config.AccessControl.DefaultPolicy)
+
+		return nil
+	}
+
+	accessControlCheckWriteOutput(object, subject, newAccessControlCheckResult(object, subject, results, ctx.config.AccessControl.DefaultPolicy, verbose))
+
+	return nil
+}
+
+func newDebugAuthzRequestCtx(name, implementation string, target *url.URL, method string, remoteIP net.IP) (ctx *fasthttp.RequestCtx) {
+	ctx = &fasthttp.RequestCtx{}
+
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}, nil)
+
+	if name == schema.AuthzEndpointNameLegacy {
+		ctx.Request.SetRequestURI(pathAuthzLegacy)
+	} else {
+		ctx.Request.SetRequestURI(path.Join(pathAuthz, name))
+	}
+
+	ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+
+	switch implementation {
+	case schema.AuthzImplementationForwardAuth:
+		ctx.Request.Header.Set(fasthttp.HeaderXForwardedProto, target.Scheme)
+		ctx.Request.Header.Set(fasthttp.HeaderXForwardedHost, target.Host)
+		ctx.Request.Header.Set(headerXForwardedURI, target.RequestURI())
+		ctx.Request.Header.Set(headerXForwardedMethod, method)
+	case schema.AuthzImplementationAuthRequest:
+		ctx.Request.Header.Set(headerXOriginalURL, target.String())
+		ctx.Request.Header.Set(headerXOriginalMethod, method)
+	case schema.AuthzImplementationExtAuthz:
+		ctx.Request.Header.SetMethod(method)
+		ctx.Request.Header.SetHost(target.Host)
+		ctx.Request.Header.Set(fasthttp.HeaderXForwardedProto, target.Scheme)
+		ctx.SetUserValue(middlewares.UserValueRouterKeyExtAuthzPath, target.RequestURI())
+	default:
+		ctx.Request.Header.Set(headerXOriginalURL, target.String())
+		ctx.Request.Header.Set(headerXForwardedMethod, method)
+	}
+
+	if remoteIP != nil {
+		ctx.Request.Header.Set(fasthttp.HeaderXForwardedFor, remoteIP.String())
+	}
+
+	return ctx
+}
+
+// debugAuthzSubject determines the subject of the replayed request from the headers emitted by the authz endpoint
+// falling back to the session.
+func debugAuthzSubject(ctx *fasthttp.RequestCtx, provider *session.Session, remoteIP net.IP) (subject authorization.Subject) {
+	subject.IP = remoteIP
+
+	if subject.IP == nil {
+		subject.IP = ctx.RemoteIP()
+	}
+
+	if username := ctx.Response.Header.Peek(headerRemoteUser); len(username) != 0 {
+		subject.Username = string(username)
+
+		if groups := ctx.Response.Header.Peek(headerRemoteGroups); len(groups) != 0 {
+			subject.Groups = strings.Split(string(groups), ",")
+		}
+
+		return subject
+	}
+
+	if provider == nil {
+		return subject
+	}
+
+	userSession, err := provider.GetSession(ctx)
+	if err != nil || userSession.IsAnonymous() {
+		return subject
+	}
+
+	subject.Username, subject.Groups = userSession.Username, userSession.Groups
+
+	return subject
+}
+
+func debugAuthzWriteOutput(ctx *fasthttp.RequestCtx, name, implementation string, verbose bool) {
+	fmt.Printf("Replayed the request against the '%s' authz endpoint (implementation '%s').\n\n", name, implementation)
+
+	fmt.Printf("Decision: %d %s\n\n", ctx.Response.StatusCode(), fasthttp.StatusMessage(ctx.Response.StatusCode()))
+
+	var lines []string
+
+	ctx.Response.Header.VisitAll(func(key, value []byte) {
+		lines = append(lines, fmt.Sprintf("    %s:\t%s", key, value))
+	})
+
+	sort.Strings(lines)
+
+	fmt.Println("Response Headers:")
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(w, line)
+	}
+
+	_ = w.Flush()
+
+	if verbose && len(ctx.Response.Body()) != 0 {
+		fmt.Printf("\nResponse Body:\n    %s\n", ctx.Response.Body())
+	}
+
+	fmt.Println()
+}
+
+func newDebugLDAPCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "ldap <username>",
+		Short:   cmdAutheliaDebugLDAPShort,
+		Long:    cmdAutheliaDebugLDAPLong,
+		Example: cmdAutheliaDebugLDAPExample,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: ctx.ChainRunE(
+			ctx.HelperConfigLoadRunE,
+			ctx.HelperConfigValidateKeysRunE,
+			ctx.HelperConfigValidateRunE,
+			ctx.ConfigValidateLogRunE,
+		),
+		RunE: ctx.DebugLDAPRunE,
+
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameBackend, "", "the name of the backend to diagnose when the ldap_backends option is configured, defaults to the first backend")
+
+	return cmd
+}
+
+// DebugLDAPRunE is the RunE for the authelia debug ldap command.
+func (ctx *CmdCtx) DebugLDAPRunE(cmd *cobra.Command, args []string) (err error) {
+	var name string
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameBackend); err != nil {
+		return err
+	}
+
+	config := ctx.config.AuthenticationBackend
+
+	if len(config.LDAPBackends) != 0 {
+		if config.LDAP, err = debugLDAPBackend(config.LDAPBackends, name); err != nil {
+			return err
+		}
+	}
+
+	if config.LDAP == nil {
+		return fmt.Errorf("the ldap authentication backend is not configured")
+	}
+
+	if _, errs := ctx.LoadTrustedCertificates(); len(errs) != 0 {
+		return fmt.Errorf("failed to load the trusted certificates: %w", errors.Join(errs...))
+	}
+
+	provider := authentication.NewLDAPUserProvider(config, ctx.trusted)
+
+	diagnostics, err := provider.Diagnose(args[0])
+
+	debugLDAPWriteOutput(diagnostics)
+
+	if err != nil {
+		return fmt.Errorf("failed to diagnose the user '%s': %w", args[0], err)
+	}
+
+	return nil
+}
+
+func debugLDAPBackend(backends []schema.AuthenticationBackendLDAPBackend, name string) (config *schema.AuthenticationBackendLDAP, err error) {
+	if name == "" {
+		return &backends[0].AuthenticationBackendLDAP, nil
+	}
+
+	for i, backend := range backends {
+		if backend.Name == name {
+			return &backends[i].AuthenticationBackendLDAP, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the ldap backend '%s' is not configured", name)
+}
+
+func debugLDAPWriteOutput(diagnostics *authentication.LDAPDiagnostics) {
+	fmt.Println("Connection:")
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "    Address:\t%s\n", diagnostics.Address)
+	_, _ = fmt.Fprintf(w, "    StartTLS:\t%t\n", diagnostics.StartTLS)
+
+	if diagnostics.TLS != nil {
+		_, _ = fmt.Fprintf(w, "    TLS:\t%s (%s)\n", tls.VersionName(diagnostics.TLS.Version), tls.CipherSuiteName(diagnostics.TLS.CipherSuite))
+
+		if len(diagnostics.TLS.PeerCertificates) != 0 {
+			certificate := diagnostics.TLS.PeerCertificates[0]
+
+			_, _ = fmt.Fprintf(w, "    Certificate:\t%s (issued by %s, expires %s)\n", certificate.Subject, certificate.Issuer, certificate.NotAfter.UTC().Format(time.RFC3339))
+		}
+	} else {
+		_, _ = fmt.Fprintln(w, "    TLS:\tnone")
+	}
+
+	_, _ = fmt.Fprintf(w, "    Password Modify Extension:\t%t\n", diagnostics.Features.Extensions.PwdModifyExOp)
+	_, _ = fmt.Fprintf(w, "    Password Policy Hints:\t%t\n", diagnostics.Features.ControlTypes.MsftPwdPolHints || diagnostics.Features.ControlTypes.MsftPwdPolHintsDeprecated)
+
+	_ = w.Flush()
+
+	fmt.Println()
+
+	if diagnostics.Users != nil {
+		debugLDAPWriteSearch("Users Search", diagnostics.Users)
+	}
+
+	if diagnostics.Groups != nil {
+		debugLDAPWriteSearch("Groups Search", diagnostics.Groups)
+	}
+
+	if diagnostics.Details != nil {
+		fmt.Println("Resolved User:")
+
+		w = tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+		_, _ = fmt.Fprintf(w, "    Username:\t%s\n", diagnostics.Details.Username)
+		_, _ = fmt.Fprintf(w, "    Display Name:\t%s\n", diagnostics.Details.DisplayName)
+		_, _ = fmt.Fprintf(w, "    Emails:\t%s\n", strings.Join(diagnostics.Details.Emails, ", "))
+		_, _ = fmt.Fprintf(w, "    Groups:\t%s\n", strings.Join(diagnostics.Details.Groups, ", "))
+
+		_ = w.Flush()
+
+		fmt.Println()
+	}
+
+	if len(diagnostics.Warnings) != 0 {
+		fmt.Println("Warnings:")
+
+		for _, warning := range diagnostics.Warnings {
+			fmt.Printf("    - %s\n", warning)
+		}
+
+		fmt.Println()
+	}
+}
+
+func debugLDAPWriteSearch(title string, search *authentication.LDAPDiagnosticsSearch) {
+	fmt.Printf("%s:\n", title)
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "    Base DN:\t%s\n", search.BaseDN)
+	_, _ = fmt.Fprintf(w, "    Filter:\t%s\n", search.Filter)
+	_, _ = fmt.Fprintf(w, "    Attributes:\t%s\n", strings.Join(search.Attributes, ", "))
+	_, _ = fmt.Fprintf(w, "    Entries:\t%d\n", len(search.Entries))
+
+	if len(search.Referrals) != 0 {
+		_, _ = fmt.Fprintf(w, "    Referrals:\t%s\n", strings.Join(search.Referrals, ", "))
+	}
+
+	_ = w.Flush()
+
+	for _, entry := range search.Entries {
+		debugLDAPWriteEntry(entry)
+	}
+
+	fmt.Println()
+}
+
+func debugLDAPWriteEntry(entry *ldap.Entry) {
+	fmt.Printf("\n    %s\n", entry.DN)
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	for _, attribute := range entry.Attributes {
+		if len(attribute.Values) == 0 {
+			_, _ = fmt.Fprintf(w, "        %s:\t(no values)\n", attribute.Name)
+
+			continue
+		}
+
+		for _, value := range attribute.Values {
+			_, _ = fmt.Fprintf(w, "        %s:\t%s\n", attribute.Name, value)
+		}
+	}
+
+	_ = w.Flush()
+}
+
+func newDebugSMTPCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "smtp",
+		Short:   cmdAutheliaDebugSMTPShort,
+		Long:    cmdAutheliaDebugSMTPLong,
+		Example: cmdAutheliaDebugSMTPExample,
+		Args:    cobra.NoArgs,
+		PreRunE: ctx.ChainRunE(
+			ctx.HelperConfigLoadRunE,
+			ctx.HelperConfigValidateKeysRunE,
+			ctx.HelperConfigValidateRunE,
+			ctx.ConfigValidateLogRunE,
+		),
+		RunE: ctx.DebugSMTPRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameTo, "", "the email address to send the test message to")
+	cmd.Flags().String(cmdFlagNameSubject, "Test Email", "the title of the test message which replaces the {title} placeholder of the configured subject")
+
+	_ = cmd.MarkFlagRequired(cmdFlagNameTo)
+
+	return cmd
+}
+
+// DebugSMTPRunE is the RunE for the authelia debug smtp command.
+func (ctx *CmdCtx) DebugSMTPRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		to, subject string
+		recipient   *mail.Address
+	)
+
+	if to, err = cmd.Flags().GetString(cmdFlagNameTo); err != nil {
+		return err
+	}
+
+	if subject, err = cmd.Flags().GetString(cmdFlagNameSubject); err != nil {
+		return err
+	}
+
+	if recipient, err = mail.ParseAddress(to); err != nil {
+		return fmt.Errorf("failed to parse the email address '%s': %w", to, err)
+	}
+
+	if ctx.config.Notifier.SMTP == nil {
+		return fmt.Errorf("the smtp notifier is not configured")
+	}
+
+	if _, errs := ctx.LoadTrustedCertificates(); len(errs) != 0 {
+		return fmt.Errorf("failed to load the trusted certificates: %w", errors.Join(errs...))
+	}
+
+	var provider *templates.Provider
+
+	if provider, err = templates.New(templates.Config{EmailTemplatesPath: ctx.config.Notifier.TemplatePath}); err != nil {
+		return fmt.Errorf("failed to load the templates: %w", err)
+	}
+
+	data := templates.EmailEventValues{
+		Title:       subject,
+		DisplayName: recipient.Name,
+		RemoteIP:    "127.0.0.1",
+		Details: map[string]any{
+			"Message": "This is a test message sent by the authelia debug smtp command.",
+		},
+	}
+
+	if data.DisplayName == "" {
+		data.DisplayName = recipient.Address
+	}
+
+	notifier := notification.NewSMTPNotifier(ctx.config.Notifier.SMTP, ctx.trusted)
+
+	steps, err := notifier.Diagnose(ctx, *recipient, subject, provider.GetEventEmailTemplate(), data)
+
+	debugSMTPWriteOutput(steps)
+
+	if err != nil {
+		return fmt.Errorf("failed to send the test message: %w", err)
+	}
+
+	fmt.Printf("Successfully sent the test message to '%s'\n", recipient.String())
+
+	return nil
+}
+
+func debugSMTPWriteOutput(steps []notification.SMTPDiagnosticsStep) {
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	for _, step := range steps {
+		if step.Err != nil {
+			_, _ = fmt.Fprintf(w, "%s:\tFAILED\t%v\n", step.Name, step.Err)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s:\tOK\t%s\n", step.Name, step.Detail)
+		}
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+}
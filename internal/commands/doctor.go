@@ -0,0 +1,558 @@
+package commands
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/ntp"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newDoctorCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "doctor",
+		Short:   cmdAutheliaDoctorShort,
+		Long:    cmdAutheliaDoctorLong,
+		Example: cmdAutheliaDoctorExample,
+		Args:    cobra.NoArgs,
+		PreRunE: ctx.ChainRunE(
+			ctx.HelperConfigLoadRunE,
+			ctx.HelperConfigValidateKeysRunE,
+			ctx.HelperConfigValidateRunE,
+			ctx.ConfigValidateLogRunE,
+		),
+		RunE: ctx.DoctorRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().Duration(cmdFlagNameExpiryThreshold, time.Hour*24*30, "the duration before a certificate expires at which the expiration is reported as a problem")
+
+	return cmd
+}
+
+// DoctorRunE is the RunE for the authelia doctor command.
+func (ctx *CmdCtx) DoctorRunE(cmd *cobra.Command, _ []string) (err error) {
+	var threshold time.Duration
+
+	if threshold, err = cmd.Flags().GetDuration(cmdFlagNameExpiryThreshold); err != nil {
+		return err
+	}
+
+	if _, errs := ctx.LoadProviders(); len(errs) != 0 {
+		return fmt.Errorf("failed to load the providers: %w", errors.Join(errs...))
+	}
+
+	var findings []doctorFinding
+
+	offset, err := ctx.providers.NTP.GetOffset()
+
+	findings = append(findings, doctorCheckClock(&ctx.config.NTP, offset, err))
+	findings = append(findings, doctorCheckSecrets(ctx.config)...)
+	findings = append(findings, doctorCheckPermissions(doctorSensitiveFiles(ctx.config, ctx.cconfig.files))...)
+	findings = append(findings, ctx.doctorCheckStorage()...)
+
+	if ctx.config.Session.Redis != nil {
+		findings = append(findings, ctx.doctorCheckRedis())
+	}
+
+	certificates, problems := doctorCertificates(ctx.config)
+
+	findings = append(findings, problems...)
+	findings = append(findings, doctorCheckCertificates(certificates, time.Now(), threshold)...)
+	findings = append(findings, doctorCheckProxy(ctx.config)...)
+
+	doctorWriteOutput(findings)
+
+	if n := doctorCount(findings, doctorSeverityCritical); n != 0 {
+		return fmt.Errorf("found %d critical problems", n)
+	}
+
+	return nil
+}
+
+type doctorSeverity int
+
+const (
+	doctorSeverityCritical doctorSeverity = iota
+	doctorSeverityWarning
+	doctorSeverityOK
+)
+
+func (s doctorSeverity) String() string {
+	switch s {
+	case doctorSeverityCritical:
+		return "CRITICAL"
+	case doctorSeverityWarning:
+		return "WARNING"
+	default:
+		return "OK"
+	}
+}
+
+// doctorFinding is the outcome of a single check performed by the doctor command.
+type doctorFinding struct {
+	severity doctorSeverity
+	check    string
+	message  string
+}
+
+// doctorFile is a file which is checked for permissions which are too permissive.
+type doctorFile struct {
+	name      string
+	path      string
+	sensitive bool
+}
+
+// doctorTLS is a TLS configuration which may contain a client certificate chain.
+type doctorTLS struct {
+	name   string
+	config *schema.TLS
+}
+
+// doctorCertificate is a certificate which is checked for expiration.
+type doctorCertificate struct {
+	source      string
+	certificate *x509.Certificate
+}
+
+const (
+	doctorCheckNameClock        = "clock"
+	doctorCheckNameSecrets      = "secrets"
+	doctorCheckNamePermissions  = "permissions"
+	doctorCheckNameStorage      = "storage"
+	doctorCheckNameRedis        = "redis"
+	doctorCheckNameCertificates = "certificates"
+	doctorCheckNameProxy        = "proxy"
+
+	doctorSecretLengthMinimum     = 20
+	doctorSecretLengthRecommended = 64
+)
+
+func doctorCheckClock(config *schema.NTP, offset time.Duration, err error) doctorFinding {
+	servers := ntp.Servers(config)
+
+	addresses := make([]string, len(servers))
+
+	for i, server := range servers {
+		addresses[i] = server.String()
+	}
+
+	address := strings.Join(addresses, "', '")
+
+	switch {
+	case err != nil:
+		return doctorFinding{doctorSeverityWarning, doctorCheckNameClock, fmt.Sprintf("the clock skew could not be determined using the NTP servers '%s': %v", address, err)}
+	case offset > config.MaximumDesync:
+		return doctorFinding{doctorSeverityCritical, doctorCheckNameClock, fmt.Sprintf("the clock skew of %s against the NTP servers '%s' exceeds the maximum desync of %s, one-time passwords and tokens may be rejected", offset, address, config.MaximumDesync)}
+	case offset > config.MaximumDesync/2:
+		return doctorFinding{doctorSeverityWarning, doctorCheckNameClock, fmt.Sprintf("the clock skew of %s against the NTP servers '%s' is approaching the maximum desync of %s", offset, address, config.MaximumDesync)}
+	default:
+		return doctorFinding{doctorSeverityOK, doctorCheckNameClock, fmt.Sprintf("the clock skew of %s against the NTP servers '%s' is within the maximum desync of %s", offset, address, config.MaximumDesync)}
+	}
+}
+
+func doctorCheckSecrets(config *schema.Configuration) (findings []doctorFinding) {
+	secrets := [][2]string{
+		{"session.secret", config.Session.Secret},
+		{"storage.encryption_key", config.Storage.EncryptionKey},
+	}
+
+	if !config.AuthenticationBackend.PasswordReset.Disable {
+		secrets = append(secrets, [2]string{"identity_validation.reset_password.jwt_secret", config.IdentityValidation.ResetPassword.JWTSecret})
+	}
+
+	if config.IdentityProviders.OIDC != nil {
+		secrets = append(secrets, [2]string{"identity_providers.oidc.hmac_secret", config.IdentityProviders.OIDC.HMACSecret})
+	}
+
+	seen := map[string]string{}
+
+	n := 0
+
+	for _, secret := range secrets {
+		name, value := secret[0], secret[1]
+
+		if value == "" {
+			continue
+		}
+
+		n++
+
+		switch length := len(value); {
+		case length < doctorSecretLengthMinimum:
+			findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameSecrets, fmt.Sprintf("the '%s' secret is %d characters long which is shorter than the minimum of %d characters", name, length, doctorSecretLengthMinimum)})
+		case length < doctorSecretLengthRecommended:
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameSecrets, fmt.Sprintf("the '%s' secret is %d characters long which is shorter than the recommended %d characters", name, length, doctorSecretLengthRecommended)})
+		}
+
+		if other, ok := seen[value]; ok {
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameSecrets, fmt.Sprintf("the '%s' secret is the same as the '%s' secret, each secret should be unique", name, other)})
+		} else {
+			seen[value] = name
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorSeverityOK, doctorCheckNameSecrets, fmt.Sprintf("the %d secrets are unique and at least %d characters long", n, doctorSecretLengthRecommended)})
+	}
+
+	return findings
+}
+
+// doctorSensitiveFiles returns the files which contain secrets or other sensitive information.
+func doctorSensitiveFiles(config *schema.Configuration, configs []string) (files []doctorFile) {
+	for _, path := range configs {
+		files = append(files, doctorFile{name: "configuration", path: path})
+	}
+
+	if config.AuthenticationBackend.File != nil {
+		files = append(files, doctorFile{name: "users database", path: config.AuthenticationBackend.File.Path, sensitive: true})
+	}
+
+	if config.Storage.Local != nil {
+		files = append(files, doctorFile{name: "storage database", path: config.Storage.Local.Path, sensitive: true})
+	}
+
+	if config.Server.TLS.Key != "" {
+		files = append(files, doctorFile{name: "server private key", path: config.Server.TLS.Key, sensitive: true})
+	}
+
+	return files
+}
+
+func doctorCheckPermissions(files []doctorFile) (findings []doctorFinding) {
+	if runtime.GOOS == "windows" {
+		return []doctorFinding{{doctorSeverityOK, doctorCheckNamePermissions, "the file permissions are not checked on windows"}}
+	}
+
+	n := 0
+
+	for _, file := range files {
+		info, err := os.Stat(file.path)
+
+		switch {
+		case err == nil:
+		case os.IsNotExist(err):
+			continue
+		default:
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNamePermissions, fmt.Sprintf("the %s file '%s' could not be checked: %v", file.name, file.path, err)})
+
+			continue
+		}
+
+		n++
+
+		perm := info.Mode().Perm()
+
+		switch {
+		case perm&0o002 != 0:
+			findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNamePermissions, fmt.Sprintf("the %s file '%s' has the mode %04o which allows all users to modify it", file.name, file.path, perm)})
+		case perm&0o004 != 0 && file.sensitive:
+			findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNamePermissions, fmt.Sprintf("the %s file '%s' has the mode %04o which allows all users to read it", file.name, file.path, perm)})
+		case perm&0o004 != 0, perm&0o020 != 0:
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNamePermissions, fmt.Sprintf("the %s file '%s' has the mode %04o which should be restricted to the owner as it may contain secrets", file.name, file.path, perm)})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorSeverityOK, doctorCheckNamePermissions, fmt.Sprintf("the %d files containing sensitive information have restrictive permissions", n)})
+	}
+
+	return findings
+}
+
+func (ctx *CmdCtx) doctorCheckStorage() (findings []doctorFinding) {
+	var (
+		version, latest int
+		err             error
+	)
+
+	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+		return []doctorFinding{{doctorSeverityCritical, doctorCheckNameStorage, fmt.Sprintf("the schema version could not be determined: %v", err)}}
+	}
+
+	if latest, err = ctx.providers.StorageProvider.SchemaLatestVersion(); err != nil {
+		return []doctorFinding{{doctorSeverityCritical, doctorCheckNameStorage, fmt.Sprintf("the latest schema version could not be determined: %v", err)}}
+	}
+
+	findings = append(findings, doctorCheckStorageVersion(version, latest))
+
+	if version == 0 || version > latest {
+		return findings
+	}
+
+	var result storage.EncryptionValidationResult
+
+	if result, err = ctx.providers.StorageProvider.SchemaEncryptionCheckKey(ctx, false); err != nil && !errors.Is(err, storage.ErrSchemaEncryptionVersionUnsupported) {
+		return append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameStorage, fmt.Sprintf("the encryption key could not be checked: %v", err)})
+	}
+
+	if !result.Success() {
+		return append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameStorage, "the encryption key is not the key which was used to encrypt the data in the storage"})
+	}
+
+	return findings
+}
+
+func doctorCheckStorageVersion(version, latest int) doctorFinding {
+	switch {
+	case version == 0:
+		return doctorFinding{doctorSeverityWarning, doctorCheckNameStorage, "the schema does not exist and will be created when starting"}
+	case version < latest:
+		return doctorFinding{doctorSeverityWarning, doctorCheckNameStorage, fmt.Sprintf("the schema version %d is outdated and will be migrated to version %d when starting, a backup should be taken beforehand", version, latest)}
+	case version > latest:
+		return doctorFinding{doctorSeverityCritical, doctorCheckNameStorage, fmt.Sprintf("the schema version %d is newer than the latest schema version %d supported by this version of Authelia", version, latest)}
+	default:
+		return doctorFinding{doctorSeverityOK, doctorCheckNameStorage, fmt.Sprintf("the schema version %d is up to date", version)}
+	}
+}
+
+func (ctx *CmdCtx) doctorCheckRedis() doctorFinding {
+	config := ctx.config.Session.Redis
+
+	address := config.Host
+
+	if !strings.HasPrefix(address, "/") {
+		address = net.JoinHostPort(config.Host, strconv.Itoa(config.Port))
+	}
+
+	if err := ctx.providers.SessionProvider.HealthCheck(ctx); err != nil {
+		return doctorFinding{doctorSeverityCritical, doctorCheckNameRedis, fmt.Sprintf("redis at '%s' is not reachable: %v", address, err)}
+	}
+
+	return doctorFinding{doctorSeverityOK, doctorCheckNameRedis, fmt.Sprintf("redis at '%s' is reachable", address)}
+}
+
+// doctorCertificates returns the certificates from the configuration and the certificates directory, and findings for
+// any certificate files which could not be read.
+func doctorCertificates(config *schema.Configuration) (certificates []doctorCertificate, findings []doctorFinding) {
+	var paths []string
+
+	if config.Server.TLS.Certificate != "" {
+		paths = append(paths, config.Server.TLS.Certificate)
+	}
+
+	if config.CertificatesDirectory != "" {
+		if entries, err := os.ReadDir(config.CertificatesDirectory); err == nil {
+			for _, entry := range entries {
+				switch ext := strings.ToLower(filepath.Ext(entry.Name())); {
+				case entry.IsDir():
+					continue
+				case ext == ".cer", ext == ".crt", ext == ".pem":
+					paths = append(paths, filepath.Join(config.CertificatesDirectory, entry.Name()))
+				}
+			}
+		}
+	}
+
+	for _, path := range paths {
+		parsed, err := doctorReadCertificates(path)
+		if err != nil {
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameCertificates, fmt.Sprintf("the certificates in the file '%s' could not be read: %v", path, err)})
+
+			continue
+		}
+
+		for _, certificate := range parsed {
+			certificates = append(certificates, doctorCertificate{path, certificate})
+		}
+	}
+
+	var chains []doctorTLS
+
+	if config.AuthenticationBackend.LDAP != nil {
+		chains = append(chains, doctorTLS{"authentication_backend.ldap.tls", config.AuthenticationBackend.LDAP.TLS})
+	}
+
+	for i, backend := range config.AuthenticationBackend.LDAPBackends {
+		chains = append(chains, doctorTLS{fmt.Sprintf("authentication_backend.ldap_backends[%d].tls", i), backend.TLS})
+	}
+
+	if config.Notifier.SMTP != nil {
+		chains = append(chains, doctorTLS{"notifier.smtp.tls", config.Notifier.SMTP.TLS})
+	}
+
+	if config.Session.Redis != nil {
+		chains = append(chains, doctorTLS{"session.redis.tls", config.Session.Redis.TLS})
+	}
+
+	if config.Storage.MySQL != nil {
+		chains = append(chains, doctorTLS{"storage.mysql.tls", config.Storage.MySQL.TLS})
+	}
+
+	if config.Storage.PostgreSQL != nil {
+		chains = append(chains, doctorTLS{"storage.postgres.tls", config.Storage.PostgreSQL.TLS})
+	}
+
+	if config.Storage.CockroachDB != nil {
+		chains = append(chains, doctorTLS{"storage.cockroachdb.tls", config.Storage.CockroachDB.TLS})
+	}
+
+	for _, chain := range chains {
+		if chain.config == nil {
+			continue
+		}
+
+		for _, certificate := range chain.config.CertificateChain.Certificates() {
+			certificates = append(certificates, doctorCertificate{chain.name, certificate})
+		}
+	}
+
+	if config.IdentityProviders.OIDC != nil {
+		for i, jwk := range config.IdentityProviders.OIDC.JSONWebKeys {
+			for _, certificate := range jwk.CertificateChain.Certificates() {
+				certificates = append(certificates, doctorCertificate{fmt.Sprintf("identity_providers.oidc.jwks[%d]", i), certificate})
+			}
+		}
+	}
+
+	return certificates, findings
+}
+
+func doctorReadCertificates(path string) (certificates []*x509.Certificate, err error) {
+	var data []byte
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	var block *pem.Block
+
+	for {
+		if block, data = pem.Decode(data); block == nil {
+			break
+		}
+
+		if block.Type != utils.BlockTypeCertificate {
+			continue
+		}
+
+		var certificate *x509.Certificate
+
+		if certificate, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("the file does not contain any PEM encoded certificates")
+	}
+
+	return certificates, nil
+}
+
+func doctorCheckCertificates(certificates []doctorCertificate, now time.Time, threshold time.Duration) (findings []doctorFinding) {
+	var next *doctorCertificate
+
+	for i, c := range certificates {
+		name := doctorCertificateName(c.certificate)
+
+		switch {
+		case now.After(c.certificate.NotAfter):
+			findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameCertificates, fmt.Sprintf("the certificate '%s' from '%s' expired on %s", name, c.source, c.certificate.NotAfter.UTC().Format(time.RFC3339))})
+		case now.Before(c.certificate.NotBefore):
+			findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameCertificates, fmt.Sprintf("the certificate '%s' from '%s' is not valid until %s", name, c.source, c.certificate.NotBefore.UTC().Format(time.RFC3339))})
+		case c.certificate.NotAfter.Sub(now) < threshold:
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameCertificates, fmt.Sprintf("the certificate '%s' from '%s' expires in %d days on %s", name, c.source, int(c.certificate.NotAfter.Sub(now).Hours()/24), c.certificate.NotAfter.UTC().Format(time.RFC3339))})
+		}
+
+		if next == nil || c.certificate.NotAfter.Before(next.certificate.NotAfter) {
+			next = &certificates[i]
+		}
+	}
+
+	switch {
+	case len(findings) != 0:
+		return findings
+	case next == nil:
+		return []doctorFinding{{doctorSeverityOK, doctorCheckNameCertificates, "there are no certificates configured"}}
+	default:
+		return []doctorFinding{{doctorSeverityOK, doctorCheckNameCertificates, fmt.Sprintf("the %d certificates are valid and the next to expire is '%s' from '%s' on %s", len(certificates), doctorCertificateName(next.certificate), next.source, next.certificate.NotAfter.UTC().Format(time.RFC3339))}}
+	}
+}
+
+func doctorCertificateName(certificate *x509.Certificate) string {
+	if certificate.Subject.CommonName != "" {
+		return certificate.Subject.CommonName
+	}
+
+	return certificate.Subject.String()
+}
+
+func doctorCheckProxy(config *schema.Configuration) (findings []doctorFinding) {
+	if len(config.Server.Endpoints.Authz) == 0 {
+		findings = append(findings, doctorFinding{doctorSeverityCritical, doctorCheckNameProxy, "there are no authz endpoints configured so a proxy can't use Authelia to authorize requests"})
+	}
+
+	var listenerPath, listenerPort string
+
+	if config.Server.Address != nil {
+		listenerPath, listenerPort = strings.TrimSuffix(config.Server.Address.RouterPath(), "/"), strconv.Itoa(config.Server.Address.Port())
+	}
+
+	for _, cookie := range config.Session.Cookies {
+		if cookie.AutheliaURL == nil {
+			continue
+		}
+
+		if path := strings.TrimSuffix(cookie.AutheliaURL.Path, "/"); path != listenerPath {
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameProxy, fmt.Sprintf("the authelia_url '%s' for the session cookie domain '%s' has the path '%s/' but the server address has the path '%s/', the path of the server address should usually match the path of the authelia_url as the proxy must otherwise rewrite the path", cookie.AutheliaURL, cookie.Domain, path, listenerPath)})
+		}
+
+		if cookie.AutheliaURL.Scheme == "https" && cookie.AutheliaURL.Port() == listenerPort && config.Server.TLS.Certificate == "" {
+			findings = append(findings, doctorFinding{doctorSeverityWarning, doctorCheckNameProxy, fmt.Sprintf("the authelia_url '%s' for the session cookie domain '%s' uses the https scheme with port %s which is the port the server listens on without TLS, the authelia_url should be the URL of the TLS terminating proxy", cookie.AutheliaURL, cookie.Domain, listenerPort)})
+		}
+	}
+
+	if len(findings) == 0 {
+		findings = append(findings, doctorFinding{doctorSeverityOK, doctorCheckNameProxy, "no common proxy misconfigurations were detected"})
+	}
+
+	return findings
+}
+
+func doctorCount(findings []doctorFinding, severity doctorSeverity) (n int) {
+	for _, finding := range findings {
+		if finding.severity == severity {
+			n++
+		}
+	}
+
+	return n
+}
+
+func doctorWriteOutput(findings []doctorFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].severity < findings[j].severity
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "Severity\tCheck\tFinding")
+
+	for _, finding := range findings {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", finding.severity, finding.check, finding.message)
+	}
+
+	_ = w.Flush()
+
+	fmt.Printf("\nFound %d critical problems and %d warnings.\n", doctorCount(findings, doctorSeverityCritical), doctorCount(findings, doctorSeverityWarning))
+}
@@ -0,0 +1,251 @@
+package commands
+
+import (
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestDoctorCheckClock(t *testing.T) {
+	config := &schema.NTP{Address: &schema.AddressUDP{Address: schema.NewAddressFromNetworkValues("udp", "time.cloudflare.com", 123)}, MaximumDesync: time.Second * 3}
+
+	testCases := []struct {
+		name     string
+		offset   time.Duration
+		err      error
+		expected doctorSeverity
+	}{
+		{"ShouldBeOK", time.Millisecond * 20, nil, doctorSeverityOK},
+		{"ShouldWarnApproaching", time.Second * 2, nil, doctorSeverityWarning},
+		{"ShouldBeCriticalExceeds", time.Second * 4, nil, doctorSeverityCritical},
+		{"ShouldWarnError", 0, errors.New("error occurred during dial: timeout"), doctorSeverityWarning},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := doctorCheckClock(config, tc.offset, tc.err)
+
+			assert.Equal(t, tc.expected, actual.severity)
+			assert.Equal(t, doctorCheckNameClock, actual.check)
+		})
+	}
+}
+
+func TestDoctorCheckSecrets(t *testing.T) {
+	long := strings.Repeat("a", doctorSecretLengthRecommended)
+
+	testCases := []struct {
+		name     string
+		config   *schema.Configuration
+		expected []doctorFinding
+	}{
+		{
+			"ShouldBeOK",
+			&schema.Configuration{
+				Session:               schema.Session{Secret: long},
+				Storage:               schema.Storage{EncryptionKey: strings.Repeat("b", doctorSecretLengthRecommended)},
+				AuthenticationBackend: schema.AuthenticationBackend{PasswordReset: schema.AuthenticationBackendPasswordReset{Disable: true}},
+			},
+			[]doctorFinding{{doctorSeverityOK, doctorCheckNameSecrets, "the 2 secrets are unique and at least 64 characters long"}},
+		},
+		{
+			"ShouldReportShortAndDuplicate",
+			&schema.Configuration{
+				Session:            schema.Session{Secret: "insecure_secret"},
+				Storage:            schema.Storage{EncryptionKey: "a_not_so_secure_encryption_key"},
+				IdentityValidation: schema.IdentityValidation{ResetPassword: schema.IdentityValidationResetPassword{JWTSecret: long}},
+				IdentityProviders:  schema.IdentityProviders{OIDC: &schema.IdentityProvidersOpenIDConnect{HMACSecret: long}},
+			},
+			[]doctorFinding{
+				{doctorSeverityCritical, doctorCheckNameSecrets, "the 'session.secret' secret is 15 characters long which is shorter than the minimum of 20 characters"},
+				{doctorSeverityWarning, doctorCheckNameSecrets, "the 'storage.encryption_key' secret is 30 characters long which is shorter than the recommended 64 characters"},
+				{doctorSeverityWarning, doctorCheckNameSecrets, "the 'identity_providers.oidc.hmac_secret' secret is the same as the 'identity_validation.reset_password.jwt_secret' secret, each secret should be unique"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, doctorCheckSecrets(tc.config))
+		})
+	}
+}
+
+func TestDoctorCheckPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the file permissions are not checked on windows")
+	}
+
+	dir := t.TempDir()
+
+	write := func(name string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+
+		require.NoError(t, os.WriteFile(path, []byte("data"), 0600))
+		require.NoError(t, os.Chmod(path, mode))
+
+		return path
+	}
+
+	restricted := write("restricted.yml", 0600)
+	readable := write("readable.yml", 0644)
+	writable := write("writable.yml", 0666)
+
+	testCases := []struct {
+		name     string
+		files    []doctorFile
+		expected []doctorSeverity
+	}{
+		{"ShouldBeOK", []doctorFile{{name: "configuration", path: restricted}, {name: "users database", path: filepath.Join(dir, "missing.yml"), sensitive: true}}, []doctorSeverity{doctorSeverityOK}},
+		{"ShouldWarnReadable", []doctorFile{{name: "configuration", path: readable}}, []doctorSeverity{doctorSeverityWarning}},
+		{"ShouldBeCriticalReadableSensitive", []doctorFile{{name: "users database", path: readable, sensitive: true}}, []doctorSeverity{doctorSeverityCritical}},
+		{"ShouldBeCriticalWritable", []doctorFile{{name: "configuration", path: writable}, {name: "configuration", path: restricted}}, []doctorSeverity{doctorSeverityCritical}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []doctorSeverity
+
+			for _, finding := range doctorCheckPermissions(tc.files) {
+				actual = append(actual, finding.severity)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestDoctorCheckStorageVersion(t *testing.T) {
+	assert.Equal(t, doctorSeverityWarning, doctorCheckStorageVersion(0, 16).severity)
+	assert.Equal(t, doctorSeverityWarning, doctorCheckStorageVersion(15, 16).severity)
+	assert.Equal(t, doctorSeverityCritical, doctorCheckStorageVersion(17, 16).severity)
+	assert.Equal(t, doctorFinding{doctorSeverityOK, doctorCheckNameStorage, "the schema version 16 is up to date"}, doctorCheckStorageVersion(16, 16))
+}
+
+func TestDoctorCheckCertificates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	certificate := func(name string, notBefore, notAfter time.Time) *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: name}, NotBefore: notBefore, NotAfter: notAfter}
+	}
+
+	valid := doctorCertificate{"server.tls.certificate", certificate("auth.example.com", now.AddDate(0, -1, 0), now.AddDate(1, 0, 0))}
+	soon := doctorCertificate{"session.redis.tls", certificate("redis", now.AddDate(0, -1, 0), now.AddDate(0, 0, 10))}
+
+	testCases := []struct {
+		name         string
+		certificates []doctorCertificate
+		expected     []doctorFinding
+	}{
+		{
+			"ShouldBeOKNone",
+			nil,
+			[]doctorFinding{{doctorSeverityOK, doctorCheckNameCertificates, "there are no certificates configured"}},
+		},
+		{
+			"ShouldBeOK",
+			[]doctorCertificate{valid, {"certificates", certificate("Example Root CA", now.AddDate(-1, 0, 0), now.AddDate(2, 0, 0))}},
+			[]doctorFinding{{doctorSeverityOK, doctorCheckNameCertificates, "the 2 certificates are valid and the next to expire is 'auth.example.com' from 'server.tls.certificate' on 2027-01-01T00:00:00Z"}},
+		},
+		{
+			"ShouldReportExpiringAndExpired",
+			[]doctorCertificate{valid, soon, {"storage.postgres.tls", certificate("postgres", now.AddDate(-1, 0, 0), now.AddDate(0, 0, -1))}},
+			[]doctorFinding{
+				{doctorSeverityWarning, doctorCheckNameCertificates, "the certificate 'redis' from 'session.redis.tls' expires in 10 days on 2026-01-11T00:00:00Z"},
+				{doctorSeverityCritical, doctorCheckNameCertificates, "the certificate 'postgres' from 'storage.postgres.tls' expired on 2025-12-31T00:00:00Z"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, doctorCheckCertificates(tc.certificates, now, time.Hour*24*30))
+		})
+	}
+}
+
+func TestDoctorReadCertificates(t *testing.T) {
+	dir := t.TempDir()
+
+	certPEM, keyPEM, err := utils.GenerateCertificate(utils.ECDSAKeyBuilder{}.WithCurve(elliptic.P256()), []string{"example.com"}, time.Now(), time.Hour, false)
+
+	require.NoError(t, err)
+
+	bundle := filepath.Join(dir, "bundle.pem")
+	key := filepath.Join(dir, "key.pem")
+
+	require.NoError(t, os.WriteFile(bundle, append(append([]byte{}, keyPEM...), certPEM...), 0600))
+	require.NoError(t, os.WriteFile(key, keyPEM, 0600))
+
+	certificates, err := doctorReadCertificates(bundle)
+
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+	assert.Equal(t, []string{"example.com"}, certificates[0].DNSNames)
+
+	_, err = doctorReadCertificates(key)
+
+	assert.EqualError(t, err, "the file does not contain any PEM encoded certificates")
+}
+
+func TestDoctorCheckProxy(t *testing.T) {
+	mustParseURL := func(raw string) *url.URL {
+		u, err := url.Parse(raw)
+
+		require.NoError(t, err)
+
+		return u
+	}
+
+	testCases := []struct {
+		name     string
+		server   schema.Server
+		cookies  []schema.SessionCookie
+		expected []doctorSeverity
+	}{
+		{
+			"ShouldBeOK",
+			schema.Server{Address: &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "0.0.0.0", 9091)}, Endpoints: schema.ServerEndpoints{Authz: schema.DefaultServerConfiguration.Endpoints.Authz}},
+			[]schema.SessionCookie{{Domain: "example.com", AutheliaURL: mustParseURL("https://auth.example.com/")}},
+			[]doctorSeverity{doctorSeverityOK},
+		},
+		{
+			"ShouldBeCriticalNoAuthz",
+			schema.Server{Address: &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "0.0.0.0", 9091)}},
+			[]schema.SessionCookie{{Domain: "example.com", AutheliaURL: mustParseURL("https://auth.example.com/")}},
+			[]doctorSeverity{doctorSeverityCritical},
+		},
+		{
+			"ShouldWarnPathMismatchAndPlaintextPort",
+			schema.Server{Address: &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "0.0.0.0", 9091)}, Endpoints: schema.ServerEndpoints{Authz: schema.DefaultServerConfiguration.Endpoints.Authz}},
+			[]schema.SessionCookie{{Domain: "example.com", AutheliaURL: mustParseURL("https://example.com:9091/authelia/")}},
+			[]doctorSeverity{doctorSeverityWarning, doctorSeverityWarning},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actual []doctorSeverity
+
+			for _, finding := range doctorCheckProxy(&schema.Configuration{Server: tc.server, Session: schema.Session{Cookies: tc.cookies}}) {
+				actual = append(actual, finding.severity)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/metrics"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// newConfigurationDriftDetect returns a function which publishes the fingerprint of the effective configuration of this
+// instance to the storage provider and returns the other instances which have published a different fingerprint. The
+// fingerprints of instances which haven't published one within the configured expiration are pruned and ignored.
+func newConfigurationDriftDetect(instance string, config func() *schema.Configuration, provider storage.Provider, recorder metrics.Recorder, clock clock.Provider) func(ctx context.Context) (drifted []string, err error) {
+	return func(ctx context.Context) (drifted []string, err error) {
+		now := clock.Now()
+
+		fingerprint := model.ConfigurationFingerprint{
+			Instance:    instance,
+			Fingerprint: configuration.Fingerprint(config()),
+			Version:     utils.Version(),
+			UpdatedAt:   now,
+		}
+
+		if err = provider.SaveConfigurationFingerprint(ctx, fingerprint); err != nil {
+			return nil, err
+		}
+
+		expired := now.Add(-driftConfigurationExpiration)
+
+		if err = provider.DeleteConfigurationFingerprints(ctx, expired); err != nil {
+			return nil, err
+		}
+
+		var fingerprints []model.ConfigurationFingerprint
+
+		if fingerprints, err = provider.LoadConfigurationFingerprints(ctx, expired); err != nil {
+			return nil, err
+		}
+
+		for _, f := range fingerprints {
+			if f.Instance == instance || f.Fingerprint == fingerprint.Fingerprint {
+				continue
+			}
+
+			if f.Version != fingerprint.Version {
+				drifted = append(drifted, fmt.Sprintf("%s (version %s)", f.Instance, f.Version))
+			} else {
+				drifted = append(drifted, f.Instance)
+			}
+		}
+
+		if recorder != nil {
+			recorder.RecordConfigurationDrift(len(drifted))
+		}
+
+		return drifted, nil
+	}
+}
+
+// configurationDriftInstance returns the name this instance publishes its configuration fingerprint with, which is
+// the hostname as it's unique per replica in most deployments, or a random value if the hostname is unavailable.
+func configurationDriftInstance(r random.Provider) string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+
+	return r.StringCustom(16, random.CharSetAlphaNumeric)
+}
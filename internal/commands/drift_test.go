@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestConfigurationDriftDetect(t *testing.T) {
+	config := &schema.Configuration{Theme: "dark"}
+
+	fingerprint := configuration.Fingerprint(config)
+
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		name         string
+		fingerprints []model.ConfigurationFingerprint
+		err          error
+		expected     []string
+		expectedErr  string
+	}{
+		{
+			"ShouldNotDetectDrift",
+			[]model.ConfigurationFingerprint{
+				{Instance: "a", Fingerprint: fingerprint, Version: utils.Version()},
+				{Instance: "b", Fingerprint: fingerprint, Version: utils.Version()},
+			},
+			nil,
+			nil,
+			"",
+		},
+		{
+			"ShouldDetectDrift",
+			[]model.ConfigurationFingerprint{
+				{Instance: "a", Fingerprint: fingerprint, Version: utils.Version()},
+				{Instance: "b", Fingerprint: "abc", Version: utils.Version()},
+				{Instance: "c", Fingerprint: "abc", Version: "v1.0.0"},
+			},
+			nil,
+			[]string{"b", "c (version v1.0.0)"},
+			"",
+		},
+		{
+			"ShouldReturnErr",
+			nil,
+			errors.New("bad conn"),
+			nil,
+			"bad conn",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			provider := mocks.NewMockStorage(ctrl)
+
+			gomock.InOrder(
+				provider.EXPECT().SaveConfigurationFingerprint(gomock.Any(), model.ConfigurationFingerprint{Instance: "a", Fingerprint: fingerprint, Version: utils.Version(), UpdatedAt: now}).Return(nil),
+				provider.EXPECT().DeleteConfigurationFingerprints(gomock.Any(), now.Add(-driftConfigurationExpiration)).Return(nil),
+				provider.EXPECT().LoadConfigurationFingerprints(gomock.Any(), now.Add(-driftConfigurationExpiration)).Return(tc.fingerprints, tc.err),
+			)
+
+			detect := newConfigurationDriftDetect("a", func() *schema.Configuration { return config }, provider, nil, clock.NewFixed(now))
+
+			drifted, err := detect(context.Background())
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expected, drifted)
+		})
+	}
+}
@@ -1,21 +1,32 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/base32"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func getStorageProvider(ctx *CmdCtx) (provider storage.Provider) {
 	switch {
 	case ctx.config.Storage.PostgreSQL != nil:
 		return storage.NewPostgreSQLProvider(ctx.config, ctx.trusted)
+	case ctx.config.Storage.CockroachDB != nil:
+		return storage.NewCockroachDBProvider(ctx.config, ctx.trusted)
 	case ctx.config.Storage.MySQL != nil:
 		return storage.NewMySQLProvider(ctx.config, ctx.trusted)
 	case ctx.config.Storage.Local != nil:
@@ -68,6 +79,470 @@ func storageTOTPGenerateRunEOptsFromFlags(flags *pflag.FlagSet) (force bool, fil
 	return force, filename, secret, nil
 }
 
+// storageTOTPReadPassphrase reads the passphrase for an encrypted bundle from the flag or the terminal, prompting for
+// confirmation when reading from the terminal if confirm is true.
+func storageTOTPReadPassphrase(flags *pflag.FlagSet, confirm bool) (passphrase string, err error) {
+	if flags.Changed(cmdFlagNamePassphrase) {
+		if passphrase, err = flags.GetString(cmdFlagNamePassphrase); err != nil {
+			return "", err
+		}
+	} else {
+		if passphrase, err = termReadPasswordWithPrompt("Enter Passphrase: ", cmdFlagNamePassphrase); err != nil {
+			return "", err
+		}
+
+		if confirm {
+			var confirmation string
+
+			if confirmation, err = termReadPasswordWithPrompt("Confirm Passphrase: ", cmdFlagNamePassphrase); err != nil {
+				return "", err
+			}
+
+			if passphrase != confirmation {
+				return "", errors.New("the passphrase and the confirmation don't match")
+			}
+		}
+	}
+
+	if passphrase == "" {
+		return "", errors.New("the passphrase must not be blank")
+	}
+
+	return passphrase, nil
+}
+
+// storageTOTPIsURIs returns true if the first line of the data which isn't blank is a TOTP URI.
+func storageTOTPIsURIs(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("otpauth://"))
+}
+
+// storageTOTPConfigurationsFromURIs parses the TOTP URIs with one URI per line, ignoring blank lines and lines starting
+// with '#'.
+func storageTOTPConfigurationsFromURIs(data []byte, now time.Time) (configs []model.TOTPConfiguration, err error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var config model.TOTPConfiguration
+
+	for i := 1; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if config, err = storageTOTPConfigurationFromURI(line, now); err != nil {
+			return nil, fmt.Errorf("error parsing the URI on line %d: %w", i, err)
+		}
+
+		configs = append(configs, config)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// storageTOTPConfigurationFromURI parses a TOTP URI with the 'otpauth://totp/issuer:username?secret=...' format using
+// the defaults of the format for the algorithm, digits, and period if they're omitted.
+func storageTOTPConfigurationFromURI(uri string, now time.Time) (config model.TOTPConfiguration, err error) {
+	var u *url.URL
+
+	if u, err = url.Parse(uri); err != nil {
+		return config, err
+	}
+
+	switch {
+	case u.Scheme != "otpauth":
+		return config, fmt.Errorf("the URI has the '%s' scheme but it must have the 'otpauth' scheme", u.Scheme)
+	case u.Host != "totp":
+		return config, fmt.Errorf("the URI has the '%s' type but only the 'totp' type is supported", u.Host)
+	}
+
+	query := u.Query()
+
+	issuer, username, found := strings.Cut(strings.TrimPrefix(u.Path, "/"), ":")
+	if !found {
+		issuer, username = "", issuer
+	}
+
+	if value := query.Get("issuer"); value != "" {
+		issuer = value
+	}
+
+	config = model.TOTPConfiguration{
+		CreatedAt: now,
+		Username:  strings.TrimSpace(username),
+		Issuer:    strings.TrimSpace(issuer),
+		Algorithm: schema.TOTPAlgorithmSHA1,
+		Digits:    6,
+		Period:    30,
+		Secret:    []byte(strings.ToUpper(strings.TrimRight(query.Get("secret"), "="))),
+	}
+
+	if config.Username == "" {
+		return config, errors.New("the URI does not have a username")
+	}
+
+	var decoded []byte
+
+	if decoded, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(string(config.Secret)); err != nil || len(decoded) == 0 {
+		return config, errors.New("the URI does not have a valid base32 secret")
+	}
+
+	if value := query.Get("algorithm"); value != "" {
+		if config.Algorithm = strings.ToUpper(value); !utils.IsStringInSlice(config.Algorithm, schema.TOTPPossibleAlgorithms) {
+			return config, fmt.Errorf("the URI has the '%s' algorithm but it must be one of %s", value, utils.StringJoinOr(schema.TOTPPossibleAlgorithms))
+		}
+	}
+
+	var n uint64
+
+	if value := query.Get("digits"); value != "" {
+		if n, err = strconv.ParseUint(value, 10, 32); err != nil || (n != 6 && n != 8) {
+			return config, fmt.Errorf("the URI has the '%s' digits but it must be 6 or 8", value)
+		}
+
+		config.Digits = uint(n)
+	}
+
+	if value := query.Get("period"); value != "" {
+		if n, err = strconv.ParseUint(value, 10, 32); err != nil || n == 0 {
+			return config, fmt.Errorf("the URI has the '%s' period but it must be a positive integer", value)
+		}
+
+		config.Period = uint(n)
+	}
+
+	return config, nil
+}
+
+// storageEncryptionCheckResult is the result of the authelia storage encryption check command.
+type storageEncryptionCheckResult struct {
+	Result string                              `json:"result"`
+	Cause  string                              `json:"cause,omitempty"`
+	Tables []storageEncryptionCheckTableResult `json:"tables,omitempty"`
+}
+
+// storageEncryptionCheckTableResult is the result of the authelia storage encryption check command for a table.
+type storageEncryptionCheckTableResult struct {
+	Name    string `json:"name"`
+	Result  string `json:"result"`
+	Invalid int    `json:"invalid"`
+	Total   int    `json:"total"`
+}
+
+// storageEncryptionRotateKeyResult is the result of the authelia storage encryption rotate-key command.
+type storageEncryptionRotateKeyResult struct {
+	Rotated int                                     `json:"rotated"`
+	Tables  []storageEncryptionRotateKeyTableResult `json:"tables"`
+}
+
+// storageEncryptionRotateKeyTableResult is the result of the authelia storage encryption rotate-key command for a table.
+type storageEncryptionRotateKeyTableResult struct {
+	Name    string `json:"name"`
+	Rotated int    `json:"rotated"`
+	Skipped int    `json:"skipped"`
+	Total   int    `json:"total"`
+}
+
+// storageSchemaInfoResult is the result of the authelia storage schema-info command.
+type storageSchemaInfoResult struct {
+	Version           int      `json:"version"`
+	LatestVersion     int      `json:"latest_version"`
+	CompatibleVersion int      `json:"compatible_version"`
+	UpgradeAvailable  bool     `json:"upgrade_available"`
+	Tables            []string `json:"tables"`
+	Encryption        string   `json:"encryption"`
+}
+
+// storageSchemaMigration is the output of the authelia storage migrate list-up and list-down commands for a migration.
+type storageSchemaMigration struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// storageMigrateResult is the result of the authelia storage migrate up and down commands.
+type storageMigrateResult struct {
+	Version int `json:"version"`
+}
+
+// storageFileResult is the result of the authelia storage subcommands which import data from or export data to files.
+type storageFileResult struct {
+	Count  int    `json:"count"`
+	Format string `json:"format"`
+	Path   string `json:"path"`
+}
+
+// storageWebAuthnCredential is the output of the authelia storage user webauthn list command for a credential.
+type storageWebAuthnCredential struct {
+	ID          int        `json:"id"`
+	KID         string     `json:"kid"`
+	Description string     `json:"description"`
+	Username    string     `json:"username"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+func newStorageWebAuthnCredential(credential model.WebAuthnCredential) (c storageWebAuthnCredential) {
+	c = storageWebAuthnCredential{
+		ID:          credential.ID,
+		KID:         credential.KID.String(),
+		Description: credential.Description,
+		Username:    credential.Username,
+		CreatedAt:   credential.CreatedAt,
+	}
+
+	if credential.LastUsedAt.Valid {
+		c.LastUsedAt = &credential.LastUsedAt.Time
+	}
+
+	return c
+}
+
+// storageAuditEvent is the output of the authelia storage audit list command for an event.
+type storageAuditEvent struct {
+	ID            string          `json:"id"`
+	Time          time.Time       `json:"time"`
+	Type          string          `json:"type"`
+	Result        string          `json:"result"`
+	Username      string          `json:"username,omitempty"`
+	RemoteIP      string          `json:"remote_ip,omitempty"`
+	UserAgent     string          `json:"user_agent,omitempty"`
+	RequestMethod string          `json:"request_method,omitempty"`
+	RequestHost   string          `json:"request_host,omitempty"`
+	RequestPath   string          `json:"request_path,omitempty"`
+	TraceID       string          `json:"trace_id,omitempty"`
+	Details       json.RawMessage `json:"details,omitempty"`
+}
+
+func newStorageAuditEvent(event model.AuditEvent) (e storageAuditEvent) {
+	e = storageAuditEvent{
+		ID:            event.EventID,
+		Time:          event.Time,
+		Type:          event.Type,
+		Result:        event.Result,
+		Username:      event.Username,
+		RemoteIP:      event.RemoteIP,
+		UserAgent:     event.UserAgent,
+		RequestMethod: event.RequestMethod,
+		RequestHost:   event.RequestHost,
+		RequestPath:   event.RequestPath,
+		TraceID:       event.TraceID,
+	}
+
+	if event.Details != "" && event.Details != "{}" && json.Valid([]byte(event.Details)) {
+		e.Details = json.RawMessage(event.Details)
+	}
+
+	return e
+}
+
+// storageAuditValue returns the value for the table output of the authelia storage audit list command.
+func storageAuditValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// storageAuditFilterFromFlags returns the audit event filter from the flags of the authelia storage audit list command.
+func storageAuditFilterFromFlags(flags *pflag.FlagSet) (filter model.AuditEventFilter, err error) {
+	if filter.Username, err = flags.GetString(cmdFlagNameUsername); err != nil {
+		return filter, err
+	}
+
+	if filter.Type, err = flags.GetString(cmdFlagNameType); err != nil {
+		return filter, err
+	}
+
+	if filter.Since, err = storageAuditTimeFromFlags(flags, cmdFlagNameSince); err != nil {
+		return filter, err
+	}
+
+	if filter.Until, err = storageAuditTimeFromFlags(flags, cmdFlagNameUntil); err != nil {
+		return filter, err
+	}
+
+	if !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return filter, fmt.Errorf("the '%s' flag must be before the '%s' flag", cmdFlagNameSince, cmdFlagNameUntil)
+	}
+
+	return filter, nil
+}
+
+// storageAuditTimeFromFlags parses a flag as either a RFC3339 timestamp or a duration before the current time.
+func storageAuditTimeFromFlags(flags *pflag.FlagSet, name string) (t time.Time, err error) {
+	var value string
+
+	if value, err = flags.GetString(name); err != nil || value == "" {
+		return t, err
+	}
+
+	if t, err = time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	var duration time.Duration
+
+	if duration, err = time.ParseDuration(value); err != nil || duration < 0 {
+		return t, fmt.Errorf("the '%s' flag value '%s' must be a RFC3339 timestamp or a positive duration", name, value)
+	}
+
+	return time.Now().Add(-duration), nil
+}
+
+// storageTOTPGenerateResult is the result of the authelia storage user totp generate command.
+type storageTOTPGenerateResult struct {
+	Username string `json:"username"`
+	URI      string `json:"uri"`
+	Path     string `json:"path,omitempty"`
+}
+
+// storageIdentifiersGenerateResult is the result of the authelia storage user identifiers generate command.
+type storageIdentifiersGenerateResult struct {
+	Users      []string `json:"users"`
+	Sectors    []string `json:"sectors"`
+	Services   []string `json:"services"`
+	Duplicates int      `json:"duplicates"`
+	Added      int      `json:"added"`
+}
+
+// storageDirectoryUserResult is the output of the authelia storage user add and modify commands for a user.
+type storageDirectoryUserResult struct {
+	Username    string   `json:"username"`
+	DisplayName string   `json:"display_name"`
+	Emails      []string `json:"emails"`
+	Groups      []string `json:"groups"`
+	Disabled    bool     `json:"disabled"`
+}
+
+func newStorageDirectoryUserResult(user *model.DirectoryUser, groups []string) storageDirectoryUserResult {
+	return storageDirectoryUserResult{
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Emails:      user.Emails,
+		Groups:      groups,
+		Disabled:    !user.Active,
+	}
+}
+
+// storageUser is the output of the authelia storage user list and search commands for a user.
+type storageUser struct {
+	Username            string     `json:"username"`
+	Method              string     `json:"method"`
+	TOTP                bool       `json:"totp"`
+	WebAuthnCredentials int        `json:"webauthn_credentials"`
+	Duo                 bool       `json:"duo"`
+	LastSignIn          *time.Time `json:"last_sign_in,omitempty"`
+	Banned              bool       `json:"banned"`
+	BannedUntil         *time.Time `json:"banned_until,omitempty"`
+}
+
+func newStorageUser(summary model.UserSummary) (user storageUser) {
+	user = storageUser{
+		Username:            summary.Username,
+		Method:              summary.Method,
+		TOTP:                summary.HasTOTP,
+		WebAuthnCredentials: summary.WebAuthnCredentials,
+		Duo:                 summary.HasDuo,
+	}
+
+	if summary.LastSignInAt.Valid {
+		user.LastSignIn = &summary.LastSignInAt.Time
+	}
+
+	return user
+}
+
+// storageUserFilter filters the users listed by the authelia storage user list and search commands.
+type storageUserFilter struct {
+	pattern     string
+	enrolled    []string
+	notEnrolled []string
+	banned      bool
+}
+
+func storageUserFilterFromFlags(flags *pflag.FlagSet, args []string) (filter *storageUserFilter, err error) {
+	filter = &storageUserFilter{}
+
+	if len(args) != 0 {
+		filter.pattern = strings.ToLower(args[0])
+	}
+
+	if filter.enrolled, err = flags.GetStringSlice(cmdFlagNameEnrolled); err != nil {
+		return nil, err
+	}
+
+	if filter.notEnrolled, err = flags.GetStringSlice(cmdFlagNameNotEnrolled); err != nil {
+		return nil, err
+	}
+
+	if filter.banned, err = flags.GetBool(cmdFlagNameBanned); err != nil {
+		return nil, err
+	}
+
+	for _, methods := range [][]string{filter.enrolled, filter.notEnrolled} {
+		for i, method := range methods {
+			if methods[i] = strings.ToLower(method); !utils.IsStringInSlice(methods[i], storageUserMethods) {
+				return nil, fmt.Errorf("the second factor method '%s' is not valid, options are %s", method, utils.StringJoinOr(storageUserMethods))
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// matchesUsername returns true if the username matches the pattern. Patterns with the '*' or '?' wildcards must match
+// the whole username, otherwise the username must contain the pattern.
+func (f *storageUserFilter) matchesUsername(username string) bool {
+	if f.pattern == "" {
+		return true
+	}
+
+	username = strings.ToLower(username)
+
+	if strings.ContainsAny(f.pattern, "*?") {
+		matched, err := path.Match(f.pattern, username)
+
+		return err == nil && matched
+	}
+
+	return strings.Contains(username, f.pattern)
+}
+
+// matchesMethods returns true if the user has enrolled all of the required methods and none of the excluded methods.
+func (f *storageUserFilter) matchesMethods(summary model.UserSummary) bool {
+	enrolled := func(method string) bool {
+		switch method {
+		case storageUserMethodTOTP:
+			return summary.HasTOTP
+		case storageUserMethodWebAuthn:
+			return summary.WebAuthnCredentials != 0
+		case storageUserMethodDuo:
+			return summary.HasDuo
+		default:
+			return false
+		}
+	}
+
+	for _, method := range f.enrolled {
+		if !enrolled(method) {
+			return false
+		}
+	}
+
+	for _, method := range f.notEnrolled {
+		if enrolled(method) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func storageWebAuthnDeleteRunEOptsFromFlags(flags *pflag.FlagSet, args []string) (all, byKID bool, description, kid, user string, err error) {
 	if len(args) != 0 {
 		user = args[0]
@@ -1,11 +1,212 @@
 package commands
 
 import (
+	"database/sql"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 func TestGetStorageProvider(t *testing.T) {
 	assert.Nil(t, getStorageProvider(NewCmdCtx()))
 }
+
+func TestStorageTOTPConfigurationFromURI(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		uri      string
+		expected model.TOTPConfiguration
+		err      string
+	}{
+		{
+			"ShouldParse",
+			"otpauth://totp/Authelia:john?algorithm=SHA256&digits=8&issuer=Authelia&period=60&secret=JBSWY3DPEHPK3PXP",
+			model.TOTPConfiguration{CreatedAt: now, Username: "john", Issuer: "Authelia", Algorithm: "SHA256", Digits: 8, Period: 60, Secret: []byte("JBSWY3DPEHPK3PXP")},
+			"",
+		},
+		{
+			"ShouldParseDefaults",
+			"otpauth://totp/john?secret=jbswy3dpehpk3pxp",
+			model.TOTPConfiguration{CreatedAt: now, Username: "john", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("JBSWY3DPEHPK3PXP")},
+			"",
+		},
+		{
+			"ShouldParseIssuerParameter",
+			"otpauth://totp/Example:john?issuer=Authelia&secret=JBSWY3DPEHPK3PXP",
+			model.TOTPConfiguration{CreatedAt: now, Username: "john", Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("JBSWY3DPEHPK3PXP")},
+			"",
+		},
+		{"ShouldErrScheme", "https://totp/john?secret=JBSWY3DPEHPK3PXP", model.TOTPConfiguration{}, "the URI has the 'https' scheme but it must have the 'otpauth' scheme"},
+		{"ShouldErrType", "otpauth://hotp/john?secret=JBSWY3DPEHPK3PXP", model.TOTPConfiguration{}, "the URI has the 'hotp' type but only the 'totp' type is supported"},
+		{"ShouldErrUsername", "otpauth://totp/Authelia:?secret=JBSWY3DPEHPK3PXP", model.TOTPConfiguration{}, "the URI does not have a username"},
+		{"ShouldErrSecret", "otpauth://totp/john?secret=abc1", model.TOTPConfiguration{}, "the URI does not have a valid base32 secret"},
+		{"ShouldErrAlgorithm", "otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&algorithm=MD5", model.TOTPConfiguration{}, "the URI has the 'MD5' algorithm but it must be one of 'SHA1', 'SHA256', or 'SHA512'"},
+		{"ShouldErrDigits", "otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&digits=7", model.TOTPConfiguration{}, "the URI has the '7' digits but it must be 6 or 8"},
+		{"ShouldErrPeriod", "otpauth://totp/john?secret=JBSWY3DPEHPK3PXP&period=0", model.TOTPConfiguration{}, "the URI has the '0' period but it must be a positive integer"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := storageTOTPConfigurationFromURI(tc.uri, now)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestStorageTOTPConfigurationsFromURIs(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data := []byte("otpauth://totp/Authelia:john?secret=JBSWY3DPEHPK3PXP\n\n# comment\notpauth://totp/Authelia:harry?secret=KRSXG5CTMVRXEZLU\n")
+
+	assert.True(t, storageTOTPIsURIs(data))
+	assert.False(t, storageTOTPIsURIs([]byte("totp_configurations: []\n")))
+
+	configs, err := storageTOTPConfigurationsFromURIs(data, now)
+
+	require.NoError(t, err)
+	require.Len(t, configs, 2)
+	assert.Equal(t, "john", configs[0].Username)
+	assert.Equal(t, "harry", configs[1].Username)
+
+	_, err = storageTOTPConfigurationsFromURIs([]byte("otpauth://totp/Authelia:john?secret=JBSWY3DPEHPK3PXP\notpauth://totp/john\n"), now)
+
+	assert.EqualError(t, err, "error parsing the URI on line 2: the URI does not have a valid base32 secret")
+}
+
+func TestStorageUserFilter(t *testing.T) {
+	john := model.UserSummary{Username: "John", HasTOTP: true, WebAuthnCredentials: 2}
+	harry := model.UserSummary{Username: "harry", HasDuo: true}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		flags    map[string]string
+		expected []bool
+		err      string
+	}{
+		{"ShouldMatchAll", nil, nil, []bool{true, true}, ""},
+		{"ShouldMatchContains", []string{"OH"}, nil, []bool{true, false}, ""},
+		{"ShouldMatchWildcard", []string{"h*y"}, nil, []bool{false, true}, ""},
+		{"ShouldNotMatchWildcardPartial", []string{"h*r"}, nil, []bool{false, false}, ""},
+		{"ShouldMatchEnrolled", nil, map[string]string{cmdFlagNameEnrolled: "totp,webauthn"}, []bool{true, false}, ""},
+		{"ShouldMatchNotEnrolled", nil, map[string]string{cmdFlagNameNotEnrolled: "WebAuthn"}, []bool{false, true}, ""},
+		{"ShouldMatchEnrolledAndNotEnrolled", nil, map[string]string{cmdFlagNameEnrolled: "duo", cmdFlagNameNotEnrolled: "totp"}, []bool{false, true}, ""},
+		{"ShouldErrInvalidMethod", nil, map[string]string{cmdFlagNameEnrolled: "sms"}, nil, "the second factor method 'sms' is not valid, options are 'totp', 'webauthn', or 'duo'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+
+			cmdFlagsStorageUserList(cmd)
+
+			for name, value := range tc.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+
+			filter, err := storageUserFilterFromFlags(cmd.Flags(), tc.args)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			var actual []bool
+
+			for _, summary := range []model.UserSummary{john, harry} {
+				actual = append(actual, filter.matchesUsername(summary.Username) && filter.matchesMethods(summary))
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestNewStorageUser(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, storageUser{Username: "john", Method: "totp", TOTP: true, WebAuthnCredentials: 1, LastSignIn: &now}, newStorageUser(model.UserSummary{Username: "john", Method: "totp", HasTOTP: true, WebAuthnCredentials: 1, LastSignInAt: sql.NullTime{Time: now, Valid: true}}))
+	assert.Equal(t, storageUser{Username: "harry", Duo: true}, newStorageUser(model.UserSummary{Username: "harry", HasDuo: true}))
+}
+
+func TestNewStorageDirectoryUserResult(t *testing.T) {
+	user := &model.DirectoryUser{Username: "john", DisplayName: "John Smith", Emails: model.StringSlicePipeDelimited{"john@example.com"}, Active: true}
+
+	assert.Equal(t, storageDirectoryUserResult{Username: "john", DisplayName: "John Smith", Emails: []string{"john@example.com"}, Groups: []string{"admins"}}, newStorageDirectoryUserResult(user, []string{"admins"}))
+
+	user.Active = false
+
+	assert.Equal(t, storageDirectoryUserResult{Username: "john", DisplayName: "John Smith", Emails: []string{"john@example.com"}, Disabled: true}, newStorageDirectoryUserResult(user, nil))
+}
+
+func TestStorageAuditFilterFromFlags(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flags    map[string]string
+		expected model.AuditEventFilter
+		err      string
+	}{
+		{"ShouldMatchAll", nil, model.AuditEventFilter{}, ""},
+		{"ShouldParseUsernameAndType", map[string]string{cmdFlagNameUsername: "john", cmdFlagNameType: "totp.register"}, model.AuditEventFilter{Username: "john", Type: "totp.register"}, ""},
+		{"ShouldParseTimestamps", map[string]string{cmdFlagNameSince: "2026-10-01T00:00:00Z", cmdFlagNameUntil: "2026-10-02T00:00:00Z"}, model.AuditEventFilter{Since: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), Until: time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)}, ""},
+		{"ShouldErrSinceAfterUntil", map[string]string{cmdFlagNameSince: "2026-10-02T00:00:00Z", cmdFlagNameUntil: "2026-10-01T00:00:00Z"}, model.AuditEventFilter{}, "the 'since' flag must be before the 'until' flag"},
+		{"ShouldErrInvalidTime", map[string]string{cmdFlagNameSince: "yesterday"}, model.AuditEventFilter{}, "the 'since' flag value 'yesterday' must be a RFC3339 timestamp or a positive duration"},
+		{"ShouldErrNegativeDuration", map[string]string{cmdFlagNameUntil: "-1h"}, model.AuditEventFilter{}, "the 'until' flag value '-1h' must be a RFC3339 timestamp or a positive duration"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+
+			cmdFlagsStorageAuditList(cmd)
+
+			for name, value := range tc.flags {
+				require.NoError(t, cmd.Flags().Set(name, value))
+			}
+
+			filter, err := storageAuditFilterFromFlags(cmd.Flags())
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, filter)
+		})
+	}
+
+	cmd := &cobra.Command{}
+
+	cmdFlagsStorageAuditList(cmd)
+
+	require.NoError(t, cmd.Flags().Set(cmdFlagNameSince, "24h"))
+
+	filter, err := storageAuditFilterFromFlags(cmd.Flags())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour*24), filter.Since, time.Minute)
+}
+
+func TestNewStorageAuditEvent(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, storageAuditEvent{ID: "a", Time: now, Type: "totp.register", Result: "success", Username: "john", Details: []byte(`{"algorithm":"SHA1"}`)}, newStorageAuditEvent(model.AuditEvent{ID: 1, EventID: "a", Time: now, Type: "totp.register", Result: "success", Username: "john", Details: `{"algorithm":"SHA1"}`}))
+	assert.Equal(t, storageAuditEvent{ID: "b", Time: now, Type: "session.logout", Result: "success"}, newStorageAuditEvent(model.AuditEvent{ID: 2, EventID: "b", Time: now, Type: "session.logout", Result: "success", Details: "{}"}))
+}
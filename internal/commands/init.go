@@ -0,0 +1,539 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newInitCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "init",
+		Short:   cmdAutheliaInitShort,
+		Long:    cmdAutheliaInitLong,
+		Example: cmdAutheliaInitExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.InitRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().StringP(cmdFlagNameDirectory, "d", "", "directory where the generated files are written")
+	cmd.Flags().Bool(cmdFlagNameForce, false, "overwrite the generated files if they already exist")
+	cmd.Flags().Bool(cmdFlagNameNonInteractive, false, "never prompt and use the flags or their defaults instead, this is implied when not using an interactive terminal")
+	cmd.Flags().String(cmdFlagNameDomain, "", "the root domain protected by Authelia which is used as the session cookie domain")
+	cmd.Flags().String(cmdFlagNameAutheliaURL, "", "the URL of the Authelia Portal, defaults to the 'auth' subdomain of the domain")
+	cmd.Flags().String(cmdFlagNameStorage, initStorageLocal, fmt.Sprintf("the storage provider, options are %s", utils.StringJoinOr(initStorageTypes)))
+	cmd.Flags().String(cmdFlagNameStorageAddress, "", "the address of the SQL server when not using the local storage provider")
+	cmd.Flags().String(cmdFlagNameStorageDatabase, "authelia", "the SQL database name when not using the local storage provider")
+	cmd.Flags().String(cmdFlagNameStorageUsername, "authelia", "the SQL username when not using the local storage provider")
+	cmd.Flags().String(cmdFlagNameStoragePassword, "", "the SQL password when not using the local storage provider")
+	cmd.Flags().String(cmdFlagNameProxy, initProxyNone, fmt.Sprintf("the proxy to generate a snippet for, options are %s", utils.StringJoinOr(initProxyTypes)))
+	cmd.Flags().String(cmdFlagNameProxyUpstream, "http://authelia:9091", "the URL the proxy uses to reach Authelia")
+	cmd.Flags().String(cmdFlagNameAdminUsername, "admin", "the username of the first administrator")
+	cmd.Flags().String(cmdFlagNameAdminDisplayName, "Administrator", "the display name of the first administrator")
+	cmd.Flags().String(cmdFlagNameAdminEmail, "", "the email of the first administrator, defaults to the username at the domain")
+	cmd.Flags().String(cmdFlagNameAdminPassword, "", "the password of the first administrator, a random password is generated when not using an interactive terminal")
+
+	return cmd
+}
+
+// InitRunE is the RunE for the authelia init command.
+func (ctx *CmdCtx) InitRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		dir        string
+		force, non bool
+	)
+
+	if dir, err = cmd.Flags().GetString(cmdFlagNameDirectory); err != nil {
+		return err
+	}
+
+	if force, err = cmd.Flags().GetBool(cmdFlagNameForce); err != nil {
+		return err
+	}
+
+	if non, err = cmd.Flags().GetBool(cmdFlagNameNonInteractive); err != nil {
+		return err
+	}
+
+	if dir, err = filepath.Abs(dir); err != nil {
+		return fmt.Errorf("error occurred determining the absolute path of the directory: %w", err)
+	}
+
+	prompter := &initPrompter{
+		cmd:         cmd,
+		interactive: !non && term.IsTerminal(int(syscall.Stdin)), //nolint:unconvert,nolintlint
+	}
+
+	values, generated, err := ctx.initValues(prompter, dir)
+	if err != nil {
+		return err
+	}
+
+	files, err := initRender(values)
+	if err != nil {
+		return err
+	}
+
+	if err = initValidateConfiguration(files[initFileConfiguration]); err != nil {
+		return err
+	}
+
+	if err = initWriteFiles(dir, files, force); err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated the configuration in the directory '%s':\n\n", dir)
+
+	for _, name := range initFileNames(values.Proxy) {
+		fmt.Printf("\t- %s\n", name)
+	}
+
+	fmt.Println("")
+
+	if generated {
+		fmt.Printf("Random Password for the user '%s': %s\n\n", values.AdminUsername, values.AdminPassword)
+	}
+
+	fmt.Printf("Run 'authelia --config %s' to start Authelia.\n", filepath.Join(dir, initFileConfiguration))
+
+	return nil
+}
+
+func (ctx *CmdCtx) initValues(prompter *initPrompter, dir string) (values *initTemplateValues, generated bool, err error) {
+	values = &initTemplateValues{
+		UsersDatabasePath:    filepath.Join(dir, initFileUsersDatabase),
+		StoragePath:          filepath.Join(dir, initFileStorage),
+		NotificationPath:     filepath.Join(dir, initFileNotification),
+		JWTSecret:            ctx.providers.Random.StringCustom(initSecretLength, random.CharSetAlphaNumeric),
+		SessionSecret:        ctx.providers.Random.StringCustom(initSecretLength, random.CharSetAlphaNumeric),
+		StorageEncryptionKey: ctx.providers.Random.StringCustom(initSecretLength, random.CharSetAlphaNumeric),
+	}
+
+	if values.Domain, err = prompter.value(cmdFlagNameDomain, "Domain", "", initValidateDomain); err != nil {
+		return nil, false, err
+	}
+
+	values.Domain = strings.ToLower(values.Domain)
+
+	if values.AutheliaURL, err = prompter.value(cmdFlagNameAutheliaURL, "Authelia URL", fmt.Sprintf("https://auth.%s/", values.Domain), initValidateAutheliaURL); err != nil {
+		return nil, false, err
+	}
+
+	if values.Storage, err = prompter.choice(cmdFlagNameStorage, "Storage", initStorageTypes); err != nil {
+		return nil, false, err
+	}
+
+	if values.Storage != initStorageLocal {
+		address := "tcp://127.0.0.1:3306"
+
+		if values.Storage == initStoragePostgres {
+			address = "tcp://127.0.0.1:5432"
+		}
+
+		if values.StorageAddress, err = prompter.value(cmdFlagNameStorageAddress, "Storage Address", address, nil); err != nil {
+			return nil, false, err
+		}
+
+		if values.StorageDatabase, err = prompter.value(cmdFlagNameStorageDatabase, "Storage Database", "", nil); err != nil {
+			return nil, false, err
+		}
+
+		if values.StorageUsername, err = prompter.value(cmdFlagNameStorageUsername, "Storage Username", "", nil); err != nil {
+			return nil, false, err
+		}
+
+		if values.StoragePassword, _, err = prompter.password(cmdFlagNameStoragePassword, "Storage Password", false); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if values.AdminUsername, err = prompter.value(cmdFlagNameAdminUsername, "Administrator Username", "", nil); err != nil {
+		return nil, false, err
+	}
+
+	if values.AdminDisplayName, err = prompter.value(cmdFlagNameAdminDisplayName, "Administrator Display Name", "", nil); err != nil {
+		return nil, false, err
+	}
+
+	if values.AdminEmail, err = prompter.value(cmdFlagNameAdminEmail, "Administrator Email", fmt.Sprintf("%s@%s", values.AdminUsername, values.Domain), nil); err != nil {
+		return nil, false, err
+	}
+
+	if values.AdminPassword, generated, err = prompter.password(cmdFlagNameAdminPassword, "Administrator Password", true); err != nil {
+		return nil, false, err
+	}
+
+	if values.Proxy, err = prompter.choice(cmdFlagNameProxy, "Proxy", initProxyTypes); err != nil {
+		return nil, false, err
+	}
+
+	if values.Proxy != initProxyNone {
+		if values.ProxyUpstream, err = prompter.value(cmdFlagNameProxyUpstream, "Proxy Upstream", "", initValidateProxyUpstream); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if values.AdminDigest, err = initHashPassword(values.AdminPassword); err != nil {
+		return nil, false, err
+	}
+
+	return values, generated, nil
+}
+
+type initTemplateValues struct {
+	Domain      string
+	AutheliaURL string
+
+	JWTSecret            string
+	SessionSecret        string
+	StorageEncryptionKey string
+
+	Storage         string
+	StoragePath     string
+	StorageAddress  string
+	StorageDatabase string
+	StorageUsername string
+	StoragePassword string
+
+	UsersDatabasePath string
+	NotificationPath  string
+
+	AdminUsername    string
+	AdminDisplayName string
+	AdminEmail       string
+	AdminPassword    string
+	AdminDigest      string
+
+	Proxy         string
+	ProxyUpstream string
+}
+
+// AutheliaHost returns the host of the Authelia URL.
+func (v *initTemplateValues) AutheliaHost() string {
+	uri, err := url.ParseRequestURI(v.AutheliaURL)
+	if err != nil {
+		return ""
+	}
+
+	return uri.Host
+}
+
+// initPrompter resolves the values of the init command from the flags, the interactive terminal, or the defaults. The
+// flags always take precedence over the terminal, and the terminal is only used when it's interactive.
+type initPrompter struct {
+	cmd         *cobra.Command
+	interactive bool
+}
+
+func (p *initPrompter) value(flag, prompt, def string, validate func(value string) error) (value string, err error) {
+	if def == "" {
+		if def, err = p.cmd.Flags().GetString(flag); err != nil {
+			return "", err
+		}
+	}
+
+	if p.cmd.Flags().Changed(flag) || !p.interactive {
+		if value, err = p.cmd.Flags().GetString(flag); err != nil {
+			return "", err
+		}
+
+		if value == "" {
+			value = def
+		}
+
+		if value == "" {
+			return "", fmt.Errorf("you must either use an interactive terminal or use the --%s flag", flag)
+		}
+
+		if validate != nil {
+			if err = validate(value); err != nil {
+				return "", fmt.Errorf("flag --%s with value '%s' is invalid: %w", flag, value, err)
+			}
+		}
+
+		return value, nil
+	}
+
+	if def != "" {
+		prompt = fmt.Sprintf("%s [%s]: ", prompt, def)
+	} else {
+		prompt += ": "
+	}
+
+	for {
+		if value, err = termReadLineWithPrompt(prompt); err != nil {
+			return "", err
+		}
+
+		if value == "" {
+			value = def
+		}
+
+		switch {
+		case value == "":
+			fmt.Println("A value is required.")
+		case validate == nil:
+			return value, nil
+		default:
+			if err = validate(value); err == nil {
+				return value, nil
+			}
+
+			fmt.Printf("The value '%s' is invalid: %v.\n", value, err)
+		}
+	}
+}
+
+func (p *initPrompter) choice(flag, prompt string, options []string) (value string, err error) {
+	return p.value(flag, fmt.Sprintf("%s (%s)", prompt, strings.Join(options, ", ")), "", func(value string) error {
+		if !utils.IsStringInSlice(value, options) {
+			return fmt.Errorf("must be one of %s", utils.StringJoinOr(options))
+		}
+
+		return nil
+	})
+}
+
+func (p *initPrompter) password(flag, prompt string, generate bool) (password string, generated bool, err error) {
+	if p.cmd.Flags().Changed(flag) {
+		if password, err = p.cmd.Flags().GetString(flag); err != nil {
+			return "", false, err
+		}
+
+		if password == "" {
+			return "", false, fmt.Errorf("flag --%s is invalid: must not be empty", flag)
+		}
+
+		return password, false, nil
+	}
+
+	if !p.interactive {
+		if !generate {
+			return "", false, fmt.Errorf("you must either use an interactive terminal or use the --%s flag", flag)
+		}
+
+		return (&random.Cryptographical{}).StringCustom(initSecretLength/2, random.CharSetAlphaNumeric), true, nil
+	}
+
+	for {
+		var confirm string
+
+		if password, err = termReadPasswordWithPrompt(fmt.Sprintf("%s: ", prompt), flag); err != nil {
+			return "", false, err
+		}
+
+		if password == "" {
+			fmt.Println("A value is required.")
+
+			continue
+		}
+
+		if confirm, err = termReadPasswordWithPrompt(fmt.Sprintf("Confirm %s: ", prompt), flag); err != nil {
+			return "", false, err
+		}
+
+		if password == confirm {
+			return password, false, nil
+		}
+
+		fmt.Println("The passwords did not match.")
+	}
+}
+
+func initValidateDomain(value string) (err error) {
+	switch {
+	case strings.Contains(value, "://"), strings.Contains(value, "/"):
+		return errors.New("must be a domain not a URL")
+	case !strings.Contains(value, "."):
+		return errors.New("must contain at least one period")
+	case strings.HasPrefix(value, "."), strings.HasPrefix(value, "*"):
+		return errors.New("must not be a wildcard")
+	}
+
+	return nil
+}
+
+func initValidateAutheliaURL(value string) (err error) {
+	var uri *url.URL
+
+	if uri, err = url.ParseRequestURI(value); err != nil {
+		return errors.New("must be an absolute URL")
+	}
+
+	if uri.Scheme != "https" {
+		return errors.New("must have the https scheme")
+	}
+
+	return nil
+}
+
+func initValidateProxyUpstream(value string) (err error) {
+	var uri *url.URL
+
+	if uri, err = url.ParseRequestURI(value); err != nil || uri.Host == "" {
+		return errors.New("must be an absolute URL")
+	}
+
+	return nil
+}
+
+func initHashPassword(password string) (digest string, err error) {
+	hash, err := authentication.NewFileCryptoHashFromConfig(schema.DefaultPasswordConfig)
+	if err != nil {
+		return "", err
+	}
+
+	d, err := hash.Hash(password)
+	if err != nil {
+		return "", fmt.Errorf("error occurred hashing the administrator password: %w", err)
+	}
+
+	return d.Encode(), nil
+}
+
+// initRender renders the files generated by the init command keyed by their file name.
+func initRender(values *initTemplateValues) (files map[string][]byte, err error) {
+	files = map[string][]byte{}
+
+	if files[initFileConfiguration], err = initRenderTemplate(initFileConfiguration, tmplInitConfiguration, values); err != nil {
+		return nil, err
+	}
+
+	database := &authentication.FileDatabaseModel{
+		Users: map[string]authentication.FileDatabaseUserDetailsModel{
+			values.AdminUsername: {
+				Password:    values.AdminDigest,
+				DisplayName: values.AdminDisplayName,
+				Email:       values.AdminEmail,
+				Groups:      []string{"admins"},
+			},
+		},
+	}
+
+	if files[initFileUsersDatabase], err = yaml.Marshal(database); err != nil {
+		return nil, fmt.Errorf("error occurred rendering the file '%s': %w", initFileUsersDatabase, err)
+	}
+
+	var tmpl string
+
+	switch values.Proxy {
+	case initProxyNGINX:
+		tmpl = tmplInitProxyNGINX
+	case initProxyTraefik:
+		tmpl = tmplInitProxyTraefik
+	case initProxyCaddy:
+		tmpl = tmplInitProxyCaddy
+	default:
+		return files, nil
+	}
+
+	name := initProxyFiles[values.Proxy]
+
+	if files[name], err = initRenderTemplate(name, tmpl, values); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func initRenderTemplate(name, tmpl string, values *initTemplateValues) (data []byte, err error) {
+	var t *template.Template
+
+	if t, err = template.New(name).Funcs(template.FuncMap{"squote": initYAMLSingleQuote}).Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("error occurred parsing the template for the file '%s': %w", name, err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = t.Execute(buf, values); err != nil {
+		return nil, fmt.Errorf("error occurred rendering the file '%s': %w", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func initYAMLSingleQuote(value string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(value, "'", "''"))
+}
+
+// initValidateConfiguration ensures the generated configuration is valid prior to writing it.
+func initValidateConfiguration(data []byte) (err error) {
+	val := schema.NewStructValidator()
+
+	var config *schema.Configuration
+
+	if _, config, err = configuration.Load(val, configuration.NewBytesSource(data)); err != nil {
+		return fmt.Errorf("error occurred loading the generated configuration: %w", err)
+	}
+
+	validator.ValidateConfiguration(config, val)
+
+	if !val.HasErrors() {
+		return nil
+	}
+
+	errs := val.Errors()
+
+	messages := make([]string, len(errs))
+
+	for i, e := range errs {
+		messages[i] = e.Error()
+	}
+
+	return fmt.Errorf("the generated configuration is invalid: %s", strings.Join(messages, ", "))
+}
+
+func initWriteFiles(dir string, files map[string][]byte, force bool) (err error) {
+	if !force {
+		for name := range files {
+			path := filepath.Join(dir, name)
+
+			if _, err = os.Stat(path); err == nil {
+				return fmt.Errorf("the file '%s' already exists, use the --%s flag to overwrite it", path, cmdFlagNameForce)
+			}
+		}
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("error occurred creating the directory '%s': %w", dir, err)
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+
+		if err = os.WriteFile(path, data, 0600); err != nil {
+			return fmt.Errorf("error occurred writing the file '%s': %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func initFileNames(proxy string) (names []string) {
+	names = []string{initFileConfiguration, initFileUsersDatabase}
+
+	if name, ok := initProxyFiles[proxy]; ok {
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitValidateDomain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldPassDomain", "example.com", ""},
+		{"ShouldPassSubdomain", "home.example.com", ""},
+		{"ShouldFailURL", "https://example.com", "must be a domain not a URL"},
+		{"ShouldFailNoPeriod", "localhost", "must contain at least one period"},
+		{"ShouldFailWildcard", "*.example.com", "must not be a wildcard"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := initValidateDomain(tc.have)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInitRender(t *testing.T) {
+	values := &initTemplateValues{
+		Domain:               "example.com",
+		AutheliaURL:          "https://auth.example.com/",
+		JWTSecret:            "jwt",
+		SessionSecret:        "session",
+		StorageEncryptionKey: "encryption",
+		Storage:              initStoragePostgres,
+		StorageAddress:       "tcp://postgres:5432",
+		StorageDatabase:      "authelia",
+		StorageUsername:      "authelia",
+		StoragePassword:      "it's",
+		UsersDatabasePath:    "/config/users_database.yml",
+		NotificationPath:     "/config/notification.txt",
+		AdminUsername:        "admin",
+		AdminDisplayName:     "Administrator",
+		AdminEmail:           "admin@example.com",
+		AdminDigest:          "$plaintext$example",
+		Proxy:                initProxyCaddy,
+		ProxyUpstream:        "http://authelia:9091",
+	}
+
+	files, err := initRender(values)
+	require.NoError(t, err)
+
+	require.Len(t, files, 3)
+
+	assert.Contains(t, string(files[initFileConfiguration]), "    password: 'it''s'\n")
+	assert.Contains(t, string(files[initFileConfiguration]), "      authelia_url: 'https://auth.example.com/'\n")
+	assert.NotContains(t, string(files[initFileConfiguration]), "local:")
+	assert.Contains(t, string(files[initFileUsersDatabase]), "password: $plaintext$example")
+	assert.Contains(t, string(files[initProxyFiles[initProxyCaddy]]), "auth.example.com {")
+
+	assert.Equal(t, []string{initFileConfiguration, initFileUsersDatabase, "authelia.Caddyfile"}, initFileNames(values.Proxy))
+	assert.Equal(t, []string{initFileConfiguration, initFileUsersDatabase}, initFileNames(initProxyNone))
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// cmdOutputFormatFromCmd returns the output format requested with the --output flag.
+func cmdOutputFormatFromCmd(cmd *cobra.Command) (format string, err error) {
+	if format, err = cmd.Flags().GetString(cmdFlagNameOutput); err != nil {
+		return "", err
+	}
+
+	if !utils.IsStringInSlice(format, outputFormats) {
+		return "", fmt.Errorf("the output format '%s' is not valid, options are %s", format, utils.StringJoinOr(outputFormats))
+	}
+
+	return format, nil
+}
+
+// cmdOutputIsJSON returns true if the JSON output format was requested with the --output flag.
+func cmdOutputIsJSON(cmd *cobra.Command) bool {
+	format, err := cmdOutputFormatFromCmd(cmd)
+
+	return err == nil && format == outputFormatJSON
+}
+
+// cmdOutputWriteJSON writes the data to the output of the command as a JSON response using the same envelope as the
+// API responses.
+func cmdOutputWriteJSON(cmd *cobra.Command, data any) (err error) {
+	return cmdOutputEncodeJSON(cmd.OutOrStdout(), middlewares.OKResponse{Status: outputStatusOK, Data: data})
+}
+
+// cmdOutputWriteJSONError writes the error to the output of the command as a JSON error response.
+func cmdOutputWriteJSONError(cmd *cobra.Command, err error) {
+	_ = cmdOutputEncodeJSON(cmd.OutOrStdout(), middlewares.ErrorResponse{Status: outputStatusKO, Message: err.Error()})
+}
+
+func cmdOutputEncodeJSON(w io.Writer, v any) (err error) {
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+// cmdWithOutput adjusts the command and all of its subcommands so when the JSON output format is requested every
+// error, including the errors from the flags, arguments, and pre-runs, is written as a JSON error response instead of
+// the cobra error and usage text.
+func cmdWithOutput(cmd *cobra.Command) *cobra.Command {
+	if cmd.Args != nil {
+		cmd.Args = cobra.PositionalArgs(cmdOutputWrapRunE(CobraRunECmd(cmd.Args)))
+	}
+
+	cmd.PersistentPreRunE = cmdOutputWrapRunE(cmd.PersistentPreRunE)
+	cmd.PreRunE = cmdOutputWrapRunE(cmd.PreRunE)
+	cmd.RunE = cmdOutputWrapRunE(cmd.RunE)
+
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return cmdOutputHandleErr(cmd, err)
+	})
+
+	for _, subcmd := range cmd.Commands() {
+		cmdWithOutput(subcmd)
+	}
+
+	return cmd
+}
+
+func cmdOutputWrapRunE(fn CobraRunECmd) CobraRunECmd {
+	if fn == nil {
+		return nil
+	}
+
+	return func(cmd *cobra.Command, args []string) (err error) {
+		if _, err = cmdOutputFormatFromCmd(cmd); err != nil {
+			return err
+		}
+
+		return cmdOutputHandleErr(cmd, fn(cmd, args))
+	}
+}
+
+func cmdOutputHandleErr(cmd *cobra.Command, err error) error {
+	if err == nil || !cmdOutputIsJSON(cmd) {
+		return err
+	}
+
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+
+	cmdOutputWriteJSONError(cmd, err)
+
+	return err
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOutputCmd(runE CobraRunECmd) (cmd *cobra.Command, buf *bytes.Buffer) {
+	buf = &bytes.Buffer{}
+
+	cmd = &cobra.Command{
+		Use: "authelia",
+	}
+
+	cmd.PersistentFlags().String(cmdFlagNameOutput, outputFormatText, "")
+
+	child := &cobra.Command{
+		Use:  "child",
+		Args: cobra.NoArgs,
+		RunE: runE,
+	}
+
+	child.Flags().Bool("example", false, "")
+
+	parent := &cobra.Command{
+		Use: "parent",
+	}
+
+	parent.AddCommand(child)
+
+	cmd.AddCommand(cmdWithOutput(parent))
+
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+
+	return cmd, buf
+}
+
+func TestCmdOutputFormatFromCmd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{"ShouldDefaultToText", nil, outputFormatText, ""},
+		{"ShouldParseJSON", []string{"--output", "json"}, outputFormatJSON, ""},
+		{"ShouldErrorOnInvalid", []string{"--output", "yaml"}, "", "the output format 'yaml' is not valid, options are 'text' or 'json'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+
+			cmd.Flags().String(cmdFlagNameOutput, outputFormatText, "")
+
+			require.NoError(t, cmd.Flags().Parse(tc.args))
+
+			format, err := cmdOutputFormatFromCmd(cmd)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, format)
+				assert.Equal(t, tc.expected == outputFormatJSON, cmdOutputIsJSON(cmd))
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.False(t, cmdOutputIsJSON(cmd))
+			}
+		})
+	}
+}
+
+func TestCmdWithOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		runE     CobraRunECmd
+		expected string
+		err      string
+	}{
+		{
+			"ShouldWriteData",
+			[]string{"parent", "child", "--output", "json"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return cmdOutputWriteJSON(cmd, map[string]int{"count": 1})
+			},
+			"{\n  \"status\": \"OK\",\n  \"data\": {\n    \"count\": 1\n  }\n}\n",
+			"",
+		},
+		{
+			"ShouldWriteWithoutData",
+			[]string{"parent", "child", "--output", "json"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return cmdOutputWriteJSON(cmd, nil)
+			},
+			"{\n  \"status\": \"OK\"\n}\n",
+			"",
+		},
+		{
+			"ShouldWriteRunError",
+			[]string{"parent", "child", "--output", "json"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return errors.New("bad things happened")
+			},
+			"{\n  \"status\": \"KO\",\n  \"message\": \"bad things happened\"\n}\n",
+			"bad things happened",
+		},
+		{
+			"ShouldWriteArgsError",
+			[]string{"parent", "child", "--output", "json", "extra"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return nil
+			},
+			"{\n  \"status\": \"KO\",\n  \"message\": \"unknown command \\\"extra\\\" for \\\"authelia parent child\\\"\"\n}\n",
+			"unknown command \"extra\" for \"authelia parent child\"",
+		},
+		{
+			"ShouldWriteFlagError",
+			[]string{"parent", "child", "--output", "json", "--example=abc"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return nil
+			},
+			"{\n  \"status\": \"KO\",\n  \"message\": \"invalid argument \\\"abc\\\" for \\\"--example\\\" flag: strconv.ParseBool: parsing \\\"abc\\\": invalid syntax\"\n}\n",
+			"invalid argument \"abc\" for \"--example\" flag: strconv.ParseBool: parsing \"abc\": invalid syntax",
+		},
+		{
+			"ShouldNotWriteErrorAsJSONForText",
+			[]string{"parent", "child"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return errors.New("bad things happened")
+			},
+			"Error: bad things happened\n",
+			"bad things happened",
+		},
+		{
+			"ShouldErrorOnInvalidFormat",
+			[]string{"parent", "child", "--output", "yaml"},
+			func(cmd *cobra.Command, args []string) (err error) {
+				return nil
+			},
+			"Error: the output format 'yaml' is not valid, options are 'text' or 'json'\n",
+			"the output format 'yaml' is not valid, options are 'text' or 'json'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, buf := newTestOutputCmd(tc.runE)
+
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, buf.String())
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.True(t, strings.HasPrefix(buf.String(), tc.expected), buf.String())
+			}
+		})
+	}
+}
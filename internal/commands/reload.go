@@ -0,0 +1,324 @@
+package commands
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/notification"
+)
+
+// NewConfigReloader returns a new ConfigReloader which reloads the configuration from the same sources that were used
+// to load the configuration of the provided CmdCtx.
+func NewConfigReloader(ctx *CmdCtx, watch bool, refresh time.Duration) (reloader *ConfigReloader) {
+	return &ConfigReloader{
+		ctx:      ctx,
+		files:    ctx.cconfig.files,
+		filters:  ctx.cconfig.filters,
+		lists:    ctx.cconfig.lists,
+		defaults: ctx.cconfig.defaults,
+		watch:    watch,
+		refresh:  refresh,
+		current:  ctx.config,
+		log:      ctx.log,
+	}
+}
+
+// ConfigReloader is a ProviderReload which reloads the configuration and applies the sections which can be applied
+// without a restart. The configuration is completely validated before any of it is applied.
+type ConfigReloader struct {
+	ctx *CmdCtx
+
+	files    []string
+	filters  []string
+	lists    configuration.ListMergeStrategy
+	defaults configuration.Source
+	watch    bool
+	refresh  time.Duration
+
+	// current is the configuration which is currently in effect, i.e. the startup configuration with the reloaded
+	// sections applied.
+	current *schema.Configuration
+
+	log *logrus.Logger
+
+	mu sync.Mutex
+}
+
+// ConfigReloadReport describes the outcome of a configuration reload.
+type ConfigReloadReport struct {
+	// Applied are the sections which changed and were applied.
+	Applied []string
+
+	// Restart are the sections which changed but can only be applied by restarting.
+	Restart []string
+}
+
+// Reload the configuration and apply the sections which have changed.
+func (r *ConfigReloader) Reload() (reloaded bool, err error) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	var config *schema.Configuration
+
+	if config, err = r.load(); err != nil {
+		return false, err
+	}
+
+	var (
+		report ConfigReloadReport
+		next   *schema.Configuration
+		apply  []func()
+	)
+
+	if report, next, apply, err = r.plan(config); err != nil {
+		return false, err
+	}
+
+	for _, fn := range apply {
+		fn()
+	}
+
+	r.current = next
+
+	for _, section := range report.Restart {
+		r.log.WithField(logFieldSection, section).Warn("Configuration section has changed but can only be applied by restarting")
+	}
+
+	if len(report.Applied) == 0 {
+		return false, nil
+	}
+
+	r.log.WithField("sections", report.Applied).Info("Configuration sections have been reloaded")
+
+	return true, nil
+}
+
+// Current returns the configuration which is currently in effect.
+func (r *ConfigReloader) Current() (config *schema.Configuration) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+func (r *ConfigReloader) load() (config *schema.Configuration, err error) {
+	var filters []configuration.BytesFilter
+
+	if filters, err = configuration.NewFileFilters(r.filters); err != nil {
+		return nil, fmt.Errorf("error occurred loading configuration: %w", err)
+	}
+
+	sources := configuration.NewDefaultSourcesWithDefaults(
+		r.files,
+		filters,
+		r.lists,
+		configuration.DefaultEnvPrefix,
+		configuration.DefaultEnvDelimiter,
+		r.defaults)
+
+	val := schema.NewStructValidator()
+
+	config = &schema.Configuration{}
+
+	var keys []string
+
+	if keys, err = configuration.LoadAdvanced(val, "", config, sources...); err != nil {
+		return nil, fmt.Errorf("error occurred loading configuration: %w", err)
+	}
+
+	validator.ValidateKeys(keys, configuration.GetMultiKeyMappedDeprecationKeys(), configuration.DefaultEnvPrefix, val)
+
+	tc := &tls.Config{
+		RootCAs:    r.ctx.trusted,
+		MinVersion: tls.VersionTLS12,
+		MaxVersion: tls.VersionTLS13,
+	}
+
+	validator.ValidateConfiguration(config, val, validator.WithTLSConfig(tc))
+
+	for _, warning := range val.Warnings() {
+		r.log.Warnf("Configuration: %+v", warning)
+	}
+
+	if errs := val.Errors(); len(errs) != 0 {
+		return nil, fmt.Errorf("error occurred validating configuration: the configuration was not applied as it has %d errors: %w", len(errs), errors.Join(errs...))
+	}
+
+	return config, nil
+}
+
+// plan determines which of the changed sections can be applied and prepares them. Nothing is applied until all of the
+// sections are prepared successfully. The next configuration retains the current values for the sections which can
+// only be applied by restarting so that they are reported on every reload until a restart.
+func (r *ConfigReloader) plan(config *schema.Configuration) (report ConfigReloadReport, next *schema.Configuration, apply []func(), err error) {
+	current := *r.current
+
+	next = &current
+
+	authorizer := false
+
+	for _, section := range configReloadChangedSections(r.current, config) {
+		switch section {
+		case configSectionAccessControl:
+			next.AccessControl = config.AccessControl
+
+			authorizer = true
+		case configSectionRegulation:
+			next.Regulation = config.Regulation
+
+			apply = append(apply, func() {
+				r.ctx.providers.Regulator.SetConfig(config.Regulation)
+			})
+		case configSectionNotifier:
+			var fn func()
+
+			if fn, err = r.planNotifier(config); err != nil {
+				return report, nil, nil, err
+			}
+
+			if config.Notifier.TemplatePath != r.current.Notifier.TemplatePath {
+				report.Restart = append(report.Restart, configSectionNotifier+".template_path")
+			}
+
+			if fn == nil {
+				continue
+			}
+
+			next.Notifier = config.Notifier
+			next.Notifier.TemplatePath = r.current.Notifier.TemplatePath
+
+			apply = append(apply, fn)
+		case configSectionLog:
+			if !r.isLogLevelsOnly(config) {
+				report.Restart = append(report.Restart, section)
+
+				continue
+			}
+
+			next.Log.Level, next.Log.Modules = config.Log.Level, config.Log.Modules
+
+			apply = append(apply, func() {
+				logging.SetLevels(config.Log.Level, config.Log.Modules)
+			})
+		case configSectionIdentityProviders:
+			if !r.isOpenIDConnectClientsOnly(config) {
+				report.Restart = append(report.Restart, section)
+
+				continue
+			}
+
+			oidc := *r.current.IdentityProviders.OIDC
+
+			oidc.Clients, oidc.AuthorizationPolicies = config.IdentityProviders.OIDC.Clients, config.IdentityProviders.OIDC.AuthorizationPolicies
+
+			next.IdentityProviders.OIDC = &oidc
+
+			apply = append(apply, func() {
+				r.ctx.providers.OpenIDConnect.SetClients(config.IdentityProviders.OIDC)
+			})
+
+			authorizer = true
+		default:
+			report.Restart = append(report.Restart, section)
+
+			continue
+		}
+
+		report.Applied = append(report.Applied, section)
+	}
+
+	if authorizer {
+		apply = append(apply, func() {
+			r.ctx.providers.Authorizer.SetConfiguration(next)
+		})
+	}
+
+	return report, next, apply, nil
+}
+
+// planNotifier creates the new notifier and performs the startup check so a notifier which does not work is never
+// applied. It returns a nil func if only options which can't be applied without a restart have changed.
+func (r *ConfigReloader) planNotifier(config *schema.Configuration) (fn func(), err error) {
+	a, b := r.current.Notifier, config.Notifier
+
+	a.TemplatePath, b.TemplatePath = "", ""
+
+	if reflect.DeepEqual(a, b) {
+		return nil, nil
+	}
+
+	provider, ok := r.ctx.providers.Notifier.(*notification.ReloadableNotifier)
+	if !ok {
+		return nil, fmt.Errorf("error occurred reloading the notifier: the notifier does not support reloading")
+	}
+
+	notifier := r.ctx.newNotifier(&config.Notifier)
+
+	if err = doStartupCheck(r.ctx, providerNameNotification, notifier, config.Notifier.DisableStartupCheck); err != nil {
+		return nil, fmt.Errorf("error occurred reloading the notifier: the startup check failed: %w", err)
+	}
+
+	return func() {
+		provider.Set(notifier)
+	}, nil
+}
+
+// isOpenIDConnectClientsOnly returns true if the only changes to the identity providers section are the
+// OpenID Connect 1.0 clients and authorization policies.
+func (r *ConfigReloader) isOpenIDConnectClientsOnly(config *schema.Configuration) bool {
+	a, b := r.current.IdentityProviders.OIDC, config.IdentityProviders.OIDC
+
+	if a == nil || b == nil || r.ctx.providers.OpenIDConnect == nil {
+		return false
+	}
+
+	x, y := *a, *b
+
+	x.Clients, y.Clients = nil, nil
+	x.AuthorizationPolicies, y.AuthorizationPolicies = nil, nil
+
+	return reflect.DeepEqual(x, y)
+}
+
+// isLogLevelsOnly returns true if the only changes to the log section are the levels of the standard output and the
+// modules.
+func (r *ConfigReloader) isLogLevelsOnly(config *schema.Configuration) bool {
+	x, y := r.current.Log, config.Log
+
+	x.Level, y.Level = "", ""
+	x.Modules, y.Modules = schema.LogModules{}, schema.LogModules{}
+
+	return reflect.DeepEqual(x, y)
+}
+
+// configReloadChangedSections returns the names of the top level sections which differ between the configurations.
+func configReloadChangedSections(a, b *schema.Configuration) (sections []string) {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	t := va.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		if reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("koanf"), ",")
+
+		sections = append(sections, name)
+	}
+
+	return sections
+}
@@ -0,0 +1,179 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+)
+
+func TestConfigReloadChangedSections(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     func(config *schema.Configuration)
+		expected []string
+	}{
+		{
+			"ShouldDetectNoChanges",
+			func(_ *schema.Configuration) {},
+			nil,
+		},
+		{
+			"ShouldDetectAccessControl",
+			func(config *schema.Configuration) {
+				config.AccessControl.DefaultPolicy = "one_factor"
+			},
+			[]string{configSectionAccessControl},
+		},
+		{
+			"ShouldDetectMultipleSectionsInOrder",
+			func(config *schema.Configuration) {
+				config.Theme = "dark"
+				config.Regulation.MaxRetries = 10
+				config.Server.AssetPath = "/assets"
+			},
+			[]string{"theme", configSectionRegulation, "server"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := newTestConfigReload(), newTestConfigReload()
+
+			tc.have(b)
+
+			assert.Equal(t, tc.expected, configReloadChangedSections(a, b))
+		})
+	}
+}
+
+func TestConfigReloaderPlan(t *testing.T) {
+	testCases := []struct {
+		name            string
+		have            func(config *schema.Configuration)
+		expectedApplied []string
+		expectedRestart []string
+		expected        func(t *testing.T, next *schema.Configuration)
+	}{
+		{
+			"ShouldApplyAccessControlAndRegulation",
+			func(config *schema.Configuration) {
+				config.AccessControl.DefaultPolicy = "one_factor"
+				config.Regulation.MaxRetries = 10
+			},
+			[]string{configSectionAccessControl, configSectionRegulation},
+			nil,
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Equal(t, "one_factor", next.AccessControl.DefaultPolicy)
+				assert.Equal(t, 10, next.Regulation.MaxRetries)
+			},
+		},
+		{
+			"ShouldRequireRestartAndRetainCurrentValues",
+			func(config *schema.Configuration) {
+				config.Theme = "dark"
+				config.Session.Name = "example"
+			},
+			nil,
+			[]string{"theme", "session"},
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Equal(t, "light", next.Theme)
+				assert.Equal(t, "authelia_session", next.Session.Name)
+			},
+		},
+		{
+			"ShouldApplyLogLevels",
+			func(config *schema.Configuration) {
+				config.Log.Level = logging.LevelDebug
+				config.Log.Modules.Storage = logging.LevelTrace
+			},
+			[]string{configSectionLog},
+			nil,
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Equal(t, logging.LevelDebug, next.Log.Level)
+				assert.Equal(t, logging.LevelTrace, next.Log.Modules.Storage)
+			},
+		},
+		{
+			"ShouldRequireRestartForLogOptions",
+			func(config *schema.Configuration) {
+				config.Log.Level = logging.LevelDebug
+				config.Log.Format = logging.FormatJSON
+			},
+			nil,
+			[]string{configSectionLog},
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Equal(t, logging.LevelInfo, next.Log.Level)
+				assert.Equal(t, "", next.Log.Format)
+			},
+		},
+		{
+			"ShouldRequireRestartForTemplatePathOnly",
+			func(config *schema.Configuration) {
+				config.Notifier.TemplatePath = "/templates"
+			},
+			nil,
+			[]string{"notifier.template_path"},
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Equal(t, "", next.Notifier.TemplatePath)
+			},
+		},
+		{
+			"ShouldRequireRestartForIdentityProvidersWithoutProvider",
+			func(config *schema.Configuration) {
+				config.IdentityProviders.OIDC = &schema.IdentityProvidersOpenIDConnect{}
+			},
+			nil,
+			[]string{configSectionIdentityProviders},
+			func(t *testing.T, next *schema.Configuration) {
+				assert.Nil(t, next.IdentityProviders.OIDC)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := NewCmdCtx()
+
+			ctx.config = newTestConfigReload()
+			ctx.cconfig = NewCmdCtxConfig()
+
+			reloader := NewConfigReloader(ctx, false, 0)
+
+			config := newTestConfigReload()
+
+			tc.have(config)
+
+			report, next, _, err := reloader.plan(config)
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedApplied, report.Applied)
+			assert.Equal(t, tc.expectedRestart, report.Restart)
+
+			tc.expected(t, next)
+		})
+	}
+}
+
+func newTestConfigReload() *schema.Configuration {
+	return &schema.Configuration{
+		Theme: "light",
+		Log:   schema.Log{Level: logging.LevelInfo},
+		AccessControl: schema.AccessControl{
+			DefaultPolicy: "deny",
+		},
+		Regulation: schema.Regulation{
+			MaxRetries: 3,
+			FindTime:   time.Minute * 2,
+			BanTime:    time.Minute * 5,
+		},
+		Session: schema.Session{
+			Name: "authelia_session",
+		},
+	}
+}
@@ -1,13 +1,19 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/health"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -40,14 +46,31 @@ func NewRootCmd() (cmd *cobra.Command) {
 
 	cmd.PersistentFlags().StringSliceP(cmdFlagNameConfig, "c", []string{"configuration.yml"}, "configuration files or directories to load, for more information run 'authelia -h authelia config'")
 	cmd.PersistentFlags().StringSlice(cmdFlagNameConfigExpFilters, nil, "list of filters to apply to all configuration files, for more information run 'authelia -h authelia filters'")
+	cmd.PersistentFlags().StringSlice(cmdFlagNameConfigProfiles, nil, "list of configuration profiles to load from the overrides directory alongside the configuration files, for more information run 'authelia -h authelia config'")
+	cmd.PersistentFlags().String(cmdFlagNameConfigMergeLists, "replace", "strategy used to merge the lists of the configuration files, options are 'replace' and 'append', for more information run 'authelia -h authelia config'")
+	cmd.PersistentFlags().String(cmdFlagNameOutput, outputFormatText, "output format of the access-control, crypto, stats, storage, and templates subcommands, options are 'text' and 'json'")
+
+	cmd.Flags().Bool(cmdFlagNameConfigWatch, false, "reload the configuration when the configuration files or directories change, the configuration is always reloaded on SIGHUP")
+	cmd.Flags().Duration(cmdFlagNameConfigSecretsRefreshInterval, 0, "reload the configuration periodically to refresh the secrets referenced from external secret managers, disabled if 0")
+	cmd.Flags().Bool(cmdFlagNameDryRun, false, "construct all providers and check the connectivity of the services they depend on then exit without starting any listeners or making any changes")
 
 	cmd.AddCommand(
-		newAccessControlCommand(ctx),
+		cmdWithOutput(newAccessControlCommand(ctx)),
+		newAPICmd(ctx),
+		newBackupCmd(ctx),
+		newBenchCmd(ctx),
 		newBuildInfoCmd(ctx),
-		newCryptoCmd(ctx),
-		newStorageCmd(ctx),
+		cmdWithOutput(newCryptoCmd(ctx)),
+		newDebugCmd(ctx),
+		newDoctorCmd(ctx),
+		newInitCmd(ctx),
+		cmdWithOutput(newStatsCmd(ctx)),
+		cmdWithOutput(newStorageCmd(ctx)),
+		cmdWithOutput(newTemplatesCmd(ctx)),
+		newUsersCmd(ctx),
 		newConfigCmd(ctx),
 		newConfigValidateLegacyCmd(ctx),
+		newServiceCmd(ctx),
 
 		newHelpTopic("config", "Help for the config file/directory paths", helpTopicConfig),
 		newHelpTopic("filters", "help topic for the config filters", helpTopicConfigFilters),
@@ -58,8 +81,18 @@ func NewRootCmd() (cmd *cobra.Command) {
 	return cmd
 }
 
-func (ctx *CmdCtx) RootRunE(_ *cobra.Command, _ []string) (err error) {
-	ctx.log.Infof("Authelia %s is starting", utils.Version())
+func (ctx *CmdCtx) RootRunE(cmd *cobra.Command, _ []string) (err error) {
+	var dryRun bool
+
+	if dryRun, err = cmd.Flags().GetBool(cmdFlagNameDryRun); err != nil {
+		return err
+	}
+
+	if dryRun {
+		ctx.log.Infof("Authelia %s is starting a dry run", utils.Version())
+	} else {
+		ctx.log.Infof("Authelia %s is starting", utils.Version())
+	}
 
 	if os.Getenv("ENVIRONMENT") == "dev" {
 		ctx.log.Info("===> Authelia is running in development mode. <===")
@@ -69,6 +102,8 @@ func (ctx *CmdCtx) RootRunE(_ *cobra.Command, _ []string) (err error) {
 		ctx.log.Fatalf("Cannot configure logger: %v", err)
 	}
 
+	logging.SetRedactor(logging.NewRedactor(ctx.config.Log.Redaction, []byte(ctx.config.Storage.EncryptionKey)))
+
 	warns, errs := ctx.LoadProviders()
 
 	if len(warns) != 0 {
@@ -85,15 +120,34 @@ func (ctx *CmdCtx) RootRunE(_ *cobra.Command, _ []string) (err error) {
 		ctx.log.Fatalf("Errors occurred provisioning providers.")
 	}
 
+	if dryRun {
+		doDryRunChecks(ctx)
+
+		return nil
+	}
+
 	doStartupChecks(ctx)
 
+	var (
+		watch   bool
+		refresh time.Duration
+	)
+
+	if watch, err = cmd.Flags().GetBool(cmdFlagNameConfigWatch); err != nil {
+		return err
+	}
+
+	if refresh, err = cmd.Flags().GetDuration(cmdFlagNameConfigSecretsRefreshInterval); err != nil {
+		return err
+	}
+
+	ctx.reload = NewConfigReloader(ctx, watch, refresh)
+
 	ctx.cconfig = nil
 
 	ctx.log.Trace("Starting Services")
 
-	servicesRun(ctx)
-
-	return nil
+	return servicesRunManaged(ctx)
 }
 
 func doStartupChecks(ctx *CmdCtx) {
@@ -151,6 +205,111 @@ func doStartupChecks(ctx *CmdCtx) {
 	}
 }
 
+// doDryRunChecks checks the providers without the side effects of the startup checks such as the storage schema
+// migrations, i.e. the connectivity is checked using the health checks and the storage schema is only inspected.
+func doDryRunChecks(ctx *CmdCtx) {
+	var failures []string
+
+	// The same failures are tolerated as when starting, i.e. if the startup check is disabled or the failure is ignored.
+	tolerated := map[string]bool{
+		"notifier": ctx.config.Notifier.DisableStartupCheck,
+		"ntp":      ctx.config.NTP.DisableStartupCheck || ctx.config.NTP.DisableFailure,
+	}
+
+	for _, check := range ctx.providers.Health.Report(ctx).Checks {
+		log := ctx.log.WithFields(map[string]any{logFieldProvider: check.Name, "latency": time.Duration(check.Latency).String()})
+
+		switch {
+		case check.Status == health.StatusUp:
+			log.Info("Dry run check completed successfully")
+		case tolerated[check.Name]:
+			log.WithError(errors.New(check.LastError)).Warn(logMessageDryRunCheckError)
+		default:
+			log.WithError(errors.New(check.LastError)).Error(logMessageDryRunCheckError)
+
+			failures = append(failures, check.Name)
+		}
+	}
+
+	if ctx.config.AuthenticationBackend.File != nil {
+		if err := doDryRunCheckAuthenticationFile(ctx.config.AuthenticationBackend.File); err != nil {
+			ctx.log.WithError(err).WithField(logFieldProvider, providerNameAuthenticationBackend).Error(logMessageDryRunCheckError)
+
+			failures = append(failures, providerNameAuthenticationBackend)
+		} else {
+			ctx.log.WithField(logFieldProvider, providerNameAuthenticationBackend).Info("Dry run check completed successfully")
+		}
+	}
+
+	// The storage schema can only be inspected if the storage is reachable.
+	if !utils.IsStringInSlice(providerNameStorage, failures) {
+		if err := doDryRunCheckStorageSchema(ctx); err != nil {
+			ctx.log.WithError(err).WithField(logFieldProvider, providerNameStorage).Error(logMessageDryRunCheckError)
+
+			failures = append(failures, providerNameStorage)
+		}
+	}
+
+	if len(failures) != 0 {
+		ctx.log.WithField("providers", failures).Fatalf("One or more providers had failures performing dry run checks, for more detail check the error level logs")
+	}
+
+	ctx.log.Info("Dry run completed successfully")
+}
+
+// doDryRunCheckAuthenticationFile loads the file authentication backend database without generating it if it doesn't
+// exist, unlike the startup check.
+func doDryRunCheckAuthenticationFile(config *schema.AuthenticationBackendFile) (err error) {
+	if _, err = os.Stat(config.Path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("user authentication database file doesn't exist at path '%s'", config.Path)
+		}
+
+		return fmt.Errorf("error checking user authentication database file: %w", err)
+	}
+
+	return authentication.NewFileUserDatabase(config.Path, config.Search.Email, config.Search.CaseInsensitive).Load()
+}
+
+func doDryRunCheckStorageSchema(ctx *CmdCtx) (err error) {
+	var version, latest int
+
+	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+		return fmt.Errorf("error checking the schema version: %w", err)
+	}
+
+	if latest, err = ctx.providers.StorageProvider.SchemaLatestVersion(); err != nil {
+		return fmt.Errorf("error checking the latest schema version: %w", err)
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldProvider: providerNameStorage, "version": version, "latest": latest})
+
+	switch {
+	case version == 0:
+		log.Info("Storage schema does not exist and will be created when starting")
+
+		return nil
+	case version < latest:
+		log.Info("Storage schema is outdated and will be migrated when starting")
+	case version > latest:
+		return fmt.Errorf("the schema version %d is newer than the latest schema version %d supported by this version of Authelia", version, latest)
+	default:
+		log.Info("Storage schema is up to date")
+	}
+
+	var result storage.EncryptionValidationResult
+
+	if result, err = ctx.providers.StorageProvider.SchemaEncryptionCheckKey(ctx, false); err != nil && !errors.Is(err, storage.ErrSchemaEncryptionVersionUnsupported) {
+		return fmt.Errorf("error checking the encryption key: %w", err)
+	}
+
+	if !result.Success() {
+		return storage.ErrSchemaEncryptionInvalidKey
+	}
+
+	return nil
+}
+
 func doStartupCheck(ctx *CmdCtx, name string, provider model.StartupCheck, disabled bool) error {
 	if disabled {
 		ctx.log.Debugf("%s provider: startup check skipped as it is disabled", name)
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestDoDryRunCheckAuthenticationFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yml")
+	invalid := filepath.Join(dir, "invalid.yml")
+	missing := filepath.Join(dir, "missing.yml")
+
+	require.NoError(t, os.WriteFile(valid, []byte("users:\n  john:\n    displayname: 'John'\n    password: '$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM'\n    email: 'john@example.com'\n"), 0600))
+	require.NoError(t, os.WriteFile(invalid, []byte("users: [\n"), 0600))
+
+	assert.NoError(t, doDryRunCheckAuthenticationFile(&schema.AuthenticationBackendFile{Path: valid}))
+	assert.Error(t, doDryRunCheckAuthenticationFile(&schema.AuthenticationBackendFile{Path: invalid}))
+	assert.EqualError(t, doDryRunCheckAuthenticationFile(&schema.AuthenticationBackendFile{Path: missing}), "user authentication database file doesn't exist at path '"+missing+"'")
+
+	_, err := os.Stat(missing)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/systemd"
+)
+
+func newServiceCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "service",
+		Short:   cmdAutheliaServiceShort,
+		Long:    cmdAutheliaServiceLong,
+		Example: cmdAutheliaServiceExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(newServiceInstallCmd(ctx), newServiceUninstallCmd(ctx))
+
+	return cmd
+}
+
+func newServiceInstallCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "install",
+		Short:   cmdAutheliaServiceInstallShort,
+		Long:    cmdAutheliaServiceInstallLong,
+		Example: cmdAutheliaServiceInstallExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.ServiceInstallRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameName, serviceNameDefault, "the name of the service")
+	cmd.Flags().String(cmdFlagNameDisplayName, serviceDisplayNameDefault, "the display name of the service")
+	cmd.Flags().String(cmdFlagNameDescription, serviceDescriptionDefault, "the description of the service")
+
+	return cmd
+}
+
+func newServiceUninstallCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "uninstall",
+		Short:   cmdAutheliaServiceUninstallShort,
+		Long:    cmdAutheliaServiceUninstallLong,
+		Example: cmdAutheliaServiceUninstallExample,
+		Args:    cobra.NoArgs,
+		RunE:    ctx.ServiceUninstallRunE,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameName, serviceNameDefault, "the name of the service")
+
+	return cmd
+}
+
+// ServiceManager represents the service manager which supervises the process such as systemd or the Windows Service
+// Control Manager.
+type ServiceManager interface {
+	// Notify the service manager that the process has changed to the ServiceState.
+	Notify(state ServiceState)
+
+	// Stop returns a channel which is closed when the service manager requests that the process stops.
+	Stop() <-chan struct{}
+}
+
+// ServiceState represents a lifecycle state of the process which is reported to the ServiceManager.
+type ServiceState int
+
+const (
+	// ServiceStateReady is the state after the startup is complete or after a configuration reload is complete.
+	ServiceStateReady ServiceState = iota
+
+	// ServiceStateReloading is the state while the configuration is reloading.
+	ServiceStateReloading
+
+	// ServiceStateStopping is the state after the shutdown has been initiated.
+	ServiceStateStopping
+)
+
+// NewSystemdServiceManager returns a new SystemdServiceManager.
+func NewSystemdServiceManager(log *logrus.Logger) (manager *SystemdServiceManager) {
+	return &SystemdServiceManager{
+		log: log.WithField(logFieldService, serviceManagerSystemd),
+	}
+}
+
+// SystemdServiceManager is a ServiceManager which notifies systemd of the lifecycle of the process when it's running
+// as a service with the notify or notify-reload service types. It does nothing otherwise.
+type SystemdServiceManager struct {
+	log *logrus.Entry
+}
+
+// Notify systemd that the process has changed to the ServiceState.
+func (m *SystemdServiceManager) Notify(state ServiceState) {
+	var states []string
+
+	switch state {
+	case ServiceStateReady:
+		states = []string{systemd.StateReady, systemd.Status("Running")}
+	case ServiceStateReloading:
+		states = append(systemd.Reloading(), systemd.Status("Reloading the configuration"))
+	case ServiceStateStopping:
+		states = []string{systemd.StateStopping, systemd.Status("Shutting down")}
+	default:
+		return
+	}
+
+	switch sent, err := systemd.Notify(states...); {
+	case err != nil:
+		m.log.WithError(err).Warn("Error occurred notifying the service manager")
+	case sent:
+		m.log.WithField("states", states).Trace("Notified the service manager")
+	}
+}
+
+// Stop returns a nil channel as systemd requests that the process stops via signals.
+func (m *SystemdServiceManager) Stop() <-chan struct{} {
+	return nil
+}
+
+// NoopServiceManager is a ServiceManager which is used when the process is not supervised by a service manager.
+type NoopServiceManager struct{}
+
+// Notify does nothing.
+func (m *NoopServiceManager) Notify(_ ServiceState) {}
+
+// Stop returns a nil channel as the process is never requested to stop by a service manager.
+func (m *NoopServiceManager) Stop() <-chan struct{} {
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package commands
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+)
+
+func servicesRunManaged(ctx *CmdCtx) (err error) {
+	servicesRun(ctx, NewSystemdServiceManager(ctx.log))
+
+	return nil
+}
+
+// ServiceInstallRunE is the RunE for the authelia service install command.
+func (ctx *CmdCtx) ServiceInstallRunE(_ *cobra.Command, _ []string) (err error) {
+	return fmt.Errorf("error occurred installing the service: %w", errServiceUnsupported())
+}
+
+// ServiceUninstallRunE is the RunE for the authelia service uninstall command.
+func (ctx *CmdCtx) ServiceUninstallRunE(_ *cobra.Command, _ []string) (err error) {
+	return fmt.Errorf("error occurred uninstalling the service: %w", errServiceUnsupported())
+}
+
+func errServiceUnsupported() error {
+	if runtime.GOOS == "linux" {
+		return fmt.Errorf("the operation is only supported on windows, on linux use a systemd unit with the 'notify' or 'notify-reload' service type instead")
+	}
+
+	return fmt.Errorf("the operation is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,246 @@
+//go:build windows
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+func servicesRunManaged(ctx *CmdCtx) (err error) {
+	var managed bool
+
+	if managed, err = svc.IsWindowsService(); err != nil {
+		return fmt.Errorf("error occurred determining if the process is running as a windows service: %w", err)
+	}
+
+	if !managed {
+		servicesRun(ctx, &NoopServiceManager{})
+
+		return nil
+	}
+
+	// The name is ignored by the Service Control Manager for services which run in their own process.
+	if err = svc.Run(serviceNameDefault, &windowsServiceHandler{ctx: ctx}); err != nil {
+		return fmt.Errorf("error occurred running the windows service: %w", err)
+	}
+
+	return nil
+}
+
+type windowsServiceHandler struct {
+	ctx *CmdCtx
+}
+
+// Execute implements svc.Handler.
+func (h *windowsServiceHandler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (specific bool, code uint32) {
+	changes <- svc.Status{State: svc.StartPending}
+
+	manager := NewWindowsServiceManager(changes, h.ctx.log)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		servicesRun(h.ctx, manager)
+	}()
+
+	for {
+		select {
+		case <-done:
+			changes <- svc.Status{State: svc.Stopped}
+
+			return false, 0
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				changes <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				manager.stop()
+			default:
+				manager.log.WithField("command", request.Cmd).Warn("Unexpected control request received from the service manager")
+			}
+		}
+	}
+}
+
+// NewWindowsServiceManager returns a new WindowsServiceManager.
+func NewWindowsServiceManager(changes chan<- svc.Status, log *logrus.Logger) (manager *WindowsServiceManager) {
+	return &WindowsServiceManager{
+		changes: changes,
+		done:    make(chan struct{}),
+		log:     log.WithField(logFieldService, serviceManagerWindows),
+	}
+}
+
+// WindowsServiceManager is a ServiceManager which reports the lifecycle of the process to the Windows Service Control
+// Manager.
+type WindowsServiceManager struct {
+	changes chan<- svc.Status
+
+	done chan struct{}
+	once sync.Once
+
+	log *logrus.Entry
+}
+
+// Notify the Windows Service Control Manager that the process has changed to the ServiceState.
+func (m *WindowsServiceManager) Notify(state ServiceState) {
+	switch state {
+	case ServiceStateReady:
+		m.changes <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	case ServiceStateStopping:
+		m.changes <- svc.Status{State: svc.StopPending}
+	}
+}
+
+// Stop returns a channel which is closed when the Windows Service Control Manager requests that the process stops.
+func (m *WindowsServiceManager) Stop() <-chan struct{} {
+	return m.done
+}
+
+func (m *WindowsServiceManager) stop() {
+	m.once.Do(func() {
+		close(m.done)
+	})
+}
+
+// ServiceInstallRunE is the RunE for the authelia service install command.
+func (ctx *CmdCtx) ServiceInstallRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		name, display, description, executable string
+		configs, filters                       []string
+		lists                                  string
+	)
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameName); err != nil {
+		return err
+	}
+
+	if display, err = cmd.Flags().GetString(cmdFlagNameDisplayName); err != nil {
+		return err
+	}
+
+	if description, err = cmd.Flags().GetString(cmdFlagNameDescription); err != nil {
+		return err
+	}
+
+	if configs, _, err = loadXEnvCLIConfigValues(cmd); err != nil {
+		return err
+	}
+
+	if filters, _, err = loadXEnvCLIStringSliceValue(cmd, cmdFlagEnvNameConfigFilters, cmdFlagNameConfigExpFilters); err != nil {
+		return err
+	}
+
+	if lists, _, err = loadXEnvCLIStringValue(cmd, cmdFlagEnvNameConfigMergeLists, cmdFlagNameConfigMergeLists); err != nil {
+		return err
+	}
+
+	if executable, err = os.Executable(); err != nil {
+		return fmt.Errorf("error occurred installing the service: error occurred determining the path of the executable: %w", err)
+	}
+
+	args := make([]string, 0, len(configs)+len(filters)+1)
+
+	for _, config := range configs {
+		args = append(args, fmt.Sprintf("--%s=%s", cmdFlagNameConfig, config))
+	}
+
+	for _, filter := range filters {
+		args = append(args, fmt.Sprintf("--%s=%s", cmdFlagNameConfigExpFilters, filter))
+	}
+
+	if lists != "" {
+		args = append(args, fmt.Sprintf("--%s=%s", cmdFlagNameConfigMergeLists, lists))
+	}
+
+	var m *mgr.Mgr
+
+	if m, err = mgr.Connect(); err != nil {
+		return fmt.Errorf("error occurred installing the service: error occurred connecting to the service manager: %w", err)
+	}
+
+	defer m.Disconnect()
+
+	var s *mgr.Service
+
+	if s, err = m.OpenService(name); err == nil {
+		s.Close()
+
+		return fmt.Errorf("error occurred installing the service: the service '%s' already exists", name)
+	}
+
+	config := mgr.Config{
+		DisplayName: display,
+		Description: description,
+		StartType:   mgr.StartAutomatic,
+	}
+
+	if s, err = m.CreateService(name, executable, config, args...); err != nil {
+		return fmt.Errorf("error occurred installing the service: %w", err)
+	}
+
+	defer s.Close()
+
+	actions := []mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: serviceRestartDelay},
+		{Type: mgr.ServiceRestart, Delay: serviceRestartDelay},
+		{Type: mgr.ServiceRestart, Delay: serviceRestartDelay},
+	}
+
+	if err = s.SetRecoveryActions(actions, uint32(serviceRecoveryReset/time.Second)); err != nil {
+		return fmt.Errorf("error occurred installing the service: error occurred setting the recovery actions: %w", err)
+	}
+
+	fmt.Printf("Installed the service '%s' with the executable '%s'\n", name, executable)
+
+	return nil
+}
+
+// ServiceUninstallRunE is the RunE for the authelia service uninstall command.
+func (ctx *CmdCtx) ServiceUninstallRunE(cmd *cobra.Command, _ []string) (err error) {
+	var name string
+
+	if name, err = cmd.Flags().GetString(cmdFlagNameName); err != nil {
+		return err
+	}
+
+	var m *mgr.Mgr
+
+	if m, err = mgr.Connect(); err != nil {
+		return fmt.Errorf("error occurred uninstalling the service: error occurred connecting to the service manager: %w", err)
+	}
+
+	defer m.Disconnect()
+
+	var s *mgr.Service
+
+	if s, err = m.OpenService(name); err != nil {
+		if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+			return fmt.Errorf("error occurred uninstalling the service: the service '%s' does not exist", name)
+		}
+
+		return fmt.Errorf("error occurred uninstalling the service: %w", err)
+	}
+
+	defer s.Close()
+
+	if err = s.Delete(); err != nil {
+		return fmt.Errorf("error occurred uninstalling the service: %w", err)
+	}
+
+	fmt.Printf("Uninstalled the service '%s'\n", name)
+
+	return nil
+}
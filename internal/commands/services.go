@@ -2,8 +2,10 @@ package commands
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -13,12 +15,27 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/duo"
+	"github.com/authelia/authelia/v4/internal/kubernetes"
+	"github.com/authelia/authelia/v4/internal/kv"
+	"github.com/authelia/authelia/v4/internal/ldapserver"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/metrics"
+	"github.com/authelia/authelia/v4/internal/ntp"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/server"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/systemd"
 )
 
 // NewServerService creates a new ServerService with the appropriate logger etc.
@@ -33,6 +50,16 @@ func NewServerService(name string, server *fasthttp.Server, listener net.Listene
 	}
 }
 
+// NewLDAPServerService creates a new LDAPServerService with the appropriate logger etc.
+func NewLDAPServerService(name string, server *ldapserver.Server, listener net.Listener, log *logrus.Logger) (service *LDAPServerService) {
+	return &LDAPServerService{
+		name:     name,
+		server:   server,
+		listener: listener,
+		log:      log.WithFields(map[string]any{logFieldService: serviceTypeServer, serviceTypeServer: name}),
+	}
+}
+
 // NewFileWatcherService creates a new FileWatcherService with the appropriate logger etc.
 func NewFileWatcherService(name, path string, reload ProviderReload, log *logrus.Logger) (service *FileWatcherService, err error) {
 	if path == "" {
@@ -83,6 +110,93 @@ func NewFileWatcherService(name, path string, reload ProviderReload, log *logrus
 	return service, nil
 }
 
+// NewKVWatcherService creates a new KVWatcherService with the appropriate logger etc.
+func NewKVWatcherService(name, reference string, reload ProviderReload, log *logrus.Logger) (service *KVWatcherService, err error) {
+	var (
+		client kv.Client
+		key    string
+	)
+
+	if client, key, err = kv.NewClientFromReference(reference); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &KVWatcherService{
+		name:      name,
+		reference: reference,
+		client:    client,
+		key:       key,
+		reload:    reload,
+		ctx:       ctx,
+		cancel:    cancel,
+		log:       log.WithFields(map[string]any{logFieldService: serviceTypeWatcher, serviceTypeWatcher: name}),
+	}, nil
+}
+
+// NewControllerService creates a new ControllerService with the appropriate logger etc.
+func NewControllerService(name string, controller Controller, log *logrus.Logger) (service *ControllerService) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &ControllerService{
+		name:       name,
+		controller: controller,
+		ctx:        ctx,
+		cancel:     cancel,
+		log:        log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: name}),
+	}
+}
+
+// NewRefreshService creates a new RefreshService with the appropriate logger etc.
+func NewRefreshService(name string, interval time.Duration, reload ProviderReload, log *logrus.Logger) (service *RefreshService) {
+	return &RefreshService{
+		name:     name,
+		interval: interval,
+		reload:   reload,
+		done:     make(chan struct{}),
+		log:      log.WithFields(map[string]any{logFieldService: serviceTypeRefresh, serviceTypeRefresh: name}),
+	}
+}
+
+// NewWatchdogService creates a new WatchdogService with the appropriate logger etc.
+func NewWatchdogService(name string, interval time.Duration, ping func() (err error), log *logrus.Logger) (service *WatchdogService) {
+	return &WatchdogService{
+		name:     name,
+		interval: interval,
+		ping:     ping,
+		done:     make(chan struct{}),
+		log:      log.WithFields(map[string]any{logFieldService: serviceTypeWatchdog, serviceTypeWatchdog: name}),
+	}
+}
+
+// NewDriftService creates a new DriftService with the appropriate logger etc.
+func NewDriftService(name string, interval time.Duration, detect func(ctx context.Context) (drifted []string, err error), log *logrus.Logger) (service *DriftService) {
+	return &DriftService{
+		name:     name,
+		interval: interval,
+		detect:   detect,
+		done:     make(chan struct{}),
+		log:      log.WithFields(map[string]any{logFieldService: serviceTypeDrift, serviceTypeDrift: name}),
+	}
+}
+
+// NewTelemetryService creates a new TelemetryService with the appropriate logger etc.
+func NewTelemetryService(name string, interval time.Duration, report func(ctx context.Context) (err error), log *logrus.Logger) (service *TelemetryService) {
+	return &TelemetryService{
+		name:     name,
+		interval: interval,
+		report:   report,
+		done:     make(chan struct{}),
+		log:      log.WithFields(map[string]any{logFieldService: serviceTypeTelemetry, serviceTypeTelemetry: name}),
+	}
+}
+
+// Controller represents the required methods to support running a controller.
+type Controller interface {
+	Run(ctx context.Context)
+}
+
 // ProviderReload represents the required methods to support reloading a provider.
 type ProviderReload interface {
 	Reload() (reloaded bool, err error)
@@ -161,6 +275,59 @@ func (service *ServerService) Log() *logrus.Entry {
 	return service.log
 }
 
+// LDAPServerService is a Service which runs the LDAP server.
+type LDAPServerService struct {
+	name     string
+	server   *ldapserver.Server
+	listener net.Listener
+	log      *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'server'.
+func (service *LDAPServerService) ServiceType() string {
+	return serviceTypeServer
+}
+
+// ServiceName returns the individual name for this service.
+func (service *LDAPServerService) ServiceName() string {
+	return service.name
+}
+
+// Run the LDAPServerService.
+func (service *LDAPServerService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.Infof(fmtLogLDAPServerListening, connectionType(service.server.IsTLS()), service.listener.Addr().String())
+
+	if err = service.server.Serve(service.listener); err != nil {
+		service.log.WithError(err).Error("Error returned attempting to serve requests")
+
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown the LDAPServerService.
+func (service *LDAPServerService) Shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+
+	defer cancel()
+
+	if err := service.server.Shutdown(ctx); err != nil {
+		service.log.WithError(err).Error("Error occurred during shutdown")
+	}
+}
+
+// Log returns the *logrus.Entry of the LDAPServerService.
+func (service *LDAPServerService) Log() *logrus.Entry {
+	return service.log
+}
+
 // FileWatcherService is a Service that watches files for changes.
 type FileWatcherService struct {
 	name string
@@ -246,6 +413,422 @@ func (service *FileWatcherService) Log() *logrus.Entry {
 	return service.log
 }
 
+// KVWatcherService is a Service that watches a key in a key value store for changes.
+type KVWatcherService struct {
+	name      string
+	reference string
+	client    kv.Client
+	key       string
+	reload    ProviderReload
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'watcher'.
+func (service *KVWatcherService) ServiceType() string {
+	return serviceTypeWatcher
+}
+
+// ServiceName returns the individual name for this service.
+func (service *KVWatcherService) ServiceName() string {
+	return service.name
+}
+
+// Run the KVWatcherService.
+func (service *KVWatcherService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	log := service.log.WithField(logFieldReference, service.reference)
+
+	log.Info("Watching key for changes")
+
+	var revision, next uint64
+
+	for {
+		switch {
+		case revision == 0:
+			_, revision, err = service.client.Get(service.ctx, service.key)
+		default:
+			if next, err = service.client.Watch(service.ctx, service.key, revision); err != nil {
+				break
+			}
+
+			revision = next
+
+			log.WithField("revision", revision).Debug("Key modification was detected")
+
+			var reloaded bool
+
+			switch reloaded, err = service.reload.Reload(); {
+			case err != nil:
+				log.WithError(err).Error("Error occurred during reload")
+			case reloaded:
+				log.Info("Reloaded successfully")
+			default:
+				log.Debug("Reload was triggered but it was skipped")
+			}
+
+			continue
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if service.ctx.Err() != nil {
+			return nil
+		}
+
+		log.WithError(err).Error("Error while watching key for changes")
+
+		select {
+		case <-time.After(kvWatcherRetryInterval):
+		case <-service.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Shutdown the KVWatcherService.
+func (service *KVWatcherService) Shutdown() {
+	service.cancel()
+}
+
+// Log returns the *logrus.Entry of the KVWatcherService.
+func (service *KVWatcherService) Log() *logrus.Entry {
+	return service.log
+}
+
+// ControllerService is a Service which runs a controller such as the Kubernetes controller.
+type ControllerService struct {
+	name       string
+	controller Controller
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'controller'.
+func (service *ControllerService) ServiceType() string {
+	return serviceTypeController
+}
+
+// ServiceName returns the individual name for this service.
+func (service *ControllerService) ServiceName() string {
+	return service.name
+}
+
+// Run the ControllerService.
+func (service *ControllerService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.Info("Controller is starting")
+
+	service.controller.Run(service.ctx)
+
+	return nil
+}
+
+// Shutdown the ControllerService.
+func (service *ControllerService) Shutdown() {
+	service.cancel()
+}
+
+// Log returns the *logrus.Entry of the ControllerService.
+func (service *ControllerService) Log() *logrus.Entry {
+	return service.log
+}
+
+// RefreshService is a Service which periodically reloads a provider.
+type RefreshService struct {
+	name     string
+	interval time.Duration
+	reload   ProviderReload
+
+	done chan struct{}
+	once sync.Once
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'refresh'.
+func (service *RefreshService) ServiceType() string {
+	return serviceTypeRefresh
+}
+
+// ServiceName returns the individual name for this service.
+func (service *RefreshService) ServiceName() string {
+	return service.name
+}
+
+// Run the RefreshService.
+func (service *RefreshService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.WithField("interval", service.interval.String()).Info("Refreshing periodically")
+
+	ticker := time.NewTicker(service.interval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			var reloaded bool
+
+			switch reloaded, err = service.reload.Reload(); {
+			case err != nil:
+				service.log.WithError(err).Error("Error occurred during refresh")
+			case reloaded:
+				service.log.Info("Refreshed successfully")
+			default:
+				service.log.Debug("Refresh was triggered but there were no changes to apply")
+			}
+		case <-service.done:
+			return nil
+		}
+	}
+}
+
+// Shutdown the RefreshService.
+func (service *RefreshService) Shutdown() {
+	service.once.Do(func() {
+		close(service.done)
+	})
+}
+
+// Log returns the *logrus.Entry of the RefreshService.
+func (service *RefreshService) Log() *logrus.Entry {
+	return service.log
+}
+
+// WatchdogService is a Service which periodically sends keep-alive pings to a watchdog.
+type WatchdogService struct {
+	name     string
+	interval time.Duration
+	ping     func() (err error)
+
+	done chan struct{}
+	once sync.Once
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'watchdog'.
+func (service *WatchdogService) ServiceType() string {
+	return serviceTypeWatchdog
+}
+
+// ServiceName returns the individual name for this service.
+func (service *WatchdogService) ServiceName() string {
+	return service.name
+}
+
+// Run the WatchdogService.
+func (service *WatchdogService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.WithField("interval", service.interval.String()).Info("Sending keep-alive pings periodically")
+
+	ticker := time.NewTicker(service.interval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err = service.ping(); err != nil {
+				service.log.WithError(err).Error("Error occurred sending keep-alive ping")
+			}
+		case <-service.done:
+			return nil
+		}
+	}
+}
+
+// Shutdown the WatchdogService.
+func (service *WatchdogService) Shutdown() {
+	service.once.Do(func() {
+		close(service.done)
+	})
+}
+
+// Log returns the *logrus.Entry of the WatchdogService.
+func (service *WatchdogService) Log() *logrus.Entry {
+	return service.log
+}
+
+// DriftService is a Service which periodically detects drift between this instance and the other instances.
+type DriftService struct {
+	name     string
+	interval time.Duration
+	detect   func(ctx context.Context) (drifted []string, err error)
+
+	done chan struct{}
+	once sync.Once
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'drift'.
+func (service *DriftService) ServiceType() string {
+	return serviceTypeDrift
+}
+
+// ServiceName returns the individual name for this service.
+func (service *DriftService) ServiceName() string {
+	return service.name
+}
+
+// Run the DriftService.
+func (service *DriftService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.WithField("interval", service.interval.String()).Info("Detecting drift periodically")
+
+	ticker := time.NewTicker(service.interval)
+
+	defer ticker.Stop()
+
+	service.check()
+
+	for {
+		select {
+		case <-ticker.C:
+			service.check()
+		case <-service.done:
+			return nil
+		}
+	}
+}
+
+func (service *DriftService) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), service.interval)
+
+	defer cancel()
+
+	switch drifted, err := service.detect(ctx); {
+	case err != nil:
+		service.log.WithError(err).Error("Error occurred detecting drift")
+	case len(drifted) != 0:
+		service.log.WithField("instances", drifted).Warn("Drift was detected between this instance and other instances which will cause inconsistent behaviour depending on which instance handles a request")
+	default:
+		service.log.Trace("No drift was detected")
+	}
+}
+
+// Shutdown the DriftService.
+func (service *DriftService) Shutdown() {
+	service.once.Do(func() {
+		close(service.done)
+	})
+}
+
+// Log returns the *logrus.Entry of the DriftService.
+func (service *DriftService) Log() *logrus.Entry {
+	return service.log
+}
+
+// TelemetryService is a Service which periodically sends the telemetry such as the anonymous usage reports and the
+// pushed metrics.
+type TelemetryService struct {
+	name     string
+	interval time.Duration
+	report   func(ctx context.Context) (err error)
+
+	done chan struct{}
+	once sync.Once
+
+	log *logrus.Entry
+}
+
+// ServiceType returns the service type for this service, which is always 'telemetry'.
+func (service *TelemetryService) ServiceType() string {
+	return serviceTypeTelemetry
+}
+
+// ServiceName returns the individual name for this service.
+func (service *TelemetryService) ServiceName() string {
+	return service.name
+}
+
+// Run the TelemetryService.
+func (service *TelemetryService) Run() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.log.WithError(recoverErr(r)).Error("Critical error caught (recovered)")
+		}
+	}()
+
+	service.log.WithField("interval", service.interval.String()).Info("Sending telemetry periodically")
+
+	ticker := time.NewTicker(service.interval)
+
+	defer ticker.Stop()
+
+	service.send()
+
+	for {
+		select {
+		case <-ticker.C:
+			service.send()
+		case <-service.done:
+			return nil
+		}
+	}
+}
+
+func (service *TelemetryService) send() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+
+	defer cancel()
+
+	if err := service.report(ctx); err != nil {
+		service.log.WithError(err).Error("Error occurred sending the telemetry")
+
+		return
+	}
+
+	service.log.Trace("Telemetry sent successfully")
+}
+
+// Shutdown the TelemetryService.
+func (service *TelemetryService) Shutdown() {
+	service.once.Do(func() {
+		close(service.done)
+	})
+}
+
+// Log returns the *logrus.Entry of the TelemetryService.
+func (service *TelemetryService) Log() *logrus.Entry {
+	return service.log
+}
+
 func svcSvrMainFunc(ctx *CmdCtx) (service Service) {
 	switch svr, listener, paths, isTLS, err := server.CreateDefaultServer(ctx.config, ctx.providers); {
 	case err != nil:
@@ -272,6 +855,46 @@ func svcSvrMetricsFunc(ctx *CmdCtx) (service Service) {
 	return service
 }
 
+func svcSvrAdminFunc(ctx *CmdCtx) (service Service) {
+	switch svr, listener, paths, isTLS, err := server.CreateAdminServer(ctx.config, ctx.providers); {
+	case err != nil:
+		ctx.log.WithError(err).Fatal("Create Server Service (admin) returned error")
+	case svr != nil && listener != nil:
+		service = NewServerService("admin", svr, listener, paths, isTLS, ctx.log)
+	default:
+		ctx.log.Debug("Create Server Service (admin) skipped")
+	}
+
+	return service
+}
+
+func svcSvrLDAPFunc(ctx *CmdCtx) (service Service) {
+	if !ctx.config.Server.LDAP.Enabled {
+		ctx.log.Debug("Create Server Service (ldap) skipped")
+
+		return nil
+	}
+
+	svr, err := ldapserver.NewServer(&ctx.config.Server.LDAP, ldapserver.Providers{
+		UserProvider: ctx.providers.UserProvider,
+		Storage:      ctx.providers.StorageProvider,
+		Regulator:    ctx.providers.Regulator,
+		Metrics:      ctx.providers.Metrics,
+		Audit:        ctx.providers.Audit,
+		Clock:        clock.New(),
+	}, ctx.log.WithFields(map[string]any{logFieldService: serviceTypeServer, serviceTypeServer: "ldap"}))
+	if err != nil {
+		ctx.log.WithError(err).Fatal("Create Server Service (ldap) returned error")
+	}
+
+	listener, err := ctx.config.Server.LDAP.Address.Listener()
+	if err != nil {
+		ctx.log.WithError(fmt.Errorf("error occurred while attempting to initialize ldap server listener for address '%s': %w", ctx.config.Server.LDAP.Address.String(), err)).Fatal("Create Server Service (ldap) returned error")
+	}
+
+	return NewLDAPServerService("ldap", svr, listener, ctx.log)
+}
+
 func svcWatcherUsersFunc(ctx *CmdCtx) (service Service) {
 	var err error
 
@@ -286,6 +909,282 @@ func svcWatcherUsersFunc(ctx *CmdCtx) (service Service) {
 	return service
 }
 
+func svcWatcherAssetsFunc(ctx *CmdCtx) (service Service) {
+	var err error
+
+	if ctx.config.Server.AssetPath != "" && ctx.config.Server.AssetWatch {
+		if service, err = NewFileWatcherService("assets", ctx.config.Server.AssetPath, ctx.providers.Branding, ctx.log); err != nil {
+			ctx.log.WithError(err).Fatal("Create Watcher Service (assets) returned error")
+		}
+	}
+
+	return service
+}
+
+func svcWatchersConfigFunc(ctx *CmdCtx) (services []Service) {
+	if ctx.reload == nil || !ctx.reload.watch {
+		return nil
+	}
+
+	for _, path := range ctx.reload.files {
+		var (
+			service Service
+			err     error
+		)
+
+		if kv.IsReference(path) {
+			service, err = NewKVWatcherService("configuration", path, ctx.reload, ctx.log)
+		} else {
+			service, err = NewFileWatcherService("configuration", path, ctx.reload, ctx.log)
+		}
+
+		if err != nil {
+			ctx.log.WithError(err).Fatal("Create Watcher Service (configuration) returned error")
+		}
+
+		services = append(services, service)
+	}
+
+	return services
+}
+
+func svcRefreshSecretsFunc(ctx *CmdCtx) (service Service) {
+	if ctx.reload == nil || ctx.reload.refresh <= 0 {
+		return nil
+	}
+
+	return NewRefreshService("secrets", ctx.reload.refresh, ctx.reload, ctx.log)
+}
+
+func svcControllerKubernetesFunc(ctx *CmdCtx) (service Service) {
+	if !ctx.config.AccessControl.Kubernetes.Enable {
+		return nil
+	}
+
+	var (
+		client *kubernetes.Client
+		err    error
+	)
+
+	if client, err = kubernetes.NewInClusterClient(ctx.trusted); err != nil {
+		ctx.log.WithError(err).Fatal("Create Controller Service (kubernetes) returned error")
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "kubernetes"})
+
+	return NewControllerService("kubernetes", kubernetes.NewController(ctx.config.AccessControl, client, ctx.providers.Authorizer, log), ctx.log)
+}
+
+func svcControllerAuditFunc(ctx *CmdCtx) (service Service) {
+	bus, ok := ctx.providers.Audit.(*audit.Bus)
+	if !ok {
+		return nil
+	}
+
+	return NewControllerService("audit", bus, ctx.log)
+}
+
+func svcControllerNTPFunc(ctx *CmdCtx) (service Service) {
+	// The monitoring is also disabled by the startup check option as it promises no remote service is contacted.
+	if ctx.providers.NTP == nil || ctx.config.NTP.DisableStartupCheck || ctx.config.NTP.DisableMonitoring {
+		return nil
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "ntp"})
+
+	return NewControllerService("ntp", ntp.NewMonitor(ctx.providers.NTP, ctx.providers.Metrics, log), ctx.log)
+}
+
+func svcControllerStorageCleanupFunc(ctx *CmdCtx) (service Service) {
+	if ctx.providers.StorageProvider == nil || ctx.config.Storage.Cleanup.Disable {
+		return nil
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "storage-cleanup"})
+
+	cleanup := storage.NewCleanup(ctx.providers.StorageProvider, ctx.config.Storage.Cleanup, auditStorageRetention(&ctx.config.Audit), ctx.providers.Metrics, clock.New(), log)
+
+	// The cleanup deletes rows shared by all replicas so only the leader needs to run it.
+	return NewControllerService("storage-cleanup", ctx.elector.Controller("storage-cleanup", cleanup, log), ctx.log)
+}
+
+func svcControllerSessionRevocationsFunc(ctx *CmdCtx) (service Service) {
+	if ctx.providers.StorageProvider == nil || ctx.providers.SessionProvider == nil {
+		return nil
+	}
+
+	if !(ctx.config.Server.Admin.Enabled && ctx.config.Server.Admin.EnableAPI) && !ctx.config.Session.EnableUserSessions {
+		return nil
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "session-revocations"})
+
+	controller := session.NewRevocationSync(ctx.providers.SessionProvider.Revocations(), ctx.providers.StorageProvider, sessionRevocationSyncInterval, clock.New(), log)
+
+	// Each replica refuses the revoked sessions itself so every replica needs to run it.
+	return NewControllerService("session-revocations", controller, ctx.log)
+}
+
+func svcControllerBackchannelAuthenticationFunc(ctx *CmdCtx) (service Service) {
+	if ctx.providers.OpenIDConnect == nil || (ctx.config.DuoAPI.Disable && ctx.providers.Push == nil) || !ctx.config.IdentityProviders.OIDC.BackchannelAuthentication.Enabled {
+		return nil
+	}
+
+	log := ctx.log.WithFields(map[string]any{logFieldService: serviceTypeController, serviceTypeController: "backchannel-authentication"})
+
+	client := &http.Client{Timeout: time.Second * 10, Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ctx.trusted, MinVersion: tls.VersionTLS12}}}
+
+	var authenticator oidc.BackchannelAuthenticator = ctx.providers.Push
+
+	if authenticator == nil {
+		authenticator = duo.NewDuoAPIFromConfiguration(&ctx.config.DuoAPI, os.Getenv("ENVIRONMENT") == "dev")
+	}
+
+	controller := oidc.NewBackchannelAuthenticationController(ctx.providers.OpenIDConnect.Store, authenticator, client, ctx.config.IdentityProviders.OIDC.BackchannelAuthentication.PollingInterval, clock.New(), log)
+
+	// The status of each request must only be checked and each client notified once so only the leader needs to run it.
+	return NewControllerService("backchannel-authentication", ctx.elector.Controller("backchannel-authentication", controller, log), ctx.log)
+}
+
+// auditStorageRetention returns the longest retention of the storage audit sinks, or 0 if there are none.
+func auditStorageRetention(config *schema.Audit) (retention time.Duration) {
+	if !config.Enabled {
+		return 0
+	}
+
+	for _, sink := range config.Sinks {
+		if sink.Storage != nil && sink.Storage.Retention > retention {
+			retention = sink.Storage.Retention
+		}
+	}
+
+	return retention
+}
+
+func svcWatchdogSystemdFunc(ctx *CmdCtx) (service Service) {
+	interval, err := systemd.WatchdogInterval()
+
+	switch {
+	case err != nil:
+		ctx.log.WithError(err).Error("Error occurred determining the watchdog interval of the service manager")
+
+		return nil
+	case interval <= 0:
+		return nil
+	}
+
+	// The keep-alive pings are sent at half of the interval as recommended by systemd.
+	return NewWatchdogService(serviceManagerSystemd, interval/2, func() (err error) {
+		_, err = systemd.Notify(systemd.StateWatchdog)
+
+		return err
+	}, ctx.log)
+}
+
+func svcDriftConfigurationFunc(ctx *CmdCtx) (service Service) {
+	// Drift can only occur between instances which share a storage backend, which the local backend can't be.
+	if ctx.config.Storage.PostgreSQL == nil && ctx.config.Storage.MySQL == nil && ctx.config.Storage.CockroachDB == nil {
+		return nil
+	}
+
+	config := func() *schema.Configuration {
+		if ctx.reload == nil {
+			return ctx.config
+		}
+
+		return ctx.reload.Current()
+	}
+
+	detect := newConfigurationDriftDetect(configurationDriftInstance(ctx.providers.Random), config, ctx.providers.StorageProvider, ctx.providers.Metrics, clock.New())
+
+	return NewDriftService("configuration", driftConfigurationInterval, detect, ctx.log)
+}
+
+func svcTelemetryUsageFunc(ctx *CmdCtx) (service Service) {
+	if !ctx.config.Telemetry.Usage.Enabled {
+		return nil
+	}
+
+	report := newTelemetryUsageReport(ctx.config, ctx.providers.StorageProvider, ctx.trusted, clock.New())
+
+	// The usage report describes the whole deployment so only the leader needs to send it.
+	report = ctx.elector.Func("telemetry-usage", ctx.config.Telemetry.Usage.Interval, report)
+
+	return NewTelemetryService("usage", ctx.config.Telemetry.Usage.Interval, report, ctx.log)
+}
+
+func svcTelemetryMetricsStatsDFunc(ctx *CmdCtx) (service Service) {
+	if !ctx.config.Telemetry.Metrics.StatsD.Enabled {
+		return nil
+	}
+
+	exporter := metrics.NewStatsD(&ctx.config.Telemetry.Metrics.StatsD, prometheus.DefaultGatherer)
+
+	return NewTelemetryService("statsd", ctx.config.Telemetry.Metrics.StatsD.Interval, exporter.Export, ctx.log)
+}
+
+func svcTelemetryMetricsOTLPFunc(ctx *CmdCtx) (service Service) {
+	if !ctx.config.Telemetry.Metrics.OTLP.Enabled {
+		return nil
+	}
+
+	exporter := metrics.NewOTLP(&ctx.config.Telemetry.Metrics.OTLP, prometheus.DefaultGatherer, ctx.trusted)
+
+	return NewTelemetryService("otlp", ctx.config.Telemetry.Metrics.OTLP.Interval, exporter.Export, ctx.log)
+}
+
+func servicesWait(cctx context.Context, ctx *CmdCtx, manager ServiceManager, quit, hup, usr <-chan os.Signal) {
+	for {
+		select {
+		case s := <-usr:
+			if signalIsLogLevelsIncrease(s) {
+				logging.IncreaseLevels()
+			} else {
+				logging.ResetLevels()
+			}
+
+			level, modules := logging.Levels()
+
+			ctx.log.WithFields(map[string]any{"signal": s.String(), "level": level, "modules": modules}).Info("Log levels adjusted due to process signal")
+		case s := <-hup:
+			log := ctx.log.WithField("signal", s.String())
+
+			if ctx.reload == nil {
+				log.Warn("Configuration reload was requested but it is not supported")
+
+				continue
+			}
+
+			log.Info("Configuration reload initiated due to process signal")
+
+			manager.Notify(ServiceStateReloading)
+
+			switch reloaded, err := ctx.reload.Reload(); {
+			case err != nil:
+				log.WithError(err).Error("Error occurred during configuration reload")
+			case reloaded:
+				log.Info("Configuration reloaded successfully")
+			default:
+				log.Info("Configuration reload completed without applying any changes")
+			}
+
+			manager.Notify(ServiceStateReady)
+		case s := <-quit:
+			ctx.log.WithField("signal", s.String()).Debug("Shutdown initiated due to process signal")
+
+			return
+		case <-manager.Stop():
+			ctx.log.Debug("Shutdown initiated due to service manager request")
+
+			return
+		case <-cctx.Done():
+			ctx.log.Debug("Shutdown initiated due to context completion")
+
+			return
+		}
+	}
+}
+
 func connectionType(isTLS bool) string {
 	if isTLS {
 		return "TLS"
@@ -294,49 +1193,62 @@ func connectionType(isTLS bool) string {
 	return "non-TLS"
 }
 
-func servicesRun(ctx *CmdCtx) {
+func servicesRun(ctx *CmdCtx, manager ServiceManager) {
 	cctx, cancel := context.WithCancel(ctx)
 
 	group, cctx := errgroup.WithContext(cctx)
 
 	defer cancel()
 
-	quit := make(chan os.Signal, 1)
+	quit, hup, usr := make(chan os.Signal, 1), make(chan os.Signal, 1), make(chan os.Signal, 1)
 
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(hup, syscall.SIGHUP)
+	signalNotifyLogLevels(usr)
 
 	defer signal.Stop(quit)
+	defer signal.Stop(hup)
+	defer signal.Stop(usr)
 
 	var (
 		services []Service
 	)
 
+	load := func(service Service) {
+		service.Log().Trace("Service Loaded")
+
+		services = append(services, service)
+
+		group.Go(service.Run)
+	}
+
 	for _, serviceFunc := range []func(ctx *CmdCtx) Service{
-		svcSvrMainFunc, svcSvrMetricsFunc,
-		svcWatcherUsersFunc,
+		svcSvrMainFunc, svcSvrMetricsFunc, svcSvrAdminFunc, svcSvrLDAPFunc,
+		svcWatcherUsersFunc, svcWatcherAssetsFunc, svcRefreshSecretsFunc, svcControllerKubernetesFunc,
+		svcControllerAuditFunc, svcControllerNTPFunc, svcControllerStorageCleanupFunc, svcControllerSessionRevocationsFunc, svcControllerBackchannelAuthenticationFunc,
+		svcWatchdogSystemdFunc, svcDriftConfigurationFunc, svcTelemetryUsageFunc, svcTelemetryMetricsStatsDFunc, svcTelemetryMetricsOTLPFunc,
 	} {
 		if service := serviceFunc(ctx); service != nil {
-			service.Log().Trace("Service Loaded")
-
-			services = append(services, service)
-
-			group.Go(service.Run)
+			load(service)
 		}
 	}
 
+	for _, service := range svcWatchersConfigFunc(ctx) {
+		load(service)
+	}
+
 	ctx.log.Info("Startup complete")
 
-	select {
-	case s := <-quit:
-		ctx.log.WithField("signal", s.String()).Debug("Shutdown initiated due to process signal")
-	case <-cctx.Done():
-		ctx.log.Debug("Shutdown initiated due to context completion")
-	}
+	manager.Notify(ServiceStateReady)
+
+	servicesWait(cctx, ctx, manager, quit, hup, usr)
 
 	cancel()
 
 	ctx.log.Info("Shutdown initiated")
 
+	manager.Notify(ServiceStateStopping)
+
 	wgShutdown := &sync.WaitGroup{}
 
 	ctx.log.Tracef("Shutdown of %d services is required", len(services))
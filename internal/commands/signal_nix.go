@@ -0,0 +1,19 @@
+//go:build !windows
+
+package commands
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// signalNotifyLogLevels relays the signals which adjust the log levels at runtime to the channel.
+func signalNotifyLogLevels(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+}
+
+// signalIsLogLevelsIncrease returns true if the signal increases the log levels, otherwise it resets them.
+func signalIsLogLevelsIncrease(s os.Signal) bool {
+	return s == syscall.SIGUSR1
+}
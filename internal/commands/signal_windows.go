@@ -0,0 +1,16 @@
+//go:build windows
+
+package commands
+
+import (
+	"os"
+)
+
+// signalNotifyLogLevels is a no-op as windows does not have the signals which adjust the log levels at runtime.
+func signalNotifyLogLevels(_ chan<- os.Signal) {}
+
+// signalIsLogLevelsIncrease always returns false as windows does not have the signals which adjust the log levels at
+// runtime.
+func signalIsLogLevelsIncrease(_ os.Signal) bool {
+	return false
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newStatsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "stats",
+		Short:   cmdAutheliaStatsShort,
+		Long:    cmdAutheliaStatsLong,
+		Example: cmdAutheliaStatsExample,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigStorageCommandLineConfigRunE,
+			ctx.HelperConfigLoadRunE,
+			ctx.ConfigValidateStorageRunE,
+			ctx.LoadProvidersStorageRunE,
+		),
+		RunE: ctx.StatsRunE,
+		Args: cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorage(cmd)
+
+	cmd.Flags().Int(cmdFlagNameDays, 30, "the number of days a user must have signed in within to be considered active")
+
+	return cmd
+}
+
+// StatsRunE is the RunE for the authelia stats command.
+func (ctx *CmdCtx) StatsRunE(cmd *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var days int
+
+	if days, err = cmd.Flags().GetInt(cmdFlagNameDays); err != nil {
+		return err
+	}
+
+	if days < 1 {
+		return fmt.Errorf("the '--%s' flag must be at least 1 but it's configured as %d", cmdFlagNameDays, days)
+	}
+
+	var result statsResult
+
+	if result, err = statsLoad(ctx, ctx.config, ctx.providers.StorageProvider, time.Now(), time.Hour*24*time.Duration(days)); err != nil {
+		return err
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
+	return statsWriteReport(os.Stdout, result)
+}
+
+// statsLoad loads the usage statistics from the storage provider considering the users and clients active within the
+// period before now.
+func statsLoad(ctx context.Context, config *schema.Configuration, provider storage.Provider, now time.Time, period time.Duration) (result statsResult, err error) {
+	var stats model.UsageStatistics
+
+	if stats, err = provider.LoadUsageStatistics(ctx, now.Add(-period)); err != nil {
+		return statsResult{}, fmt.Errorf("failed to load the usage statistics: %w", err)
+	}
+
+	clients := 0
+
+	if config.IdentityProviders.OIDC != nil {
+		clients = len(config.IdentityProviders.OIDC.Clients)
+	}
+
+	return newStatsResult(stats, clients, period), nil
+}
+
+func newStatsResult(stats model.UsageStatistics, clients int, period time.Duration) statsResult {
+	return statsResult{
+		Days:                 int(period / (time.Hour * 24)),
+		Users:                stats.Users,
+		UsersActive:          stats.UsersActive,
+		UsersSecondFactor:    stats.UsersSecondFactor,
+		SecondFactorAdoption: statsPercentage(stats.UsersSecondFactor, stats.Users),
+		Methods: statsResultMethods{
+			TOTP:     stats.UsersTOTP,
+			WebAuthn: stats.UsersWebAuthn,
+			Duo:      stats.UsersDuo,
+		},
+		ClientsConfigured: clients,
+		ClientsActive:     stats.ClientsActive,
+	}
+}
+
+// statsPercentage returns the value as a percentage of the total rounded to one decimal place.
+func statsPercentage(value, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return math.Round(float64(value)/float64(total)*1000) / 10
+}
+
+func statsWriteReport(out io.Writer, result statsResult) (err error) {
+	w := tabwriter.NewWriter(out, 1, 1, 4, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "Users:\t%d\n", result.Users)
+	_, _ = fmt.Fprintf(w, "Active Users (%d days):\t%d\n", result.Days, result.UsersActive)
+	_, _ = fmt.Fprintf(w, "2FA Adoption:\t%.1f%% (%d users)\n", result.SecondFactorAdoption, result.UsersSecondFactor)
+	_, _ = fmt.Fprintf(w, "    TOTP:\t%d\n", result.Methods.TOTP)
+	_, _ = fmt.Fprintf(w, "    WebAuthn:\t%d\n", result.Methods.WebAuthn)
+	_, _ = fmt.Fprintf(w, "    Duo:\t%d\n", result.Methods.Duo)
+	_, _ = fmt.Fprintf(w, "OpenID Connect 1.0 Clients:\t%d configured, %d in use (%d days)\n", result.ClientsConfigured, result.ClientsActive, result.Days)
+
+	return w.Flush()
+}
+
+type statsResult struct {
+	Days                 int                `json:"days"`
+	Users                int                `json:"users"`
+	UsersActive          int                `json:"users_active"`
+	UsersSecondFactor    int                `json:"users_second_factor"`
+	SecondFactorAdoption float64            `json:"second_factor_adoption"`
+	Methods              statsResultMethods `json:"methods"`
+	ClientsConfigured    int                `json:"clients_configured"`
+	ClientsActive        int                `json:"clients_active"`
+}
+
+type statsResultMethods struct {
+	TOTP     int `json:"totp"`
+	WebAuthn int `json:"webauthn"`
+	Duo      int `json:"duo"`
+}
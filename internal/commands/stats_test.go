@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestNewStatsResult(t *testing.T) {
+	stats := model.UsageStatistics{
+		Users:             8,
+		UsersActive:       5,
+		UsersSecondFactor: 3,
+		UsersTOTP:         2,
+		UsersWebAuthn:     2,
+		UsersDuo:          1,
+		ClientsActive:     1,
+	}
+
+	result := newStatsResult(stats, 4, time.Hour*24*30)
+
+	assert.Equal(t, statsResult{
+		Days:                 30,
+		Users:                8,
+		UsersActive:          5,
+		UsersSecondFactor:    3,
+		SecondFactorAdoption: 37.5,
+		Methods:              statsResultMethods{TOTP: 2, WebAuthn: 2, Duo: 1},
+		ClientsConfigured:    4,
+		ClientsActive:        1,
+	}, result)
+
+	buf := &bytes.Buffer{}
+
+	require.NoError(t, statsWriteReport(buf, result))
+
+	assert.Contains(t, buf.String(), "Active Users (30 days):")
+	assert.Contains(t, buf.String(), "37.5% (3 users)")
+	assert.Contains(t, buf.String(), "4 configured, 1 in use (30 days)")
+}
+
+func TestStatsPercentage(t *testing.T) {
+	assert.Equal(t, 0.0, statsPercentage(0, 0))
+	assert.Equal(t, 100.0, statsPercentage(3, 3))
+	assert.Equal(t, 33.3, statsPercentage(1, 3))
+	assert.Equal(t, 66.7, statsPercentage(2, 3))
+}
+
+func TestTelemetryInstallationID(t *testing.T) {
+	id := telemetryInstallationID("a-very-long-encryption-key-value")
+
+	assert.Len(t, id, 32)
+	assert.Equal(t, id, telemetryInstallationID("a-very-long-encryption-key-value"))
+	assert.NotEqual(t, id, telemetryInstallationID("another-long-encryption-key-value"))
+	assert.NotContains(t, id, "encryption")
+}
+
+func TestTelemetryStorageType(t *testing.T) {
+	assert.Equal(t, "postgres", telemetryStorageType(&schema.Storage{PostgreSQL: &schema.StoragePostgreSQL{}}))
+	assert.Equal(t, "cockroachdb", telemetryStorageType(&schema.Storage{CockroachDB: &schema.StorageCockroachDB{}}))
+	assert.Equal(t, "mysql", telemetryStorageType(&schema.Storage{MySQL: &schema.StorageMySQL{}}))
+	assert.Equal(t, "sqlite", telemetryStorageType(&schema.Storage{Local: &schema.StorageLocal{}}))
+	assert.Equal(t, "unknown", telemetryStorageType(&schema.Storage{}))
+}
+
+func TestTelemetryUsageReport(t *testing.T) {
+	now := time.Unix(1760000000, 0)
+
+	testCases := []struct {
+		name        string
+		status      int
+		err         error
+		expectedErr string
+	}{
+		{"ShouldSendReport", http.StatusNoContent, nil, ""},
+		{"ShouldReturnErrStatusCode", http.StatusInternalServerError, nil, "error posting the usage report: the endpoint responded with status code 500"},
+		{"ShouldReturnErrStorage", http.StatusNoContent, errors.New("bad conn"), "failed to load the usage statistics: bad conn"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received []byte
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received, _ = io.ReadAll(r.Body)
+
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+				w.WriteHeader(tc.status)
+			}))
+
+			defer server.Close()
+
+			endpoint, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			config := &schema.Configuration{
+				Storage: schema.Storage{EncryptionKey: "a-very-long-encryption-key-value", Local: &schema.StorageLocal{Path: "db.sqlite3"}},
+				Telemetry: schema.Telemetry{Usage: schema.TelemetryUsage{
+					Enabled:  true,
+					Endpoint: endpoint,
+					Period:   time.Hour * 24 * 7,
+					Timeout:  time.Second,
+				}},
+			}
+
+			ctrl := gomock.NewController(t)
+
+			provider := mocks.NewMockStorage(ctrl)
+
+			provider.EXPECT().LoadUsageStatistics(gomock.Any(), now.Add(-time.Hour*24*7)).Return(model.UsageStatistics{Users: 2, UsersActive: 1, UsersSecondFactor: 1, UsersTOTP: 1}, tc.err)
+
+			report := newTelemetryUsageReport(config, provider, nil, clock.NewFixed(now))
+
+			err = report(context.Background())
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			actual := telemetryUsageReport{}
+
+			require.NoError(t, json.Unmarshal(received, &actual))
+
+			assert.Equal(t, telemetryInstallationID(config.Storage.EncryptionKey), actual.Installation)
+			assert.Equal(t, utils.Version(), actual.Version)
+			assert.Equal(t, "sqlite", actual.Storage)
+			assert.Equal(t, 7, actual.Usage.Days)
+			assert.Equal(t, 50.0, actual.Usage.SecondFactorAdoption)
+			assert.True(t, now.Equal(actual.Time))
+			assert.NotContains(t, string(received), "a-very-long-encryption-key-value")
+		})
+	}
+}
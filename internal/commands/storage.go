@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func newStorageCmd(ctx *CmdCtx) (cmd *cobra.Command) {
@@ -26,6 +27,21 @@ func newStorageCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
+	cmdFlagsStorage(cmd)
+
+	cmd.AddCommand(
+		newStorageMigrateCmd(ctx),
+		newStorageSchemaInfoCmd(ctx),
+		newStorageEncryptionCmd(ctx),
+		newStorageUserCmd(ctx),
+		newStorageAuditCmd(ctx),
+	)
+
+	return cmd
+}
+
+// cmdFlagsStorage adds the persistent flags which configure the storage provider to the command.
+func cmdFlagsStorage(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(cmdFlagNameEncryptionKey, "", "the storage encryption key to use")
 
 	cmd.PersistentFlags().String(cmdFlagNameSQLite3Path, "", "the SQLite database path")
@@ -46,15 +62,6 @@ func newStorageCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.PersistentFlags().String("postgres.ssl.root_certificate", "", "the PostgreSQL ssl root certificate file location")
 	cmd.PersistentFlags().String("postgres.ssl.certificate", "", "the PostgreSQL ssl certificate file location")
 	cmd.PersistentFlags().String("postgres.ssl.key", "", "the PostgreSQL ssl key file location")
-
-	cmd.AddCommand(
-		newStorageMigrateCmd(ctx),
-		newStorageSchemaInfoCmd(ctx),
-		newStorageEncryptionCmd(ctx),
-		newStorageUserCmd(ctx),
-	)
-
-	return cmd
 }
 
 func newStorageEncryptionCmd(ctx *CmdCtx) (cmd *cobra.Command) {
@@ -71,6 +78,7 @@ func newStorageEncryptionCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newStorageEncryptionChangeKeyCmd(ctx),
 		newStorageEncryptionCheckCmd(ctx),
+		newStorageEncryptionRotateKeyCmd(ctx),
 	)
 
 	return cmd
@@ -110,6 +118,68 @@ func newStorageEncryptionChangeKeyCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageEncryptionRotateKeyCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "rotate-key",
+		Short:   cmdAutheliaStorageEncryptionRotateKeyShort,
+		Long:    cmdAutheliaStorageEncryptionRotateKeyLong,
+		Example: cmdAutheliaStorageEncryptionRotateKeyExample,
+		RunE:    ctx.StorageSchemaEncryptionRotateKeyRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().Int(cmdFlagNameBatchSize, 100, "the number of values to re-encrypt in each transaction")
+
+	return cmd
+}
+
+func newStorageAuditCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "audit",
+		Short:   cmdAutheliaStorageAuditShort,
+		Long:    cmdAutheliaStorageAuditLong,
+		Example: cmdAutheliaStorageAuditExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newStorageAuditListCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newStorageAuditListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaStorageAuditListShort,
+		Long:    cmdAutheliaStorageAuditListLong,
+		Example: cmdAutheliaStorageAuditListExample,
+		RunE:    ctx.StorageAuditListRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorageAuditList(cmd)
+
+	return cmd
+}
+
+func cmdFlagsStorageAuditList(cmd *cobra.Command) {
+	cmd.Flags().String(cmdFlagNameUsername, "", "only include events performed by the specified username")
+	cmd.Flags().String(cmdFlagNameType, "", "only include events with the specified event type")
+	cmd.Flags().String(cmdFlagNameSince, "", "only include events which occurred at or after the specified RFC3339 timestamp or duration ago")
+	cmd.Flags().String(cmdFlagNameUntil, "", "only include events which occurred before the specified RFC3339 timestamp or duration ago")
+	cmd.Flags().Int(cmdFlagNameLimit, 50, "the maximum number of events to list")
+	cmd.Flags().Int(cmdFlagNamePage, 0, "the page of events to list starting at 0")
+	cmd.Flags().String(cmdFlagNameOrder, "desc", "the order to list the events in by the time they occurred, either 'desc' or 'asc'")
+}
+
 func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "user",
@@ -122,7 +192,12 @@ func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	}
 
 	cmd.AddCommand(
+		newStorageUserAddCmd(ctx),
+		newStorageUserModifyCmd(ctx),
+		newStorageUserDeleteCmd(ctx),
 		newStorageUserIdentifiersCmd(ctx),
+		newStorageUserListCmd(ctx),
+		newStorageUserSearchCmd(ctx),
 		newStorageUserTOTPCmd(ctx),
 		newStorageUserWebAuthnCmd(ctx),
 	)
@@ -130,6 +205,110 @@ func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageUserListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaStorageUserListShort,
+		Long:    cmdAutheliaStorageUserListLong,
+		Example: cmdAutheliaStorageUserListExample,
+		RunE:    ctx.StorageUserListRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorageUserList(cmd)
+
+	return cmd
+}
+
+func newStorageUserSearchCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "search <pattern>",
+		Short:   cmdAutheliaStorageUserSearchShort,
+		Long:    cmdAutheliaStorageUserSearchLong,
+		Example: cmdAutheliaStorageUserSearchExample,
+		RunE:    ctx.StorageUserListRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorageUserList(cmd)
+
+	return cmd
+}
+
+func cmdFlagsStorageUserList(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(cmdFlagNameEnrolled, nil, fmt.Sprintf("only include users who have enrolled all of the specified second factor methods, options are %s", utils.StringJoinOr(storageUserMethods)))
+	cmd.Flags().StringSlice(cmdFlagNameNotEnrolled, nil, fmt.Sprintf("only include users who have not enrolled any of the specified second factor methods, options are %s", utils.StringJoinOr(storageUserMethods)))
+	cmd.Flags().Bool(cmdFlagNameBanned, false, "only include users who are currently banned by the regulation")
+	cmd.Flags().String(cmdFlagNameFormat, storageUserFormatTable, fmt.Sprintf("the output format, options are %s", utils.StringJoinOr(storageUserFormats)))
+}
+
+func newStorageUserAddCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "add <username>",
+		Short:   cmdAutheliaStorageUserAddShort,
+		Long:    cmdAutheliaStorageUserAddLong,
+		Example: cmdAutheliaStorageUserAddExample,
+		RunE:    ctx.StorageUserAddRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorageUserDirectory(cmd)
+
+	cmd.Flags().String(cmdFlagNamePassword, "", "the password of the user, the password is read from the terminal if not specified")
+	cmd.Flags().Bool(cmdFlagNameNoConfirm, false, "skip the password confirmation prompt")
+
+	return cmd
+}
+
+func newStorageUserModifyCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "modify <username>",
+		Short:   cmdAutheliaStorageUserModifyShort,
+		Long:    cmdAutheliaStorageUserModifyLong,
+		Example: cmdAutheliaStorageUserModifyExample,
+		RunE:    ctx.StorageUserModifyRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorageUserDirectory(cmd)
+
+	cmd.Flags().String(cmdFlagNamePassword, "", "the new password of the user")
+	cmd.Flags().Bool(cmdFlagNameChangePassword, false, "change the password of the user by reading the new password from the terminal")
+	cmd.Flags().Bool(cmdFlagNameNoConfirm, false, "skip the password confirmation prompt")
+
+	return cmd
+}
+
+func newStorageUserDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete <username>",
+		Short:   cmdAutheliaStorageUserDeleteShort,
+		Long:    cmdAutheliaStorageUserDeleteLong,
+		Example: cmdAutheliaStorageUserDeleteExample,
+		RunE:    ctx.StorageUserDeleteRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func cmdFlagsStorageUserDirectory(cmd *cobra.Command) {
+	cmd.Flags().String(cmdFlagNameDisplayName, "", "the display name of the user")
+	cmd.Flags().StringSlice(cmdFlagNameEmail, nil, "the email addresses of the user, the first address is the primary address")
+	cmd.Flags().StringSlice(cmdFlagNameGroup, nil, "the names of the groups the user is a member of")
+	cmd.Flags().Bool(cmdFlagNameDisabled, false, "disable the user which prevents them from signing in")
+}
+
 func newStorageUserIdentifiersCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "identifiers",
@@ -199,6 +378,9 @@ func newStorageUserIdentifiersGenerateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.Flags().StringSlice(cmdFlagNameServices, []string{identifierServiceOpenIDConnect}, fmt.Sprintf("The list of services to generate the opaque identifiers for, valid values are: %s", strings.Join(validIdentifierServices, ", ")))
 	cmd.Flags().StringSlice(cmdFlagNameSectors, []string{""}, "The list of sectors to generate identifiers for")
 
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameUsers, ctx.CompletionStorageUsernamesFlag)
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameSectors, ctx.CompletionDomains)
+
 	return cmd
 }
 
@@ -211,6 +393,7 @@ func newStorageUserIdentifiersAddCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		RunE:    ctx.StorageUserIdentifiersAddRunE,
 		Args:    cobra.ExactArgs(1),
 
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
 		DisableAutoGenTag: true,
 	}
 
@@ -218,6 +401,8 @@ func newStorageUserIdentifiersAddCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.Flags().String(cmdFlagNameService, identifierServiceOpenIDConnect, fmt.Sprintf("The service to add the identifier for, valid values are: %s", strings.Join(validIdentifierServices, ", ")))
 	cmd.Flags().String(cmdFlagNameSector, "", "The sector identifier to use (should usually be blank)")
 
+	_ = cmd.RegisterFlagCompletionFunc(cmdFlagNameSector, ctx.CompletionDomains)
+
 	return cmd
 }
 
@@ -283,6 +468,7 @@ func newStorageUserWebAuthnListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		RunE:    ctx.StorageUserWebAuthnListRunE,
 		Args:    cobra.MaximumNArgs(1),
 
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
 		DisableAutoGenTag: true,
 	}
 
@@ -298,6 +484,7 @@ func newStorageUserWebAuthnDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		RunE:    ctx.StorageUserWebAuthnDeleteRunE,
 		Args:    cobra.MaximumNArgs(1),
 
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
 		DisableAutoGenTag: true,
 	}
 
@@ -338,6 +525,7 @@ func newStorageUserTOTPGenerateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		RunE:    ctx.StorageUserTOTPGenerateRunE,
 		Args:    cobra.ExactArgs(1),
 
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
 		DisableAutoGenTag: true,
 	}
 
@@ -362,6 +550,7 @@ func newStorageUserTOTPDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		RunE:    ctx.StorageUserTOTPDeleteRunE,
 		Args:    cobra.ExactArgs(1),
 
+		ValidArgsFunction: ctx.CompletionStorageUsernames,
 		DisableAutoGenTag: true,
 	}
 
@@ -380,6 +569,8 @@ func newStorageUserTOTPImportCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
+	cmd.Flags().String(cmdFlagNamePassphrase, "", "manually supply the passphrase for an encrypted bundle rather than using the terminal prompt")
+
 	return cmd
 }
 
@@ -396,6 +587,7 @@ func newStorageUserTOTPExportCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	}
 
 	cmd.AddCommand(
+		newStorageUserTOTPExportBundleCmd(ctx),
 		newStorageUserTOTPExportCSVCmd(ctx),
 		newStorageUserTOTPExportPNGCmd(ctx),
 		newStorageUserTOTPExportURICmd(ctx),
@@ -418,6 +610,26 @@ func newStorageUserTOTPExportURICmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
+	cmd.Flags().StringP(cmdFlagNameFile, "f", "", "The file name for the URI export, if not provided the URIs are printed to the console")
+
+	return cmd
+}
+
+func newStorageUserTOTPExportBundleCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "bundle",
+		Short:   cmdAutheliaStorageUserTOTPExportBundleShort,
+		Long:    cmdAutheliaStorageUserTOTPExportBundleLong,
+		Example: cmdAutheliaStorageUserTOTPExportBundleExample,
+		RunE:    ctx.StorageUserTOTPExportBundleRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().StringP(cmdFlagNameFile, "f", "authelia.export.totp.age", "The file name for the encrypted bundle export")
+	cmd.Flags().String(cmdFlagNamePassphrase, "", "manually supply the passphrase to encrypt the bundle with rather than using the terminal prompt")
+
 	return cmd
 }
 
@@ -3,6 +3,7 @@ package commands
 import (
 	"bytes"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"image"
@@ -12,15 +13,21 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/go-crypt/crypt/algorithm"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/sops"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/totp"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -106,7 +113,7 @@ func (ctx *CmdCtx) ConfigValidateStorageRunE(_ *cobra.Command, _ []string) (err
 		return err
 	}
 
-	validator.ValidateStorage(ctx.config.Storage, ctx.cconfig.validator)
+	validator.ValidateStorage(&ctx.config.Storage, ctx.cconfig.validator)
 
 	validator.ValidateTOTP(ctx.config, ctx.cconfig.validator)
 
@@ -149,39 +156,55 @@ func (ctx *CmdCtx) StorageSchemaEncryptionCheckRunE(cmd *cobra.Command, args []s
 		return err
 	}
 
+	output := storageEncryptionCheckResult{}
+
 	if result, err = ctx.providers.StorageProvider.SchemaEncryptionCheckKey(ctx, verbose); err != nil {
 		switch {
 		case errors.Is(err, storage.ErrSchemaEncryptionVersionUnsupported):
-			fmt.Printf("Storage Encryption Key Validation: FAILURE\n\n\tCause: The schema version doesn't support encryption.\n")
+			output.Result, output.Cause = "FAILURE", "The schema version doesn't support encryption"
 		default:
-			fmt.Printf("Storage Encryption Key Validation: UNKNOWN\n\n\tCause: %v.\n", err)
+			output.Result, output.Cause = "UNKNOWN", err.Error()
 		}
+
+		verbose = false
 	} else {
 		if result.Success() {
-			fmt.Println("Storage Encryption Key Validation: SUCCESS")
+			output.Result = "SUCCESS"
 		} else {
-			fmt.Printf("Storage Encryption Key Validation: FAILURE\n\n\tCause: %v.\n", storage.ErrSchemaEncryptionInvalidKey)
+			output.Result, output.Cause = "FAILURE", storage.ErrSchemaEncryptionInvalidKey.Error()
 		}
 
 		if verbose {
-			fmt.Printf("\nTables:")
+			output.Tables = make([]storageEncryptionCheckTableResult, 0, len(result.Tables))
 
-			tables := make([]string, 0, len(result.Tables))
-
-			for name := range result.Tables {
-				tables = append(tables, name)
+			for name, table := range result.Tables {
+				output.Tables = append(output.Tables, storageEncryptionCheckTableResult{Name: name, Result: table.ResultDescriptor(), Invalid: table.Invalid, Total: table.Total})
 			}
 
-			sort.Strings(tables)
+			sort.Slice(output.Tables, func(i, j int) bool {
+				return output.Tables[i].Name < output.Tables[j].Name
+			})
+		}
+	}
 
-			for _, name := range tables {
-				table := result.Tables[name]
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, output)
+	}
 
-				fmt.Printf("\n\n\tTable (%s): %s\n\t\tInvalid Rows: %d\n\t\tTotal Rows: %d", name, table.ResultDescriptor(), table.Invalid, table.Total)
-			}
+	if output.Cause == "" {
+		fmt.Printf("Storage Encryption Key Validation: %s\n", output.Result)
+	} else {
+		fmt.Printf("Storage Encryption Key Validation: %s\n\n\tCause: %s.\n", output.Result, output.Cause)
+	}
+
+	if verbose {
+		fmt.Printf("\nTables:")
 
-			fmt.Printf("\n")
+		for _, table := range output.Tables {
+			fmt.Printf("\n\n\tTable (%s): %s\n\t\tInvalid Rows: %d\n\t\tTotal Rows: %d", table.Name, table.Result, table.Invalid, table.Total)
 		}
+
+		fmt.Printf("\n")
 	}
 
 	return nil
@@ -234,13 +257,72 @@ func (ctx *CmdCtx) StorageSchemaEncryptionChangeKeyRunE(cmd *cobra.Command, args
 		return err
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, nil)
+	}
+
 	fmt.Println("Completed the encryption key change. Please adjust your configuration to use the new key.")
 
 	return nil
 }
 
+// StorageSchemaEncryptionRotateKeyRunE is the RunE for the authelia storage encryption rotate-key command.
+func (ctx *CmdCtx) StorageSchemaEncryptionRotateKeyRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		batchSize int
+		result    storage.EncryptionRotateKeyResult
+	)
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if batchSize, err = cmd.Flags().GetInt(cmdFlagNameBatchSize); err != nil {
+		return err
+	}
+
+	if len(ctx.config.Storage.PreviousEncryptionKeys) == 0 {
+		return errors.New("the previous encryption keys must be configured to rotate the encryption key")
+	}
+
+	if result, err = ctx.providers.StorageProvider.SchemaEncryptionRotateKey(ctx, batchSize); err != nil {
+		return err
+	}
+
+	output := storageEncryptionRotateKeyResult{
+		Rotated: result.Rotated(),
+		Tables:  make([]storageEncryptionRotateKeyTableResult, 0, len(result.Tables)),
+	}
+
+	for name, table := range result.Tables {
+		output.Tables = append(output.Tables, storageEncryptionRotateKeyTableResult{Name: name, Rotated: table.Rotated, Skipped: table.Skipped, Total: table.Total})
+	}
+
+	sort.Slice(output.Tables, func(i, j int) bool {
+		return output.Tables[i].Name < output.Tables[j].Name
+	})
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, output)
+	}
+
+	fmt.Printf("Storage Encryption Key Rotation: %d values re-encrypted\n\nTables:", output.Rotated)
+
+	for _, table := range output.Tables {
+		fmt.Printf("\n\n\tTable (%s):\n\t\tRotated Rows: %d\n\t\tSkipped Rows: %d\n\t\tTotal Rows: %d", table.Name, table.Rotated, table.Skipped, table.Total)
+	}
+
+	fmt.Printf("\n\nCompleted the encryption key rotation. Please remove the previous encryption keys from your configuration once all instances use the new key.\n")
+
+	return nil
+}
+
 // StorageMigrateHistoryRunE is the RunE for the authelia storage migrate history command.
-func (ctx *CmdCtx) StorageMigrateHistoryRunE(_ *cobra.Command, _ []string) (err error) {
+func (ctx *CmdCtx) StorageMigrateHistoryRunE(cmd *cobra.Command, _ []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
@@ -255,6 +337,10 @@ func (ctx *CmdCtx) StorageMigrateHistoryRunE(_ *cobra.Command, _ []string) (err
 	}
 
 	if version <= 0 {
+		if cmdOutputIsJSON(cmd) {
+			return cmdOutputWriteJSON(cmd, []model.Migration{})
+		}
+
 		fmt.Println("No migration history is available for schemas that not version 1 or above.")
 		return
 	}
@@ -267,6 +353,10 @@ func (ctx *CmdCtx) StorageMigrateHistoryRunE(_ *cobra.Command, _ []string) (err
 		return errors.New("no migration history found which may indicate a broken schema")
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, migrations)
+	}
+
 	fmt.Printf("Migration History:\n\n")
 
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
@@ -304,6 +394,16 @@ func (ctx *CmdCtx) NewStorageMigrateListRunE(up bool) func(cmd *cobra.Command, a
 			return err
 		}
 
+		if cmdOutputIsJSON(cmd) {
+			output := make([]storageSchemaMigration, len(migrations))
+
+			for i, migration := range migrations {
+				output[i] = storageSchemaMigration{Version: migration.Version, Name: migration.Name}
+			}
+
+			return cmdOutputWriteJSON(cmd, output)
+		}
+
 		if len(migrations) == 0 {
 			fmt.Printf("Storage Schema Migration List (%s)\n\nNo Migrations Available\n", directionStr)
 		} else {
@@ -341,12 +441,11 @@ func (ctx *CmdCtx) NewStorageMigrationRunE(up bool) func(cmd *cobra.Command, arg
 
 		switch {
 		case up:
-			switch cmd.Flags().Changed(cmdFlagNameTarget) {
-			case true:
-				return ctx.providers.StorageProvider.SchemaMigrate(ctx, true, target)
-			default:
-				return ctx.providers.StorageProvider.SchemaMigrate(ctx, true, storage.SchemaLatest)
+			if !cmd.Flags().Changed(cmdFlagNameTarget) {
+				target = storage.SchemaLatest
 			}
+
+			err = ctx.providers.StorageProvider.SchemaMigrate(ctx, true, target)
 		default:
 			if !cmd.Flags().Changed(cmdFlagNameTarget) {
 				return errors.New("you must set a target version")
@@ -362,13 +461,25 @@ func (ctx *CmdCtx) NewStorageMigrationRunE(up bool) func(cmd *cobra.Command, arg
 				return errors.New("cancelling down migration due to user not accepting data destruction")
 			}
 
-			return ctx.providers.StorageProvider.SchemaMigrate(ctx, false, target)
+			err = ctx.providers.StorageProvider.SchemaMigrate(ctx, false, target)
+		}
+
+		if err != nil || !cmdOutputIsJSON(cmd) {
+			return err
+		}
+
+		var version int
+
+		if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+			return err
 		}
+
+		return cmdOutputWriteJSON(cmd, storageMigrateResult{Version: version})
 	}
 }
 
 // StorageSchemaInfoRunE is the RunE for the authelia storage schema info command.
-func (ctx *CmdCtx) StorageSchemaInfoRunE(_ *cobra.Command, _ []string) (err error) {
+func (ctx *CmdCtx) StorageSchemaInfoRunE(cmd *cobra.Command, _ []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
@@ -376,8 +487,8 @@ func (ctx *CmdCtx) StorageSchemaInfoRunE(_ *cobra.Command, _ []string) (err erro
 	var (
 		upgradeStr, tablesStr string
 
-		tables          []string
-		version, latest int
+		tables                      []string
+		version, latest, compatible int
 	)
 
 	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil && err.Error() != "unknown schema state" {
@@ -398,6 +509,10 @@ func (ctx *CmdCtx) StorageSchemaInfoRunE(_ *cobra.Command, _ []string) (err erro
 		return err
 	}
 
+	if compatible, err = ctx.providers.StorageProvider.SchemaCompatibleVersion(ctx); err != nil && err.Error() != "unknown schema state" {
+		return err
+	}
+
 	if latest > version {
 		upgradeStr = fmt.Sprintf("yes - version %d", latest)
 	} else {
@@ -422,7 +537,15 @@ func (ctx *CmdCtx) StorageSchemaInfoRunE(_ *cobra.Command, _ []string) (err erro
 		encryption = "valid"
 	}
 
-	fmt.Printf("Schema Version: %s\nSchema Upgrade Available: %s\nSchema Tables: %s\nSchema Encryption Key: %s\n", storage.SchemaVersionToString(version), upgradeStr, tablesStr, encryption)
+	if cmdOutputIsJSON(cmd) {
+		if tables == nil {
+			tables = []string{}
+		}
+
+		return cmdOutputWriteJSON(cmd, storageSchemaInfoResult{Version: version, LatestVersion: latest, CompatibleVersion: compatible, UpgradeAvailable: latest > version, Tables: tables, Encryption: encryption})
+	}
+
+	fmt.Printf("Schema Version: %s\nSchema Compatible Version: %s\nSchema Upgrade Available: %s\nSchema Tables: %s\nSchema Encryption Key: %s\n", storage.SchemaVersionToString(version), storage.SchemaVersionToString(compatible), upgradeStr, tablesStr, encryption)
 
 	return nil
 }
@@ -486,6 +609,10 @@ func (ctx *CmdCtx) StorageUserWebAuthnExportRunE(cmd *cobra.Command, args []stri
 		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatYAML, Path: filename})
+	}
+
 	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, count, "WebAuthn credentials", "YAML", filename)
 
 	return nil
@@ -537,6 +664,10 @@ func (ctx *CmdCtx) StorageUserWebAuthnImportRunE(cmd *cobra.Command, args []stri
 		}
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: len(export.WebAuthnCredentials), Format: storageFileFormatYAML, Path: filename})
+	}
+
 	fmt.Printf(cliOutputFmtSuccessfulUserImportFile, len(export.WebAuthnCredentials), "WebAuthn credentials", "YAML", filename)
 
 	return nil
@@ -567,6 +698,14 @@ func (ctx *CmdCtx) StorageUserWebAuthnListRunE(cmd *cobra.Command, args []string
 		return fmt.Errorf("user '%s' has no WebAuthn credentials", user)
 	case err != nil:
 		return fmt.Errorf("can't list devices for user '%s': %w", user, err)
+	case cmdOutputIsJSON(cmd):
+		output := make([]storageWebAuthnCredential, len(devices))
+
+		for i, device := range devices {
+			output[i] = newStorageWebAuthnCredential(device)
+		}
+
+		return cmdOutputWriteJSON(cmd, output)
 	default:
 		fmt.Printf("WebAuthn Credentials for user '%s':\n\n", user)
 
@@ -583,7 +722,7 @@ func (ctx *CmdCtx) StorageUserWebAuthnListRunE(cmd *cobra.Command, args []string
 }
 
 // StorageUserWebAuthnListAllRunE is the RunE for the authelia storage user webauthn list command when no args are specified.
-func (ctx *CmdCtx) StorageUserWebAuthnListAllRunE(_ *cobra.Command, _ []string) (err error) {
+func (ctx *CmdCtx) StorageUserWebAuthnListAllRunE(cmd *cobra.Command, _ []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
@@ -596,6 +735,8 @@ func (ctx *CmdCtx) StorageUserWebAuthnListAllRunE(_ *cobra.Command, _ []string)
 
 	limit := 10
 
+	output := make([]storageWebAuthnCredential, 0)
+
 	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
 
 	_, _ = fmt.Fprintln(w, "ID\tKID\tDescription\tUsername")
@@ -610,6 +751,8 @@ func (ctx *CmdCtx) StorageUserWebAuthnListAllRunE(_ *cobra.Command, _ []string)
 		}
 
 		for _, device := range devices {
+			output = append(output, newStorageWebAuthnCredential(device))
+
 			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", device.ID, device.KID, device.Description, device.Username)
 		}
 
@@ -618,6 +761,10 @@ func (ctx *CmdCtx) StorageUserWebAuthnListAllRunE(_ *cobra.Command, _ []string)
 		}
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, output)
+	}
+
 	fmt.Printf("WebAuthn Credentials:\n\n")
 
 	return w.Flush()
@@ -647,21 +794,26 @@ func (ctx *CmdCtx) StorageUserWebAuthnDeleteRunE(cmd *cobra.Command, args []stri
 			return fmt.Errorf("failed to delete WebAuthn credential with kid '%s': %w", kid, err)
 		}
 
+		if cmdOutputIsJSON(cmd) {
+			return cmdOutputWriteJSON(cmd, nil)
+		}
+
 		fmt.Printf("Successfully deleted WebAuthn credential with key id '%s'\n", kid)
 	} else {
 		err = ctx.providers.StorageProvider.DeleteWebAuthnCredentialByUsername(ctx, user, description)
 
-		if all {
-			if err != nil {
-				return fmt.Errorf("failed to delete all WebAuthn credentials with username '%s': %w", user, err)
-			}
+		switch {
+		case all && err != nil:
+			return fmt.Errorf("failed to delete all WebAuthn credentials with username '%s': %w", user, err)
+		case err != nil:
+			return fmt.Errorf("failed to delete WebAuthn credential with username '%s' and description '%s': %w", user, description, err)
+		case cmdOutputIsJSON(cmd):
+			return cmdOutputWriteJSON(cmd, nil)
+		}
 
+		if all {
 			fmt.Printf("Successfully deleted all WebAuthn credentials for user '%s'\n", user)
 		} else {
-			if err != nil {
-				return fmt.Errorf("failed to delete WebAuthn credential with username '%s' and description '%s': %w", user, description, err)
-			}
-
 			fmt.Printf("Successfully deleted WebAuthn credential with description '%s' for user '%s'\n", description, user)
 		}
 	}
@@ -731,6 +883,10 @@ func (ctx *CmdCtx) StorageUserTOTPGenerateRunE(cmd *cobra.Command, args []string
 		return err
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageTOTPGenerateResult{Username: args[0], URI: c.URI(), Path: filename})
+	}
+
 	fmt.Printf("Successfully generated TOTP configuration for user '%s' with URI '%s'%s\n", args[0], c.URI(), extraInfo)
 
 	return nil
@@ -756,6 +912,10 @@ func (ctx *CmdCtx) StorageUserTOTPDeleteRunE(cmd *cobra.Command, args []string)
 		return fmt.Errorf("failed to delete TOTP configuration for user '%s': %+v", user, err)
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, nil)
+	}
+
 	fmt.Printf("Successfully deleted TOTP configuration for user '%s'\n", user)
 
 	return nil
@@ -824,12 +984,16 @@ func (ctx *CmdCtx) StorageUserTOTPExportRunE(cmd *cobra.Command, _ []string) (er
 		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatYAML, Path: filename})
+	}
+
 	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, count, "TOTP configurations", "YAML", filename)
 
 	return nil
 }
 
-func (ctx *CmdCtx) StorageUserTOTPImportRunE(_ *cobra.Command, args []string) (err error) {
+func (ctx *CmdCtx) StorageUserTOTPImportRunE(cmd *cobra.Command, args []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
@@ -855,14 +1019,41 @@ func (ctx *CmdCtx) StorageUserTOTPImportRunE(_ *cobra.Command, args []string) (e
 		return err
 	}
 
+	format, formatJSON := "YAML", storageFileFormatYAML
+
+	if sops.IsAgeEncrypted(data) {
+		format, formatJSON = "encrypted bundle", storageFileFormatBundle
+
+		var passphrase string
+
+		if passphrase, err = storageTOTPReadPassphrase(cmd.Flags(), false); err != nil {
+			return err
+		}
+
+		if data, err = sops.AgeDecryptWithPassphrase(data, passphrase); err != nil {
+			return fmt.Errorf("error occurred decrypting the file '%s': %w", filename, err)
+		}
+	}
+
 	export := &model.TOTPConfigurationExport{}
 
-	if err = yaml.Unmarshal(data, export); err != nil {
-		return err
+	switch {
+	case storageTOTPIsURIs(data):
+		if format == "YAML" {
+			format, formatJSON = "TOTP URI", storageFileFormatURI
+		}
+
+		if export.TOTPConfigurations, err = storageTOTPConfigurationsFromURIs(data, time.Now()); err != nil {
+			return err
+		}
+	default:
+		if err = yaml.Unmarshal(data, export); err != nil {
+			return err
+		}
 	}
 
 	if len(export.TOTPConfigurations) == 0 {
-		return fmt.Errorf("can't import a YAML file without TOTP configuration data")
+		return fmt.Errorf("can't import a %s file without TOTP configuration data", format)
 	}
 
 	if err = ctx.CheckSchema(); err != nil {
@@ -875,35 +1066,48 @@ func (ctx *CmdCtx) StorageUserTOTPImportRunE(_ *cobra.Command, args []string) (e
 		}
 	}
 
-	fmt.Printf(cliOutputFmtSuccessfulUserImportFile, len(export.TOTPConfigurations), "TOTP configurations", "YAML", filename)
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: len(export.TOTPConfigurations), Format: formatJSON, Path: filename})
+	}
+
+	fmt.Printf(cliOutputFmtSuccessfulUserImportFile, len(export.TOTPConfigurations), "TOTP configurations", format, filename)
 
 	return nil
 }
 
-func (ctx *CmdCtx) StorageUserTOTPExportURIRunE(_ *cobra.Command, _ []string) (err error) {
+func (ctx *CmdCtx) StorageUserTOTPExportURIRunE(cmd *cobra.Command, _ []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
 
 	var (
-		configs []model.TOTPConfiguration
+		filename string
+		configs  []model.TOTPConfiguration
 	)
 
 	if err = ctx.CheckSchema(); err != nil {
 		return storageWrapCheckSchemaErr(err)
 	}
 
+	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+		return err
+	}
+
 	limit := 10
 	count := 0
 
 	buf := &bytes.Buffer{}
 
+	uris := make([]string, 0)
+
 	for page := 0; true; page++ {
 		if configs, err = ctx.providers.StorageProvider.LoadTOTPConfigurations(ctx, limit, page); err != nil {
 			return err
 		}
 
 		for _, c := range configs {
+			uris = append(uris, c.URI())
+
 			buf.WriteString(fmt.Sprintf("%s\n", c.URI()))
 		}
 
@@ -916,6 +1120,24 @@ func (ctx *CmdCtx) StorageUserTOTPExportURIRunE(_ *cobra.Command, _ []string) (e
 		}
 	}
 
+	if filename != "" {
+		if err = os.WriteFile(filename, buf.Bytes(), 0600); err != nil {
+			return err
+		}
+
+		if cmdOutputIsJSON(cmd) {
+			return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatURI, Path: filename})
+		}
+
+		fmt.Printf(cliOutputFmtSuccessfulUserExportFile, count, "TOTP configurations", "TOTP URI's", filename)
+
+		return nil
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, uris)
+	}
+
 	fmt.Print(buf.String())
 
 	fmt.Printf("\n\nSuccessfully exported %d TOTP configurations as TOTP URI's and printed them to the console\n", count)
@@ -923,6 +1145,81 @@ func (ctx *CmdCtx) StorageUserTOTPExportURIRunE(_ *cobra.Command, _ []string) (e
 	return nil
 }
 
+// StorageUserTOTPExportBundleRunE is the RunE for the authelia storage user totp export bundle command.
+func (ctx *CmdCtx) StorageUserTOTPExportBundleRunE(cmd *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		filename, passphrase string
+		configs              []model.TOTPConfiguration
+		data                 []byte
+	)
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+		return err
+	}
+
+	switch _, err = os.Stat(filename); {
+	case err == nil:
+		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", filename)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	}
+
+	if passphrase, err = storageTOTPReadPassphrase(cmd.Flags(), true); err != nil {
+		return err
+	}
+
+	limit := 10
+	count := 0
+
+	buf := &bytes.Buffer{}
+
+	for page := 0; true; page++ {
+		if configs, err = ctx.providers.StorageProvider.LoadTOTPConfigurations(ctx, limit, page); err != nil {
+			return err
+		}
+
+		for _, c := range configs {
+			buf.WriteString(fmt.Sprintf("%s\n", c.URI()))
+		}
+
+		l := len(configs)
+
+		count += l
+
+		if l < limit {
+			break
+		}
+	}
+
+	if count == 0 {
+		return fmt.Errorf("no data to export")
+	}
+
+	if data, err = sops.AgeEncryptWithPassphrase(buf.Bytes(), passphrase); err != nil {
+		return fmt.Errorf("error occurred encrypting the bundle: %w", err)
+	}
+
+	if err = os.WriteFile(filename, data, 0600); err != nil {
+		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatBundle, Path: filename})
+	}
+
+	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, count, "TOTP configurations", "an encrypted bundle", filename)
+
+	return nil
+}
+
 func (ctx *CmdCtx) StorageUserTOTPExportCSVRunE(cmd *cobra.Command, _ []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
@@ -971,6 +1268,10 @@ func (ctx *CmdCtx) StorageUserTOTPExportCSVRunE(cmd *cobra.Command, _ []string)
 		return err
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatCSV, Path: filename})
+	}
+
 	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, count, "TOTP configurations", "CSV", filename)
 
 	return nil
@@ -1047,75 +1348,262 @@ func (ctx *CmdCtx) StorageUserTOTPExportPNGRunE(cmd *cobra.Command, _ []string)
 		}
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: count, Format: storageFileFormatPNG, Path: dir})
+	}
+
 	fmt.Printf("Successfully exported %d TOTP configuration as QR codes in PNG format to the '%s' directory\n", count, dir)
 
 	return nil
 }
 
-// StorageUserIdentifiersExportRunE is the RunE for the authelia storage user identifiers export command.
-func (ctx *CmdCtx) StorageUserIdentifiersExportRunE(cmd *cobra.Command, _ []string) (err error) {
+// StorageUserListRunE is the RunE for the authelia storage user list and search commands.
+func (ctx *CmdCtx) StorageUserListRunE(cmd *cobra.Command, args []string) (err error) {
 	defer func() {
 		_ = ctx.providers.StorageProvider.Close()
 	}()
 
-	if err = ctx.CheckSchema(); err != nil {
-		return storageWrapCheckSchemaErr(err)
-	}
-
 	var (
-		filename string
+		filter    *storageUserFilter
+		format    string
+		summaries []model.UserSummary
 	)
 
-	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+	if filter, err = storageUserFilterFromFlags(cmd.Flags(), args); err != nil {
 		return err
 	}
 
-	switch _, err = os.Stat(filename); {
-	case err == nil:
-		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", filename)
-	case !os.IsNotExist(err):
-		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	if format, err = cmd.Flags().GetString(cmdFlagNameFormat); err != nil {
+		return err
 	}
 
-	export := &model.UserOpaqueIdentifiersExport{
-		Identifiers: nil,
+	if !utils.IsStringInSlice(format, storageUserFormats) {
+		return fmt.Errorf("the format '%s' is not valid, options are %s", format, utils.StringJoinOr(storageUserFormats))
 	}
 
-	if export.Identifiers, err = ctx.providers.StorageProvider.LoadUserOpaqueIdentifiers(ctx); err != nil {
-		return err
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
 	}
 
-	if len(export.Identifiers) == 0 {
-		return fmt.Errorf("no data to export")
-	}
+	regulator := regulation.NewRegulator(ctx.config.Regulation, ctx.providers.StorageProvider, &clock.Real{})
 
-	if err = exportYAMLWithJSONSchema("export.identifiers", filename, export); err != nil {
-		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
-	}
+	limit := 100
 
-	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, len(export.Identifiers), "User Opaque Identifiers", "YAML", filename)
+	users := make([]storageUser, 0)
 
-	return nil
-}
+	for page := 0; true; page++ {
+		if summaries, err = ctx.providers.StorageProvider.LoadUserSummaries(ctx, limit, page); err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
 
-// StorageUserIdentifiersImportRunE is the RunE for the authelia storage user identifiers import command.
-func (ctx *CmdCtx) StorageUserIdentifiersImportRunE(cmd *cobra.Command, args []string) (err error) {
-	defer func() {
-		_ = ctx.providers.StorageProvider.Close()
-	}()
+		for _, summary := range summaries {
+			if !filter.matchesUsername(summary.Username) || !filter.matchesMethods(summary) {
+				continue
+			}
 
-	var (
-		filename string
+			user := newStorageUser(summary)
 
-		stat os.FileInfo
-		data []byte
-	)
+			var until time.Time
 
-	filename = args[0]
+			if until, err = regulator.Regulate(ctx, summary.Username); errors.Is(err, regulation.ErrUserIsBanned) {
+				user.Banned, user.BannedUntil = true, &until
+			}
 
-	if stat, err = os.Stat(filename); err != nil {
-		return fmt.Errorf("must specify a file that exists but '%s' had an error opening it: %w", filename, err)
-	}
+			if filter.banned && !user.Banned {
+				continue
+			}
+
+			users = append(users, user)
+		}
+
+		if len(summaries) < limit {
+			break
+		}
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, users)
+	}
+
+	if format == storageUserFormatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(users)
+	}
+
+	if len(users) == 0 {
+		fmt.Println("No users were found which match the criteria")
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "Username\tMethod\tTOTP\tWebAuthn\tDuo\tLast Sign In\tBanned")
+
+	for _, user := range users {
+		method, lastSignIn, banned := user.Method, "never", "no"
+
+		if method == "" {
+			method = "none"
+		}
+
+		if user.LastSignIn != nil {
+			lastSignIn = user.LastSignIn.Format(time.RFC3339)
+		}
+
+		if user.Banned {
+			banned = fmt.Sprintf("until %s", user.BannedUntil.Format(time.RFC3339))
+		}
+
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%t\t%s\t%s\n", user.Username, method, user.TOTP, user.WebAuthnCredentials, user.Duo, lastSignIn, banned)
+	}
+
+	return w.Flush()
+}
+
+// StorageAuditListRunE is the RunE for the authelia storage audit list command.
+func (ctx *CmdCtx) StorageAuditListRunE(cmd *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		filter     model.AuditEventFilter
+		pagination model.Pagination
+		order      string
+		events     []model.AuditEvent
+	)
+
+	if filter, err = storageAuditFilterFromFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if pagination.Limit, err = cmd.Flags().GetInt(cmdFlagNameLimit); err != nil {
+		return err
+	}
+
+	if pagination.Page, err = cmd.Flags().GetInt(cmdFlagNamePage); err != nil {
+		return err
+	}
+
+	if pagination.Limit < 1 || pagination.Page < 0 {
+		return fmt.Errorf("the '%s' flag must be 1 or more and the '%s' flag must be 0 or more", cmdFlagNameLimit, cmdFlagNamePage)
+	}
+
+	if order, err = cmd.Flags().GetString(cmdFlagNameOrder); err != nil {
+		return err
+	}
+
+	if pagination.Order, err = model.NewSortOrder(order); err != nil {
+		return fmt.Errorf("the '%s' flag is invalid: %w", cmdFlagNameOrder, err)
+	}
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if events, err = ctx.providers.StorageProvider.LoadAuditEvents(ctx, filter, pagination); err != nil {
+		return fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	results := make([]storageAuditEvent, len(events))
+
+	for i, event := range events {
+		results[i] = newStorageAuditEvent(event)
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, results)
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No audit events were found which match the criteria")
+
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 4, ' ', 0)
+
+	_, _ = fmt.Fprintln(w, "Time\tType\tResult\tUsername\tRemote IP\tRequest")
+
+	for _, event := range results {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", event.Time.Format(time.RFC3339), event.Type, event.Result, storageAuditValue(event.Username), storageAuditValue(event.RemoteIP), storageAuditValue(strings.TrimSpace(event.RequestMethod+" "+event.RequestHost+event.RequestPath)))
+	}
+
+	return w.Flush()
+}
+
+// StorageUserIdentifiersExportRunE is the RunE for the authelia storage user identifiers export command.
+func (ctx *CmdCtx) StorageUserIdentifiersExportRunE(cmd *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var (
+		filename string
+	)
+
+	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+		return err
+	}
+
+	switch _, err = os.Stat(filename); {
+	case err == nil:
+		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", filename)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	}
+
+	export := &model.UserOpaqueIdentifiersExport{
+		Identifiers: nil,
+	}
+
+	if export.Identifiers, err = ctx.providers.StorageProvider.LoadUserOpaqueIdentifiers(ctx); err != nil {
+		return err
+	}
+
+	if len(export.Identifiers) == 0 {
+		return fmt.Errorf("no data to export")
+	}
+
+	if err = exportYAMLWithJSONSchema("export.identifiers", filename, export); err != nil {
+		return fmt.Errorf("error occurred writing to file '%s': %w", filename, err)
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: len(export.Identifiers), Format: storageFileFormatYAML, Path: filename})
+	}
+
+	fmt.Printf(cliOutputFmtSuccessfulUserExportFile, len(export.Identifiers), "User Opaque Identifiers", "YAML", filename)
+
+	return nil
+}
+
+// StorageUserIdentifiersImportRunE is the RunE for the authelia storage user identifiers import command.
+func (ctx *CmdCtx) StorageUserIdentifiersImportRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		filename string
+
+		stat os.FileInfo
+		data []byte
+	)
+
+	filename = args[0]
+
+	if stat, err = os.Stat(filename); err != nil {
+		return fmt.Errorf("must specify a file that exists but '%s' had an error opening it: %w", filename, err)
+	}
 
 	if stat.IsDir() {
 		return fmt.Errorf("must specify a file that exists but '%s' is a directory", filename)
@@ -1145,6 +1633,10 @@ func (ctx *CmdCtx) StorageUserIdentifiersImportRunE(cmd *cobra.Command, args []s
 		}
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageFileResult{Count: len(export.Identifiers), Format: storageFileFormatYAML, Path: filename})
+	}
+
 	fmt.Printf(cliOutputFmtSuccessfulUserImportFile, len(export.Identifiers), "User Opaque Identifiers", "YAML", filename)
 
 	return nil
@@ -1216,6 +1708,10 @@ func (ctx *CmdCtx) StorageUserIdentifiersGenerateRunE(cmd *cobra.Command, _ []st
 		}
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, storageIdentifiersGenerateResult{Users: users, Sectors: sectors, Services: services, Duplicates: duplicates, Added: added})
+	}
+
 	fmt.Printf("Successfully generated and added opaque identifiers:\n")
 	fmt.Printf("\tUsers: '%s'\n", strings.Join(users, "', '"))
 	fmt.Printf("\tSectors: '%s'\n", strings.Join(sectors, "', '"))
@@ -1288,7 +1784,328 @@ func (ctx *CmdCtx) StorageUserIdentifiersAddRunE(cmd *cobra.Command, args []stri
 		return err
 	}
 
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, opaqueID)
+	}
+
 	fmt.Printf("Added User Opaque Identifier:\n\tService: %s\n\tSector: %s\n\tUsername: %s\n\tIdentifier: %s\n\n", opaqueID.Service, opaqueID.SectorID, opaqueID.Username, opaqueID.Identifier)
 
 	return nil
 }
+
+// StorageUserAddRunE is the RunE for the authelia storage user add command.
+func (ctx *CmdCtx) StorageUserAddRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	user := model.DirectoryUser{
+		Username: args[0],
+	}
+
+	var (
+		groups   []string
+		disabled bool
+		password string
+		hash     algorithm.Hash
+		digest   algorithm.Digest
+	)
+
+	if user.DisplayName, err = cmd.Flags().GetString(cmdFlagNameDisplayName); err != nil {
+		return err
+	}
+
+	if user.Emails, err = cmd.Flags().GetStringSlice(cmdFlagNameEmail); err != nil {
+		return err
+	}
+
+	if groups, err = cmd.Flags().GetStringSlice(cmdFlagNameGroup); err != nil {
+		return err
+	}
+
+	if disabled, err = cmd.Flags().GetBool(cmdFlagNameDisabled); err != nil {
+		return err
+	}
+
+	user.Active = !disabled
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if _, err = ctx.storageLoadDirectoryUser(user.Username); err == nil {
+		return fmt.Errorf("the user '%s' already exists", user.Username)
+	} else if !errors.Is(err, storage.ErrNoDirectoryUser) {
+		return err
+	}
+
+	if hash, err = ctx.storageDirectoryUserHash(); err != nil {
+		return err
+	}
+
+	if password, _, err = cmdCryptoHashGetPassword(cmd, nil, false, false); err != nil {
+		return err
+	}
+
+	if password == "" {
+		return fmt.Errorf("the password of the user must not be empty")
+	}
+
+	if digest, err = hash.Hash(password); err != nil {
+		return fmt.Errorf("error occurred hashing the password: %w", err)
+	}
+
+	if user.PublicID, err = uuid.NewRandom(); err != nil {
+		return err
+	}
+
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+
+	if err = ctx.providers.StorageProvider.SaveDirectoryUser(ctx, user); err != nil {
+		return fmt.Errorf("failed to save the user '%s': %w", user.Username, err)
+	}
+
+	if err = ctx.providers.StorageProvider.UpdateDirectoryUserPassword(ctx, user.Username, digest.Encode()); err != nil {
+		return fmt.Errorf("failed to save the password of the user '%s': %w", user.Username, err)
+	}
+
+	if err = ctx.storageSaveDirectoryUserGroups(&user, groups); err != nil {
+		return err
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, newStorageDirectoryUserResult(&user, groups))
+	}
+
+	fmt.Printf("Successfully added the user '%s'\n", user.Username)
+
+	return nil
+}
+
+// StorageUserModifyRunE is the RunE for the authelia storage user modify command.
+func (ctx *CmdCtx) StorageUserModifyRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var user *model.DirectoryUser
+
+	if user, err = ctx.storageLoadDirectoryUser(args[0]); err != nil {
+		if errors.Is(err, storage.ErrNoDirectoryUser) {
+			return fmt.Errorf("the user '%s' doesn't exist", args[0])
+		}
+
+		return err
+	}
+
+	flags := cmd.Flags()
+
+	if flags.Changed(cmdFlagNameDisplayName) {
+		if user.DisplayName, err = flags.GetString(cmdFlagNameDisplayName); err != nil {
+			return err
+		}
+	}
+
+	if flags.Changed(cmdFlagNameEmail) {
+		if user.Emails, err = flags.GetStringSlice(cmdFlagNameEmail); err != nil {
+			return err
+		}
+	}
+
+	if flags.Changed(cmdFlagNameDisabled) {
+		var disabled bool
+
+		if disabled, err = flags.GetBool(cmdFlagNameDisabled); err != nil {
+			return err
+		}
+
+		user.Active = !disabled
+	}
+
+	var changePassword bool
+
+	if changePassword, err = flags.GetBool(cmdFlagNameChangePassword); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = time.Now()
+
+	if err = ctx.providers.StorageProvider.UpdateDirectoryUser(ctx, *user); err != nil {
+		return fmt.Errorf("failed to save the user '%s': %w", user.Username, err)
+	}
+
+	if changePassword || flags.Changed(cmdFlagNamePassword) {
+		var (
+			password string
+			hash     algorithm.Hash
+			digest   algorithm.Digest
+		)
+
+		if hash, err = ctx.storageDirectoryUserHash(); err != nil {
+			return err
+		}
+
+		if password, _, err = cmdCryptoHashGetPassword(cmd, nil, false, false); err != nil {
+			return err
+		}
+
+		if password == "" {
+			return fmt.Errorf("the password of the user must not be empty")
+		}
+
+		if digest, err = hash.Hash(password); err != nil {
+			return fmt.Errorf("error occurred hashing the password: %w", err)
+		}
+
+		if err = ctx.providers.StorageProvider.UpdateDirectoryUserPassword(ctx, user.Username, digest.Encode()); err != nil {
+			return fmt.Errorf("failed to save the password of the user '%s': %w", user.Username, err)
+		}
+	}
+
+	var groups []string
+
+	if flags.Changed(cmdFlagNameGroup) {
+		if groups, err = flags.GetStringSlice(cmdFlagNameGroup); err != nil {
+			return err
+		}
+
+		if err = ctx.storageSaveDirectoryUserGroups(user, groups); err != nil {
+			return err
+		}
+	} else {
+		var results []model.DirectoryGroup
+
+		if results, err = ctx.providers.StorageProvider.LoadDirectoryUserGroups(ctx, user.Username); err != nil {
+			return fmt.Errorf("failed to load the groups of the user '%s': %w", user.Username, err)
+		}
+
+		for _, group := range results {
+			groups = append(groups, group.DisplayName)
+		}
+	}
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, newStorageDirectoryUserResult(user, groups))
+	}
+
+	fmt.Printf("Successfully modified the user '%s'\n", user.Username)
+
+	return nil
+}
+
+// StorageUserDeleteRunE is the RunE for the authelia storage user delete command.
+func (ctx *CmdCtx) StorageUserDeleteRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var user *model.DirectoryUser
+
+	if user, err = ctx.storageLoadDirectoryUser(args[0]); err == nil {
+		err = ctx.providers.StorageProvider.DeleteDirectoryUser(ctx, user.PublicID)
+	}
+
+	switch {
+	case errors.Is(err, storage.ErrNoDirectoryUser):
+		return fmt.Errorf("the user '%s' doesn't exist", args[0])
+	case err != nil:
+		return fmt.Errorf("failed to delete the user '%s': %w", args[0], err)
+	case cmdOutputIsJSON(cmd):
+		return cmdOutputWriteJSON(cmd, nil)
+	}
+
+	fmt.Printf("Successfully deleted the user '%s'\n", user.Username)
+
+	return nil
+}
+
+// storageLoadDirectoryUser loads the directory user with the given username.
+func (ctx *CmdCtx) storageLoadDirectoryUser(username string) (user *model.DirectoryUser, err error) {
+	var users []model.DirectoryUser
+
+	if users, err = ctx.providers.StorageProvider.LoadDirectoryUsers(ctx, model.DirectoryUserFilter{Username: username}, 1, 0); err != nil {
+		return nil, fmt.Errorf("failed to load the user '%s': %w", username, err)
+	}
+
+	if len(users) == 0 || users[0].Username != username {
+		return nil, storage.ErrNoDirectoryUser
+	}
+
+	return &users[0], nil
+}
+
+// storageSaveDirectoryUserGroups replaces the groups of the directory user with the groups with the given names,
+// creating the groups which don't exist.
+func (ctx *CmdCtx) storageSaveDirectoryUserGroups(user *model.DirectoryUser, names []string) (err error) {
+	ids := make([]uuid.UUID, 0, len(names))
+
+	for _, name := range names {
+		var groups []model.DirectoryGroup
+
+		if groups, err = ctx.providers.StorageProvider.LoadDirectoryGroups(ctx, model.DirectoryGroupFilter{DisplayName: name}, 1, 0); err != nil {
+			return fmt.Errorf("failed to load the group '%s': %w", name, err)
+		}
+
+		if len(groups) != 0 {
+			ids = append(ids, groups[0].PublicID)
+
+			continue
+		}
+
+		group := model.DirectoryGroup{
+			DisplayName: name,
+			CreatedAt:   time.Now(),
+		}
+
+		if group.PublicID, err = uuid.NewRandom(); err != nil {
+			return err
+		}
+
+		group.UpdatedAt = group.CreatedAt
+
+		if err = ctx.providers.StorageProvider.SaveDirectoryGroup(ctx, group); err != nil {
+			return fmt.Errorf("failed to save the group '%s': %w", name, err)
+		}
+
+		ids = append(ids, group.PublicID)
+	}
+
+	if err = ctx.providers.StorageProvider.SaveDirectoryUserGroups(ctx, user.PublicID, ids); err != nil {
+		return fmt.Errorf("failed to save the groups of the user '%s': %w", user.Username, err)
+	}
+
+	return nil
+}
+
+// storageDirectoryUserHash returns the password hashing algorithm of the SQL authentication backend, or the default
+// password hashing algorithm if the SQL authentication backend isn't configured.
+func (ctx *CmdCtx) storageDirectoryUserHash() (hash algorithm.Hash, err error) {
+	config := schema.DefaultPasswordConfig
+
+	if ctx.config.AuthenticationBackend.SQL != nil {
+		config = ctx.config.AuthenticationBackend.SQL.Password
+	}
+
+	val := schema.NewStructValidator()
+
+	validator.ValidatePasswordConfiguration(&config, val)
+
+	if errs := val.Errors(); len(errs) != 0 {
+		return nil, fmt.Errorf("errors occurred validating the password configuration: %w", errs[0])
+	}
+
+	if hash, err = authentication.NewFileCryptoHashFromConfig(config); err != nil {
+		return nil, fmt.Errorf("error occurred configuring the password hashing algorithm: %w", err)
+	}
+
+	return hash, nil
+}
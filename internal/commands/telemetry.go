@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// newTelemetryUsageReport returns a function which posts an anonymous usage report to the configured endpoint. The
+// report only contains the aggregate usage statistics, the version and platform of Authelia, and the type of the
+// storage backend, alongside an installation identifier which can't be used to determine the encryption key.
+func newTelemetryUsageReport(config *schema.Configuration, provider storage.Provider, trusted *x509.CertPool, clock clock.Provider) func(ctx context.Context) (err error) {
+	transport := &http.Transport{}
+
+	if config.Telemetry.Usage.TLS != nil {
+		transport.TLSClientConfig = utils.NewTLSConfig(config.Telemetry.Usage.TLS, trusted)
+	}
+
+	client := &http.Client{Timeout: config.Telemetry.Usage.Timeout, Transport: transport}
+
+	endpoint := config.Telemetry.Usage.Endpoint.String()
+
+	installation := telemetryInstallationID(config.Storage.EncryptionKey)
+
+	return func(ctx context.Context) (err error) {
+		now := clock.Now()
+
+		report := telemetryUsageReport{
+			Installation: installation,
+			Version:      utils.Version(),
+			OS:           runtime.GOOS,
+			Arch:         runtime.GOARCH,
+			Storage:      telemetryStorageType(&config.Storage),
+			Time:         now.UTC(),
+		}
+
+		if report.Usage, err = statsLoad(ctx, config, provider, now, config.Telemetry.Usage.Period); err != nil {
+			return err
+		}
+
+		var data []byte
+
+		if data, err = json.Marshal(report); err != nil {
+			return fmt.Errorf("error encoding the usage report: %w", err)
+		}
+
+		var req *http.Request
+
+		if req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("error creating the request: %w", err)
+		}
+
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", fmt.Sprintf("Authelia/%s", report.Version))
+
+		var resp *http.Response
+
+		if resp, err = client.Do(req); err != nil {
+			return fmt.Errorf("error posting the usage report: %w", err)
+		}
+
+		defer resp.Body.Close()
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("error posting the usage report: the endpoint responded with status code %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
+
+// telemetryInstallationID returns an identifier derived from the storage encryption key which is the same for every
+// instance sharing the storage backend, so the reports from multiple replicas of one installation can be deduplicated.
+func telemetryInstallationID(key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(telemetryInstallationContext))
+
+	return hex.EncodeToString(mac.Sum(nil))[:telemetryInstallationLength]
+}
+
+func telemetryStorageType(config *schema.Storage) string {
+	switch {
+	case config.PostgreSQL != nil:
+		return "postgres"
+	case config.CockroachDB != nil:
+		return "cockroachdb"
+	case config.MySQL != nil:
+		return "mysql"
+	case config.Local != nil:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+type telemetryUsageReport struct {
+	Installation string      `json:"installation"`
+	Version      string      `json:"version"`
+	OS           string      `json:"os"`
+	Arch         string      `json:"arch"`
+	Storage      string      `json:"storage"`
+	Usage        statsResult `json:"usage"`
+	Time         time.Time   `json:"time"`
+}
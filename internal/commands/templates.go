@@ -0,0 +1,133 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+func newTemplatesCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "templates",
+		Short:   cmdAutheliaTemplatesShort,
+		Long:    cmdAutheliaTemplatesLong,
+		Example: cmdAutheliaTemplatesExample,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newTemplatesLintCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newTemplatesLintCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "lint",
+		Short:   cmdAutheliaTemplatesLintShort,
+		Long:    cmdAutheliaTemplatesLintLong,
+		Example: cmdAutheliaTemplatesLintExample,
+		RunE:    ctx.TemplatesLintRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNamePath, "", "the path of the notification template overrides, defaults to the 'notifier.template_path' option from the configuration")
+
+	return cmd
+}
+
+// TemplatesLintRunE is the RunE for the authelia templates lint command.
+func (ctx *CmdCtx) TemplatesLintRunE(cmd *cobra.Command, args []string) (err error) {
+	var path string
+
+	if path, err = cmd.Flags().GetString(cmdFlagNamePath); err != nil {
+		return err
+	}
+
+	if path == "" {
+		if err = ctx.HelperConfigLoadRunE(cmd, args); err != nil {
+			return err
+		}
+
+		if path = ctx.config.Notifier.TemplatePath; path == "" {
+			return fmt.Errorf("the '--%s' flag or the 'notifier.template_path' option must be configured", cmdFlagNamePath)
+		}
+	}
+
+	var results []templates.LintResult
+
+	if results, err = templates.LintEmailTemplateOverrides(path); err != nil {
+		return err
+	}
+
+	result := newTemplatesLintResult(path, results)
+
+	if cmdOutputIsJSON(cmd) {
+		return cmdOutputWriteJSON(cmd, result)
+	}
+
+	templatesLintWriteReport(os.Stdout, result)
+
+	if !result.Valid {
+		return fmt.Errorf("the template overrides in the path '%s' have errors", path)
+	}
+
+	return nil
+}
+
+func newTemplatesLintResult(path string, results []templates.LintResult) (result templatesLintResult) {
+	result = templatesLintResult{Path: path, Valid: true, Templates: results}
+
+	for _, r := range results {
+		if r.HasErrors() {
+			result.Valid = false
+		}
+	}
+
+	if result.Templates == nil {
+		result.Templates = []templates.LintResult{}
+	}
+
+	return result
+}
+
+func templatesLintWriteReport(w io.Writer, result templatesLintResult) {
+	if len(result.Templates) == 0 {
+		_, _ = fmt.Fprintf(w, "No template overrides were found in the path '%s'.\n", result.Path)
+
+		return
+	}
+
+	for _, r := range result.Templates {
+		switch {
+		case r.HasErrors():
+			_, _ = fmt.Fprintf(w, "%s: INVALID\n", r.Path)
+		case len(r.Warnings) != 0:
+			_, _ = fmt.Fprintf(w, "%s: WARNING\n", r.Path)
+		default:
+			_, _ = fmt.Fprintf(w, "%s: OK\n", r.Path)
+		}
+
+		for _, e := range r.Errors {
+			_, _ = fmt.Fprintf(w, "\terror: %s\n", e)
+		}
+
+		for _, warning := range r.Warnings {
+			_, _ = fmt.Fprintf(w, "\twarning: %s\n", warning)
+		}
+	}
+}
+
+type templatesLintResult struct {
+	Path      string                 `json:"path"`
+	Valid     bool                   `json:"valid"`
+	Templates []templates.LintResult `json:"templates"`
+}
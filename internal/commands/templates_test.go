@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+func TestNewTemplatesLintResult(t *testing.T) {
+	result := newTemplatesLintResult("/config/templates", nil)
+
+	assert.Equal(t, templatesLintResult{Path: "/config/templates", Valid: true, Templates: []templates.LintResult{}}, result)
+
+	results := []templates.LintResult{
+		{Path: "/config/templates/Event.html"},
+		{Path: "/config/templates/Evnt.txt", Warnings: []string{"ignored"}},
+	}
+
+	assert.True(t, newTemplatesLintResult("/config/templates", results).Valid)
+
+	results = append(results, templates.LintResult{Path: "/config/templates/Event.txt", Errors: []string{"invalid"}})
+
+	assert.False(t, newTemplatesLintResult("/config/templates", results).Valid)
+}
+
+func TestTemplatesLintWriteReport(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	templatesLintWriteReport(buf, newTemplatesLintResult("/config/templates", nil))
+
+	assert.Equal(t, "No template overrides were found in the path '/config/templates'.\n", buf.String())
+
+	buf.Reset()
+
+	templatesLintWriteReport(buf, newTemplatesLintResult("/config/templates", []templates.LintResult{
+		{Path: "/config/templates/Event.html"},
+		{Path: "/config/templates/Event.txt", Errors: []string{"invalid"}, Warnings: []string{"example"}},
+		{Path: "/config/templates/Evnt.txt", Warnings: []string{"ignored"}},
+	}))
+
+	assert.Equal(t, "/config/templates/Event.html: OK\n/config/templates/Event.txt: INVALID\n\terror: invalid\n\twarning: example\n/config/templates/Evnt.txt: WARNING\n\twarning: ignored\n", buf.String())
+}
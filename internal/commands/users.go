@@ -0,0 +1,377 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-crypt/crypt/algorithm"
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func newUsersCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "users",
+		Short:   cmdAutheliaUsersShort,
+		Long:    cmdAutheliaUsersLong,
+		Example: cmdAutheliaUsersExample,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.HelperConfigLoadRunE,
+			ctx.ConfigValidateUsersRunE,
+			ctx.ConfigValidateSectionPasswordRunE,
+		),
+		Args: cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newUsersImportCmd(ctx),
+		newUsersExportCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newUsersImportCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     cmdUseImportFileName,
+		Short:   cmdAutheliaUsersImportShort,
+		Long:    cmdAutheliaUsersImportLong,
+		Example: cmdAutheliaUsersImportExample,
+		RunE:    ctx.UsersImportRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameFormat, "", fmt.Sprintf("the format of the file, options are %s, detected from the file extension if not specified", utils.StringJoinOr(usersFormats)))
+	cmd.Flags().String(cmdFlagNameOnConflict, usersConflictError, fmt.Sprintf("the action to take when a user already exists, options are %s", utils.StringJoinOr(usersConflicts)))
+
+	return cmd
+}
+
+func newUsersExportCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     cmdUseExport,
+		Short:   cmdAutheliaUsersExportShort,
+		Long:    cmdAutheliaUsersExportLong,
+		Example: cmdAutheliaUsersExportExample,
+		RunE:    ctx.UsersExportRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().StringP(cmdFlagNameFile, "f", "", "the file name for the export, the export is written to stdout if not specified")
+	cmd.Flags().String(cmdFlagNameFormat, "", fmt.Sprintf("the format of the export, options are %s, detected from the file extension if not specified and otherwise '%s'", utils.StringJoinOr(usersFormats), usersFormatCSV))
+	cmd.Flags().String(cmdFlagNameBaseDN, "dc=example,dc=com", "the base distinguished name of the entries for the LDIF format")
+
+	return cmd
+}
+
+// ConfigValidateUsersRunE validates the file authentication backend config before running commands using it.
+func (ctx *CmdCtx) ConfigValidateUsersRunE(_ *cobra.Command, _ []string) (err error) {
+	switch {
+	case ctx.config.AuthenticationBackend.File == nil:
+		return fmt.Errorf("the users commands require the file authentication backend to be configured")
+	case ctx.config.AuthenticationBackend.File.Path == "":
+		return fmt.Errorf("the users commands require the file authentication backend path to be configured")
+	}
+
+	return nil
+}
+
+// UsersImportRunE is the RunE for the authelia users import command.
+func (ctx *CmdCtx) UsersImportRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		format, conflict string
+		records          []usersRecord
+		hash             algorithm.Hash
+		file             *os.File
+	)
+
+	filename := args[0]
+
+	if format, err = cmd.Flags().GetString(cmdFlagNameFormat); err != nil {
+		return err
+	}
+
+	if format, err = usersFormat(format, filename); err != nil {
+		return err
+	}
+
+	if conflict, err = cmd.Flags().GetString(cmdFlagNameOnConflict); err != nil {
+		return err
+	}
+
+	if !utils.IsStringInSlice(conflict, usersConflicts) {
+		return fmt.Errorf("the '--%s' flag has the value '%s' but it must be one of %s", cmdFlagNameOnConflict, conflict, utils.StringJoinOr(usersConflicts))
+	}
+
+	if file, err = os.Open(filename); err != nil {
+		return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+	}
+
+	records, err = usersDecode(format, file)
+
+	_ = file.Close()
+
+	if err != nil {
+		return fmt.Errorf("error occurred reading '%s': %w", filename, err)
+	}
+
+	config := ctx.config.AuthenticationBackend.File
+
+	var database *authentication.FileDatabaseModel
+
+	if database, err = usersReadDatabase(config.Path); err != nil {
+		return err
+	}
+
+	if hash, err = authentication.NewFileCryptoHashFromConfig(config.Password); err != nil {
+		return fmt.Errorf("error occurred configuring the password hashing algorithm: %w", err)
+	}
+
+	var result usersImportResult
+
+	if result, err = usersImport(database, records, conflict, hash, config.Search.CaseInsensitive); err != nil {
+		return fmt.Errorf("error occurred importing the users from '%s': %w", filename, err)
+	}
+
+	db := authentication.NewFileUserDatabase(config.Path, config.Search.Email, config.Search.CaseInsensitive)
+
+	if err = database.ReadToFileUserDatabase(db); err != nil {
+		return fmt.Errorf("error occurred validating the users: %w", err)
+	}
+
+	if err = db.LoadAliases(); err != nil {
+		return fmt.Errorf("error occurred validating the users: %w", err)
+	}
+
+	if err = database.Write(config.Path); err != nil {
+		return fmt.Errorf("error occurred writing the users to '%s': %w", config.Path, err)
+	}
+
+	fmt.Printf(cliOutputFmtSuccessfulUsersImportFile, result.created+result.updated, strings.ToUpper(format), filename, config.Path, result.created, result.updated, result.skipped)
+
+	return nil
+}
+
+// UsersExportRunE is the RunE for the authelia users export command.
+func (ctx *CmdCtx) UsersExportRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		filename, format, baseDN string
+		database                 *authentication.FileDatabaseModel
+	)
+
+	if filename, err = cmd.Flags().GetString(cmdFlagNameFile); err != nil {
+		return err
+	}
+
+	if format, err = cmd.Flags().GetString(cmdFlagNameFormat); err != nil {
+		return err
+	}
+
+	if baseDN, err = cmd.Flags().GetString(cmdFlagNameBaseDN); err != nil {
+		return err
+	}
+
+	switch {
+	case filename != "":
+		if format, err = usersFormat(format, filename); err != nil {
+			return err
+		}
+
+		switch _, err = os.Stat(filename); {
+		case err == nil:
+			return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", filename)
+		case !os.IsNotExist(err):
+			return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+		}
+	case format == "":
+		format = usersFormatCSV
+	default:
+		if format, err = usersFormat(format, ""); err != nil {
+			return err
+		}
+	}
+
+	if database, err = usersReadDatabase(ctx.config.AuthenticationBackend.File.Path); err != nil {
+		return err
+	}
+
+	records := usersExport(database)
+
+	var w io.Writer = os.Stdout
+
+	if filename != "" {
+		var file *os.File
+
+		if file, err = os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600); err != nil {
+			return fmt.Errorf("error occurred opening '%s': %w", filename, err)
+		}
+
+		defer file.Close()
+
+		w = file
+	}
+
+	if err = usersEncode(format, w, records, baseDN); err != nil {
+		return fmt.Errorf("error occurred writing the users: %w", err)
+	}
+
+	if filename != "" {
+		fmt.Printf(cliOutputFmtSuccessfulUserExportFile, len(records), "users", strings.ToUpper(format), filename)
+	}
+
+	return nil
+}
+
+const (
+	cliOutputFmtSuccessfulUsersImportFile = "Successfully imported %d users from the %s file '%s' into the '%s' file (%d created, %d updated, %d skipped)\n"
+)
+
+type usersImportResult struct {
+	created, updated, skipped int
+}
+
+// usersReadDatabase reads the file authentication backend database, treating a database which doesn't exist or is
+// empty as a database without any users.
+func usersReadDatabase(path string) (database *authentication.FileDatabaseModel, err error) {
+	database = &authentication.FileDatabaseModel{}
+
+	switch err = database.Read(path); {
+	case err == nil:
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, authentication.ErrNoContent):
+		database.Users = map[string]authentication.FileDatabaseUserDetailsModel{}
+	default:
+		return nil, fmt.Errorf("error occurred reading the users from '%s': %w", path, err)
+	}
+
+	if database.Users == nil {
+		database.Users = map[string]authentication.FileDatabaseUserDetailsModel{}
+	}
+
+	return database, nil
+}
+
+// usersImport merges the records into the database. All records are validated before the database is modified so
+// the database is left unchanged if any of them are invalid or conflict with an existing user when the conflict
+// action is error. Passwords which are not already crypt digests are hashed with the provided hash.
+func usersImport(database *authentication.FileDatabaseModel, records []usersRecord, conflict string, hash algorithm.Hash, caseInsensitive bool) (result usersImportResult, err error) {
+	normalize := func(username string) string {
+		if caseInsensitive {
+			return strings.ToLower(username)
+		}
+
+		return username
+	}
+
+	existing := map[string]string{}
+
+	for username := range database.Users {
+		existing[normalize(username)] = username
+	}
+
+	seen := map[string]int{}
+
+	for i, record := range records {
+		switch {
+		case record.Username == "":
+			return result, fmt.Errorf("user %d: the username is required", i+1)
+		case record.Password == "":
+			return result, fmt.Errorf("user '%s': the password is required", record.Username)
+		}
+
+		if j, ok := seen[normalize(record.Username)]; ok {
+			return result, fmt.Errorf("user '%s': the username is a duplicate of user %d", record.Username, j+1)
+		}
+
+		seen[normalize(record.Username)] = i
+
+		if _, ok := existing[normalize(record.Username)]; ok && conflict == usersConflictError {
+			return result, fmt.Errorf("user '%s': the user already exists, use the '--%s' flag to skip or overwrite existing users", record.Username, cmdFlagNameOnConflict)
+		}
+
+		if usersIsDigest(record.Password) {
+			if _, err = authentication.DecodeAlgorithmDigest(record.Password); err != nil {
+				return result, fmt.Errorf("user '%s': the password digest could not be decoded: %w", record.Username, err)
+			}
+		}
+	}
+
+	for _, record := range records {
+		current, ok := existing[normalize(record.Username)]
+
+		if ok && conflict == usersConflictSkip {
+			result.skipped++
+
+			continue
+		}
+
+		details := authentication.FileDatabaseUserDetailsModel{
+			Password:    record.Password,
+			DisplayName: record.DisplayName,
+			Email:       record.Email,
+			Groups:      record.Groups,
+			Disabled:    record.Disabled,
+		}
+
+		if details.DisplayName == "" {
+			details.DisplayName = record.Username
+		}
+
+		if !usersIsDigest(details.Password) {
+			var digest algorithm.Digest
+
+			if digest, err = hash.Hash(details.Password); err != nil {
+				return result, fmt.Errorf("user '%s': the password could not be hashed: %w", record.Username, err)
+			}
+
+			details.Password = digest.Encode()
+		}
+
+		if ok {
+			delete(database.Users, current)
+
+			result.updated++
+		} else {
+			result.created++
+		}
+
+		database.Users[record.Username] = details
+	}
+
+	return result, nil
+}
+
+func usersExport(database *authentication.FileDatabaseModel) (records []usersRecord) {
+	for username, details := range database.Users {
+		records = append(records, usersRecord{
+			Username:    username,
+			DisplayName: details.DisplayName,
+			Email:       details.Email,
+			Password:    details.Password,
+			Groups:      details.Groups,
+			Disabled:    details.Disabled,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Username < records[j].Username
+	})
+
+	return records
+}
+
+// usersIsDigest returns true if the password is a crypt digest rather than a plaintext password.
+func usersIsDigest(password string) bool {
+	return strings.HasPrefix(password, "$")
+}
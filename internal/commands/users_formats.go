@@ -0,0 +1,572 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// usersRecord is the format independent representation of a user of the file authentication backend which is used by
+// the users import and export subcommands.
+type usersRecord struct {
+	Username    string   `json:"username"`
+	DisplayName string   `json:"displayname"`
+	Email       string   `json:"email,omitempty"`
+	Password    string   `json:"password"`
+	Groups      []string `json:"groups,omitempty"`
+	Disabled    bool     `json:"disabled,omitempty"`
+}
+
+// usersFormat returns the explicitly provided format or otherwise the format detected from the extension of the file.
+func usersFormat(format, filename string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+
+	switch format {
+	case usersFormatCSV, usersFormatJSON, usersFormatLDIF:
+		return format, nil
+	case "":
+		return "", fmt.Errorf("the format could not be detected from the file name '%s', it must be specified with the '--%s' flag", filename, cmdFlagNameFormat)
+	default:
+		return "", fmt.Errorf("the format '%s' is not supported, it must be one of %s", format, utils.StringJoinOr(usersFormats))
+	}
+}
+
+func usersDecode(format string, r io.Reader) (records []usersRecord, err error) {
+	switch format {
+	case usersFormatCSV:
+		return usersDecodeCSV(r)
+	case usersFormatJSON:
+		return usersDecodeJSON(r)
+	case usersFormatLDIF:
+		return usersDecodeLDIF(r)
+	default:
+		return nil, fmt.Errorf("the format '%s' is not supported, it must be one of %s", format, utils.StringJoinOr(usersFormats))
+	}
+}
+
+func usersEncode(format string, w io.Writer, records []usersRecord, baseDN string) (err error) {
+	switch format {
+	case usersFormatCSV:
+		return usersEncodeCSV(w, records)
+	case usersFormatJSON:
+		return usersEncodeJSON(w, records)
+	case usersFormatLDIF:
+		return usersEncodeLDIF(w, records, baseDN)
+	default:
+		return fmt.Errorf("the format '%s' is not supported, it must be one of %s", format, utils.StringJoinOr(usersFormats))
+	}
+}
+
+func usersDecodeCSV(r io.Reader) (records []usersRecord, err error) {
+	reader := csv.NewReader(r)
+
+	reader.TrimLeadingSpace = true
+
+	var rows [][]string
+
+	if rows, err = reader.ReadAll(); err != nil {
+		return nil, fmt.Errorf("error parsing the CSV: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+
+	for i, column := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	if _, ok := columns[usersColumnUsername]; !ok {
+		return nil, fmt.Errorf("error parsing the CSV: the header row must contain the '%s' column", usersColumnUsername)
+	}
+
+	value := func(row []string, column string) string {
+		if i, ok := columns[column]; ok {
+			return strings.TrimSpace(row[i])
+		}
+
+		return ""
+	}
+
+	for i, row := range rows[1:] {
+		record := usersRecord{
+			Username:    value(row, usersColumnUsername),
+			DisplayName: value(row, usersColumnDisplayName),
+			Email:       value(row, usersColumnEmail),
+			Password:    value(row, usersColumnPassword),
+		}
+
+		for _, group := range strings.Split(value(row, usersColumnGroups), usersCSVGroupsSeparator) {
+			if group = strings.TrimSpace(group); group != "" {
+				record.Groups = append(record.Groups, group)
+			}
+		}
+
+		if disabled := value(row, usersColumnDisabled); disabled != "" {
+			if record.Disabled, err = strconv.ParseBool(disabled); err != nil {
+				return nil, fmt.Errorf("error parsing the CSV: row %d: the '%s' column has the value '%s' which is not a boolean", i+2, usersColumnDisabled, disabled)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func usersEncodeCSV(w io.Writer, records []usersRecord) (err error) {
+	writer := csv.NewWriter(w)
+
+	if err = writer.Write([]string{usersColumnUsername, usersColumnDisplayName, usersColumnEmail, usersColumnPassword, usersColumnGroups, usersColumnDisabled}); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err = writer.Write([]string{record.Username, record.DisplayName, record.Email, record.Password, strings.Join(record.Groups, usersCSVGroupsSeparator), strconv.FormatBool(record.Disabled)}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func usersDecodeJSON(r io.Reader) (records []usersRecord, err error) {
+	decoder := json.NewDecoder(r)
+
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(&records); err != nil {
+		return nil, fmt.Errorf("error parsing the JSON: %w", err)
+	}
+
+	return records, nil
+}
+
+func usersEncodeJSON(w io.Writer, records []usersRecord) (err error) {
+	encoder := json.NewEncoder(w)
+
+	encoder.SetIndent("", "  ")
+
+	if records == nil {
+		records = []usersRecord{}
+	}
+
+	return encoder.Encode(records)
+}
+
+// ldifEntry is a single entry of an LDIF file. The attribute names are lowercase and exclude any options.
+type ldifEntry struct {
+	dn         string
+	attributes map[string][]string
+}
+
+func (e ldifEntry) first(attribute string) string {
+	if values := e.attributes[attribute]; len(values) != 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// usersDecodeLDIF decodes the users from an LDIF file such as one exported from an LDAP directory server. The users
+// are the entries with a uid attribute, and their groups are determined from both the memberOf attribute and the
+// group entries which reference them.
+func usersDecodeLDIF(r io.Reader) (records []usersRecord, err error) {
+	var entries []ldifEntry
+
+	if entries, err = ldifParse(r); err != nil {
+		return nil, fmt.Errorf("error parsing the LDIF: %w", err)
+	}
+
+	var (
+		groups  []ldifEntry
+		indexes = map[string]int{}
+	)
+
+	for _, entry := range entries {
+		if ldifIsGroup(entry) {
+			groups = append(groups, entry)
+
+			continue
+		}
+
+		if entry.first(ldifAttributeUID) == "" {
+			continue
+		}
+
+		record := usersRecord{
+			Username:    entry.first(ldifAttributeUID),
+			DisplayName: entry.first(ldifAttributeDisplayName),
+			Email:       entry.first(ldifAttributeMail),
+		}
+
+		if record.DisplayName == "" {
+			record.DisplayName = entry.first(ldifAttributeCN)
+		}
+
+		if record.Password, err = ldifPassword(entry.first(ldifAttributeUserPassword)); err != nil {
+			return nil, fmt.Errorf("error parsing the LDIF: entry '%s': %w", entry.dn, err)
+		}
+
+		for _, dn := range entry.attributes[ldifAttributeMemberOf] {
+			if attribute, value := ldifFirstRDN(dn); attribute == ldifAttributeCN && !utils.IsStringInSlice(value, record.Groups) {
+				record.Groups = append(record.Groups, value)
+			}
+		}
+
+		indexes[ldifNormalizeDN(entry.dn)] = len(records)
+		records = append(records, record)
+	}
+
+	for _, group := range groups {
+		name := group.first(ldifAttributeCN)
+
+		if name == "" {
+			continue
+		}
+
+		var members []int
+
+		for _, attribute := range []string{ldifAttributeMember, ldifAttributeUniqueMember} {
+			for _, dn := range group.attributes[attribute] {
+				if i, ok := indexes[ldifNormalizeDN(dn)]; ok {
+					members = append(members, i)
+				}
+			}
+		}
+
+		for _, uid := range group.attributes[ldifAttributeMemberUID] {
+			for i, record := range records {
+				if record.Username == uid {
+					members = append(members, i)
+				}
+			}
+		}
+
+		for _, i := range members {
+			if !utils.IsStringInSlice(name, records[i].Groups) {
+				records[i].Groups = append(records[i].Groups, name)
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// usersEncodeLDIF encodes the users as inetOrgPerson entries and their groups as groupOfNames entries suitable for
+// importing into an LDAP directory server. The disabled status of the users is not encoded as LDIF has no standard
+// representation for it.
+func usersEncodeLDIF(w io.Writer, records []usersRecord, baseDN string) (err error) {
+	buf := bufio.NewWriter(w)
+
+	_, _ = buf.WriteString("version: 1\n")
+
+	var (
+		groups  []string
+		members = map[string][]string{}
+	)
+
+	for _, record := range records {
+		dn := fmt.Sprintf("uid=%s,ou=users,%s", ldifEscapeDNValue(record.Username), baseDN)
+
+		_, _ = buf.WriteString("\n")
+
+		ldifWriteAttribute(buf, "dn", dn)
+		ldifWriteAttribute(buf, "objectClass", "inetOrgPerson")
+		ldifWriteAttribute(buf, "uid", record.Username)
+		ldifWriteAttribute(buf, "cn", record.DisplayName)
+		ldifWriteAttribute(buf, "sn", record.DisplayName)
+		ldifWriteAttribute(buf, "displayName", record.DisplayName)
+
+		if record.Email != "" {
+			ldifWriteAttribute(buf, "mail", record.Email)
+		}
+
+		ldifWriteAttribute(buf, "userPassword", ldifPasswordSchemeCrypt+record.Password)
+
+		for _, group := range record.Groups {
+			if _, ok := members[group]; !ok {
+				groups = append(groups, group)
+			}
+
+			members[group] = append(members[group], dn)
+		}
+	}
+
+	for _, group := range groups {
+		_, _ = buf.WriteString("\n")
+
+		ldifWriteAttribute(buf, "dn", fmt.Sprintf("cn=%s,ou=groups,%s", ldifEscapeDNValue(group), baseDN))
+		ldifWriteAttribute(buf, "objectClass", "groupOfNames")
+		ldifWriteAttribute(buf, "cn", group)
+
+		for _, member := range members[group] {
+			ldifWriteAttribute(buf, "member", member)
+		}
+	}
+
+	return buf.Flush()
+}
+
+func ldifParse(r io.Reader) (entries []ldifEntry, err error) {
+	scanner := bufio.NewScanner(r)
+
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		lines   []string
+		numbers []int
+		comment bool
+		number  int
+	)
+
+	flush := func() (err error) {
+		if len(lines) == 0 {
+			return nil
+		}
+
+		entry, start := ldifEntry{attributes: map[string][]string{}}, numbers[0]
+
+		for i, line := range lines {
+			var name, value string
+
+			if name, value, err = ldifParseLine(line); err != nil {
+				return fmt.Errorf("line %d: %w", numbers[i], err)
+			}
+
+			switch name {
+			case "dn":
+				entry.dn = value
+			case "version":
+				if entry.dn == "" && len(entries) == 0 {
+					continue
+				}
+
+				entry.attributes[name] = append(entry.attributes[name], value)
+			case "changetype":
+				if !strings.EqualFold(value, "add") {
+					return fmt.Errorf("line %d: change records with the type '%s' are not supported", numbers[i], value)
+				}
+			default:
+				entry.attributes[name] = append(entry.attributes[name], value)
+			}
+		}
+
+		lines, numbers = nil, nil
+
+		if entry.dn == "" {
+			if len(entry.attributes) == 0 {
+				return nil
+			}
+
+			return fmt.Errorf("line %d: entry does not have a dn", start)
+		}
+
+		entries = append(entries, entry)
+
+		return nil
+	}
+
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		switch {
+		case line == "":
+			if err = flush(); err != nil {
+				return nil, err
+			}
+
+			comment = false
+		case strings.HasPrefix(line, "#"):
+			comment = true
+		case strings.HasPrefix(line, " "):
+			if comment {
+				continue
+			}
+
+			if len(lines) == 0 {
+				return nil, fmt.Errorf("line %d: continuation line without a preceding line", number)
+			}
+
+			lines[len(lines)-1] += line[1:]
+		default:
+			comment = false
+
+			lines = append(lines, line)
+			numbers = append(numbers, number)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = flush(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func ldifParseLine(line string) (name, value string, err error) {
+	i := strings.Index(line, ":")
+
+	if i < 1 {
+		return "", "", fmt.Errorf("the line '%s' is not an attribute value", line)
+	}
+
+	name, value = strings.ToLower(line[:i]), line[i+1:]
+
+	if j := strings.Index(name, ";"); j != -1 {
+		name = name[:j]
+	}
+
+	switch {
+	case strings.HasPrefix(value, ":"):
+		var decoded []byte
+
+		if decoded, err = base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:])); err != nil {
+			return "", "", fmt.Errorf("the attribute '%s' has a value which is not valid base64: %w", name, err)
+		}
+
+		return name, string(decoded), nil
+	case strings.HasPrefix(value, "<"):
+		return "", "", fmt.Errorf("the attribute '%s' has a URL value which is not supported", name)
+	default:
+		return name, strings.TrimLeft(value, " "), nil
+	}
+}
+
+func ldifIsGroup(entry ldifEntry) bool {
+	for _, class := range entry.attributes[ldifAttributeObjectClass] {
+		switch strings.ToLower(class) {
+		case "groupofnames", "groupofuniquenames", "posixgroup", "group":
+			return true
+		}
+	}
+
+	return false
+}
+
+// ldifPassword returns the value of the userPassword attribute in a form suitable for the file authentication backend,
+// which is either a crypt digest or a plaintext password, as other schemes can't be converted.
+func ldifPassword(value string) (password string, err error) {
+	switch {
+	case len(value) >= len(ldifPasswordSchemeCrypt) && strings.EqualFold(value[:len(ldifPasswordSchemeCrypt)], ldifPasswordSchemeCrypt):
+		return value[len(ldifPasswordSchemeCrypt):], nil
+	case strings.HasPrefix(value, "{"):
+		if i := strings.Index(value, "}"); i != -1 {
+			return "", fmt.Errorf("the password uses the scheme '%s' which is not supported, only crypt digests and plaintext passwords are supported", value[:i+1])
+		}
+	}
+
+	return value, nil
+}
+
+// ldifFirstRDN returns the lowercase attribute name and the unescaped value of the first relative distinguished name
+// of a distinguished name.
+func ldifFirstRDN(dn string) (attribute, value string) {
+	var (
+		b       strings.Builder
+		escaped bool
+	)
+
+loop:
+	for _, r := range dn {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+
+			continue
+		case r == ',' || r == '+':
+			break loop
+		}
+
+		b.WriteRune(r)
+	}
+
+	rdn := b.String()
+
+	if i := strings.Index(rdn, "="); i != -1 {
+		return strings.ToLower(strings.TrimSpace(rdn[:i])), strings.TrimSpace(rdn[i+1:])
+	}
+
+	return "", ""
+}
+
+func ldifNormalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func ldifEscapeDNValue(value string) string {
+	var b strings.Builder
+
+	for i, r := range value {
+		switch {
+		case strings.ContainsRune(`,+"\<>;=`, r),
+			i == 0 && (r == ' ' || r == '#'),
+			i == len(value)-1 && r == ' ':
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func ldifWriteAttribute(w *bufio.Writer, name, value string) {
+	if ldifIsSafeString(value) {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", name, value)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s:: %s\n", name, base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+}
+
+// ldifIsSafeString returns true if the value can be represented in LDIF without base64 encoding per RFC2849.
+func ldifIsSafeString(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	switch value[0] {
+	case ' ', ':', '<':
+		return false
+	}
+
+	if value[len(value)-1] == ' ' {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == 0 || value[i] == '\n' || value[i] == '\r' || value[i] > 127 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-crypt/crypt"
+	"github.com/go-crypt/crypt/algorithm/shacrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+)
+
+const (
+	testUsersDigest = "$6$rounds=50000$lHrHgilQ1Iv7QtRO$5HKggvFoJTflzHQZuUjM7bdKJcuCYEoQfhRiUtStAFIrhc.EgtGTZg20UmoJphzL3w9b1vBPDngQjhOq2Sik91"
+)
+
+func TestUsersFormat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		format   string
+		filename string
+		expected string
+		err      string
+	}{
+		{"ShouldDetectCSV", "", "users.csv", usersFormatCSV, ""},
+		{"ShouldDetectJSONUpperCase", "", "users.JSON", usersFormatJSON, ""},
+		{"ShouldDetectLDIF", "", "export/users.ldif", usersFormatLDIF, ""},
+		{"ShouldPreferExplicit", usersFormatLDIF, "users.txt", usersFormatLDIF, ""},
+		{"ShouldErrNoExtension", "", "users", "", "the format could not be detected from the file name 'users', it must be specified with the '--format' flag"},
+		{"ShouldErrUnknown", "", "users.yml", "", "the format 'yml' is not supported, it must be one of 'csv', 'json', or 'ldif'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := usersFormat(tc.format, tc.filename)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestUsersEncodeDecode(t *testing.T) {
+	records := []usersRecord{
+		{Username: "harry", DisplayName: "Harry Potter", Email: "harry.potter@authelia.com", Password: testUsersDigest, Groups: []string{"admins", "dev"}},
+		{Username: "john", DisplayName: "John Smith, Jr.", Email: "john.smith@authelia.com", Password: testUsersDigest, Groups: []string{"dev"}, Disabled: true},
+		{Username: "bob", DisplayName: "Bob Dylan", Password: testUsersDigest},
+	}
+
+	for _, format := range usersFormats {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+
+			require.NoError(t, usersEncode(format, buf, records, "dc=example,dc=com"))
+
+			actual, err := usersDecode(format, buf)
+
+			require.NoError(t, err)
+
+			expected := records
+
+			if format == usersFormatLDIF {
+				expected = make([]usersRecord, len(records))
+
+				copy(expected, records)
+
+				expected[1].Disabled = false
+			}
+
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestUsersDecodeCSV(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected []usersRecord
+		err      string
+	}{
+		{
+			"ShouldDecodeReorderedColumns",
+			"Password,Username,Groups\np@ssw0rd,john,admins; dev\n",
+			[]usersRecord{{Username: "john", Password: "p@ssw0rd", Groups: []string{"admins", "dev"}}},
+			"",
+		},
+		{
+			"ShouldErrMissingUsernameColumn",
+			"name,password\njohn,p@ssw0rd\n",
+			nil,
+			"error parsing the CSV: the header row must contain the 'username' column",
+		},
+		{
+			"ShouldErrDisabled",
+			"username,disabled\njohn,false\nharry,maybe\n",
+			nil,
+			"error parsing the CSV: row 3: the 'disabled' column has the value 'maybe' which is not a boolean",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := usersDecodeCSV(strings.NewReader(tc.have))
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestUsersDecodeLDIF(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected []usersRecord
+		err      string
+	}{
+		{
+			"ShouldDecodeDirectoryExport",
+			`version: 1
+
+# john, users, example.com
+dn: uid=john,ou=users,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: john
+cn: John Smith
+mail: john.smith@authelia.com
+userPassword: {CRYPT}$6$rounds=50000$lHrHgilQ1Iv7QtRO$5HKggvFoJTflzHQZuUjM7bdKJcu
+ CYEoQfhRiUtStAFIrhc.EgtGTZg20UmoJphzL3w9b1vBPDngQjhOq2Sik91
+memberOf: cn=admins,ou=groups,dc=example,dc=com
+
+dn: uid=harry,ou=users,dc=example,dc=com
+objectClass: inetOrgPerson
+uid: harry
+displayName:: SGFycnkgUG90dGVy
+userPassword: p@ssw0rd
+
+dn: cn=dev,ou=groups,dc=example,dc=com
+objectClass: groupOfNames
+cn: dev
+member: uid=john,ou=users,dc=example,dc=com
+member: UID=Harry, OU=Users, DC=Example, DC=Com
+
+dn: cn=ops,ou=groups,dc=example,dc=com
+objectClass: posixGroup
+cn: ops
+memberUid: harry
+`,
+			[]usersRecord{
+				{Username: "john", DisplayName: "John Smith", Email: "john.smith@authelia.com", Password: testUsersDigest, Groups: []string{"admins", "dev"}},
+				{Username: "harry", DisplayName: "Harry Potter", Password: "p@ssw0rd", Groups: []string{"dev", "ops"}},
+			},
+			"",
+		},
+		{
+			"ShouldErrUnsupportedScheme",
+			"dn: uid=john,dc=example,dc=com\nuid: john\nuserPassword: {SSHA}abc\n",
+			nil,
+			"error parsing the LDIF: entry 'uid=john,dc=example,dc=com': the password uses the scheme '{SSHA}' which is not supported, only crypt digests and plaintext passwords are supported",
+		},
+		{
+			"ShouldErrChangeType",
+			"dn: uid=john,dc=example,dc=com\nchangetype: delete\n",
+			nil,
+			"error parsing the LDIF: line 2: change records with the type 'delete' are not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := usersDecodeLDIF(strings.NewReader(tc.have))
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestUsersImport(t *testing.T) {
+	hash, err := shacrypt.New(shacrypt.WithIterations(1000))
+
+	require.NoError(t, err)
+
+	database := func() *authentication.FileDatabaseModel {
+		return &authentication.FileDatabaseModel{
+			Users: map[string]authentication.FileDatabaseUserDetailsModel{
+				"John": {Password: testUsersDigest, DisplayName: "John"},
+			},
+		}
+	}
+
+	records := []usersRecord{
+		{Username: "john", DisplayName: "John Smith", Password: testUsersDigest},
+		{Username: "harry", Password: "p@ssw0rd"},
+	}
+
+	testCases := []struct {
+		name            string
+		records         []usersRecord
+		conflict        string
+		caseInsensitive bool
+		expected        usersImportResult
+		expectedUsers   []string
+		err             string
+	}{
+		{"ShouldImportCaseSensitive", records, usersConflictError, false, usersImportResult{created: 2}, []string{"John", "harry", "john"}, ""},
+		{"ShouldErrConflict", records, usersConflictError, true, usersImportResult{}, []string{"John"}, "user 'john': the user already exists, use the '--on-conflict' flag to skip or overwrite existing users"},
+		{"ShouldSkipConflict", records, usersConflictSkip, true, usersImportResult{created: 1, skipped: 1}, []string{"John", "harry"}, ""},
+		{"ShouldOverwriteConflict", records, usersConflictOverwrite, true, usersImportResult{created: 1, updated: 1}, []string{"harry", "john"}, ""},
+		{"ShouldErrDuplicate", append(records, usersRecord{Username: "HARRY", Password: "x"}), usersConflictSkip, true, usersImportResult{}, []string{"John"}, "user 'HARRY': the username is a duplicate of user 2"},
+		{"ShouldErrNoPassword", []usersRecord{{Username: "bob"}}, usersConflictError, false, usersImportResult{}, []string{"John"}, "user 'bob': the password is required"},
+		{"ShouldErrBadDigest", []usersRecord{{Username: "bob", Password: "$6$bad"}}, usersConflictError, false, usersImportResult{}, []string{"John"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := database()
+
+			result, err := usersImport(db, tc.records, tc.conflict, hash, tc.caseInsensitive)
+
+			var users []string
+
+			for _, record := range usersExport(db) {
+				users = append(users, record.Username)
+			}
+
+			assert.Equal(t, tc.expectedUsers, users)
+
+			switch {
+			case tc.err != "":
+				assert.EqualError(t, err, tc.err)
+			case tc.expected == usersImportResult{}:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+
+			if err != nil {
+				return
+			}
+
+			harry, ok := db.Users["harry"]
+
+			require.True(t, ok)
+			assert.Equal(t, "harry", harry.DisplayName)
+
+			valid, err := crypt.CheckPassword("p@ssw0rd", harry.Password)
+
+			assert.NoError(t, err)
+			assert.True(t, valid)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/kv"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/random"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -207,6 +208,23 @@ func termReadPasswordWithPrompt(prompt, flag string) (password string, err error
 	return password, nil
 }
 
+func termReadLineWithPrompt(prompt string) (line string, err error) {
+	terminal, fd, state, err := getTerminal(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	defer func(fd int, oldState *term.State) {
+		_ = term.Restore(fd, oldState)
+	}(fd, state)
+
+	if line, err = terminal.ReadLine(); err != nil {
+		return "", fmt.Errorf("failed to read the input from the terminal: %w", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
 type XEnvCLIResult int
 
 const (
@@ -229,6 +247,10 @@ func loadXEnvCLIConfigValues(cmd *cobra.Command) (configs []string, filters []co
 		return nil, nil, err
 	}
 
+	if configs, err = loadXEnvCLIConfigProfiles(cmd, configs); err != nil {
+		return nil, nil, err
+	}
+
 	if filterNames, _, err = loadXEnvCLIStringSliceValue(cmd, cmdFlagEnvNameConfigFilters, cmdFlagNameConfigExpFilters); err != nil {
 		return nil, nil, err
 	}
@@ -249,6 +271,12 @@ func loadXNormalizedPaths(paths []string, result XEnvCLIResult) ([]string, error
 	var stat os.FileInfo
 
 	for _, path := range paths {
+		if kv.IsReference(path) {
+			configs = append(configs, path)
+
+			continue
+		}
+
 		if path, err = filepath.Abs(path); err != nil {
 			return nil, fmt.Errorf("failed to determine absolute path for '%s': %w", path, err)
 		}
@@ -296,6 +324,114 @@ func loadXNormalizedPaths(paths []string, result XEnvCLIResult) ([]string, error
 	return configs, nil
 }
 
+// loadXEnvCLIConfigProfiles appends the override files of each of the configuration profiles to the configuration paths.
+// The override files for a profile named 'prod' are the 'overrides/prod.yml' and 'overrides/prod.yaml' files relative to
+// each configuration directory and the directory of each configuration file.
+func loadXEnvCLIConfigProfiles(cmd *cobra.Command, configs []string) (paths []string, err error) {
+	var profiles []string
+
+	if profiles, _, err = loadXEnvCLIStringSliceValue(cmd, cmdFlagEnvNameConfigProfiles, cmdFlagNameConfigProfiles); err != nil {
+		return nil, err
+	}
+
+	paths = configs
+
+	var (
+		dirs []string
+		stat os.FileInfo
+	)
+
+	for _, config := range configs {
+		if kv.IsReference(config) {
+			continue
+		}
+
+		dir := config
+
+		if stat, err = os.Stat(config); err != nil || !stat.IsDir() {
+			dir = filepath.Dir(config)
+		}
+
+		if !utils.IsStringInSlice(dir, dirs) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, profile := range profiles {
+		if profile = strings.TrimSpace(profile); profile == "" {
+			continue
+		}
+
+		if strings.ContainsAny(profile, `/\`) || profile == "." || profile == ".." {
+			return nil, fmt.Errorf("error occurred loading configuration: flag '--%s' is invalid: the profile '%s' must not contain a path", cmdFlagNameConfigProfiles, profile)
+		}
+
+		var found bool
+
+		for _, dir := range dirs {
+			for _, ext := range []string{extYML, extYAML, extTOML, extJSON} {
+				path := filepath.Join(dir, dirConfigOverrides, profile+ext)
+
+				if stat, err = os.Stat(path); err != nil || stat.IsDir() {
+					continue
+				}
+
+				found = true
+
+				if !utils.IsStringInSlice(path, paths) {
+					paths = append(paths, path)
+				}
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("error occurred loading configuration: flag '--%s' is invalid: the profile '%s' does not have an override file in the '%s' directory of any of the configuration paths", cmdFlagNameConfigProfiles, profile, dirConfigOverrides)
+		}
+	}
+
+	return paths, nil
+}
+
+func loadXEnvCLIConfigListMergeStrategy(cmd *cobra.Command) (lists configuration.ListMergeStrategy, err error) {
+	var value string
+
+	if value, _, err = loadXEnvCLIStringValue(cmd, cmdFlagEnvNameConfigMergeLists, cmdFlagNameConfigMergeLists); err != nil {
+		return "", err
+	}
+
+	if lists, err = configuration.NewListMergeStrategy(value); err != nil {
+		return "", fmt.Errorf("error occurred loading configuration: flag '--%s' is invalid: %w", cmdFlagNameConfigMergeLists, err)
+	}
+
+	return lists, nil
+}
+
+func loadXEnvCLIStringValue(cmd *cobra.Command, envKey, flagName string) (value string, result XEnvCLIResult, err error) {
+	if cmd.Flags().Changed(flagName) {
+		value, err = cmd.Flags().GetString(flagName)
+
+		return value, XEnvCLIResultCLIExplicit, err
+	}
+
+	var (
+		env string
+		ok  bool
+	)
+
+	if envKey != "" {
+		env, ok = os.LookupEnv(envKey)
+	}
+
+	switch {
+	case ok && env != "":
+		return env, XEnvCLIResultEnvironment, nil
+	default:
+		value, err = cmd.Flags().GetString(flagName)
+
+		return value, XEnvCLIResultCLIImplicit, err
+	}
+}
+
 func loadXEnvCLIStringSliceValue(cmd *cobra.Command, envKey, flagName string) (value []string, result XEnvCLIResult, err error) {
 	if cmd.Flags().Changed(flagName) {
 		value, err = cmd.Flags().GetStringSlice(flagName)
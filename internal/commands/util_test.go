@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -78,6 +79,78 @@ func TestLoadXEnvCLIStringSliceValue(t *testing.T) {
 	}
 }
 
+func TestLoadXEnvCLIConfigProfiles(t *testing.T) {
+	root := t.TempDir()
+
+	config := filepath.Join(root, "base.yml")
+	overrides := filepath.Join(root, "overrides")
+
+	require.NoError(t, os.WriteFile(config, []byte("log:\n  level: 'info'\n"), 0600))
+	require.NoError(t, os.Mkdir(overrides, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(overrides, "prod.yml"), []byte("log:\n  level: 'warn'\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(overrides, "ha.yaml"), []byte("session:\n  redis:\n    host: 'redis'\n"), 0600))
+
+	testCases := []struct {
+		name     string
+		env      string
+		flag     []string
+		expected []string
+		err      string
+	}{
+		{
+			"ShouldNotLoadWithoutProfiles",
+			"", nil,
+			[]string{config}, "",
+		},
+		{
+			"ShouldLoadProfilesInOrder",
+			"", []string{"prod", "ha"},
+			[]string{config, filepath.Join(overrides, "prod.yml"), filepath.Join(overrides, "ha.yaml")}, "",
+		},
+		{
+			"ShouldLoadProfilesFromEnv",
+			"ha", nil,
+			[]string{config, filepath.Join(overrides, "ha.yaml")}, "",
+		},
+		{
+			"ShouldErrorOnMissingProfile",
+			"", []string{"dev"},
+			nil, "error occurred loading configuration: flag '--config.profiles' is invalid: the profile 'dev' does not have an override file in the 'overrides' directory of any of the configuration paths",
+		},
+		{
+			"ShouldErrorOnProfilePath",
+			"", []string{"../prod"},
+			nil, "error occurred loading configuration: flag '--config.profiles' is invalid: the profile '../prod' must not contain a path",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+
+			cmd.Flags().StringSlice(cmdFlagNameConfigProfiles, nil, "")
+
+			if tc.flag != nil {
+				require.NoError(t, cmd.Flags().Set(cmdFlagNameConfigProfiles, strings.Join(tc.flag, ",")))
+			}
+
+			if tc.env != "" {
+				t.Setenv(cmdFlagEnvNameConfigProfiles, tc.env)
+			}
+
+			actual, err := loadXEnvCLIConfigProfiles(cmd, []string{config})
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestLoadXNormalizedPaths(t *testing.T) {
 	root := t.TempDir()
 
@@ -12,6 +12,14 @@ const DefaultEnvPrefix = "AUTHELIA_"
 // DefaultEnvDelimiter is the default environment delimiter.
 const DefaultEnvDelimiter = "_"
 
+const (
+	// ListMergeReplace is the ListMergeStrategy which replaces the lists of the previously loaded sources.
+	ListMergeReplace ListMergeStrategy = "replace"
+
+	// ListMergeAppend is the ListMergeStrategy which appends to the lists of the previously loaded sources.
+	ListMergeAppend ListMergeStrategy = "append"
+)
+
 const (
 	constSecretSuffix = "_FILE"
 
@@ -21,12 +29,24 @@ const (
 
 	extYML  = ".yml"
 	extYAML = ".yaml"
+	extTOML = ".toml"
+	extJSON = ".json"
+
+	yamlTagMerge  = "!!merge"
+	yamlTagMap    = "!!map"
+	yamlTagString = "!!str"
+	yamlTagNull   = "!!null"
+
+	yamlDocumentStart = "---"
+	yamlDocumentEnd   = "..."
 )
 
 const (
 	filterField     = "filter"
 	filterTemplate  = "template"
 	filterExpandEnv = "expand-env"
+
+	templateFileMaxDepth = 10
 )
 
 var (
@@ -42,7 +62,9 @@ const (
 	errFmtSecretOSError         = "secrets: error loading secret path %s into key '%s': %w"
 	errFmtSecretOSPermission    = "secrets: error loading secret path %s into key '%s': file permission error occurred: %w"
 	errFmtSecretOSNotExist      = "secrets: error loading secret path %s into key '%s': file does not exist error occurred: %w"
+	errFmtSecretReferenceError  = "secrets: error loading secret reference into key '%s': %w"
 	errFmtGenerateConfiguration = "error occurred generating configuration: %+v"
+	errFmtListMergeStrategy     = "list merge strategy '%s' is invalid: must be one of '%s' or '%s'"
 
 	errFmtDecodeHookCouldNotParse           = "could not decode '%s' to a %s%s: %w"
 	errFmtDecodeHookCouldNotParseBasic      = "could not decode to a %s%s: %w"
@@ -56,6 +78,14 @@ const (
 
 	errFmtAutoMapKey         = "configuration key '%s' is deprecated in %s and has been replaced by '%s': " + errFmtSuffixAutoRemappedKey
 	errFmtAutoMapKeyExisting = "configuration key '%s' is deprecated in %s and has been replaced by '%s': this has not been automatically mapped for you because the replacement key also exists and you will need to adjust your configuration to remove this message"
+
+	errFmtMigrateKey               = "configuration key '%s' which is deprecated in %s was migrated to '%s'"
+	errFmtMigrateKeyExisting       = "configuration key '%s' which is deprecated in %s was removed because the replacement key '%s' also exists"
+	errFmtMigrateKeyManual         = "configuration key '%s' which is deprecated in %s has been replaced by '%s' and must be migrated manually"
+	errFmtMigrateKeyNotMapping     = "configuration key '%s' which is deprecated in %s could not be migrated to '%s' because '%s' is not a mapping"
+	errFmtMigrateKeyMapFunc        = "configuration key '%s' which is deprecated in %s could not be migrated to '%s': %w"
+	errFmtMigrateMultiKeys         = "configuration keys %s which are deprecated in %s were migrated to '%s' with the value '%s'"
+	errFmtMigrateMultiKeysExisting = "configuration keys %s which are deprecated in %s could not be migrated to '%s' because the replacement key also exists"
 )
 
 const (
@@ -80,3 +110,8 @@ var (
 	secretExclusionPrefix = []string{"identity_providers.oidc.lifespans."}
 	secretExclusionExact  = []string{"server.tls.key", "authentication_backend.disable_reset_password", "tls_key"}
 )
+
+const (
+	jsonSchemaFileConfiguration = "configuration"
+	jsonSchemaVersionLatest     = "latest"
+)
@@ -0,0 +1,102 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetFileKeyPositions returns the positions of every key within the files of the provided sources formatted as
+// '<path>:<line>:<column>'. The positions are relative to the content of the files after the filters are applied. The
+// positions are only determined for YAML and JSON files.
+func GetFileKeyPositions(sources ...Source) (positions map[string][]string, err error) {
+	var (
+		source *FileSource
+		files  []*File
+		ok     bool
+	)
+
+	positions = map[string][]string{}
+
+	for _, s := range sources {
+		if source, ok = s.(*FileSource); !ok {
+			continue
+		}
+
+		if files, err = source.ReadFiles(); err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			if strings.EqualFold(filepath.Ext(file.Path), extTOML) {
+				continue
+			}
+
+			if err = getFileKeyPositions(file, positions); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return positions, nil
+}
+
+func getFileKeyPositions(file *File, positions map[string][]string) (err error) {
+	var root yaml.Node
+
+	if err = yaml.Unmarshal(file.Data, &root); err != nil {
+		return fmt.Errorf("error occurred parsing file '%s': %w", file.Path, err)
+	}
+
+	for _, node := range root.Content {
+		getYAMLNodeKeyPositions(file.Path, "", node, positions)
+	}
+
+	return nil
+}
+
+func getYAMLNodeKeyPositions(path, prefix string, node *yaml.Node, positions map[string][]string) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		getYAMLNodeKeyPositions(path, prefix, node.Alias, positions)
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			getYAMLNodeKeyPositions(path, prefix+"[]", item, positions)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+
+			// Merge keys are not keys themselves, the keys of the merged mappings belong to this mapping.
+			if key.Tag == yamlTagMerge {
+				getYAMLMergeKeyPositions(path, prefix, value, positions)
+
+				continue
+			}
+
+			full := key.Value
+
+			if prefix != "" {
+				full = prefix + constDelimiter + key.Value
+			}
+
+			positions[full] = append(positions[full], fmt.Sprintf("%s:%d:%d", path, key.Line, key.Column))
+
+			getYAMLNodeKeyPositions(path, full, value, positions)
+		}
+	}
+}
+
+func getYAMLMergeKeyPositions(path, prefix string, node *yaml.Node, positions map[string][]string) {
+	if node.Kind != yaml.SequenceNode {
+		getYAMLNodeKeyPositions(path, prefix, node, positions)
+
+		return
+	}
+
+	for _, item := range node.Content {
+		getYAMLNodeKeyPositions(path, prefix, item, positions)
+	}
+}
@@ -0,0 +1,49 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetFileKeyPositions(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "config.yml")
+
+	data := []byte(`---
+defaults: &defaults
+  policy: 'one_factor'
+totp:
+  skewy: 1
+access_control:
+  rules:
+    - domain: 'example.com'
+      <<: *defaults
+    - domain: 'app.example.com'
+      policy: 'two_factor'
+...
+`)
+
+	require.NoError(t, os.WriteFile(path, data, 0600))
+
+	positions, err := GetFileKeyPositions(NewFileSource(path), NewEnvironmentSource(DefaultEnvPrefix, DefaultEnvDelimiter))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{path + ":2:1"}, positions["defaults"])
+	assert.Equal(t, []string{path + ":5:3"}, positions["totp.skewy"])
+	assert.Equal(t, []string{path + ":8:7", path + ":10:7"}, positions["access_control.rules[].domain"])
+	assert.Equal(t, []string{path + ":3:3", path + ":11:7"}, positions["access_control.rules[].policy"])
+	assert.NotContains(t, positions, "access_control.rules[].<<")
+}
+
+func TestGetFileKeyPositionsShouldErrorOnInvalidYAML(t *testing.T) {
+	positions := map[string][]string{}
+
+	err := getFileKeyPositions(&File{Path: "config.yml", Data: []byte("totp:\n  skew: [1\n")}, positions)
+
+	assert.EqualError(t, err, "error occurred parsing file 'config.yml': yaml: line 1: did not find expected ',' or ']'")
+}
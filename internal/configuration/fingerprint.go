@@ -0,0 +1,181 @@
+package configuration
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// Fingerprint returns a hex encoded SHA256 checksum of the effective configuration. The checksum is deterministic for
+// equal configurations regardless of the order the configuration was loaded in, which allows instances to compare their
+// configurations without exchanging them.
+func Fingerprint(config *schema.Configuration) string {
+	h := sha256.New()
+
+	fingerprintValue(h, reflect.ValueOf(config))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var (
+	typeAddress                 = reflect.TypeOf(schema.Address{})
+	typeX509CertificateChain    = reflect.TypeOf(schema.X509CertificateChain{})
+	typeRefreshIntervalDuration = reflect.TypeOf(schema.RefreshIntervalDuration{})
+	typeX509Certificate         = reflect.TypeOf(x509.Certificate{})
+)
+
+//nolint:gocyclo // Complexity is required to handle all of the kinds.
+func fingerprintValue(h hash.Hash, value reflect.Value) {
+	switch value.Kind() {
+	case reflect.Invalid:
+		fingerprintWrite(h, "nil")
+
+		return
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			fingerprintWrite(h, "nil")
+
+			return
+		}
+
+		fingerprintValue(h, value.Elem())
+
+		return
+	}
+
+	if fingerprintSpecial(h, value) {
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		t := value.Type()
+
+		fingerprintWrite(h, "{")
+
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+
+			fingerprintWrite(h, t.Field(i).Name, ":")
+			fingerprintValue(h, value.Field(i))
+			fingerprintWrite(h, ",")
+		}
+
+		fingerprintWrite(h, "}")
+	case reflect.Slice, reflect.Array:
+		fingerprintWrite(h, "[")
+
+		for i := 0; i < value.Len(); i++ {
+			fingerprintValue(h, value.Index(i))
+			fingerprintWrite(h, ",")
+		}
+
+		fingerprintWrite(h, "]")
+	case reflect.Map:
+		keys := value.MapKeys()
+
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		fingerprintWrite(h, "{")
+
+		for _, key := range keys {
+			fingerprintValue(h, key)
+			fingerprintWrite(h, ":")
+			fingerprintValue(h, value.MapIndex(key))
+			fingerprintWrite(h, ",")
+		}
+
+		fingerprintWrite(h, "}")
+	case reflect.String:
+		fingerprintWrite(h, strconv.Quote(value.String()))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		fingerprintWrite(h, value.Kind().String())
+	default:
+		fingerprintWrite(h, fmt.Sprint(value.Interface()))
+	}
+}
+
+// fingerprintSpecial handles the types which have no exported fields, or which have a more suitable representation
+// than their exported fields, returning true if the value was handled.
+func fingerprintSpecial(h hash.Hash, value reflect.Value) bool {
+	switch value.Type() {
+	case typeAddress:
+		address := value.Interface().(schema.Address)
+
+		fingerprintWrite(h, strconv.Quote(address.String()))
+
+		return true
+	case typeX509CertificateChain:
+		chain := value.Interface().(schema.X509CertificateChain)
+
+		fingerprintValue(h, reflect.ValueOf(chain.CertificatesRaw()))
+
+		return true
+	case typeRefreshIntervalDuration:
+		duration := value.Interface().(schema.RefreshIntervalDuration)
+
+		fingerprintWrite(h, fmt.Sprintf("%v:%t:%t", duration.Value(), duration.Always(), duration.Never()))
+
+		return true
+	case typeX509Certificate:
+		fingerprintValue(h, reflect.ValueOf(value.Interface().(x509.Certificate).Raw))
+
+		return true
+	}
+
+	if value.Kind() != reflect.Struct || !value.CanInterface() {
+		return false
+	}
+
+	pointer := reflect.New(value.Type())
+
+	pointer.Elem().Set(value)
+
+	if marshaler, ok := pointer.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			fingerprintWrite(h, strconv.Quote(string(text)))
+
+			return true
+		}
+	}
+
+	if fingerprintHasExportedFields(value.Type()) {
+		return false
+	}
+
+	if stringer, ok := pointer.Interface().(fmt.Stringer); ok {
+		fingerprintWrite(h, strconv.Quote(stringer.String()))
+	} else {
+		fingerprintWrite(h, value.Type().String())
+	}
+
+	return true
+}
+
+func fingerprintHasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fingerprintWrite(h hash.Hash, values ...string) {
+	for _, value := range values {
+		_, _ = h.Write([]byte(value))
+	}
+}
@@ -0,0 +1,107 @@
+package configuration
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := func() *schema.Configuration {
+		return &schema.Configuration{
+			Theme: "light",
+			AuthenticationBackend: schema.AuthenticationBackend{
+				RefreshInterval: schema.NewRefreshIntervalDuration(time.Minute),
+			},
+			AccessControl: schema.AccessControl{
+				DefaultPolicy: "deny",
+				Rules: []schema.AccessControlRule{
+					{Domains: []string{"app.example.com"}, Policy: "one_factor"},
+				},
+			},
+			Server: schema.Server{
+				Address: &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "0.0.0.0", 9091)},
+			},
+			Storage: schema.Storage{
+				PostgreSQL: &schema.StoragePostgreSQL{
+					StorageSQL: schema.StorageSQL{
+						Address: &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "postgres", 5432)},
+					},
+				},
+			},
+			IdentityProviders: schema.IdentityProviders{
+				OIDC: &schema.IdentityProvidersOpenIDConnect{
+					AuthorizationPolicies: map[string]schema.IdentityProvidersOpenIDConnectPolicy{
+						"a": {DefaultPolicy: "one_factor"},
+						"b": {DefaultPolicy: "two_factor"},
+						"c": {DefaultPolicy: "deny"},
+					},
+				},
+			},
+		}
+	}
+
+	expected := Fingerprint(base())
+
+	assert.Regexp(t, regexp.MustCompile(`^[a-f0-9]{64}$`), expected)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, expected, Fingerprint(base()))
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(config *schema.Configuration)
+	}{
+		{
+			"ShouldDifferAccessControlRule",
+			func(config *schema.Configuration) {
+				config.AccessControl.Rules[0].Policy = "two_factor"
+			},
+		},
+		{
+			"ShouldDifferAccessControlRuleOrder",
+			func(config *schema.Configuration) {
+				config.AccessControl.Rules = append([]schema.AccessControlRule{{Domains: []string{"*.example.com"}, Policy: "bypass"}}, config.AccessControl.Rules...)
+			},
+		},
+		{
+			"ShouldDifferAddress",
+			func(config *schema.Configuration) {
+				config.Storage.PostgreSQL.Address = &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues("tcp", "postgres", 5433)}
+			},
+		},
+		{
+			"ShouldDifferRefreshInterval",
+			func(config *schema.Configuration) {
+				config.AuthenticationBackend.RefreshInterval = schema.NewRefreshIntervalDurationAlways()
+			},
+		},
+		{
+			"ShouldDifferMapValue",
+			func(config *schema.Configuration) {
+				config.IdentityProviders.OIDC.AuthorizationPolicies["b"] = schema.IdentityProvidersOpenIDConnectPolicy{DefaultPolicy: "one_factor"}
+			},
+		},
+		{
+			"ShouldDifferNil",
+			func(config *schema.Configuration) {
+				config.IdentityProviders.OIDC = nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := base()
+
+			tc.modify(config)
+
+			assert.NotEqual(t, expected, Fingerprint(config))
+		})
+	}
+}
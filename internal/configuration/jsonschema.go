@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/authelia/jsonschema"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewJSONSchema returns the JSON Schema for the configuration of the provided version. The descriptions are only
+// available in the published schemas as they're extracted from the source code comments.
+func NewJSONSchema(version string) (s *jsonschema.Schema) {
+	r := &jsonschema.Reflector{
+		RequiredFromJSONSchemaTags: true,
+		Mapper:                     JSONSchemaKoanfMapper,
+	}
+
+	s = r.Reflect(&schema.Configuration{})
+
+	if v, err := model.NewSemanticVersion(version); err == nil {
+		s.ID = jsonschema.ID(fmt.Sprintf(model.FormatJSONSchemaIdentifier, fmt.Sprintf("v%d.%d", v.Major, v.Minor), jsonSchemaFileConfiguration))
+	} else {
+		s.ID = jsonschema.ID(fmt.Sprintf(model.FormatJSONSchemaIdentifier, jsonSchemaVersionLatest, jsonSchemaFileConfiguration))
+	}
+
+	return s
+}
+
+// JSONSchemaKoanfMapper maps the types which are decoded by the koanf decode hooks to their JSON Schema.
+func JSONSchemaKoanfMapper(t reflect.Type) *jsonschema.Schema {
+	switch t.String() {
+	case "regexp.Regexp", "*regexp.Regexp":
+		return &jsonschema.Schema{
+			Type:   jsonschema.TypeString,
+			Format: jsonschema.FormatStringRegex,
+		}
+	case "time.Duration", "*time.Duration":
+		return &jsonschema.Schema{
+			OneOf: []*jsonschema.Schema{
+				{
+					Type:    jsonschema.TypeString,
+					Pattern: `^\d+\s*(y|M|w|d|h|m|s|ms|((year|month|week|day|hour|minute|second|millisecond)s?))(\s*\d+\s*(y|M|w|d|h|m|s|ms|((year|month|week|day|hour|minute|second|millisecond)s?)))*$`,
+				},
+				{
+					Type:        jsonschema.TypeInteger,
+					Description: "The duration in seconds",
+				},
+			},
+		}
+	case "schema.CryptographicKey":
+		return &jsonschema.Schema{
+			Type:    jsonschema.TypeString,
+			Pattern: `^-{5}BEGIN (((RSA|EC) )?(PRIVATE|PUBLIC) KEY|CERTIFICATE)-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (((RSA|EC) )?(PRIVATE|PUBLIC) KEY|CERTIFICATE)-{5}\n?$`,
+		}
+	case "schema.CryptographicPrivateKey":
+		return &jsonschema.Schema{
+			Type:    jsonschema.TypeString,
+			Pattern: `^-{5}BEGIN ((RSA|EC) )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END ((RSA|EC) )?PRIVATE KEY-{5}\n?$`,
+		}
+	case "rsa.PrivateKey", "*rsa.PrivateKey":
+		return &jsonschema.Schema{
+			Type:    jsonschema.TypeString,
+			Pattern: `^-{5}(BEGIN (RSA )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (RSA )?PRIVATE KEY-{5}\n?)+$`,
+		}
+	case "ecdsa.PrivateKey", "*.ecdsa.PrivateKey":
+		return &jsonschema.Schema{
+			Type:    jsonschema.TypeString,
+			Pattern: `^-{5}(BEGIN ((EC )?PRIVATE KEY-{5}\n([a-zA-Z0-9\/+]{1,64}\n)+([a-zA-Z0-9\/+]{1,64}[=]{0,2})\n-{5}END (EC )?PRIVATE KEY-{5}\n?)+$`,
+		}
+	case "mail.Address", "*mail.Address":
+		return &jsonschema.Schema{
+			Type:   jsonschema.TypeString,
+			Format: jsonschema.FormatStringEmail,
+		}
+	case "schema.CSPTemplate":
+		return &jsonschema.Schema{
+			Type: jsonschema.TypeString,
+		}
+	}
+
+	return nil
+}
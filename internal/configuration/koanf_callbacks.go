@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/secrets"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -41,6 +42,17 @@ func koanfEnvironmentSecretsCallback(keyMap map[string]string, validator *schema
 			return "", nil
 		}
 
+		if secrets.IsReference(value) {
+			v, err := secrets.Resolve(value)
+			if err != nil {
+				validator.Push(fmt.Errorf(errFmtSecretReferenceError, k, err))
+
+				return "", nil
+			}
+
+			return k, v
+		}
+
 		switch v, err := loadSecret(value); err {
 		case nil:
 			return k, v
@@ -13,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/sops"
 	"github.com/authelia/authelia/v4/internal/templates"
 )
 
@@ -36,6 +37,12 @@ func (f *FilteredFile) ReadBytes() (data []byte, err error) {
 		return nil, err
 	}
 
+	if sops.IsEncrypted(data) {
+		if data, err = sops.Decrypt(data); err != nil {
+			return nil, fmt.Errorf("error decrypting file '%s': %w", f.path, err)
+		}
+	}
+
 	if len(data) == 0 || len(f.filters) == 0 {
 		return data, nil
 	}
@@ -80,8 +87,9 @@ func (f *ExpandEnvBytesFilter) Filter(in []byte) (out []byte, err error) {
 }
 
 type TemplateBytesFilter struct {
-	t   *template.Template
-	log *logrus.Entry
+	t     *template.Template
+	log   *logrus.Entry
+	depth int
 }
 
 func (f *TemplateBytesFilter) Name() (name string) {
@@ -110,6 +118,47 @@ func (f *TemplateBytesFilter) Filter(in []byte) (out []byte, err error) {
 	return out, nil
 }
 
+// funcs returns the functions available to the configuration templates.
+func (f *TemplateBytesFilter) funcs() template.FuncMap {
+	return template.FuncMap{
+		"fileTemplate": f.fileTemplate,
+	}
+}
+
+// fileTemplate renders the content of a file as a template with the same functions, allowing configuration files to
+// include other files which are also templates.
+func (f *TemplateBytesFilter) fileTemplate(path string) (out string, err error) {
+	if f.depth >= templateFileMaxDepth {
+		return "", fmt.Errorf("error rendering file template '%s': the maximum depth of %d nested file templates was exceeded", path, templateFileMaxDepth)
+	}
+
+	var data []byte
+
+	if data, err = os.ReadFile(path); err != nil {
+		return "", fmt.Errorf("error rendering file template '%s': %w", path, err)
+	}
+
+	f.depth++
+
+	defer func() {
+		f.depth--
+	}()
+
+	var t *template.Template
+
+	if t, err = template.New(path).Funcs(templates.FuncMap()).Funcs(f.funcs()).Parse(string(data)); err != nil {
+		return "", fmt.Errorf("error rendering file template '%s': %w", path, err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = t.Execute(buf, nil); err != nil {
+		return "", fmt.Errorf("error rendering file template '%s': %w", path, err)
+	}
+
+	return buf.String(), nil
+}
+
 // NewFileFiltersDefault returns the default list of BytesFilter.
 func NewFileFiltersDefault() []BytesFilter {
 	return []BytesFilter{
@@ -155,8 +204,11 @@ func NewExpandEnvFileFilter() BytesFilter {
 
 // NewTemplateFileFilter returns a new BytesFilter which passes the bytes through text/template.
 func NewTemplateFileFilter() BytesFilter {
-	return &TemplateBytesFilter{
+	filter := &TemplateBytesFilter{
 		log: logging.Logger().WithFields(map[string]any{filterField: filterTemplate}),
-		t:   template.New("config.template").Funcs(templates.FuncMap()),
 	}
+
+	filter.t = template.New("config.template").Funcs(templates.FuncMap()).Funcs(filter.funcs())
+
+	return filter
 }
@@ -1,9 +1,13 @@
 package configuration
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewFileFilters(t *testing.T) {
@@ -78,3 +82,27 @@ func TestNewFileFilters(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateFileFilterFileTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	include := filepath.Join(dir, "access-control.yml")
+	loop := filepath.Join(dir, "loop.yml")
+
+	require.NoError(t, os.WriteFile(include, []byte("default_policy: '{{ default \"deny\" (env \"X_AUTHELIA_TEST_POLICY\") }}'\n"), 0600))
+	require.NoError(t, os.WriteFile(loop, []byte(fmt.Sprintf("{{ fileTemplate %q }}", loop)), 0600))
+
+	t.Setenv("X_AUTHELIA_TEST_POLICY", "one_factor")
+
+	filter := NewTemplateFileFilter()
+
+	out, err := filter.Filter([]byte(fmt.Sprintf("access_control:\n  {{- fileTemplate %q | trim | nindent 2 }}\n", include)))
+	require.NoError(t, err)
+	assert.Equal(t, "access_control:\n  default_policy: 'one_factor'\n", string(out))
+
+	_, err = filter.Filter([]byte(fmt.Sprintf("{{ fileTemplate %q }}", loop)))
+	assert.ErrorContains(t, err, "the maximum depth of 10 nested file templates was exceeded")
+
+	_, err = filter.Filter([]byte(`{{ fileTemplate "/path/does/not/exist.yml" }}`))
+	assert.ErrorContains(t, err, "error rendering file template '/path/does/not/exist.yml': open /path/does/not/exist.yml: no such file or directory")
+}
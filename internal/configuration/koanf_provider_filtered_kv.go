@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/kv"
+	"github.com/authelia/authelia/v4/internal/sops"
+)
+
+// FilteredKV implements a koanf.Provider.
+type FilteredKV struct {
+	reference string
+	filters   []BytesFilter
+}
+
+// FilteredKVProvider returns a koanf.Provider which provides filtered output from the value of a key in a key value
+// store.
+func FilteredKVProvider(reference string, filters ...BytesFilter) *FilteredKV {
+	return &FilteredKV{
+		reference: reference,
+		filters:   filters,
+	}
+}
+
+// ReadBytes reads the value of the key from the key value store, passes it through any configured filters, and
+// returns the bytes.
+func (f *FilteredKV) ReadBytes() (data []byte, err error) {
+	var (
+		client kv.Client
+		key    string
+	)
+
+	if client, key, err = kv.NewClientFromReference(f.reference); err != nil {
+		return nil, err
+	}
+
+	if data, _, err = client.Get(context.Background(), key); err != nil {
+		return nil, fmt.Errorf("error loading key value store reference '%s': %w", f.reference, err)
+	}
+
+	if sops.IsEncrypted(data) {
+		if data, err = sops.Decrypt(data); err != nil {
+			return nil, fmt.Errorf("error decrypting key value store reference '%s': %w", f.reference, err)
+		}
+	}
+
+	if len(data) == 0 || len(f.filters) == 0 {
+		return data, nil
+	}
+
+	for _, filter := range f.filters {
+		if data, err = filter.Filter(data); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+// Read is not supported by the filtered key value store koanf.Provider.
+func (f *FilteredKV) Read() (map[string]any, error) {
+	return nil, errors.New("filtered key value store provider does not support this method")
+}
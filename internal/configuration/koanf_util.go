@@ -2,8 +2,13 @@ package configuration
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/v2"
 
@@ -11,6 +16,19 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
+// koanfParser returns the koanf.Parser for the configuration format detected from the extension of the path, paths
+// without a TOML or JSON extension are parsed as YAML.
+func koanfParser(path string) koanf.Parser {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case extTOML:
+		return toml.Parser()
+	case extJSON:
+		return json.Parser()
+	default:
+		return yaml.Parser()
+	}
+}
+
 func koanfGetKeys(ko *koanf.Koanf) (keys []string) {
 	keys = ko.Keys()
 
@@ -193,3 +211,48 @@ func koanfRemapKeysMultiMapped(keys map[string]any, val *schema.StructValidator,
 		dm.MapFunc(dm, keys, val)
 	}
 }
+
+// koanfMerge merges the src koanf.Koanf into the dst koanf.Koanf using the ListMergeStrategy.
+func koanfMerge(dst, src *koanf.Koanf, lists ListMergeStrategy) (err error) {
+	if lists != ListMergeAppend {
+		return dst.Merge(src)
+	}
+
+	return dst.Load(confmap.Provider(src.Raw(), ""), nil, koanfLoadOptions(lists)...)
+}
+
+// koanfLoadOptions returns the koanf.Option slice which loads a koanf.Provider using the ListMergeStrategy.
+func koanfLoadOptions(lists ListMergeStrategy) (opts []koanf.Option) {
+	if lists == ListMergeAppend {
+		opts = append(opts, koanf.WithMergeFunc(koanfMergeAppendLists))
+	}
+
+	return opts
+}
+
+// koanfMergeAppendLists merges the src map into the dest map in the same way as the default koanf merge function with
+// the exception that the lists which exist in both maps are appended instead of replaced.
+func koanfMergeAppendLists(src, dest map[string]any) (err error) {
+	for key, value := range src {
+		switch v := value.(type) {
+		case map[string]any:
+			if d, ok := dest[key].(map[string]any); ok {
+				if err = koanfMergeAppendLists(v, d); err != nil {
+					return err
+				}
+
+				continue
+			}
+		case []any:
+			if d, ok := dest[key].([]any); ok {
+				dest[key] = append(slices.Clone(d), v...)
+
+				continue
+			}
+		}
+
+		dest[key] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,367 @@
+package configuration
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// Migrate rewrites the deprecated keys within the content of a YAML configuration file to their replacements using the
+// same deprecations as the automatic mapping performed when the configuration is loaded. The warnings pushed to the
+// validator describe each of the changes, and the errors describe each of the deprecated keys which could not be
+// migrated and must be migrated manually. If nothing was migrated the content is returned unmodified.
+func Migrate(data []byte, val *schema.StructValidator) (migrated []byte, err error) {
+	header, body, footer := splitYAMLDocumentMarkers(data)
+
+	var root yaml.Node
+
+	if err = yaml.Unmarshal(body, &root); err != nil {
+		return nil, fmt.Errorf("error occurred parsing the configuration: %w", err)
+	}
+
+	if len(root.Content) == 0 {
+		return data, nil
+	}
+
+	document := root.Content[0]
+
+	if document.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("error occurred parsing the configuration: the configuration is not a mapping")
+	}
+
+	keys := make([]string, 0, len(deprecations))
+
+	for key := range deprecations {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var changed bool
+
+	for _, key := range keys {
+		if migrateDeprecation(document, deprecations[key], val) {
+			changed = true
+		}
+	}
+
+	for _, dm := range deprecationsMKM {
+		if migrateMultiKeyMappedDeprecation(document, dm, val) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return data, nil
+	}
+
+	buf := bytes.NewBuffer(header)
+
+	encoder := yaml.NewEncoder(buf)
+
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(&root); err != nil {
+		return nil, fmt.Errorf("error occurred encoding the migrated configuration: %w", err)
+	}
+
+	if err = encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error occurred encoding the migrated configuration: %w", err)
+	}
+
+	buf.Write(footer)
+
+	return buf.Bytes(), nil
+}
+
+func migrateDeprecation(document *yaml.Node, d Deprecation, val *schema.StructValidator) (changed bool) {
+	prefix, key, ok := strings.Cut(d.Key, "[].")
+	if !ok {
+		return migrateDeprecationKey(document, d, d.Key, d.NewKey, strings.Split(d.Key, constDelimiter), strings.Split(d.NewKey, constDelimiter), val)
+	}
+
+	_, newKey, _ := strings.Cut(d.NewKey, "[].")
+
+	parent, i := yamlMappingLookup(document, strings.Split(prefix, constDelimiter))
+	if parent == nil || parent.Content[i+1].Kind != yaml.SequenceNode {
+		return false
+	}
+
+	for n, item := range parent.Content[i+1].Content {
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if migrateDeprecationKey(item, d, fmt.Sprintf("%s[%d].%s", prefix, n, key), fmt.Sprintf("%s[%d].%s", prefix, n, newKey), strings.Split(key, constDelimiter), strings.Split(newKey, constDelimiter), val) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// migrateDeprecationKey migrates the key at the path to the new path relative to the node. The key is renamed in place
+// if the parent of the key is the same, otherwise it's moved to the end of the parent of the new key.
+func migrateDeprecationKey(node *yaml.Node, d Deprecation, name, newName string, path, newPath []string, val *schema.StructValidator) (changed bool) {
+	parent, i := yamlMappingLookup(node, path)
+	if parent == nil {
+		return false
+	}
+
+	if !d.AutoMap {
+		val.Push(fmt.Errorf(errFmtMigrateKeyManual, name, d.Version, newName))
+
+		return false
+	}
+
+	if p, _ := yamlMappingLookup(node, newPath); p != nil {
+		yamlMappingRemove(node, path)
+
+		val.PushWarning(fmt.Errorf(errFmtMigrateKeyExisting, name, d.Version, newName))
+
+		return true
+	}
+
+	key, value := parent.Content[i], parent.Content[i+1]
+
+	if d.MapFunc != nil {
+		var (
+			v      any
+			mapped = &yaml.Node{}
+		)
+
+		if err := value.Decode(&v); err != nil {
+			val.Push(fmt.Errorf(errFmtMigrateKeyMapFunc, name, d.Version, newName, err))
+
+			return false
+		}
+
+		if err := mapped.Encode(d.MapFunc(v)); err != nil {
+			val.Push(fmt.Errorf(errFmtMigrateKeyMapFunc, name, d.Version, newName, err))
+
+			return false
+		}
+
+		value = mapped
+	}
+
+	last := len(newPath) - 1
+
+	if slices.Equal(path[:len(path)-1], newPath[:last]) {
+		key.Value, parent.Content[i+1] = newPath[last], value
+	} else {
+		target := yamlMappingEnsure(node, newPath[:last])
+		if target == nil {
+			val.Push(fmt.Errorf(errFmtMigrateKeyNotMapping, name, d.Version, newName, strings.Join(newPath[:last], constDelimiter)))
+
+			return false
+		}
+
+		target.Content = append(target.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlTagString, Value: newPath[last], HeadComment: key.HeadComment}, value)
+
+		yamlMappingRemove(node, path)
+	}
+
+	val.PushWarning(fmt.Errorf(errFmtMigrateKey, name, d.Version, newName))
+
+	return true
+}
+
+func migrateMultiKeyMappedDeprecation(document *yaml.Node, dm MultiKeyMappedDeprecation, val *schema.StructValidator) (changed bool) {
+	keys := map[string]any{}
+
+	for _, key := range dm.Keys {
+		parent, i := yamlMappingLookup(document, strings.Split(key, constDelimiter))
+		if parent == nil {
+			continue
+		}
+
+		var value any
+
+		if err := parent.Content[i+1].Decode(&value); err == nil {
+			keys[key] = value
+		}
+	}
+
+	if len(keys) == 0 {
+		return false
+	}
+
+	newPath := strings.Split(dm.NewKey, constDelimiter)
+
+	if p, _ := yamlMappingLookup(document, newPath); p != nil {
+		val.Push(fmt.Errorf(errFmtMigrateMultiKeysExisting, utils.StringJoinAnd(dm.Keys), dm.Version, dm.NewKey))
+
+		return false
+	}
+
+	// The mapping functions push the warnings logged during the automatic mapping which are not relevant to the
+	// migration, so only the errors are retained.
+	mval := schema.NewStructValidator()
+
+	dm.MapFunc(dm, keys, mval)
+
+	for _, err := range mval.Errors() {
+		val.Push(err)
+	}
+
+	value, ok := keys[dm.NewKey].(string)
+	if !ok {
+		return false
+	}
+
+	last := len(newPath) - 1
+
+	target := yamlMappingEnsure(document, newPath[:last])
+	if target == nil {
+		val.Push(fmt.Errorf(errFmtMigrateKeyNotMapping, utils.StringJoinAnd(dm.Keys), dm.Version, dm.NewKey, strings.Join(newPath[:last], constDelimiter)))
+
+		return false
+	}
+
+	target.Content = append(target.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: yamlTagString, Value: newPath[last]},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: yamlTagString, Value: value, Style: yaml.SingleQuotedStyle},
+	)
+
+	for _, key := range dm.Keys {
+		yamlMappingRemove(document, strings.Split(key, constDelimiter))
+	}
+
+	val.PushWarning(fmt.Errorf(errFmtMigrateMultiKeys, utils.StringJoinAnd(dm.Keys), dm.Version, dm.NewKey, value))
+
+	return true
+}
+
+// yamlMappingLookup returns the mapping which contains the key at the path and the index of the key within the content
+// of the mapping, or nil if the path does not exist.
+func yamlMappingLookup(node *yaml.Node, path []string) (parent *yaml.Node, index int) {
+	for n, key := range path {
+		if node.Kind != yaml.MappingNode {
+			return nil, -1
+		}
+
+		if index = yamlMappingIndex(node, key); index == -1 {
+			return nil, -1
+		}
+
+		if n == len(path)-1 {
+			return node, index
+		}
+
+		node = node.Content[index+1]
+	}
+
+	return nil, -1
+}
+
+// yamlMappingEnsure returns the mapping at the path creating any of the mappings which do not exist, or nil if any of
+// the values along the path are not a mapping.
+func yamlMappingEnsure(node *yaml.Node, path []string) *yaml.Node {
+	for _, key := range path {
+		index := yamlMappingIndex(node, key)
+
+		if index == -1 {
+			child := &yaml.Node{Kind: yaml.MappingNode, Tag: yamlTagMap}
+
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: yamlTagString, Value: key}, child)
+
+			node = child
+
+			continue
+		}
+
+		child := node.Content[index+1]
+
+		switch {
+		case child.Kind == yaml.MappingNode:
+		case child.Kind == yaml.ScalarNode && child.Tag == yamlTagNull:
+			child.Kind, child.Tag, child.Value = yaml.MappingNode, yamlTagMap, ""
+		default:
+			return nil
+		}
+
+		node = child
+	}
+
+	return node
+}
+
+// yamlMappingRemove removes the key at the path and any mappings along the path which are empty as a result.
+func yamlMappingRemove(node *yaml.Node, path []string) (removed bool) {
+	index := yamlMappingIndex(node, path[0])
+	if index == -1 {
+		return false
+	}
+
+	if len(path) != 1 {
+		child := node.Content[index+1]
+
+		if child.Kind != yaml.MappingNode || !yamlMappingRemove(child, path[1:]) {
+			return false
+		}
+
+		if len(child.Content) != 0 {
+			return true
+		}
+	}
+
+	node.Content = append(node.Content[:index], node.Content[index+2:]...)
+
+	return true
+}
+
+func yamlMappingIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splitYAMLDocumentMarkers splits the leading lines up to and including the document start marker, and the document
+// end marker from the content so they're retained verbatim as the encoder does not output them.
+func splitYAMLDocumentMarkers(data []byte) (header, body, footer []byte) {
+	body = data
+
+	for offset := 0; offset < len(data); {
+		line, _, _ := bytes.Cut(data[offset:], []byte("\n"))
+
+		end := offset + len(line) + 1
+
+		trimmed := bytes.TrimSpace(line)
+
+		if bytes.Equal(trimmed, []byte(yamlDocumentStart)) {
+			header, body = data[:min(end, len(data)):min(end, len(data))], data[min(end, len(data)):]
+
+			break
+		}
+
+		if len(trimmed) != 0 && trimmed[0] != '#' {
+			break
+		}
+
+		offset = end
+	}
+
+	trimmed := bytes.TrimRight(body, " \t\r\n")
+
+	if bytes.HasSuffix(trimmed, []byte("\n"+yamlDocumentEnd)) {
+		body, footer = trimmed[:len(trimmed)-len(yamlDocumentEnd)], []byte(yamlDocumentEnd+"\n")
+	}
+
+	return header, body, footer
+}
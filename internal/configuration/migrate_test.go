@@ -0,0 +1,112 @@
+package configuration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestMigrate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		warnings []string
+		errors   []string
+	}{
+		{
+			"ShouldNotModifyCurrentConfiguration",
+			"---\nlog:\n    level: 'debug'\n...\n",
+			"---\nlog:\n    level: 'debug'\n...\n",
+			nil,
+			nil,
+		},
+		{
+			"ShouldRenameKeyInPlace",
+			"# yamllint disable\n---\n# The session configuration.\nsession:\n  remember_me_duration: '1M'\n  inactivity: '5m'\n...\n",
+			"# yamllint disable\n---\n# The session configuration.\nsession:\n  remember_me: '1M'\n  inactivity: '5m'\n...\n",
+			[]string{"configuration key 'session.remember_me_duration' which is deprecated in 4.38.0 was migrated to 'session.remember_me'"},
+			nil,
+		},
+		{
+			"ShouldMoveKey",
+			"jwt_secret: 'abc'\nlog_level: 'debug'\nlog:\n  format: 'json'\n",
+			"log:\n  format: 'json'\n  level: 'debug'\nidentity_validation:\n  reset_password:\n    jwt_secret: 'abc'\n",
+			[]string{
+				"configuration key 'jwt_secret' which is deprecated in 4.38.0 was migrated to 'identity_validation.reset_password.jwt_secret'",
+				"configuration key 'log_level' which is deprecated in 4.30.0 was migrated to 'log.level'",
+			},
+			nil,
+		},
+		{
+			"ShouldRemoveKeyWhenReplacementExists",
+			"server:\n  read_buffer_size: 4096\n  buffers:\n    read: 8192\n",
+			"server:\n  buffers:\n    read: 8192\n",
+			[]string{"configuration key 'server.read_buffer_size' which is deprecated in 4.36.0 was removed because the replacement key 'server.buffers.read' also exists"},
+			nil,
+		},
+		{
+			"ShouldRenameSequenceKeys",
+			"identity_providers:\n  oidc:\n    clients:\n      - id: 'one'\n        secret: 'abc'\n      - client_id: 'two'\n",
+			"identity_providers:\n  oidc:\n    clients:\n      - client_id: 'one'\n        client_secret: 'abc'\n      - client_id: 'two'\n",
+			[]string{
+				"configuration key 'identity_providers.oidc.clients[0].id' which is deprecated in 4.38.0 was migrated to 'identity_providers.oidc.clients[0].client_id'",
+				"configuration key 'identity_providers.oidc.clients[0].secret' which is deprecated in 4.38.0 was migrated to 'identity_providers.oidc.clients[0].client_secret'",
+			},
+			nil,
+		},
+		{
+			"ShouldMigrateMultipleKeys",
+			"host: '0.0.0.0'\nport: 9092\nserver:\n  path: 'auth'\n",
+			"server:\n  address: 'tcp://0.0.0.0:9092/auth'\n",
+			[]string{
+				"configuration key 'host' which is deprecated in 4.30.0 was migrated to 'server.host'",
+				"configuration key 'port' which is deprecated in 4.30.0 was migrated to 'server.port'",
+				"configuration keys 'server.host', 'server.port', and 'server.path' which are deprecated in 4.38.0 were migrated to 'server.address' with the value 'tcp://0.0.0.0:9092/auth'",
+			},
+			nil,
+		},
+		{
+			"ShouldReportManualMigration",
+			"notifier:\n  smtp:\n    trusted_cert: '/certs/ca.pem'\n",
+			"notifier:\n  smtp:\n    trusted_cert: '/certs/ca.pem'\n",
+			nil,
+			[]string{"configuration key 'notifier.smtp.trusted_cert' which is deprecated in 4.25.0 has been replaced by 'certificates_directory' and must be migrated manually"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			val := schema.NewStructValidator()
+
+			migrated, err := Migrate([]byte(tc.have), val)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, string(migrated))
+
+			warnings := make([]string, len(val.Warnings()))
+
+			for i, warning := range val.Warnings() {
+				warnings[i] = warning.Error()
+			}
+
+			errors := make([]string, len(val.Errors()))
+
+			for i, e := range val.Errors() {
+				errors[i] = e.Error()
+			}
+
+			assert.ElementsMatch(t, tc.warnings, warnings)
+			assert.ElementsMatch(t, tc.errors, errors)
+		})
+	}
+}
+
+func TestMigrateShouldErrorOnInvalidYAML(t *testing.T) {
+	_, err := Migrate([]byte("log:\n  level: [debug\n"), schema.NewStructValidator())
+
+	assert.EqualError(t, err, "error occurred parsing the configuration: yaml: line 1: did not find expected ',' or ']'")
+}
@@ -1099,3 +1099,125 @@ func MustLoadCryptoRaw(ca bool, alg, ext string, extra ...string) string {
 const (
 	pathCrypto = "./test_resources/crypto/%s.%s"
 )
+
+func TestShouldLoadConfigurationFormats(t *testing.T) {
+	testCases := []struct {
+		name string
+		file string
+		have string
+	}{
+		{"ShouldLoadYAML", "config.yml", "totp:\n  issuer: 'example.com'\n  skew: 2\nsession:\n  inactivity: '10m'\n"},
+		{"ShouldLoadTOML", "config.toml", "[totp]\nissuer = 'example.com'\nskew = 2\n\n[session]\ninactivity = '10m'\n"},
+		{"ShouldLoadJSON", "config.json", `{"totp": {"issuer": "example.com", "skew": 2}, "session": {"inactivity": "10m"}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			path := filepath.Join(dir, tc.file)
+
+			require.NoError(t, testCreateFile(path, tc.have, 0600))
+
+			for _, source := range []Source{NewFileSource(path), NewFileSource(dir)} {
+				val := schema.NewStructValidator()
+
+				_, config, err := Load(val, source)
+
+				require.NoError(t, err)
+				assert.Len(t, val.Errors(), 0)
+				assert.Len(t, val.Warnings(), 0)
+
+				assert.Equal(t, "example.com", config.TOTP.Issuer)
+				require.NotNil(t, config.TOTP.Skew)
+				assert.Equal(t, 2, *config.TOTP.Skew)
+				assert.Equal(t, time.Minute*10, config.Session.Inactivity)
+			}
+		})
+	}
+}
+
+func TestShouldFailIfTOMLIsInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg := filepath.Join(dir, "myconf.toml")
+	assert.NoError(t, testCreateFile(cfg, "[totp\nskew = 2\n", 0700))
+
+	val := schema.NewStructValidator()
+	_, _, err := Load(val, NewFileSource(cfg))
+
+	assert.NoError(t, err)
+	assert.Len(t, val.Errors(), 1)
+	assert.ErrorContains(t, val.Errors()[0], "toml: ")
+}
+
+func TestShouldMergeListsUsingListMergeStrategy(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "base.yml")
+	override := filepath.Join(dir, "prod.yml")
+
+	require.NoError(t, testCreateFile(base, "access_control:\n  default_policy: 'deny'\n  rules:\n    - domain: 'public.example.com'\n      policy: 'bypass'\n", 0600))
+	require.NoError(t, testCreateFile(override, "access_control:\n  rules:\n    - domain: 'admin.example.com'\n      policy: 'two_factor'\n", 0600))
+
+	testCases := []struct {
+		name     string
+		lists    ListMergeStrategy
+		expected []string
+	}{
+		{"ShouldReplace", ListMergeReplace, []string{"admin.example.com"}},
+		{"ShouldReplaceByDefault", "", []string{"admin.example.com"}},
+		{"ShouldAppend", ListMergeAppend, []string{"public.example.com", "admin.example.com"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			val := schema.NewStructValidator()
+
+			_, config, err := Load(val, NewPathSourcesWithListMergeStrategy([]string{base, override}, nil, tc.lists)...)
+
+			require.NoError(t, err)
+			assert.Len(t, val.Errors(), 0)
+
+			assert.Equal(t, "deny", config.AccessControl.DefaultPolicy)
+
+			domains := make([]string, len(config.AccessControl.Rules))
+
+			for i, rule := range config.AccessControl.Rules {
+				require.Len(t, rule.Domains, 1)
+
+				domains[i] = rule.Domains[0]
+			}
+
+			assert.Equal(t, tc.expected, domains)
+		})
+	}
+}
+
+func TestNewListMergeStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected ListMergeStrategy
+		err      string
+	}{
+		{"ShouldDefaultToReplace", "", ListMergeReplace, ""},
+		{"ShouldParseReplace", "replace", ListMergeReplace, ""},
+		{"ShouldParseAppend", "append", ListMergeAppend, ""},
+		{"ShouldErrorOnInvalid", "prepend", "", "list merge strategy 'prepend' is invalid: must be one of 'replace' or 'append'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := NewListMergeStrategy(tc.have)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -1,5 +1,9 @@
 package schema
 
+import (
+	"time"
+)
+
 // AccessControl represents the configuration related to ACLs.
 type AccessControl struct {
 	// The default policy if no other policy matches the request.
@@ -10,6 +14,20 @@ type AccessControl struct {
 
 	// The ACL rules list.
 	Rules []AccessControlRule `koanf:"rules" json:"rules" jsonschema:"title=Rules List" jsonschema_description:"The list of ACL rules to enumerate for requests."`
+
+	// The Kubernetes controller which synthesizes rules from annotations.
+	Kubernetes AccessControlKubernetes `koanf:"kubernetes" json:"kubernetes" jsonschema:"title=Kubernetes" jsonschema_description:"The Kubernetes controller which synthesizes ACL rules from annotated Ingress and HTTPRoute resources."`
+}
+
+// AccessControlKubernetes represents the configuration of the Kubernetes controller which synthesizes ACL rules from
+// annotated resources.
+type AccessControlKubernetes struct {
+	Enable           bool          `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables the Kubernetes controller."`
+	Namespaces       []string      `koanf:"namespaces" json:"namespaces" jsonschema:"title=Namespaces" jsonschema_description:"The namespaces to watch for annotated resources, all namespaces are watched if not configured."`
+	BypassNamespaces []string      `koanf:"bypass_namespaces" json:"bypass_namespaces" jsonschema:"title=Bypass Namespaces" jsonschema_description:"The namespaces of the annotated resources which are permitted to use the bypass policy."`
+	AnnotationPrefix string        `koanf:"annotation_prefix" json:"annotation_prefix" jsonschema:"default=authelia.com,title=Annotation Prefix" jsonschema_description:"The prefix of the annotations used to configure the synthesized rules."`
+	SyncInterval     time.Duration `koanf:"sync_interval" json:"sync_interval" jsonschema:"default=30 seconds,title=Sync Interval" jsonschema_description:"The maximum interval between full synchronizations of the resources with the Kubernetes API."`
+	GatewayAPI       bool          `koanf:"gateway_api" json:"gateway_api" jsonschema:"default=false,title=Gateway API" jsonschema_description:"Enables watching of the Gateway API HTTPRoute resources in addition to Ingress resources."`
 }
 
 // AccessControlNetwork represents one ACL network group entry.
@@ -28,6 +46,12 @@ type AccessControlRule struct {
 	Resources    AccessControlRuleRegex     `koanf:"resources" json:"resources" jsonschema:"title=Resources or Paths" jsonschema_description:"The regex patterns to match the resource paths that this rule applies to."`
 	Methods      AccessControlRuleMethods   `koanf:"methods" json:"methods" jsonschema:"enum=GET,enum=HEAD,enum=POST,enum=PUT,enum=DELETE,enum=CONNECT,enum=OPTIONS,enum=TRACE,enum=PATCH,enum=PROPFIND,enum=PROPPATCH,enum=MKCOL,enum=COPY,enum=MOVE,enum=LOCK,enum=UNLOCK" jsonschema_description:"The list of request methods this rule applies to."`
 	Query        [][]AccessControlRuleQuery `koanf:"query" json:"query" jsonschema:"title=Query Rules" jsonschema_description:"The list of query parameter rules this rule applies to."`
+	Device       string                     `koanf:"device" json:"device" jsonschema:"default=any,enum=any,enum=managed,enum=unmanaged,title=Device" jsonschema_description:"The device requirement this rule applies to, the managed value only matches requests which present a valid client certificate issued by the device certificates authority."`
+	Challenge    string                     `koanf:"challenge" json:"challenge" jsonschema:"default=auto,enum=auto,enum=redirect,enum=status,title=Challenge" jsonschema_description:"The challenge behaviour for unauthorized requests this rule applies to, the auto value responds to WebSocket and gRPC clients with a status code instead of a redirect."`
+
+	AuthnStrategies []ServerEndpointsAuthzAuthnStrategy `koanf:"authn_strategies" json:"authn_strategies" jsonschema:"title=Authn Strategies" jsonschema_description:"The authentication strategies and their authorization schemes the authz endpoints use for the requests this rule applies to instead of the authentication strategies of the endpoint."`
+
+	WebAuthnUserVerification string `koanf:"webauthn_user_verification" json:"webauthn_user_verification" jsonschema:"default=preferred,enum=preferred,enum=required,title=WebAuthn User Verification" jsonschema_description:"The WebAuthn user verification requirement this rule applies, the required value only permits sessions whose WebAuthn assertion verified the user with a PIN or biometric."`
 }
 
 // AccessControlRuleQuery represents the ACL query criteria.
@@ -37,6 +61,13 @@ type AccessControlRuleQuery struct {
 	Value    any    `koanf:"value" json:"value" jsonschema:"title=Value" jsonschema_description:"The Query Parameter value for this rule."`
 }
 
+// DefaultAccessControlKubernetesConfiguration represents the default configuration related to the Kubernetes
+// controller.
+var DefaultAccessControlKubernetesConfiguration = AccessControlKubernetes{
+	AnnotationPrefix: "authelia.com",
+	SyncInterval:     time.Second * 30,
+}
+
 // DefaultACLNetwork represents the default configuration related to access control network group configuration.
 var DefaultACLNetwork = []AccessControlNetwork{
 	{
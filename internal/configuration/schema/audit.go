@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+)
+
+// Audit represents the configuration of the audit event bus.
+type Audit struct {
+	Enabled    bool        `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the audit event bus."`
+	BufferSize int         `koanf:"buffer_size" json:"buffer_size" jsonschema:"default=1024,title=Buffer Size" jsonschema_description:"The number of events which can be queued before new events are dropped."`
+	Sinks      []AuditSink `koanf:"sinks" json:"sinks" jsonschema:"title=Sinks" jsonschema_description:"The sinks the audit events are written to."`
+}
+
+// AuditSink represents the configuration of an audit event sink.
+type AuditSink struct {
+	Name          string   `koanf:"name" json:"name" jsonschema:"title=Name" jsonschema_description:"The name of the sink used in the logs."`
+	Events        []string `koanf:"events" json:"events" jsonschema:"title=Events" jsonschema_description:"The event types written to this sink, all event types are written if not configured."`
+	ExcludeEvents []string `koanf:"exclude_events" json:"exclude_events" jsonschema:"title=Exclude Events" jsonschema_description:"The event types which are not written to this sink."`
+
+	File    *AuditSinkFile    `koanf:"file" json:"file" jsonschema:"title=File" jsonschema_description:"The File sink."`
+	Syslog  *AuditSinkSyslog  `koanf:"syslog" json:"syslog" jsonschema:"title=Syslog" jsonschema_description:"The Syslog sink."`
+	Webhook *AuditSinkWebhook `koanf:"webhook" json:"webhook" jsonschema:"title=Webhook" jsonschema_description:"The Webhook sink."`
+	Kafka   *AuditSinkKafka   `koanf:"kafka" json:"kafka" jsonschema:"title=Kafka" jsonschema_description:"The Kafka sink."`
+	Storage *AuditSinkStorage `koanf:"storage" json:"storage" jsonschema:"title=Storage" jsonschema_description:"The Storage sink."`
+}
+
+// AuditSinkFile represents the configuration of the audit sink which writes the events to a file.
+type AuditSinkFile struct {
+	Path string `koanf:"path" json:"path" jsonschema:"title=Path" jsonschema_description:"The path of the file the events are appended to."`
+}
+
+// AuditSinkSyslog represents the configuration of the audit sink which writes the events to a syslog server.
+type AuditSinkSyslog struct {
+	Address  *AddressUDP `koanf:"address" json:"address" jsonschema:"default=udp://127.0.0.1:514,title=Address" jsonschema_description:"The address of the syslog server."`
+	Facility string      `koanf:"facility" json:"facility" jsonschema:"default=auth,enum=kern,enum=user,enum=mail,enum=daemon,enum=auth,enum=syslog,enum=lpr,enum=news,enum=uucp,enum=cron,enum=authpriv,enum=ftp,enum=local0,enum=local1,enum=local2,enum=local3,enum=local4,enum=local5,enum=local6,enum=local7,title=Facility" jsonschema_description:"The syslog facility of the messages."`
+	Tag      string      `koanf:"tag" json:"tag" jsonschema:"default=authelia,title=Tag" jsonschema_description:"The app name of the messages."`
+}
+
+// AuditSinkWebhook represents the configuration of the audit sink which posts the events to a webhook.
+type AuditSinkWebhook struct {
+	URL     *url.URL          `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The URL the events are posted to."`
+	Headers map[string]string `koanf:"headers" json:"headers" jsonschema:"title=Headers" jsonschema_description:"The additional headers sent with each request."`
+	Timeout time.Duration     `koanf:"timeout" json:"timeout" jsonschema:"default=5 seconds,title=Timeout" jsonschema_description:"The timeout of each request."`
+	TLS     *TLS              `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The webhook TLS connection properties."`
+}
+
+// AuditSinkKafka represents the configuration of the audit sink which produces the events to a Kafka topic.
+type AuditSinkKafka struct {
+	Brokers []string            `koanf:"brokers" json:"brokers" jsonschema:"title=Brokers" jsonschema_description:"The addresses of the Kafka brokers in the host:port format."`
+	Topic   string              `koanf:"topic" json:"topic" jsonschema:"title=Topic" jsonschema_description:"The topic the events are produced to."`
+	Timeout time.Duration       `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of each write."`
+	TLS     *TLS                `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The Kafka TLS connection properties, the connection is only secured with TLS if configured."`
+	SASL    *AuditSinkKafkaSASL `koanf:"sasl" json:"sasl" jsonschema:"title=SASL" jsonschema_description:"The Kafka SASL authentication properties."`
+}
+
+// AuditSinkKafkaSASL represents the configuration of the Kafka SASL authentication.
+type AuditSinkKafkaSASL struct {
+	Mechanism string `koanf:"mechanism" json:"mechanism" jsonschema:"default=plain,enum=plain,enum=scram-sha-256,enum=scram-sha-512,title=Mechanism" jsonschema_description:"The SASL mechanism."`
+	Username  string `koanf:"username" json:"username" jsonschema:"title=Username" jsonschema_description:"The username for SASL authentication."`
+	Password  string `koanf:"password" json:"password" jsonschema:"title=Password" jsonschema_description:"The password for SASL authentication."`
+}
+
+// AuditSinkStorage represents the configuration of the audit sink which saves the events to the storage provider.
+type AuditSinkStorage struct {
+	Retention time.Duration `koanf:"retention" json:"retention" jsonschema:"default=90 days,title=Retention" jsonschema_description:"The duration the events are retained before they're deleted by the storage cleanup."`
+}
+
+// DefaultAuditConfiguration represents the default configuration related to the audit event bus.
+var DefaultAuditConfiguration = Audit{
+	BufferSize: 1024,
+}
+
+// DefaultAuditSinkSyslogConfiguration represents the default configuration related to the syslog audit sink.
+var DefaultAuditSinkSyslogConfiguration = AuditSinkSyslog{
+	Address:  &AddressUDP{Address{true, false, -1, 514, &url.URL{Scheme: AddressSchemeUDP, Host: "127.0.0.1:514"}}},
+	Facility: "auth",
+	Tag:      "authelia",
+}
+
+// DefaultAuditSinkWebhookConfiguration represents the default configuration related to the webhook audit sink.
+var DefaultAuditSinkWebhookConfiguration = AuditSinkWebhook{
+	Timeout: time.Second * 5,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultAuditSinkKafkaConfiguration represents the default configuration related to the Kafka audit sink.
+var DefaultAuditSinkKafkaConfiguration = AuditSinkKafka{
+	Timeout: time.Second * 10,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+	SASL: &AuditSinkKafkaSASL{
+		Mechanism: "plain",
+	},
+}
+
+// DefaultAuditSinkStorageConfiguration represents the default configuration related to the storage audit sink.
+var DefaultAuditSinkStorageConfiguration = AuditSinkStorage{
+	Retention: time.Hour * 24 * 90,
+}
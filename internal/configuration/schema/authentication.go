@@ -15,6 +15,11 @@ type AuthenticationBackend struct {
 	// The file authentication backend configuration.
 	File *AuthenticationBackendFile `koanf:"file" json:"file" jsonschema:"title=File Backend" jsonschema_description:"The file authentication backend configuration."`
 	LDAP *AuthenticationBackendLDAP `koanf:"ldap" json:"ldap" jsonschema:"title=LDAP Backend" jsonschema_description:"The LDAP authentication backend configuration."`
+	SQL  *AuthenticationBackendSQL  `koanf:"sql" json:"sql" jsonschema:"title=SQL Backend" jsonschema_description:"The SQL authentication backend configuration."`
+
+	LDAPBackends []AuthenticationBackendLDAPBackend `koanf:"ldap_backends" json:"ldap_backends" jsonschema:"title=LDAP Backends" jsonschema_description:"The list of LDAP authentication backends which are used in order."`
+
+	SPNEGO AuthenticationBackendSPNEGO `koanf:"spnego" json:"spnego" jsonschema:"title=SPNEGO" jsonschema_description:"The SPNEGO (Kerberos) authentication configuration."`
 }
 
 // AuthenticationBackendPasswordReset represents the configuration related to password reset functionality.
@@ -33,6 +38,23 @@ type AuthenticationBackendFile struct {
 	Search AuthenticationBackendFileSearch `koanf:"search" json:"search" jsonschema:"title=Search" jsonschema_description:"Configures the user searching behaviour."`
 }
 
+// AuthenticationBackendSQL represents the configuration related to the SQL backend which stores the users in the
+// storage backend.
+type AuthenticationBackendSQL struct {
+	Password AuthenticationBackendFilePassword `koanf:"password" json:"password" jsonschema:"title=Password Options" jsonschema_description:"Allows configuration of the password hashing options when the user passwords are changed by Authelia."`
+}
+
+// AuthenticationBackendSPNEGO represents the configuration related to the SPNEGO (Kerberos) authentication of the users
+// of domain-joined machines.
+type AuthenticationBackendSPNEGO struct {
+	Enabled          bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the SPNEGO authentication."`
+	Keytab           string        `koanf:"keytab" json:"keytab" jsonschema:"title=Keytab" jsonschema_description:"The path to the keytab file which contains the keys of the service principal."`
+	ServicePrincipal string        `koanf:"service_principal" json:"service_principal" jsonschema:"title=Service Principal" jsonschema_description:"The name of the service principal in the keytab used to validate the tickets."`
+	Realms           []string      `koanf:"realms" json:"realms" jsonschema:"title=Realms" jsonschema_description:"The list of realms the users are permitted to authenticate from."`
+	UsernameFormat   string        `koanf:"username_format" json:"username_format" jsonschema:"default={username},title=Username Format" jsonschema_description:"The format used to map the principal name of the user to their username."`
+	MaxClockSkew     time.Duration `koanf:"max_clock_skew" json:"max_clock_skew" jsonschema:"default=5 minutes,title=Maximum Clock Skew" jsonschema_description:"The maximum clock skew permitted between the clients and Authelia."`
+}
+
 // AuthenticationBackendFileSearch represents the configuration related to file-based backend searching.
 type AuthenticationBackendFileSearch struct {
 	Email           bool `koanf:"email" json:"email" jsonschema:"default=false,title=Email Searching" jsonschema_description:"Allows users to either use their username or their configured email as a username."`
@@ -41,13 +63,14 @@ type AuthenticationBackendFileSearch struct {
 
 // AuthenticationBackendFilePassword represents the configuration related to password hashing.
 type AuthenticationBackendFilePassword struct {
-	Algorithm string `koanf:"algorithm" json:"algorithm" jsonschema:"default=argon2,enum=argon2,enum=sha2crypt,enum=pbkdf2,enum=bcrypt,enum=scrypt,title=Algorithm" jsonschema_description:"The password hashing algorithm to use."`
+	Algorithm string `koanf:"algorithm" json:"algorithm" jsonschema:"default=argon2,enum=argon2,enum=sha2crypt,enum=pbkdf2,enum=bcrypt,enum=scrypt,enum=balloon,title=Algorithm" jsonschema_description:"The password hashing algorithm to use."`
 
 	Argon2    AuthenticationBackendFilePasswordArgon2    `koanf:"argon2" json:"argon2" jsonschema:"title=Argon2" jsonschema_description:"Configure the Argon2 password hashing parameters."`
 	SHA2Crypt AuthenticationBackendFilePasswordSHA2Crypt `koanf:"sha2crypt" json:"sha2crypt" jsonschema:"title=SHA2Crypt" jsonschema_description:"Configure the SHA2Crypt password hashing parameters."`
 	PBKDF2    AuthenticationBackendFilePasswordPBKDF2    `koanf:"pbkdf2" json:"pbkdf2" jsonschema:"title=PBKDF2" jsonschema_description:"Configure the PBKDF2 password hashing parameters."`
 	BCrypt    AuthenticationBackendFilePasswordBCrypt    `koanf:"bcrypt" json:"bcrypt" jsonschema:"title=BCrypt" jsonschema_description:"Configure the BCrypt password hashing parameters."`
 	SCrypt    AuthenticationBackendFilePasswordSCrypt    `koanf:"scrypt" json:"scrypt" jsonschema:"title=SCrypt" jsonschema_description:"Configure the SCrypt password hashing parameters."`
+	Balloon   AuthenticationBackendFilePasswordBalloon   `koanf:"balloon" json:"balloon" jsonschema:"title=Balloon" jsonschema_description:"Configure the Balloon password hashing parameters."`
 
 	// Deprecated: Use individual password options instead.
 	Iterations int `koanf:"iterations" json:"iterations" jsonschema:"deprecated,title=Iterations"`
@@ -104,6 +127,15 @@ type AuthenticationBackendFilePasswordSCrypt struct {
 	SaltLength  int `koanf:"salt_length" json:"salt_length" jsonschema:"default=16,minimum=8,maximum=1024,title=Salt Length" jsonschema_description:"The SCrypt salt length to be used."`
 }
 
+// AuthenticationBackendFilePasswordBalloon represents the balloon hashing settings.
+type AuthenticationBackendFilePasswordBalloon struct {
+	Variant    string `koanf:"variant" json:"variant" jsonschema:"default=sha256,enum=sha256,enum=sha512,title=Variant" jsonschema_description:"The Balloon variant to be used."`
+	SpaceCost  int    `koanf:"space_cost" json:"space_cost" jsonschema:"default=16384,minimum=1,maximum=2147483647,title=Space Cost" jsonschema_description:"The Balloon space cost (parameter s) in blocks to be used."`
+	TimeCost   int    `koanf:"time_cost" json:"time_cost" jsonschema:"default=3,minimum=1,maximum=2147483647,title=Time Cost" jsonschema_description:"The Balloon time cost (parameter t) in rounds to be used."`
+	Delta      int    `koanf:"delta" json:"delta" jsonschema:"default=3,minimum=1,maximum=1024,title=Delta" jsonschema_description:"The Balloon number of dependencies per block (parameter d) to be used."`
+	SaltLength int    `koanf:"salt_length" json:"salt_length" jsonschema:"default=16,minimum=8,maximum=1024,title=Salt Length" jsonschema_description:"The Balloon salt length to be used."`
+}
+
 // AuthenticationBackendLDAP represents the configuration related to LDAP server.
 type AuthenticationBackendLDAP struct {
 	Address        *AddressLDAP  `koanf:"address" json:"address" jsonschema:"title=Address" jsonschema_description:"The address of the LDAP directory server."`
@@ -112,6 +144,8 @@ type AuthenticationBackendLDAP struct {
 	StartTLS       bool          `koanf:"start_tls" json:"start_tls" jsonschema:"default=false,title=StartTLS" jsonschema_description:"Enables the use of StartTLS."`
 	TLS            *TLS          `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The LDAP directory server TLS connection properties."`
 
+	Pooling AuthenticationBackendLDAPPooling `koanf:"pooling" json:"pooling" jsonschema:"title=Pooling" jsonschema_description:"The LDAP Connection Pooling properties."`
+
 	BaseDN string `koanf:"base_dn" json:"base_dn" jsonschema:"title=Base DN" jsonschema_description:"The base for all directory server operations."`
 
 	AdditionalUsersDN string `koanf:"additional_users_dn" json:"additional_users_dn" jsonschema:"title=Additional User Base" jsonschema_description:"The base in addition to the Base DN for all directory server operations for users."`
@@ -131,6 +165,22 @@ type AuthenticationBackendLDAP struct {
 	Password string `koanf:"password" json:"password" jsonschema:"title=Password" jsonschema_description:"The password for LDAP authenticated binding."`
 }
 
+// AuthenticationBackendLDAPBackend represents the configuration related to one of multiple LDAP backends.
+type AuthenticationBackendLDAPBackend struct {
+	Name    string   `koanf:"name" json:"name" jsonschema:"title=Name" jsonschema_description:"The unique name of the LDAP backend."`
+	Domains []string `koanf:"domains" json:"domains" jsonschema:"title=Domains" jsonschema_description:"The domains which route the usernames with the domain as a suffix exclusively to this LDAP backend."`
+
+	AuthenticationBackendLDAP `koanf:",squash"`
+}
+
+// AuthenticationBackendLDAPPooling represents the configuration related to LDAP connection pooling.
+type AuthenticationBackendLDAPPooling struct {
+	Enable  bool          `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enable LDAP connection pooling."`
+	Count   int           `koanf:"count" json:"count" jsonschema:"default=5,title=Count" jsonschema_description:"The number of connections to keep open for LDAP connection pooling."`
+	Retries int           `koanf:"retries" json:"retries" jsonschema:"default=2,title=Retries" jsonschema_description:"The number of attempts to open a connection before failing."`
+	Timeout time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The duration of time to wait for a connection to become available in the pool before failing."`
+}
+
 // AuthenticationBackendLDAPAttributes represents the configuration related to LDAP server attributes.
 type AuthenticationBackendLDAPAttributes struct {
 	DistinguishedName string `koanf:"distinguished_name" json:"distinguished_name" jsonschema:"title=Attribute: Distinguished Name" jsonschema_description:"The directory server attribute which contains the distinguished name for all objects."`
@@ -145,6 +195,19 @@ var DefaultAuthenticationBackendConfig = AuthenticationBackend{
 	RefreshInterval: NewRefreshIntervalDuration(time.Minute * 5),
 }
 
+// DefaultLDAPAuthenticationBackendConfigurationPooling represents the default LDAP connection pooling config.
+var DefaultLDAPAuthenticationBackendConfigurationPooling = AuthenticationBackendLDAPPooling{
+	Count:   5,
+	Retries: 2,
+	Timeout: time.Second * 10,
+}
+
+// DefaultAuthenticationBackendSPNEGO represents the default SPNEGO authentication config.
+var DefaultAuthenticationBackendSPNEGO = AuthenticationBackendSPNEGO{
+	UsernameFormat: "{username}",
+	MaxClockSkew:   time.Minute * 5,
+}
+
 // DefaultPasswordConfig represents the default configuration related to Argon2id hashing.
 var DefaultPasswordConfig = AuthenticationBackendFilePassword{
 	Algorithm: argon2,
@@ -177,6 +240,13 @@ var DefaultPasswordConfig = AuthenticationBackendFilePassword{
 		KeyLength:   32,
 		SaltLength:  16,
 	},
+	Balloon: AuthenticationBackendFilePasswordBalloon{
+		Variant:    sha256,
+		SpaceCost:  16384,
+		TimeCost:   3,
+		Delta:      3,
+		SaltLength: 16,
+	},
 }
 
 // DefaultCIPasswordConfig represents the default configuration related to Argon2id hashing for CI.
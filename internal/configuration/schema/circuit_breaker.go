@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"time"
+)
+
+// CircuitBreaker represents the configuration of the circuit breakers around the calls to the dependencies.
+type CircuitBreaker struct {
+	Enabled          bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the circuit breakers so the calls to a failing dependency fail fast instead of waiting for it."`
+	Dependencies     []string      `koanf:"dependencies" json:"dependencies" jsonschema:"enum=ldap,enum=smtp,enum=duo,enum=push,enum=storage,title=Dependencies" jsonschema_description:"The dependencies which have a circuit breaker."`
+	FailureThreshold int           `koanf:"failure_threshold" json:"failure_threshold" jsonschema:"default=5,minimum=1,title=Failure Threshold" jsonschema_description:"The number of consecutive failures which open the circuit breaker."`
+	OpenDuration     time.Duration `koanf:"open_duration" json:"open_duration" jsonschema:"default=30 seconds,title=Open Duration" jsonschema_description:"The duration the circuit breaker stays open before the dependency is probed."`
+	ProbeTimeout     time.Duration `koanf:"probe_timeout" json:"probe_timeout" jsonschema:"default=5 seconds,title=Probe Timeout" jsonschema_description:"The duration the health check which probes the dependency can take before it's considered a failure."`
+}
+
+// DefaultCircuitBreakerConfiguration represents the default configuration parameters for the circuit breakers.
+var DefaultCircuitBreakerConfiguration = CircuitBreaker{
+	Dependencies:     []string{CircuitBreakerDependencyLDAP, CircuitBreakerDependencySMTP, CircuitBreakerDependencyDuo, CircuitBreakerDependencyPush, CircuitBreakerDependencyStorage},
+	FailureThreshold: 5,
+	OpenDuration:     time.Second * 30,
+	ProbeTimeout:     time.Second * 5,
+}
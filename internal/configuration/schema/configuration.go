@@ -8,7 +8,7 @@ import (
 type Configuration struct {
 	Theme                 string `koanf:"theme" json:"theme" jsonschema:"default=light,enum=auto,enum=light,enum=dark,enum=grey,title=Theme Name" jsonschema_description:"The name of the theme to apply to the web UI."`
 	CertificatesDirectory string `koanf:"certificates_directory" json:"certificates_directory" jsonschema:"title=Certificates Directory Path" jsonschema_description:"The path to a directory which is used to determine the certificates that are trusted."`
-	Default2FAMethod      string `koanf:"default_2fa_method" json:"default_2fa_method" jsonschema:"enum=totp,enum=webauthn,enum=mobile_push,title=Default 2FA method" jsonschema_description:"When a user logs in for the first time this is the 2FA method configured for them."`
+	Default2FAMethod      string `koanf:"default_2fa_method" json:"default_2fa_method" jsonschema:"enum=totp,enum=webauthn,enum=mobile_push,enum=sms,enum=email,title=Default 2FA method" jsonschema_description:"When a user logs in for the first time this is the 2FA method configured for them."`
 
 	Log                   Log                   `koanf:"log" json:"log" jsonschema:"title=Log" jsonschema_description:"Logging Configuration."`
 	IdentityProviders     IdentityProviders     `koanf:"identity_providers" json:"identity_providers" jsonschema:"title=Identity Providers" jsonschema_description:"Identity Providers Configuration."`
@@ -16,7 +16,11 @@ type Configuration struct {
 	Session               Session               `koanf:"session" json:"session" jsonschema:"title=Session" jsonschema_description:"Session Configuration."`
 	TOTP                  TOTP                  `koanf:"totp" json:"totp" jsonschema:"title=TOTP" jsonschema_description:"Time-based One-Time Password Configuration."`
 	DuoAPI                DuoAPI                `koanf:"duo_api" json:"duo_api" jsonschema:"title=Duo API" jsonschema_description:"Duo API Configuration."`
+	Push                  Push                  `koanf:"push" json:"push" jsonschema:"title=Push" jsonschema_description:"Push Notification Configuration."`
+	SMS                   SMS                   `koanf:"sms" json:"sms" jsonschema:"title=SMS" jsonschema_description:"SMS Configuration."`
+	Email                 Email                 `koanf:"email" json:"email" jsonschema:"title=Email" jsonschema_description:"Email Second Factor Configuration."`
 	AccessControl         AccessControl         `koanf:"access_control" json:"access_control" jsonschema:"title=Access Control" jsonschema_description:"Access Control Configuration."`
+	DeviceCertificates    DeviceCertificates    `koanf:"device_certificates" json:"device_certificates" jsonschema:"title=Device Certificates" jsonschema_description:"Device Certificates Configuration."`
 	NTP                   NTP                   `koanf:"ntp" json:"ntp" jsonschema:"title=NTP" jsonschema_description:"Network Time Protocol Configuration."`
 	Regulation            Regulation            `koanf:"regulation" json:"regulation" jsonschema:"title=Regulation" jsonschema_description:"Regulation Configuration."`
 	Storage               Storage               `koanf:"storage" json:"storage" jsonschema:"title=Storage" jsonschema_description:"Storage Configuration."`
@@ -27,6 +31,9 @@ type Configuration struct {
 	PasswordPolicy        PasswordPolicy        `koanf:"password_policy" json:"password_policy" jsonschema:"title=Password Policy" jsonschema_description:"Password Policy Configuration."`
 	PrivacyPolicy         PrivacyPolicy         `koanf:"privacy_policy" json:"privacy_policy" jsonschema:"title=Privacy Policy" jsonschema_description:"Privacy Policy Configuration."`
 	IdentityValidation    IdentityValidation    `koanf:"identity_validation" json:"identity_validation" jsonschema:"title=Identity Validation" jsonschema_description:"Identity Validation Configuration."`
+	Audit                 Audit                 `koanf:"audit" json:"audit" jsonschema:"title=Audit" jsonschema_description:"Audit Configuration."`
+	LeaderElection        LeaderElection        `koanf:"leader_election" json:"leader_election" jsonschema:"title=Leader Election" jsonschema_description:"Leader Election Configuration."`
+	CircuitBreaker        CircuitBreaker        `koanf:"circuit_breaker" json:"circuit_breaker" jsonschema:"title=Circuit Breaker" jsonschema_description:"Circuit Breaker Configuration."`
 
 	// Deprecated: Use the session cookies option with the same name instead.
 	DefaultRedirectionURL *url.URL `koanf:"default_redirection_url" json:"default_redirection_url" jsonschema:"deprecated,format=uri,title=The default redirection URL"`
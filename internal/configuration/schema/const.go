@@ -9,9 +9,15 @@ import (
 const (
 	argon2   = "argon2"
 	argon2id = "argon2id"
+	sha256   = "sha256"
 	sha512   = "sha512"
 )
 
+const (
+	// CSPTemplateNoncePlaceholder is the placeholder in a CSPTemplate which is replaced with the per-request nonce.
+	CSPTemplateNoncePlaceholder = "${NONCE}"
+)
+
 const (
 	// TLSVersion13 is the textual representation of TLS 1.3.
 	TLSVersion13 = "TLS1.3"
@@ -90,6 +96,23 @@ const (
 	TOTPAlgorithmSHA512 = "SHA512"
 )
 
+const (
+	// OneTimeCodeCharacterSetUnambiguous is the string for the one-time code character set which excludes the characters
+	// which are easily confused with each other.
+	OneTimeCodeCharacterSetUnambiguous = "unambiguous"
+
+	// OneTimeCodeCharacterSetAlphaNumeric is the string for the upper-case alphanumeric one-time code character set.
+	OneTimeCodeCharacterSetAlphaNumeric = "alphanumeric"
+
+	// OneTimeCodeCharacterSetNumeric is the string for the numeric one-time code character set.
+	OneTimeCodeCharacterSetNumeric = "numeric"
+)
+
+const (
+	// OneTimeCodeDeliveryEmail is the string for the delivery of one-time codes via the notifier.
+	OneTimeCodeDeliveryEmail = "email"
+)
+
 const (
 	// RememberMeDisabled represents the duration for a disabled remember me session configuration.
 	RememberMeDisabled = time.Second * -1
@@ -106,6 +129,9 @@ const (
 
 	// TOTPSecretSizeMinimum is the minimum secret size.
 	TOTPSecretSizeMinimum = 20
+
+	// TOTPDriftMaximum is the maximum number of periods the learned clock drift can be configured to.
+	TOTPDriftMaximum = 20
 )
 
 var (
@@ -128,6 +154,22 @@ const (
 	blockCERTIFICATE = "CERTIFICATE"
 )
 
+// Push notification providers.
+const (
+	PushProviderPrivacyIDEA = "privacyidea"
+	PushProviderWebhook     = "webhook"
+	PushProviderMobile      = "mobile"
+	PushProviderNtfy        = "ntfy"
+	PushProviderGotify      = "gotify"
+)
+
+// SMS providers.
+const (
+	SMSProviderTwilio = "twilio"
+	SMSProviderVonage = "vonage"
+	SMSProviderHTTP   = "http"
+)
+
 // Authorization Schemes.
 const (
 	SchemeBasic  = "basic"
@@ -153,6 +195,41 @@ const (
 	AuthzStrategyHeaderLegacy                        = "HeaderLegacy"
 )
 
+// Session Storage values.
+const (
+	SessionStorageMemory = "memory"
+	SessionStorageRedis  = "redis"
+	SessionStorageSQL    = "sql"
+)
+
+// Leader Election values.
+const (
+	LeaderElectionBackendStorage = "storage"
+	LeaderElectionBackendRedis   = "redis"
+)
+
+// Storage IAM Authentication values.
+const (
+	StorageIAMAuthenticationProviderAWS   = "aws"
+	StorageIAMAuthenticationProviderAzure = "azure"
+	StorageIAMAuthenticationProviderGCP   = "gcp"
+)
+
+// Telemetry Metrics StatsD Flavor values.
+const (
+	TelemetryMetricsStatsDFlavorStatsD    = "statsd"
+	TelemetryMetricsStatsDFlavorDogStatsD = "dogstatsd"
+)
+
+// Circuit Breaker values.
+const (
+	CircuitBreakerDependencyLDAP    = "ldap"
+	CircuitBreakerDependencySMTP    = "smtp"
+	CircuitBreakerDependencyDuo     = "duo"
+	CircuitBreakerDependencyPush    = "push"
+	CircuitBreakerDependencyStorage = "storage"
+)
+
 const (
 	ldapGroupSearchModeFilter = "filter"
 )
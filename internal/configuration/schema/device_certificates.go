@@ -0,0 +1,21 @@
+package schema
+
+import (
+	"time"
+)
+
+// DeviceCertificates represents the configuration of the internal certificate authority which issues client
+// certificates to managed devices.
+type DeviceCertificates struct {
+	Enabled          bool                    `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the issuance of client certificates to managed devices."`
+	CertificateChain X509CertificateChain    `koanf:"certificate_chain" json:"certificate_chain" jsonschema:"title=Certificate Chain" jsonschema_description:"The certificate chain of the certificate authority which issues the client certificates."`
+	PrivateKey       CryptographicPrivateKey `koanf:"private_key" json:"private_key" jsonschema:"title=Private Key" jsonschema_description:"The private key of the certificate authority which issues the client certificates."`
+	Lifespan         time.Duration           `koanf:"lifespan" json:"lifespan" jsonschema:"default=1 day,title=Lifespan" jsonschema_description:"The lifespan of the issued client certificates."`
+	Header           string                  `koanf:"header" json:"header" jsonschema:"default=X-Forwarded-Tls-Client-Cert,title=Header" jsonschema_description:"The header the proxy uses to forward the client certificate presented by the device."`
+}
+
+// DefaultDeviceCertificatesConfiguration represents the default configuration parameters for the device certificates.
+var DefaultDeviceCertificatesConfiguration = DeviceCertificates{
+	Lifespan: time.Hour * 24,
+	Header:   "X-Forwarded-Tls-Client-Cert",
+}
@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"time"
+)
+
+// Email represents the configuration related to the Email second factor method which delivers One-Time Codes and
+// optionally a magic link to the email address of a user with the notifier.
+type Email struct {
+	Enable          bool          `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables the Email second factor method."`
+	Characters      int           `koanf:"characters" json:"characters" jsonschema:"default=8,minimum=6,maximum=20,title=Characters" jsonschema_description:"The number of characters in the generated One-Time Codes."`
+	CharacterSet    string        `koanf:"character_set" json:"character_set" jsonschema:"default=unambiguous,enum=unambiguous,enum=alphanumeric,enum=numeric,title=Character Set" jsonschema_description:"The set of characters the generated One-Time Codes consist of."`
+	CodeLifespan    time.Duration `koanf:"code_lifespan" json:"code_lifespan" jsonschema:"default=10 minutes,title=Code Lifespan" jsonschema_description:"The lifespan of the generated One-Time Codes after which they're considered invalid."`
+	MaximumAttempts int           `koanf:"max_attempts" json:"max_attempts" jsonschema:"default=3,minimum=1,title=Maximum Attempts" jsonschema_description:"The number of failed attempts to validate a One-Time Code after which it's revoked."`
+	Cooldown        time.Duration `koanf:"cooldown" json:"cooldown" jsonschema:"default=1 minute,title=Cooldown" jsonschema_description:"The minimum duration between the One-Time Codes sent to each user."`
+	EnableMagicLink bool          `koanf:"enable_magic_link" json:"enable_magic_link" jsonschema:"default=false,title=Enable Magic Link" jsonschema_description:"Includes a link in the email which validates the One-Time Code when opened in the browser which requested it."`
+}
+
+// DefaultEmailConfiguration represents the default configuration related to the Email second factor method.
+var DefaultEmailConfiguration = Email{
+	Characters:      8,
+	CharacterSet:    OneTimeCodeCharacterSetUnambiguous,
+	CodeLifespan:    time.Minute * 10,
+	MaximumAttempts: 3,
+	Cooldown:        time.Minute,
+}
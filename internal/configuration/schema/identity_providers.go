@@ -9,6 +9,39 @@ import (
 // IdentityProviders represents the Identity Providers configuration for Authelia.
 type IdentityProviders struct {
 	OIDC *IdentityProvidersOpenIDConnect `koanf:"oidc" json:"oidc"`
+	SAML *IdentityProvidersSAML          `koanf:"saml" json:"saml"`
+}
+
+// IdentityProvidersSAML represents the configuration for the SAML 2.0 Identity Provider.
+type IdentityProvidersSAML struct {
+	EntityID string `koanf:"entity_id" json:"entity_id" jsonschema:"title=Entity ID" jsonschema_description:"The Entity ID of the Identity Provider which defaults to the URL of the metadata endpoint."`
+
+	CertificateChain X509CertificateChain `koanf:"certificate_chain" json:"certificate_chain" jsonschema:"title=Certificate Chain" jsonschema_description:"The certificate chain with the RSA public key used to sign the SAML responses and assertions."`
+	PrivateKey       *rsa.PrivateKey      `koanf:"private_key" json:"private_key" jsonschema:"title=Private Key" jsonschema_description:"The RSA private key used to sign the SAML responses and assertions."`
+
+	SignatureAlgorithm string `koanf:"signature_algorithm" json:"signature_algorithm" jsonschema:"default=RS256,enum=RS256,enum=RS512,title=Signature Algorithm" jsonschema_description:"The algorithm used to sign the SAML responses and assertions."`
+
+	AssertionLifespan time.Duration `koanf:"assertion_lifespan" json:"assertion_lifespan" jsonschema:"default=5 minutes,title=Assertion Lifespan" jsonschema_description:"The duration an assertion can be used by a service provider after it's issued."`
+	SessionLifespan   time.Duration `koanf:"session_lifespan" json:"session_lifespan" jsonschema:"default=8 hours,title=Session Lifespan" jsonschema_description:"The duration a service provider can maintain the session it establishes with an assertion."`
+
+	ServiceProviders []IdentityProvidersSAMLServiceProvider `koanf:"service_providers" json:"service_providers" jsonschema:"title=Service Providers" jsonschema_description:"The SAML 2.0 service providers registry."`
+}
+
+// IdentityProvidersSAMLServiceProvider represents the configuration for a SAML 2.0 Service Provider.
+type IdentityProvidersSAMLServiceProvider struct {
+	EntityID string `koanf:"entity_id" json:"entity_id" jsonschema:"required,title=Entity ID" jsonschema_description:"The Entity ID of the service provider."`
+	Name     string `koanf:"name" json:"name" jsonschema:"title=Name" jsonschema_description:"The display name of the service provider."`
+
+	Metadata string `koanf:"metadata" json:"metadata" jsonschema:"title=Metadata" jsonschema_description:"The path to a file with the SAML 2.0 metadata of the service provider which configures the assertion consumer service and single logout service URLs."`
+
+	AssertionConsumerServiceURLs []*url.URL `koanf:"assertion_consumer_service_urls" json:"assertion_consumer_service_urls" jsonschema:"format=uri,title=Assertion Consumer Service URLs" jsonschema_description:"The URLs the assertions are permitted to be sent to with the HTTP-POST binding."`
+	SingleLogoutServiceURL       *url.URL   `koanf:"single_logout_service_url" json:"single_logout_service_url" jsonschema:"format=uri,title=Single Logout Service URL" jsonschema_description:"The URL the logout responses are sent to with the HTTP-Redirect binding."`
+
+	CertificateChain X509CertificateChain `koanf:"certificate_chain" json:"certificate_chain" jsonschema:"title=Certificate Chain" jsonschema_description:"The certificate chain with the RSA public key used to verify the signatures of the logout requests."`
+
+	NameIDFormat        string `koanf:"name_id_format" json:"name_id_format" jsonschema:"default=unspecified,enum=unspecified,enum=email,enum=persistent,enum=transient,title=Name ID Format" jsonschema_description:"The format of the name identifier of the subject of the assertions."`
+	AuthorizationPolicy string `koanf:"authorization_policy" json:"authorization_policy" jsonschema:"default=two_factor,enum=one_factor,enum=two_factor,title=Authorization Policy" jsonschema_description:"The authorization policy required to issue assertions for this service provider."`
+	SignResponse        bool   `koanf:"sign_response" json:"sign_response" jsonschema:"default=false,title=Sign Response" jsonschema_description:"Signs the response in addition to the assertion."`
 }
 
 // IdentityProvidersOpenIDConnect represents the configuration for OpenID Connect 1.0.
@@ -33,6 +66,9 @@ type IdentityProvidersOpenIDConnect struct {
 
 	Clients []IdentityProvidersOpenIDConnectClient `koanf:"clients" json:"clients" jsonschema:"title=Clients" jsonschema_description:"OpenID Connect 1.0 clients registry."`
 
+	DynamicClientRegistration IdentityProvidersOpenIDConnectDynamicClientRegistration `koanf:"dynamic_client_registration" json:"dynamic_client_registration" jsonschema:"title=Dynamic Client Registration" jsonschema_description:"Configuration options for the OAuth 2.0 Dynamic Client Registration endpoint."`
+	BackchannelAuthentication IdentityProvidersOpenIDConnectBackchannelAuthentication `koanf:"backchannel_authentication" json:"backchannel_authentication" jsonschema:"title=Backchannel Authentication" jsonschema_description:"Configuration options for the OpenID Connect 1.0 Client Initiated Backchannel Authentication endpoint."`
+
 	AuthorizationPolicies map[string]IdentityProvidersOpenIDConnectPolicy `koanf:"authorization_policies" json:"authorization_policies" jsonschema:"title=Authorization Policies" jsonschema_description:"Custom client authorization policies."`
 	Lifespans             IdentityProvidersOpenIDConnectLifespans         `koanf:"lifespans" json:"lifespans" jsonschema:"title=Lifespans" jsonschema_description:"Token lifespans configuration."`
 
@@ -71,6 +107,7 @@ type IdentityProvidersOpenIDConnectDiscovery struct {
 type IdentityProvidersOpenIDConnectLifespans struct {
 	IdentityProvidersOpenIDConnectLifespanToken `koanf:",squash"`
 	JWTSecuredAuthorization                     time.Duration `koanf:"jwt_secured_authorization" json:"jwt_secured_authorization" jsonschema:"default=5 minutes,title=JARM" jsonschema_description:"Allows tuning the token lifespan for the JWT Secured Authorization Response Mode (JARM)."`
+	DeviceCode                                  time.Duration `koanf:"device_code" json:"device_code" jsonschema:"default=10 minutes,title=Device Code" jsonschema_description:"Allows tuning the lifespan of the device code and user code issued by the Device Authorization Endpoint."`
 
 	Custom map[string]IdentityProvidersOpenIDConnectLifespan `koanf:"custom" json:"custom" jsonschema:"title=Custom Lifespans" jsonschema_description:"Allows creating custom lifespans to be used by individual clients."`
 }
@@ -89,6 +126,8 @@ type IdentityProvidersOpenIDConnectLifespanGrants struct {
 	ClientCredentials IdentityProvidersOpenIDConnectLifespanToken `koanf:"client_credentials" json:"client_credentials" jsonschema:"title=Client Credentials Grant" jsonschema_description:"Allows tuning the token lifespans for the client credentials grant."`
 	RefreshToken      IdentityProvidersOpenIDConnectLifespanToken `koanf:"refresh_token" json:"refresh_token" jsonschema:"title=Refresh Token Grant" jsonschema_description:"Allows tuning the token lifespans for the refresh token grant."`
 	JWTBearer         IdentityProvidersOpenIDConnectLifespanToken `koanf:"jwt_bearer" json:"jwt_bearer" jsonschema:"title=JWT Bearer Grant" jsonschema_description:"Allows tuning the token lifespans for the JWT bearer grant."`
+	DeviceCode        IdentityProvidersOpenIDConnectLifespanToken `koanf:"device_code" json:"device_code" jsonschema:"title=Device Code Grant" jsonschema_description:"Allows tuning the token lifespans for the device code grant."`
+	TokenExchange     IdentityProvidersOpenIDConnectLifespanToken `koanf:"token_exchange" json:"token_exchange" jsonschema:"title=Token Exchange Grant" jsonschema_description:"Allows tuning the token lifespans for the token exchange grant."`
 }
 
 // IdentityProvidersOpenIDConnectLifespanToken allows tuning the lifespans for each token type.
@@ -101,12 +140,31 @@ type IdentityProvidersOpenIDConnectLifespanToken struct {
 
 // IdentityProvidersOpenIDConnectCORS represents an OpenID Connect 1.0 CORS config.
 type IdentityProvidersOpenIDConnectCORS struct {
-	Endpoints      []string   `koanf:"endpoints" json:"endpoints" jsonschema:"uniqueItems,enum=authorization,enum=pushed-authorization-request,enum=token,enum=introspection,enum=revocation,enum=userinfo,title=Endpoints" jsonschema_description:"List of endpoints to enable CORS handling for."`
+	Endpoints      []string   `koanf:"endpoints" json:"endpoints" jsonschema:"uniqueItems,enum=authorization,enum=pushed-authorization-request,enum=device-authorization,enum=token,enum=introspection,enum=revocation,enum=userinfo,title=Endpoints" jsonschema_description:"List of endpoints to enable CORS handling for."`
 	AllowedOrigins []*url.URL `koanf:"allowed_origins" json:"allowed_origins" jsonschema:"format=uri,title=Allowed Origins" jsonschema_description:"List of arbitrary allowed origins for CORS requests."`
 
 	AllowedOriginsFromClientRedirectURIs bool `koanf:"allowed_origins_from_client_redirect_uris" json:"allowed_origins_from_client_redirect_uris" jsonschema:"default=false,title=Allowed Origins From Client Redirect URIs" jsonschema_description:"Automatically include the redirect URIs from the registered clients."`
 }
 
+// IdentityProvidersOpenIDConnectDynamicClientRegistration represents the configuration for the OAuth 2.0 Dynamic Client
+// Registration endpoint.
+type IdentityProvidersOpenIDConnectDynamicClientRegistration struct {
+	Enabled             bool     `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the OAuth 2.0 Dynamic Client Registration endpoint."`
+	InitialAccessTokens []string `koanf:"initial_access_tokens" json:"initial_access_tokens" jsonschema:"title=Initial Access Tokens" jsonschema_description:"List of bearer tokens one of which must be presented to register a client."`
+	OpenRegistration    bool     `koanf:"open_registration" json:"open_registration" jsonschema:"default=false,title=Open Registration" jsonschema_description:"Allows anyone who can reach the registration endpoint to register public clients using the authorization code grant without an initial access token."`
+	AuthorizationPolicy string   `koanf:"authorization_policy" json:"authorization_policy" jsonschema:"default=two_factor,title=Authorization Policy" jsonschema_description:"The authorization policy applied to all dynamically registered clients."`
+	Scopes              []string `koanf:"scopes" json:"scopes" jsonschema:"title=Scopes" jsonschema_description:"The scopes dynamically registered clients are allowed to register."`
+	GrantTypes          []string `koanf:"grant_types" json:"grant_types" jsonschema:"enum=authorization_code,enum=refresh_token,enum=client_credentials,enum=urn:ietf:params:oauth:grant-type:device_code,title=Grant Types" jsonschema_description:"The grant types dynamically registered clients are allowed to register."`
+}
+
+// IdentityProvidersOpenIDConnectBackchannelAuthentication represents the configuration for the OpenID Connect 1.0
+// Client Initiated Backchannel Authentication endpoint.
+type IdentityProvidersOpenIDConnectBackchannelAuthentication struct {
+	Enabled         bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the OpenID Connect 1.0 Client Initiated Backchannel Authentication endpoint."`
+	Lifespan        time.Duration `koanf:"lifespan" json:"lifespan" jsonschema:"default=5 minutes,title=Lifespan" jsonschema_description:"The duration a backchannel authentication request is valid for."`
+	PollingInterval time.Duration `koanf:"polling_interval" json:"polling_interval" jsonschema:"default=5 seconds,title=Polling Interval" jsonschema_description:"The minimum duration clients must wait between polling the token endpoint and the interval the out-of-band approval is checked."`
+}
+
 // IdentityProvidersOpenIDConnectClient represents a configuration for an OpenID Connect 1.0 client.
 type IdentityProvidersOpenIDConnectClient struct {
 	ID                  string          `koanf:"client_id" json:"client_id" jsonschema:"required,minLength=1,title=Client ID" jsonschema_description:"The Client ID."`
@@ -120,19 +178,22 @@ type IdentityProvidersOpenIDConnectClient struct {
 
 	Audience      []string `koanf:"audience" json:"audience" jsonschema:"uniqueItems,title=Audience" jsonschema_description:"List of authorized audiences."`
 	Scopes        []string `koanf:"scopes" json:"scopes" jsonschema:"required,enum=openid,enum=offline_access,enum=groups,enum=email,enum=profile,enum=authelia.bearer.authz,uniqueItems,title=Scopes" jsonschema_description:"The Scopes this client is allowed request and be granted."`
-	GrantTypes    []string `koanf:"grant_types" json:"grant_types" jsonschema:"enum=authorization_code,enum=implicit,enum=refresh_token,enum=client_credentials,uniqueItems,title=Grant Types" jsonschema_description:"The Grant Types this client is allowed to use for the protected endpoints."`
+	GrantTypes    []string `koanf:"grant_types" json:"grant_types" jsonschema:"enum=authorization_code,enum=implicit,enum=refresh_token,enum=client_credentials,enum=urn:openid:params:grant-type:ciba,uniqueItems,title=Grant Types" jsonschema_description:"The Grant Types this client is allowed to use for the protected endpoints."`
 	ResponseTypes []string `koanf:"response_types" json:"response_types" jsonschema:"enum=code,enum=id_token token,enum=id_token,enum=token,enum=code token,enum=code id_token,enum=code id_token token,uniqueItems,title=Response Types" jsonschema_description:"The Response Types the client is authorized to request."`
 	ResponseModes []string `koanf:"response_modes" json:"response_modes" jsonschema:"enum=form_post,enum=form_post.jwt,enum=query,enum=query.jwt,enum=fragment,enum=fragment.jwt,enum=jwt,uniqueItems,title=Response Modes" jsonschema_description:"The Response Modes this client is authorized request."`
 
 	AuthorizationPolicy string `koanf:"authorization_policy" json:"authorization_policy" jsonschema:"title=Authorization Policy" jsonschema_description:"The Authorization Policy to apply to this client."`
 	Lifespan            string `koanf:"lifespan" json:"lifespan" jsonschema:"title=Lifespan Name" jsonschema_description:"The name of the custom lifespan to utilize for this client."`
 
+	WebAuthnUserVerification string `koanf:"webauthn_user_verification" json:"webauthn_user_verification" jsonschema:"default=preferred,enum=preferred,enum=required,title=WebAuthn User Verification" jsonschema_description:"The WebAuthn user verification requirement for this client, the required value only permits sessions whose WebAuthn assertion verified the user with a PIN or biometric."`
+
 	RequestedAudienceMode        string         `koanf:"requested_audience_mode" json:"requested_audience_mode" jsonschema:"enum=explicit,enum=implicit,title=Requested Audience Mode" jsonschema_description:"The Requested Audience Mode used for this client."`
 	ConsentMode                  string         `koanf:"consent_mode" json:"consent_mode" jsonschema:"enum=auto,enum=explicit,enum=implicit,enum=pre-configured,title=Consent Mode" jsonschema_description:"The Consent Mode used for this client."`
 	ConsentPreConfiguredDuration *time.Duration `koanf:"pre_configured_consent_duration" json:"pre_configured_consent_duration" jsonschema:"default=7 days,title=Pre-Configured Consent Duration" jsonschema_description:"The Pre-Configured Consent Duration when using Consent Mode pre-configured for this client."`
 
 	RequirePushedAuthorizationRequests bool `koanf:"require_pushed_authorization_requests" json:"require_pushed_authorization_requests" jsonschema:"default=false,title=Require Pushed Authorization Requests" jsonschema_description:"Requires Pushed Authorization Requests for this client to perform an authorization."`
 	RequirePKCE                        bool `koanf:"require_pkce" json:"require_pkce" jsonschema:"default=false,title=Require PKCE" jsonschema_description:"Requires a Proof Key for this client to perform Code Exchange."`
+	DPoPBoundAccessTokens              bool `koanf:"dpop_bound_access_tokens" json:"dpop_bound_access_tokens" jsonschema:"default=false,title=DPoP Bound Access Tokens" jsonschema_description:"Requires this client to use DPoP to obtain sender-constrained access tokens."`
 
 	PKCEChallengeMethod string `koanf:"pkce_challenge_method" json:"pkce_challenge_method" jsonschema:"enum=plain,enum=S256,title=PKCE Challenge Method" jsonschema_description:"The PKCE Challenge Method enforced on this client."`
 
@@ -155,9 +216,25 @@ type IdentityProvidersOpenIDConnectClient struct {
 	JSONWebKeysURI *url.URL `koanf:"jwks_uri" json:"jwks_uri" jsonschema:"title=JSON Web Keys URI" jsonschema_description:"URI of the JWKS endpoint which contains the Public Keys used to validate request objects and the 'private_key_jwt' client authentication method for this client."`
 	JSONWebKeys    []JWK    `koanf:"jwks" json:"jwks" jsonschema:"title=JSON Web Keys" jsonschema_description:"List of arbitrary Public Keys used to validate request objects and the 'private_key_jwt' client authentication method for this client."`
 
+	BackchannelTokenDeliveryMode          string   `koanf:"backchannel_token_delivery_mode" json:"backchannel_token_delivery_mode" jsonschema:"enum=poll,enum=ping,title=Backchannel Token Delivery Mode" jsonschema_description:"The Client Initiated Backchannel Authentication token delivery mode this client uses."`
+	BackchannelClientNotificationEndpoint *url.URL `koanf:"backchannel_client_notification_endpoint" json:"backchannel_client_notification_endpoint" jsonschema:"format=uri,title=Backchannel Client Notification Endpoint" jsonschema_description:"The endpoint notified when a Client Initiated Backchannel Authentication request is completed using the ping token delivery mode."`
+
 	Discovery IdentityProvidersOpenIDConnectDiscovery `json:"-"` // MetaData value. Not configurable by users.
 }
 
+// DefaultSAMLConfiguration contains defaults for the SAML 2.0 Identity Provider.
+var DefaultSAMLConfiguration = IdentityProvidersSAML{
+	SignatureAlgorithm: "RS256",
+	AssertionLifespan:  time.Minute * 5,
+	SessionLifespan:    time.Hour * 8,
+}
+
+// DefaultSAMLServiceProviderConfiguration contains defaults for the SAML 2.0 Service Providers.
+var DefaultSAMLServiceProviderConfiguration = IdentityProvidersSAMLServiceProvider{
+	NameIDFormat:        "unspecified",
+	AuthorizationPolicy: policyTwoFactor,
+}
+
 // DefaultOpenIDConnectConfiguration contains defaults for OIDC.
 var DefaultOpenIDConnectConfiguration = IdentityProvidersOpenIDConnect{
 	Lifespans: IdentityProvidersOpenIDConnectLifespans{
@@ -171,6 +248,21 @@ var DefaultOpenIDConnectConfiguration = IdentityProvidersOpenIDConnect{
 	EnforcePKCE: "public_clients_only",
 }
 
+// DefaultOpenIDConnectDynamicClientRegistrationConfiguration contains defaults for the OAuth 2.0 Dynamic Client
+// Registration endpoint.
+var DefaultOpenIDConnectDynamicClientRegistrationConfiguration = IdentityProvidersOpenIDConnectDynamicClientRegistration{
+	AuthorizationPolicy: policyTwoFactor,
+	Scopes:              []string{"openid", "offline_access", "groups", "profile", "email"},
+	GrantTypes:          []string{"authorization_code", "refresh_token"},
+}
+
+// DefaultOpenIDConnectBackchannelAuthenticationConfiguration contains defaults for the OpenID Connect 1.0 Client
+// Initiated Backchannel Authentication endpoint.
+var DefaultOpenIDConnectBackchannelAuthenticationConfiguration = IdentityProvidersOpenIDConnectBackchannelAuthentication{
+	Lifespan:        time.Minute * 5,
+	PollingInterval: time.Second * 5,
+}
+
 var DefaultOpenIDConnectPolicyConfiguration = IdentityProvidersOpenIDConnectPolicy{
 	DefaultPolicy: policyTwoFactor,
 }
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"strings"
 	"time"
 )
 
@@ -8,13 +9,71 @@ import (
 type IdentityValidation struct {
 	ResetPassword   IdentityValidationResetPassword   `koanf:"reset_password" json:"reset_password" jsonschema:"title=Reset Password" jsonschema_description:"Identity Validation options for the Reset Password flow."`
 	ElevatedSession IdentityValidationElevatedSession `koanf:"elevated_session" json:"elevated_session" jsonschema:"title=Elevated Session" jsonschema_description:"Identity Validation options for obtaining an Elevated Session for flows such as the Credential Management flows."`
+	EmailDomains    IdentityValidationEmailDomains    `koanf:"email_domains" json:"email_domains" jsonschema:"title=Email Domains" jsonschema_description:"The email domains which are permitted to receive the Identity Validation notifications."`
+}
+
+// IdentityValidationEmailDomains represents the email domains which are permitted to receive the identity verification
+// notifications.
+type IdentityValidationEmailDomains struct {
+	Allow []string `koanf:"allow" json:"allow" jsonschema:"title=Allow" jsonschema_description:"The email domains which are permitted to receive the Identity Validation notifications, when configured all other email domains are denied."`
+	Deny  []string `koanf:"deny" json:"deny" jsonschema:"title=Deny" jsonschema_description:"The email domains which are not permitted to receive the Identity Validation notifications."`
+}
+
+// IsPermitted returns true if the domain of the email address is permitted to receive the identity verification
+// notifications. Each configured domain matches itself and all of its subdomains, the deny list takes precedence over
+// the allow list, and when the allow list is configured the domain must match one of its domains.
+func (d *IdentityValidationEmailDomains) IsPermitted(address string) bool {
+	if len(d.Allow) == 0 && len(d.Deny) == 0 {
+		return true
+	}
+
+	i := strings.LastIndex(address, "@")
+	if i == -1 {
+		return false
+	}
+
+	domain := strings.ToLower(strings.TrimSuffix(address[i+1:], "."))
+
+	for _, deny := range d.Deny {
+		if isEmailDomainMatch(domain, deny) {
+			return false
+		}
+	}
+
+	if len(d.Allow) == 0 {
+		return true
+	}
+
+	for _, allow := range d.Allow {
+		if isEmailDomainMatch(domain, allow) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEmailDomainMatch(domain, match string) bool {
+	return domain == match || strings.HasSuffix(domain, "."+match)
 }
 
 // IdentityValidationResetPassword represents the tunable aspects of the reset password identity verification action/flow.
 type IdentityValidationResetPassword struct {
 	JWTExpiration time.Duration `koanf:"jwt_lifespan" json:"jwt_lifespan" jsonschema:"title=JWT Lifespan,default=5 minutes" jsonschema_description:"The lifespan of the JSON Web Token after it's initially generated after which it's considered invalid."`
-	JWTAlgorithm  string        `koanf:"jwt_algorithm" json:"jwt_algorithm" jsonschema:"title=JWT Algorithm,default=HS256,enum=HS256,enum=HS384,enum=HS512" jsonschema_description:"The JSON Web Token Algorithm (JWA) used to sign the Reset Password flow JSON Web Token's."`
-	JWTSecret     string        `koanf:"jwt_secret" json:"jwt_secret" jsonschema:"title=JWT Secret" jsonschema_description:"The secret key used to sign the Reset Password flow JSON Web Token's."`
+	JWTAlgorithm  string        `koanf:"jwt_algorithm" json:"jwt_algorithm" jsonschema:"title=JWT Algorithm,default=HS256,enum=HS256,enum=HS384,enum=HS512,enum=RS256,enum=RS384,enum=RS512,enum=ES256,enum=ES384,enum=ES512,enum=PS256,enum=PS384,enum=PS512" jsonschema_description:"The JSON Web Token Algorithm (JWA) used to sign the Reset Password flow JSON Web Token's."`
+	JWTSecret     string        `koanf:"jwt_secret" json:"jwt_secret" jsonschema:"title=JWT Secret" jsonschema_description:"The secret key used to sign the Reset Password flow JSON Web Token's when the algorithm is a HMAC algorithm."`
+	JWTKeyID      string        `koanf:"jwt_key_id" json:"jwt_key_id" jsonschema:"title=JWT Key ID" jsonschema_description:"The Key ID of the OpenID Connect 1.0 issuer JSON Web Key used to sign the Reset Password flow JSON Web Token's when the algorithm is not a HMAC algorithm."`
+
+	BindRemoteIP      bool                        `koanf:"bind_remote_ip" json:"bind_remote_ip" jsonschema:"title=Bind Remote IP,default=false" jsonschema_description:"Requires the JSON Web Token is used from the same remote IP which requested it."`
+	BindSession       bool                        `koanf:"bind_session" json:"bind_session" jsonschema:"title=Bind Session,default=false" jsonschema_description:"Requires the JSON Web Token is used from the same session which requested it."`
+	IssuanceRateLimit IdentityValidationRateLimit `koanf:"issuance_rate_limit" json:"issuance_rate_limit" jsonschema:"title=Issuance Rate Limit" jsonschema_description:"The limit of JSON Web Token's issued to each user within a period."`
+}
+
+// IdentityValidationRateLimit represents the limit of identity verifications issued to each user within a period.
+type IdentityValidationRateLimit struct {
+	Disable  bool          `koanf:"disable" json:"disable" jsonschema:"title=Disable,default=false" jsonschema_description:"Disables the issuance rate limit."`
+	Requests int           `koanf:"requests" json:"requests" jsonschema:"title=Requests,default=5,minimum=1" jsonschema_description:"The number of identity verifications which can be issued to each user within the period."`
+	Period   time.Duration `koanf:"period" json:"period" jsonschema:"title=Period,default=1 hour" jsonschema_description:"The period the number of issued identity verifications is counted within."`
 }
 
 // IdentityValidationElevatedSession represents the tunable aspects of the credential control identity verification action/flow.
@@ -22,6 +81,8 @@ type IdentityValidationElevatedSession struct {
 	CodeLifespan        time.Duration `koanf:"code_lifespan" json:"code_lifespan" jsonschema:"title=Code Lifespan,default=5 minutes" jsonschema_description:"The lifespan of the randomly generated One Time Code after which it's considered invalid."`
 	ElevationLifespan   time.Duration `koanf:"elevation_lifespan" json:"elevation_lifespan" jsonschema:"title=Elevation Lifespan,default=10 minutes" jsonschema_description:"The lifespan of the elevation after initially validating the One-Time Code before it expires."`
 	Characters          int           `koanf:"characters" json:"otp_characters" jsonschema:"title=OTP Characters,minimum=6,maximum=12,default=8" jsonschema_description:"Number of characters in the generated OTP codes."`
+	CharacterSet        string        `koanf:"character_set" json:"character_set" jsonschema:"title=Character Set,default=unambiguous,enum=unambiguous,enum=alphanumeric,enum=numeric" jsonschema_description:"The set of characters the generated OTP codes consist of."`
+	Delivery            string        `koanf:"delivery" json:"delivery" jsonschema:"title=Delivery,default=email,enum=email" jsonschema_description:"The channel used to deliver the generated OTP codes to the user."`
 	RequireSecondFactor bool          `koanf:"require_second_factor" json:"require_second_factor" jsonschema:"title=Require Second Factor,default=false" jsonschema_description:"Requires the user use a second factor if they have any known second factor methods."`
 	SkipSecondFactor    bool          `koanf:"skip_second_factor" json:"skip_second_factor" jsonschema:"title=Skip Second Factor,default=false" jsonschema_description:"Skips the primary identity verification process if the user has authenticated with a second factor."`
 }
@@ -31,10 +92,16 @@ var DefaultIdentityValidation = IdentityValidation{
 	ResetPassword: IdentityValidationResetPassword{
 		JWTExpiration: time.Minute * 5,
 		JWTAlgorithm:  "HS256",
+		IssuanceRateLimit: IdentityValidationRateLimit{
+			Requests: 5,
+			Period:   time.Hour,
+		},
 	},
 	ElevatedSession: IdentityValidationElevatedSession{
 		CodeLifespan:      time.Minute * 5,
 		ElevationLifespan: time.Minute * 10,
 		Characters:        8,
+		CharacterSet:      OneTimeCodeCharacterSetUnambiguous,
+		Delivery:          OneTimeCodeDeliveryEmail,
 	},
 }
@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityValidationEmailDomainsIsPermitted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     IdentityValidationEmailDomains
+		address  string
+		expected bool
+	}{
+		{"ShouldPermitWithoutPolicy", IdentityValidationEmailDomains{}, "john@example.com", true},
+		{"ShouldPermitWithoutPolicyOrAddress", IdentityValidationEmailDomains{}, "", true},
+		{"ShouldPermitAllowed", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "john@example.com", true},
+		{"ShouldPermitAllowedSubdomain", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "john@mail.example.com", true},
+		{"ShouldPermitAllowedMixedCase", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "john@EXAMPLE.com", true},
+		{"ShouldNotPermitNotAllowed", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "john@example.org", false},
+		{"ShouldNotPermitSuffixWithoutPeriod", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "john@badexample.com", false},
+		{"ShouldNotPermitWithoutAddress", IdentityValidationEmailDomains{Allow: []string{"example.com"}}, "", false},
+		{"ShouldNotPermitDenied", IdentityValidationEmailDomains{Deny: []string{"example.org"}}, "john@example.org", false},
+		{"ShouldPermitNotDenied", IdentityValidationEmailDomains{Deny: []string{"example.org"}}, "john@example.com", true},
+		{"ShouldNotPermitDeniedSubdomainOfAllowed", IdentityValidationEmailDomains{Allow: []string{"example.com"}, Deny: []string{"partner.example.com"}}, "john@partner.example.com", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.have.IsPermitted(tc.address))
+		})
+	}
+}
@@ -14,7 +14,32 @@ var Keys = []string{
 	"log.level",
 	"log.format",
 	"log.file_path",
+	"log.file_format",
 	"log.keep_stdout",
+	"log.modules.storage",
+	"log.modules.ldap",
+	"log.modules.oidc",
+	"log.modules.session",
+	"log.redaction.usernames",
+	"log.redaction.ip_addresses",
+	"log.redaction.emails",
+	"log.redaction.ipv4_prefix_length",
+	"log.redaction.ipv6_prefix_length",
+	"log.syslog.enabled",
+	"log.syslog.address",
+	"log.syslog.level",
+	"log.syslog.format",
+	"log.syslog.facility",
+	"log.syslog.tag",
+	"log.syslog.timeout",
+	"log.syslog.tls.minimum_version",
+	"log.syslog.tls.maximum_version",
+	"log.syslog.tls.skip_verify",
+	"log.syslog.tls.server_name",
+	"log.syslog.tls.private_key",
+	"log.syslog.tls.certificate_chain",
+	"log.journald.enabled",
+	"log.journald.level",
 	"identity_providers.oidc.hmac_secret",
 	"identity_providers.oidc.jwks",
 	"identity_providers.oidc.jwks[].key_id",
@@ -33,6 +58,15 @@ var Keys = []string{
 	"identity_providers.oidc.cors.endpoints",
 	"identity_providers.oidc.cors.allowed_origins",
 	"identity_providers.oidc.cors.allowed_origins_from_client_redirect_uris",
+	"identity_providers.oidc.dynamic_client_registration.enabled",
+	"identity_providers.oidc.dynamic_client_registration.initial_access_tokens",
+	"identity_providers.oidc.dynamic_client_registration.open_registration",
+	"identity_providers.oidc.dynamic_client_registration.authorization_policy",
+	"identity_providers.oidc.dynamic_client_registration.scopes",
+	"identity_providers.oidc.dynamic_client_registration.grant_types",
+	"identity_providers.oidc.backchannel_authentication.enabled",
+	"identity_providers.oidc.backchannel_authentication.lifespan",
+	"identity_providers.oidc.backchannel_authentication.polling_interval",
 	"identity_providers.oidc.clients",
 	"identity_providers.oidc.clients[].client_id",
 	"identity_providers.oidc.clients[].client_name",
@@ -48,10 +82,12 @@ var Keys = []string{
 	"identity_providers.oidc.clients[].response_modes",
 	"identity_providers.oidc.clients[].authorization_policy",
 	"identity_providers.oidc.clients[].lifespan",
+	"identity_providers.oidc.clients[].webauthn_user_verification",
 	"identity_providers.oidc.clients[].requested_audience_mode",
 	"identity_providers.oidc.clients[].consent_mode",
 	"identity_providers.oidc.clients[].pre_configured_consent_duration",
 	"identity_providers.oidc.clients[].require_pushed_authorization_requests",
+	"identity_providers.oidc.clients[].dpop_bound_access_tokens",
 	"identity_providers.oidc.clients[].require_pkce",
 	"identity_providers.oidc.clients[].pkce_challenge_method",
 	"identity_providers.oidc.clients[].authorization_signed_response_alg",
@@ -75,6 +111,8 @@ var Keys = []string{
 	"identity_providers.oidc.clients[].jwks[].algorithm",
 	"identity_providers.oidc.clients[].jwks[].key",
 	"identity_providers.oidc.clients[].jwks[].certificate_chain",
+	"identity_providers.oidc.clients[].backchannel_token_delivery_mode",
+	"identity_providers.oidc.clients[].backchannel_client_notification_endpoint",
 	"identity_providers.oidc.clients[]",
 	"identity_providers.oidc.authorization_policies",
 	"identity_providers.oidc.authorization_policies.*.default_policy",
@@ -86,6 +124,7 @@ var Keys = []string{
 	"identity_providers.oidc.lifespans.id_token",
 	"identity_providers.oidc.lifespans.refresh_token",
 	"identity_providers.oidc.lifespans.jwt_secured_authorization",
+	"identity_providers.oidc.lifespans.device_code",
 	"identity_providers.oidc.lifespans.custom",
 	"identity_providers.oidc.lifespans.custom.*.access_token",
 	"identity_providers.oidc.lifespans.custom.*.authorize_code",
@@ -111,12 +150,43 @@ var Keys = []string{
 	"identity_providers.oidc.lifespans.custom.*.grants.jwt_bearer.authorize_code",
 	"identity_providers.oidc.lifespans.custom.*.grants.jwt_bearer.id_token",
 	"identity_providers.oidc.lifespans.custom.*.grants.jwt_bearer.refresh_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.device_code.access_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.device_code.authorize_code",
+	"identity_providers.oidc.lifespans.custom.*.grants.device_code.id_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.device_code.refresh_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.token_exchange.access_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.token_exchange.authorize_code",
+	"identity_providers.oidc.lifespans.custom.*.grants.token_exchange.id_token",
+	"identity_providers.oidc.lifespans.custom.*.grants.token_exchange.refresh_token",
 	"identity_providers.oidc",
 	"identity_providers.oidc.issuer_certificate_chain",
 	"identity_providers.oidc.issuer_private_key",
+	"identity_providers.saml",
+	"identity_providers.saml.entity_id",
+	"identity_providers.saml.certificate_chain",
+	"identity_providers.saml.private_key",
+	"identity_providers.saml.signature_algorithm",
+	"identity_providers.saml.assertion_lifespan",
+	"identity_providers.saml.session_lifespan",
+	"identity_providers.saml.service_providers",
+	"identity_providers.saml.service_providers[].entity_id",
+	"identity_providers.saml.service_providers[].name",
+	"identity_providers.saml.service_providers[].metadata",
+	"identity_providers.saml.service_providers[].assertion_consumer_service_urls",
+	"identity_providers.saml.service_providers[].single_logout_service_url",
+	"identity_providers.saml.service_providers[].certificate_chain",
+	"identity_providers.saml.service_providers[].name_id_format",
+	"identity_providers.saml.service_providers[].authorization_policy",
+	"identity_providers.saml.service_providers[].sign_response",
 	"authentication_backend.password_reset.disable",
 	"authentication_backend.password_reset.custom_url",
 	"authentication_backend.refresh_interval",
+	"authentication_backend.spnego.enabled",
+	"authentication_backend.spnego.keytab",
+	"authentication_backend.spnego.service_principal",
+	"authentication_backend.spnego.realms",
+	"authentication_backend.spnego.username_format",
+	"authentication_backend.spnego.max_clock_skew",
 	"authentication_backend.file.path",
 	"authentication_backend.file.watch",
 	"authentication_backend.file.password.algorithm",
@@ -139,6 +209,11 @@ var Keys = []string{
 	"authentication_backend.file.password.scrypt.parallelism",
 	"authentication_backend.file.password.scrypt.key_length",
 	"authentication_backend.file.password.scrypt.salt_length",
+	"authentication_backend.file.password.balloon.variant",
+	"authentication_backend.file.password.balloon.space_cost",
+	"authentication_backend.file.password.balloon.time_cost",
+	"authentication_backend.file.password.balloon.delta",
+	"authentication_backend.file.password.balloon.salt_length",
 	"authentication_backend.file.password.iterations",
 	"authentication_backend.file.password.memory",
 	"authentication_backend.file.password.parallelism",
@@ -156,6 +231,10 @@ var Keys = []string{
 	"authentication_backend.ldap.tls.server_name",
 	"authentication_backend.ldap.tls.private_key",
 	"authentication_backend.ldap.tls.certificate_chain",
+	"authentication_backend.ldap.pooling.enable",
+	"authentication_backend.ldap.pooling.count",
+	"authentication_backend.ldap.pooling.retries",
+	"authentication_backend.ldap.pooling.timeout",
 	"authentication_backend.ldap.base_dn",
 	"authentication_backend.ldap.additional_users_dn",
 	"authentication_backend.ldap.users_filter",
@@ -173,6 +252,69 @@ var Keys = []string{
 	"authentication_backend.ldap.permit_feature_detection_failure",
 	"authentication_backend.ldap.user",
 	"authentication_backend.ldap.password",
+	"authentication_backend.sql.password.algorithm",
+	"authentication_backend.sql.password.argon2.variant",
+	"authentication_backend.sql.password.argon2.iterations",
+	"authentication_backend.sql.password.argon2.memory",
+	"authentication_backend.sql.password.argon2.parallelism",
+	"authentication_backend.sql.password.argon2.key_length",
+	"authentication_backend.sql.password.argon2.salt_length",
+	"authentication_backend.sql.password.sha2crypt.variant",
+	"authentication_backend.sql.password.sha2crypt.iterations",
+	"authentication_backend.sql.password.sha2crypt.salt_length",
+	"authentication_backend.sql.password.pbkdf2.variant",
+	"authentication_backend.sql.password.pbkdf2.iterations",
+	"authentication_backend.sql.password.pbkdf2.salt_length",
+	"authentication_backend.sql.password.bcrypt.variant",
+	"authentication_backend.sql.password.bcrypt.cost",
+	"authentication_backend.sql.password.scrypt.iterations",
+	"authentication_backend.sql.password.scrypt.block_size",
+	"authentication_backend.sql.password.scrypt.parallelism",
+	"authentication_backend.sql.password.scrypt.key_length",
+	"authentication_backend.sql.password.scrypt.salt_length",
+	"authentication_backend.sql.password.balloon.variant",
+	"authentication_backend.sql.password.balloon.space_cost",
+	"authentication_backend.sql.password.balloon.time_cost",
+	"authentication_backend.sql.password.balloon.delta",
+	"authentication_backend.sql.password.balloon.salt_length",
+	"authentication_backend.sql.password.iterations",
+	"authentication_backend.sql.password.memory",
+	"authentication_backend.sql.password.parallelism",
+	"authentication_backend.sql.password.key_length",
+	"authentication_backend.sql.password.salt_length",
+	"authentication_backend.ldap_backends[].name",
+	"authentication_backend.ldap_backends[].domains",
+	"authentication_backend.ldap_backends[].address",
+	"authentication_backend.ldap_backends[].implementation",
+	"authentication_backend.ldap_backends[].timeout",
+	"authentication_backend.ldap_backends[].start_tls",
+	"authentication_backend.ldap_backends[].tls.minimum_version",
+	"authentication_backend.ldap_backends[].tls.maximum_version",
+	"authentication_backend.ldap_backends[].tls.skip_verify",
+	"authentication_backend.ldap_backends[].tls.server_name",
+	"authentication_backend.ldap_backends[].tls.private_key",
+	"authentication_backend.ldap_backends[].tls.certificate_chain",
+	"authentication_backend.ldap_backends[].pooling.enable",
+	"authentication_backend.ldap_backends[].pooling.count",
+	"authentication_backend.ldap_backends[].pooling.retries",
+	"authentication_backend.ldap_backends[].pooling.timeout",
+	"authentication_backend.ldap_backends[].base_dn",
+	"authentication_backend.ldap_backends[].additional_users_dn",
+	"authentication_backend.ldap_backends[].users_filter",
+	"authentication_backend.ldap_backends[].additional_groups_dn",
+	"authentication_backend.ldap_backends[].groups_filter",
+	"authentication_backend.ldap_backends[].group_search_mode",
+	"authentication_backend.ldap_backends[].attributes.distinguished_name",
+	"authentication_backend.ldap_backends[].attributes.username",
+	"authentication_backend.ldap_backends[].attributes.display_name",
+	"authentication_backend.ldap_backends[].attributes.mail",
+	"authentication_backend.ldap_backends[].attributes.member_of",
+	"authentication_backend.ldap_backends[].attributes.group_name",
+	"authentication_backend.ldap_backends[].permit_referrals",
+	"authentication_backend.ldap_backends[].permit_unauthenticated_bind",
+	"authentication_backend.ldap_backends[].permit_feature_detection_failure",
+	"authentication_backend.ldap_backends[].user",
+	"authentication_backend.ldap_backends[].password",
 	"session.name",
 	"session.same_site",
 	"session.expiration",
@@ -180,6 +322,8 @@ var Keys = []string{
 	"session.remember_me",
 	"session",
 	"session.secret",
+	"session.storage",
+	"session.enable_user_sessions",
 	"session.cookies",
 	"session.cookies[].name",
 	"session.cookies[].same_site",
@@ -190,6 +334,10 @@ var Keys = []string{
 	"session.cookies[].domain",
 	"session.cookies[].authelia_url",
 	"session.cookies[].default_redirection_url",
+	"session.cookies[].branding.title",
+	"session.cookies[].branding.logo_url",
+	"session.cookies[].branding.announcement",
+	"session.cookies[].branding.support_contact",
 	"session.cookies[]",
 	"session.redis.host",
 	"session.redis.port",
@@ -212,6 +360,12 @@ var Keys = []string{
 	"session.redis.high_availability.nodes",
 	"session.redis.high_availability.nodes[].host",
 	"session.redis.high_availability.nodes[].port",
+	"session.redis.cluster.route_by_latency",
+	"session.redis.cluster.route_randomly",
+	"session.redis.cluster.maximum_redirects",
+	"session.redis.cluster.nodes",
+	"session.redis.cluster.nodes[].host",
+	"session.redis.cluster.nodes[].port",
 	"session.domain",
 	"totp.disable",
 	"totp.issuer",
@@ -224,11 +378,115 @@ var Keys = []string{
 	"totp.allowed_digits",
 	"totp.allowed_periods",
 	"totp.disable_reuse_security_policy",
+	"totp.drift_compensation.enable",
+	"totp.drift_compensation.maximum",
 	"duo_api.disable",
 	"duo_api.hostname",
 	"duo_api.integration_key",
 	"duo_api.secret_key",
 	"duo_api.enable_self_enrollment",
+	"push.disable",
+	"push.provider",
+	"push.timeout",
+	"push.privacyidea",
+	"push.privacyidea.url",
+	"push.privacyidea.realm",
+	"push.privacyidea.token",
+	"push.privacyidea.tls.minimum_version",
+	"push.privacyidea.tls.maximum_version",
+	"push.privacyidea.tls.skip_verify",
+	"push.privacyidea.tls.server_name",
+	"push.privacyidea.tls.private_key",
+	"push.privacyidea.tls.certificate_chain",
+	"push.webhook",
+	"push.webhook.url",
+	"push.webhook.token",
+	"push.webhook.tls.minimum_version",
+	"push.webhook.tls.maximum_version",
+	"push.webhook.tls.skip_verify",
+	"push.webhook.tls.server_name",
+	"push.webhook.tls.private_key",
+	"push.webhook.tls.certificate_chain",
+	"push.mobile",
+	"push.mobile.url",
+	"push.mobile.token",
+	"push.mobile.tls.minimum_version",
+	"push.mobile.tls.maximum_version",
+	"push.mobile.tls.skip_verify",
+	"push.mobile.tls.server_name",
+	"push.mobile.tls.private_key",
+	"push.mobile.tls.certificate_chain",
+	"push.ntfy",
+	"push.ntfy.url",
+	"push.ntfy.topic",
+	"push.ntfy.token",
+	"push.ntfy.tls.minimum_version",
+	"push.ntfy.tls.maximum_version",
+	"push.ntfy.tls.skip_verify",
+	"push.ntfy.tls.server_name",
+	"push.ntfy.tls.private_key",
+	"push.ntfy.tls.certificate_chain",
+	"push.gotify",
+	"push.gotify.url",
+	"push.gotify.tokens",
+	"push.gotify.tls.minimum_version",
+	"push.gotify.tls.maximum_version",
+	"push.gotify.tls.skip_verify",
+	"push.gotify.tls.server_name",
+	"push.gotify.tls.private_key",
+	"push.gotify.tls.certificate_chain",
+	"sms.disable",
+	"sms.provider",
+	"sms.characters",
+	"sms.code_lifespan",
+	"sms.enable_voice",
+	"sms.rate_limit.disable",
+	"sms.rate_limit.requests",
+	"sms.rate_limit.period",
+	"sms.twilio",
+	"sms.twilio.url",
+	"sms.twilio.account_sid",
+	"sms.twilio.auth_token",
+	"sms.twilio.from",
+	"sms.twilio.timeout",
+	"sms.twilio.tls.minimum_version",
+	"sms.twilio.tls.maximum_version",
+	"sms.twilio.tls.skip_verify",
+	"sms.twilio.tls.server_name",
+	"sms.twilio.tls.private_key",
+	"sms.twilio.tls.certificate_chain",
+	"sms.vonage",
+	"sms.vonage.url",
+	"sms.vonage.voice_url",
+	"sms.vonage.api_key",
+	"sms.vonage.api_secret",
+	"sms.vonage.from",
+	"sms.vonage.application_id",
+	"sms.vonage.private_key",
+	"sms.vonage.timeout",
+	"sms.vonage.tls.minimum_version",
+	"sms.vonage.tls.maximum_version",
+	"sms.vonage.tls.skip_verify",
+	"sms.vonage.tls.server_name",
+	"sms.vonage.tls.private_key",
+	"sms.vonage.tls.certificate_chain",
+	"sms.http",
+	"sms.http.url",
+	"sms.http.token",
+	"sms.http.timeout",
+	"sms.http.tls.minimum_version",
+	"sms.http.tls.maximum_version",
+	"sms.http.tls.skip_verify",
+	"sms.http.tls.server_name",
+	"sms.http.tls.private_key",
+	"sms.http.tls.certificate_chain",
+	"email.enable",
+	"email.characters",
+	"email.character_set",
+	"email.code_lifespan",
+	"email.max_attempts",
+	"email.cooldown",
+	"email.enable_magic_link",
 	"access_control.default_policy",
 	"access_control.networks",
 	"access_control.networks[].name",
@@ -245,20 +503,56 @@ var Keys = []string{
 	"access_control.rules[].query[][].key",
 	"access_control.rules[].query[][].value",
 	"access_control.rules[].query",
+	"access_control.rules[].device",
+	"access_control.rules[].challenge",
+	"access_control.rules[].authn_strategies",
+	"access_control.rules[].authn_strategies[].name",
+	"access_control.rules[].authn_strategies[].schemes",
+	"access_control.rules[].webauthn_user_verification",
+	"access_control.kubernetes.enable",
+	"access_control.kubernetes.namespaces",
+	"access_control.kubernetes.bypass_namespaces",
+	"access_control.kubernetes.annotation_prefix",
+	"access_control.kubernetes.sync_interval",
+	"access_control.kubernetes.gateway_api",
+	"device_certificates.enabled",
+	"device_certificates.certificate_chain",
+	"device_certificates.private_key",
+	"device_certificates.lifespan",
+	"device_certificates.header",
 	"ntp.address",
+	"ntp.servers",
+	"ntp.quorum",
 	"ntp.version",
 	"ntp.max_desync",
 	"ntp.disable_startup_check",
 	"ntp.disable_failure",
+	"ntp.interval",
+	"ntp.disable_monitoring",
+	"leader_election.enabled",
+	"leader_election.backend",
+	"leader_election.lease_duration",
+	"leader_election.renew_interval",
+	"circuit_breaker.enabled",
+	"circuit_breaker.dependencies",
+	"circuit_breaker.failure_threshold",
+	"circuit_breaker.open_duration",
+	"circuit_breaker.probe_timeout",
 	"regulation.max_retries",
 	"regulation.find_time",
 	"regulation.ban_time",
+	"regulation.anomalies.window",
+	"regulation.anomalies.usernames_per_ip",
+	"regulation.anomalies.ips_per_username",
 	"storage.local.path",
 	"storage.mysql.address",
 	"storage.mysql.database",
 	"storage.mysql.username",
 	"storage.mysql.password",
 	"storage.mysql.timeout",
+	"storage.mysql.replicas",
+	"storage.mysql.iam_authentication.provider",
+	"storage.mysql.iam_authentication.region",
 	"storage.mysql.tls.minimum_version",
 	"storage.mysql.tls.maximum_version",
 	"storage.mysql.tls.skip_verify",
@@ -270,7 +564,10 @@ var Keys = []string{
 	"storage.postgres.username",
 	"storage.postgres.password",
 	"storage.postgres.timeout",
+	"storage.postgres.replicas",
 	"storage.postgres.schema",
+	"storage.postgres.iam_authentication.provider",
+	"storage.postgres.iam_authentication.region",
 	"storage.postgres.tls.minimum_version",
 	"storage.postgres.tls.maximum_version",
 	"storage.postgres.tls.skip_verify",
@@ -281,7 +578,25 @@ var Keys = []string{
 	"storage.postgres.ssl.root_certificate",
 	"storage.postgres.ssl.certificate",
 	"storage.postgres.ssl.key",
+	"storage.cockroachdb.address",
+	"storage.cockroachdb.database",
+	"storage.cockroachdb.username",
+	"storage.cockroachdb.password",
+	"storage.cockroachdb.timeout",
+	"storage.cockroachdb.replicas",
+	"storage.cockroachdb.schema",
+	"storage.cockroachdb.maximum_retries",
+	"storage.cockroachdb.tls.minimum_version",
+	"storage.cockroachdb.tls.maximum_version",
+	"storage.cockroachdb.tls.skip_verify",
+	"storage.cockroachdb.tls.server_name",
+	"storage.cockroachdb.tls.private_key",
+	"storage.cockroachdb.tls.certificate_chain",
 	"storage.encryption_key",
+	"storage.previous_encryption_keys",
+	"storage.cleanup.disable",
+	"storage.cleanup.interval",
+	"storage.cleanup.batch_size",
 	"notifier.disable_startup_check",
 	"notifier.filesystem.filename",
 	"notifier.smtp.address",
@@ -303,14 +618,48 @@ var Keys = []string{
 	"notifier.smtp.tls.certificate_chain",
 	"notifier.smtp.host",
 	"notifier.smtp.port",
+	"notifier.ses.region",
+	"notifier.ses.endpoint",
+	"notifier.ses.timeout",
+	"notifier.ses.sender",
+	"notifier.ses.subject",
+	"notifier.ses.configuration_set",
+	"notifier.ses.disable_html_emails",
 	"notifier.template_path",
 	"server.address",
 	"server.asset_path",
+	"server.asset_watch",
 	"server.disable_healthcheck",
 	"server.tls.certificate",
 	"server.tls.key",
 	"server.tls.client_certificates",
 	"server.headers.csp_template",
+	"server.headers.strict_transport_security.max_age",
+	"server.headers.strict_transport_security.include_subdomains",
+	"server.headers.strict_transport_security.preload",
+	"server.headers.permissions_policy",
+	"server.headers.referrer_policy",
+	"server.headers.frame_options",
+	"server.headers.groups.portal.content_security_policy",
+	"server.headers.groups.portal.content_security_policy_report_only",
+	"server.headers.groups.portal.cross_origin_opener_policy",
+	"server.headers.groups.portal.cross_origin_embedder_policy",
+	"server.headers.groups.portal.cross_origin_resource_policy",
+	"server.headers.groups.api.content_security_policy",
+	"server.headers.groups.api.content_security_policy_report_only",
+	"server.headers.groups.api.cross_origin_opener_policy",
+	"server.headers.groups.api.cross_origin_embedder_policy",
+	"server.headers.groups.api.cross_origin_resource_policy",
+	"server.headers.groups.openid_connect.content_security_policy",
+	"server.headers.groups.openid_connect.content_security_policy_report_only",
+	"server.headers.groups.openid_connect.cross_origin_opener_policy",
+	"server.headers.groups.openid_connect.cross_origin_embedder_policy",
+	"server.headers.groups.openid_connect.cross_origin_resource_policy",
+	"server.headers.groups.openapi.content_security_policy",
+	"server.headers.groups.openapi.content_security_policy_report_only",
+	"server.headers.groups.openapi.cross_origin_opener_policy",
+	"server.headers.groups.openapi.cross_origin_embedder_policy",
+	"server.headers.groups.openapi.cross_origin_resource_policy",
 	"server.endpoints.enable_pprof",
 	"server.endpoints.enable_expvars",
 	"server.endpoints.authz",
@@ -318,11 +667,55 @@ var Keys = []string{
 	"server.endpoints.authz.*.authn_strategies",
 	"server.endpoints.authz.*.authn_strategies[].name",
 	"server.endpoints.authz.*.authn_strategies[].schemes",
+	"server.endpoints.health.cache_duration",
+	"server.endpoints.health.timeout",
+	"server.endpoints.health.admin_groups",
+	"server.endpoints.scim.enabled",
+	"server.endpoints.scim.token",
+	"server.endpoints.mobile.enabled",
+	"server.endpoints.mobile.access_token_lifespan",
+	"server.endpoints.mobile.refresh_token_lifespan",
 	"server.buffers.read",
 	"server.buffers.write",
 	"server.timeouts.read",
 	"server.timeouts.write",
 	"server.timeouts.idle",
+	"server.limits.max_request_body_size",
+	"server.limits.max_connections",
+	"server.limits.max_connections_per_ip",
+	"server.limits.max_requests_per_connection",
+	"server.keepalive.disable",
+	"server.keepalive.tcp_period",
+	"server.h2c.enable",
+	"server.h2c.max_concurrent_streams",
+	"server.h2c.max_read_frame_size",
+	"server.scanner_filter.enable",
+	"server.scanner_filter.delay",
+	"server.scanner_filter.user_agents",
+	"server.scanner_filter.paths",
+	"server.scanner_filter.allowed_networks",
+	"server.admin.enabled",
+	"server.admin.address",
+	"server.admin.token",
+	"server.admin.enable_pprof",
+	"server.admin.enable_events",
+	"server.admin.enable_api",
+	"server.admin.buffers.read",
+	"server.admin.buffers.write",
+	"server.admin.timeouts.read",
+	"server.admin.timeouts.write",
+	"server.admin.timeouts.idle",
+	"server.ldap.enabled",
+	"server.ldap.address",
+	"server.ldap.base_dn",
+	"server.ldap.search_groups",
+	"server.ldap.size_limit",
+	"server.ldap.idle_timeout",
+	"server.ldap.tls.certificate",
+	"server.ldap.tls.key",
+	"server.ldap.tls.client_certificates",
+	"server.headless.enabled",
+	"server.headless.allowed_origins",
 	"telemetry.metrics.enabled",
 	"telemetry.metrics.address",
 	"telemetry.metrics.buffers.read",
@@ -330,11 +723,49 @@ var Keys = []string{
 	"telemetry.metrics.timeouts.read",
 	"telemetry.metrics.timeouts.write",
 	"telemetry.metrics.timeouts.idle",
+	"telemetry.metrics.statsd.enabled",
+	"telemetry.metrics.statsd.address",
+	"telemetry.metrics.statsd.flavor",
+	"telemetry.metrics.statsd.prefix",
+	"telemetry.metrics.statsd.tags",
+	"telemetry.metrics.statsd.interval",
+	"telemetry.metrics.otlp.enabled",
+	"telemetry.metrics.otlp.endpoint",
+	"telemetry.metrics.otlp.headers",
+	"telemetry.metrics.otlp.attributes",
+	"telemetry.metrics.otlp.interval",
+	"telemetry.metrics.otlp.timeout",
+	"telemetry.metrics.otlp.tls.minimum_version",
+	"telemetry.metrics.otlp.tls.maximum_version",
+	"telemetry.metrics.otlp.tls.skip_verify",
+	"telemetry.metrics.otlp.tls.server_name",
+	"telemetry.metrics.otlp.tls.private_key",
+	"telemetry.metrics.otlp.tls.certificate_chain",
+	"telemetry.usage.enabled",
+	"telemetry.usage.endpoint",
+	"telemetry.usage.interval",
+	"telemetry.usage.period",
+	"telemetry.usage.timeout",
+	"telemetry.usage.tls.minimum_version",
+	"telemetry.usage.tls.maximum_version",
+	"telemetry.usage.tls.skip_verify",
+	"telemetry.usage.tls.server_name",
+	"telemetry.usage.tls.private_key",
+	"telemetry.usage.tls.certificate_chain",
 	"webauthn.disable",
 	"webauthn.display_name",
 	"webauthn.attestation_conveyance_preference",
 	"webauthn.user_verification",
 	"webauthn.timeout",
+	"webauthn.filtering.prohibit_backup_eligibility",
+	"webauthn.filtering.group_policies",
+	"webauthn.filtering.group_policies[].groups",
+	"webauthn.filtering.group_policies[].prohibit_backup_eligibility",
+	"webauthn.selection_criteria.attachment",
+	"webauthn.selection_criteria.discoverability",
+	"webauthn.passkey_login.enable",
+	"webauthn.passkey_login.user_verification",
+	"webauthn.passkey_login.two_factor_with_user_verification",
 	"password_policy.standard.enabled",
 	"password_policy.standard.min_length",
 	"password_policy.standard.max_length",
@@ -344,16 +775,77 @@ var Keys = []string{
 	"password_policy.standard.require_special",
 	"password_policy.zxcvbn.enabled",
 	"password_policy.zxcvbn.min_score",
+	"password_policy.zxcvbn.min_entropy",
+	"password_policy.banned_words",
+	"password_policy.group_policies",
+	"password_policy.group_policies[].groups",
+	"password_policy.group_policies[].standard.enabled",
+	"password_policy.group_policies[].standard.min_length",
+	"password_policy.group_policies[].standard.max_length",
+	"password_policy.group_policies[].standard.require_uppercase",
+	"password_policy.group_policies[].standard.require_lowercase",
+	"password_policy.group_policies[].standard.require_number",
+	"password_policy.group_policies[].standard.require_special",
+	"password_policy.group_policies[].zxcvbn.enabled",
+	"password_policy.group_policies[].zxcvbn.min_score",
+	"password_policy.group_policies[].zxcvbn.min_entropy",
 	"privacy_policy.enabled",
 	"privacy_policy.require_user_acceptance",
 	"privacy_policy.policy_url",
 	"identity_validation.reset_password.jwt_lifespan",
 	"identity_validation.reset_password.jwt_algorithm",
 	"identity_validation.reset_password.jwt_secret",
+	"identity_validation.reset_password.jwt_key_id",
+	"identity_validation.reset_password.bind_remote_ip",
+	"identity_validation.reset_password.bind_session",
+	"identity_validation.reset_password.issuance_rate_limit.disable",
+	"identity_validation.reset_password.issuance_rate_limit.requests",
+	"identity_validation.reset_password.issuance_rate_limit.period",
 	"identity_validation.elevated_session.code_lifespan",
 	"identity_validation.elevated_session.elevation_lifespan",
 	"identity_validation.elevated_session.characters",
+	"identity_validation.elevated_session.character_set",
+	"identity_validation.elevated_session.delivery",
 	"identity_validation.elevated_session.require_second_factor",
 	"identity_validation.elevated_session.skip_second_factor",
+	"identity_validation.email_domains.allow",
+	"identity_validation.email_domains.deny",
+	"audit.enabled",
+	"audit.buffer_size",
+	"audit.sinks",
+	"audit.sinks[].name",
+	"audit.sinks[].events",
+	"audit.sinks[].exclude_events",
+	"audit.sinks[].file",
+	"audit.sinks[].file.path",
+	"audit.sinks[].syslog",
+	"audit.sinks[].syslog.address",
+	"audit.sinks[].syslog.facility",
+	"audit.sinks[].syslog.tag",
+	"audit.sinks[].webhook",
+	"audit.sinks[].webhook.url",
+	"audit.sinks[].webhook.headers",
+	"audit.sinks[].webhook.timeout",
+	"audit.sinks[].webhook.tls.minimum_version",
+	"audit.sinks[].webhook.tls.maximum_version",
+	"audit.sinks[].webhook.tls.skip_verify",
+	"audit.sinks[].webhook.tls.server_name",
+	"audit.sinks[].webhook.tls.private_key",
+	"audit.sinks[].webhook.tls.certificate_chain",
+	"audit.sinks[].kafka",
+	"audit.sinks[].kafka.brokers",
+	"audit.sinks[].kafka.topic",
+	"audit.sinks[].kafka.timeout",
+	"audit.sinks[].kafka.tls.minimum_version",
+	"audit.sinks[].kafka.tls.maximum_version",
+	"audit.sinks[].kafka.tls.skip_verify",
+	"audit.sinks[].kafka.tls.server_name",
+	"audit.sinks[].kafka.tls.private_key",
+	"audit.sinks[].kafka.tls.certificate_chain",
+	"audit.sinks[].kafka.sasl.mechanism",
+	"audit.sinks[].kafka.sasl.username",
+	"audit.sinks[].kafka.sasl.password",
+	"audit.sinks[].storage",
+	"audit.sinks[].storage.retention",
 	"default_redirection_url",
 }
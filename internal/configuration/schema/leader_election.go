@@ -0,0 +1,20 @@
+package schema
+
+import (
+	"time"
+)
+
+// LeaderElection represents the configuration of the leader election between replicas.
+type LeaderElection struct {
+	Enabled       bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the leader election so the singleton background jobs only run on one replica."`
+	Backend       string        `koanf:"backend" json:"backend" jsonschema:"default=storage,enum=storage,enum=redis,title=Backend" jsonschema_description:"The backend used to hold the leader election leases."`
+	LeaseDuration time.Duration `koanf:"lease_duration" json:"lease_duration" jsonschema:"default=30 seconds,title=Lease Duration" jsonschema_description:"The duration a lease is held for before another replica can acquire it if it's not renewed."`
+	RenewInterval time.Duration `koanf:"renew_interval" json:"renew_interval" jsonschema:"default=10 seconds,title=Renew Interval" jsonschema_description:"The interval the leases are acquired or renewed at."`
+}
+
+// DefaultLeaderElectionConfiguration represents the default configuration parameters for the leader election.
+var DefaultLeaderElectionConfiguration = LeaderElection{
+	Backend:       LeaderElectionBackendStorage,
+	LeaseDuration: time.Second * 30,
+	RenewInterval: time.Second * 10,
+}
@@ -1,11 +1,58 @@
 package schema
 
+import (
+	"crypto/tls"
+	"time"
+)
+
 // Log represents the logging configuration.
 type Log struct {
 	Level      string `koanf:"level" json:"level" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=Level" jsonschema_description:"The minimum Level a Log message must be before it's added to the log."`
-	Format     string `koanf:"format" json:"format" jsonschema:"enum=json,enum=text,title=Format" jsonschema_description:"The Format of Log messages."`
+	Format     string `koanf:"format" json:"format" jsonschema:"enum=json,enum=text,enum=cef,enum=leef,enum=ecs,title=Format" jsonschema_description:"The Format of Log messages."`
 	FilePath   string `koanf:"file_path" json:"file_path" jsonschema:"title=File Path" jsonschema_description:"The File Path to save the logs to instead of sending them to stdout, it's strongly recommended this option is only enabled with 'keep_stdout' also enabled."`
+	FileFormat string `koanf:"file_format" json:"file_format" jsonschema:"enum=json,enum=text,enum=cef,enum=leef,enum=ecs,title=File Format" jsonschema_description:"The Format of Log messages written to the File Path, defaults to the Format."`
 	KeepStdout bool   `koanf:"keep_stdout" json:"keep_stdout" jsonschema:"default=false,title=Keep Stdout" jsonschema_description:"Enables keeping stdout when using the File Path option."`
+
+	Modules   LogModules   `koanf:"modules" json:"modules" jsonschema:"title=Modules" jsonschema_description:"The Level of the Log messages of the individual modules, each defaults to the Level."`
+	Redaction LogRedaction `koanf:"redaction" json:"redaction" jsonschema:"title=Redaction" jsonschema_description:"The redaction of the personal data in the Log messages and the audit events."`
+
+	Syslog   LogSyslog   `koanf:"syslog" json:"syslog" jsonschema:"title=Syslog" jsonschema_description:"The Syslog output configuration."`
+	Journald LogJournald `koanf:"journald" json:"journald" jsonschema:"title=Journald" jsonschema_description:"The Journald output configuration."`
+}
+
+// LogModules represents the log level configuration of the individual modules.
+type LogModules struct {
+	Storage       string `koanf:"storage" json:"storage" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=Storage" jsonschema_description:"The minimum Level a Log message of the storage module must be before it's added to the log."`
+	LDAP          string `koanf:"ldap" json:"ldap" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=LDAP" jsonschema_description:"The minimum Level a Log message of the LDAP module must be before it's added to the log."`
+	OpenIDConnect string `koanf:"oidc" json:"oidc" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=OpenID Connect 1.0" jsonschema_description:"The minimum Level a Log message of the OpenID Connect 1.0 module must be before it's added to the log."`
+	Session       string `koanf:"session" json:"session" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=Session" jsonschema_description:"The minimum Level a Log message of the session module must be before it's added to the log."`
+}
+
+// LogRedaction represents the configuration of the redaction of the personal data in the logs and the audit events.
+type LogRedaction struct {
+	Usernames        bool `koanf:"usernames" json:"usernames" jsonschema:"default=false,title=Usernames" jsonschema_description:"Replaces the usernames with a keyed hash of the username."`
+	IPAddresses      bool `koanf:"ip_addresses" json:"ip_addresses" jsonschema:"default=false,title=IP Addresses" jsonschema_description:"Truncates the IP addresses to the prefix lengths."`
+	Emails           bool `koanf:"emails" json:"emails" jsonschema:"default=false,title=Emails" jsonschema_description:"Masks the local part of the email addresses."`
+	IPv4PrefixLength int  `koanf:"ipv4_prefix_length" json:"ipv4_prefix_length" jsonschema:"default=24,minimum=0,maximum=32,title=IPv4 Prefix Length" jsonschema_description:"The prefix length the IPv4 addresses are truncated to."`
+	IPv6PrefixLength int  `koanf:"ipv6_prefix_length" json:"ipv6_prefix_length" jsonschema:"default=48,minimum=0,maximum=128,title=IPv6 Prefix Length" jsonschema_description:"The prefix length the IPv6 addresses are truncated to."`
+}
+
+// LogSyslog represents the configuration of the output which sends the logs to a syslog server.
+type LogSyslog struct {
+	Enabled  bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables sending the logs to a syslog server."`
+	Address  *AddressTCP   `koanf:"address" json:"address" jsonschema:"title=Address" jsonschema_description:"The address of the syslog server."`
+	Level    string        `koanf:"level" json:"level" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=Level" jsonschema_description:"The minimum Level a Log message must be before it's sent to the syslog server, defaults to the Level."`
+	Format   string        `koanf:"format" json:"format" jsonschema:"enum=json,enum=text,enum=cef,enum=leef,enum=ecs,title=Format" jsonschema_description:"The Format of the content of the syslog messages, defaults to the Format."`
+	Facility string        `koanf:"facility" json:"facility" jsonschema:"default=daemon,enum=kern,enum=user,enum=mail,enum=daemon,enum=auth,enum=syslog,enum=lpr,enum=news,enum=uucp,enum=cron,enum=authpriv,enum=ftp,enum=local0,enum=local1,enum=local2,enum=local3,enum=local4,enum=local5,enum=local6,enum=local7,title=Facility" jsonschema_description:"The syslog facility of the messages."`
+	Tag      string        `koanf:"tag" json:"tag" jsonschema:"default=authelia,title=Tag" jsonschema_description:"The app name of the messages."`
+	Timeout  time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=5 seconds,title=Timeout" jsonschema_description:"The timeout of connecting and writing to the syslog server."`
+	TLS      *TLS          `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The syslog server TLS connection properties, the connection is only secured with TLS if configured."`
+}
+
+// LogJournald represents the configuration of the output which sends the logs to the systemd journal.
+type LogJournald struct {
+	Enabled bool   `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables sending the logs to the systemd journal."`
+	Level   string `koanf:"level" json:"level" jsonschema:"enum=error,enum=warn,enum=info,enum=debug,enum=trace,title=Level" jsonschema_description:"The minimum Level a Log message must be before it's sent to the systemd journal, defaults to the Level."`
 }
 
 // DefaultLoggingConfiguration is the default logging configuration.
@@ -13,3 +60,19 @@ var DefaultLoggingConfiguration = Log{
 	Level:  "info",
 	Format: "text",
 }
+
+// DefaultLogRedactionConfiguration is the default log redaction configuration.
+var DefaultLogRedactionConfiguration = LogRedaction{
+	IPv4PrefixLength: 24,
+	IPv6PrefixLength: 48,
+}
+
+// DefaultLogSyslogConfiguration is the default syslog output configuration.
+var DefaultLogSyslogConfiguration = LogSyslog{
+	Facility: "daemon",
+	Tag:      "authelia",
+	Timeout:  time.Second * 5,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
@@ -12,6 +12,7 @@ type Notifier struct {
 	DisableStartupCheck bool                `koanf:"disable_startup_check" json:"disable_startup_check" jsonschema:"default=false,title=Disable Startup Check" jsonschema_description:"Disables the notifier startup checks."`
 	FileSystem          *NotifierFileSystem `koanf:"filesystem" json:"filesystem" jsonschema:"title=File System" jsonschema_description:"The File System notifier."`
 	SMTP                *NotifierSMTP       `koanf:"smtp" json:"smtp" jsonschema:"title=SMTP" jsonschema_description:"The SMTP notifier."`
+	SES                 *NotifierSES        `koanf:"ses" json:"ses" jsonschema:"title=SES" jsonschema_description:"The Amazon Simple Email Service notifier."`
 	TemplatePath        string              `koanf:"template_path" json:"template_path" jsonschema:"title=Template Path" jsonschema_description:"The path for notifier template overrides."`
 }
 
@@ -42,6 +43,24 @@ type NotifierSMTP struct {
 	Port int `koanf:"port" json:"port" jsonschema:"deprecated"`
 }
 
+// NotifierSES represents the configuration of the Amazon Simple Email Service to send emails with. The credentials are
+// sourced from the environment.
+type NotifierSES struct {
+	Region            string        `koanf:"region" json:"region" jsonschema:"title=Region" jsonschema_description:"The AWS region of the Amazon Simple Email Service, defaults to the region configured in the environment."`
+	Endpoint          *url.URL      `koanf:"endpoint" json:"endpoint" jsonschema:"format=uri,title=Endpoint" jsonschema_description:"The endpoint of the Amazon Simple Email Service API, defaults to the regional endpoint."`
+	Timeout           time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=5 seconds,title=Timeout" jsonschema_description:"The Amazon Simple Email Service API request timeout."`
+	Sender            mail.Address  `koanf:"sender" json:"sender" jsonschema:"title=Sender" jsonschema_description:"The sender used for the emails, the address or domain must be a verified identity."`
+	Subject           string        `koanf:"subject" json:"subject" jsonschema:"default=[Authelia] {title},title=Subject" jsonschema_description:"The subject format used."`
+	ConfigurationSet  string        `koanf:"configuration_set" json:"configuration_set" jsonschema:"title=Configuration Set" jsonschema_description:"The name of the configuration set used for the emails."`
+	DisableHTMLEmails bool          `koanf:"disable_html_emails" json:"disable_html_emails" jsonschema:"default=false,title=Disable HTML Emails" jsonschema_description:"Disables the mixed content type of emails and only sends the plaintext version."`
+}
+
+// DefaultSESNotifierConfiguration represents default configuration parameters for the SES notifier.
+var DefaultSESNotifierConfiguration = NotifierSES{
+	Timeout: time.Second * 5,
+	Subject: "[Authelia] {title}",
+}
+
 // DefaultSMTPNotifierConfiguration represents default configuration parameters for the SMTP notifier.
 var DefaultSMTPNotifierConfiguration = NotifierSMTP{
 	Address:             &AddressSMTP{Address{true, false, -1, 25, &url.URL{Scheme: AddressSchemeSMTP, Host: "localhost:25"}}},
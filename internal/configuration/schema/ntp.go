@@ -8,10 +8,14 @@ import (
 // NTP represents the configuration related to ntp server.
 type NTP struct {
 	Address             *AddressUDP   `koanf:"address" json:"address" jsonschema:"title=NTP Address" jsonschema_description:"The remote address of the NTP server."`
+	Servers             []*AddressUDP `koanf:"servers" json:"servers" jsonschema:"title=NTP Servers" jsonschema_description:"The remote addresses of additional NTP servers."`
+	Quorum              int           `koanf:"quorum" json:"quorum" jsonschema:"title=Quorum" jsonschema_description:"The number of NTP servers which must respond and agree on the time. Defaults to a majority of the servers."`
 	Version             int           `koanf:"version" json:"version" jsonschema:"enum=3,enum=4,title=NTP Version" jsonschema_description:"The NTP Version to use."`
 	MaximumDesync       time.Duration `koanf:"max_desync" json:"max_desync" jsonschema:"default=3 seconds,title=Maximum Desync" jsonschema_description:"The maximum amount of time that the server can be out of sync."`
 	DisableStartupCheck bool          `koanf:"disable_startup_check" json:"disable_startup_check" jsonschema:"default=false,title=Disable Startup Check" jsonschema_description:"Disables the NTP Startup Check entirely."`
 	DisableFailure      bool          `koanf:"disable_failure" json:"disable_failure" jsonschema:"default=false,title=Disable Failure" jsonschema_description:"Disables complete failure whe the Startup Check fails and instead just logs the error."`
+	Interval            time.Duration `koanf:"interval" json:"interval" jsonschema:"default=1 hour,title=Interval" jsonschema_description:"The interval the clock skew is checked at while running."`
+	DisableMonitoring   bool          `koanf:"disable_monitoring" json:"disable_monitoring" jsonschema:"default=false,title=Disable Monitoring" jsonschema_description:"Disables checking the clock skew periodically while running."`
 }
 
 // DefaultNTPConfiguration represents default configuration parameters for the NTP server.
@@ -19,4 +23,5 @@ var DefaultNTPConfiguration = NTP{
 	Address:       &AddressUDP{Address{valid: true, socket: false, port: 123, url: &url.URL{Scheme: AddressSchemeUDP, Host: "time.cloudflare.com:123"}}},
 	Version:       4,
 	MaximumDesync: time.Second * 3,
+	Interval:      time.Hour,
 }
@@ -1,9 +1,38 @@
 package schema
 
+import (
+	"slices"
+)
+
 // PasswordPolicy represents the configuration related to password policy.
 type PasswordPolicy struct {
 	Standard PasswordPolicyStandard `koanf:"standard" json:"standard" jsonschema:"title=Standard" jsonschema_description:"The standard password policy engine."`
 	ZXCVBN   PasswordPolicyZXCVBN   `koanf:"zxcvbn" json:"zxcvbn" jsonschema:"title=ZXCVBN" jsonschema_description:"The ZXCVBN password policy engine."`
+
+	BannedWords   []string                    `koanf:"banned_words" json:"banned_words" jsonschema:"title=Banned Words" jsonschema_description:"The list of words which are not allowed to be part of a password."`
+	GroupPolicies []PasswordPolicyGroupPolicy `koanf:"group_policies" json:"group_policies" jsonschema:"title=Group Policies" jsonschema_description:"The list of password policies which apply to users in specific groups instead of the top level policy."`
+}
+
+// PasswordPolicyGroupPolicy represents the configuration related to a password policy which applies to users in
+// specific groups.
+type PasswordPolicyGroupPolicy struct {
+	Groups   []string               `koanf:"groups" json:"groups" jsonschema:"title=Groups" jsonschema_description:"The groups this password policy applies to."`
+	Standard PasswordPolicyStandard `koanf:"standard" json:"standard" jsonschema:"title=Standard" jsonschema_description:"The standard password policy engine."`
+	ZXCVBN   PasswordPolicyZXCVBN   `koanf:"zxcvbn" json:"zxcvbn" jsonschema:"title=ZXCVBN" jsonschema_description:"The ZXCVBN password policy engine."`
+}
+
+// ForGroups returns the standard and ZXCVBN password policies which apply to a user with the provided groups. The
+// first group policy which includes any of the groups applies, otherwise the top level policies apply.
+func (p *PasswordPolicy) ForGroups(groups []string) (standard PasswordPolicyStandard, zxcvbn PasswordPolicyZXCVBN) {
+	for _, policy := range p.GroupPolicies {
+		for _, group := range policy.Groups {
+			if slices.Contains(groups, group) {
+				return policy.Standard, policy.ZXCVBN
+			}
+		}
+	}
+
+	return p.Standard, p.ZXCVBN
 }
 
 // PasswordPolicyStandard represents the configuration related to standard parameters of password policy.
@@ -19,8 +48,9 @@ type PasswordPolicyStandard struct {
 
 // PasswordPolicyZXCVBN represents the configuration related to ZXCVBN parameters of password policy.
 type PasswordPolicyZXCVBN struct {
-	Enabled  bool `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the ZXCVBN password policy engine."`
-	MinScore int  `koanf:"min_score" json:"min_score" jsonschema:"default=3,title=Minimum Score" jsonschema_description:"The minimum ZXCVBN score allowed."`
+	Enabled    bool `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the ZXCVBN password policy engine."`
+	MinScore   int  `koanf:"min_score" json:"min_score" jsonschema:"default=3,title=Minimum Score" jsonschema_description:"The minimum ZXCVBN score allowed."`
+	MinEntropy int  `koanf:"min_entropy" json:"min_entropy" jsonschema:"default=0,title=Minimum Entropy" jsonschema_description:"The minimum ZXCVBN entropy in bits allowed, 0 disables this check."`
 }
 
 // DefaultPasswordPolicyConfiguration is the default password policy configuration.
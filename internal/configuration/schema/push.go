@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"crypto/tls"
+	"net/url"
+	"time"
+)
+
+// Push represents the configuration related to the push notification second factor.
+type Push struct {
+	Disable  bool          `koanf:"disable" json:"disable" jsonschema:"default=false,title=Disable" jsonschema_description:"Disable the push notification second factor."`
+	Provider string        `koanf:"provider" json:"provider" jsonschema:"enum=privacyidea,enum=webhook,enum=mobile,enum=ntfy,enum=gotify,title=Provider" jsonschema_description:"The push notification provider used to approve the second factor."`
+	Timeout  time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=1 minute,title=Timeout" jsonschema_description:"The duration a user has to approve a push notification."`
+
+	PrivacyIDEA *PushPrivacyIDEA `koanf:"privacyidea" json:"privacyidea" jsonschema:"title=privacyIDEA" jsonschema_description:"The privacyIDEA push notification provider."`
+	Webhook     *PushWebhook     `koanf:"webhook" json:"webhook" jsonschema:"title=Webhook" jsonschema_description:"The webhook push notification provider."`
+	Mobile      *PushMobile      `koanf:"mobile" json:"mobile" jsonschema:"title=Mobile" jsonschema_description:"The mobile app push notification provider."`
+	Ntfy        *PushNtfy        `koanf:"ntfy" json:"ntfy" jsonschema:"title=ntfy" jsonschema_description:"The ntfy push notification provider."`
+	Gotify      *PushGotify      `koanf:"gotify" json:"gotify" jsonschema:"title=Gotify" jsonschema_description:"The Gotify push notification provider."`
+}
+
+// PushPrivacyIDEA represents the configuration of the push notification provider which approves the second factor
+// with the push tokens of a privacyIDEA server.
+type PushPrivacyIDEA struct {
+	URL   *url.URL `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The URL of the privacyIDEA server."`
+	Realm string   `koanf:"realm" json:"realm" jsonschema:"title=Realm" jsonschema_description:"The realm of the users."`
+	Token string   `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The administrative API token used to trigger the challenges."`
+	TLS   *TLS     `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The privacyIDEA TLS connection properties."`
+}
+
+// PushWebhook represents the configuration of the push notification provider which approves the second factor with
+// a webhook.
+type PushWebhook struct {
+	URL   *url.URL `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The base URL of the webhook."`
+	Token string   `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The bearer token sent with each request."`
+	TLS   *TLS     `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The webhook TLS connection properties."`
+}
+
+// PushMobile represents the configuration of the push notification provider which delivers the push challenges to the
+// mobile devices enrolled for push notifications with the mobile authentication endpoints using a push relay.
+type PushMobile struct {
+	URL   *url.URL `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The URL of the push relay which delivers the push challenges to the mobile devices."`
+	Token string   `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The bearer token sent with each request."`
+	TLS   *TLS     `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The push relay TLS connection properties."`
+}
+
+// PushNtfy represents the configuration of the push notification provider which publishes the push challenges to a
+// ntfy topic.
+type PushNtfy struct {
+	URL   *url.URL `koanf:"url" json:"url" jsonschema:"format=uri,default=https://ntfy.sh,title=URL" jsonschema_description:"The URL of the ntfy server."`
+	Topic string   `koanf:"topic" json:"topic" jsonschema:"title=Topic" jsonschema_description:"The topic the push challenges are published to. The {username} placeholder is replaced with the username of the user."`
+	Token string   `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The access token used to publish the push challenges."`
+	TLS   *TLS     `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The ntfy TLS connection properties."`
+}
+
+// PushGotify represents the configuration of the push notification provider which publishes the push challenges to a
+// Gotify application of each user.
+type PushGotify struct {
+	URL    *url.URL          `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The URL of the Gotify server."`
+	Tokens map[string]string `koanf:"tokens" json:"tokens" jsonschema:"title=Tokens" jsonschema_description:"The application tokens the push challenges of each user are published with keyed by the username."`
+	TLS    *TLS              `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The Gotify TLS connection properties."`
+}
+
+// DefaultPushConfiguration represents the default configuration related to the push notification second factor.
+var DefaultPushConfiguration = Push{
+	Timeout: time.Minute,
+}
+
+// DefaultPushPrivacyIDEAConfiguration represents the default configuration related to the privacyIDEA push
+// notification provider.
+var DefaultPushPrivacyIDEAConfiguration = PushPrivacyIDEA{
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultPushWebhookConfiguration represents the default configuration related to the webhook push notification
+// provider.
+var DefaultPushWebhookConfiguration = PushWebhook{
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultPushMobileConfiguration represents the default configuration related to the mobile app push notification
+// provider.
+var DefaultPushMobileConfiguration = PushMobile{
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultPushNtfyConfiguration represents the default configuration related to the ntfy push notification provider.
+var DefaultPushNtfyConfiguration = PushNtfy{
+	URL: &url.URL{
+		Scheme: "https",
+		Host:   "ntfy.sh",
+	},
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultPushGotifyConfiguration represents the default configuration related to the Gotify push notification
+// provider.
+var DefaultPushGotifyConfiguration = PushGotify{
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
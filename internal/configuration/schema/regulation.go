@@ -9,6 +9,15 @@ type Regulation struct {
 	MaxRetries int           `koanf:"max_retries" json:"max_retries" jsonschema:"default=3,title=Maximum Retries" jsonschema_description:"The maximum number of failed attempts permitted before banning a user."`
 	FindTime   time.Duration `koanf:"find_time" json:"find_time" jsonschema:"default=2 minutes,title=Find Time" jsonschema_description:"The amount of time to consider when determining the number of failed attempts."`
 	BanTime    time.Duration `koanf:"ban_time" json:"ban_time" jsonschema:"default=5 minutes,title=Ban Time" jsonschema_description:"The amount of time to ban the user for when it's determined the maximum retries has been exceeded."`
+
+	Anomalies RegulationAnomalies `koanf:"anomalies" json:"anomalies" jsonschema:"title=Anomalies" jsonschema_description:"The configuration of the detection of the credential stuffing and password spraying patterns."`
+}
+
+// RegulationAnomalies represents the configuration related to the detection of the authentication attack patterns.
+type RegulationAnomalies struct {
+	Window         time.Duration `koanf:"window" json:"window" jsonschema:"default=10 minutes,title=Window" jsonschema_description:"The amount of time to consider when detecting the attack patterns."`
+	UsernamesPerIP int           `koanf:"usernames_per_ip" json:"usernames_per_ip" jsonschema:"default=5,title=Usernames Per IP" jsonschema_description:"The number of distinct usernames which must have failed from a single IP within the window to be considered a spray."`
+	IPsPerUsername int           `koanf:"ips_per_username" json:"ips_per_username" jsonschema:"default=10,title=IPs Per Username" jsonschema_description:"The number of distinct IPs a single username must have failed from within the window to be considered a distributed attack."`
 }
 
 // DefaultRegulationConfiguration represents default configuration parameters for the regulator.
@@ -16,4 +25,9 @@ var DefaultRegulationConfiguration = Regulation{
 	MaxRetries: 3,
 	FindTime:   time.Minute * 2,
 	BanTime:    time.Minute * 5,
+	Anomalies: RegulationAnomalies{
+		Window:         time.Minute * 10,
+		UsernamesPerIP: 5,
+		IPsPerUsername: 10,
+	},
 }
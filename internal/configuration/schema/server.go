@@ -9,6 +9,7 @@ import (
 type Server struct {
 	Address            *AddressTCP `koanf:"address" json:"address" jsonschema:"default=tcp://:9091/,title=Address" jsonschema_description:"The address to listen on."`
 	AssetPath          string      `koanf:"asset_path" json:"asset_path" jsonschema:"title=Asset Path" jsonschema_description:"The directory where the server asset overrides reside."`
+	AssetWatch         bool        `koanf:"asset_watch" json:"asset_watch" jsonschema:"default=false,title=Asset Watch" jsonschema_description:"Enables reloading the branding manifest and partials in the asset path when they change."`
 	DisableHealthcheck bool        `koanf:"disable_healthcheck" json:"disable_healthcheck" jsonschema:"default=false,title=Disable Healthcheck" jsonschema_description:"Disables the healthcheck functionality."`
 
 	TLS       ServerTLS       `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The server TLS configuration."`
@@ -17,6 +18,82 @@ type Server struct {
 
 	Buffers  ServerBuffers  `koanf:"buffers" json:"buffers" jsonschema:"title=Buffers" jsonschema_description:"The server buffers configuration."`
 	Timeouts ServerTimeouts `koanf:"timeouts" json:"timeouts" jsonschema:"title=Timeouts" jsonschema_description:"The server timeouts configuration."`
+	Limits   ServerLimits   `koanf:"limits" json:"limits" jsonschema:"title=Limits" jsonschema_description:"The server limits configuration."`
+
+	KeepAlive ServerKeepAlive `koanf:"keepalive" json:"keepalive" jsonschema:"title=Keep Alive" jsonschema_description:"The server keep-alive configuration."`
+	H2C       ServerH2C       `koanf:"h2c" json:"h2c" jsonschema:"title=H2C" jsonschema_description:"The server HTTP/2 over cleartext configuration."`
+
+	ScannerFilter ServerScannerFilter `koanf:"scanner_filter" json:"scanner_filter" jsonschema:"title=Scanner Filter" jsonschema_description:"The server scanner filter configuration."`
+
+	Admin ServerAdmin `koanf:"admin" json:"admin" jsonschema:"title=Admin" jsonschema_description:"The admin server configuration."`
+
+	LDAP ServerLDAP `koanf:"ldap" json:"ldap" jsonschema:"title=LDAP" jsonschema_description:"The LDAP server configuration."`
+
+	Headless ServerHeadless `koanf:"headless" json:"headless" jsonschema:"title=Headless" jsonschema_description:"The server headless mode configuration."`
+}
+
+// ServerHeadless represents the configuration of the headless mode which disables the portal so the API can be used by
+// custom login frontends and native apps.
+type ServerHeadless struct {
+	Enabled        bool       `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the headless mode which disables the portal and only serves the API."`
+	AllowedOrigins []*url.URL `koanf:"allowed_origins" json:"allowed_origins" jsonschema:"format=uri,title=Allowed Origins" jsonschema_description:"The list of origins of the custom frontends which are permitted to make cross-origin requests to the API."`
+}
+
+// ServerAdmin represents the configuration of the admin server which serves the diagnostics endpoints on a separate
+// listener.
+type ServerAdmin struct {
+	Enabled      bool        `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the admin server."`
+	Address      *AddressTCP `koanf:"address" json:"address" jsonschema:"default=tcp://127.0.0.1:9960/,title=Address" jsonschema_description:"The address for the admin server to listen on."`
+	Token        string      `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The bearer token required to access the admin server."`
+	EnablePprof  bool        `koanf:"enable_pprof" json:"enable_pprof" jsonschema:"default=false,title=Enable PProf" jsonschema_description:"Enables the pprof and goroutine dump endpoints on the admin server."`
+	EnableEvents bool        `koanf:"enable_events" json:"enable_events" jsonschema:"default=false,title=Enable Events" jsonschema_description:"Enables the real-time audit event stream endpoint on the admin server."`
+	EnableAPI    bool        `koanf:"enable_api" json:"enable_api" jsonschema:"default=false,title=Enable API" jsonschema_description:"Enables the API to manage the sessions, second factors, and bans of users on the admin server."`
+
+	Buffers  ServerBuffers  `koanf:"buffers" json:"buffers" jsonschema:"title=Buffers" jsonschema_description:"The server buffers configuration for the admin server."`
+	Timeouts ServerTimeouts `koanf:"timeouts" json:"timeouts" jsonschema:"title=Timeouts" jsonschema_description:"The server timeouts configuration for the admin server."`
+}
+
+// ServerLDAP represents the configuration of the LDAP server which emulates a read-only directory of the users and
+// groups of the SQL authentication backend for the applications which can only authenticate users using LDAP.
+type ServerLDAP struct {
+	Enabled      bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the LDAP server."`
+	Address      *AddressTCP   `koanf:"address" json:"address" jsonschema:"default=tcp://127.0.0.1:3890/,title=Address" jsonschema_description:"The address for the LDAP server to listen on."`
+	BaseDN       string        `koanf:"base_dn" json:"base_dn" jsonschema:"title=Base DN" jsonschema_description:"The distinguished name of the root of the directory."`
+	SearchGroups []string      `koanf:"search_groups" json:"search_groups" jsonschema:"title=Search Groups" jsonschema_description:"The groups of the users which are permitted to search the directory, if empty all users are permitted."`
+	SizeLimit    int           `koanf:"size_limit" json:"size_limit" jsonschema:"default=500,minimum=1,title=Size Limit" jsonschema_description:"The maximum number of entries returned by a search."`
+	IdleTimeout  time.Duration `koanf:"idle_timeout" json:"idle_timeout" jsonschema:"default=5 minutes,title=Idle Timeout" jsonschema_description:"The amount of time a connection may be idle before it's closed."`
+
+	TLS ServerTLS `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The LDAP server TLS configuration."`
+}
+
+// ServerScannerFilter represents the configuration of the middleware which tarpits obvious scanners.
+type ServerScannerFilter struct {
+	Enable          bool          `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables the scanner filter."`
+	Delay           time.Duration `koanf:"delay" json:"delay" jsonschema:"default=5 seconds,title=Delay" jsonschema_description:"The amount of time to delay the response to a filtered request."`
+	UserAgents      []string      `koanf:"user_agents" json:"user_agents" jsonschema:"title=User Agents" jsonschema_description:"The list of regular expressions matched against the User-Agent header to identify scanners."`
+	Paths           []string      `koanf:"paths" json:"paths" jsonschema:"title=Paths" jsonschema_description:"The list of regular expressions matched against the request path to identify scanners."`
+	AllowedNetworks []string      `koanf:"allowed_networks" json:"allowed_networks" jsonschema:"title=Allowed Networks" jsonschema_description:"The list of networks which are never filtered."`
+}
+
+// ServerKeepAlive represents the configuration of the http server keep-alive behaviour.
+type ServerKeepAlive struct {
+	Disable   bool          `koanf:"disable" json:"disable" jsonschema:"default=false,title=Disable" jsonschema_description:"Disables HTTP/1.1 keep-alive connections so each connection is closed after serving a single request."`
+	TCPPeriod time.Duration `koanf:"tcp_period" json:"tcp_period" jsonschema:"default=0 seconds,title=TCP Period" jsonschema_description:"Enables TCP keep-alive probes with the provided interval, a value of 0 disables the probes."`
+}
+
+// ServerH2C represents the configuration of the http server HTTP/2 over cleartext support.
+type ServerH2C struct {
+	Enable               bool `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables serving HTTP/2 with prior knowledge over cleartext connections in addition to HTTP/1.1."`
+	MaxConcurrentStreams int  `koanf:"max_concurrent_streams" json:"max_concurrent_streams" jsonschema:"default=250,minimum=1,title=Max Concurrent Streams" jsonschema_description:"The maximum number of concurrent streams per HTTP/2 connection."`
+	MaxReadFrameSize     int  `koanf:"max_read_frame_size" json:"max_read_frame_size" jsonschema:"default=1048576,minimum=16384,maximum=16777215,title=Max Read Frame Size" jsonschema_description:"The maximum size in bytes of a HTTP/2 frame the server will read."`
+}
+
+// ServerLimits represents the configuration of the http server resource limits.
+type ServerLimits struct {
+	MaxRequestBodySize       int `koanf:"max_request_body_size" json:"max_request_body_size" jsonschema:"default=4194304,minimum=1,title=Max Request Body Size" jsonschema_description:"The maximum size in bytes of a request body."`
+	MaxConnections           int `koanf:"max_connections" json:"max_connections" jsonschema:"default=262144,minimum=1,title=Max Connections" jsonschema_description:"The maximum number of concurrent connections the server will serve."`
+	MaxConnectionsPerIP      int `koanf:"max_connections_per_ip" json:"max_connections_per_ip" jsonschema:"default=0,minimum=0,title=Max Connections Per IP" jsonschema_description:"The maximum number of concurrent connections from a single IP, a value of 0 is unlimited."`
+	MaxRequestsPerConnection int `koanf:"max_requests_per_connection" json:"max_requests_per_connection" jsonschema:"default=0,minimum=0,title=Max Requests Per Connection" jsonschema_description:"The maximum number of requests served per connection before it is closed, a value of 0 is unlimited."`
 }
 
 // ServerEndpoints is the endpoints configuration for the HTTP server.
@@ -25,6 +102,32 @@ type ServerEndpoints struct {
 	EnableExpvars bool `koanf:"enable_expvars" json:"enable_expvars" jsonschema:"default=false,title=Enable ExpVars" jsonschema_description:"Enables the developer specific ExpVars endpoints which should not be used in production and only used for debugging purposes."`
 
 	Authz map[string]ServerEndpointsAuthz `koanf:"authz" json:"authz" jsonschema:"title=Authz" jsonschema_description:"Configures the Authorization endpoints."`
+
+	Health ServerEndpointsHealth `koanf:"health" json:"health" jsonschema:"title=Health" jsonschema_description:"Configures the Health endpoints."`
+
+	SCIM ServerEndpointsSCIM `koanf:"scim" json:"scim" jsonschema:"title=SCIM" jsonschema_description:"Configures the SCIM 2.0 provisioning endpoints."`
+
+	Mobile ServerEndpointsMobile `koanf:"mobile" json:"mobile" jsonschema:"title=Mobile" jsonschema_description:"Configures the mobile authentication endpoints."`
+}
+
+// ServerEndpointsMobile is the mobile authentication endpoints configuration for the HTTP server.
+type ServerEndpointsMobile struct {
+	Enabled              bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the mobile authentication endpoints."`
+	AccessTokenLifespan  time.Duration `koanf:"access_token_lifespan" json:"access_token_lifespan" jsonschema:"default=5 minutes,title=Access Token Lifespan" jsonschema_description:"The amount of time an access token issued to a mobile device is valid for."`
+	RefreshTokenLifespan time.Duration `koanf:"refresh_token_lifespan" json:"refresh_token_lifespan" jsonschema:"default=30 days,title=Refresh Token Lifespan" jsonschema_description:"The amount of time a refresh token issued to a mobile device is valid for after it was last used."`
+}
+
+// ServerEndpointsSCIM is the SCIM 2.0 provisioning endpoints configuration for the HTTP server.
+type ServerEndpointsSCIM struct {
+	Enabled bool   `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the SCIM 2.0 provisioning endpoints."`
+	Token   string `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The bearer token the identity provider must use to authenticate to the SCIM 2.0 provisioning endpoints."`
+}
+
+// ServerEndpointsHealth is the Health endpoints configuration for the HTTP server.
+type ServerEndpointsHealth struct {
+	CacheDuration time.Duration `koanf:"cache_duration" json:"cache_duration" jsonschema:"default=10 seconds,title=Cache Duration" jsonschema_description:"The amount of time the results of the dependency checks are cached for."`
+	Timeout       time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=5 seconds,title=Timeout" jsonschema_description:"The amount of time each dependency check is allowed to take before it's considered a failure."`
+	AdminGroups   []string      `koanf:"admin_groups" json:"admin_groups" jsonschema:"title=Admin Groups" jsonschema_description:"The groups which are permitted to view the detailed health report."`
 }
 
 // ServerEndpointsAuthz is the Authz endpoints configuration for the HTTP server.
@@ -50,6 +153,38 @@ type ServerTLS struct {
 // ServerHeaders represents the customization of the http server headers.
 type ServerHeaders struct {
 	CSPTemplate CSPTemplate `koanf:"csp_template" json:"csp_template" jsonschema:"title=CSP Template" jsonschema_description:"The Content Security Policy template."`
+
+	StrictTransportSecurity ServerHeadersStrictTransportSecurity `koanf:"strict_transport_security" json:"strict_transport_security" jsonschema:"title=Strict Transport Security" jsonschema_description:"The Strict-Transport-Security header configuration."`
+
+	PermissionsPolicy string `koanf:"permissions_policy" json:"permissions_policy" jsonschema:"title=Permissions Policy" jsonschema_description:"The value of the Permissions-Policy header."`
+	ReferrerPolicy    string `koanf:"referrer_policy" json:"referrer_policy" jsonschema:"default=strict-origin-when-cross-origin,enum=no-referrer,enum=no-referrer-when-downgrade,enum=origin,enum=origin-when-cross-origin,enum=same-origin,enum=strict-origin,enum=strict-origin-when-cross-origin,enum=unsafe-url,title=Referrer Policy" jsonschema_description:"The value of the Referrer-Policy header."`
+	FrameOptions      string `koanf:"frame_options" json:"frame_options" jsonschema:"default=DENY,enum=DENY,enum=SAMEORIGIN,title=Frame Options" jsonschema_description:"The value of the X-Frame-Options header."`
+
+	Groups ServerHeadersGroups `koanf:"groups" json:"groups" jsonschema:"title=Groups" jsonschema_description:"The endpoint group specific headers configuration."`
+}
+
+// ServerHeadersStrictTransportSecurity represents the configuration of the Strict-Transport-Security header.
+type ServerHeadersStrictTransportSecurity struct {
+	MaxAge            time.Duration `koanf:"max_age" json:"max_age" jsonschema:"default=0 seconds,title=Max Age" jsonschema_description:"The max-age directive of the header, a value of 0 disables the header."`
+	IncludeSubdomains bool          `koanf:"include_subdomains" json:"include_subdomains" jsonschema:"default=false,title=Include Subdomains" jsonschema_description:"Includes the includeSubDomains directive in the header."`
+	Preload           bool          `koanf:"preload" json:"preload" jsonschema:"default=false,title=Preload" jsonschema_description:"Includes the preload directive in the header."`
+}
+
+// ServerHeadersGroups represents the headers configuration of each endpoint group.
+type ServerHeadersGroups struct {
+	Portal        ServerHeadersGroup `koanf:"portal" json:"portal" jsonschema:"title=Portal" jsonschema_description:"The headers configuration for the portal and authz endpoints."`
+	API           ServerHeadersGroup `koanf:"api" json:"api" jsonschema:"title=API" jsonschema_description:"The headers configuration for the API endpoints."`
+	OpenIDConnect ServerHeadersGroup `koanf:"openid_connect" json:"openid_connect" jsonschema:"title=OpenID Connect" jsonschema_description:"The headers configuration for the OpenID Connect 1.0 endpoints."`
+	OpenAPI       ServerHeadersGroup `koanf:"openapi" json:"openapi" jsonschema:"title=OpenAPI" jsonschema_description:"The headers configuration for the OpenAPI documentation endpoints."`
+}
+
+// ServerHeadersGroup represents the headers configuration of an endpoint group.
+type ServerHeadersGroup struct {
+	ContentSecurityPolicy           CSPTemplate `koanf:"content_security_policy" json:"content_security_policy" jsonschema:"title=Content Security Policy" jsonschema_description:"The Content-Security-Policy template for this group, the nonce placeholder is replaced with the per-request nonce where supported."`
+	ContentSecurityPolicyReportOnly bool        `koanf:"content_security_policy_report_only" json:"content_security_policy_report_only" jsonschema:"default=false,title=Content Security Policy Report Only" jsonschema_description:"Sends the Content-Security-Policy-Report-Only header instead of the Content-Security-Policy header."`
+	CrossOriginOpenerPolicy         string      `koanf:"cross_origin_opener_policy" json:"cross_origin_opener_policy" jsonschema:"enum=,enum=unsafe-none,enum=same-origin-allow-popups,enum=same-origin,title=Cross-Origin-Opener-Policy" jsonschema_description:"The value of the Cross-Origin-Opener-Policy header."`
+	CrossOriginEmbedderPolicy       string      `koanf:"cross_origin_embedder_policy" json:"cross_origin_embedder_policy" jsonschema:"enum=,enum=unsafe-none,enum=require-corp,enum=credentialless,title=Cross-Origin-Embedder-Policy" jsonschema_description:"The value of the Cross-Origin-Embedder-Policy header."`
+	CrossOriginResourcePolicy       string      `koanf:"cross_origin_resource_policy" json:"cross_origin_resource_policy" jsonschema:"enum=,enum=same-site,enum=same-origin,enum=cross-origin,title=Cross-Origin-Resource-Policy" jsonschema_description:"The value of the Cross-Origin-Resource-Policy header."`
 }
 
 // DefaultServerConfiguration represents the default values of the Server.
@@ -64,6 +199,60 @@ var DefaultServerConfiguration = Server{
 		Write: time.Second * 6,
 		Idle:  time.Second * 30,
 	},
+	Limits: ServerLimits{
+		MaxRequestBodySize: 4 * 1024 * 1024,
+		MaxConnections:     256 * 1024,
+	},
+	H2C: ServerH2C{
+		MaxConcurrentStreams: 250,
+		MaxReadFrameSize:     1024 * 1024,
+	},
+	Admin: ServerAdmin{
+		Address: &AddressTCP{Address{true, false, -1, 9960, &url.URL{Scheme: AddressSchemeTCP, Host: "127.0.0.1:9960", Path: "/"}}},
+		Buffers: ServerBuffers{
+			Read:  4096,
+			Write: 4096,
+		},
+		Timeouts: ServerTimeouts{
+			Read:  time.Second * 6,
+			Write: time.Minute,
+			Idle:  time.Second * 30,
+		},
+	},
+	LDAP: ServerLDAP{
+		Address:     &AddressTCP{Address{true, false, -1, 3890, &url.URL{Scheme: AddressSchemeTCP, Host: "127.0.0.1:3890", Path: "/"}}},
+		SizeLimit:   500,
+		IdleTimeout: time.Minute * 5,
+	},
+	ScannerFilter: ServerScannerFilter{
+		Delay: time.Second * 5,
+		UserAgents: []string{
+			`(?i)(sqlmap|nikto|nmap|masscan|zgrab|nuclei|dirbuster|gobuster|feroxbuster|ffuf|wpscan|acunetix|nessus|openvas|w3af|jaeles|httpx)`,
+		},
+		Paths: []string{
+			`(?i)\.(php[0-9]?|asp|aspx|jsp|cgi)$`,
+			`(?i)^/(wp-admin|wp-login|wp-content|wp-includes|xmlrpc|phpmyadmin|pma|cgi-bin|actuator|vendor/phpunit|boaform)(/|$)`,
+			`^/\.(env|git|svn|hg|aws|ssh|DS_Store)(/|$)`,
+		},
+	},
+	Headers: ServerHeaders{
+		PermissionsPolicy: "accelerometer=(), autoplay=(), camera=(), display-capture=(), geolocation=(), gyroscope=(), keyboard-map=(), magnetometer=(), microphone=(), midi=(), payment=(), picture-in-picture=(), screen-wake-lock=(), sync-xhr=(), xr-spatial-tracking=(), interest-cohort=()",
+		ReferrerPolicy:    "strict-origin-when-cross-origin",
+		FrameOptions:      "DENY",
+		Groups: ServerHeadersGroups{
+			API: ServerHeadersGroup{
+				ContentSecurityPolicy: "default-src 'none'",
+			},
+			OpenIDConnect: ServerHeadersGroup{
+				ContentSecurityPolicy: "default-src 'none'",
+			},
+			OpenAPI: ServerHeadersGroup{
+				CrossOriginOpenerPolicy:   "same-origin",
+				CrossOriginEmbedderPolicy: "unsafe-none",
+				CrossOriginResourcePolicy: "cross-origin",
+			},
+		},
+	},
 	Endpoints: ServerEndpoints{
 		Authz: map[string]ServerEndpointsAuthz{
 			AuthzEndpointNameLegacy: {
@@ -114,5 +303,13 @@ var DefaultServerConfiguration = Server{
 				},
 			},
 		},
+		Health: ServerEndpointsHealth{
+			CacheDuration: time.Second * 10,
+			Timeout:       time.Second * 5,
+		},
+		Mobile: ServerEndpointsMobile{
+			AccessTokenLifespan:  time.Minute * 5,
+			RefreshTokenLifespan: time.Hour * 24 * 30,
+		},
 	},
 }
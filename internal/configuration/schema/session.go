@@ -12,6 +12,10 @@ type Session struct {
 
 	Secret string `koanf:"secret" json:"secret" jsonschema:"title=Secret" jsonschema_description:"Secret used to encrypt the session data."`
 
+	Storage string `koanf:"storage" json:"storage" jsonschema:"enum=memory,enum=redis,enum=sql,title=Storage" jsonschema_description:"The storage of the session data. Defaults to redis when the redis options are configured and otherwise memory."`
+
+	EnableUserSessions bool `koanf:"enable_user_sessions" json:"enable_user_sessions" jsonschema:"default=false,title=Enable User Sessions" jsonschema_description:"Enables the list of the active sessions of the user in the portal which allows the user to revoke them."`
+
 	Cookies []SessionCookie `koanf:"cookies" json:"cookies" jsonschema:"title=Cookies" jsonschema_description:"List of cookie domain configurations."`
 
 	Redis *SessionRedis `koanf:"redis" json:"redis" jsonschema:"title=Redis" jsonschema_description:"Redis Session Provider configuration."`
@@ -38,9 +42,19 @@ type SessionCookie struct {
 	AutheliaURL           *url.URL `koanf:"authelia_url" json:"authelia_url" jsonschema:"format=uri,title=Authelia URL" jsonschema_description:"The Root Authelia URL to redirect users to for this session cookie configuration."`
 	DefaultRedirectionURL *url.URL `koanf:"default_redirection_url" json:"default_redirection_url" jsonschema:"format=uri,title=Default Redirection URL" jsonschema_description:"The default redirection URL for this session cookie configuration."`
 
+	Branding SessionCookieBranding `koanf:"branding" json:"branding" jsonschema:"title=Branding" jsonschema_description:"The portal branding for this session cookie configuration."`
+
 	Legacy bool `json:"-"`
 }
 
+// SessionCookieBranding represents the portal branding for a cookie domain.
+type SessionCookieBranding struct {
+	Title          string   `koanf:"title" json:"title" jsonschema:"title=Title" jsonschema_description:"The title of the portal for this session cookie configuration."`
+	LogoURL        *url.URL `koanf:"logo_url" json:"logo_url" jsonschema:"format=uri,title=Logo URL" jsonschema_description:"The URL of the logo displayed by the portal for this session cookie configuration."`
+	Announcement   string   `koanf:"announcement" json:"announcement" jsonschema:"title=Announcement" jsonschema_description:"The announcement banner displayed by the portal for this session cookie configuration."`
+	SupportContact *url.URL `koanf:"support_contact" json:"support_contact" jsonschema:"format=uri,title=Support Contact" jsonschema_description:"The support contact URI displayed by the portal for this session cookie configuration."`
+}
+
 // SessionRedis represents the configuration related to redis session store.
 type SessionRedis struct {
 	Host                     string `koanf:"host" json:"host" jsonschema:"title=Host" jsonschema_description:"The redis server host."`
@@ -53,6 +67,7 @@ type SessionRedis struct {
 	TLS                      *TLS   `koanf:"tls" json:"tls"`
 
 	HighAvailability *SessionRedisHighAvailability `koanf:"high_availability" json:"high_availability"`
+	Cluster          *SessionRedisCluster          `koanf:"cluster" json:"cluster"`
 }
 
 // SessionRedisHighAvailability holds configuration variables for Redis Cluster/Sentinel.
@@ -66,6 +81,15 @@ type SessionRedisHighAvailability struct {
 	Nodes []SessionRedisHighAvailabilityNode `koanf:"nodes" json:"nodes" jsonschema:"title=Nodes" jsonschema_description:"The pre-populated list of nodes for the sentinel instance."`
 }
 
+// SessionRedisCluster holds configuration variables for Redis Cluster.
+type SessionRedisCluster struct {
+	RouteByLatency   bool `koanf:"route_by_latency" json:"route_by_latency" jsonschema:"default=false,title=Route by Latency" jsonschema_description:"Routes the read-only commands to the closest node of the slot."`
+	RouteRandomly    bool `koanf:"route_randomly" json:"route_randomly" jsonschema:"default=false,title=Route Randomly" jsonschema_description:"Routes the read-only commands to a random node of the slot."`
+	MaximumRedirects int  `koanf:"maximum_redirects" json:"maximum_redirects" jsonschema:"default=3,title=Maximum Redirects" jsonschema_description:"The maximum number of MOVED and ASK redirects followed for a single command."`
+
+	Nodes []SessionRedisHighAvailabilityNode `koanf:"nodes" json:"nodes" jsonschema:"title=Nodes" jsonschema_description:"The list of seed nodes used to discover the topology of the cluster."`
+}
+
 // SessionRedisHighAvailabilityNode Represents a Node.
 type SessionRedisHighAvailabilityNode struct {
 	Host string `koanf:"host" json:"host" jsonschema:"title=Host" jsonschema_description:"The redis sentinel node host."`
@@ -92,6 +116,11 @@ var DefaultRedisConfiguration = SessionRedis{
 	},
 }
 
+// DefaultRedisClusterConfiguration is the default redis cluster configuration.
+var DefaultRedisClusterConfiguration = SessionRedisCluster{
+	MaximumRedirects: 3,
+}
+
 // DefaultRedisHighAvailabilityConfiguration is the default redis configuration.
 var DefaultRedisHighAvailabilityConfiguration = SessionRedis{
 	Port:                     26379,
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"net/url"
+	"time"
+)
+
+// SMS represents the configuration related to the SMS second factor method which delivers One-Time Codes to the
+// verified phone number of a user by SMS or voice call.
+type SMS struct {
+	Disable      bool          `koanf:"disable" json:"disable" jsonschema:"default=false,title=Disable" jsonschema_description:"Disables the SMS second factor method."`
+	Provider     string        `koanf:"provider" json:"provider" jsonschema:"enum=twilio,enum=vonage,enum=http,title=Provider" jsonschema_description:"The provider used to deliver the One-Time Codes."`
+	Characters   int           `koanf:"characters" json:"characters" jsonschema:"default=6,minimum=6,maximum=10,title=Characters" jsonschema_description:"The number of digits in the generated One-Time Codes."`
+	CodeLifespan time.Duration `koanf:"code_lifespan" json:"code_lifespan" jsonschema:"default=5 minutes,title=Code Lifespan" jsonschema_description:"The lifespan of the generated One-Time Codes after which they're considered invalid."`
+	EnableVoice  bool          `koanf:"enable_voice" json:"enable_voice" jsonschema:"default=false,title=Enable Voice" jsonschema_description:"Allows the users to receive the One-Time Codes with a voice call instead of a SMS."`
+	RateLimit    SMSRateLimit  `koanf:"rate_limit" json:"rate_limit" jsonschema:"title=Rate Limit" jsonschema_description:"The limit of One-Time Codes sent to each user within a period."`
+
+	Twilio *SMSTwilio `koanf:"twilio" json:"twilio" jsonschema:"title=Twilio" jsonschema_description:"The Twilio SMS provider."`
+	Vonage *SMSVonage `koanf:"vonage" json:"vonage" jsonschema:"title=Vonage" jsonschema_description:"The Vonage SMS provider."`
+	HTTP   *SMSHTTP   `koanf:"http" json:"http" jsonschema:"title=HTTP" jsonschema_description:"The generic HTTP gateway SMS provider."`
+}
+
+// SMSRateLimit represents the limit of One-Time Codes sent to each user within a period.
+type SMSRateLimit struct {
+	Disable  bool          `koanf:"disable" json:"disable" jsonschema:"title=Disable,default=false" jsonschema_description:"Disables the rate limit."`
+	Requests int           `koanf:"requests" json:"requests" jsonschema:"title=Requests,default=5,minimum=1" jsonschema_description:"The number of One-Time Codes which can be sent to each user within the period."`
+	Period   time.Duration `koanf:"period" json:"period" jsonschema:"title=Period,default=1 hour" jsonschema_description:"The period the number of sent One-Time Codes is counted within."`
+}
+
+// SMSTwilio represents the configuration of the SMS provider which delivers the One-Time Codes with the Twilio
+// Programmable Messaging and Programmable Voice APIs.
+type SMSTwilio struct {
+	URL        *url.URL      `koanf:"url" json:"url" jsonschema:"format=uri,default=https://api.twilio.com,title=URL" jsonschema_description:"The URL of the Twilio API."`
+	AccountSID string        `koanf:"account_sid" json:"account_sid" jsonschema:"title=Account SID" jsonschema_description:"The Account SID provided by your Twilio console."`
+	AuthToken  string        `koanf:"auth_token" json:"auth_token" jsonschema:"title=Auth Token" jsonschema_description:"The Auth Token provided by your Twilio console."`
+	From       string        `koanf:"from" json:"from" jsonschema:"title=From" jsonschema_description:"The Twilio phone number in the E.164 format or the alphanumeric sender ID the One-Time Codes are sent from."`
+	Timeout    time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of the requests to the Twilio API."`
+	TLS        *TLS          `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The Twilio API TLS connection properties."`
+}
+
+// SMSVonage represents the configuration of the SMS provider which delivers the One-Time Codes with the Vonage SMS and
+// Voice APIs.
+type SMSVonage struct {
+	URL           *url.URL        `koanf:"url" json:"url" jsonschema:"format=uri,default=https://rest.nexmo.com,title=URL" jsonschema_description:"The URL of the Vonage SMS API."`
+	VoiceURL      *url.URL        `koanf:"voice_url" json:"voice_url" jsonschema:"format=uri,default=https://api.nexmo.com,title=Voice URL" jsonschema_description:"The URL of the Vonage Voice API."`
+	APIKey        string          `koanf:"api_key" json:"api_key" jsonschema:"title=API Key" jsonschema_description:"The API Key provided by your Vonage dashboard."`
+	APISecret     string          `koanf:"api_secret" json:"api_secret" jsonschema:"title=API Secret" jsonschema_description:"The API Secret provided by your Vonage dashboard."`
+	From          string          `koanf:"from" json:"from" jsonschema:"title=From" jsonschema_description:"The Vonage phone number in the E.164 format or the alphanumeric sender ID the One-Time Codes are sent from."`
+	ApplicationID string          `koanf:"application_id" json:"application_id" jsonschema:"title=Application ID" jsonschema_description:"The ID of the Vonage application used to place the voice calls."`
+	PrivateKey    *rsa.PrivateKey `koanf:"private_key" json:"private_key" jsonschema:"title=Private Key" jsonschema_description:"The private key of the Vonage application used to place the voice calls."`
+	Timeout       time.Duration   `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of the requests to the Vonage APIs."`
+	TLS           *TLS            `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The Vonage APIs TLS connection properties."`
+}
+
+// SMSHTTP represents the configuration of the SMS provider which delivers the One-Time Codes by posting them to a
+// generic HTTP gateway.
+type SMSHTTP struct {
+	URL     *url.URL      `koanf:"url" json:"url" jsonschema:"format=uri,title=URL" jsonschema_description:"The URL of the HTTP gateway."`
+	Token   string        `koanf:"token" json:"token" jsonschema:"title=Token" jsonschema_description:"The bearer token sent with each request."`
+	Timeout time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of the requests to the HTTP gateway."`
+	TLS     *TLS          `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The HTTP gateway TLS connection properties."`
+}
+
+// DefaultSMSConfiguration represents the default configuration related to the SMS second factor method.
+var DefaultSMSConfiguration = SMS{
+	Characters:   6,
+	CodeLifespan: time.Minute * 5,
+	RateLimit: SMSRateLimit{
+		Requests: 5,
+		Period:   time.Hour,
+	},
+}
+
+// DefaultSMSTwilioConfiguration represents the default configuration related to the Twilio SMS provider.
+var DefaultSMSTwilioConfiguration = SMSTwilio{
+	URL: &url.URL{
+		Scheme: "https",
+		Host:   "api.twilio.com",
+	},
+	Timeout: time.Second * 10,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultSMSVonageConfiguration represents the default configuration related to the Vonage SMS provider.
+var DefaultSMSVonageConfiguration = SMSVonage{
+	URL: &url.URL{
+		Scheme: "https",
+		Host:   "rest.nexmo.com",
+	},
+	VoiceURL: &url.URL{
+		Scheme: "https",
+		Host:   "api.nexmo.com",
+	},
+	Timeout: time.Second * 10,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
+
+// DefaultSMSHTTPConfiguration represents the default configuration related to the generic HTTP gateway SMS provider.
+var DefaultSMSHTTPConfiguration = SMSHTTP{
+	Timeout: time.Second * 10,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
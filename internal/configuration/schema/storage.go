@@ -8,11 +8,22 @@ import (
 
 // Storage represents the configuration of the storage backend.
 type Storage struct {
-	Local      *StorageLocal      `koanf:"local" json:"local" jsonschema:"title=Local" jsonschema_description:"The Local SQLite3 Storage configuration settings."`
-	MySQL      *StorageMySQL      `koanf:"mysql" json:"mysql" jsonschema:"title=MySQL" jsonschema_description:"The MySQL/MariaDB Storage configuration settings."`
-	PostgreSQL *StoragePostgreSQL `koanf:"postgres" json:"postgres" jsonschema:"title=PostgreSQL" jsonschema_description:"The PostgreSQL Storage configuration settings."`
+	Local       *StorageLocal       `koanf:"local" json:"local" jsonschema:"title=Local" jsonschema_description:"The Local SQLite3 Storage configuration settings."`
+	MySQL       *StorageMySQL       `koanf:"mysql" json:"mysql" jsonschema:"title=MySQL" jsonschema_description:"The MySQL/MariaDB Storage configuration settings."`
+	PostgreSQL  *StoragePostgreSQL  `koanf:"postgres" json:"postgres" jsonschema:"title=PostgreSQL" jsonschema_description:"The PostgreSQL Storage configuration settings."`
+	CockroachDB *StorageCockroachDB `koanf:"cockroachdb" json:"cockroachdb" jsonschema:"title=CockroachDB" jsonschema_description:"The CockroachDB Storage configuration settings."`
 
-	EncryptionKey string `koanf:"encryption_key" json:"encryption_key" jsonschema:"title=Encryption Key" jsonschema_description:"The Storage Encryption Key used to secure security sensitive values in the storage engine."`
+	EncryptionKey          string   `koanf:"encryption_key" json:"encryption_key" jsonschema:"title=Encryption Key" jsonschema_description:"The Storage Encryption Key used to secure security sensitive values in the storage engine."`
+	PreviousEncryptionKeys []string `koanf:"previous_encryption_keys" json:"previous_encryption_keys" jsonschema:"title=Previous Encryption Keys" jsonschema_description:"The previous Storage Encryption Keys which are used to decrypt the values which have not been rotated to the Encryption Key yet."`
+
+	Cleanup StorageCleanup `koanf:"cleanup" json:"cleanup" jsonschema:"title=Cleanup" jsonschema_description:"The periodic cleanup of the expired rows in the storage."`
+}
+
+// StorageCleanup represents the configuration of the periodic cleanup of the expired rows in the storage.
+type StorageCleanup struct {
+	Disable   bool          `koanf:"disable" json:"disable" jsonschema:"default=false,title=Disable" jsonschema_description:"Disables the periodic cleanup of the expired rows."`
+	Interval  time.Duration `koanf:"interval" json:"interval" jsonschema:"default=1 hour,title=Interval" jsonschema_description:"The interval between each cleanup."`
+	BatchSize int           `koanf:"batch_size" json:"batch_size" jsonschema:"default=1000,title=Batch Size" jsonschema_description:"The maximum number of rows deleted by each statement during a cleanup."`
 }
 
 // StorageLocal represents the configuration when using local storage.
@@ -27,12 +38,16 @@ type StorageSQL struct {
 	Username string        `koanf:"username" json:"username" jsonschema:"title=Username" jsonschema_description:"The username to use to authenticate."`
 	Password string        `koanf:"password" json:"password" jsonschema:"title=Password" jsonschema_description:"The password to use to authenticate."`
 	Timeout  time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=5 seconds,title=Timeout" jsonschema_description:"The timeout for the database connection."`
+
+	Replicas []*AddressTCP `koanf:"replicas" json:"replicas" jsonschema:"title=Replicas" jsonschema_description:"The addresses of the read-only replicas of the database which are used for reads which tolerate replication lag."`
 }
 
 // StorageMySQL represents the configuration of a MySQL database.
 type StorageMySQL struct {
 	StorageSQL `koanf:",squash"`
 
+	IAMAuthentication StorageIAMAuthentication `koanf:"iam_authentication" json:"iam_authentication" jsonschema:"title=IAM Authentication" jsonschema_description:"The IAM database authentication of the managed database which replaces the password."`
+
 	TLS *TLS `koanf:"tls" json:"tls"`
 }
 
@@ -41,12 +56,31 @@ type StoragePostgreSQL struct {
 	StorageSQL `koanf:",squash"`
 	Schema     string `koanf:"schema" json:"schema" jsonschema:"default=public,title=Schema" jsonschema_description:"The default schema name to use."`
 
+	IAMAuthentication StorageIAMAuthentication `koanf:"iam_authentication" json:"iam_authentication" jsonschema:"title=IAM Authentication" jsonschema_description:"The IAM database authentication of the managed database which replaces the password."`
+
 	TLS *TLS `koanf:"tls" json:"tls"`
 
 	// Deprecated: Use the TLS configuration instead.
 	SSL *StoragePostgreSQLSSL `koanf:"ssl" json:"ssl" jsonschema:"deprecated,title=SSL"`
 }
 
+// StorageCockroachDB represents the configuration of a CockroachDB database.
+type StorageCockroachDB struct {
+	StorageSQL `koanf:",squash"`
+	Schema     string `koanf:"schema" json:"schema" jsonschema:"default=public,title=Schema" jsonschema_description:"The default schema name to use."`
+
+	MaximumRetries int `koanf:"maximum_retries" json:"maximum_retries" jsonschema:"default=5,title=Maximum Retries" jsonschema_description:"The maximum number of times a transaction is retried when it fails due to a serialization conflict."`
+
+	TLS *TLS `koanf:"tls" json:"tls"`
+}
+
+// StorageIAMAuthentication represents the configuration of the IAM database authentication of a managed database where
+// a short-lived token issued using the workload identity of the environment is used as the password.
+type StorageIAMAuthentication struct {
+	Provider string `koanf:"provider" json:"provider" jsonschema:"enum=aws,enum=azure,enum=gcp,title=Provider" jsonschema_description:"The cloud provider which issues the IAM authentication tokens."`
+	Region   string `koanf:"region" json:"region" jsonschema:"title=Region" jsonschema_description:"The AWS region of the database, defaults to the region configured in the environment."`
+}
+
 // StoragePostgreSQLSSL represents the SSL configuration of a PostgreSQL database.
 type StoragePostgreSQLSSL struct {
 	Mode            string `koanf:"mode" json:"mode" jsonschema:"deprecated,enum=disable,enum=verify-ca,enum=require,enum=verify-full,title=Mode" jsonschema_description:"The SSL mode to use, deprecated and replaced with the TLS options."`
@@ -55,6 +89,12 @@ type StoragePostgreSQLSSL struct {
 	Key             string `koanf:"key" json:"key" jsonschema:"deprecated,title=Key" jsonschema_description:"Path to the Private Key to use, deprecated and replaced with the TLS options."`
 }
 
+// DefaultStorageCleanupConfiguration represents the default storage cleanup configuration.
+var DefaultStorageCleanupConfiguration = StorageCleanup{
+	Interval:  time.Hour,
+	BatchSize: 1000,
+}
+
 // DefaultSQLStorageConfiguration represents the default SQL configuration.
 var DefaultSQLStorageConfiguration = StorageSQL{
 	Timeout: 5 * time.Second,
@@ -83,3 +123,15 @@ var DefaultPostgreSQLStorageConfiguration = StoragePostgreSQL{
 		Mode: "disable",
 	},
 }
+
+// DefaultCockroachDBStorageConfiguration represents the default CockroachDB configuration.
+var DefaultCockroachDBStorageConfiguration = StorageCockroachDB{
+	StorageSQL: StorageSQL{
+		Address: &AddressTCP{Address{true, false, -1, 26257, &url.URL{Scheme: AddressSchemeTCP, Host: "localhost:26257"}}},
+	},
+	Schema:         "public",
+	MaximumRetries: 5,
+	TLS: &TLS{
+		MinimumVersion: TLSVersion{tls.VersionTLS12},
+	},
+}
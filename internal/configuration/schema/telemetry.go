@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"crypto/tls"
 	"net/url"
 	"time"
 )
@@ -8,6 +9,7 @@ import (
 // Telemetry represents the telemetry config.
 type Telemetry struct {
 	Metrics TelemetryMetrics `koanf:"metrics" json:"metrics" jsonschema:"title=Metrics" jsonschema_description:"The telemetry metrics server configuration."`
+	Usage   TelemetryUsage   `koanf:"usage" json:"usage" jsonschema:"title=Usage" jsonschema_description:"The telemetry usage reporting configuration."`
 }
 
 // TelemetryMetrics represents the telemetry metrics config.
@@ -17,6 +19,40 @@ type TelemetryMetrics struct {
 
 	Buffers  ServerBuffers  `koanf:"buffers" json:"buffers" jsonschema:"title=Buffers" jsonschema_description:"The server buffers configuration for the metrics server."`
 	Timeouts ServerTimeouts `koanf:"timeouts" json:"timeouts" jsonschema:"title=Timeouts" jsonschema_description:"The server timeouts configuration for the metrics server."`
+
+	StatsD TelemetryMetricsStatsD `koanf:"statsd" json:"statsd" jsonschema:"title=StatsD" jsonschema_description:"The StatsD metrics exporter configuration."`
+	OTLP   TelemetryMetricsOTLP   `koanf:"otlp" json:"otlp" jsonschema:"title=OTLP" jsonschema_description:"The OpenTelemetry Protocol metrics exporter configuration."`
+}
+
+// TelemetryMetricsStatsD represents the telemetry metrics StatsD exporter config.
+type TelemetryMetricsStatsD struct {
+	Enabled  bool              `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the StatsD metrics exporter."`
+	Address  *AddressUDP       `koanf:"address" json:"address" jsonschema:"default=udp://127.0.0.1:8125,title=Address" jsonschema_description:"The address of the StatsD server."`
+	Flavor   string            `koanf:"flavor" json:"flavor" jsonschema:"default=statsd,enum=statsd,enum=dogstatsd,title=Flavor" jsonschema_description:"The StatsD protocol flavor, the dogstatsd flavor sends the labels as tags."`
+	Prefix   string            `koanf:"prefix" json:"prefix" jsonschema:"title=Prefix" jsonschema_description:"The prefix added to the name of every metric."`
+	Tags     map[string]string `koanf:"tags" json:"tags" jsonschema:"title=Tags" jsonschema_description:"The additional tags sent with every metric when using the dogstatsd flavor."`
+	Interval time.Duration     `koanf:"interval" json:"interval" jsonschema:"default=10 seconds,title=Interval" jsonschema_description:"The interval between each export."`
+}
+
+// TelemetryMetricsOTLP represents the telemetry metrics OpenTelemetry Protocol exporter config.
+type TelemetryMetricsOTLP struct {
+	Enabled    bool              `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the OpenTelemetry Protocol metrics exporter."`
+	Endpoint   *url.URL          `koanf:"endpoint" json:"endpoint" jsonschema:"default=http://127.0.0.1:4318/v1/metrics,format=uri,title=Endpoint" jsonschema_description:"The OTLP/HTTP endpoint the metrics are posted to."`
+	Headers    map[string]string `koanf:"headers" json:"headers" jsonschema:"title=Headers" jsonschema_description:"The additional headers sent with each request."`
+	Attributes map[string]string `koanf:"attributes" json:"attributes" jsonschema:"title=Attributes" jsonschema_description:"The additional resource attributes sent with the metrics."`
+	Interval   time.Duration     `koanf:"interval" json:"interval" jsonschema:"default=30 seconds,title=Interval" jsonschema_description:"The interval between each export."`
+	Timeout    time.Duration     `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of each request."`
+	TLS        *TLS              `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The OTLP exporter TLS connection properties."`
+}
+
+// TelemetryUsage represents the telemetry usage reporting config.
+type TelemetryUsage struct {
+	Enabled  bool          `koanf:"enabled" json:"enabled" jsonschema:"default=false,title=Enabled" jsonschema_description:"Enables the anonymous usage reports."`
+	Endpoint *url.URL      `koanf:"endpoint" json:"endpoint" jsonschema:"format=uri,title=Endpoint" jsonschema_description:"The URL the anonymous usage reports are posted to."`
+	Interval time.Duration `koanf:"interval" json:"interval" jsonschema:"default=24 hours,title=Interval" jsonschema_description:"The interval between the usage reports."`
+	Period   time.Duration `koanf:"period" json:"period" jsonschema:"default=30 days,title=Period" jsonschema_description:"The period a user must have signed in within to be considered active."`
+	Timeout  time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=10 seconds,title=Timeout" jsonschema_description:"The timeout of each request."`
+	TLS      *TLS          `koanf:"tls" json:"tls" jsonschema:"title=TLS" jsonschema_description:"The usage reporting TLS connection properties."`
 }
 
 // DefaultTelemetryConfig is the default telemetry configuration.
@@ -32,5 +68,26 @@ var DefaultTelemetryConfig = Telemetry{
 			Write: time.Second * 6,
 			Idle:  time.Second * 30,
 		},
+		StatsD: TelemetryMetricsStatsD{
+			Address:  &AddressUDP{Address{true, false, -1, 8125, &url.URL{Scheme: AddressSchemeUDP, Host: "127.0.0.1:8125"}}},
+			Flavor:   TelemetryMetricsStatsDFlavorStatsD,
+			Interval: time.Second * 10,
+		},
+		OTLP: TelemetryMetricsOTLP{
+			Endpoint: &url.URL{Scheme: "http", Host: "127.0.0.1:4318", Path: "/v1/metrics"},
+			Interval: time.Second * 30,
+			Timeout:  time.Second * 10,
+			TLS: &TLS{
+				MinimumVersion: TLSVersion{tls.VersionTLS12},
+			},
+		},
+	},
+	Usage: TelemetryUsage{
+		Interval: time.Hour * 24,
+		Period:   time.Hour * 24 * 30,
+		Timeout:  time.Second * 10,
+		TLS: &TLS{
+			MinimumVersion: TLSVersion{tls.VersionTLS12},
+		},
 	},
 }
@@ -15,6 +15,14 @@ type TOTP struct {
 	AllowedPeriods    []int    `koanf:"allowed_periods" json:"allowed_periods" jsonschema:"title=Allowed Periods,default=30" jsonschema_description:"List of periods the user is allowed to select in addition to the default."`
 
 	DisableReuseSecurityPolicy bool `koanf:"disable_reuse_security_policy" json:"disable_reuse_security_policy" jsonschema:"title=Disable Reuse Security Policy,default=false" jsonschema_description:"Disables the security policy that prevents reuse of a TOTP code."`
+
+	DriftCompensation TOTPDriftCompensation `koanf:"drift_compensation" json:"drift_compensation" jsonschema:"title=Drift Compensation" jsonschema_description:"Drift Compensation learns the clock drift of each TOTP device."`
+}
+
+// TOTPDriftCompensation represents the configuration related to learning the clock drift of each TOTP device.
+type TOTPDriftCompensation struct {
+	Enable  bool `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables learning and accepting the clock drift of each TOTP device."`
+	Maximum int  `koanf:"maximum" json:"maximum" jsonschema:"default=4,minimum=1,maximum=20,title=Maximum" jsonschema_description:"The maximum number of periods the learned clock drift of a TOTP device can offset the validation window by."`
 }
 
 var defaultTOTPSkew = 1
@@ -30,4 +38,7 @@ var DefaultTOTPConfiguration = TOTP{
 	AllowedAlgorithms: []string{TOTPAlgorithmSHA1},
 	AllowedDigits:     []int{6},
 	AllowedPeriods:    []int{30},
+	DriftCompensation: TOTPDriftCompensation{
+		Maximum: 4,
+	},
 }
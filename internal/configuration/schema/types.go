@@ -598,6 +598,16 @@ func (AccessControlRuleSubjects) JSONSchema() *jsonschema.Schema {
 
 type CSPTemplate string
 
+// HasNonce returns true if the template contains the nonce placeholder.
+func (t CSPTemplate) HasNonce() bool {
+	return strings.Contains(string(t), CSPTemplateNoncePlaceholder)
+}
+
+// WithNonce returns the template with the nonce placeholder replaced by the provided nonce.
+func (t CSPTemplate) WithNonce(nonce string) string {
+	return strings.ReplaceAll(string(t), CSPTemplateNoncePlaceholder, nonce)
+}
+
 var jsonschemaURI = jsonschema.Schema{
 	Type:   jsonschema.TypeString,
 	Format: jsonschema.FormatStringURI,
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"slices"
 	"time"
 
 	"github.com/go-webauthn/webauthn/protocol"
@@ -15,6 +16,55 @@ type WebAuthn struct {
 	UserVerification     protocol.UserVerificationRequirement `koanf:"user_verification" json:"user_verification" jsonschema:"default=preferred,enum=discouraged,enum=preferred,enum=required,title=User Verification" jsonschema_description:"The default user verification preference for all WebAuthn credentials."`
 
 	Timeout time.Duration `koanf:"timeout" json:"timeout" jsonschema:"default=60 seconds,title=Timeout" jsonschema_description:"The default timeout for all WebAuthn ceremonies."`
+
+	Filtering WebAuthnFiltering `koanf:"filtering" json:"filtering" jsonschema:"title=Filtering" jsonschema_description:"The WebAuthn credential filtering configuration."`
+
+	SelectionCriteria WebAuthnSelectionCriteria `koanf:"selection_criteria" json:"selection_criteria" jsonschema:"title=Selection Criteria" jsonschema_description:"The WebAuthn authenticator selection criteria used when registering credentials."`
+	PasskeyLogin      WebAuthnPasskeyLogin      `koanf:"passkey_login" json:"passkey_login" jsonschema:"title=Passkey Login" jsonschema_description:"The WebAuthn passkey login configuration."`
+}
+
+// WebAuthnSelectionCriteria represents the WebAuthn authenticator selection criteria used when registering
+// credentials.
+type WebAuthnSelectionCriteria struct {
+	Attachment      protocol.AuthenticatorAttachment `koanf:"attachment" json:"attachment" jsonschema:"enum=platform,enum=cross-platform,title=Attachment" jsonschema_description:"The authenticator attachment modality a credential must have to be registered."`
+	Discoverability protocol.ResidentKeyRequirement  `koanf:"discoverability" json:"discoverability" jsonschema:"enum=discouraged,enum=preferred,enum=required,title=Discoverability" jsonschema_description:"The requirement for registered credentials to be discoverable credentials also known as resident keys."`
+}
+
+// WebAuthnPasskeyLogin represents the WebAuthn passkey login configuration which allows discoverable credentials to
+// satisfy the first factor.
+type WebAuthnPasskeyLogin struct {
+	Enable           bool                                 `koanf:"enable" json:"enable" jsonschema:"default=false,title=Enable" jsonschema_description:"Enables logging in with a discoverable credential instead of a username and password."`
+	UserVerification protocol.UserVerificationRequirement `koanf:"user_verification" json:"user_verification" jsonschema:"default=required,enum=discouraged,enum=preferred,enum=required,title=User Verification" jsonschema_description:"The user verification requirement for logging in with a passkey."`
+
+	TwoFactorWithUserVerification bool `koanf:"two_factor_with_user_verification" json:"two_factor_with_user_verification" jsonschema:"default=false,title=Two Factor With User Verification" jsonschema_description:"Satisfies both factors when the authenticator verified the user while logging in with a passkey."`
+}
+
+// WebAuthnFiltering represents the WebAuthn credential filtering configuration.
+type WebAuthnFiltering struct {
+	ProhibitBackupEligibility bool                           `koanf:"prohibit_backup_eligibility" json:"prohibit_backup_eligibility" jsonschema:"default=false,title=Prohibit Backup Eligibility" jsonschema_description:"Prohibits the registration and use of credentials which are eligible to be backed up or synced such as multi-device passkeys."`
+	GroupPolicies             []WebAuthnFilteringGroupPolicy `koanf:"group_policies" json:"group_policies" jsonschema:"title=Group Policies" jsonschema_description:"The list of filtering policies which apply to users in specific groups instead of the top level policy."`
+}
+
+// WebAuthnFilteringGroupPolicy represents the WebAuthn credential filtering configuration which applies to users in
+// specific groups.
+type WebAuthnFilteringGroupPolicy struct {
+	Groups                    []string `koanf:"groups" json:"groups" jsonschema:"title=Groups" jsonschema_description:"The groups this filtering policy applies to."`
+	ProhibitBackupEligibility bool     `koanf:"prohibit_backup_eligibility" json:"prohibit_backup_eligibility" jsonschema:"default=false,title=Prohibit Backup Eligibility" jsonschema_description:"Prohibits the registration and use of credentials which are eligible to be backed up or synced such as multi-device passkeys."`
+}
+
+// ProhibitBackupEligibilityForGroups returns true if credentials which are eligible to be backed up are prohibited for
+// a user with the provided groups. The first group policy which includes any of the groups applies, otherwise the top
+// level policy applies.
+func (f *WebAuthnFiltering) ProhibitBackupEligibilityForGroups(groups []string) (prohibit bool) {
+	for _, policy := range f.GroupPolicies {
+		for _, group := range policy.Groups {
+			if slices.Contains(groups, group) {
+				return policy.ProhibitBackupEligibility
+			}
+		}
+	}
+
+	return f.ProhibitBackupEligibility
 }
 
 // DefaultWebAuthnConfiguration describes the default values for the WebAuthn.
@@ -24,4 +74,12 @@ var DefaultWebAuthnConfiguration = WebAuthn{
 
 	ConveyancePreference: protocol.PreferIndirectAttestation,
 	UserVerification:     protocol.VerificationPreferred,
+
+	SelectionCriteria: WebAuthnSelectionCriteria{
+		Attachment:      protocol.CrossPlatform,
+		Discoverability: protocol.ResidentKeyRequirementDiscouraged,
+	},
+	PasskeyLogin: WebAuthnPasskeyLogin{
+		UserVerification: protocol.VerificationRequired,
+	},
 }
@@ -6,7 +6,6 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/confmap"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/providers/posflag"
@@ -14,6 +13,7 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/kv"
 )
 
 // NewFileSource returns a configuration.Source configured to load from a specified path. If there is an issue
@@ -64,7 +64,7 @@ func (s *FileSource) Name() (name string) {
 
 // Merge the FileSource koanf.Koanf into the provided one.
 func (s *FileSource) Merge(ko *koanf.Koanf, _ *schema.StructValidator) (err error) {
-	return ko.Merge(s.koanf)
+	return koanfMerge(ko, s.koanf, s.lists)
 }
 
 // Load the Source into the FileSource koanf.Koanf.
@@ -83,7 +83,7 @@ func (s *FileSource) Load(val *schema.StructValidator) (err error) {
 		return s.loadDir(val)
 	}
 
-	return s.koanf.Load(FilteredFileProvider(s.path, s.filters...), yaml.Parser())
+	return s.koanf.Load(FilteredFileProvider(s.path, s.filters...), koanfParser(s.path))
 }
 
 func (s *FileSource) loadDir(_ *schema.StructValidator) (err error) {
@@ -101,8 +101,8 @@ func (s *FileSource) loadDir(_ *schema.StructValidator) (err error) {
 		name := entry.Name()
 
 		switch ext := filepath.Ext(name); ext {
-		case extYML, extYAML:
-			if err = s.koanf.Load(FilteredFileProvider(filepath.Join(s.path, name), s.filters...), yaml.Parser()); err != nil {
+		case extYML, extYAML, extTOML, extJSON:
+			if err = s.koanf.Load(FilteredFileProvider(filepath.Join(s.path, name), s.filters...), koanfParser(name), koanfLoadOptions(s.lists)...); err != nil {
 				return err
 			}
 		}
@@ -169,7 +169,7 @@ func (s *FileSource) readFilesDirectory(path string) (files []*File, err error)
 		name := entry.Name()
 
 		switch ext := filepath.Ext(name); ext {
-		case extYML, extYAML:
+		case extYML, extYAML, extTOML, extJSON:
 			if file, err = s.readFile(filepath.Join(s.path, name)); err != nil {
 				return nil, err
 			}
@@ -191,6 +191,74 @@ func (s *FileSource) GetBytesFilterNames() (names []string) {
 	return names
 }
 
+// NewKVSource returns a configuration.Source configured to load the YAML stored in the value of a key in a key value
+// store such as 'consul://127.0.0.1:8500/authelia/configuration.yml'. The value is parsed as TOML or JSON instead if
+// the key has the respective extension.
+func NewKVSource(reference string, filters ...BytesFilter) (source *KVSource) {
+	return &KVSource{
+		koanf:     koanf.New(constDelimiter),
+		reference: reference,
+		filters:   filters,
+	}
+}
+
+// Name of the Source.
+func (s *KVSource) Name() (name string) {
+	return fmt.Sprintf("key value store(%s)", s.reference)
+}
+
+// Merge the KVSource koanf.Koanf into the provided one.
+func (s *KVSource) Merge(ko *koanf.Koanf, _ *schema.StructValidator) (err error) {
+	return koanfMerge(ko, s.koanf, s.lists)
+}
+
+// Load the Source into the KVSource koanf.Koanf.
+func (s *KVSource) Load(_ *schema.StructValidator) (err error) {
+	return s.koanf.Load(FilteredKVProvider(s.reference, s.filters...), koanfParser(s.reference))
+}
+
+// NewPathSources returns a slice of configuration.Source configured to load from the specified paths which are files,
+// directories, or references to keys in a key value store.
+func NewPathSources(paths []string, filters []BytesFilter) (sources []Source) {
+	return NewPathSourcesWithListMergeStrategy(paths, filters, ListMergeReplace)
+}
+
+// NewPathSourcesWithListMergeStrategy returns a slice of configuration.Source configured to load from the specified
+// paths which are files, directories, or references to keys in a key value store, and which merge their lists with the
+// lists of the previously loaded sources using the ListMergeStrategy.
+func NewPathSourcesWithListMergeStrategy(paths []string, filters []BytesFilter, lists ListMergeStrategy) (sources []Source) {
+	for _, path := range paths {
+		if kv.IsReference(path) {
+			source := NewKVSource(path, filters...)
+
+			source.lists = lists
+
+			sources = append(sources, source)
+		} else {
+			source := NewFilteredFileSource(path, filters...)
+
+			source.lists = lists
+
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
+
+// NewListMergeStrategy returns the ListMergeStrategy with the provided name, the ListMergeReplace strategy is returned
+// if the name is empty.
+func NewListMergeStrategy(name string) (lists ListMergeStrategy, err error) {
+	switch lists = ListMergeStrategy(name); lists {
+	case "":
+		return ListMergeReplace, nil
+	case ListMergeReplace, ListMergeAppend:
+		return lists, nil
+	default:
+		return "", fmt.Errorf(errFmtListMergeStrategy, name, ListMergeReplace, ListMergeAppend)
+	}
+}
+
 // NewBytesSource returns a configuration.Source configured to load from a specified bytes.
 func NewBytesSource(data []byte) (source *BytesSource) {
 	return &BytesSource{
@@ -215,7 +283,7 @@ func (b *BytesSource) Load(val *schema.StructValidator) (err error) {
 		return nil
 	}
 
-	return b.koanf.Load(b, yaml.Parser())
+	return b.koanf.Load(b, koanfParser(""))
 }
 
 // ReadBytes reads the contents of a file on disk, passes it through any configured filters, and returns the bytes.
@@ -358,10 +426,7 @@ func (s *MapSource) Load(_ *schema.StructValidator) (err error) {
 
 // NewDefaultSources returns a slice of Source configured to load from specified YAML files.
 func NewDefaultSources(paths []string, prefix, delimiter string, additionalSources ...Source) (sources []Source) {
-	fileSources := NewFileSources(paths)
-	for _, source := range fileSources {
-		sources = append(sources, source)
-	}
+	sources = NewPathSources(paths, nil)
 
 	sources = append(sources, NewEnvironmentSource(prefix, delimiter))
 	sources = append(sources, NewSecretsSource(prefix, delimiter))
@@ -375,10 +440,7 @@ func NewDefaultSources(paths []string, prefix, delimiter string, additionalSourc
 
 // NewDefaultSourcesFiltered returns a slice of Source configured to load from specified YAML files.
 func NewDefaultSourcesFiltered(paths []string, filters []BytesFilter, prefix, delimiter string, additionalSources ...Source) (sources []Source) {
-	fileSources := NewFilteredFileSources(paths, filters)
-	for _, source := range fileSources {
-		sources = append(sources, source)
-	}
+	sources = NewPathSources(paths, filters)
 
 	sources = append(sources, NewEnvironmentSource(prefix, delimiter))
 	sources = append(sources, NewSecretsSource(prefix, delimiter))
@@ -390,16 +452,20 @@ func NewDefaultSourcesFiltered(paths []string, filters []BytesFilter, prefix, de
 	return sources
 }
 
-// NewDefaultSourcesWithDefaults returns a slice of Source configured to load from specified YAML files with additional sources.
-func NewDefaultSourcesWithDefaults(paths []string, filters []BytesFilter, prefix, delimiter string, defaults Source, additionalSources ...Source) (sources []Source) {
+// NewDefaultSourcesWithDefaults returns a slice of Source configured to load from specified YAML files with additional
+// sources, merging the lists of the YAML files using the ListMergeStrategy.
+func NewDefaultSourcesWithDefaults(paths []string, filters []BytesFilter, lists ListMergeStrategy, prefix, delimiter string, defaults Source, additionalSources ...Source) (sources []Source) {
 	if defaults != nil {
 		sources = []Source{defaults}
 	}
 
-	if len(filters) == 0 {
-		sources = append(sources, NewDefaultSources(paths, prefix, delimiter, additionalSources...)...)
-	} else {
-		sources = append(sources, NewDefaultSourcesFiltered(paths, filters, prefix, delimiter, additionalSources...)...)
+	sources = append(sources, NewPathSourcesWithListMergeStrategy(paths, filters, lists)...)
+
+	sources = append(sources, NewEnvironmentSource(prefix, delimiter))
+	sources = append(sources, NewSecretsSource(prefix, delimiter))
+
+	if len(additionalSources) != 0 {
+		sources = append(sources, additionalSources...)
 	}
 
 	return sources
@@ -14,11 +14,24 @@ type Source interface {
 	Load(val *schema.StructValidator) (err error)
 }
 
+// ListMergeStrategy describes how the lists of a file or key value store configuration.Source are merged with the lists
+// of the previously loaded sources.
+type ListMergeStrategy string
+
 // FileSource is a file configuration.Source.
 type FileSource struct {
 	koanf   *koanf.Koanf
 	path    string
 	filters []BytesFilter
+	lists   ListMergeStrategy
+}
+
+// KVSource is a key value store configuration.Source.
+type KVSource struct {
+	koanf     *koanf.Koanf
+	reference string
+	filters   []BytesFilter
+	lists     ListMergeStrategy
 }
 
 // BytesSource is a raw bytes configuration.Source.
@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/go-webauthn/webauthn/protocol"
+
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -18,7 +20,7 @@ func IsPolicyValid(policy string) (isValid bool) {
 
 // IsSubjectValid check if a subject is valid.
 func IsSubjectValid(subject string) (isValid bool) {
-	return subject == "" || strings.HasPrefix(subject, "user:") || strings.HasPrefix(subject, "group:") || strings.HasPrefix(subject, "oauth2:client:")
+	return subject == "" || strings.HasPrefix(subject, prefixSubjectUser) || strings.HasPrefix(subject, prefixSubjectGroup) || strings.HasPrefix(subject, "oauth2:client:")
 }
 
 // IsNetworkGroupValid check if a network group is valid.
@@ -69,11 +71,33 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 			}
 		}
 	}
+
+	validateAccessControlKubernetes(config, validator)
+}
+
+func validateAccessControlKubernetes(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.AccessControl.Kubernetes.Enable {
+		return
+	}
+
+	if config.AccessControl.Kubernetes.AnnotationPrefix == "" {
+		config.AccessControl.Kubernetes.AnnotationPrefix = schema.DefaultAccessControlKubernetesConfiguration.AnnotationPrefix
+	} else if strings.Contains(config.AccessControl.Kubernetes.AnnotationPrefix, "/") {
+		validator.Push(fmt.Errorf(errFmtAccessControlKubernetesAnnotationPrefix, config.AccessControl.Kubernetes.AnnotationPrefix))
+	}
+
+	if config.AccessControl.Kubernetes.SyncInterval <= 0 {
+		config.AccessControl.Kubernetes.SyncInterval = schema.DefaultAccessControlKubernetesConfiguration.SyncInterval
+	}
 }
 
 // ValidateRules validates an ACL Rule configuration.
 func ValidateRules(config *schema.Configuration, validator *schema.StructValidator) {
 	if len(config.AccessControl.Rules) == 0 {
+		if config.AccessControl.Kubernetes.Enable {
+			return
+		}
+
 		if config.AccessControl.DefaultPolicy != policyOneFactor && config.AccessControl.DefaultPolicy != policyTwoFactor {
 			validator.Push(fmt.Errorf(errFmtAccessControlDefaultPolicyWithoutRules, config.AccessControl.DefaultPolicy))
 
@@ -99,6 +123,44 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 			}
 		}
 
+		switch rule.Challenge {
+		case "":
+			config.AccessControl.Rules[i].Challenge = challengeAuto
+		default:
+			if !utils.IsStringInSlice(rule.Challenge, validACLRuleChallenges) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleInvalidChallenge, ruleDescriptor(rulePosition, rule), utils.StringJoinOr(validACLRuleChallenges), rule.Challenge))
+			}
+		}
+
+		switch rule.Device {
+		case "":
+			config.AccessControl.Rules[i].Device = deviceAny
+		case deviceManaged, deviceUnmanaged:
+			if !config.DeviceCertificates.Enabled {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleDeviceCertificatesDisabled, ruleDescriptor(rulePosition, rule), rule.Device))
+			}
+		default:
+			if !utils.IsStringInSlice(rule.Device, validACLRuleDevices) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleInvalidDevice, ruleDescriptor(rulePosition, rule), utils.StringJoinOr(validACLRuleDevices), rule.Device))
+			}
+		}
+
+		switch rule.WebAuthnUserVerification {
+		case "":
+			config.AccessControl.Rules[i].WebAuthnUserVerification = string(protocol.VerificationPreferred)
+		case string(protocol.VerificationRequired):
+			switch {
+			case rule.Policy != policyTwoFactor:
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleUserVerificationPolicy, ruleDescriptor(rulePosition, rule), rule.Policy))
+			case config.WebAuthn.Disable:
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleUserVerificationDisabled, ruleDescriptor(rulePosition, rule)))
+			}
+		default:
+			if !utils.IsStringInSlice(rule.WebAuthnUserVerification, validUserVerifications) {
+				validator.Push(fmt.Errorf(errFmtAccessControlRuleInvalidUserVerification, ruleDescriptor(rulePosition, rule), utils.StringJoinOr(validUserVerifications), rule.WebAuthnUserVerification))
+			}
+		}
+
 		validateNetworks(rulePosition, rule, config.AccessControl, validator)
 
 		validateSubjects(rulePosition, rule, validator)
@@ -107,12 +169,59 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 
 		validateQuery(i, rule, config, validator)
 
+		validateAuthnStrategies(i, rule, config, validator)
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
 	}
 }
 
+func validateAuthnStrategies(i int, rule schema.AccessControlRule, config *schema.Configuration, validator *schema.StructValidator) {
+	if len(rule.AuthnStrategies) == 0 {
+		return
+	}
+
+	if len(rule.Subjects) != 0 {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategiesCriteria, ruleDescriptor(i+1, rule), "subject"))
+	}
+
+	if rule.Device != "" && rule.Device != deviceAny {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategiesCriteria, ruleDescriptor(i+1, rule), "device"))
+	}
+
+	names := make([]string, 0, len(rule.AuthnStrategies))
+
+	for j, strategy := range rule.AuthnStrategies {
+		if strategy.Name != "" && utils.IsStringInSlice(strategy.Name, names) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategyDuplicate, ruleDescriptor(i+1, rule), strategy.Name))
+		}
+
+		names = append(names, strategy.Name)
+
+		switch {
+		case strategy.Name == "":
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategyNoName, ruleDescriptor(i+1, rule), j+1))
+		case !utils.IsStringInSlice(strategy.Name, validAuthzAuthnStrategies):
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategy, ruleDescriptor(i+1, rule), j+1, utils.StringJoinOr(validAuthzAuthnStrategies), strategy.Name))
+		case utils.IsStringInSlice(strategy.Name, validAuthzAuthnHeaderStrategies):
+			if len(strategy.Schemes) == 0 {
+				config.AccessControl.Rules[i].AuthnStrategies[j].Schemes = []string{schema.SchemeBasic}
+
+				continue
+			}
+
+			for _, scheme := range strategy.Schemes {
+				if !utils.IsStringInSliceFold(scheme, validAuthzAuthnStrategySchemes) {
+					validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategySchemes, ruleDescriptor(i+1, rule), j+1, strategy.Name, utils.StringJoinOr(validAuthzAuthnStrategySchemes), scheme))
+				}
+			}
+		case len(strategy.Schemes) != 0:
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleAuthnStrategySchemesInvalid, ruleDescriptor(i+1, rule), j+1, strategy.Name))
+		}
+	}
+}
+
 func validateBypass(rulePosition int, rule schema.AccessControlRule, validator *schema.StructValidator) {
 	if len(rule.Subjects) != 0 {
 		validator.Push(fmt.Errorf(errAccessControlRuleBypassPolicyInvalidWithSubjects, ruleDescriptor(rulePosition, rule)))
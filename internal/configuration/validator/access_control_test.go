@@ -116,6 +116,32 @@ func (suite *AccessControl) TestShouldRaiseErrorWithNoRulesDefined() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: 'default_policy' option 'deny' is invalid: when no rules are specified it must be 'two_factor' or 'one_factor'")
 }
 
+func (suite *AccessControl) TestShouldNotRaiseErrorWithNoRulesDefinedWithKubernetes() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{}
+	suite.config.AccessControl.Kubernetes.Enable = true
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultAccessControlKubernetesConfiguration.AnnotationPrefix, suite.config.AccessControl.Kubernetes.AnnotationPrefix)
+	suite.Assert().Equal(schema.DefaultAccessControlKubernetesConfiguration.SyncInterval, suite.config.AccessControl.Kubernetes.SyncInterval)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidKubernetesAnnotationPrefix() {
+	suite.config.AccessControl.Kubernetes.Enable = true
+	suite.config.AccessControl.Kubernetes.AnnotationPrefix = "authelia.com/"
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: kubernetes: option 'annotation_prefix' must not contain a '/' but it's configured as 'authelia.com/'")
+}
+
 func (suite *AccessControl) TestShouldRaiseWarningWithNoRulesDefined() {
 	suite.config.AccessControl.Rules = []schema.AccessControlRule{}
 
@@ -164,6 +190,243 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidPolicy() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'public.example.com'): option 'policy' must be one of 'bypass', 'one_factor', 'two_factor', or 'deny' but it's configured as 'invalid'")
 }
 
+func (suite *AccessControl) TestShouldSetDefaultChallenge() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  policyOneFactor,
+		},
+		{
+			Domains:   []string{"ws.example.com"},
+			Policy:    policyOneFactor,
+			Challenge: challengeRedirect,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(challengeAuto, suite.config.AccessControl.Rules[0].Challenge)
+	suite.Assert().Equal(challengeRedirect, suite.config.AccessControl.Rules[1].Challenge)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidChallenge() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains:   []string{"public.example.com"},
+			Policy:    policyOneFactor,
+			Challenge: testInvalid,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'public.example.com'): option 'challenge' must be one of 'auto', 'redirect', or 'status' but it's configured as 'invalid'")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultAuthnStrategySchemes() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"dav.example.com"},
+			Policy:  policyOneFactor,
+			AuthnStrategies: []schema.ServerEndpointsAuthzAuthnStrategy{
+				{Name: schema.AuthzStrategyHeaderAuthorization},
+				{Name: schema.AuthzStrategyHeaderProxyAuthorization, Schemes: []string{schema.SchemeBearer}},
+				{Name: schema.AuthzStrategyHeaderCookieSession},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal([]string{schema.SchemeBasic}, suite.config.AccessControl.Rules[0].AuthnStrategies[0].Schemes)
+	suite.Assert().Equal([]string{schema.SchemeBearer}, suite.config.AccessControl.Rules[0].AuthnStrategies[1].Schemes)
+	suite.Assert().Len(suite.config.AccessControl.Rules[0].AuthnStrategies[2].Schemes, 0)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidAuthnStrategies() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"dav.example.com"},
+			Policy:  policyOneFactor,
+			AuthnStrategies: []schema.ServerEndpointsAuthzAuthnStrategy{
+				{Name: ""},
+				{Name: testInvalid},
+				{Name: schema.AuthzStrategyHeaderAuthorization, Schemes: []string{testInvalid}},
+				{Name: schema.AuthzStrategyHeaderAuthorization},
+				{Name: schema.AuthzStrategyHeaderCookieSession, Schemes: []string{schema.SchemeBasic}},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'dav.example.com'): authn_strategies: strategy #1: option 'name' must be configured")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access_control: rule #1 (domain 'dav.example.com'): authn_strategies: strategy #2: option 'name' must be one of 'CookieSession', 'HeaderAuthorization', 'HeaderProxyAuthorization', 'HeaderAuthRequestProxyAuthorization', or 'HeaderLegacy' but it's configured as 'invalid'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access_control: rule #1 (domain 'dav.example.com'): authn_strategies: strategy #3 (HeaderAuthorization): option 'schemes' must only include the values 'basic' or 'bearer' but has 'invalid'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access_control: rule #1 (domain 'dav.example.com'): authn_strategies: duplicate strategy name detected with name 'HeaderAuthorization'")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access_control: rule #1 (domain 'dav.example.com'): authn_strategies: strategy #5 (CookieSession): option 'schemes' is not valid for the strategy")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorAuthnStrategiesWithSubjectOrDevice() {
+	suite.config.DeviceCertificates.Enabled = true
+
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains:  []string{"dav.example.com"},
+			Policy:   policyOneFactor,
+			Subjects: [][]string{{"group:dav"}},
+			AuthnStrategies: []schema.ServerEndpointsAuthzAuthnStrategy{
+				{Name: schema.AuthzStrategyHeaderAuthorization},
+			},
+		},
+		{
+			Domains: []string{"dav.example.com"},
+			Policy:  policyOneFactor,
+			Device:  deviceManaged,
+			AuthnStrategies: []schema.ServerEndpointsAuthzAuthnStrategy{
+				{Name: schema.AuthzStrategyHeaderAuthorization},
+			},
+		},
+		{
+			Domains: []string{"dav.example.com"},
+			Policy:  policyOneFactor,
+			Device:  deviceAny,
+			AuthnStrategies: []schema.ServerEndpointsAuthzAuthnStrategy{
+				{Name: schema.AuthzStrategyHeaderAuthorization},
+			},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'dav.example.com'): option 'authn_strategies' is not supported when option 'subject' is configured as the authentication strategies are determined before the subject is authenticated")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access_control: rule #2 (domain 'dav.example.com'): option 'authn_strategies' is not supported when option 'device' is configured as the authentication strategies are determined before the subject is authenticated")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultUserVerification() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  policyOneFactor,
+		},
+		{
+			Domains:                  []string{"secure.example.com"},
+			Policy:                   policyTwoFactor,
+			WebAuthnUserVerification: "required",
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal("preferred", suite.config.AccessControl.Rules[0].WebAuthnUserVerification)
+	suite.Assert().Equal("required", suite.config.AccessControl.Rules[1].WebAuthnUserVerification)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidUserVerification() {
+	suite.config.WebAuthn.Disable = true
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains:                  []string{"public.example.com"},
+			Policy:                   policyTwoFactor,
+			WebAuthnUserVerification: testInvalid,
+		},
+		{
+			Domains:                  []string{"one.example.com"},
+			Policy:                   policyOneFactor,
+			WebAuthnUserVerification: "required",
+		},
+		{
+			Domains:                  []string{"two.example.com"},
+			Policy:                   policyTwoFactor,
+			WebAuthnUserVerification: "required",
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 3)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'public.example.com'): option 'webauthn_user_verification' must be one of 'preferred' or 'required' but it's configured as 'invalid'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access_control: rule #2 (domain 'one.example.com'): option 'webauthn_user_verification' is configured as 'required' but option 'policy' is configured as 'one_factor' when it must be 'two_factor'")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access_control: rule #3 (domain 'two.example.com'): option 'webauthn_user_verification' is configured as 'required' but the 'webauthn' section is disabled")
+}
+
+func (suite *AccessControl) TestShouldSetDefaultDevice() {
+	suite.config.DeviceCertificates.Enabled = true
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  policyOneFactor,
+		},
+		{
+			Domains: []string{"managed.example.com"},
+			Policy:  policyOneFactor,
+			Device:  deviceManaged,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(deviceAny, suite.config.AccessControl.Rules[0].Device)
+	suite.Assert().Equal(deviceManaged, suite.config.AccessControl.Rules[1].Device)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidDevice() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  policyOneFactor,
+			Device:  testInvalid,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'public.example.com'): option 'device' must be one of 'any', 'managed', or 'unmanaged' but it's configured as 'invalid'")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorDeviceWithoutDeviceCertificates() {
+	suite.config.AccessControl.Rules = []schema.AccessControlRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  policyOneFactor,
+			Device:  deviceUnmanaged,
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access_control: rule #1 (domain 'public.example.com'): option 'device' is configured as 'unmanaged' but the 'device_certificates' section is not enabled")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidNetwork() {
 	suite.config.AccessControl.Rules = []schema.AccessControlRule{
 		{
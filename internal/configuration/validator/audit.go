@@ -0,0 +1,218 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateAudit validates and updates the audit configuration.
+func ValidateAudit(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Audit.Enabled {
+		return
+	}
+
+	if config.Audit.BufferSize <= 0 {
+		config.Audit.BufferSize = schema.DefaultAuditConfiguration.BufferSize
+	}
+
+	if len(config.Audit.Sinks) == 0 {
+		// The admin event stream is a valid destination for the events on its own.
+		if !config.Server.Admin.Enabled || !config.Server.Admin.EnableEvents {
+			validator.Push(errors.New(errFmtAuditNoSinks))
+		}
+
+		return
+	}
+
+	names := map[string]struct{}{}
+
+	for i := range config.Audit.Sinks {
+		validateAuditSink(i+1, &config.Audit.Sinks[i], validator)
+
+		if _, ok := names[config.Audit.Sinks[i].Name]; ok {
+			validator.Push(fmt.Errorf(errFmtAuditSinkNameDuplicate, i+1, config.Audit.Sinks[i].Name))
+		}
+
+		names[config.Audit.Sinks[i].Name] = struct{}{}
+	}
+}
+
+func validateAuditSink(n int, config *schema.AuditSink, validator *schema.StructValidator) {
+	var drivers []string
+
+	if config.File != nil {
+		drivers = append(drivers, audit.SinkTypeFile)
+	}
+
+	if config.Syslog != nil {
+		drivers = append(drivers, audit.SinkTypeSyslog)
+	}
+
+	if config.Webhook != nil {
+		drivers = append(drivers, audit.SinkTypeWebhook)
+	}
+
+	if config.Kafka != nil {
+		drivers = append(drivers, audit.SinkTypeKafka)
+	}
+
+	if config.Storage != nil {
+		drivers = append(drivers, audit.SinkTypeStorage)
+	}
+
+	switch len(drivers) {
+	case 0:
+		validator.Push(fmt.Errorf(errFmtAuditSinkNoDriver, n, config.Name))
+
+		return
+	case 1:
+		if config.Name == "" {
+			config.Name = drivers[0]
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtAuditSinkMultipleDrivers, n, config.Name, utils.StringJoinAnd(drivers)))
+
+		return
+	}
+
+	validateAuditSinkEvents(n, config.Name, "events", config.Events, validator)
+	validateAuditSinkEvents(n, config.Name, "exclude_events", config.ExcludeEvents, validator)
+
+	switch {
+	case config.File != nil:
+		validateAuditSinkFile(n, config.Name, config.File, validator)
+	case config.Syslog != nil:
+		validateAuditSinkSyslog(n, config.Name, config.Syslog, validator)
+	case config.Webhook != nil:
+		validateAuditSinkWebhook(n, config.Name, config.Webhook, validator)
+	case config.Kafka != nil:
+		validateAuditSinkKafka(n, config.Name, config.Kafka, validator)
+	case config.Storage != nil:
+		validateAuditSinkStorage(n, config.Name, config.Storage, validator)
+	}
+}
+
+func validateAuditSinkEvents(n int, name, option string, patterns []string, validator *schema.StructValidator) {
+	for _, pattern := range patterns {
+		if !audit.IsValidPattern(pattern) {
+			validator.Push(fmt.Errorf(errFmtAuditSinkEventsInvalid, n, name, option, pattern, utils.StringJoinAnd(audit.EventTypes)))
+		}
+	}
+}
+
+func validateAuditSinkFile(n int, name string, config *schema.AuditSinkFile, validator *schema.StructValidator) {
+	if config.Path == "" {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, audit.SinkTypeFile, "path"))
+	}
+}
+
+func validateAuditSinkSyslog(n int, name string, config *schema.AuditSinkSyslog, validator *schema.StructValidator) {
+	if config.Address == nil {
+		config.Address = schema.DefaultAuditSinkSyslogConfiguration.Address
+	}
+
+	if err := config.Address.ValidateListener(); err != nil {
+		validator.Push(fmt.Errorf(errFmtAuditSinkSyslogAddress, n, name, config.Address.String(), err))
+	}
+
+	if !config.Address.IsUnixDomainSocket() && config.Address.Port() == 0 {
+		config.Address.SetPort(schema.DefaultAuditSinkSyslogConfiguration.Address.Port())
+	}
+
+	if config.Facility == "" {
+		config.Facility = schema.DefaultAuditSinkSyslogConfiguration.Facility
+	} else if _, ok := logging.SyslogFacilities[config.Facility]; !ok {
+		validator.Push(fmt.Errorf(errFmtAuditSinkSyslogFacility, n, name, utils.StringJoinOr(syslogFacilityNames()), config.Facility))
+	}
+
+	if config.Tag == "" {
+		config.Tag = schema.DefaultAuditSinkSyslogConfiguration.Tag
+	}
+}
+
+func validateAuditSinkWebhook(n int, name string, config *schema.AuditSinkWebhook, validator *schema.StructValidator) {
+	if config.URL == nil {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, audit.SinkTypeWebhook, "url"))
+	} else if config.URL.Scheme != schemeHTTP && config.URL.Scheme != schemeHTTPS {
+		validator.Push(fmt.Errorf(errFmtAuditSinkWebhookURLScheme, n, name, config.URL.Scheme))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultAuditSinkWebhookConfiguration.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: schema.DefaultAuditSinkWebhookConfiguration.TLS.MinimumVersion,
+		MaximumVersion: schema.DefaultAuditSinkWebhookConfiguration.TLS.MaximumVersion,
+	}
+
+	if config.URL != nil {
+		configDefaultTLS.ServerName = config.URL.Hostname()
+	}
+
+	if err := ValidateTLSConfig(config.TLS, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtAuditSinkTLSConfigInvalid, n, name, audit.SinkTypeWebhook, err))
+	}
+}
+
+func validateAuditSinkKafka(n int, name string, config *schema.AuditSinkKafka, validator *schema.StructValidator) {
+	if len(config.Brokers) == 0 {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, audit.SinkTypeKafka, "brokers"))
+	}
+
+	if config.Topic == "" {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, audit.SinkTypeKafka, "topic"))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultAuditSinkKafkaConfiguration.Timeout
+	}
+
+	if config.TLS != nil {
+		configDefaultTLS := &schema.TLS{
+			MinimumVersion: schema.DefaultAuditSinkKafkaConfiguration.TLS.MinimumVersion,
+			MaximumVersion: schema.DefaultAuditSinkKafkaConfiguration.TLS.MaximumVersion,
+		}
+
+		if err := ValidateTLSConfig(config.TLS, configDefaultTLS); err != nil {
+			validator.Push(fmt.Errorf(errFmtAuditSinkTLSConfigInvalid, n, name, audit.SinkTypeKafka, err))
+		}
+	}
+
+	if config.SASL == nil {
+		return
+	}
+
+	switch {
+	case config.SASL.Mechanism == "":
+		config.SASL.Mechanism = schema.DefaultAuditSinkKafkaConfiguration.SASL.Mechanism
+	case !utils.IsStringInSlice(config.SASL.Mechanism, audit.SASLMechanisms):
+		validator.Push(fmt.Errorf(errFmtAuditSinkKafkaSASLInvalid, n, name, utils.StringJoinOr(audit.SASLMechanisms), config.SASL.Mechanism))
+	}
+
+	if config.SASL.Username == "" {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, "kafka: sasl", "username"))
+	}
+
+	if config.SASL.Password == "" {
+		validator.Push(fmt.Errorf(errFmtAuditSinkOptionRequired, n, name, "kafka: sasl", "password"))
+	}
+}
+
+func validateAuditSinkStorage(n int, name string, config *schema.AuditSinkStorage, validator *schema.StructValidator) {
+	switch {
+	case config.Retention < 0:
+		validator.Push(fmt.Errorf(errFmtAuditSinkStorageRetention, n, name, config.Retention))
+	case config.Retention == 0:
+		config.Retention = schema.DefaultAuditSinkStorageConfiguration.Retention
+	}
+}
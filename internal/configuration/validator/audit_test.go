@@ -0,0 +1,225 @@
+package validator
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldNotValidateAuditWhenDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateAudit(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, 0, config.Audit.BufferSize)
+}
+
+func TestShouldRaiseErrorWhenAuditHasNoSinks(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{Audit: schema.Audit{Enabled: true}}
+
+	ValidateAudit(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "audit: option 'sinks' must have at least one sink configured when the audit event bus is enabled")
+	assert.Equal(t, schema.DefaultAuditConfiguration.BufferSize, config.Audit.BufferSize)
+}
+
+func TestShouldNotRaiseErrorWhenAuditHasNoSinksWithAdminEvents(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Audit:  schema.Audit{Enabled: true},
+		Server: schema.Server{Admin: schema.ServerAdmin{Enabled: true, EnableEvents: true}},
+	}
+
+	ValidateAudit(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultAuditConfiguration.BufferSize, config.Audit.BufferSize)
+}
+
+func TestShouldSetDefaultAuditSinkValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Audit: schema.Audit{
+			Enabled: true,
+			Sinks: []schema.AuditSink{
+				{File: &schema.AuditSinkFile{Path: "/var/log/authelia/audit.log"}},
+				{Syslog: &schema.AuditSinkSyslog{Address: &schema.AddressUDP{Address: MustParseAddress("udp://syslog.example.com")}}},
+				{Webhook: &schema.AuditSinkWebhook{URL: MustParseURL("https://audit.example.com/events")}},
+				{Kafka: &schema.AuditSinkKafka{Brokers: []string{"kafka:9092"}, Topic: "authelia", SASL: &schema.AuditSinkKafkaSASL{Username: "authelia", Password: "secret"}}},
+				{Storage: &schema.AuditSinkStorage{}},
+			},
+		},
+	}
+
+	ValidateAudit(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.Equal(t, "file", config.Audit.Sinks[0].Name)
+	assert.Equal(t, "syslog", config.Audit.Sinks[1].Name)
+	assert.Equal(t, "webhook", config.Audit.Sinks[2].Name)
+	assert.Equal(t, "kafka", config.Audit.Sinks[3].Name)
+	assert.Equal(t, "storage", config.Audit.Sinks[4].Name)
+
+	assert.Equal(t, "udp://syslog.example.com:514", config.Audit.Sinks[1].Syslog.Address.String())
+	assert.Equal(t, "auth", config.Audit.Sinks[1].Syslog.Facility)
+	assert.Equal(t, "authelia", config.Audit.Sinks[1].Syslog.Tag)
+
+	assert.Equal(t, time.Second*5, config.Audit.Sinks[2].Webhook.Timeout)
+	require.NotNil(t, config.Audit.Sinks[2].Webhook.TLS)
+	assert.Equal(t, "audit.example.com", config.Audit.Sinks[2].Webhook.TLS.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.Audit.Sinks[2].Webhook.TLS.MinimumVersion.Value)
+
+	assert.Equal(t, time.Second*10, config.Audit.Sinks[3].Kafka.Timeout)
+	assert.Nil(t, config.Audit.Sinks[3].Kafka.TLS)
+	assert.Equal(t, "plain", config.Audit.Sinks[3].Kafka.SASL.Mechanism)
+
+	assert.Equal(t, time.Hour*24*90, config.Audit.Sinks[4].Storage.Retention)
+}
+
+func TestShouldSetDefaultAuditSinkSyslogAddress(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Audit: schema.Audit{
+			Enabled: true,
+			Sinks: []schema.AuditSink{
+				{Name: "local", Syslog: &schema.AuditSinkSyslog{Facility: "local0", Tag: "sso"}},
+			},
+		},
+	}
+
+	ValidateAudit(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "local", config.Audit.Sinks[0].Name)
+	assert.Equal(t, "udp://127.0.0.1:514", config.Audit.Sinks[0].Syslog.Address.String())
+	assert.Equal(t, "local0", config.Audit.Sinks[0].Syslog.Facility)
+	assert.Equal(t, "sso", config.Audit.Sinks[0].Syslog.Tag)
+}
+
+func TestShouldRaiseErrorsOnInvalidAuditSinks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		sinks    []schema.AuditSink
+		expected []string
+	}{
+		{
+			"ShouldRaiseErrorOnNoDriver",
+			[]schema.AuditSink{{Name: "example"}},
+			[]string{
+				"audit: sinks: sink #1 (example): must have one of the 'file', 'syslog', 'webhook', 'kafka', or 'storage' options configured",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnMultipleDrivers",
+			[]schema.AuditSink{{Name: "example", File: &schema.AuditSinkFile{Path: "/audit.log"}, Kafka: &schema.AuditSinkKafka{}}},
+			[]string{
+				"audit: sinks: sink #1 (example): must only have one of the 'file', 'syslog', 'webhook', 'kafka', or 'storage' options configured but has 'file' and 'kafka' configured",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnDuplicateNames",
+			[]schema.AuditSink{{File: &schema.AuditSinkFile{Path: "/audit.log"}}, {File: &schema.AuditSinkFile{Path: "/audit2.log"}}},
+			[]string{
+				"audit: sinks: sink #2 (file): option 'name' must be unique but another sink has the same name",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidEvents",
+			[]schema.AuditSink{{File: &schema.AuditSinkFile{Path: "/audit.log"}, Events: []string{"authentication.*", "abc"}, ExcludeEvents: []string{"totp"}}},
+			[]string{
+				"audit: sinks: sink #1 (file): option 'events' has the value 'abc' which doesn't match any event types, the event types are 'authentication.first_factor', 'authentication.second_factor', 'authorization.decision', 'session.logout', 'session.elevation', 'password.reset', 'totp.register', 'totp.delete', 'webauthn.register', 'webauthn.update', 'webauthn.delete', 'preferences.update', and 'oidc.consent'",
+				"audit: sinks: sink #1 (file): option 'exclude_events' has the value 'totp' which doesn't match any event types, the event types are 'authentication.first_factor', 'authentication.second_factor', 'authorization.decision', 'session.logout', 'session.elevation', 'password.reset', 'totp.register', 'totp.delete', 'webauthn.register', 'webauthn.update', 'webauthn.delete', 'preferences.update', and 'oidc.consent'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnFileWithoutPath",
+			[]schema.AuditSink{{File: &schema.AuditSinkFile{}}},
+			[]string{
+				"audit: sinks: sink #1 (file): file: option 'path' is required",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnSyslogInvalid",
+			[]schema.AuditSink{{Syslog: &schema.AuditSinkSyslog{Address: &schema.AddressUDP{Address: MustParseAddress("ldap://127.0.0.1")}, Facility: "abc"}}},
+			[]string{
+				"audit: sinks: sink #1 (syslog): syslog: option 'address' with value 'ldap://127.0.0.1:389' is invalid: scheme must be one of 'tcp', 'tcp4', 'tcp6', 'udp', 'udp4', 'udp6', or 'unix' but is configured as 'ldap'",
+				"audit: sinks: sink #1 (syslog): syslog: option 'facility' must be one of 'auth', 'authpriv', 'cron', 'daemon', 'ftp', 'kern', 'local0', 'local1', 'local2', 'local3', 'local4', 'local5', 'local6', 'local7', 'lpr', 'mail', 'news', 'syslog', 'user', or 'uucp' but it's configured as 'abc'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnWebhookWithoutURL",
+			[]schema.AuditSink{{Webhook: &schema.AuditSinkWebhook{}}},
+			[]string{
+				"audit: sinks: sink #1 (webhook): webhook: option 'url' is required",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnWebhookInvalidScheme",
+			[]schema.AuditSink{{Webhook: &schema.AuditSinkWebhook{URL: MustParseURL("ftp://audit.example.com")}}},
+			[]string{
+				"audit: sinks: sink #1 (webhook): webhook: option 'url' must have a scheme of 'http' or 'https' but it's configured as 'ftp'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnWebhookInvalidTLS",
+			[]schema.AuditSink{{Webhook: &schema.AuditSinkWebhook{URL: MustParseURL("https://audit.example.com"), TLS: &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS13}, MaximumVersion: schema.TLSVersion{Value: tls.VersionTLS12}}}}},
+			[]string{
+				"audit: sinks: sink #1 (webhook): webhook: tls: option combination of 'minimum_version' and 'maximum_version' is invalid: minimum version TLS1.3 is greater than the maximum version TLS1.2",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnKafkaRequired",
+			[]schema.AuditSink{{Kafka: &schema.AuditSinkKafka{SASL: &schema.AuditSinkKafkaSASL{}}}},
+			[]string{
+				"audit: sinks: sink #1 (kafka): kafka: option 'brokers' is required",
+				"audit: sinks: sink #1 (kafka): kafka: option 'topic' is required",
+				"audit: sinks: sink #1 (kafka): kafka: sasl: option 'username' is required",
+				"audit: sinks: sink #1 (kafka): kafka: sasl: option 'password' is required",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnKafkaInvalidSASLMechanism",
+			[]schema.AuditSink{{Kafka: &schema.AuditSinkKafka{Brokers: []string{"kafka:9092"}, Topic: "authelia", SASL: &schema.AuditSinkKafkaSASL{Mechanism: "gssapi", Username: "authelia", Password: "secret"}}}},
+			[]string{
+				"audit: sinks: sink #1 (kafka): kafka: sasl: option 'mechanism' must be one of 'plain', 'scram-sha-256', or 'scram-sha-512' but it's configured as 'gssapi'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnStorageNegativeRetention",
+			[]schema.AuditSink{{Storage: &schema.AuditSinkStorage{Retention: -time.Hour}}},
+			[]string{
+				"audit: sinks: sink #1 (storage): storage: option 'retention' must be 0 or more but it's configured as '-1h0m0s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Audit: schema.Audit{Enabled: true, Sinks: tc.sinks}}
+
+			ValidateAudit(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
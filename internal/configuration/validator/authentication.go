@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/go-crypt/crypt/algorithm/argon2"
@@ -11,14 +12,20 @@ import (
 	"github.com/go-crypt/crypt/algorithm/scrypt"
 	"github.com/go-crypt/crypt/algorithm/shacrypt"
 
+	"github.com/authelia/authelia/v4/internal/authentication/balloon"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateAuthenticationBackend validates and updates the authentication backend configuration.
 func ValidateAuthenticationBackend(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
-	if config.LDAP == nil && config.File == nil {
+	switch countAuthenticationBackends(config) {
+	case 0:
 		validator.Push(errors.New(errFmtAuthBackendNotConfigured))
+	case 1:
+		break
+	default:
+		validator.Push(errors.New(errFmtAuthBackendMultipleConfigured))
 	}
 
 	if !config.RefreshInterval.Valid() {
@@ -38,10 +45,6 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackend, validat
 		}
 	}
 
-	if config.LDAP != nil && config.File != nil {
-		validator.Push(errors.New(errFmtAuthBackendMultipleConfigured))
-	}
-
 	if config.File != nil {
 		validateFileAuthenticationBackend(config.File, validator)
 	}
@@ -49,6 +52,70 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackend, validat
 	if config.LDAP != nil {
 		validateLDAPAuthenticationBackend(config, validator)
 	}
+
+	if config.SQL != nil {
+		ValidatePasswordConfiguration(&config.SQL.Password, validator)
+	}
+
+	if len(config.LDAPBackends) != 0 {
+		validateLDAPBackendsAuthenticationBackend(config, validator)
+	}
+
+	validateSPNEGOAuthenticationBackend(&config.SPNEGO, validator)
+}
+
+// validateSPNEGOAuthenticationBackend validates and updates the SPNEGO authentication configuration.
+func validateSPNEGOAuthenticationBackend(config *schema.AuthenticationBackendSPNEGO, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	if config.Keytab == "" {
+		validator.Push(errors.New(errFmtSPNEGOKeytabNotConfigured))
+	}
+
+	if config.UsernameFormat == "" {
+		config.UsernameFormat = schema.DefaultAuthenticationBackendSPNEGO.UsernameFormat
+	} else if !strings.Contains(config.UsernameFormat, "{username}") {
+		validator.Push(fmt.Errorf(errFmtSPNEGOUsernameFormat, config.UsernameFormat))
+	}
+
+	if len(config.Realms) == 0 && !strings.Contains(config.UsernameFormat, "{realm}") {
+		validator.Push(fmt.Errorf(errFmtSPNEGORealmsRequired, config.UsernameFormat))
+	}
+
+	if config.MaxClockSkew <= 0 {
+		config.MaxClockSkew = schema.DefaultAuthenticationBackendSPNEGO.MaxClockSkew
+	}
+
+	for _, realm := range config.Realms {
+		if realm == "" {
+			validator.Push(errors.New(errFmtSPNEGORealmEmpty))
+
+			break
+		}
+	}
+}
+
+// countAuthenticationBackends returns the number of configured authentication backends.
+func countAuthenticationBackends(config *schema.AuthenticationBackend) (n int) {
+	if config.File != nil {
+		n++
+	}
+
+	if config.LDAP != nil {
+		n++
+	}
+
+	if config.SQL != nil {
+		n++
+	}
+
+	if len(config.LDAPBackends) != 0 {
+		n++
+	}
+
+	return n
 }
 
 // validateFileAuthenticationBackend validates and updates the file authentication backend configuration.
@@ -78,6 +145,7 @@ func ValidatePasswordConfiguration(config *schema.AuthenticationBackendFilePassw
 	validateFileAuthenticationBackendPasswordConfigPBKDF2(config, validator)
 	validateFileAuthenticationBackendPasswordConfigBCrypt(config, validator)
 	validateFileAuthenticationBackendPasswordConfigSCrypt(config, validator)
+	validateFileAuthenticationBackendPasswordConfigBalloon(config, validator)
 }
 
 //nolint:gocyclo // Function is well formed.
@@ -265,6 +333,54 @@ func validateFileAuthenticationBackendPasswordConfigSCrypt(config *schema.Authen
 	}
 }
 
+//nolint:gocyclo
+func validateFileAuthenticationBackendPasswordConfigBalloon(config *schema.AuthenticationBackendFilePassword, validator *schema.StructValidator) {
+	switch {
+	case config.Balloon.Variant == "":
+		config.Balloon.Variant = schema.DefaultPasswordConfig.Balloon.Variant
+	case utils.IsStringInSlice(config.Balloon.Variant, validBalloonVariants):
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordInvalidVariant, hashBalloon, utils.StringJoinOr(validBalloonVariants), config.Balloon.Variant))
+	}
+
+	switch {
+	case config.Balloon.SpaceCost == 0:
+		config.Balloon.SpaceCost = schema.DefaultPasswordConfig.Balloon.SpaceCost
+	case config.Balloon.SpaceCost < balloon.SpaceCostMin:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooSmall, hashBalloon, "space_cost", config.Balloon.SpaceCost, balloon.SpaceCostMin))
+	case config.Balloon.SpaceCost > balloon.SpaceCostMax:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooLarge, hashBalloon, "space_cost", config.Balloon.SpaceCost, balloon.SpaceCostMax))
+	}
+
+	switch {
+	case config.Balloon.TimeCost == 0:
+		config.Balloon.TimeCost = schema.DefaultPasswordConfig.Balloon.TimeCost
+	case config.Balloon.TimeCost < balloon.TimeCostMin:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooSmall, hashBalloon, "time_cost", config.Balloon.TimeCost, balloon.TimeCostMin))
+	case config.Balloon.TimeCost > balloon.TimeCostMax:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooLarge, hashBalloon, "time_cost", config.Balloon.TimeCost, balloon.TimeCostMax))
+	}
+
+	switch {
+	case config.Balloon.Delta == 0:
+		config.Balloon.Delta = schema.DefaultPasswordConfig.Balloon.Delta
+	case config.Balloon.Delta < balloon.DeltaMin:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooSmall, hashBalloon, "delta", config.Balloon.Delta, balloon.DeltaMin))
+	case config.Balloon.Delta > balloon.DeltaMax:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooLarge, hashBalloon, "delta", config.Balloon.Delta, balloon.DeltaMax))
+	}
+
+	switch {
+	case config.Balloon.SaltLength == 0:
+		config.Balloon.SaltLength = schema.DefaultPasswordConfig.Balloon.SaltLength
+	case config.Balloon.SaltLength < balloon.SaltLengthMin:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooSmall, hashBalloon, "salt_length", config.Balloon.SaltLength, balloon.SaltLengthMin))
+	case config.Balloon.SaltLength > balloon.SaltLengthMax:
+		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordOptionTooLarge, hashBalloon, "salt_length", config.Balloon.SaltLength, balloon.SaltLengthMax))
+	}
+}
+
 //nolint:gocyclo,staticcheck // Function is clear enough and being used for deprecated functionality mapping.
 func validateFileAuthenticationBackendPasswordConfigLegacy(config *schema.AuthenticationBackendFilePassword) {
 	switch config.Algorithm {
@@ -344,9 +460,79 @@ func validateLDAPAuthenticationBackend(config *schema.AuthenticationBackend, val
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendFilterReplacedPlaceholders, "groups_filter", "{1}", "{username}"))
 	}
 
+	validateLDAPAuthenticationBackendPooling(config.LDAP)
+
 	validateLDAPRequiredParameters(config, validator)
 }
 
+func validateLDAPAuthenticationBackendPooling(config *schema.AuthenticationBackendLDAP) {
+	if !config.Pooling.Enable {
+		return
+	}
+
+	if config.Pooling.Count <= 0 {
+		config.Pooling.Count = schema.DefaultLDAPAuthenticationBackendConfigurationPooling.Count
+	}
+
+	if config.Pooling.Retries <= 0 {
+		config.Pooling.Retries = schema.DefaultLDAPAuthenticationBackendConfigurationPooling.Retries
+	}
+
+	if config.Pooling.Timeout <= 0 {
+		config.Pooling.Timeout = schema.DefaultLDAPAuthenticationBackendConfigurationPooling.Timeout
+	}
+}
+
+// validateLDAPBackendsAuthenticationBackend validates each of the multiple LDAP backends with the same validation as
+// the single LDAP backend, prefixing the errors and warnings with the name of the backend.
+func validateLDAPBackendsAuthenticationBackend(config *schema.AuthenticationBackend, validator *schema.StructValidator) {
+	var (
+		names   []string
+		domains = map[string]string{}
+	)
+
+	for i := range config.LDAPBackends {
+		backend := &config.LDAPBackends[i]
+
+		name := fmt.Sprintf("#%d", i+1)
+
+		switch {
+		case backend.Name == "":
+			validator.Push(fmt.Errorf(errFmtLDAPBackendsMissingName, name))
+		case slices.Contains(names, backend.Name):
+			validator.Push(fmt.Errorf(errFmtLDAPBackendsDuplicateName, backend.Name))
+		default:
+			name = backend.Name
+		}
+
+		names = append(names, backend.Name)
+
+		for j, domain := range backend.Domains {
+			backend.Domains[j] = strings.ToLower(domain)
+
+			if !reDomainCharacters.MatchString(backend.Domains[j]) {
+				validator.Push(fmt.Errorf(errFmtLDAPBackendsInvalidDomain, name, domain))
+			} else if other, ok := domains[backend.Domains[j]]; ok {
+				validator.Push(fmt.Errorf(errFmtLDAPBackendsDuplicateDomain, name, backend.Domains[j], other))
+			} else {
+				domains[backend.Domains[j]] = name
+			}
+		}
+
+		sub := schema.NewStructValidator()
+
+		validateLDAPAuthenticationBackend(&schema.AuthenticationBackend{PasswordReset: config.PasswordReset, LDAP: &backend.AuthenticationBackendLDAP}, sub)
+
+		for _, err := range sub.Errors() {
+			validator.Push(fmt.Errorf(errFmtLDAPBackendsBackend, name, strings.TrimPrefix(err.Error(), errPrefixLDAPAuthBackend)))
+		}
+
+		for _, err := range sub.Warnings() {
+			validator.PushWarning(fmt.Errorf(errFmtLDAPBackendsBackend, name, strings.TrimPrefix(err.Error(), errPrefixLDAPAuthBackend)))
+		}
+	}
+}
+
 func validateLDAPAuthenticationBackendImplementation(config *schema.AuthenticationBackend, validator *schema.StructValidator) *schema.TLS {
 	var implementation *schema.AuthenticationBackendLDAP
 
@@ -25,7 +25,7 @@ func TestShouldRaiseErrorWhenBothBackendsProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 7)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file' or 'ldap' backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', 'sql', or 'ldap_backends' backend is configured")
 	assert.EqualError(t, validator.Errors()[1], "authentication_backend: ldap: option 'address' is required")
 	assert.EqualError(t, validator.Errors()[2], "authentication_backend: ldap: option 'user' is required")
 	assert.EqualError(t, validator.Errors()[3], "authentication_backend: ldap: option 'password' is required")
@@ -41,7 +41,33 @@ func TestShouldRaiseErrorWhenNoBackendProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file' or 'ldap' authentication backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file', 'ldap', 'sql', or 'ldap_backends' authentication backend is configured")
+}
+
+func TestShouldValidateSQLBackend(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackend{
+		SQL: &schema.AuthenticationBackendSQL{},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Equal(t, schema.DefaultPasswordConfig.Algorithm, backendConfig.SQL.Password.Algorithm)
+	assert.Equal(t, schema.DefaultPasswordConfig.Argon2, backendConfig.SQL.Password.Argon2)
+
+	validator = schema.NewStructValidator()
+	backendConfig = schema.AuthenticationBackend{
+		File: &schema.AuthenticationBackendFile{Path: "/tmp"},
+		SQL:  &schema.AuthenticationBackendSQL{Password: schema.AuthenticationBackendFilePassword{Algorithm: "md5"}},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', 'sql', or 'ldap_backends' backend is configured")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: file: password: option 'algorithm' must be one of 'sha2crypt', 'pbkdf2', 'scrypt', 'bcrypt', 'balloon', or 'argon2' but it's configured as 'md5'")
 }
 
 type FileBasedAuthenticationBackend struct {
@@ -440,6 +466,64 @@ func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenSCryptOptio
 	suite.EqualError(suite.validator.Errors()[4], "authentication_backend: file: password: scrypt: option 'salt_length' is configured as '2147483647' but must be less than or equal to '1024'")
 }
 
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorOnInvalidBalloonVariant() {
+	suite.config.File.Password = schema.AuthenticationBackendFilePassword{}
+	suite.config.File.Password.Algorithm = "balloon"
+	suite.config.File.Password.Balloon.Variant = testInvalid
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: balloon: option 'variant' must be one of 'sha256' or 'sha512' but it's configured as 'invalid'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultBalloonValues() {
+	suite.config.File.Password = schema.AuthenticationBackendFilePassword{Algorithm: "balloon"}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Len(suite.validator.Errors(), 0)
+
+	suite.Equal(schema.DefaultPasswordConfig.Balloon, suite.config.File.Password.Balloon)
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenBalloonOptionsTooLow() {
+	suite.config.File.Password.Balloon.SpaceCost = -1
+	suite.config.File.Password.Balloon.TimeCost = -1
+	suite.config.File.Password.Balloon.Delta = -1
+	suite.config.File.Password.Balloon.SaltLength = 7
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: balloon: option 'space_cost' is configured as '-1' but must be greater than or equal to '1'")
+	suite.EqualError(suite.validator.Errors()[1], "authentication_backend: file: password: balloon: option 'time_cost' is configured as '-1' but must be greater than or equal to '1'")
+	suite.EqualError(suite.validator.Errors()[2], "authentication_backend: file: password: balloon: option 'delta' is configured as '-1' but must be greater than or equal to '1'")
+	suite.EqualError(suite.validator.Errors()[3], "authentication_backend: file: password: balloon: option 'salt_length' is configured as '7' but must be greater than or equal to '8'")
+}
+
+func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenBalloonOptionsTooHigh() {
+	suite.config.File.Password.Balloon.SpaceCost = 2147483648
+	suite.config.File.Password.Balloon.TimeCost = 2147483648
+	suite.config.File.Password.Balloon.Delta = 1025
+	suite.config.File.Password.Balloon.SaltLength = 1025
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: balloon: option 'space_cost' is configured as '2147483648' but must be less than or equal to '2147483647'")
+	suite.EqualError(suite.validator.Errors()[1], "authentication_backend: file: password: balloon: option 'time_cost' is configured as '2147483648' but must be less than or equal to '2147483647'")
+	suite.EqualError(suite.validator.Errors()[2], "authentication_backend: file: password: balloon: option 'delta' is configured as '1025' but must be less than or equal to '1024'")
+	suite.EqualError(suite.validator.Errors()[3], "authentication_backend: file: password: balloon: option 'salt_length' is configured as '1025' but must be less than or equal to '1024'")
+}
+
 func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenArgon2OptionsTooLow() {
 	suite.config.File.Password.Argon2.Iterations = -1
 	suite.config.File.Password.Argon2.Memory = -1
@@ -510,7 +594,7 @@ func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenBadAlgorith
 	suite.Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 1)
 
-	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' must be one of 'sha2crypt', 'pbkdf2', 'scrypt', 'bcrypt', or 'argon2' but it's configured as 'bogus'")
+	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: file: password: option 'algorithm' must be one of 'sha2crypt', 'pbkdf2', 'scrypt', 'bcrypt', 'balloon', or 'argon2' but it's configured as 'bogus'")
 }
 
 func (suite *FileBasedAuthenticationBackend) TestShouldSetDefaultValues() {
@@ -967,6 +1051,25 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldNotAllowTLSVerMinGreaterT
 	suite.EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: tls: option combination of 'minimum_version' and 'maximum_version' is invalid: minimum version TLS1.3 is greater than the maximum version TLS1.2")
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultPooling() {
+	suite.config.LDAP.Pooling = schema.AuthenticationBackendLDAPPooling{Enable: true}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Len(suite.validator.Errors(), 0)
+
+	suite.Equal(schema.AuthenticationBackendLDAPPooling{Enable: true, Count: 5, Retries: 2, Timeout: time.Second * 10}, suite.config.LDAP.Pooling)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldNotSetDefaultPoolingWhenDisabled() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Errors(), 0)
+
+	suite.Equal(schema.AuthenticationBackendLDAPPooling{}, suite.config.LDAP.Pooling)
+}
+
 func TestLDAPAuthenticationBackend(t *testing.T) {
 	suite.Run(t, new(LDAPAuthenticationBackendSuite))
 }
@@ -1284,3 +1387,182 @@ func (suite *LDAPImplementationSuite) NotEqualImplementationDefaults(expected sc
 		suite.NotEqual(expected.Attributes.MemberOf, suite.config.LDAP.Attributes.MemberOf)
 	}
 }
+
+func TestShouldValidateLDAPBackends(t *testing.T) {
+	newBackend := func(name string, domains ...string) schema.AuthenticationBackendLDAPBackend {
+		return schema.AuthenticationBackendLDAPBackend{
+			Name:    name,
+			Domains: domains,
+			AuthenticationBackendLDAP: schema.AuthenticationBackendLDAP{
+				Address:      &schema.AddressLDAP{Address: *testLDAPAddress},
+				User:         testLDAPUser,
+				Password:     testLDAPPassword,
+				BaseDN:       testLDAPBaseDN,
+				UsersFilter:  "({username_attribute}={input})",
+				GroupsFilter: "(cn={input})",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		have     func() schema.AuthenticationBackend
+		expected func(t *testing.T, config *schema.AuthenticationBackend)
+		errs     []string
+	}{
+		{
+			"ShouldValidateAndSetDefaults",
+			func() schema.AuthenticationBackend {
+				return schema.AuthenticationBackend{
+					LDAPBackends: []schema.AuthenticationBackendLDAPBackend{newBackend("corp", "Corp.Example.com"), newBackend("legacy")},
+				}
+			},
+			func(t *testing.T, config *schema.AuthenticationBackend) {
+				assert.Equal(t, []string{"corp.example.com"}, config.LDAPBackends[0].Domains)
+				assert.Equal(t, schema.LDAPImplementationCustom, config.LDAPBackends[0].Implementation)
+				assert.Equal(t, schema.LDAPImplementationCustom, config.LDAPBackends[1].Implementation)
+				assert.Equal(t, time.Second*5, config.LDAPBackends[1].Timeout)
+				assert.Equal(t, "uid", config.LDAPBackends[1].Attributes.Username)
+			},
+			nil,
+		},
+		{
+			"ShouldErrorWhenConfiguredWithLDAP",
+			func() schema.AuthenticationBackend {
+				backend := newBackend("legacy")
+
+				return schema.AuthenticationBackend{
+					LDAP:         &backend.AuthenticationBackendLDAP,
+					LDAPBackends: []schema.AuthenticationBackendLDAPBackend{newBackend("corp")},
+				}
+			},
+			nil,
+			[]string{
+				"authentication_backend: please ensure only one of the 'file', 'ldap', 'sql', or 'ldap_backends' backend is configured",
+			},
+		},
+		{
+			"ShouldErrorOnNamesAndDomains",
+			func() schema.AuthenticationBackend {
+				return schema.AuthenticationBackend{
+					LDAPBackends: []schema.AuthenticationBackendLDAPBackend{
+						newBackend("corp", "example.com"),
+						newBackend("corp", "EXAMPLE.com"),
+						newBackend("", "@example.org"),
+					},
+				}
+			},
+			nil,
+			[]string{
+				"authentication_backend: ldap_backends: corp: option 'name' must be unique but it's configured for more than one backend",
+				"authentication_backend: ldap_backends: #2: option 'domains' contains the domain 'example.com' which is already configured for the backend 'corp'",
+				"authentication_backend: ldap_backends: #3: option 'name' is required",
+				"authentication_backend: ldap_backends: #3: option 'domains' must only contain valid domains but it contains '@example.org'",
+			},
+		},
+		{
+			"ShouldPrefixBackendErrors",
+			func() schema.AuthenticationBackend {
+				backend := newBackend("corp")
+				backend.BaseDN = ""
+				backend.Implementation = "bad"
+
+				return schema.AuthenticationBackend{
+					LDAPBackends: []schema.AuthenticationBackendLDAPBackend{backend},
+				}
+			},
+			nil,
+			[]string{
+				"authentication_backend: ldap_backends: corp: option 'implementation' must be one of 'custom', 'activedirectory', 'rfc2307bis', 'freeipa', 'lldap', or 'glauth' but it's configured as 'bad'",
+				"authentication_backend: ldap_backends: corp: option 'base_dn' is required",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := tc.have()
+
+			ValidateAuthenticationBackend(&config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+
+			if tc.expected != nil {
+				tc.expected(t, &config)
+			}
+		})
+	}
+}
+
+func TestShouldValidateSPNEGOAuthenticationBackend(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.AuthenticationBackendSPNEGO
+		expected schema.AuthenticationBackendSPNEGO
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.AuthenticationBackendSPNEGO{UsernameFormat: "bad"},
+			schema.AuthenticationBackendSPNEGO{UsernameFormat: "bad"},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", Realms: []string{"EXAMPLE.COM"}},
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", Realms: []string{"EXAMPLE.COM"}, UsernameFormat: "{username}", MaxClockSkew: time.Minute * 5},
+			nil,
+		},
+		{
+			"ShouldNotRequireRealmsWhenFormatHasRealm",
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", UsernameFormat: "{username}@{realm}"},
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", UsernameFormat: "{username}@{realm}", MaxClockSkew: time.Minute * 5},
+			nil,
+		},
+		{
+			"ShouldErrorOnMissingRealms",
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab"},
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", UsernameFormat: "{username}", MaxClockSkew: time.Minute * 5},
+			[]string{
+				"authentication_backend: spnego: option 'realms' is required when the option 'username_format' does not contain the '{realm}' placeholder but it's configured as '{username}'",
+			},
+		},
+		{
+			"ShouldNotOverrideConfiguredValues",
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", Realms: []string{"EXAMPLE.COM"}, UsernameFormat: "{username}@{realm}", MaxClockSkew: time.Minute},
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Keytab: "/config/authelia.keytab", Realms: []string{"EXAMPLE.COM"}, UsernameFormat: "{username}@{realm}", MaxClockSkew: time.Minute},
+			nil,
+		},
+		{
+			"ShouldErrorOnInvalidValues",
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Realms: []string{"EXAMPLE.COM", ""}, UsernameFormat: "{realm}"},
+			schema.AuthenticationBackendSPNEGO{Enabled: true, Realms: []string{"EXAMPLE.COM", ""}, UsernameFormat: "{realm}", MaxClockSkew: time.Minute * 5},
+			[]string{
+				"authentication_backend: spnego: option 'keytab' is required when the spnego authentication is enabled",
+				"authentication_backend: spnego: option 'username_format' must contain the '{username}' placeholder but it's configured as '{realm}'",
+				"authentication_backend: spnego: option 'realms' must not contain empty values",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			validateSPNEGOAuthenticationBackend(&tc.have, validator)
+
+			assert.Equal(t, tc.expected, tc.have)
+
+			require.Len(t, validator.Errors(), len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateCircuitBreaker validates and updates the circuit breaker configuration.
+func ValidateCircuitBreaker(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.CircuitBreaker.FailureThreshold <= 0 {
+		config.CircuitBreaker.FailureThreshold = schema.DefaultCircuitBreakerConfiguration.FailureThreshold
+	}
+
+	if config.CircuitBreaker.OpenDuration <= 0 {
+		config.CircuitBreaker.OpenDuration = schema.DefaultCircuitBreakerConfiguration.OpenDuration
+	}
+
+	if config.CircuitBreaker.ProbeTimeout <= 0 {
+		config.CircuitBreaker.ProbeTimeout = schema.DefaultCircuitBreakerConfiguration.ProbeTimeout
+	}
+
+	if config.CircuitBreaker.ProbeTimeout >= config.CircuitBreaker.OpenDuration {
+		validator.Push(fmt.Errorf(errFmtCircuitBreakerProbeTimeout, config.CircuitBreaker.ProbeTimeout, config.CircuitBreaker.OpenDuration))
+	}
+
+	if len(config.CircuitBreaker.Dependencies) == 0 {
+		config.CircuitBreaker.Dependencies = append([]string{}, schema.DefaultCircuitBreakerConfiguration.Dependencies...)
+
+		return
+	}
+
+	seen := map[string]bool{}
+
+	for _, dependency := range config.CircuitBreaker.Dependencies {
+		if !utils.IsStringInSlice(dependency, validCircuitBreakerDependencies) {
+			validator.Push(fmt.Errorf(errFmtCircuitBreakerDependency, utils.StringJoinAnd(validCircuitBreakerDependencies), dependency))
+
+			continue
+		}
+
+		if seen[dependency] {
+			validator.Push(fmt.Errorf(errFmtCircuitBreakerDependencyDuplicate, dependency))
+		}
+
+		seen[dependency] = true
+	}
+}
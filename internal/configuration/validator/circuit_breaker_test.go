@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateCircuitBreaker(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.Configuration
+		expected schema.CircuitBreaker
+		errors   []string
+	}{
+		{
+			"ShouldSetDefaults",
+			schema.Configuration{},
+			schema.CircuitBreaker{
+				Dependencies:     []string{schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencyDuo, schema.CircuitBreakerDependencyPush, schema.CircuitBreakerDependencyStorage},
+				FailureThreshold: 5,
+				OpenDuration:     time.Second * 30,
+				ProbeTimeout:     time.Second * 5,
+			},
+			nil,
+		},
+		{
+			"ShouldAllowCustomValues",
+			schema.Configuration{
+				CircuitBreaker: schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencyLDAP}, FailureThreshold: 3, OpenDuration: time.Minute, ProbeTimeout: time.Second * 10},
+			},
+			schema.CircuitBreaker{
+				Enabled:          true,
+				Dependencies:     []string{schema.CircuitBreakerDependencyLDAP},
+				FailureThreshold: 3,
+				OpenDuration:     time.Minute,
+				ProbeTimeout:     time.Second * 10,
+			},
+			nil,
+		},
+		{
+			"ShouldErrorInvalidDependency",
+			schema.Configuration{
+				CircuitBreaker: schema.CircuitBreaker{Dependencies: []string{schema.CircuitBreakerDependencyLDAP, "redis"}},
+			},
+			schema.CircuitBreaker{
+				Dependencies:     []string{schema.CircuitBreakerDependencyLDAP, "redis"},
+				FailureThreshold: 5,
+				OpenDuration:     time.Second * 30,
+				ProbeTimeout:     time.Second * 5,
+			},
+			[]string{
+				"circuit_breaker: option 'dependencies' must only contain values from 'ldap', 'smtp', 'duo', 'push', and 'storage' but it's configured with 'redis'",
+			},
+		},
+		{
+			"ShouldErrorDuplicateDependency",
+			schema.Configuration{
+				CircuitBreaker: schema.CircuitBreaker{Dependencies: []string{schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencySMTP}},
+			},
+			schema.CircuitBreaker{
+				Dependencies:     []string{schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencySMTP},
+				FailureThreshold: 5,
+				OpenDuration:     time.Second * 30,
+				ProbeTimeout:     time.Second * 5,
+			},
+			[]string{
+				"circuit_breaker: option 'dependencies' must not contain duplicate values but it's configured with 'smtp' more than once",
+			},
+		},
+		{
+			"ShouldErrorProbeTimeoutNotLessThanOpenDuration",
+			schema.Configuration{
+				CircuitBreaker: schema.CircuitBreaker{OpenDuration: time.Second * 10, ProbeTimeout: time.Second * 10},
+			},
+			schema.CircuitBreaker{
+				Dependencies:     []string{schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencyDuo, schema.CircuitBreakerDependencyPush, schema.CircuitBreakerDependencyStorage},
+				FailureThreshold: 5,
+				OpenDuration:     time.Second * 10,
+				ProbeTimeout:     time.Second * 10,
+			},
+			[]string{
+				"circuit_breaker: option 'probe_timeout' must be less than the option 'open_duration' but it's configured as '10s' and the option 'open_duration' is configured as '10s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			ValidateCircuitBreaker(&tc.have, validator)
+
+			assert.Equal(t, tc.expected, tc.have.CircuitBreaker)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			errors := validator.Errors()
+
+			require.Len(t, errors, len(tc.errors))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, errors[i], err)
+			}
+		})
+	}
+}
@@ -33,6 +33,12 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 		}
 	}
 
+	ValidateSMS(config, validator)
+
+	ValidateEmail(config, validator)
+
+	ValidatePush(config, validator)
+
 	validateDefault2FAMethod(config, validator)
 
 	ValidateTheme(config, validator)
@@ -47,6 +53,8 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateAuthenticationBackend(&config.AuthenticationBackend, validator)
 
+	ValidateDeviceCertificates(config, validator)
+
 	ValidateAccessControl(config, validator)
 
 	ValidateRules(config, validator)
@@ -59,7 +67,7 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateTelemetry(config, validator)
 
-	ValidateStorage(config.Storage, validator)
+	ValidateStorage(&config.Storage, validator)
 
 	ValidateNotifier(&config.Notifier, validator)
 
@@ -67,11 +75,19 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateIdentityValidation(config, validator)
 
+	ValidateAudit(config, validator)
+
 	ValidateNTP(config, validator)
 
+	ValidateLeaderElection(config, validator)
+
+	ValidateCircuitBreaker(config, validator)
+
 	ValidatePasswordPolicy(&config.PasswordPolicy, validator)
 
 	ValidatePrivacyPolicy(&config.PrivacyPolicy, validator)
+
+	ValidateReferences(config, validator)
 }
 
 func validateDefault2FAMethod(config *schema.Configuration, validator *schema.StructValidator) {
@@ -95,10 +111,18 @@ func validateDefault2FAMethod(config *schema.Configuration, validator *schema.St
 		enabledMethods = append(enabledMethods, "webauthn")
 	}
 
-	if !config.DuoAPI.Disable {
+	if !config.DuoAPI.Disable || !config.Push.Disable {
 		enabledMethods = append(enabledMethods, "mobile_push")
 	}
 
+	if !config.SMS.Disable {
+		enabledMethods = append(enabledMethods, "sms")
+	}
+
+	if config.Email.Enable {
+		enabledMethods = append(enabledMethods, "email")
+	}
+
 	if !utils.IsStringInSlice(config.Default2FAMethod, enabledMethods) {
 		validator.Push(fmt.Errorf(errFmtInvalidDefault2FAMethodDisabled, utils.StringJoinOr(enabledMethods), config.Default2FAMethod))
 	}
@@ -68,7 +68,7 @@ func TestShouldEnsureNotifierConfigIsProvided(t *testing.T) {
 
 	ValidateConfiguration(&config, validator)
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "notifier: you must ensure either the 'smtp' or 'filesystem' notifier is configured")
+	assert.EqualError(t, validator.Errors()[0], "notifier: you must ensure either the 'smtp', 'ses', or 'filesystem' notifier is configured")
 }
 
 func TestShouldAddDefaultAccessControl(t *testing.T) {
@@ -212,6 +212,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 					IntegrationKey: "another key",
 					Hostname:       "none",
 				},
+				SMS: schema.SMS{Disable: true},
 			},
 		},
 		{
@@ -223,6 +224,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 					IntegrationKey: "another key",
 					Hostname:       "none",
 				},
+				SMS: schema.SMS{Disable: true},
 			},
 		},
 		{
@@ -234,6 +236,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 					IntegrationKey: "another key",
 					Hostname:       "none",
 				},
+				SMS: schema.SMS{Disable: true},
 			},
 		},
 		{
@@ -245,6 +248,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 					IntegrationKey: "another key",
 					Hostname:       "none",
 				},
+				SMS:  schema.SMS{Disable: true},
 				TOTP: schema.TOTP{Disable: true},
 			},
 			expectedErrs: []string{
@@ -260,6 +264,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 					IntegrationKey: "another key",
 					Hostname:       "none",
 				},
+				SMS:      schema.SMS{Disable: true},
 				WebAuthn: schema.WebAuthn{Disable: true},
 			},
 			expectedErrs: []string{
@@ -271,6 +276,7 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 			have: &schema.Configuration{
 				Default2FAMethod: "mobile_push",
 				DuoAPI:           schema.DuoAPI{Disable: true},
+				SMS:              schema.SMS{Disable: true},
 			},
 			expectedErrs: []string{
 				"option 'default_2fa_method' must be one of the enabled options 'totp' or 'webauthn' but it's configured as 'mobile_push'",
@@ -282,7 +288,46 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 				Default2FAMethod: "duo",
 			},
 			expectedErrs: []string{
-				"option 'default_2fa_method' must be one of 'totp', 'webauthn', or 'mobile_push' but it's configured as 'duo'",
+				"option 'default_2fa_method' must be one of 'totp', 'webauthn', 'mobile_push', 'sms', or 'email' but it's configured as 'duo'",
+			},
+		},
+		{
+			desc: "ShouldAllowConfiguredMethodSMS",
+			have: &schema.Configuration{
+				Default2FAMethod: "sms",
+				DuoAPI:           schema.DuoAPI{Disable: true},
+				SMS:              schema.SMS{Provider: schema.SMSProviderTwilio},
+			},
+		},
+		{
+			desc: "ShouldNotAllowDisabledMethodSMS",
+			have: &schema.Configuration{
+				Default2FAMethod: "sms",
+				DuoAPI:           schema.DuoAPI{Disable: true},
+				SMS:              schema.SMS{Disable: true},
+			},
+			expectedErrs: []string{
+				"option 'default_2fa_method' must be one of the enabled options 'totp' or 'webauthn' but it's configured as 'sms'",
+			},
+		},
+		{
+			desc: "ShouldAllowConfiguredMethodEmail",
+			have: &schema.Configuration{
+				Default2FAMethod: "email",
+				DuoAPI:           schema.DuoAPI{Disable: true},
+				SMS:              schema.SMS{Disable: true},
+				Email:            schema.Email{Enable: true},
+			},
+		},
+		{
+			desc: "ShouldNotAllowDisabledMethodEmail",
+			have: &schema.Configuration{
+				Default2FAMethod: "email",
+				DuoAPI:           schema.DuoAPI{Disable: true},
+				SMS:              schema.SMS{Disable: true},
+			},
+			expectedErrs: []string{
+				"option 'default_2fa_method' must be one of the enabled options 'totp' or 'webauthn' but it's configured as 'email'",
 			},
 		},
 	}
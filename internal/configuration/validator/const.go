@@ -24,10 +24,49 @@ const (
 	policyDeny      = "deny"
 )
 
+// Subject constants.
+const (
+	prefixSubjectUser  = "user:"
+	prefixSubjectGroup = "group:"
+)
+
+// Challenge constants.
+const (
+	challengeAuto     = "auto"
+	challengeRedirect = "redirect"
+	challengeStatus   = "status"
+)
+
+// Device constants.
+const (
+	deviceAny       = "any"
+	deviceManaged   = "managed"
+	deviceUnmanaged = "unmanaged"
+)
+
 const (
 	durationZero = time.Duration(0)
 )
 
+const (
+	serverAdminTokenMinLength = 32
+
+	serverEndpointsSCIMTokenMinLength = 32
+)
+
+const (
+	sessionBrandingTitleMaxLength        = 64
+	sessionBrandingAnnouncementMaxLength = 1024
+)
+
+const (
+	telemetryUsageIntervalMinimum = time.Hour
+)
+
+const (
+	storageCleanupIntervalMinimum = time.Minute
+)
+
 const (
 	digestSHA1   = "sha1"
 	digestSHA224 = "sha224"
@@ -46,6 +85,7 @@ const (
 	hashPBKDF2    = "pbkdf2"
 	hashSCrypt    = "scrypt"
 	hashBCrypt    = "bcrypt"
+	hashBalloon   = "balloon"
 )
 
 // Scheme constants.
@@ -62,16 +102,20 @@ const (
 
 // Notifier Error constants.
 const (
-	errFmtNotifierMultipleConfigured = "notifier: please ensure only one of the 'smtp' or 'filesystem' notifier is configured"
-	errFmtNotifierNotConfigured      = "notifier: you must ensure either the 'smtp' or 'filesystem' notifier " +
+	errFmtNotifierMultipleConfigured = "notifier: please ensure only one of the 'smtp', 'ses', or 'filesystem' notifier is configured"
+	errFmtNotifierNotConfigured      = "notifier: you must ensure either the 'smtp', 'ses', or 'filesystem' notifier " +
 		"is configured"
 	errFmtNotifierTemplatePathNotExist            = "notifier: option 'template_path' refers to location '%s' which does not exist"
 	errFmtNotifierTemplatePathUnknownError        = "notifier: option 'template_path' refers to location '%s' which couldn't be opened: %w"
+	errFmtNotifierTemplateInvalid                 = "notifier: option 'template_path' refers to location '%s' which has templates which couldn't be loaded: %w"
+	errFmtNotifierTemplateUnknownFields           = "notifier: option 'template_path' refers to location '%s' which has the template '%s' which references the values %s that are not available to the template: the available values are %s"
 	errFmtNotifierFileSystemFileNameNotConfigured = "notifier: filesystem: option 'filename' is required"
 	errFmtNotifierSMTPNotConfigured               = "notifier: smtp: option '%s' is required"
 	errFmtNotifierSMTPTLSConfigInvalid            = "notifier: smtp: tls: %w"
 	errFmtNotifierSMTPAddress                     = "notifier: smtp: option 'address' with value '%s' is invalid: %w"
 	errFmtNotifierSMTPAddressLegacyAndModern      = "notifier: smtp: option 'host' and 'port' can't be configured at the same time as 'address'"
+	errFmtNotifierSESNotConfigured                = "notifier: ses: option '%s' is required"
+	errFmtNotifierSESEndpoint                     = "notifier: ses: option 'endpoint' must have the 'https' scheme but it's configured as '%s'"
 
 	errFmtNotifierStartTlsDisabled = "notifier: smtp: option 'disable_starttls' is enabled: " +
 		"opportunistic STARTTLS is explicitly disabled which means all emails will be sent insecurely over plaintext " +
@@ -85,10 +129,10 @@ const (
 
 // Authentication Backend Error constants.
 const (
-	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file' or 'ldap' " +
-		"authentication backend is configured"
-	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file' or 'ldap' " +
-		"backend is configured"
+	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file', 'ldap', 'sql', or " +
+		"'ldap_backends' authentication backend is configured"
+	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file', 'ldap', " +
+		"'sql', or 'ldap_backends' backend is configured"
 	errFmtAuthBackendRefreshInterval = "authentication_backend: option 'refresh_interval' is configured to '%s' but " +
 		"it must be either in duration common syntax or one of 'disable', or 'always': %w"
 	errFmtAuthBackendPasswordResetCustomURLScheme = "authentication_backend: password_reset: option 'custom_url' is" +
@@ -126,6 +170,19 @@ const (
 		"must contain one of the %s placeholders when using a group_search_mode of '%s' but they're absent"
 	errFmtLDAPAuthBackendFilterMissingAttribute = "authentication_backend: ldap: attributes: option '%s' " +
 		"must be provided when using the %s placeholder but it's absent"
+
+	errPrefixLDAPAuthBackend = "authentication_backend: ldap: "
+
+	errFmtLDAPBackendsBackend         = "authentication_backend: ldap_backends: %s: %s"
+	errFmtLDAPBackendsMissingName     = "authentication_backend: ldap_backends: %s: option 'name' is required"
+	errFmtLDAPBackendsDuplicateName   = "authentication_backend: ldap_backends: %s: option 'name' must be unique but it's configured for more than one backend"
+	errFmtLDAPBackendsInvalidDomain   = "authentication_backend: ldap_backends: %s: option 'domains' must only contain valid domains but it contains '%s'"
+	errFmtLDAPBackendsDuplicateDomain = "authentication_backend: ldap_backends: %s: option 'domains' contains the domain '%s' which is already configured for the backend '%s'"
+
+	errFmtSPNEGOKeytabNotConfigured = "authentication_backend: spnego: option 'keytab' is required when the spnego authentication is enabled"
+	errFmtSPNEGOUsernameFormat      = "authentication_backend: spnego: option 'username_format' must contain the '{username}' placeholder but it's configured as '%s'"
+	errFmtSPNEGORealmEmpty          = "authentication_backend: spnego: option 'realms' must not contain empty values"
+	errFmtSPNEGORealmsRequired      = "authentication_backend: spnego: option 'realms' is required when the option 'username_format' does not contain the '{realm}' placeholder but it's configured as '%s'"
 )
 
 // TOTP Error constants.
@@ -137,28 +194,67 @@ const (
 	errFmtTOTPInvalidDigits           = "totp: option 'digits' must be 6 or 8 but it's configured as '%d'"
 	errFmtTOTPInvalidAllowedDigit     = "totp: option 'allowed_digits' must only have the values 6 or 8 but one of the values is '%d'"
 	errFmtTOTPInvalidSecretSize       = "totp: option 'secret_size' must be %d or higher but it's configured as '%d'" //nolint:gosec
+	errFmtTOTPInvalidDriftMaximum     = "totp: drift_compensation: option 'maximum' must be between %d and %d but it's configured as '%d'"
 )
 
 // Storage Error constants.
 const (
-	errStrStorage                                  = "storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided"
-	errStrStorageMultiple                          = "storage: option 'local', 'mysql' and 'postgres' are mutually exclusive but %s have been configured"
+	errStrStorage                                  = "storage: configuration for a 'local', 'mysql', 'postgres' or 'cockroachdb' database must be provided"
+	errStrStorageMultiple                          = "storage: option 'local', 'mysql', 'postgres' and 'cockroachdb' are mutually exclusive but %s have been configured"
 	errStrStorageEncryptionKeyMustBeProvided       = "storage: option 'encryption_key' is required"
 	errStrStorageEncryptionKeyTooShort             = "storage: option 'encryption_key' must be 20 characters or longer"
+	errFmtStoragePreviousEncryptionKeyTooShort     = "storage: option 'previous_encryption_keys' value #%d must be 20 characters or longer"
+	errFmtStoragePreviousEncryptionKeySame         = "storage: option 'previous_encryption_keys' value #%d must not be the same as the 'encryption_key'"
 	errFmtStorageUserPassMustBeProvided            = "storage: %s: option 'username' and 'password' are required" //nolint:gosec
 	errFmtStorageOptionMustBeProvided              = "storage: %s: option '%s' is required"
 	errFmtStorageOptionAddressConflictWithHostPort = "storage: %s: option 'host' and 'port' can't be configured at the same time as 'address'"
 	errFmtStorageFailedToConvertHostPortToAddress  = "storage: %s: option 'address' failed to parse options 'host' and 'port' as address: %w"
+	errFmtStorageReplicaNotProvided                = "storage: %s: option 'replicas' value #%d is empty"
+	errFmtStorageReplicaAddress                    = "storage: %s: option 'replicas' value #%d '%s' is invalid: %w"
 
 	errFmtStorageTLSConfigInvalid                 = "storage: %s: tls: %w"
+	errFmtStorageIAMAuthenticationProvider        = "storage: %s: iam_authentication: option 'provider' must be one of %s but it's configured as '%s'"
+	errFmtStorageIAMAuthenticationRegion          = "storage: %s: iam_authentication: option 'region' must only be configured when the 'provider' is 'aws' but the 'provider' is configured as '%s'"
+	errFmtStorageIAMAuthenticationPassword        = "storage: %s: option 'password' must not be configured when the 'iam_authentication' option 'provider' is configured"
+	errFmtStorageIAMAuthenticationTLS             = "storage: %s: option 'tls' must be configured when the 'iam_authentication' option 'provider' is configured"
 	errFmtStoragePostgreSQLInvalidSSLMode         = "storage: postgres: ssl: option 'mode' must be one of %s but it's configured as '%s'"
 	errFmtStoragePostgreSQLInvalidSSLAndTLSConfig = "storage: postgres: can't define both 'tls' and 'ssl' configuration options"
+	errFmtStorageCockroachDBMaximumRetries        = "storage: cockroachdb: option 'maximum_retries' must be greater than 0 but it's configured as '%d'"
+	errFmtStorageCleanupIntervalMinimum           = "storage: cleanup: option 'interval' must be at least 1 minute but it's configured as '%s'"
+	errFmtStorageCleanupBatchSize                 = "storage: cleanup: option 'batch_size' must be greater than 0 but it's configured as '%d'"
 	warnFmtStoragePostgreSQLInvalidSSLDeprecated  = "storage: postgres: ssl: the ssl configuration options are deprecated and we recommend the tls options instead"
 )
 
 // Telemetry Error constants.
 const (
 	errFmtTelemetryMetricsAddress = "telemetry: metrics: option 'address' with value '%s' is invalid: %w"
+
+	errFmtTelemetryMetricsStatsDAddress        = "telemetry: metrics: statsd: option 'address' with value '%s' is invalid: %w"
+	errFmtTelemetryMetricsStatsDFlavor         = "telemetry: metrics: statsd: option 'flavor' must be one of %s but it's configured as '%s'"
+	errFmtTelemetryMetricsStatsDTags           = "telemetry: metrics: statsd: option 'tags' is only sent with the 'dogstatsd' flavor but the flavor is configured as '%s'"
+	errFmtTelemetryMetricsOTLPEndpointScheme   = "telemetry: metrics: otlp: option 'endpoint' must have the scheme 'http' or 'https' but it's configured as '%s'"
+	errFmtTelemetryMetricsOTLPTLSConfigInvalid = "telemetry: metrics: otlp: tls: %w"
+
+	errTelemetryUsageEndpointRequired    = "telemetry: usage: option 'endpoint' is required when the usage reports are enabled"
+	errFmtTelemetryUsageEndpointScheme   = "telemetry: usage: option 'endpoint' must have the scheme 'http' or 'https' but it's configured as '%s'"
+	errFmtTelemetryUsageIntervalMinimum  = "telemetry: usage: option 'interval' must be at least 1 hour but it's configured as '%s'"
+	errFmtTelemetryUsageTLSConfigInvalid = "telemetry: usage: tls: %w"
+)
+
+// Audit Error constants.
+const (
+	errFmtAuditNoSinks              = "audit: option 'sinks' must have at least one sink configured when the audit event bus is enabled"
+	errFmtAuditSinkNoDriver         = "audit: sinks: sink #%d (%s): must have one of the 'file', 'syslog', 'webhook', 'kafka', or 'storage' options configured"
+	errFmtAuditSinkMultipleDrivers  = "audit: sinks: sink #%d (%s): must only have one of the 'file', 'syslog', 'webhook', 'kafka', or 'storage' options configured but has %s configured"
+	errFmtAuditSinkNameDuplicate    = "audit: sinks: sink #%d (%s): option 'name' must be unique but another sink has the same name"
+	errFmtAuditSinkEventsInvalid    = "audit: sinks: sink #%d (%s): option '%s' has the value '%s' which doesn't match any event types, the event types are %s"
+	errFmtAuditSinkOptionRequired   = "audit: sinks: sink #%d (%s): %s: option '%s' is required"
+	errFmtAuditSinkSyslogAddress    = "audit: sinks: sink #%d (%s): syslog: option 'address' with value '%s' is invalid: %w"
+	errFmtAuditSinkSyslogFacility   = "audit: sinks: sink #%d (%s): syslog: option 'facility' must be one of %s but it's configured as '%s'"
+	errFmtAuditSinkWebhookURLScheme = "audit: sinks: sink #%d (%s): webhook: option 'url' must have a scheme of 'http' or 'https' but it's configured as '%s'"
+	errFmtAuditSinkTLSConfigInvalid = "audit: sinks: sink #%d (%s): %s: tls: %w"
+	errFmtAuditSinkKafkaSASLInvalid = "audit: sinks: sink #%d (%s): kafka: sasl: option 'mechanism' must be one of %s but it's configured as '%s'"
+	errFmtAuditSinkStorageRetention = "audit: sinks: sink #%d (%s): storage: option 'retention' must be 0 or more but it's configured as '%s'"
 )
 
 // OpenID Error constants.
@@ -193,6 +289,15 @@ const (
 	errFmtOIDCCORSInvalidOriginWildcardWithClients = "identity_providers: oidc: cors: option 'allowed_origins' contains the wildcard origin '*' cannot be specified with option 'allowed_origins_from_client_redirect_uris' enabled"
 	errFmtOIDCCORSInvalidEndpoint                  = "identity_providers: oidc: cors: option 'endpoints' contains an invalid value '%s': must be one of %s"
 
+	errOIDCDynamicClientRegistrationOpen                        = "identity_providers: oidc: dynamic_client_registration: option 'open_registration' is enabled which allows anyone who can reach the registration endpoint to register a public client"
+	errOIDCDynamicClientRegistrationNoInitialAccessTokens       = "identity_providers: oidc: dynamic_client_registration: option 'initial_access_tokens' is required unless option 'open_registration' is enabled"
+	errOIDCDynamicClientRegistrationOpenInitialAccessTokens     = "identity_providers: oidc: dynamic_client_registration: option 'open_registration' must not be enabled when option 'initial_access_tokens' is configured"
+	errFmtOIDCDynamicClientRegistrationInitialAccessTokenLength = "identity_providers: oidc: dynamic_client_registration: option 'initial_access_tokens' has an invalid value: token #%d must be at least 20 characters long"
+	errFmtOIDCDynamicClientRegistrationInvalidValue             = "identity_providers: oidc: dynamic_client_registration: option " + errFmtMustBeOneOf
+	errFmtOIDCDynamicClientRegistrationInvalidEntries           = "identity_providers: oidc: dynamic_client_registration: option " + errFmtMustOnlyHaveValues + "but the values %s are present"
+
+	errFmtOIDCBackchannelAuthenticationPollingInterval = "identity_providers: oidc: backchannel_authentication: option 'polling_interval' must be less than option 'lifespan' but it's configured as '%s' and option 'lifespan' is configured as '%s'"
+
 	errFmtOIDCPolicyInvalidName          = "identity_providers: oidc: authorization_policies: authorization policies must have a name but one with a blank name exists"
 	errFmtOIDCPolicyInvalidNameStandard  = "identity_providers: oidc: authorization_policies: policy '%s': option '%s' must not be one of %s but it's configured as '%s'"
 	errFmtOIDCPolicyMissingOption        = "identity_providers: oidc: authorization_policies: policy '%s': option '%s' is required"
@@ -283,6 +388,13 @@ const (
 	errFmtOIDCClientInvalidGrantTypePublic = errFmtOIDCClientOption + "'grant_types' " +
 		"should only have the '%s' value if it is of the confidential client type but it's of the public client type"
 
+	errFmtOIDCClientInvalidGrantTypeCIBADisabled = errFmtOIDCClientOption + "'grant_types' " +
+		"must only have the '%s' value if the 'backchannel_authentication' option is enabled for the provider"
+	errFmtOIDCClientInvalidBackchannelNotificationEndpointRequired = errFmtOIDCClientOption +
+		"'backchannel_client_notification_endpoint' is required when option 'backchannel_token_delivery_mode' is configured as 'ping'"
+	errFmtOIDCClientInvalidBackchannelNotificationEndpointScheme = errFmtOIDCClientOption +
+		"'backchannel_client_notification_endpoint' must have the 'https' scheme but the scheme is '%s'"
+
 	errFmtOIDCClientInvalidRefreshTokenOptionWithoutCodeResponseType = errFmtOIDCClientOption +
 		"'%s' should only have the values %s if the client is also configured with a 'response_type' such as %s which respond with authorization codes"
 
@@ -299,10 +411,39 @@ const (
 	errFmtOIDCClientPublicKeysROSAMissingAlgorithm        = errFmtOIDCClientOption + "'request_object_signing_alg' must be one of %s configured in the client option 'jwks'"
 )
 
+// SAML Error constants.
+const (
+	errFmtSAMLNoPrivateKey              = "identity_providers: saml: option 'private_key' is required"
+	errFmtSAMLPrivateKeyRSALessThan2048 = "identity_providers: saml: option 'private_key' is an RSA %d bit private key but it must at minimum be a RSA 2048 bit private key"
+	errFmtSAMLNoCertificateChain        = "identity_providers: saml: option 'certificate_chain' is required"
+	errFmtSAMLCertificateMismatch       = "identity_providers: saml: option 'certificate_chain' does not appear to contain the public key for the private key provided by option 'private_key'"
+	errFmtSAMLCertificateChainInvalid   = "identity_providers: saml: option 'certificate_chain' produced an error during validation of the chain: %w"
+	errFmtSAMLInvalidOption             = "identity_providers: saml: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtSAMLNoServiceProviders        = "identity_providers: saml: option 'service_providers' must have at least one service provider"
+
+	errFmtSAMLServiceProviderNoEntityID        = "identity_providers: saml: service_providers: service provider #%d: option 'entity_id' is required"
+	errFmtSAMLServiceProviderDuplicateEntityID = "identity_providers: saml: service_providers: service provider '%s': option 'entity_id' must be unique but it's configured for more than one service provider"
+	errFmtSAMLServiceProviderInvalidOption     = "identity_providers: saml: service_providers: service provider '%s': option '%s' must be one of %s but it's configured as '%s'"
+	errFmtSAMLServiceProviderNoACSURLs         = "identity_providers: saml: service_providers: service provider '%s': option 'assertion_consumer_service_urls' is required when option 'metadata' is not configured"
+	errFmtSAMLServiceProviderInvalidURL        = "identity_providers: saml: service_providers: service provider '%s': option '%s' must be an absolute URL with the 'https' scheme but it's configured as '%s'"
+	errFmtSAMLServiceProviderMetadata          = "identity_providers: saml: service_providers: service provider '%s': option 'metadata' must be the path to a file but an error occurred: %w"
+	errFmtSAMLServiceProviderCertificateNotRSA = "identity_providers: saml: service_providers: service provider '%s': option 'certificate_chain' must contain an RSA public key"
+	errFmtSAMLServiceProviderCertificateChain  = "identity_providers: saml: service_providers: service provider '%s': option 'certificate_chain' produced an error during validation of the chain: %w"
+)
+
 // WebAuthn Error constants.
 const (
 	errFmtWebAuthnConveyancePreference = "webauthn: option 'attestation_conveyance_preference' must be one of %s but it's configured as '%s'"
 	errFmtWebAuthnUserVerification     = "webauthn: option 'user_verification' must be one of %s but it's configured as '%s'"
+
+	errFmtWebAuthnFilteringGroupPolicyNoGroups       = "webauthn: filtering: group_policies: policy #%d: option 'groups' must have at least one group"
+	errFmtWebAuthnFilteringGroupPolicyGroupDuplicate = "webauthn: filtering: group_policies: policy #%d: option 'groups' has the group '%s' which is already configured in policy #%d"
+
+	errFmtWebAuthnSelectionCriteriaAttachment      = "webauthn: selection_criteria: option 'attachment' must be one of %s but it's configured as '%s'"
+	errFmtWebAuthnSelectionCriteriaDiscoverability = "webauthn: selection_criteria: option 'discoverability' must be one of %s but it's configured as '%s'"
+	errFmtWebAuthnPasskeyLoginUserVerification     = "webauthn: passkey_login: option 'user_verification' must be one of %s but it's configured as '%s'"
+	errFmtWebAuthnPasskeyLoginDiscoverability      = "webauthn: passkey_login: option 'enable' must not be true when the 'selection_criteria' option 'discoverability' is configured as '%s'"
+	errFmtWebAuthnPasskeyLoginTwoFactor            = "webauthn: passkey_login: option 'two_factor_with_user_verification' must not be true when the option 'user_verification' is configured as '%s'"
 )
 
 // Access Control error constants.
@@ -315,9 +456,23 @@ const (
 		"network '%s' is not a valid IP or CIDR notation"
 	errFmtAccessControlWarnNoRulesDefaultPolicy = "access_control: no rules have been specified so the " +
 		"'default_policy' of '%s' is going to be applied to all requests"
+	errFmtAccessControlKubernetesAnnotationPrefix = "access_control: kubernetes: option 'annotation_prefix' must " +
+		"not contain a '/' but it's configured as '%s'"
 	errFmtAccessControlRuleNoDomains                    = "access_control: rule %s: option 'domain' or 'domain_regex' must be present but are both absent"
 	errFmtAccessControlRuleNoPolicy                     = "access_control: rule %s: option 'policy' must be present but it's absent"
 	errFmtAccessControlRuleInvalidPolicy                = "access_control: rule %s: option 'policy' must be one of %s but it's configured as '%s'"
+	errFmtAccessControlRuleInvalidChallenge             = "access_control: rule %s: option 'challenge' must be one of %s but it's configured as '%s'"
+	errFmtAccessControlRuleInvalidDevice                = "access_control: rule %s: option 'device' must be one of %s but it's configured as '%s'"
+	errFmtAccessControlRuleDeviceCertificatesDisabled   = "access_control: rule %s: option 'device' is configured as '%s' but the 'device_certificates' section is not enabled"
+	errFmtAccessControlRuleInvalidUserVerification      = "access_control: rule %s: option 'webauthn_user_verification' must be one of %s but it's configured as '%s'"
+	errFmtAccessControlRuleUserVerificationPolicy       = "access_control: rule %s: option 'webauthn_user_verification' is configured as 'required' but option 'policy' is configured as '%s' when it must be 'two_factor'"
+	errFmtAccessControlRuleUserVerificationDisabled     = "access_control: rule %s: option 'webauthn_user_verification' is configured as 'required' but the 'webauthn' section is disabled"
+	errFmtAccessControlRuleAuthnStrategy                = "access_control: rule %s: authn_strategies: strategy #%d: option 'name' must be one of %s but it's configured as '%s'"
+	errFmtAccessControlRuleAuthnStrategyNoName          = "access_control: rule %s: authn_strategies: strategy #%d: option 'name' must be configured"
+	errFmtAccessControlRuleAuthnStrategyDuplicate       = "access_control: rule %s: authn_strategies: duplicate strategy name detected with name '%s'"
+	errFmtAccessControlRuleAuthnStrategySchemes         = "access_control: rule %s: authn_strategies: strategy #%d (%s): option 'schemes' must only include the values %s but has '%s'"
+	errFmtAccessControlRuleAuthnStrategySchemesInvalid  = "access_control: rule %s: authn_strategies: strategy #%d (%s): option 'schemes' is not valid for the strategy"
+	errFmtAccessControlRuleAuthnStrategiesCriteria      = "access_control: rule %s: option 'authn_strategies' is not supported when option '%s' is configured as the authentication strategies are determined before the subject is authenticated"
 	errAccessControlRuleBypassPolicyOptionBypassIs      = "access_control: rule %s: 'policy' option 'bypass' is "
 	errAccessControlRuleBypassPolicyInvalidWithSubjects = errAccessControlRuleBypassPolicyOptionBypassIs +
 		"not supported when 'subject' option is configured: see " +
@@ -349,7 +504,32 @@ const (
 // NTP Error constants.
 const (
 	errFmtNTPVersion       = "ntp: option 'version' must be either 3 or 4 but it's configured as '%d'"
-	errFmtNTPAddressScheme = "ntp: option 'address' with value '%s' is invalid: %w"
+	errFmtNTPAddressScheme = "ntp: option '%s' with value '%s' is invalid: %w"
+	errFmtNTPServerNil     = "ntp: option 'servers' must not contain empty values but the value at index %d is empty"
+	errFmtNTPQuorum        = "ntp: option 'quorum' must be between 1 and the number of configured servers %d but it's configured as '%d'"
+)
+
+// Leader Election Error constants.
+const (
+	errFmtLeaderElectionBackend       = "leader_election: option 'backend' must be one of %s but it's configured as '%s'"
+	errFmtLeaderElectionRenewInterval = "leader_election: option 'renew_interval' must be less than the option 'lease_duration' but it's configured as '%s' and the option 'lease_duration' is configured as '%s'"
+	errLeaderElectionRedis            = "leader_election: option 'backend' is configured as 'redis' but the 'session' section does not have the 'redis' option configured"
+	errLeaderElectionStorageLocal     = "leader_election: option 'backend' is configured as 'storage' but the 'storage' section has the 'local' option configured which can't be shared by replicas"
+)
+
+// Circuit Breaker Error constants.
+const (
+	errFmtCircuitBreakerDependency          = "circuit_breaker: option 'dependencies' must only contain values from %s but it's configured with '%s'"
+	errFmtCircuitBreakerProbeTimeout        = "circuit_breaker: option 'probe_timeout' must be less than the option 'open_duration' but it's configured as '%s' and the option 'open_duration' is configured as '%s'"
+	errFmtCircuitBreakerDependencyDuplicate = "circuit_breaker: option 'dependencies' must not contain duplicate values but it's configured with '%s' more than once"
+)
+
+// Device Certificates Error constants.
+const (
+	errFmtDeviceCertificatesOptionRequired = "device_certificates: option '%s' is required"
+	errFmtDeviceCertificatesChainInvalid   = "device_certificates: option 'certificate_chain' produced an error during validation of the chain: %w"
+	errDeviceCertificatesNotCA             = "device_certificates: option 'certificate_chain' must have a certificate authority certificate with the certificate signing key usage as the first certificate"
+	errDeviceCertificatesKeyMismatch       = "device_certificates: option 'certificate_chain' does not appear to contain the public key for the private key provided by option 'private_key'"
 )
 
 // Session error constants.
@@ -360,6 +540,9 @@ const (
 	errFmtSessionLegacyAndWarning         = "session: option 'domain' and option 'cookies' can't be specified at the same time"
 	errFmtSessionSameSite                 = "session: option 'same_site' must be one of %s but it's configured as '%s'"
 	errFmtSessionSecretRequired           = "session: option 'secret' is required when using the '%s' provider"
+	errFmtSessionStorage                  = "session: option 'storage' must be one of %s but it's configured as '%s'"
+	errSessionStorageRedis                = "session: option 'storage' is configured as 'redis' but the 'redis' option is not configured"
+	errFmtSessionStorageRedisConfigured   = "session: option 'storage' is configured as '%s' but the 'redis' option is also configured which is only used when it's configured as 'redis'"
 	errFmtSessionRedisPortRange           = "session: redis: option 'port' must be between 1 and 65535 but it's configured as '%d'"
 	errFmtSessionRedisHostRequired        = "session: redis: option 'host' is required"
 	errFmtSessionRedisHostOrNodesRequired = "session: redis: option 'host' or the 'high_availability' option 'nodes' is required"
@@ -368,6 +551,12 @@ const (
 	errFmtSessionRedisSentinelMissingName     = "session: redis: high_availability: option 'sentinel_name' is required"
 	errFmtSessionRedisSentinelNodeHostMissing = "session: redis: high_availability: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
 
+	errSessionRedisClusterAndHighAvailability = "session: redis: option 'cluster' and option 'high_availability' can't be configured at the same time"
+	errSessionRedisClusterHostOrNodesRequired = "session: redis: option 'host' or the 'cluster' option 'nodes' is required"
+	errFmtSessionRedisClusterDatabaseIndex    = "session: redis: cluster: option 'database_index' must be 0 as redis cluster only supports the first database but it's configured as '%d'"
+	errFmtSessionRedisClusterNodeHostMissing  = "session: redis: cluster: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
+	errFmtSessionRedisClusterNodePortRange    = "session: redis: cluster: option 'nodes': option 'port' must be between 1 and 65535 but it's configured as '%d'"
+
 	errFmtSessionDomainMustBeRoot                        = "session: domain config %s: option 'domain' must be the domain you wish to protect not a wildcard domain but it's configured as '%s'"
 	errFmtSessionDomainSameSite                          = "session: domain config %s: option 'same_site' must be one of %s but it's configured as '%s'"
 	errFmtSessionDomainOptionRequired                    = "session: domain config %s: option '%s' is required"
@@ -381,6 +570,20 @@ const (
 	errFmtSessionDomainInvalidDomain                     = "session: domain config %s: option 'domain' does not appear to be a valid cookie domain or an ip address"
 	errFmtSessionDomainInvalidDomainNoDots               = "session: domain config %s: option 'domain' is not a valid cookie domain: must have at least a single period or be an ip address"
 	errFmtSessionDomainInvalidDomainPublic               = "session: domain config %s: option 'domain' is not a valid cookie domain: the domain is part of the special public suffix list"
+
+	errFmtSessionDomainBrandingLength               = "session: domain config %s: branding: option '%s' must not be more than %d characters but it's configured with %d characters"
+	errFmtSessionDomainBrandingURLNotAbsolute       = "session: domain config %s: branding: option '%s' is not absolute with a value of '%s'"
+	errFmtSessionDomainBrandingLogoURLInsecure      = "session: domain config %s: branding: option 'logo_url' does not have a secure scheme with a value of '%s'"
+	errFmtSessionDomainBrandingSupportContactScheme = "session: domain config %s: branding: option 'support_contact' must have one of the schemes %s but it's configured with the scheme '%s'"
+)
+
+// References Error constants.
+const (
+	errFmtReferencesAccessControlRuleDomainNotInCookieScope = "access_control: rule %s: option 'domain' has the value '%s' which is not within the cookie scope of any of the session domains %s so requests to it can't be authenticated"
+	errFmtReferencesAccessControlRuleSubjectUnknown         = "access_control: rule %s: option 'subject' references '%s' which doesn't exist in the file authentication backend database"
+	errFmtReferencesOIDCPolicyRuleSubjectUnknown            = "identity_providers: oidc: authorization_policies: policy '%s': rules: rule #%d: option 'subject' references '%s' which doesn't exist in the file authentication backend database"
+	errReferencesPushDuoEnabled                             = "push: option 'provider' must not be configured when the duo_api is enabled as they both provide the 'mobile_push' second factor method"
+	errReferencesOIDCBackchannelAuthenticationDuoDisabled   = "identity_providers: oidc: backchannel_authentication: option 'enabled' requires the duo_api or push to be configured to perform the out-of-band authentication but they're disabled"
 )
 
 // Regulation Error Consts.
@@ -390,9 +593,9 @@ const (
 
 // Server Error constants.
 const (
-	errFmtServerTLSCert             = "server: tls: option 'key' must also be accompanied by option 'certificate'"
-	errFmtServerTLSKey              = "server: tls: option 'certificate' must also be accompanied by option 'key'"
-	errFmtServerTLSClientAuthNoAuth = "server: tls: client authentication cannot be configured if no server certificate and key are provided"
+	errFmtServerTLSCert             = "server: %s: option 'key' must also be accompanied by option 'certificate'"
+	errFmtServerTLSKey              = "server: %s: option 'certificate' must also be accompanied by option 'key'"
+	errFmtServerTLSClientAuthNoAuth = "server: %s: client authentication cannot be configured if no server certificate and key are provided"
 
 	errFmtServerAddress = "server: option 'address' with value '%s' is invalid: %w"
 
@@ -406,15 +609,62 @@ const (
 	errFmtServerEndpointsAuthzStrategyNoName            = "server: endpoints: authz: %s: authn_strategies: strategy #%d: option 'name' must be configured"
 	errFmtServerEndpointsAuthzStrategyDuplicate         = "server: endpoints: authz: %s: authn_strategies: duplicate strategy name detected with name '%s'"
 	errFmtServerEndpointsAuthzPrefixDuplicate           = "server: endpoints: authz: %s: endpoint starts with the same prefix as the '%s' endpoint with the '%s' implementation which accepts prefixes as part of its implementation"
+	errFmtServerEndpointsSCIMTokenRequired              = "server: endpoints: scim: option 'token' is required when the SCIM endpoints are enabled"
+	errFmtServerEndpointsSCIMTokenLength                = "server: endpoints: scim: option 'token' must be at least %d characters but it has %d characters"
+	errFmtServerEndpointsMobileLifespan                 = "server: endpoints: mobile: option 'access_token_lifespan' must be less than option 'refresh_token_lifespan' but it's configured as '%s' and '%s' respectively"
+	errFmtServerEndpointsMobileTOTPDisabled             = "server: endpoints: mobile: the mobile endpoints require TOTP as the second factor but TOTP is disabled"
 	errFmtServerEndpointsAuthzInvalidName               = "server: endpoints: authz: %s: contains invalid characters"
 
 	errFmtServerEndpointsAuthzLegacyInvalidImplementation = "server: endpoints: authz: %s: option 'implementation' is invalid: the endpoint with the name 'legacy' must use the 'Legacy' implementation"
+
+	errFmtServerAssetWatchWithoutPath = "server: option 'asset_watch' must not be enabled when option 'asset_path' is not configured"
+	errFmtServerAssetPathBranding     = "server: option 'asset_path' has an invalid overlay: %w"
+
+	errFmtServerLimitsNegative = "server: limits: option '%s' must be 0 or greater but it's configured as '%d'"
+
+	errFmtServerKeepAliveTCPPeriodNegative = "server: keepalive: option 'tcp_period' must be 0 or greater but it's configured as '%s'"
+
+	errFmtServerH2CWithTLS          = "server: h2c: option 'enable' must not be enabled when the tls options are configured"
+	errFmtServerH2CMaxReadFrameSize = "server: h2c: option 'max_read_frame_size' must be between 16384 and 16777215 but it's configured as '%d'"
+
+	errFmtServerAdminAddress       = "server: admin: option 'address' with value '%s' is invalid: %w"
+	errFmtServerAdminTokenRequired = "server: admin: option 'token' is required when the admin server is enabled"
+	errFmtServerAdminTokenLength   = "server: admin: option 'token' must be at least %d characters but it has %d characters"
+	errFmtServerAdminAddressInUse  = "server: admin: option 'address' must not be the same as the server address '%s'"
+
+	errFmtServerAdminEventsAuditDisabled = "server: admin: option 'enable_events' requires the audit events to be enabled with the 'audit.enabled' option"
+
+	errFmtServerLDAPAddress               = "server: ldap: option 'address' with value '%s' is invalid: %w"
+	errFmtServerLDAPAddressInUse          = "server: ldap: option 'address' must not be the same as the server address '%s'"
+	errFmtServerLDAPAuthenticationBackend = "server: ldap: option 'enabled' requires the 'sql' authentication backend to be configured"
+	errFmtServerLDAPBaseDNRequired        = "server: ldap: option 'base_dn' is required when the ldap server is enabled"
+	errFmtServerLDAPBaseDN                = "server: ldap: option 'base_dn' with value '%s' is not a valid distinguished name"
+
+	errFmtServerHeadlessOriginWildcard = "server: headless: option 'allowed_origins' must not contain the wildcard origin '*' as the API permits credentials"
+	errFmtServerHeadlessOriginInvalid  = "server: headless: option 'allowed_origins' contains an invalid value '%s' as it has a %s: origins must only be scheme, hostname, and an optional port"
+	errFmtServerHeadlessOriginScheme   = "server: headless: option 'allowed_origins' contains an invalid value '%s' as it has the scheme '%s': origins must have the 'http' or 'https' scheme"
+
+	errFmtServerScannerFilterInvalidRegex   = "server: scanner_filter: option '%s' has an invalid regular expression '%s': %w"
+	errFmtServerScannerFilterInvalidNetwork = "server: scanner_filter: option 'allowed_networks' must only contain valid IP addresses or CIDR notation networks but it has '%s'"
+
+	errFmtServerHeadersOptionInvalid                    = "server: headers: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtServerHeadersGroupOptionInvalid               = "server: headers: groups: %s: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtServerHeadersHSTSMaxAgeNegative               = "server: headers: strict_transport_security: option 'max_age' must be 0 or greater but it's configured as '%s'"
+	errFmtServerHeadersHSTSOptionWithoutMaxAge          = "server: headers: strict_transport_security: option '%s' must not be enabled when option 'max_age' is 0"
+	errFmtServerHeadersHSTSPreloadRequirementsNotMet    = "server: headers: strict_transport_security: option 'preload' requires option 'include_subdomains' to be enabled and option 'max_age' to be at least 1 year"
+	errFmtServerHeadersGroupContentSecurityPolicyNonce  = "server: headers: groups: %s: option 'content_security_policy' must not contain the nonce placeholder '%s' as this group does not support nonces"
+	errFmtServerHeadersCSPTemplateAndPortalGroupBothSet = "server: headers: option 'csp_template' and option 'groups.portal.content_security_policy' must not both be configured"
 )
 
 const (
-	errPasswordPolicyMultipleDefined                        = "password_policy: only a single password policy mechanism can be specified"
-	errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero = "password_policy: standard: option 'min_length' must be greater than 0 but it's configured as %d"
-	errFmtPasswordPolicyZXCVBNMinScoreInvalid               = "password_policy: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as %d"
+	errFmtPasswordPolicyMultipleDefined                     = "%s: only a single password policy mechanism can be specified"
+	errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero = "%s: standard: option 'min_length' must be greater than 0 but it's configured as %d"
+	errFmtPasswordPolicyZXCVBNMinScoreInvalid               = "%s: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as %d"
+	errFmtPasswordPolicyZXCVBNMinEntropyNegative            = "%s: zxcvbn: option 'min_entropy' must be 0 or greater but it's configured as %d"
+	errFmtPasswordPolicyBannedWordEmpty                     = "password_policy: option 'banned_words' value #%d is empty"
+	errFmtPasswordPolicyGroupPolicyNoGroups                 = "password_policy: group_policies: policy #%d: option 'groups' must have at least one group"
+	errFmtPasswordPolicyGroupPolicyNoMechanism              = "password_policy: group_policies: policy #%d: must have one of the 'standard' or 'zxcvbn' password policy mechanisms enabled"
+	errFmtPasswordPolicyGroupPolicyGroupDuplicate           = "password_policy: group_policies: policy #%d: option 'groups' has the group '%s' which is already configured in policy #%d"
 )
 
 const (
@@ -426,6 +676,34 @@ const (
 	errFmtDuoMissingOption = "duo_api: option '%s' is required when duo is enabled but it's absent"
 )
 
+const (
+	errFmtPushInvalidProvider          = "push: option 'provider' must be one of %s but it's configured as '%s'"
+	errPushMultipleProviders           = "push: option 'provider' must be configured when more than one provider is configured but it's absent"
+	errFmtPushProviderMissingOption    = "push: %s: option '%s' is required when the provider is '%s' but it's absent"
+	errFmtPushProviderURLScheme        = "push: %s: option 'url' must have a scheme of 'http' or 'https' but it's configured as '%s'"
+	errFmtPushProviderTLSConfigInvalid = "push: %s: tls: %w"
+	errFmtPushGotifyTokenEmpty         = "push: gotify: tokens: option '%s' must not be empty"
+
+	errPushMobileEndpointsDisabled = "push: mobile: the provider requires the mobile authentication endpoints to be enabled with the option 'server.endpoints.mobile.enabled' but they're disabled"
+)
+
+const (
+	errFmtSMSInvalidProvider          = "sms: option 'provider' must be one of %s but it's configured as '%s'"
+	errSMSMultipleProviders           = "sms: option 'provider' must be configured when more than one provider is configured but it's absent"
+	errFmtSMSProviderMissingOption    = "sms: %s: option '%s' is required when the provider is '%s' but it's absent"
+	errFmtSMSProviderVoiceMissing     = "sms: vonage: option '%s' is required when the option 'enable_voice' is true but it's absent"
+	errFmtSMSProviderURLScheme        = "sms: %s: option '%s' must have a scheme of 'http' or 'https' but it's configured as '%s'"
+	errFmtSMSProviderTLSConfigInvalid = "sms: %s: tls: %w"
+	errFmtSMSCharacters               = "sms: option 'characters' must be between 6 and 10 but it's configured as '%d'"
+)
+
+const (
+	errFmtEmailCharacters       = "email: option 'characters' must be between 6 and 20 but it's configured as '%d'"
+	errFmtEmailCharacterSet     = "email: option 'character_set' must be one of %s but it's configured as '%s'"
+	errFmtEmailCooldown         = "email: option 'cooldown' must be greater than 0 but it's configured as '%s'"
+	errFmtEmailCooldownLifespan = "email: option 'cooldown' must be less than the option 'code_lifespan' but they're configured as '%s' and '%s' respectively"
+)
+
 // Error constants.
 const (
 	errFmtInvalidDefault2FAMethod         = "option 'default_2fa_method' must be one of %s but it's configured as '%s'"
@@ -433,7 +711,16 @@ const (
 
 	errFmtReplacedConfigurationKey = "invalid configuration key '%s' was replaced by '%s'"
 
-	errFmtLoggingInvalid = "log: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtKeyNotExpected            = "configuration key not expected: %s"
+	errFmtKeyNotExpectedPosition    = "%s: configuration key not expected: %s"
+	errFmtKeyEnvironmentNotExpected = "configuration environment variable not expected: %s"
+
+	errFmtLoggingInvalid                = "log: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtLoggingOutputInvalid          = "log: %s: option '%s' must be one of %s but it's configured as '%s'"
+	errFmtLoggingSyslogAddressRequired  = "log: syslog: option 'address' is required when the syslog output is enabled"
+	errFmtLoggingSyslogAddress          = "log: syslog: option 'address' with value '%s' is invalid: %w"
+	errFmtLoggingSyslogTLSConfigInvalid = "log: syslog: tls: %w"
+	errFmtLoggingRedactionPrefixLength  = "log: redaction: option '%s' must be between 0 and %d but it's configured as '%d'"
 
 	errFileHashing  = "config key incorrect: authentication_backend.file.hashing should be authentication_backend.file.password"
 	errFilePHashing = "config key incorrect: authentication_backend.file.password_hashing should be authentication_backend.file.password"
@@ -443,7 +730,13 @@ const (
 const (
 	errFmtIdentityValidationResetPasswordJWTAlgorithm      = "identity_validation: reset_password: option 'jwt_algorithm' must be one of %s but it's configured as '%s'"
 	errFmtIdentityValidationResetPasswordJWTSecret         = "identity_validation: reset_password: option 'jwt_secret' is required when the reset password functionality isn't disabled"
+	errFmtIdentityValidationResetPasswordJWTKey            = "identity_validation: reset_password: option 'jwt_algorithm' is configured as '%s' which requires the 'identity_providers: oidc: jwks' option to have a key with this algorithm but none are configured"
+	errFmtIdentityValidationResetPasswordJWTKeyID          = "identity_validation: reset_password: option 'jwt_key_id' is configured as '%s' but the 'identity_providers: oidc: jwks' option does not have a key with this key id and the algorithm '%s'"
 	errFmtIdentityValidationElevatedSessionCharacterLength = "identity_validation: elevated_session: option 'characters' must be 20 or less but it's configured as %d"
+	errFmtIdentityValidationElevatedSessionCharacterSet    = "identity_validation: elevated_session: option 'character_set' must be one of %s but it's configured as '%s'"
+	errFmtIdentityValidationElevatedSessionDelivery        = "identity_validation: elevated_session: option 'delivery' must be one of %s but it's configured as '%s'"
+	errFmtIdentityValidationEmailDomainsInvalid            = "identity_validation: email_domains: option '%s' must only contain valid domains but it contains '%s'"
+	errFmtIdentityValidationEmailDomainsAllowAndDeny       = "identity_validation: email_domains: the domain '%s' is configured in both option 'allow' and option 'deny'"
 )
 
 const (
@@ -463,11 +756,25 @@ const (
 	auto = "auto"
 )
 
+var validSessionStorages = []string{schema.SessionStorageMemory, schema.SessionStorageRedis, schema.SessionStorageSQL}
+
+var validLeaderElectionBackends = []string{schema.LeaderElectionBackendStorage, schema.LeaderElectionBackendRedis}
+
+var validCircuitBreakerDependencies = []string{schema.CircuitBreakerDependencyLDAP, schema.CircuitBreakerDependencySMTP, schema.CircuitBreakerDependencyDuo, schema.CircuitBreakerDependencyPush, schema.CircuitBreakerDependencyStorage}
+
+var validPushProviders = []string{schema.PushProviderPrivacyIDEA, schema.PushProviderWebhook, schema.PushProviderMobile, schema.PushProviderNtfy, schema.PushProviderGotify}
+
 var (
 	validAuthzImplementations       = []string{schema.AuthzImplementationAuthRequest, schema.AuthzImplementationForwardAuth, schema.AuthzImplementationExtAuthz, schema.AuthzImplementationLegacy}
 	validAuthzAuthnStrategies       = []string{schema.AuthzStrategyHeaderCookieSession, schema.AuthzStrategyHeaderAuthorization, schema.AuthzStrategyHeaderProxyAuthorization, schema.AuthzStrategyHeaderAuthRequestProxyAuthorization, schema.AuthzStrategyHeaderLegacy}
 	validAuthzAuthnHeaderStrategies = []string{schema.AuthzStrategyHeaderAuthorization, schema.AuthzStrategyHeaderProxyAuthorization, schema.AuthzStrategyHeaderAuthRequestProxyAuthorization}
 	validAuthzAuthnStrategySchemes  = []string{schema.SchemeBasic, schema.SchemeBearer}
+
+	validServerHeadersReferrerPolicies            = []string{"no-referrer", "no-referrer-when-downgrade", "origin", "origin-when-cross-origin", "same-origin", "strict-origin", "strict-origin-when-cross-origin", "unsafe-url"}
+	validServerHeadersFrameOptions                = []string{"DENY", "SAMEORIGIN"}
+	validServerHeadersCrossOriginOpenerPolicies   = []string{"unsafe-none", "same-origin-allow-popups", "same-origin"}
+	validServerHeadersCrossOriginEmbedderPolicies = []string{"unsafe-none", "require-corp", "credentialless"}
+	validServerHeadersCrossOriginResourcePolicies = []string{"same-site", "same-origin", "cross-origin"}
 )
 
 var (
@@ -491,17 +798,26 @@ var (
 	validSHA2CryptVariants = []string{digestSHA256, digestSHA512}
 	validPBKDF2Variants    = []string{digestSHA1, digestSHA224, digestSHA256, digestSHA384, digestSHA512}
 	validBCryptVariants    = []string{"standard", digestSHA256}
-	validHashAlgorithms    = []string{hashSHA2Crypt, hashPBKDF2, hashSCrypt, hashBCrypt, hashArgon2}
+	validBalloonVariants   = []string{digestSHA256, digestSHA512}
+	validHashAlgorithms    = []string{hashSHA2Crypt, hashPBKDF2, hashSCrypt, hashBCrypt, hashBalloon, hashArgon2}
+)
+
+var (
+	validSessionBrandingSupportContactSchemes = []string{"https", "mailto", "tel"}
 )
 
 var (
 	validStoragePostgreSQLSSLModes           = []string{"disable", "require", "verify-ca", "verify-full"}
+	validStorageIAMAuthenticationProviders   = []string{schema.StorageIAMAuthenticationProviderAWS, schema.StorageIAMAuthenticationProviderAzure, schema.StorageIAMAuthenticationProviderGCP}
 	validThemeNames                          = []string{"light", "dark", "grey", auto}
 	validSessionSameSiteValues               = []string{"none", "lax", "strict"}
 	validLogLevels                           = []string{logging.LevelTrace, logging.LevelDebug, logging.LevelInfo, logging.LevelWarn, logging.LevelError}
-	validLogFormats                          = []string{logging.FormatText, logging.FormatJSON}
+	validLogFormats                          = []string{logging.FormatText, logging.FormatJSON, logging.FormatCEF, logging.FormatLEEF, logging.FormatECS}
+	validTelemetryMetricsStatsDFlavors       = []string{schema.TelemetryMetricsStatsDFlavorStatsD, schema.TelemetryMetricsStatsDFlavorDogStatsD}
 	validWebAuthnConveyancePreferences       = []string{string(protocol.PreferNoAttestation), string(protocol.PreferIndirectAttestation), string(protocol.PreferDirectAttestation)}
 	validWebAuthnUserVerificationRequirement = []string{string(protocol.VerificationDiscouraged), string(protocol.VerificationPreferred), string(protocol.VerificationRequired)}
+	validWebAuthnAttachments                 = []string{string(protocol.Platform), string(protocol.CrossPlatform)}
+	validWebAuthnDiscoverability             = []string{string(protocol.ResidentKeyRequirementDiscouraged), string(protocol.ResidentKeyRequirementPreferred), string(protocol.ResidentKeyRequirementRequired)}
 	validRFC7231HTTPMethodVerbs              = []string{fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodPost, fasthttp.MethodPut, fasthttp.MethodPatch, fasthttp.MethodDelete, fasthttp.MethodTrace, fasthttp.MethodConnect, fasthttp.MethodOptions}
 	validRFC4918HTTPMethodVerbs              = []string{"COPY", "LOCK", "MKCOL", "MOVE", "PROPFIND", "PROPPATCH", "UNLOCK"}
 )
@@ -509,10 +825,21 @@ var (
 var (
 	validACLHTTPMethodVerbs = append(validRFC7231HTTPMethodVerbs, validRFC4918HTTPMethodVerbs...)
 	validACLRulePolicies    = []string{policyBypass, policyOneFactor, policyTwoFactor, policyDeny}
+	validACLRuleChallenges  = []string{challengeAuto, challengeRedirect, challengeStatus}
+	validACLRuleDevices     = []string{deviceAny, deviceManaged, deviceUnmanaged}
+	validUserVerifications  = []string{string(protocol.VerificationPreferred), string(protocol.VerificationRequired)}
 	validACLRuleOperators   = []string{operatorPresent, operatorAbsent, operatorEqual, operatorNotEqual, operatorPattern, operatorNotPattern}
 )
 
-var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push"}
+var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push", "sms", "email"}
+
+var validSMSProviders = []string{schema.SMSProviderTwilio, schema.SMSProviderVonage, schema.SMSProviderHTTP}
+
+var (
+	validSAMLSignatureAlgorithms   = []string{"RS256", "RS512"}
+	validSAMLNameIDFormats         = []string{"unspecified", "email", "persistent", "transient"}
+	validSAMLAuthorizationPolicies = []string{policyOneFactor, policyTwoFactor}
+)
 
 const (
 	attrOIDCKey                   = "key"
@@ -540,17 +867,29 @@ const (
 	attrOIDCAccessTokenSigKID     = "access_token_signed_response_key_id"
 	attrOIDCPKCEChallengeMethod   = "pkce_challenge_method"
 	attrOIDCRequestedAudienceMode = "requested_audience_mode"
+	attrOIDCBackchannelTokenMode  = "backchannel_token_delivery_mode"
+	attrOIDCUserVerification      = "webauthn_user_verification"
 	attrSessionAutheliaURL        = "authelia_url"
 	attrSessionDomain             = "domain"
 	attrDefaultRedirectionURL     = "default_redirection_url"
+	attrSessionBrandingTitle      = "title"
+	attrSessionBrandingLogoURL    = "logo_url"
+	attrSessionBrandingAnnounce   = "announcement"
+	attrSessionBrandingSupport    = "support_contact"
 )
 
 var (
-	validIdentityValidationJWTAlgorithms = []string{oidc.SigningAlgHMACUsingSHA256, oidc.SigningAlgHMACUsingSHA384, oidc.SigningAlgHMACUsingSHA512}
+	validIdentityValidationJWTHMACAlgorithms = []string{oidc.SigningAlgHMACUsingSHA256, oidc.SigningAlgHMACUsingSHA384, oidc.SigningAlgHMACUsingSHA512}
+	validIdentityValidationJWTAlgorithms     = []string{oidc.SigningAlgHMACUsingSHA256, oidc.SigningAlgHMACUsingSHA384, oidc.SigningAlgHMACUsingSHA512, oidc.SigningAlgRSAUsingSHA256, oidc.SigningAlgRSAUsingSHA384, oidc.SigningAlgRSAUsingSHA512, oidc.SigningAlgECDSAUsingP256AndSHA256, oidc.SigningAlgECDSAUsingP384AndSHA384, oidc.SigningAlgECDSAUsingP521AndSHA512, oidc.SigningAlgRSAPSSUsingSHA256, oidc.SigningAlgRSAPSSUsingSHA384, oidc.SigningAlgRSAPSSUsingSHA512}
+	validIdentityValidationCharacterSets     = []string{schema.OneTimeCodeCharacterSetUnambiguous, schema.OneTimeCodeCharacterSetAlphaNumeric, schema.OneTimeCodeCharacterSetNumeric}
+	validIdentityValidationDeliveries        = []string{schema.OneTimeCodeDeliveryEmail}
 )
 
 var (
-	validOIDCCORSEndpoints = []string{oidc.EndpointAuthorization, oidc.EndpointPushedAuthorizationRequest, oidc.EndpointToken, oidc.EndpointIntrospection, oidc.EndpointRevocation, oidc.EndpointUserinfo}
+	validOIDCDynamicClientRegistrationScopes     = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, oidc.ScopeOfflineAccess, oidc.ScopeOffline}
+	validOIDCDynamicClientRegistrationGrantTypes = []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken, oidc.GrantTypeClientCredentials, oidc.GrantTypeDeviceCode}
+
+	validOIDCCORSEndpoints = []string{oidc.EndpointAuthorization, oidc.EndpointPushedAuthorizationRequest, oidc.EndpointDeviceAuthorization, oidc.EndpointToken, oidc.EndpointIntrospection, oidc.EndpointRevocation, oidc.EndpointUserinfo}
 
 	validOIDCClientScopes                    = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, oidc.ScopeOfflineAccess, oidc.ScopeOffline, oidc.ScopeAutheliaBearerAuthz}
 	validOIDCClientConsentModes              = []string{auto, oidc.ClientConsentModeImplicit.String(), oidc.ClientConsentModeExplicit.String(), oidc.ClientConsentModePreConfigured.String()}
@@ -559,7 +898,7 @@ var (
 	validOIDCClientResponseTypesImplicitFlow = []string{oidc.ResponseTypeImplicitFlowIDToken, oidc.ResponseTypeImplicitFlowToken, oidc.ResponseTypeImplicitFlowBoth}
 	validOIDCClientResponseTypesHybridFlow   = []string{oidc.ResponseTypeHybridFlowIDToken, oidc.ResponseTypeHybridFlowToken, oidc.ResponseTypeHybridFlowBoth}
 	validOIDCClientResponseTypesRefreshToken = []string{oidc.ResponseTypeAuthorizationCodeFlow, oidc.ResponseTypeHybridFlowIDToken, oidc.ResponseTypeHybridFlowToken, oidc.ResponseTypeHybridFlowBoth}
-	validOIDCClientGrantTypes                = []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeImplicit, oidc.GrantTypeClientCredentials, oidc.GrantTypeRefreshToken}
+	validOIDCClientGrantTypes                = []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeImplicit, oidc.GrantTypeClientCredentials, oidc.GrantTypeRefreshToken, oidc.GrantTypeDeviceCode, oidc.GrantTypeTokenExchange, oidc.GrantTypeCIBA}
 
 	validOIDCClientTokenEndpointAuthMethods                = []string{oidc.ClientAuthMethodNone, oidc.ClientAuthMethodClientSecretPost, oidc.ClientAuthMethodClientSecretBasic, oidc.ClientAuthMethodPrivateKeyJWT, oidc.ClientAuthMethodClientSecretJWT}
 	validOIDCClientTokenEndpointAuthMethodsConfidential    = []string{oidc.ClientAuthMethodClientSecretPost, oidc.ClientAuthMethodClientSecretBasic, oidc.ClientAuthMethodPrivateKeyJWT}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateDeviceCertificates validates and updates the device certificates configuration.
+func ValidateDeviceCertificates(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.DeviceCertificates.Enabled {
+		return
+	}
+
+	if config.DeviceCertificates.Lifespan <= 0 {
+		config.DeviceCertificates.Lifespan = schema.DefaultDeviceCertificatesConfiguration.Lifespan
+	}
+
+	if config.DeviceCertificates.Header == "" {
+		config.DeviceCertificates.Header = schema.DefaultDeviceCertificatesConfiguration.Header
+	}
+
+	if config.DeviceCertificates.PrivateKey == nil {
+		validator.Push(fmt.Errorf(errFmtDeviceCertificatesOptionRequired, "private_key"))
+	}
+
+	if !config.DeviceCertificates.CertificateChain.HasCertificates() {
+		validator.Push(fmt.Errorf(errFmtDeviceCertificatesOptionRequired, "certificate_chain"))
+
+		return
+	}
+
+	if err := config.DeviceCertificates.CertificateChain.Validate(); err != nil {
+		validator.Push(fmt.Errorf(errFmtDeviceCertificatesChainInvalid, err))
+	}
+
+	if leaf := config.DeviceCertificates.CertificateChain.Leaf(); !leaf.IsCA || leaf.KeyUsage&x509.KeyUsageCertSign == 0 {
+		validator.Push(errors.New(errDeviceCertificatesNotCA))
+	}
+
+	if config.DeviceCertificates.PrivateKey != nil && !config.DeviceCertificates.CertificateChain.EqualKey(config.DeviceCertificates.PrivateKey) {
+		validator.Push(errors.New(errDeviceCertificatesKeyMismatch))
+	}
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateDeviceCertificates(t *testing.T) {
+	chain := MustLoadCertificateChain("CA", "rsa.2048")
+
+	key, ok := MustLoadCrypto("CA", "rsa.2048", "pem").(*rsa.PrivateKey)
+	require.True(t, ok)
+
+	testCases := []struct {
+		name     string
+		have     schema.DeviceCertificates
+		expected schema.DeviceCertificates
+		errors   []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.DeviceCertificates{},
+			schema.DeviceCertificates{},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: key},
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: key, Lifespan: time.Hour * 24, Header: "X-Forwarded-Tls-Client-Cert"},
+			nil,
+		},
+		{
+			"ShouldNotOverrideCustomValues",
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: key, Lifespan: time.Hour, Header: "X-SSL-Client-Cert"},
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: key, Lifespan: time.Hour, Header: "X-SSL-Client-Cert"},
+			nil,
+		},
+		{
+			"ShouldErrorMissingOptions",
+			schema.DeviceCertificates{Enabled: true},
+			schema.DeviceCertificates{Enabled: true, Lifespan: time.Hour * 24, Header: "X-Forwarded-Tls-Client-Cert"},
+			[]string{
+				"device_certificates: option 'private_key' is required",
+				"device_certificates: option 'certificate_chain' is required",
+			},
+		},
+		{
+			"ShouldErrorNotCertificateAuthority",
+			schema.DeviceCertificates{Enabled: true, CertificateChain: certRSA2048, PrivateKey: keyRSA2048},
+			schema.DeviceCertificates{Enabled: true, CertificateChain: certRSA2048, PrivateKey: keyRSA2048, Lifespan: time.Hour * 24, Header: "X-Forwarded-Tls-Client-Cert"},
+			[]string{
+				"device_certificates: option 'certificate_chain' must have a certificate authority certificate with the certificate signing key usage as the first certificate",
+			},
+		},
+		{
+			"ShouldErrorKeyMismatch",
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: keyRSA2048},
+			schema.DeviceCertificates{Enabled: true, CertificateChain: chain, PrivateKey: keyRSA2048, Lifespan: time.Hour * 24, Header: "X-Forwarded-Tls-Client-Cert"},
+			[]string{
+				"device_certificates: option 'certificate_chain' does not appear to contain the public key for the private key provided by option 'private_key'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.Configuration{DeviceCertificates: tc.have}
+
+			ValidateDeviceCertificates(config, validator)
+
+			assert.Equal(t, tc.expected, config.DeviceCertificates)
+
+			errors := validator.Errors()
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, errors, len(tc.errors))
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, errors[i], err)
+			}
+		})
+	}
+}
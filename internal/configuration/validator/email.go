@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateEmail validates and updates the Email second factor configuration.
+func ValidateEmail(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Email.Enable {
+		return
+	}
+
+	switch {
+	case config.Email.Characters == 0:
+		config.Email.Characters = schema.DefaultEmailConfiguration.Characters
+	case config.Email.Characters < 6, config.Email.Characters > 20:
+		validator.Push(fmt.Errorf(errFmtEmailCharacters, config.Email.Characters))
+	}
+
+	switch {
+	case len(config.Email.CharacterSet) == 0:
+		config.Email.CharacterSet = schema.DefaultEmailConfiguration.CharacterSet
+	case !utils.IsStringInSlice(config.Email.CharacterSet, validIdentityValidationCharacterSets):
+		validator.Push(fmt.Errorf(errFmtEmailCharacterSet, utils.StringJoinOr(validIdentityValidationCharacterSets), config.Email.CharacterSet))
+	}
+
+	if config.Email.CodeLifespan <= 0 {
+		config.Email.CodeLifespan = schema.DefaultEmailConfiguration.CodeLifespan
+	}
+
+	if config.Email.MaximumAttempts <= 0 {
+		config.Email.MaximumAttempts = schema.DefaultEmailConfiguration.MaximumAttempts
+	}
+
+	switch {
+	case config.Email.Cooldown == 0:
+		config.Email.Cooldown = schema.DefaultEmailConfiguration.Cooldown
+	case config.Email.Cooldown < 0:
+		validator.Push(fmt.Errorf(errFmtEmailCooldown, config.Email.Cooldown))
+	case config.Email.Cooldown >= config.Email.CodeLifespan:
+		validator.Push(fmt.Errorf(errFmtEmailCooldownLifespan, config.Email.Cooldown, config.Email.CodeLifespan))
+	}
+}
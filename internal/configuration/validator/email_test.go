@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateEmail(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.Email
+		expected func(t *testing.T, config *schema.Email)
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.Email{Characters: 2, CharacterSet: "abc"},
+			func(t *testing.T, config *schema.Email) {
+				assert.False(t, config.Enable)
+				assert.Equal(t, 2, config.Characters)
+				assert.Equal(t, time.Duration(0), config.Cooldown)
+			},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.Email{Enable: true},
+			func(t *testing.T, config *schema.Email) {
+				assert.True(t, config.Enable)
+				assert.Equal(t, 8, config.Characters)
+				assert.Equal(t, schema.OneTimeCodeCharacterSetUnambiguous, config.CharacterSet)
+				assert.Equal(t, time.Minute*10, config.CodeLifespan)
+				assert.Equal(t, 3, config.MaximumAttempts)
+				assert.Equal(t, time.Minute, config.Cooldown)
+				assert.False(t, config.EnableMagicLink)
+			},
+			nil,
+		},
+		{
+			"ShouldNotOverrideConfigured",
+			schema.Email{Enable: true, Characters: 6, CharacterSet: schema.OneTimeCodeCharacterSetNumeric, CodeLifespan: time.Minute * 5, MaximumAttempts: 5, Cooldown: time.Second * 30, EnableMagicLink: true},
+			func(t *testing.T, config *schema.Email) {
+				assert.Equal(t, 6, config.Characters)
+				assert.Equal(t, schema.OneTimeCodeCharacterSetNumeric, config.CharacterSet)
+				assert.Equal(t, time.Minute*5, config.CodeLifespan)
+				assert.Equal(t, 5, config.MaximumAttempts)
+				assert.Equal(t, time.Second*30, config.Cooldown)
+				assert.True(t, config.EnableMagicLink)
+			},
+			nil,
+		},
+		{
+			"ShouldErrorCharacters",
+			schema.Email{Enable: true, Characters: 4},
+			nil,
+			[]string{
+				"email: option 'characters' must be between 6 and 20 but it's configured as '4'",
+			},
+		},
+		{
+			"ShouldErrorCharacterSet",
+			schema.Email{Enable: true, CharacterSet: "emoji"},
+			nil,
+			[]string{
+				"email: option 'character_set' must be one of 'unambiguous', 'alphanumeric', or 'numeric' but it's configured as 'emoji'",
+			},
+		},
+		{
+			"ShouldErrorNegativeCooldown",
+			schema.Email{Enable: true, Cooldown: time.Second * -1},
+			nil,
+			[]string{
+				"email: option 'cooldown' must be greater than 0 but it's configured as '-1s'",
+			},
+		},
+		{
+			"ShouldErrorCooldownExceedsLifespan",
+			schema.Email{Enable: true, CodeLifespan: time.Minute, Cooldown: time.Minute * 2},
+			nil,
+			[]string{
+				"email: option 'cooldown' must be less than the option 'code_lifespan' but they're configured as '2m0s' and '1m0s' respectively",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Email: tc.have}
+
+			ValidateEmail(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+
+			if tc.expected != nil {
+				tc.expected(t, &config.Email)
+			}
+		})
+	}
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"sort"
 	"strconv"
 
 	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/go-webauthn/webauthn/protocol"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
@@ -19,6 +21,7 @@ import (
 // ValidateIdentityProviders validates and updates the IdentityProviders configuration.
 func ValidateIdentityProviders(ctx *ValidateCtx, config *schema.IdentityProviders, validator *schema.StructValidator) {
 	validateOIDC(ctx, config.OIDC, validator)
+	validateSAML(config.SAML, validator)
 }
 
 func validateOIDC(ctx *ValidateCtx, config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator) {
@@ -51,11 +54,14 @@ func validateOIDC(ctx *ValidateCtx, config *schema.IdentityProvidersOpenIDConnec
 	}
 
 	validateOIDCOptionsCORS(config, validator)
+	validateOIDCDynamicClientRegistration(config, validator)
+	validateOIDCBackchannelAuthentication(config, validator)
 
-	if len(config.Clients) == 0 {
-		validator.Push(errors.New(errFmtOIDCProviderNoClientsConfigured))
-	} else {
+	switch {
+	case len(config.Clients) != 0:
 		validateOIDCClients(ctx, config, validator)
+	case !config.DynamicClientRegistration.Enabled:
+		validator.Push(errors.New(errFmtOIDCProviderNoClientsConfigured))
 	}
 }
 
@@ -362,6 +368,68 @@ func validateOIDCOptionsCORSEndpoints(config *schema.IdentityProvidersOpenIDConn
 	}
 }
 
+func validateOIDCDynamicClientRegistration(config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator) {
+	if !config.DynamicClientRegistration.Enabled {
+		return
+	}
+
+	dcr := &config.DynamicClientRegistration
+
+	switch {
+	case dcr.OpenRegistration && len(dcr.InitialAccessTokens) != 0:
+		validator.Push(errors.New(errOIDCDynamicClientRegistrationOpenInitialAccessTokens))
+	case dcr.OpenRegistration:
+		validator.PushWarning(errors.New(errOIDCDynamicClientRegistrationOpen))
+	case len(dcr.InitialAccessTokens) == 0:
+		validator.Push(errors.New(errOIDCDynamicClientRegistrationNoInitialAccessTokens))
+	}
+
+	for i, token := range dcr.InitialAccessTokens {
+		if len(token) < 20 {
+			validator.Push(fmt.Errorf(errFmtOIDCDynamicClientRegistrationInitialAccessTokenLength, i+1))
+		}
+	}
+
+	switch {
+	case dcr.AuthorizationPolicy == "":
+		dcr.AuthorizationPolicy = schema.DefaultOpenIDConnectDynamicClientRegistrationConfiguration.AuthorizationPolicy
+	case !utils.IsStringInSlice(dcr.AuthorizationPolicy, config.Discovery.AuthorizationPolicies):
+		validator.Push(fmt.Errorf(errFmtOIDCDynamicClientRegistrationInvalidValue, "authorization_policy", utils.StringJoinOr(config.Discovery.AuthorizationPolicies), dcr.AuthorizationPolicy))
+	}
+
+	if len(dcr.Scopes) == 0 {
+		dcr.Scopes = schema.DefaultOpenIDConnectDynamicClientRegistrationConfiguration.Scopes
+	} else if invalid, _ := validateList(dcr.Scopes, validOIDCDynamicClientRegistrationScopes, false); len(invalid) != 0 {
+		validator.Push(fmt.Errorf(errFmtOIDCDynamicClientRegistrationInvalidEntries, attrOIDCScopes, utils.StringJoinOr(validOIDCDynamicClientRegistrationScopes), utils.StringJoinAnd(invalid)))
+	}
+
+	if len(dcr.GrantTypes) == 0 {
+		dcr.GrantTypes = schema.DefaultOpenIDConnectDynamicClientRegistrationConfiguration.GrantTypes
+	} else if invalid, _ := validateList(dcr.GrantTypes, validOIDCDynamicClientRegistrationGrantTypes, false); len(invalid) != 0 {
+		validator.Push(fmt.Errorf(errFmtOIDCDynamicClientRegistrationInvalidEntries, attrOIDCGrantTypes, utils.StringJoinOr(validOIDCDynamicClientRegistrationGrantTypes), utils.StringJoinAnd(invalid)))
+	}
+}
+
+func validateOIDCBackchannelAuthentication(config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator) {
+	if !config.BackchannelAuthentication.Enabled {
+		return
+	}
+
+	ciba := &config.BackchannelAuthentication
+
+	if ciba.Lifespan <= 0 {
+		ciba.Lifespan = schema.DefaultOpenIDConnectBackchannelAuthenticationConfiguration.Lifespan
+	}
+
+	if ciba.PollingInterval <= 0 {
+		ciba.PollingInterval = schema.DefaultOpenIDConnectBackchannelAuthenticationConfiguration.PollingInterval
+	}
+
+	if ciba.PollingInterval >= ciba.Lifespan {
+		validator.Push(fmt.Errorf(errFmtOIDCBackchannelAuthenticationPollingInterval, ciba.PollingInterval, ciba.Lifespan))
+	}
+}
+
 func validateOIDCClients(ctx *ValidateCtx, config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator) {
 	var (
 		errDeprecated bool
@@ -460,6 +528,15 @@ func validateOIDCClient(ctx *ValidateCtx, c int, config *schema.IdentityProvider
 		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidValue, config.Clients[c].ID, attrOIDCRequestedAudienceMode, utils.StringJoinOr([]string{oidc.ClientRequestedAudienceModeExplicit.String(), oidc.ClientRequestedAudienceModeImplicit.String()}), config.Clients[c].RequestedAudienceMode))
 	}
 
+	switch config.Clients[c].WebAuthnUserVerification {
+	case "":
+		config.Clients[c].WebAuthnUserVerification = string(protocol.VerificationPreferred)
+	case string(protocol.VerificationPreferred), string(protocol.VerificationRequired):
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidValue, config.Clients[c].ID, attrOIDCUserVerification, utils.StringJoinOr(validUserVerifications), config.Clients[c].WebAuthnUserVerification))
+	}
+
 	setDefaults := validateOIDCClientScopesSpecialBearerAuthz(c, config, ccg, validator)
 
 	validateOIDCClientConsentMode(c, config, validator, setDefaults)
@@ -470,6 +547,7 @@ func validateOIDCClient(ctx *ValidateCtx, c int, config *schema.IdentityProvider
 	validateOIDCClientGrantTypes(c, config, validator, setDefaults, errDeprecatedFunc)
 	validateOIDCClientRedirectURIs(c, config, validator, errDeprecatedFunc)
 	validateOIDCClientRequestURIs(c, config, validator)
+	validateOIDCClientBackchannelAuthentication(c, config, validator)
 
 	validateOIDDClientSigningAlgs(c, config, validator)
 
@@ -674,7 +752,8 @@ func validateOIDCClientScopes(c int, config *schema.IdentityProvidersOpenIDConne
 	}
 
 	if utils.IsStringSliceContainsAny([]string{oidc.ScopeOfflineAccess, oidc.ScopeOffline}, config.Clients[c].Scopes) &&
-		!utils.IsStringSliceContainsAny(validOIDCClientResponseTypesRefreshToken, config.Clients[c].ResponseTypes) {
+		!utils.IsStringSliceContainsAny(validOIDCClientResponseTypesRefreshToken, config.Clients[c].ResponseTypes) &&
+		!utils.IsStringInSlice(oidc.GrantTypeDeviceCode, config.Clients[c].GrantTypes) {
 		errDeprecatedFunc()
 
 		validator.PushWarning(fmt.Errorf(errFmtOIDCClientInvalidRefreshTokenOptionWithoutCodeResponseType,
@@ -887,9 +966,17 @@ func validateOIDCClientGrantTypesCheckRelated(c int, config *schema.IdentityProv
 
 				validator.PushWarning(fmt.Errorf(errFmtOIDCClientInvalidGrantTypeMatch, config.Clients[c].ID, grantType, "for either the implicit or hybrid flow", utils.StringJoinOr(append(append([]string{}, validOIDCClientResponseTypesImplicitFlow...), validOIDCClientResponseTypesHybridFlow...)), utils.StringJoinAnd(config.Clients[c].ResponseTypes)))
 			}
-		case oidc.GrantTypeClientCredentials:
+		case oidc.GrantTypeClientCredentials, oidc.GrantTypeTokenExchange:
+			if config.Clients[c].Public {
+				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidGrantTypePublic, config.Clients[c].ID, grantType))
+			}
+		case oidc.GrantTypeCIBA:
 			if config.Clients[c].Public {
-				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidGrantTypePublic, config.Clients[c].ID, oidc.GrantTypeClientCredentials))
+				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidGrantTypePublic, config.Clients[c].ID, grantType))
+			}
+
+			if !config.BackchannelAuthentication.Enabled {
+				validator.Push(fmt.Errorf(errFmtOIDCClientInvalidGrantTypeCIBADisabled, config.Clients[c].ID, grantType))
 			}
 		case oidc.GrantTypeRefreshToken:
 			if !utils.IsStringSliceContainsAny([]string{oidc.ScopeOfflineAccess, oidc.ScopeOffline}, config.Clients[c].Scopes) {
@@ -898,7 +985,8 @@ func validateOIDCClientGrantTypesCheckRelated(c int, config *schema.IdentityProv
 				validator.PushWarning(fmt.Errorf(errFmtOIDCClientInvalidGrantTypeRefresh, config.Clients[c].ID))
 			}
 
-			if !utils.IsStringSliceContainsAny(validOIDCClientResponseTypesRefreshToken, config.Clients[c].ResponseTypes) {
+			if !utils.IsStringSliceContainsAny(validOIDCClientResponseTypesRefreshToken, config.Clients[c].ResponseTypes) &&
+				!utils.IsStringSliceContainsAny([]string{oidc.GrantTypeDeviceCode, oidc.GrantTypeCIBA}, config.Clients[c].GrantTypes) {
 				errDeprecatedFunc()
 
 				validator.PushWarning(fmt.Errorf(errFmtOIDCClientInvalidRefreshTokenOptionWithoutCodeResponseType,
@@ -911,6 +999,28 @@ func validateOIDCClientGrantTypesCheckRelated(c int, config *schema.IdentityProv
 	}
 }
 
+func validateOIDCClientBackchannelAuthentication(c int, config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator) {
+	if !utils.IsStringInSlice(oidc.GrantTypeCIBA, config.Clients[c].GrantTypes) {
+		return
+	}
+
+	switch config.Clients[c].BackchannelTokenDeliveryMode {
+	case "":
+		config.Clients[c].BackchannelTokenDeliveryMode = oidc.BackchannelTokenDeliveryModePoll
+	case oidc.BackchannelTokenDeliveryModePoll:
+		break
+	case oidc.BackchannelTokenDeliveryModePing:
+		switch endpoint := config.Clients[c].BackchannelClientNotificationEndpoint; {
+		case endpoint == nil:
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidBackchannelNotificationEndpointRequired, config.Clients[c].ID))
+		case endpoint.Scheme != schemeHTTPS:
+			validator.Push(fmt.Errorf(errFmtOIDCClientInvalidBackchannelNotificationEndpointScheme, config.Clients[c].ID, endpoint.Scheme))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtOIDCClientInvalidValue, config.Clients[c].ID, attrOIDCBackchannelTokenMode, utils.StringJoinOr([]string{oidc.BackchannelTokenDeliveryModePoll, oidc.BackchannelTokenDeliveryModePing}), config.Clients[c].BackchannelTokenDeliveryMode))
+	}
+}
+
 func validateOIDCClientRedirectURIs(c int, config *schema.IdentityProvidersOpenIDConnect, validator *schema.StructValidator, errDeprecatedFunc func()) {
 	var (
 		parsedRedirectURI *url.URL
@@ -1233,3 +1343,128 @@ func validateOIDCAlgKIDDefault(config *schema.IdentityProvidersOpenIDConnect, al
 
 	return
 }
+
+func validateSAML(config *schema.IdentityProvidersSAML, validator *schema.StructValidator) {
+	if config == nil {
+		return
+	}
+
+	if config.SignatureAlgorithm == "" {
+		config.SignatureAlgorithm = schema.DefaultSAMLConfiguration.SignatureAlgorithm
+	} else if !utils.IsStringInSlice(config.SignatureAlgorithm, validSAMLSignatureAlgorithms) {
+		validator.Push(fmt.Errorf(errFmtSAMLInvalidOption, "signature_algorithm", utils.StringJoinOr(validSAMLSignatureAlgorithms), config.SignatureAlgorithm))
+	}
+
+	if config.AssertionLifespan <= durationZero {
+		config.AssertionLifespan = schema.DefaultSAMLConfiguration.AssertionLifespan
+	}
+
+	if config.SessionLifespan <= durationZero {
+		config.SessionLifespan = schema.DefaultSAMLConfiguration.SessionLifespan
+	}
+
+	validateSAMLKeyPair(config, validator)
+
+	if len(config.ServiceProviders) == 0 {
+		validator.Push(errors.New(errFmtSAMLNoServiceProviders))
+
+		return
+	}
+
+	entityIDs := map[string]bool{}
+
+	for i := range config.ServiceProviders {
+		sp := &config.ServiceProviders[i]
+
+		if sp.EntityID == "" {
+			validator.Push(fmt.Errorf(errFmtSAMLServiceProviderNoEntityID, i+1))
+
+			continue
+		}
+
+		if entityIDs[sp.EntityID] {
+			validator.Push(fmt.Errorf(errFmtSAMLServiceProviderDuplicateEntityID, sp.EntityID))
+		}
+
+		entityIDs[sp.EntityID] = true
+
+		validateSAMLServiceProvider(sp, validator)
+	}
+}
+
+func validateSAMLKeyPair(config *schema.IdentityProvidersSAML, validator *schema.StructValidator) {
+	checkEqualKey := true
+
+	switch {
+	case config.PrivateKey == nil || config.PrivateKey.PublicKey.N == nil:
+		validator.Push(errors.New(errFmtSAMLNoPrivateKey))
+
+		checkEqualKey = false
+	case config.PrivateKey.Size() < 256:
+		validator.Push(fmt.Errorf(errFmtSAMLPrivateKeyRSALessThan2048, config.PrivateKey.Size()*8))
+
+		checkEqualKey = false
+	}
+
+	if !config.CertificateChain.HasCertificates() {
+		validator.Push(errors.New(errFmtSAMLNoCertificateChain))
+
+		return
+	}
+
+	if checkEqualKey && !config.CertificateChain.EqualKey(config.PrivateKey) {
+		validator.Push(errors.New(errFmtSAMLCertificateMismatch))
+	}
+
+	if err := config.CertificateChain.Validate(); err != nil {
+		validator.Push(fmt.Errorf(errFmtSAMLCertificateChainInvalid, err))
+	}
+}
+
+func validateSAMLServiceProvider(config *schema.IdentityProvidersSAMLServiceProvider, validator *schema.StructValidator) {
+	if config.NameIDFormat == "" {
+		config.NameIDFormat = schema.DefaultSAMLServiceProviderConfiguration.NameIDFormat
+	} else if !utils.IsStringInSlice(config.NameIDFormat, validSAMLNameIDFormats) {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderInvalidOption, config.EntityID, "name_id_format", utils.StringJoinOr(validSAMLNameIDFormats), config.NameIDFormat))
+	}
+
+	if config.AuthorizationPolicy == "" {
+		config.AuthorizationPolicy = schema.DefaultSAMLServiceProviderConfiguration.AuthorizationPolicy
+	} else if !utils.IsStringInSlice(config.AuthorizationPolicy, validSAMLAuthorizationPolicies) {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderInvalidOption, config.EntityID, "authorization_policy", utils.StringJoinOr(validSAMLAuthorizationPolicies), config.AuthorizationPolicy))
+	}
+
+	if config.Metadata != "" {
+		if _, err := os.Stat(config.Metadata); err != nil {
+			validator.Push(fmt.Errorf(errFmtSAMLServiceProviderMetadata, config.EntityID, err))
+		}
+	} else if len(config.AssertionConsumerServiceURLs) == 0 {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderNoACSURLs, config.EntityID))
+	}
+
+	for _, uri := range config.AssertionConsumerServiceURLs {
+		if !isSAMLServiceProviderURL(uri) {
+			validator.Push(fmt.Errorf(errFmtSAMLServiceProviderInvalidURL, config.EntityID, "assertion_consumer_service_urls", uri))
+		}
+	}
+
+	if config.SingleLogoutServiceURL != nil && !isSAMLServiceProviderURL(config.SingleLogoutServiceURL) {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderInvalidURL, config.EntityID, "single_logout_service_url", config.SingleLogoutServiceURL))
+	}
+
+	if !config.CertificateChain.HasCertificates() {
+		return
+	}
+
+	if _, ok := config.CertificateChain.Leaf().PublicKey.(*rsa.PublicKey); !ok {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderCertificateNotRSA, config.EntityID))
+	}
+
+	if err := config.CertificateChain.Validate(); err != nil {
+		validator.Push(fmt.Errorf(errFmtSAMLServiceProviderCertificateChain, config.EntityID, err))
+	}
+}
+
+func isSAMLServiceProviderURL(uri *url.URL) bool {
+	return uri != nil && uri.IsAbs() && uri.Scheme == schemeHTTPS && uri.Host != ""
+}
@@ -110,7 +110,7 @@ func TestShouldRaiseErrorWhenCORSEndpointsNotValid(t *testing.T) {
 
 	require.Len(t, validator.Errors(), 1)
 
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: cors: option 'endpoints' contains an invalid value 'invalid_endpoint': must be one of 'authorization', 'pushed-authorization-request', 'token', 'introspection', 'revocation', or 'userinfo'")
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: cors: option 'endpoints' contains an invalid value 'invalid_endpoint': must be one of 'authorization', 'pushed-authorization-request', 'device-authorization', 'token', 'introspection', 'revocation', or 'userinfo'")
 }
 
 func TestShouldRaiseErrorWhenOIDCPKCEEnforceValueInvalid(t *testing.T) {
@@ -183,6 +183,25 @@ func TestShouldRaiseErrorWhenOIDCServerNoClients(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'clients' must have one or more clients configured")
 }
 
+func TestShouldNotRaiseErrorWhenOIDCServerNoClientsWithDynamicClientRegistration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProviders{
+		OIDC: &schema.IdentityProvidersOpenIDConnect{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: keyRSA2048,
+			DynamicClientRegistration: schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L"},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(NewValidateCtx(), config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+}
+
 func TestShouldRaiseErrorWhenOIDCServerClientBadValues(t *testing.T) {
 	mux := http.NewServeMux()
 
@@ -584,6 +603,39 @@ func TestShouldRaiseErrorWhenOIDCServerClientBadValues(t *testing.T) {
 				"identity_providers: oidc: clients: client 'client-bad-ram': option 'requested_audience_mode' must be one of 'explicit' or 'implicit' but it's configured as 'magic'",
 			},
 		},
+		{
+			name: "SetDefaultUserVerification",
+			clients: []schema.IdentityProvidersOpenIDConnectClient{
+				{
+					ID:                  "client-no-uv",
+					Secret:              tOpenIDConnectPlainTextClientSecret,
+					AuthorizationPolicy: policyTwoFactor,
+					RedirectURIs: []string{
+						"https://google.com",
+					},
+				},
+			},
+			test: func(t *testing.T, actual []schema.IdentityProvidersOpenIDConnectClient) {
+				assert.Equal(t, "preferred", actual[0].WebAuthnUserVerification)
+			},
+		},
+		{
+			name: "InvalidUserVerification",
+			clients: []schema.IdentityProvidersOpenIDConnectClient{
+				{
+					ID:                  "client-bad-uv",
+					Secret:              tOpenIDConnectPlainTextClientSecret,
+					AuthorizationPolicy: policyTwoFactor,
+					RedirectURIs: []string{
+						"https://google.com",
+					},
+					WebAuthnUserVerification: "discouraged",
+				},
+			},
+			errors: []string{
+				"identity_providers: oidc: clients: client 'client-bad-uv': option 'webauthn_user_verification' must be one of 'preferred' or 'required' but it's configured as 'discouraged'",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -658,7 +710,7 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadGrantTypes(t *testing.T)
 	ValidateIdentityProviders(NewValidateCtx(), config, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: clients: client 'good_id': option 'grant_types' must only have the values 'authorization_code', 'implicit', 'client_credentials', or 'refresh_token' but the values 'bad_grant_type' are present")
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: clients: client 'good_id': option 'grant_types' must only have the values 'authorization_code', 'implicit', 'client_credentials', 'refresh_token', 'urn:ietf:params:oauth:grant-type:device_code', 'urn:ietf:params:oauth:grant-type:token-exchange', or 'urn:openid:params:grant-type:ciba' but the values 'bad_grant_type' are present")
 }
 
 func TestShouldNotErrorOnCertificateValid(t *testing.T) {
@@ -1334,6 +1386,25 @@ func TestValidateOIDCClients(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"ShouldNotWarnOnRefreshTokenWithDeviceCodeGrantType",
+			nil,
+			nil,
+			tcv{
+				[]string{oidc.ScopeOpenID, oidc.ScopeProfile, oidc.ScopeOfflineAccess},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				nil,
+				[]string{oidc.GrantTypeDeviceCode, oidc.GrantTypeRefreshToken},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID, oidc.ScopeProfile, oidc.ScopeOfflineAccess},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeDeviceCode, oidc.GrantTypeRefreshToken},
+			},
+			nil,
+			nil,
+		},
 		{
 			"ShouldRaiseErrorOnInvalidGrantTypes",
 			nil,
@@ -1352,7 +1423,7 @@ func TestValidateOIDCClients(t *testing.T) {
 			},
 			nil,
 			[]string{
-				"identity_providers: oidc: clients: client 'test': option 'grant_types' must only have the values 'authorization_code', 'implicit', 'client_credentials', or 'refresh_token' but the values 'invalid' are present",
+				"identity_providers: oidc: clients: client 'test': option 'grant_types' must only have the values 'authorization_code', 'implicit', 'client_credentials', 'refresh_token', 'urn:ietf:params:oauth:grant-type:device_code', 'urn:ietf:params:oauth:grant-type:token-exchange', or 'urn:openid:params:grant-type:ciba' but the values 'invalid' are present",
 			},
 		},
 		{
@@ -1401,6 +1472,157 @@ func TestValidateOIDCClients(t *testing.T) {
 				"identity_providers: oidc: clients: client 'test': option 'grant_types' should only have the 'client_credentials' value if it is of the confidential client type but it's of the public client type",
 			},
 		},
+		{
+			"ShouldRaiseErrorOnTokenExchangeGrantTypeForPublicClient",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.Clients[0].Public = true
+				have.Clients[0].Secret = nil
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+			},
+			nil,
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeTokenExchange},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeTokenExchange},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: clients: client 'test': option 'grant_types' should only have the 'urn:ietf:params:oauth:grant-type:token-exchange' value if it is of the confidential client type but it's of the public client type",
+			},
+		},
+		{
+			"ShouldSetDefaultBackchannelTokenDeliveryModeForCIBAGrantType",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.BackchannelAuthentication.Enabled = true
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+			},
+			func(t *testing.T, have *schema.IdentityProvidersOpenIDConnect) {
+				assert.Equal(t, oidc.BackchannelTokenDeliveryModePoll, have.Clients[0].BackchannelTokenDeliveryMode)
+			},
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeCIBA},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeCIBA},
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnCIBAGrantTypeForPublicClientAndDisabledProvider",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.Clients[0].Public = true
+				have.Clients[0].Secret = nil
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+			},
+			nil,
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeCIBA},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeCIBA},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: clients: client 'test': option 'grant_types' should only have the 'urn:openid:params:grant-type:ciba' value if it is of the confidential client type but it's of the public client type",
+				"identity_providers: oidc: clients: client 'test': option 'grant_types' must only have the 'urn:openid:params:grant-type:ciba' value if the 'backchannel_authentication' option is enabled for the provider",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnCIBAPingModeWithoutNotificationEndpoint",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.BackchannelAuthentication.Enabled = true
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+				have.Clients[0].BackchannelTokenDeliveryMode = oidc.BackchannelTokenDeliveryModePing
+			},
+			nil,
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeCIBA},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeCIBA},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: clients: client 'test': option 'backchannel_client_notification_endpoint' is required when option 'backchannel_token_delivery_mode' is configured as 'ping'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnCIBAPingModeWithInsecureNotificationEndpoint",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.BackchannelAuthentication.Enabled = true
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+				have.Clients[0].BackchannelTokenDeliveryMode = oidc.BackchannelTokenDeliveryModePing
+				have.Clients[0].BackchannelClientNotificationEndpoint = MustParseURL("http://app.example.com/ciba")
+			},
+			nil,
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeCIBA},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeCIBA},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: clients: client 'test': option 'backchannel_client_notification_endpoint' must have the 'https' scheme but the scheme is 'http'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnCIBAInvalidTokenDeliveryMode",
+			func(have *schema.IdentityProvidersOpenIDConnect) {
+				have.BackchannelAuthentication.Enabled = true
+				have.Clients[0].Scopes = []string{oidc.ScopeOpenID}
+				have.Clients[0].BackchannelTokenDeliveryMode = "push"
+			},
+			nil,
+			tcv{
+				nil,
+				nil,
+				nil,
+				[]string{oidc.GrantTypeCIBA},
+			},
+			tcv{
+				[]string{oidc.ScopeOpenID},
+				[]string{oidc.ResponseTypeAuthorizationCodeFlow},
+				[]string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery},
+				[]string{oidc.GrantTypeCIBA},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: clients: client 'test': option 'backchannel_token_delivery_mode' must be one of 'poll' or 'ping' but it's configured as 'push'",
+			},
+		},
 		{
 			"ShouldNotRaiseErrorOnValidGrantTypesForConfidentialClient",
 			func(have *schema.IdentityProvidersOpenIDConnect) {
@@ -4124,3 +4346,397 @@ func init() {
 
 	keyRSA2048Legacy = MustLoadRSAPrivateKey("2048", "legacy")
 }
+
+func TestValidateOIDCBackchannelAuthentication(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.IdentityProvidersOpenIDConnectBackchannelAuthentication
+		expected schema.IdentityProvidersOpenIDConnectBackchannelAuthentication
+		errors   []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{},
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{
+				Enabled: true,
+			},
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{
+				Enabled:         true,
+				Lifespan:        time.Minute * 5,
+				PollingInterval: time.Second * 5,
+			},
+			nil,
+		},
+		{
+			"ShouldErrorOnPollingIntervalLongerThanLifespan",
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{
+				Enabled:         true,
+				Lifespan:        time.Minute,
+				PollingInterval: time.Minute * 2,
+			},
+			schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{
+				Enabled:         true,
+				Lifespan:        time.Minute,
+				PollingInterval: time.Minute * 2,
+			},
+			[]string{
+				"identity_providers: oidc: backchannel_authentication: option 'polling_interval' must be less than option 'lifespan' but it's configured as '2m0s' and option 'lifespan' is configured as '1m0s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.IdentityProvidersOpenIDConnect{
+				BackchannelAuthentication: tc.have,
+			}
+
+			validateOIDCBackchannelAuthentication(config, validator)
+
+			assert.Equal(t, tc.expected, config.BackchannelAuthentication)
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errors))
+
+			for i, expected := range tc.errors {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestValidateOIDCDynamicClientRegistration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.IdentityProvidersOpenIDConnectDynamicClientRegistration
+		expected schema.IdentityProvidersOpenIDConnectDynamicClientRegistration
+		warnings []string
+		errors   []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{},
+			nil,
+			nil,
+		},
+		{
+			"ShouldSetDefaultsAndWarnOpenRegistration",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:          true,
+				OpenRegistration: true,
+			},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				OpenRegistration:    true,
+				AuthorizationPolicy: "two_factor",
+				Scopes:              []string{"openid", "offline_access", "groups", "profile", "email"},
+				GrantTypes:          []string{"authorization_code", "refresh_token"},
+			},
+			[]string{
+				"identity_providers: oidc: dynamic_client_registration: option 'open_registration' is enabled which allows anyone who can reach the registration endpoint to register a public client",
+			},
+			nil,
+		},
+		{
+			"ShouldErrorOnMissingInitialAccessTokens",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled: true,
+			},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				AuthorizationPolicy: "two_factor",
+				Scopes:              []string{"openid", "offline_access", "groups", "profile", "email"},
+				GrantTypes:          []string{"authorization_code", "refresh_token"},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: dynamic_client_registration: option 'initial_access_tokens' is required unless option 'open_registration' is enabled",
+			},
+		},
+		{
+			"ShouldErrorOnOpenRegistrationWithInitialAccessTokens",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				OpenRegistration:    true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L"},
+			},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				OpenRegistration:    true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L"},
+				AuthorizationPolicy: "two_factor",
+				Scopes:              []string{"openid", "offline_access", "groups", "profile", "email"},
+				GrantTypes:          []string{"authorization_code", "refresh_token"},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: dynamic_client_registration: option 'open_registration' must not be enabled when option 'initial_access_tokens' is configured",
+			},
+		},
+		{
+			"ShouldAllowCustomValues",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L"},
+				AuthorizationPolicy: "one_factor",
+				Scopes:              []string{"openid"},
+				GrantTypes:          []string{"client_credentials"},
+			},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L"},
+				AuthorizationPolicy: "one_factor",
+				Scopes:              []string{"openid"},
+				GrantTypes:          []string{"client_credentials"},
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldErrorOnBadValues",
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L", "abc"},
+				AuthorizationPolicy: "deny",
+				Scopes:              []string{"openid", "authelia.bearer.authz"},
+				GrantTypes:          []string{"implicit", "refresh_token"},
+			},
+			schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+				Enabled:             true,
+				InitialAccessTokens: []string{"ayGPPF3beAwacxMmnQ4VQMVuKZWeZj3L", "abc"},
+				AuthorizationPolicy: "deny",
+				Scopes:              []string{"openid", "authelia.bearer.authz"},
+				GrantTypes:          []string{"implicit", "refresh_token"},
+			},
+			nil,
+			[]string{
+				"identity_providers: oidc: dynamic_client_registration: option 'authorization_policy' must be one of 'one_factor' or 'two_factor' but it's configured as 'deny'",
+				"identity_providers: oidc: dynamic_client_registration: option 'grant_types' must only have the values 'authorization_code', 'refresh_token', 'client_credentials', or 'urn:ietf:params:oauth:grant-type:device_code' but the values 'implicit' are present",
+				"identity_providers: oidc: dynamic_client_registration: option 'initial_access_tokens' has an invalid value: token #2 must be at least 20 characters long",
+				"identity_providers: oidc: dynamic_client_registration: option 'scopes' must only have the values 'openid', 'email', 'profile', 'groups', 'offline_access', or 'offline' but the values 'authelia.bearer.authz' are present",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.IdentityProvidersOpenIDConnect{
+				DynamicClientRegistration: tc.have,
+			}
+
+			validateOIDCAuthorizationPolicies(config, validator)
+			validateOIDCDynamicClientRegistration(config, validator)
+
+			assert.Equal(t, tc.expected, config.DynamicClientRegistration)
+
+			warns := validator.Warnings()
+			sort.Sort(utils.ErrSliceSortAlphabetical(warns))
+
+			require.Len(t, warns, len(tc.warnings))
+
+			for i, expected := range tc.warnings {
+				assert.EqualError(t, warns[i], expected)
+			}
+
+			errs := validator.Errors()
+			sort.Sort(utils.ErrSliceSortAlphabetical(errs))
+
+			require.Len(t, errs, len(tc.errors))
+
+			for i, expected := range tc.errors {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestValidateSAML(t *testing.T) {
+	acs := []*url.URL{MustParseURL("https://app.example.com/saml/acs")}
+
+	testCases := []struct {
+		name     string
+		have     *schema.IdentityProvidersSAML
+		expected *schema.IdentityProvidersSAML
+		errors   []string
+	}{
+		{
+			"ShouldNotValidateWhenNotConfigured",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			&schema.IdentityProvidersSAML{
+				CertificateChain: certRSA2048,
+				PrivateKey:       keyRSA2048,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs},
+				},
+			},
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA2048,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "RS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+				},
+			},
+			nil,
+		},
+		{
+			"ShouldErrorMissingOptions",
+			&schema.IdentityProvidersSAML{},
+			&schema.IdentityProvidersSAML{
+				SignatureAlgorithm: "RS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+			},
+			[]string{
+				"identity_providers: saml: option 'private_key' is required",
+				"identity_providers: saml: option 'certificate_chain' is required",
+				"identity_providers: saml: option 'service_providers' must have at least one service provider",
+			},
+		},
+		{
+			"ShouldErrorKeyMismatch",
+			&schema.IdentityProvidersSAML{
+				CertificateChain: certRSA4096,
+				PrivateKey:       keyRSA2048,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs},
+				},
+			},
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA4096,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "RS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+				},
+			},
+			[]string{
+				"identity_providers: saml: option 'certificate_chain' does not appear to contain the public key for the private key provided by option 'private_key'",
+			},
+		},
+		{
+			"ShouldErrorInvalidServiceProviders",
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA2048,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "HS256",
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{},
+					{EntityID: "https://app.example.com", NameIDFormat: "x509", AuthorizationPolicy: "deny"},
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: []*url.URL{MustParseURL("http://app.example.com/saml/acs")}, SingleLogoutServiceURL: MustParseURL("/saml/slo")},
+					{EntityID: "https://other.example.com", Metadata: "/not/a/file.xml"},
+				},
+			},
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA2048,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "HS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{},
+					{EntityID: "https://app.example.com", NameIDFormat: "x509", AuthorizationPolicy: "deny"},
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: []*url.URL{MustParseURL("http://app.example.com/saml/acs")}, SingleLogoutServiceURL: MustParseURL("/saml/slo"), NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+					{EntityID: "https://other.example.com", Metadata: "/not/a/file.xml", NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+				},
+			},
+			[]string{
+				"identity_providers: saml: option 'signature_algorithm' must be one of 'RS256' or 'RS512' but it's configured as 'HS256'",
+				"identity_providers: saml: service_providers: service provider #1: option 'entity_id' is required",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'name_id_format' must be one of 'unspecified', 'email', 'persistent', or 'transient' but it's configured as 'x509'",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'authorization_policy' must be one of 'one_factor' or 'two_factor' but it's configured as 'deny'",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'assertion_consumer_service_urls' is required when option 'metadata' is not configured",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'entity_id' must be unique but it's configured for more than one service provider",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'assertion_consumer_service_urls' must be an absolute URL with the 'https' scheme but it's configured as 'http://app.example.com/saml/acs'",
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'single_logout_service_url' must be an absolute URL with the 'https' scheme but it's configured as '/saml/slo'",
+				"identity_providers: saml: service_providers: service provider 'https://other.example.com': option 'metadata' must be the path to a file but an error occurred: stat /not/a/file.xml: no such file or directory",
+			},
+		},
+		{
+			"ShouldAllowServiceProviderCertificate",
+			&schema.IdentityProvidersSAML{
+				CertificateChain: certRSA2048,
+				PrivateKey:       keyRSA2048,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, CertificateChain: certRSA4096},
+				},
+			},
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA2048,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "RS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, CertificateChain: certRSA4096, NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+				},
+			},
+			nil,
+		},
+		{
+			"ShouldErrorServiceProviderCertificateNotRSA",
+			&schema.IdentityProvidersSAML{
+				CertificateChain: certRSA2048,
+				PrivateKey:       keyRSA2048,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, CertificateChain: certECDSAP256},
+				},
+			},
+			&schema.IdentityProvidersSAML{
+				CertificateChain:   certRSA2048,
+				PrivateKey:         keyRSA2048,
+				SignatureAlgorithm: "RS256",
+				AssertionLifespan:  time.Minute * 5,
+				SessionLifespan:    time.Hour * 8,
+				ServiceProviders: []schema.IdentityProvidersSAMLServiceProvider{
+					{EntityID: "https://app.example.com", AssertionConsumerServiceURLs: acs, CertificateChain: certECDSAP256, NameIDFormat: "unspecified", AuthorizationPolicy: policyTwoFactor},
+				},
+			},
+			[]string{
+				"identity_providers: saml: service_providers: service provider 'https://app.example.com': option 'certificate_chain' must contain an RSA public key",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.IdentityProviders{SAML: tc.have}
+
+			ValidateIdentityProviders(NewValidateCtx(), config, validator)
+
+			assert.Equal(t, tc.expected, config.SAML)
+
+			errs := validator.Errors()
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, errs, len(tc.errors))
+
+			for i, expected := range tc.errors {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
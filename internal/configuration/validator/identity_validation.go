@@ -3,6 +3,8 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -21,8 +23,18 @@ func ValidateIdentityValidation(config *schema.Configuration, validator *schema.
 		validator.Push(fmt.Errorf(errFmtIdentityValidationResetPasswordJWTAlgorithm, utils.StringJoinOr(validIdentityValidationJWTAlgorithms), config.IdentityValidation.ResetPassword.JWTAlgorithm))
 	}
 
-	if !config.AuthenticationBackend.PasswordReset.Disable && len(config.IdentityValidation.ResetPassword.JWTSecret) == 0 {
-		validator.Push(errors.New(errFmtIdentityValidationResetPasswordJWTSecret))
+	if !config.AuthenticationBackend.PasswordReset.Disable {
+		validateIdentityValidationResetPasswordJWTKey(config, validator)
+	}
+
+	if !config.IdentityValidation.ResetPassword.IssuanceRateLimit.Disable {
+		if config.IdentityValidation.ResetPassword.IssuanceRateLimit.Requests <= 0 {
+			config.IdentityValidation.ResetPassword.IssuanceRateLimit.Requests = schema.DefaultIdentityValidation.ResetPassword.IssuanceRateLimit.Requests
+		}
+
+		if config.IdentityValidation.ResetPassword.IssuanceRateLimit.Period <= 0 {
+			config.IdentityValidation.ResetPassword.IssuanceRateLimit.Period = schema.DefaultIdentityValidation.ResetPassword.IssuanceRateLimit.Period
+		}
 	}
 
 	if config.IdentityValidation.ElevatedSession.CodeLifespan <= 0 {
@@ -38,4 +50,80 @@ func ValidateIdentityValidation(config *schema.Configuration, validator *schema.
 	} else if config.IdentityValidation.ElevatedSession.Characters > 20 {
 		validator.Push(fmt.Errorf(errFmtIdentityValidationElevatedSessionCharacterLength, config.IdentityValidation.ElevatedSession.Characters))
 	}
+
+	switch {
+	case len(config.IdentityValidation.ElevatedSession.CharacterSet) == 0:
+		config.IdentityValidation.ElevatedSession.CharacterSet = schema.DefaultIdentityValidation.ElevatedSession.CharacterSet
+	case !utils.IsStringInSlice(config.IdentityValidation.ElevatedSession.CharacterSet, validIdentityValidationCharacterSets):
+		validator.Push(fmt.Errorf(errFmtIdentityValidationElevatedSessionCharacterSet, utils.StringJoinOr(validIdentityValidationCharacterSets), config.IdentityValidation.ElevatedSession.CharacterSet))
+	}
+
+	switch {
+	case len(config.IdentityValidation.ElevatedSession.Delivery) == 0:
+		config.IdentityValidation.ElevatedSession.Delivery = schema.DefaultIdentityValidation.ElevatedSession.Delivery
+	case !utils.IsStringInSlice(config.IdentityValidation.ElevatedSession.Delivery, validIdentityValidationDeliveries):
+		validator.Push(fmt.Errorf(errFmtIdentityValidationElevatedSessionDelivery, utils.StringJoinOr(validIdentityValidationDeliveries), config.IdentityValidation.ElevatedSession.Delivery))
+	}
+
+	validateIdentityValidationEmailDomains(config, validator)
+}
+
+// validateIdentityValidationResetPasswordJWTKey ensures the key material required by the configured algorithm is
+// available. The HMAC algorithms use the secret, all other algorithms use one of the OpenID Connect 1.0 issuer keys
+// which also allows the tokens to be validated by other services using the public JSON Web Key Set.
+func validateIdentityValidationResetPasswordJWTKey(config *schema.Configuration, validator *schema.StructValidator) {
+	reset := &config.IdentityValidation.ResetPassword
+
+	if utils.IsStringInSlice(reset.JWTAlgorithm, validIdentityValidationJWTHMACAlgorithms) {
+		if len(reset.JWTSecret) == 0 {
+			validator.Push(errors.New(errFmtIdentityValidationResetPasswordJWTSecret))
+		}
+
+		return
+	}
+
+	if !utils.IsStringInSlice(reset.JWTAlgorithm, validIdentityValidationJWTAlgorithms) {
+		return
+	}
+
+	if config.IdentityProviders.OIDC != nil {
+		for _, jwk := range config.IdentityProviders.OIDC.JSONWebKeys {
+			if jwk.Algorithm != reset.JWTAlgorithm || (len(reset.JWTKeyID) != 0 && jwk.KeyID != reset.JWTKeyID) {
+				continue
+			}
+
+			reset.JWTKeyID = jwk.KeyID
+
+			return
+		}
+	}
+
+	if len(reset.JWTKeyID) == 0 {
+		validator.Push(fmt.Errorf(errFmtIdentityValidationResetPasswordJWTKey, reset.JWTAlgorithm))
+	} else {
+		validator.Push(fmt.Errorf(errFmtIdentityValidationResetPasswordJWTKeyID, reset.JWTKeyID, reset.JWTAlgorithm))
+	}
+}
+
+func validateIdentityValidationEmailDomains(config *schema.Configuration, validator *schema.StructValidator) {
+	domains := &config.IdentityValidation.EmailDomains
+
+	validateIdentityValidationEmailDomainsList("allow", domains.Allow, validator)
+	validateIdentityValidationEmailDomainsList("deny", domains.Deny, validator)
+
+	for _, domain := range domains.Allow {
+		if slices.Contains(domains.Deny, domain) {
+			validator.Push(fmt.Errorf(errFmtIdentityValidationEmailDomainsAllowAndDeny, domain))
+		}
+	}
+}
+
+func validateIdentityValidationEmailDomainsList(option string, domains []string, validator *schema.StructValidator) {
+	for i, domain := range domains {
+		domains[i] = strings.ToLower(domain)
+
+		if !reDomainCharacters.MatchString(domains[i]) {
+			validator.Push(fmt.Errorf(errFmtIdentityValidationEmailDomainsInvalid, option, domain))
+		}
+	}
 }
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,16 +26,121 @@ func TestShouldRaiseErrorWithUndefinedJWTSecretKey(t *testing.T) {
 func TestShouldErrorOnInvalidAlgorithms(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
-	config.IdentityValidation.ResetPassword.JWTAlgorithm = "RS256"
+	config.IdentityValidation.ResetPassword.JWTAlgorithm = "HS1"
 
 	ValidateConfiguration(&config, validator)
 	require.Len(t, validator.Errors(), 1)
 	require.Len(t, validator.Warnings(), 1)
 
-	assert.EqualError(t, validator.Errors()[0], "identity_validation: reset_password: option 'jwt_algorithm' must be one of 'HS256', 'HS384', or 'HS512' but it's configured as 'RS256'")
+	assert.EqualError(t, validator.Errors()[0], "identity_validation: reset_password: option 'jwt_algorithm' must be one of 'HS256', 'HS384', 'HS512', 'RS256', 'RS384', 'RS512', 'ES256', 'ES384', 'ES512', 'PS256', 'PS384', or 'PS512' but it's configured as 'HS1'")
 	assert.EqualError(t, validator.Warnings()[0], "access_control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests")
 }
 
+func TestShouldValidateIdentityValidationResetPasswordJWTKey(t *testing.T) {
+	jwks := []schema.JWK{
+		{KeyID: "rsa", Algorithm: "RS256", Key: keyRSA2048},
+		{KeyID: "ecdsa", Algorithm: "ES256", Key: keyECDSAP256},
+		{KeyID: "ecdsa-next", Algorithm: "ES256", Key: keyECDSAP256},
+	}
+
+	testCases := []struct {
+		name     string
+		have     schema.IdentityValidationResetPassword
+		oidc     *schema.IdentityProvidersOpenIDConnect
+		disable  bool
+		expected string
+		errs     []string
+	}{
+		{
+			"ShouldAllowHMACWithSecret",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "HS512", JWTSecret: "abc"},
+			nil,
+			false,
+			"",
+			nil,
+		},
+		{
+			"ShouldSetKeyIDFromFirstMatchingKey",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "ES256"},
+			&schema.IdentityProvidersOpenIDConnect{JSONWebKeys: jwks},
+			false,
+			"ecdsa",
+			nil,
+		},
+		{
+			"ShouldAllowKeyID",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "ES256", JWTKeyID: "ecdsa-next"},
+			&schema.IdentityProvidersOpenIDConnect{JSONWebKeys: jwks},
+			false,
+			"ecdsa-next",
+			nil,
+		},
+		{
+			"ShouldNotRequireKeyWhenPasswordResetDisabled",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "ES256"},
+			nil,
+			true,
+			"",
+			nil,
+		},
+		{
+			"ShouldErrorNoOpenIDConnect",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "RS256"},
+			nil,
+			false,
+			"",
+			[]string{
+				"identity_validation: reset_password: option 'jwt_algorithm' is configured as 'RS256' which requires the 'identity_providers: oidc: jwks' option to have a key with this algorithm but none are configured",
+			},
+		},
+		{
+			"ShouldErrorNoKeyWithAlgorithm",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "PS256"},
+			&schema.IdentityProvidersOpenIDConnect{JSONWebKeys: jwks},
+			false,
+			"",
+			[]string{
+				"identity_validation: reset_password: option 'jwt_algorithm' is configured as 'PS256' which requires the 'identity_providers: oidc: jwks' option to have a key with this algorithm but none are configured",
+			},
+		},
+		{
+			"ShouldErrorKeyIDWithDifferentAlgorithm",
+			schema.IdentityValidationResetPassword{JWTAlgorithm: "ES256", JWTKeyID: "rsa"},
+			&schema.IdentityProvidersOpenIDConnect{JSONWebKeys: jwks},
+			false,
+			"rsa",
+			[]string{
+				"identity_validation: reset_password: option 'jwt_key_id' is configured as 'rsa' but the 'identity_providers: oidc: jwks' option does not have a key with this key id and the algorithm 'ES256'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.Configuration{
+				AuthenticationBackend: schema.AuthenticationBackend{PasswordReset: schema.AuthenticationBackendPasswordReset{Disable: tc.disable}},
+				IdentityValidation:    schema.IdentityValidation{ResetPassword: tc.have},
+				IdentityProviders:     schema.IdentityProviders{OIDC: tc.oidc},
+			}
+
+			ValidateIdentityValidation(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			assert.Equal(t, tc.expected, config.IdentityValidation.ResetPassword.JWTKeyID)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range errs {
+				assert.EqualError(t, err, tc.errs[i])
+			}
+		})
+	}
+}
+
 func TestShouldErrorOnInvalidCharLen(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
@@ -47,3 +153,97 @@ func TestShouldErrorOnInvalidCharLen(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "identity_validation: elevated_session: option 'characters' must be 20 or less but it's configured as 40")
 	assert.EqualError(t, validator.Warnings()[0], "access_control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests")
 }
+
+func TestShouldSetDefaultElevatedSessionCharacterSetAndDelivery(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateIdentityValidation(config, validator)
+
+	assert.Len(t, validator.Errors(), 1)
+	assert.Equal(t, schema.OneTimeCodeCharacterSetUnambiguous, config.IdentityValidation.ElevatedSession.CharacterSet)
+	assert.Equal(t, schema.OneTimeCodeDeliveryEmail, config.IdentityValidation.ElevatedSession.Delivery)
+}
+
+func TestShouldErrorOnInvalidElevatedSessionCharacterSetAndDelivery(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.IdentityValidation.ElevatedSession.CharacterSet = "symbols"
+	config.IdentityValidation.ElevatedSession.Delivery = "sms"
+
+	ValidateIdentityValidation(&config, validator)
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_validation: elevated_session: option 'character_set' must be one of 'unambiguous', 'alphanumeric', or 'numeric' but it's configured as 'symbols'")
+	assert.EqualError(t, validator.Errors()[1], "identity_validation: elevated_session: option 'delivery' must be one of 'email' but it's configured as 'sms'")
+}
+
+func TestShouldSetDefaultResetPasswordIssuanceRateLimit(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.IdentityValidation.ResetPassword.IssuanceRateLimit = schema.IdentityValidationRateLimit{Requests: -1}
+
+	ValidateIdentityValidation(&config, validator)
+	require.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, 5, config.IdentityValidation.ResetPassword.IssuanceRateLimit.Requests)
+	assert.Equal(t, time.Hour, config.IdentityValidation.ResetPassword.IssuanceRateLimit.Period)
+
+	config.IdentityValidation.ResetPassword.IssuanceRateLimit = schema.IdentityValidationRateLimit{Disable: true}
+
+	ValidateIdentityValidation(&config, validator)
+	require.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, schema.IdentityValidationRateLimit{Disable: true}, config.IdentityValidation.ResetPassword.IssuanceRateLimit)
+}
+
+func TestShouldValidateIdentityValidationEmailDomains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.IdentityValidationEmailDomains
+		expected schema.IdentityValidationEmailDomains
+		errs     []string
+	}{
+		{
+			"ShouldLowerCaseDomains",
+			schema.IdentityValidationEmailDomains{Allow: []string{"Example.com"}, Deny: []string{"PARTNER.example.com"}},
+			schema.IdentityValidationEmailDomains{Allow: []string{"example.com"}, Deny: []string{"partner.example.com"}},
+			nil,
+		},
+		{
+			"ShouldErrorOnInvalidDomains",
+			schema.IdentityValidationEmailDomains{Allow: []string{"@example.com"}, Deny: []string{"https://example.org"}},
+			schema.IdentityValidationEmailDomains{Allow: []string{"@example.com"}, Deny: []string{"https://example.org"}},
+			[]string{
+				"identity_validation: email_domains: option 'allow' must only contain valid domains but it contains '@example.com'",
+				"identity_validation: email_domains: option 'deny' must only contain valid domains but it contains 'https://example.org'",
+			},
+		},
+		{
+			"ShouldErrorOnDomainInBothLists",
+			schema.IdentityValidationEmailDomains{Allow: []string{"example.com"}, Deny: []string{"Example.com"}},
+			schema.IdentityValidationEmailDomains{Allow: []string{"example.com"}, Deny: []string{"example.com"}},
+			[]string{
+				"identity_validation: email_domains: the domain 'example.com' is configured in both option 'allow' and option 'deny'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultConfig()
+			config.IdentityValidation.EmailDomains = tc.have
+
+			ValidateIdentityValidation(&config, validator)
+
+			assert.Equal(t, tc.expected, config.IdentityValidation.EmailDomains)
+
+			require.Len(t, validator.Errors(), len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, validator.Errors()[i], err)
+			}
+		})
+	}
+}
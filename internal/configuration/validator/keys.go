@@ -12,6 +12,17 @@ import (
 
 // ValidateKeys determines if all provided keys are valid.
 func ValidateKeys(keys, remapped []string, prefix string, validator *schema.StructValidator) {
+	validateKeys(keys, remapped, prefix, false, nil, validator)
+}
+
+// ValidateKeysStrict determines if all provided keys are valid. Unlike ValidateKeys unexpected environment variables
+// are errors instead of warnings, and unexpected keys are reported at each of the provided positions they're
+// defined at.
+func ValidateKeysStrict(keys, remapped []string, prefix string, positions map[string][]string, validator *schema.StructValidator) {
+	validateKeys(keys, remapped, prefix, true, positions, validator)
+}
+
+func validateKeys(keys, remapped []string, prefix string, strict bool, positions map[string][]string, validator *schema.StructValidator) {
 	var errStrings []string
 
 	var patterns []*regexp.Regexp
@@ -55,10 +66,17 @@ KEYS:
 				errStrings = append(errStrings, err)
 			}
 		} else {
-			if strings.HasPrefix(key, prefix) {
-				validator.PushWarning(fmt.Errorf("configuration environment variable not expected: %s", key))
-			} else {
-				validator.Push(fmt.Errorf("configuration key not expected: %s", key))
+			switch {
+			case strings.HasPrefix(key, prefix) && strict:
+				validator.Push(fmt.Errorf(errFmtKeyEnvironmentNotExpected, key))
+			case strings.HasPrefix(key, prefix):
+				validator.PushWarning(fmt.Errorf(errFmtKeyEnvironmentNotExpected, key))
+			case len(positions[key]) != 0:
+				for _, position := range positions[key] {
+					validator.Push(fmt.Errorf(errFmtKeyNotExpectedPosition, position, key))
+				}
+			default:
+				validator.Push(fmt.Errorf(errFmtKeyNotExpected, key))
 			}
 		}
 	}
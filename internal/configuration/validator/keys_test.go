@@ -49,6 +49,30 @@ func TestShouldNotValidateBadEnvKeys(t *testing.T) {
 	assert.EqualError(t, warns[1], "configuration environment variable not expected: AUTHELIA_BAD_ENV_KEY")
 }
 
+func TestShouldNotValidateBadKeysStrict(t *testing.T) {
+	configKeys := schema.Keys
+	configKeys = append(configKeys, "bad_key")
+	configKeys = append(configKeys, "totp.skewy")
+	configKeys = append(configKeys, "AUTHELIA_BAD_ENV_KEY")
+
+	positions := map[string][]string{
+		"totp.skewy": {"config.yml:12:3", "config.extra.yml:2:3"},
+	}
+
+	validator := schema.NewStructValidator()
+	ValidateKeysStrict(configKeys, nil, "AUTHELIA_", positions, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+
+	errs := validator.Errors()
+	require.Len(t, errs, 4)
+
+	assert.EqualError(t, errs[0], "configuration key not expected: bad_key")
+	assert.EqualError(t, errs[1], "config.yml:12:3: configuration key not expected: totp.skewy")
+	assert.EqualError(t, errs[2], "config.extra.yml:2:3: configuration key not expected: totp.skewy")
+	assert.EqualError(t, errs[3], "configuration environment variable not expected: AUTHELIA_BAD_ENV_KEY")
+}
+
 func TestAllSpecificErrorKeys(t *testing.T) {
 	var configKeys []string //nolint:prealloc // This is because the test is dynamic based on the keys that exist in the map.
 
@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateLeaderElection validates and updates the leader election configuration.
+func ValidateLeaderElection(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.LeaderElection.LeaseDuration <= 0 {
+		config.LeaderElection.LeaseDuration = schema.DefaultLeaderElectionConfiguration.LeaseDuration
+	}
+
+	if config.LeaderElection.RenewInterval <= 0 {
+		config.LeaderElection.RenewInterval = schema.DefaultLeaderElectionConfiguration.RenewInterval
+	}
+
+	switch config.LeaderElection.Backend {
+	case "":
+		config.LeaderElection.Backend = schema.DefaultLeaderElectionConfiguration.Backend
+	case schema.LeaderElectionBackendStorage, schema.LeaderElectionBackendRedis:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtLeaderElectionBackend, utils.StringJoinOr(validLeaderElectionBackends), config.LeaderElection.Backend))
+	}
+
+	if config.LeaderElection.RenewInterval >= config.LeaderElection.LeaseDuration {
+		validator.Push(fmt.Errorf(errFmtLeaderElectionRenewInterval, config.LeaderElection.RenewInterval, config.LeaderElection.LeaseDuration))
+	}
+
+	if !config.LeaderElection.Enabled {
+		return
+	}
+
+	switch config.LeaderElection.Backend {
+	case schema.LeaderElectionBackendRedis:
+		if config.Session.Redis == nil {
+			validator.Push(errors.New(errLeaderElectionRedis))
+		}
+	case schema.LeaderElectionBackendStorage:
+		if config.Storage.Local != nil {
+			validator.PushWarning(errors.New(errLeaderElectionStorageLocal))
+		}
+	}
+}
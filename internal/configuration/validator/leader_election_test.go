@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateLeaderElection(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.Configuration
+		expected schema.LeaderElection
+		warnings []string
+		errors   []string
+	}{
+		{
+			"ShouldSetDefaults",
+			schema.Configuration{},
+			schema.LeaderElection{
+				Backend:       schema.LeaderElectionBackendStorage,
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldSetDefaultsWhenEnabled",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Enabled: true},
+				Storage:        schema.Storage{PostgreSQL: &schema.StoragePostgreSQL{}},
+			},
+			schema.LeaderElection{
+				Enabled:       true,
+				Backend:       schema.LeaderElectionBackendStorage,
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldAllowRedisBackend",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Enabled: true, Backend: schema.LeaderElectionBackendRedis, LeaseDuration: time.Minute, RenewInterval: time.Second * 20},
+				Session:        schema.Session{Redis: &schema.SessionRedis{}},
+			},
+			schema.LeaderElection{
+				Enabled:       true,
+				Backend:       schema.LeaderElectionBackendRedis,
+				LeaseDuration: time.Minute,
+				RenewInterval: time.Second * 20,
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldErrorInvalidBackend",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Backend: "etcd"},
+			},
+			schema.LeaderElection{
+				Backend:       "etcd",
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			[]string{
+				"leader_election: option 'backend' must be one of 'storage' or 'redis' but it's configured as 'etcd'",
+			},
+		},
+		{
+			"ShouldErrorRenewIntervalNotLessThanLeaseDuration",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{LeaseDuration: time.Second * 10, RenewInterval: time.Second * 10},
+			},
+			schema.LeaderElection{
+				Backend:       schema.LeaderElectionBackendStorage,
+				LeaseDuration: time.Second * 10,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			[]string{
+				"leader_election: option 'renew_interval' must be less than the option 'lease_duration' but it's configured as '10s' and the option 'lease_duration' is configured as '10s'",
+			},
+		},
+		{
+			"ShouldErrorRedisBackendWithoutSessionRedis",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Enabled: true, Backend: schema.LeaderElectionBackendRedis},
+			},
+			schema.LeaderElection{
+				Enabled:       true,
+				Backend:       schema.LeaderElectionBackendRedis,
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			[]string{
+				"leader_election: option 'backend' is configured as 'redis' but the 'session' section does not have the 'redis' option configured",
+			},
+		},
+		{
+			"ShouldNotErrorRedisBackendWithoutSessionRedisWhenDisabled",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Backend: schema.LeaderElectionBackendRedis},
+			},
+			schema.LeaderElection{
+				Backend:       schema.LeaderElectionBackendRedis,
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldWarnStorageBackendWithLocalStorage",
+			schema.Configuration{
+				LeaderElection: schema.LeaderElection{Enabled: true},
+				Storage:        schema.Storage{Local: &schema.StorageLocal{Path: "/config/db.sqlite3"}},
+			},
+			schema.LeaderElection{
+				Enabled:       true,
+				Backend:       schema.LeaderElectionBackendStorage,
+				LeaseDuration: time.Second * 30,
+				RenewInterval: time.Second * 10,
+			},
+			[]string{
+				"leader_election: option 'backend' is configured as 'storage' but the 'storage' section has the 'local' option configured which can't be shared by replicas",
+			},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			ValidateLeaderElection(&tc.have, validator)
+
+			assert.Equal(t, tc.expected, tc.have.LeaderElection)
+
+			warnings, errors := validator.Warnings(), validator.Errors()
+
+			require.Len(t, warnings, len(tc.warnings))
+			require.Len(t, errors, len(tc.errors))
+
+			for i, warning := range tc.warnings {
+				assert.EqualError(t, warnings[i], warning)
+			}
+
+			for i, err := range tc.errors {
+				assert.EqualError(t, errors[i], err)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -21,7 +24,142 @@ func ValidateLog(config *schema.Configuration, validator *schema.StructValidator
 		validator.Push(fmt.Errorf(errFmtLoggingInvalid, "format", utils.StringJoinOr(validLogFormats), config.Log.Format))
 	}
 
+	if config.Log.FileFormat != "" && !utils.IsStringInSlice(config.Log.FileFormat, validLogFormats) {
+		validator.Push(fmt.Errorf(errFmtLoggingInvalid, "file_format", utils.StringJoinOr(validLogFormats), config.Log.FileFormat))
+	}
+
 	if !utils.IsStringInSlice(config.Log.Level, validLogLevels) {
 		validator.Push(fmt.Errorf(errFmtLoggingInvalid, "level", utils.StringJoinOr(validLogLevels), config.Log.Level))
 	}
+
+	validateLogModules(config, validator)
+	validateLogRedaction(config, validator)
+	validateLogSyslog(config, validator)
+	validateLogJournald(config, validator)
+}
+
+func validateLogModules(config *schema.Configuration, validator *schema.StructValidator) {
+	for _, module := range []struct {
+		name  string
+		level string
+	}{
+		{logging.ModuleStorage, config.Log.Modules.Storage},
+		{logging.ModuleLDAP, config.Log.Modules.LDAP},
+		{logging.ModuleOpenIDConnect, config.Log.Modules.OpenIDConnect},
+		{logging.ModuleSession, config.Log.Modules.Session},
+	} {
+		if module.level != "" && !utils.IsStringInSlice(module.level, validLogLevels) {
+			validator.Push(fmt.Errorf(errFmtLoggingOutputInvalid, "modules", module.name, utils.StringJoinOr(validLogLevels), module.level))
+		}
+	}
+}
+
+func validateLogRedaction(config *schema.Configuration, validator *schema.StructValidator) {
+	switch {
+	case config.Log.Redaction.IPv4PrefixLength == 0:
+		config.Log.Redaction.IPv4PrefixLength = schema.DefaultLogRedactionConfiguration.IPv4PrefixLength
+	case config.Log.Redaction.IPv4PrefixLength < 0 || config.Log.Redaction.IPv4PrefixLength > 32:
+		validator.Push(fmt.Errorf(errFmtLoggingRedactionPrefixLength, "ipv4_prefix_length", 32, config.Log.Redaction.IPv4PrefixLength))
+	}
+
+	switch {
+	case config.Log.Redaction.IPv6PrefixLength == 0:
+		config.Log.Redaction.IPv6PrefixLength = schema.DefaultLogRedactionConfiguration.IPv6PrefixLength
+	case config.Log.Redaction.IPv6PrefixLength < 0 || config.Log.Redaction.IPv6PrefixLength > 128:
+		validator.Push(fmt.Errorf(errFmtLoggingRedactionPrefixLength, "ipv6_prefix_length", 128, config.Log.Redaction.IPv6PrefixLength))
+	}
+}
+
+func validateLogSyslog(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Log.Syslog.Enabled {
+		return
+	}
+
+	syslog := &config.Log.Syslog
+
+	if syslog.Level == "" {
+		syslog.Level = config.Log.Level
+	} else if !utils.IsStringInSlice(syslog.Level, validLogLevels) {
+		validator.Push(fmt.Errorf(errFmtLoggingOutputInvalid, "syslog", "level", utils.StringJoinOr(validLogLevels), syslog.Level))
+	}
+
+	if syslog.Format == "" {
+		syslog.Format = config.Log.Format
+	} else if !utils.IsStringInSlice(syslog.Format, validLogFormats) {
+		validator.Push(fmt.Errorf(errFmtLoggingOutputInvalid, "syslog", "format", utils.StringJoinOr(validLogFormats), syslog.Format))
+	}
+
+	if syslog.Facility == "" {
+		syslog.Facility = schema.DefaultLogSyslogConfiguration.Facility
+	} else if _, ok := logging.SyslogFacilities[syslog.Facility]; !ok {
+		validator.Push(fmt.Errorf(errFmtLoggingOutputInvalid, "syslog", "facility", utils.StringJoinOr(syslogFacilityNames()), syslog.Facility))
+	}
+
+	if syslog.Tag == "" {
+		syslog.Tag = schema.DefaultLogSyslogConfiguration.Tag
+	}
+
+	if syslog.Timeout <= 0 {
+		syslog.Timeout = schema.DefaultLogSyslogConfiguration.Timeout
+	}
+
+	switch {
+	case syslog.Address == nil:
+		validator.Push(errors.New(errFmtLoggingSyslogAddressRequired))
+	case syslog.Address.IsUnixDomainSocket():
+		break
+	default:
+		if err := syslog.Address.ValidateListener(); err != nil {
+			validator.Push(fmt.Errorf(errFmtLoggingSyslogAddress, syslog.Address.String(), err))
+		}
+
+		if syslog.Address.Port() == 0 {
+			if syslog.TLS != nil {
+				syslog.Address.SetPort(6514)
+			} else {
+				syslog.Address.SetPort(514)
+			}
+		}
+	}
+
+	if syslog.TLS == nil {
+		return
+	}
+
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: schema.DefaultLogSyslogConfiguration.TLS.MinimumVersion,
+		MaximumVersion: schema.DefaultLogSyslogConfiguration.TLS.MaximumVersion,
+	}
+
+	if syslog.Address != nil {
+		configDefaultTLS.ServerName = syslog.Address.Hostname()
+	}
+
+	if err := ValidateTLSConfig(syslog.TLS, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtLoggingSyslogTLSConfigInvalid, err))
+	}
+}
+
+func validateLogJournald(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Log.Journald.Enabled {
+		return
+	}
+
+	if config.Log.Journald.Level == "" {
+		config.Log.Journald.Level = config.Log.Level
+	} else if !utils.IsStringInSlice(config.Log.Journald.Level, validLogLevels) {
+		validator.Push(fmt.Errorf(errFmtLoggingOutputInvalid, "journald", "level", utils.StringJoinOr(validLogLevels), config.Log.Journald.Level))
+	}
+}
+
+func syslogFacilityNames() (facilities []string) {
+	facilities = make([]string, 0, len(logging.SyslogFacilities))
+
+	for facility := range logging.SyslogFacilities {
+		facilities = append(facilities, facility)
+	}
+
+	sort.Strings(facilities)
+
+	return facilities
 }
@@ -1,7 +1,9 @@
 package validator
 
 import (
+	"crypto/tls"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,5 +60,171 @@ func TestShouldRaiseErrorOnInvalidLoggingFormat(t *testing.T) {
 	assert.Len(t, validator.Warnings(), 0)
 	require.Len(t, validator.Errors(), 1)
 
-	assert.EqualError(t, validator.Errors()[0], "log: option 'format' must be one of 'text' or 'json' but it's configured as 'FORMAT'")
+	assert.EqualError(t, validator.Errors()[0], "log: option 'format' must be one of 'text', 'json', 'cef', 'leef', or 'ecs' but it's configured as 'FORMAT'")
+}
+
+func TestShouldRaiseErrorOnInvalidLoggingFileFormat(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Level:      "trace",
+			Format:     "ecs",
+			FileFormat: "FORMAT",
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "log: option 'file_format' must be one of 'text', 'json', 'cef', 'leef', or 'ecs' but it's configured as 'FORMAT'")
+}
+
+func TestShouldSetDefaultLoggingOutputValues(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Level:    "debug",
+			Format:   "ecs",
+			Syslog:   schema.LogSyslog{Enabled: true, Address: &schema.AddressTCP{Address: MustParseAddress("tcp://syslog.example.com")}},
+			Journald: schema.LogJournald{Enabled: true},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "tcp://syslog.example.com:514", config.Log.Syslog.Address.String())
+	assert.Equal(t, "debug", config.Log.Syslog.Level)
+	assert.Equal(t, "ecs", config.Log.Syslog.Format)
+	assert.Equal(t, "daemon", config.Log.Syslog.Facility)
+	assert.Equal(t, "authelia", config.Log.Syslog.Tag)
+	assert.Equal(t, time.Second*5, config.Log.Syslog.Timeout)
+	assert.Nil(t, config.Log.Syslog.TLS)
+
+	assert.Equal(t, "debug", config.Log.Journald.Level)
+}
+
+func TestShouldSetDefaultLoggingSyslogTLSValues(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Syslog: schema.LogSyslog{Enabled: true, Level: "warn", Address: &schema.AddressTCP{Address: MustParseAddress("tcp://syslog.example.com")}, TLS: &schema.TLS{}},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, "info", config.Log.Level)
+	assert.Equal(t, "warn", config.Log.Syslog.Level)
+	assert.Equal(t, "tcp://syslog.example.com:6514", config.Log.Syslog.Address.String())
+	require.NotNil(t, config.Log.Syslog.TLS)
+	assert.Equal(t, "syslog.example.com", config.Log.Syslog.TLS.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.Log.Syslog.TLS.MinimumVersion.Value)
+}
+
+func TestShouldRaiseErrorsOnInvalidLoggingOutputs(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Syslog: schema.LogSyslog{
+				Enabled:  true,
+				Level:    "verbose",
+				Format:   "xml",
+				Facility: "abc",
+				TLS:      &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS13}, MaximumVersion: schema.TLSVersion{Value: tls.VersionTLS12}},
+			},
+			Journald: schema.LogJournald{Enabled: true, Level: "verbose"},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 6)
+
+	assert.EqualError(t, validator.Errors()[0], "log: syslog: option 'level' must be one of 'trace', 'debug', 'info', 'warn', or 'error' but it's configured as 'verbose'")
+	assert.EqualError(t, validator.Errors()[1], "log: syslog: option 'format' must be one of 'text', 'json', 'cef', 'leef', or 'ecs' but it's configured as 'xml'")
+	assert.EqualError(t, validator.Errors()[2], "log: syslog: option 'facility' must be one of 'auth', 'authpriv', 'cron', 'daemon', 'ftp', 'kern', 'local0', 'local1', 'local2', 'local3', 'local4', 'local5', 'local6', 'local7', 'lpr', 'mail', 'news', 'syslog', 'user', or 'uucp' but it's configured as 'abc'")
+	assert.EqualError(t, validator.Errors()[3], "log: syslog: option 'address' is required when the syslog output is enabled")
+	assert.EqualError(t, validator.Errors()[4], "log: syslog: tls: option combination of 'minimum_version' and 'maximum_version' is invalid: minimum version TLS1.3 is greater than the maximum version TLS1.2")
+	assert.EqualError(t, validator.Errors()[5], "log: journald: option 'level' must be one of 'trace', 'debug', 'info', 'warn', or 'error' but it's configured as 'verbose'")
+}
+
+func TestShouldRaiseErrorsOnInvalidLoggingModules(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Modules: schema.LogModules{
+				Storage:       "debug",
+				LDAP:          "verbose",
+				OpenIDConnect: "TRACE",
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "log: modules: option 'ldap' must be one of 'trace', 'debug', 'info', 'warn', or 'error' but it's configured as 'verbose'")
+	assert.EqualError(t, validator.Errors()[1], "log: modules: option 'oidc' must be one of 'trace', 'debug', 'info', 'warn', or 'error' but it's configured as 'TRACE'")
+
+	assert.Equal(t, "debug", config.Log.Modules.Storage)
+	assert.Equal(t, "", config.Log.Modules.Session)
+}
+
+func TestShouldSetDefaultLoggingRedactionValues(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Redaction: schema.LogRedaction{
+				Usernames:   true,
+				IPAddresses: true,
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, 24, config.Log.Redaction.IPv4PrefixLength)
+	assert.Equal(t, 48, config.Log.Redaction.IPv6PrefixLength)
+}
+
+func TestShouldRaiseErrorsOnInvalidLoggingRedactionPrefixLengths(t *testing.T) {
+	config := &schema.Configuration{
+		Log: schema.Log{
+			Redaction: schema.LogRedaction{
+				IPAddresses:      true,
+				IPv4PrefixLength: 33,
+				IPv6PrefixLength: -1,
+			},
+		},
+	}
+
+	validator := schema.NewStructValidator()
+
+	ValidateLog(config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "log: redaction: option 'ipv4_prefix_length' must be between 0 and 32 but it's configured as '33'")
+	assert.EqualError(t, validator.Errors()[1], "log: redaction: option 'ipv6_prefix_length' must be between 0 and 128 but it's configured as '-1'")
 }
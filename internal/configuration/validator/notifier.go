@@ -4,17 +4,29 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateNotifier validates and update notifier configuration.
 func ValidateNotifier(config *schema.Notifier, validator *schema.StructValidator) {
-	if config.SMTP == nil && config.FileSystem == nil {
+	n := 0
+
+	for _, configured := range []bool{config.SMTP != nil, config.SES != nil, config.FileSystem != nil} {
+		if configured {
+			n++
+		}
+	}
+
+	switch {
+	case n == 0:
 		validator.Push(errors.New(errFmtNotifierNotConfigured))
 
 		return
-	} else if config.SMTP != nil && config.FileSystem != nil {
+	case n > 1:
 		validator.Push(errors.New(errFmtNotifierMultipleConfigured))
 
 		return
@@ -28,7 +40,11 @@ func ValidateNotifier(config *schema.Notifier, validator *schema.StructValidator
 		return
 	}
 
-	validateSMTPNotifier(config.SMTP, validator)
+	if config.SES != nil {
+		validateSESNotifier(config.SES, validator)
+	} else {
+		validateSMTPNotifier(config.SMTP, validator)
+	}
 
 	validateNotifierTemplates(config, validator)
 }
@@ -46,6 +62,17 @@ func validateNotifierTemplates(config *schema.Notifier, validator *schema.Struct
 		validator.Push(fmt.Errorf(errFmtNotifierTemplatePathUnknownError, config.TemplatePath, err))
 		return
 	}
+
+	unknown, err := templates.UnknownEmailTemplateOverrideFields(config.TemplatePath)
+	if err != nil {
+		validator.Push(fmt.Errorf(errFmtNotifierTemplateInvalid, config.TemplatePath, err))
+
+		return
+	}
+
+	for _, u := range unknown {
+		validator.Push(fmt.Errorf(errFmtNotifierTemplateUnknownFields, config.TemplatePath, filepath.Base(u.Path), utils.StringJoinAnd(u.Fields), utils.StringJoinAnd(u.Available)))
+	}
 }
 
 func validateSMTPNotifier(config *schema.NotifierSMTP, validator *schema.StructValidator) {
@@ -93,6 +120,24 @@ func validateSMTPNotifier(config *schema.NotifierSMTP, validator *schema.StructV
 	}
 }
 
+func validateSESNotifier(config *schema.NotifierSES, validator *schema.StructValidator) {
+	if config.Timeout == 0 {
+		config.Timeout = schema.DefaultSESNotifierConfiguration.Timeout
+	}
+
+	if config.Sender.Address == "" {
+		validator.Push(fmt.Errorf(errFmtNotifierSESNotConfigured, "sender"))
+	}
+
+	if config.Subject == "" {
+		config.Subject = schema.DefaultSESNotifierConfiguration.Subject
+	}
+
+	if config.Endpoint != nil && config.Endpoint.Scheme != schemeHTTPS {
+		validator.Push(fmt.Errorf(errFmtNotifierSESEndpoint, config.Endpoint.Scheme))
+	}
+}
+
 func validateSMTPNotifierAddress(config *schema.NotifierSMTP, validator *schema.StructValidator) {
 	if config.Address == nil {
 		if config.Host == "" && config.Port == 0 { //nolint:staticcheck
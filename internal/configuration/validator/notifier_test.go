@@ -4,6 +4,8 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/mail"
+	"net/url"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -29,6 +31,7 @@ func (suite *NotifierSuite) SetupTest() {
 		Sender:   mail.Address{Name: "Authelia", Address: "authelia@example.com"},
 	}
 	suite.config.FileSystem = nil
+	suite.config.SES = nil
 }
 
 /*
@@ -71,6 +74,53 @@ func (suite *NotifierSuite) TestShouldEnsureEitherSMTPOrFilesystemIsProvided() {
 	suite.EqualError(suite.validator.Errors()[0], errFmtNotifierMultipleConfigured)
 }
 
+func (suite *NotifierSuite) TestShouldEnsureOnlyOneOfSMTPOrSESIsProvided() {
+	suite.config.SES = &schema.NotifierSES{
+		Sender: mail.Address{Name: "Authelia", Address: "authelia@example.com"},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.EqualError(suite.validator.Errors()[0], "notifier: please ensure only one of the 'smtp', 'ses', or 'filesystem' notifier is configured")
+}
+
+/*
+SES Tests.
+*/
+func (suite *NotifierSuite) TestSESShouldSetDefaults() {
+	suite.config.SMTP = nil
+	suite.config.SES = &schema.NotifierSES{
+		Region: "us-east-1",
+		Sender: mail.Address{Name: "Authelia", Address: "authelia@example.com"},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Len(suite.validator.Errors(), 0)
+
+	suite.Equal(schema.DefaultSESNotifierConfiguration.Timeout, suite.config.SES.Timeout)
+	suite.Equal(schema.DefaultSESNotifierConfiguration.Subject, suite.config.SES.Subject)
+}
+
+func (suite *NotifierSuite) TestSESShouldRaiseErrorOnInvalidOptions() {
+	suite.config.SMTP = nil
+	suite.config.SES = &schema.NotifierSES{
+		Endpoint: &url.URL{Scheme: "http", Host: "localhost:4566"},
+	}
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.EqualError(suite.validator.Errors()[0], "notifier: ses: option 'sender' is required")
+	suite.EqualError(suite.validator.Errors()[1], "notifier: ses: option 'endpoint' must have the 'https' scheme but it's configured as 'http'")
+}
+
 /*
 SMTP Tests.
 */
@@ -257,6 +307,36 @@ func (suite *NotifierSuite) TestTemplatesEmptyDirNoExist() {
 	assert.EqualError(suite.T(), suite.validator.Errors()[0], fmt.Sprintf("notifier: option 'template_path' refers to location '%s' which does not exist", p))
 }
 
+func (suite *NotifierSuite) TestTemplatesUnknownFields() {
+	dir := suite.T().TempDir()
+
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "Event.txt"), []byte("Hi {{ .DisplayName }}, {{ .Username }}"), 0600))
+
+	suite.config.TemplatePath = dir
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	assert.EqualError(suite.T(), suite.validator.Errors()[0], fmt.Sprintf("notifier: option 'template_path' refers to location '%s' which has the template 'Event.txt' which references the values 'Username' that are not available to the template: the available values are 'Title', 'DisplayName', 'Details', and 'RemoteIP'", dir))
+}
+
+func (suite *NotifierSuite) TestTemplatesInvalid() {
+	dir := suite.T().TempDir()
+
+	suite.Require().NoError(os.WriteFile(filepath.Join(dir, "Event.html"), []byte("{{ .Title "), 0600))
+
+	suite.config.TemplatePath = dir
+
+	ValidateNotifier(&suite.config, suite.validator)
+
+	suite.Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	assert.EqualError(suite.T(), suite.validator.Errors()[0], fmt.Sprintf("notifier: option 'template_path' refers to location '%s' which has templates which couldn't be loaded: failed to parse template override at path '%s': template: Event.html:1: unclosed action", dir, filepath.Join(dir, "Event.html")))
+}
+
 /*
 File Tests.
 */
@@ -8,12 +8,35 @@ import (
 
 // ValidateNTP validates and update NTP configuration.
 func ValidateNTP(config *schema.Configuration, validator *schema.StructValidator) {
-	if config.NTP.Address == nil {
+	if config.NTP.Address == nil && len(config.NTP.Servers) == 0 {
 		config.NTP.Address = schema.DefaultNTPConfiguration.Address
 	}
 
-	if !config.NTP.Address.IsUDP() {
-		validator.Push(fmt.Errorf(errFmtNTPAddressScheme, config.NTP.Address.String(), fmt.Errorf("scheme must be one of 'udp', 'udp4', or 'udp6' but is configured as '%s'", config.NTP.Address.Scheme())))
+	servers := 0
+
+	if config.NTP.Address != nil {
+		validateNTPAddress("address", config.NTP.Address, validator)
+
+		servers++
+	}
+
+	for i, server := range config.NTP.Servers {
+		if server == nil {
+			validator.Push(fmt.Errorf(errFmtNTPServerNil, i))
+
+			continue
+		}
+
+		validateNTPAddress(fmt.Sprintf("servers[%d]", i), server, validator)
+
+		servers++
+	}
+
+	switch {
+	case config.NTP.Quorum == 0:
+		config.NTP.Quorum = servers/2 + 1
+	case config.NTP.Quorum < 0 || config.NTP.Quorum > servers:
+		validator.Push(fmt.Errorf(errFmtNTPQuorum, servers, config.NTP.Quorum))
 	}
 
 	if config.NTP.Version == 0 {
@@ -25,4 +48,20 @@ func ValidateNTP(config *schema.Configuration, validator *schema.StructValidator
 	if config.NTP.MaximumDesync <= 0 {
 		config.NTP.MaximumDesync = schema.DefaultNTPConfiguration.MaximumDesync
 	}
+
+	if config.NTP.Interval <= 0 {
+		config.NTP.Interval = schema.DefaultNTPConfiguration.Interval
+	}
+}
+
+func validateNTPAddress(option string, address *schema.AddressUDP, validator *schema.StructValidator) {
+	if !address.IsUDP() {
+		validator.Push(fmt.Errorf(errFmtNTPAddressScheme, option, address.String(), fmt.Errorf("scheme must be one of 'udp', 'udp4', or 'udp6' but is configured as '%s'", address.Scheme())))
+
+		return
+	}
+
+	if address.Port() == 0 {
+		address.SetPort(schema.DefaultNTPConfiguration.Address.Port())
+	}
 }
@@ -26,6 +26,8 @@ func TestShouldSetDefaultNTPValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultNTPConfiguration.Version, config.NTP.Version)
 	assert.Equal(t, schema.DefaultNTPConfiguration.MaximumDesync, config.NTP.MaximumDesync)
 	assert.Equal(t, schema.DefaultNTPConfiguration.DisableStartupCheck, config.NTP.DisableStartupCheck)
+	assert.Equal(t, schema.DefaultNTPConfiguration.Interval, config.NTP.Interval)
+	assert.Equal(t, 1, config.NTP.Quorum)
 }
 
 func TestShouldSetDefaultNtpVersion(t *testing.T) {
@@ -63,3 +65,89 @@ func TestShouldRaiseErrorOnInvalidNTPScheme(t *testing.T) {
 
 	assert.EqualError(t, validator.Errors()[0], "ntp: option 'address' with value 'tcp://abc:123' is invalid: scheme must be one of 'udp', 'udp4', or 'udp6' but is configured as 'tcp'")
 }
+
+func TestShouldValidateNTPServers(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.NTP
+		quorum   int
+		expected []string
+	}{
+		{
+			"ShouldSetMajorityQuorum",
+			schema.NTP{
+				Address: &schema.AddressUDP{Address: MustParseAddress("udp://time.cloudflare.com")},
+				Servers: []*schema.AddressUDP{
+					{Address: MustParseAddress("udp://time.google.com:123")},
+					{Address: MustParseAddress("udp://pool.ntp.org")},
+				},
+			},
+			2,
+			nil,
+		},
+		{
+			"ShouldAllowServersWithoutAddress",
+			schema.NTP{
+				Servers: []*schema.AddressUDP{
+					{Address: MustParseAddress("udp://time.google.com:123")},
+					{Address: MustParseAddress("udp://pool.ntp.org")},
+					{Address: MustParseAddress("udp://time.cloudflare.com")},
+					{Address: MustParseAddress("udp://time.apple.com")},
+				},
+				Quorum: 2,
+			},
+			2,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorQuorumTooLarge",
+			schema.NTP{
+				Servers: []*schema.AddressUDP{
+					{Address: MustParseAddress("udp://time.google.com:123")},
+				},
+				Quorum: 2,
+			},
+			2,
+			[]string{"ntp: option 'quorum' must be between 1 and the number of configured servers 1 but it's configured as '2'"},
+		},
+		{
+			"ShouldRaiseErrorInvalidServer",
+			schema.NTP{
+				Servers: []*schema.AddressUDP{
+					{Address: MustParseAddress("udp://time.google.com:123")},
+					{Address: MustParseAddress("tcp://pool.ntp.org:123")},
+					nil,
+				},
+			},
+			2,
+			[]string{
+				"ntp: option 'servers[1]' with value 'tcp://pool.ntp.org:123' is invalid: scheme must be one of 'udp', 'udp4', or 'udp6' but is configured as 'tcp'",
+				"ntp: option 'servers' must not contain empty values but the value at index 2 is empty",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{NTP: tc.have}
+
+			ValidateNTP(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+
+			assert.Equal(t, tc.quorum, config.NTP.Quorum)
+			assert.Equal(t, schema.DefaultNTPConfiguration.Interval, config.NTP.Interval)
+
+			for _, server := range config.NTP.Servers {
+				if server != nil && server.IsUDP() {
+					assert.Equal(t, 123, server.Port())
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,8 @@
 package validator
 
 import (
-	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -10,28 +10,74 @@ import (
 
 // ValidatePasswordPolicy validates and updates the Password Policy configuration.
 func ValidatePasswordPolicy(config *schema.PasswordPolicy, validator *schema.StructValidator) {
-	if !utils.IsBoolCountLessThanN(1, true, config.Standard.Enabled, config.ZXCVBN.Enabled) {
-		validator.Push(errors.New(errPasswordPolicyMultipleDefined))
+	validatePasswordPolicyMechanisms("password_policy", &config.Standard, &config.ZXCVBN, validator)
+
+	for i, word := range config.BannedWords {
+		if config.BannedWords[i] = strings.ToLower(strings.TrimSpace(word)); config.BannedWords[i] == "" {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyBannedWordEmpty, i+1))
+		}
+	}
+
+	validatePasswordPolicyGroupPolicies(config, validator)
+}
+
+func validatePasswordPolicyGroupPolicies(config *schema.PasswordPolicy, validator *schema.StructValidator) {
+	groups := map[string]int{}
+
+	for i := range config.GroupPolicies {
+		policy := &config.GroupPolicies[i]
+
+		if len(policy.Groups) == 0 {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyGroupPolicyNoGroups, i+1))
+		}
+
+		for _, group := range policy.Groups {
+			if j, ok := groups[group]; ok {
+				validator.Push(fmt.Errorf(errFmtPasswordPolicyGroupPolicyGroupDuplicate, i+1, group, j))
+
+				continue
+			}
+
+			groups[group] = i + 1
+		}
+
+		if !policy.Standard.Enabled && !policy.ZXCVBN.Enabled {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyGroupPolicyNoMechanism, i+1))
+
+			continue
+		}
+
+		validatePasswordPolicyMechanisms(fmt.Sprintf("password_policy: group_policies: policy #%d", i+1), &policy.Standard, &policy.ZXCVBN, validator)
+	}
+}
+
+func validatePasswordPolicyMechanisms(path string, standard *schema.PasswordPolicyStandard, zxcvbn *schema.PasswordPolicyZXCVBN, validator *schema.StructValidator) {
+	if !utils.IsBoolCountLessThanN(1, true, standard.Enabled, zxcvbn.Enabled) {
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyMultipleDefined, path))
 	}
 
-	if config.Standard.Enabled {
-		if config.Standard.MinLength == 0 {
-			config.Standard.MinLength = schema.DefaultPasswordPolicyConfiguration.Standard.MinLength
-		} else if config.Standard.MinLength < 0 {
-			validator.Push(fmt.Errorf(errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero, config.Standard.MinLength))
+	if standard.Enabled {
+		if standard.MinLength == 0 {
+			standard.MinLength = schema.DefaultPasswordPolicyConfiguration.Standard.MinLength
+		} else if standard.MinLength < 0 {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero, path, standard.MinLength))
 		}
 
-		if config.Standard.MaxLength == 0 {
-			config.Standard.MaxLength = schema.DefaultPasswordPolicyConfiguration.Standard.MaxLength
+		if standard.MaxLength == 0 {
+			standard.MaxLength = schema.DefaultPasswordPolicyConfiguration.Standard.MaxLength
 		}
 	}
 
-	if config.ZXCVBN.Enabled {
+	if zxcvbn.Enabled {
 		switch {
-		case config.ZXCVBN.MinScore == 0:
-			config.ZXCVBN.MinScore = schema.DefaultPasswordPolicyConfiguration.ZXCVBN.MinScore
-		case config.ZXCVBN.MinScore < 0, config.ZXCVBN.MinScore > 4:
-			validator.Push(fmt.Errorf(errFmtPasswordPolicyZXCVBNMinScoreInvalid, config.ZXCVBN.MinScore))
+		case zxcvbn.MinScore == 0:
+			zxcvbn.MinScore = schema.DefaultPasswordPolicyConfiguration.ZXCVBN.MinScore
+		case zxcvbn.MinScore < 0, zxcvbn.MinScore > 4:
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyZXCVBNMinScoreInvalid, path, zxcvbn.MinScore))
+		}
+
+		if zxcvbn.MinEntropy < 0 {
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyZXCVBNMinEntropyNegative, path, zxcvbn.MinEntropy))
 		}
 	}
 }
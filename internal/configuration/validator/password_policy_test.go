@@ -150,3 +150,90 @@ func TestValidatePasswordPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePasswordPolicyBannedWords(t *testing.T) {
+	config := &schema.PasswordPolicy{BannedWords: []string{" Authelia ", "example", ""}}
+
+	validator := &schema.StructValidator{}
+	ValidatePasswordPolicy(config, validator)
+
+	assert.Equal(t, []string{"authelia", "example", ""}, config.BannedWords)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "password_policy: option 'banned_words' value #3 is empty")
+}
+
+func TestValidatePasswordPolicyGroupPolicies(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		have         []schema.PasswordPolicyGroupPolicy
+		expected     []schema.PasswordPolicyGroupPolicy
+		expectedErrs []string
+	}{
+		{
+			desc: "ShouldSetDefaults",
+			have: []schema.PasswordPolicyGroupPolicy{
+				{Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true}},
+				{Groups: []string{"dev", "ops"}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinEntropy: 60}},
+			},
+			expected: []schema.PasswordPolicyGroupPolicy{
+				{Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8}},
+				{Groups: []string{"dev", "ops"}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 3, MinEntropy: 60}},
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsWhenMisconfigured",
+			have: []schema.PasswordPolicyGroupPolicy{
+				{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: -1}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 5, MinEntropy: -1}},
+				{Groups: []string{"admins"}},
+			},
+			expected: []schema.PasswordPolicyGroupPolicy{
+				{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: -1}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 5, MinEntropy: -1}},
+				{Groups: []string{"admins"}},
+			},
+			expectedErrs: []string{
+				"password_policy: group_policies: policy #1: option 'groups' must have at least one group",
+				"password_policy: group_policies: policy #1: only a single password policy mechanism can be specified",
+				"password_policy: group_policies: policy #1: standard: option 'min_length' must be greater than 0 but it's configured as -1",
+				"password_policy: group_policies: policy #1: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as 5",
+				"password_policy: group_policies: policy #1: zxcvbn: option 'min_entropy' must be 0 or greater but it's configured as -1",
+				"password_policy: group_policies: policy #2: must have one of the 'standard' or 'zxcvbn' password policy mechanisms enabled",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorOnDuplicateGroups",
+			have: []schema.PasswordPolicyGroupPolicy{
+				{Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 16}},
+				{Groups: []string{"dev", "admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 12}},
+			},
+			expected: []schema.PasswordPolicyGroupPolicy{
+				{Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 16}},
+				{Groups: []string{"dev", "admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 12}},
+			},
+			expectedErrs: []string{
+				"password_policy: group_policies: policy #2: option 'groups' has the group 'admins' which is already configured in policy #1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			config := &schema.PasswordPolicy{GroupPolicies: tc.have}
+
+			validator := &schema.StructValidator{}
+			ValidatePasswordPolicy(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			assert.Equal(t, tc.expected, config.GroupPolicies)
+
+			errs := validator.Errors()
+			require.Len(t, errs, len(tc.expectedErrs))
+
+			for i := 0; i < len(errs); i++ {
+				t.Run(fmt.Sprintf("Err%d", i+1), func(t *testing.T) {
+					assert.EqualError(t, errs[i], tc.expectedErrs[i])
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidatePush validates and updates the push notification configuration.
+func ValidatePush(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Push.Disable {
+		return
+	}
+
+	if config.Push.Provider == "" {
+		var providers []string
+
+		if config.Push.PrivacyIDEA != nil {
+			providers = append(providers, schema.PushProviderPrivacyIDEA)
+		}
+
+		if config.Push.Webhook != nil {
+			providers = append(providers, schema.PushProviderWebhook)
+		}
+
+		if config.Push.Mobile != nil {
+			providers = append(providers, schema.PushProviderMobile)
+		}
+
+		if config.Push.Ntfy != nil {
+			providers = append(providers, schema.PushProviderNtfy)
+		}
+
+		if config.Push.Gotify != nil {
+			providers = append(providers, schema.PushProviderGotify)
+		}
+
+		switch len(providers) {
+		case 0:
+			config.Push.Disable = true
+
+			return
+		case 1:
+			config.Push.Provider = providers[0]
+		default:
+			validator.Push(errors.New(errPushMultipleProviders))
+
+			return
+		}
+	}
+
+	switch config.Push.Provider {
+	case schema.PushProviderPrivacyIDEA:
+		if config.Push.PrivacyIDEA == nil {
+			config.Push.PrivacyIDEA = &schema.PushPrivacyIDEA{}
+		}
+
+		validatePushPrivacyIDEA(config.Push.PrivacyIDEA, validator)
+	case schema.PushProviderWebhook:
+		if config.Push.Webhook == nil {
+			config.Push.Webhook = &schema.PushWebhook{}
+		}
+
+		validatePushWebhook(config.Push.Webhook, validator)
+	case schema.PushProviderMobile:
+		if config.Push.Mobile == nil {
+			config.Push.Mobile = &schema.PushMobile{}
+		}
+
+		validatePushMobile(config, validator)
+	case schema.PushProviderNtfy:
+		if config.Push.Ntfy == nil {
+			config.Push.Ntfy = &schema.PushNtfy{}
+		}
+
+		validatePushNtfy(config.Push.Ntfy, validator)
+	case schema.PushProviderGotify:
+		if config.Push.Gotify == nil {
+			config.Push.Gotify = &schema.PushGotify{}
+		}
+
+		validatePushGotify(config.Push.Gotify, validator)
+	default:
+		validator.Push(fmt.Errorf(errFmtPushInvalidProvider, utils.StringJoinOr(validPushProviders), config.Push.Provider))
+
+		return
+	}
+
+	if config.Push.Timeout <= 0 {
+		config.Push.Timeout = schema.DefaultPushConfiguration.Timeout
+	}
+}
+
+func validatePushPrivacyIDEA(config *schema.PushPrivacyIDEA, validator *schema.StructValidator) {
+	validatePushProviderURL(schema.PushProviderPrivacyIDEA, config.URL, validator)
+
+	if config.Token == "" {
+		validator.Push(fmt.Errorf(errFmtPushProviderMissingOption, schema.PushProviderPrivacyIDEA, "token", schema.PushProviderPrivacyIDEA))
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validatePushProviderTLS(schema.PushProviderPrivacyIDEA, config.URL, config.TLS, schema.DefaultPushPrivacyIDEAConfiguration.TLS, validator)
+}
+
+func validatePushWebhook(config *schema.PushWebhook, validator *schema.StructValidator) {
+	validatePushProviderURL(schema.PushProviderWebhook, config.URL, validator)
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validatePushProviderTLS(schema.PushProviderWebhook, config.URL, config.TLS, schema.DefaultPushWebhookConfiguration.TLS, validator)
+}
+
+func validatePushMobile(config *schema.Configuration, validator *schema.StructValidator) {
+	mobile := config.Push.Mobile
+
+	validatePushProviderURL(schema.PushProviderMobile, mobile.URL, validator)
+
+	if !config.Server.Endpoints.Mobile.Enabled {
+		validator.Push(errors.New(errPushMobileEndpointsDisabled))
+	}
+
+	if mobile.TLS == nil {
+		mobile.TLS = &schema.TLS{}
+	}
+
+	validatePushProviderTLS(schema.PushProviderMobile, mobile.URL, mobile.TLS, schema.DefaultPushMobileConfiguration.TLS, validator)
+}
+
+func validatePushNtfy(config *schema.PushNtfy, validator *schema.StructValidator) {
+	if config.URL == nil {
+		config.URL = schema.DefaultPushNtfyConfiguration.URL
+	}
+
+	validatePushProviderURL(schema.PushProviderNtfy, config.URL, validator)
+
+	if config.Topic == "" {
+		validator.Push(fmt.Errorf(errFmtPushProviderMissingOption, schema.PushProviderNtfy, "topic", schema.PushProviderNtfy))
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validatePushProviderTLS(schema.PushProviderNtfy, config.URL, config.TLS, schema.DefaultPushNtfyConfiguration.TLS, validator)
+}
+
+func validatePushGotify(config *schema.PushGotify, validator *schema.StructValidator) {
+	validatePushProviderURL(schema.PushProviderGotify, config.URL, validator)
+
+	if len(config.Tokens) == 0 {
+		validator.Push(fmt.Errorf(errFmtPushProviderMissingOption, schema.PushProviderGotify, "tokens", schema.PushProviderGotify))
+	}
+
+	usernames := make([]string, 0, len(config.Tokens))
+
+	for username := range config.Tokens {
+		usernames = append(usernames, username)
+	}
+
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		if config.Tokens[username] == "" {
+			validator.Push(fmt.Errorf(errFmtPushGotifyTokenEmpty, username))
+		}
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validatePushProviderTLS(schema.PushProviderGotify, config.URL, config.TLS, schema.DefaultPushGotifyConfiguration.TLS, validator)
+}
+
+func validatePushProviderURL(provider string, address *url.URL, validator *schema.StructValidator) {
+	if address == nil {
+		validator.Push(fmt.Errorf(errFmtPushProviderMissingOption, provider, "url", provider))
+	} else if address.Scheme != schemeHTTP && address.Scheme != schemeHTTPS {
+		validator.Push(fmt.Errorf(errFmtPushProviderURLScheme, provider, address.Scheme))
+	}
+}
+
+func validatePushProviderTLS(provider string, address *url.URL, config, defaults *schema.TLS, validator *schema.StructValidator) {
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: defaults.MinimumVersion,
+		MaximumVersion: defaults.MaximumVersion,
+	}
+
+	if address != nil {
+		configDefaultTLS.ServerName = address.Hostname()
+	}
+
+	if err := ValidateTLSConfig(config, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtPushProviderTLSConfigInvalid, provider, err))
+	}
+}
@@ -0,0 +1,192 @@
+package validator
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidatePush(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.Push
+		expected func(t *testing.T, config *schema.Push)
+		errs     []string
+	}{
+		{
+			"ShouldDisableWithoutProviders",
+			schema.Push{},
+			func(t *testing.T, config *schema.Push) {
+				assert.True(t, config.Disable)
+				assert.Equal(t, "", config.Provider)
+			},
+			nil,
+		},
+		{
+			"ShouldNotValidateDisabled",
+			schema.Push{Disable: true, Provider: "abc"},
+			func(t *testing.T, config *schema.Push) {
+				assert.True(t, config.Disable)
+			},
+			nil,
+		},
+		{
+			"ShouldSetPrivacyIDEADefaults",
+			schema.Push{PrivacyIDEA: &schema.PushPrivacyIDEA{URL: MustParseURL("https://pi.example.com"), Realm: "example", Token: "abc"}},
+			func(t *testing.T, config *schema.Push) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, schema.PushProviderPrivacyIDEA, config.Provider)
+				assert.Equal(t, time.Minute, config.Timeout)
+				require.NotNil(t, config.PrivacyIDEA.TLS)
+				assert.Equal(t, "pi.example.com", config.PrivacyIDEA.TLS.ServerName)
+				assert.Equal(t, uint16(tls.VersionTLS12), config.PrivacyIDEA.TLS.MinimumVersion.Value)
+			},
+			nil,
+		},
+		{
+			"ShouldSetWebhookDefaults",
+			schema.Push{Provider: schema.PushProviderWebhook, Timeout: time.Second * 30, Webhook: &schema.PushWebhook{URL: MustParseURL("http://push:8080/authelia")}},
+			func(t *testing.T, config *schema.Push) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, time.Second*30, config.Timeout)
+				require.NotNil(t, config.Webhook.TLS)
+				assert.Equal(t, "push", config.Webhook.TLS.ServerName)
+			},
+			nil,
+		},
+		{
+			"ShouldErrorMultipleProviders",
+			schema.Push{PrivacyIDEA: &schema.PushPrivacyIDEA{}, Webhook: &schema.PushWebhook{}},
+			nil,
+			[]string{
+				"push: option 'provider' must be configured when more than one provider is configured but it's absent",
+			},
+		},
+		{
+			"ShouldErrorInvalidProvider",
+			schema.Push{Provider: "duo"},
+			nil,
+			[]string{
+				"push: option 'provider' must be one of 'privacyidea', 'webhook', 'mobile', 'ntfy', or 'gotify' but it's configured as 'duo'",
+			},
+		},
+		{
+			"ShouldErrorPrivacyIDEAMissingOptions",
+			schema.Push{Provider: schema.PushProviderPrivacyIDEA},
+			nil,
+			[]string{
+				"push: privacyidea: option 'url' is required when the provider is 'privacyidea' but it's absent",
+				"push: privacyidea: option 'token' is required when the provider is 'privacyidea' but it's absent",
+			},
+		},
+		{
+			"ShouldErrorWebhookBadScheme",
+			schema.Push{Webhook: &schema.PushWebhook{URL: MustParseURL("ftp://push.example.com")}},
+			nil,
+			[]string{
+				"push: webhook: option 'url' must have a scheme of 'http' or 'https' but it's configured as 'ftp'",
+			},
+		},
+		{
+			"ShouldSetNtfyDefaults",
+			schema.Push{Ntfy: &schema.PushNtfy{Topic: "authelia-{username}"}},
+			func(t *testing.T, config *schema.Push) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, schema.PushProviderNtfy, config.Provider)
+				assert.Equal(t, "https://ntfy.sh", config.Ntfy.URL.String())
+				assert.Equal(t, time.Minute, config.Timeout)
+				require.NotNil(t, config.Ntfy.TLS)
+				assert.Equal(t, "ntfy.sh", config.Ntfy.TLS.ServerName)
+			},
+			nil,
+		},
+		{
+			"ShouldErrorNtfyMissingTopic",
+			schema.Push{Provider: schema.PushProviderNtfy},
+			nil,
+			[]string{
+				"push: ntfy: option 'topic' is required when the provider is 'ntfy' but it's absent",
+			},
+		},
+		{
+			"ShouldSetGotifyDefaults",
+			schema.Push{Gotify: &schema.PushGotify{URL: MustParseURL("https://gotify.example.com"), Tokens: map[string]string{"john": "abc"}}},
+			func(t *testing.T, config *schema.Push) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, schema.PushProviderGotify, config.Provider)
+				require.NotNil(t, config.Gotify.TLS)
+				assert.Equal(t, "gotify.example.com", config.Gotify.TLS.ServerName)
+			},
+			nil,
+		},
+		{
+			"ShouldErrorGotifyMissingOptions",
+			schema.Push{Provider: schema.PushProviderGotify},
+			nil,
+			[]string{
+				"push: gotify: option 'url' is required when the provider is 'gotify' but it's absent",
+				"push: gotify: option 'tokens' is required when the provider is 'gotify' but it's absent",
+			},
+		},
+		{
+			"ShouldErrorGotifyEmptyTokens",
+			schema.Push{Gotify: &schema.PushGotify{URL: MustParseURL("https://gotify.example.com"), Tokens: map[string]string{"john": "", "harry": "", "bob": "abc"}}},
+			nil,
+			[]string{
+				"push: gotify: tokens: option 'harry' must not be empty",
+				"push: gotify: tokens: option 'john' must not be empty",
+			},
+		},
+		{
+			"ShouldErrorMobileEndpointsDisabled",
+			schema.Push{Mobile: &schema.PushMobile{URL: MustParseURL("https://relay.example.com")}},
+			nil,
+			[]string{
+				"push: mobile: the provider requires the mobile authentication endpoints to be enabled with the option 'server.endpoints.mobile.enabled' but they're disabled",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Push: tc.have}
+
+			ValidatePush(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+
+			if tc.expected != nil {
+				tc.expected(t, &config.Push)
+			}
+		})
+	}
+}
+
+func TestValidatePushMobile(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Push:   schema.Push{Mobile: &schema.PushMobile{URL: MustParseURL("https://relay.example.com/authelia")}},
+		Server: schema.Server{Endpoints: schema.ServerEndpoints{Mobile: schema.ServerEndpointsMobile{Enabled: true}}},
+	}
+
+	ValidatePush(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.False(t, config.Push.Disable)
+	assert.Equal(t, schema.PushProviderMobile, config.Push.Provider)
+	assert.Equal(t, time.Minute, config.Push.Timeout)
+	require.NotNil(t, config.Push.Mobile.TLS)
+	assert.Equal(t, "relay.example.com", config.Push.Mobile.TLS.ServerName)
+}
@@ -0,0 +1,187 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateReferences validates the references between the sections of the configuration such as the access control
+// rules which reference domains or groups which are configured elsewhere. It must be run after the other validations as
+// it relies on the default values they set.
+func ValidateReferences(config *schema.Configuration, validator *schema.StructValidator) {
+	validateReferencesAccessControlDomains(config, validator)
+	validateReferencesSubjects(config, validator)
+	validateReferencesBackchannelAuthentication(config, validator)
+	validateReferencesPush(config, validator)
+}
+
+// validateReferencesBackchannelAuthentication ensures the out-of-band authenticator required by the OpenID Connect 1.0
+// Client Initiated Backchannel Authentication flow is configured when the flow is enabled.
+func validateReferencesBackchannelAuthentication(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.IdentityProviders.OIDC == nil || !config.IdentityProviders.OIDC.BackchannelAuthentication.Enabled {
+		return
+	}
+
+	if config.DuoAPI.Disable && config.Push.Disable {
+		validator.Push(errors.New(errReferencesOIDCBackchannelAuthenticationDuoDisabled))
+	}
+}
+
+// validateReferencesPush ensures only one of the Duo API and the push notification providers is configured as both
+// provide the mobile_push second factor method.
+func validateReferencesPush(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Push.Disable || config.Push.Provider == "" || config.DuoAPI.Disable {
+		return
+	}
+
+	validator.Push(errors.New(errReferencesPushDuoEnabled))
+}
+
+// validateReferencesAccessControlDomains ensures the domains of the access control rules which require authentication
+// are within the scope of the cookie domain of one of the session domains, as users can't be authenticated otherwise.
+func validateReferencesAccessControlDomains(config *schema.Configuration, validator *schema.StructValidator) {
+	if len(config.Session.Cookies) == 0 {
+		return
+	}
+
+	domains := make([]string, len(config.Session.Cookies))
+
+	for i, cookie := range config.Session.Cookies {
+		domains[i] = cookie.Domain
+	}
+
+	for i, rule := range config.AccessControl.Rules {
+		if rule.Policy == policyBypass || rule.Policy == policyDeny {
+			continue
+		}
+
+		for _, domain := range rule.Domains {
+			if isDomainInCookieScope(strings.ToLower(domain), domains) {
+				continue
+			}
+
+			validator.PushWarning(fmt.Errorf(errFmtReferencesAccessControlRuleDomainNotInCookieScope, ruleDescriptor(i+1, rule), domain, utils.StringJoinAnd(domains)))
+		}
+	}
+}
+
+// validateReferencesSubjects ensures the users and groups referenced by the subjects of the access control rules and
+// the OpenID Connect 1.0 authorization policies exist in the file authentication backend database.
+func validateReferencesSubjects(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.AuthenticationBackend.File == nil || config.AuthenticationBackend.File.Path == "" {
+		return
+	}
+
+	users, groups, ok := loadReferencesUserDatabase(config.AuthenticationBackend.File.Path)
+	if !ok {
+		return
+	}
+
+	insensitive := config.AuthenticationBackend.File.Search.CaseInsensitive
+
+	if insensitive {
+		for i, user := range users {
+			users[i] = strings.ToLower(user)
+		}
+	}
+
+	for i, rule := range config.AccessControl.Rules {
+		for _, subject := range unknownReferencesSubjects(rule.Subjects, users, groups, insensitive) {
+			validator.PushWarning(fmt.Errorf(errFmtReferencesAccessControlRuleSubjectUnknown, ruleDescriptor(i+1, rule), subject))
+		}
+	}
+
+	if config.IdentityProviders.OIDC == nil {
+		return
+	}
+
+	names := make([]string, 0, len(config.IdentityProviders.OIDC.AuthorizationPolicies))
+
+	for name := range config.IdentityProviders.OIDC.AuthorizationPolicies {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for i, rule := range config.IdentityProviders.OIDC.AuthorizationPolicies[name].Rules {
+			for _, subject := range unknownReferencesSubjects(rule.Subjects, users, groups, insensitive) {
+				validator.PushWarning(fmt.Errorf(errFmtReferencesOIDCPolicyRuleSubjectUnknown, name, i+1, subject))
+			}
+		}
+	}
+}
+
+func isDomainInCookieScope(domain string, domains []string) bool {
+	for _, d := range domains {
+		if utils.HasDomainSuffix(domain, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func unknownReferencesSubjects(subjects [][]string, users, groups []string, insensitive bool) (unknown []string) {
+	for _, rule := range subjects {
+		for _, subject := range rule {
+			switch {
+			case strings.HasPrefix(subject, prefixSubjectUser):
+				username := strings.TrimPrefix(subject, prefixSubjectUser)
+
+				if insensitive {
+					username = strings.ToLower(username)
+				}
+
+				if !utils.IsStringInSlice(username, users) {
+					unknown = append(unknown, subject)
+				}
+			case strings.HasPrefix(subject, prefixSubjectGroup):
+				if !utils.IsStringInSlice(strings.TrimPrefix(subject, prefixSubjectGroup), groups) {
+					unknown = append(unknown, subject)
+				}
+			}
+		}
+	}
+
+	return unknown
+}
+
+// loadReferencesUserDatabase loads the usernames and groups from the file authentication backend database. The result
+// is not ok if the database doesn't exist yet or can't be parsed, as these problems are reported when it's loaded.
+func loadReferencesUserDatabase(path string) (users, groups []string, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	database := struct {
+		Users map[string]struct {
+			Groups []string `yaml:"groups"`
+		} `yaml:"users"`
+	}{}
+
+	if err = yaml.Unmarshal(data, &database); err != nil {
+		return nil, nil, false
+	}
+
+	for username, user := range database.Users {
+		users = append(users, username)
+
+		for _, group := range user.Groups {
+			if !utils.IsStringInSlice(group, groups) {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	return users, groups, true
+}
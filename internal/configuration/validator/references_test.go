@@ -0,0 +1,285 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateReferencesAccessControlDomains(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cookies  []schema.SessionCookie
+		rules    []schema.AccessControlRule
+		expected []string
+	}{
+		{
+			"ShouldNotWarnDomainsInScope",
+			[]schema.SessionCookie{{Domain: "example.com"}, {Domain: "example2.com"}},
+			[]schema.AccessControlRule{
+				{Domains: []string{"example.com", "*.example.com", "{user}.example.com"}, Policy: policyOneFactor},
+				{Domains: []string{"APP.example2.com"}, Policy: policyTwoFactor},
+			},
+			nil,
+		},
+		{
+			"ShouldNotWarnBypassOrDeny",
+			[]schema.SessionCookie{{Domain: "example.com"}},
+			[]schema.AccessControlRule{
+				{Domains: []string{"public.example.org"}, Policy: policyBypass},
+				{Domains: []string{"private.example.org"}, Policy: policyDeny},
+			},
+			nil,
+		},
+		{
+			"ShouldNotWarnWithoutCookies",
+			nil,
+			[]schema.AccessControlRule{
+				{Domains: []string{"app.example.org"}, Policy: policyOneFactor},
+			},
+			nil,
+		},
+		{
+			"ShouldWarnDomainsNotInScope",
+			[]schema.SessionCookie{{Domain: "example.com"}, {Domain: "example2.com"}},
+			[]schema.AccessControlRule{
+				{Domains: []string{"app.example.com", "app.example.org"}, Policy: policyOneFactor},
+				{Domains: []string{"notexample.com"}, Policy: policyTwoFactor},
+			},
+			[]string{
+				"access_control: rule #1 (domain 'app.example.com,app.example.org'): option 'domain' has the value 'app.example.org' which is not within the cookie scope of any of the session domains 'example.com' and 'example2.com' so requests to it can't be authenticated",
+				"access_control: rule #2 (domain 'notexample.com'): option 'domain' has the value 'notexample.com' which is not within the cookie scope of any of the session domains 'example.com' and 'example2.com' so requests to it can't be authenticated",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &schema.Configuration{
+				Session:       schema.Session{Cookies: tc.cookies},
+				AccessControl: schema.AccessControl{Rules: tc.rules},
+			}
+
+			validator := schema.NewStructValidator()
+
+			ValidateReferences(config, validator)
+
+			assert.Len(t, validator.Errors(), 0)
+
+			warnings := make([]string, len(validator.Warnings()))
+
+			for i, warning := range validator.Warnings() {
+				warnings[i] = warning.Error()
+			}
+
+			assert.ElementsMatch(t, tc.expected, warnings)
+		})
+	}
+}
+
+func TestValidateReferencesSubjects(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "users.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("users:\n  John:\n    groups: ['admins', 'dev']\n  harry:\n    groups: ['dev']\n"), 0600))
+
+	testCases := []struct {
+		name        string
+		path        string
+		insensitive bool
+		expected    []string
+	}{
+		{
+			"ShouldWarnUnknownSubjects",
+			path,
+			false,
+			[]string{
+				"access_control: rule #1 (domain 'example.com'): option 'subject' references 'group:unknown' which doesn't exist in the file authentication backend database",
+				"access_control: rule #1 (domain 'example.com'): option 'subject' references 'user:john' which doesn't exist in the file authentication backend database",
+				"identity_providers: oidc: authorization_policies: policy 'policy': rules: rule #2: option 'subject' references 'user:bob' which doesn't exist in the file authentication backend database",
+			},
+		},
+		{
+			"ShouldWarnUnknownSubjectsCaseInsensitive",
+			path,
+			true,
+			[]string{
+				"access_control: rule #1 (domain 'example.com'): option 'subject' references 'group:unknown' which doesn't exist in the file authentication backend database",
+				"identity_providers: oidc: authorization_policies: policy 'policy': rules: rule #2: option 'subject' references 'user:bob' which doesn't exist in the file authentication backend database",
+			},
+		},
+		{
+			"ShouldNotWarnDatabaseNotExist",
+			filepath.Join(dir, "notexist.yml"),
+			false,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &schema.Configuration{
+				AuthenticationBackend: schema.AuthenticationBackend{
+					File: &schema.AuthenticationBackendFile{
+						Path:   tc.path,
+						Search: schema.AuthenticationBackendFileSearch{CaseInsensitive: tc.insensitive},
+					},
+				},
+				AccessControl: schema.AccessControl{
+					Rules: []schema.AccessControlRule{
+						{
+							Domains:  []string{"example.com"},
+							Policy:   policyOneFactor,
+							Subjects: [][]string{{"user:john", "group:admins"}, {"group:unknown"}, {"oauth2:client:app"}},
+						},
+					},
+				},
+				IdentityProviders: schema.IdentityProviders{
+					OIDC: &schema.IdentityProvidersOpenIDConnect{
+						AuthorizationPolicies: map[string]schema.IdentityProvidersOpenIDConnectPolicy{
+							"policy": {
+								Rules: []schema.IdentityProvidersOpenIDConnectPolicyRule{
+									{Policy: policyOneFactor, Subjects: [][]string{{"user:harry"}, {"group:dev"}}},
+									{Policy: policyTwoFactor, Subjects: [][]string{{"user:bob"}}},
+								},
+							},
+						},
+					},
+				},
+			}
+
+			validator := schema.NewStructValidator()
+
+			ValidateReferences(config, validator)
+
+			assert.Len(t, validator.Errors(), 0)
+
+			warnings := make([]string, len(validator.Warnings()))
+
+			for i, warning := range validator.Warnings() {
+				warnings[i] = warning.Error()
+			}
+
+			assert.ElementsMatch(t, tc.expected, warnings)
+		})
+	}
+}
+
+func TestValidateReferencesBackchannelAuthentication(t *testing.T) {
+	testCases := []struct {
+		name     string
+		oidc     *schema.IdentityProvidersOpenIDConnect
+		disable  bool
+		expected []string
+	}{
+		{
+			"ShouldNotErrorWithoutOIDC",
+			nil,
+			true,
+			nil,
+		},
+		{
+			"ShouldNotErrorWhenDisabled",
+			&schema.IdentityProvidersOpenIDConnect{},
+			true,
+			nil,
+		},
+		{
+			"ShouldNotErrorWhenDuoConfigured",
+			&schema.IdentityProvidersOpenIDConnect{BackchannelAuthentication: schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{Enabled: true}},
+			false,
+			nil,
+		},
+		{
+			"ShouldErrorWhenDuoDisabled",
+			&schema.IdentityProvidersOpenIDConnect{BackchannelAuthentication: schema.IdentityProvidersOpenIDConnectBackchannelAuthentication{Enabled: true}},
+			true,
+			[]string{
+				"identity_providers: oidc: backchannel_authentication: option 'enabled' requires the duo_api or push to be configured to perform the out-of-band authentication but they're disabled",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &schema.Configuration{
+				DuoAPI:            schema.DuoAPI{Disable: tc.disable},
+				Push:              schema.Push{Disable: true},
+				IdentityProviders: schema.IdentityProviders{OIDC: tc.oidc},
+			}
+
+			validator := schema.NewStructValidator()
+
+			ValidateReferences(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := make([]string, len(validator.Errors()))
+
+			for i, err := range validator.Errors() {
+				errs[i] = err.Error()
+			}
+
+			assert.ElementsMatch(t, tc.expected, errs)
+		})
+	}
+}
+
+func TestValidateReferencesPush(t *testing.T) {
+	testCases := []struct {
+		name     string
+		duo      schema.DuoAPI
+		push     schema.Push
+		expected []string
+	}{
+		{
+			"ShouldNotErrorWithDuo",
+			schema.DuoAPI{Hostname: "api-123456789.example.com"},
+			schema.Push{Disable: true},
+			nil,
+		},
+		{
+			"ShouldNotErrorWithPush",
+			schema.DuoAPI{Disable: true},
+			schema.Push{Provider: schema.PushProviderWebhook},
+			nil,
+		},
+		{
+			"ShouldErrorWithDuoAndPush",
+			schema.DuoAPI{Hostname: "api-123456789.example.com"},
+			schema.Push{Provider: schema.PushProviderWebhook},
+			[]string{
+				"push: option 'provider' must not be configured when the duo_api is enabled as they both provide the 'mobile_push' second factor method",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &schema.Configuration{
+				DuoAPI: tc.duo,
+				Push:   tc.push,
+			}
+
+			validator := schema.NewStructValidator()
+
+			ValidateReferences(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := make([]string, len(validator.Errors()))
+
+			for i, err := range validator.Errors() {
+				errs[i] = err.Error()
+			}
+
+			assert.ElementsMatch(t, tc.expected, errs)
+		})
+	}
+}
@@ -19,4 +19,16 @@ func ValidateRegulation(config *schema.Configuration, validator *schema.StructVa
 	if config.Regulation.FindTime > config.Regulation.BanTime {
 		validator.Push(errors.New(errFmtRegulationFindTimeGreaterThanBanTime))
 	}
+
+	if config.Regulation.Anomalies.Window <= 0 {
+		config.Regulation.Anomalies.Window = schema.DefaultRegulationConfiguration.Anomalies.Window
+	}
+
+	if config.Regulation.Anomalies.UsernamesPerIP <= 0 {
+		config.Regulation.Anomalies.UsernamesPerIP = schema.DefaultRegulationConfiguration.Anomalies.UsernamesPerIP
+	}
+
+	if config.Regulation.Anomalies.IPsPerUsername <= 0 {
+		config.Regulation.Anomalies.IPsPerUsername = schema.DefaultRegulationConfiguration.Anomalies.IPsPerUsername
+	}
 }
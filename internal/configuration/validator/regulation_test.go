@@ -52,3 +52,20 @@ func TestShouldRaiseErrorWhenFindTimeLessThanBanTime(t *testing.T) {
 	assert.Len(t, validator.Errors(), 1)
 	assert.EqualError(t, validator.Errors()[0], "regulation: option 'find_time' must be less than or equal to option 'ban_time'")
 }
+
+func TestShouldSetDefaultRegulationAnomaliesWhenUnset(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultRegulationConfig()
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultRegulationConfiguration.Anomalies, config.Regulation.Anomalies)
+
+	config.Regulation.Anomalies = schema.RegulationAnomalies{Window: time.Hour, UsernamesPerIP: 20, IPsPerUsername: -1}
+
+	ValidateRegulation(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.RegulationAnomalies{Window: time.Hour, UsernamesPerIP: 20, IPsPerUsername: 10}, config.Regulation.Anomalies)
+}
@@ -4,41 +4,52 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/authelia/authelia/v4/internal/branding"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateServerTLS checks a server TLS configuration is correct.
 func ValidateServerTLS(config *schema.Configuration, validator *schema.StructValidator) {
-	if config.Server.TLS.Key != "" && config.Server.TLS.Certificate == "" {
-		validator.Push(errors.New(errFmtServerTLSCert))
-	} else if config.Server.TLS.Key == "" && config.Server.TLS.Certificate != "" {
-		validator.Push(errors.New(errFmtServerTLSKey))
+	validateServerTLS("tls", &config.Server.TLS, validator)
+}
+
+// validateServerTLS checks a TLS configuration of one of the servers is correct, the name is the prefix of the errors
+// after the 'server' prefix.
+func validateServerTLS(name string, config *schema.ServerTLS, validator *schema.StructValidator) {
+	if config.Key != "" && config.Certificate == "" {
+		validator.Push(fmt.Errorf(errFmtServerTLSCert, name))
+	} else if config.Key == "" && config.Certificate != "" {
+		validator.Push(fmt.Errorf(errFmtServerTLSKey, name))
 	}
 
-	if config.Server.TLS.Key != "" {
-		validateServerTLSFileExists("key", config.Server.TLS.Key, validator)
+	if config.Key != "" {
+		validateServerTLSFileExists(name, "key", config.Key, validator)
 	}
 
-	if config.Server.TLS.Certificate != "" {
-		validateServerTLSFileExists("certificate", config.Server.TLS.Certificate, validator)
+	if config.Certificate != "" {
+		validateServerTLSFileExists(name, "certificate", config.Certificate, validator)
 	}
 
-	if config.Server.TLS.Key == "" && config.Server.TLS.Certificate == "" &&
-		len(config.Server.TLS.ClientCertificates) > 0 {
-		validator.Push(errors.New(errFmtServerTLSClientAuthNoAuth))
+	if config.Key == "" && config.Certificate == "" &&
+		len(config.ClientCertificates) > 0 {
+		validator.Push(fmt.Errorf(errFmtServerTLSClientAuthNoAuth, name))
 	}
 
-	for _, clientCertPath := range config.Server.TLS.ClientCertificates {
-		validateServerTLSFileExists("client_certificates", clientCertPath, validator)
+	for _, clientCertPath := range config.ClientCertificates {
+		validateServerTLSFileExists(name, "client_certificates", clientCertPath, validator)
 	}
 }
 
 // validateServerTLSFileExists checks whether a file exist.
-func validateServerTLSFileExists(name, path string, validator *schema.StructValidator) {
+func validateServerTLSFileExists(prefix, name, path string, validator *schema.StructValidator) {
 	var (
 		info os.FileInfo
 		err  error
@@ -46,11 +57,11 @@ func validateServerTLSFileExists(name, path string, validator *schema.StructVali
 
 	switch info, err = os.Stat(path); {
 	case os.IsNotExist(err):
-		validator.Push(fmt.Errorf("server: tls: option '%s' with path '%s' refers to a file that doesn't exist", name, path))
+		validator.Push(fmt.Errorf("server: %s: option '%s' with path '%s' refers to a file that doesn't exist", prefix, name, path))
 	case err != nil:
-		validator.Push(fmt.Errorf("server: tls: option '%s' with path '%s' could not be verified due to a file system error: %w", name, path, err))
+		validator.Push(fmt.Errorf("server: %s: option '%s' with path '%s' could not be verified due to a file system error: %w", prefix, name, path, err))
 	case info.IsDir():
-		validator.Push(fmt.Errorf("server: tls: option '%s' with path '%s' refers to a directory but it should refer to a file", name, path))
+		validator.Push(fmt.Errorf("server: %s: option '%s' with path '%s' refers to a directory but it should refer to a file", prefix, name, path))
 	}
 }
 
@@ -79,7 +90,353 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 		config.Server.Timeouts.Idle = schema.DefaultServerConfiguration.Timeouts.Idle
 	}
 
+	ValidateServerAssets(config, validator)
+	ValidateServerLimits(config, validator)
+	ValidateServerKeepAlive(config, validator)
+	ValidateServerH2C(config, validator)
+	ValidateServerScannerFilter(config, validator)
+	ValidateServerHeaders(config, validator)
 	ValidateServerEndpoints(config, validator)
+	ValidateServerAdmin(config, validator)
+	ValidateServerLDAP(config, validator)
+	ValidateServerHeadless(config, validator)
+}
+
+// ValidateServerLDAP configures the default LDAP server values and checks the configured values are valid.
+func ValidateServerLDAP(config *schema.Configuration, validator *schema.StructValidator) {
+	server := &config.Server.LDAP
+
+	if !server.Enabled {
+		return
+	}
+
+	if server.Address == nil {
+		server.Address = schema.DefaultServerConfiguration.LDAP.Address
+	}
+
+	if err := server.Address.ValidateHTTP(); err != nil {
+		validator.Push(fmt.Errorf(errFmtServerLDAPAddress, server.Address.String(), err))
+	}
+
+	if server.Address.Port() == 0 {
+		server.Address.SetPort(schema.DefaultServerConfiguration.LDAP.Address.Port())
+	}
+
+	addresses := []*schema.AddressTCP{config.Server.Address}
+
+	if config.Server.Admin.Enabled {
+		addresses = append(addresses, config.Server.Admin.Address)
+	}
+
+	for _, address := range addresses {
+		if address != nil && server.Address.Port() == address.Port() && server.Address.Hostname() == address.Hostname() {
+			validator.Push(fmt.Errorf(errFmtServerLDAPAddressInUse, address.String()))
+		}
+	}
+
+	if config.AuthenticationBackend.SQL == nil {
+		validator.Push(errors.New(errFmtServerLDAPAuthenticationBackend))
+	}
+
+	if server.BaseDN == "" {
+		validator.Push(errors.New(errFmtServerLDAPBaseDNRequired))
+	} else if dn, err := ldap.ParseDN(server.BaseDN); err != nil || len(dn.RDNs) == 0 {
+		validator.Push(fmt.Errorf(errFmtServerLDAPBaseDN, server.BaseDN))
+	}
+
+	if server.SizeLimit <= 0 {
+		server.SizeLimit = schema.DefaultServerConfiguration.LDAP.SizeLimit
+	}
+
+	if server.IdleTimeout <= 0 {
+		server.IdleTimeout = schema.DefaultServerConfiguration.LDAP.IdleTimeout
+	}
+
+	validateServerTLS("ldap: tls", &server.TLS, validator)
+}
+
+// ValidateServerHeadless checks the headless mode configuration is valid.
+func ValidateServerHeadless(config *schema.Configuration, validator *schema.StructValidator) {
+	headless := &config.Server.Headless
+
+	if !headless.Enabled {
+		return
+	}
+
+	for _, origin := range headless.AllowedOrigins {
+		if origin == nil {
+			continue
+		}
+
+		if origin.String() == "*" {
+			validator.Push(errors.New(errFmtServerHeadlessOriginWildcard))
+
+			continue
+		}
+
+		if origin.Scheme != schemeHTTP && origin.Scheme != schemeHTTPS {
+			validator.Push(fmt.Errorf(errFmtServerHeadlessOriginScheme, origin.String(), origin.Scheme))
+		}
+
+		if origin.Path != "" {
+			validator.Push(fmt.Errorf(errFmtServerHeadlessOriginInvalid, origin.String(), "path"))
+		}
+
+		if origin.RawQuery != "" {
+			validator.Push(fmt.Errorf(errFmtServerHeadlessOriginInvalid, origin.String(), "query string"))
+		}
+	}
+}
+
+// ValidateServerAdmin configures the default admin server values and checks the configured values are valid.
+func ValidateServerAdmin(config *schema.Configuration, validator *schema.StructValidator) {
+	admin := &config.Server.Admin
+
+	if !admin.Enabled {
+		return
+	}
+
+	if admin.Address == nil {
+		admin.Address = schema.DefaultServerConfiguration.Admin.Address
+	}
+
+	if err := admin.Address.ValidateHTTP(); err != nil {
+		validator.Push(fmt.Errorf(errFmtServerAdminAddress, admin.Address.String(), err))
+	}
+
+	if admin.Address.Port() == 0 {
+		admin.Address.SetPort(schema.DefaultServerConfiguration.Admin.Address.Port())
+	}
+
+	if admin.Address.RouterPath() == "" {
+		admin.Address.SetPath(schema.DefaultServerConfiguration.Admin.Address.Path())
+	}
+
+	if config.Server.Address != nil && admin.Address.Port() == config.Server.Address.Port() && admin.Address.Hostname() == config.Server.Address.Hostname() {
+		validator.Push(fmt.Errorf(errFmtServerAdminAddressInUse, config.Server.Address.String()))
+	}
+
+	switch n := len(admin.Token); {
+	case n == 0:
+		validator.Push(errors.New(errFmtServerAdminTokenRequired))
+	case n < serverAdminTokenMinLength:
+		validator.Push(fmt.Errorf(errFmtServerAdminTokenLength, serverAdminTokenMinLength, n))
+	}
+
+	if admin.EnableEvents && !config.Audit.Enabled {
+		validator.Push(errors.New(errFmtServerAdminEventsAuditDisabled))
+	}
+
+	if admin.Buffers.Read <= 0 {
+		admin.Buffers.Read = schema.DefaultServerConfiguration.Admin.Buffers.Read
+	}
+
+	if admin.Buffers.Write <= 0 {
+		admin.Buffers.Write = schema.DefaultServerConfiguration.Admin.Buffers.Write
+	}
+
+	if admin.Timeouts.Read <= 0 {
+		admin.Timeouts.Read = schema.DefaultServerConfiguration.Admin.Timeouts.Read
+	}
+
+	if admin.Timeouts.Write <= 0 {
+		admin.Timeouts.Write = schema.DefaultServerConfiguration.Admin.Timeouts.Write
+	}
+
+	if admin.Timeouts.Idle <= 0 {
+		admin.Timeouts.Idle = schema.DefaultServerConfiguration.Admin.Timeouts.Idle
+	}
+}
+
+// ValidateServerAssets checks the asset overlay directory and the branding manifest within it are valid.
+func ValidateServerAssets(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.AssetPath == "" {
+		if config.Server.AssetWatch {
+			validator.Push(errors.New(errFmtServerAssetWatchWithoutPath))
+		}
+
+		return
+	}
+
+	if _, err := branding.NewProvider(config.Server.AssetPath); err != nil {
+		validator.Push(fmt.Errorf(errFmtServerAssetPathBranding, err))
+	}
+}
+
+// ValidateServerLimits configures the default resource limits and checks the configured limits are valid.
+func ValidateServerLimits(config *schema.Configuration, validator *schema.StructValidator) {
+	limits := &config.Server.Limits
+
+	if limits.MaxRequestBodySize <= 0 {
+		limits.MaxRequestBodySize = schema.DefaultServerConfiguration.Limits.MaxRequestBodySize
+	}
+
+	if limits.MaxConnections <= 0 {
+		limits.MaxConnections = schema.DefaultServerConfiguration.Limits.MaxConnections
+	}
+
+	if limits.MaxConnectionsPerIP < 0 {
+		validator.Push(fmt.Errorf(errFmtServerLimitsNegative, "max_connections_per_ip", limits.MaxConnectionsPerIP))
+	}
+
+	if limits.MaxRequestsPerConnection < 0 {
+		validator.Push(fmt.Errorf(errFmtServerLimitsNegative, "max_requests_per_connection", limits.MaxRequestsPerConnection))
+	}
+}
+
+// ValidateServerKeepAlive checks the keep-alive configuration is valid.
+func ValidateServerKeepAlive(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.KeepAlive.TCPPeriod < 0 {
+		validator.Push(fmt.Errorf(errFmtServerKeepAliveTCPPeriodNegative, config.Server.KeepAlive.TCPPeriod))
+	}
+}
+
+// ValidateServerH2C configures the default HTTP/2 over cleartext limits and checks the configured values are valid.
+func ValidateServerH2C(config *schema.Configuration, validator *schema.StructValidator) {
+	h2c := &config.Server.H2C
+
+	if h2c.MaxConcurrentStreams <= 0 {
+		h2c.MaxConcurrentStreams = schema.DefaultServerConfiguration.H2C.MaxConcurrentStreams
+	}
+
+	switch {
+	case h2c.MaxReadFrameSize == 0:
+		h2c.MaxReadFrameSize = schema.DefaultServerConfiguration.H2C.MaxReadFrameSize
+	case h2c.MaxReadFrameSize < 16384 || h2c.MaxReadFrameSize > 16777215:
+		validator.Push(fmt.Errorf(errFmtServerH2CMaxReadFrameSize, h2c.MaxReadFrameSize))
+	}
+
+	if h2c.Enable && (config.Server.TLS.Certificate != "" || config.Server.TLS.Key != "") {
+		validator.Push(errors.New(errFmtServerH2CWithTLS))
+	}
+}
+
+// ValidateServerScannerFilter configures the default scanner filter patterns and checks the configured values are valid.
+func ValidateServerScannerFilter(config *schema.Configuration, validator *schema.StructValidator) {
+	filter := &config.Server.ScannerFilter
+
+	if !filter.Enable {
+		return
+	}
+
+	if filter.Delay <= 0 {
+		filter.Delay = schema.DefaultServerConfiguration.ScannerFilter.Delay
+	}
+
+	if len(filter.UserAgents) == 0 {
+		filter.UserAgents = schema.DefaultServerConfiguration.ScannerFilter.UserAgents
+	}
+
+	if len(filter.Paths) == 0 {
+		filter.Paths = schema.DefaultServerConfiguration.ScannerFilter.Paths
+	}
+
+	var err error
+
+	for _, pattern := range filter.UserAgents {
+		if _, err = regexp.Compile(pattern); err != nil {
+			validator.Push(fmt.Errorf(errFmtServerScannerFilterInvalidRegex, "user_agents", pattern, err))
+		}
+	}
+
+	for _, pattern := range filter.Paths {
+		if _, err = regexp.Compile(pattern); err != nil {
+			validator.Push(fmt.Errorf(errFmtServerScannerFilterInvalidRegex, "paths", pattern, err))
+		}
+	}
+
+	for _, network := range filter.AllowedNetworks {
+		if !IsNetworkValid(network) {
+			validator.Push(fmt.Errorf(errFmtServerScannerFilterInvalidNetwork, network))
+		}
+	}
+}
+
+// ValidateServerHeaders configures the default security headers and checks the configured values are valid.
+func ValidateServerHeaders(config *schema.Configuration, validator *schema.StructValidator) {
+	headers := &config.Server.Headers
+
+	if headers.PermissionsPolicy == "" {
+		headers.PermissionsPolicy = schema.DefaultServerConfiguration.Headers.PermissionsPolicy
+	}
+
+	switch {
+	case headers.ReferrerPolicy == "":
+		headers.ReferrerPolicy = schema.DefaultServerConfiguration.Headers.ReferrerPolicy
+	case !utils.IsStringInSlice(headers.ReferrerPolicy, validServerHeadersReferrerPolicies):
+		validator.Push(fmt.Errorf(errFmtServerHeadersOptionInvalid, "referrer_policy", utils.StringJoinOr(validServerHeadersReferrerPolicies), headers.ReferrerPolicy))
+	}
+
+	switch {
+	case headers.FrameOptions == "":
+		headers.FrameOptions = schema.DefaultServerConfiguration.Headers.FrameOptions
+	case !utils.IsStringInSlice(headers.FrameOptions, validServerHeadersFrameOptions):
+		validator.Push(fmt.Errorf(errFmtServerHeadersOptionInvalid, "frame_options", utils.StringJoinOr(validServerHeadersFrameOptions), headers.FrameOptions))
+	}
+
+	validateServerHeadersStrictTransportSecurity(&headers.StrictTransportSecurity, validator)
+
+	switch {
+	case headers.CSPTemplate == "":
+		break
+	case headers.Groups.Portal.ContentSecurityPolicy == "":
+		headers.Groups.Portal.ContentSecurityPolicy = headers.CSPTemplate
+	default:
+		validator.Push(errors.New(errFmtServerHeadersCSPTemplateAndPortalGroupBothSet))
+	}
+
+	defaults := schema.DefaultServerConfiguration.Headers.Groups
+
+	validateServerHeadersGroup("portal", &headers.Groups.Portal, defaults.Portal, true, validator)
+	validateServerHeadersGroup("api", &headers.Groups.API, defaults.API, false, validator)
+	validateServerHeadersGroup("openid_connect", &headers.Groups.OpenIDConnect, defaults.OpenIDConnect, false, validator)
+	validateServerHeadersGroup("openapi", &headers.Groups.OpenAPI, defaults.OpenAPI, true, validator)
+}
+
+func validateServerHeadersStrictTransportSecurity(config *schema.ServerHeadersStrictTransportSecurity, validator *schema.StructValidator) {
+	switch {
+	case config.MaxAge < 0:
+		validator.Push(fmt.Errorf(errFmtServerHeadersHSTSMaxAgeNegative, config.MaxAge))
+	case config.MaxAge == 0:
+		if config.IncludeSubdomains {
+			validator.Push(fmt.Errorf(errFmtServerHeadersHSTSOptionWithoutMaxAge, "include_subdomains"))
+		}
+
+		if config.Preload {
+			validator.Push(fmt.Errorf(errFmtServerHeadersHSTSOptionWithoutMaxAge, "preload"))
+		}
+	case config.Preload && (!config.IncludeSubdomains || config.MaxAge < time.Hour*24*365):
+		validator.Push(errors.New(errFmtServerHeadersHSTSPreloadRequirementsNotMet))
+	}
+}
+
+func validateServerHeadersGroup(name string, config *schema.ServerHeadersGroup, defaults schema.ServerHeadersGroup, nonce bool, validator *schema.StructValidator) {
+	if config.ContentSecurityPolicy == "" {
+		config.ContentSecurityPolicy = defaults.ContentSecurityPolicy
+	} else if !nonce && config.ContentSecurityPolicy.HasNonce() {
+		validator.Push(fmt.Errorf(errFmtServerHeadersGroupContentSecurityPolicyNonce, name, schema.CSPTemplateNoncePlaceholder))
+	}
+
+	switch {
+	case config.CrossOriginOpenerPolicy == "":
+		config.CrossOriginOpenerPolicy = defaults.CrossOriginOpenerPolicy
+	case !utils.IsStringInSlice(config.CrossOriginOpenerPolicy, validServerHeadersCrossOriginOpenerPolicies):
+		validator.Push(fmt.Errorf(errFmtServerHeadersGroupOptionInvalid, name, "cross_origin_opener_policy", utils.StringJoinOr(validServerHeadersCrossOriginOpenerPolicies), config.CrossOriginOpenerPolicy))
+	}
+
+	switch {
+	case config.CrossOriginEmbedderPolicy == "":
+		config.CrossOriginEmbedderPolicy = defaults.CrossOriginEmbedderPolicy
+	case !utils.IsStringInSlice(config.CrossOriginEmbedderPolicy, validServerHeadersCrossOriginEmbedderPolicies):
+		validator.Push(fmt.Errorf(errFmtServerHeadersGroupOptionInvalid, name, "cross_origin_embedder_policy", utils.StringJoinOr(validServerHeadersCrossOriginEmbedderPolicies), config.CrossOriginEmbedderPolicy))
+	}
+
+	switch {
+	case config.CrossOriginResourcePolicy == "":
+		config.CrossOriginResourcePolicy = defaults.CrossOriginResourcePolicy
+	case !utils.IsStringInSlice(config.CrossOriginResourcePolicy, validServerHeadersCrossOriginResourcePolicies):
+		validator.Push(fmt.Errorf(errFmtServerHeadersGroupOptionInvalid, name, "cross_origin_resource_policy", utils.StringJoinOr(validServerHeadersCrossOriginResourcePolicies), config.CrossOriginResourcePolicy))
+	}
 }
 
 // ValidateServerAddress checks the configured server address is correct.
@@ -118,6 +475,10 @@ func ValidateServerEndpoints(config *schema.Configuration, validator *schema.Str
 		validator.PushWarning(fmt.Errorf("server: endpoints: option 'enable_pprof' should not be enabled in production"))
 	}
 
+	validateServerEndpointsHealth(config)
+	validateServerEndpointsSCIM(config, validator)
+	validateServerEndpointsMobile(config, validator)
+
 	if len(config.Server.Endpoints.Authz) == 0 {
 		config.Server.Endpoints.Authz = schema.DefaultServerConfiguration.Endpoints.Authz
 
@@ -158,6 +519,51 @@ func ValidateServerEndpoints(config *schema.Configuration, validator *schema.Str
 	}
 }
 
+func validateServerEndpointsSCIM(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Server.Endpoints.SCIM.Enabled {
+		return
+	}
+
+	switch n := len(config.Server.Endpoints.SCIM.Token); {
+	case n == 0:
+		validator.Push(errors.New(errFmtServerEndpointsSCIMTokenRequired))
+	case n < serverEndpointsSCIMTokenMinLength:
+		validator.Push(fmt.Errorf(errFmtServerEndpointsSCIMTokenLength, serverEndpointsSCIMTokenMinLength, n))
+	}
+}
+
+func validateServerEndpointsMobile(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Server.Endpoints.Mobile.Enabled {
+		return
+	}
+
+	if config.Server.Endpoints.Mobile.AccessTokenLifespan <= 0 {
+		config.Server.Endpoints.Mobile.AccessTokenLifespan = schema.DefaultServerConfiguration.Endpoints.Mobile.AccessTokenLifespan
+	}
+
+	if config.Server.Endpoints.Mobile.RefreshTokenLifespan <= 0 {
+		config.Server.Endpoints.Mobile.RefreshTokenLifespan = schema.DefaultServerConfiguration.Endpoints.Mobile.RefreshTokenLifespan
+	}
+
+	if config.Server.Endpoints.Mobile.AccessTokenLifespan >= config.Server.Endpoints.Mobile.RefreshTokenLifespan {
+		validator.Push(fmt.Errorf(errFmtServerEndpointsMobileLifespan, config.Server.Endpoints.Mobile.AccessTokenLifespan, config.Server.Endpoints.Mobile.RefreshTokenLifespan))
+	}
+
+	if config.TOTP.Disable {
+		validator.Push(errors.New(errFmtServerEndpointsMobileTOTPDisabled))
+	}
+}
+
+func validateServerEndpointsHealth(config *schema.Configuration) {
+	if config.Server.Endpoints.Health.CacheDuration <= 0 {
+		config.Server.Endpoints.Health.CacheDuration = schema.DefaultServerConfiguration.Endpoints.Health.CacheDuration
+	}
+
+	if config.Server.Endpoints.Health.Timeout <= 0 {
+		config.Server.Endpoints.Health.Timeout = schema.DefaultServerConfiguration.Endpoints.Health.Timeout
+	}
+}
+
 func validateServerEndpointsAuthzEndpoint(config *schema.Configuration, name string, endpoint schema.ServerEndpointsAuthz, validator *schema.StructValidator) {
 	if name == legacy {
 		switch endpoint.Implementation {
@@ -2,7 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -577,7 +579,7 @@ func TestServerAuthzEndpointLegacyAsImplementationLegacyWhenBlank(t *testing.T)
 func TestValidateTLSPathStatInvalidArgument(t *testing.T) {
 	validator := schema.NewStructValidator()
 
-	validateServerTLSFileExists("key", string([]byte{0x0, 0x1}), validator)
+	validateServerTLSFileExists("tls", "key", string([]byte{0x0, 0x1}), validator)
 
 	require.Len(t, validator.Errors(), 1)
 
@@ -589,9 +591,632 @@ func TestValidateTLSPathIsDir(t *testing.T) {
 
 	validator := schema.NewStructValidator()
 
-	validateServerTLSFileExists("key", dir, validator)
+	validateServerTLSFileExists("tls", "key", dir, validator)
 
 	require.Len(t, validator.Errors(), 1)
 
 	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("server: tls: option 'key' with path '%s' refers to a directory but it should refer to a file", dir))
 }
+
+func TestServerHeadersDefaults(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.Headers, config.Server.Headers)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{}
+
+	config.Server.Headers.CSPTemplate = "default-src 'self' 'nonce-${NONCE}'"
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.CSPTemplate("default-src 'self' 'nonce-${NONCE}'"), config.Server.Headers.Groups.Portal.ContentSecurityPolicy)
+}
+
+func TestServerHeadersErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerHeaders
+		expected []string
+	}{
+		{
+			"ShouldRaiseErrorInvalidValues",
+			schema.ServerHeaders{
+				ReferrerPolicy: "bad",
+				FrameOptions:   "ALLOW",
+				Groups: schema.ServerHeadersGroups{
+					Portal: schema.ServerHeadersGroup{
+						CrossOriginOpenerPolicy:   "bad",
+						CrossOriginEmbedderPolicy: "bad",
+						CrossOriginResourcePolicy: "bad",
+					},
+				},
+			},
+			[]string{
+				"server: headers: option 'referrer_policy' must be one of 'no-referrer', 'no-referrer-when-downgrade', 'origin', 'origin-when-cross-origin', 'same-origin', 'strict-origin', 'strict-origin-when-cross-origin', or 'unsafe-url' but it's configured as 'bad'",
+				"server: headers: option 'frame_options' must be one of 'DENY' or 'SAMEORIGIN' but it's configured as 'ALLOW'",
+				"server: headers: groups: portal: option 'cross_origin_opener_policy' must be one of 'unsafe-none', 'same-origin-allow-popups', or 'same-origin' but it's configured as 'bad'",
+				"server: headers: groups: portal: option 'cross_origin_embedder_policy' must be one of 'unsafe-none', 'require-corp', or 'credentialless' but it's configured as 'bad'",
+				"server: headers: groups: portal: option 'cross_origin_resource_policy' must be one of 'same-site', 'same-origin', or 'cross-origin' but it's configured as 'bad'",
+			},
+		},
+		{
+			"ShouldRaiseErrorNegativeMaxAge",
+			schema.ServerHeaders{
+				StrictTransportSecurity: schema.ServerHeadersStrictTransportSecurity{MaxAge: -time.Second},
+			},
+			[]string{
+				"server: headers: strict_transport_security: option 'max_age' must be 0 or greater but it's configured as '-1s'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOptionsWithoutMaxAge",
+			schema.ServerHeaders{
+				StrictTransportSecurity: schema.ServerHeadersStrictTransportSecurity{IncludeSubdomains: true, Preload: true},
+			},
+			[]string{
+				"server: headers: strict_transport_security: option 'include_subdomains' must not be enabled when option 'max_age' is 0",
+				"server: headers: strict_transport_security: option 'preload' must not be enabled when option 'max_age' is 0",
+			},
+		},
+		{
+			"ShouldRaiseErrorPreloadRequirements",
+			schema.ServerHeaders{
+				StrictTransportSecurity: schema.ServerHeadersStrictTransportSecurity{MaxAge: time.Hour, Preload: true},
+			},
+			[]string{
+				"server: headers: strict_transport_security: option 'preload' requires option 'include_subdomains' to be enabled and option 'max_age' to be at least 1 year",
+			},
+		},
+		{
+			"ShouldRaiseErrorNonceUnsupported",
+			schema.ServerHeaders{
+				Groups: schema.ServerHeadersGroups{
+					API: schema.ServerHeadersGroup{ContentSecurityPolicy: "default-src 'nonce-${NONCE}'"},
+				},
+			},
+			[]string{
+				"server: headers: groups: api: option 'content_security_policy' must not contain the nonce placeholder '${NONCE}' as this group does not support nonces",
+			},
+		},
+		{
+			"ShouldRaiseErrorBothCSPTemplates",
+			schema.ServerHeaders{
+				CSPTemplate: "default-src 'self'",
+				Groups: schema.ServerHeadersGroups{
+					Portal: schema.ServerHeadersGroup{ContentSecurityPolicy: "default-src 'none'"},
+				},
+			},
+			[]string{
+				"server: headers: option 'csp_template' and option 'groups.portal.content_security_policy' must not both be configured",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Server: schema.Server{Headers: tc.have}}
+
+			ValidateServerHeaders(config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestServerEndpointsHealth(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.Server{
+			Endpoints: schema.ServerEndpoints{
+				Health: schema.ServerEndpointsHealth{
+					CacheDuration: -1,
+					AdminGroups:   []string{"admins"},
+				},
+			},
+		},
+	}
+
+	ValidateServerEndpoints(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.Endpoints.Health.CacheDuration, config.Server.Endpoints.Health.CacheDuration)
+	assert.Equal(t, schema.DefaultServerConfiguration.Endpoints.Health.Timeout, config.Server.Endpoints.Health.Timeout)
+	assert.Equal(t, []string{"admins"}, config.Server.Endpoints.Health.AdminGroups)
+}
+
+func TestServerEndpointsSCIM(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerEndpointsSCIM
+		expected []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.ServerEndpointsSCIM{},
+			nil,
+		},
+		{
+			"ShouldAllowValidToken",
+			schema.ServerEndpointsSCIM{Enabled: true, Token: "aBDtEbCcnH8CmBRzmXV3XTq6eRRJt4Rj"},
+			nil,
+		},
+		{
+			"ShouldErrorOnMissingToken",
+			schema.ServerEndpointsSCIM{Enabled: true},
+			[]string{
+				"server: endpoints: scim: option 'token' is required when the SCIM endpoints are enabled",
+			},
+		},
+		{
+			"ShouldErrorOnShortToken",
+			schema.ServerEndpointsSCIM{Enabled: true, Token: "abc123"},
+			[]string{
+				"server: endpoints: scim: option 'token' must be at least 32 characters but it has 6 characters",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Server: schema.Server{Endpoints: schema.ServerEndpoints{SCIM: tc.have}}}
+
+			ValidateServerEndpoints(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestServerEndpointsMobile(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerEndpointsMobile
+		disable  bool
+		access   time.Duration
+		refresh  time.Duration
+		expected []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.ServerEndpointsMobile{},
+			false,
+			0,
+			0,
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.ServerEndpointsMobile{Enabled: true},
+			false,
+			time.Minute * 5,
+			time.Hour * 24 * 30,
+			nil,
+		},
+		{
+			"ShouldAllowCustomLifespans",
+			schema.ServerEndpointsMobile{Enabled: true, AccessTokenLifespan: time.Minute, RefreshTokenLifespan: time.Hour},
+			false,
+			time.Minute,
+			time.Hour,
+			nil,
+		},
+		{
+			"ShouldErrorOnAccessTokenLifespanLongerThanRefreshTokenLifespan",
+			schema.ServerEndpointsMobile{Enabled: true, AccessTokenLifespan: time.Hour, RefreshTokenLifespan: time.Minute},
+			false,
+			time.Hour,
+			time.Minute,
+			[]string{
+				"server: endpoints: mobile: option 'access_token_lifespan' must be less than option 'refresh_token_lifespan' but it's configured as '1h0m0s' and '1m0s' respectively",
+			},
+		},
+		{
+			"ShouldErrorOnTOTPDisabled",
+			schema.ServerEndpointsMobile{Enabled: true},
+			true,
+			time.Minute * 5,
+			time.Hour * 24 * 30,
+			[]string{
+				"server: endpoints: mobile: the mobile endpoints require TOTP as the second factor but TOTP is disabled",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.Server{Endpoints: schema.ServerEndpoints{Mobile: tc.have}},
+				TOTP:   schema.TOTP{Disable: tc.disable},
+			}
+
+			ValidateServerEndpoints(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, errs[i], expected)
+			}
+
+			assert.Equal(t, tc.access, config.Server.Endpoints.Mobile.AccessTokenLifespan)
+			assert.Equal(t, tc.refresh, config.Server.Endpoints.Mobile.RefreshTokenLifespan)
+		})
+	}
+}
+
+func TestServerLimits(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.Server{
+			Limits: schema.ServerLimits{
+				MaxRequestBodySize: -1,
+				MaxConnections:     -1,
+			},
+		},
+	}
+
+	ValidateServerLimits(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.Limits, config.Server.Limits)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Limits: schema.ServerLimits{
+				MaxConnectionsPerIP:      -1,
+				MaxRequestsPerConnection: -2,
+			},
+		},
+	}
+
+	ValidateServerLimits(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "server: limits: option 'max_connections_per_ip' must be 0 or greater but it's configured as '-1'")
+	assert.EqualError(t, validator.Errors()[1], "server: limits: option 'max_requests_per_connection' must be 0 or greater but it's configured as '-2'")
+}
+
+func TestServerAssets(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.Server{
+			AssetWatch: true,
+		},
+	}
+
+	ValidateServerAssets(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "server: option 'asset_watch' must not be enabled when option 'asset_path' is not configured")
+
+	dir := t.TempDir()
+
+	validator = schema.NewStructValidator()
+	config.Server.AssetPath = dir
+
+	ValidateServerAssets(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "branding.yml"), []byte("default:\n  primary_color: 'blue'\n"), 0600))
+
+	validator = schema.NewStructValidator()
+
+	ValidateServerAssets(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("server: option 'asset_path' has an invalid overlay: error validating the branding manifest '%s': default: option 'primary_color' must be a hex color in the '#RGB' or '#RRGGBB' format but it's configured as 'blue'", filepath.Join(dir, "branding.yml")))
+}
+
+func TestServerScannerFilter(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.Server{
+			ScannerFilter: schema.ServerScannerFilter{
+				Enable: true,
+			},
+		},
+	}
+
+	ValidateServerScannerFilter(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.ScannerFilter.Delay, config.Server.ScannerFilter.Delay)
+	assert.Equal(t, schema.DefaultServerConfiguration.ScannerFilter.UserAgents, config.Server.ScannerFilter.UserAgents)
+	assert.Equal(t, schema.DefaultServerConfiguration.ScannerFilter.Paths, config.Server.ScannerFilter.Paths)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			ScannerFilter: schema.ServerScannerFilter{
+				Enable:          true,
+				UserAgents:      []string{"(bad"},
+				Paths:           []string{"^/ok$", "[bad"},
+				AllowedNetworks: []string{"10.0.0.0/8", "bad"},
+			},
+		},
+	}
+
+	ValidateServerScannerFilter(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "server: scanner_filter: option 'user_agents' has an invalid regular expression '(bad': error parsing regexp: missing closing ): `(bad`")
+	assert.EqualError(t, validator.Errors()[1], "server: scanner_filter: option 'paths' has an invalid regular expression '[bad': error parsing regexp: missing closing ]: `[bad`")
+	assert.EqualError(t, validator.Errors()[2], "server: scanner_filter: option 'allowed_networks' must only contain valid IP addresses or CIDR notation networks but it has 'bad'")
+}
+
+func TestServerKeepAliveAndH2C(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateServerKeepAlive(config, validator)
+	ValidateServerH2C(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.H2C, config.Server.H2C)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			TLS: schema.ServerTLS{
+				Certificate: "/tmp/cert.pem",
+				Key:         "/tmp/key.pem",
+			},
+			KeepAlive: schema.ServerKeepAlive{
+				TCPPeriod: -time.Second,
+			},
+			H2C: schema.ServerH2C{
+				Enable:           true,
+				MaxReadFrameSize: 1024,
+			},
+		},
+	}
+
+	ValidateServerKeepAlive(config, validator)
+	ValidateServerH2C(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "server: keepalive: option 'tcp_period' must be 0 or greater but it's configured as '-1s'")
+	assert.EqualError(t, validator.Errors()[1], "server: h2c: option 'max_read_frame_size' must be between 16384 and 16777215 but it's configured as '1024'")
+	assert.EqualError(t, validator.Errors()[2], "server: h2c: option 'enable' must not be enabled when the tls options are configured")
+}
+
+func TestServerAdmin(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateServerAdmin(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Nil(t, config.Server.Admin.Address)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+			Admin: schema.ServerAdmin{
+				Enabled: true,
+				Token:   "4b0neCDHGdHfXXpAvnuqtwjbqLZ3meA3",
+			},
+		},
+	}
+
+	ValidateServerAdmin(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "tcp://127.0.0.1:9960/", config.Server.Admin.Address.String())
+	assert.Equal(t, schema.DefaultServerConfiguration.Admin.Buffers, config.Server.Admin.Buffers)
+	assert.Equal(t, schema.DefaultServerConfiguration.Admin.Timeouts, config.Server.Admin.Timeouts)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+			Admin: schema.ServerAdmin{
+				Enabled: true,
+				Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+				Token:   "abc",
+			},
+		},
+	}
+
+	ValidateServerAdmin(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "server: admin: option 'address' must not be the same as the server address 'tcp://:9091/'")
+	assert.EqualError(t, validator.Errors()[1], "server: admin: option 'token' must be at least 32 characters but it has 3 characters")
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Admin: schema.ServerAdmin{
+				Enabled: true,
+				Address: &schema.AddressTCP{Address: MustParseAddress("udp://127.0.0.1:9960/")},
+			},
+		},
+	}
+
+	ValidateServerAdmin(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "server: admin: option 'address' with value 'udp://127.0.0.1:9960/' is invalid: scheme must be one of 'tcp', 'tcp4', 'tcp6', or 'unix' but is configured as 'udp'")
+	assert.EqualError(t, validator.Errors()[1], "server: admin: option 'token' is required when the admin server is enabled")
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Admin: schema.ServerAdmin{
+				Enabled:      true,
+				Token:        "4b0neCDHGdHfXXpAvnuqtwjbqLZ3meA3",
+				EnableEvents: true,
+			},
+		},
+	}
+
+	ValidateServerAdmin(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "server: admin: option 'enable_events' requires the audit events to be enabled with the 'audit.enabled' option")
+}
+
+func TestServerLDAP(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateServerLDAP(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Nil(t, config.Server.LDAP.Address)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackend{
+			SQL: &schema.AuthenticationBackendSQL{},
+		},
+		Server: schema.Server{
+			Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+			LDAP: schema.ServerLDAP{
+				Enabled: true,
+				BaseDN:  "dc=example,dc=com",
+			},
+		},
+	}
+
+	ValidateServerLDAP(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "tcp://127.0.0.1:3890/", config.Server.LDAP.Address.String())
+	assert.Equal(t, 500, config.Server.LDAP.SizeLimit)
+	assert.Equal(t, time.Minute*5, config.Server.LDAP.IdleTimeout)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		Server: schema.Server{
+			Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+			LDAP: schema.ServerLDAP{
+				Enabled: true,
+				Address: &schema.AddressTCP{Address: MustParseAddress("tcp://:9091/")},
+				BaseDN:  "example.com",
+				TLS: schema.ServerTLS{
+					Key: "/ldap.key",
+				},
+			},
+		},
+	}
+
+	ValidateServerLDAP(config, validator)
+
+	require.Len(t, validator.Errors(), 5)
+	assert.EqualError(t, validator.Errors()[0], "server: ldap: option 'address' must not be the same as the server address 'tcp://:9091/'")
+	assert.EqualError(t, validator.Errors()[1], "server: ldap: option 'enabled' requires the 'sql' authentication backend to be configured")
+	assert.EqualError(t, validator.Errors()[2], "server: ldap: option 'base_dn' with value 'example.com' is not a valid distinguished name")
+	assert.EqualError(t, validator.Errors()[3], "server: ldap: tls: option 'key' must also be accompanied by option 'certificate'")
+	assert.EqualError(t, validator.Errors()[4], "server: ldap: tls: option 'key' with path '/ldap.key' refers to a file that doesn't exist")
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		AuthenticationBackend: schema.AuthenticationBackend{
+			SQL: &schema.AuthenticationBackendSQL{},
+		},
+		Server: schema.Server{
+			LDAP: schema.ServerLDAP{
+				Enabled: true,
+				Address: &schema.AddressTCP{Address: MustParseAddress("udp://127.0.0.1:3890/")},
+			},
+		},
+	}
+
+	ValidateServerLDAP(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "server: ldap: option 'address' with value 'udp://127.0.0.1:3890/' is invalid: scheme must be one of 'tcp', 'tcp4', 'tcp6', or 'unix' but is configured as 'udp'")
+	assert.EqualError(t, validator.Errors()[1], "server: ldap: option 'base_dn' is required when the ldap server is enabled")
+}
+
+func TestServerHeadless(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerHeadless
+		expected []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.ServerHeadless{AllowedOrigins: []*url.URL{MustParseURL("*")}},
+			nil,
+		},
+		{
+			"ShouldAllowNoOrigins",
+			schema.ServerHeadless{Enabled: true},
+			nil,
+		},
+		{
+			"ShouldAllowValidOrigins",
+			schema.ServerHeadless{Enabled: true, AllowedOrigins: []*url.URL{MustParseURL("https://login.example.com"), MustParseURL("http://localhost:3000")}},
+			nil,
+		},
+		{
+			"ShouldErrorOnWildcard",
+			schema.ServerHeadless{Enabled: true, AllowedOrigins: []*url.URL{MustParseURL("*")}},
+			[]string{
+				"server: headless: option 'allowed_origins' must not contain the wildcard origin '*' as the API permits credentials",
+			},
+		},
+		{
+			"ShouldErrorOnPathAndQuery",
+			schema.ServerHeadless{Enabled: true, AllowedOrigins: []*url.URL{MustParseURL("https://login.example.com/path?query=1")}},
+			[]string{
+				"server: headless: option 'allowed_origins' contains an invalid value 'https://login.example.com/path?query=1' as it has a path: origins must only be scheme, hostname, and an optional port",
+				"server: headless: option 'allowed_origins' contains an invalid value 'https://login.example.com/path?query=1' as it has a query string: origins must only be scheme, hostname, and an optional port",
+			},
+		},
+		{
+			"ShouldErrorOnScheme",
+			schema.ServerHeadless{Enabled: true, AllowedOrigins: []*url.URL{MustParseURL("ftp://login.example.com")}},
+			[]string{
+				"server: headless: option 'allowed_origins' contains an invalid value 'ftp://login.example.com' as it has the scheme 'ftp': origins must have the 'http' or 'https' scheme",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{Server: schema.Server{Headless: tc.have}}
+
+			ValidateServerHeadless(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
@@ -18,16 +18,44 @@ func ValidateSession(config *schema.Configuration, validator *schema.StructValid
 	}
 
 	if config.Session.Redis != nil {
-		if config.Session.Redis.HighAvailability != nil {
+		switch {
+		case config.Session.Redis.Cluster != nil && config.Session.Redis.HighAvailability != nil:
+			validator.Push(errors.New(errSessionRedisClusterAndHighAvailability))
+		case config.Session.Redis.Cluster != nil:
+			validateRedisCluster(&config.Session, validator)
+		case config.Session.Redis.HighAvailability != nil:
 			validateRedisSentinel(&config.Session, validator)
-		} else {
+		default:
 			validateRedis(&config.Session, validator)
 		}
 	}
 
+	validateSessionStorage(&config.Session, validator)
+
 	validateSession(config, validator)
 }
 
+func validateSessionStorage(config *schema.Session, validator *schema.StructValidator) {
+	switch config.Storage {
+	case "":
+		return
+	case schema.SessionStorageRedis:
+		if config.Redis == nil {
+			validator.Push(errors.New(errSessionStorageRedis))
+		}
+	case schema.SessionStorageMemory, schema.SessionStorageSQL:
+		if config.Redis != nil {
+			validator.Push(fmt.Errorf(errFmtSessionStorageRedisConfigured, config.Storage))
+		}
+
+		if config.Storage == schema.SessionStorageSQL && config.Secret == "" {
+			validator.Push(fmt.Errorf(errFmtSessionSecretRequired, schema.SessionStorageSQL))
+		}
+	default:
+		validator.Push(fmt.Errorf(errFmtSessionStorage, utils.StringJoinOr(validSessionStorages), config.Storage))
+	}
+}
+
 func validateSession(config *schema.Configuration, validator *schema.StructValidator) {
 	if config.Session.Expiration <= 0 {
 		config.Session.Expiration = schema.DefaultSessionConfiguration.Expiration // 1 hour.
@@ -103,6 +131,8 @@ func validateSessionCookieDomains(config *schema.Session, validator *schema.Stru
 
 		validateSessionSameSite(i, config, validator)
 
+		validateSessionBranding(i, config, validator)
+
 		domains = append(domains, d.Domain)
 	}
 }
@@ -242,6 +272,40 @@ func validateSessionSameSite(i int, config *schema.Session, validator *schema.St
 	}
 }
 
+// validateSessionBranding validates the portal branding of a session cookie domain.
+func validateSessionBranding(i int, config *schema.Session, validator *schema.StructValidator) {
+	var d = config.Cookies[i]
+
+	for _, attr := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{attrSessionBrandingTitle, d.Branding.Title, sessionBrandingTitleMaxLength},
+		{attrSessionBrandingAnnounce, d.Branding.Announcement, sessionBrandingAnnouncementMaxLength},
+	} {
+		if n := len([]rune(attr.value)); n > attr.max {
+			validator.Push(fmt.Errorf(errFmtSessionDomainBrandingLength, sessionDomainDescriptor(i, d), attr.name, attr.max, n))
+		}
+	}
+
+	if d.Branding.LogoURL != nil {
+		if !d.Branding.LogoURL.IsAbs() {
+			validator.Push(fmt.Errorf(errFmtSessionDomainBrandingURLNotAbsolute, sessionDomainDescriptor(i, d), attrSessionBrandingLogoURL, d.Branding.LogoURL))
+		} else if !utils.IsURISecure(d.Branding.LogoURL) {
+			validator.Push(fmt.Errorf(errFmtSessionDomainBrandingLogoURLInsecure, sessionDomainDescriptor(i, d), d.Branding.LogoURL))
+		}
+	}
+
+	if d.Branding.SupportContact != nil {
+		if !d.Branding.SupportContact.IsAbs() {
+			validator.Push(fmt.Errorf(errFmtSessionDomainBrandingURLNotAbsolute, sessionDomainDescriptor(i, d), attrSessionBrandingSupport, d.Branding.SupportContact))
+		} else if !utils.IsStringInSlice(d.Branding.SupportContact.Scheme, validSessionBrandingSupportContactSchemes) {
+			validator.Push(fmt.Errorf(errFmtSessionDomainBrandingSupportContactScheme, sessionDomainDescriptor(i, d), utils.StringJoinOr(validSessionBrandingSupportContactSchemes), d.Branding.SupportContact.Scheme))
+		}
+	}
+}
+
 func sessionDomainDescriptor(position int, domain schema.SessionCookie) string {
 	return fmt.Sprintf("#%d (domain '%s')", position+1, domain.Domain)
 }
@@ -317,3 +381,48 @@ func validateRedisSentinel(config *schema.Session, validator *schema.StructValid
 		validator.Push(errors.New(errFmtSessionRedisSentinelNodeHostMissing))
 	}
 }
+
+func validateRedisCluster(config *schema.Session, validator *schema.StructValidator) {
+	if config.Redis.Host == "" && len(config.Redis.Cluster.Nodes) == 0 {
+		validator.Push(errors.New(errSessionRedisClusterHostOrNodesRequired))
+	}
+
+	if config.Redis.DatabaseIndex != 0 {
+		validator.Push(fmt.Errorf(errFmtSessionRedisClusterDatabaseIndex, config.Redis.DatabaseIndex))
+	}
+
+	validateRedisCommon(config, validator)
+
+	if config.Redis.Host != "" && config.Redis.Port == 0 {
+		config.Redis.Port = schema.DefaultRedisConfiguration.Port
+	} else if config.Redis.Host != "" && (config.Redis.Port < 1 || config.Redis.Port > 65535) {
+		validator.Push(fmt.Errorf(errFmtSessionRedisPortRange, config.Redis.Port))
+	}
+
+	if config.Redis.MaximumActiveConnections <= 0 {
+		config.Redis.MaximumActiveConnections = schema.DefaultRedisConfiguration.MaximumActiveConnections
+	}
+
+	if config.Redis.Cluster.MaximumRedirects == 0 {
+		config.Redis.Cluster.MaximumRedirects = schema.DefaultRedisClusterConfiguration.MaximumRedirects
+	}
+
+	hostMissing := false
+
+	for i, node := range config.Redis.Cluster.Nodes {
+		if node.Host == "" {
+			hostMissing = true
+		}
+
+		switch {
+		case node.Port == 0:
+			config.Redis.Cluster.Nodes[i].Port = schema.DefaultRedisConfiguration.Port
+		case node.Port < 1 || node.Port > 65535:
+			validator.Push(fmt.Errorf(errFmtSessionRedisClusterNodePortRange, node.Port))
+		}
+	}
+
+	if hostMissing {
+		validator.Push(errors.New(errFmtSessionRedisClusterNodeHostMissing))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -609,6 +610,142 @@ func TestShouldNotRaiseErrorsAndSetDefaultPortWhenRedisSentinelPortBlank(t *test
 	assert.Equal(t, 26379, config.Session.Redis.Port)
 }
 
+func TestShouldSetDefaultsWhenRedisClusterHasNodes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Session.Redis = &schema.SessionRedis{
+		Cluster: &schema.SessionRedisCluster{
+			Nodes: []schema.SessionRedisHighAvailabilityNode{
+				{
+					Host: "node-1",
+					Port: 7000,
+				},
+				{
+					Host: "node-2",
+				},
+			},
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+
+	assert.Equal(t, 0, config.Session.Redis.Port)
+	assert.Equal(t, 8, config.Session.Redis.MaximumActiveConnections)
+	assert.Equal(t, 3, config.Session.Redis.Cluster.MaximumRedirects)
+	assert.Equal(t, 7000, config.Session.Redis.Cluster.Nodes[0].Port)
+	assert.Equal(t, 6379, config.Session.Redis.Cluster.Nodes[1].Port)
+}
+
+func TestShouldRaiseErrorsWhenRedisClusterOptionsIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Session.Secret = ""
+	config.Session.Redis = &schema.SessionRedis{
+		DatabaseIndex: 2,
+		Cluster: &schema.SessionRedisCluster{
+			Nodes: []schema.SessionRedisHighAvailabilityNode{
+				{
+					Port: 7000,
+				},
+				{
+					Host: "node-2",
+					Port: 65536,
+				},
+			},
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	errors := validator.Errors()
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, errors, 4)
+
+	assert.EqualError(t, errors[0], fmt.Sprintf(errFmtSessionRedisClusterDatabaseIndex, 2))
+	assert.EqualError(t, errors[1], fmt.Sprintf(errFmtSessionSecretRequired, "redis"))
+	assert.EqualError(t, errors[2], fmt.Sprintf(errFmtSessionRedisClusterNodePortRange, 65536))
+	assert.EqualError(t, errors[3], errFmtSessionRedisClusterNodeHostMissing)
+
+	validator.Clear()
+
+	config = newDefaultSessionConfig()
+
+	config.Session.Redis = &schema.SessionRedis{
+		Cluster: &schema.SessionRedisCluster{},
+	}
+
+	ValidateSession(&config, validator)
+
+	errors = validator.Errors()
+
+	require.Len(t, errors, 1)
+
+	assert.EqualError(t, errors[0], errSessionRedisClusterHostOrNodesRequired)
+
+	validator.Clear()
+
+	config = newDefaultSessionConfig()
+
+	config.Session.Redis = &schema.SessionRedis{
+		Host:             "redis",
+		Cluster:          &schema.SessionRedisCluster{},
+		HighAvailability: &schema.SessionRedisHighAvailability{SentinelName: "sentinel"},
+	}
+
+	ValidateSession(&config, validator)
+
+	errors = validator.Errors()
+
+	require.Len(t, errors, 1)
+
+	assert.EqualError(t, errors[0], errSessionRedisClusterAndHighAvailability)
+}
+
+func TestShouldValidateSessionStorage(t *testing.T) {
+	testCases := []struct {
+		name    string
+		storage string
+		secret  string
+		redis   *schema.SessionRedis
+		errs    []string
+	}{
+		{"ShouldAllowEmpty", "", testJWTSecret, nil, nil},
+		{"ShouldAllowMemory", schema.SessionStorageMemory, testJWTSecret, nil, nil},
+		{"ShouldAllowSQL", schema.SessionStorageSQL, testJWTSecret, nil, nil},
+		{"ShouldAllowRedis", schema.SessionStorageRedis, testJWTSecret, &schema.SessionRedis{Host: "redis.local", Port: 6379}, nil},
+		{"ShouldRaiseErrorSQLWithoutSecret", schema.SessionStorageSQL, "", nil, []string{"session: option 'secret' is required when using the 'sql' provider"}},
+		{"ShouldRaiseErrorRedisWithoutRedis", schema.SessionStorageRedis, testJWTSecret, nil, []string{"session: option 'storage' is configured as 'redis' but the 'redis' option is not configured"}},
+		{"ShouldRaiseErrorSQLWithRedis", schema.SessionStorageSQL, testJWTSecret, &schema.SessionRedis{Host: "redis.local", Port: 6379}, []string{"session: option 'storage' is configured as 'sql' but the 'redis' option is also configured which is only used when it's configured as 'redis'"}},
+		{"ShouldRaiseErrorInvalid", "file", testJWTSecret, nil, []string{"session: option 'storage' must be one of 'memory', 'redis', or 'sql' but it's configured as 'file'"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+
+			config.Session.Storage = tc.storage
+			config.Session.Secret = tc.secret
+			config.Session.Redis = tc.redis
+
+			ValidateSession(&config, validator)
+
+			assert.False(t, validator.HasWarnings())
+			require.Len(t, validator.Errors(), len(tc.errs))
+
+			for i, err := range validator.Errors() {
+				assert.EqualError(t, err, tc.errs[i])
+			}
+		})
+	}
+}
+
 func TestShouldRaiseErrorWhenRedisHostAndHighAvailabilityNodesEmpty(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -1013,6 +1150,87 @@ func TestShouldNotAllowLegacyAndModernCookiesConfig(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "session: option 'domain' and option 'cookies' can't be specified at the same time")
 }
 
+func TestShouldValidateSessionBranding(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.SessionCookieBranding
+		expected []string
+	}{
+		{
+			"ShouldAllowEmpty",
+			schema.SessionCookieBranding{},
+			nil,
+		},
+		{
+			"ShouldAllowValid",
+			schema.SessionCookieBranding{
+				Title:          "Example Login",
+				LogoURL:        MustParseURL("https://cdn.example.com/logo.svg"),
+				Announcement:   "Scheduled maintenance on Saturday.",
+				SupportContact: MustParseURL("mailto:support@example.com"),
+			},
+			nil,
+		},
+		{
+			"ShouldAllowTelephoneSupportContact",
+			schema.SessionCookieBranding{
+				SupportContact: MustParseURL("tel:+61-400-000-000"),
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnLongValues",
+			schema.SessionCookieBranding{
+				Title:        strings.Repeat("a", 65),
+				Announcement: strings.Repeat("b", 1025),
+			},
+			[]string{
+				"session: domain config #1 (domain 'example.com'): branding: option 'title' must not be more than 64 characters but it's configured with 65 characters",
+				"session: domain config #1 (domain 'example.com'): branding: option 'announcement' must not be more than 1024 characters but it's configured with 1025 characters",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnRelativeURLs",
+			schema.SessionCookieBranding{
+				LogoURL:        MustParseURL("/logo.svg"),
+				SupportContact: MustParseURL("support"),
+			},
+			[]string{
+				"session: domain config #1 (domain 'example.com'): branding: option 'logo_url' is not absolute with a value of '/logo.svg'",
+				"session: domain config #1 (domain 'example.com'): branding: option 'support_contact' is not absolute with a value of 'support'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidSchemes",
+			schema.SessionCookieBranding{
+				LogoURL:        MustParseURL("http://cdn.example.com/logo.svg"),
+				SupportContact: MustParseURL("ftp://example.com/support"),
+			},
+			[]string{
+				"session: domain config #1 (domain 'example.com'): branding: option 'logo_url' does not have a secure scheme with a value of 'http://cdn.example.com/logo.svg'",
+				"session: domain config #1 (domain 'example.com'): branding: option 'support_contact' must have one of the schemes 'https', 'mailto', or 'tel' but it's configured with the scheme 'ftp'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+			config.Session.Cookies[0].Branding = tc.have
+
+			ValidateSession(&config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+		})
+	}
+}
+
 func MustParseURL(uri string) *url.URL {
 	u, err := url.Parse(uri)
 
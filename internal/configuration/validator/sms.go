@@ -0,0 +1,204 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// ValidateSMS validates and updates the SMS configuration.
+func ValidateSMS(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.SMS.Disable {
+		return
+	}
+
+	if config.SMS.Provider == "" {
+		var providers []string
+
+		if config.SMS.Twilio != nil {
+			providers = append(providers, schema.SMSProviderTwilio)
+		}
+
+		if config.SMS.Vonage != nil {
+			providers = append(providers, schema.SMSProviderVonage)
+		}
+
+		if config.SMS.HTTP != nil {
+			providers = append(providers, schema.SMSProviderHTTP)
+		}
+
+		switch len(providers) {
+		case 0:
+			config.SMS.Disable = true
+
+			return
+		case 1:
+			config.SMS.Provider = providers[0]
+		default:
+			validator.Push(errors.New(errSMSMultipleProviders))
+
+			return
+		}
+	}
+
+	switch config.SMS.Provider {
+	case schema.SMSProviderTwilio:
+		if config.SMS.Twilio == nil {
+			config.SMS.Twilio = &schema.SMSTwilio{}
+		}
+
+		validateSMSTwilio(config.SMS.Twilio, validator)
+	case schema.SMSProviderVonage:
+		if config.SMS.Vonage == nil {
+			config.SMS.Vonage = &schema.SMSVonage{}
+		}
+
+		validateSMSVonage(config.SMS.Vonage, config.SMS.EnableVoice, validator)
+	case schema.SMSProviderHTTP:
+		if config.SMS.HTTP == nil {
+			config.SMS.HTTP = &schema.SMSHTTP{}
+		}
+
+		validateSMSHTTP(config.SMS.HTTP, validator)
+	default:
+		validator.Push(fmt.Errorf(errFmtSMSInvalidProvider, utils.StringJoinOr(validSMSProviders), config.SMS.Provider))
+
+		return
+	}
+
+	switch {
+	case config.SMS.Characters == 0:
+		config.SMS.Characters = schema.DefaultSMSConfiguration.Characters
+	case config.SMS.Characters < 6, config.SMS.Characters > 10:
+		validator.Push(fmt.Errorf(errFmtSMSCharacters, config.SMS.Characters))
+	}
+
+	if config.SMS.CodeLifespan <= 0 {
+		config.SMS.CodeLifespan = schema.DefaultSMSConfiguration.CodeLifespan
+	}
+
+	if !config.SMS.RateLimit.Disable {
+		if config.SMS.RateLimit.Requests <= 0 {
+			config.SMS.RateLimit.Requests = schema.DefaultSMSConfiguration.RateLimit.Requests
+		}
+
+		if config.SMS.RateLimit.Period <= 0 {
+			config.SMS.RateLimit.Period = schema.DefaultSMSConfiguration.RateLimit.Period
+		}
+	}
+}
+
+func validateSMSTwilio(config *schema.SMSTwilio, validator *schema.StructValidator) {
+	if config.URL == nil {
+		config.URL = schema.DefaultSMSTwilioConfiguration.URL
+	}
+
+	validateSMSProviderURL(schema.SMSProviderTwilio, "url", config.URL, validator)
+
+	if config.AccountSID == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderTwilio, "account_sid", schema.SMSProviderTwilio))
+	}
+
+	if config.AuthToken == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderTwilio, "auth_token", schema.SMSProviderTwilio))
+	}
+
+	if config.From == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderTwilio, "from", schema.SMSProviderTwilio))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultSMSTwilioConfiguration.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validateSMSProviderTLS(schema.SMSProviderTwilio, config.URL, config.TLS, schema.DefaultSMSTwilioConfiguration.TLS, validator)
+}
+
+func validateSMSVonage(config *schema.SMSVonage, voice bool, validator *schema.StructValidator) {
+	if config.URL == nil {
+		config.URL = schema.DefaultSMSVonageConfiguration.URL
+	}
+
+	if config.VoiceURL == nil {
+		config.VoiceURL = schema.DefaultSMSVonageConfiguration.VoiceURL
+	}
+
+	validateSMSProviderURL(schema.SMSProviderVonage, "url", config.URL, validator)
+	validateSMSProviderURL(schema.SMSProviderVonage, "voice_url", config.VoiceURL, validator)
+
+	if config.APIKey == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderVonage, "api_key", schema.SMSProviderVonage))
+	}
+
+	if config.APISecret == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderVonage, "api_secret", schema.SMSProviderVonage))
+	}
+
+	if config.From == "" {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, schema.SMSProviderVonage, "from", schema.SMSProviderVonage))
+	}
+
+	if voice {
+		if config.ApplicationID == "" {
+			validator.Push(fmt.Errorf(errFmtSMSProviderVoiceMissing, "application_id"))
+		}
+
+		if config.PrivateKey == nil {
+			validator.Push(fmt.Errorf(errFmtSMSProviderVoiceMissing, "private_key"))
+		}
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultSMSVonageConfiguration.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validateSMSProviderTLS(schema.SMSProviderVonage, config.URL, config.TLS, schema.DefaultSMSVonageConfiguration.TLS, validator)
+}
+
+func validateSMSHTTP(config *schema.SMSHTTP, validator *schema.StructValidator) {
+	validateSMSProviderURL(schema.SMSProviderHTTP, "url", config.URL, validator)
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultSMSHTTPConfiguration.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	validateSMSProviderTLS(schema.SMSProviderHTTP, config.URL, config.TLS, schema.DefaultSMSHTTPConfiguration.TLS, validator)
+}
+
+func validateSMSProviderURL(provider, option string, address *url.URL, validator *schema.StructValidator) {
+	if address == nil {
+		validator.Push(fmt.Errorf(errFmtSMSProviderMissingOption, provider, option, provider))
+	} else if address.Scheme != schemeHTTP && address.Scheme != schemeHTTPS {
+		validator.Push(fmt.Errorf(errFmtSMSProviderURLScheme, provider, option, address.Scheme))
+	}
+}
+
+func validateSMSProviderTLS(provider string, address *url.URL, config, defaults *schema.TLS, validator *schema.StructValidator) {
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: defaults.MinimumVersion,
+		MaximumVersion: defaults.MaximumVersion,
+	}
+
+	if address != nil {
+		configDefaultTLS.ServerName = address.Hostname()
+	}
+
+	if err := ValidateTLSConfig(config, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtSMSProviderTLSConfigInvalid, provider, err))
+	}
+}
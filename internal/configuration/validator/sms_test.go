@@ -0,0 +1,172 @@
+package validator
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateSMS(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.SMS
+		expected func(t *testing.T, config *schema.SMS)
+		errs     []string
+	}{
+		{
+			"ShouldDisableWithoutProviders",
+			schema.SMS{},
+			func(t *testing.T, config *schema.SMS) {
+				assert.True(t, config.Disable)
+				assert.Equal(t, "", config.Provider)
+			},
+			nil,
+		},
+		{
+			"ShouldNotValidateDisabled",
+			schema.SMS{Disable: true, Provider: "abc"},
+			func(t *testing.T, config *schema.SMS) {
+				assert.True(t, config.Disable)
+			},
+			nil,
+		},
+		{
+			"ShouldSetTwilioDefaults",
+			schema.SMS{Twilio: &schema.SMSTwilio{AccountSID: "AC123", AuthToken: "abc", From: "+15550100"}},
+			func(t *testing.T, config *schema.SMS) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, schema.SMSProviderTwilio, config.Provider)
+				assert.Equal(t, 6, config.Characters)
+				assert.Equal(t, time.Minute*5, config.CodeLifespan)
+				assert.Equal(t, 5, config.RateLimit.Requests)
+				assert.Equal(t, time.Hour, config.RateLimit.Period)
+				assert.Equal(t, "https://api.twilio.com", config.Twilio.URL.String())
+				assert.Equal(t, time.Second*10, config.Twilio.Timeout)
+				require.NotNil(t, config.Twilio.TLS)
+				assert.Equal(t, "api.twilio.com", config.Twilio.TLS.ServerName)
+				assert.Equal(t, uint16(tls.VersionTLS12), config.Twilio.TLS.MinimumVersion.Value)
+			},
+			nil,
+		},
+		{
+			"ShouldSetVonageDefaults",
+			schema.SMS{Provider: schema.SMSProviderVonage, Characters: 8, Vonage: &schema.SMSVonage{APIKey: "abc", APISecret: "123", From: "Authelia"}},
+			func(t *testing.T, config *schema.SMS) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, 8, config.Characters)
+				assert.Equal(t, "https://rest.nexmo.com", config.Vonage.URL.String())
+				assert.Equal(t, "https://api.nexmo.com", config.Vonage.VoiceURL.String())
+				require.NotNil(t, config.Vonage.TLS)
+				assert.Equal(t, "rest.nexmo.com", config.Vonage.TLS.ServerName)
+			},
+			nil,
+		},
+		{
+			"ShouldSetHTTPDefaults",
+			schema.SMS{HTTP: &schema.SMSHTTP{URL: MustParseURL("http://gateway:8080/send")}, RateLimit: schema.SMSRateLimit{Disable: true}},
+			func(t *testing.T, config *schema.SMS) {
+				assert.False(t, config.Disable)
+				assert.Equal(t, schema.SMSProviderHTTP, config.Provider)
+				assert.Equal(t, 0, config.RateLimit.Requests)
+				assert.Equal(t, time.Second*10, config.HTTP.Timeout)
+				require.NotNil(t, config.HTTP.TLS)
+				assert.Equal(t, "gateway", config.HTTP.TLS.ServerName)
+			},
+			nil,
+		},
+		{
+			"ShouldAllowVonageVoice",
+			schema.SMS{EnableVoice: true, Vonage: &schema.SMSVonage{APIKey: "abc", APISecret: "123", From: "+15550100", ApplicationID: "app", PrivateKey: keyRSA2048}},
+			nil,
+			nil,
+		},
+		{
+			"ShouldErrorMultipleProviders",
+			schema.SMS{Twilio: &schema.SMSTwilio{}, HTTP: &schema.SMSHTTP{}},
+			nil,
+			[]string{
+				"sms: option 'provider' must be configured when more than one provider is configured but it's absent",
+			},
+		},
+		{
+			"ShouldErrorInvalidProvider",
+			schema.SMS{Provider: "sinch"},
+			nil,
+			[]string{
+				"sms: option 'provider' must be one of 'twilio', 'vonage', or 'http' but it's configured as 'sinch'",
+			},
+		},
+		{
+			"ShouldErrorTwilioMissingOptions",
+			schema.SMS{Provider: schema.SMSProviderTwilio},
+			nil,
+			[]string{
+				"sms: twilio: option 'account_sid' is required when the provider is 'twilio' but it's absent",
+				"sms: twilio: option 'auth_token' is required when the provider is 'twilio' but it's absent",
+				"sms: twilio: option 'from' is required when the provider is 'twilio' but it's absent",
+			},
+		},
+		{
+			"ShouldErrorVonageMissingOptions",
+			schema.SMS{Provider: schema.SMSProviderVonage, EnableVoice: true},
+			nil,
+			[]string{
+				"sms: vonage: option 'api_key' is required when the provider is 'vonage' but it's absent",
+				"sms: vonage: option 'api_secret' is required when the provider is 'vonage' but it's absent",
+				"sms: vonage: option 'from' is required when the provider is 'vonage' but it's absent",
+				"sms: vonage: option 'application_id' is required when the option 'enable_voice' is true but it's absent",
+				"sms: vonage: option 'private_key' is required when the option 'enable_voice' is true but it's absent",
+			},
+		},
+		{
+			"ShouldErrorHTTPMissingURL",
+			schema.SMS{Provider: schema.SMSProviderHTTP},
+			nil,
+			[]string{
+				"sms: http: option 'url' is required when the provider is 'http' but it's absent",
+			},
+		},
+		{
+			"ShouldErrorHTTPBadScheme",
+			schema.SMS{HTTP: &schema.SMSHTTP{URL: MustParseURL("ftp://gateway.example.com")}},
+			nil,
+			[]string{
+				"sms: http: option 'url' must have a scheme of 'http' or 'https' but it's configured as 'ftp'",
+			},
+		},
+		{
+			"ShouldErrorCharacters",
+			schema.SMS{Characters: 4, HTTP: &schema.SMSHTTP{URL: MustParseURL("https://gateway.example.com")}},
+			nil,
+			[]string{
+				"sms: option 'characters' must be between 6 and 10 but it's configured as '4'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{SMS: tc.have}
+
+			ValidateSMS(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+
+			if tc.expected != nil {
+				tc.expected(t, &config.SMS)
+			}
+		})
+	}
+}
@@ -9,14 +9,23 @@ import (
 )
 
 // ValidateStorage validates storage configuration.
-func ValidateStorage(config schema.Storage, validator *schema.StructValidator) {
+func ValidateStorage(config *schema.Storage, validator *schema.StructValidator) {
 	if config.EncryptionKey == "" {
 		validator.Push(errors.New(errStrStorageEncryptionKeyMustBeProvided))
 	} else if len(config.EncryptionKey) < 20 {
 		validator.Push(errors.New(errStrStorageEncryptionKeyTooShort))
 	}
 
-	if config.Local == nil && config.MySQL == nil && config.PostgreSQL == nil {
+	for i, key := range config.PreviousEncryptionKeys {
+		switch {
+		case len(key) < 20:
+			validator.Push(fmt.Errorf(errFmtStoragePreviousEncryptionKeyTooShort, i+1))
+		case key == config.EncryptionKey:
+			validator.Push(fmt.Errorf(errFmtStoragePreviousEncryptionKeySame, i+1))
+		}
+	}
+
+	if config.Local == nil && config.MySQL == nil && config.PostgreSQL == nil && config.CockroachDB == nil {
 		validator.Push(errors.New(errStrStorage))
 
 		return
@@ -36,6 +45,10 @@ func ValidateStorage(config schema.Storage, validator *schema.StructValidator) {
 		configured = append(configured, "postgres")
 	}
 
+	if config.CockroachDB != nil {
+		configured = append(configured, "cockroachdb")
+	}
+
 	if len(configured) > 1 {
 		validator.Push(fmt.Errorf(errStrStorageMultiple, utils.StringJoinAnd(configured)))
 
@@ -53,9 +66,31 @@ func ValidateStorage(config schema.Storage, validator *schema.StructValidator) {
 	if config.PostgreSQL != nil {
 		validatePostgreSQLConfiguration(config.PostgreSQL, validator)
 	}
+
+	if config.CockroachDB != nil {
+		validateCockroachDBConfiguration(config.CockroachDB, validator)
+	}
+
+	validateStorageCleanup(&config.Cleanup, validator)
+}
+
+func validateStorageCleanup(config *schema.StorageCleanup, validator *schema.StructValidator) {
+	switch {
+	case config.Interval == 0:
+		config.Interval = schema.DefaultStorageCleanupConfiguration.Interval
+	case config.Interval < storageCleanupIntervalMinimum:
+		validator.Push(fmt.Errorf(errFmtStorageCleanupIntervalMinimum, config.Interval))
+	}
+
+	switch {
+	case config.BatchSize == 0:
+		config.BatchSize = schema.DefaultStorageCleanupConfiguration.BatchSize
+	case config.BatchSize < 0:
+		validator.Push(fmt.Errorf(errFmtStorageCleanupBatchSize, config.BatchSize))
+	}
 }
 
-func validateSQLConfiguration(config, defaults *schema.StorageSQL, validator *schema.StructValidator, provider string) {
+func validateSQLConfiguration(config, defaults *schema.StorageSQL, validator *schema.StructValidator, provider string, passwordless bool) {
 	if config.Address == nil {
 		validator.Push(fmt.Errorf(errFmtStorageOptionMustBeProvided, provider, "address"))
 	} else {
@@ -70,7 +105,25 @@ func validateSQLConfiguration(config, defaults *schema.StorageSQL, validator *sc
 		config.Address.SetPort(defaults.Address.Port())
 	}
 
-	if config.Username == "" || config.Password == "" {
+	for i, replica := range config.Replicas {
+		switch {
+		case replica == nil:
+			validator.Push(fmt.Errorf(errFmtStorageReplicaNotProvided, provider, i+1))
+		default:
+			if err := replica.ValidateSQL(); err != nil {
+				validator.Push(fmt.Errorf(errFmtStorageReplicaAddress, provider, i+1, replica.String(), err))
+			} else if replica.IsTCP() && replica.Port() == 0 {
+				replica.SetPort(defaults.Address.Port())
+			}
+		}
+	}
+
+	switch {
+	case passwordless && config.Username == "":
+		validator.Push(fmt.Errorf(errFmtStorageOptionMustBeProvided, provider, "username"))
+	case passwordless && config.Password != "":
+		validator.Push(fmt.Errorf(errFmtStorageIAMAuthenticationPassword, provider))
+	case !passwordless && (config.Username == "" || config.Password == ""):
 		validator.Push(fmt.Errorf(errFmtStorageUserPassMustBeProvided, provider))
 	}
 
@@ -84,7 +137,8 @@ func validateSQLConfiguration(config, defaults *schema.StorageSQL, validator *sc
 }
 
 func validateMySQLConfiguration(config *schema.StorageMySQL, validator *schema.StructValidator) {
-	validateSQLConfiguration(&config.StorageSQL, &schema.DefaultMySQLStorageConfiguration.StorageSQL, validator, "mysql")
+	validateSQLConfiguration(&config.StorageSQL, &schema.DefaultMySQLStorageConfiguration.StorageSQL, validator, "mysql", config.IAMAuthentication.Provider != "")
+	validateStorageIAMAuthentication(&config.IAMAuthentication, config.TLS != nil, validator, "mysql")
 
 	if config.TLS != nil {
 		configDefaultTLS := &schema.TLS{
@@ -103,7 +157,8 @@ func validateMySQLConfiguration(config *schema.StorageMySQL, validator *schema.S
 }
 
 func validatePostgreSQLConfiguration(config *schema.StoragePostgreSQL, validator *schema.StructValidator) {
-	validateSQLConfiguration(&config.StorageSQL, &schema.DefaultPostgreSQLStorageConfiguration.StorageSQL, validator, "postgres")
+	validateSQLConfiguration(&config.StorageSQL, &schema.DefaultPostgreSQLStorageConfiguration.StorageSQL, validator, "postgres", config.IAMAuthentication.Provider != "")
+	validateStorageIAMAuthentication(&config.IAMAuthentication, config.TLS != nil, validator, "postgres")
 
 	if config.Schema == "" {
 		config.Schema = schema.DefaultPostgreSQLStorageConfiguration.Schema
@@ -134,6 +189,56 @@ func validatePostgreSQLConfiguration(config *schema.StoragePostgreSQL, validator
 	}
 }
 
+// validateStorageIAMAuthentication validates the IAM database authentication which requires TLS as the tokens are
+// bearer credentials.
+func validateStorageIAMAuthentication(config *schema.StorageIAMAuthentication, tls bool, validator *schema.StructValidator, provider string) {
+	if config.Provider == "" {
+		return
+	}
+
+	if !utils.IsStringInSlice(config.Provider, validStorageIAMAuthenticationProviders) {
+		validator.Push(fmt.Errorf(errFmtStorageIAMAuthenticationProvider, provider, utils.StringJoinOr(validStorageIAMAuthenticationProviders), config.Provider))
+	}
+
+	if config.Region != "" && config.Provider != schema.StorageIAMAuthenticationProviderAWS {
+		validator.Push(fmt.Errorf(errFmtStorageIAMAuthenticationRegion, provider, config.Provider))
+	}
+
+	if !tls {
+		validator.Push(fmt.Errorf(errFmtStorageIAMAuthenticationTLS, provider))
+	}
+}
+
+func validateCockroachDBConfiguration(config *schema.StorageCockroachDB, validator *schema.StructValidator) {
+	validateSQLConfiguration(&config.StorageSQL, &schema.DefaultCockroachDBStorageConfiguration.StorageSQL, validator, "cockroachdb", false)
+
+	if config.Schema == "" {
+		config.Schema = schema.DefaultCockroachDBStorageConfiguration.Schema
+	}
+
+	switch {
+	case config.MaximumRetries == 0:
+		config.MaximumRetries = schema.DefaultCockroachDBStorageConfiguration.MaximumRetries
+	case config.MaximumRetries < 0:
+		validator.Push(fmt.Errorf(errFmtStorageCockroachDBMaximumRetries, config.MaximumRetries))
+	}
+
+	if config.TLS != nil {
+		configDefaultTLS := &schema.TLS{
+			MinimumVersion: schema.DefaultCockroachDBStorageConfiguration.TLS.MinimumVersion,
+			MaximumVersion: schema.DefaultCockroachDBStorageConfiguration.TLS.MaximumVersion,
+		}
+
+		if config.Address != nil {
+			configDefaultTLS.ServerName = config.Address.Hostname()
+		}
+
+		if err := ValidateTLSConfig(config.TLS, configDefaultTLS); err != nil {
+			validator.Push(fmt.Errorf(errFmtStorageTLSConfigInvalid, "cockroachdb", err))
+		}
+	}
+}
+
 func validateLocalStorageConfiguration(config *schema.StorageLocal, validator *schema.StructValidator) {
 	if config.Path == "" {
 		validator.Push(fmt.Errorf(errFmtStorageOptionMustBeProvided, "local", "path"))
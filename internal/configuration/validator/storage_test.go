@@ -3,6 +3,7 @@ package validator
 import (
 	"crypto/tls"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -21,6 +22,8 @@ func (suite *StorageSuite) SetupTest() {
 	suite.config.Local = nil
 	suite.config.PostgreSQL = nil
 	suite.config.MySQL = nil
+	suite.config.CockroachDB = nil
+	suite.config.Cleanup = schema.StorageCleanup{}
 }
 
 func (suite *StorageSuite) TestShouldValidateOneStorageIsConfigured() {
@@ -28,11 +31,11 @@ func (suite *StorageSuite) TestShouldValidateOneStorageIsConfigured() {
 	suite.config.PostgreSQL = nil
 	suite.config.MySQL = nil
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
-	suite.Assert().EqualError(suite.val.Errors()[0], "storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided")
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: configuration for a 'local', 'mysql', 'postgres' or 'cockroachdb' database must be provided")
 }
 
 func (suite *StorageSuite) TestShouldValidateMultipleStorageIsConfigured() {
@@ -40,11 +43,64 @@ func (suite *StorageSuite) TestShouldValidateMultipleStorageIsConfigured() {
 	suite.config.PostgreSQL = &schema.StoragePostgreSQL{}
 	suite.config.MySQL = &schema.StorageMySQL{}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
-	suite.Assert().EqualError(suite.val.Errors()[0], "storage: option 'local', 'mysql' and 'postgres' are mutually exclusive but 'local', 'mysql', and 'postgres' have been configured")
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: option 'local', 'mysql', 'postgres' and 'cockroachdb' are mutually exclusive but 'local', 'mysql', and 'postgres' have been configured")
+}
+
+func (suite *StorageSuite) TestShouldValidateCockroachDBHostUsernamePasswordAndDatabaseAreProvided() {
+	suite.config.CockroachDB = &schema.StorageCockroachDB{}
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Errors(), 3)
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: cockroachdb: option 'address' is required")
+	suite.Assert().EqualError(suite.val.Errors()[1], "storage: cockroachdb: option 'username' and 'password' are required")
+	suite.Assert().EqualError(suite.val.Errors()[2], "storage: cockroachdb: option 'database' is required")
+
+	suite.val.Clear()
+	suite.config.CockroachDB = &schema.StorageCockroachDB{
+		StorageSQL: schema.StorageSQL{
+			Address: &schema.AddressTCP{
+				Address: MustParseAddress("tcp://cockroachdb"),
+			},
+			Username: "myuser",
+			Password: "pass",
+			Database: "database",
+		},
+	}
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 0)
+
+	suite.Assert().Equal(26257, suite.config.CockroachDB.Address.Port())
+	suite.Assert().Equal("public", suite.config.CockroachDB.Schema)
+	suite.Assert().Equal(5, suite.config.CockroachDB.MaximumRetries)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidCockroachDBOptions() {
+	suite.config.CockroachDB = &schema.StorageCockroachDB{
+		StorageSQL: schema.StorageSQL{
+			Address:  &schema.AddressTCP{Address: MustParseAddress("tcp://cockroachdb:26257")},
+			Username: "myuser",
+			Password: "pass",
+			Database: "database",
+		},
+		MaximumRetries: -1,
+		TLS: &schema.TLS{
+			MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12},
+		},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 1)
+
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: cockroachdb: option 'maximum_retries' must be greater than 0 but it's configured as '-1'")
+	suite.Assert().Equal("cockroachdb", suite.config.CockroachDB.TLS.ServerName)
 }
 
 func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
@@ -52,7 +108,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 		Path: "",
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -62,7 +118,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 	suite.val.Clear()
 	suite.config.Local.Path = "/myapth"
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 0)
@@ -70,7 +126,7 @@ func (suite *StorageSuite) TestShouldValidateLocalPathIsProvided() {
 
 func (suite *StorageSuite) TestShouldValidateMySQLHostUsernamePasswordAndDatabaseAreProvided() {
 	suite.config.MySQL = &schema.StorageMySQL{}
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Errors(), 3)
 	suite.Assert().EqualError(suite.val.Errors()[0], "storage: mysql: option 'address' is required")
@@ -88,7 +144,7 @@ func (suite *StorageSuite) TestShouldValidateMySQLHostUsernamePasswordAndDatabas
 			Database: "database",
 		},
 	}
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 0)
@@ -107,7 +163,7 @@ func (suite *StorageSuite) TestShouldSetDefaultMySQLTLSServerName() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -131,7 +187,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidMySQLTLSVersion() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -155,7 +211,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidMySQLTLSMinVersionGreate
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -166,7 +222,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidMySQLTLSMinVersionGreate
 func (suite *StorageSuite) TestShouldValidatePostgreSQLHostUsernamePasswordAndDatabaseAreProvided() {
 	suite.config.PostgreSQL = &schema.StoragePostgreSQL{}
 	suite.config.MySQL = nil
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Errors(), 3)
 	suite.Assert().EqualError(suite.val.Errors()[0], "storage: postgres: option 'address' is required")
@@ -184,7 +240,7 @@ func (suite *StorageSuite) TestShouldValidatePostgreSQLHostUsernamePasswordAndDa
 			Database: "database",
 		},
 	}
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -202,7 +258,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSchemaDefault() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -226,7 +282,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresTLSDefaults() {
 		TLS: &schema.TLS{},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -252,7 +308,7 @@ func (suite *StorageSuite) TestShouldSetDefaultPostgreSQLTLSServerName() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -260,6 +316,115 @@ func (suite *StorageSuite) TestShouldSetDefaultPostgreSQLTLSServerName() {
 	suite.Assert().Equal(suite.config.PostgreSQL.Address.Hostname(), suite.config.PostgreSQL.TLS.ServerName)
 }
 
+func (suite *StorageSuite) TestShouldValidatePostgreSQLReplicas() {
+	suite.config.PostgreSQL = &schema.StoragePostgreSQL{
+		StorageSQL: schema.StorageSQL{
+			Address:  &schema.AddressTCP{Address: MustParseAddress("tcp://postgres:5432")},
+			Username: "myuser",
+			Password: "pass",
+			Database: "database",
+			Replicas: []*schema.AddressTCP{
+				{Address: MustParseAddress("tcp://replica1")},
+				{Address: MustParseAddress("tcp://replica2:5433")},
+			},
+		},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Assert().Len(suite.val.Warnings(), 0)
+	suite.Assert().Len(suite.val.Errors(), 0)
+
+	suite.Assert().Equal(5432, suite.config.PostgreSQL.Replicas[0].Port())
+	suite.Assert().Equal(5433, suite.config.PostgreSQL.Replicas[1].Port())
+
+	suite.val.Clear()
+
+	suite.config.PostgreSQL.Replicas = []*schema.AddressTCP{
+		nil,
+		{Address: MustParseAddress("udp://replica2:5432")},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Assert().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 2)
+
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: postgres: option 'replicas' value #1 is empty")
+	suite.Assert().EqualError(suite.val.Errors()[1], "storage: postgres: option 'replicas' value #2 'udp://replica2:5432' is invalid: scheme must be one of 'tcp', 'tcp4', 'tcp6', or 'unix' but is configured as 'udp'")
+}
+
+func (suite *StorageSuite) TestShouldValidatePostgreSQLIAMAuthentication() {
+	suite.config.PostgreSQL = &schema.StoragePostgreSQL{
+		StorageSQL: schema.StorageSQL{
+			Address: &schema.AddressTCP{
+				Address: MustParseAddress("tcp://authelia.abc123.us-east-1.rds.amazonaws.com:5432"),
+			},
+			Username: "authelia",
+			Database: "authelia",
+		},
+		IAMAuthentication: schema.StorageIAMAuthentication{
+			Provider: schema.StorageIAMAuthenticationProviderAWS,
+			Region:   "us-east-1",
+		},
+		TLS: &schema.TLS{},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Assert().Len(suite.val.Warnings(), 0)
+	suite.Assert().Len(suite.val.Errors(), 0)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPostgreSQLIAMAuthentication() {
+	suite.config.PostgreSQL = &schema.StoragePostgreSQL{
+		StorageSQL: schema.StorageSQL{
+			Address: &schema.AddressTCP{
+				Address: MustParseAddress("tcp://postgre:4321"),
+			},
+			Username: "authelia",
+			Password: "pass",
+			Database: "authelia",
+		},
+		IAMAuthentication: schema.StorageIAMAuthentication{
+			Provider: "oracle",
+			Region:   "us-east-1",
+		},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Assert().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 4)
+
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: postgres: option 'password' must not be configured when the 'iam_authentication' option 'provider' is configured")
+	suite.Assert().EqualError(suite.val.Errors()[1], "storage: postgres: iam_authentication: option 'provider' must be one of 'aws', 'azure', or 'gcp' but it's configured as 'oracle'")
+	suite.Assert().EqualError(suite.val.Errors()[2], "storage: postgres: iam_authentication: option 'region' must only be configured when the 'provider' is 'aws' but the 'provider' is configured as 'oracle'")
+	suite.Assert().EqualError(suite.val.Errors()[3], "storage: postgres: option 'tls' must be configured when the 'iam_authentication' option 'provider' is configured")
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnMySQLIAMAuthenticationWithoutUsername() {
+	suite.config.MySQL = &schema.StorageMySQL{
+		StorageSQL: schema.StorageSQL{
+			Address: &schema.AddressTCP{
+				Address: MustParseAddress("tcp://mysql:3306"),
+			},
+			Database: "authelia",
+		},
+		IAMAuthentication: schema.StorageIAMAuthentication{
+			Provider: schema.StorageIAMAuthenticationProviderAzure,
+		},
+		TLS: &schema.TLS{},
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Assert().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 1)
+
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: mysql: option 'username' is required")
+}
+
 func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPostgreSQLTLSVersion() {
 	suite.config.PostgreSQL = &schema.StoragePostgreSQL{
 		StorageSQL: schema.StorageSQL{
@@ -275,7 +440,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPostgreSQLTLSVersion() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -299,7 +464,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPostgreSQLMinVersionGrea
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -320,7 +485,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSSLDefaults() {
 		SSL: &schema.StoragePostgreSQLSSL{},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 1)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -345,7 +510,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnTLSAndLegacySSL() {
 		TLS: &schema.TLS{},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -369,7 +534,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresDefaultsDontOverrideConfigu
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 1)
 	suite.Assert().Len(suite.val.Errors(), 0)
@@ -395,7 +560,7 @@ func (suite *StorageSuite) TestShouldValidatePostgresSSLModeMustBeValid() {
 		},
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Assert().Len(suite.val.Warnings(), 1)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -408,7 +573,7 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnNoEncryptionKey() {
 		Path: "/this/is/a/path",
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
@@ -421,13 +586,55 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnShortEncryptionKey() {
 		Path: "/this/is/a/path",
 	}
 
-	ValidateStorage(suite.config, suite.val)
+	ValidateStorage(&suite.config, suite.val)
 
 	suite.Require().Len(suite.val.Warnings(), 0)
 	suite.Require().Len(suite.val.Errors(), 1)
 	suite.Assert().EqualError(suite.val.Errors()[0], "storage: option 'encryption_key' must be 20 characters or longer")
 }
 
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPreviousEncryptionKeys() {
+	suite.config.PreviousEncryptionKeys = []string{"a_previous_encryption_key_of_the_database", "abc", testEncryptionKey}
+	suite.config.Local = &schema.StorageLocal{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 2)
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: option 'previous_encryption_keys' value #2 must be 20 characters or longer")
+	suite.Assert().EqualError(suite.val.Errors()[1], "storage: option 'previous_encryption_keys' value #3 must not be the same as the 'encryption_key'")
+}
+
+func (suite *StorageSuite) TestShouldSetCleanupDefaults() {
+	suite.config.Local = &schema.StorageLocal{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultStorageCleanupConfiguration, suite.config.Cleanup)
+}
+
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidCleanup() {
+	suite.config.Local = &schema.StorageLocal{
+		Path: "/this/is/a/path",
+	}
+
+	suite.config.Cleanup = schema.StorageCleanup{Interval: time.Second * 30, BatchSize: -1}
+
+	ValidateStorage(&suite.config, suite.val)
+
+	suite.Require().Len(suite.val.Warnings(), 0)
+	suite.Require().Len(suite.val.Errors(), 2)
+	suite.Assert().EqualError(suite.val.Errors()[0], "storage: cleanup: option 'interval' must be at least 1 minute but it's configured as '30s'")
+	suite.Assert().EqualError(suite.val.Errors()[1], "storage: cleanup: option 'batch_size' must be greater than 0 but it's configured as '-1'")
+}
+
 func TestShouldRunStorageSuite(t *testing.T) {
 	suite.Run(t, new(StorageSuite))
 }
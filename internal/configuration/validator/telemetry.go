@@ -1,9 +1,11 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ValidateTelemetry validates the telemetry configuration.
@@ -43,4 +45,120 @@ func ValidateTelemetry(config *schema.Configuration, validator *schema.StructVal
 	if config.Telemetry.Metrics.Timeouts.Idle <= 0 {
 		config.Telemetry.Metrics.Timeouts.Idle = schema.DefaultTelemetryConfig.Metrics.Timeouts.Idle
 	}
+
+	validateTelemetryMetricsStatsD(&config.Telemetry.Metrics.StatsD, validator)
+	validateTelemetryMetricsOTLP(&config.Telemetry.Metrics.OTLP, validator)
+	validateTelemetryUsage(&config.Telemetry.Usage, validator)
+}
+
+func validateTelemetryMetricsStatsD(config *schema.TelemetryMetricsStatsD, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	if config.Address == nil {
+		config.Address = schema.DefaultTelemetryConfig.Metrics.StatsD.Address
+	}
+
+	if err := config.Address.ValidateListener(); err != nil {
+		validator.Push(fmt.Errorf(errFmtTelemetryMetricsStatsDAddress, config.Address.String(), err))
+	}
+
+	if !config.Address.IsUnixDomainSocket() && config.Address.Port() == 0 {
+		config.Address.SetPort(schema.DefaultTelemetryConfig.Metrics.StatsD.Address.Port())
+	}
+
+	switch {
+	case config.Flavor == "":
+		config.Flavor = schema.DefaultTelemetryConfig.Metrics.StatsD.Flavor
+	case !utils.IsStringInSlice(config.Flavor, validTelemetryMetricsStatsDFlavors):
+		validator.Push(fmt.Errorf(errFmtTelemetryMetricsStatsDFlavor, utils.StringJoinOr(validTelemetryMetricsStatsDFlavors), config.Flavor))
+	}
+
+	if len(config.Tags) != 0 && config.Flavor != schema.TelemetryMetricsStatsDFlavorDogStatsD {
+		validator.PushWarning(fmt.Errorf(errFmtTelemetryMetricsStatsDTags, config.Flavor))
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = schema.DefaultTelemetryConfig.Metrics.StatsD.Interval
+	}
+}
+
+func validateTelemetryMetricsOTLP(config *schema.TelemetryMetricsOTLP, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	if config.Endpoint == nil {
+		config.Endpoint = schema.DefaultTelemetryConfig.Metrics.OTLP.Endpoint
+	} else if config.Endpoint.Scheme != schemeHTTP && config.Endpoint.Scheme != schemeHTTPS {
+		validator.Push(fmt.Errorf(errFmtTelemetryMetricsOTLPEndpointScheme, config.Endpoint.Scheme))
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = schema.DefaultTelemetryConfig.Metrics.OTLP.Interval
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultTelemetryConfig.Metrics.OTLP.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: schema.DefaultTelemetryConfig.Metrics.OTLP.TLS.MinimumVersion,
+		MaximumVersion: schema.DefaultTelemetryConfig.Metrics.OTLP.TLS.MaximumVersion,
+		ServerName:     config.Endpoint.Hostname(),
+	}
+
+	if err := ValidateTLSConfig(config.TLS, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtTelemetryMetricsOTLPTLSConfigInvalid, err))
+	}
+}
+
+func validateTelemetryUsage(config *schema.TelemetryUsage, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	switch {
+	case config.Endpoint == nil:
+		validator.Push(errors.New(errTelemetryUsageEndpointRequired))
+	case config.Endpoint.Scheme != schemeHTTP && config.Endpoint.Scheme != schemeHTTPS:
+		validator.Push(fmt.Errorf(errFmtTelemetryUsageEndpointScheme, config.Endpoint.Scheme))
+	}
+
+	switch {
+	case config.Interval == 0:
+		config.Interval = schema.DefaultTelemetryConfig.Usage.Interval
+	case config.Interval < telemetryUsageIntervalMinimum:
+		validator.Push(fmt.Errorf(errFmtTelemetryUsageIntervalMinimum, config.Interval))
+	}
+
+	if config.Period <= 0 {
+		config.Period = schema.DefaultTelemetryConfig.Usage.Period
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultTelemetryConfig.Usage.Timeout
+	}
+
+	if config.TLS == nil {
+		config.TLS = &schema.TLS{}
+	}
+
+	configDefaultTLS := &schema.TLS{
+		MinimumVersion: schema.DefaultTelemetryConfig.Usage.TLS.MinimumVersion,
+		MaximumVersion: schema.DefaultTelemetryConfig.Usage.TLS.MaximumVersion,
+	}
+
+	if config.Endpoint != nil {
+		configDefaultTLS.ServerName = config.Endpoint.Hostname()
+	}
+
+	if err := ValidateTLSConfig(config.TLS, configDefaultTLS); err != nil {
+		validator.Push(fmt.Errorf(errFmtTelemetryUsageTLSConfigInvalid, err))
+	}
 }
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"crypto/tls"
 	"testing"
 	"time"
 
@@ -157,3 +158,208 @@ func TestValidateTelemetryShouldCorrectlyIdentifyValidAddressSchemes(t *testing.
 		})
 	}
 }
+
+func TestValidateTelemetryUsage(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.TelemetryUsage
+		expected schema.TelemetryUsage
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.TelemetryUsage{},
+			schema.TelemetryUsage{},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.TelemetryUsage{Enabled: true, Endpoint: MustParseURL("https://telemetry.example.com/usage")},
+			schema.TelemetryUsage{
+				Enabled:  true,
+				Endpoint: MustParseURL("https://telemetry.example.com/usage"),
+				Interval: time.Hour * 24,
+				Period:   time.Hour * 24 * 30,
+				Timeout:  time.Second * 10,
+				TLS:      &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12}, ServerName: "telemetry.example.com"},
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorMissingEndpoint",
+			schema.TelemetryUsage{Enabled: true, Interval: time.Hour, Period: time.Hour, Timeout: time.Second, TLS: &schema.TLS{}},
+			schema.TelemetryUsage{Enabled: true, Interval: time.Hour, Period: time.Hour, Timeout: time.Second, TLS: &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12}}},
+			[]string{"telemetry: usage: option 'endpoint' is required when the usage reports are enabled"},
+		},
+		{
+			"ShouldRaiseErrorInvalidSchemeAndInterval",
+			schema.TelemetryUsage{Enabled: true, Endpoint: MustParseURL("ftp://telemetry.example.com"), Interval: time.Minute},
+			schema.TelemetryUsage{
+				Enabled:  true,
+				Endpoint: MustParseURL("ftp://telemetry.example.com"),
+				Interval: time.Minute,
+				Period:   time.Hour * 24 * 30,
+				Timeout:  time.Second * 10,
+				TLS:      &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12}, ServerName: "telemetry.example.com"},
+			},
+			[]string{
+				"telemetry: usage: option 'endpoint' must have the scheme 'http' or 'https' but it's configured as 'ftp'",
+				"telemetry: usage: option 'interval' must be at least 1 hour but it's configured as '1m0s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.Configuration{Telemetry: schema.Telemetry{Usage: tc.have}}
+
+			ValidateTelemetry(config, validator)
+
+			assert.Equal(t, tc.expected, config.Telemetry.Usage)
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, expected := range tc.errs {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestValidateTelemetryMetricsStatsD(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.TelemetryMetricsStatsD
+		expected schema.TelemetryMetricsStatsD
+		wrns     []string
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.TelemetryMetricsStatsD{},
+			schema.TelemetryMetricsStatsD{},
+			nil,
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.TelemetryMetricsStatsD{Enabled: true},
+			schema.TelemetryMetricsStatsD{
+				Enabled:  true,
+				Address:  schema.DefaultTelemetryConfig.Metrics.StatsD.Address,
+				Flavor:   "statsd",
+				Interval: time.Second * 10,
+			},
+			nil,
+			nil,
+		},
+		{
+			"ShouldSetDefaultPort",
+			schema.TelemetryMetricsStatsD{Enabled: true, Address: &schema.AddressUDP{Address: MustParseAddress("udp://statsd.example.com")}, Flavor: "dogstatsd", Tags: map[string]string{"env": "prod"}, Interval: time.Minute},
+			schema.TelemetryMetricsStatsD{Enabled: true, Address: &schema.AddressUDP{Address: MustParseAddress("udp://statsd.example.com:8125")}, Flavor: "dogstatsd", Tags: map[string]string{"env": "prod"}, Interval: time.Minute},
+			nil,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorInvalidFlavorAndWarnTags",
+			schema.TelemetryMetricsStatsD{Enabled: true, Address: &schema.AddressUDP{Address: MustParseAddress("udp://127.0.0.1:8125")}, Flavor: "graphite", Tags: map[string]string{"env": "prod"}, Interval: time.Minute},
+			schema.TelemetryMetricsStatsD{Enabled: true, Address: &schema.AddressUDP{Address: MustParseAddress("udp://127.0.0.1:8125")}, Flavor: "graphite", Tags: map[string]string{"env": "prod"}, Interval: time.Minute},
+			[]string{"telemetry: metrics: statsd: option 'tags' is only sent with the 'dogstatsd' flavor but the flavor is configured as 'graphite'"},
+			[]string{"telemetry: metrics: statsd: option 'flavor' must be one of 'statsd' or 'dogstatsd' but it's configured as 'graphite'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.Configuration{Telemetry: schema.Telemetry{Metrics: schema.TelemetryMetrics{StatsD: tc.have}}}
+
+			ValidateTelemetry(config, validator)
+
+			assert.Equal(t, tc.expected, config.Telemetry.Metrics.StatsD)
+
+			wrns := validator.Warnings()
+
+			require.Len(t, wrns, len(tc.wrns))
+
+			for i, expected := range tc.wrns {
+				assert.EqualError(t, wrns[i], expected)
+			}
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, expected := range tc.errs {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestValidateTelemetryMetricsOTLP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.TelemetryMetricsOTLP
+		expected schema.TelemetryMetricsOTLP
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateDisabled",
+			schema.TelemetryMetricsOTLP{},
+			schema.TelemetryMetricsOTLP{},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.TelemetryMetricsOTLP{Enabled: true},
+			schema.TelemetryMetricsOTLP{
+				Enabled:  true,
+				Endpoint: MustParseURL("http://127.0.0.1:4318/v1/metrics"),
+				Interval: time.Second * 30,
+				Timeout:  time.Second * 10,
+				TLS:      &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12}, ServerName: "127.0.0.1"},
+			},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorInvalidScheme",
+			schema.TelemetryMetricsOTLP{Enabled: true, Endpoint: MustParseURL("grpc://otel.example.com:4317"), Interval: time.Minute, Timeout: time.Second},
+			schema.TelemetryMetricsOTLP{
+				Enabled:  true,
+				Endpoint: MustParseURL("grpc://otel.example.com:4317"),
+				Interval: time.Minute,
+				Timeout:  time.Second,
+				TLS:      &schema.TLS{MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS12}, ServerName: "otel.example.com"},
+			},
+			[]string{"telemetry: metrics: otlp: option 'endpoint' must have the scheme 'http' or 'https' but it's configured as 'grpc'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			config := &schema.Configuration{Telemetry: schema.Telemetry{Metrics: schema.TelemetryMetrics{OTLP: tc.have}}}
+
+			ValidateTelemetry(config, validator)
+
+			assert.Equal(t, tc.expected, config.Telemetry.Metrics.OTLP)
+			assert.Len(t, validator.Warnings(), 0)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, expected := range tc.errs {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
@@ -31,6 +31,17 @@ func ValidateTOTP(config *schema.Configuration, validator *schema.StructValidato
 	} else if config.TOTP.SecretSize < schema.TOTPSecretSizeMinimum {
 		validator.Push(fmt.Errorf(errFmtTOTPInvalidSecretSize, schema.TOTPSecretSizeMinimum, config.TOTP.SecretSize))
 	}
+
+	validateTOTPDriftCompensation(config, validator)
+}
+
+func validateTOTPDriftCompensation(config *schema.Configuration, validator *schema.StructValidator) {
+	switch {
+	case config.TOTP.DriftCompensation.Maximum == 0:
+		config.TOTP.DriftCompensation.Maximum = schema.DefaultTOTPConfiguration.DriftCompensation.Maximum
+	case config.TOTP.DriftCompensation.Maximum < 1 || config.TOTP.DriftCompensation.Maximum > schema.TOTPDriftMaximum:
+		validator.Push(fmt.Errorf(errFmtTOTPInvalidDriftMaximum, 1, schema.TOTPDriftMaximum, config.TOTP.DriftCompensation.Maximum))
+	}
 }
 
 func validateTOTPValueSetAlgorithm(config *schema.Configuration, validator *schema.StructValidator) {
@@ -47,6 +47,7 @@ func TestValidateTOTP(t *testing.T) {
 				AllowedAlgorithms: []string{schema.TOTPAlgorithmSHA1},
 				AllowedDigits:     []int{6},
 				AllowedPeriods:    []int{30},
+				DriftCompensation: schema.DefaultTOTPConfiguration.DriftCompensation,
 			},
 		},
 		{
@@ -72,6 +73,7 @@ func TestValidateTOTP(t *testing.T) {
 				AllowedAlgorithms: []string{schema.TOTPAlgorithmSHA1},
 				AllowedDigits:     []int{6},
 				AllowedPeriods:    []int{30},
+				DriftCompensation: schema.DefaultTOTPConfiguration.DriftCompensation,
 			},
 		},
 		{
@@ -135,6 +137,45 @@ func TestValidateTOTP(t *testing.T) {
 				"totp: option 'allowed_digits' must only have the values 6 or 8 but one of the values is '6'",
 			},
 		},
+		{
+			desc: "ShouldValidateDriftCompensation",
+			have: schema.TOTP{
+				Issuer:            "abc",
+				DriftCompensation: schema.TOTPDriftCompensation{Enable: true, Maximum: 10},
+			},
+			expected: schema.TOTP{
+				DefaultAlgorithm:  schema.TOTPAlgorithmSHA1,
+				DefaultDigits:     6,
+				DefaultPeriod:     30,
+				SecretSize:        32,
+				Skew:              schema.DefaultTOTPConfiguration.Skew,
+				Issuer:            "abc",
+				AllowedAlgorithms: []string{schema.TOTPAlgorithmSHA1},
+				AllowedDigits:     []int{6},
+				AllowedPeriods:    []int{30},
+				DriftCompensation: schema.TOTPDriftCompensation{Enable: true, Maximum: 10},
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorWhenInvalidDriftCompensationMaximum",
+			have: schema.TOTP{
+				Issuer:            "abc",
+				DriftCompensation: schema.TOTPDriftCompensation{Enable: true, Maximum: 21},
+			},
+			errs: []string{
+				"totp: drift_compensation: option 'maximum' must be between 1 and 20 but it's configured as '21'",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorWhenNegativeDriftCompensationMaximum",
+			have: schema.TOTP{
+				Issuer:            "abc",
+				DriftCompensation: schema.TOTPDriftCompensation{Maximum: -1},
+			},
+			errs: []string{
+				"totp: drift_compensation: option 'maximum' must be between 1 and 20 but it's configured as '-1'",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -159,6 +200,7 @@ func TestValidateTOTP(t *testing.T) {
 				assert.Equal(t, tc.expected.AllowedAlgorithms, config.TOTP.AllowedAlgorithms)
 				assert.Equal(t, tc.expected.AllowedDigits, config.TOTP.AllowedDigits)
 				assert.Equal(t, tc.expected.AllowedPeriods, config.TOTP.AllowedPeriods)
+				assert.Equal(t, tc.expected.DriftCompensation, config.TOTP.DriftCompensation)
 			} else {
 				expectedErrs := len(tc.errs)
 
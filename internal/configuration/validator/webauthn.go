@@ -3,6 +3,8 @@ package validator
 import (
 	"fmt"
 
+	"github.com/go-webauthn/webauthn/protocol"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -30,4 +32,73 @@ func ValidateWebAuthn(config *schema.Configuration, validator *schema.StructVali
 	case !utils.IsStringInSlice(string(config.WebAuthn.UserVerification), validWebAuthnUserVerificationRequirement):
 		validator.Push(fmt.Errorf(errFmtWebAuthnUserVerification, utils.StringJoinOr(validWebAuthnConveyancePreferences), config.WebAuthn.UserVerification))
 	}
+
+	validateWebAuthnFilteringGroupPolicies(&config.WebAuthn.Filtering, validator)
+	validateWebAuthnSelectionCriteria(&config.WebAuthn, validator)
+	validateWebAuthnPasskeyLogin(&config.WebAuthn, validator)
+}
+
+// validateWebAuthnSelectionCriteria validates the selection criteria. The defaults differ when passkey login is
+// enabled as the credentials must be discoverable and are commonly platform authenticators.
+func validateWebAuthnSelectionCriteria(config *schema.WebAuthn, validator *schema.StructValidator) {
+	switch {
+	case config.SelectionCriteria.Attachment == "":
+		if !config.PasskeyLogin.Enable {
+			config.SelectionCriteria.Attachment = schema.DefaultWebAuthnConfiguration.SelectionCriteria.Attachment
+		}
+	case !utils.IsStringInSlice(string(config.SelectionCriteria.Attachment), validWebAuthnAttachments):
+		validator.Push(fmt.Errorf(errFmtWebAuthnSelectionCriteriaAttachment, utils.StringJoinOr(validWebAuthnAttachments), config.SelectionCriteria.Attachment))
+	}
+
+	switch {
+	case config.SelectionCriteria.Discoverability == "":
+		if config.PasskeyLogin.Enable {
+			config.SelectionCriteria.Discoverability = protocol.ResidentKeyRequirementPreferred
+		} else {
+			config.SelectionCriteria.Discoverability = schema.DefaultWebAuthnConfiguration.SelectionCriteria.Discoverability
+		}
+	case !utils.IsStringInSlice(string(config.SelectionCriteria.Discoverability), validWebAuthnDiscoverability):
+		validator.Push(fmt.Errorf(errFmtWebAuthnSelectionCriteriaDiscoverability, utils.StringJoinOr(validWebAuthnDiscoverability), config.SelectionCriteria.Discoverability))
+	}
+}
+
+func validateWebAuthnPasskeyLogin(config *schema.WebAuthn, validator *schema.StructValidator) {
+	switch {
+	case config.PasskeyLogin.UserVerification == "":
+		config.PasskeyLogin.UserVerification = schema.DefaultWebAuthnConfiguration.PasskeyLogin.UserVerification
+	case !utils.IsStringInSlice(string(config.PasskeyLogin.UserVerification), validWebAuthnUserVerificationRequirement):
+		validator.Push(fmt.Errorf(errFmtWebAuthnPasskeyLoginUserVerification, utils.StringJoinOr(validWebAuthnUserVerificationRequirement), config.PasskeyLogin.UserVerification))
+	}
+
+	if !config.PasskeyLogin.Enable {
+		return
+	}
+
+	if config.SelectionCriteria.Discoverability == protocol.ResidentKeyRequirementDiscouraged {
+		validator.Push(fmt.Errorf(errFmtWebAuthnPasskeyLoginDiscoverability, config.SelectionCriteria.Discoverability))
+	}
+
+	if config.PasskeyLogin.TwoFactorWithUserVerification && config.PasskeyLogin.UserVerification == protocol.VerificationDiscouraged {
+		validator.Push(fmt.Errorf(errFmtWebAuthnPasskeyLoginTwoFactor, config.PasskeyLogin.UserVerification))
+	}
+}
+
+func validateWebAuthnFilteringGroupPolicies(config *schema.WebAuthnFiltering, validator *schema.StructValidator) {
+	groups := map[string]int{}
+
+	for i, policy := range config.GroupPolicies {
+		if len(policy.Groups) == 0 {
+			validator.Push(fmt.Errorf(errFmtWebAuthnFilteringGroupPolicyNoGroups, i+1))
+		}
+
+		for _, group := range policy.Groups {
+			if j, ok := groups[group]; ok {
+				validator.Push(fmt.Errorf(errFmtWebAuthnFilteringGroupPolicyGroupDuplicate, i+1, group, j))
+
+				continue
+			}
+
+			groups[group] = i + 1
+		}
+	}
 }
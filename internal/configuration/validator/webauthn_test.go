@@ -96,3 +96,140 @@ func TestWebAuthnShouldRaiseErrorsOnInvalidOptions(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "webauthn: option 'attestation_conveyance_preference' must be one of 'none', 'indirect', or 'direct' but it's configured as 'no'")
 	assert.EqualError(t, validator.Errors()[1], "webauthn: option 'user_verification' must be one of 'none', 'indirect', or 'direct' but it's configured as 'yes'")
 }
+
+func TestWebAuthnShouldValidateFilteringGroupPolicies(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		WebAuthn: schema.WebAuthn{
+			Filtering: schema.WebAuthnFiltering{
+				GroupPolicies: []schema.WebAuthnFilteringGroupPolicy{
+					{Groups: []string{"admins"}, ProhibitBackupEligibility: true},
+					{Groups: []string{"dev", "ops"}},
+				},
+			},
+		},
+	}
+
+	ValidateWebAuthn(config, validator)
+
+	require.Len(t, validator.Errors(), 0)
+
+	assert.True(t, config.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups([]string{"dev", "admins"}))
+	assert.False(t, config.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups([]string{"ops"}))
+	assert.False(t, config.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups(nil))
+
+	config.WebAuthn.Filtering.ProhibitBackupEligibility = true
+
+	assert.False(t, config.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups([]string{"ops"}))
+	assert.True(t, config.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups([]string{"users"}))
+}
+
+func TestWebAuthnShouldRaiseErrorsOnInvalidFilteringGroupPolicies(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		WebAuthn: schema.WebAuthn{
+			Filtering: schema.WebAuthnFiltering{
+				GroupPolicies: []schema.WebAuthnFilteringGroupPolicy{
+					{Groups: []string{"admins"}, ProhibitBackupEligibility: true},
+					{},
+					{Groups: []string{"dev", "admins"}},
+				},
+			},
+		},
+	}
+
+	ValidateWebAuthn(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "webauthn: filtering: group_policies: policy #2: option 'groups' must have at least one group")
+	assert.EqualError(t, validator.Errors()[1], "webauthn: filtering: group_policies: policy #3: option 'groups' has the group 'admins' which is already configured in policy #1")
+}
+
+func TestWebAuthnShouldSetDefaultSelectionCriteria(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{}
+
+	ValidateWebAuthn(config, validator)
+
+	require.Len(t, validator.Errors(), 0)
+	assert.Equal(t, protocol.CrossPlatform, config.WebAuthn.SelectionCriteria.Attachment)
+	assert.Equal(t, protocol.ResidentKeyRequirementDiscouraged, config.WebAuthn.SelectionCriteria.Discoverability)
+	assert.Equal(t, protocol.VerificationRequired, config.WebAuthn.PasskeyLogin.UserVerification)
+
+	validator = schema.NewStructValidator()
+	config = &schema.Configuration{
+		WebAuthn: schema.WebAuthn{
+			PasskeyLogin: schema.WebAuthnPasskeyLogin{Enable: true},
+		},
+	}
+
+	ValidateWebAuthn(config, validator)
+
+	require.Len(t, validator.Errors(), 0)
+	assert.Equal(t, protocol.AuthenticatorAttachment(""), config.WebAuthn.SelectionCriteria.Attachment)
+	assert.Equal(t, protocol.ResidentKeyRequirementPreferred, config.WebAuthn.SelectionCriteria.Discoverability)
+	assert.Equal(t, protocol.VerificationRequired, config.WebAuthn.PasskeyLogin.UserVerification)
+}
+
+func TestWebAuthnShouldRaiseErrorsOnInvalidPasskeyLogin(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.WebAuthn
+		expected []string
+	}{
+		{
+			"ShouldRaiseErrorOnInvalidSelectionCriteria",
+			schema.WebAuthn{
+				SelectionCriteria: schema.WebAuthnSelectionCriteria{Attachment: "usb", Discoverability: "always"},
+			},
+			[]string{
+				"webauthn: selection_criteria: option 'attachment' must be one of 'platform' or 'cross-platform' but it's configured as 'usb'",
+				"webauthn: selection_criteria: option 'discoverability' must be one of 'discouraged', 'preferred', or 'required' but it's configured as 'always'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidUserVerification",
+			schema.WebAuthn{
+				PasskeyLogin: schema.WebAuthnPasskeyLogin{Enable: true, UserVerification: "always"},
+			},
+			[]string{
+				"webauthn: passkey_login: option 'user_verification' must be one of 'discouraged', 'preferred', or 'required' but it's configured as 'always'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnDiscouragedDiscoverability",
+			schema.WebAuthn{
+				SelectionCriteria: schema.WebAuthnSelectionCriteria{Discoverability: protocol.ResidentKeyRequirementDiscouraged},
+				PasskeyLogin:      schema.WebAuthnPasskeyLogin{Enable: true},
+			},
+			[]string{
+				"webauthn: passkey_login: option 'enable' must not be true when the 'selection_criteria' option 'discoverability' is configured as 'discouraged'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnTwoFactorWithoutUserVerification",
+			schema.WebAuthn{
+				PasskeyLogin: schema.WebAuthnPasskeyLogin{Enable: true, UserVerification: protocol.VerificationDiscouraged, TwoFactorWithUserVerification: true},
+			},
+			[]string{
+				"webauthn: passkey_login: option 'two_factor_with_user_verification' must not be true when the option 'user_verification' is configured as 'discouraged'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{WebAuthn: tc.have}
+
+			ValidateWebAuthn(config, validator)
+
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+		})
+	}
+}
@@ -14,3 +14,12 @@ const (
 
 // PossibleMethods is the set of all possible Duo 2FA methods.
 var PossibleMethods = []string{Push} // OTP, Phone, SMS.
+
+// Duo Auth Results.
+const (
+	allow   = "allow"
+	deny    = "deny"
+	auth    = "auth"
+	enroll  = "enroll"
+	waiting = "waiting"
+)
@@ -1,13 +1,17 @@
 package duo
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/url"
 
 	duoapi "github.com/duosecurity/duo_api_golang"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
@@ -18,6 +22,16 @@ func NewDuoAPI(duoAPI *duoapi.DuoApi) *APIImpl {
 	}
 }
 
+// NewDuoAPIFromConfiguration creates a duo API instance from the configuration. The TLS certificate of the Duo API
+// host is not verified when insecure is true which is only intended for the development environment.
+func NewDuoAPIFromConfiguration(config *schema.DuoAPI, insecure bool) *APIImpl {
+	if insecure {
+		return NewDuoAPI(duoapi.NewDuoApi(config.IntegrationKey, config.SecretKey, config.Hostname, "", duoapi.SetInsecure()))
+	}
+
+	return NewDuoAPI(duoapi.NewDuoApi(config.IntegrationKey, config.SecretKey, config.Hostname, ""))
+}
+
 // Call performs a request to the DuoAPI.
 func (d *APIImpl) Call(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, values url.Values, method string, path string) (*Response, error) {
 	var response Response
@@ -77,3 +91,108 @@ func (d *APIImpl) AuthCall(ctx *middlewares.AutheliaCtx, userSession *session.Us
 
 	return &authResponse, nil
 }
+
+// StartBackchannelAuthentication sends an asynchronous push to the enrolled device of the user in order to approve an
+// OpenID Connect 1.0 Client Initiated Backchannel Authentication request and returns the Duo transaction identifier.
+func (d *APIImpl) StartBackchannelAuthentication(_ context.Context, username, remoteIP, message string) (txid string, err error) {
+	values := url.Values{}
+
+	values.Set("username", username)
+	values.Set("factor", Push)
+	values.Set("device", "auto")
+	values.Set("async", "1")
+
+	if len(remoteIP) != 0 {
+		values.Set("ipaddr", remoteIP)
+	}
+
+	if len(message) != 0 {
+		values.Set("pushinfo", url.Values{"Message": []string{message}}.Encode())
+	}
+
+	var (
+		response     *Response
+		authResponse AsyncAuthResponse
+	)
+
+	if response, err = d.signedCall(fasthttp.MethodPost, "/auth/v2/auth", values); err != nil {
+		return "", err
+	}
+
+	if err = json.Unmarshal(response.Response, &authResponse); err != nil {
+		return "", err
+	}
+
+	if len(authResponse.TransactionID) == 0 {
+		return "", fmt.Errorf("duo auth request did not return a transaction id")
+	}
+
+	return authResponse.TransactionID, nil
+}
+
+// GetBackchannelAuthenticationStatus returns the status of a push sent with StartBackchannelAuthentication.
+func (d *APIImpl) GetBackchannelAuthenticationStatus(_ context.Context, txid string) (status int, err error) {
+	var (
+		response     *Response
+		authResponse AuthResponse
+	)
+
+	if response, err = d.signedCall(fasthttp.MethodGet, "/auth/v2/auth_status", url.Values{"txid": []string{txid}}); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	if err = json.Unmarshal(response.Response, &authResponse); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	switch authResponse.Result {
+	case allow:
+		return model.OAuth2CIBAStatusApproved, nil
+	case deny:
+		return model.OAuth2CIBAStatusDenied, nil
+	default:
+		return model.OAuth2CIBAStatusPending, nil
+	}
+}
+
+func (d *APIImpl) signedCall(method, path string, values url.Values) (response *Response, err error) {
+	var responseBytes []byte
+
+	if _, responseBytes, err = d.DuoApi.SignedCall(method, path, values); err != nil {
+		return nil, err
+	}
+
+	response = &Response{}
+
+	if err = json.Unmarshal(responseBytes, response); err != nil {
+		return nil, err
+	}
+
+	if response.Stat != "OK" {
+		return nil, fmt.Errorf("duo %s request failed: %s (%s), error code %d", path, response.Message, response.MessageDetail, response.Code)
+	}
+
+	return response, nil
+}
+
+// HealthCheck performs a check request to the DuoAPI which verifies the integration key and secret key.
+func (d *APIImpl) HealthCheck(_ context.Context) (err error) {
+	var (
+		response      Response
+		responseBytes []byte
+	)
+
+	if _, responseBytes, err = d.DuoApi.SignedCall(fasthttp.MethodGet, "/auth/v2/check", nil); err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(responseBytes, &response); err != nil {
+		return err
+	}
+
+	if response.Stat != "OK" {
+		return fmt.Errorf("duo check request failed: %s (%s), error code %d", response.Message, response.MessageDetail, response.Code)
+	}
+
+	return nil
+}
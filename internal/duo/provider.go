@@ -0,0 +1,6 @@
+package duo
+
+var (
+	_ Provider = (*APIImpl)(nil)
+	_ Provider = (*CircuitBreakerProvider)(nil)
+)
@@ -1,6 +1,7 @@
 package duo
 
 import (
+	"context"
 	"encoding/json"
 	"net/url"
 
@@ -10,13 +11,24 @@ import (
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
-// API interface wrapping duo api library for testing purpose.
+// API is the interface of the MFA push providers used by the push second factor. The values are the parameters of
+// the Duo Auth API which every provider interprets, and the responses are the responses of the Duo Auth API which
+// every provider translates its results to.
 type API interface {
-	Call(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, values url.Values, method string, path string) (response *Response, err error)
 	PreAuthCall(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, values url.Values) (response *PreAuthResponse, err error)
 	AuthCall(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, values url.Values) (response *AuthResponse, err error)
 }
 
+// Provider is a MFA push provider which in addition to the API used by the push second factor can approve OpenID
+// Connect 1.0 Client Initiated Backchannel Authentication requests and be health checked.
+type Provider interface {
+	API
+
+	StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error)
+	GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error)
+	HealthCheck(ctx context.Context) (err error)
+}
+
 // APIImpl implementation of DuoAPI interface.
 type APIImpl struct {
 	*duoapi.DuoApi
@@ -50,6 +62,11 @@ type AuthResponse struct {
 	TrustedDeviceToken string `json:"trusted_device_token"`
 }
 
+// AsyncAuthResponse is a response for an asynchronous authorization request.
+type AsyncAuthResponse struct {
+	TransactionID string `json:"txid"`
+}
+
 // PreAuthResponse is a response for a preauthorization request.
 type PreAuthResponse struct {
 	Result          string   `json:"result"`
@@ -0,0 +1,39 @@
+package election
+
+import (
+	"time"
+)
+
+const (
+	redisKeyPrefix = "authelia-leader-election:"
+
+	// releaseTimeout is the maximum duration the release of a lease can take when a holder steps down.
+	releaseTimeout = time.Second * 5
+)
+
+const (
+	// redisScriptAcquire sets the key to the holder when the key doesn't exist and extends the expiration of the key
+	// when it's already held by the holder, as a single atomic operation.
+	redisScriptAcquire = `
+local current = redis.call('GET', KEYS[1])
+
+if current == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+
+	return 1
+elseif current == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+
+	return 1
+end
+
+return 0`
+
+	// redisScriptRelease deletes the key only if it's held by the holder.
+	redisScriptRelease = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+
+return 0`
+)
@@ -0,0 +1,224 @@
+package election
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+// NewElector returns a new Elector which campaigns for the leases using the lock as the holder.
+func NewElector(config schema.LeaderElection, lock Lock, holder string) *Elector {
+	return &Elector{
+		config: config,
+		lock:   lock,
+		holder: holder,
+	}
+}
+
+// NewHolder returns a holder identifier which is unique to this replica. The hostname is included so the replica
+// holding a lease can be easily identified by administrators.
+func NewHolder(r random.Provider) string {
+	suffix := r.StringCustom(8, random.CharSetAlphaNumeric)
+
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname + "-" + suffix
+	}
+
+	return suffix
+}
+
+// Elector coordinates the singleton background jobs so they only run on the replica which holds the lease for each
+// job. A nil *Elector is valid and runs the jobs on every replica, which is the behavior when the leader election is
+// disabled.
+type Elector struct {
+	config schema.LeaderElection
+	lock   Lock
+	holder string
+}
+
+// Holder returns the holder identifier of this replica.
+func (e *Elector) Holder() string {
+	if e == nil {
+		return ""
+	}
+
+	return e.holder
+}
+
+// Controller returns a Controller which only runs the controller while this replica holds the lease with the given
+// name.
+func (e *Elector) Controller(name string, controller Controller, log *logrus.Entry) Controller {
+	if e == nil {
+		return controller
+	}
+
+	return NewSingleton(name, e.holder, e.lock, e.config, controller, log)
+}
+
+// Func returns a function which only calls fn while this replica holds the lease with the given name. The function
+// is expected to be called periodically at the provided interval, and each call acquires or renews the lease for the
+// interval plus the lease duration so the same replica keeps calling fn while it's healthy.
+func (e *Elector) Func(name string, interval time.Duration, fn func(ctx context.Context) (err error)) func(ctx context.Context) (err error) {
+	if e == nil {
+		return fn
+	}
+
+	lease := interval + e.config.LeaseDuration
+
+	return func(ctx context.Context) (err error) {
+		var acquired bool
+
+		if acquired, err = e.lock.Acquire(ctx, name, e.holder, lease); err != nil || !acquired {
+			return err
+		}
+
+		return fn(ctx)
+	}
+}
+
+// NewSingleton returns a new Singleton which runs the controller while the holder holds the lease with the given name.
+func NewSingleton(name, holder string, lock Lock, config schema.LeaderElection, controller Controller, log *logrus.Entry) *Singleton {
+	return &Singleton{
+		name:       name,
+		holder:     holder,
+		lock:       lock,
+		config:     config,
+		controller: controller,
+		log:        log.WithFields(map[string]any{"lease": name, "holder": holder}),
+	}
+}
+
+// Singleton is a Controller which campaigns for a lease and runs the wrapped controller only while it holds the lease.
+// The lease is renewed at the renew interval, and the wrapped controller is stopped as soon as the lease can't be
+// renewed so that it never runs on more than one replica at a time for longer than the lease duration.
+type Singleton struct {
+	name       string
+	holder     string
+	lock       Lock
+	config     schema.LeaderElection
+	controller Controller
+	log        *logrus.Entry
+
+	leader atomic.Bool
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// IsLeader returns true if this replica currently holds the lease and is running the wrapped controller.
+func (s *Singleton) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// Run the Singleton until the context is canceled, releasing the lease if it's held when the context is canceled.
+func (s *Singleton) Run(ctx context.Context) {
+	s.log.WithFields(map[string]any{"lease_duration": s.config.LeaseDuration.String(), "renew_interval": s.config.RenewInterval.String()}).Debug("Campaigning for the leader election lease")
+
+	ticker := time.NewTicker(s.config.RenewInterval)
+
+	defer ticker.Stop()
+
+	s.campaign(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.campaign(ctx)
+		case <-ctx.Done():
+			s.shutdown()
+
+			return
+		}
+	}
+}
+
+func (s *Singleton) campaign(ctx context.Context) {
+	cctx, cancel := context.WithTimeout(ctx, s.config.RenewInterval)
+
+	defer cancel()
+
+	acquired, err := s.lock.Acquire(cctx, s.name, s.holder, s.config.LeaseDuration)
+
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.log.WithError(err).Error("Error occurred acquiring the leader election lease")
+
+		s.stop("The leader election lease could not be renewed")
+	case acquired:
+		s.start(ctx)
+	default:
+		s.stop("The leader election lease is held by another replica")
+	}
+}
+
+func (s *Singleton) start(ctx context.Context) {
+	if s.leader.Load() {
+		s.log.Trace("Renewed the leader election lease")
+
+		return
+	}
+
+	var cctx context.Context
+
+	cctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+
+	s.leader.Store(true)
+
+	s.log.Info("Acquired the leader election lease, starting the controller")
+
+	go func(done chan struct{}) {
+		defer close(done)
+
+		s.controller.Run(cctx)
+	}(s.done)
+}
+
+func (s *Singleton) stop(reason string) {
+	if !s.leader.Load() {
+		s.log.Trace(reason)
+
+		return
+	}
+
+	s.cancel()
+
+	<-s.done
+
+	s.leader.Store(false)
+
+	s.log.Warnf("%s, stopped the controller", reason)
+}
+
+func (s *Singleton) shutdown() {
+	if !s.leader.Load() {
+		return
+	}
+
+	s.cancel()
+
+	<-s.done
+
+	s.leader.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+
+	defer cancel()
+
+	if err := s.lock.Release(ctx, s.name, s.holder); err != nil {
+		s.log.WithError(err).Warn("Error occurred releasing the leader election lease")
+
+		return
+	}
+
+	s.log.Debug("Released the leader election lease")
+}
@@ -0,0 +1,320 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+type testLock struct {
+	mu      sync.Mutex
+	holders map[string]string
+	err     error
+
+	released []string
+}
+
+func newTestLock() *testLock {
+	return &testLock{holders: map[string]string{}}
+}
+
+func (l *testLock) Acquire(_ context.Context, name, holder string, _ time.Duration) (acquired bool, err error) {
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return false, l.err
+	}
+
+	if current, ok := l.holders[name]; ok && current != holder {
+		return false, nil
+	}
+
+	l.holders[name] = holder
+
+	return true, nil
+}
+
+func (l *testLock) Release(_ context.Context, name, holder string) (err error) {
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	if l.holders[name] == holder {
+		delete(l.holders, name)
+	}
+
+	l.released = append(l.released, name+":"+holder)
+
+	return nil
+}
+
+func (l *testLock) set(name, holder string, err error) {
+	l.mu.Lock()
+
+	defer l.mu.Unlock()
+
+	if holder == "" {
+		delete(l.holders, name)
+	} else {
+		l.holders[name] = holder
+	}
+
+	l.err = err
+}
+
+type testController struct {
+	running atomic.Int32
+	started atomic.Int32
+}
+
+func (c *testController) Run(ctx context.Context) {
+	c.started.Add(1)
+	c.running.Add(1)
+
+	defer c.running.Add(-1)
+
+	<-ctx.Done()
+}
+
+func newTestLog() *logrus.Entry {
+	logger, _ := test.NewNullLogger()
+
+	logger.SetLevel(logrus.TraceLevel)
+
+	return logrus.NewEntry(logger)
+}
+
+var testConfig = schema.LeaderElection{
+	Enabled:       true,
+	Backend:       schema.LeaderElectionBackendStorage,
+	LeaseDuration: time.Millisecond * 300,
+	RenewInterval: time.Millisecond * 20,
+}
+
+func TestSingletonShouldRunOnlyOnLeader(t *testing.T) {
+	lock := newTestLock()
+
+	controllerA, controllerB := &testController{}, &testController{}
+
+	singletonA := NewSingleton("storage-cleanup", "host-a", lock, testConfig, controllerA, newTestLog())
+	singletonB := NewSingleton("storage-cleanup", "host-b", lock, testConfig, controllerB, newTestLog())
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+
+	defer cancelB()
+
+	doneA, doneB := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(doneA)
+
+		singletonA.Run(ctxA)
+	}()
+
+	require.Eventually(t, singletonA.IsLeader, time.Second, time.Millisecond*5)
+
+	go func() {
+		defer close(doneB)
+
+		singletonB.Run(ctxB)
+	}()
+
+	time.Sleep(testConfig.RenewInterval * 3)
+
+	assert.True(t, singletonA.IsLeader())
+	assert.False(t, singletonB.IsLeader())
+	assert.Equal(t, int32(1), controllerA.running.Load())
+	assert.Equal(t, int32(0), controllerB.running.Load())
+
+	cancelA()
+
+	<-doneA
+
+	assert.False(t, singletonA.IsLeader())
+	assert.Equal(t, int32(0), controllerA.running.Load())
+	assert.Equal(t, []string{"storage-cleanup:host-a"}, lock.released)
+
+	require.Eventually(t, singletonB.IsLeader, time.Second, time.Millisecond*5)
+	require.Eventually(t, func() bool { return controllerB.running.Load() == 1 }, time.Second, time.Millisecond*5)
+
+	cancelB()
+
+	<-doneB
+
+	assert.Equal(t, int32(0), controllerB.running.Load())
+	assert.Equal(t, int32(1), controllerA.started.Load())
+	assert.Equal(t, int32(1), controllerB.started.Load())
+}
+
+func TestSingletonShouldStepDownWhenLeaseIsLost(t *testing.T) {
+	testCases := []struct {
+		name   string
+		holder string
+		err    error
+	}{
+		{
+			"ShouldStepDownWhenHeldByAnotherReplica",
+			"host-b",
+			nil,
+		},
+		{
+			"ShouldStepDownOnError",
+			"host-a",
+			errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lock := newTestLock()
+			controller := &testController{}
+
+			singleton := NewSingleton("storage-cleanup", "host-a", lock, testConfig, controller, newTestLog())
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				singleton.Run(ctx)
+			}()
+
+			require.Eventually(t, func() bool { return controller.running.Load() == 1 }, time.Second, time.Millisecond*5)
+
+			lock.set("storage-cleanup", tc.holder, tc.err)
+
+			require.Eventually(t, func() bool { return !singleton.IsLeader() }, time.Second, time.Millisecond*5)
+
+			assert.Equal(t, int32(0), controller.running.Load())
+
+			lock.set("storage-cleanup", "", nil)
+
+			require.Eventually(t, singleton.IsLeader, time.Second, time.Millisecond*5)
+			require.Eventually(t, func() bool { return controller.started.Load() == 2 }, time.Second, time.Millisecond*5)
+
+			cancel()
+
+			<-done
+
+			assert.Equal(t, int32(0), controller.running.Load())
+		})
+	}
+}
+
+func TestElectorShouldRunControllerWhenNil(t *testing.T) {
+	var elector *Elector
+
+	controller := &testController{}
+
+	assert.Equal(t, controller, elector.Controller("storage-cleanup", controller, newTestLog()))
+	assert.Equal(t, "", elector.Holder())
+}
+
+func TestElectorShouldWrapController(t *testing.T) {
+	elector := NewElector(testConfig, newTestLock(), "host-a")
+
+	controller, ok := elector.Controller("storage-cleanup", &testController{}, newTestLog()).(*Singleton)
+
+	require.True(t, ok)
+	assert.Equal(t, "storage-cleanup", controller.name)
+	assert.Equal(t, "host-a", controller.holder)
+	assert.Equal(t, "host-a", elector.Holder())
+}
+
+func TestElectorFunc(t *testing.T) {
+	var calls int
+
+	fn := func(ctx context.Context) (err error) {
+		calls++
+
+		return nil
+	}
+
+	var elector *Elector
+
+	assert.NoError(t, elector.Func("telemetry-usage", time.Hour, fn)(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	lock := newTestLock()
+
+	elector = NewElector(testConfig, lock, "host-a")
+
+	guarded := elector.Func("telemetry-usage", time.Hour, fn)
+
+	assert.NoError(t, guarded(context.Background()))
+	assert.Equal(t, 2, calls)
+
+	lock.set("telemetry-usage", "host-b", nil)
+
+	assert.NoError(t, guarded(context.Background()))
+	assert.Equal(t, 2, calls)
+
+	lock.set("telemetry-usage", "host-a", errors.New("connection refused"))
+
+	assert.EqualError(t, guarded(context.Background()), "connection refused")
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewHolder(t *testing.T) {
+	holder := NewHolder(random.NewMathematical())
+
+	assert.NotEqual(t, holder, NewHolder(random.NewMathematical()))
+	assert.GreaterOrEqual(t, len(holder), 8)
+
+	if i := strings.LastIndex(holder, "-"); i != -1 {
+		assert.Len(t, holder[i+1:], 8)
+	}
+}
+
+type testStorageProvider struct {
+	now, expires time.Time
+	released     bool
+}
+
+func (p *testStorageProvider) AcquireLeaderElectionLease(_ context.Context, _, _ string, now, expires time.Time) (acquired bool, err error) {
+	p.now, p.expires = now, expires
+
+	return true, nil
+}
+
+func (p *testStorageProvider) ReleaseLeaderElectionLease(_ context.Context, _, _ string) (err error) {
+	p.released = true
+
+	return nil
+}
+
+func TestStorageLock(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	provider := &testStorageProvider{}
+
+	lock := NewStorageLock(provider, clock.NewFixed(now))
+
+	acquired, err := lock.Acquire(context.Background(), "storage-cleanup", "host-a", time.Second*30)
+
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	assert.Equal(t, now, provider.now)
+	assert.Equal(t, now.Add(time.Second*30), provider.expires)
+
+	assert.NoError(t, lock.Release(context.Background(), "storage-cleanup", "host-a"))
+	assert.True(t, provider.released)
+}
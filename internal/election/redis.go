@@ -0,0 +1,130 @@
+package election
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewRedisLock returns a new RedisLock which holds the leases in the Redis server of the session configuration.
+func NewRedisLock(config *schema.SessionRedis, certPool *x509.CertPool) *RedisLock {
+	return &RedisLock{
+		client:  newRedisClient(config, certPool),
+		acquire: redis.NewScript(redisScriptAcquire),
+		release: redis.NewScript(redisScriptRelease),
+	}
+}
+
+// RedisLock is a Lock which holds the leases as keys which expire in Redis.
+type RedisLock struct {
+	client  redis.UniversalClient
+	acquire *redis.Script
+	release *redis.Script
+}
+
+// Acquire implements Lock.
+func (l *RedisLock) Acquire(ctx context.Context, name, holder string, lease time.Duration) (acquired bool, err error) {
+	var result int
+
+	if result, err = l.acquire.Run(ctx, l.client, []string{redisKeyPrefix + name}, holder, lease.Milliseconds()).Int(); err != nil {
+		return false, fmt.Errorf("error acquiring leader election lease with name '%s': %w", name, err)
+	}
+
+	return result == 1, nil
+}
+
+// Release implements Lock.
+func (l *RedisLock) Release(ctx context.Context, name, holder string) (err error) {
+	if err = l.release.Run(ctx, l.client, []string{redisKeyPrefix + name}, holder).Err(); err != nil {
+		return fmt.Errorf("error releasing leader election lease with name '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// Close closes the connections to Redis.
+func (l *RedisLock) Close() (err error) {
+	return l.client.Close()
+}
+
+func newRedisClient(config *schema.SessionRedis, certPool *x509.CertPool) redis.UniversalClient {
+	var tlsConfig *tls.Config
+
+	if config.TLS != nil {
+		tlsConfig = utils.NewTLSConfig(config.TLS, certPool)
+	}
+
+	// The leases are always written to the master when using sentinel or cluster, as such the route options which allow
+	// reading from the replicas are intentionally not used.
+	if config.Cluster != nil {
+		addrs := make([]string, 0)
+
+		if config.Host != "" {
+			addrs = append(addrs, fmt.Sprintf("%s:%d", strings.ToLower(config.Host), config.Port))
+		}
+
+		for _, node := range config.Cluster.Nodes {
+			addr := fmt.Sprintf("%s:%d", strings.ToLower(node.Host), node.Port)
+			if !utils.IsStringInSlice(addr, addrs) {
+				addrs = append(addrs, addr)
+			}
+		}
+
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        addrs,
+			MaxRedirects: config.Cluster.MaximumRedirects,
+			Username:     config.Username,
+			Password:     config.Password,
+			TLSConfig:    tlsConfig,
+		})
+	}
+
+	if config.HighAvailability != nil && config.HighAvailability.SentinelName != "" {
+		addrs := make([]string, 0)
+
+		if config.Host != "" {
+			addrs = append(addrs, fmt.Sprintf("%s:%d", strings.ToLower(config.Host), config.Port))
+		}
+
+		for _, node := range config.HighAvailability.Nodes {
+			addr := fmt.Sprintf("%s:%d", strings.ToLower(node.Host), node.Port)
+			if !utils.IsStringInSlice(addr, addrs) {
+				addrs = append(addrs, addr)
+			}
+		}
+
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       config.HighAvailability.SentinelName,
+			SentinelAddrs:    addrs,
+			SentinelUsername: config.HighAvailability.SentinelUsername,
+			SentinelPassword: config.HighAvailability.SentinelPassword,
+			Username:         config.Username,
+			Password:         config.Password,
+			DB:               config.DatabaseIndex,
+			TLSConfig:        tlsConfig,
+		})
+	}
+
+	network, addr := "tcp", fmt.Sprintf("%s:%d", config.Host, config.Port)
+
+	if config.Port == 0 {
+		network, addr = "unix", config.Host
+	}
+
+	return redis.NewClient(&redis.Options{
+		Network:   network,
+		Addr:      addr,
+		Username:  config.Username,
+		Password:  config.Password,
+		DB:        config.DatabaseIndex,
+		TLSConfig: tlsConfig,
+	})
+}
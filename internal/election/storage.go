@@ -0,0 +1,35 @@
+package election
+
+import (
+	"context"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewStorageLock returns a new StorageLock which holds the leases in the leader_election_leases table of the storage
+// provider.
+func NewStorageLock(provider StorageProvider, clock clock.Provider) *StorageLock {
+	return &StorageLock{
+		provider: provider,
+		clock:    clock,
+	}
+}
+
+// StorageLock is a Lock which holds the leases using the storage provider.
+type StorageLock struct {
+	provider StorageProvider
+	clock    clock.Provider
+}
+
+// Acquire implements Lock.
+func (l *StorageLock) Acquire(ctx context.Context, name, holder string, lease time.Duration) (acquired bool, err error) {
+	now := l.clock.Now()
+
+	return l.provider.AcquireLeaderElectionLease(ctx, name, holder, now, now.Add(lease))
+}
+
+// Release implements Lock.
+func (l *StorageLock) Release(ctx context.Context, name, holder string) (err error) {
+	return l.provider.ReleaseLeaderElectionLease(ctx, name, holder)
+}
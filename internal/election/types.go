@@ -0,0 +1,27 @@
+package election
+
+import (
+	"context"
+	"time"
+)
+
+// Lock is implemented by the backends which hold the leader election leases.
+type Lock interface {
+	// Acquire acquires or renews the lease with the given name for the holder for the lease duration, returning true
+	// if the holder holds the lease.
+	Acquire(ctx context.Context, name, holder string, lease time.Duration) (acquired bool, err error)
+
+	// Release releases the lease with the given name if it's held by the holder.
+	Release(ctx context.Context, name, holder string) (err error)
+}
+
+// Controller represents the required methods to support running a controller.
+type Controller interface {
+	Run(ctx context.Context)
+}
+
+// StorageProvider describes the storage provider methods required by the StorageLock.
+type StorageProvider interface {
+	AcquireLeaderElectionLease(ctx context.Context, name, holder string, now, expires time.Time) (acquired bool, err error)
+	ReleaseLeaderElectionLease(ctx context.Context, name, holder string) (err error)
+}
@@ -27,6 +27,10 @@ var (
 	headerRemoteGroups    = []byte("Remote-Groups")
 	headerRemoteName      = []byte("Remote-Name")
 	headerRemoteEmail     = []byte("Remote-Email")
+
+	headerLocation    = []byte(fasthttp.HeaderLocation)
+	headerGRPCStatus  = []byte("Grpc-Status")
+	headerGRPCMessage = []byte("Grpc-Message")
 )
 
 const (
@@ -34,7 +38,14 @@ const (
 )
 
 var (
-	headerValueAuthenticateBasic = []byte(`Basic realm="Authorization Required"`)
+	headerValueAuthenticateBasic     = []byte(`Basic realm="Authorization Required"`)
+	headerValueAuthenticateNegotiate = []byte("Negotiate")
+)
+
+// gRPC status codes as defined by https://grpc.github.io/grpc/core/md_doc_statuscodes.html.
+const (
+	grpcStatusPermissionDenied = 7
+	grpcStatusUnauthenticated  = 16
 )
 
 const (
@@ -45,6 +56,15 @@ const (
 	queryArgConsentID  = "consent_id"
 	queryArgWorkflow   = "workflow"
 	queryArgWorkflowID = "workflow_id"
+	queryArgLimit      = "limit"
+	queryArgPage       = "page"
+	queryArgOrder      = "order"
+	queryArgType       = "type"
+	queryArgClientID   = "client_id"
+	queryArgUserCode   = "user_code"
+	queryArgCode       = "code"
+	queryArgUV         = "uv"
+	queryArgMediation  = "mediation"
 )
 
 var (
@@ -52,6 +72,38 @@ var (
 	qryArgRD        = []byte(queryArgRD)
 	qryArgAuth      = []byte(queryArgAuth)
 	qryArgConsentID = []byte(queryArgConsentID)
+	qryArgUserCode  = []byte(queryArgUserCode)
+	qryArgUV        = []byte(queryArgUV)
+	qryArgMediation = []byte(queryArgMediation)
+)
+
+const (
+	appPasswordPrefix                   = "authelia_ap_"  //nolint:gosec
+	clientRegistrationAccessTokenPrefix = "authelia_rat_" //nolint:gosec
+	mobileAccessTokenPrefix             = "authelia_mat_" //nolint:gosec
+	mobileRefreshTokenPrefix            = "authelia_mrt_" //nolint:gosec
+)
+
+const (
+	mobilePushProviderAPNS = "apns"
+	mobilePushProviderFCM  = "fcm"
+
+	mobileDeviceNameMaxLength     = 100
+	mobileDevicePlatformMaxLength = 20
+	mobilePushTokenMaxLength      = 512
+)
+
+const (
+	userSessionIndexUserAgentMaxLength = 512
+)
+
+var (
+	headerValueAuthenticateBearer = []byte(`Bearer error="invalid_token"`)
+)
+
+const (
+	paginationLimitDefault = 20
+	paginationLimitMaximum = 100
 )
 
 var (
@@ -70,11 +122,26 @@ const (
 	messageSecurityKeyDuplicateName              = "Another one of your security keys is already registered with that display name."
 	messageUnableToResetPassword                 = "Unable to reset your password."
 	messageMFAValidationFailed                   = "Authentication failed, please retry later."
+	messageUnableToGenerateRecoveryCodes         = "Unable to generate recovery codes."
 	messagePasswordWeak                          = "Your supplied password does not meet the password policy requirements."
+	messageUnableToRegisterPhoneNumber           = "Unable to register your phone number."
+	messageUnableToDeletePhoneNumber             = "Unable to delete your phone number."
+	messageUnableToSendOneTimeCode               = "Unable to send the one-time code, please retry later."
 )
 
 const (
 	workflowOpenIDConnect = "openid_connect"
+	workflowSAML          = "saml"
+)
+
+const (
+	passkeyMediationConditional = "conditional"
+)
+
+const (
+	recoveryCodesCount      = 10
+	recoveryCodeLength      = 10
+	recoveryCodeGroupLength = 5
 )
 
 const (
@@ -91,7 +158,8 @@ const (
 )
 
 const (
-	logFmtAuthzRedirect = "Access to %s (method %s) is not authorized to user %s, responding with status code %d with location redirect to %s"
+	logFmtAuthzRedirect  = "Access to %s (method %s) is not authorized to user %s, responding with status code %d with location redirect to %s"
+	logFmtAuthzChallenge = "Access to %s (method %s) is not authorized to user %s, responding with status code %d with challenge for non-browser client with location %s"
 
 	logFmtAuthorizationPrefix = "Authorization Request with id '%s' on client with id '%s' "
 
@@ -130,6 +198,8 @@ const (
 	deny   = "deny"
 	enroll = "enroll"
 	auth   = "auth"
+
+	waiting = "waiting"
 )
 
 const ldapPasswordComplexityCode = "0000052D."
@@ -151,5 +221,13 @@ const (
 )
 
 var (
-	errUserAnonymous = errors.New("user is anonymous")
+	errUserAnonymous      = errors.New("user is anonymous")
+	errMobileTokenInvalid = errors.New("the token is missing, invalid, expired, or revoked")
+)
+
+const (
+	scimMaxResults = 100
+
+	scimEmailTypeWork  = "work"
+	scimEmailTypeOther = "other"
 )
@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// AdminUserSessionsGET returns the active sessions of the user of the admin API request.
+func AdminUserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		username   string
+		pagination model.Pagination
+		sessions   []indexedUserSession
+		err        error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred listing the sessions of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if pagination, err = getPagination(ctx); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing the sessions of user '%s': error occurred parsing the pagination", username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if sessions, err = loadIndexedUserSessions(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing the sessions of user '%s': error occurred loading the sessions", username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	// The sessions are only known after they're loaded from the session provider so they're sorted and paginated
+	// with the most recent activity first after they're loaded.
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Session.LastActivity > sessions[j].Session.LastActivity
+	})
+
+	sessions = model.Paginate(sessions, pagination)
+
+	response := make([]AdminUserSessionResponse, len(sessions))
+
+	for i, s := range sessions {
+		response[i] = AdminUserSessionResponse{
+			ID:                  s.Index.PublicID.String(),
+			Domain:              s.Index.Domain,
+			AuthenticationLevel: s.Session.AuthenticationLevel.String(),
+			LastActivity:        time.Unix(s.Session.LastActivity, 0).UTC(),
+		}
+
+		if s.Index.RemoteIP.IP != nil {
+			response[i].RemoteIP = s.Index.RemoteIP.IP.String()
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing the sessions of user '%s': %s", username, errStrRespBody)
+	}
+}
+
+// AdminUserSessionsDELETE revokes all of the active sessions of the user of the admin API request.
+func AdminUserSessionsDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username string
+		sessions []indexedUserSession
+		err      error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred revoking the sessions of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if sessions, err = loadIndexedUserSessions(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking the sessions of user '%s': error occurred loading the sessions", username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	response := AdminUserSessionsRevokeResponse{}
+
+	for _, s := range sessions {
+		if err = revokeIndexedUserSession(ctx, s.Index); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred revoking the sessions of user '%s': error occurred revoking the session with id '%s'", username, s.Index.PublicID)
+
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		ctx.AuditEvent(audit.EventTypeSessionRevoke, audit.ResultSuccess, username, map[string]any{"id": s.Index.PublicID.String(), "domain": s.Index.Domain, "admin": true})
+
+		response.Revoked++
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking the sessions of user '%s': %s", username, errStrRespBody)
+	}
+}
+
+// AdminUserSessionDELETE revokes an individual active session of the user of the admin API request.
+func AdminUserSessionDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username string
+		publicID uuid.UUID
+		index    *model.UserSessionIndex
+		err      error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred revoking a session of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	value, _ := ctx.UserValue("id").(string)
+
+	if publicID, err = uuid.Parse(value); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking a session of user '%s': error occurred parsing the session id", username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if index, err = ctx.Providers.StorageProvider.LoadUserSessionIndex(ctx, username, publicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking the session with id '%s' of user '%s': error occurred loading the session index from the storage backend", publicID, username)
+
+		if errors.Is(err, storage.ErrNoUserSessionIndex) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = revokeIndexedUserSession(ctx, *index); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking the session with id '%s' of user '%s'", publicID, username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeSessionRevoke, audit.ResultSuccess, username, map[string]any{"id": publicID.String(), "domain": index.Domain, "admin": true})
+
+	ctx.ReplyOK()
+}
+
+// AdminUserBanDELETE lifts the ban of the user of the admin API request.
+func AdminUserBanDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username string
+		err      error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred lifting the ban of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.Regulator.Unban(ctx, username, string(ctx.RequestURI()), string(ctx.Method())); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred lifting the ban of user '%s': error occurred saving the authentication log to the storage backend", username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// AdminUserTOTPDELETE deletes the TOTP configuration of the user of the admin API request.
+func AdminUserTOTPDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username string
+		err      error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred deleting the TOTP configuration of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if _, err = ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the TOTP configuration of user '%s': error occurred loading the configuration from the storage backend", username)
+
+		if errors.Is(err, storage.ErrNoTOTPConfiguration) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteTOTPConfiguration(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the TOTP configuration of user '%s': error occurred deleting the configuration from the storage backend", username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeTOTPDelete, audit.ResultSuccess, username, map[string]any{"admin": true})
+
+	ctx.ReplyOK()
+}
+
+// AdminUserWebAuthnDELETE deletes all of the WebAuthn credentials of the user of the admin API request.
+func AdminUserWebAuthnDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username    string
+		credentials []model.WebAuthnCredential
+		err         error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred deleting the WebAuthn credentials of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if credentials, err = ctx.Providers.StorageProvider.LoadWebAuthnCredentialsByUsername(ctx, "", username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the WebAuthn credentials of user '%s': error occurred loading the credentials from the storage backend", username)
+
+		if errors.Is(err, storage.ErrNoWebAuthnCredential) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteWebAuthnCredentialByUsername(ctx, username, ""); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the WebAuthn credentials of user '%s': error occurred deleting the credentials from the storage backend", username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	for _, credential := range credentials {
+		ctx.AuditEvent(audit.EventTypeWebAuthnDelete, audit.ResultSuccess, username, map[string]any{"description": credential.Description, "admin": true})
+	}
+
+	ctx.ReplyOK()
+}
+
+// AdminUserWebAuthnCredentialDELETE deletes an individual WebAuthn credential of the user of the admin API request
+// given the key id of the credential.
+func AdminUserWebAuthnCredentialDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		username    string
+		credentials []model.WebAuthnCredential
+		err         error
+	)
+
+	if username, err = getAdminUsernameFromContext(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred deleting a WebAuthn credential of a user")
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	kid, _ := ctx.UserValue("kid").(string)
+
+	if len(kid) == 0 {
+		ctx.Logger.WithError(fmt.Errorf("the key id wasn't provided")).Errorf("Error occurred deleting a WebAuthn credential of user '%s'", username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if credentials, err = ctx.Providers.StorageProvider.LoadWebAuthnCredentialsByUsername(ctx, "", username); err != nil && !errors.Is(err, storage.ErrNoWebAuthnCredential) {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the WebAuthn credential with key id '%s' of user '%s': error occurred loading the credentials from the storage backend", kid, username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	var credential *model.WebAuthnCredential
+
+	for i := range credentials {
+		if credentials[i].KID.String() == kid {
+			credential = &credentials[i]
+
+			break
+		}
+	}
+
+	if credential == nil {
+		ctx.Logger.WithError(storage.ErrNoWebAuthnCredential).Errorf("Error occurred deleting the WebAuthn credential with key id '%s' of user '%s'", kid, username)
+
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteWebAuthnCredential(ctx, kid); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting the WebAuthn credential with key id '%s' of user '%s': error occurred deleting the credential from the storage backend", kid, username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeWebAuthnDelete, audit.ResultSuccess, username, map[string]any{"description": credential.Description, "admin": true})
+
+	ctx.ReplyOK()
+}
+
+func getAdminUsernameFromContext(ctx *middlewares.AutheliaCtx) (username string, err error) {
+	var ok bool
+
+	if username, ok = ctx.UserValue("user").(string); !ok || len(username) == 0 {
+		return "", fmt.Errorf("the username wasn't provided")
+	}
+
+	return username, nil
+}
+
+// revokeIndexedUserSession destroys the session referred to by the user session index and removes it from the index.
+// The revocation is saved to the storage backend so the other replicas also refuse the session if a request which was
+// in flight when it was revoked saves it again.
+func revokeIndexedUserSession(ctx *middlewares.AutheliaCtx, index model.UserSessionIndex) (err error) {
+	var expiresAt time.Time
+
+	if expiresAt, err = ctx.Providers.SessionProvider.RevokeSessionByID(index.SessionID); err != nil {
+		return fmt.Errorf("error occurred destroying the session: %w", err)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveUserSessionRevocation(ctx, index.SessionID, ctx.Clock.Now(), expiresAt); err != nil {
+		return fmt.Errorf("error occurred saving the session revocation to the storage backend: %w", err)
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteUserSessionIndex(ctx, index.SessionID); err != nil {
+		return fmt.Errorf("error occurred deleting the session index from the storage backend: %w", err)
+	}
+
+	return nil
+}
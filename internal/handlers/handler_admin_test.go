@@ -0,0 +1,481 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestAdminUserSessionsGET(t *testing.T) {
+	publicID := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleMissingUsername",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred listing the sessions of a user", "the username wasn't provided")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("user", testUsername)
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred listing the sessions of user 'john': error occurred loading the sessions", "bad block")
+			},
+		},
+		{
+			"ShouldHandleSessions",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("user", testUsername)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return([]model.UserSessionIndex{
+						{PublicID: uuid.MustParse("0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3"), Username: testUsername, Domain: "example.com", SessionID: []byte("stale")},
+						{PublicID: publicID, Username: testUsername, Domain: "example.com", RemoteIP: model.NewNullIP(net.ParseIP("192.168.0.1")), SessionID: id},
+					}, nil),
+					mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, []byte("stale")).Return(nil),
+				)
+			},
+			`{"status":"OK","data":[{"id":"5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e","domain":"example.com","remote_ip":"192.168.0.1","authentication_level":"one_factor","last_activity":"2023-11-14T22:13:20Z"}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleSessionsPastLastPage",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("user", testUsername)
+				mock.Ctx.QueryArgs().Set(queryArgPage, "1")
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return([]model.UserSessionIndex{
+					{PublicID: publicID, Username: testUsername, Domain: "example.com", SessionID: id},
+				}, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleBadPagination",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("user", testUsername)
+				mock.Ctx.QueryArgs().Set(queryArgLimit, "0")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred listing the sessions of user 'john': error occurred parsing the pagination", "the 'limit' query argument must be between 1 and 100 but it's 0")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			AdminUserSessionsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestAdminUserSessionsDELETE(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+	mock.Ctx.SetUserValue("user", testUsername)
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return([]model.UserSessionIndex{
+			{PublicID: uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e"), Username: testUsername, Domain: "example.com", SessionID: id},
+		}, nil),
+		mock.StorageMock.EXPECT().SaveUserSessionRevocation(mock.Ctx, id, mock.Clock.Now(), gomock.Any()).Return(nil),
+		mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, id).Return(nil),
+	)
+
+	AdminUserSessionsDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, `{"status":"OK","data":{"revoked":1}}`, string(mock.Ctx.Response.Body()))
+
+	_, found, err := mock.Ctx.Providers.SessionProvider.LoadUserSessionByID(id)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.True(t, mock.Ctx.Providers.SessionProvider.Revocations().IsRevoked(id))
+}
+
+func TestAdminUserSessionDELETE(t *testing.T) {
+	publicID := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleInvalidID",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("user", testUsername)
+				mock.Ctx.SetUserValue("id", "abc")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking a session of user 'john': error occurred parsing the session id", "invalid UUID length: 3")
+			},
+		},
+		{
+			"ShouldHandleNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("user", testUsername)
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(nil, storage.ErrNoUserSessionIndex)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking the session with id '5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e' of user 'john': error occurred loading the session index from the storage backend", "no user session index found")
+			},
+		},
+		{
+			"ShouldRevokeSession",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("user", testUsername)
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(&model.UserSessionIndex{PublicID: publicID, Username: testUsername, Domain: "example.com", SessionID: id}, nil),
+					mock.StorageMock.EXPECT().SaveUserSessionRevocation(mock.Ctx, id, mock.Clock.Now(), gomock.Any()).Return(nil),
+					mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, id).Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			AdminUserSessionDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestAdminUserBanDELETE(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	mock.Ctx.SetUserValue("user", testUsername)
+
+	mock.StorageMock.EXPECT().AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ any, attempt model.AuthenticationAttempt) error {
+			assert.True(t, attempt.Successful)
+			assert.Equal(t, testUsername, attempt.Username)
+			assert.Equal(t, regulation.AuthTypeUnban, attempt.Type)
+
+			return nil
+		})
+
+	AdminUserBanDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, `{"status":"OK"}`, string(mock.Ctx.Response.Body()))
+}
+
+func TestAdminUserTOTPDELETE(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldHandleNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadTOTPConfiguration(mock.Ctx, testUsername).Return(nil, storage.ErrNoTOTPConfiguration)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadTOTPConfiguration(mock.Ctx, testUsername).Return(&model.TOTPConfiguration{}, nil),
+					mock.StorageMock.EXPECT().DeleteTOTPConfiguration(mock.Ctx, testUsername).Return(fmt.Errorf("bad block")),
+				)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusInternalServerError,
+		},
+		{
+			"ShouldDelete",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadTOTPConfiguration(mock.Ctx, testUsername).Return(&model.TOTPConfiguration{}, nil),
+					mock.StorageMock.EXPECT().DeleteTOTPConfiguration(mock.Ctx, testUsername).Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.SetUserValue("user", testUsername)
+
+			tc.setup(t, mock)
+
+			AdminUserTOTPDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestAdminUserWebAuthnDELETE(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldHandleNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadWebAuthnCredentialsByUsername(mock.Ctx, "", testUsername).Return(nil, storage.ErrNoWebAuthnCredential)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+		},
+		{
+			"ShouldDelete",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadWebAuthnCredentialsByUsername(mock.Ctx, "", testUsername).Return([]model.WebAuthnCredential{{ID: 1, Username: testUsername, Description: "yubikey"}}, nil),
+					mock.StorageMock.EXPECT().DeleteWebAuthnCredentialByUsername(mock.Ctx, testUsername, "").Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.SetUserValue("user", testUsername)
+
+			tc.setup(t, mock)
+
+			AdminUserWebAuthnDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestAdminUserWebAuthnCredentialDELETE(t *testing.T) {
+	kid := model.NewBase64([]byte("abc/def"))
+
+	testCases := []struct {
+		name           string
+		kid            string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldHandleMissingKeyID",
+			"",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+		},
+		{
+			"ShouldHandleCredentialOfAnotherUser",
+			kid.String(),
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadWebAuthnCredentialsByUsername(mock.Ctx, "", testUsername).Return(nil, storage.ErrNoWebAuthnCredential)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+		},
+		{
+			"ShouldDelete",
+			kid.String(),
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadWebAuthnCredentialsByUsername(mock.Ctx, "", testUsername).Return([]model.WebAuthnCredential{{ID: 1, Username: testUsername, Description: "yubikey", KID: kid}}, nil),
+					mock.StorageMock.EXPECT().DeleteWebAuthnCredential(mock.Ctx, kid.String()).Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.SetUserValue("user", testUsername)
+			mock.Ctx.SetUserValue("kid", tc.kid)
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			AdminUserWebAuthnCredentialDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestIndexUserSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Server.Admin.Enabled = true
+	mock.Ctx.Configuration.Server.Admin.EnableAPI = true
+
+	id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+	userSession, err := mock.Ctx.GetSession()
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return(nil, nil),
+		mock.StorageMock.EXPECT().SaveUserSessionIndex(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ any, index model.UserSessionIndex) error {
+				assert.Equal(t, testUsername, index.Username)
+				assert.Equal(t, "example.com", index.Domain)
+				assert.Equal(t, id, index.SessionID)
+				assert.NotEqual(t, uuid.Nil, index.PublicID)
+
+				return nil
+			}),
+		mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, id).Return(nil),
+	)
+
+	indexUserSession(mock.Ctx, userSession)
+
+	unindexUserSession(mock.Ctx, getUserSessionID(mock.Ctx))
+}
+
+func TestIndexUserSessionShouldSkipWhenDisabled(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+	userSession, err := mock.Ctx.GetSession()
+	require.NoError(t, err)
+
+	indexUserSession(mock.Ctx, userSession)
+
+	unindexUserSession(mock.Ctx, getUserSessionID(mock.Ctx))
+}
+
+func setAdminTestSession(t *testing.T, mock *mocks.MockAutheliaCtx, lastActivity time.Time) (id []byte) {
+	us, err := mock.Ctx.GetSession()
+
+	require.NoError(t, err)
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+	us.LastActivity = lastActivity.Unix()
+
+	require.NoError(t, mock.Ctx.SaveSession(us))
+
+	id = getUserSessionID(mock.Ctx)
+
+	require.NotEmpty(t, id)
+
+	return id
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// AppPasswordsGET returns the app passwords of the current user.
+func AppPasswordsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		passwords   []model.AppPassword
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading app passwords: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred loading app passwords")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if passwords, err = ctx.Providers.StorageProvider.LoadAppPasswords(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading app passwords for user '%s': error occurred loading the app passwords from the storage backend", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if passwords == nil {
+		passwords = []model.AppPassword{}
+	}
+
+	if err = ctx.SetJSONBody(passwords); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading app passwords for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// AppPasswordsPOST generates a new app password for the current user which is permitted to authenticate the user to the
+// domains in the body. The raw password is only returned in this response.
+func AppPasswordsPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON bodyCreateAppPasswordRequest
+
+		userSession session.UserSession
+		domains     []string
+
+		err error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating app password: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred generating app password")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = json.Unmarshal(ctx.PostBody(), &bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating app password for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if len(bodyJSON.Name) == 0 || len(bodyJSON.Name) > 100 {
+		ctx.Logger.WithError(fmt.Errorf("name must be between 1 and 100 characters but it's %d characters", len(bodyJSON.Name))).Errorf("Error occurred generating app password for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if domains, err = appPasswordDomains(bodyJSON.Domains); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating app password for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	password := model.NewAppPassword(uuid.New(), userSession.Username, bodyJSON.Name, appPasswordPrefix+ctx.Providers.Random.StringCustom(32, random.CharSetAlphaNumeric), domains, ctx.Clock.Now())
+
+	if err = ctx.Providers.StorageProvider.SaveAppPassword(ctx, password); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating app password for user '%s': error occurred saving the app password to the storage backend", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Generated app password with id '%s' and name '%s' for user '%s'", password.PublicID, password.Name, userSession.Username)
+
+	ctx.AuditEvent(audit.EventTypeAppPasswordGenerate, audit.ResultSuccess, userSession.Username, map[string]any{"id": password.PublicID.String(), "name": password.Name, "domains": domains})
+
+	if err = ctx.SetJSONBody(AppPasswordResponse{AppPassword: password, Password: password.Password}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating app password for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// AppPasswordDELETE deletes a specific app password of the current user.
+func AppPasswordDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		id          uuid.UUID
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting app password: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred deleting app password")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	value, _ := ctx.UserValue("id").(string)
+
+	if id, err = uuid.Parse(value); err != nil {
+		ctx.Logger.WithError(fmt.Errorf("the id '%s' isn't a valid id: %w", value, err)).Errorf("Error occurred deleting app password for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteAppPassword(ctx, userSession.Username, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting app password with id '%s' for user '%s'", id, userSession.Username)
+
+		if errors.Is(err, storage.ErrNoAppPassword) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeAppPasswordDelete, audit.ResultSuccess, userSession.Username, map[string]any{"id": id.String()})
+
+	ctx.ReplyOK()
+}
+
+// appPasswordDomains returns the normalized domains an app password is scoped to, each domain is either a domain name or
+// a domain name with the '*.' prefix which matches any of its subdomains.
+func appPasswordDomains(values []string) (domains []string, err error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one domain must be provided")
+	}
+
+	for _, value := range values {
+		domain := strings.ToLower(strings.TrimSpace(value))
+
+		if len(domain) == 0 || len(domain) > 253 || strings.ContainsAny(domain, "|/: ") || strings.Contains(strings.TrimPrefix(domain, "*."), "*") {
+			return nil, fmt.Errorf("the domain '%s' isn't a valid domain", value)
+		}
+
+		domains = append(domains, domain)
+	}
+
+	return domains, nil
+}
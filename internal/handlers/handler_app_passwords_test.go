@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestAppPasswordsGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userSession    session.UserSession
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			session.UserSession{},
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading app passwords", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadAppPasswords(mock.Ctx, testUsername).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading app passwords for user 'john': error occurred loading the app passwords from the storage backend", "bad block")
+			},
+		},
+		{
+			"ShouldHandleNoAppPasswords",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadAppPasswords(mock.Ctx, testUsername).Return(nil, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleAppPasswords",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadAppPasswords(mock.Ctx, testUsername).Return([]model.AppPassword{{ID: 1, PublicID: uuid.MustParse("d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b"), Username: testUsername, Name: "Mail", Domains: []string{"mail.example.com"}, CreatedAt: time.Unix(1700000000, 0).UTC()}}, nil)
+			},
+			`{"status":"OK","data":[{"id":"d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b","name":"Mail","domains":["mail.example.com"],"created_at":"2023-11-14T22:13:20Z"}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldLoadAppPasswordsOfCanonicalUsername",
+			session.UserSession{},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.UserProviderMock.EXPECT().GetDetails("John").Return(&authentication.UserDetails{Username: testUsername}, nil)
+
+				details, err := mock.Ctx.Providers.UserProvider.GetDetails("John")
+				require.NoError(t, err)
+
+				us, err := mock.Ctx.GetSession()
+				require.NoError(t, err)
+
+				us.SetOneFactor(time.Unix(1700000000, 0), details, false)
+
+				require.NoError(t, mock.Ctx.SaveSession(us))
+
+				mock.StorageMock.EXPECT().LoadAppPasswords(mock.Ctx, gomock.Eq(testUsername)).Return(nil, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtxWithUserSession(t, tc.userSession)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			AppPasswordsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestAppPasswordsPOST(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userSession    session.UserSession
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		body           string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			session.UserSession{},
+			nil,
+			`{"name":"Mail","domains":["mail.example.com"]}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating app password", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleBadBody",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			nil,
+			`{"name":`,
+			fasthttp.StatusBadRequest,
+			nil,
+		},
+		{
+			"ShouldHandleEmptyName",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			nil,
+			`{"name":"","domains":["mail.example.com"]}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating app password for user 'john'", "name must be between 1 and 100 characters but it's 0 characters")
+			},
+		},
+		{
+			"ShouldHandleNoDomains",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			nil,
+			`{"name":"Mail","domains":[]}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating app password for user 'john'", "at least one domain must be provided")
+			},
+		},
+		{
+			"ShouldHandleInvalidDomain",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			nil,
+			`{"name":"Mail","domains":["mail.*.example.com"]}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating app password for user 'john'", "the domain 'mail.*.example.com' isn't a valid domain")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().SaveAppPassword(mock.Ctx, gomock.Any()).Return(fmt.Errorf("bad block"))
+			},
+			`{"name":"Mail","domains":["mail.example.com"]}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating app password for user 'john': error occurred saving the app password to the storage backend", "bad block")
+			},
+		},
+		{
+			"ShouldGenerate",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().SaveAppPassword(mock.Ctx, gomock.Any()).DoAndReturn(func(_ any, password model.AppPassword) error {
+					assert.Equal(t, testUsername, password.Username)
+					assert.Equal(t, "Mail", password.Name)
+					assert.Equal(t, model.StringSlicePipeDelimited{"mail.example.com", "*.example.org"}, password.Domains)
+					assert.True(t, strings.HasPrefix(password.Password, appPasswordPrefix))
+					assert.Len(t, password.Password, len(appPasswordPrefix)+32)
+
+					return nil
+				})
+			},
+			`{"name":"Mail","domains":["Mail.Example.com"," *.example.org "]}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				var response struct {
+					Status string `json:"status"`
+					Data   struct {
+						AppPassword model.AppPasswordJSON `json:"app_password"`
+						Password    string                `json:"password"`
+					} `json:"data"`
+				}
+
+				require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &response))
+
+				assert.Equal(t, "OK", response.Status)
+				assert.Equal(t, "Mail", response.Data.AppPassword.Name)
+				assert.Equal(t, []string{"mail.example.com", "*.example.org"}, response.Data.AppPassword.Domains)
+				assert.True(t, strings.HasPrefix(response.Data.Password, appPasswordPrefix))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtxWithUserSession(t, tc.userSession)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			AppPasswordsPOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestAppPasswordDELETE(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userSession    session.UserSession
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			session.UserSession{},
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred deleting app password", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleInvalidID",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("id", "abc")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred deleting app password for user 'john'", "the id 'abc' isn't a valid id: invalid UUID length: 3")
+			},
+		},
+		{
+			"ShouldHandleNotFound",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("id", "d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")
+
+				mock.StorageMock.EXPECT().DeleteAppPassword(mock.Ctx, testUsername, uuid.MustParse("d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")).Return(storage.ErrNoAppPassword)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred deleting app password with id 'd4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b' for user 'john'", "no app password found")
+			},
+		},
+		{
+			"ShouldDelete",
+			session.UserSession{Username: testUsername, AuthenticationLevel: authentication.OneFactor},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("id", "d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")
+
+				mock.StorageMock.EXPECT().DeleteAppPassword(mock.Ctx, testUsername, uuid.MustParse("d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")).Return(nil)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtxWithUserSession(t, tc.userSession)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			AppPasswordDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
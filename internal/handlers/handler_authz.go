@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/go-webauthn/webauthn/protocol"
+
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/middlewares"
@@ -62,12 +65,13 @@ func (authz *Authz) Handler(ctx *middlewares.AutheliaCtx) {
 	authn.Object = object
 	authn.Method = friendlyMethod(authn.Object.Method)
 
-	ruleHasSubject, required := ctx.Providers.Authorizer.GetRequiredLevel(
+	requirements := ctx.Providers.Authorizer.GetRequirements(
 		authorization.Subject{
-			Username: authn.Details.Username,
-			Groups:   authn.Details.Groups,
-			ClientID: authn.ClientID,
-			IP:       ctx.RemoteIP(),
+			Username:      authn.Details.Username,
+			Groups:        authn.Details.Groups,
+			ClientID:      authn.ClientID,
+			IP:            ctx.RemoteIP(),
+			ManagedDevice: isManagedDevice(ctx, authn.Details.Username),
 		},
 		object,
 	)
@@ -75,14 +79,14 @@ func (authz *Authz) Handler(ctx *middlewares.AutheliaCtx) {
 	if err != nil {
 		authn.Object = object
 
-		if !ruleHasSubject && required != authorization.Bypass {
+		if !requirements.HasSubjects && requirements.Level != authorization.Bypass {
 			switch {
 			case strategy == nil:
 				ctx.ReplyUnauthorized()
 			case strategy.HeaderStrategy():
 				ctx.Logger.WithError(err).Error("Error occurred while attempting to authenticate a request")
 
-				strategy.HandleUnauthorized(ctx, authn, authz.getRedirectionURL(&object, autheliaURL))
+				strategy.HandleUnauthorized(ctx, authn, authz.getRedirectionURL(&object, autheliaURL, requirements))
 
 				return
 			}
@@ -91,20 +95,51 @@ func (authz *Authz) Handler(ctx *middlewares.AutheliaCtx) {
 		ctx.Logger.WithError(err).Debug("Error occurred while attempting to authenticate a request but the matched rule was a bypass rule")
 	}
 
-	switch isAuthzResult(authn.Level, required, ruleHasSubject) {
+	result := isAuthzResult(authn.Level, requirements.Level, requirements.HasSubjects)
+
+	if result == AuthzResultAuthorized && !isAuthzUserVerificationSufficient(authn, requirements) {
+		ctx.Logger.Debugf("Access to '%s' requires the user '%s' to complete the second factor with WebAuthn user verification", object.URL.String(), authn.Username)
+
+		result = AuthzResultUnauthorized
+	}
+
+	ctx.RecordAuthzDecision(authzRuleMetricName(requirements.Position), requirements.Level.String(), result.String())
+
+	metadata := map[string]any{
+		"decision":   result.String(),
+		"rule":       authzRuleMetricName(requirements.Position),
+		"policy":     requirements.Level.String(),
+		"target_url": object.URL.String(),
+		"method":     object.Method,
+	}
+
+	if requirements.UserVerification {
+		metadata["user_verified"] = authn.UserVerified
+	}
+
+	ctx.AuditEvent(audit.EventTypeAuthorizationDecision, audit.NewResult(result == AuthzResultAuthorized), authn.Username, metadata)
+
+	switch result {
 	case AuthzResultForbidden:
 		ctx.Logger.Infof("Access to '%s' is forbidden to user '%s'", object.URL.String(), authn.Username)
 		ctx.ReplyForbidden()
+
+		if ctx.IsGRPC() {
+			handleAuthzResponseGRPC(ctx, grpcStatusPermissionDenied)
+		}
 	case AuthzResultUnauthorized:
 		var handler HandlerAuthzUnauthorized
 
-		if strategy != nil {
+		switch {
+		case strategy != nil:
 			handler = strategy.HandleUnauthorized
-		} else {
+		case isAuthzChallengeStatus(ctx, requirements.Challenge):
+			handler = handleAuthzUnauthorizedChallengeStatus
+		default:
 			handler = authz.handleUnauthorized
 		}
 
-		handler(ctx, authn, authz.getRedirectionURL(&object, autheliaURL))
+		handler(ctx, authn, authz.getRedirectionURL(&object, autheliaURL, requirements))
 	case AuthzResultAuthorized:
 		authz.handleAuthorized(ctx, authn)
 	}
@@ -134,7 +169,7 @@ func (authz *Authz) getAutheliaURL(ctx *middlewares.AutheliaCtx, provider *sessi
 	return nil, fmt.Errorf("authelia url lookup failed")
 }
 
-func (authz *Authz) getRedirectionURL(object *authorization.Object, autheliaURL *url.URL) (redirectionURL *url.URL) {
+func (authz *Authz) getRedirectionURL(object *authorization.Object, autheliaURL *url.URL, requirements authorization.Requirements) (redirectionURL *url.URL) {
 	if autheliaURL == nil {
 		return nil
 	}
@@ -153,13 +188,17 @@ func (authz *Authz) getRedirectionURL(object *authorization.Object, autheliaURL
 		qry.Set(queryArgRM, object.Method)
 	}
 
+	if requirements.UserVerification {
+		qry.Set(queryArgUV, string(protocol.VerificationRequired))
+	}
+
 	redirectionURL.RawQuery = qry.Encode()
 
 	return redirectionURL
 }
 
 func (authz *Authz) authn(ctx *middlewares.AutheliaCtx, provider *session.Session, object *authorization.Object) (authn *Authn, strategy AuthnStrategy, err error) {
-	for _, strategy = range authz.strategies {
+	for _, strategy = range authz.getStrategies(ctx, object) {
 		if authn, err = strategy.Get(ctx, provider, object); err != nil {
 			// Ensure an error returned can never result in an authenticated user.
 			authn.Level = authentication.NotAuthenticated
@@ -185,3 +224,15 @@ func (authz *Authz) authn(ctx *middlewares.AutheliaCtx, provider *session.Sessio
 
 	return authn, nil, nil
 }
+
+// getStrategies returns the authn strategies of the first rule which matches the object if it configures any, otherwise
+// it returns the authn strategies of this Authz.
+func (authz *Authz) getStrategies(ctx *middlewares.AutheliaCtx, object *authorization.Object) (strategies []AuthnStrategy) {
+	if config := ctx.Providers.Authorizer.GetAuthnStrategies(authorization.Subject{IP: ctx.RemoteIP()}, *object); len(config) != 0 {
+		if strategies = authz.ruleStrategies.Get(config); len(strategies) != 0 {
+			return strategies
+		}
+	}
+
+	return authz.strategies
+}
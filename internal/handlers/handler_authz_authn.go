@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -136,8 +138,9 @@ func (s *CookieSessionAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, provider
 			Emails:      userSession.Emails,
 			Groups:      userSession.Groups,
 		},
-		Level: userSession.AuthenticationLevel,
-		Type:  AuthnTypeCookie,
+		Level:        userSession.AuthenticationLevel,
+		Type:         AuthnTypeCookie,
+		UserVerified: userSession.AuthenticationMethodRefs.WebAuthnUserVerified,
 	}, nil
 }
 
@@ -256,12 +259,14 @@ func (s *HeaderAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, _ *session.Sessi
 	return authn, nil
 }
 
-func (s *HeaderAuthnStrategy) handleGetBasic(ctx *middlewares.AutheliaCtx, authn *Authn, _ *authorization.Object) (username string, level authentication.Level, err error) {
+func (s *HeaderAuthnStrategy) handleGetBasic(ctx *middlewares.AutheliaCtx, authn *Authn, object *authorization.Object) (username string, level authentication.Level, err error) {
 	var (
 		valid bool
 	)
 
-	if valid, err = ctx.Providers.UserProvider.CheckUserPassword(authn.Header.Authorization.Basic()); err != nil {
+	username, password := authn.Header.Authorization.Basic()
+
+	if valid, err = checkAuthorizationBasicCredentials(ctx, username, password, object); err != nil {
 		return "", authentication.NotAuthenticated, fmt.Errorf("failed to validate parsed credentials of %s header for user '%s': %w", s.headerAuthorize, authn.Header.Authorization.BasicUsername(), err)
 	}
 
@@ -272,6 +277,41 @@ func (s *HeaderAuthnStrategy) handleGetBasic(ctx *middlewares.AutheliaCtx, authn
 	return authn.Header.Authorization.BasicUsername(), authentication.OneFactor, nil
 }
 
+// checkAuthorizationBasicCredentials checks the username and password of the basic scheme of the Authorization header.
+// The passwords with the app password prefix are checked against the app passwords of the user first, and are only
+// valid for the domains the app password is scoped to. The other passwords are checked by the user provider. As the user
+// providers match the usernames case-insensitively the app passwords are looked up with the username of the user
+// provider rather than the username in the header.
+func checkAuthorizationBasicCredentials(ctx *middlewares.AutheliaCtx, username, password string, object *authorization.Object) (valid bool, err error) {
+	if object != nil && strings.HasPrefix(password, appPasswordPrefix) {
+		var (
+			details     *authentication.UserDetails
+			appPassword *model.AppPassword
+		)
+
+		if details, err = ctx.Providers.UserProvider.GetDetails(username); err != nil {
+			return false, fmt.Errorf("error occurred retrieving the user details: %w", err)
+		}
+
+		switch appPassword, err = ctx.Providers.StorageProvider.LoadAppPassword(ctx, details.Username, password); {
+		case err == nil:
+			if !appPassword.IsPermitted(object.Domain) {
+				return false, fmt.Errorf("the app password with id '%s' is not permitted to authenticate to the domain '%s'", appPassword.PublicID, object.Domain)
+			}
+
+			if err = ctx.Providers.StorageProvider.UpdateAppPasswordSignIn(ctx, appPassword.ID, sql.NullTime{Time: ctx.Clock.Now(), Valid: true}); err != nil {
+				ctx.Logger.WithError(err).Errorf("Error occurred updating the app password with id '%s' for user '%s'", appPassword.PublicID, details.Username)
+			}
+
+			return true, nil
+		case !errors.Is(err, storage.ErrNoAppPassword):
+			return false, fmt.Errorf("error occurred loading the app password: %w", err)
+		}
+	}
+
+	return ctx.Providers.UserProvider.CheckUserPassword(username, password)
+}
+
 // CanHandleUnauthorized returns true if this AuthnStrategy should handle Unauthorized requests.
 func (s *HeaderAuthnStrategy) CanHandleUnauthorized() (handle bool) {
 	return s.handleAuthenticate
@@ -299,7 +339,7 @@ func (s *HeaderAuthnStrategy) HandleUnauthorized(ctx *middlewares.AutheliaCtx, a
 type HeaderLegacyAuthnStrategy struct{}
 
 // Get returns the Authn information for this AuthnStrategy.
-func (s *HeaderLegacyAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, _ *session.Session, _ *authorization.Object) (authn *Authn, err error) {
+func (s *HeaderLegacyAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, _ *session.Session, object *authorization.Object) (authn *Authn, err error) {
 	var (
 		username, password string
 		value, header      []byte
@@ -340,7 +380,7 @@ func (s *HeaderLegacyAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, _ *session
 		details *authentication.UserDetails
 	)
 
-	if valid, err = ctx.Providers.UserProvider.CheckUserPassword(username, password); err != nil {
+	if valid, err = checkAuthorizationBasicCredentials(ctx, username, password, object); err != nil {
 		return authn, fmt.Errorf("failed to validate parsed credentials of %s header for user '%s': %w", header, username, err)
 	}
 
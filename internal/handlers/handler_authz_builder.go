@@ -81,24 +81,29 @@ func (b *AuthzBuilder) WithEndpointConfig(config schema.ServerEndpointsAuthz) *A
 		b.WithImplementationLegacy()
 	}
 
-	b.WithStrategies()
+	b.WithStrategies(newAuthnStrategies(config.AuthnStrategies, b.config.RefreshInterval)...)
 
-	for _, strategy := range config.AuthnStrategies {
+	return b
+}
+
+// newAuthnStrategies returns the AuthnStrategy implementations for the given authn strategies configuration.
+func newAuthnStrategies(config []schema.ServerEndpointsAuthzAuthnStrategy, refreshInterval schema.RefreshIntervalDuration) (strategies []AuthnStrategy) {
+	for _, strategy := range config {
 		switch strategy.Name {
 		case AuthnStrategyCookieSession:
-			b.strategies = append(b.strategies, NewCookieSessionAuthnStrategy(b.config.RefreshInterval))
+			strategies = append(strategies, NewCookieSessionAuthnStrategy(refreshInterval))
 		case AuthnStrategyHeaderAuthorization:
-			b.strategies = append(b.strategies, NewHeaderAuthorizationAuthnStrategy(strategy.Schemes...))
+			strategies = append(strategies, NewHeaderAuthorizationAuthnStrategy(strategy.Schemes...))
 		case AuthnStrategyHeaderProxyAuthorization:
-			b.strategies = append(b.strategies, NewHeaderProxyAuthorizationAuthnStrategy(strategy.Schemes...))
+			strategies = append(strategies, NewHeaderProxyAuthorizationAuthnStrategy(strategy.Schemes...))
 		case AuthnStrategyHeaderAuthRequestProxyAuthorization:
-			b.strategies = append(b.strategies, NewHeaderProxyAuthorizationAuthRequestAuthnStrategy(strategy.Schemes...))
+			strategies = append(strategies, NewHeaderProxyAuthorizationAuthRequestAuthnStrategy(strategy.Schemes...))
 		case AuthnStrategyHeaderLegacy:
-			b.strategies = append(b.strategies, NewHeaderLegacyAuthnStrategy())
+			strategies = append(strategies, NewHeaderLegacyAuthnStrategy())
 		}
 	}
 
-	return b
+	return strategies
 }
 
 // Build returns a new Authz from the currently configured options in this builder.
@@ -106,6 +111,7 @@ func (b *AuthzBuilder) Build() (authz *Authz) {
 	authz = &Authz{
 		config:           b.config,
 		strategies:       b.strategies,
+		ruleStrategies:   &authnStrategiesCache{refresh: b.config.RefreshInterval},
 		handleAuthorized: handleAuthzAuthorizedStandard,
 		implementation:   b.implementation,
 	}
@@ -91,3 +91,36 @@ func TestAuthzBuilder_WithEndpointConfig(t *testing.T) {
 
 	assert.Len(t, builder.strategies, 5)
 }
+
+func TestAuthzBuilder_BuildShouldCacheRuleStrategies(t *testing.T) {
+	authz := NewAuthzBuilder().Build()
+
+	strategies := authz.ruleStrategies.Get([]schema.ServerEndpointsAuthzAuthnStrategy{
+		{Name: "HeaderAuthorization", Schemes: []string{schema.SchemeBasic}},
+		{Name: "CookieSession"},
+	})
+
+	assert.Len(t, strategies, 2)
+
+	cached := authz.ruleStrategies.Get([]schema.ServerEndpointsAuthzAuthnStrategy{
+		{Name: "HeaderAuthorization", Schemes: []string{schema.SchemeBasic}},
+		{Name: "CookieSession"},
+	})
+
+	assert.Len(t, cached, 2)
+	assert.Same(t, strategies[0], cached[0])
+	assert.Same(t, strategies[1], cached[1])
+
+	other := authz.ruleStrategies.Get([]schema.ServerEndpointsAuthzAuthnStrategy{
+		{Name: "HeaderAuthorization", Schemes: []string{schema.SchemeBearer}},
+		{Name: "CookieSession"},
+	})
+
+	assert.Len(t, other, 2)
+	assert.NotSame(t, strategies[0], other[0])
+
+	assert.Len(t, authz.ruleStrategies.Get([]schema.ServerEndpointsAuthzAuthnStrategy{{Name: "invalid"}}), 0)
+	assert.Len(t, authz.ruleStrategies.Get([]schema.ServerEndpointsAuthzAuthnStrategy{{Name: "invalid"}}), 0)
+
+	assert.Len(t, authz.ruleStrategies.entries, 3)
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -83,6 +85,45 @@ func handleAuthzUnauthorizedAuthorizationBasic(ctx *middlewares.AutheliaCtx, aut
 	ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, headerValueAuthenticateBasic)
 }
 
+// isAuthzChallengeStatus returns true if the unauthorized response should be a status code with challenge metadata
+// rather than a redirect. This is always the case for WebSocket and gRPC clients unless the matched rule explicitly
+// configures a redirect, as these clients are unable to follow redirects.
+func isAuthzChallengeStatus(ctx *middlewares.AutheliaCtx, challenge authorization.Challenge) bool {
+	switch challenge {
+	case authorization.ChallengeRedirect:
+		return false
+	case authorization.ChallengeStatus:
+		return true
+	default:
+		return ctx.IsWebSocket() || ctx.IsGRPC()
+	}
+}
+
+func handleAuthzUnauthorizedChallengeStatus(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL) {
+	ctx.Logger.Infof(logFmtAuthzChallenge, authn.Object.String(), authn.Method, authn.Username, fasthttp.StatusUnauthorized, redirectionURL)
+
+	ctx.ReplyUnauthorized()
+
+	if redirectionURL != nil {
+		ctx.Response.Header.SetBytesK(headerLocation, redirectionURL.String())
+	}
+
+	if ctx.IsGRPC() {
+		handleAuthzResponseGRPC(ctx, grpcStatusUnauthenticated)
+	}
+}
+
+// handleAuthzResponseGRPC adjusts the current response so it conforms to the gRPC trailers-only response format which
+// allows gRPC and gRPC-Web clients to interpret the response status.
+func handleAuthzResponseGRPC(ctx *middlewares.AutheliaCtx, status int) {
+	statusCode := ctx.Response.StatusCode()
+
+	ctx.Response.ResetBody()
+	ctx.Response.Header.SetContentTypeBytes(ctx.Request.Header.ContentType())
+	ctx.Response.Header.SetBytesK(headerGRPCStatus, strconv.Itoa(status))
+	ctx.Response.Header.SetBytesK(headerGRPCMessage, url.PathEscape(fasthttp.StatusMessage(statusCode)))
+}
+
 var protoHostSeparator = []byte("://")
 
 func getRequestURIFromForwardedHeaders(protocol, host, uri []byte) (requestURI *url.URL, err error) {
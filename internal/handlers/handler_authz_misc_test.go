@@ -7,6 +7,7 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/session"
 )
@@ -21,6 +22,26 @@ func TestFriendlyMethod(t *testing.T) {
 	assert.Equal(t, "GET", friendlyMethod(fasthttp.MethodGet))
 }
 
+func TestIsAuthzUserVerificationSufficient(t *testing.T) {
+	testCases := []struct {
+		name         string
+		authn        *Authn
+		requirements authorization.Requirements
+		expected     bool
+	}{
+		{"ShouldAllowNotRequired", &Authn{}, authorization.Requirements{Level: authorization.TwoFactor}, true},
+		{"ShouldAllowOneFactor", &Authn{}, authorization.Requirements{Level: authorization.OneFactor, UserVerification: true}, true},
+		{"ShouldAllowVerified", &Authn{UserVerified: true}, authorization.Requirements{Level: authorization.TwoFactor, UserVerification: true}, true},
+		{"ShouldDenyNotVerified", &Authn{}, authorization.Requirements{Level: authorization.TwoFactor, UserVerification: true}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isAuthzUserVerificationSufficient(tc.authn, tc.requirements))
+		})
+	}
+}
+
 func TestGenerateVerifySessionHasUpToDateProfileTraceLogs(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 
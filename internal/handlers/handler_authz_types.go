@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/url"
+	"sync"
 
 	oauthelia2 "authelia.com/provider/oauth2"
 
@@ -23,6 +24,8 @@ type Authz struct {
 
 	strategies []AuthnStrategy
 
+	ruleStrategies *authnStrategiesCache
+
 	handleGetObject HandlerAuthzGetObject
 
 	handleGetAutheliaURL HandlerAuthzGetAutheliaURL
@@ -33,6 +36,20 @@ type Authz struct {
 	implementation AuthzImplementation
 }
 
+// authnStrategiesCache holds the AuthnStrategy implementations of the authn strategies configured on the access control
+// rules so each distinct configuration is only created once instead of on every request.
+type authnStrategiesCache struct {
+	refresh schema.RefreshIntervalDuration
+
+	mu      sync.RWMutex
+	entries []authnStrategiesCacheEntry
+}
+
+type authnStrategiesCacheEntry struct {
+	config     []schema.ServerEndpointsAuthzAuthnStrategy
+	strategies []AuthnStrategy
+}
+
 // HandlerAuthzUnauthorized is a Authz handler func that handles unauthorized responses.
 type HandlerAuthzUnauthorized func(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL)
 
@@ -79,6 +96,9 @@ type Authn struct {
 	Object  authorization.Object
 	Type    AuthnType
 
+	// UserVerified is true when the second factor was a WebAuthn assertion which verified the user.
+	UserVerified bool
+
 	Header HeaderAuthorization
 }
 
@@ -127,6 +147,20 @@ const (
 	AuthzResultAuthorized
 )
 
+// String returns the metrics representation of the AuthzResult.
+func (r AuthzResult) String() string {
+	switch r {
+	case AuthzResultForbidden:
+		return "forbidden"
+	case AuthzResultUnauthorized:
+		return "unauthorized"
+	case AuthzResultAuthorized:
+		return "authorized"
+	default:
+		return ""
+	}
+}
+
 // AuthzImplementation represents an Authz implementation.
 type AuthzImplementation int
 
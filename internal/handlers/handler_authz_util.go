@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"slices"
+	"strconv"
+
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -26,6 +30,38 @@ func friendlyUsername(username string) (fusername string) {
 	}
 }
 
+// authzRuleMetricName returns the rule label used by the authorization decision metrics, the position of the matched rule
+// or default when the default policy applied.
+func authzRuleMetricName(position int) string {
+	if position == 0 {
+		return "default"
+	}
+
+	return strconv.Itoa(position)
+}
+
+// isManagedDevice returns true if the request has a client certificate forwarded by the proxy which was issued to a
+// managed device by the device certificate authority. If the username is not empty the certificate must have been
+// issued to that user.
+func isManagedDevice(ctx *middlewares.AutheliaCtx, username string) bool {
+	if ctx.Providers.DeviceCertificates == nil {
+		return false
+	}
+
+	value := ctx.Request.Header.PeekBytes([]byte(ctx.Providers.DeviceCertificates.GetHeader()))
+	if len(value) == 0 {
+		return false
+	}
+
+	if _, err := ctx.Providers.DeviceCertificates.VerifyHeader(ctx, value, username); err != nil {
+		ctx.Logger.WithError(err).Debugf("Client certificate forwarded for user '%s' is not a valid device certificate", friendlyUsername(username))
+
+		return false
+	}
+
+	return true
+}
+
 func isAuthzResult(level authentication.Level, required authorization.Level, ruleHasSubject bool) AuthzResult {
 	switch {
 	case required == authorization.Bypass:
@@ -43,6 +79,12 @@ func isAuthzResult(level authentication.Level, required authorization.Level, rul
 	}
 }
 
+// isAuthzUserVerificationSufficient returns false if the requirements require the WebAuthn assertion of the session to
+// have verified the user and it didn't.
+func isAuthzUserVerificationSufficient(authn *Authn, requirements authorization.Requirements) bool {
+	return !requirements.UserVerification || requirements.Level != authorization.TwoFactor || authn.UserVerified
+}
+
 // generateVerifySessionHasUpToDateProfileTraceLogs is used to generate trace logs only when trace logging is enabled.
 // The information calculated in this function is completely useless other than trace for now.
 func generateVerifySessionHasUpToDateProfileTraceLogs(ctx *middlewares.AutheliaCtx, userSession *session.UserSession,
@@ -103,3 +145,51 @@ func generateVerifySessionHasUpToDateProfileTraceLogs(ctx *middlewares.AutheliaC
 		ctx.Logger.Trace("User session display name is current")
 	}
 }
+
+// Get returns the AuthnStrategy implementations for the given authn strategies configuration, creating them if this is
+// the first time the configuration has been seen.
+func (c *authnStrategiesCache) Get(config []schema.ServerEndpointsAuthzAuthnStrategy) (strategies []AuthnStrategy) {
+	c.mu.RLock()
+
+	strategies = c.lookup(config)
+
+	c.mu.RUnlock()
+
+	if strategies != nil {
+		return strategies
+	}
+
+	c.mu.Lock()
+
+	defer c.mu.Unlock()
+
+	if strategies = c.lookup(config); strategies != nil {
+		return strategies
+	}
+
+	if strategies = newAuthnStrategies(config, c.refresh); strategies == nil {
+		strategies = []AuthnStrategy{}
+	}
+
+	c.entries = append(c.entries, authnStrategiesCacheEntry{config: config, strategies: strategies})
+
+	return strategies
+}
+
+// lookup returns the cached AuthnStrategy implementations for the given authn strategies configuration, the caller must
+// hold the lock.
+func (c *authnStrategiesCache) lookup(config []schema.ServerEndpointsAuthzAuthnStrategy) (strategies []AuthnStrategy) {
+	for _, entry := range c.entries {
+		if isAuthnStrategiesConfigEqual(entry.config, config) {
+			return entry.strategies
+		}
+	}
+
+	return nil
+}
+
+func isAuthnStrategiesConfigEqual(a, b []schema.ServerEndpointsAuthzAuthnStrategy) bool {
+	return slices.EqualFunc(a, b, func(x, y schema.ServerEndpointsAuthzAuthnStrategy) bool {
+		return x.Name == y.Name && slices.Equal(x.Schemes, y.Schemes)
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// ConfigurationBrandingGET get the portal branding of the session cookie domain of the request. The branding from the
+// session cookie configuration takes precedence over the branding from the asset overlay manifest.
+func ConfigurationBrandingGET(ctx *middlewares.AutheliaCtx) {
+	body := configurationBrandingBody{}
+
+	var domain string
+
+	if provider, err := ctx.GetSessionProvider(); err == nil {
+		domain = provider.Config.Domain
+
+		branding := provider.Config.Branding
+
+		body.Title = branding.Title
+		body.Announcement = branding.Announcement
+
+		if branding.LogoURL != nil {
+			body.LogoURL = branding.LogoURL.String()
+		}
+
+		if branding.SupportContact != nil {
+			body.SupportContact = branding.SupportContact.String()
+		}
+	}
+
+	if body.Title == "" {
+		body.Title = ctx.Providers.Branding.Get(domain).Title
+	}
+
+	if err := ctx.SetJSONBody(body); err != nil {
+		ctx.Logger.Errorf("Unable to set configuration branding response in body: %s", err)
+	}
+}
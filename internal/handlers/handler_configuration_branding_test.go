@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/branding"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func TestConfigurationBrandingGET(t *testing.T) {
+	manifest := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(manifest, "branding.yml"), []byte("default:\n  title: 'Manifest Login'\n"), 0600))
+
+	testCases := []struct {
+		name     string
+		host     string
+		have     schema.SessionCookieBranding
+		manifest string
+		expected configurationBrandingBody
+	}{
+		{
+			"ShouldReturnEmpty",
+			"example.com",
+			schema.SessionCookieBranding{},
+			"",
+			configurationBrandingBody{},
+		},
+		{
+			"ShouldReturnCookieBranding",
+			"example.com",
+			schema.SessionCookieBranding{
+				Title:          "Example Login",
+				LogoURL:        &url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/logo.svg"},
+				Announcement:   "Scheduled maintenance on Saturday.",
+				SupportContact: &url.URL{Scheme: "mailto", Opaque: "support@example.com"},
+			},
+			"",
+			configurationBrandingBody{
+				Title:          "Example Login",
+				LogoURL:        "https://cdn.example.com/logo.svg",
+				Announcement:   "Scheduled maintenance on Saturday.",
+				SupportContact: "mailto:support@example.com",
+			},
+		},
+		{
+			"ShouldReturnManifestTitle",
+			"example.com",
+			schema.SessionCookieBranding{Announcement: "Hello."},
+			manifest,
+			configurationBrandingBody{
+				Title:        "Manifest Login",
+				Announcement: "Hello.",
+			},
+		},
+		{
+			"ShouldPreferCookieTitle",
+			"example.com",
+			schema.SessionCookieBranding{Title: "Example Login"},
+			manifest,
+			configurationBrandingBody{
+				Title: "Example Login",
+			},
+		},
+		{
+			"ShouldReturnOnlyMatchingCookieBranding",
+			"example2.com",
+			schema.SessionCookieBranding{Title: "Example Login"},
+			"",
+			configurationBrandingBody{},
+		},
+		{
+			"ShouldReturnManifestTitleWithoutCookieDomain",
+			"example.net",
+			schema.SessionCookieBranding{Title: "Example Login"},
+			manifest,
+			configurationBrandingBody{
+				Title: "Manifest Login",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			var err error
+
+			mock.Ctx.Configuration.Session.Cookies[0].Branding = tc.have
+			mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+
+			mock.Ctx.Providers.Branding, err = branding.NewProvider(tc.manifest)
+			require.NoError(t, err)
+
+			mock.Ctx.Request.Header.Set(fasthttp.HeaderXForwardedProto, "https")
+			mock.Ctx.Request.Header.Set(fasthttp.HeaderXForwardedHost, tc.host)
+
+			ConfigurationBrandingGET(mock.Ctx)
+
+			mock.Assert200OK(t, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+// ConfigurationFeaturesGET get the optional features which are enabled accessible to authenticated users.
+func ConfigurationFeaturesGET(ctx *middlewares.AutheliaCtx) {
+	body := configurationFeaturesBody{
+		Features: middlewares.NewFeatures(&ctx.Configuration),
+	}
+
+	if err := ctx.SetJSONBody(body); err != nil {
+		ctx.Logger.Errorf("Unable to set configuration features response in body: %s", err)
+	}
+}
@@ -4,22 +4,27 @@ import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
-// PasswordPolicyConfigurationGET get the password policy configuration.
+// PasswordPolicyConfigurationGET get the password policy configuration. If a password reset is in progress the
+// password policy which applies to the groups of the user resetting their password is returned.
 func PasswordPolicyConfigurationGET(ctx *middlewares.AutheliaCtx) {
 	policyResponse := PasswordPolicyBody{
 		Mode: "disabled",
 	}
 
-	if ctx.Configuration.PasswordPolicy.Standard.Enabled {
+	standard, zxcvbn := ctx.Configuration.PasswordPolicy.ForGroups(passwordPolicyGroups(ctx))
+
+	if standard.Enabled {
 		policyResponse.Mode = "standard"
-		policyResponse.MinLength = ctx.Configuration.PasswordPolicy.Standard.MinLength
-		policyResponse.MaxLength = ctx.Configuration.PasswordPolicy.Standard.MaxLength
-		policyResponse.RequireLowercase = ctx.Configuration.PasswordPolicy.Standard.RequireLowercase
-		policyResponse.RequireUppercase = ctx.Configuration.PasswordPolicy.Standard.RequireUppercase
-		policyResponse.RequireNumber = ctx.Configuration.PasswordPolicy.Standard.RequireNumber
-		policyResponse.RequireSpecial = ctx.Configuration.PasswordPolicy.Standard.RequireSpecial
-	} else if ctx.Configuration.PasswordPolicy.ZXCVBN.Enabled {
+		policyResponse.MinLength = standard.MinLength
+		policyResponse.MaxLength = standard.MaxLength
+		policyResponse.RequireLowercase = standard.RequireLowercase
+		policyResponse.RequireUppercase = standard.RequireUppercase
+		policyResponse.RequireNumber = standard.RequireNumber
+		policyResponse.RequireSpecial = standard.RequireSpecial
+	} else if zxcvbn.Enabled {
 		policyResponse.Mode = "zxcvbn"
+		policyResponse.MinScore = zxcvbn.MinScore
+		policyResponse.MinEntropy = zxcvbn.MinEntropy
 	}
 
 	var err error
@@ -28,3 +33,24 @@ func PasswordPolicyConfigurationGET(ctx *middlewares.AutheliaCtx) {
 		ctx.Logger.Errorf("Unable to send password Policy: %s", err)
 	}
 }
+
+// passwordPolicyGroups returns the groups of the user with a password reset in progress, if any.
+func passwordPolicyGroups(ctx *middlewares.AutheliaCtx) (groups []string) {
+	if len(ctx.Configuration.PasswordPolicy.GroupPolicies) == 0 {
+		return nil
+	}
+
+	userSession, err := ctx.GetSession()
+	if err != nil || userSession.PasswordResetUsername == nil {
+		return nil
+	}
+
+	details, err := ctx.Providers.UserProvider.GetDetails(*userSession.PasswordResetUsername)
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving details for user '%s' to determine their password policy", *userSession.PasswordResetUsername)
+
+		return nil
+	}
+
+	return details.Groups
+}
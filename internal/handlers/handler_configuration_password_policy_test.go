@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/suite"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 )
 
@@ -77,6 +79,53 @@ func (s *PasswordPolicySuite) TestShouldBeZXCVBN() {
 	assert.Equal(s.T(), "zxcvbn", response.Data.Mode)
 }
 
+func (s *PasswordPolicySuite) TestShouldBeGroupPolicy() {
+	s.mock.Ctx.Configuration.PasswordPolicy.Standard = schema.PasswordPolicyStandard{Enabled: true, MinLength: 8}
+	s.mock.Ctx.Configuration.PasswordPolicy.GroupPolicies = []schema.PasswordPolicyGroupPolicy{
+		{Groups: []string{"admins"}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 4, MinEntropy: 60}},
+	}
+
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	username := testUsername
+	userSession.PasswordResetUsername = &username
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.UserProviderMock.EXPECT().
+		GetDetails(testUsername).
+		Return(&authentication.UserDetails{Username: testUsername, Groups: []string{"dev", "admins"}}, nil)
+
+	PasswordPolicyConfigurationGET(s.mock.Ctx)
+
+	response := &passwordPolicyResponseBody{}
+	err = json.Unmarshal(s.mock.Ctx.Response.Body(), response)
+
+	s.Require().NoError(err)
+	s.Equal(fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+	s.Equal("zxcvbn", response.Data.Mode)
+	s.Equal(4, response.Data.MinScore)
+	s.Equal(60, response.Data.MinEntropy)
+}
+
+func (s *PasswordPolicySuite) TestShouldBeTopLevelPolicyWithoutPasswordReset() {
+	s.mock.Ctx.Configuration.PasswordPolicy.Standard = schema.PasswordPolicyStandard{Enabled: true, MinLength: 8}
+	s.mock.Ctx.Configuration.PasswordPolicy.GroupPolicies = []schema.PasswordPolicyGroupPolicy{
+		{Groups: []string{"admins"}, ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 4}},
+	}
+
+	PasswordPolicyConfigurationGET(s.mock.Ctx)
+
+	response := &passwordPolicyResponseBody{}
+	err := json.Unmarshal(s.mock.Ctx.Response.Body(), response)
+
+	s.Require().NoError(err)
+	s.Equal(fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+	s.Equal("standard", response.Data.Mode)
+	s.Equal(8, response.Data.MinLength)
+}
+
 func TestRunPasswordPolicySuite(t *testing.T) {
 	s := new(PasswordPolicySuite)
 	suite.Run(t, s)
@@ -7,6 +7,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
 )
 
@@ -39,6 +40,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldHaveAllConfiguredMethods
 		WebAuthn: schema.WebAuthn{
 			Disable: false,
 		},
+		SMS: schema.SMS{
+			Disable: false,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -54,7 +58,7 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldHaveAllConfiguredMethods
 	ConfigurationGET(s.mock.Ctx)
 
 	s.mock.Assert200OK(s.T(), configurationBody{
-		AvailableMethods: []string{"totp", "webauthn", "mobile_push"},
+		AvailableMethods: []string{"totp", "webauthn", "mobile_push", "sms"},
 	})
 }
 
@@ -69,6 +73,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveTOTPFromAvailableM
 		WebAuthn: schema.WebAuthn{
 			Disable: false,
 		},
+		SMS: schema.SMS{
+			Disable: true,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -99,6 +106,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveWebAuthnFromAvaila
 		WebAuthn: schema.WebAuthn{
 			Disable: true,
 		},
+		SMS: schema.SMS{
+			Disable: true,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -129,6 +139,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveDuoFromAvailableMe
 		WebAuthn: schema.WebAuthn{
 			Disable: false,
 		},
+		SMS: schema.SMS{
+			Disable: true,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -159,6 +172,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveAllMethodsWhenNoTw
 		WebAuthn: schema.WebAuthn{
 			Disable: false,
 		},
+		SMS: schema.SMS{
+			Disable: true,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -189,6 +205,9 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveAllMethodsWhenAllD
 		WebAuthn: schema.WebAuthn{
 			Disable: true,
 		},
+		SMS: schema.SMS{
+			Disable: true,
+		},
 		AccessControl: schema.AccessControl{
 			DefaultPolicy: "deny",
 			Rules: []schema.AccessControlRule{
@@ -208,6 +227,38 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveAllMethodsWhenAllD
 	})
 }
 
+func (s *SecondFactorAvailableMethodsFixture) TestShouldReturnEnabledFeatures() {
+	s.mock.Ctx.Configuration = schema.Configuration{
+		IdentityProviders: schema.IdentityProviders{
+			OIDC: &schema.IdentityProvidersOpenIDConnect{},
+		},
+		AuthenticationBackend: schema.AuthenticationBackend{
+			PasswordReset: schema.AuthenticationBackendPasswordReset{
+				Disable: true,
+			},
+		},
+		DuoAPI: schema.DuoAPI{
+			Disable: true,
+		},
+		Telemetry: schema.Telemetry{
+			Metrics: schema.TelemetryMetrics{
+				Enabled: true,
+			},
+		},
+	}
+
+	ConfigurationFeaturesGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), configurationFeaturesBody{
+		Features: middlewares.Features{
+			OpenIDConnect: true,
+			Metrics:       true,
+			TOTP:          true,
+			WebAuthn:      true,
+		},
+	})
+}
+
 func TestRunSuite(t *testing.T) {
 	s := new(SecondFactorAvailableMethodsFixture)
 	suite.Run(t, s)
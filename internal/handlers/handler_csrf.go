@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// CSRFTokenGET is the handler serving the CSRF token of the session which is required by the requests which use an
+// unsafe method when the headless mode is enabled. The token is generated the first time it's requested and remains the
+// same for the life of the session.
+func CSRFTokenGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred retrieving the CSRF token: error occurred retrieving the user session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if len(userSession.CSRFToken) == 0 {
+		userSession.CSRFToken = ctx.Providers.Random.StringCustom(64, random.CharSetAlphaNumeric)
+
+		if err = ctx.SaveSession(userSession); err != nil {
+			ctx.Logger.WithError(err).Error("Error occurred retrieving the CSRF token: error occurred saving the user session")
+
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	}
+
+	if err = ctx.SetJSONBody(CSRFTokenResponse{Token: userSession.CSRFToken}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the CSRF token: %s", errStrRespBody)
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestCSRFTokenGET(t *testing.T) {
+	type response struct {
+		Status string            `json:"status"`
+		Data   CSRFTokenResponse `json:"data"`
+	}
+
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	CSRFTokenGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	first := response{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &first))
+
+	assert.Equal(t, "OK", first.Status)
+	assert.Len(t, first.Data.Token, 64)
+
+	userSession, err := mock.Ctx.GetSession()
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Data.Token, userSession.CSRFToken)
+
+	mock.Ctx.Response.Reset()
+
+	CSRFTokenGET(mock.Ctx)
+
+	second := response{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &second))
+
+	assert.Equal(t, first.Data.Token, second.Data.Token)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/pki"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// DeviceCertificatesGET returns a page of the client certificates issued to the managed devices of the current user.
+func DeviceCertificatesGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession  session.UserSession
+		pagination   model.Pagination
+		certificates []model.DeviceCertificate
+		err          error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading device certificates: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred loading device certificates")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if pagination, err = getPagination(ctx); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading device certificates for user '%s': error occurred parsing the pagination", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if certificates, err = ctx.Providers.StorageProvider.LoadDeviceCertificates(ctx, userSession.Username, pagination); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading device certificates for user '%s': error occurred loading the certificates from the storage backend", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(certificates); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading device certificates for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// DeviceCertificatesPOST signs the certificate signing request in the body and issues a client certificate to a
+// managed device of the current user.
+func DeviceCertificatesPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON bodyIssueDeviceCertificateRequest
+
+		userSession session.UserSession
+		request     *x509.CertificateRequest
+		certificate *model.DeviceCertificate
+		encoded     []byte
+
+		err error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred issuing device certificate: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred issuing device certificate")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = json.Unmarshal(ctx.PostBody(), &bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred issuing device certificate for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if len(bodyJSON.Name) == 0 || len(bodyJSON.Name) > 100 {
+		ctx.Logger.WithError(fmt.Errorf("name must be between 1 and 100 characters but it's %d characters", len(bodyJSON.Name))).Errorf("Error occurred issuing device certificate for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if request, err = pki.ParseCertificateRequest([]byte(bodyJSON.CertificateSigningRequest)); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred issuing device certificate for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if certificate, encoded, err = ctx.Providers.DeviceCertificates.Issue(ctx, userSession.Username, bodyJSON.Name, request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred issuing device certificate for user '%s'", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Issued device certificate with serial '%s' to the device '%s' for user '%s'", certificate.Serial, certificate.Name, userSession.Username)
+
+	ctx.AuditEvent(audit.EventTypeDeviceCertificateIssue, audit.ResultSuccess, userSession.Username, map[string]any{"serial": certificate.Serial, "name": certificate.Name, "expires_at": certificate.ExpiresAt})
+
+	if err = ctx.SetJSONBody(DeviceCertificateResponse{Device: certificate, Certificate: string(encoded)}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred issuing device certificate for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// DeviceCertificateDELETE revokes a specific client certificate issued to a managed device of the current user.
+func DeviceCertificateDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking device certificate: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred revoking device certificate")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	serial, ok := ctx.UserValue("serial").(string)
+	if !ok || len(serial) == 0 {
+		ctx.Logger.WithError(fmt.Errorf("the serial wasn't provided")).Errorf("Error occurred revoking device certificate for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.RevokeDeviceCertificate(ctx, userSession.Username, serial, ctx.Clock.Now()); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking device certificate with serial '%s' for user '%s'", serial, userSession.Username)
+
+		if errors.Is(err, storage.ErrNoDeviceCertificate) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeDeviceCertificateRevoke, audit.ResultSuccess, userSession.Username, map[string]any{"serial": serial})
+
+	ctx.ReplyOK()
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestDeviceCertificatesGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading device certificates", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setDeviceCertificatesTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadDeviceCertificates(mock.Ctx, testUsername, model.Pagination{Limit: paginationLimitDefault}).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading device certificates for user 'john': error occurred loading the certificates from the storage backend", "bad block")
+			},
+		},
+		{
+			"ShouldHandleCertificates",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setDeviceCertificatesTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadDeviceCertificates(mock.Ctx, testUsername, model.Pagination{Limit: paginationLimitDefault}).Return([]model.DeviceCertificate{{ID: 1, Serial: "abc", Username: testUsername, Name: "laptop", IssuedAt: time.Unix(1700000000, 0).UTC(), ExpiresAt: time.Unix(1700086400, 0).UTC()}}, nil)
+			},
+			`{"status":"OK","data":[{"serial":"abc","name":"laptop","issued_at":"2023-11-14T22:13:20Z","expires_at":"2023-11-15T22:13:20Z"}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			DeviceCertificatesGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestDeviceCertificateDELETE(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking device certificate", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleMissingSerial",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setDeviceCertificatesTestSession(t, mock)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking device certificate for user 'john'", "the serial wasn't provided")
+			},
+		},
+		{
+			"ShouldHandleNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setDeviceCertificatesTestSession(t, mock)
+
+				mock.Ctx.SetUserValue("serial", "abc")
+
+				mock.StorageMock.EXPECT().RevokeDeviceCertificate(mock.Ctx, testUsername, "abc", gomock.Any()).Return(storage.ErrNoDeviceCertificate)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking device certificate with serial 'abc' for user 'john'", "no device certificate found")
+			},
+		},
+		{
+			"ShouldRevoke",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setDeviceCertificatesTestSession(t, mock)
+
+				mock.Ctx.SetUserValue("serial", "abc")
+
+				mock.StorageMock.EXPECT().RevokeDeviceCertificate(mock.Ctx, testUsername, "abc", gomock.Any()).Return(nil)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			DeviceCertificateDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestIsManagedDeviceShouldBeFalseWithoutProvider(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Tls-Client-Cert", "abc")
+
+	assert.False(t, isManagedDevice(mock.Ctx, testUsername))
+}
+
+func setDeviceCertificatesTestSession(t *testing.T, mock *mocks.MockAutheliaCtx) {
+	us, err := mock.Ctx.GetSession()
+
+	require.NoError(t, err)
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, mock.Ctx.SaveSession(us))
+}
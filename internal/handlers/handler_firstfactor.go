@@ -6,6 +6,7 @@ import (
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 // FirstFactorPOST is the handler performing the first factory.
@@ -70,90 +71,110 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
-		provider, err := ctx.GetSessionProvider()
-		if err != nil {
-			ctx.Logger.WithError(err).Error("Failed to get session provider during 1FA attempt")
+		userSession, ok := handleFirstFactorSession(ctx, bodyJSON.Username, bodyJSON.KeepMeLoggedIn, regulation.AuthType1FA, nil)
+		if !ok {
+			return
+		}
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+		successful = true
 
-			return
+		switch bodyJSON.Workflow {
+		case workflowOpenIDConnect:
+			handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+		case workflowSAML:
+			handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+		default:
+			Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
 		}
+	}
+}
 
-		userSession, err := provider.GetSession(ctx.RequestCtx)
-		if err != nil {
-			ctx.Logger.Errorf("%s", err)
+// handleFirstFactorSession establishes the session of a user who passed the first factor with the details from the user
+// provider, responding with a 401 Unauthorized if it fails. The setFactors func, if provided, is called before the
+// session is saved in order to adjust the factors of the session for methods other than a username and password.
+func handleFirstFactorSession(ctx *middlewares.AutheliaCtx, username string, rememberMe *bool, authType string, setFactors func(userSession *session.UserSession)) (userSession session.UserSession, ok bool) {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Failed to get session provider during %s attempt", authType)
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 
-			return
-		}
+		return userSession, false
+	}
 
-		newSession := provider.NewDefaultUserSession()
+	if userSession, err = provider.GetSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf("%s", err)
 
-		// Reset all values from previous session except OIDC workflow before regenerating the cookie.
-		if err = ctx.SaveSession(newSession); err != nil {
-			ctx.Logger.WithError(err).Errorf(logFmtErrSessionReset, regulation.AuthType1FA, bodyJSON.Username)
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+		return userSession, false
+	}
 
-			return
-		}
+	newSession := provider.NewDefaultUserSession()
 
-		if err = ctx.RegenerateSession(); err != nil {
-			ctx.Logger.WithError(err).Errorf(logFmtErrSessionRegenerate, regulation.AuthType1FA, bodyJSON.Username)
+	// Reset all values from previous session except OIDC workflow before regenerating the cookie.
+	if err = ctx.SaveSession(newSession); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionReset, authType, username)
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 
-			return
-		}
+		return userSession, false
+	}
 
-		// Check if bodyJSON.KeepMeLoggedIn can be deref'd and derive the value based on the configuration and JSON data.
-		keepMeLoggedIn := !provider.Config.DisableRememberMe && bodyJSON.KeepMeLoggedIn != nil && *bodyJSON.KeepMeLoggedIn
+	if err = ctx.RegenerateSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionRegenerate, authType, username)
 
-		// Set the cookie to expire if remember me is enabled and the user has asked us to.
-		if keepMeLoggedIn {
-			err = provider.UpdateExpiration(ctx.RequestCtx, provider.Config.RememberMe)
-			if err != nil {
-				ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthType1FA, logFmtActionAuthentication, bodyJSON.Username)
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 
-				respondUnauthorized(ctx, messageAuthenticationFailed)
+		return userSession, false
+	}
 
-				return
-			}
-		}
+	// Check if rememberMe can be deref'd and derive the value based on the configuration and JSON data.
+	keepMeLoggedIn := !provider.Config.DisableRememberMe && rememberMe != nil && *rememberMe
 
-		// Get the details of the given user from the user provider.
-		userDetails, err := ctx.Providers.UserProvider.GetDetails(bodyJSON.Username)
+	// Set the cookie to expire if remember me is enabled and the user has asked us to.
+	if keepMeLoggedIn {
+		err = provider.UpdateExpiration(ctx.RequestCtx, provider.Config.RememberMe)
 		if err != nil {
-			ctx.Logger.WithError(err).Errorf(logFmtErrObtainProfileDetails, regulation.AuthType1FA, bodyJSON.Username)
+			ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "updated expiration", authType, logFmtActionAuthentication, username)
 
 			respondUnauthorized(ctx, messageAuthenticationFailed)
 
-			return
+			return userSession, false
 		}
+	}
 
-		ctx.Logger.Tracef(logFmtTraceProfileDetails, bodyJSON.Username, userDetails.Groups, userDetails.Emails)
+	// Get the details of the given user from the user provider.
+	userDetails, err := ctx.Providers.UserProvider.GetDetails(username)
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrObtainProfileDetails, authType, username)
 
-		userSession.SetOneFactor(ctx.Clock.Now(), userDetails, keepMeLoggedIn)
+		respondUnauthorized(ctx, messageAuthenticationFailed)
 
-		if ctx.Configuration.AuthenticationBackend.RefreshInterval.Update() {
-			userSession.RefreshTTL = ctx.Clock.Now().Add(ctx.Configuration.AuthenticationBackend.RefreshInterval.Value())
-		}
+		return userSession, false
+	}
 
-		if err = ctx.SaveSession(userSession); err != nil {
-			ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "updated profile", regulation.AuthType1FA, logFmtActionAuthentication, bodyJSON.Username)
+	ctx.Logger.Tracef(logFmtTraceProfileDetails, username, userDetails.Groups, userDetails.Emails)
 
-			respondUnauthorized(ctx, messageAuthenticationFailed)
+	userSession.SetOneFactor(ctx.Clock.Now(), userDetails, keepMeLoggedIn)
 
-			return
-		}
+	if setFactors != nil {
+		setFactors(&userSession)
+	}
 
-		successful = true
+	if ctx.Configuration.AuthenticationBackend.RefreshInterval.Update() {
+		userSession.RefreshTTL = ctx.Clock.Now().Add(ctx.Configuration.AuthenticationBackend.RefreshInterval.Value())
+	}
 
-		if bodyJSON.Workflow == workflowOpenIDConnect {
-			handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
-		} else {
-			Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
-		}
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "updated profile", authType, logFmtActionAuthentication, username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return userSession, false
 	}
+
+	indexUserSession(ctx, userSession)
+
+	return userSession, true
 }
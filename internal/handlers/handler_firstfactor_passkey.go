@@ -0,0 +1,336 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// FirstFactorPasskeyGET handler starts the discoverable assertion ceremony which allows a passkey to perform the first
+// factor. The mediation query argument is returned to the client so the conditional mediation (autofill) UI can request
+// the options when the login portal is loaded.
+func FirstFactorPasskeyGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		w           *webauthn.WebAuthn
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a WebAuthn passkey authentication challenge: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageAuthenticationFailed)
+
+		return
+	}
+
+	if w, err = handleNewWebAuthn(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred generating a WebAuthn passkey authentication challenge: error occurred provisioning the configuration")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageAuthenticationFailed)
+
+		return
+	}
+
+	var (
+		assertion *protocol.CredentialAssertion
+		data      session.WebAuthn
+	)
+
+	if assertion, data.SessionData, err = w.BeginDiscoverableLogin(webauthn.WithUserVerification(ctx.Configuration.WebAuthn.PasskeyLogin.UserVerification)); err != nil {
+		ctx.Logger.WithError(formatWebAuthnError(err)).Error("Error occurred generating a WebAuthn passkey authentication challenge: error occurred starting the authentication session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageAuthenticationFailed)
+
+		return
+	}
+
+	userSession.WebAuthn = &data
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a WebAuthn passkey authentication challenge: %s", errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageAuthenticationFailed)
+
+		return
+	}
+
+	response := passkeyCredentialAssertion{CredentialAssertion: assertion}
+
+	if mediation := ctx.QueryArgs().PeekBytes(qryArgMediation); bytes.Equal(mediation, []byte(passkeyMediationConditional)) {
+		response.Mediation = passkeyMediationConditional
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a WebAuthn passkey authentication challenge: %s", errStrRespBody)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageAuthenticationFailed)
+
+		return
+	}
+}
+
+// FirstFactorPasskeyPOST handler completes the discoverable assertion ceremony, performing the first factor for the
+// user the passkey belongs to. The second factor is also performed if the configuration allows passkeys which verified
+// the user to satisfy both factors.
+//
+//nolint:gocyclo
+func FirstFactorPasskeyPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+
+		err error
+
+		w    *webauthn.WebAuthn
+		c    *webauthn.Credential
+		user *model.WebAuthnUser
+
+		bodyJSON bodyFirstFactorPasskeyRequest
+
+		assertionResponse *protocol.ParsedCredentialAssertionData
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrParseRequestBody, regulation.AuthTypePasskey)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if assertionResponse, err = protocol.ParseCredentialRequestResponseBody(bytes.NewReader(bodyJSON.Response)); err != nil {
+		ctx.Logger.WithError(formatWebAuthnError(err)).Errorf(logFmtErrParseRequestBody, regulation.AuthTypePasskey)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a WebAuthn passkey authentication challenge: %s", errStrUserSessionData)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if userSession.WebAuthn == nil || userSession.WebAuthn.SessionData == nil {
+		ctx.Logger.WithError(fmt.Errorf("challenge session data is not present")).Errorf("Error occurred validating a WebAuthn passkey authentication challenge: %s", errStrUserSessionData)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if w, err = handleNewWebAuthn(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred validating a WebAuthn passkey authentication challenge: error occurred provisioning the configuration")
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	sessionData := *userSession.WebAuthn.SessionData
+
+	userSession.WebAuthn = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a WebAuthn passkey authentication challenge: %s", errStrUserSessionDataSave)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if c, err = w.ValidateDiscoverableLogin(handleWebAuthnDiscoverableUser(ctx, w.Config.RPID, &user), sessionData, assertionResponse); err != nil {
+		if user == nil {
+			ctx.Logger.WithError(formatWebAuthnError(err)).Error("Error occurred validating a WebAuthn passkey authentication challenge: error occurred looking up the user the passkey belongs to")
+		} else {
+			_ = markAuthenticationAttempt(ctx, false, nil, user.Username, regulation.AuthTypePasskey, formatWebAuthnError(err))
+		}
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, user.Username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			_ = markAuthenticationAttempt(ctx, false, &bannedUntil, user.Username, regulation.AuthTypePasskey, nil)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		ctx.Logger.WithError(err).Errorf(logFmtErrRegulationFail, regulation.AuthTypePasskey, user.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	var credential *model.WebAuthnCredential
+
+	for i := range user.Credentials {
+		if bytes.Equal(user.Credentials[i].KID.Bytes(), c.ID) {
+			credential = &user.Credentials[i]
+
+			break
+		}
+	}
+
+	if credential == nil {
+		ctx.Logger.WithError(fmt.Errorf("credential was not found")).Errorf("Error occurred validating a WebAuthn passkey authentication challenge for user '%s': error occurred saving the credential sign-in information to storage", user.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if c.Authenticator.CloneWarning {
+		_ = markAuthenticationAttempt(ctx, false, nil, user.Username, regulation.AuthTypePasskey, fmt.Errorf("authenticator sign count indicates that it is cloned"))
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if credential.BackupEligible {
+		var prohibited bool
+
+		if prohibited, err = handleWebAuthnPasskeyProhibitBackupEligibility(ctx, user.Username); err != nil {
+			ctx.Logger.WithError(err).Errorf(logFmtErrObtainProfileDetails, regulation.AuthTypePasskey, user.Username)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		if prohibited {
+			_ = markAuthenticationAttempt(ctx, false, nil, user.Username, regulation.AuthTypePasskey, fmt.Errorf("the credential is eligible to be backed up which is prohibited by the filtering policy"))
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+	}
+
+	credential.UpdateSignInInfo(w.Config, ctx.Clock.Now().UTC(), c.Authenticator)
+
+	credential.BackupState = c.Flags.BackupState
+
+	if err = ctx.Providers.StorageProvider.UpdateWebAuthnCredentialSignIn(ctx, *credential); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a WebAuthn passkey authentication challenge for user '%s': error occurred saving the credential sign-in information to the storage backend", user.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	var (
+		hardware     = assertionResponse.ParsedPublicKeyCredential.AuthenticatorAttachment == protocol.CrossPlatform
+		userPresence = assertionResponse.Response.AuthenticatorData.Flags.HasUserPresent()
+		userVerified = assertionResponse.Response.AuthenticatorData.Flags.HasUserVerified()
+	)
+
+	if err = markAuthenticationAttemptWithMetadata(ctx, true, nil, user.Username, regulation.AuthTypePasskey, nil, map[string]any{
+		"user_presence": userPresence,
+		"user_verified": userVerified,
+	}); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	twoFactor := userVerified && ctx.Configuration.WebAuthn.PasskeyLogin.TwoFactorWithUserVerification
+
+	userSession, ok := handleFirstFactorSession(ctx, user.Username, bodyJSON.KeepMeLoggedIn, regulation.AuthTypePasskey, func(userSession *session.UserSession) {
+		userSession.SetOneFactorPasskey(hardware, userPresence, userVerified)
+
+		if twoFactor {
+			userSession.SetTwoFactorWebAuthn(ctx.Clock.Now(), hardware, userPresence, userVerified)
+		}
+	})
+	if !ok {
+		return
+	}
+
+	switch {
+	case bodyJSON.Workflow == workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case bodyJSON.Workflow == workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	case twoFactor:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	default:
+		Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
+	}
+}
+
+// handleWebAuthnDiscoverableUser returns a webauthn.DiscoverableUserHandler which loads the WebAuthn user with the user
+// handle returned by the authenticator, and stores it in user so the caller knows who attempted to authenticate.
+func handleWebAuthnDiscoverableUser(ctx *middlewares.AutheliaCtx, rpid string, user **model.WebAuthnUser) webauthn.DiscoverableUserHandler {
+	return func(_, userHandle []byte) (webauthn.User, error) {
+		u, err := ctx.Providers.StorageProvider.LoadWebAuthnUserByUserID(ctx, rpid, string(userHandle))
+
+		switch {
+		case err != nil:
+			return nil, err
+		case u == nil:
+			return nil, fmt.Errorf("no user was found with the user handle")
+		}
+
+		if u.Credentials, err = ctx.Providers.StorageProvider.LoadWebAuthnCredentialsByUsername(ctx, rpid, u.Username); err != nil {
+			return nil, err
+		}
+
+		if u.DisplayName == "" {
+			u.DisplayName = u.Username
+		}
+
+		*user = u
+
+		return u, nil
+	}
+}
+
+// handleWebAuthnPasskeyProhibitBackupEligibility returns true if the filtering policy prohibits credentials which are
+// eligible to be backed up for the user. The details of the user are only retrieved when a group policy could apply.
+func handleWebAuthnPasskeyProhibitBackupEligibility(ctx *middlewares.AutheliaCtx, username string) (prohibit bool, err error) {
+	filtering := &ctx.Configuration.WebAuthn.Filtering
+
+	if len(filtering.GroupPolicies) == 0 {
+		return filtering.ProhibitBackupEligibility, nil
+	}
+
+	var details *authentication.UserDetails
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(username); err != nil {
+		return false, err
+	}
+
+	return filtering.ProhibitBackupEligibilityForGroups(details.Groups), nil
+}
+
+// passkeyCredentialAssertion is the response of the passkey first factor GET endpoint.
+type passkeyCredentialAssertion struct {
+	*protocol.CredentialAssertion
+
+	Mediation string `json:"mediation,omitempty"`
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func newPasskeyWebAuthnConfiguration() *schema.WebAuthn {
+	config := schema.DefaultWebAuthnConfiguration
+
+	config.SelectionCriteria.Attachment = ""
+	config.SelectionCriteria.Discoverability = protocol.ResidentKeyRequirementPreferred
+	config.PasskeyLogin.Enable = true
+
+	return &config
+}
+
+func TestFirstFactorPasskeyGET(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected *regexp.Regexp
+	}{
+		{
+			"ShouldSuccess",
+			"",
+			regexp.MustCompile(`^\{"status":"OK","data":\{"publicKey":\{"challenge":"[a-zA-Z0-9/_-]+={0,2}","timeout":60000,"rpId":"login.example.com","userVerification":"required"}}}$`),
+		},
+		{
+			"ShouldSuccessConditionalMediation",
+			"conditional",
+			regexp.MustCompile(`^\{"status":"OK","data":\{"publicKey":\{"challenge":"[a-zA-Z0-9/_-]+={0,2}","timeout":60000,"rpId":"login.example.com","userVerification":"required"},"mediation":"conditional"}}$`),
+		},
+		{
+			"ShouldIgnoreUnknownMediation",
+			"silent",
+			regexp.MustCompile(`^\{"status":"OK","data":\{"publicKey":\{"challenge":"[a-zA-Z0-9/_-]+={0,2}","timeout":60000,"rpId":"login.example.com","userVerification":"required"}}}$`),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Configuration.WebAuthn = *newPasskeyWebAuthnConfiguration()
+
+			mock.Ctx.Request.Header.Set("X-Original-URL", "https://login.example.com:8080")
+
+			if tc.query != "" {
+				mock.Ctx.QueryArgs().Set(queryArgMediation, tc.query)
+			}
+
+			FirstFactorPasskeyGET(mock.Ctx)
+
+			assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+			assert.Regexp(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			us, err := mock.Ctx.GetSession()
+
+			require.NoError(t, err)
+
+			require.NotNil(t, us.WebAuthn)
+			require.NotNil(t, us.WebAuthn.SessionData)
+
+			assert.True(t, us.IsAnonymous())
+			assert.Nil(t, us.WebAuthn.SessionData.UserID)
+			assert.Equal(t, protocol.VerificationRequired, us.WebAuthn.SessionData.UserVerification)
+		})
+	}
+}
+
+func TestFirstFactorPasskeyPOST(t *testing.T) {
+	const (
+		dataReqFmt     = `{"response":{"id":"rwOwV8WCh1hrE0M6mvaoRGpGHidqK6IlhkDJ2xERhPU","rawId":"rwOwV8WCh1hrE0M6mvaoRGpGHidqK6IlhkDJ2xERhPU","response":{"authenticatorData":"DGygg5w6VoNVeDP2GKJVZmXfKgiJZHh9U4ULStTTvtwFAAAAAw","clientDataJSON":"%s","signature":"MEQCIBlJ2Fxf6ZwLNTCQglz0AW0pD4HlU8W5Yk696jjfxVxhAiAhAMkLh8iKyhW6zSmzwfQDjMF2nKjVHzEs7jLHRPDZ2A"%s},"type":"public-key","clientExtensionResults":{},"authenticatorAttachment":"cross-platform"},"keepMeLoggedIn":true}`
+		dataClientJSON = `{"type":"webauthn.get","challenge":"in1cL-oWfSjSd7uuwUvv2ndOAmRXb0cOAbUoTtAqvGE","origin":"%s","crossOrigin":false,"other_keys_can_be_added_here":"do not compare clientDataJSON against a template. See https://goo.gl/yabPex"}`
+		userID         = "ZytlJlVuWzdgN2BxTyI8Uy9uS2xpJSdsT2ZsJUA5UEBve1c2NENCKDNSWWphaGVC"
+	)
+
+	var (
+		clientDataJSON     = base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(dataClientJSON, "https://login.example.com:8080")))
+		dataReqGood        = fmt.Sprintf(dataReqFmt, clientDataJSON, fmt.Sprintf(`,"userHandle":"%s"`, base64.RawURLEncoding.EncodeToString([]byte(userID))))
+		dataReqNoHandle    = fmt.Sprintf(dataReqFmt, clientDataJSON, "")
+		dataReqBadRPIDHash = fmt.Sprintf(dataReqFmt, base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(dataClientJSON, "http://example.com"))), fmt.Sprintf(`,"userHandle":"%s"`, base64.RawURLEncoding.EncodeToString([]byte(userID))))
+	)
+
+	decode := func(in string) []byte {
+		value, err := base64.StdEncoding.DecodeString(in)
+		if err != nil {
+			t.Fatal("Failed to decode base64 string:", err)
+		}
+
+		return value
+	}
+
+	credential := model.WebAuthnCredential{
+		ID:              1,
+		RPID:            "login.example.com",
+		Username:        testUsername,
+		Description:     "passkey",
+		KID:             model.NewBase64(decode("rwOwV8WCh1hrE0M6mvaoRGpGHidqK6IlhkDJ2xERhPU=")),
+		AttestationType: "packed",
+		Attachment:      "cross-platform",
+		Transport:       "usb",
+		Discoverable:    true,
+		Present:         true,
+		Verified:        true,
+		PublicKey:       []byte{165, 1, 2, 3, 38, 32, 1, 33, 88, 32, 184, 17, 198, 170, 14, 81, 23, 237, 100, 218, 123, 122, 48, 76, 56, 148, 23, 111, 173, 245, 67, 239, 176, 229, 199, 205, 213, 46, 239, 91, 222, 183, 34, 88, 32, 171, 141, 116, 74, 68, 180, 81, 66, 81, 127, 81, 41, 236, 173, 38, 7, 9, 34, 128, 167, 101, 51, 25, 84, 239, 100, 10, 124, 117, 165, 178, 179},
+	}
+
+	setupSession := func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+		us, err := mock.Ctx.GetSession()
+
+		require.NoError(t, err)
+
+		us.WebAuthn = &session.WebAuthn{
+			SessionData: &webauthn.SessionData{
+				Challenge:        "in1cL-oWfSjSd7uuwUvv2ndOAmRXb0cOAbUoTtAqvGE",
+				Expires:          time.Now().Add(time.Minute),
+				UserVerification: protocol.VerificationRequired,
+			},
+		}
+
+		require.NoError(t, mock.Ctx.SaveSession(us))
+	}
+
+	setupSuccess := func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+		setupSession(t, mock)
+
+		gomock.InOrder(
+			mock.StorageMock.
+				EXPECT().
+				LoadWebAuthnUserByUserID(mock.Ctx, "login.example.com", userID).
+				Return(&model.WebAuthnUser{ID: 1, RPID: "login.example.com", Username: testUsername, UserID: userID}, nil),
+			mock.StorageMock.
+				EXPECT().
+				LoadWebAuthnCredentialsByUsername(mock.Ctx, "login.example.com", testUsername).
+				Return([]model.WebAuthnCredential{credential}, nil),
+			mock.StorageMock.
+				EXPECT().
+				UpdateWebAuthnCredentialSignIn(mock.Ctx, gomock.Any()).
+				Return(nil),
+			mock.StorageMock.
+				EXPECT().
+				AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+				Return(nil),
+			mock.UserProviderMock.
+				EXPECT().
+				GetDetails(testUsername).
+				Return(&authentication.UserDetails{Username: testUsername, Emails: []string{"john@example.com"}, Groups: []string{"dev"}}, nil),
+		)
+	}
+
+	testCases := []struct {
+		name          string
+		config        func(config *schema.WebAuthn)
+		setup         func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		have          string
+		code          int
+		expectedLog   string
+		expectedError string
+		expected      func(t *testing.T, us session.UserSession)
+	}{
+		{
+			"ShouldSuccess",
+			nil,
+			setupSuccess,
+			dataReqGood,
+			fasthttp.StatusOK,
+			"",
+			"",
+			func(t *testing.T, us session.UserSession) {
+				assert.Equal(t, testUsername, us.Username)
+				assert.Equal(t, authentication.OneFactor, us.AuthenticationLevel)
+				assert.True(t, us.KeepMeLoggedIn)
+				assert.Nil(t, us.WebAuthn)
+
+				assert.False(t, us.AuthenticationMethodRefs.UsernameAndPassword)
+				assert.True(t, us.AuthenticationMethodRefs.WebAuthn)
+				assert.True(t, us.AuthenticationMethodRefs.WebAuthnHardware)
+				assert.True(t, us.AuthenticationMethodRefs.WebAuthnUserPresence)
+				assert.True(t, us.AuthenticationMethodRefs.WebAuthnUserVerified)
+			},
+		},
+		{
+			"ShouldSuccessTwoFactorWithUserVerification",
+			func(config *schema.WebAuthn) {
+				config.PasskeyLogin.TwoFactorWithUserVerification = true
+			},
+			setupSuccess,
+			dataReqGood,
+			fasthttp.StatusOK,
+			"",
+			"",
+			func(t *testing.T, us session.UserSession) {
+				assert.Equal(t, testUsername, us.Username)
+				assert.Equal(t, authentication.TwoFactor, us.AuthenticationLevel)
+				assert.False(t, us.AuthenticationMethodRefs.UsernameAndPassword)
+				assert.True(t, us.AuthenticationMethodRefs.WebAuthn)
+			},
+		},
+		{
+			"ShouldFailBadBody",
+			nil,
+			nil,
+			``,
+			fasthttp.StatusUnauthorized,
+			"Failed to parse Passkey request body",
+			"unable to parse body: unexpected end of JSON input",
+			nil,
+		},
+		{
+			"ShouldFailWithoutSessionData",
+			nil,
+			nil,
+			dataReqGood,
+			fasthttp.StatusUnauthorized,
+			"Error occurred validating a WebAuthn passkey authentication challenge: error occurred retrieving the user session data",
+			"challenge session data is not present",
+			nil,
+		},
+		{
+			"ShouldFailWithoutUserHandle",
+			nil,
+			setupSession,
+			dataReqNoHandle,
+			fasthttp.StatusUnauthorized,
+			"Error occurred validating a WebAuthn passkey authentication challenge: error occurred looking up the user the passkey belongs to",
+			"Client-side Discoverable Assertion was attempted with a blank User Handle",
+			nil,
+		},
+		{
+			"ShouldFailUserNotFound",
+			nil,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setupSession(t, mock)
+
+				mock.StorageMock.
+					EXPECT().
+					LoadWebAuthnUserByUserID(mock.Ctx, "login.example.com", userID).
+					Return(nil, nil)
+			},
+			dataReqGood,
+			fasthttp.StatusUnauthorized,
+			"Error occurred validating a WebAuthn passkey authentication challenge: error occurred looking up the user the passkey belongs to",
+			"Failed to lookup Client-side Discoverable Credential: no user was found with the user handle",
+			nil,
+		},
+		{
+			"ShouldFailBadOrigin",
+			nil,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setupSession(t, mock)
+
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnUserByUserID(mock.Ctx, "login.example.com", userID).
+						Return(&model.WebAuthnUser{ID: 1, RPID: "login.example.com", Username: testUsername, UserID: userID}, nil),
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnCredentialsByUsername(mock.Ctx, "login.example.com", testUsername).
+						Return([]model.WebAuthnCredential{credential}, nil),
+					mock.StorageMock.
+						EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+				)
+			},
+			dataReqBadRPIDHash,
+			fasthttp.StatusUnauthorized,
+			"Unsuccessful Passkey authentication attempt by user 'john'",
+			"Error validating origin (verification_error): Expected Values: [https://login.example.com:8080], Received: http://example.com",
+			nil,
+		},
+		{
+			"ShouldFailBackupEligibleCredentialWhenProhibited",
+			func(config *schema.WebAuthn) {
+				config.Filtering.ProhibitBackupEligibility = true
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setupSession(t, mock)
+
+				eligible := credential
+				eligible.BackupEligible = true
+
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnUserByUserID(mock.Ctx, "login.example.com", userID).
+						Return(&model.WebAuthnUser{ID: 1, RPID: "login.example.com", Username: testUsername, UserID: userID}, nil),
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnCredentialsByUsername(mock.Ctx, "login.example.com", testUsername).
+						Return([]model.WebAuthnCredential{eligible}, nil),
+					mock.StorageMock.
+						EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+				)
+			},
+			dataReqGood,
+			fasthttp.StatusUnauthorized,
+			"Unsuccessful Passkey authentication attempt by user 'john'",
+			"the credential is eligible to be backed up which is prohibited by the filtering policy",
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			config := newPasskeyWebAuthnConfiguration()
+
+			if tc.config != nil {
+				tc.config(config)
+			}
+
+			mock.Ctx.Configuration.WebAuthn = *config
+
+			mock.Ctx.Request.Header.Set("X-Original-URL", "https://login.example.com:8080")
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.have)
+
+			FirstFactorPasskeyPOST(mock.Ctx)
+
+			assert.Equal(t, tc.code, mock.Ctx.Response.StatusCode())
+
+			if tc.expectedLog != "" {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), tc.expectedLog, tc.expectedError)
+			}
+
+			if tc.expected == nil {
+				return
+			}
+
+			us, err := mock.Ctx.GetSession()
+			require.NoError(t, err)
+
+			tc.expected(t, us)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+// FirstFactorSPNEGOPOST is the handler performing the first factor with the Kerberos ticket the browser sends with the
+// SPNEGO (Negotiate) authentication scheme. Requests without a ticket are challenged with the Negotiate scheme, and
+// requests with a ticket which isn't valid are rejected without a challenge so the browser falls back to the login form.
+func FirstFactorSPNEGOPOST(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodyFirstFactorSPNEGORequest{}
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrParseRequestBody, regulation.AuthTypeSPNEGO)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	username, err := ctx.Providers.SPNEGO.Authenticate(ctx.Request.Header.PeekBytes(headerAuthorization))
+
+	switch {
+	case errors.Is(err, authentication.ErrSPNEGONoToken):
+		ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, headerValueAuthenticateNegotiate)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	case err != nil:
+		ctx.Logger.WithError(err).Error("Error occurred validating the SPNEGO token")
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			_ = markAuthenticationAttempt(ctx, false, &bannedUntil, username, regulation.AuthTypeSPNEGO, nil)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		ctx.Logger.WithError(err).Errorf(logFmtErrRegulationFail, regulation.AuthTypeSPNEGO, username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, username, regulation.AuthTypeSPNEGO, nil); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	userSession, ok := handleFirstFactorSession(ctx, username, bodyJSON.KeepMeLoggedIn, regulation.AuthTypeSPNEGO, nil)
+	if !ok {
+		return
+	}
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
+		Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestFirstFactorSPNEGOPOST(t *testing.T) {
+	testCases := []struct {
+		name          string
+		setup         func(t *testing.T, mock *mocks.MockAutheliaCtx, spnego *mocks.MockSPNEGOProvider)
+		body          string
+		code          int
+		authenticate  string
+		expectedLog   string
+		expectedError string
+		username      string
+	}{
+		{
+			"ShouldChallengeWithoutToken",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, spnego *mocks.MockSPNEGOProvider) {
+				spnego.EXPECT().Authenticate(gomock.Any()).Return("", authentication.ErrSPNEGONoToken)
+			},
+			`{}`,
+			fasthttp.StatusUnauthorized,
+			"Negotiate",
+			"",
+			"",
+			"",
+		},
+		{
+			"ShouldFailWithInvalidToken",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, spnego *mocks.MockSPNEGOProvider) {
+				mock.Ctx.Request.Header.Set(fasthttp.HeaderAuthorization, "Negotiate YIIBhAYGKwYBBQUCoIIBeDCCAXSgMDAuBgkqhkiC9xIBAgIGCSqGSIb3EgECAgYKKwYBBAGCNwICHgYKKwYBBAGCNwICCqKCAT4EggE6")
+
+				spnego.EXPECT().Authenticate([]byte("Negotiate YIIBhAYGKwYBBQUCoIIBeDCCAXSgMDAuBgkqhkiC9xIBAgIGCSqGSIb3EgECAgYKKwYBBAGCNwICHgYKKwYBBAGCNwICCqKCAT4EggE6")).
+					Return("", errors.New("error validating the kerberos ticket: clock skew with client too large"))
+			},
+			`{}`,
+			fasthttp.StatusUnauthorized,
+			"",
+			"Error occurred validating the SPNEGO token",
+			"error validating the kerberos ticket: clock skew with client too large",
+			"",
+		},
+		{
+			"ShouldFailWithBadBody",
+			nil,
+			``,
+			fasthttp.StatusUnauthorized,
+			"",
+			"Failed to parse SPNEGO request body",
+			"unable to parse body: unexpected end of JSON input",
+			"",
+		},
+		{
+			"ShouldFailWhenUserNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, spnego *mocks.MockSPNEGOProvider) {
+				gomock.InOrder(
+					spnego.EXPECT().Authenticate(gomock.Any()).Return("john", nil),
+					mock.StorageMock.EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+					mock.UserProviderMock.EXPECT().GetDetails("john").Return(nil, authentication.ErrUserNotFound),
+				)
+			},
+			`{}`,
+			fasthttp.StatusUnauthorized,
+			"",
+			"Could not obtain profile details during SPNEGO authentication for user 'john'",
+			"user not found",
+			"",
+		},
+		{
+			"ShouldAuthenticate",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, spnego *mocks.MockSPNEGOProvider) {
+				gomock.InOrder(
+					spnego.EXPECT().Authenticate(gomock.Any()).Return("john", nil),
+					mock.StorageMock.EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+					mock.UserProviderMock.EXPECT().GetDetails("john").Return(&authentication.UserDetails{
+						Username: "john",
+						Emails:   []string{"john@example.com"},
+						Groups:   []string{"dev"},
+					}, nil),
+				)
+			},
+			`{"keepMeLoggedIn":true}`,
+			fasthttp.StatusOK,
+			"",
+			"",
+			"",
+			"john",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			spnego := mocks.NewMockSPNEGOProvider(mock.Ctrl)
+
+			mock.Ctx.Providers.SPNEGO = spnego
+
+			if tc.setup != nil {
+				tc.setup(t, mock, spnego)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			FirstFactorSPNEGOPOST(mock.Ctx)
+
+			assert.Equal(t, tc.code, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.authenticate, string(mock.Ctx.Response.Header.PeekBytes(headerWWWAuthenticate)))
+
+			if tc.expectedLog != "" {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), tc.expectedLog, tc.expectedError)
+			}
+
+			if tc.username == "" {
+				return
+			}
+
+			userSession, err := mock.Ctx.GetSession()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.username, userSession.Username)
+			assert.Equal(t, authentication.OneFactor, userSession.AuthenticationLevel)
+			assert.True(t, userSession.KeepMeLoggedIn)
+		})
+	}
+}
@@ -1,10 +1,70 @@
 package handlers
 
 import (
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/health"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // HealthGET can be used by health checks.
 func HealthGET(ctx *middlewares.AutheliaCtx) {
 	ctx.ReplyOK()
 }
+
+// HealthReadyGET can be used by readiness probes. It responds with a 503 Service Unavailable if any of the critical
+// dependencies are failing their health checks.
+func HealthReadyGET(ctx *middlewares.AutheliaCtx) {
+	if ctx.Providers.Health == nil || ctx.Providers.Health.Ready(ctx) {
+		ctx.ReplyOK()
+
+		return
+	}
+
+	ctx.ReplyStatusCode(fasthttp.StatusServiceUnavailable)
+}
+
+// HealthDetailsGET responds with the detailed health report of all of the dependencies. Only users in the configured
+// admin groups are permitted to view the report.
+func HealthDetailsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred retrieving user session")
+
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	if !utils.IsStringSliceContainsAny(userSession.Groups, ctx.Configuration.Server.Endpoints.Health.AdminGroups) {
+		ctx.Logger.Debugf("User '%s' is not permitted to view the health details as they are not a member of any of the admin groups", userSession.Username)
+
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	if ctx.Providers.Health == nil {
+		ctx.ReplyStatusCode(fasthttp.StatusNotFound)
+
+		return
+	}
+
+	report := ctx.Providers.Health.Report(ctx)
+
+	statusCode := fasthttp.StatusOK
+
+	if report.Status == health.StatusDown {
+		statusCode = fasthttp.StatusServiceUnavailable
+	}
+
+	if err = ctx.ReplyJSON(report, statusCode); err != nil {
+		ctx.Error(err, messageOperationFailed)
+	}
+}
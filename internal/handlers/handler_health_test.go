@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/health"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/session"
 )
@@ -25,3 +31,76 @@ func TestHealthOk(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
 	assert.Equal(t, okMessageBytes, mock.Ctx.Response.Body())
 }
+
+func TestHealthReady(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	HealthReadyGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, okMessageBytes, mock.Ctx.Response.Body())
+
+	mock.Ctx.Providers.Health = health.NewProvider(&mock.Clock, time.Second, time.Second)
+	mock.Ctx.Providers.Health.Register("storage", true, health.CheckFunc(func(ctx context.Context) error {
+		return errors.New("database is locked")
+	}))
+
+	mock.Ctx.Response.Reset()
+
+	HealthReadyGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusServiceUnavailable, mock.Ctx.Response.StatusCode())
+}
+
+func TestHealthDetails(t *testing.T) {
+	testCases := []struct {
+		name     string
+		groups   []string
+		admin    []string
+		expected int
+	}{
+		{"ShouldForbidWithoutAdminGroups", []string{"admins"}, nil, fasthttp.StatusForbidden},
+		{"ShouldForbidNonAdmin", []string{"dev"}, []string{"admins"}, fasthttp.StatusForbidden},
+		{"ShouldAllowAdmin", []string{"dev", "admins"}, []string{"admins"}, fasthttp.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtxWithUserSession(t, session.UserSession{
+				Username:            "john",
+				Groups:              tc.groups,
+				AuthenticationLevel: authentication.TwoFactor,
+			})
+			defer mock.Close()
+
+			mock.Ctx.Configuration.Server.Endpoints.Health.AdminGroups = tc.admin
+			mock.Ctx.Providers.Health = health.NewProvider(&mock.Clock, time.Second, time.Second)
+			mock.Ctx.Providers.Health.Register("storage", true, health.CheckFunc(func(ctx context.Context) error {
+				return errors.New("database is locked")
+			}))
+
+			HealthDetailsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expected, mock.Ctx.Response.StatusCode())
+
+			if tc.expected == fasthttp.StatusForbidden {
+				return
+			}
+
+			report := struct {
+				Status string `json:"status"`
+				Checks []struct {
+					Name      string `json:"name"`
+					LastError string `json:"last_error"`
+				} `json:"checks"`
+			}{}
+
+			require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &report))
+			assert.Equal(t, health.StatusDown, report.Status)
+			require.Len(t, report.Checks, 1)
+			assert.Equal(t, "storage", report.Checks[0].Name)
+			assert.Equal(t, "database is locked", report.Checks[0].LastError)
+		})
+	}
+}
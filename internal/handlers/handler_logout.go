@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
@@ -25,9 +26,25 @@ func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to parse body during logout: %w", err), messageOperationFailed)
 	}
 
+	userSession, _ := ctx.GetSession()
+
+	id := getUserSessionID(ctx)
+
 	err = ctx.DestroySession()
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to destroy session during logout: %w", err), messageOperationFailed)
+	} else {
+		unindexUserSession(ctx, id)
+	}
+
+	if userSession.Username != "" {
+		ctx.AuditEvent(audit.EventTypeSessionLogout, audit.NewResult(err == nil), userSession.Username, nil)
+
+		if ctx.Providers.SAML != nil {
+			if err = ctx.Providers.StorageProvider.RevokeSAMLSessionsByUsername(ctx, userSession.Username, ctx.Clock.Now()); err != nil {
+				ctx.Logger.WithError(err).Errorf("Error occurred revoking the SAML sessions of user '%s' during logout", userSession.Username)
+			}
+		}
 	}
 
 	redirectionURL, err := url.ParseRequestURI(body.TargetURL)
@@ -0,0 +1,544 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// MobileAuthenticatePOST authenticates a user of a native app with their password and a TOTP code, registers the
+// mobile device, and responds with the access token and the refresh token issued to the device.
+func MobileAuthenticatePOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		var successful bool
+
+		requestTime := time.Now()
+
+		if delayFunc != nil {
+			defer delayFunc(ctx, requestTime, &successful)
+		}
+
+		var (
+			bodyJSON bodyMobileAuthenticateRequest
+			details  *authentication.UserDetails
+			err      error
+		)
+
+		if err = ctx.ParseBody(&bodyJSON); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device: %s", errStrReqBodyParse)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		if len(bodyJSON.DeviceName) > mobileDeviceNameMaxLength || len(bodyJSON.Platform) > mobileDevicePlatformMaxLength {
+			ctx.Logger.Errorf("Error occurred authenticating a mobile device for user '%s': the device name must not exceed %d characters and the platform must not exceed %d characters", bodyJSON.Username, mobileDeviceNameMaxLength, mobileDevicePlatformMaxLength)
+
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		if err = mobileCheckFirstFactor(ctx, bodyJSON.Username, bodyJSON.Password); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device for user '%s': error occurred validating the password", bodyJSON.Username)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		if err = mobileCheckSecondFactor(ctx, bodyJSON.Username, bodyJSON.Token); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device for user '%s': error occurred validating the TOTP code", bodyJSON.Username)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if details, err = ctx.Providers.UserProvider.GetDetails(bodyJSON.Username); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device for user '%s': error occurred retrieving the user details", bodyJSON.Username)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+
+		now := ctx.Clock.Now()
+
+		device := model.MobileDevice{
+			PublicID:  uuid.New(),
+			Username:  details.Username,
+			Name:      bodyJSON.DeviceName,
+			Platform:  bodyJSON.Platform,
+			CreatedAt: now,
+		}
+
+		response := newMobileTokens(ctx, &device, now)
+
+		if err = ctx.Providers.StorageProvider.SaveMobileDevice(ctx, device); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device for user '%s': error occurred saving the device to the storage backend", device.Username)
+
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		ctx.AuditEvent(audit.EventTypeMobileDeviceRegister, audit.ResultSuccess, device.Username, map[string]any{"id": device.PublicID.String(), "name": device.Name, "platform": device.Platform})
+
+		successful = true
+
+		if err = ctx.SetJSONBody(response); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred authenticating a mobile device for user '%s': %s", device.Username, errStrRespBody)
+		}
+	}
+}
+
+// MobileTokenPOST exchanges the refresh token of a mobile device for a new access token and a new refresh token. The
+// refresh token is bound to the device it was issued to and can only be exchanged once, and exchanging a refresh token
+// which has already been exchanged revokes the device.
+func MobileTokenPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON bodyMobileTokenRequest
+		publicID uuid.UUID
+		device   *model.MobileDevice
+		err      error
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of a mobile device: %s", errStrReqBodyParse)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if publicID, err = uuid.Parse(bodyJSON.DeviceID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s': error occurred parsing the device id", bodyJSON.DeviceID)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if device, err = ctx.Providers.StorageProvider.LoadMobileDevice(ctx, publicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s': error occurred loading the device from the storage backend", publicID)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	if device.IsRevoked() || device.IsExpired(now) {
+		ctx.Logger.WithError(errMobileTokenInvalid).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': the device is revoked or the refresh token is expired", publicID, device.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	signature := mobileTokenSignature(bodyJSON.RefreshToken)
+
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(device.RefreshSignature)) != 1 {
+		if device.PreviousRefreshSignature.Valid && subtle.ConstantTimeCompare([]byte(signature), []byte(device.PreviousRefreshSignature.String)) == 1 {
+			ctx.Logger.Warnf("Mobile device with id '%s' for user '%s' used a refresh token which has already been exchanged, the device is being revoked", publicID, device.Username)
+
+			revokeMobileDevice(ctx, device, "refresh_token_reuse")
+		} else {
+			ctx.Logger.WithError(errMobileTokenInvalid).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': the refresh token doesn't match", publicID, device.Username)
+		}
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if _, err = ctx.Providers.Regulator.Regulate(ctx, device.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': error occurred performing regulation", publicID, device.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if _, err = ctx.Providers.UserProvider.GetDetails(device.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': error occurred retrieving the user details", publicID, device.Username)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	device.PreviousRefreshSignature = sql.NullString{String: device.RefreshSignature, Valid: true}
+	device.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+
+	response := newMobileTokens(ctx, device, now)
+
+	if err = ctx.Providers.StorageProvider.UpdateMobileDeviceTokens(ctx, *device); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': error occurred saving the tokens to the storage backend", publicID, device.Username)
+
+		if errors.Is(err, storage.ErrNoMobileDevice) {
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError(messageOperationFailed)
+		}
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred refreshing the tokens of mobile device with id '%s' for user '%s': %s", publicID, device.Username, errStrRespBody)
+	}
+}
+
+// MobileUserInfoGET returns the details of the user the access token of the mobile device was issued to.
+func MobileUserInfoGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		device  *model.MobileDevice
+		details *authentication.UserDetails
+		err     error
+	)
+
+	if device, err = loadMobileDeviceFromAccessToken(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred retrieving the user details for a mobile device: error occurred validating the access token")
+
+		respondMobileUnauthorized(ctx)
+
+		return
+	}
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(device.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the user details for mobile device with id '%s' for user '%s'", device.PublicID, device.Username)
+
+		respondMobileUnauthorized(ctx)
+
+		return
+	}
+
+	response := mobileUserInfoResponse{
+		DeviceID:    device.PublicID,
+		Username:    details.Username,
+		DisplayName: details.DisplayName,
+		Emails:      details.Emails,
+		Groups:      details.Groups,
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the user details for mobile device with id '%s' for user '%s': %s", device.PublicID, device.Username, errStrRespBody)
+	}
+}
+
+// MobileDevicePushPUT enrolls the mobile device the access token was issued to for push notifications by saving the
+// push notification provider and the device token issued by the provider.
+func MobileDevicePushPUT(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON bodyMobileDevicePushRequest
+		device   *model.MobileDevice
+		err      error
+	)
+
+	if device, err = loadMobileDeviceFromAccessToken(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred enrolling a mobile device for push notifications: error occurred validating the access token")
+
+		respondMobileUnauthorized(ctx)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred enrolling mobile device with id '%s' for user '%s' for push notifications: %s", device.PublicID, device.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	switch {
+	case bodyJSON.Provider != mobilePushProviderAPNS && bodyJSON.Provider != mobilePushProviderFCM:
+		err = fmt.Errorf("the provider must be one of '%s' or '%s' but it's '%s'", mobilePushProviderAPNS, mobilePushProviderFCM, bodyJSON.Provider)
+	case len(bodyJSON.Token) > mobilePushTokenMaxLength:
+		err = fmt.Errorf("the token must not exceed %d characters but it has %d characters", mobilePushTokenMaxLength, len(bodyJSON.Token))
+	}
+
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred enrolling mobile device with id '%s' for user '%s' for push notifications", device.PublicID, device.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.UpdateMobileDevicePush(ctx, device.PublicID, bodyJSON.Provider, bodyJSON.Token); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred enrolling mobile device with id '%s' for user '%s' for push notifications: error occurred saving the registration to the storage backend", device.PublicID, device.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// MobilePushChallengePOST approves or denies a push challenge delivered to the mobile device the access token was
+// issued to. The push challenge must be for the user of the device and either for the device or all of the devices of
+// the user.
+func MobilePushChallengePOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON  bodyPushChallengeRequest
+		device    *model.MobileDevice
+		challenge *model.PushChallenge
+		id        uuid.UUID
+		err       error
+	)
+
+	if device, err = loadMobileDeviceFromAccessToken(ctx); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred responding to a push challenge with a mobile device: error occurred validating the access token")
+
+		respondMobileUnauthorized(ctx)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred responding to a push challenge with mobile device with id '%s' for user '%s': %s", device.PublicID, device.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if id, err = uuid.Parse(bodyJSON.ID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred responding to a push challenge with mobile device with id '%s' for user '%s': error occurred parsing the identifier", device.PublicID, device.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if challenge, err = ctx.Providers.StorageProvider.LoadPushChallenge(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred responding to push challenge with id '%s' with mobile device with id '%s' for user '%s': error occurred retrieving the push challenge from the storage backend", id, device.PublicID, device.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if challenge.Username != device.Username || (challenge.Device != "" && challenge.Device != device.PublicID.String()) {
+		ctx.Logger.Errorf("Error occurred responding to push challenge with id '%s' with mobile device with id '%s' for user '%s': the push challenge was not delivered to the device", id, device.PublicID, device.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	respondPushChallenge(ctx, id, bodyJSON.Token, bodyJSON.Approve)
+}
+
+// MobileDeviceDELETE signs out the mobile device the access token was issued to by revoking the device which
+// invalidates the access token and the refresh token of the device.
+func MobileDeviceDELETE(ctx *middlewares.AutheliaCtx) {
+	device, err := loadMobileDeviceFromAccessToken(ctx)
+	if err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred signing out a mobile device: error occurred validating the access token")
+
+		respondMobileUnauthorized(ctx)
+
+		return
+	}
+
+	if !revokeMobileDevice(ctx, device, "sign_out") {
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// mobileCheckFirstFactor validates the password of the user with the same regulation as the first factor endpoint.
+func mobileCheckFirstFactor(ctx *middlewares.AutheliaCtx, username, password string) (err error) {
+	var (
+		bannedUntil time.Time
+		valid       bool
+	)
+
+	if bannedUntil, err = ctx.Providers.Regulator.Regulate(ctx, username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			_ = markAuthenticationAttempt(ctx, false, &bannedUntil, username, regulation.AuthType1FA, nil)
+		}
+
+		return err
+	}
+
+	if valid, err = ctx.Providers.UserProvider.CheckUserPassword(username, password); err != nil {
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthType1FA, err)
+
+		return err
+	}
+
+	if !valid {
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthType1FA, nil)
+
+		return fmt.Errorf("the password isn't valid")
+	}
+
+	return markAuthenticationAttempt(ctx, true, nil, username, regulation.AuthType1FA, nil)
+}
+
+// mobileCheckSecondFactor validates the TOTP code of the user with the same security policies as the TOTP endpoint.
+func mobileCheckSecondFactor(ctx *middlewares.AutheliaCtx, username, code string) (err error) {
+	var (
+		config        *model.TOTPConfiguration
+		valid, exists bool
+		step          uint64
+	)
+
+	if n := len(code); n != 6 && n != 8 {
+		return fmt.Errorf("expected code length is 6 or 8 but the user provided code was %d characters in length", n)
+	}
+
+	if config, err = ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, username); err != nil {
+		return fmt.Errorf("error occurred retrieving the TOTP configuration from the storage backend: %w", err)
+	}
+
+	drift := config.Drift
+
+	if valid, step, err = ctx.Providers.TOTP.Validate(ctx, code, config); err != nil {
+		return fmt.Errorf("error occurred validating the TOTP code: %w", err)
+	}
+
+	if !valid {
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthTypeTOTP, nil)
+
+		return fmt.Errorf("the TOTP code isn't valid")
+	}
+
+	if exists, err = ctx.Providers.StorageProvider.ExistsTOTPHistory(ctx, username, step*uint64(config.Period)); err != nil {
+		return fmt.Errorf("error occurred checking the TOTP history: %w", err)
+	}
+
+	if exists {
+		if !ctx.Configuration.TOTP.DisableReuseSecurityPolicy {
+			return fmt.Errorf("the user has already used this code recently and will not be permitted to reuse it")
+		}
+
+		ctx.Logger.WithFields(map[string]any{"username": username}).Warn("User has reused a Time-based One Time Password with the given step but the policy to disable reuse is disabled")
+	} else if err = ctx.Providers.StorageProvider.SaveTOTPHistory(ctx, username, step*uint64(config.Period)); err != nil {
+		return fmt.Errorf("error occurred saving the TOTP history to the storage backend: %w", err)
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, username, regulation.AuthTypeTOTP, nil); err != nil {
+		return err
+	}
+
+	config.UpdateSignInInfo(ctx.Clock.Now())
+
+	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt); err != nil {
+		return fmt.Errorf("error occurred saving the credential sign-in information to the storage backend: %w", err)
+	}
+
+	handleTOTPDrift(ctx, config, drift, step)
+
+	return nil
+}
+
+// loadMobileDeviceFromAccessToken loads the mobile device the bearer access token of the request was issued to. The
+// device must not be revoked and the access token must not be expired.
+func loadMobileDeviceFromAccessToken(ctx *middlewares.AutheliaCtx) (device *model.MobileDevice, err error) {
+	scheme, token, found := bytes.Cut(ctx.Request.Header.PeekBytes(headerAuthorization), []byte(" "))
+
+	if !found || !bytes.EqualFold(scheme, []byte("bearer")) || !bytes.HasPrefix(token, []byte(mobileAccessTokenPrefix)) {
+		return nil, errMobileTokenInvalid
+	}
+
+	if device, err = ctx.Providers.StorageProvider.LoadMobileDeviceByAccessSignature(ctx, mobileTokenSignature(string(token))); err != nil {
+		if errors.Is(err, storage.ErrNoMobileDevice) {
+			return nil, errMobileTokenInvalid
+		}
+
+		return nil, err
+	}
+
+	if device.IsRevoked() || device.IsAccessExpired(ctx.Clock.Now()) {
+		return nil, errMobileTokenInvalid
+	}
+
+	return device, nil
+}
+
+// revokeMobileDevice revokes the mobile device and records the audit event, returning false if the device couldn't be
+// revoked.
+func revokeMobileDevice(ctx *middlewares.AutheliaCtx, device *model.MobileDevice, reason string) bool {
+	if err := ctx.Providers.StorageProvider.RevokeMobileDevice(ctx, device.PublicID, ctx.Clock.Now()); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking mobile device with id '%s' for user '%s'", device.PublicID, device.Username)
+
+		return false
+	}
+
+	ctx.AuditEvent(audit.EventTypeMobileDeviceRevoke, audit.ResultSuccess, device.Username, map[string]any{"id": device.PublicID.String(), "reason": reason})
+
+	return true
+}
+
+// newMobileTokens generates a new access token and a new refresh token for the mobile device, updating the signatures
+// and expiration of the device, and returns the response which includes the tokens.
+func newMobileTokens(ctx *middlewares.AutheliaCtx, device *model.MobileDevice, now time.Time) (response mobileTokenResponse) {
+	config := ctx.Configuration.Server.Endpoints.Mobile
+
+	response = mobileTokenResponse{
+		DeviceID:              device.PublicID,
+		TokenType:             "Bearer",
+		AccessToken:           mobileAccessTokenPrefix + ctx.Providers.Random.StringCustom(64, random.CharSetAlphaNumeric),
+		ExpiresIn:             int(config.AccessTokenLifespan.Seconds()),
+		RefreshToken:          mobileRefreshTokenPrefix + ctx.Providers.Random.StringCustom(64, random.CharSetAlphaNumeric),
+		RefreshTokenExpiresIn: int(config.RefreshTokenLifespan.Seconds()),
+	}
+
+	device.AccessSignature = mobileTokenSignature(response.AccessToken)
+	device.AccessExpiresAt = now.Add(config.AccessTokenLifespan)
+	device.RefreshSignature = mobileTokenSignature(response.RefreshToken)
+	device.ExpiresAt = now.Add(config.RefreshTokenLifespan)
+
+	return response
+}
+
+// mobileTokenSignature returns the signature of a token issued to a mobile device which is the value persisted in the
+// storage backend.
+func mobileTokenSignature(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func respondMobileUnauthorized(ctx *middlewares.AutheliaCtx) {
+	ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, headerValueAuthenticateBearer)
+
+	respondUnauthorized(ctx, messageAuthenticationFailed)
+}
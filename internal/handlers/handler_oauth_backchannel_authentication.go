@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"authelia.com/provider/oauth2/x/errorsx"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+// OAuthBackchannelAuthenticationPOST handles POST requests to the OpenID Connect 1.0 Client Initiated Backchannel
+// Authentication endpoint. The user identified by the login hint is asked to approve the request out-of-band using the
+// oidc.BackchannelAuthenticator, and the client later exchanges the returned auth_req_id at the token endpoint.
+//
+// https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.7
+func OAuthBackchannelAuthenticationPOST(authenticator oidc.BackchannelAuthenticator) middlewares.AutheliaHandlerFunc {
+	return func(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+		var (
+			client    oidc.Client
+			requester *oauthelia2.Request
+			details   *authentication.UserDetails
+			subject   uuid.UUID
+			txid      string
+			request   *model.OAuth2CIBARequest
+			err       error
+		)
+
+		if err = req.ParseForm(); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request failed with error: %+v", err)
+
+			errorsx.WriteJSONError(rw, req, oauthelia2.ErrInvalidRequest.WithHint("Unable to parse the HTTP body, make sure to send a properly formatted form request body.").WithWrap(err))
+
+			return
+		}
+
+		if client, err = handleOAuthBackchannelAuthenticationClient(ctx, req); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
+
+			errorsx.WriteJSONError(rw, req, err)
+
+			return
+		}
+
+		if requester, err = newOAuthBackchannelAuthenticationRequester(ctx, client, req.PostForm); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request on client with id '%s' failed with error: %s", client.GetID(), oauthelia2.ErrorToDebugRFC6749Error(err))
+
+			errorsx.WriteJSONError(rw, req, err)
+
+			return
+		}
+
+		username := req.PostForm.Get(oidc.FormParameterLoginHint)
+
+		if details, err = ctx.Providers.UserProvider.GetDetails(username); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request with id '%s' on client with id '%s' failed to retrieve the details of user '%s' with error: %+v", requester.GetID(), client.GetID(), username, err)
+
+			errorsx.WriteJSONError(rw, req, oidc.ErrUnknownUserID)
+
+			return
+		}
+
+		if subject, err = ctx.Providers.OpenIDConnect.GetSubject(ctx, client.GetSectorIdentifierURI(), details.Username); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request with id '%s' on client with id '%s' failed to retrieve the subject for user '%s' with error: %+v", requester.GetID(), client.GetID(), details.Username, err)
+
+			errorsx.WriteJSONError(rw, req, oauthelia2.ErrServerError)
+
+			return
+		}
+
+		extraClaims := map[string]any{}
+
+		oidcApplyScopeClaims(extraClaims, requester.GetGrantedScopes(), details)
+
+		requester.SetSession(oidc.NewSessionWithRequester(ctx, ctx.RootURL(), ctx.Providers.OpenIDConnect.KeyManager.GetKeyID(ctx, client.GetIDTokenSignedResponseKeyID(), client.GetIDTokenSignedResponseAlg()), details.Username, oidc.AuthenticationMethodsReferences{Duo: true}.MarshalRFC8176(), extraClaims, time.Time{}, subject, requester))
+
+		authReqID := ctx.Providers.Random.StringCustom(64, random.CharSetAlphaNumeric)
+		mode := client.GetBackchannelTokenDeliveryMode()
+		expires := requester.GetRequestedAt().Add(getOAuthBackchannelAuthenticationLifespan(ctx, req.PostForm))
+
+		var notification *model.OAuth2CIBANotification
+
+		if mode == oidc.BackchannelTokenDeliveryModePing {
+			notification = &model.OAuth2CIBANotification{AuthRequestID: authReqID, Token: req.PostForm.Get(oidc.FormParameterClientNotificationToken)}
+		}
+
+		if request, err = model.NewOAuth2CIBARequest(requester, oidc.BackchannelAuthenticationSignature(authReqID), details.Username, mode, notification, expires); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request with id '%s' on client with id '%s' could not be created: %+v", requester.GetID(), client.GetID(), err)
+
+			errorsx.WriteJSONError(rw, req, oauthelia2.ErrServerError)
+
+			return
+		}
+
+		if txid, err = authenticator.StartBackchannelAuthentication(ctx, details.Username, ctx.RemoteIP().String(), req.PostForm.Get(oidc.FormParameterBindingMessage)); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request with id '%s' on client with id '%s' failed to start the out-of-band authentication for user '%s' with error: %+v", requester.GetID(), client.GetID(), details.Username, err)
+
+			errorsx.WriteJSONError(rw, req, oauthelia2.ErrTemporarilyUnavailable.WithHint("The out-of-band authentication of the end-user could not be started."))
+
+			return
+		}
+
+		request.TransactionID = sql.NullString{String: txid, Valid: true}
+
+		if err = ctx.Providers.StorageProvider.SaveOAuth2CIBARequest(ctx, *request); err != nil {
+			ctx.Logger.Errorf("Backchannel Authentication Request with id '%s' on client with id '%s' could not be saved: %+v", requester.GetID(), client.GetID(), err)
+
+			errorsx.WriteJSONError(rw, req, oauthelia2.ErrServerError)
+
+			return
+		}
+
+		ctx.Logger.Debugf("Backchannel Authentication Request with id '%s' on client with id '%s' for user '%s' was successfully processed using the '%s' token delivery mode", requester.GetID(), client.GetID(), details.Username, mode)
+
+		rw.Header().Set(fasthttp.HeaderContentType, "application/json; charset=utf-8")
+		rw.Header().Set(fasthttp.HeaderCacheControl, "no-store")
+		rw.Header().Set(fasthttp.HeaderPragma, "no-cache")
+		rw.WriteHeader(http.StatusOK)
+
+		_ = json.NewEncoder(rw).Encode(oidc.BackchannelAuthenticationResponse{
+			AuthRequestID: authReqID,
+			ExpiresIn:     int64(time.Until(expires).Round(time.Second).Seconds()),
+			Interval:      int64(ctx.Providers.OpenIDConnect.GetBackchannelAuthenticationPollingInterval(ctx).Seconds()),
+		})
+	}
+}
+
+type oauthBackchannelAuthenticationClientAuthenticator interface {
+	AuthenticateClient(ctx context.Context, r *http.Request, form url.Values) (client oauthelia2.Client, method string, err error)
+}
+
+func handleOAuthBackchannelAuthenticationClient(ctx *middlewares.AutheliaCtx, req *http.Request) (client oidc.Client, err error) {
+	authenticator, ok := ctx.Providers.OpenIDConnect.Provider.(oauthBackchannelAuthenticationClientAuthenticator)
+	if !ok {
+		return nil, oauthelia2.ErrServerError.WithDebug("The OpenID Connect 1.0 Provider does not support client authentication at the backchannel authentication endpoint.")
+	}
+
+	var c oauthelia2.Client
+
+	if c, _, err = authenticator.AuthenticateClient(ctx, req, req.PostForm); err != nil {
+		return nil, err
+	}
+
+	if client, ok = c.(oidc.Client); !ok {
+		return nil, oauthelia2.ErrServerError.WithDebugf("The client type '%T' is not supported.", c)
+	}
+
+	if client.IsPublic() {
+		return nil, oauthelia2.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is marked as public and is thus not allowed to use the authorization grant '%s'.", oidc.GrantTypeCIBA)
+	}
+
+	if !client.GetGrantTypes().Has(oidc.GrantTypeCIBA) {
+		return nil, oauthelia2.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", oidc.GrantTypeCIBA)
+	}
+
+	return client, nil
+}
+
+// newOAuthBackchannelAuthenticationRequester validates the backchannel authentication request parameters and returns
+// the requester. Only the parameters which are relevant to the request are retained as the form is stored alongside
+// the request and may otherwise contain the client credentials.
+func newOAuthBackchannelAuthenticationRequester(ctx *middlewares.AutheliaCtx, client oidc.Client, form url.Values) (requester *oauthelia2.Request, err error) {
+	scopes := oauthelia2.RemoveEmpty(strings.Split(form.Get(oidc.FormParameterScope), " "))
+
+	switch {
+	case !oauthelia2.Arguments(scopes).Has(oidc.ScopeOpenID):
+		return nil, oauthelia2.ErrInvalidScope.WithHintf("The '%s' scope must be requested.", oidc.ScopeOpenID)
+	case form.Has(oidc.FormParameterLoginHintToken) || form.Has(oidc.FormParameterIDTokenHint):
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("Only the '%s' parameter is supported to identify the end-user.", oidc.FormParameterLoginHint)
+	case len(form.Get(oidc.FormParameterLoginHint)) == 0:
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be provided.", oidc.FormParameterLoginHint)
+	case client.GetBackchannelTokenDeliveryMode() == oidc.BackchannelTokenDeliveryModePing && len(form.Get(oidc.FormParameterClientNotificationToken)) == 0:
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be provided when the OAuth 2.0 Client uses the '%s' token delivery mode.", oidc.FormParameterClientNotificationToken, oidc.BackchannelTokenDeliveryModePing)
+	}
+
+	if expiry := form.Get(oidc.FormParameterRequestedExpiry); len(expiry) != 0 {
+		if seconds, err := strconv.Atoi(expiry); err != nil || seconds <= 0 {
+			return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be a positive integer.", oidc.FormParameterRequestedExpiry)
+		}
+	}
+
+	strategy := ctx.Providers.OpenIDConnect.GetScopeStrategy(ctx)
+
+	requester = oauthelia2.NewRequest()
+
+	requester.Client = client
+	requester.RequestedAt = ctx.Clock.Now().UTC()
+
+	for _, scope := range scopes {
+		if !strategy(client.GetScopes(), scope) {
+			return nil, oauthelia2.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to request scope '%s'.", scope)
+		}
+
+		requester.AppendRequestedScope(scope)
+		requester.GrantScope(scope)
+	}
+
+	for _, key := range []string{oidc.FormParameterScope, oidc.FormParameterLoginHint, oidc.FormParameterBindingMessage, oidc.FormParameterRequestedExpiry} {
+		if value := form.Get(key); len(value) != 0 {
+			requester.Form.Set(key, value)
+		}
+	}
+
+	return requester, nil
+}
+
+// getOAuthBackchannelAuthenticationLifespan returns the lifespan of the request, which is the requested expiry when
+// it's shorter than the configured lifespan.
+func getOAuthBackchannelAuthenticationLifespan(ctx *middlewares.AutheliaCtx, form url.Values) (lifespan time.Duration) {
+	lifespan = ctx.Providers.OpenIDConnect.GetBackchannelAuthenticationLifespan(ctx)
+
+	if seconds, err := strconv.Atoi(form.Get(oidc.FormParameterRequestedExpiry)); err == nil && seconds > 0 {
+		if requested := time.Duration(seconds) * time.Second; requested < lifespan {
+			return requested
+		}
+	}
+
+	return lifespan
+}
@@ -0,0 +1,385 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"authelia.com/provider/oauth2/x/errorsx"
+	"github.com/go-crypt/crypt/algorithm/pbkdf2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+// OAuthClientRegistrationPOST handles POST requests to the OAuth 2.0 Dynamic Client Registration endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7591#section-3
+func OAuthClientRegistrationPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var (
+		metadata oidc.ClientRegistrationMetadata
+		id       uuid.UUID
+		err      error
+	)
+
+	config := ctx.Configuration.IdentityProviders.OIDC
+
+	if !isClientRegistrationInitialAccessTokenValid(config.DynamicClientRegistration, oauthelia2.AccessTokenFromRequest(req)) {
+		ctx.Logger.Errorf("Client Registration Request failed with error: the initial access token was missing or invalid")
+
+		writeClientRegistrationError(rw, req, oidc.ErrInvalidToken)
+
+		return
+	}
+
+	if err = decodeClientRegistrationMetadata(req, &metadata); err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		writeClientRegistrationError(rw, req, err)
+
+		return
+	}
+
+	if metadata.ClientID != "" || metadata.ClientSecret != "" {
+		err = oidc.ErrInvalidClientMetadata.WithHint("The 'client_id' and 'client_secret' client metadata must not be provided when registering a client.")
+	} else {
+		err = metadata.Validate(config)
+	}
+
+	if err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		writeClientRegistrationError(rw, req, err)
+
+		return
+	}
+
+	if id, err = uuid.NewRandom(); err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed to generate the client id with error: %+v", err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	dynamic := model.OAuth2DynamicClient{
+		ClientID:  id.String(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	response := &oidc.ClientRegistrationResponse{
+		ClientIDIssuedAt:        now.Unix(),
+		RegistrationAccessToken: newClientRegistrationAccessToken(ctx.Providers.Random),
+		RegistrationClientURI:   clientRegistrationClientURI(ctx, dynamic.ClientID),
+	}
+
+	dynamic.RegistrationAccessTokenSignature = oidc.RegistrationAccessTokenSignature(response.RegistrationAccessToken)
+
+	if !metadata.IsPublic() {
+		var secret string
+
+		if secret, dynamic.ClientSecret, err = newClientRegistrationSecret(ctx.Providers.Random); err != nil {
+			ctx.Logger.Errorf("Client Registration Request for client with id '%s' failed to generate the client secret with error: %+v", dynamic.ClientID, err)
+
+			writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+			return
+		}
+
+		response.ClientSecret = secret
+	}
+
+	if dynamic.Metadata, err = json.Marshal(metadata); err != nil {
+		ctx.Logger.Errorf("Client Registration Request for client with id '%s' failed to encode the client metadata with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2DynamicClient(ctx, dynamic); err != nil {
+		ctx.Logger.Errorf("Client Registration Request for client with id '%s' failed to save the client with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	response.ClientRegistrationMetadata = metadata
+	response.ClientID = dynamic.ClientID
+
+	ctx.Logger.Infof("Client Registration Request successfully registered the client with id '%s' and name '%s'", dynamic.ClientID, metadata.ClientName)
+
+	writeClientRegistrationResponse(rw, http.StatusCreated, response)
+}
+
+// OAuthClientRegistrationConfigurationGET handles GET requests to the OAuth 2.0 Dynamic Client Configuration
+// endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.1
+func OAuthClientRegistrationConfigurationGET(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var (
+		dynamic  *model.OAuth2DynamicClient
+		metadata oidc.ClientRegistrationMetadata
+		err      error
+	)
+
+	if dynamic = handleClientRegistrationConfigurationAuthorization(ctx, rw, req); dynamic == nil {
+		return
+	}
+
+	if err = json.Unmarshal(dynamic.Metadata, &metadata); err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to decode the client metadata with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	writeClientRegistrationResponse(rw, http.StatusOK, newClientRegistrationConfigurationResponse(ctx, dynamic, metadata))
+}
+
+// OAuthClientRegistrationConfigurationPUT handles PUT requests to the OAuth 2.0 Dynamic Client Configuration
+// endpoint. The provided client metadata replaces the registered client metadata.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.2
+func OAuthClientRegistrationConfigurationPUT(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var (
+		dynamic  *model.OAuth2DynamicClient
+		metadata oidc.ClientRegistrationMetadata
+		secret   string
+		err      error
+	)
+
+	if dynamic = handleClientRegistrationConfigurationAuthorization(ctx, rw, req); dynamic == nil {
+		return
+	}
+
+	if err = decodeClientRegistrationMetadata(req, &metadata); err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed with error: %s", dynamic.ClientID, oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		writeClientRegistrationError(rw, req, err)
+
+		return
+	}
+
+	switch {
+	case metadata.ClientID != dynamic.ClientID:
+		err = oidc.ErrInvalidClientMetadata.WithHint("The 'client_id' client metadata must match the client being updated.")
+	case metadata.ClientSecret != "" && !isClientRegistrationSecretMatch(dynamic, metadata.ClientSecret):
+		err = oidc.ErrInvalidClientMetadata.WithHint("The 'client_secret' client metadata must match the current client secret when provided.")
+	default:
+		metadata.ClientID, metadata.ClientSecret = "", ""
+
+		err = metadata.Validate(ctx.Configuration.IdentityProviders.OIDC)
+	}
+
+	if err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed with error: %s", dynamic.ClientID, oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		writeClientRegistrationError(rw, req, err)
+
+		return
+	}
+
+	switch {
+	case metadata.IsPublic():
+		dynamic.ClientSecret = sql.NullString{}
+	case !dynamic.ClientSecret.Valid:
+		if secret, dynamic.ClientSecret, err = newClientRegistrationSecret(ctx.Providers.Random); err != nil {
+			ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to generate the client secret with error: %+v", dynamic.ClientID, err)
+
+			writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+			return
+		}
+	}
+
+	if dynamic.Metadata, err = json.Marshal(metadata); err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to encode the client metadata with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	dynamic.UpdatedAt = ctx.Clock.Now()
+
+	if err = ctx.Providers.StorageProvider.UpdateOAuth2DynamicClient(ctx, *dynamic); err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to update the client with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	response := newClientRegistrationConfigurationResponse(ctx, dynamic, metadata)
+	response.ClientSecret = secret
+
+	ctx.Logger.Infof("Client Configuration Request successfully updated the client with id '%s'", dynamic.ClientID)
+
+	writeClientRegistrationResponse(rw, http.StatusOK, response)
+}
+
+// OAuthClientRegistrationConfigurationDELETE handles DELETE requests to the OAuth 2.0 Dynamic Client Configuration
+// endpoint which deprovisions the client.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.3
+func OAuthClientRegistrationConfigurationDELETE(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var dynamic *model.OAuth2DynamicClient
+
+	if dynamic = handleClientRegistrationConfigurationAuthorization(ctx, rw, req); dynamic == nil {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.DeleteOAuth2DynamicClient(ctx, dynamic.ClientID); err != nil {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to delete the client with error: %+v", dynamic.ClientID, err)
+
+		writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+		return
+	}
+
+	ctx.Logger.Infof("Client Configuration Request successfully deleted the client with id '%s'", dynamic.ClientID)
+
+	rw.Header().Set(fasthttp.HeaderCacheControl, "no-store")
+	rw.Header().Set(fasthttp.HeaderPragma, "no-cache")
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// handleClientRegistrationConfigurationAuthorization loads the client from the path of the request and validates the
+// registration access token, writing the error response and returning nil if it's not valid. The same error is
+// returned for clients which don't exist as for invalid tokens so the endpoint can't be used to enumerate clients.
+func handleClientRegistrationConfigurationAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) (dynamic *model.OAuth2DynamicClient) {
+	var err error
+
+	clientID, _ := ctx.UserValue("client_id").(string)
+
+	if dynamic, err = ctx.Providers.StorageProvider.LoadOAuth2DynamicClient(ctx, clientID); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed to load the client with error: %+v", clientID, err)
+
+			writeClientRegistrationError(rw, req, oauthelia2.ErrServerError)
+
+			return nil
+		}
+
+		dynamic = nil
+	}
+
+	if !oidc.IsRegistrationAccessTokenValid(dynamic, oauthelia2.AccessTokenFromRequest(req)) {
+		ctx.Logger.Errorf("Client Configuration Request for client with id '%s' failed with error: the registration access token was missing or invalid or the client does not exist", clientID)
+
+		writeClientRegistrationError(rw, req, oidc.ErrInvalidToken)
+
+		return nil
+	}
+
+	return dynamic
+}
+
+func newClientRegistrationConfigurationResponse(ctx *middlewares.AutheliaCtx, dynamic *model.OAuth2DynamicClient, metadata oidc.ClientRegistrationMetadata) (response *oidc.ClientRegistrationResponse) {
+	response = &oidc.ClientRegistrationResponse{
+		ClientRegistrationMetadata: metadata,
+		ClientIDIssuedAt:           dynamic.CreatedAt.Unix(),
+		RegistrationClientURI:      clientRegistrationClientURI(ctx, dynamic.ClientID),
+	}
+
+	response.ClientID = dynamic.ClientID
+
+	return response
+}
+
+func decodeClientRegistrationMetadata(req *http.Request, metadata *oidc.ClientRegistrationMetadata) (err error) {
+	if err = json.NewDecoder(req.Body).Decode(metadata); err != nil {
+		return oidc.ErrInvalidClientMetadata.WithHint("The request body must be a JSON object containing the client metadata.").WithWrap(err).WithDebugf("Error occurred decoding the client metadata: %s.", err)
+	}
+
+	return nil
+}
+
+func clientRegistrationClientURI(ctx *middlewares.AutheliaCtx, clientID string) string {
+	return ctx.RootURL().JoinPath(oidc.EndpointPathClientRegistration, url.PathEscape(clientID)).String()
+}
+
+func isClientRegistrationInitialAccessTokenValid(config schema.IdentityProvidersOpenIDConnectDynamicClientRegistration, token string) bool {
+	if len(config.InitialAccessTokens) == 0 {
+		return config.OpenRegistration
+	}
+
+	if token == "" {
+		return false
+	}
+
+	for _, t := range config.InitialAccessTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isClientRegistrationSecretMatch(dynamic *model.OAuth2DynamicClient, secret string) bool {
+	if !dynamic.ClientSecret.Valid {
+		return false
+	}
+
+	digest, err := pbkdf2.Decode(dynamic.ClientSecret.String)
+	if err != nil {
+		return false
+	}
+
+	return digest.Match(secret)
+}
+
+func newClientRegistrationAccessToken(rand random.Provider) string {
+	return fmt.Sprintf("%s%s", clientRegistrationAccessTokenPrefix, rand.StringCustom(64, random.CharSetAlphaNumeric))
+}
+
+func newClientRegistrationSecret(rand random.Provider) (secret string, digest sql.NullString, err error) {
+	var hasher *pbkdf2.Hasher
+
+	if hasher, err = pbkdf2.NewSHA512(); err != nil {
+		return "", sql.NullString{}, err
+	}
+
+	secret = rand.StringCustom(72, random.CharSetRFC3986Unreserved)
+
+	d, err := hasher.Hash(secret)
+	if err != nil {
+		return "", sql.NullString{}, err
+	}
+
+	return secret, sql.NullString{String: d.Encode(), Valid: true}, nil
+}
+
+func writeClientRegistrationResponse(rw http.ResponseWriter, status int, response *oidc.ClientRegistrationResponse) {
+	rw.Header().Set(fasthttp.HeaderContentType, "application/json; charset=utf-8")
+	rw.Header().Set(fasthttp.HeaderCacheControl, "no-store")
+	rw.Header().Set(fasthttp.HeaderPragma, "no-cache")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(response)
+}
+
+func writeClientRegistrationError(rw http.ResponseWriter, req *http.Request, err error) {
+	if rfc := oauthelia2.ErrorToRFC6749Error(err); rfc.StatusCode() == http.StatusUnauthorized {
+		rw.Header().Set(fasthttp.HeaderWWWAuthenticate, fmt.Sprintf(`Bearer %s`, oidc.RFC6750Header("", "", rfc)))
+	}
+
+	errorsx.WriteJSONError(rw, req, err)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// OAuthDeviceAuthorizationPOST handles POST requests to the OAuth 2.0 Device Authorization endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+func OAuthDeviceAuthorizationPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var (
+		requester oauthelia2.DeviceAuthorizeRequester
+		responder oauthelia2.DeviceAuthorizeResponder
+		err       error
+	)
+
+	if requester, err = ctx.Providers.OpenIDConnect.NewRFC8628DeviceAuthorizeRequest(ctx, req); err != nil {
+		ctx.Logger.Errorf("Device Authorization Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.WriteRFC8628DeviceAuthorizeError(ctx, rw, requester, err)
+
+		return
+	}
+
+	clientID := requester.GetClient().GetID()
+
+	ctx.Logger.Debugf("Device Authorization Request with id '%s' on client with id '%s' is being processed", requester.GetID(), clientID)
+
+	session := oidc.NewSession()
+	session.ClientID = clientID
+
+	if responder, err = ctx.Providers.OpenIDConnect.NewRFC8628DeviceAuthorizeResponse(ctx, requester, session); err != nil {
+		ctx.Logger.Errorf("Device Authorization Response for Request with id '%s' on client with id '%s' could not be created: %s", requester.GetID(), clientID, oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.WriteRFC8628DeviceAuthorizeError(ctx, rw, requester, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Device Authorization Request with id '%s' on client with id '%s' was successfully processed", requester.GetID(), clientID)
+
+	ctx.Providers.OpenIDConnect.WriteRFC8628DeviceAuthorizeResponse(ctx, rw, requester, responder)
+}
+
+// OAuthDeviceAuthorizationUserVerificationGET handles GET requests to the OAuth 2.0 Device Authorization verification
+// URI by redirecting the user to the device authorization consent page, preserving the user code if it was provided.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.3
+func OAuthDeviceAuthorizationUserVerificationGET(ctx *middlewares.AutheliaCtx) {
+	redirectURL := ctx.RootURL()
+
+	redirectURL.Path = path.Join(redirectURL.Path, oidc.EndpointPathConsentDeviceAuthorization)
+
+	if userCode := ctx.QueryArgs().PeekBytes(qryArgUserCode); len(userCode) != 0 {
+		query := url.Values{}
+		query.Set(queryArgUserCode, string(userCode))
+
+		redirectURL.RawQuery = query.Encode()
+	}
+
+	ctx.Redirect(redirectURL.String(), fasthttp.StatusFound)
+}
+
+// OAuthDeviceAuthorizationGET returns the client information, requested scopes, and requested audience of an OAuth 2.0
+// Device Authorization Grant given the user code so the user can decide if they wish to approve it.
+func OAuthDeviceAuthorizationGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		requester oauthelia2.DeviceAuthorizeRequester
+		client    oidc.Client
+		handled   bool
+		err       error
+	)
+
+	if _, requester, client, handled = handleOAuthDeviceAuthorizationGetSessionsAndClient(ctx, string(ctx.QueryArgs().PeekBytes(qryArgUserCode))); handled {
+		return
+	}
+
+	body := client.GetConsentResponseBody(nil)
+
+	body.Scopes = requester.GetRequestedScopes()
+	body.Audience = requester.GetRequestedAudience()
+	body.PreConfiguration = false
+
+	if err = ctx.SetJSONBody(body); err != nil {
+		ctx.Error(fmt.Errorf("unable to set JSON body: %w", err), "Operation failed")
+	}
+}
+
+// OAuthDeviceAuthorizationPUT handles the response of the user to an OAuth 2.0 Device Authorization Grant, either
+// approving or denying it. When approved the device is able to exchange the device code for tokens at the token endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.3
+func OAuthDeviceAuthorizationPUT(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON oidc.DeviceAuthorizationPutRequestBody
+		err      error
+	)
+
+	if err = json.Unmarshal(ctx.Request.Body(), &bodyJSON); err != nil {
+		ctx.Logger.Errorf("Failed to parse JSON body in device authorization PUT: %+v", err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	var (
+		userSession session.UserSession
+		requester   oauthelia2.DeviceAuthorizeRequester
+		client      oidc.Client
+		handled     bool
+	)
+
+	if userSession, requester, client, handled = handleOAuthDeviceAuthorizationGetSessionsAndClient(ctx, bodyJSON.UserCode); handled {
+		return
+	}
+
+	if bodyJSON.Consent {
+		if err = handleOAuthDeviceAuthorizationApprove(ctx, userSession, requester, client); err != nil {
+			ctx.Logger.Errorf("Device Authorization Request with id '%s' on client with id '%s' could not be approved by user '%s': %+v", requester.GetID(), client.GetID(), userSession.Username, err)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	} else {
+		requester.SetStatus(oauthelia2.DeviceAuthorizeStatusDenied)
+	}
+
+	requester.SetLastChecked(ctx.Clock.Now())
+
+	if err = ctx.Providers.OpenIDConnect.UpdateDeviceCodeSession(ctx, requester.GetDeviceCodeSignature(), requester); err != nil {
+		ctx.Logger.Errorf("Failed to save the device authorization response to the database: %+v", err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeOpenIDConnectConsent, audit.NewResult(bodyJSON.Consent), userSession.Username, map[string]any{"client_id": client.GetID(), "granted": bodyJSON.Consent, "scopes": requester.GetGrantedScopes(), "audience": requester.GetGrantedAudience(), "grant_type": oidc.GrantTypeDeviceCode})
+
+	ctx.ReplyOK()
+}
+
+func handleOAuthDeviceAuthorizationApprove(ctx *middlewares.AutheliaCtx, userSession session.UserSession, requester oauthelia2.DeviceAuthorizeRequester, client oidc.Client) (err error) {
+	var (
+		details  *authentication.UserDetails
+		subject  uuid.UUID
+		authTime time.Time
+	)
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(userSession.Username); err != nil {
+		return fmt.Errorf("error occurred retrieving user details from the backend: %w", err)
+	}
+
+	if subject, err = ctx.Providers.OpenIDConnect.GetSubject(ctx, client.GetSectorIdentifierURI(), userSession.Username); err != nil {
+		return fmt.Errorf("error occurred retrieving subject: %w", err)
+	}
+
+	if authTime, err = userSession.AuthenticatedTime(client.GetAuthorizationPolicyRequiredLevel(authorization.Subject{Username: details.Username, Groups: details.Groups, IP: ctx.RemoteIP()})); err != nil {
+		return fmt.Errorf("error occurred checking authentication time: %w", err)
+	}
+
+	for _, scope := range requester.GetRequestedScopes() {
+		requester.GrantScope(scope)
+	}
+
+	for _, audience := range requester.GetRequestedAudience() {
+		requester.GrantAudience(audience)
+	}
+
+	extraClaims := map[string]any{}
+
+	oidcApplyScopeClaims(extraClaims, requester.GetGrantedScopes(), details)
+
+	requester.SetSession(oidc.NewSessionWithRequester(ctx, ctx.RootURL(), ctx.Providers.OpenIDConnect.KeyManager.GetKeyID(ctx, client.GetIDTokenSignedResponseKeyID(), client.GetIDTokenSignedResponseAlg()), details.Username, userSession.AuthenticationMethodRefs.MarshalRFC8176(), extraClaims, authTime, subject, requester))
+	requester.SetStatus(oauthelia2.DeviceAuthorizeStatusApproved)
+
+	return nil
+}
+
+func handleOAuthDeviceAuthorizationGetSessionsAndClient(ctx *middlewares.AutheliaCtx, userCode string) (userSession session.UserSession, requester oauthelia2.DeviceAuthorizeRequester, client oidc.Client, handled bool) {
+	var (
+		signature string
+		err       error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.Errorf("Unable to load user session for device authorization: %v", err)
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.Error("Unable to perform OAuth 2.0 Device Authorization: the user is not logged in")
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if len(userCode) == 0 {
+		ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s': the user code was not provided", userSession.Username)
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	// The user codes are short enough to be guessed, so the failed lookups are regulated like the authentication
+	// attempts of the user as recommended by RFC8628 section 5.1.
+	if _, err = ctx.Providers.Regulator.Regulate(ctx, userSession.Username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s': the user is banned", userSession.Username)
+		} else {
+			ctx.Logger.WithError(err).Errorf(logFmtErrRegulationFail, regulation.AuthTypeDeviceCode, userSession.Username)
+		}
+
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if signature, err = ctx.Providers.OpenIDConnect.Strategy.RFC8628.RFC8628UserCodeSignature(ctx, userCode); err != nil {
+		ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s': the user code could not be signed: %v", userSession.Username, err)
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if requester, err = ctx.Providers.OpenIDConnect.GetDeviceCodeSessionByUserCode(ctx, signature, oidc.NewSession()); err != nil {
+		if errors.Is(err, oauthelia2.ErrNotFound) {
+			ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s': the user code was not found", userSession.Username)
+
+			if err = ctx.Providers.Regulator.Mark(ctx, false, false, userSession.Username, "", "", regulation.AuthTypeDeviceCode); err != nil {
+				ctx.Logger.WithError(err).Errorf("Unable to mark %s authentication attempt by user '%s'", regulation.AuthTypeDeviceCode, userSession.Username)
+			}
+		} else {
+			ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s': error occurred loading the device code session: %v", userSession.Username, err)
+		}
+
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	switch {
+	case requester.GetStatus() != oauthelia2.DeviceAuthorizeStatusNew:
+		ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s' on request with id '%s': the request has already been responded to", userSession.Username, requester.GetID())
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	case requester.GetRequestedAt().Add(ctx.Providers.OpenIDConnect.GetRFC8628CodeLifespan(ctx)).Before(ctx.Clock.Now()):
+		ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s' on request with id '%s': the user code has expired", userSession.Username, requester.GetID())
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if client, err = ctx.Providers.OpenIDConnect.GetRegisteredClient(ctx, requester.GetClient().GetID()); err != nil {
+		ctx.Logger.Errorf("Unable to find related client configuration with name '%s': %v", requester.GetClient().GetID(), err)
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if !isOIDCClientAuthenticationSufficient(ctx, client, userSession) {
+		ctx.Logger.Errorf("Unable to perform OAuth 2.0 Device Authorization for user '%s' and client id '%s': the user is not sufficiently authenticated", userSession.Username, client.GetID())
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	return userSession, requester, client, false
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/google/uuid"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
@@ -34,7 +35,7 @@ func handleOIDCAuthorizationConsent(ctx *middlewares.AutheliaCtx, issuer *url.UR
 	switch {
 	case userSession.IsAnonymous():
 		handler = handleOIDCAuthorizationConsentNotAuthenticated
-	case authorization.IsAuthLevelSufficient(userSession.AuthenticationLevel, level):
+	case authorization.IsAuthLevelSufficient(userSession.AuthenticationLevel, level) && client.IsUserVerificationSufficient(userSession.AuthenticationMethodRefs, authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}):
 		if subject, err = ctx.Providers.OpenIDConnect.GetSubject(ctx, client.GetSectorIdentifierURI(), userSession.Username); err != nil {
 			ctx.Logger.Errorf(logFmtErrConsentCantGetSubject, requester.GetID(), client.GetID(), client.GetConsentPolicy(), userSession.Username, client.GetSectorIdentifierURI(), err)
 
@@ -134,7 +135,7 @@ func handleOIDCAuthorizationConsentRedirect(ctx *middlewares.AutheliaCtx, issuer
 	userSession session.UserSession, rw http.ResponseWriter, r *http.Request, requester oauthelia2.AuthorizeRequester) {
 	var location *url.URL
 
-	if client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel, authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}) {
+	if isOIDCClientAuthenticationSufficient(ctx, client, userSession) {
 		location, _ = url.ParseRequestURI(issuer.String())
 		location.Path = path.Join(location.Path, oidc.EndpointPathConsent)
 
@@ -147,6 +148,13 @@ func handleOIDCAuthorizationConsentRedirect(ctx *middlewares.AutheliaCtx, issuer
 	} else {
 		location = handleOIDCAuthorizationConsentGetRedirectionURL(ctx, issuer, consent, requester, r.Form)
 
+		if !client.IsUserVerificationSufficient(userSession.AuthenticationMethodRefs, authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}) {
+			query := location.Query()
+			query.Set(queryArgUV, string(protocol.VerificationRequired))
+
+			location.RawQuery = query.Encode()
+		}
+
 		ctx.Logger.Debugf(logFmtDbgConsentAuthenticationSufficiency, requester.GetID(), client.GetID(), client.GetConsentPolicy(), userSession.AuthenticationLevel.String(), "insufficient", client.GetAuthorizationPolicyRequiredLevel(authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}))
 	}
 
@@ -259,3 +267,11 @@ func verifyOIDCUserAuthorizedForConsent(ctx *middlewares.AutheliaCtx, client oid
 
 	return nil
 }
+
+// isOIDCClientAuthenticationSufficient returns true if the authentication level of the user session is sufficient for
+// the client and the WebAuthn assertion verified the user if the client requires it.
+func isOIDCClientAuthenticationSufficient(ctx *middlewares.AutheliaCtx, client oidc.Client, userSession session.UserSession) bool {
+	subject := authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}
+
+	return client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel, subject) && client.IsUserVerificationSufficient(userSession.AuthenticationMethodRefs, subject)
+}
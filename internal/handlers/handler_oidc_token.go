@@ -16,11 +16,20 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 	var (
 		requester oauthelia2.AccessRequester
 		responder oauthelia2.AccessResponder
+		proof     *oidc.DPoPProof
 		err       error
 	)
 
 	session := oidc.NewSession()
 
+	if proof, err = oidc.NewDPoPProofFromRequest(ctx, ctx.Providers.OpenIDConnect, req, ctx.RootURL().JoinPath(oidc.EndpointPathToken), "", ctx.Clock.Now()); err != nil {
+		ctx.Logger.Errorf("Access Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, requester, err)
+
+		return
+	}
+
 	if requester, err = ctx.Providers.OpenIDConnect.NewAccessRequest(ctx, req, session); err != nil {
 		ctx.Logger.Errorf("Access Request failed with error: %s", oauthelia2.ErrorToDebugRFC6749Error(err))
 
@@ -51,6 +60,14 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 		}
 	}
 
+	if err = oidc.BindDPoPProofToAccessRequest(requester, proof); err != nil {
+		ctx.Logger.Errorf("Access Response for Request with id '%s' failed to be created with error: %s", requester.GetID(), oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, requester, err)
+
+		return
+	}
+
 	ctx.Logger.Tracef("Access Request with id '%s' on client with id '%s' response is being generated for session with type '%T'", requester.GetID(), client.GetID(), requester.GetSession())
 
 	if responder, err = ctx.Providers.OpenIDConnect.NewAccessResponse(ctx, requester); err != nil {
@@ -61,6 +78,10 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 		return
 	}
 
+	if proof != nil {
+		responder.SetTokenType(oidc.AuthorizationSchemeDPoP)
+	}
+
 	ctx.Logger.Debugf("Access Request with id '%s' on client with id '%s' has successfully been processed", requester.GetID(), client.GetID())
 
 	ctx.Logger.Tracef("Access Request with id '%s' on client with id '%s' produced the following claims: %+v", requester.GetID(), client.GetID(), oidc.AccessResponderToClearMap(responder))
@@ -40,7 +40,9 @@ func OpenIDConnectUserinfo(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter,
 
 	ctx.Logger.Debugf("UserInfo Request with id '%s' is being processed", requestID)
 
-	if tokenType, requester, err = ctx.Providers.OpenIDConnect.IntrospectToken(req.Context(), oauthelia2.AccessTokenFromRequest(req), oauthelia2.AccessToken, oidcSession); err != nil {
+	accessToken, scheme := oidc.AccessTokenFromRequest(req)
+
+	if tokenType, requester, err = ctx.Providers.OpenIDConnect.IntrospectToken(req.Context(), accessToken, oauthelia2.AccessToken, oidcSession); err != nil {
 		ctx.Logger.Errorf("UserInfo Request with id '%s' failed with error: %s", requestID, oauthelia2.ErrorToDebugRFC6749Error(err))
 
 		if rfc := oauthelia2.ErrorToRFC6749Error(err); rfc.StatusCode() == http.StatusUnauthorized {
@@ -64,6 +66,15 @@ func OpenIDConnectUserinfo(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter,
 		return
 	}
 
+	if err = oidc.ValidateDPoPBoundAccessToken(ctx, ctx.Providers.OpenIDConnect, req, ctx.RootURL().JoinPath(oidc.EndpointPathUserinfo), scheme, accessToken, requester, ctx.Clock.Now()); err != nil {
+		ctx.Logger.Errorf("UserInfo Request with id '%s' on client with id '%s' failed with error: %s", requestID, clientID, oauthelia2.ErrorToDebugRFC6749Error(err))
+
+		rw.Header().Set(fasthttp.HeaderWWWAuthenticate, fmt.Sprintf(`%s %s`, oidc.AuthorizationSchemeDPoP, oidc.RFC6750Header("", "", oauthelia2.ErrorToRFC6749Error(err))))
+		errorsx.WriteJSONErrorCode(rw, req, http.StatusUnauthorized, err)
+
+		return
+	}
+
 	if client, err = ctx.Providers.OpenIDConnect.GetRegisteredClient(ctx, clientID); err != nil {
 		ctx.Logger.Errorf("UserInfo Request with id '%s' on client with id '%s' failed to retrieve client configuration with error: %s", requestID, client.GetID(), oauthelia2.ErrorToDebugRFC6749Error(err))
 
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// RecoveryCodesGET returns the number of recovery codes the user has remaining.
+func RecoveryCodesGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		remaining   int
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving recovery codes: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred retrieving recovery codes")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if remaining, err = ctx.Providers.StorageProvider.CountRecoveryCodes(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving recovery codes for user '%s': error occurred counting the recovery codes in the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(RecoveryCodesResponse{Remaining: remaining}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving recovery codes for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// RecoveryCodesPOST generates a new set of recovery codes for the user which replaces all of their existing recovery
+// codes, and responds with the codes as this is the only time they are available.
+func RecoveryCodesPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		codes       []string
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToGenerateRecoveryCodes)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred generating recovery codes")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToGenerateRecoveryCodes)
+
+		return
+	}
+
+	if codes, err = generateRecoveryCodes(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageUnableToGenerateRecoveryCodes)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(RecoveryCodesResponse{Remaining: len(codes), Codes: codes}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes for user '%s': %s", userSession.Username, errStrRespBody)
+
+		return
+	}
+
+	ctxLogEvent(ctx, userSession.Username, eventLogActionRecoveryCodesGenerated, map[string]any{eventLogKeyAction: eventLogActionRecoveryCodesGenerated, eventLogKeyCategory: eventLogCategoryRecoveryCodes})
+}
+
+// RecoveryCodePOST validates the recovery code provided by the user and consumes it.
+func RecoveryCodePOST(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodySignRecoveryCodeRequest{}
+
+	var (
+		userSession session.UserSession
+		consumed    bool
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a recovery code authentication: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred validating a recovery code authentication")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a recovery code authentication for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if n := len(model.NormalizeRecoveryCode(bodyJSON.Code)); n != recoveryCodeLength {
+		ctx.Logger.Errorf("Error occurred validating a recovery code authentication for user '%s': expected code length is %d but the user provided code was %d characters in length", userSession.Username, recoveryCodeLength, n)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if consumed, err = ctx.Providers.StorageProvider.ConsumeRecoveryCode(ctx, userSession.Username, bodyJSON.Code, ctx.Clock.Now(), model.NewNullIP(ctx.RemoteIP())); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a recovery code authentication for user '%s': error occurred consuming the recovery code in the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if !consumed {
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeRecoveryCode, fmt.Errorf("the recovery code doesn't exist or was already used"))
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeRecoveryCode, nil); err != nil {
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = ctx.RegenerateSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeRecoveryCode, userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession.SetTwoFactorRecoveryCode(ctx.Clock.Now())
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a recovery code authentication for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	indexUserSession(ctx, userSession)
+
+	ctxLogEvent(ctx, userSession.Username, eventLogActionRecoveryCodeUsed, map[string]any{eventLogKeyAction: eventLogActionRecoveryCodeUsed, eventLogKeyCategory: eventLogCategoryRecoveryCodes})
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	}
+}
+
+// replyRecoveryCodesEnrollment responds to the successful registration of a second factor method. When the user has no
+// remaining recovery codes a new set is generated and included in the response, as the registration of the first
+// second factor method is when the user is most likely to need them. Failing to generate the recovery codes doesn't
+// fail the registration as the user can generate them later.
+func replyRecoveryCodesEnrollment(ctx *middlewares.AutheliaCtx, username string) {
+	var (
+		remaining int
+		codes     []string
+		err       error
+	)
+
+	if remaining, err = ctx.Providers.StorageProvider.CountRecoveryCodes(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes for user '%s': error occurred counting the recovery codes in the storage backend", username)
+
+		ctx.ReplyOK()
+
+		return
+	}
+
+	if remaining != 0 {
+		ctx.ReplyOK()
+
+		return
+	}
+
+	if codes, err = generateRecoveryCodes(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes for user '%s'", username)
+
+		ctx.ReplyOK()
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(RecoveryCodesResponse{Remaining: len(codes), Codes: codes}); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating recovery codes for user '%s': %s", username, errStrRespBody)
+	}
+}
+
+// generateRecoveryCodes generates a new set of recovery codes and saves them for the user, replacing all of their
+// existing recovery codes.
+func generateRecoveryCodes(ctx *middlewares.AutheliaCtx, username string) (codes []string, err error) {
+	var (
+		raw     string
+		now     = ctx.Clock.Now()
+		records = make([]model.RecoveryCode, recoveryCodesCount)
+	)
+
+	codes = make([]string, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		if raw, err = ctx.Providers.Random.StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper); err != nil {
+			return nil, fmt.Errorf("error occurred generating the recovery codes: %w", err)
+		}
+
+		codes[i] = formatRecoveryCode(raw)
+		records[i] = model.NewRecoveryCode(username, codes[i], now)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveRecoveryCodes(ctx, username, records); err != nil {
+		ctx.AuditEvent(audit.EventTypeRecoveryCodesGenerate, audit.ResultFailure, username, nil)
+
+		return nil, fmt.Errorf("error occurred saving the recovery codes to the storage backend: %w", err)
+	}
+
+	ctx.AuditEvent(audit.EventTypeRecoveryCodesGenerate, audit.ResultSuccess, username, map[string]any{"count": recoveryCodesCount})
+
+	return codes, nil
+}
+
+// formatRecoveryCode separates the groups of characters of a recovery code so it's easier for users to transcribe.
+func formatRecoveryCode(raw string) string {
+	groups := make([]string, 0, (len(raw)+recoveryCodeGroupLength-1)/recoveryCodeGroupLength)
+
+	for i := 0; i < len(raw); i += recoveryCodeGroupLength {
+		groups = append(groups, raw[i:min(i+recoveryCodeGroupLength, len(raw))])
+	}
+
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,470 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+func TestRecoveryCodesGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldReturnRemaining",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				mock.StorageMock.
+					EXPECT().
+					CountRecoveryCodes(mock.Ctx, testUsername).
+					Return(7, nil)
+			},
+			`{"status":"OK","data":{"remaining":7}}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldFailAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred retrieving recovery codes", "user is anonymous")
+			},
+		},
+		{
+			"ShouldFailStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				mock.StorageMock.
+					EXPECT().
+					CountRecoveryCodes(mock.Ctx, testUsername).
+					Return(0, fmt.Errorf("failed to connect"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred retrieving recovery codes for user 'john': error occurred counting the recovery codes in the storage backend", "failed to connect")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			RecoveryCodesGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodesPOST(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldGenerate",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				gomock.InOrder(
+					mock.RandomMock.
+						EXPECT().
+						StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper).
+						Return("ABCDEFGHJK", nil).
+						Times(recoveryCodesCount),
+					mock.StorageMock.
+						EXPECT().
+						SaveRecoveryCodes(mock.Ctx, testUsername, gomock.Len(recoveryCodesCount)).
+						Return(nil),
+					mock.UserProviderMock.
+						EXPECT().
+						GetDetails(testUsername).
+						Return(&authentication.UserDetails{Username: testUsername, DisplayName: testDisplayName, Emails: []string{"john@example.com"}}, nil),
+					mock.NotifierMock.
+						EXPECT().
+						Send(mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Recovery Codes Generated", gomock.Any(), gomock.Any()).
+						Return(nil),
+				)
+			},
+			`{"status":"OK","data":{"remaining":10,"codes":["ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK"]}}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldFailAnonymous",
+			nil,
+			`{"status":"KO","message":"Unable to generate recovery codes."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating recovery codes", "user is anonymous")
+			},
+		},
+		{
+			"ShouldFailRandomError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				mock.RandomMock.
+					EXPECT().
+					StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper).
+					Return("", fmt.Errorf("bad entropy"))
+			},
+			`{"status":"KO","message":"Unable to generate recovery codes."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating recovery codes for user 'john'", "error occurred generating the recovery codes: bad entropy")
+			},
+		},
+		{
+			"ShouldFailStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				gomock.InOrder(
+					mock.RandomMock.
+						EXPECT().
+						StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper).
+						Return("ABCDEFGHJK", nil).
+						Times(recoveryCodesCount),
+					mock.StorageMock.
+						EXPECT().
+						SaveRecoveryCodes(mock.Ctx, testUsername, gomock.Len(recoveryCodesCount)).
+						Return(fmt.Errorf("failed to connect")),
+				)
+			},
+			`{"status":"KO","message":"Unable to generate recovery codes."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating recovery codes for user 'john'", "error occurred saving the recovery codes to the storage backend: failed to connect")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Providers.Random = mock.RandomMock
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			RecoveryCodesPOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodePOST(t *testing.T) {
+	testCases := []struct {
+		name           string
+		have           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldAuthenticate",
+			`{"code":"abcde-fghjk"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						ConsumeRecoveryCode(mock.Ctx, testUsername, "abcde-fghjk", mock.Clock.Now(), model.NewNullIP(net.ParseIP("0.0.0.0"))).
+						Return(true, nil),
+					mock.StorageMock.
+						EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+					mock.UserProviderMock.
+						EXPECT().
+						GetDetails(testUsername).
+						Return(&authentication.UserDetails{Username: testUsername, DisplayName: testDisplayName, Emails: []string{"john@example.com"}}, nil),
+					mock.NotifierMock.
+						EXPECT().
+						Send(mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Recovery Code Used", gomock.Any(), gomock.Any()).
+						Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, authentication.TwoFactor, us.AuthenticationLevel)
+				assert.True(t, us.AuthenticationMethodRefs.RecoveryCode)
+			},
+		},
+		{
+			"ShouldFailNotConsumed",
+			`{"code":"ABCDEFGHJK"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						ConsumeRecoveryCode(mock.Ctx, testUsername, "ABCDEFGHJK", mock.Clock.Now(), model.NewNullIP(net.ParseIP("0.0.0.0"))).
+						Return(false, nil),
+					mock.StorageMock.
+						EXPECT().
+						AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+						Return(nil),
+				)
+			},
+			`{"status":"KO","message":"Authentication failed, please retry later."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+
+				assert.Equal(t, authentication.OneFactor, us.AuthenticationLevel)
+				assert.False(t, us.AuthenticationMethodRefs.RecoveryCode)
+			},
+		},
+		{
+			"ShouldFailStorageError",
+			`{"code":"ABCDEFGHJK"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+
+				mock.StorageMock.
+					EXPECT().
+					ConsumeRecoveryCode(mock.Ctx, testUsername, "ABCDEFGHJK", mock.Clock.Now(), model.NewNullIP(net.ParseIP("0.0.0.0"))).
+					Return(false, fmt.Errorf("failed to connect"))
+			},
+			`{"status":"KO","message":"Authentication failed, please retry later."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a recovery code authentication for user 'john': error occurred consuming the recovery code in the storage backend", "failed to connect")
+			},
+		},
+		{
+			"ShouldFailInvalidLength",
+			`{"code":"ABCDE"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+			},
+			`{"status":"KO","message":"Authentication failed, please retry later."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a recovery code authentication for user 'john': expected code length is 10 but the user provided code was 5 characters in length", "")
+			},
+		},
+		{
+			"ShouldFailBadJSON",
+			`{"code":ABCDE"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setRecoveryCodesTestSession(t, mock)
+			},
+			`{"status":"KO","message":"Authentication failed, please retry later."}`,
+			fasthttp.StatusForbidden,
+			nil,
+		},
+		{
+			"ShouldFailAnonymous",
+			`{"code":"ABCDEFGHJK"}`,
+			nil,
+			`{"status":"KO","message":"Authentication failed, please retry later."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a recovery code authentication", "user is anonymous")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Request.SetBodyString(tc.have)
+			mock.Clock.Set(time.Unix(1701295903, 0))
+			mock.Ctx.Clock = &mock.Clock
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			RecoveryCodePOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestReplyRecoveryCodesEnrollment(t *testing.T) {
+	testCases := []struct {
+		name     string
+		setup    func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected string
+	}{
+		{
+			"ShouldNotGenerateWhenRemaining",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.
+					EXPECT().
+					CountRecoveryCodes(mock.Ctx, testUsername).
+					Return(3, nil)
+			},
+			`{"status":"OK"}`,
+		},
+		{
+			"ShouldGenerateWhenNoneRemaining",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(0, nil),
+					mock.RandomMock.
+						EXPECT().
+						StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper).
+						Return("ABCDEFGHJK", nil).
+						Times(recoveryCodesCount),
+					mock.StorageMock.
+						EXPECT().
+						SaveRecoveryCodes(mock.Ctx, testUsername, gomock.Len(recoveryCodesCount)).
+						Return(nil),
+				)
+			},
+			`{"status":"OK","data":{"remaining":10,"codes":["ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK","ABCDE-FGHJK"]}}`,
+		},
+		{
+			"ShouldNotFailOnCountError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.
+					EXPECT().
+					CountRecoveryCodes(mock.Ctx, testUsername).
+					Return(0, fmt.Errorf("failed to connect"))
+			},
+			`{"status":"OK"}`,
+		},
+		{
+			"ShouldNotFailOnSaveError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(0, nil),
+					mock.RandomMock.
+						EXPECT().
+						StringCustomErr(recoveryCodeLength, random.CharSetUnambiguousUpper).
+						Return("ABCDEFGHJK", nil).
+						Times(recoveryCodesCount),
+					mock.StorageMock.
+						EXPECT().
+						SaveRecoveryCodes(mock.Ctx, testUsername, gomock.Len(recoveryCodesCount)).
+						Return(fmt.Errorf("failed to connect")),
+				)
+			},
+			`{"status":"OK"}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Providers.Random = mock.RandomMock
+
+			tc.setup(t, mock)
+
+			replyRecoveryCodesEnrollment(mock.Ctx, testUsername)
+
+			assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestFormatRecoveryCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldFormatFullGroups", "ABCDEFGHJK", "ABCDE-FGHJK"},
+		{"ShouldFormatPartialGroup", "ABCDEFG", "ABCDE-FG"},
+		{"ShouldFormatSingleGroup", "ABC", "ABC"},
+		{"ShouldFormatEmpty", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatRecoveryCode(tc.have))
+		})
+	}
+}
+
+func setRecoveryCodesTestSession(t *testing.T, mock *mocks.MockAutheliaCtx) {
+	us, err := mock.Ctx.GetSession()
+
+	require.NoError(t, err)
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+	us.AuthenticationMethodRefs.UsernameAndPassword = true
+
+	require.NoError(t, mock.Ctx.SaveSession(us))
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// SMSRegisterPUT starts a phone number registration session and sends a One-Time Code to the phone number to verify
+// the user can receive it.
+func SMSRegisterPUT(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		bodyJSON    bodyRegisterSMS
+		number      string
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a phone number registration session: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred generating a phone number registration session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a phone number registration session for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if number, err = model.NormalizePhoneNumber(bodyJSON.PhoneNumber); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a phone number registration session for user '%s': error occurred validating the phone number", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if !handleSMSOneTimeCode(ctx, userSession.Username, model.OTCIntentPhoneNumberVerification, number, false, "generating a phone number registration session", messageUnableToRegisterPhoneNumber) {
+		return
+	}
+
+	userSession.SMS = &session.SMS{
+		PhoneNumber: number,
+		Expires:     ctx.Clock.Now().Add(time.Minute * 10),
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred generating a phone number registration session for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// SMSRegisterPOST handles validation of the One-Time Code sent to the phone number and saves the verified phone
+// number.
+func SMSRegisterPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		bodyJSON    bodyRegisterFinishSMS
+		code        *model.OneTimeCode
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred validating a phone number registration session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if userSession.SMS == nil {
+		ctx.Logger.Errorf("Error occurred validating a phone number registration session for user '%s': the user did not initiate a registration session on their current session", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if ctx.Clock.Now().After(userSession.SMS.Expires) {
+		ctx.Logger.WithError(fmt.Errorf("the registration session is expired")).Errorf("Error occurred validating a phone number registration session for user '%s': error occurred validating the session", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	bodyJSON.Code = strings.TrimSpace(bodyJSON.Code)
+
+	if n := len(bodyJSON.Code); n > model.OneTimeCodeMaximumLength {
+		ctx.Logger.Errorf("Error occurred validating a phone number registration session for user '%s': expected maximum code length is %d but the user provided code was %d characters in length", userSession.Username, model.OneTimeCodeMaximumLength, n)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if code, err = ctx.Providers.StorageProvider.LoadOneTimeCode(ctx, userSession.Username, model.OTCIntentPhoneNumberVerification, bodyJSON.Code); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': error occurred retrieving the code challenge from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if code == nil {
+		err = fmt.Errorf("the code didn't match any recorded code challenges")
+	} else {
+		err = code.Validate(ctx, model.OTCIntentPhoneNumberVerification, []byte(bodyJSON.Code))
+	}
+
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': error occurred validating the user input against the session", userSession.Username)
+
+		ctx.AuditEvent(audit.EventTypeSMSRegister, audit.ResultFailure, userSession.Username, nil)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	code.Consume(ctx)
+
+	if err = ctx.Providers.StorageProvider.ConsumeOneTimeCode(ctx, code); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': error occurred saving the consumption of the code to the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	number := model.NewPhoneNumber(userSession.Username, userSession.SMS.PhoneNumber, ctx.Clock.Now())
+
+	if err = ctx.Providers.StorageProvider.SavePhoneNumber(ctx, number); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': error occurred saving the phone number to the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	userSession.SMS = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a phone number registration session for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeSMSRegister, audit.ResultSuccess, userSession.Username, map[string]any{"phone_number": number.Masked()})
+
+	ctxLogEvent(ctx, userSession.Username, eventLogAction2FAAdded, map[string]any{eventLogKeyAction: eventLogAction2FAAdded, eventLogKeyCategory: eventLogCategoryPhoneNumber})
+
+	replyRecoveryCodesEnrollment(ctx, userSession.Username)
+}
+
+// SMSRegisterDELETE removes a pending phone number registration.
+func SMSRegisterDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting a phone number registration session: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred deleting a phone number registration session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	if userSession.SMS == nil {
+		ctx.ReplyOK()
+
+		return
+	}
+
+	userSession.SMS = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "deleted pending phone number", regulation.AuthTypeSMS, logFmtActionRegistration, userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterPhoneNumber)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// SMSConfigurationDELETE removes a verified phone number.
+func SMSConfigurationDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting a phone number: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToDeletePhoneNumber)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred deleting a phone number")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToDeletePhoneNumber)
+
+		return
+	}
+
+	if _, err = ctx.Providers.StorageProvider.LoadPhoneNumber(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting a phone number for user '%s': error occurred loading the phone number from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToDeletePhoneNumber)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeletePhoneNumber(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deleting a phone number for user '%s': error occurred deleting the phone number from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToDeletePhoneNumber)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeSMSDelete, audit.ResultSuccess, userSession.Username, nil)
+
+	ctxLogEvent(ctx, userSession.Username, eventLogAction2FARemoved, map[string]any{eventLogKeyAction: eventLogAction2FARemoved, eventLogKeyCategory: eventLogCategoryPhoneNumber})
+
+	ctx.ReplyOK()
+}
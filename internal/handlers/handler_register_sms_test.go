@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/sms"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func setSMSRegisterTestSession(t *testing.T, mock *mocks.MockAutheliaCtx, registration *session.SMS) {
+	us, err := mock.Ctx.GetSession()
+
+	require.NoError(t, err)
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+	us.SMS = registration
+
+	require.NoError(t, mock.Ctx.SaveSession(us))
+}
+
+func TestSMSRegisterPUT(t *testing.T) {
+	testCases := []struct {
+		name           string
+		have           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldSendCode",
+			`{"phone_number":"+1 (555) 123-4567"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, gomock.Any()).Return(0, nil),
+					mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentSMSSignIn, gomock.Any()).Return(0, nil),
+					mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentVoiceSignIn, gomock.Any()).Return(0, nil),
+					mock.StorageMock.EXPECT().
+						SaveOneTimeCode(mock.Ctx, gomock.Any()).
+						DoAndReturn(func(_ any, code model.OneTimeCode) (string, error) {
+							assert.Equal(t, model.OTCIntentPhoneNumberVerification, code.Intent)
+
+							return "signature", nil
+						}),
+					mock.SMSMock.EXPECT().
+						Send(mock.Ctx, gomock.Any()).
+						DoAndReturn(func(_ any, msg *sms.Message) error {
+							assert.Equal(t, "+15551234567", msg.To)
+							assert.False(t, msg.Voice)
+
+							return nil
+						}),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+				require.NotNil(t, us.SMS)
+
+				assert.Equal(t, "+15551234567", us.SMS.PhoneNumber)
+				assert.Equal(t, mock.Ctx.Clock.Now().Add(time.Minute*10), us.SMS.Expires)
+			},
+		},
+		{
+			"ShouldHandleInvalidPhoneNumber",
+			`{"phone_number":"5551234567"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating a phone number registration session for user 'john': error occurred validating the phone number", "the phone number '5551234567' is not a valid E.164 phone number")
+			},
+		},
+		{
+			"ShouldHandleRateLimit",
+			`{"phone_number":"+15551234567"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, gomock.Any()).Return(5, nil)
+				mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentSMSSignIn, gomock.Any()).Return(0, nil)
+				mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentVoiceSignIn, gomock.Any()).Return(0, nil)
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusTooManyRequests,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+				assert.Nil(t, us.SMS)
+			},
+		},
+		{
+			"ShouldHandleCountError",
+			`{"phone_number":"+15551234567"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				mock.StorageMock.EXPECT().CountOneTimeCodes(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, gomock.Any()).Return(0, fmt.Errorf("bad conn"))
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating a phone number registration session for user 'john': error occurred counting the sent codes in the storage backend", "bad conn")
+			},
+		},
+		{
+			"ShouldHandleAnonymous",
+			`{"phone_number":"+15551234567"}`,
+			nil,
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred generating a phone number registration session", "user is anonymous")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Clock = &mock.Clock
+			mock.Ctx.Configuration.SMS = schema.SMS{
+				Characters:   6,
+				CodeLifespan: time.Minute * 5,
+				RateLimit:    schema.SMSRateLimit{Requests: 5, Period: time.Hour},
+			}
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.have)
+
+			SMSRegisterPUT(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestSMSRegisterPOST(t *testing.T) {
+	now := time.Unix(1701295903, 0)
+
+	code := func(intent string) *model.OneTimeCode {
+		return &model.OneTimeCode{ID: 1, Username: testUsername, Intent: intent, ExpiresAt: now.Add(time.Minute), Code: []byte("123456")}
+	}
+
+	testCases := []struct {
+		name           string
+		have           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldSavePhoneNumber",
+			`{"code":"123456"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, &session.SMS{PhoneNumber: "+15551234567", Expires: now.Add(time.Minute)})
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().
+						LoadOneTimeCode(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, "123456").
+						Return(code(model.OTCIntentPhoneNumberVerification), nil),
+					mock.StorageMock.EXPECT().
+						ConsumeOneTimeCode(mock.Ctx, gomock.Any()).
+						Return(nil),
+					mock.StorageMock.EXPECT().
+						SavePhoneNumber(mock.Ctx, model.PhoneNumber{CreatedAt: now, Username: testUsername, Number: []byte("+15551234567")}).
+						Return(nil),
+					mock.UserProviderMock.EXPECT().
+						GetDetails(testUsername).
+						Return(nil, fmt.Errorf("not found")),
+					mock.StorageMock.EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(5, nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+				assert.Nil(t, us.SMS)
+			},
+		},
+		{
+			"ShouldFailWithoutRegistrationSession",
+			`{"code":"123456"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a phone number registration session for user 'john': the user did not initiate a registration session on their current session", "")
+			},
+		},
+		{
+			"ShouldFailExpiredRegistrationSession",
+			`{"code":"123456"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, &session.SMS{PhoneNumber: "+15551234567", Expires: now.Add(-time.Minute)})
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a phone number registration session for user 'john': error occurred validating the session", "the registration session is expired")
+			},
+		},
+		{
+			"ShouldFailWrongIntent",
+			`{"code":"123456"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, &session.SMS{PhoneNumber: "+15551234567", Expires: now.Add(time.Minute)})
+
+				mock.StorageMock.EXPECT().
+					LoadOneTimeCode(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, "123456").
+					Return(code(model.OTCIntentSMSSignIn), nil)
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a phone number registration session for user 'john': error occurred validating the user input against the session", "the code challenge has the 'sms' intent but the 'pnv' intent is required")
+			},
+		},
+		{
+			"ShouldFailSave",
+			`{"code":"123456"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, &session.SMS{PhoneNumber: "+15551234567", Expires: now.Add(time.Minute)})
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().
+						LoadOneTimeCode(mock.Ctx, testUsername, model.OTCIntentPhoneNumberVerification, "123456").
+						Return(code(model.OTCIntentPhoneNumberVerification), nil),
+					mock.StorageMock.EXPECT().
+						ConsumeOneTimeCode(mock.Ctx, gomock.Any()).
+						Return(nil),
+					mock.StorageMock.EXPECT().
+						SavePhoneNumber(mock.Ctx, gomock.Any()).
+						Return(fmt.Errorf("bad conn")),
+				)
+			},
+			`{"status":"KO","message":"Unable to register your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred validating a phone number registration session for user 'john': error occurred saving the phone number to the storage backend", "bad conn")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Clock.Set(now)
+			mock.Ctx.Clock = &mock.Clock
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.have)
+
+			SMSRegisterPOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestSMSConfigurationDELETE(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldDelete",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().
+						LoadPhoneNumber(mock.Ctx, testUsername).
+						Return(&model.PhoneNumber{ID: 1, Username: testUsername, Number: []byte("+15551234567")}, nil),
+					mock.StorageMock.EXPECT().
+						DeletePhoneNumber(mock.Ctx, testUsername).
+						Return(nil),
+					mock.UserProviderMock.EXPECT().
+						GetDetails(testUsername).
+						Return(nil, fmt.Errorf("not found")),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldFailWithoutPhoneNumber",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				mock.StorageMock.EXPECT().
+					LoadPhoneNumber(mock.Ctx, testUsername).
+					Return(nil, storage.ErrNoPhoneNumber)
+			},
+			`{"status":"KO","message":"Unable to delete your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred deleting a phone number for user 'john': error occurred loading the phone number from the storage backend", "no phone number for user")
+			},
+		},
+		{
+			"ShouldFailDelete",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setSMSRegisterTestSession(t, mock, nil)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().
+						LoadPhoneNumber(mock.Ctx, testUsername).
+						Return(&model.PhoneNumber{ID: 1, Username: testUsername, Number: []byte("+15551234567")}, nil),
+					mock.StorageMock.EXPECT().
+						DeletePhoneNumber(mock.Ctx, testUsername).
+						Return(fmt.Errorf("bad conn")),
+				)
+			},
+			`{"status":"KO","message":"Unable to delete your phone number."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred deleting a phone number for user 'john': error occurred deleting the phone number from the storage backend", "bad conn")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			SMSConfigurationDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
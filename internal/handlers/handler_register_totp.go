@@ -7,6 +7,7 @@ import (
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
@@ -214,6 +215,8 @@ func TOTPRegisterPOST(ctx *middlewares.AutheliaCtx) {
 	if !valid {
 		ctx.Logger.WithError(fmt.Errorf("user input did not match any expected value")).Errorf("Error occurred validating a TOTP registration session for user '%s'", userSession.Username)
 
+		ctx.AuditEvent(audit.EventTypeTOTPRegister, audit.ResultFailure, userSession.Username, nil)
+
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
 		ctx.SetJSONError(messageUnableToRegisterOneTimePassword)
 
@@ -251,9 +254,11 @@ func TOTPRegisterPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	ctx.AuditEvent(audit.EventTypeTOTPRegister, audit.ResultSuccess, userSession.Username, map[string]any{"algorithm": config.Algorithm, "digits": config.Digits, "period": config.Period})
+
 	ctxLogEvent(ctx, userSession.Username, eventLogAction2FAAdded, map[string]any{eventLogKeyAction: eventLogAction2FAAdded, eventLogKeyCategory: eventLogCategoryOneTimePassword})
 
-	ctx.ReplyOK()
+	replyRecoveryCodesEnrollment(ctx, userSession.Username)
 }
 
 // TOTPRegisterDELETE removes a pending TOTP registration.
@@ -344,6 +349,8 @@ func TOTPConfigurationDELETE(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	ctx.AuditEvent(audit.EventTypeTOTPDelete, audit.ResultSuccess, userSession.Username, nil)
+
 	ctxLogEvent(ctx, userSession.Username, eventLogAction2FARemoved, map[string]any{eventLogKeyAction: eventLogAction2FARemoved, eventLogKeyCategory: eventLogCategoryOneTimePassword})
 
 	ctx.ReplyOK()
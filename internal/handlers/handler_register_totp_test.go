@@ -594,6 +594,10 @@ func TestTOTPRegisterPOST(t *testing.T) {
 						EXPECT().
 						Send(mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Second Factor Method Added", gomock.Any(), gomock.Any()).
 						Return(nil),
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(1, nil),
 				)
 			},
 			`{"status":"OK"}`,
@@ -650,6 +654,10 @@ func TestTOTPRegisterPOST(t *testing.T) {
 						EXPECT().
 						Send(mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Second Factor Method Added", gomock.Any(), gomock.Any()).
 						Return(nil),
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(1, nil),
 				)
 			},
 			`{"status":"OK"}`,
@@ -699,6 +707,10 @@ func TestTOTPRegisterPOST(t *testing.T) {
 						EXPECT().
 						Send(mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Second Factor Method Added", gomock.Any(), gomock.Any()).
 						Return(fmt.Errorf("kittens")),
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(1, nil),
 				)
 			},
 			`{"status":"OK"}`,
@@ -746,6 +758,10 @@ func TestTOTPRegisterPOST(t *testing.T) {
 						EXPECT().
 						GetDetails(testUsername).
 						Return(&authentication.UserDetails{Username: testUsername, DisplayName: testDisplayName}, nil),
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(1, nil),
 				)
 			},
 			`{"status":"OK"}`,
@@ -793,6 +809,10 @@ func TestTOTPRegisterPOST(t *testing.T) {
 						EXPECT().
 						GetDetails(testUsername).
 						Return(nil, fmt.Errorf("lookup failure")),
+					mock.StorageMock.
+						EXPECT().
+						CountRecoveryCodes(mock.Ctx, testUsername).
+						Return(1, nil),
 				)
 			},
 			`{"status":"OK"}`,
@@ -10,6 +10,7 @@ import (
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -97,7 +98,7 @@ func WebAuthnRegistrationPUT(ctx *middlewares.AutheliaCtx) {
 	opts := []webauthn.RegistrationOption{
 		webauthn.WithExclusions(user.WebAuthnCredentialDescriptors()),
 		webauthn.WithExtensions(map[string]any{"credProps": true}),
-		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementDiscouraged),
+		webauthn.WithResidentKeyRequirement(ctx.Configuration.WebAuthn.SelectionCriteria.Discoverability),
 	}
 
 	data := session.WebAuthn{
@@ -219,6 +220,15 @@ func WebAuthnRegistrationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if c.Flags.BackupEligible && ctx.Configuration.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups(userSession.Groups) {
+		ctx.Logger.WithError(fmt.Errorf("the credential is eligible to be backed up which is prohibited by the filtering policy")).Errorf("Error occurred validating a WebAuthn registration challenge for user '%s': error occurred validating the credential", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToRegisterSecurityKey)
+
+		return
+	}
+
 	credential := model.NewWebAuthnCredential(ctx, w.Config.RPID, userSession.Username, userSession.WebAuthn.Description, c)
 
 	credential.Discoverable = handleWebAuthnCredentialCreationIsDiscoverable(ctx, response)
@@ -232,9 +242,11 @@ func WebAuthnRegistrationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	ctx.ReplyOK()
+	replyRecoveryCodesEnrollment(ctx, userSession.Username)
 	ctx.SetStatusCode(fasthttp.StatusCreated)
 
+	ctx.AuditEvent(audit.EventTypeWebAuthnRegister, audit.ResultSuccess, userSession.Username, map[string]any{"description": credential.Description})
+
 	ctxLogEvent(ctx, userSession.Username, eventLogAction2FAAdded, map[string]any{eventLogKeyAction: eventLogAction2FAAdded, eventLogKeyCategory: eventLogCategoryWebAuthnCredential, eventLogKeyDescription: credential.Description})
 }
 
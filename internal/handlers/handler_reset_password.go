@@ -8,6 +8,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -33,9 +35,7 @@ func ResetPasswordDELETE(ctx *middlewares.AutheliaCtx) {
 	}
 
 	token, err = jwt.ParseWithClaims(body.Token, &model.IdentityVerificationClaim{},
-		func(token *jwt.Token) (any, error) {
-			return []byte(ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret), nil
-		},
+		ctx.IdentityVerificationJWTKeyFunc,
 		jwt.WithIssuedAt(),
 		jwt.WithIssuer("Authelia"),
 		jwt.WithStrictDecoding(),
@@ -148,12 +148,26 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.Providers.PasswordPolicy.Check(requestBody.Password); err != nil {
-		ctx.Error(err, messagePasswordWeak)
+	var userInfo *authentication.UserDetails
+
+	if userInfo, err = ctx.Providers.UserProvider.GetDetails(username); err != nil {
+		ctx.Error(fmt.Errorf("error occurred retrieving details for user '%s': %w", username, err), messageUnableToResetPassword)
+		return
+	}
+
+	if err = ctx.Providers.PasswordPolicy.CheckForGroups(requestBody.Password, userInfo.Groups); err != nil {
+		ctx.RecordPasswordReset(false)
+		ctx.AuditEvent(audit.EventTypePasswordReset, audit.ResultFailure, username, map[string]any{"reason": "password policy"})
+		ctx.Logger.Error(err)
+		ctx.SetPasswordPolicyErrorJSON(err, messagePasswordWeak)
+
 		return
 	}
 
 	if err = ctx.Providers.UserProvider.UpdatePassword(username, requestBody.Password); err != nil {
+		ctx.RecordPasswordReset(false)
+		ctx.AuditEvent(audit.EventTypePasswordReset, audit.ResultFailure, username, nil)
+
 		switch {
 		case utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityCodes),
 			utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityErrors):
@@ -167,6 +181,9 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	ctx.Logger.Debugf("Password of user %s has been reset", username)
 
+	ctx.RecordPasswordReset(true)
+	ctx.AuditEvent(audit.EventTypePasswordReset, audit.ResultSuccess, username, nil)
+
 	// Reset the request.
 	userSession.PasswordResetUsername = nil
 
@@ -176,14 +193,6 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	// Send Notification.
-	userInfo, err := ctx.Providers.UserProvider.GetDetails(username)
-	if err != nil {
-		ctx.Logger.Error(err)
-		ctx.ReplyOK()
-
-		return
-	}
-
 	if len(userInfo.Emails) == 0 {
 		ctx.Logger.Error(fmt.Errorf("user %s has no email address configured", username))
 		ctx.ReplyOK()
@@ -0,0 +1,452 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/saml"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// SAMLMetadataGET handles GET requests to the SAML 2.0 metadata endpoint.
+func SAMLMetadataGET(ctx *middlewares.AutheliaCtx) {
+	issuer, err := ctx.IssuerURL()
+	if err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred determining the issuer for the SAML metadata")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	ctx.SetContentTypeApplicationSAMLMetadata()
+	ctx.SetBody(ctx.Providers.SAML.Metadata(issuer))
+}
+
+// SAMLSingleSignOnService handles GET and POST requests to the SAML 2.0 single sign-on service with the HTTP-Redirect
+// and HTTP-POST bindings respectively. Users who are not authenticated with the level required by the service provider
+// are redirected to the login portal which redirects them back to this endpoint with the HTTP-Redirect binding.
+func SAMLSingleSignOnService(ctx *middlewares.AutheliaCtx) {
+	var (
+		issuer  *url.URL
+		request *saml.AuthnRequest
+		sp      *saml.ServiceProvider
+		acs     string
+		format  string
+		err     error
+	)
+
+	if issuer, err = ctx.IssuerURL(); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred determining the issuer for the SAML authentication request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	raw, relayState, err := handleSAMLDecodeMessage(ctx, saml.FormParameterSAMLRequest)
+	if err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred decoding the SAML authentication request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if request, err = saml.ParseAuthnRequest(raw); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred parsing the SAML authentication request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if sp, err = ctx.Providers.SAML.GetServiceProvider(request.Issuer); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' could not be processed", request.ID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if acs, err = sp.GetAssertionConsumerServiceURL(request); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed", request.ID, sp.EntityID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if format, err = sp.GetNameIDFormat(request); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed", request.ID, sp.EntityID)
+
+		handleSAMLFormPost(ctx, acs, saml.FormParameterSAMLResponse, ctx.Providers.SAML.NewErrorResponse(issuer, request, acs, saml.StatusInvalidNameIDPolicy, ctx.Clock.Now()), relayState)
+
+		return
+	}
+
+	userSession, err := ctx.GetSession()
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed: error occurred retrieving the user session", request.ID, sp.EntityID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if userSession.IsAnonymous() || !authorization.IsAuthLevelSufficient(userSession.AuthenticationLevel, sp.Policy) {
+		ctx.Logger.Debugf("SAML authentication request with id '%s' from service provider '%s' requires the user to authenticate with the %s level", request.ID, sp.EntityID, sp.Policy)
+
+		handleSAMLRedirectToPortal(ctx, issuer, request, relayState)
+
+		return
+	}
+
+	var nameID string
+
+	if nameID, err = handleSAMLNameID(ctx, sp, format, &userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed: error occurred determining the name identifier", request.ID, sp.EntityID)
+
+		handleSAMLFormPost(ctx, acs, saml.FormParameterSAMLResponse, ctx.Providers.SAML.NewErrorResponse(issuer, request, acs, saml.StatusResponder, ctx.Clock.Now()), relayState)
+
+		return
+	}
+
+	level := authorization.OneFactor
+
+	if userSession.AuthenticationLevel >= authentication.TwoFactor {
+		level = authorization.TwoFactor
+	}
+
+	authenticatedAt, _ := userSession.AuthenticatedTime(level)
+
+	now := ctx.Clock.Now()
+
+	samlSession := model.SAMLSession{
+		SessionIndex: ctx.Providers.SAML.NewID(),
+		EntityID:     sp.EntityID,
+		Username:     userSession.Username,
+		NameID:       nameID,
+		NameIDFormat: format,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(ctx.Providers.SAML.GetSessionLifespan()),
+	}
+
+	var response []byte
+
+	if response, err = ctx.Providers.SAML.NewResponse(issuer, sp, &saml.Assertion{
+		Request:         request,
+		Destination:     acs,
+		NameID:          nameID,
+		NameIDFormat:    format,
+		SessionIndex:    samlSession.SessionIndex,
+		Username:        userSession.Username,
+		DisplayName:     userSession.DisplayName,
+		Emails:          userSession.Emails,
+		Groups:          userSession.Groups,
+		Level:           level,
+		AuthenticatedAt: authenticatedAt,
+		IssuedAt:        now,
+	}); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed: error occurred generating the response", request.ID, sp.EntityID)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveSAMLSession(ctx, samlSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' from service provider '%s' could not be processed: error occurred saving the session", request.ID, sp.EntityID)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeSAMLAssertion, audit.ResultSuccess, userSession.Username, map[string]any{"entity_id": sp.EntityID, "name_id_format": format, "session_index": samlSession.SessionIndex})
+
+	ctx.Logger.Debugf("SAML authentication request with id '%s' from service provider '%s' was processed successfully for user '%s'", request.ID, sp.EntityID, userSession.Username)
+
+	handleSAMLFormPost(ctx, acs, saml.FormParameterSAMLResponse, response, relayState)
+}
+
+// SAMLSingleLogoutService handles GET requests to the SAML 2.0 single logout service with the HTTP-Redirect binding.
+// The logout request must be signed with the certificate of the service provider. The SAML sessions identified by the
+// session indexes of the logout request are revoked, and if they belong to the current user the user is logged out of
+// Authelia. The logout response is sent to the service provider with the HTTP-Redirect binding.
+func SAMLSingleLogoutService(ctx *middlewares.AutheliaCtx) {
+	var (
+		issuer  *url.URL
+		request *saml.LogoutRequest
+		sp      *saml.ServiceProvider
+		err     error
+	)
+
+	if issuer, err = ctx.IssuerURL(); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred determining the issuer for the SAML logout request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	raw, relayState, err := handleSAMLDecodeMessage(ctx, saml.FormParameterSAMLRequest)
+	if err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred decoding the SAML logout request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if request, err = saml.ParseLogoutRequest(raw); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred parsing the SAML logout request")
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if sp, err = ctx.Providers.SAML.GetServiceProvider(request.Issuer); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML logout request with id '%s' could not be processed", request.ID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	if err = sp.VerifyRedirectSignature(ctx.URI().QueryString(), saml.FormParameterSAMLRequest); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML logout request with id '%s' from service provider '%s' could not be processed", request.ID, sp.EntityID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	username := handleSAMLRevokeSessions(ctx, sp, request)
+
+	status := saml.StatusSuccess
+
+	if userSession, _ := ctx.GetSession(); username != "" && userSession.Username == username {
+		id := getUserSessionID(ctx)
+
+		if err = ctx.DestroySession(); err != nil {
+			ctx.Logger.WithError(err).Errorf("SAML logout request with id '%s' from service provider '%s' could not be processed: error occurred destroying the session", request.ID, sp.EntityID)
+
+			status = saml.StatusResponder
+		} else {
+			unindexUserSession(ctx, id)
+
+			if err = ctx.Providers.StorageProvider.RevokeSAMLSessionsByUsername(ctx, username, now); err != nil {
+				ctx.Logger.WithError(err).Errorf("Error occurred revoking the SAML sessions of user '%s'", username)
+			}
+		}
+
+		ctx.AuditEvent(audit.EventTypeSAMLLogout, audit.NewResult(err == nil), username, map[string]any{"entity_id": sp.EntityID})
+	}
+
+	var redirect *url.URL
+
+	if redirect, err = ctx.Providers.SAML.NewLogoutResponseURL(issuer, sp, request, status, relayState, now); err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML logout request with id '%s' from service provider '%s' could not be responded to", request.ID, sp.EntityID)
+
+		ctx.Redirect(ctx.RootURLSlash().String(), fasthttp.StatusFound)
+
+		return
+	}
+
+	ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+}
+
+// handleSAMLRevokeSessions revokes the SAML sessions of the service provider identified by the session indexes of a
+// logout request and returns the username of the user the sessions belong to.
+func handleSAMLRevokeSessions(ctx *middlewares.AutheliaCtx, sp *saml.ServiceProvider, request *saml.LogoutRequest) (username string) {
+	var (
+		samlSession *model.SAMLSession
+		err         error
+	)
+
+	for _, index := range request.SessionIndexes {
+		if samlSession, err = ctx.Providers.StorageProvider.LoadSAMLSession(ctx, index); err != nil {
+			if !errors.Is(err, storage.ErrNoSAMLSession) {
+				ctx.Logger.WithError(err).Errorf("Error occurred loading the SAML session with session index '%s'", index)
+			}
+
+			continue
+		}
+
+		if samlSession.EntityID != sp.EntityID || samlSession.NameID != request.NameID || samlSession.IsRevoked() {
+			continue
+		}
+
+		if err = ctx.Providers.StorageProvider.RevokeSAMLSession(ctx, index, ctx.Clock.Now()); err != nil && !errors.Is(err, storage.ErrNoSAMLSession) {
+			ctx.Logger.WithError(err).Errorf("Error occurred revoking the SAML session with session index '%s'", index)
+
+			continue
+		}
+
+		username = samlSession.Username
+	}
+
+	return username
+}
+
+// handleSAMLWorkflowResponse responds to the login portal with the SAML 2.0 single sign-on service URL the user is
+// redirected to once authenticated.
+func handleSAMLWorkflowResponse(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, targetURI string) {
+	var (
+		targetURL *url.URL
+		err       error
+	)
+
+	if targetURL, err = url.ParseRequestURI(targetURI); err != nil {
+		ctx.Error(fmt.Errorf("unable to parse target URL '%s': %w", targetURI, err), messageAuthenticationFailed)
+
+		return
+	}
+
+	issuerURL := ctx.RootURL()
+
+	if targetURL.Host != issuerURL.Host || targetURL.Path != path.Join(issuerURL.Path, saml.EndpointPathSingleSignOnService) {
+		ctx.Error(fmt.Errorf("unable to redirect to '%s': target is not the saml single sign-on service of the issuer '%s'", targetURL, issuerURL), messageAuthenticationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Error(fmt.Errorf("unable to redirect to '%s': user is anonymous", targetURL), messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(redirectResponse{Redirect: targetURL.String()}); err != nil {
+		ctx.Logger.Errorf("Unable to set default redirection URL in body: %s", err)
+	}
+}
+
+// handleSAMLRedirectToPortal redirects the user to the login portal with the SAML workflow. The authentication request
+// is encoded with the HTTP-Redirect binding in the target URL so the user can be sent back once authenticated.
+func handleSAMLRedirectToPortal(ctx *middlewares.AutheliaCtx, issuer *url.URL, request *saml.AuthnRequest, relayState string) {
+	encoded, err := saml.EncodeRedirect(request.Raw)
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("SAML authentication request with id '%s' could not be processed: error occurred encoding the request", request.ID)
+
+		ctx.ReplyBadRequest()
+
+		return
+	}
+
+	target := *issuer
+
+	target.Path = path.Join(target.Path, saml.EndpointPathSingleSignOnService)
+
+	query := url.Values{saml.FormParameterSAMLRequest: []string{encoded}}
+
+	if relayState != "" {
+		query.Set(saml.FormParameterRelayState, relayState)
+	}
+
+	target.RawQuery = query.Encode()
+
+	redirect := ctx.RootURLSlash()
+
+	redirect.RawQuery = url.Values{queryArgRD: []string{target.String()}, queryArgWorkflow: []string{workflowSAML}}.Encode()
+
+	ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+}
+
+// handleSAMLNameID returns the name identifier of the user for the service provider with the given format.
+func handleSAMLNameID(ctx *middlewares.AutheliaCtx, sp *saml.ServiceProvider, format string, userSession *session.UserSession) (nameID string, err error) {
+	switch format {
+	case saml.NameIDFormatEmail:
+		if len(userSession.Emails) == 0 {
+			return "", fmt.Errorf("the user '%s' does not have an email address", userSession.Username)
+		}
+
+		return userSession.Emails[0], nil
+	case saml.NameIDFormatPersistent:
+		var opaqueID *model.UserOpaqueIdentifier
+
+		if opaqueID, err = ctx.Providers.StorageProvider.LoadUserOpaqueIdentifierBySignature(ctx, saml.OpaqueIdentifierService, sp.EntityID, userSession.Username); err != nil {
+			return "", err
+		} else if opaqueID == nil {
+			if opaqueID, err = model.NewUserOpaqueIdentifier(saml.OpaqueIdentifierService, sp.EntityID, userSession.Username); err != nil {
+				return "", err
+			}
+
+			if err = ctx.Providers.StorageProvider.SaveUserOpaqueIdentifier(ctx, *opaqueID); err != nil {
+				return "", err
+			}
+		}
+
+		return opaqueID.Identifier.String(), nil
+	case saml.NameIDFormatTransient:
+		return ctx.Providers.SAML.NewID(), nil
+	default:
+		return userSession.Username, nil
+	}
+}
+
+// handleSAMLDecodeMessage decodes a SAML message and the relay state from the query arguments of the HTTP-Redirect
+// binding or the form of the HTTP-POST binding.
+func handleSAMLDecodeMessage(ctx *middlewares.AutheliaCtx, parameter string) (raw []byte, relayState string, err error) {
+	var (
+		value  []byte
+		decode func(value []byte) (raw []byte, err error)
+	)
+
+	if ctx.IsPost() {
+		value, relayState, decode = ctx.PostArgs().Peek(parameter), string(ctx.PostArgs().Peek(saml.FormParameterRelayState)), saml.DecodePost
+	} else {
+		value, relayState, decode = ctx.QueryArgs().Peek(parameter), string(ctx.QueryArgs().Peek(saml.FormParameterRelayState)), saml.DecodeRedirect
+	}
+
+	if len(value) == 0 {
+		return nil, "", fmt.Errorf("the '%s' parameter is required", parameter)
+	}
+
+	if raw, err = decode(value); err != nil {
+		return nil, "", err
+	}
+
+	return raw, relayState, nil
+}
+
+// handleSAMLFormPost sends a SAML message to the service provider with the HTTP-POST binding.
+func handleSAMLFormPost(ctx *middlewares.AutheliaCtx, destination, parameter string, raw []byte, relayState string) {
+	parameters := url.Values{parameter: []string{saml.EncodePost(raw)}}
+
+	if relayState != "" {
+		parameters.Set(saml.FormParameterRelayState, relayState)
+	}
+
+	ctx.SetUserValue(middlewares.UserValueKeyOpenIDConnectResponseModeFormPost, true)
+
+	ctx.SetContentTypeTextHTML()
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, "no-store")
+	ctx.Response.Header.Set(fasthttp.HeaderPragma, "no-cache")
+
+	if err := ctx.Providers.Templates.GetOpenIDConnectAuthorizeResponseFormPostTemplate().Execute(ctx.Response.BodyWriter(), struct {
+		RedirURL   string
+		Parameters url.Values
+	}{
+		RedirURL:   destination,
+		Parameters: parameters,
+	}); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred rendering the SAML form post")
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+	}
+}
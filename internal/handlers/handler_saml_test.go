@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/saml"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func TestHandleSAMLWorkflowResponse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		level    authentication.Level
+		expected string
+	}{
+		{"ShouldRedirectToSingleSignOnService", "https://example.com/api/saml/sso?SAMLRequest=abc&RelayState=xyz", authentication.OneFactor, "https://example.com/api/saml/sso?SAMLRequest=abc&RelayState=xyz"},
+		{"ShouldFailOtherHost", "https://app.example.com/api/saml/sso?SAMLRequest=abc", authentication.OneFactor, ""},
+		{"ShouldFailOtherPath", "https://example.com/api/oidc/authorization?SAMLRequest=abc", authentication.OneFactor, ""},
+		{"ShouldFailAnonymous", "https://example.com/api/saml/sso?SAMLRequest=abc", authentication.NotAuthenticated, ""},
+		{"ShouldFailInvalidURL", "not a url", authentication.OneFactor, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			userSession := &session.UserSession{AuthenticationLevel: tc.level}
+
+			if tc.level != authentication.NotAuthenticated {
+				userSession.Username = testUsername
+			}
+
+			handleSAMLWorkflowResponse(mock.Ctx, userSession, tc.have)
+
+			if tc.expected == "" {
+				mock.Assert200KO(t, messageAuthenticationFailed)
+			} else {
+				mock.Assert200OK(t, redirectResponse{Redirect: tc.expected})
+			}
+		})
+	}
+}
+
+func TestHandleSAMLDecodeMessage(t *testing.T) {
+	message := []byte(`<samlp:LogoutRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" ID="_abc"></samlp:LogoutRequest>`)
+
+	encoded, err := saml.EncodeRedirect(message)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		method     string
+		args       url.Values
+		expected   []byte
+		relayState string
+		err        string
+	}{
+		{"ShouldDecodeRedirectBinding", fasthttp.MethodGet, url.Values{saml.FormParameterSAMLRequest: []string{encoded}, saml.FormParameterRelayState: []string{"xyz"}}, message, "xyz", ""},
+		{"ShouldDecodePostBinding", fasthttp.MethodPost, url.Values{saml.FormParameterSAMLRequest: []string{saml.EncodePost(message)}}, message, "", ""},
+		{"ShouldFailMissingParameter", fasthttp.MethodGet, url.Values{saml.FormParameterRelayState: []string{"xyz"}}, nil, "", "the 'SAMLRequest' parameter is required"},
+		{"ShouldFailInvalidEncoding", fasthttp.MethodPost, url.Values{saml.FormParameterSAMLRequest: []string{"not base64!"}}, nil, "", "error decoding the message: illegal base64 data at input byte 9"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Request.Header.SetMethod(tc.method)
+
+			if tc.method == fasthttp.MethodPost {
+				mock.Ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+				mock.Ctx.Request.SetBodyString(tc.args.Encode())
+			} else {
+				mock.Ctx.Request.URI().SetQueryString(tc.args.Encode())
+			}
+
+			raw, relayState, err := handleSAMLDecodeMessage(mock.Ctx, saml.FormParameterSAMLRequest)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, raw)
+				assert.Equal(t, tc.relayState, relayState)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/scim"
+)
+
+// SCIMServiceProviderConfigGET returns the SCIM 2.0 service provider configuration which describes the features
+// supported by the SCIM 2.0 endpoints.
+func SCIMServiceProviderConfigGET(ctx *middlewares.AutheliaCtx) {
+	scimReply(ctx, fasthttp.StatusOK, scim.ServiceProviderConfig{
+		Schemas:          []string{scim.SchemaServiceProviderConfig},
+		DocumentationURI: "https://www.authelia.com/configuration/miscellaneous/server-endpoints-scim/",
+		Patch:            scim.Supported{Supported: true},
+		Filter:           scim.FilterSupported{Supported: true, MaxResults: scimMaxResults},
+		AuthenticationSchemes: []scim.AuthenticationScheme{
+			{
+				Type:        "oauthbearertoken",
+				Name:        "OAuth Bearer Token",
+				Description: "Authentication using the bearer token configured in Authelia.",
+				Primary:     true,
+			},
+		},
+		Meta: &scim.Meta{ResourceType: "ServiceProviderConfig", Location: scimLocation(ctx, "ServiceProviderConfig", "")},
+	})
+}
+
+// SCIMResourceTypesGET returns the SCIM 2.0 resource types supported by the SCIM 2.0 endpoints.
+func SCIMResourceTypesGET(ctx *middlewares.AutheliaCtx) {
+	resources := []any{
+		scim.ResourceType{
+			Schemas:  []string{scim.SchemaResourceType},
+			ID:       scim.ResourceTypeUser,
+			Name:     scim.ResourceTypeUser,
+			Endpoint: "/Users",
+			Schema:   scim.SchemaUser,
+			Meta:     &scim.Meta{ResourceType: "ResourceType", Location: scimLocation(ctx, "ResourceTypes", scim.ResourceTypeUser)},
+		},
+		scim.ResourceType{
+			Schemas:  []string{scim.SchemaResourceType},
+			ID:       scim.ResourceTypeGroup,
+			Name:     scim.ResourceTypeGroup,
+			Endpoint: "/Groups",
+			Schema:   scim.SchemaGroup,
+			Meta:     &scim.Meta{ResourceType: "ResourceType", Location: scimLocation(ctx, "ResourceTypes", scim.ResourceTypeGroup)},
+		},
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scim.NewListResponse(len(resources), 1, resources))
+}
+
+// scimReply writes the SCIM 2.0 response with the given status code.
+func scimReply(ctx *middlewares.AutheliaCtx, status int, data any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred handling a SCIM request: %s", errStrRespBody)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.SetStatusCode(status)
+	ctx.SetContentType(scim.ContentType)
+	ctx.SetBody(body)
+}
+
+// scimReplyError writes the SCIM 2.0 error message as the response. Errors which are not SCIM 2.0 error messages are
+// written as an internal server error without any detail.
+func scimReplyError(ctx *middlewares.AutheliaCtx, err error) {
+	var e *scim.Error
+
+	if !errors.As(err, &e) {
+		e = scim.NewError(fasthttp.StatusInternalServerError, "", "An unexpected error occurred.")
+	}
+
+	status, _ := strconv.Atoi(e.Status)
+
+	scimReply(ctx, status, e)
+}
+
+// scimLocation returns the absolute URL of a SCIM 2.0 resource.
+func scimLocation(ctx *middlewares.AutheliaCtx, endpoint, id string) string {
+	location := ctx.RootURL().JoinPath("scim", "v2", endpoint)
+
+	if id != "" {
+		location = location.JoinPath(id)
+	}
+
+	return location.String()
+}
+
+// scimParseID returns the id of the resource of the request. The resource id is always a UUID so any other value
+// is a resource that doesn't exist.
+func scimParseID(ctx *middlewares.AutheliaCtx) (id uuid.UUID, err error) {
+	value, _ := ctx.UserValue("id").(string)
+
+	if id, err = uuid.Parse(value); err != nil {
+		return id, scim.NewError(fasthttp.StatusNotFound, "", "The resource '"+value+"' was not found.")
+	}
+
+	return id, nil
+}
+
+// scimParseListParameters returns the 1-based start index and the maximum number of resources of the list request from
+// the startIndex and count query parameters.
+func scimParseListParameters(ctx *middlewares.AutheliaCtx) (start, limit int) {
+	start, limit = 1, scimMaxResults
+
+	if value, err := strconv.Atoi(string(ctx.QueryArgs().Peek("startIndex"))); err == nil && value > 1 {
+		start = value
+	}
+
+	if value, err := strconv.Atoi(string(ctx.QueryArgs().Peek("count"))); err == nil && value < limit {
+		limit = max(value, 0)
+	}
+
+	return start, limit
+}
+
+// scimDecodeBody decodes the request body.
+func scimDecodeBody(ctx *middlewares.AutheliaCtx, v any) (err error) {
+	if err = json.Unmarshal(ctx.PostBody(), v); err != nil {
+		return scim.NewError(fasthttp.StatusBadRequest, scim.ErrorTypeInvalidSyntax, "The request body is not valid.")
+	}
+
+	return nil
+}
+
+func scimConflict(detail string) error {
+	return scim.NewError(fasthttp.StatusConflict, scim.ErrorTypeUniqueness, detail)
+}
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/scim"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// SCIMGroupsGET lists the SCIM 2.0 groups which match the filter of the request.
+func SCIMGroupsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		filter  *scim.Filter
+		groups  []model.DirectoryGroup
+		members []model.DirectoryGroupMember
+		total   int
+		err     error
+	)
+
+	if filter, err = scim.ParseFilter(string(ctx.QueryArgs().Peek("filter")), "displayName", "externalId"); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred listing the SCIM groups: error occurred parsing the filter")
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	start, limit := scimParseListParameters(ctx)
+
+	resources := []any{}
+
+	if filter != nil && filter.Value == "" {
+		scimReply(ctx, fasthttp.StatusOK, scim.NewListResponse(0, start, resources))
+
+		return
+	}
+
+	f := model.DirectoryGroupFilter{}
+
+	if filter != nil {
+		switch filter.Attribute {
+		case "displayName":
+			f.DisplayName = filter.Value
+		default:
+			f.ExternalID = filter.Value
+		}
+	}
+
+	if total, err = ctx.Providers.StorageProvider.CountDirectoryGroups(ctx, f); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred listing the SCIM groups: error occurred counting the groups in the storage backend")
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if limit > 0 {
+		if groups, err = ctx.Providers.StorageProvider.LoadDirectoryGroups(ctx, f, limit, start-1); err != nil {
+			ctx.Logger.WithError(err).Error("Error occurred listing the SCIM groups: error occurred loading the groups from the storage backend")
+
+			scimReplyError(ctx, err)
+
+			return
+		}
+	}
+
+	excludeMembers := strings.Contains(strings.ToLower(string(ctx.QueryArgs().Peek("excludedAttributes"))), "members")
+
+	for i := range groups {
+		members = nil
+
+		if !excludeMembers {
+			if members, err = ctx.Providers.StorageProvider.LoadDirectoryGroupMembers(ctx, groups[i].PublicID); err != nil {
+				ctx.Logger.WithError(err).Errorf("Error occurred listing the SCIM groups: error occurred loading the members of the group with id '%s' from the storage backend", groups[i].PublicID)
+
+				scimReplyError(ctx, err)
+
+				return
+			}
+		}
+
+		resources = append(resources, scimGroupFromModel(ctx, &groups[i], members))
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scim.NewListResponse(total, start, resources))
+}
+
+// SCIMGroupGET returns a SCIM 2.0 group.
+func SCIMGroupGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		id      uuid.UUID
+		group   *model.DirectoryGroup
+		members []model.DirectoryGroupMember
+		err     error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if group, members, err = scimLoadGroup(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the SCIM group with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scimGroupFromModel(ctx, group, members))
+}
+
+// SCIMGroupsPOST provisions a new SCIM 2.0 group.
+func SCIMGroupsPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		request scim.Group
+		id      uuid.UUID
+		members []uuid.UUID
+		err     error
+	)
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning a SCIM group: %s", errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if id, err = uuid.NewRandom(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM group '%s': error occurred generating the id", request.DisplayName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if members, err = scimValidateGroup(ctx, &request, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM group '%s'", request.DisplayName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	group := model.DirectoryGroup{
+		PublicID:    id,
+		ExternalID:  sql.NullString{String: request.ExternalID, Valid: request.ExternalID != ""},
+		DisplayName: request.DisplayName,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveDirectoryGroup(ctx, group); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM group '%s': error occurred saving the group to the storage backend", group.DisplayName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	var saved []model.DirectoryGroupMember
+
+	if saved, err = scimSaveGroupMembers(ctx, id, members); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM group '%s'", group.DisplayName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Provisioned the SCIM group '%s' with id '%s'", group.DisplayName, group.PublicID)
+
+	response := scimGroupFromModel(ctx, &group, saved)
+
+	ctx.Response.Header.Set(fasthttp.HeaderLocation, response.Meta.Location)
+
+	scimReply(ctx, fasthttp.StatusCreated, response)
+}
+
+// SCIMGroupPUT replaces the attributes and members of a SCIM 2.0 group.
+func SCIMGroupPUT(ctx *middlewares.AutheliaCtx) {
+	var (
+		id      uuid.UUID
+		group   *model.DirectoryGroup
+		request scim.Group
+		err     error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if group, _, err = scimLoadGroup(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred replacing the SCIM group with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred replacing the SCIM group with id '%s': %s", id, errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimUpdateGroup(ctx, group, &request)
+}
+
+// SCIMGroupPATCH modifies the attributes and members of a SCIM 2.0 group with the operations of the patch request.
+func SCIMGroupPATCH(ctx *middlewares.AutheliaCtx) {
+	var (
+		id      uuid.UUID
+		group   *model.DirectoryGroup
+		members []model.DirectoryGroupMember
+		request scim.PatchRequest
+		err     error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if group, members, err = scimLoadGroup(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM group with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM group with id '%s': %s", id, errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	patched := scimGroupFromModel(ctx, group, members)
+
+	if err = patched.ApplyPatch(request.Operations); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM group with id '%s': error occurred applying the patch operations", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimUpdateGroup(ctx, group, patched)
+}
+
+// SCIMGroupDELETE deprovisions a SCIM 2.0 group.
+func SCIMGroupDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		id  uuid.UUID
+		err error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteDirectoryGroup(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deprovisioning the SCIM group with id '%s': error occurred deleting the group from the storage backend", id)
+
+		if errors.Is(err, storage.ErrNoDirectoryGroup) {
+			err = scim.NewError(fasthttp.StatusNotFound, "", fmt.Sprintf("The group '%s' was not found.", id))
+		}
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Deprovisioned the SCIM group with id '%s'", id)
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// scimUpdateGroup replaces the attributes and members of the group with those of the request and writes the response.
+func scimUpdateGroup(ctx *middlewares.AutheliaCtx, group *model.DirectoryGroup, request *scim.Group) {
+	var (
+		members []uuid.UUID
+		saved   []model.DirectoryGroupMember
+		err     error
+	)
+
+	if members, err = scimValidateGroup(ctx, request, group.PublicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred updating the SCIM group with id '%s'", group.PublicID)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	group.ExternalID = sql.NullString{String: request.ExternalID, Valid: request.ExternalID != ""}
+	group.DisplayName = request.DisplayName
+	group.UpdatedAt = ctx.Clock.Now()
+
+	if err = ctx.Providers.StorageProvider.UpdateDirectoryGroup(ctx, *group); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred updating the SCIM group with id '%s': error occurred saving the group to the storage backend", group.PublicID)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if saved, err = scimSaveGroupMembers(ctx, group.PublicID, members); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred updating the SCIM group with id '%s'", group.PublicID)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scimGroupFromModel(ctx, group, saved))
+}
+
+// scimLoadGroup loads the group and its members from the storage backend.
+func scimLoadGroup(ctx *middlewares.AutheliaCtx, id uuid.UUID) (group *model.DirectoryGroup, members []model.DirectoryGroupMember, err error) {
+	if group, err = ctx.Providers.StorageProvider.LoadDirectoryGroup(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrNoDirectoryGroup) {
+			return nil, nil, scim.NewError(fasthttp.StatusNotFound, "", fmt.Sprintf("The group '%s' was not found.", id))
+		}
+
+		return nil, nil, fmt.Errorf("error occurred loading the group from the storage backend: %w", err)
+	}
+
+	if members, err = ctx.Providers.StorageProvider.LoadDirectoryGroupMembers(ctx, id); err != nil {
+		return nil, nil, fmt.Errorf("error occurred loading the members of the group from the storage backend: %w", err)
+	}
+
+	return group, members, nil
+}
+
+// scimSaveGroupMembers replaces the members of the group and returns the members as saved, which excludes the users that
+// don't exist.
+func scimSaveGroupMembers(ctx *middlewares.AutheliaCtx, id uuid.UUID, members []uuid.UUID) (saved []model.DirectoryGroupMember, err error) {
+	if err = ctx.Providers.StorageProvider.SaveDirectoryGroupMembers(ctx, id, members); err != nil {
+		return nil, fmt.Errorf("error occurred saving the members of the group to the storage backend: %w", err)
+	}
+
+	if saved, err = ctx.Providers.StorageProvider.LoadDirectoryGroupMembers(ctx, id); err != nil {
+		return nil, fmt.Errorf("error occurred loading the members of the group from the storage backend: %w", err)
+	}
+
+	return saved, nil
+}
+
+// scimValidateGroup ensures the group has a display name which isn't used by another group, and returns the ids of the
+// members.
+func scimValidateGroup(ctx *middlewares.AutheliaCtx, group *scim.Group, id uuid.UUID) (members []uuid.UUID, err error) {
+	if group.DisplayName = strings.TrimSpace(group.DisplayName); group.DisplayName == "" {
+		return nil, scim.NewError(fasthttp.StatusBadRequest, scim.ErrorTypeInvalidValue, "The attribute 'displayName' is required.")
+	}
+
+	members = make([]uuid.UUID, 0, len(group.Members))
+
+	for _, member := range group.Members {
+		var memberID uuid.UUID
+
+		if memberID, err = uuid.Parse(member.Value); err != nil {
+			return nil, scim.NewError(fasthttp.StatusBadRequest, scim.ErrorTypeInvalidValue, fmt.Sprintf("The member '%s' is not a valid user id.", member.Value))
+		}
+
+		members = append(members, memberID)
+	}
+
+	var groups []model.DirectoryGroup
+
+	if groups, err = ctx.Providers.StorageProvider.LoadDirectoryGroups(ctx, model.DirectoryGroupFilter{DisplayName: group.DisplayName}, 1, 0); err != nil {
+		return nil, fmt.Errorf("error occurred checking the display name is unique: %w", err)
+	}
+
+	if len(groups) != 0 && groups[0].PublicID != id {
+		return nil, scimConflict(fmt.Sprintf("The displayName '%s' is already in use.", group.DisplayName))
+	}
+
+	return members, nil
+}
+
+// scimGroupFromModel returns the SCIM 2.0 group of the directory group.
+func scimGroupFromModel(ctx *middlewares.AutheliaCtx, m *model.DirectoryGroup, members []model.DirectoryGroupMember) *scim.Group {
+	group := &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          m.PublicID.String(),
+		ExternalID:  m.ExternalID.String,
+		DisplayName: m.DisplayName,
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeGroup,
+			Created:      &m.CreatedAt,
+			LastModified: &m.UpdatedAt,
+			Location:     scimLocation(ctx, "Groups", m.PublicID.String()),
+		},
+	}
+
+	for _, member := range members {
+		group.Members = append(group.Members, scim.Member{
+			Value:   member.PublicID.String(),
+			Ref:     scimLocation(ctx, "Users", member.PublicID.String()),
+			Display: member.Username,
+		})
+	}
+
+	return group
+}
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/scim"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestSCIMServiceProviderConfigGET(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	SCIMServiceProviderConfigGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, scim.ContentType, string(mock.Ctx.Response.Header.ContentType()))
+
+	config := scim.ServiceProviderConfig{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &config))
+
+	assert.True(t, config.Patch.Supported)
+	assert.True(t, config.Filter.Supported)
+	assert.False(t, config.Bulk.Supported)
+	assert.Equal(t, "http://example.com/scim/v2/ServiceProviderConfig", config.Meta.Location)
+}
+
+func TestSCIMResourceTypesGET(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	SCIMResourceTypesGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Contains(t, string(mock.Ctx.Response.Body()), `"totalResults":2`)
+	assert.Contains(t, string(mock.Ctx.Response.Body()), `"endpoint":"/Users"`)
+	assert.Contains(t, string(mock.Ctx.Response.Body()), `"endpoint":"/Groups"`)
+}
+
+func TestSCIMUsersGET(t *testing.T) {
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+	created := time.Unix(1700000000, 0).UTC()
+
+	user := model.DirectoryUser{
+		PublicID:    publicID,
+		ExternalID:  sql.NullString{String: "00u1", Valid: true},
+		Username:    testUsername,
+		DisplayName: "John Smith",
+		GivenName:   "John",
+		FamilyName:  "Smith",
+		Emails:      model.StringSlicePipeDelimited{"john@example.com", "jsmith@example.com"},
+		Active:      true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	testCases := []struct {
+		name           string
+		query          string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldListUsers",
+			"",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().CountDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{}).Return(1, nil),
+					mock.StorageMock.EXPECT().LoadDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{}, 100, 0).Return([]model.DirectoryUser{user}, nil),
+				)
+			},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"totalResults":1,"startIndex":1,"itemsPerPage":1,"Resources":[{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"id":"0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90","externalId":"00u1","userName":"john","name":{"givenName":"John","familyName":"Smith"},"displayName":"John Smith","emails":[{"value":"john@example.com","type":"work","primary":true},{"value":"jsmith@example.com","type":"other"}],"active":true,"meta":{"resourceType":"User","created":"2023-11-14T22:13:20Z","lastModified":"2023-11-14T22:13:20Z","location":"http://example.com/scim/v2/Users/0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90"}}]}`,
+			fasthttp.StatusOK,
+		},
+		{
+			"ShouldListUsersWithFilterAndPage",
+			`filter=userName+eq+"john"&startIndex=3&count=2`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().CountDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{Username: testUsername}).Return(1, nil),
+					mock.StorageMock.EXPECT().LoadDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{Username: testUsername}, 2, 2).Return(nil, nil),
+				)
+			},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:ListResponse"],"totalResults":1,"startIndex":3,"itemsPerPage":0,"Resources":[]}`,
+			fasthttp.StatusOK,
+		},
+		{
+			"ShouldErrInvalidFilter",
+			`filter=userName+sw+"j"`,
+			nil,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidFilter","detail":"The filter 'userName sw \"j\"' uses the operator 'sw' but only the 'eq' operator is supported."}`,
+			fasthttp.StatusBadRequest,
+		},
+		{
+			"ShouldErrStorage",
+			"",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().CountDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{}).Return(0, fmt.Errorf("bad connection"))
+			},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"500","detail":"An unexpected error occurred."}`,
+			fasthttp.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Request.SetRequestURI("/scim/v2/Users?" + tc.query)
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			SCIMUsersGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.JSONEq(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestSCIMUsersPOST(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldProvisionUser",
+			`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"john","name":{"givenName":"John","familyName":"Smith"},"emails":[{"value":"jsmith@example.com"},{"value":"john@example.com","primary":true}],"active":true}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{Username: testUsername}, 1, 0).Return(nil, nil),
+					mock.StorageMock.EXPECT().SaveDirectoryUser(mock.Ctx, gomock.Any()).DoAndReturn(func(_ any, user model.DirectoryUser) error {
+						assert.Equal(t, testUsername, user.Username)
+						assert.Equal(t, model.StringSlicePipeDelimited{"john@example.com", "jsmith@example.com"}, user.Emails)
+						assert.False(t, user.ExternalID.Valid)
+						assert.True(t, user.Active)
+
+						return nil
+					}),
+				)
+			},
+			fasthttp.StatusCreated,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				user := scim.User{}
+
+				require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &user))
+
+				assert.Equal(t, testUsername, user.UserName)
+				assert.Equal(t, "http://example.com/scim/v2/Users/"+user.ID, string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+			},
+		},
+		{
+			"ShouldErrConflict",
+			`{"userName":"john"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{Username: testUsername}, 1, 0).Return([]model.DirectoryUser{{PublicID: uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90"), Username: testUsername}}, nil)
+			},
+			fasthttp.StatusConflict,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				assert.JSONEq(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"409","scimType":"uniqueness","detail":"The userName 'john' is already in use."}`, string(mock.Ctx.Response.Body()))
+			},
+		},
+		{
+			"ShouldErrMissingUserName",
+			`{"displayName":"John Smith"}`,
+			nil,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				assert.JSONEq(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidValue","detail":"The attribute 'userName' is required."}`, string(mock.Ctx.Response.Body()))
+			},
+		},
+		{
+			"ShouldErrInvalidBody",
+			`{`,
+			nil,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				assert.JSONEq(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidSyntax","detail":"The request body is not valid."}`, string(mock.Ctx.Response.Body()))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			SCIMUsersPOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestSCIMUserPATCH(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+	created := time.Unix(1700000000, 0).UTC()
+
+	user := &model.DirectoryUser{
+		PublicID:  publicID,
+		Username:  testUsername,
+		Emails:    model.StringSlicePipeDelimited{"john@example.com"},
+		Active:    true,
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+
+	mock.Ctx.SetUserValue("id", publicID.String())
+	mock.Ctx.Request.SetBodyString(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","value":{"active":false}},{"op":"replace","path":"emails[type eq \"work\"].value","value":"jsmith@example.com"}]}`)
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadDirectoryUser(mock.Ctx, publicID).Return(user, nil),
+		mock.StorageMock.EXPECT().LoadDirectoryUserGroups(mock.Ctx, testUsername).Return([]model.DirectoryGroup{{PublicID: uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21"), DisplayName: "admins"}}, nil),
+		mock.StorageMock.EXPECT().LoadDirectoryUsers(mock.Ctx, model.DirectoryUserFilter{Username: testUsername}, 1, 0).Return([]model.DirectoryUser{*user}, nil),
+		mock.StorageMock.EXPECT().UpdateDirectoryUser(mock.Ctx, gomock.Any()).DoAndReturn(func(_ any, updated model.DirectoryUser) error {
+			assert.False(t, updated.Active)
+			assert.Equal(t, model.StringSlicePipeDelimited{"jsmith@example.com"}, updated.Emails)
+
+			return nil
+		}),
+	)
+
+	SCIMUserPATCH(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	response := scim.User{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &response))
+
+	assert.False(t, response.IsActive())
+	require.Len(t, response.Groups, 1)
+	assert.Equal(t, "admins", response.Groups[0].Display)
+}
+
+func TestSCIMUserGET(t *testing.T) {
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+
+	testCases := []struct {
+		name           string
+		id             string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldErrInvalidID",
+			"abc",
+			nil,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"404","detail":"The resource 'abc' was not found."}`,
+			fasthttp.StatusNotFound,
+		},
+		{
+			"ShouldErrNotFound",
+			publicID.String(),
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadDirectoryUser(mock.Ctx, publicID).Return(nil, storage.ErrNoDirectoryUser)
+			},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"404","detail":"The user '0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90' was not found."}`,
+			fasthttp.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.SetUserValue("id", tc.id)
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			SCIMUserGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.JSONEq(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestSCIMUserDELETE(t *testing.T) {
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	mock.Ctx.SetUserValue("id", publicID.String())
+
+	mock.StorageMock.EXPECT().DeleteDirectoryUser(mock.Ctx, publicID).Return(nil)
+
+	SCIMUserDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusNoContent, mock.Ctx.Response.StatusCode())
+	assert.Len(t, mock.Ctx.Response.Body(), 0)
+
+	mock = mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	mock.Ctx.SetUserValue("id", publicID.String())
+
+	mock.StorageMock.EXPECT().DeleteDirectoryUser(mock.Ctx, publicID).Return(storage.ErrNoDirectoryUser)
+
+	SCIMUserDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusNotFound, mock.Ctx.Response.StatusCode())
+}
+
+func TestSCIMGroupPATCH(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	defer mock.Close()
+
+	groupID := uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21")
+	userA := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+	userB := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+	created := time.Unix(1700000000, 0).UTC()
+
+	group := &model.DirectoryGroup{PublicID: groupID, DisplayName: "admins", CreatedAt: created, UpdatedAt: created}
+
+	mock.Ctx.SetUserValue("id", groupID.String())
+	mock.Ctx.Request.SetBodyString(fmt.Sprintf(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"add","path":"members","value":[{"value":"%s"}]},{"op":"remove","path":"members[value eq \"%s\"]"}]}`, userB, userA))
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadDirectoryGroup(mock.Ctx, groupID).Return(group, nil),
+		mock.StorageMock.EXPECT().LoadDirectoryGroupMembers(mock.Ctx, groupID).Return([]model.DirectoryGroupMember{{PublicID: userA, Username: testUsername}}, nil),
+		mock.StorageMock.EXPECT().LoadDirectoryGroups(mock.Ctx, model.DirectoryGroupFilter{DisplayName: "admins"}, 1, 0).Return([]model.DirectoryGroup{*group}, nil),
+		mock.StorageMock.EXPECT().UpdateDirectoryGroup(mock.Ctx, gomock.Any()).Return(nil),
+		mock.StorageMock.EXPECT().SaveDirectoryGroupMembers(mock.Ctx, groupID, []uuid.UUID{userB}).Return(nil),
+		mock.StorageMock.EXPECT().LoadDirectoryGroupMembers(mock.Ctx, groupID).Return([]model.DirectoryGroupMember{{PublicID: userB, Username: "harry"}}, nil),
+	)
+
+	SCIMGroupPATCH(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	response := scim.Group{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &response))
+
+	require.Len(t, response.Members, 1)
+	assert.Equal(t, scim.Member{Value: userB.String(), Ref: "http://example.com/scim/v2/Users/" + userB.String(), Display: "harry"}, response.Members[0])
+}
+
+func TestSCIMGroupsPOST(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+	}{
+		{
+			"ShouldErrInvalidMember",
+			`{"displayName":"admins","members":[{"value":"john"}]}`,
+			nil,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidValue","detail":"The member 'john' is not a valid user id."}`,
+			fasthttp.StatusBadRequest,
+		},
+		{
+			"ShouldErrConflict",
+			`{"displayName":"admins"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadDirectoryGroups(mock.Ctx, model.DirectoryGroupFilter{DisplayName: "admins"}, 1, 0).Return([]model.DirectoryGroup{{PublicID: uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21"), DisplayName: "admins"}}, nil)
+			},
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"409","scimType":"uniqueness","detail":"The displayName 'admins' is already in use."}`,
+			fasthttp.StatusConflict,
+		},
+		{
+			"ShouldErrMissingDisplayName",
+			`{"members":[]}`,
+			nil,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"400","scimType":"invalidValue","detail":"The attribute 'displayName' is required."}`,
+			fasthttp.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			SCIMGroupsPOST(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.JSONEq(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
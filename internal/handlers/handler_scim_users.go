@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/scim"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// SCIMUsersGET lists the SCIM 2.0 users which match the filter of the request.
+func SCIMUsersGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		filter *scim.Filter
+		users  []model.DirectoryUser
+		total  int
+		err    error
+	)
+
+	if filter, err = scim.ParseFilter(string(ctx.QueryArgs().Peek("filter")), "userName", "externalId"); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred listing the SCIM users: error occurred parsing the filter")
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	start, limit := scimParseListParameters(ctx)
+
+	resources := []any{}
+
+	if filter != nil && filter.Value == "" {
+		scimReply(ctx, fasthttp.StatusOK, scim.NewListResponse(0, start, resources))
+
+		return
+	}
+
+	f := model.DirectoryUserFilter{}
+
+	if filter != nil {
+		switch filter.Attribute {
+		case "userName":
+			f.Username = filter.Value
+		default:
+			f.ExternalID = filter.Value
+		}
+	}
+
+	if total, err = ctx.Providers.StorageProvider.CountDirectoryUsers(ctx, f); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred listing the SCIM users: error occurred counting the users in the storage backend")
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if limit > 0 {
+		if users, err = ctx.Providers.StorageProvider.LoadDirectoryUsers(ctx, f, limit, start-1); err != nil {
+			ctx.Logger.WithError(err).Error("Error occurred listing the SCIM users: error occurred loading the users from the storage backend")
+
+			scimReplyError(ctx, err)
+
+			return
+		}
+	}
+
+	for i := range users {
+		resources = append(resources, scimUserFromModel(ctx, &users[i], nil))
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scim.NewListResponse(total, start, resources))
+}
+
+// SCIMUserGET returns a SCIM 2.0 user.
+func SCIMUserGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		id     uuid.UUID
+		user   *model.DirectoryUser
+		groups []model.DirectoryGroup
+		err    error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if user, groups, err = scimLoadUser(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the SCIM user with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scimUserFromModel(ctx, user, groups))
+}
+
+// SCIMUsersPOST provisions a new SCIM 2.0 user.
+func SCIMUsersPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		request scim.User
+		id      uuid.UUID
+		err     error
+	)
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning a SCIM user: %s", errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if id, err = uuid.NewRandom(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM user '%s': error occurred generating the id", request.UserName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	user := model.DirectoryUser{
+		PublicID:  id,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err = scimValidateUser(ctx, &request, user.PublicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM user '%s'", request.UserName)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimUserToModel(&request, &user)
+
+	if err = ctx.Providers.StorageProvider.SaveDirectoryUser(ctx, user); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred provisioning the SCIM user '%s': error occurred saving the user to the storage backend", user.Username)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Provisioned the SCIM user '%s' with id '%s'", user.Username, user.PublicID)
+
+	response := scimUserFromModel(ctx, &user, nil)
+
+	ctx.Response.Header.Set(fasthttp.HeaderLocation, response.Meta.Location)
+
+	scimReply(ctx, fasthttp.StatusCreated, response)
+}
+
+// SCIMUserPUT replaces the attributes of a SCIM 2.0 user.
+func SCIMUserPUT(ctx *middlewares.AutheliaCtx) {
+	var (
+		id      uuid.UUID
+		user    *model.DirectoryUser
+		groups  []model.DirectoryGroup
+		request scim.User
+		err     error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if user, groups, err = scimLoadUser(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred replacing the SCIM user with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred replacing the SCIM user with id '%s': %s", id, errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimUpdateUser(ctx, user, groups, &request)
+}
+
+// SCIMUserPATCH modifies the attributes of a SCIM 2.0 user with the operations of the patch request.
+func SCIMUserPATCH(ctx *middlewares.AutheliaCtx) {
+	var (
+		id      uuid.UUID
+		user    *model.DirectoryUser
+		groups  []model.DirectoryGroup
+		request scim.PatchRequest
+		err     error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if user, groups, err = scimLoadUser(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM user with id '%s'", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = scimDecodeBody(ctx, &request); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM user with id '%s': %s", id, errStrReqBodyParse)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	patched := scimUserFromModel(ctx, user, nil)
+
+	if err = patched.ApplyPatch(request.Operations); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred modifying the SCIM user with id '%s': error occurred applying the patch operations", id)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	scimUpdateUser(ctx, user, groups, patched)
+}
+
+// SCIMUserDELETE deprovisions a SCIM 2.0 user.
+func SCIMUserDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		id  uuid.UUID
+		err error
+	)
+
+	if id, err = scimParseID(ctx); err != nil {
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.DeleteDirectoryUser(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred deprovisioning the SCIM user with id '%s': error occurred deleting the user from the storage backend", id)
+
+		if errors.Is(err, storage.ErrNoDirectoryUser) {
+			err = scim.NewError(fasthttp.StatusNotFound, "", fmt.Sprintf("The user '%s' was not found.", id))
+		}
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Deprovisioned the SCIM user with id '%s'", id)
+
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// scimUpdateUser replaces the attributes of the user with the attributes of the request and writes the response.
+func scimUpdateUser(ctx *middlewares.AutheliaCtx, user *model.DirectoryUser, groups []model.DirectoryGroup, request *scim.User) {
+	var err error
+
+	if err = scimValidateUser(ctx, request, user.PublicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred updating the SCIM user with id '%s'", user.PublicID)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	username := user.Username
+
+	scimUserToModel(request, user)
+
+	user.UpdatedAt = ctx.Clock.Now()
+
+	if err = ctx.Providers.StorageProvider.UpdateDirectoryUser(ctx, *user); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred updating the SCIM user with id '%s': error occurred saving the user to the storage backend", user.PublicID)
+
+		scimReplyError(ctx, err)
+
+		return
+	}
+
+	if username != user.Username {
+		ctx.Logger.Debugf("Renamed the SCIM user with id '%s' from '%s' to '%s'", user.PublicID, username, user.Username)
+	}
+
+	scimReply(ctx, fasthttp.StatusOK, scimUserFromModel(ctx, user, groups))
+}
+
+// scimLoadUser loads the user and the groups it's a member of from the storage backend.
+func scimLoadUser(ctx *middlewares.AutheliaCtx, id uuid.UUID) (user *model.DirectoryUser, groups []model.DirectoryGroup, err error) {
+	if user, err = ctx.Providers.StorageProvider.LoadDirectoryUser(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrNoDirectoryUser) {
+			return nil, nil, scim.NewError(fasthttp.StatusNotFound, "", fmt.Sprintf("The user '%s' was not found.", id))
+		}
+
+		return nil, nil, fmt.Errorf("error occurred loading the user from the storage backend: %w", err)
+	}
+
+	if groups, err = ctx.Providers.StorageProvider.LoadDirectoryUserGroups(ctx, user.Username); err != nil {
+		return nil, nil, fmt.Errorf("error occurred loading the groups of the user from the storage backend: %w", err)
+	}
+
+	return user, groups, nil
+}
+
+// scimValidateUser ensures the user has a username which isn't used by another user.
+func scimValidateUser(ctx *middlewares.AutheliaCtx, user *scim.User, id uuid.UUID) (err error) {
+	if user.UserName = strings.TrimSpace(user.UserName); user.UserName == "" {
+		return scim.NewError(fasthttp.StatusBadRequest, scim.ErrorTypeInvalidValue, "The attribute 'userName' is required.")
+	}
+
+	var users []model.DirectoryUser
+
+	if users, err = ctx.Providers.StorageProvider.LoadDirectoryUsers(ctx, model.DirectoryUserFilter{Username: user.UserName}, 1, 0); err != nil {
+		return fmt.Errorf("error occurred checking the username is unique: %w", err)
+	}
+
+	if len(users) != 0 && users[0].PublicID != id {
+		return scimConflict(fmt.Sprintf("The userName '%s' is already in use.", user.UserName))
+	}
+
+	return nil
+}
+
+// scimUserToModel sets the attributes of the directory user from the SCIM 2.0 user. The directory user only stores the
+// addresses of the emails, with the primary address first.
+func scimUserToModel(user *scim.User, m *model.DirectoryUser) {
+	m.ExternalID = sql.NullString{String: user.ExternalID, Valid: user.ExternalID != ""}
+	m.Username = user.UserName
+	m.DisplayName = user.DisplayName
+	m.GivenName, m.FamilyName = "", ""
+	m.Active = user.IsActive()
+
+	if user.Name != nil {
+		m.GivenName, m.FamilyName = user.Name.GivenName, user.Name.FamilyName
+	}
+
+	m.Emails = make(model.StringSlicePipeDelimited, 0, len(user.Emails))
+
+	for _, email := range user.Emails {
+		switch {
+		case email.Value == "":
+			continue
+		case email.Primary:
+			m.Emails = append(model.StringSlicePipeDelimited{email.Value}, m.Emails...)
+		default:
+			m.Emails = append(m.Emails, email.Value)
+		}
+	}
+}
+
+// scimUserFromModel returns the SCIM 2.0 user of the directory user. The first email is the primary email which has
+// the work type, as this is the email most identity providers manage.
+func scimUserFromModel(ctx *middlewares.AutheliaCtx, m *model.DirectoryUser, groups []model.DirectoryGroup) *scim.User {
+	active := m.Active
+
+	user := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          m.PublicID.String(),
+		ExternalID:  m.ExternalID.String,
+		UserName:    m.Username,
+		DisplayName: m.DisplayName,
+		Active:      &active,
+		Meta: &scim.Meta{
+			ResourceType: scim.ResourceTypeUser,
+			Created:      &m.CreatedAt,
+			LastModified: &m.UpdatedAt,
+			Location:     scimLocation(ctx, "Users", m.PublicID.String()),
+		},
+	}
+
+	if m.GivenName != "" || m.FamilyName != "" {
+		user.Name = &scim.Name{GivenName: m.GivenName, FamilyName: m.FamilyName}
+	}
+
+	for i, email := range m.Emails {
+		if i == 0 {
+			user.Emails = append(user.Emails, scim.Email{Value: email, Type: scimEmailTypeWork, Primary: true})
+		} else {
+			user.Emails = append(user.Emails, scim.Email{Value: email, Type: scimEmailTypeOther})
+		}
+	}
+
+	for _, group := range groups {
+		user.Groups = append(user.Groups, scim.Member{
+			Value:   group.PublicID.String(),
+			Ref:     scimLocation(ctx, "Groups", group.PublicID.String()),
+			Display: group.DisplayName,
+		})
+	}
+
+	return user
+}
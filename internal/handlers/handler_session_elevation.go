@@ -1,15 +1,14 @@
 package handlers
 
 import (
-	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
-	"path"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -140,11 +139,22 @@ func UserSessionElevationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	identity := userSession.Identity()
+
+	if !ctx.Configuration.IdentityValidation.EmailDomains.IsPermitted(identity.Email) {
+		ctx.Logger.Errorf("Error occurred creating user session elevation One-Time Code challenge for user '%s': the domain of the email address '%s' is not permitted to receive identity verification notifications", userSession.Username, identity.Email)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
 	var (
 		otp *model.OneTimeCode
 	)
 
-	if otp, err = model.NewOneTimeCode(ctx, userSession.Username, ctx.Configuration.IdentityValidation.ElevatedSession.Characters, ctx.Configuration.IdentityValidation.ElevatedSession.CodeLifespan); err != nil {
+	if otp, err = model.NewOneTimeCode(ctx, userSession.Username, newOneTimeCodeOptions(model.OTCIntentUserSessionElevation, ctx.Configuration.IdentityValidation.ElevatedSession)); err != nil {
 		ctx.Logger.WithError(err).Errorf("Error occurred creating user session elevation One-Time Code challenge for user '%s': error occurred generating the challenge", userSession.Username)
 
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
@@ -164,22 +174,11 @@ func UserSessionElevationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	deleteID := base64.RawURLEncoding.EncodeToString(otp.PublicID[:])
-
-	linkURL := ctx.RootURL()
-
-	query := linkURL.Query()
-
-	query.Set("id", deleteID)
-
-	linkURL.Path = path.Join(linkURL.Path, "/revoke/one-time-code")
-	linkURL.RawQuery = query.Encode()
-
-	identity := userSession.Identity()
+	deleteID, linkURL := newOneTimeCodeRevocation(ctx, otp)
 
 	data := templates.EmailIdentityVerificationOTCValues{
 		Title:              "Confirm your identity",
-		RevocationLinkURL:  linkURL.String(),
+		RevocationLinkURL:  linkURL,
 		RevocationLinkText: "Revoke",
 		DisplayName:        identity.DisplayName,
 		RemoteIP:           ctx.RemoteIP().String(),
@@ -189,7 +188,7 @@ func UserSessionElevationPOST(ctx *middlewares.AutheliaCtx) {
 	ctx.Logger.WithFields(map[string]any{"signature": signature, "id": otp.PublicID.String(), "username": identity.Username}).
 		Debug("Sending an email to user to confirm identity for session elevation")
 
-	if err = ctx.Providers.Notifier.Send(ctx, identity.Address(), data.Title, ctx.Providers.Templates.GetIdentityVerificationOTCEmailTemplate(), data); err != nil {
+	if err = deliverOneTimeCode(ctx, ctx.Configuration.IdentityValidation.ElevatedSession.Delivery, identity, data); err != nil {
 		ctx.Logger.WithError(err).Errorf("Error occurred creating user session elevation One-Time Code challenge for user '%s': error occurred sending the user the notification", userSession.Username)
 
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
@@ -249,8 +248,8 @@ func UserSessionElevationPUT(ctx *middlewares.AutheliaCtx) {
 
 	bodyJSON.OneTimeCode = strings.TrimSpace(strings.ToUpper(bodyJSON.OneTimeCode))
 
-	if n := len(bodyJSON.OneTimeCode); n > 20 {
-		ctx.Logger.Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s': expected maximum code length is %d but the user provided code was %d characters in length", userSession.Username, 20, n)
+	if n := len(bodyJSON.OneTimeCode); n > model.OneTimeCodeMaximumLength {
+		ctx.Logger.Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s': expected maximum code length is %d but the user provided code was %d characters in length", userSession.Username, model.OneTimeCodeMaximumLength, n)
 
 		ctx.SetStatusCode(fasthttp.StatusBadRequest)
 		ctx.SetJSONError(messageOperationFailed)
@@ -270,32 +269,7 @@ func UserSessionElevationPUT(ctx *middlewares.AutheliaCtx) {
 		ctx.Logger.WithError(fmt.Errorf("the code didn't match any recorded code challenges")).
 			Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s': error occurred retrieving the code challenge from the storage backend", userSession.Username)
 
-		ctx.SetStatusCode(fasthttp.StatusForbidden)
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
-
-	if code.ExpiresAt.Before(ctx.Clock.Now()) {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has expired")).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
-
-		ctx.SetStatusCode(fasthttp.StatusForbidden)
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
-
-	if code.RevokedAt.Valid {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has been revoked")).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
-
-		ctx.SetStatusCode(fasthttp.StatusForbidden)
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
-
-	if code.ConsumedAt.Valid {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has already been consumed")).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
+		ctx.AuditEvent(audit.EventTypeSessionElevation, audit.ResultFailure, userSession.Username, nil)
 
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
 		ctx.SetJSONError(messageOperationFailed)
@@ -303,17 +277,10 @@ func UserSessionElevationPUT(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if code.Intent != model.OTCIntentUserSessionElevation {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has the '%s' intent but the '%s' intent is required", code.Intent, model.OTCIntentUserSessionElevation)).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
-
-		ctx.SetStatusCode(fasthttp.StatusForbidden)
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
+	if err = code.Validate(ctx, model.OTCIntentUserSessionElevation, []byte(bodyJSON.OneTimeCode)); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
 
-	if subtle.ConstantTimeCompare(code.Code, []byte(bodyJSON.OneTimeCode)) != 1 {
-		ctx.Logger.WithError(fmt.Errorf("the code does not match the code stored in the challenge")).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s'", userSession.Username)
+		ctx.AuditEvent(audit.EventTypeSessionElevation, audit.ResultFailure, userSession.Username, nil)
 
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
 		ctx.SetJSONError(messageOperationFailed)
@@ -338,6 +305,8 @@ func UserSessionElevationPUT(ctx *middlewares.AutheliaCtx) {
 		Expires:  ctx.Clock.Now().Add(ctx.Configuration.IdentityValidation.ElevatedSession.ElevationLifespan),
 	}
 
+	ctx.AuditEvent(audit.EventTypeSessionElevation, audit.ResultSuccess, userSession.Username, nil)
+
 	if err = ctx.SaveSession(userSession); err != nil {
 		ctx.Logger.WithError(err).Errorf("Error occurred validating user session elevation One-Time Code challenge for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
 
@@ -389,24 +358,8 @@ func UserSessionElevateDELETE(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if code.RevokedAt.Valid {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has already been revoked")).Errorf("Error occurred revoking user session elevation One-Time Code challenge")
-
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
-
-	if code.ConsumedAt.Valid {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has already been consumed")).Errorf("Error occurred revoking user session elevation One-Time Code challenge")
-
-		ctx.SetJSONError(messageOperationFailed)
-
-		return
-	}
-
-	if code.Intent != model.OTCIntentUserSessionElevation {
-		ctx.Logger.WithError(fmt.Errorf("the code challenge has the '%s' intent but the '%s' intent is required", code.Intent, model.OTCIntentUserSessionElevation)).Errorf("Error occurred revoking user session elevation One-Time Code challenge")
+	if err = code.ValidateRevocation(model.OTCIntentUserSessionElevation); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking user session elevation One-Time Code challenge")
 
 		ctx.SetJSONError(messageOperationFailed)
 
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/random"
@@ -521,6 +522,29 @@ func TestUserSessionElevationPOST(t *testing.T) {
 				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred creating user session elevation One-Time Code challenge for user 'john': error occurred generating the challenge", "failed to generate public id: random unavailable")
 			},
 		},
+		{
+			"ShouldHandleEmailDomainNotPermitted",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+
+				us.Username = testUsername
+				us.DisplayName = testDisplayName
+				us.Emails = []string{"john@example.com"}
+
+				us.AuthenticationLevel = authentication.OneFactor
+
+				require.NoError(t, mock.Ctx.SaveSession(us))
+
+				mock.Ctx.Configuration.IdentityValidation.EmailDomains.Deny = []string{"example.com"}
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred creating user session elevation One-Time Code challenge for user 'john': the domain of the email address 'john@example.com' is not permitted to receive identity verification notifications", "")
+			},
+		},
 		{
 			"ShouldHandleAnonymous",
 			nil,
@@ -550,6 +574,7 @@ func TestUserSessionElevationPOST(t *testing.T) {
 			defer mock.Close()
 
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Characters = 10
+			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Delivery = schema.OneTimeCodeDeliveryEmail
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.ElevationLifespan = time.Minute
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.CodeLifespan = time.Minute
 
@@ -1044,6 +1069,7 @@ func TestUserSessionElevationPUT(t *testing.T) {
 			defer mock.Close()
 
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Characters = 10
+			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Delivery = schema.OneTimeCodeDeliveryEmail
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.ElevationLifespan = time.Minute
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.CodeLifespan = time.Minute
 
@@ -1373,6 +1399,7 @@ func TestUserSessionElevationDELETE(t *testing.T) {
 			defer mock.Close()
 
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Characters = 10
+			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.Delivery = schema.OneTimeCodeDeliveryEmail
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.ElevationLifespan = time.Minute
 			mock.Ctx.Configuration.IdentityValidation.ElevatedSession.CodeLifespan = time.Minute
 
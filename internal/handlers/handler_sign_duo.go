@@ -273,9 +273,14 @@ func HandleAllow(ctx *middlewares.AutheliaCtx, userSession *session.UserSession,
 		return
 	}
 
-	if bodyJSON.Workflow == workflowOpenIDConnect {
+	indexUserSession(ctx, *userSession)
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
 		handleOIDCWorkflowResponse(ctx, userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
-	} else {
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, userSession, bodyJSON.TargetURL)
+	default:
 		Handle2FAResponse(ctx, bodyJSON.TargetURL)
 	}
 }
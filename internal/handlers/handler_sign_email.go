@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// EmailPUT sends a One-Time Code to the email address of the user and records the pending challenge in the session.
+//
+//nolint:gocyclo
+func EmailPUT(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodySignEmailStartRequest{}
+
+	var (
+		userSession session.UserSession
+		otp         *model.OneTimeCode
+		count       int
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred sending an Email One-Time Code")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	identity := userSession.Identity()
+
+	if !ctx.Configuration.IdentityValidation.EmailDomains.IsPermitted(identity.Email) {
+		ctx.Logger.Errorf("Error occurred sending an Email One-Time Code for user '%s': the domain of the email address '%s' is not permitted to receive identity verification notifications", userSession.Username, identity.Email)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	config := ctx.Configuration.Email
+
+	if count, err = ctx.Providers.StorageProvider.CountOneTimeCodes(ctx, userSession.Username, model.OTCIntentEmailSignIn, ctx.Clock.Now().Add(-config.Cooldown)); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': error occurred counting the sent codes in the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if count != 0 {
+		ctx.Logger.Errorf("Error occurred sending an Email One-Time Code for user '%s': the user has been sent a code within the last %s", userSession.Username, config.Cooldown)
+
+		ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	opts := model.OneTimeCodeOptions{
+		Intent:       model.OTCIntentEmailSignIn,
+		Length:       config.Characters,
+		CharacterSet: oneTimeCodeCharacterSets[config.CharacterSet],
+		Lifespan:     config.CodeLifespan,
+	}
+
+	if otp, err = model.NewOneTimeCode(ctx, userSession.Username, opts); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': error occurred generating the code", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	var signature string
+
+	if signature, err = ctx.Providers.StorageProvider.SaveOneTimeCode(ctx, *otp); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': error occurred saving the code to the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	data := templates.EmailSecondFactorOTCValues{
+		Title:       "Sign in",
+		DisplayName: identity.DisplayName,
+		RemoteIP:    ctx.RemoteIP().String(),
+		OneTimeCode: string(otp.Code),
+	}
+
+	if config.EnableMagicLink {
+		data.LinkURL, data.LinkText = newEmailMagicLink(ctx, otp), "Sign in"
+	}
+
+	ctx.Logger.WithFields(map[string]any{"signature": signature, "id": otp.PublicID.String(), "username": identity.Username}).
+		Debug("Sending an email to user with a One-Time Code to sign in")
+
+	if err = ctx.Providers.Notifier.Send(ctx, identity.Address(), data.Title, ctx.Providers.Templates.GetSecondFactorOTCEmailTemplate(), data); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': error occurred sending the user the notification", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	userSession.Email = &session.Email{
+		Signature:  signature,
+		TargetURL:  bodyJSON.TargetURL,
+		Workflow:   bodyJSON.Workflow,
+		WorkflowID: bodyJSON.WorkflowID,
+		Expires:    otp.ExpiresAt,
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending an Email One-Time Code for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// EmailPOST validates the One-Time Code sent to the email address of the user.
+func EmailPOST(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodySignEmailRequest{}
+
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred validating an Email authentication")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	bodyJSON.Code = strings.TrimSpace(strings.ToUpper(bodyJSON.Code))
+
+	if n := len(bodyJSON.Code); n != ctx.Configuration.Email.Characters {
+		ctx.Logger.Errorf("Error occurred validating an Email authentication for user '%s': expected code length is %d but the user provided code was %d characters in length", userSession.Username, ctx.Configuration.Email.Characters, n)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if !handleEmailOneTimeCode(ctx, &userSession, bodyJSON.Code) {
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	}
+}
+
+// EmailLinkGET validates the One-Time Code in the magic link sent to the email address of the user and redirects them
+// to the portal which continues the flow they started. The link only works in the browser which requested it as the
+// pending challenge is recorded in the session.
+func EmailLinkGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	redirect := ctx.RootURLSlash()
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email magic link: %s", errStrUserSessionData)
+
+		ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred validating an Email magic link")
+
+		ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+
+		return
+	}
+
+	code := strings.TrimSpace(strings.ToUpper(string(ctx.QueryArgs().Peek(queryArgCode))))
+
+	if n := len(code); n != ctx.Configuration.Email.Characters {
+		ctx.Logger.Errorf("Error occurred validating an Email magic link for user '%s': expected code length is %d but the link code was %d characters in length", userSession.Username, ctx.Configuration.Email.Characters, n)
+
+		ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+
+		return
+	}
+
+	pending := userSession.Email
+
+	if !handleEmailOneTimeCode(ctx, &userSession, code) {
+		ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+
+		return
+	}
+
+	query := redirect.Query()
+
+	if pending.TargetURL != "" {
+		query.Set(queryArgRD, pending.TargetURL)
+	}
+
+	if pending.Workflow != "" {
+		query.Set(queryArgWorkflow, pending.Workflow)
+	}
+
+	if pending.WorkflowID != "" {
+		query.Set(queryArgWorkflowID, pending.WorkflowID)
+	}
+
+	redirect.RawQuery = query.Encode()
+
+	ctx.Redirect(redirect.String(), fasthttp.StatusFound)
+}
+
+// handleEmailOneTimeCode validates the code against the pending challenge recorded in the session and upgrades the
+// session to 2FA. Each failed attempt is recorded in the session and the code is revoked once the configured maximum
+// number of attempts is reached. It returns false if the code could not be validated, the caller is responsible for
+// replying with an error.
+//
+//nolint:gocyclo
+func handleEmailOneTimeCode(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, raw string) (ok bool) {
+	var (
+		code *model.OneTimeCode
+		err  error
+	)
+
+	if userSession.Email == nil {
+		ctx.Logger.Errorf("Error occurred validating an Email authentication for user '%s': the user did not request a code on their current session", userSession.Username)
+
+		return false
+	}
+
+	if ctx.Clock.Now().After(userSession.Email.Expires) {
+		ctx.Logger.WithError(fmt.Errorf("the code challenge has expired")).Errorf("Error occurred validating an Email authentication for user '%s': error occurred validating the session", userSession.Username)
+
+		userSession.Email = nil
+
+		if err = ctx.SaveSession(*userSession); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+		}
+
+		return false
+	}
+
+	if code, err = ctx.Providers.StorageProvider.LoadOneTimeCodeBySignature(ctx, userSession.Email.Signature); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': error occurred retrieving the code challenge from the storage backend", userSession.Username)
+
+		return false
+	}
+
+	if code == nil {
+		err = fmt.Errorf("the code challenge recorded in the session doesn't exist")
+	} else {
+		err = code.Validate(ctx, model.OTCIntentEmailSignIn, []byte(raw))
+	}
+
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': error occurred validating the user input", userSession.Username)
+
+		handleEmailOneTimeCodeFailure(ctx, userSession, code)
+
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeEmail, nil)
+
+		return false
+	}
+
+	code.Consume(ctx)
+
+	if err = ctx.Providers.StorageProvider.ConsumeOneTimeCode(ctx, code); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': error occurred saving the consumption of the code to the storage backend", userSession.Username)
+
+		return false
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeEmail, nil); err != nil {
+		return false
+	}
+
+	if err = ctx.RegenerateSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': error regenerating the user session", userSession.Username)
+
+		return false
+	}
+
+	userSession.Email = nil
+	userSession.SetTwoFactorEmail(ctx.Clock.Now())
+
+	if err = ctx.SaveSession(*userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		return false
+	}
+
+	indexUserSession(ctx, *userSession)
+
+	return true
+}
+
+// handleEmailOneTimeCodeFailure records a failed attempt against the pending challenge and revokes the code once the
+// configured maximum number of attempts is reached.
+func handleEmailOneTimeCodeFailure(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, code *model.OneTimeCode) {
+	var err error
+
+	userSession.Email.Attempts++
+
+	if userSession.Email.Attempts >= ctx.Configuration.Email.MaximumAttempts {
+		ctx.Logger.WithFields(map[string]any{"username": userSession.Username, "attempts": userSession.Email.Attempts}).
+			Warn("The maximum number of attempts to validate the Email One-Time Code has been reached so it has been revoked")
+
+		if code != nil && code.ValidateRevocation(model.OTCIntentEmailSignIn) == nil {
+			if err = ctx.Providers.StorageProvider.RevokeOneTimeCode(ctx, code.PublicID, model.NewIP(ctx.RemoteIP())); err != nil {
+				ctx.Logger.WithError(err).Errorf("Error occurred revoking the Email One-Time Code for user '%s': error occurred saving the revocation to the storage backend", userSession.Username)
+			}
+		}
+
+		userSession.Email = nil
+	}
+
+	if err = ctx.SaveSession(*userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating an Email authentication for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+	}
+}
+
+// newEmailMagicLink returns the link which validates the one-time code when opened in the browser which requested it.
+func newEmailMagicLink(ctx *middlewares.AutheliaCtx, otp *model.OneTimeCode) (link string) {
+	linkURL := ctx.RootURL()
+
+	query := linkURL.Query()
+
+	query.Set(queryArgCode, string(otp.Code))
+
+	linkURL.Path = path.Join(linkURL.Path, "/api/secondfactor/email/link")
+	linkURL.RawQuery = query.Encode()
+
+	return linkURL.String()
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+type HandlerSignEmailSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *HandlerSignEmailSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Assert().NoError(err)
+
+	userSession.Username = testUsername
+	userSession.DisplayName = testDisplayName
+	userSession.Emails = []string{"john@example.com"}
+	userSession.AuthenticationLevel = authentication.OneFactor
+	s.Assert().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Clock.Set(time.Unix(1701295903, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Configuration.Email = schema.Email{
+		Enable:          true,
+		Characters:      8,
+		CharacterSet:    schema.OneTimeCodeCharacterSetUnambiguous,
+		CodeLifespan:    time.Minute * 10,
+		MaximumAttempts: 3,
+		Cooldown:        time.Minute,
+	}
+}
+
+func (s *HandlerSignEmailSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *HandlerSignEmailSuite) setPending(attempts int) {
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	userSession.Email = &session.Email{
+		Signature:  "signature",
+		TargetURL:  testRedirectionURLString,
+		Workflow:   workflowOpenIDConnect,
+		WorkflowID: "3ebcfbc5-b0fd-4ee0-9d3c-080ae1e7298c",
+		Attempts:   attempts,
+		Expires:    s.mock.Clock.Now().Add(time.Minute),
+	}
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *HandlerSignEmailSuite) pending() *session.Email {
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	return userSession.Email
+}
+
+func (s *HandlerSignEmailSuite) code() *model.OneTimeCode {
+	return &model.OneTimeCode{
+		ID:        1,
+		PublicID:  uuid.Must(uuid.Parse("01020304-0506-4722-8910-111213141500")),
+		Username:  testUsername,
+		Intent:    model.OTCIntentEmailSignIn,
+		ExpiresAt: s.mock.Clock.Now().Add(time.Minute),
+		Code:      []byte("ABCDEFGH"),
+	}
+}
+
+func (s *HandlerSignEmailSuite) expectSend(values templates.EmailSecondFactorOTCValues) {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			CountOneTimeCodes(s.mock.Ctx, testUsername, model.OTCIntentEmailSignIn, s.mock.Clock.Now().Add(-time.Minute)).
+			Return(0, nil),
+		s.mock.RandomMock.EXPECT().
+			Read(gomock.Any()).
+			SetArg(0, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x22, 0x09, 0x10, 0x11, 0x12, 0x13, 0x14, 0x15}).
+			Return(16, nil),
+		s.mock.RandomMock.EXPECT().
+			BytesCustomErr(8, []byte(random.CharSetUnambiguousUpper)).
+			Return([]byte("ABCDEFGH"), nil),
+		s.mock.StorageMock.EXPECT().
+			SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ any, code model.OneTimeCode) (string, error) {
+				s.Equal(model.OTCIntentEmailSignIn, code.Intent)
+				s.Equal(testUsername, code.Username)
+				s.Equal(s.mock.Clock.Now().Add(time.Minute*10), code.ExpiresAt)
+
+				return "signature", nil
+			}),
+		s.mock.NotifierMock.EXPECT().
+			Send(s.mock.Ctx, mail.Address{Name: testDisplayName, Address: "john@example.com"}, "Sign in", gomock.Any(), values).
+			Return(nil),
+	)
+}
+
+func (s *HandlerSignEmailSuite) TestShouldSendEmail() {
+	s.expectSend(templates.EmailSecondFactorOTCValues{
+		Title:       "Sign in",
+		DisplayName: testDisplayName,
+		RemoteIP:    "0.0.0.0",
+		OneTimeCode: "ABCDEFGH",
+	})
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"targetURL":"https://www.example.com"}`))
+
+	EmailPUT(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	pending := s.pending()
+	s.Require().NotNil(pending)
+	s.Equal("signature", pending.Signature)
+	s.Equal(testRedirectionURLString, pending.TargetURL)
+	s.Equal(s.mock.Clock.Now().Add(time.Minute*10), pending.Expires)
+	s.Equal(0, pending.Attempts)
+}
+
+func (s *HandlerSignEmailSuite) TestShouldSendEmailWithMagicLink() {
+	s.mock.Ctx.Configuration.Email.EnableMagicLink = true
+
+	s.expectSend(templates.EmailSecondFactorOTCValues{
+		Title:       "Sign in",
+		DisplayName: testDisplayName,
+		RemoteIP:    "0.0.0.0",
+		OneTimeCode: "ABCDEFGH",
+		LinkURL:     "http://example.com/api/secondfactor/email/link?code=ABCDEFGH",
+		LinkText:    "Sign in",
+	})
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	EmailPUT(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignEmailSuite) TestShouldNotSendEmailDuringCooldown() {
+	s.mock.StorageMock.EXPECT().
+		CountOneTimeCodes(s.mock.Ctx, testUsername, model.OTCIntentEmailSignIn, s.mock.Clock.Now().Add(-time.Minute)).
+		Return(1, nil)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	EmailPUT(s.mock.Ctx)
+
+	s.mock.AssertKO(s.T(), messageUnableToSendOneTimeCode, fasthttp.StatusTooManyRequests)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending an Email One-Time Code for user 'john': the user has been sent a code within the last 1m0s", "")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldNotSendEmailToDomainNotPermitted() {
+	s.mock.Ctx.Configuration.IdentityValidation.EmailDomains = schema.IdentityValidationEmailDomains{Allow: []string{"example.org"}}
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	EmailPUT(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageUnableToSendOneTimeCode)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending an Email One-Time Code for user 'john': the domain of the email address 'john@example.com' is not permitted to receive identity verification notifications", "")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldHandleSendError() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			CountOneTimeCodes(s.mock.Ctx, testUsername, model.OTCIntentEmailSignIn, gomock.Any()).
+			Return(0, nil),
+		s.mock.RandomMock.EXPECT().
+			Read(gomock.Any()).
+			Return(16, nil),
+		s.mock.RandomMock.EXPECT().
+			BytesCustomErr(8, gomock.Any()).
+			Return([]byte("ABCDEFGH"), nil),
+		s.mock.StorageMock.EXPECT().
+			SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return("signature", nil),
+		s.mock.NotifierMock.EXPECT().
+			Send(s.mock.Ctx, gomock.Any(), "Sign in", gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("rejected")),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	EmailPUT(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageUnableToSendOneTimeCode)
+	s.Nil(s.pending())
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending an Email One-Time Code for user 'john': error occurred sending the user the notification", "rejected")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldValidateCode() {
+	s.setPending(0)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCodeBySignature(s.mock.Ctx, "signature").
+			Return(s.code(), nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: true,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeEmail,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Configuration.Session.Cookies[0].DefaultRedirectionURL = testRedirectionURL
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":" abcdefgh "}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), redirectResponse{
+		Redirect: testRedirectionURLString,
+	})
+
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	s.Nil(userSession.Email)
+	s.Equal(authentication.TwoFactor, userSession.AuthenticationLevel)
+	s.True(userSession.AuthenticationMethodRefs.Email)
+}
+
+func (s *HandlerSignEmailSuite) TestShouldFailWithoutPendingChallenge() {
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"ABCDEFGH"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating an Email authentication for user 'john': the user did not request a code on their current session", "")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldFailWhenPendingChallengeExpired() {
+	s.setPending(0)
+	s.mock.Clock.Set(s.mock.Clock.Now().Add(time.Minute * 2))
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"ABCDEFGH"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+	s.Nil(s.pending())
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating an Email authentication for user 'john': error occurred validating the session", "the code challenge has expired")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldRecordFailedAttempt() {
+	s.setPending(0)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCodeBySignature(s.mock.Ctx, "signature").
+			Return(s.code(), nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeEmail,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"HGFEDCBA"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	pending := s.pending()
+	s.Require().NotNil(pending)
+	s.Equal(1, pending.Attempts)
+
+	AssertLogEntryMessageAndError(s.T(), MustGetLogLastSeq(s.T(), s.mock.Hook, 1), "Error occurred validating an Email authentication for user 'john': error occurred validating the user input", "the code does not match the code stored in the challenge")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldRevokeCodeAfterMaximumAttempts() {
+	s.setPending(2)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCodeBySignature(s.mock.Ctx, "signature").
+			Return(s.code(), nil),
+		s.mock.StorageMock.EXPECT().
+			RevokeOneTimeCode(s.mock.Ctx, uuid.Must(uuid.Parse("01020304-0506-4722-8910-111213141500")), model.NewIP(net.ParseIP("0.0.0.0"))).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"HGFEDCBA"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+	s.Nil(s.pending())
+}
+
+func (s *HandlerSignEmailSuite) TestShouldFailWhenCodeLengthInvalid() {
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"ABCD"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.AssertKO(s.T(), messageMFAValidationFailed, fasthttp.StatusBadRequest)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating an Email authentication for user 'john': expected code length is 8 but the user provided code was 4 characters in length", "")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldFailAnonymous() {
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	userSession.Username = ""
+	userSession.AuthenticationLevel = authentication.NotAuthenticated
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"ABCDEFGH"}`))
+
+	EmailPOST(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating an Email authentication", "user is anonymous")
+}
+
+func (s *HandlerSignEmailSuite) TestShouldValidateMagicLink() {
+	s.setPending(0)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCodeBySignature(s.mock.Ctx, "signature").
+			Return(s.code(), nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+	)
+
+	s.mock.Ctx.QueryArgs().Set(queryArgCode, "ABCDEFGH")
+
+	EmailLinkGET(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusFound, s.mock.Ctx.Response.StatusCode())
+	s.Equal("http://example.com/?rd=https%3A%2F%2Fwww.example.com&workflow=openid_connect&workflow_id=3ebcfbc5-b0fd-4ee0-9d3c-080ae1e7298c", string(s.mock.Ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	s.Equal(authentication.TwoFactor, userSession.AuthenticationLevel)
+}
+
+func (s *HandlerSignEmailSuite) TestShouldRedirectMagicLinkWithoutPendingChallenge() {
+	s.mock.Ctx.QueryArgs().Set(queryArgCode, "ABCDEFGH")
+
+	EmailLinkGET(s.mock.Ctx)
+
+	s.Equal(fasthttp.StatusFound, s.mock.Ctx.Response.StatusCode())
+	s.Equal("http://example.com/", string(s.mock.Ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	s.Equal(authentication.OneFactor, userSession.AuthenticationLevel)
+}
+
+func TestRunHandlerSignEmailSuite(t *testing.T) {
+	suite.Run(t, new(HandlerSignEmailSuite))
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/push"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// PushPOST starts a push with the push provider to all of the devices of the user without waiting for the
+// result, the result is polled by the portal with PushStatusPOST.
+func PushPOST(provider push.Provider) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		var (
+			bodyJSON    = &bodySignDuoRequest{}
+			userSession session.UserSession
+			txid        string
+			err         error
+		)
+
+		if err = ctx.ParseBody(bodyJSON); err != nil {
+			ctx.Logger.WithError(err).Errorf(logFmtErrParseRequestBody, regulation.AuthTypePush)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if userSession, err = ctx.GetSession(); err != nil {
+			ctx.Error(fmt.Errorf("error occurred retrieving user session: %w", err), messageMFAValidationFailed)
+			return
+		}
+
+		var message string
+
+		if bodyJSON.TargetURL != "" {
+			message = fmt.Sprintf("Sign in to %s", bodyJSON.TargetURL)
+		}
+
+		if txid, err = provider.StartBackchannelAuthentication(ctx, userSession.Username, ctx.RemoteIP().String(), message); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred starting a push for user '%s'", userSession.Username)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		userSession.Push = &session.Push{TransactionID: txid, Started: ctx.Clock.Now()}
+
+		if err = ctx.SaveSession(userSession); err != nil {
+			ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "push", regulation.AuthTypePush, logFmtActionAuthentication, userSession.Username)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if err = ctx.SetJSONBody(DuoSignResponse{Result: waiting}); err != nil {
+			ctx.Logger.Errorf("Unable to set JSON body in response")
+		}
+	}
+}
+
+// PushStatusPOST returns the result of the push started with PushPOST, and completes the second factor once the push
+// has been approved.
+func PushStatusPOST(provider push.Provider) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		var (
+			bodyJSON    = &bodySignDuoRequest{}
+			userSession session.UserSession
+			status      int
+			err         error
+		)
+
+		if err = ctx.ParseBody(bodyJSON); err != nil {
+			ctx.Logger.WithError(err).Errorf(logFmtErrParseRequestBody, regulation.AuthTypePush)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if userSession, err = ctx.GetSession(); err != nil {
+			ctx.Error(fmt.Errorf("error occurred retrieving user session: %w", err), messageMFAValidationFailed)
+			return
+		}
+
+		if userSession.Push == nil {
+			ctx.Logger.Errorf("Error occurred checking the push status for user '%s': the user session does not have a push", userSession.Username)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if ctx.Clock.Now().After(userSession.Push.Started.Add(ctx.Configuration.Push.Timeout)) {
+			userSession.Push = nil
+
+			if err = ctx.SaveSession(userSession); err != nil {
+				ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "push", regulation.AuthTypePush, logFmtActionAuthentication, userSession.Username)
+			}
+
+			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypePush, fmt.Errorf("push result: timeout"))
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		if status, err = provider.GetBackchannelAuthenticationStatus(ctx, userSession.Push.TransactionID); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred checking the push status for user '%s'", userSession.Username)
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+
+			return
+		}
+
+		switch status {
+		case model.OAuth2CIBAStatusPending:
+			if err = ctx.SetJSONBody(DuoSignResponse{Result: waiting}); err != nil {
+				ctx.Logger.Errorf("Unable to set JSON body in response")
+			}
+
+			return
+		case model.OAuth2CIBAStatusApproved:
+			userSession.Push = nil
+
+			if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypePush, nil); err != nil {
+				respondUnauthorized(ctx, messageMFAValidationFailed)
+				return
+			}
+
+			handlePushAllow(ctx, &userSession, bodyJSON)
+		default:
+			userSession.Push = nil
+
+			if err = ctx.SaveSession(userSession); err != nil {
+				ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "push", regulation.AuthTypePush, logFmtActionAuthentication, userSession.Username)
+			}
+
+			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypePush, fmt.Errorf("push result: %s", deny))
+
+			respondUnauthorized(ctx, messageMFAValidationFailed)
+		}
+	}
+}
+
+// PushChallengePOST approves or denies a push challenge delivered by the mobile, ntfy, or Gotify push provider. The
+// request is authorized by the token of the push challenge rather than the session as it's usually performed from
+// another device.
+func PushChallengePOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON bodyPushChallengeRequest
+		id       uuid.UUID
+		err      error
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred responding to a push challenge: %s", errStrReqBodyParse)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if id, err = uuid.Parse(bodyJSON.ID); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred responding to a push challenge: error occurred parsing the identifier")
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	respondPushChallenge(ctx, id, bodyJSON.Token, bodyJSON.Approve)
+}
+
+// respondPushChallenge saves the response to the push challenge, the response is only saved when the token is valid
+// for the push challenge and the push challenge is still pending.
+func respondPushChallenge(ctx *middlewares.AutheliaCtx, id uuid.UUID, token string, approve bool) {
+	status := model.PushChallengeStatusDenied
+
+	if approve {
+		status = model.PushChallengeStatusApproved
+	}
+
+	ok, err := ctx.Providers.StorageProvider.RespondPushChallenge(ctx, id, token, status, ctx.Clock.Now(), model.NewNullIP(ctx.RemoteIP()))
+
+	switch {
+	case err != nil:
+		ctx.Logger.WithError(err).Errorf("Error occurred responding to push challenge with id '%s': error occurred saving the response to the storage backend", id)
+
+		ctx.SetJSONError(messageOperationFailed)
+	case !ok:
+		ctx.Logger.Errorf("Error occurred responding to push challenge with id '%s': the push challenge does not exist, has already been responded to, has expired, or the token is invalid", id)
+
+		ctx.SetJSONError(messageOperationFailed)
+	default:
+		ctx.Logger.Debugf("Push challenge with id '%s' was responded to with the approval %t", id, approve)
+
+		ctx.ReplyOK()
+	}
+}
+
+// handlePushAllow completes the second factor for the user once the push has been approved.
+func handlePushAllow(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, bodyJSON *bodySignDuoRequest) {
+	var err error
+
+	if err = ctx.RegenerateSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionRegenerate, regulation.AuthTypePush, userSession.Username)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession.SetTwoFactorPush(ctx.Clock.Now())
+
+	if err = ctx.SaveSession(*userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf(logFmtErrSessionSave, "authentication time", regulation.AuthTypePush, logFmtActionAuthentication, userSession.Username)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	indexUserSession(ctx, *userSession)
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, userSession, bodyJSON.TargetURL)
+	default:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	}
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+func newPushMockAutheliaCtx(t *testing.T, push *session.Push, elapsed time.Duration) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	mock.Ctx.Configuration.Push.Timeout = time.Minute
+
+	if push != nil {
+		push.Started = mock.Clock.Now().Add(-elapsed)
+	}
+
+	userSession, err := mock.Ctx.GetSession()
+	require.NoError(t, err)
+
+	userSession.Username = testUsername
+	userSession.Push = push
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	return mock
+}
+
+func TestPushPOST(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		setup    func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider)
+		expected *session.Push
+		success  bool
+	}{
+		{
+			"ShouldStartPush",
+			`{"targetURL":"https://target.example.com"}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				provider.EXPECT().StartBackchannelAuthentication(mock.Ctx, testUsername, "0.0.0.0", "Sign in to https://target.example.com").Return("abc123", nil)
+			},
+			&session.Push{TransactionID: "abc123"},
+			true,
+		},
+		{
+			"ShouldStartPushWithoutTargetURL",
+			`{}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				provider.EXPECT().StartBackchannelAuthentication(mock.Ctx, testUsername, "0.0.0.0", "").Return("abc123", nil)
+			},
+			&session.Push{TransactionID: "abc123"},
+			true,
+		},
+		{
+			"ShouldFailStartPush",
+			`{}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				provider.EXPECT().StartBackchannelAuthentication(mock.Ctx, testUsername, "0.0.0.0", "").Return("", errors.New("bad connection"))
+			},
+			nil,
+			false,
+		},
+		{
+			"ShouldFailInvalidBody",
+			`abc`,
+			nil,
+			nil,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newPushMockAutheliaCtx(t, nil, 0)
+
+			defer mock.Close()
+
+			provider := mocks.NewMockPushProvider(mock.Ctrl)
+
+			if tc.setup != nil {
+				tc.setup(t, mock, provider)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			PushPOST(provider)(mock.Ctx)
+
+			if tc.success {
+				mock.Assert200OK(t, DuoSignResponse{Result: waiting})
+			} else {
+				mock.Assert401KO(t, messageMFAValidationFailed)
+			}
+
+			userSession, err := mock.Ctx.GetSession()
+			require.NoError(t, err)
+
+			if tc.expected == nil {
+				assert.Nil(t, userSession.Push)
+			} else {
+				require.NotNil(t, userSession.Push)
+				assert.Equal(t, tc.expected.TransactionID, userSession.Push.TransactionID)
+				assert.Equal(t, mock.Clock.Now(), userSession.Push.Started)
+			}
+		})
+	}
+}
+
+func TestPushStatusPOST(t *testing.T) {
+	testCases := []struct {
+		name    string
+		push    *session.Push
+		elapsed time.Duration
+		setup   func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider)
+		assert  func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		pending bool
+	}{
+		{
+			"ShouldWait",
+			&session.Push{TransactionID: "abc123"},
+			time.Second,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				provider.EXPECT().GetBackchannelAuthenticationStatus(mock.Ctx, "abc123").Return(model.OAuth2CIBAStatusPending, nil)
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert200OK(t, DuoSignResponse{Result: waiting})
+			},
+			true,
+		},
+		{
+			"ShouldAllow",
+			&session.Push{TransactionID: "abc123"},
+			time.Second,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				gomock.InOrder(
+					provider.EXPECT().GetBackchannelAuthenticationStatus(mock.Ctx, "abc123").Return(model.OAuth2CIBAStatusApproved, nil),
+					mock.StorageMock.EXPECT().AppendAuthenticationLog(mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+						Username:   testUsername,
+						Successful: true,
+						Time:       mock.Clock.Now(),
+						Type:       regulation.AuthTypePush,
+						RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+					})).Return(nil),
+				)
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert200OK(t, &redirectResponse{Redirect: "https://www.example.com"})
+
+				userSession, err := mock.Ctx.GetSession()
+				require.NoError(t, err)
+
+				assert.Equal(t, authentication.TwoFactor, userSession.AuthenticationLevel)
+				assert.True(t, userSession.AuthenticationMethodRefs.Push)
+			},
+			false,
+		},
+		{
+			"ShouldDeny",
+			&session.Push{TransactionID: "abc123"},
+			time.Second,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				gomock.InOrder(
+					provider.EXPECT().GetBackchannelAuthenticationStatus(mock.Ctx, "abc123").Return(model.OAuth2CIBAStatusDenied, nil),
+					mock.StorageMock.EXPECT().AppendAuthenticationLog(mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+						Username:   testUsername,
+						Successful: false,
+						Time:       mock.Clock.Now(),
+						Type:       regulation.AuthTypePush,
+						RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+					})).Return(nil),
+				)
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert401KO(t, messageMFAValidationFailed)
+			},
+			false,
+		},
+		{
+			"ShouldFailExpired",
+			&session.Push{TransactionID: "abc123"},
+			2 * time.Minute,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				mock.StorageMock.EXPECT().AppendAuthenticationLog(mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+					Username:   testUsername,
+					Successful: false,
+					Time:       mock.Clock.Now(),
+					Type:       regulation.AuthTypePush,
+					RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+				})).Return(nil)
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert401KO(t, messageMFAValidationFailed)
+			},
+			false,
+		},
+		{
+			"ShouldFailStatusError",
+			&session.Push{TransactionID: "abc123"},
+			time.Second,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx, provider *mocks.MockPushProvider) {
+				provider.EXPECT().GetBackchannelAuthenticationStatus(mock.Ctx, "abc123").Return(model.OAuth2CIBAStatusPending, errors.New("bad connection"))
+			},
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert401KO(t, messageMFAValidationFailed)
+			},
+			true,
+		},
+		{
+			"ShouldFailWithoutPush",
+			nil,
+			0,
+			nil,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Assert401KO(t, messageMFAValidationFailed)
+			},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := newPushMockAutheliaCtx(t, tc.push, tc.elapsed)
+
+			defer mock.Close()
+
+			provider := mocks.NewMockPushProvider(mock.Ctrl)
+
+			if tc.setup != nil {
+				tc.setup(t, mock, provider)
+			}
+
+			mock.Ctx.Request.SetBodyString(`{}`)
+
+			PushStatusPOST(provider)(mock.Ctx)
+
+			tc.assert(t, mock)
+
+			userSession, err := mock.Ctx.GetSession()
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.pending, userSession.Push != nil)
+		})
+	}
+}
+
+func TestPushChallengePOST(t *testing.T) {
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	testCases := []struct {
+		name     string
+		body     string
+		setup    func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected string
+	}{
+		{
+			"ShouldApprove",
+			`{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":true}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RespondPushChallenge(mock.Ctx, id, "abc123", model.PushChallengeStatusApproved, mock.Clock.Now(), model.NewNullIP(mock.Ctx.RemoteIP())).Return(true, nil)
+			},
+			`{"status":"OK"}`,
+		},
+		{
+			"ShouldDeny",
+			`{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":false}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RespondPushChallenge(mock.Ctx, id, "abc123", model.PushChallengeStatusDenied, mock.Clock.Now(), model.NewNullIP(mock.Ctx.RemoteIP())).Return(true, nil)
+			},
+			`{"status":"OK"}`,
+		},
+		{
+			"ShouldFailNotPending",
+			`{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":true}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RespondPushChallenge(mock.Ctx, id, "abc123", model.PushChallengeStatusApproved, mock.Clock.Now(), model.NewNullIP(mock.Ctx.RemoteIP())).Return(false, nil)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+		},
+		{
+			"ShouldFailStorageError",
+			`{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":true}`,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RespondPushChallenge(mock.Ctx, id, "abc123", model.PushChallengeStatusApproved, mock.Clock.Now(), model.NewNullIP(mock.Ctx.RemoteIP())).Return(false, errors.New("bad connection"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+		},
+		{
+			"ShouldFailMissingToken",
+			`{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","approve":true}`,
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+		},
+		{
+			"ShouldFailInvalidID",
+			`{"id":"abc","token":"abc123","approve":true}`,
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			mock.Ctx.Request.SetBodyString(tc.body)
+
+			PushChallengePOST(mock.Ctx)
+
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/sms"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// SMSConfigurationGET returns the users verified phone number with all but the last digits masked.
+func SMSConfigurationGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the phone number: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred retrieving the phone number")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	var number *model.PhoneNumber
+
+	if number, err = ctx.Providers.StorageProvider.LoadPhoneNumber(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the phone number for user '%s': error occurred retrieving the phone number from the storage backend", userSession.Username)
+
+		if errors.Is(err, storage.ErrNoPhoneNumber) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetJSONError("Could not find a phone number for user.")
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+			ctx.SetJSONError("Could not find a phone number for user.")
+		}
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(number); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred retrieving the phone number for user '%s': %s", userSession.Username, errStrRespBody)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+}
+
+// SMSPUT sends a One-Time Code to the verified phone number of the user by SMS or voice call.
+func SMSPUT(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodySignSMSStartRequest{}
+
+	var (
+		userSession session.UserSession
+		number      *model.PhoneNumber
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending a SMS One-Time Code: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred sending a SMS One-Time Code")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending a SMS One-Time Code for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if bodyJSON.Voice && !ctx.Configuration.SMS.EnableVoice {
+		ctx.Logger.Errorf("Error occurred sending a SMS One-Time Code for user '%s': the delivery of One-Time Codes with a voice call is not enabled", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	if number, err = ctx.Providers.StorageProvider.LoadPhoneNumber(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred sending a SMS One-Time Code for user '%s': error occurred retrieving the phone number from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageUnableToSendOneTimeCode)
+
+		return
+	}
+
+	intent := model.OTCIntentSMSSignIn
+
+	if bodyJSON.Voice {
+		intent = model.OTCIntentVoiceSignIn
+	}
+
+	if !handleSMSOneTimeCode(ctx, userSession.Username, intent, number.E164(), bodyJSON.Voice, "sending a SMS One-Time Code", messageUnableToSendOneTimeCode) {
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// SMSPOST validates the One-Time Code sent to the verified phone number of the user.
+//
+//nolint:gocyclo
+func SMSPOST(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := bodySignSMSRequest{}
+
+	var (
+		userSession session.UserSession
+		number      *model.PhoneNumber
+		code        *model.OneTimeCode
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Error("Error occurred validating a SMS authentication")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': %s", userSession.Username, errStrReqBodyParse)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	bodyJSON.Code = strings.TrimSpace(bodyJSON.Code)
+
+	if n := len(bodyJSON.Code); n != ctx.Configuration.SMS.Characters {
+		ctx.Logger.Errorf("Error occurred validating a SMS authentication for user '%s': expected code length is %d but the user provided code was %d characters in length", userSession.Username, ctx.Configuration.SMS.Characters, n)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if number, err = ctx.Providers.StorageProvider.LoadPhoneNumber(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error occurred retrieving the phone number from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	intent := model.OTCIntentSMSSignIn
+
+	if bodyJSON.Voice {
+		intent = model.OTCIntentVoiceSignIn
+	}
+
+	if code, err = ctx.Providers.StorageProvider.LoadOneTimeCode(ctx, userSession.Username, intent, bodyJSON.Code); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error occurred retrieving the code challenge from the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if code == nil {
+		err = fmt.Errorf("the code didn't match any recorded code challenges")
+	} else {
+		err = code.Validate(ctx, intent, []byte(bodyJSON.Code))
+	}
+
+	if err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error occurred validating the user input", userSession.Username)
+
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeSMS, nil)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	code.Consume(ctx)
+
+	if err = ctx.Providers.StorageProvider.ConsumeOneTimeCode(ctx, code); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error occurred saving the consumption of the code to the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeSMS, nil); err != nil {
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = ctx.RegenerateSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error regenerating the user session", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	number.UpdateSignInInfo(ctx.Clock.Now())
+
+	if err = ctx.Providers.StorageProvider.UpdatePhoneNumberSignIn(ctx, number.ID, number.LastUsedAt); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': error occurred saving the phone number sign-in information to the storage backend", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession.SetTwoFactorSMS(ctx.Clock.Now(), bodyJSON.Voice)
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred validating a SMS authentication for user '%s': %s", userSession.Username, errStrUserSessionDataSave)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageMFAValidationFailed)
+
+		return
+	}
+
+	indexUserSession(ctx, userSession)
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
+		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	}
+}
+
+// handleSMSOneTimeCode generates a One-Time Code with the intent, saves it to the storage backend, and sends it to the
+// phone number. The number of One-Time Codes sent to the user within the configured period is checked beforehand so
+// the SMS provider can't be used to flood a phone number. It returns false after replying with an error.
+func handleSMSOneTimeCode(ctx *middlewares.AutheliaCtx, username, intent, to string, voice bool, action, message string) (ok bool) {
+	var (
+		limited bool
+		otp     *model.OneTimeCode
+		err     error
+	)
+
+	if limited, err = isSMSRateLimited(ctx, username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred %s for user '%s': error occurred counting the sent codes in the storage backend", action, username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(message)
+
+		return false
+	}
+
+	if limited {
+		ctx.Logger.Errorf("Error occurred %s for user '%s': the user has been sent %d codes within the last %s", action, username, ctx.Configuration.SMS.RateLimit.Requests, ctx.Configuration.SMS.RateLimit.Period)
+
+		ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+		ctx.SetJSONError(message)
+
+		return false
+	}
+
+	opts := model.OneTimeCodeOptions{
+		Intent:       intent,
+		Length:       ctx.Configuration.SMS.Characters,
+		CharacterSet: random.CharSetNumeric,
+		Lifespan:     ctx.Configuration.SMS.CodeLifespan,
+	}
+
+	if otp, err = model.NewOneTimeCode(ctx, username, opts); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred %s for user '%s': error occurred generating the code", action, username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(message)
+
+		return false
+	}
+
+	if _, err = ctx.Providers.StorageProvider.SaveOneTimeCode(ctx, *otp); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred %s for user '%s': error occurred saving the code to the storage backend", action, username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(message)
+
+		return false
+	}
+
+	msg := &sms.Message{
+		To:       to,
+		Code:     string(otp.Code),
+		Voice:    voice,
+		Lifespan: opts.Lifespan,
+	}
+
+	ctx.Logger.WithFields(map[string]any{"id": otp.PublicID.String(), "username": username, "channel": msg.Channel()}).
+		Debug("Sending a One-Time Code to the phone number of the user")
+
+	if err = ctx.Providers.SMS.Send(ctx, msg); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred %s for user '%s': error occurred sending the code with the SMS provider", action, username)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(message)
+
+		return false
+	}
+
+	return true
+}
+
+// isSMSRateLimited returns true if the user has been sent the configured number of One-Time Codes within the
+// configured period.
+func isSMSRateLimited(ctx *middlewares.AutheliaCtx, username string) (limited bool, err error) {
+	config := ctx.Configuration.SMS.RateLimit
+
+	if config.Disable {
+		return false, nil
+	}
+
+	since := ctx.Clock.Now().Add(-config.Period)
+
+	var count, n int
+
+	for _, intent := range []string{model.OTCIntentPhoneNumberVerification, model.OTCIntentSMSSignIn, model.OTCIntentVoiceSignIn} {
+		if n, err = ctx.Providers.StorageProvider.CountOneTimeCodes(ctx, username, intent, since); err != nil {
+			return false, err
+		}
+
+		count += n
+	}
+
+	return count >= config.Requests, nil
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/sms"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type HandlerSignSMSSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *HandlerSignSMSSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Assert().NoError(err)
+
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	s.Assert().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Clock.Set(time.Unix(1701295903, 0))
+	s.mock.Ctx.Clock = &s.mock.Clock
+	s.mock.Ctx.Configuration.SMS = schema.SMS{
+		Provider:     schema.SMSProviderHTTP,
+		Characters:   6,
+		CodeLifespan: time.Minute * 5,
+		RateLimit: schema.SMSRateLimit{
+			Requests: 5,
+			Period:   time.Hour,
+		},
+	}
+}
+
+func (s *HandlerSignSMSSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *HandlerSignSMSSuite) phoneNumber() *model.PhoneNumber {
+	return &model.PhoneNumber{ID: 1, Username: testUsername, Number: []byte("+15551234567")}
+}
+
+func (s *HandlerSignSMSSuite) code(intent string) *model.OneTimeCode {
+	return &model.OneTimeCode{ID: 1, Username: testUsername, Intent: intent, ExpiresAt: s.mock.Clock.Now().Add(time.Minute), Code: []byte("123456")}
+}
+
+func (s *HandlerSignSMSSuite) expectCount(counts ...int) {
+	intents := []string{model.OTCIntentPhoneNumberVerification, model.OTCIntentSMSSignIn, model.OTCIntentVoiceSignIn}
+
+	for i, count := range counts {
+		s.mock.StorageMock.EXPECT().
+			CountOneTimeCodes(s.mock.Ctx, testUsername, intents[i], s.mock.Clock.Now().Add(-time.Hour)).
+			Return(count, nil)
+	}
+}
+
+func (s *HandlerSignSMSSuite) TestShouldReturnPhoneNumber() {
+	number := s.phoneNumber()
+	number.CreatedAt = s.mock.Clock.Now()
+
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(number, nil)
+
+	SMSConfigurationGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), number)
+	s.Contains(string(s.mock.Ctx.Response.Body()), `"phone_number":"+*******4567"`)
+}
+
+func (s *HandlerSignSMSSuite) TestShouldReturnNotFoundWithoutPhoneNumber() {
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(nil, storage.ErrNoPhoneNumber)
+
+	SMSConfigurationGET(s.mock.Ctx)
+
+	s.mock.Assert404KO(s.T(), "Could not find a phone number for user.")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldSendSMS() {
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(s.phoneNumber(), nil)
+
+	s.expectCount(1, 1, 1)
+
+	s.mock.StorageMock.EXPECT().
+		SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ any, code model.OneTimeCode) (string, error) {
+			s.Equal(model.OTCIntentSMSSignIn, code.Intent)
+			s.Len(code.Code, 6)
+			s.Equal(s.mock.Clock.Now().Add(time.Minute*5), code.ExpiresAt)
+
+			return "signature", nil
+		})
+
+	s.mock.SMSMock.EXPECT().
+		Send(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ any, msg *sms.Message) error {
+			s.Equal("+15551234567", msg.To)
+			s.False(msg.Voice)
+			s.Equal(time.Minute*5, msg.Lifespan)
+
+			return nil
+		})
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignSMSSuite) TestShouldSendVoice() {
+	s.mock.Ctx.Configuration.SMS.EnableVoice = true
+
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(s.phoneNumber(), nil)
+
+	s.expectCount(0, 0, 0)
+
+	s.mock.StorageMock.EXPECT().
+		SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ any, code model.OneTimeCode) (string, error) {
+			s.Equal(model.OTCIntentVoiceSignIn, code.Intent)
+
+			return "signature", nil
+		})
+
+	s.mock.SMSMock.EXPECT().
+		Send(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ any, msg *sms.Message) error {
+			s.True(msg.Voice)
+
+			return nil
+		})
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"voice":true}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignSMSSuite) TestShouldNotSendVoiceWhenDisabled() {
+	s.mock.Ctx.Request.SetBody([]byte(`{"voice":true}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.AssertKO(s.T(), messageUnableToSendOneTimeCode, fasthttp.StatusBadRequest)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending a SMS One-Time Code for user 'john': the delivery of One-Time Codes with a voice call is not enabled", "")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldNotSendWhenRateLimited() {
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(s.phoneNumber(), nil)
+
+	s.expectCount(2, 2, 1)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.AssertKO(s.T(), messageUnableToSendOneTimeCode, fasthttp.StatusTooManyRequests)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending a SMS One-Time Code for user 'john': the user has been sent 5 codes within the last 1h0m0s", "")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldSendWhenRateLimitDisabled() {
+	s.mock.Ctx.Configuration.SMS.RateLimit.Disable = true
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPhoneNumber(s.mock.Ctx, testUsername).
+			Return(s.phoneNumber(), nil),
+		s.mock.StorageMock.EXPECT().
+			SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return("signature", nil),
+		s.mock.SMSMock.EXPECT().
+			Send(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignSMSSuite) TestShouldHandleSendError() {
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(s.phoneNumber(), nil)
+
+	s.expectCount(0, 0, 0)
+
+	s.mock.StorageMock.EXPECT().
+		SaveOneTimeCode(s.mock.Ctx, gomock.Any()).
+		Return("signature", nil)
+
+	s.mock.SMSMock.EXPECT().
+		Send(s.mock.Ctx, gomock.Any()).
+		Return(fmt.Errorf("the provider is unavailable"))
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageUnableToSendOneTimeCode)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending a SMS One-Time Code for user 'john': error occurred sending the code with the SMS provider", "the provider is unavailable")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldHandleSendWithoutPhoneNumber() {
+	s.mock.StorageMock.EXPECT().
+		LoadPhoneNumber(s.mock.Ctx, testUsername).
+		Return(nil, storage.ErrNoPhoneNumber)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{}`))
+
+	SMSPUT(s.mock.Ctx)
+
+	s.mock.Assert403KO(s.T(), messageUnableToSendOneTimeCode)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred sending a SMS One-Time Code for user 'john': error occurred retrieving the phone number from the storage backend", "no phone number for user")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldRedirectUserToDefaultURL() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPhoneNumber(s.mock.Ctx, testUsername).
+			Return(s.phoneNumber(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCode(s.mock.Ctx, testUsername, model.OTCIntentSMSSignIn, "123456").
+			Return(s.code(model.OTCIntentSMSSignIn), nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: true,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeSMS,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			UpdatePhoneNumberSignIn(s.mock.Ctx, 1, sql.NullTime{Time: s.mock.Clock.Now(), Valid: true}).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Configuration.Session.Cookies[0].DefaultRedirectionURL = testRedirectionURL
+
+	bodyBytes, err := json.Marshal(bodySignSMSRequest{
+		Code: "123456",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), redirectResponse{
+		Redirect: testRedirectionURLString,
+	})
+}
+
+func (s *HandlerSignSMSSuite) TestShouldValidateVoiceCode() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPhoneNumber(s.mock.Ctx, testUsername).
+			Return(s.phoneNumber(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCode(s.mock.Ctx, testUsername, model.OTCIntentVoiceSignIn, "123456").
+			Return(s.code(model.OTCIntentVoiceSignIn), nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeOneTimeCode(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.EXPECT().
+			UpdatePhoneNumberSignIn(s.mock.Ctx, 1, gomock.Any()).
+			Return(nil),
+	)
+
+	bodyBytes, err := json.Marshal(bodySignSMSRequest{
+		Code:  "123456",
+		Voice: true,
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignSMSSuite) TestShouldFailWhenCodeNotFound() {
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPhoneNumber(s.mock.Ctx, testUsername).
+			Return(s.phoneNumber(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCode(s.mock.Ctx, testUsername, model.OTCIntentSMSSignIn, "654321").
+			Return(nil, nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeSMS,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"654321"}`))
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	AssertLogEntryMessageAndError(s.T(), MustGetLogLastSeq(s.T(), s.mock.Hook, 1), "Error occurred validating a SMS authentication for user 'john': error occurred validating the user input", "the code didn't match any recorded code challenges")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldFailWhenCodeExpired() {
+	code := s.code(model.OTCIntentSMSSignIn)
+	code.ExpiresAt = s.mock.Clock.Now().Add(-time.Second)
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadPhoneNumber(s.mock.Ctx, testUsername).
+			Return(s.phoneNumber(), nil),
+		s.mock.StorageMock.EXPECT().
+			LoadOneTimeCode(s.mock.Ctx, testUsername, model.OTCIntentSMSSignIn, "123456").
+			Return(code, nil),
+		s.mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"123456"}`))
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	AssertLogEntryMessageAndError(s.T(), MustGetLogLastSeq(s.T(), s.mock.Hook, 1), "Error occurred validating a SMS authentication for user 'john': error occurred validating the user input", "the code challenge has expired")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldFailWhenCodeLengthInvalid() {
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"1234"}`))
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.AssertKO(s.T(), messageMFAValidationFailed, fasthttp.StatusBadRequest)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating a SMS authentication for user 'john': expected code length is 6 but the user provided code was 4 characters in length", "")
+}
+
+func (s *HandlerSignSMSSuite) TestShouldFailAnonymous() {
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	userSession.Username = ""
+	userSession.AuthenticationLevel = authentication.NotAuthenticated
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Request.SetBody([]byte(`{"code":"123456"}`))
+
+	SMSPOST(s.mock.Ctx)
+	s.mock.Assert403KO(s.T(), messageMFAValidationFailed)
+
+	AssertLogEntryMessageAndError(s.T(), s.mock.Hook.LastEntry(), "Error occurred validating a SMS authentication", "user is anonymous")
+}
+
+func TestRunHandlerSignSMSSuite(t *testing.T) {
+	suite.Run(t, new(HandlerSignSMSSuite))
+}
@@ -123,6 +123,8 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	drift := config.Drift
+
 	if valid, step, err = ctx.Providers.TOTP.Validate(ctx, bodyJSON.Token, config); err != nil {
 		ctx.Logger.WithError(err).Errorf("Error occurred validating a TOTP authentication for user '%s': error occurred validating the user input", userSession.Username)
 
@@ -199,6 +201,8 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	handleTOTPDrift(ctx, config, drift, step)
+
 	userSession.SetTwoFactorTOTP(ctx.Clock.Now())
 
 	if err = ctx.SaveSession(userSession); err != nil {
@@ -210,9 +214,33 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if bodyJSON.Workflow == workflowOpenIDConnect {
+	indexUserSession(ctx, userSession)
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
 		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
-	} else {
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
 		Handle2FAResponse(ctx, bodyJSON.TargetURL)
 	}
 }
+
+// handleTOTPDrift records the offset of the validated step and saves the learned clock drift of the configuration when
+// the validation changed it. Failing to save the learned clock drift doesn't fail the authentication as it's learned
+// again on the next successful validation.
+func handleTOTPDrift(ctx *middlewares.AutheliaCtx, config *model.TOTPConfiguration, drift int, step uint64) {
+	ctx.RecordTOTPDrift(config.StepOffset(ctx.Clock.Now(), step))
+
+	if config.Drift == drift {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.UpdateTOTPConfigurationDrift(ctx, config.ID, config.Drift); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred saving the learned TOTP clock drift for user '%s' to the storage backend", config.Username)
+
+		return
+	}
+
+	ctx.Logger.WithFields(map[string]any{"username": config.Username, "drift": config.Drift, "previous": drift}).Debug("Learned the TOTP clock drift of the user")
+}
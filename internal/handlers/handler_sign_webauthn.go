@@ -59,6 +59,10 @@ func WebAuthnAssertionGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if removed := handleWebAuthnUserFilterCredentials(ctx, &userSession, user); removed != 0 {
+		ctx.Logger.Debugf("Excluded %%d WebAuthn credentials which are eligible to be backed up for user '%%s' as they are prohibited by the filtering policy", removed, userSession.Username)
+	}
+
 	extensions := map[string]any{}
 
 	if user.HasFIDOU2F() {
@@ -73,6 +77,10 @@ func WebAuthnAssertionGET(ctx *middlewares.AutheliaCtx) {
 		opts = append(opts, webauthn.WithAssertionExtensions(extensions))
 	}
 
+	if bytes.Equal(ctx.QueryArgs().PeekBytes(qryArgUV), []byte(protocol.VerificationRequired)) {
+		opts = append(opts, webauthn.WithUserVerification(protocol.VerificationRequired))
+	}
+
 	var (
 		assertion *protocol.CredentialAssertion
 		data      session.WebAuthn
@@ -189,6 +197,10 @@ func WebAuthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	if removed := handleWebAuthnUserFilterCredentials(ctx, &userSession, user); removed != 0 {
+		ctx.Logger.Debugf("Excluded %%d WebAuthn credentials which are eligible to be backed up for user '%%s' as they are prohibited by the filtering policy", removed, userSession.Username)
+	}
+
 	if c, err = w.ValidateLogin(user, *userSession.WebAuthn.SessionData, assertionResponse); err != nil {
 		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeWebAuthn, formatWebAuthnError(err))
 
@@ -212,6 +224,8 @@ func WebAuthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		if bytes.Equal(credential.KID.Bytes(), c.ID) {
 			credential.UpdateSignInInfo(w.Config, ctx.Clock.Now().UTC(), c.Authenticator)
 
+			credential.BackupState = c.Flags.BackupState
+
 			found = true
 
 			if err = ctx.Providers.StorageProvider.UpdateWebAuthnCredentialSignIn(ctx, credential); err != nil {
@@ -254,7 +268,10 @@ func WebAuthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeWebAuthn, nil); err != nil {
+	if err = markAuthenticationAttemptWithMetadata(ctx, true, nil, userSession.Username, regulation.AuthTypeWebAuthn, nil, map[string]any{
+		"user_presence": assertionResponse.Response.AuthenticatorData.Flags.HasUserPresent(),
+		"user_verified": assertionResponse.Response.AuthenticatorData.Flags.HasUserVerified(),
+	}); err != nil {
 		ctx.SetStatusCode(fasthttp.StatusForbidden)
 		ctx.SetJSONError(messageMFAValidationFailed)
 
@@ -266,9 +283,14 @@ func WebAuthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		assertionResponse.Response.AuthenticatorData.Flags.HasUserPresent(),
 		assertionResponse.Response.AuthenticatorData.Flags.HasUserVerified())
 
-	if bodyJSON.Workflow == workflowOpenIDConnect {
+	indexUserSession(ctx, userSession)
+
+	switch bodyJSON.Workflow {
+	case workflowOpenIDConnect:
 		handleOIDCWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL, bodyJSON.WorkflowID)
-	} else {
+	case workflowSAML:
+		handleSAMLWorkflowResponse(ctx, &userSession, bodyJSON.TargetURL)
+	default:
 		Handle2FAResponse(ctx, bodyJSON.TargetURL)
 	}
 }
@@ -100,6 +100,79 @@ func TestWebAuthnAssertionGET(t *testing.T) {
 				assert.Equal(t, []byte{0x5a, 0x79, 0x74, 0x6c, 0x4a, 0x6c, 0x56, 0x75, 0x57, 0x7a, 0x64, 0x67, 0x4e, 0x32, 0x42, 0x78, 0x54, 0x79, 0x49, 0x38, 0x55, 0x79, 0x39, 0x75, 0x53, 0x32, 0x78, 0x70, 0x4a, 0x53, 0x64, 0x73, 0x54, 0x32, 0x5a, 0x73, 0x4a, 0x55, 0x41, 0x35, 0x55, 0x45, 0x42, 0x76, 0x65, 0x31, 0x63, 0x32, 0x4e, 0x45, 0x4e, 0x43, 0x4b, 0x44, 0x4e, 0x53, 0x57, 0x57, 0x70, 0x68, 0x61, 0x47, 0x56, 0x43, 0x4a, 0x45, 0x68, 0x6c, 0x51, 0x33, 0x77, 0x70, 0x59, 0x54, 0x39, 0x48, 0x51, 0x47, 0x42, 0x77, 0x49, 0x69, 0x38, 0x7a, 0x51, 0x41, 0x3d, 0x3d}, us.WebAuthn.UserID)
 			},
 		},
+		{
+			"ShouldExcludeBackupEligibleCredentialsWhenProhibited",
+			func() *schema.WebAuthn {
+				config := schema.DefaultWebAuthnConfiguration
+
+				config.Filtering.ProhibitBackupEligibility = true
+
+				return &config
+			}(),
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+
+				us.Username = testUsername
+				us.AuthenticationLevel = authentication.OneFactor
+
+				require.NoError(t, mock.Ctx.SaveSession(us))
+
+				credentials := []model.WebAuthnCredential{
+					{
+						ID:              1,
+						RPID:            "login.example.com",
+						Username:        testUsername,
+						Description:     "test",
+						KID:             model.NewBase64(decode("rwOwV8WCh1hrE0M6mvaoRGpGHidqK6IlhkDJ2xERhPU=")),
+						AttestationType: "packed",
+						Attachment:      "cross-platform",
+						Transport:       "usb",
+						SignCount:       4,
+						Present:         true,
+						Verified:        true,
+					},
+					{
+						ID:              2,
+						RPID:            "login.example.com",
+						Username:        testUsername,
+						Description:     "passkey",
+						KID:             model.NewBase64([]byte("abc")),
+						AttestationType: "none",
+						Attachment:      "platform",
+						Transport:       "internal",
+						Present:         true,
+						Verified:        true,
+						BackupEligible:  true,
+						BackupState:     true,
+					},
+				}
+
+				gomock.InOrder(
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnUser(mock.Ctx, "login.example.com", testUsername).
+						Return(&model.WebAuthnUser{ID: 1, RPID: "login.example.com", Username: testUsername, UserID: "ZytlJlVuWzdgN2BxTyI8Uy9uS2xpJSdsT2ZsJUA5UEBve1c2NENCKDNSWWphaGVCJEhlQ3wpYT9HQGBwIi8zQA=="}, nil),
+					mock.StorageMock.
+						EXPECT().
+						LoadWebAuthnCredentialsByUsername(mock.Ctx, "login.example.com", testUsername).
+						Return(credentials, nil),
+				)
+			},
+			regexp.MustCompile(`^\{"status":"OK","data":\{"publicKey":\{"challenge":"[a-zA-Z0-9/_-]+={0,2}","timeout":60000,"rpId":"login.example.com","allowCredentials":\[\{"type":"public-key","id":"rwOwV8WCh1hrE0M6mvaoRGpGHidqK6IlhkDJ2xERhPU","transports":\["usb"]}],"userVerification":"preferred"}}}$`),
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				us, err := mock.Ctx.GetSession()
+
+				require.NoError(t, err)
+
+				require.NotNil(t, us.WebAuthn)
+				require.NotNil(t, us.WebAuthn.SessionData)
+
+				assert.Len(t, us.WebAuthn.SessionData.AllowedCredentialIDs, 1)
+			},
+		},
 		{
 			"ShouldHandleCredentialError",
 			&schema.DefaultWebAuthnConfiguration,
@@ -21,8 +21,9 @@ func StateGET(ctx *middlewares.AutheliaCtx) {
 	}
 
 	stateResponse := StateResponse{
-		Username:            userSession.Username,
-		AuthenticationLevel: userSession.AuthenticationLevel,
+		Username:             userSession.Username,
+		AuthenticationLevel:  userSession.AuthenticationLevel,
+		WebAuthnUserVerified: userSession.AuthenticationMethodRefs.WebAuthnUserVerified,
 	}
 
 	if uri := ctx.GetDefaultRedirectionURL(); uri != nil {
@@ -90,6 +90,40 @@ func (s *StateGetSuite) TestShouldReturnAuthenticationLevelFromSession() {
 	assert.Equal(s.T(), expectedBody, actualBody)
 }
 
+func (s *StateGetSuite) TestShouldReturnWebAuthnUserVerifiedFromSession() {
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Assert().NoError(err)
+
+	userSession.Username = "john"
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.AuthenticationMethodRefs.WebAuthn = true
+	userSession.AuthenticationMethodRefs.WebAuthnUserVerified = true
+	s.Assert().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	StateGET(s.mock.Ctx)
+
+	type Response struct {
+		Status string
+		Data   StateResponse
+	}
+
+	expectedBody := Response{
+		Status: "OK",
+		Data: StateResponse{
+			Username:              "john",
+			DefaultRedirectionURL: "https://www.example.com",
+			AuthenticationLevel:   authentication.TwoFactor,
+			WebAuthnUserVerified:  true,
+		},
+	}
+	actualBody := Response{}
+
+	err = json.Unmarshal(s.mock.Ctx.Response.Body(), &actualBody)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+	assert.Equal(s.T(), expectedBody, actualBody)
+}
+
 func TestRunStateGetSuite(t *testing.T) {
 	s := new(StateGetSuite)
 	suite.Run(t, s)
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// UserActivityAuthenticationsGET returns the recent authentication attempts of the current user.
+func UserActivityAuthenticationsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		pagination  model.Pagination
+		attempts    []model.AuthenticationAttempt
+		err         error
+	)
+
+	if userSession, err = getUserActivitySession(ctx, "authentication history"); err != nil {
+		return
+	}
+
+	if pagination, err = getPagination(ctx); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading authentication history for user '%s': error occurred parsing the pagination", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+
+		return
+	}
+
+	if attempts, err = ctx.Providers.StorageProvider.LoadAuthenticationHistory(ctx, userSession.Username, model.AuthenticationAttemptFilter{Type: string(ctx.QueryArgs().Peek(queryArgType))}, pagination); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading authentication history for user '%s': error occurred loading authentication attempts from the storage backend", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	body := make([]UserActivityAuthentication, len(attempts))
+
+	for i, attempt := range attempts {
+		body[i] = UserActivityAuthentication{
+			Time:          attempt.Time,
+			Successful:    attempt.Successful,
+			Banned:        attempt.Banned,
+			Type:          attempt.Type,
+			RequestURI:    attempt.RequestURI,
+			RequestMethod: attempt.RequestMethod,
+		}
+
+		if attempt.RemoteIP.IP != nil {
+			body[i].RemoteIP = attempt.RemoteIP.IP.String()
+		}
+	}
+
+	if err = ctx.SetJSONBody(body); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading authentication history for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// UserActivityDevicesGET returns the devices registered by the current user for the enabled second factor methods.
+func UserActivityDevicesGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		body        UserActivityDevices
+		err         error
+	)
+
+	if userSession, err = getUserActivitySession(ctx, "devices"); err != nil {
+		return
+	}
+
+	if !ctx.Configuration.TOTP.Disable {
+		if body.TOTP, err = ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, userSession.Username); err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+			ctx.Logger.WithError(err).Errorf("Error occurred loading devices for user '%s': error occurred loading the TOTP configuration from the storage backend", userSession.Username)
+
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	}
+
+	if !ctx.Configuration.WebAuthn.Disable {
+		var origin *url.URL
+
+		if origin, err = ctx.GetOrigin(); err != nil {
+			ctx.Logger.WithError(err).Errorf("Error occurred loading devices for user '%s': error occurred attempting to retrieve origin", userSession.Username)
+
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		if body.WebAuthn, err = ctx.Providers.StorageProvider.LoadWebAuthnCredentialsByUsername(ctx, origin.Hostname(), userSession.Username); err != nil && !errors.Is(err, storage.ErrNoWebAuthnCredential) {
+			ctx.Logger.WithError(err).Errorf("Error occurred loading devices for user '%s': error occurred loading WebAuthn credentials from the storage backend", userSession.Username)
+
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	}
+
+	if !ctx.Configuration.DuoAPI.Disable {
+		var device *model.DuoDevice
+
+		switch device, err = ctx.Providers.StorageProvider.LoadPreferredDuoDevice(ctx, userSession.Username); {
+		case err == nil:
+			body.Duo = &UserActivityDuoDevice{Device: device.Device, Method: device.Method}
+		case !errors.Is(err, storage.ErrNoDuoDevice):
+			ctx.Logger.WithError(err).Errorf("Error occurred loading devices for user '%s': error occurred loading the preferred Duo device from the storage backend", userSession.Username)
+
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	}
+
+	if err = ctx.SetJSONBody(body); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading devices for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// UserActivityConsentsGET returns the recent OpenID Connect 1.0 consent responses of the current user.
+func UserActivityConsentsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		pagination  model.Pagination
+		consents    []model.OAuth2ConsentSession
+		err         error
+	)
+
+	if userSession, err = getUserActivitySession(ctx, "consents"); err != nil {
+		return
+	}
+
+	if pagination, err = getPagination(ctx); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading consents for user '%s': error occurred parsing the pagination", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+
+		return
+	}
+
+	if consents, err = ctx.Providers.StorageProvider.LoadOAuth2ConsentSessionsByUsername(ctx, userSession.Username, model.OAuth2ConsentSessionFilter{ClientID: string(ctx.QueryArgs().Peek(queryArgClientID))}, pagination); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading consents for user '%s': error occurred loading consent sessions from the storage backend", userSession.Username)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	body := make([]UserActivityConsent, len(consents))
+
+	for i, consent := range consents {
+		body[i] = UserActivityConsent{
+			ClientID:          consent.ClientID,
+			Authorized:        consent.Authorized,
+			PreConfigured:     consent.PreConfiguration.Valid,
+			RequestedAt:       consent.RequestedAt,
+			RespondedAt:       consent.RespondedAt.Time,
+			RequestedScopes:   consent.RequestedScopes,
+			GrantedScopes:     consent.GrantedScopes,
+			RequestedAudience: consent.RequestedAudience,
+			GrantedAudience:   consent.GrantedAudience,
+		}
+
+		if ctx.Providers.OpenIDConnect == nil {
+			continue
+		}
+
+		var client oidc.Client
+
+		// The client may have been removed from the configuration since the consent was given in which case only the
+		// client id is known.
+		if client, err = ctx.Providers.OpenIDConnect.GetRegisteredClient(ctx, consent.ClientID); err == nil && client != nil {
+			body[i].ClientName = client.GetName()
+		}
+	}
+
+	if err = ctx.SetJSONBody(body); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading consents for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// getUserActivitySession returns the session of the current user for the user activity endpoints, setting the error
+// response if the session can't be loaded or is anonymous.
+func getUserActivitySession(ctx *middlewares.AutheliaCtx, activity string) (userSession session.UserSession, err error) {
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred loading %s: %s", activity, errStrUserSessionData)
+
+		ctx.SetJSONError(messageOperationFailed)
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+
+		return userSession, err
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred loading %s", activity)
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return userSession, errUserAnonymous
+	}
+
+	return userSession, nil
+}
@@ -0,0 +1,380 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestUserActivityAuthenticationsGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading authentication history", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleAttempts",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadAuthenticationHistory(mock.Ctx, testUsername, model.AuthenticationAttemptFilter{}, model.Pagination{Limit: paginationLimitDefault}).Return([]model.AuthenticationAttempt{
+					{Time: time.Unix(1700000000, 0).UTC(), Successful: true, Username: testUsername, Type: "1FA", RemoteIP: model.NewNullIP(net.ParseIP("192.168.1.10")), RequestURI: "https://login.example.com/", RequestMethod: fasthttp.MethodPost},
+					{Time: time.Unix(1690000000, 0).UTC(), Banned: true, Username: testUsername, Type: "TOTP", RequestMethod: fasthttp.MethodPost},
+				}, nil)
+			},
+			`{"status":"OK","data":[{"time":"2023-11-14T22:13:20Z","successful":true,"banned":false,"type":"1FA","remote_ip":"192.168.1.10","request_uri":"https://login.example.com/","request_method":"POST"},{"time":"2023-07-22T04:26:40Z","successful":false,"banned":true,"type":"TOTP","request_uri":"","request_method":"POST"}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandlePagination",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgLimit, "5")
+				mock.Ctx.QueryArgs().Set(queryArgPage, "2")
+
+				mock.StorageMock.EXPECT().LoadAuthenticationHistory(mock.Ctx, testUsername, model.AuthenticationAttemptFilter{}, model.Pagination{Limit: 5, Page: 2}).Return([]model.AuthenticationAttempt{}, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleFilterAndOrder",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgType, "TOTP")
+				mock.Ctx.QueryArgs().Set(queryArgOrder, "asc")
+
+				mock.StorageMock.EXPECT().LoadAuthenticationHistory(mock.Ctx, testUsername, model.AuthenticationAttemptFilter{Type: "TOTP"}, model.Pagination{Limit: paginationLimitDefault, Order: model.SortOrderAscending}).Return([]model.AuthenticationAttempt{}, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleBadOrder",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgOrder, "random")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading authentication history for user 'john': error occurred parsing the pagination", "failed to parse the 'order' query argument: the sort order 'random' is unknown and must be one of 'asc' or 'desc'")
+			},
+		},
+		{
+			"ShouldHandleBadLimit",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgLimit, "1000")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading authentication history for user 'john': error occurred parsing the pagination", "the 'limit' query argument must be between 1 and 100 but it's 1000")
+			},
+		},
+		{
+			"ShouldHandleBadPage",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgPage, "abc")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading authentication history for user 'john': error occurred parsing the pagination", "failed to parse the 'page' query argument: unexpected trailing char found. Expecting 0-9")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadAuthenticationHistory(mock.Ctx, testUsername, model.AuthenticationAttemptFilter{}, model.Pagination{Limit: paginationLimitDefault}).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading authentication history for user 'john': error occurred loading authentication attempts from the storage backend", "bad block")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			UserActivityAuthenticationsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestUserActivityDevicesGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading devices", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleNoDevices",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				expectUserActivityDevices(mock, nil, storage.ErrNoTOTPConfiguration, nil, storage.ErrNoWebAuthnCredential, nil, storage.ErrNoDuoDevice)
+			},
+			`{"status":"OK","data":{}}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleDevices",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				expectUserActivityDevices(mock,
+					&model.TOTPConfiguration{CreatedAt: time.Unix(1700000000, 0).UTC(), Username: testUsername, Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("secret")}, nil,
+					[]model.WebAuthnCredential{{ID: 1}}, nil,
+					&model.DuoDevice{ID: 1, Username: testUsername, Device: "ABC123", Method: "push"}, nil,
+				)
+			},
+			"{\"status\":\"OK\",\"data\":{\"totp\":{\"created_at\":\"2023-11-14T22:13:20Z\",\"issuer\":\"Authelia\",\"algorithm\":\"SHA1\",\"digits\":6,\"period\":30},\"webauthn\":[{\"id\":1,\"created_at\":\"0001-01-01T00:00:00Z\",\"rpid\":\"\",\"username\":\"\",\"description\":\"\",\"kid\":\"\",\"attestation_type\":\"\",\"attachment\":\"\",\"transports\":null,\"sign_count\":0,\"clone_warning\":false,\"legacy\":false,\"discoverable\":false,\"present\":false,\"verified\":false,\"backup_eligible\":false,\"backup_state\":false,\"sync_status\":\"device_bound\",\"public_key\":\"\"}],\"duo\":{\"device\":\"ABC123\",\"method\":\"push\"}}}",
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldSkipDisabledMethods",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.Configuration.TOTP.Disable = true
+				mock.Ctx.Configuration.WebAuthn.Disable = true
+				mock.Ctx.Configuration.DuoAPI.Disable = true
+			},
+			`{"status":"OK","data":{}}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleStorageErrorTOTP",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadTOTPConfiguration(mock.Ctx, testUsername).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading devices for user 'john': error occurred loading the TOTP configuration from the storage backend", "bad block")
+			},
+		},
+		{
+			"ShouldHandleStorageErrorDuo",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				expectUserActivityDevices(mock, nil, storage.ErrNoTOTPConfiguration, nil, storage.ErrNoWebAuthnCredential, nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading devices for user 'john': error occurred loading the preferred Duo device from the storage backend", "bad block")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			UserActivityDevicesGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestUserActivityConsentsGET(t *testing.T) {
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading consents", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleConsents",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername, model.OAuth2ConsentSessionFilter{}, model.Pagination{Limit: paginationLimitDefault}).Return([]model.OAuth2ConsentSession{
+					{
+						ClientID:          "example-app",
+						Authorized:        true,
+						RequestedAt:       time.Unix(1700000000, 0).UTC(),
+						RespondedAt:       sql.NullTime{Time: time.Unix(1700000010, 0).UTC(), Valid: true},
+						RequestedScopes:   []string{"openid", "profile"},
+						GrantedScopes:     []string{"openid"},
+						RequestedAudience: []string{},
+						GrantedAudience:   []string{},
+						PreConfiguration:  sql.NullInt64{Int64: 1, Valid: true},
+					},
+				}, nil)
+			},
+			`{"status":"OK","data":[{"client_id":"example-app","authorized":true,"pre_configured":true,"requested_at":"2023-11-14T22:13:20Z","responded_at":"2023-11-14T22:13:30Z","requested_scopes":["openid","profile"],"granted_scopes":["openid"],"requested_audience":[],"granted_audience":[]}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleClientFilter",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgClientID, "example-app")
+				mock.Ctx.QueryArgs().Set(queryArgPage, "1")
+
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername, model.OAuth2ConsentSessionFilter{ClientID: "example-app"}, model.Pagination{Limit: paginationLimitDefault, Page: 1}).Return([]model.OAuth2ConsentSession{}, nil)
+			},
+			`{"status":"OK","data":[]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+		{
+			"ShouldHandleBadLimit",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.Ctx.QueryArgs().Set(queryArgLimit, "0")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading consents for user 'john': error occurred parsing the pagination", "the 'limit' query argument must be between 1 and 100 but it's 0")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setUserActivityTestSession(t, mock)
+
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername, model.OAuth2ConsentSessionFilter{}, model.Pagination{Limit: paginationLimitDefault}).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred loading consents for user 'john': error occurred loading consent sessions from the storage backend", "bad block")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			UserActivityConsentsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func setUserActivityTestSession(t *testing.T, mock *mocks.MockAutheliaCtx) {
+	us, err := mock.Ctx.GetSession()
+
+	require.NoError(t, err)
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, mock.Ctx.SaveSession(us))
+}
+
+func expectUserActivityDevices(mock *mocks.MockAutheliaCtx, totp *model.TOTPConfiguration, errTOTP error, credentials []model.WebAuthnCredential, errWebAuthn error, device *model.DuoDevice, errDuo error) {
+	mock.StorageMock.EXPECT().LoadTOTPConfiguration(mock.Ctx, testUsername).Return(totp, errTOTP)
+	mock.StorageMock.EXPECT().LoadWebAuthnCredentialsByUsername(mock.Ctx, exampleDotCom, testUsername).Return(credentials, errWebAuthn)
+	mock.StorageMock.EXPECT().LoadPreferredDuoDevice(mock.Ctx, testUsername).Return(device, errDuo)
+}
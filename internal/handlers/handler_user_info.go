@@ -8,6 +8,7 @@ import (
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -141,5 +142,7 @@ func MethodPreferencePOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	ctx.AuditEvent(audit.EventTypePreferencesUpdate, audit.ResultSuccess, userSession.Username, map[string]any{"preferred_method": bodyJSON.Method})
+
 	ctx.ReplyOK()
 }
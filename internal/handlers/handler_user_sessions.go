@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// UserSessionsGET returns the active sessions of the current user with the most recent activity first.
+func UserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		sessions    []indexedUserSession
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing sessions: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred listing sessions")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if sessions, err = loadIndexedUserSessions(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing sessions for user '%s': error occurred loading the sessions", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].Session.LastActivity > sessions[j].Session.LastActivity
+	})
+
+	current := getUserSessionID(ctx)
+
+	response := make([]UserSessionResponse, len(sessions))
+
+	for i, s := range sessions {
+		response[i] = UserSessionResponse{
+			ID:                  s.Index.PublicID.String(),
+			Domain:              s.Index.Domain,
+			UserAgent:           s.Index.UserAgent,
+			AuthenticationLevel: s.Session.AuthenticationLevel.String(),
+			CreatedAt:           s.Index.CreatedAt.UTC(),
+			LastActivity:        time.Unix(s.Session.LastActivity, 0).UTC(),
+			Current:             len(current) != 0 && bytes.Equal(s.Index.SessionID, current),
+		}
+
+		if s.Index.RemoteIP.IP != nil {
+			response[i].RemoteIP = s.Index.RemoteIP.IP.String()
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred listing sessions for user '%s': %s", userSession.Username, errStrRespBody)
+	}
+}
+
+// UserSessionDELETE revokes an individual active session of the current user, which signs the user out of the device
+// the session belongs to. The current session can't be revoked this way as the user signs out of it instead.
+func UserSessionDELETE(ctx *middlewares.AutheliaCtx) {
+	var (
+		userSession session.UserSession
+		publicID    uuid.UUID
+		index       *model.UserSessionIndex
+		err         error
+	)
+
+	if userSession, err = ctx.GetSession(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking session: %s", errStrUserSessionData)
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if userSession.IsAnonymous() {
+		ctx.Logger.WithError(errUserAnonymous).Errorf("Error occurred revoking session")
+
+		ctx.SetStatusCode(fasthttp.StatusForbidden)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	value, _ := ctx.UserValue("id").(string)
+
+	if publicID, err = uuid.Parse(value); err != nil {
+		ctx.Logger.WithError(fmt.Errorf("the id '%s' isn't a valid id: %w", value, err)).Errorf("Error occurred revoking session for user '%s'", userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if index, err = ctx.Providers.StorageProvider.LoadUserSessionIndex(ctx, userSession.Username, publicID); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking session with id '%s' for user '%s': error occurred loading the session index from the storage backend", publicID, userSession.Username)
+
+		if errors.Is(err, storage.ErrNoUserSessionIndex) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+		} else {
+			ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		}
+
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if bytes.Equal(index.SessionID, getUserSessionID(ctx)) {
+		ctx.Logger.WithError(fmt.Errorf("the session is the current session")).Errorf("Error occurred revoking session with id '%s' for user '%s'", publicID, userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = revokeIndexedUserSession(ctx, *index); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred revoking session with id '%s' for user '%s'", publicID, userSession.Username)
+
+		ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	ctx.AuditEvent(audit.EventTypeSessionRevoke, audit.ResultSuccess, userSession.Username, map[string]any{"id": publicID.String(), "domain": index.Domain})
+
+	ctx.ReplyOK()
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestUserSessionsGET(t *testing.T) {
+	currentID := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+	otherID := uuid.MustParse("0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3")
+
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			nil,
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred listing sessions", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return(nil, fmt.Errorf("bad block"))
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred listing sessions for user 'john': error occurred loading the sessions", "bad block")
+			},
+		},
+		{
+			"ShouldHandleSessions",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+				other := setUserSessionsTestOtherSession(t, mock, time.Unix(1700000600, 0))
+
+				created := time.Unix(1699990000, 0)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadUserSessionIndexes(mock.Ctx, testUsername).Return([]model.UserSessionIndex{
+						{PublicID: currentID, Username: testUsername, Domain: "example.com", RemoteIP: model.NewNullIP(net.ParseIP("192.168.0.1")), UserAgent: "Mozilla/5.0 (X11; Linux x86_64)", CreatedAt: created, SessionID: id},
+						{PublicID: uuid.MustParse("9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"), Username: testUsername, Domain: "example.com", SessionID: []byte("stale")},
+						{PublicID: otherID, Username: testUsername, Domain: "example.com", UserAgent: "Mozilla/5.0 (iPhone)", CreatedAt: created, SessionID: other},
+					}, nil),
+					mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, []byte("stale")).Return(nil),
+				)
+			},
+			`{"status":"OK","data":[{"id":"0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3","domain":"example.com","remote_ip":"","user_agent":"Mozilla/5.0 (iPhone)","authentication_level":"one_factor","created_at":"2023-11-14T19:26:40Z","last_activity":"2023-11-14T22:23:20Z","current":false},{"id":"5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e","domain":"example.com","remote_ip":"192.168.0.1","user_agent":"Mozilla/5.0 (X11; Linux x86_64)","authentication_level":"one_factor","created_at":"2023-11-14T19:26:40Z","last_activity":"2023-11-14T22:13:20Z","current":true}]}`,
+			fasthttp.StatusOK,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			UserSessionsGET(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+func TestUserSessionDELETE(t *testing.T) {
+	publicID := uuid.MustParse("0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3")
+
+	testCases := []struct {
+		name           string
+		setup          func(t *testing.T, mock *mocks.MockAutheliaCtx)
+		expected       string
+		expectedStatus int
+		expectedf      func(t *testing.T, mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			"ShouldHandleAnonymous",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				mock.Ctx.SetUserValue("id", publicID.String())
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusForbidden,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking session", "user is anonymous")
+			},
+		},
+		{
+			"ShouldHandleInvalidID",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("id", "abc")
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking session for user 'john'", "the id 'abc' isn't a valid id: invalid UUID length: 3")
+			},
+		},
+		{
+			"ShouldHandleNotFound",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(nil, storage.ErrNoUserSessionIndex)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusNotFound,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking session with id '0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3' for user 'john': error occurred loading the session index from the storage backend", "no user session index found")
+			},
+		},
+		{
+			"ShouldNotRevokeCurrentSession",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				id := setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(&model.UserSessionIndex{PublicID: publicID, Username: testUsername, Domain: "example.com", SessionID: id}, nil)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusBadRequest,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking session with id '0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3' for user 'john'", "the session is the current session")
+
+				userSession, err := mock.Ctx.GetSession()
+
+				assert.NoError(t, err)
+				assert.Equal(t, testUsername, userSession.Username)
+			},
+		},
+		{
+			"ShouldHandleRevocationStorageError",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+				other := setUserSessionsTestOtherSession(t, mock, time.Unix(1700000600, 0))
+
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(&model.UserSessionIndex{PublicID: publicID, Username: testUsername, Domain: "example.com", SessionID: other}, nil),
+					mock.StorageMock.EXPECT().SaveUserSessionRevocation(mock.Ctx, other, mock.Clock.Now(), gomock.Any()).Return(fmt.Errorf("bad block")),
+				)
+			},
+			`{"status":"KO","message":"Operation failed."}`,
+			fasthttp.StatusInternalServerError,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				AssertLogEntryMessageAndError(t, mock.Hook.LastEntry(), "Error occurred revoking session with id '0b1d0c5e-3f0a-4b7e-9a61-0bd1b6a6f1a3' for user 'john'", "error occurred saving the session revocation to the storage backend: bad block")
+			},
+		},
+		{
+			"ShouldRevokeSession",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				setAdminTestSession(t, mock, time.Unix(1700000000, 0))
+				other := setUserSessionsTestOtherSession(t, mock, time.Unix(1700000600, 0))
+
+				mock.Ctx.SetUserValue("id", publicID.String())
+
+				mock.Ctx.Providers.Audit = &testUserSessionsAuditProvider{}
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadUserSessionIndex(mock.Ctx, testUsername, publicID).Return(&model.UserSessionIndex{PublicID: publicID, Username: testUsername, Domain: "example.com", SessionID: other}, nil),
+					mock.StorageMock.EXPECT().SaveUserSessionRevocation(mock.Ctx, other, mock.Clock.Now(), gomock.Any()).Return(nil),
+					mock.StorageMock.EXPECT().DeleteUserSessionIndex(mock.Ctx, other).Return(nil),
+				)
+			},
+			`{"status":"OK"}`,
+			fasthttp.StatusOK,
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) {
+				other := []byte(testUserSessionsOtherSessionID)
+
+				_, found, err := mock.Ctx.Providers.SessionProvider.LoadUserSessionByID(other)
+
+				assert.NoError(t, err)
+				assert.False(t, found)
+				assert.True(t, mock.Ctx.Providers.SessionProvider.Revocations().IsRevoked(other))
+				assert.False(t, mock.Ctx.Providers.SessionProvider.Revocations().IsRevoked(getUserSessionID(mock.Ctx)))
+
+				events := mock.Ctx.Providers.Audit.(*testUserSessionsAuditProvider).events
+
+				require.Len(t, events, 1)
+				assert.Equal(t, audit.EventTypeSessionRevoke, events[0].Type)
+				assert.Equal(t, audit.ResultSuccess, events[0].Result)
+				assert.Equal(t, testUsername, events[0].Actor.Username)
+				assert.Equal(t, map[string]any{"id": publicID.String(), "domain": "example.com"}, events[0].Details)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(t, mock)
+			}
+
+			UserSessionDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+
+			if tc.expectedf != nil {
+				tc.expectedf(t, mock)
+			}
+		})
+	}
+}
+
+type testUserSessionsAuditProvider struct {
+	events []audit.Event
+}
+
+func (p *testUserSessionsAuditProvider) Emit(event audit.Event) {
+	p.events = append(p.events, event)
+}
+
+const testUserSessionsOtherSessionID = "JQx8MzPq3WvB7TnL2cYk5RdF9hGs4AeU"
+
+// setUserSessionsTestOtherSession saves a session of the test user which belongs to another device.
+func setUserSessionsTestOtherSession(t *testing.T, mock *mocks.MockAutheliaCtx, lastActivity time.Time) (id []byte) {
+	provider, err := mock.Ctx.Providers.SessionProvider.Get("example.com")
+
+	require.NoError(t, err)
+
+	other := &fasthttp.RequestCtx{}
+	other.Request.Header.SetCookie(provider.Config.Name, testUserSessionsOtherSessionID)
+
+	us := provider.NewDefaultUserSession()
+
+	us.Username = testUsername
+	us.AuthenticationLevel = authentication.OneFactor
+	us.LastActivity = lastActivity.Unix()
+
+	require.NoError(t, provider.SaveSession(other, us))
+
+	return []byte(testUserSessionsOtherSessionID)
+}
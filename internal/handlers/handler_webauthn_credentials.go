@@ -8,6 +8,7 @@ import (
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -200,6 +201,8 @@ func WebAuthnCredentialPUT(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	ctx.AuditEvent(audit.EventTypeWebAuthnUpdate, audit.ResultSuccess, userSession.Username, map[string]any{"description": bodyJSON.Description, "previous_description": credential.Description})
+
 	ctx.ReplyOK()
 }
 
@@ -266,6 +269,8 @@ func WebAuthnCredentialDELETE(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	ctx.AuditEvent(audit.EventTypeWebAuthnDelete, audit.ResultSuccess, userSession.Username, map[string]any{"description": credential.Description})
+
 	ctxLogEvent(ctx, userSession.Username, eventLogAction2FARemoved, map[string]any{eventLogKeyAction: eventLogAction2FARemoved, eventLogKeyCategory: eventLogCategoryWebAuthnCredential, eventLogKeyDescription: credential.Description})
 
 	ctx.ReplyOK()
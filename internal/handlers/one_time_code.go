@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// oneTimeCodeDeliveryFunc delivers the one-time code described by the data to the identity.
+type oneTimeCodeDeliveryFunc func(ctx *middlewares.AutheliaCtx, identity session.Identity, data templates.EmailIdentityVerificationOTCValues) (err error)
+
+var (
+	oneTimeCodeCharacterSets = map[string]string{
+		schema.OneTimeCodeCharacterSetUnambiguous:  random.CharSetUnambiguousUpper,
+		schema.OneTimeCodeCharacterSetAlphaNumeric: random.CharSetAlphabeticUpper + random.CharSetNumeric,
+		schema.OneTimeCodeCharacterSetNumeric:      random.CharSetNumeric,
+	}
+
+	oneTimeCodeDeliveries = map[string]oneTimeCodeDeliveryFunc{
+		schema.OneTimeCodeDeliveryEmail: deliverOneTimeCodeEmail,
+	}
+)
+
+// newOneTimeCodeOptions returns the model.OneTimeCodeOptions for a flow with the given intent from the configuration.
+func newOneTimeCodeOptions(intent string, config schema.IdentityValidationElevatedSession) model.OneTimeCodeOptions {
+	return model.OneTimeCodeOptions{
+		Intent:       intent,
+		Length:       config.Characters,
+		CharacterSet: oneTimeCodeCharacterSets[config.CharacterSet],
+		Lifespan:     config.CodeLifespan,
+	}
+}
+
+// newOneTimeCodeRevocation returns the encoded identifier of the one-time code and the link which revokes it.
+func newOneTimeCodeRevocation(ctx *middlewares.AutheliaCtx, otp *model.OneTimeCode) (id, link string) {
+	id = base64.RawURLEncoding.EncodeToString(otp.PublicID[:])
+
+	linkURL := ctx.RootURL()
+
+	query := linkURL.Query()
+
+	query.Set("id", id)
+
+	linkURL.Path = path.Join(linkURL.Path, "/revoke/one-time-code")
+	linkURL.RawQuery = query.Encode()
+
+	return id, linkURL.String()
+}
+
+// deliverOneTimeCode delivers the one-time code to the identity using the configured delivery channel.
+func deliverOneTimeCode(ctx *middlewares.AutheliaCtx, delivery string, identity session.Identity, data templates.EmailIdentityVerificationOTCValues) (err error) {
+	deliver, ok := oneTimeCodeDeliveries[delivery]
+	if !ok {
+		return fmt.Errorf("the one-time code delivery method '%s' is not supported", delivery)
+	}
+
+	return deliver(ctx, identity, data)
+}
+
+func deliverOneTimeCodeEmail(ctx *middlewares.AutheliaCtx, identity session.Identity, data templates.EmailIdentityVerificationOTCValues) (err error) {
+	return ctx.Providers.Notifier.Send(ctx, identity.Address(), data.Title, ctx.Providers.Templates.GetIdentityVerificationOTCEmailTemplate(), data)
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// getPagination returns the pagination for the list endpoints from the limit, page, and order query arguments.
+func getPagination(ctx *middlewares.AutheliaCtx) (pagination model.Pagination, err error) {
+	args := ctx.QueryArgs()
+
+	pagination.Limit = paginationLimitDefault
+
+	if args.Has(queryArgLimit) {
+		if pagination.Limit, err = args.GetUint(queryArgLimit); err != nil {
+			return pagination, fmt.Errorf("failed to parse the '%s' query argument: %w", queryArgLimit, err)
+		}
+
+		if pagination.Limit < 1 || pagination.Limit > paginationLimitMaximum {
+			return pagination, fmt.Errorf("the '%s' query argument must be between 1 and %d but it's %d", queryArgLimit, paginationLimitMaximum, pagination.Limit)
+		}
+	}
+
+	if args.Has(queryArgPage) {
+		if pagination.Page, err = args.GetUint(queryArgPage); err != nil {
+			return pagination, fmt.Errorf("failed to parse the '%s' query argument: %w", queryArgPage, err)
+		}
+	}
+
+	if pagination.Order, err = model.NewSortOrder(string(args.Peek(queryArgOrder))); err != nil {
+		return pagination, fmt.Errorf("failed to parse the '%s' query argument: %w", queryArgOrder, err)
+	}
+
+	return pagination, nil
+}
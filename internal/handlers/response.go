@@ -9,10 +9,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
@@ -44,9 +46,10 @@ func Handle1FAResponse(ctx *middlewares.AutheliaCtx, targetURI, requestMethod st
 
 	_, requiredLevel := ctx.Providers.Authorizer.GetRequiredLevel(
 		authorization.Subject{
-			Username: username,
-			Groups:   groups,
-			IP:       ctx.RemoteIP(),
+			Username:      username,
+			Groups:        groups,
+			IP:            ctx.RemoteIP(),
+			ManagedDevice: isManagedDevice(ctx, username),
 		},
 		authorization.NewObject(targetURL, requestMethod))
 
@@ -214,7 +217,7 @@ func handleOIDCWorkflowResponseWithID(ctx *middlewares.AutheliaCtx, userSession
 	level := client.GetAuthorizationPolicyRequiredLevel(authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()})
 
 	switch {
-	case authorization.IsAuthLevelSufficient(userSession.AuthenticationLevel, level), level == authorization.Denied:
+	case authorization.IsAuthLevelSufficient(userSession.AuthenticationLevel, level) && client.IsUserVerificationSufficient(userSession.AuthenticationMethodRefs, authorization.Subject{Username: userSession.Username, Groups: userSession.Groups, IP: ctx.RemoteIP()}), level == authorization.Denied:
 		var (
 			targetURL *url.URL
 			form      url.Values
@@ -245,6 +248,12 @@ func handleOIDCWorkflowResponseWithID(ctx *middlewares.AutheliaCtx, userSession
 }
 
 func markAuthenticationAttempt(ctx *middlewares.AutheliaCtx, successful bool, bannedUntil *time.Time, username string, authType string, errAuth error) (err error) {
+	return markAuthenticationAttemptWithMetadata(ctx, successful, bannedUntil, username, authType, errAuth, nil)
+}
+
+// markAuthenticationAttemptWithMetadata is the same as markAuthenticationAttempt but also includes the additional
+// metadata in the audit event.
+func markAuthenticationAttemptWithMetadata(ctx *middlewares.AutheliaCtx, successful bool, bannedUntil *time.Time, username string, authType string, errAuth error, metadata map[string]any) (err error) {
 	// We only Mark if there was no underlying error.
 	ctx.Logger.Debugf("Mark %s authentication attempt made by user '%s'", authType, username)
 
@@ -267,6 +276,21 @@ func markAuthenticationAttempt(ctx *middlewares.AutheliaCtx, successful bool, ba
 		return err
 	}
 
+	eventType := audit.EventTypeAuthenticationSecondFactor
+
+	switch authType {
+	case regulation.AuthType1FA, regulation.AuthTypeSPNEGO, regulation.AuthTypePasskey:
+		eventType = audit.EventTypeAuthenticationFirstFactor
+	}
+
+	event := map[string]any{"method": authType, "banned": bannedUntil != nil}
+
+	for key, value := range metadata {
+		event[key] = value
+	}
+
+	ctx.AuditEvent(eventType, audit.NewResult(successful), username, event)
+
 	if successful {
 		ctx.Logger.Debugf("Successful %s authentication attempt made by user '%s'", authType, username)
 	} else {
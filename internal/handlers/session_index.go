@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// indexedUserSession is a user session along with the user session index used to find it.
+type indexedUserSession struct {
+	Index   model.UserSessionIndex
+	Session session.UserSession
+}
+
+// isUserSessionIndexEnabled returns true if the sessions of the users should be indexed, which is only necessary when
+// the admin API or the list of the user sessions in the portal which enumerate them are enabled.
+func isUserSessionIndexEnabled(ctx *middlewares.AutheliaCtx) bool {
+	return (ctx.Configuration.Server.Admin.Enabled && ctx.Configuration.Server.Admin.EnableAPI) || ctx.Configuration.Session.EnableUserSessions
+}
+
+// indexUserSession adds the current session of the request to the user session index, and removes the entries of the
+// sessions of the user which no longer exist. This must be called after the session has been saved with the username.
+// Errors are only logged as the index is not required to authenticate the user.
+func indexUserSession(ctx *middlewares.AutheliaCtx, userSession session.UserSession) {
+	if !isUserSessionIndexEnabled(ctx) || userSession.IsAnonymous() {
+		return
+	}
+
+	var (
+		provider *session.Session
+		publicID uuid.UUID
+		err      error
+	)
+
+	if provider, err = ctx.GetSessionProvider(); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred indexing the session for user '%s': error occurred retrieving the session provider", userSession.Username)
+
+		return
+	}
+
+	id := provider.GetSessionID(ctx.RequestCtx)
+
+	if len(id) == 0 {
+		ctx.Logger.WithError(fmt.Errorf("the session id wasn't set")).Errorf("Error occurred indexing the session for user '%s'", userSession.Username)
+
+		return
+	}
+
+	if _, err = loadIndexedUserSessions(ctx, userSession.Username); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred indexing the session for user '%s': error occurred removing the sessions which no longer exist", userSession.Username)
+	}
+
+	if publicID, err = uuid.NewRandomFromReader(ctx.Providers.Random); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred indexing the session for user '%s': error occurred generating the public id", userSession.Username)
+
+		return
+	}
+
+	index := model.UserSessionIndex{
+		PublicID:  publicID,
+		Username:  userSession.Username,
+		Domain:    provider.Config.Domain,
+		RemoteIP:  model.NewNullIP(ctx.RemoteIP()),
+		UserAgent: userSessionIndexUserAgent(ctx),
+		CreatedAt: ctx.Clock.Now(),
+		SessionID: bytes.Clone(id),
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveUserSessionIndex(ctx, index); err != nil {
+		ctx.Logger.WithError(err).Errorf("Error occurred indexing the session for user '%s': error occurred saving the index to the storage backend", userSession.Username)
+	}
+}
+
+// userSessionIndexUserAgent returns the user agent of the request truncated to the length of the user agent column of
+// the user session index.
+func userSessionIndexUserAgent(ctx *middlewares.AutheliaCtx) string {
+	userAgent := string(ctx.UserAgent())
+
+	if len(userAgent) > userSessionIndexUserAgentMaxLength {
+		userAgent = strings.ToValidUTF8(userAgent[:userSessionIndexUserAgentMaxLength], "")
+	}
+
+	return userAgent
+}
+
+// unindexUserSession removes the session with the given session id from the user session index.
+func unindexUserSession(ctx *middlewares.AutheliaCtx, id []byte) {
+	if !isUserSessionIndexEnabled(ctx) || len(id) == 0 {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.DeleteUserSessionIndex(ctx, id); err != nil {
+		ctx.Logger.WithError(err).Error("Error occurred removing the session from the index: error occurred deleting the index from the storage backend")
+	}
+}
+
+// getUserSessionID returns the session id of the current session of the request.
+func getUserSessionID(ctx *middlewares.AutheliaCtx) (id []byte) {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return nil
+	}
+
+	return bytes.Clone(provider.GetSessionID(ctx.RequestCtx))
+}
+
+// loadIndexedUserSessions loads the sessions of a user from the session provider using the user session index. The
+// entries of the index which refer to a session which no longer exists, or which no longer belongs to the user, are
+// removed from the index.
+func loadIndexedUserSessions(ctx *middlewares.AutheliaCtx, username string) (sessions []indexedUserSession, err error) {
+	var indexes []model.UserSessionIndex
+
+	if indexes, err = ctx.Providers.StorageProvider.LoadUserSessionIndexes(ctx, username); err != nil {
+		return nil, err
+	}
+
+	sessions = make([]indexedUserSession, 0, len(indexes))
+
+	for _, index := range indexes {
+		userSession, found, err := ctx.Providers.SessionProvider.LoadUserSessionByID(index.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("error occurred loading the session with id '%s': %w", index.PublicID, err)
+		}
+
+		if !found || userSession.Username != username {
+			if err = ctx.Providers.StorageProvider.DeleteUserSessionIndex(ctx, index.SessionID); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		sessions = append(sessions, indexedUserSession{Index: index, Session: userSession})
+	}
+
+	return sessions, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/url"
+	"time"
 
 	oauthelia2 "authelia.com/provider/oauth2"
 	"github.com/google/uuid"
@@ -23,6 +24,19 @@ type configurationBody struct {
 	AvailableMethods MethodList `json:"available_methods"`
 }
 
+// configurationBrandingBody the content returned by the configuration branding endpoint.
+type configurationBrandingBody struct {
+	Title          string `json:"title,omitempty"`
+	LogoURL        string `json:"logo_url,omitempty"`
+	Announcement   string `json:"announcement,omitempty"`
+	SupportContact string `json:"support_contact,omitempty"`
+}
+
+// configurationFeaturesBody the content returned by the configuration features endpoint.
+type configurationFeaturesBody struct {
+	Features middlewares.Features `json:"features"`
+}
+
 // bodySignTOTPRequest is the  model of the request body of TOTP 2FA authentication endpoint.
 type bodySignTOTPRequest struct {
 	Token      string `json:"token" valid:"required"`
@@ -31,6 +45,55 @@ type bodySignTOTPRequest struct {
 	WorkflowID string `json:"workflowID"`
 }
 
+// bodySignRecoveryCodeRequest is the model of the request body of the recovery code 2FA authentication endpoint.
+type bodySignRecoveryCodeRequest struct {
+	Code       string `json:"code" valid:"required"`
+	TargetURL  string `json:"targetURL"`
+	Workflow   string `json:"workflow"`
+	WorkflowID string `json:"workflowID"`
+}
+
+// bodySignSMSStartRequest is the model of the request body of the SMS 2FA endpoint which sends the One-Time Code.
+type bodySignSMSStartRequest struct {
+	Voice bool `json:"voice"`
+}
+
+// bodySignSMSRequest is the model of the request body of the SMS 2FA authentication endpoint.
+type bodySignSMSRequest struct {
+	Code       string `json:"code" valid:"required"`
+	Voice      bool   `json:"voice"`
+	TargetURL  string `json:"targetURL"`
+	Workflow   string `json:"workflow"`
+	WorkflowID string `json:"workflowID"`
+}
+
+// bodySignEmailStartRequest is the model of the request body of the Email 2FA endpoint which sends the One-Time Code.
+// The target URL and workflow are only used by the magic link.
+type bodySignEmailStartRequest struct {
+	TargetURL  string `json:"targetURL"`
+	Workflow   string `json:"workflow"`
+	WorkflowID string `json:"workflowID"`
+}
+
+// bodySignEmailRequest is the model of the request body of the Email 2FA authentication endpoint.
+type bodySignEmailRequest struct {
+	Code       string `json:"code" valid:"required"`
+	TargetURL  string `json:"targetURL"`
+	Workflow   string `json:"workflow"`
+	WorkflowID string `json:"workflowID"`
+}
+
+// bodyRegisterSMS is the model of the request body of the SMS registration endpoint which sends the One-Time Code.
+type bodyRegisterSMS struct {
+	PhoneNumber string `json:"phone_number"`
+}
+
+// bodyRegisterFinishSMS is the model of the request body of the SMS registration endpoint which verifies the One-Time
+// Code.
+type bodyRegisterFinishSMS struct {
+	Code string `json:"code" valid:"required"`
+}
+
 type bodyRegisterTOTP struct {
 	Algorithm string `json:"algorithm"`
 	Length    int    `json:"length"`
@@ -77,6 +140,58 @@ type bodyEditWebAuthnCredentialRequest struct {
 	Description string `json:"description"`
 }
 
+// bodyIssueDeviceCertificateRequest is the model of the request body of the Device Certificates POST endpoint.
+type bodyIssueDeviceCertificateRequest struct {
+	Name                      string `json:"name"`
+	CertificateSigningRequest string `json:"csr"`
+}
+
+// bodyCreateAppPasswordRequest is the model of the request body of the App Passwords POST endpoint.
+type bodyCreateAppPasswordRequest struct {
+	Name    string   `json:"name"`
+	Domains []string `json:"domains"`
+}
+
+// bodyMobileAuthenticateRequest is the model of the request body of the Mobile Authenticate POST endpoint.
+type bodyMobileAuthenticateRequest struct {
+	Username   string `json:"username" valid:"required"`
+	Password   string `json:"password" valid:"required"`
+	Token      string `json:"token" valid:"required"`
+	DeviceName string `json:"device_name" valid:"required"`
+	Platform   string `json:"platform" valid:"required"`
+}
+
+// bodyMobileTokenRequest is the model of the request body of the Mobile Token POST endpoint.
+type bodyMobileTokenRequest struct {
+	DeviceID     string `json:"device_id" valid:"required"`
+	RefreshToken string `json:"refresh_token" valid:"required"`
+}
+
+// bodyMobileDevicePushRequest is the model of the request body of the Mobile Device Push PUT endpoint.
+type bodyMobileDevicePushRequest struct {
+	Provider string `json:"provider" valid:"required"`
+	Token    string `json:"token" valid:"required"`
+}
+
+// mobileTokenResponse is the model of the response body of the endpoints which issue tokens to mobile devices.
+type mobileTokenResponse struct {
+	DeviceID              uuid.UUID `json:"device_id"`
+	TokenType             string    `json:"token_type"`
+	AccessToken           string    `json:"access_token"`
+	ExpiresIn             int       `json:"expires_in"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresIn int       `json:"refresh_token_expires_in"`
+}
+
+// mobileUserInfoResponse is the model of the response body of the Mobile User Info GET endpoint.
+type mobileUserInfoResponse struct {
+	DeviceID    uuid.UUID `json:"device_id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Emails      []string  `json:"emails"`
+	Groups      []string  `json:"groups"`
+}
+
 // bodySignDuoRequest is the  model of the request body of Duo 2FA authentication endpoint.
 type bodySignDuoRequest struct {
 	TargetURL  string `json:"targetURL"`
@@ -85,6 +200,13 @@ type bodySignDuoRequest struct {
 	WorkflowID string `json:"workflowID"`
 }
 
+// bodyPushChallengeRequest is the model of the request body of the endpoints which respond to a push challenge.
+type bodyPushChallengeRequest struct {
+	ID      string `json:"id" valid:"required"`
+	Token   string `json:"token" valid:"required"`
+	Approve bool   `json:"approve"`
+}
+
 // bodyPreferred2FAMethod the selected 2FA method.
 type bodyPreferred2FAMethod struct {
 	Method string `json:"method" valid:"required"`
@@ -103,6 +225,26 @@ type bodyFirstFactorRequest struct {
 	// TODO(c.michaud): add required validation once the above PR is merged.
 }
 
+// bodyFirstFactorSPNEGORequest represents the JSON body received by the SPNEGO first factor endpoint.
+type bodyFirstFactorSPNEGORequest struct {
+	TargetURL      string `json:"targetURL"`
+	Workflow       string `json:"workflow"`
+	WorkflowID     string `json:"workflowID"`
+	RequestMethod  string `json:"requestMethod"`
+	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn"`
+}
+
+// bodyFirstFactorPasskeyRequest represents the JSON body received by the passkey first factor endpoint.
+type bodyFirstFactorPasskeyRequest struct {
+	TargetURL      string `json:"targetURL"`
+	Workflow       string `json:"workflow"`
+	WorkflowID     string `json:"workflowID"`
+	RequestMethod  string `json:"requestMethod"`
+	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn"`
+
+	Response json.RawMessage `json:"response"`
+}
+
 // checkURIWithinDomainRequestBody represents the JSON body received by the endpoint checking if an URI is within
 // the configured domain.
 type checkURIWithinDomainRequestBody struct {
@@ -125,6 +267,51 @@ type TOTPKeyResponse struct {
 	OTPAuthURL   string `json:"otpauth_url"`
 }
 
+// RecoveryCodesResponse is the model of the response which describes the recovery codes of a user. The codes are only
+// included when they were generated by the request.
+type RecoveryCodesResponse struct {
+	Remaining int      `json:"remaining"`
+	Codes     []string `json:"codes,omitempty"`
+}
+
+// DeviceCertificateResponse is the model of the response sent when a client certificate is issued to a device.
+type DeviceCertificateResponse struct {
+	Device      *model.DeviceCertificate `json:"device"`
+	Certificate string                   `json:"certificate"`
+}
+
+// AppPasswordResponse is the model of the response sent when an app password is generated.
+type AppPasswordResponse struct {
+	AppPassword model.AppPassword `json:"app_password"`
+	Password    string            `json:"password"`
+}
+
+// AdminUserSessionResponse is the model of an active session of a user returned by the admin API.
+type AdminUserSessionResponse struct {
+	ID                  string    `json:"id"`
+	Domain              string    `json:"domain"`
+	RemoteIP            string    `json:"remote_ip"`
+	AuthenticationLevel string    `json:"authentication_level"`
+	LastActivity        time.Time `json:"last_activity"`
+}
+
+// UserSessionResponse is the model of an active session of the current user.
+type UserSessionResponse struct {
+	ID                  string    `json:"id"`
+	Domain              string    `json:"domain"`
+	RemoteIP            string    `json:"remote_ip"`
+	UserAgent           string    `json:"user_agent"`
+	AuthenticationLevel string    `json:"authentication_level"`
+	CreatedAt           time.Time `json:"created_at"`
+	LastActivity        time.Time `json:"last_activity"`
+	Current             bool      `json:"current"`
+}
+
+// AdminUserSessionsRevokeResponse is the model of the response sent when the admin API revokes the sessions of a user.
+type AdminUserSessionsRevokeResponse struct {
+	Revoked int `json:"revoked"`
+}
+
 // DuoDeviceBody the selected Duo device and method.
 type DuoDeviceBody struct {
 	Device string `json:"device" valid:"required"`
@@ -158,6 +345,12 @@ type StateResponse struct {
 	Username              string               `json:"username"`
 	AuthenticationLevel   authentication.Level `json:"authentication_level"`
 	DefaultRedirectionURL string               `json:"default_redirection_url"`
+	WebAuthnUserVerified  bool                 `json:"webauthn_user_verified"`
+}
+
+// CSRFTokenResponse represents the response sent by the CSRF token endpoint.
+type CSRFTokenResponse struct {
+	Token string `json:"token"`
 }
 
 // resetPasswordStep1RequestBody model of the reset password (step1) request body.
@@ -180,12 +373,53 @@ type PasswordPolicyBody struct {
 	MinLength        int    `json:"min_length"`
 	MaxLength        int    `json:"max_length"`
 	MinScore         int    `json:"min_score"`
+	MinEntropy       int    `json:"min_entropy"`
 	RequireUppercase bool   `json:"require_uppercase"`
 	RequireLowercase bool   `json:"require_lowercase"`
 	RequireNumber    bool   `json:"require_number"`
 	RequireSpecial   bool   `json:"require_special"`
 }
 
+// UserActivityAuthentication represents an authentication attempt in the response sent by the user activity
+// authentications endpoint.
+type UserActivityAuthentication struct {
+	Time          time.Time `json:"time"`
+	Successful    bool      `json:"successful"`
+	Banned        bool      `json:"banned"`
+	Type          string    `json:"type"`
+	RemoteIP      string    `json:"remote_ip,omitempty"`
+	RequestURI    string    `json:"request_uri"`
+	RequestMethod string    `json:"request_method"`
+}
+
+// UserActivityDevices represents the response sent by the user activity devices endpoint. The secrets of the devices
+// are never included.
+type UserActivityDevices struct {
+	TOTP     *model.TOTPConfiguration   `json:"totp,omitempty"`
+	WebAuthn []model.WebAuthnCredential `json:"webauthn,omitempty"`
+	Duo      *UserActivityDuoDevice     `json:"duo,omitempty"`
+}
+
+// UserActivityDuoDevice represents the preferred Duo device in the response sent by the user activity devices endpoint.
+type UserActivityDuoDevice struct {
+	Device string `json:"device"`
+	Method string `json:"method"`
+}
+
+// UserActivityConsent represents a consent response in the response sent by the user activity consents endpoint.
+type UserActivityConsent struct {
+	ClientID          string    `json:"client_id"`
+	ClientName        string    `json:"client_name,omitempty"`
+	Authorized        bool      `json:"authorized"`
+	PreConfigured     bool      `json:"pre_configured"`
+	RequestedAt       time.Time `json:"requested_at"`
+	RespondedAt       time.Time `json:"responded_at"`
+	RequestedScopes   []string  `json:"requested_scopes"`
+	GrantedScopes     []string  `json:"granted_scopes"`
+	RequestedAudience []string  `json:"requested_audience"`
+	GrantedAudience   []string  `json:"granted_audience"`
+}
+
 type handlerAuthorizationConsent func(
 	ctx *middlewares.AutheliaCtx, issuer *url.URL, client oidc.Client,
 	userSession session.UserSession, subject uuid.UUID,
@@ -16,8 +16,13 @@ const (
 	eventLogAction2FAAdded   = "Second Factor Method Added"
 	eventLogAction2FARemoved = "Second Factor Method Removed"
 
+	eventLogActionRecoveryCodesGenerated = "Recovery Codes Generated"
+	eventLogActionRecoveryCodeUsed       = "Recovery Code Used"
+
 	eventLogCategoryOneTimePassword    = "One-Time Password"
 	eventLogCategoryWebAuthnCredential = "WebAuthn Credential" //nolint:gosec
+	eventLogCategoryRecoveryCodes      = "Recovery Codes"
+	eventLogCategoryPhoneNumber        = "Phone Number"
 )
 
 func ctxLogEvent(ctx *middlewares.AutheliaCtx, username, description string, eventDetails map[string]any) {
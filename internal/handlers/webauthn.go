@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/go-webauthn/webauthn/protocol"
@@ -12,6 +13,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 const (
@@ -73,6 +75,22 @@ func handleGetWebAuthnUserByRPID(ctx *middlewares.AutheliaCtx, username, display
 	return user, nil
 }
 
+// handleWebAuthnUserFilterCredentials removes the credentials which the filtering policy for the groups of the user
+// prohibits from the WebAuthnUser, returning the number of credentials which were removed.
+func handleWebAuthnUserFilterCredentials(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, user *model.WebAuthnUser) (removed int) {
+	if !ctx.Configuration.WebAuthn.Filtering.ProhibitBackupEligibilityForGroups(userSession.Groups) {
+		return 0
+	}
+
+	n := len(user.Credentials)
+
+	user.Credentials = slices.DeleteFunc(user.Credentials, func(credential model.WebAuthnCredential) bool {
+		return credential.BackupEligible
+	})
+
+	return n - len(user.Credentials)
+}
+
 func handleNewWebAuthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err error) {
 	var (
 		origin *url.URL
@@ -88,9 +106,9 @@ func handleNewWebAuthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err
 		RPOrigins:             []string{origin.String()},
 		AttestationPreference: ctx.Configuration.WebAuthn.ConveyancePreference,
 		AuthenticatorSelection: protocol.AuthenticatorSelection{
-			AuthenticatorAttachment: protocol.CrossPlatform,
+			AuthenticatorAttachment: ctx.Configuration.WebAuthn.SelectionCriteria.Attachment,
 			RequireResidentKey:      protocol.ResidentKeyNotRequired(),
-			ResidentKey:             protocol.ResidentKeyRequirementDiscouraged,
+			ResidentKey:             ctx.Configuration.WebAuthn.SelectionCriteria.Discoverability,
 			UserVerification:        ctx.Configuration.WebAuthn.UserVerification,
 		},
 		Debug:                false,
@@ -0,0 +1,15 @@
+package health
+
+const (
+	// StatusUp indicates the dependency or all dependencies are healthy.
+	StatusUp = "up"
+
+	// StatusDown indicates the dependency or a critical dependency is not healthy.
+	StatusDown = "down"
+
+	// StatusDegraded indicates a non-critical dependency is not healthy.
+	StatusDegraded = "degraded"
+
+	// StatusUnknown indicates the dependency has not been checked.
+	StatusUnknown = "unknown"
+)
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewProvider returns a new health Provider. The results of the checks are cached for the cache duration, and each
+// check must complete within the timeout or it's considered a failure.
+func NewProvider(clock clock.Provider, cache, timeout time.Duration) (provider *Provider) {
+	return &Provider{
+		clock:   clock,
+		cache:   cache,
+		timeout: timeout,
+	}
+}
+
+// Provider performs the health checks of the registered dependencies.
+type Provider struct {
+	clock   clock.Provider
+	cache   time.Duration
+	timeout time.Duration
+
+	// refresh ensures only one run of the checks is performed at a time, the concurrent reports wait for it instead of
+	// starting another run. The mu only guards the checks and the cached results so the reports of the cached results
+	// are never blocked by a run.
+	refresh sync.Mutex
+
+	mu      sync.RWMutex
+	checks  []*check
+	checked time.Time
+}
+
+type check struct {
+	name     string
+	critical bool
+	checker  model.HealthCheck
+
+	state checkState
+}
+
+type checkState struct {
+	status    string
+	latency   time.Duration
+	lastError string
+	errorAt   *time.Time
+	successAt *time.Time
+}
+
+// Register a dependency to be checked. A failure of a critical dependency results in the provider not being ready.
+func (p *Provider) Register(name string, critical bool, checker model.HealthCheck) {
+	if checker == nil {
+		return
+	}
+
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	p.checks = append(p.checks, &check{name: name, critical: critical, checker: checker, state: checkState{status: StatusUnknown}})
+}
+
+// Report performs the health checks if the cached results have expired and returns a report of the results.
+func (p *Provider) Report(ctx context.Context) (report Report) {
+	if p.expired() {
+		p.refresh.Lock()
+
+		// The results may have been refreshed by another report while waiting.
+		if p.expired() {
+			p.run(ctx)
+		}
+
+		p.refresh.Unlock()
+	}
+
+	p.mu.RLock()
+
+	defer p.mu.RUnlock()
+
+	report = Report{
+		Status:    StatusUp,
+		Timestamp: p.checked,
+		Checks:    make([]CheckResult, len(p.checks)),
+	}
+
+	for i, c := range p.checks {
+		report.Checks[i] = CheckResult{
+			Name:          c.name,
+			Status:        c.state.status,
+			Critical:      c.critical,
+			Latency:       Duration(c.state.latency),
+			LastError:     c.state.lastError,
+			LastErrorAt:   c.state.errorAt,
+			LastSuccessAt: c.state.successAt,
+		}
+
+		if c.state.status == StatusUp {
+			continue
+		}
+
+		if c.critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	return report
+}
+
+// Ready returns true if none of the critical dependencies are failing.
+func (p *Provider) Ready(ctx context.Context) bool {
+	return p.Report(ctx).Status != StatusDown
+}
+
+func (p *Provider) expired() bool {
+	p.mu.RLock()
+
+	defer p.mu.RUnlock()
+
+	return p.checked.IsZero() || p.clock.Now().Sub(p.checked) >= p.cache
+}
+
+// run performs the checks without holding the lock and swaps the cached results once all of them have completed.
+func (p *Provider) run(ctx context.Context) {
+	now := p.clock.Now()
+
+	p.mu.RLock()
+
+	checks := make([]*check, len(p.checks))
+	states := make([]checkState, len(p.checks))
+
+	copy(checks, p.checks)
+
+	for i, c := range checks {
+		states[i] = c.state
+	}
+
+	p.mu.RUnlock()
+
+	wg := &sync.WaitGroup{}
+
+	for i, c := range checks {
+		wg.Add(1)
+
+		go func(checker model.HealthCheck, state *checkState) {
+			defer wg.Done()
+
+			p.do(ctx, checker, state)
+		}(c.checker, &states[i])
+	}
+
+	wg.Wait()
+
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	for i, c := range checks {
+		c.state = states[i]
+	}
+
+	p.checked = now
+}
+
+func (p *Provider) do(ctx context.Context, checker model.HealthCheck, state *checkState) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+
+	defer cancel()
+
+	started := p.clock.Now()
+
+	// Not every check honors the context, so the check is abandoned once the context is done. The channel is buffered
+	// so an abandoned check can still complete.
+	result := make(chan error, 1)
+
+	go func() {
+		result <- checker.HealthCheck(ctx)
+	}()
+
+	var err error
+
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	finished := p.clock.Now()
+
+	state.latency = finished.Sub(started)
+
+	if err != nil {
+		state.status, state.lastError, state.errorAt = StatusDown, err.Error(), &finished
+
+		return
+	}
+
+	state.status, state.successAt = StatusUp, &finished
+}
@@ -0,0 +1,162 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestProviderReport(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+
+	provider := NewProvider(c, time.Second*10, time.Second)
+
+	var (
+		storageErr, smtpErr error
+		calls               int
+	)
+
+	provider.Register("storage", true, CheckFunc(func(ctx context.Context) error {
+		calls++
+
+		return storageErr
+	}))
+	provider.Register("smtp", false, CheckFunc(func(ctx context.Context) error {
+		return smtpErr
+	}))
+	provider.Register("none", false, nil)
+
+	report := provider.Report(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, now, report.Timestamp)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, "storage", report.Checks[0].Name)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.True(t, report.Checks[0].Critical)
+	assert.Equal(t, &now, report.Checks[0].LastSuccessAt)
+	assert.Nil(t, report.Checks[0].LastErrorAt)
+	assert.True(t, provider.Ready(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	storageErr, smtpErr = errors.New("database is locked"), errors.New("connection refused")
+
+	report = provider.Report(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, 1, calls)
+
+	c.Set(now.Add(time.Second * 5))
+
+	report = provider.Report(context.Background())
+
+	assert.Equal(t, StatusUp, report.Status)
+	assert.Equal(t, 1, calls)
+
+	later := now.Add(time.Second * 10)
+
+	c.Set(later)
+
+	storageErr = nil
+
+	report = provider.Report(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, StatusDown, report.Checks[1].Status)
+	assert.Equal(t, "connection refused", report.Checks[1].LastError)
+	assert.Equal(t, &later, report.Checks[1].LastErrorAt)
+	assert.True(t, provider.Ready(context.Background()))
+
+	c.Set(later.Add(time.Second * 10))
+
+	storageErr = errors.New("database is locked")
+
+	report = provider.Report(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "database is locked", report.Checks[0].LastError)
+	assert.Equal(t, &later, report.Checks[0].LastSuccessAt)
+	assert.False(t, provider.Ready(context.Background()))
+}
+
+func TestProviderReportTimeout(t *testing.T) {
+	provider := NewProvider(clock.New(), time.Second, time.Millisecond)
+
+	provider.Register("ldap", true, CheckFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}))
+
+	report := provider.Report(context.Background())
+
+	assert.Equal(t, StatusDown, report.Status)
+	assert.Equal(t, "context deadline exceeded", report.Checks[0].LastError)
+}
+
+func TestProviderReportTimeoutIgnoredContext(t *testing.T) {
+	provider := NewProvider(clock.New(), time.Second, time.Millisecond*10)
+
+	done := make(chan struct{})
+
+	defer close(done)
+
+	provider.Register("ntp", false, CheckFunc(func(ctx context.Context) error {
+		<-done
+
+		return nil
+	}))
+
+	report := provider.Report(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, "context deadline exceeded", report.Checks[0].LastError)
+}
+
+func TestProviderReportDoesNotBlockOnRun(t *testing.T) {
+	provider := NewProvider(clock.New(), time.Hour, time.Second*10)
+
+	started, done := make(chan struct{}), make(chan struct{})
+
+	provider.Register("storage", true, CheckFunc(func(ctx context.Context) error {
+		close(started)
+
+		<-done
+
+		return nil
+	}))
+
+	go provider.Report(context.Background())
+
+	<-started
+
+	provider.Register("smtp", false, CheckFunc(func(ctx context.Context) error {
+		return nil
+	}))
+
+	close(done)
+
+	report := provider.Report(context.Background())
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	require.Len(t, report.Checks, 2)
+	assert.Equal(t, StatusUp, report.Checks[0].Status)
+	assert.Equal(t, StatusUnknown, report.Checks[1].Status)
+}
+
+func TestDurationMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(CheckResult{Name: "storage", Status: StatusUp, Latency: Duration(time.Microsecond * 1500)})
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"storage","status":"up","critical":false,"latency":1.500}`, string(data))
+}
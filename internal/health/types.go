@@ -0,0 +1,41 @@
+package health
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Report is the result of the health checks.
+type Report struct {
+	Status    string        `json:"status"`
+	Timestamp time.Time     `json:"timestamp"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// CheckResult is the result of a single dependency health check.
+type CheckResult struct {
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Critical      bool       `json:"critical"`
+	Latency       Duration   `json:"latency"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+}
+
+// Duration is a time.Duration which is encoded as a number of milliseconds in JSON.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(time.Duration(d))/float64(time.Millisecond), 'f', 3, 64)), nil
+}
+
+// CheckFunc is a function which implements model.HealthCheck.
+type CheckFunc func(ctx context.Context) (err error)
+
+// HealthCheck implements model.HealthCheck.
+func (f CheckFunc) HealthCheck(ctx context.Context) (err error) {
+	return f(ctx)
+}
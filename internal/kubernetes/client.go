@@ -0,0 +1,236 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewInClusterClient returns a new Client which uses the service account of the pod to communicate with the
+// Kubernetes API.
+func NewInClusterClient(trusted *x509.CertPool) (client *Client, err error) {
+	host, port := os.Getenv(envServiceHost), os.Getenv(envServicePort)
+
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("error determining the kubernetes api address: the environment variables '%s' and '%s' must be set", envServiceHost, envServicePort)
+	}
+
+	var pool *x509.CertPool
+
+	if trusted != nil {
+		pool = trusted.Clone()
+	} else {
+		pool = x509.NewCertPool()
+	}
+
+	var ca []byte
+
+	if ca, err = os.ReadFile(pathServiceAccountCA); err == nil {
+		pool.AppendCertsFromPEM(ca)
+	}
+
+	return NewClient(&url.URL{Scheme: "https", Host: net.JoinHostPort(host, port)}, pathServiceAccountToken, pool), nil
+}
+
+// NewClient returns a new Client for the Kubernetes API at the provided address. The token is read from the token
+// path on every request as the service account tokens are rotated.
+func NewClient(address *url.URL, tokenPath string, pool *x509.CertPool) (client *Client) {
+	return &Client{
+		address:   address,
+		tokenPath: tokenPath,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					RootCAs:    pool,
+					MinVersion: tls.VersionTLS12,
+				},
+			},
+		},
+	}
+}
+
+// Client is a minimal client for the Kubernetes API which lists and watches the resources used to synthesize rules.
+type Client struct {
+	address   *url.URL
+	tokenPath string
+	client    *http.Client
+}
+
+// ListIngresses lists the Ingress resources in the namespace, or all namespaces if the namespace is empty, and returns
+// the resource version of the list.
+func (c *Client) ListIngresses(ctx context.Context, namespace string) (resources []Resource, version string, err error) {
+	var items []ingress
+
+	if items, version, err = list[ingress](ctx, c, apiGroupIngress, namespace, resourceIngresses); err != nil {
+		return nil, "", err
+	}
+
+	for _, item := range items {
+		resources = append(resources, item.resource())
+	}
+
+	return resources, version, nil
+}
+
+// ListHTTPRoutes lists the Gateway API HTTPRoute resources in the namespace, or all namespaces if the namespace is
+// empty, and returns the resource version of the list.
+func (c *Client) ListHTTPRoutes(ctx context.Context, namespace string) (resources []Resource, version string, err error) {
+	var items []httpRoute
+
+	if items, version, err = list[httpRoute](ctx, c, apiGroupHTTPRoute, namespace, resourceHTTPRoutes); err != nil {
+		return nil, "", err
+	}
+
+	for _, item := range items {
+		resources = append(resources, item.resource())
+	}
+
+	return resources, version, nil
+}
+
+// WatchIngresses watches the Ingress resources in the namespace, or all namespaces if the namespace is empty, which
+// changed after the resource version. It returns once a resource has changed or the watch has ended, which is at the
+// latest after the timeout.
+func (c *Client) WatchIngresses(ctx context.Context, namespace, version string, timeout time.Duration) (err error) {
+	return c.watch(ctx, apiGroupIngress, namespace, resourceIngresses, version, timeout)
+}
+
+// WatchHTTPRoutes watches the Gateway API HTTPRoute resources in the namespace, or all namespaces if the namespace is
+// empty, which changed after the resource version. It returns once a resource has changed or the watch has ended,
+// which is at the latest after the timeout.
+func (c *Client) WatchHTTPRoutes(ctx context.Context, namespace, version string, timeout time.Duration) (err error) {
+	return c.watch(ctx, apiGroupHTTPRoute, namespace, resourceHTTPRoutes, version, timeout)
+}
+
+func list[T any](ctx context.Context, c *Client, group, namespace, resource string) (items []T, version string, err error) {
+	u := resourceURL(c.address, group, namespace, resource)
+
+	query := url.Values{"limit": []string{listLimit}}
+
+	for {
+		u.RawQuery = query.Encode()
+
+		var page objectList[T]
+
+		if err = c.get(ctx, u, requestTimeout, func(body io.Reader) (err error) {
+			if err = json.NewDecoder(body).Decode(&page); err != nil {
+				return fmt.Errorf("error decoding response from the kubernetes api: %w", err)
+			}
+
+			return nil
+		}); err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, page.Items...)
+
+		if page.Metadata.Continue == "" {
+			return items, page.Metadata.ResourceVersion, nil
+		}
+
+		query.Set("continue", page.Metadata.Continue)
+	}
+}
+
+func (c *Client) watch(ctx context.Context, group, namespace, resource, version string, timeout time.Duration) (err error) {
+	u := resourceURL(c.address, group, namespace, resource)
+
+	u.RawQuery = url.Values{
+		"watch":           []string{"true"},
+		"resourceVersion": []string{version},
+		"timeoutSeconds":  []string{strconv.Itoa(int(timeout.Seconds()))},
+	}.Encode()
+
+	return c.get(ctx, u, timeout+requestTimeout, func(body io.Reader) (err error) {
+		decoder := json.NewDecoder(body)
+
+		for {
+			var e event
+
+			if err = decoder.Decode(&e); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+
+				return fmt.Errorf("error decoding watch event from the kubernetes api: %w", err)
+			}
+
+			switch e.Type {
+			case eventAdded, eventModified, eventDeleted:
+				return nil
+			case eventError:
+				var s status
+
+				if err = json.Unmarshal(e.Object, &s); err == nil && s.Code == http.StatusGone {
+					return nil
+				}
+
+				return fmt.Errorf("error watching %s with the kubernetes api: %s", u.Path, s.Message)
+			}
+		}
+	})
+}
+
+func resourceURL(address *url.URL, group, namespace, resource string) (u *url.URL) {
+	uri := *address
+
+	if namespace == "" {
+		uri.Path = path.Join(group, resource)
+	} else {
+		uri.Path = path.Join(group, "namespaces", namespace, resource)
+	}
+
+	return &uri
+}
+
+func (c *Client) get(ctx context.Context, u *url.URL, timeout time.Duration, decode func(body io.Reader) error) (err error) {
+	var (
+		req   *http.Request
+		resp  *http.Response
+		token []byte
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	defer cancel()
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil); err != nil {
+		return fmt.Errorf("error creating request to the kubernetes api: %w", err)
+	}
+
+	if c.tokenPath != "" {
+		if token, err = os.ReadFile(c.tokenPath); err != nil {
+			return fmt.Errorf("error reading the service account token: %w", err)
+		}
+
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if resp, err = c.client.Do(req); err != nil {
+		return fmt.Errorf("error performing request to the kubernetes api: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("error performing request to the kubernetes api: %s returned status code %d: %s", u.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return decode(resp.Body)
+}
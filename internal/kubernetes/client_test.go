@@ -0,0 +1,78 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientListIngresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/networking.k8s.io/v1/namespaces/default/ingresses", r.URL.Path)
+
+		switch r.URL.Query().Get("continue") {
+		case "":
+			fmt.Fprint(w, `{"metadata":{"continue":"next","resourceVersion":"10"},"items":[{"metadata":{"name":"a","namespace":"default"},"spec":{"rules":[{"host":"a.example.com"}]}}]}`)
+		default:
+			fmt.Fprint(w, `{"metadata":{"resourceVersion":"10"},"items":[{"metadata":{"name":"b","namespace":"default"},"spec":{"rules":[{"host":"b.example.com"},{}]}}]}`)
+		}
+	}))
+
+	defer server.Close()
+
+	address, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	resources, version, err := NewClient(address, "", nil).ListIngresses(context.Background(), "default")
+
+	require.NoError(t, err)
+	assert.Equal(t, "10", version)
+	require.Len(t, resources, 2)
+	assert.Equal(t, "Ingress default/b", resources[1].String())
+	assert.Equal(t, []ResourceHost{{Hosts: []string{"b.example.com"}}}, resources[1].Hosts)
+}
+
+func TestClientWatch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		events   string
+		expected string
+	}{
+		{"ShouldReturnOnChange", `{"type":"BOOKMARK","object":{}}` + "\n" + `{"type":"MODIFIED","object":{}}`, ""},
+		{"ShouldReturnOnEnd", "", ""},
+		{"ShouldReturnOnExpired", `{"type":"ERROR","object":{"code":410,"message":"too old resource version"}}`, ""},
+		{"ShouldReturnErrorOnError", `{"type":"ERROR","object":{"code":500,"message":"internal error"}}`, "error watching /apis/gateway.networking.k8s.io/v1/httproutes with the kubernetes api: internal error"},
+		{"ShouldReturnErrorOnInvalid", `{"type":`, "error decoding watch event from the kubernetes api: unexpected EOF"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/apis/gateway.networking.k8s.io/v1/httproutes", r.URL.Path)
+				assert.Equal(t, url.Values{"watch": {"true"}, "resourceVersion": {"10"}, "timeoutSeconds": {"30"}}, r.URL.Query())
+
+				fmt.Fprint(w, tc.events)
+			}))
+
+			defer server.Close()
+
+			address, err := url.Parse(server.URL)
+			require.NoError(t, err)
+
+			err = NewClient(address, "", nil).WatchHTTPRoutes(context.Background(), "", "10", time.Second*30)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"time"
+)
+
+const (
+	annotationPolicy    = "policy"
+	annotationSubject   = "subject"
+	annotationMethods   = "methods"
+	annotationNetworks  = "networks"
+	annotationResources = "resources"
+	annotationChallenge = "challenge"
+
+	annotationUserVerification = "webauthn-user-verification"
+)
+
+const (
+	policyBypass = "bypass"
+)
+
+const (
+	kindIngress   = "Ingress"
+	kindHTTPRoute = "HTTPRoute"
+)
+
+const (
+	pathTypeExact             = "Exact"
+	pathTypePathPrefix        = "PathPrefix"
+	pathTypeRegularExpression = "RegularExpression"
+)
+
+const (
+	apiGroupIngress   = "/apis/networking.k8s.io/v1"
+	apiGroupHTTPRoute = "/apis/gateway.networking.k8s.io/v1"
+
+	resourceIngresses  = "ingresses"
+	resourceHTTPRoutes = "httproutes"
+)
+
+const (
+	envServiceHost = "KUBERNETES_SERVICE_HOST"
+	envServicePort = "KUBERNETES_SERVICE_PORT"
+
+	pathServiceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	pathServiceAccountCA    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+const (
+	eventAdded    = "ADDED"
+	eventModified = "MODIFIED"
+	eventDeleted  = "DELETED"
+	eventError    = "ERROR"
+)
+
+const (
+	listLimit      = "500"
+	requestTimeout = time.Second * 30
+
+	delimiterList    = ","
+	delimiterSubject = "&"
+	delimiterLines   = "\n"
+)
@@ -0,0 +1,154 @@
+package kubernetes
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// API is implemented by types which list and watch the annotated resources such as the Client.
+type API interface {
+	ListIngresses(ctx context.Context, namespace string) (resources []Resource, version string, err error)
+	ListHTTPRoutes(ctx context.Context, namespace string) (resources []Resource, version string, err error)
+	WatchIngresses(ctx context.Context, namespace, version string, timeout time.Duration) (err error)
+	WatchHTTPRoutes(ctx context.Context, namespace, version string, timeout time.Duration) (err error)
+}
+
+// NewController returns a new Controller which synchronizes the annotated resources whenever they change and replaces
+// the dynamic rules of the RulesSetter.
+func NewController(config schema.AccessControl, api API, setter RulesSetter, log *logrus.Entry) (controller *Controller) {
+	return &Controller{
+		config: config,
+		api:    api,
+		setter: setter,
+		log:    log,
+	}
+}
+
+// Controller synthesizes ACL rules from annotated Kubernetes resources.
+type Controller struct {
+	config schema.AccessControl
+	api    API
+	setter RulesSetter
+	log    *logrus.Entry
+}
+
+// Run the Controller until the context is done. The resources are listed and then watched from the resource version of
+// the list, and are listed again as soon as a resource changes or the watch ends. The watches end after the sync
+// interval so the resources are fully synchronized at least once per sync interval, and the sync interval is also
+// waited after an error.
+func (c *Controller) Run(ctx context.Context) {
+	for {
+		watches, err := c.sync(ctx)
+
+		if err == nil {
+			err = c.watch(ctx, watches)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err == nil {
+			continue
+		}
+
+		c.log.WithError(err).Error("Error occurred synchronizing the rules from the kubernetes api")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.config.Kubernetes.SyncInterval):
+		}
+	}
+}
+
+// Sync lists the annotated resources and replaces the dynamic rules. The rules are left unchanged if the resources
+// could not be listed, so a temporary outage of the Kubernetes API does not remove them.
+func (c *Controller) Sync(ctx context.Context) (err error) {
+	_, err = c.sync(ctx)
+
+	return err
+}
+
+func (c *Controller) sync(ctx context.Context) (watches []watch, err error) {
+	var resources []Resource
+
+	if resources, watches, err = c.list(ctx); err != nil {
+		return nil, err
+	}
+
+	rules, errs := NewAccessControlRules(c.config, resources)
+
+	for _, err = range errs {
+		c.log.WithError(err).Warn("Skipping resource with invalid annotations")
+	}
+
+	c.setter.SetDynamicRules(rules)
+
+	c.log.WithField("rules", len(rules)).Debug("Synchronized the rules from the kubernetes api")
+
+	return watches, nil
+}
+
+// watch blocks until one of the watches returns, which is when a resource changed, the watch ended, or an error
+// occurred. The remaining watches are cancelled.
+func (c *Controller) watch(ctx context.Context, watches []watch) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	defer cancel()
+
+	results := make(chan error, len(watches))
+
+	for _, w := range watches {
+		go func(w watch) {
+			results <- w.watch(ctx, w.namespace, w.version, c.config.Kubernetes.SyncInterval)
+		}(w)
+	}
+
+	return <-results
+}
+
+func (c *Controller) list(ctx context.Context) (resources []Resource, watches []watch, err error) {
+	namespaces := c.config.Kubernetes.Namespaces
+
+	if len(namespaces) == 0 {
+		namespaces = []string{""}
+	}
+
+	var (
+		items   []Resource
+		version string
+	)
+
+	for _, namespace := range namespaces {
+		if items, version, err = c.api.ListIngresses(ctx, namespace); err != nil {
+			return nil, nil, err
+		}
+
+		resources = append(resources, items...)
+		watches = append(watches, watch{namespace: namespace, version: version, watch: c.api.WatchIngresses})
+
+		if !c.config.Kubernetes.GatewayAPI {
+			continue
+		}
+
+		if items, version, err = c.api.ListHTTPRoutes(ctx, namespace); err != nil {
+			return nil, nil, err
+		}
+
+		resources = append(resources, items...)
+		watches = append(watches, watch{namespace: namespace, version: version, watch: c.api.WatchHTTPRoutes})
+	}
+
+	return resources, watches, nil
+}
+
+type watch struct {
+	namespace string
+	version   string
+	watch     func(ctx context.Context, namespace, version string, timeout time.Duration) (err error)
+}
@@ -0,0 +1,116 @@
+package kubernetes
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+type testAPI struct {
+	mu        sync.Mutex
+	resources []Resource
+	version   int
+	changes   chan struct{}
+}
+
+func (a *testAPI) ListIngresses(_ context.Context, _ string) (resources []Resource, version string, err error) {
+	a.mu.Lock()
+
+	defer a.mu.Unlock()
+
+	a.version++
+
+	return a.resources, strconv.Itoa(a.version), nil
+}
+
+func (a *testAPI) ListHTTPRoutes(_ context.Context, _ string) (resources []Resource, version string, err error) {
+	return nil, "", nil
+}
+
+func (a *testAPI) WatchIngresses(ctx context.Context, _, _ string, _ time.Duration) (err error) {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-a.changes:
+		return nil
+	}
+}
+
+func (a *testAPI) WatchHTTPRoutes(ctx context.Context, _, _ string, _ time.Duration) (err error) {
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (a *testAPI) set(resources []Resource) {
+	a.mu.Lock()
+
+	defer a.mu.Unlock()
+
+	a.resources = resources
+}
+
+type testSetter struct {
+	rules chan []schema.AccessControlRule
+}
+
+func (s *testSetter) SetDynamicRules(rules []schema.AccessControlRule) {
+	s.rules <- rules
+}
+
+func TestControllerRun(t *testing.T) {
+	api := &testAPI{changes: make(chan struct{})}
+	setter := &testSetter{rules: make(chan []schema.AccessControlRule)}
+
+	config := newTestAccessControl()
+
+	config.Kubernetes.GatewayAPI = true
+	config.Kubernetes.SyncInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		NewController(config, api, setter, logrus.NewEntry(logrus.New())).Run(ctx)
+
+		close(done)
+	}()
+
+	assert.Len(t, <-setter.rules, 0)
+
+	api.set([]Resource{
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "app",
+			Annotations: map[string]string{"authelia.com/policy": "one_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"app.example.com"}}},
+		},
+	})
+
+	api.changes <- struct{}{}
+
+	rules := <-setter.rules
+
+	require.Len(t, rules, 1)
+	assert.Equal(t, schema.AccessControlRuleDomains{"app.example.com"}, rules[0].Domains)
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-setter.rules:
+		t.Fatal("the rules were synchronized after the context was done")
+	case <-time.After(time.Second * 5):
+		t.Fatal("the controller did not stop after the context was done")
+	}
+}
@@ -0,0 +1,247 @@
+package kubernetes
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewAccessControlRules synthesizes the ACL rules from the annotated resources. Resources without the policy annotation
+// are ignored, and resources with invalid annotations are skipped and the errors are returned. The rules of each
+// resource only apply to the hosts routed by that resource, and resources which route a host that's also routed by an
+// annotated resource in another namespace are skipped. The rules are ordered by the kind, namespace, and name of the
+// resources they were synthesized from.
+func NewAccessControlRules(config schema.AccessControl, resources []Resource) (rules []schema.AccessControlRule, errs []error) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].String() < resources[j].String()
+	})
+
+	namespaces := hostNamespaces(config, resources)
+
+	for _, resource := range resources {
+		var (
+			synthesized []schema.AccessControlRule
+			err         error
+		)
+
+		if synthesized, err = newAccessControlRules(config, resource, namespaces); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", resource, err))
+
+			continue
+		}
+
+		rules = append(rules, synthesized...)
+	}
+
+	return rules, errs
+}
+
+// hostNamespaces returns the namespaces of the annotated resources which route each host.
+func hostNamespaces(config schema.AccessControl, resources []Resource) (namespaces map[string][]string) {
+	prefix := config.Kubernetes.AnnotationPrefix + "/"
+
+	namespaces = map[string][]string{}
+
+	for _, resource := range resources {
+		if _, ok := resource.Annotations[prefix+annotationPolicy]; !ok {
+			continue
+		}
+
+		for _, host := range resource.Hosts {
+			for _, h := range host.Hosts {
+				h = strings.ToLower(h)
+
+				if !utils.IsStringInSlice(resource.Namespace, namespaces[h]) {
+					namespaces[h] = append(namespaces[h], resource.Namespace)
+				}
+			}
+		}
+	}
+
+	return namespaces
+}
+
+func newAccessControlRules(config schema.AccessControl, resource Resource, namespaces map[string][]string) (rules []schema.AccessControlRule, err error) {
+	prefix := config.Kubernetes.AnnotationPrefix + "/"
+
+	policy, ok := resource.Annotations[prefix+annotationPolicy]
+	if !ok {
+		return nil, nil
+	}
+
+	base := schema.AccessControlRule{
+		Policy:    strings.TrimSpace(policy),
+		Subjects:  parseAnnotationSubjects(resource.Annotations[prefix+annotationSubject]),
+		Networks:  parseAnnotationList(resource.Annotations[prefix+annotationNetworks]),
+		Challenge: strings.TrimSpace(resource.Annotations[prefix+annotationChallenge]),
+
+		WebAuthnUserVerification: strings.TrimSpace(resource.Annotations[prefix+annotationUserVerification]),
+	}
+
+	if base.Policy == policyBypass && !utils.IsStringInSlice(resource.Namespace, config.Kubernetes.BypassNamespaces) {
+		return nil, fmt.Errorf("policy '%s' is not permitted in the namespace '%s'", policyBypass, resource.Namespace)
+	}
+
+	for _, method := range parseAnnotationList(resource.Annotations[prefix+annotationMethods]) {
+		base.Methods = append(base.Methods, strings.ToUpper(method))
+	}
+
+	var explicit schema.AccessControlRuleRegex
+
+	if value, ok := resource.Annotations[prefix+annotationResources]; ok {
+		if explicit, err = parseRegexps(parseAnnotationLines(value)); err != nil {
+			return nil, fmt.Errorf("annotation '%s%s' is invalid: %w", prefix, annotationResources, err)
+		}
+	}
+
+	for _, host := range resource.Hosts {
+		if err = validateHosts(resource, host.Hosts, namespaces); err != nil {
+			return nil, err
+		}
+
+		rule := base
+
+		rule.Domains = host.Hosts
+
+		switch {
+		case explicit != nil:
+			rule.Resources = explicit
+		default:
+			if rule.Resources, err = pathsToResources(host.Paths); err != nil {
+				return nil, err
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no hosts are defined")
+	}
+
+	if err = validateRules(config, rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// validateHosts ensures the hosts are literal hosts which are only routed by annotated resources in the namespace of
+// the resource, so a resource can't synthesize rules which apply to the hosts of another namespace.
+func validateHosts(resource Resource, hosts []string, namespaces map[string][]string) (err error) {
+	for _, host := range hosts {
+		if strings.ContainsAny(host, "*{}") {
+			return fmt.Errorf("host '%s' is not permitted: only literal hosts are permitted", host)
+		}
+
+		for _, namespace := range namespaces[strings.ToLower(host)] {
+			if namespace != resource.Namespace {
+				return fmt.Errorf("host '%s' is also routed by an annotated resource in the namespace '%s'", host, namespace)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRules(config schema.AccessControl, rules []schema.AccessControlRule) (err error) {
+	val := schema.NewStructValidator()
+
+	c := &schema.Configuration{
+		AccessControl: schema.AccessControl{
+			DefaultPolicy: config.DefaultPolicy,
+			Networks:      config.Networks,
+			Rules:         rules,
+		},
+	}
+
+	validator.ValidateRules(c, val)
+
+	if errs := val.Errors(); len(errs) != 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+func pathsToResources(paths []ResourcePath) (resources schema.AccessControlRuleRegex, err error) {
+	patterns := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		switch path.Type {
+		case pathTypeExact:
+			patterns = append(patterns, "^"+regexp.QuoteMeta(path.Value)+`(\?.*)?$`)
+		case pathTypeRegularExpression:
+			patterns = append(patterns, "^(?:"+path.Value+`)(\?.*)?$`)
+		default:
+			value := strings.TrimSuffix(path.Value, "/")
+
+			if value == "" {
+				return nil, nil
+			}
+
+			patterns = append(patterns, "^"+regexp.QuoteMeta(value)+`([/?].*)?$`)
+		}
+	}
+
+	if resources, err = parseRegexps(patterns); err != nil {
+		return nil, fmt.Errorf("path is invalid: %w", err)
+	}
+
+	return resources, nil
+}
+
+func parseRegexps(patterns []string) (regexps schema.AccessControlRuleRegex, err error) {
+	var pattern *regexp.Regexp
+
+	for _, p := range patterns {
+		if pattern, err = regexp.Compile(p); err != nil {
+			return nil, err
+		}
+
+		regexps = append(regexps, *pattern)
+	}
+
+	return regexps, nil
+}
+
+func parseAnnotationSubjects(value string) (subjects schema.AccessControlRuleSubjects) {
+	for _, entry := range parseAnnotationList(value) {
+		var subject []string
+
+		for _, s := range strings.Split(entry, delimiterSubject) {
+			if s = strings.TrimSpace(s); s != "" {
+				subject = append(subject, s)
+			}
+		}
+
+		if len(subject) != 0 {
+			subjects = append(subjects, subject)
+		}
+	}
+
+	return subjects
+}
+
+func parseAnnotationList(value string) (items []string) {
+	return splitTrim(value, delimiterList)
+}
+
+func parseAnnotationLines(value string) (items []string) {
+	return splitTrim(value, delimiterLines)
+}
+
+func splitTrim(value, sep string) (items []string) {
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
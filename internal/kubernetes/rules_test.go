@@ -0,0 +1,235 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestAccessControl() schema.AccessControl {
+	return schema.AccessControl{
+		DefaultPolicy: "deny",
+		Networks: []schema.AccessControlNetwork{
+			{Name: "internal", Networks: []string{"10.0.0.0/8"}},
+		},
+		Kubernetes: schema.DefaultAccessControlKubernetesConfiguration,
+	}
+}
+
+func regexpStrings(regexps schema.AccessControlRuleRegex) (patterns []string) {
+	for _, r := range regexps {
+		patterns = append(patterns, r.String())
+	}
+
+	return patterns
+}
+
+func TestNewAccessControlRules(t *testing.T) {
+	resources := []Resource{
+		{
+			Kind:      kindIngress,
+			Namespace: "default",
+			Name:      "unannotated",
+			Hosts:     []ResourceHost{{Hosts: []string{"unannotated.example.com"}}},
+		},
+		{
+			Kind:      kindIngress,
+			Namespace: "default",
+			Name:      "app",
+			Annotations: map[string]string{
+				"authelia.com/policy":   "two_factor",
+				"authelia.com/subject":  "group:admins & group:ops, user:john",
+				"authelia.com/methods":  "get, post",
+				"authelia.com/networks": "internal, 192.168.0.0/16",
+
+				"authelia.com/webauthn-user-verification": "required",
+			},
+			Hosts: []ResourceHost{
+				{Hosts: []string{"app.example.com"}, Paths: []ResourcePath{{Type: "Prefix", Value: "/"}}},
+				{Hosts: []string{"api.example.com"}, Paths: []ResourcePath{{Type: "Prefix", Value: "/v1/"}, {Type: pathTypeExact, Value: "/status"}}},
+			},
+		},
+		{
+			Kind:      kindHTTPRoute,
+			Namespace: "apps",
+			Name:      "public",
+			Annotations: map[string]string{
+				"authelia.com/policy":    "bypass",
+				"authelia.com/resources": "^/public/.*$\n^/assets/[a-z]{1,8}\\.js$\n",
+			},
+			Hosts: []ResourceHost{{Hosts: []string{"public.example.com", "www.example.com"}}},
+		},
+	}
+
+	config := newTestAccessControl()
+
+	config.Kubernetes.BypassNamespaces = []string{"apps"}
+
+	rules, errs := NewAccessControlRules(config, resources)
+
+	assert.Len(t, errs, 0)
+	require.Len(t, rules, 3)
+
+	assert.Equal(t, schema.AccessControlRuleDomains{"public.example.com", "www.example.com"}, rules[0].Domains)
+	assert.Equal(t, "bypass", rules[0].Policy)
+	assert.Equal(t, []string{`^/public/.*$`, `^/assets/[a-z]{1,8}\.js$`}, regexpStrings(rules[0].Resources))
+	assert.Equal(t, "auto", rules[0].Challenge)
+	assert.Equal(t, "preferred", rules[0].WebAuthnUserVerification)
+
+	assert.Equal(t, schema.AccessControlRuleDomains{"app.example.com"}, rules[1].Domains)
+	assert.Equal(t, "two_factor", rules[1].Policy)
+	assert.Nil(t, rules[1].Resources)
+	assert.Equal(t, schema.AccessControlRuleSubjects{{"group:admins", "group:ops"}, {"user:john"}}, rules[1].Subjects)
+	assert.Equal(t, schema.AccessControlRuleMethods{"GET", "POST"}, rules[1].Methods)
+	assert.Equal(t, schema.AccessControlRuleNetworks{"internal", "192.168.0.0/16"}, rules[1].Networks)
+	assert.Equal(t, "required", rules[1].WebAuthnUserVerification)
+
+	assert.Equal(t, schema.AccessControlRuleDomains{"api.example.com"}, rules[2].Domains)
+	assert.Equal(t, "two_factor", rules[2].Policy)
+	assert.Equal(t, []string{`^/v1([/?].*)?$`, `^/status(\?.*)?$`}, regexpStrings(rules[2].Resources))
+}
+
+func TestNewAccessControlRulesRestricted(t *testing.T) {
+	resources := []Resource{
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "bypass",
+			Annotations: map[string]string{"authelia.com/policy": "bypass"},
+			Hosts:       []ResourceHost{{Hosts: []string{"bypass.example.com"}}},
+		},
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "wildcard",
+			Annotations: map[string]string{"authelia.com/policy": "one_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"*.example.com"}}},
+		},
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "app",
+			Annotations: map[string]string{"authelia.com/policy": "two_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"app.example.com"}}},
+		},
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "app-api",
+			Annotations: map[string]string{"authelia.com/policy": "two_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"app.example.com"}, Paths: []ResourcePath{{Type: "Prefix", Value: "/api"}}}},
+		},
+		{
+			Kind:        kindHTTPRoute,
+			Namespace:   "other",
+			Name:        "claim",
+			Annotations: map[string]string{"authelia.com/policy": "one_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"claim.example.com"}}},
+		},
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "claim",
+			Annotations: map[string]string{"authelia.com/policy": "two_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"Claim.example.com"}}},
+		},
+		{
+			Kind:      kindIngress,
+			Namespace: "other",
+			Name:      "unannotated",
+			Hosts:     []ResourceHost{{Hosts: []string{"app.example.com"}}},
+		},
+	}
+
+	rules, errs := NewAccessControlRules(newTestAccessControl(), resources)
+
+	require.Len(t, rules, 2)
+	assert.Equal(t, schema.AccessControlRuleDomains{"app.example.com"}, rules[0].Domains)
+	assert.Nil(t, rules[0].Resources)
+	assert.Equal(t, schema.AccessControlRuleDomains{"app.example.com"}, rules[1].Domains)
+	assert.Equal(t, []string{`^/api([/?].*)?$`}, regexpStrings(rules[1].Resources))
+
+	require.Len(t, errs, 4)
+	assert.EqualError(t, errs[0], "HTTPRoute other/claim: host 'claim.example.com' is also routed by an annotated resource in the namespace 'default'")
+	assert.EqualError(t, errs[1], "Ingress default/bypass: policy 'bypass' is not permitted in the namespace 'default'")
+	assert.EqualError(t, errs[2], "Ingress default/claim: host 'Claim.example.com' is also routed by an annotated resource in the namespace 'other'")
+	assert.EqualError(t, errs[3], "Ingress default/wildcard: host '*.example.com' is not permitted: only literal hosts are permitted")
+}
+
+func TestNewAccessControlRulesInvalid(t *testing.T) {
+	resources := []Resource{
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "policy",
+			Annotations: map[string]string{"authelia.com/policy": "three_factor"},
+			Hosts:       []ResourceHost{{Hosts: []string{"policy.example.com"}}},
+		},
+		{
+			Kind:        kindIngress,
+			Namespace:   "default",
+			Name:        "hosts",
+			Annotations: map[string]string{"authelia.com/policy": "one_factor"},
+		},
+		{
+			Kind:      kindIngress,
+			Namespace: "default",
+			Name:      "resources",
+			Annotations: map[string]string{
+				"authelia.com/policy":    "one_factor",
+				"authelia.com/resources": "^/(bad$",
+			},
+			Hosts: []ResourceHost{{Hosts: []string{"resources.example.com"}}},
+		},
+		{
+			Kind:      kindIngress,
+			Namespace: "default",
+			Name:      "valid",
+			Annotations: map[string]string{
+				"authelia.com/policy": "one_factor",
+			},
+			Hosts: []ResourceHost{{Hosts: []string{"valid.example.com"}}},
+		},
+	}
+
+	rules, errs := NewAccessControlRules(newTestAccessControl(), resources)
+
+	require.Len(t, rules, 1)
+	assert.Equal(t, schema.AccessControlRuleDomains{"valid.example.com"}, rules[0].Domains)
+
+	require.Len(t, errs, 3)
+	assert.EqualError(t, errs[0], "Ingress default/hosts: no hosts are defined")
+	assert.EqualError(t, errs[1], "Ingress default/policy: access_control: rule #1 (domain 'policy.example.com'): option 'policy' must be one of 'bypass', 'one_factor', 'two_factor', or 'deny' but it's configured as 'three_factor'")
+	assert.EqualError(t, errs[2], "Ingress default/resources: annotation 'authelia.com/resources' is invalid: error parsing regexp: missing closing ): `^/(bad$`")
+}
+
+func TestHTTPRouteResource(t *testing.T) {
+	route := httpRoute{
+		Metadata: objectMeta{Name: "app", Namespace: "default"},
+		Spec: httpRouteSpec{
+			Hostnames: []string{"app.example.com"},
+			Rules: []httpRouteRule{
+				{Matches: []httpRouteMatch{{Path: &httpRoutePathMatch{Type: pathTypeRegularExpression, Value: "/api/v[0-9]+"}}}},
+				{},
+			},
+		},
+	}
+
+	resource := route.resource()
+
+	require.Len(t, resource.Hosts, 1)
+	assert.Equal(t, []ResourcePath{{Type: pathTypeRegularExpression, Value: "/api/v[0-9]+"}, {Type: pathTypePathPrefix, Value: "/"}}, resource.Hosts[0].Paths)
+
+	resources, err := pathsToResources(resource.Hosts[0].Paths)
+
+	assert.NoError(t, err)
+	assert.Nil(t, resources)
+
+	resources, err = pathsToResources(resource.Hosts[0].Paths[:1])
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`^(?:/api/v[0-9]+)(\?.*)?$`}, regexpStrings(resources))
+}
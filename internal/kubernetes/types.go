@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"encoding/json"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// RulesSetter is implemented by types which accept the synthesized rules such as the authorization.Authorizer.
+type RulesSetter interface {
+	SetDynamicRules(rules []schema.AccessControlRule)
+}
+
+// Resource is the common representation of an annotated Kubernetes resource which rules are synthesized from.
+type Resource struct {
+	Kind        string
+	Namespace   string
+	Name        string
+	Annotations map[string]string
+	Hosts       []ResourceHost
+}
+
+// ResourceHost is a host and the paths of that host which are routed by a Resource.
+type ResourceHost struct {
+	Hosts []string
+	Paths []ResourcePath
+}
+
+// ResourcePath is a path routed by a Resource.
+type ResourcePath struct {
+	Type  string
+	Value string
+}
+
+// String returns a string representation of the Resource.
+func (r Resource) String() string {
+	return r.Kind + " " + r.Namespace + "/" + r.Name
+}
+
+type objectMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+type listMeta struct {
+	Continue        string `json:"continue"`
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+type objectList[T any] struct {
+	Metadata listMeta `json:"metadata"`
+	Items    []T      `json:"items"`
+}
+
+type event struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type ingress struct {
+	Metadata objectMeta  `json:"metadata"`
+	Spec     ingressSpec `json:"spec"`
+}
+
+type ingressSpec struct {
+	Rules []ingressRule `json:"rules"`
+}
+
+type ingressRule struct {
+	Host string           `json:"host"`
+	HTTP *ingressRuleHTTP `json:"http"`
+}
+
+type ingressRuleHTTP struct {
+	Paths []ingressPath `json:"paths"`
+}
+
+type ingressPath struct {
+	Path     string `json:"path"`
+	PathType string `json:"pathType"`
+}
+
+type httpRoute struct {
+	Metadata objectMeta    `json:"metadata"`
+	Spec     httpRouteSpec `json:"spec"`
+}
+
+type httpRouteSpec struct {
+	Hostnames []string        `json:"hostnames"`
+	Rules     []httpRouteRule `json:"rules"`
+}
+
+type httpRouteRule struct {
+	Matches []httpRouteMatch `json:"matches"`
+}
+
+type httpRouteMatch struct {
+	Path *httpRoutePathMatch `json:"path"`
+}
+
+type httpRoutePathMatch struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func (i ingress) resource() (resource Resource) {
+	resource = Resource{
+		Kind:        kindIngress,
+		Namespace:   i.Metadata.Namespace,
+		Name:        i.Metadata.Name,
+		Annotations: i.Metadata.Annotations,
+	}
+
+	for _, rule := range i.Spec.Rules {
+		if rule.Host == "" {
+			continue
+		}
+
+		host := ResourceHost{Hosts: []string{rule.Host}}
+
+		if rule.HTTP != nil {
+			for _, path := range rule.HTTP.Paths {
+				host.Paths = append(host.Paths, ResourcePath{Type: path.PathType, Value: path.Path})
+			}
+		}
+
+		resource.Hosts = append(resource.Hosts, host)
+	}
+
+	return resource
+}
+
+func (r httpRoute) resource() (resource Resource) {
+	resource = Resource{
+		Kind:        kindHTTPRoute,
+		Namespace:   r.Metadata.Namespace,
+		Name:        r.Metadata.Name,
+		Annotations: r.Metadata.Annotations,
+	}
+
+	if len(r.Spec.Hostnames) == 0 {
+		return resource
+	}
+
+	host := ResourceHost{Hosts: r.Spec.Hostnames}
+
+	for _, rule := range r.Spec.Rules {
+		if len(rule.Matches) == 0 {
+			host.Paths = append(host.Paths, ResourcePath{Type: pathTypePathPrefix, Value: "/"})
+		}
+
+		for _, match := range rule.Matches {
+			if match.Path == nil {
+				host.Paths = append(host.Paths, ResourcePath{Type: pathTypePathPrefix, Value: "/"})
+
+				continue
+			}
+
+			host.Paths = append(host.Paths, ResourcePath{Type: match.Path.Type, Value: match.Path.Value})
+		}
+	}
+
+	resource.Hosts = append(resource.Hosts, host)
+
+	return resource
+}
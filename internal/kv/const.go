@@ -0,0 +1,67 @@
+package kv
+
+import (
+	"time"
+)
+
+const (
+	// SchemeConsul is the scheme for keys in the Consul KV store accessed using HTTP.
+	SchemeConsul = "consul"
+
+	// SchemeConsulHTTPS is the scheme for keys in the Consul KV store accessed using HTTPS.
+	SchemeConsulHTTPS = "consul+https"
+
+	// SchemeEtcd is the scheme for keys in etcd accessed using HTTP.
+	SchemeEtcd = "etcd"
+
+	// SchemeEtcdHTTPS is the scheme for keys in etcd accessed using HTTPS.
+	SchemeEtcdHTTPS = "etcd+https"
+)
+
+const (
+	delimiterScheme = "://"
+)
+
+const (
+	schemeHTTP  = "http"
+	schemeHTTPS = "https"
+)
+
+const (
+	envConsulHTTPAddr  = "CONSUL_HTTP_ADDR"
+	envConsulHTTPToken = "CONSUL_HTTP_TOKEN"
+	envConsulCACert    = "CONSUL_CACERT"
+
+	envEtcdEndpoints = "ETCDCTL_ENDPOINTS"
+	envEtcdUser      = "ETCDCTL_USER"
+	envEtcdPassword  = "ETCDCTL_PASSWORD"
+	envEtcdCACert    = "ETCDCTL_CACERT"
+)
+
+const (
+	headerConsulToken = "X-Consul-Token"
+	headerConsulIndex = "X-Consul-Index"
+	headerContentType = "Content-Type"
+	headerAuthorize   = "Authorization"
+
+	contentTypeJSON = "application/json"
+)
+
+const (
+	consulPathKV = "/v1/kv/"
+
+	etcdPathRange        = "/v3/kv/range"
+	etcdPathWatch        = "/v3/watch"
+	etcdPathAuthenticate = "/v3/auth/authenticate"
+)
+
+const (
+	consulDefaultAddress = "127.0.0.1:8500"
+	etcdDefaultAddress   = "127.0.0.1:2379"
+
+	consulWait = "5m"
+)
+
+const (
+	timeoutRequest = time.Second * 30
+)
@@ -0,0 +1,120 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// NewConsulClient returns a new ConsulClient for the Consul KV store at the provided address.
+func NewConsulClient(address *url.URL, token, datacenter string, client *http.Client) (c *ConsulClient) {
+	return &ConsulClient{
+		address:    address,
+		token:      token,
+		datacenter: datacenter,
+		client:     client,
+	}
+}
+
+// ConsulClient is a Client for the Consul KV store which watches keys using blocking queries.
+type ConsulClient struct {
+	address    *url.URL
+	token      string
+	datacenter string
+	client     *http.Client
+}
+
+// Get returns the raw value of the key and the index of the key.
+func (c *ConsulClient) Get(ctx context.Context, key string) (value []byte, revision uint64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutRequest)
+
+	defer cancel()
+
+	var resp *http.Response
+
+	if resp, err = c.do(ctx, key, nil); err != nil {
+		return nil, 0, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, 0, fmt.Errorf("error reading key '%s' from the consul api: the key does not exist", key)
+	}
+
+	if revision, err = consulIndex(resp); err != nil {
+		return nil, 0, err
+	}
+
+	if value, err = io.ReadAll(resp.Body); err != nil {
+		return nil, 0, fmt.Errorf("error reading key '%s' from the consul api: %w", key, err)
+	}
+
+	return value, revision, nil
+}
+
+// Watch blocks until the index of the key differs from the provided revision. The key being deleted and the index
+// being reset are both considered as a modification.
+func (c *ConsulClient) Watch(ctx context.Context, key string, revision uint64) (next uint64, err error) {
+	query := url.Values{
+		"index": []string{strconv.FormatUint(revision, 10)},
+		"wait":  []string{consulWait},
+	}
+
+	for {
+		var resp *http.Response
+
+		if resp, err = c.do(ctx, key, query); err != nil {
+			return 0, err
+		}
+
+		_ = resp.Body.Close()
+
+		if next, err = consulIndex(resp); err != nil {
+			return 0, err
+		}
+
+		if next != revision {
+			return next, nil
+		}
+	}
+}
+
+func (c *ConsulClient) do(ctx context.Context, key string, query url.Values) (resp *http.Response, err error) {
+	u := c.address.JoinPath(consulPathKV, key)
+
+	if query == nil {
+		query = url.Values{}
+	}
+
+	query.Set("raw", "")
+
+	if c.datacenter != "" {
+		query.Set("dc", c.datacenter)
+	}
+
+	u.RawQuery = query.Encode()
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil); err != nil {
+		return nil, fmt.Errorf("error creating request to the consul api: %w", err)
+	}
+
+	if c.token != "" {
+		req.Header.Set(headerConsulToken, c.token)
+	}
+
+	return do(c.client, req, "consul", http.StatusNotFound)
+}
+
+func consulIndex(resp *http.Response) (index uint64, err error) {
+	if index, err = strconv.ParseUint(resp.Header.Get(headerConsulIndex), 10, 64); err != nil {
+		return 0, fmt.Errorf("error decoding response from the consul api: the '%s' header is invalid: %w", headerConsulIndex, err)
+	}
+
+	return index, nil
+}
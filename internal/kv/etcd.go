@@ -0,0 +1,200 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// NewEtcdClient returns a new EtcdClient for the etcd cluster at the provided address. If the username is provided the
+// client authenticates before every request.
+func NewEtcdClient(address *url.URL, username, password string, client *http.Client) (c *EtcdClient) {
+	return &EtcdClient{
+		address:  address,
+		username: username,
+		password: password,
+		client:   client,
+	}
+}
+
+// EtcdClient is a Client for etcd which uses the JSON gateway of the etcd v3 API.
+type EtcdClient struct {
+	address  *url.URL
+	username string
+	password string
+	client   *http.Client
+}
+
+// Get returns the value of the key and the revision of the cluster the value was read at.
+func (c *EtcdClient) Get(ctx context.Context, key string) (value []byte, revision uint64, err error) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutRequest)
+
+	defer cancel()
+
+	var resp *http.Response
+
+	if resp, err = c.do(ctx, etcdPathRange, etcdRangeRequest{Key: []byte(key)}); err != nil {
+		return nil, 0, err
+	}
+
+	var result etcdRangeResponse
+
+	if err = decode(resp, "etcd", &result); err != nil {
+		return nil, 0, err
+	}
+
+	if len(result.KVs) == 0 {
+		return nil, 0, fmt.Errorf("error reading key '%s' from the etcd api: the key does not exist", key)
+	}
+
+	return result.KVs[0].Value, uint64(result.Header.Revision), nil
+}
+
+// Watch blocks until the key is modified after the provided revision. The revisions being compacted is considered a
+// modification as the modifications may have been compacted.
+func (c *EtcdClient) Watch(ctx context.Context, key string, revision uint64) (next uint64, err error) {
+	var resp *http.Response
+
+	request := etcdWatchRequest{
+		CreateRequest: etcdWatchCreateRequest{
+			Key:           []byte(key),
+			StartRevision: int64(revision) + 1,
+		},
+	}
+
+	if resp, err = c.do(ctx, etcdPathWatch, request); err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+
+	for {
+		var result etcdWatchResponse
+
+		if err = decoder.Decode(&result); err != nil {
+			return 0, fmt.Errorf("error decoding response from the etcd api: %w", err)
+		}
+
+		switch {
+		case result.Error != nil:
+			return 0, fmt.Errorf("error watching key '%s' using the etcd api: %s", key, result.Error.Message)
+		case result.Result == nil:
+			continue
+		case result.Result.CompactRevision != 0, len(result.Result.Events) != 0:
+			return uint64(result.Result.Header.Revision), nil
+		case result.Result.Canceled:
+			return 0, fmt.Errorf("error watching key '%s' using the etcd api: the watch was canceled: %s", key, result.Result.CancelReason)
+		}
+	}
+}
+
+func (c *EtcdClient) do(ctx context.Context, path string, body any) (resp *http.Response, err error) {
+	var token string
+
+	if c.username != "" {
+		if token, err = c.authenticate(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var data []byte
+
+	if data, err = json.Marshal(body); err != nil {
+		return nil, fmt.Errorf("error encoding request to the etcd api: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.address.JoinPath(path).String(), bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error creating request to the etcd api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeJSON)
+
+	if token != "" {
+		req.Header.Set(headerAuthorize, token)
+	}
+
+	return do(c.client, req, "etcd")
+}
+
+func (c *EtcdClient) authenticate(ctx context.Context) (token string, err error) {
+	var data []byte
+
+	if data, err = json.Marshal(etcdAuthenticateRequest{Name: c.username, Password: c.password}); err != nil {
+		return "", fmt.Errorf("error encoding request to the etcd api: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.address.JoinPath(etcdPathAuthenticate).String(), bytes.NewReader(data)); err != nil {
+		return "", fmt.Errorf("error creating request to the etcd api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeJSON)
+
+	var resp *http.Response
+
+	if resp, err = do(c.client, req, "etcd"); err != nil {
+		return "", err
+	}
+
+	var result etcdAuthenticateResponse
+
+	if err = decode(resp, "etcd", &result); err != nil {
+		return "", err
+	}
+
+	return result.Token, nil
+}
+
+type etcdHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+type etcdRangeRequest struct {
+	Key []byte `json:"key"`
+}
+
+type etcdRangeResponse struct {
+	Header etcdHeader `json:"header"`
+	KVs    []struct {
+		Value []byte `json:"value"`
+	} `json:"kvs"`
+}
+
+type etcdWatchRequest struct {
+	CreateRequest etcdWatchCreateRequest `json:"create_request"`
+}
+
+type etcdWatchCreateRequest struct {
+	Key           []byte `json:"key"`
+	StartRevision int64  `json:"start_revision,string"`
+}
+
+type etcdWatchResponse struct {
+	Result *struct {
+		Header          etcdHeader        `json:"header"`
+		Canceled        bool              `json:"canceled"`
+		CancelReason    string            `json:"cancel_reason"`
+		CompactRevision int64             `json:"compact_revision,string"`
+		Events          []json.RawMessage `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type etcdAuthenticateRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type etcdAuthenticateResponse struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,170 @@
+package kv
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// IsReference returns true if the value is a reference to a key in a supported key value store.
+func IsReference(value string) bool {
+	scheme, _, found := strings.Cut(value, delimiterScheme)
+	if !found {
+		return false
+	}
+
+	switch strings.ToLower(scheme) {
+	case SchemeConsul, SchemeConsulHTTPS, SchemeEtcd, SchemeEtcdHTTPS:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewClientFromReference returns a new Client for the key value store of the reference and the key the reference
+// refers to. The references have the format '<scheme>://<host>:<port>/<key>', and if the host is omitted the address
+// is read from the environment variables which are conventional for the key value store.
+func NewClientFromReference(value string) (client Client, key string, err error) {
+	var (
+		reference, address *url.URL
+		httpClient         *http.Client
+	)
+
+	if reference, err = url.Parse(value); err != nil {
+		return nil, "", fmt.Errorf("error parsing key value store reference '%s': %w", value, err)
+	}
+
+	switch scheme := strings.ToLower(reference.Scheme); scheme {
+	case SchemeConsul, SchemeConsulHTTPS:
+		if key = strings.TrimPrefix(reference.Path, "/"); key == "" {
+			return nil, "", fmt.Errorf("error parsing key value store reference '%s': the reference must have a key", value)
+		}
+
+		if address, err = newAddress(scheme == SchemeConsulHTTPS, reference.Host, os.Getenv(envConsulHTTPAddr), consulDefaultAddress); err != nil {
+			return nil, "", fmt.Errorf("error parsing key value store reference '%s': %w", value, err)
+		}
+
+		if httpClient, err = newHTTPClient(os.Getenv(envConsulCACert)); err != nil {
+			return nil, "", err
+		}
+
+		return NewConsulClient(address, os.Getenv(envConsulHTTPToken), reference.Query().Get("dc"), httpClient), key, nil
+	case SchemeEtcd, SchemeEtcdHTTPS:
+		if key = reference.Path; strings.Trim(key, "/") == "" {
+			return nil, "", fmt.Errorf("error parsing key value store reference '%s': the reference must have a key", value)
+		}
+
+		endpoint, _, _ := strings.Cut(os.Getenv(envEtcdEndpoints), ",")
+
+		if address, err = newAddress(scheme == SchemeEtcdHTTPS, reference.Host, endpoint, etcdDefaultAddress); err != nil {
+			return nil, "", fmt.Errorf("error parsing key value store reference '%s': %w", value, err)
+		}
+
+		if httpClient, err = newHTTPClient(os.Getenv(envEtcdCACert)); err != nil {
+			return nil, "", err
+		}
+
+		username, password := os.Getenv(envEtcdUser), os.Getenv(envEtcdPassword)
+
+		if password == "" {
+			username, password, _ = strings.Cut(username, ":")
+		}
+
+		return NewEtcdClient(address, username, password, httpClient), key, nil
+	default:
+		return nil, "", fmt.Errorf("error parsing key value store reference '%s': the scheme '%s' is not supported", value, reference.Scheme)
+	}
+}
+
+// newAddress returns the address of the key value store. The host of the reference takes precedence over the address
+// from the environment which may include the scheme, followed by the default address.
+func newAddress(https bool, host, env, fallback string) (address *url.URL, err error) {
+	scheme := schemeHTTP
+
+	if https {
+		scheme = schemeHTTPS
+	}
+
+	switch {
+	case host != "":
+		return &url.URL{Scheme: scheme, Host: host}, nil
+	case env == "":
+		return &url.URL{Scheme: scheme, Host: fallback}, nil
+	case strings.Contains(env, delimiterScheme):
+		if address, err = url.Parse(env); err != nil {
+			return nil, fmt.Errorf("the address '%s' is invalid: %w", env, err)
+		}
+
+		return &url.URL{Scheme: address.Scheme, Host: address.Host}, nil
+	default:
+		return &url.URL{Scheme: scheme, Host: env}, nil
+	}
+}
+
+func newHTTPClient(ca string) (client *http.Client, err error) {
+	var pool *x509.CertPool
+
+	if ca != "" {
+		var data []byte
+
+		if data, err = os.ReadFile(ca); err != nil {
+			return nil, fmt.Errorf("error reading the certificate authority '%s': %w", ca, err)
+		}
+
+		pool = x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("error reading the certificate authority '%s': the file does not contain any PEM encoded certificates", ca)
+		}
+	}
+
+	// The client intentionally does not have a timeout as watching a key is a long running request. The timeouts are
+	// implemented using the request contexts instead.
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}, nil
+}
+
+func do(client *http.Client, req *http.Request, name string, statuses ...int) (resp *http.Response, err error) {
+	if resp, err = client.Do(req); err != nil {
+		return nil, fmt.Errorf("error performing request to the %s api: %w", name, err)
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+
+	for _, status := range statuses {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("error performing request to the %s api: %s returned status code %d: %s", name, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func decode(resp *http.Response, name string, v any) (err error) {
+	defer resp.Body.Close()
+
+	if err = json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response from the %s api: %w", name, err)
+	}
+
+	return nil
+}
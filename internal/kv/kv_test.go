@@ -0,0 +1,182 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReference(t *testing.T) {
+	assert.True(t, IsReference("consul://127.0.0.1:8500/authelia/configuration.yml"))
+	assert.True(t, IsReference("CONSUL+HTTPS://consul.example.com/authelia/configuration.yml"))
+	assert.True(t, IsReference("etcd:///authelia/configuration.yml"))
+	assert.True(t, IsReference("etcd+https://etcd.example.com:2379/authelia/configuration.yml"))
+	assert.False(t, IsReference("/etc/authelia/configuration.yml"))
+	assert.False(t, IsReference("vault://secret/authelia"))
+}
+
+func TestNewClientFromReference(t *testing.T) {
+	testCases := []struct {
+		name    string
+		have    string
+		env     map[string]string
+		address string
+		key     string
+		err     string
+	}{
+		{"ShouldParseConsul", "consul://consul.example.com:8500/authelia/configuration.yml", nil, "http://consul.example.com:8500", "authelia/configuration.yml", ""},
+		{"ShouldParseConsulHTTPS", "consul+https://consul.example.com/authelia/configuration.yml", nil, "https://consul.example.com", "authelia/configuration.yml", ""},
+		{"ShouldParseConsulEnvironment", "consul:///authelia/configuration.yml", map[string]string{envConsulHTTPAddr: "https://consul.example.com:8501"}, "https://consul.example.com:8501", "authelia/configuration.yml", ""},
+		{"ShouldParseConsulDefault", "consul:///authelia/configuration.yml", nil, "http://127.0.0.1:8500", "authelia/configuration.yml", ""},
+		{"ShouldParseEtcd", "etcd://etcd.example.com:2379/authelia/configuration.yml", nil, "http://etcd.example.com:2379", "/authelia/configuration.yml", ""},
+		{"ShouldParseEtcdEnvironment", "etcd+https:///authelia/configuration.yml", map[string]string{envEtcdEndpoints: "etcd1.example.com:2379,etcd2.example.com:2379"}, "https://etcd1.example.com:2379", "/authelia/configuration.yml", ""},
+		{"ShouldFailConsulWithoutKey", "consul://consul.example.com:8500/", nil, "", "", "error parsing key value store reference 'consul://consul.example.com:8500/': the reference must have a key"},
+		{"ShouldFailEtcdWithoutKey", "etcd://etcd.example.com:2379", nil, "", "", "error parsing key value store reference 'etcd://etcd.example.com:2379': the reference must have a key"},
+		{"ShouldFailUnsupportedScheme", "zookeeper://zk.example.com/authelia", nil, "", "", "error parsing key value store reference 'zookeeper://zk.example.com/authelia': the scheme 'zookeeper' is not supported"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, env := range []string{envConsulHTTPAddr, envConsulHTTPToken, envConsulCACert, envEtcdEndpoints, envEtcdUser, envEtcdPassword, envEtcdCACert} {
+				t.Setenv(env, tc.env[env])
+			}
+
+			client, key, err := NewClientFromReference(tc.have)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, client)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.key, key)
+
+			switch c := client.(type) {
+			case *ConsulClient:
+				assert.Equal(t, tc.address, c.address.String())
+			case *EtcdClient:
+				assert.Equal(t, tc.address, c.address.String())
+			default:
+				t.Fatalf("unexpected client type %T", client)
+			}
+		})
+	}
+}
+
+func TestConsulClient(t *testing.T) {
+	var queries atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get(headerConsulToken))
+		assert.Equal(t, "dc1", r.URL.Query().Get("dc"))
+
+		switch r.URL.Path {
+		case "/v1/kv/authelia/configuration.yml":
+			switch index := r.URL.Query().Get("index"); {
+			case index == "":
+				w.Header().Set(headerConsulIndex, "10")
+			case queries.Add(1) == 1:
+				// The first blocking query times out without a modification.
+				assert.Equal(t, consulWait, r.URL.Query().Get("wait"))
+
+				w.Header().Set(headerConsulIndex, index)
+			default:
+				w.Header().Set(headerConsulIndex, "12")
+			}
+
+			_, _ = w.Write([]byte("log:\n  level: 'debug'\n"))
+		default:
+			w.Header().Set(headerConsulIndex, "10")
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	address, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client := NewConsulClient(address, "token", "dc1", server.Client())
+
+	value, revision, err := client.Get(context.Background(), "authelia/configuration.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "log:\n  level: 'debug'\n", string(value))
+	assert.Equal(t, uint64(10), revision)
+
+	next, err := client.Watch(context.Background(), "authelia/configuration.yml", revision)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(12), next)
+	assert.Equal(t, int32(2), queries.Load())
+
+	_, _, err = client.Get(context.Background(), "authelia/missing.yml")
+	assert.EqualError(t, err, "error reading key 'authelia/missing.yml' from the consul api: the key does not exist")
+}
+
+func TestEtcdClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == etcdPathAuthenticate {
+			var request etcdAuthenticateRequest
+
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, etcdAuthenticateRequest{Name: "authelia", Password: "secret"}, request)
+
+			_, _ = w.Write([]byte(`{"token":"abc.123"}`))
+
+			return
+		}
+
+		assert.Equal(t, "abc.123", r.Header.Get(headerAuthorize))
+
+		switch r.URL.Path {
+		case etcdPathRange:
+			var request etcdRangeRequest
+
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+			if string(request.Key) != "/authelia/configuration.yml" {
+				_, _ = w.Write([]byte(`{"header":{"revision":"7"}}`))
+
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"header":{"revision":"7"},"kvs":[{"key":"L2F1dGhlbGlhL2NvbmZpZ3VyYXRpb24ueW1s","value":"bG9nOiB7fQo=","mod_revision":"5"}],"count":"1"}`))
+		case etcdPathWatch:
+			var request etcdWatchRequest
+
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, int64(8), request.CreateRequest.StartRevision)
+
+			_, _ = fmt.Fprint(w, `{"result":{"header":{"revision":"7"},"created":true}}`+"\n")
+			_, _ = fmt.Fprint(w, `{"result":{"header":{"revision":"9"},"events":[{"kv":{"key":"L2F1dGhlbGlhL2NvbmZpZ3VyYXRpb24ueW1s","mod_revision":"9"}}]}}`+"\n")
+		}
+	}))
+
+	defer server.Close()
+
+	address, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	client := NewEtcdClient(address, "authelia", "secret", server.Client())
+
+	value, revision, err := client.Get(context.Background(), "/authelia/configuration.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "log: {}\n", string(value))
+	assert.Equal(t, uint64(7), revision)
+
+	next, err := client.Watch(context.Background(), "/authelia/configuration.yml", revision)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(9), next)
+
+	_, _, err = client.Get(context.Background(), "/authelia/missing.yml")
+	assert.EqualError(t, err, "error reading key '/authelia/missing.yml' from the etcd api: the key does not exist")
+}
@@ -0,0 +1,14 @@
+package kv
+
+import (
+	"context"
+)
+
+// Client is a client for a key value store which stores configuration.
+type Client interface {
+	// Get returns the value of the key and the revision of the store the value was read at.
+	Get(ctx context.Context, key string) (value []byte, revision uint64, err error)
+
+	// Watch blocks until the key is modified after the provided revision and returns the new revision.
+	Watch(ctx context.Context, key string, revision uint64) (next uint64, err error)
+}
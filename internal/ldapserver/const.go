@@ -0,0 +1,91 @@
+package ldapserver
+
+import (
+	"errors"
+)
+
+// errSizeLimitExceeded is returned when a search has more entries than the size limit of the search.
+var errSizeLimitExceeded = errors.New("the size limit was exceeded")
+
+const (
+	attributeObjectClass          = "objectClass"
+	attributeUID                  = "uid"
+	attributeCN                   = "cn"
+	attributeOU                   = "ou"
+	attributeDisplayName          = "displayName"
+	attributeGivenName            = "givenName"
+	attributeSN                   = "sn"
+	attributeMail                 = "mail"
+	attributeMemberOf             = "memberOf"
+	attributeMember               = "member"
+	attributeEntryUUID            = "entryUUID"
+	attributeNamingContexts       = "namingContexts"
+	attributeSupportedLDAPVersion = "supportedLDAPVersion"
+	attributeSupportedExtension   = "supportedExtension"
+	attributeVendorName           = "vendorName"
+)
+
+const (
+	objectClassTop                  = "top"
+	objectClassPerson               = "person"
+	objectClassOrganizationalPerson = "organizationalPerson"
+	objectClassInetOrgPerson        = "inetOrgPerson"
+	objectClassGroupOfNames         = "groupOfNames"
+	objectClassOrganizationalUnit   = "organizationalUnit"
+)
+
+const (
+	// selectorAll selects all of the user attributes.
+	selectorAll = "*"
+
+	// selectorOperational selects all of the operational attributes.
+	selectorOperational = "+"
+
+	// selectorNone selects none of the attributes.
+	selectorNone = "1.1"
+)
+
+const (
+	ouUsers  = "users"
+	ouGroups = "groups"
+)
+
+const (
+	oidWhoAmI = "1.3.6.1.4.1.4203.1.11.3"
+)
+
+const (
+	tagExtendedRequestName   = 0
+	tagExtendedResponseValue = 11
+	tagAuthenticationSimple  = 0
+)
+
+const (
+	protocolVersion = 3
+	vendorName      = "Authelia"
+
+	// maxPacketSize is the maximum size of a request, which is far larger than any legitimate bind or search request.
+	maxPacketSize = 1024 * 1024
+
+	pageSize = 100
+)
+
+const (
+	logFieldRemoteIP  = "remote_ip"
+	logFieldUsername  = "username"
+	requestMethodBind = "BIND"
+)
+
+const (
+	msgReadOnly            = "the directory is read-only"
+	msgProtocolVersion     = "only version 3 of the protocol is supported"
+	msgAuthMethod          = "only simple authentication is supported"
+	msgUnauthenticatedBind = "unauthenticated binds are not permitted"
+	msgInvalidCredentials  = "the credentials are invalid"
+	msgInsufficientAccess  = "the bound user is not permitted to search the directory"
+	msgNoSuchObject        = "the base object does not exist"
+	msgInvalidDN           = "the base object is not a valid distinguished name"
+	msgExtendedUnsupported = "the extended operation is not supported"
+	msgMalformedRequest    = "the request is malformed"
+	msgInternalError       = "an internal error occurred"
+)
@@ -0,0 +1,431 @@
+package ldapserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// directory is the layout of the emulated directory. The users are the entries 'uid=<username>,ou=users,<base>' and
+// the groups are the entries 'cn=<name>,ou=groups,<base>'.
+type directory struct {
+	baseDN   *ldap.DN
+	usersDN  *ldap.DN
+	groupsDN *ldap.DN
+
+	storage Storage
+}
+
+func newDirectory(base string, storage Storage) (d *directory, err error) {
+	d = &directory{storage: storage}
+
+	if d.baseDN, err = ldap.ParseDN(base); err != nil {
+		return nil, fmt.Errorf("error parsing the base dn '%s': %w", base, err)
+	}
+
+	if len(d.baseDN.RDNs) == 0 {
+		return nil, fmt.Errorf("error parsing the base dn '%s': the base dn must not be empty", base)
+	}
+
+	d.usersDN, _ = ldap.ParseDN(attributeOU + "=" + ouUsers + "," + d.baseDN.String())
+	d.groupsDN, _ = ldap.ParseDN(attributeOU + "=" + ouGroups + "," + d.baseDN.String())
+
+	return d, nil
+}
+
+// userDN returns the distinguished name of the user.
+func (d *directory) userDN(username string) string {
+	return attributeUID + "=" + ldap.EscapeDN(username) + "," + d.usersDN.String()
+}
+
+// groupDN returns the distinguished name of the group.
+func (d *directory) groupDN(name string) string {
+	return attributeCN + "=" + ldap.EscapeDN(name) + "," + d.groupsDN.String()
+}
+
+// username returns the username of the user with the distinguished name.
+func (d *directory) username(name string) (username string, ok bool) {
+	return d.child(name, d.usersDN, attributeUID)
+}
+
+// child returns the value of the relative distinguished name of the distinguished name if it's an immediate child of
+// the parent with a relative distinguished name of the attribute.
+func (d *directory) child(name string, parent *ldap.DN, attribute string) (value string, ok bool) {
+	dn, err := ldap.ParseDN(name)
+	if err != nil || len(dn.RDNs) != len(parent.RDNs)+1 || !parent.AncestorOfFold(dn) {
+		return "", false
+	}
+
+	if rdn := dn.RDNs[0]; len(rdn.Attributes) == 1 && strings.EqualFold(rdn.Attributes[0].Type, attribute) {
+		return rdn.Attributes[0].Value, true
+	}
+
+	return "", false
+}
+
+// rootDSE returns the entry describing the server.
+func (d *directory) rootDSE() *entry {
+	e := &entry{}
+
+	e.add(attributeObjectClass, objectClassTop)
+	e.add(attributeNamingContexts, d.baseDN.String())
+	e.add(attributeSupportedLDAPVersion, strconv.Itoa(protocolVersion))
+	e.add(attributeSupportedExtension, oidWhoAmI)
+	e.add(attributeVendorName, vendorName)
+
+	return e
+}
+
+// search sends the entries within the scope of the base of the search request which match the filter. It returns the
+// LDAP result code of the search.
+func (d *directory) search(ctx context.Context, req *searchRequest, filter *ber.Packet, send func(e *entry) (err error)) (code uint16, err error) {
+	var base *ldap.DN
+
+	if base, err = ldap.ParseDN(req.base); err != nil {
+		return ldap.LDAPResultInvalidDNSyntax, nil
+	}
+
+	s := &search{directory: d, filter: filter, limit: req.sizeLimit, send: send}
+
+	switch {
+	case base.EqualFold(d.baseDN):
+		err = s.root(ctx, req.scope)
+	case base.EqualFold(d.usersDN):
+		err = s.ou(ctx, req.scope, ouUsers, s.users)
+	case base.EqualFold(d.groupsDN):
+		err = s.ou(ctx, req.scope, ouGroups, s.groups)
+	default:
+		var found bool
+
+		if found, err = s.object(ctx, req.base, req.scope); err == nil && !found {
+			return ldap.LDAPResultNoSuchObject, nil
+		}
+	}
+
+	switch {
+	case errors.Is(err, errSizeLimitExceeded):
+		return ldap.LDAPResultSizeLimitExceeded, nil
+	case err != nil:
+		return ldap.LDAPResultOperationsError, err
+	default:
+		return ldap.LDAPResultSuccess, nil
+	}
+}
+
+// search is the state of a single search.
+type search struct {
+	*directory
+
+	filter *ber.Packet
+	limit  int
+	count  int
+	send   func(e *entry) (err error)
+}
+
+func (s *search) root(ctx context.Context, scope int64) (err error) {
+	if scope != ldap.ScopeSingleLevel {
+		e := &entry{dn: s.baseDN.String()}
+
+		e.add(attributeObjectClass, objectClassTop)
+
+		for _, a := range s.baseDN.RDNs[0].Attributes {
+			e.add(a.Type, a.Value)
+		}
+
+		if err = s.emit(e); err != nil || scope == ldap.ScopeBaseObject {
+			return err
+		}
+	}
+
+	next := ldap.ScopeBaseObject
+
+	if scope == ldap.ScopeWholeSubtree {
+		next = ldap.ScopeWholeSubtree
+	}
+
+	if err = s.ou(ctx, int64(next), ouUsers, s.users); err != nil {
+		return err
+	}
+
+	return s.ou(ctx, int64(next), ouGroups, s.groups)
+}
+
+func (s *search) ou(ctx context.Context, scope int64, name string, children func(ctx context.Context, value string) (found bool, err error)) (err error) {
+	if scope != ldap.ScopeSingleLevel {
+		e := &entry{dn: attributeOU + "=" + name + "," + s.baseDN.String()}
+
+		e.add(attributeObjectClass, objectClassTop, objectClassOrganizationalUnit)
+		e.add(attributeOU, name)
+
+		if err = s.emit(e); err != nil || scope == ldap.ScopeBaseObject {
+			return err
+		}
+	}
+
+	_, err = children(ctx, "")
+
+	return err
+}
+
+// object searches the user or group entry with the distinguished name. The users and groups have no children so a
+// single level search only checks the entry exists.
+func (s *search) object(ctx context.Context, name string, scope int64) (found bool, err error) {
+	if username, ok := s.child(name, s.usersDN, attributeUID); ok {
+		if scope == ldap.ScopeSingleLevel {
+			return s.exists(ctx, model.DirectoryUserFilter{Username: username}, model.DirectoryGroupFilter{})
+		}
+
+		return s.users(ctx, username)
+	}
+
+	if group, ok := s.child(name, s.groupsDN, attributeCN); ok {
+		if scope == ldap.ScopeSingleLevel {
+			return s.exists(ctx, model.DirectoryUserFilter{}, model.DirectoryGroupFilter{DisplayName: group})
+		}
+
+		return s.groups(ctx, group)
+	}
+
+	return false, nil
+}
+
+// exists returns true if the active user or the group which matches the non-empty filter exists.
+func (s *search) exists(ctx context.Context, user model.DirectoryUserFilter, group model.DirectoryGroupFilter) (found bool, err error) {
+	if user.Username != "" {
+		var users []model.DirectoryUser
+
+		if users, err = s.storage.LoadDirectoryUsers(ctx, user, 1, 0); err != nil {
+			return false, fmt.Errorf("failed to load the users: %w", err)
+		}
+
+		return len(users) != 0 && users[0].Active && users[0].Username == user.Username, nil
+	}
+
+	var groups []model.DirectoryGroup
+
+	if groups, err = s.storage.LoadDirectoryGroups(ctx, group, 1, 0); err != nil {
+		return false, fmt.Errorf("failed to load the groups: %w", err)
+	}
+
+	return len(groups) != 0 && strings.EqualFold(groups[0].DisplayName, group.DisplayName), nil
+}
+
+// users sends the active users which match the filter, or only the user with the username if it's not empty. The
+// groups of the users are only loaded for the users which match the filter unless it references the groups.
+func (s *search) users(ctx context.Context, username string) (found bool, err error) {
+	filter := model.DirectoryUserFilter{Username: username}
+
+	if value, ok := filterEquality(s.filter, attributeUID); ok && username == "" {
+		filter.Username = value
+	}
+
+	references := filterReferences(s.filter, attributeMemberOf)
+
+	for offset := 0; ; offset += pageSize {
+		var users []model.DirectoryUser
+
+		if users, err = s.storage.LoadDirectoryUsers(ctx, filter, pageSize, offset); err != nil {
+			return found, fmt.Errorf("failed to load the users: %w", err)
+		}
+
+		for _, user := range users {
+			if !user.Active || (filter.Username != "" && user.Username != filter.Username) {
+				continue
+			}
+
+			found = true
+
+			e := s.user(user)
+
+			if references {
+				if err = s.memberOf(ctx, e, user.Username); err != nil {
+					return found, err
+				}
+			}
+
+			if !s.match(e) {
+				continue
+			}
+
+			if !references {
+				if err = s.memberOf(ctx, e, user.Username); err != nil {
+					return found, err
+				}
+			}
+
+			if err = s.emit(e); err != nil {
+				return found, err
+			}
+		}
+
+		if len(users) < pageSize {
+			return found, nil
+		}
+	}
+}
+
+// groups sends the groups which match the filter, or only the group with the name if it's not empty. The members of
+// the groups are only loaded for the groups which match the filter unless it references the members.
+func (s *search) groups(ctx context.Context, name string) (found bool, err error) {
+	filter := model.DirectoryGroupFilter{DisplayName: name}
+
+	if name == "" {
+		// The groups never match a filter which requires an user attribute.
+		if _, ok := filterEquality(s.filter, attributeUID); ok {
+			return false, nil
+		}
+
+		if value, ok := filterEquality(s.filter, attributeCN); ok {
+			filter.DisplayName = value
+		}
+	}
+
+	references := filterReferences(s.filter, attributeMember)
+
+	for offset := 0; ; offset += pageSize {
+		var groups []model.DirectoryGroup
+
+		if groups, err = s.storage.LoadDirectoryGroups(ctx, filter, pageSize, offset); err != nil {
+			return found, fmt.Errorf("failed to load the groups: %w", err)
+		}
+
+		for _, group := range groups {
+			if filter.DisplayName != "" && !strings.EqualFold(group.DisplayName, filter.DisplayName) {
+				continue
+			}
+
+			found = true
+
+			e := s.group(group)
+
+			if references {
+				if err = s.members(ctx, e, group); err != nil {
+					return found, err
+				}
+			}
+
+			if !s.match(e) {
+				continue
+			}
+
+			if !references {
+				if err = s.members(ctx, e, group); err != nil {
+					return found, err
+				}
+			}
+
+			if err = s.emit(e); err != nil {
+				return found, err
+			}
+		}
+
+		if len(groups) < pageSize {
+			return found, nil
+		}
+	}
+}
+
+func (s *search) user(user model.DirectoryUser) (e *entry) {
+	name := user.DisplayName
+
+	if name == "" {
+		name = user.Username
+	}
+
+	surname := user.FamilyName
+
+	if surname == "" {
+		surname = user.Username
+	}
+
+	e = &entry{dn: s.userDN(user.Username)}
+
+	e.add(attributeObjectClass, objectClassTop, objectClassPerson, objectClassOrganizationalPerson, objectClassInetOrgPerson)
+	e.add(attributeUID, user.Username)
+	e.add(attributeCN, name)
+	e.add(attributeDisplayName, name)
+	e.add(attributeSN, surname)
+
+	if user.GivenName != "" {
+		e.add(attributeGivenName, user.GivenName)
+	}
+
+	e.add(attributeMail, user.Emails...)
+	e.add(attributeEntryUUID, user.PublicID.String())
+
+	return e
+}
+
+func (s *search) group(group model.DirectoryGroup) (e *entry) {
+	e = &entry{dn: s.groupDN(group.DisplayName)}
+
+	e.add(attributeObjectClass, objectClassTop, objectClassGroupOfNames)
+	e.add(attributeCN, group.DisplayName)
+	e.add(attributeEntryUUID, group.PublicID.String())
+
+	return e
+}
+
+func (s *search) memberOf(ctx context.Context, e *entry, username string) (err error) {
+	var groups []model.DirectoryGroup
+
+	if groups, err = s.storage.LoadDirectoryUserGroups(ctx, username); err != nil {
+		return fmt.Errorf("failed to load the groups of user '%s': %w", username, err)
+	}
+
+	values := make([]string, len(groups))
+
+	for i, group := range groups {
+		values[i] = s.groupDN(group.DisplayName)
+	}
+
+	e.add(attributeMemberOf, values...)
+
+	return nil
+}
+
+func (s *search) members(ctx context.Context, e *entry, group model.DirectoryGroup) (err error) {
+	var members []model.DirectoryGroupMember
+
+	if members, err = s.storage.LoadDirectoryGroupMembers(ctx, group.PublicID); err != nil {
+		return fmt.Errorf("failed to load the members of group '%s': %w", group.DisplayName, err)
+	}
+
+	values := make([]string, len(members))
+
+	for i, member := range members {
+		values[i] = s.userDN(member.Username)
+	}
+
+	e.add(attributeMember, values...)
+
+	return nil
+}
+
+// match returns true if the entry matches the filter, a nil filter matches all entries.
+func (s *search) match(e *entry) bool {
+	return s.filter == nil || filterMatch(s.filter, e)
+}
+
+// emit sends the entry if it matches the filter, and returns errSizeLimitExceeded if the size limit of the search has
+// already been reached.
+func (s *search) emit(e *entry) (err error) {
+	if !s.match(e) {
+		return nil
+	}
+
+	if s.limit > 0 && s.count >= s.limit {
+		return errSizeLimitExceeded
+	}
+
+	s.count++
+
+	return s.send(e)
+}
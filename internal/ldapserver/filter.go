@@ -0,0 +1,177 @@
+package ldapserver
+
+import (
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+// filterMatch returns true if the entry matches the filter. The values are compared case-insensitively as all of the
+// attributes of the emulated directory are directory strings or distinguished names, and the extensible matches are
+// never matched.
+func filterMatch(filter *ber.Packet, e *entry) bool {
+	if filter == nil || filter.ClassType != ber.ClassContext {
+		return false
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !filterMatch(child, e) {
+				return false
+			}
+		}
+
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if filterMatch(child, e) {
+				return true
+			}
+		}
+
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !filterMatch(filter.Children[0], e)
+	case ldap.FilterPresent:
+		return len(e.get(filter.Data.String())) != 0
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+		name, value, ok := filterAssertion(filter)
+
+		return ok && filterAny(e.get(name), func(v string) bool { return valueEqual(name, v, value) })
+	case ldap.FilterGreaterOrEqual:
+		name, value, ok := filterAssertion(filter)
+
+		return ok && filterAny(e.get(name), func(v string) bool { return strings.ToLower(v) >= strings.ToLower(value) })
+	case ldap.FilterLessOrEqual:
+		name, value, ok := filterAssertion(filter)
+
+		return ok && filterAny(e.get(name), func(v string) bool { return strings.ToLower(v) <= strings.ToLower(value) })
+	case ldap.FilterSubstrings:
+		if len(filter.Children) != 2 {
+			return false
+		}
+
+		return filterAny(e.get(filter.Children[0].Data.String()), func(v string) bool { return substringsMatch(filter.Children[1], v) })
+	default:
+		return false
+	}
+}
+
+// filterEquality returns the value of the equality assertion on the attribute if the filter only matches the entries
+// with the value, i.e. the filter is the assertion or the assertion is one of the filters of an and filter.
+func filterEquality(filter *ber.Packet, name string) (value string, ok bool) {
+	if filter == nil || filter.ClassType != ber.ClassContext {
+		return "", false
+	}
+
+	switch filter.Tag {
+	case ldap.FilterEqualityMatch:
+		var attribute string
+
+		if attribute, value, ok = filterAssertion(filter); ok && strings.EqualFold(attribute, name) {
+			return value, true
+		}
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if value, ok = filterEquality(child, name); ok {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// filterReferences returns true if the filter references the attribute.
+func filterReferences(filter *ber.Packet, name string) bool {
+	if filter == nil || filter.ClassType != ber.ClassContext {
+		return false
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd, ldap.FilterOr, ldap.FilterNot:
+		for _, child := range filter.Children {
+			if filterReferences(child, name) {
+				return true
+			}
+		}
+
+		return false
+	case ldap.FilterPresent:
+		return strings.EqualFold(filter.Data.String(), name)
+	default:
+		return len(filter.Children) != 0 && strings.EqualFold(filter.Children[0].Data.String(), name)
+	}
+}
+
+// filterAssertion returns the attribute and value of an attribute value assertion.
+func filterAssertion(filter *ber.Packet) (name, value string, ok bool) {
+	if len(filter.Children) != 2 {
+		return "", "", false
+	}
+
+	return filter.Children[0].Data.String(), filter.Children[1].Data.String(), true
+}
+
+func filterAny(values []string, fn func(value string) bool) bool {
+	for _, value := range values {
+		if fn(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valueEqual compares the values of the attribute, the distinguished names are compared after they're parsed so the
+// differences in the formatting such as the spaces between the components are ignored.
+func valueEqual(name, a, b string) bool {
+	if strings.EqualFold(name, attributeMember) || strings.EqualFold(name, attributeMemberOf) {
+		dnA, errA := ldap.ParseDN(a)
+		dnB, errB := ldap.ParseDN(b)
+
+		if errA == nil && errB == nil {
+			return dnA.EqualFold(dnB)
+		}
+	}
+
+	return strings.EqualFold(a, b)
+}
+
+// substringsMatch returns true if the value matches the initial, any, and final substrings in order.
+func substringsMatch(substrings *ber.Packet, value string) bool {
+	value = strings.ToLower(value)
+
+	for _, substring := range substrings.Children {
+		s := strings.ToLower(substring.Data.String())
+
+		switch substring.Tag {
+		case ldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, s) {
+				return false
+			}
+
+			value = value[len(s):]
+		case ldap.FilterSubstringsAny:
+			i := strings.Index(value, s)
+
+			if i < 0 {
+				return false
+			}
+
+			value = value[i+len(s):]
+		case ldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, s) {
+				return false
+			}
+
+			value = ""
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package ldapserver
+
+import (
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	e := &entry{dn: "uid=john,ou=users,dc=example,dc=com"}
+
+	e.add(attributeObjectClass, objectClassTop, objectClassPerson, objectClassOrganizationalPerson, objectClassInetOrgPerson)
+	e.add(attributeUID, "john")
+	e.add(attributeCN, "John Doe")
+	e.add(attributeMail, "john.doe@example.com")
+	e.add(attributeMemberOf, "cn=admins,ou=groups,dc=example,dc=com")
+
+	testCases := []struct {
+		name     string
+		filter   string
+		expected bool
+	}{
+		{"ShouldMatchPresent", "(objectClass=*)", true},
+		{"ShouldNotMatchPresentMissing", "(givenName=*)", false},
+		{"ShouldMatchEqualityCaseInsensitive", "(objectClass=InetOrgPerson)", true},
+		{"ShouldNotMatchEquality", "(uid=jane)", false},
+		{"ShouldMatchAttributeNameCaseInsensitive", "(UID=john)", true},
+		{"ShouldMatchAnd", "(&(objectClass=inetOrgPerson)(uid=john))", true},
+		{"ShouldNotMatchAnd", "(&(objectClass=groupOfNames)(uid=john))", false},
+		{"ShouldMatchOr", "(|(uid=jane)(mail=john.doe@example.com))", true},
+		{"ShouldMatchNot", "(!(uid=jane))", true},
+		{"ShouldNotMatchNot", "(!(uid=john))", false},
+		{"ShouldMatchSubstringsInitial", "(cn=John*)", true},
+		{"ShouldMatchSubstringsAny", "(mail=*doe@*)", true},
+		{"ShouldMatchSubstringsFinal", "(mail=*@example.com)", true},
+		{"ShouldNotMatchSubstrings", "(mail=*@example.org)", false},
+		{"ShouldMatchMemberOfDN", "(memberOf=CN=admins, OU=groups, DC=example, DC=com)", true},
+		{"ShouldNotMatchMemberOfDN", "(memberOf=cn=users,ou=groups,dc=example,dc=com)", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ldap.CompileFilter(tc.filter)
+			require.NoError(t, err)
+
+			// Decode the encoded filter so its the same as the filter of a request read from a connection.
+			decoded, err := ber.DecodePacketErr(filter.Bytes())
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, filterMatch(decoded, e))
+		})
+	}
+}
+
+func TestFilterEquality(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   string
+		expected string
+		ok       bool
+	}{
+		{"ShouldReturnEquality", "(uid=john)", "john", true},
+		{"ShouldReturnEqualityOfAnd", "(&(objectClass=inetOrgPerson)(uid=john))", "john", true},
+		{"ShouldNotReturnEqualityOfOr", "(|(uid=john)(uid=jane))", "", false},
+		{"ShouldNotReturnOtherAttribute", "(cn=john)", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := ldap.CompileFilter(tc.filter)
+			require.NoError(t, err)
+
+			decoded, err := ber.DecodePacketErr(filter.Bytes())
+			require.NoError(t, err)
+
+			value, ok := filterEquality(decoded, attributeUID)
+
+			assert.Equal(t, tc.expected, value)
+			assert.Equal(t, tc.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package ldapserver
+
+import (
+	"context"
+	"net"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+)
+
+// regulationCtx is the regulation.Context of a bind.
+type regulationCtx struct {
+	context.Context
+
+	server *Server
+	ip     net.IP
+}
+
+// RemoteIP returns the IP of the client which performed the bind.
+func (ctx *regulationCtx) RemoteIP() (ip net.IP) {
+	return ctx.ip
+}
+
+// AuditEvent emits an audit event of the given type for the bind.
+func (ctx *regulationCtx) AuditEvent(eventType, result, username string, details map[string]any) {
+	if ctx.server.providers.Audit == nil {
+		return
+	}
+
+	ctx.server.providers.Audit.Emit(audit.Event{
+		Time:   ctx.server.providers.Clock.Now(),
+		Type:   eventType,
+		Result: result,
+		Actor: audit.EventActor{
+			Username: username,
+			RemoteIP: ctx.ip.String(),
+		},
+		Request: audit.EventRequest{
+			Method: requestMethodBind,
+		},
+		Details: details,
+	})
+}
+
+// RecordAuthn records the metrics of the bind.
+func (ctx *regulationCtx) RecordAuthn(success, banned bool, authType string) {
+	if ctx.server.providers.Metrics == nil {
+		return
+	}
+
+	ctx.server.providers.Metrics.RecordAuthn(success, banned, authType)
+}
+
+// RecordAuthnBan records the metrics of a ban issued by the regulator.
+func (ctx *regulationCtx) RecordAuthnBan(authType string) {
+	if ctx.server.providers.Metrics == nil {
+		return
+	}
+
+	ctx.server.providers.Metrics.RecordAuthnBan(authType)
+}
+
+// RecordAuthnAnomaly records the metrics of an anomaly detected by the regulator.
+func (ctx *regulationCtx) RecordAuthnAnomaly(pattern string) {
+	if ctx.server.providers.Metrics == nil {
+		return
+	}
+
+	ctx.server.providers.Metrics.RecordAuthnAnomaly(pattern)
+}
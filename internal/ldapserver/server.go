@@ -0,0 +1,313 @@
+package ldapserver
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewServer returns a new Server which emulates a read-only directory of the users and groups of the SQL
+// authentication backend.
+func NewServer(config *schema.ServerLDAP, providers Providers, log *logrus.Entry) (server *Server, err error) {
+	server = &Server{
+		config:    config,
+		providers: providers,
+		conns:     map[net.Conn]struct{}{},
+		log:       log,
+	}
+
+	if server.directory, err = newDirectory(config.BaseDN, providers.Storage); err != nil {
+		return nil, err
+	}
+
+	if config.TLS.Certificate != "" && config.TLS.Key != "" {
+		if server.tls, err = newTLSConfig(&config.TLS); err != nil {
+			return nil, err
+		}
+	}
+
+	return server, nil
+}
+
+// Server is a minimal LDAP server which supports the simple bind and search operations.
+type Server struct {
+	config    *schema.ServerLDAP
+	providers Providers
+	directory *directory
+	tls       *tls.Config
+
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+
+	log *logrus.Entry
+}
+
+// IsTLS returns true if the Server serves the connections over TLS.
+func (s *Server) IsTLS() bool {
+	return s.tls != nil
+}
+
+// Serve accepts the connections on the listener until the Server is shutdown.
+func (s *Server) Serve(listener net.Listener) (err error) {
+	if s.tls != nil {
+		listener = tls.NewListener(listener, s.tls)
+	}
+
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return listener.Close()
+	}
+
+	s.listener = listener
+
+	s.mu.Unlock()
+
+	for {
+		var conn net.Conn
+
+		if conn, err = listener.Accept(); err != nil {
+			if s.isClosed() {
+				return nil
+			}
+
+			var ne net.Error
+
+			if errors.As(err, &ne) && ne.Timeout() {
+				time.Sleep(time.Millisecond * 100)
+
+				continue
+			}
+
+			return err
+		}
+
+		if !s.track(conn, true) {
+			_ = conn.Close()
+
+			return nil
+		}
+
+		s.wg.Add(1)
+
+		go s.serve(conn)
+	}
+}
+
+// Shutdown stops accepting new connections, closes the open connections, and waits for the connections to finish.
+func (s *Server) Shutdown(ctx context.Context) (err error) {
+	s.mu.Lock()
+
+	s.closed = true
+
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// track adds or removes the connection from the open connections, it returns false if the Server is shutdown.
+func (s *Server) track(conn net.Conn, add bool) bool {
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	if !add {
+		delete(s.conns, conn)
+
+		return true
+	}
+
+	if s.closed {
+		return false
+	}
+
+	s.conns[conn] = struct{}{}
+
+	return true
+}
+
+func (s *Server) serve(conn net.Conn) {
+	c := &session{
+		server: s,
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		ip:     remoteIP(conn),
+	}
+
+	c.log = s.log.WithField(logFieldRemoteIP, c.ip.String())
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.log.WithField("panic", r).Error("Critical error caught (recovered) while serving the connection")
+		}
+
+		_ = conn.Close()
+
+		s.track(conn, false)
+
+		s.wg.Done()
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	c.log.Trace("Connection accepted")
+
+	for {
+		if s.config.IdleTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.config.IdleTimeout))
+		}
+
+		packet, err := ber.ReadPacket(io.LimitReader(c.reader, maxPacketSize))
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.log.WithError(err).Debug("Connection closed after an error reading the request")
+			}
+
+			return
+		}
+
+		if !c.handle(ctx, packet) {
+			c.log.Trace("Connection closed")
+
+			return
+		}
+	}
+}
+
+// remoteIP returns the IP of the remote end of the connection, the connections of a unix domain socket are local.
+func remoteIP(conn net.Conn) net.IP {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+
+	return net.IPv4(127, 0, 0, 1)
+}
+
+func newTLSConfig(config *schema.ServerTLS) (c *tls.Config, err error) {
+	var certificate tls.Certificate
+
+	if certificate, err = tls.LoadX509KeyPair(config.Certificate, config.Key); err != nil {
+		return nil, fmt.Errorf("unable to load tls server certificate '%s' or private key '%s': %w", config.Certificate, config.Key, err)
+	}
+
+	c = &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if len(config.ClientCertificates) == 0 {
+		return c, nil
+	}
+
+	pool := x509.NewCertPool()
+
+	for _, path := range config.ClientCertificates {
+		var data []byte
+
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("unable to load tls client certificate '%s': %w", path, err)
+		}
+
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("unable to load tls client certificate '%s': the file does not contain a PEM encoded certificate", path)
+		}
+	}
+
+	c.ClientCAs = pool
+	c.ClientAuth = tls.RequireAndVerifyClientCert
+
+	return c, nil
+}
+
+// newResult returns the LDAPResult of a response operation with the tag.
+func newResult(tag ber.Tag, code uint16, message string) (op *ber.Packet) {
+	op = ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, ldap.ApplicationMap[uint8(tag)])
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+
+	return op
+}
+
+// newSearchResultEntry returns the SearchResultEntry operation of the entry with the attributes selected by the
+// search request.
+func newSearchResultEntry(e *entry, req *searchRequest) (op *ber.Packet) {
+	op = ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "Object Name"))
+
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+
+	for _, a := range e.attributes {
+		if !req.selects(a) {
+			continue
+		}
+
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, a.name, "Type"))
+
+		values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+
+		if !req.typesOnly {
+			for _, value := range a.values {
+				values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+		}
+
+		attribute.AppendChild(values)
+		attributes.AppendChild(attribute)
+	}
+
+	op.AppendChild(attributes)
+
+	return op
+}
@@ -0,0 +1,177 @@
+package ldapserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func setupServer(t *testing.T, config *schema.ServerLDAP) (client *ldap.Conn, storage *mocks.MockStorage, users *mocks.MockUserProvider) {
+	ctrl := gomock.NewController(t)
+
+	storage = mocks.NewMockStorage(ctrl)
+	users = mocks.NewMockUserProvider(ctrl)
+
+	c := clock.NewFixed(time.Unix(1700000000, 0))
+
+	server, err := NewServer(config, Providers{
+		UserProvider: users,
+		Storage:      storage,
+		Regulator:    regulation.NewRegulator(schema.Regulation{}, storage, c),
+		Clock:        c,
+	}, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+
+		defer cancel()
+
+		assert.NoError(t, server.Shutdown(ctx))
+	})
+
+	client, err = ldap.DialURL("ldap://" + listener.Addr().String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client, storage, users
+}
+
+func newTestConfig() *schema.ServerLDAP {
+	return &schema.ServerLDAP{
+		Enabled:     true,
+		BaseDN:      "dc=example,dc=com",
+		SizeLimit:   500,
+		IdleTimeout: time.Minute,
+	}
+}
+
+func TestNewServerShouldErrorOnEmptyBaseDN(t *testing.T) {
+	server, err := NewServer(&schema.ServerLDAP{}, Providers{}, logrus.NewEntry(logrus.New()))
+
+	assert.Nil(t, server)
+	assert.Error(t, err)
+}
+
+func TestServerShouldBindAndSearch(t *testing.T) {
+	client, storage, users := setupServer(t, newTestConfig())
+
+	id := uuid.MustParse("9b1de3a1-0a0d-4f87-9b8a-6f3f69a3d1f1")
+
+	gomock.InOrder(
+		users.EXPECT().CheckUserPassword("john", "password").Return(true, nil),
+		storage.EXPECT().AppendAuthenticationLog(gomock.Any(), gomock.Any()).Return(nil),
+		storage.EXPECT().
+			LoadDirectoryUsers(gomock.Any(), model.DirectoryUserFilter{Username: "john"}, pageSize, 0).
+			Return([]model.DirectoryUser{{PublicID: id, Username: "john", DisplayName: "John Doe", FamilyName: "Doe", GivenName: "John", Emails: []string{"john.doe@example.com"}, Active: true}}, nil),
+		storage.EXPECT().
+			LoadDirectoryUserGroups(gomock.Any(), "john").
+			Return([]model.DirectoryGroup{{DisplayName: "admins"}}, nil),
+	)
+
+	require.NoError(t, client.Bind("uid=john,ou=users,dc=example,dc=com", "password"))
+
+	result, err := client.Search(ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(&(objectClass=inetOrgPerson)(uid=john))", []string{"uid", "mail", "memberOf", "entryUUID"}, nil))
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+
+	entry := result.Entries[0]
+
+	assert.Equal(t, "uid=john,ou=users,dc=example,dc=com", entry.DN)
+	assert.Equal(t, "john", entry.GetAttributeValue("uid"))
+	assert.Equal(t, "john.doe@example.com", entry.GetAttributeValue("mail"))
+	assert.Equal(t, []string{"cn=admins,ou=groups,dc=example,dc=com"}, entry.GetAttributeValues("memberOf"))
+	assert.Equal(t, id.String(), entry.GetAttributeValue("entryUUID"))
+	assert.Empty(t, entry.GetAttributeValues("cn"))
+
+	whoami, err := client.WhoAmI(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "dn:uid=john,ou=users,dc=example,dc=com", whoami.AuthzID)
+}
+
+func TestServerShouldRejectInvalidCredentials(t *testing.T) {
+	client, storage, users := setupServer(t, newTestConfig())
+
+	gomock.InOrder(
+		users.EXPECT().CheckUserPassword("john", "bad").Return(false, nil),
+		storage.EXPECT().AppendAuthenticationLog(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	err := client.Bind("uid=john,ou=users,dc=example,dc=com", "bad")
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials))
+
+	err = client.Bind("cn=john,dc=other,dc=com", "bad")
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials))
+
+	err = client.UnauthenticatedBind("uid=john,ou=users,dc=example,dc=com")
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform))
+}
+
+func TestServerShouldRestrictAnonymousConnections(t *testing.T) {
+	client, _, _ := setupServer(t, newTestConfig())
+
+	result, err := client.Search(ldap.NewSearchRequest("", ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", []string{"namingContexts", "supportedLDAPVersion"}, nil))
+	require.NoError(t, err)
+	require.Len(t, result.Entries, 1)
+
+	assert.Equal(t, "dc=example,dc=com", result.Entries[0].GetAttributeValue("namingContexts"))
+	assert.Equal(t, "3", result.Entries[0].GetAttributeValue("supportedLDAPVersion"))
+
+	_, err = client.Search(ldap.NewSearchRequest("dc=example,dc=com", ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", nil, nil))
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights))
+}
+
+func TestServerShouldRestrictSearchToSearchGroups(t *testing.T) {
+	config := newTestConfig()
+	config.SearchGroups = []string{"ldap-search"}
+
+	client, storage, users := setupServer(t, config)
+
+	gomock.InOrder(
+		users.EXPECT().CheckUserPassword("john", "password").Return(true, nil),
+		storage.EXPECT().AppendAuthenticationLog(gomock.Any(), gomock.Any()).Return(nil),
+		storage.EXPECT().LoadDirectoryUserGroups(gomock.Any(), "john").Return([]model.DirectoryGroup{{DisplayName: "admins"}}, nil),
+	)
+
+	require.NoError(t, client.Bind("uid=john,ou=users,dc=example,dc=com", "password"))
+
+	_, err := client.Search(ldap.NewSearchRequest("ou=users,dc=example,dc=com", ldap.ScopeSingleLevel, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)", nil, nil))
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultInsufficientAccessRights))
+}
+
+func TestServerShouldRejectWrites(t *testing.T) {
+	client, _, _ := setupServer(t, newTestConfig())
+
+	err := client.Del(ldap.NewDelRequest("uid=john,ou=users,dc=example,dc=com", nil))
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform))
+
+	add := ldap.NewAddRequest("uid=jane,ou=users,dc=example,dc=com", nil)
+	add.Attribute("uid", []string{"jane"})
+
+	err = client.Add(add)
+	assert.True(t, ldap.IsErrorWithCode(err, ldap.LDAPResultUnwillingToPerform))
+}
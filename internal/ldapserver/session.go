@@ -0,0 +1,298 @@
+package ldapserver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// session is the state of a single connection. The username is the user the connection is bound as, which is empty
+// for an anonymous connection which may only read the root DSE.
+type session struct {
+	server *Server
+	conn   net.Conn
+	reader *bufio.Reader
+	ip     net.IP
+
+	username string
+	search   bool
+
+	log *logrus.Entry
+}
+
+// handle performs the operation of the request, it returns false if the connection must be closed.
+func (c *session) handle(ctx context.Context, packet *ber.Packet) bool {
+	if len(packet.Children) < 2 {
+		return false
+	}
+
+	id, ok := packet.Children[0].Value.(int64)
+	if !ok {
+		return false
+	}
+
+	op := packet.Children[1]
+
+	if op.ClassType != ber.ClassApplication {
+		return false
+	}
+
+	switch op.Tag {
+	case ldap.ApplicationBindRequest:
+		return c.reply(id, c.bind(ctx, op))
+	case ldap.ApplicationUnbindRequest:
+		return false
+	case ldap.ApplicationSearchRequest:
+		return c.searchRequest(ctx, id, op)
+	case ldap.ApplicationExtendedRequest:
+		return c.reply(id, c.extended(op))
+	case ldap.ApplicationAbandonRequest:
+		// The operations are performed in order so there is never an outstanding operation to abandon.
+		return true
+	case ldap.ApplicationModifyRequest, ldap.ApplicationAddRequest, ldap.ApplicationDelRequest, ldap.ApplicationModifyDNRequest, ldap.ApplicationCompareRequest:
+		return c.reply(id, newResult(op.Tag+1, ldap.LDAPResultUnwillingToPerform, msgReadOnly))
+	default:
+		return false
+	}
+}
+
+// bind performs a simple bind. A failed bind leaves the connection anonymous.
+func (c *session) bind(ctx context.Context, op *ber.Packet) (response *ber.Packet) {
+	c.username, c.search = "", false
+
+	if len(op.Children) != 3 {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, msgMalformedRequest)
+	}
+
+	if version, ok := op.Children[0].Value.(int64); !ok || version != protocolVersion {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultProtocolError, msgProtocolVersion)
+	}
+
+	name, auth := op.Children[1].Data.String(), op.Children[2]
+
+	if auth.ClassType != ber.ClassContext || auth.Tag != tagAuthenticationSimple {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultAuthMethodNotSupported, msgAuthMethod)
+	}
+
+	password := auth.Data.String()
+
+	switch {
+	case name == "" && password == "":
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+	case password == "":
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultUnwillingToPerform, msgUnauthenticatedBind)
+	}
+
+	username, ok := c.server.directory.username(name)
+	if !ok {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, msgInvalidCredentials)
+	}
+
+	log := c.log.WithField(logFieldUsername, username)
+
+	if !c.authenticate(ctx, username, password, log) {
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultInvalidCredentials, msgInvalidCredentials)
+	}
+
+	search, err := c.permitted(ctx, username)
+	if err != nil {
+		log.WithError(err).Error("Error occurred loading the groups of the user")
+
+		return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultOperationsError, msgInternalError)
+	}
+
+	c.username, c.search = username, search
+
+	log.Debug("Bind was successful")
+
+	return newResult(ldap.ApplicationBindResponse, ldap.LDAPResultSuccess, "")
+}
+
+// authenticate checks the password of the user and marks the attempt, the attempts are regulated the same as the
+// first factor of the portal so the binds can't be used to bypass the regulation.
+func (c *session) authenticate(ctx context.Context, username, password string, log *logrus.Entry) (valid bool) {
+	rctx := &regulationCtx{Context: ctx, server: c.server, ip: c.ip}
+
+	var (
+		until time.Time
+		err   error
+	)
+
+	if until, err = c.server.providers.Regulator.Regulate(ctx, username); err != nil && errors.Is(err, regulation.ErrUserIsBanned) {
+		log.WithField("until", until).Debug("Bind was rejected as the user is banned")
+
+		c.mark(rctx, false, true, username, log)
+
+		return false
+	}
+
+	if valid, err = c.server.providers.UserProvider.CheckUserPassword(username, password); err != nil && !errors.Is(err, authentication.ErrUserNotFound) {
+		log.WithError(err).Error("Error occurred checking the password of the user")
+	}
+
+	c.mark(rctx, valid && err == nil, false, username, log)
+
+	return valid && err == nil
+}
+
+func (c *session) mark(rctx *regulationCtx, successful, banned bool, username string, log *logrus.Entry) {
+	if err := c.server.providers.Regulator.Mark(rctx, successful, banned, username, "", requestMethodBind, regulation.AuthTypeLDAP); err != nil {
+		log.WithError(err).Error("Unable to mark the bind attempt")
+	}
+
+	rctx.AuditEvent(audit.EventTypeAuthenticationFirstFactor, audit.NewResult(successful), username, map[string]any{"method": regulation.AuthTypeLDAP, "banned": banned})
+}
+
+// permitted returns true if the user is permitted to search the directory.
+func (c *session) permitted(ctx context.Context, username string) (permitted bool, err error) {
+	if len(c.server.config.SearchGroups) == 0 {
+		return true, nil
+	}
+
+	groups, err := c.server.providers.Storage.LoadDirectoryUserGroups(ctx, username)
+	if err != nil {
+		return false, err
+	}
+
+	for _, group := range groups {
+		if utils.IsStringInSliceFold(group.DisplayName, c.server.config.SearchGroups) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// searchRequest performs a search, the root DSE may be read anonymously but all other searches require the
+// connection to be bound as a user permitted to search.
+func (c *session) searchRequest(ctx context.Context, id int64, op *ber.Packet) bool {
+	req, filter, ok := decodeSearchRequest(op)
+	if !ok {
+		return c.reply(id, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, msgMalformedRequest))
+	}
+
+	if req.sizeLimit <= 0 || req.sizeLimit > c.server.config.SizeLimit {
+		req.sizeLimit = c.server.config.SizeLimit
+	}
+
+	send := func(e *entry) (err error) {
+		return c.write(id, newSearchResultEntry(e, req))
+	}
+
+	if req.base == "" && req.scope == ldap.ScopeBaseObject {
+		if e := c.server.directory.rootDSE(); filterMatch(filter, e) {
+			if err := send(e); err != nil {
+				return false
+			}
+		}
+
+		return c.reply(id, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+	}
+
+	if !c.search {
+		return c.reply(id, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights, msgInsufficientAccess))
+	}
+
+	code, err := c.server.directory.search(ctx, req, filter, send)
+
+	var message string
+
+	switch {
+	case err != nil:
+		c.log.WithError(err).WithField(logFieldUsername, c.username).Error("Error occurred performing the search")
+
+		message = msgInternalError
+	case code == ldap.LDAPResultNoSuchObject:
+		message = msgNoSuchObject
+	case code == ldap.LDAPResultInvalidDNSyntax:
+		message = msgInvalidDN
+	}
+
+	return c.reply(id, newResult(ldap.ApplicationSearchResultDone, code, message))
+}
+
+// extended performs the Who am I? extended operation, the other extended operations such as StartTLS are not
+// supported.
+func (c *session) extended(op *ber.Packet) (response *ber.Packet) {
+	if len(op.Children) == 0 || op.Children[0].ClassType != ber.ClassContext || op.Children[0].Tag != tagExtendedRequestName {
+		return newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, msgMalformedRequest)
+	}
+
+	if op.Children[0].Data.String() != oidWhoAmI {
+		return newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, msgExtendedUnsupported)
+	}
+
+	var authzid string
+
+	if c.username != "" {
+		authzid = "dn:" + c.server.directory.userDN(c.username)
+	}
+
+	response = newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, "")
+	response.AppendChild(ber.NewString(ber.ClassContext, ber.TypePrimitive, tagExtendedResponseValue, authzid, "Response Value"))
+
+	return response
+}
+
+// reply writes the response and returns false if it could not be written.
+func (c *session) reply(id int64, op *ber.Packet) bool {
+	return c.write(id, op) == nil
+}
+
+func (c *session) write(id int64, op *ber.Packet) (err error) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	packet.AppendChild(op)
+
+	if c.server.config.IdleTimeout > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.server.config.IdleTimeout))
+	}
+
+	if _, err = c.conn.Write(packet.Bytes()); err != nil {
+		c.log.WithError(err).Debug("Error occurred writing the response")
+	}
+
+	return err
+}
+
+// decodeSearchRequest decodes the SearchRequest operation.
+func decodeSearchRequest(op *ber.Packet) (req *searchRequest, filter *ber.Packet, ok bool) {
+	if len(op.Children) != 8 {
+		return nil, nil, false
+	}
+
+	req = &searchRequest{base: op.Children[0].Data.String()}
+
+	var sizeLimit int64
+
+	if req.scope, ok = op.Children[1].Value.(int64); !ok {
+		return nil, nil, false
+	}
+
+	if sizeLimit, ok = op.Children[3].Value.(int64); !ok {
+		return nil, nil, false
+	}
+
+	if req.typesOnly, ok = op.Children[5].Value.(bool); !ok {
+		return nil, nil, false
+	}
+
+	req.sizeLimit = int(sizeLimit)
+
+	for _, attribute := range op.Children[7].Children {
+		req.attributes = append(req.attributes, attribute.Data.String())
+	}
+
+	return req, op.Children[6], true
+}
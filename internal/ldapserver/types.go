@@ -0,0 +1,101 @@
+package ldapserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/metrics"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+// Storage is a cut down version of the storage.Provider interface with just the methods the Server uses to read the
+// directory.
+type Storage interface {
+	LoadDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter, limit, offset int) (users []model.DirectoryUser, err error)
+	LoadDirectoryUserGroups(ctx context.Context, username string) (groups []model.DirectoryGroup, err error)
+	LoadDirectoryGroups(ctx context.Context, filter model.DirectoryGroupFilter, limit, offset int) (groups []model.DirectoryGroup, err error)
+	LoadDirectoryGroupMembers(ctx context.Context, publicID uuid.UUID) (members []model.DirectoryGroupMember, err error)
+}
+
+// Providers are the providers used by the Server. The Metrics and Audit providers are optional.
+type Providers struct {
+	UserProvider authentication.UserProvider
+	Storage      Storage
+	Regulator    *regulation.Regulator
+	Metrics      metrics.Provider
+	Audit        audit.Provider
+	Clock        clock.Provider
+}
+
+// entry is an entry of the emulated directory.
+type entry struct {
+	dn         string
+	attributes []attribute
+}
+
+// get returns the values of the attribute with the given name which is matched case-insensitively.
+func (e *entry) get(name string) (values []string) {
+	for _, a := range e.attributes {
+		if strings.EqualFold(a.name, name) {
+			return a.values
+		}
+	}
+
+	return nil
+}
+
+// add appends the attribute to the entry if it has any values.
+func (e *entry) add(name string, values ...string) {
+	if len(values) == 0 {
+		return
+	}
+
+	e.attributes = append(e.attributes, attribute{name: name, values: values})
+}
+
+// attribute is an attribute of an entry of the emulated directory.
+type attribute struct {
+	name   string
+	values []string
+}
+
+// isOperational returns true if the attribute is an operational attribute which is only returned when it's explicitly
+// requested or the operational attributes are selected.
+func (a attribute) isOperational() bool {
+	return strings.EqualFold(a.name, attributeEntryUUID)
+}
+
+// searchRequest is a decoded search request.
+type searchRequest struct {
+	base       string
+	scope      int64
+	sizeLimit  int
+	typesOnly  bool
+	attributes []string
+}
+
+// selects returns true if the attribute is selected by the attributes of the search request.
+func (r *searchRequest) selects(a attribute) bool {
+	if len(r.attributes) == 0 {
+		return !a.isOperational()
+	}
+
+	for _, selector := range r.attributes {
+		switch {
+		case selector == selectorNone:
+			continue
+		case selector == selectorAll && !a.isOperational(), selector == selectorOperational && a.isOperational():
+			return true
+		case strings.EqualFold(selector, a.name):
+			return true
+		}
+	}
+
+	return false
+}
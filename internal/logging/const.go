@@ -11,6 +11,9 @@ import (
 const (
 	FormatText = "text"
 	FormatJSON = "json"
+	FormatCEF  = "cef"
+	FormatLEEF = "leef"
+	FormatECS  = "ecs"
 )
 
 type LogLevel string
@@ -41,6 +44,17 @@ func (l LogLevel) Level() logrus.Level {
 	}
 }
 
+// Module names.
+const (
+	ModuleStorage       = "storage"
+	ModuleLDAP          = "ldap"
+	ModuleOpenIDConnect = "oidc"
+	ModuleSession       = "session"
+)
+
+// Modules is the list of the modules which have their own logger.
+var Modules = []string{ModuleStorage, ModuleLDAP, ModuleOpenIDConnect, ModuleSession}
+
 // Field names.
 const (
 	FieldRemoteIP   = "remote_ip"
@@ -48,9 +62,91 @@ const (
 	FieldPath       = "path"
 	FieldPathRaw    = "path_raw"
 	FieldStatusCode = "status_code"
+	FieldUsername   = "username"
+)
+
+const (
+	fieldStack  = "stack"
+	fieldCaller = "caller"
+)
+
+const (
+	formatterVendor  = "Authelia"
+	formatterProduct = "Authelia"
+	formatterService = "authelia"
+
+	cefVersion  = 0
+	leefVersion = "1.0"
+	ecsVersion  = "8.11.0"
+
+	leefTimeFormat     = "MMM dd yyyy HH:mm:ss.SSS Z"
+	leefTimeFormatGo   = "Jan 02 2006 15:04:05.000 -0700"
+	ecsTimestampFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// SyslogFacilities maps the syslog facility names to their codes.
+var SyslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+const (
+	syslogSeverityCritical = 2
+	syslogSeverityError    = 3
+	syslogSeverityWarning  = 4
+	syslogSeverityInfo     = 6
+	syslogSeverityDebug    = 7
+
+	syslogVersion    = 1
+	syslogNil        = "-"
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	syslogQueueSize  = 1024
+)
+
+const (
+	journaldSocketPath       = "/run/systemd/journal/socket"
+	journaldFieldMessage     = "MESSAGE"
+	journaldFieldPriority    = "PRIORITY"
+	journaldFieldIdentifier  = "SYSLOG_IDENTIFIER"
+	journaldIdentifier       = "authelia"
+	journaldFieldNameMaxSize = 64
+)
+
+const (
+	redactionKeyContext = "authelia log redaction"
+	redactionHashLength = 16
+	redactionMask       = "***"
 )
 
 var (
+	version = "unknown"
+
+	outputs        = &outputHook{}
+	modules        = &moduleLoggers{}
+	redactions     = &redactionHook{}
+	outputHookOnce sync.Once
+
 	stacktrace       sync.Once
 	reFormatFilePath = regexp.MustCompile(`(%d|\{datetime(:([^}]+))?})`)
+	reRedactUsername = regexp.MustCompile(`\b[Uu]ser(name)? '[^']*'`)
+	reRedactEmail    = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	reRedactIP       = regexp.MustCompile(`(?:[0-9A-Fa-f]{0,4}:){2,7}(?:[0-9A-Fa-f]{1,4}|\d{1,3}(?:\.\d{1,3}){3})?|\b\d{1,3}(?:\.\d{1,3}){3}\b`)
 )
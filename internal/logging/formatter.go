@@ -0,0 +1,256 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetVersion sets the version reported by the CEF, LEEF, and ECS formatters.
+func SetVersion(v string) {
+	version = v
+}
+
+// NewFormatter returns the logrus.Formatter for the given format. The file option disables the colors and enables the
+// full timestamps of the text format.
+func NewFormatter(format string, file bool) logrus.Formatter {
+	switch format {
+	case FormatJSON:
+		return &logrus.JSONFormatter{}
+	case FormatCEF:
+		return &CEFFormatter{Version: version}
+	case FormatLEEF:
+		return &LEEFFormatter{Version: version}
+	case FormatECS:
+		return &ECSFormatter{Version: version}
+	default:
+		if file {
+			return &logrus.TextFormatter{
+				DisableColors: true,
+				FullTimestamp: true,
+			}
+		}
+
+		return &logrus.TextFormatter{}
+	}
+}
+
+// CEFFormatter is a logrus.Formatter which formats the entries in the ArcSight Common Event Format.
+type CEFFormatter struct {
+	Version string
+}
+
+// Format the entry.
+func (f *CEFFormatter) Format(entry *logrus.Entry) (data []byte, err error) {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "CEF:%d|%s|%s|%s|%s|%s|%d|",
+		cefVersion, cefEscapeHeader(formatterVendor), cefEscapeHeader(formatterProduct), cefEscapeHeader(f.Version),
+		entry.Level.String(), cefEscapeHeader(entry.Message), severity(entry.Level))
+
+	buf.WriteString("rt=")
+	buf.WriteString(strconv.FormatInt(entry.Time.UnixMilli(), 10))
+
+	for _, key := range sortedKeys(entry.Data) {
+		name := key
+
+		switch key {
+		case FieldRemoteIP:
+			name = "src"
+		case FieldMethod:
+			name = "requestMethod"
+		case FieldPath:
+			name = "request"
+		case FieldUsername:
+			name = "suser"
+		}
+
+		buf.WriteByte(' ')
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(cefEscapeExtension(fieldString(entry.Data[key])))
+	}
+
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// LEEFFormatter is a logrus.Formatter which formats the entries in the IBM QRadar Log Event Extended Format.
+type LEEFFormatter struct {
+	Version string
+}
+
+// Format the entry.
+func (f *LEEFFormatter) Format(entry *logrus.Entry) (data []byte, err error) {
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "LEEF:%s|%s|%s|%s|%s|", leefVersion, leefEscapeHeader(formatterVendor), leefEscapeHeader(formatterProduct),
+		leefEscapeHeader(f.Version), entry.Level.String())
+
+	fmt.Fprintf(buf, "devTime=%s\tdevTimeFormat=%s\tsev=%d\tmsg=%s",
+		entry.Time.Format(leefTimeFormatGo), leefTimeFormat, severity(entry.Level), leefEscapeAttribute(entry.Message))
+
+	for _, key := range sortedKeys(entry.Data) {
+		name := key
+
+		switch key {
+		case FieldRemoteIP:
+			name = "src"
+		case FieldPath:
+			name = "url"
+		case FieldUsername:
+			name = "usrName"
+		}
+
+		buf.WriteByte('\t')
+		buf.WriteString(name)
+		buf.WriteByte('=')
+		buf.WriteString(leefEscapeAttribute(fieldString(entry.Data[key])))
+	}
+
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+// ECSFormatter is a logrus.Formatter which formats the entries as JSON using the Elastic Common Schema. The fields
+// which don't have an equivalent in the schema are added to the authelia object.
+type ECSFormatter struct {
+	Version string
+}
+
+// Format the entry.
+func (f *ECSFormatter) Format(entry *logrus.Entry) (data []byte, err error) {
+	event := map[string]any{
+		"@timestamp": entry.Time.Format(ecsTimestampFormat),
+		"message":    entry.Message,
+		"log":        map[string]any{"level": entry.Level.String()},
+		"ecs":        map[string]any{"version": ecsVersion},
+		"service":    map[string]any{"name": formatterService, "version": f.Version},
+	}
+
+	custom := map[string]any{}
+
+	for key, value := range entry.Data {
+		switch key {
+		case FieldRemoteIP:
+			ecsSet(event, fieldString(value), "source", "ip")
+		case FieldMethod:
+			ecsSet(event, fieldString(value), "http", "request", "method")
+		case FieldPath:
+			ecsSet(event, fieldString(value), "url", "path")
+		case FieldPathRaw:
+			ecsSet(event, fieldString(value), "url", "original")
+		case FieldStatusCode:
+			ecsSet(event, value, "http", "response", "status_code")
+		case FieldUsername:
+			ecsSet(event, fieldString(value), "user", "name")
+		case logrus.ErrorKey:
+			ecsSet(event, fieldString(value), "error", "message")
+		case fieldStack:
+			ecsSet(event, fieldString(value), "error", "stack_trace")
+		default:
+			if err, ok := value.(error); ok {
+				custom[key] = err.Error()
+			} else {
+				custom[key] = value
+			}
+		}
+	}
+
+	if len(custom) != 0 {
+		event[formatterService] = custom
+	}
+
+	if data, err = json.Marshal(event); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+func ecsSet(event map[string]any, value any, path ...string) {
+	current := event
+
+	for _, key := range path[:len(path)-1] {
+		next, ok := current[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[key] = next
+		}
+
+		current = next
+	}
+
+	current[path[len(path)-1]] = value
+}
+
+// severity returns the severity of the level on the scale of 0 to 10 used by CEF and LEEF.
+func severity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return 10
+	case logrus.ErrorLevel:
+		return 8
+	case logrus.WarnLevel:
+		return 6
+	case logrus.InfoLevel:
+		return 3
+	default:
+		return 1
+	}
+}
+
+func sortedKeys(data logrus.Fields) (keys []string) {
+	keys = make([]string, 0, len(data))
+
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+var (
+	cefHeaderReplacer    = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtensionReplacer = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+	leefHeaderReplacer   = strings.NewReplacer(`|`, `\|`, "\n", " ", "\r", " ", "\t", " ")
+	leefValueReplacer    = strings.NewReplacer("\t", `\t`, "\n", `\n`, "\r", `\r`)
+)
+
+func cefEscapeHeader(value string) string {
+	return cefHeaderReplacer.Replace(value)
+}
+
+func cefEscapeExtension(value string) string {
+	return cefExtensionReplacer.Replace(value)
+}
+
+func leefEscapeHeader(value string) string {
+	return leefHeaderReplacer.Replace(value)
+}
+
+func leefEscapeAttribute(value string) string {
+	return leefValueReplacer.Replace(value)
+}
@@ -0,0 +1,117 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEntry(level logrus.Level, message string, fields logrus.Fields) *logrus.Entry {
+	return &logrus.Entry{
+		Logger:  logrus.New(),
+		Data:    fields,
+		Time:    time.Unix(1700000000, 0).In(time.FixedZone("AEST", 10*60*60)),
+		Level:   level,
+		Message: message,
+	}
+}
+
+func TestNewFormatter(t *testing.T) {
+	assert.IsType(t, &logrus.TextFormatter{}, NewFormatter(FormatText, false))
+	assert.IsType(t, &logrus.TextFormatter{}, NewFormatter("", false))
+	assert.True(t, NewFormatter(FormatText, true).(*logrus.TextFormatter).DisableColors)
+	assert.IsType(t, &logrus.JSONFormatter{}, NewFormatter(FormatJSON, false))
+	assert.IsType(t, &CEFFormatter{}, NewFormatter(FormatCEF, false))
+	assert.IsType(t, &LEEFFormatter{}, NewFormatter(FormatLEEF, false))
+	assert.IsType(t, &ECSFormatter{}, NewFormatter(FormatECS, false))
+}
+
+func TestCEFFormatter(t *testing.T) {
+	formatter := &CEFFormatter{Version: "v4.38.0"}
+
+	data, err := formatter.Format(newTestEntry(logrus.WarnLevel, "Access to 'https://example.com' is forbidden|denied", logrus.Fields{
+		FieldRemoteIP: "192.168.1.10",
+		FieldMethod:   "GET",
+		FieldPath:     "/api/authz/forward-auth",
+		FieldUsername: "john",
+		"query":       "a=b\\c\nd",
+	}))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, "CEF:0|Authelia|Authelia|v4.38.0|warning|Access to 'https://example.com' is forbidden\\|denied|6|"+
+		"rt=1700000000000 requestMethod=GET request=/api/authz/forward-auth query=a\\=b\\\\c\\nd src=192.168.1.10 suser=john\n", string(data))
+}
+
+func TestLEEFFormatter(t *testing.T) {
+	formatter := &LEEFFormatter{Version: "v4.38.0"}
+
+	data, err := formatter.Format(newTestEntry(logrus.ErrorLevel, "Unsuccessful 1FA authentication attempt", logrus.Fields{
+		FieldRemoteIP:   "192.168.1.10",
+		FieldPath:       "/api/firstfactor",
+		FieldUsername:   "john",
+		logrus.ErrorKey: errors.New("invalid\tcredentials"),
+	}))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, "LEEF:1.0|Authelia|Authelia|v4.38.0|error|"+
+		"devTime=Nov 15 2023 08:13:20.000 +1000\tdevTimeFormat=MMM dd yyyy HH:mm:ss.SSS Z\tsev=8\tmsg=Unsuccessful 1FA authentication attempt\t"+
+		"error=invalid\\tcredentials\turl=/api/firstfactor\tsrc=192.168.1.10\tusrName=john\n", string(data))
+}
+
+func TestECSFormatter(t *testing.T) {
+	formatter := &ECSFormatter{Version: "v4.38.0"}
+
+	data, err := formatter.Format(newTestEntry(logrus.InfoLevel, "Request completed", logrus.Fields{
+		FieldRemoteIP:   "192.168.1.10",
+		FieldMethod:     "POST",
+		FieldPath:       "/api/firstfactor",
+		FieldPathRaw:    "/api/firstfactor?rd=https%3A%2F%2Fexample.com",
+		FieldStatusCode: 200,
+		FieldUsername:   "john",
+		logrus.ErrorKey: errors.New("bad thing"),
+		fieldStack:      "main.go:1",
+		"service":       "server",
+	}))
+
+	require.NoError(t, err)
+
+	assert.Equal(t, byte('\n'), data[len(data)-1])
+
+	actual := map[string]any{}
+
+	require.NoError(t, json.Unmarshal(data, &actual))
+
+	assert.Equal(t, map[string]any{
+		"@timestamp": "2023-11-15T08:13:20.000000+10:00",
+		"message":    "Request completed",
+		"log":        map[string]any{"level": "info"},
+		"ecs":        map[string]any{"version": "8.11.0"},
+		"service":    map[string]any{"name": "authelia", "version": "v4.38.0"},
+		"source":     map[string]any{"ip": "192.168.1.10"},
+		"http": map[string]any{
+			"request":  map[string]any{"method": "POST"},
+			"response": map[string]any{"status_code": float64(200)},
+		},
+		"url":      map[string]any{"path": "/api/firstfactor", "original": "/api/firstfactor?rd=https%3A%2F%2Fexample.com"},
+		"user":     map[string]any{"name": "john"},
+		"error":    map[string]any{"message": "bad thing", "stack_trace": "main.go:1"},
+		"authelia": map[string]any{"service": "server"},
+	}, actual)
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, 10, severity(logrus.PanicLevel))
+	assert.Equal(t, 10, severity(logrus.FatalLevel))
+	assert.Equal(t, 8, severity(logrus.ErrorLevel))
+	assert.Equal(t, 6, severity(logrus.WarnLevel))
+	assert.Equal(t, 3, severity(logrus.InfoLevel))
+	assert.Equal(t, 1, severity(logrus.DebugLevel))
+	assert.Equal(t, 1, severity(logrus.TraceLevel))
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newJournaldOutput returns a new journaldOutput which sends the entries to the systemd journal.
+func newJournaldOutput(level logrus.Level) *journaldOutput {
+	return &journaldOutput{
+		level: level,
+		path:  journaldSocketPath,
+	}
+}
+
+// journaldOutput is an output which sends each entry to the systemd journal using the native journal protocol. The
+// fields of the entry are sent as the journal fields so they can be used to filter the journal.
+type journaldOutput struct {
+	level logrus.Level
+	path  string
+
+	conn net.Conn
+}
+
+// Level returns the most verbose level of the entries written to this output.
+func (o *journaldOutput) Level() logrus.Level {
+	return o.level
+}
+
+// Write the entry to the systemd journal.
+func (o *journaldOutput) Write(entry *logrus.Entry) (err error) {
+	if o.conn == nil {
+		if o.conn, err = net.Dial("unixgram", o.path); err != nil {
+			o.conn = nil
+
+			return fmt.Errorf("error connecting to the journal: %w", err)
+		}
+	}
+
+	if _, err = o.conn.Write(o.message(entry)); err != nil {
+		_ = o.conn.Close()
+
+		o.conn = nil
+
+		return fmt.Errorf("error writing to the journal: %w", err)
+	}
+
+	return nil
+}
+
+// Close the connection to the systemd journal.
+func (o *journaldOutput) Close() (err error) {
+	if o.conn == nil {
+		return nil
+	}
+
+	err = o.conn.Close()
+
+	o.conn = nil
+
+	return err
+}
+
+func (o *journaldOutput) message(entry *logrus.Entry) []byte {
+	buf := &bytes.Buffer{}
+
+	journaldAppendField(buf, journaldFieldMessage, entry.Message)
+	journaldAppendField(buf, journaldFieldPriority, strconv.Itoa(syslogSeverity(entry.Level)))
+	journaldAppendField(buf, journaldFieldIdentifier, journaldIdentifier)
+
+	for _, key := range sortedKeys(entry.Data) {
+		name := journaldFieldName(key)
+
+		switch name {
+		case "", journaldFieldMessage, journaldFieldPriority, journaldFieldIdentifier:
+			continue
+		}
+
+		journaldAppendField(buf, name, fieldString(entry.Data[key]))
+	}
+
+	return buf.Bytes()
+}
+
+// journaldAppendField appends a field to the message. Values which contain a new line are encoded with their length
+// as described by the native journal protocol.
+func journaldAppendField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+
+		return
+	}
+
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journaldFieldName converts the name of a field to a valid journal field name which only contains upper case
+// letters, digits, and underscores, and which does not start with a digit or an underscore.
+func journaldFieldName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, name)
+
+	name = strings.TrimLeft(name, "_0123456789")
+
+	if len(name) > journaldFieldNameMaxSize {
+		name = name[:journaldFieldNameMaxSize]
+	}
+
+	return name
+}
@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"net"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournaldOutput_ShouldFormatMessage(t *testing.T) {
+	output := newJournaldOutput(logrus.InfoLevel)
+
+	entry := &logrus.Entry{
+		Level:   logrus.WarnLevel,
+		Message: "example",
+		Data: logrus.Fields{
+			"remote_ip":   "127.0.0.1",
+			"stack":       "line1\nline2",
+			"message":     "ignored",
+			"_1.internal": true,
+		},
+	}
+
+	assert.Equal(t, "MESSAGE=example\nPRIORITY=4\nSYSLOG_IDENTIFIER=authelia\nINTERNAL=true\nREMOTE_IP=127.0.0.1\nSTACK\n\x0b\x00\x00\x00\x00\x00\x00\x00line1\nline2\n", string(output.message(entry)))
+}
+
+func TestJournaldFieldName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldUpperCase", "remote_ip", "REMOTE_IP"},
+		{"ShouldReplaceInvalidCharacters", "user.name-x", "USER_NAME_X"},
+		{"ShouldTrimInvalidPrefix", "__1abc", "ABC"},
+		{"ShouldReturnEmpty", "_", ""},
+		{"ShouldTruncate", "abcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghijabcdefghij", "ABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJABCDEFGHIJABCD"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, journaldFieldName(tc.have))
+		})
+	}
+}
+
+func TestJournaldOutput_ShouldWriteToSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix datagram sockets are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "journal.sock")
+
+	conn, err := net.ListenPacket("unixgram", path)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	output := newJournaldOutput(logrus.DebugLevel)
+	output.path = path
+
+	require.NoError(t, output.Write(&logrus.Entry{Level: logrus.DebugLevel, Message: "example"}))
+
+	buf := make([]byte, 1024)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "MESSAGE=example\nPRIORITY=7\nSYSLOG_IDENTIFIER=authelia\n", string(buf[:n]))
+
+	assert.NoError(t, output.Close())
+	assert.NoError(t, output.Close())
+
+	output.path = filepath.Join(t.TempDir(), "missing.sock")
+
+	assert.ErrorContains(t, output.Write(&logrus.Entry{Level: logrus.DebugLevel, Message: "example"}), "error connecting to the journal: ")
+}
@@ -1,8 +1,10 @@
 package logging
 
 import (
+	"errors"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	logrus_stack "github.com/Gurpartap/logrus-stack"
@@ -48,14 +50,14 @@ func initializeStackTracer(config schema.Log) {
 func ConfigureLogger(config schema.Log, log bool) (err error) {
 	setLevelStr(config.Level, log)
 
-	switch config.Format {
-	case FormatJSON:
-		logrus.SetFormatter(&logrus.JSONFormatter{})
-	default:
-		logrus.SetFormatter(&logrus.TextFormatter{})
-	}
+	level := LogLevel(config.Level).Level()
+	formatter := NewFormatter(config.Format, false)
 
-	var writers []io.Writer
+	var (
+		writers []io.Writer
+		extra   []entryWriter
+		main    *formattedOutput
+	)
 
 	switch {
 	case config.FilePath != "":
@@ -65,24 +67,68 @@ func ConfigureLogger(config schema.Log, log bool) (err error) {
 			return err
 		}
 
-		if config.Format != FormatJSON {
-			logrus.SetFormatter(&logrus.TextFormatter{
-				DisableColors: true,
-				FullTimestamp: true,
-			})
+		format := config.FileFormat
+
+		if format == "" {
+			format = config.Format
 		}
 
-		writers = []io.Writer{file}
+		switch {
+		case config.KeepStdout && format != config.Format:
+			// The outputs use different formats so the file is written by the output hook with its own formatter.
+			writers = []io.Writer{os.Stdout}
+
+			main = &formattedOutput{writer: file, formatter: NewFormatter(format, true)}
+		case config.KeepStdout:
+			formatter = NewFormatter(format, true)
+
+			writers = []io.Writer{file, os.Stdout}
+		default:
+			formatter = NewFormatter(format, true)
 
-		if config.KeepStdout {
-			writers = append(writers, os.Stdout)
+			writers = []io.Writer{file}
 		}
 	default:
 		writers = []io.Writer{os.Stdout}
 	}
 
+	if config.Syslog.Enabled {
+		var syslog *syslogOutput
+
+		if syslog, err = newSyslogOutput(&config.Syslog, outputLevel(config.Syslog.Level, level), NewFormatter(outputFormat(config.Syslog.Format, config.Format), true)); err != nil {
+			return err
+		}
+
+		extra = append(extra, syslog)
+	}
+
+	if config.Journald.Enabled {
+		extra = append(extra, newJournaldOutput(outputLevel(config.Journald.Level, level)))
+	}
+
+	maximum := logrus.PanicLevel
+
+	for _, o := range extra {
+		if o.Level() > maximum {
+			maximum = o.Level()
+		}
+	}
+
+	if main != nil {
+		extra = append(extra, main)
+	}
+
+	outputHookOnce.Do(func() {
+		logrus.AddHook(redactions)
+		logrus.AddHook(outputs)
+	})
+
+	outputs.Set(extra...)
+
 	logrus.SetOutput(io.MultiWriter(writers...))
 
+	modules.setup(level, moduleLevels(config.Modules), maximum, formatter)
+
 	return nil
 }
 
@@ -93,3 +139,127 @@ func setLevelStr(level string, log bool) {
 		logrus.Infof("Log severity set to %s", level)
 	}
 }
+
+func outputLevel(level string, fallback logrus.Level) logrus.Level {
+	if level == "" {
+		return fallback
+	}
+
+	return LogLevel(level).Level()
+}
+
+func outputFormat(format, fallback string) string {
+	if format == "" {
+		return fallback
+	}
+
+	return format
+}
+
+// entryWriter is an output written by the outputHook which has its own level.
+type entryWriter interface {
+	Level() logrus.Level
+	Write(entry *logrus.Entry) (err error)
+	Close() (err error)
+}
+
+// outputHook is a logrus.Hook which writes the entries to the additional outputs.
+type outputHook struct {
+	outputs []entryWriter
+
+	mu sync.Mutex
+}
+
+// Set the outputs of the hook, the previous outputs are closed.
+func (h *outputHook) Set(outputs ...entryWriter) {
+	h.mu.Lock()
+
+	defer h.mu.Unlock()
+
+	for _, o := range h.outputs {
+		_ = o.Close()
+	}
+
+	h.outputs = outputs
+}
+
+// Levels implements logrus.Hook.
+func (h *outputHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *outputHook) Fire(entry *logrus.Entry) (err error) {
+	h.mu.Lock()
+
+	defer h.mu.Unlock()
+
+	var errs []error
+
+	for _, o := range h.outputs {
+		if entry.Level > o.Level() {
+			continue
+		}
+
+		if err = o.Write(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// formattedOutput is an output which writes the entries to a writer using its own formatter. The entries are written
+// using the same level as the standard output.
+type formattedOutput struct {
+	writer    io.Writer
+	formatter logrus.Formatter
+}
+
+// Level returns the most verbose level of the entries written to this output, the entries are filtered using the
+// level of the logger of each entry instead.
+func (o *formattedOutput) Level() logrus.Level {
+	return logrus.TraceLevel
+}
+
+// Write the entry to the writer.
+func (o *formattedOutput) Write(entry *logrus.Entry) (err error) {
+	if entry.Level > modules.levelOfLogger(entry.Logger) {
+		return nil
+	}
+
+	var data []byte
+
+	if data, err = o.formatter.Format(entry); err != nil {
+		return err
+	}
+
+	_, err = o.writer.Write(data)
+
+	return err
+}
+
+// Close the writer if it's an io.Closer.
+func (o *formattedOutput) Close() (err error) {
+	if closer, ok := o.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// levelFormatter is a logrus.Formatter which omits the entries which are more verbose than the level.
+type levelFormatter struct {
+	logrus.Formatter
+
+	level logrus.Level
+}
+
+// Format the entry.
+func (f *levelFormatter) Format(entry *logrus.Entry) (data []byte, err error) {
+	if entry.Level > f.level {
+		return nil, nil
+	}
+
+	return f.Formatter.Format(entry)
+}
@@ -3,9 +3,12 @@ package logging
 import (
 	"fmt"
 	"io"
+	"net"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -68,6 +71,105 @@ func TestShouldFormatLogsAsJSON(t *testing.T) {
 	assert.Contains(t, string(b), "{\"level\":\"info\",\"msg\":\"This is a test\",")
 }
 
+func TestShouldFormatLogsPerOutput(t *testing.T) {
+	dir := t.TempDir()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+	err := InitializeLogger(schema.Log{Format: "text", FileFormat: "cef", FilePath: path, KeepStdout: true}, false)
+	require.NoError(t, err)
+
+	assert.IsType(t, &logrus.TextFormatter{}, Logger().Formatter)
+
+	Logger().Info("This is a test")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^CEF:0\|Authelia\|Authelia\|[^|]*\|info\|This is a test\|3\|rt=\d+\n$`, string(b))
+
+	err = InitializeLogger(schema.Log{Format: "json", FilePath: path, KeepStdout: false}, false)
+	require.NoError(t, err)
+
+	Logger().Info("This is another test")
+
+	b, err = io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "{\"level\":\"info\",\"msg\":\"This is another test\",")
+	assert.NotContains(t, string(b), "CEF:")
+}
+
+func TestShouldFormatLogsAsECS(t *testing.T) {
+	dir := t.TempDir()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+	err := InitializeLogger(schema.Log{Format: "ecs", FilePath: path, KeepStdout: false}, false)
+	require.NoError(t, err)
+
+	Logger().Info("This is a test")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "\"message\":\"This is a test\"")
+	assert.Contains(t, string(b), "\"ecs\":{\"version\":\"8.11.0\"}")
+}
+
+func TestShouldFilterLogsPerOutputLevel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix datagram sockets are not supported on windows")
+	}
+
+	dir := t.TempDir()
+
+	socket := filepath.Join(dir, "journal.sock")
+
+	conn, err := net.ListenPacket("unixgram", socket)
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+	err = InitializeLogger(schema.Log{Level: "info", Format: "text", FilePath: path, Journald: schema.LogJournald{Enabled: true, Level: "debug"}}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+	assert.IsType(t, &levelFormatter{}, Logger().Formatter)
+
+	outputs.outputs[0].(*journaldOutput).path = socket
+
+	Logger().Debug("This is a debug test")
+
+	buf := make([]byte, 1024)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "MESSAGE=This is a debug test\nPRIORITY=7\nSYSLOG_IDENTIFIER=authelia\n", string(buf[:n]))
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Empty(t, b)
+
+	err = InitializeLogger(schema.Log{Level: "info", Format: "text"}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+	assert.Empty(t, outputs.outputs)
+}
+
 func TestShouldRaiseErrorOnInvalidFile(t *testing.T) {
 	err := InitializeLogger(schema.Log{FilePath: "/not/a/valid/path/to.log"}, false)
 
@@ -0,0 +1,315 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// LoggerModule returns the logger of the module. The loggers of the modules share the formatter, the outputs, and the
+// hooks of the standard logger but have their own level which defaults to the level of the standard logger.
+func LoggerModule(name string) *logrus.Logger {
+	return modules.logger(name)
+}
+
+// SetLevels sets the configured level of the standard logger and the modules, which are also the levels restored by
+// ResetLevels.
+func SetLevels(level string, config schema.LogModules) {
+	modules.configure(LogLevel(level).Level(), moduleLevels(config))
+}
+
+// SetModuleLevel sets the level of a module at runtime, or the level of the standard logger if the name is empty. The
+// modules without a configured level use the level of the standard logger.
+func SetModuleLevel(name, level string) (err error) {
+	if !isLevel(level) {
+		return fmt.Errorf("the level '%s' is not valid", level)
+	}
+
+	if name != "" && !isModule(name) {
+		return fmt.Errorf("the module '%s' is not valid", name)
+	}
+
+	modules.set(name, LogLevel(level).Level())
+
+	return nil
+}
+
+// IncreaseLevels increases the level of the standard logger and the modules by one step up to the trace level.
+func IncreaseLevels() {
+	modules.increase()
+}
+
+// ResetLevels restores the configured levels of the standard logger and the modules.
+func ResetLevels() {
+	modules.reset()
+}
+
+// Levels returns the current level of the standard logger and the current levels of each module.
+func Levels() (level string, levels map[string]string) {
+	return modules.levels()
+}
+
+func moduleLevels(config schema.LogModules) (levels map[string]logrus.Level) {
+	levels = map[string]logrus.Level{}
+
+	for name, level := range map[string]string{
+		ModuleStorage:       config.Storage,
+		ModuleLDAP:          config.LDAP,
+		ModuleOpenIDConnect: config.OpenIDConnect,
+		ModuleSession:       config.Session,
+	} {
+		if level != "" {
+			levels[name] = LogLevel(level).Level()
+		}
+	}
+
+	return levels
+}
+
+func isModule(name string) bool {
+	for _, module := range Modules {
+		if module == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isLevel(level string) bool {
+	switch level {
+	case LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError:
+		return true
+	default:
+		return false
+	}
+}
+
+func levelName(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return LevelError
+	case logrus.WarnLevel:
+		return LevelWarn
+	case logrus.InfoLevel:
+		return LevelInfo
+	case logrus.DebugLevel:
+		return LevelDebug
+	default:
+		return LevelTrace
+	}
+}
+
+// moduleLoggers manages the levels of the standard logger and the loggers of the modules.
+type moduleLoggers struct {
+	loggers map[string]*logrus.Logger
+
+	configured map[string]logrus.Level
+	current    map[string]logrus.Level
+
+	level, levelConfigured logrus.Level
+
+	// outputs is the most verbose level of the additional outputs.
+	outputs   logrus.Level
+	formatter logrus.Formatter
+
+	// applied are the levels of the standard output of each logger.
+	applied sync.Map
+
+	mu sync.Mutex
+}
+
+func (m *moduleLoggers) logger(name string) *logrus.Logger {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	if logger, ok := m.loggers[name]; ok {
+		return logger
+	}
+
+	std := logrus.StandardLogger()
+
+	logger := &logrus.Logger{
+		Out:          std.Out,
+		Formatter:    std.Formatter,
+		Hooks:        std.Hooks,
+		Level:        std.GetLevel(),
+		ExitFunc:     std.ExitFunc,
+		ReportCaller: std.ReportCaller,
+	}
+
+	if m.loggers == nil {
+		m.loggers = map[string]*logrus.Logger{}
+	}
+
+	m.loggers[name] = logger
+
+	if m.formatter != nil {
+		m.apply(name, logger)
+	}
+
+	return logger
+}
+
+// setup the outputs and formatter shared by the loggers, and the configured levels.
+func (m *moduleLoggers) setup(level logrus.Level, configured map[string]logrus.Level, outputs logrus.Level, formatter logrus.Formatter) {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	m.outputs, m.formatter = outputs, formatter
+
+	m.configureLocked(level, configured)
+}
+
+func (m *moduleLoggers) configure(level logrus.Level, configured map[string]logrus.Level) {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	m.configureLocked(level, configured)
+}
+
+func (m *moduleLoggers) configureLocked(level logrus.Level, configured map[string]logrus.Level) {
+	m.levelConfigured, m.configured = level, configured
+
+	m.resetLocked()
+}
+
+func (m *moduleLoggers) set(name string, level logrus.Level) {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	switch {
+	case name == "":
+		m.level = level
+	case m.current == nil:
+		m.current = map[string]logrus.Level{name: level}
+	default:
+		m.current[name] = level
+	}
+
+	m.applyAll()
+}
+
+func (m *moduleLoggers) increase() {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	if m.level < logrus.TraceLevel {
+		m.level++
+	}
+
+	for name, level := range m.current {
+		if level < logrus.TraceLevel {
+			m.current[name] = level + 1
+		}
+	}
+
+	m.applyAll()
+}
+
+func (m *moduleLoggers) reset() {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	m.resetLocked()
+}
+
+func (m *moduleLoggers) resetLocked() {
+	m.level = m.levelConfigured
+	m.current = make(map[string]logrus.Level, len(m.configured))
+
+	for name, level := range m.configured {
+		m.current[name] = level
+	}
+
+	m.applyAll()
+}
+
+func (m *moduleLoggers) levels() (level string, levels map[string]string) {
+	m.mu.Lock()
+
+	defer m.mu.Unlock()
+
+	levels = make(map[string]string, len(Modules))
+
+	for _, name := range Modules {
+		levels[name] = levelName(m.levelOf(name))
+	}
+
+	return levelName(m.level), levels
+}
+
+func (m *moduleLoggers) levelOf(name string) logrus.Level {
+	if level, ok := m.current[name]; ok {
+		return level
+	}
+
+	return m.level
+}
+
+// levelOfLogger returns the level of the standard output of the logger.
+func (m *moduleLoggers) levelOfLogger(logger *logrus.Logger) logrus.Level {
+	if level, ok := m.applied.Load(logger); ok {
+		return level.(logrus.Level)
+	}
+
+	return logger.GetLevel()
+}
+
+func (m *moduleLoggers) applyAll() {
+	if m.formatter == nil {
+		return
+	}
+
+	m.apply("", logrus.StandardLogger())
+
+	names := make([]string, 0, len(m.loggers))
+
+	for name := range m.loggers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		m.apply(name, m.loggers[name])
+	}
+}
+
+// apply the level and formatter to the logger. The logger includes the entries of the most verbose output so the
+// entries which are more verbose than the level of the logger are omitted from the main output.
+func (m *moduleLoggers) apply(name string, logger *logrus.Logger) {
+	level := m.level
+
+	if name != "" {
+		level = m.levelOf(name)
+
+		logger.SetOutput(logrus.StandardLogger().Out)
+	}
+
+	m.applied.Store(logger, level)
+
+	maximum := level
+
+	if m.outputs > maximum {
+		maximum = m.outputs
+	}
+
+	logger.SetLevel(maximum)
+
+	if maximum == level {
+		logger.SetFormatter(m.formatter)
+	} else {
+		logger.SetFormatter(&levelFormatter{Formatter: m.formatter, level: level})
+	}
+}
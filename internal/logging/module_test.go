@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldLogModulesWithTheirOwnLevel(t *testing.T) {
+	dir := t.TempDir()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+
+	err := InitializeLogger(schema.Log{Level: "info", Format: "text", FilePath: path, Modules: schema.LogModules{Storage: "debug", LDAP: "error"}}, false)
+	require.NoError(t, err)
+
+	storage, ldap, session := LoggerModule(ModuleStorage), LoggerModule(ModuleLDAP), LoggerModule(ModuleSession)
+
+	assert.Same(t, storage, LoggerModule(ModuleStorage))
+	assert.Equal(t, logrus.DebugLevel, storage.GetLevel())
+	assert.Equal(t, logrus.ErrorLevel, ldap.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, session.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, Logger().GetLevel())
+
+	storage.Debug("storage debug")
+	ldap.Warn("ldap warn")
+	session.Info("session info")
+	Logger().Debug("main debug")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "level=debug msg=\"storage debug\"\n")
+	assert.Contains(t, string(b), "level=info msg=\"session info\"\n")
+	assert.NotContains(t, string(b), "ldap warn")
+	assert.NotContains(t, string(b), "main debug")
+
+	level, levels := Levels()
+
+	assert.Equal(t, LevelInfo, level)
+	assert.Equal(t, map[string]string{ModuleStorage: LevelDebug, ModuleLDAP: LevelError, ModuleOpenIDConnect: LevelInfo, ModuleSession: LevelInfo}, levels)
+
+	require.NoError(t, SetModuleLevel(ModuleSession, LevelTrace))
+	require.NoError(t, SetModuleLevel("", LevelWarn))
+
+	assert.Equal(t, logrus.TraceLevel, session.GetLevel())
+	assert.Equal(t, logrus.WarnLevel, Logger().GetLevel())
+	assert.Equal(t, logrus.WarnLevel, LoggerModule(ModuleOpenIDConnect).GetLevel())
+
+	IncreaseLevels()
+
+	level, levels = Levels()
+
+	assert.Equal(t, LevelInfo, level)
+	assert.Equal(t, map[string]string{ModuleStorage: LevelTrace, ModuleLDAP: LevelWarn, ModuleOpenIDConnect: LevelInfo, ModuleSession: LevelTrace}, levels)
+
+	ResetLevels()
+
+	level, levels = Levels()
+
+	assert.Equal(t, LevelInfo, level)
+	assert.Equal(t, map[string]string{ModuleStorage: LevelDebug, ModuleLDAP: LevelError, ModuleOpenIDConnect: LevelInfo, ModuleSession: LevelInfo}, levels)
+
+	SetLevels(LevelWarn, schema.LogModules{Session: LevelDebug})
+
+	level, levels = Levels()
+
+	assert.Equal(t, LevelWarn, level)
+	assert.Equal(t, map[string]string{ModuleStorage: LevelWarn, ModuleLDAP: LevelWarn, ModuleOpenIDConnect: LevelWarn, ModuleSession: LevelDebug}, levels)
+
+	err = InitializeLogger(schema.Log{Level: "info", Format: "text"}, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, logrus.InfoLevel, storage.GetLevel())
+}
+
+func TestShouldLogModulesToOutputsWithTheirOwnFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+
+	err := InitializeLogger(schema.Log{Level: "info", Format: "text", FileFormat: "json", FilePath: path, KeepStdout: true, Modules: schema.LogModules{OpenIDConnect: "debug"}}, false)
+	require.NoError(t, err)
+
+	LoggerModule(ModuleOpenIDConnect).Debug("oidc debug")
+	Logger().Debug("main debug")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "{\"level\":\"debug\",\"msg\":\"oidc debug\",")
+	assert.NotContains(t, string(b), "main debug")
+
+	err = InitializeLogger(schema.Log{Level: "info", Format: "text"}, false)
+	require.NoError(t, err)
+}
+
+func TestShouldRaiseErrorOnInvalidModuleLevel(t *testing.T) {
+	assert.EqualError(t, SetModuleLevel(ModuleStorage, "verbose"), "the level 'verbose' is not valid")
+	assert.EqualError(t, SetModuleLevel("abc", LevelDebug), "the module 'abc' is not valid")
+}
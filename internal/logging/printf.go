@@ -43,3 +43,11 @@ func LoggerCtxPrintf(level logrus.Level) (logger *CtxPrintfLogger) {
 		logrus: logrus.StandardLogger(),
 	}
 }
+
+// LoggerCtxPrintfModule returns a new CtxPrintfLogger given a level which uses the logger of the module.
+func LoggerCtxPrintfModule(level logrus.Level, module string) (logger *CtxPrintfLogger) {
+	return &CtxPrintfLogger{
+		level:  level,
+		logrus: LoggerModule(module),
+	}
+}
@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewRedactor returns a new Redactor for the configuration, or nil if none of the redactions are enabled. The usernames
+// are hashed using a key derived from the secret so the hashed usernames are consistent across restarts and instances
+// which share the secret.
+func NewRedactor(config schema.LogRedaction, secret []byte) (redactor *Redactor) {
+	if !config.Usernames && !config.IPAddresses && !config.Emails {
+		return nil
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(redactionKeyContext))
+
+	return &Redactor{
+		usernames: config.Usernames,
+		ips:       config.IPAddresses,
+		emails:    config.Emails,
+		ipv4:      config.IPv4PrefixLength,
+		ipv6:      config.IPv6PrefixLength,
+		key:       mac.Sum(nil),
+	}
+}
+
+// SetRedactor sets the Redactor applied to the log entries of the standard logger and the loggers of the modules. The
+// redaction is disabled if the redactor is nil.
+func SetRedactor(redactor *Redactor) {
+	redactions.Set(redactor)
+}
+
+// Redactor redacts the personal data such as the usernames, the IP addresses, and the email addresses from the values
+// written to the logs and the audit events. A nil Redactor returns the values unchanged.
+type Redactor struct {
+	usernames, ips, emails bool
+	ipv4, ipv6             int
+
+	key []byte
+}
+
+// Username returns the username replaced with a keyed hash if the usernames are redacted.
+func (r *Redactor) Username(username string) string {
+	if r == nil || !r.usernames || username == "" {
+		return username
+	}
+
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(username))
+
+	return hex.EncodeToString(mac.Sum(nil))[:redactionHashLength]
+}
+
+// IP returns the IP address truncated to the configured prefix length if the IP addresses are redacted. Values which
+// are not an IP address are returned unchanged.
+func (r *Redactor) IP(ip string) string {
+	if r == nil || !r.ips {
+		return ip
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+
+	addr = addr.Unmap()
+
+	bits := r.ipv6
+
+	if addr.Is4() {
+		bits = r.ipv4
+	}
+
+	prefix, err := addr.WithZone("").Prefix(bits)
+	if err != nil {
+		return ip
+	}
+
+	return prefix.Addr().String()
+}
+
+// Email returns the email address with the local part masked if the email addresses are redacted.
+func (r *Redactor) Email(email string) string {
+	if r == nil || !r.emails {
+		return email
+	}
+
+	i := strings.LastIndex(email, "@")
+	if i < 1 {
+		return email
+	}
+
+	return email[:1] + redactionMask + email[i:]
+}
+
+// String returns the value with the personal data redacted. The usernames are detected using the user and username
+// phrases used by the log messages such as "user 'john'", and the IP and email addresses are detected anywhere in the
+// value.
+func (r *Redactor) String(value string) string {
+	if r == nil {
+		return value
+	}
+
+	if r.usernames {
+		value = reRedactUsername.ReplaceAllStringFunc(value, func(match string) string {
+			i := strings.IndexByte(match, '\'')
+
+			return match[:i+1] + r.Username(match[i+1:len(match)-1]) + "'"
+		})
+	}
+
+	if r.emails {
+		value = reRedactEmail.ReplaceAllStringFunc(value, r.Email)
+	}
+
+	if r.ips {
+		value = reRedactIP.ReplaceAllStringFunc(value, r.IP)
+	}
+
+	return value
+}
+
+// Entry redacts the personal data from the message and the fields of the entry.
+func (r *Redactor) Entry(entry *logrus.Entry) {
+	if r == nil {
+		return
+	}
+
+	if username, ok := entry.Data[FieldUsername].(string); ok && username != "" && r.usernames {
+		entry.Message = strings.ReplaceAll(entry.Message, "'"+username+"'", "'"+r.Username(username)+"'")
+	}
+
+	entry.Message = r.String(entry.Message)
+
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			switch key {
+			case FieldUsername:
+				entry.Data[key] = r.Username(v)
+			case FieldRemoteIP:
+				entry.Data[key] = r.IP(v)
+			default:
+				entry.Data[key] = r.String(v)
+			}
+		case error:
+			if redacted := r.String(v.Error()); redacted != v.Error() {
+				entry.Data[key] = errors.New(redacted)
+			}
+		}
+	}
+}
+
+// redactionHook is a logrus.Hook which redacts the entries before they're written to any of the outputs.
+type redactionHook struct {
+	redactor *Redactor
+
+	mu sync.RWMutex
+}
+
+// Set the Redactor of the hook.
+func (h *redactionHook) Set(redactor *Redactor) {
+	h.mu.Lock()
+
+	defer h.mu.Unlock()
+
+	h.redactor = redactor
+}
+
+// Levels implements logrus.Hook.
+func (h *redactionHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook.
+func (h *redactionHook) Fire(entry *logrus.Entry) (err error) {
+	h.mu.RLock()
+
+	defer h.mu.RUnlock()
+
+	h.redactor.Entry(entry)
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+var testRedactionConfig = schema.LogRedaction{
+	Usernames:        true,
+	IPAddresses:      true,
+	Emails:           true,
+	IPv4PrefixLength: 24,
+	IPv6PrefixLength: 48,
+}
+
+func TestNewRedactor_ShouldReturnNilWhenDisabled(t *testing.T) {
+	var redactor *Redactor
+
+	assert.Nil(t, NewRedactor(schema.DefaultLogRedactionConfiguration, []byte("secret")))
+
+	assert.Equal(t, "john", redactor.Username("john"))
+	assert.Equal(t, "192.168.1.10", redactor.IP("192.168.1.10"))
+	assert.Equal(t, "john@example.com", redactor.Email("john@example.com"))
+	assert.Equal(t, "user 'john'", redactor.String("user 'john'"))
+}
+
+func TestRedactor_Username(t *testing.T) {
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	hashed := redactor.Username("john")
+
+	assert.Len(t, hashed, 16)
+	assert.NotEqual(t, "john", hashed)
+	assert.Equal(t, hashed, redactor.Username("john"))
+	assert.Equal(t, hashed, NewRedactor(testRedactionConfig, []byte("secret")).Username("john"))
+	assert.NotEqual(t, hashed, NewRedactor(testRedactionConfig, []byte("other")).Username("john"))
+	assert.NotEqual(t, hashed, redactor.Username("harry"))
+	assert.Equal(t, "", redactor.Username(""))
+}
+
+func TestRedactor_IP(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldTruncateIPv4", "192.168.1.10", "192.168.1.0"},
+		{"ShouldTruncateIPv6", "2001:db8:1234:5678::1", "2001:db8:1234::"},
+		{"ShouldTruncateIPv4MappedIPv6", "::ffff:192.168.1.10", "192.168.1.0"},
+		{"ShouldNotTruncateInvalid", "example.com", "example.com"},
+	}
+
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, redactor.IP(tc.have))
+		})
+	}
+}
+
+func TestRedactor_Email(t *testing.T) {
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	assert.Equal(t, "j***@example.com", redactor.Email("john.doe@example.com"))
+	assert.Equal(t, "@example.com", redactor.Email("@example.com"))
+	assert.Equal(t, "john", redactor.Email("john"))
+}
+
+func TestRedactor_String(t *testing.T) {
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	hashed := redactor.Username("john")
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldRedactUser", "Unsuccessful 1FA authentication attempt by user 'john'", fmt.Sprintf("Unsuccessful 1FA authentication attempt by user '%s'", hashed)},
+		{"ShouldRedactUsername", "Error occurred looking up username 'john'", fmt.Sprintf("Error occurred looking up username '%s'", hashed)},
+		{"ShouldRedactEmail", "Sending an email to john.doe@example.com", "Sending an email to j***@example.com"},
+		{"ShouldRedactIPs", "Request from 192.168.1.10:9091 via 2001:db8:1234:5678::1", "Request from 192.168.1.0:9091 via 2001:db8:1234::"},
+		{"ShouldNotRedactTimes", "Session expires at 10:00:00", "Session expires at 10:00:00"},
+		{"ShouldNotRedactVersions", "Authelia v4.38.0 is starting", "Authelia v4.38.0 is starting"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, redactor.String(tc.have))
+		})
+	}
+}
+
+func TestRedactor_Entry(t *testing.T) {
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	entry := &logrus.Entry{
+		Message: "Credentials for 'john' are invalid",
+		Data: logrus.Fields{
+			FieldUsername:   "john",
+			FieldRemoteIP:   "192.168.1.10",
+			FieldPath:       "/api/firstfactor",
+			logrus.ErrorKey: errors.New("user 'john' was not found"),
+			FieldStatusCode: 401,
+		},
+	}
+
+	redactor.Entry(entry)
+
+	hashed := redactor.Username("john")
+
+	assert.Equal(t, fmt.Sprintf("Credentials for '%s' are invalid", hashed), entry.Message)
+	assert.Equal(t, hashed, entry.Data[FieldUsername])
+	assert.Equal(t, "192.168.1.0", entry.Data[FieldRemoteIP])
+	assert.Equal(t, "/api/firstfactor", entry.Data[FieldPath])
+	assert.EqualError(t, entry.Data[logrus.ErrorKey].(error), fmt.Sprintf("user '%s' was not found", hashed))
+	assert.Equal(t, 401, entry.Data[FieldStatusCode])
+}
+
+func TestShouldRedactLogs(t *testing.T) {
+	dir := t.TempDir()
+
+	path := fmt.Sprintf("%s/authelia.log", dir)
+
+	err := InitializeLogger(schema.Log{Level: "info", Format: "json", FilePath: path}, false)
+	require.NoError(t, err)
+
+	redactor := NewRedactor(testRedactionConfig, []byte("secret"))
+
+	SetRedactor(redactor)
+
+	defer SetRedactor(nil)
+
+	Logger().WithField(FieldRemoteIP, "192.168.1.10").Errorf("Unsuccessful 1FA authentication attempt by user '%s'", "john")
+
+	SetRedactor(nil)
+
+	Logger().WithField(FieldRemoteIP, "192.168.1.10").Errorf("Unsuccessful 1FA authentication attempt by user '%s'", "harry")
+
+	f, err := os.OpenFile(path, os.O_RDONLY, 0)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), fmt.Sprintf(`"msg":"Unsuccessful 1FA authentication attempt by user '%s'","remote_ip":"192.168.1.0"`, redactor.Username("john")))
+	assert.Contains(t, string(b), `"msg":"Unsuccessful 1FA authentication attempt by user 'harry'","remote_ip":"192.168.1.10"`)
+	assert.NotContains(t, string(b), "john")
+}
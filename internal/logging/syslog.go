@@ -0,0 +1,258 @@
+package logging
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// newSyslogOutput returns a new syslogOutput which sends the entries to the configured syslog server.
+func newSyslogOutput(config *schema.LogSyslog, level logrus.Level, formatter logrus.Formatter) (output *syslogOutput, err error) {
+	if config.Address == nil {
+		return nil, fmt.Errorf("the syslog server address is not configured")
+	}
+
+	facility, ok := SyslogFacilities[config.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility '%s'", config.Facility)
+	}
+
+	hostname, _ := os.Hostname()
+
+	if hostname == "" {
+		hostname = syslogNil
+	}
+
+	output = &syslogOutput{
+		address:   &config.Address.Address,
+		level:     level,
+		formatter: formatter,
+		facility:  facility,
+		tag:       config.Tag,
+		hostname:  hostname,
+		pid:       os.Getpid(),
+		timeout:   config.Timeout,
+		queue:     make(chan []byte, syslogQueueSize),
+	}
+
+	if config.TLS != nil {
+		output.tls = newTLSConfig(config.TLS)
+
+		if output.tls.ServerName == "" {
+			output.tls.ServerName = config.Address.Hostname()
+		}
+	}
+
+	go output.run()
+
+	return output, nil
+}
+
+// syslogOutput is an output which sends each entry to a syslog server as a RFC5424 message. The messages are queued
+// and sent in the background so an unavailable syslog server does not delay the callers, and the messages are dropped
+// when the queue is full or while the syslog server is unavailable.
+type syslogOutput struct {
+	address   *schema.Address
+	level     logrus.Level
+	formatter logrus.Formatter
+	facility  int
+	tag       string
+	hostname  string
+	pid       int
+	timeout   time.Duration
+	tls       *tls.Config
+
+	queue chan []byte
+
+	conn   net.Conn
+	stream bool
+}
+
+// Level returns the most verbose level of the entries written to this output.
+func (o *syslogOutput) Level() logrus.Level {
+	return o.level
+}
+
+// Write the entry to the queue.
+func (o *syslogOutput) Write(entry *logrus.Entry) (err error) {
+	var data []byte
+
+	if data, err = o.formatter.Format(entry); err != nil {
+		return err
+	}
+
+	select {
+	case o.queue <- o.message(entry, data):
+	default:
+	}
+
+	return nil
+}
+
+// Close the queue, the remaining messages are sent in the background before the connection is closed.
+func (o *syslogOutput) Close() (err error) {
+	close(o.queue)
+
+	return nil
+}
+
+func (o *syslogOutput) run() {
+	var failed time.Time
+
+	for message := range o.queue {
+		if !failed.IsZero() && time.Since(failed) < o.timeout {
+			continue
+		}
+
+		if err := o.send(message); err != nil {
+			if failed.IsZero() {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to send the log messages to the syslog server, the messages will be dropped until it's available: %v\n", err)
+			}
+
+			failed = time.Now()
+
+			continue
+		}
+
+		failed = time.Time{}
+	}
+
+	if o.conn != nil {
+		_ = o.conn.Close()
+	}
+}
+
+func (o *syslogOutput) send(message []byte) (err error) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if o.conn == nil {
+			if err = o.dial(); err != nil {
+				return fmt.Errorf("error connecting to the syslog server: %w", err)
+			}
+		}
+
+		_ = o.conn.SetWriteDeadline(time.Now().Add(o.timeout))
+
+		if _, err = o.conn.Write(o.frame(message)); err == nil {
+			return nil
+		}
+
+		_ = o.conn.Close()
+
+		o.conn = nil
+	}
+
+	return fmt.Errorf("error writing to the syslog server: %w", err)
+}
+
+func (o *syslogOutput) dial() (err error) {
+	dialer := &net.Dialer{Timeout: o.timeout}
+
+	switch {
+	case !o.address.IsUnixDomainSocket() && o.tls != nil:
+		o.conn, err = tls.DialWithDialer(dialer, o.address.Network(), o.address.NetworkAddress(), o.tls)
+		o.stream = true
+	case !o.address.IsUnixDomainSocket():
+		o.conn, err = dialer.Dial(o.address.Network(), o.address.NetworkAddress())
+		o.stream = true
+	default:
+		// The local syslog sockets are usually datagram sockets but some implementations use stream sockets.
+		if o.conn, err = dialer.Dial("unixgram", o.address.NetworkAddress()); err == nil {
+			o.stream = false
+
+			return nil
+		}
+
+		o.conn, err = dialer.Dial("unix", o.address.NetworkAddress())
+		o.stream = true
+	}
+
+	return err
+}
+
+// frame the message for the connection. Stream connections use the octet counting framing described in RFC6587 and
+// RFC5425 where each message is prefixed by its length.
+func (o *syslogOutput) frame(message []byte) []byte {
+	if !o.stream {
+		return message
+	}
+
+	buf := make([]byte, 0, len(message)+8)
+
+	buf = strconv.AppendInt(buf, int64(len(message)), 10)
+	buf = append(buf, ' ')
+
+	return append(buf, message...)
+}
+
+// message formats the entry as a RFC5424 message with the formatted entry as the message content.
+func (o *syslogOutput) message(entry *logrus.Entry, data []byte) []byte {
+	data = bytes.TrimRight(data, "\n")
+
+	buf := make([]byte, 0, len(data)+128)
+
+	buf = append(buf, '<')
+	buf = strconv.AppendInt(buf, int64(o.facility*8+syslogSeverity(entry.Level)), 10)
+	buf = append(buf, '>')
+	buf = strconv.AppendInt(buf, syslogVersion, 10)
+	buf = append(buf, ' ')
+	buf = entry.Time.UTC().AppendFormat(buf, syslogTimeFormat)
+	buf = append(buf, ' ')
+	buf = append(buf, o.hostname...)
+	buf = append(buf, ' ')
+	buf = append(buf, o.tag...)
+	buf = append(buf, ' ')
+	buf = strconv.AppendInt(buf, int64(o.pid), 10)
+	buf = append(buf, ' ')
+	buf = append(buf, syslogNil...)
+	buf = append(buf, ' ')
+	buf = append(buf, syslogNil...)
+	buf = append(buf, ' ')
+
+	return append(buf, data...)
+}
+
+// syslogSeverity returns the syslog severity of the level which is also used as the journald priority.
+func syslogSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return syslogSeverityCritical
+	case logrus.ErrorLevel:
+		return syslogSeverityError
+	case logrus.WarnLevel:
+		return syslogSeverityWarning
+	case logrus.InfoLevel:
+		return syslogSeverityInfo
+	default:
+		return syslogSeverityDebug
+	}
+}
+
+func newTLSConfig(config *schema.TLS) *tls.Config {
+	var certificates []tls.Certificate
+
+	if config.PrivateKey != nil && config.CertificateChain.HasCertificates() {
+		certificates = []tls.Certificate{
+			{
+				Certificate: config.CertificateChain.CertificatesRaw(),
+				Leaf:        config.CertificateChain.Leaf(),
+				PrivateKey:  config.PrivateKey,
+			},
+		}
+	}
+
+	return &tls.Config{
+		ServerName:         config.ServerName,
+		InsecureSkipVerify: config.SkipVerify, //nolint:gosec // Informed choice by user. Off by default.
+		MinVersion:         config.MinimumVersion.MinVersion(),
+		MaxVersion:         config.MaximumVersion.MaxVersion(),
+		Certificates:       certificates,
+	}
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewSyslogOutput_ShouldErrorOnInvalidConfiguration(t *testing.T) {
+	output, err := newSyslogOutput(&schema.LogSyslog{Facility: "daemon"}, logrus.InfoLevel, &logrus.TextFormatter{})
+
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "the syslog server address is not configured")
+
+	address := schema.NewAddressFromNetworkValues(schema.AddressSchemeTCP, "127.0.0.1", 514)
+
+	output, err = newSyslogOutput(&schema.LogSyslog{Address: &schema.AddressTCP{Address: address}, Facility: "abc"}, logrus.InfoLevel, &logrus.TextFormatter{})
+
+	assert.Nil(t, output)
+	assert.EqualError(t, err, "unknown syslog facility 'abc'")
+}
+
+func TestSyslogOutput_ShouldFormatMessage(t *testing.T) {
+	output := &syslogOutput{facility: SyslogFacilities["daemon"], tag: "authelia", hostname: "example", pid: 123}
+
+	entry := &logrus.Entry{Time: time.Unix(1700000000, 0), Level: logrus.ErrorLevel}
+
+	message := output.message(entry, []byte("level=error msg=example\n"))
+
+	assert.Equal(t, `<27>1 2023-11-14T22:13:20.000000Z example authelia 123 - - level=error msg=example`, string(message))
+	assert.Equal(t, string(message), string(output.frame(message)))
+
+	output.stream = true
+
+	assert.Equal(t, `82 <27>1 2023-11-14T22:13:20.000000Z example authelia 123 - - level=error msg=example`, string(output.frame(message)))
+}
+
+func TestSyslogOutput_ShouldWriteToTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	address := schema.NewAddressFromNetworkValues(schema.AddressSchemeTCP, "127.0.0.1", listener.Addr().(*net.TCPAddr).Port)
+
+	output, err := newSyslogOutput(&schema.LogSyslog{Address: &schema.AddressTCP{Address: address}, Facility: "local0", Tag: "authelia", Timeout: time.Second * 5}, logrus.DebugLevel, &logrus.JSONFormatter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, logrus.DebugLevel, output.Level())
+
+	require.NoError(t, output.Write(&logrus.Entry{Time: time.Unix(1700000000, 0), Level: logrus.InfoLevel, Message: "example", Data: logrus.Fields{}}))
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+
+	reader := bufio.NewReader(conn)
+
+	length, err := reader.ReadString(' ')
+	require.NoError(t, err)
+
+	n, err := strconv.Atoi(length[:len(length)-1])
+	require.NoError(t, err)
+
+	buf := make([]byte, n)
+
+	_, err = reader.Read(buf)
+	require.NoError(t, err)
+
+	hostname, _ := os.Hostname()
+
+	assert.Regexp(t, `^<134>1 2023-11-14T22:13:20.000000Z `+hostname+` authelia `+strconv.Itoa(os.Getpid())+` - - \{"level":"info","msg":"example","time":"[^"]+"\}$`, string(buf))
+
+	assert.NoError(t, output.Close())
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	testCases := []struct {
+		level    logrus.Level
+		expected int
+	}{
+		{logrus.PanicLevel, 2},
+		{logrus.FatalLevel, 2},
+		{logrus.ErrorLevel, 3},
+		{logrus.WarnLevel, 4},
+		{logrus.InfoLevel, 6},
+		{logrus.DebugLevel, 7},
+		{logrus.TraceLevel, 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, syslogSeverity(tc.level))
+		})
+	}
+}
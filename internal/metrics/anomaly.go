@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func newAnomalyCollector(anomalies AnomalyCounter) *anomalyCollector {
+	return &anomalyCollector{
+		anomalies: anomalies,
+		failureRatio: prometheus.NewDesc(
+			prometheus.BuildFQName("", "authelia", "authn_anomaly_failure_ratio"),
+			"The ratio of the failed 1FA attempts to all of the 1FA attempts within the anomaly window.",
+			nil, nil,
+		),
+		usernamesPerIP: prometheus.NewDesc(
+			prometheus.BuildFQName("", "authelia", "authn_anomaly_usernames_per_ip"),
+			"The highest number of distinct usernames which failed 1FA from a single IP within the anomaly window.",
+			nil, nil,
+		),
+		ipsPerUsername: prometheus.NewDesc(
+			prometheus.BuildFQName("", "authelia", "authn_anomaly_ips_per_username"),
+			"The highest number of distinct IPs a single username failed 1FA from within the anomaly window.",
+			nil, nil,
+		),
+		active: prometheus.NewDesc(
+			prometheus.BuildFQName("", "authelia", "authn_anomaly_active"),
+			"The number of IPs or usernames currently exceeding the anomaly thresholds by pattern.",
+			[]string{"pattern"}, nil,
+		),
+	}
+}
+
+// anomalyCollector collects the anomaly statistics from a single snapshot so the values are consistent with each other.
+type anomalyCollector struct {
+	anomalies AnomalyCounter
+
+	failureRatio   *prometheus.Desc
+	usernamesPerIP *prometheus.Desc
+	ipsPerUsername *prometheus.Desc
+	active         *prometheus.Desc
+}
+
+// Describe implements prometheus.Collector.
+func (c *anomalyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.failureRatio
+	ch <- c.usernamesPerIP
+	ch <- c.ipsPerUsername
+	ch <- c.active
+}
+
+// Collect implements prometheus.Collector.
+func (c *anomalyCollector) Collect(ch chan<- prometheus.Metric) {
+	anomalies := c.anomalies.Anomalies()
+
+	ch <- prometheus.MustNewConstMetric(c.failureRatio, prometheus.GaugeValue, anomalies.FailureRatio())
+	ch <- prometheus.MustNewConstMetric(c.usernamesPerIP, prometheus.GaugeValue, float64(anomalies.UsernamesPerIPMax))
+	ch <- prometheus.MustNewConstMetric(c.ipsPerUsername, prometheus.GaugeValue, float64(anomalies.IPsPerUsernameMax))
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(anomalies.SpraySources), regulation.AnomalyPatternSpray)
+	ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(anomalies.DistributedTargets), regulation.AnomalyPatternDistributed)
+}
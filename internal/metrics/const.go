@@ -0,0 +1,38 @@
+package metrics
+
+const (
+	authnFactorOne = "1fa"
+	authnFactorTwo = "2fa"
+)
+
+// Flows recorded by the flow duration metrics.
+const (
+	FlowAuthz              = "authz"
+	FlowFirstFactor        = "1fa"
+	FlowSecondFactor       = "2fa"
+	FlowOpenIDConnectToken = "oidc_token"
+)
+
+const (
+	exemplarLabelTraceID = "trace_id"
+)
+
+const (
+	statsdPacketSize = 1432
+)
+
+const (
+	otlpAggregationTemporalityCumulative = 2
+	otlpScopeName                        = "github.com/authelia/authelia/v4/internal/metrics"
+)
+
+const (
+	authnMethodPassword = "password"
+	authnMethodLDAP     = "ldap"
+)
+
+const (
+	authnResultSuccess = "success"
+	authnResultFailure = "failure"
+	authnResultBanned  = "banned"
+)
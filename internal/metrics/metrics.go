@@ -4,18 +4,37 @@ import (
 	"time"
 
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // Provider implementation.
 type Provider interface {
 	Recorder
 	regulation.MetricsRecorder
+	storage.MetricsRecorder
 }
 
 // Recorder of metrics.
 type Recorder interface {
 	RecordRequest(statusCode, requestMethod string, elapsed time.Duration)
 	RecordRequestOpenIDConnect(endpoint, statusCode string, elapsed time.Duration)
+	RecordFlowDuration(flow, statusCode, traceID string, elapsed time.Duration)
 	RecordAuthz(statusCode string)
+	RecordAuthzDecision(rule, policy, decision string)
 	RecordAuthenticationDuration(success bool, elapsed time.Duration)
+	RecordPasswordReset(success bool)
+	RecordTOTPDrift(offset int)
+	RecordScannerFiltered(reason string)
+	RecordConfigurationDrift(instances int)
+	RecordNTPCheck(success bool, skew time.Duration, responded int)
+}
+
+// SessionCounter counts the active sessions.
+type SessionCounter interface {
+	Count() (count int)
+}
+
+// AnomalyCounter derives the anomaly statistics from the recent authentication attempts.
+type AnomalyCounter interface {
+	Anomalies() (anomalies regulation.Anomalies)
 }
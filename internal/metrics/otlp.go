@@ -0,0 +1,346 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewOTLP returns a new OpenTelemetry Protocol exporter which posts the metrics collected by the gatherer to the
+// configured OTLP/HTTP endpoint using the JSON encoding. All of the values are sent with the cumulative temporality.
+func NewOTLP(config *schema.TelemetryMetricsOTLP, gatherer prometheus.Gatherer, trusted *x509.CertPool) (exporter *OTLP) {
+	transport := &http.Transport{}
+
+	if config.TLS != nil {
+		transport.TLSClientConfig = utils.NewTLSConfig(config.TLS, trusted)
+	}
+
+	attributes := map[string]string{
+		"service.name":    "authelia",
+		"service.version": utils.Version(),
+	}
+
+	for key, value := range config.Attributes {
+		attributes[key] = value
+	}
+
+	return &OTLP{
+		config:   config,
+		gatherer: gatherer,
+		client:   &http.Client{Timeout: config.Timeout, Transport: transport},
+		resource: otlpResource{Attributes: otlpAttributes(attributes)},
+		start:    time.Now(),
+	}
+}
+
+// OTLP is a metrics exporter which pushes the metrics to an OpenTelemetry Protocol collector.
+type OTLP struct {
+	config   *schema.TelemetryMetricsOTLP
+	gatherer prometheus.Gatherer
+	client   *http.Client
+	resource otlpResource
+	start    time.Time
+}
+
+// Export gathers the metrics and posts them to the OTLP endpoint.
+func (e *OTLP) Export(ctx context.Context) (err error) {
+	var families []*dto.MetricFamily
+
+	if families, err = e.gatherer.Gather(); err != nil {
+		return fmt.Errorf("error gathering the metrics: %w", err)
+	}
+
+	var data []byte
+
+	if data, err = json.Marshal(e.request(families, time.Now())); err != nil {
+		return fmt.Errorf("error encoding the metrics: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint.String(), bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error creating the request: %w", err)
+	}
+
+	for key, value := range e.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("Authelia/%s", utils.Version()))
+
+	var resp *http.Response
+
+	if resp, err = e.client.Do(req); err != nil {
+		return fmt.Errorf("error posting the metrics: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("error posting the metrics: the endpoint responded with status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (e *OTLP) request(families []*dto.MetricFamily, now time.Time) otlpExportMetricsServiceRequest {
+	start, timestamp := strconv.FormatInt(e.start.UnixNano(), 10), strconv.FormatInt(now.UnixNano(), 10)
+
+	metrics := make([]otlpMetric, 0, len(families))
+
+	for _, family := range families {
+		metric := otlpMetric{Name: family.GetName(), Description: family.GetHelp()}
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			metric.Sum = &otlpSum{AggregationTemporality: otlpAggregationTemporalityCumulative, IsMonotonic: true}
+
+			for _, m := range family.GetMetric() {
+				metric.Sum.DataPoints = otlpAppendNumberDataPoint(metric.Sum.DataPoints, m.GetLabel(), start, timestamp, m.GetCounter().GetValue())
+			}
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			metric.Gauge = &otlpGauge{}
+
+			for _, m := range family.GetMetric() {
+				value := m.GetGauge().GetValue()
+
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+
+				metric.Gauge.DataPoints = otlpAppendNumberDataPoint(metric.Gauge.DataPoints, m.GetLabel(), "", timestamp, value)
+			}
+		case dto.MetricType_HISTOGRAM:
+			metric.Histogram = &otlpHistogram{AggregationTemporality: otlpAggregationTemporalityCumulative}
+
+			for _, m := range family.GetMetric() {
+				metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, newOTLPHistogramDataPoint(m, start, timestamp))
+			}
+		case dto.MetricType_SUMMARY:
+			metric.Summary = &otlpSummary{}
+
+			for _, m := range family.GetMetric() {
+				metric.Summary.DataPoints = append(metric.Summary.DataPoints, newOTLPSummaryDataPoint(m, start, timestamp))
+			}
+		default:
+			continue
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	return otlpExportMetricsServiceRequest{
+		ResourceMetrics: []otlpResourceMetrics{
+			{
+				Resource: e.resource,
+				ScopeMetrics: []otlpScopeMetrics{
+					{
+						Scope:   otlpScope{Name: otlpScopeName, Version: utils.Version()},
+						Metrics: metrics,
+					},
+				},
+			},
+		},
+	}
+}
+
+func otlpAppendNumberDataPoint(points []otlpNumberDataPoint, labels []*dto.LabelPair, start, timestamp string, value float64) []otlpNumberDataPoint {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return points
+	}
+
+	return append(points, otlpNumberDataPoint{
+		Attributes:        otlpLabelAttributes(labels),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		AsDouble:          value,
+	})
+}
+
+// newOTLPHistogramDataPoint converts the cumulative Prometheus buckets into the OTLP buckets which only count the
+// observations between the previous and the current bound, with the final bucket counting the observations above the
+// last explicit bound.
+func newOTLPHistogramDataPoint(m *dto.Metric, start, timestamp string) otlpHistogramDataPoint {
+	histogram := m.GetHistogram()
+
+	point := otlpHistogramDataPoint{
+		Attributes:        otlpLabelAttributes(m.GetLabel()),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Count:             strconv.FormatUint(histogram.GetSampleCount(), 10),
+		Sum:               histogram.GetSampleSum(),
+	}
+
+	var previous uint64
+
+	for _, bucket := range histogram.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			break
+		}
+
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(bucket.GetCumulativeCount()-previous, 10))
+
+		previous = bucket.GetCumulativeCount()
+	}
+
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(histogram.GetSampleCount()-previous, 10))
+
+	return point
+}
+
+func newOTLPSummaryDataPoint(m *dto.Metric, start, timestamp string) otlpSummaryDataPoint {
+	summary := m.GetSummary()
+
+	point := otlpSummaryDataPoint{
+		Attributes:        otlpLabelAttributes(m.GetLabel()),
+		StartTimeUnixNano: start,
+		TimeUnixNano:      timestamp,
+		Count:             strconv.FormatUint(summary.GetSampleCount(), 10),
+		Sum:               summary.GetSampleSum(),
+	}
+
+	for _, quantile := range summary.GetQuantile() {
+		if math.IsNaN(quantile.GetValue()) || math.IsInf(quantile.GetValue(), 0) {
+			continue
+		}
+
+		point.QuantileValues = append(point.QuantileValues, otlpValueAtQuantile{Quantile: quantile.GetQuantile(), Value: quantile.GetValue()})
+	}
+
+	return point
+}
+
+func otlpLabelAttributes(labels []*dto.LabelPair) (attributes []otlpKeyValue) {
+	for _, label := range labels {
+		attributes = append(attributes, otlpKeyValue{Key: label.GetName(), Value: otlpAnyValue{StringValue: label.GetValue()}})
+	}
+
+	return attributes
+}
+
+func otlpAttributes(values map[string]string) (attributes []otlpKeyValue) {
+	keys := make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attributes = append(attributes, otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: values[key]}})
+	}
+
+	return attributes
+}
+
+type otlpExportMetricsServiceRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   otlpScope    `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpMetric struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Sum         *otlpSum       `json:"sum,omitempty"`
+	Gauge       *otlpGauge     `json:"gauge,omitempty"`
+	Histogram   *otlpHistogram `json:"histogram,omitempty"`
+	Summary     *otlpSummary   `json:"summary,omitempty"`
+}
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpNumberDataPoint `json:"dataPoints"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpSummary struct {
+	DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+}
+
+// otlpNumberDataPoint and the other data points encode the 64-bit integers as strings as required by the OTLP JSON
+// encoding.
+type otlpNumberDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          float64        `json:"asDouble"`
+}
+
+type otlpHistogramDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	Count             string         `json:"count"`
+	Sum               float64        `json:"sum"`
+	BucketCounts      []string       `json:"bucketCounts"`
+	ExplicitBounds    []float64      `json:"explicitBounds"`
+}
+
+type otlpSummaryDataPoint struct {
+	Attributes        []otlpKeyValue        `json:"attributes,omitempty"`
+	StartTimeUnixNano string                `json:"startTimeUnixNano"`
+	TimeUnixNano      string                `json:"timeUnixNano"`
+	Count             string                `json:"count"`
+	Sum               float64               `json:"sum"`
+	QuantileValues    []otlpValueAtQuantile `json:"quantileValues,omitempty"`
+}
+
+type otlpValueAtQuantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
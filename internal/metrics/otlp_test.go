@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestOTLPExport(t *testing.T) {
+	testCases := []struct {
+		name        string
+		status      int
+		expectedErr string
+	}{
+		{"ShouldExport", http.StatusOK, ""},
+		{"ShouldReturnErrStatusCode", http.StatusServiceUnavailable, "error posting the metrics: the endpoint responded with status code 503"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var received otlpExportMetricsServiceRequest
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v1/metrics", r.URL.Path)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "abc123", r.Header.Get("X-API-Key"))
+
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+				w.WriteHeader(tc.status)
+			}))
+
+			defer server.Close()
+
+			endpoint, err := url.Parse(server.URL + "/v1/metrics")
+			require.NoError(t, err)
+
+			registry := prometheus.NewRegistry()
+
+			request := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_request", Help: "The requests."}, []string{"code"})
+			duration := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_duration", Buckets: []float64{.5, 1}})
+			active := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_active"})
+
+			registry.MustRegister(request, duration, active)
+
+			request.WithLabelValues("200").Add(4)
+			duration.Observe(.25)
+			duration.Observe(.75)
+			duration.Observe(2)
+			active.Set(3)
+
+			exporter := NewOTLP(&schema.TelemetryMetricsOTLP{
+				Endpoint:   endpoint,
+				Headers:    map[string]string{"X-API-Key": "abc123"},
+				Attributes: map[string]string{"deployment.environment": "prod"},
+				Timeout:    time.Second,
+			}, registry, nil)
+
+			err = exporter.Export(context.Background())
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, received.ResourceMetrics, 1)
+
+			resource := received.ResourceMetrics[0]
+
+			require.Len(t, resource.Resource.Attributes, 3)
+			assert.Equal(t, otlpKeyValue{Key: "deployment.environment", Value: otlpAnyValue{StringValue: "prod"}}, resource.Resource.Attributes[0])
+			assert.Equal(t, otlpKeyValue{Key: "service.name", Value: otlpAnyValue{StringValue: "authelia"}}, resource.Resource.Attributes[1])
+
+			require.Len(t, resource.ScopeMetrics, 1)
+			assert.Equal(t, otlpScopeName, resource.ScopeMetrics[0].Scope.Name)
+
+			metrics := resource.ScopeMetrics[0].Metrics
+
+			require.Len(t, metrics, 3)
+
+			assert.Equal(t, "test_active", metrics[0].Name)
+			require.NotNil(t, metrics[0].Gauge)
+			require.Len(t, metrics[0].Gauge.DataPoints, 1)
+			assert.Equal(t, 3.0, metrics[0].Gauge.DataPoints[0].AsDouble)
+
+			assert.Equal(t, "test_duration", metrics[1].Name)
+			require.NotNil(t, metrics[1].Histogram)
+			require.Len(t, metrics[1].Histogram.DataPoints, 1)
+			assert.Equal(t, otlpAggregationTemporalityCumulative, metrics[1].Histogram.AggregationTemporality)
+			assert.Equal(t, "3", metrics[1].Histogram.DataPoints[0].Count)
+			assert.Equal(t, 3.0, metrics[1].Histogram.DataPoints[0].Sum)
+			assert.Equal(t, []float64{.5, 1}, metrics[1].Histogram.DataPoints[0].ExplicitBounds)
+			assert.Equal(t, []string{"1", "1", "1"}, metrics[1].Histogram.DataPoints[0].BucketCounts)
+
+			assert.Equal(t, "test_request", metrics[2].Name)
+			assert.Equal(t, "The requests.", metrics[2].Description)
+			require.NotNil(t, metrics[2].Sum)
+			assert.True(t, metrics[2].Sum.IsMonotonic)
+			require.Len(t, metrics[2].Sum.DataPoints, 1)
+			assert.Equal(t, 4.0, metrics[2].Sum.DataPoints[0].AsDouble)
+			assert.Equal(t, []otlpKeyValue{{Key: "code", Value: otlpAnyValue{StringValue: "200"}}}, metrics[2].Sum.DataPoints[0].Attributes)
+			assert.NotEmpty(t, metrics[2].Sum.DataPoints[0].StartTimeUnixNano)
+			assert.NotEmpty(t, metrics[2].Sum.DataPoints[0].TimeUnixNano)
+		})
+	}
+}
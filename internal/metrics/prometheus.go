@@ -3,17 +3,27 @@ package metrics
 import (
 	"strconv"
 	"time"
+	"unicode/utf8"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-// NewPrometheus returns a new Prometheus metrics recorder.
-func NewPrometheus() (provider *Prometheus) {
+// NewPrometheus returns a new Prometheus metrics recorder. The number of active sessions is recorded when the sessions
+// counter is not nil, and likewise the anomaly statistics are recorded when the anomaly counter is not nil.
+func NewPrometheus(sessions SessionCounter, anomalies AnomalyCounter) (provider *Prometheus) {
 	provider = &Prometheus{}
 
 	provider.register()
 
+	if sessions != nil {
+		provider.registerSessions(sessions)
+	}
+
+	if anomalies != nil {
+		provider.registerAnomalies(anomalies)
+	}
+
 	return provider
 }
 
@@ -22,10 +32,25 @@ type Prometheus struct {
 	authnDuration   *prometheus.HistogramVec
 	reqDuration     *prometheus.HistogramVec
 	reqDurationOIDC *prometheus.HistogramVec
+	flowDuration    *prometheus.HistogramVec
 	reqCounter      *prometheus.CounterVec
 	authzCounter    *prometheus.CounterVec
+	authzDecision   *prometheus.CounterVec
 	authnCounter    *prometheus.CounterVec
 	authn2FACounter *prometheus.CounterVec
+	authnAttempt    *prometheus.CounterVec
+	authnBan        *prometheus.CounterVec
+	authnAnomaly    *prometheus.CounterVec
+	passwordReset   *prometheus.CounterVec
+	totpDrift       prometheus.Histogram
+	scannerCounter  *prometheus.CounterVec
+	configDrift     prometheus.Gauge
+	ntpCheck        *prometheus.CounterVec
+	ntpSkew         prometheus.Gauge
+	ntpResponded    prometheus.Gauge
+	storageCleanup  *prometheus.CounterVec
+	sessions        prometheus.GaugeFunc
+	anomalies       prometheus.Collector
 }
 
 // RecordRequest takes the statusCode string, requestMethod string, and the elapsed time.Duration to record the request and request duration metrics.
@@ -39,19 +64,66 @@ func (r *Prometheus) RecordRequestOpenIDConnect(endpoint, statusCode string, ela
 	r.reqDurationOIDC.WithLabelValues(endpoint, statusCode).Observe(elapsed.Seconds())
 }
 
+// RecordFlowDuration takes the flow string, statusCode string, traceID string, and the elapsed time.Duration to record
+// the end-to-end duration metrics of the flow. The trace id is attached to the observation as an exemplar if it's not
+// empty so the slow requests can be correlated with the logs, audit events, and traces.
+func (r *Prometheus) RecordFlowDuration(flow, statusCode, traceID string, elapsed time.Duration) {
+	observer := r.flowDuration.WithLabelValues(flow, statusCode)
+
+	if exemplar, ok := observer.(prometheus.ExemplarObserver); ok && isValidExemplarTraceID(traceID) {
+		exemplar.ObserveWithExemplar(elapsed.Seconds(), prometheus.Labels{exemplarLabelTraceID: traceID})
+
+		return
+	}
+
+	observer.Observe(elapsed.Seconds())
+}
+
 // RecordAuthz takes the statusCode string to record the verify endpoint request metrics.
 func (r *Prometheus) RecordAuthz(statusCode string) {
 	r.authzCounter.WithLabelValues(statusCode).Inc()
 }
 
+// RecordAuthzDecision takes the rule, policy, and decision strings to record the authorization decision metrics.
+func (r *Prometheus) RecordAuthzDecision(rule, policy, decision string) {
+	r.authzDecision.WithLabelValues(rule, policy, decision).Inc()
+}
+
 // RecordAuthn takes the success and regulated booleans and a method string to record the authentication metrics.
 func (r *Prometheus) RecordAuthn(success, banned bool, authType string) {
-	switch authType {
-	case "1fa", "":
+	factor, method := authnFactorAndMethod(authType)
+
+	switch factor {
+	case authnFactorOne:
 		r.authnCounter.WithLabelValues(strconv.FormatBool(success), strconv.FormatBool(banned)).Inc()
 	default:
 		r.authn2FACounter.WithLabelValues(strconv.FormatBool(success), strconv.FormatBool(banned), authType).Inc()
 	}
+
+	r.authnAttempt.WithLabelValues(factor, method, authnResult(success, banned)).Inc()
+}
+
+// RecordAuthnBan takes the method string to record the metrics of the bans issued by the regulator.
+func (r *Prometheus) RecordAuthnBan(authType string) {
+	factor, method := authnFactorAndMethod(authType)
+
+	r.authnBan.WithLabelValues(factor, method).Inc()
+}
+
+// RecordAuthnAnomaly takes the pattern string to record the metrics of the anomalies detected by the regulator.
+func (r *Prometheus) RecordAuthnAnomaly(pattern string) {
+	r.authnAnomaly.WithLabelValues(pattern).Inc()
+}
+
+// RecordPasswordReset takes the success boolean to record the password reset metrics.
+func (r *Prometheus) RecordPasswordReset(success bool) {
+	r.passwordReset.WithLabelValues(strconv.FormatBool(success)).Inc()
+}
+
+// RecordTOTPDrift takes the offset in periods of a validated TOTP code from the current period to record the TOTP
+// clock drift distribution metrics.
+func (r *Prometheus) RecordTOTPDrift(offset int) {
+	r.totpDrift.Observe(float64(offset))
 }
 
 // RecordAuthenticationDuration takes the statusCode string, requestMethod string, and the elapsed time.Duration to record the request and request duration metrics.
@@ -59,6 +131,34 @@ func (r *Prometheus) RecordAuthenticationDuration(success bool, elapsed time.Dur
 	r.authnDuration.WithLabelValues(strconv.FormatBool(success)).Observe(elapsed.Seconds())
 }
 
+// RecordScannerFiltered takes the reason string to record the scanner filter metrics.
+func (r *Prometheus) RecordScannerFiltered(reason string) {
+	r.scannerCounter.WithLabelValues(reason).Inc()
+}
+
+// RecordConfigurationDrift takes the number of instances which have a different effective configuration to this
+// instance to record the configuration drift metrics.
+func (r *Prometheus) RecordConfigurationDrift(instances int) {
+	r.configDrift.Set(float64(instances))
+}
+
+// RecordNTPCheck takes the success boolean, the clock skew time.Duration, and the number of the NTP servers which
+// responded to record the clock skew metrics. The clock skew is only recorded when the check was successful.
+func (r *Prometheus) RecordNTPCheck(success bool, skew time.Duration, responded int) {
+	r.ntpCheck.WithLabelValues(strconv.FormatBool(success)).Inc()
+	r.ntpResponded.Set(float64(responded))
+
+	if success {
+		r.ntpSkew.Set(skew.Seconds())
+	}
+}
+
+// RecordStorageCleanup takes the table string and the number of rows deleted from it to record the storage cleanup
+// metrics.
+func (r *Prometheus) RecordStorageCleanup(table string, deleted int64) {
+	r.storageCleanup.WithLabelValues(table).Add(float64(deleted))
+}
+
 func (r *Prometheus) register() {
 	r.authnDuration = promauto.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -90,6 +190,16 @@ func (r *Prometheus) register() {
 		[]string{"endpoint", "code"},
 	)
 
+	r.flowDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Subsystem: "authelia",
+			Name:      "flow_duration",
+			Help:      "The end-to-end time the authz, login, and OpenID Connect 1.0 token flows take in seconds.",
+			Buckets:   []float64{.005, .01, .025, .05, .075, .1, .15, .2, .3, .4, .5, .75, 1, 1.5, 2, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"flow", "code"},
+	)
+
 	r.reqCounter = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: "authelia",
@@ -108,6 +218,15 @@ func (r *Prometheus) register() {
 		[]string{"code"},
 	)
 
+	r.authzDecision = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "authz_decision",
+			Help:      "The number of authz decisions made by each access control rule.",
+		},
+		[]string{"rule", "policy", "decision"},
+	)
+
 	r.authnCounter = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Subsystem: "authelia",
@@ -125,4 +244,145 @@ func (r *Prometheus) register() {
 		},
 		[]string{"success", "banned", "type"},
 	)
+
+	r.authnAttempt = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "authn_attempt",
+			Help:      "The number of authentication attempts processed by factor, method, and result.",
+		},
+		[]string{"factor", "method", "result"},
+	)
+
+	r.authnBan = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "authn_ban",
+			Help:      "The number of bans issued by the regulator.",
+		},
+		[]string{"factor", "method"},
+	)
+
+	r.authnAnomaly = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "authn_anomaly",
+			Help:      "The number of authentication anomalies detected by the regulator.",
+		},
+		[]string{"pattern"},
+	)
+
+	r.passwordReset = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "password_reset",
+			Help:      "The number of password resets processed.",
+		},
+		[]string{"success"},
+	)
+
+	r.totpDrift = promauto.NewHistogram(
+		prometheus.HistogramOpts{
+			Subsystem: "authelia",
+			Name:      "totp_drift",
+			Help:      "The offset in periods of the validated TOTP codes from the current period, which is positive when the clock of the device is ahead.",
+			Buckets:   []float64{-10, -5, -4, -3, -2, -1, 0, 1, 2, 3, 4, 5, 10},
+		},
+	)
+
+	r.scannerCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "scanner_filtered",
+			Help:      "The number of requests filtered by the scanner filter.",
+		},
+		[]string{"reason"},
+	)
+
+	r.configDrift = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "authelia",
+			Name:      "configuration_drift",
+			Help:      "The number of other instances which have a different effective configuration to this instance.",
+		},
+	)
+
+	r.ntpCheck = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "ntp_check",
+			Help:      "The number of clock skew checks against the NTP servers.",
+		},
+		[]string{"success"},
+	)
+
+	r.ntpSkew = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "authelia",
+			Name:      "ntp_clock_skew",
+			Help:      "The clock skew in seconds measured against the NTP servers, which is positive when the system clock is behind.",
+		},
+	)
+
+	r.ntpResponded = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: "authelia",
+			Name:      "ntp_servers_responded",
+			Help:      "The number of NTP servers which responded to the latest clock skew check.",
+		},
+	)
+
+	r.storageCleanup = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: "authelia",
+			Name:      "storage_cleanup_deleted",
+			Help:      "The number of expired rows deleted from each storage table by the cleanup.",
+		},
+		[]string{"table"},
+	)
+}
+
+func (r *Prometheus) registerSessions(sessions SessionCounter) {
+	r.sessions = promauto.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Subsystem: "authelia",
+			Name:      "active_sessions",
+			Help:      "The number of sessions stored by the session provider.",
+		},
+		func() float64 {
+			return float64(sessions.Count())
+		},
+	)
+}
+
+func (r *Prometheus) registerAnomalies(anomalies AnomalyCounter) {
+	r.anomalies = newAnomalyCollector(anomalies)
+
+	prometheus.MustRegister(r.anomalies)
+}
+
+func authnFactorAndMethod(authType string) (factor, method string) {
+	switch authType {
+	case "1fa", "":
+		return authnFactorOne, authnMethodPassword
+	case authnMethodLDAP:
+		return authnFactorOne, authnMethodLDAP
+	default:
+		return authnFactorTwo, authType
+	}
+}
+
+func authnResult(success, banned bool) string {
+	switch {
+	case banned:
+		return authnResultBanned
+	case success:
+		return authnResultSuccess
+	default:
+		return authnResultFailure
+	}
+}
+
+func isValidExemplarTraceID(traceID string) bool {
+	return traceID != "" && utf8.ValidString(traceID) && utf8.RuneCountInString(exemplarLabelTraceID)+utf8.RuneCountInString(traceID) <= prometheus.ExemplarMaxRunes
 }
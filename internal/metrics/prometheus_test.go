@@ -1,20 +1,154 @@
 package metrics
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/regulation"
 )
 
 func TestNewPrometheus(t *testing.T) {
-	p := NewPrometheus()
+	p := NewPrometheus(&testSessionCounter{count: 5}, &testAnomalyCounter{anomalies: regulation.Anomalies{Attempts: 4, Failures: 3, UsernamesPerIPMax: 6, IPsPerUsernameMax: 2, SpraySources: 1}})
 
 	assert.NotNil(t, p)
+	assert.NotNil(t, p.sessions)
+	assert.NotNil(t, p.anomalies)
 
 	p.RecordRequest("400", "GET", time.Second)
 	p.RecordAuthz("400")
+	p.RecordAuthzDecision("1", "one_factor", "authorized")
+	p.RecordAuthzDecision("default", "deny", "forbidden")
 	p.RecordAuthn(true, false, "WebAuthn")
 	p.RecordAuthn(true, false, "1fa")
+	p.RecordAuthn(false, true, "1fa")
+	p.RecordAuthnBan("1fa")
+	p.RecordAuthnBan("totp")
+	p.RecordAuthnAnomaly(regulation.AnomalyPatternSpray)
+	p.RecordPasswordReset(true)
+	p.RecordTOTPDrift(-2)
 	p.RecordAuthenticationDuration(true, time.Second)
+	p.RecordScannerFiltered("path")
+	p.RecordConfigurationDrift(1)
+	p.RecordNTPCheck(true, -time.Millisecond*1500, 3)
+	p.RecordNTPCheck(false, 0, 1)
+	p.RecordStorageCleanup("oauth2_blacklisted_jti", 10)
+	p.RecordFlowDuration(FlowFirstFactor, "200", "4bf92f3577b34da6a3ce929d0e0e4736", 150*time.Millisecond)
+	p.RecordFlowDuration(FlowAuthz, "200", "", time.Millisecond)
+	p.RecordFlowDuration(FlowOpenIDConnectToken, "200", strings.Repeat("a", 200), time.Millisecond)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	gauges := map[string]float64{}
+
+	for _, family := range families {
+		if family.GetType() != dto.MetricType_GAUGE || !strings.HasPrefix(family.GetName(), "authelia_authn_anomaly_") && !strings.HasPrefix(family.GetName(), "authelia_ntp_") {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			name := family.GetName()
+
+			for _, label := range metric.GetLabel() {
+				name += "{" + label.GetValue() + "}"
+			}
+
+			gauges[name] = metric.GetGauge().GetValue()
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"authelia_authn_anomaly_failure_ratio":       0.75,
+		"authelia_authn_anomaly_usernames_per_ip":    6,
+		"authelia_authn_anomaly_ips_per_username":    2,
+		"authelia_authn_anomaly_active{spray}":       1,
+		"authelia_authn_anomaly_active{distributed}": 0,
+		"authelia_ntp_clock_skew":                    -1.5,
+		"authelia_ntp_servers_responded":             1,
+	}, gauges)
+
+	exemplars := map[string]string{}
+
+	for _, family := range families {
+		if family.GetName() != "authelia_flow_duration" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() != "flow" {
+					continue
+				}
+
+				for _, bucket := range metric.GetHistogram().GetBucket() {
+					for _, pair := range bucket.GetExemplar().GetLabel() {
+						exemplars[label.GetValue()] = pair.GetValue()
+					}
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]string{FlowFirstFactor: "4bf92f3577b34da6a3ce929d0e0e4736"}, exemplars)
+}
+
+func TestIsValidExemplarTraceID(t *testing.T) {
+	assert.True(t, isValidExemplarTraceID("4bf92f3577b34da6a3ce929d0e0e4736"))
+	assert.False(t, isValidExemplarTraceID(""))
+	assert.False(t, isValidExemplarTraceID(strings.Repeat("a", 121)))
+	assert.False(t, isValidExemplarTraceID("\xff"))
+}
+
+func TestAuthnFactorAndMethod(t *testing.T) {
+	testCases := []struct {
+		name   string
+		have   string
+		factor string
+		method string
+	}{
+		{"ShouldHandleFirstFactor", "1fa", "1fa", "password"},
+		{"ShouldHandleEmpty", "", "1fa", "password"},
+		{"ShouldHandleLDAP", "ldap", "1fa", "ldap"},
+		{"ShouldHandleTOTP", "totp", "2fa", "totp"},
+		{"ShouldHandleWebAuthn", "webauthn", "2fa", "webauthn"},
+		{"ShouldHandleDuo", "duo", "2fa", "duo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			factor, method := authnFactorAndMethod(tc.have)
+
+			assert.Equal(t, tc.factor, factor)
+			assert.Equal(t, tc.method, method)
+		})
+	}
+}
+
+func TestAuthnResult(t *testing.T) {
+	assert.Equal(t, "success", authnResult(true, false))
+	assert.Equal(t, "failure", authnResult(false, false))
+	assert.Equal(t, "banned", authnResult(false, true))
+	assert.Equal(t, "banned", authnResult(true, true))
+}
+
+type testSessionCounter struct {
+	count int
+}
+
+func (c *testSessionCounter) Count() int {
+	return c.count
+}
+
+type testAnomalyCounter struct {
+	anomalies regulation.Anomalies
+}
+
+func (c *testAnomalyCounter) Anomalies() regulation.Anomalies {
+	return c.anomalies
 }
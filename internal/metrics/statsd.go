@@ -0,0 +1,244 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewStatsD returns a new StatsD exporter which sends the metrics collected by the gatherer to the configured StatsD
+// server. The counters are sent as the difference since the previous export, the gauges as their current value, and
+// the histograms and summaries as the difference of their count and sum.
+func NewStatsD(config *schema.TelemetryMetricsStatsD, gatherer prometheus.Gatherer) (exporter *StatsD) {
+	exporter = &StatsD{
+		config:   config,
+		gatherer: gatherer,
+		prefix:   strings.TrimSuffix(config.Prefix, "."),
+		previous: map[string]float64{},
+	}
+
+	if config.Flavor == schema.TelemetryMetricsStatsDFlavorDogStatsD {
+		for key, value := range config.Tags {
+			exporter.tags = append(exporter.tags, statsdSanitize(key)+":"+statsdSanitize(value))
+		}
+
+		sort.Strings(exporter.tags)
+	}
+
+	return exporter
+}
+
+// StatsD is a metrics exporter which pushes the metrics to a StatsD or DogStatsD server.
+type StatsD struct {
+	config   *schema.TelemetryMetricsStatsD
+	gatherer prometheus.Gatherer
+	prefix   string
+	tags     []string
+
+	mu       sync.Mutex
+	previous map[string]float64
+}
+
+// Export gathers the metrics and sends them to the StatsD server.
+func (e *StatsD) Export(ctx context.Context) (err error) {
+	var families []*dto.MetricFamily
+
+	if families, err = e.gatherer.Gather(); err != nil {
+		return fmt.Errorf("error gathering the metrics: %w", err)
+	}
+
+	e.mu.Lock()
+
+	defer e.mu.Unlock()
+
+	lines, current := e.lines(families)
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	var conn net.Conn
+
+	if conn, err = e.config.Address.Dial(); err != nil {
+		return fmt.Errorf("error connecting to the statsd server: %w", err)
+	}
+
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+
+	for _, packet := range statsdPackets(lines, statsdPacketSize) {
+		if _, err = conn.Write(packet); err != nil {
+			return fmt.Errorf("error writing the metrics to the statsd server: %w", err)
+		}
+	}
+
+	e.previous = current
+
+	return nil
+}
+
+// lines returns the StatsD lines for the metric families alongside the cumulative values the next deltas are
+// calculated from.
+func (e *StatsD) lines(families []*dto.MetricFamily) (lines []string, current map[string]float64) {
+	current = make(map[string]float64, len(e.previous))
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			name, tags := e.name(family.GetName(), metric.GetLabel())
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				lines = e.appendCounter(lines, current, name, tags, metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = e.appendGauge(lines, name, tags, metric.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				lines = e.appendGauge(lines, name, tags, metric.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				lines = e.appendCounter(lines, current, name+".count", tags, float64(metric.GetHistogram().GetSampleCount()))
+				lines = e.appendCounter(lines, current, name+".sum", tags, metric.GetHistogram().GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				lines = e.appendCounter(lines, current, name+".count", tags, float64(metric.GetSummary().GetSampleCount()))
+				lines = e.appendCounter(lines, current, name+".sum", tags, metric.GetSummary().GetSampleSum())
+			}
+		}
+	}
+
+	return lines, current
+}
+
+func (e *StatsD) appendCounter(lines []string, current map[string]float64, name, tags string, value float64) []string {
+	if !statsdIsFinite(value) {
+		return lines
+	}
+
+	key := name + tags
+
+	current[key] = value
+
+	delta := value
+
+	// A lower value than the previous one means the counter has been reset, so the whole value is the delta.
+	if previous, ok := e.previous[key]; ok && value >= previous {
+		delta = value - previous
+	}
+
+	if delta == 0 {
+		return lines
+	}
+
+	return append(lines, name+":"+statsdFormatFloat(delta)+"|c"+tags)
+}
+
+func (e *StatsD) appendGauge(lines []string, name, tags string, value float64) []string {
+	if !statsdIsFinite(value) {
+		return lines
+	}
+
+	// A signed gauge value is interpreted as a relative change, so negative values have to be preceded by a reset.
+	if value < 0 {
+		lines = append(lines, name+":0|g"+tags)
+	}
+
+	return append(lines, name+":"+statsdFormatFloat(value)+"|g"+tags)
+}
+
+// name returns the StatsD name and the tags suffix of a metric. The label values are appended to the name for the
+// statsd flavor as it has no concept of tags, whereas they're sent as tags for the dogstatsd flavor.
+func (e *StatsD) name(family string, labels []*dto.LabelPair) (name, tags string) {
+	b := strings.Builder{}
+
+	if e.prefix != "" {
+		b.WriteString(e.prefix)
+		b.WriteRune('.')
+	}
+
+	b.WriteString(statsdSanitize(family))
+
+	if e.config.Flavor != schema.TelemetryMetricsStatsDFlavorDogStatsD {
+		for _, label := range labels {
+			if label.GetValue() == "" {
+				continue
+			}
+
+			b.WriteRune('.')
+			b.WriteString(strings.ReplaceAll(statsdSanitize(label.GetValue()), ".", "_"))
+		}
+
+		return b.String(), ""
+	}
+
+	values := make([]string, 0, len(labels)+len(e.tags))
+
+	for _, label := range labels {
+		if label.GetValue() == "" {
+			continue
+		}
+
+		values = append(values, statsdSanitize(label.GetName())+":"+statsdSanitize(label.GetValue()))
+	}
+
+	values = append(values, e.tags...)
+
+	if len(values) == 0 {
+		return b.String(), ""
+	}
+
+	return b.String(), "|#" + strings.Join(values, ",")
+}
+
+// statsdPackets joins the lines into packets which don't exceed the size unless a single line exceeds it.
+func statsdPackets(lines []string, size int) (packets [][]byte) {
+	var packet []byte
+
+	for _, line := range lines {
+		if len(packet) != 0 && len(packet)+len(line)+1 > size {
+			packets = append(packets, packet)
+			packet = nil
+		}
+
+		if len(packet) != 0 {
+			packet = append(packet, '\n')
+		}
+
+		packet = append(packet, line...)
+	}
+
+	if len(packet) != 0 {
+		packets = append(packets, packet)
+	}
+
+	return packets
+}
+
+// statsdSanitize replaces the characters which have a special meaning in the StatsD protocol.
+func statsdSanitize(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', ' ', '\t', '\n', '\r':
+			return '_'
+		default:
+			return r
+		}
+	}, value)
+}
+
+func statsdFormatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func statsdIsFinite(value float64) bool {
+	return !math.IsNaN(value) && !math.IsInf(value, 0)
+}
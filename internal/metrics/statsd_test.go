@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestStatsDExport(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config schema.TelemetryMetricsStatsD
+		first  []string
+		second []string
+	}{
+		{
+			"ShouldExportStatsD",
+			schema.TelemetryMetricsStatsD{Flavor: schema.TelemetryMetricsStatsDFlavorStatsD, Prefix: "authelia."},
+			[]string{
+				"authelia.test_active:3|g",
+				"authelia.test_duration.200.count:2|c",
+				"authelia.test_duration.200.sum:1.5|c",
+				"authelia.test_request.200.GET:4|c",
+			},
+			[]string{
+				"authelia.test_active:0|g",
+				"authelia.test_active:-1|g",
+				"authelia.test_request.200.GET:1|c",
+			},
+		},
+		{
+			"ShouldExportDogStatsD",
+			schema.TelemetryMetricsStatsD{Flavor: schema.TelemetryMetricsStatsDFlavorDogStatsD, Tags: map[string]string{"env": "prod"}},
+			[]string{
+				"test_active:3|g|#env:prod",
+				"test_duration.count:2|c|#code:200,env:prod",
+				"test_duration.sum:1.5|c|#code:200,env:prod",
+				"test_request:4|c|#code:200,method:GET,env:prod",
+			},
+			[]string{
+				"test_active:0|g|#env:prod",
+				"test_active:-1|g|#env:prod",
+				"test_request:1|c|#code:200,method:GET,env:prod",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			defer conn.Close()
+
+			address, err := schema.NewAddress("udp://" + conn.LocalAddr().String())
+			require.NoError(t, err)
+
+			tc.config.Address = &schema.AddressUDP{Address: *address}
+
+			registry := prometheus.NewRegistry()
+
+			request := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_request"}, []string{"code", "method"})
+			duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_duration", Buckets: []float64{.5, 1}}, []string{"code"})
+			active := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_active"})
+
+			registry.MustRegister(request, duration, active)
+
+			request.WithLabelValues("200", "GET").Add(4)
+			duration.WithLabelValues("200").Observe(.5)
+			duration.WithLabelValues("200").Observe(1)
+			active.Set(3)
+
+			exporter := NewStatsD(&tc.config, registry)
+
+			require.NoError(t, exporter.Export(context.Background()))
+			assert.Equal(t, tc.first, testStatsDReadLines(t, conn))
+
+			request.WithLabelValues("200", "GET").Inc()
+			active.Set(-1)
+
+			require.NoError(t, exporter.Export(context.Background()))
+			assert.Equal(t, tc.second, testStatsDReadLines(t, conn))
+		})
+	}
+}
+
+func TestStatsDPackets(t *testing.T) {
+	packets := statsdPackets([]string{"a:1|c", "b:2|c", "c:3|c", strings.Repeat("d", 20)}, 12)
+
+	require.Len(t, packets, 3)
+	assert.Equal(t, "a:1|c\nb:2|c", string(packets[0]))
+	assert.Equal(t, "c:3|c", string(packets[1]))
+	assert.Equal(t, strings.Repeat("d", 20), string(packets[2]))
+
+	assert.Nil(t, statsdPackets(nil, 12))
+}
+
+func TestStatsDSanitize(t *testing.T) {
+	assert.Equal(t, "a_b_c_d_e_f_g", statsdSanitize("a:b|c@d#e,f g"))
+	assert.Equal(t, "authelia.request", statsdSanitize("authelia.request"))
+}
+
+func testStatsDReadLines(t *testing.T, conn net.PacketConn) []string {
+	buf := make([]byte, statsdPacketSize)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return strings.Split(string(buf[:n]), "\n")
+}
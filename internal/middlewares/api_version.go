@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// StripAPIVersion rewrites the path of requests to the /api/v2/ prefix to the unversioned /api/ prefix so they're
+// routed to the same handlers, and records the version of the API the request was made to so the handlers are able to
+// respond accordingly. The OpenID Connect 1.0 and authz endpoints are not part of the versioned API.
+func StripAPIVersion(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		uri := string(ctx.RequestURI())
+
+		if suffix, ok := strings.CutPrefix(uri, pathAPIVersion2); ok && isAPIVersionSuffix(suffix) &&
+			!strings.HasPrefix(uri, pathAPIVersion2OIDC) && !strings.HasPrefix(uri, pathAPIVersion2Authz) {
+			if _, ok = ctx.UserValue(UserValueKeyRawURI).(string); !ok {
+				ctx.SetUserValue(UserValueKeyRawURI, uri)
+			}
+
+			ctx.SetUserValue(UserValueKeyAPIVersion, APIVersion2)
+
+			if suffix == "" || suffix[0] == '?' {
+				suffix = strSlash + suffix
+			}
+
+			ctx.Request.SetRequestURI(pathAPI + suffix)
+		}
+
+		next(ctx)
+	}
+}
+
+func isAPIVersionSuffix(suffix string) bool {
+	return suffix == "" || suffix[0] == '/' || suffix[0] == '?'
+}
+
+// NewErrorCode returns the ErrorCode which describes an error response with the given status code.
+func NewErrorCode(status int) ErrorCode {
+	switch {
+	case status == fasthttp.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case status == fasthttp.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case status == fasthttp.StatusForbidden:
+		return ErrorCodeForbidden
+	case status == fasthttp.StatusNotFound:
+		return ErrorCodeNotFound
+	case status == fasthttp.StatusConflict:
+		return ErrorCodeConflict
+	case status == fasthttp.StatusTooManyRequests:
+		return ErrorCodeTooManyRequests
+	case status >= fasthttp.StatusInternalServerError:
+		return ErrorCodeServerError
+	case status >= fasthttp.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	default:
+		return ErrorCodeOperationFailed
+	}
+}
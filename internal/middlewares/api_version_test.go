@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestStripAPIVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		uri      string
+		expected string
+		version  int
+	}{
+		{"ShouldStripVersion", "/api/v2/state", "/api/state", APIVersion2},
+		{"ShouldStripVersionWithQuery", "/api/v2/user/activity/authentications?limit=10", "/api/user/activity/authentications?limit=10", APIVersion2},
+		{"ShouldStripVersionRoot", "/api/v2", "/api/", APIVersion2},
+		{"ShouldStripVersionRootSlash", "/api/v2/", "/api/", APIVersion2},
+		{"ShouldStripVersionRootQuery", "/api/v2?x=1", "/api/?x=1", APIVersion2},
+		{"ShouldNotStripUnversioned", "/api/state", "/api/state", APIVersion1},
+		{"ShouldNotStripSimilarPrefix", "/api/v20/state", "/api/v20/state", APIVersion1},
+		{"ShouldNotStripOpenIDConnect", "/api/v2/oidc/token", "/api/v2/oidc/token", APIVersion1},
+		{"ShouldNotStripAuthz", "/api/v2/authz/forward-auth", "/api/v2/authz/forward-auth", APIVersion1},
+		{"ShouldNotStripPortal", "/v2/api/state", "/v2/api/state", APIVersion1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.SetRequestURI(tc.uri)
+
+			var (
+				uri     string
+				version int
+			)
+
+			StripAPIVersion(func(ctx *fasthttp.RequestCtx) {
+				uri = string(ctx.RequestURI())
+				version = (&AutheliaCtx{RequestCtx: ctx}).APIVersion()
+			})(ctx)
+
+			assert.Equal(t, tc.expected, uri)
+			assert.Equal(t, tc.version, version)
+
+			if tc.version == APIVersion2 {
+				assert.Equal(t, tc.uri, ctx.UserValue(UserValueKeyRawURI))
+			} else {
+				assert.Nil(t, ctx.UserValue(UserValueKeyRawURI))
+			}
+		})
+	}
+}
+
+func TestNewErrorCode(t *testing.T) {
+	testCases := []struct {
+		status   int
+		expected ErrorCode
+	}{
+		{fasthttp.StatusOK, ErrorCodeOperationFailed},
+		{fasthttp.StatusBadRequest, ErrorCodeInvalidRequest},
+		{fasthttp.StatusUnauthorized, ErrorCodeUnauthorized},
+		{fasthttp.StatusForbidden, ErrorCodeForbidden},
+		{fasthttp.StatusNotFound, ErrorCodeNotFound},
+		{fasthttp.StatusMethodNotAllowed, ErrorCodeInvalidRequest},
+		{fasthttp.StatusConflict, ErrorCodeConflict},
+		{fasthttp.StatusTooManyRequests, ErrorCodeTooManyRequests},
+		{fasthttp.StatusInternalServerError, ErrorCodeServerError},
+		{fasthttp.StatusBadGateway, ErrorCodeServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fasthttp.StatusMessage(tc.status), func(t *testing.T) {
+			assert.Equal(t, tc.expected, NewErrorCode(tc.status))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,13 +12,16 @@ import (
 
 	"github.com/asaskevich/govalidator"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/random"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -24,6 +29,15 @@ import (
 
 // NewRequestLogger create a new request logger for the given request.
 func NewRequestLogger(ctx *fasthttp.RequestCtx) (entry *logrus.Entry) {
+	return newRequestLogger(ctx, logging.Logger())
+}
+
+// NewRequestLoggerModule create a new request logger for the given request which uses the logger of the module.
+func NewRequestLoggerModule(ctx *fasthttp.RequestCtx, module string) (entry *logrus.Entry) {
+	return newRequestLogger(ctx, logging.LoggerModule(module))
+}
+
+func newRequestLogger(ctx *fasthttp.RequestCtx, logger *logrus.Logger) (entry *logrus.Entry) {
 	fields := logrus.Fields{
 		logging.FieldMethod:   string(ctx.Method()),
 		logging.FieldRemoteIP: RequestCtxRemoteIP(ctx).String(),
@@ -34,7 +48,7 @@ func NewRequestLogger(ctx *fasthttp.RequestCtx) (entry *logrus.Entry) {
 		fields[logging.FieldPathRaw] = uri
 	}
 
-	return logging.Logger().WithFields(fields)
+	return logger.WithFields(fields)
 }
 
 // NewAutheliaCtx instantiate an AutheliaCtx out of a RequestCtx.
@@ -51,7 +65,7 @@ func NewAutheliaCtx(requestCTX *fasthttp.RequestCtx, configuration schema.Config
 
 // AvailableSecondFactorMethods returns the available 2FA methods.
 func (ctx *AutheliaCtx) AvailableSecondFactorMethods() (methods []string) {
-	methods = make([]string, 0, 3)
+	methods = make([]string, 0, 5)
 
 	if !ctx.Configuration.TOTP.Disable {
 		methods = append(methods, model.SecondFactorMethodTOTP)
@@ -61,10 +75,18 @@ func (ctx *AutheliaCtx) AvailableSecondFactorMethods() (methods []string) {
 		methods = append(methods, model.SecondFactorMethodWebAuthn)
 	}
 
-	if !ctx.Configuration.DuoAPI.Disable {
+	if !ctx.Configuration.DuoAPI.Disable || !ctx.Configuration.Push.Disable {
 		methods = append(methods, model.SecondFactorMethodDuo)
 	}
 
+	if !ctx.Configuration.SMS.Disable {
+		methods = append(methods, model.SecondFactorMethodSMS)
+	}
+
+	if ctx.Configuration.Email.Enable {
+		methods = append(methods, model.SecondFactorMethodEmail)
+	}
+
 	return methods
 }
 
@@ -77,7 +99,27 @@ func (ctx *AutheliaCtx) Error(err error, message string) {
 
 // SetJSONError sets the body of the response to an JSON error KO message.
 func (ctx *AutheliaCtx) SetJSONError(message string) {
-	if err := ctx.ReplyJSON(ErrorResponse{Status: "KO", Message: message}, 0); err != nil {
+	if err := ctx.ReplyJSON(ErrorResponse{Status: "KO", Code: ctx.errorCode(), Message: message}, 0); err != nil {
+		ctx.Logger.Error(err)
+	}
+}
+
+// SetPasswordPolicyErrorJSON sets the body of the response to an JSON error KO message which includes the password
+// policy requirements which were not met if the error is a PasswordPolicyError.
+func (ctx *AutheliaCtx) SetPasswordPolicyErrorJSON(err error, message string) {
+	response := PasswordPolicyErrorResponse{Status: "KO", Code: ctx.errorCode(), Message: message, Requirements: []string{}}
+
+	var perr *PasswordPolicyError
+
+	if errors.As(err, &perr) {
+		response.Requirements = perr.Requirements
+
+		if response.Code != "" {
+			response.Code = ErrorCodePasswordPolicy
+		}
+	}
+
+	if err = ctx.ReplyJSON(response, 0); err != nil {
 		ctx.Logger.Error(err)
 	}
 }
@@ -88,14 +130,14 @@ func (ctx *AutheliaCtx) SetAuthenticationErrorJSON(status int, message string, a
 		ctx.SetStatusCode(status)
 	}
 
-	if err := ctx.ReplyJSON(AuthenticationErrorResponse{Status: "KO", Message: message, Authentication: authentication, Elevation: elevation}, 0); err != nil {
+	if err := ctx.ReplyJSON(AuthenticationErrorResponse{Status: "KO", Code: ctx.errorCode(), Message: message, Authentication: authentication, Elevation: elevation}, 0); err != nil {
 		ctx.Logger.Error(err)
 	}
 }
 
 // ReplyError reply with an error but does not display any stack trace in the logs.
 func (ctx *AutheliaCtx) ReplyError(err error, message string) {
-	b, marshalErr := json.Marshal(ErrorResponse{Status: "KO", Message: message})
+	b, marshalErr := json.Marshal(ErrorResponse{Status: "KO", Code: ctx.errorCode(), Message: message})
 
 	if marshalErr != nil {
 		ctx.Logger.Error(marshalErr)
@@ -236,6 +278,25 @@ func (ctx *AutheliaCtx) AuthzPath() (uri []byte) {
 	return nil
 }
 
+// APIVersion returns the version of the API the request was made to.
+func (ctx *AutheliaCtx) APIVersion() int {
+	if version, ok := ctx.UserValue(UserValueKeyAPIVersion).(int); ok {
+		return version
+	}
+
+	return APIVersion1
+}
+
+// errorCode returns the ErrorCode for the error response of the request given the status code of the response, or
+// an empty ErrorCode if the version of the API the request was made to doesn't include the error codes.
+func (ctx *AutheliaCtx) errorCode() ErrorCode {
+	if ctx.APIVersion() < APIVersion2 {
+		return ""
+	}
+
+	return NewErrorCode(ctx.Response.StatusCode())
+}
+
 // BasePath returns the base_url as per the path visited by the client.
 func (ctx *AutheliaCtx) BasePath() string {
 	if baseURL := ctx.UserValue(UserValueKeyBaseURL); baseURL != nil {
@@ -471,6 +532,11 @@ func (ctx *AutheliaCtx) SetContentTypeApplicationYAML() {
 	ctx.SetContentTypeBytes(contentTypeApplicationYAML)
 }
 
+// SetContentTypeApplicationSAMLMetadata efficiently sets the Content-Type header to 'application/samlmetadata+xml'.
+func (ctx *AutheliaCtx) SetContentTypeApplicationSAMLMetadata() {
+	ctx.SetContentTypeBytes(contentTypeApplicationSAML)
+}
+
 // SetContentSecurityPolicy sets the Content-Security-Policy header.
 func (ctx *AutheliaCtx) SetContentSecurityPolicy(value string) {
 	ctx.Response.Header.SetBytesK(headerContentSecurityPolicy, value)
@@ -583,6 +649,41 @@ func (ctx *AutheliaCtx) IsXHR() (xhr bool) {
 	return false
 }
 
+// IsWebSocket returns true if the request is a WebSocket upgrade request.
+func (ctx *AutheliaCtx) IsWebSocket() (websocket bool) {
+	if !bytes.EqualFold(ctx.Request.Header.PeekBytes(headerUpgrade), headerValueWebSocket) {
+		return false
+	}
+
+	for _, value := range bytes.Split(ctx.Request.Header.PeekBytes(headerConnection), []byte(",")) {
+		if bytes.EqualFold(bytes.TrimSpace(value), headerValueUpgrade) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsGRPC returns true if the request is a gRPC or gRPC-Web request.
+func (ctx *AutheliaCtx) IsGRPC() (grpc bool) {
+	contentType := ctx.Request.Header.ContentType()
+
+	if !bytes.HasPrefix(contentType, contentTypeApplicationGRPC) {
+		return false
+	}
+
+	if len(contentType) == len(contentTypeApplicationGRPC) {
+		return true
+	}
+
+	switch contentType[len(contentTypeApplicationGRPC)] {
+	case '+', ';', '-':
+		return true
+	default:
+		return false
+	}
+}
+
 // AcceptsMIME takes a mime type and returns true if the request accepts that type or the wildcard type.
 func (ctx *AutheliaCtx) AcceptsMIME(mime string) (acceptsMime bool) {
 	accepts := strings.Split(string(ctx.Request.Header.PeekBytes(headerAccept)), ",")
@@ -644,6 +745,119 @@ func (ctx *AutheliaCtx) RecordAuthn(success, regulated bool, method string) {
 	ctx.Providers.Metrics.RecordAuthn(success, regulated, method)
 }
 
+// RecordAuthnBan records the metrics of a ban issued by the regulator.
+func (ctx *AutheliaCtx) RecordAuthnBan(method string) {
+	if ctx.Providers.Metrics == nil {
+		return
+	}
+
+	ctx.Providers.Metrics.RecordAuthnBan(method)
+}
+
+// RecordAuthnAnomaly records the metrics of an anomaly detected by the regulator.
+func (ctx *AutheliaCtx) RecordAuthnAnomaly(pattern string) {
+	if ctx.Providers.Metrics == nil {
+		return
+	}
+
+	ctx.Providers.Metrics.RecordAuthnAnomaly(pattern)
+}
+
+// RecordPasswordReset records password reset metrics.
+func (ctx *AutheliaCtx) RecordPasswordReset(success bool) {
+	if ctx.Providers.Metrics == nil {
+		return
+	}
+
+	ctx.Providers.Metrics.RecordPasswordReset(success)
+}
+
+// RecordTOTPDrift records the TOTP clock drift metrics.
+func (ctx *AutheliaCtx) RecordTOTPDrift(offset int) {
+	if ctx.Providers.Metrics == nil {
+		return
+	}
+
+	ctx.Providers.Metrics.RecordTOTPDrift(offset)
+}
+
+// RecordAuthzDecision records authorization decision metrics.
+func (ctx *AutheliaCtx) RecordAuthzDecision(rule, policy, decision string) {
+	if ctx.Providers.Metrics == nil {
+		return
+	}
+
+	ctx.Providers.Metrics.RecordAuthzDecision(rule, policy, decision)
+}
+
+// AuditEvent emits an audit event of the given type for the current request.
+func (ctx *AutheliaCtx) AuditEvent(eventType, result, username string, details map[string]any) {
+	if ctx.Providers.Audit == nil {
+		return
+	}
+
+	ctx.Providers.Audit.Emit(audit.Event{
+		Time:   ctx.Clock.Now(),
+		Type:   eventType,
+		Result: result,
+		Actor: audit.EventActor{
+			Username:  username,
+			RemoteIP:  ctx.RemoteIP().String(),
+			UserAgent: string(ctx.UserAgent()),
+		},
+		Request: audit.EventRequest{
+			Method: string(ctx.Method()),
+			Host:   string(ctx.GetXForwardedHost()),
+			Path:   string(ctx.Path()),
+		},
+		TraceID: ctx.TraceID(),
+		Details: details,
+	})
+}
+
+// TraceID returns the trace id of the request. The trace id is taken from the W3C traceparent header, falling back to
+// the X-Request-Id header, and is otherwise generated. The trace id is the same for the lifetime of the request.
+func (ctx *AutheliaCtx) TraceID() (id string) {
+	return RequestCtxTraceID(ctx.RequestCtx)
+}
+
+// RequestCtxTraceID returns the trace id of the fasthttp.RequestCtx using the same logic as AutheliaCtx.TraceID, and is
+// intended for use in the Basic middlewares which don't have access to an AutheliaCtx.
+func RequestCtxTraceID(ctx *fasthttp.RequestCtx) (id string) {
+	var ok bool
+
+	if id, ok = ctx.UserValue(UserValueKeyTraceID).(string); ok {
+		return id
+	}
+
+	if id = requestCtxTraceParentTraceID(ctx); id == "" {
+		if requestID := ctx.Request.Header.PeekBytes(headerXRequestID); len(requestID) != 0 {
+			id = string(requestID)
+		} else if u, err := uuid.NewRandom(); err == nil {
+			id = strings.ReplaceAll(u.String(), "-", "")
+		}
+	}
+
+	ctx.SetUserValue(UserValueKeyTraceID, id)
+
+	return id
+}
+
+// requestCtxTraceParentTraceID returns the trace-id field of the W3C traceparent header if it's present and valid.
+func requestCtxTraceParentTraceID(ctx *fasthttp.RequestCtx) string {
+	parts := strings.Split(string(ctx.Request.Header.PeekBytes(headerTraceParent)), "-")
+
+	if len(parts) < 4 || len(parts[1]) != traceParentTraceIDLength || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(parts[1])
+}
+
 // GetClock returns the clock. For use with interface fulfillment.
 func (ctx *AutheliaCtx) GetClock() (clock clock.Provider) {
 	return ctx.Clock
@@ -659,6 +873,77 @@ func (ctx *AutheliaCtx) GetJWTWithTimeFuncOption() (option jwt.ParserOption) {
 	return jwt.WithTimeFunc(ctx.Clock.Now)
 }
 
+// GetIdentityVerificationJWTSigner returns the signing method, key id, and key used to sign the identity verification
+// JSON Web Tokens. The HMAC algorithms use the configured secret and all other algorithms use the configured OpenID
+// Connect 1.0 issuer key.
+func (ctx *AutheliaCtx) GetIdentityVerificationJWTSigner() (method jwt.SigningMethod, kid string, key any, err error) {
+	config := &ctx.Configuration.IdentityValidation.ResetPassword
+
+	switch method = jwt.GetSigningMethod(config.JWTAlgorithm); method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		var jwk *schema.JWK
+
+		if jwk = ctx.getIdentityVerificationJWK(config.JWTKeyID, method.Alg()); jwk == nil {
+			return nil, "", nil, fmt.Errorf("error finding the identity verification key with id '%s' and algorithm '%s': the key is not configured", config.JWTKeyID, method.Alg())
+		}
+
+		return method, jwk.KeyID, jwk.Key, nil
+	case *jwt.SigningMethodHMAC:
+		return method, "", []byte(config.JWTSecret), nil
+	default:
+		return jwt.SigningMethodHS256, "", []byte(config.JWTSecret), nil
+	}
+}
+
+// IdentityVerificationJWTKeyFunc is a jwt.Keyfunc which returns the key used to validate the identity verification
+// JSON Web Tokens. Tokens signed with a HMAC algorithm are validated with the configured secret and all other tokens
+// are validated with the public key of the configured OpenID Connect 1.0 issuer key with the key id from the token
+// header, which allows the tokens to be validated while the keys are rotated.
+func (ctx *AutheliaCtx) IdentityVerificationJWTKeyFunc(token *jwt.Token) (key any, err error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if len(ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret) == 0 {
+			return nil, fmt.Errorf("the token is signed with the '%s' algorithm but the secret is not configured", token.Method.Alg())
+		}
+
+		return []byte(ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		kid, _ := token.Header[oidc.JWTHeaderKeyIdentifier].(string)
+
+		if len(kid) == 0 {
+			return nil, fmt.Errorf("the token is signed with the '%s' algorithm but does not have a key id", token.Method.Alg())
+		}
+
+		var jwk *schema.JWK
+
+		if jwk = ctx.getIdentityVerificationJWK(kid, token.Method.Alg()); jwk == nil {
+			return nil, fmt.Errorf("the token is signed with the key with id '%s' and algorithm '%s' but the key is not configured", kid, token.Method.Alg())
+		}
+
+		if private, ok := jwk.Key.(schema.CryptographicPrivateKey); ok {
+			return private.Public(), nil
+		}
+
+		return jwk.Key, nil
+	default:
+		return nil, fmt.Errorf("the token is signed with the unsupported algorithm '%s'", token.Method.Alg())
+	}
+}
+
+func (ctx *AutheliaCtx) getIdentityVerificationJWK(kid, alg string) (jwk *schema.JWK) {
+	if ctx.Configuration.IdentityProviders.OIDC == nil {
+		return nil
+	}
+
+	for i, key := range ctx.Configuration.IdentityProviders.OIDC.JSONWebKeys {
+		if key.Algorithm == alg && (len(kid) == 0 || key.KeyID == kid) {
+			return &ctx.Configuration.IdentityProviders.OIDC.JSONWebKeys[i]
+		}
+	}
+
+	return nil
+}
+
 // GetConfiguration returns the current configuration.
 func (ctx *AutheliaCtx) GetConfiguration() (config schema.Configuration) {
 	return ctx.Configuration
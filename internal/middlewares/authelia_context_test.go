@@ -10,6 +10,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"go.uber.org/mock/gomock"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
@@ -49,6 +50,86 @@ func TestAutheliaCtx_RemoteIP(t *testing.T) {
 	}
 }
 
+func TestAutheliaCtx_TraceID(t *testing.T) {
+	testCases := []struct {
+		name        string
+		traceparent string
+		requestID   string
+		expected    string
+	}{
+		{"ShouldUseTraceParent", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", "abc", "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"ShouldUseRequestID", "", "abc", "abc"},
+		{"ShouldUseRequestIDOnInvalidTraceParent", "00-xyz-00f067aa0ba902b7-01", "abc", "abc"},
+		{"ShouldUseRequestIDOnZeroTraceParent", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "abc", "abc"},
+		{"ShouldGenerate", "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			if tc.traceparent != "" {
+				mock.Ctx.Request.Header.Set("traceparent", tc.traceparent)
+			}
+
+			if tc.requestID != "" {
+				mock.Ctx.Request.Header.Set("X-Request-Id", tc.requestID)
+			}
+
+			id := mock.Ctx.TraceID()
+
+			if tc.expected == "" {
+				assert.Regexp(t, "^[0-9a-f]{32}$", id)
+			} else {
+				assert.Equal(t, tc.expected, id)
+			}
+
+			assert.Equal(t, id, mock.Ctx.TraceID())
+		})
+	}
+}
+
+type testAuditProvider struct {
+	events []audit.Event
+}
+
+func (p *testAuditProvider) Emit(event audit.Event) {
+	p.events = append(p.events, event)
+}
+
+func TestAutheliaCtx_AuditEvent(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.AuditEvent(audit.EventTypeSessionLogout, audit.ResultSuccess, "john", nil)
+
+	provider := &testAuditProvider{}
+
+	mock.Ctx.Providers.Audit = provider
+
+	mock.Ctx.SetRemoteAddr(&net.TCPAddr{Port: 80, IP: net.ParseIP("127.0.0.127")})
+	mock.Ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	mock.Ctx.Request.SetRequestURI("/api/logout")
+	mock.Ctx.Request.Header.Set(fasthttp.HeaderXForwardedHost, "auth.example.com")
+	mock.Ctx.Request.Header.Set(fasthttp.HeaderUserAgent, "example")
+	mock.Ctx.Request.Header.Set("X-Request-Id", "abc")
+
+	mock.Ctx.AuditEvent(audit.EventTypeSessionLogout, audit.ResultSuccess, "john", map[string]any{"example": true})
+
+	require.Len(t, provider.events, 1)
+
+	assert.Equal(t, audit.Event{
+		Time:    mock.Clock.Now(),
+		Type:    audit.EventTypeSessionLogout,
+		Result:  audit.ResultSuccess,
+		Actor:   audit.EventActor{Username: "john", RemoteIP: "127.0.0.127", UserAgent: "example"},
+		Request: audit.EventRequest{Method: fasthttp.MethodPost, Host: "auth.example.com", Path: "/api/logout"},
+		TraceID: "abc",
+		Details: map[string]any{"example": true},
+	}, provider.events[0])
+}
+
 func TestContentTypes(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -182,6 +263,62 @@ func TestAutheliaCtx_AuthzPath(t *testing.T) {
 	}
 }
 
+func TestAutheliaCtx_SetJSONErrorAPIVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		version  any
+		status   int
+		expected string
+	}{
+		{
+			"ShouldNotIncludeCodeUnversioned",
+			nil,
+			fasthttp.StatusNotFound,
+			`{"status":"KO","message":"Operation failed."}`,
+		},
+		{
+			"ShouldIncludeCodeVersion2",
+			middlewares.APIVersion2,
+			fasthttp.StatusNotFound,
+			`{"status":"KO","code":"not_found","message":"Operation failed."}`,
+		},
+		{
+			"ShouldIncludeCodeVersion2StatusOK",
+			middlewares.APIVersion2,
+			fasthttp.StatusOK,
+			`{"status":"KO","code":"operation_failed","message":"Operation failed."}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			if tc.version != nil {
+				mock.Ctx.SetUserValue(middlewares.UserValueKeyAPIVersion, tc.version)
+			}
+
+			mock.Ctx.SetStatusCode(tc.status)
+			mock.Ctx.SetJSONError("Operation failed.")
+
+			assert.Equal(t, tc.expected, string(mock.Ctx.Response.Body()))
+		})
+	}
+}
+
+func TestAutheliaCtx_SetPasswordPolicyErrorJSONAPIVersion(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.SetUserValue(middlewares.UserValueKeyAPIVersion, middlewares.APIVersion2)
+
+	mock.Ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	mock.Ctx.SetPasswordPolicyErrorJSON(&middlewares.PasswordPolicyError{Requirements: []string{middlewares.PasswordPolicyRequirementMinLength}}, "Weak password.")
+
+	assert.Equal(t, `{"status":"KO","code":"password_policy","message":"Weak password.","requirements":["min_length"]}`, string(mock.Ctx.Response.Body()))
+}
+
 func TestAutheliaCtx_RootURLSlash(t *testing.T) {
 	testCases := []struct {
 		name              string
@@ -318,7 +455,7 @@ func TestShouldCallNextWithAutheliaCtx(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.Configuration{}
 	userProvider := mocks.NewMockUserProvider(ctrl)
-	sessionProvider := session.NewProvider(configuration.Session, nil)
+	sessionProvider := session.NewProvider(configuration.Session, nil, nil)
 	providers := middlewares.Providers{
 		UserProvider:    userProvider,
 		SessionProvider: sessionProvider,
@@ -423,11 +560,72 @@ func TestShouldDetectNonXHR(t *testing.T) {
 	assert.False(t, mock.Ctx.IsXHR())
 }
 
+func TestShouldDetectWebSocket(t *testing.T) {
+	testCases := []struct {
+		name       string
+		upgrade    string
+		connection string
+		expected   bool
+	}{
+		{"ShouldDetectStandard", "websocket", "Upgrade", true},
+		{"ShouldDetectMixedCase", "WebSocket", "keep-alive, Upgrade", true},
+		{"ShouldNotDetectWithoutConnection", "websocket", "", false},
+		{"ShouldNotDetectOtherUpgrade", "h2c", "Upgrade", false},
+		{"ShouldNotDetectEmpty", "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			if tc.upgrade != "" {
+				mock.Ctx.Request.Header.Set(fasthttp.HeaderUpgrade, tc.upgrade)
+			}
+
+			if tc.connection != "" {
+				mock.Ctx.Request.Header.Set(fasthttp.HeaderConnection, tc.connection)
+			}
+
+			assert.Equal(t, tc.expected, mock.Ctx.IsWebSocket())
+		})
+	}
+}
+
+func TestShouldDetectGRPC(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		expected    bool
+	}{
+		{"ShouldDetectStandard", "application/grpc", true},
+		{"ShouldDetectProto", "application/grpc+proto", true},
+		{"ShouldDetectWeb", "application/grpc-web", true},
+		{"ShouldDetectWebText", "application/grpc-web-text+proto", true},
+		{"ShouldNotDetectJSON", "application/json", false},
+		{"ShouldNotDetectSimilar", "application/grpcx", false},
+		{"ShouldNotDetectEmpty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mock.Ctx.Request.Header.SetContentType(tc.contentType)
+
+			assert.Equal(t, tc.expected, mock.Ctx.IsGRPC())
+		})
+	}
+}
+
 func TestShouldReturnCorrectSecondFactorMethods(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
 	mock.Ctx.Configuration.DuoAPI.Disable = true
+	mock.Ctx.Configuration.Push.Disable = true
+	mock.Ctx.Configuration.SMS.Disable = true
 
 	assert.Equal(t, []string{model.SecondFactorMethodTOTP, model.SecondFactorMethodWebAuthn}, mock.Ctx.AvailableSecondFactorMethods())
 
@@ -446,6 +644,22 @@ func TestShouldReturnCorrectSecondFactorMethods(t *testing.T) {
 	mock.Ctx.Configuration.DuoAPI.Disable = true
 
 	assert.Equal(t, []string{}, mock.Ctx.AvailableSecondFactorMethods())
+
+	mock.Ctx.Configuration.Push.Disable = false
+
+	assert.Equal(t, []string{model.SecondFactorMethodDuo}, mock.Ctx.AvailableSecondFactorMethods())
+
+	mock.Ctx.Configuration.Push.Disable = true
+
+	assert.Equal(t, []string{}, mock.Ctx.AvailableSecondFactorMethods())
+
+	mock.Ctx.Configuration.SMS.Disable = false
+
+	assert.Equal(t, []string{model.SecondFactorMethodSMS}, mock.Ctx.AvailableSecondFactorMethods())
+
+	mock.Ctx.Configuration.Email.Enable = true
+
+	assert.Equal(t, []string{model.SecondFactorMethodSMS, model.SecondFactorMethodEmail}, mock.Ctx.AvailableSecondFactorMethods())
 }
 
 func TestAutheliaCtx_QueryFuncs(t *testing.T) {
@@ -23,6 +23,11 @@ var (
 	headerXOriginalMethod  = []byte("X-Original-Method")
 	headerXForwardedMethod = []byte("X-Forwarded-Method")
 
+	headerXCSRFToken = []byte(HeaderXCSRFToken)
+
+	headerUpgrade    = []byte(fasthttp.HeaderUpgrade)
+	headerConnection = []byte(fasthttp.HeaderConnection)
+
 	headerVary   = []byte(fasthttp.HeaderVary)
 	headerOrigin = []byte(fasthttp.HeaderOrigin)
 
@@ -41,36 +46,35 @@ var (
 	headerCacheControl          = []byte(fasthttp.HeaderCacheControl)
 	headerContentSecurityPolicy = []byte(fasthttp.HeaderContentSecurityPolicy)
 
+	headerContentSecurityPolicyReportOnly = []byte(fasthttp.HeaderContentSecurityPolicyReportOnly)
+	headerStrictTransportSecurity         = []byte(fasthttp.HeaderStrictTransportSecurity)
+
 	headerPermissionsPolicy         = []byte("Permissions-Policy")
 	headerCrossOriginOpenerPolicy   = []byte("Cross-Origin-Opener-Policy")
 	headerCrossOriginEmbedderPolicy = []byte("Cross-Origin-Embedder-Policy")
 	headerCrossOriginResourcePolicy = []byte("Cross-Origin-Resource-Policy")
 	headerXDNSPrefetchControl       = []byte("X-DNS-Prefetch-Control")
+
+	headerTraceParent = []byte("traceparent")
+	headerXRequestID  = []byte("X-Request-Id")
 )
 
 var (
-	headerValueFalse           = []byte("false")
-	headerValueTrue            = []byte("true")
-	headerValueOff             = []byte("off")
-	headerValueMaxAge          = []byte("100")
-	headerValueVary            = []byte("Accept-Encoding, Origin")
-	headerValueVaryWildcard    = []byte("Accept-Encoding")
-	headerValueOriginWildcard  = []byte("*")
-	headerValueZero            = []byte("0")
-	headerValueCSPNone         = []byte("default-src 'none'")
-	headerValueCSPNoneFormPost = []byte("default-src 'none'; script-src 'sha256-skflBqA90WuHvoczvimLdj49ExKdizFjX2Itd6xKZdU='")
-
-	headerValueNoSniff                 = []byte("nosniff")
-	headerValueStrictOriginCrossOrigin = []byte("strict-origin-when-cross-origin")
-	headerValueDENY                    = []byte("DENY")
-	headerValueSameOrigin              = []byte("same-origin")
-	headerValueCrossOrigin             = []byte("cross-origin")
-	headerValueSameSite                = []byte("same-site")
-	headerValueUnsafeNone              = []byte("unsafe-none")
-	headerValueRequireCORP             = []byte("require-corp")
-	headerValueNoCache                 = []byte("no-cache")
-	headerValueNoStore                 = []byte("no-store")
-	headerValuePermissionsPolicy       = []byte("accelerometer=(), autoplay=(), camera=(), display-capture=(), geolocation=(), gyroscope=(), keyboard-map=(), magnetometer=(), microphone=(), midi=(), payment=(), picture-in-picture=(), screen-wake-lock=(), sync-xhr=(), xr-spatial-tracking=(), interest-cohort=()")
+	headerValueFalse          = []byte("false")
+	headerValueTrue           = []byte("true")
+	headerValueOff            = []byte("off")
+	headerValueMaxAge         = []byte("100")
+	headerValueVary           = []byte("Accept-Encoding, Origin")
+	headerValueVaryWildcard   = []byte("Accept-Encoding")
+	headerValueOriginWildcard = []byte("*")
+	headerValueZero           = []byte("0")
+	headerValueWebSocket      = []byte("websocket")
+	headerValueUpgrade        = []byte("upgrade")
+	headerValueCSPNone        = []byte("default-src 'none'")
+
+	headerValueNoSniff = []byte("nosniff")
+	headerValueNoCache = []byte("no-cache")
+	headerValueNoStore = []byte("no-store")
 )
 
 const (
@@ -78,6 +82,15 @@ const (
 	strProtoHTTP  = "http"
 	strSlash      = "/"
 
+	cspSourceNone           = "'none'"
+	cspDirectiveScriptSrc   = "script-src"
+	cspSourceFormPostScript = "'sha256-skflBqA90WuHvoczvimLdj49ExKdizFjX2Itd6xKZdU='"
+
+	scannerFilterReasonUserAgent = "user_agent"
+	scannerFilterReasonPath      = "path"
+
+	scannerFilterMaxTarpits = 100
+
 	queryArgRedirect    = "rd"
 	queryArgAutheliaURL = "authelia_url"
 	queryArgToken       = "token"
@@ -87,12 +100,68 @@ const (
 	UserValueKeyBaseURL int8 = iota
 	UserValueKeyOpenIDConnectResponseModeFormPost
 	UserValueKeyRawURI
+	UserValueKeyTraceID
+	UserValueKeyAPIVersion
 )
 
 const (
 	UserValueRouterKeyExtAuthzPath = "extauthz"
 )
 
+// The versions of the first-party API.
+const (
+	// APIVersion1 is the version of the API served at the unversioned /api/ prefix.
+	APIVersion1 = 1
+
+	// APIVersion2 is the version of the API served at the /api/v2/ prefix. It's identical to APIVersion1 except the
+	// error responses include an ErrorCode.
+	APIVersion2 = 2
+)
+
+const (
+	pathAPI              = "/api"
+	pathAPIVersion2      = "/api/v2"
+	pathAPIVersion2OIDC  = "/api/v2/oidc/"
+	pathAPIVersion2Authz = "/api/v2/authz/"
+)
+
+// The error codes returned in the error responses of APIVersion2. The doc comments of these constants are used by
+// authelia-gen to generate the descriptions in the OpenAPI specification and must start with the name of the
+// constant followed by 'is returned when'.
+const (
+	// ErrorCodeOperationFailed is returned when the operation failed for a reason not described by another code.
+	ErrorCodeOperationFailed ErrorCode = "operation_failed"
+
+	// ErrorCodeInvalidRequest is returned when the request was malformed or one of the parameters was invalid.
+	ErrorCodeInvalidRequest ErrorCode = "invalid_request"
+
+	// ErrorCodeUnauthorized is returned when the user isn't authenticated or failed to authenticate.
+	ErrorCodeUnauthorized ErrorCode = "unauthorized"
+
+	// ErrorCodeForbidden is returned when the user isn't authorized to perform the operation, for example when the
+	// session must be elevated first.
+	ErrorCodeForbidden ErrorCode = "forbidden"
+
+	// ErrorCodeNotFound is returned when the resource of the request doesn't exist.
+	ErrorCodeNotFound ErrorCode = "not_found"
+
+	// ErrorCodeConflict is returned when the operation conflicts with the current state of the resource.
+	ErrorCodeConflict ErrorCode = "conflict"
+
+	// ErrorCodePasswordPolicy is returned when the supplied password doesn't meet the password policy.
+	ErrorCodePasswordPolicy ErrorCode = "password_policy"
+
+	// ErrorCodeTooManyRequests is returned when the user has been banned by the regulation or is being rate limited.
+	ErrorCodeTooManyRequests ErrorCode = "too_many_requests"
+
+	// ErrorCodeServerError is returned when an unexpected error occurred on the server.
+	ErrorCodeServerError ErrorCode = "server_error"
+)
+
+const (
+	traceParentTraceIDLength = 32
+)
+
 var (
 	protoHTTPS = []byte(strProtoHTTPS)
 	protoHTTP  = []byte(strProtoHTTP)
@@ -106,22 +175,60 @@ var (
 	contentTypeTextHTML        = []byte("text/html; charset=utf-8")
 	contentTypeApplicationJSON = []byte("application/json; charset=utf-8")
 	contentTypeApplicationYAML = []byte("application/yaml; charset=utf-8")
+	contentTypeApplicationSAML = []byte("application/samlmetadata+xml")
+	contentTypeApplicationGRPC = []byte("application/grpc")
 )
 
 const (
 	headerValueXRequestedWithXHR = "XMLHttpRequest"
 )
 
+const (
+	// HeaderXCSRFToken is the header which contains the CSRF token of the session when the headless mode is enabled.
+	HeaderXCSRFToken = "X-CSRF-Token"
+)
+
 var okMessageBytes = []byte("{\"status\":\"OK\"}")
 
 const (
 	messageOperationFailed                      = "Operation failed"
+	messageCSRFTokenInvalid                     = "The CSRF token is missing or invalid"
 	messageIdentityVerificationTokenAlreadyUsed = "The identity verification token has already been used"
+	messageIdentityVerificationTokenBinding     = "The identity verification token must be used from the same browser and network it was requested from"
 	messageIdentityVerificationTokenHasExpired  = "The identity verification token has expired"
 	messageIdentityVerificationTokenNotValidYet = "The identity verification token is only valid in the future"
 	messageIdentityVerificationTokenSig         = "The identity verification token has an invalid signature"
 )
 
+const (
+	featureOpenIDConnect     = "openid_connect"
+	featureMetrics           = "metrics"
+	featurePasswordReset     = "password_reset"
+	featurePasswordPolicy    = "password_policy"
+	featurePrivacyPolicy     = "privacy_policy"
+	featureTOTP              = "totp"
+	featureWebAuthn          = "webauthn"
+	featureDuo               = "duo"
+	featureDuoSelfEnrollment = "duo_self_enrollment"
+	featurePush              = "push"
+	featureSPNEGO            = "spnego"
+	featurePasskeyLogin      = "passkey_login"
+)
+
 var protoHostSeparator = []byte("://")
 
 var errPasswordPolicyNoMet = errors.New("the supplied password does not met the security policy")
+
+// The password policy requirements reported by a PasswordPolicyError, which match the options of the password policy
+// configuration response.
+const (
+	PasswordPolicyRequirementMinLength   = "min_length"
+	PasswordPolicyRequirementMaxLength   = "max_length"
+	PasswordPolicyRequirementUppercase   = "require_uppercase"
+	PasswordPolicyRequirementLowercase   = "require_lowercase"
+	PasswordPolicyRequirementNumber      = "require_number"
+	PasswordPolicyRequirementSpecial     = "require_special"
+	PasswordPolicyRequirementMinScore    = "min_score"
+	PasswordPolicyRequirementMinEntropy  = "min_entropy"
+	PasswordPolicyRequirementBannedWords = "banned_words"
+)
@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RequireCSRFToken requires the requests which use an unsafe method include the CSRF token of the session in the
+// X-CSRF-Token header. It's used by the headless mode where the API may be used by frontends on other origins, so the
+// SameSite attribute of the session cookie can't be relied on to prevent cross-site request forgery.
+func RequireCSRFToken(next RequestHandler) RequestHandler {
+	return func(ctx *AutheliaCtx) {
+		if ctx.IsGet() || ctx.IsHead() || ctx.IsOptions() {
+			next(ctx)
+
+			return
+		}
+
+		userSession, err := ctx.GetSession()
+		if err != nil {
+			ctx.Logger.WithError(err).Error("Error occurred retrieving the user session to validate the CSRF token")
+
+			ctx.SetStatusCode(fasthttp.StatusForbidden)
+			ctx.SetJSONError(messageCSRFTokenInvalid)
+
+			return
+		}
+
+		token := ctx.Request.Header.PeekBytes(headerXCSRFToken)
+
+		if len(userSession.CSRFToken) == 0 || subtle.ConstantTimeCompare(token, []byte(userSession.CSRFToken)) != 1 {
+			ctx.Logger.Warnf("Request to '%s' was rejected as the CSRF token was missing or didn't match the CSRF token of the session", ctx.Path())
+
+			ctx.SetStatusCode(fasthttp.StatusForbidden)
+			ctx.SetJSONError(messageCSRFTokenInvalid)
+
+			return
+		}
+
+		next(ctx)
+	}
+}
@@ -0,0 +1,60 @@
+package middlewares_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestRequireCSRFToken(t *testing.T) {
+	testCases := []struct {
+		name     string
+		method   string
+		session  string
+		header   string
+		expected int
+	}{
+		{"ShouldAllowSafeMethodWithoutToken", fasthttp.MethodGet, "", "", fasthttp.StatusOK},
+		{"ShouldAllowHeadMethodWithoutToken", fasthttp.MethodHead, "abc123", "", fasthttp.StatusOK},
+		{"ShouldAllowUnsafeMethodWithMatchingToken", fasthttp.MethodPost, "abc123", "abc123", fasthttp.StatusOK},
+		{"ShouldRejectUnsafeMethodWithoutToken", fasthttp.MethodPost, "abc123", "", fasthttp.StatusForbidden},
+		{"ShouldRejectUnsafeMethodWithMismatchedToken", fasthttp.MethodDelete, "abc123", "abc124", fasthttp.StatusForbidden},
+		{"ShouldRejectUnsafeMethodWithoutSessionToken", fasthttp.MethodPut, "", "", fasthttp.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+
+			defer mock.Close()
+
+			userSession, err := mock.Ctx.GetSession()
+			require.NoError(t, err)
+
+			userSession.CSRFToken = tc.session
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.Ctx.Request.Header.SetMethod(tc.method)
+
+			if tc.header != "" {
+				mock.Ctx.Request.Header.Set(middlewares.HeaderXCSRFToken, tc.header)
+			}
+
+			middlewares.RequireCSRFToken(NilHandler)(mock.Ctx)
+
+			assert.Equal(t, tc.expected, mock.Ctx.Response.StatusCode())
+
+			if tc.expected == fasthttp.StatusOK {
+				assert.Equal(t, "Example Nil", string(mock.Ctx.Response.Body()))
+			} else {
+				assert.Equal(t, `{"status":"KO","message":"The CSRF token is missing or invalid"}`, string(mock.Ctx.Response.Body()))
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewFeatures returns the Features which are enabled by the provided configuration.
+func NewFeatures(config *schema.Configuration) (features Features) {
+	return Features{
+		OpenIDConnect:     config.IdentityProviders.OIDC != nil,
+		Metrics:           config.Telemetry.Metrics.Enabled || config.Telemetry.Metrics.StatsD.Enabled || config.Telemetry.Metrics.OTLP.Enabled,
+		PasswordReset:     !config.AuthenticationBackend.PasswordReset.Disable,
+		PasswordPolicy:    config.PasswordPolicy.Standard.Enabled || config.PasswordPolicy.ZXCVBN.Enabled,
+		PrivacyPolicy:     config.PrivacyPolicy.Enabled,
+		TOTP:              !config.TOTP.Disable,
+		WebAuthn:          !config.WebAuthn.Disable,
+		Duo:               !config.DuoAPI.Disable,
+		DuoSelfEnrollment: !config.DuoAPI.Disable && config.DuoAPI.EnableSelfEnrollment,
+		Push:              !config.Push.Disable,
+		SPNEGO:            config.AuthenticationBackend.SPNEGO.Enabled,
+		PasskeyLogin:      !config.WebAuthn.Disable && config.WebAuthn.PasskeyLogin.Enable,
+	}
+}
+
+// Features describes which of the optional subsystems are enabled so the frontend and automation can adapt to them.
+type Features struct {
+	OpenIDConnect     bool `json:"openid_connect"`
+	Metrics           bool `json:"metrics"`
+	PasswordReset     bool `json:"password_reset"`
+	PasswordPolicy    bool `json:"password_policy"`
+	PrivacyPolicy     bool `json:"privacy_policy"`
+	TOTP              bool `json:"totp"`
+	WebAuthn          bool `json:"webauthn"`
+	Duo               bool `json:"duo"`
+	DuoSelfEnrollment bool `json:"duo_self_enrollment"`
+	Push              bool `json:"push"`
+	SPNEGO            bool `json:"spnego"`
+	PasskeyLogin      bool `json:"passkey_login"`
+}
+
+// Names returns the names of the enabled features which are the same as the JSON property names.
+func (f Features) Names() (names []string) {
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{featureOpenIDConnect, f.OpenIDConnect},
+		{featureMetrics, f.Metrics},
+		{featurePasswordReset, f.PasswordReset},
+		{featurePasswordPolicy, f.PasswordPolicy},
+		{featurePrivacyPolicy, f.PrivacyPolicy},
+		{featureTOTP, f.TOTP},
+		{featureWebAuthn, f.WebAuthn},
+		{featureDuo, f.Duo},
+		{featureDuoSelfEnrollment, f.DuoSelfEnrollment},
+		{featurePush, f.Push},
+		{featureSPNEGO, f.SPNEGO},
+		{featurePasskeyLogin, f.PasskeyLogin},
+	}
+
+	names = make([]string, 0, len(features))
+
+	for _, feature := range features {
+		if feature.enabled {
+			names = append(names, feature.name)
+		}
+	}
+
+	return names
+}
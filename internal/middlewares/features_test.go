@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewFeatures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     *schema.Configuration
+		expected Features
+		names    []string
+	}{
+		{
+			"ShouldReturnDefaults",
+			&schema.Configuration{},
+			Features{PasswordReset: true, TOTP: true, WebAuthn: true, Duo: true, Push: true},
+			[]string{"password_reset", "totp", "webauthn", "duo", "push"},
+		},
+		{
+			"ShouldReturnNoneWhenDisabled",
+			&schema.Configuration{
+				AuthenticationBackend: schema.AuthenticationBackend{PasswordReset: schema.AuthenticationBackendPasswordReset{Disable: true}},
+				TOTP:                  schema.TOTP{Disable: true},
+				WebAuthn:              schema.WebAuthn{Disable: true, PasskeyLogin: schema.WebAuthnPasskeyLogin{Enable: true}},
+				DuoAPI:                schema.DuoAPI{Disable: true, EnableSelfEnrollment: true},
+				Push:                  schema.Push{Disable: true},
+			},
+			Features{},
+			[]string{},
+		},
+		{
+			"ShouldReturnAllWhenEnabled",
+			&schema.Configuration{
+				IdentityProviders:     schema.IdentityProviders{OIDC: &schema.IdentityProvidersOpenIDConnect{}},
+				Telemetry:             schema.Telemetry{Metrics: schema.TelemetryMetrics{Enabled: true}},
+				PasswordPolicy:        schema.PasswordPolicy{ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true}},
+				PrivacyPolicy:         schema.PrivacyPolicy{Enabled: true},
+				DuoAPI:                schema.DuoAPI{EnableSelfEnrollment: true},
+				AuthenticationBackend: schema.AuthenticationBackend{SPNEGO: schema.AuthenticationBackendSPNEGO{Enabled: true}},
+				WebAuthn:              schema.WebAuthn{PasskeyLogin: schema.WebAuthnPasskeyLogin{Enable: true}},
+			},
+			Features{OpenIDConnect: true, Metrics: true, PasswordReset: true, PasswordPolicy: true, PrivacyPolicy: true, TOTP: true, WebAuthn: true, Duo: true, DuoSelfEnrollment: true, Push: true, SPNEGO: true, PasskeyLogin: true},
+			[]string{"openid_connect", "metrics", "password_reset", "password_policy", "privacy_policy", "totp", "webauthn", "duo", "duo_self_enrollment", "push", "spnego", "passkey_login"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := NewFeatures(tc.have)
+
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.names, actual.Names())
+		})
+	}
+}
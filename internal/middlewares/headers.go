@@ -1,85 +1,258 @@
 package middlewares
 
 import (
+	"bytes"
+	"strconv"
+	"strings"
+
 	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
-// The SecurityHeaders middleware adds several modern recommended security headers with safe values.
-func SecurityHeaders(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	return func(ctx *fasthttp.RequestCtx) {
-		SetStandardSecurityHeaders(ctx)
+// NewSecurityHeaderPolicies returns the SecurityHeaderPolicies for each endpoint group given a schema.ServerHeaders.
+func NewSecurityHeaderPolicies(config schema.ServerHeaders) (policies *SecurityHeaderPolicies) {
+	return &SecurityHeaderPolicies{
+		Portal:        NewSecurityHeadersPolicy(config, config.Groups.Portal),
+		API:           NewSecurityHeadersPolicy(config, config.Groups.API),
+		OpenIDConnect: NewSecurityHeadersPolicy(config, config.Groups.OpenIDConnect),
+		OpenAPI:       NewSecurityHeadersPolicy(config, config.Groups.OpenAPI),
+	}
+}
 
-		next(ctx)
+// SecurityHeaderPolicies is a collection of SecurityHeadersPolicy for each endpoint group.
+type SecurityHeaderPolicies struct {
+	Portal        *SecurityHeadersPolicy
+	API           *SecurityHeadersPolicy
+	OpenIDConnect *SecurityHeadersPolicy
+	OpenAPI       *SecurityHeadersPolicy
+}
+
+// NewSecurityHeadersPolicy returns a new SecurityHeadersPolicy given the server wide headers configuration and the
+// configuration of a specific endpoint group. Any header with an empty value is not sent.
+func NewSecurityHeadersPolicy(config schema.ServerHeaders, group schema.ServerHeadersGroup) (policy *SecurityHeadersPolicy) {
+	policy = &SecurityHeadersPolicy{
+		csp:       group.ContentSecurityPolicy,
+		cspHeader: headerContentSecurityPolicy,
 	}
+
+	if group.ContentSecurityPolicyReportOnly {
+		policy.cspHeader = headerContentSecurityPolicyReportOnly
+	}
+
+	policy.headers = append(policy.headers,
+		securityHeader{headerXContentTypeOptions, headerValueNoSniff},
+		securityHeader{headerXDNSPrefetchControl, headerValueOff},
+	)
+
+	for _, header := range []struct {
+		key   []byte
+		value string
+	}{
+		{headerReferrerPolicy, config.ReferrerPolicy},
+		{headerPermissionsPolicy, config.PermissionsPolicy},
+		{headerXFrameOptions, config.FrameOptions},
+		{headerCrossOriginOpenerPolicy, group.CrossOriginOpenerPolicy},
+		{headerCrossOriginEmbedderPolicy, group.CrossOriginEmbedderPolicy},
+		{headerCrossOriginResourcePolicy, group.CrossOriginResourcePolicy},
+	} {
+		if header.value == "" {
+			continue
+		}
+
+		policy.headers = append(policy.headers, securityHeader{header.key, []byte(header.value)})
+	}
+
+	if config.StrictTransportSecurity.MaxAge > 0 {
+		policy.hsts = newStrictTransportSecurityValue(config.StrictTransportSecurity)
+	}
+
+	return policy
+}
+
+// SecurityHeadersPolicy is a middleware which applies a set of configured security headers to responses.
+type SecurityHeadersPolicy struct {
+	headers   []securityHeader
+	hsts      []byte
+	csp       schema.CSPTemplate
+	cspHeader []byte
+}
+
+type securityHeader struct {
+	key, value []byte
 }
 
-// The SecurityHeadersRelaxed middleware adds several modern recommended security headers with relaxed values.
-func SecurityHeadersRelaxed(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+// Middleware sets the security headers and the Content-Security-Policy header if it does not require a nonce.
+func (p *SecurityHeadersPolicy) Middleware(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
-		SetRelaxedSecurityHeaders(ctx)
+		p.SetSecurityHeaders(ctx)
+
+		if !p.csp.HasNonce() {
+			p.SetContentSecurityPolicy(ctx, "")
+		}
 
 		next(ctx)
 	}
 }
 
-// The SecurityHeadersBase middleware adds several modern recommended security headers with relaxed values.
-func SecurityHeadersBase(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+// MiddlewareOpenIDConnect is similar to Middleware except it adjusts the Content-Security-Policy so the script used
+// by the form_post response mode is permitted when the handler indicates it's rendering that response.
+func (p *SecurityHeadersPolicy) MiddlewareOpenIDConnect(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	csp := p.csp
+
+	if csp == "" {
+		csp = schema.CSPTemplate(headerValueCSPNone)
+	}
+
+	formPost := []byte(ParseContentSecurityPolicy(string(csp)).WithDirective(cspDirectiveScriptSrc, cspSourceFormPostScript).Build())
+
 	return func(ctx *fasthttp.RequestCtx) {
-		SetBaseSecurityHeaders(ctx)
+		ctx.SetUserValue(UserValueKeyOpenIDConnectResponseModeFormPost, false)
+
+		p.SetSecurityHeaders(ctx)
 
 		next(ctx)
+
+		if modeFormPost, ok := ctx.UserValue(UserValueKeyOpenIDConnectResponseModeFormPost).(bool); ok && modeFormPost {
+			ctx.Response.Header.SetCanonical(p.cspHeader, formPost)
+		} else {
+			ctx.Response.Header.SetCanonical(p.cspHeader, []byte(csp))
+		}
 	}
 }
 
-// The SetStandardSecurityHeaders function adds several modern recommended security headers with safe values.
-func SetStandardSecurityHeaders(ctx *fasthttp.RequestCtx) {
-	SetBaseSecurityHeaders(ctx)
+// SetSecurityHeaders sets all of the security headers except the Content-Security-Policy header.
+func (p *SecurityHeadersPolicy) SetSecurityHeaders(ctx *fasthttp.RequestCtx) {
+	for _, header := range p.headers {
+		ctx.Response.Header.SetBytesKV(header.key, header.value)
+	}
+
+	if p.hsts != nil && isSecureRequest(ctx) {
+		ctx.Response.Header.SetBytesKV(headerStrictTransportSecurity, p.hsts)
+	}
+}
 
-	ctx.Response.Header.SetBytesKV(headerCrossOriginOpenerPolicy, headerValueSameOrigin)
-	ctx.Response.Header.SetBytesKV(headerCrossOriginEmbedderPolicy, headerValueRequireCORP)
-	ctx.Response.Header.SetBytesKV(headerCrossOriginResourcePolicy, headerValueSameSite)
+// HasContentSecurityPolicy returns true if this policy has a configured Content-Security-Policy.
+func (p *SecurityHeadersPolicy) HasContentSecurityPolicy() bool {
+	return p.csp != ""
 }
 
-// The SetRelaxedSecurityHeaders function adds several modern recommended security headers with relaxed values.
-func SetRelaxedSecurityHeaders(ctx *fasthttp.RequestCtx) {
-	SetBaseSecurityHeaders(ctx)
+// SetContentSecurityPolicy sets the configured Content-Security-Policy header replacing the nonce placeholder with
+// the provided nonce. Returns false if there is no configured Content-Security-Policy.
+func (p *SecurityHeadersPolicy) SetContentSecurityPolicy(ctx *fasthttp.RequestCtx, nonce string) bool {
+	if p.csp == "" {
+		return false
+	}
+
+	ctx.Response.Header.SetCanonical(p.cspHeader, []byte(p.csp.WithNonce(nonce)))
 
-	ctx.Response.Header.SetBytesKV(headerCrossOriginOpenerPolicy, headerValueSameOrigin)
-	ctx.Response.Header.SetBytesKV(headerCrossOriginEmbedderPolicy, headerValueUnsafeNone)
-	ctx.Response.Header.SetBytesKV(headerCrossOriginResourcePolicy, headerValueCrossOrigin)
+	return true
 }
 
-func SetBaseSecurityHeaders(ctx *fasthttp.RequestCtx) {
-	ctx.Response.Header.SetBytesKV(headerXContentTypeOptions, headerValueNoSniff)
-	ctx.Response.Header.SetBytesKV(headerReferrerPolicy, headerValueStrictOriginCrossOrigin)
-	ctx.Response.Header.SetBytesKV(headerPermissionsPolicy, headerValuePermissionsPolicy)
-	ctx.Response.Header.SetBytesKV(headerXFrameOptions, headerValueDENY)
-	ctx.Response.Header.SetBytesKV(headerXDNSPrefetchControl, headerValueOff)
+// ContentSecurityPolicy returns the configured Content-Security-Policy template.
+func (p *SecurityHeadersPolicy) ContentSecurityPolicy() schema.CSPTemplate {
+	return p.csp
 }
 
-// SecurityHeadersCSPNone middleware adds the Content-Security-Policy header with the value "default-src 'none';".
-func SecurityHeadersCSPNone(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	return func(ctx *fasthttp.RequestCtx) {
-		ctx.Response.Header.SetBytesKV(headerContentSecurityPolicy, headerValueCSPNone)
+func newStrictTransportSecurityValue(config schema.ServerHeadersStrictTransportSecurity) []byte {
+	buf := bytes.NewBufferString("max-age=")
 
-		next(ctx)
+	buf.WriteString(strconv.Itoa(int(config.MaxAge.Seconds())))
+
+	if config.IncludeSubdomains {
+		buf.WriteString("; includeSubDomains")
+	}
+
+	if config.Preload {
+		buf.WriteString("; preload")
 	}
+
+	return buf.Bytes()
 }
 
-// SecurityHeadersCSPNoneOpenIDConnect middleware adds the Content-Security-Policy header with the value
-// "default-src 'none'" except in special circumstances.
-func SecurityHeadersCSPNoneOpenIDConnect(next fasthttp.RequestHandler) fasthttp.RequestHandler {
-	return func(ctx *fasthttp.RequestCtx) {
-		ctx.SetUserValue(UserValueKeyOpenIDConnectResponseModeFormPost, false)
+func isSecureRequest(ctx *fasthttp.RequestCtx) bool {
+	return ctx.IsTLS() || bytes.Equal(ctx.Request.Header.PeekBytes(headerXForwardedProto), protoHTTPS)
+}
 
-		next(ctx)
+// NewContentSecurityPolicyBuilder returns a new ContentSecurityPolicyBuilder.
+func NewContentSecurityPolicyBuilder() (builder *ContentSecurityPolicyBuilder) {
+	return &ContentSecurityPolicyBuilder{}
+}
 
-		if modeFormPost, ok := ctx.UserValue(UserValueKeyOpenIDConnectResponseModeFormPost).(bool); ok && modeFormPost {
-			ctx.Response.Header.SetBytesKV(headerContentSecurityPolicy, headerValueCSPNoneFormPost)
-		} else {
-			ctx.Response.Header.SetBytesKV(headerContentSecurityPolicy, headerValueCSPNone)
+// ParseContentSecurityPolicy parses an existing Content-Security-Policy value into a ContentSecurityPolicyBuilder.
+func ParseContentSecurityPolicy(value string) (builder *ContentSecurityPolicyBuilder) {
+	builder = NewContentSecurityPolicyBuilder()
+
+	for _, directive := range strings.Split(value, ";") {
+		fields := strings.Fields(directive)
+
+		if len(fields) == 0 {
+			continue
 		}
+
+		builder.WithDirective(fields[0], fields[1:]...)
 	}
+
+	return builder
+}
+
+// ContentSecurityPolicyBuilder is used to build Content-Security-Policy header values.
+type ContentSecurityPolicyBuilder struct {
+	directives []cspDirective
+}
+
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+// WithDirective appends the sources to the named directive, adding the directive if it doesn't exist. If the
+// directive only has the 'none' source it's replaced by the provided sources.
+func (b *ContentSecurityPolicyBuilder) WithDirective(name string, sources ...string) *ContentSecurityPolicyBuilder {
+	name = strings.ToLower(name)
+
+	for i, directive := range b.directives {
+		if directive.name != name {
+			continue
+		}
+
+		if len(sources) != 0 && len(directive.sources) == 1 && directive.sources[0] == cspSourceNone {
+			b.directives[i].sources = nil
+		}
+
+		b.directives[i].sources = append(b.directives[i].sources, sources...)
+
+		return b
+	}
+
+	b.directives = append(b.directives, cspDirective{name: name, sources: sources})
+
+	return b
+}
+
+// WithNonce appends the nonce placeholder source to the named directive.
+func (b *ContentSecurityPolicyBuilder) WithNonce(name string) *ContentSecurityPolicyBuilder {
+	return b.WithDirective(name, "'nonce-"+schema.CSPTemplateNoncePlaceholder+"'")
+}
+
+// Build returns the Content-Security-Policy template.
+func (b *ContentSecurityPolicyBuilder) Build() schema.CSPTemplate {
+	buf := &strings.Builder{}
+
+	for i, directive := range b.directives {
+		if i != 0 {
+			buf.WriteString("; ")
+		}
+
+		buf.WriteString(directive.name)
+
+		for _, source := range directive.sources {
+			buf.WriteRune(' ')
+			buf.WriteString(source)
+		}
+	}
+
+	return schema.CSPTemplate(buf.String())
 }
 
 // SecurityHeadersNoStore middleware adds the Pragma no-cache and Cache-Control no-store headers.
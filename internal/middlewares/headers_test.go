@@ -0,0 +1,174 @@
+package middlewares
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestSecurityHeadersPolicy_Middleware(t *testing.T) {
+	config := schema.DefaultServerConfiguration.Headers
+
+	policies := NewSecurityHeaderPolicies(config)
+
+	ctx := &fasthttp.RequestCtx{}
+
+	policies.API.Middleware(func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "nosniff", string(ctx.Response.Header.Peek(fasthttp.HeaderXContentTypeOptions)))
+	assert.Equal(t, "off", string(ctx.Response.Header.Peek("X-DNS-Prefetch-Control")))
+	assert.Equal(t, "strict-origin-when-cross-origin", string(ctx.Response.Header.Peek(fasthttp.HeaderReferrerPolicy)))
+	assert.Equal(t, config.PermissionsPolicy, string(ctx.Response.Header.Peek("Permissions-Policy")))
+	assert.Equal(t, "DENY", string(ctx.Response.Header.Peek(fasthttp.HeaderXFrameOptions)))
+	assert.Equal(t, "default-src 'none'", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+	assert.Equal(t, "", string(ctx.Response.Header.Peek("Cross-Origin-Opener-Policy")))
+	assert.Equal(t, "", string(ctx.Response.Header.Peek(fasthttp.HeaderStrictTransportSecurity)))
+
+	ctx = &fasthttp.RequestCtx{}
+
+	policies.OpenAPI.Middleware(func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "same-origin", string(ctx.Response.Header.Peek("Cross-Origin-Opener-Policy")))
+	assert.Equal(t, "unsafe-none", string(ctx.Response.Header.Peek("Cross-Origin-Embedder-Policy")))
+	assert.Equal(t, "cross-origin", string(ctx.Response.Header.Peek("Cross-Origin-Resource-Policy")))
+	assert.Equal(t, "", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+}
+
+func TestSecurityHeadersPolicy_StrictTransportSecurity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ServerHeadersStrictTransportSecurity
+		proto    string
+		expected string
+	}{
+		{
+			"ShouldNotSetWhenDisabled",
+			schema.ServerHeadersStrictTransportSecurity{},
+			"https",
+			"",
+		},
+		{
+			"ShouldNotSetWhenInsecure",
+			schema.ServerHeadersStrictTransportSecurity{MaxAge: time.Hour},
+			"http",
+			"",
+		},
+		{
+			"ShouldSetMaxAge",
+			schema.ServerHeadersStrictTransportSecurity{MaxAge: time.Hour},
+			"https",
+			"max-age=3600",
+		},
+		{
+			"ShouldSetAllDirectives",
+			schema.ServerHeadersStrictTransportSecurity{MaxAge: time.Hour * 24 * 365, IncludeSubdomains: true, Preload: true},
+			"https",
+			"max-age=31536000; includeSubDomains; preload",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy := NewSecurityHeadersPolicy(schema.ServerHeaders{StrictTransportSecurity: tc.have}, schema.ServerHeadersGroup{})
+
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.Header.Set(fasthttp.HeaderXForwardedProto, tc.proto)
+
+			policy.SetSecurityHeaders(ctx)
+
+			assert.Equal(t, tc.expected, string(ctx.Response.Header.Peek(fasthttp.HeaderStrictTransportSecurity)))
+		})
+	}
+}
+
+func TestSecurityHeadersPolicy_ContentSecurityPolicyNonce(t *testing.T) {
+	policy := NewSecurityHeadersPolicy(schema.ServerHeaders{}, schema.ServerHeadersGroup{ContentSecurityPolicy: "default-src 'self'; style-src 'nonce-${NONCE}'"})
+
+	ctx := &fasthttp.RequestCtx{}
+
+	policy.Middleware(func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+
+	assert.True(t, policy.HasContentSecurityPolicy())
+	assert.True(t, policy.SetContentSecurityPolicy(ctx, "abc"))
+
+	assert.Equal(t, "default-src 'self'; style-src 'nonce-abc'", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+
+	policy = NewSecurityHeadersPolicy(schema.ServerHeaders{}, schema.ServerHeadersGroup{ContentSecurityPolicy: "default-src 'self'", ContentSecurityPolicyReportOnly: true})
+
+	ctx = &fasthttp.RequestCtx{}
+
+	policy.Middleware(func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+	assert.Equal(t, "default-src 'self'", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicyReportOnly)))
+
+	policy = NewSecurityHeadersPolicy(schema.ServerHeaders{}, schema.ServerHeadersGroup{})
+
+	assert.False(t, policy.HasContentSecurityPolicy())
+	assert.False(t, policy.SetContentSecurityPolicy(ctx, "abc"))
+}
+
+func TestSecurityHeadersPolicy_MiddlewareOpenIDConnect(t *testing.T) {
+	policy := NewSecurityHeadersPolicy(schema.ServerHeaders{}, schema.ServerHeadersGroup{ContentSecurityPolicy: "default-src 'none'"})
+
+	ctx := &fasthttp.RequestCtx{}
+
+	policy.MiddlewareOpenIDConnect(func(ctx *fasthttp.RequestCtx) {})(ctx)
+
+	assert.Equal(t, "default-src 'none'", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+
+	ctx = &fasthttp.RequestCtx{}
+
+	policy.MiddlewareOpenIDConnect(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetUserValue(UserValueKeyOpenIDConnectResponseModeFormPost, true)
+	})(ctx)
+
+	assert.Equal(t, "default-src 'none'; script-src 'sha256-skflBqA90WuHvoczvimLdj49ExKdizFjX2Itd6xKZdU='", string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy)))
+}
+
+func TestContentSecurityPolicyBuilder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     *ContentSecurityPolicyBuilder
+		expected schema.CSPTemplate
+	}{
+		{
+			"ShouldBuildEmpty",
+			NewContentSecurityPolicyBuilder(),
+			"",
+		},
+		{
+			"ShouldBuildDirectives",
+			NewContentSecurityPolicyBuilder().WithDirective("default-src", "'self'").WithDirective("frame-ancestors", "'none'"),
+			"default-src 'self'; frame-ancestors 'none'",
+		},
+		{
+			"ShouldAppendSources",
+			NewContentSecurityPolicyBuilder().WithDirective("default-src", "'self'").WithDirective("DEFAULT-SRC", "https://example.com"),
+			"default-src 'self' https://example.com",
+		},
+		{
+			"ShouldReplaceNone",
+			ParseContentSecurityPolicy("default-src 'none'; script-src 'none'").WithDirective("script-src", "'self'"),
+			"default-src 'none'; script-src 'self'",
+		},
+		{
+			"ShouldAddNonce",
+			ParseContentSecurityPolicy("default-src 'self';; ").WithNonce("style-src"),
+			"default-src 'self'; style-src 'nonce-${NONCE}'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.have.Build())
+		})
+	}
+}
@@ -11,6 +11,9 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/templates"
 )
 
@@ -37,6 +40,31 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 			return
 		}
 
+		if !ctx.Configuration.IdentityValidation.EmailDomains.IsPermitted(identity.Email) {
+			// In that case we reply ok to avoid user enumeration.
+			ctx.Logger.Errorf("Error occurred issuing an identity verification token for user '%s': the domain of the email address '%s' is not permitted to receive identity verification notifications", identity.Username, identity.Email)
+			ctx.ReplyOK()
+
+			return
+		}
+
+		if limit := ctx.Configuration.IdentityValidation.ResetPassword.IssuanceRateLimit; !limit.Disable && limit.Requests > 0 {
+			var count int
+
+			if count, err = ctx.Providers.StorageProvider.CountIdentityVerifications(ctx, identity.Username, args.ActionClaim, requestTime.Add(-limit.Period)); err != nil {
+				ctx.Error(err, messageOperationFailed)
+				return
+			}
+
+			if count >= limit.Requests {
+				// In that case we reply ok to avoid user enumeration.
+				ctx.Logger.Errorf("Error occurred issuing an identity verification token for user '%s': the user has already been issued %d tokens within the last %s which is the maximum allowed", identity.Username, count, limit.Period)
+				ctx.ReplyOK()
+
+				return
+			}
+		}
+
 		var jti uuid.UUID
 
 		if jti, err = uuid.NewRandom(); err != nil {
@@ -49,22 +77,19 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 		// Create the claim with the action to sign it.
 		claims := verification.ToIdentityVerificationClaim()
 
-		var method *jwt.SigningMethodHMAC
-
-		switch ctx.Configuration.IdentityValidation.ResetPassword.JWTAlgorithm {
-		case "HS256":
-			method = jwt.SigningMethodHS256
-		case "HS384":
-			method = jwt.SigningMethodHS384
-		case "HS512":
-			method = jwt.SigningMethodHS512
-		default:
-			method = jwt.SigningMethodHS256
+		method, kid, key, err := ctx.GetIdentityVerificationJWTSigner()
+		if err != nil {
+			ctx.Error(err, messageOperationFailed)
+			return
 		}
 
 		token := jwt.NewWithClaims(method, claims)
 
-		signedToken, err := token.SignedString([]byte(ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret))
+		if kid != "" {
+			token.Header[oidc.JWTHeaderKeyIdentifier] = kid
+		}
+
+		signedToken, err := token.SignedString(key)
 		if err != nil {
 			ctx.Error(err, messageOperationFailed)
 			return
@@ -75,6 +100,22 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 			return
 		}
 
+		if ctx.Configuration.IdentityValidation.ResetPassword.BindSession {
+			var userSession session.UserSession
+
+			if userSession, err = ctx.GetSession(); err != nil {
+				ctx.Error(err, messageOperationFailed)
+				return
+			}
+
+			userSession.IdentityVerificationJTI = verification.JTI.String()
+
+			if err = ctx.SaveSession(userSession); err != nil {
+				ctx.Error(err, messageOperationFailed)
+				return
+			}
+		}
+
 		linkURL := ctx.RootURL()
 
 		query := linkURL.Query()
@@ -139,9 +180,7 @@ func IdentityVerificationFinish(args IdentityVerificationFinishArgs, next func(c
 		}
 
 		token, err := jwt.ParseWithClaims(finishBody.Token, &model.IdentityVerificationClaim{},
-			func(token *jwt.Token) (any, error) {
-				return []byte(ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret), nil
-			},
+			ctx.IdentityVerificationJWTKeyFunc,
 			jwt.WithIssuedAt(),
 			jwt.WithIssuer("Authelia"),
 			jwt.WithStrictDecoding(),
@@ -224,7 +263,22 @@ func IdentityVerificationFinish(args IdentityVerificationFinishArgs, next func(c
 			return
 		}
 
-		if err = ctx.Providers.StorageProvider.ConsumeIdentityVerification(ctx, claims.ID, model.NewNullIP(ctx.RemoteIP())); err != nil {
+		if err = identityVerificationFinishCheckBinding(ctx, claims); err != nil {
+			ctx.Logger.WithError(err).Error("Error occurred handling the identity verification token, the token is bound to another client")
+			ctx.SetJSONError(messageIdentityVerificationTokenBinding)
+
+			return
+		}
+
+		switch err = ctx.Providers.StorageProvider.ConsumeIdentityVerification(ctx, claims.ID, model.NewNullIP(ctx.RemoteIP())); {
+		case err == nil:
+			break
+		case errors.Is(err, storage.ErrIdentityVerificationNotConsumable):
+			ctx.Logger.WithError(err).Error("Error occurred consuming the identity verification during the validation phase, the token was consumed or revoked by another request")
+			ctx.SetJSONError(messageIdentityVerificationTokenAlreadyUsed)
+
+			return
+		default:
 			ctx.Logger.WithError(err).Error("Error occurred consuming the identity verification during the validation phase")
 			ctx.SetJSONError(messageOperationFailed)
 
@@ -234,3 +288,35 @@ func IdentityVerificationFinish(args IdentityVerificationFinishArgs, next func(c
 		next(ctx, claims.Username)
 	}
 }
+
+// identityVerificationFinishCheckBinding checks the identity verification is used from the same session and remote IP
+// which it was issued to when the tokens are bound to them.
+func identityVerificationFinishCheckBinding(ctx *AutheliaCtx, claims *model.IdentityVerificationClaim) (err error) {
+	config := ctx.Configuration.IdentityValidation.ResetPassword
+
+	if config.BindSession {
+		var userSession session.UserSession
+
+		if userSession, err = ctx.GetSession(); err != nil {
+			return fmt.Errorf("error retrieving the user session: %w", err)
+		}
+
+		if userSession.IdentityVerificationJTI != claims.ID {
+			return fmt.Errorf("the token was not issued to this session")
+		}
+	}
+
+	if config.BindRemoteIP {
+		var verification *model.IdentityVerification
+
+		if verification, err = ctx.Providers.StorageProvider.LoadIdentityVerification(ctx, claims.ID); err != nil {
+			return err
+		}
+
+		if !verification.IssuedIP.IP.Equal(ctx.RemoteIP()) {
+			return fmt.Errorf("the token was issued to the remote ip '%s' but it was used from the remote ip '%s'", verification.IssuedIP.IP, ctx.RemoteIP())
+		}
+	}
+
+	return nil
+}
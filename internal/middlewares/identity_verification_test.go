@@ -1,7 +1,11 @@
 package middlewares_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
+	"net"
 	"net/mail"
 	"testing"
 	"time"
@@ -13,10 +17,12 @@ import (
 	"github.com/valyala/fasthttp"
 	"go.uber.org/mock/gomock"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 const testJWTSecret = "abc"
@@ -115,6 +121,77 @@ func TestShouldSucceedIdentityVerificationStartProcess(t *testing.T) {
 	defer mock.Close()
 }
 
+func TestShouldNotSendAnEmailWhenIssuanceRateLimitIsExceeded(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret = testJWTSecret
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.IssuanceRateLimit.Requests = 3
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.IssuanceRateLimit.Period = time.Hour
+
+	mock.StorageMock.EXPECT().
+		CountIdentityVerifications(mock.Ctx, gomock.Eq("john"), gomock.Eq("Claim"), gomock.Any()).
+		Return(3, nil)
+
+	middlewares.IdentityVerificationStart(newArgs(defaultRetriever), nil)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "Error occurred issuing an identity verification token for user 'john': the user has already been issued 3 tokens within the last 1h0m0s which is the maximum allowed", mock.Hook.LastEntry().Message)
+}
+
+func TestShouldNotSendAnEmailWhenEmailDomainIsNotPermitted(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret = testJWTSecret
+	mock.Ctx.Configuration.IdentityValidation.EmailDomains.Allow = []string{"example.org"}
+
+	middlewares.IdentityVerificationStart(newArgs(defaultRetriever), nil)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "Error occurred issuing an identity verification token for user 'john': the domain of the email address 'john@example.com' is not permitted to receive identity verification notifications", mock.Hook.LastEntry().Message)
+}
+
+func TestShouldBindIdentityVerificationToSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.JWTSecret = testJWTSecret
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.BindSession = true
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.IssuanceRateLimit.Requests = 3
+	mock.Ctx.Configuration.IdentityValidation.ResetPassword.IssuanceRateLimit.Period = time.Hour
+	mock.Ctx.Request.Header.Add(fasthttp.HeaderXForwardedProto, "http")
+	mock.Ctx.Request.Header.Add(fasthttp.HeaderXForwardedHost, "host")
+
+	var verification model.IdentityVerification
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			CountIdentityVerifications(mock.Ctx, gomock.Eq("john"), gomock.Eq("Claim"), gomock.Any()).
+			Return(2, nil),
+		mock.StorageMock.EXPECT().
+			SaveIdentityVerification(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ any, v model.IdentityVerification) error {
+				verification = v
+
+				return nil
+			}),
+	)
+
+	mock.NotifierMock.EXPECT().
+		Send(gomock.Eq(mock.Ctx), gomock.Eq(mail.Address{Address: "john@example.com"}), gomock.Eq("Title"), gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	middlewares.IdentityVerificationStart(newArgs(defaultRetriever), nil)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	userSession, err := mock.Ctx.GetSession()
+
+	assert.NoError(t, err)
+	assert.Equal(t, verification.JTI.String(), userSession.IdentityVerificationJTI)
+}
+
 // Test Finish process.
 type IdentityVerificationFinishProcess struct {
 	suite.Suite
@@ -275,7 +352,162 @@ func (s *IdentityVerificationFinishProcess) TestShouldReturn200OnFinishComplete(
 	assert.Equal(s.T(), fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
 }
 
+func (s *IdentityVerificationFinishProcess) TestShouldReturn200OnFinishCompleteWithIssuerKey() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.JWTAlgorithm = "ES256"
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.IdentityProvidersOpenIDConnect{
+		JSONWebKeys: []schema.JWK{{KeyID: "next", Algorithm: "ES256", Key: key}},
+	}
+
+	verification := model.NewIdentityVerification(uuid.New(), "john", "EXP_ACTION", s.mock.Ctx.RemoteIP(), time.Minute*5)
+
+	method, kid, signer, err := s.mock.Ctx.GetIdentityVerificationJWTSigner()
+	s.Require().NoError(err)
+	s.Equal(jwt.SigningMethodES256, method)
+	s.Equal("next", kid)
+
+	token := jwt.NewWithClaims(method, verification.ToIdentityVerificationClaim())
+	token.Header["kid"] = kid
+
+	ss, err := token.SignedString(signer)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", ss))
+
+	s.mock.StorageMock.EXPECT().
+		FindIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+		Return(true, nil)
+
+	s.mock.StorageMock.EXPECT().
+		ConsumeIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String()), gomock.Eq(model.NewNullIP(s.mock.Ctx.RemoteIP()))).
+		Return(nil)
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	assert.Equal(s.T(), fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *IdentityVerificationFinishProcess) TestShouldFailIfTokenIsSignedWithUnknownIssuerKey() {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.JWTAlgorithm = "ES256"
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.IdentityProvidersOpenIDConnect{
+		JSONWebKeys: []schema.JWK{{KeyID: "next", Algorithm: "ES256", Key: key}},
+	}
+
+	verification := model.NewIdentityVerification(uuid.New(), "john", "EXP_ACTION", s.mock.Ctx.RemoteIP(), time.Minute*5)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, verification.ToIdentityVerificationClaim())
+	token.Header["kid"] = "previous"
+
+	ss, err := token.SignedString(key)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", ss))
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Operation failed")
+	assert.EqualError(s.T(), s.mock.Hook.LastEntry().Data["error"].(error), "token is unverifiable: error while executing keyfunc: the token is signed with the key with id 'previous' and algorithm 'ES256' but the key is not configured")
+}
+
 func TestRunIdentityVerificationFinish(t *testing.T) {
 	s := new(IdentityVerificationFinishProcess)
 	suite.Run(t, s)
 }
+
+func (s *IdentityVerificationFinishProcess) TestShouldFailIfTokenIsNotBoundToSession() {
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.BindSession = true
+
+	token, verification := createToken(s.mock, "john", "EXP_ACTION",
+		time.Now().Add(1*time.Minute))
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", token))
+
+	s.mock.StorageMock.EXPECT().
+		FindIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+		Return(true, nil)
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "The identity verification token must be used from the same browser and network it was requested from")
+	assert.Equal(s.T(), "Error occurred handling the identity verification token, the token is bound to another client", s.mock.Hook.LastEntry().Message)
+	assert.EqualError(s.T(), s.mock.Hook.LastEntry().Data["error"].(error), "the token was not issued to this session")
+}
+
+func (s *IdentityVerificationFinishProcess) TestShouldFailIfTokenIsUsedFromAnotherRemoteIP() {
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.BindRemoteIP = true
+
+	token, verification := createToken(s.mock, "john", "EXP_ACTION",
+		time.Now().Add(1*time.Minute))
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", token))
+
+	verification.IssuedIP = model.NewIP(net.ParseIP("192.168.0.10"))
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			FindIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+			Return(true, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+			Return(&verification, nil),
+	)
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "The identity verification token must be used from the same browser and network it was requested from")
+	assert.EqualError(s.T(), s.mock.Hook.LastEntry().Data["error"].(error), fmt.Sprintf("the token was issued to the remote ip '192.168.0.10' but it was used from the remote ip '%s'", s.mock.Ctx.RemoteIP()))
+}
+
+func (s *IdentityVerificationFinishProcess) TestShouldSucceedIfTokenIsBoundToSessionAndRemoteIP() {
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.BindSession = true
+	s.mock.Ctx.Configuration.IdentityValidation.ResetPassword.BindRemoteIP = true
+
+	token, verification := createToken(s.mock, "john", "EXP_ACTION",
+		time.Now().Add(1*time.Minute))
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", token))
+
+	userSession, err := s.mock.Ctx.GetSession()
+	s.Require().NoError(err)
+
+	userSession.IdentityVerificationJTI = verification.JTI.String()
+
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			FindIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+			Return(true, nil),
+		s.mock.StorageMock.EXPECT().
+			LoadIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+			Return(&verification, nil),
+		s.mock.StorageMock.EXPECT().
+			ConsumeIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String()), gomock.Eq(model.NewNullIP(s.mock.Ctx.RemoteIP()))).
+			Return(nil),
+	)
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	assert.Equal(s.T(), fasthttp.StatusOK, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *IdentityVerificationFinishProcess) TestShouldFailIfTokenIsConsumedConcurrently() {
+	token, verification := createToken(s.mock, "john", "EXP_ACTION",
+		time.Now().Add(1*time.Minute))
+	s.mock.Ctx.Request.SetBodyString(fmt.Sprintf("{\"token\":\"%s\"}", token))
+
+	s.mock.StorageMock.EXPECT().
+		FindIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String())).
+		Return(true, nil)
+
+	s.mock.StorageMock.EXPECT().
+		ConsumeIdentityVerification(s.mock.Ctx, gomock.Eq(verification.JTI.String()), gomock.Eq(model.NewNullIP(s.mock.Ctx.RemoteIP()))).
+		Return(storage.ErrIdentityVerificationNotConsumable)
+
+	middlewares.IdentityVerificationFinish(newFinishArgs(), next)(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "The identity verification token has already been used")
+	assert.Equal(s.T(), "Error occurred consuming the identity verification during the validation phase, the token was consumed or revoked by another request", s.mock.Hook.LastEntry().Message)
+}
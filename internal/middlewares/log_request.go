@@ -16,3 +16,14 @@ func LogRequest(next fasthttp.RequestHandler) fasthttp.RequestHandler {
 		log.Tracef("Replied (status=%d)", ctx.Response.StatusCode())
 	}
 }
+
+// LogModule is an AutheliaMiddleware which replaces the request logger with one which uses the logger of the module.
+func LogModule(module string) AutheliaMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx *AutheliaCtx) {
+			ctx.Logger = NewRequestLoggerModule(ctx.RequestCtx, module)
+
+			next(ctx)
+		}
+	}
+}
@@ -3,8 +3,11 @@ package middlewares
 import (
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/logging"
 )
 
 func TestShouldCallNextFunction(t *testing.T) {
@@ -17,3 +20,22 @@ func TestShouldCallNextFunction(t *testing.T) {
 
 	assert.Equal(t, true, val)
 }
+
+func TestShouldUseModuleRequestLogger(t *testing.T) {
+	ctx := &AutheliaCtx{RequestCtx: &fasthttp.RequestCtx{}}
+
+	ctx.Request.SetRequestURI("/api/oidc/token")
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+
+	var logger *logrus.Logger
+
+	LogModule(logging.ModuleOpenIDConnect)(func(ctx *AutheliaCtx) {
+		logger = ctx.Logger.Logger
+
+		assert.Equal(t, "/api/oidc/token", ctx.Logger.Data[logging.FieldPath])
+		assert.Equal(t, fasthttp.MethodPost, ctx.Logger.Data[logging.FieldMethod])
+	})(ctx)
+
+	assert.Same(t, logging.LoggerModule(logging.ModuleOpenIDConnect), logger)
+	assert.NotSame(t, logging.Logger(), logger)
+}
@@ -67,3 +67,22 @@ func NewMetricsAuthzRequest(metrics metrics.Recorder) (middleware Basic) {
 		}
 	}
 }
+
+// NewMetricsFlow returns a middleware which records the end-to-end duration of a flow if provided with a
+// metrics.Recorder, otherwise it returns nil. It should wrap the handler as early as possible so the duration includes
+// all of the middlewares such as the artificial delay applied to the authentication attempts.
+func NewMetricsFlow(metrics metrics.Recorder, flow string) (middleware Basic) {
+	if metrics == nil {
+		return nil
+	}
+
+	return func(next fasthttp.RequestHandler) (handler fasthttp.RequestHandler) {
+		return func(ctx *fasthttp.RequestCtx) {
+			started := time.Now()
+
+			next(ctx)
+
+			metrics.RecordFlowDuration(flow, strconv.Itoa(ctx.Response.StatusCode()), RequestCtxTraceID(ctx), time.Since(started))
+		}
+	}
+}
@@ -1,94 +1,222 @@
 package middlewares
 
 import (
+	"fmt"
+	"math"
 	"regexp"
+	"strings"
 
 	"github.com/trustelem/zxcvbn"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // PasswordPolicyProvider represents an implementation of a password policy provider.
 type PasswordPolicyProvider interface {
 	Check(password string) (err error)
+	CheckForGroups(password string, groups []string) (err error)
 }
 
 // NewPasswordPolicyProvider returns a new password policy provider.
 func NewPasswordPolicyProvider(config schema.PasswordPolicy) (provider PasswordPolicyProvider) {
-	if !config.Standard.Enabled && !config.ZXCVBN.Enabled {
-		return &StandardPasswordPolicyProvider{}
+	provider = newPasswordPolicyProvider(config.Standard, config.ZXCVBN, config.BannedWords)
+
+	if len(config.GroupPolicies) == 0 {
+		return provider
+	}
+
+	p := &GroupPasswordPolicyProvider{fallback: provider}
+
+	for _, policy := range config.GroupPolicies {
+		p.policies = append(p.policies, groupPasswordPolicy{groups: policy.Groups, provider: newPasswordPolicyProvider(policy.Standard, policy.ZXCVBN, config.BannedWords)})
+	}
+
+	return p
+}
+
+func newPasswordPolicyProvider(standard schema.PasswordPolicyStandard, zxcvbn schema.PasswordPolicyZXCVBN, bannedWords []string) (provider PasswordPolicyProvider) {
+	var banned []string
+
+	for _, word := range bannedWords {
+		banned = append(banned, strings.ToLower(word))
+	}
+
+	if !standard.Enabled && !zxcvbn.Enabled {
+		return &StandardPasswordPolicyProvider{banned: banned}
 	}
 
-	if config.Standard.Enabled {
-		p := &StandardPasswordPolicyProvider{}
+	if standard.Enabled {
+		p := &StandardPasswordPolicyProvider{banned: banned}
 
-		p.min, p.max = config.Standard.MinLength, config.Standard.MaxLength
+		p.min, p.max = standard.MinLength, standard.MaxLength
 
-		if config.Standard.RequireLowercase {
+		if standard.RequireLowercase {
 			p.patterns = append(p.patterns, *regexp.MustCompile(`[a-z]+`))
+			p.requirements = append(p.requirements, PasswordPolicyRequirementLowercase)
 		}
 
-		if config.Standard.RequireUppercase {
+		if standard.RequireUppercase {
 			p.patterns = append(p.patterns, *regexp.MustCompile(`[A-Z]+`))
+			p.requirements = append(p.requirements, PasswordPolicyRequirementUppercase)
 		}
 
-		if config.Standard.RequireNumber {
+		if standard.RequireNumber {
 			p.patterns = append(p.patterns, *regexp.MustCompile(`[0-9]+`))
+			p.requirements = append(p.requirements, PasswordPolicyRequirementNumber)
 		}
 
-		if config.Standard.RequireSpecial {
+		if standard.RequireSpecial {
 			p.patterns = append(p.patterns, *regexp.MustCompile(`[^a-zA-Z0-9]+`))
+			p.requirements = append(p.requirements, PasswordPolicyRequirementSpecial)
 		}
 
 		return p
 	}
 
-	if config.ZXCVBN.Enabled {
-		return &ZXCVBNPasswordPolicyProvider{minScore: config.ZXCVBN.MinScore}
+	if zxcvbn.Enabled {
+		return &ZXCVBNPasswordPolicyProvider{minScore: zxcvbn.MinScore, minEntropy: zxcvbn.MinEntropy, banned: banned}
 	}
 
-	return &StandardPasswordPolicyProvider{}
+	return &StandardPasswordPolicyProvider{banned: banned}
+}
+
+// PasswordPolicyError is returned when a password does not meet the password policy, and lists each requirement of
+// the password policy which was not met.
+type PasswordPolicyError struct {
+	Requirements []string
+}
+
+// Error implements the error interface.
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%s: the password does not meet the %s requirements", errPasswordPolicyNoMet, strings.Join(e.Requirements, ", "))
+}
+
+// Is returns true if the target is the generic password policy error.
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == errPasswordPolicyNoMet
+}
+
+func newPasswordPolicyError(requirements []string) (err error) {
+	if len(requirements) == 0 {
+		return nil
+	}
+
+	return &PasswordPolicyError{Requirements: requirements}
+}
+
+// GroupPasswordPolicyProvider handles password policy checking where the policy depends on the groups of the user.
+type GroupPasswordPolicyProvider struct {
+	fallback PasswordPolicyProvider
+	policies []groupPasswordPolicy
+}
+
+type groupPasswordPolicy struct {
+	groups   []string
+	provider PasswordPolicyProvider
+}
+
+// Check checks the password against the top level policy.
+func (p GroupPasswordPolicyProvider) Check(password string) (err error) {
+	return p.fallback.Check(password)
+}
+
+// CheckForGroups checks the password against the first policy which applies to any of the groups, or the top level
+// policy if none of them apply.
+func (p GroupPasswordPolicyProvider) CheckForGroups(password string, groups []string) (err error) {
+	for _, policy := range p.policies {
+		if utils.IsStringSliceContainsAny(policy.groups, groups) {
+			return policy.provider.Check(password)
+		}
+	}
+
+	return p.fallback.Check(password)
 }
 
 // ZXCVBNPasswordPolicyProvider handles zxcvbn password policy checking.
 type ZXCVBNPasswordPolicyProvider struct {
-	minScore int
+	minScore   int
+	minEntropy int
+	banned     []string
 }
 
 // Check checks the password against the policy.
 func (p ZXCVBNPasswordPolicyProvider) Check(password string) (err error) {
-	result := zxcvbn.PasswordStrength(password, nil)
+	var requirements []string
+
+	// The banned words are also used as the user inputs so passwords derived from them are scored as weaker.
+	result := zxcvbn.PasswordStrength(password, p.banned)
 
 	if result.Score < p.minScore {
-		return errPasswordPolicyNoMet
+		requirements = append(requirements, PasswordPolicyRequirementMinScore)
 	}
 
-	return nil
+	if p.minEntropy > 0 && math.Log2(result.Guesses) < float64(p.minEntropy) {
+		requirements = append(requirements, PasswordPolicyRequirementMinEntropy)
+	}
+
+	if isPasswordBanned(password, p.banned) {
+		requirements = append(requirements, PasswordPolicyRequirementBannedWords)
+	}
+
+	return newPasswordPolicyError(requirements)
+}
+
+// CheckForGroups checks the password against the policy, the groups are ignored as the policy applies to all groups.
+func (p ZXCVBNPasswordPolicyProvider) CheckForGroups(password string, _ []string) (err error) {
+	return p.Check(password)
 }
 
 // StandardPasswordPolicyProvider handles standard password policy checking.
 type StandardPasswordPolicyProvider struct {
-	patterns []regexp.Regexp
-	min, max int
+	patterns     []regexp.Regexp
+	requirements []string
+	min, max     int
+	banned       []string
 }
 
 // Check checks the password against the policy.
 func (p StandardPasswordPolicyProvider) Check(password string) (err error) {
-	patterns := len(p.patterns)
+	var requirements []string
 
-	if (p.min > 0 && len(password) < p.min) || (p.max > 0 && len(password) > p.max) {
-		return errPasswordPolicyNoMet
+	if p.min > 0 && len(password) < p.min {
+		requirements = append(requirements, PasswordPolicyRequirementMinLength)
 	}
 
-	if patterns == 0 {
-		return nil
+	if p.max > 0 && len(password) > p.max {
+		requirements = append(requirements, PasswordPolicyRequirementMaxLength)
 	}
 
-	for i := 0; i < patterns; i++ {
+	for i := 0; i < len(p.patterns); i++ {
 		if !p.patterns[i].MatchString(password) {
-			return errPasswordPolicyNoMet
+			requirements = append(requirements, p.requirements[i])
+		}
+	}
+
+	if isPasswordBanned(password, p.banned) {
+		requirements = append(requirements, PasswordPolicyRequirementBannedWords)
+	}
+
+	return newPasswordPolicyError(requirements)
+}
+
+// CheckForGroups checks the password against the policy, the groups are ignored as the policy applies to all groups.
+func (p StandardPasswordPolicyProvider) CheckForGroups(password string, _ []string) (err error) {
+	return p.Check(password)
+}
+
+func isPasswordBanned(password string, banned []string) bool {
+	if len(banned) == 0 {
+		return false
+	}
+
+	password = strings.ToLower(password)
+
+	for _, word := range banned {
+		if strings.Contains(password, word) {
+			return true
 		}
 	}
 
-	return nil
+	return false
 }
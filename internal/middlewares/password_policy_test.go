@@ -39,22 +39,22 @@ func TestNewPasswordPolicyProvider(t *testing.T) {
 		{
 			desc:     "ShouldReturnConfiguredProviderWithMinLowercase",
 			have:     schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8, RequireLowercase: true}},
-			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`)}},
+			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`)}, requirements: []string{PasswordPolicyRequirementLowercase}},
 		},
 		{
 			desc:     "ShouldReturnConfiguredProviderWithMinLowercaseUppercase",
 			have:     schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8, RequireLowercase: true, RequireUppercase: true}},
-			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`)}},
+			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`)}, requirements: []string{PasswordPolicyRequirementLowercase, PasswordPolicyRequirementUppercase}},
 		},
 		{
 			desc:     "ShouldReturnConfiguredProviderWithMinLowercaseUppercaseNumber",
 			have:     schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8, RequireLowercase: true, RequireUppercase: true, RequireNumber: true}},
-			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`), *regexp.MustCompile(`[0-9]+`)}},
+			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`), *regexp.MustCompile(`[0-9]+`)}, requirements: []string{PasswordPolicyRequirementLowercase, PasswordPolicyRequirementUppercase, PasswordPolicyRequirementNumber}},
 		},
 		{
 			desc:     "ShouldReturnConfiguredProviderWithMinLowercaseUppercaseSpecial",
 			have:     schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8, RequireLowercase: true, RequireUppercase: true, RequireSpecial: true}},
-			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`), *regexp.MustCompile(`[^a-zA-Z0-9]+`)}},
+			expected: &StandardPasswordPolicyProvider{min: 8, patterns: []regexp.Regexp{*regexp.MustCompile(`[a-z]+`), *regexp.MustCompile(`[A-Z]+`), *regexp.MustCompile(`[^a-zA-Z0-9]+`)}, requirements: []string{PasswordPolicyRequirementLowercase, PasswordPolicyRequirementUppercase, PasswordPolicyRequirementSpecial}},
 		},
 	}
 
@@ -138,9 +138,115 @@ func TestPasswordPolicyProvider_Validate(t *testing.T) {
 			for i := 0; i < len(tc.have); i++ {
 				provider := NewPasswordPolicyProvider(tc.config)
 				t.Run(tc.have[i], func(t *testing.T) {
-					assert.Equal(t, tc.expected[i], provider.Check(tc.have[i]))
+					if tc.expected[i] == nil {
+						assert.NoError(t, provider.Check(tc.have[i]))
+					} else {
+						assert.ErrorIs(t, provider.Check(tc.have[i]), tc.expected[i])
+					}
 				})
 			}
 		})
 	}
 }
+
+func TestPasswordPolicyProvider_Requirements(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		config   schema.PasswordPolicy
+		have     string
+		expected []string
+	}{
+		{
+			desc:     "ShouldReportAllStandardRequirements",
+			config:   schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8, RequireLowercase: true, RequireUppercase: true, RequireNumber: true, RequireSpecial: true}},
+			have:     "abc",
+			expected: []string{PasswordPolicyRequirementMinLength, PasswordPolicyRequirementUppercase, PasswordPolicyRequirementNumber, PasswordPolicyRequirementSpecial},
+		},
+		{
+			desc:     "ShouldReportMaxLength",
+			config:   schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MaxLength: 4}},
+			have:     "abcdef",
+			expected: []string{PasswordPolicyRequirementMaxLength},
+		},
+		{
+			desc:     "ShouldReportBannedWordsStandard",
+			config:   schema.PasswordPolicy{Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8}, BannedWords: []string{"authelia"}},
+			have:     "MyAutheliaPassword",
+			expected: []string{PasswordPolicyRequirementBannedWords},
+		},
+		{
+			desc:     "ShouldReportBannedWordsWithoutPolicy",
+			config:   schema.PasswordPolicy{BannedWords: []string{"Example"}},
+			have:     "example123",
+			expected: []string{PasswordPolicyRequirementBannedWords},
+		},
+		{
+			desc:     "ShouldReportMinScoreAndEntropy",
+			config:   schema.PasswordPolicy{ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 3, MinEntropy: 40}},
+			have:     "password1",
+			expected: []string{PasswordPolicyRequirementMinScore, PasswordPolicyRequirementMinEntropy},
+		},
+		{
+			desc:     "ShouldReportMinEntropy",
+			config:   schema.PasswordPolicy{ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 1, MinEntropy: 128}},
+			have:     "qjik2n@jAkjlmn123",
+			expected: []string{PasswordPolicyRequirementMinEntropy},
+		},
+		{
+			desc:     "ShouldReportBannedWordsZXCVBN",
+			config:   schema.PasswordPolicy{ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 1}, BannedWords: []string{"authelia"}},
+			have:     "qjik2n@jAkjlmnAUTHELIA",
+			expected: []string{PasswordPolicyRequirementBannedWords},
+		},
+		{
+			desc:   "ShouldPassZXCVBN",
+			config: schema.PasswordPolicy{ZXCVBN: schema.PasswordPolicyZXCVBN{Enabled: true, MinScore: 4, MinEntropy: 40}},
+			have:   "qjik2n@jAkjlmn123",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := NewPasswordPolicyProvider(tc.config).Check(tc.have)
+
+			if tc.expected == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var perr *PasswordPolicyError
+
+			require.ErrorAs(t, err, &perr)
+			assert.ErrorIs(t, err, errPasswordPolicyNoMet)
+			assert.Equal(t, tc.expected, perr.Requirements)
+		})
+	}
+}
+
+func TestPasswordPolicyProvider_CheckForGroups(t *testing.T) {
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicy{
+		Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 8},
+		GroupPolicies: []schema.PasswordPolicyGroupPolicy{
+			{Groups: []string{"admins"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 16}},
+			{Groups: []string{"dev", "ops"}, Standard: schema.PasswordPolicyStandard{Enabled: true, MinLength: 12}},
+		},
+	})
+
+	require.IsType(t, &GroupPasswordPolicyProvider{}, provider)
+
+	assert.NoError(t, provider.Check("abcdefghij"))
+	assert.NoError(t, provider.CheckForGroups("abcdefghij", nil))
+	assert.NoError(t, provider.CheckForGroups("abcdefghij", []string{"users"}))
+	assert.ErrorIs(t, provider.CheckForGroups("abcdefghij", []string{"users", "ops"}), errPasswordPolicyNoMet)
+	assert.NoError(t, provider.CheckForGroups("abcdefghijklm", []string{"users", "ops"}))
+	assert.ErrorIs(t, provider.CheckForGroups("abcdefghijklm", []string{"ops", "admins"}), errPasswordPolicyNoMet)
+	assert.NoError(t, provider.CheckForGroups("abcdefghijklmnop", []string{"admins"}))
+}
+
+func TestPasswordPolicyError(t *testing.T) {
+	err := &PasswordPolicyError{Requirements: []string{PasswordPolicyRequirementMinLength, PasswordPolicyRequirementBannedWords}}
+
+	assert.EqualError(t, err, "the supplied password does not met the security policy: the password does not meet the min_length, banned_words requirements")
+	assert.ErrorIs(t, err, errPasswordPolicyNoMet)
+}
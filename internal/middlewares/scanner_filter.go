@@ -0,0 +1,159 @@
+package middlewares
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/metrics"
+)
+
+// NewScannerFilter returns a middleware which tarpits requests that are obviously from scanners if enabled, otherwise
+// it returns nil. Requests are identified as being from a scanner if the User-Agent header or path matches one of the
+// configured patterns. Requests from the allowed networks and requests with a path which has one of the skip prefixes
+// are never filtered. At most scannerFilterMaxTarpits requests are delayed at once so the tarpit can't exhaust the
+// server workers, any filtered request beyond that limit receives the response immediately.
+func NewScannerFilter(config schema.ServerScannerFilter, metrics metrics.Recorder, skip ...string) (middleware Basic) {
+	if !config.Enable {
+		return nil
+	}
+
+	filter := &scannerFilter{
+		delay:   config.Delay,
+		tarpits: make(chan struct{}, scannerFilterMaxTarpits),
+		metrics: metrics,
+		skip:    skip,
+	}
+
+	for _, pattern := range config.UserAgents {
+		if re, err := regexp.Compile(pattern); err == nil {
+			filter.userAgents = append(filter.userAgents, re)
+		}
+	}
+
+	for _, pattern := range config.Paths {
+		if re, err := regexp.Compile(pattern); err == nil {
+			filter.paths = append(filter.paths, re)
+		}
+	}
+
+	for _, network := range config.AllowedNetworks {
+		if cidr, err := parseScannerFilterNetwork(network); err == nil {
+			filter.networks = append(filter.networks, cidr)
+		}
+	}
+
+	return filter.Middleware
+}
+
+type scannerFilter struct {
+	delay      time.Duration
+	tarpits    chan struct{}
+	userAgents []*regexp.Regexp
+	paths      []*regexp.Regexp
+	networks   []*net.IPNet
+	skip       []string
+	metrics    metrics.Recorder
+}
+
+// Middleware is the Basic middleware implementation of the scanner filter.
+func (f *scannerFilter) Middleware(next fasthttp.RequestHandler) (handler fasthttp.RequestHandler) {
+	return func(ctx *fasthttp.RequestCtx) {
+		reason := f.match(ctx)
+
+		if reason == "" {
+			next(ctx)
+
+			return
+		}
+
+		NewRequestLogger(ctx).WithField("reason", reason).Debug("Request was identified as a scanner and will be tarpitted")
+
+		if f.metrics != nil {
+			f.metrics.RecordScannerFiltered(reason)
+		}
+
+		f.tarpit(ctx)
+
+		SetContentTypeTextPlain(ctx)
+
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetBodyString(fasthttp.StatusMessage(fasthttp.StatusNotFound))
+	}
+}
+
+func (f *scannerFilter) tarpit(ctx *fasthttp.RequestCtx) {
+	select {
+	case f.tarpits <- struct{}{}:
+		defer func() {
+			<-f.tarpits
+		}()
+	default:
+		return
+	}
+
+	timer := time.NewTimer(f.delay)
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		timer.Stop()
+	}
+}
+
+func (f *scannerFilter) match(ctx *fasthttp.RequestCtx) (reason string) {
+	path := string(ctx.Path())
+
+	for _, prefix := range f.skip {
+		if strings.HasPrefix(path, prefix) {
+			return ""
+		}
+	}
+
+	if len(f.networks) != 0 {
+		ip := RequestCtxRemoteIP(ctx)
+
+		for _, network := range f.networks {
+			if network.Contains(ip) {
+				return ""
+			}
+		}
+	}
+
+	if userAgent := ctx.Request.Header.UserAgent(); len(bytes.TrimSpace(userAgent)) != 0 {
+		for _, re := range f.userAgents {
+			if re.Match(userAgent) {
+				return scannerFilterReasonUserAgent
+			}
+		}
+	}
+
+	for _, re := range f.paths {
+		if re.MatchString(path) {
+			return scannerFilterReasonPath
+		}
+	}
+
+	return ""
+}
+
+func parseScannerFilterNetwork(value string) (cidr *net.IPNet, err error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+
+		if ip.To4() != nil {
+			value += "/32"
+		} else {
+			value += "/128"
+		}
+	}
+
+	_, cidr, err = net.ParseCIDR(value)
+
+	return cidr, err
+}
@@ -0,0 +1,130 @@
+package middlewares
+
+import (
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestNewScannerFilterDisabled(t *testing.T) {
+	assert.Nil(t, NewScannerFilter(schema.ServerScannerFilter{}, nil))
+}
+
+func TestScannerFilter(t *testing.T) {
+	config := schema.DefaultServerConfiguration.ScannerFilter
+
+	config.Enable = true
+	config.Delay = time.Millisecond
+	config.AllowedNetworks = []string{"10.0.0.0/8", "192.168.1.1"}
+
+	middleware := NewScannerFilter(config, nil, "/api/authz")
+
+	testCases := []struct {
+		name      string
+		path      string
+		userAgent string
+		remoteIP  string
+		xff       string
+		filtered  bool
+	}{
+		{"ShouldNotFilterPortal", "/", "Mozilla/5.0", "127.0.0.1", "", false},
+		{"ShouldNotFilterAPI", "/api/state", "Mozilla/5.0", "127.0.0.1", "", false},
+		{"ShouldNotFilterWellKnown", "/.well-known/openid-configuration", "", "127.0.0.1", "", false},
+		{"ShouldFilterPHP", "/index.php", "Mozilla/5.0", "127.0.0.1", "", true},
+		{"ShouldFilterWordPress", "/wp-admin/", "Mozilla/5.0", "127.0.0.1", "", true},
+		{"ShouldFilterDotEnv", "/.env", "Mozilla/5.0", "127.0.0.1", "", true},
+		{"ShouldFilterUserAgent", "/", "sqlmap/1.7#stable (https://sqlmap.org)", "127.0.0.1", "", true},
+		{"ShouldFilterUserAgentCaseInsensitive", "/", "Mozilla/5.0 (compatible; Nmap Scripting Engine)", "127.0.0.1", "", true},
+		{"ShouldNotFilterSkipped", "/api/authz/forward-auth", "sqlmap", "127.0.0.1", "", false},
+		{"ShouldNotFilterAllowedNetwork", "/index.php", "sqlmap", "10.20.0.1", "", false},
+		{"ShouldNotFilterAllowedIP", "/index.php", "sqlmap", "192.168.1.1", "", false},
+		{"ShouldFilterOtherIP", "/index.php", "sqlmap", "192.168.1.2", "", true},
+		{"ShouldNotFilterAllowedNetworkForwardedFor", "/index.php", "sqlmap", "192.168.1.2", "10.20.0.1", false},
+		{"ShouldFilterOtherIPForwardedFor", "/index.php", "sqlmap", "10.20.0.1", "192.168.1.2", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP(tc.remoteIP)}, nil)
+
+			ctx.Request.SetRequestURI(tc.path)
+			ctx.Request.Header.SetUserAgent(tc.userAgent)
+
+			if tc.xff != "" {
+				ctx.Request.Header.Set(fasthttp.HeaderXForwardedFor, tc.xff)
+			}
+
+			called := false
+
+			middleware(func(ctx *fasthttp.RequestCtx) {
+				called = true
+
+				ctx.SetStatusCode(fasthttp.StatusOK)
+			})(ctx)
+
+			assert.Equal(t, !tc.filtered, called)
+
+			if tc.filtered {
+				assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+			} else {
+				assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+			}
+		})
+	}
+}
+
+func TestScannerFilterShouldNotTarpitBeyondLimit(t *testing.T) {
+	config := schema.DefaultServerConfiguration.ScannerFilter
+
+	config.Enable = true
+	config.Delay = time.Hour
+
+	filter := &scannerFilter{
+		delay:   config.Delay,
+		tarpits: make(chan struct{}, 1),
+	}
+
+	for _, pattern := range config.Paths {
+		filter.paths = append(filter.paths, regexp.MustCompile(pattern))
+	}
+
+	filter.tarpits <- struct{}{}
+
+	ctx := &fasthttp.RequestCtx{}
+
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}, nil)
+
+	ctx.Request.SetRequestURI("/index.php")
+
+	called := false
+
+	start := time.Now()
+
+	filter.Middleware(func(ctx *fasthttp.RequestCtx) {
+		called = true
+	})(ctx)
+
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.False(t, called)
+	assert.Equal(t, fasthttp.StatusNotFound, ctx.Response.StatusCode())
+	assert.Len(t, filter.tarpits, 1)
+
+	<-filter.tarpits
+
+	filter.delay = time.Millisecond
+
+	filter.Middleware(func(ctx *fasthttp.RequestCtx) {
+		called = true
+	})(ctx)
+
+	assert.False(t, called)
+	assert.Len(t, filter.tarpits, 0)
+}
@@ -1,20 +1,30 @@
 package middlewares
 
 import (
+	"context"
+	"crypto/x509"
+
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/branding"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/health"
 	"github.com/authelia/authelia/v4/internal/metrics"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/ntp"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/push"
 	"github.com/authelia/authelia/v4/internal/random"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/saml"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/sms"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/templates"
 	"github.com/authelia/authelia/v4/internal/totp"
@@ -35,19 +45,36 @@ type AutheliaCtx struct {
 
 // Providers contain all provider provided to Authelia.
 type Providers struct {
-	Authorizer      *authorization.Authorizer
-	SessionProvider *session.Provider
-	Regulator       *regulation.Regulator
-	OpenIDConnect   *oidc.OpenIDConnectProvider
-	Metrics         metrics.Provider
-	NTP             *ntp.Provider
-	UserProvider    authentication.UserProvider
-	StorageProvider storage.Provider
-	Notifier        notification.Notifier
-	Templates       *templates.Provider
-	TOTP            totp.Provider
-	PasswordPolicy  PasswordPolicyProvider
-	Random          random.Provider
+	Authorizer         *authorization.Authorizer
+	SessionProvider    *session.Provider
+	Regulator          *regulation.Regulator
+	OpenIDConnect      *oidc.OpenIDConnectProvider
+	SAML               *saml.Provider
+	Metrics            metrics.Provider
+	Audit              audit.Provider
+	AuditStream        *audit.StreamSink
+	NTP                *ntp.Provider
+	UserProvider       authentication.UserProvider
+	SPNEGO             authentication.SPNEGOProvider
+	StorageProvider    storage.Provider
+	Notifier           notification.Notifier
+	SMS                sms.Provider
+	Push               push.Provider
+	Templates          *templates.Provider
+	TOTP               totp.Provider
+	PasswordPolicy     PasswordPolicyProvider
+	Random             random.Provider
+	Health             *health.Provider
+	Branding           *branding.Provider
+	DeviceCertificates DeviceCertificateProvider
+}
+
+// DeviceCertificateProvider issues the client certificates of managed devices and verifies the client certificates
+// forwarded by the proxy.
+type DeviceCertificateProvider interface {
+	GetHeader() (header string)
+	Issue(ctx context.Context, username, name string, request *x509.CertificateRequest) (certificate *model.DeviceCertificate, encoded []byte, err error)
+	VerifyHeader(ctx context.Context, value []byte, username string) (certificate *model.DeviceCertificate, err error)
 }
 
 // RequestHandler represents an Authelia request handler.
@@ -119,18 +146,31 @@ type OKResponse struct {
 	Data   any    `json:"data,omitempty"`
 }
 
+// ErrorCode is a machine-readable code which identifies the type of an error response of the API.
+type ErrorCode string
+
 // ErrorResponse model of an error response.
 type ErrorResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+	Status  string    `json:"status"`
+	Code    ErrorCode `json:"code,omitempty"`
+	Message string    `json:"message"`
+}
+
+// PasswordPolicyErrorResponse model of an error response when a password does not meet the password policy.
+type PasswordPolicyErrorResponse struct {
+	Status       string    `json:"status"`
+	Code         ErrorCode `json:"code,omitempty"`
+	Message      string    `json:"message"`
+	Requirements []string  `json:"requirements"`
 }
 
 // AuthenticationErrorResponse model of an error response.
 type AuthenticationErrorResponse struct {
-	Status         string `json:"status"`
-	Message        string `json:"message"`
-	Authentication bool   `json:"authentication"`
-	Elevation      bool   `json:"elevation"`
+	Status         string    `json:"status"`
+	Code           ErrorCode `json:"code,omitempty"`
+	Message        string    `json:"message"`
+	Authentication bool      `json:"authentication"`
+	Elevation      bool      `json:"elevation"`
 }
 
 // ElevatedForbiddenResponse is a response for RequireElevated.
@@ -35,6 +35,7 @@ type MockAutheliaCtx struct {
 	UserProviderMock *MockUserProvider
 	StorageMock      *MockStorage
 	NotifierMock     *MockNotifier
+	SMSMock          *MockSMS
 	TOTPMock         *MockTOTP
 	RandomMock       *MockRandom
 
@@ -196,11 +197,14 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 	mockAuthelia.NotifierMock = NewMockNotifier(mockAuthelia.Ctrl)
 	providers.Notifier = mockAuthelia.NotifierMock
 
+	mockAuthelia.SMSMock = NewMockSMS(mockAuthelia.Ctrl)
+	providers.SMS = mockAuthelia.SMSMock
+
 	providers.Authorizer = authorization.NewAuthorizer(
 		&config)
 
 	providers.SessionProvider = session.NewProvider(
-		config.Session, nil)
+		config.Session, nil, nil)
 
 	providers.Regulator = regulation.NewRegulator(config.Regulation, providers.StorageProvider, &mockAuthelia.Clock)
 
@@ -4,10 +4,14 @@ package mocks
 // command `go generate github.com/authelia/authelia/v4/internal/mocks`.
 
 //go:generate mockgen -package mocks -destination user_provider.go -mock_names UserProvider=MockUserProvider github.com/authelia/authelia/v4/internal/authentication UserProvider
+//go:generate mockgen -package mocks -destination spnego_provider.go -mock_names SPNEGOProvider=MockSPNEGOProvider github.com/authelia/authelia/v4/internal/authentication SPNEGOProvider
 //go:generate mockgen -package mocks -destination notifier.go -mock_names Notifier=MockNotifier github.com/authelia/authelia/v4/internal/notification Notifier
+//go:generate mockgen -package mocks -destination sms.go -mock_names Provider=MockSMS github.com/authelia/authelia/v4/internal/sms Provider
 //go:generate mockgen -package mocks -destination totp.go -mock_names Provider=MockTOTP github.com/authelia/authelia/v4/internal/totp Provider
 //go:generate mockgen -package mocks -destination storage.go -mock_names Provider=MockStorage github.com/authelia/authelia/v4/internal/storage Provider
 //go:generate mockgen -package mocks -destination duo_api.go -mock_names API=MockAPI github.com/authelia/authelia/v4/internal/duo API
+//go:generate mockgen -package mocks -destination duo_provider.go -mock_names Provider=MockDuoProvider github.com/authelia/authelia/v4/internal/duo Provider
+//go:generate mockgen -package mocks -destination push_provider.go -mock_names Provider=MockPushProvider github.com/authelia/authelia/v4/internal/push Provider
 //go:generate mockgen -package mocks -destination random.go -mock_names Provider=MockRandom github.com/authelia/authelia/v4/internal/random Provider
 
 // Fosite Mocks.
@@ -44,6 +44,21 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AcquireLeaderElectionLease mocks base method.
+func (m *MockStorage) AcquireLeaderElectionLease(arg0 context.Context, arg1, arg2 string, arg3, arg4 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLeaderElectionLease", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLeaderElectionLease indicates an expected call of AcquireLeaderElectionLease.
+func (mr *MockStorageMockRecorder) AcquireLeaderElectionLease(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLeaderElectionLease", reflect.TypeOf((*MockStorage)(nil).AcquireLeaderElectionLease), arg0, arg1, arg2, arg3, arg4)
+}
+
 // AppendAuthenticationLog mocks base method.
 func (m *MockStorage) AppendAuthenticationLog(arg0 context.Context, arg1 model.AuthenticationAttempt) error {
 	m.ctrl.T.Helper()
@@ -129,6 +144,139 @@ func (mr *MockStorageMockRecorder) ConsumeOneTimeCode(arg0, arg1 any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeOneTimeCode", reflect.TypeOf((*MockStorage)(nil).ConsumeOneTimeCode), arg0, arg1)
 }
 
+// ConsumeRecoveryCode mocks base method.
+func (m *MockStorage) ConsumeRecoveryCode(arg0 context.Context, arg1, arg2 string, arg3 time.Time, arg4 model.NullIP) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockStorageMockRecorder) ConsumeRecoveryCode(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockStorage)(nil).ConsumeRecoveryCode), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CountDirectoryGroups mocks base method.
+func (m *MockStorage) CountDirectoryGroups(arg0 context.Context, arg1 model.DirectoryGroupFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDirectoryGroups", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDirectoryGroups indicates an expected call of CountDirectoryGroups.
+func (mr *MockStorageMockRecorder) CountDirectoryGroups(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDirectoryGroups", reflect.TypeOf((*MockStorage)(nil).CountDirectoryGroups), arg0, arg1)
+}
+
+// CountDirectoryUsers mocks base method.
+func (m *MockStorage) CountDirectoryUsers(arg0 context.Context, arg1 model.DirectoryUserFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDirectoryUsers", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDirectoryUsers indicates an expected call of CountDirectoryUsers.
+func (mr *MockStorageMockRecorder) CountDirectoryUsers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDirectoryUsers", reflect.TypeOf((*MockStorage)(nil).CountDirectoryUsers), arg0, arg1)
+}
+
+// CountIdentityVerifications mocks base method.
+func (m *MockStorage) CountIdentityVerifications(arg0 context.Context, arg1, arg2 string, arg3 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountIdentityVerifications", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountIdentityVerifications indicates an expected call of CountIdentityVerifications.
+func (mr *MockStorageMockRecorder) CountIdentityVerifications(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountIdentityVerifications", reflect.TypeOf((*MockStorage)(nil).CountIdentityVerifications), arg0, arg1, arg2, arg3)
+}
+
+// CountOneTimeCodes mocks base method.
+func (m *MockStorage) CountOneTimeCodes(arg0 context.Context, arg1, arg2 string, arg3 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOneTimeCodes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOneTimeCodes indicates an expected call of CountOneTimeCodes.
+func (mr *MockStorageMockRecorder) CountOneTimeCodes(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOneTimeCodes", reflect.TypeOf((*MockStorage)(nil).CountOneTimeCodes), arg0, arg1, arg2, arg3)
+}
+
+// CountRecoveryCodes mocks base method.
+func (m *MockStorage) CountRecoveryCodes(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecoveryCodes indicates an expected call of CountRecoveryCodes.
+func (mr *MockStorageMockRecorder) CountRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).CountRecoveryCodes), arg0, arg1)
+}
+
+// CountSessions mocks base method.
+func (m *MockStorage) CountSessions(arg0 context.Context, arg1 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSessions", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSessions indicates an expected call of CountSessions.
+func (mr *MockStorageMockRecorder) CountSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSessions", reflect.TypeOf((*MockStorage)(nil).CountSessions), arg0, arg1)
+}
+
+// DeactivateOAuth2CIBARequest mocks base method.
+func (m *MockStorage) DeactivateOAuth2CIBARequest(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateOAuth2CIBARequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateOAuth2CIBARequest indicates an expected call of DeactivateOAuth2CIBARequest.
+func (mr *MockStorageMockRecorder) DeactivateOAuth2CIBARequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2CIBARequest", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2CIBARequest), arg0, arg1)
+}
+
+// DeactivateOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) DeactivateOAuth2DeviceCodeSession(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateOAuth2DeviceCodeSession indicates an expected call of DeactivateOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) DeactivateOAuth2DeviceCodeSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2DeviceCodeSession), arg0, arg1)
+}
+
 // DeactivateOAuth2Session mocks base method.
 func (m *MockStorage) DeactivateOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -157,6 +305,255 @@ func (mr *MockStorageMockRecorder) DeactivateOAuth2SessionByRequestID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2SessionByRequestID), arg0, arg1, arg2)
 }
 
+// DeleteAbandonedOAuth2ConsentSessions mocks base method.
+func (m *MockStorage) DeleteAbandonedOAuth2ConsentSessions(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAbandonedOAuth2ConsentSessions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAbandonedOAuth2ConsentSessions indicates an expected call of DeleteAbandonedOAuth2ConsentSessions.
+func (mr *MockStorageMockRecorder) DeleteAbandonedOAuth2ConsentSessions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAbandonedOAuth2ConsentSessions", reflect.TypeOf((*MockStorage)(nil).DeleteAbandonedOAuth2ConsentSessions), arg0, arg1, arg2)
+}
+
+// DeleteAppPassword mocks base method.
+func (m *MockStorage) DeleteAppPassword(arg0 context.Context, arg1 string, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAppPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAppPassword indicates an expected call of DeleteAppPassword.
+func (mr *MockStorageMockRecorder) DeleteAppPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAppPassword", reflect.TypeOf((*MockStorage)(nil).DeleteAppPassword), arg0, arg1, arg2)
+}
+
+// DeleteAuditEvents mocks base method.
+func (m *MockStorage) DeleteAuditEvents(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditEvents indicates an expected call of DeleteAuditEvents.
+func (mr *MockStorageMockRecorder) DeleteAuditEvents(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditEvents", reflect.TypeOf((*MockStorage)(nil).DeleteAuditEvents), arg0, arg1, arg2)
+}
+
+// DeleteConfigurationFingerprints mocks base method.
+func (m *MockStorage) DeleteConfigurationFingerprints(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigurationFingerprints", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigurationFingerprints indicates an expected call of DeleteConfigurationFingerprints.
+func (mr *MockStorageMockRecorder) DeleteConfigurationFingerprints(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigurationFingerprints", reflect.TypeOf((*MockStorage)(nil).DeleteConfigurationFingerprints), arg0, arg1)
+}
+
+// DeleteDirectoryGroup mocks base method.
+func (m *MockStorage) DeleteDirectoryGroup(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDirectoryGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDirectoryGroup indicates an expected call of DeleteDirectoryGroup.
+func (mr *MockStorageMockRecorder) DeleteDirectoryGroup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectoryGroup", reflect.TypeOf((*MockStorage)(nil).DeleteDirectoryGroup), arg0, arg1)
+}
+
+// DeleteDirectoryUser mocks base method.
+func (m *MockStorage) DeleteDirectoryUser(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDirectoryUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDirectoryUser indicates an expected call of DeleteDirectoryUser.
+func (mr *MockStorageMockRecorder) DeleteDirectoryUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDirectoryUser", reflect.TypeOf((*MockStorage)(nil).DeleteDirectoryUser), arg0, arg1)
+}
+
+// DeleteExpiredDeviceCertificates mocks base method.
+func (m *MockStorage) DeleteExpiredDeviceCertificates(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredDeviceCertificates", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredDeviceCertificates indicates an expected call of DeleteExpiredDeviceCertificates.
+func (mr *MockStorageMockRecorder) DeleteExpiredDeviceCertificates(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredDeviceCertificates", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredDeviceCertificates), arg0, arg1, arg2)
+}
+
+// DeleteExpiredIdentityVerifications mocks base method.
+func (m *MockStorage) DeleteExpiredIdentityVerifications(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredIdentityVerifications", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredIdentityVerifications indicates an expected call of DeleteExpiredIdentityVerifications.
+func (mr *MockStorageMockRecorder) DeleteExpiredIdentityVerifications(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredIdentityVerifications", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredIdentityVerifications), arg0, arg1, arg2)
+}
+
+// DeleteExpiredMobileDevices mocks base method.
+func (m *MockStorage) DeleteExpiredMobileDevices(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredMobileDevices", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredMobileDevices indicates an expected call of DeleteExpiredMobileDevices.
+func (mr *MockStorageMockRecorder) DeleteExpiredMobileDevices(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredMobileDevices", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredMobileDevices), arg0, arg1, arg2)
+}
+
+// DeleteExpiredOAuth2BlacklistedJTIs mocks base method.
+func (m *MockStorage) DeleteExpiredOAuth2BlacklistedJTIs(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOAuth2BlacklistedJTIs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredOAuth2BlacklistedJTIs indicates an expected call of DeleteExpiredOAuth2BlacklistedJTIs.
+func (mr *MockStorageMockRecorder) DeleteExpiredOAuth2BlacklistedJTIs(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOAuth2BlacklistedJTIs", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredOAuth2BlacklistedJTIs), arg0, arg1, arg2)
+}
+
+// DeleteExpiredOAuth2CIBARequests mocks base method.
+func (m *MockStorage) DeleteExpiredOAuth2CIBARequests(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredOAuth2CIBARequests", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredOAuth2CIBARequests indicates an expected call of DeleteExpiredOAuth2CIBARequests.
+func (mr *MockStorageMockRecorder) DeleteExpiredOAuth2CIBARequests(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredOAuth2CIBARequests", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredOAuth2CIBARequests), arg0, arg1, arg2)
+}
+
+// DeleteExpiredPushChallenges mocks base method.
+func (m *MockStorage) DeleteExpiredPushChallenges(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredPushChallenges", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredPushChallenges indicates an expected call of DeleteExpiredPushChallenges.
+func (mr *MockStorageMockRecorder) DeleteExpiredPushChallenges(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredPushChallenges", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredPushChallenges), arg0, arg1, arg2)
+}
+
+// DeleteExpiredSAMLSessions mocks base method.
+func (m *MockStorage) DeleteExpiredSAMLSessions(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSAMLSessions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredSAMLSessions indicates an expected call of DeleteExpiredSAMLSessions.
+func (mr *MockStorageMockRecorder) DeleteExpiredSAMLSessions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSAMLSessions", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredSAMLSessions), arg0, arg1, arg2)
+}
+
+// DeleteExpiredSessions mocks base method.
+func (m *MockStorage) DeleteExpiredSessions(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredSessions indicates an expected call of DeleteExpiredSessions.
+func (mr *MockStorageMockRecorder) DeleteExpiredSessions(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessions", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredSessions), arg0, arg1, arg2)
+}
+
+// DeleteExpiredUserSessionRevocations mocks base method.
+func (m *MockStorage) DeleteExpiredUserSessionRevocations(arg0 context.Context, arg1 time.Time, arg2 int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredUserSessionRevocations", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredUserSessionRevocations indicates an expected call of DeleteExpiredUserSessionRevocations.
+func (mr *MockStorageMockRecorder) DeleteExpiredUserSessionRevocations(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredUserSessionRevocations", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredUserSessionRevocations), arg0, arg1, arg2)
+}
+
+// DeleteOAuth2DynamicClient mocks base method.
+func (m *MockStorage) DeleteOAuth2DynamicClient(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2DynamicClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2DynamicClient indicates an expected call of DeleteOAuth2DynamicClient.
+func (mr *MockStorageMockRecorder) DeleteOAuth2DynamicClient(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2DynamicClient", reflect.TypeOf((*MockStorage)(nil).DeleteOAuth2DynamicClient), arg0, arg1)
+}
+
+// DeletePhoneNumber mocks base method.
+func (m *MockStorage) DeletePhoneNumber(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePhoneNumber", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePhoneNumber indicates an expected call of DeletePhoneNumber.
+func (mr *MockStorageMockRecorder) DeletePhoneNumber(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePhoneNumber", reflect.TypeOf((*MockStorage)(nil).DeletePhoneNumber), arg0, arg1)
+}
+
 // DeletePreferredDuoDevice mocks base method.
 func (m *MockStorage) DeletePreferredDuoDevice(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -171,6 +568,34 @@ func (mr *MockStorageMockRecorder) DeletePreferredDuoDevice(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).DeletePreferredDuoDevice), arg0, arg1)
 }
 
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStorage) DeleteRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStorageMockRecorder) DeleteRecoveryCodes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteSession mocks base method.
+func (m *MockStorage) DeleteSession(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSession indicates an expected call of DeleteSession.
+func (mr *MockStorageMockRecorder) DeleteSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockStorage)(nil).DeleteSession), arg0, arg1)
+}
+
 // DeleteTOTPConfiguration mocks base method.
 func (m *MockStorage) DeleteTOTPConfiguration(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -185,6 +610,20 @@ func (mr *MockStorageMockRecorder) DeleteTOTPConfiguration(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfiguration), arg0, arg1)
 }
 
+// DeleteUserSessionIndex mocks base method.
+func (m *MockStorage) DeleteUserSessionIndex(arg0 context.Context, arg1 []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessionIndex", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessionIndex indicates an expected call of DeleteUserSessionIndex.
+func (mr *MockStorageMockRecorder) DeleteUserSessionIndex(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessionIndex", reflect.TypeOf((*MockStorage)(nil).DeleteUserSessionIndex), arg0, arg1)
+}
+
 // DeleteWebAuthnCredential mocks base method.
 func (m *MockStorage) DeleteWebAuthnCredential(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -243,6 +682,66 @@ func (mr *MockStorageMockRecorder) FindIdentityVerification(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentityVerification", reflect.TypeOf((*MockStorage)(nil).FindIdentityVerification), arg0, arg1)
 }
 
+// LoadAppPassword mocks base method.
+func (m *MockStorage) LoadAppPassword(arg0 context.Context, arg1, arg2 string) (*model.AppPassword, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAppPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.AppPassword)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAppPassword indicates an expected call of LoadAppPassword.
+func (mr *MockStorageMockRecorder) LoadAppPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAppPassword", reflect.TypeOf((*MockStorage)(nil).LoadAppPassword), arg0, arg1, arg2)
+}
+
+// LoadAppPasswords mocks base method.
+func (m *MockStorage) LoadAppPasswords(arg0 context.Context, arg1 string) ([]model.AppPassword, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAppPasswords", arg0, arg1)
+	ret0, _ := ret[0].([]model.AppPassword)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAppPasswords indicates an expected call of LoadAppPasswords.
+func (mr *MockStorageMockRecorder) LoadAppPasswords(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAppPasswords", reflect.TypeOf((*MockStorage)(nil).LoadAppPasswords), arg0, arg1)
+}
+
+// LoadAuditEvents mocks base method.
+func (m *MockStorage) LoadAuditEvents(arg0 context.Context, arg1 model.AuditEventFilter, arg2 model.Pagination) ([]model.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAuditEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAuditEvents indicates an expected call of LoadAuditEvents.
+func (mr *MockStorageMockRecorder) LoadAuditEvents(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuditEvents", reflect.TypeOf((*MockStorage)(nil).LoadAuditEvents), arg0, arg1, arg2)
+}
+
+// LoadAuthenticationHistory mocks base method.
+func (m *MockStorage) LoadAuthenticationHistory(arg0 context.Context, arg1 string, arg2 model.AuthenticationAttemptFilter, arg3 model.Pagination) ([]model.AuthenticationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAuthenticationHistory", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.AuthenticationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAuthenticationHistory indicates an expected call of LoadAuthenticationHistory.
+func (mr *MockStorageMockRecorder) LoadAuthenticationHistory(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuthenticationHistory", reflect.TypeOf((*MockStorage)(nil).LoadAuthenticationHistory), arg0, arg1, arg2, arg3)
+}
+
 // LoadAuthenticationLogs mocks base method.
 func (m *MockStorage) LoadAuthenticationLogs(arg0 context.Context, arg1 string, arg2 time.Time, arg3, arg4 int) ([]model.AuthenticationAttempt, error) {
 	m.ctrl.T.Helper()
@@ -258,6 +757,156 @@ func (mr *MockStorageMockRecorder) LoadAuthenticationLogs(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).LoadAuthenticationLogs), arg0, arg1, arg2, arg3, arg4)
 }
 
+// LoadConfigurationFingerprints mocks base method.
+func (m *MockStorage) LoadConfigurationFingerprints(arg0 context.Context, arg1 time.Time) ([]model.ConfigurationFingerprint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadConfigurationFingerprints", arg0, arg1)
+	ret0, _ := ret[0].([]model.ConfigurationFingerprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadConfigurationFingerprints indicates an expected call of LoadConfigurationFingerprints.
+func (mr *MockStorageMockRecorder) LoadConfigurationFingerprints(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadConfigurationFingerprints", reflect.TypeOf((*MockStorage)(nil).LoadConfigurationFingerprints), arg0, arg1)
+}
+
+// LoadDeviceCertificate mocks base method.
+func (m *MockStorage) LoadDeviceCertificate(arg0 context.Context, arg1 string) (*model.DeviceCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDeviceCertificate", arg0, arg1)
+	ret0, _ := ret[0].(*model.DeviceCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDeviceCertificate indicates an expected call of LoadDeviceCertificate.
+func (mr *MockStorageMockRecorder) LoadDeviceCertificate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDeviceCertificate", reflect.TypeOf((*MockStorage)(nil).LoadDeviceCertificate), arg0, arg1)
+}
+
+// LoadDeviceCertificates mocks base method.
+func (m *MockStorage) LoadDeviceCertificates(arg0 context.Context, arg1 string, arg2 model.Pagination) ([]model.DeviceCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDeviceCertificates", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.DeviceCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDeviceCertificates indicates an expected call of LoadDeviceCertificates.
+func (mr *MockStorageMockRecorder) LoadDeviceCertificates(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDeviceCertificates", reflect.TypeOf((*MockStorage)(nil).LoadDeviceCertificates), arg0, arg1, arg2)
+}
+
+// LoadDirectoryGroup mocks base method.
+func (m *MockStorage) LoadDirectoryGroup(arg0 context.Context, arg1 uuid.UUID) (*model.DirectoryGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryGroup", arg0, arg1)
+	ret0, _ := ret[0].(*model.DirectoryGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryGroup indicates an expected call of LoadDirectoryGroup.
+func (mr *MockStorageMockRecorder) LoadDirectoryGroup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryGroup", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryGroup), arg0, arg1)
+}
+
+// LoadDirectoryGroupMembers mocks base method.
+func (m *MockStorage) LoadDirectoryGroupMembers(arg0 context.Context, arg1 uuid.UUID) ([]model.DirectoryGroupMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryGroupMembers", arg0, arg1)
+	ret0, _ := ret[0].([]model.DirectoryGroupMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryGroupMembers indicates an expected call of LoadDirectoryGroupMembers.
+func (mr *MockStorageMockRecorder) LoadDirectoryGroupMembers(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryGroupMembers", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryGroupMembers), arg0, arg1)
+}
+
+// LoadDirectoryGroups mocks base method.
+func (m *MockStorage) LoadDirectoryGroups(arg0 context.Context, arg1 model.DirectoryGroupFilter, arg2, arg3 int) ([]model.DirectoryGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryGroups", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.DirectoryGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryGroups indicates an expected call of LoadDirectoryGroups.
+func (mr *MockStorageMockRecorder) LoadDirectoryGroups(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryGroups", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryGroups), arg0, arg1, arg2, arg3)
+}
+
+// LoadDirectoryUser mocks base method.
+func (m *MockStorage) LoadDirectoryUser(arg0 context.Context, arg1 uuid.UUID) (*model.DirectoryUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryUser", arg0, arg1)
+	ret0, _ := ret[0].(*model.DirectoryUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryUser indicates an expected call of LoadDirectoryUser.
+func (mr *MockStorageMockRecorder) LoadDirectoryUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryUser", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryUser), arg0, arg1)
+}
+
+// LoadDirectoryUserGroups mocks base method.
+func (m *MockStorage) LoadDirectoryUserGroups(arg0 context.Context, arg1 string) ([]model.DirectoryGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryUserGroups", arg0, arg1)
+	ret0, _ := ret[0].([]model.DirectoryGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryUserGroups indicates an expected call of LoadDirectoryUserGroups.
+func (mr *MockStorageMockRecorder) LoadDirectoryUserGroups(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryUserGroups", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryUserGroups), arg0, arg1)
+}
+
+// LoadDirectoryUserPassword mocks base method.
+func (m *MockStorage) LoadDirectoryUserPassword(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryUserPassword indicates an expected call of LoadDirectoryUserPassword.
+func (mr *MockStorageMockRecorder) LoadDirectoryUserPassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryUserPassword", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryUserPassword), arg0, arg1)
+}
+
+// LoadDirectoryUsers mocks base method.
+func (m *MockStorage) LoadDirectoryUsers(arg0 context.Context, arg1 model.DirectoryUserFilter, arg2, arg3 int) ([]model.DirectoryUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDirectoryUsers", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.DirectoryUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDirectoryUsers indicates an expected call of LoadDirectoryUsers.
+func (mr *MockStorageMockRecorder) LoadDirectoryUsers(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDirectoryUsers", reflect.TypeOf((*MockStorage)(nil).LoadDirectoryUsers), arg0, arg1, arg2, arg3)
+}
+
 // LoadIdentityVerification mocks base method.
 func (m *MockStorage) LoadIdentityVerification(arg0 context.Context, arg1 string) (*model.IdentityVerification, error) {
 	m.ctrl.T.Helper()
@@ -273,6 +922,51 @@ func (mr *MockStorageMockRecorder) LoadIdentityVerification(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadIdentityVerification", reflect.TypeOf((*MockStorage)(nil).LoadIdentityVerification), arg0, arg1)
 }
 
+// LoadMobileDevice mocks base method.
+func (m *MockStorage) LoadMobileDevice(arg0 context.Context, arg1 uuid.UUID) (*model.MobileDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadMobileDevice", arg0, arg1)
+	ret0, _ := ret[0].(*model.MobileDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadMobileDevice indicates an expected call of LoadMobileDevice.
+func (mr *MockStorageMockRecorder) LoadMobileDevice(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMobileDevice", reflect.TypeOf((*MockStorage)(nil).LoadMobileDevice), arg0, arg1)
+}
+
+// LoadMobileDeviceByAccessSignature mocks base method.
+func (m *MockStorage) LoadMobileDeviceByAccessSignature(arg0 context.Context, arg1 string) (*model.MobileDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadMobileDeviceByAccessSignature", arg0, arg1)
+	ret0, _ := ret[0].(*model.MobileDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadMobileDeviceByAccessSignature indicates an expected call of LoadMobileDeviceByAccessSignature.
+func (mr *MockStorageMockRecorder) LoadMobileDeviceByAccessSignature(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMobileDeviceByAccessSignature", reflect.TypeOf((*MockStorage)(nil).LoadMobileDeviceByAccessSignature), arg0, arg1)
+}
+
+// LoadMobileDevicesPush mocks base method.
+func (m *MockStorage) LoadMobileDevicesPush(arg0 context.Context, arg1 string, arg2 time.Time) ([]model.MobileDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadMobileDevicesPush", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.MobileDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadMobileDevicesPush indicates an expected call of LoadMobileDevicesPush.
+func (mr *MockStorageMockRecorder) LoadMobileDevicesPush(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadMobileDevicesPush", reflect.TypeOf((*MockStorage)(nil).LoadMobileDevicesPush), arg0, arg1, arg2)
+}
+
 // LoadOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) LoadOAuth2BlacklistedJTI(arg0 context.Context, arg1 string) (*model.OAuth2BlacklistedJTI, error) {
 	m.ctrl.T.Helper()
@@ -288,6 +982,36 @@ func (mr *MockStorageMockRecorder) LoadOAuth2BlacklistedJTI(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2BlacklistedJTI", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2BlacklistedJTI), arg0, arg1)
 }
 
+// LoadOAuth2CIBARequest mocks base method.
+func (m *MockStorage) LoadOAuth2CIBARequest(arg0 context.Context, arg1 string) (*model.OAuth2CIBARequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2CIBARequest", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2CIBARequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2CIBARequest indicates an expected call of LoadOAuth2CIBARequest.
+func (mr *MockStorageMockRecorder) LoadOAuth2CIBARequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2CIBARequest", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2CIBARequest), arg0, arg1)
+}
+
+// LoadOAuth2CIBARequestsPending mocks base method.
+func (m *MockStorage) LoadOAuth2CIBARequestsPending(arg0 context.Context, arg1 time.Time, arg2 int) ([]model.OAuth2CIBARequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2CIBARequestsPending", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.OAuth2CIBARequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2CIBARequestsPending indicates an expected call of LoadOAuth2CIBARequestsPending.
+func (mr *MockStorageMockRecorder) LoadOAuth2CIBARequestsPending(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2CIBARequestsPending", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2CIBARequestsPending), arg0, arg1, arg2)
+}
+
 // LoadOAuth2ConsentPreConfigurations mocks base method.
 func (m *MockStorage) LoadOAuth2ConsentPreConfigurations(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*storage.ConsentPreConfigRows, error) {
 	m.ctrl.T.Helper()
@@ -303,19 +1027,79 @@ func (mr *MockStorageMockRecorder) LoadOAuth2ConsentPreConfigurations(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentPreConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentPreConfigurations), arg0, arg1, arg2)
 }
 
-// LoadOAuth2ConsentSessionByChallengeID mocks base method.
-func (m *MockStorage) LoadOAuth2ConsentSessionByChallengeID(arg0 context.Context, arg1 uuid.UUID) (*model.OAuth2ConsentSession, error) {
+// LoadOAuth2ConsentSessionByChallengeID mocks base method.
+func (m *MockStorage) LoadOAuth2ConsentSessionByChallengeID(arg0 context.Context, arg1 uuid.UUID) (*model.OAuth2ConsentSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2ConsentSessionByChallengeID", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2ConsentSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2ConsentSessionByChallengeID indicates an expected call of LoadOAuth2ConsentSessionByChallengeID.
+func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionByChallengeID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionByChallengeID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionByChallengeID), arg0, arg1)
+}
+
+// LoadOAuth2ConsentSessionsByUsername mocks base method.
+func (m *MockStorage) LoadOAuth2ConsentSessionsByUsername(arg0 context.Context, arg1 string, arg2 model.OAuth2ConsentSessionFilter, arg3 model.Pagination) ([]model.OAuth2ConsentSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2ConsentSessionsByUsername", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]model.OAuth2ConsentSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2ConsentSessionsByUsername indicates an expected call of LoadOAuth2ConsentSessionsByUsername.
+func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionsByUsername(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionsByUsername), arg0, arg1, arg2, arg3)
+}
+
+// LoadOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) LoadOAuth2DeviceCodeSession(arg0 context.Context, arg1 string) (*model.OAuth2DeviceCodeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DeviceCodeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2DeviceCodeSession indicates an expected call of LoadOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) LoadOAuth2DeviceCodeSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DeviceCodeSession), arg0, arg1)
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCode mocks base method.
+func (m *MockStorage) LoadOAuth2DeviceCodeSessionByUserCode(arg0 context.Context, arg1 string) (*model.OAuth2DeviceCodeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2DeviceCodeSessionByUserCode", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DeviceCodeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCode indicates an expected call of LoadOAuth2DeviceCodeSessionByUserCode.
+func (mr *MockStorageMockRecorder) LoadOAuth2DeviceCodeSessionByUserCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DeviceCodeSessionByUserCode", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DeviceCodeSessionByUserCode), arg0, arg1)
+}
+
+// LoadOAuth2DynamicClient mocks base method.
+func (m *MockStorage) LoadOAuth2DynamicClient(arg0 context.Context, arg1 string) (*model.OAuth2DynamicClient, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadOAuth2ConsentSessionByChallengeID", arg0, arg1)
-	ret0, _ := ret[0].(*model.OAuth2ConsentSession)
+	ret := m.ctrl.Call(m, "LoadOAuth2DynamicClient", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DynamicClient)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoadOAuth2ConsentSessionByChallengeID indicates an expected call of LoadOAuth2ConsentSessionByChallengeID.
-func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionByChallengeID(arg0, arg1 any) *gomock.Call {
+// LoadOAuth2DynamicClient indicates an expected call of LoadOAuth2DynamicClient.
+func (mr *MockStorageMockRecorder) LoadOAuth2DynamicClient(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionByChallengeID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionByChallengeID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DynamicClient", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DynamicClient), arg0, arg1)
 }
 
 // LoadOAuth2PARContext mocks base method.
@@ -348,6 +1132,21 @@ func (mr *MockStorageMockRecorder) LoadOAuth2Session(arg0, arg1, arg2 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2Session", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2Session), arg0, arg1, arg2)
 }
 
+// LoadOAuth2TokenExchangeLineageByParentRequestID mocks base method.
+func (m *MockStorage) LoadOAuth2TokenExchangeLineageByParentRequestID(arg0 context.Context, arg1 string) ([]model.OAuth2TokenExchangeLineage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2TokenExchangeLineageByParentRequestID", arg0, arg1)
+	ret0, _ := ret[0].([]model.OAuth2TokenExchangeLineage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2TokenExchangeLineageByParentRequestID indicates an expected call of LoadOAuth2TokenExchangeLineageByParentRequestID.
+func (mr *MockStorageMockRecorder) LoadOAuth2TokenExchangeLineageByParentRequestID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2TokenExchangeLineageByParentRequestID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2TokenExchangeLineageByParentRequestID), arg0, arg1)
+}
+
 // LoadOneTimeCode mocks base method.
 func (m *MockStorage) LoadOneTimeCode(arg0 context.Context, arg1, arg2, arg3 string) (*model.OneTimeCode, error) {
 	m.ctrl.T.Helper()
@@ -408,6 +1207,21 @@ func (mr *MockStorageMockRecorder) LoadOneTimeCodeBySignature(arg0, arg1 any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOneTimeCodeBySignature", reflect.TypeOf((*MockStorage)(nil).LoadOneTimeCodeBySignature), arg0, arg1)
 }
 
+// LoadPhoneNumber mocks base method.
+func (m *MockStorage) LoadPhoneNumber(arg0 context.Context, arg1 string) (*model.PhoneNumber, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPhoneNumber", arg0, arg1)
+	ret0, _ := ret[0].(*model.PhoneNumber)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPhoneNumber indicates an expected call of LoadPhoneNumber.
+func (mr *MockStorageMockRecorder) LoadPhoneNumber(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPhoneNumber", reflect.TypeOf((*MockStorage)(nil).LoadPhoneNumber), arg0, arg1)
+}
+
 // LoadPreferred2FAMethod mocks base method.
 func (m *MockStorage) LoadPreferred2FAMethod(arg0 context.Context, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -438,6 +1252,51 @@ func (mr *MockStorageMockRecorder) LoadPreferredDuoDevice(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).LoadPreferredDuoDevice), arg0, arg1)
 }
 
+// LoadPushChallenge mocks base method.
+func (m *MockStorage) LoadPushChallenge(arg0 context.Context, arg1 uuid.UUID) (*model.PushChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadPushChallenge", arg0, arg1)
+	ret0, _ := ret[0].(*model.PushChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadPushChallenge indicates an expected call of LoadPushChallenge.
+func (mr *MockStorageMockRecorder) LoadPushChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPushChallenge", reflect.TypeOf((*MockStorage)(nil).LoadPushChallenge), arg0, arg1)
+}
+
+// LoadSAMLSession mocks base method.
+func (m *MockStorage) LoadSAMLSession(arg0 context.Context, arg1 string) (*model.SAMLSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSAMLSession", arg0, arg1)
+	ret0, _ := ret[0].(*model.SAMLSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadSAMLSession indicates an expected call of LoadSAMLSession.
+func (mr *MockStorageMockRecorder) LoadSAMLSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSAMLSession", reflect.TypeOf((*MockStorage)(nil).LoadSAMLSession), arg0, arg1)
+}
+
+// LoadSession mocks base method.
+func (m *MockStorage) LoadSession(arg0 context.Context, arg1 []byte, arg2 time.Time) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSession", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadSession indicates an expected call of LoadSession.
+func (mr *MockStorageMockRecorder) LoadSession(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSession", reflect.TypeOf((*MockStorage)(nil).LoadSession), arg0, arg1, arg2)
+}
+
 // LoadTOTPConfiguration mocks base method.
 func (m *MockStorage) LoadTOTPConfiguration(arg0 context.Context, arg1 string) (*model.TOTPConfiguration, error) {
 	m.ctrl.T.Helper()
@@ -468,6 +1327,21 @@ func (mr *MockStorageMockRecorder) LoadTOTPConfigurations(arg0, arg1, arg2 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurations), arg0, arg1, arg2)
 }
 
+// LoadUsageStatistics mocks base method.
+func (m *MockStorage) LoadUsageStatistics(arg0 context.Context, arg1 time.Time) (model.UsageStatistics, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUsageStatistics", arg0, arg1)
+	ret0, _ := ret[0].(model.UsageStatistics)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUsageStatistics indicates an expected call of LoadUsageStatistics.
+func (mr *MockStorageMockRecorder) LoadUsageStatistics(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUsageStatistics", reflect.TypeOf((*MockStorage)(nil).LoadUsageStatistics), arg0, arg1)
+}
+
 // LoadUserInfo mocks base method.
 func (m *MockStorage) LoadUserInfo(arg0 context.Context, arg1 string) (model.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -528,6 +1402,66 @@ func (mr *MockStorageMockRecorder) LoadUserOpaqueIdentifiers(arg0 any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserOpaqueIdentifiers", reflect.TypeOf((*MockStorage)(nil).LoadUserOpaqueIdentifiers), arg0)
 }
 
+// LoadUserSessionIndex mocks base method.
+func (m *MockStorage) LoadUserSessionIndex(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*model.UserSessionIndex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserSessionIndex", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.UserSessionIndex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserSessionIndex indicates an expected call of LoadUserSessionIndex.
+func (mr *MockStorageMockRecorder) LoadUserSessionIndex(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserSessionIndex", reflect.TypeOf((*MockStorage)(nil).LoadUserSessionIndex), arg0, arg1, arg2)
+}
+
+// LoadUserSessionIndexes mocks base method.
+func (m *MockStorage) LoadUserSessionIndexes(arg0 context.Context, arg1 string) ([]model.UserSessionIndex, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserSessionIndexes", arg0, arg1)
+	ret0, _ := ret[0].([]model.UserSessionIndex)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserSessionIndexes indicates an expected call of LoadUserSessionIndexes.
+func (mr *MockStorageMockRecorder) LoadUserSessionIndexes(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserSessionIndexes", reflect.TypeOf((*MockStorage)(nil).LoadUserSessionIndexes), arg0, arg1)
+}
+
+// LoadUserSessionRevocations mocks base method.
+func (m *MockStorage) LoadUserSessionRevocations(arg0 context.Context, arg1, arg2 time.Time) ([]model.UserSessionRevocation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserSessionRevocations", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.UserSessionRevocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserSessionRevocations indicates an expected call of LoadUserSessionRevocations.
+func (mr *MockStorageMockRecorder) LoadUserSessionRevocations(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserSessionRevocations", reflect.TypeOf((*MockStorage)(nil).LoadUserSessionRevocations), arg0, arg1, arg2)
+}
+
+// LoadUserSummaries mocks base method.
+func (m *MockStorage) LoadUserSummaries(arg0 context.Context, arg1, arg2 int) ([]model.UserSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserSummaries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.UserSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserSummaries indicates an expected call of LoadUserSummaries.
+func (mr *MockStorageMockRecorder) LoadUserSummaries(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserSummaries", reflect.TypeOf((*MockStorage)(nil).LoadUserSummaries), arg0, arg1, arg2)
+}
+
 // LoadWebAuthnCredentialByID mocks base method.
 func (m *MockStorage) LoadWebAuthnCredentialByID(arg0 context.Context, arg1 int) (*model.WebAuthnCredential, error) {
 	m.ctrl.T.Helper()
@@ -582,94 +1516,320 @@ func (m *MockStorage) LoadWebAuthnUser(arg0 context.Context, arg1, arg2 string)
 	return ret0, ret1
 }
 
-// LoadWebAuthnUser indicates an expected call of LoadWebAuthnUser.
-func (mr *MockStorageMockRecorder) LoadWebAuthnUser(arg0, arg1, arg2 any) *gomock.Call {
+// LoadWebAuthnUser indicates an expected call of LoadWebAuthnUser.
+func (mr *MockStorageMockRecorder) LoadWebAuthnUser(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebAuthnUser", reflect.TypeOf((*MockStorage)(nil).LoadWebAuthnUser), arg0, arg1, arg2)
+}
+
+// LoadWebAuthnUserByUserID mocks base method.
+func (m *MockStorage) LoadWebAuthnUserByUserID(arg0 context.Context, arg1, arg2 string) (*model.WebAuthnUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadWebAuthnUserByUserID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*model.WebAuthnUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadWebAuthnUserByUserID indicates an expected call of LoadWebAuthnUserByUserID.
+func (mr *MockStorageMockRecorder) LoadWebAuthnUserByUserID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebAuthnUserByUserID", reflect.TypeOf((*MockStorage)(nil).LoadWebAuthnUserByUserID), arg0, arg1, arg2)
+}
+
+// RegenerateSession mocks base method.
+func (m *MockStorage) RegenerateSession(arg0 context.Context, arg1, arg2 []byte, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateSession", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegenerateSession indicates an expected call of RegenerateSession.
+func (mr *MockStorageMockRecorder) RegenerateSession(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateSession", reflect.TypeOf((*MockStorage)(nil).RegenerateSession), arg0, arg1, arg2, arg3)
+}
+
+// ReleaseLeaderElectionLease mocks base method.
+func (m *MockStorage) ReleaseLeaderElectionLease(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLeaderElectionLease", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLeaderElectionLease indicates an expected call of ReleaseLeaderElectionLease.
+func (mr *MockStorageMockRecorder) ReleaseLeaderElectionLease(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLeaderElectionLease", reflect.TypeOf((*MockStorage)(nil).ReleaseLeaderElectionLease), arg0, arg1, arg2)
+}
+
+// RespondPushChallenge mocks base method.
+func (m *MockStorage) RespondPushChallenge(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 int, arg4 time.Time, arg5 model.NullIP) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RespondPushChallenge", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RespondPushChallenge indicates an expected call of RespondPushChallenge.
+func (mr *MockStorageMockRecorder) RespondPushChallenge(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondPushChallenge", reflect.TypeOf((*MockStorage)(nil).RespondPushChallenge), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// RevokeDeviceCertificate mocks base method.
+func (m *MockStorage) RevokeDeviceCertificate(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeDeviceCertificate", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeDeviceCertificate indicates an expected call of RevokeDeviceCertificate.
+func (mr *MockStorageMockRecorder) RevokeDeviceCertificate(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeDeviceCertificate", reflect.TypeOf((*MockStorage)(nil).RevokeDeviceCertificate), arg0, arg1, arg2, arg3)
+}
+
+// RevokeIdentityVerification mocks base method.
+func (m *MockStorage) RevokeIdentityVerification(arg0 context.Context, arg1 string, arg2 model.NullIP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeIdentityVerification", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeIdentityVerification indicates an expected call of RevokeIdentityVerification.
+func (mr *MockStorageMockRecorder) RevokeIdentityVerification(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).RevokeIdentityVerification), arg0, arg1, arg2)
+}
+
+// RevokeMobileDevice mocks base method.
+func (m *MockStorage) RevokeMobileDevice(arg0 context.Context, arg1 uuid.UUID, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeMobileDevice", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeMobileDevice indicates an expected call of RevokeMobileDevice.
+func (mr *MockStorageMockRecorder) RevokeMobileDevice(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeMobileDevice", reflect.TypeOf((*MockStorage)(nil).RevokeMobileDevice), arg0, arg1, arg2)
+}
+
+// RevokeOAuth2PARContext mocks base method.
+func (m *MockStorage) RevokeOAuth2PARContext(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2PARContext", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2PARContext indicates an expected call of RevokeOAuth2PARContext.
+func (mr *MockStorageMockRecorder) RevokeOAuth2PARContext(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2PARContext), arg0, arg1)
+}
+
+// RevokeOAuth2Session mocks base method.
+func (m *MockStorage) RevokeOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2Session", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2Session indicates an expected call of RevokeOAuth2Session.
+func (mr *MockStorageMockRecorder) RevokeOAuth2Session(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2Session", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2Session), arg0, arg1, arg2)
+}
+
+// RevokeOAuth2SessionByRequestID mocks base method.
+func (m *MockStorage) RevokeOAuth2SessionByRequestID(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2SessionByRequestID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2SessionByRequestID indicates an expected call of RevokeOAuth2SessionByRequestID.
+func (mr *MockStorageMockRecorder) RevokeOAuth2SessionByRequestID(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2SessionByRequestID), arg0, arg1, arg2)
+}
+
+// RevokeOneTimeCode mocks base method.
+func (m *MockStorage) RevokeOneTimeCode(arg0 context.Context, arg1 uuid.UUID, arg2 model.IP) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOneTimeCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOneTimeCode indicates an expected call of RevokeOneTimeCode.
+func (mr *MockStorageMockRecorder) RevokeOneTimeCode(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOneTimeCode", reflect.TypeOf((*MockStorage)(nil).RevokeOneTimeCode), arg0, arg1, arg2)
+}
+
+// RevokeSAMLSession mocks base method.
+func (m *MockStorage) RevokeSAMLSession(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSAMLSession", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSAMLSession indicates an expected call of RevokeSAMLSession.
+func (mr *MockStorageMockRecorder) RevokeSAMLSession(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSAMLSession", reflect.TypeOf((*MockStorage)(nil).RevokeSAMLSession), arg0, arg1, arg2)
+}
+
+// RevokeSAMLSessionsByUsername mocks base method.
+func (m *MockStorage) RevokeSAMLSessionsByUsername(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSAMLSessionsByUsername", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSAMLSessionsByUsername indicates an expected call of RevokeSAMLSessionsByUsername.
+func (mr *MockStorageMockRecorder) RevokeSAMLSessionsByUsername(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSAMLSessionsByUsername", reflect.TypeOf((*MockStorage)(nil).RevokeSAMLSessionsByUsername), arg0, arg1, arg2)
+}
+
+// Rollback mocks base method.
+func (m *MockStorage) Rollback(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockStorageMockRecorder) Rollback(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStorage)(nil).Rollback), arg0)
+}
+
+// SaveAppPassword mocks base method.
+func (m *MockStorage) SaveAppPassword(arg0 context.Context, arg1 model.AppPassword) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAppPassword", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAppPassword indicates an expected call of SaveAppPassword.
+func (mr *MockStorageMockRecorder) SaveAppPassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAppPassword", reflect.TypeOf((*MockStorage)(nil).SaveAppPassword), arg0, arg1)
+}
+
+// SaveAuditEvent mocks base method.
+func (m *MockStorage) SaveAuditEvent(arg0 context.Context, arg1 model.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuditEvent", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuditEvent indicates an expected call of SaveAuditEvent.
+func (mr *MockStorageMockRecorder) SaveAuditEvent(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebAuthnUser", reflect.TypeOf((*MockStorage)(nil).LoadWebAuthnUser), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuditEvent", reflect.TypeOf((*MockStorage)(nil).SaveAuditEvent), arg0, arg1)
 }
 
-// RevokeIdentityVerification mocks base method.
-func (m *MockStorage) RevokeIdentityVerification(arg0 context.Context, arg1 string, arg2 model.NullIP) error {
+// SaveConfigurationFingerprint mocks base method.
+func (m *MockStorage) SaveConfigurationFingerprint(arg0 context.Context, arg1 model.ConfigurationFingerprint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeIdentityVerification", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "SaveConfigurationFingerprint", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeIdentityVerification indicates an expected call of RevokeIdentityVerification.
-func (mr *MockStorageMockRecorder) RevokeIdentityVerification(arg0, arg1, arg2 any) *gomock.Call {
+// SaveConfigurationFingerprint indicates an expected call of SaveConfigurationFingerprint.
+func (mr *MockStorageMockRecorder) SaveConfigurationFingerprint(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).RevokeIdentityVerification), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConfigurationFingerprint", reflect.TypeOf((*MockStorage)(nil).SaveConfigurationFingerprint), arg0, arg1)
 }
 
-// RevokeOAuth2PARContext mocks base method.
-func (m *MockStorage) RevokeOAuth2PARContext(arg0 context.Context, arg1 string) error {
+// SaveDeviceCertificate mocks base method.
+func (m *MockStorage) SaveDeviceCertificate(arg0 context.Context, arg1 model.DeviceCertificate) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeOAuth2PARContext", arg0, arg1)
+	ret := m.ctrl.Call(m, "SaveDeviceCertificate", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeOAuth2PARContext indicates an expected call of RevokeOAuth2PARContext.
-func (mr *MockStorageMockRecorder) RevokeOAuth2PARContext(arg0, arg1 any) *gomock.Call {
+// SaveDeviceCertificate indicates an expected call of SaveDeviceCertificate.
+func (mr *MockStorageMockRecorder) SaveDeviceCertificate(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2PARContext), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDeviceCertificate", reflect.TypeOf((*MockStorage)(nil).SaveDeviceCertificate), arg0, arg1)
 }
 
-// RevokeOAuth2Session mocks base method.
-func (m *MockStorage) RevokeOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
+// SaveDirectoryGroup mocks base method.
+func (m *MockStorage) SaveDirectoryGroup(arg0 context.Context, arg1 model.DirectoryGroup) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeOAuth2Session", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "SaveDirectoryGroup", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeOAuth2Session indicates an expected call of RevokeOAuth2Session.
-func (mr *MockStorageMockRecorder) RevokeOAuth2Session(arg0, arg1, arg2 any) *gomock.Call {
+// SaveDirectoryGroup indicates an expected call of SaveDirectoryGroup.
+func (mr *MockStorageMockRecorder) SaveDirectoryGroup(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2Session", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2Session), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDirectoryGroup", reflect.TypeOf((*MockStorage)(nil).SaveDirectoryGroup), arg0, arg1)
 }
 
-// RevokeOAuth2SessionByRequestID mocks base method.
-func (m *MockStorage) RevokeOAuth2SessionByRequestID(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
+// SaveDirectoryGroupMembers mocks base method.
+func (m *MockStorage) SaveDirectoryGroupMembers(arg0 context.Context, arg1 uuid.UUID, arg2 []uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeOAuth2SessionByRequestID", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "SaveDirectoryGroupMembers", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeOAuth2SessionByRequestID indicates an expected call of RevokeOAuth2SessionByRequestID.
-func (mr *MockStorageMockRecorder) RevokeOAuth2SessionByRequestID(arg0, arg1, arg2 any) *gomock.Call {
+// SaveDirectoryGroupMembers indicates an expected call of SaveDirectoryGroupMembers.
+func (mr *MockStorageMockRecorder) SaveDirectoryGroupMembers(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2SessionByRequestID), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDirectoryGroupMembers", reflect.TypeOf((*MockStorage)(nil).SaveDirectoryGroupMembers), arg0, arg1, arg2)
 }
 
-// RevokeOneTimeCode mocks base method.
-func (m *MockStorage) RevokeOneTimeCode(arg0 context.Context, arg1 uuid.UUID, arg2 model.IP) error {
+// SaveDirectoryUser mocks base method.
+func (m *MockStorage) SaveDirectoryUser(arg0 context.Context, arg1 model.DirectoryUser) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RevokeOneTimeCode", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "SaveDirectoryUser", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// RevokeOneTimeCode indicates an expected call of RevokeOneTimeCode.
-func (mr *MockStorageMockRecorder) RevokeOneTimeCode(arg0, arg1, arg2 any) *gomock.Call {
+// SaveDirectoryUser indicates an expected call of SaveDirectoryUser.
+func (mr *MockStorageMockRecorder) SaveDirectoryUser(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOneTimeCode", reflect.TypeOf((*MockStorage)(nil).RevokeOneTimeCode), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDirectoryUser", reflect.TypeOf((*MockStorage)(nil).SaveDirectoryUser), arg0, arg1)
 }
 
-// Rollback mocks base method.
-func (m *MockStorage) Rollback(arg0 context.Context) error {
+// SaveDirectoryUserGroups mocks base method.
+func (m *MockStorage) SaveDirectoryUserGroups(arg0 context.Context, arg1 uuid.UUID, arg2 []uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Rollback", arg0)
+	ret := m.ctrl.Call(m, "SaveDirectoryUserGroups", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// Rollback indicates an expected call of Rollback.
-func (mr *MockStorageMockRecorder) Rollback(arg0 any) *gomock.Call {
+// SaveDirectoryUserGroups indicates an expected call of SaveDirectoryUserGroups.
+func (mr *MockStorageMockRecorder) SaveDirectoryUserGroups(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStorage)(nil).Rollback), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDirectoryUserGroups", reflect.TypeOf((*MockStorage)(nil).SaveDirectoryUserGroups), arg0, arg1, arg2)
 }
 
 // SaveIdentityVerification mocks base method.
@@ -686,6 +1846,20 @@ func (mr *MockStorageMockRecorder) SaveIdentityVerification(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdentityVerification", reflect.TypeOf((*MockStorage)(nil).SaveIdentityVerification), arg0, arg1)
 }
 
+// SaveMobileDevice mocks base method.
+func (m *MockStorage) SaveMobileDevice(arg0 context.Context, arg1 model.MobileDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveMobileDevice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveMobileDevice indicates an expected call of SaveMobileDevice.
+func (mr *MockStorageMockRecorder) SaveMobileDevice(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveMobileDevice", reflect.TypeOf((*MockStorage)(nil).SaveMobileDevice), arg0, arg1)
+}
+
 // SaveOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) SaveOAuth2BlacklistedJTI(arg0 context.Context, arg1 model.OAuth2BlacklistedJTI) error {
 	m.ctrl.T.Helper()
@@ -700,6 +1874,20 @@ func (mr *MockStorageMockRecorder) SaveOAuth2BlacklistedJTI(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2BlacklistedJTI", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2BlacklistedJTI), arg0, arg1)
 }
 
+// SaveOAuth2CIBARequest mocks base method.
+func (m *MockStorage) SaveOAuth2CIBARequest(arg0 context.Context, arg1 model.OAuth2CIBARequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2CIBARequest", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2CIBARequest indicates an expected call of SaveOAuth2CIBARequest.
+func (mr *MockStorageMockRecorder) SaveOAuth2CIBARequest(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2CIBARequest", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2CIBARequest), arg0, arg1)
+}
+
 // SaveOAuth2ConsentPreConfiguration mocks base method.
 func (m *MockStorage) SaveOAuth2ConsentPreConfiguration(arg0 context.Context, arg1 model.OAuth2ConsentPreConfig) (int64, error) {
 	m.ctrl.T.Helper()
@@ -771,6 +1959,34 @@ func (mr *MockStorageMockRecorder) SaveOAuth2ConsentSessionSubject(arg0, arg1 an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2ConsentSessionSubject", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2ConsentSessionSubject), arg0, arg1)
 }
 
+// SaveOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) SaveOAuth2DeviceCodeSession(arg0 context.Context, arg1 model.OAuth2DeviceCodeSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DeviceCodeSession indicates an expected call of SaveOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) SaveOAuth2DeviceCodeSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DeviceCodeSession), arg0, arg1)
+}
+
+// SaveOAuth2DynamicClient mocks base method.
+func (m *MockStorage) SaveOAuth2DynamicClient(arg0 context.Context, arg1 model.OAuth2DynamicClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DynamicClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DynamicClient indicates an expected call of SaveOAuth2DynamicClient.
+func (mr *MockStorageMockRecorder) SaveOAuth2DynamicClient(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DynamicClient", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DynamicClient), arg0, arg1)
+}
+
 // SaveOAuth2PARContext mocks base method.
 func (m *MockStorage) SaveOAuth2PARContext(arg0 context.Context, arg1 model.OAuth2PARContext) error {
 	m.ctrl.T.Helper()
@@ -799,6 +2015,20 @@ func (mr *MockStorageMockRecorder) SaveOAuth2Session(arg0, arg1, arg2 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2Session", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2Session), arg0, arg1, arg2)
 }
 
+// SaveOAuth2TokenExchangeLineage mocks base method.
+func (m *MockStorage) SaveOAuth2TokenExchangeLineage(arg0 context.Context, arg1 model.OAuth2TokenExchangeLineage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2TokenExchangeLineage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2TokenExchangeLineage indicates an expected call of SaveOAuth2TokenExchangeLineage.
+func (mr *MockStorageMockRecorder) SaveOAuth2TokenExchangeLineage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2TokenExchangeLineage", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2TokenExchangeLineage), arg0, arg1)
+}
+
 // SaveOneTimeCode mocks base method.
 func (m *MockStorage) SaveOneTimeCode(arg0 context.Context, arg1 model.OneTimeCode) (string, error) {
 	m.ctrl.T.Helper()
@@ -814,6 +2044,20 @@ func (mr *MockStorageMockRecorder) SaveOneTimeCode(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOneTimeCode", reflect.TypeOf((*MockStorage)(nil).SaveOneTimeCode), arg0, arg1)
 }
 
+// SavePhoneNumber mocks base method.
+func (m *MockStorage) SavePhoneNumber(arg0 context.Context, arg1 model.PhoneNumber) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePhoneNumber", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePhoneNumber indicates an expected call of SavePhoneNumber.
+func (mr *MockStorageMockRecorder) SavePhoneNumber(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePhoneNumber", reflect.TypeOf((*MockStorage)(nil).SavePhoneNumber), arg0, arg1)
+}
+
 // SavePreferred2FAMethod mocks base method.
 func (m *MockStorage) SavePreferred2FAMethod(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -842,6 +2086,62 @@ func (mr *MockStorageMockRecorder) SavePreferredDuoDevice(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).SavePreferredDuoDevice), arg0, arg1)
 }
 
+// SavePushChallenge mocks base method.
+func (m *MockStorage) SavePushChallenge(arg0 context.Context, arg1 model.PushChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePushChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePushChallenge indicates an expected call of SavePushChallenge.
+func (mr *MockStorageMockRecorder) SavePushChallenge(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePushChallenge", reflect.TypeOf((*MockStorage)(nil).SavePushChallenge), arg0, arg1)
+}
+
+// SaveRecoveryCodes mocks base method.
+func (m *MockStorage) SaveRecoveryCodes(arg0 context.Context, arg1 string, arg2 []model.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRecoveryCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRecoveryCodes indicates an expected call of SaveRecoveryCodes.
+func (mr *MockStorageMockRecorder) SaveRecoveryCodes(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).SaveRecoveryCodes), arg0, arg1, arg2)
+}
+
+// SaveSAMLSession mocks base method.
+func (m *MockStorage) SaveSAMLSession(arg0 context.Context, arg1 model.SAMLSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSAMLSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSAMLSession indicates an expected call of SaveSAMLSession.
+func (mr *MockStorageMockRecorder) SaveSAMLSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSAMLSession", reflect.TypeOf((*MockStorage)(nil).SaveSAMLSession), arg0, arg1)
+}
+
+// SaveSession mocks base method.
+func (m *MockStorage) SaveSession(arg0 context.Context, arg1, arg2 []byte, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSession", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSession indicates an expected call of SaveSession.
+func (mr *MockStorageMockRecorder) SaveSession(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSession", reflect.TypeOf((*MockStorage)(nil).SaveSession), arg0, arg1, arg2, arg3)
+}
+
 // SaveTOTPConfiguration mocks base method.
 func (m *MockStorage) SaveTOTPConfiguration(arg0 context.Context, arg1 model.TOTPConfiguration) error {
 	m.ctrl.T.Helper()
@@ -884,6 +2184,34 @@ func (mr *MockStorageMockRecorder) SaveUserOpaqueIdentifier(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserOpaqueIdentifier", reflect.TypeOf((*MockStorage)(nil).SaveUserOpaqueIdentifier), arg0, arg1)
 }
 
+// SaveUserSessionIndex mocks base method.
+func (m *MockStorage) SaveUserSessionIndex(arg0 context.Context, arg1 model.UserSessionIndex) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSessionIndex", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserSessionIndex indicates an expected call of SaveUserSessionIndex.
+func (mr *MockStorageMockRecorder) SaveUserSessionIndex(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSessionIndex", reflect.TypeOf((*MockStorage)(nil).SaveUserSessionIndex), arg0, arg1)
+}
+
+// SaveUserSessionRevocation mocks base method.
+func (m *MockStorage) SaveUserSessionRevocation(arg0 context.Context, arg1 []byte, arg2, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserSessionRevocation", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserSessionRevocation indicates an expected call of SaveUserSessionRevocation.
+func (mr *MockStorageMockRecorder) SaveUserSessionRevocation(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserSessionRevocation", reflect.TypeOf((*MockStorage)(nil).SaveUserSessionRevocation), arg0, arg1, arg2, arg3)
+}
+
 // SaveWebAuthnCredential mocks base method.
 func (m *MockStorage) SaveWebAuthnCredential(arg0 context.Context, arg1 model.WebAuthnCredential) error {
 	m.ctrl.T.Helper()
@@ -912,6 +2240,21 @@ func (mr *MockStorageMockRecorder) SaveWebAuthnUser(arg0, arg1 any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveWebAuthnUser", reflect.TypeOf((*MockStorage)(nil).SaveWebAuthnUser), arg0, arg1)
 }
 
+// SchemaCompatibleVersion mocks base method.
+func (m *MockStorage) SchemaCompatibleVersion(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaCompatibleVersion", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchemaCompatibleVersion indicates an expected call of SchemaCompatibleVersion.
+func (mr *MockStorageMockRecorder) SchemaCompatibleVersion(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaCompatibleVersion", reflect.TypeOf((*MockStorage)(nil).SchemaCompatibleVersion), arg0)
+}
+
 // SchemaEncryptionChangeKey mocks base method.
 func (m *MockStorage) SchemaEncryptionChangeKey(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -941,6 +2284,21 @@ func (mr *MockStorageMockRecorder) SchemaEncryptionCheckKey(arg0, arg1 any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionCheckKey", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionCheckKey), arg0, arg1)
 }
 
+// SchemaEncryptionRotateKey mocks base method.
+func (m *MockStorage) SchemaEncryptionRotateKey(arg0 context.Context, arg1 int) (storage.EncryptionRotateKeyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaEncryptionRotateKey", arg0, arg1)
+	ret0, _ := ret[0].(storage.EncryptionRotateKeyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchemaEncryptionRotateKey indicates an expected call of SchemaEncryptionRotateKey.
+func (mr *MockStorageMockRecorder) SchemaEncryptionRotateKey(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionRotateKey", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionRotateKey), arg0, arg1)
+}
+
 // SchemaLatestVersion mocks base method.
 func (m *MockStorage) SchemaLatestVersion() (int, error) {
 	m.ctrl.T.Helper()
@@ -1059,6 +2417,160 @@ func (mr *MockStorageMockRecorder) StartupCheck() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartupCheck", reflect.TypeOf((*MockStorage)(nil).StartupCheck))
 }
 
+// UpdateAppPasswordSignIn mocks base method.
+func (m *MockStorage) UpdateAppPasswordSignIn(arg0 context.Context, arg1 int, arg2 sql.NullTime) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAppPasswordSignIn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAppPasswordSignIn indicates an expected call of UpdateAppPasswordSignIn.
+func (mr *MockStorageMockRecorder) UpdateAppPasswordSignIn(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAppPasswordSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateAppPasswordSignIn), arg0, arg1, arg2)
+}
+
+// UpdateDirectoryGroup mocks base method.
+func (m *MockStorage) UpdateDirectoryGroup(arg0 context.Context, arg1 model.DirectoryGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDirectoryGroup", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDirectoryGroup indicates an expected call of UpdateDirectoryGroup.
+func (mr *MockStorageMockRecorder) UpdateDirectoryGroup(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDirectoryGroup", reflect.TypeOf((*MockStorage)(nil).UpdateDirectoryGroup), arg0, arg1)
+}
+
+// UpdateDirectoryUser mocks base method.
+func (m *MockStorage) UpdateDirectoryUser(arg0 context.Context, arg1 model.DirectoryUser) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDirectoryUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDirectoryUser indicates an expected call of UpdateDirectoryUser.
+func (mr *MockStorageMockRecorder) UpdateDirectoryUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDirectoryUser", reflect.TypeOf((*MockStorage)(nil).UpdateDirectoryUser), arg0, arg1)
+}
+
+// UpdateDirectoryUserPassword mocks base method.
+func (m *MockStorage) UpdateDirectoryUserPassword(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDirectoryUserPassword", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDirectoryUserPassword indicates an expected call of UpdateDirectoryUserPassword.
+func (mr *MockStorageMockRecorder) UpdateDirectoryUserPassword(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDirectoryUserPassword", reflect.TypeOf((*MockStorage)(nil).UpdateDirectoryUserPassword), arg0, arg1, arg2)
+}
+
+// UpdateMobileDevicePush mocks base method.
+func (m *MockStorage) UpdateMobileDevicePush(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMobileDevicePush", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMobileDevicePush indicates an expected call of UpdateMobileDevicePush.
+func (mr *MockStorageMockRecorder) UpdateMobileDevicePush(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMobileDevicePush", reflect.TypeOf((*MockStorage)(nil).UpdateMobileDevicePush), arg0, arg1, arg2, arg3)
+}
+
+// UpdateMobileDeviceTokens mocks base method.
+func (m *MockStorage) UpdateMobileDeviceTokens(arg0 context.Context, arg1 model.MobileDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMobileDeviceTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMobileDeviceTokens indicates an expected call of UpdateMobileDeviceTokens.
+func (mr *MockStorageMockRecorder) UpdateMobileDeviceTokens(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMobileDeviceTokens", reflect.TypeOf((*MockStorage)(nil).UpdateMobileDeviceTokens), arg0, arg1)
+}
+
+// UpdateOAuth2CIBARequestCheckedAt mocks base method.
+func (m *MockStorage) UpdateOAuth2CIBARequestCheckedAt(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2CIBARequestCheckedAt", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2CIBARequestCheckedAt indicates an expected call of UpdateOAuth2CIBARequestCheckedAt.
+func (mr *MockStorageMockRecorder) UpdateOAuth2CIBARequestCheckedAt(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2CIBARequestCheckedAt", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2CIBARequestCheckedAt), arg0, arg1, arg2)
+}
+
+// UpdateOAuth2CIBARequestNotified mocks base method.
+func (m *MockStorage) UpdateOAuth2CIBARequestNotified(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2CIBARequestNotified", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2CIBARequestNotified indicates an expected call of UpdateOAuth2CIBARequestNotified.
+func (mr *MockStorageMockRecorder) UpdateOAuth2CIBARequestNotified(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2CIBARequestNotified", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2CIBARequestNotified), arg0, arg1)
+}
+
+// UpdateOAuth2CIBARequestStatus mocks base method.
+func (m *MockStorage) UpdateOAuth2CIBARequestStatus(arg0 context.Context, arg1 string, arg2 int, arg3 sql.NullTime) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2CIBARequestStatus", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2CIBARequestStatus indicates an expected call of UpdateOAuth2CIBARequestStatus.
+func (mr *MockStorageMockRecorder) UpdateOAuth2CIBARequestStatus(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2CIBARequestStatus", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2CIBARequestStatus), arg0, arg1, arg2, arg3)
+}
+
+// UpdateOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) UpdateOAuth2DeviceCodeSession(arg0 context.Context, arg1 model.OAuth2DeviceCodeSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2DeviceCodeSession indicates an expected call of UpdateOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) UpdateOAuth2DeviceCodeSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2DeviceCodeSession), arg0, arg1)
+}
+
+// UpdateOAuth2DynamicClient mocks base method.
+func (m *MockStorage) UpdateOAuth2DynamicClient(arg0 context.Context, arg1 model.OAuth2DynamicClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2DynamicClient", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2DynamicClient indicates an expected call of UpdateOAuth2DynamicClient.
+func (mr *MockStorageMockRecorder) UpdateOAuth2DynamicClient(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2DynamicClient", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2DynamicClient), arg0, arg1)
+}
+
 // UpdateOAuth2PARContext mocks base method.
 func (m *MockStorage) UpdateOAuth2PARContext(arg0 context.Context, arg1 model.OAuth2PARContext) error {
 	m.ctrl.T.Helper()
@@ -1073,6 +2585,34 @@ func (mr *MockStorageMockRecorder) UpdateOAuth2PARContext(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2PARContext), arg0, arg1)
 }
 
+// UpdatePhoneNumberSignIn mocks base method.
+func (m *MockStorage) UpdatePhoneNumberSignIn(arg0 context.Context, arg1 int, arg2 sql.NullTime) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhoneNumberSignIn", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhoneNumberSignIn indicates an expected call of UpdatePhoneNumberSignIn.
+func (mr *MockStorageMockRecorder) UpdatePhoneNumberSignIn(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhoneNumberSignIn", reflect.TypeOf((*MockStorage)(nil).UpdatePhoneNumberSignIn), arg0, arg1, arg2)
+}
+
+// UpdateTOTPConfigurationDrift mocks base method.
+func (m *MockStorage) UpdateTOTPConfigurationDrift(arg0 context.Context, arg1, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTOTPConfigurationDrift", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTOTPConfigurationDrift indicates an expected call of UpdateTOTPConfigurationDrift.
+func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationDrift(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationDrift", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationDrift), arg0, arg1, arg2)
+}
+
 // UpdateTOTPConfigurationSignIn mocks base method.
 func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 sql.NullTime) error {
 	m.ctrl.T.Helper()
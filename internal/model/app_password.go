@@ -0,0 +1,88 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewAppPassword returns a new AppPassword for the user with the given raw value which is permitted to authenticate
+// the user to the given domains.
+func NewAppPassword(publicID uuid.UUID, username, name, password string, domains []string, now time.Time) AppPassword {
+	return AppPassword{
+		PublicID:  publicID,
+		CreatedAt: now,
+		Username:  username,
+		Name:      name,
+		Domains:   domains,
+		Password:  password,
+	}
+}
+
+// AppPassword represents an app password row in the database. An app password is a random password which a user
+// generates for a specific application which can only authenticate with the Authorization header, and which is only
+// permitted to authenticate the user to the domains it's scoped to. Only the signature of the password is stored and
+// the raw value is only available when the password is generated.
+type AppPassword struct {
+	ID         int                      `db:"id"`
+	PublicID   uuid.UUID                `db:"public_id"`
+	CreatedAt  time.Time                `db:"created_at"`
+	LastUsedAt sql.NullTime             `db:"last_used_at"`
+	Username   string                   `db:"username"`
+	Name       string                   `db:"name"`
+	Domains    StringSlicePipeDelimited `db:"domains"`
+	Password   string                   `db:"-"`
+	Signature  string                   `db:"signature"`
+}
+
+// IsPermitted returns true if the AppPassword is permitted to authenticate the user to the given domain. The domains
+// of the AppPassword either match the domain exactly, or match any subdomain of the domain when they have the '*.'
+// prefix.
+func (p *AppPassword) IsPermitted(domain string) bool {
+	domain = strings.ToLower(domain)
+
+	for _, d := range p.Domains {
+		d = strings.ToLower(d)
+
+		switch {
+		case d == domain:
+			return true
+		case strings.HasPrefix(d, "*.") && strings.HasSuffix(domain, d[1:]):
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppPasswordJSON is the JSON representation for an AppPassword.
+type AppPasswordJSON struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Domains    []string   `json:"domains"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// MarshalJSON returns the AppPassword in a JSON friendly manner.
+func (p AppPassword) MarshalJSON() (data []byte, err error) {
+	o := AppPasswordJSON{
+		ID:        p.PublicID,
+		Name:      p.Name,
+		Domains:   p.Domains,
+		CreatedAt: p.CreatedAt,
+	}
+
+	if o.Domains == nil {
+		o.Domains = []string{}
+	}
+
+	if p.LastUsedAt.Valid {
+		o.LastUsedAt = &p.LastUsedAt.Time
+	}
+
+	return json.Marshal(o)
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppPasswordIsPermitted(t *testing.T) {
+	password := AppPassword{Domains: []string{"mail.example.com", "*.apps.example.com"}}
+
+	testCases := []struct {
+		name     string
+		domain   string
+		expected bool
+	}{
+		{"ShouldPermitExact", "mail.example.com", true},
+		{"ShouldPermitExactCaseInsensitive", "MAIL.example.com", true},
+		{"ShouldPermitWildcardSubdomain", "calendar.apps.example.com", true},
+		{"ShouldPermitWildcardNestedSubdomain", "a.b.apps.example.com", true},
+		{"ShouldNotPermitWildcardRoot", "apps.example.com", false},
+		{"ShouldNotPermitOtherSubdomain", "www.example.com", false},
+		{"ShouldNotPermitSuffix", "badmail.example.com", false},
+		{"ShouldNotPermitEmpty", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, password.IsPermitted(tc.domain))
+		})
+	}
+}
+
+func TestAppPasswordMarshalJSON(t *testing.T) {
+	id := uuid.MustParse("a9e1f4f0-5bde-4e6a-9e4e-6f1c9cc4f4a1")
+	now := time.Unix(1700000000, 0).UTC()
+
+	password := NewAppPassword(id, "john", "Mail", "authelia_ap_abc", []string{"mail.example.com"}, now)
+	password.Signature = "signature"
+
+	data, err := json.Marshal(password)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"id":"a9e1f4f0-5bde-4e6a-9e4e-6f1c9cc4f4a1","name":"Mail","domains":["mail.example.com"],"created_at":"2023-11-14T22:13:20Z"}`, string(data))
+
+	password.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+	password.Domains = nil
+
+	data, err = json.Marshal(password)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"id":"a9e1f4f0-5bde-4e6a-9e4e-6f1c9cc4f4a1","name":"Mail","domains":[],"created_at":"2023-11-14T22:13:20Z","last_used_at":"2023-11-14T22:13:20Z"}`, string(data))
+}
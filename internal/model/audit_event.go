@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+// AuditEvent represents a security relevant event which has been recorded in the audit trail.
+type AuditEvent struct {
+	ID            int       `db:"id"`
+	EventID       string    `db:"event_id"`
+	Time          time.Time `db:"time"`
+	Type          string    `db:"type"`
+	Result        string    `db:"result"`
+	Username      string    `db:"username"`
+	RemoteIP      string    `db:"remote_ip"`
+	UserAgent     string    `db:"user_agent"`
+	RequestMethod string    `db:"request_method"`
+	RequestHost   string    `db:"request_host"`
+	RequestPath   string    `db:"request_path"`
+	TraceID       string    `db:"trace_id"`
+	Details       string    `db:"details"`
+}
+
+// AuditEventFilter describes the criteria used to select audit events. Empty values match all events.
+type AuditEventFilter struct {
+	Username string
+	Type     string
+	Since    time.Time
+	Until    time.Time
+}
@@ -16,3 +16,9 @@ type AuthenticationAttempt struct {
 	RequestURI    string    `db:"request_uri"`
 	RequestMethod string    `db:"request_method"`
 }
+
+// AuthenticationAttemptFilter describes the criteria used to select authentication attempts. Empty values match all
+// attempts.
+type AuthenticationAttemptFilter struct {
+	Type string
+}
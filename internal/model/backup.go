@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+// BackupMetadata represents the metadata of a backup archive which describes the point in time the backup was created.
+type BackupMetadata struct {
+	Version               string    `yaml:"version" json:"version" jsonschema:"title=Version" jsonschema_description:"The version of Authelia which created the backup."`
+	SchemaVersion         int       `yaml:"schema_version" json:"schema_version" jsonschema:"title=Schema Version" jsonschema_description:"The storage schema version at the time the backup was created."`
+	ConfigurationHash     string    `yaml:"configuration_hash" json:"configuration_hash" jsonschema:"title=Configuration Hash" jsonschema_description:"The fingerprint of the effective configuration at the time the backup was created."`
+	CreatedAt             time.Time `yaml:"created_at" json:"created_at" jsonschema:"title=Created At" jsonschema_description:"The time the backup was created."`
+	TOTPConfigurations    int       `yaml:"totp_configurations" json:"totp_configurations" jsonschema:"title=TOTP Configurations" jsonschema_description:"The number of TOTP configurations in the backup."`
+	WebAuthnCredentials   int       `yaml:"webauthn_credentials" json:"webauthn_credentials" jsonschema:"title=WebAuthn Credentials" jsonschema_description:"The number of WebAuthn credentials in the backup."`
+	UserOpaqueIdentifiers int       `yaml:"user_opaque_identifiers" json:"user_opaque_identifiers" jsonschema:"title=User Opaque Identifiers" jsonschema_description:"The number of user opaque identifiers in the backup."`
+}
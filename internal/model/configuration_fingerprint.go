@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// ConfigurationFingerprint represents the fingerprint of the effective configuration of an instance.
+type ConfigurationFingerprint struct {
+	ID          int       `db:"id"`
+	Instance    string    `db:"instance"`
+	Fingerprint string    `db:"fingerprint"`
+	Version     string    `db:"version"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
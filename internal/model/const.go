@@ -20,11 +20,35 @@ const (
 
 	// SecondFactorMethodDuo method using Duo application to receive push notifications.
 	SecondFactorMethodDuo = "mobile_push"
+
+	// SecondFactorMethodSMS method using One-Time Codes delivered to a verified phone number by SMS or voice call.
+	SecondFactorMethodSMS = "sms"
+
+	// SecondFactorMethodEmail method using One-Time Codes delivered to the email address of the user.
+	SecondFactorMethodEmail = "email"
+)
+
+// OAuth2CIBARequest status values.
+const (
+	// OAuth2CIBAStatusPending indicates the user has not yet responded to the out-of-band authentication.
+	OAuth2CIBAStatusPending = iota
+
+	// OAuth2CIBAStatusApproved indicates the user approved the out-of-band authentication.
+	OAuth2CIBAStatusApproved
+
+	// OAuth2CIBAStatusDenied indicates the user denied the out-of-band authentication or it failed.
+	OAuth2CIBAStatusDenied
 )
 
 var (
 	reSemanticVersion = regexp.MustCompile(`^v?(?P<Major>0|[1-9]\d*)\.(?P<Minor>0|[1-9]\d*)\.(?P<Patch>0|[1-9]\d*)(?:-(?P<PreRelease>(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+(?P<Metadata>[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
 	reToken64         = regexp.MustCompile(`^[a-zA-Z0-9_.~+/=-]+$`)
+	rePhoneNumberE164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+)
+
+const (
+	// phoneNumberUnmaskedDigits is the number of trailing digits of a phone number which are not masked.
+	phoneNumberUnmaskedDigits = 4
 )
 
 const (
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// DeviceCertificate represents a client certificate issued to a managed device row in the database.
+type DeviceCertificate struct {
+	ID        int          `db:"id"`
+	Serial    string       `db:"serial"`
+	Username  string       `db:"username"`
+	Name      string       `db:"name"`
+	IssuedAt  time.Time    `db:"issued_at"`
+	ExpiresAt time.Time    `db:"expires_at"`
+	RevokedAt sql.NullTime `db:"revoked_at"`
+}
+
+// IsRevoked returns true if the DeviceCertificate has been revoked.
+func (c *DeviceCertificate) IsRevoked() bool {
+	return c.RevokedAt.Valid
+}
+
+// IsExpired returns true if the DeviceCertificate has expired at the given time.
+func (c *DeviceCertificate) IsExpired(now time.Time) bool {
+	return !c.ExpiresAt.After(now)
+}
+
+// DeviceCertificateJSON is the JSON representation for a DeviceCertificate.
+type DeviceCertificateJSON struct {
+	Serial    string     `json:"serial"`
+	Name      string     `json:"name"`
+	IssuedAt  time.Time  `json:"issued_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// MarshalJSON returns the DeviceCertificate in a JSON friendly manner.
+func (c DeviceCertificate) MarshalJSON() (data []byte, err error) {
+	o := DeviceCertificateJSON{
+		Serial:    c.Serial,
+		Name:      c.Name,
+		IssuedAt:  c.IssuedAt,
+		ExpiresAt: c.ExpiresAt,
+	}
+
+	if c.RevokedAt.Valid {
+		o.RevokedAt = &c.RevokedAt.Time
+	}
+
+	return json.Marshal(o)
+}
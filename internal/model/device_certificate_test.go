@@ -0,0 +1,42 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceCertificate(t *testing.T) {
+	issued := time.Unix(1700000000, 0).UTC()
+
+	certificate := DeviceCertificate{
+		ID:        1,
+		Serial:    "0a1b2c",
+		Username:  "john",
+		Name:      "laptop",
+		IssuedAt:  issued,
+		ExpiresAt: issued.Add(time.Hour),
+	}
+
+	assert.False(t, certificate.IsRevoked())
+	assert.False(t, certificate.IsExpired(issued))
+	assert.True(t, certificate.IsExpired(issued.Add(time.Hour)))
+
+	data, err := json.Marshal(certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"serial":"0a1b2c","name":"laptop","issued_at":"2023-11-14T22:13:20Z","expires_at":"2023-11-14T23:13:20Z"}`, string(data))
+
+	certificate.RevokedAt = sql.NullTime{Time: issued.Add(time.Minute), Valid: true}
+
+	assert.True(t, certificate.IsRevoked())
+
+	data, err = json.Marshal(certificate)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"serial":"0a1b2c","name":"laptop","issued_at":"2023-11-14T22:13:20Z","expires_at":"2023-11-14T23:13:20Z","revoked_at":"2023-11-14T22:14:20Z"}`, string(data))
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DirectoryUser represents a directory user row in the database. The directory users are provisioned by an external
+// identity provider such as with the SCIM 2.0 endpoints, and are identified externally by the PublicID.
+type DirectoryUser struct {
+	ID          int                      `db:"id"`
+	PublicID    uuid.UUID                `db:"public_id"`
+	ExternalID  sql.NullString           `db:"external_id"`
+	Username    string                   `db:"username"`
+	DisplayName string                   `db:"display_name"`
+	GivenName   string                   `db:"given_name"`
+	FamilyName  string                   `db:"family_name"`
+	Emails      StringSlicePipeDelimited `db:"emails"`
+	Active      bool                     `db:"active"`
+	CreatedAt   time.Time                `db:"created_at"`
+	UpdatedAt   time.Time                `db:"updated_at"`
+}
+
+// DirectoryGroup represents a directory group row in the database.
+type DirectoryGroup struct {
+	ID          int            `db:"id"`
+	PublicID    uuid.UUID      `db:"public_id"`
+	ExternalID  sql.NullString `db:"external_id"`
+	DisplayName string         `db:"display_name"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+// DirectoryGroupMember represents a member of a directory group, which is a directory user.
+type DirectoryGroupMember struct {
+	PublicID uuid.UUID `db:"public_id"`
+	Username string    `db:"username"`
+}
+
+// DirectoryUserFilter is used to filter the directory users loaded from the database. The empty fields are not used
+// to filter the users.
+type DirectoryUserFilter struct {
+	Username   string
+	ExternalID string
+}
+
+// DirectoryGroupFilter is used to filter the directory groups loaded from the database. The empty fields are not used
+// to filter the groups.
+type DirectoryGroupFilter struct {
+	DisplayName string
+	ExternalID  string
+}
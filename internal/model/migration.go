@@ -6,9 +6,9 @@ import (
 
 // Migration represents a migration row in the database.
 type Migration struct {
-	ID      int       `db:"id"`
-	Applied time.Time `db:"applied"`
-	Before  int       `db:"version_before"`
-	After   int       `db:"version_after"`
-	Version string    `db:"application_version"`
+	ID      int       `db:"id" json:"id"`
+	Applied time.Time `db:"applied" json:"applied"`
+	Before  int       `db:"version_before" json:"version_before"`
+	After   int       `db:"version_after" json:"version_after"`
+	Version string    `db:"application_version" json:"application_version"`
 }
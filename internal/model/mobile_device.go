@@ -0,0 +1,44 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MobileDevice represents a mobile device row in the database. A mobile device is a native app which authenticated a
+// user with the mobile authentication endpoints. Only the signatures of the access and refresh tokens are stored, and
+// the signature of the previous refresh token is kept to detect the reuse of rotated refresh tokens.
+type MobileDevice struct {
+	ID                       int            `db:"id"`
+	PublicID                 uuid.UUID      `db:"public_id"`
+	Username                 string         `db:"username"`
+	Name                     string         `db:"name"`
+	Platform                 string         `db:"platform"`
+	CreatedAt                time.Time      `db:"created_at"`
+	LastUsedAt               sql.NullTime   `db:"last_used_at"`
+	AccessSignature          string         `db:"access_signature"`
+	AccessExpiresAt          time.Time      `db:"access_expires_at"`
+	RefreshSignature         string         `db:"refresh_signature"`
+	PreviousRefreshSignature sql.NullString `db:"previous_refresh_signature"`
+	ExpiresAt                time.Time      `db:"expires_at"`
+	PushProvider             sql.NullString `db:"push_provider"`
+	PushToken                sql.NullString `db:"push_token"`
+	RevokedAt                sql.NullTime   `db:"revoked_at"`
+}
+
+// IsRevoked returns true if the MobileDevice has been revoked.
+func (d *MobileDevice) IsRevoked() bool {
+	return d.RevokedAt.Valid
+}
+
+// IsExpired returns true if the refresh token of the MobileDevice has expired at the given time.
+func (d *MobileDevice) IsExpired(now time.Time) bool {
+	return !d.ExpiresAt.After(now)
+}
+
+// IsAccessExpired returns true if the access token of the MobileDevice has expired at the given time.
+func (d *MobileDevice) IsAccessExpired(now time.Time) bool {
+	return !d.AccessExpiresAt.After(now)
+}
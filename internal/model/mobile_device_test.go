@@ -0,0 +1,33 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMobileDevice(t *testing.T) {
+	created := time.Unix(1700000000, 0).UTC()
+
+	device := MobileDevice{
+		ID:              1,
+		Username:        "john",
+		Name:            "phone",
+		Platform:        "ios",
+		CreatedAt:       created,
+		AccessExpiresAt: created.Add(time.Minute),
+		ExpiresAt:       created.Add(time.Hour),
+	}
+
+	assert.False(t, device.IsRevoked())
+	assert.False(t, device.IsAccessExpired(created))
+	assert.True(t, device.IsAccessExpired(created.Add(time.Minute)))
+	assert.False(t, device.IsExpired(created.Add(time.Minute)))
+	assert.True(t, device.IsExpired(created.Add(time.Hour)))
+
+	device.RevokedAt = sql.NullTime{Time: created.Add(time.Second), Valid: true}
+
+	assert.True(t, device.IsRevoked())
+}
@@ -142,6 +142,158 @@ func NewOAuth2PARContext(contextID string, r oauthelia2.AuthorizeRequester) (con
 	}, nil
 }
 
+// NewOAuth2DeviceCodeSessionFromRequest creates a new OAuth2DeviceCodeSession from a oauthelia2.DeviceAuthorizeRequester.
+func NewOAuth2DeviceCodeSessionFromRequest(r oauthelia2.DeviceAuthorizeRequester) (session *OAuth2DeviceCodeSession, err error) {
+	if r == nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2DeviceCodeSession: the oauthelia2.DeviceAuthorizeRequester was nil")
+	}
+
+	var (
+		subject     sql.NullString
+		s           OpenIDSession
+		ok          bool
+		sessionData []byte
+	)
+
+	if s, ok = r.GetSession().(OpenIDSession); !ok {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2DeviceCodeSession: the session type OpenIDSession was expected but the type '%T' was used", r.GetSession())
+	}
+
+	subject = sql.NullString{String: s.GetSubject()}
+
+	subject.Valid = len(subject.String) > 0
+
+	if sessionData, err = json.Marshal(s); err != nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2DeviceCodeSession: an error was returned while attempting to marshal the session data to json: %w", err)
+	}
+
+	requested, granted := r.GetRequestedScopes(), r.GetGrantedScopes()
+
+	if requested == nil {
+		requested = oauthelia2.Arguments{}
+	}
+
+	if granted == nil {
+		granted = oauthelia2.Arguments{}
+	}
+
+	return &OAuth2DeviceCodeSession{
+		RequestID:         r.GetID(),
+		ClientID:          r.GetClient().GetID(),
+		Signature:         r.GetDeviceCodeSignature(),
+		UserCodeSignature: r.GetUserCodeSignature(),
+		Status:            int(r.GetStatus()),
+		Subject:           subject,
+		RequestedAt:       r.GetRequestedAt(),
+		CheckedAt:         r.GetLastChecked(),
+		RequestedScopes:   StringSlicePipeDelimited(requested),
+		GrantedScopes:     StringSlicePipeDelimited(granted),
+		RequestedAudience: StringSlicePipeDelimited(r.GetRequestedAudience()),
+		GrantedAudience:   StringSlicePipeDelimited(r.GetGrantedAudience()),
+		Active:            true,
+		Revoked:           false,
+		Form:              r.GetRequestForm().Encode(),
+		Session:           sessionData,
+	}, nil
+}
+
+// NewOAuth2TokenExchangeLineage creates a new OAuth2TokenExchangeLineage from the oauthelia2.Requester of a token
+// issued via the OAuth 2.0 Token Exchange grant and the oauthelia2.Requester of the subject token it was exchanged from.
+func NewOAuth2TokenExchangeLineage(r, parent oauthelia2.Requester) (lineage *OAuth2TokenExchangeLineage, err error) {
+	if r == nil || parent == nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2TokenExchangeLineage: the oauthelia2.Requester was nil")
+	}
+
+	var (
+		subject sql.NullString
+		s       OpenIDSession
+		ok      bool
+	)
+
+	if s, ok = r.GetSession().(OpenIDSession); !ok {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2TokenExchangeLineage: the session type OpenIDSession was expected but the type '%T' was used", r.GetSession())
+	}
+
+	subject = sql.NullString{String: s.GetSubject()}
+
+	subject.Valid = len(subject.String) > 0
+
+	return &OAuth2TokenExchangeLineage{
+		RequestID:       r.GetID(),
+		ParentRequestID: parent.GetID(),
+		ClientID:        r.GetClient().GetID(),
+		ParentClientID:  parent.GetClient().GetID(),
+		Subject:         subject,
+		ExchangedAt:     r.GetRequestedAt(),
+	}, nil
+}
+
+// NewOAuth2CIBARequest creates a new OAuth2CIBARequest from the oauthelia2.Requester of an OpenID Connect 1.0 Client
+// Initiated Backchannel Authentication request. The auth_req_id and client notification token are only retained for
+// the ping token delivery mode as they're required to notify the client.
+func NewOAuth2CIBARequest(r oauthelia2.Requester, signature, username, mode string, notification *OAuth2CIBANotification, expires time.Time) (request *OAuth2CIBARequest, err error) {
+	if r == nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2CIBARequest: the oauthelia2.Requester was nil")
+	}
+
+	var (
+		subject     sql.NullString
+		s           OpenIDSession
+		ok          bool
+		sessionData []byte
+	)
+
+	if s, ok = r.GetSession().(OpenIDSession); !ok {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2CIBARequest: the session type OpenIDSession was expected but the type '%T' was used", r.GetSession())
+	}
+
+	subject = sql.NullString{String: s.GetSubject()}
+
+	subject.Valid = len(subject.String) > 0
+
+	data := OAuth2CIBARequestData{
+		Notification: notification,
+	}
+
+	if data.Session, err = json.Marshal(s); err != nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2CIBARequest: an error was returned while attempting to marshal the session data to json: %w", err)
+	}
+
+	if sessionData, err = json.Marshal(data); err != nil {
+		return nil, fmt.Errorf("failed to create new *model.OAuth2CIBARequest: an error was returned while attempting to marshal the request data to json: %w", err)
+	}
+
+	requested, granted := r.GetRequestedScopes(), r.GetGrantedScopes()
+
+	if requested == nil {
+		requested = oauthelia2.Arguments{}
+	}
+
+	if granted == nil {
+		granted = oauthelia2.Arguments{}
+	}
+
+	return &OAuth2CIBARequest{
+		RequestID:         r.GetID(),
+		ClientID:          r.GetClient().GetID(),
+		Signature:         signature,
+		Status:            OAuth2CIBAStatusPending,
+		Subject:           subject,
+		Username:          username,
+		DeliveryMode:      mode,
+		RequestedAt:       r.GetRequestedAt(),
+		ExpiresAt:         expires,
+		CheckedAt:         r.GetRequestedAt(),
+		RequestedScopes:   StringSlicePipeDelimited(requested),
+		GrantedScopes:     StringSlicePipeDelimited(granted),
+		RequestedAudience: StringSlicePipeDelimited(r.GetRequestedAudience()),
+		GrantedAudience:   StringSlicePipeDelimited(r.GetGrantedAudience()),
+		Active:            true,
+		Form:              r.GetRequestForm().Encode(),
+		Session:           sessionData,
+	}, nil
+}
+
 // OAuth2ConsentPreConfig stores information about an OAuth2.0 Pre-Configured Consent.
 type OAuth2ConsentPreConfig struct {
 	ID       int64     `db:"id"`
@@ -178,6 +330,12 @@ func (s *OAuth2ConsentPreConfig) CanConsent() bool {
 	return !s.Revoked && (!s.ExpiresAt.Valid || s.ExpiresAt.Time.After(time.Now()))
 }
 
+// OAuth2ConsentSessionFilter describes the criteria used to select OAuth2.0 consent sessions. Empty values match all
+// consent sessions.
+type OAuth2ConsentSessionFilter struct {
+	ClientID string
+}
+
 // OAuth2ConsentSession stores information about an OAuth2.0 Consent.
 type OAuth2ConsentSession struct {
 	ID          int           `db:"id"`
@@ -393,6 +551,200 @@ func (par *OAuth2PARContext) ToAuthorizeRequest(ctx context.Context, session oau
 	return request, nil
 }
 
+// OAuth2DeviceCodeSession represents an OAuth 2.0 Device Authorization Grant session.
+type OAuth2DeviceCodeSession struct {
+	ID                int                      `db:"id"`
+	RequestID         string                   `db:"request_id"`
+	ClientID          string                   `db:"client_id"`
+	Signature         string                   `db:"signature"`
+	UserCodeSignature string                   `db:"user_code_signature"`
+	Status            int                      `db:"status"`
+	Subject           sql.NullString           `db:"subject"`
+	RequestedAt       time.Time                `db:"requested_at"`
+	CheckedAt         time.Time                `db:"checked_at"`
+	RequestedScopes   StringSlicePipeDelimited `db:"requested_scopes"`
+	GrantedScopes     StringSlicePipeDelimited `db:"granted_scopes"`
+	RequestedAudience StringSlicePipeDelimited `db:"requested_audience"`
+	GrantedAudience   StringSlicePipeDelimited `db:"granted_audience"`
+	Active            bool                     `db:"active"`
+	Revoked           bool                     `db:"revoked"`
+	Form              string                   `db:"form_data"`
+	Session           []byte                   `db:"session_data"`
+}
+
+// ToRequest converts an OAuth2DeviceCodeSession into a oauthelia2.DeviceAuthorizeRequest given a oauthelia2.Session
+// and oauthelia2.Storage.
+func (s *OAuth2DeviceCodeSession) ToRequest(ctx context.Context, session oauthelia2.Session, store oauthelia2.Storage) (request *oauthelia2.DeviceAuthorizeRequest, err error) {
+	if session != nil {
+		if err = json.Unmarshal(s.Session, session); err != nil {
+			return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Device Code Session back to a Request while trying to unmarshal the JSON session data: %w", err)
+		}
+	}
+
+	var (
+		client oauthelia2.Client
+		form   url.Values
+	)
+
+	if client, err = store.GetClient(ctx, s.ClientID); err != nil {
+		return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Device Code Session back to a Request while trying to lookup the registered client: %w", err)
+	}
+
+	if form, err = url.ParseQuery(s.Form); err != nil {
+		return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Device Code Session back to a Request while trying to parse the original form: %w", err)
+	}
+
+	request = oauthelia2.NewDeviceAuthorizeRequest()
+
+	request.Request = oauthelia2.Request{
+		ID:                s.RequestID,
+		RequestedAt:       s.RequestedAt,
+		Client:            client,
+		RequestedScope:    oauthelia2.Arguments(s.RequestedScopes),
+		GrantedScope:      oauthelia2.Arguments(s.GrantedScopes),
+		RequestedAudience: oauthelia2.Arguments(s.RequestedAudience),
+		GrantedAudience:   oauthelia2.Arguments(s.GrantedAudience),
+		Form:              form,
+		Session:           session,
+	}
+
+	request.DeviceCodeSignature = s.Signature
+	request.UserCodeSignature = s.UserCodeSignature
+	request.Status = oauthelia2.DeviceAuthorizeStatus(s.Status)
+	request.LastChecked = s.CheckedAt
+
+	return request, nil
+}
+
+// IsExpired returns true if the device code and user code of this session were issued before the provided time.
+func (s *OAuth2DeviceCodeSession) IsExpired(before time.Time) bool {
+	return s.RequestedAt.Before(before)
+}
+
+// OAuth2TokenExchangeLineage represents the relationship between a token issued via the OAuth 2.0 Token Exchange grant
+// and the subject token it was exchanged from. It's used to revoke all exchanged tokens when the parent is revoked.
+type OAuth2TokenExchangeLineage struct {
+	ID              int            `db:"id"`
+	RequestID       string         `db:"request_id"`
+	ParentRequestID string         `db:"parent_request_id"`
+	ClientID        string         `db:"client_id"`
+	ParentClientID  string         `db:"parent_client_id"`
+	Subject         sql.NullString `db:"subject"`
+	ExchangedAt     time.Time      `db:"exchanged_at"`
+}
+
+// OAuth2DynamicClient represents an OAuth 2.0 client registered via the Dynamic Client Registration endpoint. The
+// registration access token and client secret are only stored as a signature and digest respectively, and the metadata
+// is the JSON encoded client metadata as described by RFC7591.
+type OAuth2DynamicClient struct {
+	ID                               int            `db:"id"`
+	ClientID                         string         `db:"client_id"`
+	RegistrationAccessTokenSignature string         `db:"registration_access_token_signature"`
+	ClientSecret                     sql.NullString `db:"client_secret"`
+	Metadata                         []byte         `db:"metadata"`
+	CreatedAt                        time.Time      `db:"created_at"`
+	UpdatedAt                        time.Time      `db:"updated_at"`
+}
+
+// OAuth2CIBARequest represents an OpenID Connect 1.0 Client Initiated Backchannel Authentication request. The status
+// is updated as the out-of-band authentication identified by the TransactionID is approved or denied by the user.
+type OAuth2CIBARequest struct {
+	ID                int                      `db:"id"`
+	RequestID         string                   `db:"request_id"`
+	ClientID          string                   `db:"client_id"`
+	Signature         string                   `db:"signature"`
+	Status            int                      `db:"status"`
+	Subject           sql.NullString           `db:"subject"`
+	Username          string                   `db:"username"`
+	TransactionID     sql.NullString           `db:"txid"`
+	DeliveryMode      string                   `db:"delivery_mode"`
+	Notified          bool                     `db:"notified"`
+	RequestedAt       time.Time                `db:"requested_at"`
+	ExpiresAt         time.Time                `db:"expires_at"`
+	CheckedAt         time.Time                `db:"checked_at"`
+	AuthenticatedAt   sql.NullTime             `db:"authenticated_at"`
+	RequestedScopes   StringSlicePipeDelimited `db:"requested_scopes"`
+	GrantedScopes     StringSlicePipeDelimited `db:"granted_scopes"`
+	RequestedAudience StringSlicePipeDelimited `db:"requested_audience"`
+	GrantedAudience   StringSlicePipeDelimited `db:"granted_audience"`
+	Active            bool                     `db:"active"`
+	Form              string                   `db:"form_data"`
+	Session           []byte                   `db:"session_data"`
+}
+
+// OAuth2CIBARequestData represents the encrypted session data of an OAuth2CIBARequest.
+type OAuth2CIBARequestData struct {
+	Session      json.RawMessage         `json:"session"`
+	Notification *OAuth2CIBANotification `json:"notification,omitempty"`
+}
+
+// OAuth2CIBANotification represents the values required to notify a client using the ping token delivery mode.
+type OAuth2CIBANotification struct {
+	AuthRequestID string `json:"auth_req_id"`
+	Token         string `json:"client_notification_token"`
+}
+
+// ToRequest converts an OAuth2CIBARequest into a oauthelia2.Request given a oauthelia2.Session and oauthelia2.Storage.
+func (r *OAuth2CIBARequest) ToRequest(ctx context.Context, session oauthelia2.Session, store oauthelia2.Storage) (request *oauthelia2.Request, err error) {
+	if session != nil {
+		var data *OAuth2CIBARequestData
+
+		if data, err = r.GetData(); err != nil {
+			return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Backchannel Authentication Request back to a Request while trying to unmarshal the JSON request data: %w", err)
+		}
+
+		if err = json.Unmarshal(data.Session, session); err != nil {
+			return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Backchannel Authentication Request back to a Request while trying to unmarshal the JSON session data: %w", err)
+		}
+	}
+
+	var (
+		client oauthelia2.Client
+		form   url.Values
+	)
+
+	if client, err = store.GetClient(ctx, r.ClientID); err != nil {
+		return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Backchannel Authentication Request back to a Request while trying to lookup the registered client: %w", err)
+	}
+
+	if form, err = url.ParseQuery(r.Form); err != nil {
+		return nil, fmt.Errorf("error occurred while mapping OAuth 2.0 Backchannel Authentication Request back to a Request while trying to parse the original form: %w", err)
+	}
+
+	return &oauthelia2.Request{
+		ID:                r.RequestID,
+		RequestedAt:       r.RequestedAt,
+		Client:            client,
+		RequestedScope:    oauthelia2.Arguments(r.RequestedScopes),
+		GrantedScope:      oauthelia2.Arguments(r.GrantedScopes),
+		RequestedAudience: oauthelia2.Arguments(r.RequestedAudience),
+		GrantedAudience:   oauthelia2.Arguments(r.GrantedAudience),
+		Form:              form,
+		Session:           session,
+	}, nil
+}
+
+// GetData returns the decoded session data of this OAuth2CIBARequest.
+func (r *OAuth2CIBARequest) GetData() (data *OAuth2CIBARequestData, err error) {
+	data = &OAuth2CIBARequestData{}
+
+	if err = json.Unmarshal(r.Session, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// IsExpired returns true if this OAuth2CIBARequest expired before the provided time.
+func (r *OAuth2CIBARequest) IsExpired(now time.Time) bool {
+	return r.ExpiresAt.Before(now)
+}
+
+// IsPending returns true if the out-of-band authentication of this OAuth2CIBARequest has not been completed.
+func (r *OAuth2CIBARequest) IsPending() bool {
+	return r.Status == OAuth2CIBAStatusPending
+}
+
 // OpenIDSession represents the types available for an oidc.Session that are required in the models package.
 type OpenIDSession interface {
 	oauthelia2.Session
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"crypto/subtle"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,10 +16,63 @@ const (
 	// OTCIntentUserSessionElevation is the intent value for a one-time code indicating it's used for user session
 	// elevation.
 	OTCIntentUserSessionElevation = "use"
+
+	// OTCIntentPhoneNumberVerification is the intent value for a one-time code indicating it's used to verify a phone
+	// number before it's registered for the SMS second factor method.
+	OTCIntentPhoneNumberVerification = "pnv"
+
+	// OTCIntentSMSSignIn is the intent value for a one-time code indicating it's used to sign in with the SMS second
+	// factor method.
+	OTCIntentSMSSignIn = "sms"
+
+	// OTCIntentVoiceSignIn is the intent value for a one-time code indicating it's used to sign in with the SMS second
+	// factor method where the one-time code was delivered with a voice call.
+	OTCIntentVoiceSignIn = "tel"
+
+	// OTCIntentEmailSignIn is the intent value for a one-time code indicating it's used to sign in with the Email second
+	// factor method.
+	OTCIntentEmailSignIn = "eml"
+)
+
+const (
+	// OneTimeCodeMaximumLength is the maximum number of characters a one-time code may contain.
+	OneTimeCodeMaximumLength = 20
+)
+
+var (
+	errOneTimeCodeExpired         = errors.New("the code challenge has expired")
+	errOneTimeCodeRevoked         = errors.New("the code challenge has been revoked")
+	errOneTimeCodeAlreadyRevoked  = errors.New("the code challenge has already been revoked")
+	errOneTimeCodeAlreadyConsumed = errors.New("the code challenge has already been consumed")
+	errOneTimeCodeMismatch        = errors.New("the code does not match the code stored in the challenge")
 )
 
+// OneTimeCodeOptions represents the options used to generate a OneTimeCode. Each flow which uses a one-time code has its
+// own intent so a code issued for one flow can never be used for another.
+type OneTimeCodeOptions struct {
+	// Intent is the intent of the flow the code is issued for.
+	Intent string
+
+	// Length is the number of characters in the code.
+	Length int
+
+	// CharacterSet is the set of characters the code is generated from, defaults to random.CharSetUnambiguousUpper.
+	CharacterSet string
+
+	// Lifespan is the duration the code is valid for after it's issued.
+	Lifespan time.Duration
+}
+
 // NewOneTimeCode returns a new OneTimeCode.
-func NewOneTimeCode(ctx Context, username string, characters int, duration time.Duration) (otp *OneTimeCode, err error) {
+func NewOneTimeCode(ctx Context, username string, opts OneTimeCodeOptions) (otp *OneTimeCode, err error) {
+	if opts.Length <= 0 || opts.Length > OneTimeCodeMaximumLength {
+		return nil, fmt.Errorf("failed to generate code: the length must be between 1 and %d but it's %d", OneTimeCodeMaximumLength, opts.Length)
+	}
+
+	if opts.CharacterSet == "" {
+		opts.CharacterSet = random.CharSetUnambiguousUpper
+	}
+
 	var (
 		publicID uuid.UUID
 		code     []byte
@@ -29,7 +84,7 @@ func NewOneTimeCode(ctx Context, username string, characters int, duration time.
 		return nil, fmt.Errorf("failed to generate public id: %w", err)
 	}
 
-	if code, err = src.BytesCustomErr(characters, []byte(random.CharSetUnambiguousUpper)); err != nil {
+	if code, err = src.BytesCustomErr(opts.Length, []byte(opts.CharacterSet)); err != nil {
 		return nil, fmt.Errorf("failed to generate random bytes: %w", err)
 	}
 
@@ -37,9 +92,9 @@ func NewOneTimeCode(ctx Context, username string, characters int, duration time.
 		PublicID:  publicID,
 		IssuedAt:  ctx.GetClock().Now(),
 		IssuedIP:  NewIP(ctx.RemoteIP()),
-		ExpiresAt: ctx.GetClock().Now().Add(duration),
+		ExpiresAt: ctx.GetClock().Now().Add(opts.Lifespan),
 		Username:  username,
-		Intent:    OTCIntentUserSessionElevation,
+		Intent:    opts.Intent,
 		Code:      code,
 	}, nil
 }
@@ -66,3 +121,37 @@ func (otp *OneTimeCode) Consume(ctx Context) {
 	otp.ConsumedAt = sql.NullTime{Valid: true, Time: ctx.GetClock().Now()}
 	otp.ConsumedIP = NewNullIP(ctx.RemoteIP())
 }
+
+// Validate returns an error if the one-time code can't be consumed for the intent with the provided code, i.e. it has
+// expired, has been revoked or consumed, was issued for another intent, or the code does not match.
+func (otp *OneTimeCode) Validate(ctx Context, intent string, code []byte) (err error) {
+	switch {
+	case otp.ExpiresAt.Before(ctx.GetClock().Now()):
+		return errOneTimeCodeExpired
+	case otp.RevokedAt.Valid:
+		return errOneTimeCodeRevoked
+	case otp.ConsumedAt.Valid:
+		return errOneTimeCodeAlreadyConsumed
+	case otp.Intent != intent:
+		return fmt.Errorf("the code challenge has the '%s' intent but the '%s' intent is required", otp.Intent, intent)
+	case subtle.ConstantTimeCompare(otp.Code, code) != 1:
+		return errOneTimeCodeMismatch
+	default:
+		return nil
+	}
+}
+
+// ValidateRevocation returns an error if the one-time code can't be revoked for the intent, i.e. it has already been
+// revoked or consumed, or was issued for another intent.
+func (otp *OneTimeCode) ValidateRevocation(intent string) (err error) {
+	switch {
+	case otp.RevokedAt.Valid:
+		return errOneTimeCodeAlreadyRevoked
+	case otp.ConsumedAt.Valid:
+		return errOneTimeCodeAlreadyConsumed
+	case otp.Intent != intent:
+		return fmt.Errorf("the code challenge has the '%s' intent but the '%s' intent is required", otp.Intent, intent)
+	default:
+		return nil
+	}
+}
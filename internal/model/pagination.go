@@ -0,0 +1,68 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortOrder is the order the records of a list are sorted in by the time they were recorded.
+type SortOrder int
+
+const (
+	// SortOrderDescending sorts the records with the most recent record first, which is the default.
+	SortOrderDescending SortOrder = iota
+
+	// SortOrderAscending sorts the records with the least recent record first.
+	SortOrderAscending
+)
+
+// String returns the query argument representation of the SortOrder.
+func (o SortOrder) String() string {
+	switch o {
+	case SortOrderAscending:
+		return "asc"
+	default:
+		return "desc"
+	}
+}
+
+// NewSortOrder parses the query argument representation of a SortOrder, an empty value is SortOrderDescending.
+func NewSortOrder(value string) (order SortOrder, err error) {
+	switch strings.ToLower(value) {
+	case "", "desc":
+		return SortOrderDescending, nil
+	case "asc":
+		return SortOrderAscending, nil
+	default:
+		return SortOrderDescending, fmt.Errorf("the sort order '%s' is unknown and must be one of 'asc' or 'desc'", value)
+	}
+}
+
+// Pagination describes the page of a list of records which is requested and the order the records are sorted in.
+type Pagination struct {
+	Limit int
+	Page  int
+	Order SortOrder
+}
+
+// Offset returns the number of records which come before the page.
+func (p Pagination) Offset() int {
+	return p.Limit * p.Page
+}
+
+// Paginate returns the page of a list of records already sorted with the most recent record first.
+func Paginate[T any](items []T, pagination Pagination) (page []T) {
+	page = make([]T, 0, pagination.Limit)
+
+	n := len(items)
+
+	for i := pagination.Offset(); i < n && len(page) < pagination.Limit; i++ {
+		if pagination.Order == SortOrderAscending {
+			page = append(page, items[n-1-i])
+		} else {
+			page = append(page, items[i])
+		}
+	}
+
+	return page
+}
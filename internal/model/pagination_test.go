@@ -0,0 +1,64 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSortOrder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected SortOrder
+		err      string
+	}{
+		{"ShouldParseEmpty", "", SortOrderDescending, ""},
+		{"ShouldParseDescending", "desc", SortOrderDescending, ""},
+		{"ShouldParseAscending", "ASC", SortOrderAscending, ""},
+		{"ShouldNotParseUnknown", "up", SortOrderDescending, "the sort order 'up' is unknown and must be one of 'asc' or 'desc'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := NewSortOrder(tc.have)
+
+			assert.Equal(t, tc.expected, actual)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+
+				actual, err = NewSortOrder(actual.String())
+
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []int{5, 4, 3, 2, 1}
+
+	testCases := []struct {
+		name     string
+		have     Pagination
+		expected []int
+	}{
+		{"ShouldReturnFirstPage", Pagination{Limit: 2}, []int{5, 4}},
+		{"ShouldReturnSecondPage", Pagination{Limit: 2, Page: 1}, []int{3, 2}},
+		{"ShouldReturnPartialPage", Pagination{Limit: 2, Page: 2}, []int{1}},
+		{"ShouldReturnEmptyPage", Pagination{Limit: 2, Page: 3}, []int{}},
+		{"ShouldReturnFirstPageAscending", Pagination{Limit: 2, Order: SortOrderAscending}, []int{1, 2}},
+		{"ShouldReturnPartialPageAscending", Pagination{Limit: 2, Page: 2, Order: SortOrderAscending}, []int{5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Paginate(items, tc.have))
+			assert.Equal(t, tc.have.Limit*tc.have.Page, tc.have.Offset())
+		})
+	}
+}
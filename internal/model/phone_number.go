@@ -0,0 +1,95 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NewPhoneNumber returns a new PhoneNumber for the user from a phone number which has already been normalized with
+// NormalizePhoneNumber.
+func NewPhoneNumber(username, number string, now time.Time) PhoneNumber {
+	return PhoneNumber{
+		CreatedAt: now,
+		Username:  username,
+		Number:    []byte(number),
+	}
+}
+
+// NormalizePhoneNumber returns the phone number in the E.164 format after removing the common visual separators, or
+// an error if the phone number is not a valid E.164 phone number.
+func NormalizePhoneNumber(raw string) (number string, err error) {
+	number = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		default:
+			return r
+		}
+	}, strings.TrimSpace(raw))
+
+	if strings.HasPrefix(number, "00") {
+		number = "+" + number[2:]
+	}
+
+	if !rePhoneNumberE164.MatchString(number) {
+		return "", fmt.Errorf("the phone number '%s' is not a valid E.164 phone number", raw)
+	}
+
+	return number, nil
+}
+
+// PhoneNumber represents a verified phone number of a user used for the SMS second factor method. The number is
+// stored encrypted in the database.
+type PhoneNumber struct {
+	ID         int          `db:"id"`
+	CreatedAt  time.Time    `db:"created_at"`
+	LastUsedAt sql.NullTime `db:"last_used_at"`
+	Username   string       `db:"username"`
+	Number     []byte       `db:"phone_number"`
+}
+
+// PhoneNumberJSON is the JSON representation for a PhoneNumber.
+type PhoneNumberJSON struct {
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Number     string     `json:"phone_number"`
+}
+
+// MarshalJSON returns the PhoneNumber in a JSON friendly manner with the number masked.
+func (p PhoneNumber) MarshalJSON() (data []byte, err error) {
+	o := PhoneNumberJSON{
+		CreatedAt: p.CreatedAt,
+		Number:    p.Masked(),
+	}
+
+	if p.LastUsedAt.Valid {
+		o.LastUsedAt = &p.LastUsedAt.Time
+	}
+
+	return json.Marshal(o)
+}
+
+// E164 returns the phone number in the E.164 format.
+func (p *PhoneNumber) E164() string {
+	return string(p.Number)
+}
+
+// Masked returns the phone number with all but the last few digits masked so it can be displayed to the user without
+// revealing the full number.
+func (p *PhoneNumber) Masked() string {
+	n := len(p.Number)
+
+	if n <= phoneNumberUnmaskedDigits+1 {
+		return strings.Repeat("*", n)
+	}
+
+	return "+" + strings.Repeat("*", n-phoneNumberUnmaskedDigits-1) + string(p.Number[n-phoneNumberUnmaskedDigits:])
+}
+
+// UpdateSignInInfo adjusts the values of the PhoneNumber after a sign in.
+func (p *PhoneNumber) UpdateSignInInfo(now time.Time) {
+	p.LastUsedAt = sql.NullTime{Time: now, Valid: true}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		err      string
+	}{
+		{"ShouldAcceptE164", "+15555550123", "+15555550123", ""},
+		{"ShouldRemoveSeparators", " +1 (555) 555-01.23 ", "+15555550123", ""},
+		{"ShouldReplaceInternationalPrefix", "0061 412 345 678", "+61412345678", ""},
+		{"ShouldRejectMissingPlus", "15555550123", "", "the phone number '15555550123' is not a valid E.164 phone number"},
+		{"ShouldRejectLeadingZero", "+05555550123", "", "the phone number '+05555550123' is not a valid E.164 phone number"},
+		{"ShouldRejectTooShort", "+12345", "", "the phone number '+12345' is not a valid E.164 phone number"},
+		{"ShouldRejectTooLong", "+1234567890123456", "", "the phone number '+1234567890123456' is not a valid E.164 phone number"},
+		{"ShouldRejectLetters", "+1555CALLNOW", "", "the phone number '+1555CALLNOW' is not a valid E.164 phone number"},
+		{"ShouldRejectEmpty", "", "", "the phone number '' is not a valid E.164 phone number"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := NormalizePhoneNumber(tc.have)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Equal(t, "", actual)
+			}
+		})
+	}
+}
+
+func TestPhoneNumber(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+
+	number := NewPhoneNumber("john", "+15555550123", now)
+
+	assert.Equal(t, "john", number.Username)
+	assert.Equal(t, now, number.CreatedAt)
+	assert.Equal(t, "+15555550123", number.E164())
+	assert.Equal(t, "+*******0123", number.Masked())
+	assert.False(t, number.LastUsedAt.Valid)
+
+	number.UpdateSignInInfo(now.Add(time.Minute))
+
+	assert.Equal(t, sql.NullTime{Time: now.Add(time.Minute), Valid: true}, number.LastUsedAt)
+
+	data, err := json.Marshal(number)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"created_at":"2023-11-14T22:13:20Z","last_used_at":"2023-11-14T22:14:20Z","phone_number":"+*******0123"}`, string(data))
+
+	number.LastUsedAt = sql.NullTime{}
+
+	data, err = json.Marshal(number)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"created_at":"2023-11-14T22:13:20Z","phone_number":"+*******0123"}`, string(data))
+}
+
+func TestPhoneNumberMaskedShort(t *testing.T) {
+	number := PhoneNumber{Number: []byte("+1234")}
+
+	assert.Equal(t, "*****", number.Masked())
+}
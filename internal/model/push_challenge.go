@@ -0,0 +1,65 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushChallenge status values.
+const (
+	// PushChallengeStatusPending indicates the user has not yet responded to the push challenge.
+	PushChallengeStatusPending = iota
+
+	// PushChallengeStatusApproved indicates the user approved the push challenge.
+	PushChallengeStatusApproved
+
+	// PushChallengeStatusDenied indicates the user denied the push challenge.
+	PushChallengeStatusDenied
+)
+
+// NewPushChallenge returns a new pending PushChallenge for the user which expires after the lifespan.
+func NewPushChallenge(username, device, message, token string, ip NullIP, now time.Time, lifespan time.Duration) PushChallenge {
+	return PushChallenge{
+		ChallengeID: uuid.New(),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(lifespan),
+		Username:    username,
+		Device:      device,
+		Message:     message,
+		Token:       token,
+		RequestIP:   ip,
+		Status:      PushChallengeStatusPending,
+	}
+}
+
+// PushChallenge represents a push challenge row in the database. A push challenge is a request to approve a sign in
+// which is delivered to the devices of a user by a push provider and responded to with Authelia. Only the signature
+// of the token which authorizes the response is stored and the raw value is only available when the challenge is
+// created.
+type PushChallenge struct {
+	ID          int          `db:"id"`
+	ChallengeID uuid.UUID    `db:"challenge_id"`
+	CreatedAt   time.Time    `db:"created_at"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	Username    string       `db:"username"`
+	Device      string       `db:"device"`
+	Message     string       `db:"message"`
+	Token       string       `db:"-"`
+	Signature   string       `db:"signature"`
+	RequestIP   NullIP       `db:"request_ip"`
+	Status      int          `db:"status"`
+	RespondedAt sql.NullTime `db:"responded_at"`
+	RespondedIP NullIP       `db:"responded_ip"`
+}
+
+// IsPending returns true if the PushChallenge has not been responded to and has not expired at the given time.
+func (c *PushChallenge) IsPending(now time.Time) bool {
+	return c.Status == PushChallengeStatusPending && !c.IsExpired(now)
+}
+
+// IsExpired returns true if the PushChallenge has expired at the given time.
+func (c *PushChallenge) IsExpired(now time.Time) bool {
+	return !c.ExpiresAt.After(now)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPushChallenge(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	challenge := NewPushChallenge("john", "ntfy", "Sign in to example.com", "abc", NewNullIP(net.ParseIP("127.0.0.1")), now, time.Minute)
+
+	assert.NotEqual(t, uuid.Nil, challenge.ChallengeID)
+	assert.Equal(t, now, challenge.CreatedAt)
+	assert.Equal(t, now.Add(time.Minute), challenge.ExpiresAt)
+	assert.Equal(t, "john", challenge.Username)
+	assert.Equal(t, "ntfy", challenge.Device)
+	assert.Equal(t, "Sign in to example.com", challenge.Message)
+	assert.Equal(t, "abc", challenge.Token)
+	assert.Equal(t, "127.0.0.1", challenge.RequestIP.IP.String())
+	assert.Equal(t, PushChallengeStatusPending, challenge.Status)
+}
+
+func TestPushChallengeIsPending(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		name            string
+		have            PushChallenge
+		pending, expire bool
+	}{
+		{"ShouldBePending", PushChallenge{ExpiresAt: now.Add(time.Second)}, true, false},
+		{"ShouldNotBePendingWhenExpired", PushChallenge{ExpiresAt: now}, false, true},
+		{"ShouldNotBePendingWhenApproved", PushChallenge{ExpiresAt: now.Add(time.Second), Status: PushChallengeStatusApproved}, false, false},
+		{"ShouldNotBePendingWhenDenied", PushChallenge{ExpiresAt: now.Add(time.Second), Status: PushChallengeStatusDenied}, false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.pending, tc.have.IsPending(now))
+			assert.Equal(t, tc.expire, tc.have.IsExpired(now))
+		})
+	}
+}
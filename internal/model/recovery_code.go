@@ -0,0 +1,42 @@
+package model
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// NewRecoveryCode returns a new RecoveryCode for the user with the given raw value.
+func NewRecoveryCode(username, code string, now time.Time) RecoveryCode {
+	return RecoveryCode{
+		CreatedAt: now,
+		Username:  username,
+		Code:      code,
+	}
+}
+
+// RecoveryCode represents a recovery code row in the database. A recovery code is a single-use code which can be used
+// in place of the other second factor methods. Only the signature of the code is stored and the raw value is only
+// available when the code is generated.
+type RecoveryCode struct {
+	ID         int          `db:"id"`
+	CreatedAt  time.Time    `db:"created_at"`
+	Username   string       `db:"username"`
+	Code       string       `db:"-"`
+	Signature  string       `db:"signature"`
+	ConsumedAt sql.NullTime `db:"consumed_at"`
+	ConsumedIP NullIP       `db:"consumed_ip"`
+}
+
+// NormalizeRecoveryCode returns the recovery code without the separators, whitespace, and letter case which users may
+// introduce when entering it, so the entered code can be compared with the generated code.
+func NormalizeRecoveryCode(raw string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || unicode.IsSpace(r) {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, raw)
+}
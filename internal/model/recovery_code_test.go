@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecoveryCode(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	code := NewRecoveryCode("john", "ABCDE-FGHJK", now)
+
+	assert.Equal(t, RecoveryCode{CreatedAt: now, Username: "john", Code: "ABCDE-FGHJK"}, code)
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldNotChangeNormalized", "ABCDEFGHJK", "ABCDEFGHJK"},
+		{"ShouldRemoveSeparator", "ABCDE-FGHJK", "ABCDEFGHJK"},
+		{"ShouldRemoveWhitespace", " abcde fghjk\n", "ABCDEFGHJK"},
+		{"ShouldHandleEmpty", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeRecoveryCode(tc.have))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SAMLSession represents a SAML 2.0 session row in the database. A SAML session is established with a service provider
+// when an assertion is issued to it, and it's revoked when the service provider requests the user is logged out.
+type SAMLSession struct {
+	ID           int          `db:"id"`
+	SessionIndex string       `db:"session_index"`
+	EntityID     string       `db:"entity_id"`
+	Username     string       `db:"username"`
+	NameID       string       `db:"name_id"`
+	NameIDFormat string       `db:"name_id_format"`
+	CreatedAt    time.Time    `db:"created_at"`
+	ExpiresAt    time.Time    `db:"expires_at"`
+	RevokedAt    sql.NullTime `db:"revoked_at"`
+}
+
+// IsRevoked returns true if the SAMLSession has been revoked.
+func (s *SAMLSession) IsRevoked() bool {
+	return s.RevokedAt.Valid
+}
+
+// IsExpired returns true if the SAMLSession has expired at the given time.
+func (s *SAMLSession) IsExpired(now time.Time) bool {
+	return !s.ExpiresAt.After(now)
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSAMLSession(t *testing.T) {
+	created := time.Unix(1700000000, 0).UTC()
+
+	session := SAMLSession{
+		ID:           1,
+		SessionIndex: "_abc",
+		EntityID:     "https://app.example.com",
+		Username:     "john",
+		NameID:       "john",
+		CreatedAt:    created,
+		ExpiresAt:    created.Add(time.Hour),
+	}
+
+	assert.False(t, session.IsRevoked())
+	assert.False(t, session.IsExpired(created))
+	assert.True(t, session.IsExpired(created.Add(time.Hour)))
+
+	session.RevokedAt = sql.NullTime{Time: created.Add(time.Second), Valid: true}
+
+	assert.True(t, session.IsRevoked())
+}
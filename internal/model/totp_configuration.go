@@ -35,6 +35,7 @@ type TOTPConfiguration struct {
 	Digits     uint         `db:"digits"`
 	Period     uint         `db:"period"`
 	Secret     []byte       `db:"secret"`
+	Drift      int          `db:"drift"`
 }
 
 // TOTPConfigurationJSON is the JSON representation for a TOTPConfiguration.
@@ -77,6 +78,17 @@ func (c *TOTPConfiguration) HistorySince(now time.Time, skew *int) time.Time {
 	return now.Add(-time.Second * time.Duration(c.Period) * time.Duration(s))
 }
 
+// StepOffset returns the number of periods the step of a successfully validated code is offset from the step of the
+// current time, which is positive when the clock of the device is ahead. The steps are derived from the Unix time so
+// the time zone of the device, the user, or the server has no bearing on the offset.
+func (c *TOTPConfiguration) StepOffset(now time.Time, step uint64) int {
+	if c.Period == 0 {
+		return 0
+	}
+
+	return int(int64(step) - now.Unix()/int64(c.Period))
+}
+
 // LastUsed provides LastUsedAt as a *time.Time instead of sql.NullTime.
 func (c *TOTPConfiguration) LastUsed() *time.Time {
 	if c.LastUsedAt.Valid {
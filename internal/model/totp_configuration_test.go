@@ -168,3 +168,15 @@ func MustRead(n int) []byte {
 
 	return data
 }
+
+func TestTOTPConfigurationStepOffset(t *testing.T) {
+	config := &TOTPConfiguration{Period: 30}
+
+	now := time.Unix(1700000000, 0)
+
+	assert.Equal(t, 0, config.StepOffset(now, uint64(now.Unix()/30)))
+	assert.Equal(t, 2, config.StepOffset(now, uint64(now.Unix()/30)+2))
+	assert.Equal(t, -3, config.StepOffset(now, uint64(now.Unix()/30)-3))
+	assert.Equal(t, 0, config.StepOffset(now.In(time.FixedZone("AEST", 10*60*60)), uint64(now.Unix()/30)))
+	assert.Equal(t, 0, (&TOTPConfiguration{}).StepOffset(now, 10))
+}
@@ -157,6 +157,11 @@ type StartupCheck interface {
 	StartupCheck() (err error)
 }
 
+// HealthCheck represents a provider that has a health check which is safe to perform repeatedly at runtime.
+type HealthCheck interface {
+	HealthCheck(ctx context.Context) (err error)
+}
+
 // StringSlicePipeDelimited is a string slice that is stored in the database delimited by pipes.
 type StringSlicePipeDelimited []string
 
@@ -0,0 +1,12 @@
+package model
+
+// UsageStatistics represents the statistics of the usage of Authelia which are derived from the storage.
+type UsageStatistics struct {
+	Users             int `db:"users"`
+	UsersActive       int `db:"users_active"`
+	UsersSecondFactor int `db:"users_second_factor"`
+	UsersTOTP         int `db:"users_totp"`
+	UsersWebAuthn     int `db:"users_webauthn"`
+	UsersDuo          int `db:"users_duo"`
+	ClientsActive     int `db:"clients_active"`
+}
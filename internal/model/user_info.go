@@ -20,6 +20,9 @@ type UserInfo struct {
 
 	// True if a duo device has been configured as the preferred.
 	HasDuo bool `db:"has_duo" json:"has_duo" valid:"required"`
+
+	// True if a phone number has been verified for the SMS method.
+	HasSMS bool `db:"has_sms" json:"has_sms" valid:"required"`
 }
 
 // SetDefaultPreferred2FAMethod configures the default method based on what is configured as available and the users available methods.
@@ -32,6 +35,7 @@ func (i *UserInfo) SetDefaultPreferred2FAMethod(methods []string, fallback strin
 	before := i.Method
 
 	totp, webauthn, duo := utils.IsStringInSlice(SecondFactorMethodTOTP, methods), utils.IsStringInSlice(SecondFactorMethodWebAuthn, methods), utils.IsStringInSlice(SecondFactorMethodDuo, methods)
+	sms, email := utils.IsStringInSlice(SecondFactorMethodSMS, methods), utils.IsStringInSlice(SecondFactorMethodEmail, methods)
 
 	if i.Method == "" && utils.IsStringInSlice(fallback, methods) {
 		i.Method = fallback
@@ -40,13 +44,13 @@ func (i *UserInfo) SetDefaultPreferred2FAMethod(methods []string, fallback strin
 	}
 
 	if i.Method == "" {
-		i.setMethod(totp, webauthn, duo, methods, fallback)
+		i.setMethod(totp, webauthn, duo, sms, email, methods, fallback)
 	}
 
 	return before != i.Method
 }
 
-func (i *UserInfo) setMethod(totp, webauthn, duo bool, methods []string, fallback string) {
+func (i *UserInfo) setMethod(totp, webauthn, duo, sms, email bool, methods []string, fallback string) {
 	switch {
 	case i.HasTOTP && totp:
 		i.Method = SecondFactorMethodTOTP
@@ -54,13 +58,19 @@ func (i *UserInfo) setMethod(totp, webauthn, duo bool, methods []string, fallbac
 		i.Method = SecondFactorMethodWebAuthn
 	case i.HasDuo && duo:
 		i.Method = SecondFactorMethodDuo
+	case i.HasSMS && sms:
+		i.Method = SecondFactorMethodSMS
 	case fallback != "" && utils.IsStringInSlice(fallback, methods):
 		i.Method = fallback
+	case email:
+		i.Method = SecondFactorMethodEmail
 	case totp:
 		i.Method = SecondFactorMethodTOTP
 	case webauthn:
 		i.Method = SecondFactorMethodWebAuthn
 	case duo:
 		i.Method = SecondFactorMethodDuo
+	case sms:
+		i.Method = SecondFactorMethodSMS
 	}
 }
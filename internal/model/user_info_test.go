@@ -20,7 +20,7 @@ func TestUserInfo_SetDefaultMethod(t *testing.T) {
 
 		has := ""
 
-		if have.HasTOTP || have.HasDuo || have.HasWebAuthn {
+		if have.HasTOTP || have.HasDuo || have.HasWebAuthn || have.HasSMS {
 			has += " has"
 
 			if have.HasTOTP {
@@ -34,6 +34,10 @@ func TestUserInfo_SetDefaultMethod(t *testing.T) {
 			if have.HasWebAuthn {
 				has += " " + SecondFactorMethodWebAuthn
 			}
+
+			if have.HasSMS {
+				has += " " + SecondFactorMethodSMS
+			}
 		}
 
 		available := none
@@ -300,6 +304,65 @@ func TestUserInfo_SetDefaultMethod(t *testing.T) {
 			fallback: SecondFactorMethodDuo,
 			changed:  true,
 		},
+		{
+			have: UserInfo{
+				HasSMS: true,
+			},
+			want: UserInfo{
+				Method: SecondFactorMethodSMS,
+				HasSMS: true,
+			},
+			methods: []string{SecondFactorMethodTOTP, SecondFactorMethodSMS},
+			changed: true,
+		},
+		{
+			have: UserInfo{
+				Method: SecondFactorMethodSMS,
+				HasSMS: true,
+			},
+			want: UserInfo{
+				Method: SecondFactorMethodTOTP,
+				HasSMS: true,
+			},
+			methods: []string{SecondFactorMethodTOTP, SecondFactorMethodWebAuthn},
+			changed: true,
+		},
+		{
+			have: UserInfo{},
+			want: UserInfo{
+				Method: SecondFactorMethodSMS,
+			},
+			methods: []string{SecondFactorMethodSMS},
+			changed: true,
+		},
+		{
+			have: UserInfo{},
+			want: UserInfo{
+				Method: SecondFactorMethodEmail,
+			},
+			methods: []string{SecondFactorMethodTOTP, SecondFactorMethodWebAuthn, SecondFactorMethodEmail},
+			changed: true,
+		},
+		{
+			have: UserInfo{
+				HasTOTP: true,
+			},
+			want: UserInfo{
+				Method:  SecondFactorMethodTOTP,
+				HasTOTP: true,
+			},
+			methods: []string{SecondFactorMethodTOTP, SecondFactorMethodEmail},
+			changed: true,
+		},
+		{
+			have: UserInfo{},
+			want: UserInfo{
+				Method: SecondFactorMethodWebAuthn,
+			},
+			methods:  []string{SecondFactorMethodWebAuthn, SecondFactorMethodEmail},
+			fallback: SecondFactorMethodWebAuthn,
+			changed:  true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -24,7 +24,7 @@ func NewUserOpaqueIdentifier(service, sectorID, username string) (id *UserOpaque
 
 // UserOpaqueIdentifier represents an opaque identifier for a user. Commonly used with OAuth 2.0 and OpenID Connect.
 type UserOpaqueIdentifier struct {
-	ID       int    `db:"id" yaml:"-"`
+	ID       int    `db:"id" yaml:"-" json:"-"`
 	Service  string `db:"service" yaml:"service" json:"service" jsonschema:"title=Service" jsonschema_description:"The service name this UUID is used with."`
 	SectorID string `db:"sector_id" yaml:"sector_id" json:"sector_id" jsonschema:"title=Sector Identifier" jsonschema_description:"Sector Identifier this UUID is used with."`
 	Username string `db:"username" yaml:"username" json:"username" jsonschema:"title=Username" jsonschema_description:"The username of the user this UUID is for."`
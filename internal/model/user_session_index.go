@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserSessionIndex represents a user session index row in the database. The index is used to enumerate the sessions of
+// a user as the session providers are only able to look up a session given the session id. The SessionID is stored
+// encrypted and the Signature is used to look up the row given the session id.
+type UserSessionIndex struct {
+	ID        int       `db:"id"`
+	PublicID  uuid.UUID `db:"public_id"`
+	Signature string    `db:"signature"`
+	Username  string    `db:"username"`
+	Domain    string    `db:"domain"`
+	RemoteIP  NullIP    `db:"remote_ip"`
+	UserAgent string    `db:"user_agent"`
+	CreatedAt time.Time `db:"created_at"`
+	SessionID []byte    `db:"session_id"`
+}
+
+// UserSessionRevocation represents a user session revocation row in the database. The revocations are shared with all
+// of the replicas so a session which was revoked is refused even if it's saved again by a request which was in flight
+// when it was revoked. The Signature is derived from the session id.
+type UserSessionRevocation struct {
+	ID        int       `db:"id"`
+	Signature string    `db:"signature"`
+	RevokedAt time.Time `db:"revoked_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
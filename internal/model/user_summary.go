@@ -0,0 +1,26 @@
+package model
+
+import (
+	"database/sql"
+)
+
+// UserSummary represents a summary of the information stored for a user such as the enrolled second factor methods
+// and the last successful sign in.
+type UserSummary struct {
+	Username string `db:"username"`
+
+	// The preferred 2FA method.
+	Method string `db:"second_factor_method"`
+
+	// True if a TOTP configuration has been registered.
+	HasTOTP bool `db:"has_totp"`
+
+	// The number of registered WebAuthn credentials.
+	WebAuthnCredentials int `db:"webauthn_credentials"`
+
+	// True if a duo device has been configured as the preferred.
+	HasDuo bool `db:"has_duo"`
+
+	// The time of the last successful 1FA authentication.
+	LastSignInAt sql.NullTime `db:"-"`
+}
@@ -19,6 +19,20 @@ const (
 	attestationTypeFIDOU2F = "fido-u2f"
 )
 
+const (
+	// WebAuthnCredentialSyncStatusDeviceBound is the sync status of a credential which is not eligible to be backed up
+	// and is bound to a single authenticator.
+	WebAuthnCredentialSyncStatusDeviceBound = "device_bound"
+
+	// WebAuthnCredentialSyncStatusSynced is the sync status of a credential which is eligible to be backed up and was
+	// backed up or synced at the time it was last used.
+	WebAuthnCredentialSyncStatusSynced = "synced"
+
+	// WebAuthnCredentialSyncStatusNotSynced is the sync status of a credential which is eligible to be backed up but was
+	// not backed up or synced at the time it was last used.
+	WebAuthnCredentialSyncStatusNotSynced = "not_synced"
+)
+
 // WebAuthnUser is an object to represent a user for the WebAuthn lib.
 type WebAuthnUser struct {
 	ID          int    `db:"id"`
@@ -197,6 +211,18 @@ func (c *WebAuthnCredential) UpdateSignInInfo(config *webauthn.Config, now time.
 	}
 }
 
+// SyncStatus returns the passkey sync status of the WebAuthnCredential derived from the backup flags.
+func (c *WebAuthnCredential) SyncStatus() string {
+	switch {
+	case !c.BackupEligible:
+		return WebAuthnCredentialSyncStatusDeviceBound
+	case c.BackupState:
+		return WebAuthnCredentialSyncStatusSynced
+	default:
+		return WebAuthnCredentialSyncStatusNotSynced
+	}
+}
+
 // DataValueLastUsedAt provides LastUsedAt as a *time.Time instead of sql.NullTime.
 func (c *WebAuthnCredential) DataValueLastUsedAt() *time.Time {
 	if c.LastUsedAt.Valid {
@@ -229,6 +255,7 @@ func (c *WebAuthnCredential) ToData() WebAuthnCredentialData {
 		Description:     c.Description,
 		KID:             c.KID.String(),
 		AAGUID:          c.DataValueAAGUID(),
+		Authenticator:   WebAuthnAuthenticatorName(c.AAGUID),
 		AttestationType: c.AttestationType,
 		Attachment:      c.Attachment,
 		SignCount:       c.SignCount,
@@ -238,6 +265,7 @@ func (c *WebAuthnCredential) ToData() WebAuthnCredentialData {
 		Verified:        c.Verified,
 		BackupEligible:  c.BackupEligible,
 		BackupState:     c.BackupState,
+		SyncStatus:      c.SyncStatus(),
 		PublicKey:       base64.StdEncoding.EncodeToString(c.PublicKey),
 	}
 
@@ -320,6 +348,7 @@ type WebAuthnCredentialData struct {
 	Description     string     `yaml:"description" json:"description" jsonschema:"title=Description" jsonschema_description:"The user description of this credential."`
 	KID             string     `yaml:"kid" json:"kid" jsonschema:"title=Public Key ID" jsonschema_description:"The Public Key ID of this credential."`
 	AAGUID          *string    `yaml:"aaguid,omitempty" json:"aaguid,omitempty" jsonschema:"title=AAGUID" jsonschema_description:"The Authenticator Attestation Global Unique Identifier of this credential."`
+	Authenticator   string     `yaml:"-" json:"authenticator,omitempty" jsonschema:"-"`
 	AttestationType string     `yaml:"attestation_type" json:"attestation_type" jsonschema:"title=Attestation Type" jsonschema_description:"The attestation format type this credential uses."`
 	Attachment      string     `yaml:"attachment" json:"attachment" jsonschema:"title=Attachment" jsonschema_description:"The last recorded credential attachment type."`
 	Transports      []string   `yaml:"transports" json:"transports" jsonschema:"title=Transports" jsonschema_description:"The last recorded credential transports."`
@@ -331,6 +360,7 @@ type WebAuthnCredentialData struct {
 	Verified        bool       `yaml:"verified" json:"verified" jsonschema:"title=Verified" jsonschema_description:"The verified status of this credential."`
 	BackupEligible  bool       `yaml:"backup_eligible" json:"backup_eligible" jsonschema:"title=Backup Eligible" jsonschema_description:"The backup eligible status of this credential."`
 	BackupState     bool       `yaml:"backup_state" json:"backup_state" jsonschema:"title=Backup Eligible" jsonschema_description:"The backup eligible status of this credential."`
+	SyncStatus      string     `yaml:"-" json:"sync_status" jsonschema:"-"`
 	PublicKey       string     `yaml:"public_key" json:"public_key" jsonschema:"title=Public Key" jsonschema_description:"The credential public key."`
 }
 
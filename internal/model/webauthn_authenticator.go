@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// webAuthnAuthenticatorNames is a list of the human readable names of well known authenticators and passkey providers
+// keyed by the AAGUID they report during registration. The AAGUID only identifies the make and model of an
+// authenticator, and it's only reported when the attestation conveyance preference allows it, so credentials without a
+// known AAGUID fall back to the attachment when they're described to the user.
+var webAuthnAuthenticatorNames = map[uuid.UUID]string{
+	uuid.MustParse("ea9b8d66-4d01-1d21-3ce4-b6b48cb575d4"): "Google Password Manager",
+	uuid.MustParse("adce0002-35bc-c60a-648b-0b25f1f05503"): "Chrome on Mac",
+	uuid.MustParse("fbfc3007-154e-4ecc-8c0b-6e020557d7bd"): "iCloud Keychain",
+	uuid.MustParse("08987058-cadc-4b81-b6e1-30de50dcbe96"): "Windows Hello",
+	uuid.MustParse("9ddd1817-af5a-4672-a2b9-3e3dd95000a9"): "Windows Hello",
+	uuid.MustParse("6028b017-b1d4-4c02-b4b3-afcdafc96bb2"): "Windows Hello",
+	uuid.MustParse("53414d53-554e-4700-0000-000000000000"): "Samsung Pass",
+	uuid.MustParse("bada5566-a7aa-401f-bd96-45619a55120d"): "1Password",
+	uuid.MustParse("d548826e-79b4-db40-a3d8-11116f7e8349"): "Bitwarden",
+	uuid.MustParse("531126d6-e717-415c-9320-3d9aa6981239"): "Dashlane",
+	uuid.MustParse("fdb141b2-5d84-443e-8a35-4698c205a502"): "KeePassXC",
+	uuid.MustParse("cb69481e-8ff7-4039-93ec-0a2729a154a8"): "YubiKey 5 Series",
+	uuid.MustParse("ee882879-721c-4913-9775-3dfcce97072a"): "YubiKey 5 Series",
+	uuid.MustParse("fa2b99dc-9e39-4257-8f92-4a30d23c4118"): "YubiKey 5 Series with NFC",
+	uuid.MustParse("2fc0579f-8113-47ea-b116-bb5a8db9202a"): "YubiKey 5 Series with NFC",
+	uuid.MustParse("c5ef55ff-ad9a-4b9f-b580-adebafe026d0"): "YubiKey 5Ci",
+	uuid.MustParse("149a2021-8ef6-4133-96b8-81f8d5b7f1f5"): "Security Key by Yubico with NFC",
+	uuid.MustParse("a4e9fc6d-4cbe-4758-b8ba-37598bb5bbaa"): "Security Key by Yubico with NFC",
+}
+
+// WebAuthnAuthenticatorName returns the human readable name of the authenticator with the provided AAGUID or an empty
+// string if the AAGUID is absent or not well known.
+func WebAuthnAuthenticatorName(aaguid uuid.NullUUID) string {
+	if !aaguid.Valid {
+		return ""
+	}
+
+	return webAuthnAuthenticatorNames[aaguid.UUID]
+}
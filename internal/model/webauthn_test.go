@@ -179,6 +179,7 @@ func TestWebAuthnCredential_ToData(t *testing.T) {
 				Transports:      []string{"nfc", "usb"},
 				LastUsedAt:      toTimePtr(time.Unix(10, 0)),
 				AttestationType: "fido-u2f",
+				SyncStatus:      model.WebAuthnCredentialSyncStatusDeviceBound,
 			},
 		},
 		{
@@ -202,6 +203,52 @@ func TestWebAuthnCredential_ToData(t *testing.T) {
 				AttestationType: "fido-u2f",
 				PublicKey:       "YWJj",
 				AAGUID:          toStrPtr("b4e159da-a52b-4690-81dd-08972950db5f"),
+				SyncStatus:      model.WebAuthnCredentialSyncStatusDeviceBound,
+			},
+		},
+		{
+			"ShouldParseToDataWithAuthenticator",
+			model.WebAuthnCredential{
+				RPID:            "org.example.com",
+				AttestationType: "packed",
+				AAGUID:          uuid.NullUUID{UUID: uuid.Must(uuid.Parse("cb69481e-8ff7-4039-93ec-0a2729a154a8")), Valid: true},
+			},
+			model.WebAuthnCredentialData{
+				RPID:            "org.example.com",
+				AttestationType: "packed",
+				AAGUID:          toStrPtr("cb69481e-8ff7-4039-93ec-0a2729a154a8"),
+				Authenticator:   "YubiKey 5 Series",
+				SyncStatus:      model.WebAuthnCredentialSyncStatusDeviceBound,
+			},
+		},
+		{
+			"ShouldParseToDataSynced",
+			model.WebAuthnCredential{
+				RPID:            "org.example.com",
+				AttestationType: "none",
+				BackupEligible:  true,
+				BackupState:     true,
+			},
+			model.WebAuthnCredentialData{
+				RPID:            "org.example.com",
+				AttestationType: "none",
+				BackupEligible:  true,
+				BackupState:     true,
+				SyncStatus:      model.WebAuthnCredentialSyncStatusSynced,
+			},
+		},
+		{
+			"ShouldParseToDataNotSynced",
+			model.WebAuthnCredential{
+				RPID:            "org.example.com",
+				AttestationType: "none",
+				BackupEligible:  true,
+			},
+			model.WebAuthnCredentialData{
+				RPID:            "org.example.com",
+				AttestationType: "none",
+				BackupEligible:  true,
+				SyncStatus:      model.WebAuthnCredentialSyncStatusNotSynced,
 			},
 		},
 	}
@@ -221,6 +268,24 @@ func TestWebAuthnCredential_ToData(t *testing.T) {
 	}
 }
 
+func TestWebAuthnAuthenticatorName(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     uuid.NullUUID
+		expected string
+	}{
+		{"ShouldReturnKnownAuthenticator", uuid.NullUUID{UUID: uuid.Must(uuid.Parse("fbfc3007-154e-4ecc-8c0b-6e020557d7bd")), Valid: true}, "iCloud Keychain"},
+		{"ShouldReturnEmptyUnknownAuthenticator", uuid.NullUUID{UUID: uuid.Must(uuid.Parse("b4e159da-a52b-4690-81dd-08972950db5f")), Valid: true}, ""},
+		{"ShouldReturnEmptyNullAAGUID", uuid.NullUUID{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, model.WebAuthnAuthenticatorName(tc.have))
+		})
+	}
+}
+
 func TestWebAuthnCredentialData_ToCredential(t *testing.T) {
 	toTimePtr := func(in time.Time) *time.Time {
 		return &in
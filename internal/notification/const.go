@@ -9,6 +9,23 @@ const (
 	posixNewLine = "\n"
 )
 
+const (
+	smtpDiagnosticsStepRender       = "Render"
+	smtpDiagnosticsStepConnect      = "Connect"
+	smtpDiagnosticsStepTLS          = "TLS"
+	smtpDiagnosticsStepAuthenticate = "Authenticate"
+	smtpDiagnosticsStepSend         = "Send"
+)
+
+const (
+	envAWSEndpointSES = "AWS_ENDPOINT_URL_SES"
+
+	sesService                 = "ses"
+	sesDefaultEndpointTemplate = "https://email.%s.amazonaws.com"
+	sesPathAccount             = "/v2/email/account"
+	sesPathOutboundEmails      = "/v2/email/outbound-emails"
+)
+
 var (
 	posixDoubleNewLine = []byte(posixNewLine + posixNewLine)
 )
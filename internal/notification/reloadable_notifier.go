@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"context"
+	"net/mail"
+	"sync"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// NewReloadableNotifier returns a ReloadableNotifier which delegates to the provided Notifier.
+func NewReloadableNotifier(notifier Notifier) *ReloadableNotifier {
+	return &ReloadableNotifier{
+		notifier: notifier,
+	}
+}
+
+// ReloadableNotifier is a Notifier which delegates to another Notifier that can be replaced at runtime, for example
+// when the configuration is reloaded.
+type ReloadableNotifier struct {
+	notifier Notifier
+
+	mu sync.RWMutex
+}
+
+// Set replaces the Notifier used for all subsequent notifications.
+func (n *ReloadableNotifier) Set(notifier Notifier) {
+	n.mu.Lock()
+
+	defer n.mu.Unlock()
+
+	n.notifier = notifier
+}
+
+// StartupCheck implements the startup check provider interface.
+func (n *ReloadableNotifier) StartupCheck() (err error) {
+	return n.get().StartupCheck()
+}
+
+// HealthCheck implements the health check interface when the current Notifier supports it.
+func (n *ReloadableNotifier) HealthCheck(ctx context.Context) (err error) {
+	if checker, ok := n.get().(model.HealthCheck); ok {
+		return checker.HealthCheck(ctx)
+	}
+
+	return nil
+}
+
+// Send a notification using the current Notifier.
+func (n *ReloadableNotifier) Send(ctx context.Context, recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (err error) {
+	return n.get().Send(ctx, recipient, subject, et, data)
+}
+
+func (n *ReloadableNotifier) get() Notifier {
+	n.mu.RLock()
+
+	defer n.mu.RUnlock()
+
+	return n.notifier
+}
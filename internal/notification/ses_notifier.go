@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	gomail "github.com/wneessen/go-mail"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/secrets"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// NewSESNotifier creates a SESNotifier using the notifier configuration. The credentials are provided by
+// secrets.NewAWSCredentialsProviderFromEnvironment.
+func NewSESNotifier(config *schema.NotifierSES) *SESNotifier {
+	log := logging.Logger().WithFields(map[string]any{"provider": "notifier"})
+
+	region := config.Region
+
+	if region == "" {
+		region = secrets.AWSRegionFromEnvironment()
+	}
+
+	var endpoint string
+
+	switch {
+	case config.Endpoint != nil:
+		endpoint = config.Endpoint.String()
+	case os.Getenv(envAWSEndpointSES) != "":
+		endpoint = os.Getenv(envAWSEndpointSES)
+	default:
+		endpoint = fmt.Sprintf(sesDefaultEndpointTemplate, region)
+	}
+
+	client := &http.Client{Timeout: config.Timeout}
+
+	domain := "localhost.localdomain"
+
+	if at := strings.LastIndex(config.Sender.Address, "@"); at >= 0 {
+		domain = config.Sender.Address[at+1:]
+	}
+
+	log.WithFields(map[string]any{
+		"region":   region,
+		"endpoint": endpoint,
+		"timeout":  config.Timeout.Seconds(),
+		"domain":   domain,
+	}).Trace("Configuring Provider")
+
+	return &SESNotifier{
+		config:      config,
+		region:      region,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		domain:      domain,
+		client:      client,
+		credentials: secrets.NewAWSCredentialsProviderFromEnvironment(client, clock.New()),
+		clock:       clock.New(),
+		random:      &random.Cryptographical{},
+		log:         log,
+	}
+}
+
+// SESNotifier a notifier to send emails using the Amazon Simple Email Service API.
+type SESNotifier struct {
+	config      *schema.NotifierSES
+	region      string
+	endpoint    string
+	domain      string
+	client      *http.Client
+	credentials secrets.AWSCredentialsProvider
+	clock       clock.Provider
+	random      random.Provider
+	log         *logrus.Entry
+}
+
+// StartupCheck implements model.StartupCheck to perform startup check operations.
+func (n *SESNotifier) StartupCheck() (err error) {
+	return n.HealthCheck(context.Background())
+}
+
+// HealthCheck implements model.HealthCheck to perform health check operations. The account is retrieved which
+// ensures the credentials are valid and permitted to use the API without sending an email.
+func (n *SESNotifier) HealthCheck(ctx context.Context) (err error) {
+	if err = n.do(ctx, http.MethodGet, sesPathAccount, nil); err != nil {
+		return fmt.Errorf("failed to retrieve the account: %w", err)
+	}
+
+	return nil
+}
+
+// Send a notification via the SESNotifier.
+func (n *SESNotifier) Send(ctx context.Context, recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (err error) {
+	var msg *gomail.Msg
+
+	if msg, err = n.newMessage(recipient, subject, et, data); err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+
+	if _, err = msg.WriteTo(buf); err != nil {
+		return fmt.Errorf("notifier: ses: failed to write message: %w", err)
+	}
+
+	request := sesSendEmailRequest{
+		FromEmailAddress:     n.config.Sender.String(),
+		ConfigurationSetName: n.config.ConfigurationSet,
+	}
+
+	request.Destination.ToAddresses = []string{recipient.String()}
+	request.Content.Raw.Data = buf.Bytes()
+
+	var body []byte
+
+	if body, err = json.Marshal(&request); err != nil {
+		return fmt.Errorf("notifier: ses: failed to encode request: %w", err)
+	}
+
+	if err = n.do(ctx, http.MethodPost, sesPathOutboundEmails, body); err != nil {
+		return fmt.Errorf("notifier: ses: failed to send message: %w", err)
+	}
+
+	return nil
+}
+
+func (n *SESNotifier) do(ctx context.Context, method, path string, body []byte) (err error) {
+	if n.region == "" {
+		return fmt.Errorf("the aws region must be configured using either the region option or the 'AWS_REGION' environment variable")
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, method, n.endpoint+path, bytes.NewReader(body)); err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if err = secrets.SignAWSRequest(ctx, req, body, n.credentials, n.region, sesService, n.clock.Now()); err != nil {
+		return err
+	}
+
+	var resp *http.Response
+
+	if resp, err = n.client.Do(req); err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("%s returned status code %d: %s", path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	return nil
+}
+
+func (n *SESNotifier) newMessage(recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (msg *gomail.Msg, err error) {
+	msg = gomail.NewMsg(
+		gomail.WithMIMEVersion(gomail.MIME10),
+		gomail.WithBoundary(n.random.StringCustom(30, random.CharSetAlphaNumeric)),
+	)
+
+	msg.SetMessageIDWithValue(fmt.Sprintf("%d.%s@%s", os.Getpid(), n.random.StringCustom(24, random.CharSetAlphaNumeric), n.domain))
+	msg.SetDate()
+
+	if err = msg.From(n.config.Sender.String()); err != nil {
+		return nil, fmt.Errorf("notifier: ses: failed to set from address: %w", err)
+	}
+
+	if err = msg.AddTo(recipient.String()); err != nil {
+		return nil, fmt.Errorf("notifier: ses: failed to set to address: %w", err)
+	}
+
+	msg.Subject(strings.ReplaceAll(n.config.Subject, "{title}", subject))
+
+	switch {
+	case n.config.DisableHTMLEmails:
+		if err = msg.SetBodyTextTemplate(et.Text, data); err != nil {
+			return nil, fmt.Errorf("notifier: ses: failed to set body: text template errored: %w", err)
+		}
+	default:
+		if err = msg.AddAlternativeTextTemplate(et.Text, data); err != nil {
+			return nil, fmt.Errorf("notifier: ses: failed to set body: text template errored: %w", err)
+		}
+
+		if err = msg.AddAlternativeHTMLTemplate(et.HTML, data); err != nil {
+			return nil, fmt.Errorf("notifier: ses: failed to set body: html template errored: %w", err)
+		}
+	}
+
+	return msg, nil
+}
+
+// sesSendEmailRequest is the request of the SendEmail action of the Amazon Simple Email Service API version 2 with raw
+// content. The raw data is encoded as base64 by encoding/json.
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Raw struct {
+			Data []byte `json:"Data"`
+		} `json:"Raw"`
+	} `json:"Content"`
+	ConfigurationSetName string `json:"ConfigurationSetName,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/mail"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/sirupsen/logrus"
 	gomail "github.com/wneessen/go-mail"
 
+	"github.com/authelia/authelia/v4/internal/breaker"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/random"
@@ -104,10 +106,22 @@ type SMTPNotifier struct {
 	tls    *tls.Config
 	log    *logrus.Entry
 	opts   []gomail.Option
+
+	breaker *breaker.Breaker
+}
+
+// SetCircuitBreaker sets the circuit breaker used when delivering the messages to the SMTP server.
+func (n *SMTPNotifier) SetCircuitBreaker(b *breaker.Breaker) {
+	n.breaker = b
 }
 
 // StartupCheck implements model.StartupCheck to perform startup check operations.
 func (n *SMTPNotifier) StartupCheck() (err error) {
+	return n.HealthCheck(context.Background())
+}
+
+// HealthCheck implements model.HealthCheck to perform health check operations.
+func (n *SMTPNotifier) HealthCheck(ctx context.Context) (err error) {
 	var client *gomail.Client
 
 	n.log.WithFields(map[string]any{"hostname": n.config.Address.Hostname()}).Trace("Creating Startup Check Client")
@@ -116,8 +130,6 @@ func (n *SMTPNotifier) StartupCheck() (err error) {
 		return fmt.Errorf("failed to establish client: %w", err)
 	}
 
-	ctx := context.Background()
-
 	n.log.Trace("Dialing Startup Check Connection")
 
 	if auth := NewOpportunisticSMTPAuth(n.config); auth != nil {
@@ -139,7 +151,49 @@ func (n *SMTPNotifier) StartupCheck() (err error) {
 
 // Send a notification via the SMTPNotifier.
 func (n *SMTPNotifier) Send(ctx context.Context, recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (err error) {
-	msg := gomail.NewMsg(
+	var msg *gomail.Msg
+
+	if msg, err = n.newMessage(recipient, subject, et, data); err != nil {
+		return err
+	}
+
+	if err = n.breaker.Do(ctx, func(ctx context.Context) (err error) {
+		return n.send(ctx, msg)
+	}); err != nil {
+		if errors.Is(err, breaker.ErrOpen) {
+			return fmt.Errorf("notifier: smtp: failed to send message: %w", err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) send(ctx context.Context, msg *gomail.Msg) (err error) {
+	var client *gomail.Client
+
+	if client, err = n.newClient(); err != nil {
+		return fmt.Errorf("notifier: smtp: failed to establish client: %w", err)
+	}
+
+	if err = client.DialWithContext(ctx); err != nil {
+		return fmt.Errorf("notifier: smtp: failed to dial connection: %w", err)
+	}
+
+	if err = client.Send(msg); err != nil {
+		return fmt.Errorf("notifier: smtp: failed to send message: %w", err)
+	}
+
+	if err = client.Close(); err != nil {
+		return fmt.Errorf("notifier: smtp: failed to close connection: %w", err)
+	}
+
+	return nil
+}
+
+func (n *SMTPNotifier) newMessage(recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (msg *gomail.Msg, err error) {
+	msg = gomail.NewMsg(
 		gomail.WithMIMEVersion(gomail.MIME10),
 		gomail.WithBoundary(n.random.StringCustom(30, random.CharSetAlphaNumeric)),
 	)
@@ -147,11 +201,11 @@ func (n *SMTPNotifier) Send(ctx context.Context, recipient mail.Address, subject
 	n.setMessageID(msg, n.domain)
 
 	if err = msg.From(n.config.Sender.String()); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to set from address: %w", err)
+		return nil, fmt.Errorf("notifier: smtp: failed to set from address: %w", err)
 	}
 
 	if err = msg.AddTo(recipient.String()); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to set to address: %w", err)
+		return nil, fmt.Errorf("notifier: smtp: failed to set to address: %w", err)
 	}
 
 	msg.Subject(strings.ReplaceAll(n.config.Subject, "{title}", subject))
@@ -159,41 +213,31 @@ func (n *SMTPNotifier) Send(ctx context.Context, recipient mail.Address, subject
 	switch {
 	case n.config.DisableHTMLEmails:
 		if err = msg.SetBodyTextTemplate(et.Text, data); err != nil {
-			return fmt.Errorf("notifier: smtp: failed to set body: text template errored: %w", err)
+			return nil, fmt.Errorf("notifier: smtp: failed to set body: text template errored: %w", err)
 		}
 	default:
 		if err = msg.AddAlternativeTextTemplate(et.Text, data); err != nil {
-			return fmt.Errorf("notifier: smtp: failed to set body: text template errored: %w", err)
+			return nil, fmt.Errorf("notifier: smtp: failed to set body: text template errored: %w", err)
 		}
 
 		if err = msg.AddAlternativeHTMLTemplate(et.HTML, data); err != nil {
-			return fmt.Errorf("notifier: smtp: failed to set body: html template errored: %w", err)
+			return nil, fmt.Errorf("notifier: smtp: failed to set body: html template errored: %w", err)
 		}
 	}
 
-	var client *gomail.Client
+	return msg, nil
+}
 
-	if client, err = gomail.NewClient(n.config.Address.Hostname(), n.opts...); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to establish client: %w", err)
+func (n *SMTPNotifier) newClient(opts ...gomail.Option) (client *gomail.Client, err error) {
+	if client, err = gomail.NewClient(n.config.Address.Hostname(), append(append([]gomail.Option{}, n.opts...), opts...)...); err != nil {
+		return nil, err
 	}
 
 	if auth := NewOpportunisticSMTPAuth(n.config); auth != nil {
 		client.SetSMTPAuthCustom(auth)
 	}
 
-	if err = client.DialWithContext(ctx); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to dial connection: %w", err)
-	}
-
-	if err = client.Send(msg); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to send message: %w", err)
-	}
-
-	if err = client.Close(); err != nil {
-		return fmt.Errorf("notifier: smtp: failed to close connection: %w", err)
-	}
-
-	return nil
+	return client, nil
 }
 
 func (n *SMTPNotifier) setMessageID(msg *gomail.Msg, domain string) {
@@ -0,0 +1,178 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+
+	gomail "github.com/wneessen/go-mail"
+	"github.com/wneessen/go-mail/smtp"
+
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// SMTPDiagnosticsStep is the outcome of a single step of sending a message while diagnosing the SMTPNotifier.
+type SMTPDiagnosticsStep struct {
+	Name   string
+	Detail string
+	Err    error
+}
+
+// Diagnose sends a message via the SMTPNotifier in the same way as Send while recording the outcome of each step of the
+// delivery, i.e. rendering the templates, connecting to the server, negotiating TLS, authenticating, and sending. The
+// steps are returned even when an error occurs so the steps which completed successfully can be inspected.
+func (n *SMTPNotifier) Diagnose(ctx context.Context, recipient mail.Address, subject string, et *templates.EmailTemplate, data any) (steps []SMTPDiagnosticsStep, err error) {
+	var msg *gomail.Msg
+
+	if msg, err = n.newMessage(recipient, subject, et, data); err != nil {
+		return append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepRender, Err: err}), err
+	}
+
+	format := "text and html"
+
+	if n.config.DisableHTMLEmails {
+		format = "text"
+	}
+
+	steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepRender, Detail: fmt.Sprintf("rendered the %s templates for the message from '%s' to '%s'", format, n.config.Sender.String(), recipient.String())})
+
+	recorder := &smtpDiagnosticsRecorder{}
+
+	tlsconfig := n.tls
+
+	if tlsconfig == nil {
+		tlsconfig = &tls.Config{ServerName: n.config.Address.Hostname(), MinVersion: tls.VersionTLS12} //nolint:gosec // Matches the default of the client.
+	}
+
+	tlsconfig = tlsconfig.Clone()
+
+	verify := tlsconfig.VerifyConnection
+
+	tlsconfig.VerifyConnection = func(state tls.ConnectionState) error {
+		recorder.tls = &state
+
+		if verify != nil {
+			return verify(state)
+		}
+
+		return nil
+	}
+
+	var client *gomail.Client
+
+	if client, err = n.newClient(gomail.WithTLSConfig(tlsconfig), gomail.WithDialContextFunc(recorder.dialer(n.config.Address.IsExplicitlySecure(), n.config.Timeout, tlsconfig))); err != nil {
+		return append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepConnect, Err: err}), fmt.Errorf("notifier: smtp: failed to establish client: %w", err)
+	}
+
+	if auth := NewOpportunisticSMTPAuth(n.config); auth != nil {
+		recorder.auth = auth
+
+		client.SetSMTPAuthCustom(recorder)
+	}
+
+	err = client.DialWithContext(ctx)
+
+	steps = append(steps, recorder.steps(n, err)...)
+
+	if err != nil {
+		return steps, fmt.Errorf("notifier: smtp: failed to dial connection: %w", err)
+	}
+
+	if err = client.Send(msg); err != nil {
+		return append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepSend, Err: err}), fmt.Errorf("notifier: smtp: failed to send message: %w", err)
+	}
+
+	steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepSend, Detail: "the server accepted the message"})
+
+	if err = client.Close(); err != nil {
+		return steps, fmt.Errorf("notifier: smtp: failed to close connection: %w", err)
+	}
+
+	return steps, nil
+}
+
+// smtpDiagnosticsRecorder records the progress of the connection so the step which failed can be determined.
+type smtpDiagnosticsRecorder struct {
+	address   string
+	tls       *tls.ConnectionState
+	auth      smtp.Auth
+	mechanism string
+	started   bool
+}
+
+func (r *smtpDiagnosticsRecorder) dialer(implicit bool, timeout time.Duration, config *tls.Config) gomail.DialContextFunc {
+	return func(ctx context.Context, network, address string) (conn net.Conn, err error) {
+		dialer := &net.Dialer{Timeout: timeout}
+
+		if implicit {
+			conn, err = (&tls.Dialer{NetDialer: dialer, Config: config}).DialContext(ctx, network, address)
+		} else {
+			conn, err = dialer.DialContext(ctx, network, address)
+		}
+
+		if err == nil {
+			r.address = conn.RemoteAddr().String()
+		}
+
+		return conn, err
+	}
+}
+
+// Start implements smtp.Auth.
+func (r *smtpDiagnosticsRecorder) Start(server *smtp.ServerInfo) (proto string, toServer []byte, err error) {
+	r.started = true
+
+	proto, toServer, err = r.auth.Start(server)
+
+	r.mechanism = proto
+
+	return proto, toServer, err
+}
+
+// Next implements smtp.Auth.
+func (r *smtpDiagnosticsRecorder) Next(fromServer []byte, more bool) (toServer []byte, err error) {
+	return r.auth.Next(fromServer, more)
+}
+
+// steps returns the connect, TLS, and authenticate steps given the error returned when dialing the connection.
+func (r *smtpDiagnosticsRecorder) steps(n *SMTPNotifier, err error) (steps []SMTPDiagnosticsStep) {
+	if r.address == "" {
+		return []SMTPDiagnosticsStep{{Name: smtpDiagnosticsStepConnect, Err: err}}
+	}
+
+	steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepConnect, Detail: fmt.Sprintf("connected to '%s' and identified as '%s'", r.address, n.config.Identifier)})
+
+	switch {
+	case r.tls != nil:
+		detail := fmt.Sprintf("negotiated %s with the cipher suite %s", tls.VersionName(r.tls.Version), tls.CipherSuiteName(r.tls.CipherSuite))
+
+		if len(r.tls.PeerCertificates) != 0 {
+			certificate := r.tls.PeerCertificates[0]
+
+			detail += fmt.Sprintf(" and the certificate for '%s' issued by '%s' which expires %s", certificate.Subject, certificate.Issuer, certificate.NotAfter.UTC().Format(time.RFC3339))
+		}
+
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepTLS, Detail: detail})
+	case err != nil && !r.started:
+		return append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepTLS, Err: err})
+	case n.config.DisableStartTLS:
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepTLS, Detail: "skipped as StartTLS is disabled, the connection is not encrypted"})
+	default:
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepTLS, Detail: "skipped as the server does not support StartTLS, the connection is not encrypted"})
+	}
+
+	switch {
+	case err != nil:
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepAuthenticate, Err: err})
+	case r.auth == nil:
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepAuthenticate, Detail: "skipped as no username or password is configured"})
+	default:
+		steps = append(steps, SMTPDiagnosticsStep{Name: smtpDiagnosticsStepAuthenticate, Detail: fmt.Sprintf("authenticated as '%s' using the %s mechanism", n.config.Username, strings.ToUpper(r.mechanism))})
+	}
+
+	return steps
+}
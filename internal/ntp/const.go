@@ -1,7 +1,11 @@
 package ntp
 
+import "time"
+
 const ntpEpochOffset = 2208988800
 
+const ntpQueryTimeout = 5 * time.Second
+
 const (
 	ntpV3 ntpVersion = iota
 	ntpV4
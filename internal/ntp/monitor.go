@@ -0,0 +1,80 @@
+package ntp
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// NewMonitor returns a new Monitor which checks the clock skew using the provider at the configured interval.
+func NewMonitor(provider *Provider, metrics MetricsRecorder, log *logrus.Entry) *Monitor {
+	return &Monitor{
+		provider: provider,
+		metrics:  metrics,
+		log:      log,
+	}
+}
+
+// Monitor periodically checks the clock skew at runtime so a clock which drifts after startup is detected. The clock
+// skew measured by the Monitor is used by the other providers via Provider.ClockSkew.
+type Monitor struct {
+	provider *Provider
+	metrics  MetricsRecorder
+	log      *logrus.Entry
+
+	exceeded bool
+}
+
+// Run the Monitor until the context is canceled.
+func (m *Monitor) Run(ctx context.Context) {
+	m.log.WithField("interval", m.provider.config.Interval.String()).Debug("Checking the clock skew periodically")
+
+	ticker := time.NewTicker(m.provider.config.Interval)
+
+	defer ticker.Stop()
+
+	m.check(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			m.check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Monitor) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, ntpQueryTimeout)
+
+	defer cancel()
+
+	result, err := m.provider.Check(ctx)
+
+	if m.metrics != nil {
+		m.metrics.RecordNTPCheck(err == nil, result.Skew, result.Responded)
+	}
+
+	if err != nil {
+		m.log.WithError(err).Warn("Could not determine the clock skew due to an error")
+
+		return
+	}
+
+	log := m.log.WithFields(map[string]any{"skew": result.Skew.String(), "max_desync": m.provider.config.MaximumDesync.String(), "servers": result.Servers, "responded": result.Responded})
+
+	exceeded := result.Offset() > m.provider.config.MaximumDesync
+
+	switch {
+	case exceeded:
+		log.Warn("The system clock skew exceeds the maximum desync which will cause one-time passwords and tokens to be rejected, the TOTP validation window is widened until the system clock is synchronized")
+	case m.exceeded:
+		log.Info("The system clock skew is within the maximum desync again")
+	default:
+		log.Trace("The system clock skew is within the maximum desync")
+	}
+
+	m.exceeded = exceeded
+}
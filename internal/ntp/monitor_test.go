@@ -0,0 +1,193 @@
+package ntp
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestProviderCheck(t *testing.T) {
+	testCases := []struct {
+		name      string
+		skews     []time.Duration
+		down      int
+		quorum    int
+		expected  time.Duration
+		responded int
+		err       string
+	}{
+		{"ShouldUseMedian", []time.Duration{time.Second, time.Second * 2, time.Second * 3}, 0, 2, time.Second * 2, 3, ""},
+		{"ShouldUseMedianEven", []time.Duration{-time.Second, time.Second}, 0, 2, 0, 2, ""},
+		{"ShouldTolerateDownServer", []time.Duration{time.Second * 10, time.Second * 10}, 1, 2, time.Second * 10, 2, ""},
+		{"ShouldErrNoQuorum", []time.Duration{time.Second}, 2, 2, 0, 1, "only 1 of the 3 NTP servers responded but the quorum is 2: "},
+		{"ShouldErrDisagree", []time.Duration{0, time.Minute, time.Hour}, 0, 2, time.Minute, 3, "only 1 of the 3 NTP servers agree on the time within the maximum desync but the quorum is 2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &schema.NTP{Version: 4, MaximumDesync: time.Second * 3, Quorum: tc.quorum}
+
+			for _, skew := range tc.skews {
+				config.Servers = append(config.Servers, newTestNTPServer(t, skew))
+			}
+
+			for i := 0; i < tc.down; i++ {
+				config.Servers = append(config.Servers, newTestNTPServerDown(t))
+			}
+
+			provider := NewProvider(config)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+
+			defer cancel()
+
+			result, err := provider.Check(ctx)
+
+			assert.Equal(t, len(tc.skews)+tc.down, result.Servers)
+			assert.Equal(t, tc.responded, result.Responded)
+
+			skew, exceeded := provider.ClockSkew()
+
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.err)
+				assert.Equal(t, time.Duration(0), skew)
+				assert.False(t, exceeded)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, float64(tc.expected), float64(result.Skew), float64(time.Millisecond*100))
+			assert.InDelta(t, float64(tc.expected), float64(skew), float64(time.Millisecond*100))
+			assert.Equal(t, tc.expected > config.MaximumDesync, exceeded)
+		})
+	}
+}
+
+func TestProviderShouldReturnSingleServerError(t *testing.T) {
+	config := &schema.NTP{Version: 4, MaximumDesync: time.Second * 3, Quorum: 1, Servers: []*schema.AddressUDP{newTestNTPServerDown(t)}}
+
+	provider := NewProvider(config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*200)
+
+	defer cancel()
+
+	_, err := provider.Check(ctx)
+
+	assert.ErrorContains(t, err, "error occurred reading ntp packet response to the connection")
+}
+
+func TestMonitor(t *testing.T) {
+	config := &schema.NTP{Version: 4, MaximumDesync: time.Second * 3, Quorum: 1, Interval: time.Hour, Servers: []*schema.AddressUDP{newTestNTPServer(t, -time.Second*10)}}
+
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.TraceLevel)
+
+	recorder := &testMetricsRecorder{}
+
+	monitor := NewMonitor(NewProvider(config), recorder, logrus.NewEntry(logger))
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		monitor.Run(ctx)
+
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(hook.AllEntries()) >= 2
+	}, time.Second, time.Millisecond*10)
+
+	cancel()
+
+	<-done
+
+	require.Len(t, recorder.checks, 1)
+	assert.True(t, recorder.checks[0].success)
+	assert.Equal(t, 1, recorder.checks[0].responded)
+	assert.InDelta(t, float64(-time.Second*10), float64(recorder.checks[0].skew), float64(time.Millisecond*100))
+
+	assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	assert.Contains(t, hook.LastEntry().Message, "The system clock skew exceeds the maximum desync")
+	assert.True(t, monitor.exceeded)
+
+	_, exceeded := monitor.provider.ClockSkew()
+
+	assert.True(t, exceeded)
+}
+
+func newTestNTPServer(t *testing.T, skew time.Duration) *schema.AddressUDP {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	go func() {
+		buf := make([]byte, 48)
+
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			now := time.Now().Add(skew)
+
+			resp := &ntpPacket{
+				TxTimeSeconds:  uint32(now.Unix() + ntpEpochOffset),
+				TxTimeFraction: uint32((int64(now.Nanosecond()) << 32) / 1e9),
+			}
+
+			b := &bytes.Buffer{}
+
+			_ = binary.Write(b, binary.BigEndian, resp)
+
+			_, _ = conn.WriteTo(b.Bytes(), addr)
+		}
+	}()
+
+	return &schema.AddressUDP{Address: schema.NewAddressFromNetworkValues(schema.AddressSchemeUDP, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port)}
+}
+
+// newTestNTPServerDown returns the address of a server which never responds.
+func newTestNTPServerDown(t *testing.T) *schema.AddressUDP {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return &schema.AddressUDP{Address: schema.NewAddressFromNetworkValues(schema.AddressSchemeUDP, "127.0.0.1", conn.LocalAddr().(*net.UDPAddr).Port)}
+}
+
+type testMetricsCheck struct {
+	success   bool
+	skew      time.Duration
+	responded int
+}
+
+type testMetricsRecorder struct {
+	checks []testMetricsCheck
+}
+
+func (r *testMetricsRecorder) RecordNTPCheck(success bool, skew time.Duration, responded int) {
+	r.checks = append(r.checks, testMetricsCheck{success, skew, responded})
+}
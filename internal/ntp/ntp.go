@@ -1,10 +1,13 @@
 package ntp
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"net"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -21,16 +24,31 @@ func NewProvider(config *schema.NTP) *Provider {
 
 // StartupCheck implements the startup check provider interface.
 func (p *Provider) StartupCheck() (err error) {
-	var offset time.Duration
+	var result Result
 
-	if offset, err = p.GetOffset(); err != nil {
+	if result, err = p.Check(context.Background()); err != nil {
 		p.log.WithError(err).Warnf("Could not determine the clock offset due to an error")
 
 		return nil
 	}
 
-	if offset > p.config.MaximumDesync {
-		return errors.New("the system clock is not synchronized accurately enough with the configured NTP server")
+	if result.Offset() > p.config.MaximumDesync {
+		return errors.New("the system clock is not synchronized accurately enough with the configured NTP servers")
+	}
+
+	return nil
+}
+
+// HealthCheck implements the health check provider interface.
+func (p *Provider) HealthCheck(ctx context.Context) (err error) {
+	var result Result
+
+	if result, err = p.Check(ctx); err != nil {
+		return err
+	}
+
+	if result.Offset() > p.config.MaximumDesync {
+		return errors.New("the system clock is not synchronized accurately enough with the configured NTP servers")
 	}
 
 	return nil
@@ -38,20 +56,118 @@ func (p *Provider) StartupCheck() (err error) {
 
 // GetOffset returns the current offset for this provider.
 func (p *Provider) GetOffset() (offset time.Duration, err error) {
+	var result Result
+
+	if result, err = p.Check(context.Background()); err != nil {
+		return offset, err
+	}
+
+	return result.Offset(), nil
+}
+
+// ClockSkew returns the most recently measured clock skew and if it exceeds the maximum desync. The skew is zero and
+// never exceeds the maximum desync until the clock skew has been successfully measured at least once.
+func (p *Provider) ClockSkew() (skew time.Duration, exceeded bool) {
+	if !p.measured.Load() {
+		return 0, false
+	}
+
+	skew = time.Duration(p.skew.Load())
+
+	return skew, ntpAbsDuration(skew) > p.config.MaximumDesync
+}
+
+// Check queries all of the configured servers concurrently and returns the median clock skew of the servers which
+// responded. An error is returned if fewer servers than the quorum responded, or if fewer servers than the quorum
+// agree with the median within the maximum desync.
+func (p *Provider) Check(ctx context.Context) (result Result, err error) {
+	servers := Servers(p.config)
+
+	result.Servers = len(servers)
+
+	skews, errs := make([]time.Duration, len(servers)), make([]error, len(servers))
+
+	wg := sync.WaitGroup{}
+
+	for i, server := range servers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			skews[i], errs[i] = p.query(ctx, server)
+		}()
+	}
+
+	wg.Wait()
+
+	responded := make([]time.Duration, 0, len(servers))
+
+	for i, server := range servers {
+		if errs[i] != nil {
+			p.log.WithError(errs[i]).WithField("server", server.String()).Debug("Error occurred querying the NTP server")
+
+			continue
+		}
+
+		responded = append(responded, skews[i])
+	}
+
+	result.Responded = len(responded)
+
+	quorum := max(p.config.Quorum, 1)
+
+	switch {
+	case len(servers) == 1 && len(responded) == 0:
+		return result, errs[0]
+	case len(responded) < quorum:
+		return result, fmt.Errorf("only %d of the %d NTP servers responded but the quorum is %d: %w", len(responded), len(servers), quorum, errors.Join(errs...))
+	}
+
+	result.Skew = ntpMedian(responded)
+
+	agree := 0
+
+	for _, skew := range responded {
+		if ntpAbsDuration(skew-result.Skew) <= p.config.MaximumDesync {
+			agree++
+		}
+	}
+
+	if agree < quorum {
+		return result, fmt.Errorf("only %d of the %d NTP servers agree on the time within the maximum desync but the quorum is %d", agree, len(servers), quorum)
+	}
+
+	p.skew.Store(int64(result.Skew))
+	p.measured.Store(true)
+
+	return result, nil
+}
+
+// query returns the clock skew against an individual server.
+func (p *Provider) query(ctx context.Context, server *schema.AddressUDP) (skew time.Duration, err error) {
 	var conn net.Conn
 
-	if conn, err = net.Dial(p.config.Address.Network(), p.config.Address.NetworkAddress()); err != nil {
-		return offset, fmt.Errorf("error occurred during dial: %w", err)
+	dialer := &net.Dialer{}
+
+	if conn, err = dialer.DialContext(ctx, server.Network(), server.NetworkAddress()); err != nil {
+		return skew, fmt.Errorf("error occurred during dial: %w", err)
 	}
 
 	defer func() {
 		if closeErr := conn.Close(); closeErr != nil {
-			p.log.WithError(err).Error("Error occurred closing connection with NTP sever")
+			p.log.WithError(closeErr).Error("Error occurred closing connection with NTP sever")
 		}
 	}()
 
-	if err = conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
-		return offset, fmt.Errorf("error occurred setting connection deadline: %w", err)
+	deadline := time.Now().Add(ntpQueryTimeout)
+
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err = conn.SetDeadline(deadline); err != nil {
+		return skew, fmt.Errorf("error occurred setting connection deadline: %w", err)
 	}
 
 	version := ntpV4
@@ -62,7 +178,7 @@ func (p *Provider) GetOffset() (offset time.Duration, err error) {
 	req := &ntpPacket{LeapVersionMode: ntpLeapVersionClientMode(version)}
 
 	if err = binary.Write(conn, binary.BigEndian, req); err != nil {
-		return offset, fmt.Errorf("error occurred writing ntp packet request to the connection: %w", err)
+		return skew, fmt.Errorf("error occurred writing ntp packet request to the connection: %w", err)
 	}
 
 	now := time.Now()
@@ -70,10 +186,43 @@ func (p *Provider) GetOffset() (offset time.Duration, err error) {
 	resp := &ntpPacket{}
 
 	if err = binary.Read(conn, binary.BigEndian, resp); err != nil {
-		return offset, fmt.Errorf("error occurred reading ntp packet response to the connection: %w", err)
+		return skew, fmt.Errorf("error occurred reading ntp packet response to the connection: %w", err)
 	}
 
 	ntpTime := ntpPacketToTime(resp)
 
 	return calcOffset(now, ntpTime), nil
 }
+
+// Servers returns the addresses of all of the configured servers.
+func Servers(config *schema.NTP) (servers []*schema.AddressUDP) {
+	if config.Address != nil {
+		servers = append(servers, config.Address)
+	}
+
+	for _, server := range config.Servers {
+		if server != nil {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers
+}
+
+func ntpMedian(skews []time.Duration) time.Duration {
+	sorted := make([]time.Duration, len(skews))
+
+	copy(sorted, skews)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	n := len(sorted)
+
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
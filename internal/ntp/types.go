@@ -1,6 +1,9 @@
 package ntp
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -10,6 +13,32 @@ import (
 type Provider struct {
 	config *schema.NTP
 	log    *logrus.Logger
+
+	skew     atomic.Int64
+	measured atomic.Bool
+}
+
+// Result represents the result of checking the clock skew against the NTP servers.
+type Result struct {
+	// Skew is the difference between the time agreed on by the NTP servers and the system clock. It's positive when
+	// the system clock is behind and negative when the system clock is ahead.
+	Skew time.Duration
+
+	// Servers is the number of NTP servers which were queried.
+	Servers int
+
+	// Responded is the number of NTP servers which responded.
+	Responded int
+}
+
+// Offset returns the absolute value of the clock skew.
+func (r Result) Offset() time.Duration {
+	return ntpAbsDuration(r.Skew)
+}
+
+// MetricsRecorder represents the methods used to record the clock skew.
+type MetricsRecorder interface {
+	RecordNTPCheck(success bool, skew time.Duration, responded int)
 }
 
 type ntpVersion int
@@ -39,10 +39,16 @@ func ntpIsOffsetTooLarge(maxOffset time.Duration, first, second time.Time) (tooL
 	return offset > maxOffset
 }
 
-func calcOffset(first, second time.Time) time.Duration {
-	if first.After(second) {
-		return first.Sub(second)
+// calcOffset returns the signed offset of the remote time from the local time, which is positive when the local time is
+// behind the remote time.
+func calcOffset(local, remote time.Time) time.Duration {
+	return remote.Sub(local)
+}
+
+func ntpAbsDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
 	}
 
-	return second.Sub(first)
+	return d
 }
@@ -9,6 +9,8 @@ func NewAuthenticationMethodsReferencesFromClaim(claim []string) (amr Authentica
 			amr.TOTP = true
 		case AMRShortMessageService:
 			amr.Duo = true
+		case AMRTelephone:
+			amr.Voice = true
 		case AMRProofOfPossession:
 			amr.WebAuthn = true
 		case AMRHardwareSecuredKey:
@@ -18,6 +20,8 @@ func NewAuthenticationMethodsReferencesFromClaim(claim []string) (amr Authentica
 			amr.WebAuthn = true
 			amr.WebAuthnSoftware = true
 		case AMRUserPresence:
+			amr.WebAuthnUserPresence = true
+		case AMRPersonalIdentificationNumber:
 			amr.WebAuthnUserVerified = true
 		}
 	}
@@ -30,11 +34,16 @@ type AuthenticationMethodsReferences struct {
 	UsernameAndPassword  bool
 	TOTP                 bool
 	Duo                  bool
+	Push                 bool
 	WebAuthn             bool
 	WebAuthnHardware     bool
 	WebAuthnSoftware     bool
 	WebAuthnUserPresence bool
 	WebAuthnUserVerified bool
+	RecoveryCode         bool
+	SMS                  bool
+	Voice                bool
+	Email                bool
 }
 
 // FactorKnowledge returns true if a "something you know" factor of authentication was used.
@@ -44,7 +53,7 @@ func (r AuthenticationMethodsReferences) FactorKnowledge() bool {
 
 // FactorPossession returns true if a "something you have" factor of authentication was used.
 func (r AuthenticationMethodsReferences) FactorPossession() bool {
-	return r.TOTP || r.Duo || r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware
+	return r.TOTP || r.Duo || r.Push || r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware || r.RecoveryCode || r.SMS || r.Voice || r.Email
 }
 
 // MultiFactorAuthentication returns true if multiple factors were used.
@@ -54,12 +63,12 @@ func (r AuthenticationMethodsReferences) MultiFactorAuthentication() bool {
 
 // ChannelBrowser returns true if a browser was used to authenticate.
 func (r AuthenticationMethodsReferences) ChannelBrowser() bool {
-	return r.UsernameAndPassword || r.TOTP || r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware
+	return r.UsernameAndPassword || r.TOTP || r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware || r.RecoveryCode
 }
 
 // ChannelService returns true if a non-browser service was used to authenticate.
 func (r AuthenticationMethodsReferences) ChannelService() bool {
-	return r.Duo
+	return r.Duo || r.Push || r.SMS || r.Voice || r.Email
 }
 
 // MultiChannelAuthentication returns true if the user used more than one channel to authenticate.
@@ -76,15 +85,19 @@ func (r AuthenticationMethodsReferences) MarshalRFC8176() []string {
 		amr = append(amr, AMRPasswordBasedAuthentication)
 	}
 
-	if r.TOTP {
+	if r.TOTP || r.RecoveryCode || r.SMS || r.Voice || r.Email {
 		amr = append(amr, AMROneTimePassword)
 	}
 
-	if r.Duo {
+	if r.Duo || r.SMS {
 		amr = append(amr, AMRShortMessageService)
 	}
 
-	if r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware {
+	if r.Voice {
+		amr = append(amr, AMRTelephone)
+	}
+
+	if r.WebAuthn || r.WebAuthnHardware || r.WebAuthnSoftware || r.Push {
 		amr = append(amr, AMRProofOfPossession)
 	}
 
@@ -23,7 +23,17 @@ func TestNewAuthenticationMethodsReferencesFromClaim(t *testing.T) {
 		{
 			"ShouldHandleWebAuthnHardware",
 			[]string{"pop", "hwk", "mca", "mfa", "pwd", "sms", "user"},
-			oidc.AuthenticationMethodsReferences{WebAuthn: true, WebAuthnHardware: true, UsernameAndPassword: true, Duo: true, WebAuthnUserVerified: true},
+			oidc.AuthenticationMethodsReferences{WebAuthn: true, WebAuthnHardware: true, UsernameAndPassword: true, Duo: true, WebAuthnUserPresence: true},
+		},
+		{
+			"ShouldHandleWebAuthnUserVerified",
+			[]string{"pop", "hwk", "mca", "mfa", "pwd", "user", "pin"},
+			oidc.AuthenticationMethodsReferences{WebAuthn: true, WebAuthnHardware: true, UsernameAndPassword: true, WebAuthnUserPresence: true, WebAuthnUserVerified: true},
+		},
+		{
+			"ShouldHandleVoice",
+			[]string{"mca", "mfa", "pwd", "otp", "tel"},
+			oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, TOTP: true, Voice: true},
 		},
 	}
 
@@ -70,6 +80,62 @@ func TestAuthenticationMethodsReferences(t *testing.T) {
 				RFC8176:                    []string{"otp"},
 			},
 		},
+		{
+			desc: "Recovery Code",
+
+			is: oidc.AuthenticationMethodsReferences{RecoveryCode: true},
+			want: testAMRWant{
+				FactorKnowledge:            false,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  false,
+				ChannelBrowser:             true,
+				ChannelService:             false,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"otp"},
+			},
+		},
+		{
+			desc: "SMS",
+
+			is: oidc.AuthenticationMethodsReferences{SMS: true},
+			want: testAMRWant{
+				FactorKnowledge:            false,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  false,
+				ChannelBrowser:             false,
+				ChannelService:             true,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"otp", "sms"},
+			},
+		},
+		{
+			desc: "Voice",
+
+			is: oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, Voice: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             true,
+				MultiChannelAuthentication: true,
+				RFC8176:                    []string{"pwd", "otp", "tel", "mfa", "mca"},
+			},
+		},
+		{
+			desc: "Email",
+
+			is: oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, Email: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             true,
+				MultiChannelAuthentication: true,
+				RFC8176:                    []string{"pwd", "otp", "mfa", "mca"},
+			},
+		},
 		{
 			desc: "WebAuthn",
 
@@ -154,6 +220,34 @@ func TestAuthenticationMethodsReferences(t *testing.T) {
 				RFC8176:                    []string{"sms"},
 			},
 		},
+		{
+			desc: "Push",
+
+			is: oidc.AuthenticationMethodsReferences{Push: true},
+			want: testAMRWant{
+				FactorKnowledge:            false,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  false,
+				ChannelBrowser:             false,
+				ChannelService:             true,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"pop"},
+			},
+		},
+		{
+			desc: "Username and Password with Push",
+
+			is: oidc.AuthenticationMethodsReferences{Push: true, UsernameAndPassword: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             true,
+				MultiChannelAuthentication: true,
+				RFC8176:                    []string{"pwd", "pop", "mfa", "mca"},
+			},
+		},
 		{
 			desc: "Duo WebAuthn TOTP",
 
@@ -0,0 +1,422 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"authelia.com/provider/oauth2/handler/oauth2"
+	"authelia.com/provider/oauth2/handler/openid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// BackchannelAuthenticator describes an out-of-band authenticator which the user approves or denies OpenID Connect 1.0
+// Client Initiated Backchannel Authentication requests with, such as a push notification to an enrolled device.
+type BackchannelAuthenticator interface {
+	// StartBackchannelAuthentication asks the user to approve a request on their enrolled device and returns the
+	// transaction identifier which the status of the request can later be retrieved with.
+	StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error)
+
+	// GetBackchannelAuthenticationStatus returns the model.OAuth2CIBAStatusPending, model.OAuth2CIBAStatusApproved,
+	// or model.OAuth2CIBAStatusDenied status of a transaction.
+	GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error)
+}
+
+// BackchannelAuthenticationStorage describes the storage requirements of the BackchannelAuthenticationGrantHandler.
+type BackchannelAuthenticationStorage interface {
+	oauth2.AccessTokenStorage
+	oauth2.RefreshTokenStorage
+
+	// GetBackchannelAuthenticationSession returns the stored request and original requester given an auth_req_id
+	// signature.
+	GetBackchannelAuthenticationSession(ctx context.Context, signature string, session oauthelia2.Session) (request *model.OAuth2CIBARequest, r oauthelia2.Requester, err error)
+
+	// UpdateBackchannelAuthenticationSessionCheckedAt records the time the client last polled for a request.
+	UpdateBackchannelAuthenticationSessionCheckedAt(ctx context.Context, signature string, checkedAt time.Time) (err error)
+
+	// InvalidateBackchannelAuthenticationSession prevents a request from being exchanged for tokens again.
+	InvalidateBackchannelAuthenticationSession(ctx context.Context, signature string) (err error)
+}
+
+// BackchannelAuthenticationResponse is the successful response of the backchannel authentication endpoint.
+//
+// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.7.3
+type BackchannelAuthenticationResponse struct {
+	AuthRequestID string `json:"auth_req_id"`
+	ExpiresIn     int64  `json:"expires_in"`
+	Interval      int64  `json:"interval,omitempty"`
+}
+
+// BackchannelAuthenticationSignature returns the signature an auth_req_id is stored with.
+func BackchannelAuthenticationSignature(authReqID string) (signature string) {
+	sum := sha256.Sum256([]byte(authReqID))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// BackchannelAuthenticationGrantHandler is an oauthelia2.TokenEndpointHandler which implements the OpenID Connect 1.0
+// Client Initiated Backchannel Authentication grant. The client exchanges the auth_req_id returned from the backchannel
+// authentication endpoint for tokens once the user has approved the request on their enrolled device.
+//
+// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.10.1
+type BackchannelAuthenticationGrantHandler struct {
+	AccessTokenStrategy  oauth2.AccessTokenStrategy
+	RefreshTokenStrategy oauth2.RefreshTokenStrategy
+	IDTokenHandleHelper  *openid.IDTokenHandleHelper
+	Storage              BackchannelAuthenticationStorage
+	Config               interface {
+		oauthelia2.AccessTokenLifespanProvider
+		oauthelia2.RefreshTokenLifespanProvider
+		oauthelia2.RefreshTokenScopesProvider
+		oauthelia2.IDTokenLifespanProvider
+		GetBackchannelAuthenticationPollingInterval(ctx context.Context) time.Duration
+	}
+}
+
+// HandleTokenEndpointRequest implements oauthelia2.TokenEndpointHandler.
+func (h *BackchannelAuthenticationGrantHandler) HandleTokenEndpointRequest(ctx context.Context, requester oauthelia2.AccessRequester) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return oauthelia2.ErrUnknownRequest
+	}
+
+	client := requester.GetClient()
+
+	if client.IsPublic() {
+		return oauthelia2.ErrInvalidGrant.WithHintf("The OAuth 2.0 Client is marked as public and is thus not allowed to use the authorization grant '%s'.", GrantTypeCIBA)
+	}
+
+	if !client.GetGrantTypes().Has(GrantTypeCIBA) {
+		return oauthelia2.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", GrantTypeCIBA)
+	}
+
+	var (
+		request  *model.OAuth2CIBARequest
+		original oauthelia2.Requester
+	)
+
+	if request, original, err = h.getBackchannelAuthenticationSession(ctx, requester); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	switch {
+	case request.IsExpired(now):
+		return ErrExpiredToken
+	case request.Status == model.OAuth2CIBAStatusDenied:
+		return oauthelia2.ErrAccessDenied.WithHint("The end-user denied the authentication request.")
+	case request.IsPending():
+		if err = h.Storage.UpdateBackchannelAuthenticationSessionCheckedAt(ctx, request.Signature, now); err != nil {
+			return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to update the backchannel authentication request with error: %s.", err.Error())
+		}
+
+		if now.Sub(request.CheckedAt) < h.Config.GetBackchannelAuthenticationPollingInterval(ctx) {
+			return ErrSlowDown
+		}
+
+		return ErrAuthorizationPending
+	}
+
+	for _, scope := range original.GetGrantedScopes() {
+		requester.GrantScope(scope)
+	}
+
+	for _, audience := range original.GetGrantedAudience() {
+		requester.GrantAudience(audience)
+	}
+
+	session, ok := original.GetSession().(*Session)
+	if !ok {
+		return oauthelia2.ErrServerError.WithDebugf("Failed to restore the session of the backchannel authentication request as the session type '%T' is not supported.", original.GetSession())
+	}
+
+	if request.AuthenticatedAt.Valid {
+		session.Claims.AuthTime = request.AuthenticatedAt.Time.UTC()
+	}
+
+	session.SetExpiresAt(oauthelia2.AccessToken, now.Add(oauthelia2.GetEffectiveLifespan(client, GrantTypeCIBA, oauthelia2.AccessToken, h.Config.GetAccessTokenLifespan(ctx))).Round(time.Second))
+
+	if lifespan := oauthelia2.GetEffectiveLifespan(client, GrantTypeCIBA, oauthelia2.RefreshToken, h.Config.GetRefreshTokenLifespan(ctx)); lifespan > -1 {
+		session.SetExpiresAt(oauthelia2.RefreshToken, now.Add(lifespan).Round(time.Second))
+	}
+
+	requester.SetID(original.GetID())
+	requester.SetSession(session)
+
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements oauthelia2.TokenEndpointHandler.
+func (h *BackchannelAuthenticationGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester oauthelia2.AccessRequester, responder oauthelia2.AccessResponder) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return oauthelia2.ErrUnknownRequest
+	}
+
+	var (
+		token, signature string
+		request          *model.OAuth2CIBARequest
+	)
+
+	if request, _, err = h.getBackchannelAuthenticationSession(ctx, requester); err != nil {
+		return err
+	}
+
+	if request.Status != model.OAuth2CIBAStatusApproved {
+		return oauthelia2.ErrInvalidGrant.WithHintf("The '%s' has not been approved by the end-user.", FormParameterAuthRequestID)
+	}
+
+	// The auth_req_id is invalidated before any token is issued so it can only ever be exchanged once.
+	if err = h.Storage.InvalidateBackchannelAuthenticationSession(ctx, request.Signature); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to invalidate the backchannel authentication request with error: %s.", err.Error())
+	}
+
+	if token, signature, err = h.AccessTokenStrategy.GenerateAccessToken(ctx, requester); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to generate the access token with error: %s.", err.Error())
+	}
+
+	if err = h.Storage.CreateAccessTokenSession(ctx, signature, requester.Sanitize([]string{})); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to save the access token with error: %s.", err.Error())
+	}
+
+	client := requester.GetClient()
+
+	if requester.GetGrantedScopes().HasOneOf(h.Config.GetRefreshTokenScopes(ctx)...) && client.GetGrantTypes().Has(GrantTypeRefreshToken) {
+		var refresh string
+
+		if refresh, signature, err = h.RefreshTokenStrategy.GenerateRefreshToken(ctx, requester); err != nil {
+			return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to generate the refresh token with error: %s.", err.Error())
+		}
+
+		if err = h.Storage.CreateRefreshTokenSession(ctx, signature, requester.Sanitize([]string{})); err != nil {
+			return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to save the refresh token with error: %s.", err.Error())
+		}
+
+		responder.SetExtra(oauthelia2.RefreshToken.String(), refresh)
+	}
+
+	responder.SetAccessToken(token)
+	responder.SetTokenType(oauthelia2.BearerAccessToken)
+	responder.SetExpiresIn(time.Until(requester.GetSession().GetExpiresAt(oauthelia2.AccessToken)).Round(time.Second))
+	responder.SetScopes(requester.GetGrantedScopes())
+
+	if requester.GetGrantedScopes().Has(ScopeOpenID) {
+		lifespan := oauthelia2.GetEffectiveLifespan(client, GrantTypeCIBA, oauthelia2.IDToken, h.Config.GetIDTokenLifespan(ctx))
+
+		if err = h.IDTokenHandleHelper.IssueExplicitIDToken(ctx, lifespan, requester, responder); err != nil {
+			return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to generate the ID token with error: %s.", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// CanSkipClientAuth implements oauthelia2.TokenEndpointHandler.
+func (h *BackchannelAuthenticationGrantHandler) CanSkipClientAuth(ctx context.Context, requester oauthelia2.AccessRequester) (skip bool) {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements oauthelia2.TokenEndpointHandler.
+func (h *BackchannelAuthenticationGrantHandler) CanHandleTokenEndpointRequest(ctx context.Context, requester oauthelia2.AccessRequester) (handle bool) {
+	return requester.GetGrantTypes().ExactOne(GrantTypeCIBA)
+}
+
+func (h *BackchannelAuthenticationGrantHandler) getBackchannelAuthenticationSession(ctx context.Context, requester oauthelia2.AccessRequester) (request *model.OAuth2CIBARequest, original oauthelia2.Requester, err error) {
+	authReqID := requester.GetRequestForm().Get(FormParameterAuthRequestID)
+
+	if len(authReqID) == 0 {
+		return nil, nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be provided.", FormParameterAuthRequestID)
+	}
+
+	if request, original, err = h.Storage.GetBackchannelAuthenticationSession(ctx, BackchannelAuthenticationSignature(authReqID), NewSession()); err != nil {
+		switch {
+		case errors.Is(err, oauthelia2.ErrNotFound), errors.Is(err, oauthelia2.ErrInactiveToken):
+			return nil, nil, oauthelia2.ErrInvalidGrant.WithHintf("The '%s' is not valid or has already been used.", FormParameterAuthRequestID).WithWrap(err)
+		default:
+			return nil, nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to lookup the backchannel authentication request with error: %s.", err.Error())
+		}
+	}
+
+	if original.GetClient().GetID() != requester.GetClient().GetID() {
+		return nil, nil, oauthelia2.ErrInvalidGrant.WithHintf("The '%s' was not issued to the OAuth 2.0 Client.", FormParameterAuthRequestID)
+	}
+
+	return request, original, nil
+}
+
+// NewBackchannelAuthenticationController creates a new BackchannelAuthenticationController.
+func NewBackchannelAuthenticationController(store *Store, authenticator BackchannelAuthenticator, client *http.Client, interval time.Duration, clock clock.Provider, log *logrus.Entry) *BackchannelAuthenticationController {
+	return &BackchannelAuthenticationController{
+		store:         store,
+		authenticator: authenticator,
+		client:        client,
+		interval:      interval,
+		clock:         clock,
+		log:           log,
+	}
+}
+
+// BackchannelAuthenticationController periodically retrieves the status of the pending OpenID Connect 1.0 Client
+// Initiated Backchannel Authentication requests from the BackchannelAuthenticator and notifies the clients which use the
+// ping token delivery mode once the user has approved or denied the request.
+type BackchannelAuthenticationController struct {
+	store         *Store
+	authenticator BackchannelAuthenticator
+	client        *http.Client
+	interval      time.Duration
+	clock         clock.Provider
+	log           *logrus.Entry
+}
+
+// Run the BackchannelAuthenticationController until the context is canceled.
+func (c *BackchannelAuthenticationController) Run(ctx context.Context) {
+	c.log.WithFields(map[string]any{"interval": c.interval.String()}).Debug("Checking the pending backchannel authentication requests periodically")
+
+	ticker := time.NewTicker(c.interval)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.check(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *BackchannelAuthenticationController) check(ctx context.Context) {
+	now := c.clock.Now()
+
+	requests, err := c.store.provider.LoadOAuth2CIBARequestsPending(ctx, now, backchannelAuthenticationBatchSize)
+	if err != nil {
+		c.log.WithError(err).Error("Error occurred loading the pending backchannel authentication requests")
+
+		return
+	}
+
+	for i := range requests {
+		request := &requests[i]
+
+		log := c.log.WithFields(map[string]any{"request_id": request.RequestID, "client_id": request.ClientID, "username": request.Username})
+
+		if request.IsPending() {
+			if err = c.update(ctx, request); err != nil {
+				log.WithError(err).Error("Error occurred checking the status of the backchannel authentication request")
+
+				continue
+			}
+		}
+
+		if request.IsPending() || request.DeliveryMode != BackchannelTokenDeliveryModePing || request.Notified {
+			continue
+		}
+
+		if err = c.notify(ctx, request); err != nil {
+			log.WithError(err).Error("Error occurred notifying the client of the completed backchannel authentication request")
+
+			continue
+		}
+
+		log.Debug("Notified the client of the completed backchannel authentication request")
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (c *BackchannelAuthenticationController) update(ctx context.Context, request *model.OAuth2CIBARequest) (err error) {
+	if !request.TransactionID.Valid {
+		return fmt.Errorf("the request does not have a transaction identifier")
+	}
+
+	var status int
+
+	if status, err = c.authenticator.GetBackchannelAuthenticationStatus(ctx, request.TransactionID.String); err != nil {
+		return err
+	}
+
+	if status == model.OAuth2CIBAStatusPending {
+		return nil
+	}
+
+	var authenticatedAt sql.NullTime
+
+	if status == model.OAuth2CIBAStatusApproved {
+		authenticatedAt = sql.NullTime{Time: c.clock.Now(), Valid: true}
+	}
+
+	if err = c.store.provider.UpdateOAuth2CIBARequestStatus(ctx, request.Signature, status, authenticatedAt); err != nil {
+		return err
+	}
+
+	request.Status, request.AuthenticatedAt = status, authenticatedAt
+
+	return nil
+}
+
+func (c *BackchannelAuthenticationController) notify(ctx context.Context, request *model.OAuth2CIBARequest) (err error) {
+	var (
+		data   *model.OAuth2CIBARequestData
+		client Client
+		body   []byte
+	)
+
+	if data, err = request.GetData(); err != nil {
+		return err
+	}
+
+	if data.Notification == nil {
+		return fmt.Errorf("the request does not have any notification data")
+	}
+
+	if client, err = c.store.GetRegisteredClient(ctx, request.ClientID); err != nil {
+		return err
+	}
+
+	endpoint := client.GetBackchannelClientNotificationEndpoint()
+
+	if len(endpoint) == 0 {
+		return fmt.Errorf("the client does not have a backchannel client notification endpoint")
+	}
+
+	if body, err = json.Marshal(map[string]string{FormParameterAuthRequestID: data.Notification.AuthRequestID}); err != nil {
+		return err
+	}
+
+	var (
+		req  *http.Request
+		resp *http.Response
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body)); err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+data.Notification.Token)
+
+	if resp, err = c.client.Do(req); err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("the client notification endpoint responded with status code %d", resp.StatusCode)
+	}
+
+	return c.store.provider.UpdateOAuth2CIBARequestNotified(ctx, request.Signature)
+}
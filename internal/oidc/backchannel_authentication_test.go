@@ -0,0 +1,84 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func TestBackchannelAuthenticationSignature(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", oidc.BackchannelAuthenticationSignature("hello"))
+	assert.NotEqual(t, oidc.BackchannelAuthenticationSignature("hello"), oidc.BackchannelAuthenticationSignature("hello2"))
+}
+
+func TestBackchannelAuthenticationGrantHandler_CanHandleTokenEndpointRequest(t *testing.T) {
+	handler := &oidc.BackchannelAuthenticationGrantHandler{}
+
+	assert.True(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeCIBA}}))
+	assert.False(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeDeviceCode}}))
+	assert.False(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeCIBA, oidc.GrantTypeRefreshToken}}))
+	assert.False(t, handler.CanSkipClientAuth(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeCIBA}}))
+}
+
+func TestBackchannelAuthenticationGrantHandler_HandleTokenEndpointRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		client   *oidc.RegisteredClient
+		form     url.Values
+		grant    string
+		expected string
+	}{
+		{
+			"ShouldNotHandleOtherGrantTypes",
+			&oidc.RegisteredClient{ID: "call-center", GrantTypes: []string{oidc.GrantTypeCIBA}},
+			url.Values{},
+			oidc.GrantTypeClientCredentials,
+			"The handler is not responsible for this request.",
+		},
+		{
+			"ShouldRejectPublicClients",
+			&oidc.RegisteredClient{ID: "call-center", Public: true, GrantTypes: []string{oidc.GrantTypeCIBA}},
+			url.Values{},
+			oidc.GrantTypeCIBA,
+			"The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client. The OAuth 2.0 Client is marked as public and is thus not allowed to use the authorization grant 'urn:openid:params:grant-type:ciba'.",
+		},
+		{
+			"ShouldRejectClientsWithoutGrantType",
+			&oidc.RegisteredClient{ID: "call-center", GrantTypes: []string{oidc.GrantTypeClientCredentials}},
+			url.Values{},
+			oidc.GrantTypeCIBA,
+			"The client is not authorized to request a token using this method. The OAuth 2.0 Client is not allowed to use the authorization grant 'urn:openid:params:grant-type:ciba'.",
+		},
+		{
+			"ShouldRejectMissingAuthRequestID",
+			&oidc.RegisteredClient{ID: "call-center", GrantTypes: []string{oidc.GrantTypeCIBA}},
+			url.Values{},
+			oidc.GrantTypeCIBA,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'auth_req_id' parameter must be provided.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &oidc.BackchannelAuthenticationGrantHandler{}
+
+			requester := &oauthelia2.AccessRequest{
+				GrantTypes: oauthelia2.Arguments{tc.grant},
+				Request: oauthelia2.Request{
+					Client:  tc.client,
+					Form:    tc.form,
+					Session: oidc.NewSession(),
+				},
+			}
+
+			err := handler.HandleTokenEndpointRequest(context.Background(), requester)
+
+			assert.Equal(t, tc.expected, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	oauthelia2 "authelia.com/provider/oauth2"
 	"authelia.com/provider/oauth2/x/errorsx"
 	jose "github.com/go-jose/go-jose/v4"
+	"github.com/go-webauthn/webauthn/protocol"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
@@ -35,10 +36,15 @@ func NewClient(config schema.IdentityProvidersOpenIDConnectClient, c *schema.Ide
 		PKCEChallengeMethod:        config.PKCEChallengeMethod,
 
 		RequirePushedAuthorizationRequests:      config.RequirePushedAuthorizationRequests,
+		DPoPBoundAccessTokens:                   config.DPoPBoundAccessTokens,
+		BackchannelTokenDeliveryMode:            config.BackchannelTokenDeliveryMode,
+		BackchannelClientNotificationEndpoint:   config.BackchannelClientNotificationEndpoint,
 		ClientCredentialsFlowAllowImplicitScope: false,
 		AllowMultipleAuthenticationMethods:      config.AllowMultipleAuthenticationMethods,
 
-		AuthorizationPolicy:   NewClientAuthorizationPolicy(config.AuthorizationPolicy, c),
+		AuthorizationPolicy:     NewClientAuthorizationPolicy(config.AuthorizationPolicy, c),
+		RequireUserVerification: config.WebAuthnUserVerification == string(protocol.VerificationRequired),
+
 		ConsentPolicy:         NewClientConsentPolicy(config.ConsentMode, config.ConsentPreConfiguredDuration),
 		RequestedAudienceMode: NewClientRequestedAudienceMode(config.RequestedAudienceMode),
 
@@ -425,6 +431,31 @@ func (c *RegisteredClient) GetRequirePushedAuthorizationRequests() (require bool
 	return c.RequirePushedAuthorizationRequests
 }
 
+// GetDPoPBoundAccessTokens returns true if this client MUST use DPoP to obtain sender-constrained access tokens.
+func (c *RegisteredClient) GetDPoPBoundAccessTokens() (require bool) {
+	return c.DPoPBoundAccessTokens
+}
+
+// GetBackchannelTokenDeliveryMode returns the Client Initiated Backchannel Authentication token delivery mode for
+// this client.
+func (c *RegisteredClient) GetBackchannelTokenDeliveryMode() (mode string) {
+	if c.BackchannelTokenDeliveryMode == "" {
+		return BackchannelTokenDeliveryModePoll
+	}
+
+	return c.BackchannelTokenDeliveryMode
+}
+
+// GetBackchannelClientNotificationEndpoint returns the endpoint notified when a Client Initiated Backchannel
+// Authentication request is completed using the ping token delivery mode.
+func (c *RegisteredClient) GetBackchannelClientNotificationEndpoint() (endpoint string) {
+	if c.BackchannelClientNotificationEndpoint == nil {
+		return ""
+	}
+
+	return c.BackchannelClientNotificationEndpoint.String()
+}
+
 // GetPushedAuthorizeContextLifespan should return a custom lifespan or a duration of 0 seconds to utilize the
 // global lifespan.
 func (c *RegisteredClient) GetPushedAuthorizeContextLifespan() (lifespan time.Duration) {
@@ -486,6 +517,16 @@ func (c *RegisteredClient) GetAuthorizationPolicy() (policy ClientAuthorizationP
 	return c.AuthorizationPolicy
 }
 
+// IsUserVerificationSufficient returns false if the client requires the WebAuthn assertion to verify the user when the
+// authorization policy requires two factor authentication and the AuthenticationMethodsReferences indicate it didn't.
+func (c *RegisteredClient) IsUserVerificationSufficient(amr AuthenticationMethodsReferences, subject authorization.Subject) (sufficient bool) {
+	if !c.RequireUserVerification || c.GetAuthorizationPolicyRequiredLevel(subject) != authorization.TwoFactor {
+		return true
+	}
+
+	return amr.WebAuthnUserVerified
+}
+
 // IsPublic returns the value of the Public property.
 func (c *RegisteredClient) IsPublic() (public bool) {
 	return c.Public
@@ -674,6 +715,10 @@ func (c *RegisteredClient) getGrantTypeLifespan(gt oauthelia2.GrantType) (gtl sc
 		return c.Lifespans.Grants.RefreshToken
 	case oauthelia2.GrantTypeJWTBearer:
 		return c.Lifespans.Grants.JWTBearer
+	case oauthelia2.GrantTypeDeviceCode:
+		return c.Lifespans.Grants.DeviceCode
+	case GrantTypeTokenExchange:
+		return c.Lifespans.Grants.TokenExchange
 	default:
 		return gtl
 	}
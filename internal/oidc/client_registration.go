@@ -0,0 +1,346 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	jose "github.com/go-jose/go-jose/v4"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+var (
+	clientRegistrationTokenEndpointAuthMethods = []string{ClientAuthMethodNone, ClientAuthMethodClientSecretBasic, ClientAuthMethodClientSecretPost, ClientAuthMethodPrivateKeyJWT}
+
+	clientRegistrationTokenEndpointAuthSigningAlgs = []string{
+		SigningAlgRSAUsingSHA256, SigningAlgRSAUsingSHA384, SigningAlgRSAUsingSHA512,
+		SigningAlgRSAPSSUsingSHA256, SigningAlgRSAPSSUsingSHA384, SigningAlgRSAPSSUsingSHA512,
+		SigningAlgECDSAUsingP256AndSHA256, SigningAlgECDSAUsingP384AndSHA384, SigningAlgECDSAUsingP521AndSHA512,
+	}
+
+	clientRegistrationURIMetadata = []string{"client_uri", "logo_uri", "policy_uri", "tos_uri", "jwks_uri"}
+)
+
+// ClientRegistrationMetadata represents the client metadata of a request to the Dynamic Client Registration endpoint
+// or the Dynamic Client Configuration endpoint.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc7591#section-2
+type ClientRegistrationMetadata struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+
+	ClientName      string   `json:"client_name,omitempty"`
+	ClientURI       string   `json:"client_uri,omitempty"`
+	LogoURI         string   `json:"logo_uri,omitempty"`
+	PolicyURI       string   `json:"policy_uri,omitempty"`
+	TOSURI          string   `json:"tos_uri,omitempty"`
+	Contacts        []string `json:"contacts,omitempty"`
+	SoftwareID      string   `json:"software_id,omitempty"`
+	SoftwareVersion string   `json:"software_version,omitempty"`
+
+	RedirectURIs  []string `json:"redirect_uris,omitempty"`
+	GrantTypes    []string `json:"grant_types,omitempty"`
+	ResponseTypes []string `json:"response_types,omitempty"`
+	Scope         string   `json:"scope,omitempty"`
+
+	TokenEndpointAuthMethod     string `json:"token_endpoint_auth_method,omitempty"`
+	TokenEndpointAuthSigningAlg string `json:"token_endpoint_auth_signing_alg,omitempty"`
+
+	JSONWebKeysURI string              `json:"jwks_uri,omitempty"`
+	JSONWebKeys    *jose.JSONWebKeySet `json:"jwks,omitempty"`
+
+	IDTokenSignedResponseAlg  string `json:"id_token_signed_response_alg,omitempty"`
+	UserinfoSignedResponseAlg string `json:"userinfo_signed_response_alg,omitempty"`
+}
+
+// ClientRegistrationResponse represents the response of the Dynamic Client Registration endpoint and the Dynamic
+// Client Configuration endpoint.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.1 and
+// https://datatracker.ietf.org/doc/html/rfc7592#section-3
+type ClientRegistrationResponse struct {
+	ClientRegistrationMetadata
+
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at,omitempty"`
+	ClientSecretExpiresAt   int64  `json:"client_secret_expires_at"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+}
+
+// IsPublic returns true if the client metadata describes a public client.
+func (m *ClientRegistrationMetadata) IsPublic() bool {
+	return m.TokenEndpointAuthMethod == ClientAuthMethodNone
+}
+
+// Validate validates the client metadata against the Dynamic Client Registration configuration and sets the default
+// values for any metadata which was not provided.
+//
+//nolint:gocyclo
+func (m *ClientRegistrationMetadata) Validate(config *schema.IdentityProvidersOpenIDConnect) (err error) {
+	dcr := config.DynamicClientRegistration
+
+	if m.JSONWebKeys != nil {
+		return ErrInvalidClientMetadata.WithHint("The 'jwks' client metadata is not supported, the 'jwks_uri' client metadata must be used instead.")
+	}
+
+	if len(m.GrantTypes) == 0 {
+		m.GrantTypes = []string{GrantTypeAuthorizationCode}
+	}
+
+	for _, grantType := range m.GrantTypes {
+		if !utils.IsStringInSlice(grantType, dcr.GrantTypes) {
+			return ErrInvalidClientMetadata.WithHintf("The grant type '%s' is not permitted for dynamically registered clients.", grantType)
+		}
+	}
+
+	if dcr.OpenRegistration && (len(m.GrantTypes) != 1 || m.GrantTypes[0] != GrantTypeAuthorizationCode) {
+		return ErrInvalidClientMetadata.WithHintf("The 'grant_types' client metadata must only contain '%s' when the registration is open.", GrantTypeAuthorizationCode)
+	}
+
+	code := utils.IsStringInSlice(GrantTypeAuthorizationCode, m.GrantTypes)
+
+	switch {
+	case code && len(m.ResponseTypes) == 0:
+		m.ResponseTypes = []string{ResponseTypeAuthorizationCodeFlow}
+	case code && (len(m.ResponseTypes) != 1 || m.ResponseTypes[0] != ResponseTypeAuthorizationCodeFlow):
+		return ErrInvalidClientMetadata.WithHintf("The 'response_types' client metadata must only contain '%s'.", ResponseTypeAuthorizationCodeFlow)
+	case !code && len(m.ResponseTypes) != 0:
+		return ErrInvalidClientMetadata.WithHintf("The 'response_types' client metadata must not be provided without the '%s' grant type.", GrantTypeAuthorizationCode)
+	}
+
+	switch {
+	case m.TokenEndpointAuthMethod == "" && dcr.OpenRegistration:
+		m.TokenEndpointAuthMethod = ClientAuthMethodNone
+	case m.TokenEndpointAuthMethod == "":
+		m.TokenEndpointAuthMethod = ClientAuthMethodClientSecretBasic
+	case dcr.OpenRegistration && m.TokenEndpointAuthMethod != ClientAuthMethodNone:
+		return ErrInvalidClientMetadata.WithHintf("The 'token_endpoint_auth_method' client metadata must be '%s' when the registration is open.", ClientAuthMethodNone)
+	case !utils.IsStringInSlice(m.TokenEndpointAuthMethod, clientRegistrationTokenEndpointAuthMethods):
+		return ErrInvalidClientMetadata.WithHintf("The 'token_endpoint_auth_method' client metadata must be one of %s.", utils.StringJoinOr(clientRegistrationTokenEndpointAuthMethods))
+	}
+
+	switch m.TokenEndpointAuthMethod {
+	case ClientAuthMethodNone:
+		if utils.IsStringInSlice(GrantTypeClientCredentials, m.GrantTypes) {
+			return ErrInvalidClientMetadata.WithHintf("The grant type '%s' is not permitted for public clients.", GrantTypeClientCredentials)
+		}
+	case ClientAuthMethodPrivateKeyJWT:
+		if m.JSONWebKeysURI == "" {
+			return ErrInvalidClientMetadata.WithHintf("The 'jwks_uri' client metadata is required when the 'token_endpoint_auth_method' is '%s'.", ClientAuthMethodPrivateKeyJWT)
+		}
+
+		if m.TokenEndpointAuthSigningAlg == "" {
+			m.TokenEndpointAuthSigningAlg = SigningAlgRSAUsingSHA256
+		}
+	}
+
+	if m.TokenEndpointAuthSigningAlg != "" {
+		if m.TokenEndpointAuthMethod != ClientAuthMethodPrivateKeyJWT {
+			return ErrInvalidClientMetadata.WithHintf("The 'token_endpoint_auth_signing_alg' client metadata must only be provided when the 'token_endpoint_auth_method' is '%s'.", ClientAuthMethodPrivateKeyJWT)
+		}
+
+		if !utils.IsStringInSlice(m.TokenEndpointAuthSigningAlg, clientRegistrationTokenEndpointAuthSigningAlgs) {
+			return ErrInvalidClientMetadata.WithHintf("The 'token_endpoint_auth_signing_alg' client metadata must be one of %s.", utils.StringJoinOr(clientRegistrationTokenEndpointAuthSigningAlgs))
+		}
+	}
+
+	if err = m.validateScope(dcr); err != nil {
+		return err
+	}
+
+	if err = m.validateRedirectURIs(code); err != nil {
+		return err
+	}
+
+	if err = m.validateURIs(); err != nil {
+		return err
+	}
+
+	return m.validateSigningAlgs(config)
+}
+
+func (m *ClientRegistrationMetadata) validateScope(dcr schema.IdentityProvidersOpenIDConnectDynamicClientRegistration) (err error) {
+	scopes := oauthelia2.RemoveEmpty(strings.Split(m.Scope, " "))
+
+	if len(scopes) == 0 {
+		scopes = dcr.Scopes
+	}
+
+	for _, scope := range scopes {
+		if !utils.IsStringInSlice(scope, dcr.Scopes) {
+			return ErrInvalidClientMetadata.WithHintf("The scope '%s' is not permitted for dynamically registered clients.", scope)
+		}
+	}
+
+	if utils.IsStringInSlice(GrantTypeRefreshToken, m.GrantTypes) && !utils.IsStringSliceContainsAny([]string{ScopeOfflineAccess, ScopeOffline}, scopes) {
+		return ErrInvalidClientMetadata.WithHintf("The '%s' grant type requires the '%s' scope.", GrantTypeRefreshToken, ScopeOfflineAccess)
+	}
+
+	m.Scope = strings.Join(scopes, " ")
+
+	return nil
+}
+
+func (m *ClientRegistrationMetadata) validateRedirectURIs(code bool) (err error) {
+	if code && len(m.RedirectURIs) == 0 {
+		return ErrInvalidRedirectURI.WithHintf("The 'redirect_uris' client metadata is required when using the '%s' grant type.", GrantTypeAuthorizationCode)
+	}
+
+	var uri *url.URL
+
+	for _, redirectURI := range m.RedirectURIs {
+		if uri, err = url.Parse(redirectURI); err != nil || !uri.IsAbs() {
+			return ErrInvalidRedirectURI.WithHintf("The redirect URI '%s' must be an absolute URI.", redirectURI)
+		}
+
+		switch {
+		case uri.Fragment != "":
+			return ErrInvalidRedirectURI.WithHintf("The redirect URI '%s' must not contain a fragment.", redirectURI)
+		case uri.Scheme == "https":
+			continue
+		case uri.Scheme == "http":
+			if !isLoopbackHostname(uri.Hostname()) {
+				return ErrInvalidRedirectURI.WithHintf("The redirect URI '%s' must use the 'https' scheme unless it's a loopback address.", redirectURI)
+			}
+		case !m.IsPublic():
+			return ErrInvalidRedirectURI.WithHintf("The redirect URI '%s' uses the private-use scheme '%s' which is only permitted for public clients.", redirectURI, uri.Scheme)
+		}
+	}
+
+	return nil
+}
+
+func (m *ClientRegistrationMetadata) validateURIs() (err error) {
+	var uri *url.URL
+
+	for i, value := range []string{m.ClientURI, m.LogoURI, m.PolicyURI, m.TOSURI, m.JSONWebKeysURI} {
+		if value == "" {
+			continue
+		}
+
+		if uri, err = url.ParseRequestURI(value); err != nil || uri.Scheme != "https" {
+			return ErrInvalidClientMetadata.WithHintf("The '%s' client metadata must be an absolute URI with the 'https' scheme.", clientRegistrationURIMetadata[i])
+		}
+	}
+
+	return nil
+}
+
+func (m *ClientRegistrationMetadata) validateSigningAlgs(config *schema.IdentityProvidersOpenIDConnect) (err error) {
+	switch {
+	case m.IDTokenSignedResponseAlg == "":
+		m.IDTokenSignedResponseAlg = SigningAlgRSAUsingSHA256
+	case !utils.IsStringInSlice(m.IDTokenSignedResponseAlg, config.Discovery.ResponseObjectSigningAlgs):
+		return ErrInvalidClientMetadata.WithHintf("The 'id_token_signed_response_alg' client metadata must be one of %s.", utils.StringJoinOr(config.Discovery.ResponseObjectSigningAlgs))
+	}
+
+	switch {
+	case m.UserinfoSignedResponseAlg == "":
+		m.UserinfoSignedResponseAlg = SigningAlgNone
+	case m.UserinfoSignedResponseAlg != SigningAlgNone && !utils.IsStringInSlice(m.UserinfoSignedResponseAlg, config.Discovery.ResponseObjectSigningAlgs):
+		return ErrInvalidClientMetadata.WithHintf("The 'userinfo_signed_response_alg' client metadata must be one of %s.", utils.StringJoinOr(append([]string{SigningAlgNone}, config.Discovery.ResponseObjectSigningAlgs...)))
+	}
+
+	return nil
+}
+
+// ToClientConfiguration returns the schema.IdentityProvidersOpenIDConnectClient for the validated client metadata.
+// Dynamically registered clients always use the authorization policy from the Dynamic Client Registration
+// configuration, require explicit consent, and are required to use PKCE with the S256 method if they're public.
+func (m *ClientRegistrationMetadata) ToClientConfiguration(id string, secret *schema.PasswordDigest, config *schema.IdentityProvidersOpenIDConnect) (client schema.IdentityProvidersOpenIDConnectClient) {
+	client = schema.IdentityProvidersOpenIDConnectClient{
+		ID:     id,
+		Name:   m.ClientName,
+		Secret: secret,
+		Public: m.IsPublic(),
+
+		RedirectURIs:  m.RedirectURIs,
+		Scopes:        oauthelia2.RemoveEmpty(strings.Split(m.Scope, " ")),
+		GrantTypes:    m.GrantTypes,
+		ResponseTypes: m.ResponseTypes,
+
+		AuthorizationPolicy:   config.DynamicClientRegistration.AuthorizationPolicy,
+		RequestedAudienceMode: ClientRequestedAudienceModeExplicit.String(),
+		ConsentMode:           ClientConsentModeExplicit.String(),
+
+		AuthorizationSignedResponseAlg: SigningAlgNone,
+		IDTokenSignedResponseAlg:       m.IDTokenSignedResponseAlg,
+		AccessTokenSignedResponseAlg:   SigningAlgNone,
+		UserinfoSignedResponseAlg:      m.UserinfoSignedResponseAlg,
+		IntrospectionSignedResponseAlg: SigningAlgNone,
+
+		TokenEndpointAuthMethod:     m.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlg: m.TokenEndpointAuthSigningAlg,
+	}
+
+	if len(m.ResponseTypes) != 0 {
+		client.ResponseModes = []string{ResponseModeFormPost, ResponseModeQuery}
+	}
+
+	if client.Public {
+		client.RequirePKCE = true
+		client.PKCEChallengeMethod = PKCEChallengeMethodSHA256
+	}
+
+	if m.JSONWebKeysURI != "" {
+		client.JSONWebKeysURI, _ = url.Parse(m.JSONWebKeysURI)
+	}
+
+	return client
+}
+
+// NewDynamicClient returns a Client from a model.OAuth2DynamicClient.
+func NewDynamicClient(dynamic *model.OAuth2DynamicClient, config *schema.IdentityProvidersOpenIDConnect) (client Client, err error) {
+	metadata := ClientRegistrationMetadata{}
+
+	if err = json.Unmarshal(dynamic.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("error decoding the metadata of the dynamically registered client with id '%s': %w", dynamic.ClientID, err)
+	}
+
+	var secret *schema.PasswordDigest
+
+	if dynamic.ClientSecret.Valid {
+		if secret, err = schema.DecodePasswordDigest(dynamic.ClientSecret.String); err != nil {
+			return nil, fmt.Errorf("error decoding the client secret of the dynamically registered client with id '%s': %w", dynamic.ClientID, err)
+		}
+	}
+
+	return NewClient(metadata.ToClientConfiguration(dynamic.ClientID, secret, config), config), nil
+}
+
+// RegistrationAccessTokenSignature returns the signature of a registration access token which is stored in place of
+// the token itself.
+func RegistrationAccessTokenSignature(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRegistrationAccessTokenValid returns true if the registration access token matches the signature stored for the
+// dynamically registered client.
+func IsRegistrationAccessTokenValid(dynamic *model.OAuth2DynamicClient, token string) bool {
+	if dynamic == nil || token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(RegistrationAccessTokenSignature(token)), []byte(dynamic.RegistrationAccessTokenSignature)) == 1
+}
+
+func isLoopbackHostname(hostname string) bool {
+	if hostname == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(hostname)
+
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,358 @@
+package oidc_test
+
+import (
+	"database/sql"
+	"testing"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func newTestClientRegistrationConfig() *schema.IdentityProvidersOpenIDConnect {
+	return &schema.IdentityProvidersOpenIDConnect{
+		DynamicClientRegistration: schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+			Enabled:             true,
+			AuthorizationPolicy: authorization.TwoFactor.String(),
+			Scopes:              []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess, oidc.ScopeProfile},
+			GrantTypes:          []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken, oidc.GrantTypeClientCredentials},
+		},
+		Discovery: schema.IdentityProvidersOpenIDConnectDiscovery{
+			ResponseObjectSigningAlgs: []string{oidc.SigningAlgRSAUsingSHA256, oidc.SigningAlgECDSAUsingP256AndSHA256},
+		},
+	}
+}
+
+func TestClientRegistrationMetadata_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     oidc.ClientRegistrationMetadata
+		expected *oidc.ClientRegistrationMetadata
+		err      string
+	}{
+		{
+			"ShouldSetDefaults",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+			},
+			&oidc.ClientRegistrationMetadata{
+				RedirectURIs:              []string{"https://app.example.com/callback"},
+				GrantTypes:                []string{oidc.GrantTypeAuthorizationCode},
+				ResponseTypes:             []string{oidc.ResponseTypeAuthorizationCodeFlow},
+				Scope:                     "openid offline_access profile",
+				TokenEndpointAuthMethod:   oidc.ClientAuthMethodClientSecretBasic,
+				IDTokenSignedResponseAlg:  oidc.SigningAlgRSAUsingSHA256,
+				UserinfoSignedResponseAlg: oidc.SigningAlgNone,
+			},
+			"",
+		},
+		{
+			"ShouldAllowPublicLoopbackAndPrivateUseRedirectURIs",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:            []string{"http://127.0.0.1:8080/callback", "http://localhost/callback", "com.example.app:/callback"},
+				GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				Scope:                   "openid offline_access",
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodNone,
+			},
+			&oidc.ClientRegistrationMetadata{
+				RedirectURIs:              []string{"http://127.0.0.1:8080/callback", "http://localhost/callback", "com.example.app:/callback"},
+				GrantTypes:                []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				ResponseTypes:             []string{oidc.ResponseTypeAuthorizationCodeFlow},
+				Scope:                     "openid offline_access",
+				TokenEndpointAuthMethod:   oidc.ClientAuthMethodNone,
+				IDTokenSignedResponseAlg:  oidc.SigningAlgRSAUsingSHA256,
+				UserinfoSignedResponseAlg: oidc.SigningAlgNone,
+			},
+			"",
+		},
+		{
+			"ShouldAllowClientCredentialsWithoutRedirectURIs",
+			oidc.ClientRegistrationMetadata{
+				GrantTypes: []string{oidc.GrantTypeClientCredentials},
+				Scope:      "profile",
+			},
+			&oidc.ClientRegistrationMetadata{
+				GrantTypes:                []string{oidc.GrantTypeClientCredentials},
+				Scope:                     "profile",
+				TokenEndpointAuthMethod:   oidc.ClientAuthMethodClientSecretBasic,
+				IDTokenSignedResponseAlg:  oidc.SigningAlgRSAUsingSHA256,
+				UserinfoSignedResponseAlg: oidc.SigningAlgNone,
+			},
+			"",
+		},
+		{
+			"ShouldRejectInlineJWKS",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				JSONWebKeys:  &jose.JSONWebKeySet{},
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'jwks' client metadata is not supported, the 'jwks_uri' client metadata must be used instead.",
+		},
+		{
+			"ShouldRejectGrantTypeNotPermitted",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				GrantTypes:   []string{oidc.GrantTypeImplicit},
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The grant type 'implicit' is not permitted for dynamically registered clients.",
+		},
+		{
+			"ShouldRejectImplicitResponseTypes",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:  []string{"https://app.example.com/callback"},
+				ResponseTypes: []string{oidc.ResponseTypeImplicitFlowIDToken},
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'response_types' client metadata must only contain 'code'.",
+		},
+		{
+			"ShouldRejectPublicClientCredentials",
+			oidc.ClientRegistrationMetadata{
+				GrantTypes:              []string{oidc.GrantTypeClientCredentials},
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodNone,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The grant type 'client_credentials' is not permitted for public clients.",
+		},
+		{
+			"ShouldRejectClientSecretJWT",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodClientSecretJWT,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'token_endpoint_auth_method' client metadata must be one of 'none', 'client_secret_basic', 'client_secret_post', or 'private_key_jwt'.",
+		},
+		{
+			"ShouldRejectPrivateKeyJWTWithoutJWKSURI",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'jwks_uri' client metadata is required when the 'token_endpoint_auth_method' is 'private_key_jwt'.",
+		},
+		{
+			"ShouldRejectScopeNotPermitted",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				Scope:        "openid groups",
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The scope 'groups' is not permitted for dynamically registered clients.",
+		},
+		{
+			"ShouldRejectRefreshTokenWithoutOfflineAccess",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				GrantTypes:   []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				Scope:        "openid",
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'refresh_token' grant type requires the 'offline_access' scope.",
+		},
+		{
+			"ShouldRejectMissingRedirectURIs",
+			oidc.ClientRegistrationMetadata{},
+			nil,
+			"The value of one or more redirection URIs is invalid. The 'redirect_uris' client metadata is required when using the 'authorization_code' grant type.",
+		},
+		{
+			"ShouldRejectRedirectURIWithFragment",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback#abc"},
+			},
+			nil,
+			"The value of one or more redirection URIs is invalid. The redirect URI 'https://app.example.com/callback#abc' must not contain a fragment.",
+		},
+		{
+			"ShouldRejectNonLoopbackHTTPRedirectURI",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"http://app.example.com/callback"},
+			},
+			nil,
+			"The value of one or more redirection URIs is invalid. The redirect URI 'http://app.example.com/callback' must use the 'https' scheme unless it's a loopback address.",
+		},
+		{
+			"ShouldRejectPrivateUseRedirectURIForConfidentialClient",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"com.example.app:/callback"},
+			},
+			nil,
+			"The value of one or more redirection URIs is invalid. The redirect URI 'com.example.app:/callback' uses the private-use scheme 'com.example.app' which is only permitted for public clients.",
+		},
+		{
+			"ShouldRejectInsecureLogoURI",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				LogoURI:      "http://app.example.com/logo.png",
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'logo_uri' client metadata must be an absolute URI with the 'https' scheme.",
+		},
+		{
+			"ShouldRejectUnsupportedIDTokenAlg",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:             []string{"https://app.example.com/callback"},
+				IDTokenSignedResponseAlg: oidc.SigningAlgRSAPSSUsingSHA256,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'id_token_signed_response_alg' client metadata must be one of 'RS256' or 'ES256'.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.have.Validate(newTestClientRegistrationConfig())
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, *tc.expected, tc.have)
+			} else {
+				assert.Equal(t, tc.err, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+			}
+		})
+	}
+}
+
+func TestClientRegistrationMetadata_ValidateOpenRegistration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     oidc.ClientRegistrationMetadata
+		expected *oidc.ClientRegistrationMetadata
+		err      string
+	}{
+		{
+			"ShouldDefaultToPublicClient",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs: []string{"https://app.example.com/callback"},
+			},
+			&oidc.ClientRegistrationMetadata{
+				RedirectURIs:              []string{"https://app.example.com/callback"},
+				GrantTypes:                []string{oidc.GrantTypeAuthorizationCode},
+				ResponseTypes:             []string{oidc.ResponseTypeAuthorizationCodeFlow},
+				Scope:                     "openid offline_access profile",
+				TokenEndpointAuthMethod:   oidc.ClientAuthMethodNone,
+				IDTokenSignedResponseAlg:  oidc.SigningAlgRSAUsingSHA256,
+				UserinfoSignedResponseAlg: oidc.SigningAlgNone,
+			},
+			"",
+		},
+		{
+			"ShouldRejectConfidentialClient",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodClientSecretBasic,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'token_endpoint_auth_method' client metadata must be 'none' when the registration is open.",
+		},
+		{
+			"ShouldRejectRefreshToken",
+			oidc.ClientRegistrationMetadata{
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				Scope:                   "openid offline_access",
+				TokenEndpointAuthMethod: oidc.ClientAuthMethodNone,
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'grant_types' client metadata must only contain 'authorization_code' when the registration is open.",
+		},
+		{
+			"ShouldRejectClientCredentials",
+			oidc.ClientRegistrationMetadata{
+				GrantTypes: []string{oidc.GrantTypeClientCredentials},
+			},
+			nil,
+			"The value of one of the client metadata fields is invalid and the server has rejected this request. The 'grant_types' client metadata must only contain 'authorization_code' when the registration is open.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := newTestClientRegistrationConfig()
+			config.DynamicClientRegistration.OpenRegistration = true
+
+			err := tc.have.Validate(config)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, *tc.expected, tc.have)
+			} else {
+				assert.Equal(t, tc.err, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+			}
+		})
+	}
+}
+
+func TestClientRegistrationMetadata_ToClientConfiguration(t *testing.T) {
+	config := newTestClientRegistrationConfig()
+
+	metadata := oidc.ClientRegistrationMetadata{
+		ClientName:              "Example App",
+		RedirectURIs:            []string{"com.example.app:/callback"},
+		Scope:                   "openid offline_access",
+		GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+		TokenEndpointAuthMethod: oidc.ClientAuthMethodNone,
+	}
+
+	require.NoError(t, metadata.Validate(config))
+
+	client := metadata.ToClientConfiguration("abc", nil, config)
+
+	assert.Equal(t, "abc", client.ID)
+	assert.Equal(t, "Example App", client.Name)
+	assert.True(t, client.Public)
+	assert.True(t, client.RequirePKCE)
+	assert.Equal(t, oidc.PKCEChallengeMethodSHA256, client.PKCEChallengeMethod)
+	assert.Equal(t, []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}, client.Scopes)
+	assert.Equal(t, []string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery}, client.ResponseModes)
+	assert.Equal(t, "two_factor", client.AuthorizationPolicy)
+	assert.Equal(t, "explicit", client.ConsentMode)
+	assert.Equal(t, "explicit", client.RequestedAudienceMode)
+}
+
+func TestNewDynamicClient(t *testing.T) {
+	config := newTestClientRegistrationConfig()
+
+	client, err := oidc.NewDynamicClient(&model.OAuth2DynamicClient{
+		ClientID:     "abc",
+		ClientSecret: sql.NullString{String: tOpenIDConnectPBKDF2ClientSecret.Encode(), Valid: true},
+		Metadata:     []byte(`{"client_name":"Example App","redirect_uris":["https://app.example.com/callback"],"grant_types":["authorization_code"],"response_types":["code"],"scope":"openid profile","token_endpoint_auth_method":"client_secret_post"}`),
+	}, config)
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc", client.GetID())
+	assert.Equal(t, "Example App", client.GetName())
+	assert.False(t, client.IsPublic())
+	assert.Equal(t, oidc.ClientAuthMethodClientSecretPost, client.GetTokenEndpointAuthMethod())
+	assert.Equal(t, oauthelia2.Arguments{oidc.ScopeOpenID, oidc.ScopeProfile}, client.GetScopes())
+	assert.Equal(t, authorization.TwoFactor, client.GetAuthorizationPolicyRequiredLevel(authorization.Subject{}))
+	assert.NotNil(t, client.GetClientSecret())
+
+	client, err = oidc.NewDynamicClient(&model.OAuth2DynamicClient{ClientID: "abc", Metadata: []byte(`{`)}, config)
+
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "error decoding the metadata of the dynamically registered client with id 'abc': unexpected end of JSON input")
+}
+
+func TestIsRegistrationAccessTokenValid(t *testing.T) {
+	dynamic := &model.OAuth2DynamicClient{
+		ClientID:                         "abc",
+		RegistrationAccessTokenSignature: oidc.RegistrationAccessTokenSignature("authelia_rat_example"),
+	}
+
+	assert.Equal(t, "e5aac1d4b5b44c5c9ca5bd98f47cad8b1648fcd6b877acbfe733c9b583958cbd", dynamic.RegistrationAccessTokenSignature)
+	assert.True(t, oidc.IsRegistrationAccessTokenValid(dynamic, "authelia_rat_example"))
+	assert.False(t, oidc.IsRegistrationAccessTokenValid(dynamic, "authelia_rat_other"))
+	assert.False(t, oidc.IsRegistrationAccessTokenValid(dynamic, ""))
+	assert.False(t, oidc.IsRegistrationAccessTokenValid(nil, "authelia_rat_example"))
+}
@@ -251,6 +251,21 @@ func TestIsAuthenticationLevelSufficient(t *testing.T) {
 	assert.False(t, c.IsAuthenticationLevelSufficient(authentication.TwoFactor, authorization.Subject{}))
 }
 
+func TestIsUserVerificationSufficient(t *testing.T) {
+	c := &oidc.RegisteredClient{AuthorizationPolicy: oidc.ClientAuthorizationPolicy{DefaultPolicy: authorization.TwoFactor}}
+
+	assert.True(t, c.IsUserVerificationSufficient(oidc.AuthenticationMethodsReferences{}, authorization.Subject{}))
+
+	c.RequireUserVerification = true
+
+	assert.False(t, c.IsUserVerificationSufficient(oidc.AuthenticationMethodsReferences{WebAuthn: true, WebAuthnUserPresence: true}, authorization.Subject{}))
+	assert.True(t, c.IsUserVerificationSufficient(oidc.AuthenticationMethodsReferences{WebAuthn: true, WebAuthnUserVerified: true}, authorization.Subject{}))
+
+	c.AuthorizationPolicy = oidc.ClientAuthorizationPolicy{DefaultPolicy: authorization.OneFactor}
+
+	assert.True(t, c.IsUserVerificationSufficient(oidc.AuthenticationMethodsReferences{}, authorization.Subject{}))
+}
+
 func TestClient_GetConsentResponseBody(t *testing.T) {
 	c := &oidc.RegisteredClient{}
 
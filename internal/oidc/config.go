@@ -14,7 +14,9 @@ import (
 	"authelia.com/provider/oauth2/handler/openid"
 	"authelia.com/provider/oauth2/handler/par"
 	"authelia.com/provider/oauth2/handler/pkce"
+	"authelia.com/provider/oauth2/handler/rfc8628"
 	"authelia.com/provider/oauth2/i18n"
+	"authelia.com/provider/oauth2/token/hmac"
 	"authelia.com/provider/oauth2/token/jwt"
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 
@@ -31,6 +33,9 @@ func NewConfig(config *schema.IdentityProvidersOpenIDConnect, signer jwt.Signer,
 		MinParameterEntropy:        config.MinimumParameterEntropy,
 		Lifespans: LifespansConfig{
 			IdentityProvidersOpenIDConnectLifespanToken: config.Lifespans.IdentityProvidersOpenIDConnectLifespanToken,
+			RFC8628Code: config.Lifespans.DeviceCode,
+			CIBARequest: config.BackchannelAuthentication.Lifespan,
+			CIBAPolling: config.BackchannelAuthentication.PollingInterval,
 		},
 		ProofKeyCodeExchange: ProofKeyCodeExchangeConfig{
 			Enforce:                   config.EnforcePKCE == "always",
@@ -64,6 +69,11 @@ func NewConfig(config *schema.IdentityProvidersOpenIDConnect, signer jwt.Signer,
 		Config: c,
 	}
 
+	c.Strategy.RFC8628 = &rfc8628.DefaultRFC8628CodeStrategy{
+		Enigma: &hmac.HMACStrategy{Config: c},
+		Config: c,
+	}
+
 	return c
 }
 
@@ -134,6 +144,9 @@ type LifespansConfig struct {
 
 	RFC8628Code    time.Duration
 	RFC8628Polling time.Duration
+
+	CIBARequest time.Duration
+	CIBAPolling time.Duration
 }
 
 // HashConfig holds specific oauthelia2.Configurator information for hashing.
@@ -145,6 +158,7 @@ type HashConfig struct {
 type StrategyConfig struct {
 	Core                 oauth2.CoreStrategy
 	OpenID               openid.OpenIDConnectTokenStrategy
+	RFC8628              rfc8628.RFC8628CodeStrategy
 	Audience             oauthelia2.AudienceMatchingStrategy
 	Scope                oauthelia2.ScopeStrategy
 	JWKSFetcher          oauthelia2.JWKSFetcherStrategy
@@ -198,8 +212,11 @@ type HandlersConfig struct {
 	// PushedAuthorizeEndpoint is a list of handlers that are called before the PAR endpoint is served.
 	PushedAuthorizeEndpoint oauthelia2.PushedAuthorizeEndpointHandlers
 
+	// RFC8628DeviceAuthorizeEndpoint is a list of handlers that are called before the device authorization endpoint is
+	// served.
 	RFC8628DeviceAuthorizeEndpoint oauthelia2.RFC8628DeviceAuthorizeEndpointHandlers
 
+	// RFC8628UserAuthorizeEndpoint is a list of handlers that are called when the user authorizes a device.
 	RFC8628UserAuthorizeEndpoint oauthelia2.RFC8628UserAuthorizeEndpointHandlers
 }
 
@@ -325,6 +342,45 @@ func (c *Config) LoadHandlers(store *Store) {
 			Storage: store,
 			Config:  c,
 		},
+		&rfc8628.DeviceAuthorizeHandler{
+			Strategy: c.Strategy.RFC8628,
+			Storage:  store,
+			Config:   c,
+		},
+		&rfc8628.UserAuthorizeHandler{
+			Strategy: c.Strategy.RFC8628,
+			Storage:  store,
+			Config:   c,
+		},
+		&rfc8628.DeviceCodeTokenHandler{
+			DeviceCodeStrategy:     c.Strategy.RFC8628,
+			AccessTokenStrategy:    c.Strategy.Core,
+			RefreshTokenStrategy:   c.Strategy.Core,
+			CoreStorage:            store,
+			TokenRevocationStorage: store,
+			Config:                 c,
+		},
+		&TokenExchangeGrantHandler{
+			AccessTokenStrategy: c.Strategy.Core,
+			Storage:             store,
+			Config:              c,
+		},
+		&BackchannelAuthenticationGrantHandler{
+			AccessTokenStrategy:  c.Strategy.Core,
+			RefreshTokenStrategy: c.Strategy.Core,
+			IDTokenHandleHelper: &openid.IDTokenHandleHelper{
+				IDTokenStrategy: c.Strategy.OpenID,
+			},
+			Storage: store,
+			Config:  c,
+		},
+		&openid.OpenIDConnectDeviceAuthorizeHandler{
+			IDTokenHandleHelper: &openid.IDTokenHandleHelper{
+				IDTokenStrategy: c.Strategy.OpenID,
+			},
+			OpenIDConnectRequestStorage: store,
+			Config:                      c,
+		},
 
 		// Response Mode Handling.
 		&oauthelia2.DefaultResponseModeHandler{
@@ -364,6 +420,14 @@ func (c *Config) LoadHandlers(store *Store) {
 			x.PushedAuthorizeEndpoint.Append(h)
 		}
 
+		if h, ok := handler.(oauthelia2.RFC8628DeviceAuthorizeEndpointHandler); ok {
+			x.RFC8628DeviceAuthorizeEndpoint.Append(h)
+		}
+
+		if h, ok := handler.(oauthelia2.RFC8628UserAuthorizeEndpointHandler); ok {
+			x.RFC8628UserAuthorizeEndpoint.Append(h)
+		}
+
 		if h, ok := handler.(oauthelia2.ResponseModeHandler); ok {
 			x.ResponseMode.Append(h)
 		}
@@ -787,6 +851,26 @@ func (c *Config) GetRFC8628TokenPollingInterval(ctx context.Context) (interval t
 	return c.Lifespans.RFC8628Polling
 }
 
+// GetBackchannelAuthenticationLifespan returns the lifespan of an OpenID Connect 1.0 Client Initiated Backchannel
+// Authentication request.
+func (c *Config) GetBackchannelAuthenticationLifespan(ctx context.Context) time.Duration {
+	if c.Lifespans.CIBARequest.Seconds() <= 0 {
+		c.Lifespans.CIBARequest = schema.DefaultOpenIDConnectBackchannelAuthenticationConfiguration.Lifespan
+	}
+
+	return c.Lifespans.CIBARequest
+}
+
+// GetBackchannelAuthenticationPollingInterval returns the minimum interval a client must wait between polling the
+// token endpoint for the result of an OpenID Connect 1.0 Client Initiated Backchannel Authentication request.
+func (c *Config) GetBackchannelAuthenticationPollingInterval(ctx context.Context) time.Duration {
+	if c.Lifespans.CIBAPolling.Seconds() <= 0 {
+		c.Lifespans.CIBAPolling = schema.DefaultOpenIDConnectBackchannelAuthenticationConfiguration.PollingInterval
+	}
+
+	return c.Lifespans.CIBAPolling
+}
+
 func (c *Config) GetRFC8628DeviceAuthorizeEndpointHandlers(ctx context.Context) oauthelia2.RFC8628DeviceAuthorizeEndpointHandlers {
 	return c.Handlers.RFC8628DeviceAuthorizeEndpoint
 }
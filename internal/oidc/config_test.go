@@ -249,6 +249,8 @@ func TestNewConfig(t *testing.T) {
 	config.LoadHandlers(nil)
 
 	assert.Len(t, config.Handlers.TokenIntrospection, 1)
+	assert.NotEmpty(t, config.Handlers.RFC8628DeviceAuthorizeEndpoint)
+	assert.NotEmpty(t, config.Handlers.RFC8628UserAuthorizeEndpoint)
 
 	config.JWTAccessToken.EnableStatelessIntrospection = true
 
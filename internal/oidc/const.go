@@ -47,6 +47,12 @@ const (
 	ClaimActive                              = "active"
 	ClaimUsername                            = "username"
 	ClaimTokenIntrospection                  = "token_introspection"
+	ClaimActor                               = "act"
+	ClaimConfirmation                        = "cnf"
+	ClaimJWKThumbprint                       = "jkt"
+	ClaimHTTPMethod                          = "htm"
+	ClaimHTTPURI                             = "htu"
+	ClaimDPoPAccessTokenHash                 = "ath"
 )
 
 const (
@@ -62,6 +68,30 @@ const (
 	lifespanRFC8628CodeDefault                = time.Minute * 10
 	lifespanRFC8628PollingIntervalDefault     = time.Second * 10
 	lifespanVerifiableCredentialsNonceDefault = time.Hour
+	lifespanDPoPProofDefault                  = time.Minute * 5
+)
+
+const (
+	// backchannelAuthenticationBatchSize is the maximum number of pending backchannel authentication requests checked
+	// by each run of the BackchannelAuthenticationController.
+	backchannelAuthenticationBatchSize = 100
+)
+
+const (
+	// HeaderDPoP is the header used to send a DPoP proof JWT.
+	HeaderDPoP = "DPoP"
+
+	// AuthorizationSchemeDPoP is the authorization scheme used to present DPoP bound access tokens, and the token type
+	// of DPoP bound access tokens.
+	AuthorizationSchemeDPoP = "DPoP"
+
+	// AuthorizationSchemeBearer is the authorization scheme used to present bearer access tokens.
+	AuthorizationSchemeBearer = "Bearer"
+
+	headerAuthorization = "Authorization"
+
+	dpopJTIPrefix             = "dpop:"
+	dpopProofMaximumClockSkew = time.Minute
 )
 
 const (
@@ -91,6 +121,21 @@ const (
 	GrantTypeRefreshToken      = valueRefreshToken
 	GrantTypeAuthorizationCode = "authorization_code"
 	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
+	GrantTypeCIBA              = "urn:openid:params:grant-type:ciba"
+)
+
+// Client Initiated Backchannel Authentication Token Delivery Mode strings.
+const (
+	BackchannelTokenDeliveryModePoll = "poll"
+	BackchannelTokenDeliveryModePing = "ping"
+)
+
+// Token Type Identifier strings.
+// See: https://datatracker.ietf.org/doc/html/rfc8693#section-3
+const (
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
 )
 
 // Client Auth Method strings.
@@ -173,6 +218,22 @@ const (
 	FormParameterScope        = valueScope
 	FormParameterIssuer       = valueIss
 	FormParameterPrompt       = "prompt"
+	FormParameterUserCode     = "user_code"
+
+	FormParameterSubjectToken       = "subject_token"
+	FormParameterSubjectTokenType   = "subject_token_type"
+	FormParameterActorToken         = "actor_token"
+	FormParameterActorTokenType     = "actor_token_type"
+	FormParameterRequestedTokenType = "requested_token_type"
+	FormParameterIssuedTokenType    = "issued_token_type"
+
+	FormParameterAuthRequestID           = "auth_req_id"
+	FormParameterLoginHint               = "login_hint"
+	FormParameterLoginHintToken          = "login_hint_token"
+	FormParameterIDTokenHint             = "id_token_hint"
+	FormParameterBindingMessage          = "binding_message"
+	FormParameterClientNotificationToken = "client_notification_token"
+	FormParameterRequestedExpiry         = "requested_expiry"
 )
 
 const (
@@ -190,6 +251,9 @@ const (
 	EndpointIntrospection              = "introspection"
 	EndpointRevocation                 = "revocation"
 	EndpointPushedAuthorizationRequest = "pushed-authorization-request"
+	EndpointDeviceAuthorization        = "device-authorization"
+	EndpointClientRegistration         = "registration"
+	EndpointBackchannelAuthentication  = "backchannel-authentication"
 )
 
 // JWT Headers.
@@ -207,11 +271,13 @@ const (
 const (
 	JWTHeaderTypeValueTokenIntrospectionJWT = "token-introspection+jwt"
 	JWTHeaderTypeValueAccessTokenJWT        = "at+jwt"
+	JWTHeaderTypeValueDPoPJWT               = "dpop+jwt"
 )
 
 // Paths.
 const (
 	EndpointPathConsent                           = "/consent"
+	EndpointPathConsentDeviceAuthorization        = EndpointPathConsent + "/openid/device-authorization"
 	EndpointPathWellKnownOpenIDConfiguration      = "/.well-known/openid-configuration"
 	EndpointPathWellKnownOAuthAuthorizationServer = "/.well-known/oauth-authorization-server"
 	EndpointPathJWKs                              = "/jwks.json"
@@ -225,6 +291,9 @@ const (
 	EndpointPathRevocation    = EndpointPathRoot + "/" + EndpointRevocation
 
 	EndpointPathPushedAuthorizationRequest = EndpointPathRoot + "/" + EndpointPushedAuthorizationRequest
+	EndpointPathDeviceAuthorization        = EndpointPathRoot + "/" + EndpointDeviceAuthorization
+	EndpointPathClientRegistration         = EndpointPathRoot + "/" + EndpointClientRegistration
+	EndpointPathBackchannelAuthentication  = EndpointPathRoot + "/" + EndpointBackchannelAuthentication
 
 	EndpointPathRFC8628UserVerificationURL = EndpointPathRoot + "/device-code/user-verification"
 )
@@ -334,6 +403,15 @@ const (
 	//
 	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
 	AMRShortMessageService = "sms"
+
+	// AMRTelephone is an RFC8176 Authentication Method Reference Value that represents authentication via
+	// confirmation using a telephone call to the user at a registered number.
+	//
+	// Authelia utilizes this when a user has used a One-Time Code delivered by a voice call to authenticate. Factor:
+	// Have, Channel: Service.
+	//
+	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
+	AMRTelephone = "tel"
 )
 
 const (
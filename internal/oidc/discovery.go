@@ -30,6 +30,8 @@ func NewOpenIDConnectWellKnownConfiguration(c *schema.IdentityProvidersOpenIDCon
 					GrantTypeImplicit,
 					GrantTypeClientCredentials,
 					GrantTypeRefreshToken,
+					GrantTypeDeviceCode,
+					GrantTypeTokenExchange,
 				},
 				ResponseModesSupported: []string{
 					ResponseModeFormPost,
@@ -127,12 +129,26 @@ func NewOpenIDConnectWellKnownConfiguration(c *schema.IdentityProvidersOpenIDCon
 					SigningAlgNone,
 				},
 			},
+			OAuth2DeviceAuthorizationGrantDiscoveryOptions: &OAuth2DeviceAuthorizationGrantDiscoveryOptions{},
 			OAuth2PushedAuthorizationDiscoveryOptions: &OAuth2PushedAuthorizationDiscoveryOptions{
 				RequirePushedAuthorizationRequests: c.RequirePushedAuthorizationRequests,
 			},
 			OAuth2IssuerIdentificationDiscoveryOptions: &OAuth2IssuerIdentificationDiscoveryOptions{
 				AuthorizationResponseIssuerParameterSupported: true,
 			},
+			OAuth2DPoPDiscoveryOptions: &OAuth2DPoPDiscoveryOptions{
+				DPoPSigningAlgValuesSupported: []string{
+					SigningAlgRSAUsingSHA256,
+					SigningAlgRSAUsingSHA384,
+					SigningAlgRSAUsingSHA512,
+					SigningAlgECDSAUsingP256AndSHA256,
+					SigningAlgECDSAUsingP384AndSHA384,
+					SigningAlgECDSAUsingP521AndSHA512,
+					SigningAlgRSAPSSUsingSHA256,
+					SigningAlgRSAPSSUsingSHA384,
+					SigningAlgRSAPSSUsingSHA512,
+				},
+			},
 		},
 
 		OpenIDConnectDiscoveryOptions: OpenIDConnectDiscoveryOptions{
@@ -199,6 +215,16 @@ func NewOpenIDConnectWellKnownConfiguration(c *schema.IdentityProvidersOpenIDCon
 	sort.Sort(SortedSigningAlgs(config.IntrospectionSigningAlgValuesSupported))
 	sort.Sort(SortedSigningAlgs(config.AuthorizationSigningAlgValuesSupported))
 
+	if c.BackchannelAuthentication.Enabled {
+		config.GrantTypesSupported = append(config.GrantTypesSupported, GrantTypeCIBA)
+		config.OpenIDConnectClientInitiatedBackChannelAuthFlowDiscoveryOptions = &OpenIDConnectClientInitiatedBackChannelAuthFlowDiscoveryOptions{
+			BackChannelTokenDeliveryModesSupported: []string{
+				BackchannelTokenDeliveryModePoll,
+				BackchannelTokenDeliveryModePing,
+			},
+		}
+	}
+
 	if c.EnablePKCEPlainChallenge {
 		config.CodeChallengeMethodsSupported = append(config.CodeChallengeMethodsSupported, PKCEChallengeMethodPlain)
 	}
@@ -243,6 +269,11 @@ func (opts OAuth2WellKnownConfiguration) Copy() (optsCopy OAuth2WellKnownConfigu
 		*optsCopy.OAuth2PushedAuthorizationDiscoveryOptions = *opts.OAuth2PushedAuthorizationDiscoveryOptions
 	}
 
+	if opts.OAuth2DPoPDiscoveryOptions != nil {
+		optsCopy.OAuth2DPoPDiscoveryOptions = &OAuth2DPoPDiscoveryOptions{}
+		*optsCopy.OAuth2DPoPDiscoveryOptions = *opts.OAuth2DPoPDiscoveryOptions
+	}
+
 	return optsCopy
 }
 
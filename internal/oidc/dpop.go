@@ -0,0 +1,265 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	jose "github.com/go-jose/go-jose/v4"
+)
+
+var dpopSigningAlgValuesSupported = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+}
+
+// DPoPProofStorage describes the storage requirements for the replay protection of DPoP proofs. The JTI of each
+// proof is stored in the same way as the JTI of a client assertion.
+type DPoPProofStorage interface {
+	ClientAssertionJWTValid(ctx context.Context, jti string) (err error)
+	SetClientAssertionJWT(ctx context.Context, jti string, exp time.Time) (err error)
+}
+
+// DPoPProof represents a validated DPoP proof JWT.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-4.2
+type DPoPProof struct {
+	JTI      string
+	Method   string
+	URI      string
+	IssuedAt time.Time
+
+	// JWKThumbprint is the base64url encoded SHA-256 JWK Thumbprint (RFC7638) of the public key of the proof.
+	JWKThumbprint string
+}
+
+type dpopProofClaims struct {
+	JTI             string `json:"jti"`
+	Method          string `json:"htm"`
+	URI             string `json:"htu"`
+	IssuedAt        int64  `json:"iat"`
+	AccessTokenHash string `json:"ath,omitempty"`
+}
+
+// NewDPoPProofFromRequest validates the DPoP proof of a request to the endpoint with the provided URI, returning nil
+// if the request does not include a DPoP proof. If the access token is provided the proof must include a matching
+// access token hash.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-4.3
+func NewDPoPProofFromRequest(ctx context.Context, store DPoPProofStorage, req *http.Request, uri *url.URL, accessToken string, now time.Time) (proof *DPoPProof, err error) {
+	values := req.Header.Values(HeaderDPoP)
+
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		return NewDPoPProof(ctx, store, values[0], req.Method, uri, accessToken, now)
+	default:
+		return nil, ErrInvalidDPoPProof.WithHintf("The request must not include more than one '%s' header.", HeaderDPoP)
+	}
+}
+
+// NewDPoPProof validates the DPoP proof JWT value for a request with the provided method and URI and returns the
+// validated DPoP proof. The JTI of the proof is recorded so the proof can't be replayed.
+//
+//nolint:gocyclo
+func NewDPoPProof(ctx context.Context, store DPoPProofStorage, value, method string, uri *url.URL, accessToken string, now time.Time) (proof *DPoPProof, err error) {
+	var jws *jose.JSONWebSignature
+
+	if jws, err = jose.ParseSigned(value, dpopSigningAlgValuesSupported); err != nil {
+		return nil, ErrInvalidDPoPProof.WithHint("The DPoP proof could not be parsed or uses an unsupported algorithm.").WithWrap(err).WithDebugf("Error occurred parsing the DPoP proof: %s.", err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, ErrInvalidDPoPProof.WithHint("The DPoP proof must have exactly one signature.")
+	}
+
+	header := jws.Signatures[0].Protected
+
+	if typ, ok := header.ExtraHeaders[jose.HeaderType].(string); !ok || typ != JWTHeaderTypeValueDPoPJWT {
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof must have the '%s' header with the value '%s'.", JWTHeaderKeyType, JWTHeaderTypeValueDPoPJWT)
+	}
+
+	jwk := header.JSONWebKey
+
+	if jwk == nil || !jwk.Valid() || !jwk.IsPublic() {
+		return nil, ErrInvalidDPoPProof.WithHint("The DPoP proof must have the 'jwk' header with a valid public key.")
+	}
+
+	var payload []byte
+
+	if payload, err = jws.Verify(jwk); err != nil {
+		return nil, ErrInvalidDPoPProof.WithHint("The DPoP proof signature could not be verified.").WithWrap(err).WithDebugf("Error occurred verifying the DPoP proof: %s.", err)
+	}
+
+	claims := dpopProofClaims{}
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalidDPoPProof.WithHint("The DPoP proof claims could not be decoded.").WithWrap(err).WithDebugf("Error occurred decoding the DPoP proof claims: %s.", err)
+	}
+
+	issuedAt := time.Unix(claims.IssuedAt, 0).UTC()
+
+	switch {
+	case claims.JTI == "":
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof must have the '%s' claim.", ClaimJWTID)
+	case claims.Method != method:
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof '%s' claim does not match the method of the request.", ClaimHTTPMethod)
+	case !isDPoPProofURIMatch(claims.URI, uri):
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof '%s' claim does not match the URI of the request.", ClaimHTTPURI)
+	case claims.IssuedAt == 0, issuedAt.Before(now.Add(-lifespanDPoPProofDefault)), issuedAt.After(now.Add(dpopProofMaximumClockSkew)):
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof '%s' claim is missing or is not within the acceptable time window.", ClaimIssuedAt)
+	case accessToken != "" && claims.AccessTokenHash != DPoPAccessTokenHash(accessToken):
+		return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof '%s' claim does not match the access token.", ClaimDPoPAccessTokenHash)
+	}
+
+	var thumbprint []byte
+
+	if thumbprint, err = jwk.Thumbprint(crypto.SHA256); err != nil {
+		return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Error occurred calculating the DPoP proof JWK thumbprint: %s.", err)
+	}
+
+	jti := dpopJTIPrefix + claims.JTI
+
+	if err = store.ClientAssertionJWTValid(ctx, jti); err != nil {
+		if errors.Is(err, oauthelia2.ErrJTIKnown) {
+			return nil, ErrInvalidDPoPProof.WithHintf("The DPoP proof '%s' claim has been used before.", ClaimJWTID)
+		}
+
+		return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Error occurred checking the DPoP proof JTI: %s.", err)
+	}
+
+	if err = store.SetClientAssertionJWT(ctx, jti, issuedAt.Add(lifespanDPoPProofDefault+dpopProofMaximumClockSkew)); err != nil {
+		return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Error occurred saving the DPoP proof JTI: %s.", err)
+	}
+
+	return &DPoPProof{
+		JTI:           claims.JTI,
+		Method:        claims.Method,
+		URI:           claims.URI,
+		IssuedAt:      issuedAt,
+		JWKThumbprint: base64.RawURLEncoding.EncodeToString(thumbprint),
+	}, nil
+}
+
+// BindDPoPProofToAccessRequest binds the access request to the public key of the DPoP proof at the token endpoint.
+// Refresh tokens issued to public clients are bound to the public key of the proof, and the access tokens issued using
+// them must be bound to the same key. Refresh tokens issued to confidential clients are not bound as they're already
+// bound to the client authentication.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-5
+func BindDPoPProofToAccessRequest(requester oauthelia2.AccessRequester, proof *DPoPProof) (err error) {
+	var (
+		session *Session
+		ok      bool
+	)
+
+	if session, ok = requester.GetSession().(*Session); !ok {
+		return oauthelia2.ErrServerError.WithDebugf("Failed to bind the DPoP proof as the session type '%T' is not supported.", requester.GetSession())
+	}
+
+	client := requester.GetClient()
+
+	bound := client.IsPublic() && session.DPoPJWKThumbprint != ""
+
+	switch {
+	case proof == nil && bound:
+		return ErrInvalidDPoPProof.WithHint("The refresh token is bound to a DPoP key and the request must include a DPoP proof.")
+	case proof == nil && isDPoPBoundAccessTokensRequired(client):
+		return ErrInvalidDPoPProof.WithHint("The OAuth 2.0 Client is registered to use DPoP bound access tokens and the request must include a DPoP proof.")
+	case proof == nil:
+		session.DPoPJWKThumbprint = ""
+
+		return nil
+	case bound && session.DPoPJWKThumbprint != proof.JWKThumbprint:
+		return ErrInvalidDPoPProof.WithHint("The refresh token is bound to a different DPoP key than the key of the DPoP proof.")
+	}
+
+	session.DPoPJWKThumbprint = proof.JWKThumbprint
+
+	return nil
+}
+
+// ValidateDPoPBoundAccessToken validates the use of an access token at a protected resource. Access tokens which are
+// bound to a DPoP key must be presented using the DPoP authorization scheme with a DPoP proof signed by the same key,
+// and access tokens which are not bound must not be presented using the DPoP authorization scheme.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc9449#section-7
+func ValidateDPoPBoundAccessToken(ctx context.Context, store DPoPProofStorage, req *http.Request, uri *url.URL, scheme, token string, requester oauthelia2.Requester, now time.Time) (err error) {
+	var thumbprint string
+
+	if session, ok := requester.GetSession().(*Session); ok {
+		thumbprint = session.DPoPJWKThumbprint
+	}
+
+	switch {
+	case thumbprint == "" && scheme == AuthorizationSchemeDPoP:
+		return oauthelia2.ErrInvalidTokenFormat.WithHintf("The access token is not bound to a DPoP key and must be presented using the '%s' authorization scheme.", AuthorizationSchemeBearer)
+	case thumbprint == "":
+		return nil
+	case scheme != AuthorizationSchemeDPoP:
+		return oauthelia2.ErrInvalidTokenFormat.WithHintf("The access token is bound to a DPoP key and must be presented using the '%s' authorization scheme.", AuthorizationSchemeDPoP)
+	}
+
+	var proof *DPoPProof
+
+	if proof, err = NewDPoPProofFromRequest(ctx, store, req, uri, token, now); err != nil {
+		return err
+	}
+
+	switch {
+	case proof == nil:
+		return ErrInvalidDPoPProof.WithHint("The access token is bound to a DPoP key and the request must include a DPoP proof.")
+	case proof.JWKThumbprint != thumbprint:
+		return ErrInvalidDPoPProof.WithHint("The access token is bound to a different DPoP key than the key of the DPoP proof.")
+	}
+
+	return nil
+}
+
+// AccessTokenFromRequest returns the access token and the authorization scheme used to present it. In addition to the
+// schemes supported by oauthelia2.AccessTokenFromRequest the DPoP authorization scheme is supported.
+func AccessTokenFromRequest(req *http.Request) (token, scheme string) {
+	if scheme, token, ok := strings.Cut(req.Header.Get(headerAuthorization), " "); ok && strings.EqualFold(scheme, AuthorizationSchemeDPoP) {
+		return token, AuthorizationSchemeDPoP
+	}
+
+	return oauthelia2.AccessTokenFromRequest(req), AuthorizationSchemeBearer
+}
+
+// DPoPAccessTokenHash returns the value of the ath claim of a DPoP proof for the access token.
+func DPoPAccessTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func isDPoPProofURIMatch(value string, uri *url.URL) bool {
+	if value == "" || uri == nil {
+		return false
+	}
+
+	htu, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(htu.Scheme, uri.Scheme) && strings.EqualFold(htu.Host, uri.Host) && htu.EscapedPath() == uri.EscapedPath()
+}
+
+func isDPoPBoundAccessTokensRequired(client oauthelia2.Client) bool {
+	if c, ok := client.(Client); ok {
+		return c.GetDPoPBoundAccessTokens()
+	}
+
+	return false
+}
@@ -0,0 +1,428 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+type testDPoPProofStorage struct {
+	jtis map[string]time.Time
+}
+
+func (s *testDPoPProofStorage) ClientAssertionJWTValid(_ context.Context, jti string) (err error) {
+	if _, ok := s.jtis[jti]; ok {
+		return oauthelia2.ErrJTIKnown
+	}
+
+	return nil
+}
+
+func (s *testDPoPProofStorage) SetClientAssertionJWT(_ context.Context, jti string, exp time.Time) (err error) {
+	s.jtis[jti] = exp
+
+	return nil
+}
+
+func newTestDPoPProof(t *testing.T, key *ecdsa.PrivateKey, typ string, claims map[string]any) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: key}, (&jose.SignerOptions{EmbedJWK: true}).WithType(jose.ContentType(typ)))
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	jws, err := signer.Sign(payload)
+	require.NoError(t, err)
+
+	value, err := jws.CompactSerialize()
+	require.NoError(t, err)
+
+	return value
+}
+
+func TestNewDPoPProof(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{Key: key.Public()}
+
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0).UTC()
+	uri := &url.URL{Scheme: "https", Host: "auth.example.com", Path: oidc.EndpointPathToken}
+
+	testCases := []struct {
+		name     string
+		typ      string
+		claims   map[string]any
+		token    string
+		expected string
+	}{
+		{
+			"ShouldValidate",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()},
+			"",
+			"",
+		},
+		{
+			"ShouldValidateIgnoringQueryAndCase",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "HTTPS://AUTH.EXAMPLE.COM/api/oidc/token?a=b", "iat": now.Unix()},
+			"",
+			"",
+		},
+		{
+			"ShouldValidateAccessTokenHash",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix(), "ath": oidc.DPoPAccessTokenHash("authelia_at_abc.123")},
+			"authelia_at_abc.123",
+			"",
+		},
+		{
+			"ShouldRejectType",
+			"JWT",
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof must have the 'typ' header with the value 'dpop+jwt'.",
+		},
+		{
+			"ShouldRejectMissingJTI",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof must have the 'jti' claim.",
+		},
+		{
+			"ShouldRejectMethod",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodGet, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof 'htm' claim does not match the method of the request.",
+		},
+		{
+			"ShouldRejectURI",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/userinfo", "iat": now.Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof 'htu' claim does not match the URI of the request.",
+		},
+		{
+			"ShouldRejectExpired",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Add(-time.Minute * 6).Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof 'iat' claim is missing or is not within the acceptable time window.",
+		},
+		{
+			"ShouldRejectFuture",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Add(time.Minute * 2).Unix()},
+			"",
+			"The DPoP proof of the request is invalid. The DPoP proof 'iat' claim is missing or is not within the acceptable time window.",
+		},
+		{
+			"ShouldRejectAccessTokenHash",
+			oidc.JWTHeaderTypeValueDPoPJWT,
+			map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix(), "ath": oidc.DPoPAccessTokenHash("authelia_at_def.456")},
+			"authelia_at_abc.123",
+			"The DPoP proof of the request is invalid. The DPoP proof 'ath' claim does not match the access token.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &testDPoPProofStorage{jtis: map[string]time.Time{}}
+
+			value := newTestDPoPProof(t, key, tc.typ, tc.claims)
+
+			proof, err := oidc.NewDPoPProof(context.Background(), store, value, http.MethodPost, uri, tc.token, now)
+
+			if tc.expected == "" {
+				require.NoError(t, err)
+				require.NotNil(t, proof)
+
+				assert.Equal(t, "abc", proof.JTI)
+				assert.Equal(t, base64.RawURLEncoding.EncodeToString(thumbprint), proof.JWKThumbprint)
+				assert.Contains(t, store.jtis, "dpop:abc")
+
+				_, err = oidc.NewDPoPProof(context.Background(), store, value, http.MethodPost, uri, tc.token, now)
+
+				assert.Equal(t, "The DPoP proof of the request is invalid. The DPoP proof 'jti' claim has been used before.", oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+			} else {
+				assert.Nil(t, proof)
+				assert.Equal(t, tc.expected, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+				assert.Len(t, store.jtis, 0)
+			}
+		})
+	}
+}
+
+func TestNewDPoPProofFromRequest(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	uri := &url.URL{Scheme: "https", Host: "auth.example.com", Path: oidc.EndpointPathToken}
+	store := &testDPoPProofStorage{jtis: map[string]time.Time{}}
+
+	req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", nil)
+
+	proof, err := oidc.NewDPoPProofFromRequest(context.Background(), store, req, uri, "", now)
+	assert.NoError(t, err)
+	assert.Nil(t, proof)
+
+	req.Header.Add(oidc.HeaderDPoP, newTestDPoPProof(t, key, oidc.JWTHeaderTypeValueDPoPJWT, map[string]any{"jti": "abc", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()}))
+
+	proof, err = oidc.NewDPoPProofFromRequest(context.Background(), store, req, uri, "", now)
+	assert.NoError(t, err)
+	assert.NotNil(t, proof)
+
+	req.Header.Add(oidc.HeaderDPoP, newTestDPoPProof(t, key, oidc.JWTHeaderTypeValueDPoPJWT, map[string]any{"jti": "def", "htm": http.MethodPost, "htu": "https://auth.example.com/api/oidc/token", "iat": now.Unix()}))
+
+	proof, err = oidc.NewDPoPProofFromRequest(context.Background(), store, req, uri, "", now)
+	assert.Nil(t, proof)
+	assert.Equal(t, "The DPoP proof of the request is invalid. The request must not include more than one 'DPoP' header.", oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+}
+
+func TestBindDPoPProofToAccessRequest(t *testing.T) {
+	testCases := []struct {
+		name       string
+		client     *oidc.RegisteredClient
+		bound      string
+		proof      *oidc.DPoPProof
+		expected   string
+		thumbprint string
+	}{
+		{
+			"ShouldNotBindWithoutProof",
+			&oidc.RegisteredClient{ID: "app"},
+			"",
+			nil,
+			"",
+			"",
+		},
+		{
+			"ShouldBindProof",
+			&oidc.RegisteredClient{ID: "app"},
+			"",
+			&oidc.DPoPProof{JWKThumbprint: "abc"},
+			"",
+			"abc",
+		},
+		{
+			"ShouldUnbindConfidentialClientWithoutProof",
+			&oidc.RegisteredClient{ID: "app"},
+			"abc",
+			nil,
+			"",
+			"",
+		},
+		{
+			"ShouldRebindConfidentialClient",
+			&oidc.RegisteredClient{ID: "app"},
+			"abc",
+			&oidc.DPoPProof{JWKThumbprint: "def"},
+			"",
+			"def",
+		},
+		{
+			"ShouldBindPublicClientWithSameKey",
+			&oidc.RegisteredClient{ID: "app", Public: true},
+			"abc",
+			&oidc.DPoPProof{JWKThumbprint: "abc"},
+			"",
+			"abc",
+		},
+		{
+			"ShouldRejectPublicClientWithDifferentKey",
+			&oidc.RegisteredClient{ID: "app", Public: true},
+			"abc",
+			&oidc.DPoPProof{JWKThumbprint: "def"},
+			"The DPoP proof of the request is invalid. The refresh token is bound to a different DPoP key than the key of the DPoP proof.",
+			"abc",
+		},
+		{
+			"ShouldRejectPublicClientWithoutProof",
+			&oidc.RegisteredClient{ID: "app", Public: true},
+			"abc",
+			nil,
+			"The DPoP proof of the request is invalid. The refresh token is bound to a DPoP key and the request must include a DPoP proof.",
+			"abc",
+		},
+		{
+			"ShouldRejectRequiredWithoutProof",
+			&oidc.RegisteredClient{ID: "app", DPoPBoundAccessTokens: true},
+			"",
+			nil,
+			"The DPoP proof of the request is invalid. The OAuth 2.0 Client is registered to use DPoP bound access tokens and the request must include a DPoP proof.",
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			session := oidc.NewSession()
+			session.DPoPJWKThumbprint = tc.bound
+
+			requester := &oauthelia2.AccessRequest{
+				Request: oauthelia2.Request{
+					Client:  tc.client,
+					Session: session,
+				},
+			}
+
+			err := oidc.BindDPoPProofToAccessRequest(requester, tc.proof)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.expected, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+			}
+
+			assert.Equal(t, tc.thumbprint, session.DPoPJWKThumbprint)
+		})
+	}
+}
+
+func TestValidateDPoPBoundAccessToken(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	jwk := jose.JSONWebKey{Key: key.Public()}
+
+	thumbprint, err := jwk.Thumbprint(crypto.SHA256)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	uri := &url.URL{Scheme: "https", Host: "auth.example.com", Path: oidc.EndpointPathUserinfo}
+	token := "authelia_at_abc.123"
+
+	claims := map[string]any{"jti": "abc", "htm": http.MethodGet, "htu": "https://auth.example.com/api/oidc/userinfo", "iat": now.Unix(), "ath": oidc.DPoPAccessTokenHash(token)}
+
+	testCases := []struct {
+		name     string
+		bound    bool
+		scheme   string
+		proof    string
+		expected string
+	}{
+		{
+			"ShouldAllowUnboundBearer",
+			false,
+			oidc.AuthorizationSchemeBearer,
+			"",
+			"",
+		},
+		{
+			"ShouldAllowBoundDPoP",
+			true,
+			oidc.AuthorizationSchemeDPoP,
+			newTestDPoPProof(t, key, oidc.JWTHeaderTypeValueDPoPJWT, claims),
+			"",
+		},
+		{
+			"ShouldRejectUnboundDPoP",
+			false,
+			oidc.AuthorizationSchemeDPoP,
+			"",
+			"Invalid token format. The access token is not bound to a DPoP key and must be presented using the 'Bearer' authorization scheme.",
+		},
+		{
+			"ShouldRejectBoundBearer",
+			true,
+			oidc.AuthorizationSchemeBearer,
+			"",
+			"Invalid token format. The access token is bound to a DPoP key and must be presented using the 'DPoP' authorization scheme.",
+		},
+		{
+			"ShouldRejectBoundDPoPWithoutProof",
+			true,
+			oidc.AuthorizationSchemeDPoP,
+			"",
+			"The DPoP proof of the request is invalid. The access token is bound to a DPoP key and the request must include a DPoP proof.",
+		},
+		{
+			"ShouldRejectBoundDPoPWithOtherKey",
+			true,
+			oidc.AuthorizationSchemeDPoP,
+			newTestDPoPProof(t, other, oidc.JWTHeaderTypeValueDPoPJWT, claims),
+			"The DPoP proof of the request is invalid. The access token is bound to a different DPoP key than the key of the DPoP proof.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &testDPoPProofStorage{jtis: map[string]time.Time{}}
+
+			session := oidc.NewSession()
+
+			if tc.bound {
+				session.DPoPJWKThumbprint = base64.RawURLEncoding.EncodeToString(thumbprint)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/userinfo", nil)
+
+			if tc.proof != "" {
+				req.Header.Set(oidc.HeaderDPoP, tc.proof)
+			}
+
+			err := oidc.ValidateDPoPBoundAccessToken(context.Background(), store, req, uri, tc.scheme, token, &oauthelia2.Request{Session: session}, now)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, tc.expected, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+			}
+		})
+	}
+}
+
+func TestAccessTokenFromRequest(t *testing.T) {
+	testCases := []struct {
+		name           string
+		header         string
+		expectedToken  string
+		expectedScheme string
+	}{
+		{"ShouldHandleBearer", "Bearer authelia_at_abc.123", "authelia_at_abc.123", oidc.AuthorizationSchemeBearer},
+		{"ShouldHandleDPoP", "DPoP authelia_at_abc.123", "authelia_at_abc.123", oidc.AuthorizationSchemeDPoP},
+		{"ShouldHandleDPoPCaseInsensitive", "dpop authelia_at_abc.123", "authelia_at_abc.123", oidc.AuthorizationSchemeDPoP},
+		{"ShouldHandleNone", "", "", oidc.AuthorizationSchemeBearer},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://auth.example.com/api/oidc/userinfo", nil)
+
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+
+			token, scheme := oidc.AccessTokenFromRequest(req)
+
+			assert.Equal(t, tc.expectedToken, token)
+			assert.Equal(t, tc.expectedScheme, scheme)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"errors"
+	"net/http"
 
 	oauthelia2 "authelia.com/provider/oauth2"
 )
@@ -30,4 +31,92 @@ var (
 	ErrConsentMalformedChallengeID = oauthelia2.ErrServerError.WithHint("Malformed consent session challenge ID.")
 
 	ErrClientAuthorizationUserAccessDenied = oauthelia2.ErrAccessDenied.WithHint("The user was denied access to this client.")
+
+	// ErrInvalidTarget is sent when the requested audience of an OAuth 2.0 Token Exchange is invalid or is not
+	// acceptable to the authorization server.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.2
+	ErrInvalidTarget = &oauthelia2.RFC6749Error{
+		ErrorField:       "invalid_target",
+		DescriptionField: "The authorization server is unwilling or unable to issue a token for any target service indicated by the 'audience' parameter.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrInvalidDPoPProof is sent when the DPoP proof of a request is invalid.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc9449#section-5
+	ErrInvalidDPoPProof = &oauthelia2.RFC6749Error{
+		ErrorField:       "invalid_dpop_proof",
+		DescriptionField: "The DPoP proof of the request is invalid.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrInvalidRedirectURI is sent when the value of one or more redirection URIs of a request to the Dynamic Client
+	// Registration endpoint is invalid.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.2
+	ErrInvalidRedirectURI = &oauthelia2.RFC6749Error{
+		ErrorField:       "invalid_redirect_uri",
+		DescriptionField: "The value of one or more redirection URIs is invalid.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrInvalidClientMetadata is sent when the value of one of the client metadata fields of a request to the Dynamic
+	// Client Registration endpoint is invalid.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.2
+	ErrInvalidClientMetadata = &oauthelia2.RFC6749Error{
+		ErrorField:       "invalid_client_metadata",
+		DescriptionField: "The value of one of the client metadata fields is invalid and the server has rejected this request.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrInvalidToken is sent when the bearer token of a request to a protected resource such as the Dynamic Client
+	// Configuration endpoint is missing, expired, revoked, malformed, or otherwise invalid.
+	//
+	// See: https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+	ErrInvalidToken = &oauthelia2.RFC6749Error{
+		ErrorField:       "invalid_token",
+		DescriptionField: "The access token provided is expired, revoked, malformed, or invalid for other reasons.",
+		CodeField:        http.StatusUnauthorized,
+	}
+
+	// ErrAuthorizationPending is sent when the end-user has not yet completed the out-of-band approval of a Client
+	// Initiated Backchannel Authentication request.
+	//
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.11
+	ErrAuthorizationPending = &oauthelia2.RFC6749Error{
+		ErrorField:       "authorization_pending",
+		DescriptionField: "The authorization request is still pending as the end user hasn't yet been authenticated.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrSlowDown is sent when a Client Initiated Backchannel Authentication request is still pending and the client
+	// polled the token endpoint more frequently than the interval permits.
+	//
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.11
+	ErrSlowDown = &oauthelia2.RFC6749Error{
+		ErrorField:       "slow_down",
+		DescriptionField: "The authorization request is still pending and polling should continue, but the interval must be increased.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrExpiredToken is sent when the auth_req_id of a Client Initiated Backchannel Authentication request has expired.
+	//
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.11
+	ErrExpiredToken = &oauthelia2.RFC6749Error{
+		ErrorField:       "expired_token",
+		DescriptionField: "The auth_req_id has expired and the Client will need to make a new Authentication Request.",
+		CodeField:        http.StatusBadRequest,
+	}
+
+	// ErrUnknownUserID is sent when the login_hint of a Client Initiated Backchannel Authentication request does not
+	// identify a valid user.
+	//
+	// See: https://openid.net/specs/openid-client-initiated-backchannel-authentication-core-1_0.html#rfc.section.13
+	ErrUnknownUserID = &oauthelia2.RFC6749Error{
+		ErrorField:       "unknown_user_id",
+		DescriptionField: "The OpenID Provider is not able to identify which end-user the Client wishes to be authenticated by means of the hint provided in the request.",
+		CodeField:        http.StatusBadRequest,
+	}
 )
@@ -33,6 +33,20 @@ func NewOpenIDConnectProvider(config *schema.IdentityProvidersOpenIDConnect, sto
 	return provider
 }
 
+// SetClients replaces the registered clients with the clients from the configuration. It returns false if the
+// ClientStore does not support replacing the registered clients.
+func (p *OpenIDConnectProvider) SetClients(config *schema.IdentityProvidersOpenIDConnect) (ok bool) {
+	var store *MemoryClientStore
+
+	if store, ok = p.Store.ClientStore.(*MemoryClientStore); !ok {
+		return false
+	}
+
+	store.SetClients(config)
+
+	return true
+}
+
 // GetOAuth2WellKnownConfiguration returns the discovery document for the OAuth Configuration.
 func (p *OpenIDConnectProvider) GetOAuth2WellKnownConfiguration(issuer string) OAuth2WellKnownConfiguration {
 	options := p.discovery.OAuth2WellKnownConfiguration.Copy()
@@ -42,10 +56,15 @@ func (p *OpenIDConnectProvider) GetOAuth2WellKnownConfiguration(issuer string) O
 	options.JWKSURI = fmt.Sprintf("%s%s", issuer, EndpointPathJWKs)
 	options.AuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathAuthorization)
 	options.PushedAuthorizationRequestEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathPushedAuthorizationRequest)
+	options.DeviceAuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathDeviceAuthorization)
 	options.TokenEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathToken)
 	options.IntrospectionEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathIntrospection)
 	options.RevocationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRevocation)
 
+	if p.isDynamicClientRegistrationEnabled() {
+		options.RegistrationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathClientRegistration)
+	}
+
 	return options
 }
 
@@ -58,10 +77,23 @@ func (p *OpenIDConnectProvider) GetOpenIDConnectWellKnownConfiguration(issuer st
 	options.JWKSURI = fmt.Sprintf("%s%s", issuer, EndpointPathJWKs)
 	options.AuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathAuthorization)
 	options.PushedAuthorizationRequestEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathPushedAuthorizationRequest)
+	options.DeviceAuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathDeviceAuthorization)
 	options.TokenEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathToken)
 	options.UserinfoEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathUserinfo)
 	options.IntrospectionEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathIntrospection)
 	options.RevocationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRevocation)
 
+	if p.isDynamicClientRegistrationEnabled() {
+		options.RegistrationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathClientRegistration)
+	}
+
+	if options.OpenIDConnectClientInitiatedBackChannelAuthFlowDiscoveryOptions != nil {
+		options.BackChannelAuthenticationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathBackchannelAuthentication)
+	}
+
 	return options
 }
+
+func (p *OpenIDConnectProvider) isDynamicClientRegistrationEnabled() bool {
+	return p.Store != nil && p.Store.config != nil && p.Store.config.DynamicClientRegistration.Enabled
+}
@@ -70,6 +70,48 @@ func NewSessionWithAuthorizeRequest(ctx Context, issuer *url.URL, kid, username
 	return session
 }
 
+// NewSessionWithRequester uses details from a Requester to generate an OpenIDSession for flows which do not have a
+// consent session such as the OAuth 2.0 Device Authorization Grant.
+func NewSessionWithRequester(ctx Context, issuer *url.URL, kid, username string, amr []string, extra map[string]any,
+	authTime time.Time, subject uuid.UUID, requester oauthelia2.Requester) (session *Session) {
+	if extra == nil {
+		extra = map[string]any{}
+	}
+
+	session = &Session{
+		DefaultSession: &openid.DefaultSession{
+			Claims: &jwt.IDTokenClaims{
+				Subject:     subject.String(),
+				Issuer:      issuer.String(),
+				AuthTime:    authTime,
+				RequestedAt: requester.GetRequestedAt(),
+				IssuedAt:    ctx.GetClock().Now().UTC(),
+				Nonce:       requester.GetRequestForm().Get(ClaimNonce),
+				Extra:       extra,
+
+				AuthenticationMethodsReferences: amr,
+			},
+			Headers: &jwt.Headers{
+				Extra: map[string]any{
+					JWTHeaderKeyIdentifier: kid,
+				},
+			},
+			Subject:  subject.String(),
+			Username: username,
+		},
+		KID:                   kid,
+		ClientID:              requester.GetClient().GetID(),
+		ExcludeNotBeforeClaim: false,
+		AllowedTopLevelClaims: nil,
+		Extra:                 map[string]any{},
+	}
+
+	session.Claims.Add(ClaimAuthorizedParty, session.ClientID)
+	session.Claims.Add(ClaimClientIdentifier, session.ClientID)
+
+	return session
+}
+
 // Session holds OpenID Connect 1.0 Session information.
 type Session struct {
 	*openid.DefaultSession `json:"id_token"`
@@ -80,9 +122,33 @@ type Session struct {
 	ClientCredentials     bool           `json:"client_credentials"`
 	ExcludeNotBeforeClaim bool           `json:"exclude_nbf_claim"`
 	AllowedTopLevelClaims []string       `json:"allowed_top_level_claims"`
+	Actor                 *SessionActor  `json:"actor,omitempty"`
+	DPoPJWKThumbprint     string         `json:"dpop_jkt,omitempty"`
 	Extra                 map[string]any `json:"extra"`
 }
 
+// SessionActor represents the party acting on behalf of the subject of a token issued via the OAuth 2.0 Token Exchange
+// grant. The Actor is the previous actor when the subject token was itself issued via a token exchange.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc8693#section-4.1
+type SessionActor struct {
+	Subject string        `json:"sub"`
+	Actor   *SessionActor `json:"act,omitempty"`
+}
+
+// ToClaim returns the value of the 'act' claim for this actor.
+func (a *SessionActor) ToClaim() (claim map[string]any) {
+	claim = map[string]any{
+		ClaimSubject: a.Subject,
+	}
+
+	if a.Actor != nil {
+		claim[ClaimActor] = a.Actor.ToClaim()
+	}
+
+	return claim
+}
+
 // GetChallengeID returns the challenge id.
 func (s *Session) GetChallengeID() uuid.NullUUID {
 	return s.ChallengeID
@@ -113,7 +179,7 @@ func (s *Session) GetJWTClaims() jwt.JWTClaimsContainer {
 
 	for _, cl := range s.AllowedTopLevelClaims {
 		switch cl {
-		case ClaimJWTID, ClaimIssuer, ClaimSubject, ClaimAudience, ClaimExpirationTime, ClaimNotBefore, ClaimIssuedAt, ClaimClientIdentifier, ClaimScopeNonStandard, ClaimExtra:
+		case ClaimJWTID, ClaimIssuer, ClaimSubject, ClaimAudience, ClaimExpirationTime, ClaimNotBefore, ClaimIssuedAt, ClaimClientIdentifier, ClaimScopeNonStandard, ClaimExtra, ClaimConfirmation:
 			continue
 		case ClaimAuthenticationMethodsReference:
 			amr = true
@@ -153,6 +219,16 @@ func (s *Session) GetJWTClaims() jwt.JWTClaimsContainer {
 		claims.Extra[ClaimClientIdentifier] = s.ClientID
 	}
 
+	if s.Actor != nil {
+		claims.Extra[ClaimActor] = s.Actor.ToClaim()
+	}
+
+	if len(s.DPoPJWKThumbprint) != 0 {
+		claims.Extra[ClaimConfirmation] = map[string]any{
+			ClaimJWKThumbprint: s.DPoPJWKThumbprint,
+		}
+	}
+
 	return claims
 }
 
@@ -165,9 +241,24 @@ func (s *Session) GetIDTokenClaims() *jwt.IDTokenClaims {
 	return s.DefaultSession.Claims
 }
 
-// GetExtraClaims returns the Extra/Unregistered claims for this session.
+// GetExtraClaims returns the Extra/Unregistered claims for this session. When the session is bound to a DPoP key the
+// confirmation claim is included so it's available to Token Introspection.
 func (s *Session) GetExtraClaims() map[string]any {
-	return s.Extra
+	if len(s.DPoPJWKThumbprint) == 0 {
+		return s.Extra
+	}
+
+	extra := make(map[string]any, len(s.Extra)+1)
+
+	for key, value := range s.Extra {
+		extra[key] = value
+	}
+
+	extra[ClaimConfirmation] = map[string]any{
+		ClaimJWKThumbprint: s.DPoPJWKThumbprint,
+	}
+
+	return extra
 }
 
 // Clone copies the OpenIDSession to a new oauthelia2.Session.
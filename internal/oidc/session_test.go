@@ -65,6 +65,21 @@ func TestOpenIDSession_GetExtraClaims(t *testing.T) {
 				"a": 1,
 			},
 		},
+		{
+			"ShouldReturnConfirmation",
+			&oidc.Session{
+				DPoPJWKThumbprint: "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I",
+				Extra: map[string]any{
+					"a": 1,
+				},
+			},
+			map[string]any{
+				"a": 1,
+				oidc.ClaimConfirmation: map[string]any{
+					oidc.ClaimJWKThumbprint: "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I",
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -171,6 +186,16 @@ func TestSession_GetJWTClaims(t *testing.T) {
 			}, Extra: map[string]any{}, ClientID: abc, AllowedTopLevelClaims: []string{oidc.ClaimClientIdentifier, oidc.ClaimAuthenticationMethodsReference}},
 			&jwt.JWTClaims{Extra: map[string]any{oidc.ClaimAuthenticationMethodsReference: []string{oidc.AMRMultiFactorAuthentication}, oidc.ClaimClientIdentifier: abc}},
 		},
+		{
+			"ShouldIncludeActor",
+			&oidc.Session{DefaultSession: openid.NewDefaultSession(), ClientID: abc, Actor: &oidc.SessionActor{Subject: abc, Actor: &oidc.SessionActor{Subject: "backend"}}},
+			&jwt.JWTClaims{Extra: map[string]any{oidc.ClaimClientIdentifier: abc, oidc.ClaimActor: map[string]any{oidc.ClaimSubject: abc, oidc.ClaimActor: map[string]any{oidc.ClaimSubject: "backend"}}}},
+		},
+		{
+			"ShouldIncludeConfirmation",
+			&oidc.Session{DefaultSession: openid.NewDefaultSession(), ClientID: abc, DPoPJWKThumbprint: "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I", AllowedTopLevelClaims: []string{oidc.ClaimConfirmation}},
+			&jwt.JWTClaims{Extra: map[string]any{oidc.ClaimClientIdentifier: abc, oidc.ClaimConfirmation: map[string]any{oidc.ClaimJWKThumbprint: "0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"}}},
+		},
 	}
 
 	for _, tc := range testCases {
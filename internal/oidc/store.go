@@ -23,31 +23,48 @@ func NewStore(config *schema.IdentityProvidersOpenIDConnect, provider storage.Pr
 	store = &Store{
 		ClientStore: NewMemoryClientStore(config),
 		provider:    provider,
+		config:      config,
 	}
 
 	return store
 }
 
 func NewMemoryClientStore(config *schema.IdentityProvidersOpenIDConnect) (store *MemoryClientStore) {
-	logger := logging.Logger()
+	store = &MemoryClientStore{}
 
-	store = &MemoryClientStore{
-		clients: map[string]Client{},
-	}
+	store.SetClients(config)
+
+	return store
+}
+
+// SetClients replaces all of the registered clients with the clients from the configuration.
+func (s *MemoryClientStore) SetClients(config *schema.IdentityProvidersOpenIDConnect) {
+	logger := logging.LoggerModule(logging.ModuleOpenIDConnect)
+
+	clients := make(map[string]Client, len(config.Clients))
 
 	for _, client := range config.Clients {
 		policy := authorization.NewLevel(client.AuthorizationPolicy)
 		logger.Debugf("Registering client %s with policy %s (%v)", client.ID, client.AuthorizationPolicy, policy)
 
-		store.clients[client.ID] = NewClient(client, config)
+		clients[client.ID] = NewClient(client, config)
 	}
 
-	return store
+	s.mu.Lock()
+
+	defer s.mu.Unlock()
+
+	s.clients = clients
 }
 
 // GetRegisteredClient returns a Client matching the provided id.
 func (s *MemoryClientStore) GetRegisteredClient(_ context.Context, id string) (client Client, err error) {
+	s.mu.RLock()
+
 	client, ok := s.clients[id]
+
+	s.mu.RUnlock()
+
 	if !ok {
 		return nil, oauthelia2.ErrInvalidClient.WithDebugf("Client with id '%s' does not appear to be a registered client.", id)
 	}
@@ -55,6 +72,31 @@ func (s *MemoryClientStore) GetRegisteredClient(_ context.Context, id string) (c
 	return client, nil
 }
 
+// GetRegisteredClient returns a Client matching the provided id. The clients from the configuration take precedence,
+// and if the Dynamic Client Registration endpoint is enabled the clients registered via that endpoint are loaded from
+// the storage provider.
+func (s *Store) GetRegisteredClient(ctx context.Context, id string) (client Client, err error) {
+	if client, err = s.ClientStore.GetRegisteredClient(ctx, id); err == nil || s.config == nil || !s.config.DynamicClientRegistration.Enabled {
+		return client, err
+	}
+
+	var dynamic *model.OAuth2DynamicClient
+
+	if dynamic, err = s.provider.LoadOAuth2DynamicClient(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, oauthelia2.ErrInvalidClient.WithDebugf("Client with id '%s' does not appear to be a registered client.", id)
+		}
+
+		return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Error occurred loading the client with id '%s': %s", id, err)
+	}
+
+	if client, err = NewDynamicClient(dynamic, s.config); err != nil {
+		return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Error occurred loading the client with id '%s': %s", id, err)
+	}
+
+	return client, nil
+}
+
 // GenerateOpaqueUserID either retrieves or creates an opaque user id from a sectorID and username.
 func (s *Store) GenerateOpaqueUserID(ctx context.Context, sectorID, username string) (opaqueID *model.UserOpaqueIdentifier, err error) {
 	if opaqueID, err = s.provider.LoadUserOpaqueIdentifierBySignature(ctx, "openid", sectorID, username); err != nil {
@@ -179,9 +221,14 @@ func (s *Store) DeleteAccessTokenSession(ctx context.Context, signature string)
 
 // RevokeAccessToken revokes an access token as specified in: https://datatracker.ietf.org/doc/html/rfc7009#section-2.1
 // If the token passed to the request is an access token, the server MAY revoke the respective refresh token as well.
+// Tokens which were issued via the OAuth 2.0 Token Exchange grant from this access token are also revoked.
 // This implements a portion of oauth2.TokenRevocationStorage.
 func (s *Store) RevokeAccessToken(ctx context.Context, requestID string) (err error) {
-	return s.revokeSessionByRequestID(ctx, storage.OAuth2SessionTypeAccessToken, requestID)
+	if err = s.revokeSessionByRequestID(ctx, storage.OAuth2SessionTypeAccessToken, requestID); err != nil {
+		return err
+	}
+
+	return s.revokeTokenExchangeDescendants(ctx, requestID)
 }
 
 // GetAccessTokenSession gets the authorization request for a given access token.
@@ -293,6 +340,106 @@ func (s *Store) DeletePARSession(ctx context.Context, requestURI string) (err er
 	return s.provider.RevokeOAuth2PARContext(ctx, requestURI)
 }
 
+// CreateDeviceCodeSession stores the device authorization request for a given device code signature.
+// This implements a portion of rfc8628.RFC8628CoreStorage.
+func (s *Store) CreateDeviceCodeSession(ctx context.Context, signature string, request oauthelia2.DeviceAuthorizeRequester) (err error) {
+	var session *model.OAuth2DeviceCodeSession
+
+	if session, err = model.NewOAuth2DeviceCodeSessionFromRequest(request); err != nil {
+		return err
+	}
+
+	session.Signature = signature
+
+	return s.provider.SaveOAuth2DeviceCodeSession(ctx, *session)
+}
+
+// UpdateDeviceCodeSession updates the device authorization request for a given device code signature, this is used
+// when the user approves or denies the request and when the device polls the token endpoint.
+// This implements a portion of rfc8628.RFC8628CoreStorage.
+func (s *Store) UpdateDeviceCodeSession(ctx context.Context, signature string, request oauthelia2.DeviceAuthorizeRequester) (err error) {
+	var session *model.OAuth2DeviceCodeSession
+
+	if session, err = model.NewOAuth2DeviceCodeSessionFromRequest(request); err != nil {
+		return err
+	}
+
+	session.Signature = signature
+
+	return s.provider.UpdateOAuth2DeviceCodeSession(ctx, *session)
+}
+
+// GetDeviceCodeSession hydrates the session based on the given device code signature and returns the device
+// authorization request. If the device code has been invalidated with InvalidateDeviceCodeSession, this method returns
+// the request along with the oauthelia2.ErrInactiveToken error.
+// This implements a portion of rfc8628.RFC8628CoreStorage.
+func (s *Store) GetDeviceCodeSession(ctx context.Context, signature string, session oauthelia2.Session) (request oauthelia2.DeviceAuthorizeRequester, err error) {
+	return s.loadDeviceCodeSession(ctx, s.provider.LoadOAuth2DeviceCodeSession, signature, session)
+}
+
+// GetDeviceCodeSessionByUserCode hydrates the session based on the given user code signature and returns the device
+// authorization request.
+// This implements a portion of rfc8628.RFC8628CoreStorage.
+func (s *Store) GetDeviceCodeSessionByUserCode(ctx context.Context, signature string, session oauthelia2.Session) (request oauthelia2.DeviceAuthorizeRequester, err error) {
+	return s.loadDeviceCodeSession(ctx, s.provider.LoadOAuth2DeviceCodeSessionByUserCode, signature, session)
+}
+
+// InvalidateDeviceCodeSession is called when a device code has been exchanged for tokens, consecutive requests to
+// GetDeviceCodeSession return the oauthelia2.ErrInactiveToken error.
+// This implements a portion of rfc8628.RFC8628CoreStorage.
+func (s *Store) InvalidateDeviceCodeSession(ctx context.Context, signature string) (err error) {
+	return s.provider.DeactivateOAuth2DeviceCodeSession(ctx, signature)
+}
+
+// GetBackchannelAuthenticationSession hydrates the session based on the given auth_req_id signature and returns the
+// stored OpenID Connect 1.0 Client Initiated Backchannel Authentication request along with the original requester. If
+// the request has been invalidated with InvalidateBackchannelAuthenticationSession, this method returns the
+// oauthelia2.ErrInactiveToken error.
+func (s *Store) GetBackchannelAuthenticationSession(ctx context.Context, signature string, session oauthelia2.Session) (request *model.OAuth2CIBARequest, r oauthelia2.Requester, err error) {
+	if request, err = s.provider.LoadOAuth2CIBARequest(ctx, signature); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil, oauthelia2.ErrNotFound
+		default:
+			return nil, nil, err
+		}
+	}
+
+	if r, err = request.ToRequest(ctx, session, s); err != nil {
+		return nil, nil, err
+	}
+
+	if !request.Active {
+		return request, r, oauthelia2.ErrInactiveToken
+	}
+
+	return request, r, nil
+}
+
+// UpdateBackchannelAuthenticationSessionCheckedAt records the time the client last polled the token endpoint for the
+// OpenID Connect 1.0 Client Initiated Backchannel Authentication request with the given auth_req_id signature.
+func (s *Store) UpdateBackchannelAuthenticationSessionCheckedAt(ctx context.Context, signature string, checkedAt time.Time) (err error) {
+	return s.provider.UpdateOAuth2CIBARequestCheckedAt(ctx, signature, checkedAt)
+}
+
+// InvalidateBackchannelAuthenticationSession is called when an auth_req_id has been exchanged for tokens, consecutive
+// requests to GetBackchannelAuthenticationSession return the oauthelia2.ErrInactiveToken error.
+func (s *Store) InvalidateBackchannelAuthenticationSession(ctx context.Context, signature string) (err error) {
+	return s.provider.DeactivateOAuth2CIBARequest(ctx, signature)
+}
+
+// CreateTokenExchangeLineage stores the relationship between a token issued via the OAuth 2.0 Token Exchange grant and
+// the subject token it was exchanged from so the exchanged token can be revoked alongside the subject token.
+func (s *Store) CreateTokenExchangeLineage(ctx context.Context, request, parent oauthelia2.Requester) (err error) {
+	var lineage *model.OAuth2TokenExchangeLineage
+
+	if lineage, err = model.NewOAuth2TokenExchangeLineage(request, parent); err != nil {
+		return err
+	}
+
+	return s.provider.SaveOAuth2TokenExchangeLineage(ctx, *lineage)
+}
+
 // IsJWTUsed implements an interface required for RFC7523.
 func (s *Store) IsJWTUsed(ctx context.Context, jti string) (used bool, err error) {
 	if err = s.ClientAssertionJWTValid(ctx, jti); err != nil {
@@ -338,6 +485,29 @@ func (s *Store) loadRequesterBySignature(ctx context.Context, sessionType storag
 	return r, nil
 }
 
+func (s *Store) loadDeviceCodeSession(ctx context.Context, load func(ctx context.Context, signature string) (*model.OAuth2DeviceCodeSession, error), signature string, session oauthelia2.Session) (r oauthelia2.DeviceAuthorizeRequester, err error) {
+	var sessionModel *model.OAuth2DeviceCodeSession
+
+	if sessionModel, err = load(ctx, signature); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, oauthelia2.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if r, err = sessionModel.ToRequest(ctx, session, s); err != nil {
+		return nil, err
+	}
+
+	if !sessionModel.Active {
+		return r, oauthelia2.ErrInactiveToken
+	}
+
+	return r, nil
+}
+
 func (s *Store) saveSession(ctx context.Context, sessionType storage.OAuth2SessionType, signature string, r oauthelia2.Requester) (err error) {
 	var session *model.OAuth2Session
 
@@ -352,6 +522,22 @@ func (s *Store) revokeSessionBySignature(ctx context.Context, sessionType storag
 	return s.provider.RevokeOAuth2Session(ctx, sessionType, signature)
 }
 
+func (s *Store) revokeTokenExchangeDescendants(ctx context.Context, requestID string) (err error) {
+	var lineage []model.OAuth2TokenExchangeLineage
+
+	if lineage, err = s.provider.LoadOAuth2TokenExchangeLineageByParentRequestID(ctx, requestID); err != nil {
+		return err
+	}
+
+	for _, l := range lineage {
+		if err = s.RevokeAccessToken(ctx, l.RequestID); err != nil && !errors.Is(err, oauthelia2.ErrNotFound) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *Store) revokeSessionByRequestID(ctx context.Context, sessionType storage.OAuth2SessionType, requestID string) (err error) {
 	if err = s.provider.RevokeOAuth2SessionByRequestID(ctx, sessionType, requestID); err != nil {
 		switch {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	oauthelia2 "authelia.com/provider/oauth2"
+	"authelia.com/provider/oauth2/handler/openid"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -126,6 +127,56 @@ func TestOpenIDConnectStore_IsValidClientID(t *testing.T) {
 	assert.False(t, invalidClient)
 }
 
+func TestOpenIDConnectStore_GetRegisteredClient_DynamicClient(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mocks.NewMockStorage(ctrl)
+
+	s := oidc.NewStore(&schema.IdentityProvidersOpenIDConnect{
+		Clients: []schema.IdentityProvidersOpenIDConnectClient{
+			{
+				ID:                  myclient,
+				AuthorizationPolicy: onefactor,
+				Secret:              tOpenIDConnectPlainTextClientSecret,
+			},
+		},
+		DynamicClientRegistration: schema.IdentityProvidersOpenIDConnectDynamicClientRegistration{
+			Enabled:             true,
+			AuthorizationPolicy: authorization.TwoFactor.String(),
+		},
+	}, mock)
+
+	gomock.InOrder(
+		mock.EXPECT().LoadOAuth2DynamicClient(ctx, "dynamic").
+			Return(&model.OAuth2DynamicClient{ClientID: "dynamic", Metadata: []byte(`{"redirect_uris":["https://app.example.com/callback"],"token_endpoint_auth_method":"none"}`)}, nil),
+		mock.EXPECT().LoadOAuth2DynamicClient(ctx, "missing").
+			Return(nil, sql.ErrNoRows),
+		mock.EXPECT().LoadOAuth2DynamicClient(ctx, "broken").
+			Return(nil, fmt.Errorf("timeout")),
+	)
+
+	client, err := s.GetRegisteredClient(ctx, myclient)
+	require.NoError(t, err)
+	assert.Equal(t, myclient, client.GetID())
+
+	client, err = s.GetRegisteredClient(ctx, "dynamic")
+	require.NoError(t, err)
+	assert.Equal(t, "dynamic", client.GetID())
+	assert.True(t, client.IsPublic())
+	assert.Equal(t, authorization.TwoFactor, client.GetAuthorizationPolicyRequiredLevel(authorization.Subject{}))
+
+	client, err = s.GetRegisteredClient(ctx, "missing")
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "invalid_client")
+
+	client, err = s.GetRegisteredClient(ctx, "broken")
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "server_error")
+}
+
 func TestStoreSuite(t *testing.T) {
 	suite.Run(t, &StoreSuite{})
 }
@@ -393,6 +444,10 @@ func (s *StoreSuite) TestRevokeSessions() {
 			EXPECT().
 			RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "65471ccb-d650-4006-a95f-cb4f4e3d7200").
 			Return(nil),
+		s.mock.
+			EXPECT().
+			LoadOAuth2TokenExchangeLineageByParentRequestID(s.ctx, "65471ccb-d650-4006-a95f-cb4f4e3d7200").
+			Return(nil, nil),
 		s.mock.
 			EXPECT().
 			RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "65471ccb-d650-4006-a95f-cb4f4e3d7201").
@@ -589,6 +644,99 @@ func (s *StoreSuite) TestGetSessions() {
 	s.EqualError(err, "sql: no rows in result set")
 }
 
+func (s *StoreSuite) TestGetDeviceCodeSessions() {
+	session := &oidc.Session{
+		ClientID: "hs256",
+	}
+	sessionData, _ := json.Marshal(session)
+
+	gomock.InOrder(
+		s.mock.EXPECT().LoadOAuth2DeviceCodeSession(s.ctx, "dc_123").
+			Return(&model.OAuth2DeviceCodeSession{ClientID: "hs256", Signature: "dc_123", UserCodeSignature: "uc_123", Session: sessionData, Active: true}, nil),
+		s.mock.EXPECT().LoadOAuth2DeviceCodeSession(s.ctx, "dc_456").
+			Return(&model.OAuth2DeviceCodeSession{ClientID: "hs256", Signature: "dc_456", Session: sessionData, Active: false}, nil),
+		s.mock.EXPECT().LoadOAuth2DeviceCodeSession(s.ctx, "dc_aaa").
+			Return(nil, sql.ErrNoRows),
+		s.mock.EXPECT().LoadOAuth2DeviceCodeSessionByUserCode(s.ctx, "uc_123").
+			Return(&model.OAuth2DeviceCodeSession{ClientID: "hs256", Signature: "dc_123", UserCodeSignature: "uc_123", Session: sessionData, Active: true}, nil),
+		s.mock.EXPECT().LoadOAuth2DeviceCodeSessionByUserCode(s.ctx, "uc_130").
+			Return(nil, fmt.Errorf("timeout")),
+		s.mock.EXPECT().DeactivateOAuth2DeviceCodeSession(s.ctx, "dc_123").
+			Return(nil),
+	)
+
+	var (
+		r   oauthelia2.DeviceAuthorizeRequester
+		err error
+	)
+
+	r, err = s.store.GetDeviceCodeSession(s.ctx, "dc_123", &oidc.Session{})
+	s.Require().NotNil(r)
+	s.NoError(err)
+	s.Equal("uc_123", r.GetUserCodeSignature())
+
+	r, err = s.store.GetDeviceCodeSession(s.ctx, "dc_456", &oidc.Session{})
+	s.NotNil(r)
+	s.ErrorIs(err, oauthelia2.ErrInactiveToken)
+
+	r, err = s.store.GetDeviceCodeSession(s.ctx, "dc_aaa", &oidc.Session{})
+	s.Nil(r)
+	s.EqualError(err, "not_found")
+
+	r, err = s.store.GetDeviceCodeSessionByUserCode(s.ctx, "uc_123", &oidc.Session{})
+	s.Require().NotNil(r)
+	s.NoError(err)
+	s.Equal("dc_123", r.GetDeviceCodeSignature())
+
+	r, err = s.store.GetDeviceCodeSessionByUserCode(s.ctx, "uc_130", &oidc.Session{})
+	s.Nil(r)
+	s.EqualError(err, "timeout")
+
+	s.NoError(s.store.InvalidateDeviceCodeSession(s.ctx, "dc_123"))
+}
+
+func (s *StoreSuite) TestTokenExchangeLineage() {
+	requestedAt := time.Unix(1700000000, 0)
+
+	gomock.InOrder(
+		s.mock.EXPECT().SaveOAuth2TokenExchangeLineage(s.ctx, model.OAuth2TokenExchangeLineage{RequestID: "child", ParentRequestID: "parent", ClientID: "backend", ParentClientID: "frontend", Subject: sql.NullString{String: "john-subject", Valid: true}, ExchangedAt: requestedAt}).
+			Return(nil),
+		s.mock.EXPECT().RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "parent").
+			Return(nil),
+		s.mock.EXPECT().LoadOAuth2TokenExchangeLineageByParentRequestID(s.ctx, "parent").
+			Return([]model.OAuth2TokenExchangeLineage{{RequestID: "child"}, {RequestID: "sibling"}}, nil),
+		s.mock.EXPECT().RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "child").
+			Return(nil),
+		s.mock.EXPECT().LoadOAuth2TokenExchangeLineageByParentRequestID(s.ctx, "child").
+			Return([]model.OAuth2TokenExchangeLineage{{RequestID: "grandchild"}}, nil),
+		s.mock.EXPECT().RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "grandchild").
+			Return(nil),
+		s.mock.EXPECT().LoadOAuth2TokenExchangeLineageByParentRequestID(s.ctx, "grandchild").
+			Return(nil, nil),
+		s.mock.EXPECT().RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "sibling").
+			Return(sql.ErrNoRows),
+		s.mock.EXPECT().RevokeOAuth2SessionByRequestID(s.ctx, storage.OAuth2SessionTypeAccessToken, "other").
+			Return(nil),
+		s.mock.EXPECT().LoadOAuth2TokenExchangeLineageByParentRequestID(s.ctx, "other").
+			Return(nil, fmt.Errorf("timeout")),
+	)
+
+	s.NoError(s.store.CreateTokenExchangeLineage(s.ctx, &oauthelia2.Request{
+		ID:          "child",
+		RequestedAt: requestedAt,
+		Client:      &oidc.RegisteredClient{ID: "backend"},
+		Session: &oidc.Session{
+			DefaultSession: &openid.DefaultSession{Subject: "john-subject"},
+		},
+	}, &oauthelia2.Request{
+		ID:     "parent",
+		Client: &oidc.RegisteredClient{ID: "frontend"},
+	}))
+
+	s.NoError(s.store.RevokeAccessToken(s.ctx, "parent"))
+	s.EqualError(s.store.RevokeAccessToken(s.ctx, "other"), "timeout")
+}
+
 func (s *StoreSuite) TestIsJWTUsed() {
 	gomock.InOrder(
 		s.mock.
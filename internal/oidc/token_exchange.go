@@ -0,0 +1,218 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"authelia.com/provider/oauth2/handler/oauth2"
+)
+
+// TokenExchangeStorage describes the storage requirements of the TokenExchangeGrantHandler.
+type TokenExchangeStorage interface {
+	oauth2.AccessTokenStorage
+
+	// CreateTokenExchangeLineage stores the relationship between a token issued via the OAuth 2.0 Token Exchange grant
+	// and the subject token it was exchanged from.
+	CreateTokenExchangeLineage(ctx context.Context, request, parent oauthelia2.Requester) (err error)
+}
+
+// TokenExchangeGrantHandler is an oauthelia2.TokenEndpointHandler which implements the OAuth 2.0 Token Exchange grant
+// for the purpose of delegation. A confidential client exchanges an access token issued to a user for another access
+// token for the same user which is restricted to the requested audience and a subset of the granted scopes.
+//
+// See: https://datatracker.ietf.org/doc/html/rfc8693
+type TokenExchangeGrantHandler struct {
+	AccessTokenStrategy oauth2.AccessTokenStrategy
+	Storage             TokenExchangeStorage
+	Config              interface {
+		oauthelia2.AccessTokenLifespanProvider
+		oauthelia2.ScopeStrategyProvider
+		oauthelia2.AudienceStrategyProvider
+	}
+}
+
+// HandleTokenEndpointRequest implements oauthelia2.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) HandleTokenEndpointRequest(ctx context.Context, requester oauthelia2.AccessRequester) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return oauthelia2.ErrUnknownRequest
+	}
+
+	client := requester.GetClient()
+
+	if client.IsPublic() {
+		return oauthelia2.ErrInvalidGrant.WithHintf("The OAuth 2.0 Client is marked as public and is thus not allowed to use the authorization grant '%s'.", GrantTypeTokenExchange)
+	}
+
+	if !client.GetGrantTypes().Has(GrantTypeTokenExchange) {
+		return oauthelia2.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", GrantTypeTokenExchange)
+	}
+
+	var parent oauthelia2.Requester
+
+	if parent, err = h.getSubjectTokenRequester(ctx, requester); err != nil {
+		return err
+	}
+
+	if err = h.handleTokenExchangeScopes(ctx, requester, parent); err != nil {
+		return err
+	}
+
+	audience := requester.GetRequestedAudience()
+
+	if len(audience) == 0 {
+		return ErrInvalidTarget.WithHint("The 'audience' parameter must be provided to restrict the audience of the exchanged token.")
+	}
+
+	if err = h.Config.GetAudienceStrategy(ctx)(client.GetAudience(), audience); err != nil {
+		return ErrInvalidTarget.WithHint("The OAuth 2.0 Client is not allowed to request one or more of the requested audiences.").WithWrap(err).WithDebug(oauthelia2.ErrorToDebugRFC6749Error(err).Error())
+	}
+
+	for _, aud := range audience {
+		requester.GrantAudience(aud)
+	}
+
+	var (
+		session *Session
+		ok      bool
+	)
+
+	if session, ok = parent.GetSession().Clone().(*Session); !ok {
+		return oauthelia2.ErrServerError.WithDebugf("Failed to clone the session of the subject token as the session type '%T' is not supported.", parent.GetSession())
+	}
+
+	session.Actor = &SessionActor{Subject: client.GetID(), Actor: session.Actor}
+	session.ClientID = client.GetID()
+
+	expiresAt := time.Now().UTC().Add(oauthelia2.GetEffectiveLifespan(client, GrantTypeTokenExchange, oauthelia2.AccessToken, h.Config.GetAccessTokenLifespan(ctx))).Round(time.Second)
+
+	// The exchanged token must never outlive the subject token it was exchanged from.
+	if parentExpiresAt := parent.GetSession().GetExpiresAt(oauthelia2.AccessToken); !parentExpiresAt.IsZero() && parentExpiresAt.Before(expiresAt) {
+		expiresAt = parentExpiresAt
+	}
+
+	session.SetExpiresAt(oauthelia2.AccessToken, expiresAt)
+
+	requester.SetSession(session)
+
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements oauthelia2.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester oauthelia2.AccessRequester, responder oauthelia2.AccessResponder) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return oauthelia2.ErrUnknownRequest
+	}
+
+	var (
+		parent           oauthelia2.Requester
+		token, signature string
+	)
+
+	if parent, err = h.getSubjectTokenRequester(ctx, requester); err != nil {
+		return err
+	}
+
+	if token, signature, err = h.AccessTokenStrategy.GenerateAccessToken(ctx, requester); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to generate the access token with error: %s.", err.Error())
+	}
+
+	if err = h.Storage.CreateAccessTokenSession(ctx, signature, requester.Sanitize([]string{})); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to save the access token with error: %s.", err.Error())
+	}
+
+	if err = h.Storage.CreateTokenExchangeLineage(ctx, requester, parent); err != nil {
+		return oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to save the token exchange lineage with error: %s.", err.Error())
+	}
+
+	responder.SetAccessToken(token)
+	responder.SetTokenType(oauthelia2.BearerAccessToken)
+	responder.SetExpiresIn(time.Until(requester.GetSession().GetExpiresAt(oauthelia2.AccessToken)).Round(time.Second))
+	responder.SetScopes(requester.GetGrantedScopes())
+	responder.SetExtra(FormParameterIssuedTokenType, TokenTypeAccessToken)
+
+	return nil
+}
+
+// CanSkipClientAuth implements oauthelia2.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) CanSkipClientAuth(ctx context.Context, requester oauthelia2.AccessRequester) (skip bool) {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements oauthelia2.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) CanHandleTokenEndpointRequest(ctx context.Context, requester oauthelia2.AccessRequester) (handle bool) {
+	return requester.GetGrantTypes().ExactOne(GrantTypeTokenExchange)
+}
+
+func (h *TokenExchangeGrantHandler) getSubjectTokenRequester(ctx context.Context, requester oauthelia2.AccessRequester) (parent oauthelia2.Requester, err error) {
+	form := requester.GetRequestForm()
+
+	token := form.Get(FormParameterSubjectToken)
+
+	switch tokenType := form.Get(FormParameterRequestedTokenType); {
+	case len(token) == 0:
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be provided.", FormParameterSubjectToken)
+	case form.Get(FormParameterSubjectTokenType) != TokenTypeAccessToken:
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be '%s'.", FormParameterSubjectTokenType, TokenTypeAccessToken)
+	case form.Has(FormParameterActorToken) || form.Has(FormParameterActorTokenType):
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter is not supported as the OAuth 2.0 Client is always the actor.", FormParameterActorToken)
+	case len(tokenType) != 0 && tokenType != TokenTypeAccessToken:
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' parameter must be '%s' when provided.", FormParameterRequestedTokenType, TokenTypeAccessToken)
+	}
+
+	if parent, err = h.Storage.GetAccessTokenSession(ctx, h.AccessTokenStrategy.AccessTokenSignature(ctx, token), NewSession()); err != nil {
+		switch {
+		case errors.Is(err, oauthelia2.ErrNotFound), errors.Is(err, oauthelia2.ErrInactiveToken):
+			return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' is not valid or has been revoked.", FormParameterSubjectToken).WithWrap(err)
+		default:
+			return nil, oauthelia2.ErrServerError.WithWrap(err).WithDebugf("Failed to lookup the subject token with error: %s.", err.Error())
+		}
+	}
+
+	if err = h.AccessTokenStrategy.ValidateAccessToken(ctx, parent, token); err != nil {
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' is not valid or has expired.", FormParameterSubjectToken).WithWrap(err).WithDebug(oauthelia2.ErrorToDebugRFC6749Error(err).Error())
+	}
+
+	if session, ok := parent.GetSession().(*Session); !ok || session.ClientCredentials || len(session.GetSubject()) == 0 {
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' must have been issued to a user.", FormParameterSubjectToken)
+	}
+
+	// The subject token must either have been issued to this client or must include this client in its audience.
+	clientID := requester.GetClient().GetID()
+
+	if parent.GetClient().GetID() != clientID && !parent.GetGrantedAudience().Has(clientID) {
+		return nil, oauthelia2.ErrInvalidRequest.WithHintf("The '%s' was not issued to or for the OAuth 2.0 Client.", FormParameterSubjectToken)
+	}
+
+	return parent, nil
+}
+
+func (h *TokenExchangeGrantHandler) handleTokenExchangeScopes(ctx context.Context, requester oauthelia2.AccessRequester, parent oauthelia2.Requester) (err error) {
+	client, strategy := requester.GetClient(), h.Config.GetScopeStrategy(ctx)
+
+	scopes := requester.GetRequestedScopes()
+
+	if len(scopes) == 0 {
+		for _, scope := range parent.GetGrantedScopes() {
+			if strategy(client.GetScopes(), scope) {
+				requester.GrantScope(scope)
+			}
+		}
+
+		return nil
+	}
+
+	for _, scope := range scopes {
+		switch {
+		case !strategy(client.GetScopes(), scope):
+			return oauthelia2.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to request scope '%s'.", scope)
+		case !parent.GetGrantedScopes().Has(scope):
+			return oauthelia2.ErrInvalidScope.WithHintf("The scope '%s' was not granted to the '%s' and can't be requested.", scope, FormParameterSubjectToken)
+		}
+
+		requester.GrantScope(scope)
+	}
+
+	return nil
+}
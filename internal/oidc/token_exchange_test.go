@@ -0,0 +1,100 @@
+package oidc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	oauthelia2 "authelia.com/provider/oauth2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func TestTokenExchangeGrantHandler_CanHandleTokenEndpointRequest(t *testing.T) {
+	handler := &oidc.TokenExchangeGrantHandler{}
+
+	assert.True(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeTokenExchange}}))
+	assert.False(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeClientCredentials}}))
+	assert.False(t, handler.CanHandleTokenEndpointRequest(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeTokenExchange, oidc.GrantTypeClientCredentials}}))
+	assert.False(t, handler.CanSkipClientAuth(context.Background(), &oauthelia2.AccessRequest{GrantTypes: oauthelia2.Arguments{oidc.GrantTypeTokenExchange}}))
+}
+
+func TestTokenExchangeGrantHandler_HandleTokenEndpointRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		client   *oidc.RegisteredClient
+		form     url.Values
+		grant    string
+		expected string
+	}{
+		{
+			"ShouldNotHandleOtherGrantTypes",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{},
+			oidc.GrantTypeClientCredentials,
+			"The handler is not responsible for this request.",
+		},
+		{
+			"ShouldRejectPublicClients",
+			&oidc.RegisteredClient{ID: "backend", Public: true, GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{},
+			oidc.GrantTypeTokenExchange,
+			"The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client. The OAuth 2.0 Client is marked as public and is thus not allowed to use the authorization grant 'urn:ietf:params:oauth:grant-type:token-exchange'.",
+		},
+		{
+			"ShouldRejectClientsWithoutGrantType",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeClientCredentials}},
+			url.Values{},
+			oidc.GrantTypeTokenExchange,
+			"The client is not authorized to request a token using this method. The OAuth 2.0 Client is not allowed to use the authorization grant 'urn:ietf:params:oauth:grant-type:token-exchange'.",
+		},
+		{
+			"ShouldRejectMissingSubjectToken",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{oidc.FormParameterSubjectTokenType: []string{oidc.TokenTypeAccessToken}},
+			oidc.GrantTypeTokenExchange,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'subject_token' parameter must be provided.",
+		},
+		{
+			"ShouldRejectUnsupportedSubjectTokenType",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{oidc.FormParameterSubjectToken: []string{"authelia_at_abc.123"}, oidc.FormParameterSubjectTokenType: []string{"urn:ietf:params:oauth:token-type:id_token"}},
+			oidc.GrantTypeTokenExchange,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'subject_token_type' parameter must be 'urn:ietf:params:oauth:token-type:access_token'.",
+		},
+		{
+			"ShouldRejectActorToken",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{oidc.FormParameterSubjectToken: []string{"authelia_at_abc.123"}, oidc.FormParameterSubjectTokenType: []string{oidc.TokenTypeAccessToken}, oidc.FormParameterActorToken: []string{"authelia_at_def.456"}},
+			oidc.GrantTypeTokenExchange,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'actor_token' parameter is not supported as the OAuth 2.0 Client is always the actor.",
+		},
+		{
+			"ShouldRejectUnsupportedRequestedTokenType",
+			&oidc.RegisteredClient{ID: "backend", GrantTypes: []string{oidc.GrantTypeTokenExchange}},
+			url.Values{oidc.FormParameterSubjectToken: []string{"authelia_at_abc.123"}, oidc.FormParameterSubjectTokenType: []string{oidc.TokenTypeAccessToken}, oidc.FormParameterRequestedTokenType: []string{"urn:ietf:params:oauth:token-type:refresh_token"}},
+			oidc.GrantTypeTokenExchange,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'requested_token_type' parameter must be 'urn:ietf:params:oauth:token-type:access_token' when provided.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &oidc.TokenExchangeGrantHandler{}
+
+			requester := &oauthelia2.AccessRequest{
+				GrantTypes: oauthelia2.Arguments{tc.grant},
+				Request: oauthelia2.Request{
+					Client:  tc.client,
+					Form:    tc.form,
+					Session: oidc.NewSession(),
+				},
+			}
+
+			err := handler.HandleTokenEndpointRequest(context.Background(), requester)
+
+			assert.Equal(t, tc.expected, oauthelia2.ErrorToRFC6749Error(err).GetDescription())
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	oauthelia2 "authelia.com/provider/oauth2"
@@ -36,11 +37,12 @@ type OpenIDConnectProvider struct {
 // interfaces to the storage.Provider interface:
 // oauthelia2.Storage, oauthelia2.ClientManager, storage.Transactional, oauth2.AuthorizeCodeStorage, oauth2.AccessTokenStorage,
 // oauth2.RefreshTokenStorage, oauth2.TokenRevocationStorage, pkce.PKCERequestStorage,
-// openid.OpenIDConnectRequestStorage, and partially implements rfc7523.RFC7523KeyStorage.
+// openid.OpenIDConnectRequestStorage, rfc8628.RFC8628CoreStorage, and partially implements rfc7523.RFC7523KeyStorage.
 type Store struct {
 	ClientStore
 
 	provider storage.Provider
+	config   *schema.IdentityProvidersOpenIDConnect
 }
 
 // ClientStore is an abstraction used for the Store struct which stores clients.
@@ -52,6 +54,8 @@ type ClientStore interface {
 // MemoryClientStore is an implementation of the ClientStore which just stores the clients in memory.
 type MemoryClientStore struct {
 	clients map[string]Client
+
+	mu sync.RWMutex
 }
 
 // RegisteredClient represents a registered client.
@@ -64,6 +68,10 @@ type RegisteredClient struct {
 	Public               bool
 
 	RequirePushedAuthorizationRequests bool
+	DPoPBoundAccessTokens              bool
+
+	BackchannelTokenDeliveryMode          string
+	BackchannelClientNotificationEndpoint *url.URL
 
 	RequirePKCE                bool
 	RequirePKCEChallengeMethod bool
@@ -104,7 +112,8 @@ type RegisteredClient struct {
 	AllowMultipleAuthenticationMethods      bool
 	ClientCredentialsFlowAllowImplicitScope bool
 
-	AuthorizationPolicy ClientAuthorizationPolicy
+	AuthorizationPolicy     ClientAuthorizationPolicy
+	RequireUserVerification bool
 
 	ConsentPolicy         ClientConsentPolicy
 	RequestedAudienceMode ClientRequestedAudienceMode
@@ -140,6 +149,10 @@ type Client interface {
 	GetIntrospectionSignedResponseKeyID() (kid string)
 
 	GetRequirePushedAuthorizationRequests() (enforce bool)
+	GetDPoPBoundAccessTokens() (require bool)
+
+	GetBackchannelTokenDeliveryMode() (mode string)
+	GetBackchannelClientNotificationEndpoint() (endpoint string)
 
 	GetEnforcePKCE() (enforce bool)
 	GetEnforcePKCEChallengeMethod() (enforce bool)
@@ -152,6 +165,7 @@ type Client interface {
 	IsAuthenticationLevelSufficient(level authentication.Level, subject authorization.Subject) (sufficient bool)
 	GetAuthorizationPolicyRequiredLevel(subject authorization.Subject) (level authorization.Level)
 	GetAuthorizationPolicy() (policy ClientAuthorizationPolicy)
+	IsUserVerificationSufficient(amr AuthenticationMethodsReferences, subject authorization.Subject) (sufficient bool)
 
 	GetEffectiveLifespan(gt oauthelia2.GrantType, tt oauthelia2.TokenType, fallback time.Duration) (lifespan time.Duration)
 }
@@ -232,6 +246,13 @@ type ConsentGetResponseBody struct {
 	PreConfiguration  bool     `json:"pre_configuration"`
 }
 
+// DeviceAuthorizationPutRequestBody schema of the request body of the device authorization PUT endpoint which is used
+// by the user to approve or deny an OAuth 2.0 Device Authorization Grant.
+type DeviceAuthorizationPutRequestBody struct {
+	UserCode string `json:"user_code"`
+	Consent  bool   `json:"consent"`
+}
+
 // ConsentPostRequestBody schema of the request body of the consent POST endpoint.
 type ConsentPostRequestBody struct {
 	ConsentID    string `json:"id"`
@@ -553,6 +574,18 @@ type OAuth2PushedAuthorizationDiscoveryOptions struct {
 	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
 }
 
+// OAuth2DPoPDiscoveryOptions represents the well known discovery document specific to the
+// OAuth 2.0 Demonstrating Proof of Possession (RFC9449) implementation.
+//
+// OAuth 2.0 Demonstrating Proof of Possession: https://datatracker.ietf.org/doc/html/rfc9449#section-5.1
+type OAuth2DPoPDiscoveryOptions struct {
+	/*
+		A JSON array containing a list of the JWS alg values supported by the authorization server for DPoP proof
+		JWTs.
+	*/
+	DPoPSigningAlgValuesSupported []string `json:"dpop_signing_alg_values_supported,omitempty"`
+}
+
 // OpenIDConnectDiscoveryOptions represents the discovery options specific to OpenID Connect.
 type OpenIDConnectDiscoveryOptions struct {
 	/*
@@ -925,6 +958,7 @@ type OAuth2WellKnownConfiguration struct {
 	*OAuth2JWTIntrospectionResponseDiscoveryOptions
 	*OAuth2JWTSecuredAuthorizationRequestDiscoveryOptions
 	*OAuth2PushedAuthorizationDiscoveryOptions
+	*OAuth2DPoPDiscoveryOptions
 }
 
 type OAuth2WellKnownSignedConfiguration struct {
@@ -0,0 +1,17 @@
+package pki
+
+import (
+	"time"
+)
+
+const (
+	blockTypeCertificate        = "CERTIFICATE"
+	blockTypeCertificateRequest = "CERTIFICATE REQUEST"
+
+	// serialBits is the number of random bits in the serial number of the issued certificates.
+	serialBits = 128
+
+	// backdate is the duration the issued certificates are valid before the time they are issued at to tolerate clock
+	// skew between the devices and Authelia.
+	backdate = time.Minute
+)
@@ -0,0 +1,16 @@
+package pki
+
+import (
+	"errors"
+)
+
+var (
+	// ErrCertificateNotIssued is returned when a certificate was not issued by the certificate authority.
+	ErrCertificateNotIssued = errors.New("the certificate was not issued by the device certificate authority")
+
+	// ErrCertificateRevoked is returned when a certificate has been revoked.
+	ErrCertificateRevoked = errors.New("the certificate has been revoked")
+
+	// ErrCertificateUsername is returned when a certificate was issued to another user.
+	ErrCertificateUsername = errors.New("the certificate was issued to another user")
+)
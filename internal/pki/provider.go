@@ -0,0 +1,171 @@
+package pki
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// NewProvider creates a new Provider given the device certificates configuration. It returns nil if the device
+// certificates are not enabled.
+func NewProvider(config *schema.DeviceCertificates, store storage.DeviceCertificateProvider, clock clock.Provider) (provider *Provider, err error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	signer, ok := config.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("error creating the device certificate authority: the private key of type '%T' can't be used to sign certificates", config.PrivateKey)
+	}
+
+	certificates := config.CertificateChain.Certificates()
+
+	if len(certificates) == 0 {
+		return nil, errors.New("error creating the device certificate authority: the certificate chain is empty")
+	}
+
+	provider = &Provider{
+		config: config,
+		store:  store,
+		clock:  clock,
+		signer: signer,
+		ca:     certificates[0],
+		chain:  certificates,
+		roots:  x509.NewCertPool(),
+	}
+
+	provider.roots.AddCert(provider.ca)
+
+	return provider, nil
+}
+
+// Provider is the internal certificate authority which issues short-lived client certificates to managed devices and
+// verifies the client certificates presented by them.
+type Provider struct {
+	config *schema.DeviceCertificates
+	store  storage.DeviceCertificateProvider
+	clock  clock.Provider
+	signer crypto.Signer
+
+	ca    *x509.Certificate
+	chain []*x509.Certificate
+	roots *x509.CertPool
+}
+
+// GetHeader returns the name of the header the proxy forwards the client certificate in.
+func (p *Provider) GetHeader() string {
+	return p.config.Header
+}
+
+// Issue signs the certificate signing request and issues a client certificate to the named device of the user. The
+// returned bytes are the PEM encoded certificate followed by the certificate chain of the certificate authority.
+func (p *Provider) Issue(ctx context.Context, username, name string, request *x509.CertificateRequest) (certificate *model.DeviceCertificate, encoded []byte, err error) {
+	if err = request.CheckSignature(); err != nil {
+		return nil, nil, fmt.Errorf("error issuing device certificate: the certificate signing request has an invalid signature: %w", err)
+	}
+
+	var serial *big.Int
+
+	if serial, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits)); err != nil {
+		return nil, nil, fmt.Errorf("error issuing device certificate: error generating the serial number: %w", err)
+	}
+
+	now := p.clock.Now().UTC()
+
+	expires := now.Add(p.config.Lifespan)
+
+	if expires.After(p.ca.NotAfter) {
+		expires = p.ca.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: username},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              expires,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+
+	var der []byte
+
+	if der, err = x509.CreateCertificate(rand.Reader, template, p.ca, request.PublicKey, p.signer); err != nil {
+		return nil, nil, fmt.Errorf("error issuing device certificate: error signing the certificate: %w", err)
+	}
+
+	certificate = &model.DeviceCertificate{
+		Serial:    serial.Text(16),
+		Username:  username,
+		Name:      name,
+		IssuedAt:  now,
+		ExpiresAt: expires,
+	}
+
+	if err = p.store.SaveDeviceCertificate(ctx, *certificate); err != nil {
+		return nil, nil, fmt.Errorf("error issuing device certificate: %w", err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	_ = pem.Encode(buf, &pem.Block{Type: blockTypeCertificate, Bytes: der})
+
+	for _, cert := range p.chain {
+		_ = pem.Encode(buf, &pem.Block{Type: blockTypeCertificate, Bytes: cert.Raw})
+	}
+
+	return certificate, buf.Bytes(), nil
+}
+
+// Verify verifies the client certificate was issued by the certificate authority, has not expired, and has not been
+// revoked. If the username is not empty the certificate must also have been issued to that user.
+func (p *Provider) Verify(ctx context.Context, cert *x509.Certificate, username string) (certificate *model.DeviceCertificate, err error) {
+	now := p.clock.Now()
+
+	if _, err = cert.Verify(x509.VerifyOptions{Roots: p.roots, CurrentTime: now, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		return nil, errors.Join(ErrCertificateNotIssued, err)
+	}
+
+	if certificate, err = p.store.LoadDeviceCertificate(ctx, cert.SerialNumber.Text(16)); err != nil {
+		if errors.Is(err, storage.ErrNoDeviceCertificate) {
+			return nil, ErrCertificateNotIssued
+		}
+
+		return nil, err
+	}
+
+	switch {
+	case certificate.IsRevoked():
+		return nil, ErrCertificateRevoked
+	case certificate.Username != cert.Subject.CommonName:
+		return nil, ErrCertificateNotIssued
+	case username != "" && certificate.Username != username:
+		return nil, ErrCertificateUsername
+	}
+
+	return certificate, nil
+}
+
+// VerifyHeader parses the value of the header the proxy forwards the client certificate in and verifies it as
+// described by Verify.
+func (p *Provider) VerifyHeader(ctx context.Context, value []byte, username string) (certificate *model.DeviceCertificate, err error) {
+	var cert *x509.Certificate
+
+	if cert, err = ParseCertificateHeader(value); err != nil {
+		return nil, err
+	}
+
+	return p.Verify(ctx, cert, username)
+}
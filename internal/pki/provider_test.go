@@ -0,0 +1,217 @@
+package pki
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"database/sql"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(nil, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewProvider(&schema.DeviceCertificates{}, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	config := newTestConfig(t)
+
+	provider, err = NewProvider(config, nil, clock.NewFixed(time.Unix(1700000000, 0)))
+
+	assert.NoError(t, err)
+	require.NotNil(t, provider)
+	assert.Equal(t, "X-Forwarded-Tls-Client-Cert", provider.GetHeader())
+
+	config.CertificateChain = schema.X509CertificateChain{}
+
+	provider, err = NewProvider(config, nil, nil)
+
+	assert.EqualError(t, err, "error creating the device certificate authority: the certificate chain is empty")
+	assert.Nil(t, provider)
+}
+
+func TestProviderShouldIssueAndVerify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMockStorage(ctrl)
+
+	now := time.Unix(1700000000, 0)
+
+	provider, err := NewProvider(newTestConfig(t), store, clock.NewFixed(now))
+	require.NoError(t, err)
+
+	var saved model.DeviceCertificate
+
+	store.EXPECT().SaveDeviceCertificate(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, certificate model.DeviceCertificate) error {
+		saved = certificate
+
+		return nil
+	})
+
+	certificate, encoded, err := provider.Issue(context.Background(), "john", "laptop", newTestCertificateRequest(t))
+
+	require.NoError(t, err)
+	require.NotNil(t, certificate)
+	assert.Equal(t, saved, *certificate)
+	assert.Equal(t, "john", certificate.Username)
+	assert.Equal(t, "laptop", certificate.Name)
+	assert.Equal(t, now.UTC(), certificate.IssuedAt)
+	assert.Equal(t, now.UTC().Add(time.Hour*24), certificate.ExpiresAt)
+
+	block, rest := pem.Decode(encoded)
+	require.NotNil(t, block)
+
+	ca, _ := pem.Decode(rest)
+	require.NotNil(t, ca)
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	assert.Equal(t, "john", cert.Subject.CommonName)
+	assert.Equal(t, certificate.Serial, cert.SerialNumber.Text(16))
+	assert.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+	assert.False(t, cert.IsCA)
+	assert.Equal(t, provider.ca.Raw, ca.Bytes)
+
+	store.EXPECT().LoadDeviceCertificate(gomock.Any(), certificate.Serial).Return(certificate, nil).Times(3)
+
+	loaded, err := provider.VerifyHeader(context.Background(), []byte(url.QueryEscape(string(pem.EncodeToMemory(block)))), "john")
+
+	assert.NoError(t, err)
+	assert.Equal(t, certificate, loaded)
+
+	loaded, err = provider.VerifyHeader(context.Background(), []byte(base64.StdEncoding.EncodeToString(cert.Raw)), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, certificate, loaded)
+
+	loaded, err = provider.Verify(context.Background(), cert, "harry")
+
+	assert.ErrorIs(t, err, ErrCertificateUsername)
+	assert.Nil(t, loaded)
+
+	store.EXPECT().LoadDeviceCertificate(gomock.Any(), certificate.Serial).Return(&model.DeviceCertificate{Serial: certificate.Serial, Username: "john", RevokedAt: sql.NullTime{Time: now, Valid: true}}, nil)
+
+	loaded, err = provider.Verify(context.Background(), cert, "john")
+
+	assert.ErrorIs(t, err, ErrCertificateRevoked)
+	assert.Nil(t, loaded)
+
+	store.EXPECT().LoadDeviceCertificate(gomock.Any(), certificate.Serial).Return(nil, storage.ErrNoDeviceCertificate)
+
+	loaded, err = provider.Verify(context.Background(), cert, "john")
+
+	assert.ErrorIs(t, err, ErrCertificateNotIssued)
+	assert.Nil(t, loaded)
+
+	store.EXPECT().LoadDeviceCertificate(gomock.Any(), certificate.Serial).Return(nil, errors.New("bad connection"))
+
+	loaded, err = provider.Verify(context.Background(), cert, "john")
+
+	assert.EqualError(t, err, "bad connection")
+	assert.Nil(t, loaded)
+
+	expired, err := NewProvider(provider.config, store, clock.NewFixed(now.Add(time.Hour*25)))
+	require.NoError(t, err)
+
+	loaded, err = expired.Verify(context.Background(), cert, "john")
+
+	assert.ErrorIs(t, err, ErrCertificateNotIssued)
+	assert.Nil(t, loaded)
+}
+
+func TestProviderShouldNotVerifyForeignCertificates(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	provider, err := NewProvider(newTestConfig(t), nil, clock.NewFixed(now))
+	require.NoError(t, err)
+
+	foreign := newTestConfig(t)
+
+	cert := foreign.CertificateChain.Leaf()
+
+	loaded, err := provider.Verify(context.Background(), cert, "john")
+
+	assert.ErrorIs(t, err, ErrCertificateNotIssued)
+	assert.Nil(t, loaded)
+}
+
+func TestProviderShouldNotIssueInvalidRequest(t *testing.T) {
+	provider, err := NewProvider(newTestConfig(t), nil, clock.NewFixed(time.Unix(1700000000, 0)))
+	require.NoError(t, err)
+
+	request := newTestCertificateRequest(t)
+	request.Signature = []byte("invalid")
+
+	certificate, encoded, err := provider.Issue(context.Background(), "john", "laptop", request)
+
+	assert.ErrorContains(t, err, "error issuing device certificate: the certificate signing request has an invalid signature")
+	assert.Nil(t, certificate)
+	assert.Nil(t, encoded)
+}
+
+func newTestConfig(t *testing.T) *schema.DeviceCertificates {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Authelia Device CA"},
+		NotBefore:             time.Unix(1600000000, 0),
+		NotAfter:              time.Unix(1800000000, 0),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+
+	chain, err := schema.NewX509CertificateChain(string(pem.EncodeToMemory(&pem.Block{Type: blockTypeCertificate, Bytes: der})))
+	require.NoError(t, err)
+
+	return &schema.DeviceCertificates{
+		Enabled:          true,
+		CertificateChain: *chain,
+		PrivateKey:       key,
+		Lifespan:         time.Hour * 24,
+		Header:           "X-Forwarded-Tls-Client-Cert",
+	}
+}
+
+func newTestCertificateRequest(t *testing.T) *x509.CertificateRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "laptop"}}, key)
+	require.NoError(t, err)
+
+	request, err := x509.ParseCertificateRequest(der)
+	require.NoError(t, err)
+
+	return request
+}
@@ -0,0 +1,73 @@
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseCertificateHeader parses the client certificate forwarded by a proxy in a header. The value may be a PEM
+// encoded certificate or the base64 encoded DER of the certificate, either of which may also be URL encoded. If the
+// value contains a comma separated list of certificates only the first one is parsed.
+func ParseCertificateHeader(value []byte) (cert *x509.Certificate, err error) {
+	raw := string(value)
+
+	if unescaped, err := url.PathUnescape(raw); err == nil {
+		raw = unescaped
+	}
+
+	raw = strings.TrimSpace(raw)
+
+	if len(raw) == 0 {
+		return nil, errors.New("error parsing client certificate: the value is empty")
+	}
+
+	if strings.HasPrefix(raw, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(raw))
+
+		if block == nil || block.Type != blockTypeCertificate {
+			return nil, errors.New("error parsing client certificate: the value is not a PEM encoded certificate")
+		}
+
+		if cert, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, fmt.Errorf("error parsing client certificate: %w", err)
+		}
+
+		return cert, nil
+	}
+
+	if i := strings.IndexByte(raw, ','); i != -1 {
+		raw = raw[:i]
+	}
+
+	var der []byte
+
+	if der, err = base64.StdEncoding.DecodeString(raw); err != nil {
+		return nil, fmt.Errorf("error parsing client certificate: %w", err)
+	}
+
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		return nil, fmt.Errorf("error parsing client certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+// ParseCertificateRequest parses a PEM encoded certificate signing request.
+func ParseCertificateRequest(value []byte) (request *x509.CertificateRequest, err error) {
+	block, _ := pem.Decode(value)
+
+	if block == nil || block.Type != blockTypeCertificateRequest {
+		return nil, errors.New("error parsing certificate signing request: the value is not a PEM encoded certificate signing request")
+	}
+
+	if request, err = x509.ParseCertificateRequest(block.Bytes); err != nil {
+		return nil, fmt.Errorf("error parsing certificate signing request: %w", err)
+	}
+
+	return request, nil
+}
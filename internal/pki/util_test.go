@@ -0,0 +1,63 @@
+package pki
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCertificateHeader(t *testing.T) {
+	config := newTestConfig(t)
+
+	cert := config.CertificateChain.Leaf()
+
+	encoded := base64.StdEncoding.EncodeToString(cert.Raw)
+
+	testCases := []struct {
+		name string
+		have string
+		err  string
+	}{
+		{"ShouldParsePEM", string(pem.EncodeToMemory(&pem.Block{Type: blockTypeCertificate, Bytes: cert.Raw})), ""},
+		{"ShouldParseURLEncodedPEM", url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: blockTypeCertificate, Bytes: cert.Raw}))), ""},
+		{"ShouldParseDER", encoded, ""},
+		{"ShouldParseFirstDER", encoded + "," + encoded, ""},
+		{"ShouldErrorEmpty", "", "error parsing client certificate: the value is empty"},
+		{"ShouldErrorBadPEM", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: cert.Raw})), "error parsing client certificate: the value is not a PEM encoded certificate"},
+		{"ShouldErrorBadBase64", "!!!", "error parsing client certificate: illegal base64 data at input byte 0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseCertificateHeader([]byte(tc.have))
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				require.NotNil(t, actual)
+				assert.Equal(t, cert.Raw, actual.Raw)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func TestParseCertificateRequest(t *testing.T) {
+	request := newTestCertificateRequest(t)
+
+	actual, err := ParseCertificateRequest(pem.EncodeToMemory(&pem.Block{Type: blockTypeCertificateRequest, Bytes: request.Raw}))
+
+	assert.NoError(t, err)
+	require.NotNil(t, actual)
+	assert.Equal(t, request.Raw, actual.Raw)
+
+	actual, err = ParseCertificateRequest([]byte("not pem"))
+
+	assert.EqualError(t, err, "error parsing certificate signing request: the value is not a PEM encoded certificate signing request")
+	assert.Nil(t, actual)
+}
@@ -0,0 +1,51 @@
+package push
+
+import (
+	"context"
+
+	"github.com/authelia/authelia/v4/internal/breaker"
+)
+
+// NewCircuitBreakerProvider returns a Provider which calls the provider unless the circuit breaker is open in which
+// case the calls fail fast. The provider is returned as is when the circuit breaker is nil.
+func NewCircuitBreakerProvider(provider Provider, b *breaker.Breaker) Provider {
+	if b == nil {
+		return provider
+	}
+
+	return &CircuitBreakerProvider{provider: provider, breaker: b}
+}
+
+// CircuitBreakerProvider is a Provider which guards the calls to another Provider with a circuit breaker.
+type CircuitBreakerProvider struct {
+	provider Provider
+	breaker  *breaker.Breaker
+}
+
+// StartBackchannelAuthentication performs the StartBackchannelAuthentication of the provider.
+func (p *CircuitBreakerProvider) StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error) {
+	err = p.breaker.Do(ctx, func(ctx context.Context) (err error) {
+		txid, err = p.provider.StartBackchannelAuthentication(ctx, username, remoteIP, message)
+
+		return err
+	})
+
+	return txid, err
+}
+
+// GetBackchannelAuthenticationStatus performs the GetBackchannelAuthenticationStatus of the provider.
+func (p *CircuitBreakerProvider) GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error) {
+	err = p.breaker.Do(ctx, func(ctx context.Context) (err error) {
+		status, err = p.provider.GetBackchannelAuthenticationStatus(ctx, txid)
+
+		return err
+	})
+
+	return status, err
+}
+
+// HealthCheck performs the HealthCheck of the provider which is not guarded by the circuit breaker so the health of
+// the provider is always reported accurately.
+func (p *CircuitBreakerProvider) HealthCheck(ctx context.Context) (err error) {
+	return p.provider.HealthCheck(ctx)
+}
@@ -0,0 +1,175 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/random"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// Deliverer delivers the push challenges created by Authelia to the devices of a user. Unlike the other push
+// notification providers the challenges are stored and responded to with Authelia, as such a Deliverer is only
+// responsible for delivering the challenges to the devices of the user.
+type Deliverer interface {
+	Deliver(ctx context.Context, challenge *Challenge) (err error)
+	HealthCheck(ctx context.Context) (err error)
+}
+
+// Challenge is a push challenge delivered to the devices of a user by a Deliverer.
+type Challenge struct {
+	ID        uuid.UUID
+	Token     string
+	Username  string
+	RemoteIP  string
+	Message   string
+	ExpiresAt time.Time
+
+	// RootURL is the root URL of Authelia used to respond to the push challenge, it's nil when the push challenge was
+	// not created with a request to Authelia.
+	RootURL *url.URL
+}
+
+// URL returns the URL of the page the user can approve or deny the push challenge on, or nil if the root URL is
+// not known.
+func (c *Challenge) URL() *url.URL {
+	if c.RootURL == nil {
+		return nil
+	}
+
+	uri := *c.RootURL
+
+	uri.Path = path.Join(uri.Path, pathChallengePage)
+	uri.RawQuery = url.Values{"id": []string{c.ID.String()}, "token": []string{c.Token}}.Encode()
+
+	return &uri
+}
+
+// ResponseURL returns the URL of the endpoint which approves or denies the push challenge, or nil if the root URL is
+// not known.
+func (c *Challenge) ResponseURL() *url.URL {
+	if c.RootURL == nil {
+		return nil
+	}
+
+	uri := *c.RootURL
+
+	uri.Path = path.Join(uri.Path, pathChallengeResponse)
+
+	return &uri
+}
+
+// NewChallengeProvider creates a push notification provider which stores the push challenges with Authelia and
+// delivers them with the Deliverer. The push challenges expire after the timeout.
+func NewChallengeProvider(deliverer Deliverer, store storage.Provider, timeout time.Duration) *ChallengeProvider {
+	return &ChallengeProvider{
+		deliverer: deliverer,
+		store:     store,
+		clock:     clock.New(),
+		random:    &random.Cryptographical{},
+		timeout:   timeout,
+	}
+}
+
+// ChallengeProvider is a push notification provider backed by a Deliverer. Each push creates a push challenge which
+// is stored with Authelia and delivered to the devices of the user by the Deliverer, and the push is approved once
+// the user approves the push challenge with Authelia.
+type ChallengeProvider struct {
+	deliverer Deliverer
+	store     storage.Provider
+	clock     clock.Provider
+	random    random.Provider
+
+	timeout time.Duration
+}
+
+// StartBackchannelAuthentication creates a push challenge for all of the devices of the user and returns the
+// identifier of the push challenge as the transaction identifier. The push challenge can only be responded to with
+// the portal when the context knows the root URL of Authelia i.e. it's the context of a request to Authelia.
+func (p *ChallengeProvider) StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error) {
+	var root *url.URL
+
+	if rctx, ok := ctx.(interface{ RootURL() *url.URL }); ok {
+		root = rctx.RootURL()
+	}
+
+	var token string
+
+	if token, err = p.random.StringCustomErr(challengeTokenLength, random.CharSetAlphaNumeric); err != nil {
+		return "", fmt.Errorf("error generating the push challenge token: %w", err)
+	}
+
+	c := model.NewPushChallenge(username, "", message, token, model.NewNullIPFromString(remoteIP), p.clock.Now(), p.timeout)
+
+	if err = p.store.SavePushChallenge(ctx, c); err != nil {
+		return "", err
+	}
+
+	challenge := &Challenge{
+		ID:        c.ChallengeID,
+		Token:     token,
+		Username:  username,
+		RemoteIP:  remoteIP,
+		Message:   message,
+		ExpiresAt: c.ExpiresAt,
+		RootURL:   root,
+	}
+
+	if err = p.deliverer.Deliver(ctx, challenge); err != nil {
+		return "", fmt.Errorf("error delivering the push challenge with id '%s': %w", c.ChallengeID, err)
+	}
+
+	return c.ChallengeID.String(), nil
+}
+
+// GetBackchannelAuthenticationStatus returns the status of a push challenge created with
+// StartBackchannelAuthentication. A push challenge which has expired is considered denied.
+func (p *ChallengeProvider) GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error) {
+	var id uuid.UUID
+
+	if id, err = uuid.Parse(txid); err != nil {
+		return model.OAuth2CIBAStatusPending, fmt.Errorf("error parsing the push challenge id: %w", err)
+	}
+
+	var challenge *model.PushChallenge
+
+	if challenge, err = p.store.LoadPushChallenge(ctx, id); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	switch {
+	case challenge.Status == model.PushChallengeStatusApproved:
+		return model.OAuth2CIBAStatusApproved, nil
+	case challenge.Status == model.PushChallengeStatusDenied, challenge.IsExpired(p.clock.Now()):
+		return model.OAuth2CIBAStatusDenied, nil
+	default:
+		return model.OAuth2CIBAStatusPending, nil
+	}
+}
+
+// HealthCheck performs the health check of the Deliverer.
+func (p *ChallengeProvider) HealthCheck(ctx context.Context) (err error) {
+	return p.deliverer.HealthCheck(ctx)
+}
+
+// challengeText returns the text of the notification which delivers the push challenge.
+func challengeText(challenge *Challenge) string {
+	text := challenge.Message
+
+	if text == "" {
+		text = "Approve the sign in request"
+	}
+
+	if challenge.RemoteIP != "" {
+		return fmt.Sprintf("%s from %s.", text, challenge.RemoteIP)
+	}
+
+	return text + "."
+}
@@ -0,0 +1,195 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type testPushStorage struct {
+	storage.Provider
+
+	challenges map[uuid.UUID]*model.PushChallenge
+	devices    []model.MobileDevice
+	err        error
+}
+
+func (s *testPushStorage) SavePushChallenge(_ context.Context, challenge model.PushChallenge) (err error) {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.challenges[challenge.ChallengeID] = &challenge
+
+	return nil
+}
+
+func (s *testPushStorage) LoadPushChallenge(_ context.Context, challengeID uuid.UUID) (challenge *model.PushChallenge, err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	c, ok := s.challenges[challengeID]
+	if !ok {
+		return nil, storage.ErrNoPushChallenge
+	}
+
+	value := *c
+
+	return &value, nil
+}
+
+func (s *testPushStorage) LoadMobileDevicesPush(_ context.Context, username string, _ time.Time) (devices []model.MobileDevice, err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	for _, device := range s.devices {
+		if device.Username == username {
+			devices = append(devices, device)
+		}
+	}
+
+	return devices, nil
+}
+
+type testDeliverer struct {
+	delivered []*Challenge
+	err       error
+}
+
+func (p *testDeliverer) Deliver(_ context.Context, challenge *Challenge) (err error) {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.delivered = append(p.delivered, challenge)
+
+	return nil
+}
+
+func (p *testDeliverer) HealthCheck(_ context.Context) (err error) {
+	return p.err
+}
+
+func newTestChallengeProvider(now time.Time) (provider *ChallengeProvider, deliverer *testDeliverer, store *testPushStorage) {
+	deliverer = &testDeliverer{}
+	store = &testPushStorage{challenges: map[uuid.UUID]*model.PushChallenge{}}
+
+	provider = NewChallengeProvider(deliverer, store, time.Millisecond*200)
+	provider.clock = clock.NewFixed(now)
+
+	return provider, deliverer, store
+}
+
+func TestChallengeURL(t *testing.T) {
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	challenge := &Challenge{ID: id, Token: "abc123"}
+
+	assert.Nil(t, challenge.URL())
+	assert.Nil(t, challenge.ResponseURL())
+
+	challenge.RootURL = &url.URL{Scheme: "https", Host: "auth.example.com", Path: "/authelia"}
+
+	assert.Equal(t, "https://auth.example.com/authelia/push/challenge?id=c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e&token=abc123", challenge.URL().String())
+	assert.Equal(t, "https://auth.example.com/authelia/api/secondfactor/push/challenge", challenge.ResponseURL().String())
+	assert.Equal(t, "/authelia", challenge.RootURL.Path)
+}
+
+func TestChallengeProviderStartBackchannelAuthentication(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	provider, deliverer, store := newTestChallengeProvider(now)
+
+	txid, err := provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "Sign in to example.com")
+	require.NoError(t, err)
+
+	id, err := uuid.Parse(txid)
+	require.NoError(t, err)
+
+	require.Contains(t, store.challenges, id)
+	require.Len(t, deliverer.delivered, 1)
+
+	stored := store.challenges[id]
+
+	assert.Equal(t, "john", stored.Username)
+	assert.Equal(t, "", stored.Device)
+	assert.Equal(t, "Sign in to example.com", stored.Message)
+	assert.Equal(t, "127.0.0.1", stored.RequestIP.IP.String())
+	assert.Equal(t, now.Add(time.Millisecond*200), stored.ExpiresAt)
+
+	delivered := deliverer.delivered[0]
+
+	assert.Equal(t, id, delivered.ID)
+	assert.Equal(t, stored.Token, delivered.Token)
+	assert.Len(t, delivered.Token, challengeTokenLength)
+	assert.Equal(t, "john", delivered.Username)
+	assert.Equal(t, "127.0.0.1", delivered.RemoteIP)
+	assert.Equal(t, stored.ExpiresAt, delivered.ExpiresAt)
+	assert.Nil(t, delivered.RootURL)
+
+	deliverer.err = errors.New("connection refused")
+
+	txid, err = provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "")
+	assert.Regexp(t, `^error delivering the push challenge with id '[0-9a-f-]{36}': connection refused$`, err.Error())
+	assert.Equal(t, "", txid)
+
+	deliverer.err = nil
+	store.err = errors.New("bad connection")
+
+	txid, err = provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "")
+	assert.EqualError(t, err, "bad connection")
+	assert.Equal(t, "", txid)
+}
+
+func TestChallengeProviderGetBackchannelAuthenticationStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	testCases := []struct {
+		name      string
+		challenge *model.PushChallenge
+		txid      string
+		expected  int
+		err       string
+	}{
+		{"ShouldBePending", &model.PushChallenge{ChallengeID: id, ExpiresAt: now.Add(time.Minute)}, id.String(), model.OAuth2CIBAStatusPending, ""},
+		{"ShouldBeApproved", &model.PushChallenge{ChallengeID: id, ExpiresAt: now.Add(time.Minute), Status: model.PushChallengeStatusApproved}, id.String(), model.OAuth2CIBAStatusApproved, ""},
+		{"ShouldBeDenied", &model.PushChallenge{ChallengeID: id, ExpiresAt: now.Add(time.Minute), Status: model.PushChallengeStatusDenied}, id.String(), model.OAuth2CIBAStatusDenied, ""},
+		{"ShouldBeDeniedWhenExpired", &model.PushChallenge{ChallengeID: id, ExpiresAt: now}, id.String(), model.OAuth2CIBAStatusDenied, ""},
+		{"ShouldBeApprovedWhenApprovedBeforeExpiry", &model.PushChallenge{ChallengeID: id, ExpiresAt: now, Status: model.PushChallengeStatusApproved}, id.String(), model.OAuth2CIBAStatusApproved, ""},
+		{"ShouldFailUnknown", nil, id.String(), model.OAuth2CIBAStatusPending, "no push challenge found"},
+		{"ShouldFailInvalidID", nil, "abc", model.OAuth2CIBAStatusPending, "error parsing the push challenge id: invalid UUID length: 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, _, store := newTestChallengeProvider(now)
+
+			if tc.challenge != nil {
+				store.challenges[tc.challenge.ChallengeID] = tc.challenge
+			}
+
+			status, err := provider.GetBackchannelAuthenticationStatus(context.Background(), tc.txid)
+
+			assert.Equal(t, tc.expected, status)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
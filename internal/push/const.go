@@ -0,0 +1,47 @@
+package push
+
+const (
+	pathPrivacyIDEATokens           = "/token/"
+	pathPrivacyIDEATriggerChallenge = "/validate/triggerchallenge"
+	pathPrivacyIDEAPollTransaction  = "/validate/polltransaction"
+	pathPrivacyIDEACheck            = "/validate/check"
+
+	pathWebhookAuth       = "/auth"
+	pathWebhookAuthStatus = "/auth/status"
+	pathWebhookHealth     = "/health"
+
+	pathNtfyHealth     = "/v1/health"
+	pathGotifyMessage  = "/message"
+	pathGotifyHealth   = "/health"
+	pathMobileHealth   = "/health"
+	pathMobileDelivery = "/push"
+
+	pathChallengePage     = "/push/challenge"
+	pathChallengeResponse = "/api/secondfactor/push/challenge"
+
+	privacyIDEATokenTypePush        = "push"
+	privacyIDEAAuthenticationAccept = "ACCEPT"
+
+	challengeTokenLength = 32
+
+	ntfyTopicUsername = "{username}"
+	ntfyPriorityHigh  = 4
+
+	gotifyPriorityHigh = 8
+)
+
+// Webhook Auth Results.
+const (
+	allow   = "allow"
+	deny    = "deny"
+	waiting = "waiting"
+)
+
+const (
+	headerAccept        = "Accept"
+	headerContentType   = "Content-Type"
+	headerAuthorization = "Authorization"
+
+	contentTypeApplicationJSON = "application/json"
+	contentTypeApplicationForm = "application/x-www-form-urlencoded"
+)
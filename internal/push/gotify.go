@@ -0,0 +1,67 @@
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewGotify creates a Deliverer which publishes the push challenges to the Gotify application of each user.
+func NewGotify(config *schema.PushGotify, trusted *x509.CertPool) *Gotify {
+	return &Gotify{
+		client: newHTTPClient(config.TLS, trusted),
+		url:    config.URL,
+		tokens: config.Tokens,
+	}
+}
+
+// Gotify is a Deliverer backed by a Gotify server. Each push challenge is published with the application token
+// of the user as a message which opens the page that approves or denies the push challenge with Authelia. The push
+// challenges of the users without an application token can't be delivered.
+type Gotify struct {
+	client *http.Client
+	url    *url.URL
+	tokens map[string]string
+}
+
+// Deliver publishes the push challenge with the application token of the user.
+func (g *Gotify) Deliver(ctx context.Context, challenge *Challenge) (err error) {
+	token, ok := g.tokens[challenge.Username]
+	if !ok {
+		return fmt.Errorf("the user '%s' does not have a gotify application token", challenge.Username)
+	}
+
+	request := GotifyMessageRequest{
+		Title:    "Authelia",
+		Message:  challengeText(challenge),
+		Priority: gotifyPriorityHigh,
+	}
+
+	if uri := challenge.URL(); uri != nil {
+		request.Message = fmt.Sprintf("%s Open %s to approve or deny it.", request.Message, uri.String())
+		request.Extras = map[string]any{
+			"client::notification": map[string]any{
+				"click": map[string]string{"url": uri.String()},
+			},
+		}
+	}
+
+	if err = doJSONRequest(ctx, g.client, http.MethodPost, endpoint(g.url, pathGotifyMessage, nil), "Bearer "+token, request, nil); err != nil {
+		return fmt.Errorf("gotify %s request failed: %w", pathGotifyMessage, err)
+	}
+
+	return nil
+}
+
+// HealthCheck performs a request to the health endpoint of the Gotify server.
+func (g *Gotify) HealthCheck(ctx context.Context) (err error) {
+	if err = doJSON(ctx, g.client, http.MethodGet, endpoint(g.url, pathGotifyHealth, nil), "", "", nil, nil); err != nil {
+		return fmt.Errorf("gotify %s request failed: %w", pathGotifyHealth, err)
+	}
+
+	return nil
+}
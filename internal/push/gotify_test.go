@@ -0,0 +1,79 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestGotify(t *testing.T, handler http.HandlerFunc) *Gotify {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL + "/gotify")
+	require.NoError(t, err)
+
+	return NewGotify(&schema.PushGotify{URL: uri, Tokens: map[string]string{"john": "AbCdEf"}}, nil)
+}
+
+func TestGotifyDeliver(t *testing.T) {
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	var request map[string]any
+
+	provider := newTestGotify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/gotify/message", r.URL.Path)
+		assert.Equal(t, "Bearer AbCdEf", r.Header.Get(headerAuthorization))
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		_, _ = w.Write([]byte(`{"id":1}`))
+	})
+
+	challenge := &Challenge{
+		ID:       id,
+		Token:    "abc123",
+		Username: "john",
+		Message:  "Sign in to https://app.example.com",
+		RootURL:  &url.URL{Scheme: "https", Host: "auth.example.com"},
+	}
+
+	require.NoError(t, provider.Deliver(context.Background(), challenge))
+
+	page := "https://auth.example.com/push/challenge?id=c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e&token=abc123"
+
+	assert.Equal(t, "Sign in to https://app.example.com. Open "+page+" to approve or deny it.", request["message"])
+	assert.Equal(t, float64(gotifyPriorityHigh), request["priority"])
+	assert.Equal(t, map[string]any{"client::notification": map[string]any{"click": map[string]any{"url": page}}}, request["extras"])
+
+	err := provider.Deliver(context.Background(), &Challenge{Username: "harry"})
+	assert.EqualError(t, err, "the user 'harry' does not have a gotify application token")
+}
+
+func TestGotifyHealthCheck(t *testing.T) {
+	status := http.StatusOK
+
+	provider := newTestGotify(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gotify/health", r.URL.Path)
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"health":"green","database":"green"}`))
+	})
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	status = http.StatusInternalServerError
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "gotify /health request failed: the server responded with status code 500")
+}
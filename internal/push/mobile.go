@@ -0,0 +1,96 @@
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// NewMobile creates a Deliverer which delivers the push challenges to the mobile devices of a user with a push
+// relay.
+func NewMobile(config *schema.PushMobile, trusted *x509.CertPool, store storage.Provider) *Mobile {
+	mobile := &Mobile{
+		client: newHTTPClient(config.TLS, trusted),
+		url:    config.URL,
+		store:  store,
+		clock:  clock.New(),
+	}
+
+	if config.Token != "" {
+		mobile.authorization = "Bearer " + config.Token
+	}
+
+	return mobile
+}
+
+// Mobile is a Deliverer backed by the mobile devices enrolled for push notifications with the mobile
+// authentication endpoints. The push challenges are sent to a push relay which delivers them to the devices with the
+// push provider each device was enrolled with, and the devices respond to them with the mobile authentication
+// endpoints.
+type Mobile struct {
+	client        *http.Client
+	url           *url.URL
+	authorization string
+
+	store storage.Provider
+	clock clock.Provider
+}
+
+// Deliver sends the push challenge to the push relay for all of the mobile devices of the user.
+func (m *Mobile) Deliver(ctx context.Context, challenge *Challenge) (err error) {
+	var mobiles []model.MobileDevice
+
+	if mobiles, err = m.store.LoadMobileDevicesPush(ctx, challenge.Username, m.clock.Now()); err != nil {
+		return err
+	}
+
+	request := MobilePushRequest{
+		ChallengeID: challenge.ID.String(),
+		Token:       challenge.Token,
+		Username:    challenge.Username,
+		RemoteIP:    challenge.RemoteIP,
+		Message:     challenge.Message,
+		ExpiresAt:   challenge.ExpiresAt.Unix(),
+	}
+
+	for _, mobile := range mobiles {
+		request.Devices = append(request.Devices, MobilePushDevice{
+			ID:       mobile.PublicID.String(),
+			Platform: mobile.Platform,
+			Provider: mobile.PushProvider.String,
+			Token:    mobile.PushToken.String,
+		})
+	}
+
+	if len(request.Devices) == 0 {
+		return fmt.Errorf("the user '%s' has no mobile devices enrolled for push notifications", challenge.Username)
+	}
+
+	return m.call(ctx, http.MethodPost, pathMobileDelivery, request)
+}
+
+// HealthCheck performs a request to the health endpoint of the push relay.
+func (m *Mobile) HealthCheck(ctx context.Context) (err error) {
+	return m.call(ctx, http.MethodGet, pathMobileHealth, nil)
+}
+
+func (m *Mobile) call(ctx context.Context, method, path string, request any) (err error) {
+	if request == nil {
+		err = doJSON(ctx, m.client, method, endpoint(m.url, path, nil), m.authorization, "", nil, nil)
+	} else {
+		err = doJSONRequest(ctx, m.client, method, endpoint(m.url, path, nil), m.authorization, request, nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("mobile push relay %s request failed: %w", path, err)
+	}
+
+	return nil
+}
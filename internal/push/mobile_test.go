@@ -0,0 +1,103 @@
+package push
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func newTestMobile(t *testing.T, handler http.HandlerFunc) (provider *Mobile, store *testPushStorage) {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL + "/relay")
+	require.NoError(t, err)
+
+	store = &testPushStorage{devices: []model.MobileDevice{
+		{PublicID: uuid.MustParse("5f9d5a1e-8c1b-4f7e-9a3d-2b6c4e8f0a17"), Username: "john", Name: "Pixel", Platform: "android", PushProvider: sql.NullString{String: "fcm", Valid: true}, PushToken: sql.NullString{String: "fcm-token", Valid: true}},
+		{PublicID: uuid.MustParse("b8a3b1ff-9b0f-4d52-8c8b-2d0b1e6a7c11"), Username: "john", Name: "iPhone", Platform: "ios", PushProvider: sql.NullString{String: "apns", Valid: true}, PushToken: sql.NullString{String: "apns-token", Valid: true}},
+	}}
+
+	return NewMobile(&schema.PushMobile{URL: uri, Token: "abc123"}, nil, store), store
+}
+
+func TestMobileDeliver(t *testing.T) {
+	var request MobilePushRequest
+
+	provider, store := newTestMobile(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/relay/push", r.URL.Path)
+		assert.Equal(t, "Bearer abc123", r.Header.Get(headerAuthorization))
+
+		request = MobilePushRequest{}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	challenge := &Challenge{
+		ID:        uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e"),
+		Token:     "xyz",
+		Username:  "john",
+		RemoteIP:  "127.0.0.1",
+		Message:   "Sign in to https://app.example.com",
+		ExpiresAt: time.Unix(1700000000, 0),
+	}
+
+	require.NoError(t, provider.Deliver(context.Background(), challenge))
+
+	assert.Equal(t, MobilePushRequest{
+		ChallengeID: "c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e",
+		Token:       "xyz",
+		Username:    "john",
+		RemoteIP:    "127.0.0.1",
+		Message:     "Sign in to https://app.example.com",
+		ExpiresAt:   1700000000,
+		Devices: []MobilePushDevice{
+			{ID: "5f9d5a1e-8c1b-4f7e-9a3d-2b6c4e8f0a17", Platform: "android", Provider: "fcm", Token: "fcm-token"},
+			{ID: "b8a3b1ff-9b0f-4d52-8c8b-2d0b1e6a7c11", Platform: "ios", Provider: "apns", Token: "apns-token"},
+		},
+	}, request)
+
+	challenge.Username = "harry"
+
+	err := provider.Deliver(context.Background(), challenge)
+	assert.EqualError(t, err, "the user 'harry' has no mobile devices enrolled for push notifications")
+
+	store.err = errors.New("bad connection")
+
+	err = provider.Deliver(context.Background(), challenge)
+	assert.EqualError(t, err, "bad connection")
+}
+
+func TestMobileHealthCheck(t *testing.T) {
+	status := http.StatusOK
+
+	provider, _ := newTestMobile(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/relay/health", r.URL.Path)
+
+		w.WriteHeader(status)
+	})
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	status = http.StatusServiceUnavailable
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "mobile push relay /health request failed: the server responded with status code 503")
+}
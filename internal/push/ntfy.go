@@ -0,0 +1,95 @@
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewNtfy creates a Deliverer which publishes the push challenges to a ntfy topic.
+func NewNtfy(config *schema.PushNtfy, trusted *x509.CertPool) *Ntfy {
+	ntfy := &Ntfy{
+		client: newHTTPClient(config.TLS, trusted),
+		url:    config.URL,
+		topic:  config.Topic,
+	}
+
+	if config.Token != "" {
+		ntfy.authorization = "Bearer " + config.Token
+	}
+
+	return ntfy
+}
+
+// Ntfy is a Deliverer backed by a ntfy server. Each push challenge is published to the topic of the user as a
+// notification with actions which approve or deny the push challenge with Authelia, as such the topic should only be
+// readable by the user.
+type Ntfy struct {
+	client        *http.Client
+	url           *url.URL
+	topic         string
+	authorization string
+}
+
+// Deliver publishes the push challenge to the topic of the user.
+func (n *Ntfy) Deliver(ctx context.Context, challenge *Challenge) (err error) {
+	request := NtfyPublishRequest{
+		Topic:    strings.ReplaceAll(n.topic, ntfyTopicUsername, challenge.Username),
+		Title:    "Authelia",
+		Message:  challengeText(challenge),
+		Priority: ntfyPriorityHigh,
+		Tags:     []string{"lock"},
+	}
+
+	if uri := challenge.URL(); uri != nil {
+		request.Click = uri.String()
+	}
+
+	if uri := challenge.ResponseURL(); uri != nil {
+		request.Actions = []NtfyAction{
+			ntfyResponseAction("Approve", uri, challenge, true),
+			ntfyResponseAction("Deny", uri, challenge, false),
+		}
+	}
+
+	if err = doJSONRequest(ctx, n.client, http.MethodPost, n.url.String(), n.authorization, request, nil); err != nil {
+		return fmt.Errorf("ntfy publish request failed: %w", err)
+	}
+
+	return nil
+}
+
+// HealthCheck performs a request to the health endpoint of the ntfy server.
+func (n *Ntfy) HealthCheck(ctx context.Context) (err error) {
+	response := struct {
+		Healthy bool `json:"healthy"`
+	}{}
+
+	if err = doJSON(ctx, n.client, http.MethodGet, endpoint(n.url, pathNtfyHealth, nil), n.authorization, "", nil, &response); err != nil {
+		return fmt.Errorf("ntfy %s request failed: %w", pathNtfyHealth, err)
+	}
+
+	if !response.Healthy {
+		return errors.New("ntfy reported the server is not healthy")
+	}
+
+	return nil
+}
+
+func ntfyResponseAction(label string, uri *url.URL, challenge *Challenge, approve bool) NtfyAction {
+	return NtfyAction{
+		Action:  "http",
+		Label:   label,
+		URL:     uri.String(),
+		Method:  http.MethodPost,
+		Headers: map[string]string{headerContentType: contentTypeApplicationJSON},
+		Body:    fmt.Sprintf(`{"id":%q,"token":%q,"approve":%t}`, challenge.ID.String(), challenge.Token, approve),
+		Clear:   true,
+	}
+}
@@ -0,0 +1,113 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestNtfy(t *testing.T, handler http.HandlerFunc) *Ntfy {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return NewNtfy(&schema.PushNtfy{URL: uri, Topic: "authelia-{username}", Token: "tk_abc123"}, nil)
+}
+
+func TestNtfyDeliver(t *testing.T) {
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	var request NtfyPublishRequest
+
+	provider := newTestNtfy(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/", r.URL.Path)
+		assert.Equal(t, "Bearer tk_abc123", r.Header.Get(headerAuthorization))
+
+		request = NtfyPublishRequest{}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		_, _ = w.Write([]byte(`{"id":"abc","topic":"authelia-john"}`))
+	})
+
+	challenge := &Challenge{
+		ID:        id,
+		Token:     "abc123",
+		Username:  "john",
+		RemoteIP:  "127.0.0.1",
+		Message:   "Sign in to https://app.example.com",
+		ExpiresAt: time.Unix(1700000000, 0),
+		RootURL:   &url.URL{Scheme: "https", Host: "auth.example.com"},
+	}
+
+	require.NoError(t, provider.Deliver(context.Background(), challenge))
+
+	assert.Equal(t, "authelia-john", request.Topic)
+	assert.Equal(t, "Sign in to https://app.example.com from 127.0.0.1.", request.Message)
+	assert.Equal(t, ntfyPriorityHigh, request.Priority)
+	assert.Equal(t, "https://auth.example.com/push/challenge?id=c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e&token=abc123", request.Click)
+
+	require.Len(t, request.Actions, 2)
+
+	assert.Equal(t, "Approve", request.Actions[0].Label)
+	assert.Equal(t, "http", request.Actions[0].Action)
+	assert.Equal(t, "https://auth.example.com/api/secondfactor/push/challenge", request.Actions[0].URL)
+	assert.Equal(t, http.MethodPost, request.Actions[0].Method)
+	assert.Equal(t, `{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":true}`, request.Actions[0].Body)
+	assert.Equal(t, "Deny", request.Actions[1].Label)
+	assert.Equal(t, `{"id":"c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e","token":"abc123","approve":false}`, request.Actions[1].Body)
+
+	challenge.RootURL = nil
+	challenge.Message = ""
+	challenge.RemoteIP = ""
+
+	require.NoError(t, provider.Deliver(context.Background(), challenge))
+
+	assert.Equal(t, "Approve the sign in request.", request.Message)
+	assert.Equal(t, "", request.Click)
+	assert.Len(t, request.Actions, 0)
+}
+
+func TestNtfyDeliverShouldFail(t *testing.T) {
+	provider := newTestNtfy(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	err := provider.Deliver(context.Background(), &Challenge{Username: "john"})
+	assert.EqualError(t, err, "ntfy publish request failed: the server responded with status code 403")
+}
+
+func TestNtfyHealthCheck(t *testing.T) {
+	healthy := true
+
+	provider := newTestNtfy(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, pathNtfyHealth, r.URL.Path)
+
+		if healthy {
+			_, _ = w.Write([]byte(`{"healthy":true}`))
+		} else {
+			_, _ = w.Write([]byte(`{"healthy":false}`))
+		}
+	})
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+
+	healthy = false
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "ntfy reported the server is not healthy")
+}
@@ -0,0 +1,191 @@
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewPrivacyIDEA creates a push notification provider which approves the second factor with the push tokens of a
+// privacyIDEA server.
+func NewPrivacyIDEA(config *schema.PushPrivacyIDEA, trusted *x509.CertPool) *PrivacyIDEA {
+	return &PrivacyIDEA{
+		client: newHTTPClient(config.TLS, trusted),
+		url:    config.URL,
+		realm:  config.Realm,
+		token:  config.Token,
+	}
+}
+
+// PrivacyIDEA is a push notification provider backed by the push tokens of a privacyIDEA server. A push triggers a
+// challenge for all of the push tokens of the user, and is approved once the challenge is answered and accepted.
+type PrivacyIDEA struct {
+	client *http.Client
+	url    *url.URL
+	realm  string
+	token  string
+}
+
+// StartBackchannelAuthentication triggers a push challenge for all of the push tokens of the user and returns the
+// transaction identifier.
+func (p *PrivacyIDEA) StartBackchannelAuthentication(ctx context.Context, username, _, _ string) (txid string, err error) {
+	var id string
+
+	if id, err = p.triggerChallenge(ctx, username); err != nil {
+		return "", err
+	}
+
+	// The username is required to finalize the transaction so it's retained with the transaction identifier.
+	return url.Values{"user": []string{username}, "transaction_id": []string{id}}.Encode(), nil
+}
+
+// GetBackchannelAuthenticationStatus returns the status of a push sent with StartBackchannelAuthentication. The
+// privacyIDEA API does not report declined challenges so they remain pending until they expire.
+func (p *PrivacyIDEA) GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error) {
+	var username, id string
+
+	if username, id, err = parsePrivacyIDEATransaction(txid); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	var answered bool
+
+	if answered, err = p.pollTransaction(ctx, id); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	if !answered {
+		return model.OAuth2CIBAStatusPending, nil
+	}
+
+	var accepted bool
+
+	if accepted, err = p.check(ctx, username, id); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	if accepted {
+		return model.OAuth2CIBAStatusApproved, nil
+	}
+
+	return model.OAuth2CIBAStatusDenied, nil
+}
+
+// HealthCheck performs a token list request which verifies the URL and the token.
+func (p *PrivacyIDEA) HealthCheck(ctx context.Context) (err error) {
+	return p.call(ctx, http.MethodGet, pathPrivacyIDEATokens, url.Values{"pagesize": []string{"1"}}, true, &PrivacyIDEAResponse{})
+}
+
+func (p *PrivacyIDEA) triggerChallenge(ctx context.Context, username string) (id string, err error) {
+	form := url.Values{"user": []string{username}, "type": []string{privacyIDEATokenTypePush}}
+
+	if p.realm != "" {
+		form.Set("realm", p.realm)
+	}
+
+	var (
+		result PrivacyIDEAResponse
+		detail PrivacyIDEAChallengeDetail
+	)
+
+	if err = p.call(ctx, http.MethodPost, pathPrivacyIDEATriggerChallenge, form, true, &result); err != nil {
+		return "", err
+	}
+
+	if len(result.Detail) != 0 {
+		if err = json.Unmarshal(result.Detail, &detail); err != nil {
+			return "", fmt.Errorf("error decoding the privacyidea challenge: %w", err)
+		}
+	}
+
+	if detail.TransactionID == "" {
+		return "", fmt.Errorf("privacyidea did not trigger a push challenge for user '%s'", username)
+	}
+
+	return detail.TransactionID, nil
+}
+
+func (p *PrivacyIDEA) pollTransaction(ctx context.Context, id string) (answered bool, err error) {
+	var result PrivacyIDEAResponse
+
+	if err = p.call(ctx, http.MethodGet, pathPrivacyIDEAPollTransaction, url.Values{"transaction_id": []string{id}}, false, &result); err != nil {
+		return false, err
+	}
+
+	return isPrivacyIDEAValueTrue(result.Result.Value), nil
+}
+
+func (p *PrivacyIDEA) check(ctx context.Context, username, id string) (accepted bool, err error) {
+	form := url.Values{"user": []string{username}, "pass": []string{""}, "transaction_id": []string{id}}
+
+	if p.realm != "" {
+		form.Set("realm", p.realm)
+	}
+
+	var result PrivacyIDEAResponse
+
+	if err = p.call(ctx, http.MethodPost, pathPrivacyIDEACheck, form, false, &result); err != nil {
+		return false, err
+	}
+
+	return isPrivacyIDEAValueTrue(result.Result.Value) && (result.Result.Authentication == "" || result.Result.Authentication == privacyIDEAAuthenticationAccept), nil
+}
+
+func (p *PrivacyIDEA) call(ctx context.Context, method, path string, values url.Values, authorize bool, response *PrivacyIDEAResponse) (err error) {
+	var (
+		uri, authorization, contentType string
+		body                            io.Reader
+	)
+
+	if method == http.MethodGet {
+		uri = endpoint(p.url, path, values)
+	} else {
+		uri, contentType, body = endpoint(p.url, path, nil), contentTypeApplicationForm, strings.NewReader(values.Encode())
+	}
+
+	if authorize {
+		authorization = p.token
+	}
+
+	err = doJSON(ctx, p.client, method, uri, authorization, contentType, body, response)
+
+	switch {
+	case response.Result.Error != nil:
+		return fmt.Errorf("privacyidea %s request failed: %s, error code %d", path, response.Result.Error.Message, response.Result.Error.Code)
+	case err != nil:
+		return fmt.Errorf("privacyidea %s request failed: %w", path, err)
+	case !response.Result.Status:
+		return fmt.Errorf("privacyidea %s request failed: the result has a false status", path)
+	}
+
+	return nil
+}
+
+func parsePrivacyIDEATransaction(txid string) (username, id string, err error) {
+	var values url.Values
+
+	if values, err = url.ParseQuery(txid); err != nil {
+		return "", "", fmt.Errorf("error parsing the privacyidea transaction: %w", err)
+	}
+
+	if username, id = values.Get("user"), values.Get("transaction_id"); username == "" || id == "" {
+		return "", "", errors.New("error parsing the privacyidea transaction: the user or transaction id is absent")
+	}
+
+	return username, id, nil
+}
+
+func isPrivacyIDEAValueTrue(value json.RawMessage) bool {
+	var v bool
+
+	return json.Unmarshal(value, &v) == nil && v
+}
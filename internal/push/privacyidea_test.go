@@ -0,0 +1,118 @@
+package push
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func newTestPrivacyIDEA(t *testing.T, handler http.HandlerFunc) *PrivacyIDEA {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL + "/pi")
+	require.NoError(t, err)
+
+	return NewPrivacyIDEA(&schema.PushPrivacyIDEA{URL: uri, Realm: "example", Token: "admin-token"}, nil)
+}
+
+func TestPrivacyIDEABackchannelAuthentication(t *testing.T) {
+	testCases := []struct {
+		name     string
+		answered bool
+		check    string
+		expected int
+	}{
+		{"ShouldBePending", false, "", model.OAuth2CIBAStatusPending},
+		{"ShouldApprove", true, `{"result":{"status":true,"value":true,"authentication":"ACCEPT"}}`, model.OAuth2CIBAStatusApproved},
+		{"ShouldDenyRejected", true, `{"result":{"status":true,"value":false,"authentication":"REJECT"}}`, model.OAuth2CIBAStatusDenied},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var checks atomic.Int32
+
+			provider := newTestPrivacyIDEA(t, func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/pi/validate/triggerchallenge":
+					require.NoError(t, r.ParseForm())
+					assert.Equal(t, "admin-token", r.Header.Get(headerAuthorization))
+					assert.Equal(t, "john", r.PostForm.Get("user"))
+					assert.Equal(t, "example", r.PostForm.Get("realm"))
+					assert.Equal(t, "push", r.PostForm.Get("type"))
+
+					_, _ = w.Write([]byte(`{"result":{"status":true,"value":1},"detail":{"transaction_id":"0123456789"}}`))
+				case "/pi/validate/polltransaction":
+					assert.Equal(t, "", r.Header.Get(headerAuthorization))
+					assert.Equal(t, "0123456789", r.URL.Query().Get("transaction_id"))
+
+					if tc.answered {
+						_, _ = w.Write([]byte(`{"result":{"status":true,"value":true}}`))
+					} else {
+						_, _ = w.Write([]byte(`{"result":{"status":true,"value":false}}`))
+					}
+				case "/pi/validate/check":
+					require.NoError(t, r.ParseForm())
+					assert.Equal(t, "", r.Header.Get(headerAuthorization))
+					assert.Equal(t, "john", r.PostForm.Get("user"))
+					assert.Equal(t, "0123456789", r.PostForm.Get("transaction_id"))
+
+					checks.Add(1)
+
+					_, _ = w.Write([]byte(tc.check))
+				default:
+					t.Errorf("unexpected request to %s", r.URL.Path)
+				}
+			})
+
+			txid, err := provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "Login to Example")
+			require.NoError(t, err)
+
+			status, err := provider.GetBackchannelAuthenticationStatus(context.Background(), txid)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, status)
+
+			if tc.answered {
+				assert.Equal(t, int32(1), checks.Load())
+			} else {
+				assert.Equal(t, int32(0), checks.Load())
+			}
+		})
+	}
+}
+
+func TestPrivacyIDEABackchannelAuthenticationErrors(t *testing.T) {
+	provider := newTestPrivacyIDEA(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pi/validate/triggerchallenge":
+			_, _ = w.Write([]byte(`{"result":{"status":true,"value":0},"detail":{"messages":["No token found"]}}`))
+		case "/pi/token/":
+			_, _ = w.Write([]byte(`{"result":{"status":false,"error":{"code":4033,"message":"Authentication failure. Wrong credentials"}}}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+
+	_, err := provider.StartBackchannelAuthentication(context.Background(), "jane", "127.0.0.1", "")
+	assert.EqualError(t, err, "privacyidea did not trigger a push challenge for user 'jane'")
+
+	status, err := provider.GetBackchannelAuthenticationStatus(context.Background(), "0123456789")
+	assert.EqualError(t, err, "error parsing the privacyidea transaction: the user or transaction id is absent")
+	assert.Equal(t, model.OAuth2CIBAStatusPending, status)
+
+	status, err = provider.GetBackchannelAuthenticationStatus(context.Background(), "transaction_id=0123456789&user=john")
+	assert.EqualError(t, err, "privacyidea /validate/polltransaction request failed: the server responded with status code 502")
+	assert.Equal(t, model.OAuth2CIBAStatusPending, status)
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "privacyidea /token/ request failed: Authentication failure. Wrong credentials, error code 4033")
+}
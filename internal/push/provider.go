@@ -0,0 +1,144 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewProvider creates the push notification provider configured by the provider option of the configuration. The
+// push challenges of the providers which deliver the push challenges created by Authelia are stored with the storage
+// provider.
+func NewProvider(config *schema.Push, trusted *x509.CertPool, store storage.Provider) Provider {
+	switch config.Provider {
+	case schema.PushProviderPrivacyIDEA:
+		return NewPrivacyIDEA(config.PrivacyIDEA, trusted)
+	case schema.PushProviderWebhook:
+		return NewWebhook(config.Webhook, trusted)
+	case schema.PushProviderMobile:
+		return NewChallengeProvider(NewMobile(config.Mobile, trusted, store), store, config.Timeout)
+	case schema.PushProviderNtfy:
+		return NewChallengeProvider(NewNtfy(config.Ntfy, trusted), store, config.Timeout)
+	case schema.PushProviderGotify:
+		return NewChallengeProvider(NewGotify(config.Gotify, trusted), store, config.Timeout)
+	default:
+		return nil
+	}
+}
+
+func newHTTPClient(config *schema.TLS, trusted *x509.CertPool) *http.Client {
+	transport := &http.Transport{}
+
+	if config != nil {
+		transport.TLSClientConfig = utils.NewTLSConfig(config, trusted)
+	}
+
+	// The individual requests are bounded by the context of the caller, this is only a safety net.
+	return &http.Client{Timeout: time.Second * 30, Transport: transport}
+}
+
+// endpoint returns the string form of the base URL with the path joined to it. The trailing slash of the path is
+// retained as some APIs treat it as significant.
+func endpoint(base *url.URL, p string, query url.Values) string {
+	uri := *base
+
+	uri.Path = path.Join(uri.Path, p)
+
+	if strings.HasSuffix(p, "/") {
+		uri.Path += "/"
+	}
+
+	if query != nil {
+		uri.RawQuery = query.Encode()
+	}
+
+	return uri.String()
+}
+
+// doJSON performs a request and decodes the JSON response body into v.
+func doJSON(ctx context.Context, client *http.Client, method, uri, authorization, contentType string, body io.Reader, v any) (err error) {
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, method, uri, body); err != nil {
+		return fmt.Errorf("error creating the request: %w", err)
+	}
+
+	req.Header.Set(headerAccept, contentTypeApplicationJSON)
+
+	if contentType != "" {
+		req.Header.Set(headerContentType, contentType)
+	}
+
+	if authorization != "" {
+		req.Header.Set(headerAuthorization, authorization)
+	}
+
+	var resp *http.Response
+
+	if resp, err = client.Do(req); err != nil {
+		return fmt.Errorf("error performing the request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	var data []byte
+
+	if data, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err != nil {
+		return fmt.Errorf("error reading the response: %w", err)
+	}
+
+	if v != nil && len(data) != 0 {
+		if err = json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("error decoding the response with status code %d: %w", resp.StatusCode, err)
+		}
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &statusError{code: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// doJSONRequest performs a request with the request encoded as the JSON request body and decodes the JSON response
+// body into v.
+func doJSONRequest(ctx context.Context, client *http.Client, method, uri, authorization string, request, v any) (err error) {
+	var data []byte
+
+	if data, err = json.Marshal(request); err != nil {
+		return fmt.Errorf("error encoding the request: %w", err)
+	}
+
+	return doJSON(ctx, client, method, uri, authorization, contentTypeApplicationJSON, bytes.NewReader(data), v)
+}
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("the server responded with status code %d", e.code)
+}
+
+var (
+	_ Provider = (*PrivacyIDEA)(nil)
+	_ Provider = (*Webhook)(nil)
+	_ Provider = (*ChallengeProvider)(nil)
+	_ Provider = (*CircuitBreakerProvider)(nil)
+
+	_ Deliverer = (*Mobile)(nil)
+	_ Deliverer = (*Ntfy)(nil)
+	_ Deliverer = (*Gotify)(nil)
+)
@@ -0,0 +1,108 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Provider is a push notification provider which the user approves or denies the second factor and the OpenID
+// Connect 1.0 Client Initiated Backchannel Authentication requests with.
+type Provider interface {
+	StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error)
+	GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error)
+	HealthCheck(ctx context.Context) (err error)
+}
+
+// PrivacyIDEAResponse is a response from the privacyIDEA API.
+type PrivacyIDEAResponse struct {
+	Result PrivacyIDEAResult `json:"result"`
+	Detail json.RawMessage   `json:"detail"`
+}
+
+// PrivacyIDEAResult is the result of a response from the privacyIDEA API.
+type PrivacyIDEAResult struct {
+	Status         bool              `json:"status"`
+	Value          json.RawMessage   `json:"value"`
+	Authentication string            `json:"authentication"`
+	Error          *PrivacyIDEAError `json:"error"`
+}
+
+// PrivacyIDEAError is the error of a failed response from the privacyIDEA API.
+type PrivacyIDEAError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// PrivacyIDEAChallengeDetail is the detail of a trigger challenge response from the privacyIDEA API.
+type PrivacyIDEAChallengeDetail struct {
+	TransactionID string `json:"transaction_id"`
+}
+
+// WebhookAuthRequest is the body of the authorization request sent to the webhook.
+type WebhookAuthRequest struct {
+	Username string            `json:"username"`
+	RemoteIP string            `json:"remote_ip,omitempty"`
+	Info     map[string]string `json:"info,omitempty"`
+}
+
+// WebhookAuthResponse is the response of the webhook to an authorization request.
+type WebhookAuthResponse struct {
+	TransactionID string `json:"txid"`
+}
+
+// WebhookAuthStatusResponse is the response of the webhook to an authorization status request.
+type WebhookAuthStatusResponse struct {
+	Result        string `json:"result"`
+	Status        string `json:"status"`
+	StatusMessage string `json:"status_msg"`
+}
+
+// MobilePushRequest is the body of the request sent to the push relay which delivers a push challenge to the mobile
+// devices of a user.
+type MobilePushRequest struct {
+	ChallengeID string             `json:"challenge_id"`
+	Token       string             `json:"token"`
+	Username    string             `json:"username"`
+	RemoteIP    string             `json:"remote_ip,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	ExpiresAt   int64              `json:"expires_at"`
+	Devices     []MobilePushDevice `json:"devices"`
+}
+
+// MobilePushDevice is a mobile device of a MobilePushRequest.
+type MobilePushDevice struct {
+	ID       string `json:"id"`
+	Platform string `json:"platform"`
+	Provider string `json:"provider"`
+	Token    string `json:"token"`
+}
+
+// NtfyPublishRequest is the body of the JSON publish request sent to a ntfy server.
+type NtfyPublishRequest struct {
+	Topic    string       `json:"topic"`
+	Title    string       `json:"title,omitempty"`
+	Message  string       `json:"message"`
+	Priority int          `json:"priority,omitempty"`
+	Tags     []string     `json:"tags,omitempty"`
+	Click    string       `json:"click,omitempty"`
+	Actions  []NtfyAction `json:"actions,omitempty"`
+}
+
+// NtfyAction is an action button of a NtfyPublishRequest.
+type NtfyAction struct {
+	Action  string            `json:"action"`
+	Label   string            `json:"label"`
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Clear   bool              `json:"clear,omitempty"`
+}
+
+// GotifyMessageRequest is the body of the create message request sent to a Gotify server.
+type GotifyMessageRequest struct {
+	Title    string         `json:"title,omitempty"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
@@ -0,0 +1,100 @@
+package push
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// NewWebhook creates a push notification provider which approves the second factor with a webhook.
+func NewWebhook(config *schema.PushWebhook, trusted *x509.CertPool) *Webhook {
+	webhook := &Webhook{
+		client: newHTTPClient(config.TLS, trusted),
+		url:    config.URL,
+	}
+
+	if config.Token != "" {
+		webhook.authorization = "Bearer " + config.Token
+	}
+
+	return webhook
+}
+
+// Webhook is a push notification provider backed by a webhook. The webhook creates an approval request for a user,
+// and reports the result of the approval request once the user has responded to it.
+type Webhook struct {
+	client        *http.Client
+	url           *url.URL
+	authorization string
+}
+
+// StartBackchannelAuthentication creates an approval request and returns the transaction identifier.
+func (w *Webhook) StartBackchannelAuthentication(ctx context.Context, username, remoteIP, message string) (txid string, err error) {
+	request := WebhookAuthRequest{
+		Username: username,
+		RemoteIP: remoteIP,
+	}
+
+	if message != "" {
+		request.Info = map[string]string{"Message": message}
+	}
+
+	var response WebhookAuthResponse
+
+	if err = w.call(ctx, http.MethodPost, pathWebhookAuth, nil, request, &response); err != nil {
+		return "", err
+	}
+
+	if response.TransactionID == "" {
+		return "", fmt.Errorf("webhook %s request did not return a transaction id", pathWebhookAuth)
+	}
+
+	return response.TransactionID, nil
+}
+
+// GetBackchannelAuthenticationStatus returns the status of an approval request created with
+// StartBackchannelAuthentication.
+func (w *Webhook) GetBackchannelAuthenticationStatus(ctx context.Context, txid string) (status int, err error) {
+	var response WebhookAuthStatusResponse
+
+	if err = w.call(ctx, http.MethodGet, pathWebhookAuthStatus, url.Values{"txid": []string{txid}}, nil, &response); err != nil {
+		return model.OAuth2CIBAStatusPending, err
+	}
+
+	switch response.Result {
+	case allow:
+		return model.OAuth2CIBAStatusApproved, nil
+	case deny:
+		return model.OAuth2CIBAStatusDenied, nil
+	case waiting:
+		return model.OAuth2CIBAStatusPending, nil
+	default:
+		return model.OAuth2CIBAStatusPending, fmt.Errorf("webhook %s request returned the unknown result '%s'", pathWebhookAuthStatus, response.Result)
+	}
+}
+
+// HealthCheck performs a request to the health endpoint of the webhook.
+func (w *Webhook) HealthCheck(ctx context.Context) (err error) {
+	return w.call(ctx, http.MethodGet, pathWebhookHealth, nil, nil, nil)
+}
+
+func (w *Webhook) call(ctx context.Context, method, path string, query url.Values, request, response any) (err error) {
+	uri := endpoint(w.url, path, query)
+
+	if request == nil {
+		err = doJSON(ctx, w.client, method, uri, w.authorization, "", nil, response)
+	} else {
+		err = doJSONRequest(ctx, w.client, method, uri, w.authorization, request, response)
+	}
+
+	if err != nil {
+		return fmt.Errorf("webhook %s request failed: %w", path, err)
+	}
+
+	return nil
+}
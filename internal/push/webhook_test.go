@@ -0,0 +1,99 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func newTestWebhook(t *testing.T, handler http.HandlerFunc) *Webhook {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL + "/push")
+	require.NoError(t, err)
+
+	return NewWebhook(&schema.PushWebhook{URL: uri, Token: "abc123"}, nil)
+}
+
+func TestWebhookBackchannelAuthentication(t *testing.T) {
+	var result atomic.Value
+
+	result.Store(waiting)
+
+	provider := newTestWebhook(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer abc123", r.Header.Get(headerAuthorization))
+
+		switch r.URL.Path {
+		case "/push/auth":
+			request := WebhookAuthRequest{}
+
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, WebhookAuthRequest{Username: "john", RemoteIP: "127.0.0.1", Info: map[string]string{"Message": "Login to Example"}}, request)
+
+			_, _ = w.Write([]byte(`{"txid":"abc"}`))
+		case "/push/auth/status":
+			assert.Equal(t, "abc", r.URL.Query().Get("txid"))
+
+			_ = json.NewEncoder(w).Encode(WebhookAuthStatusResponse{Result: result.Load().(string)})
+		case "/push/health":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	txid, err := provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "Login to Example")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", txid)
+
+	for _, tc := range []struct {
+		result   string
+		expected int
+		err      string
+	}{
+		{waiting, model.OAuth2CIBAStatusPending, ""},
+		{allow, model.OAuth2CIBAStatusApproved, ""},
+		{deny, model.OAuth2CIBAStatusDenied, ""},
+		{"abc", model.OAuth2CIBAStatusPending, "webhook /auth/status request returned the unknown result 'abc'"},
+	} {
+		result.Store(tc.result)
+
+		status, err := provider.GetBackchannelAuthenticationStatus(context.Background(), txid)
+
+		if tc.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, tc.err)
+		}
+
+		assert.Equal(t, tc.expected, status)
+	}
+
+	assert.NoError(t, provider.HealthCheck(context.Background()))
+}
+
+func TestWebhookErrors(t *testing.T) {
+	provider := newTestWebhook(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/push/auth":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+
+	_, err := provider.StartBackchannelAuthentication(context.Background(), "john", "127.0.0.1", "")
+	assert.EqualError(t, err, "webhook /auth request did not return a transaction id")
+
+	assert.EqualError(t, provider.HealthCheck(context.Background()), "webhook /health request failed: the server responded with status code 503")
+}
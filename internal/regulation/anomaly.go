@@ -0,0 +1,245 @@
+package regulation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewAnomalyDetector returns a new AnomalyDetector which detects the attack patterns within the configured window.
+func NewAnomalyDetector(config schema.RegulationAnomalies, clock clock.Provider) *AnomalyDetector {
+	return &AnomalyDetector{
+		config:    config,
+		clock:     clock,
+		ips:       map[string]*anomalySet{},
+		usernames: map[string]*anomalySet{},
+	}
+}
+
+// AnomalyDetector keeps the recent first factor attempts in memory to derive the statistics which indicate an attack,
+// such as a single IP failing to sign in as many distinct users or a single user failing to sign in from many distinct
+// IPs. Unlike the bans these patterns are not specific to any one user, so they are only exported as metrics for
+// alerting rather than acted upon.
+type AnomalyDetector struct {
+	config schema.RegulationAnomalies
+	clock  clock.Provider
+
+	ips       map[string]*anomalySet
+	usernames map[string]*anomalySet
+	buckets   [anomalyBuckets]anomalyBucket
+	pruned    time.Time
+
+	mu sync.Mutex
+}
+
+// SetConfig replaces the configuration used for all subsequent attempts.
+func (d *AnomalyDetector) SetConfig(config schema.RegulationAnomalies) {
+	d.mu.Lock()
+
+	defer d.mu.Unlock()
+
+	d.config = config
+}
+
+// Observe an attempt returning the patterns which were detected as a result of the attempt. A pattern is only returned
+// once until the number of distinct usernames or IPs drops below the threshold again.
+func (d *AnomalyDetector) Observe(ip, username string, successful bool) (patterns []string) {
+	d.mu.Lock()
+
+	defer d.mu.Unlock()
+
+	if !d.enabled() {
+		return nil
+	}
+
+	now := d.clock.Now()
+
+	d.prune(now, false)
+
+	bucket := d.bucket(now)
+
+	bucket.attempts++
+
+	if successful {
+		return nil
+	}
+
+	bucket.failures++
+
+	if d.observe(d.ips, ip, username, now, d.config.UsernamesPerIP) {
+		patterns = append(patterns, AnomalyPatternSpray)
+	}
+
+	if d.observe(d.usernames, username, ip, now, d.config.IPsPerUsername) {
+		patterns = append(patterns, AnomalyPatternDistributed)
+	}
+
+	return patterns
+}
+
+// Statistics returns the Anomalies derived from the attempts within the window.
+func (d *AnomalyDetector) Statistics() (anomalies Anomalies) {
+	d.mu.Lock()
+
+	defer d.mu.Unlock()
+
+	if !d.enabled() {
+		return anomalies
+	}
+
+	now := d.clock.Now()
+
+	d.prune(now, true)
+
+	start := now.Add(-d.config.Window).Unix() / d.bucketSeconds()
+
+	for _, bucket := range d.buckets {
+		if bucket.index > start {
+			anomalies.Attempts += bucket.attempts
+			anomalies.Failures += bucket.failures
+		}
+	}
+
+	for _, set := range d.ips {
+		anomalies.UsernamesPerIPMax = max(anomalies.UsernamesPerIPMax, len(set.members))
+
+		if len(set.members) >= d.config.UsernamesPerIP {
+			anomalies.SpraySources++
+		}
+	}
+
+	for _, set := range d.usernames {
+		anomalies.IPsPerUsernameMax = max(anomalies.IPsPerUsernameMax, len(set.members))
+
+		if len(set.members) >= d.config.IPsPerUsername {
+			anomalies.DistributedTargets++
+		}
+	}
+
+	return anomalies
+}
+
+func (d *AnomalyDetector) enabled() bool {
+	return d.config.Window > 0 && d.config.UsernamesPerIP > 0 && d.config.IPsPerUsername > 0
+}
+
+// observe adds the member to the set of the key returning true if the set has reached the threshold for the first time.
+func (d *AnomalyDetector) observe(sets map[string]*anomalySet, key, member string, now time.Time, threshold int) bool {
+	set, ok := sets[key]
+
+	if !ok {
+		// The number of tracked keys is limited so an attack using a large number of IPs or usernames does not result in
+		// unbounded memory usage.
+		if len(sets) >= anomalyMaxKeys {
+			return false
+		}
+
+		set = &anomalySet{members: map[string]time.Time{}}
+
+		sets[key] = set
+	}
+
+	if _, ok = set.members[member]; ok || len(set.members) < anomalyMaxMembers {
+		set.members[member] = now
+	}
+
+	if set.detected || len(set.members) < threshold {
+		return false
+	}
+
+	set.detected = true
+
+	return true
+}
+
+// prune removes the members which were last seen before the window. Unless forced the members are only pruned once per
+// bucket duration as it's not necessary to be exact when observing the attempts.
+func (d *AnomalyDetector) prune(now time.Time, force bool) {
+	if !force && now.Sub(d.pruned) < d.config.Window/anomalyBuckets {
+		return
+	}
+
+	d.pruned = now
+
+	cutoff := now.Add(-d.config.Window)
+
+	prune := func(sets map[string]*anomalySet, threshold int) {
+		for key, set := range sets {
+			for member, seen := range set.members {
+				if !seen.After(cutoff) {
+					delete(set.members, member)
+				}
+			}
+
+			switch n := len(set.members); {
+			case n == 0:
+				delete(sets, key)
+			case n < threshold:
+				set.detected = false
+			}
+		}
+	}
+
+	prune(d.ips, d.config.UsernamesPerIP)
+	prune(d.usernames, d.config.IPsPerUsername)
+}
+
+func (d *AnomalyDetector) bucket(now time.Time) *anomalyBucket {
+	index := now.Unix() / d.bucketSeconds()
+
+	bucket := &d.buckets[index%anomalyBuckets]
+
+	if bucket.index != index {
+		*bucket = anomalyBucket{index: index}
+	}
+
+	return bucket
+}
+
+func (d *AnomalyDetector) bucketSeconds() int64 {
+	return max(int64(d.config.Window/time.Second)/anomalyBuckets, 1)
+}
+
+type anomalySet struct {
+	members  map[string]time.Time
+	detected bool
+}
+
+type anomalyBucket struct {
+	index    int64
+	attempts int
+	failures int
+}
+
+// Anomalies represents the statistics derived from the recent first factor attempts.
+type Anomalies struct {
+	// Attempts is the number of attempts within the window.
+	Attempts int
+
+	// Failures is the number of failed attempts within the window.
+	Failures int
+
+	// UsernamesPerIPMax is the highest number of distinct usernames which failed from a single IP.
+	UsernamesPerIPMax int
+
+	// IPsPerUsernameMax is the highest number of distinct IPs a single username failed from.
+	IPsPerUsernameMax int
+
+	// SpraySources is the number of IPs which have failed for at least the configured number of distinct usernames.
+	SpraySources int
+
+	// DistributedTargets is the number of usernames which have failed from at least the configured number of distinct
+	// IPs.
+	DistributedTargets int
+}
+
+// FailureRatio returns the ratio of the failed attempts to all of the attempts within the window.
+func (a Anomalies) FailureRatio() float64 {
+	if a.Attempts == 0 {
+		return 0
+	}
+
+	return float64(a.Failures) / float64(a.Attempts)
+}
@@ -0,0 +1,89 @@
+package regulation_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func TestAnomalyDetectorShouldDetectSpray(t *testing.T) {
+	c := clock.NewFixed(time.Unix(1760000000, 0))
+
+	detector := regulation.NewAnomalyDetector(schema.RegulationAnomalies{Window: time.Minute * 10, UsernamesPerIP: 3, IPsPerUsername: 10}, c)
+
+	assert.Nil(t, detector.Observe("10.0.0.1", "john", false))
+	assert.Nil(t, detector.Observe("10.0.0.1", "john", false))
+	assert.Nil(t, detector.Observe("10.0.0.1", "harry", false))
+	assert.Equal(t, []string{regulation.AnomalyPatternSpray}, detector.Observe("10.0.0.1", "bob", false))
+	assert.Nil(t, detector.Observe("10.0.0.1", "fred", false))
+	assert.Nil(t, detector.Observe("10.0.0.2", "john", true))
+
+	assert.Equal(t, regulation.Anomalies{
+		Attempts:          6,
+		Failures:          5,
+		UsernamesPerIPMax: 4,
+		IPsPerUsernameMax: 1,
+		SpraySources:      1,
+	}, detector.Statistics())
+
+	c.Set(c.Now().Add(time.Minute * 11))
+
+	assert.Equal(t, regulation.Anomalies{}, detector.Statistics())
+	assert.Nil(t, detector.Observe("10.0.0.1", "john", false))
+	assert.Nil(t, detector.Observe("10.0.0.1", "harry", false))
+	assert.Equal(t, []string{regulation.AnomalyPatternSpray}, detector.Observe("10.0.0.1", "bob", false))
+}
+
+func TestAnomalyDetectorShouldDetectDistributed(t *testing.T) {
+	c := clock.NewFixed(time.Unix(1760000000, 0))
+
+	detector := regulation.NewAnomalyDetector(schema.RegulationAnomalies{Window: time.Minute * 10, UsernamesPerIP: 5, IPsPerUsername: 4}, c)
+
+	var patterns []string
+
+	for i := 1; i <= 6; i++ {
+		patterns = append(patterns, detector.Observe(fmt.Sprintf("10.0.0.%d", i), "john", false)...)
+
+		c.Set(c.Now().Add(time.Minute))
+	}
+
+	assert.Equal(t, []string{regulation.AnomalyPatternDistributed}, patterns)
+
+	actual := detector.Statistics()
+
+	assert.Equal(t, 6, actual.IPsPerUsernameMax)
+	assert.Equal(t, 1, actual.UsernamesPerIPMax)
+	assert.Equal(t, 1, actual.DistributedTargets)
+	assert.Equal(t, 0, actual.SpraySources)
+	assert.Equal(t, 1.0, actual.FailureRatio())
+
+	// Only the attempts from the 10.0.0.4, 10.0.0.5, and 10.0.0.6 IPs remain within the window.
+	c.Set(c.Now().Add(time.Minute * 6))
+
+	actual = detector.Statistics()
+
+	assert.Equal(t, 3, actual.IPsPerUsernameMax)
+	assert.Equal(t, 0, actual.DistributedTargets)
+}
+
+func TestAnomalyDetectorShouldNotDetectWhenDisabled(t *testing.T) {
+	detector := regulation.NewAnomalyDetector(schema.RegulationAnomalies{}, clock.NewFixed(time.Unix(1760000000, 0)))
+
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, detector.Observe("10.0.0.1", fmt.Sprintf("user%d", i), false))
+	}
+
+	assert.Equal(t, regulation.Anomalies{}, detector.Statistics())
+	assert.Equal(t, 0.0, detector.Statistics().FailureRatio())
+
+	detector.SetConfig(schema.RegulationAnomalies{Window: time.Minute, UsernamesPerIP: 2, IPsPerUsername: 2})
+
+	assert.Nil(t, detector.Observe("10.0.0.1", "john", false))
+	assert.Equal(t, []string{regulation.AnomalyPatternSpray}, detector.Observe("10.0.0.1", "harry", false))
+}
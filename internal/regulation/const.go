@@ -9,6 +9,18 @@ const (
 	// AuthType1FA is the string representing an auth log for first-factor authentication.
 	AuthType1FA = "1FA"
 
+	// AuthTypeSPNEGO is the string representing an auth log for first-factor authentication via a Kerberos ticket with
+	// SPNEGO.
+	AuthTypeSPNEGO = "SPNEGO"
+
+	// AuthTypePasskey is the string representing an auth log for first-factor authentication via a discoverable
+	// FIDO2/CTAP2/WebAuthn credential.
+	AuthTypePasskey = "Passkey"
+
+	// AuthTypeLDAP is the string representing an auth log for first-factor authentication via a simple bind to the LDAP
+	// server.
+	AuthTypeLDAP = "LDAP"
+
 	// AuthTypeTOTP is the string representing an auth log for second-factor authentication via TOTP.
 	AuthTypeTOTP = "TOTP"
 
@@ -17,4 +29,43 @@ const (
 
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypePush is the string representing an auth log for second-factor authentication via a push notification.
+	AuthTypePush = "Push"
+
+	// AuthTypeRecoveryCode is the string representing an auth log for second-factor authentication via a single-use
+	// recovery code.
+	AuthTypeRecoveryCode = "RecoveryCode"
+
+	// AuthTypeSMS is the string representing an auth log for second-factor authentication via a One-Time Code delivered
+	// by SMS or voice call.
+	AuthTypeSMS = "SMS"
+
+	// AuthTypeEmail is the string representing an auth log for second-factor authentication via a One-Time Code
+	// delivered by email.
+	AuthTypeEmail = "Email"
+
+	// AuthTypeDeviceCode is the string representing an auth log for the lookup of the user code of an OAuth 2.0 Device
+	// Authorization Grant by a logged in user.
+	AuthTypeDeviceCode = "DeviceCode"
+
+	// AuthTypeUnban is the string representing an auth log for an administrator lifting the ban of a user. It's
+	// regarded as a successful first-factor authentication by the regulator.
+	AuthTypeUnban = "Unban"
+)
+
+const (
+	// AnomalyPatternSpray is the pattern of a single IP failing the first factor for many distinct usernames, which is
+	// typical of password spraying and credential stuffing.
+	AnomalyPatternSpray = "spray"
+
+	// AnomalyPatternDistributed is the pattern of a single username failing the first factor from many distinct IPs,
+	// which is typical of a distributed brute force.
+	AnomalyPatternDistributed = "distributed"
+)
+
+const (
+	anomalyBuckets    = 10
+	anomalyMaxKeys    = 65536
+	anomalyMaxMembers = 1024
 )
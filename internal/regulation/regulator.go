@@ -2,9 +2,11 @@ package regulation
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -14,19 +16,50 @@ import (
 // NewRegulator create a regulator instance.
 func NewRegulator(config schema.Regulation, store storage.RegulatorProvider, clock clock.Provider) *Regulator {
 	return &Regulator{
-		enabled: config.MaxRetries > 0,
-		store:   store,
-		clock:   clock,
-		config:  config,
+		enabled:   config.MaxRetries > 0,
+		store:     store,
+		clock:     clock,
+		config:    config,
+		anomalies: NewAnomalyDetector(config.Anomalies, clock),
 	}
 }
 
+// SetConfig replaces the regulation configuration used for all subsequent attempts.
+func (r *Regulator) SetConfig(config schema.Regulation) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	r.enabled, r.config = config.MaxRetries > 0, config
+
+	r.anomalies.SetConfig(config.Anomalies)
+}
+
+// Anomalies returns the statistics derived from the recent first factor attempts.
+func (r *Regulator) Anomalies() Anomalies {
+	return r.anomalies.Statistics()
+}
+
+func (r *Regulator) getConfig() (enabled bool, config schema.Regulation) {
+	r.mu.RLock()
+
+	defer r.mu.RUnlock()
+
+	return r.enabled, r.config
+}
+
 // Mark an authentication attempt.
 // We split Mark and Regulate in order to avoid timing attacks.
-func (r *Regulator) Mark(ctx Context, successful, banned bool, username, requestURI, requestMethod, authType string) error {
+func (r *Regulator) Mark(ctx Context, successful, banned bool, username, requestURI, requestMethod, authType string) (err error) {
 	ctx.RecordAuthn(successful, banned, strings.ToLower(authType))
 
-	return r.store.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
+	if strings.EqualFold(authType, AuthType1FA) || strings.EqualFold(authType, AuthTypeLDAP) {
+		for _, pattern := range r.anomalies.Observe(ctx.RemoteIP().String(), strings.ToLower(username), successful) {
+			ctx.RecordAuthnAnomaly(pattern)
+		}
+	}
+
+	if err = r.store.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
 		Time:          r.clock.Now(),
 		Successful:    successful,
 		Banned:        banned,
@@ -35,26 +68,63 @@ func (r *Regulator) Mark(ctx Context, successful, banned bool, username, request
 		RemoteIP:      model.NewNullIP(ctx.RemoteIP()),
 		RequestURI:    requestURI,
 		RequestMethod: requestMethod,
-	})
+	}); err != nil {
+		return err
+	}
+
+	// A failed attempt which was not made while banned may be the attempt which results in a ban.
+	if !successful && !banned {
+		var until time.Time
+
+		if until, err = r.Regulate(ctx, username); errors.Is(err, ErrUserIsBanned) {
+			ctx.RecordAuthnBan(strings.ToLower(authType))
+			ctx.AuditEvent(audit.EventTypeRegulationBan, audit.ResultSuccess, username, map[string]any{"method": strings.ToLower(authType), "until": until.UTC()})
+		}
+	}
+
+	return nil
+}
+
+// Unban lifts the ban of a user by marking a successful attempt which resets the regulation of the user as if they had
+// successfully authenticated.
+func (r *Regulator) Unban(ctx Context, username, requestURI, requestMethod string) (err error) {
+	if err = r.store.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
+		Time:          r.clock.Now(),
+		Successful:    true,
+		Banned:        false,
+		Username:      username,
+		Type:          AuthTypeUnban,
+		RemoteIP:      model.NewNullIP(ctx.RemoteIP()),
+		RequestURI:    requestURI,
+		RequestMethod: requestMethod,
+	}); err != nil {
+		return err
+	}
+
+	ctx.AuditEvent(audit.EventTypeRegulationUnban, audit.ResultSuccess, username, nil)
+
+	return nil
 }
 
 // Regulate the authentication attempts for a given user.
 // This method returns ErrUserIsBanned if the user is banned along with the time until when the user is banned.
 func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, error) {
+	enabled, config := r.getConfig()
+
 	// If there is regulation configuration, no regulation applies.
-	if !r.enabled {
+	if !enabled {
 		return time.Time{}, nil
 	}
 
-	attempts, err := r.store.LoadAuthenticationLogs(ctx, username, r.clock.Now().Add(-r.config.BanTime), 10, 0)
+	attempts, err := r.store.LoadAuthenticationLogs(ctx, username, r.clock.Now().Add(-config.BanTime), 10, 0)
 	if err != nil {
 		return time.Time{}, nil
 	}
 
-	latestFailedAttempts := make([]model.AuthenticationAttempt, 0, r.config.MaxRetries)
+	latestFailedAttempts := make([]model.AuthenticationAttempt, 0, config.MaxRetries)
 
 	for _, attempt := range attempts {
-		if attempt.Successful || len(latestFailedAttempts) >= r.config.MaxRetries {
+		if attempt.Successful || len(latestFailedAttempts) >= config.MaxRetries {
 			// We stop appending failed attempts once we find the first successful attempts or we reach
 			// the configured number of retries, meaning the user is already banned.
 			break
@@ -65,17 +135,17 @@ func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, e
 
 	// If the number of failed attempts within the ban time is less than the max number of retries
 	// then the user is not banned.
-	if len(latestFailedAttempts) < r.config.MaxRetries {
+	if len(latestFailedAttempts) < config.MaxRetries {
 		return time.Time{}, nil
 	}
 
 	// Now we compute the time between the latest attempt and the MaxRetry-th one. If it's
 	// within the FindTime then it means that the user has been banned.
 	durationBetweenLatestAttempts := latestFailedAttempts[0].Time.Sub(
-		latestFailedAttempts[r.config.MaxRetries-1].Time)
+		latestFailedAttempts[config.MaxRetries-1].Time)
 
-	if durationBetweenLatestAttempts < r.config.FindTime {
-		bannedUntil := latestFailedAttempts[0].Time.Add(r.config.BanTime)
+	if durationBetweenLatestAttempts < config.FindTime {
+		bannedUntil := latestFailedAttempts[0].Time.Add(config.BanTime)
 		return bannedUntil, ErrUserIsBanned
 	}
 
@@ -11,6 +11,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"go.uber.org/mock/gomock"
 
+	"github.com/authelia/authelia/v4/internal/audit"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -55,6 +56,112 @@ func (s *RegulatorSuite) TestShouldMark() {
 	s.NoError(regulator.Mark(s.mock.Ctx, true, false, "john", "https://google.com", fasthttp.MethodGet, "1fa"))
 }
 
+func (s *RegulatorSuite) TestShouldMarkFailedAttemptWhichResultsInBan() {
+	regulator := regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	attemptsInDB := []model.AuthenticationAttempt{
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.mock.Clock.Now(),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.mock.Clock.Now().Add(-4 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.mock.Clock.Now().Add(-6 * time.Second),
+		},
+	}
+
+	provider := &testAuditProvider{}
+
+	s.mock.Ctx.Providers.Audit = provider
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, model.AuthenticationAttempt{
+			Time:          s.mock.Clock.Now(),
+			Successful:    false,
+			Banned:        false,
+			Username:      "john",
+			Type:          "1fa",
+			RemoteIP:      model.NewNullIP(net.ParseIP("127.0.0.1")),
+			RequestURI:    "https://google.com",
+			RequestMethod: fasthttp.MethodGet,
+		}),
+		s.mock.StorageMock.EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return(attemptsInDB, nil),
+	)
+
+	s.NoError(regulator.Mark(s.mock.Ctx, false, false, "john", "https://google.com", fasthttp.MethodGet, "1fa"))
+
+	s.Require().Len(provider.events, 1)
+	s.Equal(audit.EventTypeRegulationBan, provider.events[0].Type)
+	s.Equal(audit.ResultSuccess, provider.events[0].Result)
+	s.Equal("john", provider.events[0].Actor.Username)
+	s.Equal("1fa", provider.events[0].Details["method"])
+}
+
+func (s *RegulatorSuite) TestShouldUnban() {
+	regulator := regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	provider := &testAuditProvider{}
+
+	s.mock.Ctx.Providers.Audit = provider
+
+	s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, model.AuthenticationAttempt{
+		Time:          s.mock.Clock.Now(),
+		Successful:    true,
+		Banned:        false,
+		Username:      "john",
+		Type:          regulation.AuthTypeUnban,
+		RemoteIP:      model.NewNullIP(net.ParseIP("127.0.0.1")),
+		RequestURI:    "/api/admin/users/john/ban",
+		RequestMethod: fasthttp.MethodDelete,
+	})
+
+	s.NoError(regulator.Unban(s.mock.Ctx, "john", "/api/admin/users/john/ban", fasthttp.MethodDelete))
+
+	s.Require().Len(provider.events, 1)
+	s.Equal(audit.EventTypeRegulationUnban, provider.events[0].Type)
+	s.Equal("john", provider.events[0].Actor.Username)
+}
+
+func (s *RegulatorSuite) TestShouldNotAuditUnbanOnError() {
+	regulator := regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	provider := &testAuditProvider{}
+
+	s.mock.Ctx.Providers.Audit = provider
+
+	s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).Return(fmt.Errorf("failed to insert"))
+
+	s.EqualError(regulator.Unban(s.mock.Ctx, "john", "/api/admin/users/john/ban", fasthttp.MethodDelete), "failed to insert")
+
+	s.Len(provider.events, 0)
+}
+
+func (s *RegulatorSuite) TestShouldNotRegulateWhenMarkingBannedAttempt() {
+	regulator := regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
+
+	s.mock.StorageMock.EXPECT().AppendAuthenticationLog(s.mock.Ctx, model.AuthenticationAttempt{
+		Time:          s.mock.Clock.Now(),
+		Successful:    false,
+		Banned:        true,
+		Username:      "john",
+		Type:          "1fa",
+		RemoteIP:      model.NewNullIP(net.ParseIP("127.0.0.1")),
+		RequestURI:    "https://google.com",
+		RequestMethod: fasthttp.MethodGet,
+	})
+
+	s.NoError(regulator.Mark(s.mock.Ctx, false, true, "john", "https://google.com", fasthttp.MethodGet, "1fa"))
+}
+
 func (s *RegulatorSuite) TestShouldHandleRegulateError() {
 	regulator := regulation.NewRegulator(s.mock.Ctx.Configuration.Regulation, s.mock.StorageMock, &s.mock.Clock)
 
@@ -328,3 +435,44 @@ func (s *RegulatorSuite) TestShouldHaveRegulatorDisabled() {
 	_, err = regulator.Regulate(s.mock.Ctx, "john")
 	assert.Equal(s.T(), regulation.ErrUserIsBanned, err)
 }
+
+func (s *RegulatorSuite) TestShouldApplyUpdatedConfig() {
+	attemptsInDB := []model.AuthenticationAttempt{
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.mock.Clock.Now().Add(-31 * time.Second),
+		},
+		{
+			Username:   "john",
+			Successful: false,
+			Time:       s.mock.Clock.Now().Add(-34 * time.Second),
+		},
+	}
+
+	s.mock.StorageMock.EXPECT().
+		LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq("john"), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+		Return(attemptsInDB, nil)
+
+	regulator := regulation.NewRegulator(schema.Regulation{}, s.mock.StorageMock, &s.mock.Clock)
+
+	_, err := regulator.Regulate(s.mock.Ctx, "john")
+	assert.NoError(s.T(), err)
+
+	regulator.SetConfig(schema.Regulation{
+		MaxRetries: 2,
+		FindTime:   time.Second * 180,
+		BanTime:    time.Second * 180,
+	})
+
+	_, err = regulator.Regulate(s.mock.Ctx, "john")
+	assert.Equal(s.T(), regulation.ErrUserIsBanned, err)
+}
+
+type testAuditProvider struct {
+	events []audit.Event
+}
+
+func (p *testAuditProvider) Emit(event audit.Event) {
+	p.events = append(p.events, event)
+}
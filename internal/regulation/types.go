@@ -3,6 +3,7 @@ package regulation
 import (
 	"context"
 	"net"
+	"sync"
 
 	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -19,6 +20,10 @@ type Regulator struct {
 	store storage.RegulatorProvider
 
 	clock clock.Provider
+
+	anomalies *AnomalyDetector
+
+	mu sync.RWMutex
 }
 
 // Context represents a regulator context.
@@ -27,9 +32,12 @@ type Context interface {
 	MetricsRecorder
 
 	RemoteIP() (ip net.IP)
+	AuditEvent(eventType, result, username string, details map[string]any)
 }
 
 // MetricsRecorder represents the methods used to record regulation.
 type MetricsRecorder interface {
 	RecordAuthn(success, banned bool, authType string)
+	RecordAuthnBan(authType string)
+	RecordAuthnAnomaly(pattern string)
 }
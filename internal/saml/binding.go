@@ -0,0 +1,145 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeRedirect decodes a SAML message encoded with the HTTP-Redirect binding which is the base64 encoding of the
+// DEFLATE compressed XML of the message.
+func DecodeRedirect(value []byte) (raw []byte, err error) {
+	var compressed []byte
+
+	if compressed, err = decodeBase64(value); err != nil {
+		return nil, err
+	}
+
+	reader := flate.NewReader(bytes.NewReader(compressed))
+
+	defer reader.Close()
+
+	if raw, err = io.ReadAll(io.LimitReader(reader, maxMessageSize+1)); err != nil {
+		return nil, fmt.Errorf("error decompressing the message: %w", err)
+	}
+
+	if len(raw) > maxMessageSize {
+		return nil, errors.New("error decompressing the message: the message is too large")
+	}
+
+	return raw, nil
+}
+
+// DecodePost decodes a SAML message encoded with the HTTP-POST binding which is the base64 encoding of the XML of the
+// message.
+func DecodePost(value []byte) (raw []byte, err error) {
+	if len(value) > maxMessageSize*2 {
+		return nil, errors.New("error decoding the message: the message is too large")
+	}
+
+	return decodeBase64(value)
+}
+
+// EncodeRedirect encodes a SAML message with the HTTP-Redirect binding.
+func EncodeRedirect(raw []byte) (encoded string, err error) {
+	buf := &bytes.Buffer{}
+
+	var writer *flate.Writer
+
+	if writer, err = flate.NewWriter(buf, flate.BestCompression); err != nil {
+		return "", fmt.Errorf("error compressing the message: %w", err)
+	}
+
+	if _, err = writer.Write(raw); err != nil {
+		return "", fmt.Errorf("error compressing the message: %w", err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return "", fmt.Errorf("error compressing the message: %w", err)
+	}
+
+	return encodeBase64(buf.Bytes()), nil
+}
+
+// EncodePost encodes a SAML message with the HTTP-POST binding.
+func EncodePost(raw []byte) (encoded string) {
+	return encodeBase64(raw)
+}
+
+// ParseAuthnRequest parses the raw XML of an AuthnRequest.
+func ParseAuthnRequest(raw []byte) (request *AuthnRequest, err error) {
+	request = &AuthnRequest{}
+
+	if err = xml.Unmarshal(raw, request); err != nil {
+		return nil, fmt.Errorf("error parsing the authentication request: %w", err)
+	}
+
+	if request.Version != version {
+		return nil, fmt.Errorf("error parsing the authentication request: the version '%s' is not supported", request.Version)
+	}
+
+	if request.ID == "" {
+		return nil, errors.New("error parsing the authentication request: the request does not have an id")
+	}
+
+	if request.Issuer == "" {
+		return nil, errors.New("error parsing the authentication request: the request does not have an issuer")
+	}
+
+	request.Raw = raw
+
+	return request, nil
+}
+
+// ParseLogoutRequest parses the raw XML of a LogoutRequest.
+func ParseLogoutRequest(raw []byte) (request *LogoutRequest, err error) {
+	request = &LogoutRequest{}
+
+	if err = xml.Unmarshal(raw, request); err != nil {
+		return nil, fmt.Errorf("error parsing the logout request: %w", err)
+	}
+
+	if request.Version != version {
+		return nil, fmt.Errorf("error parsing the logout request: the version '%s' is not supported", request.Version)
+	}
+
+	if request.ID == "" {
+		return nil, errors.New("error parsing the logout request: the request does not have an id")
+	}
+
+	if request.Issuer == "" {
+		return nil, errors.New("error parsing the logout request: the request does not have an issuer")
+	}
+
+	return request, nil
+}
+
+func encodeBase64(value []byte) string {
+	return base64.StdEncoding.EncodeToString(value)
+}
+
+func decodeBase64(value []byte) (decoded []byte, err error) {
+	// Some service providers wrap the encoded messages.
+	value = bytes.Map(func(r rune) rune {
+		switch r {
+		case '\r', '\n', ' ', '\t':
+			return -1
+		default:
+			return r
+		}
+	}, value)
+
+	decoded = make([]byte, base64.StdEncoding.DecodedLen(len(value)))
+
+	var n int
+
+	if n, err = base64.StdEncoding.Decode(decoded, value); err != nil {
+		return nil, fmt.Errorf("error decoding the message: %w", err)
+	}
+
+	return decoded[:n], nil
+}
@@ -0,0 +1,114 @@
+package saml
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAuthnRequest = `<samlp:AuthnRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_abc123" Version="2.0" IssueInstant="2023-11-14T22:13:20Z" Destination="https://auth.example.com/api/saml/sso" AssertionConsumerServiceURL="https://app.example.com/saml/acs" ProtocolBinding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST">
+  <saml:Issuer>https://app.example.com</saml:Issuer>
+  <samlp:NameIDPolicy Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress" AllowCreate="true"/>
+</samlp:AuthnRequest>`
+
+const testLogoutRequest = `<samlp:LogoutRequest xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" ID="_def456" Version="2.0" IssueInstant="2023-11-14T22:13:20Z" Destination="https://auth.example.com/api/saml/slo">
+  <saml:Issuer>https://app.example.com</saml:Issuer>
+  <saml:NameID Format="urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress">john@example.com</saml:NameID>
+  <samlp:SessionIndex>_session1</samlp:SessionIndex>
+  <samlp:SessionIndex>_session2</samlp:SessionIndex>
+</samlp:LogoutRequest>`
+
+func TestRedirectBinding(t *testing.T) {
+	encoded, err := EncodeRedirect([]byte(testAuthnRequest))
+	require.NoError(t, err)
+
+	raw, err := DecodeRedirect([]byte(encoded))
+	require.NoError(t, err)
+
+	assert.Equal(t, testAuthnRequest, string(raw))
+
+	_, err = DecodeRedirect([]byte("not base64!"))
+	assert.EqualError(t, err, "error decoding the message: illegal base64 data at input byte 9")
+
+	_, err = DecodeRedirect([]byte(base64.StdEncoding.EncodeToString([]byte(testAuthnRequest))))
+	assert.ErrorContains(t, err, "error decompressing the message: ")
+
+	buf := &bytes.Buffer{}
+
+	writer, err := flate.NewWriter(buf, flate.BestCompression)
+	require.NoError(t, err)
+
+	_, err = writer.Write(bytes.Repeat([]byte("a"), maxMessageSize+1))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	_, err = DecodeRedirect([]byte(base64.StdEncoding.EncodeToString(buf.Bytes())))
+	assert.EqualError(t, err, "error decompressing the message: the message is too large")
+}
+
+func TestPostBinding(t *testing.T) {
+	encoded := EncodePost([]byte(testAuthnRequest))
+
+	wrapped := strings.Join([]string{encoded[:10], encoded[10:20], encoded[20:]}, "\r\n")
+
+	raw, err := DecodePost([]byte(wrapped))
+	require.NoError(t, err)
+
+	assert.Equal(t, testAuthnRequest, string(raw))
+
+	_, err = DecodePost(bytes.Repeat([]byte("a"), maxMessageSize*2+1))
+	assert.EqualError(t, err, "error decoding the message: the message is too large")
+}
+
+func TestParseAuthnRequest(t *testing.T) {
+	request, err := ParseAuthnRequest([]byte(testAuthnRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, "_abc123", request.ID)
+	assert.Equal(t, "https://app.example.com", request.Issuer)
+	assert.Equal(t, "https://app.example.com/saml/acs", request.AssertionConsumerServiceURL)
+	assert.Equal(t, BindingHTTPPost, request.ProtocolBinding)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), request.IssueInstant)
+	assert.Equal(t, &NameIDPolicy{Format: NameIDFormatEmail, AllowCreate: true}, request.NameIDPolicy)
+	assert.Equal(t, testAuthnRequest, string(request.Raw))
+
+	testCases := []struct {
+		name string
+		have string
+		err  string
+	}{
+		{"ShouldErrorVersion", strings.Replace(testAuthnRequest, `Version="2.0"`, `Version="1.1"`, 1), "error parsing the authentication request: the version '1.1' is not supported"},
+		{"ShouldErrorID", strings.Replace(testAuthnRequest, `ID="_abc123"`, "", 1), "error parsing the authentication request: the request does not have an id"},
+		{"ShouldErrorIssuer", strings.Replace(testAuthnRequest, "https://app.example.com</saml:Issuer>", "</saml:Issuer>", 1), "error parsing the authentication request: the request does not have an issuer"},
+		{"ShouldErrorElement", testLogoutRequest, "error parsing the authentication request: expected element type <AuthnRequest> but have <LogoutRequest>"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err = ParseAuthnRequest([]byte(tc.have))
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseLogoutRequest(t *testing.T) {
+	request, err := ParseLogoutRequest([]byte(testLogoutRequest))
+	require.NoError(t, err)
+
+	assert.Equal(t, "_def456", request.ID)
+	assert.Equal(t, "https://app.example.com", request.Issuer)
+	assert.Equal(t, "john@example.com", request.NameID)
+	assert.Equal(t, []string{"_session1", "_session2"}, request.SessionIndexes)
+
+	_, err = ParseLogoutRequest([]byte(strings.Replace(testLogoutRequest, `ID="_def456"`, "", 1)))
+	assert.EqualError(t, err, "error parsing the logout request: the request does not have an id")
+
+	_, err = ParseLogoutRequest([]byte(testAuthnRequest))
+	assert.EqualError(t, err, "error parsing the logout request: expected element type <LogoutRequest> but have <AuthnRequest>")
+}
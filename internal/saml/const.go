@@ -0,0 +1,105 @@
+package saml
+
+// XML namespace strings.
+const (
+	NamespaceAssertion = "urn:oasis:names:tc:SAML:2.0:assertion"
+	NamespaceProtocol  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	NamespaceMetadata  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	NamespaceDSig      = "http://www.w3.org/2000/09/xmldsig#"
+)
+
+// XML namespace prefix strings.
+const (
+	prefixAssertion = "saml"
+	prefixProtocol  = "samlp"
+	prefixMetadata  = "md"
+	prefixDSig      = "ds"
+)
+
+// Binding strings.
+const (
+	BindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	BindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+)
+
+// Name Identifier Format strings.
+const (
+	NameIDFormatUnspecified = "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified"
+	NameIDFormatEmail       = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatPersistent  = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDFormatTransient   = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+)
+
+// Status Code strings.
+const (
+	StatusSuccess             = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	StatusRequester           = "urn:oasis:names:tc:SAML:2.0:status:Requester"
+	StatusResponder           = "urn:oasis:names:tc:SAML:2.0:status:Responder"
+	StatusInvalidNameIDPolicy = "urn:oasis:names:tc:SAML:2.0:status:InvalidNameIDPolicy"
+)
+
+// Authentication Context Class strings.
+const (
+	AuthnContextPasswordProtectedTransport = "urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport"
+	AuthnContextMultiFactor                = "https://refeds.org/profile/mfa"
+)
+
+// Attribute strings.
+const (
+	AttributeNameFormatBasic = "urn:oasis:names:tc:SAML:2.0:attrname-format:basic"
+
+	AttributeUsername    = "uid"
+	AttributeEmail       = "mail"
+	AttributeDisplayName = "displayName"
+	AttributeGroups      = "groups"
+)
+
+// Signature algorithm strings.
+const (
+	AlgorithmExclusiveCanonicalization = "http://www.w3.org/2001/10/xml-exc-c14n#"
+	AlgorithmEnvelopedSignature        = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+	AlgorithmRSASHA256                 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	AlgorithmRSASHA512                 = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha512"
+	AlgorithmDigestSHA256              = "http://www.w3.org/2001/04/xmlenc#sha256"
+	AlgorithmDigestSHA512              = "http://www.w3.org/2001/04/xmlenc#sha512"
+)
+
+// Configuration value strings.
+const (
+	SigningAlgRSAUsingSHA256 = "RS256"
+	SigningAlgRSAUsingSHA512 = "RS512"
+
+	nameIDFormatUnspecified = "unspecified"
+	nameIDFormatEmail       = "email"
+	nameIDFormatPersistent  = "persistent"
+	nameIDFormatTransient   = "transient"
+)
+
+// Form and query parameter strings.
+const (
+	FormParameterSAMLRequest  = "SAMLRequest"
+	FormParameterSAMLResponse = "SAMLResponse"
+	FormParameterRelayState   = "RelayState"
+	FormParameterSigAlg       = "SigAlg"
+	FormParameterSignature    = "Signature"
+)
+
+// Endpoint path strings.
+const (
+	EndpointPathMetadata            = "/api/saml/metadata"
+	EndpointPathSingleSignOnService = "/api/saml/sso"
+	EndpointPathSingleLogoutService = "/api/saml/slo"
+)
+
+const (
+	// OpaqueIdentifierService is the service name used for the opaque identifiers of the persistent name identifiers.
+	OpaqueIdentifierService = "saml"
+
+	// ConfirmationMethodBearer is the bearer subject confirmation method.
+	ConfirmationMethodBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+
+	version        = "2.0"
+	timeFormat     = "2006-01-02T15:04:05Z"
+	maxMessageSize = 1 << 20
+	keyUseSigning  = "signing"
+)
@@ -0,0 +1,495 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/random"
+)
+
+// NewProvider creates a new Provider given the SAML 2.0 configuration. It returns nil if the SAML 2.0 Identity Provider
+// is not configured.
+func NewProvider(config *schema.IdentityProvidersSAML) (provider *Provider, err error) {
+	if config == nil {
+		return nil, nil
+	}
+
+	leaf := config.CertificateChain.Leaf()
+	if leaf == nil {
+		return nil, fmt.Errorf("error creating the saml provider: the certificate chain has no certificates")
+	}
+
+	provider = &Provider{
+		config:           config,
+		serviceProviders: map[string]*ServiceProvider{},
+		random:           &random.Cryptographical{},
+	}
+
+	if provider.signer, err = newSigner(config.PrivateKey, leaf.Raw, config.SignatureAlgorithm); err != nil {
+		return nil, fmt.Errorf("error creating the saml provider: %w", err)
+	}
+
+	for _, sp := range config.ServiceProviders {
+		var registered *ServiceProvider
+
+		if registered, err = NewServiceProvider(sp); err != nil {
+			return nil, fmt.Errorf("error creating the saml provider: %w", err)
+		}
+
+		provider.serviceProviders[registered.EntityID] = registered
+	}
+
+	return provider, nil
+}
+
+// NewServiceProvider creates a new ServiceProvider given the configuration of a SAML 2.0 Service Provider. The
+// assertion consumer service and single logout service URLs of the metadata are added to the configured URLs.
+func NewServiceProvider(config schema.IdentityProvidersSAMLServiceProvider) (sp *ServiceProvider, err error) {
+	sp = &ServiceProvider{
+		EntityID:     config.EntityID,
+		Name:         config.Name,
+		NameIDFormat: toNameIDFormat(config.NameIDFormat),
+		Policy:       authorization.NewLevel(config.AuthorizationPolicy),
+		SignResponse: config.SignResponse,
+	}
+
+	for _, uri := range config.AssertionConsumerServiceURLs {
+		sp.AssertionConsumerServiceURLs = append(sp.AssertionConsumerServiceURLs, uri.String())
+	}
+
+	if config.SingleLogoutServiceURL != nil {
+		sp.SingleLogoutServiceURL = config.SingleLogoutServiceURL.String()
+	}
+
+	if config.CertificateChain.HasCertificates() {
+		sp.Certificate = config.CertificateChain.Leaf()
+	}
+
+	if config.Metadata == "" {
+		return sp, nil
+	}
+
+	var f *os.File
+
+	if f, err = os.Open(config.Metadata); err != nil {
+		return nil, fmt.Errorf("error reading the metadata of service provider '%s': %w", config.EntityID, err)
+	}
+
+	defer f.Close()
+
+	metadata := &EntityDescriptor{}
+
+	if err = xml.NewDecoder(io.LimitReader(f, maxMessageSize)).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("error decoding the metadata of service provider '%s': %w", config.EntityID, err)
+	}
+
+	if metadata.EntityID != config.EntityID {
+		return nil, fmt.Errorf("error decoding the metadata of service provider '%s': the metadata is for the entity '%s'", config.EntityID, metadata.EntityID)
+	}
+
+	if metadata.SPSSODescriptor == nil {
+		return nil, fmt.Errorf("error decoding the metadata of service provider '%s': the metadata does not describe a service provider", config.EntityID)
+	}
+
+	for _, endpoint := range metadata.SPSSODescriptor.AssertionConsumerServices {
+		if endpoint.Binding == BindingHTTPPost && !sp.IsAssertionConsumerServiceURL(endpoint.Location) {
+			sp.AssertionConsumerServiceURLs = append(sp.AssertionConsumerServiceURLs, endpoint.Location)
+		}
+	}
+
+	if sp.SingleLogoutServiceURL == "" {
+		for _, endpoint := range metadata.SPSSODescriptor.SingleLogoutServices {
+			if endpoint.Binding == BindingHTTPRedirect {
+				sp.SingleLogoutServiceURL = endpoint.Location
+
+				break
+			}
+		}
+	}
+
+	if sp.Certificate == nil {
+		if sp.Certificate, err = getSigningCertificate(metadata.SPSSODescriptor.KeyDescriptors); err != nil {
+			return nil, fmt.Errorf("error decoding the metadata of service provider '%s': %w", config.EntityID, err)
+		}
+	}
+
+	if len(sp.AssertionConsumerServiceURLs) == 0 {
+		return nil, fmt.Errorf("error decoding the metadata of service provider '%s': the metadata does not have an assertion consumer service with the '%s' binding", config.EntityID, BindingHTTPPost)
+	}
+
+	return sp, nil
+}
+
+// Provider is the SAML 2.0 Identity Provider.
+type Provider struct {
+	config           *schema.IdentityProvidersSAML
+	serviceProviders map[string]*ServiceProvider
+	signer           *signer
+	random           random.Provider
+}
+
+// GetServiceProvider returns the registered service provider with the given entity id.
+func (p *Provider) GetServiceProvider(entityID string) (sp *ServiceProvider, err error) {
+	var ok bool
+
+	if sp, ok = p.serviceProviders[entityID]; !ok {
+		return nil, fmt.Errorf("the service provider '%s' is not registered", entityID)
+	}
+
+	return sp, nil
+}
+
+// GetSessionLifespan returns the duration a service provider can maintain the session it establishes with an
+// assertion.
+func (p *Provider) GetSessionLifespan() time.Duration {
+	return p.config.SessionLifespan
+}
+
+// GetEntityID returns the entity id of the identity provider given the root URL of the request.
+func (p *Provider) GetEntityID(root *url.URL) string {
+	if p.config.EntityID != "" {
+		return p.config.EntityID
+	}
+
+	return root.JoinPath(EndpointPathMetadata).String()
+}
+
+// NewID returns a new random identifier for a SAML message or assertion.
+func (p *Provider) NewID() string {
+	return "_" + p.random.StringCustom(40, random.CharSetAlphaNumeric)
+}
+
+// Metadata returns the SAML 2.0 metadata of the identity provider given the root URL of the request.
+func (p *Provider) Metadata(root *url.URL) []byte {
+	sso, slo := root.JoinPath(EndpointPathSingleSignOnService).String(), root.JoinPath(EndpointPathSingleLogoutService).String()
+
+	descriptor := newMetadataElement("IDPSSODescriptor").
+		Attr("WantAuthnRequestsSigned", "false").
+		Attr("protocolSupportEnumeration", NamespaceProtocol).
+		Add(
+			newMetadataElement("KeyDescriptor").Attr("use", keyUseSigning).Add(p.signer.KeyInfo()),
+			newMetadataElement("SingleLogoutService").Attr("Binding", BindingHTTPRedirect).Attr("Location", slo),
+		)
+
+	for _, format := range []string{NameIDFormatUnspecified, NameIDFormatEmail, NameIDFormatPersistent, NameIDFormatTransient} {
+		descriptor.Add(newMetadataElement("NameIDFormat").Text(format))
+	}
+
+	descriptor.Add(
+		newMetadataElement("SingleSignOnService").Attr("Binding", BindingHTTPRedirect).Attr("Location", sso),
+		newMetadataElement("SingleSignOnService").Attr("Binding", BindingHTTPPost).Attr("Location", sso),
+	)
+
+	return newMetadataElement("EntityDescriptor").
+		Namespace(prefixMetadata, NamespaceMetadata).
+		Namespace(prefixDSig, NamespaceDSig).
+		Attr("entityID", p.GetEntityID(root)).
+		Add(descriptor).
+		Document()
+}
+
+// NewResponse returns a new signed SAML 2.0 Response which contains a signed assertion for the service provider. The
+// response itself is only signed if the service provider is configured to sign the response.
+func (p *Provider) NewResponse(root *url.URL, sp *ServiceProvider, assertion *Assertion) (response []byte, err error) {
+	issuer := p.GetEntityID(root)
+	issued := assertion.IssuedAt.UTC().Format(timeFormat)
+	expires := assertion.IssuedAt.Add(p.config.AssertionLifespan).UTC().Format(timeFormat)
+
+	var inResponseTo string
+
+	if assertion.Request != nil {
+		inResponseTo = assertion.Request.ID
+	}
+
+	confirmation := newAssertionElement("SubjectConfirmationData").
+		Attr("NotOnOrAfter", expires).
+		Attr("Recipient", assertion.Destination)
+
+	if inResponseTo != "" {
+		confirmation.Attr("InResponseTo", inResponseTo)
+	}
+
+	class := AuthnContextPasswordProtectedTransport
+
+	if assertion.Level >= authorization.TwoFactor {
+		class = AuthnContextMultiFactor
+	}
+
+	assertionID := p.NewID()
+
+	a := newAssertionElement("Assertion").
+		Namespace(prefixAssertion, NamespaceAssertion).
+		Attr("ID", assertionID).
+		Attr("IssueInstant", issued).
+		Attr("Version", version).
+		Add(
+			newAssertionElement("Issuer").Text(issuer),
+			newAssertionElement("Subject").Add(
+				newAssertionElement("NameID").Attr("Format", assertion.NameIDFormat).Text(assertion.NameID),
+				newAssertionElement("SubjectConfirmation").Attr("Method", ConfirmationMethodBearer).Add(confirmation),
+			),
+			newAssertionElement("Conditions").
+				Attr("NotBefore", issued).
+				Attr("NotOnOrAfter", expires).
+				Add(newAssertionElement("AudienceRestriction").Add(newAssertionElement("Audience").Text(sp.EntityID))),
+			newAssertionElement("AuthnStatement").
+				Attr("AuthnInstant", assertion.AuthenticatedAt.UTC().Format(timeFormat)).
+				Attr("SessionIndex", assertion.SessionIndex).
+				Attr("SessionNotOnOrAfter", assertion.IssuedAt.Add(p.config.SessionLifespan).UTC().Format(timeFormat)).
+				Add(newAssertionElement("AuthnContext").Add(newAssertionElement("AuthnContextClassRef").Text(class))),
+		)
+
+	if statement := newAttributeStatement(assertion); statement != nil {
+		a.Add(statement)
+	}
+
+	if err = p.signer.SignElement(a, assertionID); err != nil {
+		return nil, fmt.Errorf("error signing the assertion: %w", err)
+	}
+
+	responseID := p.NewID()
+
+	r := newProtocolElement("Response").
+		Namespace(prefixProtocol, NamespaceProtocol).
+		Attr("Destination", assertion.Destination).
+		Attr("ID", responseID).
+		Attr("IssueInstant", issued).
+		Attr("Version", version).
+		Add(
+			newAssertionElement("Issuer").Namespace(prefixAssertion, NamespaceAssertion).Text(issuer),
+			newStatus(StatusSuccess),
+			a,
+		)
+
+	if inResponseTo != "" {
+		r.Attr("InResponseTo", inResponseTo)
+	}
+
+	if sp.SignResponse {
+		if err = p.signer.SignElement(r, responseID); err != nil {
+			return nil, fmt.Errorf("error signing the response: %w", err)
+		}
+	}
+
+	return r.Document(), nil
+}
+
+// NewErrorResponse returns a new SAML 2.0 Response which indicates the AuthnRequest failed with the given status.
+func (p *Provider) NewErrorResponse(root *url.URL, request *AuthnRequest, destination, status string, now time.Time) (response []byte) {
+	return newProtocolElement("Response").
+		Namespace(prefixProtocol, NamespaceProtocol).
+		Attr("Destination", destination).
+		Attr("ID", p.NewID()).
+		Attr("InResponseTo", request.ID).
+		Attr("IssueInstant", now.UTC().Format(timeFormat)).
+		Attr("Version", version).
+		Add(
+			newAssertionElement("Issuer").Namespace(prefixAssertion, NamespaceAssertion).Text(p.GetEntityID(root)),
+			newStatus(status),
+		).
+		Document()
+}
+
+// NewLogoutResponseURL returns the URL of the single logout service of the service provider with a signed SAML 2.0
+// LogoutResponse using the HTTP-Redirect binding.
+func (p *Provider) NewLogoutResponseURL(root *url.URL, sp *ServiceProvider, request *LogoutRequest, status, relayState string, now time.Time) (redirect *url.URL, err error) {
+	if sp.SingleLogoutServiceURL == "" {
+		return nil, fmt.Errorf("the service provider '%s' does not have a single logout service url", sp.EntityID)
+	}
+
+	if redirect, err = url.Parse(sp.SingleLogoutServiceURL); err != nil {
+		return nil, fmt.Errorf("error parsing the single logout service url of service provider '%s': %w", sp.EntityID, err)
+	}
+
+	response := newProtocolElement("LogoutResponse").
+		Namespace(prefixProtocol, NamespaceProtocol).
+		Attr("Destination", sp.SingleLogoutServiceURL).
+		Attr("ID", p.NewID()).
+		Attr("InResponseTo", request.ID).
+		Attr("IssueInstant", now.UTC().Format(timeFormat)).
+		Attr("Version", version).
+		Add(
+			newAssertionElement("Issuer").Namespace(prefixAssertion, NamespaceAssertion).Text(p.GetEntityID(root)),
+			newStatus(status),
+		)
+
+	var encoded string
+
+	if encoded, err = EncodeRedirect(response.Bytes()); err != nil {
+		return nil, fmt.Errorf("error encoding the logout response: %w", err)
+	}
+
+	// The signature of the HTTP-Redirect binding is calculated over the URL encoded parameters in this exact order.
+	octets := url.Values{FormParameterSAMLResponse: []string{encoded}}.Encode()
+
+	if relayState != "" {
+		octets += "&" + url.Values{FormParameterRelayState: []string{relayState}}.Encode()
+	}
+
+	octets += "&" + url.Values{FormParameterSigAlg: []string{p.signer.algorithm}}.Encode()
+
+	var signature []byte
+
+	if signature, err = p.signer.Sign([]byte(octets)); err != nil {
+		return nil, fmt.Errorf("error signing the logout response: %w", err)
+	}
+
+	query := octets + "&" + url.Values{FormParameterSignature: []string{encodeBase64(signature)}}.Encode()
+
+	if redirect.RawQuery == "" {
+		redirect.RawQuery = query
+	} else {
+		redirect.RawQuery += "&" + query
+	}
+
+	return redirect, nil
+}
+
+// IsAssertionConsumerServiceURL returns true if the URL is one of the assertion consumer service URLs of the service
+// provider.
+func (sp *ServiceProvider) IsAssertionConsumerServiceURL(uri string) bool {
+	for _, acs := range sp.AssertionConsumerServiceURLs {
+		if acs == uri {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetAssertionConsumerServiceURL returns the assertion consumer service URL for an AuthnRequest. If the request
+// doesn't specify one the first registered URL is returned.
+func (sp *ServiceProvider) GetAssertionConsumerServiceURL(request *AuthnRequest) (uri string, err error) {
+	if request.ProtocolBinding != "" && request.ProtocolBinding != BindingHTTPPost {
+		return "", fmt.Errorf("the protocol binding '%s' is not supported", request.ProtocolBinding)
+	}
+
+	if request.AssertionConsumerServiceURL == "" {
+		return sp.AssertionConsumerServiceURLs[0], nil
+	}
+
+	if !sp.IsAssertionConsumerServiceURL(request.AssertionConsumerServiceURL) {
+		return "", fmt.Errorf("the assertion consumer service url '%s' is not registered for the service provider '%s'", request.AssertionConsumerServiceURL, sp.EntityID)
+	}
+
+	return request.AssertionConsumerServiceURL, nil
+}
+
+// GetNameIDFormat returns the name identifier format for an AuthnRequest. The format requested by the name identifier
+// policy must be the configured format or the unspecified format.
+func (sp *ServiceProvider) GetNameIDFormat(request *AuthnRequest) (format string, err error) {
+	if request.NameIDPolicy == nil || request.NameIDPolicy.Format == "" || request.NameIDPolicy.Format == NameIDFormatUnspecified {
+		return sp.NameIDFormat, nil
+	}
+
+	if request.NameIDPolicy.Format != sp.NameIDFormat {
+		return "", fmt.Errorf("the name identifier format '%s' is not configured for the service provider '%s'", request.NameIDPolicy.Format, sp.EntityID)
+	}
+
+	return sp.NameIDFormat, nil
+}
+
+// VerifyRedirectSignature verifies the signature of a message sent by the service provider with the HTTP-Redirect
+// binding given the raw query string of the request and the parameter of the message. Messages which are not signed
+// are rejected.
+func (sp *ServiceProvider) VerifyRedirectSignature(query []byte, parameter string) (err error) {
+	if sp.Certificate == nil {
+		return fmt.Errorf("the service provider '%s' does not have a certificate to verify the signature with", sp.EntityID)
+	}
+
+	return verifyRedirectSignature(sp.Certificate, string(query), parameter)
+}
+
+// GetDisplayName returns the name of the service provider or the entity id if it doesn't have a name.
+func (sp *ServiceProvider) GetDisplayName() string {
+	if sp.Name == "" {
+		return sp.EntityID
+	}
+
+	return sp.Name
+}
+
+func newStatus(code string) *element {
+	return newProtocolElement("Status").Add(newProtocolElement("StatusCode").Attr("Value", code))
+}
+
+func newAttributeStatement(assertion *Assertion) *element {
+	statement := newAssertionElement("AttributeStatement")
+
+	add := func(name string, values ...string) {
+		if len(values) == 0 {
+			return
+		}
+
+		attribute := newAssertionElement("Attribute").Attr("Name", name).Attr("NameFormat", AttributeNameFormatBasic)
+
+		for _, value := range values {
+			attribute.Add(newAssertionElement("AttributeValue").Text(value))
+		}
+
+		statement.Add(attribute)
+	}
+
+	add(AttributeUsername, assertion.Username)
+
+	if len(assertion.Emails) != 0 {
+		add(AttributeEmail, assertion.Emails[0])
+	}
+
+	if assertion.DisplayName != "" {
+		add(AttributeDisplayName, assertion.DisplayName)
+	}
+
+	add(AttributeGroups, assertion.Groups...)
+
+	if len(statement.children) == 0 {
+		return nil
+	}
+
+	return statement
+}
+
+// getSigningCertificate returns the first signing certificate of the key descriptors of the metadata of a service
+// provider, or nil if it doesn't have one.
+func getSigningCertificate(descriptors []KeyDescriptor) (certificate *x509.Certificate, err error) {
+	for _, descriptor := range descriptors {
+		if (descriptor.Use != "" && descriptor.Use != keyUseSigning) || len(descriptor.Certificates) == 0 {
+			continue
+		}
+
+		var raw []byte
+
+		if raw, err = decodeBase64([]byte(descriptor.Certificates[0])); err != nil {
+			return nil, fmt.Errorf("error decoding the signing certificate: %w", err)
+		}
+
+		if certificate, err = x509.ParseCertificate(raw); err != nil {
+			return nil, fmt.Errorf("error parsing the signing certificate: %w", err)
+		}
+
+		return certificate, nil
+	}
+
+	return nil, nil
+}
+
+func toNameIDFormat(format string) string {
+	switch strings.ToLower(format) {
+	case nameIDFormatEmail:
+		return NameIDFormatEmail
+	case nameIDFormatPersistent:
+		return NameIDFormatPersistent
+	case nameIDFormatTransient:
+		return NameIDFormatTransient
+	default:
+		return NameIDFormatUnspecified
+	}
+}
@@ -0,0 +1,575 @@
+package saml
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestCertificate(t *testing.T, name string) (certificate *x509.Certificate, key *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	certificate, err = x509.ParseCertificate(raw)
+	require.NoError(t, err)
+
+	return certificate, key
+}
+
+func newTestProvider(t *testing.T, sps ...schema.IdentityProvidersSAMLServiceProvider) (provider *Provider, key *rsa.PrivateKey) {
+	certificate, key := newTestCertificate(t, "auth.example.com")
+
+	var err error
+
+	if len(sps) == 0 {
+		sps = []schema.IdentityProvidersSAMLServiceProvider{
+			{
+				EntityID:                     "https://app.example.com",
+				AssertionConsumerServiceURLs: []*url.URL{{Scheme: "https", Host: "app.example.com", Path: "/saml/acs"}},
+				SingleLogoutServiceURL:       &url.URL{Scheme: "https", Host: "app.example.com", Path: "/saml/slo"},
+				NameIDFormat:                 "email",
+				AuthorizationPolicy:          "two_factor",
+			},
+		}
+	}
+
+	provider, err = NewProvider(&schema.IdentityProvidersSAML{
+		CertificateChain:   schema.NewX509CertificateChainFromCerts([]*x509.Certificate{certificate}),
+		PrivateKey:         key,
+		SignatureAlgorithm: SigningAlgRSAUsingSHA256,
+		AssertionLifespan:  time.Minute * 5,
+		SessionLifespan:    time.Hour * 8,
+		ServiceProviders:   sps,
+	})
+	require.NoError(t, err)
+
+	return provider, key
+}
+
+// verifyTestSignature verifies the enveloped signature of the element with the given id in a document rendered by the
+// provider. The rendered elements are in their canonical form so the signed octets are extracted from the document.
+func verifyTestSignature(t *testing.T, key *rsa.PublicKey, document []byte, name, id string) {
+	start := bytes.Index(document, []byte("<"+name+" "))
+	require.NotEqual(t, -1, start)
+
+	end := bytes.LastIndex(document, []byte("</"+name+">"))
+	require.NotEqual(t, -1, end)
+
+	element := document[start : end+len("</"+name+">")]
+
+	require.True(t, bytes.Contains(element, []byte(`ID="`+id+`"`)))
+
+	signature := regexp.MustCompile(`<ds:Signature xmlns:ds="[^"]+">.*?<ds:SignedInfo>(.*?)</ds:SignedInfo><ds:SignatureValue>([^<]+)</ds:SignatureValue>.*?</ds:Signature>`).FindSubmatch(element)
+	require.NotNil(t, signature)
+
+	signed := bytes.Replace(element, signature[0], nil, 1)
+
+	digest := sha256.Sum256(signed)
+
+	assert.Contains(t, string(signature[1]), `<ds:Reference URI="#`+id+`">`)
+	assert.Contains(t, string(signature[1]), "<ds:DigestValue>"+base64.StdEncoding.EncodeToString(digest[:])+"</ds:DigestValue>")
+
+	value, err := base64.StdEncoding.DecodeString(string(signature[2]))
+	require.NoError(t, err)
+
+	info := sha256.Sum256([]byte(`<ds:SignedInfo xmlns:ds="` + NamespaceDSig + `">` + string(signature[1]) + `</ds:SignedInfo>`))
+
+	assert.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, info[:], value))
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, _ = newTestProvider(t)
+
+	sp, err := provider.GetServiceProvider("https://app.example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, &ServiceProvider{
+		EntityID:                     "https://app.example.com",
+		AssertionConsumerServiceURLs: []string{"https://app.example.com/saml/acs"},
+		SingleLogoutServiceURL:       "https://app.example.com/saml/slo",
+		NameIDFormat:                 NameIDFormatEmail,
+		Policy:                       authorization.TwoFactor,
+	}, sp)
+
+	assert.Equal(t, "https://app.example.com", sp.GetDisplayName())
+
+	_, err = provider.GetServiceProvider("https://other.example.com")
+	assert.EqualError(t, err, "the service provider 'https://other.example.com' is not registered")
+}
+
+func TestNewServiceProviderMetadata(t *testing.T) {
+	dir := t.TempDir()
+
+	metadata := filepath.Join(dir, "metadata.xml")
+
+	certificate, _ := newTestCertificate(t, "app.example.com")
+
+	require.NoError(t, os.WriteFile(metadata, []byte(`<?xml version="1.0"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://app.example.com">
+  <md:SPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>YWJj</ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#"><ds:X509Data><ds:X509Certificate>
+        `+base64.StdEncoding.EncodeToString(certificate.Raw)+`
+      </ds:X509Certificate></ds:X509Data></ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://app.example.com/saml/slo/post"/>
+    <md:SingleLogoutService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://app.example.com/saml/slo"/>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Artifact" Location="https://app.example.com/saml/artifact" index="0"/>
+    <md:AssertionConsumerService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://app.example.com/saml/acs" index="1"/>
+  </md:SPSSODescriptor>
+</md:EntityDescriptor>`), 0600))
+
+	sp, err := NewServiceProvider(schema.IdentityProvidersSAMLServiceProvider{
+		EntityID:            "https://app.example.com",
+		Name:                "Example App",
+		Metadata:            metadata,
+		NameIDFormat:        "persistent",
+		AuthorizationPolicy: "one_factor",
+		SignResponse:        true,
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, sp.Certificate)
+	assert.Equal(t, certificate.Raw, sp.Certificate.Raw)
+
+	sp.Certificate = nil
+
+	assert.Equal(t, &ServiceProvider{
+		EntityID:                     "https://app.example.com",
+		Name:                         "Example App",
+		AssertionConsumerServiceURLs: []string{"https://app.example.com/saml/acs"},
+		SingleLogoutServiceURL:       "https://app.example.com/saml/slo",
+		NameIDFormat:                 NameIDFormatPersistent,
+		Policy:                       authorization.OneFactor,
+		SignResponse:                 true,
+	}, sp)
+
+	assert.Equal(t, "Example App", sp.GetDisplayName())
+
+	_, err = NewServiceProvider(schema.IdentityProvidersSAMLServiceProvider{EntityID: "https://other.example.com", Metadata: metadata})
+	assert.EqualError(t, err, "error decoding the metadata of service provider 'https://other.example.com': the metadata is for the entity 'https://app.example.com'")
+
+	_, err = NewServiceProvider(schema.IdentityProvidersSAMLServiceProvider{EntityID: "https://app.example.com", Metadata: filepath.Join(dir, "missing.xml")})
+	assert.ErrorContains(t, err, "error reading the metadata of service provider 'https://app.example.com': open ")
+}
+
+func TestProviderMetadata(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	metadata := provider.Metadata(&url.URL{Scheme: "https", Host: "auth.example.com"})
+
+	descriptor := &struct {
+		XMLName  xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+		EntityID string   `xml:"entityID,attr"`
+		IDP      struct {
+			Certificate string     `xml:"KeyDescriptor>KeyInfo>X509Data>X509Certificate"`
+			SSO         []Endpoint `xml:"SingleSignOnService"`
+			SLO         []Endpoint `xml:"SingleLogoutService"`
+			Formats     []string   `xml:"NameIDFormat"`
+		} `xml:"IDPSSODescriptor"`
+	}{}
+
+	require.NoError(t, xml.Unmarshal(metadata, descriptor))
+
+	assert.Equal(t, "https://auth.example.com/api/saml/metadata", descriptor.EntityID)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(provider.signer.certificate), descriptor.IDP.Certificate)
+	assert.Equal(t, []Endpoint{{Binding: BindingHTTPRedirect, Location: "https://auth.example.com/api/saml/sso"}, {Binding: BindingHTTPPost, Location: "https://auth.example.com/api/saml/sso"}}, descriptor.IDP.SSO)
+	assert.Equal(t, []Endpoint{{Binding: BindingHTTPRedirect, Location: "https://auth.example.com/api/saml/slo"}}, descriptor.IDP.SLO)
+	assert.Equal(t, []string{NameIDFormatUnspecified, NameIDFormatEmail, NameIDFormatPersistent, NameIDFormatTransient}, descriptor.IDP.Formats)
+
+	provider.config.EntityID = "urn:example:idp"
+
+	assert.Equal(t, "urn:example:idp", provider.GetEntityID(&url.URL{Scheme: "https", Host: "auth.example.com"}))
+}
+
+func TestProviderNewResponse(t *testing.T) {
+	testCases := []struct {
+		name         string
+		signResponse bool
+		level        authorization.Level
+		class        string
+	}{
+		{"ShouldSignAssertion", false, authorization.OneFactor, AuthnContextPasswordProtectedTransport},
+		{"ShouldSignAssertionAndResponse", true, authorization.TwoFactor, AuthnContextMultiFactor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, key := newTestProvider(t)
+
+			sp, err := provider.GetServiceProvider("https://app.example.com")
+			require.NoError(t, err)
+
+			sp.SignResponse = tc.signResponse
+
+			issued := time.Unix(1700000000, 0)
+
+			raw, err := provider.NewResponse(&url.URL{Scheme: "https", Host: "auth.example.com"}, sp, &Assertion{
+				Request:         &AuthnRequest{ID: "_request"},
+				Destination:     "https://app.example.com/saml/acs",
+				NameID:          "john@example.com",
+				NameIDFormat:    NameIDFormatEmail,
+				SessionIndex:    "_session",
+				Username:        "john",
+				DisplayName:     "John <Smith>",
+				Emails:          []string{"john@example.com"},
+				Groups:          []string{"admins", "dev"},
+				Level:           tc.level,
+				AuthenticatedAt: issued.Add(-time.Minute),
+				IssuedAt:        issued,
+			})
+			require.NoError(t, err)
+
+			response := &struct {
+				XMLName      xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol Response"`
+				ID           string   `xml:"ID,attr"`
+				InResponseTo string   `xml:"InResponseTo,attr"`
+				Destination  string   `xml:"Destination,attr"`
+				Issuer       string   `xml:"Issuer"`
+				Status       struct {
+					Code struct {
+						Value string `xml:"Value,attr"`
+					} `xml:"StatusCode"`
+				} `xml:"Status"`
+				Assertion struct {
+					ID      string `xml:"ID,attr"`
+					Issuer  string `xml:"Issuer"`
+					Subject struct {
+						NameID struct {
+							Format string `xml:"Format,attr"`
+							Value  string `xml:",chardata"`
+						} `xml:"NameID"`
+						Confirmation struct {
+							Data struct {
+								InResponseTo string `xml:"InResponseTo,attr"`
+								NotOnOrAfter string `xml:"NotOnOrAfter,attr"`
+								Recipient    string `xml:"Recipient,attr"`
+							} `xml:"SubjectConfirmationData"`
+						} `xml:"SubjectConfirmation"`
+					} `xml:"Subject"`
+					Audience  string `xml:"Conditions>AudienceRestriction>Audience"`
+					Statement struct {
+						SessionIndex        string `xml:"SessionIndex,attr"`
+						SessionNotOnOrAfter string `xml:"SessionNotOnOrAfter,attr"`
+						Class               string `xml:"AuthnContext>AuthnContextClassRef"`
+					} `xml:"AuthnStatement"`
+					Attributes []struct {
+						Name   string   `xml:"Name,attr"`
+						Values []string `xml:"AttributeValue"`
+					} `xml:"AttributeStatement>Attribute"`
+				} `xml:"urn:oasis:names:tc:SAML:2.0:assertion Assertion"`
+			}{}
+
+			require.NoError(t, xml.Unmarshal(raw, response))
+
+			assert.Equal(t, "_request", response.InResponseTo)
+			assert.Equal(t, "https://app.example.com/saml/acs", response.Destination)
+			assert.Equal(t, "https://auth.example.com/api/saml/metadata", response.Issuer)
+			assert.Equal(t, StatusSuccess, response.Status.Code.Value)
+			assert.Equal(t, "https://auth.example.com/api/saml/metadata", response.Assertion.Issuer)
+			assert.Equal(t, NameIDFormatEmail, response.Assertion.Subject.NameID.Format)
+			assert.Equal(t, "john@example.com", response.Assertion.Subject.NameID.Value)
+			assert.Equal(t, "_request", response.Assertion.Subject.Confirmation.Data.InResponseTo)
+			assert.Equal(t, "2023-11-14T22:18:20Z", response.Assertion.Subject.Confirmation.Data.NotOnOrAfter)
+			assert.Equal(t, "https://app.example.com/saml/acs", response.Assertion.Subject.Confirmation.Data.Recipient)
+			assert.Equal(t, "https://app.example.com", response.Assertion.Audience)
+			assert.Equal(t, "_session", response.Assertion.Statement.SessionIndex)
+			assert.Equal(t, "2023-11-15T06:13:20Z", response.Assertion.Statement.SessionNotOnOrAfter)
+			assert.Equal(t, tc.class, response.Assertion.Statement.Class)
+
+			require.Len(t, response.Assertion.Attributes, 4)
+			assert.Equal(t, AttributeUsername, response.Assertion.Attributes[0].Name)
+			assert.Equal(t, []string{"john"}, response.Assertion.Attributes[0].Values)
+			assert.Equal(t, AttributeEmail, response.Assertion.Attributes[1].Name)
+			assert.Equal(t, []string{"john@example.com"}, response.Assertion.Attributes[1].Values)
+			assert.Equal(t, AttributeDisplayName, response.Assertion.Attributes[2].Name)
+			assert.Equal(t, []string{"John <Smith>"}, response.Assertion.Attributes[2].Values)
+			assert.Equal(t, AttributeGroups, response.Assertion.Attributes[3].Name)
+			assert.Equal(t, []string{"admins", "dev"}, response.Assertion.Attributes[3].Values)
+
+			verifyTestSignature(t, &key.PublicKey, raw, "saml:Assertion", response.Assertion.ID)
+
+			if tc.signResponse {
+				verifyTestSignature(t, &key.PublicKey, raw, "samlp:Response", response.ID)
+			} else {
+				assert.Equal(t, 1, bytes.Count(raw, []byte("<ds:Signature ")))
+			}
+		})
+	}
+}
+
+func TestProviderNewErrorResponse(t *testing.T) {
+	provider, _ := newTestProvider(t)
+
+	raw := provider.NewErrorResponse(&url.URL{Scheme: "https", Host: "auth.example.com"}, &AuthnRequest{ID: "_request"}, "https://app.example.com/saml/acs", StatusInvalidNameIDPolicy, time.Unix(1700000000, 0))
+
+	assert.Contains(t, string(raw), `Destination="https://app.example.com/saml/acs"`)
+	assert.Contains(t, string(raw), `InResponseTo="_request"`)
+	assert.Contains(t, string(raw), `IssueInstant="2023-11-14T22:13:20Z"`)
+	assert.Contains(t, string(raw), `<saml:Issuer xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">https://auth.example.com/api/saml/metadata</saml:Issuer>`)
+	assert.Contains(t, string(raw), `<samlp:StatusCode Value="`+StatusInvalidNameIDPolicy+`"></samlp:StatusCode>`)
+	assert.NotContains(t, string(raw), "ds:Signature")
+}
+
+func TestProviderNewLogoutResponseURL(t *testing.T) {
+	provider, key := newTestProvider(t)
+
+	sp, err := provider.GetServiceProvider("https://app.example.com")
+	require.NoError(t, err)
+
+	redirect, err := provider.NewLogoutResponseURL(&url.URL{Scheme: "https", Host: "auth.example.com"}, sp, &LogoutRequest{ID: "_logout"}, StatusSuccess, "state", time.Unix(1700000000, 0))
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://app.example.com/saml/slo", (&url.URL{Scheme: redirect.Scheme, Host: redirect.Host, Path: redirect.Path}).String())
+
+	query := redirect.Query()
+
+	assert.Equal(t, "state", query.Get(FormParameterRelayState))
+	assert.Equal(t, AlgorithmRSASHA256, query.Get(FormParameterSigAlg))
+
+	raw, err := DecodeRedirect([]byte(query.Get(FormParameterSAMLResponse)))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(raw), `InResponseTo="_logout"`)
+	assert.Contains(t, string(raw), `<samlp:StatusCode Value="`+StatusSuccess+`">`)
+
+	signature, err := base64.StdEncoding.DecodeString(query.Get(FormParameterSignature))
+	require.NoError(t, err)
+
+	signed := redirect.RawQuery[:bytes.LastIndex([]byte(redirect.RawQuery), []byte("&"+FormParameterSignature+"="))]
+
+	digest := sha256.Sum256([]byte(signed))
+
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+	sp.SingleLogoutServiceURL = ""
+
+	_, err = provider.NewLogoutResponseURL(&url.URL{Scheme: "https", Host: "auth.example.com"}, sp, &LogoutRequest{ID: "_logout"}, StatusSuccess, "", time.Unix(1700000000, 0))
+	assert.EqualError(t, err, "the service provider 'https://app.example.com' does not have a single logout service url")
+}
+
+func TestServiceProviderAuthnRequest(t *testing.T) {
+	sp := &ServiceProvider{
+		EntityID:                     "https://app.example.com",
+		AssertionConsumerServiceURLs: []string{"https://app.example.com/saml/acs", "https://app.example.com/saml/acs2"},
+		NameIDFormat:                 NameIDFormatPersistent,
+	}
+
+	testCases := []struct {
+		name           string
+		have           *AuthnRequest
+		expectedACS    string
+		expectedFormat string
+		errACS         string
+		errFormat      string
+	}{
+		{
+			"ShouldUseDefaults",
+			&AuthnRequest{},
+			"https://app.example.com/saml/acs",
+			NameIDFormatPersistent,
+			"",
+			"",
+		},
+		{
+			"ShouldUseRequested",
+			&AuthnRequest{AssertionConsumerServiceURL: "https://app.example.com/saml/acs2", ProtocolBinding: BindingHTTPPost, NameIDPolicy: &NameIDPolicy{Format: NameIDFormatPersistent}},
+			"https://app.example.com/saml/acs2",
+			NameIDFormatPersistent,
+			"",
+			"",
+		},
+		{
+			"ShouldErrorUnregistered",
+			&AuthnRequest{AssertionConsumerServiceURL: "https://evil.example.com/saml/acs", NameIDPolicy: &NameIDPolicy{Format: NameIDFormatEmail}},
+			"",
+			"",
+			"the assertion consumer service url 'https://evil.example.com/saml/acs' is not registered for the service provider 'https://app.example.com'",
+			"the name identifier format 'urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress' is not configured for the service provider 'https://app.example.com'",
+		},
+		{
+			"ShouldErrorBinding",
+			&AuthnRequest{ProtocolBinding: BindingHTTPRedirect, NameIDPolicy: &NameIDPolicy{Format: NameIDFormatUnspecified}},
+			"",
+			NameIDFormatPersistent,
+			"the protocol binding 'urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect' is not supported",
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			acs, err := sp.GetAssertionConsumerServiceURL(tc.have)
+
+			if tc.errACS == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errACS)
+			}
+
+			assert.Equal(t, tc.expectedACS, acs)
+
+			format, err := sp.GetNameIDFormat(tc.have)
+
+			if tc.errFormat == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.errFormat)
+			}
+
+			assert.Equal(t, tc.expectedFormat, format)
+		})
+	}
+}
+
+func TestServiceProviderVerifyRedirectSignature(t *testing.T) {
+	certificate, key := newTestCertificate(t, "app.example.com")
+	_, other := newTestCertificate(t, "other.example.com")
+
+	sign := func(key *rsa.PrivateKey, hash crypto.Hash, octets string) string {
+		h := hash.New()
+
+		h.Write([]byte(octets))
+
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, hash, h.Sum(nil))
+		require.NoError(t, err)
+
+		return octets + "&" + url.Values{FormParameterSignature: []string{base64.StdEncoding.EncodeToString(signature)}}.Encode()
+	}
+
+	request := "SAMLRequest=" + url.QueryEscape("fZA9a8MwEIb/ir+e/Q==")
+	sigAlgSHA256 := "SigAlg=" + url.QueryEscape(AlgorithmRSASHA256)
+	sigAlgSHA512 := "SigAlg=" + url.QueryEscape(AlgorithmRSASHA512)
+
+	testCases := []struct {
+		name        string
+		certificate *x509.Certificate
+		query       string
+		err         string
+	}{
+		{
+			"ShouldVerify",
+			certificate,
+			sign(key, crypto.SHA256, request+"&RelayState=state&"+sigAlgSHA256),
+			"",
+		},
+		{
+			"ShouldVerifyWithoutRelayState",
+			certificate,
+			sign(key, crypto.SHA256, request+"&"+sigAlgSHA256),
+			"",
+		},
+		{
+			"ShouldVerifySHA512",
+			certificate,
+			sign(key, crypto.SHA512, request+"&RelayState=state&"+sigAlgSHA512),
+			"",
+		},
+		{
+			"ShouldErrorNoCertificate",
+			nil,
+			sign(key, crypto.SHA256, request+"&"+sigAlgSHA256),
+			"the service provider 'https://app.example.com' does not have a certificate to verify the signature with",
+		},
+		{
+			"ShouldErrorUnsigned",
+			certificate,
+			request + "&RelayState=state",
+			"error verifying the signature: the message is not signed",
+		},
+		{
+			"ShouldErrorNoSignatureAlgorithm",
+			certificate,
+			sign(key, crypto.SHA256, request),
+			"error verifying the signature: the message is not signed",
+		},
+		{
+			"ShouldErrorNoMessage",
+			certificate,
+			sign(key, crypto.SHA256, "RelayState=state&"+sigAlgSHA256),
+			"error verifying the signature: the 'SAMLRequest' parameter is required",
+		},
+		{
+			"ShouldErrorDuplicateMessage",
+			certificate,
+			"SAML%52equest=abc&" + sign(key, crypto.SHA256, request+"&"+sigAlgSHA256),
+			"error verifying the signature: the 'SAMLRequest' parameter must only be sent once",
+		},
+		{
+			"ShouldErrorTamperedRelayState",
+			certificate,
+			strings.Replace(sign(key, crypto.SHA256, request+"&RelayState=state&"+sigAlgSHA256), "RelayState=state", "RelayState=other", 1),
+			"error verifying the signature: the signature is invalid",
+		},
+		{
+			"ShouldErrorWrongKey",
+			certificate,
+			sign(other, crypto.SHA256, request+"&"+sigAlgSHA256),
+			"error verifying the signature: the signature is invalid",
+		},
+		{
+			"ShouldErrorAlgorithmMismatch",
+			certificate,
+			sign(key, crypto.SHA256, request+"&"+sigAlgSHA512),
+			"error verifying the signature: the signature is invalid",
+		},
+		{
+			"ShouldErrorUnsupportedAlgorithm",
+			certificate,
+			sign(key, crypto.SHA256, request+"&SigAlg="+url.QueryEscape("http://www.w3.org/2000/09/xmldsig#rsa-sha1")),
+			"error verifying the signature: the signature algorithm 'http://www.w3.org/2000/09/xmldsig#rsa-sha1' is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sp := &ServiceProvider{EntityID: "https://app.example.com", Certificate: tc.certificate}
+
+			err := sp.VerifyRedirectSignature([]byte(tc.query), FormParameterSAMLRequest)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package saml
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	// Register the hash functions used by the signature algorithms.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// signer signs the SAML messages with the RSA private key of the identity provider.
+type signer struct {
+	key         *rsa.PrivateKey
+	certificate []byte
+
+	hash      crypto.Hash
+	algorithm string
+	digest    string
+}
+
+func newSigner(key *rsa.PrivateKey, certificate []byte, alg string) (s *signer, err error) {
+	s = &signer{
+		key:         key,
+		certificate: certificate,
+	}
+
+	switch alg {
+	case SigningAlgRSAUsingSHA256, "":
+		s.hash, s.algorithm, s.digest = crypto.SHA256, AlgorithmRSASHA256, AlgorithmDigestSHA256
+	case SigningAlgRSAUsingSHA512:
+		s.hash, s.algorithm, s.digest = crypto.SHA512, AlgorithmRSASHA512, AlgorithmDigestSHA512
+	default:
+		return nil, fmt.Errorf("error creating the signer: the signature algorithm '%s' is not supported", alg)
+	}
+
+	return s, nil
+}
+
+// Sign signs the raw octets with the private key and returns the signature.
+func (s *signer) Sign(octets []byte) (signature []byte, err error) {
+	h := s.hash.New()
+
+	h.Write(octets)
+
+	return rsa.SignPKCS1v15(rand.Reader, s.key, s.hash, h.Sum(nil))
+}
+
+// SignElement signs the element which has the given ID with an enveloped signature. The signature is inserted as a
+// child of the element directly after the issuer which must be the first child of the element.
+func (s *signer) SignElement(e *element, id string) (err error) {
+	h := s.hash.New()
+
+	h.Write(e.Bytes())
+
+	info := newDSigElement("SignedInfo").Add(
+		newDSigElement("CanonicalizationMethod").Attr("Algorithm", AlgorithmExclusiveCanonicalization),
+		newDSigElement("SignatureMethod").Attr("Algorithm", s.algorithm),
+		newDSigElement("Reference").Attr("URI", "#"+id).Add(
+			newDSigElement("Transforms").Add(
+				newDSigElement("Transform").Attr("Algorithm", AlgorithmEnvelopedSignature),
+				newDSigElement("Transform").Attr("Algorithm", AlgorithmExclusiveCanonicalization),
+			),
+			newDSigElement("DigestMethod").Attr("Algorithm", s.digest),
+			newDSigElement("DigestValue").Text(base64.StdEncoding.EncodeToString(h.Sum(nil))),
+		),
+	)
+
+	// The canonical form of the SignedInfo element when it's canonicalized on its own declares the namespace of its
+	// prefix as it has no ancestors, whereas within the document it's declared by the Signature element.
+	info.Namespace(prefixDSig, NamespaceDSig)
+
+	var signature []byte
+
+	if signature, err = s.Sign(info.Bytes()); err != nil {
+		return fmt.Errorf("error signing element with id '%s': %w", id, err)
+	}
+
+	delete(info.namespaces, prefixDSig)
+
+	e.Insert(1, newDSigElement("Signature").Namespace(prefixDSig, NamespaceDSig).Add(
+		info,
+		newDSigElement("SignatureValue").Text(base64.StdEncoding.EncodeToString(signature)),
+		s.KeyInfo(),
+	))
+
+	return nil
+}
+
+// KeyInfo returns the ds:KeyInfo element with the signing certificate.
+func (s *signer) KeyInfo() *element {
+	return newDSigElement("KeyInfo").Add(
+		newDSigElement("X509Data").Add(
+			newDSigElement("X509Certificate").Text(base64.StdEncoding.EncodeToString(s.certificate)),
+		),
+	)
+}
+
+// verifyRedirectSignature verifies the signature of a message sent with the HTTP-Redirect binding. The signature is
+// calculated over the URL encoded parameters exactly as they were sent so they're taken from the raw query string
+// rather than being encoded again. Each of the parameters must only be sent once so the verified parameters are always
+// the parameters which are decoded by the handler.
+func verifyRedirectSignature(certificate *x509.Certificate, query, parameter string) (err error) {
+	var (
+		parameters = map[string]string{}
+		values     = map[string]string{}
+	)
+
+	for _, pair := range strings.Split(query, "&") {
+		key, value, _ := strings.Cut(pair, "=")
+
+		if key, err = url.QueryUnescape(key); err != nil {
+			return fmt.Errorf("error verifying the signature: error decoding the parameters: %w", err)
+		}
+
+		switch key {
+		case parameter, FormParameterRelayState, FormParameterSigAlg, FormParameterSignature:
+			if _, ok := parameters[key]; ok {
+				return fmt.Errorf("error verifying the signature: the '%s' parameter must only be sent once", key)
+			}
+
+			parameters[key], values[key] = pair, value
+		}
+	}
+
+	switch {
+	case parameters[parameter] == "":
+		return fmt.Errorf("error verifying the signature: the '%s' parameter is required", parameter)
+	case parameters[FormParameterSigAlg] == "" || parameters[FormParameterSignature] == "":
+		return errors.New("error verifying the signature: the message is not signed")
+	}
+
+	var algorithm, encoded string
+
+	if algorithm, err = url.QueryUnescape(values[FormParameterSigAlg]); err != nil {
+		return fmt.Errorf("error verifying the signature: error decoding the signature algorithm: %w", err)
+	}
+
+	var hash crypto.Hash
+
+	switch algorithm {
+	case AlgorithmRSASHA256:
+		hash = crypto.SHA256
+	case AlgorithmRSASHA512:
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("error verifying the signature: the signature algorithm '%s' is not supported", algorithm)
+	}
+
+	key, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("error verifying the signature: the certificate does not have an RSA public key")
+	}
+
+	if encoded, err = url.QueryUnescape(values[FormParameterSignature]); err != nil {
+		return fmt.Errorf("error verifying the signature: error decoding the signature: %w", err)
+	}
+
+	var signature []byte
+
+	if signature, err = decodeBase64([]byte(encoded)); err != nil {
+		return fmt.Errorf("error verifying the signature: %w", err)
+	}
+
+	octets := parameters[parameter]
+
+	if relayState, ok := parameters[FormParameterRelayState]; ok {
+		octets += "&" + relayState
+	}
+
+	octets += "&" + parameters[FormParameterSigAlg]
+
+	h := hash.New()
+
+	h.Write([]byte(octets))
+
+	if err = rsa.VerifyPKCS1v15(key, hash, h.Sum(nil), signature); err != nil {
+		return errors.New("error verifying the signature: the signature is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package saml
+
+import (
+	"crypto/x509"
+	"encoding/xml"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+)
+
+// AuthnRequest is a SAML 2.0 AuthnRequest sent by a service provider.
+type AuthnRequest struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+
+	ID                          string    `xml:"ID,attr"`
+	Version                     string    `xml:"Version,attr"`
+	IssueInstant                time.Time `xml:"IssueInstant,attr"`
+	Destination                 string    `xml:"Destination,attr"`
+	AssertionConsumerServiceURL string    `xml:"AssertionConsumerServiceURL,attr"`
+	ProtocolBinding             string    `xml:"ProtocolBinding,attr"`
+	ForceAuthn                  bool      `xml:"ForceAuthn,attr"`
+	IsPassive                   bool      `xml:"IsPassive,attr"`
+
+	Issuer       string        `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	NameIDPolicy *NameIDPolicy `xml:"urn:oasis:names:tc:SAML:2.0:protocol NameIDPolicy"`
+
+	// Raw is the raw XML of the request.
+	Raw []byte `xml:"-"`
+}
+
+// NameIDPolicy is the name identifier policy of an AuthnRequest.
+type NameIDPolicy struct {
+	Format      string `xml:"Format,attr"`
+	AllowCreate bool   `xml:"AllowCreate,attr"`
+}
+
+// LogoutRequest is a SAML 2.0 LogoutRequest sent by a service provider.
+type LogoutRequest struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:protocol LogoutRequest"`
+
+	ID           string    `xml:"ID,attr"`
+	Version      string    `xml:"Version,attr"`
+	IssueInstant time.Time `xml:"IssueInstant,attr"`
+	Destination  string    `xml:"Destination,attr"`
+
+	Issuer         string   `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	NameID         string   `xml:"urn:oasis:names:tc:SAML:2.0:assertion NameID"`
+	SessionIndexes []string `xml:"urn:oasis:names:tc:SAML:2.0:protocol SessionIndex"`
+}
+
+// EntityDescriptor is the SAML 2.0 metadata of a service provider.
+type EntityDescriptor struct {
+	XMLName xml.Name `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+
+	EntityID        string           `xml:"entityID,attr"`
+	SPSSODescriptor *SPSSODescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata SPSSODescriptor"`
+}
+
+// SPSSODescriptor is the service provider role descriptor of the SAML 2.0 metadata of a service provider.
+type SPSSODescriptor struct {
+	KeyDescriptors            []KeyDescriptor `xml:"urn:oasis:names:tc:SAML:2.0:metadata KeyDescriptor"`
+	AssertionConsumerServices []Endpoint      `xml:"urn:oasis:names:tc:SAML:2.0:metadata AssertionConsumerService"`
+	SingleLogoutServices      []Endpoint      `xml:"urn:oasis:names:tc:SAML:2.0:metadata SingleLogoutService"`
+}
+
+// KeyDescriptor is a key of a role descriptor in the SAML 2.0 metadata. The key is used for both signing and
+// encryption when the use is not specified.
+type KeyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+// Endpoint is an endpoint in the SAML 2.0 metadata.
+type Endpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// ServiceProvider is a registered SAML 2.0 service provider.
+type ServiceProvider struct {
+	EntityID string
+	Name     string
+
+	AssertionConsumerServiceURLs []string
+	SingleLogoutServiceURL       string
+
+	// Certificate is the certificate with the public key used to verify the signatures of the logout requests, it's nil
+	// when neither the configuration nor the metadata has one.
+	Certificate *x509.Certificate
+
+	NameIDFormat string
+	Policy       authorization.Level
+	SignResponse bool
+}
+
+// Assertion describes the subject and authentication of an assertion issued to a service provider.
+type Assertion struct {
+	// Request is the AuthnRequest the assertion is issued in response to.
+	Request *AuthnRequest
+
+	// Destination is the assertion consumer service URL the response is sent to.
+	Destination string
+
+	NameID       string
+	NameIDFormat string
+	SessionIndex string
+
+	Username    string
+	DisplayName string
+	Emails      []string
+	Groups      []string
+
+	Level           authorization.Level
+	AuthenticatedAt time.Time
+	IssuedAt        time.Time
+}
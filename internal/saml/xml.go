@@ -0,0 +1,169 @@
+package saml
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+// element is a XML element which is always rendered in its exclusive canonical form so the rendered bytes of an
+// element can be signed directly. To ensure the rendered output is canonical the elements must only declare the
+// namespaces of the prefixes they visibly utilize when they're not already declared by one of their ancestors, must not
+// use prefixed attributes, and must not mix text and child elements.
+type element struct {
+	name       string
+	namespaces map[string]string
+	attributes map[string]string
+	children   []*element
+	text       string
+}
+
+func newElement(name string) *element {
+	return &element{
+		name:       name,
+		namespaces: map[string]string{},
+		attributes: map[string]string{},
+	}
+}
+
+// Namespace declares a namespace prefix on the element.
+func (e *element) Namespace(prefix, uri string) *element {
+	e.namespaces[prefix] = uri
+
+	return e
+}
+
+// Attr sets an attribute on the element.
+func (e *element) Attr(name, value string) *element {
+	e.attributes[name] = value
+
+	return e
+}
+
+// Text sets the text content of the element.
+func (e *element) Text(text string) *element {
+	e.text = text
+
+	return e
+}
+
+// Add appends child elements to the element.
+func (e *element) Add(children ...*element) *element {
+	e.children = append(e.children, children...)
+
+	return e
+}
+
+// Insert inserts a child element at the given index.
+func (e *element) Insert(index int, child *element) *element {
+	if index >= len(e.children) {
+		return e.Add(child)
+	}
+
+	e.children = append(e.children[:index], append([]*element{child}, e.children[index:]...)...)
+
+	return e
+}
+
+// Bytes returns the canonical form of the element.
+func (e *element) Bytes() []byte {
+	buf := &bytes.Buffer{}
+
+	e.write(buf)
+
+	return buf.Bytes()
+}
+
+// Document returns the element as a XML document with the XML declaration.
+func (e *element) Document() []byte {
+	buf := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>`)
+
+	e.write(buf)
+
+	return buf.Bytes()
+}
+
+func (e *element) write(buf *bytes.Buffer) {
+	buf.WriteByte('<')
+	buf.WriteString(e.name)
+
+	for _, prefix := range sortedKeys(e.namespaces) {
+		if prefix == "" {
+			buf.WriteString(` xmlns="`)
+		} else {
+			buf.WriteString(` xmlns:`)
+			buf.WriteString(prefix)
+			buf.WriteString(`="`)
+		}
+
+		buf.WriteString(escapeAttribute(e.namespaces[prefix]))
+		buf.WriteByte('"')
+	}
+
+	for _, name := range sortedKeys(e.attributes) {
+		buf.WriteByte(' ')
+		buf.WriteString(name)
+		buf.WriteString(`="`)
+		buf.WriteString(escapeAttribute(e.attributes[name]))
+		buf.WriteByte('"')
+	}
+
+	buf.WriteByte('>')
+
+	if len(e.children) == 0 {
+		buf.WriteString(escapeText(e.text))
+	}
+
+	for _, child := range e.children {
+		child.write(buf)
+	}
+
+	buf.WriteString("</")
+	buf.WriteString(e.name)
+	buf.WriteByte('>')
+}
+
+func sortedKeys(values map[string]string) (keys []string) {
+	keys = make([]string, 0, len(values))
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+var (
+	replacerText      = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	replacerAttribute = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(value string) string {
+	return replacerText.Replace(value)
+}
+
+func escapeAttribute(value string) string {
+	return replacerAttribute.Replace(value)
+}
+
+func qualified(prefix, name string) string {
+	return prefix + ":" + name
+}
+
+func newAssertionElement(name string) *element {
+	return newElement(qualified(prefixAssertion, name))
+}
+
+func newProtocolElement(name string) *element {
+	return newElement(qualified(prefixProtocol, name))
+}
+
+func newMetadataElement(name string) *element {
+	return newElement(qualified(prefixMetadata, name))
+}
+
+func newDSigElement(name string) *element {
+	return newElement(qualified(prefixDSig, name))
+}
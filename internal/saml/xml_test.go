@@ -0,0 +1,51 @@
+package saml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElementBytes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     *element
+		expected string
+	}{
+		{
+			"ShouldRenderEmptyElement",
+			newAssertionElement("Issuer"),
+			`<saml:Issuer></saml:Issuer>`,
+		},
+		{
+			"ShouldRenderNamespacesBeforeSortedAttributes",
+			newProtocolElement("Response").Attr("Version", "2.0").Attr("ID", "_1").Namespace(prefixProtocol, NamespaceProtocol).Namespace("", "urn:default").Attr("Destination", "https://app.example.com"),
+			`<samlp:Response xmlns="urn:default" xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" Destination="https://app.example.com" ID="_1" Version="2.0"></samlp:Response>`,
+		},
+		{
+			"ShouldEscapeText",
+			newAssertionElement("AttributeValue").Text("a & b < c > d \"e\" 'f'\r\n"),
+			"<saml:AttributeValue>a &amp; b &lt; c &gt; d \"e\" 'f'&#xD;\n</saml:AttributeValue>",
+		},
+		{
+			"ShouldEscapeAttributes",
+			newAssertionElement("Attribute").Attr("Name", "a & b < c > d \"e\" 'f'\t\r\n"),
+			`<saml:Attribute Name="a &amp; b &lt; c > d &quot;e&quot; 'f'&#x9;&#xD;&#xA;"></saml:Attribute>`,
+		},
+		{
+			"ShouldRenderChildren",
+			newAssertionElement("Subject").Add(newAssertionElement("NameID").Text("john")).Insert(0, newAssertionElement("Issuer")).Insert(5, newAssertionElement("Conditions")),
+			`<saml:Subject><saml:Issuer></saml:Issuer><saml:NameID>john</saml:NameID><saml:Conditions></saml:Conditions></saml:Subject>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, string(tc.have.Bytes()))
+		})
+	}
+}
+
+func TestElementDocument(t *testing.T) {
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?><md:EntityDescriptor entityID="https://auth.example.com"></md:EntityDescriptor>`, string(newMetadataElement("EntityDescriptor").Attr("entityID", "https://auth.example.com").Document()))
+}
@@ -0,0 +1,82 @@
+package scim
+
+const (
+	// ContentType is the media type of the SCIM 2.0 protocol.
+	ContentType = "application/scim+json"
+)
+
+const (
+	// SchemaUser is the URN of the core user schema.
+	SchemaUser = "urn:ietf:params:scim:schemas:core:2.0:User"
+
+	// SchemaGroup is the URN of the core group schema.
+	SchemaGroup = "urn:ietf:params:scim:schemas:core:2.0:Group"
+
+	// SchemaListResponse is the URN of the list response message.
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// SchemaPatchOp is the URN of the patch operation message.
+	SchemaPatchOp = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+
+	// SchemaError is the URN of the error message.
+	SchemaError = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// SchemaServiceProviderConfig is the URN of the service provider configuration schema.
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// SchemaResourceType is the URN of the resource type schema.
+	SchemaResourceType = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+const (
+	// ResourceTypeUser is the name of the user resource type.
+	ResourceTypeUser = "User"
+
+	// ResourceTypeGroup is the name of the group resource type.
+	ResourceTypeGroup = "Group"
+)
+
+// The error types defined by RFC7644 Section 3.12.
+const (
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeUniqueness    = "uniqueness"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeInvalidValue  = "invalidValue"
+	ErrorTypeMutability    = "mutability"
+)
+
+const (
+	// OperationAdd is the add patch operation.
+	OperationAdd = "add"
+
+	// OperationRemove is the remove patch operation.
+	OperationRemove = "remove"
+
+	// OperationReplace is the replace patch operation.
+	OperationReplace = "replace"
+)
+
+const (
+	attributeID          = "id"
+	attributeExternalID  = "externalid"
+	attributeUserName    = "username"
+	attributeDisplayName = "displayname"
+	attributeActive      = "active"
+	attributeName        = "name"
+	attributeGivenName   = "name.givenname"
+	attributeFamilyName  = "name.familyname"
+	attributeEmails      = "emails"
+	attributeMembers     = "members"
+
+	subAttributeValue   = "value"
+	subAttributeType    = "type"
+	subAttributePrimary = "primary"
+)
+
+const (
+	operatorEqual = "eq"
+
+	prefixSchemaURN = "urn:"
+)
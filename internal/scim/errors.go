@@ -0,0 +1,21 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// NewError returns a new SCIM 2.0 error message with the given HTTP status code.
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+func newErrorf(scimType, format string, a ...any) *Error {
+	return NewError(http.StatusBadRequest, scimType, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,89 @@
+package scim
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ParseFilter parses a SCIM 2.0 filter which is an equality comparison of one of the given attributes, for example
+// `userName eq "john"`. The attribute names are matched case-insensitively and the attribute of the returned Filter is
+// the matching attribute as given. An empty filter returns a nil Filter.
+func ParseFilter(filter string, attributes ...string) (f *Filter, err error) {
+	if filter = strings.TrimSpace(filter); filter == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(filter, " ", 3)
+
+	if len(parts) != 3 {
+		return nil, newErrorf(ErrorTypeInvalidFilter, "The filter '%s' is not a valid comparison.", filter)
+	}
+
+	if !strings.EqualFold(parts[1], operatorEqual) {
+		return nil, newErrorf(ErrorTypeInvalidFilter, "The filter '%s' uses the operator '%s' but only the 'eq' operator is supported.", filter, parts[1])
+	}
+
+	f = &Filter{}
+
+	for _, attribute := range attributes {
+		if strings.EqualFold(parts[0], attribute) {
+			f.Attribute = attribute
+
+			break
+		}
+	}
+
+	if f.Attribute == "" {
+		return nil, newErrorf(ErrorTypeInvalidFilter, "The filter '%s' uses the attribute '%s' which can't be filtered.", filter, parts[0])
+	}
+
+	if err = json.Unmarshal([]byte(strings.TrimSpace(parts[2])), &f.Value); err != nil {
+		return nil, newErrorf(ErrorTypeInvalidFilter, "The filter '%s' does not have a valid string value.", filter)
+	}
+
+	return f, nil
+}
+
+type path struct {
+	attribute    string
+	filter       *Filter
+	subAttribute string
+}
+
+// parsePath parses the path of a patch operation. The attribute and sub-attribute of the returned path are lower case
+// and have the schema URN of the resource removed.
+func parsePath(value, schema string) (p path, err error) {
+	value = strings.TrimSpace(value)
+
+	if len(value) > len(schema) && strings.EqualFold(value[:len(schema)+1], schema+":") {
+		value = value[len(schema)+1:]
+	}
+
+	start := strings.Index(value, "[")
+
+	if start == -1 {
+		return path{attribute: strings.ToLower(value)}, nil
+	}
+
+	end := strings.Index(value, "]")
+
+	if end < start {
+		return p, newErrorf(ErrorTypeInvalidPath, "The path '%s' has an invalid value filter.", value)
+	}
+
+	p.attribute = strings.ToLower(value[:start])
+
+	if p.filter, err = ParseFilter(value[start+1:end], subAttributeValue, subAttributeType); err != nil || p.filter == nil {
+		return p, newErrorf(ErrorTypeInvalidPath, "The path '%s' has an invalid value filter.", value)
+	}
+
+	if rest := value[end+1:]; rest != "" {
+		if rest[0] != '.' {
+			return p, newErrorf(ErrorTypeInvalidPath, "The path '%s' is not valid.", value)
+		}
+
+		p.subAttribute = strings.ToLower(rest[1:])
+	}
+
+	return p, nil
+}
@@ -0,0 +1,155 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected *Filter
+		err      string
+	}{
+		{
+			"ShouldParseEmpty",
+			"",
+			nil,
+			"",
+		},
+		{
+			"ShouldParseUserName",
+			`userName eq "john"`,
+			&Filter{Attribute: "userName", Value: "john"},
+			"",
+		},
+		{
+			"ShouldParseCaseInsensitive",
+			`USERNAME EQ "john smith"`,
+			&Filter{Attribute: "userName", Value: "john smith"},
+			"",
+		},
+		{
+			"ShouldParseEscapedValue",
+			`externalId eq "a\"b"`,
+			&Filter{Attribute: "externalId", Value: `a"b`},
+			"",
+		},
+		{
+			"ShouldErrOperator",
+			`userName co "john"`,
+			nil,
+			"invalidFilter: The filter 'userName co \"john\"' uses the operator 'co' but only the 'eq' operator is supported.",
+		},
+		{
+			"ShouldErrAttribute",
+			`displayName eq "john"`,
+			nil,
+			"invalidFilter: The filter 'displayName eq \"john\"' uses the attribute 'displayName' which can't be filtered.",
+		},
+		{
+			"ShouldErrUnquotedValue",
+			`userName eq john`,
+			nil,
+			"invalidFilter: The filter 'userName eq john' does not have a valid string value.",
+		},
+		{
+			"ShouldErrNotComparison",
+			`userName pr`,
+			nil,
+			"invalidFilter: The filter 'userName pr' is not a valid comparison.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseFilter(tc.have, "userName", "externalId")
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, actual)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected path
+		err      string
+	}{
+		{
+			"ShouldParseAttribute",
+			"userName",
+			path{attribute: "username"},
+			"",
+		},
+		{
+			"ShouldParseSubAttribute",
+			"name.givenName",
+			path{attribute: "name.givenname"},
+			"",
+		},
+		{
+			"ShouldParseSchemaURN",
+			"urn:ietf:params:scim:schemas:core:2.0:User:userName",
+			path{attribute: "username"},
+			"",
+		},
+		{
+			"ShouldParseValueFilter",
+			`emails[type eq "work"].value`,
+			path{attribute: "emails", filter: &Filter{Attribute: "type", Value: "work"}, subAttribute: "value"},
+			"",
+		},
+		{
+			"ShouldParseValueFilterWithoutSubAttribute",
+			`emails[value eq "john@example.com"]`,
+			path{attribute: "emails", filter: &Filter{Attribute: "value", Value: "john@example.com"}},
+			"",
+		},
+		{
+			"ShouldErrUnterminatedValueFilter",
+			`emails[type eq "work"`,
+			path{},
+			`invalidPath: The path 'emails[type eq "work"' has an invalid value filter.`,
+		},
+		{
+			"ShouldErrInvalidValueFilter",
+			`emails[primary eq "true"]`,
+			path{},
+			`invalidPath: The path 'emails[primary eq "true"]' has an invalid value filter.`,
+		},
+		{
+			"ShouldErrInvalidRemainder",
+			`emails[type eq "work"]value`,
+			path{},
+			`invalidPath: The path 'emails[type eq "work"]value' is not valid.`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := parsePath(tc.have, SchemaUser)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package scim
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ApplyPatch applies the operations of a patch operation message to the user.
+func (u *User) ApplyPatch(operations []PatchOperation) (err error) {
+	return applyPatch(operations, SchemaUser, u.apply)
+}
+
+// ApplyPatch applies the operations of a patch operation message to the group.
+func (g *Group) ApplyPatch(operations []PatchOperation) (err error) {
+	return applyPatch(operations, SchemaGroup, g.apply)
+}
+
+// applyPatch applies the operations using the apply func of the resource. The attributes of schema extensions such as
+// the enterprise user extension are not supported and are ignored, so identity providers which send them by default
+// can still provision the resource.
+func applyPatch(operations []PatchOperation, schema string, apply func(op string, p path, value json.RawMessage) error) (err error) {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+
+		switch op {
+		case OperationAdd, OperationReplace, OperationRemove:
+		default:
+			return newErrorf(ErrorTypeInvalidSyntax, "The operation '%s' is not a valid patch operation.", operation.Op)
+		}
+
+		if operation.Path != "" {
+			var p path
+
+			if p, err = parsePath(operation.Path, schema); err != nil {
+				return err
+			}
+
+			if strings.HasPrefix(p.attribute, prefixSchemaURN) {
+				continue
+			}
+
+			if err = apply(op, p, operation.Value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if op == OperationRemove {
+			return newErrorf(ErrorTypeNoTarget, "The remove operation must have a path.")
+		}
+
+		values := map[string]json.RawMessage{}
+
+		if err = json.Unmarshal(operation.Value, &values); err != nil {
+			return newErrorf(ErrorTypeInvalidValue, "The value of the %s operation without a path must be an object.", op)
+		}
+
+		for key, value := range values {
+			switch key = strings.ToLower(key); key {
+			case attributeID, "schemas", "meta":
+				continue
+			}
+
+			var p path
+
+			if p, err = parsePath(key, schema); err != nil {
+				return err
+			}
+
+			if strings.HasPrefix(p.attribute, prefixSchemaURN) {
+				continue
+			}
+
+			if err = apply(op, p, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (u *User) apply(op string, p path, value json.RawMessage) (err error) {
+	if p.filter != nil && p.attribute != attributeEmails {
+		return newErrorf(ErrorTypeInvalidPath, "The attribute '%s' does not support value filters.", p.attribute)
+	}
+
+	switch p.attribute {
+	case attributeUserName:
+		if op == OperationRemove {
+			return newErrorf(ErrorTypeMutability, "The attribute 'userName' is required and can't be removed.")
+		}
+
+		return unmarshalString(p.attribute, value, &u.UserName)
+	case attributeExternalID:
+		return applyString(op, p.attribute, value, &u.ExternalID)
+	case attributeDisplayName:
+		return applyString(op, p.attribute, value, &u.DisplayName)
+	case attributeActive:
+		if op == OperationRemove {
+			u.Active = nil
+
+			return nil
+		}
+
+		var active bool
+
+		if active, err = unmarshalBool(p.attribute, value); err != nil {
+			return err
+		}
+
+		u.Active = &active
+
+		return nil
+	case attributeName:
+		return u.applyName(op, value)
+	case attributeGivenName, attributeFamilyName:
+		if u.Name == nil {
+			u.Name = &Name{}
+		}
+
+		if p.attribute == attributeGivenName {
+			return applyString(op, p.attribute, value, &u.Name.GivenName)
+		}
+
+		return applyString(op, p.attribute, value, &u.Name.FamilyName)
+	case attributeEmails:
+		return u.applyEmails(op, p, value)
+	case attributeID, attributeMembers:
+		return newErrorf(ErrorTypeMutability, "The attribute '%s' can't be modified.", p.attribute)
+	default:
+		return newErrorf(ErrorTypeInvalidPath, "The attribute '%s' is not supported.", p.attribute)
+	}
+}
+
+func (u *User) applyName(op string, value json.RawMessage) (err error) {
+	if op == OperationRemove {
+		u.Name = nil
+
+		return nil
+	}
+
+	name := Name{}
+
+	if err = json.Unmarshal(value, &name); err != nil {
+		return newErrorf(ErrorTypeInvalidValue, "The value of the attribute 'name' must be an object.")
+	}
+
+	if op == OperationReplace || u.Name == nil {
+		u.Name = &name
+
+		return nil
+	}
+
+	if name.Formatted != "" {
+		u.Name.Formatted = name.Formatted
+	}
+
+	if name.GivenName != "" {
+		u.Name.GivenName = name.GivenName
+	}
+
+	if name.FamilyName != "" {
+		u.Name.FamilyName = name.FamilyName
+	}
+
+	return nil
+}
+
+func (u *User) applyEmails(op string, p path, value json.RawMessage) (err error) {
+	if p.filter == nil {
+		if op == OperationRemove {
+			u.Emails = nil
+
+			return nil
+		}
+
+		var emails []Email
+
+		if err = unmarshalMultiValued(p.attribute, value, &emails); err != nil {
+			return err
+		}
+
+		if op == OperationReplace {
+			u.Emails = emails
+		} else {
+			u.Emails = append(u.Emails, emails...)
+		}
+
+		return nil
+	}
+
+	matches := func(email Email) bool {
+		if p.filter.Attribute == subAttributeType {
+			return strings.EqualFold(email.Type, p.filter.Value)
+		}
+
+		return strings.EqualFold(email.Value, p.filter.Value)
+	}
+
+	if op == OperationRemove {
+		emails := make([]Email, 0, len(u.Emails))
+
+		for _, email := range u.Emails {
+			if !matches(email) {
+				emails = append(emails, email)
+			}
+		}
+
+		u.Emails = emails
+
+		return nil
+	}
+
+	var email Email
+
+	switch p.subAttribute {
+	case "":
+		if err = json.Unmarshal(value, &email); err != nil {
+			return newErrorf(ErrorTypeInvalidValue, "The value of the attribute 'emails' must be an object.")
+		}
+	case subAttributeValue:
+		if err = unmarshalString(p.attribute, value, &email.Value); err != nil {
+			return err
+		}
+	case subAttributeType:
+		if err = unmarshalString(p.attribute, value, &email.Type); err != nil {
+			return err
+		}
+	case subAttributePrimary:
+		if email.Primary, err = unmarshalBool(p.attribute, value); err != nil {
+			return err
+		}
+	default:
+		return newErrorf(ErrorTypeInvalidPath, "The sub-attribute '%s' of the attribute 'emails' is not supported.", p.subAttribute)
+	}
+
+	found := false
+
+	for i := range u.Emails {
+		if !matches(u.Emails[i]) {
+			continue
+		}
+
+		found = true
+
+		switch p.subAttribute {
+		case "":
+			u.Emails[i] = email
+		case subAttributeValue:
+			u.Emails[i].Value = email.Value
+		case subAttributeType:
+			u.Emails[i].Type = email.Type
+		case subAttributePrimary:
+			u.Emails[i].Primary = email.Primary
+		}
+	}
+
+	if !found {
+		if p.filter.Attribute == subAttributeType && email.Type == "" {
+			email.Type = p.filter.Value
+		}
+
+		u.Emails = append(u.Emails, email)
+	}
+
+	return nil
+}
+
+func (g *Group) apply(op string, p path, value json.RawMessage) (err error) {
+	if p.filter != nil && p.attribute != attributeMembers {
+		return newErrorf(ErrorTypeInvalidPath, "The attribute '%s' does not support value filters.", p.attribute)
+	}
+
+	switch p.attribute {
+	case attributeDisplayName:
+		if op == OperationRemove {
+			return newErrorf(ErrorTypeMutability, "The attribute 'displayName' is required and can't be removed.")
+		}
+
+		return unmarshalString(p.attribute, value, &g.DisplayName)
+	case attributeExternalID:
+		return applyString(op, p.attribute, value, &g.ExternalID)
+	case attributeMembers:
+		return g.applyMembers(op, p, value)
+	case attributeID:
+		return newErrorf(ErrorTypeMutability, "The attribute '%s' can't be modified.", p.attribute)
+	default:
+		return newErrorf(ErrorTypeInvalidPath, "The attribute '%s' is not supported.", p.attribute)
+	}
+}
+
+func (g *Group) applyMembers(op string, p path, value json.RawMessage) (err error) {
+	var members []Member
+
+	if p.filter != nil {
+		if op != OperationRemove || p.filter.Attribute != subAttributeValue || p.subAttribute != "" {
+			return newErrorf(ErrorTypeInvalidPath, "The value filter of the attribute 'members' is only supported by the remove operation.")
+		}
+
+		members = []Member{{Value: p.filter.Value}}
+	} else if len(value) != 0 {
+		if err = unmarshalMultiValued(p.attribute, value, &members); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case op == OperationReplace:
+		g.Members = nil
+
+		fallthrough
+	case op == OperationAdd:
+		for _, member := range members {
+			if !containsMember(g.Members, member.Value) {
+				g.Members = append(g.Members, member)
+			}
+		}
+	case len(members) == 0:
+		g.Members = nil
+	default:
+		remaining := make([]Member, 0, len(g.Members))
+
+		for _, member := range g.Members {
+			if !containsMember(members, member.Value) {
+				remaining = append(remaining, member)
+			}
+		}
+
+		g.Members = remaining
+	}
+
+	return nil
+}
+
+func containsMember(members []Member, value string) bool {
+	for _, member := range members {
+		if strings.EqualFold(member.Value, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applyString(op, attribute string, value json.RawMessage, field *string) (err error) {
+	if op == OperationRemove {
+		*field = ""
+
+		return nil
+	}
+
+	return unmarshalString(attribute, value, field)
+}
+
+func unmarshalString(attribute string, value json.RawMessage, field *string) (err error) {
+	if err = json.Unmarshal(value, field); err != nil {
+		return newErrorf(ErrorTypeInvalidValue, "The value of the attribute '%s' must be a string.", attribute)
+	}
+
+	return nil
+}
+
+// unmarshalBool decodes a boolean value. Some identity providers such as Microsoft Entra ID send booleans as strings so
+// both forms are accepted.
+func unmarshalBool(attribute string, value json.RawMessage) (result bool, err error) {
+	if err = json.Unmarshal(value, &result); err == nil {
+		return result, nil
+	}
+
+	var str string
+
+	if err = json.Unmarshal(value, &str); err == nil {
+		if result, err = strconv.ParseBool(str); err == nil {
+			return result, nil
+		}
+	}
+
+	return false, newErrorf(ErrorTypeInvalidValue, "The value of the attribute '%s' must be a boolean.", attribute)
+}
+
+// unmarshalMultiValued decodes a multi-valued attribute which may be given as an array or as a single object.
+func unmarshalMultiValued[T any](attribute string, value json.RawMessage, field *[]T) (err error) {
+	if err = json.Unmarshal(value, field); err == nil {
+		return nil
+	}
+
+	var single T
+
+	if err = json.Unmarshal(value, &single); err != nil {
+		return newErrorf(ErrorTypeInvalidValue, "The value of the attribute '%s' must be an array.", attribute)
+	}
+
+	*field = []T{single}
+
+	return nil
+}
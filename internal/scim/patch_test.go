@@ -0,0 +1,254 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserApplyPatch(t *testing.T) {
+	active := true
+
+	newUser := func() *User {
+		return &User{
+			UserName:    "john",
+			DisplayName: "John Smith",
+			Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+			Emails:      []Email{{Value: "john@example.com", Type: "work", Primary: true}},
+			Active:      &active,
+		}
+	}
+
+	inactive := false
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected *User
+		err      string
+	}{
+		{
+			"ShouldReplaceActiveWithoutPath",
+			`[{"op":"replace","value":{"active":false}}]`,
+			&User{
+				UserName:    "john",
+				DisplayName: "John Smith",
+				Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+				Emails:      []Email{{Value: "john@example.com", Type: "work", Primary: true}},
+				Active:      &inactive,
+			},
+			"",
+		},
+		{
+			"ShouldReplaceActiveString",
+			`[{"op":"Replace","path":"active","value":"False"}]`,
+			&User{
+				UserName:    "john",
+				DisplayName: "John Smith",
+				Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+				Emails:      []Email{{Value: "john@example.com", Type: "work", Primary: true}},
+				Active:      &inactive,
+			},
+			"",
+		},
+		{
+			"ShouldReplaceAttributes",
+			`[{"op":"replace","path":"userName","value":"jsmith"},{"op":"replace","path":"name.familyName","value":"Smythe"},{"op":"add","path":"externalId","value":"00u1"},{"op":"remove","path":"displayName"}]`,
+			&User{
+				UserName:   "jsmith",
+				ExternalID: "00u1",
+				Name:       &Name{GivenName: "John", FamilyName: "Smythe"},
+				Emails:     []Email{{Value: "john@example.com", Type: "work", Primary: true}},
+				Active:     &active,
+			},
+			"",
+		},
+		{
+			"ShouldReplaceEmailWithValueFilter",
+			`[{"op":"replace","path":"emails[type eq \"work\"].value","value":"jsmith@example.com"}]`,
+			&User{
+				UserName:    "john",
+				DisplayName: "John Smith",
+				Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+				Emails:      []Email{{Value: "jsmith@example.com", Type: "work", Primary: true}},
+				Active:      &active,
+			},
+			"",
+		},
+		{
+			"ShouldAddEmailWithValueFilter",
+			`[{"op":"add","path":"emails[type eq \"home\"].value","value":"john@example.org"}]`,
+			&User{
+				UserName:    "john",
+				DisplayName: "John Smith",
+				Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+				Emails:      []Email{{Value: "john@example.com", Type: "work", Primary: true}, {Value: "john@example.org", Type: "home"}},
+				Active:      &active,
+			},
+			"",
+		},
+		{
+			"ShouldRemoveEmailWithValueFilter",
+			`[{"op":"remove","path":"emails[value eq \"john@example.com\"]"}]`,
+			&User{
+				UserName:    "john",
+				DisplayName: "John Smith",
+				Name:        &Name{GivenName: "John", FamilyName: "Smith"},
+				Emails:      []Email{},
+				Active:      &active,
+			},
+			"",
+		},
+		{
+			"ShouldIgnoreExtensionAttributes",
+			`[{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"IT"},{"op":"replace","value":{"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User":{"department":"IT"},"id":"abc"}}]`,
+			newUser(),
+			"",
+		},
+		{
+			"ShouldErrRemoveUserName",
+			`[{"op":"remove","path":"userName"}]`,
+			nil,
+			"mutability: The attribute 'userName' is required and can't be removed.",
+		},
+		{
+			"ShouldErrRemoveWithoutPath",
+			`[{"op":"remove"}]`,
+			nil,
+			"noTarget: The remove operation must have a path.",
+		},
+		{
+			"ShouldErrInvalidOperation",
+			`[{"op":"move","path":"userName"}]`,
+			nil,
+			"invalidSyntax: The operation 'move' is not a valid patch operation.",
+		},
+		{
+			"ShouldErrUnknownAttribute",
+			`[{"op":"replace","path":"nickName","value":"johnny"}]`,
+			nil,
+			"invalidPath: The attribute 'nickname' is not supported.",
+		},
+		{
+			"ShouldErrInvalidActive",
+			`[{"op":"replace","path":"active","value":"maybe"}]`,
+			nil,
+			"invalidValue: The value of the attribute 'active' must be a boolean.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var operations []PatchOperation
+
+			require.NoError(t, json.Unmarshal([]byte(tc.have), &operations))
+
+			user := newUser()
+
+			err := user.ApplyPatch(operations)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, user)
+		})
+	}
+}
+
+func TestGroupApplyPatch(t *testing.T) {
+	newGroup := func() *Group {
+		return &Group{
+			DisplayName: "admins",
+			Members:     []Member{{Value: "a"}, {Value: "b"}},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected *Group
+		err      string
+	}{
+		{
+			"ShouldAddMembers",
+			`[{"op":"add","path":"members","value":[{"value":"b"},{"value":"c"}]}]`,
+			&Group{DisplayName: "admins", Members: []Member{{Value: "a"}, {Value: "b"}, {Value: "c"}}},
+			"",
+		},
+		{
+			"ShouldRemoveMemberWithValueFilter",
+			`[{"op":"remove","path":"members[value eq \"a\"]"}]`,
+			&Group{DisplayName: "admins", Members: []Member{{Value: "b"}}},
+			"",
+		},
+		{
+			"ShouldRemoveMembersWithValue",
+			`[{"op":"remove","path":"members","value":[{"value":"b"}]}]`,
+			&Group{DisplayName: "admins", Members: []Member{{Value: "a"}}},
+			"",
+		},
+		{
+			"ShouldRemoveAllMembers",
+			`[{"op":"remove","path":"members"}]`,
+			&Group{DisplayName: "admins"},
+			"",
+		},
+		{
+			"ShouldReplaceMembers",
+			`[{"op":"replace","path":"members","value":[{"value":"c"}]}]`,
+			&Group{DisplayName: "admins", Members: []Member{{Value: "c"}}},
+			"",
+		},
+		{
+			"ShouldReplaceDisplayNameWithoutPath",
+			`[{"op":"replace","value":{"id":"abc","displayName":"administrators"}}]`,
+			&Group{DisplayName: "administrators", Members: []Member{{Value: "a"}, {Value: "b"}}},
+			"",
+		},
+		{
+			"ShouldErrAddMemberWithValueFilter",
+			`[{"op":"add","path":"members[value eq \"a\"]","value":{"value":"a"}}]`,
+			nil,
+			"invalidPath: The value filter of the attribute 'members' is only supported by the remove operation.",
+		},
+		{
+			"ShouldErrRemoveDisplayName",
+			`[{"op":"remove","path":"displayName"}]`,
+			nil,
+			"mutability: The attribute 'displayName' is required and can't be removed.",
+		},
+		{
+			"ShouldErrInvalidMembers",
+			`[{"op":"add","path":"members","value":"a"}]`,
+			nil,
+			"invalidValue: The value of the attribute 'members' must be an array.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var operations []PatchOperation
+
+			require.NoError(t, json.Unmarshal([]byte(tc.have), &operations))
+
+			group := newGroup()
+
+			err := group.ApplyPatch(operations)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, group)
+		})
+	}
+}
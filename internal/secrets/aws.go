@@ -0,0 +1,342 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewAWSSecretsManagerProviderFromEnvironment returns a new AWSSecretsManagerProvider configured using the
+// 'AWS_REGION' or 'AWS_DEFAULT_REGION', and 'AWS_ENDPOINT_URL_SECRETS_MANAGER' environment variables, and the
+// credentials provided by NewAWSCredentialsProviderFromEnvironment.
+func NewAWSSecretsManagerProviderFromEnvironment() (provider *AWSSecretsManagerProvider) {
+	return NewAWSSecretsManagerProvider(AWSRegionFromEnvironment(), os.Getenv(envAWSEndpoint), defaultAWSCredentialsProvider(), newHTTPClient(nil), clock.New())
+}
+
+// NewAWSSecretsManagerProvider returns a new AWSSecretsManagerProvider. If the endpoint is empty the regional endpoint
+// is used.
+func NewAWSSecretsManagerProvider(region, endpoint string, credentials AWSCredentialsProvider, client *http.Client, clock clock.Provider) (provider *AWSSecretsManagerProvider) {
+	return &AWSSecretsManagerProvider{
+		region:      region,
+		endpoint:    endpoint,
+		credentials: credentials,
+		client:      client,
+		clock:       clock,
+	}
+}
+
+// AWSCredentials are the credentials used to sign requests to AWS. The credentials are static if the expiration is
+// zero, otherwise they're temporary credentials which must be retrieved again before they expire.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expires         time.Time
+}
+
+// Retrieve implements AWSCredentialsProvider for static credentials.
+func (c AWSCredentials) Retrieve(_ context.Context) (credentials AWSCredentials, err error) {
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("the aws credentials must be configured using the '%s' and '%s' environment variables", envAWSAccessKeyID, envAWSSecretAccessKey)
+	}
+
+	return c, nil
+}
+
+func (c AWSCredentials) valid(now time.Time) bool {
+	return c.AccessKeyID != "" && (c.Expires.IsZero() || now.Before(c.Expires.Add(-tokenExpirySkew)))
+}
+
+// AWSSecretsManagerProvider is a Provider for AWS Secrets Manager. References have the format
+// 'aws-sm://<name or arn>?version_stage=<stage>&version_id=<id>#<key>' where the version stage and version id are
+// optional, and the key is only required if the secret string is a JSON object and a single value is required. If the
+// reference is an ARN then the region is determined from the ARN.
+type AWSSecretsManagerProvider struct {
+	region      string
+	endpoint    string
+	credentials AWSCredentialsProvider
+	client      *http.Client
+	clock       clock.Provider
+}
+
+// Fetch the secret value the reference refers to.
+func (p *AWSSecretsManagerProvider) Fetch(ctx context.Context, reference *Reference) (secret string, err error) {
+	var (
+		region      string
+		credentials AWSCredentials
+	)
+
+	if region, err = awsRegion(p.region, reference.Path); err != nil {
+		return "", err
+	}
+
+	if credentials, err = p.credentials.Retrieve(ctx); err != nil {
+		return "", err
+	}
+
+	endpoint := p.endpoint
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf(awsDefaultEndpointTemplate, region)
+	}
+
+	var body []byte
+
+	if body, err = json.Marshal(awsGetSecretValueRequest{
+		SecretID:     reference.Path,
+		VersionStage: reference.Query.Get(awsQueryVersionStage),
+		VersionID:    reference.Query.Get(awsQueryVersionID),
+	}); err != nil {
+		return "", fmt.Errorf("error encoding request to the aws secrets manager api: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body)); err != nil {
+		return "", fmt.Errorf("error creating request to the aws secrets manager api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, awsContentType)
+	req.Header.Set(headerAmzTarget, awsTargetGetSecretValue)
+
+	awsSignRequest(req, body, credentials, region, awsService, p.clock.Now())
+
+	var resp awsGetSecretValueResponse
+
+	if err = do(p.client, req, "aws secrets manager", &resp); err != nil {
+		return "", err
+	}
+
+	if resp.SecretString == nil {
+		return "", fmt.Errorf("the secret does not have a secret string")
+	}
+
+	return secretValueKey(*resp.SecretString, reference.Key)
+}
+
+// AWSRegionFromEnvironment returns the region configured using the 'AWS_REGION' or 'AWS_DEFAULT_REGION' environment
+// variables.
+func AWSRegionFromEnvironment() (region string) {
+	if region = os.Getenv(envAWSRegion); region == "" {
+		region = os.Getenv(envAWSDefaultRegion)
+	}
+
+	return region
+}
+
+// awsRegion returns the region of the identifier if it's an ARN, otherwise the configured region.
+func awsRegion(region string, identifier string) (string, error) {
+	if parts := strings.Split(identifier, ":"); len(parts) > 3 && parts[0] == "arn" {
+		region = parts[3]
+	}
+
+	if region == "" {
+		return "", fmt.Errorf("the aws region must be configured using the '%s' environment variable or the reference must be an arn", envAWSRegion)
+	}
+
+	return region, nil
+}
+
+type awsGetSecretValueRequest struct {
+	SecretID     string `json:"SecretId"`
+	VersionStage string `json:"VersionStage,omitempty"`
+	VersionID    string `json:"VersionId,omitempty"`
+}
+
+type awsGetSecretValueResponse struct {
+	SecretString *string `json:"SecretString"`
+}
+
+// SignAWSRequest signs the request to the service using the AWS Signature Version 4 process with the credentials of the
+// provider. The body must be the exact body of the request.
+func SignAWSRequest(ctx context.Context, req *http.Request, body []byte, provider AWSCredentialsProvider, region, service string, now time.Time) (err error) {
+	var credentials AWSCredentials
+
+	if credentials, err = provider.Retrieve(ctx); err != nil {
+		return err
+	}
+
+	awsSignRequest(req, body, credentials, region, service, now)
+
+	return nil
+}
+
+// NewAWSRDSTokenSourceFromEnvironment returns a new TokenSource which provides the IAM authentication tokens of the user
+// for the Amazon RDS or Aurora database at the address using the credentials provided by
+// NewAWSCredentialsProviderFromEnvironment. If the region is empty the 'AWS_REGION' or 'AWS_DEFAULT_REGION' environment
+// variables are used.
+func NewAWSRDSTokenSourceFromEnvironment(region, address, username string) (source TokenSource) {
+	if region == "" {
+		region = AWSRegionFromEnvironment()
+	}
+
+	return &awsRDSTokenSource{
+		region:      region,
+		address:     address,
+		username:    username,
+		credentials: defaultAWSCredentialsProvider(),
+		clock:       clock.New(),
+	}
+}
+
+// awsRDSTokenSource generates the IAM authentication tokens for a database user which are presigned URLs of the
+// connect action. The tokens are generated locally so they're not cached.
+type awsRDSTokenSource struct {
+	region      string
+	address     string
+	username    string
+	credentials AWSCredentialsProvider
+	clock       clock.Provider
+}
+
+// Token implements TokenSource.
+func (s *awsRDSTokenSource) Token(ctx context.Context) (token string, err error) {
+	if s.region == "" {
+		return "", fmt.Errorf("the aws region must be configured using either the region option or the '%s' environment variable", envAWSRegion)
+	}
+
+	var credentials AWSCredentials
+
+	if credentials, err = s.credentials.Retrieve(ctx); err != nil {
+		return "", err
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, "https://"+s.address+"/?Action=connect&DBUser="+url.QueryEscape(s.username), nil); err != nil {
+		return "", fmt.Errorf("error creating the aws rds authentication token: %w", err)
+	}
+
+	awsPresignRequest(req, credentials, s.region, awsRDSService, awsRDSTokenExpiry, s.clock.Now())
+
+	return strings.TrimPrefix(req.URL.String(), "https://"), nil
+}
+
+// awsSignRequest signs the request and all of its headers using the AWS Signature Version 4 process. The session
+// token header is set before signing if the credentials have one.
+func awsSignRequest(req *http.Request, body []byte, credentials AWSCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+
+	amzdate := now.Format(awsTimeFormat)
+
+	req.Header.Set(headerAmzDate, amzdate)
+
+	if credentials.SessionToken != "" {
+		req.Header.Set(headerAmzSecurityToken, credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	payload := sha256.Sum256(body)
+
+	scope := awsScope(now, region, service)
+
+	signed, signature := awsSignature(req, headers, hex.EncodeToString(payload[:]), credentials, amzdate, scope)
+
+	req.Header.Set(headerAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", awsAlgorithm, credentials.AccessKeyID, scope, signed, signature))
+}
+
+// awsPresignRequest signs the request using the query parameters of the AWS Signature Version 4 process so the URL of
+// the request can be used by another party until it expires. Only the host header is signed.
+func awsPresignRequest(req *http.Request, credentials AWSCredentials, region, service string, expires time.Duration, now time.Time) {
+	now = now.UTC()
+
+	amzdate := now.Format(awsTimeFormat)
+
+	scope := awsScope(now, region, service)
+
+	query := req.URL.Query()
+
+	query.Set(queryAmzAlgorithm, awsAlgorithm)
+	query.Set(queryAmzCredential, credentials.AccessKeyID+"/"+scope)
+	query.Set(queryAmzDate, amzdate)
+	query.Set(queryAmzExpires, strconv.Itoa(int(expires.Seconds())))
+	query.Set(queryAmzSignedHeaders, "host")
+
+	if credentials.SessionToken != "" {
+		query.Set(queryAmzSecurityToken, credentials.SessionToken)
+	}
+
+	req.URL.RawQuery = awsEncodeQuery(query)
+
+	payload := sha256.Sum256(nil)
+
+	_, signature := awsSignature(req, map[string]string{"host": req.URL.Host}, hex.EncodeToString(payload[:]), credentials, amzdate, scope)
+
+	req.URL.RawQuery += "&" + queryAmzSignature + "=" + signature
+}
+
+func awsScope(now time.Time, region, service string) string {
+	return strings.Join([]string{now.Format(awsDateFormat), region, service, awsRequest}, "/")
+}
+
+// awsSignature returns the signed headers and the signature of the canonical form of the request.
+func awsSignature(req *http.Request, headers map[string]string, payload string, credentials AWSCredentials, amzdate, scope string) (signed, signature string) {
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	canonical := &strings.Builder{}
+
+	uri := req.URL.EscapedPath()
+
+	if uri == "" {
+		uri = "/"
+	}
+
+	canonical.WriteString(req.Method + "\n" + uri + "\n" + awsEncodeQuery(req.URL.Query()) + "\n")
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signed = strings.Join(names, ";")
+
+	canonical.WriteString("\n" + signed + "\n" + payload)
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+
+	stringToSign := strings.Join([]string{awsAlgorithm, amzdate, scope, hex.EncodeToString(digest[:])}, "\n")
+
+	key := []byte("AWS4" + credentials.SecretAccessKey)
+
+	for _, part := range strings.Split(scope, "/") {
+		key = awsHMAC(key, part)
+	}
+
+	return signed, hex.EncodeToString(awsHMAC(key, stringToSign))
+}
+
+// awsEncodeQuery encodes the query in the canonical form which is sorted by key and uses percent encoding for spaces.
+func awsEncodeQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func awsHMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,322 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// AWSCredentialsProvider provides the credentials used to sign requests to AWS.
+type AWSCredentialsProvider interface {
+	// Retrieve the credentials, the provider is responsible for refreshing temporary credentials before they expire.
+	Retrieve(ctx context.Context) (credentials AWSCredentials, err error)
+}
+
+// NewAWSCredentialsProviderFromEnvironment returns a new AWSCredentialsProvider which uses the first source of
+// credentials configured in the environment in the same order as the AWS SDKs:
+//
+//  1. The static credentials configured using the 'AWS_ACCESS_KEY_ID', 'AWS_SECRET_ACCESS_KEY', and
+//     'AWS_SESSION_TOKEN' environment variables.
+//  2. The web identity configured using the 'AWS_WEB_IDENTITY_TOKEN_FILE', 'AWS_ROLE_ARN', and
+//     'AWS_ROLE_SESSION_NAME' environment variables, i.e. IAM Roles for Service Accounts.
+//  3. The container credentials configured using the 'AWS_CONTAINER_CREDENTIALS_FULL_URI' or
+//     'AWS_CONTAINER_CREDENTIALS_RELATIVE_URI', and 'AWS_CONTAINER_AUTHORIZATION_TOKEN' or
+//     'AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE' environment variables, i.e. Amazon ECS task roles and EKS Pod Identity.
+//  4. The instance profile retrieved from the instance metadata service unless the 'AWS_EC2_METADATA_DISABLED'
+//     environment variable is true.
+//
+// The temporary credentials of the sources other than the static credentials are cached and retrieved again before
+// they expire, the web identity and the authorization token files are read each time so rotated tokens are used.
+func NewAWSCredentialsProviderFromEnvironment(client *http.Client, clock clock.Provider) (provider AWSCredentialsProvider) {
+	switch {
+	case os.Getenv(envAWSAccessKeyID) != "" || os.Getenv(envAWSSecretAccessKey) != "":
+		return AWSCredentials{
+			AccessKeyID:     os.Getenv(envAWSAccessKeyID),
+			SecretAccessKey: os.Getenv(envAWSSecretAccessKey),
+			SessionToken:    os.Getenv(envAWSSessionToken),
+		}
+	case os.Getenv(envAWSWebIdentityTokenFile) != "" && os.Getenv(envAWSRoleARN) != "":
+		endpoint := os.Getenv(envAWSEndpointSTS)
+
+		if endpoint == "" {
+			if region := AWSRegionFromEnvironment(); region != "" {
+				endpoint = fmt.Sprintf(awsSTSDefaultEndpointTemplate, region)
+			} else {
+				endpoint = awsSTSDefaultEndpoint
+			}
+		}
+
+		name := os.Getenv(envAWSRoleSessionName)
+
+		if name == "" {
+			name = awsSTSDefaultSessionName
+		}
+
+		return newAWSCachedCredentialsProvider(&awsWebIdentityCredentialsSource{
+			endpoint:  endpoint,
+			roleARN:   os.Getenv(envAWSRoleARN),
+			name:      name,
+			tokenFile: os.Getenv(envAWSWebIdentityTokenFile),
+			client:    client,
+		}, clock)
+	case os.Getenv(envAWSContainerCredentialsFullURI) != "" || os.Getenv(envAWSContainerCredentialsRelative) != "":
+		uri := os.Getenv(envAWSContainerCredentialsFullURI)
+
+		if uri == "" {
+			uri = awsContainerCredentialsHost + os.Getenv(envAWSContainerCredentialsRelative)
+		}
+
+		return newAWSCachedCredentialsProvider(&awsContainerCredentialsSource{
+			uri:       uri,
+			token:     os.Getenv(envAWSContainerAuthorizationToken),
+			tokenFile: os.Getenv(envAWSContainerAuthorizationFile),
+			client:    client,
+		}, clock)
+	case strings.EqualFold(os.Getenv(envAWSEC2MetadataDisabled), "true"):
+		return AWSCredentials{}
+	default:
+		endpoint := os.Getenv(envAWSEC2MetadataEndpoint)
+
+		if endpoint == "" {
+			endpoint = awsInstanceMetadataEndpoint
+		}
+
+		return newAWSCachedCredentialsProvider(&awsInstanceCredentialsSource{
+			endpoint: strings.TrimSuffix(endpoint, "/"),
+			client:   client,
+		}, clock)
+	}
+}
+
+var (
+	awsDefaultCredentials     AWSCredentialsProvider
+	awsDefaultCredentialsOnce sync.Once
+)
+
+// defaultAWSCredentialsProvider returns the AWSCredentialsProvider from the environment which is shared by all of the
+// AWS integrations so the temporary credentials are only retrieved once.
+func defaultAWSCredentialsProvider() AWSCredentialsProvider {
+	awsDefaultCredentialsOnce.Do(func() {
+		awsDefaultCredentials = NewAWSCredentialsProviderFromEnvironment(newHTTPClient(nil), clock.New())
+	})
+
+	return awsDefaultCredentials
+}
+
+// awsCredentialsSource is implemented by each of the sources of temporary credentials.
+type awsCredentialsSource interface {
+	retrieve(ctx context.Context) (credentials AWSCredentials, err error)
+}
+
+func newAWSCachedCredentialsProvider(source awsCredentialsSource, clock clock.Provider) *awsCachedCredentialsProvider {
+	return &awsCachedCredentialsProvider{
+		source: source,
+		clock:  clock,
+	}
+}
+
+// awsCachedCredentialsProvider is an AWSCredentialsProvider which caches the temporary credentials of the source until
+// shortly before they expire.
+type awsCachedCredentialsProvider struct {
+	source awsCredentialsSource
+	clock  clock.Provider
+
+	credentials AWSCredentials
+	mu          sync.Mutex
+}
+
+// Retrieve implements AWSCredentialsProvider.
+func (p *awsCachedCredentialsProvider) Retrieve(ctx context.Context) (credentials AWSCredentials, err error) {
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	if p.credentials.valid(p.clock.Now()) {
+		return p.credentials, nil
+	}
+
+	if credentials, err = p.source.retrieve(ctx); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	p.credentials = credentials
+
+	return credentials, nil
+}
+
+// awsWebIdentityCredentialsSource retrieves the temporary credentials of a role by exchanging the web identity token
+// with the AWS Security Token Service.
+type awsWebIdentityCredentialsSource struct {
+	endpoint  string
+	roleARN   string
+	name      string
+	tokenFile string
+	client    *http.Client
+}
+
+func (s *awsWebIdentityCredentialsSource) retrieve(ctx context.Context) (credentials AWSCredentials, err error) {
+	var token []byte
+
+	if token, err = os.ReadFile(s.tokenFile); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error reading the aws web identity token file: %w", err)
+	}
+
+	form := url.Values{
+		"Action":           []string{"AssumeRoleWithWebIdentity"},
+		"Version":          []string{awsSTSVersion},
+		"RoleArn":          []string{s.roleARN},
+		"RoleSessionName":  []string{s.name},
+		"WebIdentityToken": []string{strings.TrimSpace(string(token))},
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(s.endpoint, "/")+"/", strings.NewReader(form.Encode())); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error creating request to the aws sts api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeForm)
+
+	var resp awsAssumeRoleWithWebIdentityResponse
+
+	if err = doXML(s.client, req, "aws sts", &resp); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	return resp.Result.Credentials.credentials()
+}
+
+// awsContainerCredentialsSource retrieves the temporary credentials of the task or pod from the container credentials
+// endpoint.
+type awsContainerCredentialsSource struct {
+	uri       string
+	token     string
+	tokenFile string
+	client    *http.Client
+}
+
+func (s *awsContainerCredentialsSource) retrieve(ctx context.Context) (credentials AWSCredentials, err error) {
+	token := s.token
+
+	if s.tokenFile != "" {
+		var data []byte
+
+		if data, err = os.ReadFile(s.tokenFile); err != nil {
+			return AWSCredentials{}, fmt.Errorf("error reading the aws container authorization token file: %w", err)
+		}
+
+		token = strings.TrimSpace(string(data))
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.uri, nil); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error creating request to the aws container credentials api: %w", err)
+	}
+
+	if token != "" {
+		req.Header.Set(headerAuthorization, token)
+	}
+
+	var resp awsTemporaryCredentials
+
+	if err = do(s.client, req, "aws container credentials", &resp); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	return resp.credentials()
+}
+
+// awsInstanceCredentialsSource retrieves the temporary credentials of the instance profile from the instance metadata
+// service using a session token, i.e. IMDSv2.
+type awsInstanceCredentialsSource struct {
+	endpoint string
+	client   *http.Client
+}
+
+func (s *awsInstanceCredentialsSource) retrieve(ctx context.Context) (credentials AWSCredentials, err error) {
+	var (
+		req         *http.Request
+		token, role string
+	)
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPut, s.endpoint+"/latest/api/token", nil); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error creating request to the aws instance metadata api: %w", err)
+	}
+
+	req.Header.Set(headerAmzMetadataTTL, awsInstanceMetadataTokenTTL)
+
+	if token, err = doText(s.client, req, "aws instance metadata"); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint+"/latest/meta-data/iam/security-credentials/", nil); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error creating request to the aws instance metadata api: %w", err)
+	}
+
+	req.Header.Set(headerAmzMetadataToken, token)
+
+	if role, err = doText(s.client, req, "aws instance metadata"); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	if role, _, _ = strings.Cut(role, "\n"); role == "" {
+		return AWSCredentials{}, fmt.Errorf("the instance does not have an instance profile")
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint+"/latest/meta-data/iam/security-credentials/"+url.PathEscape(role), nil); err != nil {
+		return AWSCredentials{}, fmt.Errorf("error creating request to the aws instance metadata api: %w", err)
+	}
+
+	req.Header.Set(headerAmzMetadataToken, token)
+
+	var resp awsTemporaryCredentials
+
+	if err = do(s.client, req, "aws instance metadata", &resp); err != nil {
+		return AWSCredentials{}, err
+	}
+
+	return resp.credentials()
+}
+
+type awsAssumeRoleWithWebIdentityResponse struct {
+	Result struct {
+		Credentials awsTemporaryCredentials `xml:"Credentials"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+}
+
+// awsTemporaryCredentials is the format of the temporary credentials of the AWS Security Token Service, the container
+// credentials endpoint, and the instance metadata service.
+type awsTemporaryCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId" xml:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey" xml:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken" xml:"SessionToken"`
+	Token           string    `json:"Token" xml:"-"`
+	Expiration      time.Time `json:"Expiration" xml:"Expiration"`
+}
+
+func (c awsTemporaryCredentials) credentials() (credentials AWSCredentials, err error) {
+	credentials = AWSCredentials{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Expires:         c.Expiration,
+	}
+
+	if credentials.SessionToken == "" {
+		credentials.SessionToken = c.Token
+	}
+
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("the response did not include the aws credentials")
+	}
+
+	return credentials, nil
+}
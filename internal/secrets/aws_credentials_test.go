@@ -0,0 +1,205 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestNewAWSCredentialsProviderFromEnvironment(t *testing.T) {
+	t.Run("ShouldUseStaticCredentials", func(t *testing.T) {
+		t.Setenv(envAWSAccessKeyID, "AKIDEXAMPLE")
+		t.Setenv(envAWSSecretAccessKey, "secret")
+		t.Setenv(envAWSSessionToken, "token")
+		t.Setenv(envAWSWebIdentityTokenFile, "/var/run/secrets/token")
+		t.Setenv(envAWSRoleARN, "arn:aws:iam::123456789012:role/authelia")
+
+		credentials, err := NewAWSCredentialsProviderFromEnvironment(http.DefaultClient, clock.New()).Retrieve(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"}, credentials)
+	})
+
+	t.Run("ShouldFailPartialStaticCredentials", func(t *testing.T) {
+		t.Setenv(envAWSAccessKeyID, "AKIDEXAMPLE")
+		t.Setenv(envAWSSecretAccessKey, "")
+
+		_, err := NewAWSCredentialsProviderFromEnvironment(http.DefaultClient, clock.New()).Retrieve(context.Background())
+
+		assert.EqualError(t, err, "the aws credentials must be configured using the 'AWS_ACCESS_KEY_ID' and 'AWS_SECRET_ACCESS_KEY' environment variables")
+	})
+
+	t.Run("ShouldFailMetadataDisabled", func(t *testing.T) {
+		t.Setenv(envAWSAccessKeyID, "")
+		t.Setenv(envAWSSecretAccessKey, "")
+		t.Setenv(envAWSWebIdentityTokenFile, "")
+		t.Setenv(envAWSContainerCredentialsFullURI, "")
+		t.Setenv(envAWSContainerCredentialsRelative, "")
+		t.Setenv(envAWSEC2MetadataDisabled, "true")
+
+		_, err := NewAWSCredentialsProviderFromEnvironment(http.DefaultClient, clock.New()).Retrieve(context.Background())
+
+		assert.EqualError(t, err, "the aws credentials must be configured using the 'AWS_ACCESS_KEY_ID' and 'AWS_SECRET_ACCESS_KEY' environment variables")
+	})
+}
+
+func TestAWSWebIdentityCredentialsProvider(t *testing.T) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.PostForm.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::123456789012:role/authelia", r.PostForm.Get("RoleArn"))
+		assert.Equal(t, awsSTSDefaultSessionName, r.PostForm.Get("RoleSessionName"))
+		assert.Equal(t, "eyJ.web.identity", r.PostForm.Get("WebIdentityToken"))
+
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleWithWebIdentityResult><Credentials><AccessKeyId>ASIAEXAMPLE</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken><Expiration>2024-01-01T01:00:00Z</Expiration></Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`))
+	}))
+
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "token")
+
+	require.NoError(t, os.WriteFile(file, []byte("eyJ.web.identity\n"), 0600))
+
+	t.Setenv(envAWSAccessKeyID, "")
+	t.Setenv(envAWSSecretAccessKey, "")
+	t.Setenv(envAWSWebIdentityTokenFile, file)
+	t.Setenv(envAWSRoleARN, "arn:aws:iam::123456789012:role/authelia")
+	t.Setenv(envAWSRoleSessionName, "")
+	t.Setenv(envAWSEndpointSTS, server.URL)
+
+	c := clock.NewFixed(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	provider := NewAWSCredentialsProviderFromEnvironment(server.Client(), c)
+
+	credentials, err := provider.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, AWSCredentials{AccessKeyID: "ASIAEXAMPLE", SecretAccessKey: "secret", SessionToken: "token", Expires: time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)}, credentials)
+
+	_, err = provider.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), requests.Load())
+
+	c.Set(c.Now().Add(time.Minute * 59))
+
+	_, err = provider.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), requests.Load())
+
+	require.NoError(t, os.Remove(file))
+
+	c.Set(c.Now().Add(time.Hour * 2))
+
+	_, err = provider.Retrieve(context.Background())
+	assert.ErrorContains(t, err, "error reading the aws web identity token file: ")
+}
+
+func TestAWSContainerCredentialsProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/credentials/example", r.URL.Path)
+		assert.Equal(t, "pod-identity-token", r.Header.Get(headerAuthorization))
+
+		_, _ = w.Write([]byte(`{"AccessKeyId":"ASIAEXAMPLE","SecretAccessKey":"secret","Token":"token","Expiration":"2024-01-01T06:00:00Z"}`))
+	}))
+
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "token")
+
+	require.NoError(t, os.WriteFile(file, []byte("pod-identity-token"), 0600))
+
+	t.Setenv(envAWSAccessKeyID, "")
+	t.Setenv(envAWSSecretAccessKey, "")
+	t.Setenv(envAWSWebIdentityTokenFile, "")
+	t.Setenv(envAWSContainerCredentialsFullURI, server.URL+"/v2/credentials/example")
+	t.Setenv(envAWSContainerAuthorizationToken, "")
+	t.Setenv(envAWSContainerAuthorizationFile, file)
+
+	credentials, err := NewAWSCredentialsProviderFromEnvironment(server.Client(), clock.NewFixed(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))).Retrieve(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, AWSCredentials{AccessKeyID: "ASIAEXAMPLE", SecretAccessKey: "secret", SessionToken: "token", Expires: time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)}, credentials)
+}
+
+func TestAWSInstanceCredentialsProvider(t *testing.T) {
+	testCases := []struct {
+		name     string
+		role     string
+		expected AWSCredentials
+		err      string
+	}{
+		{
+			"ShouldRetrieveCredentials",
+			"authelia\n",
+			AWSCredentials{AccessKeyID: "ASIAEXAMPLE", SecretAccessKey: "secret", SessionToken: "token", Expires: time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)},
+			"",
+		},
+		{
+			"ShouldFailWithoutInstanceProfile",
+			"",
+			AWSCredentials{},
+			"the instance does not have an instance profile",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/latest/api/token":
+					assert.Equal(t, http.MethodPut, r.Method)
+					assert.Equal(t, awsInstanceMetadataTokenTTL, r.Header.Get(headerAmzMetadataTTL))
+
+					_, _ = w.Write([]byte("imds-token"))
+				case "/latest/meta-data/iam/security-credentials/":
+					assert.Equal(t, "imds-token", r.Header.Get(headerAmzMetadataToken))
+
+					_, _ = w.Write([]byte(tc.role))
+				case "/latest/meta-data/iam/security-credentials/authelia":
+					assert.Equal(t, "imds-token", r.Header.Get(headerAmzMetadataToken))
+
+					_, _ = w.Write([]byte(`{"Code":"Success","AccessKeyId":"ASIAEXAMPLE","SecretAccessKey":"secret","Token":"token","Expiration":"2024-01-01T06:00:00Z"}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+
+			defer server.Close()
+
+			t.Setenv(envAWSAccessKeyID, "")
+			t.Setenv(envAWSSecretAccessKey, "")
+			t.Setenv(envAWSWebIdentityTokenFile, "")
+			t.Setenv(envAWSContainerCredentialsFullURI, "")
+			t.Setenv(envAWSContainerCredentialsRelative, "")
+			t.Setenv(envAWSEC2MetadataDisabled, "")
+			t.Setenv(envAWSEC2MetadataEndpoint, server.URL+"/")
+
+			credentials, err := NewAWSCredentialsProviderFromEnvironment(server.Client(), clock.NewFixed(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))).Retrieve(context.Background())
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, credentials)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestAWSSignRequest(t *testing.T) {
+	// This is the get-vanilla case from the AWS Signature Version 4 test suite.
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	credentials := AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+
+	awsSignRequest(req, nil, credentials, "us-east-1", "service", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "20150830T123600Z", req.Header.Get(headerAmzDate))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", req.Header.Get(headerAuthorization))
+}
+
+func TestAWSSecretsManagerProviderFetch(t *testing.T) {
+	testCases := []struct {
+		name     string
+		region   string
+		value    string
+		status   int
+		body     string
+		expected string
+		err      string
+		request  awsGetSecretValueRequest
+	}{
+		{
+			"ShouldFetchSecretString",
+			"us-east-1",
+			"aws-sm://authelia/jwt",
+			http.StatusOK,
+			`{"SecretString":"abc123"}`,
+			"abc123",
+			"",
+			awsGetSecretValueRequest{SecretID: "authelia/jwt"},
+		},
+		{
+			"ShouldFetchSecretStringKeyWithVersionArn",
+			"",
+			"aws-sm://arn:aws:secretsmanager:ap-southeast-2:123456789012:secret:authelia-AbCdEf?version_stage=AWSPREVIOUS#ldap",
+			http.StatusOK,
+			`{"SecretString":"{\"ldap\":\"password\",\"smtp\":\"other\"}"}`,
+			"password",
+			"",
+			awsGetSecretValueRequest{SecretID: "arn:aws:secretsmanager:ap-southeast-2:123456789012:secret:authelia-AbCdEf", VersionStage: "AWSPREVIOUS"},
+		},
+		{
+			"ShouldFailWithoutRegion",
+			"",
+			"aws-sm://authelia/jwt",
+			http.StatusOK,
+			`{"SecretString":"abc123"}`,
+			"",
+			"error resolving secret reference 'aws-sm://authelia/jwt': the aws region must be configured using the 'AWS_REGION' environment variable or the reference must be an arn",
+			awsGetSecretValueRequest{},
+		},
+		{
+			"ShouldFailBinarySecret",
+			"us-east-1",
+			"aws-sm://authelia/jwt",
+			http.StatusOK,
+			`{"SecretBinary":"YWJj"}`,
+			"",
+			"error resolving secret reference 'aws-sm://authelia/jwt': the secret does not have a secret string",
+			awsGetSecretValueRequest{SecretID: "authelia/jwt"},
+		},
+		{
+			"ShouldFailNotFound",
+			"us-east-1",
+			"aws-sm://authelia/jwt",
+			http.StatusBadRequest,
+			`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`,
+			"",
+			"error resolving secret reference 'aws-sm://authelia/jwt': error performing request to the aws secrets manager api: / returned status code 400: {\"__type\":\"ResourceNotFoundException\",\"message\":\"Secrets Manager can't find the specified secret.\"}",
+			awsGetSecretValueRequest{SecretID: "authelia/jwt"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var request awsGetSecretValueRequest
+
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+				assert.Equal(t, tc.request, request)
+				assert.Equal(t, awsTargetGetSecretValue, r.Header.Get(headerAmzTarget))
+				assert.Equal(t, "token", r.Header.Get(headerAmzSecurityToken))
+				assert.Contains(t, r.Header.Get(headerAuthorization), "Credential=AKIDEXAMPLE/20240101/")
+				assert.Contains(t, r.Header.Get(headerAuthorization), "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target,")
+
+				w.WriteHeader(tc.status)
+
+				_, _ = w.Write([]byte(tc.body))
+			}))
+
+			defer server.Close()
+
+			credentials := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"}
+
+			resolver := NewResolver(map[string]Provider{
+				SchemeAWSSecretsManager: NewAWSSecretsManagerProvider(tc.region, server.URL, credentials, server.Client(), clock.NewFixed(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))),
+			})
+
+			secret, err := resolver.Resolve(context.Background(), tc.value)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, secret)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Equal(t, "", secret)
+			}
+		})
+	}
+}
+
+func TestAWSRDSTokenSource(t *testing.T) {
+	source := &awsRDSTokenSource{
+		region:      "us-east-1",
+		address:     "authelia.abc123.us-east-1.rds.amazonaws.com:5432",
+		username:    "authelia",
+		credentials: AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", SessionToken: "token"},
+		clock:       clock.NewFixed(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^authelia\.abc123\.us-east-1\.rds\.amazonaws\.com:5432/\?Action=connect&DBUser=authelia&X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIDEXAMPLE%2F20240101%2Fus-east-1%2Frds-db%2Faws4_request&X-Amz-Date=20240101T000000Z&X-Amz-Expires=900&X-Amz-Security-Token=token&X-Amz-SignedHeaders=host&X-Amz-Signature=[0-9a-f]{64}$`, token)
+
+	source.region = ""
+
+	_, err = source.Token(context.Background())
+	assert.EqualError(t, err, "the aws region must be configured using either the region option or the 'AWS_REGION' environment variable")
+}
@@ -0,0 +1,286 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewAzureKeyVaultProviderFromEnvironment returns a new AzureKeyVaultProvider which uses the access tokens provided by
+// NewAzureTokenSourceFromEnvironment.
+func NewAzureKeyVaultProviderFromEnvironment() (provider *AzureKeyVaultProvider) {
+	client := newHTTPClient(nil)
+
+	return NewAzureKeyVaultProvider("", NewAzureTokenSourceFromEnvironment(azureResourceKeyVault, client, clock.New()), client)
+}
+
+// NewAzureKeyVaultProvider returns a new AzureKeyVaultProvider. If the endpoint is empty the endpoint of the vault in
+// the reference is used.
+func NewAzureKeyVaultProvider(endpoint string, tokens TokenSource, client *http.Client) (provider *AzureKeyVaultProvider) {
+	return &AzureKeyVaultProvider{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		tokens:   tokens,
+	}
+}
+
+// AzureKeyVaultProvider is a Provider for Azure Key Vault secrets. References have the format
+// 'azure-kv://<vault>/<secret>/<version>#<key>' where the vault is either the name of the vault or the host of the
+// vault, the version is optional and defaults to the latest version, and the key is only required if the secret is a
+// JSON object and a single value is required.
+type AzureKeyVaultProvider struct {
+	endpoint string
+	client   *http.Client
+	tokens   TokenSource
+}
+
+// Fetch the secret value the reference refers to.
+func (p *AzureKeyVaultProvider) Fetch(ctx context.Context, reference *Reference) (secret string, err error) {
+	parts := strings.Split(reference.Path, "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		parts = append(parts, "")
+	case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] != "":
+		// The version is explicitly specified.
+	default:
+		return "", fmt.Errorf("the path '%s' must have the format '<vault>/<secret>' or '<vault>/<secret>/<version>'", reference.Path)
+	}
+
+	endpoint := p.endpoint
+
+	if endpoint == "" {
+		if strings.Contains(parts[0], ".") {
+			endpoint = "https://" + parts[0]
+		} else {
+			endpoint = "https://" + parts[0] + azureKeyVaultDefaultDomain
+		}
+	}
+
+	var token string
+
+	if token, err = p.tokens.Token(ctx); err != nil {
+		return "", err
+	}
+
+	path := "/secrets/" + url.PathEscape(parts[1])
+
+	if parts[2] != "" {
+		path += "/" + url.PathEscape(parts[2])
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+path+"?"+azureQueryAPIVersion+"="+azureKeyVaultAPIVersion, nil); err != nil {
+		return "", fmt.Errorf("error creating request to the azure key vault api: %w", err)
+	}
+
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+
+	var resp azureSecretBundle
+
+	if err = do(p.client, req, "azure key vault", &resp); err != nil {
+		return "", err
+	}
+
+	return secretValueKey(resp.Value, reference.Key)
+}
+
+// NewAzureTokenSourceFromEnvironment returns a new TokenSource which provides the access tokens for the resource using
+// the first managed identity configured in the environment in the same order as the Azure SDKs:
+//
+//  1. The workload identity configured using the 'AZURE_FEDERATED_TOKEN_FILE', 'AZURE_CLIENT_ID', 'AZURE_TENANT_ID',
+//     and 'AZURE_AUTHORITY_HOST' environment variables, i.e. AKS workload identity.
+//  2. The managed identity endpoint configured using the 'IDENTITY_ENDPOINT' and 'IDENTITY_HEADER' environment
+//     variables, i.e. App Service, Azure Functions, and Container Apps.
+//  3. The managed identity retrieved from the instance metadata service, i.e. Virtual Machines and Scale Sets.
+//
+// The 'AZURE_CLIENT_ID' environment variable selects the user assigned managed identity for the latter two. The access
+// tokens are cached and retrieved again before they expire, the federated token file is read each time so rotated
+// tokens are used.
+func NewAzureTokenSourceFromEnvironment(resource string, client *http.Client, clock clock.Provider) (source TokenSource) {
+	tokens := &azureTokenSource{
+		resource: resource,
+		clientID: os.Getenv(envAzureClientID),
+		client:   client,
+		clock:    clock,
+	}
+
+	switch {
+	case os.Getenv(envAzureFederatedTokenFile) != "" && os.Getenv(envAzureTenantID) != "":
+		authority := os.Getenv(envAzureAuthorityHost)
+
+		if authority == "" {
+			authority = azureDefaultAuthorityHost
+		}
+
+		tokens.endpoint = strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(os.Getenv(envAzureTenantID)) + "/oauth2/v2.0/token"
+		tokens.tokenFile = os.Getenv(envAzureFederatedTokenFile)
+	case os.Getenv(envAzureIdentityEndpoint) != "" && os.Getenv(envAzureIdentityHeader) != "":
+		tokens.endpoint = os.Getenv(envAzureIdentityEndpoint)
+		tokens.header = os.Getenv(envAzureIdentityHeader)
+	default:
+		tokens.endpoint = azureInstanceMetadataEndpoint
+	}
+
+	return tokens
+}
+
+// azureTokenSource provides the cached access token for the resource retrieved using the federated token file if
+// configured, otherwise from the managed identity endpoint if configured, otherwise from the instance metadata service.
+type azureTokenSource struct {
+	resource  string
+	clientID  string
+	endpoint  string
+	tokenFile string
+	header    string
+	client    *http.Client
+	clock     clock.Provider
+
+	token accessToken
+	mu    sync.Mutex
+}
+
+// Token implements TokenSource.
+func (p *azureTokenSource) Token(ctx context.Context) (token string, err error) {
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+
+	if p.token.valid(now) {
+		return p.token.value, nil
+	}
+
+	var req *http.Request
+
+	switch {
+	case p.tokenFile != "":
+		req, err = p.federated(ctx)
+	case p.header != "":
+		req, err = p.managed(ctx, azureAppServiceAPIVersion, headerIdentityHeader, p.header)
+	default:
+		req, err = p.managed(ctx, azureInstanceMetadataAPIVersion, headerMetadata, azureInstanceMetadataHeaderEnabled)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	var resp azureTokenResponse
+
+	if err = do(p.client, req, "azure identity", &resp); err != nil {
+		return "", err
+	}
+
+	if resp.AccessToken == "" {
+		return "", fmt.Errorf("the response did not include the azure access token")
+	}
+
+	p.token = accessToken{value: resp.AccessToken}
+
+	if resp.ExpiresOn > 0 {
+		p.token.expires = time.Unix(int64(resp.ExpiresOn), 0).Add(-tokenExpirySkew)
+	} else {
+		p.token.expires = now.Add(time.Duration(resp.ExpiresIn)*time.Second - tokenExpirySkew)
+	}
+
+	return p.token.value, nil
+}
+
+// federated returns the request which exchanges the federated token for an access token using the client credentials
+// grant with a client assertion.
+func (p *azureTokenSource) federated(ctx context.Context) (req *http.Request, err error) {
+	var assertion []byte
+
+	if assertion, err = os.ReadFile(p.tokenFile); err != nil {
+		return nil, fmt.Errorf("error reading the azure federated token file: %w", err)
+	}
+
+	form := url.Values{
+		azureQueryClientID:           []string{p.clientID},
+		azureFormClientAssertion:     []string{strings.TrimSpace(string(assertion))},
+		azureFormClientAssertionType: []string{azureClientAssertionType},
+		azureFormGrantType:           []string{azureGrantTypeClientCredentials},
+		azureFormScope:               []string{p.resource + azureScopeDefaultSuffix},
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, strings.NewReader(form.Encode())); err != nil {
+		return nil, fmt.Errorf("error creating request to the azure identity api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeForm)
+
+	return req, nil
+}
+
+// managed returns the request which retrieves the access token of the managed identity, the header proves the request
+// originates from the workload rather than from a server side request forgery.
+func (p *azureTokenSource) managed(ctx context.Context, version, header, value string) (req *http.Request, err error) {
+	query := url.Values{
+		azureQueryAPIVersion: []string{version},
+		azureQueryResource:   []string{p.resource},
+	}
+
+	if p.clientID != "" {
+		query.Set(azureQueryClientID, p.clientID)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"?"+query.Encode(), nil); err != nil {
+		return nil, fmt.Errorf("error creating request to the azure identity api: %w", err)
+	}
+
+	req.Header.Set(header, value)
+
+	return req, nil
+}
+
+type azureSecretBundle struct {
+	Value string `json:"value"`
+}
+
+// azureTokenResponse is the format of the access token responses of the Microsoft identity platform and the managed
+// identity endpoints, the latter of which encode the numbers as strings.
+type azureTokenResponse struct {
+	AccessToken string       `json:"access_token"`
+	ExpiresIn   azureSeconds `json:"expires_in"`
+	ExpiresOn   azureSeconds `json:"expires_on"`
+}
+
+// azureSeconds is a number of seconds which is encoded as either a JSON number or a JSON string.
+type azureSeconds int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *azureSeconds) UnmarshalJSON(data []byte) (err error) {
+	var value string
+
+	if err = json.Unmarshal(data, &value); err != nil {
+		value = string(data)
+	}
+
+	if value == "" {
+		*s = 0
+
+		return nil
+	}
+
+	var seconds int64
+
+	if seconds, err = strconv.ParseInt(value, 10, 64); err != nil {
+		return fmt.Errorf("error parsing the seconds '%s': %w", value, err)
+	}
+
+	*s = azureSeconds(seconds)
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestAzureKeyVaultProviderFetch(t *testing.T) {
+	var tokens atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata/identity/oauth2/token":
+			assert.Equal(t, azureInstanceMetadataHeaderEnabled, r.Header.Get(headerMetadata))
+			assert.Equal(t, azureInstanceMetadataAPIVersion, r.URL.Query().Get(azureQueryAPIVersion))
+			assert.Equal(t, azureResourceKeyVault, r.URL.Query().Get(azureQueryResource))
+
+			tokens.Add(1)
+
+			_, _ = w.Write([]byte(`{"access_token":"eyJ.kv","expires_in":"3599","expires_on":"1700003599","resource":"https://vault.azure.net","token_type":"Bearer"}`))
+		case "/secrets/authelia":
+			assert.Equal(t, "Bearer eyJ.kv", r.Header.Get(headerAuthorization))
+			assert.Equal(t, azureKeyVaultAPIVersion, r.URL.Query().Get(azureQueryAPIVersion))
+
+			_, _ = w.Write([]byte(`{"value":"{\"jwt\":\"abc\"}","id":"https://example.vault.azure.net/secrets/authelia/1"}`))
+		case "/secrets/authelia/0123456789abcdef":
+			_, _ = w.Write([]byte(`{"value":"older","id":"https://example.vault.azure.net/secrets/authelia/0123456789abcdef"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	c := clock.NewFixed(time.Unix(1700000000, 0))
+
+	source := &azureTokenSource{
+		resource: azureResourceKeyVault,
+		endpoint: server.URL + "/metadata/identity/oauth2/token",
+		client:   server.Client(),
+		clock:    c,
+	}
+
+	resolver := NewResolver(map[string]Provider{
+		SchemeAzureKeyVault: NewAzureKeyVaultProvider(server.URL, source, server.Client()),
+	})
+
+	secret, err := resolver.Resolve(context.Background(), "azure-kv://example/authelia#jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "azure-kv://example.vault.azure.net/authelia/0123456789abcdef")
+	require.NoError(t, err)
+	assert.Equal(t, "older", secret)
+
+	assert.Equal(t, int32(1), tokens.Load())
+
+	c.Set(c.Now().Add(time.Hour))
+
+	_, err = resolver.Resolve(context.Background(), "azure-kv://example/authelia")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), tokens.Load())
+
+	_, err = resolver.Resolve(context.Background(), "azure-kv://example")
+	assert.EqualError(t, err, "error resolving secret reference 'azure-kv://example': the path 'example' must have the format '<vault>/<secret>' or '<vault>/<secret>/<version>'")
+}
+
+func TestNewAzureTokenSourceFromEnvironment(t *testing.T) {
+	t.Run("ShouldUseWorkloadIdentity", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/00000000-0000-0000-0000-000000000000/oauth2/v2.0/token", r.URL.Path)
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "11111111-1111-1111-1111-111111111111", r.PostForm.Get(azureQueryClientID))
+			assert.Equal(t, "eyJ.federated", r.PostForm.Get(azureFormClientAssertion))
+			assert.Equal(t, azureClientAssertionType, r.PostForm.Get(azureFormClientAssertionType))
+			assert.Equal(t, azureGrantTypeClientCredentials, r.PostForm.Get(azureFormGrantType))
+			assert.Equal(t, "https://ossrdbms-aad.database.windows.net/.default", r.PostForm.Get(azureFormScope))
+
+			_, _ = w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"eyJ.workload"}`))
+		}))
+
+		defer server.Close()
+
+		file := filepath.Join(t.TempDir(), "token")
+
+		require.NoError(t, os.WriteFile(file, []byte("eyJ.federated\n"), 0600))
+
+		t.Setenv(envAzureFederatedTokenFile, file)
+		t.Setenv(envAzureTenantID, "00000000-0000-0000-0000-000000000000")
+		t.Setenv(envAzureClientID, "11111111-1111-1111-1111-111111111111")
+		t.Setenv(envAzureAuthorityHost, server.URL+"/")
+
+		token, err := NewAzureTokenSourceFromEnvironment(azureResourceOpenSourceRDBMS, server.Client(), clock.New()).Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "eyJ.workload", token)
+	})
+
+	t.Run("ShouldUseManagedIdentityEndpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/msi/token", r.URL.Path)
+			assert.Equal(t, "secret-header", r.Header.Get(headerIdentityHeader))
+			assert.Equal(t, azureAppServiceAPIVersion, r.URL.Query().Get(azureQueryAPIVersion))
+			assert.Equal(t, azureResourceKeyVault, r.URL.Query().Get(azureQueryResource))
+			assert.Equal(t, "11111111-1111-1111-1111-111111111111", r.URL.Query().Get(azureQueryClientID))
+
+			_, _ = w.Write([]byte(`{"access_token":"eyJ.app","expires_on":"1700003599","resource":"https://vault.azure.net","token_type":"Bearer"}`))
+		}))
+
+		defer server.Close()
+
+		t.Setenv(envAzureFederatedTokenFile, "")
+		t.Setenv(envAzureClientID, "11111111-1111-1111-1111-111111111111")
+		t.Setenv(envAzureIdentityEndpoint, server.URL+"/msi/token")
+		t.Setenv(envAzureIdentityHeader, "secret-header")
+
+		token, err := NewAzureTokenSourceFromEnvironment(azureResourceKeyVault, server.Client(), clock.NewFixed(time.Unix(1700000000, 0))).Token(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, "eyJ.app", token)
+	})
+}
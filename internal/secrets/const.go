@@ -0,0 +1,178 @@
+package secrets
+
+import (
+	"time"
+)
+
+const (
+	// SchemeVault is the reference scheme for HashiCorp Vault KV version 2 secrets engines.
+	SchemeVault = "vault"
+
+	// SchemeAWSSecretsManager is the reference scheme for AWS Secrets Manager.
+	SchemeAWSSecretsManager = "aws-sm"
+
+	// SchemeGCPSecretManager is the reference scheme for Google Cloud Secret Manager.
+	SchemeGCPSecretManager = "gcp-sm"
+
+	// SchemeAzureKeyVault is the reference scheme for Azure Key Vault secrets.
+	SchemeAzureKeyVault = "azure-kv"
+)
+
+const (
+	// DatabaseProviderAWS is the database provider for Amazon RDS and Aurora IAM database authentication.
+	DatabaseProviderAWS = "aws"
+
+	// DatabaseProviderAzure is the database provider for Azure Database Microsoft Entra authentication.
+	DatabaseProviderAzure = "azure"
+
+	// DatabaseProviderGCP is the database provider for Cloud SQL IAM database authentication.
+	DatabaseProviderGCP = "gcp"
+)
+
+const (
+	delimiterScheme = "://"
+	delimiterQuery  = "?"
+	delimiterKey    = "#"
+)
+
+const (
+	envVaultAddress   = "VAULT_ADDR"
+	envVaultToken     = "VAULT_TOKEN"
+	envVaultNamespace = "VAULT_NAMESPACE"
+	envVaultCACert    = "VAULT_CACERT"
+
+	envAWSRegion          = "AWS_REGION"
+	envAWSDefaultRegion   = "AWS_DEFAULT_REGION"
+	envAWSAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	envAWSSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+	envAWSSessionToken    = "AWS_SESSION_TOKEN"
+	envAWSEndpoint        = "AWS_ENDPOINT_URL_SECRETS_MANAGER"
+	envAWSEndpointKMS     = "AWS_ENDPOINT_URL_KMS"
+	envAWSEndpointSTS     = "AWS_ENDPOINT_URL_STS"
+
+	envAWSWebIdentityTokenFile         = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	envAWSRoleARN                      = "AWS_ROLE_ARN"
+	envAWSRoleSessionName              = "AWS_ROLE_SESSION_NAME"
+	envAWSContainerCredentialsFullURI  = "AWS_CONTAINER_CREDENTIALS_FULL_URI"
+	envAWSContainerCredentialsRelative = "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"
+	envAWSContainerAuthorizationToken  = "AWS_CONTAINER_AUTHORIZATION_TOKEN"
+	envAWSContainerAuthorizationFile   = "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"
+	envAWSEC2MetadataDisabled          = "AWS_EC2_METADATA_DISABLED"
+	envAWSEC2MetadataEndpoint          = "AWS_EC2_METADATA_SERVICE_ENDPOINT"
+
+	envGCPAccessToken           = "GOOGLE_OAUTH_ACCESS_TOKEN"
+	envGCPMetadataHost          = "GCE_METADATA_HOST"
+	envGCPApplicationCredential = "GOOGLE_APPLICATION_CREDENTIALS"
+
+	envAzureClientID           = "AZURE_CLIENT_ID"
+	envAzureTenantID           = "AZURE_TENANT_ID"
+	envAzureFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+	envAzureAuthorityHost      = "AZURE_AUTHORITY_HOST"
+	envAzureIdentityEndpoint   = "IDENTITY_ENDPOINT"
+	envAzureIdentityHeader     = "IDENTITY_HEADER"
+)
+
+const (
+	contentTypeForm = "application/x-www-form-urlencoded"
+)
+
+const (
+	headerAuthorization    = "Authorization"
+	headerContentType      = "Content-Type"
+	headerVaultToken       = "X-Vault-Token"
+	headerVaultNamespace   = "X-Vault-Namespace"
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzTarget        = "X-Amz-Target"
+	headerAmzSecurityToken = "X-Amz-Security-Token"
+	headerMetadataFlavor   = "Metadata-Flavor"
+	headerMetadata         = "Metadata"
+	headerIdentityHeader   = "X-IDENTITY-HEADER"
+	headerAmzMetadataToken = "X-Aws-Ec2-Metadata-Token"
+	headerAmzMetadataTTL   = "X-Aws-Ec2-Metadata-Token-Ttl-Seconds"
+)
+
+const (
+	awsService                 = "secretsmanager"
+	awsTargetGetSecretValue    = "secretsmanager.GetSecretValue"
+	awsContentType             = "application/x-amz-json-1.1"
+	awsAlgorithm               = "AWS4-HMAC-SHA256"
+	awsRequest                 = "aws4_request"
+	awsTimeFormat              = "20060102T150405Z"
+	awsDateFormat              = "20060102"
+	awsQueryVersionStage       = "version_stage"
+	awsQueryVersionID          = "version_id"
+	awsDefaultEndpointTemplate = "https://secretsmanager.%s.amazonaws.com"
+
+	awsKMSService                 = "kms"
+	awsKMSTargetDecrypt           = "TrentService.Decrypt"
+	awsKMSDefaultEndpointTemplate = "https://kms.%s.amazonaws.com"
+
+	awsSTSDefaultEndpoint         = "https://sts.amazonaws.com"
+	awsSTSDefaultEndpointTemplate = "https://sts.%s.amazonaws.com"
+	awsSTSVersion                 = "2011-06-15"
+	awsSTSDefaultSessionName      = "authelia"
+
+	awsContainerCredentialsHost = "http://169.254.170.2"
+	awsInstanceMetadataEndpoint = "http://169.254.169.254"
+	awsInstanceMetadataTokenTTL = "21600"
+
+	awsRDSService     = "rds-db"
+	awsRDSTokenExpiry = time.Minute * 15
+)
+
+const (
+	gcpDefaultEndpoint     = "https://secretmanager.googleapis.com"
+	gcpDefaultMetadataHost = "metadata.google.internal"
+	gcpMetadataTokenPath   = "/computeMetadata/v1/instance/service-accounts/default/token"
+	gcpMetadataFlavor      = "Google"
+	gcpVersionLatest       = "latest"
+	gcpKMSDefaultEndpoint  = "https://cloudkms.googleapis.com"
+	gcpContentType         = "application/json"
+	gcpScopeCloudPlatform  = "https://www.googleapis.com/auth/cloud-platform"
+	gcpGrantTypeExchange   = "urn:ietf:params:oauth:grant-type:token-exchange"
+	gcpTokenTypeAccess     = "urn:ietf:params:oauth:token-type:access_token"
+	gcpTypeExternalAccount = "external_account"
+)
+
+const (
+	azureDefaultAuthorityHost          = "https://login.microsoftonline.com/"
+	azureInstanceMetadataEndpoint      = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureInstanceMetadataAPIVersion    = "2018-02-01"
+	azureAppServiceAPIVersion          = "2019-08-01"
+	azureClientAssertionType           = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	azureKeyVaultDefaultDomain         = ".vault.azure.net"
+	azureKeyVaultAPIVersion            = "7.4"
+	azureResourceKeyVault              = "https://vault.azure.net"
+	azureResourceOpenSourceRDBMS       = "https://ossrdbms-aad.database.windows.net"
+	azureScopeDefaultSuffix            = "/.default"
+	azureGrantTypeClientCredentials    = "client_credentials"
+	azureQueryAPIVersion               = "api-version"
+	azureQueryResource                 = "resource"
+	azureQueryClientID                 = "client_id"
+	azureFormClientAssertion           = "client_assertion"
+	azureFormClientAssertionType       = "client_assertion_type"
+	azureFormGrantType                 = "grant_type"
+	azureFormScope                     = "scope"
+	azureInstanceMetadataHeaderEnabled = "true"
+)
+
+const (
+	queryAmzAlgorithm     = "X-Amz-Algorithm"
+	queryAmzCredential    = "X-Amz-Credential"
+	queryAmzDate          = "X-Amz-Date"
+	queryAmzExpires       = "X-Amz-Expires"
+	queryAmzSecurityToken = "X-Amz-Security-Token"
+	queryAmzSignedHeaders = "X-Amz-SignedHeaders"
+	queryAmzSignature     = "X-Amz-Signature"
+)
+
+const (
+	vaultQueryVersion = "version"
+)
+
+const (
+	timeoutFetch = time.Second * 30
+
+	// tokenExpirySkew is subtracted from the expiry of cached access tokens so they're refreshed before they expire.
+	tokenExpirySkew = time.Minute
+)
@@ -0,0 +1,23 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewDatabaseTokenSourceFromEnvironment returns a new TokenSource which provides the IAM authentication tokens used as
+// the password of the user for the managed database at the address. The provider is one of 'aws', 'azure', or 'gcp',
+// and the region is only used by 'aws'.
+func NewDatabaseTokenSourceFromEnvironment(provider, region, address, username string) (source TokenSource, err error) {
+	switch provider {
+	case DatabaseProviderAWS:
+		return NewAWSRDSTokenSourceFromEnvironment(region, address, username), nil
+	case DatabaseProviderAzure:
+		return NewAzureTokenSourceFromEnvironment(azureResourceOpenSourceRDBMS, newHTTPClient(nil), clock.New()), nil
+	case DatabaseProviderGCP:
+		return defaultGCPTokenSource(), nil
+	default:
+		return nil, fmt.Errorf("the database provider '%s' is not supported", provider)
+	}
+}
@@ -0,0 +1,365 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewGCPSecretManagerProviderFromEnvironment returns a new GCPSecretManagerProvider which uses the access tokens
+// provided by NewGCPTokenSourceFromEnvironment.
+func NewGCPSecretManagerProviderFromEnvironment() (provider *GCPSecretManagerProvider) {
+	return &GCPSecretManagerProvider{
+		endpoint: gcpDefaultEndpoint,
+		client:   newHTTPClient(nil),
+		tokens:   defaultGCPTokenSource(),
+	}
+}
+
+// NewGCPSecretManagerProvider returns a new GCPSecretManagerProvider. If the token is empty the access token is
+// retrieved from the metadata server.
+func NewGCPSecretManagerProvider(endpoint, metadata, token string, client *http.Client, clock clock.Provider) (provider *GCPSecretManagerProvider) {
+	return &GCPSecretManagerProvider{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		tokens:   newGCPTokenSource(metadata, token, client, clock),
+	}
+}
+
+// GCPSecretManagerProvider is a Provider for Google Cloud Secret Manager. References have the format
+// 'gcp-sm://projects/<project>/secrets/<secret>/versions/<version>#<key>' where the version is optional and defaults
+// to the latest version, and the key is only required if the secret is a JSON object and a single value is required.
+type GCPSecretManagerProvider struct {
+	endpoint string
+	client   *http.Client
+	tokens   TokenSource
+}
+
+// Fetch the secret value the reference refers to.
+func (p *GCPSecretManagerProvider) Fetch(ctx context.Context, reference *Reference) (secret string, err error) {
+	parts := strings.Split(reference.Path, "/")
+
+	switch {
+	case len(parts) == 4 && parts[0] == "projects" && parts[2] == "secrets":
+		parts = append(parts, "versions", gcpVersionLatest)
+	case len(parts) == 6 && parts[0] == "projects" && parts[2] == "secrets" && parts[4] == "versions":
+		// The version is explicitly specified.
+	default:
+		return "", fmt.Errorf("the path '%s' must have the format 'projects/<project>/secrets/<secret>' or 'projects/<project>/secrets/<secret>/versions/<version>'", reference.Path)
+	}
+
+	var token string
+
+	if token, err = p.tokens.Token(ctx); err != nil {
+		return "", err
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.endpoint+"/v1/"+strings.Join(parts, "/")+":access", nil); err != nil {
+		return "", fmt.Errorf("error creating request to the gcp secret manager api: %w", err)
+	}
+
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+
+	var resp gcpAccessSecretVersionResponse
+
+	if err = do(p.client, req, "gcp secret manager", &resp); err != nil {
+		return "", err
+	}
+
+	var data []byte
+
+	if data, err = base64.StdEncoding.DecodeString(resp.Payload.Data); err != nil {
+		return "", fmt.Errorf("error decoding the secret payload: %w", err)
+	}
+
+	return secretValueKey(string(data), reference.Key)
+}
+
+// NewGCPTokenSourceFromEnvironment returns a new TokenSource which provides the access tokens for Google Cloud using
+// the first source configured in the environment:
+//
+//  1. The static access token configured using the 'GOOGLE_OAUTH_ACCESS_TOKEN' environment variable.
+//  2. The workload identity federation credential configuration file configured using the
+//     'GOOGLE_APPLICATION_CREDENTIALS' environment variable, i.e. GKE workload identity federation and the workload
+//     identity pools of other platforms. Only the 'external_account' type is supported.
+//  3. The access token of the default service account retrieved from the metadata server configured using the
+//     'GCE_METADATA_HOST' environment variable, i.e. GKE workload identity and the attached service account.
+//
+// The access tokens other than the static access token are cached and retrieved again before they expire, the
+// credential configuration file and the subject token are read each time so rotated tokens are used.
+func NewGCPTokenSourceFromEnvironment(client *http.Client, clock clock.Provider) (source TokenSource) {
+	host := os.Getenv(envGCPMetadataHost)
+
+	if host == "" {
+		host = gcpDefaultMetadataHost
+	}
+
+	tokens := newGCPTokenSource("http://"+host, os.Getenv(envGCPAccessToken), client, clock)
+
+	tokens.credentials = os.Getenv(envGCPApplicationCredential)
+
+	return tokens
+}
+
+var (
+	gcpDefaultTokens     TokenSource
+	gcpDefaultTokensOnce sync.Once
+)
+
+// defaultGCPTokenSource returns the TokenSource from the environment which is shared by all of the Google Cloud
+// integrations so the access tokens are only retrieved once.
+func defaultGCPTokenSource() TokenSource {
+	gcpDefaultTokensOnce.Do(func() {
+		gcpDefaultTokens = NewGCPTokenSourceFromEnvironment(newHTTPClient(nil), clock.New())
+	})
+
+	return gcpDefaultTokens
+}
+
+func newGCPTokenSource(metadata, token string, client *http.Client, clock clock.Provider) *gcpTokenSource {
+	return &gcpTokenSource{
+		metadata: strings.TrimSuffix(metadata, "/"),
+		static:   token,
+		client:   client,
+		clock:    clock,
+	}
+}
+
+// gcpTokenSource provides the static access token if configured, otherwise the cached access token retrieved using
+// the credential configuration file if configured or the default service account from the metadata server.
+type gcpTokenSource struct {
+	metadata    string
+	static      string
+	credentials string
+	client      *http.Client
+	clock       clock.Provider
+
+	token accessToken
+	mu    sync.Mutex
+}
+
+// Token implements TokenSource.
+func (p *gcpTokenSource) Token(ctx context.Context) (token string, err error) {
+	if p.static != "" {
+		return p.static, nil
+	}
+
+	p.mu.Lock()
+
+	defer p.mu.Unlock()
+
+	now := p.clock.Now()
+
+	if p.token.valid(now) {
+		return p.token.value, nil
+	}
+
+	var t accessToken
+
+	if p.credentials != "" {
+		t, err = p.external(ctx, now)
+	} else {
+		t, err = p.instance(ctx, now)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	p.token = t
+
+	return p.token.value, nil
+}
+
+func (p *gcpTokenSource) instance(ctx context.Context, now time.Time) (token accessToken, err error) {
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, p.metadata+gcpMetadataTokenPath, nil); err != nil {
+		return token, fmt.Errorf("error creating request to the gcp metadata server: %w", err)
+	}
+
+	req.Header.Set(headerMetadataFlavor, gcpMetadataFlavor)
+
+	var resp gcpTokenResponse
+
+	if err = do(p.client, req, "gcp metadata", &resp); err != nil {
+		return token, err
+	}
+
+	return accessToken{
+		value:   resp.AccessToken,
+		expires: now.Add(time.Duration(resp.ExpiresIn)*time.Second - tokenExpirySkew),
+	}, nil
+}
+
+// external exchanges the subject token of the credential configuration file for a federated access token with the
+// Security Token Service, and for the access token of the service account if impersonation is configured.
+func (p *gcpTokenSource) external(ctx context.Context, now time.Time) (token accessToken, err error) {
+	var (
+		data   []byte
+		config gcpExternalAccount
+	)
+
+	if data, err = os.ReadFile(p.credentials); err != nil {
+		return token, fmt.Errorf("error reading the gcp credential configuration file: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &config); err != nil {
+		return token, fmt.Errorf("error decoding the gcp credential configuration file: %w", err)
+	}
+
+	if config.Type != gcpTypeExternalAccount {
+		return token, fmt.Errorf("the gcp credential configuration file has the type '%s' but only the '%s' type is supported", config.Type, gcpTypeExternalAccount)
+	}
+
+	var subject string
+
+	if subject, err = p.subject(ctx, config.CredentialSource); err != nil {
+		return token, err
+	}
+
+	form := url.Values{
+		"grant_type":           []string{gcpGrantTypeExchange},
+		"audience":             []string{config.Audience},
+		"scope":                []string{gcpScopeCloudPlatform},
+		"requested_token_type": []string{gcpTokenTypeAccess},
+		"subject_token_type":   []string{config.SubjectTokenType},
+		"subject_token":        []string{subject},
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, strings.NewReader(form.Encode())); err != nil {
+		return token, fmt.Errorf("error creating request to the gcp sts api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeForm)
+
+	var resp gcpTokenResponse
+
+	if err = do(p.client, req, "gcp sts", &resp); err != nil {
+		return token, err
+	}
+
+	if config.ServiceAccountImpersonationURL == "" {
+		return accessToken{
+			value:   resp.AccessToken,
+			expires: now.Add(time.Duration(resp.ExpiresIn)*time.Second - tokenExpirySkew),
+		}, nil
+	}
+
+	if data, err = json.Marshal(map[string][]string{"scope": {gcpScopeCloudPlatform}}); err != nil {
+		return token, fmt.Errorf("error encoding request to the gcp iam credentials api: %w", err)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, config.ServiceAccountImpersonationURL, bytes.NewReader(data)); err != nil {
+		return token, fmt.Errorf("error creating request to the gcp iam credentials api: %w", err)
+	}
+
+	req.Header.Set(headerAuthorization, "Bearer "+resp.AccessToken)
+	req.Header.Set(headerContentType, gcpContentType)
+
+	var impersonated gcpGenerateAccessTokenResponse
+
+	if err = do(p.client, req, "gcp iam credentials", &impersonated); err != nil {
+		return token, err
+	}
+
+	return accessToken{
+		value:   impersonated.AccessToken,
+		expires: impersonated.ExpireTime.Add(-tokenExpirySkew),
+	}, nil
+}
+
+// subject returns the subject token of the credential source, i.e. the token issued by the platform the workload runs
+// on.
+func (p *gcpTokenSource) subject(ctx context.Context, source gcpCredentialSource) (subject string, err error) {
+	var data []byte
+
+	switch {
+	case source.File != "":
+		if data, err = os.ReadFile(source.File); err != nil {
+			return "", fmt.Errorf("error reading the gcp credential source file: %w", err)
+		}
+	case source.URL != "":
+		var req *http.Request
+
+		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, source.URL, nil); err != nil {
+			return "", fmt.Errorf("error creating request to the gcp credential source url: %w", err)
+		}
+
+		for key, value := range source.Headers {
+			req.Header.Set(key, value)
+		}
+
+		var value string
+
+		if value, err = doText(p.client, req, "gcp credential source"); err != nil {
+			return "", err
+		}
+
+		data = []byte(value)
+	default:
+		return "", fmt.Errorf("the gcp credential configuration file must have a credential source with either a file or url")
+	}
+
+	if source.Format.Type != "json" {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	var values map[string]any
+
+	if err = json.Unmarshal(data, &values); err != nil {
+		return "", fmt.Errorf("error decoding the gcp credential source: %w", err)
+	}
+
+	return secretMapKey(values, source.Format.SubjectTokenFieldName)
+}
+
+type gcpAccessSecretVersionResponse struct {
+	Payload struct {
+		Data string `json:"data"`
+	} `json:"payload"`
+}
+
+type gcpTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type gcpGenerateAccessTokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
+
+// gcpExternalAccount is the supported subset of the workload identity federation credential configuration file.
+type gcpExternalAccount struct {
+	Type                           string              `json:"type"`
+	Audience                       string              `json:"audience"`
+	SubjectTokenType               string              `json:"subject_token_type"`
+	TokenURL                       string              `json:"token_url"`
+	ServiceAccountImpersonationURL string              `json:"service_account_impersonation_url"`
+	CredentialSource               gcpCredentialSource `json:"credential_source"`
+}
+
+type gcpCredentialSource struct {
+	File    string            `json:"file"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Format  struct {
+		Type                  string `json:"type"`
+		SubjectTokenFieldName string `json:"subject_token_field_name"`
+	} `json:"format"`
+}
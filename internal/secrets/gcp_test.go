@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestGCPTokenSourceExternalAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/token":
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, gcpGrantTypeExchange, r.PostForm.Get("grant_type"))
+			assert.Equal(t, "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider", r.PostForm.Get("audience"))
+			assert.Equal(t, gcpScopeCloudPlatform, r.PostForm.Get("scope"))
+			assert.Equal(t, "urn:ietf:params:oauth:token-type:jwt", r.PostForm.Get("subject_token_type"))
+			assert.Equal(t, "eyJ.subject", r.PostForm.Get("subject_token"))
+
+			_, _ = w.Write([]byte(`{"access_token":"ya29.federated","issued_token_type":"urn:ietf:params:oauth:token-type:access_token","token_type":"Bearer","expires_in":3600}`))
+		case "/v1/projects/-/serviceAccounts/authelia@example.iam.gserviceaccount.com:generateAccessToken":
+			assert.Equal(t, "Bearer ya29.federated", r.Header.Get(headerAuthorization))
+
+			_, _ = w.Write([]byte(`{"accessToken":"ya29.impersonated","expireTime":"2023-11-14T23:13:20Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte(`{"id_token":"eyJ.subject"}`), 0600))
+
+	testCases := []struct {
+		name          string
+		impersonation string
+		expected      string
+		err           string
+	}{
+		{
+			"ShouldExchangeSubjectToken",
+			"",
+			"ya29.federated",
+			"",
+		},
+		{
+			"ShouldImpersonateServiceAccount",
+			server.URL + "/v1/projects/-/serviceAccounts/authelia@example.iam.gserviceaccount.com:generateAccessToken",
+			"ya29.impersonated",
+			"",
+		},
+		{
+			"ShouldFailImpersonation",
+			server.URL + "/v1/projects/-/serviceAccounts/other@example.iam.gserviceaccount.com:generateAccessToken",
+			"",
+			"error performing request to the gcp iam credentials api: /v1/projects/-/serviceAccounts/other@example.iam.gserviceaccount.com:generateAccessToken returned status code 404: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := map[string]any{
+				"type":                              gcpTypeExternalAccount,
+				"audience":                          "//iam.googleapis.com/projects/1/locations/global/workloadIdentityPools/pool/providers/provider",
+				"subject_token_type":                "urn:ietf:params:oauth:token-type:jwt",
+				"token_url":                         server.URL + "/v1/token",
+				"service_account_impersonation_url": tc.impersonation,
+				"credential_source": map[string]any{
+					"file":   filepath.Join(dir, "token"),
+					"format": map[string]any{"type": "json", "subject_token_field_name": "id_token"},
+				},
+			}
+
+			data, err := json.Marshal(config)
+			require.NoError(t, err)
+
+			file := filepath.Join(t.TempDir(), "credentials.json")
+
+			require.NoError(t, os.WriteFile(file, data, 0600))
+
+			t.Setenv(envGCPAccessToken, "")
+			t.Setenv(envGCPApplicationCredential, file)
+
+			token, err := NewGCPTokenSourceFromEnvironment(server.Client(), clock.NewFixed(time.Unix(1700000000, 0))).Token(context.Background())
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, token)
+		})
+	}
+}
+
+func TestGCPTokenSourceExternalAccountUnsupported(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials.json")
+
+	require.NoError(t, os.WriteFile(file, []byte(`{"type":"service_account"}`), 0600))
+
+	t.Setenv(envGCPAccessToken, "")
+	t.Setenv(envGCPApplicationCredential, file)
+
+	_, err := NewGCPTokenSourceFromEnvironment(http.DefaultClient, clock.New()).Token(context.Background())
+
+	assert.EqualError(t, err, "the gcp credential configuration file has the type 'service_account' but only the 'external_account' type is supported")
+}
@@ -0,0 +1,174 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+// NewAWSKMSProviderFromEnvironment returns a new AWSKMSProvider configured using the 'AWS_REGION' or
+// 'AWS_DEFAULT_REGION', and 'AWS_ENDPOINT_URL_KMS' environment variables, and the credentials provided by
+// NewAWSCredentialsProviderFromEnvironment.
+func NewAWSKMSProviderFromEnvironment() (provider *AWSKMSProvider) {
+	return NewAWSKMSProvider(AWSRegionFromEnvironment(), os.Getenv(envAWSEndpointKMS), defaultAWSCredentialsProvider(), newHTTPClient(nil), clock.New())
+}
+
+// NewAWSKMSProvider returns a new AWSKMSProvider. If the endpoint is empty the regional endpoint is used.
+func NewAWSKMSProvider(region, endpoint string, credentials AWSCredentialsProvider, client *http.Client, clock clock.Provider) (provider *AWSKMSProvider) {
+	return &AWSKMSProvider{
+		region:      region,
+		endpoint:    endpoint,
+		credentials: credentials,
+		client:      client,
+		clock:       clock,
+	}
+}
+
+// AWSKMSProvider is a KMSProvider for AWS Key Management Service. If the key is an ARN then the region is determined
+// from the ARN.
+type AWSKMSProvider struct {
+	region      string
+	endpoint    string
+	credentials AWSCredentialsProvider
+	client      *http.Client
+	clock       clock.Provider
+}
+
+// Decrypt the ciphertext using the key and the encryption context.
+func (p *AWSKMSProvider) Decrypt(ctx context.Context, key string, ciphertext []byte, encryptionContext map[string]string) (plaintext []byte, err error) {
+	var (
+		region      string
+		credentials AWSCredentials
+	)
+
+	if region, err = awsRegion(p.region, key); err != nil {
+		return nil, err
+	}
+
+	if credentials, err = p.credentials.Retrieve(ctx); err != nil {
+		return nil, err
+	}
+
+	endpoint := p.endpoint
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf(awsKMSDefaultEndpointTemplate, region)
+	}
+
+	var body []byte
+
+	if body, err = json.Marshal(awsDecryptRequest{
+		CiphertextBlob:    ciphertext,
+		KeyID:             key,
+		EncryptionContext: encryptionContext,
+	}); err != nil {
+		return nil, fmt.Errorf("error encoding request to the aws kms api: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(body)); err != nil {
+		return nil, fmt.Errorf("error creating request to the aws kms api: %w", err)
+	}
+
+	req.Header.Set(headerContentType, awsContentType)
+	req.Header.Set(headerAmzTarget, awsKMSTargetDecrypt)
+
+	awsSignRequest(req, body, credentials, region, awsKMSService, p.clock.Now())
+
+	var resp awsDecryptResponse
+
+	if err = do(p.client, req, "aws kms", &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Plaintext, nil
+}
+
+type awsDecryptRequest struct {
+	CiphertextBlob    []byte            `json:"CiphertextBlob"`
+	KeyID             string            `json:"KeyId,omitempty"`
+	EncryptionContext map[string]string `json:"EncryptionContext,omitempty"`
+}
+
+type awsDecryptResponse struct {
+	Plaintext []byte `json:"Plaintext"`
+}
+
+// NewGCPKMSProviderFromEnvironment returns a new GCPKMSProvider which uses the access tokens provided by
+// NewGCPTokenSourceFromEnvironment.
+func NewGCPKMSProviderFromEnvironment() (provider *GCPKMSProvider) {
+	return &GCPKMSProvider{
+		endpoint: gcpKMSDefaultEndpoint,
+		client:   newHTTPClient(nil),
+		tokens:   defaultGCPTokenSource(),
+	}
+}
+
+// NewGCPKMSProvider returns a new GCPKMSProvider. If the token is empty the access token is retrieved from the
+// metadata server.
+func NewGCPKMSProvider(endpoint, metadata, token string, client *http.Client, clock clock.Provider) (provider *GCPKMSProvider) {
+	return &GCPKMSProvider{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client:   client,
+		tokens:   newGCPTokenSource(metadata, token, client, clock),
+	}
+}
+
+// GCPKMSProvider is a KMSProvider for Google Cloud Key Management Service. Keys have the format
+// 'projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>'.
+type GCPKMSProvider struct {
+	endpoint string
+	client   *http.Client
+	tokens   TokenSource
+}
+
+// Decrypt the ciphertext using the key. The encryption context is not supported and must be empty.
+func (p *GCPKMSProvider) Decrypt(ctx context.Context, key string, ciphertext []byte, encryptionContext map[string]string) (plaintext []byte, err error) {
+	if len(encryptionContext) != 0 {
+		return nil, fmt.Errorf("the gcp kms does not support an encryption context")
+	}
+
+	var token string
+
+	if token, err = p.tokens.Token(ctx); err != nil {
+		return nil, err
+	}
+
+	var body []byte
+
+	if body, err = json.Marshal(gcpDecryptRequest{Ciphertext: ciphertext}); err != nil {
+		return nil, fmt.Errorf("error encoding request to the gcp kms api: %w", err)
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/v1/"+strings.Trim(key, "/")+":decrypt", bytes.NewReader(body)); err != nil {
+		return nil, fmt.Errorf("error creating request to the gcp kms api: %w", err)
+	}
+
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+	req.Header.Set(headerContentType, gcpContentType)
+
+	var resp gcpDecryptResponse
+
+	if err = do(p.client, req, "gcp kms", &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Plaintext, nil
+}
+
+type gcpDecryptRequest struct {
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+type gcpDecryptResponse struct {
+	Plaintext []byte `json:"plaintext"`
+}
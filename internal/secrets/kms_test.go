@@ -0,0 +1,88 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestAWSKMSProviderDecrypt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, awsKMSTargetDecrypt, r.Header.Get(headerAmzTarget))
+		assert.Contains(t, r.Header.Get(headerAuthorization), "/eu-west-1/kms/aws4_request")
+
+		var request awsDecryptRequest
+
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		if string(request.CiphertextBlob) != "ciphertext" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			_, _ = w.Write([]byte(`{"__type":"InvalidCiphertextException"}`))
+
+			return
+		}
+
+		assert.Equal(t, map[string]string{"role": "authelia"}, request.EncryptionContext)
+
+		_, _ = w.Write([]byte(`{"KeyId":"arn:aws:kms:eu-west-1:123456789012:key/abc","Plaintext":"cGxhaW50ZXh0"}`))
+	}))
+
+	defer server.Close()
+
+	credentials := AWSCredentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}
+
+	provider := NewAWSKMSProvider("", server.URL, credentials, server.Client(), clock.NewFixed(time.Unix(1700000000, 0)))
+
+	plaintext, err := provider.Decrypt(context.Background(), "arn:aws:kms:eu-west-1:123456789012:key/abc", []byte("ciphertext"), map[string]string{"role": "authelia"})
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", string(plaintext))
+
+	_, err = provider.Decrypt(context.Background(), "arn:aws:kms:eu-west-1:123456789012:key/abc", []byte("invalid"), nil)
+	assert.EqualError(t, err, `error performing request to the aws kms api: / returned status code 400: {"__type":"InvalidCiphertextException"}`)
+
+	_, err = provider.Decrypt(context.Background(), "alias/authelia", []byte("ciphertext"), nil)
+	assert.EqualError(t, err, "the aws region must be configured using the 'AWS_REGION' environment variable or the reference must be an arn")
+
+	provider = NewAWSKMSProvider("eu-west-1", server.URL, AWSCredentials{}, server.Client(), clock.New())
+
+	_, err = provider.Decrypt(context.Background(), "alias/authelia", []byte("ciphertext"), nil)
+	assert.EqualError(t, err, "the aws credentials must be configured using the 'AWS_ACCESS_KEY_ID' and 'AWS_SECRET_ACCESS_KEY' environment variables")
+}
+
+func TestGCPKMSProviderDecrypt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ya29.token", r.Header.Get(headerAuthorization))
+
+		switch r.URL.Path {
+		case "/v1/projects/example/locations/global/keyRings/authelia/cryptoKeys/sops:decrypt":
+			var request gcpDecryptRequest
+
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "ciphertext", string(request.Ciphertext))
+
+			_, _ = w.Write([]byte(`{"plaintext":"cGxhaW50ZXh0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	provider := NewGCPKMSProvider(server.URL, server.URL, "ya29.token", server.Client(), clock.New())
+
+	plaintext, err := provider.Decrypt(context.Background(), "projects/example/locations/global/keyRings/authelia/cryptoKeys/sops", []byte("ciphertext"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "plaintext", string(plaintext))
+
+	_, err = provider.Decrypt(context.Background(), "projects/example/locations/global/keyRings/authelia/cryptoKeys/sops", []byte("ciphertext"), map[string]string{"role": "authelia"})
+	assert.EqualError(t, err, "the gcp kms does not support an encryption context")
+}
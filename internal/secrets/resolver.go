@@ -0,0 +1,181 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// NewResolver returns a new Resolver which resolves references using the provided providers keyed by their scheme.
+func NewResolver(providers map[string]Provider) (resolver *Resolver) {
+	return &Resolver{
+		providers: providers,
+	}
+}
+
+// NewResolverFromEnvironment returns a new Resolver with all of the providers, each configured using the environment
+// variables which are conventional for the respective secret manager. The providers are only required to be
+// configured correctly when a reference with their scheme is resolved.
+func NewResolverFromEnvironment() (resolver *Resolver) {
+	return NewResolver(map[string]Provider{
+		SchemeVault:             NewVaultProviderFromEnvironment(),
+		SchemeAWSSecretsManager: NewAWSSecretsManagerProviderFromEnvironment(),
+		SchemeGCPSecretManager:  NewGCPSecretManagerProviderFromEnvironment(),
+		SchemeAzureKeyVault:     NewAzureKeyVaultProviderFromEnvironment(),
+	})
+}
+
+var (
+	resolver     *Resolver
+	resolverOnce sync.Once
+)
+
+// IsReference returns true if the value is a reference to a secret in an external secret manager.
+func IsReference(value string) bool {
+	return defaultResolver().IsReference(value)
+}
+
+// Resolve the secret value of a reference to a secret in an external secret manager.
+func Resolve(value string) (secret string, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutFetch)
+
+	defer cancel()
+
+	return defaultResolver().Resolve(ctx, value)
+}
+
+func defaultResolver() *Resolver {
+	resolverOnce.Do(func() {
+		resolver = NewResolverFromEnvironment()
+	})
+
+	return resolver
+}
+
+// IsReference returns true if the value is a reference with a scheme which has a provider.
+func (r *Resolver) IsReference(value string) bool {
+	scheme, _, found := strings.Cut(value, delimiterScheme)
+	if !found {
+		return false
+	}
+
+	_, ok := r.providers[strings.ToLower(scheme)]
+
+	return ok
+}
+
+// Resolve the secret value of a reference.
+func (r *Resolver) Resolve(ctx context.Context, value string) (secret string, err error) {
+	var reference *Reference
+
+	if reference, err = ParseReference(value); err != nil {
+		return "", err
+	}
+
+	provider, ok := r.providers[reference.Scheme]
+	if !ok {
+		return "", fmt.Errorf("error resolving secret reference '%s': the scheme '%s' is not supported", reference, reference.Scheme)
+	}
+
+	if secret, err = provider.Fetch(ctx, reference); err != nil {
+		return "", fmt.Errorf("error resolving secret reference '%s': %w", reference, err)
+	}
+
+	return secret, nil
+}
+
+// ParseReference parses a reference in the format '<scheme>://<path>?<query>#<key>'. The path is not parsed as a URL
+// as various secret managers have identifiers which are not valid URL hosts or paths such as AWS ARNs.
+func ParseReference(value string) (reference *Reference, err error) {
+	scheme, remainder, found := strings.Cut(value, delimiterScheme)
+	if !found || scheme == "" {
+		return nil, fmt.Errorf("error parsing secret reference '%s': the reference must have a scheme", value)
+	}
+
+	reference = &Reference{
+		Scheme: strings.ToLower(scheme),
+	}
+
+	if i := strings.LastIndex(remainder, delimiterKey); i != -1 {
+		remainder, reference.Key = remainder[:i], remainder[i+1:]
+	}
+
+	var query string
+
+	remainder, query, _ = strings.Cut(remainder, delimiterQuery)
+
+	if reference.Query, err = url.ParseQuery(query); err != nil {
+		return nil, fmt.Errorf("error parsing secret reference '%s': the query is invalid: %w", value, err)
+	}
+
+	if reference.Path = strings.Trim(remainder, "/"); reference.Path == "" {
+		return nil, fmt.Errorf("error parsing secret reference '%s': the reference must have a path", value)
+	}
+
+	return reference, nil
+}
+
+// String returns the reference in its string form.
+func (r *Reference) String() string {
+	b := &strings.Builder{}
+
+	b.WriteString(r.Scheme)
+	b.WriteString(delimiterScheme)
+	b.WriteString(r.Path)
+
+	if len(r.Query) != 0 {
+		b.WriteString(delimiterQuery)
+		b.WriteString(r.Query.Encode())
+	}
+
+	if r.Key != "" {
+		b.WriteString(delimiterKey)
+		b.WriteString(r.Key)
+	}
+
+	return b.String()
+}
+
+// secretValueKey returns the value of the key from a secret which is a JSON object, or the whole secret if the key is
+// empty.
+func secretValueKey(secret, key string) (value string, err error) {
+	if key == "" {
+		return secret, nil
+	}
+
+	values := map[string]any{}
+
+	if err = json.Unmarshal([]byte(secret), &values); err != nil {
+		return "", fmt.Errorf("the key '%s' was specified but the secret is not a JSON object: %w", key, err)
+	}
+
+	return secretMapKey(values, key)
+}
+
+// secretMapKey returns the string value of the key from the secret values. If the key is empty and there is exactly
+// one value then that value is returned.
+func secretMapKey(values map[string]any, key string) (value string, err error) {
+	if key == "" {
+		if len(values) != 1 {
+			return "", fmt.Errorf("the key must be specified as the secret has %d keys", len(values))
+		}
+
+		for k := range values {
+			key = k
+		}
+	}
+
+	raw, ok := values[key]
+	if !ok {
+		return "", fmt.Errorf("the secret does not have the key '%s'", key)
+	}
+
+	if value, ok = raw.(string); !ok {
+		return "", fmt.Errorf("the secret key '%s' is not a string", key)
+	}
+
+	return value, nil
+}
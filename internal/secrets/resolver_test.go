@@ -0,0 +1,201 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+)
+
+func TestParseReference(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected *Reference
+		err      string
+	}{
+		{
+			"ShouldParsePath",
+			"vault://secret/authelia",
+			&Reference{Scheme: SchemeVault, Path: "secret/authelia", Query: url.Values{}},
+			"",
+		},
+		{
+			"ShouldParseQueryAndKey",
+			"VAULT://secret/authelia/?version=2#jwt",
+			&Reference{Scheme: SchemeVault, Path: "secret/authelia", Query: url.Values{"version": []string{"2"}}, Key: "jwt"},
+			"",
+		},
+		{
+			"ShouldParseARN",
+			"aws-sm://arn:aws:secretsmanager:us-east-1:123456789012:secret:authelia#key",
+			&Reference{Scheme: SchemeAWSSecretsManager, Path: "arn:aws:secretsmanager:us-east-1:123456789012:secret:authelia", Query: url.Values{}, Key: "key"},
+			"",
+		},
+		{
+			"ShouldFailWithoutScheme",
+			"/run/secrets/jwt",
+			nil,
+			"error parsing secret reference '/run/secrets/jwt': the reference must have a scheme",
+		},
+		{
+			"ShouldFailWithoutPath",
+			"vault://#key",
+			nil,
+			"error parsing secret reference 'vault://#key': the reference must have a path",
+		},
+		{
+			"ShouldFailWithInvalidQuery",
+			"vault://secret/authelia?version=%zz",
+			nil,
+			"error parsing secret reference 'vault://secret/authelia?version=%zz': the query is invalid: invalid URL escape \"%zz\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseReference(tc.have)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func TestResolverIsReference(t *testing.T) {
+	resolver := NewResolverFromEnvironment()
+
+	assert.True(t, resolver.IsReference("vault://secret/authelia#jwt"))
+	assert.True(t, resolver.IsReference("AWS-SM://authelia"))
+	assert.True(t, resolver.IsReference("gcp-sm://projects/example/secrets/authelia"))
+	assert.False(t, resolver.IsReference("/run/secrets/jwt"))
+	assert.False(t, resolver.IsReference("file:///run/secrets/jwt"))
+	assert.False(t, resolver.IsReference("https://example.com"))
+}
+
+func TestVaultProviderFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get(headerVaultToken))
+		assert.Equal(t, "ns1", r.Header.Get(headerVaultNamespace))
+
+		switch r.URL.Path {
+		case "/v1/secret/data/authelia":
+			assert.Equal(t, "2", r.URL.Query().Get("version"))
+
+			_, _ = w.Write([]byte(`{"data":{"data":{"jwt":"abc","ldap":"def","number":1}}}`))
+		case "/v1/kv/data/authelia/single":
+			_, _ = w.Write([]byte(`{"data":{"data":{"value":"xyz"}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+
+	defer server.Close()
+
+	resolver := NewResolver(map[string]Provider{
+		SchemeVault: NewVaultProvider(server.URL, "token", "ns1", server.Client()),
+	})
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		err      string
+	}{
+		{"ShouldFetchKey", "vault://secret/authelia?version=2#ldap", "def", ""},
+		{"ShouldFetchSingleKey", "vault://kv/authelia/single", "xyz", ""},
+		{"ShouldFailMultipleKeysWithoutKey", "vault://secret/authelia?version=2", "", "error resolving secret reference 'vault://secret/authelia?version=2': the key must be specified as the secret has 3 keys"},
+		{"ShouldFailMissingKey", "vault://secret/authelia?version=2#smtp", "", "error resolving secret reference 'vault://secret/authelia?version=2#smtp': the secret does not have the key 'smtp'"},
+		{"ShouldFailNonStringKey", "vault://secret/authelia?version=2#number", "", "error resolving secret reference 'vault://secret/authelia?version=2#number': the secret key 'number' is not a string"},
+		{"ShouldFailWithoutMount", "vault://authelia#jwt", "", "error resolving secret reference 'vault://authelia#jwt': the path 'authelia' must include the mount and the path of the secret"},
+		{"ShouldFailNotFound", "vault://secret/missing#jwt", "", "error resolving secret reference 'vault://secret/missing#jwt': error performing request to the vault api: /v1/secret/data/missing returned status code 404: {\"errors\":[]}"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret, err := resolver.Resolve(context.Background(), tc.have)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, secret)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+
+	_, err := NewResolver(map[string]Provider{
+		SchemeVault: NewVaultProvider("", "", "", server.Client()),
+	}).Resolve(context.Background(), "vault://secret/authelia#jwt")
+
+	assert.EqualError(t, err, "error resolving secret reference 'vault://secret/authelia#jwt': the vault address must be configured using the 'VAULT_ADDR' environment variable")
+}
+
+func TestGCPSecretManagerProviderFetch(t *testing.T) {
+	var tokens atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case gcpMetadataTokenPath:
+			assert.Equal(t, gcpMetadataFlavor, r.Header.Get(headerMetadataFlavor))
+
+			tokens.Add(1)
+
+			_, _ = w.Write([]byte(`{"access_token":"ya29.token","expires_in":3599,"token_type":"Bearer"}`))
+		case "/v1/projects/example/secrets/authelia/versions/latest:access":
+			assert.Equal(t, "Bearer ya29.token", r.Header.Get(headerAuthorization))
+
+			_, _ = w.Write([]byte(`{"name":"projects/1/secrets/authelia/versions/3","payload":{"data":"eyJqd3QiOiJhYmMifQ=="}}`))
+		case "/v1/projects/example/secrets/authelia/versions/2:access":
+			_, _ = w.Write([]byte(`{"name":"projects/1/secrets/authelia/versions/2","payload":{"data":"b2xkZXI="}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	c := clock.NewFixed(time.Unix(1700000000, 0))
+
+	resolver := NewResolver(map[string]Provider{
+		SchemeGCPSecretManager: NewGCPSecretManagerProvider(server.URL, server.URL, "", server.Client(), c),
+	})
+
+	secret, err := resolver.Resolve(context.Background(), "gcp-sm://projects/example/secrets/authelia#jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "gcp-sm://projects/example/secrets/authelia/versions/2")
+	require.NoError(t, err)
+	assert.Equal(t, "older", secret)
+
+	assert.Equal(t, int32(1), tokens.Load())
+
+	c.Set(c.Now().Add(time.Hour))
+
+	_, err = resolver.Resolve(context.Background(), "gcp-sm://projects/example/secrets/authelia/versions/2")
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), tokens.Load())
+
+	_, err = resolver.Resolve(context.Background(), "gcp-sm://example/authelia")
+	assert.EqualError(t, err, "error resolving secret reference 'gcp-sm://example/authelia': the path 'example/authelia' must have the format 'projects/<project>/secrets/<secret>' or 'projects/<project>/secrets/<secret>/versions/<version>'")
+
+	_, err = resolver.Resolve(context.Background(), "gcp-sm://projects/example/secrets/authelia/versions/2#jwt")
+	assert.EqualError(t, err, "error resolving secret reference 'gcp-sm://projects/example/secrets/authelia/versions/2#jwt': the key 'jwt' was specified but the secret is not a JSON object: invalid character 'o' looking for beginning of value")
+}
@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"context"
+	"net/url"
+)
+
+// Provider is implemented by each of the external secret managers.
+type Provider interface {
+	// Fetch the secret value the reference refers to.
+	Fetch(ctx context.Context, reference *Reference) (secret string, err error)
+}
+
+// KMSProvider is implemented by each of the external key management services.
+type KMSProvider interface {
+	// Decrypt the ciphertext using the key and the encryption context.
+	Decrypt(ctx context.Context, key string, ciphertext []byte, encryptionContext map[string]string) (plaintext []byte, err error)
+}
+
+// TokenSource is implemented by each of the sources of the bearer access tokens used to authenticate to cloud
+// services.
+type TokenSource interface {
+	// Token returns a valid access token, the source is responsible for refreshing the access token before it expires.
+	Token(ctx context.Context) (token string, err error)
+}
+
+// Resolver resolves references to secrets in external secret managers using the Provider for the scheme of the
+// reference.
+type Resolver struct {
+	providers map[string]Provider
+}
+
+// Reference is a parsed reference to a secret in an external secret manager.
+type Reference struct {
+	// Scheme is the lowercase scheme which determines the Provider.
+	Scheme string
+
+	// Path is the provider specific identifier of the secret.
+	Path string
+
+	// Query is the provider specific options.
+	Query url.Values
+
+	// Key is the key of the value within a secret which has multiple values.
+	Key string
+}
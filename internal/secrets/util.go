@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func newHTTPClient(pool *x509.CertPool) *http.Client {
+	return &http.Client{
+		Timeout: timeoutFetch,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}
+}
+
+// do performs the request and decodes the JSON response into v. The body of error responses is included in the error
+// as the secret managers only include diagnostic information in them.
+func do(client *http.Client, req *http.Request, name string, v any) (err error) {
+	return doDecode(client, req, name, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(v)
+	})
+}
+
+// doXML performs the request and decodes the XML response into v in the same way as do.
+func doXML(client *http.Client, req *http.Request, name string, v any) (err error) {
+	return doDecode(client, req, name, func(body io.Reader) error {
+		return xml.NewDecoder(body).Decode(v)
+	})
+}
+
+// doText performs the request and returns the response body as a trimmed string in the same way as do.
+func doText(client *http.Client, req *http.Request, name string) (value string, err error) {
+	err = doDecode(client, req, name, func(body io.Reader) error {
+		data, err := io.ReadAll(io.LimitReader(body, 1<<16))
+
+		value = strings.TrimSpace(string(data))
+
+		return err
+	})
+
+	return value, err
+}
+
+func doDecode(client *http.Client, req *http.Request, name string, decode func(body io.Reader) error) (err error) {
+	var resp *http.Response
+
+	if resp, err = client.Do(req); err != nil {
+		return fmt.Errorf("error performing request to the %s api: %w", name, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("error performing request to the %s api: %s returned status code %d: %s", name, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err = decode(resp.Body); err != nil {
+		return fmt.Errorf("error decoding response from the %s api: %w", name, err)
+	}
+
+	return nil
+}
+
+// accessToken is a cached access token.
+type accessToken struct {
+	value   string
+	expires time.Time
+}
+
+func (t accessToken) valid(now time.Time) bool {
+	return t.value != "" && now.Before(t.expires)
+}
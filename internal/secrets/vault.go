@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+)
+
+// NewVaultProviderFromEnvironment returns a new VaultProvider configured using the 'VAULT_ADDR', 'VAULT_TOKEN',
+// 'VAULT_NAMESPACE', and 'VAULT_CACERT' environment variables.
+func NewVaultProviderFromEnvironment() (provider *VaultProvider) {
+	var pool *x509.CertPool
+
+	if file := os.Getenv(envVaultCACert); file != "" {
+		if ca, err := os.ReadFile(file); err == nil {
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+
+			pool.AppendCertsFromPEM(ca)
+		}
+	}
+
+	return NewVaultProvider(os.Getenv(envVaultAddress), os.Getenv(envVaultToken), os.Getenv(envVaultNamespace), newHTTPClient(pool))
+}
+
+// NewVaultProvider returns a new VaultProvider.
+func NewVaultProvider(address, token, namespace string, client *http.Client) (provider *VaultProvider) {
+	return &VaultProvider{
+		address:   address,
+		token:     token,
+		namespace: namespace,
+		client:    client,
+	}
+}
+
+// VaultProvider is a Provider for the HashiCorp Vault KV version 2 secrets engine. References have the format
+// 'vault://<mount>/<path>?version=<version>#<key>' where the version is optional, and the key is optional if the secret
+// only has a single key.
+type VaultProvider struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// Fetch the secret value the reference refers to.
+func (p *VaultProvider) Fetch(ctx context.Context, reference *Reference) (secret string, err error) {
+	if p.address == "" {
+		return "", fmt.Errorf("the vault address must be configured using the '%s' environment variable", envVaultAddress)
+	}
+
+	if p.token == "" {
+		return "", fmt.Errorf("the vault token must be configured using the '%s' environment variable", envVaultToken)
+	}
+
+	mount, name, found := strings.Cut(reference.Path, "/")
+	if !found || name == "" {
+		return "", fmt.Errorf("the path '%s' must include the mount and the path of the secret", reference.Path)
+	}
+
+	var u *url.URL
+
+	if u, err = url.Parse(p.address); err != nil {
+		return "", fmt.Errorf("the vault address '%s' is invalid: %w", p.address, err)
+	}
+
+	u.Path = path.Join(u.Path, "v1", mount, "data", name)
+
+	if version := reference.Query.Get(vaultQueryVersion); version != "" {
+		u.RawQuery = url.Values{vaultQueryVersion: []string{version}}.Encode()
+	}
+
+	var req *http.Request
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil); err != nil {
+		return "", fmt.Errorf("error creating request to the vault api: %w", err)
+	}
+
+	req.Header.Set(headerVaultToken, p.token)
+
+	if p.namespace != "" {
+		req.Header.Set(headerVaultNamespace, p.namespace)
+	}
+
+	var resp vaultSecretResponse
+
+	if err = do(p.client, req, "vault", &resp); err != nil {
+		return "", err
+	}
+
+	return secretMapKey(resp.Data.Data, reference.Key)
+}
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
@@ -0,0 +1,293 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/fasthttp/router"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/pprofhandler"
+
+	"github.com/authelia/authelia/v4/internal/audit"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/handlers"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func handleAdmin(configuration *schema.Configuration, providers middlewares.Providers) fasthttp.RequestHandler {
+	config := &configuration.Server.Admin
+
+	r := router.New()
+
+	r.GET("/debug/info", handleAdminInfo(time.Now()))
+
+	if config.EnableEvents && providers.AuditStream != nil {
+		r.GET("/events", handleAdminEvents(providers.AuditStream, config.Timeouts.Write))
+	}
+
+	if config.EnablePprof {
+		r.GET("/debug/pprof/{name?}", pprofhandler.PprofHandler)
+		r.GET("/debug/goroutines", handleAdminGoroutines)
+	}
+
+	if config.EnableAPI {
+		bridge := middlewares.NewBridgeBuilder(*configuration, providers).
+			WithPreMiddlewares(middlewares.SecurityHeadersNoStore).
+			Build()
+
+		r.GET("/api/admin/users/{user}/sessions", bridge(handlers.AdminUserSessionsGET))
+		r.DELETE("/api/admin/users/{user}/sessions", bridge(handlers.AdminUserSessionsDELETE))
+		r.DELETE("/api/admin/users/{user}/sessions/{id}", bridge(handlers.AdminUserSessionDELETE))
+		r.DELETE("/api/admin/users/{user}/ban", bridge(handlers.AdminUserBanDELETE))
+		r.DELETE("/api/admin/users/{user}/totp", bridge(handlers.AdminUserTOTPDELETE))
+		r.DELETE("/api/admin/users/{user}/webauthn", bridge(handlers.AdminUserWebAuthnDELETE))
+
+		// The key id is a standard base64 value which may contain a slash.
+		r.DELETE("/api/admin/users/{user}/webauthn/{kid:*}", bridge(handlers.AdminUserWebAuthnCredentialDELETE))
+	}
+
+	r.HandleMethodNotAllowed = true
+	r.MethodNotAllowed = handlers.Status(fasthttp.StatusMethodNotAllowed)
+	r.NotFound = handlers.Status(fasthttp.StatusNotFound)
+
+	handler := handleAdminAuthorization(config.Token, r.Handler)
+
+	if config.Address.RouterPath() != "/" {
+		handler = middlewares.StripPath(config.Address.RouterPath())(handler)
+	}
+
+	return handler
+}
+
+// handleAdminAuthorization only permits the requests which have the configured bearer token.
+func handleAdminAuthorization(token string, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	expected := []byte(token)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		value, found := bytes.CutPrefix(ctx.Request.Header.PeekBytes(headerAuthorization), prefixBearer)
+
+		if !found || len(expected) == 0 || subtle.ConstantTimeCompare(value, expected) != 1 {
+			ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, valueBearerAdmin)
+			ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+			ctx.SetBodyString(fasthttp.StatusMessage(fasthttp.StatusUnauthorized))
+
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+func handleAdminInfo(started time.Time) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		var stats runtime.MemStats
+
+		runtime.ReadMemStats(&stats)
+
+		info := adminInfo{
+			Version: utils.Version(),
+			Build: adminInfoBuild{
+				Tag:    utils.BuildTag,
+				State:  utils.BuildState,
+				Commit: utils.BuildCommit,
+				Branch: utils.BuildBranch,
+				Date:   utils.BuildDate,
+				Number: utils.BuildNumber,
+				Extra:  utils.BuildExtra,
+			},
+			Runtime: adminInfoRuntime{
+				GoVersion:  runtime.Version(),
+				OS:         runtime.GOOS,
+				Arch:       runtime.GOARCH,
+				CPUs:       runtime.NumCPU(),
+				MaxProcs:   runtime.GOMAXPROCS(0),
+				Goroutines: runtime.NumGoroutine(),
+			},
+			Memory: adminInfoMemory{
+				Alloc:        stats.Alloc,
+				TotalAlloc:   stats.TotalAlloc,
+				Sys:          stats.Sys,
+				HeapAlloc:    stats.HeapAlloc,
+				HeapInuse:    stats.HeapInuse,
+				HeapObjects:  stats.HeapObjects,
+				GCCycles:     stats.NumGC,
+				GCPauseTotal: time.Duration(stats.PauseTotalNs).String(),
+			},
+			Started: started.UTC(),
+			Uptime:  time.Since(started).Round(time.Second).String(),
+		}
+
+		if stats.LastGC != 0 {
+			last := time.Unix(0, int64(stats.LastGC)).UTC()
+
+			info.Memory.GCLast = &last
+		}
+
+		data, err := json.Marshal(info)
+		if err != nil {
+			ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+
+			return
+		}
+
+		ctx.SetContentTypeBytes(contentTypeApplicationJSON)
+		ctx.SetBody(data)
+	}
+}
+
+// handleAdminEvents streams the audit events to the client as server-sent events. The events can be filtered with the
+// 'events' and 'exclude_events' query parameters which accept a comma separated list of the audit event patterns. The
+// connection is hijacked so the stream is not limited by the write timeout of the server, instead the write timeout
+// applies to each individual event.
+func handleAdminEvents(stream *audit.StreamSink, timeout time.Duration) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		include := adminEventsPatterns(ctx.QueryArgs().Peek("events"))
+		exclude := adminEventsPatterns(ctx.QueryArgs().Peek("exclude_events"))
+
+		for _, patterns := range [][]string{include, exclude} {
+			for _, pattern := range patterns {
+				if !audit.IsValidPattern(pattern) {
+					ctx.Error(fmt.Sprintf("The event pattern '%s' does not match any of the event types.", pattern), fasthttp.StatusBadRequest)
+
+					return
+				}
+			}
+		}
+
+		filter := audit.NewFilter(include, exclude)
+
+		ctx.HijackSetNoResponse(true)
+		ctx.Hijack(func(conn net.Conn) {
+			subscription := stream.Subscribe(filter)
+
+			defer subscription.Close()
+
+			_ = writeAdminEvents(conn, subscription, adminEventsHeartbeat, timeout)
+		})
+	}
+}
+
+// writeAdminEvents writes the response header followed by the events of the subscription until either the subscription
+// ends or a write fails, which is usually due to the client disconnecting. A comment is written at each heartbeat so
+// the disconnected clients are detected and the proxies do not close the idle connection.
+func writeAdminEvents(conn net.Conn, subscription *audit.StreamSubscription, heartbeat, timeout time.Duration) (err error) {
+	w := bufio.NewWriter(conn)
+
+	flush := func() error {
+		if timeout > 0 {
+			_ = conn.SetWriteDeadline(time.Now().Add(timeout))
+		}
+
+		return w.Flush()
+	}
+
+	_, _ = w.WriteString(adminEventsResponseHeader)
+	_, _ = w.WriteString(": connected\n\n")
+
+	if err = flush(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(heartbeat)
+
+	defer ticker.Stop()
+
+	var dropped uint64
+
+	for {
+		select {
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return nil
+			}
+
+			var data []byte
+
+			if data, err = json.Marshal(event); err != nil {
+				continue
+			}
+
+			_, _ = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-ticker.C:
+			if n := subscription.Dropped(); n != dropped {
+				_, _ = fmt.Fprintf(w, "event: dropped\ndata: {\"dropped\":%d}\n\n", n-dropped)
+
+				dropped = n
+			} else {
+				_, _ = w.WriteString(": heartbeat\n\n")
+			}
+		}
+
+		if err = flush(); err != nil {
+			return err
+		}
+	}
+}
+
+func adminEventsPatterns(value []byte) (patterns []string) {
+	for _, pattern := range strings.Split(string(value), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// handleAdminGoroutines writes the stack traces of all of the goroutines in the same format as an unrecovered panic.
+func handleAdminGoroutines(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentTypeBytes(contentTypeTextPlain)
+
+	if err := pprof.Lookup("goroutine").WriteTo(ctx, 2); err != nil {
+		ctx.Error(fasthttp.StatusMessage(fasthttp.StatusInternalServerError), fasthttp.StatusInternalServerError)
+	}
+}
+
+type adminInfo struct {
+	Version string           `json:"version"`
+	Build   adminInfoBuild   `json:"build"`
+	Runtime adminInfoRuntime `json:"runtime"`
+	Memory  adminInfoMemory  `json:"memory"`
+	Started time.Time        `json:"started"`
+	Uptime  string           `json:"uptime"`
+}
+
+type adminInfoBuild struct {
+	Tag    string `json:"tag"`
+	State  string `json:"state"`
+	Commit string `json:"commit"`
+	Branch string `json:"branch"`
+	Date   string `json:"date,omitempty"`
+	Number string `json:"number"`
+	Extra  string `json:"extra,omitempty"`
+}
+
+type adminInfoRuntime struct {
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	CPUs       int    `json:"cpus"`
+	MaxProcs   int    `json:"max_procs"`
+	Goroutines int    `json:"goroutines"`
+}
+
+type adminInfoMemory struct {
+	Alloc        uint64     `json:"alloc"`
+	TotalAlloc   uint64     `json:"total_alloc"`
+	Sys          uint64     `json:"sys"`
+	HeapAlloc    uint64     `json:"heap_alloc"`
+	HeapInuse    uint64     `json:"heap_inuse"`
+	HeapObjects  uint64     `json:"heap_objects"`
+	GCCycles     uint32     `json:"gc_cycles"`
+	GCPauseTotal string     `json:"gc_pause_total"`
+	GCLast       *time.Time `json:"gc_last,omitempty"`
+}
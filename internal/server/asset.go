@@ -29,7 +29,7 @@ var (
 	locales embed.FS
 )
 
-func newPublicHTMLEmbeddedHandler() fasthttp.RequestHandler {
+func newPublicHTMLEmbeddedHandler(headers *middlewares.SecurityHeadersPolicy) fasthttp.RequestHandler {
 	etags := map[string][]byte{}
 
 	getEmbedETags(assets, assetsRoot, etags)
@@ -59,7 +59,7 @@ func newPublicHTMLEmbeddedHandler() fasthttp.RequestHandler {
 			return
 		}
 
-		middlewares.SetBaseSecurityHeaders(ctx)
+		headers.SetSecurityHeaders(ctx)
 
 		contentType := mime.TypeByExtension(path.Ext(p))
 		if len(contentType) == 0 {
@@ -155,7 +155,7 @@ func newLocalesPathResolver() func(ctx *fasthttp.RequestCtx) (supported bool, as
 	}
 }
 
-func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
+func newLocalesEmbeddedHandler(headers *middlewares.SecurityHeadersPolicy) (handler fasthttp.RequestHandler) {
 	etags := map[string][]byte{}
 
 	getEmbedETags(locales, "locales", etags)
@@ -191,7 +191,7 @@ func newLocalesEmbeddedHandler() (handler fasthttp.RequestHandler) {
 			data = []byte("{}")
 		}
 
-		middlewares.SetBaseSecurityHeaders(ctx)
+		headers.SetSecurityHeaders(ctx)
 		middlewares.SetContentTypeApplicationJSON(ctx)
 
 		switch {
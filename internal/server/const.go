@@ -2,6 +2,7 @@ package server
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/valyala/fasthttp"
 )
@@ -52,6 +53,7 @@ var (
 		{name: "/.well-known", prefix: "/.well-known/"},
 		{name: "/static", prefix: "/static/"},
 		{name: "/locales", prefix: "/locales/"},
+		{name: "/scim", prefix: "/scim/"},
 	}
 )
 
@@ -64,6 +66,17 @@ const (
 	schemeHTTP  = "http"
 	schemeHTTPS = "https"
 	prefixAPI   = "/api/"
+	prefixSCIM  = "/scim/v2/"
+)
+
+const (
+	pathAPIVersion1 = "/api"
+	pathAPIVersion2 = "/api/v2"
+)
+
+const (
+	pathOpenIDConnectConsent = "/api/oidc/consent"
+	prefixOpenIDConnect      = "/api/oidc/"
 )
 
 var (
@@ -72,6 +85,26 @@ var (
 	headerCacheControl = []byte(fasthttp.HeaderCacheControl)
 
 	headerValueCacheControlETaggedAssets = []byte("public, max-age=0, must-revalidate")
+
+	headerAuthorization   = []byte(fasthttp.HeaderAuthorization)
+	headerWWWAuthenticate = []byte(fasthttp.HeaderWWWAuthenticate)
+
+	prefixBearer     = []byte("Bearer ")
+	valueBearerAdmin = []byte(`Bearer realm="admin"`)
+	valueBearerSCIM  = []byte(`Bearer realm="scim"`)
+
+	contentTypeApplicationJSON = []byte("application/json; charset=utf-8")
+	contentTypeTextPlain       = []byte("text/plain; charset=utf-8")
+)
+
+const (
+	adminEventsHeartbeat = time.Second * 15
+
+	adminEventsResponseHeader = "HTTP/1.1 200 OK\r\n" +
+		"Content-Type: text/event-stream; charset=utf-8\r\n" +
+		"Cache-Control: no-cache\r\n" +
+		"Connection: close\r\n" +
+		"X-Accel-Buffering: no\r\n\r\n"
 )
 
 const healthCheckEnv = `# Written by Authelia Process
@@ -90,6 +123,10 @@ const (
 	errMessageServerGeneric        = "An unknown error occurred while handling a request from client."
 )
 
+const (
+	tmplCSPImageSource = "; img-src 'self' %s://%s"
+)
+
 const (
 	tmplCSPSwaggerNonce = "default-src 'self'; img-src 'self' https://validator.swagger.io data:; object-src 'none'; script-src 'self' 'unsafe-inline' 'nonce-%s'; style-src 'self' 'nonce-%s'; base-uri 'self'"
 	tmplCSPSwagger      = "default-src 'self'; img-src 'self' https://validator.swagger.io data:; object-src 'none'; script-src 'self' 'unsafe-inline'; style-src 'self'; base-uri 'self'"
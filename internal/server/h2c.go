@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// newH2CListener returns a net.Listener which serves the connections which begin with the HTTP/2 client connection
+// preface using HTTP/2, and returns all other connections from Accept so they're served by the fasthttp.Server.
+func newH2CListener(listener net.Listener, server *fasthttp.Server, config schema.Server) (l *h2cListener) {
+	l = &h2cListener{
+		Listener: listener,
+		h2: &http2.Server{
+			MaxConcurrentStreams: uint32(config.H2C.MaxConcurrentStreams), //nolint:gosec // Validated by the configuration validator.
+			MaxReadFrameSize:     uint32(config.H2C.MaxReadFrameSize),     //nolint:gosec // Validated by the configuration validator.
+			IdleTimeout:          config.Timeouts.Idle,
+		},
+		opts: &http2.ServeConnOpts{
+			Handler: newH2CHandler(server.Handler, server.Logger, config.Limits.MaxRequestBodySize),
+		},
+		timeout:   config.Timeouts.Read,
+		keepalive: config.KeepAlive.TCPPeriod,
+		conns:     make(chan net.Conn),
+		errs:      make(chan error),
+		done:      make(chan struct{}),
+		active:    map[net.Conn]struct{}{},
+	}
+
+	go l.accept()
+
+	return l
+}
+
+// h2cListener is a net.Listener which multiplexes HTTP/2 with prior knowledge and HTTP/1.1 on the same listener.
+type h2cListener struct {
+	net.Listener
+
+	h2   *http2.Server
+	opts *http2.ServeConnOpts
+
+	timeout   time.Duration
+	keepalive time.Duration
+
+	conns chan net.Conn
+	errs  chan error
+	done  chan struct{}
+	once  sync.Once
+
+	mu     sync.Mutex
+	active map[net.Conn]struct{}
+}
+
+// Accept waits for and returns the next connection which is not a HTTP/2 connection.
+func (l *h2cListener) Accept() (conn net.Conn, err error) {
+	select {
+	case conn = <-l.conns:
+		return conn, nil
+	case err = <-l.errs:
+		return nil, err
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close the listener and all active HTTP/2 connections.
+func (l *h2cListener) Close() (err error) {
+	l.once.Do(func() {
+		close(l.done)
+
+		err = l.Listener.Close()
+
+		l.mu.Lock()
+
+		for conn := range l.active {
+			_ = conn.Close()
+		}
+
+		l.mu.Unlock()
+	})
+
+	return err
+}
+
+func (l *h2cListener) accept() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			select {
+			case l.errs <- err:
+			case <-l.done:
+				return
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			continue
+		}
+
+		if tc, ok := conn.(*net.TCPConn); ok && l.keepalive > 0 {
+			_ = tc.SetKeepAlive(true)
+			_ = tc.SetKeepAlivePeriod(l.keepalive)
+		}
+
+		go l.handle(conn)
+	}
+}
+
+func (l *h2cListener) handle(conn net.Conn) {
+	preface := []byte(http2.ClientPreface)
+
+	buf, match := make([]byte, len(preface)), true
+
+	if l.timeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(l.timeout))
+	}
+
+	var n int
+
+	for n < len(buf) {
+		m, err := conn.Read(buf[n:])
+
+		n += m
+
+		if match = bytes.Equal(buf[:n], preface[:n]); !match || err != nil {
+			break
+		}
+	}
+
+	_ = conn.SetReadDeadline(time.Time{})
+
+	c := &h2cConn{Conn: conn, buf: buf[:n]}
+
+	switch {
+	case n == 0:
+		_ = conn.Close()
+	case match && n == len(preface):
+		l.serve(c)
+	default:
+		select {
+		case l.conns <- c:
+		case <-l.done:
+			_ = conn.Close()
+		}
+	}
+}
+
+func (l *h2cListener) serve(conn net.Conn) {
+	l.mu.Lock()
+
+	select {
+	case <-l.done:
+		l.mu.Unlock()
+
+		_ = conn.Close()
+
+		return
+	default:
+		l.active[conn] = struct{}{}
+	}
+
+	l.mu.Unlock()
+
+	l.h2.ServeConn(conn, l.opts)
+
+	_ = conn.Close()
+
+	l.mu.Lock()
+
+	delete(l.active, conn)
+
+	l.mu.Unlock()
+}
+
+// h2cConn is a net.Conn which replays the bytes read while detecting the protocol.
+type h2cConn struct {
+	net.Conn
+
+	buf []byte
+}
+
+// Read from the buffered bytes before reading from the underlying net.Conn.
+func (c *h2cConn) Read(p []byte) (n int, err error) {
+	if len(c.buf) != 0 {
+		n = copy(p, c.buf)
+		c.buf = c.buf[n:]
+
+		return n, nil
+	}
+
+	return c.Conn.Read(p)
+}
+
+// newH2CHandler returns a http.Handler which converts the HTTP/2 requests to fasthttp requests and serves them with
+// the fasthttp.RequestHandler.
+func newH2CHandler(handler fasthttp.RequestHandler, logger fasthttp.Logger, maxRequestBodySize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &fasthttp.Request{}
+
+		req.Header.SetMethod(r.Method)
+		req.SetRequestURI(r.RequestURI)
+		req.Header.SetHost(r.Host)
+
+		for key, values := range r.Header {
+			for _, value := range values {
+				req.Header.Add(key, value)
+			}
+		}
+
+		if r.ContentLength > int64(maxRequestBodySize) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, int64(maxRequestBodySize)+1))
+
+		switch {
+		case err != nil:
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+
+			return
+		case len(body) > maxRequestBodySize:
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+
+			return
+		case len(body) != 0:
+			req.SetBody(body)
+		}
+
+		var addr net.Addr = &net.TCPAddr{}
+
+		if addrport, err := netip.ParseAddrPort(r.RemoteAddr); err == nil {
+			addr = net.TCPAddrFromAddrPort(addrport)
+		}
+
+		ctx := &fasthttp.RequestCtx{}
+
+		ctx.Init(req, addr, logger)
+
+		handler(ctx)
+
+		header := w.Header()
+
+		ctx.Response.Header.VisitAll(func(key, value []byte) {
+			switch k := string(key); k {
+			case fasthttp.HeaderConnection, fasthttp.HeaderContentLength, fasthttp.HeaderTransferEncoding, fasthttp.HeaderKeepAlive, fasthttp.HeaderUpgrade:
+				return
+			default:
+				header.Add(k, string(value))
+			}
+		})
+
+		w.WriteHeader(ctx.Response.StatusCode())
+
+		if r.Method != http.MethodHead && !ctx.Response.SkipBody {
+			_, _ = w.Write(ctx.Response.Body())
+		}
+	})
+}
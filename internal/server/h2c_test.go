@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+	"golang.org/x/net/http2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestH2CListener(t *testing.T) {
+	server := &fasthttp.Server{
+		Handler: func(ctx *fasthttp.RequestCtx) {
+			ctx.Response.Header.Set("X-Protocol", string(ctx.Request.Header.Protocol()))
+			ctx.Response.Header.Set("X-Cookie", string(ctx.Request.Header.Cookie("session")))
+
+			ctx.SetStatusCode(fasthttp.StatusAccepted)
+			ctx.SetBodyString(string(ctx.Method()) + " " + string(ctx.Host()) + string(ctx.RequestURI()) + " " + string(ctx.PostBody()))
+		},
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := schema.DefaultServerConfiguration
+	config.Limits.MaxRequestBodySize = 10
+
+	listener := newH2CListener(ln, server, config)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	defer func() {
+		assert.NoError(t, server.Shutdown())
+	}()
+
+	address := "http://" + ln.Addr().String()
+
+	h2 := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, address+"/api/authz/forward-auth?rd=1", strings.NewReader("body"))
+	require.NoError(t, err)
+
+	req.Host = "auth.example.com"
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+
+	resp, err := h2.Do(req)
+	require.NoError(t, err)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "POST auth.example.com/api/authz/forward-auth?rd=1 body", string(body))
+	assert.Equal(t, "abc", resp.Header.Get("X-Cookie"))
+	assert.Equal(t, "", resp.Header.Get("Connection"))
+
+	req, err = http.NewRequest(http.MethodPost, address+"/", strings.NewReader("more than ten bytes"))
+	require.NoError(t, err)
+
+	resp, err = h2.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	resp, err = http.Get(address + "/path")
+	require.NoError(t, err)
+
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 1, resp.ProtoMajor)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "HTTP/1.1", resp.Header.Get("X-Protocol"))
+	assert.Equal(t, "GET "+ln.Addr().String()+"/path ", string(body))
+}
@@ -9,8 +9,8 @@ import (
 	"strings"
 	"time"
 
-	duoapi "github.com/duosecurity/duo_api_golang"
 	"github.com/fasthttp/router"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
@@ -18,12 +18,16 @@ import (
 	"github.com/valyala/fasthttp/fasthttpadaptor"
 	"github.com/valyala/fasthttp/pprofhandler"
 
+	"github.com/authelia/authelia/v4/internal/breaker"
+	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/duo"
 	"github.com/authelia/authelia/v4/internal/handlers"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/metrics"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/saml"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -118,18 +122,23 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 
 	optsTemplatedFile := NewTemplatedFileOptions(config)
 
+	headers := optsTemplatedFile.Headers
+
 	serveIndexHandler := ServeTemplatedFile(providers.Templates.GetAssetIndexTemplate(), optsTemplatedFile)
 	serveOpenAPIHandler := ServeTemplatedOpenAPI(providers.Templates.GetAssetOpenAPIIndexTemplate(), optsTemplatedFile)
-	serveOpenAPISpecHandler := ETagRootURL(ServeTemplatedOpenAPI(providers.Templates.GetAssetOpenAPISpecTemplate(), optsTemplatedFile))
+	serveOpenAPISpecHandler := ServeVersionedOpenAPI(
+		ETagRootURL(ServeTemplatedOpenAPI(providers.Templates.GetAssetOpenAPISpecTemplate(), optsTemplatedFile)),
+		ETagRootURL(ServeTemplatedOpenAPI(providers.Templates.GetAssetOpenAPISpecV2Template(), optsTemplatedFile)),
+	)
 
-	handlerPublicHTML := newPublicHTMLEmbeddedHandler()
-	handlerLocales := newLocalesEmbeddedHandler()
+	handlerPublicHTML := newPublicHTMLEmbeddedHandler(headers.Portal)
+	handlerLocales := newLocalesEmbeddedHandler(headers.Portal)
 
 	bridge := middlewares.NewBridgeBuilder(*config, providers).
-		WithPreMiddlewares(middlewares.SecurityHeadersBase).Build()
+		WithPreMiddlewares(headers.Portal.Middleware).Build()
 
 	bridgeSwagger := middlewares.NewBridgeBuilder(*config, providers).
-		WithPreMiddlewares(middlewares.SecurityHeadersRelaxed).Build()
+		WithPreMiddlewares(headers.OpenAPI.Middleware).Build()
 
 	policyCORSPublicGET := middlewares.NewCORSPolicyBuilder().
 		WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodGet).
@@ -138,30 +147,33 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 
 	r := router.New()
 
-	// Static Assets.
-	r.HEAD("/", bridge(serveIndexHandler))
-	r.GET("/", bridge(serveIndexHandler))
+	// The portal is not served in headless mode as the API is used by a custom frontend.
+	if !config.Server.Headless.Enabled {
+		// Static Assets.
+		r.HEAD("/", bridge(serveIndexHandler))
+		r.GET("/", bridge(serveIndexHandler))
 
-	for _, f := range filesRoot {
-		r.HEAD("/"+f, handlerPublicHTML)
-		r.GET("/"+f, handlerPublicHTML)
-	}
+		for _, f := range filesRoot {
+			r.HEAD("/"+f, handlerPublicHTML)
+			r.GET("/"+f, handlerPublicHTML)
+		}
 
-	r.HEAD("/favicon.ico", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerPublicHTML))
-	r.GET("/favicon.ico", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerPublicHTML))
+		r.HEAD("/favicon.ico", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerPublicHTML))
+		r.GET("/favicon.ico", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerPublicHTML))
 
-	r.HEAD("/static/media/logo.png", middlewares.AssetOverride(config.Server.AssetPath, 2, handlerPublicHTML))
-	r.GET("/static/media/logo.png", middlewares.AssetOverride(config.Server.AssetPath, 2, handlerPublicHTML))
+		r.HEAD("/static/media/logo.png", bridge(ServeBrandingLogo(middlewares.AssetOverride(config.Server.AssetPath, 2, handlerPublicHTML))))
+		r.GET("/static/media/logo.png", bridge(ServeBrandingLogo(middlewares.AssetOverride(config.Server.AssetPath, 2, handlerPublicHTML))))
 
-	r.HEAD("/static/{filepath:*}", handlerPublicHTML)
-	r.GET("/static/{filepath:*}", handlerPublicHTML)
+		r.HEAD("/static/{filepath:*}", handlerPublicHTML)
+		r.GET("/static/{filepath:*}", handlerPublicHTML)
 
-	// Locales.
-	r.HEAD("/locales/{language:[a-z]{1,3}}-{variant:[a-zA-Z0-9-]+}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
-	r.GET("/locales/{language:[a-z]{1,3}}-{variant:[a-zA-Z0-9-]+}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
+		// Locales.
+		r.HEAD("/locales/{language:[a-z]{1,3}}-{variant:[a-zA-Z0-9-]+}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
+		r.GET("/locales/{language:[a-z]{1,3}}-{variant:[a-zA-Z0-9-]+}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
 
-	r.HEAD("/locales/{language:[a-z]{1,3}}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
-	r.GET("/locales/{language:[a-z]{1,3}}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
+		r.HEAD("/locales/{language:[a-z]{1,3}}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
+		r.GET("/locales/{language:[a-z]{1,3}}/{namespace:[a-z]+}.json", middlewares.AssetOverride(config.Server.AssetPath, 0, handlerLocales))
+	}
 
 	// Swagger.
 	r.HEAD(prefixAPI, bridgeSwagger(serveOpenAPIHandler))
@@ -181,37 +193,54 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		r.GET(prefixAPI+file, handlerPublicHTML)
 	}
 
+	var postMiddlewaresAPI []middlewares.AutheliaMiddleware
+
+	if config.Server.Headless.Enabled {
+		postMiddlewaresAPI = append(postMiddlewaresAPI, middlewares.RequireCSRFToken)
+	}
+
 	middlewareAPI := middlewares.NewBridgeBuilder(*config, providers).
-		WithPreMiddlewares(middlewares.SecurityHeadersBase, middlewares.SecurityHeadersNoStore, middlewares.SecurityHeadersCSPNone).
+		WithPreMiddlewares(headers.API.Middleware, middlewares.SecurityHeadersNoStore).
+		WithPostMiddlewares(postMiddlewaresAPI...).
 		Build()
 
 	middleware1FA := middlewares.NewBridgeBuilder(*config, providers).
-		WithPreMiddlewares(middlewares.SecurityHeadersBase, middlewares.SecurityHeadersNoStore, middlewares.SecurityHeadersCSPNone).
-		WithPostMiddlewares(middlewares.Require1FA).
+		WithPreMiddlewares(headers.API.Middleware, middlewares.SecurityHeadersNoStore).
+		WithPostMiddlewares(append(postMiddlewaresAPI, middlewares.Require1FA)...).
 		Build()
 
 	middlewareElevated1FA := middlewares.NewBridgeBuilder(*config, providers).
-		WithPreMiddlewares(middlewares.SecurityHeadersBase, middlewares.SecurityHeadersNoStore, middlewares.SecurityHeadersCSPNone).
-		WithPostMiddlewares(middlewares.RequireElevated).
+		WithPreMiddlewares(headers.API.Middleware, middlewares.SecurityHeadersNoStore).
+		WithPostMiddlewares(append(postMiddlewaresAPI, middlewares.RequireElevated)...).
 		Build()
 
+	if config.Server.Headless.Enabled {
+		r.GET("/api/csrf", middlewareAPI(handlers.CSRFTokenGET))
+	}
+
 	r.HEAD("/api/health", middlewareAPI(handlers.HealthGET))
 	r.GET("/api/health", middlewareAPI(handlers.HealthGET))
+	r.HEAD("/api/health/ready", middlewareAPI(handlers.HealthReadyGET))
+	r.GET("/api/health/ready", middlewareAPI(handlers.HealthReadyGET))
+	r.GET("/api/health/details", middleware1FA(handlers.HealthDetailsGET))
 
 	r.GET("/api/state", middlewareAPI(handlers.StateGET))
 
 	r.GET("/api/configuration", middleware1FA(handlers.ConfigurationGET))
+	r.GET("/api/configuration/features", middleware1FA(handlers.ConfigurationFeaturesGET))
 
+	r.GET("/api/configuration/branding", middlewareAPI(handlers.ConfigurationBrandingGET))
 	r.GET("/api/configuration/password-policy", middlewareAPI(handlers.PasswordPolicyConfigurationGET))
 
 	metricsVRMW := middlewares.NewMetricsAuthzRequest(providers.Metrics)
+	metricsFlowAuthzMW := middlewares.NewMetricsFlow(providers.Metrics, metrics.FlowAuthz)
 
 	for name, endpoint := range config.Server.Endpoints.Authz {
 		uri := path.Join(pathAuthz, name)
 
 		authz := handlers.NewAuthzBuilder().WithConfig(config).WithEndpointConfig(endpoint).Build()
 
-		handler := middlewares.Wrap(metricsVRMW, bridge(authz.Handler))
+		handler := middlewares.MultiWrap(bridge(authz.Handler), metricsFlowAuthzMW, metricsVRMW)
 
 		switch name {
 		case "legacy":
@@ -251,7 +280,20 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 
 	delayFunc := middlewares.TimingAttackDelay(10, 250, 85, time.Second, true)
 
-	r.POST("/api/firstfactor", middlewareAPI(handlers.FirstFactorPOST(delayFunc)))
+	metricsFlow1FAMW := middlewares.NewMetricsFlow(providers.Metrics, metrics.FlowFirstFactor)
+	metricsFlow2FAMW := middlewares.NewMetricsFlow(providers.Metrics, metrics.FlowSecondFactor)
+
+	r.POST("/api/firstfactor", middlewares.Wrap(metricsFlow1FAMW, middlewareAPI(handlers.FirstFactorPOST(delayFunc))))
+
+	if providers.SPNEGO != nil {
+		r.POST("/api/firstfactor/spnego", middlewares.Wrap(metricsFlow1FAMW, middlewareAPI(handlers.FirstFactorSPNEGOPOST)))
+	}
+
+	if !config.WebAuthn.Disable && config.WebAuthn.PasskeyLogin.Enable {
+		r.GET("/api/firstfactor/passkey", middlewareAPI(handlers.FirstFactorPasskeyGET))
+		r.POST("/api/firstfactor/passkey", middlewares.Wrap(metricsFlow1FAMW, middlewareAPI(handlers.FirstFactorPasskeyPOST)))
+	}
+
 	r.POST("/api/logout", middlewareAPI(handlers.LogoutPOST))
 
 	// Only register endpoints if forgot password is not disabled.
@@ -270,6 +312,29 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 	r.POST("/api/user/info", middleware1FA(handlers.UserInfoPOST))
 	r.POST("/api/user/info/2fa_method", middleware1FA(handlers.MethodPreferencePOST))
 
+	// Recent security activity of the user.
+	r.GET("/api/user/activity/authentications", middleware1FA(handlers.UserActivityAuthenticationsGET))
+	r.GET("/api/user/activity/devices", middleware1FA(handlers.UserActivityDevicesGET))
+	r.GET("/api/user/activity/consents", middleware1FA(handlers.UserActivityConsentsGET))
+
+	if providers.DeviceCertificates != nil {
+		// Management of the client certificates issued to managed devices.
+		r.GET("/api/user/device-certificates", middleware1FA(handlers.DeviceCertificatesGET))
+		r.POST("/api/user/device-certificates", middlewareElevated1FA(handlers.DeviceCertificatesPOST))
+		r.DELETE("/api/user/device-certificates/{serial}", middlewareElevated1FA(handlers.DeviceCertificateDELETE))
+	}
+
+	// Management of the app passwords used by the applications which authenticate with the Authorization header.
+	r.GET("/api/user/app-passwords", middleware1FA(handlers.AppPasswordsGET))
+	r.POST("/api/user/app-passwords", middlewareElevated1FA(handlers.AppPasswordsPOST))
+	r.DELETE("/api/user/app-passwords/{id}", middlewareElevated1FA(handlers.AppPasswordDELETE))
+
+	if config.Session.EnableUserSessions {
+		// Management of the active sessions of the user on other devices.
+		r.GET("/api/user/sessions", middleware1FA(handlers.UserSessionsGET))
+		r.DELETE("/api/user/sessions/{id}", middlewareElevated1FA(handlers.UserSessionDELETE))
+	}
+
 	// User Session Elevation.
 	middlewareDelaySecond := middlewares.ArbitraryDelay(time.Second)
 
@@ -282,7 +347,7 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 	if !config.TOTP.Disable {
 		// TOTP related endpoints.
 		r.GET("/api/secondfactor/totp", middleware1FA(handlers.TimeBasedOneTimePasswordGET))
-		r.POST("/api/secondfactor/totp", middleware1FA(handlers.TimeBasedOneTimePasswordPOST))
+		r.POST("/api/secondfactor/totp", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.TimeBasedOneTimePasswordPOST)))
 		r.DELETE("/api/secondfactor/totp", middleware1FA(handlers.TOTPConfigurationDELETE))
 
 		r.GET("/api/secondfactor/totp/register", middlewareElevated1FA(handlers.TOTPRegisterGET))
@@ -291,9 +356,28 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		r.DELETE("/api/secondfactor/totp/register", middlewareElevated1FA(handlers.TOTPRegisterDELETE))
 	}
 
+	if !config.SMS.Disable {
+		// SMS related endpoints.
+		r.GET("/api/secondfactor/sms", middleware1FA(handlers.SMSConfigurationGET))
+		r.PUT("/api/secondfactor/sms", middleware1FA(handlers.SMSPUT))
+		r.POST("/api/secondfactor/sms", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.SMSPOST)))
+		r.DELETE("/api/secondfactor/sms", middleware1FA(handlers.SMSConfigurationDELETE))
+
+		r.PUT("/api/secondfactor/sms/register", middlewareElevated1FA(handlers.SMSRegisterPUT))
+		r.POST("/api/secondfactor/sms/register", middlewareElevated1FA(handlers.SMSRegisterPOST))
+		r.DELETE("/api/secondfactor/sms/register", middlewareElevated1FA(handlers.SMSRegisterDELETE))
+	}
+
+	if config.Email.Enable {
+		// Email related endpoints.
+		r.PUT("/api/secondfactor/email", middleware1FA(handlers.EmailPUT))
+		r.POST("/api/secondfactor/email", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.EmailPOST)))
+		r.GET("/api/secondfactor/email/link", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.EmailLinkGET)))
+	}
+
 	if !config.WebAuthn.Disable {
 		r.GET("/api/secondfactor/webauthn", middleware1FA(handlers.WebAuthnAssertionGET))
-		r.POST("/api/secondfactor/webauthn", middleware1FA(handlers.WebAuthnAssertionPOST))
+		r.POST("/api/secondfactor/webauthn", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.WebAuthnAssertionPOST)))
 
 		// Management of the WebAuthn credentials.
 		r.GET("/api/secondfactor/webauthn/credentials", middleware1FA(handlers.WebAuthnCredentialsGET))
@@ -306,26 +390,39 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		r.DELETE("/api/secondfactor/webauthn/credential/{credentialID}", middlewareElevated1FA(handlers.WebAuthnCredentialDELETE))
 	}
 
+	// Recovery code related endpoints.
+	r.POST("/api/secondfactor/recovery_code", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.RecoveryCodePOST)))
+	r.GET("/api/secondfactor/recovery_codes", middleware1FA(handlers.RecoveryCodesGET))
+	r.POST("/api/secondfactor/recovery_codes", middlewareElevated1FA(handlers.RecoveryCodesPOST))
+
+	var duoAPI duo.Provider
+
 	// Configure DUO api endpoint only if configuration exists.
 	if !config.DuoAPI.Disable {
-		var duoAPI duo.API
-		if os.Getenv("ENVIRONMENT") == dev {
-			duoAPI = duo.NewDuoAPI(duoapi.NewDuoApi(
-				config.DuoAPI.IntegrationKey,
-				config.DuoAPI.SecretKey,
-				config.DuoAPI.Hostname, "", duoapi.SetInsecure()))
-		} else {
-			duoAPI = duo.NewDuoAPI(duoapi.NewDuoApi(
-				config.DuoAPI.IntegrationKey,
-				config.DuoAPI.SecretKey,
-				config.DuoAPI.Hostname, ""))
-		}
+		duoAPI = duo.NewDuoAPIFromConfiguration(&config.DuoAPI, os.Getenv(environment) == dev)
+		duoAPI = duo.NewCircuitBreakerProvider(duoAPI, breaker.New(&config.CircuitBreaker, schema.CircuitBreakerDependencyDuo, schema.CircuitBreakerDependencyDuo, clock.New(), duoAPI))
 
 		r.GET("/api/secondfactor/duo_devices", middleware1FA(handlers.DuoDevicesGET(duoAPI)))
-		r.POST("/api/secondfactor/duo", middleware1FA(handlers.DuoPOST(duoAPI)))
+		r.POST("/api/secondfactor/duo", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.DuoPOST(duoAPI))))
 		r.POST("/api/secondfactor/duo_device", middleware1FA(handlers.DuoDevicePOST))
 	}
 
+	if providers.Push != nil {
+		r.POST("/api/secondfactor/push", middleware1FA(handlers.PushPOST(providers.Push)))
+		r.POST("/api/secondfactor/push/status", middlewares.Wrap(metricsFlow2FAMW, middleware1FA(handlers.PushStatusPOST(providers.Push))))
+
+		switch config.Push.Provider {
+		case schema.PushProviderMobile, schema.PushProviderNtfy, schema.PushProviderGotify:
+			// The push challenges are usually responded to from another device and are authorized by the token of the
+			// push challenge rather than the session cookie so the CSRF token of the headless mode isn't required.
+			bridgePushChallenge := middlewares.NewBridgeBuilder(*config, providers).
+				WithPreMiddlewares(headers.API.Middleware, middlewares.SecurityHeadersNoStore).
+				Build()
+
+			r.POST("/api/secondfactor/push/challenge", bridgePushChallenge(handlers.PushChallengePOST))
+		}
+	}
+
 	if config.Server.Endpoints.EnablePprof {
 		r.GET("/debug/pprof/{name?}", pprofhandler.PprofHandler)
 	}
@@ -334,13 +431,75 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		r.GET("/debug/vars", expvarhandler.ExpvarHandler)
 	}
 
+	if config.Server.Endpoints.SCIM.Enabled {
+		bridgeSCIM := middlewares.NewBridgeBuilder(*config, providers).
+			WithPreMiddlewares(handleSCIMAuthorization(config.Server.Endpoints.SCIM.Token), middlewares.SecurityHeadersNoStore).
+			Build()
+
+		r.GET(prefixSCIM+"ServiceProviderConfig", bridgeSCIM(handlers.SCIMServiceProviderConfigGET))
+		r.GET(prefixSCIM+"ResourceTypes", bridgeSCIM(handlers.SCIMResourceTypesGET))
+
+		r.GET(prefixSCIM+"Users", bridgeSCIM(handlers.SCIMUsersGET))
+		r.POST(prefixSCIM+"Users", bridgeSCIM(handlers.SCIMUsersPOST))
+		r.GET(prefixSCIM+"Users/{id}", bridgeSCIM(handlers.SCIMUserGET))
+		r.PUT(prefixSCIM+"Users/{id}", bridgeSCIM(handlers.SCIMUserPUT))
+		r.PATCH(prefixSCIM+"Users/{id}", bridgeSCIM(handlers.SCIMUserPATCH))
+		r.DELETE(prefixSCIM+"Users/{id}", bridgeSCIM(handlers.SCIMUserDELETE))
+
+		r.GET(prefixSCIM+"Groups", bridgeSCIM(handlers.SCIMGroupsGET))
+		r.POST(prefixSCIM+"Groups", bridgeSCIM(handlers.SCIMGroupsPOST))
+		r.GET(prefixSCIM+"Groups/{id}", bridgeSCIM(handlers.SCIMGroupGET))
+		r.PUT(prefixSCIM+"Groups/{id}", bridgeSCIM(handlers.SCIMGroupPUT))
+		r.PATCH(prefixSCIM+"Groups/{id}", bridgeSCIM(handlers.SCIMGroupPATCH))
+		r.DELETE(prefixSCIM+"Groups/{id}", bridgeSCIM(handlers.SCIMGroupDELETE))
+	}
+
+	if config.Server.Endpoints.Mobile.Enabled {
+		// Mobile authentication endpoints. These endpoints don't use the session cookie so the CSRF token of the headless
+		// mode isn't required.
+		bridgeMobile := middlewares.NewBridgeBuilder(*config, providers).
+			WithPreMiddlewares(headers.API.Middleware, middlewares.SecurityHeadersNoStore).
+			Build()
+
+		r.POST("/api/mobile/authenticate", middlewares.Wrap(metricsFlow1FAMW, bridgeMobile(handlers.MobileAuthenticatePOST(delayFunc))))
+		r.POST("/api/mobile/token", bridgeMobile(handlers.MobileTokenPOST))
+		r.GET("/api/mobile/user/info", bridgeMobile(handlers.MobileUserInfoGET))
+		r.PUT("/api/mobile/device/push", bridgeMobile(handlers.MobileDevicePushPUT))
+
+		if providers.Push != nil && config.Push.Provider == schema.PushProviderMobile {
+			r.POST("/api/mobile/push/challenge", bridgeMobile(handlers.MobilePushChallengePOST))
+		}
+		r.DELETE("/api/mobile/device", bridgeMobile(handlers.MobileDeviceDELETE))
+	}
+
+	if providers.SAML != nil {
+		bridgeSAML := middlewares.NewBridgeBuilder(*config, providers).WithPreMiddlewares(
+			headers.OpenIDConnect.MiddlewareOpenIDConnect, middlewares.SecurityHeadersNoStore,
+		).Build()
+
+		r.GET(saml.EndpointPathMetadata, bridgeSAML(handlers.SAMLMetadataGET))
+		r.GET(saml.EndpointPathSingleSignOnService, bridgeSAML(handlers.SAMLSingleSignOnService))
+		r.POST(saml.EndpointPathSingleSignOnService, bridgeSAML(handlers.SAMLSingleSignOnService))
+		r.GET(saml.EndpointPathSingleLogoutService, bridgeSAML(handlers.SAMLSingleLogoutService))
+	}
+
 	if providers.OpenIDConnect != nil {
 		bridgeOIDC := middlewares.NewBridgeBuilder(*config, providers).WithPreMiddlewares(
-			middlewares.SecurityHeadersBase, middlewares.SecurityHeadersCSPNoneOpenIDConnect, middlewares.SecurityHeadersNoStore,
+			headers.OpenIDConnect.MiddlewareOpenIDConnect, middlewares.SecurityHeadersNoStore,
+		).WithPostMiddlewares(
+			middlewares.LogModule(logging.ModuleOpenIDConnect),
 		).Build()
 
+		consentPOST := handlers.OpenIDConnectConsentPOST
+
+		if config.Server.Headless.Enabled {
+			consentPOST = middlewares.RequireCSRFToken(consentPOST)
+		}
+
 		r.GET("/api/oidc/consent", bridgeOIDC(handlers.OpenIDConnectConsentGET))
-		r.POST("/api/oidc/consent", bridgeOIDC(handlers.OpenIDConnectConsentPOST))
+		r.POST("/api/oidc/consent", bridgeOIDC(consentPOST))
+		r.GET(oidc.EndpointPathDeviceAuthorization, bridgeOIDC(handlers.OAuthDeviceAuthorizationGET))
+		r.PUT(oidc.EndpointPathDeviceAuthorization, bridgeOIDC(handlers.OAuthDeviceAuthorizationPUT))
 
 		allowedOrigins := utils.StringSliceFromURLs(config.IdentityProviders.OIDC.CORS.AllowedOrigins)
 
@@ -383,6 +542,16 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		r.OPTIONS(oidc.EndpointPathPushedAuthorizationRequest, policyCORSPAR.HandleOnlyOPTIONS)
 		r.POST(oidc.EndpointPathPushedAuthorizationRequest, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointPushedAuthorizationRequest), policyCORSPAR.Middleware(bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectPushedAuthorizationRequest)))))
 
+		policyCORSDeviceAuthorization := middlewares.NewCORSPolicyBuilder().
+			WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodPost).
+			WithAllowedOrigins(allowedOrigins...).
+			WithEnabled(utils.IsStringInSliceFold(oidc.EndpointDeviceAuthorization, config.IdentityProviders.OIDC.CORS.Endpoints)).
+			Build()
+
+		r.OPTIONS(oidc.EndpointPathDeviceAuthorization, policyCORSDeviceAuthorization.HandleOnlyOPTIONS)
+		r.POST(oidc.EndpointPathDeviceAuthorization, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointDeviceAuthorization), policyCORSDeviceAuthorization.Middleware(bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthDeviceAuthorizationPOST)))))
+		r.GET(oidc.EndpointPathRFC8628UserVerificationURL, bridgeOIDC(handlers.OAuthDeviceAuthorizationUserVerificationGET))
+
 		policyCORSToken := middlewares.NewCORSPolicyBuilder().
 			WithAllowCredentials(true).
 			WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodPost).
@@ -391,7 +560,7 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 			Build()
 
 		r.OPTIONS(oidc.EndpointPathToken, policyCORSToken.HandleOPTIONS)
-		r.POST(oidc.EndpointPathToken, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointToken), policyCORSToken.Middleware(bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectTokenPOST)))))
+		r.POST(oidc.EndpointPathToken, middlewares.MultiWrap(policyCORSToken.Middleware(bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectTokenPOST))), middlewares.NewMetricsFlow(providers.Metrics, metrics.FlowOpenIDConnectToken), middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointToken)))
 
 		policyCORSUserinfo := middlewares.NewCORSPolicyBuilder().
 			WithAllowCredentials(true).
@@ -431,14 +600,49 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 		// TODO (james-d-elliott): Remove in GA. This is a legacy implementation of the above endpoint.
 		r.OPTIONS("/api/oidc/revoke", policyCORSRevocation.HandleOPTIONS)
 		r.POST("/api/oidc/revoke", middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointRevocation), policyCORSRevocation.Middleware(bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthRevocationPOST)))))
+
+		var authenticator oidc.BackchannelAuthenticator = providers.Push
+
+		if authenticator == nil && duoAPI != nil {
+			authenticator = duoAPI
+		}
+
+		if authenticator != nil && config.IdentityProviders.OIDC.BackchannelAuthentication.Enabled {
+			r.POST(oidc.EndpointPathBackchannelAuthentication, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointBackchannelAuthentication), bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthBackchannelAuthenticationPOST(authenticator)))))
+		}
+
+		if config.IdentityProviders.OIDC.DynamicClientRegistration.Enabled {
+			pathClientConfiguration := oidc.EndpointPathClientRegistration + "/{client_id}"
+
+			r.POST(oidc.EndpointPathClientRegistration, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointClientRegistration), bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthClientRegistrationPOST))))
+			r.GET(pathClientConfiguration, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointClientRegistration), bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthClientRegistrationConfigurationGET))))
+			r.PUT(pathClientConfiguration, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointClientRegistration), bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthClientRegistrationConfigurationPUT))))
+			r.DELETE(pathClientConfiguration, middlewares.Wrap(middlewares.NewMetricsRequestOpenIDConnect(providers.Metrics, oidc.EndpointClientRegistration), bridgeOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthClientRegistrationConfigurationDELETE))))
+		}
 	}
 
 	r.RedirectFixedPath = false
 	r.HandleMethodNotAllowed = true
 	r.MethodNotAllowed = handleMethodNotAllowed
-	r.NotFound = handleNotFound(bridge(serveIndexHandler))
 
-	handler := middlewares.LogRequest(r.Handler)
+	handler := r.Handler
+
+	if config.Server.Headless.Enabled {
+		r.NotFound = handlers.Status(fasthttp.StatusNotFound)
+
+		if len(config.Server.Headless.AllowedOrigins) != 0 {
+			handler = handleHeadlessCORS(config.Server.Headless, handler)
+		}
+	} else {
+		r.NotFound = handleNotFound(bridge(serveIndexHandler))
+	}
+
+	handler = middlewares.LogRequest(handler)
+
+	handler = middlewares.Wrap(middlewares.NewScannerFilter(config.Server.ScannerFilter, providers.Metrics, pathAuthz, pathAuthzLegacy), handler)
+
+	handler = middlewares.StripAPIVersion(handler)
+
 	if config.Server.Address.RouterPath() != "/" {
 		handler = middlewares.StripPath(config.Server.Address.RouterPath())(handler)
 	}
@@ -451,7 +655,7 @@ func handleRouter(config *schema.Configuration, providers middlewares.Providers)
 func handleMetrics(path string) fasthttp.RequestHandler {
 	r := router.New()
 
-	r.GET(path, fasthttpadaptor.NewFastHTTPHandler(promhttp.Handler()))
+	r.GET(path, fasthttpadaptor.NewFastHTTPHandler(promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}))))
 
 	r.HandleMethodNotAllowed = true
 	r.MethodNotAllowed = handlers.Status(fasthttp.StatusMethodNotAllowed)
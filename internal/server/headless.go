@@ -0,0 +1,54 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// handleHeadlessCORS applies the CORS policy of the headless mode to the first-party API so it can be used by custom
+// frontends served from the allowed origins. The OpenID Connect 1.0 endpoints, the authz endpoints, and the OpenAPI
+// specification are excluded as they have their own policies, except for the consent endpoint which is used by the
+// frontend. Preflight requests are answered before they reach the router.
+func handleHeadlessCORS(config schema.ServerHeadless, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	policy := middlewares.NewCORSPolicyBuilder().
+		WithAllowedOrigins(utils.StringSliceFromURLs(config.AllowedOrigins)...).
+		WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodHead, fasthttp.MethodGet, fasthttp.MethodPost, fasthttp.MethodPut, fasthttp.MethodDelete).
+		WithAllowedHeaders(fasthttp.HeaderAccept, fasthttp.HeaderContentType, fasthttp.HeaderXRequestedWith, middlewares.HeaderXCSRFToken).
+		WithAllowCredentials(true).
+		Build()
+
+	return func(ctx *fasthttp.RequestCtx) {
+		if !isHeadlessAPIPath(string(ctx.Path())) {
+			next(ctx)
+
+			return
+		}
+
+		if ctx.IsOptions() && len(ctx.Request.Header.Peek(fasthttp.HeaderAccessControlRequestMethod)) != 0 {
+			policy.HandleOPTIONS(ctx)
+
+			return
+		}
+
+		policy.Middleware(next)(ctx)
+	}
+}
+
+// isHeadlessAPIPath returns true if the path is part of the API which is used by a custom frontend in headless mode.
+func isHeadlessAPIPath(path string) bool {
+	switch {
+	case path == pathOpenIDConnectConsent:
+		return true
+	case !strings.HasPrefix(path, prefixAPI), path == prefixAPI, path == "/api/index.html", path == "/api/openapi.yml":
+		return false
+	case strings.HasPrefix(path, pathAuthz), strings.HasPrefix(path, pathAuthzLegacy), strings.HasPrefix(path, prefixOpenIDConnect):
+		return false
+	}
+
+	return !utils.IsStringInSlice(strings.TrimPrefix(path, prefixAPI), filesSwagger)
+}
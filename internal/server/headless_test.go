@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestIsHeadlessAPIPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected bool
+	}{
+		{"/api/firstfactor", true},
+		{"/api/user/info", true},
+		{"/api/csrf", true},
+		{"/api/oidc/consent", true},
+		{"/api/", false},
+		{"/api/openapi.yml", false},
+		{"/api/index.html", false},
+		{"/api/swagger-ui.css", false},
+		{"/api/authz/forward-auth", false},
+		{"/api/verify", false},
+		{"/api/oidc/token", false},
+		{"/", false},
+		{"/static/js/main.js", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isHeadlessAPIPath(tc.path))
+		})
+	}
+}
+
+func TestHandleHeadlessCORS(t *testing.T) {
+	config := schema.ServerHeadless{
+		Enabled:        true,
+		AllowedOrigins: []*url.URL{{Scheme: "https", Host: "login.example.com"}},
+	}
+
+	next := func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusTeapot)
+	}
+
+	testCases := []struct {
+		name           string
+		method         string
+		path           string
+		origin         string
+		preflight      bool
+		expected       int
+		expectedOrigin string
+		expectedCreds  string
+	}{
+		{"ShouldHandlePreflight", fasthttp.MethodOptions, "/api/firstfactor", "https://login.example.com", true, fasthttp.StatusOK, "https://login.example.com", "true"},
+		{"ShouldAddHeadersToRequest", fasthttp.MethodPost, "/api/firstfactor", "https://login.example.com", false, fasthttp.StatusTeapot, "https://login.example.com", "true"},
+		{"ShouldNotAllowOtherOrigin", fasthttp.MethodPost, "/api/firstfactor", "https://evil.example.com", false, fasthttp.StatusTeapot, "", "true"},
+		{"ShouldSkipExcludedPath", fasthttp.MethodGet, "/api/verify", "https://login.example.com", false, fasthttp.StatusTeapot, "", ""},
+		{"ShouldSkipPreflightExcludedPath", fasthttp.MethodOptions, "/api/oidc/token", "https://login.example.com", true, fasthttp.StatusTeapot, "", ""},
+	}
+
+	handler := handleHeadlessCORS(config, next)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.Header.SetMethod(tc.method)
+			ctx.Request.SetRequestURI(tc.path)
+			ctx.Request.Header.Set(fasthttp.HeaderOrigin, tc.origin)
+
+			if tc.preflight {
+				ctx.Request.Header.Set(fasthttp.HeaderAccessControlRequestMethod, fasthttp.MethodPost)
+			}
+
+			handler(ctx)
+
+			assert.Equal(t, tc.expected, ctx.Response.StatusCode())
+			assert.Equal(t, tc.expectedOrigin, string(ctx.Response.Header.Peek(fasthttp.HeaderAccessControlAllowOrigin)))
+			assert.Equal(t, tc.expectedCreds, string(ctx.Response.Header.Peek(fasthttp.HeaderAccessControlAllowCredentials)))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/scim"
+)
+
+// handleSCIMAuthorization only permits the SCIM requests which have the configured bearer token, and responds to the
+// other requests with a SCIM error message.
+func handleSCIMAuthorization(token string) middlewares.Middleware {
+	expected := []byte(token)
+
+	body, _ := json.Marshal(scim.NewError(http.StatusUnauthorized, "", "The bearer token is missing or invalid."))
+
+	return func(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+		return func(ctx *fasthttp.RequestCtx) {
+			value, found := bytes.CutPrefix(ctx.Request.Header.PeekBytes(headerAuthorization), prefixBearer)
+
+			if !found || len(expected) == 0 || subtle.ConstantTimeCompare(value, expected) != 1 {
+				ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, valueBearerSCIM)
+				ctx.SetStatusCode(fasthttp.StatusUnauthorized)
+				ctx.SetContentType(scim.ContentType)
+				ctx.SetBody(body)
+
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
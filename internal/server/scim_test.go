@@ -0,0 +1,52 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+)
+
+func TestShouldHandleSCIMAuthorization(t *testing.T) {
+	const token = "wQ7oBsbT3jYwqSJpX8e9n4LfhCvKdGmR"
+
+	testCases := []struct {
+		name          string
+		token         string
+		authorization string
+		expected      int
+	}{
+		{"ShouldRejectMissingToken", token, "", fasthttp.StatusUnauthorized},
+		{"ShouldRejectInvalidToken", token, "Bearer abc", fasthttp.StatusUnauthorized},
+		{"ShouldRejectBasicScheme", token, "Basic " + token, fasthttp.StatusUnauthorized},
+		{"ShouldRejectWhenNotConfigured", "", "Bearer ", fasthttp.StatusUnauthorized},
+		{"ShouldPermitValidToken", token, "Bearer " + token, fasthttp.StatusNoContent},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := handleSCIMAuthorization(tc.token)(func(ctx *fasthttp.RequestCtx) {
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+			})
+
+			ctx := &fasthttp.RequestCtx{}
+
+			ctx.Request.SetRequestURI("/scim/v2/Users")
+			ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+
+			if tc.authorization != "" {
+				ctx.Request.Header.Set(fasthttp.HeaderAuthorization, tc.authorization)
+			}
+
+			handler(ctx)
+
+			assert.Equal(t, tc.expected, ctx.Response.StatusCode())
+
+			if tc.expected == fasthttp.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="scim"`, string(ctx.Response.Header.Peek(fasthttp.HeaderWWWAuthenticate)))
+				assert.Equal(t, "application/scim+json", string(ctx.Response.Header.ContentType()))
+				assert.JSONEq(t, `{"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"],"status":"401","detail":"The bearer token is missing or invalid."}`, string(ctx.Response.Body()))
+			}
+		})
+	}
+}
@@ -30,6 +30,13 @@ func CreateDefaultServer(config *schema.Configuration, providers middlewares.Pro
 		ReadTimeout:           config.Server.Timeouts.Read,
 		WriteTimeout:          config.Server.Timeouts.Write,
 		IdleTimeout:           config.Server.Timeouts.Idle,
+		MaxRequestBodySize:    config.Server.Limits.MaxRequestBodySize,
+		Concurrency:           config.Server.Limits.MaxConnections,
+		MaxConnsPerIP:         config.Server.Limits.MaxConnectionsPerIP,
+		MaxRequestsPerConn:    config.Server.Limits.MaxRequestsPerConnection,
+		DisableKeepalive:      config.Server.KeepAlive.Disable,
+		TCPKeepalive:          config.Server.KeepAlive.TCPPeriod > 0,
+		TCPKeepalivePeriod:    config.Server.KeepAlive.TCPPeriod,
 		Logger:                logging.LoggerPrintf(logrus.DebugLevel),
 	}
 
@@ -70,6 +77,10 @@ func CreateDefaultServer(config *schema.Configuration, providers middlewares.Pro
 		listener = tls.NewListener(listener, server.TLSConfig.Clone())
 	}
 
+	if config.Server.H2C.Enable {
+		listener = newH2CListener(listener, server, config.Server)
+	}
+
 	if err = writeHealthCheckEnv(config.Server.DisableHealthcheck, connectionScheme, config.Server.Address.Hostname(),
 		config.Server.Address.RouterPath(), config.Server.Address.Port()); err != nil {
 		return nil, nil, nil, false, fmt.Errorf("unable to configure healthcheck: %w", err)
@@ -89,7 +100,7 @@ func CreateDefaultServer(config *schema.Configuration, providers middlewares.Pro
 
 // CreateMetricsServer creates a metrics server.
 func CreateMetricsServer(config *schema.Configuration, providers middlewares.Providers) (server *fasthttp.Server, listener net.Listener, paths []string, tls bool, err error) {
-	if providers.Metrics == nil {
+	if providers.Metrics == nil || !config.Telemetry.Metrics.Enabled {
 		return
 	}
 
@@ -111,3 +122,28 @@ func CreateMetricsServer(config *schema.Configuration, providers middlewares.Pro
 
 	return server, listener, []string{config.Telemetry.Metrics.Address.RouterPath()}, false, nil
 }
+
+// CreateAdminServer creates the admin server which serves the diagnostics endpoints.
+func CreateAdminServer(config *schema.Configuration, providers middlewares.Providers) (server *fasthttp.Server, listener net.Listener, paths []string, tls bool, err error) {
+	if !config.Server.Admin.Enabled {
+		return
+	}
+
+	server = &fasthttp.Server{
+		ErrorHandler:          handleError("server.admin"),
+		NoDefaultServerHeader: true,
+		Handler:               handleAdmin(config, providers),
+		ReadBufferSize:        config.Server.Admin.Buffers.Read,
+		WriteBufferSize:       config.Server.Admin.Buffers.Write,
+		ReadTimeout:           config.Server.Admin.Timeouts.Read,
+		WriteTimeout:          config.Server.Admin.Timeouts.Write,
+		IdleTimeout:           config.Server.Admin.Timeouts.Idle,
+		Logger:                logging.LoggerPrintf(logrus.DebugLevel),
+	}
+
+	if listener, err = config.Server.Admin.Address.Listener(); err != nil {
+		return nil, nil, nil, false, fmt.Errorf("error occurred while attempting to initialize admin server listener for address '%s': %w", config.Server.Admin.Address.String(), err)
+	}
+
+	return server, listener, []string{config.Server.Admin.Address.RouterPath()}, false, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha1" //nolint:gosec
 	"encoding/hex"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -14,11 +15,13 @@ import (
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/branding"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/random"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // ServeTemplatedFile serves a templated version of a specified file,
@@ -39,7 +42,7 @@ func ServeTemplatedFile(t templates.Template, opts *TemplatedFileOptions) middle
 			}
 		}
 
-		middlewares.SetBaseSecurityHeaders(ctx.RequestCtx)
+		opts.Headers.Portal.SetSecurityHeaders(ctx.RequestCtx)
 
 		switch ext {
 		case extHTML:
@@ -52,19 +55,11 @@ func ServeTemplatedFile(t templates.Template, opts *TemplatedFileOptions) middle
 
 		nonce := ctx.Providers.Random.StringCustom(32, random.CharSetAlphaNumeric)
 
-		switch {
-		case ctx.Configuration.Server.Headers.CSPTemplate != "":
-			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, strings.ReplaceAll(string(ctx.Configuration.Server.Headers.CSPTemplate), placeholderCSPNonce, nonce))
-		case isDevEnvironment:
-			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(tmplCSPDevelopment, nonce))
-		default:
-			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(tmplCSPDefault, nonce))
-		}
-
 		var (
 			rememberMe string
 			baseURL    string
 			domain     string
+			imgSrc     string
 			provider   *session.Session
 		)
 
@@ -73,11 +68,30 @@ func ServeTemplatedFile(t templates.Template, opts *TemplatedFileOptions) middle
 		if provider, err = ctx.GetSessionProvider(); err == nil {
 			domain = provider.Config.Domain
 			rememberMe = strconv.FormatBool(!provider.Config.DisableRememberMe)
+			imgSrc = cspImageSource(provider.Config.Branding.LogoURL)
+		}
+
+		switch {
+		case opts.Headers.Portal.HasContentSecurityPolicy():
+			opts.Headers.Portal.SetContentSecurityPolicy(ctx.RequestCtx, nonce)
+		case isDevEnvironment:
+			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(tmplCSPDevelopment, nonce)+imgSrc)
+		default:
+			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(tmplCSPDefault, nonce)+imgSrc)
+		}
+
+		common := opts.CommonData(ctx.BasePath(), baseURL, domain, nonce, logoOverride, rememberMe)
+
+		if err = common.setBranding(ctx.Providers.Branding.Get(domain)); err != nil {
+			ctx.RequestCtx.Error("an error occurred", fasthttp.StatusServiceUnavailable)
+			ctx.Logger.WithError(err).Errorf("Error occcurred rendering branding partial")
+
+			return
 		}
 
 		data := &bytes.Buffer{}
 
-		if err = t.Execute(data, opts.CommonData(ctx.BasePath(), baseURL, domain, nonce, logoOverride, rememberMe)); err != nil {
+		if err = t.Execute(data, common); err != nil {
 			ctx.RequestCtx.Error("an error occurred", fasthttp.StatusServiceUnavailable)
 			ctx.Logger.WithError(err).Errorf("Error occcurred rendering template")
 
@@ -100,6 +114,18 @@ func ServeTemplatedFile(t templates.Template, opts *TemplatedFileOptions) middle
 	}
 }
 
+// ServeVersionedOpenAPI serves the OpenAPI specification of the version of the API the request was made to.
+func ServeVersionedOpenAPI(v1, v2 middlewares.RequestHandler) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		switch ctx.APIVersion() {
+		case middlewares.APIVersion2:
+			v2(ctx)
+		default:
+			v1(ctx)
+		}
+	}
+}
+
 // ServeTemplatedOpenAPI serves templated OpenAPI related files.
 func ServeTemplatedOpenAPI(t templates.Template, opts *TemplatedFileOptions) middlewares.RequestHandler {
 	ext := path.Ext(t.Name())
@@ -109,9 +135,16 @@ func ServeTemplatedOpenAPI(t templates.Template, opts *TemplatedFileOptions) mid
 	return func(ctx *middlewares.AutheliaCtx) {
 		var nonce string
 
-		if spec {
+		switch {
+		case opts.Headers.OpenAPI.HasContentSecurityPolicy():
+			if !spec {
+				nonce = ctx.Providers.Random.StringCustom(32, random.CharSetAlphaNumeric)
+			}
+
+			opts.Headers.OpenAPI.SetContentSecurityPolicy(ctx.RequestCtx, nonce)
+		case spec:
 			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, tmplCSPSwagger)
-		} else {
+		default:
 			nonce = ctx.Providers.Random.StringCustom(32, random.CharSetAlphaNumeric)
 			ctx.Response.Header.Add(fasthttp.HeaderContentSecurityPolicy, fmt.Sprintf(tmplCSPSwaggerNonce, nonce, nonce))
 		}
@@ -146,7 +179,7 @@ func ServeTemplatedOpenAPI(t templates.Template, opts *TemplatedFileOptions) mid
 
 		data := &bytes.Buffer{}
 
-		if err = t.Execute(data, opts.OpenAPIData(ctx.BasePath(), baseURL, domain, nonce)); err != nil {
+		if err = t.Execute(data, opts.OpenAPIData(ctx.BasePath(), baseURL, domain, nonce, ctx.APIVersion())); err != nil {
 			ctx.RequestCtx.Error("an error occurred", fasthttp.StatusServiceUnavailable)
 			ctx.Logger.WithError(err).Errorf("Error occcurred rendering template")
 
@@ -169,6 +202,36 @@ func ServeTemplatedOpenAPI(t templates.Template, opts *TemplatedFileOptions) mid
 	}
 }
 
+// ServeBrandingLogo serves the logo from the branding of the domain of the request, or passes the request to the next
+// handler if the branding has no logo.
+func ServeBrandingLogo(next fasthttp.RequestHandler) middlewares.RequestHandler {
+	return func(ctx *middlewares.AutheliaCtx) {
+		var domain string
+
+		if provider, err := ctx.GetSessionProvider(); err == nil {
+			domain = provider.Config.Domain
+		}
+
+		if logo := ctx.Providers.Branding.Get(domain).Logo; logo != "" {
+			fasthttp.ServeFile(ctx.RequestCtx, logo)
+
+			return
+		}
+
+		next(ctx.RequestCtx)
+	}
+}
+
+// cspImageSource returns the img-src directive which allows the origin of the branding logo URL to be loaded by the
+// default content security policy, or an empty string if there is no branding logo URL.
+func cspImageSource(logo *url.URL) string {
+	if logo == nil || !logo.IsAbs() || logo.Host == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(tmplCSPImageSource, logo.Scheme, logo.Host)
+}
+
 // ETagRootURL dynamically matches the If-None-Match header and adds the ETag header.
 func ETagRootURL(next middlewares.RequestHandler) middlewares.RequestHandler {
 	etags := map[string][]byte{}
@@ -266,6 +329,7 @@ func NewTemplatedFileOptions(config *schema.Configuration) (opts *TemplatedFileO
 	opts = &TemplatedFileOptions{
 		AssetPath:              config.Server.AssetPath,
 		DuoSelfEnrollment:      strFalse,
+		Features:               strings.Join(middlewares.NewFeatures(config).Names(), ","),
 		RememberMe:             strconv.FormatBool(!config.Session.DisableRememberMe),
 		ResetPassword:          strconv.FormatBool(!config.AuthenticationBackend.PasswordReset.Disable),
 		ResetPasswordCustomURL: config.AuthenticationBackend.PasswordReset.CustomURL.String(),
@@ -276,9 +340,21 @@ func NewTemplatedFileOptions(config *schema.Configuration) (opts *TemplatedFileO
 		EndpointsPasswordReset: !(config.AuthenticationBackend.PasswordReset.Disable || config.AuthenticationBackend.PasswordReset.CustomURL.String() != ""),
 		EndpointsWebAuthn:      !config.WebAuthn.Disable,
 		EndpointsTOTP:          !config.TOTP.Disable,
+		EndpointsSMS:           !config.SMS.Disable,
+		EndpointsEmail:         config.Email.Enable,
 		EndpointsDuo:           !config.DuoAPI.Disable,
+		EndpointsPush:          !config.Push.Disable,
+		EndpointsPushChallenge: !config.Push.Disable && utils.IsStringInSlice(config.Push.Provider, []string{schema.PushProviderMobile, schema.PushProviderNtfy, schema.PushProviderGotify}),
 		EndpointsOpenIDConnect: !(config.IdentityProviders.OIDC == nil),
+		EndpointsHeadless:      config.Server.Headless.Enabled,
+		EndpointsMobile:        config.Server.Endpoints.Mobile.Enabled,
+		EndpointsMobilePush:    config.Server.Endpoints.Mobile.Enabled && !config.Push.Disable && config.Push.Provider == schema.PushProviderMobile,
+		EndpointsSPNEGO:        config.AuthenticationBackend.SPNEGO.Enabled,
+		EndpointsPasskey:       !config.WebAuthn.Disable && config.WebAuthn.PasskeyLogin.Enable,
+		EndpointsSAML:          !(config.IdentityProviders.SAML == nil),
 		EndpointsAuthz:         config.Server.Endpoints.Authz,
+
+		Headers: middlewares.NewSecurityHeaderPolicies(config.Server.Headers),
 	}
 
 	if config.PrivacyPolicy.Enabled {
@@ -297,6 +373,7 @@ func NewTemplatedFileOptions(config *schema.Configuration) (opts *TemplatedFileO
 type TemplatedFileOptions struct {
 	AssetPath              string
 	DuoSelfEnrollment      string
+	Features               string
 	RememberMe             string
 	ResetPassword          string
 	ResetPasswordCustomURL string
@@ -308,10 +385,22 @@ type TemplatedFileOptions struct {
 	EndpointsPasswordReset bool
 	EndpointsWebAuthn      bool
 	EndpointsTOTP          bool
+	EndpointsSMS           bool
+	EndpointsEmail         bool
 	EndpointsDuo           bool
+	EndpointsPush          bool
+	EndpointsPushChallenge bool
 	EndpointsOpenIDConnect bool
+	EndpointsHeadless      bool
+	EndpointsMobile        bool
+	EndpointsMobilePush    bool
+	EndpointsSPNEGO        bool
+	EndpointsPasskey       bool
+	EndpointsSAML          bool
 
 	EndpointsAuthz map[string]schema.ServerEndpointsAuthz
+
+	Headers *middlewares.SecurityHeaderPolicies
 }
 
 // CommonData returns a TemplatedFileCommonData with the dynamic options.
@@ -327,6 +416,7 @@ func (options *TemplatedFileOptions) CommonData(base, baseURL, domain, nonce, lo
 		CSPNonce:               nonce,
 		LogoOverride:           logoOverride,
 		DuoSelfEnrollment:      options.DuoSelfEnrollment,
+		Features:               options.Features,
 		RememberMe:             options.RememberMe,
 		ResetPassword:          options.ResetPassword,
 		ResetPasswordCustomURL: options.ResetPasswordCustomURL,
@@ -346,6 +436,7 @@ func (options *TemplatedFileOptions) commonDataWithRememberMe(base, baseURL, dom
 		CSPNonce:               nonce,
 		LogoOverride:           logoOverride,
 		DuoSelfEnrollment:      options.DuoSelfEnrollment,
+		Features:               options.Features,
 		RememberMe:             rememberMe,
 		ResetPassword:          options.ResetPassword,
 		ResetPasswordCustomURL: options.ResetPasswordCustomURL,
@@ -355,19 +446,36 @@ func (options *TemplatedFileOptions) commonDataWithRememberMe(base, baseURL, dom
 }
 
 // OpenAPIData returns a TemplatedFileOpenAPIData with the dynamic options.
-func (options *TemplatedFileOptions) OpenAPIData(base, baseURL, domain, nonce string) TemplatedFileOpenAPIData {
+func (options *TemplatedFileOptions) OpenAPIData(base, baseURL, domain, nonce string, version int) TemplatedFileOpenAPIData {
+	pathAPI := pathAPIVersion1
+
+	if version == middlewares.APIVersion2 {
+		pathAPI = pathAPIVersion2
+	}
+
 	return TemplatedFileOpenAPIData{
 		Base:     base,
 		BaseURL:  baseURL,
 		Domain:   domain,
 		CSPNonce: nonce,
+		PathAPI:  pathAPI,
 
 		Session:        options.Session,
 		PasswordReset:  options.EndpointsPasswordReset,
 		WebAuthn:       options.EndpointsWebAuthn,
 		TOTP:           options.EndpointsTOTP,
+		SMS:            options.EndpointsSMS,
+		Email:          options.EndpointsEmail,
 		Duo:            options.EndpointsDuo,
+		Push:           options.EndpointsPush,
+		PushChallenge:  options.EndpointsPushChallenge,
 		OpenIDConnect:  options.EndpointsOpenIDConnect,
+		Headless:       options.EndpointsHeadless,
+		Mobile:         options.EndpointsMobile,
+		MobilePush:     options.EndpointsMobilePush,
+		SPNEGO:         options.EndpointsSPNEGO,
+		Passkey:        options.EndpointsPasskey,
+		SAML:           options.EndpointsSAML,
 		EndpointsAuthz: options.EndpointsAuthz,
 	}
 }
@@ -380,6 +488,7 @@ type TemplatedFileCommonData struct {
 	CSPNonce               string
 	LogoOverride           string
 	DuoSelfEnrollment      string
+	Features               string
 	RememberMe             string
 	ResetPassword          string
 	ResetPasswordCustomURL string
@@ -387,6 +496,30 @@ type TemplatedFileCommonData struct {
 	PrivacyPolicyAccept    string
 	Session                string
 	Theme                  string
+
+	BrandingTitle          string
+	BrandingPrimaryColor   string
+	BrandingSecondaryColor string
+	BrandingFooter         string
+	BrandingMessage        string
+	BrandingHead           string
+}
+
+// setBranding sets the values of the branding and renders the head partial of the branding with the common data.
+func (data *TemplatedFileCommonData) setBranding(b *branding.Branding) (err error) {
+	data.BrandingTitle = b.Title
+	data.BrandingPrimaryColor = b.PrimaryColor
+	data.BrandingSecondaryColor = b.SecondaryColor
+	data.BrandingFooter = b.Footer
+	data.BrandingMessage = b.Message
+
+	if b.Logo != "" {
+		data.LogoOverride = strTrue
+	}
+
+	data.BrandingHead, err = b.Head(*data)
+
+	return err
 }
 
 // TemplatedFileOpenAPIData is a struct which is used for the OpenAPI spec file.
@@ -395,12 +528,23 @@ type TemplatedFileOpenAPIData struct {
 	BaseURL       string
 	Domain        string
 	CSPNonce      string
+	PathAPI       string
 	Session       string
 	PasswordReset bool
 	WebAuthn      bool
 	TOTP          bool
+	SMS           bool
+	Email         bool
 	Duo           bool
+	Push          bool
+	PushChallenge bool
 	OpenIDConnect bool
+	Headless      bool
+	Mobile        bool
+	MobilePush    bool
+	SPNEGO        bool
+	Passkey       bool
+	SAML          bool
 
 	EndpointsAuthz map[string]schema.ServerEndpointsAuthz
 }
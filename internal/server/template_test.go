@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"io/fs"
 	"net/url"
 	"os"
@@ -11,14 +12,17 @@ import (
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/templates"
 )
 
 const (
-	assetsOpenAPIPath = "public_html/api/openapi.yml"
-	localOpenAPIPath  = "../../api/openapi.yml"
+	assetsOpenAPIPath   = "public_html/api/openapi.yml"
+	localOpenAPIPath    = "../../api/openapi.yml"
+	assetsOpenAPIV2Path = "public_html/api/openapi.v2.yml"
+	localOpenAPIV2Path  = "../../api/openapi.v2.yml"
 )
 
 type ReadFileOpenAPI struct{}
@@ -27,6 +31,8 @@ func (lfs *ReadFileOpenAPI) Open(name string) (fs.File, error) {
 	switch name {
 	case assetsOpenAPIPath:
 		return os.Open(localOpenAPIPath)
+	case assetsOpenAPIV2Path:
+		return os.Open(localOpenAPIV2Path)
 	default:
 		return assets.Open(name)
 	}
@@ -36,6 +42,8 @@ func (lfs *ReadFileOpenAPI) ReadFile(name string) ([]byte, error) {
 	switch name {
 	case assetsOpenAPIPath:
 		return os.ReadFile(localOpenAPIPath)
+	case assetsOpenAPIV2Path:
+		return os.ReadFile(localOpenAPIV2Path)
 	default:
 		return assets.ReadFile(name)
 	}
@@ -62,7 +70,7 @@ func TestShouldTemplateOpenAPI(t *testing.T) {
 		},
 	}
 
-	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
 
 	opts := NewTemplatedFileOptions(&mock.Ctx.Configuration)
 
@@ -80,4 +88,85 @@ func TestShouldTemplateOpenAPI(t *testing.T) {
 
 	assert.NotEqual(t, "", body)
 	assert.Contains(t, body, "example: 'https://auth.example.com/?rd=https%3A%2F%2Fexample.com%2F&rm=GET'")
+	assert.NotContains(t, body, "  /api/csrf:\n")
+	assert.NotContains(t, body, "  /api/mobile/authenticate:\n")
+	assert.NotContains(t, body, "  /api/firstfactor/spnego:\n")
+	assert.NotContains(t, body, "  /api/firstfactor/passkey:\n")
+}
+
+func TestShouldTemplateOpenAPIVersion2(t *testing.T) {
+	provider, err := templates.New(templates.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, provider.LoadTemplatedAssets(&ReadFileOpenAPI{}))
+
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	mock.Ctx.Configuration.Server = schema.DefaultServerConfiguration
+	mock.Ctx.Configuration.Server.Headless.Enabled = true
+	mock.Ctx.Configuration.Server.Endpoints.Mobile.Enabled = true
+	mock.Ctx.Configuration.AuthenticationBackend.SPNEGO.Enabled = true
+	mock.Ctx.Configuration.WebAuthn.PasskeyLogin.Enable = true
+	mock.Ctx.Configuration.IdentityProviders.SAML = &schema.IdentityProvidersSAML{}
+	mock.Ctx.Configuration.Session = schema.Session{
+		Cookies: []schema.SessionCookie{
+			{
+				Domain: "example.com",
+
+				AutheliaURL: &url.URL{Scheme: "https", Host: "auth.example.com", Path: "/"},
+			},
+		},
+	}
+
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+
+	opts := NewTemplatedFileOptions(&mock.Ctx.Configuration)
+
+	handler := ServeVersionedOpenAPI(
+		ServeTemplatedOpenAPI(provider.GetAssetOpenAPISpecTemplate(), opts),
+		ServeTemplatedOpenAPI(provider.GetAssetOpenAPISpecV2Template(), opts),
+	)
+
+	mock.Ctx.SetUserValue(middlewares.UserValueKeyAPIVersion, middlewares.APIVersion2)
+
+	handler(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	body := string(mock.Ctx.Response.Body())
+
+	assert.Contains(t, body, "  version: 2.0.0\n")
+	assert.Contains(t, body, "  /api/v2/user/info:\n")
+	assert.Contains(t, body, "  /api/oidc/consent:\n")
+	assert.Contains(t, body, "  /api/v2/csrf:\n")
+	assert.Contains(t, body, "  /api/v2/mobile/authenticate:\n")
+	assert.Contains(t, body, "  /api/v2/firstfactor/spnego:\n")
+	assert.Contains(t, body, "  /api/v2/firstfactor/passkey:\n")
+	assert.Contains(t, body, "  /api/saml/sso:\n")
+	assert.Contains(t, body, "    mobile_auth:\n")
+	assert.NotContains(t, body, "  /api/user/info:\n")
+	assert.Contains(t, body, "$ref: '#/components/schemas/middlewares.ErrorCode'")
+
+	for _, code := range []middlewares.ErrorCode{middlewares.ErrorCodeOperationFailed, middlewares.ErrorCodeNotFound, middlewares.ErrorCodeServerError} {
+		assert.Contains(t, body, fmt.Sprintf("        - '%s'\n", code))
+	}
+}
+
+func TestCSPImageSource(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     *url.URL
+		expected string
+	}{
+		{"ShouldReturnEmptyNil", nil, ""},
+		{"ShouldReturnEmptyRelative", &url.URL{Path: "/logo.svg"}, ""},
+		{"ShouldReturnOrigin", &url.URL{Scheme: "https", Host: "cdn.example.com", Path: "/brand/logo.svg"}, "; img-src 'self' https://cdn.example.com"},
+		{"ShouldReturnOriginWithPort", &url.URL{Scheme: "https", Host: "cdn.example.com:8443", Path: "/logo.svg"}, "; img-src 'self' https://cdn.example.com:8443"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, cspImageSource(tc.have))
+		})
+	}
 }
@@ -11,7 +11,17 @@ const (
 	testUsername   = "john"
 )
 
+// Provider names.
+const (
+	ProviderNameMemory        = "memory"
+	ProviderNameRedis         = "redis"
+	ProviderNameRedisSentinel = "redis-sentinel"
+	ProviderNameRedisCluster  = "redis-cluster"
+	ProviderNameSQL           = "sql"
+)
+
 const (
 	userSessionStorerKey = "UserSession"
 	randomSessionChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+	healthCheckSessionID = "health-check"
 )
@@ -1,31 +1,48 @@
 package session
 
 import (
+	"context"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/fasthttp/session/v2"
 
+	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // Provider contains a list of domain sessions.
 type Provider struct {
 	sessions map[string]*Session
+
+	name       string
+	provider   session.Provider
+	serializer Serializer
+
+	revocations *Revocations
+	lifespan    time.Duration
 }
 
-// NewProvider instantiate a session provider given a configuration.
-func NewProvider(config schema.Session, certPool *x509.CertPool) *Provider {
-	log := logging.Logger()
+// NewProvider instantiate a session provider given a configuration. The storage provider is only used when the sessions
+// are stored in the SQL storage.
+func NewProvider(config schema.Session, certPool *x509.CertPool, store storage.SessionProvider) *Provider {
+	log := logging.LoggerModule(logging.ModuleSession)
 
-	name, p, s, err := NewSessionProvider(config, certPool)
+	name, p, s, err := NewSessionProvider(config, certPool, store)
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	provider := &Provider{
-		sessions: map[string]*Session{},
+		sessions:    map[string]*Session{},
+		name:        name,
+		provider:    p,
+		serializer:  s,
+		revocations: NewRevocations(clock.New()),
 	}
 
 	var (
@@ -40,12 +57,42 @@ func NewProvider(config schema.Session, certPool *x509.CertPool) *Provider {
 		provider.sessions[dconfig.Domain] = &Session{
 			Config:        dconfig,
 			sessionHolder: holder,
+			revocations:   provider.revocations,
 		}
+
+		provider.lifespan = max(provider.lifespan, dconfig.Expiration, dconfig.RememberMe)
 	}
 
 	return provider
 }
 
+// IsRedis returns true if the sessions are stored in Redis.
+func (p *Provider) IsRedis() bool {
+	return p.name == ProviderNameRedis || p.name == ProviderNameRedisSentinel || p.name == ProviderNameRedisCluster
+}
+
+// HealthCheck implements the health check provider interface. It checks the connection to Redis if it's configured.
+func (p *Provider) HealthCheck(_ context.Context) (err error) {
+	if !p.IsRedis() || p.provider == nil {
+		return nil
+	}
+
+	if _, err = p.provider.Get([]byte(healthCheckSessionID)); err != nil {
+		return fmt.Errorf("error checking redis connection: %w", err)
+	}
+
+	return nil
+}
+
+// Count returns the number of sessions stored by the session provider.
+func (p *Provider) Count() (count int) {
+	if p.provider == nil {
+		return 0
+	}
+
+	return p.provider.Count()
+}
+
 // Get returns session information for specified domain.
 func (p *Provider) Get(domain string) (*Session, error) {
 	if domain == "" {
@@ -60,3 +107,75 @@ func (p *Provider) Get(domain string) (*Session, error) {
 
 	return s, nil
 }
+
+// LoadUserSessionByID loads the user session with the given session id directly from the session provider, i.e.
+// without a request for the session. The found value is false if the session does not exist.
+func (p *Provider) LoadUserSessionByID(id []byte) (userSession UserSession, found bool, err error) {
+	if p.provider == nil || len(id) == 0 {
+		return userSession, false, nil
+	}
+
+	var data []byte
+
+	if data, err = p.provider.Get(id); err != nil {
+		return userSession, false, fmt.Errorf("error retrieving the session: %w", err)
+	}
+
+	if len(data) == 0 {
+		return userSession, false, nil
+	}
+
+	dict := session.Dict{KV: map[string]any{}}
+
+	if p.serializer != nil {
+		err = p.serializer.Decode(&dict, data)
+	} else {
+		err = session.Base64Decode(&dict, data)
+	}
+
+	if err != nil {
+		return userSession, false, fmt.Errorf("error decoding the session: %w", err)
+	}
+
+	userSessionJSON, ok := dict.KV[userSessionStorerKey].([]byte)
+	if !ok {
+		return userSession, false, nil
+	}
+
+	if err = json.Unmarshal(userSessionJSON, &userSession); err != nil {
+		return userSession, false, fmt.Errorf("error unmarshalling the user session: %w", err)
+	}
+
+	return userSession, true, nil
+}
+
+// DestroySessionByID destroys the session with the given session id directly in the session provider, i.e. without a
+// request for the session.
+func (p *Provider) DestroySessionByID(id []byte) (err error) {
+	if p.provider == nil || len(id) == 0 {
+		return nil
+	}
+
+	return p.provider.Destroy(id)
+}
+
+// RevokeSessionByID destroys the session with the given session id directly in the session provider and adds it to the
+// revocations, so the session is refused even if a request which was in flight when it was revoked saves it again. The
+// revocation expires when the session would have expired at the latest, which is returned so it can be shared with the
+// other replicas.
+func (p *Provider) RevokeSessionByID(id []byte) (expiresAt time.Time, err error) {
+	if err = p.DestroySessionByID(id); err != nil {
+		return expiresAt, err
+	}
+
+	expiresAt = p.revocations.clock.Now().Add(p.lifespan)
+
+	p.revocations.Revoke(storage.SessionSignature(id), expiresAt)
+
+	return expiresAt, nil
+}
+
+// Revocations returns the revocations of the sessions which are checked by each of the cookie domain sessions.
+func (p *Provider) Revocations() *Revocations {
+	return p.revocations
+}
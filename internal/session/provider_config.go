@@ -4,17 +4,21 @@ import (
 	"crypto/rand"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/fasthttp/session/v2"
 	"github.com/fasthttp/session/v2/providers/memory"
 	"github.com/fasthttp/session/v2/providers/redis"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
+	"github.com/authelia/authelia/v4/internal/clock"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -93,9 +97,18 @@ func NewProviderConfigAndSession(config schema.SessionCookie, providerName strin
 	return c, p, nil
 }
 
-func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name string, provider session.Provider, serializer Serializer, err error) {
-	// If redis configuration is provided, then use the redis provider.
+func NewSessionProvider(config schema.Session, certPool *x509.CertPool, store storage.SessionProvider) (name string, provider session.Provider, serializer Serializer, err error) {
+	// If the sql storage or the redis configuration is provided, then use the respective provider.
 	switch {
+	case config.Storage == schema.SessionStorageSQL:
+		name = ProviderNameSQL
+		serializer = NewEncryptingSerializer(config.Secret)
+
+		if store == nil {
+			return name, nil, serializer, errors.New("error configuring the sql session provider: the storage provider is not configured")
+		}
+
+		provider = NewSQLProvider(store, clock.New())
 	case config.Redis != nil:
 		serializer = NewEncryptingSerializer(config.Secret)
 
@@ -105,7 +118,41 @@ func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name st
 			tlsConfig = utils.NewTLSConfig(config.Redis.TLS, certPool)
 		}
 
-		if config.Redis.HighAvailability != nil && config.Redis.HighAvailability.SentinelName != "" {
+		switch {
+		case config.Redis.Cluster != nil:
+			addrs := make([]string, 0)
+
+			if config.Redis.Host != "" {
+				addrs = append(addrs, fmt.Sprintf("%s:%d", strings.ToLower(config.Redis.Host), config.Redis.Port))
+			}
+
+			for _, node := range config.Redis.Cluster.Nodes {
+				addr := fmt.Sprintf("%s:%d", strings.ToLower(node.Host), node.Port)
+				if !utils.IsStringInSlice(addr, addrs) {
+					addrs = append(addrs, addr)
+				}
+			}
+
+			name = ProviderNameRedisCluster
+
+			goredis.SetLogger(logging.LoggerCtxPrintfModule(logrus.TraceLevel, logging.ModuleSession))
+
+			var cluster *RedisClusterProvider
+
+			if cluster, err = NewRedisClusterProvider(&goredis.ClusterOptions{
+				Addrs:          addrs,
+				MaxRedirects:   config.Redis.Cluster.MaximumRedirects,
+				RouteByLatency: config.Redis.Cluster.RouteByLatency,
+				RouteRandomly:  config.Redis.Cluster.RouteRandomly,
+				Username:       config.Redis.Username,
+				Password:       config.Redis.Password,
+				PoolSize:       config.Redis.MaximumActiveConnections,
+				MinIdleConns:   config.Redis.MinimumIdleConnections,
+				TLSConfig:      tlsConfig,
+			}, "authelia-session"); err == nil {
+				provider = cluster
+			}
+		case config.Redis.HighAvailability != nil && config.Redis.HighAvailability.SentinelName != "":
 			addrs := make([]string, 0)
 
 			if config.Redis.Host != "" {
@@ -119,10 +166,10 @@ func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name st
 				}
 			}
 
-			name = "redis-sentinel"
+			name = ProviderNameRedisSentinel
 
 			provider, err = redis.NewFailover(redis.FailoverConfig{
-				Logger:           logging.LoggerCtxPrintf(logrus.TraceLevel),
+				Logger:           logging.LoggerCtxPrintfModule(logrus.TraceLevel, logging.ModuleSession),
 				MasterName:       config.Redis.HighAvailability.SentinelName,
 				SentinelAddrs:    addrs,
 				SentinelUsername: config.Redis.HighAvailability.SentinelUsername,
@@ -138,8 +185,8 @@ func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name st
 				TLSConfig:        tlsConfig,
 				KeyPrefix:        "authelia-session",
 			})
-		} else {
-			name = "redis"
+		default:
+			name = ProviderNameRedis
 			network := "tcp"
 
 			var addr string
@@ -152,7 +199,7 @@ func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name st
 			}
 
 			provider, err = redis.New(redis.Config{
-				Logger:          logging.LoggerCtxPrintf(logrus.TraceLevel),
+				Logger:          logging.LoggerCtxPrintfModule(logrus.TraceLevel, logging.ModuleSession),
 				Network:         network,
 				Addr:            addr,
 				Username:        config.Redis.Username,
@@ -166,7 +213,7 @@ func NewSessionProvider(config schema.Session, certPool *x509.CertPool) (name st
 			})
 		}
 	default:
-		name = "memory"
+		name = ProviderNameMemory
 		provider, err = memory.New(memory.Config{})
 	}
 
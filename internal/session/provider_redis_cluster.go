@@ -0,0 +1,125 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClusterProvider returns a new RedisClusterProvider after checking the connection to the cluster.
+func NewRedisClusterProvider(options *redis.ClusterOptions, prefix string) (provider *RedisClusterProvider, err error) {
+	provider = &RedisClusterProvider{
+		client: redis.NewClusterClient(options),
+		prefix: prefix,
+	}
+
+	if err = provider.client.Ping(context.Background()).Err(); err != nil {
+		_ = provider.client.Close()
+
+		return nil, fmt.Errorf("error connecting to the redis cluster: %w", err)
+	}
+
+	return provider, nil
+}
+
+// RedisClusterProvider is a session.Provider which stores the sessions in a Redis Cluster. The cluster client follows
+// the MOVED and ASK redirects and refreshes the topology of the cluster as the slots are migrated or fail over.
+//
+// The keys share the prefix as a hash tag so every session is stored in the same hash slot, which permits the multi-key
+// operations such as regenerating the session id with RENAME.
+type RedisClusterProvider struct {
+	client *redis.ClusterClient
+	prefix string
+}
+
+// Get returns the data of the session with the given id.
+func (p *RedisClusterProvider) Get(id []byte) (data []byte, err error) {
+	if data, err = p.client.Get(context.Background(), p.key(id)).Bytes(); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save the data and expiration of the session with the given id.
+func (p *RedisClusterProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	return p.client.Set(context.Background(), p.key(id), data, expiration).Err()
+}
+
+// Regenerate changes the id of the session with the given id to the new id and updates the expiration.
+func (p *RedisClusterProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	ctx := context.Background()
+
+	key, newKey := p.key(id), p.key(newID)
+
+	var exists int64
+
+	if exists, err = p.client.Exists(ctx, key).Result(); err != nil || exists == 0 {
+		return err
+	}
+
+	_, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Rename(ctx, key, newKey)
+		pipe.Expire(ctx, newKey, expiration)
+
+		return nil
+	})
+
+	return err
+}
+
+// Destroy the session with the given id.
+func (p *RedisClusterProvider) Destroy(id []byte) (err error) {
+	return p.client.Del(context.Background(), p.key(id)).Err()
+}
+
+// Count returns the number of sessions stored in the cluster. The keys are scanned on every master as the cluster
+// client otherwise sends the commands without a key to a single random node.
+func (p *RedisClusterProvider) Count() (count int) {
+	ctx := context.Background()
+
+	var total atomic.Int64
+
+	err := p.client.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) (err error) {
+		iter := client.Scan(ctx, 0, p.key([]byte("*")), 1000).Iterator()
+
+		for iter.Next(ctx) {
+			total.Add(1)
+		}
+
+		return iter.Err()
+	})
+	if err != nil {
+		return 0
+	}
+
+	return int(total.Load())
+}
+
+// NeedGC returns false as the sessions are expired by Redis.
+func (p *RedisClusterProvider) NeedGC() bool {
+	return false
+}
+
+// GC is a no-op as the sessions are expired by Redis.
+func (p *RedisClusterProvider) GC() (err error) {
+	return nil
+}
+
+// Close the connections to the cluster.
+func (p *RedisClusterProvider) Close() (err error) {
+	return p.client.Close()
+}
+
+func (p *RedisClusterProvider) key(id []byte) string {
+	return redisClusterKey(p.prefix, id)
+}
+
+// redisClusterKey returns the key of the session with the prefix as the hash tag.
+func redisClusterKey(prefix string, id []byte) string {
+	return "{" + prefix + "}:" + string(id)
+}
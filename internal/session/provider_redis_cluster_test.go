@@ -0,0 +1,35 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestRedisClusterKey(t *testing.T) {
+	assert.Equal(t, "{authelia-session}:abc123", redisClusterKey("authelia-session", []byte("abc123")))
+	assert.Equal(t, "{authelia-session}:*", redisClusterKey("authelia-session", []byte("*")))
+}
+
+func TestShouldReturnErrorWhenRedisClusterUnavailable(t *testing.T) {
+	config := schema.Session{
+		Secret: "abc",
+		Redis: &schema.SessionRedis{
+			Cluster: &schema.SessionRedisCluster{
+				MaximumRedirects: 3,
+				Nodes: []schema.SessionRedisHighAvailabilityNode{
+					{Host: "127.0.0.1", Port: 1},
+				},
+			},
+		},
+	}
+
+	name, provider, serializer, err := NewSessionProvider(config, nil, nil)
+
+	assert.Equal(t, ProviderNameRedisCluster, name)
+	assert.Nil(t, provider)
+	assert.NotNil(t, serializer)
+	assert.ErrorContains(t, err, "error connecting to the redis cluster")
+}
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// NewSQLProvider returns a new SQLProvider which stores the sessions in the given storage provider.
+func NewSQLProvider(store storage.SessionProvider, clock clock.Provider) *SQLProvider {
+	return &SQLProvider{
+		store: store,
+		clock: clock,
+	}
+}
+
+// SQLProvider is a session.Provider which stores the sessions in the SQL storage provider, so the replicas of small
+// highly available deployments can share the sessions without a Redis server. The data is the same serialized and
+// encrypted data as the Redis providers store.
+//
+// Each row has the time the session expires, the sessions which have expired are never loaded or counted and are
+// deleted by the periodic storage cleanup.
+type SQLProvider struct {
+	store storage.SessionProvider
+	clock clock.Provider
+}
+
+// Get returns the data of the session with the given id.
+func (p *SQLProvider) Get(id []byte) (data []byte, err error) {
+	if data, err = p.store.LoadSession(context.Background(), id, p.clock.Now()); err != nil {
+		if errors.Is(err, storage.ErrNoSession) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save the data and expiration of the session with the given id.
+func (p *SQLProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	return p.store.SaveSession(context.Background(), id, data, p.clock.Now().Add(expiration))
+}
+
+// Regenerate changes the id of the session with the given id to the new id and updates the expiration.
+func (p *SQLProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	return p.store.RegenerateSession(context.Background(), id, newID, p.clock.Now().Add(expiration))
+}
+
+// Destroy the session with the given id.
+func (p *SQLProvider) Destroy(id []byte) (err error) {
+	return p.store.DeleteSession(context.Background(), id)
+}
+
+// Count returns the number of sessions which have not expired.
+func (p *SQLProvider) Count() (count int) {
+	var err error
+
+	if count, err = p.store.CountSessions(context.Background(), p.clock.Now()); err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// NeedGC returns false as the expired sessions are deleted by the storage cleanup.
+func (p *SQLProvider) NeedGC() bool {
+	return false
+}
+
+// GC is a no-op as the expired sessions are deleted by the storage cleanup.
+func (p *SQLProvider) GC() (err error) {
+	return nil
+}
@@ -0,0 +1,152 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestSQLProvider(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+	store := &testSQLStore{sessions: map[string]testSQLSession{}}
+
+	provider := NewSQLProvider(store, c)
+
+	assert.False(t, provider.NeedGC())
+	assert.NoError(t, provider.GC())
+
+	data, err := provider.Get([]byte("abc123"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, provider.Save([]byte("abc123"), []byte("data"), time.Hour))
+	assert.Equal(t, now.Add(time.Hour), store.sessions["abc123"].expiresAt)
+
+	data, err = provider.Get([]byte("abc123"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+	assert.Equal(t, 1, provider.Count())
+
+	require.NoError(t, provider.Regenerate([]byte("abc123"), []byte("def456"), time.Hour*2))
+
+	data, err = provider.Get([]byte("abc123"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	data, err = provider.Get([]byte("def456"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	c.Set(now.Add(time.Hour * 3))
+
+	data, err = provider.Get([]byte("def456"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, 0, provider.Count())
+
+	require.NoError(t, provider.Save([]byte("ghi789"), []byte("data"), time.Hour))
+	require.NoError(t, provider.Destroy([]byte("ghi789")))
+	assert.NotContains(t, store.sessions, "ghi789")
+
+	store.err = errors.New("bad connection")
+
+	data, err = provider.Get([]byte("def456"))
+	assert.EqualError(t, err, "bad connection")
+	assert.Nil(t, data)
+	assert.Equal(t, 0, provider.Count())
+}
+
+func TestShouldReturnErrorWhenSQLStorageNotConfigured(t *testing.T) {
+	config := schema.Session{
+		Secret:  "abc",
+		Storage: schema.SessionStorageSQL,
+	}
+
+	name, provider, serializer, err := NewSessionProvider(config, nil, nil)
+
+	assert.Equal(t, ProviderNameSQL, name)
+	assert.Nil(t, provider)
+	assert.NotNil(t, serializer)
+	assert.EqualError(t, err, "error configuring the sql session provider: the storage provider is not configured")
+
+	name, provider, _, err = NewSessionProvider(config, nil, &testSQLStore{})
+
+	assert.Equal(t, ProviderNameSQL, name)
+	assert.IsType(t, &SQLProvider{}, provider)
+	assert.NoError(t, err)
+}
+
+type testSQLSession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+type testSQLStore struct {
+	sessions map[string]testSQLSession
+	err      error
+}
+
+func (s *testSQLStore) SaveSession(_ context.Context, sessionID, data []byte, expiresAt time.Time) (err error) {
+	s.sessions[string(sessionID)] = testSQLSession{data: data, expiresAt: expiresAt}
+
+	return s.err
+}
+
+func (s *testSQLStore) LoadSession(_ context.Context, sessionID []byte, now time.Time) (data []byte, err error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	session, ok := s.sessions[string(sessionID)]
+	if !ok || !session.expiresAt.After(now) {
+		return nil, storage.ErrNoSession
+	}
+
+	return session.data, nil
+}
+
+func (s *testSQLStore) RegenerateSession(_ context.Context, sessionID, newSessionID []byte, expiresAt time.Time) (err error) {
+	if session, ok := s.sessions[string(sessionID)]; ok {
+		delete(s.sessions, string(sessionID))
+
+		session.expiresAt = expiresAt
+
+		s.sessions[string(newSessionID)] = session
+	}
+
+	return s.err
+}
+
+func (s *testSQLStore) DeleteSession(_ context.Context, sessionID []byte) (err error) {
+	delete(s.sessions, string(sessionID))
+
+	return s.err
+}
+
+func (s *testSQLStore) CountSessions(_ context.Context, now time.Time) (count int, err error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	for _, session := range s.sessions {
+		if session.expiresAt.After(now) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *testSQLStore) DeleteExpiredSessions(_ context.Context, _ time.Time, _ int) (deleted int64, err error) {
+	return 0, s.err
+}
@@ -25,7 +25,7 @@ func newTestSession() (*Session, error) {
 		},
 	}
 
-	provider := NewProvider(config, nil)
+	provider := NewProvider(config, nil, nil)
 
 	return provider.Get(testDomain)
 }
@@ -326,3 +326,50 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	assert.Equal(t, "", newUserSession.Username)
 	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
 }
+
+func TestShouldLoadAndDestroyUserSessionByID(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	config := schema.Session{}
+	config.Cookies = []schema.SessionCookie{
+		{
+			SessionCookieCommon: schema.SessionCookieCommon{
+				Name:       testName,
+				Expiration: testExpiration,
+			},
+			Domain: testDomain,
+		},
+	}
+
+	provider := NewProvider(config, nil, nil)
+
+	domainSession, err := provider.Get(testDomain)
+	assert.NoError(t, err)
+
+	session, err := domainSession.GetSession(ctx)
+	assert.NoError(t, err)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	assert.NoError(t, domainSession.SaveSession(ctx, session))
+
+	id := domainSession.GetSessionID(ctx)
+	assert.Len(t, id, 32)
+
+	loaded, found, err := provider.LoadUserSessionByID(id)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, testUsername, loaded.Username)
+	assert.Equal(t, authentication.OneFactor, loaded.AuthenticationLevel)
+
+	assert.NoError(t, provider.DestroySessionByID(id))
+
+	_, found, err = provider.LoadUserSessionByID(id)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	_, found, err = provider.LoadUserSessionByID(nil)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
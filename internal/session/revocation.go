@@ -0,0 +1,165 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// NewRevocations returns a new empty Revocations.
+func NewRevocations(clock clock.Provider) *Revocations {
+	return &Revocations{
+		clock:   clock,
+		entries: map[string]time.Time{},
+	}
+}
+
+// Revocations is the list of the sessions which have been revoked, keyed by the signature of the session id. A session
+// which is revoked is destroyed in the session provider, however a request which was in flight when it was revoked may
+// still save it again, so the revocations are checked when the session of a request is retrieved. Each revocation is
+// only kept until the session would have expired.
+type Revocations struct {
+	clock clock.Provider
+
+	mu      sync.RWMutex
+	entries map[string]time.Time
+}
+
+// Revoke adds the session with the given signature to the revocations until the given time.
+func (r *Revocations) Revoke(signature string, expiresAt time.Time) {
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	if current, ok := r.entries[signature]; ok && current.After(expiresAt) {
+		return
+	}
+
+	r.entries[signature] = expiresAt
+}
+
+// IsRevoked returns true if the session with the given session id has been revoked.
+func (r *Revocations) IsRevoked(id []byte) bool {
+	if len(id) == 0 {
+		return false
+	}
+
+	r.mu.RLock()
+
+	defer r.mu.RUnlock()
+
+	if len(r.entries) == 0 {
+		return false
+	}
+
+	expiresAt, ok := r.entries[storage.SessionSignature(id)]
+
+	return ok && expiresAt.After(r.clock.Now())
+}
+
+// Len returns the number of revocations.
+func (r *Revocations) Len() int {
+	r.mu.RLock()
+
+	defer r.mu.RUnlock()
+
+	return len(r.entries)
+}
+
+// Prune removes the revocations of the sessions which would have expired.
+func (r *Revocations) Prune() {
+	now := r.clock.Now()
+
+	r.mu.Lock()
+
+	defer r.mu.Unlock()
+
+	for signature, expiresAt := range r.entries {
+		if !expiresAt.After(now) {
+			delete(r.entries, signature)
+		}
+	}
+}
+
+// NewRevocationSync returns a new RevocationSync which loads the revocations from the storage provider at the given
+// interval.
+func NewRevocationSync(revocations *Revocations, store storage.UserSessionRevocationProvider, interval time.Duration, clock clock.Provider, log *logrus.Entry) *RevocationSync {
+	return &RevocationSync{
+		revocations: revocations,
+		store:       store,
+		interval:    interval,
+		clock:       clock,
+		log:         log,
+	}
+}
+
+// RevocationSync periodically loads the revocations of the sessions saved to the storage provider into the
+// Revocations, which broadcasts the revocations made by any of the replicas to all of the replicas. The first sync loads
+// all of the revocations which have not expired, and each subsequent sync loads the revocations made since the previous
+// sync with an overlap of one interval so revocations committed late by another replica are not missed.
+type RevocationSync struct {
+	revocations *Revocations
+	store       storage.UserSessionRevocationProvider
+	interval    time.Duration
+	clock       clock.Provider
+	log         *logrus.Entry
+
+	since time.Time
+}
+
+// Run the RevocationSync until the context is canceled.
+func (s *RevocationSync) Run(ctx context.Context) {
+	s.log.WithFields(map[string]any{"interval": s.interval.String()}).Debug("Loading the session revocations periodically")
+
+	ticker := time.NewTicker(s.interval)
+
+	defer ticker.Stop()
+
+	s.sync(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sync(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *RevocationSync) sync(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+
+	defer cancel()
+
+	var (
+		revocations []model.UserSessionRevocation
+		err         error
+	)
+
+	now := s.clock.Now()
+
+	if revocations, err = s.store.LoadUserSessionRevocations(ctx, s.since, now); err != nil {
+		s.log.WithError(err).Error("Error occurred loading the session revocations")
+
+		return
+	}
+
+	for _, revocation := range revocations {
+		s.revocations.Revoke(revocation.Signature, revocation.ExpiresAt)
+	}
+
+	s.revocations.Prune()
+
+	s.since = now.Add(-s.interval)
+
+	if len(revocations) != 0 {
+		s.log.WithFields(map[string]any{"loaded": len(revocations), "revocations": s.revocations.Len()}).Debug("Loaded the session revocations")
+	}
+}
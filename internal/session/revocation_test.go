@@ -0,0 +1,176 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestRevocations(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := clock.NewFixed(now)
+
+	revocations := NewRevocations(c)
+
+	assert.False(t, revocations.IsRevoked([]byte("abc")))
+	assert.False(t, revocations.IsRevoked(nil))
+
+	revocations.Revoke(storage.SessionSignature([]byte("abc")), now.Add(time.Hour))
+	revocations.Revoke(storage.SessionSignature([]byte("def")), now.Add(time.Minute))
+
+	assert.True(t, revocations.IsRevoked([]byte("abc")))
+	assert.True(t, revocations.IsRevoked([]byte("def")))
+	assert.False(t, revocations.IsRevoked([]byte("ghi")))
+
+	revocations.Revoke(storage.SessionSignature([]byte("abc")), now.Add(time.Minute))
+
+	c.Set(now.Add(time.Minute * 2))
+
+	assert.True(t, revocations.IsRevoked([]byte("abc")))
+	assert.False(t, revocations.IsRevoked([]byte("def")))
+	assert.Equal(t, 2, revocations.Len())
+
+	revocations.Prune()
+
+	assert.Equal(t, 1, revocations.Len())
+}
+
+func TestRevocationSync(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := clock.NewFixed(now)
+
+	store := &testRevocationStore{
+		revocations: []model.UserSessionRevocation{
+			{ID: 1, Signature: storage.SessionSignature([]byte("abc")), RevokedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		},
+	}
+
+	log, hook := test.NewNullLogger()
+
+	revocations := NewRevocations(c)
+
+	sync := NewRevocationSync(revocations, store, time.Second*10, c, logrus.NewEntry(log))
+
+	sync.sync(context.Background())
+
+	assert.True(t, revocations.IsRevoked([]byte("abc")))
+	assert.Equal(t, []time.Time{{}}, store.since)
+
+	c.Set(now.Add(time.Second * 10))
+
+	store.err = errors.New("bad connection")
+
+	sync.sync(context.Background())
+
+	assert.Equal(t, []time.Time{{}, now.Add(-time.Second * 10)}, store.since)
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Error occurred loading the session revocations", hook.LastEntry().Message)
+
+	store.err = nil
+	store.revocations = []model.UserSessionRevocation{
+		{ID: 2, Signature: storage.SessionSignature([]byte("def")), RevokedAt: now.Add(time.Second * 5), ExpiresAt: now.Add(time.Hour)},
+	}
+
+	sync.sync(context.Background())
+
+	assert.Equal(t, []time.Time{{}, now.Add(-time.Second * 10), now.Add(-time.Second * 10)}, store.since)
+	assert.True(t, revocations.IsRevoked([]byte("abc")))
+	assert.True(t, revocations.IsRevoked([]byte("def")))
+
+	sync.sync(context.Background())
+
+	assert.Equal(t, now, store.since[3])
+}
+
+func TestShouldRefuseRevokedSession(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	config := schema.Session{}
+	config.Cookies = []schema.SessionCookie{
+		{
+			SessionCookieCommon: schema.SessionCookieCommon{
+				Name:       testName,
+				Expiration: testExpiration,
+				RememberMe: time.Hour,
+			},
+			Domain: testDomain,
+		},
+	}
+
+	provider := NewProvider(config, nil, nil)
+
+	domainSession, err := provider.Get(testDomain)
+	require.NoError(t, err)
+
+	session, err := domainSession.GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, domainSession.SaveSession(ctx, session))
+
+	id := domainSession.GetSessionID(ctx)
+
+	expiresAt, err := provider.RevokeSessionByID(id)
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	_, found, err := provider.LoadUserSessionByID(id)
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// A request which was in flight when the session was revoked saves it again.
+	require.NoError(t, domainSession.SaveSession(ctx, session))
+
+	_, found, err = provider.LoadUserSessionByID(id)
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	session, err = domainSession.GetSession(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "", session.Username)
+	assert.Equal(t, authentication.NotAuthenticated, session.AuthenticationLevel)
+
+	_, found, err = provider.LoadUserSessionByID(id)
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+type testRevocationStore struct {
+	revocations []model.UserSessionRevocation
+	err         error
+	since       []time.Time
+}
+
+func (s *testRevocationStore) SaveUserSessionRevocation(_ context.Context, _ []byte, _, _ time.Time) (err error) {
+	return nil
+}
+
+func (s *testRevocationStore) LoadUserSessionRevocations(_ context.Context, since, _ time.Time) (revocations []model.UserSessionRevocation, err error) {
+	s.since = append(s.since, since)
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.revocations, nil
+}
+
+func (s *testRevocationStore) DeleteExpiredUserSessionRevocations(_ context.Context, _ time.Time, _ int) (deleted int64, err error) {
+	return 0, nil
+}
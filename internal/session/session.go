@@ -15,6 +15,7 @@ type Session struct {
 	Config schema.SessionCookie
 
 	sessionHolder *session.Session
+	revocations   *Revocations
 }
 
 // NewDefaultUserSession returns a new default UserSession for this session provider.
@@ -30,6 +31,16 @@ func (p *Session) NewDefaultUserSession() (userSession UserSession) {
 func (p *Session) GetSession(ctx *fasthttp.RequestCtx) (userSession UserSession, err error) {
 	var store *session.Store
 
+	// A session which has been revoked is destroyed again as a request which was in flight when it was revoked may have
+	// saved it again after it was destroyed.
+	if p.revocations != nil && p.revocations.IsRevoked(p.GetSessionID(ctx)) {
+		if err = p.sessionHolder.Destroy(ctx); err != nil {
+			return p.NewDefaultUserSession(), err
+		}
+
+		return p.NewDefaultUserSession(), nil
+	}
+
 	if store, err = p.sessionHolder.Get(ctx); err != nil {
 		return p.NewDefaultUserSession(), err
 	}
@@ -82,6 +93,11 @@ func (p *Session) RegenerateSession(ctx *fasthttp.RequestCtx) error {
 	return p.sessionHolder.Regenerate(ctx)
 }
 
+// GetSessionID returns the session id of the session from a request.
+func (p *Session) GetSessionID(ctx *fasthttp.RequestCtx) (id []byte) {
+	return ctx.Request.Header.Cookie(p.Config.Name)
+}
+
 // DestroySession destroy a session ID and delete the cookie.
 func (p *Session) DestroySession(ctx *fasthttp.RequestCtx) error {
 	return p.sessionHolder.Destroy(ctx)
@@ -39,11 +39,22 @@ type UserSession struct {
 	// WebAuthn holds the session registration data for this session.
 	WebAuthn *WebAuthn
 	TOTP     *TOTP
+	Push     *Push
+	SMS      *SMS
+	Email    *Email
 
 	// This boolean is set to true after identity verification and checked
 	// while doing the query actually updating the password.
 	PasswordResetUsername *string
 
+	// IdentityVerificationJTI is the JTI of the most recent identity verification token issued to this session which
+	// is checked when the tokens are bound to the session which issued them.
+	IdentityVerificationJTI string
+
+	// CSRFToken is the token which must be included in the requests which use an unsafe method when the headless mode is
+	// enabled.
+	CSRFToken string
+
 	RefreshTTL time.Time
 
 	Elevations Elevations
@@ -59,6 +70,30 @@ type TOTP struct {
 	Expires   time.Time
 }
 
+// SMS holds the SMS registration session data. The phone number is only saved once the One-Time Code sent to it has
+// been verified.
+type SMS struct {
+	PhoneNumber string
+	Expires     time.Time
+}
+
+// Email holds the Email second factor session data. The One-Time Code which was sent can only be validated by the
+// session which requested it, and it's revoked once the maximum number of failed attempts is reached.
+type Email struct {
+	Signature  string
+	TargetURL  string
+	Workflow   string
+	WorkflowID string
+	Attempts   int
+	Expires    time.Time
+}
+
+// Push holds the push started with the push second factor which is polled by the portal.
+type Push struct {
+	TransactionID string
+	Started       time.Time
+}
+
 // WebAuthn holds the standard WebAuthn session data plus some extra.
 type WebAuthn struct {
 	*webauthn.SessionData
@@ -38,6 +38,23 @@ func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDe
 	s.AuthenticationMethodRefs.UsernameAndPassword = true
 }
 
+// SetOneFactorPasskey replaces the username and password AMR set by SetOneFactor with the relevant WebAuthn AMR's as
+// the first factor was performed with a passkey.
+func (s *UserSession) SetOneFactorPasskey(hardware, userPresence, userVerified bool) {
+	s.AuthenticationMethodRefs.UsernameAndPassword = false
+
+	s.AuthenticationMethodRefs.WebAuthn = true
+	s.AuthenticationMethodRefs.WebAuthnUserPresence, s.AuthenticationMethodRefs.WebAuthnUserVerified = userPresence, userVerified
+
+	if hardware {
+		s.AuthenticationMethodRefs.WebAuthnHardware = true
+	} else {
+		s.AuthenticationMethodRefs.WebAuthnSoftware = true
+	}
+
+	s.WebAuthn = nil
+}
+
 func (s *UserSession) setTwoFactor(now time.Time) {
 	s.SecondFactorAuthnTimestamp = now.Unix()
 	s.LastActivity = now.Unix()
@@ -56,6 +73,36 @@ func (s *UserSession) SetTwoFactorDuo(now time.Time) {
 	s.AuthenticationMethodRefs.Duo = true
 }
 
+// SetTwoFactorPush sets the relevant push notification AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorPush(now time.Time) {
+	s.setTwoFactor(now)
+	s.AuthenticationMethodRefs.Push = true
+}
+
+// SetTwoFactorRecoveryCode sets the relevant recovery code AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorRecoveryCode(now time.Time) {
+	s.setTwoFactor(now)
+	s.AuthenticationMethodRefs.RecoveryCode = true
+}
+
+// SetTwoFactorSMS sets the relevant SMS or voice AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorSMS(now time.Time, voice bool) {
+	s.setTwoFactor(now)
+
+	if voice {
+		s.AuthenticationMethodRefs.Voice = true
+	} else {
+		s.AuthenticationMethodRefs.SMS = true
+	}
+}
+
+// SetTwoFactorEmail sets the relevant Email AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorEmail(now time.Time) {
+	s.setTwoFactor(now)
+
+	s.AuthenticationMethodRefs.Email = true
+}
+
 // SetTwoFactorWebAuthn sets the relevant WebAuthn AMR's and sets the factor to 2FA.
 func (s *UserSession) SetTwoFactorWebAuthn(now time.Time, hardware, userPresence, userVerified bool) {
 	s.setTwoFactor(now)
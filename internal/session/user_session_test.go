@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
@@ -55,6 +56,52 @@ func TestUserSession_SetTwoFactorWebAuthn(t *testing.T) {
 	}
 }
 
+func TestUserSession_SetOneFactorPasskey(t *testing.T) {
+	actual := &UserSession{AuthenticationMethodRefs: oidc.AuthenticationMethodsReferences{UsernameAndPassword: true}, WebAuthn: &WebAuthn{}}
+
+	actual.SetOneFactorPasskey(false, true, true)
+
+	assert.Equal(t, oidc.AuthenticationMethodsReferences{
+		WebAuthn:             true,
+		WebAuthnSoftware:     true,
+		WebAuthnUserPresence: true,
+		WebAuthnUserVerified: true,
+	}, actual.AuthenticationMethodRefs)
+	assert.Nil(t, actual.WebAuthn)
+	assert.Equal(t, authentication.NotAuthenticated, actual.AuthenticationLevel)
+}
+
+func TestUserSession_SetTwoFactorSMS(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	actual := &UserSession{}
+
+	actual.SetTwoFactorSMS(now, false)
+
+	assert.Equal(t, oidc.AuthenticationMethodsReferences{SMS: true}, actual.AuthenticationMethodRefs)
+	assert.Equal(t, authentication.TwoFactor, actual.AuthenticationLevel)
+	assert.Equal(t, now.Unix(), actual.SecondFactorAuthnTimestamp)
+
+	actual = &UserSession{}
+
+	actual.SetTwoFactorSMS(now, true)
+
+	assert.Equal(t, oidc.AuthenticationMethodsReferences{Voice: true}, actual.AuthenticationMethodRefs)
+	assert.Equal(t, authentication.TwoFactor, actual.AuthenticationLevel)
+}
+
+func TestUserSession_SetTwoFactorEmail(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	actual := &UserSession{}
+
+	actual.SetTwoFactorEmail(now)
+
+	assert.Equal(t, oidc.AuthenticationMethodsReferences{Email: true}, actual.AuthenticationMethodRefs)
+	assert.Equal(t, authentication.TwoFactor, actual.AuthenticationLevel)
+	assert.Equal(t, now.Unix(), actual.SecondFactorAuthnTimestamp)
+}
+
 func TestUserSession_Misc(t *testing.T) {
 	session := &UserSession{}
 
@@ -0,0 +1,34 @@
+package sms
+
+const (
+	pathTwilioMessages = "/2010-04-01/Accounts/%s/Messages.json"
+	pathTwilioCalls    = "/2010-04-01/Accounts/%s/Calls.json"
+
+	pathVonageSMS   = "/sms/json"
+	pathVonageCalls = "/v1/calls"
+
+	vonageStatusSuccess = "0"
+	vonageTypePhone     = "phone"
+	vonageActionTalk    = "talk"
+
+	// voiceRepetitions is the number of times the One-Time Code is read during a voice call.
+	voiceRepetitions = 2
+
+	// vonageTokenLifespan is the lifespan of the JWT which authenticates the requests to the Vonage Voice API.
+	vonageTokenLifespan = 60
+)
+
+// Channels.
+const (
+	ChannelSMS   = "sms"
+	ChannelVoice = "voice"
+)
+
+const (
+	headerAccept        = "Accept"
+	headerContentType   = "Content-Type"
+	headerAuthorization = "Authorization"
+
+	contentTypeApplicationJSON = "application/json"
+	contentTypeApplicationForm = "application/x-www-form-urlencoded"
+)
@@ -0,0 +1,71 @@
+package sms
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewHTTP creates a Provider which delivers the One-Time Codes by posting them to a generic HTTP gateway.
+func NewHTTP(config *schema.SMSHTTP, trusted *x509.CertPool) *HTTP {
+	provider := &HTTP{
+		client: newHTTPClient(config.TLS, config.Timeout, trusted),
+		url:    config.URL,
+	}
+
+	if config.Token != "" {
+		provider.authorization = "Bearer " + config.Token
+	}
+
+	return provider
+}
+
+// HTTP is a Provider backed by a generic HTTP gateway. Each One-Time Code is posted to the gateway as a HTTPRequest
+// which includes both the rendered message and the One-Time Code so the gateway can choose how to deliver it.
+type HTTP struct {
+	client        *http.Client
+	url           *url.URL
+	authorization string
+}
+
+// Send posts the One-Time Code to the gateway.
+func (h *HTTP) Send(ctx context.Context, message *Message) (err error) {
+	request := HTTPRequest{
+		To:      message.To,
+		Channel: message.Channel(),
+		Code:    message.Code,
+		Message: message.Text(),
+	}
+
+	if message.Voice {
+		request.Message = message.Speech()
+	}
+
+	var req *http.Request
+
+	if req, err = newJSONRequest(ctx, h.url.String(), request); err != nil {
+		return fmt.Errorf("http gateway %s request failed: %w", message.Channel(), err)
+	}
+
+	if h.authorization != "" {
+		req.Header.Set(headerAuthorization, h.authorization)
+	}
+
+	if err = doRequest(h.client, req, nil); err != nil {
+		return fmt.Errorf("http gateway %s request failed: %w", message.Channel(), err)
+	}
+
+	return nil
+}
+
+// HTTPRequest is the body of the requests to the generic HTTP gateway.
+type HTTPRequest struct {
+	To      string `json:"to"`
+	Channel string `json:"channel"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
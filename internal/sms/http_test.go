@@ -0,0 +1,73 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestHTTP(t *testing.T, token string, handler http.HandlerFunc) *HTTP {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL + "/send")
+	require.NoError(t, err)
+
+	return NewHTTP(&schema.SMSHTTP{URL: uri, Token: token, Timeout: time.Second}, nil)
+}
+
+func TestHTTPSend(t *testing.T) {
+	var request HTTPRequest
+
+	provider := newTestHTTP(t, "abc123", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/send", r.URL.Path)
+		assert.Equal(t, "Bearer abc123", r.Header.Get(headerAuthorization))
+		assert.Equal(t, contentTypeApplicationJSON, r.Header.Get(headerContentType))
+
+		request = HTTPRequest{}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Lifespan: time.Minute}))
+
+	assert.Equal(t, HTTPRequest{To: "+15550100123", Channel: ChannelSMS, Code: "123456", Message: "Your Authelia verification code is 123456. It expires in 1 minutes."}, request)
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Voice: true}))
+
+	assert.Equal(t, HTTPRequest{To: "+15550100123", Channel: ChannelVoice, Code: "123456", Message: "Your Authelia verification code is 1, 2, 3, 4, 5, 6."}, request)
+}
+
+func TestHTTPSendError(t *testing.T) {
+	provider := newTestHTTP(t, "", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get(headerAuthorization))
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	err := provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456"})
+
+	assert.EqualError(t, err, "http gateway sms request failed: the server responded with status code 503")
+}
+
+func TestNewProvider(t *testing.T) {
+	uri := &url.URL{Scheme: "https", Host: "example.com"}
+
+	assert.IsType(t, &Twilio{}, NewProvider(&schema.SMS{Provider: schema.SMSProviderTwilio, Twilio: &schema.SMSTwilio{URL: uri}}, nil))
+	assert.IsType(t, &Vonage{}, NewProvider(&schema.SMS{Provider: schema.SMSProviderVonage, Vonage: &schema.SMSVonage{URL: uri, VoiceURL: uri}}, nil))
+	assert.IsType(t, &HTTP{}, NewProvider(&schema.SMS{Provider: schema.SMSProviderHTTP, HTTP: &schema.SMSHTTP{URL: uri}}, nil))
+	assert.Nil(t, NewProvider(&schema.SMS{}, nil))
+}
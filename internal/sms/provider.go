@@ -0,0 +1,130 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// Provider is a SMS provider which delivers One-Time Codes to phone numbers by SMS or voice call.
+type Provider interface {
+	Send(ctx context.Context, message *Message) (err error)
+}
+
+// NewProvider creates the SMS provider configured by the provider option of the configuration.
+func NewProvider(config *schema.SMS, trusted *x509.CertPool) Provider {
+	switch config.Provider {
+	case schema.SMSProviderTwilio:
+		return NewTwilio(config.Twilio, trusted)
+	case schema.SMSProviderVonage:
+		return NewVonage(config.Vonage, trusted)
+	case schema.SMSProviderHTTP:
+		return NewHTTP(config.HTTP, trusted)
+	default:
+		return nil
+	}
+}
+
+func newHTTPClient(config *schema.TLS, timeout time.Duration, trusted *x509.CertPool) *http.Client {
+	transport := &http.Transport{}
+
+	if config != nil {
+		transport.TLSClientConfig = utils.NewTLSConfig(config, trusted)
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// endpoint returns the string form of the base URL with the path joined to it.
+func endpoint(base *url.URL, p string) string {
+	uri := *base
+
+	uri.Path = path.Join(uri.Path, p)
+
+	return uri.String()
+}
+
+// doRequest performs a request and decodes the JSON response body into v. The response body is decoded into v even
+// when the server responds with an error status code as the providers describe the errors in the response body.
+func doRequest(client *http.Client, req *http.Request, v any) (err error) {
+	req.Header.Set(headerAccept, contentTypeApplicationJSON)
+
+	var resp *http.Response
+
+	if resp, err = client.Do(req); err != nil {
+		return fmt.Errorf("error performing the request: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	var data []byte
+
+	if data, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err != nil {
+		return fmt.Errorf("error reading the response: %w", err)
+	}
+
+	if v != nil && len(data) != 0 && strings.HasPrefix(resp.Header.Get(headerContentType), contentTypeApplicationJSON) {
+		if err = json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("error decoding the response with status code %d: %w", resp.StatusCode, err)
+		}
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return &statusError{code: resp.StatusCode}
+	}
+
+	return nil
+}
+
+// newFormRequest returns a request with the form encoded as the request body.
+func newFormRequest(ctx context.Context, uri string, form url.Values) (req *http.Request, err error) {
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, uri, strings.NewReader(form.Encode())); err != nil {
+		return nil, fmt.Errorf("error creating the request: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeApplicationForm)
+
+	return req, nil
+}
+
+// newJSONRequest returns a request with the request encoded as the JSON request body.
+func newJSONRequest(ctx context.Context, uri string, request any) (req *http.Request, err error) {
+	var data []byte
+
+	if data, err = json.Marshal(request); err != nil {
+		return nil, fmt.Errorf("error encoding the request: %w", err)
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, uri, bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error creating the request: %w", err)
+	}
+
+	req.Header.Set(headerContentType, contentTypeApplicationJSON)
+
+	return req, nil
+}
+
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("the server responded with status code %d", e.code)
+}
+
+var (
+	_ Provider = (*Twilio)(nil)
+	_ Provider = (*Vonage)(nil)
+	_ Provider = (*HTTP)(nil)
+)
@@ -0,0 +1,91 @@
+package sms
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewTwilio creates a Provider which delivers the One-Time Codes with the Twilio Programmable Messaging and
+// Programmable Voice APIs.
+func NewTwilio(config *schema.SMSTwilio, trusted *x509.CertPool) *Twilio {
+	return &Twilio{
+		client:     newHTTPClient(config.TLS, config.Timeout, trusted),
+		url:        config.URL,
+		accountSID: config.AccountSID,
+		authToken:  config.AuthToken,
+		from:       config.From,
+	}
+}
+
+// Twilio is a Provider backed by the Twilio APIs.
+type Twilio struct {
+	client     *http.Client
+	url        *url.URL
+	accountSID string
+	authToken  string
+	from       string
+}
+
+// Send delivers the One-Time Code with a SMS or a voice call.
+func (t *Twilio) Send(ctx context.Context, message *Message) (err error) {
+	form := url.Values{}
+	form.Set("To", message.To)
+	form.Set("From", t.from)
+
+	var p string
+
+	if message.Voice {
+		p = fmt.Sprintf(pathTwilioCalls, url.PathEscape(t.accountSID))
+
+		form.Set("Twiml", twilioTwiML(message))
+	} else {
+		p = fmt.Sprintf(pathTwilioMessages, url.PathEscape(t.accountSID))
+
+		form.Set("Body", message.Text())
+	}
+
+	var req *http.Request
+
+	if req, err = newFormRequest(ctx, endpoint(t.url, p), form); err != nil {
+		return fmt.Errorf("twilio %s request failed: %w", message.Channel(), err)
+	}
+
+	req.SetBasicAuth(t.accountSID, t.authToken)
+
+	response := TwilioErrorResponse{}
+
+	if err = doRequest(t.client, req, &response); err != nil {
+		if response.Message != "" {
+			return fmt.Errorf("twilio %s request failed with error code %d: %s: %w", message.Channel(), response.Code, response.Message, err)
+		}
+
+		return fmt.Errorf("twilio %s request failed: %w", message.Channel(), err)
+	}
+
+	return nil
+}
+
+// twilioTwiML returns the TwiML document which reads the One-Time Code during the voice call.
+func twilioTwiML(message *Message) string {
+	says := make([]string, voiceRepetitions)
+
+	for i := range says {
+		says[i] = fmt.Sprintf(`<Say>%s</Say><Pause length="1"/>`, html.EscapeString(message.Speech()))
+	}
+
+	return "<Response>" + strings.Join(says, "") + "</Response>"
+}
+
+// TwilioErrorResponse is the body of the error responses of the Twilio APIs.
+type TwilioErrorResponse struct {
+	Code     int    `json:"code"`
+	Message  string `json:"message"`
+	MoreInfo string `json:"more_info"`
+}
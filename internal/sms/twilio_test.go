@@ -0,0 +1,97 @@
+package sms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestTwilio(t *testing.T, handler http.HandlerFunc) *Twilio {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return NewTwilio(&schema.SMSTwilio{URL: uri, AccountSID: "AC123", AuthToken: "secret", From: "+15550100999", Timeout: time.Second}, nil)
+}
+
+func TestTwilioSend(t *testing.T) {
+	var form url.Values
+
+	provider := newTestTwilio(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+
+		username, password, ok := r.BasicAuth()
+
+		assert.True(t, ok)
+		assert.Equal(t, "AC123", username)
+		assert.Equal(t, "secret", password)
+
+		require.NoError(t, r.ParseForm())
+
+		form = r.PostForm
+
+		w.Header().Set(headerContentType, contentTypeApplicationJSON)
+		w.WriteHeader(http.StatusCreated)
+
+		_, _ = w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+	})
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Lifespan: time.Minute * 5}))
+
+	assert.Equal(t, "+15550100123", form.Get("To"))
+	assert.Equal(t, "+15550100999", form.Get("From"))
+	assert.Equal(t, "Your Authelia verification code is 123456. It expires in 5 minutes.", form.Get("Body"))
+}
+
+func TestTwilioSendVoice(t *testing.T) {
+	var form url.Values
+
+	provider := newTestTwilio(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2010-04-01/Accounts/AC123/Calls.json", r.URL.Path)
+
+		require.NoError(t, r.ParseForm())
+
+		form = r.PostForm
+
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "1234", Voice: true}))
+
+	assert.Equal(t, "+15550100123", form.Get("To"))
+	assert.Equal(t, `<Response><Say>Your Authelia verification code is 1, 2, 3, 4.</Say><Pause length="1"/><Say>Your Authelia verification code is 1, 2, 3, 4.</Say><Pause length="1"/></Response>`, form.Get("Twiml"))
+	assert.Equal(t, "", form.Get("Body"))
+}
+
+func TestTwilioSendError(t *testing.T) {
+	provider := newTestTwilio(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, contentTypeApplicationJSON)
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, _ = w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number.","status":400}`))
+	})
+
+	err := provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456"})
+
+	assert.EqualError(t, err, "twilio sms request failed with error code 21211: The 'To' number is not a valid phone number.: the server responded with status code 400")
+
+	provider = newTestTwilio(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	err = provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Voice: true})
+
+	assert.EqualError(t, err, "twilio voice request failed: the server responded with status code 500")
+}
@@ -0,0 +1,48 @@
+package sms
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message is a One-Time Code to be delivered to a phone number.
+type Message struct {
+	// To is the phone number in the E.164 format.
+	To string
+
+	// Code is the One-Time Code.
+	Code string
+
+	// Voice is true when the One-Time Code should be delivered with a voice call instead of a SMS.
+	Voice bool
+
+	// Lifespan is the duration the One-Time Code is valid for.
+	Lifespan time.Duration
+}
+
+// Channel returns the channel the message is delivered with.
+func (m *Message) Channel() string {
+	if m.Voice {
+		return ChannelVoice
+	}
+
+	return ChannelSMS
+}
+
+// Text returns the text of the SMS which delivers the One-Time Code.
+func (m *Message) Text() string {
+	if minutes := int(m.Lifespan.Minutes()); minutes > 0 {
+		return fmt.Sprintf("Your Authelia verification code is %s. It expires in %d minutes.", m.Code, minutes)
+	}
+
+	return fmt.Sprintf("Your Authelia verification code is %s.", m.Code)
+}
+
+// Speech returns the text read during the voice call which delivers the One-Time Code. The digits of the One-Time Code
+// are separated so they are read individually.
+func (m *Message) Speech() string {
+	digits := strings.Join(strings.Split(m.Code, ""), ", ")
+
+	return fmt.Sprintf("Your Authelia verification code is %s.", digits)
+}
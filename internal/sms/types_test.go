@@ -0,0 +1,22 @@
+package sms
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	message := &Message{To: "+15550100123", Code: "123456", Lifespan: time.Minute * 5}
+
+	assert.Equal(t, ChannelSMS, message.Channel())
+	assert.Equal(t, "Your Authelia verification code is 123456. It expires in 5 minutes.", message.Text())
+	assert.Equal(t, "Your Authelia verification code is 1, 2, 3, 4, 5, 6.", message.Speech())
+
+	message.Voice = true
+	message.Lifespan = time.Second * 30
+
+	assert.Equal(t, ChannelVoice, message.Channel())
+	assert.Equal(t, "Your Authelia verification code is 123456.", message.Text())
+}
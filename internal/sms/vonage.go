@@ -0,0 +1,187 @@
+package sms
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewVonage creates a Provider which delivers the One-Time Codes with the Vonage SMS and Voice APIs.
+func NewVonage(config *schema.SMSVonage, trusted *x509.CertPool) *Vonage {
+	return &Vonage{
+		client:        newHTTPClient(config.TLS, config.Timeout, trusted),
+		url:           config.URL,
+		voiceURL:      config.VoiceURL,
+		apiKey:        config.APIKey,
+		apiSecret:     config.APISecret,
+		from:          config.From,
+		applicationID: config.ApplicationID,
+		privateKey:    config.PrivateKey,
+	}
+}
+
+// Vonage is a Provider backed by the Vonage APIs. The SMS are sent with the SMS API which is authenticated with the API
+// Key and API Secret, and the voice calls are placed with the Voice API which is authenticated with a JWT signed by the
+// private key of the Vonage application.
+type Vonage struct {
+	client        *http.Client
+	url           *url.URL
+	voiceURL      *url.URL
+	apiKey        string
+	apiSecret     string
+	from          string
+	applicationID string
+	privateKey    *rsa.PrivateKey
+}
+
+// Send delivers the One-Time Code with a SMS or a voice call.
+func (v *Vonage) Send(ctx context.Context, message *Message) (err error) {
+	if message.Voice {
+		if err = v.call(ctx, message); err != nil {
+			return fmt.Errorf("vonage voice request failed: %w", err)
+		}
+
+		return nil
+	}
+
+	if err = v.sms(ctx, message); err != nil {
+		return fmt.Errorf("vonage sms request failed: %w", err)
+	}
+
+	return nil
+}
+
+func (v *Vonage) sms(ctx context.Context, message *Message) (err error) {
+	form := url.Values{}
+	form.Set("api_key", v.apiKey)
+	form.Set("api_secret", v.apiSecret)
+	form.Set("from", strings.TrimPrefix(v.from, "+"))
+	form.Set("to", strings.TrimPrefix(message.To, "+"))
+	form.Set("text", message.Text())
+
+	var req *http.Request
+
+	if req, err = newFormRequest(ctx, endpoint(v.url, pathVonageSMS), form); err != nil {
+		return err
+	}
+
+	response := VonageSMSResponse{}
+
+	if err = doRequest(v.client, req, &response); err != nil {
+		return err
+	}
+
+	if len(response.Messages) == 0 {
+		return errors.New("the server did not respond with the status of the message")
+	}
+
+	for _, m := range response.Messages {
+		if m.Status != vonageStatusSuccess {
+			return fmt.Errorf("the server responded with status %s: %s", m.Status, m.ErrorText)
+		}
+	}
+
+	return nil
+}
+
+func (v *Vonage) call(ctx context.Context, message *Message) (err error) {
+	if v.privateKey == nil {
+		return errors.New("the provider is not configured with a private key")
+	}
+
+	var token string
+
+	if token, err = v.token(time.Now()); err != nil {
+		return fmt.Errorf("error signing the token: %w", err)
+	}
+
+	request := VonageCallRequest{
+		To:   []VonageEndpoint{{Type: vonageTypePhone, Number: strings.TrimPrefix(message.To, "+")}},
+		From: VonageEndpoint{Type: vonageTypePhone, Number: strings.TrimPrefix(v.from, "+")},
+		NCCO: []VonageAction{{Action: vonageActionTalk, Text: message.Speech(), Loop: voiceRepetitions}},
+	}
+
+	var req *http.Request
+
+	if req, err = newJSONRequest(ctx, endpoint(v.voiceURL, pathVonageCalls), request); err != nil {
+		return err
+	}
+
+	req.Header.Set(headerAuthorization, "Bearer "+token)
+
+	response := VonageErrorResponse{}
+
+	if err = doRequest(v.client, req, &response); err != nil {
+		if response.Title != "" {
+			return fmt.Errorf("%s: %s: %w", response.Title, response.Detail, err)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// token returns the JWT which authenticates the requests to the Vonage Voice API.
+func (v *Vonage) token(now time.Time) (token string, err error) {
+	claims := jwt.MapClaims{
+		"application_id": v.applicationID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Second * vonageTokenLifespan).Unix(),
+		"jti":            uuid.NewString(),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(v.privateKey)
+}
+
+// VonageSMSResponse is the body of the responses of the Vonage SMS API.
+type VonageSMSResponse struct {
+	MessageCount string             `json:"message-count"`
+	Messages     []VonageSMSMessage `json:"messages"`
+}
+
+// VonageSMSMessage is the status of a message in the responses of the Vonage SMS API.
+type VonageSMSMessage struct {
+	To        string `json:"to"`
+	MessageID string `json:"message-id"`
+	Status    string `json:"status"`
+	ErrorText string `json:"error-text"`
+}
+
+// VonageCallRequest is the body of the requests to the Vonage Voice API which create a call.
+type VonageCallRequest struct {
+	To   []VonageEndpoint `json:"to"`
+	From VonageEndpoint   `json:"from"`
+	NCCO []VonageAction   `json:"ncco"`
+}
+
+// VonageEndpoint is an endpoint of a call.
+type VonageEndpoint struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
+
+// VonageAction is an action of the Call Control Object of a call.
+type VonageAction struct {
+	Action string `json:"action"`
+	Text   string `json:"text"`
+	Loop   int    `json:"loop,omitempty"`
+}
+
+// VonageErrorResponse is the body of the error responses of the Vonage Voice API.
+type VonageErrorResponse struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
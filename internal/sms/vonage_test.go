@@ -0,0 +1,108 @@
+package sms
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestVonage(t *testing.T, key *rsa.PrivateKey, handler http.HandlerFunc) *Vonage {
+	server := httptest.NewServer(handler)
+
+	t.Cleanup(server.Close)
+
+	uri, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return NewVonage(&schema.SMSVonage{URL: uri, VoiceURL: uri, APIKey: "abc", APISecret: "123", From: "+15550100999", ApplicationID: "app-id", PrivateKey: key, Timeout: time.Second}, nil)
+}
+
+func TestVonageSend(t *testing.T) {
+	var form url.Values
+
+	provider := newTestVonage(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/sms/json", r.URL.Path)
+
+		require.NoError(t, r.ParseForm())
+
+		form = r.PostForm
+
+		w.Header().Set(headerContentType, contentTypeApplicationJSON)
+
+		_, _ = w.Write([]byte(`{"message-count":"1","messages":[{"to":"15550100123","message-id":"abc","status":"0"}]}`))
+	})
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Lifespan: time.Minute * 5}))
+
+	assert.Equal(t, "abc", form.Get("api_key"))
+	assert.Equal(t, "123", form.Get("api_secret"))
+	assert.Equal(t, "15550100999", form.Get("from"))
+	assert.Equal(t, "15550100123", form.Get("to"))
+	assert.Equal(t, "Your Authelia verification code is 123456. It expires in 5 minutes.", form.Get("text"))
+}
+
+func TestVonageSendError(t *testing.T) {
+	provider := newTestVonage(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerContentType, contentTypeApplicationJSON)
+
+		_, _ = w.Write([]byte(`{"message-count":"1","messages":[{"status":"4","error-text":"Bad Credentials"}]}`))
+	})
+
+	err := provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456"})
+	assert.EqualError(t, err, "vonage sms request failed: the server responded with status 4: Bad Credentials")
+
+	err = provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Voice: true})
+	assert.EqualError(t, err, "vonage voice request failed: the provider is not configured with a private key")
+}
+
+func TestVonageSendVoice(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var request VonageCallRequest
+
+	provider := newTestVonage(t, key, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/calls", r.URL.Path)
+
+		token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get(headerAuthorization), "Bearer "), func(token *jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"RS256"}))
+
+		require.NoError(t, err)
+
+		claims, ok := token.Claims.(jwt.MapClaims)
+		require.True(t, ok)
+
+		assert.Equal(t, "app-id", claims["application_id"])
+		assert.NotEmpty(t, claims["jti"])
+
+		request = VonageCallRequest{}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		w.Header().Set(headerContentType, contentTypeApplicationJSON)
+		w.WriteHeader(http.StatusCreated)
+
+		_, _ = w.Write([]byte(`{"uuid":"abc","status":"started"}`))
+	})
+
+	require.NoError(t, provider.Send(context.Background(), &Message{To: "+15550100123", Code: "123456", Voice: true}))
+
+	assert.Equal(t, []VonageEndpoint{{Type: vonageTypePhone, Number: "15550100123"}}, request.To)
+	assert.Equal(t, VonageEndpoint{Type: vonageTypePhone, Number: "15550100999"}, request.From)
+	assert.Equal(t, []VonageAction{{Action: vonageActionTalk, Text: "Your Authelia verification code is 1, 2, 3, 4, 5, 6.", Loop: 2}}, request.NCCO)
+}
@@ -0,0 +1,630 @@
+package sops
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ParseAgeIdentities parses the age identities in the content of an age key file where each identity is on its own
+// line, and empty lines and lines starting with '#' are ignored.
+func ParseAgeIdentities(data string) (identities []*AgeIdentity, err error) {
+	var identity *AgeIdentity
+
+	for i, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if identity, err = ParseAgeIdentity(line); err != nil {
+			return nil, fmt.Errorf("error parsing age identity on line %d: %w", i+1, err)
+		}
+
+		identities = append(identities, identity)
+	}
+
+	return identities, nil
+}
+
+// ParseAgeIdentity parses an age X25519 identity with the format 'AGE-SECRET-KEY-1...'.
+func ParseAgeIdentity(value string) (identity *AgeIdentity, err error) {
+	var (
+		hrp  string
+		data []byte
+	)
+
+	if hrp, data, err = bech32Decode(value); err != nil {
+		return nil, err
+	}
+
+	if hrp != ageIdentityHRP {
+		return nil, fmt.Errorf("the identity has the prefix '%s' but it must have the prefix '%s'", strings.ToUpper(hrp), strings.ToUpper(ageIdentityHRP))
+	}
+
+	identity = &AgeIdentity{}
+
+	if identity.key, err = ecdh.X25519().NewPrivateKey(data); err != nil {
+		return nil, fmt.Errorf("the identity is not a valid X25519 key: %w", err)
+	}
+
+	return identity, nil
+}
+
+// Recipient returns the age recipient of the identity with the format 'age1...'.
+func (i *AgeIdentity) Recipient() string {
+	recipient, _ := bech32Encode(ageRecipientHRP, i.key.PublicKey().Bytes())
+
+	return recipient
+}
+
+// unwrap returns the file key if the body of the X25519 stanza was wrapped for this identity.
+func (i *AgeIdentity) unwrap(share, body []byte) (key []byte, err error) {
+	var public *ecdh.PublicKey
+
+	if public, err = ecdh.X25519().NewPublicKey(share); err != nil {
+		return nil, err
+	}
+
+	var shared []byte
+
+	if shared, err = i.key.ECDH(public); err != nil {
+		return nil, err
+	}
+
+	salt := append(append([]byte{}, share...), i.key.PublicKey().Bytes()...)
+
+	wrap := make([]byte, chacha20poly1305.KeySize)
+
+	if _, err = io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(ageLabelX25519)), wrap); err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), body, nil)
+}
+
+// AgeDecrypt decrypts an age encrypted file which may be ASCII armored using the first identity which the file is
+// encrypted to. Only X25519 recipients are supported.
+func AgeDecrypt(data []byte, identities ...*AgeIdentity) (plaintext []byte, err error) {
+	if data, err = ageDearmor(data); err != nil {
+		return nil, err
+	}
+
+	var (
+		header, mac, payload []byte
+		stanzas              []ageStanza
+	)
+
+	if header, stanzas, mac, payload, err = ageParse(data); err != nil {
+		return nil, fmt.Errorf("error parsing age header: %w", err)
+	}
+
+	var key []byte
+
+	for _, stanza := range stanzas {
+		if stanza.kind != ageStanzaX25519 {
+			continue
+		}
+
+		for _, identity := range identities {
+			if key, err = identity.unwrap(stanza.share, stanza.body); err == nil {
+				break
+			}
+		}
+
+		if key != nil {
+			break
+		}
+	}
+
+	if len(key) != ageFileKeySize {
+		return nil, errors.New("none of the age identities match any of the recipients")
+	}
+
+	if !hmac.Equal(mac, ageHMAC(key, header)) {
+		return nil, errors.New("error verifying age header: the mac is not valid")
+	}
+
+	return ageDecryptPayload(key, payload)
+}
+
+// AgeEncryptWithPassphrase encrypts the plaintext using the age format with a scrypt recipient for the passphrase, and
+// returns the ASCII armored result. The result can be decrypted with AgeDecryptWithPassphrase or any age implementation.
+func AgeEncryptWithPassphrase(plaintext []byte, passphrase string) (data []byte, err error) {
+	return ageEncryptWithPassphrase(plaintext, passphrase, ageScryptWorkFactor)
+}
+
+func ageEncryptWithPassphrase(plaintext []byte, passphrase string, logN int) (data []byte, err error) {
+	key, salt := make([]byte, ageFileKeySize), make([]byte, ageScryptSaltSize)
+
+	if _, err = rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	var wrap []byte
+
+	if wrap, err = ageScryptKey(passphrase, salt, logN); err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	header := &bytes.Buffer{}
+
+	header.WriteString(ageVersionLine + "\n")
+	header.WriteString(fmt.Sprintf("%s%s %s %d\n", ageStanzaPrefix, ageStanzaScrypt, base64.RawStdEncoding.EncodeToString(salt), logN))
+	header.WriteString(base64.RawStdEncoding.EncodeToString(aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), key, nil)) + "\n")
+	header.WriteString(strings.TrimSpace(ageMACPrefix))
+
+	out := &bytes.Buffer{}
+
+	out.Write(header.Bytes())
+	out.WriteString(" " + base64.RawStdEncoding.EncodeToString(ageHMAC(key, header.Bytes())) + "\n")
+
+	if err = ageEncryptPayload(out, key, plaintext); err != nil {
+		return nil, err
+	}
+
+	return ageArmor(out.Bytes()), nil
+}
+
+// AgeDecryptWithPassphrase decrypts an age encrypted file which may be ASCII armored and which is encrypted with a
+// scrypt recipient for the passphrase.
+func AgeDecryptWithPassphrase(data []byte, passphrase string) (plaintext []byte, err error) {
+	if data, err = ageDearmor(data); err != nil {
+		return nil, err
+	}
+
+	var (
+		header, mac, payload []byte
+		stanzas              []ageStanza
+	)
+
+	if header, stanzas, mac, payload, err = ageParse(data); err != nil {
+		return nil, fmt.Errorf("error parsing age header: %w", err)
+	}
+
+	// The scrypt recipient must be the only recipient as per the specification.
+	if len(stanzas) != 1 || stanzas[0].kind != ageStanzaScrypt {
+		return nil, errors.New("the file is not encrypted with a passphrase")
+	}
+
+	if stanzas[0].logN > ageScryptWorkFactorMaximum {
+		return nil, fmt.Errorf("the work factor %d of the passphrase exceeds the maximum of %d", stanzas[0].logN, ageScryptWorkFactorMaximum)
+	}
+
+	var wrap []byte
+
+	if wrap, err = ageScryptKey(passphrase, stanzas[0].salt, stanzas[0].logN); err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+
+	if key, err = aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), stanzas[0].body, nil); err != nil || len(key) != ageFileKeySize {
+		return nil, errors.New("the passphrase is not valid for the file")
+	}
+
+	if !hmac.Equal(mac, ageHMAC(key, header)) {
+		return nil, errors.New("error verifying age header: the mac is not valid")
+	}
+
+	return ageDecryptPayload(key, payload)
+}
+
+// IsAgeEncrypted returns true if the data appears to be an age encrypted file in either the binary or armored format.
+func IsAgeEncrypted(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+
+	return bytes.HasPrefix(trimmed, []byte(ageVersionLine+"\n")) || bytes.HasPrefix(trimmed, []byte(ageArmorHeader))
+}
+
+type ageStanza struct {
+	kind  string
+	share []byte
+	salt  []byte
+	logN  int
+	body  []byte
+}
+
+func ageDearmor(data []byte) (out []byte, err error) {
+	trimmed := bytes.TrimSpace(data)
+
+	if !bytes.HasPrefix(trimmed, []byte(ageArmorHeader)) {
+		return data, nil
+	}
+
+	if !bytes.HasSuffix(trimmed, []byte(ageArmorFooter)) {
+		return nil, errors.New("error decoding age armor: the footer is missing")
+	}
+
+	encoded := strings.Join(strings.Fields(string(trimmed[len(ageArmorHeader):len(trimmed)-len(ageArmorFooter)])), "")
+
+	if out, err = base64.StdEncoding.Strict().DecodeString(encoded); err != nil {
+		return nil, fmt.Errorf("error decoding age armor: %w", err)
+	}
+
+	return out, nil
+}
+
+func ageArmor(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	out := &bytes.Buffer{}
+
+	out.WriteString(ageArmorHeader + "\n")
+
+	for len(encoded) > ageColumnsPerLine {
+		out.WriteString(encoded[:ageColumnsPerLine] + "\n")
+
+		encoded = encoded[ageColumnsPerLine:]
+	}
+
+	out.WriteString(encoded + "\n")
+	out.WriteString(ageArmorFooter + "\n")
+
+	return out.Bytes()
+}
+
+func ageScryptKey(passphrase string, salt []byte, logN int) (key []byte, err error) {
+	return scrypt.Key([]byte(passphrase), append([]byte(ageLabelScrypt), salt...), 1<<logN, 8, 1, chacha20poly1305.KeySize)
+}
+
+// ageParse splits the binary age format into the header which the mac is calculated for, the stanzas, the mac, and
+// the payload.
+func ageParse(data []byte) (header []byte, stanzas []ageStanza, mac, payload []byte, err error) {
+	line, rest, found := bytes.Cut(data, []byte("\n"))
+	if !found || string(line) != ageVersionLine {
+		return nil, nil, nil, nil, errors.New("the version line is not valid")
+	}
+
+	for {
+		if line, rest, found = bytes.Cut(rest, []byte("\n")); !found {
+			return nil, nil, nil, nil, errors.New("the header is incomplete")
+		}
+
+		switch {
+		case bytes.HasPrefix(line, []byte(ageMACPrefix)):
+			// The mac is calculated for the header up to and including the '---' but excluding the space.
+			start := len(data) - len(rest) - len(line) - 1
+
+			header = data[:start+len(ageMACPrefix)-1]
+
+			if mac, err = base64.RawStdEncoding.Strict().DecodeString(string(line[len(ageMACPrefix):])); err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("the mac is not valid: %w", err)
+			}
+
+			return header, stanzas, mac, rest, nil
+		case bytes.HasPrefix(line, []byte(ageStanzaPrefix)):
+			args := strings.Fields(string(line[len(ageStanzaPrefix):]))
+
+			if len(args) == 0 {
+				return nil, nil, nil, nil, errors.New("a stanza does not have a type")
+			}
+
+			stanza := ageStanza{kind: args[0]}
+
+			switch stanza.kind {
+			case ageStanzaX25519:
+				if len(args) != 2 {
+					return nil, nil, nil, nil, errors.New("a X25519 stanza does not have exactly one argument")
+				}
+
+				if stanza.share, err = base64.RawStdEncoding.Strict().DecodeString(args[1]); err != nil {
+					return nil, nil, nil, nil, fmt.Errorf("a X25519 stanza argument is not valid: %w", err)
+				}
+			case ageStanzaScrypt:
+				if len(args) != 3 {
+					return nil, nil, nil, nil, errors.New("a scrypt stanza does not have exactly two arguments")
+				}
+
+				if stanza.salt, err = base64.RawStdEncoding.Strict().DecodeString(args[1]); err != nil || len(stanza.salt) != ageScryptSaltSize {
+					return nil, nil, nil, nil, errors.New("a scrypt stanza salt is not valid")
+				}
+
+				if stanza.logN, err = strconv.Atoi(args[2]); err != nil || stanza.logN <= 0 || args[2] != strconv.Itoa(stanza.logN) {
+					return nil, nil, nil, nil, errors.New("a scrypt stanza work factor is not valid")
+				}
+			}
+
+			if stanza.body, rest, err = ageParseStanzaBody(rest); err != nil {
+				return nil, nil, nil, nil, err
+			}
+
+			stanzas = append(stanzas, stanza)
+		default:
+			return nil, nil, nil, nil, fmt.Errorf("the line '%s' is not expected", line)
+		}
+	}
+}
+
+// ageParseStanzaBody decodes the body of a stanza which is wrapped at 64 columns and terminated by a line which is
+// shorter than 64 columns.
+func ageParseStanzaBody(data []byte) (body, rest []byte, err error) {
+	var (
+		line  []byte
+		found bool
+		b     strings.Builder
+	)
+
+	rest = data
+
+	for {
+		if line, rest, found = bytes.Cut(rest, []byte("\n")); !found {
+			return nil, nil, errors.New("a stanza body is incomplete")
+		}
+
+		if len(line) > ageColumnsPerLine {
+			return nil, nil, errors.New("a stanza body line is too long")
+		}
+
+		b.Write(line)
+
+		if len(line) < ageColumnsPerLine {
+			break
+		}
+	}
+
+	if body, err = base64.RawStdEncoding.Strict().DecodeString(b.String()); err != nil {
+		return nil, nil, fmt.Errorf("a stanza body is not valid: %w", err)
+	}
+
+	return body, rest, nil
+}
+
+func ageHMAC(key, header []byte) []byte {
+	mac := hmac.New(sha256.New, ageHKDF(key, nil, ageLabelHeader))
+
+	mac.Write(header)
+
+	return mac.Sum(nil)
+}
+
+func ageHKDF(secret, salt []byte, label string) []byte {
+	key := make([]byte, chacha20poly1305.KeySize)
+
+	_, _ = io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(label)), key)
+
+	return key
+}
+
+// ageDecryptPayload decrypts the payload which is encrypted using the STREAM construction with ChaCha20-Poly1305 in
+// chunks of 64 KiB, where the nonce of each chunk is the big endian chunk counter and a flag for the last chunk.
+func ageDecryptPayload(key, payload []byte) (plaintext []byte, err error) {
+	if len(payload) < ageNonceSize {
+		return nil, errors.New("error decrypting age payload: the nonce is missing")
+	}
+
+	aead, err := chacha20poly1305.New(ageHKDF(key, payload[:ageNonceSize], ageLabelPayload))
+	if err != nil {
+		return nil, err
+	}
+
+	payload = payload[ageNonceSize:]
+
+	var (
+		chunk []byte
+		nonce = make([]byte, chacha20poly1305.NonceSize)
+	)
+
+	for counter := uint64(0); ; counter++ {
+		size := min(len(payload), ageChunkSize+aead.Overhead())
+
+		last := size == len(payload)
+
+		binary.BigEndian.PutUint64(nonce[3:11], counter)
+
+		if last {
+			nonce[11] = 1
+		}
+
+		if chunk, err = aead.Open(nil, nonce, payload[:size], nil); err != nil {
+			return nil, fmt.Errorf("error decrypting age payload: chunk %d: %w", counter, err)
+		}
+
+		if len(chunk) == 0 && counter != 0 {
+			return nil, errors.New("error decrypting age payload: the last chunk is empty")
+		}
+
+		plaintext = append(plaintext, chunk...)
+
+		if last {
+			return plaintext, nil
+		}
+
+		payload = payload[size:]
+	}
+}
+
+// ageEncryptPayload writes the nonce and the payload encrypted using the STREAM construction with ChaCha20-Poly1305 in
+// chunks of 64 KiB.
+func ageEncryptPayload(out *bytes.Buffer, key, plaintext []byte) (err error) {
+	salt := make([]byte, ageNonceSize)
+
+	if _, err = rand.Read(salt); err != nil {
+		return err
+	}
+
+	out.Write(salt)
+
+	aead, err := chacha20poly1305.New(ageHKDF(key, salt, ageLabelPayload))
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+
+	for counter := uint64(0); ; counter++ {
+		size := min(len(plaintext), ageChunkSize)
+
+		last := size == len(plaintext)
+
+		binary.BigEndian.PutUint64(nonce[3:11], counter)
+
+		if last {
+			nonce[11] = 1
+		}
+
+		out.Write(aead.Seal(nil, nonce, plaintext[:size], nil))
+
+		if last {
+			return nil
+		}
+
+		plaintext = plaintext[size:]
+	}
+}
+
+func bech32Decode(value string) (hrp string, data []byte, err error) {
+	if strings.ToLower(value) != value && strings.ToUpper(value) != value {
+		return "", nil, errors.New("the value has mixed case")
+	}
+
+	value = strings.ToLower(value)
+
+	pos := strings.LastIndexByte(value, '1')
+	if pos < 1 || pos+7 > len(value) {
+		return "", nil, errors.New("the value is not valid bech32")
+	}
+
+	hrp = value[:pos]
+
+	values := make([]byte, 0, len(value)-pos-1)
+
+	for _, c := range value[pos+1:] {
+		index := strings.IndexRune(bech32Charset, c)
+		if index == -1 {
+			return "", nil, fmt.Errorf("the value has the invalid character '%c'", c)
+		}
+
+		values = append(values, byte(index))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, errors.New("the value has an invalid checksum")
+	}
+
+	if data, err = bech32ConvertBits(values[:len(values)-6], 5, 8, false); err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
+
+func bech32Encode(hrp string, data []byte) (value string, err error) {
+	var values []byte
+
+	if values, err = bech32ConvertBits(data, 8, 5, true); err != nil {
+		return "", err
+	}
+
+	polymod := bech32Polymod(append(append(bech32ExpandHRP(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	for i := 0; i < 6; i++ {
+		values = append(values, byte(polymod>>uint(5*(5-i)))&31)
+	}
+
+	b := strings.Builder{}
+
+	b.WriteString(hrp)
+	b.WriteByte('1')
+
+	for _, v := range values {
+		b.WriteByte(bech32Charset[v])
+	}
+
+	return b.String(), nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+
+	for _, v := range values {
+		top := chk >> 25
+
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	values := make([]byte, 0, len(hrp)*2+1)
+
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+
+	values = append(values, 0)
+
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+
+	return values
+}
+
+func bech32ConvertBits(data []byte, from, to uint, pad bool) (out []byte, err error) {
+	var (
+		acc  uint32
+		bits uint
+	)
+
+	maxv := uint32(1)<<to - 1
+
+	for _, b := range data {
+		acc = acc<<from | uint32(b)
+		bits += from
+
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	switch {
+	case pad && bits > 0:
+		out = append(out, byte(acc<<(to-bits)&maxv))
+	case !pad && (bits >= from || acc<<(to-bits)&maxv != 0):
+		return nil, errors.New("the value has invalid padding")
+	}
+
+	return out, nil
+}
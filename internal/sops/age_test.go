@@ -0,0 +1,274 @@
+package sops
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+func TestBech32(t *testing.T) {
+	testCases := []struct {
+		name string
+		have string
+		hrp  string
+		err  string
+	}{
+		{"ShouldDecodeUppercase", "A12UEL5L", "a", ""},
+		{"ShouldDecodeCharset", "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", "abcdef", ""},
+		{"ShouldFailMixedCase", "A12uEL5L", "", "the value has mixed case"},
+		{"ShouldFailChecksum", "a12uel5m", "", "the value has an invalid checksum"},
+		{"ShouldFailCharacter", "a12ubl5l", "", "the value has the invalid character 'b'"},
+		{"ShouldFailNoSeparator", "pzry9x0s0muk", "", "the value is not valid bech32"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hrp, _, err := bech32Decode(tc.have)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.hrp, hrp)
+		})
+	}
+
+	data := []byte("authelia")
+
+	value, err := bech32Encode("test", data)
+	require.NoError(t, err)
+
+	hrp, decoded, err := bech32Decode(value)
+	require.NoError(t, err)
+	assert.Equal(t, "test", hrp)
+	assert.Equal(t, data, decoded)
+}
+
+func TestParseAgeIdentities(t *testing.T) {
+	identity, encoded := newTestAgeIdentity(t)
+
+	identities, err := ParseAgeIdentities("# created: 2024-01-01T00:00:00Z\n# public key: " + identity.Recipient() + "\n" + encoded + "\n\n")
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+
+	assert.Equal(t, identity.Recipient(), identities[0].Recipient())
+	assert.True(t, strings.HasPrefix(identity.Recipient(), "age1"))
+
+	recipient, err := bech32Encode(ageRecipientHRP, identity.key.PublicKey().Bytes())
+	require.NoError(t, err)
+
+	_, err = ParseAgeIdentities(recipient)
+	assert.EqualError(t, err, "error parsing age identity on line 1: the identity has the prefix 'AGE' but it must have the prefix 'AGE-SECRET-KEY-'")
+}
+
+func TestAgeDecrypt(t *testing.T) {
+	identity, _ := newTestAgeIdentity(t)
+	other, _ := newTestAgeIdentity(t)
+
+	large := bytes.Repeat([]byte("a"), ageChunkSize*2+10)
+
+	testCases := []struct {
+		name      string
+		plaintext []byte
+		armor     bool
+	}{
+		{"ShouldDecryptBinary", []byte("example"), false},
+		{"ShouldDecryptArmored", []byte("example"), true},
+		{"ShouldDecryptEmpty", []byte{}, false},
+		{"ShouldDecryptMultipleChunks", large, true},
+		{"ShouldDecryptExactChunk", large[:ageChunkSize], false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encrypted := ageEncryptForTest(t, tc.plaintext, tc.armor, other.key.PublicKey(), identity.key.PublicKey())
+
+			plaintext, err := AgeDecrypt(encrypted, identity)
+			require.NoError(t, err)
+			assert.Equal(t, tc.plaintext, append([]byte{}, plaintext...))
+		})
+	}
+
+	encrypted := ageEncryptForTest(t, []byte("example"), false, other.key.PublicKey())
+
+	_, err := AgeDecrypt(encrypted, identity)
+	assert.EqualError(t, err, "none of the age identities match any of the recipients")
+
+	encrypted[len(encrypted)-1] ^= 1
+
+	_, err = AgeDecrypt(encrypted, other)
+	assert.EqualError(t, err, "error decrypting age payload: chunk 0: chacha20poly1305: message authentication failed")
+
+	_, err = AgeDecrypt([]byte("age-encryption.org/v2\n"), identity)
+	assert.EqualError(t, err, "error parsing age header: the version line is not valid")
+}
+
+func newTestAgeIdentity(t *testing.T) (identity *AgeIdentity, encoded string) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	encoded, err = bech32Encode(ageIdentityHRP, key.Bytes())
+	require.NoError(t, err)
+
+	identity, err = ParseAgeIdentity(strings.ToUpper(encoded))
+	require.NoError(t, err)
+
+	return identity, strings.ToUpper(encoded)
+}
+
+// ageEncryptForTest encrypts the plaintext to the X25519 recipients using the age format.
+func ageEncryptForTest(t *testing.T, plaintext []byte, armor bool, recipients ...*ecdh.PublicKey) []byte {
+	key := make([]byte, ageFileKeySize)
+
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	header := &bytes.Buffer{}
+
+	header.WriteString(ageVersionLine + "\n")
+
+	for _, recipient := range recipients {
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		shared, err := ephemeral.ECDH(recipient)
+		require.NoError(t, err)
+
+		share := ephemeral.PublicKey().Bytes()
+
+		aead, err := chacha20poly1305.New(ageHKDF(shared, append(append([]byte{}, share...), recipient.Bytes()...), ageLabelX25519))
+		require.NoError(t, err)
+
+		body := base64.RawStdEncoding.EncodeToString(aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), key, nil))
+
+		header.WriteString(ageStanzaPrefix + ageStanzaX25519 + " " + base64.RawStdEncoding.EncodeToString(share) + "\n")
+
+		for len(body) >= ageColumnsPerLine {
+			header.WriteString(body[:ageColumnsPerLine] + "\n")
+
+			body = body[ageColumnsPerLine:]
+		}
+
+		header.WriteString(body + "\n")
+	}
+
+	header.WriteString("---")
+
+	out := &bytes.Buffer{}
+
+	out.Write(header.Bytes())
+	out.WriteString(" " + base64.RawStdEncoding.EncodeToString(ageHMAC(key, header.Bytes())) + "\n")
+
+	nonce := make([]byte, ageNonceSize)
+
+	_, err = rand.Read(nonce)
+	require.NoError(t, err)
+
+	out.Write(nonce)
+
+	aead, err := chacha20poly1305.New(ageHKDF(key, nonce, ageLabelPayload))
+	require.NoError(t, err)
+
+	counter := make([]byte, chacha20poly1305.NonceSize)
+
+	for i := uint64(0); ; i++ {
+		size := min(len(plaintext), ageChunkSize)
+
+		binary.BigEndian.PutUint64(counter[3:11], i)
+
+		last := size == len(plaintext)
+
+		if last {
+			counter[11] = 1
+		}
+
+		out.Write(aead.Seal(nil, counter, plaintext[:size], nil))
+
+		if last {
+			break
+		}
+
+		plaintext = plaintext[size:]
+	}
+
+	if !armor {
+		return out.Bytes()
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(out.Bytes())
+
+	armored := &strings.Builder{}
+
+	armored.WriteString(ageArmorHeader + "\n")
+
+	for len(encoded) > ageColumnsPerLine {
+		armored.WriteString(encoded[:ageColumnsPerLine] + "\n")
+
+		encoded = encoded[ageColumnsPerLine:]
+	}
+
+	armored.WriteString(encoded + "\n" + ageArmorFooter + "\n")
+
+	return []byte(armored.String())
+}
+
+func TestAgeEncryptWithPassphrase(t *testing.T) {
+	large := bytes.Repeat([]byte("a"), ageChunkSize*2+10)
+
+	testCases := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{"ShouldEncrypt", []byte("example")},
+		{"ShouldEncryptEmpty", []byte{}},
+		{"ShouldEncryptMultipleChunks", large},
+		{"ShouldEncryptExactChunk", large[:ageChunkSize]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encrypted, err := ageEncryptWithPassphrase(tc.plaintext, "p@ssw0rd", 10)
+			require.NoError(t, err)
+
+			assert.True(t, IsAgeEncrypted(encrypted))
+			assert.True(t, bytes.HasPrefix(encrypted, []byte(ageArmorHeader+"\n")))
+
+			plaintext, err := AgeDecryptWithPassphrase(encrypted, "p@ssw0rd")
+			require.NoError(t, err)
+			assert.Equal(t, tc.plaintext, append([]byte{}, plaintext...))
+		})
+	}
+
+	encrypted, err := ageEncryptWithPassphrase([]byte("example"), "p@ssw0rd", 10)
+	require.NoError(t, err)
+
+	_, err = AgeDecryptWithPassphrase(encrypted, "password")
+	assert.EqualError(t, err, "the passphrase is not valid for the file")
+
+	identity, _ := newTestAgeIdentity(t)
+
+	_, err = AgeDecryptWithPassphrase(ageEncryptForTest(t, []byte("example"), false, identity.key.PublicKey()), "p@ssw0rd")
+	assert.EqualError(t, err, "the file is not encrypted with a passphrase")
+
+	_, err = AgeDecrypt(encrypted, identity)
+	assert.EqualError(t, err, "none of the age identities match any of the recipients")
+
+	decoded, err := ageDearmor(encrypted)
+	require.NoError(t, err)
+
+	_, err = AgeDecryptWithPassphrase(bytes.Replace(decoded, []byte(" 10\n"), []byte(" 23\n"), 1), "p@ssw0rd")
+	assert.EqualError(t, err, "the work factor 23 of the passphrase exceeds the maximum of 22")
+
+	assert.False(t, IsAgeEncrypted([]byte("otpauth://totp/Authelia:john?secret=ABC")))
+}
@@ -0,0 +1,77 @@
+package sops
+
+import (
+	"regexp"
+	"time"
+)
+
+const (
+	envAgeKey     = "SOPS_AGE_KEY"
+	envAgeKeyFile = "SOPS_AGE_KEY_FILE"
+)
+
+const (
+	keyMetadata = "sops"
+
+	pathAgeKeys = "sops/age/keys.txt"
+)
+
+const (
+	typeString  = "str"
+	typeInteger = "int"
+	typeFloat   = "float"
+	typeBoolean = "bool"
+	typeBytes   = "bytes"
+)
+
+const (
+	yamlTagString  = "!!str"
+	yamlTagInteger = "!!int"
+	yamlTagFloat   = "!!float"
+	yamlTagBoolean = "!!bool"
+	yamlTagNull    = "!!null"
+)
+
+const (
+	ageVersionLine    = "age-encryption.org/v1"
+	ageStanzaPrefix   = "-> "
+	ageMACPrefix      = "--- "
+	ageStanzaX25519   = "X25519"
+	ageStanzaScrypt   = "scrypt"
+	ageColumnsPerLine = 64
+	ageFileKeySize    = 16
+	ageNonceSize      = 16
+	ageChunkSize      = 64 * 1024
+
+	ageScryptSaltSize          = 16
+	ageScryptWorkFactor        = 18
+	ageScryptWorkFactorMaximum = 22
+
+	ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+	ageArmorFooter = "-----END AGE ENCRYPTED FILE-----"
+
+	ageLabelX25519  = "age-encryption.org/v1/X25519"
+	ageLabelScrypt  = "age-encryption.org/v1/scrypt"
+	ageLabelHeader  = "header"
+	ageLabelPayload = "payload"
+
+	ageIdentityHRP  = "age-secret-key-"
+	ageRecipientHRP = "age"
+)
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+const (
+	timeoutDecrypt = time.Second * 30
+)
+
+var (
+	// reEncryptedValue matches the values encrypted by SOPS. The values are encrypted using AES-256-GCM with the
+	// data key and the base64 encoded data, iv, and tag are stored alongside the type of the plaintext value.
+	reEncryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.*),iv:(.+),tag:(.+),type:(.+)]$`)
+
+	// reMetadata matches the top level SOPS metadata key of YAML or JSON documents.
+	reMetadata = regexp.MustCompile(`(?m)^(sops|"sops"\s*):`)
+)
@@ -0,0 +1,422 @@
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/authelia/authelia/v4/internal/secrets"
+)
+
+// IsEncrypted returns true if the document has the top level SOPS metadata key.
+func IsEncrypted(data []byte) bool {
+	return reMetadata.Match(data)
+}
+
+// Decrypt a SOPS encrypted YAML or JSON document using the decrypter configured using the environment.
+func Decrypt(data []byte) (plaintext []byte, err error) {
+	var decrypter *Decrypter
+
+	if decrypter, err = NewDecrypterFromEnvironment(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutDecrypt)
+
+	defer cancel()
+
+	return decrypter.Decrypt(ctx, data)
+}
+
+// NewDecrypter returns a new Decrypter which decrypts the data key using the age identities, the AWS KMS provider, or
+// the GCP KMS provider.
+func NewDecrypter(identities []*AgeIdentity, aws, gcp secrets.KMSProvider) (decrypter *Decrypter) {
+	return &Decrypter{
+		identities: identities,
+		aws:        aws,
+		gcp:        gcp,
+	}
+}
+
+// NewDecrypterFromEnvironment returns a new Decrypter configured using the same environment variables as SOPS. The age
+// identities are read from the 'SOPS_AGE_KEY' environment variable, the file path in the 'SOPS_AGE_KEY_FILE'
+// environment variable, or the 'sops/age/keys.txt' file in the user configuration directory. The key management
+// services are configured using the conventional environment variables of each of them.
+func NewDecrypterFromEnvironment() (decrypter *Decrypter, err error) {
+	var identities []*AgeIdentity
+
+	if identities, err = ageIdentitiesFromEnvironment(); err != nil {
+		return nil, err
+	}
+
+	return NewDecrypter(identities, secrets.NewAWSKMSProviderFromEnvironment(), secrets.NewGCPKMSProviderFromEnvironment()), nil
+}
+
+func ageIdentitiesFromEnvironment() (identities []*AgeIdentity, err error) {
+	if value := os.Getenv(envAgeKey); value != "" {
+		if identities, err = ParseAgeIdentities(value); err != nil {
+			return nil, fmt.Errorf("error loading age identities from the '%s' environment variable: %w", envAgeKey, err)
+		}
+	}
+
+	path, optional := os.Getenv(envAgeKeyFile), false
+
+	if path == "" {
+		var dir string
+
+		if dir, err = os.UserConfigDir(); err != nil {
+			return identities, nil
+		}
+
+		path, optional = filepath.Join(dir, pathAgeKeys), true
+	}
+
+	var data []byte
+
+	if data, err = os.ReadFile(path); err != nil {
+		if optional && errors.Is(err, os.ErrNotExist) {
+			return identities, nil
+		}
+
+		return nil, fmt.Errorf("error loading age identities from file '%s': %w", path, err)
+	}
+
+	var file []*AgeIdentity
+
+	if file, err = ParseAgeIdentities(string(data)); err != nil {
+		return nil, fmt.Errorf("error loading age identities from file '%s': %w", path, err)
+	}
+
+	return append(identities, file...), nil
+}
+
+// Decrypt a SOPS encrypted YAML or JSON document. The metadata is removed, every encrypted value is replaced with its
+// plaintext value, and the MAC of the document is verified. The decrypted document is returned as YAML.
+func (d *Decrypter) Decrypt(ctx context.Context, data []byte) (plaintext []byte, err error) {
+	var root yaml.Node
+
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: error parsing document: %w", err)
+	}
+
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("error decrypting sops document: the document is not a mapping")
+	}
+
+	var metadata *Metadata
+
+	if metadata, err = extractMetadata(root.Content[0]); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: %w", err)
+	}
+
+	var key []byte
+
+	if key, err = d.dataKey(ctx, metadata); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: %w", err)
+	}
+
+	digest := sha512.New()
+
+	if err = decryptNode(key, nil, root.Content[0], metadata.MACOnlyEncrypted, digest); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: %w", err)
+	}
+
+	if err = verifyMAC(key, metadata, digest); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: %w", err)
+	}
+
+	if plaintext, err = yaml.Marshal(&root); err != nil {
+		return nil, fmt.Errorf("error decrypting sops document: error encoding document: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// dataKey decrypts the data key using the first master key which can be decrypted.
+func (d *Decrypter) dataKey(ctx context.Context, metadata *Metadata) (key []byte, err error) {
+	groups := metadata.KeyGroups
+
+	if len(groups) == 0 {
+		groups = []MetadataKeyGroup{{AWSKMS: metadata.AWSKMS, GCPKMS: metadata.GCPKMS, Age: metadata.Age}}
+	}
+
+	if len(groups) != 1 {
+		return nil, fmt.Errorf("documents with %d key groups are not supported as they require shamir secret sharing", len(groups))
+	}
+
+	group := groups[0]
+
+	var errs []error
+
+	for _, k := range group.Age {
+		if len(d.identities) == 0 {
+			errs = append(errs, fmt.Errorf("age recipient '%s': no age identities are configured using the '%s' or '%s' environment variables", k.Recipient, envAgeKey, envAgeKeyFile))
+
+			continue
+		}
+
+		if key, err = AgeDecrypt([]byte(k.Encrypted), d.identities...); err == nil {
+			return dataKeyValid(key)
+		}
+
+		errs = append(errs, fmt.Errorf("age recipient '%s': %w", k.Recipient, err))
+	}
+
+	for _, k := range group.AWSKMS {
+		if k.Role != "" {
+			errs = append(errs, fmt.Errorf("aws kms key '%s': assuming the role '%s' is not supported", k.ARN, k.Role))
+
+			continue
+		}
+
+		if key, err = decryptKMS(ctx, d.aws, k.ARN, k.Encrypted, k.EncryptionContext); err == nil {
+			return dataKeyValid(key)
+		}
+
+		errs = append(errs, fmt.Errorf("aws kms key '%s': %w", k.ARN, err))
+	}
+
+	for _, k := range group.GCPKMS {
+		if key, err = decryptKMS(ctx, d.gcp, k.ResourceID, k.Encrypted, nil); err == nil {
+			return dataKeyValid(key)
+		}
+
+		errs = append(errs, fmt.Errorf("gcp kms key '%s': %w", k.ResourceID, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("the document does not have any age, aws kms, or gcp kms master keys")
+	}
+
+	return nil, fmt.Errorf("error decrypting the data key: %w", errors.Join(errs...))
+}
+
+func decryptKMS(ctx context.Context, provider secrets.KMSProvider, key, encrypted string, encryptionContext map[string]string) (plaintext []byte, err error) {
+	if provider == nil {
+		return nil, errors.New("the key management service is not configured")
+	}
+
+	var ciphertext []byte
+
+	if ciphertext, err = base64.StdEncoding.DecodeString(encrypted); err != nil {
+		return nil, fmt.Errorf("error decoding the encrypted data key: %w", err)
+	}
+
+	return provider.Decrypt(ctx, key, ciphertext, encryptionContext)
+}
+
+func dataKeyValid(key []byte) ([]byte, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("the data key must be 32 bytes but it's %d bytes", len(key))
+	}
+
+	return key, nil
+}
+
+// extractMetadata removes the metadata from the top level mapping of the document and decodes it.
+func extractMetadata(node *yaml.Node) (metadata *Metadata, err error) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != keyMetadata {
+			continue
+		}
+
+		metadata = &Metadata{}
+
+		if err = node.Content[i+1].Decode(metadata); err != nil {
+			return nil, fmt.Errorf("error decoding metadata: %w", err)
+		}
+
+		node.Content = append(node.Content[:i], node.Content[i+2:]...)
+
+		return metadata, nil
+	}
+
+	return nil, fmt.Errorf("the document does not have the '%s' metadata key", keyMetadata)
+}
+
+// decryptNode decrypts every encrypted value beneath the node in place, and writes the value of every leaf which
+// contributes to the MAC to the digest in the same order SOPS does. The additional data of each value is the path of
+// mapping keys leading to it, sequences do not contribute to the path.
+func decryptNode(key []byte, path []string, node *yaml.Node, macOnlyEncrypted bool, digest hash.Hash) (err error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err = decryptNode(key, append(path[:len(path):len(path)], node.Content[i].Value), node.Content[i+1], macOnlyEncrypted, digest); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if err = decryptNode(key, path, item, macOnlyEncrypted, digest); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if !reEncryptedValue.MatchString(node.Value) {
+			if macOnlyEncrypted || node.Tag == yamlTagNull {
+				return nil
+			}
+
+			_, _ = digest.Write(plaintextBytes(node))
+
+			return nil
+		}
+
+		var (
+			plaintext []byte
+			kind      string
+		)
+
+		if plaintext, kind, err = decryptValue(key, node.Value, strings.Join(path, ":")+":"); err != nil {
+			return fmt.Errorf("error decrypting the value of '%s': %w", strings.Join(path, "."), err)
+		}
+
+		var mac string
+
+		if node.Tag, node.Value, mac, err = decodeValue(plaintext, kind); err != nil {
+			return fmt.Errorf("error decrypting the value of '%s': %w", strings.Join(path, "."), err)
+		}
+
+		node.Style = 0
+
+		_, _ = digest.Write([]byte(mac))
+	}
+
+	return nil
+}
+
+// decryptValue decrypts an encrypted value using the data key and the additional data, and returns the plaintext and
+// the type of the plaintext.
+func decryptValue(key []byte, value, additional string) (plaintext []byte, kind string, err error) {
+	matches := reEncryptedValue.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, "", errors.New("the value is not encrypted")
+	}
+
+	var data, iv, tag []byte
+
+	if data, err = base64.StdEncoding.DecodeString(matches[1]); err != nil {
+		return nil, "", fmt.Errorf("error decoding data: %w", err)
+	}
+
+	if iv, err = base64.StdEncoding.DecodeString(matches[2]); err != nil {
+		return nil, "", fmt.Errorf("error decoding iv: %w", err)
+	}
+
+	if tag, err = base64.StdEncoding.DecodeString(matches[3]); err != nil {
+		return nil, "", fmt.Errorf("error decoding tag: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, "", err
+	}
+
+	if plaintext, err = gcm.Open(nil, iv, append(data, tag...), []byte(additional)); err != nil {
+		return nil, "", err
+	}
+
+	return plaintext, matches[4], nil
+}
+
+// decodeValue returns the YAML tag and value for the plaintext of a decrypted value, and the value which contributes
+// to the MAC.
+func decodeValue(plaintext []byte, kind string) (tag, value, mac string, err error) {
+	switch kind {
+	case typeString, typeBytes:
+		return yamlTagString, string(plaintext), string(plaintext), nil
+	case typeInteger:
+		var v int
+
+		if v, err = strconv.Atoi(string(plaintext)); err != nil {
+			return "", "", "", err
+		}
+
+		return yamlTagInteger, strconv.Itoa(v), strconv.Itoa(v), nil
+	case typeFloat:
+		var v float64
+
+		if v, err = strconv.ParseFloat(string(plaintext), 64); err != nil {
+			return "", "", "", err
+		}
+
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+
+		return yamlTagFloat, value, value, nil
+	case typeBoolean:
+		var v bool
+
+		if v, err = strconv.ParseBool(string(plaintext)); err != nil {
+			return "", "", "", err
+		}
+
+		return yamlTagBoolean, strconv.FormatBool(v), formatBool(v), nil
+	default:
+		return "", "", "", fmt.Errorf("the type '%s' is not supported", kind)
+	}
+}
+
+// plaintextBytes returns the value of an unencrypted scalar which contributes to the MAC.
+func plaintextBytes(node *yaml.Node) []byte {
+	var value any
+
+	if err := node.Decode(&value); err != nil {
+		return []byte(node.Value)
+	}
+
+	switch v := value.(type) {
+	case int:
+		return []byte(strconv.Itoa(v))
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		return []byte(formatBool(v))
+	default:
+		return []byte(node.Value)
+	}
+}
+
+// formatBool formats booleans the same way SOPS does for compatibility with the original Python implementation.
+func formatBool(v bool) string {
+	if v {
+		return "True"
+	}
+
+	return "False"
+}
+
+// verifyMAC verifies the MAC of the document which is the uppercase hex encoded SHA-512 digest of the values, and is
+// encrypted using the last modified timestamp as the additional data.
+func verifyMAC(key []byte, metadata *Metadata, digest hash.Hash) (err error) {
+	if metadata.MAC == "" {
+		return errors.New("the document does not have a mac")
+	}
+
+	var mac []byte
+
+	if mac, _, err = decryptValue(key, metadata.MAC, metadata.LastModified); err != nil {
+		return fmt.Errorf("error decrypting the mac: %w", err)
+	}
+
+	if !strings.EqualFold(string(mac), fmt.Sprintf("%X", digest.Sum(nil))) {
+		return errors.New("the mac does not match the values, the document may have been tampered with")
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/secrets"
+)
+
+func TestIsEncrypted(t *testing.T) {
+	assert.True(t, IsEncrypted([]byte("log:\n  level: ENC[AES256_GCM,data:abc=,iv:abc=,tag:abc=,type:str]\nsops:\n  version: 3.8.1\n")))
+	assert.True(t, IsEncrypted([]byte("{\n\"log\": {},\n\"sops\": {}\n}")))
+	assert.False(t, IsEncrypted([]byte("log:\n  level: debug\n")))
+	assert.False(t, IsEncrypted([]byte("log:\n  sops: true\n")))
+}
+
+func TestDecrypterDecrypt(t *testing.T) {
+	identity, _ := newTestAgeIdentity(t)
+	other, _ := newTestAgeIdentity(t)
+
+	key := make([]byte, 32)
+
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	age := fmt.Sprintf("    age:\n        - recipient: %s\n          enc: |\n%s", identity.Recipient(), indentForTest(string(ageEncryptForTest(t, key, true, identity.key.PublicKey())), 12))
+
+	testCases := []struct {
+		name       string
+		identities []*AgeIdentity
+		kms        secrets.KMSProvider
+		keys       string
+		swap       bool
+		expected   string
+		err        string
+	}{
+		{
+			"ShouldDecryptAge",
+			[]*AgeIdentity{identity}, nil, age, false,
+			"server:\n    address: tcp://:9091/\n    buffers:\n        read: 4096\ntotp:\n    disable: true\nlog:\n    level_unencrypted: debug\naccess_control:\n    rules:\n        - domain: example.com\n          policy: one_factor\n        - domain: app.example.com\n          policy: two_factor\n",
+			"",
+		},
+		{
+			"ShouldDecryptAWSKMS",
+			nil, &testKMSProvider{key: key},
+			"    kms:\n        - arn: arn:aws:kms:us-east-1:123456789012:key/abc\n          context:\n            app: authelia\n          enc: " + base64.StdEncoding.EncodeToString([]byte("wrapped")) + "\n",
+			false,
+			"server:\n    address: tcp://:9091/\n    buffers:\n        read: 4096\ntotp:\n    disable: true\nlog:\n    level_unencrypted: debug\naccess_control:\n    rules:\n        - domain: example.com\n          policy: one_factor\n        - domain: app.example.com\n          policy: two_factor\n",
+			"",
+		},
+		{
+			"ShouldFailWrongIdentity",
+			[]*AgeIdentity{other}, nil, age, false, "",
+			"error decrypting sops document: error decrypting the data key: age recipient '" + identity.Recipient() + "': none of the age identities match any of the recipients",
+		},
+		{
+			"ShouldFailNoIdentities",
+			nil, nil, age, false, "",
+			"error decrypting sops document: error decrypting the data key: age recipient '" + identity.Recipient() + "': no age identities are configured using the 'SOPS_AGE_KEY' or 'SOPS_AGE_KEY_FILE' environment variables",
+		},
+		{
+			"ShouldFailTamperedOrder",
+			[]*AgeIdentity{identity}, nil, age, true, "",
+			"error decrypting sops document: the mac does not match the values, the document may have been tampered with",
+		},
+		{
+			"ShouldFailNoMasterKeys",
+			[]*AgeIdentity{identity}, nil, "    pgp: []\n", false, "",
+			"error decrypting sops document: the document does not have any age, aws kms, or gcp kms master keys",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			document := newTestDocument(t, key, tc.keys, tc.swap)
+
+			plaintext, err := NewDecrypter(tc.identities, tc.kms, nil).Decrypt(context.Background(), document)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(plaintext))
+		})
+	}
+}
+
+func TestDecrypterDecryptShouldFailNotEncrypted(t *testing.T) {
+	_, err := NewDecrypter(nil, nil, nil).Decrypt(context.Background(), []byte("log:\n  level: debug\n"))
+	assert.EqualError(t, err, "error decrypting sops document: the document does not have the 'sops' metadata key")
+
+	_, err = NewDecrypter(nil, nil, nil).Decrypt(context.Background(), []byte("- log\n"))
+	assert.EqualError(t, err, "error decrypting sops document: the document is not a mapping")
+}
+
+type testKMSProvider struct {
+	key []byte
+}
+
+func (p *testKMSProvider) Decrypt(_ context.Context, key string, ciphertext []byte, encryptionContext map[string]string) (plaintext []byte, err error) {
+	if key != "arn:aws:kms:us-east-1:123456789012:key/abc" || string(ciphertext) != "wrapped" || encryptionContext["app"] != "authelia" {
+		return nil, fmt.Errorf("invalid request")
+	}
+
+	return p.key, nil
+}
+
+// newTestDocument returns a document encrypted the same way SOPS encrypts documents.
+func newTestDocument(t *testing.T, key []byte, keys string, swap bool) []byte {
+	values := []struct {
+		path      string
+		plaintext string
+		kind      string
+	}{
+		{"server:address:", "tcp://:9091/", typeString},
+		{"server:buffers:read:", "4096", typeInteger},
+		{"totp:disable:", "True", typeBoolean},
+		{"", "debug", ""},
+		{"access_control:rules:domain:", "example.com", typeString},
+		{"access_control:rules:policy:", "one_factor", typeString},
+		{"access_control:rules:domain:", "app.example.com", typeString},
+		{"access_control:rules:policy:", "two_factor", typeString},
+	}
+
+	digest := sha512.New()
+
+	encrypted := make([]any, len(values))
+
+	for i, v := range values {
+		digest.Write([]byte(v.plaintext))
+
+		if v.kind == "" {
+			encrypted[i] = v.plaintext
+
+			continue
+		}
+
+		encrypted[i] = encryptValueForTest(t, key, v.plaintext, v.path, v.kind)
+	}
+
+	if swap {
+		encrypted[4], encrypted[6] = encrypted[6], encrypted[4]
+	}
+
+	lastmodified := "2024-01-01T00:00:00Z"
+
+	document := fmt.Sprintf("server:\n    address: %s\n    buffers:\n        read: %s\ntotp:\n    disable: %s\nlog:\n    level_unencrypted: %s\n"+
+		"access_control:\n    rules:\n        - domain: %s\n          policy: %s\n        - domain: %s\n          policy: %s\n", encrypted...)
+
+	document += "sops:\n" + keys +
+		"    lastmodified: \"" + lastmodified + "\"\n" +
+		"    mac: " + encryptValueForTest(t, key, fmt.Sprintf("%X", digest.Sum(nil)), lastmodified, typeString) + "\n" +
+		"    unencrypted_suffix: _unencrypted\n" +
+		"    version: 3.8.1\n"
+
+	return []byte(document)
+}
+
+func encryptValueForTest(t *testing.T, key []byte, plaintext, additional, kind string) string {
+	iv := make([]byte, 32)
+
+	_, err := rand.Read(iv)
+	require.NoError(t, err)
+
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	require.NoError(t, err)
+
+	sealed := gcm.Seal(nil, iv, []byte(plaintext), []byte(additional))
+
+	data, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:%s]",
+		base64.StdEncoding.EncodeToString(data), base64.StdEncoding.EncodeToString(iv), base64.StdEncoding.EncodeToString(tag), kind)
+}
+
+func indentForTest(value string, spaces int) string {
+	lines := strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.Repeat(" ", spaces) + line
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,59 @@
+package sops
+
+import (
+	"crypto/ecdh"
+
+	"github.com/authelia/authelia/v4/internal/secrets"
+)
+
+// Decrypter decrypts SOPS encrypted documents using the age identities and key management services it's configured
+// with.
+type Decrypter struct {
+	identities []*AgeIdentity
+	aws        secrets.KMSProvider
+	gcp        secrets.KMSProvider
+}
+
+// AgeIdentity is an age X25519 identity which is used to decrypt the data key.
+type AgeIdentity struct {
+	key *ecdh.PrivateKey
+}
+
+// Metadata is the SOPS metadata stored under the 'sops' key of an encrypted document.
+type Metadata struct {
+	AWSKMS           []MetadataAWSKMS   `yaml:"kms"`
+	GCPKMS           []MetadataGCPKMS   `yaml:"gcp_kms"`
+	Age              []MetadataAge      `yaml:"age"`
+	KeyGroups        []MetadataKeyGroup `yaml:"key_groups"`
+	LastModified     string             `yaml:"lastmodified"`
+	MAC              string             `yaml:"mac"`
+	MACOnlyEncrypted bool               `yaml:"mac_only_encrypted"`
+	Version          string             `yaml:"version"`
+}
+
+// MetadataKeyGroup is a group of master keys which is used when the data key is split using Shamir's Secret Sharing.
+type MetadataKeyGroup struct {
+	AWSKMS []MetadataAWSKMS `yaml:"kms"`
+	GCPKMS []MetadataGCPKMS `yaml:"gcp_kms"`
+	Age    []MetadataAge    `yaml:"age"`
+}
+
+// MetadataAWSKMS is the data key encrypted using an AWS KMS key.
+type MetadataAWSKMS struct {
+	ARN               string            `yaml:"arn"`
+	Role              string            `yaml:"role"`
+	EncryptionContext map[string]string `yaml:"context"`
+	Encrypted         string            `yaml:"enc"`
+}
+
+// MetadataGCPKMS is the data key encrypted using a GCP KMS key.
+type MetadataGCPKMS struct {
+	ResourceID string `yaml:"resource_id"`
+	Encrypted  string `yaml:"enc"`
+}
+
+// MetadataAge is the data key encrypted to an age recipient.
+type MetadataAge struct {
+	Recipient string `yaml:"recipient"`
+	Encrypted string `yaml:"enc"`
+}
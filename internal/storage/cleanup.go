@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewCleanup returns a new Cleanup which deletes the expired rows from the provider at the configured interval. The
+// audit events older than the audit retention are also deleted unless it's 0.
+func NewCleanup(provider Provider, config schema.StorageCleanup, auditRetention time.Duration, metrics MetricsRecorder, clock clock.Provider, log *logrus.Entry) *Cleanup {
+	return &Cleanup{
+		provider:       provider,
+		config:         config,
+		auditRetention: auditRetention,
+		metrics:        metrics,
+		clock:          clock,
+		log:            log,
+	}
+}
+
+// Cleanup periodically deletes the rows which have expired and are no longer used from the provider, as otherwise
+// they accumulate indefinitely. The rows are deleted in batches so each statement only holds its locks briefly.
+type Cleanup struct {
+	provider       Provider
+	config         schema.StorageCleanup
+	auditRetention time.Duration
+	metrics        MetricsRecorder
+	clock          clock.Provider
+	log            *logrus.Entry
+}
+
+type cleanupTask struct {
+	table  string
+	before time.Time
+	delete func(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// Run the Cleanup until the context is canceled.
+func (c *Cleanup) Run(ctx context.Context) {
+	c.log.WithFields(map[string]any{"interval": c.config.Interval.String(), "batch_size": c.config.BatchSize}).Debug("Deleting the expired rows periodically")
+
+	ticker := time.NewTicker(c.config.Interval)
+
+	defer ticker.Stop()
+
+	c.cleanup(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanup(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Cleanup) cleanup(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Interval)
+
+	defer cancel()
+
+	now := c.clock.Now()
+
+	for _, task := range c.tasks(now) {
+		deleted, err := c.delete(ctx, task)
+
+		if c.metrics != nil && deleted != 0 {
+			c.metrics.RecordStorageCleanup(task.table, deleted)
+		}
+
+		log := c.log.WithFields(map[string]any{"table": task.table, "deleted": deleted})
+
+		switch {
+		case err != nil:
+			log.WithError(err).Error("Error occurred deleting the expired rows")
+		case deleted != 0:
+			log.Debug("Deleted the expired rows")
+		default:
+			log.Trace("No expired rows were found")
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// delete runs the task in batches until a batch deletes fewer rows than the batch size.
+func (c *Cleanup) delete(ctx context.Context, task cleanupTask) (deleted int64, err error) {
+	var n int64
+
+	for {
+		n, err = task.delete(ctx, task.before, c.config.BatchSize)
+
+		deleted += n
+
+		if err != nil || n < int64(c.config.BatchSize) {
+			return deleted, err
+		}
+	}
+}
+
+func (c *Cleanup) tasks(now time.Time) (tasks []cleanupTask) {
+	tasks = []cleanupTask{
+		{table: tableOAuth2BlacklistedJTI, before: now, delete: c.provider.DeleteExpiredOAuth2BlacklistedJTIs},
+		{table: tableOAuth2ConsentSession, before: now.Add(-cleanupConsentSessionLifespan), delete: c.provider.DeleteAbandonedOAuth2ConsentSessions},
+		{table: tableIdentityVerification, before: now, delete: c.provider.DeleteExpiredIdentityVerifications},
+		{table: tableOAuth2CIBARequest, before: now, delete: c.provider.DeleteExpiredOAuth2CIBARequests},
+		{table: tableDeviceCertificate, before: now, delete: c.provider.DeleteExpiredDeviceCertificates},
+		{table: tableMobileDevice, before: now, delete: c.provider.DeleteExpiredMobileDevices},
+		{table: tableSAMLSession, before: now, delete: c.provider.DeleteExpiredSAMLSessions},
+		{table: tablePushChallenge, before: now, delete: c.provider.DeleteExpiredPushChallenges},
+		{table: tableSessions, before: now, delete: c.provider.DeleteExpiredSessions},
+		{table: tableUserSessionRevocation, before: now, delete: c.provider.DeleteExpiredUserSessionRevocations},
+	}
+
+	if c.auditRetention > 0 {
+		tasks = append(tasks, cleanupTask{table: tableAuditEvents, before: now.Add(-c.auditRetention), delete: c.provider.DeleteAuditEvents})
+	}
+
+	return tasks
+}
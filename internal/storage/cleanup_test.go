@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestCleanupShouldDeleteInBatches(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	provider := &testCleanupProvider{
+		jti:     []int64{2, 2, 1},
+		consent: []int64{0},
+		iv:      []int64{2, 0},
+		ciba:    []int64{1},
+		devices: []int64{3, 0},
+		mobile:  []int64{1},
+		saml:    []int64{2, 1},
+		push:    []int64{1},
+		session: []int64{2, 2, 0},
+		revoked: []int64{1},
+	}
+
+	metrics := &testCleanupMetrics{deleted: map[string]int64{}}
+
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.TraceLevel)
+
+	c := NewCleanup(provider, schema.StorageCleanup{Interval: time.Hour, BatchSize: 2}, 0, metrics, clock.NewFixed(now), logrus.NewEntry(log))
+
+	c.cleanup(context.Background())
+
+	assert.Equal(t, map[string]int64{tableOAuth2BlacklistedJTI: 5, tableIdentityVerification: 2, tableOAuth2CIBARequest: 1, tableDeviceCertificate: 3, tableMobileDevice: 1, tableSAMLSession: 3, tablePushChallenge: 1, tableSessions: 4, tableUserSessionRevocation: 1}, metrics.deleted)
+	assert.Equal(t, []time.Time{now, now, now}, provider.jtiBefore)
+	assert.Equal(t, []time.Time{now.Add(-cleanupConsentSessionLifespan)}, provider.consentBefore)
+	assert.Equal(t, []time.Time{now, now}, provider.ivBefore)
+	assert.Equal(t, []time.Time{now}, provider.cibaBefore)
+	assert.Equal(t, []time.Time{now, now}, provider.devicesBefore)
+	assert.Equal(t, []time.Time{now}, provider.mobileBefore)
+	assert.Equal(t, []time.Time{now, now}, provider.samlBefore)
+	assert.Equal(t, []time.Time{now}, provider.pushBefore)
+	assert.Equal(t, []time.Time{now, now, now}, provider.sessionBefore)
+	assert.Equal(t, []time.Time{now}, provider.revokedBefore)
+	assert.Len(t, provider.auditBefore, 0)
+
+	for _, entry := range hook.AllEntries() {
+		assert.NotEqual(t, logrus.ErrorLevel, entry.Level)
+	}
+}
+
+func TestCleanupShouldDeleteAuditEventsWithRetention(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	provider := &testCleanupProvider{
+		audit: []int64{2, 1},
+	}
+
+	metrics := &testCleanupMetrics{deleted: map[string]int64{}}
+
+	log, _ := test.NewNullLogger()
+
+	c := NewCleanup(provider, schema.StorageCleanup{Interval: time.Hour, BatchSize: 2}, time.Hour*24, metrics, clock.NewFixed(now), logrus.NewEntry(log))
+
+	c.cleanup(context.Background())
+
+	assert.Equal(t, map[string]int64{tableAuditEvents: 3}, metrics.deleted)
+	assert.Equal(t, []time.Time{now.Add(-time.Hour * 24), now.Add(-time.Hour * 24)}, provider.auditBefore)
+}
+
+func TestCleanupShouldContinueAfterError(t *testing.T) {
+	provider := &testCleanupProvider{
+		jti:     []int64{2, 1},
+		jtiErr:  errors.New("bad connection"),
+		consent: []int64{1},
+		iv:      []int64{0},
+	}
+
+	log, hook := test.NewNullLogger()
+
+	c := NewCleanup(provider, schema.StorageCleanup{Interval: time.Hour, BatchSize: 2}, 0, nil, clock.NewFixed(time.Unix(1700000000, 0)), logrus.NewEntry(log))
+
+	c.cleanup(context.Background())
+
+	assert.Len(t, provider.jtiBefore, 1)
+	assert.Len(t, provider.consentBefore, 1)
+	assert.Len(t, provider.ivBefore, 1)
+
+	entry := hook.LastEntry()
+
+	if assert.Len(t, hook.AllEntries(), 1) {
+		assert.Equal(t, logrus.ErrorLevel, entry.Level)
+		assert.Equal(t, "Error occurred deleting the expired rows", entry.Message)
+		assert.Equal(t, tableOAuth2BlacklistedJTI, entry.Data["table"])
+		assert.Equal(t, int64(2), entry.Data["deleted"])
+	}
+}
+
+type testCleanupProvider struct {
+	Provider
+
+	jti, consent, iv, ciba, devices, mobile, saml, push, session, revoked, audit                                                                   []int64
+	jtiErr                                                                                                                                         error
+	jtiBefore, consentBefore, ivBefore, cibaBefore, devicesBefore, mobileBefore, samlBefore, pushBefore, sessionBefore, revokedBefore, auditBefore []time.Time
+}
+
+func (p *testCleanupProvider) DeleteExpiredOAuth2BlacklistedJTIs(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.jtiBefore = append(p.jtiBefore, before)
+
+	return testCleanupNext(&p.jti), p.jtiErr
+}
+
+func (p *testCleanupProvider) DeleteAbandonedOAuth2ConsentSessions(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.consentBefore = append(p.consentBefore, before)
+
+	return testCleanupNext(&p.consent), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredIdentityVerifications(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.ivBefore = append(p.ivBefore, before)
+
+	return testCleanupNext(&p.iv), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredOAuth2CIBARequests(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.cibaBefore = append(p.cibaBefore, before)
+
+	return testCleanupNext(&p.ciba), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredDeviceCertificates(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.devicesBefore = append(p.devicesBefore, before)
+
+	return testCleanupNext(&p.devices), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredMobileDevices(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.mobileBefore = append(p.mobileBefore, before)
+
+	return testCleanupNext(&p.mobile), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredSAMLSessions(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.samlBefore = append(p.samlBefore, before)
+
+	return testCleanupNext(&p.saml), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredPushChallenges(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.pushBefore = append(p.pushBefore, before)
+
+	return testCleanupNext(&p.push), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredSessions(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.sessionBefore = append(p.sessionBefore, before)
+
+	return testCleanupNext(&p.session), nil
+}
+
+func (p *testCleanupProvider) DeleteExpiredUserSessionRevocations(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.revokedBefore = append(p.revokedBefore, before)
+
+	return testCleanupNext(&p.revoked), nil
+}
+
+func (p *testCleanupProvider) DeleteAuditEvents(_ context.Context, before time.Time, _ int) (deleted int64, err error) {
+	p.auditBefore = append(p.auditBefore, before)
+
+	return testCleanupNext(&p.audit), nil
+}
+
+func testCleanupNext(values *[]int64) (value int64) {
+	if len(*values) == 0 {
+		return 0
+	}
+
+	value, *values = (*values)[0], (*values)[1:]
+
+	return value
+}
+
+type testCleanupMetrics struct {
+	deleted map[string]int64
+}
+
+func (m *testCleanupMetrics) RecordStorageCleanup(table string, deleted int64) {
+	m.deleted[table] += deleted
+}
@@ -2,19 +2,33 @@ package storage
 
 import (
 	"regexp"
+	"time"
 )
 
 const (
-	tableAuthenticationLogs   = "authentication_logs"
-	tableDuoDevices           = "duo_devices"
-	tableIdentityVerification = "identity_verification"
-	tableOneTimeCode          = "one_time_code"
-	tableTOTPConfigurations   = "totp_configurations"
-	tableTOTPHistory          = "totp_history"
-	tableUserOpaqueIdentifier = "user_opaque_identifier"
-	tableUserPreferences      = "user_preferences"
-	tableWebAuthnCredentials  = "webauthn_credentials" //nolint:gosec // This is a table name, not a credential.
-	tableWebAuthnUsers        = "webauthn_users"
+	tableAppPassword           = "app_password"
+	tableAuthenticationLogs    = "authentication_logs"
+	tableDeviceCertificate     = "device_certificate"
+	tableDirectoryUsers        = "directory_users"
+	tableDirectoryGroups       = "directory_groups"
+	tableDirectoryMembers      = "directory_group_members"
+	tableDuoDevices            = "duo_devices"
+	tableIdentityVerification  = "identity_verification"
+	tableMobileDevice          = "mobile_device"
+	tableOneTimeCode           = "one_time_code"
+	tablePhoneNumber           = "phone_number"
+	tablePushChallenge         = "push_challenge"
+	tableRecoveryCode          = "recovery_code"
+	tableSAMLSession           = "saml_session"
+	tableSessions              = "sessions"
+	tableTOTPConfigurations    = "totp_configurations"
+	tableTOTPHistory           = "totp_history"
+	tableUserOpaqueIdentifier  = "user_opaque_identifier"
+	tableUserPreferences       = "user_preferences"
+	tableUserSessionIndex      = "user_session_index"
+	tableUserSessionRevocation = "user_session_revocation"
+	tableWebAuthnCredentials   = "webauthn_credentials" //nolint:gosec // This is a table name, not a credential.
+	tableWebAuthnUsers         = "webauthn_users"
 
 	tableOAuth2BlacklistedJTI          = "oauth2_blacklisted_jti"
 	tableOAuth2ConsentSession          = "oauth2_consent_session"
@@ -22,10 +36,18 @@ const (
 
 	tableOAuth2AccessTokenSession   = "oauth2_access_token_session" //nolint:gosec // This is not a hardcoded credential.
 	tableOAuth2AuthorizeCodeSession = "oauth2_authorization_code_session"
+	tableOAuth2CIBARequest          = "oauth2_ciba_request"
+	tableOAuth2DeviceCodeSession    = "oauth2_device_code_session"
+	tableOAuth2DynamicClient        = "oauth2_dynamic_client"
 	tableOAuth2OpenIDConnectSession = "oauth2_openid_connect_session"
 	tableOAuth2PARContext           = "oauth2_par_context"
 	tableOAuth2PKCERequestSession   = "oauth2_pkce_request_session"
-	tableOAuth2RefreshTokenSession  = "oauth2_refresh_token_session" //nolint:gosec // This is not a hardcoded credential.
+	tableOAuth2RefreshTokenSession  = "oauth2_refresh_token_session"  //nolint:gosec // This is not a hardcoded credential.
+	tableOAuth2TokenExchangeLineage = "oauth2_token_exchange_lineage" //nolint:gosec // This is not a hardcoded credential.
+
+	tableConfigurationFingerprints = "configuration_fingerprints"
+	tableAuditEvents               = "audit_events"
+	tableLeaderElectionLeases      = "leader_election_leases"
 
 	tableMigrations = "migrations"
 	tableEncryption = "encryption"
@@ -35,6 +57,16 @@ const (
 	encryptionNameCheck = "check"
 )
 
+// encryptionRotateKeyTargets are the tables and columns which contain values encrypted with the encryption key, other
+// than the OAuth 2.0 session tables.
+var encryptionRotateKeyTargets = []encRotateKeyTarget{
+	{table: tableOneTimeCode, column: "code"},
+	{table: tableTOTPConfigurations, column: "secret"},
+	{table: tableWebAuthnCredentials, column: "public_key"},
+	{table: tableUserSessionIndex, column: "session_id"},
+	{table: tablePhoneNumber, column: "phone_number"},
+}
+
 // WARNING: Do not change/remove these consts. They are used for Pre1 migrations.
 const (
 	tablePre1TOTPSecrets                = "totp_secrets"
@@ -52,15 +84,35 @@ var tablesPre1 = []string{
 }
 
 const (
-	pathMigrations   = "migrations"
-	providerMySQL    = "mysql"
-	providerPostgres = "postgres"
-	providerSQLite   = "sqlite"
+	pathMigrations      = "migrations"
+	providerMySQL       = "mysql"
+	providerPostgres    = "postgres"
+	providerSQLite      = "sqlite"
+	providerCockroachDB = "cockroachdb"
+)
+
+const (
+	// sqlStateSerializationFailure is the SQLSTATE returned by PostgreSQL compatible databases when a transaction can't
+	// be serialized and must be retried.
+	sqlStateSerializationFailure = "40001"
+
+	transactionRetryBackoffInitial = 10 * time.Millisecond
+	transactionRetryBackoffMaximum = time.Second
+)
+
+const (
+	// cleanupConsentSessionLifespan is how long a consent session can go without a response before the cleanup
+	// considers it abandoned.
+	cleanupConsentSessionLifespan = 24 * time.Hour
 )
 
 const (
 	// SchemaLatest represents the value expected for a "migrate to latest" migration. It's the maximum 32bit signed integer.
 	SchemaLatest = 2147483647
+
+	// schemaVersionCompatible is the first schema version which records the compatible schema version of each
+	// migration in the migrations table.
+	schemaVersionCompatible = 22
 )
 
 type ctxKey int
@@ -73,6 +125,11 @@ var (
 	reMigration = regexp.MustCompile(`^V(?P<Version>\d{4})\.(?P<Name>[^.]+)\.(?P<Direction>(up|down))\.sql$`)
 )
 
+var (
+	// auditEventsUntilMax is the upper time bound used when selecting audit events without an upper bound.
+	auditEventsUntilMax = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+)
+
 const (
 	na      = "N/A"
 	invalid = "invalid"
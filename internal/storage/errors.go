@@ -14,9 +14,43 @@ var (
 	// ErrNoWebAuthnCredential error thrown when no WebAuthn credential handle has been found in DB.
 	ErrNoWebAuthnCredential = errors.New("no WebAuthn credential found")
 
+	// ErrNoPhoneNumber error thrown when no verified phone number has been found in DB.
+	ErrNoPhoneNumber = errors.New("no phone number for user")
+
 	// ErrNoDuoDevice error thrown when no Duo device and method has been found in DB.
 	ErrNoDuoDevice = errors.New("no Duo device and method saved")
 
+	// ErrNoDeviceCertificate error thrown when no device certificate has been found in DB.
+	ErrNoDeviceCertificate = errors.New("no device certificate found")
+
+	// ErrNoUserSessionIndex error thrown when no user session index has been found in DB.
+	ErrNoUserSessionIndex = errors.New("no user session index found")
+
+	// ErrNoAppPassword error thrown when no app password has been found in DB.
+	ErrNoAppPassword = errors.New("no app password found")
+
+	// ErrNoSession error thrown when no session which hasn't expired has been found in DB.
+	ErrNoSession = errors.New("no session found")
+
+	// ErrNoDirectoryUser error thrown when no directory user has been found in DB.
+	ErrNoDirectoryUser = errors.New("no directory user found")
+
+	// ErrNoMobileDevice error thrown when no mobile device has been found in DB.
+	ErrNoMobileDevice = errors.New("no mobile device found")
+
+	// ErrNoPushChallenge error thrown when no push challenge has been found in DB.
+	ErrNoPushChallenge = errors.New("no push challenge found")
+
+	// ErrNoSAMLSession error thrown when no SAML session has been found in DB.
+	ErrNoSAMLSession = errors.New("no saml session found")
+
+	// ErrNoDirectoryGroup error thrown when no directory group has been found in DB.
+	ErrNoDirectoryGroup = errors.New("no directory group found")
+
+	// ErrIdentityVerificationNotConsumable error thrown when an identity verification can't be consumed as it doesn't
+	// exist or has already been consumed or revoked.
+	ErrIdentityVerificationNotConsumable = errors.New("the identity verification doesn't exist or has already been consumed or revoked")
+
 	// ErrNoAvailableMigrations is returned when no available migrations can be found.
 	ErrNoAvailableMigrations = errors.New("no available migrations")
 
@@ -51,12 +85,19 @@ const (
 	errFmtFailedMigration                     = "schema migration %d (%s) failed: %w"
 	errFmtSchemaCurrentGreaterThanLatestKnown = "current schema version is greater than the latest known schema " +
 		"version, you must downgrade to schema version %d before you can use this version of Authelia"
+	errFmtSchemaCurrentIncompatible = "current schema version %d requires a version of Authelia which supports at least " +
+		"schema version %d but the latest schema version supported by this version of Authelia is %d, you must upgrade " +
+		"this version of Authelia or downgrade the schema before you can use this version of Authelia"
 )
 
 const (
 	logFmtMigrationFromTo   = "Storage schema migration from %s to %s is being attempted"
 	logFmtMigrationComplete = "Storage schema migration from %s to %s is complete"
 	logFmtErrClosingConn    = "Error occurred closing SQL connection: %v"
+
+	logFmtSchemaCurrentCompatible = "Storage schema version %d is newer than the latest schema version %d supported by " +
+		"this version of Authelia but is compatible with it, skipping the schema migration which is expected during a " +
+		"rolling upgrade and this version of Authelia should be upgraded as soon as possible"
 )
 
 const (
@@ -16,13 +16,46 @@ import (
 //go:embed migrations/*
 var migrationsFS embed.FS
 
+// migrationsExpand are the versions of the up migrations which only expand the schema, i.e. they only add tables,
+// indexes, or columns which are nullable or have a default value. Versions of Authelia which only know about the schema
+// prior to one of these migrations can continue to operate against the schema after it has been applied. All other
+// migrations are contract migrations which every running version of Authelia must know about. Migrations prior to
+// schemaVersionCompatible are all considered contract migrations.
+var migrationsExpand = map[int]bool{
+	22: true,
+	23: true,
+	24: true,
+	25: true,
+	26: true,
+	27: true,
+	28: true,
+	29: true,
+	30: true,
+	31: true,
+	32: true,
+	33: true,
+	34: true,
+	35: true,
+	36: true,
+}
+
+// schemaCompatibleVersion returns the oldest latest schema version a version of Authelia must know about in order to
+// operate against the given schema version.
+func schemaCompatibleVersion(version int) int {
+	for version > 0 && migrationsExpand[version] {
+		version--
+	}
+
+	return version
+}
+
 func latestMigrationVersion(providerName string) (version int, err error) {
 	var (
 		entries   []fs.DirEntry
 		migration model.SchemaMigration
 	)
 
-	if entries, err = migrationsFS.ReadDir(path.Join(pathMigrations, providerName)); err != nil {
+	if entries, err = migrationsFS.ReadDir(path.Join(pathMigrations, migrationsProviderName(providerName))); err != nil {
 		return -1, err
 	}
 
@@ -60,7 +93,7 @@ func loadMigrations(providerName string, prior, target int) (migrations []model.
 		entries   []fs.DirEntry
 	)
 
-	if entries, err = migrationsFS.ReadDir(path.Join(pathMigrations, providerName)); err != nil {
+	if entries, err = migrationsFS.ReadDir(path.Join(pathMigrations, migrationsProviderName(providerName))); err != nil {
 		return nil, err
 	}
 
@@ -136,11 +169,16 @@ func scanMigration(providerName, m string) (migration model.SchemaMigration, err
 
 	var data []byte
 
-	if data, err = migrationsFS.ReadFile(path.Join(pathMigrations, providerName, m)); err != nil {
+	if data, err = migrationsFS.ReadFile(path.Join(pathMigrations, migrationsProviderName(providerName), m)); err != nil {
 		return model.SchemaMigration{}, err
 	}
 
-	migration.Query = string(data)
+	switch providerName {
+	case providerCockroachDB:
+		migration.Query = cockroachDBMigrationQuery(string(data))
+	default:
+		migration.Query = string(data)
+	}
 
 	switch direction := result[reMigration.SubexpIndex("Direction")]; direction {
 	case "up":
@@ -155,3 +193,14 @@ func scanMigration(providerName, m string) (migration model.SchemaMigration, err
 
 	return migration, nil
 }
+
+// migrationsProviderName returns the name of the provider which has the migrations for the given providerName. Providers
+// which are compatible with another provider share the migrations of that provider.
+func migrationsProviderName(providerName string) string {
+	switch providerName {
+	case providerCockroachDB:
+		return providerPostgres
+	default:
+		return providerName
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS configuration_fingerprints;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS configuration_fingerprints (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    instance VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    version VARCHAR(100) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX configuration_fingerprints_instance_key ON configuration_fingerprints (instance);
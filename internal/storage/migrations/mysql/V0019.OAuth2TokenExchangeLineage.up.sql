@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS oauth2_token_exchange_lineage (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    request_id VARCHAR(40) NOT NULL,
+    parent_request_id VARCHAR(40) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    parent_client_id VARCHAR(255) NOT NULL,
+    subject CHAR(36) NULL DEFAULT NULL,
+    exchanged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX oauth2_token_exchange_lineage_request_id_key ON oauth2_token_exchange_lineage (request_id);
+CREATE INDEX oauth2_token_exchange_lineage_parent_request_id_idx ON oauth2_token_exchange_lineage (parent_request_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS oauth2_dynamic_client (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    client_id VARCHAR(255) NOT NULL,
+    registration_access_token_signature VARCHAR(255) NOT NULL,
+    client_secret VARCHAR(512) NULL DEFAULT NULL,
+    metadata TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX oauth2_dynamic_client_client_id_key ON oauth2_dynamic_client (client_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS device_certificate (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    serial VARCHAR(64) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX device_certificate_serial_key ON device_certificate (serial);
+CREATE INDEX device_certificate_username_idx ON device_certificate (username);
+CREATE INDEX device_certificate_expires_at_idx ON device_certificate (expires_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS user_session_index (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    public_id CHAR(36) NOT NULL,
+    signature VARCHAR(128) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    domain VARCHAR(255) NOT NULL,
+    remote_ip VARCHAR(39) NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    session_id BLOB NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX user_session_index_public_id_key ON user_session_index (public_id);
+CREATE UNIQUE INDEX user_session_index_signature_key ON user_session_index (signature);
+CREATE INDEX user_session_index_username_idx ON user_session_index (username);
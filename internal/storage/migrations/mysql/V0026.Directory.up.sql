@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS directory_users (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    public_id CHAR(36) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    given_name VARCHAR(255) NOT NULL DEFAULT '',
+    family_name VARCHAR(255) NOT NULL DEFAULT '',
+    emails TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX directory_users_public_id_key ON directory_users (public_id);
+CREATE UNIQUE INDEX directory_users_username_key ON directory_users (username);
+CREATE INDEX directory_users_external_id_idx ON directory_users (external_id);
+
+CREATE TABLE IF NOT EXISTS directory_groups (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    public_id CHAR(36) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX directory_groups_public_id_key ON directory_groups (public_id);
+CREATE UNIQUE INDEX directory_groups_display_name_key ON directory_groups (display_name);
+CREATE INDEX directory_groups_external_id_idx ON directory_groups (external_id);
+
+CREATE TABLE IF NOT EXISTS directory_group_members (
+    group_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (group_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE INDEX directory_group_members_user_id_idx ON directory_group_members (user_id);
+
+ALTER TABLE directory_group_members
+    ADD CONSTRAINT directory_group_members_group_id_fkey
+        FOREIGN KEY (group_id)
+            REFERENCES directory_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    ADD CONSTRAINT directory_group_members_user_id_fkey
+        FOREIGN KEY (user_id)
+            REFERENCES directory_users (id) ON UPDATE CASCADE ON DELETE CASCADE;
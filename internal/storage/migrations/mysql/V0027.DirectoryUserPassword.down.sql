@@ -0,0 +1 @@
+ALTER TABLE directory_users DROP COLUMN password;
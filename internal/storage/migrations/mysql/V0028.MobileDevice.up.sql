@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS mobile_device (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    public_id CHAR(36) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    access_signature VARCHAR(128) NOT NULL,
+    access_expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    refresh_signature VARCHAR(128) NOT NULL,
+    previous_refresh_signature VARCHAR(128) NULL DEFAULT NULL,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    push_provider VARCHAR(20) NULL DEFAULT NULL,
+    push_token VARCHAR(512) NULL DEFAULT NULL,
+    revoked_at TIMESTAMP NULL DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX mobile_device_public_id_key ON mobile_device (public_id);
+CREATE UNIQUE INDEX mobile_device_access_signature_key ON mobile_device (access_signature);
+CREATE UNIQUE INDEX mobile_device_refresh_signature_key ON mobile_device (refresh_signature);
+CREATE INDEX mobile_device_username_idx ON mobile_device (username);
+CREATE INDEX mobile_device_expires_at_idx ON mobile_device (expires_at);
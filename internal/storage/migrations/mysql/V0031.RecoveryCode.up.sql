@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS recovery_code (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    signature VARCHAR(128) NOT NULL,
+    consumed_at TIMESTAMP NULL DEFAULT NULL,
+    consumed_ip VARCHAR(39) NULL DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX recovery_code_signature_key ON recovery_code (signature);
+CREATE INDEX recovery_code_username_idx ON recovery_code (username);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    signature VARCHAR(128) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data BLOB NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX sessions_signature_key ON sessions (signature);
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
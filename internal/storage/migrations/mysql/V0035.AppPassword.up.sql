@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS app_password (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    public_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    domains TEXT NOT NULL,
+    signature VARCHAR(128) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX app_password_public_id_key ON app_password (public_id);
+CREATE UNIQUE INDEX app_password_signature_key ON app_password (signature);
+CREATE INDEX app_password_username_idx ON app_password (username);
@@ -0,0 +1,11 @@
+ALTER TABLE user_session_index ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS user_session_revocation (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    signature VARCHAR(128) NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE INDEX user_session_revocation_revoked_at_idx ON user_session_revocation (revoked_at);
+CREATE INDEX user_session_revocation_expires_at_idx ON user_session_revocation (expires_at);
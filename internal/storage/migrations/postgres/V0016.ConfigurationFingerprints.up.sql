@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS configuration_fingerprints (
+    id SERIAL CONSTRAINT configuration_fingerprints_pkey PRIMARY KEY,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    instance VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    version VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX configuration_fingerprints_instance_key ON configuration_fingerprints (instance);
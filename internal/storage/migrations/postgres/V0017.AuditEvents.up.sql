@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id SERIAL CONSTRAINT audit_events_pkey PRIMARY KEY,
+    event_id CHAR(36) NOT NULL,
+    time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    type VARCHAR(100) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    remote_ip VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_method VARCHAR(8) NOT NULL DEFAULT '',
+    request_host VARCHAR(255) NOT NULL DEFAULT '',
+    request_path TEXT NOT NULL DEFAULT '',
+    trace_id VARCHAR(64) NOT NULL DEFAULT '',
+    details TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX audit_events_time_idx ON audit_events (time);
+CREATE INDEX audit_events_username_time_idx ON audit_events (username, time);
+CREATE INDEX audit_events_type_time_idx ON audit_events (type, time);
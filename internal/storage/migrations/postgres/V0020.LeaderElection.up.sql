@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS leader_election_leases (
+    id SERIAL CONSTRAINT leader_election_leases_pkey PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX leader_election_leases_name_key ON leader_election_leases (name);
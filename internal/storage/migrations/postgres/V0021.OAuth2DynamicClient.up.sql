@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS oauth2_dynamic_client (
+    id SERIAL CONSTRAINT oauth2_dynamic_client_pkey PRIMARY KEY,
+    client_id VARCHAR(255) NOT NULL,
+    registration_access_token_signature VARCHAR(255) NOT NULL,
+    client_secret VARCHAR(512) NULL DEFAULT NULL,
+    metadata TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX oauth2_dynamic_client_client_id_key ON oauth2_dynamic_client (client_id);
@@ -0,0 +1 @@
+ALTER TABLE migrations ADD COLUMN version_compatible INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS device_certificate (
+    id SERIAL CONSTRAINT device_certificate_pkey PRIMARY KEY,
+    serial VARCHAR(64) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX device_certificate_serial_key ON device_certificate (serial);
+CREATE INDEX device_certificate_username_idx ON device_certificate (username);
+CREATE INDEX device_certificate_expires_at_idx ON device_certificate (expires_at);
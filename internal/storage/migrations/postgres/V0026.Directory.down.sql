@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS directory_group_members;
+DROP TABLE IF EXISTS directory_groups;
+DROP TABLE IF EXISTS directory_users;
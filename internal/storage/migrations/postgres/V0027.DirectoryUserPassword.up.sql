@@ -0,0 +1 @@
+ALTER TABLE directory_users ADD COLUMN password TEXT NULL DEFAULT NULL;
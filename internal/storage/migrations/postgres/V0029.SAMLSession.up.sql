@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS saml_session (
+    id SERIAL CONSTRAINT saml_session_pkey PRIMARY KEY,
+    session_index VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(512) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    name_id VARCHAR(512) NOT NULL,
+    name_id_format VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX saml_session_session_index_key ON saml_session (session_index);
+CREATE INDEX saml_session_username_idx ON saml_session (username);
+CREATE INDEX saml_session_expires_at_idx ON saml_session (expires_at);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS recovery_code (
+    id SERIAL CONSTRAINT recovery_code_pkey PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    signature VARCHAR(128) NOT NULL,
+    consumed_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    consumed_ip VARCHAR(39) NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX recovery_code_signature_key ON recovery_code (signature);
+CREATE INDEX recovery_code_username_idx ON recovery_code (username);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS push_challenge (
+    id SERIAL CONSTRAINT push_challenge_pkey PRIMARY KEY,
+    challenge_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    device VARCHAR(100) NOT NULL,
+    message TEXT NOT NULL,
+    signature VARCHAR(128) NOT NULL,
+    request_ip VARCHAR(39) NULL DEFAULT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    responded_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    responded_ip VARCHAR(39) NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX push_challenge_challenge_id_key ON push_challenge (challenge_id);
+CREATE INDEX push_challenge_username_idx ON push_challenge (username);
+CREATE INDEX push_challenge_expires_at_idx ON push_challenge (expires_at);
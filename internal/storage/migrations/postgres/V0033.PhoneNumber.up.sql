@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS phone_number (
+    id SERIAL CONSTRAINT phone_number_pkey PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    phone_number BYTEA NOT NULL
+);
+
+CREATE UNIQUE INDEX phone_number_username_key ON phone_number (username);
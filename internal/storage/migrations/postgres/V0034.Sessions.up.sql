@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL CONSTRAINT sessions_pkey PRIMARY KEY,
+    signature VARCHAR(128) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data BYTEA NOT NULL
+);
+
+CREATE UNIQUE INDEX sessions_signature_key ON sessions (signature);
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS app_password (
+    id SERIAL CONSTRAINT app_password_pkey PRIMARY KEY,
+    public_id CHAR(36) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    domains TEXT NOT NULL,
+    signature VARCHAR(128) NOT NULL
+);
+
+CREATE UNIQUE INDEX app_password_public_id_key ON app_password (public_id);
+CREATE UNIQUE INDEX app_password_signature_key ON app_password (signature);
+CREATE INDEX app_password_username_idx ON app_password (username);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS configuration_fingerprints (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    instance VARCHAR(255) NOT NULL,
+    fingerprint CHAR(64) NOT NULL,
+    version VARCHAR(100) NOT NULL
+);
+
+CREATE UNIQUE INDEX configuration_fingerprints_instance_key ON configuration_fingerprints (instance);
@@ -0,0 +1 @@
+ALTER TABLE migrations DROP COLUMN version_compatible;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS oauth2_ciba_request (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    request_id VARCHAR(40) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    signature VARCHAR(255) NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    subject CHAR(36) NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    txid VARCHAR(255) NULL DEFAULT NULL,
+    delivery_mode VARCHAR(10) NOT NULL,
+    notified BOOLEAN NOT NULL DEFAULT FALSE,
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    authenticated_at DATETIME NULL DEFAULT NULL,
+    requested_scopes TEXT NOT NULL,
+    granted_scopes TEXT NOT NULL,
+    requested_audience TEXT NULL DEFAULT '',
+    granted_audience TEXT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    form_data TEXT NOT NULL,
+    session_data BLOB NOT NULL
+);
+
+CREATE UNIQUE INDEX oauth2_ciba_request_signature_key ON oauth2_ciba_request (signature);
+CREATE INDEX oauth2_ciba_request_request_id_idx ON oauth2_ciba_request (request_id);
+CREATE INDEX oauth2_ciba_request_client_id_idx ON oauth2_ciba_request (client_id);
+CREATE INDEX oauth2_ciba_request_expires_at_idx ON oauth2_ciba_request (expires_at);
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS directory_users (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    public_id CHAR(36) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    display_name VARCHAR(255) NOT NULL DEFAULT '',
+    given_name VARCHAR(255) NOT NULL DEFAULT '',
+    family_name VARCHAR(255) NOT NULL DEFAULT '',
+    emails TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX directory_users_public_id_key ON directory_users (public_id);
+CREATE UNIQUE INDEX directory_users_username_key ON directory_users (username);
+CREATE INDEX directory_users_external_id_idx ON directory_users (external_id);
+
+CREATE TABLE IF NOT EXISTS directory_groups (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    public_id CHAR(36) NOT NULL,
+    external_id VARCHAR(255) NULL DEFAULT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX directory_groups_public_id_key ON directory_groups (public_id);
+CREATE UNIQUE INDEX directory_groups_display_name_key ON directory_groups (display_name);
+CREATE INDEX directory_groups_external_id_idx ON directory_groups (external_id);
+
+CREATE TABLE IF NOT EXISTS directory_group_members (
+    group_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (group_id, user_id),
+    CONSTRAINT directory_group_members_group_id_fkey
+        FOREIGN KEY (group_id)
+            REFERENCES directory_groups (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT directory_group_members_user_id_fkey
+        FOREIGN KEY (user_id)
+            REFERENCES directory_users (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX directory_group_members_user_id_idx ON directory_group_members (user_id);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS saml_session (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    session_index VARCHAR(64) NOT NULL,
+    entity_id VARCHAR(512) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    name_id VARCHAR(512) NOT NULL,
+    name_id_format VARCHAR(100) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX saml_session_session_index_key ON saml_session (session_index);
+CREATE INDEX saml_session_username_idx ON saml_session (username);
+CREATE INDEX saml_session_expires_at_idx ON saml_session (expires_at);
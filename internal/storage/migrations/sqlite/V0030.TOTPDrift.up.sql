@@ -0,0 +1 @@
+ALTER TABLE totp_configurations ADD COLUMN drift INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS recovery_code (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    signature VARCHAR(128) NOT NULL,
+    consumed_at DATETIME NULL DEFAULT NULL,
+    consumed_ip VARCHAR(39) NULL DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX recovery_code_signature_key ON recovery_code (signature);
+CREATE INDEX recovery_code_username_idx ON recovery_code (username);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS phone_number (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at DATETIME NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    phone_number BLOB NOT NULL
+);
+
+CREATE UNIQUE INDEX phone_number_username_key ON phone_number (username);
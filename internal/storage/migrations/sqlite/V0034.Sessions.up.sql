@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    signature VARCHAR(128) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data BLOB NOT NULL
+);
+
+CREATE UNIQUE INDEX sessions_signature_key ON sessions (signature);
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
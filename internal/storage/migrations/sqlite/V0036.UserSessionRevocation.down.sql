@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_session_revocation;
+
+ALTER TABLE user_session_index DROP COLUMN user_agent;
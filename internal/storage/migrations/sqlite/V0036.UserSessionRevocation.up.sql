@@ -0,0 +1,11 @@
+ALTER TABLE user_session_index ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS user_session_revocation (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    signature VARCHAR(128) NOT NULL,
+    revoked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX user_session_revocation_revoked_at_idx ON user_session_revocation (revoked_at);
+CREATE INDEX user_session_revocation_expires_at_idx ON user_session_revocation (expires_at);
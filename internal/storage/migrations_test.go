@@ -11,7 +11,7 @@ import (
 
 const (
 	// This is the latest schema version for the purpose of tests.
-	LatestVersion = 15
+	LatestVersion = 36
 )
 
 func TestShouldObtainCorrectMigrations(t *testing.T) {
@@ -31,6 +31,10 @@ func TestShouldObtainCorrectMigrations(t *testing.T) {
 			"ShouldTestMySQL",
 			providerMySQL,
 		},
+		{
+			"ShouldTestCockroachDB",
+			providerCockroachDB,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -77,6 +81,40 @@ func TestShouldObtainCorrectMigrations(t *testing.T) {
 	}
 }
 
+func TestCockroachDBMigrationQuery(t *testing.T) {
+	query := `ALTER TABLE totp_configurations
+    DROP CONSTRAINT IF EXISTS totp_configurations_username_key,
+    DROP CONSTRAINT IF EXISTS totp_configurations_pkey;
+
+DROP INDEX IF EXISTS totp_configurations_username_key;
+
+ALTER TABLE oauth2_consent_session
+    DROP CONSTRAINT IF EXISTS oauth2_consent_session_pkey1,
+    DROP CONSTRAINT oauth2_consent_session_subject_fkey;
+
+ALTER TABLE webauthn_devices
+    ALTER COLUMN aaguid DROP NOT NULL;
+`
+
+	expected := `DROP INDEX IF EXISTS totp_configurations_username_key CASCADE;
+
+ALTER TABLE oauth2_consent_session
+    DROP CONSTRAINT oauth2_consent_session_subject_fkey;
+
+ALTER TABLE webauthn_devices
+    ALTER COLUMN aaguid DROP NOT NULL;`
+
+	assert.Equal(t, expected, cockroachDBMigrationQuery(query))
+
+	migrations, err := loadMigrations(providerCockroachDB, 0, LatestVersion)
+	require.NoError(t, err)
+
+	for _, migration := range migrations {
+		assert.Equal(t, providerCockroachDB, migration.Provider)
+		assert.NotRegexp(t, `(?i)DROP CONSTRAINT\s+(IF EXISTS\s+)?\S+_(pkey|key)\d*[,;]`, migration.Query)
+	}
+}
+
 func TestMigrationShouldReturnErrorOnSame(t *testing.T) {
 	migrations, err := loadMigrations(providerPostgres, 1, 1)
 
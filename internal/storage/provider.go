@@ -33,6 +33,17 @@ type Provider interface {
 	// LoadUserInfo loads the model.UserInfo from the storage provider.
 	LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error)
 
+	// LoadUserSummaries loads a page of model.UserSummary for every user known to the storage provider.
+	LoadUserSummaries(ctx context.Context, limit, page int) (summaries []model.UserSummary, err error)
+
+	// LoadUsageStatistics loads the model.UsageStatistics from the storage provider considering the users and clients
+	// active since the provided time.
+	LoadUsageStatistics(ctx context.Context, since time.Time) (stats model.UsageStatistics, err error)
+
+	// LoadAuthenticationHistory loads a page of the authentication attempts for a user which match the provided filter
+	// from the storage provider, sorted by the time of the attempt.
+	LoadAuthenticationHistory(ctx context.Context, username string, filter model.AuthenticationAttemptFilter, pagination model.Pagination) (attempts []model.AuthenticationAttempt, err error)
+
 	/*
 		Implementation for User Opaque Identifiers.
 	*/
@@ -61,6 +72,10 @@ type Provider interface {
 	// sign in information.
 	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt sql.NullTime) (err error)
 
+	// UpdateTOTPConfigurationDrift updates the learned clock drift of a registered TOTP configuration in the storage
+	// provider.
+	UpdateTOTPConfigurationDrift(ctx context.Context, id, drift int) (err error)
+
 	// DeleteTOTPConfiguration delete a TOTP configuration from the storage provider given a username.
 	DeleteTOTPConfiguration(ctx context.Context, username string) (err error)
 
@@ -90,6 +105,9 @@ type Provider interface {
 	// LoadWebAuthnUser loads a registered WebAuthn user from the storage provider.
 	LoadWebAuthnUser(ctx context.Context, rpid, username string) (user *model.WebAuthnUser, err error)
 
+	// LoadWebAuthnUserByUserID loads a registered WebAuthn user from the storage provider using the user handle.
+	LoadWebAuthnUserByUserID(ctx context.Context, rpid, userID string) (user *model.WebAuthnUser, err error)
+
 	/*
 		Implementation for User WebAuthn Device Registrations.
 	*/
@@ -138,7 +156,8 @@ type Provider interface {
 	// SaveIdentityVerification save an identity verification record to the storage provider.
 	SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error)
 
-	// ConsumeIdentityVerification marks an identity verification record in the storage provider as consumed.
+	// ConsumeIdentityVerification marks an identity verification record in the storage provider as consumed. The
+	// ErrIdentityVerificationNotConsumable error is returned if the record was already consumed or revoked.
 	ConsumeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error)
 
 	// RevokeIdentityVerification marks an identity verification record in the storage provider as revoked.
@@ -147,10 +166,18 @@ type Provider interface {
 	// FindIdentityVerification checks if an identity verification record is in the storage provider and active.
 	FindIdentityVerification(ctx context.Context, jti string) (found bool, err error)
 
+	// CountIdentityVerifications returns the number of identity verification records issued to a user for an action
+	// since the provided time.
+	CountIdentityVerifications(ctx context.Context, username, action string, since time.Time) (count int, err error)
+
 	// LoadIdentityVerification loads an Identity Verification but does not do any validation.
 	// For easy validation you should use FindIdentityVerification which ensures the JWT is still valid.
 	LoadIdentityVerification(ctx context.Context, jti string) (verification *model.IdentityVerification, err error)
 
+	// DeleteExpiredIdentityVerifications deletes up to the limit of the identity verification records which expired
+	// before the provided time from the storage provider, returning the number of records deleted.
+	DeleteExpiredIdentityVerifications(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+
 	/*
 		Implementation for Identity Verification (OTP).
 	*/
@@ -162,6 +189,9 @@ type Provider interface {
 	// ConsumeOneTimeCode consumes a one-time code using the signature.
 	ConsumeOneTimeCode(ctx context.Context, code *model.OneTimeCode) (err error)
 
+	// CountOneTimeCodes returns the number of one-time codes issued to a user for an intent since the given time.
+	CountOneTimeCodes(ctx context.Context, username, intent string, since time.Time) (count int, err error)
+
 	// RevokeOneTimeCode revokes a one-time code in the storage provider using the public ID.
 	RevokeOneTimeCode(ctx context.Context, id uuid.UUID, ip model.IP) (err error)
 
@@ -213,6 +243,15 @@ type Provider interface {
 	// challenge ID.
 	LoadOAuth2ConsentSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (consent *model.OAuth2ConsentSession, err error)
 
+	// LoadOAuth2ConsentSessionsByUsername returns a page of the OAuth2.0 consent sessions a user has responded to which
+	// match the provided filter from the storage provider, sorted by the time of the response.
+	LoadOAuth2ConsentSessionsByUsername(ctx context.Context, username string, filter model.OAuth2ConsentSessionFilter, pagination model.Pagination) (consents []model.OAuth2ConsentSession, err error)
+
+	// DeleteAbandonedOAuth2ConsentSessions deletes up to the limit of the OAuth2.0 consent sessions which were
+	// requested before the provided time and never responded to from the storage provider, returning the number of
+	// sessions deleted.
+	DeleteAbandonedOAuth2ConsentSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+
 	/*
 		Implementation for OAuth2.0 General Sessions.
 	*/
@@ -251,6 +290,81 @@ type Provider interface {
 	// UpdateOAuth2PARContext updates an existing OAuth2.0 PAR context in the storage provider.
 	UpdateOAuth2PARContext(ctx context.Context, par model.OAuth2PARContext) (err error)
 
+	// SaveOAuth2DeviceCodeSession saves an OAuth2.0 device code session to the storage provider.
+	SaveOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error)
+
+	// UpdateOAuth2DeviceCodeSession updates the status, subject, last checked time, grants, and session data of an
+	// existing OAuth2.0 device code session in the storage provider.
+	UpdateOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error)
+
+	// LoadOAuth2DeviceCodeSession loads an OAuth2.0 device code session from the storage provider given the device
+	// code signature.
+	LoadOAuth2DeviceCodeSession(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error)
+
+	// LoadOAuth2DeviceCodeSessionByUserCode loads an OAuth2.0 device code session from the storage provider given the
+	// user code signature.
+	LoadOAuth2DeviceCodeSessionByUserCode(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error)
+
+	// DeactivateOAuth2DeviceCodeSession marks an OAuth2.0 device code session as inactive in the storage provider.
+	DeactivateOAuth2DeviceCodeSession(ctx context.Context, signature string) (err error)
+
+	// SaveOAuth2CIBARequest saves an OpenID Connect 1.0 Client Initiated Backchannel Authentication request to the
+	// storage provider.
+	SaveOAuth2CIBARequest(ctx context.Context, request model.OAuth2CIBARequest) (err error)
+
+	// UpdateOAuth2CIBARequestStatus updates the status of a pending OpenID Connect 1.0 Client Initiated Backchannel
+	// Authentication request in the storage provider.
+	UpdateOAuth2CIBARequestStatus(ctx context.Context, signature string, status int, authenticatedAt sql.NullTime) (err error)
+
+	// UpdateOAuth2CIBARequestCheckedAt updates the time the client last polled for an OpenID Connect 1.0 Client
+	// Initiated Backchannel Authentication request in the storage provider.
+	UpdateOAuth2CIBARequestCheckedAt(ctx context.Context, signature string, checkedAt time.Time) (err error)
+
+	// UpdateOAuth2CIBARequestNotified marks the client of an OpenID Connect 1.0 Client Initiated Backchannel
+	// Authentication request as notified in the storage provider.
+	UpdateOAuth2CIBARequestNotified(ctx context.Context, signature string) (err error)
+
+	// LoadOAuth2CIBARequest loads an OpenID Connect 1.0 Client Initiated Backchannel Authentication request from the
+	// storage provider given the signature.
+	LoadOAuth2CIBARequest(ctx context.Context, signature string) (request *model.OAuth2CIBARequest, err error)
+
+	// LoadOAuth2CIBARequestsPending loads up to the limit of the active OpenID Connect 1.0 Client Initiated
+	// Backchannel Authentication requests which have not expired and are either pending or have a client which must
+	// be notified.
+	LoadOAuth2CIBARequestsPending(ctx context.Context, now time.Time, limit int) (requests []model.OAuth2CIBARequest, err error)
+
+	// DeactivateOAuth2CIBARequest marks an OpenID Connect 1.0 Client Initiated Backchannel Authentication request as
+	// inactive in the storage provider.
+	DeactivateOAuth2CIBARequest(ctx context.Context, signature string) (err error)
+
+	// DeleteExpiredOAuth2CIBARequests deletes up to the limit of the OpenID Connect 1.0 Client Initiated Backchannel
+	// Authentication requests which expired before the given time from the storage provider.
+	DeleteExpiredOAuth2CIBARequests(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+
+	// SaveOAuth2TokenExchangeLineage saves the lineage of a token issued via the OAuth2.0 Token Exchange grant to the
+	// storage provider.
+	SaveOAuth2TokenExchangeLineage(ctx context.Context, lineage model.OAuth2TokenExchangeLineage) (err error)
+
+	// LoadOAuth2TokenExchangeLineageByParentRequestID loads the lineage of all tokens which were issued via the OAuth2.0
+	// Token Exchange grant from a token with the given request id.
+	LoadOAuth2TokenExchangeLineageByParentRequestID(ctx context.Context, requestID string) (lineage []model.OAuth2TokenExchangeLineage, err error)
+
+	// SaveOAuth2DynamicClient saves an OAuth2.0 client registered via the Dynamic Client Registration endpoint to the
+	// storage provider.
+	SaveOAuth2DynamicClient(ctx context.Context, client model.OAuth2DynamicClient) (err error)
+
+	// UpdateOAuth2DynamicClient updates the registration access token signature, client secret, and metadata of an
+	// OAuth2.0 client registered via the Dynamic Client Registration endpoint in the storage provider.
+	UpdateOAuth2DynamicClient(ctx context.Context, client model.OAuth2DynamicClient) (err error)
+
+	// LoadOAuth2DynamicClient loads an OAuth2.0 client registered via the Dynamic Client Registration endpoint from the
+	// storage provider.
+	LoadOAuth2DynamicClient(ctx context.Context, clientID string) (client *model.OAuth2DynamicClient, err error)
+
+	// DeleteOAuth2DynamicClient deletes an OAuth2.0 client registered via the Dynamic Client Registration endpoint from
+	// the storage provider.
+	DeleteOAuth2DynamicClient(ctx context.Context, clientID string) (err error)
+
 	/*
 		Implementation for OAuth2.0 Blacklisted JTI's.
 	*/
@@ -261,6 +375,53 @@ type Provider interface {
 	// LoadOAuth2BlacklistedJTI loads an OAuth2.0 blacklisted JTI from the storage provider.
 	LoadOAuth2BlacklistedJTI(ctx context.Context, signature string) (blacklistedJTI *model.OAuth2BlacklistedJTI, err error)
 
+	// DeleteExpiredOAuth2BlacklistedJTIs deletes up to the limit of the OAuth2.0 blacklisted JTIs which expired before
+	// the provided time from the storage provider, returning the number of JTIs deleted.
+	DeleteExpiredOAuth2BlacklistedJTIs(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+
+	/*
+		Implementation for Configuration Fingerprints.
+	*/
+
+	// SaveConfigurationFingerprint saves the fingerprint of the effective configuration of an instance to the storage
+	// provider.
+	SaveConfigurationFingerprint(ctx context.Context, fingerprint model.ConfigurationFingerprint) (err error)
+
+	// LoadConfigurationFingerprints loads the configuration fingerprints of all instances which have been updated since
+	// the provided time from the storage provider.
+	LoadConfigurationFingerprints(ctx context.Context, since time.Time) (fingerprints []model.ConfigurationFingerprint, err error)
+
+	// DeleteConfigurationFingerprints deletes the configuration fingerprints of all instances which have not been
+	// updated since the provided time from the storage provider.
+	DeleteConfigurationFingerprints(ctx context.Context, before time.Time) (err error)
+
+	/*
+		Implementation for Audit Events.
+	*/
+
+	// SaveAuditEvent saves an audit event to the storage provider.
+	SaveAuditEvent(ctx context.Context, event model.AuditEvent) (err error)
+
+	// LoadAuditEvents loads a page of the audit events which match the provided filter from the storage provider,
+	// sorted by the time of the event.
+	LoadAuditEvents(ctx context.Context, filter model.AuditEventFilter, pagination model.Pagination) (events []model.AuditEvent, err error)
+
+	// DeleteAuditEvents deletes up to the limit of the audit events which were recorded before the provided time from
+	// the storage provider, returning the number of events deleted.
+	DeleteAuditEvents(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+
+	/*
+		Implementation for Leader Election.
+	*/
+
+	// AcquireLeaderElectionLease acquires or renews the leader election lease with the given name for the holder until
+	// the expires time, returning true if the holder holds the lease. The lease can only be acquired if it's not held
+	// by another holder or if the lease of the other holder expired before the provided now time.
+	AcquireLeaderElectionLease(ctx context.Context, name, holder string, now, expires time.Time) (acquired bool, err error)
+
+	// ReleaseLeaderElectionLease releases the leader election lease with the given name if it's held by the holder.
+	ReleaseLeaderElectionLease(ctx context.Context, name, holder string) (err error)
+
 	/*
 		Implementation for Schema controls.
 	*/
@@ -274,6 +435,10 @@ type Provider interface {
 	// SchemaLatestVersion returns the latest version available for migration for the storage provider.
 	SchemaLatestVersion() (version int, err error)
 
+	// SchemaCompatibleVersion returns the oldest latest schema version a version of Authelia must know about in order
+	// to operate against the current schema of the storage provider.
+	SchemaCompatibleVersion(ctx context.Context) (version int, err error)
+
 	// SchemaMigrationHistory returns the storage provider migration history rows.
 	SchemaMigrationHistory(ctx context.Context) (migrations []model.Migration, err error)
 
@@ -295,7 +460,23 @@ type Provider interface {
 	// SchemaEncryptionCheckKey checks the encryption key configured is valid for the storage provider.
 	SchemaEncryptionCheckKey(ctx context.Context, verbose bool) (result EncryptionValidationResult, err error)
 
+	// SchemaEncryptionRotateKey uses the previous keys to decrypt the values in the storage provider which are not
+	// encrypted with the configured key and encrypts them with the configured key, updating each batch of values using
+	// a transaction.
+	SchemaEncryptionRotateKey(ctx context.Context, batchSize int) (result EncryptionRotateKeyResult, err error)
+
 	RegulatorProvider
+	DeviceCertificateProvider
+	UserSessionIndexProvider
+	UserSessionRevocationProvider
+	SessionProvider
+	DirectoryProvider
+	MobileDeviceProvider
+	SAMLSessionProvider
+	RecoveryCodeProvider
+	PushChallengeProvider
+	PhoneNumberProvider
+	AppPasswordProvider
 }
 
 // RegulatorProvider is an interface providing storage capabilities for persisting any kind of data related to the regulator.
@@ -306,3 +487,280 @@ type RegulatorProvider interface {
 	// LoadAuthenticationLogs loads authentication attempts from the storage provider (paginated).
 	LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
 }
+
+// DeviceCertificateProvider is an interface providing storage capabilities for persisting the client certificates
+// issued to managed devices.
+type DeviceCertificateProvider interface {
+	// SaveDeviceCertificate saves a client certificate issued to a managed device to the storage provider.
+	SaveDeviceCertificate(ctx context.Context, certificate model.DeviceCertificate) (err error)
+
+	// LoadDeviceCertificate loads a client certificate issued to a managed device from the storage provider given the
+	// serial.
+	LoadDeviceCertificate(ctx context.Context, serial string) (certificate *model.DeviceCertificate, err error)
+
+	// LoadDeviceCertificates loads a page of the client certificates issued to the managed devices of a user from the
+	// storage provider, sorted by the time the certificate was issued.
+	LoadDeviceCertificates(ctx context.Context, username string, pagination model.Pagination) (certificates []model.DeviceCertificate, err error)
+
+	// RevokeDeviceCertificate revokes a client certificate issued to a managed device of a user in the storage
+	// provider.
+	RevokeDeviceCertificate(ctx context.Context, username, serial string, revokedAt time.Time) (err error)
+
+	// DeleteExpiredDeviceCertificates deletes up to the limit of the client certificates issued to managed devices
+	// which expired before the given time from the storage provider.
+	DeleteExpiredDeviceCertificates(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// UserSessionIndexProvider is an interface providing storage capabilities for persisting the index of the sessions of
+// users which allows enumerating the sessions of a user.
+type UserSessionIndexProvider interface {
+	// SaveUserSessionIndex saves a user session index to the storage provider. The session id is encrypted and the
+	// signature is derived from the session id.
+	SaveUserSessionIndex(ctx context.Context, index model.UserSessionIndex) (err error)
+
+	// LoadUserSessionIndexes loads the user session indexes of a user from the storage provider.
+	LoadUserSessionIndexes(ctx context.Context, username string) (indexes []model.UserSessionIndex, err error)
+
+	// LoadUserSessionIndex loads a user session index of a user from the storage provider given the public id.
+	LoadUserSessionIndex(ctx context.Context, username string, publicID uuid.UUID) (index *model.UserSessionIndex, err error)
+
+	// DeleteUserSessionIndex deletes a user session index from the storage provider given the session id.
+	DeleteUserSessionIndex(ctx context.Context, sessionID []byte) (err error)
+}
+
+// UserSessionRevocationProvider is an interface providing storage capabilities for persisting the revocations of the
+// sessions of users which are shared with all of the replicas. The session ids are only stored as a signature derived
+// from the session id.
+type UserSessionRevocationProvider interface {
+	// SaveUserSessionRevocation saves the revocation of a session to the storage provider given the session id.
+	SaveUserSessionRevocation(ctx context.Context, sessionID []byte, revokedAt, expiresAt time.Time) (err error)
+
+	// LoadUserSessionRevocations loads the revocations of the sessions which were revoked since the given time and
+	// which have not expired from the storage provider.
+	LoadUserSessionRevocations(ctx context.Context, since, now time.Time) (revocations []model.UserSessionRevocation, err error)
+
+	// DeleteExpiredUserSessionRevocations deletes up to the limit of the revocations of the sessions which expired
+	// before the given time from the storage provider.
+	DeleteExpiredUserSessionRevocations(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// SessionProvider is an interface providing storage capabilities for persisting the serialized data of the sessions
+// when the sessions are stored in the storage provider instead of Redis or memory. The session ids are only stored as a
+// signature derived from the session id.
+type SessionProvider interface {
+	// SaveSession saves the serialized data of a session to the storage provider, replacing the existing data of the
+	// session with the same id.
+	SaveSession(ctx context.Context, sessionID, data []byte, expiresAt time.Time) (err error)
+
+	// LoadSession loads the serialized data of a session which has not expired from the storage provider given the
+	// session id.
+	LoadSession(ctx context.Context, sessionID []byte, now time.Time) (data []byte, err error)
+
+	// RegenerateSession changes the id and the expiration of a session in the storage provider.
+	RegenerateSession(ctx context.Context, sessionID, newSessionID []byte, expiresAt time.Time) (err error)
+
+	// DeleteSession deletes a session from the storage provider given the session id.
+	DeleteSession(ctx context.Context, sessionID []byte) (err error)
+
+	// CountSessions returns the number of sessions which have not expired in the storage provider.
+	CountSessions(ctx context.Context, now time.Time) (count int, err error)
+
+	// DeleteExpiredSessions deletes up to the limit of the sessions which expired before the given time from the
+	// storage provider.
+	DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// DirectoryProvider is an interface providing storage capabilities for persisting the users and groups which are
+// provisioned by an external identity provider.
+type DirectoryProvider interface {
+	// SaveDirectoryUser saves a new directory user to the storage provider.
+	SaveDirectoryUser(ctx context.Context, user model.DirectoryUser) (err error)
+
+	// UpdateDirectoryUser updates the attributes of a directory user in the storage provider given the public id.
+	UpdateDirectoryUser(ctx context.Context, user model.DirectoryUser) (err error)
+
+	// LoadDirectoryUser loads a directory user from the storage provider given the public id.
+	LoadDirectoryUser(ctx context.Context, publicID uuid.UUID) (user *model.DirectoryUser, err error)
+
+	// LoadDirectoryUsers loads up to the limit of the directory users which match the filter from the storage provider
+	// starting at the offset.
+	LoadDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter, limit, offset int) (users []model.DirectoryUser, err error)
+
+	// CountDirectoryUsers returns the number of directory users which match the filter in the storage provider.
+	CountDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter) (count int, err error)
+
+	// LoadDirectoryUserPassword loads the password digest of an active directory user from the storage provider given
+	// the username. The digest is empty if the directory user doesn't have a password.
+	LoadDirectoryUserPassword(ctx context.Context, username string) (password string, err error)
+
+	// UpdateDirectoryUserPassword updates the password digest of a directory user in the storage provider given the
+	// username.
+	UpdateDirectoryUserPassword(ctx context.Context, username, password string) (err error)
+
+	// DeleteDirectoryUser deletes a directory user and their group memberships from the storage provider given the
+	// public id.
+	DeleteDirectoryUser(ctx context.Context, publicID uuid.UUID) (err error)
+
+	// LoadDirectoryUserGroups loads the directory groups a directory user is a member of from the storage provider
+	// given the username.
+	LoadDirectoryUserGroups(ctx context.Context, username string) (groups []model.DirectoryGroup, err error)
+
+	// SaveDirectoryUserGroups replaces the group memberships of a directory user in the storage provider given the
+	// public id of the user and the public ids of the groups. The public ids of groups which don't exist are ignored.
+	SaveDirectoryUserGroups(ctx context.Context, publicID uuid.UUID, groups []uuid.UUID) (err error)
+
+	// SaveDirectoryGroup saves a new directory group to the storage provider.
+	SaveDirectoryGroup(ctx context.Context, group model.DirectoryGroup) (err error)
+
+	// UpdateDirectoryGroup updates the attributes of a directory group in the storage provider given the public id.
+	UpdateDirectoryGroup(ctx context.Context, group model.DirectoryGroup) (err error)
+
+	// LoadDirectoryGroup loads a directory group from the storage provider given the public id.
+	LoadDirectoryGroup(ctx context.Context, publicID uuid.UUID) (group *model.DirectoryGroup, err error)
+
+	// LoadDirectoryGroups loads up to the limit of the directory groups which match the filter from the storage
+	// provider starting at the offset.
+	LoadDirectoryGroups(ctx context.Context, filter model.DirectoryGroupFilter, limit, offset int) (groups []model.DirectoryGroup, err error)
+
+	// CountDirectoryGroups returns the number of directory groups which match the filter in the storage provider.
+	CountDirectoryGroups(ctx context.Context, filter model.DirectoryGroupFilter) (count int, err error)
+
+	// DeleteDirectoryGroup deletes a directory group and its memberships from the storage provider given the public id.
+	DeleteDirectoryGroup(ctx context.Context, publicID uuid.UUID) (err error)
+
+	// SaveDirectoryGroupMembers replaces the members of a directory group in the storage provider given the public id
+	// of the group and the public ids of the users. The public ids of users which don't exist are ignored.
+	SaveDirectoryGroupMembers(ctx context.Context, publicID uuid.UUID, members []uuid.UUID) (err error)
+
+	// LoadDirectoryGroupMembers loads the members of a directory group from the storage provider given the public id.
+	LoadDirectoryGroupMembers(ctx context.Context, publicID uuid.UUID) (members []model.DirectoryGroupMember, err error)
+}
+
+// MobileDeviceProvider is an interface providing storage capabilities for persisting the mobile devices which
+// authenticated users with the mobile authentication endpoints.
+type MobileDeviceProvider interface {
+	// SaveMobileDevice saves a new mobile device to the storage provider.
+	SaveMobileDevice(ctx context.Context, device model.MobileDevice) (err error)
+
+	// LoadMobileDevice loads a mobile device from the storage provider given the public id.
+	LoadMobileDevice(ctx context.Context, publicID uuid.UUID) (device *model.MobileDevice, err error)
+
+	// LoadMobileDeviceByAccessSignature loads a mobile device from the storage provider given the signature of the
+	// access token.
+	LoadMobileDeviceByAccessSignature(ctx context.Context, signature string) (device *model.MobileDevice, err error)
+
+	// UpdateMobileDeviceTokens updates the access and refresh tokens of a mobile device in the storage provider given
+	// the public id. The tokens are only updated if the current refresh token signature of the device is the previous
+	// refresh token signature of the given device which ensures a refresh token is only exchanged once.
+	UpdateMobileDeviceTokens(ctx context.Context, device model.MobileDevice) (err error)
+
+	// LoadMobileDevicesPush loads the mobile devices of a user from the storage provider which are enrolled for push
+	// notifications and which are neither revoked nor expired at the given time.
+	LoadMobileDevicesPush(ctx context.Context, username string, now time.Time) (devices []model.MobileDevice, err error)
+
+	// UpdateMobileDevicePush updates the push notification registration of a mobile device in the storage provider
+	// given the public id.
+	UpdateMobileDevicePush(ctx context.Context, publicID uuid.UUID, provider, token string) (err error)
+
+	// RevokeMobileDevice revokes a mobile device in the storage provider given the public id.
+	RevokeMobileDevice(ctx context.Context, publicID uuid.UUID, revokedAt time.Time) (err error)
+
+	// DeleteExpiredMobileDevices deletes up to the limit of the mobile devices which refresh tokens expired before the
+	// given time from the storage provider.
+	DeleteExpiredMobileDevices(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// PushChallengeProvider is an interface providing storage capabilities for persisting the push challenges which are
+// delivered to the devices of the users by the push providers and responded to with Authelia.
+type PushChallengeProvider interface {
+	// SavePushChallenge saves a new push challenge to the storage provider. Only the signature of the token is saved.
+	SavePushChallenge(ctx context.Context, challenge model.PushChallenge) (err error)
+
+	// LoadPushChallenge loads a push challenge from the storage provider given the challenge id.
+	LoadPushChallenge(ctx context.Context, challengeID uuid.UUID) (challenge *model.PushChallenge, err error)
+
+	// RespondPushChallenge responds to a pending push challenge in the storage provider given the challenge id and the
+	// raw value of the token, returning true if the challenge was responded to and false if the challenge doesn't
+	// exist, the token doesn't match, the challenge expired, or it was already responded to.
+	RespondPushChallenge(ctx context.Context, challengeID uuid.UUID, token string, status int, respondedAt time.Time, ip model.NullIP) (responded bool, err error)
+
+	// DeleteExpiredPushChallenges deletes up to the limit of the push challenges which expired before the given time
+	// from the storage provider.
+	DeleteExpiredPushChallenges(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
+
+// RecoveryCodeProvider is an interface providing storage capabilities for persisting the single-use recovery codes
+// which can be used in place of the other second factor methods.
+type RecoveryCodeProvider interface {
+	// SaveRecoveryCodes replaces the recovery codes of a user in the storage provider with the given recovery codes.
+	// Only the signatures of the codes are saved.
+	SaveRecoveryCodes(ctx context.Context, username string, codes []model.RecoveryCode) (err error)
+
+	// ConsumeRecoveryCode consumes the recovery code of a user in the storage provider given the raw value, returning
+	// true if the code was consumed and false if the code doesn't exist or was already consumed.
+	ConsumeRecoveryCode(ctx context.Context, username, raw string, consumedAt time.Time, ip model.NullIP) (consumed bool, err error)
+
+	// CountRecoveryCodes returns the number of recovery codes of a user in the storage provider which were not
+	// consumed.
+	CountRecoveryCodes(ctx context.Context, username string) (count int, err error)
+
+	// DeleteRecoveryCodes deletes all of the recovery codes of a user from the storage provider.
+	DeleteRecoveryCodes(ctx context.Context, username string) (err error)
+}
+
+// PhoneNumberProvider is an interface providing storage capabilities for persisting the verified phone numbers which
+// the One-Time Codes of the SMS second factor method are delivered to.
+type PhoneNumberProvider interface {
+	// SavePhoneNumber saves the verified phone number of a user to the storage provider, replacing any existing phone
+	// number of the user.
+	SavePhoneNumber(ctx context.Context, number model.PhoneNumber) (err error)
+
+	// LoadPhoneNumber loads the verified phone number of a user from the storage provider.
+	LoadPhoneNumber(ctx context.Context, username string) (number *model.PhoneNumber, err error)
+
+	// UpdatePhoneNumberSignIn updates the verified phone number in the storage provider with the relevant sign in
+	// information.
+	UpdatePhoneNumberSignIn(ctx context.Context, id int, lastUsedAt sql.NullTime) (err error)
+
+	// DeletePhoneNumber deletes the verified phone number of a user from the storage provider.
+	DeletePhoneNumber(ctx context.Context, username string) (err error)
+}
+
+// AppPasswordProvider is an interface providing storage capabilities for persisting the app passwords which users
+// generate for the applications which can only authenticate with the Authorization header.
+type AppPasswordProvider interface {
+	// SaveAppPassword saves a new app password to the storage provider. Only the signature of the password is stored.
+	SaveAppPassword(ctx context.Context, password model.AppPassword) (err error)
+
+	// LoadAppPassword loads an app password of a user from the storage provider given the raw value.
+	LoadAppPassword(ctx context.Context, username, raw string) (password *model.AppPassword, err error)
+
+	// LoadAppPasswords loads the app passwords of a user from the storage provider.
+	LoadAppPasswords(ctx context.Context, username string) (passwords []model.AppPassword, err error)
+
+	// UpdateAppPasswordSignIn updates an app password in the storage provider with the relevant sign in information.
+	UpdateAppPasswordSignIn(ctx context.Context, id int, lastUsedAt sql.NullTime) (err error)
+
+	// DeleteAppPassword deletes an app password of a user from the storage provider given the public id.
+	DeleteAppPassword(ctx context.Context, username string, publicID uuid.UUID) (err error)
+}
+
+// SAMLSessionProvider is an interface providing storage capabilities for persisting the SAML 2.0 sessions established
+// with the service providers.
+type SAMLSessionProvider interface {
+	// SaveSAMLSession saves a new SAML 2.0 session to the storage provider.
+	SaveSAMLSession(ctx context.Context, session model.SAMLSession) (err error)
+
+	// LoadSAMLSession loads a SAML 2.0 session from the storage provider given the session index.
+	LoadSAMLSession(ctx context.Context, sessionIndex string) (session *model.SAMLSession, err error)
+
+	// RevokeSAMLSession revokes a SAML 2.0 session in the storage provider given the session index.
+	RevokeSAMLSession(ctx context.Context, sessionIndex string, revokedAt time.Time) (err error)
+
+	// RevokeSAMLSessionsByUsername revokes all of the SAML 2.0 sessions of a user in the storage provider.
+	RevokeSAMLSessionsByUsername(ctx context.Context, username string, revokedAt time.Time) (err error)
+
+	// DeleteExpiredSAMLSessions deletes up to the limit of the SAML 2.0 sessions which expired before the given time
+	// from the storage provider.
+	DeleteExpiredSAMLSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error)
+}
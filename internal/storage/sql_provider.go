@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strconv"
@@ -21,8 +23,19 @@ import (
 
 // NewSQLProvider generates a generic SQLProvider to be used with other SQL provider NewUp's.
 func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceName string) (provider SQLProvider) {
-	db, err := sqlx.Open(driverName, dataSourceName)
+	db, err := sqlOpen(config, schema.CircuitBreakerDependencyStorage, driverName, dataSourceName)
 
+	return newSQLProvider(config, name, driverName, db, err)
+}
+
+// NewSQLProviderWithConnector generates a generic SQLProvider in the same way as NewSQLProvider except the connections
+// are opened using the connector, which is used when the connections require options which can't be expressed as a
+// data source name such as the IAM database authentication.
+func NewSQLProviderWithConnector(config *schema.Configuration, name, driverName string, connector driver.Connector) (provider SQLProvider) {
+	return newSQLProvider(config, name, driverName, sqlOpenConnector(config, schema.CircuitBreakerDependencyStorage, driverName, connector), nil)
+}
+
+func newSQLProvider(config *schema.Configuration, name, driverName string, db *sqlx.DB, err error) (provider SQLProvider) {
 	provider = SQLProvider{
 		db:         db,
 		name:       name,
@@ -32,21 +45,27 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 
 		keys: SQLProviderKeys{
 			encryption: sha256.Sum256([]byte(config.Storage.EncryptionKey)),
+			previous:   make([][32]byte, len(config.Storage.PreviousEncryptionKeys)),
 		},
 
-		log: logging.Logger(),
+		log: logging.LoggerModule(logging.ModuleStorage),
 
 		sqlInsertAuthenticationAttempt:            fmt.Sprintf(queryFmtInsertAuthenticationLogEntry, tableAuthenticationLogs),
 		sqlSelectAuthenticationAttemptsByUsername: fmt.Sprintf(queryFmtSelect1FAAuthenticationLogEntryByUsername, tableAuthenticationLogs),
+		sqlSelectLatestSignInByUsername:           fmt.Sprintf(queryFmtSelectLatestSuccessful1FAAuthenticationLogEntryTimeByUsername, tableAuthenticationLogs),
+		sqlSelectAuthenticationHistoryByUsername:  newSQLSortedQuery(queryFmtSelectAuthenticationLogEntryByUsername, tableAuthenticationLogs),
 
-		sqlInsertIdentityVerification:  fmt.Sprintf(queryFmtInsertIdentityVerification, tableIdentityVerification),
-		sqlConsumeIdentityVerification: fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
-		sqlRevokeIdentityVerification:  fmt.Sprintf(queryFmtRevokeIdentityVerification, tableIdentityVerification),
-		sqlSelectIdentityVerification:  fmt.Sprintf(queryFmtSelectIdentityVerification, tableIdentityVerification),
+		sqlInsertIdentityVerification:         fmt.Sprintf(queryFmtInsertIdentityVerification, tableIdentityVerification),
+		sqlConsumeIdentityVerification:        fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
+		sqlRevokeIdentityVerification:         fmt.Sprintf(queryFmtRevokeIdentityVerification, tableIdentityVerification),
+		sqlDeleteExpiredIdentityVerifications: fmt.Sprintf(queryFmtDeleteExpiredIdentityVerifications, tableIdentityVerification),
+		sqlSelectIdentityVerification:         fmt.Sprintf(queryFmtSelectIdentityVerification, tableIdentityVerification),
+		sqlSelectCountIdentityVerifications:   fmt.Sprintf(queryFmtSelectCountIdentityVerifications, tableIdentityVerification),
 
 		sqlInsertOneTimeCode:            fmt.Sprintf(queryFmtInsertOTC, tableOneTimeCode),
 		sqlConsumeOneTimeCode:           fmt.Sprintf(queryFmtConsumeOTC, tableOneTimeCode),
 		sqlRevokeOneTimeCode:            fmt.Sprintf(queryFmtRevokeOTC, tableOneTimeCode),
+		sqlSelectCountOneTimeCodes:      fmt.Sprintf(queryFmtSelectCountOTC, tableOneTimeCode),
 		sqlSelectOneTimeCode:            fmt.Sprintf(queryFmtSelectOTCBySignatureAndUsername, tableOneTimeCode),
 		sqlSelectOneTimeCodeBySignature: fmt.Sprintf(queryFmtSelectOTCBySignature, tableOneTimeCode),
 		sqlSelectOneTimeCodeByID:        fmt.Sprintf(queryFmtSelectOTCByID, tableOneTimeCode),
@@ -58,13 +77,15 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlSelectTOTPConfigs: fmt.Sprintf(queryFmtSelectTOTPConfigurations, tableTOTPConfigurations),
 
 		sqlUpdateTOTPConfigRecordSignIn:           fmt.Sprintf(queryFmtUpdateTOTPConfigRecordSignIn, tableTOTPConfigurations),
+		sqlUpdateTOTPConfigDrift:                  fmt.Sprintf(queryFmtUpdateTOTPConfigDrift, tableTOTPConfigurations),
 		sqlUpdateTOTPConfigRecordSignInByUsername: fmt.Sprintf(queryFmtUpdateTOTPConfigRecordSignInByUsername, tableTOTPConfigurations),
 
 		sqlInsertTOTPHistory: fmt.Sprintf(queryFmtInsertTOTPHistory, tableTOTPHistory),
 		sqlSelectTOTPHistory: fmt.Sprintf(queryFmtSelectTOTPHistory, tableTOTPHistory),
 
-		sqlInsertWebAuthnUser: fmt.Sprintf(queryFmtInsertWebAuthnUser, tableWebAuthnUsers),
-		sqlSelectWebAuthnUser: fmt.Sprintf(queryFmtSelectWebAuthnUser, tableWebAuthnUsers),
+		sqlInsertWebAuthnUser:         fmt.Sprintf(queryFmtInsertWebAuthnUser, tableWebAuthnUsers),
+		sqlSelectWebAuthnUser:         fmt.Sprintf(queryFmtSelectWebAuthnUser, tableWebAuthnUsers),
+		sqlSelectWebAuthnUserByUserID: fmt.Sprintf(queryFmtSelectWebAuthnUserByUserID, tableWebAuthnUsers),
 
 		sqlInsertWebAuthnCredential:                           fmt.Sprintf(queryFmtInsertWebAuthnCredential, tableWebAuthnCredentials),
 		sqlSelectWebAuthnCredentials:                          fmt.Sprintf(queryFmtSelectWebAuthnCredentials, tableWebAuthnCredentials),
@@ -81,23 +102,127 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlDeleteDuoDevice: fmt.Sprintf(queryFmtDeleteDuoDevice, tableDuoDevices),
 		sqlSelectDuoDevice: fmt.Sprintf(queryFmtSelectDuoDevice, tableDuoDevices),
 
+		sqlInsertDeviceCertificate:            fmt.Sprintf(queryFmtInsertDeviceCertificate, tableDeviceCertificate),
+		sqlSelectDeviceCertificate:            fmt.Sprintf(queryFmtSelectDeviceCertificate, tableDeviceCertificate),
+		sqlSelectDeviceCertificatesByUsername: newSQLSortedQuery(queryFmtSelectDeviceCertificatesByUsername, tableDeviceCertificate),
+		sqlRevokeDeviceCertificate:            fmt.Sprintf(queryFmtRevokeDeviceCertificate, tableDeviceCertificate),
+		sqlDeleteExpiredDeviceCertificates:    fmt.Sprintf(queryFmtDeleteExpiredDeviceCertificates, tableDeviceCertificate),
+
+		sqlInsertUserSessionIndex:           fmt.Sprintf(queryFmtInsertUserSessionIndex, tableUserSessionIndex),
+		sqlSelectUserSessionIndex:           fmt.Sprintf(queryFmtSelectUserSessionIndex, tableUserSessionIndex),
+		sqlSelectUserSessionIndexByUsername: fmt.Sprintf(queryFmtSelectUserSessionIndexByUsername, tableUserSessionIndex),
+		sqlDeleteUserSessionIndex:           fmt.Sprintf(queryFmtDeleteUserSessionIndex, tableUserSessionIndex),
+
+		sqlInsertUserSessionRevocation:         fmt.Sprintf(queryFmtInsertUserSessionRevocation, tableUserSessionRevocation),
+		sqlSelectUserSessionRevocations:        fmt.Sprintf(queryFmtSelectUserSessionRevocations, tableUserSessionRevocation),
+		sqlDeleteExpiredUserSessionRevocations: fmt.Sprintf(queryFmtDeleteExpiredUserSessionRevocations, tableUserSessionRevocation),
+
+		sqlInsertAppPassword:            fmt.Sprintf(queryFmtInsertAppPassword, tableAppPassword),
+		sqlSelectAppPassword:            fmt.Sprintf(queryFmtSelectAppPassword, tableAppPassword),
+		sqlSelectAppPasswordsByUsername: fmt.Sprintf(queryFmtSelectAppPasswordsByUsername, tableAppPassword),
+		sqlUpdateAppPasswordSignIn:      fmt.Sprintf(queryFmtUpdateAppPasswordRecordSignIn, tableAppPassword),
+		sqlDeleteAppPassword:            fmt.Sprintf(queryFmtDeleteAppPassword, tableAppPassword),
+
+		sqlSelectSession:          fmt.Sprintf(queryFmtSelectSession, tableSessions),
+		sqlUpsertSession:          fmt.Sprintf(queryFmtUpsertSession, tableSessions),
+		sqlUpdateSessionSignature: fmt.Sprintf(queryFmtUpdateSessionSignature, tableSessions),
+		sqlDeleteSession:          fmt.Sprintf(queryFmtDeleteSession, tableSessions),
+		sqlSelectCountSessions:    fmt.Sprintf(queryFmtSelectCountSessions, tableSessions),
+		sqlDeleteExpiredSessions:  fmt.Sprintf(queryFmtDeleteExpiredSessions, tableSessions),
+
+		sqlInsertDirectoryUser:                fmt.Sprintf(queryFmtInsertDirectoryUser, tableDirectoryUsers),
+		sqlUpdateDirectoryUser:                fmt.Sprintf(queryFmtUpdateDirectoryUser, tableDirectoryUsers),
+		sqlSelectDirectoryUser:                fmt.Sprintf(queryFmtSelectDirectoryUser, tableDirectoryUsers),
+		sqlSelectDirectoryUsers:               fmt.Sprintf(queryFmtSelectDirectoryUsers, tableDirectoryUsers),
+		sqlSelectDirectoryUsersCount:          fmt.Sprintf(queryFmtSelectDirectoryUsersCount, tableDirectoryUsers),
+		sqlSelectDirectoryUserPassword:        fmt.Sprintf(queryFmtSelectDirectoryUserPassword, tableDirectoryUsers),
+		sqlUpdateDirectoryUserPassword:        fmt.Sprintf(queryFmtUpdateDirectoryUserPassword, tableDirectoryUsers),
+		sqlDeleteDirectoryUser:                fmt.Sprintf(queryFmtDeleteDirectoryUser, tableDirectoryUsers),
+		sqlSelectDirectoryUserGroups:          fmt.Sprintf(queryFmtSelectDirectoryUserGroups, tableDirectoryGroups, tableDirectoryMembers, tableDirectoryUsers),
+		sqlInsertDirectoryGroup:               fmt.Sprintf(queryFmtInsertDirectoryGroup, tableDirectoryGroups),
+		sqlUpdateDirectoryGroup:               fmt.Sprintf(queryFmtUpdateDirectoryGroup, tableDirectoryGroups),
+		sqlSelectDirectoryGroup:               fmt.Sprintf(queryFmtSelectDirectoryGroup, tableDirectoryGroups),
+		sqlSelectDirectoryGroups:              fmt.Sprintf(queryFmtSelectDirectoryGroups, tableDirectoryGroups),
+		sqlSelectDirectoryGroupsCount:         fmt.Sprintf(queryFmtSelectDirectoryGroupsCount, tableDirectoryGroups),
+		sqlDeleteDirectoryGroup:               fmt.Sprintf(queryFmtDeleteDirectoryGroup, tableDirectoryGroups),
+		sqlInsertDirectoryGroupMember:         fmt.Sprintf(queryFmtInsertDirectoryGroupMember, tableDirectoryMembers, tableDirectoryGroups, tableDirectoryUsers),
+		sqlSelectDirectoryGroupMembers:        fmt.Sprintf(queryFmtSelectDirectoryGroupMembers, tableDirectoryUsers, tableDirectoryMembers, tableDirectoryGroups),
+		sqlDeleteDirectoryGroupMembersByGroup: fmt.Sprintf(queryFmtDeleteDirectoryGroupMembersByGroup, tableDirectoryMembers, tableDirectoryGroups),
+		sqlDeleteDirectoryGroupMembersByUser:  fmt.Sprintf(queryFmtDeleteDirectoryGroupMembersByUser, tableDirectoryMembers, tableDirectoryUsers),
+
+		sqlInsertRecoveryCode:       fmt.Sprintf(queryFmtInsertRecoveryCode, tableRecoveryCode),
+		sqlConsumeRecoveryCode:      fmt.Sprintf(queryFmtConsumeRecoveryCode, tableRecoveryCode),
+		sqlSelectRecoveryCodesCount: fmt.Sprintf(queryFmtSelectRecoveryCodesCount, tableRecoveryCode),
+		sqlDeleteRecoveryCodes:      fmt.Sprintf(queryFmtDeleteRecoveryCodes, tableRecoveryCode),
+
+		sqlUpsertPhoneNumber:             fmt.Sprintf(queryFmtUpsertPhoneNumber, tablePhoneNumber),
+		sqlSelectPhoneNumber:             fmt.Sprintf(queryFmtSelectPhoneNumber, tablePhoneNumber),
+		sqlUpdatePhoneNumberRecordSignIn: fmt.Sprintf(queryFmtUpdatePhoneNumberRecordSignIn, tablePhoneNumber),
+		sqlDeletePhoneNumber:             fmt.Sprintf(queryFmtDeletePhoneNumber, tablePhoneNumber),
+
+		sqlInsertPushChallenge:         fmt.Sprintf(queryFmtInsertPushChallenge, tablePushChallenge),
+		sqlSelectPushChallenge:         fmt.Sprintf(queryFmtSelectPushChallenge, tablePushChallenge),
+		sqlUpdatePushChallengeResponse: fmt.Sprintf(queryFmtUpdatePushChallengeResponse, tablePushChallenge),
+		sqlDeleteExpiredPushChallenges: fmt.Sprintf(queryFmtDeleteExpiredPushChallenges, tablePushChallenge),
+
+		sqlInsertMobileDevice:                  fmt.Sprintf(queryFmtInsertMobileDevice, tableMobileDevice),
+		sqlSelectMobileDevice:                  fmt.Sprintf(queryFmtSelectMobileDevice, tableMobileDevice),
+		sqlSelectMobileDeviceByAccessSignature: fmt.Sprintf(queryFmtSelectMobileDeviceByAccessSignature, tableMobileDevice),
+		sqlUpdateMobileDeviceTokens:            fmt.Sprintf(queryFmtUpdateMobileDeviceTokens, tableMobileDevice),
+		sqlSelectMobileDevicesPush:             fmt.Sprintf(queryFmtSelectMobileDevicesPush, tableMobileDevice),
+		sqlUpdateMobileDevicePush:              fmt.Sprintf(queryFmtUpdateMobileDevicePush, tableMobileDevice),
+		sqlRevokeMobileDevice:                  fmt.Sprintf(queryFmtRevokeMobileDevice, tableMobileDevice),
+		sqlDeleteExpiredMobileDevices:          fmt.Sprintf(queryFmtDeleteExpiredMobileDevices, tableMobileDevice),
+
+		sqlInsertSAMLSession:            fmt.Sprintf(queryFmtInsertSAMLSession, tableSAMLSession),
+		sqlSelectSAMLSession:            fmt.Sprintf(queryFmtSelectSAMLSession, tableSAMLSession),
+		sqlRevokeSAMLSession:            fmt.Sprintf(queryFmtRevokeSAMLSession, tableSAMLSession),
+		sqlRevokeSAMLSessionsByUsername: fmt.Sprintf(queryFmtRevokeSAMLSessionsByUsername, tableSAMLSession),
+		sqlDeleteExpiredSAMLSessions:    fmt.Sprintf(queryFmtDeleteExpiredSAMLSessions, tableSAMLSession),
+
 		sqlUpsertPreferred2FAMethod: fmt.Sprintf(queryFmtUpsertPreferred2FAMethod, tableUserPreferences),
 		sqlSelectPreferred2FAMethod: fmt.Sprintf(queryFmtSelectPreferred2FAMethod, tableUserPreferences),
-		sqlSelectUserInfo:           fmt.Sprintf(queryFmtSelectUserInfo, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableUserPreferences),
+		sqlSelectUserInfo:           fmt.Sprintf(queryFmtSelectUserInfo, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tablePhoneNumber, tableUserPreferences),
+		sqlSelectUserSummaries:      fmt.Sprintf(queryFmtSelectUserSummaries, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableUserPreferences, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableAuthenticationLogs, tableUserPreferences),
+		sqlSelectUsageStatistics:    fmt.Sprintf(queryFmtSelectUsageStatistics, tableUserPreferences, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableAuthenticationLogs, tableAuthenticationLogs, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableTOTPConfigurations, tableWebAuthnCredentials, tableDuoDevices, tableOAuth2ConsentSession),
 
 		sqlInsertUserOpaqueIdentifier:            fmt.Sprintf(queryFmtInsertUserOpaqueIdentifier, tableUserOpaqueIdentifier),
 		sqlSelectUserOpaqueIdentifier:            fmt.Sprintf(queryFmtSelectUserOpaqueIdentifier, tableUserOpaqueIdentifier),
 		sqlSelectUserOpaqueIdentifiers:           fmt.Sprintf(queryFmtSelectUserOpaqueIdentifiers, tableUserOpaqueIdentifier),
 		sqlSelectUserOpaqueIdentifierBySignature: fmt.Sprintf(queryFmtSelectUserOpaqueIdentifierBySignature, tableUserOpaqueIdentifier),
 
-		sqlUpsertOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
-		sqlSelectOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtSelectOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
+		sqlUpsertOAuth2BlacklistedJTI:         fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
+		sqlDeleteExpiredOAuth2BlacklistedJTIs: fmt.Sprintf(queryFmtDeleteExpiredOAuth2BlacklistedJTIs, tableOAuth2BlacklistedJTI),
+		sqlSelectOAuth2BlacklistedJTI:         fmt.Sprintf(queryFmtSelectOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
 
 		sqlInsertOAuth2PARContext: fmt.Sprintf(queryFmtInsertOAuth2PARContext, tableOAuth2PARContext),
 		sqlUpdateOAuth2PARContext: fmt.Sprintf(queryFmtUpdateOAuth2PARContext, tableOAuth2PARContext),
 		sqlSelectOAuth2PARContext: fmt.Sprintf(queryFmtSelectOAuth2PARContext, tableOAuth2PARContext),
 		sqlRevokeOAuth2PARContext: fmt.Sprintf(queryFmtRevokeOAuth2Session, tableOAuth2PARContext),
 
+		sqlInsertOAuth2DeviceCodeSession:                    fmt.Sprintf(queryFmtInsertOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+		sqlUpdateOAuth2DeviceCodeSession:                    fmt.Sprintf(queryFmtUpdateOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+		sqlSelectOAuth2DeviceCodeSession:                    fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+		sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature: fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSessionByUserCodeSignature, tableOAuth2DeviceCodeSession),
+		sqlDeactivateOAuth2DeviceCodeSession:                fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2DeviceCodeSession),
+
+		sqlInsertOAuth2CIBARequest:          fmt.Sprintf(queryFmtInsertOAuth2CIBARequest, tableOAuth2CIBARequest),
+		sqlUpdateOAuth2CIBARequestStatus:    fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestStatus, tableOAuth2CIBARequest),
+		sqlUpdateOAuth2CIBARequestCheckedAt: fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestCheckedAt, tableOAuth2CIBARequest),
+		sqlUpdateOAuth2CIBARequestNotified:  fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestNotified, tableOAuth2CIBARequest),
+		sqlSelectOAuth2CIBARequest:          fmt.Sprintf(queryFmtSelectOAuth2CIBARequest, tableOAuth2CIBARequest),
+		sqlSelectOAuth2CIBARequestsPending:  fmt.Sprintf(queryFmtSelectOAuth2CIBARequestsPending, tableOAuth2CIBARequest),
+		sqlDeactivateOAuth2CIBARequest:      fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2CIBARequest),
+		sqlDeleteExpiredOAuth2CIBARequests:  fmt.Sprintf(queryFmtDeleteExpiredOAuth2CIBARequests, tableOAuth2CIBARequest),
+
+		sqlInsertOAuth2TokenExchangeLineage:                  fmt.Sprintf(queryFmtInsertOAuth2TokenExchangeLineage, tableOAuth2TokenExchangeLineage),
+		sqlSelectOAuth2TokenExchangeLineageByParentRequestID: fmt.Sprintf(queryFmtSelectOAuth2TokenExchangeLineageByParentRequestID, tableOAuth2TokenExchangeLineage),
+
+		sqlInsertOAuth2DynamicClient: fmt.Sprintf(queryFmtInsertOAuth2DynamicClient, tableOAuth2DynamicClient),
+		sqlUpdateOAuth2DynamicClient: fmt.Sprintf(queryFmtUpdateOAuth2DynamicClient, tableOAuth2DynamicClient),
+		sqlSelectOAuth2DynamicClient: fmt.Sprintf(queryFmtSelectOAuth2DynamicClient, tableOAuth2DynamicClient),
+		sqlDeleteOAuth2DynamicClient: fmt.Sprintf(queryFmtDeleteOAuth2DynamicClient, tableOAuth2DynamicClient),
+
 		sqlInsertOAuth2ConsentPreConfiguration:  fmt.Sprintf(queryFmtInsertOAuth2ConsentPreConfiguration, tableOAuth2ConsentPreConfiguration),
 		sqlSelectOAuth2ConsentPreConfigurations: fmt.Sprintf(queryFmtSelectOAuth2ConsentPreConfigurations, tableOAuth2ConsentPreConfiguration),
 
@@ -105,7 +230,9 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlUpdateOAuth2ConsentSessionSubject:       fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionSubject, tableOAuth2ConsentSession),
 		sqlUpdateOAuth2ConsentSessionResponse:      fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionResponse, tableOAuth2ConsentSession),
 		sqlUpdateOAuth2ConsentSessionGranted:       fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionGranted, tableOAuth2ConsentSession),
+		sqlDeleteAbandonedOAuth2ConsentSessions:    fmt.Sprintf(queryFmtDeleteAbandonedOAuth2ConsentSessions, tableOAuth2ConsentSession),
 		sqlSelectOAuth2ConsentSessionByChallengeID: fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionByChallengeID, tableOAuth2ConsentSession),
+		sqlSelectOAuth2ConsentSessionsByUsername:   newSQLSortedQuery(queryFmtSelectOAuth2ConsentSessionsByUsername, tableOAuth2ConsentSession, tableUserOpaqueIdentifier),
 
 		sqlInsertOAuth2AccessTokenSession:                fmt.Sprintf(queryFmtInsertOAuth2Session, tableOAuth2AccessTokenSession),
 		sqlSelectOAuth2AccessTokenSession:                fmt.Sprintf(queryFmtSelectOAuth2Session, tableOAuth2AccessTokenSession),
@@ -142,9 +269,24 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlDeactivateOAuth2RefreshTokenSession:            fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2RefreshTokenSession),
 		sqlDeactivateOAuth2RefreshTokenSessionByRequestID: fmt.Sprintf(queryFmtDeactivateOAuth2SessionByRequestID, tableOAuth2RefreshTokenSession),
 
-		sqlInsertMigration:       fmt.Sprintf(queryFmtInsertMigration, tableMigrations),
-		sqlSelectMigrations:      fmt.Sprintf(queryFmtSelectMigrations, tableMigrations),
-		sqlSelectLatestMigration: fmt.Sprintf(queryFmtSelectLatestMigration, tableMigrations),
+		sqlUpsertConfigurationFingerprint:  fmt.Sprintf(queryFmtUpsertConfigurationFingerprint, tableConfigurationFingerprints),
+		sqlSelectConfigurationFingerprints: fmt.Sprintf(queryFmtSelectConfigurationFingerprints, tableConfigurationFingerprints),
+		sqlDeleteConfigurationFingerprints: fmt.Sprintf(queryFmtDeleteConfigurationFingerprints, tableConfigurationFingerprints),
+
+		sqlInsertAuditEvent:  fmt.Sprintf(queryFmtInsertAuditEvent, tableAuditEvents),
+		sqlSelectAuditEvents: newSQLSortedQuery(queryFmtSelectAuditEvents, tableAuditEvents),
+		sqlDeleteAuditEvents: fmt.Sprintf(queryFmtDeleteAuditEvents, tableAuditEvents),
+
+		sqlUpdateLeaderElectionLease: fmt.Sprintf(queryFmtUpdateLeaderElectionLease, tableLeaderElectionLeases),
+		sqlSelectLeaderElectionLease: fmt.Sprintf(queryFmtSelectLeaderElectionLease, tableLeaderElectionLeases),
+		sqlInsertLeaderElectionLease: fmt.Sprintf(queryFmtInsertLeaderElectionLease, tableLeaderElectionLeases),
+		sqlDeleteLeaderElectionLease: fmt.Sprintf(queryFmtDeleteLeaderElectionLease, tableLeaderElectionLeases),
+
+		sqlInsertMigration:                 fmt.Sprintf(queryFmtInsertMigration, tableMigrations),
+		sqlInsertMigrationWithCompatible:   fmt.Sprintf(queryFmtInsertMigrationWithCompatible, tableMigrations),
+		sqlSelectMigrations:                fmt.Sprintf(queryFmtSelectMigrations, tableMigrations),
+		sqlSelectLatestMigration:           fmt.Sprintf(queryFmtSelectLatestMigration, tableMigrations),
+		sqlSelectLatestMigrationCompatible: fmt.Sprintf(queryFmtSelectLatestMigrationCompatible, tableMigrations),
 
 		sqlUpsertEncryptionValue: fmt.Sprintf(queryFmtUpsertEncryptionValue, tableEncryption),
 		sqlSelectEncryptionValue: fmt.Sprintf(queryFmtSelectEncryptionValue, tableEncryption),
@@ -152,6 +294,10 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlFmtRenameTable: queryFmtRenameTable,
 	}
 
+	for i, key := range config.Storage.PreviousEncryptionKeys {
+		provider.keys.previous[i] = sha256.Sum256([]byte(key))
+	}
+
 	return provider
 }
 
@@ -164,6 +310,10 @@ type SQLProvider struct {
 	schema     string
 	config     *schema.Configuration
 	errOpen    error
+	retries    int
+
+	replicas    []*sqlReplica
+	replicaNext uint64
 
 	keys SQLProviderKeys
 
@@ -172,17 +322,22 @@ type SQLProvider struct {
 	// Table: authentication_logs.
 	sqlInsertAuthenticationAttempt            string
 	sqlSelectAuthenticationAttemptsByUsername string
+	sqlSelectLatestSignInByUsername           string
+	sqlSelectAuthenticationHistoryByUsername  sqlSortedQuery
 
 	// Table: identity_verification.
-	sqlInsertIdentityVerification  string
-	sqlConsumeIdentityVerification string
-	sqlRevokeIdentityVerification  string
-	sqlSelectIdentityVerification  string
+	sqlInsertIdentityVerification         string
+	sqlConsumeIdentityVerification        string
+	sqlRevokeIdentityVerification         string
+	sqlDeleteExpiredIdentityVerifications string
+	sqlSelectIdentityVerification         string
+	sqlSelectCountIdentityVerifications   string
 
 	// Table: one_time_code.
 	sqlInsertOneTimeCode            string
 	sqlConsumeOneTimeCode           string
 	sqlRevokeOneTimeCode            string
+	sqlSelectCountOneTimeCodes      string
 	sqlSelectOneTimeCode            string
 	sqlSelectOneTimeCodeBySignature string
 	sqlSelectOneTimeCodeByID        string
@@ -195,6 +350,7 @@ type SQLProvider struct {
 	sqlSelectTOTPConfigs string
 
 	sqlUpdateTOTPConfigRecordSignIn           string
+	sqlUpdateTOTPConfigDrift                  string
 	sqlUpdateTOTPConfigRecordSignInByUsername string
 
 	// Table: totp_history.
@@ -202,8 +358,9 @@ type SQLProvider struct {
 	sqlSelectTOTPHistory string
 
 	// Table: webauthn_users.
-	sqlInsertWebAuthnUser string
-	sqlSelectWebAuthnUser string
+	sqlInsertWebAuthnUser         string
+	sqlSelectWebAuthnUser         string
+	sqlSelectWebAuthnUserByUserID string
 
 	// Table: webauthn_credentials.
 	sqlInsertWebAuthnCredential                  string
@@ -224,10 +381,101 @@ type SQLProvider struct {
 	sqlDeleteDuoDevice string
 	sqlSelectDuoDevice string
 
+	// Table: device_certificate.
+	sqlInsertDeviceCertificate            string
+	sqlSelectDeviceCertificate            string
+	sqlSelectDeviceCertificatesByUsername sqlSortedQuery
+	sqlRevokeDeviceCertificate            string
+	sqlDeleteExpiredDeviceCertificates    string
+
+	// Table: user_session_index.
+	sqlInsertUserSessionIndex           string
+	sqlSelectUserSessionIndex           string
+	sqlSelectUserSessionIndexByUsername string
+	sqlDeleteUserSessionIndex           string
+
+	// Table: user_session_revocation.
+	sqlInsertUserSessionRevocation         string
+	sqlSelectUserSessionRevocations        string
+	sqlDeleteExpiredUserSessionRevocations string
+
+	// Table: app_password.
+	sqlInsertAppPassword            string
+	sqlSelectAppPassword            string
+	sqlSelectAppPasswordsByUsername string
+	sqlUpdateAppPasswordSignIn      string
+	sqlDeleteAppPassword            string
+
+	// Table: sessions.
+	sqlSelectSession          string
+	sqlUpsertSession          string
+	sqlUpdateSessionSignature string
+	sqlDeleteSession          string
+	sqlSelectCountSessions    string
+	sqlDeleteExpiredSessions  string
+
+	// Tables: directory_users, directory_groups, directory_group_members.
+	sqlInsertDirectoryUser                string
+	sqlUpdateDirectoryUser                string
+	sqlSelectDirectoryUser                string
+	sqlSelectDirectoryUsers               string
+	sqlSelectDirectoryUsersCount          string
+	sqlSelectDirectoryUserPassword        string
+	sqlUpdateDirectoryUserPassword        string
+	sqlDeleteDirectoryUser                string
+	sqlSelectDirectoryUserGroups          string
+	sqlInsertDirectoryGroup               string
+	sqlUpdateDirectoryGroup               string
+	sqlSelectDirectoryGroup               string
+	sqlSelectDirectoryGroups              string
+	sqlSelectDirectoryGroupsCount         string
+	sqlDeleteDirectoryGroup               string
+	sqlInsertDirectoryGroupMember         string
+	sqlSelectDirectoryGroupMembers        string
+	sqlDeleteDirectoryGroupMembersByGroup string
+	sqlDeleteDirectoryGroupMembersByUser  string
+
+	// Table: recovery_code.
+	sqlInsertRecoveryCode       string
+	sqlConsumeRecoveryCode      string
+	sqlSelectRecoveryCodesCount string
+	sqlDeleteRecoveryCodes      string
+
+	// Table: phone_number.
+	sqlUpsertPhoneNumber             string
+	sqlSelectPhoneNumber             string
+	sqlUpdatePhoneNumberRecordSignIn string
+	sqlDeletePhoneNumber             string
+
+	// Table: push_challenge.
+	sqlInsertPushChallenge         string
+	sqlSelectPushChallenge         string
+	sqlUpdatePushChallengeResponse string
+	sqlDeleteExpiredPushChallenges string
+
+	// Table: mobile_device.
+	sqlInsertMobileDevice                  string
+	sqlSelectMobileDevice                  string
+	sqlSelectMobileDeviceByAccessSignature string
+	sqlUpdateMobileDeviceTokens            string
+	sqlSelectMobileDevicesPush             string
+	sqlUpdateMobileDevicePush              string
+	sqlRevokeMobileDevice                  string
+	sqlDeleteExpiredMobileDevices          string
+
+	// Table: saml_session.
+	sqlInsertSAMLSession            string
+	sqlSelectSAMLSession            string
+	sqlRevokeSAMLSession            string
+	sqlRevokeSAMLSessionsByUsername string
+	sqlDeleteExpiredSAMLSessions    string
+
 	// Table: user_preferences.
 	sqlUpsertPreferred2FAMethod string
 	sqlSelectPreferred2FAMethod string
 	sqlSelectUserInfo           string
+	sqlSelectUserSummaries      string
+	sqlSelectUsageStatistics    string
 
 	// Table: user_opaque_identifier.
 	sqlInsertUserOpaqueIdentifier            string
@@ -235,10 +483,28 @@ type SQLProvider struct {
 	sqlSelectUserOpaqueIdentifiers           string
 	sqlSelectUserOpaqueIdentifierBySignature string
 
+	// Table: configuration_fingerprints.
+	sqlUpsertConfigurationFingerprint  string
+	sqlSelectConfigurationFingerprints string
+	sqlDeleteConfigurationFingerprints string
+
+	// Table: audit_events.
+	sqlInsertAuditEvent  string
+	sqlSelectAuditEvents sqlSortedQuery
+	sqlDeleteAuditEvents string
+
+	// Table: leader_election_leases.
+	sqlUpdateLeaderElectionLease string
+	sqlSelectLeaderElectionLease string
+	sqlInsertLeaderElectionLease string
+	sqlDeleteLeaderElectionLease string
+
 	// Table: migrations.
-	sqlInsertMigration       string
-	sqlSelectMigrations      string
-	sqlSelectLatestMigration string
+	sqlInsertMigration                 string
+	sqlInsertMigrationWithCompatible   string
+	sqlSelectMigrations                string
+	sqlSelectLatestMigration           string
+	sqlSelectLatestMigrationCompatible string
 
 	// Table: encryption.
 	sqlUpsertEncryptionValue string
@@ -253,7 +519,9 @@ type SQLProvider struct {
 	sqlUpdateOAuth2ConsentSessionSubject       string
 	sqlUpdateOAuth2ConsentSessionResponse      string
 	sqlUpdateOAuth2ConsentSessionGranted       string
+	sqlDeleteAbandonedOAuth2ConsentSessions    string
 	sqlSelectOAuth2ConsentSessionByChallengeID string
+	sqlSelectOAuth2ConsentSessionsByUsername   sqlSortedQuery
 
 	// Table: oauth2_authorization_code_session.
 	sqlInsertOAuth2AuthorizeCodeSession                string
@@ -285,6 +553,33 @@ type SQLProvider struct {
 	sqlSelectOAuth2PARContext string
 	sqlRevokeOAuth2PARContext string
 
+	// Table: oauth2_device_code_session.
+	sqlInsertOAuth2DeviceCodeSession                    string
+	sqlUpdateOAuth2DeviceCodeSession                    string
+	sqlSelectOAuth2DeviceCodeSession                    string
+	sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature string
+	sqlDeactivateOAuth2DeviceCodeSession                string
+
+	// Table: oauth2_ciba_request.
+	sqlInsertOAuth2CIBARequest          string
+	sqlUpdateOAuth2CIBARequestStatus    string
+	sqlUpdateOAuth2CIBARequestCheckedAt string
+	sqlUpdateOAuth2CIBARequestNotified  string
+	sqlSelectOAuth2CIBARequest          string
+	sqlSelectOAuth2CIBARequestsPending  string
+	sqlDeactivateOAuth2CIBARequest      string
+	sqlDeleteExpiredOAuth2CIBARequests  string
+
+	// Table: oauth2_token_exchange_lineage.
+	sqlInsertOAuth2TokenExchangeLineage                  string
+	sqlSelectOAuth2TokenExchangeLineageByParentRequestID string
+
+	// Table: oauth2_dynamic_client.
+	sqlInsertOAuth2DynamicClient string
+	sqlUpdateOAuth2DynamicClient string
+	sqlSelectOAuth2DynamicClient string
+	sqlDeleteOAuth2DynamicClient string
+
 	// Table: oauth2_pkce_request_session.
 	sqlInsertOAuth2PKCERequestSession                string
 	sqlSelectOAuth2PKCERequestSession                string
@@ -301,8 +596,9 @@ type SQLProvider struct {
 	sqlDeactivateOAuth2RefreshTokenSession            string
 	sqlDeactivateOAuth2RefreshTokenSessionByRequestID string
 
-	sqlUpsertOAuth2BlacklistedJTI string
-	sqlSelectOAuth2BlacklistedJTI string
+	sqlUpsertOAuth2BlacklistedJTI         string
+	sqlDeleteExpiredOAuth2BlacklistedJTIs string
+	sqlSelectOAuth2BlacklistedJTI         string
 
 	// Utility.
 	sqlSelectExistingTables string
@@ -312,10 +608,24 @@ type SQLProvider struct {
 // SQLProviderKeys are the cryptography keys used by a SQLProvider.
 type SQLProviderKeys struct {
 	encryption [32]byte
+	previous   [][32]byte
 	otcHMAC    []byte
 	otpHMAC    []byte
 }
 
+// HealthCheck implements the provider health check interface.
+func (p *SQLProvider) HealthCheck(ctx context.Context) (err error) {
+	if p.errOpen != nil {
+		return fmt.Errorf("error opening database: %w", p.errOpen)
+	}
+
+	if err = p.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("error pinging database: %w", err)
+	}
+
+	return nil
+}
+
 // StartupCheck implements the provider startup check interface.
 func (p *SQLProvider) StartupCheck() (err error) {
 	if p.errOpen != nil {
@@ -335,10 +645,14 @@ func (p *SQLProvider) StartupCheck() (err error) {
 		return fmt.Errorf("error pinging database: %w", err)
 	}
 
-	p.log.Infof("Storage schema is being checked for updates")
-
 	ctx := context.Background()
 
+	if err = p.replicaCheck(ctx); err != nil {
+		return err
+	}
+
+	p.log.Infof("Storage schema is being checked for updates")
+
 	var result EncryptionValidationResult
 
 	if result, err = p.SchemaEncryptionCheckKey(ctx, false); err != nil && !errors.Is(err, ErrSchemaEncryptionVersionUnsupported) {
@@ -349,13 +663,21 @@ func (p *SQLProvider) StartupCheck() (err error) {
 		return ErrSchemaEncryptionInvalidKey
 	}
 
-	switch err = p.SchemaMigrate(ctx, true, SchemaLatest); err {
-	case nil:
-		break
-	case ErrSchemaAlreadyUpToDate:
-		p.log.Infof("Storage schema is already up to date")
-	default:
-		return fmt.Errorf("error during schema migrate: %w", err)
+	var compatible bool
+
+	if compatible, err = p.schemaCompatibilityCheck(ctx); err != nil {
+		return fmt.Errorf("error during schema compatibility check: %w", err)
+	}
+
+	if !compatible {
+		switch err = p.SchemaMigrate(ctx, true, SchemaLatest); err {
+		case nil:
+			break
+		case ErrSchemaAlreadyUpToDate:
+			p.log.Infof("Storage schema is already up to date")
+		default:
+			return fmt.Errorf("error during schema migrate: %w", err)
+		}
 	}
 
 	if p.keys.otcHMAC, err = p.getHMACOneTimeCode(ctx); err != nil {
@@ -404,6 +726,10 @@ func (p *SQLProvider) Rollback(ctx context.Context) (err error) {
 
 // Close the underlying storage provider.
 func (p *SQLProvider) Close() (err error) {
+	if err = p.replicaClose(); err != nil {
+		return err
+	}
+
 	return p.db.Close()
 }
 
@@ -418,7 +744,7 @@ func (p *SQLProvider) SavePreferred2FAMethod(ctx context.Context, username strin
 
 // LoadPreferred2FAMethod load the preferred method for 2FA for a username from the storage provider.
 func (p *SQLProvider) LoadPreferred2FAMethod(ctx context.Context, username string) (method string, err error) {
-	err = p.db.GetContext(ctx, &method, p.sqlSelectPreferred2FAMethod, username)
+	err = p.readGetContext(ctx, &method, p.sqlSelectPreferred2FAMethod, username)
 
 	switch {
 	case err == nil:
@@ -432,7 +758,7 @@ func (p *SQLProvider) LoadPreferred2FAMethod(ctx context.Context, username strin
 
 // LoadUserInfo loads the model.UserInfo from the storage provider.
 func (p *SQLProvider) LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error) {
-	err = p.db.GetContext(ctx, &info, p.sqlSelectUserInfo, username, username, username, username)
+	err = p.readGetContext(ctx, &info, p.sqlSelectUserInfo, username, username, username, username, username)
 
 	switch {
 	case err == nil, errors.Is(err, sql.ErrNoRows):
@@ -442,6 +768,36 @@ func (p *SQLProvider) LoadUserInfo(ctx context.Context, username string) (info m
 	}
 }
 
+// LoadUserSummaries loads a page of model.UserSummary for every user known to the storage provider ordered by the
+// username. Users are known to the storage provider if they have any preferences, second factor methods, or have
+// successfully authenticated.
+func (p *SQLProvider) LoadUserSummaries(ctx context.Context, limit, page int) (summaries []model.UserSummary, err error) {
+	summaries = make([]model.UserSummary, 0, limit)
+
+	if err = p.readSelectContext(ctx, &summaries, p.sqlSelectUserSummaries, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting user summaries: %w", err)
+	}
+
+	for i := range summaries {
+		var latest time.Time
+
+		switch err = p.readGetContext(ctx, &latest, p.sqlSelectLatestSignInByUsername, summaries[i].Username); {
+		case err == nil:
+			summaries[i].LastSignInAt = sql.NullTime{Time: latest, Valid: true}
+		case errors.Is(err, sql.ErrNoRows):
+			continue
+		default:
+			return nil, fmt.Errorf("error selecting the latest sign in for user '%s': %w", summaries[i].Username, err)
+		}
+	}
+
+	return summaries, nil
+}
+
 // SaveUserOpaqueIdentifier saves a new opaque user identifier to the storage provider.
 func (p *SQLProvider) SaveUserOpaqueIdentifier(ctx context.Context, subject model.UserOpaqueIdentifier) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserOpaqueIdentifier, subject.Service, subject.SectorID, subject.Username, subject.Identifier); err != nil {
@@ -515,7 +871,7 @@ func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TO
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertTOTPConfig,
 		config.CreatedAt, config.LastUsedAt,
 		config.Username, config.Issuer,
-		config.Algorithm, config.Digits, config.Period, config.Secret); err != nil {
+		config.Algorithm, config.Digits, config.Period, config.Secret, config.Drift); err != nil {
 		return fmt.Errorf("error upserting TOTP configuration for user '%s': %w", config.Username, err)
 	}
 
@@ -531,6 +887,16 @@ func (p *SQLProvider) UpdateTOTPConfigurationSignIn(ctx context.Context, id int,
 	return nil
 }
 
+// UpdateTOTPConfigurationDrift updates the learned clock drift of a registered TOTP configuration in the storage
+// provider.
+func (p *SQLProvider) UpdateTOTPConfigurationDrift(ctx context.Context, id, drift int) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigDrift, drift, id); err != nil {
+		return fmt.Errorf("error updating TOTP configuration drift for id %d: %w", id, err)
+	}
+
+	return nil
+}
+
 // DeleteTOTPConfiguration delete a TOTP configuration from the storage provider given a username.
 func (p *SQLProvider) DeleteTOTPConfiguration(ctx context.Context, username string) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlDeleteTOTPConfig, username); err != nil {
@@ -587,7 +953,7 @@ func (p *SQLProvider) ExistsTOTPHistory(ctx context.Context, username string, st
 func (p *SQLProvider) LoadTOTPConfigurations(ctx context.Context, limit, page int) (configs []model.TOTPConfiguration, err error) {
 	configs = make([]model.TOTPConfiguration, 0, limit)
 
-	if err = p.db.SelectContext(ctx, &configs, p.sqlSelectTOTPConfigs, limit, limit*page); err != nil {
+	if err = p.readSelectContext(ctx, &configs, p.sqlSelectTOTPConfigs, limit, limit*page); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -629,6 +995,22 @@ func (p *SQLProvider) LoadWebAuthnUser(ctx context.Context, rpid, username strin
 	return user, nil
 }
 
+// LoadWebAuthnUserByUserID loads a registered WebAuthn user from the storage provider using the user handle.
+func (p *SQLProvider) LoadWebAuthnUserByUserID(ctx context.Context, rpid, userID string) (user *model.WebAuthnUser, err error) {
+	user = &model.WebAuthnUser{}
+
+	if err = p.db.GetContext(ctx, user, p.sqlSelectWebAuthnUserByUserID, rpid, userID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting WebAuthn user with user id '%s' and relying party id '%s': %w", userID, rpid, err)
+		}
+	}
+
+	return user, nil
+}
+
 // SaveWebAuthnCredential saves a registered WebAuthn credential to the storage provider.
 func (p *SQLProvider) SaveWebAuthnCredential(ctx context.Context, credential model.WebAuthnCredential) (err error) {
 	if credential.PublicKey, err = p.encrypt(credential.PublicKey); err != nil {
@@ -703,7 +1085,7 @@ func (p *SQLProvider) DeleteWebAuthnCredentialByUsername(ctx context.Context, us
 func (p *SQLProvider) LoadWebAuthnCredentials(ctx context.Context, limit, page int) (credentials []model.WebAuthnCredential, err error) {
 	credentials = make([]model.WebAuthnCredential, 0, limit)
 
-	if err = p.db.SelectContext(ctx, &credentials, p.sqlSelectWebAuthnCredentials, limit, limit*page); err != nil {
+	if err = p.readSelectContext(ctx, &credentials, p.sqlSelectWebAuthnCredentials, limit, limit*page); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -795,242 +1177,1169 @@ func (p *SQLProvider) LoadPreferredDuoDevice(ctx context.Context, username strin
 	return device, nil
 }
 
-// SaveIdentityVerification save an identity verification record to the storage provider.
-func (p *SQLProvider) SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlInsertIdentityVerification,
-		verification.JTI, verification.IssuedAt, verification.IssuedIP, verification.ExpiresAt,
-		verification.Username, verification.Action); err != nil {
-		return fmt.Errorf("error inserting identity verification for user '%s' with uuid '%s': %w", verification.Username, verification.JTI, err)
+// SaveDeviceCertificate saves a client certificate issued to a managed device to the storage provider.
+func (p *SQLProvider) SaveDeviceCertificate(ctx context.Context, certificate model.DeviceCertificate) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertDeviceCertificate,
+		certificate.Serial, certificate.Username, certificate.Name, certificate.IssuedAt, certificate.ExpiresAt); err != nil {
+		return fmt.Errorf("error inserting device certificate with serial '%s' for user '%s': %w", certificate.Serial, certificate.Username, err)
 	}
 
 	return nil
 }
 
-// ConsumeIdentityVerification marks an identity verification record in the storage provider as consumed.
-func (p *SQLProvider) ConsumeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlConsumeIdentityVerification, ip, jti); err != nil {
-		return fmt.Errorf("error updating identity verification: %w", err)
+// LoadDeviceCertificate loads a client certificate issued to a managed device from the storage provider given the
+// serial.
+func (p *SQLProvider) LoadDeviceCertificate(ctx context.Context, serial string) (certificate *model.DeviceCertificate, err error) {
+	certificate = &model.DeviceCertificate{}
+
+	if err = p.db.GetContext(ctx, certificate, p.sqlSelectDeviceCertificate, serial); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoDeviceCertificate
+		}
+
+		return nil, fmt.Errorf("error selecting device certificate with serial '%s': %w", serial, err)
 	}
 
-	return nil
+	return certificate, nil
 }
 
-// RevokeIdentityVerification marks an identity verification record in the storage provider as revoked.
-func (p *SQLProvider) RevokeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlRevokeIdentityVerification, ip, jti); err != nil {
-		return fmt.Errorf("error updating identity verification: %w", err)
+// LoadDeviceCertificates loads a page of the client certificates issued to the managed devices of a user from the
+// storage provider.
+func (p *SQLProvider) LoadDeviceCertificates(ctx context.Context, username string, pagination model.Pagination) (certificates []model.DeviceCertificate, err error) {
+	if err = p.db.SelectContext(ctx, &certificates, p.sqlSelectDeviceCertificatesByUsername.get(pagination.Order), username, pagination.Limit, pagination.Offset()); err != nil {
+		return nil, fmt.Errorf("error selecting device certificates for user '%s': %w", username, err)
+	}
+
+	return certificates, nil
+}
+
+// RevokeDeviceCertificate revokes a client certificate issued to a managed device of a user in the storage provider.
+func (p *SQLProvider) RevokeDeviceCertificate(ctx context.Context, username, serial string, revokedAt time.Time) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlRevokeDeviceCertificate, revokedAt, username, serial); err != nil {
+		return fmt.Errorf("error revoking device certificate with serial '%s' for user '%s': %w", serial, username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoDeviceCertificate
 	}
 
 	return nil
 }
 
-// FindIdentityVerification checks if an identity verification record is in the storage provider and active.
-func (p *SQLProvider) FindIdentityVerification(ctx context.Context, jti string) (found bool, err error) {
-	verification := model.IdentityVerification{}
-	if err = p.db.GetContext(ctx, &verification, p.sqlSelectIdentityVerification, jti); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return false, nil
-		}
+// DeleteExpiredDeviceCertificates deletes up to the limit of the client certificates issued to managed devices which
+// expired before the given time from the storage provider.
+func (p *SQLProvider) DeleteExpiredDeviceCertificates(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredDeviceCertificates, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired device certificates: %w", err)
+	}
 
-		return false, fmt.Errorf("error selecting identity verification exists: %w", err)
+	return deleted, nil
+}
+
+// SaveUserSessionIndex saves a user session index to the storage provider. The session id is encrypted and the
+// signature is derived from the session id.
+func (p *SQLProvider) SaveUserSessionIndex(ctx context.Context, index model.UserSessionIndex) (err error) {
+	index.Signature = SessionSignature(index.SessionID)
+
+	if index.SessionID, err = p.encrypt(index.SessionID); err != nil {
+		return fmt.Errorf("error encrypting the session id of the user session index for user '%s' with signature '%s': %w", index.Username, index.Signature, err)
 	}
 
-	switch {
-	case verification.RevokedAt.Valid:
-		return false, fmt.Errorf("the token has been revoked")
-	case verification.ConsumedAt.Valid:
-		return false, fmt.Errorf("the token has already been consumed")
-	case verification.ExpiresAt.Before(time.Now()):
-		return false, fmt.Errorf("the token expired %s ago", time.Since(verification.ExpiresAt))
-	default:
-		return true, nil
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserSessionIndex,
+		index.PublicID, index.Signature, index.Username, index.Domain, index.RemoteIP, index.UserAgent, index.CreatedAt, index.SessionID); err != nil {
+		return fmt.Errorf("error inserting user session index for user '%s' with signature '%s': %w", index.Username, index.Signature, err)
 	}
+
+	return nil
 }
 
-// LoadIdentityVerification loads an Identity Verification but does not do any validation.
-// For easy validation you should use FindIdentityVerification which ensures the JWT is still valid.
-func (p *SQLProvider) LoadIdentityVerification(ctx context.Context, jti string) (verification *model.IdentityVerification, err error) {
-	verification = &model.IdentityVerification{}
+// LoadUserSessionIndexes loads the user session indexes of a user from the storage provider.
+func (p *SQLProvider) LoadUserSessionIndexes(ctx context.Context, username string) (indexes []model.UserSessionIndex, err error) {
+	if err = p.db.SelectContext(ctx, &indexes, p.sqlSelectUserSessionIndexByUsername, username); err != nil {
+		return nil, fmt.Errorf("error selecting user session indexes for user '%s': %w", username, err)
+	}
 
-	if err = p.db.GetContext(ctx, verification, p.sqlSelectIdentityVerification, jti); err != nil {
-		return nil, fmt.Errorf("error selecting identity verification: %w", err)
+	for i := range indexes {
+		if indexes[i].SessionID, err = p.decrypt(indexes[i].SessionID); err != nil {
+			return nil, fmt.Errorf("error decrypting the session id of the user session index with id '%s' for user '%s': %w", indexes[i].PublicID, username, err)
+		}
 	}
 
-	return verification, nil
+	return indexes, nil
 }
 
-// SaveOneTimeCode saves a One-Time Code to the storage provider after generating the signature which is returned
-// along with any error.
-func (p *SQLProvider) SaveOneTimeCode(ctx context.Context, code model.OneTimeCode) (signature string, err error) {
-	code.Signature = p.otcHMACSignature([]byte(code.Username), []byte(code.Intent), code.Code)
+// LoadUserSessionIndex loads a user session index of a user from the storage provider given the public id.
+func (p *SQLProvider) LoadUserSessionIndex(ctx context.Context, username string, publicID uuid.UUID) (index *model.UserSessionIndex, err error) {
+	index = &model.UserSessionIndex{}
 
-	if code.Code, err = p.encrypt(code.Code); err != nil {
-		return "", fmt.Errorf("error encrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	if err = p.db.GetContext(ctx, index, p.sqlSelectUserSessionIndex, username, publicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoUserSessionIndex
+		}
+
+		return nil, fmt.Errorf("error selecting user session index with id '%s' for user '%s': %w", publicID, username, err)
 	}
 
-	if _, err = p.db.ExecContext(ctx, p.sqlInsertOneTimeCode,
-		code.PublicID, code.Signature, code.IssuedAt, code.IssuedIP, code.ExpiresAt,
-		code.Username, code.Intent, code.Code); err != nil {
-		return "", fmt.Errorf("error inserting one-time code for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	if index.SessionID, err = p.decrypt(index.SessionID); err != nil {
+		return nil, fmt.Errorf("error decrypting the session id of the user session index with id '%s' for user '%s': %w", publicID, username, err)
 	}
 
-	return code.Signature, nil
+	return index, nil
 }
 
-// ConsumeOneTimeCode consumes a one-time code using the signature.
-func (p *SQLProvider) ConsumeOneTimeCode(ctx context.Context, code *model.OneTimeCode) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlConsumeOneTimeCode, code.ConsumedAt, code.ConsumedIP, code.Signature); err != nil {
-		return fmt.Errorf("error updating one-time code (consume): %w", err)
+// DeleteUserSessionIndex deletes a user session index from the storage provider given the session id.
+func (p *SQLProvider) DeleteUserSessionIndex(ctx context.Context, sessionID []byte) (err error) {
+	signature := SessionSignature(sessionID)
+
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteUserSessionIndex, signature); err != nil {
+		return fmt.Errorf("error deleting user session index with signature '%s': %w", signature, err)
 	}
 
 	return nil
 }
 
-// RevokeOneTimeCode revokes a one-time code in the storage provider using the public ID.
-func (p *SQLProvider) RevokeOneTimeCode(ctx context.Context, publicID uuid.UUID, ip model.IP) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlRevokeOneTimeCode, ip, publicID); err != nil {
-		return fmt.Errorf("error updating one-time code (revoke): %w", err)
+// SaveUserSessionRevocation saves the revocation of a session to the storage provider given the session id so the
+// other replicas also refuse the session. The revocation is only kept until the session would have expired.
+func (p *SQLProvider) SaveUserSessionRevocation(ctx context.Context, sessionID []byte, revokedAt, expiresAt time.Time) (err error) {
+	signature := SessionSignature(sessionID)
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserSessionRevocation, signature, revokedAt, expiresAt); err != nil {
+		return fmt.Errorf("error inserting user session revocation with signature '%s': %w", signature, err)
 	}
 
 	return nil
 }
 
-// LoadOneTimeCode loads a one-time code from the storage provider given a username, intent, and code.
-func (p *SQLProvider) LoadOneTimeCode(ctx context.Context, username, intent, raw string) (code *model.OneTimeCode, err error) {
-	code = &model.OneTimeCode{}
-
-	signature := p.otcHMACSignature([]byte(username), []byte(intent), []byte(raw))
+// LoadUserSessionRevocations loads the revocations of the sessions which were revoked since the given time and which
+// have not expired from the storage provider.
+func (p *SQLProvider) LoadUserSessionRevocations(ctx context.Context, since, now time.Time) (revocations []model.UserSessionRevocation, err error) {
+	if err = p.db.SelectContext(ctx, &revocations, p.sqlSelectUserSessionRevocations, since, now); err != nil {
+		return nil, fmt.Errorf("error selecting user session revocations: %w", err)
+	}
 
-	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCode, signature, username); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
+	return revocations, nil
+}
 
-		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+// DeleteExpiredUserSessionRevocations deletes up to the limit of the revocations of the sessions which expired before
+// the given time from the storage provider.
+func (p *SQLProvider) DeleteExpiredUserSessionRevocations(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredUserSessionRevocations, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired user session revocations: %w", err)
 	}
 
-	if code.Code, err = p.decrypt(code.Code); err != nil {
-		return nil, fmt.Errorf("error decrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	return deleted, nil
+}
+
+// SaveAppPassword saves a new app password to the storage provider. Only the signature of the password is stored.
+func (p *SQLProvider) SaveAppPassword(ctx context.Context, password model.AppPassword) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertAppPassword,
+		password.PublicID, password.CreatedAt, password.Username, password.Name, password.Domains,
+		p.appPasswordSignature(password.Username, password.Password)); err != nil {
+		return fmt.Errorf("error inserting app password with name '%s' for user '%s': %w", password.Name, password.Username, err)
 	}
 
-	return code, nil
+	return nil
 }
 
-// LoadOneTimeCodeBySignature loads a one-time code from the storage provider given the signature.
-// This method should NOT be used to validate a One-Time Code, LoadOneTimeCode should be used instead.
-func (p *SQLProvider) LoadOneTimeCodeBySignature(ctx context.Context, signature string) (code *model.OneTimeCode, err error) {
-	code = &model.OneTimeCode{}
+// LoadAppPassword loads an app password of a user from the storage provider given the raw value.
+func (p *SQLProvider) LoadAppPassword(ctx context.Context, username, raw string) (password *model.AppPassword, err error) {
+	password = &model.AppPassword{}
 
-	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeBySignature, signature); err != nil {
+	if err = p.db.GetContext(ctx, password, p.sqlSelectAppPassword, username, p.appPasswordSignature(username, raw)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
+			return nil, ErrNoAppPassword
 		}
 
-		return nil, fmt.Errorf("error selecting one-time code: %w", err)
-	}
-
-	if code.Code, err = p.decrypt(code.Code); err != nil {
-		return nil, fmt.Errorf("error decrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+		return nil, fmt.Errorf("error selecting app password for user '%s': %w", username, err)
 	}
 
-	return code, nil
+	return password, nil
 }
 
-// LoadOneTimeCodeByID loads a one-time code from the storage provider given the id.
-// This does not decrypt the code. This method should NOT be used to validate a One-Time Code,
-// LoadOneTimeCode should be used instead.
-func (p *SQLProvider) LoadOneTimeCodeByID(ctx context.Context, id int) (code *model.OneTimeCode, err error) {
-	code = &model.OneTimeCode{}
-
-	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeByID, id); err != nil {
+// LoadAppPasswords loads the app passwords of a user from the storage provider.
+func (p *SQLProvider) LoadAppPasswords(ctx context.Context, username string) (passwords []model.AppPassword, err error) {
+	if err = p.db.SelectContext(ctx, &passwords, p.sqlSelectAppPasswordsByUsername, username); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 
-		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+		return nil, fmt.Errorf("error selecting app passwords for user '%s': %w", username, err)
 	}
 
-	return code, nil
+	return passwords, nil
 }
 
-// LoadOneTimeCodeByPublicID loads a one-time code from the storage provider given the public identifier.
-// This does not decrypt the code. This method SHOULD ONLY be used to find the One-Time Code for the
-// purpose of deletion.
-func (p *SQLProvider) LoadOneTimeCodeByPublicID(ctx context.Context, id uuid.UUID) (code *model.OneTimeCode, err error) {
-	code = &model.OneTimeCode{}
+// UpdateAppPasswordSignIn updates an app password in the storage provider with the relevant sign in information.
+func (p *SQLProvider) UpdateAppPasswordSignIn(ctx context.Context, id int, lastUsedAt sql.NullTime) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateAppPasswordSignIn, lastUsedAt, id); err != nil {
+		return fmt.Errorf("error updating app password sign in with id '%d': %w", id, err)
+	}
 
-	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeByPublicID, id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil
-		}
+	return nil
+}
 
-		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+// DeleteAppPassword deletes an app password of a user from the storage provider given the public id.
+func (p *SQLProvider) DeleteAppPassword(ctx context.Context, username string, publicID uuid.UUID) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlDeleteAppPassword, username, publicID); err != nil {
+		return fmt.Errorf("error deleting app password with id '%s' for user '%s': %w", publicID, username, err)
 	}
 
-	return code, nil
-}
+	if affected == 0 {
+		return ErrNoAppPassword
+	}
 
-// SaveOAuth2ConsentPreConfiguration inserts an OAuth2.0 consent pre-configuration in the storage provider.
-func (p *SQLProvider) SaveOAuth2ConsentPreConfiguration(ctx context.Context, config model.OAuth2ConsentPreConfig) (insertedID int64, err error) {
-	switch p.name {
-	case providerPostgres:
-		if err = p.db.GetContext(ctx, &insertedID, p.sqlInsertOAuth2ConsentPreConfiguration,
-			config.ClientID, config.Subject, config.CreatedAt, config.ExpiresAt,
-			config.Revoked, config.Scopes, config.Audience); err != nil {
-			return -1, fmt.Errorf("error inserting oauth2 consent pre-configuration for subject '%s' with client id '%s' and scopes '%s': %w", config.Subject.String(), config.ClientID, strings.Join(config.Scopes, " "), err)
-		}
+	return nil
+}
 
-		return insertedID, nil
-	default:
-		var result sql.Result
+func (p *SQLProvider) appPasswordSignature(username, raw string) string {
+	return p.otcHMACSignature([]byte(username), []byte(tableAppPassword), []byte(raw))
+}
 
-		if result, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2ConsentPreConfiguration,
-			config.ClientID, config.Subject, config.CreatedAt, config.ExpiresAt,
-			config.Revoked, config.Scopes, config.Audience); err != nil {
-			return -1, fmt.Errorf("error inserting oauth2 consent pre-configuration for subject '%s' with client id '%s' and scopes '%s': %w", config.Subject.String(), config.ClientID, strings.Join(config.Scopes, " "), err)
-		}
+// SaveSession saves the serialized data of a session to the storage provider, replacing the existing data of the
+// session with the same id.
+func (p *SQLProvider) SaveSession(ctx context.Context, sessionID, data []byte, expiresAt time.Time) (err error) {
+	signature := SessionSignature(sessionID)
 
-		return result.LastInsertId()
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertSession, signature, expiresAt, data); err != nil {
+		return fmt.Errorf("error upserting session with signature '%s': %w", signature, err)
 	}
+
+	return nil
 }
 
-// LoadOAuth2ConsentPreConfigurations returns an OAuth2.0 consents pre-configurations from the storage provider given the consent signature.
-func (p *SQLProvider) LoadOAuth2ConsentPreConfigurations(ctx context.Context, clientID string, subject uuid.UUID) (rows *ConsentPreConfigRows, err error) {
-	var r *sqlx.Rows
+// LoadSession loads the serialized data of a session which has not expired from the storage provider given the
+// session id.
+func (p *SQLProvider) LoadSession(ctx context.Context, sessionID []byte, now time.Time) (data []byte, err error) {
+	signature := SessionSignature(sessionID)
 
-	if r, err = p.db.QueryxContext(ctx, p.sqlSelectOAuth2ConsentPreConfigurations, clientID, subject); err != nil {
+	if err = p.db.GetContext(ctx, &data, p.sqlSelectSession, signature, now); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return &ConsentPreConfigRows{}, nil
+			return nil, ErrNoSession
 		}
 
-		return &ConsentPreConfigRows{}, fmt.Errorf("error selecting oauth2 consent pre-configurations by signature with client id '%s' and subject '%s': %w", clientID, subject.String(), err)
+		return nil, fmt.Errorf("error selecting session with signature '%s': %w", signature, err)
 	}
 
-	return &ConsentPreConfigRows{rows: r}, nil
+	return data, nil
 }
 
-// SaveOAuth2ConsentSession inserts an OAuth2.0 consent session to the storage provider.
-func (p *SQLProvider) SaveOAuth2ConsentSession(ctx context.Context, consent model.OAuth2ConsentSession) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2ConsentSession,
-		consent.ChallengeID, consent.ClientID, consent.Subject, consent.Authorized, consent.Granted,
-		consent.RequestedAt, consent.RespondedAt, consent.Form,
-		consent.RequestedScopes, consent.GrantedScopes, consent.RequestedAudience, consent.GrantedAudience, consent.PreConfiguration); err != nil {
-		return fmt.Errorf("error inserting oauth2 consent session with challenge id '%s' for subject '%s': %w", consent.ChallengeID.String(), consent.Subject.UUID.String(), err)
+// RegenerateSession changes the id and the expiration of a session in the storage provider.
+func (p *SQLProvider) RegenerateSession(ctx context.Context, sessionID, newSessionID []byte, expiresAt time.Time) (err error) {
+	signature := SessionSignature(sessionID)
+
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateSessionSignature, SessionSignature(newSessionID), expiresAt, signature); err != nil {
+		return fmt.Errorf("error updating session with signature '%s': %w", signature, err)
 	}
 
 	return nil
 }
 
-// SaveOAuth2ConsentSessionSubject updates an OAuth2.0 consent session in the storage provider with the subject.
-func (p *SQLProvider) SaveOAuth2ConsentSessionSubject(ctx context.Context, consent model.OAuth2ConsentSession) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2ConsentSessionSubject, consent.Subject, consent.ID); err != nil {
-		return fmt.Errorf("error updating oauth2 consent session subject with id '%d' and challenge id '%s' for subject '%s': %w", consent.ID, consent.ChallengeID, consent.Subject.UUID, err)
+// DeleteSession deletes a session from the storage provider given the session id.
+func (p *SQLProvider) DeleteSession(ctx context.Context, sessionID []byte) (err error) {
+	signature := SessionSignature(sessionID)
+
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteSession, signature); err != nil {
+		return fmt.Errorf("error deleting session with signature '%s': %w", signature, err)
 	}
 
 	return nil
 }
 
-// SaveOAuth2ConsentSessionResponse updates an OAuth2.0 consent session in the storage provider with the response.
+// CountSessions returns the number of sessions which have not expired in the storage provider.
+func (p *SQLProvider) CountSessions(ctx context.Context, now time.Time) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectCountSessions, now); err != nil {
+		return 0, fmt.Errorf("error counting sessions: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpiredSessions deletes up to the limit of the sessions which expired before the given time from the storage
+// provider.
+func (p *SQLProvider) DeleteExpiredSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredSessions, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired sessions: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SaveDirectoryUser saves a new directory user to the storage provider.
+func (p *SQLProvider) SaveDirectoryUser(ctx context.Context, user model.DirectoryUser) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertDirectoryUser,
+		user.PublicID, user.ExternalID, user.Username, user.DisplayName, user.GivenName, user.FamilyName, user.Emails,
+		user.Active, user.CreatedAt, user.UpdatedAt); err != nil {
+		return fmt.Errorf("error inserting directory user '%s' with id '%s': %w", user.Username, user.PublicID, err)
+	}
+
+	return nil
+}
+
+// UpdateDirectoryUser updates the attributes of a directory user in the storage provider given the public id.
+func (p *SQLProvider) UpdateDirectoryUser(ctx context.Context, user model.DirectoryUser) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateDirectoryUser,
+		user.ExternalID, user.Username, user.DisplayName, user.GivenName, user.FamilyName, user.Emails, user.Active,
+		user.UpdatedAt, user.PublicID); err != nil {
+		return fmt.Errorf("error updating directory user '%s' with id '%s': %w", user.Username, user.PublicID, err)
+	}
+
+	return nil
+}
+
+// LoadDirectoryUser loads a directory user from the storage provider given the public id.
+func (p *SQLProvider) LoadDirectoryUser(ctx context.Context, publicID uuid.UUID) (user *model.DirectoryUser, err error) {
+	user = &model.DirectoryUser{}
+
+	if err = p.db.GetContext(ctx, user, p.sqlSelectDirectoryUser, publicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoDirectoryUser
+		}
+
+		return nil, fmt.Errorf("error selecting directory user with id '%s': %w", publicID, err)
+	}
+
+	return user, nil
+}
+
+// LoadDirectoryUsers loads up to the limit of the directory users which match the filter from the storage provider
+// starting at the offset.
+func (p *SQLProvider) LoadDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter, limit, offset int) (users []model.DirectoryUser, err error) {
+	if err = p.db.SelectContext(ctx, &users, p.sqlSelectDirectoryUsers,
+		filter.Username, filter.Username, filter.ExternalID, filter.ExternalID, limit, offset); err != nil {
+		return nil, fmt.Errorf("error selecting directory users: %w", err)
+	}
+
+	return users, nil
+}
+
+// CountDirectoryUsers returns the number of directory users which match the filter in the storage provider.
+func (p *SQLProvider) CountDirectoryUsers(ctx context.Context, filter model.DirectoryUserFilter) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectDirectoryUsersCount,
+		filter.Username, filter.Username, filter.ExternalID, filter.ExternalID); err != nil {
+		return 0, fmt.Errorf("error counting directory users: %w", err)
+	}
+
+	return count, nil
+}
+
+// LoadDirectoryUserPassword loads the password digest of an active directory user from the storage provider given the
+// username. The digest is empty if the directory user doesn't have a password.
+func (p *SQLProvider) LoadDirectoryUserPassword(ctx context.Context, username string) (password string, err error) {
+	if err = p.db.GetContext(ctx, &password, p.sqlSelectDirectoryUserPassword, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoDirectoryUser
+		}
+
+		return "", fmt.Errorf("error selecting password of directory user '%s': %w", username, err)
+	}
+
+	return password, nil
+}
+
+// UpdateDirectoryUserPassword updates the password digest of a directory user in the storage provider given the
+// username.
+func (p *SQLProvider) UpdateDirectoryUserPassword(ctx context.Context, username, password string) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlUpdateDirectoryUserPassword, password, time.Now(), username); err != nil {
+		return fmt.Errorf("error updating password of directory user '%s': %w", username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoDirectoryUser
+	}
+
+	return nil
+}
+
+// DeleteDirectoryUser deletes a directory user and their group memberships from the storage provider given the
+// public id.
+func (p *SQLProvider) DeleteDirectoryUser(ctx context.Context, publicID uuid.UUID) (err error) {
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryGroupMembersByUser, publicID); err != nil {
+			return fmt.Errorf("error deleting group memberships of directory user with id '%s': %w", publicID, err)
+		}
+
+		var (
+			result   sql.Result
+			affected int64
+		)
+
+		if result, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryUser, publicID); err != nil {
+			return fmt.Errorf("error deleting directory user with id '%s': %w", publicID, err)
+		}
+
+		if affected, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("error deleting directory user with id '%s': %w", publicID, err)
+		}
+
+		if affected == 0 {
+			return ErrNoDirectoryUser
+		}
+
+		return nil
+	})
+}
+
+// LoadDirectoryUserGroups loads the directory groups a directory user is a member of from the storage provider given
+// the username.
+func (p *SQLProvider) LoadDirectoryUserGroups(ctx context.Context, username string) (groups []model.DirectoryGroup, err error) {
+	if err = p.db.SelectContext(ctx, &groups, p.sqlSelectDirectoryUserGroups, username); err != nil {
+		return nil, fmt.Errorf("error selecting directory groups of directory user '%s': %w", username, err)
+	}
+
+	return groups, nil
+}
+
+// SaveDirectoryUserGroups replaces the group memberships of a directory user in the storage provider given the public
+// id of the user and the public ids of the groups. The public ids of groups which don't exist are ignored.
+func (p *SQLProvider) SaveDirectoryUserGroups(ctx context.Context, publicID uuid.UUID, groups []uuid.UUID) (err error) {
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryGroupMembersByUser, publicID); err != nil {
+			return fmt.Errorf("error deleting group memberships of directory user with id '%s': %w", publicID, err)
+		}
+
+		for _, group := range groups {
+			if _, err = tx.ExecContext(ctx, p.sqlInsertDirectoryGroupMember, group, publicID); err != nil {
+				return fmt.Errorf("error inserting membership of directory group with id '%s' for directory user with id '%s': %w", group, publicID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// SaveDirectoryGroup saves a new directory group to the storage provider.
+func (p *SQLProvider) SaveDirectoryGroup(ctx context.Context, group model.DirectoryGroup) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertDirectoryGroup,
+		group.PublicID, group.ExternalID, group.DisplayName, group.CreatedAt, group.UpdatedAt); err != nil {
+		return fmt.Errorf("error inserting directory group '%s' with id '%s': %w", group.DisplayName, group.PublicID, err)
+	}
+
+	return nil
+}
+
+// UpdateDirectoryGroup updates the attributes of a directory group in the storage provider given the public id.
+func (p *SQLProvider) UpdateDirectoryGroup(ctx context.Context, group model.DirectoryGroup) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateDirectoryGroup,
+		group.ExternalID, group.DisplayName, group.UpdatedAt, group.PublicID); err != nil {
+		return fmt.Errorf("error updating directory group '%s' with id '%s': %w", group.DisplayName, group.PublicID, err)
+	}
+
+	return nil
+}
+
+// LoadDirectoryGroup loads a directory group from the storage provider given the public id.
+func (p *SQLProvider) LoadDirectoryGroup(ctx context.Context, publicID uuid.UUID) (group *model.DirectoryGroup, err error) {
+	group = &model.DirectoryGroup{}
+
+	if err = p.db.GetContext(ctx, group, p.sqlSelectDirectoryGroup, publicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoDirectoryGroup
+		}
+
+		return nil, fmt.Errorf("error selecting directory group with id '%s': %w", publicID, err)
+	}
+
+	return group, nil
+}
+
+// LoadDirectoryGroups loads up to the limit of the directory groups which match the filter from the storage provider
+// starting at the offset.
+func (p *SQLProvider) LoadDirectoryGroups(ctx context.Context, filter model.DirectoryGroupFilter, limit, offset int) (groups []model.DirectoryGroup, err error) {
+	if err = p.db.SelectContext(ctx, &groups, p.sqlSelectDirectoryGroups,
+		filter.DisplayName, filter.DisplayName, filter.ExternalID, filter.ExternalID, limit, offset); err != nil {
+		return nil, fmt.Errorf("error selecting directory groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// CountDirectoryGroups returns the number of directory groups which match the filter in the storage provider.
+func (p *SQLProvider) CountDirectoryGroups(ctx context.Context, filter model.DirectoryGroupFilter) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectDirectoryGroupsCount,
+		filter.DisplayName, filter.DisplayName, filter.ExternalID, filter.ExternalID); err != nil {
+		return 0, fmt.Errorf("error counting directory groups: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteDirectoryGroup deletes a directory group and its memberships from the storage provider given the public id.
+func (p *SQLProvider) DeleteDirectoryGroup(ctx context.Context, publicID uuid.UUID) (err error) {
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryGroupMembersByGroup, publicID); err != nil {
+			return fmt.Errorf("error deleting members of directory group with id '%s': %w", publicID, err)
+		}
+
+		var (
+			result   sql.Result
+			affected int64
+		)
+
+		if result, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryGroup, publicID); err != nil {
+			return fmt.Errorf("error deleting directory group with id '%s': %w", publicID, err)
+		}
+
+		if affected, err = result.RowsAffected(); err != nil {
+			return fmt.Errorf("error deleting directory group with id '%s': %w", publicID, err)
+		}
+
+		if affected == 0 {
+			return ErrNoDirectoryGroup
+		}
+
+		return nil
+	})
+}
+
+// SaveDirectoryGroupMembers replaces the members of a directory group in the storage provider given the public id of
+// the group and the public ids of the users. The public ids of users which don't exist are ignored.
+func (p *SQLProvider) SaveDirectoryGroupMembers(ctx context.Context, publicID uuid.UUID, members []uuid.UUID) (err error) {
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.ExecContext(ctx, p.sqlDeleteDirectoryGroupMembersByGroup, publicID); err != nil {
+			return fmt.Errorf("error deleting members of directory group with id '%s': %w", publicID, err)
+		}
+
+		for _, member := range members {
+			if _, err = tx.ExecContext(ctx, p.sqlInsertDirectoryGroupMember, publicID, member); err != nil {
+				return fmt.Errorf("error inserting member with id '%s' of directory group with id '%s': %w", member, publicID, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// LoadDirectoryGroupMembers loads the members of a directory group from the storage provider given the public id.
+func (p *SQLProvider) LoadDirectoryGroupMembers(ctx context.Context, publicID uuid.UUID) (members []model.DirectoryGroupMember, err error) {
+	if err = p.db.SelectContext(ctx, &members, p.sqlSelectDirectoryGroupMembers, publicID); err != nil {
+		return nil, fmt.Errorf("error selecting members of directory group with id '%s': %w", publicID, err)
+	}
+
+	return members, nil
+}
+
+// SaveMobileDevice saves a new mobile device to the storage provider.
+func (p *SQLProvider) SaveMobileDevice(ctx context.Context, device model.MobileDevice) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertMobileDevice,
+		device.PublicID, device.Username, device.Name, device.Platform, device.CreatedAt,
+		device.AccessSignature, device.AccessExpiresAt, device.RefreshSignature, device.ExpiresAt); err != nil {
+		return fmt.Errorf("error inserting mobile device with id '%s' for user '%s': %w", device.PublicID, device.Username, err)
+	}
+
+	return nil
+}
+
+// LoadMobileDevice loads a mobile device from the storage provider given the public id.
+func (p *SQLProvider) LoadMobileDevice(ctx context.Context, publicID uuid.UUID) (device *model.MobileDevice, err error) {
+	device = &model.MobileDevice{}
+
+	if err = p.db.GetContext(ctx, device, p.sqlSelectMobileDevice, publicID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoMobileDevice
+		}
+
+		return nil, fmt.Errorf("error selecting mobile device with id '%s': %w", publicID, err)
+	}
+
+	return device, nil
+}
+
+// LoadMobileDeviceByAccessSignature loads a mobile device from the storage provider given the signature of the access
+// token.
+func (p *SQLProvider) LoadMobileDeviceByAccessSignature(ctx context.Context, signature string) (device *model.MobileDevice, err error) {
+	device = &model.MobileDevice{}
+
+	if err = p.db.GetContext(ctx, device, p.sqlSelectMobileDeviceByAccessSignature, signature); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoMobileDevice
+		}
+
+		return nil, fmt.Errorf("error selecting mobile device with access token signature '%s': %w", signature, err)
+	}
+
+	return device, nil
+}
+
+// UpdateMobileDeviceTokens updates the access and refresh tokens of a mobile device in the storage provider given the
+// public id. The tokens are only updated if the current refresh token signature of the device is the previous refresh
+// token signature of the given device which ensures a refresh token is only exchanged once.
+func (p *SQLProvider) UpdateMobileDeviceTokens(ctx context.Context, device model.MobileDevice) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlUpdateMobileDeviceTokens,
+		device.AccessSignature, device.AccessExpiresAt, device.RefreshSignature, device.PreviousRefreshSignature,
+		device.ExpiresAt, device.LastUsedAt, device.PublicID, device.PreviousRefreshSignature); err != nil {
+		return fmt.Errorf("error updating the tokens of mobile device with id '%s' for user '%s': %w", device.PublicID, device.Username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoMobileDevice
+	}
+
+	return nil
+}
+
+// LoadMobileDevicesPush loads the mobile devices of a user from the storage provider which are enrolled for push
+// notifications and which are neither revoked nor expired at the given time.
+func (p *SQLProvider) LoadMobileDevicesPush(ctx context.Context, username string, now time.Time) (devices []model.MobileDevice, err error) {
+	if err = p.db.SelectContext(ctx, &devices, p.sqlSelectMobileDevicesPush, username, now); err != nil {
+		return nil, fmt.Errorf("error selecting mobile devices enrolled for push notifications for user '%s': %w", username, err)
+	}
+
+	return devices, nil
+}
+
+// UpdateMobileDevicePush updates the push notification registration of a mobile device in the storage provider given
+// the public id.
+func (p *SQLProvider) UpdateMobileDevicePush(ctx context.Context, publicID uuid.UUID, provider, token string) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlUpdateMobileDevicePush, provider, token, publicID); err != nil {
+		return fmt.Errorf("error updating the push notification registration of mobile device with id '%s': %w", publicID, err)
+	}
+
+	if affected == 0 {
+		return ErrNoMobileDevice
+	}
+
+	return nil
+}
+
+// RevokeMobileDevice revokes a mobile device in the storage provider given the public id.
+func (p *SQLProvider) RevokeMobileDevice(ctx context.Context, publicID uuid.UUID, revokedAt time.Time) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlRevokeMobileDevice, revokedAt, publicID); err != nil {
+		return fmt.Errorf("error revoking mobile device with id '%s': %w", publicID, err)
+	}
+
+	if affected == 0 {
+		return ErrNoMobileDevice
+	}
+
+	return nil
+}
+
+// DeleteExpiredMobileDevices deletes up to the limit of the mobile devices which refresh tokens expired before the
+// given time from the storage provider.
+func (p *SQLProvider) DeleteExpiredMobileDevices(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredMobileDevices, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired mobile devices: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SaveSAMLSession saves a new SAML 2.0 session to the storage provider.
+func (p *SQLProvider) SaveSAMLSession(ctx context.Context, session model.SAMLSession) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertSAMLSession,
+		session.SessionIndex, session.EntityID, session.Username, session.NameID, session.NameIDFormat,
+		session.CreatedAt, session.ExpiresAt); err != nil {
+		return fmt.Errorf("error inserting saml session with session index '%s' for user '%s': %w", session.SessionIndex, session.Username, err)
+	}
+
+	return nil
+}
+
+// LoadSAMLSession loads a SAML 2.0 session from the storage provider given the session index.
+func (p *SQLProvider) LoadSAMLSession(ctx context.Context, sessionIndex string) (session *model.SAMLSession, err error) {
+	session = &model.SAMLSession{}
+
+	if err = p.db.GetContext(ctx, session, p.sqlSelectSAMLSession, sessionIndex); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoSAMLSession
+		}
+
+		return nil, fmt.Errorf("error selecting saml session with session index '%s': %w", sessionIndex, err)
+	}
+
+	return session, nil
+}
+
+// RevokeSAMLSession revokes a SAML 2.0 session in the storage provider given the session index.
+func (p *SQLProvider) RevokeSAMLSession(ctx context.Context, sessionIndex string, revokedAt time.Time) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlRevokeSAMLSession, revokedAt, sessionIndex); err != nil {
+		return fmt.Errorf("error revoking saml session with session index '%s': %w", sessionIndex, err)
+	}
+
+	if affected == 0 {
+		return ErrNoSAMLSession
+	}
+
+	return nil
+}
+
+// RevokeSAMLSessionsByUsername revokes all of the SAML 2.0 sessions of a user in the storage provider.
+func (p *SQLProvider) RevokeSAMLSessionsByUsername(ctx context.Context, username string, revokedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRevokeSAMLSessionsByUsername, revokedAt, username); err != nil {
+		return fmt.Errorf("error revoking saml sessions for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSAMLSessions deletes up to the limit of the SAML 2.0 sessions which expired before the given time from
+// the storage provider.
+func (p *SQLProvider) DeleteExpiredSAMLSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredSAMLSessions, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired saml sessions: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SaveIdentityVerification save an identity verification record to the storage provider.
+func (p *SQLProvider) SaveIdentityVerification(ctx context.Context, verification model.IdentityVerification) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertIdentityVerification,
+		verification.JTI, verification.IssuedAt, verification.IssuedIP, verification.ExpiresAt,
+		verification.Username, verification.Action); err != nil {
+		return fmt.Errorf("error inserting identity verification for user '%s' with uuid '%s': %w", verification.Username, verification.JTI, err)
+	}
+
+	return nil
+}
+
+// ConsumeIdentityVerification marks an identity verification record in the storage provider as consumed.
+func (p *SQLProvider) ConsumeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlConsumeIdentityVerification, ip, jti); err != nil {
+		return fmt.Errorf("error updating identity verification: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrIdentityVerificationNotConsumable
+	}
+
+	return nil
+}
+
+// RevokeIdentityVerification marks an identity verification record in the storage provider as revoked.
+func (p *SQLProvider) RevokeIdentityVerification(ctx context.Context, jti string, ip model.NullIP) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRevokeIdentityVerification, ip, jti); err != nil {
+		return fmt.Errorf("error updating identity verification: %w", err)
+	}
+
+	return nil
+}
+
+// CountIdentityVerifications returns the number of identity verification records issued to a user for an action
+// since the provided time.
+func (p *SQLProvider) CountIdentityVerifications(ctx context.Context, username, action string, since time.Time) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectCountIdentityVerifications, username, action, since); err != nil {
+		return 0, fmt.Errorf("error selecting the number of identity verifications issued to user '%s' for action '%s': %w", username, action, err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpiredIdentityVerifications deletes up to the limit of the identity verification records which expired before
+// the provided time from the storage provider, returning the number of records deleted.
+func (p *SQLProvider) DeleteExpiredIdentityVerifications(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredIdentityVerifications, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired identity verifications: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// FindIdentityVerification checks if an identity verification record is in the storage provider and active.
+func (p *SQLProvider) FindIdentityVerification(ctx context.Context, jti string) (found bool, err error) {
+	verification := model.IdentityVerification{}
+	if err = p.db.GetContext(ctx, &verification, p.sqlSelectIdentityVerification, jti); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("error selecting identity verification exists: %w", err)
+	}
+
+	switch {
+	case verification.RevokedAt.Valid:
+		return false, fmt.Errorf("the token has been revoked")
+	case verification.ConsumedAt.Valid:
+		return false, fmt.Errorf("the token has already been consumed")
+	case verification.ExpiresAt.Before(time.Now()):
+		return false, fmt.Errorf("the token expired %s ago", time.Since(verification.ExpiresAt))
+	default:
+		return true, nil
+	}
+}
+
+// LoadIdentityVerification loads an Identity Verification but does not do any validation.
+// For easy validation you should use FindIdentityVerification which ensures the JWT is still valid.
+func (p *SQLProvider) LoadIdentityVerification(ctx context.Context, jti string) (verification *model.IdentityVerification, err error) {
+	verification = &model.IdentityVerification{}
+
+	if err = p.db.GetContext(ctx, verification, p.sqlSelectIdentityVerification, jti); err != nil {
+		return nil, fmt.Errorf("error selecting identity verification: %w", err)
+	}
+
+	return verification, nil
+}
+
+// SaveRecoveryCodes replaces the recovery codes of a user in the storage provider with the given recovery codes. Only
+// the signatures of the codes are saved.
+func (p *SQLProvider) SaveRecoveryCodes(ctx context.Context, username string, codes []model.RecoveryCode) (err error) {
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		if _, err = tx.ExecContext(ctx, p.sqlDeleteRecoveryCodes, username); err != nil {
+			return fmt.Errorf("error deleting recovery codes for user '%s': %w", username, err)
+		}
+
+		for _, code := range codes {
+			if _, err = tx.ExecContext(ctx, p.sqlInsertRecoveryCode, code.CreatedAt, username, p.recoveryCodeSignature(username, code.Code)); err != nil {
+				return fmt.Errorf("error inserting recovery code for user '%s': %w", username, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ConsumeRecoveryCode consumes the recovery code of a user in the storage provider given the raw value, returning true
+// if the code was consumed. The code is consumed with a single statement which only affects codes which were not
+// already consumed, so concurrent requests can't consume the same code more than once.
+func (p *SQLProvider) ConsumeRecoveryCode(ctx context.Context, username, raw string, consumedAt time.Time, ip model.NullIP) (consumed bool, err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlConsumeRecoveryCode, consumedAt, ip, p.recoveryCodeSignature(username, raw), username); err != nil {
+		return false, fmt.Errorf("error updating recovery code (consume) for user '%s': %w", username, err)
+	}
+
+	return affected == 1, nil
+}
+
+// CountRecoveryCodes returns the number of recovery codes of a user in the storage provider which were not consumed.
+func (p *SQLProvider) CountRecoveryCodes(ctx context.Context, username string) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectRecoveryCodesCount, username); err != nil {
+		return 0, fmt.Errorf("error selecting recovery codes count for user '%s': %w", username, err)
+	}
+
+	return count, nil
+}
+
+// DeleteRecoveryCodes deletes all of the recovery codes of a user from the storage provider.
+func (p *SQLProvider) DeleteRecoveryCodes(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteRecoveryCodes, username); err != nil {
+		return fmt.Errorf("error deleting recovery codes for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) recoveryCodeSignature(username, raw string) string {
+	return p.otcHMACSignature([]byte(username), []byte(tableRecoveryCode), []byte(model.NormalizeRecoveryCode(raw)))
+}
+
+// SavePhoneNumber saves the verified phone number of a user to the storage provider, replacing any existing phone
+// number of the user. The phone number is encrypted.
+func (p *SQLProvider) SavePhoneNumber(ctx context.Context, number model.PhoneNumber) (err error) {
+	if number.Number, err = p.encrypt(number.Number); err != nil {
+		return fmt.Errorf("error encrypting phone number for user '%s': %w", number.Username, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertPhoneNumber, number.CreatedAt, number.LastUsedAt, number.Username, number.Number); err != nil {
+		return fmt.Errorf("error upserting phone number for user '%s': %w", number.Username, err)
+	}
+
+	return nil
+}
+
+// LoadPhoneNumber loads the verified phone number of a user from the storage provider.
+func (p *SQLProvider) LoadPhoneNumber(ctx context.Context, username string) (number *model.PhoneNumber, err error) {
+	number = &model.PhoneNumber{}
+
+	if err = p.db.GetContext(ctx, number, p.sqlSelectPhoneNumber, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoPhoneNumber
+		}
+
+		return nil, fmt.Errorf("error selecting phone number for user '%s': %w", username, err)
+	}
+
+	if number.Number, err = p.decrypt(number.Number); err != nil {
+		return nil, fmt.Errorf("error decrypting phone number for user '%s': %w", username, err)
+	}
+
+	return number, nil
+}
+
+// UpdatePhoneNumberSignIn updates the verified phone number in the storage provider with the relevant sign in
+// information.
+func (p *SQLProvider) UpdatePhoneNumberSignIn(ctx context.Context, id int, lastUsedAt sql.NullTime) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdatePhoneNumberRecordSignIn, lastUsedAt, id); err != nil {
+		return fmt.Errorf("error updating phone number id %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeletePhoneNumber deletes the verified phone number of a user from the storage provider.
+func (p *SQLProvider) DeletePhoneNumber(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeletePhoneNumber, username); err != nil {
+		return fmt.Errorf("error deleting phone number for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SavePushChallenge saves a new push challenge to the storage provider. Only the signature of the token is saved.
+func (p *SQLProvider) SavePushChallenge(ctx context.Context, challenge model.PushChallenge) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertPushChallenge,
+		challenge.ChallengeID, challenge.CreatedAt, challenge.ExpiresAt, challenge.Username, challenge.Device,
+		challenge.Message, p.pushChallengeSignature(challenge.ChallengeID, challenge.Token), challenge.RequestIP,
+		challenge.Status); err != nil {
+		return fmt.Errorf("error inserting push challenge with id '%s' for user '%s': %w", challenge.ChallengeID, challenge.Username, err)
+	}
+
+	return nil
+}
+
+// LoadPushChallenge loads a push challenge from the storage provider given the challenge id.
+func (p *SQLProvider) LoadPushChallenge(ctx context.Context, challengeID uuid.UUID) (challenge *model.PushChallenge, err error) {
+	challenge = &model.PushChallenge{}
+
+	if err = p.db.GetContext(ctx, challenge, p.sqlSelectPushChallenge, challengeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoPushChallenge
+		}
+
+		return nil, fmt.Errorf("error selecting push challenge with id '%s': %w", challengeID, err)
+	}
+
+	return challenge, nil
+}
+
+// RespondPushChallenge responds to a pending push challenge in the storage provider given the challenge id and the raw
+// value of the token, returning true if the challenge was responded to. The challenge is responded to with a single
+// statement which only affects pending challenges which have not expired, so concurrent requests can't respond to the
+// same challenge more than once.
+func (p *SQLProvider) RespondPushChallenge(ctx context.Context, challengeID uuid.UUID, token string, status int, respondedAt time.Time, ip model.NullIP) (responded bool, err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlUpdatePushChallengeResponse, status, respondedAt, ip, challengeID, p.pushChallengeSignature(challengeID, token), respondedAt); err != nil {
+		return false, fmt.Errorf("error updating push challenge (respond) with id '%s': %w", challengeID, err)
+	}
+
+	return affected == 1, nil
+}
+
+// DeleteExpiredPushChallenges deletes up to the limit of the push challenges which expired before the given time from
+// the storage provider.
+func (p *SQLProvider) DeleteExpiredPushChallenges(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredPushChallenges, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired push challenges: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (p *SQLProvider) pushChallengeSignature(challengeID uuid.UUID, raw string) string {
+	return p.otcHMACSignature([]byte(challengeID.String()), []byte(tablePushChallenge), []byte(raw))
+}
+
+// SaveOneTimeCode saves a One-Time Code to the storage provider after generating the signature which is returned
+// along with any error.
+func (p *SQLProvider) SaveOneTimeCode(ctx context.Context, code model.OneTimeCode) (signature string, err error) {
+	code.Signature = p.otcHMACSignature([]byte(code.Username), []byte(code.Intent), code.Code)
+
+	if code.Code, err = p.encrypt(code.Code); err != nil {
+		return "", fmt.Errorf("error encrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOneTimeCode,
+		code.PublicID, code.Signature, code.IssuedAt, code.IssuedIP, code.ExpiresAt,
+		code.Username, code.Intent, code.Code); err != nil {
+		return "", fmt.Errorf("error inserting one-time code for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	}
+
+	return code.Signature, nil
+}
+
+// ConsumeOneTimeCode consumes a one-time code using the signature.
+func (p *SQLProvider) ConsumeOneTimeCode(ctx context.Context, code *model.OneTimeCode) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlConsumeOneTimeCode, code.ConsumedAt, code.ConsumedIP, code.Signature); err != nil {
+		return fmt.Errorf("error updating one-time code (consume): %w", err)
+	}
+
+	return nil
+}
+
+// CountOneTimeCodes returns the number of one-time codes issued to a user for an intent since the given time.
+func (p *SQLProvider) CountOneTimeCodes(ctx context.Context, username, intent string, since time.Time) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlSelectCountOneTimeCodes, username, intent, since); err != nil {
+		return 0, fmt.Errorf("error counting one-time codes for user '%s' with intent '%s': %w", username, intent, err)
+	}
+
+	return count, nil
+}
+
+// RevokeOneTimeCode revokes a one-time code in the storage provider using the public ID.
+func (p *SQLProvider) RevokeOneTimeCode(ctx context.Context, publicID uuid.UUID, ip model.IP) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRevokeOneTimeCode, ip, publicID); err != nil {
+		return fmt.Errorf("error updating one-time code (revoke): %w", err)
+	}
+
+	return nil
+}
+
+// LoadOneTimeCode loads a one-time code from the storage provider given a username, intent, and code.
+func (p *SQLProvider) LoadOneTimeCode(ctx context.Context, username, intent, raw string) (code *model.OneTimeCode, err error) {
+	code = &model.OneTimeCode{}
+
+	signature := p.otcHMACSignature([]byte(username), []byte(intent), []byte(raw))
+
+	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCode, signature, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+	}
+
+	if code.Code, err = p.decrypt(code.Code); err != nil {
+		return nil, fmt.Errorf("error decrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	}
+
+	return code, nil
+}
+
+// LoadOneTimeCodeBySignature loads a one-time code from the storage provider given the signature.
+// This method should NOT be used to validate a One-Time Code, LoadOneTimeCode should be used instead.
+func (p *SQLProvider) LoadOneTimeCodeBySignature(ctx context.Context, signature string) (code *model.OneTimeCode, err error) {
+	code = &model.OneTimeCode{}
+
+	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeBySignature, signature); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+	}
+
+	if code.Code, err = p.decrypt(code.Code); err != nil {
+		return nil, fmt.Errorf("error decrypting the one-time code value for user '%s' with signature '%s': %w", code.Username, code.Signature, err)
+	}
+
+	return code, nil
+}
+
+// LoadOneTimeCodeByID loads a one-time code from the storage provider given the id.
+// This does not decrypt the code. This method should NOT be used to validate a One-Time Code,
+// LoadOneTimeCode should be used instead.
+func (p *SQLProvider) LoadOneTimeCodeByID(ctx context.Context, id int) (code *model.OneTimeCode, err error) {
+	code = &model.OneTimeCode{}
+
+	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeByID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+	}
+
+	return code, nil
+}
+
+// LoadOneTimeCodeByPublicID loads a one-time code from the storage provider given the public identifier.
+// This does not decrypt the code. This method SHOULD ONLY be used to find the One-Time Code for the
+// purpose of deletion.
+func (p *SQLProvider) LoadOneTimeCodeByPublicID(ctx context.Context, id uuid.UUID) (code *model.OneTimeCode, err error) {
+	code = &model.OneTimeCode{}
+
+	if err = p.db.GetContext(ctx, code, p.sqlSelectOneTimeCodeByPublicID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting one-time code: %w", err)
+	}
+
+	return code, nil
+}
+
+// SaveOAuth2ConsentPreConfiguration inserts an OAuth2.0 consent pre-configuration in the storage provider.
+func (p *SQLProvider) SaveOAuth2ConsentPreConfiguration(ctx context.Context, config model.OAuth2ConsentPreConfig) (insertedID int64, err error) {
+	switch p.name {
+	case providerPostgres, providerCockroachDB:
+		if err = p.db.GetContext(ctx, &insertedID, p.sqlInsertOAuth2ConsentPreConfiguration,
+			config.ClientID, config.Subject, config.CreatedAt, config.ExpiresAt,
+			config.Revoked, config.Scopes, config.Audience); err != nil {
+			return -1, fmt.Errorf("error inserting oauth2 consent pre-configuration for subject '%s' with client id '%s' and scopes '%s': %w", config.Subject.String(), config.ClientID, strings.Join(config.Scopes, " "), err)
+		}
+
+		return insertedID, nil
+	default:
+		var result sql.Result
+
+		if result, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2ConsentPreConfiguration,
+			config.ClientID, config.Subject, config.CreatedAt, config.ExpiresAt,
+			config.Revoked, config.Scopes, config.Audience); err != nil {
+			return -1, fmt.Errorf("error inserting oauth2 consent pre-configuration for subject '%s' with client id '%s' and scopes '%s': %w", config.Subject.String(), config.ClientID, strings.Join(config.Scopes, " "), err)
+		}
+
+		return result.LastInsertId()
+	}
+}
+
+// LoadOAuth2ConsentPreConfigurations returns an OAuth2.0 consents pre-configurations from the storage provider given the consent signature.
+func (p *SQLProvider) LoadOAuth2ConsentPreConfigurations(ctx context.Context, clientID string, subject uuid.UUID) (rows *ConsentPreConfigRows, err error) {
+	var r *sqlx.Rows
+
+	if r, err = p.db.QueryxContext(ctx, p.sqlSelectOAuth2ConsentPreConfigurations, clientID, subject); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &ConsentPreConfigRows{}, nil
+		}
+
+		return &ConsentPreConfigRows{}, fmt.Errorf("error selecting oauth2 consent pre-configurations by signature with client id '%s' and subject '%s': %w", clientID, subject.String(), err)
+	}
+
+	return &ConsentPreConfigRows{rows: r}, nil
+}
+
+// SaveOAuth2ConsentSession inserts an OAuth2.0 consent session to the storage provider.
+func (p *SQLProvider) SaveOAuth2ConsentSession(ctx context.Context, consent model.OAuth2ConsentSession) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2ConsentSession,
+		consent.ChallengeID, consent.ClientID, consent.Subject, consent.Authorized, consent.Granted,
+		consent.RequestedAt, consent.RespondedAt, consent.Form,
+		consent.RequestedScopes, consent.GrantedScopes, consent.RequestedAudience, consent.GrantedAudience, consent.PreConfiguration); err != nil {
+		return fmt.Errorf("error inserting oauth2 consent session with challenge id '%s' for subject '%s': %w", consent.ChallengeID.String(), consent.Subject.UUID.String(), err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2ConsentSessionsByUsername returns a page of the OAuth2.0 consent sessions a user has responded to which
+// match the provided filter from the storage provider, sorted by the time of the response.
+func (p *SQLProvider) LoadOAuth2ConsentSessionsByUsername(ctx context.Context, username string, filter model.OAuth2ConsentSessionFilter, pagination model.Pagination) (consents []model.OAuth2ConsentSession, err error) {
+	consents = make([]model.OAuth2ConsentSession, 0, pagination.Limit)
+
+	if err = p.readSelectContext(ctx, &consents, p.sqlSelectOAuth2ConsentSessionsByUsername.get(pagination.Order),
+		username, filter.ClientID, filter.ClientID, pagination.Limit, pagination.Offset()); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 consent sessions for user '%s': %w", username, err)
+	}
+
+	return consents, nil
+}
+
+// SaveOAuth2ConsentSessionSubject updates an OAuth2.0 consent session in the storage provider with the subject.
+func (p *SQLProvider) SaveOAuth2ConsentSessionSubject(ctx context.Context, consent model.OAuth2ConsentSession) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2ConsentSessionSubject, consent.Subject, consent.ID); err != nil {
+		return fmt.Errorf("error updating oauth2 consent session subject with id '%d' and challenge id '%s' for subject '%s': %w", consent.ID, consent.ChallengeID, consent.Subject.UUID, err)
+	}
+
+	return nil
+}
+
+// SaveOAuth2ConsentSessionResponse updates an OAuth2.0 consent session in the storage provider with the response.
 func (p *SQLProvider) SaveOAuth2ConsentSessionResponse(ctx context.Context, consent model.OAuth2ConsentSession, authorized bool) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2ConsentSessionResponse, authorized, consent.GrantedScopes, consent.GrantedAudience, consent.PreConfiguration, consent.ID); err != nil {
 		return fmt.Errorf("error updating oauth2 consent session (authorized  '%t') with id '%d' and challenge id '%s' for subject '%s': %w", authorized, consent.ID, consent.ChallengeID, consent.Subject.UUID, err)
@@ -1049,6 +2358,16 @@ func (p *SQLProvider) SaveOAuth2ConsentSessionGranted(ctx context.Context, id in
 	return nil
 }
 
+// DeleteAbandonedOAuth2ConsentSessions deletes up to the limit of the OAuth2.0 consent sessions which were requested
+// before the provided time and never responded to from the storage provider, returning the number of sessions deleted.
+func (p *SQLProvider) DeleteAbandonedOAuth2ConsentSessions(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteAbandonedOAuth2ConsentSessions, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting abandoned oauth2 consent sessions: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // LoadOAuth2ConsentSessionByChallengeID returns an OAuth2.0 consent session in the storage provider given the challenge ID.
 func (p *SQLProvider) LoadOAuth2ConsentSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (consent *model.OAuth2ConsentSession, err error) {
 	consent = &model.OAuth2ConsentSession{}
@@ -1290,6 +2609,248 @@ func (p *SQLProvider) UpdateOAuth2PARContext(ctx context.Context, par model.OAut
 	return nil
 }
 
+// SaveOAuth2DeviceCodeSession saves an OAuth2.0 device code session to the storage provider.
+func (p *SQLProvider) SaveOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error) {
+	if session.Session, err = p.encrypt(session.Session); err != nil {
+		return fmt.Errorf("error encrypting oauth2 device code session data with signature '%s' and request id '%s': %w", session.Signature, session.RequestID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2DeviceCodeSession,
+		session.RequestID, session.ClientID, session.Signature, session.UserCodeSignature, session.Status,
+		session.Subject, session.RequestedAt, session.CheckedAt, session.RequestedScopes, session.GrantedScopes,
+		session.RequestedAudience, session.GrantedAudience, session.Active, session.Revoked, session.Form, session.Session); err != nil {
+		return fmt.Errorf("error inserting oauth2 device code session data with signature '%s' and request id '%s': %w", session.Signature, session.RequestID, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2DeviceCodeSession updates the status, subject, last checked time, grants, and session data of an existing
+// OAuth2.0 device code session in the storage provider.
+func (p *SQLProvider) UpdateOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error) {
+	if session.Session, err = p.encrypt(session.Session); err != nil {
+		return fmt.Errorf("error encrypting oauth2 device code session data with signature '%s' and request id '%s': %w", session.Signature, session.RequestID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2DeviceCodeSession,
+		session.Status, session.Subject, session.CheckedAt, session.GrantedScopes, session.GrantedAudience,
+		session.Session, session.Signature); err != nil {
+		return fmt.Errorf("error updating oauth2 device code session data with signature '%s' and request id '%s': %w", session.Signature, session.RequestID, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2DeviceCodeSession loads an OAuth2.0 device code session from the storage provider given the device code
+// signature.
+func (p *SQLProvider) LoadOAuth2DeviceCodeSession(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error) {
+	session = &model.OAuth2DeviceCodeSession{}
+
+	if err = p.db.GetContext(ctx, session, p.sqlSelectOAuth2DeviceCodeSession, signature); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 device code session with signature '%s': %w", signature, err)
+	}
+
+	if session.Session, err = p.decrypt(session.Session); err != nil {
+		return nil, fmt.Errorf("error decrypting oauth2 device code session data with signature '%s' and request id '%s': %w", signature, session.RequestID, err)
+	}
+
+	return session, nil
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCode loads an OAuth2.0 device code session from the storage provider given the
+// user code signature.
+func (p *SQLProvider) LoadOAuth2DeviceCodeSessionByUserCode(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error) {
+	session = &model.OAuth2DeviceCodeSession{}
+
+	if err = p.db.GetContext(ctx, session, p.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature, signature); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 device code session with user code signature '%s': %w", signature, err)
+	}
+
+	if session.Session, err = p.decrypt(session.Session); err != nil {
+		return nil, fmt.Errorf("error decrypting oauth2 device code session data with user code signature '%s' and request id '%s': %w", signature, session.RequestID, err)
+	}
+
+	return session, nil
+}
+
+// DeactivateOAuth2DeviceCodeSession marks an OAuth2.0 device code session as inactive in the storage provider.
+func (p *SQLProvider) DeactivateOAuth2DeviceCodeSession(ctx context.Context, signature string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeactivateOAuth2DeviceCodeSession, signature); err != nil {
+		return fmt.Errorf("error deactivating oauth2 device code session with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// SaveOAuth2CIBARequest saves an OpenID Connect 1.0 Client Initiated Backchannel Authentication request to the storage
+// provider.
+func (p *SQLProvider) SaveOAuth2CIBARequest(ctx context.Context, request model.OAuth2CIBARequest) (err error) {
+	if request.Session, err = p.encrypt(request.Session); err != nil {
+		return fmt.Errorf("error encrypting oauth2 backchannel authentication request data with signature '%s' and request id '%s': %w", request.Signature, request.RequestID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2CIBARequest,
+		request.RequestID, request.ClientID, request.Signature, request.Status, request.Subject, request.Username,
+		request.TransactionID, request.DeliveryMode, request.Notified, request.RequestedAt, request.ExpiresAt,
+		request.CheckedAt, request.AuthenticatedAt, request.RequestedScopes, request.GrantedScopes,
+		request.RequestedAudience, request.GrantedAudience, request.Active, request.Form, request.Session); err != nil {
+		return fmt.Errorf("error inserting oauth2 backchannel authentication request data with signature '%s' and request id '%s': %w", request.Signature, request.RequestID, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2CIBARequestStatus updates the status of a pending OpenID Connect 1.0 Client Initiated Backchannel
+// Authentication request in the storage provider.
+func (p *SQLProvider) UpdateOAuth2CIBARequestStatus(ctx context.Context, signature string, status int, authenticatedAt sql.NullTime) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2CIBARequestStatus, status, authenticatedAt, signature); err != nil {
+		return fmt.Errorf("error updating oauth2 backchannel authentication request status with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2CIBARequestCheckedAt updates the time the client last polled for an OpenID Connect 1.0 Client Initiated
+// Backchannel Authentication request in the storage provider.
+func (p *SQLProvider) UpdateOAuth2CIBARequestCheckedAt(ctx context.Context, signature string, checkedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2CIBARequestCheckedAt, checkedAt, signature); err != nil {
+		return fmt.Errorf("error updating oauth2 backchannel authentication request checked at time with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2CIBARequestNotified marks the client of an OpenID Connect 1.0 Client Initiated Backchannel Authentication
+// request as notified in the storage provider.
+func (p *SQLProvider) UpdateOAuth2CIBARequestNotified(ctx context.Context, signature string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2CIBARequestNotified, signature); err != nil {
+		return fmt.Errorf("error updating oauth2 backchannel authentication request notified status with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2CIBARequest loads an OpenID Connect 1.0 Client Initiated Backchannel Authentication request from the
+// storage provider given the signature.
+func (p *SQLProvider) LoadOAuth2CIBARequest(ctx context.Context, signature string) (request *model.OAuth2CIBARequest, err error) {
+	request = &model.OAuth2CIBARequest{}
+
+	if err = p.db.GetContext(ctx, request, p.sqlSelectOAuth2CIBARequest, signature); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 backchannel authentication request with signature '%s': %w", signature, err)
+	}
+
+	if request.Session, err = p.decrypt(request.Session); err != nil {
+		return nil, fmt.Errorf("error decrypting oauth2 backchannel authentication request data with signature '%s' and request id '%s': %w", signature, request.RequestID, err)
+	}
+
+	return request, nil
+}
+
+// LoadOAuth2CIBARequestsPending loads up to the limit of the active OpenID Connect 1.0 Client Initiated Backchannel
+// Authentication requests which have not expired and are either pending or have a client which must be notified.
+func (p *SQLProvider) LoadOAuth2CIBARequestsPending(ctx context.Context, now time.Time, limit int) (requests []model.OAuth2CIBARequest, err error) {
+	if err = p.db.SelectContext(ctx, &requests, p.sqlSelectOAuth2CIBARequestsPending, now, limit); err != nil {
+		return nil, fmt.Errorf("error selecting pending oauth2 backchannel authentication requests: %w", err)
+	}
+
+	for i := range requests {
+		if requests[i].Session, err = p.decrypt(requests[i].Session); err != nil {
+			return nil, fmt.Errorf("error decrypting oauth2 backchannel authentication request data with signature '%s' and request id '%s': %w", requests[i].Signature, requests[i].RequestID, err)
+		}
+	}
+
+	return requests, nil
+}
+
+// DeactivateOAuth2CIBARequest marks an OpenID Connect 1.0 Client Initiated Backchannel Authentication request as
+// inactive in the storage provider.
+func (p *SQLProvider) DeactivateOAuth2CIBARequest(ctx context.Context, signature string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeactivateOAuth2CIBARequest, signature); err != nil {
+		return fmt.Errorf("error deactivating oauth2 backchannel authentication request with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredOAuth2CIBARequests deletes up to the limit of the OpenID Connect 1.0 Client Initiated Backchannel
+// Authentication requests which expired before the given time from the storage provider.
+func (p *SQLProvider) DeleteExpiredOAuth2CIBARequests(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredOAuth2CIBARequests, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired oauth2 backchannel authentication requests: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SaveOAuth2TokenExchangeLineage saves the lineage of a token issued via the OAuth2.0 Token Exchange grant to the
+// storage provider.
+func (p *SQLProvider) SaveOAuth2TokenExchangeLineage(ctx context.Context, lineage model.OAuth2TokenExchangeLineage) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2TokenExchangeLineage,
+		lineage.RequestID, lineage.ParentRequestID, lineage.ClientID, lineage.ParentClientID, lineage.Subject, lineage.ExchangedAt); err != nil {
+		return fmt.Errorf("error inserting oauth2 token exchange lineage with request id '%s' and parent request id '%s': %w", lineage.RequestID, lineage.ParentRequestID, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2TokenExchangeLineageByParentRequestID loads the lineage of all tokens which were issued via the OAuth2.0
+// Token Exchange grant from a token with the given request id.
+func (p *SQLProvider) LoadOAuth2TokenExchangeLineageByParentRequestID(ctx context.Context, requestID string) (lineage []model.OAuth2TokenExchangeLineage, err error) {
+	if err = p.db.SelectContext(ctx, &lineage, p.sqlSelectOAuth2TokenExchangeLineageByParentRequestID, requestID); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 token exchange lineage with parent request id '%s': %w", requestID, err)
+	}
+
+	return lineage, nil
+}
+
+// SaveOAuth2DynamicClient saves an OAuth2.0 client registered via the Dynamic Client Registration endpoint to the
+// storage provider.
+func (p *SQLProvider) SaveOAuth2DynamicClient(ctx context.Context, client model.OAuth2DynamicClient) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2DynamicClient,
+		client.ClientID, client.RegistrationAccessTokenSignature, client.ClientSecret, client.Metadata, client.CreatedAt, client.UpdatedAt); err != nil {
+		return fmt.Errorf("error inserting oauth2 dynamic client with client id '%s': %w", client.ClientID, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2DynamicClient updates the registration access token signature, client secret, and metadata of an
+// OAuth2.0 client registered via the Dynamic Client Registration endpoint in the storage provider.
+func (p *SQLProvider) UpdateOAuth2DynamicClient(ctx context.Context, client model.OAuth2DynamicClient) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2DynamicClient,
+		client.RegistrationAccessTokenSignature, client.ClientSecret, client.Metadata, client.UpdatedAt, client.ClientID); err != nil {
+		return fmt.Errorf("error updating oauth2 dynamic client with client id '%s': %w", client.ClientID, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2DynamicClient loads an OAuth2.0 client registered via the Dynamic Client Registration endpoint from the
+// storage provider.
+func (p *SQLProvider) LoadOAuth2DynamicClient(ctx context.Context, clientID string) (client *model.OAuth2DynamicClient, err error) {
+	client = &model.OAuth2DynamicClient{}
+
+	if err = p.db.GetContext(ctx, client, p.sqlSelectOAuth2DynamicClient, clientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("error selecting oauth2 dynamic client with client id '%s': %w", clientID, err)
+	}
+
+	return client, nil
+}
+
+// DeleteOAuth2DynamicClient deletes an OAuth2.0 client registered via the Dynamic Client Registration endpoint from
+// the storage provider.
+func (p *SQLProvider) DeleteOAuth2DynamicClient(ctx context.Context, clientID string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteOAuth2DynamicClient, clientID); err != nil {
+		return fmt.Errorf("error deleting oauth2 dynamic client with client id '%s': %w", clientID, err)
+	}
+
+	return nil
+}
+
 // SaveOAuth2BlacklistedJTI saves an OAuth2.0 blacklisted JTI to the storage provider.
 func (p *SQLProvider) SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertOAuth2BlacklistedJTI, blacklistedJTI.Signature, blacklistedJTI.ExpiresAt); err != nil {
@@ -1316,6 +2877,16 @@ func (p *SQLProvider) LoadOAuth2BlacklistedJTI(ctx context.Context, signature st
 	return blacklistedJTI, nil
 }
 
+// DeleteExpiredOAuth2BlacklistedJTIs deletes up to the limit of the OAuth2.0 blacklisted JTIs which expired before the
+// provided time from the storage provider, returning the number of JTIs deleted.
+func (p *SQLProvider) DeleteExpiredOAuth2BlacklistedJTIs(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteExpiredOAuth2BlacklistedJTIs, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting expired oauth2 blacklisted JTIs: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // AppendAuthenticationLog saves an authentication attempt to the storage provider.
 func (p *SQLProvider) AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertAuthenticationAttempt,
@@ -1331,7 +2902,7 @@ func (p *SQLProvider) AppendAuthenticationLog(ctx context.Context, attempt model
 func (p *SQLProvider) LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error) {
 	attempts = make([]model.AuthenticationAttempt, 0, limit)
 
-	if err = p.db.SelectContext(ctx, &attempts, p.sqlSelectAuthenticationAttemptsByUsername, fromDate, username, limit, limit*page); err != nil {
+	if err = p.readSelectContext(ctx, &attempts, p.sqlSelectAuthenticationAttemptsByUsername, fromDate, username, limit, limit*page); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNoAuthenticationLogs
 		}
@@ -1341,3 +2912,169 @@ func (p *SQLProvider) LoadAuthenticationLogs(ctx context.Context, username strin
 
 	return attempts, nil
 }
+
+// LoadAuthenticationHistory loads a page of the authentication attempts for a user which match the provided filter
+// from the storage provider including the banned attempts and the attempts for every factor, sorted by the time of the
+// attempt.
+func (p *SQLProvider) LoadAuthenticationHistory(ctx context.Context, username string, filter model.AuthenticationAttemptFilter, pagination model.Pagination) (attempts []model.AuthenticationAttempt, err error) {
+	attempts = make([]model.AuthenticationAttempt, 0, pagination.Limit)
+
+	if err = p.readSelectContext(ctx, &attempts, p.sqlSelectAuthenticationHistoryByUsername.get(pagination.Order),
+		username, filter.Type, filter.Type, pagination.Limit, pagination.Offset()); err != nil {
+		return nil, fmt.Errorf("error selecting authentication history for user '%s': %w", username, err)
+	}
+
+	return attempts, nil
+}
+
+// LoadUsageStatistics loads the model.UsageStatistics from the storage provider. The users which have successfully
+// authenticated and the OpenID Connect 1.0 clients which have been authorized since the provided time are considered
+// active.
+func (p *SQLProvider) LoadUsageStatistics(ctx context.Context, since time.Time) (stats model.UsageStatistics, err error) {
+	if err = p.readGetContext(ctx, &stats, p.sqlSelectUsageStatistics, since, since); err != nil {
+		return model.UsageStatistics{}, fmt.Errorf("error selecting usage statistics: %w", err)
+	}
+
+	return stats, nil
+}
+
+// SaveConfigurationFingerprint saves the fingerprint of the effective configuration of an instance to the storage
+// provider.
+func (p *SQLProvider) SaveConfigurationFingerprint(ctx context.Context, fingerprint model.ConfigurationFingerprint) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertConfigurationFingerprint,
+		fingerprint.Instance, fingerprint.Fingerprint, fingerprint.Version, fingerprint.UpdatedAt); err != nil {
+		return fmt.Errorf("error upserting configuration fingerprint for instance '%s': %w", fingerprint.Instance, err)
+	}
+
+	return nil
+}
+
+// LoadConfigurationFingerprints loads the configuration fingerprints of all instances which have been updated since
+// the provided time from the storage provider.
+func (p *SQLProvider) LoadConfigurationFingerprints(ctx context.Context, since time.Time) (fingerprints []model.ConfigurationFingerprint, err error) {
+	if err = p.readSelectContext(ctx, &fingerprints, p.sqlSelectConfigurationFingerprints, since); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting configuration fingerprints: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
+// DeleteConfigurationFingerprints deletes the configuration fingerprints of all instances which have not been updated
+// since the provided time from the storage provider.
+func (p *SQLProvider) DeleteConfigurationFingerprints(ctx context.Context, before time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteConfigurationFingerprints, before); err != nil {
+		return fmt.Errorf("error deleting configuration fingerprints: %w", err)
+	}
+
+	return nil
+}
+
+// SaveAuditEvent saves an audit event to the storage provider.
+func (p *SQLProvider) SaveAuditEvent(ctx context.Context, event model.AuditEvent) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertAuditEvent,
+		event.EventID, event.Time, event.Type, event.Result, event.Username, event.RemoteIP, event.UserAgent,
+		event.RequestMethod, event.RequestHost, event.RequestPath, event.TraceID, event.Details); err != nil {
+		return fmt.Errorf("error inserting audit event '%s' with type '%s': %w", event.EventID, event.Type, err)
+	}
+
+	return nil
+}
+
+// LoadAuditEvents loads a page of the audit events which match the provided filter from the storage provider, sorted
+// by the time of the event.
+func (p *SQLProvider) LoadAuditEvents(ctx context.Context, filter model.AuditEventFilter, pagination model.Pagination) (events []model.AuditEvent, err error) {
+	until := filter.Until
+
+	if until.IsZero() {
+		until = auditEventsUntilMax
+	}
+
+	if err = p.readSelectContext(ctx, &events, p.sqlSelectAuditEvents.get(pagination.Order),
+		filter.Since, until, filter.Username, filter.Username, filter.Type, filter.Type, pagination.Limit, pagination.Offset()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting audit events: %w", err)
+	}
+
+	return events, nil
+}
+
+// DeleteAuditEvents deletes up to the limit of the audit events which were recorded before the provided time from the
+// storage provider, returning the number of events deleted.
+func (p *SQLProvider) DeleteAuditEvents(ctx context.Context, before time.Time, limit int) (deleted int64, err error) {
+	if deleted, err = p.execRowsAffected(ctx, p.sqlDeleteAuditEvents, before, limit); err != nil {
+		return 0, fmt.Errorf("error deleting audit events: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// AcquireLeaderElectionLease acquires or renews the leader election lease with the given name for the holder until the
+// expires time, returning true if the holder holds the lease. The lease can only be acquired if it's not held by another
+// holder or if the lease of the other holder expired before the provided now time.
+func (p *SQLProvider) AcquireLeaderElectionLease(ctx context.Context, name, holder string, now, expires time.Time) (acquired bool, err error) {
+	var affected int64
+
+	if affected, err = p.execRowsAffected(ctx, p.sqlUpdateLeaderElectionLease, holder, expires, name, holder, now); err != nil {
+		return false, fmt.Errorf("error updating leader election lease with name '%s': %w", name, err)
+	}
+
+	if affected != 0 {
+		return true, nil
+	}
+
+	var current string
+
+	switch err = p.db.GetContext(ctx, &current, p.sqlSelectLeaderElectionLease, name); {
+	case err == nil:
+		return current == holder, nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return false, fmt.Errorf("error selecting leader election lease with name '%s': %w", name, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertLeaderElectionLease, name, holder, expires); err == nil {
+		return true, nil
+	}
+
+	// The insert may fail when another holder inserted the lease concurrently, in which case the holder which
+	// successfully inserted the lease holds it.
+	if errs := p.db.GetContext(ctx, &current, p.sqlSelectLeaderElectionLease, name); errs == nil {
+		return current == holder, nil
+	}
+
+	return false, fmt.Errorf("error inserting leader election lease with name '%s': %w", name, err)
+}
+
+// ReleaseLeaderElectionLease releases the leader election lease with the given name if it's held by the holder.
+func (p *SQLProvider) ReleaseLeaderElectionLease(ctx context.Context, name, holder string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteLeaderElectionLease, name, holder); err != nil {
+		return fmt.Errorf("error deleting leader election lease with name '%s': %w", name, err)
+	}
+
+	return nil
+}
+
+// execRowsAffected executes a query returning the number of rows affected by it.
+func (p *SQLProvider) execRowsAffected(ctx context.Context, query string, args ...any) (affected int64, err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, query, args...); err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// SessionSignature returns the signature of a session id which is used to look up the rows which refer to a session,
+// such as the user session index, without storing the session id in the clear.
+func SessionSignature(sessionID []byte) string {
+	sum := sha256.Sum256(sessionID)
+
+	return hex.EncodeToString(sum[:])
+}
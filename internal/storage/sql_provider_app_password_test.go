@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveAndLoadAppPassword(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.otcHMAC = []byte("abc123")
+	provider.sqlInsertAppPassword = fmt.Sprintf(queryFmtInsertAppPassword, tableAppPassword)
+	provider.sqlSelectAppPassword = fmt.Sprintf(queryFmtSelectAppPassword, tableAppPassword)
+	provider.sqlUpdateAppPasswordSignIn = fmt.Sprintf(queryFmtUpdateAppPasswordRecordSignIn, tableAppPassword)
+
+	now := time.Unix(1700000000, 0)
+	publicID := uuid.MustParse("d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")
+	password := model.NewAppPassword(publicID, "john", "Mail", "authelia_ap_abc", []string{"mail.example.com"}, now)
+	signature := provider.appPasswordSignature("john", "authelia_ap_abc")
+
+	assert.Len(t, signature, 128)
+	assert.NotEqual(t, signature, provider.appPasswordSignature("fred", "authelia_ap_abc"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertAppPassword)).
+		WithArgs(publicID, now, "john", "Mail", password.Domains, signature).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveAppPassword(context.Background(), password))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertAppPassword)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.SaveAppPassword(context.Background(), password), "error inserting app password with name 'Mail' for user 'john': bad connection")
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAppPassword)).
+		WithArgs("john", signature).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "created_at", "last_used_at", "username", "name", "domains", "signature"}).
+			AddRow(1, publicID, now, nil, "john", "Mail", "mail.example.com|*.example.org", signature))
+
+	actual, err := provider.LoadAppPassword(context.Background(), "john", "authelia_ap_abc")
+
+	require.NoError(t, err)
+	require.NotNil(t, actual)
+	assert.Equal(t, 1, actual.ID)
+	assert.Equal(t, publicID, actual.PublicID)
+	assert.Equal(t, "Mail", actual.Name)
+	assert.Equal(t, model.StringSlicePipeDelimited{"mail.example.com", "*.example.org"}, actual.Domains)
+	assert.False(t, actual.LastUsedAt.Valid)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAppPassword)).
+		WithArgs("john", signature).
+		WillReturnError(sql.ErrNoRows)
+
+	actual, err = provider.LoadAppPassword(context.Background(), "john", "authelia_ap_abc")
+
+	assert.Nil(t, actual)
+	assert.ErrorIs(t, err, ErrNoAppPassword)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAppPassword)).
+		WillReturnError(errors.New("bad connection"))
+
+	actual, err = provider.LoadAppPassword(context.Background(), "john", "authelia_ap_abc")
+
+	assert.Nil(t, actual)
+	assert.EqualError(t, err, "error selecting app password for user 'john': bad connection")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateAppPasswordSignIn)).
+		WithArgs(sql.NullTime{Time: now, Valid: true}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateAppPasswordSignIn(context.Background(), 1, sql.NullTime{Time: now, Valid: true}))
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadAndDeleteAppPasswords(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectAppPasswordsByUsername = fmt.Sprintf(queryFmtSelectAppPasswordsByUsername, tableAppPassword)
+	provider.sqlDeleteAppPassword = fmt.Sprintf(queryFmtDeleteAppPassword, tableAppPassword)
+
+	now := time.Unix(1700000000, 0)
+	publicID := uuid.MustParse("d4b3c2a1-1234-4f6e-8a9b-0c1d2e3f4a5b")
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAppPasswordsByUsername)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "public_id", "created_at", "last_used_at", "username", "name", "domains", "signature"}).
+			AddRow(1, publicID, now, now, "john", "Mail", "mail.example.com", "abc"))
+
+	passwords, err := provider.LoadAppPasswords(context.Background(), "john")
+
+	require.NoError(t, err)
+	require.Len(t, passwords, 1)
+	assert.Equal(t, "Mail", passwords[0].Name)
+	assert.True(t, passwords[0].LastUsedAt.Valid)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAppPasswordsByUsername)).
+		WillReturnError(errors.New("bad connection"))
+
+	passwords, err = provider.LoadAppPasswords(context.Background(), "john")
+
+	assert.Nil(t, passwords)
+	assert.EqualError(t, err, "error selecting app passwords for user 'john': bad connection")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteAppPassword)).
+		WithArgs("john", publicID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeleteAppPassword(context.Background(), "john", publicID))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteAppPassword)).
+		WithArgs("john", publicID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.DeleteAppPassword(context.Background(), "john", publicID), ErrNoAppPassword)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveAuditEvent(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertAuditEvent = fmt.Sprintf(queryFmtInsertAuditEvent, tableAuditEvents)
+
+	now := time.Unix(1700000000, 0)
+
+	event := model.AuditEvent{
+		EventID:  "6bdf2a43-5cb4-4f41-a43b-0a9f3d1e3c2b",
+		Time:     now,
+		Type:     "authentication.first_factor",
+		Result:   "success",
+		Username: "john",
+		RemoteIP: "192.168.0.1",
+		Details:  `{"method":"password"}`,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertAuditEvent)).
+		WithArgs(event.EventID, now, "authentication.first_factor", "success", "john", "192.168.0.1", "", "", "", "", "", `{"method":"password"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveAuditEvent(context.Background(), event))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertAuditEvent)).WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.SaveAuditEvent(context.Background(), event), "error inserting audit event '6bdf2a43-5cb4-4f41-a43b-0a9f3d1e3c2b' with type 'authentication.first_factor': table is locked")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadAuditEvents(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectAuditEvents = newSQLSortedQuery(queryFmtSelectAuditEvents, tableAuditEvents)
+
+	now := time.Unix(1700000000, 0)
+	since := now.Add(-time.Hour)
+
+	columns := []string{"id", "event_id", "time", "type", "result", "username", "remote_ip", "user_agent", "request_method", "request_host", "request_path", "trace_id", "details"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAuditEvents.descending)).
+		WithArgs(since, now, "john", "john", "", "", 10, 20).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(2, "b", now, "totp.register", "success", "john", "192.168.0.1", "curl", "POST", "auth.example.com", "/api/secondfactor/totp/register", "", "{}").
+			AddRow(1, "a", since, "authentication.first_factor", "failure", "john", "192.168.0.1", "curl", "POST", "auth.example.com", "/api/firstfactor", "", "{}"))
+
+	events, err := provider.LoadAuditEvents(context.Background(), model.AuditEventFilter{Username: "john", Since: since, Until: now}, model.Pagination{Limit: 10, Page: 2})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, 2, events[0].ID)
+	assert.Equal(t, "totp.register", events[0].Type)
+	assert.Equal(t, "authentication.first_factor", events[1].Type)
+	assert.Equal(t, "failure", events[1].Result)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAuditEvents.ascending)).
+		WithArgs(time.Time{}, auditEventsUntilMax, "", "", "password.reset", "password.reset", 50, 0).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	events, err = provider.LoadAuditEvents(context.Background(), model.AuditEventFilter{Type: "password.reset"}, model.Pagination{Limit: 50, Order: model.SortOrderAscending})
+	assert.NoError(t, err)
+	assert.Len(t, events, 0)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectAuditEvents.descending)).WillReturnError(errors.New("bad connection"))
+
+	events, err = provider.LoadAuditEvents(context.Background(), model.AuditEventFilter{}, model.Pagination{Limit: 50})
+	assert.EqualError(t, err, "error selecting audit events: bad connection")
+	assert.Nil(t, events)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteAuditEvents(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteAuditEvents = fmt.Sprintf(queryFmtDeleteAuditEvents, tableAuditEvents)
+
+	before := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteAuditEvents)).
+		WithArgs(before, 100).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	deleted, err := provider.DeleteAuditEvents(context.Background(), before, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), deleted)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteAuditEvents)).WillReturnError(errors.New("bad connection"))
+
+	deleted, err = provider.DeleteAuditEvents(context.Background(), before, 100)
+	assert.EqualError(t, err, "error deleting audit events: bad connection")
+	assert.Equal(t, int64(0), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"crypto/x509"
+	"regexp"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// CockroachDBProvider is a CockroachDB provider.
+type CockroachDBProvider struct {
+	SQLProvider
+}
+
+// NewCockroachDBProvider a CockroachDB provider.
+func NewCockroachDBProvider(config *schema.Configuration, caCertPool *x509.CertPool) (provider *CockroachDBProvider) {
+	provider = &CockroachDBProvider{
+		SQLProvider: NewSQLProvider(config, providerCockroachDB, "pgx", dsnCockroachDB(config.Storage.CockroachDB, caCertPool)),
+	}
+
+	for _, address := range config.Storage.CockroachDB.Replicas {
+		replica := *config.Storage.CockroachDB
+
+		replica.Address = address
+		replica.TLS = replicaTLS(config.Storage.CockroachDB.TLS, config.Storage.CockroachDB.Address, address)
+
+		provider.addReplica(address.String(), dsnCockroachDB(&replica, caCertPool))
+	}
+
+	// CockroachDB uses the PostgreSQL wire protocol and dialect.
+	setPostgreSQLQueries(&provider.SQLProvider)
+
+	provider.schema = config.Storage.CockroachDB.Schema
+
+	// CockroachDB always uses the SERIALIZABLE isolation level so transactions which conflict with each other must be
+	// retried by the client.
+	provider.retries = config.Storage.CockroachDB.MaximumRetries
+
+	return provider
+}
+
+func dsnCockroachDB(config *schema.StorageCockroachDB, globalCACertPool *x509.CertPool) (dsn string) {
+	return dsnPostgreSQL(&schema.StoragePostgreSQL{
+		StorageSQL: config.StorageSQL,
+		Schema:     config.Schema,
+		TLS:        config.TLS,
+	}, globalCACertPool)
+}
+
+var (
+	reCockroachDBAlterTableDropConstraints = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(?P<Table>\S+)\s+(?P<Clauses>DROP\s+CONSTRAINT\s.*)$`)
+	reCockroachDBDropConstraint            = regexp.MustCompile(`(?is)^DROP\s+CONSTRAINT\s+(IF\s+EXISTS\s+)?(?P<Name>\S+)$`)
+	reCockroachDBIndexConstraint           = regexp.MustCompile(`_(pkey|key)\d*$`)
+	reCockroachDBDropIndex                 = regexp.MustCompile(`(?is)^DROP\s+INDEX\s+IF\s+EXISTS\s+\S+$`)
+)
+
+// cockroachDBMigrationQuery adapts a PostgreSQL migration query to the differences CockroachDB has:
+//  1. Unique constraints are indexes which can't be dropped with ALTER TABLE, the PostgreSQL migrations already drop
+//     the index in a following statement so the constraint is skipped and the index is dropped with CASCADE instead.
+//  2. Every table requires a primary key, and as the primary key names are only unique to the table they don't need
+//     to be dropped before the table is copied to the backup and recreated.
+func cockroachDBMigrationQuery(query string) string {
+	statements := strings.Split(query, ";")
+
+	adapted := make([]string, 0, len(statements))
+
+	for _, statement := range statements {
+		trimmed := strings.TrimSpace(statement)
+
+		switch {
+		case trimmed == "":
+			continue
+		case reCockroachDBAlterTableDropConstraints.MatchString(trimmed):
+			if trimmed = cockroachDBMigrationAlterTableDropConstraints(trimmed); trimmed == "" {
+				continue
+			}
+		case reCockroachDBDropIndex.MatchString(trimmed):
+			trimmed += " CASCADE"
+		}
+
+		adapted = append(adapted, trimmed+";")
+	}
+
+	return strings.Join(adapted, "\n\n")
+}
+
+func cockroachDBMigrationAlterTableDropConstraints(statement string) string {
+	result := reCockroachDBAlterTableDropConstraints.FindStringSubmatch(statement)
+
+	clauses := strings.Split(result[reCockroachDBAlterTableDropConstraints.SubexpIndex("Clauses")], ",")
+
+	kept := make([]string, 0, len(clauses))
+
+	for _, clause := range clauses {
+		clause = strings.TrimSpace(clause)
+
+		match := reCockroachDBDropConstraint.FindStringSubmatch(clause)
+
+		if match == nil {
+			// The statement has a clause other than dropping a constraint so it's left as is.
+			return statement
+		}
+
+		if reCockroachDBIndexConstraint.MatchString(match[reCockroachDBDropConstraint.SubexpIndex("Name")]) {
+			continue
+		}
+
+		kept = append(kept, clause)
+	}
+
+	if len(kept) == 0 {
+		return ""
+	}
+
+	return "ALTER TABLE " + result[reCockroachDBAlterTableDropConstraints.SubexpIndex("Table")] + "\n    " + strings.Join(kept, ",\n    ")
+}
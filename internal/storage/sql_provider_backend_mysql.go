@@ -1,12 +1,16 @@
 package storage
 
 import (
+	"context"
 	"crypto/x509"
+	"database/sql/driver"
+	"fmt"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/secrets"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -17,8 +21,29 @@ type MySQLProvider struct {
 
 // NewMySQLProvider a MySQL provider.
 func NewMySQLProvider(config *schema.Configuration, caCertPool *x509.CertPool) (provider *MySQLProvider) {
-	provider = &MySQLProvider{
-		SQLProvider: NewSQLProvider(config, providerMySQL, providerMySQL, dsnMySQL(config.Storage.MySQL, caCertPool)),
+	if config.Storage.MySQL.IAMAuthentication.Provider == "" {
+		provider = &MySQLProvider{
+			SQLProvider: NewSQLProvider(config, providerMySQL, providerMySQL, dsnMySQL(config.Storage.MySQL, caCertPool, "storage")),
+		}
+	} else {
+		provider = &MySQLProvider{
+			SQLProvider: NewSQLProviderWithConnector(config, providerMySQL, providerMySQL, connectorMySQL(config.Storage.MySQL, caCertPool, "storage")),
+		}
+	}
+
+	for i, address := range config.Storage.MySQL.Replicas {
+		replica := *config.Storage.MySQL
+
+		replica.Address = address
+		replica.TLS = replicaTLS(config.Storage.MySQL.TLS, config.Storage.MySQL.Address, address)
+
+		name := fmt.Sprintf("storage-replica-%d", i+1)
+
+		if replica.IAMAuthentication.Provider == "" {
+			provider.addReplica(address.String(), dsnMySQL(&replica, caCertPool, name))
+		} else {
+			provider.addReplicaConnector(address.String(), connectorMySQL(&replica, caCertPool, name))
+		}
 	}
 
 	// All providers have differing SELECT existing table statements.
@@ -30,16 +55,51 @@ func NewMySQLProvider(config *schema.Configuration, caCertPool *x509.CertPool) (
 	return provider
 }
 
-func dsnMySQL(config *schema.StorageMySQL, caCertPool *x509.CertPool) (dataSourceName string) {
-	dsnConfig := mysql.NewConfig()
+func dsnMySQL(config *schema.StorageMySQL, caCertPool *x509.CertPool, tlsConfigName string) (dataSourceName string) {
+	return configMySQL(config, caCertPool, tlsConfigName).FormatDSN()
+}
+
+// connectorMySQL returns a driver.Connector which uses a new IAM authentication token as the password of each
+// connection. The tokens are sent in cleartext which is only permitted as TLS is required.
+func connectorMySQL(config *schema.StorageMySQL, caCertPool *x509.CertPool, tlsConfigName string) (connector driver.Connector) {
+	var (
+		tokens secrets.TokenSource
+		err    error
+	)
+
+	if tokens, err = newSQLTokenSource(&config.IAMAuthentication, config.Address, config.Username); err != nil {
+		return &sqlErrorConnector{err: err}
+	}
+
+	dsnConfig := configMySQL(config, caCertPool, tlsConfigName)
+
+	dsnConfig.AllowCleartextPasswords = true
+
+	if err = dsnConfig.Apply(mysql.BeforeConnect(func(ctx context.Context, c *mysql.Config) (err error) {
+		c.Passwd, err = tokens.Token(ctx)
+
+		return err
+	})); err != nil {
+		return &sqlErrorConnector{err: err}
+	}
+
+	if connector, err = mysql.NewConnector(dsnConfig); err != nil {
+		return &sqlErrorConnector{err: err}
+	}
+
+	return connector
+}
+
+func configMySQL(config *schema.StorageMySQL, caCertPool *x509.CertPool, tlsConfigName string) (dsnConfig *mysql.Config) {
+	dsnConfig = mysql.NewConfig()
 
 	dsnConfig.Net = config.Address.Network()
 	dsnConfig.Addr = config.Address.NetworkAddress()
 
 	if config.TLS != nil {
-		_ = mysql.RegisterTLSConfig("storage", utils.NewTLSConfig(config.TLS, caCertPool))
+		_ = mysql.RegisterTLSConfig(tlsConfigName, utils.NewTLSConfig(config.TLS, caCertPool))
 
-		dsnConfig.TLSConfig = "storage"
+		dsnConfig.TLSConfig = tlsConfigName
 	}
 
 	dsnConfig.DBName = config.Database
@@ -50,5 +110,5 @@ func dsnMySQL(config *schema.StorageMySQL, caCertPool *x509.CertPool) (dataSourc
 	dsnConfig.ParseTime = true
 	dsnConfig.Loc = time.Local
 
-	return dsnConfig.FormatDSN()
+	return dsnConfig
 }
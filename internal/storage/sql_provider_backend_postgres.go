@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"os"
@@ -21,10 +23,39 @@ type PostgreSQLProvider struct {
 
 // NewPostgreSQLProvider a PostgreSQL provider.
 func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPool) (provider *PostgreSQLProvider) {
-	provider = &PostgreSQLProvider{
-		SQLProvider: NewSQLProvider(config, providerPostgres, "pgx", dsnPostgreSQL(config.Storage.PostgreSQL, caCertPool)),
+	if config.Storage.PostgreSQL.IAMAuthentication.Provider == "" {
+		provider = &PostgreSQLProvider{
+			SQLProvider: NewSQLProvider(config, providerPostgres, "pgx", dsnPostgreSQL(config.Storage.PostgreSQL, caCertPool)),
+		}
+	} else {
+		provider = &PostgreSQLProvider{
+			SQLProvider: NewSQLProviderWithConnector(config, providerPostgres, "pgx", connectorPostgreSQL(config.Storage.PostgreSQL, caCertPool)),
+		}
+	}
+
+	for _, address := range config.Storage.PostgreSQL.Replicas {
+		replica := *config.Storage.PostgreSQL
+
+		replica.Address = address
+		replica.TLS = replicaTLS(config.Storage.PostgreSQL.TLS, config.Storage.PostgreSQL.Address, address)
+
+		if replica.IAMAuthentication.Provider == "" {
+			provider.addReplica(address.String(), dsnPostgreSQL(&replica, caCertPool))
+		} else {
+			provider.addReplicaConnector(address.String(), connectorPostgreSQL(&replica, caCertPool))
+		}
 	}
 
+	setPostgreSQLQueries(&provider.SQLProvider)
+
+	provider.schema = config.Storage.PostgreSQL.Schema
+
+	return provider
+}
+
+// setPostgreSQLQueries alters the queries of the SQLProvider for the PostgreSQL dialect which is shared by all
+// PostgreSQL compatible providers.
+func setPostgreSQLQueries(provider *SQLProvider) {
 	// All providers have differing SELECT existing table statements.
 	provider.sqlSelectExistingTables = queryPostgreSelectExistingTables
 
@@ -32,16 +63,21 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	// PostgreSQL doesn't have a UPSERT statement but has an ON CONFLICT operation instead.
 	provider.sqlUpsertDuoDevice = fmt.Sprintf(queryFmtUpsertDuoDevicePostgreSQL, tableDuoDevices)
 	provider.sqlUpsertTOTPConfig = fmt.Sprintf(queryFmtUpsertTOTPConfigurationPostgreSQL, tableTOTPConfigurations)
+	provider.sqlUpsertPhoneNumber = fmt.Sprintf(queryFmtUpsertPhoneNumberPostgreSQL, tablePhoneNumber)
+	provider.sqlUpsertSession = fmt.Sprintf(queryFmtUpsertSessionPostgreSQL, tableSessions)
 	provider.sqlUpsertPreferred2FAMethod = fmt.Sprintf(queryFmtUpsertPreferred2FAMethodPostgreSQL, tableUserPreferences)
 	provider.sqlUpsertEncryptionValue = fmt.Sprintf(queryFmtUpsertEncryptionValuePostgreSQL, tableEncryption)
 	provider.sqlUpsertOAuth2BlacklistedJTI = fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL, tableOAuth2BlacklistedJTI)
 	provider.sqlInsertOAuth2ConsentPreConfiguration = fmt.Sprintf(queryFmtInsertOAuth2ConsentPreConfigurationPostgreSQL, tableOAuth2ConsentPreConfiguration)
+	provider.sqlUpsertConfigurationFingerprint = fmt.Sprintf(queryFmtUpsertConfigurationFingerprintPostgreSQL, tableConfigurationFingerprints)
 
 	// PostgreSQL requires rebinding of any query that contains a '?' placeholder to use the '$#' notation placeholders.
 	provider.sqlFmtRenameTable = provider.db.Rebind(provider.sqlFmtRenameTable)
 
 	provider.sqlSelectPreferred2FAMethod = provider.db.Rebind(provider.sqlSelectPreferred2FAMethod)
 	provider.sqlSelectUserInfo = provider.db.Rebind(provider.sqlSelectUserInfo)
+	provider.sqlSelectUserSummaries = provider.db.Rebind(provider.sqlSelectUserSummaries)
+	provider.sqlSelectUsageStatistics = provider.db.Rebind(provider.sqlSelectUsageStatistics)
 
 	provider.sqlInsertUserOpaqueIdentifier = provider.db.Rebind(provider.sqlInsertUserOpaqueIdentifier)
 	provider.sqlSelectUserOpaqueIdentifier = provider.db.Rebind(provider.sqlSelectUserOpaqueIdentifier)
@@ -50,7 +86,9 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlInsertIdentityVerification = provider.db.Rebind(provider.sqlInsertIdentityVerification)
 	provider.sqlConsumeIdentityVerification = provider.db.Rebind(provider.sqlConsumeIdentityVerification)
 	provider.sqlRevokeIdentityVerification = provider.db.Rebind(provider.sqlRevokeIdentityVerification)
+	provider.sqlDeleteExpiredIdentityVerifications = provider.db.Rebind(provider.sqlDeleteExpiredIdentityVerifications)
 	provider.sqlSelectIdentityVerification = provider.db.Rebind(provider.sqlSelectIdentityVerification)
+	provider.sqlSelectCountIdentityVerifications = provider.db.Rebind(provider.sqlSelectCountIdentityVerifications)
 
 	provider.sqlInsertOneTimeCode = provider.db.Rebind(provider.sqlInsertOneTimeCode)
 	provider.sqlConsumeOneTimeCode = provider.db.Rebind(provider.sqlConsumeOneTimeCode)
@@ -59,18 +97,25 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectOneTimeCodeBySignature = provider.db.Rebind(provider.sqlSelectOneTimeCodeBySignature)
 	provider.sqlSelectOneTimeCodeByID = provider.db.Rebind(provider.sqlSelectOneTimeCodeByID)
 	provider.sqlSelectOneTimeCodeByPublicID = provider.db.Rebind(provider.sqlSelectOneTimeCodeByPublicID)
+	provider.sqlSelectCountOneTimeCodes = provider.db.Rebind(provider.sqlSelectCountOneTimeCodes)
 
 	provider.sqlSelectTOTPConfig = provider.db.Rebind(provider.sqlSelectTOTPConfig)
 	provider.sqlUpdateTOTPConfigRecordSignIn = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignIn)
+	provider.sqlUpdateTOTPConfigDrift = provider.db.Rebind(provider.sqlUpdateTOTPConfigDrift)
 	provider.sqlUpdateTOTPConfigRecordSignInByUsername = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignInByUsername)
 	provider.sqlDeleteTOTPConfig = provider.db.Rebind(provider.sqlDeleteTOTPConfig)
 	provider.sqlSelectTOTPConfigs = provider.db.Rebind(provider.sqlSelectTOTPConfigs)
 
+	provider.sqlSelectPhoneNumber = provider.db.Rebind(provider.sqlSelectPhoneNumber)
+	provider.sqlUpdatePhoneNumberRecordSignIn = provider.db.Rebind(provider.sqlUpdatePhoneNumberRecordSignIn)
+	provider.sqlDeletePhoneNumber = provider.db.Rebind(provider.sqlDeletePhoneNumber)
+
 	provider.sqlInsertTOTPHistory = provider.db.Rebind(provider.sqlInsertTOTPHistory)
 	provider.sqlSelectTOTPHistory = provider.db.Rebind(provider.sqlSelectTOTPHistory)
 
 	provider.sqlInsertWebAuthnUser = provider.db.Rebind(provider.sqlInsertWebAuthnUser)
 	provider.sqlSelectWebAuthnUser = provider.db.Rebind(provider.sqlSelectWebAuthnUser)
+	provider.sqlSelectWebAuthnUserByUserID = provider.db.Rebind(provider.sqlSelectWebAuthnUserByUserID)
 
 	provider.sqlInsertWebAuthnCredential = provider.db.Rebind(provider.sqlInsertWebAuthnCredential)
 	provider.sqlSelectWebAuthnCredentials = provider.db.Rebind(provider.sqlSelectWebAuthnCredentials)
@@ -86,10 +131,85 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectDuoDevice = provider.db.Rebind(provider.sqlSelectDuoDevice)
 	provider.sqlDeleteDuoDevice = provider.db.Rebind(provider.sqlDeleteDuoDevice)
 
+	provider.sqlInsertDeviceCertificate = provider.db.Rebind(provider.sqlInsertDeviceCertificate)
+	provider.sqlSelectDeviceCertificate = provider.db.Rebind(provider.sqlSelectDeviceCertificate)
+	provider.sqlSelectDeviceCertificatesByUsername = provider.sqlSelectDeviceCertificatesByUsername.rebind(provider.db)
+	provider.sqlRevokeDeviceCertificate = provider.db.Rebind(provider.sqlRevokeDeviceCertificate)
+	provider.sqlDeleteExpiredDeviceCertificates = provider.db.Rebind(provider.sqlDeleteExpiredDeviceCertificates)
+
+	provider.sqlInsertUserSessionIndex = provider.db.Rebind(provider.sqlInsertUserSessionIndex)
+	provider.sqlSelectUserSessionIndex = provider.db.Rebind(provider.sqlSelectUserSessionIndex)
+	provider.sqlSelectUserSessionIndexByUsername = provider.db.Rebind(provider.sqlSelectUserSessionIndexByUsername)
+	provider.sqlDeleteUserSessionIndex = provider.db.Rebind(provider.sqlDeleteUserSessionIndex)
+
+	provider.sqlInsertUserSessionRevocation = provider.db.Rebind(provider.sqlInsertUserSessionRevocation)
+	provider.sqlSelectUserSessionRevocations = provider.db.Rebind(provider.sqlSelectUserSessionRevocations)
+	provider.sqlDeleteExpiredUserSessionRevocations = provider.db.Rebind(provider.sqlDeleteExpiredUserSessionRevocations)
+
+	provider.sqlInsertAppPassword = provider.db.Rebind(provider.sqlInsertAppPassword)
+	provider.sqlSelectAppPassword = provider.db.Rebind(provider.sqlSelectAppPassword)
+	provider.sqlSelectAppPasswordsByUsername = provider.db.Rebind(provider.sqlSelectAppPasswordsByUsername)
+	provider.sqlUpdateAppPasswordSignIn = provider.db.Rebind(provider.sqlUpdateAppPasswordSignIn)
+	provider.sqlDeleteAppPassword = provider.db.Rebind(provider.sqlDeleteAppPassword)
+
+	provider.sqlSelectSession = provider.db.Rebind(provider.sqlSelectSession)
+	provider.sqlUpdateSessionSignature = provider.db.Rebind(provider.sqlUpdateSessionSignature)
+	provider.sqlDeleteSession = provider.db.Rebind(provider.sqlDeleteSession)
+	provider.sqlSelectCountSessions = provider.db.Rebind(provider.sqlSelectCountSessions)
+	provider.sqlDeleteExpiredSessions = provider.db.Rebind(provider.sqlDeleteExpiredSessions)
+
+	provider.sqlInsertDirectoryUser = provider.db.Rebind(provider.sqlInsertDirectoryUser)
+	provider.sqlUpdateDirectoryUser = provider.db.Rebind(provider.sqlUpdateDirectoryUser)
+	provider.sqlSelectDirectoryUser = provider.db.Rebind(provider.sqlSelectDirectoryUser)
+	provider.sqlSelectDirectoryUsers = provider.db.Rebind(provider.sqlSelectDirectoryUsers)
+	provider.sqlSelectDirectoryUsersCount = provider.db.Rebind(provider.sqlSelectDirectoryUsersCount)
+	provider.sqlSelectDirectoryUserPassword = provider.db.Rebind(provider.sqlSelectDirectoryUserPassword)
+	provider.sqlUpdateDirectoryUserPassword = provider.db.Rebind(provider.sqlUpdateDirectoryUserPassword)
+	provider.sqlDeleteDirectoryUser = provider.db.Rebind(provider.sqlDeleteDirectoryUser)
+	provider.sqlSelectDirectoryUserGroups = provider.db.Rebind(provider.sqlSelectDirectoryUserGroups)
+	provider.sqlInsertDirectoryGroup = provider.db.Rebind(provider.sqlInsertDirectoryGroup)
+	provider.sqlUpdateDirectoryGroup = provider.db.Rebind(provider.sqlUpdateDirectoryGroup)
+	provider.sqlSelectDirectoryGroup = provider.db.Rebind(provider.sqlSelectDirectoryGroup)
+	provider.sqlSelectDirectoryGroups = provider.db.Rebind(provider.sqlSelectDirectoryGroups)
+	provider.sqlSelectDirectoryGroupsCount = provider.db.Rebind(provider.sqlSelectDirectoryGroupsCount)
+	provider.sqlDeleteDirectoryGroup = provider.db.Rebind(provider.sqlDeleteDirectoryGroup)
+	provider.sqlInsertDirectoryGroupMember = provider.db.Rebind(provider.sqlInsertDirectoryGroupMember)
+	provider.sqlSelectDirectoryGroupMembers = provider.db.Rebind(provider.sqlSelectDirectoryGroupMembers)
+	provider.sqlDeleteDirectoryGroupMembersByGroup = provider.db.Rebind(provider.sqlDeleteDirectoryGroupMembersByGroup)
+	provider.sqlDeleteDirectoryGroupMembersByUser = provider.db.Rebind(provider.sqlDeleteDirectoryGroupMembersByUser)
+
+	provider.sqlInsertRecoveryCode = provider.db.Rebind(provider.sqlInsertRecoveryCode)
+	provider.sqlConsumeRecoveryCode = provider.db.Rebind(provider.sqlConsumeRecoveryCode)
+	provider.sqlSelectRecoveryCodesCount = provider.db.Rebind(provider.sqlSelectRecoveryCodesCount)
+	provider.sqlDeleteRecoveryCodes = provider.db.Rebind(provider.sqlDeleteRecoveryCodes)
+
+	provider.sqlInsertPushChallenge = provider.db.Rebind(provider.sqlInsertPushChallenge)
+	provider.sqlSelectPushChallenge = provider.db.Rebind(provider.sqlSelectPushChallenge)
+	provider.sqlUpdatePushChallengeResponse = provider.db.Rebind(provider.sqlUpdatePushChallengeResponse)
+	provider.sqlDeleteExpiredPushChallenges = provider.db.Rebind(provider.sqlDeleteExpiredPushChallenges)
+
+	provider.sqlInsertMobileDevice = provider.db.Rebind(provider.sqlInsertMobileDevice)
+	provider.sqlSelectMobileDevice = provider.db.Rebind(provider.sqlSelectMobileDevice)
+	provider.sqlSelectMobileDeviceByAccessSignature = provider.db.Rebind(provider.sqlSelectMobileDeviceByAccessSignature)
+	provider.sqlUpdateMobileDeviceTokens = provider.db.Rebind(provider.sqlUpdateMobileDeviceTokens)
+	provider.sqlSelectMobileDevicesPush = provider.db.Rebind(provider.sqlSelectMobileDevicesPush)
+	provider.sqlUpdateMobileDevicePush = provider.db.Rebind(provider.sqlUpdateMobileDevicePush)
+	provider.sqlRevokeMobileDevice = provider.db.Rebind(provider.sqlRevokeMobileDevice)
+	provider.sqlDeleteExpiredMobileDevices = provider.db.Rebind(provider.sqlDeleteExpiredMobileDevices)
+
+	provider.sqlInsertSAMLSession = provider.db.Rebind(provider.sqlInsertSAMLSession)
+	provider.sqlSelectSAMLSession = provider.db.Rebind(provider.sqlSelectSAMLSession)
+	provider.sqlRevokeSAMLSession = provider.db.Rebind(provider.sqlRevokeSAMLSession)
+	provider.sqlRevokeSAMLSessionsByUsername = provider.db.Rebind(provider.sqlRevokeSAMLSessionsByUsername)
+	provider.sqlDeleteExpiredSAMLSessions = provider.db.Rebind(provider.sqlDeleteExpiredSAMLSessions)
+
 	provider.sqlInsertAuthenticationAttempt = provider.db.Rebind(provider.sqlInsertAuthenticationAttempt)
 	provider.sqlSelectAuthenticationAttemptsByUsername = provider.db.Rebind(provider.sqlSelectAuthenticationAttemptsByUsername)
+	provider.sqlSelectLatestSignInByUsername = provider.db.Rebind(provider.sqlSelectLatestSignInByUsername)
+	provider.sqlSelectAuthenticationHistoryByUsername = provider.sqlSelectAuthenticationHistoryByUsername.rebind(provider.db)
 
 	provider.sqlInsertMigration = provider.db.Rebind(provider.sqlInsertMigration)
+	provider.sqlInsertMigrationWithCompatible = provider.db.Rebind(provider.sqlInsertMigrationWithCompatible)
 	provider.sqlSelectMigrations = provider.db.Rebind(provider.sqlSelectMigrations)
 	provider.sqlSelectLatestMigration = provider.db.Rebind(provider.sqlSelectLatestMigration)
 
@@ -101,7 +221,9 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlUpdateOAuth2ConsentSessionSubject = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionSubject)
 	provider.sqlUpdateOAuth2ConsentSessionResponse = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionResponse)
 	provider.sqlUpdateOAuth2ConsentSessionGranted = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionGranted)
+	provider.sqlDeleteAbandonedOAuth2ConsentSessions = provider.db.Rebind(provider.sqlDeleteAbandonedOAuth2ConsentSessions)
 	provider.sqlSelectOAuth2ConsentSessionByChallengeID = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionByChallengeID)
+	provider.sqlSelectOAuth2ConsentSessionsByUsername = provider.sqlSelectOAuth2ConsentSessionsByUsername.rebind(provider.db)
 
 	provider.sqlInsertOAuth2AccessTokenSession = provider.db.Rebind(provider.sqlInsertOAuth2AccessTokenSession)
 	provider.sqlRevokeOAuth2AccessTokenSession = provider.db.Rebind(provider.sqlRevokeOAuth2AccessTokenSession)
@@ -129,6 +251,29 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlRevokeOAuth2PARContext = provider.db.Rebind(provider.sqlRevokeOAuth2PARContext)
 	provider.sqlSelectOAuth2PARContext = provider.db.Rebind(provider.sqlSelectOAuth2PARContext)
 
+	provider.sqlInsertOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlInsertOAuth2DeviceCodeSession)
+	provider.sqlUpdateOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlUpdateOAuth2DeviceCodeSession)
+	provider.sqlSelectOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlSelectOAuth2DeviceCodeSession)
+	provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature = provider.db.Rebind(provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature)
+	provider.sqlDeactivateOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlDeactivateOAuth2DeviceCodeSession)
+
+	provider.sqlInsertOAuth2CIBARequest = provider.db.Rebind(provider.sqlInsertOAuth2CIBARequest)
+	provider.sqlUpdateOAuth2CIBARequestStatus = provider.db.Rebind(provider.sqlUpdateOAuth2CIBARequestStatus)
+	provider.sqlUpdateOAuth2CIBARequestCheckedAt = provider.db.Rebind(provider.sqlUpdateOAuth2CIBARequestCheckedAt)
+	provider.sqlUpdateOAuth2CIBARequestNotified = provider.db.Rebind(provider.sqlUpdateOAuth2CIBARequestNotified)
+	provider.sqlSelectOAuth2CIBARequest = provider.db.Rebind(provider.sqlSelectOAuth2CIBARequest)
+	provider.sqlSelectOAuth2CIBARequestsPending = provider.db.Rebind(provider.sqlSelectOAuth2CIBARequestsPending)
+	provider.sqlDeactivateOAuth2CIBARequest = provider.db.Rebind(provider.sqlDeactivateOAuth2CIBARequest)
+	provider.sqlDeleteExpiredOAuth2CIBARequests = provider.db.Rebind(provider.sqlDeleteExpiredOAuth2CIBARequests)
+
+	provider.sqlInsertOAuth2TokenExchangeLineage = provider.db.Rebind(provider.sqlInsertOAuth2TokenExchangeLineage)
+	provider.sqlSelectOAuth2TokenExchangeLineageByParentRequestID = provider.db.Rebind(provider.sqlSelectOAuth2TokenExchangeLineageByParentRequestID)
+
+	provider.sqlInsertOAuth2DynamicClient = provider.db.Rebind(provider.sqlInsertOAuth2DynamicClient)
+	provider.sqlUpdateOAuth2DynamicClient = provider.db.Rebind(provider.sqlUpdateOAuth2DynamicClient)
+	provider.sqlSelectOAuth2DynamicClient = provider.db.Rebind(provider.sqlSelectOAuth2DynamicClient)
+	provider.sqlDeleteOAuth2DynamicClient = provider.db.Rebind(provider.sqlDeleteOAuth2DynamicClient)
+
 	provider.sqlInsertOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlInsertOAuth2PKCERequestSession)
 	provider.sqlRevokeOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlRevokeOAuth2PKCERequestSession)
 	provider.sqlRevokeOAuth2PKCERequestSessionByRequestID = provider.db.Rebind(provider.sqlRevokeOAuth2PKCERequestSessionByRequestID)
@@ -144,14 +289,42 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlSelectOAuth2RefreshTokenSession)
 
 	provider.sqlSelectOAuth2BlacklistedJTI = provider.db.Rebind(provider.sqlSelectOAuth2BlacklistedJTI)
+	provider.sqlDeleteExpiredOAuth2BlacklistedJTIs = provider.db.Rebind(provider.sqlDeleteExpiredOAuth2BlacklistedJTIs)
 
-	provider.schema = config.Storage.PostgreSQL.Schema
+	provider.sqlSelectConfigurationFingerprints = provider.db.Rebind(provider.sqlSelectConfigurationFingerprints)
+	provider.sqlDeleteConfigurationFingerprints = provider.db.Rebind(provider.sqlDeleteConfigurationFingerprints)
 
-	return provider
+	provider.sqlInsertAuditEvent = provider.db.Rebind(provider.sqlInsertAuditEvent)
+	provider.sqlSelectAuditEvents = provider.sqlSelectAuditEvents.rebind(provider.db)
+	provider.sqlDeleteAuditEvents = provider.db.Rebind(provider.sqlDeleteAuditEvents)
+
+	provider.sqlUpdateLeaderElectionLease = provider.db.Rebind(provider.sqlUpdateLeaderElectionLease)
+	provider.sqlSelectLeaderElectionLease = provider.db.Rebind(provider.sqlSelectLeaderElectionLease)
+	provider.sqlInsertLeaderElectionLease = provider.db.Rebind(provider.sqlInsertLeaderElectionLease)
+	provider.sqlDeleteLeaderElectionLease = provider.db.Rebind(provider.sqlDeleteLeaderElectionLease)
 }
 
 func dsnPostgreSQL(config *schema.StoragePostgreSQL, globalCACertPool *x509.CertPool) (dsn string) {
-	dsnConfig, _ := pgx.ParseConfig("")
+	return stdlib.RegisterConnConfig(configPostgreSQL(config, globalCACertPool))
+}
+
+// connectorPostgreSQL returns a driver.Connector which uses a new IAM authentication token as the password of each
+// connection.
+func connectorPostgreSQL(config *schema.StoragePostgreSQL, globalCACertPool *x509.CertPool) (connector driver.Connector) {
+	tokens, err := newSQLTokenSource(&config.IAMAuthentication, config.Address, config.Username)
+	if err != nil {
+		return &sqlErrorConnector{err: err}
+	}
+
+	return stdlib.GetConnector(*configPostgreSQL(config, globalCACertPool), stdlib.OptionBeforeConnect(func(ctx context.Context, c *pgx.ConnConfig) (err error) {
+		c.Password, err = tokens.Token(ctx)
+
+		return err
+	}))
+}
+
+func configPostgreSQL(config *schema.StoragePostgreSQL, globalCACertPool *x509.CertPool) (dsnConfig *pgx.ConnConfig) {
+	dsnConfig, _ = pgx.ParseConfig("")
 
 	dsnConfig.Host = config.Address.SocketHostname()
 	dsnConfig.Port = uint16(config.Address.Port())
@@ -168,7 +341,7 @@ func dsnPostgreSQL(config *schema.StoragePostgreSQL, globalCACertPool *x509.Cert
 		dsnConfig.Port = 5432
 	}
 
-	return stdlib.RegisterConnConfig(dsnConfig)
+	return dsnConfig
 }
 
 func loadPostgreSQLTLSConfig(config *schema.StoragePostgreSQL, globalCACertPool *x509.CertPool) (tlsConfig *tls.Config) {
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/authelia/authelia/v4/internal/breaker"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// sqlOpen opens the database in the same way as sqlx.Open except the new connections to the database are guarded by a
+// circuit breaker when it's enabled for the storage, so the callers fail fast instead of each waiting for a connection
+// to a database which is unavailable.
+func sqlOpen(config *schema.Configuration, name, driverName, dataSourceName string) (db *sqlx.DB, err error) {
+	if db, err = sqlx.Open(driverName, dataSourceName); err != nil {
+		return db, err
+	}
+
+	b := sqlBreaker(config, name)
+
+	if b == nil {
+		return db, nil
+	}
+
+	// The database is only used to resolve the driver as the database opened with the connector replaces it.
+	connector, err := newSQLConnector(db.Driver(), dataSourceName)
+
+	_ = db.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlx.NewDb(sql.OpenDB(&sqlBreakerConnector{connector: connector, breaker: b}), driverName), nil
+}
+
+// sqlOpenConnector opens the database using the connector in the same way as sqlOpen.
+func sqlOpenConnector(config *schema.Configuration, name, driverName string, connector driver.Connector) (db *sqlx.DB) {
+	if b := sqlBreaker(config, name); b != nil {
+		connector = &sqlBreakerConnector{connector: connector, breaker: b}
+	}
+
+	return sqlx.NewDb(sql.OpenDB(connector), driverName)
+}
+
+func sqlBreaker(config *schema.Configuration, name string) (b *breaker.Breaker) {
+	if config == nil {
+		return nil
+	}
+
+	return breaker.New(&config.CircuitBreaker, schema.CircuitBreakerDependencyStorage, name, clock.New(), nil)
+}
+
+func newSQLConnector(d driver.Driver, dataSourceName string) (connector driver.Connector, err error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		return dc.OpenConnector(dataSourceName)
+	}
+
+	return &sqlDSNConnector{driver: d, dataSourceName: dataSourceName}, nil
+}
+
+// sqlBreakerConnector is a driver.Connector which guards the new connections of another driver.Connector with a
+// circuit breaker.
+type sqlBreakerConnector struct {
+	connector driver.Connector
+	breaker   *breaker.Breaker
+}
+
+// Connect implements driver.Connector.
+func (c *sqlBreakerConnector) Connect(ctx context.Context) (conn driver.Conn, err error) {
+	err = c.breaker.Do(ctx, func(ctx context.Context) (err error) {
+		conn, err = c.connector.Connect(ctx)
+
+		return err
+	})
+
+	return conn, err
+}
+
+// Driver implements driver.Connector.
+func (c *sqlBreakerConnector) Driver() driver.Driver {
+	return c.connector.Driver()
+}
+
+// sqlDSNConnector is a driver.Connector for the drivers which don't implement driver.DriverContext.
+type sqlDSNConnector struct {
+	driver         driver.Driver
+	dataSourceName string
+}
+
+// Connect implements driver.Connector.
+func (c *sqlDSNConnector) Connect(_ context.Context) (conn driver.Conn, err error) {
+	return c.driver.Open(c.dataSourceName)
+}
+
+// Driver implements driver.Connector.
+func (c *sqlDSNConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/breaker"
+	"github.com/authelia/authelia/v4/internal/clock"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestSQLOpen(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config *schema.Configuration
+	}{
+		{
+			"ShouldOpenWithoutConfiguration",
+			nil,
+		},
+		{
+			"ShouldOpenWhenDisabled",
+			&schema.Configuration{CircuitBreaker: schema.CircuitBreaker{Dependencies: []string{schema.CircuitBreakerDependencyStorage}}},
+		},
+		{
+			"ShouldOpenWhenNotConfiguredDependency",
+			&schema.Configuration{CircuitBreaker: schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencyLDAP}}},
+		},
+		{
+			"ShouldOpenGuarded",
+			&schema.Configuration{CircuitBreaker: schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencyStorage}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, err := sqlOpen(tc.config, "storage", "sqlite3e", ":memory:")
+
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			assert.NoError(t, db.PingContext(context.Background()))
+		})
+	}
+}
+
+func TestSQLBreakerConnector(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	c := clock.NewFixed(now)
+
+	connector := &testSQLConnector{err: errors.New("connection refused")}
+
+	b := breaker.New(&schema.CircuitBreaker{Enabled: true, Dependencies: []string{schema.CircuitBreakerDependencyStorage}, FailureThreshold: 2, OpenDuration: time.Second * 30, ProbeTimeout: time.Second}, schema.CircuitBreakerDependencyStorage, "storage", c, nil)
+
+	guarded := &sqlBreakerConnector{connector: connector, breaker: b}
+
+	for i := 0; i < 2; i++ {
+		_, err := guarded.Connect(context.Background())
+
+		assert.EqualError(t, err, "connection refused")
+	}
+
+	_, err := guarded.Connect(context.Background())
+
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.Equal(t, 2, connector.calls)
+
+	c.Set(now.Add(time.Second * 30))
+
+	connector.err = nil
+
+	_, err = guarded.Connect(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, connector.calls)
+	assert.Equal(t, breaker.StateClosed, b.State())
+	assert.Nil(t, guarded.Driver())
+}
+
+type testSQLConnector struct {
+	err   error
+	calls int
+}
+
+func (c *testSQLConnector) Connect(_ context.Context) (conn driver.Conn, err error) {
+	c.calls++
+
+	return nil, c.err
+}
+
+func (c *testSQLConnector) Driver() driver.Driver {
+	return nil
+}
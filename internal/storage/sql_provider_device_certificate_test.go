@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveDeviceCertificate(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertDeviceCertificate = fmt.Sprintf(queryFmtInsertDeviceCertificate, tableDeviceCertificate)
+
+	issued := time.Unix(1700000000, 0)
+	expires := issued.Add(time.Hour * 24)
+
+	certificate := model.DeviceCertificate{
+		Serial:    "4f2a",
+		Username:  "john",
+		Name:      "laptop",
+		IssuedAt:  issued,
+		ExpiresAt: expires,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDeviceCertificate)).
+		WithArgs("4f2a", "john", "laptop", issued, expires).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveDeviceCertificate(context.Background(), certificate))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDeviceCertificate)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveDeviceCertificate(context.Background(), certificate), "error inserting device certificate with serial '4f2a' for user 'john': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadDeviceCertificates(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectDeviceCertificate = fmt.Sprintf(queryFmtSelectDeviceCertificate, tableDeviceCertificate)
+	provider.sqlSelectDeviceCertificatesByUsername = newSQLSortedQuery(queryFmtSelectDeviceCertificatesByUsername, tableDeviceCertificate)
+
+	issued := time.Unix(1700000000, 0)
+	expires := issued.Add(time.Hour * 24)
+
+	columns := []string{"id", "serial", "username", "name", "issued_at", "expires_at", "revoked_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDeviceCertificate)).
+		WithArgs("4f2a").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "4f2a", "john", "laptop", issued, expires, nil))
+
+	certificate, err := provider.LoadDeviceCertificate(context.Background(), "4f2a")
+
+	assert.NoError(t, err)
+	require.NotNil(t, certificate)
+	assert.Equal(t, "john", certificate.Username)
+	assert.Equal(t, "laptop", certificate.Name)
+	assert.False(t, certificate.IsRevoked())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDeviceCertificate)).
+		WithArgs("ffff").
+		WillReturnError(sql.ErrNoRows)
+
+	certificate, err = provider.LoadDeviceCertificate(context.Background(), "ffff")
+
+	assert.ErrorIs(t, err, ErrNoDeviceCertificate)
+	assert.Nil(t, certificate)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDeviceCertificatesByUsername.descending)).
+		WithArgs("john", 20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "4f2a", "john", "laptop", issued, expires, nil).
+			AddRow(2, "5b3c", "john", "phone", issued, expires, issued.Add(time.Hour)))
+
+	certificates, err := provider.LoadDeviceCertificates(context.Background(), "john", model.Pagination{Limit: 20})
+
+	assert.NoError(t, err)
+	require.Len(t, certificates, 2)
+	assert.Equal(t, "4f2a", certificates[0].Serial)
+	assert.Equal(t, "phone", certificates[1].Name)
+	assert.True(t, certificates[1].IsRevoked())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDeviceCertificatesByUsername.ascending)).
+		WithArgs("jane", 10, 10).
+		WillReturnError(errors.New("bad connection"))
+
+	certificates, err = provider.LoadDeviceCertificates(context.Background(), "jane", model.Pagination{Limit: 10, Page: 1, Order: model.SortOrderAscending})
+
+	assert.EqualError(t, err, "error selecting device certificates for user 'jane': bad connection")
+	assert.Nil(t, certificates)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRevokeAndDeleteDeviceCertificates(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlRevokeDeviceCertificate = fmt.Sprintf(queryFmtRevokeDeviceCertificate, tableDeviceCertificate)
+	provider.sqlDeleteExpiredDeviceCertificates = fmt.Sprintf(queryFmtDeleteExpiredDeviceCertificates, tableDeviceCertificate)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeDeviceCertificate)).
+		WithArgs(now, "john", "4f2a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.RevokeDeviceCertificate(context.Background(), "john", "4f2a", now))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeDeviceCertificate)).
+		WithArgs(now, "jane", "4f2a").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.RevokeDeviceCertificate(context.Background(), "jane", "4f2a", now), ErrNoDeviceCertificate)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeDeviceCertificate)).
+		WithArgs(now, "john", "5b3c").
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.RevokeDeviceCertificate(context.Background(), "john", "5b3c", now), "error revoking device certificate with serial '5b3c' for user 'john': table is locked")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredDeviceCertificates)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := provider.DeleteExpiredDeviceCertificates(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
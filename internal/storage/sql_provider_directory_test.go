@@ -0,0 +1,343 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveDirectoryUser(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertDirectoryUser = fmt.Sprintf(queryFmtInsertDirectoryUser, tableDirectoryUsers)
+	provider.sqlUpdateDirectoryUser = fmt.Sprintf(queryFmtUpdateDirectoryUser, tableDirectoryUsers)
+
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+	created := time.Unix(1700000000, 0)
+
+	user := model.DirectoryUser{
+		PublicID:    publicID,
+		ExternalID:  sql.NullString{String: "00u1", Valid: true},
+		Username:    "john",
+		DisplayName: "John Smith",
+		GivenName:   "John",
+		FamilyName:  "Smith",
+		Emails:      model.StringSlicePipeDelimited{"john@example.com"},
+		Active:      true,
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryUser)).
+		WithArgs(publicID.String(), "00u1", "john", "John Smith", "John", "Smith", "john@example.com", true, created, created).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveDirectoryUser(context.Background(), user))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryUser)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveDirectoryUser(context.Background(), user), "error inserting directory user 'john' with id '0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90': duplicate key")
+
+	user.Active = false
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateDirectoryUser)).
+		WithArgs("00u1", "john", "John Smith", "John", "Smith", "john@example.com", false, created, publicID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateDirectoryUser(context.Background(), user))
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadDirectoryUsers(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectDirectoryUser = fmt.Sprintf(queryFmtSelectDirectoryUser, tableDirectoryUsers)
+	provider.sqlSelectDirectoryUsers = fmt.Sprintf(queryFmtSelectDirectoryUsers, tableDirectoryUsers)
+	provider.sqlSelectDirectoryUsersCount = fmt.Sprintf(queryFmtSelectDirectoryUsersCount, tableDirectoryUsers)
+
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+	created := time.Unix(1700000000, 0)
+
+	columns := []string{"id", "public_id", "external_id", "username", "display_name", "given_name", "family_name", "emails", "active", "created_at", "updated_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUser)).
+		WithArgs(publicID.String()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), nil, "john", "John Smith", "John", "Smith", "john@example.com|jsmith@example.com", true, created, created))
+
+	user, err := provider.LoadDirectoryUser(context.Background(), publicID)
+
+	assert.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "john", user.Username)
+	assert.False(t, user.ExternalID.Valid)
+	assert.Equal(t, model.StringSlicePipeDelimited{"john@example.com", "jsmith@example.com"}, user.Emails)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUser)).
+		WithArgs(publicID.String()).
+		WillReturnError(sql.ErrNoRows)
+
+	user, err = provider.LoadDirectoryUser(context.Background(), publicID)
+
+	assert.ErrorIs(t, err, ErrNoDirectoryUser)
+	assert.Nil(t, user)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUsers)).
+		WithArgs("john", "john", "", "", 10, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), "00u1", "john", "John Smith", "John", "Smith", "john@example.com", true, created, created))
+
+	users, err := provider.LoadDirectoryUsers(context.Background(), model.DirectoryUserFilter{Username: "john"}, 10, 0)
+
+	assert.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "00u1", users[0].ExternalID.String)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUsersCount)).
+		WithArgs("", "", "00u1", "00u1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := provider.CountDirectoryUsers(context.Background(), model.DirectoryUserFilter{ExternalID: "00u1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUsers)).
+		WillReturnError(errors.New("bad connection"))
+
+	users, err = provider.LoadDirectoryUsers(context.Background(), model.DirectoryUserFilter{}, 10, 0)
+
+	assert.EqualError(t, err, "error selecting directory users: bad connection")
+	assert.Nil(t, users)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteDirectoryUser(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteDirectoryUser = fmt.Sprintf(queryFmtDeleteDirectoryUser, tableDirectoryUsers)
+	provider.sqlDeleteDirectoryGroupMembersByUser = fmt.Sprintf(queryFmtDeleteDirectoryGroupMembersByUser, tableDirectoryMembers, tableDirectoryUsers)
+
+	publicID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByUser)).
+		WithArgs(publicID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryUser)).
+		WithArgs(publicID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectCommit()
+
+	assert.NoError(t, provider.DeleteDirectoryUser(context.Background(), publicID))
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByUser)).
+		WithArgs(publicID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryUser)).
+		WithArgs(publicID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	primary.ExpectRollback()
+
+	assert.ErrorIs(t, provider.DeleteDirectoryUser(context.Background(), publicID), ErrNoDirectoryUser)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldSaveAndLoadDirectoryGroups(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertDirectoryGroup = fmt.Sprintf(queryFmtInsertDirectoryGroup, tableDirectoryGroups)
+	provider.sqlSelectDirectoryGroup = fmt.Sprintf(queryFmtSelectDirectoryGroup, tableDirectoryGroups)
+	provider.sqlSelectDirectoryGroups = fmt.Sprintf(queryFmtSelectDirectoryGroups, tableDirectoryGroups)
+	provider.sqlSelectDirectoryUserGroups = fmt.Sprintf(queryFmtSelectDirectoryUserGroups, tableDirectoryGroups, tableDirectoryMembers, tableDirectoryUsers)
+
+	publicID := uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21")
+	created := time.Unix(1700000000, 0)
+
+	group := model.DirectoryGroup{
+		PublicID:    publicID,
+		DisplayName: "admins",
+		CreatedAt:   created,
+		UpdatedAt:   created,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryGroup)).
+		WithArgs(publicID.String(), nil, "admins", created, created).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveDirectoryGroup(context.Background(), group))
+
+	columns := []string{"id", "public_id", "external_id", "display_name", "created_at", "updated_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryGroup)).
+		WithArgs(publicID.String()).
+		WillReturnError(sql.ErrNoRows)
+
+	loaded, err := provider.LoadDirectoryGroup(context.Background(), publicID)
+
+	assert.ErrorIs(t, err, ErrNoDirectoryGroup)
+	assert.Nil(t, loaded)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryGroups)).
+		WithArgs("admins", "admins", "", "", 5, 5).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), nil, "admins", created, created))
+
+	groups, err := provider.LoadDirectoryGroups(context.Background(), model.DirectoryGroupFilter{DisplayName: "admins"}, 5, 5)
+
+	assert.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, publicID, groups[0].PublicID)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUserGroups)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), nil, "admins", created, created))
+
+	groups, err = provider.LoadDirectoryUserGroups(context.Background(), "john")
+
+	assert.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "admins", groups[0].DisplayName)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldSaveDirectoryGroupMembers(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertDirectoryGroupMember = fmt.Sprintf(queryFmtInsertDirectoryGroupMember, tableDirectoryMembers, tableDirectoryGroups, tableDirectoryUsers)
+	provider.sqlSelectDirectoryGroupMembers = fmt.Sprintf(queryFmtSelectDirectoryGroupMembers, tableDirectoryUsers, tableDirectoryMembers, tableDirectoryGroups)
+	provider.sqlDeleteDirectoryGroupMembersByGroup = fmt.Sprintf(queryFmtDeleteDirectoryGroupMembersByGroup, tableDirectoryMembers, tableDirectoryGroups)
+
+	groupID := uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21")
+	userID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByGroup)).
+		WithArgs(groupID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryGroupMember)).
+		WithArgs(groupID.String(), userID.String()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	primary.ExpectCommit()
+
+	assert.NoError(t, provider.SaveDirectoryGroupMembers(context.Background(), groupID, []uuid.UUID{userID}))
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByGroup)).
+		WithArgs(groupID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryGroupMember)).
+		WithArgs(groupID.String(), userID.String()).
+		WillReturnError(errors.New("bad connection"))
+	primary.ExpectRollback()
+
+	assert.EqualError(t, provider.SaveDirectoryGroupMembers(context.Background(), groupID, []uuid.UUID{userID}), "rollback due to error: error inserting member with id '0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90' of directory group with id '7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21': bad connection")
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryGroupMembers)).
+		WithArgs(groupID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"public_id", "username"}).
+			AddRow(userID.String(), "john"))
+
+	members, err := provider.LoadDirectoryGroupMembers(context.Background(), groupID)
+
+	assert.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, model.DirectoryGroupMember{PublicID: userID, Username: "john"}, members[0])
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldSaveDirectoryUserGroups(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertDirectoryGroupMember = fmt.Sprintf(queryFmtInsertDirectoryGroupMember, tableDirectoryMembers, tableDirectoryGroups, tableDirectoryUsers)
+	provider.sqlDeleteDirectoryGroupMembersByUser = fmt.Sprintf(queryFmtDeleteDirectoryGroupMembersByUser, tableDirectoryMembers, tableDirectoryUsers)
+
+	groupID := uuid.MustParse("7c1f0a52-2e4d-4f3b-8a64-1d9e5b7c3a21")
+	userID := uuid.MustParse("0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90")
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByUser)).
+		WithArgs(userID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertDirectoryGroupMember)).
+		WithArgs(groupID.String(), userID.String()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	primary.ExpectCommit()
+
+	assert.NoError(t, provider.SaveDirectoryUserGroups(context.Background(), userID, []uuid.UUID{groupID}))
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteDirectoryGroupMembersByUser)).
+		WithArgs(userID.String()).
+		WillReturnError(errors.New("bad connection"))
+	primary.ExpectRollback()
+
+	assert.EqualError(t, provider.SaveDirectoryUserGroups(context.Background(), userID, []uuid.UUID{groupID}), "rollback due to error: error deleting group memberships of directory user with id '0b3d7e8c-3f1a-4b8e-9c51-6b5a2f4e1d90': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadAndUpdateDirectoryUserPassword(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectDirectoryUserPassword = fmt.Sprintf(queryFmtSelectDirectoryUserPassword, tableDirectoryUsers)
+	provider.sqlUpdateDirectoryUserPassword = fmt.Sprintf(queryFmtUpdateDirectoryUserPassword, tableDirectoryUsers)
+
+	digest := "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA"
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUserPassword)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow(digest))
+
+	password, err := provider.LoadDirectoryUserPassword(context.Background(), "john")
+
+	assert.NoError(t, err)
+	assert.Equal(t, digest, password)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectDirectoryUserPassword)).
+		WithArgs("jane").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = provider.LoadDirectoryUserPassword(context.Background(), "jane")
+
+	assert.ErrorIs(t, err, ErrNoDirectoryUser)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateDirectoryUserPassword)).
+		WithArgs(digest, sqlmock.AnyArg(), "john").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateDirectoryUserPassword(context.Background(), "john", digest))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateDirectoryUserPassword)).
+		WithArgs(digest, sqlmock.AnyArg(), "jane").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.UpdateDirectoryUserPassword(context.Background(), "jane", digest), ErrNoDirectoryUser)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateDirectoryUserPassword)).
+		WithArgs(digest, sqlmock.AnyArg(), "john").
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.UpdateDirectoryUserPassword(context.Background(), "john", digest), "error updating password of directory user 'john': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
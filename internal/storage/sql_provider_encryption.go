@@ -30,15 +30,12 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, rawKey stri
 		return fmt.Errorf("error changing the storage encryption key: %w", err)
 	}
 
-	tx, err := p.db.Beginx()
-	if err != nil {
-		return fmt.Errorf("error beginning transaction to change encryption key: %w", err)
-	}
-
 	encChangeFuncs := []EncryptionChangeKeyFunc{
 		schemaEncryptionChangeKeyOneTimeCode,
 		schemaEncryptionChangeKeyTOTP,
 		schemaEncryptionChangeKeyWebAuthn,
+		schemaEncryptionChangeKeyUserSessionIndex,
+		schemaEncryptionChangeKeyPhoneNumber,
 	}
 
 	for i := 0; true; i++ {
@@ -53,17 +50,137 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, rawKey stri
 
 	encChangeFuncs = append(encChangeFuncs, schemaEncryptionChangeKeyEncryption)
 
-	for _, encChangeFunc := range encChangeFuncs {
-		if err = encChangeFunc(ctx, p, tx, key); err != nil {
-			if rerr := tx.Rollback(); rerr != nil {
-				return fmt.Errorf("rollback error %v: rollback due to error: %w", rerr, err)
+	return p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+		for _, encChangeFunc := range encChangeFuncs {
+			if err = encChangeFunc(ctx, p, tx, key); err != nil {
+				return err
 			}
+		}
+
+		return nil
+	})
+}
+
+// SchemaEncryptionRotateKey uses the configured previous keys to decrypt the values in the storage provider which have
+// not been encrypted with the configured key yet and encrypts them again with the configured key. Each batch of rows is
+// updated using its own transaction and each row is only updated if it's unchanged since it was selected, so it's safe
+// to rotate the key while the storage provider is in use by other instances configured with the same keys.
+func (p *SQLProvider) SchemaEncryptionRotateKey(ctx context.Context, batchSize int) (result EncryptionRotateKeyResult, err error) {
+	if len(p.keys.previous) == 0 {
+		return result, fmt.Errorf("error rotating the storage encryption key: no previous encryption keys are configured")
+	}
+
+	if batchSize < 1 {
+		return result, fmt.Errorf("error rotating the storage encryption key: the batch size must be 1 or more but it's %d", batchSize)
+	}
 
-			return fmt.Errorf("rollback due to error: %w", err)
+	targets := append([]encRotateKeyTarget{}, encryptionRotateKeyTargets...)
+
+	for i := 0; true; i++ {
+		typeOAuth2Session := OAuth2SessionType(i)
+
+		if typeOAuth2Session.Table() == "" {
+			break
 		}
+
+		targets = append(targets, encRotateKeyTarget{table: typeOAuth2Session.Table(), column: "session_data"})
 	}
 
-	return tx.Commit()
+	// The encryption values are rotated last so the check value only matches the encryption key once all other values
+	// have been rotated.
+	targets = append(targets, encRotateKeyTarget{table: tableEncryption, column: "value"})
+
+	result.Tables = map[string]EncryptionRotateKeyTableResult{}
+
+	for _, target := range targets {
+		var tableResult EncryptionRotateKeyTableResult
+
+		if tableResult, err = p.schemaEncryptionRotateKeyTable(ctx, target, batchSize); err != nil {
+			return result, fmt.Errorf("error rotating the storage encryption key: %w", err)
+		}
+
+		result.Tables[target.table] = tableResult
+	}
+
+	return result, nil
+}
+
+func (p *SQLProvider) schemaEncryptionRotateKeyTable(ctx context.Context, target encRotateKeyTarget, batchSize int) (result EncryptionRotateKeyTableResult, err error) {
+	var (
+		querySelect = p.db.Rebind(fmt.Sprintf(queryFmtSelectEncryptedDataBatch, target.table, target.column))
+		queryUpdate = p.db.Rebind(fmt.Sprintf(queryFmtUpdateEncryptedDataRotate, target.table, target.column))
+
+		last int
+	)
+
+	for {
+		var batch EncryptionRotateKeyTableResult
+
+		next := last
+
+		if err = p.transaction(ctx, func(tx *sqlx.Tx) (err error) {
+			batch, next = EncryptionRotateKeyTableResult{}, last
+
+			rows := make([]encEncryption, 0, batchSize)
+
+			if err = tx.SelectContext(ctx, &rows, querySelect, last, batchSize); err != nil {
+				return fmt.Errorf("error selecting the encrypted values from the '%s' table: %w", target.table, err)
+			}
+
+			for _, row := range rows {
+				batch.Total++
+
+				next = row.ID
+
+				var (
+					clearText, cipherText []byte
+					current               bool
+					res                   sql.Result
+					affected              int64
+				)
+
+				if clearText, current, err = p.decryptKeys(row.Value); err != nil {
+					return fmt.Errorf("error decrypting the value with id '%d' from the '%s' table: %w", row.ID, target.table, err)
+				}
+
+				if current {
+					continue
+				}
+
+				if cipherText, err = p.encrypt(clearText); err != nil {
+					return fmt.Errorf("error encrypting the value with id '%d' from the '%s' table: %w", row.ID, target.table, err)
+				}
+
+				if res, err = tx.ExecContext(ctx, queryUpdate, cipherText, row.ID, row.Value); err != nil {
+					return fmt.Errorf("error updating the value with id '%d' in the '%s' table: %w", row.ID, target.table, err)
+				}
+
+				if affected, err = res.RowsAffected(); err != nil {
+					return fmt.Errorf("error updating the value with id '%d' in the '%s' table: %w", row.ID, target.table, err)
+				}
+
+				if affected == 0 {
+					batch.Skipped++
+				} else {
+					batch.Rotated++
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return result, err
+		}
+
+		result.Total += batch.Total
+		result.Rotated += batch.Rotated
+		result.Skipped += batch.Skipped
+
+		if batch.Total < batchSize {
+			return result, nil
+		}
+
+		last = next
+	}
 }
 
 // SchemaEncryptionCheckKey checks the encryption key configured is valid for the database.
@@ -90,6 +207,8 @@ func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool
 			schemaEncryptionCheckKeyOneTimeCode,
 			schemaEncryptionCheckKeyTOTP,
 			schemaEncryptionCheckKeyWebAuthn,
+			schemaEncryptionCheckKeyUserSessionIndex,
+			schemaEncryptionCheckKeyPhoneNumber,
 		}
 
 		for i := 0; true; i++ {
@@ -194,6 +313,46 @@ func schemaEncryptionChangeKeyTOTP(ctx context.Context, provider *SQLProvider, t
 	return nil
 }
 
+func schemaEncryptionChangeKeyPhoneNumber(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
+	var count int
+
+	if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, tablePhoneNumber)); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	numbers := make([]encPhoneNumber, 0, count)
+
+	if err = tx.SelectContext(ctx, &numbers, fmt.Sprintf(queryFmtSelectPhoneNumbersEncryptedData, tablePhoneNumber)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("error selecting phone numbers: %w", err)
+	}
+
+	query := provider.db.Rebind(fmt.Sprintf(queryFmtUpdatePhoneNumberEncryptedData, tablePhoneNumber))
+
+	for _, n := range numbers {
+		if n.Number, err = provider.decrypt(n.Number); err != nil {
+			return fmt.Errorf("error decrypting phone number with id '%d': %w", n.ID, err)
+		}
+
+		if n.Number, err = utils.Encrypt(n.Number, &key); err != nil {
+			return fmt.Errorf("error encrypting phone number with id '%d': %w", n.ID, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, n.Number, n.ID); err != nil {
+			return fmt.Errorf("error updating phone number with id '%d': %w", n.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func schemaEncryptionChangeKeyWebAuthn(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
 	var count int
 
@@ -234,6 +393,46 @@ func schemaEncryptionChangeKeyWebAuthn(ctx context.Context, provider *SQLProvide
 	return nil
 }
 
+func schemaEncryptionChangeKeyUserSessionIndex(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
+	var count int
+
+	if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, tableUserSessionIndex)); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	indexes := make([]encUserSessionIndex, 0, count)
+
+	if err = tx.SelectContext(ctx, &indexes, fmt.Sprintf(queryFmtSelectUserSessionIndexEncryptedData, tableUserSessionIndex)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("error selecting user session indexes: %w", err)
+	}
+
+	query := provider.db.Rebind(fmt.Sprintf(queryFmtUpdateUserSessionIndexEncryptedData, tableUserSessionIndex))
+
+	for _, d := range indexes {
+		if d.SessionID, err = provider.decrypt(d.SessionID); err != nil {
+			return fmt.Errorf("error decrypting user session index session id with id '%d': %w", d.ID, err)
+		}
+
+		if d.SessionID, err = utils.Encrypt(d.SessionID, &key); err != nil {
+			return fmt.Errorf("error encrypting user session index session id with id '%d': %w", d.ID, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, d.SessionID, d.ID); err != nil {
+			return fmt.Errorf("error updating user session index session id with id '%d': %w", d.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func schemaEncryptionChangeKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType) EncryptionChangeKeyFunc {
 	return func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
 		var count int
@@ -374,6 +573,37 @@ func schemaEncryptionCheckKeyTOTP(ctx context.Context, provider *SQLProvider) (t
 	return tableTOTPConfigurations, result
 }
 
+func schemaEncryptionCheckKeyPhoneNumber(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
+	var (
+		rows *sqlx.Rows
+		err  error
+	)
+
+	if rows, err = provider.db.QueryxContext(ctx, fmt.Sprintf(queryFmtSelectPhoneNumbersEncryptedData, tablePhoneNumber)); err != nil {
+		return tablePhoneNumber, EncryptionValidationTableResult{Error: fmt.Errorf("error selecting phone numbers: %w", err)}
+	}
+
+	var number encPhoneNumber
+
+	for rows.Next() {
+		result.Total++
+
+		if err = rows.StructScan(&number); err != nil {
+			_ = rows.Close()
+
+			return tablePhoneNumber, EncryptionValidationTableResult{Error: fmt.Errorf("error scanning phone number to struct: %w", err)}
+		}
+
+		if _, err = provider.decrypt(number.Number); err != nil {
+			result.Invalid++
+		}
+	}
+
+	_ = rows.Close()
+
+	return tablePhoneNumber, result
+}
+
 func schemaEncryptionCheckKeyWebAuthn(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
 	var (
 		rows *sqlx.Rows
@@ -405,6 +635,37 @@ func schemaEncryptionCheckKeyWebAuthn(ctx context.Context, provider *SQLProvider
 	return tableWebAuthnCredentials, result
 }
 
+func schemaEncryptionCheckKeyUserSessionIndex(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
+	var (
+		rows *sqlx.Rows
+		err  error
+	)
+
+	if rows, err = provider.db.QueryxContext(ctx, fmt.Sprintf(queryFmtSelectUserSessionIndexEncryptedData, tableUserSessionIndex)); err != nil {
+		return tableUserSessionIndex, EncryptionValidationTableResult{Error: fmt.Errorf("error selecting user session indexes: %w", err)}
+	}
+
+	var index encUserSessionIndex
+
+	for rows.Next() {
+		result.Total++
+
+		if err = rows.StructScan(&index); err != nil {
+			_ = rows.Close()
+
+			return tableUserSessionIndex, EncryptionValidationTableResult{Error: fmt.Errorf("error scanning user session index to struct: %w", err)}
+		}
+
+		if _, err = provider.decrypt(index.SessionID); err != nil {
+			result.Invalid++
+		}
+	}
+
+	_ = rows.Close()
+
+	return tableUserSessionIndex, result
+}
+
 func schemaEncryptionCheckKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType) EncryptionCheckKeyFunc {
 	return func(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
 		var (
@@ -474,7 +735,25 @@ func (p *SQLProvider) encrypt(clearText []byte) (cipherText []byte, err error) {
 }
 
 func (p *SQLProvider) decrypt(cipherText []byte) (clearText []byte, err error) {
-	return utils.Decrypt(cipherText, &p.keys.encryption)
+	clearText, _, err = p.decryptKeys(cipherText)
+
+	return clearText, err
+}
+
+// decryptKeys decrypts the cipher text with the encryption key or any of the previous encryption keys, returning true
+// if it was decrypted with the encryption key.
+func (p *SQLProvider) decryptKeys(cipherText []byte) (clearText []byte, current bool, err error) {
+	if clearText, err = utils.Decrypt(cipherText, &p.keys.encryption); err == nil {
+		return clearText, true, nil
+	}
+
+	for i := range p.keys.previous {
+		if clearText, perr := utils.Decrypt(cipherText, &p.keys.previous[i]); perr == nil {
+			return clearText, false, nil
+		}
+	}
+
+	return nil, false, err
 }
 
 func (p *SQLProvider) otcHMACSignature(values ...[]byte) string {
@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestSQLProviderShouldRotateKeyTableInBatches(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys = SQLProviderKeys{
+		encryption: sha256.Sum256([]byte("a-new-very-long-secret-encryption-key")),
+		previous:   [][32]byte{sha256.Sum256([]byte("an-old-very-long-secret-encryption-key"))},
+	}
+
+	oldA, err := utils.Encrypt([]byte("secret-a"), &provider.keys.previous[0])
+	require.NoError(t, err)
+
+	current, err := utils.Encrypt([]byte("secret-b"), &provider.keys.encryption)
+	require.NoError(t, err)
+
+	oldC, err := utils.Encrypt([]byte("secret-c"), &provider.keys.previous[0])
+	require.NoError(t, err)
+
+	target := encRotateKeyTarget{table: tableTOTPConfigurations, column: "secret"}
+
+	querySelect := regexp.QuoteMeta(fmt.Sprintf(queryFmtSelectEncryptedDataBatch, target.table, target.column))
+	queryUpdate := regexp.QuoteMeta(fmt.Sprintf(queryFmtUpdateEncryptedDataRotate, target.table, target.column))
+
+	primary.ExpectBegin()
+	primary.ExpectQuery(querySelect).WithArgs(0, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(1, oldA).AddRow(2, current))
+	primary.ExpectExec(queryUpdate).WithArgs(testEncryptedWith{key: provider.keys.encryption, value: "secret-a"}, 1, oldA).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	primary.ExpectCommit()
+
+	primary.ExpectBegin()
+	primary.ExpectQuery(querySelect).WithArgs(2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(3, oldC))
+	primary.ExpectExec(queryUpdate).WithArgs(testEncryptedWith{key: provider.keys.encryption, value: "secret-c"}, 3, oldC).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	primary.ExpectCommit()
+
+	result, err := provider.schemaEncryptionRotateKeyTable(context.Background(), target, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, EncryptionRotateKeyTableResult{Total: 3, Rotated: 1, Skipped: 1}, result)
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRollbackRotateKeyTableOnUnknownKey(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys = SQLProviderKeys{
+		encryption: sha256.Sum256([]byte("a-new-very-long-secret-encryption-key")),
+		previous:   [][32]byte{sha256.Sum256([]byte("an-old-very-long-secret-encryption-key"))},
+	}
+
+	unknown := sha256.Sum256([]byte("an-unknown-very-long-secret-encryption-key"))
+
+	value, err := utils.Encrypt([]byte("secret-a"), &unknown)
+	require.NoError(t, err)
+
+	target := encRotateKeyTarget{table: tableWebAuthnCredentials, column: "public_key"}
+
+	primary.ExpectBegin()
+	primary.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(queryFmtSelectEncryptedDataBatch, target.table, target.column))).WithArgs(0, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "value"}).AddRow(7, value))
+	primary.ExpectRollback()
+
+	_, err = provider.schemaEncryptionRotateKeyTable(context.Background(), target, 10)
+
+	assert.EqualError(t, err, "rollback due to error: error decrypting the value with id '7' from the 'webauthn_credentials' table: cipher: message authentication failed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldNotRotateKeyWithoutPreviousKeys(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	_, err := provider.SchemaEncryptionRotateKey(context.Background(), 100)
+	assert.EqualError(t, err, "error rotating the storage encryption key: no previous encryption keys are configured")
+
+	provider.keys.previous = [][32]byte{sha256.Sum256([]byte("an-old-very-long-secret-encryption-key"))}
+
+	_, err = provider.SchemaEncryptionRotateKey(context.Background(), 0)
+	assert.EqualError(t, err, "error rotating the storage encryption key: the batch size must be 1 or more but it's 0")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+type testEncryptedWith struct {
+	key   [32]byte
+	value string
+}
+
+func (a testEncryptedWith) Match(v driver.Value) bool {
+	cipherText, ok := v.([]byte)
+	if !ok {
+		return false
+	}
+
+	clearText, err := utils.Decrypt(cipherText, &a.key)
+
+	return err == nil && string(clearText) == a.value
+}
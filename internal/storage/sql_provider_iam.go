@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/secrets"
+)
+
+// newSQLTokenSource returns the secrets.TokenSource of the IAM authentication tokens for the user of the database at
+// the address.
+func newSQLTokenSource(config *schema.StorageIAMAuthentication, address *schema.AddressTCP, username string) (tokens secrets.TokenSource, err error) {
+	return secrets.NewDatabaseTokenSourceFromEnvironment(config.Provider, config.Region, address.NetworkAddress(), username)
+}
+
+// sqlErrorConnector is a driver.Connector which fails every connection with the error which prevented the creation of
+// the actual driver.Connector, so the error is reported by the startup check like the errors of the data source name.
+type sqlErrorConnector struct {
+	err error
+}
+
+// Connect implements driver.Connector.
+func (c *sqlErrorConnector) Connect(_ context.Context) (conn driver.Conn, err error) {
+	return nil, c.err
+}
+
+// Driver implements driver.Connector.
+func (c *sqlErrorConnector) Driver() driver.Driver {
+	return sqlErrorDriver{err: c.err}
+}
+
+type sqlErrorDriver struct {
+	err error
+}
+
+// Open implements driver.Driver.
+func (d sqlErrorDriver) Open(_ string) (conn driver.Conn, err error) {
+	return nil, d.err
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldConsumeIdentityVerificationOnce(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlConsumeIdentityVerification = fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification)
+
+	ip := model.NewNullIP(net.ParseIP("192.168.0.1"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeIdentityVerification)).
+		WithArgs(ip, "2a5a5f02-ba43-4a4e-8e3c-0a8f7d2c8a11").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.ConsumeIdentityVerification(context.Background(), "2a5a5f02-ba43-4a4e-8e3c-0a8f7d2c8a11", ip))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeIdentityVerification)).
+		WithArgs(ip, "2a5a5f02-ba43-4a4e-8e3c-0a8f7d2c8a11").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.ConsumeIdentityVerification(context.Background(), "2a5a5f02-ba43-4a4e-8e3c-0a8f7d2c8a11", ip), ErrIdentityVerificationNotConsumable)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeIdentityVerification)).
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.ConsumeIdentityVerification(context.Background(), "2a5a5f02-ba43-4a4e-8e3c-0a8f7d2c8a11", ip), "error updating identity verification: table is locked")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldCountIdentityVerifications(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectCountIdentityVerifications = fmt.Sprintf(queryFmtSelectCountIdentityVerifications, tableIdentityVerification)
+
+	since := time.Unix(1700000000, 0)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountIdentityVerifications)).
+		WithArgs("john", "ResetPassword", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := provider.CountIdentityVerifications(context.Background(), "john", "ResetPassword", since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountIdentityVerifications)).
+		WillReturnError(errors.New("bad connection"))
+
+	count, err = provider.CountIdentityVerifications(context.Background(), "john", "ResetPassword", since)
+
+	assert.EqualError(t, err, "error selecting the number of identity verifications issued to user 'john' for action 'ResetPassword': bad connection")
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLProviderLeaderElection(t *testing.T) (provider *SQLProvider, primary sqlmock.Sqlmock) {
+	provider, primary, _ = newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdateLeaderElectionLease = fmt.Sprintf(queryFmtUpdateLeaderElectionLease, tableLeaderElectionLeases)
+	provider.sqlSelectLeaderElectionLease = fmt.Sprintf(queryFmtSelectLeaderElectionLease, tableLeaderElectionLeases)
+	provider.sqlInsertLeaderElectionLease = fmt.Sprintf(queryFmtInsertLeaderElectionLease, tableLeaderElectionLeases)
+	provider.sqlDeleteLeaderElectionLease = fmt.Sprintf(queryFmtDeleteLeaderElectionLease, tableLeaderElectionLeases)
+
+	return provider, primary
+}
+
+func TestSQLProviderShouldAcquireLeaderElectionLease(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	expires := now.Add(time.Second * 30)
+
+	testCases := []struct {
+		name     string
+		setup    func(provider *SQLProvider, primary sqlmock.Sqlmock)
+		expected bool
+		err      string
+	}{
+		{
+			"ShouldAcquireByUpdate",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			true,
+			"",
+		},
+		{
+			"ShouldNotAcquireWhenHeldByAnotherHolder",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnRows(sqlmock.NewRows([]string{"holder"}).AddRow("host-b"))
+			},
+			false,
+			"",
+		},
+		{
+			"ShouldAcquireWhenHeldByHolderWithoutChanges",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnRows(sqlmock.NewRows([]string{"holder"}).AddRow("host-a"))
+			},
+			true,
+			"",
+		},
+		{
+			"ShouldAcquireByInsert",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnError(sql.ErrNoRows)
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertLeaderElectionLease)).
+					WithArgs("storage-cleanup", "host-a", expires).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			true,
+			"",
+		},
+		{
+			"ShouldNotAcquireWhenInsertedConcurrently",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnError(sql.ErrNoRows)
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertLeaderElectionLease)).
+					WithArgs("storage-cleanup", "host-a", expires).
+					WillReturnError(errors.New("duplicate key"))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnRows(sqlmock.NewRows([]string{"holder"}).AddRow("host-b"))
+			},
+			false,
+			"",
+		},
+		{
+			"ShouldErrorUpdate",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnError(sql.ErrConnDone)
+			},
+			false,
+			"error updating leader election lease with name 'storage-cleanup': sql: connection is already closed",
+		},
+		{
+			"ShouldErrorSelect",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnError(sql.ErrConnDone)
+			},
+			false,
+			"error selecting leader election lease with name 'storage-cleanup': sql: connection is already closed",
+		},
+		{
+			"ShouldErrorInsert",
+			func(provider *SQLProvider, primary sqlmock.Sqlmock) {
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateLeaderElectionLease)).
+					WithArgs("host-a", expires, "storage-cleanup", "host-a", now).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnError(sql.ErrNoRows)
+				primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertLeaderElectionLease)).
+					WithArgs("storage-cleanup", "host-a", expires).
+					WillReturnError(sql.ErrConnDone)
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLeaderElectionLease)).
+					WithArgs("storage-cleanup").
+					WillReturnError(sql.ErrConnDone)
+			},
+			false,
+			"error inserting leader election lease with name 'storage-cleanup': sql: connection is already closed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, primary := newTestSQLProviderLeaderElection(t)
+
+			tc.setup(provider, primary)
+
+			acquired, err := provider.AcquireLeaderElectionLease(context.Background(), "storage-cleanup", "host-a", now, expires)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, acquired)
+			assert.NoError(t, primary.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSQLProviderShouldReleaseLeaderElectionLease(t *testing.T) {
+	provider, primary := newTestSQLProviderLeaderElection(t)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteLeaderElectionLease)).
+		WithArgs("storage-cleanup", "host-a").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.ReleaseLeaderElectionLease(context.Background(), "storage-cleanup", "host-a"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteLeaderElectionLease)).
+		WithArgs("storage-cleanup", "host-a").
+		WillReturnError(sql.ErrConnDone)
+
+	assert.EqualError(t, provider.ReleaseLeaderElectionLease(context.Background(), "storage-cleanup", "host-a"), "error deleting leader election lease with name 'storage-cleanup': sql: connection is already closed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveMobileDevice(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertMobileDevice = fmt.Sprintf(queryFmtInsertMobileDevice, tableMobileDevice)
+
+	id := uuid.MustParse("6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21")
+	created := time.Unix(1700000000, 0)
+
+	device := model.MobileDevice{
+		PublicID:         id,
+		Username:         "john",
+		Name:             "phone",
+		Platform:         "ios",
+		CreatedAt:        created,
+		AccessSignature:  "access",
+		AccessExpiresAt:  created.Add(time.Minute * 5),
+		RefreshSignature: "refresh",
+		ExpiresAt:        created.Add(time.Hour * 24),
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertMobileDevice)).
+		WithArgs(id.String(), "john", "phone", "ios", created, "access", created.Add(time.Minute*5), "refresh", created.Add(time.Hour*24)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveMobileDevice(context.Background(), device))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertMobileDevice)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveMobileDevice(context.Background(), device), "error inserting mobile device with id '6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21' for user 'john': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadMobileDevice(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectMobileDevice = fmt.Sprintf(queryFmtSelectMobileDevice, tableMobileDevice)
+	provider.sqlSelectMobileDeviceByAccessSignature = fmt.Sprintf(queryFmtSelectMobileDeviceByAccessSignature, tableMobileDevice)
+
+	id := uuid.MustParse("6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21")
+	created := time.Unix(1700000000, 0)
+
+	columns := []string{"id", "public_id", "username", "name", "platform", "created_at", "last_used_at", "access_signature", "access_expires_at", "refresh_signature", "previous_refresh_signature", "expires_at", "push_provider", "push_token", "revoked_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDevice)).
+		WithArgs(id.String()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, id.String(), "john", "phone", "ios", created, nil, "access", created.Add(time.Minute*5), "refresh", nil, created.Add(time.Hour*24), "apns", "abc", nil))
+
+	device, err := provider.LoadMobileDevice(context.Background(), id)
+
+	assert.NoError(t, err)
+	require.NotNil(t, device)
+	assert.Equal(t, id, device.PublicID)
+	assert.Equal(t, "john", device.Username)
+	assert.Equal(t, sql.NullString{String: "apns", Valid: true}, device.PushProvider)
+	assert.False(t, device.PreviousRefreshSignature.Valid)
+	assert.False(t, device.IsRevoked())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDevice)).
+		WithArgs(id.String()).
+		WillReturnError(sql.ErrNoRows)
+
+	device, err = provider.LoadMobileDevice(context.Background(), id)
+
+	assert.ErrorIs(t, err, ErrNoMobileDevice)
+	assert.Nil(t, device)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDeviceByAccessSignature)).
+		WithArgs("access").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, id.String(), "john", "phone", "ios", created, created, "access", created.Add(time.Minute*5), "refresh", "previous", created.Add(time.Hour*24), nil, nil, created))
+
+	device, err = provider.LoadMobileDeviceByAccessSignature(context.Background(), "access")
+
+	assert.NoError(t, err)
+	require.NotNil(t, device)
+	assert.Equal(t, "previous", device.PreviousRefreshSignature.String)
+	assert.True(t, device.IsRevoked())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDeviceByAccessSignature)).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	device, err = provider.LoadMobileDeviceByAccessSignature(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, ErrNoMobileDevice)
+	assert.Nil(t, device)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDeviceByAccessSignature)).
+		WithArgs("broken").
+		WillReturnError(errors.New("bad connection"))
+
+	device, err = provider.LoadMobileDeviceByAccessSignature(context.Background(), "broken")
+
+	assert.EqualError(t, err, "error selecting mobile device with access token signature 'broken': bad connection")
+	assert.Nil(t, device)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldUpdateMobileDevice(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdateMobileDeviceTokens = fmt.Sprintf(queryFmtUpdateMobileDeviceTokens, tableMobileDevice)
+	provider.sqlUpdateMobileDevicePush = fmt.Sprintf(queryFmtUpdateMobileDevicePush, tableMobileDevice)
+
+	id := uuid.MustParse("6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21")
+	now := time.Unix(1700000000, 0)
+
+	device := model.MobileDevice{
+		PublicID:                 id,
+		Username:                 "john",
+		AccessSignature:          "access",
+		AccessExpiresAt:          now.Add(time.Minute * 5),
+		RefreshSignature:         "refresh",
+		PreviousRefreshSignature: sql.NullString{String: "previous", Valid: true},
+		ExpiresAt:                now.Add(time.Hour * 24),
+		LastUsedAt:               sql.NullTime{Time: now, Valid: true},
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateMobileDeviceTokens)).
+		WithArgs("access", now.Add(time.Minute*5), "refresh", "previous", now.Add(time.Hour*24), now, id.String(), "previous").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateMobileDeviceTokens(context.Background(), device))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateMobileDeviceTokens)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.UpdateMobileDeviceTokens(context.Background(), device), ErrNoMobileDevice)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateMobileDeviceTokens)).
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.UpdateMobileDeviceTokens(context.Background(), device), "error updating the tokens of mobile device with id '6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21' for user 'john': table is locked")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateMobileDevicePush)).
+		WithArgs("fcm", "token", id.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateMobileDevicePush(context.Background(), id, "fcm", "token"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateMobileDevicePush)).
+		WithArgs("fcm", "token", id.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.UpdateMobileDevicePush(context.Background(), id, "fcm", "token"), ErrNoMobileDevice)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRevokeAndDeleteMobileDevices(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlRevokeMobileDevice = fmt.Sprintf(queryFmtRevokeMobileDevice, tableMobileDevice)
+	provider.sqlDeleteExpiredMobileDevices = fmt.Sprintf(queryFmtDeleteExpiredMobileDevices, tableMobileDevice)
+
+	id := uuid.MustParse("6b1e5a5e-8d2c-4a4b-9c5a-3f0f6e4f9d21")
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeMobileDevice)).
+		WithArgs(now, id.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.RevokeMobileDevice(context.Background(), id, now))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeMobileDevice)).
+		WithArgs(now, id.String()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.RevokeMobileDevice(context.Background(), id, now), ErrNoMobileDevice)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredMobileDevices)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := provider.DeleteExpiredMobileDevices(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredMobileDevices)).
+		WithArgs(now, 100).
+		WillReturnError(errors.New("table is locked"))
+
+	deleted, err = provider.DeleteExpiredMobileDevices(context.Background(), now, 100)
+
+	assert.EqualError(t, err, "error deleting expired mobile devices: table is locked")
+	assert.Equal(t, int64(0), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
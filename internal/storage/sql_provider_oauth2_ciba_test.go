@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestSQLProviderShouldSaveOAuth2CIBARequest(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlInsertOAuth2CIBARequest = fmt.Sprintf(queryFmtInsertOAuth2CIBARequest, tableOAuth2CIBARequest)
+
+	requested := time.Unix(1700000000, 0)
+	expires := requested.Add(time.Minute * 5)
+
+	request := model.OAuth2CIBARequest{
+		RequestID:       "a-request-id",
+		ClientID:        "call-center",
+		Signature:       "ciba-signature",
+		Username:        "john",
+		TransactionID:   sql.NullString{String: "a-txid", Valid: true},
+		DeliveryMode:    "poll",
+		RequestedAt:     requested,
+		ExpiresAt:       expires,
+		CheckedAt:       requested,
+		RequestedScopes: model.StringSlicePipeDelimited{"openid"},
+		GrantedScopes:   model.StringSlicePipeDelimited{},
+		Active:          true,
+		Form:            "login_hint=john",
+		Session:         []byte(`{"session":{}}`),
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2CIBARequest)).
+		WithArgs("a-request-id", "call-center", "ciba-signature", 0, sql.NullString{}, "john",
+			request.TransactionID, "poll", false, requested, expires, requested, sql.NullTime{},
+			request.RequestedScopes, request.GrantedScopes, request.RequestedAudience, request.GrantedAudience,
+			true, "login_hint=john", testEncryptedWith{key: provider.keys.encryption, value: `{"session":{}}`}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveOAuth2CIBARequest(context.Background(), request))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2CIBARequest)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveOAuth2CIBARequest(context.Background(), request), "error inserting oauth2 backchannel authentication request data with signature 'ciba-signature' and request id 'a-request-id': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldUpdateOAuth2CIBARequest(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdateOAuth2CIBARequestStatus = fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestStatus, tableOAuth2CIBARequest)
+	provider.sqlUpdateOAuth2CIBARequestCheckedAt = fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestCheckedAt, tableOAuth2CIBARequest)
+	provider.sqlUpdateOAuth2CIBARequestNotified = fmt.Sprintf(queryFmtUpdateOAuth2CIBARequestNotified, tableOAuth2CIBARequest)
+
+	now := time.Unix(1700000000, 0)
+	authenticated := sql.NullTime{Time: now, Valid: true}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2CIBARequestStatus)).
+		WithArgs(model.OAuth2CIBAStatusApproved, authenticated, "ciba-signature").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateOAuth2CIBARequestStatus(context.Background(), "ciba-signature", model.OAuth2CIBAStatusApproved, authenticated))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2CIBARequestCheckedAt)).
+		WithArgs(now, "ciba-signature").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateOAuth2CIBARequestCheckedAt(context.Background(), "ciba-signature", now))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2CIBARequestNotified)).
+		WithArgs("ciba-signature").
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.UpdateOAuth2CIBARequestNotified(context.Background(), "ciba-signature"), "error updating oauth2 backchannel authentication request notified status with signature 'ciba-signature': table is locked")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadOAuth2CIBARequests(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlSelectOAuth2CIBARequest = fmt.Sprintf(queryFmtSelectOAuth2CIBARequest, tableOAuth2CIBARequest)
+	provider.sqlSelectOAuth2CIBARequestsPending = fmt.Sprintf(queryFmtSelectOAuth2CIBARequestsPending, tableOAuth2CIBARequest)
+
+	data, err := utils.Encrypt([]byte(`{"session":{}}`), &provider.keys.encryption)
+	require.NoError(t, err)
+
+	requested := time.Unix(1700000000, 0)
+	expires := requested.Add(time.Minute * 5)
+
+	columns := []string{"id", "request_id", "client_id", "signature", "status", "subject", "username", "txid", "delivery_mode", "notified", "requested_at", "expires_at", "checked_at", "authenticated_at", "requested_scopes", "granted_scopes", "requested_audience", "granted_audience", "active", "form_data", "session_data"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2CIBARequest)).
+		WithArgs("ciba-signature").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "a-request-id", "call-center", "ciba-signature", 0, nil, "john", "a-txid", "poll", false, requested, expires, requested, nil, "openid|offline_access", "", "", "", true, "login_hint=john", data))
+
+	request, err := provider.LoadOAuth2CIBARequest(context.Background(), "ciba-signature")
+
+	assert.NoError(t, err)
+	require.NotNil(t, request)
+	assert.Equal(t, 3, request.ID)
+	assert.Equal(t, "john", request.Username)
+	assert.Equal(t, sql.NullString{String: "a-txid", Valid: true}, request.TransactionID)
+	assert.Equal(t, model.StringSlicePipeDelimited{"openid", "offline_access"}, request.RequestedScopes)
+	assert.True(t, request.IsPending())
+	assert.False(t, request.IsExpired(requested))
+	assert.True(t, request.IsExpired(expires.Add(time.Second)))
+	assert.Equal(t, []byte(`{"session":{}}`), request.Session)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2CIBARequest)).
+		WithArgs("bad-signature").
+		WillReturnError(sql.ErrNoRows)
+
+	request, err = provider.LoadOAuth2CIBARequest(context.Background(), "bad-signature")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, request)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2CIBARequestsPending)).
+		WithArgs(requested, 20).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(3, "a-request-id", "call-center", "ciba-signature", 0, nil, "john", "a-txid", "poll", false, requested, expires, requested, nil, "openid", "", "", "", true, "login_hint=john", data).
+			AddRow(4, "b-request-id", "kiosk", "ciba-signature-b", 1, nil, "jane", "b-txid", "ping", false, requested, expires, requested, requested, "openid", "openid", "", "", true, "login_hint=jane", data))
+
+	requests, err := provider.LoadOAuth2CIBARequestsPending(context.Background(), requested, 20)
+
+	assert.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "ciba-signature", requests[0].Signature)
+	assert.Equal(t, "ping", requests[1].DeliveryMode)
+	assert.False(t, requests[1].IsPending())
+	assert.True(t, requests[1].AuthenticatedAt.Valid)
+	assert.Equal(t, []byte(`{"session":{}}`), requests[1].Session)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeactivateAndDeleteOAuth2CIBARequests(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeactivateOAuth2CIBARequest = fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2CIBARequest)
+	provider.sqlDeleteExpiredOAuth2CIBARequests = fmt.Sprintf(queryFmtDeleteExpiredOAuth2CIBARequests, tableOAuth2CIBARequest)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeactivateOAuth2CIBARequest)).
+		WithArgs("ciba-signature").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeactivateOAuth2CIBARequest(context.Background(), "ciba-signature"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredOAuth2CIBARequests)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+
+	deleted, err := provider.DeleteExpiredOAuth2CIBARequests(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), deleted)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredOAuth2CIBARequests)).
+		WithArgs(now, 100).
+		WillReturnError(errors.New("bad connection"))
+
+	deleted, err = provider.DeleteExpiredOAuth2CIBARequests(context.Background(), now, 100)
+
+	assert.EqualError(t, err, "error deleting expired oauth2 backchannel authentication requests: bad connection")
+	assert.Equal(t, int64(0), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestSQLProviderShouldSaveAndUpdateOAuth2DeviceCodeSession(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlInsertOAuth2DeviceCodeSession = fmt.Sprintf(queryFmtInsertOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession)
+	provider.sqlUpdateOAuth2DeviceCodeSession = fmt.Sprintf(queryFmtUpdateOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession)
+
+	requested := time.Unix(1700000000, 0)
+
+	session := model.OAuth2DeviceCodeSession{
+		RequestID:         "a-request-id",
+		ClientID:          "tv",
+		Signature:         "device-signature",
+		UserCodeSignature: "user-signature",
+		RequestedAt:       requested,
+		CheckedAt:         requested,
+		RequestedScopes:   model.StringSlicePipeDelimited{"openid"},
+		GrantedScopes:     model.StringSlicePipeDelimited{},
+		Active:            true,
+		Form:              "client_id=tv",
+		Session:           []byte(`{}`),
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2DeviceCodeSession)).
+		WithArgs("a-request-id", "tv", "device-signature", "user-signature", 0, sql.NullString{}, requested, requested,
+			session.RequestedScopes, session.GrantedScopes, session.RequestedAudience, session.GrantedAudience,
+			true, false, "client_id=tv", testEncryptedWith{key: provider.keys.encryption, value: `{}`}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveOAuth2DeviceCodeSession(context.Background(), session))
+
+	session.Status = 1
+	session.Subject = sql.NullString{String: "a6b5f6a5-5cb0-4ea6-8f1b-8e8d5a1d8c21", Valid: true}
+	session.GrantedScopes = model.StringSlicePipeDelimited{"openid"}
+	session.Session = []byte(`{"subject":"john"}`)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2DeviceCodeSession)).
+		WithArgs(1, session.Subject, requested, session.GrantedScopes, session.GrantedAudience,
+			testEncryptedWith{key: provider.keys.encryption, value: `{"subject":"john"}`}, "device-signature").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateOAuth2DeviceCodeSession(context.Background(), session))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2DeviceCodeSession)).
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.UpdateOAuth2DeviceCodeSession(context.Background(), session), "error updating oauth2 device code session data with signature 'device-signature' and request id 'a-request-id': table is locked")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadOAuth2DeviceCodeSessionByUserCode(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature = fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSessionByUserCodeSignature, tableOAuth2DeviceCodeSession)
+
+	data, err := utils.Encrypt([]byte(`{}`), &provider.keys.encryption)
+	require.NoError(t, err)
+
+	requested := time.Unix(1700000000, 0)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature)).
+		WithArgs("user-signature").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "request_id", "client_id", "signature", "user_code_signature", "status", "subject", "requested_at", "checked_at", "requested_scopes", "granted_scopes", "requested_audience", "granted_audience", "active", "revoked", "form_data", "session_data"}).
+			AddRow(4, "a-request-id", "tv", "device-signature", "user-signature", 0, nil, requested, requested, "openid|offline_access", "", "", "", true, false, "client_id=tv", data))
+
+	session, err := provider.LoadOAuth2DeviceCodeSessionByUserCode(context.Background(), "user-signature")
+
+	assert.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, 4, session.ID)
+	assert.Equal(t, "device-signature", session.Signature)
+	assert.Equal(t, model.StringSlicePipeDelimited{"openid", "offline_access"}, session.RequestedScopes)
+	assert.False(t, session.Subject.Valid)
+	assert.Equal(t, []byte(`{}`), session.Session)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature)).
+		WithArgs("bad-signature").
+		WillReturnError(sql.ErrNoRows)
+
+	session, err = provider.LoadOAuth2DeviceCodeSessionByUserCode(context.Background(), "bad-signature")
+
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.Nil(t, session)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
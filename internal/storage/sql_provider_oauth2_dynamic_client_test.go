@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveOAuth2DynamicClient(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertOAuth2DynamicClient = fmt.Sprintf(queryFmtInsertOAuth2DynamicClient, tableOAuth2DynamicClient)
+
+	now := time.Unix(1700000000, 0)
+
+	client := model.OAuth2DynamicClient{
+		ClientID:                         "0d3e4c8a-3c6b-4f1e-9fb5-0d8f7b4c1a2e",
+		RegistrationAccessTokenSignature: "ab12",
+		ClientSecret:                     sql.NullString{String: "$pbkdf2-sha512$310000$abc$def", Valid: true},
+		Metadata:                         []byte(`{"redirect_uris":["https://app.example.com/callback"]}`),
+		CreatedAt:                        now,
+		UpdatedAt:                        now,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2DynamicClient)).
+		WithArgs(client.ClientID, "ab12", client.ClientSecret, client.Metadata, now, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveOAuth2DynamicClient(context.Background(), client))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2DynamicClient)).
+		WithArgs(client.ClientID, "ab12", client.ClientSecret, client.Metadata, now, now).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveOAuth2DynamicClient(context.Background(), client), "error inserting oauth2 dynamic client with client id '0d3e4c8a-3c6b-4f1e-9fb5-0d8f7b4c1a2e': duplicate key")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldUpdateOAuth2DynamicClient(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdateOAuth2DynamicClient = fmt.Sprintf(queryFmtUpdateOAuth2DynamicClient, tableOAuth2DynamicClient)
+
+	now := time.Unix(1700000000, 0)
+
+	client := model.OAuth2DynamicClient{
+		ClientID:                         "0d3e4c8a-3c6b-4f1e-9fb5-0d8f7b4c1a2e",
+		RegistrationAccessTokenSignature: "cd34",
+		Metadata:                         []byte(`{}`),
+		UpdatedAt:                        now,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2DynamicClient)).
+		WithArgs("cd34", client.ClientSecret, client.Metadata, now, client.ClientID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdateOAuth2DynamicClient(context.Background(), client))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateOAuth2DynamicClient)).
+		WithArgs("cd34", client.ClientSecret, client.Metadata, now, client.ClientID).
+		WillReturnError(sql.ErrConnDone)
+
+	assert.EqualError(t, provider.UpdateOAuth2DynamicClient(context.Background(), client), "error updating oauth2 dynamic client with client id '0d3e4c8a-3c6b-4f1e-9fb5-0d8f7b4c1a2e': sql: connection is already closed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadOAuth2DynamicClient(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectOAuth2DynamicClient = fmt.Sprintf(queryFmtSelectOAuth2DynamicClient, tableOAuth2DynamicClient)
+
+	now := time.Unix(1700000000, 0)
+
+	columns := []string{"id", "client_id", "registration_access_token_signature", "client_secret", "metadata", "created_at", "updated_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2DynamicClient)).
+		WithArgs("a-client").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "a-client", "ab12", nil, []byte(`{}`), now, now))
+
+	client, err := provider.LoadOAuth2DynamicClient(context.Background(), "a-client")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a-client", client.ClientID)
+	assert.Equal(t, "ab12", client.RegistrationAccessTokenSignature)
+	assert.False(t, client.ClientSecret.Valid)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2DynamicClient)).
+		WithArgs("a-client").
+		WillReturnError(sql.ErrNoRows)
+
+	client, err = provider.LoadOAuth2DynamicClient(context.Background(), "a-client")
+
+	assert.Nil(t, client)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2DynamicClient)).
+		WithArgs("a-client").
+		WillReturnError(sql.ErrConnDone)
+
+	client, err = provider.LoadOAuth2DynamicClient(context.Background(), "a-client")
+
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "error selecting oauth2 dynamic client with client id 'a-client': sql: connection is already closed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteOAuth2DynamicClient(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteOAuth2DynamicClient = fmt.Sprintf(queryFmtDeleteOAuth2DynamicClient, tableOAuth2DynamicClient)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteOAuth2DynamicClient)).
+		WithArgs("a-client").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeleteOAuth2DynamicClient(context.Background(), "a-client"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteOAuth2DynamicClient)).
+		WithArgs("a-client").
+		WillReturnError(sql.ErrConnDone)
+
+	assert.EqualError(t, provider.DeleteOAuth2DynamicClient(context.Background(), "a-client"), "error deleting oauth2 dynamic client with client id 'a-client': sql: connection is already closed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveOAuth2TokenExchangeLineage(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertOAuth2TokenExchangeLineage = fmt.Sprintf(queryFmtInsertOAuth2TokenExchangeLineage, tableOAuth2TokenExchangeLineage)
+
+	exchanged := time.Unix(1700000000, 0)
+
+	lineage := model.OAuth2TokenExchangeLineage{
+		RequestID:       "a-child-request-id",
+		ParentRequestID: "a-parent-request-id",
+		ClientID:        "backend",
+		ParentClientID:  "frontend",
+		Subject:         sql.NullString{String: "a6b5f6a5-5cb0-4ea6-8f1b-8e8d5a1d8c21", Valid: true},
+		ExchangedAt:     exchanged,
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2TokenExchangeLineage)).
+		WithArgs("a-child-request-id", "a-parent-request-id", "backend", "frontend", lineage.Subject, exchanged).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveOAuth2TokenExchangeLineage(context.Background(), lineage))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertOAuth2TokenExchangeLineage)).
+		WithArgs("a-child-request-id", "a-parent-request-id", "backend", "frontend", lineage.Subject, exchanged).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveOAuth2TokenExchangeLineage(context.Background(), lineage), "error inserting oauth2 token exchange lineage with request id 'a-child-request-id' and parent request id 'a-parent-request-id': duplicate key")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadOAuth2TokenExchangeLineageByParentRequestID(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectOAuth2TokenExchangeLineageByParentRequestID = fmt.Sprintf(queryFmtSelectOAuth2TokenExchangeLineageByParentRequestID, tableOAuth2TokenExchangeLineage)
+
+	exchanged := time.Unix(1700000000, 0)
+
+	columns := []string{"id", "request_id", "parent_request_id", "client_id", "parent_client_id", "subject", "exchanged_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2TokenExchangeLineageByParentRequestID)).
+		WithArgs("a-parent-request-id").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "a-child-request-id", "a-parent-request-id", "backend", "frontend", nil, exchanged).
+			AddRow(2, "another-child-request-id", "a-parent-request-id", "billing", "frontend", nil, exchanged))
+
+	lineage, err := provider.LoadOAuth2TokenExchangeLineageByParentRequestID(context.Background(), "a-parent-request-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, lineage, 2)
+	assert.Equal(t, "a-child-request-id", lineage[0].RequestID)
+	assert.Equal(t, "billing", lineage[1].ClientID)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectOAuth2TokenExchangeLineageByParentRequestID)).
+		WithArgs("a-parent-request-id").
+		WillReturnError(sql.ErrConnDone)
+
+	lineage, err = provider.LoadOAuth2TokenExchangeLineageByParentRequestID(context.Background(), "a-parent-request-id")
+
+	assert.Nil(t, lineage)
+	assert.EqualError(t, err, "error selecting oauth2 token exchange lineage with parent request id 'a-parent-request-id': sql: connection is already closed")
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
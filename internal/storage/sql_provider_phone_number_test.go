@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestSQLProviderShouldSavePhoneNumberEncrypted(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlUpsertPhoneNumber = fmt.Sprintf(queryFmtUpsertPhoneNumber, tablePhoneNumber)
+
+	created := time.Unix(1700000000, 0)
+
+	number := model.NewPhoneNumber("john", "+15550100123", created)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpsertPhoneNumber)).
+		WithArgs(created, sql.NullTime{}, "john", testEncryptedWith{key: provider.keys.encryption, value: "+15550100123"}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SavePhoneNumber(context.Background(), number))
+	assert.Equal(t, "+15550100123", number.E164())
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpsertPhoneNumber)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.SavePhoneNumber(context.Background(), number), "error upserting phone number for user 'john': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadPhoneNumberDecrypted(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.keys.encryption = sha256.Sum256([]byte("a-very-long-secret-encryption-key"))
+	provider.sqlSelectPhoneNumber = fmt.Sprintf(queryFmtSelectPhoneNumber, tablePhoneNumber)
+
+	created := time.Unix(1700000000, 0)
+
+	value, err := utils.Encrypt([]byte("+15550100123"), &provider.keys.encryption)
+	require.NoError(t, err)
+
+	columns := []string{"id", "created_at", "last_used_at", "username", "phone_number"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPhoneNumber)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, created, created, "john", value))
+
+	number, err := provider.LoadPhoneNumber(context.Background(), "john")
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, number.ID)
+	assert.Equal(t, "john", number.Username)
+	assert.Equal(t, "+15550100123", number.E164())
+	assert.True(t, number.LastUsedAt.Valid)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPhoneNumber)).
+		WithArgs("jane").
+		WillReturnError(sql.ErrNoRows)
+
+	number, err = provider.LoadPhoneNumber(context.Background(), "jane")
+
+	assert.Nil(t, number)
+	assert.ErrorIs(t, err, ErrNoPhoneNumber)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPhoneNumber)).
+		WithArgs("harry").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(2, created, nil, "harry", []byte("not-encrypted")))
+
+	number, err = provider.LoadPhoneNumber(context.Background(), "harry")
+
+	assert.Nil(t, number)
+	assert.EqualError(t, err, "error decrypting phone number for user 'harry': cipher: message authentication failed")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldUpdateAndDeletePhoneNumber(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdatePhoneNumberRecordSignIn = fmt.Sprintf(queryFmtUpdatePhoneNumberRecordSignIn, tablePhoneNumber)
+	provider.sqlDeletePhoneNumber = fmt.Sprintf(queryFmtDeletePhoneNumber, tablePhoneNumber)
+
+	used := sql.NullTime{Time: time.Unix(1700000000, 0), Valid: true}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdatePhoneNumberRecordSignIn)).
+		WithArgs(used, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.UpdatePhoneNumberSignIn(context.Background(), 1, used))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeletePhoneNumber)).
+		WithArgs("john").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeletePhoneNumber(context.Background(), "john"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeletePhoneNumber)).
+		WithArgs("john").
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.DeletePhoneNumber(context.Background(), "john"), "error deleting phone number for user 'john': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldCountOneTimeCodes(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectCountOneTimeCodes = fmt.Sprintf(queryFmtSelectCountOTC, tableOneTimeCode)
+
+	since := time.Unix(1700000000, 0)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountOneTimeCodes)).
+		WithArgs("john", model.OTCIntentSMSSignIn, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := provider.CountOneTimeCodes(context.Background(), "john", model.OTCIntentSMSSignIn, since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountOneTimeCodes)).
+		WillReturnError(errors.New("bad connection"))
+
+	count, err = provider.CountOneTimeCodes(context.Background(), "john", model.OTCIntentSMSSignIn, since)
+
+	assert.EqualError(t, err, "error counting one-time codes for user 'john' with intent 'sms': bad connection")
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
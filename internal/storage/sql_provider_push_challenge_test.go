@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSavePushChallenge(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertPushChallenge = fmt.Sprintf(queryFmtInsertPushChallenge, tablePushChallenge)
+
+	now := time.Unix(1700000000, 0)
+	ip := model.NewNullIP(net.ParseIP("192.168.0.1"))
+
+	challenge := model.NewPushChallenge("john", "ntfy", "Sign in to example.com", "abc123", ip, now, time.Minute)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertPushChallenge)).
+		WithArgs(challenge.ChallengeID, now, now.Add(time.Minute), "john", "ntfy", "Sign in to example.com", provider.pushChallengeSignature(challenge.ChallengeID, "abc123"), ip, model.PushChallengeStatusPending).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SavePushChallenge(context.Background(), challenge))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertPushChallenge)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SavePushChallenge(context.Background(), challenge), fmt.Sprintf("error inserting push challenge with id '%s' for user 'john': duplicate key", challenge.ChallengeID))
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadPushChallenge(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectPushChallenge = fmt.Sprintf(queryFmtSelectPushChallenge, tablePushChallenge)
+
+	now := time.Unix(1700000000, 0)
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	columns := []string{"id", "challenge_id", "created_at", "expires_at", "username", "device", "message", "signature", "request_ip", "status", "responded_at", "responded_ip"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPushChallenge)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, id.String(), now, now.Add(time.Minute), "john", "ntfy", "", "signature", "192.168.0.1", model.PushChallengeStatusApproved, now, "192.168.0.2"))
+
+	challenge, err := provider.LoadPushChallenge(context.Background(), id)
+
+	require.NoError(t, err)
+
+	assert.Equal(t, id, challenge.ChallengeID)
+	assert.Equal(t, "john", challenge.Username)
+	assert.Equal(t, model.PushChallengeStatusApproved, challenge.Status)
+	assert.Equal(t, sql.NullTime{Time: now, Valid: true}, challenge.RespondedAt)
+	assert.Equal(t, "192.168.0.2", challenge.RespondedIP.IP.String())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPushChallenge)).
+		WithArgs(id).
+		WillReturnError(sql.ErrNoRows)
+
+	challenge, err = provider.LoadPushChallenge(context.Background(), id)
+
+	assert.ErrorIs(t, err, ErrNoPushChallenge)
+	assert.Nil(t, challenge)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectPushChallenge)).
+		WithArgs(id).
+		WillReturnError(errors.New("bad connection"))
+
+	challenge, err = provider.LoadPushChallenge(context.Background(), id)
+
+	assert.EqualError(t, err, fmt.Sprintf("error selecting push challenge with id '%s': bad connection", id))
+	assert.Nil(t, challenge)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRespondPushChallengeOnce(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdatePushChallengeResponse = fmt.Sprintf(queryFmtUpdatePushChallengeResponse, tablePushChallenge)
+
+	now := time.Unix(1700000000, 0)
+	ip := model.NewNullIP(net.ParseIP("192.168.0.2"))
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+	signature := provider.pushChallengeSignature(id, "abc123")
+
+	assert.NotEqual(t, signature, provider.pushChallengeSignature(uuid.MustParse("b8a3b1ff-9b0f-4d52-8c8b-2d0b1e6a7c11"), "abc123"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdatePushChallengeResponse)).
+		WithArgs(model.PushChallengeStatusApproved, now, ip, id, signature, now).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := provider.RespondPushChallenge(context.Background(), id, "abc123", model.PushChallengeStatusApproved, now, ip)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdatePushChallengeResponse)).
+		WithArgs(model.PushChallengeStatusDenied, now, ip, id, signature, now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ok, err = provider.RespondPushChallenge(context.Background(), id, "abc123", model.PushChallengeStatusDenied, now, ip)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdatePushChallengeResponse)).
+		WillReturnError(errors.New("table is locked"))
+
+	ok, err = provider.RespondPushChallenge(context.Background(), id, "abc123", model.PushChallengeStatusDenied, now, ip)
+
+	assert.EqualError(t, err, fmt.Sprintf("error updating push challenge (respond) with id '%s': table is locked", id))
+	assert.False(t, ok)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteExpiredPushChallenges(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteExpiredPushChallenges = fmt.Sprintf(queryFmtDeleteExpiredPushChallenges, tablePushChallenge)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredPushChallenges)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	deleted, err := provider.DeleteExpiredPushChallenges(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredPushChallenges)).
+		WillReturnError(errors.New("bad connection"))
+
+	deleted, err = provider.DeleteExpiredPushChallenges(context.Background(), now, 100)
+
+	assert.EqualError(t, err, "error deleting expired push challenges: bad connection")
+	assert.Equal(t, int64(0), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadMobileDevicesPush(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectMobileDevicesPush = fmt.Sprintf(queryFmtSelectMobileDevicesPush, tableMobileDevice)
+
+	now := time.Unix(1700000000, 0)
+	id := uuid.MustParse("c2b4a9a1-7b0e-4f0a-9d55-8f2b5c0f4b1e")
+
+	columns := []string{"id", "public_id", "username", "name", "platform", "created_at", "last_used_at", "access_signature", "access_expires_at", "refresh_signature", "previous_refresh_signature", "expires_at", "push_provider", "push_token", "revoked_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDevicesPush)).
+		WithArgs("john", now).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(1, id.String(), "john", "Pixel", "android", now, nil, "access", now.Add(time.Hour), "refresh", nil, now.Add(time.Hour*24), "fcm", "token", nil))
+
+	devices, err := provider.LoadMobileDevicesPush(context.Background(), "john", now)
+
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+
+	assert.Equal(t, id, devices[0].PublicID)
+	assert.Equal(t, sql.NullString{String: "fcm", Valid: true}, devices[0].PushProvider)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectMobileDevicesPush)).
+		WillReturnError(errors.New("bad connection"))
+
+	devices, err = provider.LoadMobileDevicesPush(context.Background(), "john", now)
+
+	assert.EqualError(t, err, "error selecting mobile devices enrolled for push notifications for user 'john': bad connection")
+	assert.Nil(t, devices)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -14,6 +14,16 @@ const (
 	queryFmtInsertMigration = `
 		INSERT INTO %s (applied, version_before, version_after, application_version)
 		VALUES (?, ?, ?, ?);`
+
+	queryFmtInsertMigrationWithCompatible = `
+		INSERT INTO %s (applied, version_before, version_after, version_compatible, application_version)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtSelectLatestMigrationCompatible = `
+		SELECT version_compatible
+		FROM %s
+		ORDER BY id DESC
+		LIMIT 1;`
 )
 
 const (
@@ -35,10 +45,47 @@ const (
 
 const (
 	queryFmtSelectUserInfo = `
-		SELECT second_factor_method, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_totp, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_webauthn, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_duo
+		SELECT second_factor_method, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_totp, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_webauthn, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_duo, (SELECT EXISTS (SELECT id FROM %s WHERE username = ?)) AS has_sms
 		FROM %s
 		WHERE username = ?;`
 
+	queryFmtSelectUserSummaries = `
+		SELECT u.username, COALESCE(p.second_factor_method, '') AS second_factor_method,
+			(SELECT EXISTS (SELECT id FROM %s WHERE username = u.username)) AS has_totp,
+			(SELECT COUNT(id) FROM %s WHERE username = u.username) AS webauthn_credentials,
+			(SELECT EXISTS (SELECT id FROM %s WHERE username = u.username)) AS has_duo
+		FROM (
+			SELECT username FROM %s
+			UNION SELECT username FROM %s
+			UNION SELECT username FROM %s
+			UNION SELECT username FROM %s
+			UNION SELECT username FROM %s WHERE successful = TRUE
+		) AS u
+		LEFT JOIN %s AS p ON p.username = u.username
+		ORDER BY u.username ASC
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtSelectUsageStatistics = `
+		SELECT
+			(SELECT COUNT(*) FROM (
+				SELECT username FROM %s
+				UNION SELECT username FROM %s
+				UNION SELECT username FROM %s
+				UNION SELECT username FROM %s
+				UNION SELECT username FROM %s WHERE successful = TRUE
+			) AS u) AS users,
+			(SELECT COUNT(DISTINCT username) FROM %s WHERE successful = TRUE AND time >= ?) AS users_active,
+			(SELECT COUNT(*) FROM (
+				SELECT username FROM %s
+				UNION SELECT username FROM %s
+				UNION SELECT username FROM %s
+			) AS s) AS users_second_factor,
+			(SELECT COUNT(DISTINCT username) FROM %s) AS users_totp,
+			(SELECT COUNT(DISTINCT username) FROM %s) AS users_webauthn,
+			(SELECT COUNT(DISTINCT username) FROM %s) AS users_duo,
+			(SELECT COUNT(DISTINCT client_id) FROM %s WHERE authorized = TRUE AND responded_at >= ?) AS clients_active;`
+
 	queryFmtSelectPreferred2FAMethod = `
 		SELECT second_factor_method
 		FROM %s
@@ -65,15 +112,32 @@ const (
 		INSERT INTO %s (jti, iat, issued_ip, exp, username, action)
 		VALUES (?, ?, ?, ?, ?, ?);`
 
+	queryFmtSelectCountIdentityVerifications = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE username = ? AND action = ? AND iat >= ?;`
+
 	queryFmtConsumeIdentityVerification = `
 		UPDATE %s
 		SET consumed = CURRENT_TIMESTAMP, consumed_ip = ?
-		WHERE jti = ?;`
+		WHERE jti = ? AND consumed IS NULL AND revoked IS NULL;`
 
 	queryFmtRevokeIdentityVerification = `
 		UPDATE %s
 		SET revoked = CURRENT_TIMESTAMP, revoked_ip = ?
 		WHERE jti = ?;`
+
+	queryFmtDeleteExpiredIdentityVerifications = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE exp < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
 )
 
 const (
@@ -106,6 +170,11 @@ const (
 		SET consumed = ?, consumed_ip = ?
 		WHERE signature = ?;`
 
+	queryFmtSelectCountOTC = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE username = ? AND intent = ? AND issued >= ?;`
+
 	queryFmtRevokeOTC = `
 		UPDATE %s
 		SET revoked = CURRENT_TIMESTAMP, revoked_ip = ?
@@ -123,31 +192,36 @@ const (
 
 const (
 	queryFmtSelectTOTPConfiguration = `
-		SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret
+		SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret, drift
 		FROM %s
 		WHERE username = ?;`
 
 	queryFmtSelectTOTPConfigurations = `
-		SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret
+		SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret, drift
 		FROM %s
 		LIMIT ?
 		OFFSET ?;`
 
 	queryFmtUpsertTOTPConfiguration = `
-		REPLACE INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+		REPLACE INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret, drift)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
 
 	queryFmtUpsertTOTPConfigurationPostgreSQL = `
-		INSERT INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret, drift)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 			ON CONFLICT (username)
-			DO UPDATE SET created_at = $1, last_used_at = $2, issuer = $4, algorithm = $5, digits = $6, period = $7, secret = $8;`
+			DO UPDATE SET created_at = $1, last_used_at = $2, issuer = $4, algorithm = $5, digits = $6, period = $7, secret = $8, drift = $9;`
 
 	queryFmtUpdateTOTPConfigRecordSignIn = `
 		UPDATE %s
 		SET last_used_at = ?
 		WHERE id = ?;`
 
+	queryFmtUpdateTOTPConfigDrift = `
+		UPDATE %s
+		SET drift = ?
+		WHERE id = ?;`
+
 	queryFmtUpdateTOTPConfigRecordSignInByUsername = `
 		UPDATE %s
 		SET last_used_at = ?
@@ -248,6 +322,11 @@ const (
 		SELECT id, rpid, username, userid
 		FROM %s
 		WHERE rpid = ? AND username = ?;`
+
+	queryFmtSelectWebAuthnUserByUserID = `
+		SELECT id, rpid, username, userid
+		FROM %s
+		WHERE rpid = ? AND userid = ?;`
 )
 
 const (
@@ -268,9 +347,437 @@ const (
 
 	queryFmtSelectDuoDevice = `
 		SELECT id, username, device, method
+		FROM %[1]s
+		WHERE username = ?
+		ORDER BY issued_at %[2]s, id %[2]s
+		LIMIT ?
+		OFFSET ?;`
+)
+
+const (
+	queryFmtInsertDeviceCertificate = `
+		INSERT INTO %s (serial, username, name, issued_at, expires_at)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtSelectDeviceCertificate = `
+		SELECT id, serial, username, name, issued_at, expires_at, revoked_at
+		FROM %s
+		WHERE serial = ?;`
+
+	queryFmtSelectDeviceCertificatesByUsername = `
+		SELECT id, serial, username, name, issued_at, expires_at, revoked_at
 		FROM %s
 		WHERE username = ?
 		ORDER BY id;`
+
+	queryFmtRevokeDeviceCertificate = `
+		UPDATE %s
+		SET revoked_at = ?
+		WHERE username = ? AND serial = ? AND revoked_at IS NULL;`
+
+	queryFmtDeleteExpiredDeviceCertificates = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtInsertUserSessionIndex = `
+		INSERT INTO %s (public_id, signature, username, domain, remote_ip, user_agent, created_at, session_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectUserSessionIndex = `
+		SELECT id, public_id, signature, username, domain, remote_ip, user_agent, created_at, session_id
+		FROM %s
+		WHERE username = ? AND public_id = ?;`
+
+	queryFmtSelectUserSessionIndexByUsername = `
+		SELECT id, public_id, signature, username, domain, remote_ip, user_agent, created_at, session_id
+		FROM %s
+		WHERE username = ?
+		ORDER BY id;`
+
+	queryFmtDeleteUserSessionIndex = `
+		DELETE FROM %s
+		WHERE signature = ?;`
+
+	queryFmtSelectUserSessionIndexEncryptedData = `
+		SELECT id, session_id
+		FROM %s;`
+
+	queryFmtUpdateUserSessionIndexEncryptedData = `
+		UPDATE %s
+		SET session_id = ?
+		WHERE id = ?;`
+)
+
+const (
+	queryFmtInsertUserSessionRevocation = `
+		INSERT INTO %s (signature, revoked_at, expires_at)
+		VALUES (?, ?, ?);`
+
+	queryFmtSelectUserSessionRevocations = `
+		SELECT id, signature, revoked_at, expires_at
+		FROM %s
+		WHERE revoked_at >= ? AND expires_at > ?
+		ORDER BY id;`
+
+	queryFmtDeleteExpiredUserSessionRevocations = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtInsertAppPassword = `
+		INSERT INTO %s (public_id, created_at, username, name, domains, signature)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectAppPassword = `
+		SELECT id, public_id, created_at, last_used_at, username, name, domains, signature
+		FROM %s
+		WHERE username = ? AND signature = ?;`
+
+	queryFmtSelectAppPasswordsByUsername = `
+		SELECT id, public_id, created_at, last_used_at, username, name, domains, signature
+		FROM %s
+		WHERE username = ?
+		ORDER BY created_at;`
+
+	queryFmtUpdateAppPasswordRecordSignIn = `
+		UPDATE %s
+		SET last_used_at = ?
+		WHERE id = ?;`
+
+	queryFmtDeleteAppPassword = `
+		DELETE FROM %s
+		WHERE username = ? AND public_id = ?;`
+)
+
+const (
+	queryFmtSelectSession = `
+		SELECT data
+		FROM %s
+		WHERE signature = ? AND expires_at > ?;`
+
+	queryFmtUpsertSession = `
+		REPLACE INTO %s (signature, expires_at, data)
+		VALUES (?, ?, ?);`
+
+	queryFmtUpsertSessionPostgreSQL = `
+		INSERT INTO %s (signature, expires_at, data)
+		VALUES ($1, $2, $3)
+			ON CONFLICT (signature)
+			DO UPDATE SET expires_at = $2, data = $3;`
+
+	queryFmtUpdateSessionSignature = `
+		UPDATE %s
+		SET signature = ?, expires_at = ?
+		WHERE signature = ?;`
+
+	queryFmtDeleteSession = `
+		DELETE FROM %s
+		WHERE signature = ?;`
+
+	queryFmtSelectCountSessions = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE expires_at > ?;`
+
+	queryFmtDeleteExpiredSessions = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtInsertDirectoryUser = `
+		INSERT INTO %s (public_id, external_id, username, display_name, given_name, family_name, emails, active, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateDirectoryUser = `
+		UPDATE %s
+		SET external_id = ?, username = ?, display_name = ?, given_name = ?, family_name = ?, emails = ?, active = ?, updated_at = ?
+		WHERE public_id = ?;`
+
+	queryFmtSelectDirectoryUser = `
+		SELECT id, public_id, external_id, username, display_name, given_name, family_name, emails, active, created_at, updated_at
+		FROM %s
+		WHERE public_id = ?;`
+
+	queryFmtSelectDirectoryUsers = `
+		SELECT id, public_id, external_id, username, display_name, given_name, family_name, emails, active, created_at, updated_at
+		FROM %s
+		WHERE (? = '' OR username = ?) AND (? = '' OR external_id = ?)
+		ORDER BY id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtSelectDirectoryUsersCount = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE (? = '' OR username = ?) AND (? = '' OR external_id = ?);`
+
+	queryFmtSelectDirectoryUserPassword = `
+		SELECT COALESCE(password, '')
+		FROM %s
+		WHERE username = ? AND active = TRUE;`
+
+	queryFmtUpdateDirectoryUserPassword = `
+		UPDATE %s
+		SET password = ?, updated_at = ?
+		WHERE username = ?;`
+
+	queryFmtDeleteDirectoryUser = `
+		DELETE FROM %s
+		WHERE public_id = ?;`
+
+	queryFmtSelectDirectoryUserGroups = `
+		SELECT g.id, g.public_id, g.external_id, g.display_name, g.created_at, g.updated_at
+		FROM %s g
+		INNER JOIN %s m ON m.group_id = g.id
+		INNER JOIN %s u ON u.id = m.user_id
+		WHERE u.username = ?
+		ORDER BY g.display_name;`
+
+	queryFmtInsertDirectoryGroup = `
+		INSERT INTO %s (public_id, external_id, display_name, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtUpdateDirectoryGroup = `
+		UPDATE %s
+		SET external_id = ?, display_name = ?, updated_at = ?
+		WHERE public_id = ?;`
+
+	queryFmtSelectDirectoryGroup = `
+		SELECT id, public_id, external_id, display_name, created_at, updated_at
+		FROM %s
+		WHERE public_id = ?;`
+
+	queryFmtSelectDirectoryGroups = `
+		SELECT id, public_id, external_id, display_name, created_at, updated_at
+		FROM %s
+		WHERE (? = '' OR display_name = ?) AND (? = '' OR external_id = ?)
+		ORDER BY id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtSelectDirectoryGroupsCount = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE (? = '' OR display_name = ?) AND (? = '' OR external_id = ?);`
+
+	queryFmtDeleteDirectoryGroup = `
+		DELETE FROM %s
+		WHERE public_id = ?;`
+
+	queryFmtInsertDirectoryGroupMember = `
+		INSERT INTO %s (group_id, user_id)
+		SELECT g.id, u.id
+		FROM %s g, %s u
+		WHERE g.public_id = ? AND u.public_id = ?;`
+
+	queryFmtSelectDirectoryGroupMembers = `
+		SELECT u.public_id, u.username
+		FROM %s u
+		INNER JOIN %s m ON m.user_id = u.id
+		INNER JOIN %s g ON g.id = m.group_id
+		WHERE g.public_id = ?
+		ORDER BY u.username;`
+
+	queryFmtDeleteDirectoryGroupMembersByGroup = `
+		DELETE FROM %s
+		WHERE group_id IN (SELECT id FROM %s WHERE public_id = ?);`
+
+	queryFmtDeleteDirectoryGroupMembersByUser = `
+		DELETE FROM %s
+		WHERE user_id IN (SELECT id FROM %s WHERE public_id = ?);`
+)
+
+const (
+	queryFmtInsertRecoveryCode = `
+		INSERT INTO %s (created_at, username, signature)
+		VALUES (?, ?, ?);`
+
+	queryFmtConsumeRecoveryCode = `
+		UPDATE %s
+		SET consumed_at = ?, consumed_ip = ?
+		WHERE signature = ? AND username = ? AND consumed_at IS NULL;`
+
+	queryFmtSelectRecoveryCodesCount = `
+		SELECT COUNT(id)
+		FROM %s
+		WHERE username = ? AND consumed_at IS NULL;`
+
+	queryFmtDeleteRecoveryCodes = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtSelectPhoneNumber = `
+		SELECT id, created_at, last_used_at, username, phone_number
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtUpsertPhoneNumber = `
+		REPLACE INTO %s (created_at, last_used_at, username, phone_number)
+		VALUES (?, ?, ?, ?);`
+
+	queryFmtUpsertPhoneNumberPostgreSQL = `
+		INSERT INTO %s (created_at, last_used_at, username, phone_number)
+		VALUES ($1, $2, $3, $4)
+			ON CONFLICT (username)
+			DO UPDATE SET created_at = $1, last_used_at = $2, phone_number = $4;`
+
+	queryFmtUpdatePhoneNumberRecordSignIn = `
+		UPDATE %s
+		SET last_used_at = ?
+		WHERE id = ?;`
+
+	queryFmtDeletePhoneNumber = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectPhoneNumbersEncryptedData = `
+		SELECT id, phone_number
+		FROM %s;`
+
+	queryFmtUpdatePhoneNumberEncryptedData = `
+		UPDATE %s
+		SET phone_number = ?
+		WHERE id = ?;`
+)
+
+const (
+	queryFmtInsertPushChallenge = `
+		INSERT INTO %s (challenge_id, created_at, expires_at, username, device, message, signature, request_ip, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectPushChallenge = `
+		SELECT id, challenge_id, created_at, expires_at, username, device, message, signature, request_ip, status, responded_at, responded_ip
+		FROM %s
+		WHERE challenge_id = ?;`
+
+	queryFmtUpdatePushChallengeResponse = `
+		UPDATE %s
+		SET status = ?, responded_at = ?, responded_ip = ?
+		WHERE challenge_id = ? AND signature = ? AND status = 0 AND expires_at > ?;`
+
+	queryFmtDeleteExpiredPushChallenges = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtInsertMobileDevice = `
+		INSERT INTO %s (public_id, username, name, platform, created_at, access_signature, access_expires_at, refresh_signature, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectMobileDevice = `
+		SELECT id, public_id, username, name, platform, created_at, last_used_at, access_signature, access_expires_at, refresh_signature, previous_refresh_signature, expires_at, push_provider, push_token, revoked_at
+		FROM %s
+		WHERE public_id = ?;`
+
+	queryFmtSelectMobileDeviceByAccessSignature = `
+		SELECT id, public_id, username, name, platform, created_at, last_used_at, access_signature, access_expires_at, refresh_signature, previous_refresh_signature, expires_at, push_provider, push_token, revoked_at
+		FROM %s
+		WHERE access_signature = ?;`
+
+	queryFmtSelectMobileDevicesPush = `
+		SELECT id, public_id, username, name, platform, created_at, last_used_at, access_signature, access_expires_at, refresh_signature, previous_refresh_signature, expires_at, push_provider, push_token, revoked_at
+		FROM %s
+		WHERE username = ? AND push_provider IS NOT NULL AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY id;`
+
+	queryFmtUpdateMobileDeviceTokens = `
+		UPDATE %s
+		SET access_signature = ?, access_expires_at = ?, refresh_signature = ?, previous_refresh_signature = ?, expires_at = ?, last_used_at = ?
+		WHERE public_id = ? AND refresh_signature = ? AND revoked_at IS NULL;`
+
+	queryFmtUpdateMobileDevicePush = `
+		UPDATE %s
+		SET push_provider = ?, push_token = ?
+		WHERE public_id = ? AND revoked_at IS NULL;`
+
+	queryFmtRevokeMobileDevice = `
+		UPDATE %s
+		SET revoked_at = ?
+		WHERE public_id = ? AND revoked_at IS NULL;`
+
+	queryFmtDeleteExpiredMobileDevices = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtInsertSAMLSession = `
+		INSERT INTO %s (session_index, entity_id, username, name_id, name_id_format, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectSAMLSession = `
+		SELECT id, session_index, entity_id, username, name_id, name_id_format, created_at, expires_at, revoked_at
+		FROM %s
+		WHERE session_index = ?;`
+
+	queryFmtRevokeSAMLSession = `
+		UPDATE %s
+		SET revoked_at = ?
+		WHERE session_index = ? AND revoked_at IS NULL;`
+
+	queryFmtRevokeSAMLSessionsByUsername = `
+		UPDATE %s
+		SET revoked_at = ?
+		WHERE username = ? AND revoked_at IS NULL;`
+
+	queryFmtDeleteExpiredSAMLSessions = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
 )
 
 const (
@@ -281,10 +788,25 @@ const (
 	queryFmtSelect1FAAuthenticationLogEntryByUsername = `
 		SELECT time, successful, username
 		FROM %s
-		WHERE time > ? AND username = ? AND auth_type = '1FA' AND banned = FALSE
+		WHERE time > ? AND username = ? AND auth_type IN ('1FA', 'Unban') AND banned = FALSE
 		ORDER BY time DESC
 		LIMIT ?
 		OFFSET ?;`
+
+	queryFmtSelectAuthenticationLogEntryByUsername = `
+		SELECT time, successful, banned, username, auth_type, remote_ip, request_uri, request_method
+		FROM %[1]s
+		WHERE username = ? AND (? = '' OR auth_type = ?)
+		ORDER BY time %[2]s, id %[2]s
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtSelectLatestSuccessful1FAAuthenticationLogEntryTimeByUsername = `
+		SELECT time
+		FROM %s
+		WHERE username = ? AND auth_type = '1FA' AND successful = TRUE
+		ORDER BY time DESC
+		LIMIT 1;`
 )
 
 const (
@@ -335,6 +857,16 @@ const (
 		FROM %s
 		WHERE challenge_id = ?;`
 
+	queryFmtSelectOAuth2ConsentSessionsByUsername = `
+		SELECT c.id, c.challenge_id, c.client_id, c.subject, c.authorized, c.granted, c.requested_at, c.responded_at,
+		c.form_data, c.requested_scopes, c.granted_scopes, c.requested_audience, c.granted_audience, c.preconfiguration
+		FROM %[1]s c
+		INNER JOIN %[2]s u ON u.identifier = c.subject
+		WHERE u.username = ? AND c.responded_at IS NOT NULL AND (? = '' OR c.client_id = ?)
+		ORDER BY c.responded_at %[3]s, c.id %[3]s
+		LIMIT ?
+		OFFSET ?;`
+
 	queryFmtInsertOAuth2ConsentSession = `
 		INSERT INTO %s (challenge_id, client_id, subject, authorized, granted, requested_at, responded_at,
 		form_data, requested_scopes, granted_scopes, requested_audience, granted_audience, preconfiguration)
@@ -355,6 +887,18 @@ const (
 		SET granted = TRUE
 		WHERE id = ? AND responded_at IS NOT NULL;`
 
+	queryFmtDeleteAbandonedOAuth2ConsentSessions = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE responded_at IS NULL AND requested_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS abandoned
+		);`
+
 	queryFmtSelectOAuth2Session = `
 		SELECT id, challenge_id, request_id, client_id, signature, subject, requested_at,
 		requested_scopes, granted_scopes, requested_audience, granted_audience,
@@ -408,6 +952,107 @@ const (
 	    form_data = ?, session_data = ?
 	WHERE id = ?;`
 
+	queryFmtSelectOAuth2DeviceCodeSession = `
+		SELECT id, request_id, client_id, signature, user_code_signature, status, subject, requested_at, checked_at,
+		requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, revoked, form_data, session_data
+		FROM %s
+		WHERE signature = ? AND revoked = FALSE;`
+
+	queryFmtSelectOAuth2DeviceCodeSessionByUserCodeSignature = `
+		SELECT id, request_id, client_id, signature, user_code_signature, status, subject, requested_at, checked_at,
+		requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, revoked, form_data, session_data
+		FROM %s
+		WHERE user_code_signature = ? AND revoked = FALSE;`
+
+	queryFmtInsertOAuth2DeviceCodeSession = `
+		INSERT INTO %s (request_id, client_id, signature, user_code_signature, status, subject, requested_at, checked_at,
+		requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, revoked, form_data, session_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateOAuth2DeviceCodeSession = `
+		UPDATE %s
+		SET status = ?, subject = ?, checked_at = ?, granted_scopes = ?, granted_audience = ?, session_data = ?
+		WHERE signature = ?;`
+
+	queryFmtSelectOAuth2CIBARequest = `
+		SELECT id, request_id, client_id, signature, status, subject, username, txid, delivery_mode, notified,
+		requested_at, expires_at, checked_at, authenticated_at, requested_scopes, granted_scopes,
+		requested_audience, granted_audience, active, form_data, session_data
+		FROM %s
+		WHERE signature = ?;`
+
+	queryFmtSelectOAuth2CIBARequestsPending = `
+		SELECT id, request_id, client_id, signature, status, subject, username, txid, delivery_mode, notified,
+		requested_at, expires_at, checked_at, authenticated_at, requested_scopes, granted_scopes,
+		requested_audience, granted_audience, active, form_data, session_data
+		FROM %s
+		WHERE active = TRUE AND expires_at > ? AND (status = 0 OR (delivery_mode = 'ping' AND notified = FALSE))
+		ORDER BY id
+		LIMIT ?;`
+
+	queryFmtInsertOAuth2CIBARequest = `
+		INSERT INTO %s (request_id, client_id, signature, status, subject, username, txid, delivery_mode, notified,
+		requested_at, expires_at, checked_at, authenticated_at, requested_scopes, granted_scopes,
+		requested_audience, granted_audience, active, form_data, session_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateOAuth2CIBARequestStatus = `
+		UPDATE %s
+		SET status = ?, authenticated_at = ?
+		WHERE signature = ? AND status = 0;`
+
+	queryFmtUpdateOAuth2CIBARequestCheckedAt = `
+		UPDATE %s
+		SET checked_at = ?
+		WHERE signature = ?;`
+
+	queryFmtUpdateOAuth2CIBARequestNotified = `
+		UPDATE %s
+		SET notified = TRUE
+		WHERE signature = ?;`
+
+	queryFmtDeleteExpiredOAuth2CIBARequests = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+
+	queryFmtInsertOAuth2TokenExchangeLineage = `
+		INSERT INTO %s (request_id, parent_request_id, client_id, parent_client_id, subject, exchanged_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectOAuth2TokenExchangeLineageByParentRequestID = `
+		SELECT id, request_id, parent_request_id, client_id, parent_client_id, subject, exchanged_at
+		FROM %s
+		WHERE parent_request_id = ?;`
+
+	queryFmtInsertOAuth2DynamicClient = `
+		INSERT INTO %s (client_id, registration_access_token_signature, client_secret, metadata, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateOAuth2DynamicClient = `
+		UPDATE %s
+		SET registration_access_token_signature = ?, client_secret = ?, metadata = ?, updated_at = ?
+		WHERE client_id = ?;`
+
+	queryFmtSelectOAuth2DynamicClient = `
+		SELECT id, client_id, registration_access_token_signature, client_secret, metadata, created_at, updated_at
+		FROM %s
+		WHERE client_id = ?;`
+
+	queryFmtDeleteOAuth2DynamicClient = `
+		DELETE FROM %s
+		WHERE client_id = ?;`
+
 	queryFmtSelectOAuth2BlacklistedJTI = `
 		SELECT id, signature, expires_at
 		FROM %s
@@ -417,6 +1062,18 @@ const (
 		REPLACE INTO %s (signature, expires_at)
 		VALUES(?, ?);`
 
+	queryFmtDeleteExpiredOAuth2BlacklistedJTIs = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE expires_at < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+
 	queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL = `
 		INSERT INTO %s (signature, expires_at)
 		VALUES ($1, $2)
@@ -452,3 +1109,85 @@ const (
 		SELECT id, service, sector_id, username, identifier
 		FROM %s;`
 )
+
+const (
+	queryFmtUpsertConfigurationFingerprint = `
+		REPLACE INTO %s (instance, fingerprint, version, updated_at)
+		VALUES(?, ?, ?, ?);`
+
+	queryFmtUpsertConfigurationFingerprintPostgreSQL = `
+		INSERT INTO %s (instance, fingerprint, version, updated_at)
+		VALUES ($1, $2, $3, $4)
+			ON CONFLICT (instance)
+			DO UPDATE SET fingerprint = $2, version = $3, updated_at = $4;`
+
+	queryFmtSelectConfigurationFingerprints = `
+		SELECT id, instance, fingerprint, version, updated_at
+		FROM %s
+		WHERE updated_at >= ?
+		ORDER BY instance ASC;`
+
+	queryFmtDeleteConfigurationFingerprints = `
+		DELETE FROM %s
+		WHERE updated_at < ?;`
+)
+
+const (
+	queryFmtSelectEncryptedDataBatch = `
+		SELECT id, %[2]s AS value
+		FROM %[1]s
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?;`
+
+	queryFmtUpdateEncryptedDataRotate = `
+		UPDATE %[1]s
+		SET %[2]s = ?
+		WHERE id = ? AND %[2]s = ?;`
+)
+
+const (
+	queryFmtInsertAuditEvent = `
+		INSERT INTO %s (event_id, time, type, result, username, remote_ip, user_agent, request_method, request_host, request_path, trace_id, details)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectAuditEvents = `
+		SELECT id, event_id, time, type, result, username, remote_ip, user_agent, request_method, request_host, request_path, trace_id, details
+		FROM %[1]s
+		WHERE time >= ? AND time < ? AND (? = '' OR username = ?) AND (? = '' OR type = ?)
+		ORDER BY time %[2]s, id %[2]s
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtDeleteAuditEvents = `
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id
+				FROM %[1]s
+				WHERE time < ?
+				ORDER BY id
+				LIMIT ?
+			) AS expired
+		);`
+)
+
+const (
+	queryFmtUpdateLeaderElectionLease = `
+		UPDATE %s
+		SET holder = ?, expires_at = ?
+		WHERE name = ? AND (holder = ? OR expires_at < ?);`
+
+	queryFmtSelectLeaderElectionLease = `
+		SELECT holder
+		FROM %s
+		WHERE name = ?;`
+
+	queryFmtInsertLeaderElectionLease = `
+		INSERT INTO %s (name, holder, expires_at)
+		VALUES (?, ?, ?);`
+
+	queryFmtDeleteLeaderElectionLease = `
+		DELETE FROM %s
+		WHERE name = ? AND holder = ?;`
+)
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveRecoveryCodes(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertRecoveryCode = fmt.Sprintf(queryFmtInsertRecoveryCode, tableRecoveryCode)
+	provider.sqlDeleteRecoveryCodes = fmt.Sprintf(queryFmtDeleteRecoveryCodes, tableRecoveryCode)
+
+	created := time.Unix(1700000000, 0)
+
+	codes := []model.RecoveryCode{
+		model.NewRecoveryCode("john", "ABCDE-FGHJK", created),
+		model.NewRecoveryCode("john", "LMNPQ-RSTUV", created),
+	}
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteRecoveryCodes)).
+		WithArgs("john").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertRecoveryCode)).
+		WithArgs(created, "john", provider.recoveryCodeSignature("john", "ABCDEFGHJK")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertRecoveryCode)).
+		WithArgs(created, "john", provider.recoveryCodeSignature("john", "LMNPQRSTUV")).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	primary.ExpectCommit()
+
+	assert.NoError(t, provider.SaveRecoveryCodes(context.Background(), "john", codes))
+
+	primary.ExpectBegin()
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteRecoveryCodes)).
+		WithArgs("john").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertRecoveryCode)).
+		WillReturnError(errors.New("duplicate key"))
+	primary.ExpectRollback()
+
+	assert.EqualError(t, provider.SaveRecoveryCodes(context.Background(), "john", codes), "rollback due to error: error inserting recovery code for user 'john': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldConsumeRecoveryCodeOnce(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlConsumeRecoveryCode = fmt.Sprintf(queryFmtConsumeRecoveryCode, tableRecoveryCode)
+
+	consumed := time.Unix(1700000000, 0)
+	ip := model.NewNullIP(net.ParseIP("192.168.0.1"))
+	signature := provider.recoveryCodeSignature("john", "ABCDE-FGHJK")
+
+	assert.Equal(t, signature, provider.recoveryCodeSignature("john", "abcde fghjk"))
+	assert.NotEqual(t, signature, provider.recoveryCodeSignature("jane", "ABCDE-FGHJK"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeRecoveryCode)).
+		WithArgs(consumed, ip, signature, "john").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ok, err := provider.ConsumeRecoveryCode(context.Background(), "john", "abcde-fghjk", consumed, ip)
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeRecoveryCode)).
+		WithArgs(consumed, ip, signature, "john").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	ok, err = provider.ConsumeRecoveryCode(context.Background(), "john", "ABCDE-FGHJK", consumed, ip)
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlConsumeRecoveryCode)).
+		WillReturnError(errors.New("table is locked"))
+
+	ok, err = provider.ConsumeRecoveryCode(context.Background(), "john", "ABCDE-FGHJK", consumed, ip)
+
+	assert.EqualError(t, err, "error updating recovery code (consume) for user 'john': table is locked")
+	assert.False(t, ok)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldCountRecoveryCodes(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectRecoveryCodesCount = fmt.Sprintf(queryFmtSelectRecoveryCodesCount, tableRecoveryCode)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectRecoveryCodesCount)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	count, err := provider.CountRecoveryCodes(context.Background(), "john")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, count)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectRecoveryCodesCount)).
+		WillReturnError(errors.New("bad connection"))
+
+	count, err = provider.CountRecoveryCodes(context.Background(), "john")
+
+	assert.EqualError(t, err, "error selecting recovery codes count for user 'john': bad connection")
+	assert.Equal(t, 0, count)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteRecoveryCodes(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteRecoveryCodes = fmt.Sprintf(queryFmtDeleteRecoveryCodes, tableRecoveryCode)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteRecoveryCodes)).
+		WithArgs("john").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+
+	assert.NoError(t, provider.DeleteRecoveryCodes(context.Background(), "john"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteRecoveryCodes)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.DeleteRecoveryCodes(context.Background(), "john"), "error deleting recovery codes for user 'john': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// sqlReplica is a read-only replica of the database used by a SQLProvider.
+type sqlReplica struct {
+	db      *sqlx.DB
+	address string
+	errOpen error
+}
+
+// addReplica opens a read-only replica of the database using the same driver as the primary.
+func (p *SQLProvider) addReplica(address, dataSourceName string) {
+	db, err := sqlOpen(p.config, fmt.Sprintf("%s (replica %s)", schema.CircuitBreakerDependencyStorage, address), p.driverName, dataSourceName)
+
+	p.replicas = append(p.replicas, &sqlReplica{db: db, address: address, errOpen: err})
+}
+
+// addReplicaConnector opens a read-only replica of the database using the connector.
+func (p *SQLProvider) addReplicaConnector(address string, connector driver.Connector) {
+	db := sqlOpenConnector(p.config, fmt.Sprintf("%s (replica %s)", schema.CircuitBreakerDependencyStorage, address), p.driverName, connector)
+
+	p.replicas = append(p.replicas, &sqlReplica{db: db, address: address})
+}
+
+// replicaCheck pings every replica and logs the replicas which can't be reached. The replicas are not required to be
+// available as the reads fail over to the primary.
+func (p *SQLProvider) replicaCheck(ctx context.Context) (err error) {
+	for _, replica := range p.replicas {
+		if replica.errOpen != nil {
+			return fmt.Errorf("error opening database replica '%s': %w", replica.address, replica.errOpen)
+		}
+
+		if err = replica.db.PingContext(ctx); err != nil {
+			p.log.WithError(err).WithField("replica", replica.address).Warn("Error pinging the storage replica, reads will fail over to the other replicas or the primary until it's available")
+		}
+	}
+
+	return nil
+}
+
+// replicaClose closes every replica.
+func (p *SQLProvider) replicaClose() (err error) {
+	for _, replica := range p.replicas {
+		if replica.db == nil {
+			continue
+		}
+
+		if err = replica.db.Close(); err != nil {
+			return fmt.Errorf("error closing database replica '%s': %w", replica.address, err)
+		}
+	}
+
+	return nil
+}
+
+// read executes a read query which tolerates replication lag. The replicas are tried in turn starting at the next
+// replica in round-robin order, and the query fails over to the primary if none of the replicas could execute it.
+// Errors which would equally occur on the primary such as missing rows or a canceled context are returned as is.
+func (p *SQLProvider) read(ctx context.Context, fn func(db *sqlx.DB) (err error)) (err error) {
+	if n := len(p.replicas); n != 0 {
+		start := int(atomic.AddUint64(&p.replicaNext, 1) % uint64(n))
+
+		for i := 0; i < n; i++ {
+			replica := p.replicas[(start+i)%n]
+
+			if err = fn(replica.db); err == nil || !isReplicaFailure(ctx, err) {
+				return err
+			}
+
+			p.log.WithError(err).WithField("replica", replica.address).Debug("Error executing the read query on the storage replica, failing over")
+		}
+	}
+
+	return fn(p.db)
+}
+
+// readGetContext is the equivalent of sqlx.DB.GetContext using the replicas as per read.
+func (p *SQLProvider) readGetContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	return p.read(ctx, func(db *sqlx.DB) (err error) {
+		return db.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// readSelectContext is the equivalent of sqlx.DB.SelectContext using the replicas as per read. The dest must be a
+// pointer to a slice.
+func (p *SQLProvider) readSelectContext(ctx context.Context, dest any, query string, args ...any) (err error) {
+	return p.read(ctx, func(db *sqlx.DB) (err error) {
+		// A failed attempt may have scanned some of the rows before it failed.
+		if v := reflect.ValueOf(dest).Elem(); v.Len() != 0 {
+			v.SetLen(0)
+		}
+
+		return db.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+func isReplicaFailure(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !errors.Is(err, sql.ErrNoRows)
+}
+
+// replicaTLS returns the TLS configuration for a replica. The server name defaults to the hostname of the primary
+// during validation so it's replaced with the hostname of the replica unless it was explicitly configured otherwise.
+func replicaTLS(config *schema.TLS, primary, replica *schema.AddressTCP) *schema.TLS {
+	if config == nil || primary == nil || config.ServerName != primary.Hostname() {
+		return config
+	}
+
+	tlsConfig := *config
+
+	tlsConfig.ServerName = replica.Hostname()
+
+	return &tlsConfig
+}
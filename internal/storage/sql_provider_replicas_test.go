@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestSQLProviderReadShouldUsePrimaryWithoutReplicas(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	primary.ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("primary"))
+
+	var value string
+
+	require.NoError(t, provider.readGetContext(context.Background(), &value, "SELECT value FROM example"))
+	assert.Equal(t, "primary", value)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderReadShouldRoundRobinReplicas(t *testing.T) {
+	provider, primary, replicas := newTestSQLProviderReplicas(t, 2)
+
+	replicas[1].ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("replica2"))
+	replicas[0].ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("replica1"))
+
+	var value string
+
+	require.NoError(t, provider.readGetContext(context.Background(), &value, "SELECT value FROM example"))
+	assert.Equal(t, "replica2", value)
+
+	require.NoError(t, provider.readGetContext(context.Background(), &value, "SELECT value FROM example"))
+	assert.Equal(t, "replica1", value)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+
+	for _, replica := range replicas {
+		assert.NoError(t, replica.ExpectationsWereMet())
+	}
+}
+
+func TestSQLProviderReadShouldFailOverToPrimary(t *testing.T) {
+	provider, primary, replicas := newTestSQLProviderReplicas(t, 2)
+
+	replicas[1].ExpectQuery("SELECT value FROM example").WillReturnError(errors.New("connection refused"))
+	replicas[0].ExpectQuery("SELECT value FROM example").WillReturnError(errors.New("connection refused"))
+	primary.ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("primary"))
+
+	var value string
+
+	require.NoError(t, provider.readGetContext(context.Background(), &value, "SELECT value FROM example"))
+	assert.Equal(t, "primary", value)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+
+	for _, replica := range replicas {
+		assert.NoError(t, replica.ExpectationsWereMet())
+	}
+}
+
+func TestSQLProviderReadShouldNotFailOverNoRows(t *testing.T) {
+	provider, primary, replicas := newTestSQLProviderReplicas(t, 1)
+
+	replicas[0].ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}))
+
+	var value string
+
+	assert.ErrorIs(t, provider.readGetContext(context.Background(), &value, "SELECT value FROM example"), sql.ErrNoRows)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replicas[0].ExpectationsWereMet())
+}
+
+func TestSQLProviderReadSelectShouldDiscardPartialRows(t *testing.T) {
+	provider, primary, replicas := newTestSQLProviderReplicas(t, 1)
+
+	replicas[0].ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("replica1").AddRow("replica2").RowError(1, errors.New("connection reset")))
+	primary.ExpectQuery("SELECT value FROM example").WillReturnRows(sqlmock.NewRows([]string{"value"}).AddRow("primary1").AddRow("primary2"))
+
+	values := make([]string, 0, 2)
+
+	require.NoError(t, provider.readSelectContext(context.Background(), &values, "SELECT value FROM example"))
+	assert.Equal(t, []string{"primary1", "primary2"}, values)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replicas[0].ExpectationsWereMet())
+}
+
+func TestReplicaTLS(t *testing.T) {
+	primary := &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues(schema.AddressSchemeTCP, "primary", 5432)}
+	replica := &schema.AddressTCP{Address: schema.NewAddressFromNetworkValues(schema.AddressSchemeTCP, "replica", 5432)}
+
+	assert.Nil(t, replicaTLS(nil, primary, replica))
+
+	config := &schema.TLS{ServerName: "primary"}
+
+	actual := replicaTLS(config, primary, replica)
+
+	assert.Equal(t, "replica", actual.ServerName)
+	assert.Equal(t, "primary", config.ServerName)
+
+	config = &schema.TLS{ServerName: "database.example.com"}
+
+	assert.Equal(t, config, replicaTLS(config, primary, replica))
+}
+
+func newTestSQLProviderReplicas(t *testing.T, n int) (provider *SQLProvider, primary sqlmock.Sqlmock, replicas []sqlmock.Sqlmock) {
+	db, primary, err := sqlmock.New()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	provider = &SQLProvider{db: sqlx.NewDb(db, "sqlmock"), log: logrus.New()}
+
+	for i := 0; i < n; i++ {
+		rdb, replica, err := sqlmock.New()
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			_ = rdb.Close()
+		})
+
+		provider.replicas = append(provider.replicas, &sqlReplica{db: sqlx.NewDb(rdb, "sqlmock"), address: "tcp://replica"})
+
+		replicas = append(replicas, replica)
+	}
+
+	return provider, primary, replicas
+}
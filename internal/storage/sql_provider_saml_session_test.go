@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestSQLProviderShouldSaveSAMLSession(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertSAMLSession = fmt.Sprintf(queryFmtInsertSAMLSession, tableSAMLSession)
+
+	created := time.Unix(1700000000, 0)
+
+	session := model.SAMLSession{
+		SessionIndex: "_abc",
+		EntityID:     "https://app.example.com",
+		Username:     "john",
+		NameID:       "john@example.com",
+		NameIDFormat: "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress",
+		CreatedAt:    created,
+		ExpiresAt:    created.Add(time.Hour * 8),
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertSAMLSession)).
+		WithArgs("_abc", "https://app.example.com", "john", "john@example.com", "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress", created, created.Add(time.Hour*8)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveSAMLSession(context.Background(), session))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertSAMLSession)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveSAMLSession(context.Background(), session), "error inserting saml session with session index '_abc' for user 'john': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadSAMLSession(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectSAMLSession = fmt.Sprintf(queryFmtSelectSAMLSession, tableSAMLSession)
+
+	created := time.Unix(1700000000, 0)
+
+	columns := []string{"id", "session_index", "entity_id", "username", "name_id", "name_id_format", "created_at", "expires_at", "revoked_at"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSAMLSession)).
+		WithArgs("_abc").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "_abc", "https://app.example.com", "john", "john", "urn:oasis:names:tc:SAML:1.1:nameid-format:unspecified", created, created.Add(time.Hour), nil))
+
+	session, err := provider.LoadSAMLSession(context.Background(), "_abc")
+
+	assert.NoError(t, err)
+	require.NotNil(t, session)
+	assert.Equal(t, "https://app.example.com", session.EntityID)
+	assert.Equal(t, "john", session.Username)
+	assert.False(t, session.IsRevoked())
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSAMLSession)).
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	session, err = provider.LoadSAMLSession(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, ErrNoSAMLSession)
+	assert.Nil(t, session)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSAMLSession)).
+		WithArgs("broken").
+		WillReturnError(errors.New("bad connection"))
+
+	session, err = provider.LoadSAMLSession(context.Background(), "broken")
+
+	assert.EqualError(t, err, "error selecting saml session with session index 'broken': bad connection")
+	assert.Nil(t, session)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRevokeAndDeleteSAMLSessions(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlRevokeSAMLSession = fmt.Sprintf(queryFmtRevokeSAMLSession, tableSAMLSession)
+	provider.sqlRevokeSAMLSessionsByUsername = fmt.Sprintf(queryFmtRevokeSAMLSessionsByUsername, tableSAMLSession)
+	provider.sqlDeleteExpiredSAMLSessions = fmt.Sprintf(queryFmtDeleteExpiredSAMLSessions, tableSAMLSession)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeSAMLSession)).
+		WithArgs(now, "_abc").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.RevokeSAMLSession(context.Background(), "_abc", now))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeSAMLSession)).
+		WithArgs(now, "_abc").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, provider.RevokeSAMLSession(context.Background(), "_abc", now), ErrNoSAMLSession)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeSAMLSessionsByUsername)).
+		WithArgs(now, "john").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, provider.RevokeSAMLSessionsByUsername(context.Background(), "john", now))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlRevokeSAMLSessionsByUsername)).
+		WithArgs(now, "john").
+		WillReturnError(errors.New("table is locked"))
+
+	assert.EqualError(t, provider.RevokeSAMLSessionsByUsername(context.Background(), "john", now), "error revoking saml sessions for user 'john': table is locked")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredSAMLSessions)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := provider.DeleteExpiredSAMLSessions(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredSAMLSessions)).
+		WithArgs(now, 100).
+		WillReturnError(errors.New("table is locked"))
+
+	deleted, err = provider.DeleteExpiredSAMLSessions(context.Background(), now, 100)
+
+	assert.EqualError(t, err, "error deleting expired saml sessions: table is locked")
+	assert.Equal(t, int64(0), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
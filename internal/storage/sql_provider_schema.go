@@ -86,6 +86,25 @@ func (p *SQLProvider) SchemaLatestVersion() (version int, err error) {
 	return latestMigrationVersion(p.name)
 }
 
+// SchemaCompatibleVersion returns the oldest latest schema version a version of Authelia must know about in order to
+// operate against the current schema. This is lower than the current schema version when the migrations applied since
+// that version have only expanded the schema.
+func (p *SQLProvider) SchemaCompatibleVersion(ctx context.Context) (version int, err error) {
+	if version, err = p.SchemaVersion(ctx); err != nil {
+		return -2, err
+	}
+
+	if version < schemaVersionCompatible {
+		return version, nil
+	}
+
+	if err = p.db.GetContext(ctx, &version, p.sqlSelectLatestMigrationCompatible); err != nil {
+		return -2, err
+	}
+
+	return version, nil
+}
+
 // SchemaMigrationHistory returns the storage provider migration history rows.
 func (p *SQLProvider) SchemaMigrationHistory(ctx context.Context) (migrations []model.Migration, err error) {
 	rows, err := p.db.QueryxContext(ctx, p.sqlSelectMigrations)
@@ -154,7 +173,9 @@ func (p *SQLProvider) SchemaMigrate(ctx context.Context, up bool, version int) (
 		conn SQLXConnection
 	)
 
-	if p.name != providerMySQL {
+	// MySQL implicitly commits schema changes and CockroachDB does not support schema changes being mixed with other
+	// statements in a transaction, so the migrations are applied without a transaction for these providers.
+	if p.name != providerMySQL && p.name != providerCockroachDB {
 		if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
 			return fmt.Errorf("failed to begin transaction: %w", err)
 		}
@@ -271,7 +292,13 @@ func (p *SQLProvider) schemaMigrateFinalize(ctx context.Context, conn SQLXConnec
 		return nil
 	}
 
-	if _, err = conn.ExecContext(ctx, p.sqlInsertMigration, time.Now(), migration.Before(), migration.After(), utils.Version()); err != nil {
+	if migration.After() >= schemaVersionCompatible {
+		_, err = conn.ExecContext(ctx, p.sqlInsertMigrationWithCompatible, time.Now(), migration.Before(), migration.After(), schemaCompatibleVersion(migration.After()), utils.Version())
+	} else {
+		_, err = conn.ExecContext(ctx, p.sqlInsertMigration, time.Now(), migration.Before(), migration.After(), utils.Version())
+	}
+
+	if err != nil {
 		return fmt.Errorf("failed inserting migration record: %w", err)
 	}
 
@@ -322,6 +349,37 @@ func (p *SQLProvider) schemaLatestMigration(ctx context.Context) (migration *mod
 	return migration, nil
 }
 
+// schemaCompatibilityCheck returns true if the current schema version is newer than the latest schema version known to
+// this version of Authelia but the schema is still compatible with this version, which is expected while a newer version
+// is being rolled out. An error is returned if the current schema is newer and not compatible with this version.
+func (p *SQLProvider) schemaCompatibilityCheck(ctx context.Context) (compatible bool, err error) {
+	var current, latest, version int
+
+	if latest, err = p.SchemaLatestVersion(); err != nil {
+		return false, err
+	}
+
+	if current, err = p.SchemaVersion(ctx); err != nil {
+		return false, err
+	}
+
+	if current <= latest {
+		return false, nil
+	}
+
+	if version, err = p.SchemaCompatibleVersion(ctx); err != nil {
+		return false, fmt.Errorf("failed to determine the compatible schema version: %w", err)
+	}
+
+	if version > latest {
+		return false, fmt.Errorf(errFmtSchemaCurrentIncompatible, current, version, latest)
+	}
+
+	p.log.Warnf(logFmtSchemaCurrentCompatible, current, latest)
+
+	return true, nil
+}
+
 func schemaMigrateChecks(providerName string, up bool, targetVersion, currentVersion int) (err error) {
 	switch {
 	case currentVersion == -1:
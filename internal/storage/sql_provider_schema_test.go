@@ -1,9 +1,13 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -139,3 +143,95 @@ func TestSchemaVersionToString(t *testing.T) {
 	assert.Equal(t, "1", SchemaVersionToString(1))
 	assert.Equal(t, "2", SchemaVersionToString(2))
 }
+
+func TestSchemaCompatibleVersion(t *testing.T) {
+	assert.Equal(t, 0, schemaCompatibleVersion(0))
+	assert.Equal(t, 1, schemaCompatibleVersion(1))
+	assert.Equal(t, 21, schemaCompatibleVersion(21))
+	assert.Equal(t, 21, schemaCompatibleVersion(22))
+	assert.Equal(t, 21, schemaCompatibleVersion(23))
+	assert.Equal(t, 21, schemaCompatibleVersion(24))
+	assert.Equal(t, 21, schemaCompatibleVersion(25))
+	assert.Equal(t, 21, schemaCompatibleVersion(26))
+	assert.Equal(t, 21, schemaCompatibleVersion(27))
+	assert.Equal(t, 21, schemaCompatibleVersion(28))
+	assert.Equal(t, 21, schemaCompatibleVersion(29))
+	assert.Equal(t, 21, schemaCompatibleVersion(30))
+	assert.Equal(t, 21, schemaCompatibleVersion(31))
+	assert.Equal(t, 21, schemaCompatibleVersion(32))
+	assert.Equal(t, 21, schemaCompatibleVersion(33))
+	assert.Equal(t, 21, schemaCompatibleVersion(34))
+	assert.Equal(t, 21, schemaCompatibleVersion(35))
+	assert.Equal(t, 21, schemaCompatibleVersion(36))
+}
+
+func TestSQLProviderSchemaCompatibilityCheck(t *testing.T) {
+	testCases := []struct {
+		name       string
+		current    int
+		compatible int
+		expected   bool
+		err        string
+	}{
+		{
+			"ShouldNotBeCompatibleWhenCurrent",
+			LatestVersion,
+			-1,
+			false,
+			"",
+		},
+		{
+			"ShouldBeCompatibleWhenNewerExpandOnly",
+			LatestVersion + 1,
+			LatestVersion,
+			true,
+			"",
+		},
+		{
+			"ShouldErrWhenNewerContract",
+			LatestVersion + 2,
+			LatestVersion + 1,
+			false,
+			fmt.Sprintf(errFmtSchemaCurrentIncompatible, LatestVersion+2, LatestVersion+1, LatestVersion),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+			provider.name = providerSQLite
+			provider.sqlSelectExistingTables = querySQLiteSelectExistingTables
+			provider.sqlSelectLatestMigration = fmt.Sprintf(queryFmtSelectLatestMigration, tableMigrations)
+			provider.sqlSelectLatestMigrationCompatible = fmt.Sprintf(queryFmtSelectLatestMigrationCompatible, tableMigrations)
+
+			expectVersion := func() {
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectExistingTables)).
+					WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow(tableMigrations))
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLatestMigration)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "applied", "version_before", "version_after", "application_version"}).
+						AddRow(1, time.Unix(1700000000, 0), tc.current-1, tc.current, "v4.39.0"))
+			}
+
+			expectVersion()
+
+			if tc.compatible != -1 {
+				expectVersion()
+
+				primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectLatestMigrationCompatible)).
+					WillReturnRows(sqlmock.NewRows([]string{"version_compatible"}).AddRow(tc.compatible))
+			}
+
+			compatible, err := provider.schemaCompatibilityCheck(context.Background())
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, compatible)
+			assert.NoError(t, primary.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLProviderShouldSaveAndLoadSession(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpsertSession = fmt.Sprintf(queryFmtUpsertSession, tableSessions)
+	provider.sqlSelectSession = fmt.Sprintf(queryFmtSelectSession, tableSessions)
+
+	now := time.Unix(1700000000, 0)
+	expires := now.Add(time.Hour)
+	signature := SessionSignature([]byte("abc123"))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpsertSession)).
+		WithArgs(signature, expires, []byte("data")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveSession(context.Background(), []byte("abc123"), []byte("data"), expires))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpsertSession)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.SaveSession(context.Background(), []byte("abc123"), []byte("data"), expires), fmt.Sprintf("error upserting session with signature '%s': bad connection", signature))
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSession)).
+		WithArgs(signature, now).
+		WillReturnRows(sqlmock.NewRows([]string{"data"}).AddRow([]byte("data")))
+
+	data, err := provider.LoadSession(context.Background(), []byte("abc123"), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSession)).
+		WithArgs(signature, now).
+		WillReturnError(sql.ErrNoRows)
+
+	data, err = provider.LoadSession(context.Background(), []byte("abc123"), now)
+
+	assert.Nil(t, data)
+	assert.ErrorIs(t, err, ErrNoSession)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectSession)).
+		WillReturnError(errors.New("bad connection"))
+
+	data, err = provider.LoadSession(context.Background(), []byte("abc123"), now)
+
+	assert.Nil(t, data)
+	assert.EqualError(t, err, fmt.Sprintf("error selecting session with signature '%s': bad connection", signature))
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldRegenerateDeleteAndCountSessions(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlUpdateSessionSignature = fmt.Sprintf(queryFmtUpdateSessionSignature, tableSessions)
+	provider.sqlDeleteSession = fmt.Sprintf(queryFmtDeleteSession, tableSessions)
+	provider.sqlSelectCountSessions = fmt.Sprintf(queryFmtSelectCountSessions, tableSessions)
+	provider.sqlDeleteExpiredSessions = fmt.Sprintf(queryFmtDeleteExpiredSessions, tableSessions)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlUpdateSessionSignature)).
+		WithArgs(SessionSignature([]byte("def456")), now.Add(time.Hour), SessionSignature([]byte("abc123"))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.RegenerateSession(context.Background(), []byte("abc123"), []byte("def456"), now.Add(time.Hour)))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteSession)).
+		WithArgs(SessionSignature([]byte("def456"))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeleteSession(context.Background(), []byte("def456")))
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountSessions)).
+		WithArgs(now).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	count, err := provider.CountSessions(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, count)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectCountSessions)).
+		WillReturnError(errors.New("bad connection"))
+
+	count, err = provider.CountSessions(context.Background(), now)
+
+	assert.Equal(t, 0, count)
+	assert.EqualError(t, err, "error counting sessions: bad connection")
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredSessions)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := provider.DeleteExpiredSessions(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
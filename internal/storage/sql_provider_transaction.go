@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+)
+
+// transaction begins a transaction and executes the function within it, committing the transaction if the function
+// succeeded and rolling it back otherwise. If the provider is configured to retry transactions and the transaction
+// failed with a serialization failure, the transaction including the function is executed again, so the function must
+// not have any side effects other than the statements executed within the transaction.
+func (p *SQLProvider) transaction(ctx context.Context, fn func(tx *sqlx.Tx) (err error)) (err error) {
+	for attempt := 0; ; attempt++ {
+		if err = p.transactionAttempt(ctx, fn); err == nil || attempt >= p.retries || !isSerializationFailure(err) {
+			return err
+		}
+
+		p.log.WithError(err).WithField("attempt", attempt+1).Debug("Retrying the transaction as it failed due to a serialization failure")
+
+		timer := time.NewTimer(transactionRetryBackoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (p *SQLProvider) transactionAttempt(ctx context.Context, fn func(tx *sqlx.Tx) (err error)) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	if err = fn(tx); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return fmt.Errorf("rollback error %v: rollback due to error: %w", rerr, err)
+		}
+
+		return fmt.Errorf("rollback due to error: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// transactionRetryBackoff returns the duration to wait before the next attempt which doubles with each attempt.
+func transactionRetryBackoff(attempt int) time.Duration {
+	if attempt >= 7 {
+		return transactionRetryBackoffMaximum
+	}
+
+	return min(transactionRetryBackoffInitial<<attempt, transactionRetryBackoffMaximum)
+}
+
+// isSerializationFailure returns true if the error indicates the transaction can't be serialized with the concurrent
+// transactions and may succeed if it's retried.
+func isSerializationFailure(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == sqlStateSerializationFailure
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLProviderTransactionShouldRetrySerializationFailures(t *testing.T) {
+	testCases := []struct {
+		name     string
+		retries  int
+		failures int
+		attempts int
+		err      string
+	}{
+		{"ShouldSucceedWithoutRetries", 0, 0, 1, ""},
+		{"ShouldSucceedAfterRetry", 2, 2, 3, ""},
+		{"ShouldErrWhenRetriesExhausted", 1, 2, 2, "error committing transaction: ERROR: restart transaction (SQLSTATE 40001)"},
+		{"ShouldErrWithoutRetries", 0, 1, 1, "error committing transaction: ERROR: restart transaction (SQLSTATE 40001)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+
+			defer db.Close()
+
+			provider := &SQLProvider{db: sqlx.NewDb(db, "sqlmock"), retries: tc.retries, log: logrus.New()}
+
+			for i := 0; i < tc.attempts; i++ {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE example").WillReturnResult(sqlmock.NewResult(0, 1))
+
+				if i < tc.failures {
+					mock.ExpectCommit().WillReturnError(&pgconn.PgError{Severity: "ERROR", Code: sqlStateSerializationFailure, Message: "restart transaction"})
+				} else {
+					mock.ExpectCommit()
+				}
+			}
+
+			attempts := 0
+
+			err = provider.transaction(context.Background(), func(tx *sqlx.Tx) (err error) {
+				attempts++
+
+				_, err = tx.Exec("UPDATE example")
+
+				return err
+			})
+
+			assert.Equal(t, tc.attempts, attempts)
+			assert.NoError(t, mock.ExpectationsWereMet())
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSQLProviderTransactionShouldNotRetryOtherErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	provider := &SQLProvider{db: sqlx.NewDb(db, "sqlmock"), retries: 5, log: logrus.New()}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = provider.transaction(context.Background(), func(tx *sqlx.Tx) (err error) {
+		return errors.New("bad value")
+	})
+
+	assert.EqualError(t, err, "rollback due to error: bad value")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionRetryBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Millisecond, transactionRetryBackoff(0))
+	assert.Equal(t, 20*time.Millisecond, transactionRetryBackoff(1))
+	assert.Equal(t, 640*time.Millisecond, transactionRetryBackoff(6))
+	assert.Equal(t, time.Second, transactionRetryBackoff(7))
+	assert.Equal(t, time.Second, transactionRetryBackoff(100))
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func TestSessionSignature(t *testing.T) {
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", SessionSignature([]byte("hello")))
+	assert.NotEqual(t, SessionSignature([]byte("hello")), SessionSignature([]byte("hello2")))
+}
+
+func TestSQLProviderShouldSaveUserSessionIndex(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertUserSessionIndex = fmt.Sprintf(queryFmtInsertUserSessionIndex, tableUserSessionIndex)
+
+	publicID := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+	created := time.Unix(1700000000, 0)
+
+	index := model.UserSessionIndex{
+		PublicID:  publicID,
+		Username:  "john",
+		Domain:    "example.com",
+		UserAgent: "Mozilla/5.0",
+		CreatedAt: created,
+		SessionID: []byte("hello"),
+	}
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertUserSessionIndex)).
+		WithArgs(publicID.String(), SessionSignature([]byte("hello")), "john", "example.com", nil, "Mozilla/5.0", created, testEncryptedWith{key: provider.keys.encryption, value: "hello"}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveUserSessionIndex(context.Background(), index))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertUserSessionIndex)).
+		WillReturnError(errors.New("duplicate key"))
+
+	assert.EqualError(t, provider.SaveUserSessionIndex(context.Background(), index), "error inserting user session index for user 'john' with signature '2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824': duplicate key")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldLoadUserSessionIndexes(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlSelectUserSessionIndex = fmt.Sprintf(queryFmtSelectUserSessionIndex, tableUserSessionIndex)
+	provider.sqlSelectUserSessionIndexByUsername = fmt.Sprintf(queryFmtSelectUserSessionIndexByUsername, tableUserSessionIndex)
+
+	publicID := uuid.MustParse("5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e")
+	created := time.Unix(1700000000, 0)
+
+	sessionID, err := utils.Encrypt([]byte("hello"), &provider.keys.encryption)
+	require.NoError(t, err)
+
+	columns := []string{"id", "public_id", "signature", "username", "domain", "remote_ip", "user_agent", "created_at", "session_id"}
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionIndexByUsername)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), SessionSignature([]byte("hello")), "john", "example.com", "192.168.0.1", "Mozilla/5.0", created, sessionID))
+
+	indexes, err := provider.LoadUserSessionIndexes(context.Background(), "john")
+
+	assert.NoError(t, err)
+	require.Len(t, indexes, 1)
+	assert.Equal(t, publicID, indexes[0].PublicID)
+	assert.Equal(t, "example.com", indexes[0].Domain)
+	assert.Equal(t, "192.168.0.1", indexes[0].RemoteIP.IP.String())
+	assert.Equal(t, "Mozilla/5.0", indexes[0].UserAgent)
+	assert.Equal(t, []byte("hello"), indexes[0].SessionID)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionIndex)).
+		WithArgs("john", publicID.String()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), SessionSignature([]byte("hello")), "john", "example.com", nil, "", created, sessionID))
+
+	index, err := provider.LoadUserSessionIndex(context.Background(), "john", publicID)
+
+	assert.NoError(t, err)
+	require.NotNil(t, index)
+	assert.Equal(t, []byte("hello"), index.SessionID)
+	assert.Nil(t, index.RemoteIP.IP)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionIndex)).
+		WithArgs("john", publicID.String()).
+		WillReturnError(sql.ErrNoRows)
+
+	index, err = provider.LoadUserSessionIndex(context.Background(), "john", publicID)
+
+	assert.ErrorIs(t, err, ErrNoUserSessionIndex)
+	assert.Nil(t, index)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionIndexByUsername)).
+		WithArgs("john").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, publicID.String(), SessionSignature([]byte("hello")), "john", "example.com", nil, "", created, []byte("invalid")))
+
+	indexes, err = provider.LoadUserSessionIndexes(context.Background(), "john")
+
+	assert.EqualError(t, err, "error decrypting the session id of the user session index with id '5f2b1c1e-9d47-4d6b-a6a8-2c5f8d0c8d2e' for user 'john': cipher: message authentication failed")
+	assert.Nil(t, indexes)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldDeleteUserSessionIndex(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlDeleteUserSessionIndex = fmt.Sprintf(queryFmtDeleteUserSessionIndex, tableUserSessionIndex)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteUserSessionIndex)).
+		WithArgs(SessionSignature([]byte("hello"))).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, provider.DeleteUserSessionIndex(context.Background(), []byte("hello")))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteUserSessionIndex)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.DeleteUserSessionIndex(context.Background(), []byte("hello")), "error deleting user session index with signature '2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824': bad connection")
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestSQLProviderShouldSaveAndLoadUserSessionRevocations(t *testing.T) {
+	provider, primary, _ := newTestSQLProviderReplicas(t, 0)
+
+	provider.sqlInsertUserSessionRevocation = fmt.Sprintf(queryFmtInsertUserSessionRevocation, tableUserSessionRevocation)
+	provider.sqlSelectUserSessionRevocations = fmt.Sprintf(queryFmtSelectUserSessionRevocations, tableUserSessionRevocation)
+	provider.sqlDeleteExpiredUserSessionRevocations = fmt.Sprintf(queryFmtDeleteExpiredUserSessionRevocations, tableUserSessionRevocation)
+
+	now := time.Unix(1700000000, 0)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertUserSessionRevocation)).
+		WithArgs(SessionSignature([]byte("hello")), now, now.Add(time.Hour)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, provider.SaveUserSessionRevocation(context.Background(), []byte("hello"), now, now.Add(time.Hour)))
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlInsertUserSessionRevocation)).
+		WillReturnError(errors.New("bad connection"))
+
+	assert.EqualError(t, provider.SaveUserSessionRevocation(context.Background(), []byte("hello"), now, now.Add(time.Hour)), "error inserting user session revocation with signature '2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824': bad connection")
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionRevocations)).
+		WithArgs(now.Add(-time.Minute), now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "signature", "revoked_at", "expires_at"}).
+			AddRow(1, SessionSignature([]byte("hello")), now, now.Add(time.Hour)))
+
+	revocations, err := provider.LoadUserSessionRevocations(context.Background(), now.Add(-time.Minute), now)
+
+	assert.NoError(t, err)
+	require.Len(t, revocations, 1)
+	assert.Equal(t, SessionSignature([]byte("hello")), revocations[0].Signature)
+	assert.Equal(t, now.Add(time.Hour), revocations[0].ExpiresAt)
+
+	primary.ExpectQuery(regexp.QuoteMeta(provider.sqlSelectUserSessionRevocations)).
+		WillReturnError(errors.New("bad connection"))
+
+	revocations, err = provider.LoadUserSessionRevocations(context.Background(), now.Add(-time.Minute), now)
+
+	assert.EqualError(t, err, "error selecting user session revocations: bad connection")
+	assert.Nil(t, revocations)
+
+	primary.ExpectExec(regexp.QuoteMeta(provider.sqlDeleteExpiredUserSessionRevocations)).
+		WithArgs(now, 100).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := provider.DeleteExpiredUserSessionRevocations(context.Background(), now, 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
@@ -2,10 +2,18 @@ package storage
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
+
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
+// MetricsRecorder represents the methods used to record the rows deleted by the cleanup.
+type MetricsRecorder interface {
+	RecordStorageCleanup(table string, deleted int64)
+}
+
 // SQLXConnection is a *sqlx.DB or *sqlx.Tx.
 type SQLXConnection interface {
 	sqlx.Execer
@@ -21,6 +29,35 @@ type SQLXConnection interface {
 	sqlx.ExtContext
 }
 
+// sqlSortedQuery is a query which has been formatted once for each model.SortOrder.
+type sqlSortedQuery struct {
+	descending string
+	ascending  string
+}
+
+// newSQLSortedQuery formats a query for each model.SortOrder, the sort direction is provided to the format after the
+// other arguments.
+func newSQLSortedQuery(format string, a ...any) sqlSortedQuery {
+	return sqlSortedQuery{
+		descending: fmt.Sprintf(format, append(a[:len(a):len(a)], "DESC")...),
+		ascending:  fmt.Sprintf(format, append(a[:len(a):len(a)], "ASC")...),
+	}
+}
+
+// get returns the query for the model.SortOrder.
+func (q sqlSortedQuery) get(order model.SortOrder) string {
+	if order == model.SortOrderAscending {
+		return q.ascending
+	}
+
+	return q.descending
+}
+
+// rebind returns the sqlSortedQuery with the bind variables of each query rebound for the database.
+func (q sqlSortedQuery) rebind(db *sqlx.DB) sqlSortedQuery {
+	return sqlSortedQuery{descending: db.Rebind(q.descending), ascending: db.Rebind(q.ascending)}
+}
+
 // EncryptionChangeKeyFunc handles encryption key changes for a specific table or tables.
 type EncryptionChangeKeyFunc func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error)
 
@@ -37,11 +74,21 @@ type encWebAuthnCredential struct {
 	PublicKey []byte `db:"public_key"`
 }
 
+type encUserSessionIndex struct {
+	ID        int    `db:"id"`
+	SessionID []byte `db:"session_id"`
+}
+
 type encTOTPConfiguration struct {
 	ID     int    `db:"id"`
 	Secret []byte `db:"secret"`
 }
 
+type encPhoneNumber struct {
+	ID     int    `db:"id"`
+	Number []byte `db:"phone_number"`
+}
+
 type encOneTimeCode struct {
 	ID   int    `db:"id"`
 	Code []byte `db:"code"`
@@ -104,6 +151,34 @@ func (r EncryptionValidationTableResult) ResultDescriptor() string {
 	return "SUCCESS"
 }
 
+// EncryptionRotateKeyResult contains information about the values which were rotated to the encryption key.
+type EncryptionRotateKeyResult struct {
+	Tables map[string]EncryptionRotateKeyTableResult
+}
+
+// Rotated returns the number of values which were rotated to the encryption key in all tables.
+func (r EncryptionRotateKeyResult) Rotated() (rotated int) {
+	for _, table := range r.Tables {
+		rotated += table.Rotated
+	}
+
+	return rotated
+}
+
+// EncryptionRotateKeyTableResult contains information about the values of a table which were rotated to the encryption
+// key. The values which were changed by another writer while they were being rotated are counted as skipped, as they
+// were written with the encryption key by that writer.
+type EncryptionRotateKeyTableResult struct {
+	Total   int
+	Rotated int
+	Skipped int
+}
+
+type encRotateKeyTarget struct {
+	table  string
+	column string
+}
+
 // OAuth2SessionType represents the potential OAuth 2.0 session types.
 type OAuth2SessionType int
 
@@ -115,6 +190,8 @@ const (
 	OAuth2SessionTypePAR
 	OAuth2SessionTypePKCEChallenge
 	OAuth2SessionTypeRefreshToken
+	OAuth2SessionTypeDeviceCode
+	OAuth2SessionTypeCIBA
 )
 
 // String returns a string representation of this OAuth2SessionType.
@@ -132,6 +209,10 @@ func (s OAuth2SessionType) String() string {
 		return "pkce challenge"
 	case OAuth2SessionTypeRefreshToken:
 		return "refresh token"
+	case OAuth2SessionTypeDeviceCode:
+		return "device code"
+	case OAuth2SessionTypeCIBA:
+		return "backchannel authentication request"
 	default:
 		return "invalid"
 	}
@@ -152,6 +233,10 @@ func (s OAuth2SessionType) Table() string {
 		return tableOAuth2PKCERequestSession
 	case OAuth2SessionTypeRefreshToken:
 		return tableOAuth2RefreshTokenSession
+	case OAuth2SessionTypeDeviceCode:
+		return tableOAuth2DeviceCodeSession
+	case OAuth2SessionTypeCIBA:
+		return tableOAuth2CIBARequest
 	default:
 		return ""
 	}
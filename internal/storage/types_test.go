@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 func TestEncryptionValidationResult(t *testing.T) {
@@ -82,6 +84,25 @@ func TestOAuth2SessionType(t *testing.T) {
 	assert.Equal(t, "refresh token", OAuth2SessionTypeRefreshToken.String())
 	assert.Equal(t, tableOAuth2RefreshTokenSession, OAuth2SessionTypeRefreshToken.Table())
 
+	assert.Equal(t, "device code", OAuth2SessionTypeDeviceCode.String())
+	assert.Equal(t, tableOAuth2DeviceCodeSession, OAuth2SessionTypeDeviceCode.Table())
+
+	assert.Equal(t, "backchannel authentication request", OAuth2SessionTypeCIBA.String())
+	assert.Equal(t, tableOAuth2CIBARequest, OAuth2SessionTypeCIBA.Table())
+
 	assert.Equal(t, "invalid", OAuth2SessionType(-1).String())
 	assert.Equal(t, "", OAuth2SessionType(-1).Table())
 }
+
+func TestSQLSortedQuery(t *testing.T) {
+	query := newSQLSortedQuery(queryFmtSelectAuditEvents, tableAuditEvents)
+
+	assert.Contains(t, query.get(model.SortOrderDescending), "FROM audit_events")
+	assert.Contains(t, query.get(model.SortOrderDescending), "ORDER BY time DESC, id DESC")
+	assert.Contains(t, query.get(model.SortOrderAscending), "ORDER BY time ASC, id ASC")
+
+	query = newSQLSortedQuery(queryFmtSelectOAuth2ConsentSessionsByUsername, tableOAuth2ConsentSession, tableUserOpaqueIdentifier)
+
+	assert.Contains(t, query.get(model.SortOrderDescending), "INNER JOIN user_opaque_identifier u")
+	assert.Contains(t, query.get(model.SortOrderAscending), "ORDER BY c.responded_at ASC, c.id ASC")
+}
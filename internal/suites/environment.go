@@ -2,12 +2,11 @@ package suites
 
 import (
 	"os"
-	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
-	"github.com/authelia/authelia/v4/internal/utils"
+	"github.com/authelia/authelia/v4/harness"
 )
 
 func waitUntilServiceLogDetected(
@@ -16,25 +15,7 @@ func waitUntilServiceLogDetected(
 	dockerEnvironment *DockerEnvironment,
 	service string,
 	logPatterns []string) error {
-	log.Debug("Waiting for service " + service + " to be ready...")
-
-	err := utils.CheckUntil(interval, timeout, func() (bool, error) {
-		logs, err := dockerEnvironment.Logs(service, []string{"--tail", "20"})
-
-		if err != nil {
-			return false, err
-		}
-
-		for _, pattern := range logPatterns {
-			if strings.Contains(logs, pattern) {
-				return true, nil
-			}
-		}
-
-		return false, nil
-	})
-
-	return err
+	return harness.WaitUntilServiceLogDetected(interval, timeout, dockerEnvironment, service, logPatterns)
 }
 
 func waitUntilAutheliaBackendIsReady(dockerEnvironment *DockerEnvironment) error {
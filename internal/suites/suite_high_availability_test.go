@@ -2,8 +2,10 @@ package suites
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
@@ -11,6 +13,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/harness"
 )
 
 type HighAvailabilityWebDriverSuite struct {
@@ -325,6 +329,10 @@ func (s *HighAvailabilitySuite) TestRedirectionCheckScenario() {
 	suite.Run(s.T(), NewRedirectionCheckScenario())
 }
 
+func (s *HighAvailabilitySuite) TestHarnessScenarios() {
+	harness.Run(s.T(), newHighAvailabilityHarnessTarget(s.T()))
+}
+
 func (s *HighAvailabilitySuite) TestHighAvailabilityWebDriverSuite() {
 	suite.Run(s.T(), NewHighAvailabilityWebDriverSuite())
 }
@@ -344,3 +352,34 @@ func TestHighAvailabilitySuite(t *testing.T) {
 
 	suite.Run(t, NewHighAvailabilitySuite())
 }
+
+// newHighAvailabilityHarnessTarget returns the harness.Target for the HighAvailability suite. The NGINX backend uses
+// the AuthRequest authz implementation and the primary redis node is stopped by the failover scenario.
+func newHighAvailabilityHarnessTarget(t *testing.T) *harness.Target {
+	parse := func(raw string) *url.URL {
+		uri, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return uri
+	}
+
+	return &harness.Target{
+		PortalURL: parse(GetLoginBaseURL(BaseDomain)),
+		Proxy:     harness.NewAuthRequestProxy("NGINX", ""),
+		Resources: harness.Resources{
+			Bypass:    parse(fmt.Sprintf("%s/", PublicBaseURL)),
+			OneFactor: parse(fmt.Sprintf("%s/secret.html", SingleFactorBaseURL)),
+			TwoFactor: parse(fmt.Sprintf("%s/secret.html", SecureBaseURL)),
+			Deny:      parse(fmt.Sprintf("%s/", DenyBaseURL)),
+		},
+		User: harness.User{
+			Username: "john",
+			Password: "password",
+		},
+		Environment: haDockerEnvironment,
+		Failover:    []string{"redis-node-0"},
+		TLS:         &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // Needs to be enabled in suites. Not used in production.
+	}
+}
@@ -0,0 +1,32 @@
+package systemd
+
+const (
+	envNotifySocket = "NOTIFY_SOCKET"
+	envWatchdogUSec = "WATCHDOG_USEC"
+	envWatchdogPID  = "WATCHDOG_PID"
+)
+
+const (
+	// StateReady tells the service manager that the service startup is finished, or the service finished reloading
+	// its configuration.
+	StateReady = "READY=1"
+
+	// StateReloading tells the service manager that the service is reloading its configuration. The service must
+	// send StateReady once the reload is complete.
+	StateReloading = "RELOADING=1"
+
+	// StateStopping tells the service manager that the service is beginning its shutdown.
+	StateStopping = "STOPPING=1"
+
+	// StateWatchdog tells the service manager to update the watchdog timestamp.
+	StateWatchdog = "WATCHDOG=1"
+)
+
+const (
+	prefixStatus        = "STATUS="
+	prefixMonotonicUSec = "MONOTONIC_USEC="
+)
+
+const (
+	netUnixgram = "unixgram"
+)
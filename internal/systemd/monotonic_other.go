@@ -0,0 +1,7 @@
+//go:build !linux
+
+package systemd
+
+func monotonicUSec() (usec int64, ok bool) {
+	return 0, false
+}
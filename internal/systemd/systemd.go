@@ -0,0 +1,92 @@
+// Package systemd implements the systemd service manager notification protocol which is used by services with the
+// notify and notify-reload service types to report their readiness, reloads, and watchdog keep-alive pings.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notify sends the states to the service manager using the socket in the NOTIFY_SOCKET environment variable. If the
+// environment variable is not set, i.e. the process is not supervised by a service manager which expects
+// notifications, nothing is sent and the sent value is false.
+func Notify(states ...string) (sent bool, err error) {
+	name := os.Getenv(envNotifySocket)
+
+	if name == "" {
+		return false, nil
+	}
+
+	// The leading @ indicates a socket in the abstract namespace.
+	if name[0] == '@' {
+		name = "\x00" + name[1:]
+	}
+
+	var conn *net.UnixConn
+
+	if conn, err = net.DialUnix(netUnixgram, nil, &net.UnixAddr{Name: name, Net: netUnixgram}); err != nil {
+		return false, fmt.Errorf("error occurred connecting to the notify socket: %w", err)
+	}
+
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(strings.Join(states, "\n"))); err != nil {
+		return false, fmt.Errorf("error occurred writing to the notify socket: %w", err)
+	}
+
+	return true, nil
+}
+
+// Status returns the state which describes the status of the service in a human-readable form.
+func Status(status string) string {
+	return prefixStatus + status
+}
+
+// Reloading returns the states which tell the service manager the service is reloading its configuration, including
+// the timestamp required by the notify-reload service type where it's available on this platform.
+func Reloading() (states []string) {
+	states = []string{StateReloading}
+
+	if usec, ok := monotonicUSec(); ok {
+		states = append(states, prefixMonotonicUSec+strconv.FormatInt(usec, 10))
+	}
+
+	return states
+}
+
+// WatchdogInterval returns the interval at which the service manager expects the StateWatchdog keep-alive pings. The
+// interval is zero if the watchdog is not enabled for this process.
+func WatchdogInterval() (interval time.Duration, err error) {
+	value := os.Getenv(envWatchdogUSec)
+
+	if value == "" {
+		return 0, nil
+	}
+
+	var usec, pid int
+
+	if usec, err = strconv.Atoi(value); err != nil {
+		return 0, fmt.Errorf("error occurred parsing the '%s' environment variable: %w", envWatchdogUSec, err)
+	}
+
+	if usec <= 0 {
+		return 0, fmt.Errorf("error occurred parsing the '%s' environment variable: the value '%d' must be above 0", envWatchdogUSec, usec)
+	}
+
+	if value = os.Getenv(envWatchdogPID); value != "" {
+		if pid, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("error occurred parsing the '%s' environment variable: %w", envWatchdogPID, err)
+		}
+
+		// The watchdog applies to another process such as the parent of this process.
+		if pid != os.Getpid() {
+			return 0, nil
+		}
+	}
+
+	return time.Duration(usec) * time.Microsecond, nil
+}
@@ -0,0 +1,104 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test due to being on windows")
+	}
+
+	t.Setenv(envNotifySocket, "")
+
+	sent, err := Notify(StateReady)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	path := filepath.Join(t.TempDir(), "notify.sock")
+
+	conn, err := net.ListenUnixgram(netUnixgram, &net.UnixAddr{Name: path, Net: netUnixgram})
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	t.Setenv(envNotifySocket, path)
+
+	sent, err = Notify(StateReady, Status("Startup complete"))
+	assert.NoError(t, err)
+	assert.True(t, sent)
+
+	buf := make([]byte, 1024)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "READY=1\nSTATUS=Startup complete", string(buf[:n]))
+
+	t.Setenv(envNotifySocket, filepath.Join(t.TempDir(), "missing.sock"))
+
+	sent, err = Notify(StateStopping)
+	assert.ErrorContains(t, err, "error occurred connecting to the notify socket: ")
+	assert.False(t, sent)
+}
+
+func TestReloading(t *testing.T) {
+	states := Reloading()
+
+	require.NotEmpty(t, states)
+	assert.Equal(t, StateReloading, states[0])
+
+	if runtime.GOOS == "linux" {
+		require.Len(t, states, 2)
+		assert.True(t, strings.HasPrefix(states[1], "MONOTONIC_USEC="))
+	} else {
+		assert.Len(t, states, 1)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		usec     string
+		pid      string
+		expected time.Duration
+		err      string
+	}{
+		{"ShouldReturnZeroWhenDisabled", "", "", 0, ""},
+		{"ShouldReturnInterval", "30000000", "", time.Second * 30, ""},
+		{"ShouldReturnIntervalForThisProcess", "5000000", strconv.Itoa(os.Getpid()), time.Second * 5, ""},
+		{"ShouldReturnZeroForOtherProcess", "5000000", strconv.Itoa(os.Getpid() + 1), 0, ""},
+		{"ShouldErrorOnInvalidInterval", "abc", "", 0, "error occurred parsing the 'WATCHDOG_USEC' environment variable: strconv.Atoi: parsing \"abc\": invalid syntax"},
+		{"ShouldErrorOnZeroInterval", "0", "", 0, "error occurred parsing the 'WATCHDOG_USEC' environment variable: the value '0' must be above 0"},
+		{"ShouldErrorOnInvalidPID", "5000000", "abc", 0, "error occurred parsing the 'WATCHDOG_PID' environment variable: strconv.Atoi: parsing \"abc\": invalid syntax"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envWatchdogUSec, tc.usec)
+			t.Setenv(envWatchdogPID, tc.pid)
+
+			actual, err := WatchdogInterval()
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -9,6 +9,7 @@ const (
 const (
 	TemplateNameEmailIdentityVerificationJWT = "IdentityVerificationJWT"
 	TemplateNameEmailIdentityVerificationOTC = "IdentityVerificationOTC"
+	TemplateNameEmailSecondFactorOTC         = "SecondFactorOTC"
 	TemplateNameEmailEvent                   = "Event"
 
 	TemplateNameOIDCAuthorizeFormPost = "AuthorizeResponseFormPost.html"
@@ -19,3 +20,7 @@ const (
 	TemplateCategoryNotifications = "notification"
 	TemplateCategoryOpenIDConnect = "oidc"
 )
+
+// sandboxDeniedFuncs are the names of the functions from the FuncMap which can access the environment or the file
+// system and are therefore not available in the SandboxFuncMap.
+var sandboxDeniedFuncs = []string{"env", "mustEnv", "expandenv", "fileContent", "secret"}
@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	tt "text/template"
+	"text/template/parse"
+)
+
+// UnknownEmailTemplateOverrideFields parses each of the email template overrides within the override path and returns
+// the fields referenced by each of them which are not available to the template. Only the fields referenced from the
+// root of the values are checked as the values within the range and with actions are not known.
+func UnknownEmailTemplateOverrideFields(overridePath string) (unknown []UnknownTemplateFields, err error) {
+	names := []string{TemplateNameEmailEvent, TemplateNameEmailIdentityVerificationJWT, TemplateNameEmailIdentityVerificationOTC, TemplateNameEmailSecondFactorOTC}
+
+	for _, name := range names {
+		available := EmailTemplateFields(name)
+
+		for _, ext := range []string{extText, extHTML} {
+			path := filepath.Join(overridePath, name+ext)
+
+			if !fileExists(path) {
+				continue
+			}
+
+			var (
+				data []byte
+				t    *tt.Template
+			)
+
+			if data, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("failed to read template override at path '%s': %w", path, err)
+			}
+
+			if t, err = tt.New(name + ext).Funcs(SandboxFuncMap()).Parse(string(data)); err != nil {
+				return nil, fmt.Errorf("failed to parse template override at path '%s': %w", path, err)
+			}
+
+			var fields []string
+
+			for _, field := range templateRootFields(t.Tree) {
+				if !slices.Contains(available, field) {
+					fields = append(fields, field)
+				}
+			}
+
+			if len(fields) != 0 {
+				unknown = append(unknown, UnknownTemplateFields{Path: path, Fields: fields, Available: available})
+			}
+		}
+	}
+
+	return unknown, nil
+}
+
+// EmailTemplateFields returns the names of the fields available to the email template with the provided name.
+func EmailTemplateFields(name string) (fields []string) {
+	var values any
+
+	switch name {
+	case TemplateNameEmailEvent:
+		values = EmailEventValues{}
+	case TemplateNameEmailIdentityVerificationJWT:
+		values = EmailIdentityVerificationJWTValues{}
+	case TemplateNameEmailIdentityVerificationOTC:
+		values = EmailIdentityVerificationOTCValues{}
+	case TemplateNameEmailSecondFactorOTC:
+		values = EmailSecondFactorOTCValues{}
+	default:
+		return nil
+	}
+
+	t := reflect.TypeOf(values)
+
+	fields = make([]string, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		fields[i] = t.Field(i).Name
+	}
+
+	return fields
+}
+
+// templateRootFields returns the sorted names of the fields referenced from the root of the values by the tree.
+func templateRootFields(tree *parse.Tree) (fields []string) {
+	if tree == nil || tree.Root == nil {
+		return nil
+	}
+
+	found := map[string]struct{}{}
+
+	templateRootFieldsNode(tree.Root, true, found)
+
+	for field := range found {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+func templateRootFieldsNode(node parse.Node, root bool, found map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			templateRootFieldsNode(child, root, found)
+		}
+	case *parse.ActionNode:
+		templateRootFieldsNode(n.Pipe, root, found)
+	case *parse.IfNode:
+		templateRootFieldsNode(n.Pipe, root, found)
+		templateRootFieldsNode(n.List, root, found)
+		templateRootFieldsNode(n.ElseList, root, found)
+	case *parse.RangeNode:
+		templateRootFieldsNode(n.Pipe, root, found)
+		templateRootFieldsNode(n.List, false, found)
+		templateRootFieldsNode(n.ElseList, root, found)
+	case *parse.WithNode:
+		templateRootFieldsNode(n.Pipe, root, found)
+		templateRootFieldsNode(n.List, false, found)
+		templateRootFieldsNode(n.ElseList, root, found)
+	case *parse.TemplateNode:
+		templateRootFieldsNode(n.Pipe, root, found)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, cmd := range n.Cmds {
+			templateRootFieldsNode(cmd, root, found)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			templateRootFieldsNode(arg, root, found)
+		}
+	case *parse.ChainNode:
+		templateRootFieldsNode(n.Node, root, found)
+	case *parse.FieldNode:
+		if root && len(n.Ident) != 0 {
+			found[n.Ident[0]] = struct{}{}
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			found[n.Ident[1]] = struct{}{}
+		}
+	}
+}
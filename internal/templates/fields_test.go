@@ -0,0 +1,78 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownEmailTemplateOverrideFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected []UnknownTemplateFields
+		err      string
+	}{
+		{
+			"ShouldNotReturnUnknownFieldsEmbedded",
+			nil,
+			nil,
+			"",
+		},
+		{
+			"ShouldReturnUnknownFields",
+			map[string]string{
+				"Event.txt":                    "Hi {{ .DisplayName }}, {{ .Username }} {{ $.Domain }} {{ .Details.Example }}",
+				"Event.html":                   "{{ range $key, $value := .Details }}{{ .Unknown }}{{ $key }}{{ end }}{{ with .Title }}{{ .Unknown }}{{ end }}",
+				"IdentityVerificationOTC.html": "{{ if .OneTimeCode }}{{ .LinkURL }}{{ else }}{{ .RemoteIP }}{{ end }}",
+			},
+			[]UnknownTemplateFields{
+				{Path: "Event.txt", Fields: []string{"Domain", "Username"}, Available: []string{"Title", "DisplayName", "Details", "RemoteIP"}},
+				{Path: "IdentityVerificationOTC.html", Fields: []string{"LinkURL"}, Available: []string{"Title", "DisplayName", "RemoteIP", "OneTimeCode", "RevocationLinkURL", "RevocationLinkText"}},
+			},
+			"",
+		},
+		{
+			"ShouldReturnErrParse",
+			map[string]string{
+				"IdentityVerificationJWT.txt": "{{ .Title ",
+			},
+			nil,
+			"failed to parse template override at path '%s': template: IdentityVerificationJWT.txt:1: unclosed action",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join("embed", TemplateCategoryNotifications)
+
+			if tc.files != nil {
+				dir = t.TempDir()
+
+				for name, content := range tc.files {
+					require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+				}
+			}
+
+			actual, err := UnknownEmailTemplateOverrideFields(dir)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, fmt.Sprintf(tc.err, filepath.Join(dir, "IdentityVerificationJWT.txt")))
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			for i := range tc.expected {
+				tc.expected[i].Path = filepath.Join(dir, tc.expected[i].Path)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
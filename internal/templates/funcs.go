@@ -7,6 +7,8 @@ import (
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"hash"
 	"net/url"
@@ -21,74 +23,101 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/secrets"
 )
 
 // FuncMap returns the template FuncMap commonly used in several templates.
 func FuncMap() map[string]any {
 	return map[string]any{
-		"iterate":     FuncIterate,
-		"fileContent": FuncFileContent,
-		"secret":      FuncSecret,
-		"env":         FuncGetEnv,
-		"mustEnv":     FuncMustGetEnv,
-		"expandenv":   FuncExpandEnv,
-		"split":       FuncStringSplit,
-		"splitList":   FuncStringSplitList,
-		"join":        FuncElemsJoin,
-		"contains":    FuncStringContains,
-		"hasPrefix":   FuncStringHasPrefix,
-		"hasSuffix":   FuncStringHasSuffix,
-		"lower":       strings.ToLower,
-		"keys":        FuncKeys,
-		"sortAlpha":   FuncSortAlpha,
-		"upper":       strings.ToUpper,
-		"title":       strings.ToTitle,
-		"trim":        strings.TrimSpace,
-		"trimAll":     FuncStringTrimAll,
-		"trimSuffix":  FuncStringTrimSuffix,
-		"trimPrefix":  FuncStringTrimPrefix,
-		"replace":     FuncStringReplace,
-		"quote":       FuncStringQuote,
-		"mquote":      FuncStringQuoteMultiLine(rune(34)),
-		"sha1sum":     FuncHashSum(sha1.New),
-		"sha256sum":   FuncHashSum(sha256.New),
-		"sha512sum":   FuncHashSum(sha512.New),
-		"squote":      FuncStringSQuote,
-		"msquote":     FuncStringQuoteMultiLine(rune(39)),
-		"now":         time.Now,
-		"b64enc":      FuncB64Enc,
-		"b64dec":      FuncB64Dec,
-		"b32enc":      FuncB32Enc,
-		"b32dec":      FuncB32Dec,
-		"list":        FuncList,
-		"dict":        FuncDict,
-		"get":         FuncGet,
-		"set":         FuncSet,
-		"isAbs":       path.IsAbs,
-		"base":        path.Base,
-		"dir":         path.Dir,
-		"ext":         path.Ext,
-		"clean":       path.Clean,
-		"osBase":      filepath.Base,
-		"osClean":     filepath.Clean,
-		"osDir":       filepath.Dir,
-		"osExt":       filepath.Ext,
-		"osIsAbs":     filepath.IsAbs,
-		"deepEqual":   reflect.DeepEqual,
-		"typeOf":      FuncTypeOf,
-		"typeIs":      FuncTypeIs,
-		"typeIsLike":  FuncTypeIsLike,
-		"kindOf":      FuncKindOf,
-		"kindIs":      FuncKindIs,
-		"default":     FuncDefault,
-		"empty":       FuncEmpty,
-		"indent":      FuncIndent,
-		"nindent":     FuncNewlineIndent,
-		"mindent":     FuncMultilineIndent,
-		"uuidv4":      FuncUUIDv4,
-		"urlquery":    url.QueryEscape,
-		"urlunquery":  url.QueryUnescape,
+		"iterate":      FuncIterate,
+		"fileContent":  FuncFileContent,
+		"secret":       FuncSecret,
+		"env":          FuncGetEnv,
+		"mustEnv":      FuncMustGetEnv,
+		"expandenv":    FuncExpandEnv,
+		"split":        FuncStringSplit,
+		"splitList":    FuncStringSplitList,
+		"join":         FuncElemsJoin,
+		"contains":     FuncStringContains,
+		"hasPrefix":    FuncStringHasPrefix,
+		"hasSuffix":    FuncStringHasSuffix,
+		"lower":        strings.ToLower,
+		"keys":         FuncKeys,
+		"sortAlpha":    FuncSortAlpha,
+		"upper":        strings.ToUpper,
+		"title":        strings.ToTitle,
+		"trim":         strings.TrimSpace,
+		"trimAll":      FuncStringTrimAll,
+		"trimSuffix":   FuncStringTrimSuffix,
+		"trimPrefix":   FuncStringTrimPrefix,
+		"replace":      FuncStringReplace,
+		"quote":        FuncStringQuote,
+		"mquote":       FuncStringQuoteMultiLine(rune(34)),
+		"sha1sum":      FuncHashSum(sha1.New),
+		"sha256sum":    FuncHashSum(sha256.New),
+		"sha512sum":    FuncHashSum(sha512.New),
+		"squote":       FuncStringSQuote,
+		"msquote":      FuncStringQuoteMultiLine(rune(39)),
+		"now":          time.Now,
+		"b64enc":       FuncB64Enc,
+		"b64dec":       FuncB64Dec,
+		"b32enc":       FuncB32Enc,
+		"b32dec":       FuncB32Dec,
+		"b64urlenc":    FuncB64URLEnc,
+		"b64urldec":    FuncB64URLDec,
+		"toJson":       FuncToJSON,
+		"toPrettyJson": FuncToPrettyJSON,
+		"fromJson":     FuncFromJSON,
+		"date":         FuncDate,
+		"dateInZone":   FuncDateInZone,
+		"unixEpoch":    FuncUnixEpoch,
+		"hasKey":       FuncHasKey,
+		"dig":          FuncDig,
+		"list":         FuncList,
+		"dict":         FuncDict,
+		"get":          FuncGet,
+		"set":          FuncSet,
+		"isAbs":        path.IsAbs,
+		"base":         path.Base,
+		"dir":          path.Dir,
+		"ext":          path.Ext,
+		"clean":        path.Clean,
+		"osBase":       filepath.Base,
+		"osClean":      filepath.Clean,
+		"osDir":        filepath.Dir,
+		"osExt":        filepath.Ext,
+		"osIsAbs":      filepath.IsAbs,
+		"deepEqual":    reflect.DeepEqual,
+		"typeOf":       FuncTypeOf,
+		"typeIs":       FuncTypeIs,
+		"typeIsLike":   FuncTypeIsLike,
+		"kindOf":       FuncKindOf,
+		"kindIs":       FuncKindIs,
+		"default":      FuncDefault,
+		"empty":        FuncEmpty,
+		"coalesce":     FuncCoalesce,
+		"required":     FuncRequired,
+		"indent":       FuncIndent,
+		"nindent":      FuncNewlineIndent,
+		"mindent":      FuncMultilineIndent,
+		"uuidv4":       FuncUUIDv4,
+		"urlquery":     url.QueryEscape,
+		"urlunquery":   url.QueryUnescape,
+	}
+}
+
+// SandboxFuncMap returns the FuncMap without the functions which can access the environment or the file system. It's
+// used for the templates which are not part of the configuration such as the notification template overrides, which
+// means referencing one of these functions fails when the template is parsed.
+func SandboxFuncMap() map[string]any {
+	funcs := FuncMap()
+
+	for _, name := range sandboxDeniedFuncs {
+		delete(funcs, name)
 	}
+
+	return funcs
 }
 
 // FuncB64Enc is a helper function that provides similar functionality to the helm b64enc func.
@@ -121,6 +150,143 @@ func FuncB32Dec(input string) (string, error) {
 	return string(data), nil
 }
 
+// FuncB64URLEnc is a helper function that encodes the input with the unpadded URL safe base64 encoding.
+func FuncB64URLEnc(input string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(input))
+}
+
+// FuncB64URLDec is a helper function that decodes the input with the URL safe base64 encoding, the padding is
+// optional.
+func FuncB64URLDec(input string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(input, "="))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// FuncToJSON is a helper function that provides similar functionality to the helm mustToJson func.
+func FuncToJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// FuncToPrettyJSON is a helper function that provides similar functionality to the helm mustToPrettyJson func.
+func FuncToPrettyJSON(v any) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// FuncFromJSON is a helper function that provides similar functionality to the helm mustFromJson func.
+func FuncFromJSON(input string) (v any, err error) {
+	if err = json.Unmarshal([]byte(input), &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// FuncDate is a helper function that provides similar functionality to the helm date func. The layout is a go time
+// layout and the date is either a time.Time or a unix timestamp in seconds.
+func FuncDate(layout string, date any) (string, error) {
+	return FuncDateInZone(layout, date, "Local")
+}
+
+// FuncDateInZone is a helper function that provides similar functionality to the helm dateInZone func except an
+// unknown zone returns an error instead of falling back to UTC.
+func FuncDateInZone(layout string, date any, zone string) (string, error) {
+	var t time.Time
+
+	switch v := date.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v != nil {
+			t = *v
+		}
+	case int:
+		t = time.Unix(int64(v), 0)
+	case int32:
+		t = time.Unix(int64(v), 0)
+	case int64:
+		t = time.Unix(v, 0)
+	default:
+		return "", fmt.Errorf("the date must be a time or a unix timestamp but it's a %T", date)
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return "", err
+	}
+
+	return t.In(loc).Format(layout), nil
+}
+
+// FuncUnixEpoch is a helper function that provides similar functionality to the helm unixEpoch func.
+func FuncUnixEpoch(date time.Time) string {
+	return strconv.FormatInt(date.Unix(), 10)
+}
+
+// FuncHasKey is a helper function that provides similar functionality to the helm hasKey func.
+func FuncHasKey(m map[string]any, key string) bool {
+	_, ok := m[key]
+
+	return ok
+}
+
+// FuncDig is a helper function that provides similar functionality to the helm dig func. The arguments are the keys
+// in order, the default value, and the map. The default value is returned if any of the keys don't exist or any of the
+// values leading up to the last key are not a map.
+func FuncDig(args ...any) (any, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("dig requires at least 3 arguments but %d were provided", len(args))
+	}
+
+	var value any
+
+	switch m := args[len(args)-1].(type) {
+	case map[string]any:
+		value = m
+	case map[string]string:
+		value = m
+	default:
+		return nil, fmt.Errorf("dig requires the last argument to be a map but it's a %T", args[len(args)-1])
+	}
+
+	fallback := args[len(args)-2]
+
+	for i, k := range args[:len(args)-2] {
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("dig requires the keys to be strings but key %d is a %T", i+1, k)
+		}
+
+		switch m := value.(type) {
+		case map[string]any:
+			if value, ok = m[key]; !ok {
+				return fallback, nil
+			}
+		case map[string]string:
+			if value, ok = m[key]; !ok {
+				return fallback, nil
+			}
+		default:
+			return fallback, nil
+		}
+	}
+
+	return value, nil
+}
+
 // FuncExpandEnv is a special version of os.ExpandEnv that excludes secret keys.
 func FuncExpandEnv(s string) string {
 	return os.Expand(s, FuncGetEnv)
@@ -416,6 +582,26 @@ func FuncDefault(d any, vals ...any) any {
 	return vals[0]
 }
 
+// FuncCoalesce is a helper function that provides similar functionality to the helm coalesce func.
+func FuncCoalesce(vals ...any) any {
+	for _, val := range vals {
+		if !FuncEmpty(val) {
+			return val
+		}
+	}
+
+	return nil
+}
+
+// FuncRequired is a helper function that provides similar functionality to the helm required func.
+func FuncRequired(message string, val any) (any, error) {
+	if FuncEmpty(val) {
+		return nil, errors.New(message)
+	}
+
+	return val, nil
+}
+
 // FuncEmpty is a helper function that provides similar functionality to the helm empty func.
 func FuncEmpty(v any) bool {
 	rv := reflect.ValueOf(v)
@@ -482,8 +668,13 @@ func FuncFileContent(path string) (data string, err error) {
 	return string(raw), nil
 }
 
-// FuncSecret returns the file content stripping the newlines from the end of the content.
+// FuncSecret returns the file content stripping the newlines from the end of the content. If the path is a reference
+// to a secret in an external secret manager the secret value is returned as-is instead.
 func FuncSecret(path string) (data string, err error) {
+	if secrets.IsReference(path) {
+		return secrets.Resolve(path)
+	}
+
 	if data, err = FuncFileContent(path); err != nil {
 		return "", err
 	}
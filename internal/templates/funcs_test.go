@@ -4,8 +4,11 @@ import (
 	"crypto/sha1" //nolint:gosec
 	"crypto/sha256"
 	"crypto/sha512"
+	"fmt"
 	"hash"
 	"testing"
+	tt "text/template"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -441,6 +444,165 @@ func TestFuncBDec(t *testing.T) {
 	}
 }
 
+func TestFuncB64URL(t *testing.T) {
+	assert.Equal(t, "", FuncB64URLEnc(""))
+	assert.Equal(t, "Pz8-", FuncB64URLEnc("??>"))
+	assert.Equal(t, "YWI", FuncB64URLEnc("ab"))
+
+	actual, err := FuncB64URLDec("YWI")
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", actual)
+
+	actual, err = FuncB64URLDec("YWI=")
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", actual)
+
+	actual, err = FuncB64URLDec("Pz8-")
+	assert.NoError(t, err)
+	assert.Equal(t, "??>", actual)
+
+	actual, err = FuncB64URLDec("Pz8+")
+	assert.EqualError(t, err, "illegal base64 data at input byte 3")
+	assert.Equal(t, "", actual)
+}
+
+func TestFuncJSON(t *testing.T) {
+	actual, err := FuncToJSON(map[string]any{"b": []any{1, "2"}, "a": true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":true,"b":[1,"2"]}`, actual)
+
+	actual, err = FuncToPrettyJSON(map[string]any{"a": true})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": true\n}", actual)
+
+	actual, err = FuncToJSON(func() {})
+	assert.EqualError(t, err, "json: unsupported type: func()")
+	assert.Equal(t, "", actual)
+
+	actual, err = FuncToPrettyJSON(func() {})
+	assert.EqualError(t, err, "json: unsupported type: func()")
+	assert.Equal(t, "", actual)
+
+	value, err := FuncFromJSON(`{"a":[1,"2"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": []any{float64(1), "2"}}, value)
+
+	value, err = FuncFromJSON(`{"a":`)
+	assert.EqualError(t, err, "unexpected end of JSON input")
+	assert.Nil(t, value)
+}
+
+func TestFuncDate(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		layout   string
+		have     any
+		zone     string
+		expected string
+		err      string
+	}{
+		{"ShouldFormatTime", time.RFC3339, date, "UTC", "2024-01-02T03:04:05Z", ""},
+		{"ShouldFormatTimePointer", time.DateOnly, &date, "UTC", "2024-01-02", ""},
+		{"ShouldFormatTimeNilPointer", time.RFC3339, (*time.Time)(nil), "UTC", "0001-01-01T00:00:00Z", ""},
+		{"ShouldFormatUnixInt", time.RFC3339, int(date.Unix()), "UTC", "2024-01-02T03:04:05Z", ""},
+		{"ShouldFormatUnixInt64", time.RFC3339, date.Unix(), "UTC", "2024-01-02T03:04:05Z", ""},
+		{"ShouldFormatUnixInt32", time.RFC3339, int32(date.Unix()), "UTC", "2024-01-02T03:04:05Z", ""},
+		{"ShouldFormatInZone", time.RFC3339, date, "Australia/Melbourne", "2024-01-02T14:04:05+11:00", ""},
+		{"ShouldErrUnknownZone", time.RFC3339, date, "Not/AZone", "", "unknown time zone Not/AZone"},
+		{"ShouldErrUnknownType", time.RFC3339, "2024-01-02", "UTC", "", "the date must be a time or a unix timestamp but it's a string"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := FuncDateInZone(tc.layout, tc.have, tc.zone)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, actual)
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Equal(t, "", actual)
+			}
+		})
+	}
+
+	actual, err := FuncDate(time.RFC3339, date)
+	assert.NoError(t, err)
+	assert.Equal(t, date.Local().Format(time.RFC3339), actual)
+
+	assert.Equal(t, "1704164645", FuncUnixEpoch(date))
+}
+
+func TestFuncHasKey(t *testing.T) {
+	assert.True(t, FuncHasKey(map[string]any{"a": nil}, "a"))
+	assert.False(t, FuncHasKey(map[string]any{"a": nil}, "b"))
+	assert.False(t, FuncHasKey(nil, "a"))
+}
+
+func TestFuncDig(t *testing.T) {
+	have := map[string]any{
+		"a": map[string]any{
+			"b": map[string]string{
+				"c": "value",
+			},
+			"d": 1,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		have     []any
+		expected any
+		err      string
+	}{
+		{"ShouldDigString", []any{"a", "b", "c", "default", have}, "value", ""},
+		{"ShouldDigMap", []any{"a", "b", "default", have}, map[string]string{"c": "value"}, ""},
+		{"ShouldDigInteger", []any{"a", "d", "default", have}, 1, ""},
+		{"ShouldDefaultMissingKey", []any{"a", "x", "default", have}, "default", ""},
+		{"ShouldDefaultMissingNestedKey", []any{"a", "b", "x", "default", have}, "default", ""},
+		{"ShouldDefaultNotMap", []any{"a", "d", "x", "default", have}, "default", ""},
+		{"ShouldDigStringMap", []any{"c", "default", map[string]string{"c": "value"}}, "value", ""},
+		{"ShouldErrTooFewArgs", []any{"default", have}, nil, "dig requires at least 3 arguments but 2 were provided"},
+		{"ShouldErrNotMap", []any{"a", "default", "abc"}, nil, "dig requires the last argument to be a map but it's a string"},
+		{"ShouldErrKeyNotString", []any{"a", 1, "default", have}, nil, "dig requires the keys to be strings but key 2 is a int"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := FuncDig(tc.have...)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestSandboxFuncMap(t *testing.T) {
+	funcs := FuncMap()
+	sandbox := SandboxFuncMap()
+
+	for _, name := range []string{"env", "mustEnv", "expandenv", "fileContent", "secret"} {
+		t.Run(name, func(t *testing.T) {
+			assert.Contains(t, funcs, name)
+			assert.NotContains(t, sandbox, name)
+
+			_, err := tt.New("example").Funcs(sandbox).Parse(fmt.Sprintf(`{{ %s "/etc/passwd" }}`, name))
+
+			assert.EqualError(t, err, fmt.Sprintf(`template: example:1: function "%s" not defined`, name))
+		})
+	}
+
+	assert.Len(t, sandbox, len(funcs)-len(sandboxDeniedFuncs))
+	assert.Contains(t, sandbox, "toJson")
+}
+
 func TestFuncStringQuote(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -589,6 +751,35 @@ func TestFuncDefault(t *testing.T) {
 	}
 }
 
+func TestFuncCoalesce(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     []any
+		expected any
+	}{
+		{"ShouldReturnFirstNonEmpty", []any{"", nil, "abc", "def"}, "abc"},
+		{"ShouldReturnFirstValue", []any{1, 2}, 1},
+		{"ShouldReturnNilWhenAllEmpty", []any{"", 0, nil}, nil},
+		{"ShouldReturnNilWithoutValues", nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FuncCoalesce(tc.have...))
+		})
+	}
+}
+
+func TestFuncRequired(t *testing.T) {
+	actual, err := FuncRequired("example is required", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", actual)
+
+	actual, err = FuncRequired("example is required", "")
+	assert.EqualError(t, err, "example is required")
+	assert.Nil(t, actual)
+}
+
 func TestFuncEmpty(t *testing.T) {
 	var nilv *string
 
@@ -0,0 +1,91 @@
+package templates
+
+import (
+	"fmt"
+	th "html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	tt "text/template"
+	"text/template/parse"
+)
+
+// LintEmailTemplateOverrides checks each of the files within the email template override path the same way they're
+// checked when they're loaded by the notifier, returning a result for each file. Files which are not one of the known
+// templates are reported with a warning as they're ignored by the notifier.
+func LintEmailTemplateOverrides(overridePath string) (results []LintResult, err error) {
+	var entries []os.DirEntry
+
+	if entries, err = os.ReadDir(overridePath); err != nil {
+		return nil, fmt.Errorf("failed to read the template override path '%s': %w", overridePath, err)
+	}
+
+	names := []string{TemplateNameEmailEvent, TemplateNameEmailIdentityVerificationJWT, TemplateNameEmailIdentityVerificationOTC, TemplateNameEmailSecondFactorOTC}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		result := LintResult{Path: filepath.Join(overridePath, entry.Name())}
+
+		ext := filepath.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), ext)
+
+		if !slices.Contains(names, name) || (ext != extText && ext != extHTML) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("the file is not a known template and will be ignored, the known templates are %s", strings.Join(lintEmailTemplateFileNames(names), ", ")))
+
+			results = append(results, result)
+
+			continue
+		}
+
+		var data []byte
+
+		if data, err = os.ReadFile(result.Path); err != nil {
+			return nil, fmt.Errorf("failed to read template override at path '%s': %w", result.Path, err)
+		}
+
+		var tree *parse.Tree
+
+		switch ext {
+		case extText:
+			var t *tt.Template
+
+			if t, err = tt.New(entry.Name()).Funcs(SandboxFuncMap()).Parse(string(data)); err == nil {
+				tree = t.Tree
+			}
+		default:
+			var t *th.Template
+
+			if t, err = th.New(entry.Name()).Funcs(SandboxFuncMap()).Parse(string(data)); err == nil {
+				tree = t.Tree
+			}
+		}
+
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		} else {
+			available := EmailTemplateFields(name)
+
+			for _, field := range templateRootFields(tree) {
+				if !slices.Contains(available, field) {
+					result.Errors = append(result.Errors, fmt.Sprintf("the field '%s' is referenced but it's not available to the template, the available fields are %s", field, strings.Join(available, ", ")))
+				}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func lintEmailTemplateFileNames(names []string) (files []string) {
+	for _, name := range names {
+		files = append(files, name+extText, name+extHTML)
+	}
+
+	return files
+}
@@ -0,0 +1,84 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintEmailTemplateOverrides(t *testing.T) {
+	testCases := []struct {
+		name     string
+		files    map[string]string
+		expected []LintResult
+	}{
+		{
+			"ShouldLintEmpty",
+			nil,
+			nil,
+		},
+		{
+			"ShouldLintValid",
+			map[string]string{
+				"Event.txt":  "Hi {{ .DisplayName }}, {{ dig \"Example\" \"\" .Details }} {{ .Details | toJson }}",
+				"Event.html": "<p>{{ .Title }}</p>",
+			},
+			[]LintResult{
+				{Path: "Event.html"},
+				{Path: "Event.txt"},
+			},
+		},
+		{
+			"ShouldLintErrors",
+			map[string]string{
+				"Event.txt":                    "Hi {{ .Username }}",
+				"IdentityVerificationJWT.txt":  "{{ .Title ",
+				"IdentityVerificationOTC.html": "{{ env \"HOME\" }}",
+				"Evnt.txt":                     "{{ .Title }}",
+			},
+			[]LintResult{
+				{Path: "Event.txt", Errors: []string{"the field 'Username' is referenced but it's not available to the template, the available fields are Title, DisplayName, Details, RemoteIP"}},
+				{Path: "Evnt.txt", Warnings: []string{"the file is not a known template and will be ignored, the known templates are Event.txt, Event.html, IdentityVerificationJWT.txt, IdentityVerificationJWT.html, IdentityVerificationOTC.txt, IdentityVerificationOTC.html, SecondFactorOTC.txt, SecondFactorOTC.html"}},
+				{Path: "IdentityVerificationJWT.txt", Errors: []string{"template: IdentityVerificationJWT.txt:1: unclosed action"}},
+				{Path: "IdentityVerificationOTC.html", Errors: []string{"template: IdentityVerificationOTC.html:1: function \"env\" not defined"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			for name, content := range tc.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+			}
+
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "directory"), 0700))
+
+			actual, err := LintEmailTemplateOverrides(dir)
+			require.NoError(t, err)
+
+			for i := range tc.expected {
+				tc.expected[i].Path = filepath.Join(dir, tc.expected[i].Path)
+			}
+
+			assert.Equal(t, tc.expected, actual)
+
+			for _, result := range actual {
+				assert.Equal(t, len(result.Errors) != 0, result.HasErrors())
+			}
+		})
+	}
+}
+
+func TestLintEmailTemplateOverridesShouldErrMissingPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing")
+
+	actual, err := LintEmailTemplateOverrides(dir)
+
+	assert.EqualError(t, err, "failed to read the template override path '"+dir+"': open "+dir+": no such file or directory")
+	assert.Nil(t, actual)
+}
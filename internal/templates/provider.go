@@ -66,6 +66,17 @@ func (p *Provider) LoadTemplatedAssets(fs fs.ReadFileFS) (err error) {
 		return err
 	}
 
+	if data, err = fs.ReadFile("public_html/api/openapi.v2.yml"); err != nil {
+		return err
+	}
+
+	if p.templates.asset.api.specV2, err = tt.
+		New("assets/public_html/api/openapi.v2.yml").
+		Funcs(FuncMap()).
+		Parse(string(data)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -84,6 +95,12 @@ func (p *Provider) GetAssetOpenAPISpecTemplate() (t Template) {
 	return p.templates.asset.api.spec
 }
 
+// GetAssetOpenAPISpecV2Template returns a Template used to generate the OpenAPI specification document of version 2
+// of the API.
+func (p *Provider) GetAssetOpenAPISpecV2Template() (t Template) {
+	return p.templates.asset.api.specV2
+}
+
 // GetIdentityVerificationJWTEmailTemplate returns the EmailTemplate for Identity Verification notifications.
 func (p *Provider) GetIdentityVerificationJWTEmailTemplate() (t *EmailTemplate) {
 	return p.templates.notification.jwtIdentityVerification
@@ -94,6 +111,11 @@ func (p *Provider) GetIdentityVerificationOTCEmailTemplate() (t *EmailTemplate)
 	return p.templates.notification.otcIdentityVerification
 }
 
+// GetSecondFactorOTCEmailTemplate returns the EmailTemplate for Email second factor notifications.
+func (p *Provider) GetSecondFactorOTCEmailTemplate() (t *EmailTemplate) {
+	return p.templates.notification.otcSecondFactor
+}
+
 // GetEventEmailTemplate returns an EmailTemplate used for generic event notifications.
 func (p *Provider) GetEventEmailTemplate() (t *EmailTemplate) {
 	return p.templates.notification.event
@@ -115,6 +137,10 @@ func (p *Provider) load() (err error) {
 		errs = append(errs, err)
 	}
 
+	if p.templates.notification.otcSecondFactor, err = loadEmailTemplate(TemplateNameEmailSecondFactorOTC, p.config.EmailTemplatesPath); err != nil {
+		errs = append(errs, err)
+	}
+
 	if p.templates.notification.event, err = loadEmailTemplate(TemplateNameEmailEvent, p.config.EmailTemplatesPath); err != nil {
 		errs = append(errs, err)
 	}
@@ -0,0 +1,151 @@
+import {
+    Body,
+    Container,
+    Head,
+    Heading,
+    Hr,
+    Html,
+    Preview,
+    Section,
+    Text,
+    Tailwind,
+    Button,
+    Link,
+} from '@react-email/components';
+import * as React from 'react';
+
+interface SecondFactorOTCProps {
+    title?: string;
+    displayName?: string;
+    remoteIP?: string;
+    oneTimeCode?: string;
+    linkURL?: string;
+    linkText?: string;
+    linkPrefix?: string;
+    linkSuffix?: string;
+}
+
+export const SecondFactorOTC = ({
+    title,
+    displayName,
+    remoteIP,
+    oneTimeCode,
+    linkURL,
+    linkText,
+    linkPrefix,
+    linkSuffix,
+}: SecondFactorOTCProps) => {
+    return (
+        <Html lang="en" dir="ltr">
+            <Head />
+            <Preview>A one-time code has been generated to sign in</Preview>
+            <Tailwind>
+                <Body className="bg-white my-auto mx-auto font-sans px-2">
+                    <Container className="border border-solid border-[#eaeaea] rounded my-[40px] mx-auto p-[20px] max-w-[465px]">
+                        <Heading className="text-black text-[24px] font-normal text-center p-0 my-[30px] mx-0">
+                            A <strong>one-time code</strong> has been generated
+                            to sign in
+                        </Heading>
+                        <Text className="text-black text-[14px] leading-[24px]">
+                            Hi {displayName},
+                        </Text>
+                        <Text className="text-black text-[14px] leading-[24px]">
+                            This notification has been sent to you in order to
+                            complete the <strong>second factor</strong> of a
+                            sign in to your account.{' '}
+                        </Text>
+                        <Text className="text-black text-[14px] leading-[24px] text-center">
+                            <strong>
+                                Do not share this notification or the content of
+                                this notification with anyone.
+                            </strong>
+                        </Text>
+                        <Text className="text-black text-[14px] leading-[24px]">
+                            {' '}
+                            The following <i>one-time code</i> should only be
+                            used in the prompt displayed in your browser.
+                        </Text>
+
+                        <Hr className="border border-solid border-[#eaeaea] my-[26px] mx-0 w-full" />
+                        <Section>
+                            <Text
+                                id="one-time-code"
+                                className="text-black text-center tracking-[0.5rem] font-bold text-lg"
+                                style={{ marginRight: '-0.5rem !important' }}
+                            >
+                                {oneTimeCode}
+                            </Text>
+                        </Section>
+                        <Hr className="border border-solid border-[#eaeaea] my-[26px] mx-0 w-full" />
+                        {linkPrefix}
+                        <Section className="text-center">
+                            <Button
+                                id="link"
+                                href={linkURL}
+                                className="bg-[#1976d2] rounded text-white text-[12px] font-semibold no-underline text-center px-5 py-3"
+                            >
+                                {linkText}
+                            </Button>
+                        </Section>
+                        <Text className="text-black text-[14px] leading-[24px] text-center">
+                            Alternatively click the above button in the same
+                            browser or copy and paste this URL into it:{' '}
+                        </Text>
+                        <Text className="text-black text-[14px] leading-[24px] text-center">
+                            <Link
+                                href={linkURL}
+                                className="text-blue-600 no-underline"
+                            >
+                                {linkURL}
+                            </Link>
+                        </Text>
+                        <Hr className="border border-solid border-[#eaeaea] my-[26px] mx-0 w-full" />
+                        {linkSuffix}
+                        <Text className="text-black text-[14px] leading-[24px]">
+                            If you did not initiate the sign in your password
+                            has been compromised and you should:
+                        </Text>
+                        <Section className="text-black text-[14px] leading-[22px]">
+                            <ol>
+                                <li>Reset your password</li>
+                                <li>Contact an Administrator</li>
+                            </ol>
+                        </Section>
+                        <Hr className="border border-solid border-[#eaeaea] my-[26px] mx-0 w-full" />
+                        <Text className="text-[#666666] text-[12px] leading-[24px] text-center">
+                            This notification was intended for{' '}
+                            <span className="text-black">{displayName}</span>.
+                            This one-time code was generated due to an action
+                            from <span className="text-black">{remoteIP}</span>.
+                            If you do not believe that your actions could have
+                            triggered this event or if you are concerned about
+                            your account's safety, please follow the explicit
+                            directions in this notification.
+                        </Text>
+                    </Container>
+                    <Text className="text-[#666666] text-[10px] leading-[24px] text-center text-muted">
+                        Powered by{' '}
+                        <Link
+                            href="https://www.authelia.com"
+                            target="_blank"
+                            className="text-[#666666]"
+                        >
+                            Authelia
+                        </Link>
+                    </Text>
+                </Body>
+            </Tailwind>
+        </Html>
+    );
+};
+
+SecondFactorOTC.PreviewProps = {
+    title: 'Sign in',
+    displayName: 'John Doe',
+    oneTimeCode: 'ABC123',
+    linkURL: 'https://auth.example.com/api/secondfactor/email/link?code=ABC123',
+    linkText: 'Sign in',
+    remoteIP: '127.0.0.1',
+} as SecondFactorOTCProps;
+
+export default SecondFactorOTC;
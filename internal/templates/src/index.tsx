@@ -5,6 +5,7 @@ import * as fs from "node:fs";
 import Event from './emails/Event';
 import IdentityVerificationJWT from "./emails/IdentityVerificationJWT";
 import IdentityVerificationOTC from "./emails/IdentityVerificationOTC";
+import SecondFactorOTC from "./emails/SecondFactorOTC";
 
 const optsHTML = {
 	pretty: false,
@@ -54,6 +55,20 @@ async function doRender() {
 
 	fs.writeFileSync('../embed/notification/IdentityVerificationOTC.html', await render(<IdentityVerificationOTC {...propsOTC} />, optsHTML));
 	fs.writeFileSync('../embed/notification/IdentityVerificationOTC.txt', await render(<IdentityVerificationOTC {...propsOTC} />, optsTXT));
+
+	const propsSecondFactorOTC = {
+		title: "{{ .Title }}",
+		displayName: "{{ .DisplayName }}",
+		remoteIP: "{{ .RemoteIP }}",
+		oneTimeCode: "{{ .OneTimeCode }}",
+		linkURL: "{{ .LinkURL }}",
+		linkText: "{{ .LinkText }}",
+		linkPrefix: "{{- if .LinkURL }}",
+		linkSuffix: "{{- end }}",
+	};
+
+	fs.writeFileSync('../embed/notification/SecondFactorOTC.html', await render(<SecondFactorOTC {...propsSecondFactorOTC} />, optsHTML));
+	fs.writeFileSync('../embed/notification/SecondFactorOTC.txt', await render(<SecondFactorOTC {...propsSecondFactorOTC} />, optsTXT));
 }
 
 doRender().then();
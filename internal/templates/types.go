@@ -25,14 +25,16 @@ type AssetTemplates struct {
 
 // OpenAPIAssetTemplates are asset templates for the OpenAPI specification.
 type OpenAPIAssetTemplates struct {
-	index *tt.Template
-	spec  *tt.Template
+	index  *tt.Template
+	spec   *tt.Template
+	specV2 *tt.Template
 }
 
 // NotificationTemplates are the templates for the notification system.
 type NotificationTemplates struct {
 	jwtIdentityVerification *EmailTemplate
 	otcIdentityVerification *EmailTemplate
+	otcSecondFactor         *EmailTemplate
 	event                   *EmailTemplate
 }
 
@@ -83,3 +85,33 @@ type EmailIdentityVerificationOTCValues struct {
 	RevocationLinkURL  string
 	RevocationLinkText string
 }
+
+// EmailSecondFactorOTCValues are the values used for the Email second factor OTP templates.
+type EmailSecondFactorOTCValues struct {
+	Title       string
+	DisplayName string
+	RemoteIP    string
+	OneTimeCode string
+	LinkURL     string
+	LinkText    string
+}
+
+// UnknownTemplateFields describes the fields referenced by a template override which are not available to it.
+type UnknownTemplateFields struct {
+	Path      string
+	Fields    []string
+	Available []string
+}
+
+// LintResult describes the problems found with a template override when linting it. The template can't be loaded if
+// there are any errors.
+type LintResult struct {
+	Path     string   `json:"path"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// HasErrors returns true if the template override has any errors.
+func (r LintResult) HasErrors() bool {
+	return len(r.Errors) != 0
+}
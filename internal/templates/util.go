@@ -69,7 +69,7 @@ func readTemplate(name, ext, category, overridePath string) (tPath string, embed
 }
 
 func parseTextTemplate(name, tPath string, embed bool, data []byte) (t *tt.Template, err error) {
-	if t, err = tt.New(name + extText).Funcs(FuncMap()).Parse(string(data)); err != nil {
+	if t, err = tt.New(name + extText).Funcs(SandboxFuncMap()).Parse(string(data)); err != nil {
 		if embed {
 			return nil, fmt.Errorf("failed to parse embedded template '%s': %w", tPath, err)
 		}
@@ -81,7 +81,7 @@ func parseTextTemplate(name, tPath string, embed bool, data []byte) (t *tt.Templ
 }
 
 func parseHTMLTemplate(name, tPath string, embed bool, data []byte) (t *th.Template, err error) {
-	if t, err = th.New(name + extHTML).Funcs(FuncMap()).Parse(string(data)); err != nil {
+	if t, err = th.New(name + extHTML).Funcs(SandboxFuncMap()).Parse(string(data)); err != nil {
 		if embed {
 			return nil, fmt.Errorf("failed to parse embedded template '%s': %w", tPath, err)
 		}
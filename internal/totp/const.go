@@ -0,0 +1,5 @@
+package totp
+
+// totpSkewCompensationMaximum is the maximum number of additional periods the validation window is widened by to
+// compensate for the clock skew.
+const totpSkewCompensationMaximum uint = 2
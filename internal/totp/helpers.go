@@ -1,6 +1,8 @@
 package totp
 
 import (
+	"time"
+
 	"github.com/authelia/otp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -18,3 +20,23 @@ func otpStringToAlgo(in string) (algorithm otp.Algorithm) {
 		return otp.AlgorithmSHA1
 	}
 }
+
+// totpDriftBounded returns the drift bounded by the maximum number of periods in either direction.
+func totpDriftBounded(drift, maximum int) int {
+	return max(min(drift, maximum), -maximum)
+}
+
+// totpSkewCompensation returns the number of additional periods required to cover the clock skew.
+func totpSkewCompensation(skew time.Duration, period uint) uint {
+	if period == 0 {
+		return 0
+	}
+
+	if skew < 0 {
+		skew = -skew
+	}
+
+	p := time.Duration(period) * time.Second
+
+	return min(uint((skew+p-1)/p), totpSkewCompensationMaximum)
+}
@@ -2,6 +2,7 @@ package totp
 
 import (
 	"testing"
+	"time"
 
 	"github.com/authelia/otp"
 	"github.com/stretchr/testify/assert"
@@ -13,3 +14,20 @@ func TestOTPStringToAlgo(t *testing.T) {
 	assert.Equal(t, otp.AlgorithmSHA512, otpStringToAlgo("SHA512"))
 	assert.Equal(t, otp.AlgorithmSHA1, otpStringToAlgo(""))
 }
+
+func TestTOTPSkewCompensation(t *testing.T) {
+	assert.Equal(t, uint(0), totpSkewCompensation(0, 30))
+	assert.Equal(t, uint(1), totpSkewCompensation(time.Second*4, 30))
+	assert.Equal(t, uint(1), totpSkewCompensation(-time.Second*30, 30))
+	assert.Equal(t, uint(2), totpSkewCompensation(time.Second*31, 30))
+	assert.Equal(t, uint(2), totpSkewCompensation(-time.Hour, 30))
+	assert.Equal(t, uint(0), totpSkewCompensation(time.Hour, 0))
+}
+
+func TestTOTPDriftBounded(t *testing.T) {
+	assert.Equal(t, 0, totpDriftBounded(0, 4))
+	assert.Equal(t, 3, totpDriftBounded(3, 4))
+	assert.Equal(t, 4, totpDriftBounded(9, 4))
+	assert.Equal(t, -4, totpDriftBounded(-9, 4))
+	assert.Equal(t, -2, totpDriftBounded(-2, 4))
+}
@@ -1,6 +1,8 @@
 package totp
 
 import (
+	"time"
+
 	"github.com/authelia/authelia/v4/internal/model"
 )
 
@@ -11,3 +13,8 @@ type Provider interface {
 	Validate(ctx Context, token string, config *model.TOTPConfiguration) (valid bool, step uint64, err error)
 	Options() model.TOTPOptions
 }
+
+// ClockSkewProvider provides the measured skew of the system clock.
+type ClockSkewProvider interface {
+	ClockSkew() (skew time.Duration, exceeded bool)
+}
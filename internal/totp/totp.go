@@ -3,6 +3,7 @@ package totp
 import (
 	"encoding/base32"
 	"fmt"
+	"time"
 
 	"github.com/authelia/otp"
 	"github.com/authelia/otp/totp"
@@ -28,6 +29,10 @@ func NewTimeBasedProvider(config schema.TOTP) (provider *TimeBased) {
 		provider.skew = 1
 	}
 
+	if config.DriftCompensation.Enable {
+		provider.compensation = config.DriftCompensation.Maximum
+	}
+
 	return provider
 }
 
@@ -52,6 +57,17 @@ type TimeBased struct {
 	period    uint
 	skew      uint
 	size      uint
+
+	compensation int
+
+	drift ClockSkewProvider
+}
+
+// SetClockSkewProvider sets the provider of the measured clock skew. While the measured clock skew exceeds the maximum
+// desync the validation window is widened by the number of periods required to cover the clock skew, up to a maximum
+// of two periods, so the users are not locked out while the system clock is out of sync.
+func (p *TimeBased) SetClockSkewProvider(provider ClockSkewProvider) {
+	p.drift = provider
 }
 
 // GenerateCustom generates a TOTP with custom options.
@@ -103,16 +119,44 @@ func (p TimeBased) Generate(ctx Context, username string) (config *model.TOTPCon
 	return p.GenerateCustom(ctx, username, p.algorithm, "", p.digits, p.period, p.size)
 }
 
-// Validate the token against the given configuration.
+// Validate the token against the given configuration. When the drift compensation is enabled the validation window is
+// centered on the learned clock drift of the configuration, and the learned clock drift of the configuration is
+// updated to the offset observed for a valid token. The learned clock drift is bounded by the configured maximum and
+// is not updated while the measured clock skew of the system clock exceeds the maximum desync.
 func (p TimeBased) Validate(ctx Context, token string, config *model.TOTPConfiguration) (valid bool, step uint64, err error) {
+	skew, learn := p.skew, p.compensation > 0
+
+	if p.drift != nil {
+		if offset, exceeded := p.drift.ClockSkew(); exceeded {
+			skew += totpSkewCompensation(offset, config.Period)
+			learn = false
+		}
+	}
+
+	now := ctx.GetClock().Now().UTC()
+
+	at := now
+
+	if p.compensation > 0 {
+		at = now.Add(time.Second * time.Duration(config.Period) * time.Duration(totpDriftBounded(config.Drift, p.compensation)))
+	}
+
 	opts := totp.ValidateOpts{
 		Period:    config.Period,
-		Skew:      p.skew,
+		Skew:      skew,
 		Digits:    otp.Digits(config.Digits),
 		Algorithm: otpStringToAlgo(config.Algorithm),
 	}
 
-	return totp.ValidateCustomStep(token, string(config.Secret), ctx.GetClock().Now().UTC(), opts)
+	if valid, step, err = totp.ValidateCustomStep(token, string(config.Secret), at, opts); err != nil || !valid {
+		return valid, step, err
+	}
+
+	if learn {
+		config.Drift = totpDriftBounded(config.StepOffset(now, step), p.compensation)
+	}
+
+	return true, step, nil
 }
 
 // Options returns the configured options for this provider.
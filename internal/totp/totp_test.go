@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/authelia/otp"
+	"github.com/authelia/otp/totp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -156,3 +158,138 @@ func TestTOTPGenerate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, secret, 32)
 }
+
+func TestTOTPValidateClockSkew(t *testing.T) {
+	skew := 1
+
+	provider := NewTimeBasedProvider(schema.TOTP{
+		Issuer:           "Authelia",
+		DefaultAlgorithm: "SHA1",
+		DefaultDigits:    6,
+		DefaultPeriod:    30,
+		Skew:             &skew,
+		SecretSize:       32,
+	})
+
+	now := time.Unix(1760000000, 0)
+
+	ctx := NewContext(context.TODO(), clock.NewFixed(now), &random.Cryptographical{})
+
+	config, err := provider.Generate(ctx, "john")
+	require.NoError(t, err)
+
+	code, err := totp.GenerateCodeCustom(string(config.Secret), now.Add(-time.Second*90), totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	require.NoError(t, err)
+
+	valid, _, err := provider.Validate(ctx, code, config)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	drift := &testClockSkewProvider{skew: time.Second * 2}
+
+	provider.SetClockSkewProvider(drift)
+
+	valid, _, err = provider.Validate(ctx, code, config)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	drift.skew, drift.exceeded = -time.Second*50, true
+
+	valid, _, err = provider.Validate(ctx, code, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestTOTPValidateDriftCompensation(t *testing.T) {
+	skew := 1
+
+	provider := NewTimeBasedProvider(schema.TOTP{
+		Issuer:            "Authelia",
+		DefaultAlgorithm:  "SHA1",
+		DefaultDigits:     6,
+		DefaultPeriod:     30,
+		Skew:              &skew,
+		SecretSize:        32,
+		DriftCompensation: schema.TOTPDriftCompensation{Enable: true, Maximum: 4},
+	})
+
+	now := time.Unix(1760000000, 0)
+
+	ctx := NewContext(context.TODO(), clock.NewFixed(now), &random.Cryptographical{})
+
+	config, err := provider.Generate(ctx, "john")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		offset   int
+		valid    bool
+		expected int
+	}{
+		{"ShouldNotValidateOutsideWindow", 2, false, 0},
+		{"ShouldLearnDriftWithinWindow", 1, true, 1},
+		{"ShouldValidateWithLearnedDrift", 2, true, 2},
+		{"ShouldFollowDrift", 3, true, 3},
+		{"ShouldNotValidateOutsideLearnedWindow", 5, false, 3},
+		{"ShouldLearnMaximumDrift", 4, true, 4},
+		{"ShouldBoundDriftToMaximum", 5, true, 4},
+		{"ShouldNotValidateBeyondMaximum", 6, false, 4},
+		{"ShouldLearnDriftBackwards", 3, true, 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			code, err := totp.GenerateCodeCustom(string(config.Secret), now.Add(time.Second*30*time.Duration(tc.offset)), totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+			require.NoError(t, err)
+
+			valid, step, err := provider.Validate(ctx, code, config)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.expected, config.Drift)
+
+			if tc.valid {
+				assert.Equal(t, tc.offset, config.StepOffset(now, step))
+			}
+		})
+	}
+
+	provider.SetClockSkewProvider(&testClockSkewProvider{skew: time.Second * 40, exceeded: true})
+
+	code, err := totp.GenerateCodeCustom(string(config.Secret), now.Add(time.Second*30), totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	require.NoError(t, err)
+
+	valid, _, err := provider.Validate(ctx, code, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, 3, config.Drift)
+}
+
+func TestTOTPValidateDriftCompensationDisabled(t *testing.T) {
+	provider := NewTimeBasedProvider(schema.DefaultTOTPConfiguration)
+
+	now := time.Unix(1760000000, 0)
+
+	ctx := NewContext(context.TODO(), clock.NewFixed(now), &random.Cryptographical{})
+
+	config, err := provider.Generate(ctx, "john")
+	require.NoError(t, err)
+
+	config.Drift = 3
+
+	code, err := totp.GenerateCodeCustom(string(config.Secret), now.Add(time.Second*30), totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	require.NoError(t, err)
+
+	valid, _, err := provider.Validate(ctx, code, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, 3, config.Drift)
+}
+
+type testClockSkewProvider struct {
+	skew     time.Duration
+	exceeded bool
+}
+
+func (p *testClockSkewProvider) ClockSkew() (skew time.Duration, exceeded bool) {
+	return p.skew, p.exceeded
+}
@@ -8,9 +8,11 @@ import { Route, BrowserRouter as Router, Routes } from "react-router-dom";
 
 import NotificationBar from "@components/NotificationBar";
 import {
+    ConsentDeviceAuthorizationRoute,
     ConsentRoute,
     IndexRoute,
     LogoutRoute,
+    PushChallengeRoute,
     ResetPasswordStep1Route,
     ResetPasswordStep2Route,
     RevokeOneTimeCodeRoute,
@@ -29,12 +31,14 @@ import LoginPortal from "@views/LoginPortal/LoginPortal";
 import "@fortawesome/fontawesome-svg-core/styles.css";
 
 const ConsentView = lazy(() => import("@views/LoginPortal/ConsentView/ConsentView"));
+const DeviceAuthorizationView = lazy(() => import("@views/LoginPortal/ConsentView/DeviceAuthorizationView"));
 const SignOut = lazy(() => import("@views/LoginPortal/SignOut/SignOut"));
 const ResetPasswordStep1 = lazy(() => import("@views/ResetPassword/ResetPasswordStep1"));
 const ResetPasswordStep2 = lazy(() => import("@views/ResetPassword/ResetPasswordStep2"));
 const SettingsRouter = lazy(() => import("@views/Settings/SettingsRouter"));
 const RevokeOneTimeCodeView = lazy(() => import("@views/Revoke/RevokeOneTimeCodeView"));
 const RevokeResetPasswordTokenView = lazy(() => import("@views/Revoke/RevokeResetPasswordTokenView"));
+const PushChallengeView = lazy(() => import("@views/PushChallenge/PushChallengeView"));
 
 faConfig.autoAddCss = false;
 
@@ -65,8 +69,13 @@ const App: React.FC<Props> = (props: Props) => {
                                     <Route path={ResetPasswordStep2Route} element={<ResetPasswordStep2 />} />
                                     <Route path={LogoutRoute} element={<SignOut />} />
                                     <Route path={ConsentRoute} element={<ConsentView />} />
+                                    <Route
+                                        path={ConsentDeviceAuthorizationRoute}
+                                        element={<DeviceAuthorizationView />}
+                                    />
                                     <Route path={RevokeOneTimeCodeRoute} element={<RevokeOneTimeCodeView />} />
                                     <Route path={RevokeResetPasswordRoute} element={<RevokeResetPasswordTokenView />} />
+                                    <Route path={PushChallengeRoute} element={<PushChallengeView />} />
                                     <Route path={`${SettingsRoute}/*`} element={<SettingsRouter />} />
                                     <Route
                                         path={`${IndexRoute}*`}
@@ -1,15 +1,17 @@
 import React, { Fragment } from "react";
 
-import { Divider, Link, Theme } from "@mui/material";
+import { Divider, Link, Theme, Typography } from "@mui/material";
 import { grey } from "@mui/material/colors";
 import Grid from "@mui/material/Grid2";
 import makeStyles from "@mui/styles/makeStyles";
 import { useTranslation } from "react-i18next";
 
 import PrivacyPolicyLink from "@components/PrivacyPolicyLink";
-import { getPrivacyPolicyEnabled } from "@utils/Configuration";
+import { getBrandingFooter, getPrivacyPolicyEnabled } from "@utils/Configuration";
 
-export interface Props {}
+export interface Props {
+    supportContact?: string;
+}
 
 const url = "https://www.authelia.com";
 
@@ -18,9 +20,15 @@ const Brand = function (props: Props) {
 
     const styles = useStyles();
     const privacyEnabled = getPrivacyPolicyEnabled();
+    const footer = getBrandingFooter();
 
     return (
         <Grid container size={{ xs: 12 }} alignItems="center" justifyContent="center">
+            {footer ? (
+                <Grid size={{ xs: 12 }}>
+                    <Typography className={styles.links}>{footer}</Typography>
+                </Grid>
+            ) : null}
             <Grid size={{ xs: 4 }}>
                 <Link href={url} target="_blank" underline="hover" className={styles.links}>
                     {translate("Powered by")} Authelia
@@ -34,6 +42,16 @@ const Brand = function (props: Props) {
                     </Grid>
                 </Fragment>
             ) : null}
+            {props.supportContact ? (
+                <Fragment>
+                    <Divider orientation="vertical" flexItem variant="middle" />
+                    <Grid size={{ xs: 4 }}>
+                        <Link href={props.supportContact} underline="hover" className={styles.links}>
+                            {translate("Contact Support")}
+                        </Link>
+                    </Grid>
+                </Fragment>
+            ) : null}
         </Grid>
     );
 };
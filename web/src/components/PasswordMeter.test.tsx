@@ -12,6 +12,7 @@ it("renders without crashing", () => {
                 max_length: 0,
                 min_length: 4,
                 min_score: 0,
+                min_entropy: 0,
                 require_lowercase: false,
                 require_number: false,
                 require_special: false,
@@ -30,6 +31,7 @@ it("renders adjusted height without crashing", () => {
                 max_length: 0,
                 min_length: 4,
                 min_score: 0,
+                min_entropy: 0,
                 require_lowercase: false,
                 require_number: false,
                 require_special: false,
@@ -49,6 +51,7 @@ it("displays warning message on password too large", async () => {
                 max_length: maxLenght,
                 min_length: 4,
                 min_score: 0,
+                min_entropy: 0,
                 require_lowercase: true,
                 require_number: true,
                 require_special: true,
@@ -71,6 +74,7 @@ it("displays warning message on password too short", async () => {
                 max_length: 0,
                 min_length: minLenght,
                 min_score: 0,
+                min_entropy: 0,
                 require_lowercase: true,
                 require_number: true,
                 require_special: true,
@@ -92,6 +96,7 @@ it("displays warning message on password policy fail", async () => {
                 max_length: 0,
                 min_length: 0,
                 min_score: 0,
+                min_entropy: 0,
                 require_lowercase: true,
                 require_number: true,
                 require_special: true,
@@ -1,6 +1,7 @@
 export const IndexRoute: string = "/";
 export const AuthenticatedRoute: string = "/authenticated";
 export const ConsentRoute: string = "/consent";
+export const ConsentDeviceAuthorizationRoute: string = "/consent/openid/device-authorization";
 
 export const SecondFactorRoute: string = "/2fa";
 export const SecondFactorWebAuthnSubRoute: string = "/webauthn";
@@ -15,3 +16,4 @@ export const SettingsRoute: string = "/settings";
 export const SettingsTwoFactorAuthenticationSubRoute: string = "/two-factor-authentication";
 export const RevokeOneTimeCodeRoute: string = "/revoke/one-time-code";
 export const RevokeResetPasswordRoute: string = "/revoke/reset-password";
+export const PushChallengeRoute: string = "/push/challenge";
@@ -5,3 +5,7 @@ export const IdentityToken: string = "token";
 export const RedirectionURL: string = "rd";
 
 export const RequestMethod: string = "rm";
+
+export const UserCode: string = "user_code";
+
+export const UserVerification: string = "uv";
@@ -1,11 +1,11 @@
 import React, { createContext, useCallback, useContext, useEffect, useState } from "react";
 
-import { Theme, ThemeProvider } from "@mui/material";
+import { Theme, ThemeProvider, createTheme } from "@mui/material";
 
 import { LocalStorageThemeName } from "@constants/LocalStorage";
 import { localStorageAvailable } from "@services/LocalStorage";
 import * as themes from "@themes/index";
-import { getTheme } from "@utils/Configuration";
+import { getBrandingPrimaryColor, getBrandingSecondaryColor, getTheme } from "@utils/Configuration";
 
 const MediaQueryDarkMode = "(prefers-color-scheme: dark)";
 
@@ -62,7 +62,7 @@ export default function ThemeContextProvider(props: Props) {
     };
 
     const mediaQueryListener = (ev: MediaQueryListEvent) => {
-        setTheme(ev.matches ? themes.Dark : themes.Light);
+        setTheme(ThemeWithBranding(ev.matches ? themes.Dark : themes.Light));
     };
 
     const callback = useCallback(
@@ -121,6 +121,27 @@ function GetCurrentTheme() {
 }
 
 function ThemeFromName(name: string) {
+    return ThemeWithBranding(ThemeFromNameBase(name));
+}
+
+function ThemeWithBranding(theme: Theme) {
+    const primary = getBrandingPrimaryColor();
+    const secondary = getBrandingSecondaryColor();
+
+    if (primary === "" && secondary === "") {
+        return theme;
+    }
+
+    return createTheme(theme, {
+        palette: {
+            primary: primary === "" ? theme.palette.primary : theme.palette.augmentColor({ color: { main: primary } }),
+            secondary:
+                secondary === "" ? theme.palette.secondary : theme.palette.augmentColor({ color: { main: secondary } }),
+        },
+    });
+}
+
+function ThemeFromNameBase(name: string) {
     switch (name) {
         case themes.ThemeNameLight:
             return themes.Light;
@@ -1,6 +1,10 @@
 import { useRemoteCall } from "@hooks/RemoteCall";
-import { getConfiguration } from "@services/Configuration";
+import { getConfiguration, getConfigurationBranding } from "@services/Configuration";
 
 export function useConfiguration() {
     return useRemoteCall(getConfiguration, []);
 }
+
+export function useConfigurationBranding() {
+    return useRemoteCall(getConfigurationBranding, []);
+}
@@ -1,6 +1,6 @@
 import React, { ReactNode, useEffect } from "react";
 
-import { AppBar, Box, Container, Theme, Toolbar, Typography } from "@mui/material";
+import { Alert, AppBar, Box, Container, Theme, Toolbar, Typography } from "@mui/material";
 import Grid from "@mui/material/Grid2";
 import makeStyles from "@mui/styles/makeStyles";
 import { useTranslation } from "react-i18next";
@@ -10,8 +10,9 @@ import AccountSettingsMenu from "@components/AccountSettingsMenu";
 import Brand from "@components/Brand";
 import PrivacyPolicyDrawer from "@components/PrivacyPolicyDrawer";
 import TypographyWithTooltip from "@components/TypographyWithTooltip";
+import { useConfigurationBranding } from "@hooks/Configuration";
 import { UserInfo } from "@models/UserInfo";
-import { getLogoOverride } from "@utils/Configuration";
+import { getBrandingMessage, getBrandingTitle, getLogoOverride } from "@utils/Configuration";
 
 export interface Props {
     id?: string;
@@ -27,16 +28,24 @@ const LoginLayout = function (props: Props) {
     const { t: translate } = useTranslation();
 
     const styles = useStyles();
+    const message = getBrandingMessage();
+    const [branding, fetchBranding] = useConfigurationBranding();
 
-    const logo = getLogoOverride() ? (
+    useEffect(() => {
+        fetchBranding();
+    }, [fetchBranding]);
+
+    const logo = branding?.logo_url ? (
+        <img src={branding.logo_url} alt="Logo" className={styles.icon} />
+    ) : getLogoOverride() ? (
         <img src="./static/media/logo.png" alt="Logo" className={styles.icon} />
     ) : (
         <UserSvg className={styles.icon} />
     );
 
     useEffect(() => {
-        document.title = `${translate("Login")} - Authelia`;
-    }, [translate]);
+        document.title = `${translate("Login")} - ${branding?.title || getBrandingTitle() || "Authelia"}`;
+    }, [translate, branding]);
 
     return (
         <Box>
@@ -46,6 +55,11 @@ const LoginLayout = function (props: Props) {
                     {props.userInfo ? <AccountSettingsMenu userInfo={props.userInfo} /> : null}
                 </Toolbar>
             </AppBar>
+            {branding?.announcement ? (
+                <Alert severity={"info"} variant={"outlined"} className={styles.announcement}>
+                    {branding.announcement}
+                </Alert>
+            ) : null}
             <Grid
                 id={props.id}
                 className={styles.root}
@@ -75,10 +89,15 @@ const LoginLayout = function (props: Props) {
                                 />
                             </Grid>
                         ) : null}
+                        {message ? (
+                            <Grid size={{ xs: 12 }} className={styles.message}>
+                                <Typography variant={"body2"}>{message}</Typography>
+                            </Grid>
+                        ) : null}
                         <Grid size={{ xs: 12 }} className={styles.body}>
                             {props.children}
                         </Grid>
-                        <Brand />
+                        <Brand supportContact={branding?.support_contact} />
                     </Grid>
                 </Container>
                 <PrivacyPolicyDrawer />
@@ -98,11 +117,21 @@ const useStyles = makeStyles((theme: Theme) => ({
     },
     title: {},
     subtitle: {},
+    announcement: {
+        justifyContent: "center",
+        marginLeft: theme.spacing(2),
+        marginRight: theme.spacing(2),
+        whiteSpace: "pre-line",
+    },
     icon: {
         margin: theme.spacing(),
         width: "64px",
         fill: theme.custom.icon,
     },
+    message: {
+        marginTop: theme.spacing(),
+        whiteSpace: "pre-line",
+    },
     body: {
         marginTop: theme.spacing(),
         paddingTop: theme.spacing(),
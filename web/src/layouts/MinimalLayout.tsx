@@ -9,8 +9,9 @@ import UserSvg from "@assets/images/user.svg?react";
 import AccountSettingsMenu from "@components/AccountSettingsMenu";
 import PrivacyPolicyDrawer from "@components/PrivacyPolicyDrawer";
 import TypographyWithTooltip from "@components/TypographyWithTooltip";
+import { useConfigurationBranding } from "@hooks/Configuration";
 import { UserInfo } from "@models/UserInfo";
-import { getLogoOverride } from "@utils/Configuration";
+import { getBrandingTitle, getLogoOverride } from "@utils/Configuration";
 
 export interface Props {
     id?: string;
@@ -23,16 +24,23 @@ const MinimalLayout = function (props: Props) {
     const { t: translate } = useTranslation();
 
     const styles = useStyles();
+    const [branding, fetchBranding] = useConfigurationBranding();
 
-    const logo = getLogoOverride() ? (
+    useEffect(() => {
+        fetchBranding();
+    }, [fetchBranding]);
+
+    const logo = branding?.logo_url ? (
+        <img src={branding.logo_url} alt="Logo" className={styles.icon} />
+    ) : getLogoOverride() ? (
         <img src="./static/media/logo.png" alt="Logo" className={styles.icon} />
     ) : (
         <UserSvg className={styles.icon} />
     );
 
     useEffect(() => {
-        document.title = `${translate("Login")} - Authelia`;
-    }, [translate]);
+        document.title = `${translate("Login")} - ${branding?.title || getBrandingTitle() || "Authelia"}`;
+    }, [translate, branding]);
 
     return (
         <Box>
@@ -3,3 +3,26 @@ import { SecondFactorMethod } from "@models/Methods";
 export interface Configuration {
     available_methods: Set<SecondFactorMethod>;
 }
+
+export interface ConfigurationBranding {
+    title?: string;
+    logo_url?: string;
+    announcement?: string;
+    support_contact?: string;
+}
+
+export type Feature =
+    | "openid_connect"
+    | "metrics"
+    | "password_reset"
+    | "password_policy"
+    | "privacy_policy"
+    | "totp"
+    | "webauthn"
+    | "duo"
+    | "duo_self_enrollment"
+    | "push"
+    | "spnego"
+    | "passkey_login";
+
+export type ConfigurationFeatures = Record<Feature, boolean>;
@@ -9,6 +9,7 @@ export interface PasswordPolicyConfiguration {
     min_length: number;
     max_length: number;
     min_score: number;
+    min_entropy: number;
     require_uppercase: boolean;
     require_lowercase: boolean;
     require_number: boolean;
@@ -23,6 +23,10 @@ export interface CredentialRequest {
     publicKey: PublicKeyCredentialRequestOptionsJSON;
 }
 
+export interface PasskeyCredentialRequest extends CredentialRequest {
+    mediation?: "conditional";
+}
+
 export enum AttestationResult {
     Success = 1,
     Failure,
@@ -113,6 +117,7 @@ export interface WebAuthnCredential {
     description: string;
     kid: Uint8Array;
     aaguid?: string;
+    authenticator?: string;
     attestation_type: string;
     attachment: string;
     transports: null | string[];
@@ -6,8 +6,11 @@ const basePath = getBasePath();
 
 // Note: If you change this const you must also do so in the backend at internal/handlers/cost.go.
 export const ConsentPath = basePath + "/api/oidc/consent";
+export const ConsentDeviceAuthorizationPath = basePath + "/api/oidc/device-authorization";
 
 export const FirstFactorPath = basePath + "/api/firstfactor";
+export const FirstFactorSPNEGOPath = basePath + "/api/firstfactor/spnego";
+export const FirstFactorPasskeyPath = basePath + "/api/firstfactor/passkey";
 
 export const TOTPRegistrationPath = basePath + "/api/secondfactor/totp/register";
 export const TOTPConfigurationPath = basePath + "/api/secondfactor/totp";
@@ -21,7 +24,12 @@ export const InitiateDuoDeviceSelectionPath = basePath + "/api/secondfactor/duo_
 export const CompleteDuoDeviceSelectionPath = basePath + "/api/secondfactor/duo_device";
 
 export const CompletePushNotificationSignInPath = basePath + "/api/secondfactor/duo";
+export const InitiatePushNotificationPath = basePath + "/api/secondfactor/push";
+export const PushNotificationStatusPath = basePath + "/api/secondfactor/push/status";
+export const PushChallengePath = basePath + "/api/secondfactor/push/challenge";
 export const CompleteTOTPSignInPath = basePath + "/api/secondfactor/totp";
+export const CompleteRecoveryCodeSignInPath = basePath + "/api/secondfactor/recovery_code";
+export const RecoveryCodesPath = basePath + "/api/secondfactor/recovery_codes";
 
 export const InitiateResetPasswordPath = basePath + "/api/reset-password/identity/start";
 export const CompleteResetPasswordPath = basePath + "/api/reset-password/identity/finish";
@@ -35,8 +43,12 @@ export const StatePath = basePath + "/api/state";
 export const UserInfoPath = basePath + "/api/user/info";
 export const UserInfo2FAMethodPath = basePath + "/api/user/info/2fa_method";
 export const UserSessionElevationPath = basePath + "/api/user/session/elevation";
+export const UserAppPasswordsPath = basePath + "/api/user/app-passwords";
+export const UserSessionsPath = basePath + "/api/user/sessions";
 
 export const ConfigurationPath = basePath + "/api/configuration";
+export const ConfigurationFeaturesPath = basePath + "/api/configuration/features";
+export const ConfigurationBrandingPath = basePath + "/api/configuration/branding";
 export const PasswordPolicyConfigurationPath = basePath + "/api/configuration/password-policy";
 
 export interface AuthenticationErrorResponse extends ErrorResponse {
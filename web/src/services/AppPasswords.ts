@@ -0,0 +1,43 @@
+import { UserAppPasswordsPath } from "@services/Api";
+import { DeleteWithOptionalResponse, GetWithOptionalData, Post } from "@services/Client";
+
+interface GenerateAppPasswordBody {
+    name: string;
+    domains: string[];
+}
+
+export interface AppPassword {
+    id: string;
+    name: string;
+    domains: string[];
+    created_at: string;
+    last_used_at?: string;
+}
+
+export interface GeneratedAppPassword {
+    app_password: AppPassword;
+    password: string;
+}
+
+export async function getAppPasswords(): Promise<AppPassword[]> {
+    const res = await GetWithOptionalData<AppPassword[] | null>(UserAppPasswordsPath);
+
+    if (res === null) {
+        return [];
+    }
+
+    return res;
+}
+
+export function generateAppPassword(name: string, domains: string[]) {
+    const body: GenerateAppPasswordBody = {
+        name: name,
+        domains: domains,
+    };
+
+    return Post<GeneratedAppPassword>(UserAppPasswordsPath, body);
+}
+
+export function deleteAppPassword(id: string) {
+    return DeleteWithOptionalResponse(`${UserAppPasswordsPath}/${id}`);
+}
@@ -1,5 +1,5 @@
-import { Configuration } from "@models/Configuration";
-import { ConfigurationPath } from "@services/Api";
+import { Configuration, ConfigurationBranding, ConfigurationFeatures } from "@models/Configuration";
+import { ConfigurationBrandingPath, ConfigurationFeaturesPath, ConfigurationPath } from "@services/Api";
 import { Get } from "@services/Client";
 import { Method2FA, toSecondFactorMethod } from "@services/UserInfo";
 
@@ -7,7 +7,20 @@ interface ConfigurationPayload {
     available_methods: Method2FA[];
 }
 
+interface ConfigurationFeaturesPayload {
+    features: ConfigurationFeatures;
+}
+
 export async function getConfiguration(): Promise<Configuration> {
     const config = await Get<ConfigurationPayload>(ConfigurationPath);
     return { ...config, available_methods: new Set(config.available_methods.map(toSecondFactorMethod)) };
 }
+
+export async function getConfigurationFeatures(): Promise<ConfigurationFeatures> {
+    const res = await Get<ConfigurationFeaturesPayload>(ConfigurationFeaturesPath);
+    return res.features;
+}
+
+export async function getConfigurationBranding(): Promise<ConfigurationBranding> {
+    return Get<ConfigurationBranding>(ConfigurationBrandingPath);
+}
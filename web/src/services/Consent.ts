@@ -1,5 +1,5 @@
-import { ConsentPath } from "@services/Api";
-import { Get, Post } from "@services/Client";
+import { ConsentDeviceAuthorizationPath, ConsentPath } from "@services/Api";
+import { Get, Post, PutWithOptionalResponse } from "@services/Client";
 
 interface ConsentPostRequestBody {
     id?: string;
@@ -43,3 +43,20 @@ export function rejectConsent(clientID: string, consentID: string | null) {
     };
     return Post<ConsentPostResponseBody>(ConsentPath, body);
 }
+
+interface DeviceAuthorizationPutRequestBody {
+    user_code: string;
+    consent: boolean;
+}
+
+export function getDeviceAuthorizationResponse(userCode: string) {
+    return Get<ConsentGetResponseBody>(ConsentDeviceAuthorizationPath + "?user_code=" + encodeURIComponent(userCode));
+}
+
+export function putDeviceAuthorizationResponse(userCode: string, consent: boolean) {
+    const body: DeviceAuthorizationPutRequestBody = {
+        user_code: userCode,
+        consent: consent,
+    };
+    return PutWithOptionalResponse(ConsentDeviceAuthorizationPath, body);
+}
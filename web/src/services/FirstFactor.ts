@@ -1,7 +1,14 @@
-import { FirstFactorPath } from "@services/Api";
+import { FirstFactorPath, FirstFactorSPNEGOPath } from "@services/Api";
 import { PostWithOptionalResponse } from "@services/Client";
 import { SignInResponse } from "@services/SignIn";
 
+interface PostFirstFactorSPNEGOBody {
+    keepMeLoggedIn: boolean;
+    targetURL?: string;
+    requestMethod?: string;
+    workflow?: string;
+}
+
 interface PostFirstFactorBody {
     username: string;
     password: string;
@@ -40,3 +47,29 @@ export async function postFirstFactor(
     const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorPath, data);
     return res ? res : ({} as SignInResponse);
 }
+
+export async function postFirstFactorSPNEGO(
+    rememberMe: boolean,
+    targetURL?: string,
+    requestMethod?: string,
+    workflow?: string,
+) {
+    const data: PostFirstFactorSPNEGOBody = {
+        keepMeLoggedIn: rememberMe,
+    };
+
+    if (targetURL) {
+        data.targetURL = targetURL;
+    }
+
+    if (requestMethod) {
+        data.requestMethod = requestMethod;
+    }
+
+    if (workflow) {
+        data.workflow = workflow;
+    }
+
+    const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorSPNEGOPath, data);
+    return res ? res : ({} as SignInResponse);
+}
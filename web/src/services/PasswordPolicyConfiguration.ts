@@ -7,6 +7,7 @@ interface PasswordPolicyConfigurationPayload {
     min_length: number;
     max_length: number;
     min_score: number;
+    min_entropy: number;
     require_uppercase: boolean;
     require_lowercase: boolean;
     require_number: boolean;
@@ -2,6 +2,9 @@ import {
     CompleteDuoDeviceSelectionPath,
     CompletePushNotificationSignInPath,
     InitiateDuoDeviceSelectionPath,
+    InitiatePushNotificationPath,
+    PushChallengePath,
+    PushNotificationStatusPath,
 } from "@services/Api";
 import { Get, PostWithOptionalResponse } from "@services/Client";
 
@@ -21,6 +24,42 @@ export function completePushNotificationSignIn(targetURL?: string, workflow?: st
     return PostWithOptionalResponse<DuoSignInResponse>(CompletePushNotificationSignInPath, body);
 }
 
+export function initiatePushNotification(targetURL?: string, workflow?: string, workflowID?: string) {
+    const body: CompletePushSignInBody = {
+        targetURL: targetURL,
+        workflow: workflow,
+        workflowID: workflowID,
+    };
+
+    return PostWithOptionalResponse<DuoSignInResponse>(InitiatePushNotificationPath, body);
+}
+
+export function getPushNotificationStatus(targetURL?: string, workflow?: string, workflowID?: string) {
+    const body: CompletePushSignInBody = {
+        targetURL: targetURL,
+        workflow: workflow,
+        workflowID: workflowID,
+    };
+
+    return PostWithOptionalResponse<DuoSignInResponse>(PushNotificationStatusPath, body);
+}
+
+interface PushChallengeBody {
+    id: string;
+    token: string;
+    approve: boolean;
+}
+
+export async function respondPushChallenge(id: string, token: string, approve: boolean) {
+    const body: PushChallengeBody = {
+        id: id,
+        token: token,
+        approve: approve,
+    };
+
+    return PostWithOptionalResponse(PushChallengePath, body);
+}
+
 export interface DuoSignInResponse {
     result: string;
     devices: DuoDevice[];
@@ -0,0 +1,34 @@
+import { CompleteRecoveryCodeSignInPath, RecoveryCodesPath } from "@services/Api";
+import { Get, Post, PostWithOptionalResponse } from "@services/Client";
+import { SignInResponse } from "@services/SignIn";
+
+interface CompleteRecoveryCodeSignInBody {
+    code: string;
+    targetURL?: string;
+    workflow?: string;
+    workflowID?: string;
+}
+
+export interface RecoveryCodes {
+    remaining: number;
+    codes?: string[];
+}
+
+export function completeRecoveryCodeSignIn(code: string, targetURL?: string, workflow?: string, workflowID?: string) {
+    const body: CompleteRecoveryCodeSignInBody = {
+        code: code,
+        targetURL: targetURL,
+        workflow: workflow,
+        workflowID: workflowID,
+    };
+
+    return PostWithOptionalResponse<SignInResponse>(CompleteRecoveryCodeSignInPath, body);
+}
+
+export function getRecoveryCodes() {
+    return Get<RecoveryCodes>(RecoveryCodesPath);
+}
+
+export function generateRecoveryCodes() {
+    return Post<RecoveryCodes>(RecoveryCodesPath);
+}
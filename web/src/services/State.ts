@@ -10,6 +10,7 @@ export enum AuthenticationLevel {
 export interface AutheliaState {
     username: string;
     authentication_level: AuthenticationLevel;
+    webauthn_user_verified: boolean;
 }
 
 export async function getState(): Promise<AutheliaState> {
@@ -0,0 +1,27 @@
+import { UserSessionsPath } from "@services/Api";
+import { DeleteWithOptionalResponse, GetWithOptionalData } from "@services/Client";
+
+export interface UserSession {
+    id: string;
+    domain: string;
+    remote_ip: string;
+    user_agent: string;
+    authentication_level: string;
+    created_at: string;
+    last_activity: string;
+    current: boolean;
+}
+
+export async function getUserSessions(): Promise<UserSession[]> {
+    const res = await GetWithOptionalData<UserSession[] | null>(UserSessionsPath);
+
+    if (res === null) {
+        return [];
+    }
+
+    return res;
+}
+
+export function revokeUserSession(id: string) {
+    return DeleteWithOptionalResponse(`${UserSessionsPath}/${id}`);
+}
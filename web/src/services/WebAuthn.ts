@@ -13,12 +13,14 @@ import {
     AuthenticationResult,
     CredentialCreation,
     CredentialRequest,
+    PasskeyCredentialRequest,
     PublicKeyCredentialCreationOptionsStatus,
     PublicKeyCredentialRequestOptionsStatus,
     RegistrationResult,
 } from "@models/WebAuthn";
 import {
     AuthenticationOKResponse,
+    FirstFactorPasskeyPath,
     OKResponse,
     OptionalDataServiceResponse,
     ServiceResponse,
@@ -111,10 +113,33 @@ export async function getAttestationCreationOptions(
     };
 }
 
-export async function getAuthenticationOptions(): Promise<PublicKeyCredentialRequestOptionsStatus> {
+export async function getAuthenticationOptions(
+    userVerification?: string | null,
+): Promise<PublicKeyCredentialRequestOptionsStatus> {
     let response: AxiosResponse<ServiceResponse<CredentialRequest>>;
 
-    response = await axios.get<ServiceResponse<CredentialRequest>>(WebAuthnAssertionPath);
+    response = await axios.get<ServiceResponse<CredentialRequest>>(
+        userVerification ? `${WebAuthnAssertionPath}?uv=${encodeURIComponent(userVerification)}` : WebAuthnAssertionPath,
+    );
+
+    if (response.data.status !== "OK" || response.data.data == null) {
+        return {
+            status: response.status,
+        };
+    }
+
+    return {
+        options: response.data.data.publicKey,
+        status: response.status,
+    };
+}
+
+export async function getPasskeyAuthenticationOptions(
+    conditional: boolean,
+): Promise<PublicKeyCredentialRequestOptionsStatus> {
+    const response = await axios.get<ServiceResponse<PasskeyCredentialRequest>>(
+        conditional ? `${FirstFactorPasskeyPath}?mediation=conditional` : FirstFactorPasskeyPath,
+    );
 
     if (response.data.status !== "OK" || response.data.data == null) {
         return {
@@ -153,13 +178,13 @@ export async function startWebAuthnRegistration(options: PublicKeyCredentialCrea
     return result;
 }
 
-export async function getAuthenticationResult(options: PublicKeyCredentialRequestOptionsJSON) {
+export async function getAuthenticationResult(options: PublicKeyCredentialRequestOptionsJSON, conditional = false) {
     const result: AuthenticationResult = {
         result: AssertionResult.Success,
     };
 
     try {
-        result.response = await startAuthentication(options);
+        result.response = await startAuthentication(options, conditional);
     } catch (e) {
         const exception = e as DOMException;
         if (exception !== undefined) {
@@ -202,6 +227,24 @@ export async function postAuthenticationResponse(
     });
 }
 
+export async function postPasskeyAuthenticationResponse(
+    response: AuthenticationResponseJSON,
+    rememberMe: boolean,
+    targetURL?: string,
+    requestMethod?: string,
+    workflow?: string,
+    workflowID?: string,
+) {
+    return axios.post<ServiceResponse<SignInResponse>>(FirstFactorPasskeyPath, {
+        response: response,
+        keepMeLoggedIn: rememberMe,
+        targetURL: targetURL,
+        requestMethod: requestMethod,
+        workflow: workflow,
+        workflowID: workflowID,
+    });
+}
+
 export async function finishRegistration(response: RegistrationResponseJSON) {
     let result = {
         status: AttestationResult.Failure,
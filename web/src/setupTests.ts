@@ -33,7 +33,13 @@ const localStorageMock: LocalStorageMock = (function () {
 Object.defineProperty(window, "localStorage", { value: localStorageMock });
 
 document.body.setAttribute("data-basepath", "");
+document.body.setAttribute("data-brandingfooter", "");
+document.body.setAttribute("data-brandingmessage", "");
+document.body.setAttribute("data-brandingprimarycolor", "");
+document.body.setAttribute("data-brandingsecondarycolor", "");
+document.body.setAttribute("data-brandingtitle", "");
 document.body.setAttribute("data-duoselfenrollment", "true");
+document.body.setAttribute("data-features", "password_reset,totp,webauthn,duo,duo_self_enrollment");
 document.body.setAttribute("data-rememberme", "true");
 document.body.setAttribute("data-resetpassword", "true");
 document.body.setAttribute("data-resetpasswordcustomurl", "");
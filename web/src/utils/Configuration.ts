@@ -1,3 +1,5 @@
+import { Feature } from "@models/Configuration";
+
 export function getEmbeddedVariable(variableName: string) {
     const value = document.body.getAttribute(`data-${variableName}`);
     if (value === null) {
@@ -7,10 +9,40 @@ export function getEmbeddedVariable(variableName: string) {
     return value;
 }
 
+export function getBrandingTitle() {
+    return getEmbeddedVariable("brandingtitle");
+}
+
+export function getBrandingFooter() {
+    return getEmbeddedVariable("brandingfooter");
+}
+
+export function getBrandingMessage() {
+    return getEmbeddedVariable("brandingmessage");
+}
+
+export function getBrandingPrimaryColor() {
+    return getEmbeddedVariable("brandingprimarycolor");
+}
+
+export function getBrandingSecondaryColor() {
+    return getEmbeddedVariable("brandingsecondarycolor");
+}
+
 export function getDuoSelfEnrollment() {
     return getEmbeddedVariable("duoselfenrollment") === "true";
 }
 
+export function getFeatures() {
+    const value = getEmbeddedVariable("features");
+
+    return new Set<Feature>(value === "" ? [] : (value.split(",") as Feature[]));
+}
+
+export function hasFeature(feature: Feature) {
+    return getFeatures().has(feature);
+}
+
 export function getLogoOverride() {
     return getEmbeddedVariable("logooverride") === "true";
 }
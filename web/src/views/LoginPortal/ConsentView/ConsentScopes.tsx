@@ -0,0 +1,42 @@
+import React from "react";
+
+import { AccountBox, Autorenew, CheckBox, Contacts, Drafts, Group, LockOpen } from "@mui/icons-material";
+import { TFunction } from "i18next";
+
+export function scopeNameToAvatar(id: string) {
+    switch (id) {
+        case "openid":
+            return <AccountBox />;
+        case "offline_access":
+            return <Autorenew />;
+        case "profile":
+            return <Contacts />;
+        case "groups":
+            return <Group />;
+        case "email":
+            return <Drafts />;
+        case "authelia.bearer.authz":
+            return <LockOpen />;
+        default:
+            return <CheckBox />;
+    }
+}
+
+export function translateScopeNameToDescription(id: string, translate: TFunction): string {
+    switch (id) {
+        case "openid":
+            return translate("Use OpenID to verify your identity");
+        case "offline_access":
+            return translate("Automatically refresh these permissions without user interaction");
+        case "profile":
+            return translate("Access your profile information");
+        case "groups":
+            return translate("Access your group membership");
+        case "email":
+            return translate("Access your email addresses");
+        case "authelia.bearer.authz":
+            return translate("Access protected resources logged in as you");
+        default:
+            return id;
+    }
+}
@@ -1,6 +1,5 @@
 import React, { Fragment, ReactNode, useEffect, useState } from "react";
 
-import { AccountBox, Autorenew, CheckBox, Contacts, Drafts, Group, LockOpen } from "@mui/icons-material";
 import {
     Button,
     Checkbox,
@@ -26,28 +25,10 @@ import { useUserInfoGET } from "@hooks/UserInfo";
 import LoginLayout from "@layouts/LoginLayout";
 import { ConsentGetResponseBody, acceptConsent, getConsentResponse, rejectConsent } from "@services/Consent";
 import LoadingPage from "@views/LoadingPage/LoadingPage";
+import { scopeNameToAvatar, translateScopeNameToDescription } from "@views/LoginPortal/ConsentView/ConsentScopes";
 
 export interface Props {}
 
-function scopeNameToAvatar(id: string) {
-    switch (id) {
-        case "openid":
-            return <AccountBox />;
-        case "offline_access":
-            return <Autorenew />;
-        case "profile":
-            return <Contacts />;
-        case "groups":
-            return <Group />;
-        case "email":
-            return <Drafts />;
-        case "authelia.bearer.authz":
-            return <LockOpen />;
-        default:
-            return <CheckBox />;
-    }
-}
-
 const ConsentView = function (props: Props) {
     const { t: translate } = useTranslation();
 
@@ -98,25 +79,6 @@ const ConsentView = function (props: Props) {
         }
     }, [fetchUserInfoError, resetNotification, createErrorNotification, translate]);
 
-    const translateScopeNameToDescription = (id: string): string => {
-        switch (id) {
-            case "openid":
-                return translate("Use OpenID to verify your identity");
-            case "offline_access":
-                return translate("Automatically refresh these permissions without user interaction");
-            case "profile":
-                return translate("Access your profile information");
-            case "groups":
-                return translate("Access your group membership");
-            case "email":
-                return translate("Access your email addresses");
-            case "authelia.bearer.authz":
-                return translate("Access protected resources logged in as you");
-            default:
-                return id;
-        }
-    };
-
     const handleAcceptConsent = async () => {
         // This case should not happen in theory because the buttons are disabled when response is undefined.
         if (!response) {
@@ -176,7 +138,7 @@ const ConsentView = function (props: Props) {
                                     <Tooltip title={translate("Scope", { name: scope })}>
                                         <ListItem id={"scope-" + scope} dense>
                                             <ListItemIcon>{scopeNameToAvatar(scope)}</ListItemIcon>
-                                            <ListItemText primary={translateScopeNameToDescription(scope)} />
+                                            <ListItemText primary={translateScopeNameToDescription(scope, translate)} />
                                         </ListItem>
                                     </Tooltip>
                                 ))}
@@ -0,0 +1,254 @@
+import React, { Fragment, ReactNode, useCallback, useEffect, useState } from "react";
+
+import {
+    Button,
+    List,
+    ListItem,
+    ListItemIcon,
+    ListItemText,
+    TextField,
+    Theme,
+    Tooltip,
+    Typography,
+} from "@mui/material";
+import Grid from "@mui/material/Grid2";
+import makeStyles from "@mui/styles/makeStyles";
+import { useTranslation } from "react-i18next";
+import { useNavigate, useSearchParams } from "react-router-dom";
+
+import { IndexRoute } from "@constants/Routes";
+import { RedirectionURL, UserCode } from "@constants/SearchParams";
+import { useNotifications } from "@hooks/NotificationsContext";
+import { useUserInfoGET } from "@hooks/UserInfo";
+import LoginLayout from "@layouts/LoginLayout";
+import {
+    ConsentGetResponseBody,
+    getDeviceAuthorizationResponse,
+    putDeviceAuthorizationResponse,
+} from "@services/Consent";
+import LoadingPage from "@views/LoadingPage/LoadingPage";
+import { scopeNameToAvatar, translateScopeNameToDescription } from "@views/LoginPortal/ConsentView/ConsentScopes";
+
+export interface Props {}
+
+const DeviceAuthorizationView = function (props: Props) {
+    const { t: translate } = useTranslation();
+
+    const [userInfo, fetchUserInfo, , fetchUserInfoError] = useUserInfoGET();
+
+    const { createErrorNotification, createSuccessNotification } = useNotifications();
+    const navigate = useNavigate();
+    const [searchParams] = useSearchParams();
+
+    const [userCode, setUserCode] = useState(searchParams.get(UserCode) ?? "");
+    const [submittedUserCode, setSubmittedUserCode] = useState(searchParams.get(UserCode));
+    const [response, setResponse] = useState<ConsentGetResponseBody>();
+    const [completed, setCompleted] = useState(false);
+
+    const styles = useStyles();
+
+    useEffect(() => {
+        fetchUserInfo();
+    }, [fetchUserInfo]);
+
+    useEffect(() => {
+        if (fetchUserInfoError) {
+            navigate(`${IndexRoute}?${RedirectionURL}=${encodeURIComponent(window.location.href)}`);
+        }
+    }, [navigate, fetchUserInfoError]);
+
+    useEffect(() => {
+        if (submittedUserCode === null || submittedUserCode === "") {
+            return;
+        }
+
+        getDeviceAuthorizationResponse(submittedUserCode)
+            .then((r) => {
+                setResponse(r);
+            })
+            .catch((error) => {
+                console.error(`Unable to display device authorization screen: ${error.message}`);
+                createErrorNotification(translate("The code is invalid or has expired"));
+                setSubmittedUserCode(null);
+            });
+    }, [submittedUserCode, createErrorNotification, translate]);
+
+    const handleSubmitUserCode = () => {
+        if (userCode === "") {
+            return;
+        }
+
+        setSubmittedUserCode(userCode.trim());
+    };
+
+    const handleResponse = useCallback(
+        async (consent: boolean) => {
+            if (!response || submittedUserCode === null) {
+                return;
+            }
+
+            try {
+                await putDeviceAuthorizationResponse(submittedUserCode, consent);
+
+                setCompleted(true);
+
+                if (consent) {
+                    createSuccessNotification(translate("The device has been authorized you may now return to it"));
+                } else {
+                    createSuccessNotification(translate("The device authorization was denied"));
+                }
+            } catch (err) {
+                console.error(err);
+                createErrorNotification(translate("The code is invalid or has expired"));
+            }
+        },
+        [response, submittedUserCode, createErrorNotification, createSuccessNotification, translate],
+    );
+
+    return (
+        <ComponentOrLoading ready={userInfo !== undefined}>
+            <LoginLayout
+                id="device-authorization-stage"
+                title={`${translate("Hi")} ${userInfo?.display_name}`}
+                subtitle={translate("Device Authorization Request")}
+            >
+                {completed ? null : response === undefined ? (
+                    <Grid container spacing={2}>
+                        <Grid size={{ xs: 12 }}>
+                            <Typography>{translate("Enter the code displayed on your device")}</Typography>
+                        </Grid>
+                        <Grid size={{ xs: 12 }}>
+                            <TextField
+                                id="user-code-textfield"
+                                label={translate("Code")}
+                                variant="outlined"
+                                fullWidth
+                                value={userCode}
+                                onChange={(e) => setUserCode(e.target.value)}
+                                onKeyDown={(ev) => {
+                                    if (ev.key === "Enter") {
+                                        handleSubmitUserCode();
+                                        ev.preventDefault();
+                                    }
+                                }}
+                            />
+                        </Grid>
+                        <Grid size={{ xs: 12 }}>
+                            <Button
+                                id="user-code-button"
+                                className={styles.button}
+                                disabled={userCode === ""}
+                                onClick={handleSubmitUserCode}
+                                color="primary"
+                                variant="contained"
+                            >
+                                {translate("Submit")}
+                            </Button>
+                        </Grid>
+                    </Grid>
+                ) : (
+                    <Grid container>
+                        <Grid size={{ xs: 12 }}>
+                            <Tooltip
+                                title={
+                                    translate("Client ID", { client_id: response.client_id }) ||
+                                    "Client ID: " + response.client_id
+                                }
+                            >
+                                <Typography className={styles.clientDescription}>
+                                    {response.client_description !== ""
+                                        ? response.client_description
+                                        : response.client_id}
+                                </Typography>
+                            </Tooltip>
+                        </Grid>
+                        <Grid size={{ xs: 12 }}>
+                            <div>{translate("The above application is requesting the following permissions")}:</div>
+                        </Grid>
+                        <Grid size={{ xs: 12 }}>
+                            <div className={styles.scopesListContainer}>
+                                <List className={styles.scopesList}>
+                                    {response.scopes.map((scope: string) => (
+                                        <Tooltip key={scope} title={translate("Scope", { name: scope })}>
+                                            <ListItem id={"scope-" + scope} dense>
+                                                <ListItemIcon>{scopeNameToAvatar(scope)}</ListItemIcon>
+                                                <ListItemText
+                                                    primary={translateScopeNameToDescription(scope, translate)}
+                                                />
+                                            </ListItem>
+                                        </Tooltip>
+                                    ))}
+                                </List>
+                            </div>
+                        </Grid>
+                        <Grid size={{ xs: 12 }}>
+                            <Grid container spacing={1}>
+                                <Grid size={{ xs: 6 }}>
+                                    <Button
+                                        id="accept-button"
+                                        className={styles.button}
+                                        onClick={() => handleResponse(true)}
+                                        color="primary"
+                                        variant="contained"
+                                    >
+                                        {translate("Accept")}
+                                    </Button>
+                                </Grid>
+                                <Grid size={{ xs: 6 }}>
+                                    <Button
+                                        id="deny-button"
+                                        className={styles.button}
+                                        onClick={() => handleResponse(false)}
+                                        color="secondary"
+                                        variant="contained"
+                                    >
+                                        {translate("Deny")}
+                                    </Button>
+                                </Grid>
+                            </Grid>
+                        </Grid>
+                    </Grid>
+                )}
+            </LoginLayout>
+        </ComponentOrLoading>
+    );
+};
+
+const useStyles = makeStyles((theme: Theme) => ({
+    clientDescription: {
+        fontWeight: 600,
+    },
+    scopesListContainer: {
+        textAlign: "center",
+    },
+    scopesList: {
+        display: "inline-block",
+        backgroundColor: theme.palette.background.paper,
+        marginTop: theme.spacing(2),
+        marginBottom: theme.spacing(2),
+    },
+    button: {
+        marginLeft: theme.spacing(),
+        marginRight: theme.spacing(),
+        width: "100%",
+    },
+}));
+
+interface ComponentOrLoadingProps {
+    ready: boolean;
+
+    children: ReactNode;
+}
+
+function ComponentOrLoading(props: ComponentOrLoadingProps) {
+    return (
+        <Fragment>
+            <div className={props.ready ? "hidden" : ""}>
+                <LoadingPage />
+            </div>
+            {props.ready ? props.children : null}
+        </Fragment>
+    );
+}
+
+export default DeviceAuthorizationView;
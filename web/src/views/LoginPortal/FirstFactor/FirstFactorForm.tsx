@@ -4,6 +4,7 @@ import { Alert, AlertTitle, Button, Checkbox, FormControl, FormControlLabel, Lin
 import Grid from "@mui/material/Grid2";
 import TextField from "@mui/material/TextField";
 import makeStyles from "@mui/styles/makeStyles";
+import { browserSupportsWebAuthnAutofill } from "@simplewebauthn/browser";
 import { BroadcastChannel } from "broadcast-channel";
 import classnames from "classnames";
 import { useTranslation } from "react-i18next";
@@ -15,8 +16,15 @@ import { useNotifications } from "@hooks/NotificationsContext";
 import { useQueryParam } from "@hooks/QueryParam";
 import { useWorkflow } from "@hooks/Workflow";
 import LoginLayout from "@layouts/LoginLayout";
+import { AssertionResult } from "@models/WebAuthn";
 import { IsCapsLockModified } from "@services/CapsLock";
-import { postFirstFactor } from "@services/FirstFactor";
+import { postFirstFactor, postFirstFactorSPNEGO } from "@services/FirstFactor";
+import {
+    getAuthenticationResult,
+    getPasskeyAuthenticationOptions,
+    postPasskeyAuthenticationResponse,
+} from "@services/WebAuthn";
+import { hasFeature } from "@utils/Configuration";
 
 export interface Props {
     disabled: boolean;
@@ -37,7 +45,7 @@ const FirstFactorForm = function (props: Props) {
     const navigate = useNavigate();
     const redirectionURL = useQueryParam(RedirectionURL);
     const requestMethod = useQueryParam(RequestMethod);
-    const [workflow] = useWorkflow();
+    const [workflow, workflowID] = useWorkflow();
     const { createErrorNotification } = useNotifications();
 
     const loginChannel = useMemo(() => new BroadcastChannel<boolean>("login"), []);
@@ -50,6 +58,9 @@ const FirstFactorForm = function (props: Props) {
     const [passwordCapsLockPartial, setPasswordCapsLockPartial] = useState(false);
     const [passwordError, setPasswordError] = useState(false);
 
+    const spnegoAttempted = useRef(false);
+    const passkeyAutofillStarted = useRef(false);
+    const rememberMeRef = useRef(rememberMe);
     const usernameRef = useRef() as MutableRefObject<HTMLInputElement>;
     const passwordRef = useRef() as MutableRefObject<HTMLInputElement>;
 
@@ -68,6 +79,92 @@ const FirstFactorForm = function (props: Props) {
         });
     }, [loginChannel, redirectionURL, props]);
 
+    useEffect(() => {
+        if (spnegoAttempted.current || !hasFeature("spnego")) {
+            return;
+        }
+
+        spnegoAttempted.current = true;
+
+        // The browser only sends a Kerberos ticket when the portal is trusted for the Negotiate authentication scheme,
+        // any failure falls back to the login form.
+        postFirstFactorSPNEGO(false, redirectionURL, requestMethod, workflow)
+            .then(async (res) => {
+                await loginChannel.postMessage(true);
+                props.onAuthenticationSuccess(res ? res.redirect : undefined);
+            })
+            .catch(() => {});
+    }, [loginChannel, props, redirectionURL, requestMethod, workflow]);
+
+    useEffect(() => {
+        rememberMeRef.current = rememberMe;
+    }, [rememberMe]);
+
+    const signInWithPasskey = useCallback(
+        async (conditional: boolean) => {
+            const optionsStatus = await getPasskeyAuthenticationOptions(conditional);
+
+            if (optionsStatus.status !== 200 || optionsStatus.options == null) {
+                throw new Error("Failed to retrieve the passkey authentication options.");
+            }
+
+            const result = await getAuthenticationResult(optionsStatus.options, conditional);
+
+            if (result.result !== AssertionResult.Success || result.response == null) {
+                return false;
+            }
+
+            if (!conditional) {
+                props.onAuthenticationStart();
+            }
+
+            const res = await postPasskeyAuthenticationResponse(
+                result.response,
+                rememberMeRef.current,
+                redirectionURL,
+                requestMethod,
+                workflow,
+                workflowID,
+            );
+
+            if (res.data.status !== "OK") {
+                throw new Error("Failed to validate the passkey.");
+            }
+
+            await loginChannel.postMessage(true);
+            props.onAuthenticationSuccess(res.data.data ? res.data.data.redirect : undefined);
+
+            return true;
+        },
+        [loginChannel, props, redirectionURL, requestMethod, workflow, workflowID],
+    );
+
+    useEffect(() => {
+        if (passkeyAutofillStarted.current || !hasFeature("passkey_login")) {
+            return;
+        }
+
+        passkeyAutofillStarted.current = true;
+
+        // The conditional mediation (autofill) ceremony stays pending until the user selects a passkey from the
+        // autofill suggestions of the username field, any failure leaves the login form as is.
+        browserSupportsWebAuthnAutofill()
+            .then((supported) => (supported ? signInWithPasskey(true) : false))
+            .catch(() => {});
+    }, [signInWithPasskey]);
+
+    const handlePasskeySignIn = useCallback(async () => {
+        try {
+            if (!(await signInWithPasskey(false))) {
+                createErrorNotification(translate("The passkey was not used to sign in"));
+            }
+        } catch (err) {
+            console.error(err);
+            createErrorNotification(translate("There was a problem signing in with the passkey"));
+            props.onAuthenticationFailure();
+        }
+    }, [createErrorNotification, props, signInWithPasskey, translate]);
+
     const disabled = props.disabled;
 
     const handleRememberMeChange = () => {
@@ -208,7 +305,7 @@ const FirstFactorForm = function (props: Props) {
                             onChange={(v) => setUsername(v.target.value)}
                             onFocus={() => setUsernameError(false)}
                             autoCapitalize="none"
-                            autoComplete="username"
+                            autoComplete={hasFeature("passkey_login") ? "username webauthn" : "username"}
                             onKeyDown={handleUsernameKeyDown}
                         />
                     </Grid>
@@ -272,6 +369,20 @@ const FirstFactorForm = function (props: Props) {
                             {translate("Sign in")}
                         </Button>
                     </Grid>
+                    {hasFeature("passkey_login") ? (
+                        <Grid size={{ xs: 12 }}>
+                            <Button
+                                id="passkey-sign-in-button"
+                                variant="outlined"
+                                color="primary"
+                                fullWidth
+                                disabled={disabled}
+                                onClick={handlePasskeySignIn}
+                            >
+                                {translate("Sign in with a passkey")}
+                            </Button>
+                        </Grid>
+                    ) : null}
                     {props.resetPassword ? (
                         <Grid size={{ xs: 12 }} className={classnames(styles.actionRow, styles.flexEnd)}>
                             <Link
@@ -11,7 +11,7 @@ import {
     SecondFactorTOTPSubRoute,
     SecondFactorWebAuthnSubRoute,
 } from "@constants/Routes";
-import { RedirectionURL } from "@constants/SearchParams";
+import { RedirectionURL, UserVerification } from "@constants/SearchParams";
 import { useLocalStorageMethodContext } from "@contexts/LocalStorageMethodContext";
 import { useConfiguration } from "@hooks/Configuration";
 import { useNotifications } from "@hooks/NotificationsContext";
@@ -43,6 +43,7 @@ const RedirectionErrorMessage =
 const LoginPortal = function (props: Props) {
     const location = useLocation();
     const redirectionURL = useQueryParam(RedirectionURL);
+    const userVerificationRequired = useQueryParam(UserVerification) === "required";
     const { createErrorNotification } = useNotifications();
     const [firstFactorDisabled, setFirstFactorDisabled] = useState(true);
     const [broadcastRedirect, setBroadcastRedirect] = useState(false);
@@ -56,6 +57,15 @@ const LoginPortal = function (props: Props) {
 
     const navigate = useRouterNavigate();
 
+    // The second factor is not sufficient when the WebAuthn assertion is required to verify the user and it didn't.
+    const authenticationLevel =
+        state &&
+        userVerificationRequired &&
+        state.authentication_level === AuthenticationLevel.TwoFactor &&
+        !state.webauthn_user_verified
+            ? AuthenticationLevel.OneFactor
+            : state?.authentication_level;
+
     // Fetch the state when portal is mounted.
     useEffect(() => {
         fetchState();
@@ -109,7 +119,7 @@ const LoginPortal = function (props: Props) {
                 ((configuration &&
                     configuration.available_methods.size === 0 &&
                     state.authentication_level >= AuthenticationLevel.OneFactor) ||
-                    state.authentication_level === AuthenticationLevel.TwoFactor ||
+                    authenticationLevel === AuthenticationLevel.TwoFactor ||
                     broadcastRedirect)
             ) {
                 try {
@@ -135,7 +145,7 @@ const LoginPortal = function (props: Props) {
                 } else {
                     const method = localStorageMethod || userInfo.method;
 
-                    if (method === SecondFactorMethod.WebAuthn) {
+                    if (userVerificationRequired || method === SecondFactorMethod.WebAuthn) {
                         navigate(`${SecondFactorRoute}${SecondFactorWebAuthnSubRoute}`);
                     } else if (method === SecondFactorMethod.MobilePush) {
                         navigate(`${SecondFactorRoute}${SecondFactorPushSubRoute}`);
@@ -147,6 +157,8 @@ const LoginPortal = function (props: Props) {
         })();
     }, [
         state,
+        authenticationLevel,
+        userVerificationRequired,
         redirectionURL,
         navigate,
         userInfo,
@@ -201,9 +213,9 @@ const LoginPortal = function (props: Props) {
             <Route
                 path={`${SecondFactorRoute}/*`}
                 element={
-                    state && userInfo && configuration ? (
+                    state && authenticationLevel !== undefined && userInfo && configuration ? (
                         <SecondFactorForm
-                            authenticationLevel={state.authentication_level}
+                            authenticationLevel={authenticationLevel}
                             userInfo={userInfo}
                             configuration={configuration}
                             duoSelfEnrollment={props.duoSelfEnrollment}
@@ -1,4 +1,4 @@
-import React, { ReactNode, useCallback, useEffect, useRef, useState } from "react";
+import React, { MutableRefObject, ReactNode, useCallback, useEffect, useRef, useState } from "react";
 
 import { Button, Theme } from "@mui/material";
 import makeStyles from "@mui/styles/makeStyles";
@@ -15,9 +15,12 @@ import {
     DuoDevicePostRequest,
     completeDuoDeviceSelectionProcess,
     completePushNotificationSignIn,
+    getPushNotificationStatus,
     initiateDuoDeviceSelectionProcess,
+    initiatePushNotification,
 } from "@services/PushNotification";
 import { AuthenticationLevel } from "@services/State";
+import { hasFeature } from "@utils/Configuration";
 import DeviceSelectionContainer, {
     SelectableDevice,
     SelectedDevice,
@@ -32,6 +35,8 @@ export enum State {
     Enroll = 5,
 }
 
+const pushStatusInterval = 2000;
+
 export interface Props {
     id: string;
     authenticationLevel: AuthenticationLevel;
@@ -98,7 +103,9 @@ const PushNotificationMethod = function (props: Props) {
 
         try {
             setState(State.SignInInProgress);
-            const res = await completePushNotificationSignIn(redirectionURL, workflow, workflowID);
+            const res = hasFeature("push")
+                ? await completePushSignIn(redirectionURL, workflow, workflowID, mounted)
+                : await completePushNotificationSignIn(redirectionURL, workflow, workflowID);
             // If the request was initiated and the user changed 2FA method in the meantime,
             // the process is interrupted to avoid updating state of unmounted component.
             if (!mounted.current) return;
@@ -237,6 +244,24 @@ const PushNotificationMethod = function (props: Props) {
 
 export default PushNotificationMethod;
 
+// completePushSignIn starts a push with the push provider and polls the status of it until it's no longer waiting for
+// the user or the component is unmounted.
+async function completePushSignIn(
+    targetURL: string | undefined,
+    workflow: string | undefined,
+    workflowID: string | undefined,
+    mounted: MutableRefObject<boolean>,
+) {
+    await initiatePushNotification(targetURL, workflow, workflowID);
+
+    for (;;) {
+        const res = await getPushNotificationStatus(targetURL, workflow, workflowID);
+        if (!res || res.result !== "waiting" || !mounted.current) return res;
+
+        await new Promise((resolve) => setTimeout(resolve, pushStatusInterval));
+    }
+}
+
 const useStyles = makeStyles((theme: Theme) => ({
     icon: {
         width: "64px",
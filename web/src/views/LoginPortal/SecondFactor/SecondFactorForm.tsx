@@ -23,6 +23,7 @@ import { SecondFactorMethod } from "@models/Methods";
 import { UserInfo } from "@models/UserInfo";
 import { AuthenticationLevel } from "@services/State";
 import { setPreferred2FAMethod } from "@services/UserInfo";
+import { hasFeature } from "@utils/Configuration";
 import MethodSelectionDialog from "@views/LoginPortal/SecondFactor/MethodSelectionDialog";
 
 const OneTimePasswordMethod = lazy(() => import("@views/LoginPortal/SecondFactor/OneTimePasswordMethod"));
@@ -148,7 +149,7 @@ const SecondFactorForm = function (props: Props) {
                                     id="push-notification-method"
                                     authenticationLevel={props.authenticationLevel}
                                     duoSelfEnrollment={props.duoSelfEnrollment}
-                                    registered={props.userInfo.has_duo}
+                                    registered={hasFeature("push") || props.userInfo.has_duo}
                                     onSelectionClick={props.onMethodChanged}
                                     onSignInError={(err) => createErrorNotification(err.message)}
                                     onSignInSuccess={props.onAuthenticationSuccess}
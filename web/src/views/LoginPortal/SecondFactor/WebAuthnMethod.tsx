@@ -3,7 +3,7 @@ import React, { useCallback, useEffect, useRef, useState } from "react";
 import { useTranslation } from "react-i18next";
 
 import WebAuthnTryIcon from "@components/WebAuthnTryIcon";
-import { RedirectionURL } from "@constants/SearchParams";
+import { RedirectionURL, UserVerification } from "@constants/SearchParams";
 import { useIsMountedRef } from "@hooks/Mounted";
 import { useQueryParam } from "@hooks/QueryParam";
 import { useWorkflow } from "@hooks/Workflow";
@@ -25,6 +25,7 @@ export interface Props {
 const WebAuthnMethod = function (props: Props) {
     const [state, setState] = useState(WebAuthnTouchState.WaitTouch);
     const redirectionURL = useQueryParam(RedirectionURL);
+    const userVerification = useQueryParam(UserVerification);
     const [workflow, workflowID] = useWorkflow();
     const mounted = useIsMountedRef();
     const { t: translate } = useTranslation();
@@ -41,7 +42,7 @@ const WebAuthnMethod = function (props: Props) {
 
         try {
             setState(WebAuthnTouchState.WaitTouch);
-            const optionsStatus = await getAuthenticationOptions();
+            const optionsStatus = await getAuthenticationOptions(userVerification);
 
             if (optionsStatus.status !== 200 || optionsStatus.options == null) {
                 setState(WebAuthnTouchState.Failure);
@@ -98,6 +99,7 @@ const WebAuthnMethod = function (props: Props) {
         onSignInErrorCallback,
         onSignInSuccessCallback,
         redirectionURL,
+        userVerification,
         workflow,
         workflowID,
         mounted,
@@ -0,0 +1,105 @@
+import React, { useCallback, useEffect, useState } from "react";
+
+import { Button, Theme, Typography } from "@mui/material";
+import Grid from "@mui/material/Grid2";
+import makeStyles from "@mui/styles/makeStyles";
+import { useTranslation } from "react-i18next";
+
+import { IndexRoute } from "@constants/Routes";
+import { useNotifications } from "@hooks/NotificationsContext";
+import { useID, useToken } from "@hooks/Revoke";
+import { useRouterNavigate } from "@hooks/RouterNavigate";
+import MinimalLayout from "@layouts/MinimalLayout";
+import { respondPushChallenge } from "@services/PushNotification";
+
+const PushChallengeView = function () {
+    const styles = useStyles();
+    const { t: translate } = useTranslation();
+    const { createSuccessNotification, createErrorNotification } = useNotifications();
+    const [responded, setResponded] = useState(false);
+
+    const id = useID();
+    const token = useToken();
+    const navigate = useRouterNavigate();
+
+    const handleRedirect = useCallback(() => {
+        setTimeout(() => {
+            navigate(IndexRoute, false);
+        }, 1500);
+    }, [navigate]);
+
+    const handleResponse = useCallback(
+        async (approve: boolean) => {
+            if (!id || !token) return;
+
+            setResponded(true);
+
+            try {
+                await respondPushChallenge(id, token, approve);
+
+                if (approve) {
+                    createSuccessNotification(translate("Successfully approved the sign in request"));
+                } else {
+                    createSuccessNotification(translate("Successfully denied the sign in request"));
+                }
+            } catch (err) {
+                console.error(err);
+                createErrorNotification(translate("Failed to respond to the sign in request"));
+            }
+
+            handleRedirect();
+        },
+        [createErrorNotification, createSuccessNotification, handleRedirect, id, token, translate],
+    );
+
+    useEffect(() => {
+        if (!id || !token) {
+            createErrorNotification(translate("The sign in request identifier or token was not provided"));
+
+            handleRedirect();
+        }
+    }, [createErrorNotification, handleRedirect, id, token, translate]);
+
+    return (
+        <MinimalLayout title={translate("Sign In Request")} id="push-challenge-stage">
+            <Grid container className={styles.root} spacing={2}>
+                <Grid size={{ xs: 12 }}>
+                    <Typography>{translate("Someone is attempting to sign in to your account")}</Typography>
+                </Grid>
+                <Grid size={{ xs: 6 }}>
+                    <Button
+                        id="approve-button"
+                        variant="contained"
+                        color="primary"
+                        fullWidth
+                        disabled={responded || !id || !token}
+                        onClick={() => handleResponse(true)}
+                    >
+                        {translate("Approve")}
+                    </Button>
+                </Grid>
+                <Grid size={{ xs: 6 }}>
+                    <Button
+                        id="deny-button"
+                        variant="contained"
+                        color="secondary"
+                        fullWidth
+                        disabled={responded || !id || !token}
+                        onClick={() => handleResponse(false)}
+                    >
+                        {translate("Deny")}
+                    </Button>
+                </Grid>
+            </Grid>
+        </MinimalLayout>
+    );
+};
+
+export default PushChallengeView;
+
+const useStyles = makeStyles((theme: Theme) => ({
+    root: {
+        marginTop: theme.spacing(2),
+        marginBottom: theme.spacing(2),
+    },
+}));
@@ -35,6 +35,7 @@ const ResetPasswordStep2 = function () {
         max_length: 0,
         min_length: 8,
         min_score: 0,
+        min_entropy: 0,
         require_lowercase: false,
         require_number: false,
         require_special: false,
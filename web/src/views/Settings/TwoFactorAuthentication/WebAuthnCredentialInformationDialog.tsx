@@ -70,6 +70,14 @@ const WebAuthnCredentialInformationDialog = function (props: Props) {
                                         : props.credential.aaguid
                                 }
                             />
+                            <PropertyText
+                                name={translate("Authenticator")}
+                                value={
+                                    props.credential.authenticator === undefined
+                                        ? translate("Unknown")
+                                        : props.credential.authenticator
+                                }
+                            />
                             <PropertyText
                                 name={translate("Attestation Type")}
                                 value={props.credential.attestation_type}